@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"auralogic/internal/config"
 	"auralogic/internal/database"
@@ -13,6 +16,7 @@ import (
 	"auralogic/internal/jsworker"
 	"auralogic/internal/pkg/cache"
 	"auralogic/internal/pkg/jwt"
+	"auralogic/internal/pkg/piicrypt"
 	"auralogic/internal/repository"
 	"auralogic/internal/router"
 	"auralogic/internal/service"
@@ -23,10 +27,31 @@ import (
 //	go build -ldflags "-X main.GitCommit=$(git rev-parse --short HEAD)" ./cmd/api
 var GitCommit = ""
 
+// cliFlagValue 解析 --name=value（或 --name value），未指定时返回空串
+func cliFlagValue(args []string, name string) string {
+	flag := "--" + name
+	for i, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if value, ok := strings.CutPrefix(arg, flag+"="); ok {
+			return value
+		}
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// runModeFlag 解析 --mode=api|worker|all
+func runModeFlag(args []string) string {
+	return cliFlagValue(args, "mode")
+}
+
 func main() {
-	// 同一后端二进制双模式运行：
-	// 1) 默认 API 服务模式
+	// 同一后端二进制多模式运行：
+	// 1) 默认 API 服务模式，--mode=api / --mode=worker 可拆分 API 与后台任务进程（覆盖 app.mode）
 	// 2) --js-worker 子进程模式（供插件管理器拉起）
+	// 3) --seed=demo 启动前写入示例数据（可重复执行）
 	if len(os.Args) > 1 && strings.EqualFold(strings.TrimSpace(os.Args[1]), "--js-worker") {
 		if err := jsworker.Run(os.Args[2:]); err != nil {
 			log.Fatalf("JS worker mode failed: %v", err)
@@ -45,6 +70,22 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 	log.Printf("Config loaded from: %s", config.GetConfigPath())
+	if flagMode := runModeFlag(os.Args[1:]); flagMode != "" {
+		mode, err := config.NormalizeAppMode(flagMode)
+		if err != nil {
+			log.Fatalf("Invalid --mode: %v", err)
+		}
+		cfg.App.Mode = mode
+	}
+	log.Printf("Run mode: %s", cfg.App.Mode)
+	if cfg.App.DemoMode {
+		log.Println("Demo mode enabled: outbound email, SMS and HTTP requests are disabled")
+	}
+
+	// 收件人联系信息加密
+	if err := piicrypt.Init(&cfg.Security.PIIEncryption); err != nil {
+		log.Fatalf("Failed to initialize PII encryption: %v", err)
+	}
 
 	// 初始化日志
 	if _, err := config.InitLogger(&cfg.Log); err != nil {
@@ -68,11 +109,31 @@ func main() {
 	}
 	log.Println("Database migrated successfully")
 
-	// 初始化Redis
-	if err := cache.InitRedis(&cfg.Redis); err != nil {
-		log.Fatalf("Failed to initialize redis: %v", err)
+	if seedSet := cliFlagValue(os.Args[1:], "seed"); seedSet != "" {
+		result, err := service.SeedData(database.GetDB(), cfg, seedSet)
+		if err != nil {
+			log.Fatalf("Failed to seed %s data: %v", seedSet, err)
+		}
+		if result.Skipped {
+			log.Printf("Seed data %q already present, skipped", result.Set)
+		} else {
+			log.Printf("Seed data %q created: users=%d products=%d inventories=%d virtual_stocks=%d orders=%d (password: %s)",
+				result.Set, result.Users, result.Products, result.Inventories, result.VirtualStocks, result.Orders, service.DemoSeedPassword)
+		}
+	}
+
+	// 初始化Redis，显式关闭时回退到进程内缓存
+	if cfg.Redis.IsEnabled() {
+		if err := cache.InitRedis(&cfg.Redis); err != nil {
+			log.Fatalf("Failed to initialize redis: %v", err)
+		}
+		defer cache.Close()
+	} else {
+		cache.InitMemory()
+		if cfg.App.Mode != config.AppModeAll {
+			log.Printf("Warning: app.mode=%s without redis, background job locks and rate limits are not shared between processes", cfg.App.Mode)
+		}
 	}
-	defer cache.Close()
 
 	// 初始化JWT
 	jwt.InitJWT(&cfg.JWT)
@@ -90,6 +151,7 @@ func main() {
 	// 初始化Service
 	authService := service.NewAuthService(userRepo, cfg)
 	emailService := service.NewEmailService(db, &cfg.SMTP, cfg.App.URL)
+	authService.SetEmailChangeService(service.NewEmailChangeService(db, emailService))
 	smsService := service.NewSMSService(cfg, db)
 	marketingService := service.NewMarketingService(db, emailService, smsService)
 	bindingService := service.NewBindingService(bindingRepo, inventoryRepo, productRepo)
@@ -109,26 +171,7 @@ func main() {
 	serialService.SetPluginManager(pluginManagerService)
 	orderService.SetPluginManager(pluginManagerService)
 	orderService.SetSerialGenerationService(serialGenerationService)
-
-	// 启动邮件队列处理（如果启用）
-	emailService.Start()
-	defer emailService.Stop()
-	if emailService.IsEnabled() {
-		log.Println("Email service started")
-	}
-
-	smsDelayedCtx, smsDelayedCancel := context.WithCancel(context.Background())
-	defer smsDelayedCancel()
-	go smsService.ProcessDelayedSMS(smsDelayedCtx)
-	log.Println("SMS delayed worker started")
-
-	marketingService.Start()
-	defer marketingService.Stop()
-	log.Println("Marketing queue worker started")
-
-	serialGenerationService.Start()
-	defer serialGenerationService.Stop()
-	log.Println("Serial generation worker started")
+	orderService.SetGiftPromotionService(service.NewGiftPromotionService(db))
 
 	// 初始化内置付款方式
 	paymentMethodService := service.NewPaymentMethodService(db, cfg)
@@ -136,41 +179,141 @@ func main() {
 		log.Printf("Warning: Failed to initialize builtin payment methods: %v", err)
 	}
 
-	// 启动付款状态轮询服务
+	// api 模式下付款轮询只落库任务，由 worker 进程执行
 	paymentPollingService := service.NewPaymentPollingService(db, virtualInventoryService, emailService, cfg)
 	paymentPollingService.SetPluginManager(pluginManagerService)
-	paymentPollingService.Start()
-	defer paymentPollingService.Stop()
-
-	// 启动订单自动取消服务
-	orderCancelService := service.NewOrderCancelService(db, cfg, inventoryRepo, promoCodeRepo, virtualInventoryService, serialService)
-	orderCancelService.SetPluginManager(pluginManagerService)
-	orderCancelService.Start()
-	defer orderCancelService.Stop()
-	log.Println("Order auto-cancel service started")
-
-	// 启动工单附件自动清理服务
-	ticketAttachmentCleanupService := service.NewTicketAttachmentCleanupService(db, cfg)
-	ticketAttachmentCleanupService.Start()
-	defer ticketAttachmentCleanupService.Stop()
-	log.Println("Ticket attachment cleanup service started")
-
-	// 启动工单超时自动关闭服务
-	ticketAutoCloseService := service.NewTicketAutoCloseService(db, cfg)
-	ticketAutoCloseService.SetPluginManager(pluginManagerService)
-	ticketAutoCloseService.Start()
-	defer ticketAutoCloseService.Stop()
-	log.Println("Ticket auto-close service started")
+
+	storeService := service.NewStoreService(db)
+	productPriceService := service.NewProductPriceService(db)
+
+	// 后台任务：api 模式不启动，多个 worker 之间通过 Redis 分布式锁协调
+	if cfg.App.RunsWorkers() {
+		// 启动邮件队列处理（如果启用）
+		emailService.Start()
+		defer emailService.Stop()
+		if emailService.IsEnabled() {
+			log.Println("Email service started")
+		}
+
+		smsDelayedCtx, smsDelayedCancel := context.WithCancel(context.Background())
+		defer smsDelayedCancel()
+		go smsService.ProcessDelayedSMS(smsDelayedCtx)
+		log.Println("SMS delayed worker started")
+
+		marketingService.Start()
+		defer marketingService.Stop()
+		log.Println("Marketing queue worker started")
+
+		serialGenerationService.Start()
+		defer serialGenerationService.Stop()
+		log.Println("Serial generation worker started")
+
+		// 后台任务统一调度器（付款轮询、自动取消、物流轨迹同步、附件清理、工单自动关闭、定时调价、虚拟库存有效期）
+		backgroundScheduler := service.NewBackgroundScheduler()
+		service.SetGlobalBackgroundScheduler(backgroundScheduler)
+		defer backgroundScheduler.Stop()
+
+		// 启动付款状态轮询服务
+		paymentPollingService.Start()
+		defer paymentPollingService.Stop()
+
+		// 启动订单自动取消服务
+		orderCancelService := service.NewOrderCancelService(db, cfg, inventoryRepo, promoCodeRepo, virtualInventoryService, serialService)
+		orderCancelService.SetPluginManager(pluginManagerService)
+		orderCancelService.SetEmailService(emailService)
+		orderCancelService.Start()
+		defer orderCancelService.Stop()
+		log.Println("Order auto-cancel service started")
+
+		// 启动出站 Webhook 失败重试服务
+		webhookService := service.NewWebhookService(db)
+		webhookService.Start()
+		defer webhookService.Stop()
+		log.Println("Webhook retry service started")
+
+		// 启动物流轨迹同步服务（补注册、轮询与签收后自动完成）
+		shipmentTrackingService := service.NewShipmentTrackingService(db, cfg, orderService)
+		shipmentTrackingService.Start()
+		defer shipmentTrackingService.Stop()
+		log.Println("Shipment tracking sync service started")
+
+		// 启动工单附件自动清理服务
+		ticketAttachmentCleanupService := service.NewTicketAttachmentCleanupService(db, cfg)
+		ticketAttachmentCleanupService.Start()
+		defer ticketAttachmentCleanupService.Stop()
+		log.Println("Ticket attachment cleanup service started")
+
+		// 启动工单超时自动关闭服务
+		ticketAutoCloseService := service.NewTicketAutoCloseService(db, cfg)
+		ticketAutoCloseService.SetPluginManager(pluginManagerService)
+		ticketAutoCloseService.Start()
+		defer ticketAutoCloseService.Stop()
+		log.Println("Ticket auto-close service started")
+
+		// 启动商品定时调价服务
+		productPriceService.Start()
+		defer productPriceService.Stop()
+		log.Println("Product price schedule service started")
+
+		// 启动虚拟库存有效期检查服务
+		virtualStockExpiryService := service.NewVirtualStockExpiryService(virtualInventoryService)
+		virtualStockExpiryService.Start()
+		defer virtualStockExpiryService.Stop()
+		log.Println("Virtual stock expiry service started")
+	}
+
+	// 多店铺与自定义域名（证书签发与 HTTP-01 验证随 API 进程运行）
+	domainService := service.NewDomainService(db, cfg, storeService)
+	if cfg.App.ServesAPI() {
+		domainService.Start()
+		defer domainService.Stop()
+		log.Println("Custom domain check service started")
+	}
+
+	// 启动汇率服务（付款脚本换算与订单多币种展示）
+	exchangeRateService := service.NewExchangeRateService(db, cfg)
+	service.SetGlobalExchangeRateService(exchangeRateService)
+	orderService.SetExchangeRateService(exchangeRateService)
+	exchangeRateService.Start()
+	defer exchangeRateService.Stop()
+	log.Println("Exchange rate service started")
+
+	if !cfg.App.ServesAPI() {
+		// worker 模式不监听端口，收到退出信号后依次停止后台任务
+		signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stopSignals()
+		log.Println("Worker is running, waiting for shutdown signal")
+		<-signalCtx.Done()
+		log.Println("Worker shutting down")
+		return
+	}
 
 	// 设置路由
-	r := router.SetupRouter(cfg, authService, orderService, productService, emailService, userRepo, db, paymentPollingService, pluginManagerService, GitCommit)
+	r := router.SetupRouter(cfg, authService, orderService, productService, emailService, userRepo, db, paymentPollingService, pluginManagerService, storeService, domainService, productPriceService, GitCommit)
 
 	// 启动服务器
 	addr := fmt.Sprintf(":%d", cfg.App.Port)
 	log.Printf("Server is running on %s", addr)
 	log.Printf("Environment: %s", cfg.App.Env)
 
-	if err := r.Run(addr); err != nil {
+	var handler http.Handler = r
+	if cfg.ACME.Enabled {
+		// HTTP 端口负责 ACME HTTP-01 验证，HTTPS 端口为已验证的自定义域名自动签发证书
+		handler = domainService.HTTPHandler(r)
+		httpsServer := &http.Server{
+			Addr:      fmt.Sprintf(":%d", cfg.ACME.HTTPSPort),
+			Handler:   r,
+			TLSConfig: domainService.TLSConfig(),
+		}
+		go func() {
+			log.Printf("HTTPS server is running on %s (ACME enabled)", httpsServer.Addr)
+			if err := httpsServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Printf("HTTPS server stopped: %v", err)
+			}
+		}()
+	}
+
+	if err := http.ListenAndServe(addr, handler); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
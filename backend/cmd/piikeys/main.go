@@ -0,0 +1,54 @@
+// piikeys 用当前 active_key_id 重新加密订单收件信息并重建盲索引。
+//
+// 轮换步骤：在 security.pii_encryption.keys 中新增密钥并切换 active_key_id（旧密钥保留），
+// 运行本命令直到完成，确认无误后再从配置中移除旧密钥。首次启用加密时也用它加密已有明文数据。
+//
+//	go run ./cmd/piikeys -batch 500
+package main
+
+import (
+	"flag"
+	"log"
+
+	"auralogic/internal/config"
+	"auralogic/internal/database"
+	adminHandler "auralogic/internal/handler/admin"
+	"auralogic/internal/pkg/piicrypt"
+	"auralogic/internal/service"
+)
+
+func main() {
+	batchSize := flag.Int("batch", 200, "rows per batch")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(config.GetConfigPath())
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if !cfg.Security.PIIEncryption.Enabled {
+		log.Fatalf("security.pii_encryption.enabled is false, nothing to rotate")
+	}
+	if err := piicrypt.Init(&cfg.Security.PIIEncryption); err != nil {
+		log.Fatalf("Failed to initialize PII encryption: %v", err)
+	}
+
+	if err := database.InitDatabase(&cfg.Database); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.Close()
+
+	// 确保盲索引列已存在
+	database.SetDefaultLandingPageHTML(adminHandler.DefaultLandingPageHTML)
+	if err := database.AutoMigrate(); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	result, err := service.RotateOrderPII(database.GetDB(), *batchSize)
+	if result != nil {
+		log.Printf("PII rotation (active key %s): scanned=%d updated=%d", result.ActiveKeyID, result.Scanned, result.Updated)
+	}
+	if err != nil {
+		log.Fatalf("PII rotation failed: %v", err)
+	}
+	log.Println("PII rotation complete")
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 	"sync"
@@ -40,6 +41,12 @@ type Config struct {
 	EmailNotifications EmailNotificationsConfig `json:"email_notifications"`
 	Analytics          AnalyticsConfig          `json:"analytics"`
 	Plugin             PluginPlatformConfig     `json:"plugin"`
+	ACME               ACMEConfig               `json:"acme"`
+	SEO                SEOConfig                `json:"seo"`
+	ExchangeRate       ExchangeRateConfig       `json:"exchange_rate"`
+	Telegram           TelegramConfig           `json:"telegram"`
+
+	ShipmentTracking ShipmentTrackingConfig `json:"shipment_tracking"`
 }
 
 // AppConfig 应用配置
@@ -50,6 +57,37 @@ type AppConfig struct {
 	URL          string `json:"url"`
 	Debug        bool   `json:"debug"`
 	DefaultTheme string `json:"default_theme"` // light, dark, system
+	Mode         string `json:"mode"`          // all（默认）、api、worker，可被 --mode 启动参数覆盖
+	DemoMode     bool   `json:"demo_mode"`     // 演示模式：不发送邮件/短信、拒绝对外 HTTP 请求，前端显示演示水印
+}
+
+// 进程运行模式：all 同时提供 API 与后台任务，api / worker 用于拆分部署
+const (
+	AppModeAll    = "all"
+	AppModeAPI    = "api"
+	AppModeWorker = "worker"
+)
+
+// NormalizeAppMode 规范化运行模式，空值视为 all
+func NormalizeAppMode(mode string) (string, error) {
+	switch normalized := strings.ToLower(strings.TrimSpace(mode)); normalized {
+	case "":
+		return AppModeAll, nil
+	case AppModeAll, AppModeAPI, AppModeWorker:
+		return normalized, nil
+	default:
+		return "", fmt.Errorf("app.mode must be one of all, api, worker")
+	}
+}
+
+// ServesAPI 当前进程是否提供 HTTP API
+func (c AppConfig) ServesAPI() bool {
+	return c.Mode != AppModeWorker
+}
+
+// RunsWorkers 当前进程是否运行后台任务
+func (c AppConfig) RunsWorkers() bool {
+	return c.Mode != AppModeAPI
 }
 
 // DatabaseConfig 数据库配置
@@ -64,10 +102,13 @@ type DatabaseConfig struct {
 	MaxIdleConns    int    `json:"max_idle_conns"`
 	MaxOpenConns    int    `json:"max_open_conns"`
 	ConnMaxLifetime int    `json:"conn_max_lifetime"`
+	// 慢查询阈值（毫秒），0 使用默认 200ms，负数关闭慢查询记录
+	SlowQueryThresholdMs int `json:"slow_query_threshold_ms"`
 }
 
 // RedisConfig Redis配置
 type RedisConfig struct {
+	Enabled  *bool  `json:"enabled,omitempty"` // nil=默认启用；false 时使用进程内缓存，仅适合单进程小型部署
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
 	Password string `json:"password"`
@@ -154,6 +195,10 @@ type LoginConfig struct {
 	AllowPhoneLogin          bool `json:"allow_phone_login"`
 	AllowPhoneRegister       bool `json:"allow_phone_register"`
 	AllowPhonePasswordReset  bool `json:"allow_phone_password_reset"`
+
+	// 邮箱验证强制方式：login(未验证禁止登录)、checkout(仅禁止下单)、grace(宽限期内仅禁止下单，过期后禁止登录)
+	EmailVerificationMode      string `json:"email_verification_mode"`
+	EmailVerificationGraceDays int    `json:"email_verification_grace_days"`
 }
 
 // PasswordPolicyConfig Password策略配置
@@ -167,23 +212,132 @@ type PasswordPolicyConfig struct {
 
 // CaptchaConfig 验证码配置
 type CaptchaConfig struct {
-	Provider              string `json:"provider"`                 // none, cloudflare, google, builtin
-	SiteKey               string `json:"site_key"`                 // Cloudflare/Google 的站点密钥
-	SecretKey             string `json:"secret_key"`               // Cloudflare/Google 的服务端密钥
-	EnableForLogin        bool   `json:"enable_for_login"`         // 登录时是否需要验证码
-	EnableForRegister     bool   `json:"enable_for_register"`      // 注册时是否需要验证码
-	EnableForSerialVerify bool   `json:"enable_for_serial_verify"` // 序列号验证时是否需要验证码
-	EnableForBind         bool   `json:"enable_for_bind"`          // 绑定邮箱/手机时是否需要验证码
+	Provider              string                         `json:"provider"`                 // none, cloudflare (Turnstile), google (reCAPTCHA v2), recaptcha_v3, hcaptcha, builtin
+	SiteKey               string                         `json:"site_key"`                 // 第三方驱动的站点密钥
+	SecretKey             string                         `json:"secret_key"`               // 第三方驱动的服务端密钥
+	RecaptchaMinScore     float64                        `json:"recaptcha_min_score"`      // reCAPTCHA v3 最低通过分数（0-1），默认 0.5
+	SceneProviders        map[string]string              `json:"scene_providers"`          // 按场景（login/register/serial_verify/bind）覆盖驱动
+	ProviderKeys          map[string]CaptchaProviderKeys `json:"provider_keys"`            // 场景覆盖驱动使用的密钥，按驱动名存放
+	EnableForLogin        bool                           `json:"enable_for_login"`         // 登录时是否需要验证码
+	EnableForRegister     bool                           `json:"enable_for_register"`      // 注册时是否需要验证码
+	EnableForSerialVerify bool                           `json:"enable_for_serial_verify"` // 序列号验证时是否需要验证码
+	EnableForBind         bool                           `json:"enable_for_bind"`          // 绑定邮箱/手机时是否需要验证码
+}
+
+// CaptchaProviderKeys 单个验证码驱动的密钥
+type CaptchaProviderKeys struct {
+	SiteKey   string `json:"site_key"`
+	SecretKey string `json:"secret_key"`
+}
+
+// ResolveScene 返回场景实际使用的驱动及其密钥；场景覆盖的驱动优先使用 provider_keys，与主驱动相同时回退到 site_key/secret_key
+func (c CaptchaConfig) ResolveScene(scene string) (string, CaptchaProviderKeys) {
+	provider := strings.TrimSpace(c.Provider)
+	if override := strings.TrimSpace(c.SceneProviders[scene]); override != "" {
+		provider = override
+	}
+	if keys, ok := c.ProviderKeys[provider]; ok && strings.TrimSpace(keys.SiteKey) != "" {
+		return provider, keys
+	}
+	if provider == strings.TrimSpace(c.Provider) {
+		return provider, CaptchaProviderKeys{SiteKey: c.SiteKey, SecretKey: c.SecretKey}
+	}
+	return provider, CaptchaProviderKeys{}
 }
 
 // SecurityConfig 安全配置
 type SecurityConfig struct {
-	CORS           CORSConfig           `json:"cors"`
-	Login          LoginConfig          `json:"login"`
-	PasswordPolicy PasswordPolicyConfig `json:"password_policy"`
-	Captcha        CaptchaConfig        `json:"captcha"`
-	IPHeader       string               `json:"ip_header"`       // 获取真实IP的header名称，如 "CF-Connecting-IP", "X-Real-IP", "X-Forwarded-For"
-	TrustedProxies []string             `json:"trusted_proxies"` // Trusted reverse proxies CIDRs/IPs. Only trusted peers can supply IPHeader.
+	CORS              CORSConfig              `json:"cors"`
+	Login             LoginConfig             `json:"login"`
+	PasswordPolicy    PasswordPolicyConfig    `json:"password_policy"`
+	Captcha           CaptchaConfig           `json:"captcha"`
+	IPHeader          string                  `json:"ip_header"`       // 获取真实IP的header名称，如 "CF-Connecting-IP", "X-Real-IP", "X-Forwarded-For"
+	TrustedProxies    []string                `json:"trusted_proxies"` // Trusted reverse proxies CIDRs/IPs. Only trusted peers can supply IPHeader.
+	PIIEncryption     PIIEncryptionConfig     `json:"pii_encryption"`
+	LoginProtection   LoginProtectionConfig   `json:"login_protection"`
+	ScraperProtection ScraperProtectionConfig `json:"scraper_protection"`
+	SessionCookie     SessionCookieConfig     `json:"session_cookie"`
+	CSP               CSPConfig               `json:"csp"`
+	Approval          ApprovalConfig          `json:"approval"`
+	// 付款脚本出站 HTTP 严格模式：开启后未配置主机白名单的付款方式不能发起外部请求
+	PaymentHTTPStrictAllowlist bool `json:"payment_http_strict_allowlist"`
+}
+
+// ApprovalConfig 危险操作双人审批（四眼原则），各阈值 <= 0 表示该类操作不需要审批
+type ApprovalConfig struct {
+	Enabled               bool    `json:"enabled"`
+	RefundAmountThreshold int64   `json:"refund_amount_threshold"` // 退款金额（最小货币单位）达到该值需审批
+	BulkDeleteThreshold   int     `json:"bulk_delete_threshold"`   // 批量删除条数达到该值需审批
+	PriceChangePercent    float64 `json:"price_change_percent"`    // 商品/订单改价幅度超过该百分比需审批
+	ExpireMinutes         int     `json:"expire_minutes"`          // 审批单有效期，过期后需重新发起
+}
+
+// CSPConfig 内容安全策略（Content-Security-Policy）
+// 未启用时沿用内置的宽松策略；启用后按 directives 生成，并按 routes 对匹配路径覆盖指令
+type CSPConfig struct {
+	Enabled        bool              `json:"enabled"`
+	ReportOnly     bool              `json:"report_only"`     // 使用 Content-Security-Policy-Report-Only，只上报不拦截
+	Nonce          bool              `json:"nonce"`           // 为 script-src/style-src 追加每请求 nonce，服务端注入的脚本和样式自动带上
+	Directives     map[string]string `json:"directives"`      // 指令 → 来源列表，如 "script-src": "'self' https://cdn.example.com"
+	Routes         []CSPRoutePolicy  `json:"routes"`          // 按请求路径覆盖指令，按顺序第一个匹配的生效
+	CollectReports bool              `json:"collect_reports"` // 记录违规报告，report_uri 为空时指向 /api/csp-report
+	ReportURI      string            `json:"report_uri"`      // 自定义上报地址（外部收集服务）
+}
+
+// CSPRoutePolicy 路径级 CSP 覆盖，directives 中值为空字符串表示移除该指令
+type CSPRoutePolicy struct {
+	Name       string            `json:"name"`
+	Pattern    string            `json:"pattern"`
+	MatchType  string            `json:"match_type"` // exact | prefix | regex
+	Directives map[string]string `json:"directives"`
+}
+
+// SessionCookieConfig 可选的浏览器 Cookie 会话；客户端通过 X-Auth-Mode: cookie 登录时令牌写入 HttpOnly Cookie，
+// 未携带该请求头的 API 客户端仍使用 Bearer Token
+type SessionCookieConfig struct {
+	Enabled        bool   `json:"enabled"`
+	Name           string `json:"name"` // 会话 Cookie 名称
+	Domain         string `json:"domain"`
+	Path           string `json:"path"`
+	Secure         bool   `json:"secure"`           // 生产环境必须开启（HTTPS）
+	SameSite       string `json:"same_site"`        // lax / strict / none
+	CSRFStrategy   string `json:"csrf_strategy"`    // double_submit：校验 CSRF Cookie 与请求头一致；same_site：依赖 SameSite 并校验 Origin
+	CSRFCookieName string `json:"csrf_cookie_name"` // double_submit 使用的可读 Cookie
+	CSRFHeaderName string `json:"csrf_header_name"`
+}
+
+// LoginProtectionConfig 登录保护：连续失败锁定账户、撞库自动封禁 IP
+// 管理员手动封禁的 IP 不受 enabled 影响，始终生效
+type LoginProtectionConfig struct {
+	Enabled                bool `json:"enabled"`
+	MaxFailedAttempts      int  `json:"max_failed_attempts"`      // 连续失败次数达到后锁定账户
+	LockoutMinutes         int  `json:"lockout_minutes"`          // 首次锁定时长，之后每次锁定翻倍
+	MaxLockoutMinutes      int  `json:"max_lockout_minutes"`      // 锁定时长上限
+	StuffingDistinctEmails int  `json:"stuffing_distinct_emails"` // 同一 IP 在窗口内登录失败的不同账户数达到后自动封禁
+	StuffingWindowMinutes  int  `json:"stuffing_window_minutes"`
+	AutoBanMinutes         int  `json:"auto_ban_minutes"` // 自动封禁时长，0 表示永久
+}
+
+// ScraperProtectionConfig 商品目录防爬：按 IP 突发检测、蜜罐参数和可选的 JS 挑战令牌
+type ScraperProtectionConfig struct {
+	Enabled             bool     `json:"enabled"`
+	BurstRequests       int      `json:"burst_requests"`       // 同一 IP 在窗口内访问目录接口的请求数上限
+	BurstWindowSeconds  int      `json:"burst_window_seconds"` // 突发检测窗口
+	HoneypotParams      []string `json:"honeypot_params"`      // 正常前端从不携带的查询参数，命中即判定为爬虫
+	HoneypotAutoBan     bool     `json:"honeypot_auto_ban"`    // 命中蜜罐时自动封禁 IP
+	AutoBanMinutes      int      `json:"auto_ban_minutes"`     // 自动封禁时长，0 表示永久
+	ChallengeEnabled    bool     `json:"challenge_enabled"`    // 要求请求携带 JS 挑战令牌（X-Catalog-Token）
+	ChallengeDifficulty int      `json:"challenge_difficulty"` // 工作量证明的前导零比特数
+	ChallengeTTLMinutes int      `json:"challenge_ttl_minutes"`
+}
+
+// PIIEncryptionConfig 收件人联系信息的应用层加密
+// 密钥均为 base64 编码的 32 字节随机值；轮换时新增密钥并切换 active_key_id，再运行 cmd/piikeys 重新加密
+type PIIEncryptionConfig struct {
+	Enabled       bool              `json:"enabled"`
+	ActiveKeyID   string            `json:"active_key_id"`
+	Keys          map[string]string `json:"keys"`            // key id → base64 密钥，旧密钥保留到重新加密完成
+	BlindIndexKey string            `json:"blind_index_key"` // 邮箱/电话盲索引的 HMAC 密钥
 }
 
 // MessageRateLimit 邮件/短信发送频率限制
@@ -309,11 +463,28 @@ type OrderHighConcurrencyProtectionConfig struct {
 	MaxInFlight   int    `json:"max_inflight"`    // 每条热点链路允许的最大并发写入数
 	WaitTimeoutMs int    `json:"wait_timeout_ms"` // 等待获取并发槽位的超时时间
 	RedisLeaseMs  int    `json:"redis_lease_ms"`  // Redis 分布式槽位租约时长
+	// 单个用户/IP 同时处理中的下单请求上限，0 表示不限制
+	MaxInFlightPerUser int `json:"max_inflight_per_user"`
+	MaxInFlightPerIP   int `json:"max_inflight_per_ip"`
+	// 盲盒随机分配链路的全局并发上限，用于控制抢购时的库存行锁竞争
+	BlindBoxMaxInFlight int `json:"blind_box_max_inflight"`
+}
+
+// OrderWaitingRoomConfig 抢购排队（虚拟等候室）配置
+type OrderWaitingRoomConfig struct {
+	Enabled             bool    `json:"enabled"`
+	AllProducts         bool    `json:"all_products"`          // true 时整站下单都需排队，否则仅对开启排队的商品生效
+	AdmitPerSecond      float64 `json:"admit_per_second"`      // 令牌桶每秒放行人数
+	Burst               int     `json:"burst"`                 // 令牌桶容量（允许瞬时放行人数）
+	AdmissionTTLSeconds int     `json:"admission_ttl_seconds"` // 放行凭证有效期
+	AbandonAfterSeconds int     `json:"abandon_after_seconds"` // 排队中超过该时长未轮询视为放弃
 }
 
 type OrderConfig struct {
 	NoPrefix                       string                               `json:"no_prefix"`
 	AutoCancelHours                int                                  `json:"auto_cancel_hours"`
+	DraftExpireHours               int                                  `json:"draft_expire_hours"`     // API 草稿订单表单链接过期后再等待多少小时自动取消，0 表示不自动取消
+	PaymentReminderHours           int                                  `json:"payment_reminder_hours"` // 待付款订单在自动取消前多少小时发送付款提醒，0 表示不提醒
 	MaxPendingPaymentOrdersPerUser int                                  `json:"max_pending_payment_orders_per_user"`
 	MaxPaymentPollingTasksPerUser  int                                  `json:"max_payment_polling_tasks_per_user"`
 	MaxPaymentPollingTasksGlobal   int                                  `json:"max_payment_polling_tasks_global"`
@@ -323,10 +494,51 @@ type OrderConfig struct {
 	ShowVirtualStockRemark         bool                                 `json:"show_virtual_stock_remark"` // 是否在用户侧显示虚拟产品备注
 	EnableVirtualStockInlineIframe bool                                 `json:"enable_virtual_stock_inline_iframe"`
 	StockDisplay                   StockDisplayConfig                   `json:"stock_display"`
-	VirtualDeliveryOrder           string                               `json:"virtual_delivery_order"`        // 虚拟库存发货顺序: random(随机), newest(先发新库存), oldest(先发老库存)
-	VirtualScriptTimeoutMaxMs      int                                  `json:"virtual_script_timeout_max_ms"` // 虚拟脚本发货允许的最大执行时长
+	VirtualDeliveryOrder           string                               `json:"virtual_delivery_order"`            // 虚拟库存发货顺序: random(随机), newest(先发新库存), oldest(先发老库存), expiring(先发临期库存)；可被虚拟库存/绑定单独覆盖
+	VirtualScriptTimeoutMaxMs      int                                  `json:"virtual_script_timeout_max_ms"`     // 虚拟脚本发货允许的最大执行时长
+	VirtualStockExpiryWarningDays  int                                  `json:"virtual_stock_expiry_warning_days"` // 卡密到期前多少天标记为即将过期
+	CartReservationTTLSeconds      int                                  `json:"cart_reservation_ttl_seconds"`      // 开启"加购即预留"的商品，购物车预留的有效时长
+	TierPricingPromoStacking       string                               `json:"tier_pricing_promo_stacking"`       // 阶梯价与优惠码叠加规则: stack(叠加), exclusive(互斥，拒绝优惠码), best(取两者中更优惠的一种)
 	Invoice                        InvoiceConfig                        `json:"invoice"`
 	HighConcurrencyProtection      OrderHighConcurrencyProtectionConfig `json:"high_concurrency_protection"`
+	WaitingRoom                    OrderWaitingRoomConfig               `json:"waiting_room"`
+	Timeline                       OrderTimelineConfig                  `json:"timeline"`      // 用户侧订单时间线预估
+	Customs                        CustomsConfig                        `json:"customs"`       // 国际件报关单
+	PackageBoxes                   []PackageBox                         `json:"package_boxes"` // 包装箱目录，用于订单装箱建议
+}
+
+// PackageBox 包装箱规格，尺寸为内尺寸（毫米）
+type PackageBox struct {
+	Code           string `json:"code"`
+	Name           string `json:"name"`
+	LengthMM       int    `json:"length_mm"`
+	WidthMM        int    `json:"width_mm"`
+	HeightMM       int    `json:"height_mm"`
+	MaxWeightGrams int    `json:"max_weight_grams"` // 0 表示不限
+	TareGrams      int    `json:"tare_grams"`       // 箱子自重，计入包裹重量
+}
+
+// CustomsConfig 国际件报关单（CN22/CN23），发件人信息沿用账单公司信息
+type CustomsConfig struct {
+	SenderCountry        string `json:"sender_country"`         // 发件国家，收件国家不同即为国际件
+	DefaultOriginCountry string `json:"default_origin_country"` // 商品未设置原产国时使用，为空时取发件国家
+	ContentCategory      string `json:"content_category"`       // sale_of_goods, gift, sample, documents, returned_goods, other
+	CN22MaxValueMinor    int64  `json:"cn22_max_value_minor"`   // 申报总值不超过该值使用 CN22，否则 CN23
+}
+
+// OrderTimelineConfig 用户侧订单时间线的预计发货/送达时间
+type OrderTimelineConfig struct {
+	DefaultShipWithinDays  int                    `json:"default_ship_within_days"`  // 商品未设置发货时效时的默认天数
+	DefaultDeliveryMinDays int                    `json:"default_delivery_min_days"` // 未匹配国家时的最短送达天数
+	DefaultDeliveryMaxDays int                    `json:"default_delivery_max_days"` // 未匹配国家时的最长送达天数
+	DeliveryEstimates      []DeliveryEstimateRule `json:"delivery_estimates"`        // 按收货国家配置的送达时效
+}
+
+// DeliveryEstimateRule 按国家配置的送达时效（自发货起计算）
+type DeliveryEstimateRule struct {
+	Country string `json:"country"` // 国家代码，如 CN、US
+	MinDays int    `json:"min_days"`
+	MaxDays int    `json:"max_days"`
 }
 
 // InvoiceConfig 账单/发票配置
@@ -358,14 +570,23 @@ type MagicLinkConfig struct {
 
 // FormConfig 表单配置
 type FormConfig struct {
-	ExpireHours int `json:"expire_hours"`
+	ExpireHours       int      `json:"expire_hours"`
+	DisabledCountries []string `json:"disabled_countries"` // 不配送的国家/地区代码
 }
 
 // UploadConfig 文件上传配置
 type UploadConfig struct {
-	Dir          string   `json:"dir"`           // 上传目录
-	MaxSize      int64    `json:"max_size"`      // 最大文件大小（字节）
-	AllowedTypes []string `json:"allowed_types"` // 允许的文件类型
+	Dir            string                  `json:"dir"`             // 上传目录
+	MaxSize        int64                   `json:"max_size"`        // 最大文件大小（字节）
+	AllowedTypes   []string                `json:"allowed_types"`   // 允许的文件类型
+	ProductHotlink HotlinkProtectionConfig `json:"product_hotlink"` // 商品图片防盗链
+}
+
+// HotlinkProtectionConfig 按 Referer 限制静态资源引用，本站域名和 CORS 白名单始终允许
+type HotlinkProtectionConfig struct {
+	Enabled           bool     `json:"enabled"`
+	AllowedDomains    []string `json:"allowed_domains"`     // 额外允许的域名，支持 *.example.com
+	BlockEmptyReferer bool     `json:"block_empty_referer"` // 拒绝无 Referer 的请求（直接访问、部分 App 内浏览器）
 }
 
 // TicketAttachmentConfig 工单附件配置
@@ -377,6 +598,7 @@ type TicketAttachmentConfig struct {
 	MaxVoiceDuration  int      `json:"max_voice_duration"`  // 最大语音时长（秒）
 	AllowedImageTypes []string `json:"allowed_image_types"` // 允许的图片类型
 	RetentionDays     int      `json:"retention_days"`      // 附件保存天数，0表示永久保存
+	SignedURLTTL      int      `json:"signed_url_ttl"`      // 附件签名链接有效期（分钟），默认 60
 }
 
 // TicketConfig 工单配置
@@ -406,17 +628,19 @@ type PageRule struct {
 
 // EmailNotificationsConfig 邮件通知配置
 type EmailNotificationsConfig struct {
-	UserRegister     bool `json:"user_register"`      // 用户注册欢迎邮件
-	OrderCreated     bool `json:"order_created"`      // 订单创建/表单提交
-	OrderPaid        bool `json:"order_paid"`         // 付款确认
-	OrderShipped     bool `json:"order_shipped"`      // 订单发货
-	OrderCompleted   bool `json:"order_completed"`    // 订单完成
-	OrderCancelled   bool `json:"order_cancelled"`    // 订单取消
-	OrderResubmit    bool `json:"order_resubmit"`     // 需要重填信息
-	TicketCreated    bool `json:"ticket_created"`     // 新工单（通知管理员）
-	TicketAdminReply bool `json:"ticket_admin_reply"` // 客服回复（通知用户）
-	TicketUserReply  bool `json:"ticket_user_reply"`  // 用户回复（通知管理员）
-	TicketResolved   bool `json:"ticket_resolved"`    // 工单已解决
+	UserRegister      bool `json:"user_register"`       // 用户注册欢迎邮件
+	OrderCreated      bool `json:"order_created"`       // 订单创建/表单提交
+	OrderPaid         bool `json:"order_paid"`          // 付款确认
+	OrderShipped      bool `json:"order_shipped"`       // 订单发货
+	OrderCompleted    bool `json:"order_completed"`     // 订单完成
+	OrderCancelled    bool `json:"order_cancelled"`     // 订单取消
+	OrderResubmit     bool `json:"order_resubmit"`      // 需要重填信息
+	OrderMessageUser  bool `json:"order_message_user"`  // 用户订单留言（通知管理员）
+	OrderMessageAdmin bool `json:"order_message_admin"` // 卖家回复订单留言（通知用户）
+	TicketCreated     bool `json:"ticket_created"`      // 新工单（通知管理员）
+	TicketAdminReply  bool `json:"ticket_admin_reply"`  // 客服回复（通知用户）
+	TicketUserReply   bool `json:"ticket_user_reply"`   // 用户回复（通知管理员）
+	TicketResolved    bool `json:"ticket_resolved"`     // 工单已解决
 }
 
 // AuthBrandingConfig 认证页品牌面板配置
@@ -443,6 +667,52 @@ type AnalyticsConfig struct {
 	Enabled bool `json:"enabled"` // 是否启用数据分析功能
 }
 
+// ACMEConfig 自定义域名证书自动签发配置（HTTP-01 验证）
+type ACMEConfig struct {
+	Enabled              bool   `json:"enabled"`                // 是否启用 HTTPS 监听与证书自动签发，修改后需重启
+	Email                string `json:"email"`                  // ACME 账户联系邮箱
+	CacheDir             string `json:"cache_dir"`              // 证书与账户密钥缓存目录
+	HTTPSPort            int    `json:"https_port"`             // HTTPS 监听端口
+	DirectoryURL         string `json:"directory_url"`          // ACME 目录地址，为空使用 Let's Encrypt 生产环境
+	RenewBeforeDays      int    `json:"renew_before_days"`      // 证书到期前多少天续期
+	CheckIntervalMinutes int    `json:"check_interval_minutes"` // 域名验证与证书巡检间隔
+}
+
+// SEOConfig 搜索引擎优化配置
+type SEOConfig struct {
+	SitemapEnabled   bool     `json:"sitemap_enabled"`   // 生成 /sitemap.xml（商品需开启访客浏览才会收录）
+	IncludeKnowledge bool     `json:"include_knowledge"` // sitemap 收录知识库文章
+	DefaultOGImage   string   `json:"default_og_image"`  // 商品未设置 OG 图片且无主图时使用
+	RobotsDisallow   []string `json:"robots_disallow"`   // robots.txt 额外禁止抓取的路径
+}
+
+// ExchangeRateConfig 汇率服务配置，供付款脚本换算与订单多币种展示使用
+type ExchangeRateConfig struct {
+	Enabled           bool     `json:"enabled"`
+	FiatProvider      string   `json:"fiat_provider"`       // 法币汇率源: exchangerate_host
+	FiatAPIKey        string   `json:"fiat_api_key"`        // exchangerate.host access_key
+	CryptoProvider    string   `json:"crypto_provider"`     // 加密货币汇率源: coingecko
+	CryptoAPIKey      string   `json:"crypto_api_key"`      // CoinGecko Demo API Key，可为空
+	CacheMinutes      int      `json:"cache_minutes"`       // 汇率缓存时长，默认 10 分钟
+	StaleAlertMinutes int      `json:"stale_alert_minutes"` // 超过该时长未成功刷新视为过期并告警，默认 60 分钟
+	DisplayCurrencies []string `json:"display_currencies"`  // 订单详情额外展示的换算币种，如 USD、EUR
+}
+
+// ShipmentTrackingConfig 物流轨迹同步配置，发货后向轨迹服务商注册单号并同步签收状态
+type ShipmentTrackingConfig struct {
+	Enabled             bool   `json:"enabled"`
+	Provider            string `json:"provider"`              // 轨迹服务商: 17track | aftership
+	APIKey              string `json:"api_key"`               // 服务商 API Key
+	WebhookSecret       string `json:"webhook_secret"`        // AfterShip Webhook 签名密钥；17track 使用 API Key 验签，可为空
+	PollIntervalMinutes int    `json:"poll_interval_minutes"` // 未签收单号的轮询间隔，默认 360 分钟
+	AutoCompleteDays    int    `json:"auto_complete_days"`    // 签收满 N 天后自动完成订单，0 表示不自动完成
+}
+
+// TelegramConfig Telegram 机器人配置，用于管理员订阅通知
+type TelegramConfig struct {
+	BotToken string `json:"bot_token"` // BotFather 颁发的机器人 Token，为空时不可选择 Telegram 渠道
+}
+
 // PluginSandboxConfig 插件沙箱配置
 type PluginSandboxConfig struct {
 	Level              string   `json:"level"`                 // strict | balanced | permissive
@@ -600,7 +870,10 @@ func ReloadConfig() error {
 	defer mu.Unlock()
 
 	// 直接更新实例的各个字段（保持指针不变）
+	// 运行模式（含 --mode 参数覆盖）需重启生效
+	mode := instance.App.Mode
 	instance.App = cfg.App
+	instance.App.Mode = mode
 	instance.SMTP = cfg.SMTP
 	instance.SMS = cfg.SMS
 	instance.Security = cfg.Security
@@ -619,11 +892,24 @@ func ReloadConfig() error {
 	instance.EmailNotifications = cfg.EmailNotifications
 	instance.Analytics = cfg.Analytics
 	instance.Plugin = cfg.Plugin
+	instance.ACME = cfg.ACME
+	instance.SEO = cfg.SEO
+	instance.ExchangeRate = cfg.ExchangeRate
+	instance.ShipmentTracking = cfg.ShipmentTracking
 	// 注意：Database、Redis、JWT 通常需要重启才能生效，这里不更新
 
 	return nil
 }
 
+// ValidateConfigJSON 校验配置文件内容但不影响当前生效的配置，用于导入前预检
+func ValidateConfigJSON(data []byte) error {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	return cfg.Validate()
+}
+
 // Validate 验证配置
 func (c *Config) Validate() error {
 	// 验证应用配置
@@ -633,6 +919,11 @@ func (c *Config) Validate() error {
 	if c.App.Port == 0 {
 		c.App.Port = 8080
 	}
+	mode, err := NormalizeAppMode(c.App.Mode)
+	if err != nil {
+		return err
+	}
+	c.App.Mode = mode
 
 	// 验证数据库配置
 	if c.Database.Driver == "" {
@@ -661,6 +952,14 @@ func (c *Config) Validate() error {
 	if c.Security.CORS.MaxAge < 0 {
 		return fmt.Errorf("security.cors.max_age must be greater than or equal to 0")
 	}
+	if c.Security.PIIEncryption.Enabled {
+		if _, ok := c.Security.PIIEncryption.Keys[c.Security.PIIEncryption.ActiveKeyID]; !ok {
+			return fmt.Errorf("security.pii_encryption.active_key_id must reference a key in security.pii_encryption.keys")
+		}
+		if c.Security.PIIEncryption.BlindIndexKey == "" {
+			return fmt.Errorf("security.pii_encryption.blind_index_key is required when encryption is enabled")
+		}
+	}
 
 	// 设置默认值
 	if c.JWT.ExpireHours == 0 {
@@ -690,9 +989,51 @@ func (c *Config) Validate() error {
 	if c.Order.VirtualScriptTimeoutMaxMs < 100 {
 		c.Order.VirtualScriptTimeoutMaxMs = 100
 	}
+	if c.Order.VirtualStockExpiryWarningDays <= 0 {
+		c.Order.VirtualStockExpiryWarningDays = 7
+	}
+	if c.Order.CartReservationTTLSeconds <= 0 {
+		c.Order.CartReservationTTLSeconds = 600
+	}
+	switch c.Order.TierPricingPromoStacking {
+	case "stack", "exclusive", "best":
+	default:
+		c.Order.TierPricingPromoStacking = "stack"
+	}
 	if c.Order.MaxOrderItems == 0 {
 		c.Order.MaxOrderItems = 100
 	}
+	c.Order.Customs.SenderCountry = strings.ToUpper(strings.TrimSpace(c.Order.Customs.SenderCountry))
+	if c.Order.Customs.SenderCountry == "" {
+		c.Order.Customs.SenderCountry = "CN"
+	}
+	c.Order.Customs.DefaultOriginCountry = strings.ToUpper(strings.TrimSpace(c.Order.Customs.DefaultOriginCountry))
+	if c.Order.Customs.ContentCategory == "" {
+		c.Order.Customs.ContentCategory = "sale_of_goods"
+	}
+	if c.Order.Customs.CN22MaxValueMinor <= 0 {
+		// UPU 规定 CN22 适用于申报价值不超过 300 SDR 的邮件，按订单币种近似
+		c.Order.Customs.CN22MaxValueMinor = 30000
+	}
+	if c.Order.Timeline.DefaultShipWithinDays <= 0 {
+		c.Order.Timeline.DefaultShipWithinDays = 3
+	}
+	if c.Order.Timeline.DefaultDeliveryMinDays <= 0 {
+		c.Order.Timeline.DefaultDeliveryMinDays = 3
+	}
+	if c.Order.Timeline.DefaultDeliveryMaxDays < c.Order.Timeline.DefaultDeliveryMinDays {
+		c.Order.Timeline.DefaultDeliveryMaxDays = c.Order.Timeline.DefaultDeliveryMinDays + 4
+	}
+	for i := range c.Order.Timeline.DeliveryEstimates {
+		rule := &c.Order.Timeline.DeliveryEstimates[i]
+		rule.Country = strings.ToUpper(strings.TrimSpace(rule.Country))
+		if rule.MinDays < 0 {
+			rule.MinDays = 0
+		}
+		if rule.MaxDays < rule.MinDays {
+			rule.MaxDays = rule.MinDays
+		}
+	}
 	if c.Order.MaxItemQuantity == 0 {
 		c.Order.MaxItemQuantity = 9999
 	}
@@ -722,6 +1063,27 @@ func (c *Config) Validate() error {
 	if c.Order.HighConcurrencyProtection.RedisLeaseMs <= 0 {
 		c.Order.HighConcurrencyProtection.RedisLeaseMs = 30000
 	}
+	if c.Order.HighConcurrencyProtection.MaxInFlightPerUser < 0 {
+		c.Order.HighConcurrencyProtection.MaxInFlightPerUser = 0
+	}
+	if c.Order.HighConcurrencyProtection.MaxInFlightPerIP < 0 {
+		c.Order.HighConcurrencyProtection.MaxInFlightPerIP = 0
+	}
+	if c.Order.HighConcurrencyProtection.BlindBoxMaxInFlight < 0 {
+		c.Order.HighConcurrencyProtection.BlindBoxMaxInFlight = 0
+	}
+	if c.Order.WaitingRoom.AdmitPerSecond <= 0 {
+		c.Order.WaitingRoom.AdmitPerSecond = 5
+	}
+	if c.Order.WaitingRoom.Burst <= 0 {
+		c.Order.WaitingRoom.Burst = 10
+	}
+	if c.Order.WaitingRoom.AdmissionTTLSeconds <= 0 {
+		c.Order.WaitingRoom.AdmissionTTLSeconds = 600
+	}
+	if c.Order.WaitingRoom.AbandonAfterSeconds <= 0 {
+		c.Order.WaitingRoom.AbandonAfterSeconds = 60
+	}
 	if c.RateLimit.OrderCreate == 0 {
 		c.RateLimit.OrderCreate = 30
 	}
@@ -731,6 +1093,56 @@ func (c *Config) Validate() error {
 	if c.RateLimit.PaymentSelect == 0 {
 		c.RateLimit.PaymentSelect = 60
 	}
+	switch c.Security.Login.EmailVerificationMode {
+	case "login", "checkout", "grace":
+	default:
+		c.Security.Login.EmailVerificationMode = "login"
+	}
+	if c.Security.Login.EmailVerificationGraceDays <= 0 {
+		c.Security.Login.EmailVerificationGraceDays = 7
+	}
+	if c.Security.LoginProtection.MaxFailedAttempts <= 0 {
+		c.Security.LoginProtection.MaxFailedAttempts = 5
+	}
+	if c.Security.LoginProtection.LockoutMinutes <= 0 {
+		c.Security.LoginProtection.LockoutMinutes = 15
+	}
+	if c.Security.LoginProtection.MaxLockoutMinutes < c.Security.LoginProtection.LockoutMinutes {
+		c.Security.LoginProtection.MaxLockoutMinutes = 24 * 60
+	}
+	if c.Security.LoginProtection.StuffingDistinctEmails <= 0 {
+		c.Security.LoginProtection.StuffingDistinctEmails = 10
+	}
+	if c.Security.LoginProtection.StuffingWindowMinutes <= 0 {
+		c.Security.LoginProtection.StuffingWindowMinutes = 10
+	}
+	if c.Security.LoginProtection.AutoBanMinutes < 0 {
+		c.Security.LoginProtection.AutoBanMinutes = 0
+	}
+	if c.Security.ScraperProtection.BurstRequests <= 0 {
+		c.Security.ScraperProtection.BurstRequests = 120
+	}
+	if c.Security.ScraperProtection.BurstWindowSeconds <= 0 {
+		c.Security.ScraperProtection.BurstWindowSeconds = 60
+	}
+	if c.Security.ScraperProtection.AutoBanMinutes < 0 {
+		c.Security.ScraperProtection.AutoBanMinutes = 0
+	}
+	if c.Security.ScraperProtection.ChallengeDifficulty <= 0 || c.Security.ScraperProtection.ChallengeDifficulty > 24 {
+		c.Security.ScraperProtection.ChallengeDifficulty = 16
+	}
+	if c.Security.ScraperProtection.ChallengeTTLMinutes <= 0 {
+		c.Security.ScraperProtection.ChallengeTTLMinutes = 30
+	}
+	if c.Security.Approval.ExpireMinutes <= 0 {
+		c.Security.Approval.ExpireMinutes = 24 * 60
+	}
+	if err := c.Security.SessionCookie.applyDefaults(); err != nil {
+		return err
+	}
+	if err := c.Security.CSP.applyDefaults(); err != nil {
+		return err
+	}
 	if c.MagicLink.ExpireMinutes == 0 {
 		c.MagicLink.ExpireMinutes = 15
 	}
@@ -747,6 +1159,20 @@ func (c *Config) Validate() error {
 		c.Upload.AllowedTypes = []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
 	}
 
+	// 证书自动签发默认配置
+	if c.ACME.CacheDir == "" {
+		c.ACME.CacheDir = filepath.Join("data", "acme")
+	}
+	if c.ACME.HTTPSPort <= 0 {
+		c.ACME.HTTPSPort = 443
+	}
+	if c.ACME.RenewBeforeDays <= 0 {
+		c.ACME.RenewBeforeDays = 30
+	}
+	if c.ACME.CheckIntervalMinutes <= 0 {
+		c.ACME.CheckIntervalMinutes = 60
+	}
+
 	// 工单附件默认配置
 	if c.Ticket.Attachment == nil {
 		c.Ticket.Attachment = &TicketAttachmentConfig{
@@ -758,11 +1184,17 @@ func (c *Config) Validate() error {
 			AllowedImageTypes: []string{".jpg", ".jpeg", ".png", ".gif", ".webp"},
 		}
 	}
+	if c.Ticket.Attachment.SignedURLTTL <= 0 {
+		c.Ticket.Attachment.SignedURLTTL = 60
+	}
 
 	// 验证码默认配置
 	if c.Security.Captcha.Provider == "" {
 		c.Security.Captcha.Provider = "none"
 	}
+	if c.Security.Captcha.RecaptchaMinScore <= 0 || c.Security.Captcha.RecaptchaMinScore > 1 {
+		c.Security.Captcha.RecaptchaMinScore = 0.5
+	}
 
 	// 邮件通知默认配置（首次加载时全部开启）
 	// 注意：此处不设置默认值，零值false表示未配置时不发送
@@ -1132,6 +1564,11 @@ func (c *DatabaseConfig) GetDSN() string {
 	}
 }
 
+// IsEnabled 是否启用 Redis
+func (c RedisConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
 // GetRedisAddr getRedisAddress
 func (c *RedisConfig) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
@@ -1160,3 +1597,114 @@ func GetConfigPath() string {
 
 	return "config/config.json"
 }
+
+// applyDefaults 填充 Cookie 会话默认值并校验组合是否安全
+func (c *SessionCookieConfig) applyDefaults() error {
+	if c.Name == "" {
+		c.Name = "auralogic_sid"
+	}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	c.SameSite = strings.ToLower(strings.TrimSpace(c.SameSite))
+	if c.SameSite == "" {
+		c.SameSite = "lax"
+	}
+	c.CSRFStrategy = strings.ToLower(strings.TrimSpace(c.CSRFStrategy))
+	if c.CSRFStrategy == "" {
+		c.CSRFStrategy = "double_submit"
+	}
+	if c.CSRFCookieName == "" {
+		c.CSRFCookieName = "auralogic_csrf"
+	}
+	if c.CSRFHeaderName == "" {
+		c.CSRFHeaderName = "X-CSRF-Token"
+	}
+	switch c.SameSite {
+	case "lax", "strict", "none":
+	default:
+		return fmt.Errorf("security.session_cookie.same_site must be one of lax/strict/none")
+	}
+	switch c.CSRFStrategy {
+	case "double_submit", "same_site":
+	default:
+		return fmt.Errorf("security.session_cookie.csrf_strategy must be one of double_submit/same_site")
+	}
+	if c.Enabled && c.SameSite == "none" && !c.Secure {
+		return fmt.Errorf("security.session_cookie.same_site=none requires secure=true")
+	}
+	if c.Enabled && c.CSRFStrategy == "same_site" && c.SameSite == "none" {
+		return fmt.Errorf("security.session_cookie.csrf_strategy=same_site cannot be used with same_site=none")
+	}
+	return nil
+}
+
+// DefaultCSPDirectives 未配置 directives 时使用的策略（与未启用 CSP 管理时的响应头一致）
+func DefaultCSPDirectives() map[string]string {
+	return map[string]string{
+		"default-src":     "'self'",
+		"script-src":      "'self' 'unsafe-inline' 'unsafe-eval'",
+		"style-src":       "'self' 'unsafe-inline'",
+		"img-src":         "'self' data: https:",
+		"font-src":        "'self' data:",
+		"connect-src":     "'self' https:",
+		"frame-ancestors": "'none'",
+	}
+}
+
+var cspDirectiveNamePattern = regexp.MustCompile(`^[a-z][a-z-]*$`)
+
+func validateCSPDirectives(field string, directives map[string]string) error {
+	for name, value := range directives {
+		if !cspDirectiveNamePattern.MatchString(name) {
+			return fmt.Errorf("%s: invalid directive name %q", field, name)
+		}
+		if strings.ContainsAny(value, ";,\r\n") {
+			return fmt.Errorf("%s.%s must not contain ';', ',' or line breaks", field, name)
+		}
+	}
+	return nil
+}
+
+// ValidateCSP 保存设置前校验 CSP 配置
+func ValidateCSP(csp CSPConfig) error {
+	return csp.applyDefaults()
+}
+
+// applyDefaults 规范化 CSP 配置并校验指令和路由规则
+func (c *CSPConfig) applyDefaults() error {
+	if len(c.Directives) == 0 {
+		c.Directives = DefaultCSPDirectives()
+	}
+	if err := validateCSPDirectives("security.csp.directives", c.Directives); err != nil {
+		return err
+	}
+	c.ReportURI = strings.TrimSpace(c.ReportURI)
+	if strings.ContainsAny(c.ReportURI, " ;,\r\n") {
+		return fmt.Errorf("security.csp.report_uri is invalid")
+	}
+	for i := range c.Routes {
+		route := &c.Routes[i]
+		route.Pattern = strings.TrimSpace(route.Pattern)
+		route.MatchType = strings.ToLower(strings.TrimSpace(route.MatchType))
+		if route.MatchType == "" {
+			route.MatchType = "prefix"
+		}
+		if route.Pattern == "" {
+			return fmt.Errorf("security.csp.routes[%d].pattern is required", i)
+		}
+		switch route.MatchType {
+		case "exact", "prefix":
+		case "regex":
+			if _, err := regexp.Compile(route.Pattern); err != nil {
+				return fmt.Errorf("security.csp.routes[%d].pattern is not a valid regex: %w", i, err)
+			}
+		default:
+			return fmt.Errorf("security.csp.routes[%d].match_type must be one of exact/prefix/regex", i)
+		}
+		if err := validateCSPDirectives(fmt.Sprintf("security.csp.routes[%d].directives", i), route.Directives); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -124,3 +124,49 @@ func newValidTestConfig() Config {
 		},
 	}
 }
+
+func TestValidateNormalizesAppMode(t *testing.T) {
+	cfg := newValidTestConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if cfg.App.Mode != AppModeAll || !cfg.App.ServesAPI() || !cfg.App.RunsWorkers() {
+		t.Fatalf("expected default mode all, got %q", cfg.App.Mode)
+	}
+
+	cfg.App.Mode = " Worker "
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validate worker mode: %v", err)
+	}
+	if cfg.App.Mode != AppModeWorker || cfg.App.ServesAPI() || !cfg.App.RunsWorkers() {
+		t.Fatalf("unexpected worker mode flags: %+v", cfg.App)
+	}
+
+	cfg.App.Mode = "cron"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "app.mode") {
+		t.Fatalf("expected app.mode validation error, got %v", err)
+	}
+}
+
+func TestCaptchaResolveSceneUsesOverridesAndProviderKeys(t *testing.T) {
+	captcha := CaptchaConfig{
+		Provider:       "cloudflare",
+		SiteKey:        "cf-site",
+		SecretKey:      "cf-secret",
+		SceneProviders: map[string]string{"register": "hcaptcha", "serial_verify": "builtin", "bind": "recaptcha_v3"},
+		ProviderKeys:   map[string]CaptchaProviderKeys{"hcaptcha": {SiteKey: "hc-site", SecretKey: "hc-secret"}},
+	}
+
+	if provider, keys := captcha.ResolveScene("login"); provider != "cloudflare" || keys.SecretKey != "cf-secret" {
+		t.Fatalf("login should use the default provider, got %s %+v", provider, keys)
+	}
+	if provider, keys := captcha.ResolveScene("register"); provider != "hcaptcha" || keys.SiteKey != "hc-site" {
+		t.Fatalf("register should use the hcaptcha override, got %s %+v", provider, keys)
+	}
+	if provider, keys := captcha.ResolveScene("serial_verify"); provider != "builtin" || keys.SiteKey != "" {
+		t.Fatalf("serial_verify should fall back to builtin without keys, got %s %+v", provider, keys)
+	}
+	if provider, keys := captcha.ResolveScene("bind"); provider != "recaptcha_v3" || keys.SecretKey != "" {
+		t.Fatalf("overrides must not borrow the default provider's keys, got %s %+v", provider, keys)
+	}
+}
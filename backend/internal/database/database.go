@@ -3,11 +3,14 @@ package database
 import (
 	"fmt"
 	"log"
+	"path"
+	"regexp"
 	"strings"
 	"time"
 
 	"auralogic/internal/config"
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
@@ -18,6 +21,17 @@ import (
 
 var DB *gorm.DB
 
+func slowQueryThresholdFromConfig(cfg *config.DatabaseConfig) time.Duration {
+	switch {
+	case cfg.SlowQueryThresholdMs < 0:
+		return 0
+	case cfg.SlowQueryThresholdMs == 0:
+		return DefaultSlowQueryThreshold
+	default:
+		return time.Duration(cfg.SlowQueryThresholdMs) * time.Millisecond
+	}
+}
+
 // InitDatabase 初始化数据库连接
 func InitDatabase(cfg *config.DatabaseConfig) error {
 	var dialector gorm.Dialector
@@ -35,7 +49,7 @@ func InitDatabase(cfg *config.DatabaseConfig) error {
 
 	// GORM配置
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger: NewSlowQueryLogger(logger.Default.LogMode(logger.Silent), slowQueryThresholdFromConfig(cfg), cfg.Driver),
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
@@ -135,21 +149,30 @@ func AutoMigrate() error {
 		&models.SerialGenerationTask{},
 		&models.MagicToken{},
 		&models.APIKey{},
+		&models.APIKeyUsage{},
 		&models.OperationLog{},
 		&models.MarketingBatch{},
 		&models.MarketingBatchTask{},
+		&models.OrderImportJob{},
 		&models.EmailLog{},
 		&models.SmsLog{},
 		&models.VirtualInventory{},
 		&models.VirtualProductStock{},
+		&models.VirtualStockTransfer{},
+		&models.VirtualStockRevealLog{},
 		&models.ProductVirtualInventoryBinding{},
 		&models.CartItem{},
 		&models.PaymentMethod{},
 		&models.PaymentMethodVersion{},
 		&models.PaymentMethodStorageEntry{},
+		&models.PaymentMethodScriptLog{},
 		&models.VirtualInventoryStorageEntry{},
 		&models.OrderPaymentMethod{},
 		&models.PaymentPollingTask{},
+		&models.PaymentAmountReservation{},
+		&models.IncomingTransaction{},
+		&models.CODCollection{},
+		&models.OrderRefund{},
 		&models.Ticket{},
 		&models.TicketMessage{},
 		&models.TicketOrderAccess{},
@@ -169,6 +192,45 @@ func AutoMigrate() error {
 		&models.PluginStorageEntry{},
 		&models.PluginSecretEntry{},
 		&models.PluginPageRuleEntry{},
+		&models.Store{},
+		&models.StoreDomain{},
+		&models.StoreAdmin{},
+		&models.ThemePack{},
+		&models.LandingPageRevision{},
+		&models.ShortLink{},
+		&models.ShortLinkClick{},
+		&models.LedgerEntry{},
+		&models.ProductPriceHistory{},
+		&models.ProductPriceSchedule{},
+		&models.PromoCodeCampaign{},
+		&models.PromoCodeRedemption{},
+		&models.GiftPromotion{},
+		&models.QuoteRequest{},
+		&models.ReturnRequest{},
+		&models.OrderSubStatus{},
+		&models.OrderReminder{},
+		&models.OrderExternalRef{},
+		&models.ShipmentTracking{},
+		&models.OrderAutomationRule{},
+		&models.OrderAutomationRun{},
+		&models.WebhookEndpoint{},
+		&models.WebhookDelivery{},
+		&models.AdminSavedView{},
+		&models.FieldMaskPolicy{},
+		&models.IPBan{},
+		&models.AccountLockout{},
+		&models.SecurityEvent{},
+		&models.CSPViolation{},
+		&models.TicketAttachment{},
+		&models.AdminApproval{},
+		&models.AdminNotificationSubscription{},
+		&models.AdminNotification{},
+		&models.TicketAutomationRule{},
+		&models.OrderNote{},
+		&models.OrderMessage{},
+		&models.EmailChange{},
+		&models.ShippingRestrictionBlock{},
+		&models.ConfigRevision{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
@@ -224,6 +286,26 @@ func AutoMigrate() error {
 	if err := migratePluginHotReloadDefaults(); err != nil {
 		log.Printf("Warning: failed to backfill plugin hot reload defaults: %v", err)
 	}
+	// Migration: record ownership of existing ticket attachments so their links can be signed.
+	if err := migrateTicketAttachmentOwnership(); err != nil {
+		log.Printf("Warning: failed to backfill ticket attachment ownership: %v", err)
+	}
+	// Migration: move the legacy single admin remark of each order into the order notes thread.
+	if err := migrateOrderAdminRemarkNotes(); err != nil {
+		log.Printf("Warning: failed to migrate order admin remarks: %v", err)
+	}
+	// Migration: drop the legacy (virtual_inventory_id, status) index superseded by the covering stats index.
+	if err := migrateVirtualStockStatsIndex(); err != nil {
+		log.Printf("Warning: failed to drop legacy virtual stock status index: %v", err)
+	}
+	// Migration: assign sampling keys to existing virtual stock so random delivery can use the index.
+	if err := migrateVirtualStockRandomKeys(); err != nil {
+		log.Printf("Warning: failed to backfill virtual stock random keys: %v", err)
+	}
+	// Migration: hash existing virtual stock content so support can look up orders by code.
+	if err := migrateVirtualStockContentHashes(); err != nil {
+		log.Printf("Warning: failed to backfill virtual stock content hashes: %v", err)
+	}
 
 	return nil
 }
@@ -525,7 +607,7 @@ CREATE TABLE IF NOT EXISTS system_migrations (
 	}
 
 	return DB.Transaction(func(tx *gorm.DB) error {
-		sumExpr := orderTotalAmountSumExprForDialect(tx.Dialector.Name())
+		sumExpr := dbutil.SumAsInt64Expr(dbutil.Dialect(tx), "total_amount")
 		// Reset all active users to zero first.
 		if err := tx.Model(&models.User{}).
 			Updates(map[string]interface{}{
@@ -755,17 +837,6 @@ WHERE COALESCE(serial_generation_status, '') = ''
 	})
 }
 
-func orderTotalAmountSumExprForDialect(dialect string) string {
-	switch dialect {
-	case "postgres":
-		return "COALESCE(SUM(total_amount)::bigint, 0)"
-	case "mysql":
-		return "COALESCE(CAST(SUM(total_amount) AS SIGNED), 0)"
-	default: // sqlite and others
-		return "COALESCE(CAST(SUM(total_amount) AS INTEGER), 0)"
-	}
-}
-
 // migrateAPIKeySecretToHash 将现有API密钥从明文迁移到哈希存储
 // 注意：此迁移会使现有的API密钥失效，需要重新生成
 func migrateAPIKeySecretToHash() error {
@@ -842,3 +913,272 @@ func Close() error {
 	}
 	return nil
 }
+
+var legacyTicketAttachmentPathPattern = regexp.MustCompile(`/uploads/tickets/([A-Za-z0-9][A-Za-z0-9._/-]*)`)
+
+// migrateTicketAttachmentOwnership 按引用附件的第一条消息回填附件所属工单
+func migrateTicketAttachmentOwnership() error {
+	if DB == nil {
+		return nil
+	}
+
+	if err := DB.Exec(`
+CREATE TABLE IF NOT EXISTS system_migrations (
+	name VARCHAR(100) PRIMARY KEY,
+	executed_at TIMESTAMP
+)`).Error; err != nil {
+		return err
+	}
+
+	const migrationName = "ticket_attachment_ownership_v1"
+	var count int64
+	if err := DB.Table("system_migrations").Where("name = ?", migrationName).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		const batchSize = 200
+		var lastID uint
+
+		for {
+			var messages []models.TicketMessage
+			if err := tx.Select("id", "ticket_id", "sender_type", "sender_id", "content", "created_at").
+				Where("id > ? AND content LIKE ?", lastID, "%/uploads/tickets/%").
+				Order("id ASC").
+				Limit(batchSize).
+				Find(&messages).Error; err != nil {
+				return err
+			}
+			if len(messages) == 0 {
+				break
+			}
+
+			for _, message := range messages {
+				for _, match := range legacyTicketAttachmentPathPattern.FindAllStringSubmatch(message.Content, -1) {
+					relPath := strings.TrimPrefix(path.Clean("/"+match[1]), "/")
+					if relPath == "" || strings.HasPrefix(relPath, "..") || len(relPath) > 255 {
+						continue
+					}
+					attachment := models.TicketAttachment{
+						TicketID:     message.TicketID,
+						Path:         relPath,
+						UploaderType: message.SenderType,
+						UploaderID:   message.SenderID,
+						CreatedAt:    message.CreatedAt,
+					}
+					if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&attachment).Error; err != nil {
+						return err
+					}
+				}
+			}
+
+			lastID = messages[len(messages)-1].ID
+		}
+
+		return tx.Exec(
+			"INSERT INTO system_migrations(name, executed_at) VALUES(?, ?)",
+			migrationName, time.Now().UTC(),
+		).Error
+	})
+}
+
+// migrateOrderAdminRemarkNotes 将旧版 orders.admin_remark 文本迁移为订单备注（旧列保留不删除）
+func migrateOrderAdminRemarkNotes() error {
+	if DB == nil || !DB.Migrator().HasColumn(&models.Order{}, "admin_remark") {
+		return nil
+	}
+
+	if err := DB.Exec(`
+CREATE TABLE IF NOT EXISTS system_migrations (
+	name VARCHAR(100) PRIMARY KEY,
+	executed_at TIMESTAMP
+)`).Error; err != nil {
+		return err
+	}
+
+	const migrationName = "order_admin_remark_notes_v1"
+	var count int64
+	if err := DB.Table("system_migrations").Where("name = ?", migrationName).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	type legacyAdminRemark struct {
+		ID          uint
+		AdminRemark string
+		UpdatedAt   time.Time
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		const batchSize = 200
+		var lastID uint
+
+		for {
+			var rows []legacyAdminRemark
+			if err := tx.Table("orders").Select("id", "admin_remark", "updated_at").
+				Where("id > ? AND admin_remark IS NOT NULL AND admin_remark <> ''", lastID).
+				Order("id ASC").
+				Limit(batchSize).
+				Find(&rows).Error; err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				break
+			}
+
+			for _, row := range rows {
+				content := strings.TrimSpace(row.AdminRemark)
+				if content == "" {
+					continue
+				}
+				note := models.OrderNote{
+					OrderID:   row.ID,
+					Source:    models.OrderNoteSourceLegacy,
+					Content:   content,
+					CreatedAt: row.UpdatedAt,
+					UpdatedAt: row.UpdatedAt,
+				}
+				if err := tx.Create(&note).Error; err != nil {
+					return err
+				}
+			}
+
+			lastID = rows[len(rows)-1].ID
+		}
+
+		return tx.Exec(
+			"INSERT INTO system_migrations(name, executed_at) VALUES(?, ?)",
+			migrationName, time.Now().UTC(),
+		).Error
+	})
+}
+
+// migrateVirtualStockStatsIndex 旧索引 idx_virtual_inventory_status 已被 idx_virtual_stock_stats 覆盖，删除以减少写入开销
+func migrateVirtualStockStatsIndex() error {
+	if DB == nil {
+		return nil
+	}
+	migrator := DB.Migrator()
+	if !migrator.HasIndex(&models.VirtualProductStock{}, "idx_virtual_stock_stats") ||
+		!migrator.HasIndex(&models.VirtualProductStock{}, "idx_virtual_inventory_status") {
+		return nil
+	}
+	return migrator.DropIndex(&models.VirtualProductStock{}, "idx_virtual_inventory_status")
+}
+
+// virtualStockRandomKeyExprForDialect 生成 [1, 2^31) 随机整数的 SQL 表达式
+func virtualStockRandomKeyExprForDialect(dialect string) string {
+	switch dialect {
+	case "mysql":
+		return "FLOOR(1 + RAND() * 2147483646)"
+	case "postgres":
+		return "FLOOR(1 + random() * 2147483646)"
+	default:
+		return "1 + ABS(RANDOM() % 2147483646)"
+	}
+}
+
+// migrateVirtualStockRandomKeys 为历史库存项回填随机抽样键，新库存项由模型钩子生成
+func migrateVirtualStockRandomKeys() error {
+	if DB == nil {
+		return nil
+	}
+
+	if err := DB.Exec(`
+CREATE TABLE IF NOT EXISTS system_migrations (
+	name VARCHAR(100) PRIMARY KEY,
+	executed_at TIMESTAMP
+)`).Error; err != nil {
+		return err
+	}
+
+	const migrationName = "virtual_stock_random_key_v1"
+	var count int64
+	if err := DB.Table("system_migrations").Where("name = ?", migrationName).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf(
+			"UPDATE virtual_product_stocks SET random_key = %s WHERE random_key <= 0",
+			virtualStockRandomKeyExprForDialect(tx.Dialector.Name()),
+		)).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(
+			"INSERT INTO system_migrations(name, executed_at) VALUES(?, ?)",
+			migrationName, time.Now().UTC(),
+		).Error
+	})
+}
+
+// migrateVirtualStockContentHashes 为历史库存项回填内容哈希，新库存项由模型钩子计算
+func migrateVirtualStockContentHashes() error {
+	if DB == nil {
+		return nil
+	}
+
+	if err := DB.Exec(`
+CREATE TABLE IF NOT EXISTS system_migrations (
+	name VARCHAR(100) PRIMARY KEY,
+	executed_at TIMESTAMP
+)`).Error; err != nil {
+		return err
+	}
+
+	const migrationName = "virtual_stock_content_hash_v1"
+	var count int64
+	if err := DB.Table("system_migrations").Where("name = ?", migrationName).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		const batchSize = 500
+		var lastID uint
+
+		for {
+			var stocks []models.VirtualProductStock
+			if err := tx.Unscoped().Select("id", "content").
+				Where("id > ? AND (content_hash IS NULL OR content_hash = '')", lastID).
+				Order("id ASC").
+				Limit(batchSize).
+				Find(&stocks).Error; err != nil {
+				return err
+			}
+			if len(stocks) == 0 {
+				break
+			}
+
+			for _, stock := range stocks {
+				hash := models.HashVirtualStockContent(stock.Content)
+				if hash == "" {
+					continue
+				}
+				if err := tx.Unscoped().Model(&models.VirtualProductStock{}).
+					Where("id = ?", stock.ID).
+					UpdateColumn("content_hash", hash).Error; err != nil {
+					return err
+				}
+			}
+
+			lastID = stocks[len(stocks)-1].ID
+		}
+
+		return tx.Exec(
+			"INSERT INTO system_migrations(name, executed_at) VALUES(?, ?)",
+			migrationName, time.Now().UTC(),
+		).Error
+	})
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+const (
+	// DefaultSlowQueryThreshold 未配置时的慢查询阈值
+	DefaultSlowQueryThreshold = 200 * time.Millisecond
+	// 最多保留的慢查询指纹数量，超出后淘汰最久未出现的
+	maxSlowQueryFingerprints   = 200
+	maxSlowQueryFingerprintLen = 1000
+)
+
+var (
+	slowQueryStringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// SQLite 方言的 SQL 展开使用双引号包裹字符串（标识符用反引号）
+	slowQuerySQLiteStringLiteral = regexp.MustCompile(`"(?:[^"]|"")*"`)
+	slowQueryNumberLiteral       = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	slowQueryValueList           = regexp.MustCompile(`\((?:\s*\?\s*,)+\s*\?\s*\)`)
+	slowQueryWhitespace          = regexp.MustCompile(`\s+`)
+)
+
+// SlowQueryStat 按SQL指纹聚合的慢查询统计
+type SlowQueryStat struct {
+	Fingerprint string    `json:"fingerprint"`
+	Count       int64     `json:"count"`
+	TotalMs     float64   `json:"total_ms"`
+	AvgMs       float64   `json:"avg_ms"`
+	MaxMs       float64   `json:"max_ms"`
+	LastRows    int64     `json:"last_rows"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+type slowQueryRecorder struct {
+	mu    sync.Mutex
+	stats map[string]*SlowQueryStat
+}
+
+var slowQueries = &slowQueryRecorder{stats: make(map[string]*SlowQueryStat)}
+
+// slowQueryLogger 在静默日志基础上记录超过阈值的查询，供后台慢查询报表使用
+type slowQueryLogger struct {
+	logger.Interface
+	threshold time.Duration
+	driver    string
+}
+
+// NewSlowQueryLogger 包装 GORM 日志，threshold<=0 时不记录慢查询
+func NewSlowQueryLogger(base logger.Interface, threshold time.Duration, driver string) logger.Interface {
+	return &slowQueryLogger{Interface: base, threshold: threshold, driver: driver}
+}
+
+func (l *slowQueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &slowQueryLogger{Interface: l.Interface.LogMode(level), threshold: l.threshold, driver: l.driver}
+}
+
+func (l *slowQueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	l.Interface.Trace(ctx, begin, fc, err)
+	if l.threshold <= 0 {
+		return
+	}
+	elapsed := time.Since(begin)
+	if elapsed < l.threshold {
+		return
+	}
+	sql, rows := fc()
+	fingerprint := FingerprintSQL(l.driver, sql)
+	slowQueries.record(fingerprint, elapsed, rows, time.Now().UTC())
+	log.Printf("slow query: elapsed=%s rows=%d sql=%s", elapsed, rows, fingerprint)
+}
+
+// FingerprintSQL 将字面量替换为占位符，既便于聚合也避免在报表中暴露数据
+func FingerprintSQL(driver, sql string) string {
+	fingerprint := slowQueryStringLiteral.ReplaceAllString(sql, "?")
+	if driver == "sqlite" {
+		fingerprint = slowQuerySQLiteStringLiteral.ReplaceAllString(fingerprint, "?")
+	}
+	fingerprint = slowQueryNumberLiteral.ReplaceAllString(fingerprint, "?")
+	fingerprint = slowQueryValueList.ReplaceAllString(fingerprint, "(?...)")
+	fingerprint = strings.TrimSpace(slowQueryWhitespace.ReplaceAllString(fingerprint, " "))
+	if len(fingerprint) > maxSlowQueryFingerprintLen {
+		fingerprint = fingerprint[:maxSlowQueryFingerprintLen]
+	}
+	return fingerprint
+}
+
+func (r *slowQueryRecorder) record(fingerprint string, elapsed time.Duration, rows int64, now time.Time) {
+	ms := float64(elapsed.Microseconds()) / 1000
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stat, exists := r.stats[fingerprint]
+	if !exists {
+		if len(r.stats) >= maxSlowQueryFingerprints {
+			r.evictOldestLocked()
+		}
+		stat = &SlowQueryStat{Fingerprint: fingerprint, FirstSeenAt: now}
+		r.stats[fingerprint] = stat
+	}
+	stat.Count++
+	stat.TotalMs += ms
+	stat.AvgMs = stat.TotalMs / float64(stat.Count)
+	if ms > stat.MaxMs {
+		stat.MaxMs = ms
+	}
+	stat.LastRows = rows
+	stat.LastSeenAt = now
+}
+
+func (r *slowQueryRecorder) evictOldestLocked() {
+	var oldestKey string
+	var oldest time.Time
+	for key, stat := range r.stats {
+		if oldestKey == "" || stat.LastSeenAt.Before(oldest) {
+			oldestKey, oldest = key, stat.LastSeenAt
+		}
+	}
+	delete(r.stats, oldestKey)
+}
+
+// SlowQueryReport 按累计耗时倒序返回慢查询统计
+func SlowQueryReport(limit int) []SlowQueryStat {
+	slowQueries.mu.Lock()
+	items := make([]SlowQueryStat, 0, len(slowQueries.stats))
+	for _, stat := range slowQueries.stats {
+		items = append(items, *stat)
+	}
+	slowQueries.mu.Unlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].TotalMs != items[j].TotalMs {
+			return items[i].TotalMs > items[j].TotalMs
+		}
+		return items[i].Fingerprint < items[j].Fingerprint
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// ResetSlowQueries 清空慢查询统计
+func ResetSlowQueries() {
+	slowQueries.mu.Lock()
+	defer slowQueries.mu.Unlock()
+	slowQueries.stats = make(map[string]*SlowQueryStat)
+}
+
+// SlowQueryThreshold 当前生效的慢查询阈值（0 表示未启用）
+func SlowQueryThreshold() time.Duration {
+	if DB == nil {
+		return 0
+	}
+	if l, ok := DB.Config.Logger.(*slowQueryLogger); ok {
+		return l.threshold
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestFingerprintSQLStripsLiterals(t *testing.T) {
+	got := FingerprintSQL("mysql", "SELECT * FROM `orders`  WHERE receiver_email = 'a@b.com' AND id IN (1, 2, 3)\n LIMIT 20")
+	want := "SELECT * FROM `orders` WHERE receiver_email = ? AND id IN (?...) LIMIT ?"
+	if got != want {
+		t.Fatalf("unexpected fingerprint:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestSlowQueryLoggerAggregatesByFingerprint(t *testing.T) {
+	ResetSlowQueries()
+	defer ResetSlowQueries()
+
+	db, err := gorm.Open(sqlite.Open("file:slow-query-logger?mode=memory&cache=shared"), &gorm.Config{
+		Logger: NewSlowQueryLogger(logger.Default.LogMode(logger.Silent), time.Nanosecond, "sqlite"),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Order{}); err != nil {
+		t.Fatalf("auto migrate failed: %v", err)
+	}
+	ResetSlowQueries()
+
+	for _, status := range []string{"pending", "shipped", "draft"} {
+		var orders []models.Order
+		if err := db.Where("status = ?", status).Find(&orders).Error; err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+	}
+
+	report := SlowQueryReport(10)
+	if len(report) != 1 || report[0].Count != 3 {
+		t.Fatalf("expected queries aggregated into one fingerprint, got %+v", report)
+	}
+}
+
+func TestHotPathCompositeIndexesAreMigrated(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:hot-path-indexes?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Order{}, &models.VirtualProductStock{}); err != nil {
+		t.Fatalf("auto migrate failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		model interface{}
+		index string
+	}{
+		{&models.Order{}, "idx_orders_status_created"},
+		{&models.Order{}, "idx_orders_user_status"},
+		{&models.VirtualProductStock{}, "idx_virtual_stock_order_status"},
+	} {
+		if !db.Migrator().HasIndex(tc.model, tc.index) {
+			t.Fatalf("expected index %s to be created", tc.index)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMigrateVirtualStockRandomKeysBackfillsLegacyRows(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:virtual-stock-random-key-migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	if err := db.AutoMigrate(&models.VirtualProductStock{}); err != nil {
+		t.Fatalf("auto migrate failed: %v", err)
+	}
+
+	previousDB := DB
+	DB = db
+	defer func() {
+		DB = previousDB
+	}()
+
+	for i := 0; i < 20; i++ {
+		if err := db.Create(&models.VirtualProductStock{VirtualInventoryID: 1, Content: "LEGACY", Status: models.VirtualStockStatusAvailable}).Error; err != nil {
+			t.Fatalf("seed stock failed: %v", err)
+		}
+	}
+	// 模拟升级前写入的库存项
+	if err := db.Exec("UPDATE virtual_product_stocks SET random_key = 0").Error; err != nil {
+		t.Fatalf("reset random keys failed: %v", err)
+	}
+
+	if err := migrateVirtualStockRandomKeys(); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+
+	var stocks []models.VirtualProductStock
+	db.Find(&stocks)
+	distinct := make(map[int]bool)
+	for _, stock := range stocks {
+		if stock.RandomKey <= 0 || stock.RandomKey >= models.VirtualStockRandomKeyMax {
+			t.Fatalf("random key out of range: %d", stock.RandomKey)
+		}
+		distinct[stock.RandomKey] = true
+	}
+	if len(distinct) < 15 {
+		t.Fatalf("expected spread-out random keys, got %d distinct of %d", len(distinct), len(stocks))
+	}
+}
@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"strconv"
+	"time"
+
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultAdminActivityRangeDays = 30
+
+type AdminActivityHandler struct {
+	activityService *service.AdminActivityService
+}
+
+func NewAdminActivityHandler(activityService *service.AdminActivityService) *AdminActivityHandler {
+	return &AdminActivityHandler{activityService: activityService}
+}
+
+// parseAdminActivityRange 解析 start_date/end_date（YYYY-MM-DD，UTC，含结束日），默认最近 30 天
+func parseAdminActivityRange(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if raw := c.Query("end_date"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			response.BadRequest(c, "Invalid end_date")
+			return time.Time{}, time.Time{}, false
+		}
+		end = parsed
+	}
+	start := end.AddDate(0, 0, -(defaultAdminActivityRangeDays - 1))
+	if raw := c.Query("start_date"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			response.BadRequest(c, "Invalid start_date")
+			return time.Time{}, time.Time{}, false
+		}
+		start = parsed
+	}
+	return start, end.AddDate(0, 0, 1), true
+}
+
+func (h *AdminActivityHandler) respondActivity(c *gin.Context, adminID uint) {
+	from, to, ok := parseAdminActivityRange(c)
+	if !ok {
+		return
+	}
+	detail, err := h.activityService.GetAdminActivity(adminID, from, to)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to load admin activity", err)
+		return
+	}
+	response.Success(c, detail)
+}
+
+// GetLeaderboard 管理员工作量排行
+func (h *AdminActivityHandler) GetLeaderboard(c *gin.Context) {
+	from, to, ok := parseAdminActivityRange(c)
+	if !ok {
+		return
+	}
+	items, err := h.activityService.Leaderboard(from, to, c.Query("sort"))
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to load admin leaderboard", err)
+		return
+	}
+	response.Success(c, gin.H{
+		"start_date": from.Format("2006-01-02"),
+		"end_date":   to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"items":      items,
+	})
+}
+
+// GetAdminActivity 指定管理员的工作量详情
+func (h *AdminActivityHandler) GetAdminActivity(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return
+	}
+	h.respondActivity(c, uint(id))
+}
+
+// GetMyActivity 当前管理员自己的工作量
+func (h *AdminActivityHandler) GetMyActivity(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	h.respondActivity(c, adminID)
+}
@@ -8,6 +8,7 @@ import (
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/dbutil"
 	"auralogic/internal/pkg/logger"
 	"auralogic/internal/pkg/password"
 	"auralogic/internal/pkg/response"
@@ -42,7 +43,7 @@ func (h *AdminHandler) ListAdmins(c *gin.Context) {
 	query := h.db.Model(&models.User{}).Where("role IN ?", []string{"admin", "super_admin"})
 
 	if search != "" {
-		query = query.Where("email LIKE ? OR name LIKE ?", "%"+search+"%", "%"+search+"%")
+		query = dbutil.WhereContainsFold(query, search, "email", "name")
 	}
 
 	// get总数
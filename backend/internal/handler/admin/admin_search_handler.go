@@ -0,0 +1,112 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// adminSearchTypePermissions 每种结果类型需要的查看权限，无权限的类型直接跳过
+var adminSearchTypePermissions = map[string]string{
+	service.AdminSearchTypeOrder:   "order.view",
+	service.AdminSearchTypeUser:    "user.view",
+	service.AdminSearchTypeTicket:  "ticket.view",
+	service.AdminSearchTypeProduct: "product.view",
+	service.AdminSearchTypeSerial:  "serial.view",
+}
+
+// permittedAdminSearchTypes 当前管理员有查看权限的类型；requested 非空时只保留其中的类型
+func permittedAdminSearchTypes(c *gin.Context, requested map[string]bool) []string {
+	types := make([]string, 0, len(service.AdminSearchTypes))
+	for _, searchType := range service.AdminSearchTypes {
+		if len(requested) > 0 && !requested[searchType] {
+			continue
+		}
+		if middleware.HasAllPermissions(c, adminSearchTypePermissions[searchType]) {
+			types = append(types, searchType)
+		}
+	}
+	return types
+}
+
+type AdminSearchHandler struct {
+	searchService *service.AdminSearchService
+}
+
+func NewAdminSearchHandler(searchService *service.AdminSearchService) *AdminSearchHandler {
+	return &AdminSearchHandler{searchService: searchService}
+}
+
+// Search 全局搜索，q 至少 2 个字符；types 可选（逗号分隔）限定结果类型
+func (h *AdminSearchHandler) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if len([]rune(query)) > service.AdminSearchMaxQueryLength {
+		response.BadRequest(c, "Search query is too long")
+		return
+	}
+
+	requested := map[string]bool{}
+	for _, raw := range strings.Split(c.Query("types"), ",") {
+		if value := strings.TrimSpace(raw); value != "" {
+			requested[value] = true
+		}
+	}
+	types := permittedAdminSearchTypes(c, requested)
+
+	storeScope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	results, err := h.searchService.Search(query, service.AdminSearchOptions{
+		Types:      types,
+		StoreScope: storeScope,
+		Limit:      limit,
+	})
+	if err != nil {
+		response.InternalError(c, "Search failed")
+		return
+	}
+	response.Success(c, gin.H{"query": query, "items": results})
+}
+
+// GetPalette 命令面板数据：可用快捷操作、最近处理过的订单/用户/商品（来自操作日志）和分配给自己的工单
+func (h *AdminSearchHandler) GetPalette(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	storeScope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
+	role, _ := middleware.GetUserRole(c)
+	types := permittedAdminSearchTypes(c, nil)
+
+	recent, err := h.searchService.RecentEntities(adminID, types, storeScope, 0)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	assignedTickets := []service.AdminSearchResult{}
+	if middleware.HasAllPermissions(c, adminSearchTypePermissions[service.AdminSearchTypeTicket]) {
+		assignedTickets, err = h.searchService.AssignedTickets(adminID)
+		if err != nil {
+			response.InternalError(c, "Query failed")
+			return
+		}
+	}
+
+	response.Success(c, gin.H{
+		"actions": service.AdminQuickActionsFor(func(permission string) bool {
+			return middleware.HasAllPermissions(c, permission)
+		}, role == "super_admin"),
+		"recent":           recent,
+		"assigned_tickets": assignedTickets,
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"auralogic/internal/config"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -228,6 +229,12 @@ func (h *AnalyticsHandler) GetOrderAnalytics(c *gin.Context) {
 			Count  int64  `json:"count"`
 		} `json:"status_distribution"`
 
+		// Cancellation reasons (payment timeout vs expired draft vs manual)
+		CancelReasonDistribution []struct {
+			Reason string `json:"reason"`
+			Count  int64  `json:"count"`
+		} `json:"cancel_reason_distribution"`
+
 		// Source distribution
 		SourceDistribution []struct {
 			Source string `json:"source"`
@@ -246,6 +253,9 @@ func (h *AnalyticsHandler) GetOrderAnalytics(c *gin.Context) {
 			Count   int64  `json:"count"`
 		} `json:"country_distribution"`
 
+		// Tag distribution (top 20 tags, paid revenue excludes sandbox orders)
+		TagDistribution []service.OrderTagStat `json:"tag_distribution"`
+
 		// Amount distribution (ranges)
 		AmountDistribution []struct {
 			Range string `json:"range"`
@@ -318,6 +328,14 @@ func (h *AnalyticsHandler) GetOrderAnalytics(c *gin.Context) {
 		Order("count DESC").
 		Scan(&result.StatusDistribution)
 
+	// Cancel reason distribution
+	h.db.Model(&models.Order{}).
+		Select("COALESCE(NULLIF(cancel_reason, ''), 'manual') as reason, COUNT(*) as count").
+		Where("status = ?", models.OrderStatusCancelled).
+		Group("cancel_reason").
+		Order("count DESC").
+		Scan(&result.CancelReasonDistribution)
+
 	// Source distribution
 	h.db.Model(&models.Order{}).
 		Select("COALESCE(NULLIF(source, ''), 'direct') as source, COUNT(*) as count").
@@ -341,6 +359,11 @@ func (h *AnalyticsHandler) GetOrderAnalytics(c *gin.Context) {
 		Limit(20).
 		Scan(&result.CountryDistribution)
 
+	// Tag distribution
+	if tagStats, err := service.OrderTagBreakdown(h.db, 20); err == nil {
+		result.TagDistribution = tagStats
+	}
+
 	// Amount distribution
 	type amountRange struct {
 		Range string `json:"range"`
@@ -446,6 +469,7 @@ func (h *AnalyticsHandler) GetRevenueAnalytics(c *gin.Context) {
 	}
 
 	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// 沙箱付款方式产生的测试订单不计入营收
 	paidStatusCondition := "(status = ? OR status = ? OR status = ?)"
 	var revenueOverview struct {
 		TotalRevenue      int64 `gorm:"column:total_revenue"`
@@ -456,6 +480,7 @@ func (h *AnalyticsHandler) GetRevenueAnalytics(c *gin.Context) {
 		PositivePaidCount int64 `gorm:"column:positive_paid_count"`
 	}
 	if err := h.db.Model(&models.Order{}).
+		Where("is_sandbox = ?", false).
 		Select(strings.Join([]string{
 			aggregateSumExpr("total_amount", paidStatusCondition, "total_revenue"),
 			aggregateSumExpr("total_amount", paidStatusCondition+" AND created_at >= ?", "this_month"),
@@ -492,6 +517,7 @@ func (h *AnalyticsHandler) GetRevenueAnalytics(c *gin.Context) {
 	// Daily revenue trend (last 30 days)
 	dateExpr := h.dateGroupExpr("created_at")
 	h.db.Model(&models.Order{}).
+		Where("is_sandbox = ?", false).
 		Select(fmt.Sprintf("%s as date, COALESCE(SUM(total_amount), 0) as revenue, COUNT(*) as count", dateExpr)).
 		Where("status IN ? AND created_at >= ?", paidStatuses, thirtyDaysAgo).
 		Group(dateExpr).
@@ -501,6 +527,7 @@ func (h *AnalyticsHandler) GetRevenueAnalytics(c *gin.Context) {
 	// Monthly revenue trend (last 12 months)
 	monthExpr := h.monthGroupExpr("created_at")
 	h.db.Model(&models.Order{}).
+		Where("is_sandbox = ?", false).
 		Select(fmt.Sprintf("%s as month, COALESCE(SUM(total_amount), 0) as revenue, COUNT(*) as count", monthExpr)).
 		Where("status IN ? AND created_at >= ?", paidStatuses, twelveMonthsAgo).
 		Group(monthExpr).
@@ -509,6 +536,7 @@ func (h *AnalyticsHandler) GetRevenueAnalytics(c *gin.Context) {
 
 	// Revenue by source
 	h.db.Model(&models.Order{}).
+		Where("is_sandbox = ?", false).
 		Select("COALESCE(NULLIF(source, ''), 'direct') as source, COALESCE(SUM(total_amount), 0) as revenue, COUNT(*) as count").
 		Where("status IN ?", paidStatuses).
 		Group("source").
@@ -517,6 +545,7 @@ func (h *AnalyticsHandler) GetRevenueAnalytics(c *gin.Context) {
 
 	// Revenue by country
 	h.db.Model(&models.Order{}).
+		Where("is_sandbox = ?", false).
 		Select("COALESCE(NULLIF(receiver_country, ''), 'Unknown') as country, COALESCE(SUM(total_amount), 0) as revenue, COUNT(*) as count").
 		Where("status IN ?", paidStatuses).
 		Group("receiver_country").
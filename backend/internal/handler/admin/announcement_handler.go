@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
 	"auralogic/internal/pkg/logger"
 	"auralogic/internal/pkg/response"
 	"auralogic/internal/service"
@@ -96,7 +97,7 @@ func (h *AnnouncementHandler) buildAnnouncementListQuery(c *gin.Context) *gorm.D
 
 	query := h.db.Model(&models.Announcement{})
 	if search != "" {
-		query = query.Where("title LIKE ?", "%"+search+"%")
+		query = dbutil.WhereContainsFold(query, search, "title")
 	}
 	if mandatory == "true" {
 		query = query.Where("is_mandatory = ?", true)
@@ -3,6 +3,7 @@ package admin
 import (
 	"errors"
 	"log"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -17,6 +18,12 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	maxAPIKeyRateLimit      = 1000000
+	apiKeyUsageDefaultHours = 24
+	apiKeyUsageMaxHours     = 24 * 30
+)
+
 type APIKeyHandler struct {
 	db            *gorm.DB
 	pluginManager *service.PluginManagerService
@@ -35,19 +42,71 @@ func buildAPIKeyHookPayload(key *models.APIKey) map[string]interface{} {
 	}
 
 	return map[string]interface{}{
-		"api_key_id":   key.ID,
-		"key_name":     key.KeyName,
-		"api_key":      key.APIKey,
-		"platform":     key.Platform,
-		"scopes":       key.Scopes,
-		"rate_limit":   key.RateLimit,
-		"is_active":    key.IsActive,
-		"last_used_at": key.LastUsedAt,
-		"expires_at":   key.ExpiresAt,
-		"created_by":   key.CreatedBy,
-		"created_at":   key.CreatedAt,
-		"updated_at":   key.UpdatedAt,
+		"api_key_id":                      key.ID,
+		"key_name":                        key.KeyName,
+		"api_key":                         key.APIKey,
+		"platform":                        key.Platform,
+		"scopes":                          key.Scopes,
+		"allowed_ips":                     key.AllowedIPs,
+		"webhook_url":                     key.WebhookURL,
+		"rate_limit":                      key.RateLimit,
+		"burst_limit":                     key.BurstLimit,
+		"is_active":                       key.IsActive,
+		"last_used_at":                    key.LastUsedAt,
+		"last_used_ip":                    key.LastUsedIP,
+		"expires_at":                      key.ExpiresAt,
+		"created_by":                      key.CreatedBy,
+		"created_at":                      key.CreatedAt,
+		"updated_at":                      key.UpdatedAt,
+		"allow_duplicate_external_orders": key.AllowDuplicateExternalOrders,
+	}
+}
+
+// normalizeAPIKeyScopes 校验 scope：需为已注册权限或 scope 别名，且不超出当前管理员权限
+func normalizeAPIKeyScopes(c *gin.Context, scopes []string) ([]string, bool) {
+	normalized, unknown := middleware.NormalizeAPIKeyScopes(scopes)
+	if len(unknown) > 0 {
+		response.BadRequest(c, "Unknown API key scopes: "+strings.Join(unknown, ", "))
+		return nil, false
+	}
+	if !middleware.CanGrantAPIKeyScopes(c, normalized) {
+		response.Forbidden(c, "Cannot grant scopes beyond your own permissions")
+		return nil, false
+	}
+	return normalized, true
+}
+
+// normalizeAPIKeyAllowedIPs 校验 IP/CIDR 白名单
+func normalizeAPIKeyAllowedIPs(c *gin.Context, allowedIPs []string) ([]string, bool) {
+	normalized, err := models.NormalizeAPIKeyAllowedIPs(allowedIPs)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return nil, false
 	}
+	return normalized, true
+}
+
+// validateAPIKeyRateLimit 校验持续速率与突发容量，0 分别表示不限流与按一分钟速率计算
+func validateAPIKeyRateLimit(c *gin.Context, rateLimit, burstLimit int) bool {
+	if rateLimit < 0 || rateLimit > maxAPIKeyRateLimit || burstLimit < 0 || burstLimit > maxAPIKeyRateLimit {
+		response.BadRequest(c, "rate_limit and burst_limit must be between 0 and "+strconv.Itoa(maxAPIKeyRateLimit))
+		return false
+	}
+	return true
+}
+
+// normalizeAPIKeyWebhookURL 校验订单事件回调地址，仅允许 http/https
+func normalizeAPIKeyWebhookURL(c *gin.Context, raw string) (string, bool) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return "", true
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || len(value) > 500 {
+		response.BadRequest(c, "Invalid webhook URL")
+		return "", false
+	}
+	return value, true
 }
 
 // ListAPIKeys getAPI密钥列表
@@ -78,11 +137,15 @@ func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
 // CreateAPIKey CreateAPI密钥
 func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
 	var req struct {
-		KeyName   string    `json:"key_name" binding:"required"`
-		Platform  string    `json:"platform"`
-		Scopes    []string  `json:"scopes"`
-		RateLimit int       `json:"rate_limit"`
-		ExpiresAt time.Time `json:"expires_at"`
+		KeyName                      string    `json:"key_name" binding:"required"`
+		Platform                     string    `json:"platform"`
+		Scopes                       []string  `json:"scopes"`
+		AllowedIPs                   []string  `json:"allowed_ips"`
+		WebhookURL                   string    `json:"webhook_url"`
+		RateLimit                    int       `json:"rate_limit"`
+		BurstLimit                   int       `json:"burst_limit"`
+		ExpiresAt                    time.Time `json:"expires_at"`
+		AllowDuplicateExternalOrders bool      `json:"allow_duplicate_external_orders"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -131,6 +194,23 @@ func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
 		}
 	}
 
+	scopes, ok := normalizeAPIKeyScopes(c, req.Scopes)
+	if !ok {
+		return
+	}
+	allowedIPs, ok := normalizeAPIKeyAllowedIPs(c, req.AllowedIPs)
+	if !ok {
+		return
+	}
+	webhookURL, ok := normalizeAPIKeyWebhookURL(c, req.WebhookURL)
+	if !ok {
+		return
+	}
+	if !req.ExpiresAt.IsZero() && !req.ExpiresAt.After(time.Now()) {
+		response.BadRequest(c, "Expiration time must be in the future")
+		return
+	}
+
 	// generateAPI密钥
 	apiKey, err := utils.GenerateAPIKey("ak_live")
 	if err != nil {
@@ -148,15 +228,22 @@ func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
 	if req.RateLimit == 0 {
 		req.RateLimit = 1000
 	}
+	if !validateAPIKeyRateLimit(c, req.RateLimit, req.BurstLimit) {
+		return
+	}
 
 	key := &models.APIKey{
-		KeyName:   req.KeyName,
-		APIKey:    apiKey,
-		Platform:  req.Platform,
-		Scopes:    req.Scopes,
-		RateLimit: req.RateLimit,
-		IsActive:  true,
-		CreatedBy: currentUserID,
+		KeyName:                      req.KeyName,
+		APIKey:                       apiKey,
+		Platform:                     req.Platform,
+		Scopes:                       scopes,
+		AllowedIPs:                   allowedIPs,
+		WebhookURL:                   webhookURL,
+		RateLimit:                    req.RateLimit,
+		BurstLimit:                   req.BurstLimit,
+		IsActive:                     true,
+		CreatedBy:                    currentUserID,
+		AllowDuplicateExternalOrders: req.AllowDuplicateExternalOrders,
 	}
 
 	// 使用bcrypt哈希存储Secret
@@ -176,21 +263,30 @@ func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
 
 	// 记录操作日志
 	logger.LogAPIKeyOperation(h.db, c, "create", key.ID, map[string]interface{}{
-		"key_name": key.KeyName,
-		"platform": key.Platform,
-		"scopes":   key.Scopes,
+		"key_name":                        key.KeyName,
+		"platform":                        key.Platform,
+		"scopes":                          key.Scopes,
+		"allowed_ips":                     key.AllowedIPs,
+		"webhook_url":                     key.WebhookURL,
+		"expires_at":                      key.ExpiresAt,
+		"allow_duplicate_external_orders": key.AllowDuplicateExternalOrders,
 	})
 
 	response.Success(c, gin.H{
-		"id":         key.ID,
-		"key_name":   key.KeyName,
-		"api_key":    key.APIKey,
-		"api_secret": apiSecret,
-		"platform":   key.Platform,
-		"scopes":     key.Scopes,
-		"rate_limit": key.RateLimit,
-		"created_at": key.CreatedAt,
-		"message":    "⚠️ API Secret is only shown once, please keep it safe!",
+		"id":                              key.ID,
+		"key_name":                        key.KeyName,
+		"api_key":                         key.APIKey,
+		"api_secret":                      apiSecret,
+		"platform":                        key.Platform,
+		"scopes":                          key.Scopes,
+		"allowed_ips":                     key.AllowedIPs,
+		"webhook_url":                     key.WebhookURL,
+		"rate_limit":                      key.RateLimit,
+		"burst_limit":                     key.BurstLimit,
+		"expires_at":                      key.ExpiresAt,
+		"created_at":                      key.CreatedAt,
+		"allow_duplicate_external_orders": key.AllowDuplicateExternalOrders,
+		"message":                         "⚠️ API Secret is only shown once, please keep it safe!",
 	})
 
 	if h.pluginManager != nil {
@@ -306,9 +402,16 @@ func (h *APIKeyHandler) UpdateAPIKey(c *gin.Context) {
 	}
 
 	var req struct {
-		IsActive  *bool  `json:"is_active"`
-		RateLimit *int   `json:"rate_limit"`
-		KeyName   string `json:"key_name"`
+		IsActive                     *bool      `json:"is_active"`
+		RateLimit                    *int       `json:"rate_limit"`
+		BurstLimit                   *int       `json:"burst_limit"`
+		KeyName                      string     `json:"key_name"`
+		Scopes                       *[]string  `json:"scopes"`
+		AllowedIPs                   *[]string  `json:"allowed_ips"`
+		WebhookURL                   *string    `json:"webhook_url"`
+		ExpiresAt                    *time.Time `json:"expires_at"`
+		ClearExpiresAt               bool       `json:"clear_expires_at"`
+		AllowDuplicateExternalOrders *bool      `json:"allow_duplicate_external_orders"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -373,9 +476,48 @@ func (h *APIKeyHandler) UpdateAPIKey(c *gin.Context) {
 	if req.RateLimit != nil {
 		key.RateLimit = *req.RateLimit
 	}
+	if req.BurstLimit != nil {
+		key.BurstLimit = *req.BurstLimit
+	}
+	if !validateAPIKeyRateLimit(c, key.RateLimit, key.BurstLimit) {
+		return
+	}
 	if req.KeyName != "" {
 		key.KeyName = req.KeyName
 	}
+	if req.Scopes != nil {
+		scopes, ok := normalizeAPIKeyScopes(c, *req.Scopes)
+		if !ok {
+			return
+		}
+		key.Scopes = scopes
+	}
+	if req.AllowedIPs != nil {
+		allowedIPs, ok := normalizeAPIKeyAllowedIPs(c, *req.AllowedIPs)
+		if !ok {
+			return
+		}
+		key.AllowedIPs = allowedIPs
+	}
+	if req.WebhookURL != nil {
+		webhookURL, ok := normalizeAPIKeyWebhookURL(c, *req.WebhookURL)
+		if !ok {
+			return
+		}
+		key.WebhookURL = webhookURL
+	}
+	if req.AllowDuplicateExternalOrders != nil {
+		key.AllowDuplicateExternalOrders = *req.AllowDuplicateExternalOrders
+	}
+	if req.ClearExpiresAt {
+		key.ExpiresAt = nil
+	} else if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			response.BadRequest(c, "Expiration time must be in the future")
+			return
+		}
+		key.ExpiresAt = req.ExpiresAt
+	}
 
 	if err := h.db.Save(&key).Error; err != nil {
 		response.InternalError(c, "UpdateFailed")
@@ -383,9 +525,15 @@ func (h *APIKeyHandler) UpdateAPIKey(c *gin.Context) {
 	}
 
 	logger.LogAPIKeyOperation(h.db, c, "update", key.ID, map[string]interface{}{
-		"key_name":   req.KeyName,
-		"is_active":  req.IsActive,
-		"rate_limit": req.RateLimit,
+		"key_name":                        req.KeyName,
+		"is_active":                       req.IsActive,
+		"rate_limit":                      req.RateLimit,
+		"burst_limit":                     req.BurstLimit,
+		"scopes":                          req.Scopes,
+		"allowed_ips":                     req.AllowedIPs,
+		"webhook_url":                     req.WebhookURL,
+		"expires_at":                      key.ExpiresAt,
+		"allow_duplicate_external_orders": key.AllowDuplicateExternalOrders,
 	})
 
 	response.Success(c, key)
@@ -410,3 +558,61 @@ func (h *APIKeyHandler) UpdateAPIKey(c *gin.Context) {
 		})), afterPayload, key.ID)
 	}
 }
+
+// APIKeyUsageResponse API Key 用量面板数据
+type APIKeyUsageResponse struct {
+	APIKeyID        uint                 `json:"api_key_id"`
+	RateLimit       int                  `json:"rate_limit"`
+	BurstLimit      int                  `json:"burst_limit"`
+	RemainingTokens int                  `json:"remaining_tokens"`
+	WindowHours     int                  `json:"window_hours"`
+	TotalRequests   int64                `json:"total_requests"`
+	TotalThrottled  int64                `json:"total_throttled"`
+	Hourly          []models.APIKeyUsage `json:"hourly"`
+}
+
+// GetAPIKeyUsage 按小时返回 API Key 的调用量与被限流次数，以及令牌桶当前剩余额度
+func (h *APIKeyHandler) GetAPIKeyUsage(c *gin.Context) {
+	keyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid API key ID format")
+		return
+	}
+	hours := apiKeyUsageDefaultHours
+	if raw := strings.TrimSpace(c.Query("hours")); raw != "" {
+		parsed, parseErr := strconv.Atoi(raw)
+		if parseErr != nil || parsed <= 0 || parsed > apiKeyUsageMaxHours {
+			response.BadRequest(c, "hours must be between 1 and "+strconv.Itoa(apiKeyUsageMaxHours))
+			return
+		}
+		hours = parsed
+	}
+
+	var key models.APIKey
+	if err := h.db.First(&key, keyID).Error; err != nil {
+		response.NotFound(c, "API key does not exist")
+		return
+	}
+
+	since := models.NowFunc().Truncate(time.Hour).Add(-time.Duration(hours-1) * time.Hour)
+	hourly := make([]models.APIKeyUsage, 0, hours)
+	if err := h.db.Where("api_key_id = ? AND hour >= ?", key.ID, since).Order("hour ASC").Find(&hourly).Error; err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+
+	state := middleware.PeekAPIKeyRateLimit(&key)
+	result := APIKeyUsageResponse{
+		APIKeyID:        key.ID,
+		RateLimit:       key.RateLimit,
+		BurstLimit:      state.Burst,
+		RemainingTokens: state.Remaining,
+		WindowHours:     hours,
+		Hourly:          hourly,
+	}
+	for _, bucket := range hourly {
+		result.TotalRequests += bucket.RequestCount
+		result.TotalThrottled += bucket.ThrottledCount
+	}
+	response.Success(c, result)
+}
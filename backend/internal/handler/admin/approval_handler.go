@@ -0,0 +1,215 @@
+package admin
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ApprovalIDHeader 审批通过后发起人重新提交操作时携带的审批单 ID
+const ApprovalIDHeader = "X-Approval-ID"
+
+type ApprovalHandler struct {
+	approvalService *service.AdminApprovalService
+}
+
+func NewApprovalHandler(approvalService *service.AdminApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{approvalService: approvalService}
+}
+
+// ReviewApprovalRequest 审批意见
+type ReviewApprovalRequest struct {
+	Note string `json:"note"`
+}
+
+// adminApprovalGrant 已占用的审批单，操作成功后 complete 标记为已执行；
+// release 在未 complete 时将审批单退回已通过状态，调用方应在获取后立即 defer
+type adminApprovalGrant struct {
+	c        *gin.Context
+	approval *models.AdminApproval
+	done     bool
+}
+
+func (g *adminApprovalGrant) complete() {
+	if g == nil || g.done {
+		return
+	}
+	g.done = true
+	if err := service.NewAdminApprovalService(database.GetDB()).Complete(g.approval.ID); err != nil {
+		log.Printf("admin approval complete failed: approval=%d err=%v", g.approval.ID, err)
+	}
+}
+
+func (g *adminApprovalGrant) release() {
+	if g == nil || g.done {
+		return
+	}
+	g.done = true
+	db := database.GetDB()
+	if err := service.NewAdminApprovalService(db).Release(g.approval.ID); err != nil {
+		log.Printf("admin approval release failed: approval=%d err=%v", g.approval.ID, err)
+		return
+	}
+	logger.LogOperation(db, g.c, "approval_release", "admin_approval", &g.approval.ID, map[string]interface{}{
+		"action_type": g.approval.ActionType,
+		"resource_id": g.approval.ResourceID,
+	})
+}
+
+// requireAdminApproval 危险操作的双人审批关卡，返回 true 表示可以继续执行
+// 未携带审批单时创建待审批记录并返回 202；携带已通过的审批单时占用该审批单，
+// 调用方在操作成功后调用 complete，并 defer release 以便失败时审批单可重新使用
+func requireAdminApproval(c *gin.Context, req service.AdminApprovalRequest) (*adminApprovalGrant, bool) {
+	var approvalID uint
+	if raw := strings.TrimSpace(c.GetHeader(ApprovalIDHeader)); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || parsed == 0 {
+			response.BadRequest(c, "Invalid approval ID")
+			return nil, false
+		}
+		approvalID = uint(parsed)
+	}
+
+	db := database.GetDB()
+	approval, err := service.NewAdminApprovalService(db).Authorize(req, approvalID)
+	if errors.Is(err, service.ErrAdminApprovalRequired) {
+		logger.LogOperation(db, c, "approval_request", "admin_approval", &approval.ID, map[string]interface{}{
+			"action_type": approval.ActionType,
+			"resource_id": approval.ResourceID,
+			"summary":     approval.Summary,
+		})
+		response.ErrorWithData(c, http.StatusAccepted, response.CodeApprovalRequired, "This operation requires approval by another administrator", approval)
+		return nil, false
+	}
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return nil, false
+		}
+		response.InternalServerError(c, "Failed to verify approval", err)
+		return nil, false
+	}
+
+	logger.LogOperation(db, c, "approval_execute", "admin_approval", &approval.ID, map[string]interface{}{
+		"action_type":  approval.ActionType,
+		"resource_id":  approval.ResourceID,
+		"requested_by": approval.RequestedBy,
+		"reviewed_by":  approval.ReviewedBy,
+	})
+	return &adminApprovalGrant{c: c, approval: approval}, true
+}
+
+func parseApprovalID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid approval ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ListApprovals 审批单列表：有审批权限的管理员可查看全部，其他管理员只能查看自己发起的
+func (h *ApprovalHandler) ListApprovals(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	page, limit := response.GetPagination(c)
+	filter := service.AdminApprovalFilter{
+		Status:     strings.TrimSpace(c.Query("status")),
+		ActionType: strings.TrimSpace(c.Query("action_type")),
+	}
+	if c.Query("mine") == "true" || !middleware.HasAllPermissions(c, "admin.approve") {
+		filter.RequestedBy = adminID
+	}
+
+	approvals, total, err := h.approvalService.List(filter, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, approvals, page, limit, total)
+}
+
+// ApproveApproval 通过审批
+func (h *ApprovalHandler) ApproveApproval(c *gin.Context) {
+	h.review(c, true)
+}
+
+// RejectApproval 拒绝审批
+func (h *ApprovalHandler) RejectApproval(c *gin.Context) {
+	h.review(c, false)
+}
+
+func (h *ApprovalHandler) review(c *gin.Context, approve bool) {
+	id, ok := parseApprovalID(c)
+	if !ok {
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	var req ReviewApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	approval, err := h.approvalService.Review(id, adminID, approve, req.Note)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to review approval", err)
+		return
+	}
+
+	action := "approval_reject"
+	if approve {
+		action = "approval_approve"
+	}
+	logger.LogOperation(database.GetDB(), c, action, "admin_approval", &approval.ID, map[string]interface{}{
+		"action_type":  approval.ActionType,
+		"resource_id":  approval.ResourceID,
+		"requested_by": approval.RequestedBy,
+		"note":         approval.ReviewNote,
+	})
+	response.Success(c, approval)
+}
+
+// CancelApproval 发起人撤回审批单
+func (h *ApprovalHandler) CancelApproval(c *gin.Context) {
+	id, ok := parseApprovalID(c)
+	if !ok {
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	approval, err := h.approvalService.Cancel(id, adminID)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to cancel approval", err)
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "approval_cancel", "admin_approval", &approval.ID, map[string]interface{}{
+		"action_type": approval.ActionType,
+		"resource_id": approval.ResourceID,
+	})
+	response.Success(c, approval)
+}
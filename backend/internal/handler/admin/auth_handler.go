@@ -8,6 +8,7 @@ import (
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/logger"
 	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/utils"
 	"auralogic/internal/service"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -38,7 +39,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
 
-	token, user, err := h.authService.Login(req.Email, req.Password)
+	token, user, err := h.authService.LoginFromClient(req.Email, req.Password, utils.GetRealIP(c), c.Request.UserAgent())
 	if err != nil {
 		// 记录Failed的登录尝试
 		db := database.GetDB()
@@ -96,6 +96,9 @@ func (h *BindingHandler) CreateBinding(c *gin.Context) {
 		response.BadRequest(c, "Invalid product ID format")
 		return
 	}
+	if !ensureAdminProductStoreAccess(c, uint(productID)) {
+		return
+	}
 
 	var req CreateBindingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -229,6 +232,9 @@ func (h *BindingHandler) BatchCreateBindings(c *gin.Context) {
 		response.BadRequest(c, "Invalid product ID format")
 		return
 	}
+	if !ensureAdminProductStoreAccess(c, uint(productID)) {
+		return
+	}
 
 	var req BatchCreateBindingsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -389,6 +395,9 @@ func (h *BindingHandler) UpdateBinding(c *gin.Context) {
 		adminIDValue = *adminID
 	}
 	beforeBinding, _ := h.loadBinding(uint(bindingID))
+	if beforeBinding != nil && !ensureAdminProductStoreAccess(c, beforeBinding.ProductID) {
+		return
+	}
 	if h.pluginManager != nil {
 		originalReq := req
 		hookPayload, payloadErr := adminHookStructToPayload(req)
@@ -491,6 +500,9 @@ func (h *BindingHandler) DeleteBinding(c *gin.Context) {
 		adminIDValue = *adminID
 	}
 	beforeBinding, _ := h.loadBinding(uint(bindingID))
+	if beforeBinding != nil && !ensureAdminProductStoreAccess(c, beforeBinding.ProductID) {
+		return
+	}
 	if h.pluginManager != nil && beforeBinding != nil {
 		hookPayload := buildInventoryBindingHookPayload(beforeBinding)
 		hookPayload["admin_id"] = adminIDValue
@@ -552,6 +564,9 @@ func (h *BindingHandler) DeleteAllProductBindings(c *gin.Context) {
 		response.BadRequest(c, "Invalid product ID format")
 		return
 	}
+	if !ensureAdminProductStoreAccess(c, uint(productID)) {
+		return
+	}
 
 	count, err := h.bindingService.DeleteAllProductBindings(uint(productID))
 	if err != nil {
@@ -575,6 +590,9 @@ func (h *BindingHandler) ReplaceProductBindings(c *gin.Context) {
 		response.BadRequest(c, "Invalid product ID format")
 		return
 	}
+	if !ensureAdminProductStoreAccess(c, uint(productID)) {
+		return
+	}
 
 	var req BatchCreateBindingsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
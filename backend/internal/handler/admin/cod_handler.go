@@ -0,0 +1,157 @@
+package admin
+
+import (
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CODHandler 货到付款代收登记与对账
+type CODHandler struct {
+	db         *gorm.DB
+	codService *service.CODService
+}
+
+func NewCODHandler(db *gorm.DB, codService *service.CODService) *CODHandler {
+	return &CODHandler{db: db, codService: codService}
+}
+
+func (h *CODHandler) available(c *gin.Context) bool {
+	if h == nil || h.codService == nil {
+		response.InternalError(c, "COD service is unavailable")
+		return false
+	}
+	return true
+}
+
+// ListCollections 代收记录列表
+func (h *CODHandler) ListCollections(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	scope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
+	page, limit := response.GetPagination(c)
+	items, total, err := h.codService.ListCollections(c.Query("status"), c.Query("carrier"), scope, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, items, page, limit, total)
+}
+
+// RecordCollection 登记派送代收金额
+func (h *CODHandler) RecordCollection(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	orderID, err := parseUintParam(c.Param("id"))
+	if err != nil || orderID == 0 {
+		response.BadRequest(c, "Invalid order id")
+		return
+	}
+	var req struct {
+		Carrier         string `json:"carrier" binding:"required"`
+		CollectedAmount int64  `json:"collected_amount"`
+		Remark          string `json:"remark"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if !ensureAdminOrderStoreAccess(c, orderID) {
+		return
+	}
+	collection, err := h.codService.RecordCollection(orderID, req.Carrier, req.CollectedAmount, contextUserID(c), req.Remark)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to record collection")
+		return
+	}
+	logger.LogOperation(h.db, c, "cod_collect", "order", &orderID, map[string]interface{}{
+		"order_no":         collection.OrderNo,
+		"carrier":          collection.Carrier,
+		"amount_due":       collection.AmountDue,
+		"collected_amount": collection.CollectedAmount,
+	})
+	response.Success(c, collection)
+}
+
+// SettleCollections 标记承运商已回款
+func (h *CODHandler) SettleCollections(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req struct {
+		IDs []uint `json:"ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	// 绑定了店铺的管理员只能结清所辖店铺订单的代收记录，其余记录跳过
+	allowedStoreIDs, err := loadAdminStoreIDs(c)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	ids := req.IDs
+	var rejected []uint
+	if len(allowedStoreIDs) > 0 && len(ids) > 0 {
+		storeIDs, err := h.codService.CollectionOrderStoreIDs(ids)
+		if err != nil {
+			response.InternalError(c, "Query failed")
+			return
+		}
+		ids = make([]uint, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			storeID, found := storeIDs[id]
+			if !found || !adminStoreAllowed(allowedStoreIDs, storeID) {
+				rejected = append(rejected, id)
+				continue
+			}
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			response.Forbidden(c, "No permission to access this store")
+			return
+		}
+	}
+	settled, err := h.codService.SettleCollections(ids, contextUserID(c))
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to settle collections")
+		return
+	}
+	logger.LogOperation(h.db, c, "cod_settle", "cod_collection", nil, map[string]interface{}{
+		"ids":          ids,
+		"rejected_ids": rejected,
+		"settled":      settled,
+	})
+	response.Success(c, gin.H{"settled": settled, "rejected": len(rejected)})
+}
+
+// Report 按承运商汇总的代收对账报表
+func (h *CODHandler) Report(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	scope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
+	report, err := h.codService.Report(scope)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": report})
+}
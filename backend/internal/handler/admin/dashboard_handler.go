@@ -105,7 +105,8 @@ func (h *DashboardHandler) GetStatistics(c *gin.Context) {
 		SalesLastMonth int64 `gorm:"column:sales_last_month"`
 		SalesToday     int64 `gorm:"column:sales_today"`
 	}
-	paidStatusCondition := "(status = ? OR status = ? OR status = ?)"
+	// 沙箱付款方式产生的测试订单不计入销售额
+	salesCondition := "(status = ? OR status = ? OR status = ?) AND is_sandbox = ?"
 	if err := h.db.Model(&models.Order{}).
 		Select(strings.Join([]string{
 			"COUNT(*) AS total",
@@ -115,9 +116,9 @@ func (h *DashboardHandler) GetStatistics(c *gin.Context) {
 			aggregateCountExpr("status = ?", "pending"),
 			aggregateCountExpr("status = ?", "shipped"),
 			aggregateCountExpr("status = ?", "completed"),
-			aggregateSumExpr("total_amount", paidStatusCondition+" AND created_at >= ?", "sales_this_month"),
-			aggregateSumExpr("total_amount", paidStatusCondition+" AND created_at >= ? AND created_at < ?", "sales_last_month"),
-			aggregateSumExpr("total_amount", paidStatusCondition+" AND created_at >= ?", "sales_today"),
+			aggregateSumExpr("total_amount", salesCondition+" AND created_at >= ?", "sales_this_month"),
+			aggregateSumExpr("total_amount", salesCondition+" AND created_at >= ? AND created_at < ?", "sales_last_month"),
+			aggregateSumExpr("total_amount", salesCondition+" AND created_at >= ?", "sales_today"),
 		}, ", "),
 			todayStart,
 			monthStart,
@@ -125,9 +126,9 @@ func (h *DashboardHandler) GetStatistics(c *gin.Context) {
 			models.OrderStatusPending,
 			models.OrderStatusShipped,
 			models.OrderStatusCompleted,
-			paidStatuses[0], paidStatuses[1], paidStatuses[2], monthStart,
-			paidStatuses[0], paidStatuses[1], paidStatuses[2], lastMonthStart, monthStart,
-			paidStatuses[0], paidStatuses[1], paidStatuses[2], todayStart,
+			paidStatuses[0], paidStatuses[1], paidStatuses[2], false, monthStart,
+			paidStatuses[0], paidStatuses[1], paidStatuses[2], false, lastMonthStart, monthStart,
+			paidStatuses[0], paidStatuses[1], paidStatuses[2], false, todayStart,
 		).
 		Scan(&orderOverview).Error; err != nil {
 		response.InternalError(c, "Query failed")
@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"strconv"
+
+	"auralogic/internal/config"
+	"auralogic/internal/database"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type DomainHandler struct {
+	domainService *service.DomainService
+	cfg           *config.Config
+}
+
+func NewDomainHandler(domainService *service.DomainService, cfg *config.Config) *DomainHandler {
+	return &DomainHandler{domainService: domainService, cfg: cfg}
+}
+
+type adminDomainResponse struct {
+	models.StoreDomain
+	VerificationRecord string `json:"verification_record"`
+}
+
+func buildAdminDomainResponse(domain *models.StoreDomain) adminDomainResponse {
+	return adminDomainResponse{
+		StoreDomain:        *domain,
+		VerificationRecord: domain.VerificationRecordName(),
+	}
+}
+
+func parseAdminDomainID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid domain ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *DomainHandler) respondDomainError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// ListDomains 自定义域名列表（含验证与证书状态）
+func (h *DomainHandler) ListDomains(c *gin.Context) {
+	domains, err := h.domainService.List()
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	items := make([]adminDomainResponse, 0, len(domains))
+	for i := range domains {
+		items = append(items, buildAdminDomainResponse(&domains[i]))
+	}
+	response.Success(c, gin.H{
+		"items": items,
+		"acme": gin.H{
+			"enabled":    h.cfg.ACME.Enabled,
+			"email":      h.cfg.ACME.Email,
+			"https_port": h.cfg.ACME.HTTPSPort,
+		},
+	})
+}
+
+// RegisterDomain 注册自定义域名
+func (h *DomainHandler) RegisterDomain(c *gin.Context) {
+	var req service.DomainInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	domain, err := h.domainService.Register(req)
+	if err != nil {
+		h.respondDomainError(c, err, "Failed to register domain")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create", "store_domain", &domain.ID, map[string]interface{}{
+		"host":              domain.Host,
+		"store_id":          domain.StoreID,
+		"landing_page_slug": domain.LandingPageSlug,
+	})
+	response.Success(c, buildAdminDomainResponse(domain))
+}
+
+// UpdateDomain 更新域名所属店铺与落地页
+func (h *DomainHandler) UpdateDomain(c *gin.Context) {
+	id, ok := parseAdminDomainID(c)
+	if !ok {
+		return
+	}
+	var req service.DomainInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	domain, err := h.domainService.Update(id, req)
+	if err != nil {
+		h.respondDomainError(c, err, "Failed to update domain")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "update", "store_domain", &domain.ID, map[string]interface{}{
+		"host":              domain.Host,
+		"store_id":          domain.StoreID,
+		"landing_page_slug": domain.LandingPageSlug,
+	})
+	response.Success(c, buildAdminDomainResponse(domain))
+}
+
+// DeleteDomain 删除自定义域名
+func (h *DomainHandler) DeleteDomain(c *gin.Context) {
+	id, ok := parseAdminDomainID(c)
+	if !ok {
+		return
+	}
+	if err := h.domainService.Delete(id); err != nil {
+		h.respondDomainError(c, err, "Failed to delete domain")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "delete", "store_domain", &id, nil)
+	response.Success(c, gin.H{"message": "Domain deleted"})
+}
+
+// VerifyDomain 立即检查域名 TXT 记录
+func (h *DomainHandler) VerifyDomain(c *gin.Context) {
+	id, ok := parseAdminDomainID(c)
+	if !ok {
+		return
+	}
+	domain, err := h.domainService.Verify(id)
+	if err != nil {
+		h.respondDomainError(c, err, "Failed to verify domain")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "verify", "store_domain", &domain.ID, map[string]interface{}{
+		"host":                domain.Host,
+		"verification_status": domain.VerificationStatus,
+	})
+	response.Success(c, buildAdminDomainResponse(domain))
+}
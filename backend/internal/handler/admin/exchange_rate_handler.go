@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ExchangeRateHandler 汇率服务状态
+type ExchangeRateHandler struct {
+	rates *service.ExchangeRateService
+}
+
+func NewExchangeRateHandler(rates *service.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{rates: rates}
+}
+
+// ListRates 已缓存的汇率及过期状态
+func (h *ExchangeRateHandler) ListRates(c *gin.Context) {
+	quotes := h.rates.ListQuotes()
+	if quotes == nil {
+		quotes = []service.ExchangeRateQuote{}
+	}
+	response.Success(c, gin.H{
+		"enabled": h.rates.Enabled(),
+		"rates":   quotes,
+	})
+}
+
+// GetRate 查询指定币对汇率（未缓存时向数据源拉取）
+func (h *ExchangeRateHandler) GetRate(c *gin.Context) {
+	if !h.rates.Enabled() {
+		response.BadRequest(c, "Exchange rate service is disabled")
+		return
+	}
+	from := c.Query("from")
+	to := c.Query("to")
+	if from == "" || to == "" {
+		response.BadRequest(c, "from and to are required")
+		return
+	}
+	quote, err := h.rates.GetRate(c.Request.Context(), from, to)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	response.Success(c, quote)
+}
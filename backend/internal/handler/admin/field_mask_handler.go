@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"strings"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type FieldMaskHandler struct {
+	fieldMaskService *service.FieldMaskService
+}
+
+func NewFieldMaskHandler(fieldMaskService *service.FieldMaskService) *FieldMaskHandler {
+	return &FieldMaskHandler{fieldMaskService: fieldMaskService}
+}
+
+// UpdateFieldMaskPoliciesRequest 更新某角色的字段打码策略
+type UpdateFieldMaskPoliciesRequest struct {
+	Policies []service.FieldMaskPolicyInput `json:"policies" binding:"required"`
+}
+
+// resolveAdminFieldMask 当前管理员生效的打码规则
+// Note: 与原 order.view_privacy 一致，即使超级管理员也需要显式授予字段查看权限
+func resolveAdminFieldMask(c *gin.Context) models.FieldMaskRules {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		return models.DefaultFieldMaskRules()
+	}
+	return service.ResolveAdminFieldMaskRules(database.GetDB(), userID)
+}
+
+// ListFieldMaskPolicies 各角色的字段打码策略
+func (h *FieldMaskHandler) ListFieldMaskPolicies(c *gin.Context) {
+	roles, err := h.fieldMaskService.ListPolicies()
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{
+		"roles":              roles,
+		"fields":             models.FieldMaskFields,
+		"reveal_permissions": models.FieldMaskRevealPermissions,
+	})
+}
+
+// UpdateFieldMaskPolicies 更新角色策略
+func (h *FieldMaskHandler) UpdateFieldMaskPolicies(c *gin.Context) {
+	role := strings.TrimSpace(c.Param("role"))
+	var req UpdateFieldMaskPoliciesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	var updatedBy *uint
+	if adminID, ok := middleware.GetUserID(c); ok {
+		updatedBy = &adminID
+	}
+	policies, err := h.fieldMaskService.UpdateRolePolicies(role, req.Policies, updatedBy)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to update masking policies", err)
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "update", "field_mask_policy", nil, map[string]interface{}{
+		"role":     role,
+		"policies": req.Policies,
+	})
+	response.Success(c, gin.H{"role": role, "policies": policies})
+}
@@ -0,0 +1,124 @@
+package admin
+
+import (
+	"strconv"
+
+	"auralogic/internal/database"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type GiftPromotionHandler struct {
+	giftPromotionService *service.GiftPromotionService
+}
+
+func NewGiftPromotionHandler(giftPromotionService *service.GiftPromotionService) *GiftPromotionHandler {
+	return &GiftPromotionHandler{giftPromotionService: giftPromotionService}
+}
+
+func parseGiftPromotionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *GiftPromotionHandler) respondGiftPromotionError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// ListGiftPromotions 满赠活动列表
+func (h *GiftPromotionHandler) ListGiftPromotions(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+	items, total, err := h.giftPromotionService.List(page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, items, page, limit, total)
+}
+
+// GetGiftPromotion 满赠活动详情
+func (h *GiftPromotionHandler) GetGiftPromotion(c *gin.Context) {
+	id, ok := parseGiftPromotionID(c)
+	if !ok {
+		return
+	}
+	promotion, err := h.giftPromotionService.Get(id)
+	if err != nil {
+		h.respondGiftPromotionError(c, err, "Failed to load gift promotion")
+		return
+	}
+	response.Success(c, promotion)
+}
+
+// CreateGiftPromotion 创建满赠活动
+func (h *GiftPromotionHandler) CreateGiftPromotion(c *gin.Context) {
+	var req service.GiftPromotionInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	promotion, err := h.giftPromotionService.Create(req)
+	if err != nil {
+		h.respondGiftPromotionError(c, err, "Failed to create gift promotion")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create", "gift_promotion", &promotion.ID, map[string]interface{}{
+		"name":              promotion.Name,
+		"threshold_minor":   promotion.ThresholdMinor,
+		"gift_product_id":   promotion.GiftProductID,
+		"gift_inventory_id": promotion.GiftInventoryID,
+	})
+	response.Success(c, promotion)
+}
+
+// UpdateGiftPromotion 更新满赠活动
+func (h *GiftPromotionHandler) UpdateGiftPromotion(c *gin.Context) {
+	id, ok := parseGiftPromotionID(c)
+	if !ok {
+		return
+	}
+	var req service.GiftPromotionInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	promotion, err := h.giftPromotionService.Update(id, req)
+	if err != nil {
+		h.respondGiftPromotionError(c, err, "Failed to update gift promotion")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "update", "gift_promotion", &promotion.ID, map[string]interface{}{
+		"name":              promotion.Name,
+		"is_active":         promotion.IsActive,
+		"threshold_minor":   promotion.ThresholdMinor,
+		"gift_product_id":   promotion.GiftProductID,
+		"gift_inventory_id": promotion.GiftInventoryID,
+	})
+	response.Success(c, promotion)
+}
+
+// DeleteGiftPromotion 删除满赠活动
+func (h *GiftPromotionHandler) DeleteGiftPromotion(c *gin.Context) {
+	id, ok := parseGiftPromotionID(c)
+	if !ok {
+		return
+	}
+	if err := h.giftPromotionService.Delete(id); err != nil {
+		h.respondGiftPromotionError(c, err, "Failed to delete gift promotion")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "delete", "gift_promotion", &id, nil)
+	response.Success(c, gin.H{"message": "Gift promotion deleted"})
+}
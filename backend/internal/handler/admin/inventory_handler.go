@@ -37,6 +37,7 @@ type CreateInventoryRequest struct {
 	SafetyStock       int               `json:"safety_stock" binding:"min=0"`
 	AlertEmail        string            `json:"alert_email,omitempty"`
 	Notes             string            `json:"notes,omitempty"`
+	InventoryDimensionsRequest
 }
 
 // UpdateInventoryRequest UpdateInventory请求
@@ -47,6 +48,19 @@ type UpdateInventoryRequest struct {
 	IsActive          bool   `json:"is_active"`
 	AlertEmail        string `json:"alert_email,omitempty"`
 	Notes             string `json:"notes,omitempty"`
+	InventoryDimensionsRequest
+}
+
+// InventoryDimensionsRequest 规格重量与尺寸，0 表示沿用商品设置
+type InventoryDimensionsRequest struct {
+	WeightGrams int `json:"weight_grams" binding:"min=0"`
+	LengthMM    int `json:"length_mm" binding:"min=0"`
+	WidthMM     int `json:"width_mm" binding:"min=0"`
+	HeightMM    int `json:"height_mm" binding:"min=0"`
+}
+
+func (r InventoryDimensionsRequest) toService() service.InventoryDimensions {
+	return service.InventoryDimensions{WeightGrams: r.WeightGrams, LengthMM: r.LengthMM, WidthMM: r.WidthMM, HeightMM: r.HeightMM}
 }
 
 // AdjustStockRequest 调整库存请求
@@ -124,6 +138,9 @@ func (h *InventoryHandler) CreateInventory(c *gin.Context) {
 		req.AvailableQuantity,
 		req.SafetyStock,
 	)
+	if err == nil {
+		err = h.inventoryService.UpdateInventoryDimensions(inventory.ID, req.InventoryDimensionsRequest.toService())
+	}
 
 	if err != nil {
 		if respondAdminBizError(c, err) {
@@ -263,6 +280,9 @@ func (h *InventoryHandler) UpdateInventory(c *gin.Context) {
 		req.SafetyStock,
 		req.IsActive,
 	)
+	if err == nil {
+		err = h.inventoryService.UpdateInventoryDimensions(inventoryID, req.InventoryDimensionsRequest.toService())
+	}
 
 	if err != nil {
 		if respondAdminBizError(c, err) {
@@ -156,6 +156,7 @@ func (h *InventoryLogHandler) GetInventoryLogStatistics(c *gin.Context) {
 		ImportLogs  int64 `json:"import_logs"`
 		DeliverLogs int64 `json:"deliver_logs"`
 		DeleteLogs  int64 `json:"delete_logs"`
+		ReturnLogs  int64 `json:"return_logs"`
 	}
 
 	baseQuery := h.db.Model(&models.InventoryLog{})
@@ -172,6 +173,7 @@ func (h *InventoryLogHandler) GetInventoryLogStatistics(c *gin.Context) {
 	h.db.Model(&models.InventoryLog{}).Where("type = ?", models.InventoryLogTypeImport).Count(&stats.ImportLogs)
 	h.db.Model(&models.InventoryLog{}).Where("type = ?", models.InventoryLogTypeDeliver).Count(&stats.DeliverLogs)
 	h.db.Model(&models.InventoryLog{}).Where("type = ?", models.InventoryLogTypeDelete).Count(&stats.DeleteLogs)
+	h.db.Model(&models.InventoryLog{}).Where("type = ?", models.InventoryLogTypeReturn).Count(&stats.ReturnLogs)
 
 	response.Success(c, stats)
 }
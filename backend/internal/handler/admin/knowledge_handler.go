@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
 	"auralogic/internal/pkg/logger"
 	"auralogic/internal/pkg/response"
 	"auralogic/internal/service"
@@ -967,7 +968,7 @@ func (h *KnowledgeHandler) ListArticles(c *gin.Context) {
 		query = query.Where("category_id IN ?", ids)
 	}
 	if search != "" {
-		query = query.Where("title LIKE ?", "%"+search+"%")
+		query = dbutil.WhereContainsFold(query, search, "title")
 	}
 
 	var total int64
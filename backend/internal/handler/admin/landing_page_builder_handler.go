@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func parseLandingRevisionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid revision ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *LandingPageHandler) respondBuilderError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// GetBlockSchema 区块 JSON Schema
+func (h *LandingPageHandler) GetBlockSchema(c *gin.Context) {
+	response.Success(c, service.LandingBlockSchema())
+}
+
+// ListRevisions 落地页区块版本列表
+func (h *LandingPageHandler) ListRevisions(c *gin.Context) {
+	slug, ok := parseLandingPageSlug(c)
+	if !ok {
+		return
+	}
+	revisions, err := h.builder.ListRevisions(slug)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	var publishedRevisionID *uint
+	mode := models.LandingPageModeHTML
+	var page models.LandingPage
+	if err := h.db.Select("mode", "published_revision_id").Where("slug = ?", slug).First(&page).Error; err == nil {
+		publishedRevisionID = page.PublishedRevisionID
+		if page.Mode != "" {
+			mode = page.Mode
+		}
+	}
+	response.Success(c, gin.H{
+		"items":                 revisions,
+		"mode":                  mode,
+		"published_revision_id": publishedRevisionID,
+	})
+}
+
+// GetRevision 区块版本详情
+func (h *LandingPageHandler) GetRevision(c *gin.Context) {
+	id, ok := parseLandingRevisionID(c)
+	if !ok {
+		return
+	}
+	revision, err := h.builder.GetRevision(id)
+	if err != nil {
+		h.respondBuilderError(c, err, "Failed to load revision")
+		return
+	}
+	response.Success(c, revision)
+}
+
+// SaveDraft 保存区块草稿（生成新版本）
+func (h *LandingPageHandler) SaveDraft(c *gin.Context) {
+	var req struct {
+		Blocks []models.LandingBlock `json:"blocks" binding:"required"`
+		Note   string                `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	slug, ok := parseLandingPageSlug(c)
+	if !ok {
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	revision, err := h.builder.SaveDraft(slug, req.Blocks, req.Note, adminID)
+	if err != nil {
+		h.respondBuilderError(c, err, "Failed to save draft")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "save_draft", "landing_page_revision", &revision.ID, map[string]interface{}{
+		"slug":    revision.PageSlug,
+		"version": revision.Version,
+		"blocks":  len(revision.Blocks),
+	})
+	response.Success(c, revision)
+}
+
+// PublishRevision 发布区块版本，落地页切换为区块模式
+func (h *LandingPageHandler) PublishRevision(c *gin.Context) {
+	id, ok := parseLandingRevisionID(c)
+	if !ok {
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	revision, err := h.builder.Publish(id, adminID)
+	if err != nil {
+		h.respondBuilderError(c, err, "Failed to publish revision")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "publish", "landing_page_revision", &revision.ID, map[string]interface{}{
+		"slug":    revision.PageSlug,
+		"version": revision.Version,
+	})
+	response.Success(c, revision)
+}
+
+// PreviewRevision 服务端渲染指定版本（预览）
+func (h *LandingPageHandler) PreviewRevision(c *gin.Context) {
+	id, ok := parseLandingRevisionID(c)
+	if !ok {
+		return
+	}
+	revision, err := h.builder.GetRevision(id)
+	if err != nil {
+		h.respondBuilderError(c, err, "Failed to load revision")
+		return
+	}
+	html, err := h.builder.Render(revision.Blocks, h.buildRenderContext(c))
+	if err != nil {
+		h.respondBuilderError(c, err, "Failed to render revision")
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(middleware.ApplyCSPNonce(html, middleware.CSPNonce(c))))
+}
+
+// PreviewBlocks 服务端渲染请求体中的区块（未保存预览）
+func (h *LandingPageHandler) PreviewBlocks(c *gin.Context) {
+	var req struct {
+		Blocks []models.LandingBlock `json:"blocks" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	html, err := h.builder.Render(req.Blocks, h.buildRenderContext(c))
+	if err != nil {
+		h.respondBuilderError(c, err, "Failed to render blocks")
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(middleware.ApplyCSPNonce(html, middleware.CSPNonce(c))))
+}
@@ -24,10 +24,16 @@ type LandingPageHandler struct {
 	db            *gorm.DB
 	cfg           *config.Config
 	pluginManager *service.PluginManagerService
+	builder       *service.LandingPageBuilderService
 }
 
 func NewLandingPageHandler(db *gorm.DB, cfg *config.Config, pluginManager *service.PluginManagerService) *LandingPageHandler {
-	return &LandingPageHandler{db: db, cfg: cfg, pluginManager: pluginManager}
+	return &LandingPageHandler{
+		db:            db,
+		cfg:           cfg,
+		pluginManager: pluginManager,
+		builder:       service.NewLandingPageBuilderService(db),
+	}
 }
 
 func matchPageRule(pagePath string, rule config.PageRule) bool {
@@ -94,56 +100,92 @@ func injectLandingPageSessionScript(htmlContent string) string {
 	return sessionScript + "\n" + htmlContent
 }
 
-// ServeLandingPage 公开 GET / — 渲染落地页
-func (h *LandingPageHandler) ServeLandingPage(c *gin.Context) {
-	var page models.LandingPage
-	if err := h.db.Where("slug = ? AND is_active = ?", "home", true).First(&page).Error; err != nil {
-		c.Redirect(http.StatusFound, "/login")
-		return
+// buildRenderContext 落地页模板变量（店铺品牌优先于全局配置）
+func (h *LandingPageHandler) buildRenderContext(c *gin.Context) service.LandingRenderContext {
+	ctx := service.LandingRenderContext{
+		AppName:      h.cfg.App.Name,
+		AppURL:       h.cfg.App.URL,
+		LogoURL:      h.cfg.Customization.LogoURL,
+		PrimaryColor: h.cfg.Customization.PrimaryColor,
+		Currency:     h.cfg.Order.Currency,
 	}
-
-	// 模板变量
-	primaryColor := h.cfg.Customization.PrimaryColor
-	if primaryColor == "" {
-		primaryColor = "#3b82f6"
+	if ctx.PrimaryColor == "" {
+		ctx.PrimaryColor = "#3b82f6"
 	}
-	currency := h.cfg.Order.Currency
-	if currency == "" {
-		currency = "CNY"
+	if ctx.Currency == "" {
+		ctx.Currency = "CNY"
 	}
-	appURL := h.cfg.App.URL
-	if appURL == "" {
-		appURL = fmt.Sprintf("http://localhost:%d", h.cfg.App.Port)
+	if ctx.AppURL == "" {
+		ctx.AppURL = fmt.Sprintf("http://localhost:%d", h.cfg.App.Port)
 	}
-	logoURL := h.cfg.Customization.LogoURL
-
-	data := map[string]interface{}{
-		"AppName":      h.cfg.App.Name,
-		"AppURL":       appURL,
-		"Currency":     currency,
-		"LogoURL":      logoURL,
-		"PrimaryColor": primaryColor,
-		"Year":         time.Now().Year(),
+	if store, ok := middleware.GetStore(c); ok {
+		ctx.StoreID = store.ID
+		ctx.AppName = store.Name
+		if store.PrimaryColor != "" {
+			ctx.PrimaryColor = store.PrimaryColor
+		}
+		if store.LogoURL != "" {
+			ctx.LogoURL = store.LogoURL
+		}
 	}
+	return ctx
+}
 
-	tmpl, err := template.New("landing").Parse(page.HTMLContent)
+// ServeLandingPage 公开 GET / — 渲染落地页
+func (h *LandingPageHandler) ServeLandingPage(c *gin.Context) {
+	// 自定义域名可指定落地页，未找到时回退到默认落地页
+	var page models.LandingPage
+	slug := service.ResolveDomainLandingPageSlug(h.db, c.Request.Host)
+	err := h.db.Where("slug = ? AND is_active = ?", slug, true).First(&page).Error
+	if err != nil && slug != "home" {
+		err = h.db.Where("slug = ? AND is_active = ?", "home", true).First(&page).Error
+	}
 	if err != nil {
 		c.Redirect(http.StatusFound, "/login")
 		return
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		c.Redirect(http.StatusFound, "/login")
-		return
+	renderCtx := h.buildRenderContext(c)
+	var renderedHTML string
+	if page.Mode == models.LandingPageModeBlocks && page.PublishedRevisionID != nil {
+		// 区块模式：渲染已发布版本
+		renderedHTML, err = h.builder.RenderPublished(&page, renderCtx)
+		if err != nil {
+			log.Printf("landing page block render failed: slug=%s err=%v", page.Slug, err)
+			c.Redirect(http.StatusFound, "/login")
+			return
+		}
+	} else {
+		data := map[string]interface{}{
+			"AppName":      renderCtx.AppName,
+			"AppURL":       renderCtx.AppURL,
+			"Currency":     renderCtx.Currency,
+			"LogoURL":      renderCtx.LogoURL,
+			"PrimaryColor": renderCtx.PrimaryColor,
+			"Year":         time.Now().Year(),
+		}
+
+		tmpl, err := template.New("landing").Parse(page.HTMLContent)
+		if err != nil {
+			c.Redirect(http.StatusFound, "/login")
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			c.Redirect(http.StatusFound, "/login")
+			return
+		}
+		renderedHTML = buf.String()
 	}
-	renderedHTML := buf.String()
 	renderedHTML = injectLandingPageSessionScript(renderedHTML)
 	pageInjectPayload, resolveErr := service.ResolvePageInjectPayload(h.db, h.cfg, "/")
 	if resolveErr != nil {
 		log.Printf("landing page inject resolve failed: %v", resolveErr)
 	}
 	renderedHTML = injectPageContent(renderedHTML, pageInjectPayload.CSS, pageInjectPayload.JS)
+	// 启用 CSP nonce 时，落地页自身及注入的脚本/样式需携带本次请求的 nonce
+	renderedHTML = middleware.ApplyCSPNonce(renderedHTML, middleware.CSPNonce(c))
 
 	// 异步记录 PageView
 	ip := utils.GetRealIP(c)
@@ -164,13 +206,38 @@ func (h *LandingPageHandler) ServeLandingPage(c *gin.Context) {
 	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(renderedHTML))
 }
 
+// parseLandingPageSlug 解析 slug 查询参数，缺省为 home
+func parseLandingPageSlug(c *gin.Context) (string, bool) {
+	slug, ok := service.NormalizeLandingPageSlug(c.Query("slug"))
+	if !ok {
+		response.BadRequest(c, "Invalid landing page slug")
+		return "", false
+	}
+	return slug, true
+}
+
+// ListLandingPages 管理员 GET — 落地页列表（供自定义域名选择）
+func (h *LandingPageHandler) ListLandingPages(c *gin.Context) {
+	var pages []models.LandingPage
+	if err := h.db.Select("id", "slug", "mode", "published_revision_id", "is_active", "updated_by", "created_at", "updated_at").
+		Order("slug ASC").Find(&pages).Error; err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": pages})
+}
+
 // GetLandingPage 管理员 GET — 返回落地页 JSON
 func (h *LandingPageHandler) GetLandingPage(c *gin.Context) {
+	slug, ok := parseLandingPageSlug(c)
+	if !ok {
+		return
+	}
 	var page models.LandingPage
-	if err := h.db.Where("slug = ?", "home").First(&page).Error; err != nil {
+	if err := h.db.Where("slug = ?", slug).First(&page).Error; err != nil {
 		response.Success(c, gin.H{
 			"id":           0,
-			"slug":         "home",
+			"slug":         slug,
 			"html_content": "",
 			"is_active":    false,
 		})
@@ -188,13 +255,17 @@ func (h *LandingPageHandler) UpdateLandingPage(c *gin.Context) {
 		response.BadRequest(c, "html_content is required")
 		return
 	}
+	slug, ok := parseLandingPageSlug(c)
+	if !ok {
+		return
+	}
 	adminID, adminIDOK := middleware.RequireUserID(c)
 	if !adminIDOK {
 		return
 	}
 	if h.pluginManager != nil {
 		hookPayload := map[string]interface{}{
-			"slug":         "home",
+			"slug":         slug,
 			"html_content": req.HTMLContent,
 			"admin_id":     adminID,
 			"source":       "admin_api",
@@ -205,7 +276,7 @@ func (h *LandingPageHandler) UpdateLandingPage(c *gin.Context) {
 		}, buildAdminHookExecutionContext(c, &adminID, map[string]string{
 			"hook_resource": "landing_page",
 			"hook_source":   "admin_api",
-			"page_slug":     "home",
+			"page_slug":     slug,
 		}))
 		if hookErr != nil {
 			log.Printf("landing_page.update.before hook execution failed: admin=%d err=%v", adminID, hookErr)
@@ -236,12 +307,12 @@ func (h *LandingPageHandler) UpdateLandingPage(c *gin.Context) {
 
 	var page models.LandingPage
 	created := false
-	err := h.db.Where("slug = ?", "home").First(&page).Error
+	err := h.db.Where("slug = ?", slug).First(&page).Error
 	if err != nil {
 		// 不存在则创建
 		created = true
 		page = models.LandingPage{
-			Slug:        "home",
+			Slug:        slug,
 			HTMLContent: req.HTMLContent,
 			IsActive:    true,
 			UpdatedBy:   uid,
@@ -252,6 +323,7 @@ func (h *LandingPageHandler) UpdateLandingPage(c *gin.Context) {
 		}
 	} else {
 		page.HTMLContent = req.HTMLContent
+		page.Mode = models.LandingPageModeHTML
 		page.UpdatedBy = uid
 		if err := h.db.Save(&page).Error; err != nil {
 			response.InternalError(c, "Failed to update landing page")
@@ -295,7 +367,7 @@ func (h *LandingPageHandler) UpdateLandingPage(c *gin.Context) {
 		}(cloneAdminHookExecutionContext(buildAdminHookExecutionContext(c, &adminID, map[string]string{
 			"hook_resource": "landing_page",
 			"hook_source":   "admin_api",
-			"page_slug":     "home",
+			"page_slug":     slug,
 		})), afterPayload, page.ID)
 	}
 
@@ -305,6 +377,10 @@ func (h *LandingPageHandler) UpdateLandingPage(c *gin.Context) {
 // ResetLandingPage 管理员 POST — 重置落地页为默认内容
 func (h *LandingPageHandler) ResetLandingPage(c *gin.Context) {
 	defaultHTML := DefaultLandingPageHTML
+	slug, ok := parseLandingPageSlug(c)
+	if !ok {
+		return
+	}
 
 	adminID, adminIDOK := middleware.RequireUserID(c)
 	if !adminIDOK {
@@ -312,7 +388,7 @@ func (h *LandingPageHandler) ResetLandingPage(c *gin.Context) {
 	}
 	if h.pluginManager != nil {
 		hookPayload := map[string]interface{}{
-			"slug":         "home",
+			"slug":         slug,
 			"html_content": defaultHTML,
 			"admin_id":     adminID,
 			"source":       "admin_api",
@@ -323,7 +399,7 @@ func (h *LandingPageHandler) ResetLandingPage(c *gin.Context) {
 		}, buildAdminHookExecutionContext(c, &adminID, map[string]string{
 			"hook_resource": "landing_page",
 			"hook_source":   "admin_api",
-			"page_slug":     "home",
+			"page_slug":     slug,
 		}))
 		if hookErr != nil {
 			log.Printf("landing_page.reset.before hook execution failed: admin=%d err=%v", adminID, hookErr)
@@ -348,11 +424,11 @@ func (h *LandingPageHandler) ResetLandingPage(c *gin.Context) {
 
 	var page models.LandingPage
 	created := false
-	err := h.db.Where("slug = ?", "home").First(&page).Error
+	err := h.db.Where("slug = ?", slug).First(&page).Error
 	if err != nil {
 		created = true
 		page = models.LandingPage{
-			Slug:        "home",
+			Slug:        slug,
 			HTMLContent: defaultHTML,
 			IsActive:    true,
 			UpdatedBy:   uid,
@@ -363,6 +439,7 @@ func (h *LandingPageHandler) ResetLandingPage(c *gin.Context) {
 		}
 	} else {
 		page.HTMLContent = defaultHTML
+		page.Mode = models.LandingPageModeHTML
 		page.UpdatedBy = uid
 		if err := h.db.Save(&page).Error; err != nil {
 			response.InternalError(c, "Failed to reset landing page")
@@ -405,7 +482,7 @@ func (h *LandingPageHandler) ResetLandingPage(c *gin.Context) {
 		}(cloneAdminHookExecutionContext(buildAdminHookExecutionContext(c, &adminID, map[string]string{
 			"hook_resource": "landing_page",
 			"hook_source":   "admin_api",
-			"page_slug":     "home",
+			"page_slug":     slug,
 		})), afterPayload, page.ID)
 	}
 
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"auralogic/internal/database"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/logger"
 	"auralogic/internal/pkg/response"
@@ -243,6 +244,12 @@ func (h *LogHandler) ListOperationLogs(c *gin.Context) {
 		return
 	}
 
+	// 日志详情中的敏感字段按字段打码策略处理
+	maskRules := resolveAdminFieldMask(c)
+	for i := range logs {
+		logs[i].Details = maskRules.MaskLogDetails(logs[i].Details)
+	}
+
 	response.Paginated(c, logs, page, limit, total)
 }
 
@@ -321,8 +328,10 @@ func (h *LogHandler) ExportOperationLogs(c *gin.Context) {
 		return
 	}
 
+	maskRules := resolveAdminFieldMask(c)
 	rows := make([][]string, 0, len(logs))
 	for _, item := range logs {
+		item.Details = maskRules.MaskLogDetails(item.Details)
 		userID := ""
 		if item.UserID != nil {
 			userID = strconv.FormatUint(uint64(*item.UserID), 10)
@@ -740,3 +749,30 @@ func (h *LogHandler) RetryFailedEmails(c *gin.Context) {
 		"affected": result.RowsAffected,
 	})
 }
+
+// ListSlowQueries 慢查询报表（按SQL指纹聚合，进程内统计，重启后清空）
+func (h *LogHandler) ListSlowQueries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	response.Success(c, gin.H{
+		"threshold_ms": database.SlowQueryThreshold().Milliseconds(),
+		"items":        database.SlowQueryReport(limit),
+	})
+}
+
+// ListBackgroundJobs 后台任务运行状态（进程内统计，重启后清空）
+func (h *LogHandler) ListBackgroundJobs(c *gin.Context) {
+	items := []service.BackgroundJobStatus{}
+	if scheduler := service.GlobalBackgroundScheduler(); scheduler != nil {
+		items = scheduler.Statuses()
+	}
+	response.Success(c, gin.H{"items": items})
+}
+
+// ResetSlowQueries 清空慢查询统计
+func (h *LogHandler) ResetSlowQueries(c *gin.Context) {
+	database.ResetSlowQueries()
+	response.Success(c, gin.H{"message": "Slow query statistics cleared"})
+}
@@ -0,0 +1,198 @@
+package admin
+
+import (
+	"strconv"
+
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService *service.AdminNotificationService
+}
+
+func NewNotificationHandler(notificationService *service.AdminNotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+func parseNotificationID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *NotificationHandler) respondNotificationError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// GetNotificationMeta 可订阅事件（含当前管理员是否有权订阅）、渠道及 Telegram 是否可用
+func (h *NotificationHandler) GetNotificationMeta(c *gin.Context) {
+	events := make([]gin.H, 0, len(service.AdminNotificationEvents()))
+	for _, event := range service.AdminNotificationEvents() {
+		permission := service.AdminNotificationEventPermission(event)
+		events = append(events, gin.H{
+			"event":      event,
+			"permission": permission,
+			"allowed":    middleware.HasAllPermissions(c, permission),
+		})
+	}
+	response.Success(c, gin.H{
+		"events":              events,
+		"channels":            service.AdminNotificationChannels(),
+		"telegram_configured": h.notificationService.TelegramConfigured(),
+	})
+}
+
+// bindSubscriptionInput 解析订阅参数并确认管理员拥有该事件对应的查看权限
+func (h *NotificationHandler) bindSubscriptionInput(c *gin.Context) (service.AdminNotificationSubscriptionInput, bool) {
+	var req service.AdminNotificationSubscriptionInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return req, false
+	}
+	if permission := service.AdminNotificationEventPermission(req.Event); permission != "" && !middleware.HasAllPermissions(c, permission) {
+		response.Forbidden(c, "No permission to subscribe to this event")
+		return req, false
+	}
+	return req, true
+}
+
+// ListNotificationSubscriptions 当前管理员的订阅
+func (h *NotificationHandler) ListNotificationSubscriptions(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	subscriptions, err := h.notificationService.ListSubscriptions(adminID)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": subscriptions})
+}
+
+// CreateNotificationSubscription 新建订阅
+func (h *NotificationHandler) CreateNotificationSubscription(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindSubscriptionInput(c)
+	if !ok {
+		return
+	}
+	subscription, err := h.notificationService.CreateSubscription(adminID, req)
+	if err != nil {
+		h.respondNotificationError(c, err, "Failed to create subscription")
+		return
+	}
+	response.Success(c, subscription)
+}
+
+// UpdateNotificationSubscription 更新订阅
+func (h *NotificationHandler) UpdateNotificationSubscription(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseNotificationID(c)
+	if !ok {
+		return
+	}
+	req, ok := h.bindSubscriptionInput(c)
+	if !ok {
+		return
+	}
+	subscription, err := h.notificationService.UpdateSubscription(adminID, id, req)
+	if err != nil {
+		h.respondNotificationError(c, err, "Failed to update subscription")
+		return
+	}
+	response.Success(c, subscription)
+}
+
+// DeleteNotificationSubscription 删除订阅
+func (h *NotificationHandler) DeleteNotificationSubscription(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseNotificationID(c)
+	if !ok {
+		return
+	}
+	if err := h.notificationService.DeleteSubscription(adminID, id); err != nil {
+		h.respondNotificationError(c, err, "Failed to delete subscription")
+		return
+	}
+	response.Success(c, gin.H{"message": "Subscription deleted"})
+}
+
+// ListNotifications 站内通知，unread=1 时只返回未读
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	page, limit := response.GetPagination(c)
+	unreadOnly := c.Query("unread") == "1" || c.Query("unread") == "true"
+	notifications, total, err := h.notificationService.ListNotifications(adminID, unreadOnly, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, notifications, page, limit, total)
+}
+
+// GetUnreadNotificationCount 未读站内通知数
+func (h *NotificationHandler) GetUnreadNotificationCount(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	count, err := h.notificationService.UnreadCount(adminID)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"count": count})
+}
+
+// MarkNotificationRead 标记单条通知为已读
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseNotificationID(c)
+	if !ok {
+		return
+	}
+	if err := h.notificationService.MarkRead(adminID, id); err != nil {
+		h.respondNotificationError(c, err, "Failed to update notification")
+		return
+	}
+	response.Success(c, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllNotificationsRead 全部标记为已读
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	updated, err := h.notificationService.MarkAllRead(adminID)
+	if err != nil {
+		response.InternalError(c, "Failed to update notifications")
+		return
+	}
+	response.Success(c, gin.H{"updated": updated})
+}
@@ -0,0 +1,208 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type OrderAutomationHandler struct {
+	automationService *service.OrderAutomationService
+}
+
+func NewOrderAutomationHandler(automationService *service.OrderAutomationService) *OrderAutomationHandler {
+	return &OrderAutomationHandler{automationService: automationService}
+}
+
+// OrderAutomationDryRunRequest 试运行请求：rule_id 为已保存规则，否则使用 rule 中的未保存配置
+type OrderAutomationDryRunRequest struct {
+	RuleID  uint                              `json:"rule_id"`
+	Rule    *service.OrderAutomationRuleInput `json:"rule"`
+	OrderNo string                            `json:"order_no" binding:"required"`
+}
+
+func parseOrderAutomationRuleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *OrderAutomationHandler) respondAutomationError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// GetAutomationMeta 条件构建器元数据：触发事件、字段与运算符、动作类型、消息占位符
+func (h *OrderAutomationHandler) GetAutomationMeta(c *gin.Context) {
+	response.Success(c, gin.H{
+		"triggers":     service.OrderAutomationTriggers(),
+		"fields":       service.OrderAutomationFields(),
+		"actions":      service.OrderAutomationActionTypes(),
+		"placeholders": service.SupportedOrderAutomationPlaceholders(),
+	})
+}
+
+// ListAutomationRules 规则列表（按执行顺序）
+func (h *OrderAutomationHandler) ListAutomationRules(c *gin.Context) {
+	rules, err := h.automationService.ListRules(c.Query("trigger"))
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": rules})
+}
+
+// GetAutomationRule 规则详情
+func (h *OrderAutomationHandler) GetAutomationRule(c *gin.Context) {
+	id, ok := parseOrderAutomationRuleID(c)
+	if !ok {
+		return
+	}
+	rule, err := h.automationService.GetRule(id)
+	if err != nil {
+		h.respondAutomationError(c, err, "Failed to load automation rule")
+		return
+	}
+	response.Success(c, rule)
+}
+
+// CreateAutomationRule 创建规则
+func (h *OrderAutomationHandler) CreateAutomationRule(c *gin.Context) {
+	var req service.OrderAutomationRuleInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	var createdBy *uint
+	if adminID, ok := middleware.GetUserID(c); ok {
+		createdBy = &adminID
+	}
+	rule, err := h.automationService.CreateRule(req, createdBy)
+	if err != nil {
+		h.respondAutomationError(c, err, "Failed to create automation rule")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create", "order_automation_rule", &rule.ID, map[string]interface{}{
+		"name":    rule.Name,
+		"trigger": rule.Trigger,
+		"enabled": rule.Enabled,
+	})
+	response.Success(c, rule)
+}
+
+// UpdateAutomationRule 更新规则
+func (h *OrderAutomationHandler) UpdateAutomationRule(c *gin.Context) {
+	id, ok := parseOrderAutomationRuleID(c)
+	if !ok {
+		return
+	}
+	var req service.OrderAutomationRuleInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	rule, err := h.automationService.UpdateRule(id, req)
+	if err != nil {
+		h.respondAutomationError(c, err, "Failed to update automation rule")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "update", "order_automation_rule", &rule.ID, map[string]interface{}{
+		"name":    rule.Name,
+		"trigger": rule.Trigger,
+		"enabled": rule.Enabled,
+	})
+	response.Success(c, rule)
+}
+
+// DeleteAutomationRule 删除规则
+func (h *OrderAutomationHandler) DeleteAutomationRule(c *gin.Context) {
+	id, ok := parseOrderAutomationRuleID(c)
+	if !ok {
+		return
+	}
+	if err := h.automationService.DeleteRule(id); err != nil {
+		h.respondAutomationError(c, err, "Failed to delete automation rule")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "delete", "order_automation_rule", &id, nil)
+	response.Success(c, gin.H{"message": "Automation rule deleted"})
+}
+
+// DryRunAutomationRule 用指定订单试运行规则，不执行任何动作
+func (h *OrderAutomationHandler) DryRunAutomationRule(c *gin.Context) {
+	var req OrderAutomationDryRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	var input service.OrderAutomationRuleInput
+	switch {
+	case req.RuleID > 0:
+		rule, err := h.automationService.GetRule(req.RuleID)
+		if err != nil {
+			h.respondAutomationError(c, err, "Failed to load automation rule")
+			return
+		}
+		input = service.OrderAutomationRuleInput{
+			Name:           rule.Name,
+			Trigger:        rule.Trigger,
+			Enabled:        rule.Enabled,
+			MatchType:      rule.MatchType,
+			Conditions:     rule.Conditions,
+			Actions:        rule.Actions,
+			Priority:       rule.Priority,
+			StopProcessing: rule.StopProcessing,
+		}
+	case req.Rule != nil:
+		input = *req.Rule
+	default:
+		response.BadRequest(c, "rule_id or rule is required")
+		return
+	}
+
+	result, err := h.automationService.DryRun(input, req.OrderNo)
+	if err != nil {
+		h.respondAutomationError(c, err, "Failed to dry-run automation rule")
+		return
+	}
+	response.Success(c, result)
+}
+
+// ListAutomationRuns 执行日志
+func (h *OrderAutomationHandler) ListAutomationRuns(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+	filter := service.OrderAutomationRunFilter{
+		OrderNo: strings.TrimSpace(c.Query("order_no")),
+		Status:  strings.TrimSpace(c.Query("status")),
+	}
+	if raw := strings.TrimSpace(c.Query("rule_id")); raw != "" {
+		ruleID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			response.BadRequest(c, "Invalid rule_id")
+			return
+		}
+		filter.RuleID = uint(ruleID)
+	}
+
+	runs, total, err := h.automationService.ListRuns(filter, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, runs, page, limit, total)
+}
@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/validator"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OrderCodeLookupHandler 客服按卡密/序列号反查订单
+type OrderCodeLookupHandler struct {
+	db            *gorm.DB
+	lookupService *service.OrderCodeLookupService
+}
+
+func NewOrderCodeLookupHandler(db *gorm.DB, lookupService *service.OrderCodeLookupService) *OrderCodeLookupHandler {
+	return &OrderCodeLookupHandler{db: db, lookupService: lookupService}
+}
+
+// OrderCodeLookupRequest 使用 POST body 传递卡密，避免明文出现在访问日志中
+type OrderCodeLookupRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// LookupByCode 按卡密或序列号定位订单与买家，每次查询都记录操作日志（只记录哈希）
+func (h *OrderCodeLookupHandler) LookupByCode(c *gin.Context) {
+	if h == nil || h.lookupService == nil {
+		response.InternalError(c, "Code lookup is unavailable")
+		return
+	}
+	var req OrderCodeLookupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	code := strings.TrimSpace(req.Code)
+	if !validator.ValidateLength(code, 1, 500) {
+		response.BadRequest(c, "Code must be 1-500 characters")
+		return
+	}
+	storeIDs, err := loadAdminStoreIDs(c)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+
+	matches, err := h.lookupService.Lookup(code, storeIDs)
+	if err != nil {
+		response.InternalError(c, "Failed to look up code")
+		return
+	}
+
+	orderNos := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if match.OrderNo != "" {
+			orderNos = append(orderNos, match.OrderNo)
+		}
+	}
+	logger.LogOperation(h.db, c, "code_lookup", "order", nil, map[string]interface{}{
+		"code_hash":   models.HashVirtualStockContent(code),
+		"code_masked": models.MaskVirtualStockContent(code),
+		"matches":     len(matches),
+		"order_nos":   orderNos,
+	})
+
+	response.Success(c, gin.H{"items": matches})
+}
@@ -0,0 +1,183 @@
+package admin
+
+import (
+	"bytes"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"auralogic/internal/pkg/constants"
+	"auralogic/internal/pkg/listfilter"
+	"auralogic/internal/pkg/money"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/repository"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SetCustomsService 设置报关单服务
+func (h *OrderHandler) SetCustomsService(customsService *service.CustomsDeclarationService) {
+	h.customsService = customsService
+}
+
+// GetCustomsDeclaration 订单报关单，format=html 时返回可打印的 CN22/CN23
+func (h *OrderHandler) GetCustomsDeclaration(c *gin.Context) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+	resolveAdminFieldMask(c).ApplyToOrder(order)
+
+	declaration, err := h.customsService.Build(order)
+	if err != nil {
+		response.InternalError(c, "Failed to build customs declaration")
+		return
+	}
+	if declaration == nil {
+		response.BadRequest(c, "Order has no physical items to declare")
+		return
+	}
+
+	if c.Query("format") != "html" {
+		response.Success(c, gin.H{
+			"international": h.customsService.IsInternational(order),
+			"declaration":   declaration,
+		})
+		return
+	}
+	var buf bytes.Buffer
+	if err := customsDeclarationTemplate.Execute(&buf, declaration); err != nil {
+		response.InternalError(c, "Failed to render customs declaration")
+		return
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.String(200, buf.String())
+}
+
+// ExportCustomsDeclarations 按订单列表筛选条件导出国际件报关明细 CSV（每个商品一行）
+func (h *OrderHandler) ExportCustomsDeclarations(c *gin.Context) {
+	var req ExportOrdersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	var promoCodeID *uint
+	if req.PromoCodeID != "" {
+		if pid, err := strconv.ParseUint(req.PromoCodeID, 10, 32); err == nil {
+			pidUint := uint(pid)
+			promoCodeID = &pidUint
+		}
+	}
+	advanced, err := listfilter.Parse(req.Filter, repository.OrderListFilterSchema)
+	if err != nil {
+		respondAdminBizError(c, err)
+		return
+	}
+	storeScope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
+	orders, _, err := h.orderService.ListOrders(1, 10000, req.Status, req.SubStatus, service.SplitOrderTagQuery(req.Tag), req.Search, req.Country, req.ProductSearch, promoCodeID, strings.ToUpper(strings.TrimSpace(req.PromoCode)), nil, storeScope, advanced)
+	if err != nil {
+		response.InternalError(c, "QueryOrderFailed")
+		return
+	}
+
+	maskRules := resolveAdminFieldMask(c)
+	international := orders[:0]
+	for i := range orders {
+		if h.customsService.IsInternational(&orders[i]) {
+			maskRules.ApplyToOrder(&orders[i])
+			international = append(international, orders[i])
+		}
+	}
+	declarations, err := h.customsService.BuildForOrders(international)
+	if err != nil {
+		response.InternalError(c, "Failed to build customs declaration")
+		return
+	}
+
+	headers := []string{
+		"Order No.", "Form", "Content", "Receiver", "Receiver Country", "Receiver Address", "Postcode",
+		"SKU", "Description", "HS Code", "Origin Country", "Quantity", "Net Weight (g)", "Value", "Currency",
+		"Total Weight (g)", "Total Value",
+	}
+	rows := make([][]string, 0, len(declarations))
+	for _, declaration := range declarations {
+		for _, item := range declaration.Items {
+			rows = append(rows, []string{
+				declaration.OrderNo,
+				declaration.FormType,
+				declaration.ContentCategory,
+				declaration.ReceiverName,
+				declaration.ReceiverCountry,
+				declaration.ReceiverAddress,
+				declaration.ReceiverPostcode,
+				item.SKU,
+				item.Description,
+				item.HSCode,
+				item.OriginCountry,
+				strconv.Itoa(item.Quantity),
+				strconv.Itoa(item.WeightGrams),
+				money.MinorToString(item.ValueMinor),
+				declaration.Currency,
+				strconv.Itoa(declaration.TotalWeightGrams),
+				money.MinorToString(declaration.TotalValueMinor),
+			})
+		}
+	}
+	writeCSVAttachment(c, buildAdminCSVFileName("customs_declarations"), headers, rows)
+}
+
+var customsDeclarationTemplate = template.Must(template.New("customs").Funcs(template.FuncMap{
+	"amount":  money.MinorToString,
+	"country": constants.GetCountryNameEN,
+	"kg": func(grams int) string {
+		return strconv.FormatFloat(float64(grams)/1000, 'f', 3, 64)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.FormType}} - {{.OrderNo}}</title>
+<style>
+body{font-family:Arial,Helvetica,sans-serif;font-size:12px;margin:24px;color:#111}
+h1{font-size:18px;margin:0 0 4px}
+table{width:100%;border-collapse:collapse;margin-top:12px}
+th,td{border:1px solid #333;padding:4px 6px;text-align:left;vertical-align:top}
+th{background:#f2f2f2}
+.num{text-align:right}
+.parties{display:flex;gap:12px}
+.parties div{flex:1;border:1px solid #333;padding:6px}
+.warn{color:#b45309;margin-top:8px}
+@media print{.no-print{display:none}}
+</style>
+</head>
+<body>
+<button class="no-print" onclick="window.print()">Print</button>
+<h1>CUSTOMS DECLARATION {{.FormType}}</h1>
+<div>Order: {{.OrderNo}} &middot; Category: {{.ContentCategory}}</div>
+<div class="parties">
+<div><strong>From</strong><br>{{.SenderName}}<br>{{.SenderAddress}}<br>{{.SenderPhone}}<br>{{country .SenderCountry}}</div>
+<div><strong>To</strong><br>{{.ReceiverName}}<br>{{.ReceiverAddress}} {{.ReceiverPostcode}}<br>{{.ReceiverPhone}}<br>{{country .ReceiverCountry}}</div>
+</div>
+<table>
+<thead><tr><th>Description of contents</th><th>HS tariff number</th><th>Origin</th><th class="num">Qty</th><th class="num">Net weight (kg)</th><th class="num">Value ({{.Currency}})</th></tr></thead>
+<tbody>
+{{range .Items}}<tr><td>{{.Description}} ({{.SKU}})</td><td>{{.HSCode}}</td><td>{{.OriginCountry}}</td><td class="num">{{.Quantity}}</td><td class="num">{{kg .WeightGrams}}</td><td class="num">{{amount .ValueMinor}}</td></tr>
+{{end}}<tr><th colspan="4">Total</th><th class="num">{{kg .TotalWeightGrams}}</th><th class="num">{{amount .TotalValueMinor}}</th></tr>
+</tbody>
+</table>
+{{if .MissingHSCodes}}<div class="warn no-print">Missing HS code: {{range $i, $sku := .MissingHSCodes}}{{if $i}}, {{end}}{{$sku}}{{end}}</div>{{end}}
+<p>I certify that the particulars given in this declaration are correct and that this item does not contain any dangerous article prohibited by legislation or by postal or customs regulations.</p>
+<p>Date and sender's signature: ______________________</p>
+</body>
+</html>`))
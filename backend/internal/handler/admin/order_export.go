@@ -10,7 +10,10 @@ import (
 
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/constants"
+	"auralogic/internal/pkg/listfilter"
+	"auralogic/internal/pkg/money"
 	"auralogic/internal/pkg/response"
+	"auralogic/internal/repository"
 	"auralogic/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/xuri/excelize/v2"
@@ -19,11 +22,14 @@ import (
 // ExportOrdersRequest 导出Order请求
 type ExportOrdersRequest struct {
 	Status        string `form:"status" json:"status"`                 // Order状态过滤
+	SubStatus     string `form:"sub_status" json:"sub_status"`         // 自定义子状态过滤
+	Tag           string `form:"tag" json:"tag"`                       // 标签过滤，逗号分隔需同时包含
 	Search        string `form:"search" json:"search"`                 // 搜索关键词
 	Country       string `form:"country" json:"country"`               // 国家过滤
 	ProductSearch string `form:"product_search" json:"product_search"` // ProductSKU/名称搜索
 	PromoCode     string `form:"promo_code" json:"promo_code"`         // Promo code
 	PromoCodeID   string `form:"promo_code_id" json:"promo_code_id"`   // Promo code id
+	Filter        string `form:"filter" json:"filter"`                 // 高级筛选 JSON
 }
 
 type orderImportEntry struct {
@@ -70,6 +76,44 @@ func buildOrderImportEntries(rows [][]string) []orderImportEntry {
 	return entries
 }
 
+// writeOrderItemsSheet 写入订单项明细工作表，旧订单没有价格快照时金额列留空
+func writeOrderItemsSheet(f *excelize.File, orders []models.Order, headerStyle int) error {
+	sheetName := "Order Items"
+	if _, err := f.NewSheet(sheetName); err != nil {
+		return err
+	}
+
+	headers := []string{"Order No.", "SKU", "Product Name", "Quantity", "Unit Price", "Discount", "Line Total", "Currency"}
+	for i, header := range headers {
+		cell := string(rune('A'+i)) + "1"
+		f.SetCellValue(sheetName, cell, header)
+		f.SetCellStyle(sheetName, cell, cell, headerStyle)
+	}
+	f.SetColWidth(sheetName, "A", "A", 18)
+	f.SetColWidth(sheetName, "B", "B", 18)
+	f.SetColWidth(sheetName, "C", "C", 30)
+
+	row := 2
+	for i := range orders {
+		order := &orders[i]
+		hasItemPricing := order.HasItemPricing()
+		for _, item := range order.Items {
+			values := []interface{}{order.OrderNo, item.SKU, item.Name, item.Quantity, "", "", "", order.Currency}
+			if hasItemPricing {
+				values[4] = money.MinorToString(item.UnitPriceMinor)
+				values[5] = money.MinorToString(item.DiscountMinor)
+				values[6] = money.MinorToString(item.LineTotalMinor)
+			}
+			for j, value := range values {
+				cell := string(rune('A'+j)) + strconv.Itoa(row)
+				f.SetCellValue(sheetName, cell, value)
+			}
+			row++
+		}
+	}
+	return nil
+}
+
 // ExportOrders 导出Order到Excel
 func (h *OrderHandler) ExportOrders(c *gin.Context) {
 	var req ExportOrdersRequest
@@ -128,16 +172,25 @@ func (h *OrderHandler) ExportOrders(c *gin.Context) {
 			promoCodeID = &pidUint
 		}
 	}
-	orders, _, err := h.orderService.ListOrders(1, 10000, req.Status, req.Search, req.Country, req.ProductSearch, promoCodeID, promoCode, nil)
+	advanced, err := listfilter.Parse(req.Filter, repository.OrderListFilterSchema)
+	if err != nil {
+		respondAdminBizError(c, err)
+		return
+	}
+	storeScope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
+	orders, _, err := h.orderService.ListOrders(1, 10000, req.Status, req.SubStatus, service.SplitOrderTagQuery(req.Tag), req.Search, req.Country, req.ProductSearch, promoCodeID, promoCode, nil, storeScope, advanced)
 	if err != nil {
 		response.InternalError(c, "QueryOrderFailed")
 		return
 	}
 
-	// Check if admin has privacy view permission
-	hasPrivacyPerm := h.hasPrivacyPermission(c)
+	// 导出与列表使用相同的字段打码策略
+	maskRules := resolveAdminFieldMask(c)
 	for i := range orders {
-		h.orderService.MaskOrderIfNeeded(&orders[i], hasPrivacyPerm)
+		maskRules.ApplyToOrder(&orders[i])
 	}
 
 	// CreateExcel文件
@@ -185,7 +238,7 @@ func (h *OrderHandler) ExportOrders(c *gin.Context) {
 		"Order No.", "User Email", "Order Status", "Privacy Protected",
 		"Recipient", "Phone", "Country", "Province", "City", "District", "Address", "Postcode",
 		"Tracking No.",
-		"Created At", "Completed At",
+		"Created At", "Completed At", "Tags",
 	}
 
 	for i, header := range headers {
@@ -211,6 +264,7 @@ func (h *OrderHandler) ExportOrders(c *gin.Context) {
 		"M": 20, // 物流单号
 		"N": 20, // Create时间
 		"O": 20, // 完成时间
+		"P": 25, // 标签
 	}
 
 	for col, width := range colWidths {
@@ -280,6 +334,7 @@ func (h *OrderHandler) ExportOrders(c *gin.Context) {
 			order.TrackingNo,
 			order.CreatedAt.Format("2006-01-02 15:04:05"),
 			completedAt,
+			strings.Join(order.Tags, ", "),
 		}
 
 		for j, value := range values {
@@ -288,6 +343,12 @@ func (h *OrderHandler) ExportOrders(c *gin.Context) {
 		}
 	}
 
+	// 订单项明细（金额为下单时快照）
+	if err := writeOrderItemsSheet(f, orders, headerStyle); err != nil {
+		response.InternalError(c, "CreateExcelFailed")
+		return
+	}
+
 	// 设置默认工作表
 	f.SetActiveSheet(index)
 
@@ -316,7 +377,7 @@ func (h *OrderHandler) ExportOrders(c *gin.Context) {
 			"exported_count":         len(orders),
 			"matched_total":          len(orders),
 			"file_name":              fileName,
-			"has_privacy_permission": hasPrivacyPerm,
+			"has_privacy_permission": len(maskRules) == 0,
 			"admin_id":               adminIDValue,
 			"source":                 "admin_api",
 		}
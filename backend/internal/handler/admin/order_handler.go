@@ -3,6 +3,7 @@ package admin
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"strconv"
 	"strings"
@@ -13,11 +14,14 @@ import (
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/listfilter"
 	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/money"
 	"auralogic/internal/pkg/orderbiz"
 	"auralogic/internal/pkg/response"
 	"auralogic/internal/pkg/utils"
 	"auralogic/internal/pkg/validator"
+	"auralogic/internal/repository"
 	"auralogic/internal/service"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -29,6 +33,17 @@ type OrderHandler struct {
 	virtualInventoryService *service.VirtualInventoryService
 	jsRuntimeService        *service.JSRuntimeService
 	pluginManager           *service.PluginManagerService
+	shortLinkService        *service.ShortLinkService
+	ledgerService           *service.LedgerService
+	refundService           *service.OrderRefundService
+	subStatusService        *service.OrderSubStatusService
+	noteService             *service.OrderNoteService
+	messageService          *service.OrderMessageService
+	regionService           *service.RegionService
+	shippingRestrictions    *service.ShippingRestrictionService
+	customsService          *service.CustomsDeclarationService
+	packagePlanService      *service.PackagePlanService
+	shipmentTrackingService *service.ShipmentTrackingService
 	cfg                     *config.Config
 }
 
@@ -39,10 +54,25 @@ func NewOrderHandler(orderService *service.OrderService, serialService *service.
 		virtualInventoryService: virtualInventoryService,
 		jsRuntimeService:        jsRuntimeService,
 		pluginManager:           pluginManager,
+		regionService:           service.NewRegionService(cfg),
 		cfg:                     cfg,
 	}
 }
 
+// SetShortLinkService 设置短链服务
+func (h *OrderHandler) SetShortLinkService(shortLinkService *service.ShortLinkService) {
+	h.shortLinkService = shortLinkService
+}
+
+func (h *OrderHandler) SetLedgerService(ledgerService *service.LedgerService) {
+	h.ledgerService = ledgerService
+}
+
+// SetShippingRestrictionService 设置商品配送限制校验
+func (h *OrderHandler) SetShippingRestrictionService(shippingRestrictions *service.ShippingRestrictionService) {
+	h.shippingRestrictions = shippingRestrictions
+}
+
 func respondAdminOrderServiceError(c *gin.Context, err error, fallback string) bool {
 	if err == nil {
 		return false
@@ -88,6 +118,19 @@ func (h *OrderHandler) buildShippingFormURL(formToken *string) string {
 	return baseURL + "/form/shipping?token=" + *formToken
 }
 
+// buildShippingFormShortURL 收货表单短链，未启用短链或不可用时返回空
+func (h *OrderHandler) buildShippingFormShortURL(c *gin.Context, order *models.Order) string {
+	if h.shortLinkService == nil || order == nil || order.FormToken == nil {
+		return ""
+	}
+	link, err := h.shortLinkService.ForShippingForm(order, adminShortLinkCreator(c))
+	if err != nil {
+		log.Printf("build shipping form short link failed: order=%d err=%v", order.ID, err)
+		return ""
+	}
+	return h.shortLinkService.ShortURL(link)
+}
+
 func (h *OrderHandler) buildOrderHookExecutionContext(c *gin.Context, adminID uint, orderID uint) *service.ExecutionContext {
 	if c == nil {
 		return nil
@@ -366,33 +409,12 @@ func applyAdminMarkPaidHookPayload(options *service.MarkAsPaidOptions, payload m
 	return nil
 }
 
-// hasPrivacyPermission Check if admin has permission to view privacy info
-// Note: Even super admin needs explicit order.view_privacy permission to view privacy orders
-// Only shipping managers and similar roles who need to view shipping info should have this permission
-func (h *OrderHandler) hasPrivacyPermission(c *gin.Context) bool {
-	userID, exists := middleware.GetUserID(c)
-	if !exists {
-		return false
-	}
-
-	db := database.GetDB()
-
-	// Query admin permission
-	var perm models.AdminPermission
-	if err := db.Where("user_id = ?", userID).First(&perm).Error; err != nil {
-		// If no permission record exists, return false (including super admin)
-		return false
-	}
-
-	// Check for order.view_privacy permission
-	hasPerm := perm.HasPermission("order.view_privacy")
-	return hasPerm
-}
-
 // ListOrders Order List
 func (h *OrderHandler) ListOrders(c *gin.Context) {
 	page, limit := response.GetPagination(c)
 	status := c.Query("status")
+	subStatus := strings.TrimSpace(c.Query("sub_status"))
+	tags := service.SplitOrderTagQuery(c.Query("tag"))
 	search := c.Query("search")
 	country := c.Query("country")
 	productSearch := c.Query("product_search") // 新增：按ProductSKU/名称搜索
@@ -417,16 +439,27 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 		}
 	}
 
-	orders, total, err := h.orderService.ListOrders(page, limit, status, search, country, productSearch, promoCodeID, promoCode, userID)
+	advanced, err := listfilter.Parse(c.Query("filter"), repository.OrderListFilterSchema)
+	if err != nil {
+		respondAdminBizError(c, err)
+		return
+	}
+
+	storeScope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
+
+	orders, total, err := h.orderService.ListOrders(page, limit, status, subStatus, tags, search, country, productSearch, promoCodeID, promoCode, userID, storeScope, advanced)
 	if err != nil {
 		response.InternalError(c, "Query failed")
 		return
 	}
 
-	// Check if admin has privacy view permission, mask if not
-	hasPrivacyPerm := h.hasPrivacyPermission(c)
+	// 按当前管理员的字段打码策略处理敏感信息
+	maskRules := resolveAdminFieldMask(c)
 	for i := range orders {
-		h.orderService.MaskOrderIfNeeded(&orders[i], hasPrivacyPerm)
+		maskRules.ApplyToOrder(&orders[i])
 	}
 
 	response.Paginated(c, orders, page, limit, total)
@@ -445,6 +478,15 @@ func (h *OrderHandler) GetOrderCountries(c *gin.Context) {
 	})
 }
 
+// adminOrderPaymentRow 订单付款方式及关联付款方式的展示字段
+type adminOrderPaymentRow struct {
+	models.OrderPaymentMethod
+	MethodID   *uint
+	MethodName string
+	MethodIcon string
+	MethodType models.PaymentMethodType
+}
+
 // GetOrder - Get order details
 func (h *OrderHandler) GetOrder(c *gin.Context) {
 	orderID, ok := parseAdminOrderID(c)
@@ -457,14 +499,17 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 
-	// Check if admin has privacy view permission, mask if not
-	hasPrivacyPerm := h.hasPrivacyPermission(c)
-	h.orderService.MaskOrderIfNeeded(order, hasPrivacyPerm)
+	// 按当前管理员的字段打码策略处理敏感信息
+	maskRules := resolveAdminFieldMask(c)
+	maskRules.ApplyToOrder(order)
 
 	// 获取该订单的序列号
 	var serials interface{}
-	warnings := make([]string, 0, 3)
+	warnings := make([]string, 0, 4)
 	if h.serialService != nil {
 		serialList, err := h.serialService.GetSerialsByOrderID(orderID)
 		if err != nil {
@@ -484,8 +529,9 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 			log.Printf("admin.get_order failed to load virtual stocks: order_no=%s err=%v", order.OrderNo, err)
 			warnings = append(warnings, "Failed to load order virtual stock")
 		} else if len(stockList) > 0 {
-			if !h.cfg.Order.EnableVirtualStockInlineIframe {
-				for i := range stockList {
+			for i := range stockList {
+				stockList[i].MaskContent()
+				if !h.cfg.Order.EnableVirtualStockInlineIframe {
 					stockList[i].Presentation = ""
 				}
 			}
@@ -502,40 +548,62 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 	// 获取订单付款信息
 	var paymentInfo interface{}
 	db := database.GetDB()
-	var opm models.OrderPaymentMethod
-	if err := db.Where("order_id = ?", orderID).First(&opm).Error; err == nil {
-		var pm models.PaymentMethod
-		if err := db.First(&pm, opm.PaymentMethodID).Error; err == nil {
+	var opm adminOrderPaymentRow
+	// 订单付款方式与付款方式展示字段一次联表取出，避免加载脚本/配置等大字段
+	if err := db.Table("order_payment_methods AS opm").
+		Select("opm.*, pm.id AS method_id, pm.name AS method_name, pm.icon AS method_icon, pm.type AS method_type").
+		Joins("LEFT JOIN payment_methods pm ON pm.id = opm.payment_method_id").
+		Where("opm.order_id = ?", orderID).
+		Take(&opm).Error; err == nil {
+		if opm.MethodID != nil {
 			paymentInfo = gin.H{
 				"payment_method": gin.H{
-					"id":   pm.ID,
-					"name": pm.Name,
-					"icon": pm.Icon,
-					"type": pm.Type,
+					"id":   *opm.MethodID,
+					"name": opm.MethodName,
+					"icon": opm.MethodIcon,
+					"type": opm.MethodType,
 				},
 				"selected_at":                   opm.CreatedAt,
 				"updated_at":                    opm.UpdatedAt,
-				"payment_data":                  opm.PaymentData,
+				"payment_data":                  maskRules.MaskPaymentData(opm.PaymentData, order.PrivacyProtected),
 				"payment_card_cached":           strings.TrimSpace(opm.PaymentCardCache) != "",
 				"payment_card_cache_expires_at": opm.CacheExpiresAt,
 			}
-		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-			log.Printf("admin.get_order failed to load payment method: order_id=%d payment_method_id=%d err=%v", orderID, opm.PaymentMethodID, err)
-			warnings = append(warnings, "Failed to load order payment method")
 		}
 	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Printf("admin.get_order failed to load payment method mapping: order_id=%d err=%v", orderID, err)
 		warnings = append(warnings, "Failed to load order payment information")
 	}
 
+	// 获取订单内部备注
+	var notes interface{}
+	if h.noteService != nil {
+		noteList, err := h.noteService.List(orderID)
+		if err != nil {
+			log.Printf("admin.get_order failed to load notes: order_id=%d err=%v", orderID, err)
+			warnings = append(warnings, "Failed to load order notes")
+		} else {
+			notes = noteList
+		}
+	}
+
+	// 已并入本单的订单
+	mergedOrders, err := h.orderService.ListMergedOrders(orderID)
+	if err != nil {
+		log.Printf("admin.get_order failed to load merged orders: order_id=%d err=%v", orderID, err)
+		warnings = append(warnings, "Failed to load merged orders")
+	}
+
 	// 返回订单信息和序列号
 	payload := gin.H{
 		"order":                     order,
+		"notes":                     notes,
 		"serials":                   serials,
 		"virtual_stocks":            virtualStocks,
 		"has_pending_virtual_stock": hasPendingVirtualStock,
 		"payment_info":              paymentInfo,
 		"form_url":                  h.buildShippingFormURL(order.FormToken),
+		"merged_orders":             mergedOrders,
 	}
 	if len(warnings) > 0 {
 		payload["warnings"] = warnings
@@ -568,6 +636,15 @@ func (h *OrderHandler) AssignTracking(c *gin.Context) {
 		return
 	}
 
+	current, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, current.StoreID) {
+		return
+	}
+
 	if err := h.orderService.AssignTracking(orderID, req.TrackingNo); err != nil {
 		respondAdminOrderServiceError(c, err, "Failed to assign tracking number")
 		return
@@ -624,6 +701,9 @@ func (h *OrderHandler) CompleteOrder(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 	beforeStatus := order.Status
 	hookExecCtx := h.buildOrderHookExecutionContext(c, adminID, uint(orderID))
 	if h.pluginManager != nil {
@@ -736,6 +816,9 @@ func (h *OrderHandler) CancelOrder(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 	beforeStatus := order.Status
 	hookExecCtx := h.buildOrderHookExecutionContext(c, adminID, uint(orderID))
 	if h.pluginManager != nil {
@@ -850,6 +933,9 @@ func (h *OrderHandler) RefundOrder(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 	beforeStatus := order.Status
 	hookExecCtx := h.buildOrderHookExecutionContext(c, adminID, uint(orderID))
 	if h.pluginManager != nil {
@@ -886,84 +972,23 @@ func (h *OrderHandler) RefundOrder(c *gin.Context) {
 		}
 	}
 
-	// 只允许已付款后的订单退款（包括草稿状态，草稿表示已付款但用户尚未填写收货信息）
-	allowedStatuses := map[models.OrderStatus]bool{
-		models.OrderStatusDraft:        true,
-		models.OrderStatusPending:      true,
-		models.OrderStatusNeedResubmit: true,
-		models.OrderStatusShipped:      true,
-		models.OrderStatusCompleted:    true,
-	}
-	if !allowedStatuses[order.Status] {
+	if !service.OrderAcceptsRefund(order.Status) {
 		respondAdminOrderValidationError(c, orderbiz.RefundStatusInvalid(order.Status))
 		return
 	}
 
-	// 获取订单的付款方式
-	db := database.GetDB()
-	var opm models.OrderPaymentMethod
-	if err := db.Where("order_id = ?", orderID).First(&opm).Error; err != nil {
-		respondAdminOrderValidationError(c, orderbiz.OrderPaymentMethodNotFound())
-		return
-	}
-
-	var pm models.PaymentMethod
-	if err := db.First(&pm, opm.PaymentMethodID).Error; err != nil {
-		respondAdminOrderValidationError(c, orderbiz.PaymentMethodNotFound())
-		return
-	}
-
-	// 调用JS退款API
-	refundResult, err := h.jsRuntimeService.ExecuteRefund(&pm, order)
-	if err != nil {
-		response.InternalError(c, "Refund execution failed")
-		return
-	}
-
-	if !refundResult.Success {
-		msg := "Refund failed"
-		if refundResult.Message != "" {
-			msg = refundResult.Message
-		}
-		response.BadRequest(c, msg)
-		return
-	}
-
-	// 未发货的订单退款时释放预留库存（物理库存 + 虚拟库存 + 优惠码）
-	if order.Status == models.OrderStatusDraft || order.Status == models.OrderStatusPending || order.Status == models.OrderStatusNeedResubmit {
-		h.orderService.ReleaseOrderReserves(order)
-	}
-
-	nextStatus := models.OrderStatusRefunded
-	if refundResult.Pending {
-		nextStatus = models.OrderStatusRefundPending
-	}
-
-	// 更新订单状态
-	updates := map[string]interface{}{
-		"status": nextStatus,
-	}
-	if req.Reason != "" {
-		remark := order.AdminRemark
-		if remark != "" {
-			remark += "\n"
-		}
-		remark += "[Refund] " + req.Reason
-		updates["admin_remark"] = remark
-	}
-	if err := db.Model(order).Updates(updates).Error; err != nil {
-		response.InternalError(c, "Failed to update order status")
+	// 全额退还剩余可退金额（已有部分退款时只退剩余部分）
+	outcome, ok := h.executeOrderRefund(c, adminID, order, service.OrderRefundInput{
+		Reason:  req.Reason,
+		Source:  "admin_refund",
+		AdminID: &adminID,
+	})
+	if !ok {
 		return
 	}
-	if order.UserID != nil {
-		if err := h.orderService.SyncUserConsumptionStats(*order.UserID); err != nil {
-			logger.LogOrderOperation(db, c, "sync_user_consumption_stats_failed", order.ID, map[string]interface{}{
-				"order_no": order.OrderNo,
-				"user_id":  *order.UserID,
-				"error":    err.Error(),
-			})
-		}
-	}
+	refundResult := outcome.Result
+	nextStatus := outcome.StatusAfter
+	db := database.GetDB()
 
 	// 记录操作日志
 	logger.LogOrderOperation(db, c, "refund", order.ID, map[string]interface{}{
@@ -1051,6 +1076,9 @@ func (h *OrderHandler) ConfirmRefund(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 	if order.Status != models.OrderStatusRefundPending {
 		respondAdminOrderValidationError(c, orderbiz.RefundFinalizeStatusInvalid(order.Status))
 		return
@@ -1107,27 +1135,25 @@ func (h *OrderHandler) ConfirmRefund(c *gin.Context) {
 	now := time.Now().UTC()
 	remarkLines := make([]string, 0, 2)
 	if req.TransactionID != "" {
-		remarkLines = append(remarkLines, "[Refund Confirmed] transaction_id="+req.TransactionID)
+		remarkLines = append(remarkLines, "Refund confirmed, transaction_id="+req.TransactionID)
 	} else {
-		remarkLines = append(remarkLines, "[Refund Confirmed]")
+		remarkLines = append(remarkLines, "Refund confirmed")
 	}
 	if req.Remark != "" {
 		remarkLines = append(remarkLines, req.Remark)
 	}
-	nextAdminRemark := order.AdminRemark
-	if len(remarkLines) > 0 {
-		if nextAdminRemark != "" {
-			nextAdminRemark += "\n"
-		}
-		nextAdminRemark += strings.Join(remarkLines, "\n")
-	}
 
 	if err := db.Transaction(func(tx *gorm.DB) error {
-		updates := map[string]interface{}{
-			"status":       models.OrderStatusRefunded,
-			"admin_remark": nextAdminRemark,
+		if err := tx.Model(order).Update("status", models.OrderStatusRefunded).Error; err != nil {
+			return err
+		}
+		if err := service.AddOrderNoteTx(tx, order.ID, &adminID, models.OrderNoteSourceRefundConfirm, strings.Join(remarkLines, "\n")); err != nil {
+			return err
+		}
+		if err := service.RecordOrderRefundLedgerTx(tx, order, "confirm_refund", req.TransactionID, &adminID); err != nil {
+			return err
 		}
-		if err := tx.Model(order).Updates(updates).Error; err != nil {
+		if err := service.CompletePendingOrderRefundsTx(tx, order.ID, req.TransactionID); err != nil {
 			return err
 		}
 
@@ -1238,8 +1264,11 @@ func (h *OrderHandler) MarkAsPaid(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 	beforeStatus := order.Status
-	options := service.MarkAsPaidOptions{}
+	options := service.MarkAsPaidOptions{OperatorID: &adminID}
 	hookExecCtx := h.buildOrderHookExecutionContext(c, adminID, uint(orderID))
 	if h.pluginManager != nil {
 		originalOptions := options
@@ -1349,6 +1378,9 @@ func (h *OrderHandler) DeliverVirtualStock(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 	beforeStatus := order.Status
 	hookExecCtx := h.buildOrderHookExecutionContext(c, adminID, uint(orderID))
 	if h.pluginManager != nil {
@@ -1454,6 +1486,9 @@ func (h *OrderHandler) DeleteOrder(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 	beforeStatus := order.Status
 	hookExecCtx := h.buildOrderHookExecutionContext(c, adminID, uint(orderID))
 	if h.pluginManager != nil {
@@ -1558,6 +1593,9 @@ func (h *OrderHandler) UpdateShippingInfo(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 	beforeStatus := order.Status
 	beforeShipping := map[string]interface{}{
 		"receiver_name":     order.ReceiverName,
@@ -1703,6 +1741,44 @@ func (h *OrderHandler) UpdateShippingInfo(c *gin.Context) {
 		return
 	}
 
+	// 按更新后的国家/区号校验号码与邮编；仅在相关字段有改动时校验，避免历史数据阻塞其他字段的修改
+	if req.ReceiverCountry != "" || req.PhoneCode != "" || req.ReceiverPhone != "" || req.ReceiverPostcode != "" {
+		country, phoneCode, phone, postcode := order.ReceiverCountry, order.PhoneCode, order.ReceiverPhone, order.ReceiverPostcode
+		if req.ReceiverCountry != "" {
+			country = req.ReceiverCountry
+		}
+		if req.PhoneCode != "" {
+			phoneCode = req.PhoneCode
+		}
+		if req.ReceiverPhone != "" {
+			phone = req.ReceiverPhone
+		}
+		if req.ReceiverPostcode != "" {
+			postcode = req.ReceiverPostcode
+		}
+		if bizErr := orderbiz.ValidateReceiverRegion(country, phoneCode, phone, postcode); bizErr != nil {
+			respondAdminOrderValidationError(c, bizErr)
+			return
+		}
+	}
+	if req.ReceiverCountry != "" || req.ReceiverProvince != "" || req.ReceiverCity != "" {
+		country, province, city := order.ReceiverCountry, order.ReceiverProvince, order.ReceiverCity
+		if req.ReceiverCountry != "" {
+			country = req.ReceiverCountry
+		}
+		if req.ReceiverProvince != "" {
+			province = req.ReceiverProvince
+		}
+		if req.ReceiverCity != "" {
+			city = req.ReceiverCity
+		}
+		// 后台可为停运国家的订单修改地址，仅校验省市是否存在
+		if err := h.regionService.ValidateShippingRegion(country, province, city, false); err != nil {
+			respondAdminOrderValidationError(c, err)
+			return
+		}
+	}
+
 	// Update收货Info
 	if req.ReceiverName != "" {
 		order.ReceiverName = req.ReceiverName
@@ -1808,6 +1884,9 @@ func (h *OrderHandler) RequestResubmit(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 
 	// 只允许待发货状态的Order要求重填
 	if order.Status != models.OrderStatusPending {
@@ -1835,13 +1914,14 @@ func (h *OrderHandler) RequestResubmit(c *gin.Context) {
 	})
 
 	response.Success(c, gin.H{
-		"order_no":        updatedOrder.OrderNo,
-		"status":          models.OrderStatusNeedResubmit,
-		"new_form_token":  newToken,
-		"new_form_url":    h.buildShippingFormURL(updatedOrder.FormToken),
-		"form_expires_at": updatedOrder.FormExpiresAt,
-		"reason":          req.Reason,
-		"message":         "User has been asked to resubmit shipping info",
+		"order_no":           updatedOrder.OrderNo,
+		"status":             models.OrderStatusNeedResubmit,
+		"new_form_token":     newToken,
+		"new_form_url":       h.buildShippingFormURL(updatedOrder.FormToken),
+		"new_form_short_url": h.buildShippingFormShortURL(c, updatedOrder),
+		"form_expires_at":    updatedOrder.FormExpiresAt,
+		"reason":             req.Reason,
+		"message":            "User has been asked to resubmit shipping info",
 	})
 }
 
@@ -1853,11 +1933,21 @@ func (h *OrderHandler) CompleteAllShippedOrders(c *gin.Context) {
 		return
 	}
 
+	allowedStoreIDs, err := loadAdminStoreIDs(c)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+
 	db := database.GetDB()
 
-	// 查询所有已发货订单
+	// 查询所有已发货订单（绑定店铺的管理员只处理所绑定店铺的订单）
+	query := db.Where("status = ?", models.OrderStatusShipped)
+	if len(allowedStoreIDs) > 0 {
+		query = query.Where("store_id IN ?", allowedStoreIDs)
+	}
 	var orders []models.Order
-	if err := db.Where("status = ?", models.OrderStatusShipped).Find(&orders).Error; err != nil {
+	if err := query.Find(&orders).Error; err != nil {
 		response.InternalError(c, "Failed to query shipped orders")
 		return
 	}
@@ -2010,9 +2100,15 @@ func (h *OrderHandler) BatchUpdateOrders(c *gin.Context) {
 	failedCount := 0
 	var failedOrders []string
 
+	allowedStoreIDs, err := loadAdminStoreIDs(c)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+
 	for _, orderID := range req.OrderIDs {
 		order, getErr := h.orderService.GetOrderByID(orderID)
-		if getErr != nil || order == nil {
+		if getErr != nil || order == nil || !adminStoreAllowed(allowedStoreIDs, order.StoreID) {
 			failedCount++
 			failedOrders = append(failedOrders, strconv.FormatUint(uint64(orderID), 10))
 			continue
@@ -2265,6 +2361,9 @@ func (h *OrderHandler) UpdateOrderPrice(c *gin.Context) {
 		response.NotFound(c, "Order not found")
 		return
 	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
 
 	// 只允许修改待付款状态的订单价格
 	if order.Status != models.OrderStatusPendingPayment {
@@ -2314,12 +2413,33 @@ func (h *OrderHandler) UpdateOrderPrice(c *gin.Context) {
 		respondAdminOrderValidationError(c, orderbiz.TotalAmountNegative())
 		return
 	}
+	var approval *adminApprovalGrant
+	if service.PriceChangeRequiresApproval(order.TotalAmount, *req.TotalAmountMinor) {
+		grant, ok := requireAdminApproval(c, service.AdminApprovalRequest{
+			ActionType:   models.AdminApprovalActionOrderPriceUpdate,
+			ResourceType: "order",
+			ResourceID:   strconv.FormatUint(uint64(order.ID), 10),
+			Summary:      fmt.Sprintf("Change order %s price from %s to %s %s", order.OrderNo, money.MinorToString(order.TotalAmount), money.MinorToString(*req.TotalAmountMinor), order.Currency),
+			Payload: map[string]interface{}{
+				"order_no":               order.OrderNo,
+				"old_total_amount_minor": order.TotalAmount,
+				"new_total_amount_minor": *req.TotalAmountMinor,
+			},
+			RequestedBy: adminID,
+		})
+		if !ok {
+			return
+		}
+		approval = grant
+		defer approval.release()
+	}
 
 	// 保存修改前的价格
 	oldAmount := order.TotalAmount
 
 	// 更新订单价格
 	order.TotalAmount = *req.TotalAmountMinor
+	service.ReallocateOrderItemTotals(order)
 
 	db := database.GetDB()
 	paymentArtifactsReset := false
@@ -2327,6 +2447,9 @@ func (h *OrderHandler) UpdateOrderPrice(c *gin.Context) {
 		if err := tx.Save(order).Error; err != nil {
 			return err
 		}
+		if err := service.RecordOrderAdjustmentLedgerTx(tx, order, oldAmount, order.TotalAmount, "admin_api", &adminID); err != nil {
+			return err
+		}
 
 		var opm models.OrderPaymentMethod
 		if err := tx.Where("order_id = ?", order.ID).First(&opm).Error; err != nil {
@@ -2365,6 +2488,7 @@ func (h *OrderHandler) UpdateOrderPrice(c *gin.Context) {
 		response.InternalError(c, "Failed to update order price")
 		return
 	}
+	approval.complete()
 
 	// 记录操作日志
 	logger.LogOrderOperation(db, c, "update_price", order.ID, map[string]interface{}{
@@ -2413,6 +2537,7 @@ type CreateDraftRequest struct {
 	ExternalOrderID string             `json:"external_order_id"`
 	Platform        string             `json:"platform"`
 	Remark          string             `json:"remark"`
+	Tags            []string           `json:"tags"`
 }
 
 // CreateDraft 创建订单草稿
@@ -2459,7 +2584,8 @@ func (h *OrderHandler) CreateDraft(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.CreateDraft(
+	order, duplicate, err := h.orderService.CreateDraft(
+		c.GetUint("api_key_id"),
 		req.Items,
 		req.ExternalUserID,
 		req.ExternalOrderID,
@@ -2467,6 +2593,7 @@ func (h *OrderHandler) CreateDraft(c *gin.Context) {
 		req.UserEmail,
 		req.UserName,
 		req.Remark,
+		req.Tags,
 	)
 	if err != nil {
 		var bizErr *bizerr.Error
@@ -2486,22 +2613,35 @@ func (h *OrderHandler) CreateDraft(c *gin.Context) {
 	}
 
 	db := database.GetDB()
-	logger.LogOrderOperation(db, c, "create_draft", order.ID, map[string]interface{}{
-		"order_no":          order.OrderNo,
-		"external_user_id":  req.ExternalUserID,
-		"external_order_id": req.ExternalOrderID,
-		"platform":          req.Platform,
-		"items_count":       len(req.Items),
-	})
+	if duplicate {
+		// 同一外部订单重复提交，返回已存在的订单，不重复建单
+		logger.LogOrderOperation(db, c, "create_draft_duplicate", order.ID, map[string]interface{}{
+			"order_no":          order.OrderNo,
+			"external_user_id":  req.ExternalUserID,
+			"external_order_id": req.ExternalOrderID,
+			"platform":          req.Platform,
+		})
+	} else {
+		logger.LogOrderOperation(db, c, "create_draft", order.ID, map[string]interface{}{
+			"order_no":          order.OrderNo,
+			"external_user_id":  req.ExternalUserID,
+			"external_order_id": req.ExternalOrderID,
+			"platform":          req.Platform,
+			"items_count":       len(req.Items),
+			"tags":              order.Tags,
+		})
+	}
 
 	response.Success(c, gin.H{
-		"order_id":   order.ID,
-		"order_no":   order.OrderNo,
-		"form_url":   h.buildShippingFormURL(order.FormToken),
-		"form_token": order.FormToken,
-		"status":     order.Status,
-		"expires_at": order.FormExpiresAt,
-		"created_at": order.CreatedAt,
+		"order_id":       order.ID,
+		"order_no":       order.OrderNo,
+		"form_url":       h.buildShippingFormURL(order.FormToken),
+		"form_short_url": h.buildShippingFormShortURL(c, order),
+		"form_token":     order.FormToken,
+		"status":         order.Status,
+		"expires_at":     order.FormExpiresAt,
+		"created_at":     order.CreatedAt,
+		"duplicate":      duplicate,
 	})
 }
 
@@ -2524,6 +2664,7 @@ type CreateOrderForUserRequest struct {
 	Status           string                   `json:"status"`
 	TotalAmountMinor *int64                   `json:"total_amount_minor"`
 	UserEmail        string                   `json:"user_email"`
+	Tags             []string                 `json:"tags"`
 }
 
 // CreateOrderForUser 管理员为用户创建订单
@@ -2556,6 +2697,23 @@ func (h *OrderHandler) CreateOrderForUser(c *gin.Context) {
 		return
 	}
 
+	var createdBy *uint
+	if adminID, ok := middleware.GetUserID(c); ok {
+		createdBy = &adminID
+	}
+	if h.shippingRestrictions != nil {
+		items := make([]models.OrderItem, 0, len(req.Items))
+		for _, item := range req.Items {
+			items = append(items, models.OrderItem{SKU: item.SKU})
+		}
+		if err := h.shippingRestrictions.Enforce(items, req.ReceiverCountry, service.ShippingRestrictionAttempt{
+			Source: service.ShippingRestrictionSourceAdmin,
+			UserID: req.UserID,
+		}); err != nil {
+			respondAdminOrderValidationError(c, err)
+			return
+		}
+	}
 	order, err := h.orderService.CreateAdminOrder(service.AdminOrderRequest{
 		UserID:           req.UserID,
 		Items:            req.Items,
@@ -2574,6 +2732,8 @@ func (h *OrderHandler) CreateOrderForUser(c *gin.Context) {
 		Status:           req.Status,
 		TotalAmount:      req.TotalAmountMinor,
 		UserEmail:        req.UserEmail,
+		CreatedBy:        createdBy,
+		Tags:             req.Tags,
 	})
 	if err != nil {
 		var bizErr *bizerr.Error
@@ -2602,6 +2762,7 @@ func (h *OrderHandler) CreateOrderForUser(c *gin.Context) {
 		"order_id":        order.ID,
 		"order_no":        order.OrderNo,
 		"form_url":        h.buildShippingFormURL(order.FormToken),
+		"form_short_url":  h.buildShippingFormShortURL(c, order),
 		"form_token":      order.FormToken,
 		"form_expires_at": order.FormExpiresAt,
 		"status":          order.Status,
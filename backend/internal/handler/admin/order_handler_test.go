@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -31,9 +32,13 @@ func newOrderHandlerTestDeps(t *testing.T) (*OrderHandler, *gorm.DB) {
 		&models.User{},
 		&models.AdminPermission{},
 		&models.Order{},
+		&models.OrderNote{},
 		&models.OrderPaymentMethod{},
 		&models.PaymentMethodStorageEntry{},
 		&models.PaymentMethod{},
+		&models.LedgerEntry{},
+		&models.OrderRefund{},
+		&models.StoreAdmin{},
 	); err != nil {
 		t.Fatalf("auto migrate: %v", err)
 	}
@@ -62,6 +67,7 @@ func newOrderHandlerTestDeps(t *testing.T) (*OrderHandler, *gorm.DB) {
 
 	jsRuntimeService := service.NewJSRuntimeService(db, cfg)
 	handler := NewOrderHandler(orderService, nil, nil, jsRuntimeService, nil, cfg)
+	handler.SetRefundService(service.NewOrderRefundService(db, orderService, jsRuntimeService))
 	return handler, db
 }
 
@@ -104,6 +110,61 @@ func TestGetOrderInvalidOrderIDReturnsBizError(t *testing.T) {
 	}
 }
 
+func TestGetOrderLoadsPaymentInfoInSingleQuery(t *testing.T) {
+	handler, db := newOrderHandlerTestDeps(t)
+	order := createOrderForHandlerTest(t, db, models.OrderStatusPending)
+
+	method := models.PaymentMethod{Name: "Bank Transfer", Icon: "landmark", Type: models.PaymentMethodTypeBuiltin, Script: "function pay() {}"}
+	if err := db.Create(&method).Error; err != nil {
+		t.Fatalf("create payment method: %v", err)
+	}
+	if err := db.Create(&models.OrderPaymentMethod{OrderID: order.ID, PaymentMethodID: method.ID}).Error; err != nil {
+		t.Fatalf("create order payment method: %v", err)
+	}
+
+	paymentQueries := 0
+	_ = db.Callback().Query().After("gorm:query").Register("test:count_payment_queries", func(tx *gorm.DB) {
+		if strings.Contains(tx.Statement.SQL.String(), "payment_methods") {
+			paymentQueries++
+		}
+	})
+
+	resp := performAdminUserRequest(
+		t,
+		handler.GetOrder,
+		http.MethodGet,
+		fmt.Sprintf("/admin/orders/%d", order.ID),
+		gin.Params{{Key: "id", Value: fmt.Sprintf("%d", order.ID)}},
+		nil,
+		1,
+	)
+
+	if resp.Code != response.CodeSuccess {
+		t.Fatalf("expected success, got %d: %s", resp.Code, resp.Message)
+	}
+	if paymentQueries != 1 {
+		t.Fatalf("expected payment info loaded in 1 query, got %d", paymentQueries)
+	}
+
+	var payload struct {
+		PaymentInfo struct {
+			PaymentMethod struct {
+				ID   uint   `json:"id"`
+				Name string `json:"name"`
+				Icon string `json:"icon"`
+			} `json:"payment_method"`
+		} `json:"payment_info"`
+	}
+	raw, _ := json.Marshal(resp.Data)
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	pm := payload.PaymentInfo.PaymentMethod
+	if pm.ID != method.ID || pm.Name != "Bank Transfer" || pm.Icon != "landmark" {
+		t.Fatalf("unexpected payment method: %+v", pm)
+	}
+}
+
 func TestUpdateOrderPriceInvalidRequestReturnsBizError(t *testing.T) {
 	handler, db := newOrderHandlerTestDeps(t)
 	order := createOrderForHandlerTest(t, db, models.OrderStatusPendingPayment)
@@ -412,8 +473,12 @@ func TestConfirmRefundMarksOrderRefunded(t *testing.T) {
 	if updatedOrder.Status != models.OrderStatusRefunded {
 		t.Fatalf("expected status %q, got %q", models.OrderStatusRefunded, updatedOrder.Status)
 	}
-	if !strings.Contains(updatedOrder.AdminRemark, "REF-2026-0001") {
-		t.Fatalf("expected admin remark to contain refund transaction id, got %q", updatedOrder.AdminRemark)
+	var note models.OrderNote
+	if err := db.Where("order_id = ? AND source = ?", order.ID, models.OrderNoteSourceRefundConfirm).First(&note).Error; err != nil {
+		t.Fatalf("query refund note: %v", err)
+	}
+	if !strings.Contains(note.Content, "REF-2026-0001") || note.AuthorID == nil || *note.AuthorID != 1 {
+		t.Fatalf("expected refund note with transaction id by admin 1, got %+v", note)
 	}
 
 	var updatedOPM models.OrderPaymentMethod
@@ -477,3 +542,200 @@ func TestBatchUpdateOrdersLimitReturnsBizError(t *testing.T) {
 		t.Fatalf("expected order.batchLimitExceeded, got %q", key)
 	}
 }
+
+func TestStoreBoundAdminCannotMutateOtherStoreOrder(t *testing.T) {
+	handler, db := newOrderHandlerTestDeps(t)
+	if err := db.Create(&models.StoreAdmin{StoreID: 1, UserID: 1}).Error; err != nil {
+		t.Fatalf("bind admin store: %v", err)
+	}
+
+	otherStoreID := uint(2)
+	order := createOrderForHandlerTest(t, db, models.OrderStatusPendingPayment)
+	if err := db.Model(&order).Update("store_id", otherStoreID).Error; err != nil {
+		t.Fatalf("set order store: %v", err)
+	}
+	params := gin.Params{{Key: "id", Value: fmt.Sprintf("%d", order.ID)}}
+
+	cases := []struct {
+		name    string
+		handler gin.HandlerFunc
+		method  string
+		body    any
+	}{
+		{"complete", handler.CompleteOrder, http.MethodPost, nil},
+		{"cancel", handler.CancelOrder, http.MethodPost, nil},
+		{"mark_paid", handler.MarkAsPaid, http.MethodPost, nil},
+		{"delete", handler.DeleteOrder, http.MethodDelete, nil},
+		{"shipping_info", handler.UpdateShippingInfo, http.MethodPut, map[string]any{"receiver_name": "Mallory"}},
+		{"price", handler.UpdateOrderPrice, http.MethodPut, map[string]any{"total_amount_minor": 1}},
+		{"assign_tracking", handler.AssignTracking, http.MethodPost, map[string]any{"tracking_no": "SF123"}},
+	}
+	for _, tc := range cases {
+		resp := performAdminUserRequest(t, tc.handler, tc.method, fmt.Sprintf("/admin/orders/%d", order.ID), params, tc.body, 1)
+		if resp.Code != response.CodeForbidden {
+			t.Fatalf("%s: expected forbidden, got code=%d message=%s", tc.name, resp.Code, resp.Message)
+		}
+	}
+
+	var reloaded models.Order
+	if err := db.First(&reloaded, order.ID).Error; err != nil {
+		t.Fatalf("reload order: %v", err)
+	}
+	if reloaded.Status != models.OrderStatusPendingPayment || reloaded.TotalAmount != 1000 || reloaded.TrackingNo != "" {
+		t.Fatalf("expected other store order untouched, got %+v", reloaded)
+	}
+}
+
+func TestStoreBoundAdminCannotDeleteOtherStoreProduct(t *testing.T) {
+	_, db := newOrderHandlerTestDeps(t)
+	if err := db.AutoMigrate(&models.Product{}, &models.Inventory{}, &models.ProductInventoryBinding{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	if err := db.Create(&models.StoreAdmin{StoreID: 1, UserID: 1}).Error; err != nil {
+		t.Fatalf("bind admin store: %v", err)
+	}
+	otherStoreID := uint(2)
+	product := models.Product{SKU: "OTHER-1", Name: "Other store product", StoreID: &otherStoreID}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	productHandler := NewProductHandler(
+		service.NewProductService(repository.NewProductRepository(db), repository.NewInventoryRepository(db)),
+		nil,
+		nil,
+	)
+	params := gin.Params{{Key: "id", Value: fmt.Sprintf("%d", product.ID)}}
+	cases := []struct {
+		name    string
+		handler gin.HandlerFunc
+		method  string
+		body    any
+	}{
+		{"delete", productHandler.DeleteProduct, http.MethodDelete, nil},
+		{"status", productHandler.UpdateProductStatus, http.MethodPut, map[string]any{"status": "inactive"}},
+		{"stock", productHandler.UpdateStock, http.MethodPut, map[string]any{"stock": 0}},
+		{"featured", productHandler.ToggleFeatured, http.MethodPost, nil},
+	}
+	for _, tc := range cases {
+		resp := performAdminUserRequest(t, tc.handler, tc.method, fmt.Sprintf("/admin/products/%d", product.ID), params, tc.body, 1)
+		if resp.Code != response.CodeForbidden {
+			t.Fatalf("%s: expected forbidden, got code=%d message=%s", tc.name, resp.Code, resp.Message)
+		}
+	}
+
+	var count int64
+	db.Model(&models.Product{}).Where("id = ?", product.ID).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected other store product kept, got count=%d", count)
+	}
+}
+
+func TestStoreBoundAdminPaymentMatchScopedToOwnStores(t *testing.T) {
+	handler, db := newOrderHandlerTestDeps(t)
+	if err := db.AutoMigrate(&models.IncomingTransaction{}, &models.PaymentAmountReservation{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	if err := db.Create(&models.StoreAdmin{StoreID: 1, UserID: 1}).Error; err != nil {
+		t.Fatalf("bind admin store: %v", err)
+	}
+	ownStoreID, otherStoreID := uint(1), uint(2)
+	own := createOrderForHandlerTest(t, db, models.OrderStatusPendingPayment)
+	if err := db.Model(&own).Updates(map[string]any{"store_id": ownStoreID, "order_no": "ORD-OWN-STORE"}).Error; err != nil {
+		t.Fatalf("set own order store: %v", err)
+	}
+	other := createOrderForHandlerTest(t, db, models.OrderStatusPendingPayment)
+	if err := db.Model(&other).Update("store_id", otherStoreID).Error; err != nil {
+		t.Fatalf("set other order store: %v", err)
+	}
+
+	matchHandler := NewPaymentMatchHandler(db, service.NewPaymentMatchService(db, handler.orderService))
+	txn, _, err := matchHandler.matchService.RecordTransaction(service.IncomingTransactionInput{
+		TransactionID: "BANK-STORE-1",
+		Amount:        "10",
+		Currency:      "CNY",
+	}, models.IncomingTransactionSourceManual, nil)
+	if err != nil {
+		t.Fatalf("record transaction: %v", err)
+	}
+
+	resp := performAdminUserRequest(t, matchHandler.ListPendingOrders, http.MethodGet, "/admin/payment-matching/pending-orders", nil, nil, 1)
+	if resp.Code != response.CodeSuccess {
+		t.Fatalf("list pending orders: code=%d message=%s", resp.Code, resp.Message)
+	}
+	listed, _ := json.Marshal(resp.Data)
+	if !strings.Contains(string(listed), "ORD-OWN-STORE") || strings.Contains(string(listed), other.OrderNo) {
+		t.Fatalf("expected only own store pending orders, got %s", listed)
+	}
+
+	params := gin.Params{{Key: "id", Value: fmt.Sprintf("%d", txn.ID)}}
+	resp = performAdminUserRequest(t, matchHandler.GetSuggestions, http.MethodGet, "/admin/payment-matching/transactions/1/suggestions", params, nil, 1)
+	suggested, _ := json.Marshal(resp.Data)
+	if resp.Code != response.CodeSuccess || strings.Contains(string(suggested), other.OrderNo) {
+		t.Fatalf("expected suggestions limited to own store, got code=%d data=%s", resp.Code, suggested)
+	}
+
+	resp = performAdminUserRequest(t, matchHandler.MatchTransaction, http.MethodPost, "/admin/payment-matching/transactions/1/match", params, map[string]any{"order_id": other.ID}, 1)
+	if resp.Code != response.CodeForbidden {
+		t.Fatalf("expected forbidden match, got code=%d message=%s", resp.Code, resp.Message)
+	}
+	var reloaded models.Order
+	db.First(&reloaded, other.ID)
+	if reloaded.Status != models.OrderStatusPendingPayment {
+		t.Fatalf("expected other store order unpaid, got %s", reloaded.Status)
+	}
+}
+
+func TestStoreBoundAdminCODScopedToOwnStores(t *testing.T) {
+	_, db := newOrderHandlerTestDeps(t)
+	if err := db.AutoMigrate(&models.CODCollection{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	if err := db.Create(&models.StoreAdmin{StoreID: 1, UserID: 1}).Error; err != nil {
+		t.Fatalf("bind admin store: %v", err)
+	}
+	otherStoreID := uint(2)
+	order := createOrderForHandlerTest(t, db, models.OrderStatusShipped)
+	if err := db.Model(&order).Update("store_id", otherStoreID).Error; err != nil {
+		t.Fatalf("set order store: %v", err)
+	}
+	collection := models.CODCollection{
+		OrderID:         order.ID,
+		OrderNo:         order.OrderNo,
+		Currency:        "CNY",
+		AmountDue:       1000,
+		Status:          models.CODCollectionStatusCollected,
+		Carrier:         "SF",
+		CollectedAmount: 1000,
+	}
+	if err := db.Create(&collection).Error; err != nil {
+		t.Fatalf("create collection: %v", err)
+	}
+
+	codHandler := NewCODHandler(db, service.NewCODService(db))
+	resp := performAdminUserRequest(t, codHandler.ListCollections, http.MethodGet, "/admin/cod/collections", nil, nil, 1)
+	listed, _ := json.Marshal(resp.Data)
+	if resp.Code != response.CodeSuccess || strings.Contains(string(listed), order.OrderNo) {
+		t.Fatalf("expected other store collections hidden, got code=%d data=%s", resp.Code, listed)
+	}
+	resp = performAdminUserRequest(t, codHandler.Report, http.MethodGet, "/admin/cod/report", nil, nil, 1)
+	reported, _ := json.Marshal(resp.Data)
+	if resp.Code != response.CodeSuccess || strings.Contains(string(reported), "SF") {
+		t.Fatalf("expected other store collections excluded from report, got code=%d data=%s", resp.Code, reported)
+	}
+
+	params := gin.Params{{Key: "id", Value: fmt.Sprintf("%d", order.ID)}}
+	resp = performAdminUserRequest(t, codHandler.RecordCollection, http.MethodPost, fmt.Sprintf("/admin/orders/%d/cod-collection", order.ID), params, map[string]any{"carrier": "SF", "collected_amount": 1000}, 1)
+	if resp.Code != response.CodeForbidden {
+		t.Fatalf("expected forbidden record, got code=%d message=%s", resp.Code, resp.Message)
+	}
+	resp = performAdminUserRequest(t, codHandler.SettleCollections, http.MethodPost, "/admin/cod/settle", nil, map[string]any{"ids": []uint{collection.ID}}, 1)
+	if resp.Code != response.CodeForbidden {
+		t.Fatalf("expected forbidden settle, got code=%d message=%s", resp.Code, resp.Message)
+	}
+	var reloaded models.CODCollection
+	db.First(&reloaded, collection.ID)
+	if reloaded.Status != models.CODCollectionStatusCollected {
+		t.Fatalf("expected other store collection unsettled, got %s", reloaded.Status)
+	}
+}
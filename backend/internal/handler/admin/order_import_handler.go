@@ -0,0 +1,141 @@
+package admin
+
+import (
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OrderImportHandler 批量导入订单（平台迁移）
+type OrderImportHandler struct {
+	db            *gorm.DB
+	importService *service.OrderImportService
+}
+
+func NewOrderImportHandler(db *gorm.DB, importService *service.OrderImportService) *OrderImportHandler {
+	return &OrderImportHandler{db: db, importService: importService}
+}
+
+func (h *OrderImportHandler) available(c *gin.Context) bool {
+	if h == nil || h.importService == nil {
+		response.InternalError(c, "Order import service is unavailable")
+		return false
+	}
+	return true
+}
+
+// CreateImportJob 上传 CSV/XLSX 并创建后台导入任务
+func (h *OrderImportHandler) CreateImportJob(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "Please select an import file to upload")
+		return
+	}
+	_, tableRows, err := readAdminTabularRows(file)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "unsupported format") {
+			response.BadRequest(c, "Only .csv or .xlsx files are supported")
+			return
+		}
+		response.BadRequest(c, "Failed to parse import file")
+		return
+	}
+
+	notifyCustomers := parseOrderImportBool(c.PostForm("notify_customers"))
+	operatorID := contextUserID(c)
+	operatorName := h.resolveOperatorName(operatorID)
+
+	job, err := h.importService.CreateJob(file.Filename, tableRows, notifyCustomers, operatorID, operatorName)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to create import job")
+		return
+	}
+
+	logger.LogOperation(h.db, c, "import", "order", &job.ID, map[string]interface{}{
+		"job_no":           job.JobNo,
+		"filename":         job.Filename,
+		"total_rows":       job.TotalRows,
+		"notify_customers": job.NotifyCustomers,
+	})
+	response.Success(c, job)
+}
+
+// ListImportJobs 导入任务列表
+func (h *OrderImportHandler) ListImportJobs(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	page, limit := response.GetPagination(c)
+	jobs, total, err := h.importService.ListJobs(page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, jobs, page, limit, total)
+}
+
+// GetImportJob 导入任务详情（含行级错误报告）
+func (h *OrderImportHandler) GetImportJob(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	jobID, err := parseUintParam(c.Param("jobId"))
+	if err != nil || jobID == 0 {
+		response.BadRequest(c, "Invalid job id")
+		return
+	}
+	job, err := h.importService.GetJob(jobID)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, job)
+}
+
+// DownloadImportTemplate 下载批量导入模板（含一行示例）
+func (h *OrderImportHandler) DownloadImportTemplate(c *gin.Context) {
+	writeXLSXAttachment(c, "order_bulk_import_template.xlsx", "Orders", service.OrderImportTemplateHeaders, [][]string{
+		{
+			"LEGACY-1001", "customer@example.com", "Alice", "+1", "5550100", "customer@example.com",
+			"US", "CA", "San Francisco", "", "1 Market St", "94105", "", "shipped", "1Z999AA10123456784",
+			"SKU-001", "Sample Product", "2", "1999", `{"color":"red"}`, "physical", "",
+		},
+	})
+}
+
+func (h *OrderImportHandler) resolveOperatorName(operatorID *uint) string {
+	if operatorID == nil || h.db == nil {
+		return ""
+	}
+	var operator models.User
+	if err := h.db.Select("id", "name", "email").First(&operator, *operatorID).Error; err != nil {
+		return ""
+	}
+	if name := strings.TrimSpace(operator.Name); name != "" {
+		return name
+	}
+	return strings.TrimSpace(operator.Email)
+}
+
+func parseOrderImportBool(raw string) bool {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GetOrderFinancialSummary 订单财务汇总与账本分录
+func (h *OrderHandler) GetOrderFinancialSummary(c *gin.Context) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	if h.ledgerService == nil {
+		response.InternalError(c, "Ledger service unavailable")
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	summary, err := h.ledgerService.OrderSummary(order)
+	if err != nil {
+		response.InternalServerError(c, "Failed to build financial summary", err)
+		return
+	}
+	response.Success(c, summary)
+}
+
+// CreateOrderCredit 记录对用户的补偿额度（仅追加账本分录，不改变订单金额）
+func (h *OrderHandler) CreateOrderCredit(c *gin.Context) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		AmountMinor int64  `json:"amount_minor"`
+		Reason      string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if h.ledgerService == nil {
+		response.InternalError(c, "Ledger service unavailable")
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	var createdBy *uint
+	if adminID, ok := middleware.GetUserID(c); ok && adminID != 0 {
+		createdBy = &adminID
+	}
+	if err := h.ledgerService.RecordCredit(order, req.AmountMinor, req.Reason, createdBy); err != nil {
+		respondAdminOrderServiceError(c, err, "Failed to record credit")
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "ledger_credit", order.ID, map[string]interface{}{
+		"order_no":     order.OrderNo,
+		"amount_minor": req.AmountMinor,
+		"reason":       req.Reason,
+	})
+	summary, err := h.ledgerService.OrderSummary(order)
+	if err != nil {
+		response.InternalServerError(c, "Failed to build financial summary", err)
+		return
+	}
+	response.Success(c, summary)
+}
+
+// VerifyLedger 账本完整性校验
+func (h *OrderHandler) VerifyLedger(c *gin.Context) {
+	if h.ledgerService == nil {
+		response.InternalError(c, "Ledger service unavailable")
+		return
+	}
+	report, err := h.ledgerService.VerifyIntegrity()
+	if err != nil {
+		response.InternalServerError(c, "Failed to verify ledger", err)
+		return
+	}
+	response.Success(c, report)
+}
@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// MergeOrdersRequest 合并发货请求，order_ids 为并入当前订单的其他订单
+type MergeOrdersRequest struct {
+	OrderIDs []uint `json:"order_ids" binding:"required,min=1"`
+}
+
+// ListOrderMergeCandidates 可并入该订单的同一买家、同一收货人的待发货订单
+func (h *OrderHandler) ListOrderMergeCandidates(c *gin.Context) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	candidates, err := h.orderService.ListMergeCandidates(orderID)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to load merge candidates")
+		return
+	}
+	response.Success(c, gin.H{"items": candidates})
+}
+
+// MergeOrders 把其他待发货订单并入该订单一起发货，被合并订单随之关闭
+func (h *OrderHandler) MergeOrders(c *gin.Context) {
+	adminID, adminIDOK := middleware.RequireUserID(c)
+	if !adminIDOK {
+		return
+	}
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	var req MergeOrdersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	merged, refs, err := h.orderService.MergeOrders(orderID, service.OrderMergeInput{
+		SourceOrderIDs: req.OrderIDs,
+		OperatorID:     &adminID,
+	})
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to merge orders")
+		return
+	}
+
+	db := database.GetDB()
+	mergedOrderNos := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		mergedOrderNos = append(mergedOrderNos, ref.OrderNo)
+		logger.LogOrderOperation(db, c, "merged_into", ref.ID, map[string]interface{}{
+			"order_no":             ref.OrderNo,
+			"merged_into_order_id": merged.ID,
+			"merged_into_order_no": merged.OrderNo,
+		})
+	}
+	logger.LogOrderOperation(db, c, "merge_orders", merged.ID, map[string]interface{}{
+		"order_no":         merged.OrderNo,
+		"merged_order_nos": mergedOrderNos,
+		"total_amount":     merged.TotalAmount,
+	})
+	response.Success(c, gin.H{
+		"order":         merged,
+		"merged_orders": refs,
+	})
+}
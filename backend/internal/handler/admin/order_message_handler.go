@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SetMessageService 设置订单留言服务
+func (h *OrderHandler) SetMessageService(messageService *service.OrderMessageService) {
+	h.messageService = messageService
+}
+
+// ReplyOrderMessageRequest 回复订单留言请求
+type ReplyOrderMessageRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// loadOrderForMessages 加载订单并校验店铺访问权限
+func (h *OrderHandler) loadOrderForMessages(c *gin.Context) (*models.Order, bool) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return nil, false
+	}
+	if h.messageService == nil {
+		response.InternalError(c, "Order message service is not available")
+		return nil, false
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return nil, false
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return nil, false
+	}
+	return order, true
+}
+
+// GetOrderMessages 订单留言（同时标记用户留言为已读）
+func (h *OrderHandler) GetOrderMessages(c *gin.Context) {
+	order, ok := h.loadOrderForMessages(c)
+	if !ok {
+		return
+	}
+	thread, err := h.messageService.Thread(order.ID, service.OrderMessageSenderAdmin)
+	if err != nil {
+		response.InternalServerError(c, "Failed to load order messages", err)
+		return
+	}
+	response.Success(c, thread)
+}
+
+// ReplyOrderMessage 回复用户的订单留言
+func (h *OrderHandler) ReplyOrderMessage(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	order, ok := h.loadOrderForMessages(c)
+	if !ok {
+		return
+	}
+	var req ReplyOrderMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	message, err := h.messageService.Send(order, service.OrderMessageSenderAdmin, adminID, req.Content)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to send order message", err)
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "reply_message", order.ID, map[string]interface{}{
+		"order_no":   order.OrderNo,
+		"message_id": message.ID,
+	})
+	response.Success(c, message)
+}
+
+// EscalateOrderMessages 将订单留言升级为工单
+func (h *OrderHandler) EscalateOrderMessages(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	order, ok := h.loadOrderForMessages(c)
+	if !ok {
+		return
+	}
+
+	ticket, err := h.messageService.Escalate(order, service.OrderMessageSenderAdmin, adminID)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to create ticket", err)
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "escalate_messages", order.ID, map[string]interface{}{
+		"order_no":  order.OrderNo,
+		"ticket_id": ticket.ID,
+		"ticket_no": ticket.TicketNo,
+	})
+	response.Success(c, ticket)
+}
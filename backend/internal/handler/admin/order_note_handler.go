@@ -0,0 +1,219 @@
+package admin
+
+import (
+	"strconv"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SetNoteService 设置订单备注服务
+func (h *OrderHandler) SetNoteService(noteService *service.OrderNoteService) {
+	h.noteService = noteService
+}
+
+// CreateOrderNoteRequest 添加订单备注请求
+type CreateOrderNoteRequest struct {
+	Content    string `json:"content" binding:"required"`
+	MentionIDs []uint `json:"mention_ids"`
+}
+
+// UpdateOrderNoteRequest 修改订单备注请求
+type UpdateOrderNoteRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// PinOrderNoteRequest 置顶/取消置顶备注
+type PinOrderNoteRequest struct {
+	Pinned bool `json:"pinned"`
+}
+
+func parseOrderNoteID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("noteId"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid note ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// loadOrderForNotes 加载订单并校验店铺访问权限
+func (h *OrderHandler) loadOrderForNotes(c *gin.Context) (*models.Order, bool) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return nil, false
+	}
+	if h.noteService == nil {
+		response.InternalError(c, "Order note service is not available")
+		return nil, false
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return nil, false
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return nil, false
+	}
+	return order, true
+}
+
+func respondOrderNoteError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// ListOrderNotes 订单备注列表（置顶在前）
+func (h *OrderHandler) ListOrderNotes(c *gin.Context) {
+	order, ok := h.loadOrderForNotes(c)
+	if !ok {
+		return
+	}
+	notes, err := h.noteService.List(order.ID)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": notes})
+}
+
+// ListOrderNoteMentionableAdmins 可在订单备注中提及的管理员
+func (h *OrderHandler) ListOrderNoteMentionableAdmins(c *gin.Context) {
+	if h.noteService == nil {
+		response.InternalError(c, "Order note service is not available")
+		return
+	}
+	admins, err := h.noteService.MentionableAdmins()
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": admins})
+}
+
+// CreateOrderNote 添加订单备注，可 @ 提及其他管理员
+func (h *OrderHandler) CreateOrderNote(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	order, ok := h.loadOrderForNotes(c)
+	if !ok {
+		return
+	}
+	var req CreateOrderNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	note, err := h.noteService.Create(order, adminID, req.Content, req.MentionIDs)
+	if err != nil {
+		respondOrderNoteError(c, err, "Failed to add order note")
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "add_note", order.ID, map[string]interface{}{
+		"order_no":    order.OrderNo,
+		"note_id":     note.ID,
+		"mention_ids": note.MentionIDs,
+	})
+	response.Success(c, note)
+}
+
+// UpdateOrderNote 作者修改自己的备注
+func (h *OrderHandler) UpdateOrderNote(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	order, ok := h.loadOrderForNotes(c)
+	if !ok {
+		return
+	}
+	noteID, ok := parseOrderNoteID(c)
+	if !ok {
+		return
+	}
+	var req UpdateOrderNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	note, err := h.noteService.Update(order.ID, noteID, adminID, req.Content)
+	if err != nil {
+		respondOrderNoteError(c, err, "Failed to update order note")
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "update_note", order.ID, map[string]interface{}{
+		"order_no": order.OrderNo,
+		"note_id":  note.ID,
+	})
+	response.Success(c, note)
+}
+
+// DeleteOrderNote 作者删除自己的备注
+func (h *OrderHandler) DeleteOrderNote(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	order, ok := h.loadOrderForNotes(c)
+	if !ok {
+		return
+	}
+	noteID, ok := parseOrderNoteID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.noteService.Delete(order.ID, noteID, adminID); err != nil {
+		respondOrderNoteError(c, err, "Failed to delete order note")
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "delete_note", order.ID, map[string]interface{}{
+		"order_no": order.OrderNo,
+		"note_id":  noteID,
+	})
+	response.Success(c, gin.H{"message": "Order note deleted"})
+}
+
+// PinOrderNote 置顶/取消置顶备注
+func (h *OrderHandler) PinOrderNote(c *gin.Context) {
+	order, ok := h.loadOrderForNotes(c)
+	if !ok {
+		return
+	}
+	noteID, ok := parseOrderNoteID(c)
+	if !ok {
+		return
+	}
+	var req PinOrderNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	note, err := h.noteService.SetPinned(order.ID, noteID, req.Pinned)
+	if err != nil {
+		respondOrderNoteError(c, err, "Failed to pin order note")
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "pin_note", order.ID, map[string]interface{}{
+		"order_no": order.OrderNo,
+		"note_id":  note.ID,
+		"pinned":   note.Pinned,
+	})
+	response.Success(c, note)
+}
@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SetPackagePlanService 设置装箱建议服务
+func (h *OrderHandler) SetPackagePlanService(packagePlanService *service.PackagePlanService) {
+	h.packagePlanService = packagePlanService
+}
+
+// GetPackagePlan 订单重量与装箱建议（按当前商品/规格重量尺寸计算）
+func (h *OrderHandler) GetPackagePlan(c *gin.Context) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	plan, err := h.packagePlanService.Plan(order)
+	if err != nil {
+		response.InternalError(c, "Failed to build package plan")
+		return
+	}
+	response.Success(c, plan)
+}
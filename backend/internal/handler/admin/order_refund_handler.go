@@ -0,0 +1,272 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/money"
+	"auralogic/internal/pkg/orderbiz"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/validator"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateOrderRefundRequest 退款请求；amount_minor 为 0 且未选择商品时退还全部剩余金额，
+// 商品行 amount_minor 为 0 时按数量折算行实付金额
+type CreateOrderRefundRequest struct {
+	AmountMinor int64                    `json:"amount_minor"`
+	Items       []models.OrderRefundItem `json:"items"`
+	Reason      string                   `json:"reason"`
+}
+
+// SetRefundService 设置订单退款服务
+func (h *OrderHandler) SetRefundService(refundService *service.OrderRefundService) {
+	h.refundService = refundService
+}
+
+// executeOrderRefund 审批检查后执行退款；失败时已写出响应并返回 false
+func (h *OrderHandler) executeOrderRefund(c *gin.Context, adminID uint, order *models.Order, input service.OrderRefundInput) (*service.OrderRefundOutcome, bool) {
+	if h.refundService == nil {
+		response.InternalError(c, "Refund service unavailable")
+		return nil, false
+	}
+	quote, err := h.refundService.Quote(order, input)
+	if err != nil {
+		respondAdminOrderServiceError(c, err, "Failed to prepare refund")
+		return nil, false
+	}
+	var approval *adminApprovalGrant
+	if quote.RequiresApproval() {
+		payload := map[string]interface{}{
+			"order_no":           order.OrderNo,
+			"total_amount_minor": order.TotalAmount,
+			"currency":           order.Currency,
+			"reason":             input.Reason,
+		}
+		if quote.CumulativeMinor != quote.AmountMinor {
+			payload["cumulative_refund_minor"] = quote.CumulativeMinor
+		}
+		summary := fmt.Sprintf("Refund order %s (%s %s)", order.OrderNo, money.MinorToString(quote.AmountMinor), order.Currency)
+		if !quote.Full || len(quote.Items) > 0 {
+			payload["refund_amount_minor"] = quote.AmountMinor
+			payload["items"] = quote.Items
+			summary = fmt.Sprintf("Partially refund order %s (%s %s)", order.OrderNo, money.MinorToString(quote.AmountMinor), order.Currency)
+		}
+		grant, ok := requireAdminApproval(c, service.AdminApprovalRequest{
+			ActionType:   models.AdminApprovalActionOrderRefund,
+			ResourceType: "order",
+			ResourceID:   strconv.FormatUint(uint64(order.ID), 10),
+			Summary:      summary,
+			Payload:      payload,
+			RequestedBy:  adminID,
+		})
+		if !ok {
+			return nil, false
+		}
+		approval = grant
+		defer approval.release()
+	}
+
+	outcome, err := h.refundService.CreateRefund(order.ID, input)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return nil, false
+		}
+		response.InternalError(c, "Refund execution failed")
+		return nil, false
+	}
+	if !outcome.Result.Success {
+		msg := "Refund failed"
+		if outcome.Result.Message != "" {
+			msg = outcome.Result.Message
+		}
+		response.BadRequest(c, msg)
+		return nil, false
+	}
+	approval.complete()
+
+	if outcome.StatusAfter != outcome.StatusBefore && order.UserID != nil {
+		if err := h.orderService.SyncUserConsumptionStats(*order.UserID); err != nil {
+			logger.LogOrderOperation(database.GetDB(), c, "sync_user_consumption_stats_failed", order.ID, map[string]interface{}{
+				"order_no": order.OrderNo,
+				"user_id":  *order.UserID,
+				"error":    err.Error(),
+			})
+		}
+	}
+	return outcome, true
+}
+
+// ListOrderRefunds 订单退款记录与可退金额
+func (h *OrderHandler) ListOrderRefunds(c *gin.Context) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	if h.refundService == nil {
+		response.InternalError(c, "Refund service unavailable")
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+	summary, err := h.refundService.Summary(order)
+	if err != nil {
+		response.InternalServerError(c, "Failed to load refunds", err)
+		return
+	}
+	response.Success(c, summary)
+}
+
+// CreateOrderRefund 全额、部分或按商品行退款
+func (h *OrderHandler) CreateOrderRefund(c *gin.Context) {
+	adminID, adminIDOK := middleware.RequireUserID(c)
+	if !adminIDOK {
+		return
+	}
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	var req CreateOrderRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	req.Reason = validator.SanitizeText(req.Reason)
+	if !validator.ValidateLength(req.Reason, 0, 500) {
+		respondAdminOrderValidationError(c, orderbiz.RefundReasonTooLong(500))
+		return
+	}
+
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	outcome, ok := h.executeOrderRefund(c, adminID, order, service.OrderRefundInput{
+		AmountMinor: req.AmountMinor,
+		Items:       req.Items,
+		Reason:      req.Reason,
+		Source:      "admin_refund",
+		AdminID:     &adminID,
+	})
+	if !ok {
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "refund", order.ID, map[string]interface{}{
+		"order_no":          order.OrderNo,
+		"refund_id":         outcome.Refund.ID,
+		"amount_minor":      outcome.Refund.AmountMinor,
+		"items":             outcome.Refund.Items,
+		"full":              outcome.Refund.Full,
+		"reason":            req.Reason,
+		"status_after":      outcome.StatusAfter,
+		"refund_pending":    outcome.Result.Pending,
+		"refund_message":    outcome.Result.Message,
+		"transaction_id":    outcome.Result.TransactionID,
+		"released_reserves": outcome.ReleasedReserves,
+	})
+	if h.pluginManager != nil && outcome.StatusAfter != outcome.StatusBefore {
+		hookExecCtx := h.buildOrderHookExecutionContext(c, adminID, order.ID)
+		service.EmitOrderStatusChangedAfterHookAsync(h.pluginManager, hookExecCtx, order, outcome.StatusBefore, outcome.StatusAfter, map[string]interface{}{
+			"source":          "admin_api",
+			"trigger_action":  "order.admin.refund",
+			"admin_id":        adminID,
+			"reason":          req.Reason,
+			"refund_id":       outcome.Refund.ID,
+			"transaction_id":  outcome.Result.TransactionID,
+			"refund_pending":  outcome.Result.Pending,
+			"payment_message": outcome.Result.Message,
+		})
+	}
+	response.Success(c, outcome)
+}
+
+// ConfirmOrderRefund 人工确认待确认的退款已到账
+func (h *OrderHandler) ConfirmOrderRefund(c *gin.Context) {
+	adminID, adminIDOK := middleware.RequireUserID(c)
+	if !adminIDOK {
+		return
+	}
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	refundID, err := strconv.ParseUint(c.Param("refundId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid refund ID")
+		return
+	}
+	var req ConfirmRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		// 允许不传 body
+	}
+	req.TransactionID = validator.SanitizeInput(req.TransactionID)
+	if !validator.ValidateLength(req.TransactionID, 0, 255) {
+		respondAdminOrderValidationError(c, orderbiz.RefundTransactionIDTooLong(255))
+		return
+	}
+	if h.refundService == nil {
+		response.InternalError(c, "Refund service unavailable")
+		return
+	}
+
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+	outcome, err := h.refundService.ConfirmRefund(orderID, uint(refundID), req.TransactionID, &adminID)
+	if err != nil {
+		respondAdminOrderServiceError(c, err, "Failed to confirm refund")
+		return
+	}
+	if outcome.StatusAfter != outcome.StatusBefore && order.UserID != nil {
+		if err := h.orderService.SyncUserConsumptionStats(*order.UserID); err != nil {
+			logger.LogOrderOperation(database.GetDB(), c, "sync_user_consumption_stats_failed", order.ID, map[string]interface{}{
+				"order_no": order.OrderNo,
+				"user_id":  *order.UserID,
+				"error":    err.Error(),
+			})
+		}
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "confirm_refund", order.ID, map[string]interface{}{
+		"order_no":       order.OrderNo,
+		"refund_id":      outcome.Refund.ID,
+		"amount_minor":   outcome.Refund.AmountMinor,
+		"transaction_id": req.TransactionID,
+		"status_before":  outcome.StatusBefore,
+		"status_after":   outcome.StatusAfter,
+	})
+	if h.pluginManager != nil && outcome.StatusAfter != outcome.StatusBefore {
+		hookExecCtx := h.buildOrderHookExecutionContext(c, adminID, order.ID)
+		service.EmitOrderStatusChangedAfterHookAsync(h.pluginManager, hookExecCtx, order, outcome.StatusBefore, outcome.StatusAfter, map[string]interface{}{
+			"source":         "admin_api",
+			"trigger_action": "order.admin.refund_finalize",
+			"admin_id":       adminID,
+			"refund_id":      outcome.Refund.ID,
+			"transaction_id": req.TransactionID,
+		})
+	}
+	response.Success(c, outcome)
+}
@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/validator"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ReceiveOrderReturnRequest 登记退货请求，restock 默认为 true
+type ReceiveOrderReturnRequest struct {
+	Items             []service.OrderReturnItem `json:"items" binding:"required,min=1"`
+	Restock           *bool                     `json:"restock"`
+	InvalidateSerials bool                      `json:"invalidate_serials"`
+	Reason            string                    `json:"reason"`
+}
+
+// ReceiveOrderReturn 登记已发货订单的退货商品，可回补库存并作废序列号
+func (h *OrderHandler) ReceiveOrderReturn(c *gin.Context) {
+	adminID, adminIDOK := middleware.RequireUserID(c)
+	if !adminIDOK {
+		return
+	}
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	var req ReceiveOrderReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	req.Reason = validator.SanitizeText(req.Reason)
+	if !validator.ValidateLength(req.Reason, 0, 500) {
+		response.BadRequest(c, "Reason is too long")
+		return
+	}
+
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	operator := "unknown"
+	if email, ok := c.Get("user_email"); ok {
+		if value, ok := email.(string); ok {
+			operator = value
+		}
+	}
+	restock := req.Restock == nil || *req.Restock
+	result, err := h.orderService.ReceiveReturn(orderID, service.OrderReturnInput{
+		Items:             req.Items,
+		Restock:           restock,
+		InvalidateSerials: req.InvalidateSerials,
+		Reason:            req.Reason,
+		Operator:          operator,
+		OperatorID:        &adminID,
+	})
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to record return")
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "receive_return", order.ID, map[string]interface{}{
+		"order_no":            order.OrderNo,
+		"items":               req.Items,
+		"restock":             restock,
+		"restocked_quantity":  result.RestockedQuantity,
+		"invalidated_serials": result.InvalidatedSerials,
+		"reason":              req.Reason,
+	})
+	response.Success(c, result)
+}
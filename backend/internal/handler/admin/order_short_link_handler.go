@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func adminShortLinkCreator(c *gin.Context) *uint {
+	if c == nil {
+		return nil
+	}
+	if userID, ok := middleware.GetUserID(c); ok && userID != 0 {
+		return &userID
+	}
+	return nil
+}
+
+func buildShortLinkPayload(shortLinkService *service.ShortLinkService, link *models.ShortLink) gin.H {
+	return gin.H{
+		"id":              link.ID,
+		"code":            link.Code,
+		"target_type":     link.TargetType,
+		"target_url":      link.TargetURL,
+		"short_url":       shortLinkService.ShortURL(link),
+		"qr_code_url":     shortLinkService.QRCodeURL(link),
+		"expires_at":      link.ExpiresAt,
+		"revoked":         link.Revoked,
+		"click_count":     link.ClickCount,
+		"last_clicked_at": link.LastClickedAt,
+		"created_at":      link.CreatedAt,
+	}
+}
+
+// ListOrderShortLinks 订单短链列表
+func (h *OrderHandler) ListOrderShortLinks(c *gin.Context) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	if h.shortLinkService == nil {
+		response.InternalError(c, "Short link service unavailable")
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+	links, err := h.shortLinkService.ListByOrder(order.ID)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	items := make([]gin.H, 0, len(links))
+	for i := range links {
+		items = append(items, buildShortLinkPayload(h.shortLinkService, &links[i]))
+	}
+	response.Success(c, gin.H{"items": items})
+}
+
+// CreateOrderShortLink 生成订单收货表单/付款短链（已存在有效短链时直接返回）
+func (h *OrderHandler) CreateOrderShortLink(c *gin.Context) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Type string `json:"type" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if h.shortLinkService == nil {
+		response.InternalError(c, "Short link service unavailable")
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	var link *models.ShortLink
+	switch req.Type {
+	case models.ShortLinkTypeShippingForm:
+		link, err = h.shortLinkService.ForShippingForm(order, adminShortLinkCreator(c))
+	case models.ShortLinkTypePayment:
+		link, err = h.shortLinkService.ForPayment(order, adminShortLinkCreator(c))
+	default:
+		response.BadRequest(c, "Invalid short link type")
+		return
+	}
+	if err != nil {
+		respondAdminOrderServiceError(c, err, "Failed to create short link")
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "create_short_link", order.ID, map[string]interface{}{
+		"order_no": order.OrderNo,
+		"type":     link.TargetType,
+		"code":     link.Code,
+	})
+	response.Success(c, buildShortLinkPayload(h.shortLinkService, link))
+}
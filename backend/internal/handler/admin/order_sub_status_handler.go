@@ -0,0 +1,255 @@
+package admin
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/orderbiz"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type OrderSubStatusHandler struct {
+	subStatusService *service.OrderSubStatusService
+}
+
+func NewOrderSubStatusHandler(subStatusService *service.OrderSubStatusService) *OrderSubStatusHandler {
+	return &OrderSubStatusHandler{subStatusService: subStatusService}
+}
+
+// SetSubStatusService 设置订单子状态服务
+func (h *OrderHandler) SetSubStatusService(subStatusService *service.OrderSubStatusService) {
+	h.subStatusService = subStatusService
+}
+
+// UpdateOrderSubStatusRequest 设置订单子状态请求，sub_status 为空表示清除
+type UpdateOrderSubStatusRequest struct {
+	SubStatus string `json:"sub_status"`
+	Note      string `json:"note"`
+}
+
+func parseOrderSubStatusID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *OrderSubStatusHandler) respondSubStatusError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// ListOrderSubStatuses 子状态列表（可按核心状态过滤）
+func (h *OrderSubStatusHandler) ListOrderSubStatuses(c *gin.Context) {
+	items, err := h.subStatusService.List(strings.TrimSpace(c.Query("status")))
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": items})
+}
+
+// CreateOrderSubStatus 创建子状态
+func (h *OrderSubStatusHandler) CreateOrderSubStatus(c *gin.Context) {
+	var req service.OrderSubStatusInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	item, err := h.subStatusService.Create(req)
+	if err != nil {
+		h.respondSubStatusError(c, err, "Failed to create order sub-status")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create", "order_sub_status", &item.ID, map[string]interface{}{
+		"code":   item.Code,
+		"status": item.Status,
+		"name":   item.Name,
+	})
+	response.Success(c, item)
+}
+
+// UpdateOrderSubStatusDefinition 更新子状态
+func (h *OrderSubStatusHandler) UpdateOrderSubStatusDefinition(c *gin.Context) {
+	id, ok := parseOrderSubStatusID(c)
+	if !ok {
+		return
+	}
+	var req service.OrderSubStatusInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	item, err := h.subStatusService.Update(id, req)
+	if err != nil {
+		h.respondSubStatusError(c, err, "Failed to update order sub-status")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "update", "order_sub_status", &item.ID, map[string]interface{}{
+		"code":            item.Code,
+		"status":          item.Status,
+		"name":            item.Name,
+		"notify_customer": item.NotifyCustomer,
+	})
+	response.Success(c, item)
+}
+
+// DeleteOrderSubStatus 删除子状态
+func (h *OrderSubStatusHandler) DeleteOrderSubStatus(c *gin.Context) {
+	id, ok := parseOrderSubStatusID(c)
+	if !ok {
+		return
+	}
+	if err := h.subStatusService.Delete(id); err != nil {
+		h.respondSubStatusError(c, err, "Failed to delete order sub-status")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "delete", "order_sub_status", &id, nil)
+	response.Success(c, gin.H{"message": "Order sub-status deleted"})
+}
+
+func applyAdminOrderSubStatusHookPayload(req *UpdateOrderSubStatusRequest, payload map[string]interface{}) error {
+	if req == nil || payload == nil {
+		return nil
+	}
+	if raw, exists := payload["sub_status"]; exists {
+		value, err := orderValueToOptionalString(raw)
+		if err != nil {
+			return err
+		}
+		req.SubStatus = value
+	}
+	if raw, exists := payload["note"]; exists {
+		value, err := orderValueToOptionalString(raw)
+		if err != nil {
+			return err
+		}
+		req.Note = value
+	}
+	return nil
+}
+
+// UpdateOrderSubStatus 设置/清除订单子状态
+func (h *OrderHandler) UpdateOrderSubStatus(c *gin.Context) {
+	adminID, adminIDOK := middleware.RequireUserID(c)
+	if !adminIDOK {
+		return
+	}
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	if h.subStatusService == nil {
+		response.InternalError(c, "Order sub-status service is not available")
+		return
+	}
+
+	var req UpdateOrderSubStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondAdminOrderValidationError(c, orderbiz.InvalidRequestParameters())
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	hookExecCtx := h.buildOrderHookExecutionContext(c, adminID, uint(orderID))
+	if h.pluginManager != nil {
+		originalReq := req
+		hookPayload := map[string]interface{}{
+			"order_id":          order.ID,
+			"order_no":          order.OrderNo,
+			"admin_id":          adminID,
+			"status":            order.Status,
+			"sub_status_before": order.SubStatus,
+			"sub_status":        req.SubStatus,
+			"note":              req.Note,
+			"source":            "admin_api",
+		}
+		hookResult, hookErr := h.pluginManager.ExecuteHook(service.HookExecutionRequest{
+			Hook:    "order.admin.sub_status.before",
+			Payload: hookPayload,
+		}, hookExecCtx)
+		if hookErr != nil {
+			log.Printf("order.admin.sub_status.before hook execution failed: admin=%d order=%s err=%v", adminID, order.OrderNo, hookErr)
+		} else if hookResult != nil {
+			if hookResult.Blocked {
+				reason := strings.TrimSpace(hookResult.BlockReason)
+				if reason == "" {
+					reason = "Order sub-status update rejected by plugin"
+				}
+				response.BadRequest(c, reason)
+				return
+			}
+			if hookResult.Payload != nil {
+				if applyErr := applyAdminOrderSubStatusHookPayload(&req, hookResult.Payload); applyErr != nil {
+					log.Printf("order.admin.sub_status.before payload apply failed, fallback to original request: admin=%d order=%s err=%v", adminID, order.OrderNo, applyErr)
+					req = originalReq
+				}
+			}
+		}
+	}
+
+	change, err := h.subStatusService.SetOrderSubStatus(order.ID, req.SubStatus, strings.TrimSpace(req.Note))
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to update order sub-status", err)
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "set_sub_status", order.ID, map[string]interface{}{
+		"order_no":          order.OrderNo,
+		"status":            change.Order.Status,
+		"sub_status_before": change.Previous,
+		"sub_status_after":  change.Order.SubStatus,
+		"note":              req.Note,
+	})
+
+	if h.pluginManager != nil {
+		afterPayload := map[string]interface{}{
+			"order_id":          order.ID,
+			"order_no":          order.OrderNo,
+			"admin_id":          adminID,
+			"status":            change.Order.Status,
+			"sub_status_before": change.Previous,
+			"sub_status_after":  change.Order.SubStatus,
+			"note":              req.Note,
+			"source":            "admin_api",
+		}
+		go func(execCtx *service.ExecutionContext, payload map[string]interface{}, aid uint, orderNo string) {
+			_, hookErr := h.pluginManager.ExecuteHook(service.HookExecutionRequest{
+				Hook:    "order.admin.sub_status.after",
+				Payload: payload,
+			}, execCtx)
+			if hookErr != nil {
+				log.Printf("order.admin.sub_status.after hook execution failed: admin=%d order=%s err=%v", aid, orderNo, hookErr)
+			}
+		}(hookExecCtx, afterPayload, adminID, order.OrderNo)
+	}
+
+	response.Success(c, gin.H{
+		"order_no":   change.Order.OrderNo,
+		"status":     change.Order.Status,
+		"sub_status": change.Order.SubStatus,
+	})
+}
@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"log"
+	"strings"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/orderbiz"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateOrderTagsRequest 整体替换订单标签，空数组表示清空
+type UpdateOrderTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+func applyAdminOrderTagsHookPayload(req *UpdateOrderTagsRequest, payload map[string]interface{}) error {
+	if req == nil || payload == nil {
+		return nil
+	}
+	if raw, exists := payload["tags"]; exists {
+		value, err := productHookValueToStringSlice(raw)
+		if err != nil {
+			return err
+		}
+		req.Tags = value
+	}
+	return nil
+}
+
+// UpdateOrderTags 设置订单标签
+func (h *OrderHandler) UpdateOrderTags(c *gin.Context) {
+	adminID, adminIDOK := middleware.RequireUserID(c)
+	if !adminIDOK {
+		return
+	}
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateOrderTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondAdminOrderValidationError(c, orderbiz.InvalidRequestParameters())
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	hookExecCtx := h.buildOrderHookExecutionContext(c, adminID, uint(orderID))
+	if h.pluginManager != nil {
+		originalReq := req
+		hookPayload := map[string]interface{}{
+			"order_id":    order.ID,
+			"order_no":    order.OrderNo,
+			"admin_id":    adminID,
+			"status":      order.Status,
+			"tags_before": order.Tags,
+			"tags":        req.Tags,
+			"source":      "admin_api",
+		}
+		hookResult, hookErr := h.pluginManager.ExecuteHook(service.HookExecutionRequest{
+			Hook:    "order.admin.tags.before",
+			Payload: hookPayload,
+		}, hookExecCtx)
+		if hookErr != nil {
+			log.Printf("order.admin.tags.before hook execution failed: admin=%d order=%s err=%v", adminID, order.OrderNo, hookErr)
+		} else if hookResult != nil {
+			if hookResult.Blocked {
+				reason := strings.TrimSpace(hookResult.BlockReason)
+				if reason == "" {
+					reason = "Order tag update rejected by plugin"
+				}
+				response.BadRequest(c, reason)
+				return
+			}
+			if hookResult.Payload != nil {
+				if applyErr := applyAdminOrderTagsHookPayload(&req, hookResult.Payload); applyErr != nil {
+					log.Printf("order.admin.tags.before payload apply failed, fallback to original request: admin=%d order=%s err=%v", adminID, order.OrderNo, applyErr)
+					req = originalReq
+				}
+			}
+		}
+	}
+
+	before, after, err := service.SetOrderTags(database.GetDB(), order.ID, req.Tags)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to update order tags", err)
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "set_tags", order.ID, map[string]interface{}{
+		"order_no":    order.OrderNo,
+		"tags_before": before,
+		"tags_after":  after,
+	})
+
+	if h.pluginManager != nil {
+		afterPayload := map[string]interface{}{
+			"order_id":    order.ID,
+			"order_no":    order.OrderNo,
+			"admin_id":    adminID,
+			"status":      order.Status,
+			"tags_before": before,
+			"tags_after":  after,
+			"source":      "admin_api",
+		}
+		go func(execCtx *service.ExecutionContext, payload map[string]interface{}, aid uint, orderNo string) {
+			_, hookErr := h.pluginManager.ExecuteHook(service.HookExecutionRequest{
+				Hook:    "order.admin.tags.after",
+				Payload: payload,
+			}, execCtx)
+			if hookErr != nil {
+				log.Printf("order.admin.tags.after hook execution failed: admin=%d order=%s err=%v", aid, orderNo, hookErr)
+			}
+		}(hookExecCtx, afterPayload, adminID, order.OrderNo)
+	}
+
+	response.Success(c, gin.H{
+		"order_no": order.OrderNo,
+		"tags":     after,
+	})
+}
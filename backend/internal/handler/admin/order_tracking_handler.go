@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"errors"
+
+	"auralogic/internal/database"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SetShipmentTrackingService 设置物流轨迹同步服务
+func (h *OrderHandler) SetShipmentTrackingService(shipmentTrackingService *service.ShipmentTrackingService) {
+	h.shipmentTrackingService = shipmentTrackingService
+}
+
+// GetOrderTracking 订单物流轨迹，未追踪时 tracking 为 null
+func (h *OrderHandler) GetOrderTracking(c *gin.Context) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+
+	tracking, err := h.shipmentTrackingService.GetOrderTracking(order.ID)
+	if err != nil {
+		response.InternalServerError(c, "Failed to load shipment tracking", err)
+		return
+	}
+	response.Success(c, gin.H{
+		"enabled":  h.shipmentTrackingService.Enabled(),
+		"tracking": tracking,
+	})
+}
+
+// SyncOrderTracking 立即向服务商注册并拉取订单物流轨迹
+func (h *OrderHandler) SyncOrderTracking(c *gin.Context) {
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	order, err := h.orderService.GetOrderByID(orderID)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if !ensureAdminStoreAccess(c, order.StoreID) {
+		return
+	}
+	if order.TrackingNo == "" {
+		response.BadRequest(c, "Order has no tracking number")
+		return
+	}
+
+	tracking, err := h.shipmentTrackingService.SyncOrder(order.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrShipmentTrackingDisabled) {
+			response.BadRequest(c, "Shipment tracking is not enabled")
+			return
+		}
+		response.InternalServerError(c, "Failed to sync shipment tracking", err)
+		return
+	}
+
+	logger.LogOrderOperation(database.GetDB(), c, "sync_tracking", order.ID, map[string]interface{}{
+		"order_no":    order.OrderNo,
+		"tracking_no": tracking.TrackingNo,
+		"status":      tracking.Status,
+		"last_error":  tracking.LastError,
+	})
+	response.Success(c, gin.H{
+		"enabled":  true,
+		"tracking": tracking,
+	})
+}
@@ -0,0 +1,235 @@
+package admin
+
+import (
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PaymentMatchHandler 人工对账：到账流水与待付款订单匹配
+type PaymentMatchHandler struct {
+	db           *gorm.DB
+	matchService *service.PaymentMatchService
+}
+
+func NewPaymentMatchHandler(db *gorm.DB, matchService *service.PaymentMatchService) *PaymentMatchHandler {
+	return &PaymentMatchHandler{db: db, matchService: matchService}
+}
+
+func (h *PaymentMatchHandler) available(c *gin.Context) bool {
+	if h == nil || h.matchService == nil {
+		response.InternalError(c, "Payment match service is unavailable")
+		return false
+	}
+	return true
+}
+
+func (h *PaymentMatchHandler) transactionID(c *gin.Context) (uint, bool) {
+	id, err := parseUintParam(c.Param("id"))
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid transaction id")
+		return 0, false
+	}
+	return id, true
+}
+
+// ListTransactions 到账流水列表
+func (h *PaymentMatchHandler) ListTransactions(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	page, limit := response.GetPagination(c)
+	txns, total, err := h.matchService.ListTransactions(c.Query("status"), page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, txns, page, limit, total)
+}
+
+// CreateTransaction 手动录入到账流水
+func (h *PaymentMatchHandler) CreateTransaction(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req service.IncomingTransactionInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	txn, created, err := h.matchService.RecordTransaction(req, models.IncomingTransactionSourceManual, contextUserID(c))
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to record transaction")
+		return
+	}
+	if !created {
+		response.BadRequest(c, "Transaction already exists")
+		return
+	}
+	logger.LogOperation(h.db, c, "create", "incoming_transaction", &txn.ID, map[string]interface{}{
+		"transaction_id": txn.TransactionID,
+		"channel":        txn.Channel,
+		"amount":         txn.Amount,
+		"currency":       txn.Currency,
+	})
+	response.Success(c, txn)
+}
+
+// ImportTransactions 从 CSV/XLSX 导入到账流水
+func (h *PaymentMatchHandler) ImportTransactions(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "Please select an import file to upload")
+		return
+	}
+	_, tableRows, err := readAdminTabularRows(file)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "unsupported format") {
+			response.BadRequest(c, "Only .csv or .xlsx files are supported")
+			return
+		}
+		response.BadRequest(c, "Failed to parse import file")
+		return
+	}
+	result, err := h.matchService.ImportTransactions(tableRows, contextUserID(c))
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to import transactions")
+		return
+	}
+	logger.LogOperation(h.db, c, "import", "incoming_transaction", nil, map[string]interface{}{
+		"filename":    file.Filename,
+		"created":     result.Created,
+		"duplicates":  result.Duplicates,
+		"error_count": len(result.Errors),
+	})
+	response.Success(c, result)
+}
+
+// DownloadImportTemplate 下载到账流水导入模板
+func (h *PaymentMatchHandler) DownloadImportTemplate(c *gin.Context) {
+	writeXLSXAttachment(c, "incoming_transactions_template.xlsx", "Transactions", service.PaymentMatchImportTemplateHeaders, [][]string{
+		{"20260101000123", "199.00", "CNY", "ORD20260101ABC123", "Alice", "2026-01-01 10:30:00", "bank"},
+	})
+}
+
+// ListPendingOrders 待付款订单
+func (h *PaymentMatchHandler) ListPendingOrders(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	scope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
+	page, limit := response.GetPagination(c)
+	orders, total, err := h.matchService.ListPendingOrders(c.Query("search"), scope, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, orders, page, limit, total)
+}
+
+// GetSuggestions 到账流水的匹配建议
+func (h *PaymentMatchHandler) GetSuggestions(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	id, ok := h.transactionID(c)
+	if !ok {
+		return
+	}
+	scope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
+	suggestions, err := h.matchService.SuggestMatches(id, scope, 0)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, suggestions)
+}
+
+// MatchTransaction 匹配订单并标记已付款
+func (h *PaymentMatchHandler) MatchTransaction(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	id, ok := h.transactionID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		OrderID uint   `json:"order_id" binding:"required"`
+		Remark  string `json:"remark"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if !ensureAdminOrderStoreAccess(c, req.OrderID) {
+		return
+	}
+	txn, err := h.matchService.MatchTransaction(id, req.OrderID, contextUserID(c), req.Remark)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to match transaction")
+		return
+	}
+	logger.LogOperation(h.db, c, "payment_match", "order", &req.OrderID, map[string]interface{}{
+		"incoming_transaction_id": txn.ID,
+		"transaction_id":          txn.TransactionID,
+		"amount":                  txn.Amount,
+		"currency":                txn.Currency,
+		"order_no":                txn.MatchedOrderNo,
+	})
+	response.Success(c, txn)
+}
+
+// IgnoreTransaction 忽略到账流水
+func (h *PaymentMatchHandler) IgnoreTransaction(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	id, ok := h.transactionID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Remark string `json:"remark"`
+	}
+	_ = c.ShouldBindJSON(&req)
+	txn, err := h.matchService.IgnoreTransaction(id, contextUserID(c), req.Remark)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to ignore transaction")
+		return
+	}
+	logger.LogOperation(h.db, c, "ignore", "incoming_transaction", &txn.ID, map[string]interface{}{
+		"transaction_id": txn.TransactionID,
+		"remark":         txn.Remark,
+	})
+	response.Success(c, txn)
+}
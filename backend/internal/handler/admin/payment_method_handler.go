@@ -1,13 +1,16 @@
 package admin
 
 import (
+	"errors"
 	"log"
+	"net/http"
 	"strconv"
 	"strings"
 
 	"auralogic/internal/config"
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
 	"auralogic/internal/pkg/response"
 	"auralogic/internal/service"
 	"github.com/gin-gonic/gin"
@@ -16,9 +19,10 @@ import (
 
 // PaymentMethodHandler 付款方式管理处理器
 type PaymentMethodHandler struct {
-	db            *gorm.DB
-	service       *service.PaymentMethodService
-	pluginManager *service.PluginManagerService
+	db             *gorm.DB
+	service        *service.PaymentMethodService
+	pluginManager  *service.PluginManagerService
+	pollingService *service.PaymentPollingService
 }
 
 // NewPaymentMethodHandler 创建付款方式处理器
@@ -30,6 +34,11 @@ func NewPaymentMethodHandler(db *gorm.DB, cfg *config.Config, pluginManager *ser
 	}
 }
 
+// SetPaymentPollingService 注入付款确认服务（沙箱模拟付款使用）
+func (h *PaymentMethodHandler) SetPaymentPollingService(pollingService *service.PaymentPollingService) {
+	h.pollingService = pollingService
+}
+
 // List 获取所有付款方式
 func (h *PaymentMethodHandler) List(c *gin.Context) {
 	enabledOnly := c.Query("enabled_only") == "true"
@@ -66,6 +75,15 @@ type CreatePaymentMethodRequest struct {
 	Script       string `json:"script"`
 	Config       string `json:"config"`
 	PollInterval int    `json:"poll_interval"`
+	// 未付款自动取消时限（小时），0 表示使用商品/全局设置
+	AutoCancelHours int `json:"auto_cancel_hours" binding:"gte=0,lte=720"`
+	// 脚本出站 HTTP 主机白名单
+	AllowedHosts []string `json:"allowed_hosts"`
+	// 沙箱模式：订单标记为测试单，可由管理员模拟付款
+	Sandbox bool `json:"sandbox"`
+	// 订单标签规则：需全部包含 / 包含任一即不可用
+	RequiredOrderTags []string `json:"required_order_tags"`
+	ExcludedOrderTags []string `json:"excluded_order_tags"`
 }
 
 // Create 创建付款方式
@@ -137,13 +155,31 @@ func (h *PaymentMethodHandler) Create(c *gin.Context) {
 		return
 	}
 
+	allowedHosts, ok := normalizePaymentMethodAllowedHosts(c, req.AllowedHosts)
+	if !ok {
+		return
+	}
+	requiredOrderTags, ok := normalizePaymentMethodOrderTags(c, req.RequiredOrderTags)
+	if !ok {
+		return
+	}
+	excludedOrderTags, ok := normalizePaymentMethodOrderTags(c, req.ExcludedOrderTags)
+	if !ok {
+		return
+	}
+
 	method, err := h.service.CreateLegacyPaymentMethod(service.LegacyPaymentMethodUpsertInput{
-		Name:         &req.Name,
-		Description:  &req.Description,
-		Icon:         &req.Icon,
-		Script:       &req.Script,
-		Config:       &req.Config,
-		PollInterval: &req.PollInterval,
+		Name:              &req.Name,
+		Description:       &req.Description,
+		Icon:              &req.Icon,
+		Script:            &req.Script,
+		Config:            &req.Config,
+		PollInterval:      &req.PollInterval,
+		AutoCancelHours:   &req.AutoCancelHours,
+		AllowedHosts:      &allowedHosts,
+		Sandbox:           &req.Sandbox,
+		RequiredOrderTags: &requiredOrderTags,
+		ExcludedOrderTags: &excludedOrderTags,
 	})
 	if err != nil {
 		h.respondPaymentMethodMarketError(c, err)
@@ -193,6 +229,14 @@ type UpdatePaymentMethodRequest struct {
 	Config       *string `json:"config"`
 	Enabled      *bool   `json:"enabled"`
 	PollInterval *int    `json:"poll_interval"`
+	// 未付款自动取消时限（小时），0 表示使用商品/全局设置
+	AutoCancelHours *int `json:"auto_cancel_hours" binding:"omitempty,gte=0,lte=720"`
+	// 脚本出站 HTTP 主机白名单，传空数组表示清空
+	AllowedHosts *[]string `json:"allowed_hosts"`
+	Sandbox      *bool     `json:"sandbox"`
+	// 订单标签规则，传空数组表示清空
+	RequiredOrderTags *[]string `json:"required_order_tags"`
+	ExcludedOrderTags *[]string `json:"excluded_order_tags"`
 }
 
 // Update 更新付款方式
@@ -287,6 +331,28 @@ func (h *PaymentMethodHandler) Update(c *gin.Context) {
 		}
 	}
 
+	if req.AllowedHosts != nil {
+		allowedHosts, ok := normalizePaymentMethodAllowedHosts(c, *req.AllowedHosts)
+		if !ok {
+			return
+		}
+		req.AllowedHosts = &allowedHosts
+	}
+	if req.RequiredOrderTags != nil {
+		tags, ok := normalizePaymentMethodOrderTags(c, *req.RequiredOrderTags)
+		if !ok {
+			return
+		}
+		req.RequiredOrderTags = &tags
+	}
+	if req.ExcludedOrderTags != nil {
+		tags, ok := normalizePaymentMethodOrderTags(c, *req.ExcludedOrderTags)
+		if !ok {
+			return
+		}
+		req.ExcludedOrderTags = &tags
+	}
+
 	var updatedMethod *models.PaymentMethod
 	if req.Name != nil ||
 		req.Description != nil ||
@@ -295,13 +361,18 @@ func (h *PaymentMethodHandler) Update(c *gin.Context) {
 		req.Config != nil ||
 		req.PollInterval != nil {
 		method, err := h.service.UpdateLegacyPaymentMethod(uint(id), service.LegacyPaymentMethodUpsertInput{
-			Name:         req.Name,
-			Description:  req.Description,
-			Icon:         req.Icon,
-			Script:       req.Script,
-			Config:       req.Config,
-			PollInterval: req.PollInterval,
-			Enabled:      req.Enabled,
+			Name:              req.Name,
+			Description:       req.Description,
+			Icon:              req.Icon,
+			Script:            req.Script,
+			Config:            req.Config,
+			PollInterval:      req.PollInterval,
+			Enabled:           req.Enabled,
+			AutoCancelHours:   req.AutoCancelHours,
+			AllowedHosts:      req.AllowedHosts,
+			Sandbox:           req.Sandbox,
+			RequiredOrderTags: req.RequiredOrderTags,
+			ExcludedOrderTags: req.ExcludedOrderTags,
 		})
 		if err != nil {
 			h.respondPaymentMethodMarketError(c, err)
@@ -313,8 +384,26 @@ func (h *PaymentMethodHandler) Update(c *gin.Context) {
 		if req.Enabled != nil {
 			updates["enabled"] = *req.Enabled
 		}
+		if req.AutoCancelHours != nil {
+			updates["auto_cancel_hours"] = *req.AutoCancelHours
+		}
+		if req.Sandbox != nil {
+			updates["sandbox"] = *req.Sandbox
+		}
 
-		if err := h.service.Update(uint(id), updates); err != nil {
+		if len(updates) > 0 || (req.AllowedHosts == nil && req.RequiredOrderTags == nil && req.ExcludedOrderTags == nil) {
+			if err := h.service.Update(uint(id), updates); err != nil {
+				response.InternalError(c, "Failed to update payment method")
+				return
+			}
+		}
+		if req.AllowedHosts != nil {
+			if err := h.service.UpdateAllowedHosts(uint(id), *req.AllowedHosts); err != nil {
+				response.InternalError(c, "Failed to update payment method")
+				return
+			}
+		}
+		if err := h.service.UpdateOrderTagRules(uint(id), req.RequiredOrderTags, req.ExcludedOrderTags); err != nil {
 			response.InternalError(c, "Failed to update payment method")
 			return
 		}
@@ -496,6 +585,105 @@ func (h *PaymentMethodHandler) ToggleEnabled(c *gin.Context) {
 	response.Success(c, pm)
 }
 
+// SimulatePayment 沙箱付款方式：模拟订单付款成功（代替脚本 onCheckPaymentStatus 的结果）
+func (h *PaymentMethodHandler) SimulatePayment(c *gin.Context) {
+	if h.pollingService == nil {
+		response.InternalError(c, "Payment confirmation service is unavailable")
+		return
+	}
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || orderID == 0 {
+		response.BadRequest(c, "Invalid order ID")
+		return
+	}
+	if !ensureAdminOrderStoreAccess(c, uint(orderID)) {
+		return
+	}
+	order, err := h.pollingService.SimulateSandboxPayment(uint(orderID), contextUserID(c))
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.NotFound(c, "Order not found")
+			return
+		}
+		response.HandleError(c, "Failed to simulate payment", err)
+		return
+	}
+
+	logger.LogOperation(h.db, c, "simulate_payment", "order", &order.ID, map[string]interface{}{
+		"order_no": order.OrderNo,
+		"status":   order.Status,
+	})
+	response.Success(c, order)
+}
+
+// normalizePaymentMethodAllowedHosts 校验脚本出站主机白名单
+func normalizePaymentMethodAllowedHosts(c *gin.Context, hosts []string) ([]string, bool) {
+	normalized, err := models.NormalizePaymentMethodAllowedHosts(hosts)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return nil, false
+	}
+	return normalized, true
+}
+
+// normalizePaymentMethodOrderTags 校验付款方式的订单标签规则
+func normalizePaymentMethodOrderTags(c *gin.Context, tags []string) ([]string, bool) {
+	normalized, err := service.NormalizeOrderTags(tags)
+	if err != nil {
+		respondAdminBizError(c, err)
+		return nil, false
+	}
+	return normalized, true
+}
+
+// ListBreakers 付款方式出站 HTTP 熔断状态（进程内，可按 payment_method_id 过滤）
+func (h *PaymentMethodHandler) ListBreakers(c *gin.Context) {
+	var paymentMethodID uint
+	if raw := strings.TrimSpace(c.Query("payment_method_id")); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			response.BadRequest(c, "Invalid payment_method_id")
+			return
+		}
+		paymentMethodID = uint(id)
+	}
+	response.Success(c, gin.H{"items": service.ListPaymentHTTPBreakers(paymentMethodID)})
+}
+
+// ResetBreakers 手动关闭指定付款方式的熔断
+func (h *PaymentMethodHandler) ResetBreakers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return
+	}
+	removed := service.ResetPaymentHTTPBreakers(uint(id))
+	paymentMethodID := uint(id)
+	logger.LogOperation(h.db, c, "reset_breaker", "payment_method", &paymentMethodID, map[string]interface{}{
+		"reset_count": removed,
+	})
+	response.Success(c, gin.H{"reset_count": removed})
+}
+
+// ListScriptLogs 付款方式脚本执行日志（console 输出与执行错误）
+func (h *PaymentMethodHandler) ListScriptLogs(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return
+	}
+	page, limit := response.GetPagination(c)
+	logs, total, err := h.service.ListScriptLogs(uint(id), c.Query("callback"), page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, logs, page, limit, total)
+}
+
 // ReorderRequest 重排序请求
 type ReorderPaymentMethodRequest struct {
 	IDs []uint `json:"ids" binding:"required"`
@@ -586,7 +774,11 @@ func (h *PaymentMethodHandler) TestScript(c *gin.Context) {
 
 	result, err := h.service.TestScript(req.Script, req.Config)
 	if err != nil {
-		response.BadRequest(c, "Script execution failed")
+		// 失败时一并返回 console 输出，便于定位脚本问题
+		response.ErrorWithData(c, http.StatusBadRequest, response.CodeParamError, "Script execution failed", gin.H{
+			"error": err.Error(),
+			"logs":  result.Logs,
+		})
 		return
 	}
 	if h.pluginManager != nil {
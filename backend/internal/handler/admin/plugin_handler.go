@@ -236,10 +236,15 @@ var pluginTestAllHookChecks = []string{
 	"hook.order.admin.update_shipping.after",
 	"hook.order.admin.update_price.before",
 	"hook.order.admin.update_price.after",
+	"hook.order.admin.sub_status.before",
+	"hook.order.admin.sub_status.after",
+	"hook.order.admin.tags.before",
+	"hook.order.admin.tags.after",
 	"hook.order.admin.delete.before",
 	"hook.order.admin.delete.after",
 	"hook.order.auto_cancel.before",
 	"hook.order.auto_cancel.after",
+	"hook.order.payment_reminder.after",
 	"hook.order.status.changed.after",
 	"hook.payment.method.select.before",
 	"hook.payment.method.select.after",
@@ -12,6 +12,7 @@ import (
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/money"
 	"auralogic/internal/pkg/response"
 	"auralogic/internal/pkg/utils"
 	"auralogic/internal/service"
@@ -22,6 +23,7 @@ type ProductHandler struct {
 	productService          *service.ProductService
 	virtualInventoryService *service.VirtualInventoryService
 	pluginManager           *service.PluginManagerService
+	priceService            *service.ProductPriceService
 }
 
 type productListFilters struct {
@@ -261,6 +263,17 @@ func productHookValueToAttributes(value interface{}) ([]models.ProductAttribute,
 	return out, nil
 }
 
+func productHookValueToPriceTiers(value interface{}) ([]models.ProductPriceTier, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var out []models.ProductPriceTier
+	if err := productHookDecodeJSONValue(value, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func applyUpdateProductHookPayload(req *UpdateProductRequest, payload map[string]interface{}) error {
 	if req == nil || payload == nil {
 		return nil
@@ -350,6 +363,34 @@ func applyUpdateProductHookPayload(req *UpdateProductRequest, payload map[string
 		}
 		req.MaxPurchaseLimit = value
 	}
+	if raw, exists := payload["price_tiers"]; exists {
+		value, err := productHookValueToPriceTiers(raw)
+		if err != nil {
+			return fmt.Errorf("decode price_tiers: %w", err)
+		}
+		req.PriceTiers = value
+	}
+	if raw, exists := payload["min_order_quantity"]; exists {
+		value, err := productHookValueToInt(raw)
+		if err != nil {
+			return fmt.Errorf("decode min_order_quantity: %w", err)
+		}
+		req.MinOrderQuantity = value
+	}
+	if raw, exists := payload["max_order_quantity"]; exists {
+		value, err := productHookValueToInt(raw)
+		if err != nil {
+			return fmt.Errorf("decode max_order_quantity: %w", err)
+		}
+		req.MaxOrderQuantity = value
+	}
+	if raw, exists := payload["quantity_increment"]; exists {
+		value, err := productHookValueToInt(raw)
+		if err != nil {
+			return fmt.Errorf("decode quantity_increment: %w", err)
+		}
+		req.QuantityIncrement = value
+	}
 	if raw, exists := payload["images"]; exists {
 		value, err := productHookValueToImages(raw)
 		if err != nil {
@@ -406,6 +447,139 @@ func applyUpdateProductHookPayload(req *UpdateProductRequest, payload map[string
 		}
 		req.AutoDelivery = value
 	}
+	if raw, exists := payload["reserve_on_cart"]; exists {
+		value, err := productHookValueToBool(raw)
+		if err != nil {
+			return fmt.Errorf("decode reserve_on_cart: %w", err)
+		}
+		req.ReserveOnCart = value
+	}
+	if raw, exists := payload["waiting_room"]; exists {
+		value, err := productHookValueToBool(raw)
+		if err != nil {
+			return fmt.Errorf("decode waiting_room: %w", err)
+		}
+		req.WaitingRoom = value
+	}
+	if raw, exists := payload["price_on_request"]; exists {
+		value, err := productHookValueToBool(raw)
+		if err != nil {
+			return fmt.Errorf("decode price_on_request: %w", err)
+		}
+		req.PriceOnRequest = value
+	}
+	if raw, exists := payload["price_visible_tiers"]; exists {
+		value, err := productHookValueToStringSlice(raw)
+		if err != nil {
+			return fmt.Errorf("decode price_visible_tiers: %w", err)
+		}
+		req.PriceVisibleTiers = value
+	}
+	if raw, exists := payload["email_delivery_mode"]; exists {
+		value, err := productHookValueToOptionalString(raw)
+		if err != nil {
+			return fmt.Errorf("decode email_delivery_mode: %w", err)
+		}
+		req.EmailDeliveryMode = models.EmailDeliveryMode(value)
+	}
+	if raw, exists := payload["meta_title"]; exists {
+		value, err := productHookValueToOptionalString(raw)
+		if err != nil {
+			return fmt.Errorf("decode meta_title: %w", err)
+		}
+		req.MetaTitle = value
+	}
+	if raw, exists := payload["meta_description"]; exists {
+		value, err := productHookValueToOptionalString(raw)
+		if err != nil {
+			return fmt.Errorf("decode meta_description: %w", err)
+		}
+		req.MetaDescription = value
+	}
+	if raw, exists := payload["og_image"]; exists {
+		value, err := productHookValueToOptionalString(raw)
+		if err != nil {
+			return fmt.Errorf("decode og_image: %w", err)
+		}
+		req.OGImage = value
+	}
+	if raw, exists := payload["ship_within_days"]; exists {
+		value, err := productHookValueToInt(raw)
+		if err != nil {
+			return fmt.Errorf("decode ship_within_days: %w", err)
+		}
+		req.ShipWithinDays = value
+	}
+	if raw, exists := payload["auto_cancel_hours"]; exists {
+		value, err := productHookValueToInt(raw)
+		if err != nil {
+			return fmt.Errorf("decode auto_cancel_hours: %w", err)
+		}
+		req.AutoCancelHours = value
+	}
+	if raw, exists := payload["shipping_allowed_countries"]; exists {
+		value, err := productHookValueToStringSlice(raw)
+		if err != nil {
+			return fmt.Errorf("decode shipping_allowed_countries: %w", err)
+		}
+		req.ShippingAllowedCountries = value
+	}
+	if raw, exists := payload["shipping_blocked_countries"]; exists {
+		value, err := productHookValueToStringSlice(raw)
+		if err != nil {
+			return fmt.Errorf("decode shipping_blocked_countries: %w", err)
+		}
+		req.ShippingBlockedCountries = value
+	}
+	if raw, exists := payload["hs_code"]; exists {
+		value, err := productHookValueToOptionalString(raw)
+		if err != nil {
+			return fmt.Errorf("decode hs_code: %w", err)
+		}
+		req.HSCode = value
+	}
+	if raw, exists := payload["declared_value_minor"]; exists {
+		value, err := productHookValueToInt64(raw)
+		if err != nil {
+			return fmt.Errorf("decode declared_value_minor: %w", err)
+		}
+		req.DeclaredValueMinor = value
+	}
+	if raw, exists := payload["weight_grams"]; exists {
+		value, err := productHookValueToInt(raw)
+		if err != nil {
+			return fmt.Errorf("decode weight_grams: %w", err)
+		}
+		req.WeightGrams = value
+	}
+	if raw, exists := payload["length_mm"]; exists {
+		value, err := productHookValueToInt(raw)
+		if err != nil {
+			return fmt.Errorf("decode length_mm: %w", err)
+		}
+		req.LengthMM = value
+	}
+	if raw, exists := payload["width_mm"]; exists {
+		value, err := productHookValueToInt(raw)
+		if err != nil {
+			return fmt.Errorf("decode width_mm: %w", err)
+		}
+		req.WidthMM = value
+	}
+	if raw, exists := payload["height_mm"]; exists {
+		value, err := productHookValueToInt(raw)
+		if err != nil {
+			return fmt.Errorf("decode height_mm: %w", err)
+		}
+		req.HeightMM = value
+	}
+	if raw, exists := payload["origin_country"]; exists {
+		value, err := productHookValueToOptionalString(raw)
+		if err != nil {
+			return fmt.Errorf("decode origin_country: %w", err)
+		}
+		req.OriginCountry = value
+	}
 
 	return nil
 }
@@ -416,26 +590,49 @@ func applyCreateProductHookPayload(req *CreateProductRequest, payload map[string
 	}
 
 	patch := UpdateProductRequest{
-		SKU:                req.SKU,
-		Name:               req.Name,
-		ProductCode:        req.ProductCode,
-		ProductType:        req.ProductType,
-		Description:        req.Description,
-		ShortDescription:   req.ShortDescription,
-		Category:           req.Category,
-		Tags:               req.Tags,
-		PriceMinor:         req.PriceMinor,
-		OriginalPriceMinor: req.OriginalPriceMinor,
-		Stock:              req.Stock,
-		MaxPurchaseLimit:   req.MaxPurchaseLimit,
-		Images:             req.Images,
-		Attributes:         req.Attributes,
-		Status:             req.Status,
-		SortOrder:          req.SortOrder,
-		IsFeatured:         req.IsFeatured,
-		IsRecommended:      req.IsRecommended,
-		Remark:             req.Remark,
-		AutoDelivery:       req.AutoDelivery,
+		SKU:                      req.SKU,
+		Name:                     req.Name,
+		ProductCode:              req.ProductCode,
+		ProductType:              req.ProductType,
+		Description:              req.Description,
+		ShortDescription:         req.ShortDescription,
+		Category:                 req.Category,
+		Tags:                     req.Tags,
+		PriceMinor:               req.PriceMinor,
+		OriginalPriceMinor:       req.OriginalPriceMinor,
+		Stock:                    req.Stock,
+		MaxPurchaseLimit:         req.MaxPurchaseLimit,
+		PriceTiers:               req.PriceTiers,
+		MinOrderQuantity:         req.MinOrderQuantity,
+		MaxOrderQuantity:         req.MaxOrderQuantity,
+		QuantityIncrement:        req.QuantityIncrement,
+		Images:                   req.Images,
+		Attributes:               req.Attributes,
+		Status:                   req.Status,
+		SortOrder:                req.SortOrder,
+		IsFeatured:               req.IsFeatured,
+		IsRecommended:            req.IsRecommended,
+		Remark:                   req.Remark,
+		AutoDelivery:             req.AutoDelivery,
+		ReserveOnCart:            req.ReserveOnCart,
+		WaitingRoom:              req.WaitingRoom,
+		PriceOnRequest:           req.PriceOnRequest,
+		PriceVisibleTiers:        req.PriceVisibleTiers,
+		EmailDeliveryMode:        req.EmailDeliveryMode,
+		MetaTitle:                req.MetaTitle,
+		MetaDescription:          req.MetaDescription,
+		OGImage:                  req.OGImage,
+		ShipWithinDays:           req.ShipWithinDays,
+		AutoCancelHours:          req.AutoCancelHours,
+		ShippingAllowedCountries: req.ShippingAllowedCountries,
+		ShippingBlockedCountries: req.ShippingBlockedCountries,
+		HSCode:                   req.HSCode,
+		DeclaredValueMinor:       req.DeclaredValueMinor,
+		WeightGrams:              req.WeightGrams,
+		LengthMM:                 req.LengthMM,
+		WidthMM:                  req.WidthMM,
+		HeightMM:                 req.HeightMM,
+		OriginCountry:            req.OriginCountry,
 	}
 	if err := applyUpdateProductHookPayload(&patch, payload); err != nil {
 		return err
@@ -453,6 +650,10 @@ func applyCreateProductHookPayload(req *CreateProductRequest, payload map[string
 	req.OriginalPriceMinor = patch.OriginalPriceMinor
 	req.Stock = patch.Stock
 	req.MaxPurchaseLimit = patch.MaxPurchaseLimit
+	req.PriceTiers = patch.PriceTiers
+	req.MinOrderQuantity = patch.MinOrderQuantity
+	req.MaxOrderQuantity = patch.MaxOrderQuantity
+	req.QuantityIncrement = patch.QuantityIncrement
 	req.Images = patch.Images
 	req.Attributes = patch.Attributes
 	req.Status = patch.Status
@@ -461,6 +662,25 @@ func applyCreateProductHookPayload(req *CreateProductRequest, payload map[string
 	req.IsRecommended = patch.IsRecommended
 	req.Remark = patch.Remark
 	req.AutoDelivery = patch.AutoDelivery
+	req.ReserveOnCart = patch.ReserveOnCart
+	req.WaitingRoom = patch.WaitingRoom
+	req.PriceOnRequest = patch.PriceOnRequest
+	req.PriceVisibleTiers = patch.PriceVisibleTiers
+	req.EmailDeliveryMode = patch.EmailDeliveryMode
+	req.MetaTitle = patch.MetaTitle
+	req.MetaDescription = patch.MetaDescription
+	req.OGImage = patch.OGImage
+	req.ShipWithinDays = patch.ShipWithinDays
+	req.AutoCancelHours = patch.AutoCancelHours
+	req.ShippingAllowedCountries = patch.ShippingAllowedCountries
+	req.ShippingBlockedCountries = patch.ShippingBlockedCountries
+	req.HSCode = patch.HSCode
+	req.DeclaredValueMinor = patch.DeclaredValueMinor
+	req.WeightGrams = patch.WeightGrams
+	req.LengthMM = patch.LengthMM
+	req.WidthMM = patch.WidthMM
+	req.HeightMM = patch.HeightMM
+	req.OriginCountry = patch.OriginCountry
 	return nil
 }
 
@@ -512,6 +732,10 @@ type CreateProductRequest struct {
 	OriginalPriceMinor int64                     `json:"original_price_minor"`
 	Stock              int                       `json:"stock" binding:"gte=0"`
 	MaxPurchaseLimit   int                       `json:"max_purchase_limit" binding:"gte=0"` // 购买限制
+	PriceTiers         []models.ProductPriceTier `json:"price_tiers"`                        // 阶梯批发价
+	MinOrderQuantity   int                       `json:"min_order_quantity" binding:"gte=0"` // 单个订单项起订量
+	MaxOrderQuantity   int                       `json:"max_order_quantity" binding:"gte=0"` // 单笔订单上限
+	QuantityIncrement  int                       `json:"quantity_increment" binding:"gte=0"` // 包装倍数
 	Images             []models.ProductImage     `json:"images"`
 	Attributes         []models.ProductAttribute `json:"attributes"`
 	Status             models.ProductStatus      `json:"status"`
@@ -519,7 +743,29 @@ type CreateProductRequest struct {
 	IsFeatured         bool                      `json:"is_featured"`
 	IsRecommended      bool                      `json:"is_recommended"`
 	Remark             string                    `json:"remark"`
-	AutoDelivery       bool                      `json:"auto_delivery"` // 虚拟商品自动发货
+	AutoDelivery       bool                      `json:"auto_delivery"`       // 虚拟商品自动发货
+	ReserveOnCart      bool                      `json:"reserve_on_cart"`     // 加购即预留
+	WaitingRoom        bool                      `json:"waiting_room"`        // 抢购排队
+	PriceOnRequest     bool                      `json:"price_on_request"`    // 询价模式：隐藏价格，改为提交询价单
+	PriceVisibleTiers  []string                  `json:"price_visible_tiers"` // 询价模式下仍可见价下单的客户等级
+	EmailDeliveryMode  models.EmailDeliveryMode  `json:"email_delivery_mode" binding:"omitempty,oneof=none inline link"`
+	StoreID            *uint                     `json:"store_id"` // 所属店铺，为空表示所有店铺共享
+	MetaTitle          string                    `json:"meta_title"`
+	MetaDescription    string                    `json:"meta_description"`
+	OGImage            string                    `json:"og_image"`
+	ShipWithinDays     int                       `json:"ship_within_days" binding:"gte=0,lte=365"`  // 发货时效（天）
+	AutoCancelHours    int                       `json:"auto_cancel_hours" binding:"gte=0,lte=720"` // 未付款自动取消时限（小时）
+	// 配送限制国家（ISO 代码）
+	ShippingAllowedCountries []string `json:"shipping_allowed_countries"`
+	ShippingBlockedCountries []string `json:"shipping_blocked_countries"`
+	// 报关信息
+	HSCode             string `json:"hs_code"`
+	DeclaredValueMinor int64  `json:"declared_value_minor"`
+	WeightGrams        int    `json:"weight_grams"`
+	LengthMM           int    `json:"length_mm"`
+	WidthMM            int    `json:"width_mm"`
+	HeightMM           int    `json:"height_mm"`
+	OriginCountry      string `json:"origin_country"`
 }
 
 // CreateProduct CreateProduct
@@ -537,29 +783,55 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		response.BadRequest(c, "Invalid request parameters")
 		return
 	}
+	if !ensureAdminStoreAccess(c, req.StoreID) {
+		return
+	}
 	hookExecCtx := h.buildProductHookExecutionContext(c, adminID, 0)
 	if h.pluginManager != nil {
 		hookPayload := map[string]interface{}{
-			"admin_id":             adminID,
-			"sku":                  req.SKU,
-			"name":                 req.Name,
-			"product_code":         req.ProductCode,
-			"product_type":         req.ProductType,
-			"description":          req.Description,
-			"short_description":    req.ShortDescription,
-			"category":             req.Category,
-			"tags":                 req.Tags,
-			"price_minor":          req.PriceMinor,
-			"original_price_minor": req.OriginalPriceMinor,
-			"stock":                req.Stock,
-			"max_purchase_limit":   req.MaxPurchaseLimit,
-			"status":               req.Status,
-			"sort_order":           req.SortOrder,
-			"is_featured":          req.IsFeatured,
-			"is_recommended":       req.IsRecommended,
-			"remark":               req.Remark,
-			"auto_delivery":        req.AutoDelivery,
-			"source":               "admin_api",
+			"admin_id":                   adminID,
+			"sku":                        req.SKU,
+			"name":                       req.Name,
+			"product_code":               req.ProductCode,
+			"product_type":               req.ProductType,
+			"description":                req.Description,
+			"short_description":          req.ShortDescription,
+			"category":                   req.Category,
+			"tags":                       req.Tags,
+			"price_minor":                req.PriceMinor,
+			"original_price_minor":       req.OriginalPriceMinor,
+			"stock":                      req.Stock,
+			"max_purchase_limit":         req.MaxPurchaseLimit,
+			"price_tiers":                req.PriceTiers,
+			"min_order_quantity":         req.MinOrderQuantity,
+			"max_order_quantity":         req.MaxOrderQuantity,
+			"quantity_increment":         req.QuantityIncrement,
+			"status":                     req.Status,
+			"sort_order":                 req.SortOrder,
+			"is_featured":                req.IsFeatured,
+			"is_recommended":             req.IsRecommended,
+			"remark":                     req.Remark,
+			"auto_delivery":              req.AutoDelivery,
+			"reserve_on_cart":            req.ReserveOnCart,
+			"waiting_room":               req.WaitingRoom,
+			"price_on_request":           req.PriceOnRequest,
+			"price_visible_tiers":        req.PriceVisibleTiers,
+			"email_delivery_mode":        req.EmailDeliveryMode,
+			"meta_title":                 req.MetaTitle,
+			"meta_description":           req.MetaDescription,
+			"og_image":                   req.OGImage,
+			"ship_within_days":           req.ShipWithinDays,
+			"auto_cancel_hours":          req.AutoCancelHours,
+			"shipping_allowed_countries": req.ShippingAllowedCountries,
+			"shipping_blocked_countries": req.ShippingBlockedCountries,
+			"hs_code":                    req.HSCode,
+			"declared_value_minor":       req.DeclaredValueMinor,
+			"weight_grams":               req.WeightGrams,
+			"length_mm":                  req.LengthMM,
+			"width_mm":                   req.WidthMM,
+			"height_mm":                  req.HeightMM,
+			"origin_country":             req.OriginCountry,
+			"source":                     "admin_api",
 		}
 		hookResult, hookErr := h.pluginManager.ExecuteHook(service.HookExecutionRequest{
 			Hook:    "product.create.before",
@@ -587,26 +859,50 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	}
 
 	product := &models.Product{
-		SKU:              req.SKU,
-		Name:             req.Name,
-		ProductCode:      req.ProductCode,
-		ProductType:      req.ProductType,
-		Description:      req.Description,
-		ShortDescription: req.ShortDescription,
-		Category:         req.Category,
-		Tags:             req.Tags,
-		Price:            req.PriceMinor,
-		OriginalPrice:    req.OriginalPriceMinor,
-		Stock:            req.Stock,
-		MaxPurchaseLimit: req.MaxPurchaseLimit,
-		Images:           req.Images,
-		Attributes:       req.Attributes,
-		Status:           req.Status,
-		SortOrder:        req.SortOrder,
-		IsFeatured:       req.IsFeatured,
-		IsRecommended:    req.IsRecommended,
-		Remark:           req.Remark,
-		AutoDelivery:     req.AutoDelivery,
+		SKU:                      req.SKU,
+		Name:                     req.Name,
+		ProductCode:              req.ProductCode,
+		ProductType:              req.ProductType,
+		Description:              req.Description,
+		ShortDescription:         req.ShortDescription,
+		Category:                 req.Category,
+		Tags:                     req.Tags,
+		Price:                    req.PriceMinor,
+		OriginalPrice:            req.OriginalPriceMinor,
+		Stock:                    req.Stock,
+		MaxPurchaseLimit:         req.MaxPurchaseLimit,
+		PriceTiers:               req.PriceTiers,
+		MinOrderQuantity:         req.MinOrderQuantity,
+		MaxOrderQuantity:         req.MaxOrderQuantity,
+		QuantityIncrement:        req.QuantityIncrement,
+		Images:                   req.Images,
+		Attributes:               req.Attributes,
+		Status:                   req.Status,
+		SortOrder:                req.SortOrder,
+		IsFeatured:               req.IsFeatured,
+		IsRecommended:            req.IsRecommended,
+		Remark:                   req.Remark,
+		AutoDelivery:             req.AutoDelivery,
+		ReserveOnCart:            req.ReserveOnCart,
+		WaitingRoom:              req.WaitingRoom,
+		PriceOnRequest:           req.PriceOnRequest,
+		PriceVisibleTiers:        req.PriceVisibleTiers,
+		EmailDeliveryMode:        req.EmailDeliveryMode,
+		StoreID:                  req.StoreID,
+		MetaTitle:                req.MetaTitle,
+		MetaDescription:          req.MetaDescription,
+		OGImage:                  req.OGImage,
+		ShipWithinDays:           req.ShipWithinDays,
+		AutoCancelHours:          req.AutoCancelHours,
+		ShippingAllowedCountries: req.ShippingAllowedCountries,
+		ShippingBlockedCountries: req.ShippingBlockedCountries,
+		HSCode:                   req.HSCode,
+		DeclaredValueMinor:       req.DeclaredValueMinor,
+		WeightGrams:              req.WeightGrams,
+		LengthMM:                 req.LengthMM,
+		WidthMM:                  req.WidthMM,
+		HeightMM:                 req.HeightMM,
+		OriginCountry:            req.OriginCountry,
 	}
 
 	if err := h.productService.CreateProduct(product); err != nil {
@@ -662,6 +958,10 @@ type UpdateProductRequest struct {
 	OriginalPriceMinor int64                     `json:"original_price_minor"`
 	Stock              int                       `json:"stock"`
 	MaxPurchaseLimit   int                       `json:"max_purchase_limit"`
+	PriceTiers         []models.ProductPriceTier `json:"price_tiers"`
+	MinOrderQuantity   int                       `json:"min_order_quantity"`
+	MaxOrderQuantity   int                       `json:"max_order_quantity"`
+	QuantityIncrement  int                       `json:"quantity_increment"`
 	Images             []models.ProductImage     `json:"images"`
 	Attributes         []models.ProductAttribute `json:"attributes"`
 	Status             models.ProductStatus      `json:"status"`
@@ -669,7 +969,29 @@ type UpdateProductRequest struct {
 	IsFeatured         bool                      `json:"is_featured"`
 	IsRecommended      bool                      `json:"is_recommended"`
 	Remark             string                    `json:"remark"`
-	AutoDelivery       bool                      `json:"auto_delivery"` // 虚拟商品自动发货
+	AutoDelivery       bool                      `json:"auto_delivery"`       // 虚拟商品自动发货
+	ReserveOnCart      bool                      `json:"reserve_on_cart"`     // 加购即预留
+	WaitingRoom        bool                      `json:"waiting_room"`        // 抢购排队
+	PriceOnRequest     bool                      `json:"price_on_request"`    // 询价模式：隐藏价格，改为提交询价单
+	PriceVisibleTiers  []string                  `json:"price_visible_tiers"` // 询价模式下仍可见价下单的客户等级
+	EmailDeliveryMode  models.EmailDeliveryMode  `json:"email_delivery_mode" binding:"omitempty,oneof=none inline link"`
+	StoreID            *uint                     `json:"store_id"` // 所属店铺，为空表示所有店铺共享
+	MetaTitle          string                    `json:"meta_title"`
+	MetaDescription    string                    `json:"meta_description"`
+	OGImage            string                    `json:"og_image"`
+	ShipWithinDays     int                       `json:"ship_within_days" binding:"gte=0,lte=365"`  // 发货时效（天）
+	AutoCancelHours    int                       `json:"auto_cancel_hours" binding:"gte=0,lte=720"` // 未付款自动取消时限（小时）
+	// 配送限制国家（ISO 代码）
+	ShippingAllowedCountries []string `json:"shipping_allowed_countries"`
+	ShippingBlockedCountries []string `json:"shipping_blocked_countries"`
+	// 报关信息
+	HSCode             string `json:"hs_code"`
+	DeclaredValueMinor int64  `json:"declared_value_minor"`
+	WeightGrams        int    `json:"weight_grams"`
+	LengthMM           int    `json:"length_mm"`
+	WidthMM            int    `json:"width_mm"`
+	HeightMM           int    `json:"height_mm"`
+	OriginCountry      string `json:"origin_country"`
 }
 
 // UpdateProduct UpdateProduct
@@ -701,34 +1023,60 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		response.InternalServerError(c, "Failed to load product", err)
 		return
 	}
+	if !ensureAdminStoreAccess(c, currentProduct.StoreID) || !ensureAdminStoreAccess(c, req.StoreID) {
+		return
+	}
 	hookExecCtx := h.buildProductHookExecutionContext(c, adminID, currentProduct.ID)
 	if h.pluginManager != nil {
 		hookPayload := map[string]interface{}{
-			"admin_id":             adminID,
-			"product_id":           currentProduct.ID,
-			"sku_before":           currentProduct.SKU,
-			"name_before":          currentProduct.Name,
-			"status_before":        currentProduct.Status,
-			"stock_before":         currentProduct.Stock,
-			"sku":                  req.SKU,
-			"name":                 req.Name,
-			"product_code":         req.ProductCode,
-			"product_type":         req.ProductType,
-			"description":          req.Description,
-			"short_description":    req.ShortDescription,
-			"category":             req.Category,
-			"tags":                 req.Tags,
-			"price_minor":          req.PriceMinor,
-			"original_price_minor": req.OriginalPriceMinor,
-			"stock":                req.Stock,
-			"max_purchase_limit":   req.MaxPurchaseLimit,
-			"status":               req.Status,
-			"sort_order":           req.SortOrder,
-			"is_featured":          req.IsFeatured,
-			"is_recommended":       req.IsRecommended,
-			"remark":               req.Remark,
-			"auto_delivery":        req.AutoDelivery,
-			"source":               "admin_api",
+			"admin_id":                   adminID,
+			"product_id":                 currentProduct.ID,
+			"sku_before":                 currentProduct.SKU,
+			"name_before":                currentProduct.Name,
+			"status_before":              currentProduct.Status,
+			"stock_before":               currentProduct.Stock,
+			"sku":                        req.SKU,
+			"name":                       req.Name,
+			"product_code":               req.ProductCode,
+			"product_type":               req.ProductType,
+			"description":                req.Description,
+			"short_description":          req.ShortDescription,
+			"category":                   req.Category,
+			"tags":                       req.Tags,
+			"price_minor":                req.PriceMinor,
+			"original_price_minor":       req.OriginalPriceMinor,
+			"stock":                      req.Stock,
+			"max_purchase_limit":         req.MaxPurchaseLimit,
+			"price_tiers":                req.PriceTiers,
+			"min_order_quantity":         req.MinOrderQuantity,
+			"max_order_quantity":         req.MaxOrderQuantity,
+			"quantity_increment":         req.QuantityIncrement,
+			"status":                     req.Status,
+			"sort_order":                 req.SortOrder,
+			"is_featured":                req.IsFeatured,
+			"is_recommended":             req.IsRecommended,
+			"remark":                     req.Remark,
+			"auto_delivery":              req.AutoDelivery,
+			"reserve_on_cart":            req.ReserveOnCart,
+			"waiting_room":               req.WaitingRoom,
+			"price_on_request":           req.PriceOnRequest,
+			"price_visible_tiers":        req.PriceVisibleTiers,
+			"email_delivery_mode":        req.EmailDeliveryMode,
+			"meta_title":                 req.MetaTitle,
+			"meta_description":           req.MetaDescription,
+			"og_image":                   req.OGImage,
+			"ship_within_days":           req.ShipWithinDays,
+			"auto_cancel_hours":          req.AutoCancelHours,
+			"shipping_allowed_countries": req.ShippingAllowedCountries,
+			"shipping_blocked_countries": req.ShippingBlockedCountries,
+			"hs_code":                    req.HSCode,
+			"declared_value_minor":       req.DeclaredValueMinor,
+			"weight_grams":               req.WeightGrams,
+			"length_mm":                  req.LengthMM,
+			"width_mm":                   req.WidthMM,
+			"height_mm":                  req.HeightMM,
+			"origin_country":             req.OriginCountry,
+			"source":                     "admin_api",
 		}
 		hookResult, hookErr := h.pluginManager.ExecuteHook(service.HookExecutionRequest{
 			Hook:    "product.update.before",
@@ -755,36 +1103,82 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		}
 	}
 
+	var approval *adminApprovalGrant
+	if service.PriceChangeRequiresApproval(currentProduct.Price, req.PriceMinor) {
+		grant, ok := requireAdminApproval(c, service.AdminApprovalRequest{
+			ActionType:   models.AdminApprovalActionProductPrice,
+			ResourceType: "product",
+			ResourceID:   strconv.FormatUint(uint64(currentProduct.ID), 10),
+			Summary:      fmt.Sprintf("Change product %s price from %s to %s", currentProduct.SKU, money.MinorToString(currentProduct.Price), money.MinorToString(req.PriceMinor)),
+			Payload: map[string]interface{}{
+				"sku":             currentProduct.SKU,
+				"old_price_minor": currentProduct.Price,
+				"new_price_minor": req.PriceMinor,
+			},
+			RequestedBy: adminID,
+		})
+		if !ok {
+			return
+		}
+		approval = grant
+		defer approval.release()
+	}
+
 	updates := &models.Product{
-		SKU:              req.SKU,
-		Name:             req.Name,
-		ProductCode:      req.ProductCode,
-		ProductType:      req.ProductType,
-		Description:      req.Description,
-		ShortDescription: req.ShortDescription,
-		Category:         req.Category,
-		Tags:             req.Tags,
-		Price:            req.PriceMinor,
-		OriginalPrice:    req.OriginalPriceMinor,
-		Stock:            req.Stock,
-		MaxPurchaseLimit: req.MaxPurchaseLimit,
-		Images:           req.Images,
-		Attributes:       req.Attributes,
-		Status:           req.Status,
-		SortOrder:        req.SortOrder,
-		IsFeatured:       req.IsFeatured,
-		IsRecommended:    req.IsRecommended,
-		Remark:           req.Remark,
-		AutoDelivery:     req.AutoDelivery,
-	}
-
-	if err := h.productService.UpdateProduct(uint(productID), updates); err != nil {
+		SKU:                      req.SKU,
+		Name:                     req.Name,
+		ProductCode:              req.ProductCode,
+		ProductType:              req.ProductType,
+		Description:              req.Description,
+		ShortDescription:         req.ShortDescription,
+		Category:                 req.Category,
+		Tags:                     req.Tags,
+		Price:                    req.PriceMinor,
+		OriginalPrice:            req.OriginalPriceMinor,
+		Stock:                    req.Stock,
+		MaxPurchaseLimit:         req.MaxPurchaseLimit,
+		PriceTiers:               req.PriceTiers,
+		MinOrderQuantity:         req.MinOrderQuantity,
+		MaxOrderQuantity:         req.MaxOrderQuantity,
+		QuantityIncrement:        req.QuantityIncrement,
+		Images:                   req.Images,
+		Attributes:               req.Attributes,
+		Status:                   req.Status,
+		SortOrder:                req.SortOrder,
+		IsFeatured:               req.IsFeatured,
+		IsRecommended:            req.IsRecommended,
+		Remark:                   req.Remark,
+		AutoDelivery:             req.AutoDelivery,
+		ReserveOnCart:            req.ReserveOnCart,
+		WaitingRoom:              req.WaitingRoom,
+		PriceOnRequest:           req.PriceOnRequest,
+		PriceVisibleTiers:        req.PriceVisibleTiers,
+		EmailDeliveryMode:        req.EmailDeliveryMode,
+		StoreID:                  req.StoreID,
+		MetaTitle:                req.MetaTitle,
+		MetaDescription:          req.MetaDescription,
+		OGImage:                  req.OGImage,
+		ShipWithinDays:           req.ShipWithinDays,
+		AutoCancelHours:          req.AutoCancelHours,
+		ShippingAllowedCountries: req.ShippingAllowedCountries,
+		ShippingBlockedCountries: req.ShippingBlockedCountries,
+		HSCode:                   req.HSCode,
+		DeclaredValueMinor:       req.DeclaredValueMinor,
+		WeightGrams:              req.WeightGrams,
+		LengthMM:                 req.LengthMM,
+		WidthMM:                  req.WidthMM,
+		HeightMM:                 req.HeightMM,
+		OriginCountry:            req.OriginCountry,
+	}
+
+	if err := h.productService.UpdateProductWithOptions(uint(productID), updates, service.UpdateProductOptions{ChangedBy: &adminID}); err != nil {
 		if respondProductServiceError(c, err) {
 			return
 		}
 		response.BadRequest(c, err.Error())
 		return
 	}
+	approval.complete()
 
 	product, _ := h.productService.GetProductByID(uint(productID), false)
 	if h.pluginManager != nil && product != nil {
@@ -843,6 +1237,9 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		response.NotFound(c, service.ErrProductNotFound.Error())
 		return
 	}
+	if !ensureAdminStoreAccess(c, product.StoreID) {
+		return
+	}
 	hookExecCtx := h.buildProductHookExecutionContext(c, adminID, product.ID)
 	deleteOptions := service.DeleteProductOptions{DeleteImages: true}
 	if h.pluginManager != nil {
@@ -937,10 +1334,14 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		response.InternalServerError(c, "Failed to load product", err)
 		return
 	}
+	if !ensureAdminStoreAccess(c, product.StoreID) {
+		return
+	}
 
 	// 构建响应：ProductInfo + 简化的绑定关系
 	productResponse := map[string]interface{}{
 		"id":                   product.ID,
+		"store_id":             product.StoreID,
 		"sku":                  product.SKU,
 		"name":                 product.Name,
 		"product_code":         product.ProductCode,
@@ -953,6 +1354,10 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		"original_price_minor": product.OriginalPrice,
 		"stock":                product.Stock,
 		"max_purchase_limit":   product.MaxPurchaseLimit,
+		"price_tiers":          product.PriceTiers,
+		"min_order_quantity":   product.MinOrderQuantity,
+		"max_order_quantity":   product.MaxOrderQuantity,
+		"quantity_increment":   product.QuantityIncrement,
 		"images":               product.Images,
 		"attributes":           product.Attributes,
 		"status":               product.Status,
@@ -961,6 +1366,11 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		"is_recommended":       product.IsRecommended,
 		"remark":               product.Remark,
 		"auto_delivery":        product.AutoDelivery,
+		"reserve_on_cart":      product.ReserveOnCart,
+		"waiting_room":         product.WaitingRoom,
+		"price_on_request":     product.PriceOnRequest,
+		"price_visible_tiers":  product.PriceVisibleTiers,
+		"email_delivery_mode":  product.EmailDeliveryMode,
 		"inventory_mode":       product.InventoryMode,
 		"view_count":           product.ViewCount,
 		"sale_count":           product.SaleCount,
@@ -1023,6 +1433,10 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 func (h *ProductHandler) ListProducts(c *gin.Context) {
 	page, limit := response.GetPagination(c)
 	filters := parseProductListFilters(c)
+	storeScope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
 
 	products, total, err := h.productService.ListProducts(
 		page,
@@ -1033,6 +1447,7 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 		filters.IsFeatured,
 		nil,
 		false,
+		storeScope,
 	)
 	if err != nil {
 		response.InternalError(c, "Query failed")
@@ -1045,6 +1460,10 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 // ExportProducts 导出商品列表
 func (h *ProductHandler) ExportProducts(c *gin.Context) {
 	filters := parseProductListFilters(c)
+	storeScope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
 
 	products, total, err := h.productService.ListProducts(
 		1,
@@ -1055,6 +1474,7 @@ func (h *ProductHandler) ExportProducts(c *gin.Context) {
 		filters.IsFeatured,
 		nil,
 		false,
+		storeScope,
 	)
 	if err != nil {
 		response.InternalError(c, "Query failed")
@@ -1184,6 +1604,9 @@ func (h *ProductHandler) UpdateProductStatus(c *gin.Context) {
 		response.InternalServerError(c, "Failed to load product", err)
 		return
 	}
+	if !ensureAdminStoreAccess(c, product.StoreID) {
+		return
+	}
 	beforeStatus := product.Status
 	hookExecCtx := h.buildProductHookExecutionContext(c, adminID, product.ID)
 	if h.pluginManager != nil {
@@ -1276,6 +1699,10 @@ func (h *ProductHandler) UpdateStock(c *gin.Context) {
 		return
 	}
 
+	if !ensureAdminProductStoreAccess(c, uint(productID)) {
+		return
+	}
+
 	if err := h.productService.UpdateStock(uint(productID), req.Stock); err != nil {
 		if respondProductServiceError(c, err) {
 			return
@@ -1296,6 +1723,10 @@ func (h *ProductHandler) ToggleFeatured(c *gin.Context) {
 		return
 	}
 
+	if !ensureAdminProductStoreAccess(c, uint(productID)) {
+		return
+	}
+
 	if err := h.productService.ToggleFeatured(uint(productID)); err != nil {
 		if respondProductServiceError(c, err) {
 			return
@@ -1354,6 +1785,9 @@ func (h *ProductHandler) UpdateInventoryMode(c *gin.Context) {
 		response.InternalServerError(c, "Failed to load product", err)
 		return
 	}
+	if !ensureAdminStoreAccess(c, product.StoreID) {
+		return
+	}
 	beforeMode := product.InventoryMode
 	hookExecCtx := h.buildProductHookExecutionContext(c, adminID, product.ID)
 	if h.pluginManager != nil {
@@ -0,0 +1,138 @@
+package admin
+
+import (
+	"strconv"
+	"time"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SetPriceService 注入商品价格历史/定时调价服务
+func (h *ProductHandler) SetPriceService(priceService *service.ProductPriceService) {
+	h.priceService = priceService
+}
+
+// loadProductForPrice 解析商品ID并校验店铺权限
+func (h *ProductHandler) loadProductForPrice(c *gin.Context) (*models.Product, bool) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid product ID format")
+		return nil, false
+	}
+	if h.priceService == nil {
+		response.InternalError(c, "Product price service unavailable")
+		return nil, false
+	}
+	product, err := h.productService.GetProductByID(uint(productID), false)
+	if err != nil {
+		if respondProductServiceError(c, err) {
+			return nil, false
+		}
+		response.InternalServerError(c, "Failed to load product", err)
+		return nil, false
+	}
+	if !ensureAdminStoreAccess(c, product.StoreID) {
+		return nil, false
+	}
+	return product, true
+}
+
+// ListPriceHistory 商品价格变更记录
+func (h *ProductHandler) ListPriceHistory(c *gin.Context) {
+	product, ok := h.loadProductForPrice(c)
+	if !ok {
+		return
+	}
+	page, limit := response.GetPagination(c)
+	items, total, err := h.priceService.ListHistory(product.ID, page, limit)
+	if err != nil {
+		response.InternalServerError(c, "Query failed", err)
+		return
+	}
+	response.Paginated(c, items, page, limit, total)
+}
+
+// ListPriceSchedules 商品定时调价列表
+func (h *ProductHandler) ListPriceSchedules(c *gin.Context) {
+	product, ok := h.loadProductForPrice(c)
+	if !ok {
+		return
+	}
+	items, err := h.priceService.ListSchedules(product.ID)
+	if err != nil {
+		response.InternalServerError(c, "Query failed", err)
+		return
+	}
+	response.Success(c, gin.H{"items": items})
+}
+
+// CreatePriceSchedule 创建定时调价
+func (h *ProductHandler) CreatePriceSchedule(c *gin.Context) {
+	var req struct {
+		PriceMinor         int64     `json:"price_minor"`
+		OriginalPriceMinor *int64    `json:"original_price_minor"`
+		EffectiveAt        time.Time `json:"effective_at" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	product, ok := h.loadProductForPrice(c)
+	if !ok {
+		return
+	}
+
+	var createdBy *uint
+	if adminID, ok := middleware.GetUserID(c); ok && adminID != 0 {
+		createdBy = &adminID
+	}
+	schedule, err := h.priceService.CreateSchedule(product.ID, req.PriceMinor, req.OriginalPriceMinor, req.EffectiveAt, createdBy)
+	if err != nil {
+		if respondProductServiceError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to create price schedule", err)
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create_price_schedule", "product", &product.ID, map[string]interface{}{
+		"schedule_id":          schedule.ID,
+		"price_minor":          schedule.PriceMinor,
+		"original_price_minor": schedule.OriginalPriceMinor,
+		"effective_at":         schedule.EffectiveAt,
+	})
+	response.Success(c, schedule)
+}
+
+// CancelPriceSchedule 取消尚未生效的定时调价
+func (h *ProductHandler) CancelPriceSchedule(c *gin.Context) {
+	scheduleID, err := strconv.ParseUint(c.Param("scheduleId"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid schedule ID format")
+		return
+	}
+	product, ok := h.loadProductForPrice(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.priceService.CancelSchedule(product.ID, uint(scheduleID))
+	if err != nil {
+		if respondProductServiceError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to cancel price schedule", err)
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "cancel_price_schedule", "product", &product.ID, map[string]interface{}{
+		"schedule_id": schedule.ID,
+	})
+	response.Success(c, schedule)
+}
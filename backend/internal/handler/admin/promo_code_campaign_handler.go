@@ -0,0 +1,213 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePromoCodeCampaignRequest 批量生成优惠码活动请求
+type CreatePromoCodeCampaignRequest struct {
+	Name                string              `json:"name" binding:"required"`
+	Description         string              `json:"description"`
+	Prefix              string              `json:"prefix"`
+	Pattern             string              `json:"pattern"`
+	Quantity            int                 `json:"quantity" binding:"required"`
+	DiscountType        models.DiscountType `json:"discount_type" binding:"required"`
+	DiscountValueMinor  int64               `json:"discount_value_minor" binding:"required,gt=0"`
+	MaxDiscountMinor    int64               `json:"max_discount_minor"`
+	MinOrderAmountMinor int64               `json:"min_order_amount_minor"`
+	ProductIDs          []uint              `json:"product_ids"`
+	ProductScope        string              `json:"product_scope"`
+	ExpiresAt           *string             `json:"expires_at"`
+}
+
+type promoCodeCampaignListItem struct {
+	models.PromoCodeCampaign
+	RedeemedCount int64 `json:"redeemed_count"`
+	ReservedCount int64 `json:"reserved_count"`
+}
+
+// MarshalJSON 嵌入的活动自带 MarshalJSON，需要手动合并统计字段
+func (i promoCodeCampaignListItem) MarshalJSON() ([]byte, error) {
+	encoded, err := json.Marshal(i.PromoCodeCampaign)
+	if err != nil {
+		return nil, err
+	}
+	payload := map[string]interface{}{}
+	if err := json.Unmarshal(encoded, &payload); err != nil {
+		return nil, err
+	}
+	payload["redeemed_count"] = i.RedeemedCount
+	payload["reserved_count"] = i.ReservedCount
+	return json.Marshal(payload)
+}
+
+// CreatePromoCodeCampaign 创建活动并批量生成一次性优惠码
+func (h *PromoCodeHandler) CreatePromoCodeCampaign(c *gin.Context) {
+	var req CreatePromoCodeCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	expiresAt, err := parsePromoCodeExpiryInput(req.ExpiresAt)
+	if err != nil {
+		response.BadRequest(c, "Invalid expiry date format")
+		return
+	}
+
+	campaign := &models.PromoCodeCampaign{
+		Name:           req.Name,
+		Description:    req.Description,
+		Prefix:         req.Prefix,
+		Pattern:        req.Pattern,
+		DiscountType:   req.DiscountType,
+		DiscountValue:  req.DiscountValueMinor,
+		MaxDiscount:    req.MaxDiscountMinor,
+		MinOrderAmount: req.MinOrderAmountMinor,
+		ProductIDs:     req.ProductIDs,
+		ProductScope:   req.ProductScope,
+		ExpiresAt:      expiresAt,
+		CreatedBy:      getOptionalUserID(c),
+	}
+
+	promoCodes, err := h.promoCodeService.CreateCampaign(campaign, req.Quantity)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to create promo code campaign", err)
+		return
+	}
+
+	if h.db != nil {
+		logger.LogOperation(h.db, c, "create", "promo_code_campaign", &campaign.ID, map[string]interface{}{
+			"name":     campaign.Name,
+			"prefix":   campaign.Prefix,
+			"pattern":  campaign.Pattern,
+			"quantity": len(promoCodes),
+		})
+	}
+
+	response.Success(c, campaign)
+}
+
+// ListPromoCodeCampaigns 活动列表
+func (h *PromoCodeHandler) ListPromoCodeCampaigns(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+
+	campaigns, stats, total, err := h.promoCodeService.ListCampaigns(page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+
+	items := make([]promoCodeCampaignListItem, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		stat := stats[campaign.ID]
+		items = append(items, promoCodeCampaignListItem{
+			PromoCodeCampaign: campaign,
+			RedeemedCount:     stat.RedeemedCount,
+			ReservedCount:     stat.ReservedCount,
+		})
+	}
+
+	response.Paginated(c, items, page, limit, total)
+}
+
+// promoCodeCampaignCodeStatus 导出用的单码状态
+func promoCodeCampaignCodeStatus(promoCode *models.PromoCode, redemption *models.PromoCodeRedemption) string {
+	if redemption != nil {
+		return string(redemption.Status)
+	}
+	if promoCode.UsedQuantity > 0 {
+		return string(models.PromoCodeRedemptionRedeemed)
+	}
+	if promoCode.ReservedQuantity > 0 {
+		return string(models.PromoCodeRedemptionReserved)
+	}
+	return "unused"
+}
+
+// ExportPromoCodeCampaign 导出活动优惠码 CSV（用于分发和核对使用情况）
+func (h *PromoCodeHandler) ExportPromoCodeCampaign(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid ID")
+		return
+	}
+
+	campaign, promoCodes, redemptions, err := h.promoCodeService.GetCampaignCodes(uint(id))
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Query failed")
+		return
+	}
+
+	rows := make([][]string, 0, len(promoCodes))
+	for i := range promoCodes {
+		promoCode := &promoCodes[i]
+		var redemption *models.PromoCodeRedemption
+		if item, ok := redemptions[promoCode.ID]; ok {
+			redemption = &item
+		}
+		orderNo := ""
+		var redeemedAt *time.Time
+		if redemption != nil {
+			orderNo = redemption.OrderNo
+			redeemedAt = redemption.RedeemedAt
+		}
+		rows = append(rows, []string{
+			promoCode.Code,
+			promoCodeCampaignCodeStatus(promoCode, redemption),
+			orderNo,
+			csvTimePtrValue(redeemedAt),
+			csvTimePtrValue(promoCode.ExpiresAt),
+		})
+	}
+
+	if h.db != nil {
+		logger.LogOperation(h.db, c, "export", "promo_code_campaign", &campaign.ID, map[string]interface{}{
+			"count":  len(rows),
+			"format": "csv",
+		})
+	}
+
+	writeCSVAttachment(c, buildAdminCSVFileName(fmt.Sprintf("promo_campaign_%d", campaign.ID)), []string{
+		"Code",
+		"Status",
+		"Order No.",
+		"Redeemed At",
+		"Expires At",
+	}, rows)
+}
+
+// ListPromoCodeRedemptions 单个优惠码的使用记录
+func (h *PromoCodeHandler) ListPromoCodeRedemptions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid ID")
+		return
+	}
+	page, limit := response.GetPagination(c)
+
+	items, total, err := h.promoCodeService.ListRedemptions(uint(id), page, limit)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Query failed")
+		return
+	}
+
+	response.Paginated(c, items, page, limit, total)
+}
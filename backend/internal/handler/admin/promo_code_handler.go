@@ -44,6 +44,7 @@ type CreatePromoCodeRequest struct {
 	ProductScope        string              `json:"product_scope"`
 	Status              string              `json:"status"`
 	ExpiresAt           *string             `json:"expires_at"`
+	AutoApply           bool                `json:"auto_apply"`
 }
 
 // UpdatePromoCodeRequest 更新优惠码请求（不需要code字段）
@@ -59,6 +60,7 @@ type UpdatePromoCodeRequest struct {
 	ProductScope        string              `json:"product_scope"`
 	Status              string              `json:"status"`
 	ExpiresAt           *string             `json:"expires_at"`
+	AutoApply           bool                `json:"auto_apply"`
 }
 
 type promoCodeImportResult struct {
@@ -510,6 +512,7 @@ func (h *PromoCodeHandler) CreatePromoCode(c *gin.Context) {
 		ProductIDs:     req.ProductIDs,
 		ProductScope:   req.ProductScope,
 		Status:         models.PromoCodeStatusActive,
+		AutoApply:      req.AutoApply,
 	}
 
 	if req.Status != "" {
@@ -643,6 +646,7 @@ func (h *PromoCodeHandler) UpdatePromoCode(c *gin.Context) {
 		ProductIDs:     req.ProductIDs,
 		ProductScope:   req.ProductScope,
 		Status:         models.PromoCodeStatusActive,
+		AutoApply:      req.AutoApply,
 	}
 
 	if req.Status != "" {
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"strconv"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type QuoteRequestHandler struct {
+	quoteRequestService *service.QuoteRequestService
+}
+
+func NewQuoteRequestHandler(quoteRequestService *service.QuoteRequestService) *QuoteRequestHandler {
+	return &QuoteRequestHandler{quoteRequestService: quoteRequestService}
+}
+
+func parseQuoteRequestID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *QuoteRequestHandler) respondQuoteRequestError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// ListQuoteRequests 询价单列表，支持按状态和关键字筛选
+func (h *QuoteRequestHandler) ListQuoteRequests(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+	items, total, err := h.quoteRequestService.List(service.QuoteRequestListFilter{
+		Status: c.Query("status"),
+		Search: c.Query("search"),
+	}, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, items, page, limit, total)
+}
+
+// GetQuoteRequest 询价单详情
+func (h *QuoteRequestHandler) GetQuoteRequest(c *gin.Context) {
+	id, ok := parseQuoteRequestID(c)
+	if !ok {
+		return
+	}
+	quote, err := h.quoteRequestService.Get(id)
+	if err != nil {
+		h.respondQuoteRequestError(c, err, "Failed to load quote request")
+		return
+	}
+	response.Success(c, quote)
+}
+
+// QuoteRequest 给出报价（未被客户确认前可重新报价）
+func (h *QuoteRequestHandler) QuoteRequest(c *gin.Context) {
+	id, ok := parseQuoteRequestID(c)
+	if !ok {
+		return
+	}
+	var req service.QuoteOfferInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	quote, err := h.quoteRequestService.Quote(id, adminID, req)
+	if err != nil {
+		h.respondQuoteRequestError(c, err, "Failed to quote")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "quote", "quote_request", &quote.ID, map[string]interface{}{
+		"quote_no":                quote.QuoteNo,
+		"quoted_unit_price_minor": quote.QuotedUnitPriceMinor,
+		"valid_until":             quote.ValidUntil,
+	})
+	response.Success(c, quote)
+}
+
+// CloseQuoteRequest 关闭询价单
+func (h *QuoteRequestHandler) CloseQuoteRequest(c *gin.Context) {
+	id, ok := parseQuoteRequestID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Reply string `json:"reply"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	quote, err := h.quoteRequestService.Close(id, req.Reply)
+	if err != nil {
+		h.respondQuoteRequestError(c, err, "Failed to close quote request")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "close", "quote_request", &quote.ID, map[string]interface{}{
+		"quote_no": quote.QuoteNo,
+	})
+	response.Success(c, quote)
+}
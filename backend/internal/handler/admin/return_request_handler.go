@@ -0,0 +1,179 @@
+package admin
+
+import (
+	"strconv"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ReturnRequestHandler struct {
+	returnRequestService *service.ReturnRequestService
+}
+
+func NewReturnRequestHandler(returnRequestService *service.ReturnRequestService) *ReturnRequestHandler {
+	return &ReturnRequestHandler{returnRequestService: returnRequestService}
+}
+
+func (h *ReturnRequestHandler) respondReturnRequestError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// loadReturnRequest 读取退货申请并校验管理员的店铺权限
+func (h *ReturnRequestHandler) loadReturnRequest(c *gin.Context) (*models.ReturnRequest, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return nil, false
+	}
+	request, err := h.returnRequestService.Get(uint(id))
+	if err != nil {
+		h.respondReturnRequestError(c, err, "Failed to load return request")
+		return nil, false
+	}
+	if !ensureAdminStoreAccess(c, request.StoreID) {
+		return nil, false
+	}
+	return request, true
+}
+
+// ListReturnRequests 退货申请列表，支持按状态和单号筛选
+func (h *ReturnRequestHandler) ListReturnRequests(c *gin.Context) {
+	scope, ok := resolveAdminStoreScope(c)
+	if !ok {
+		return
+	}
+	page, limit := response.GetPagination(c)
+	items, total, err := h.returnRequestService.List(service.ReturnRequestListFilter{
+		Status:     c.Query("status"),
+		Search:     c.Query("search"),
+		StoreScope: scope,
+	}, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, items, page, limit, total)
+}
+
+// GetReturnRequest 退货申请详情
+func (h *ReturnRequestHandler) GetReturnRequest(c *gin.Context) {
+	request, ok := h.loadReturnRequest(c)
+	if !ok {
+		return
+	}
+	response.Success(c, request)
+}
+
+// ReviewReturnRequest 审核备注
+type ReviewReturnRequest struct {
+	Remark string `json:"remark"`
+}
+
+// ApproveReturnRequest 同意退货并生成退货单号
+func (h *ReturnRequestHandler) ApproveReturnRequest(c *gin.Context) {
+	h.review(c, true)
+}
+
+// RejectReturnRequest 拒绝退货申请
+func (h *ReturnRequestHandler) RejectReturnRequest(c *gin.Context) {
+	h.review(c, false)
+}
+
+func (h *ReturnRequestHandler) review(c *gin.Context, approve bool) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	request, ok := h.loadReturnRequest(c)
+	if !ok {
+		return
+	}
+	var req ReviewReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	action := "reject"
+	var err error
+	if approve {
+		action = "approve"
+		request, err = h.returnRequestService.Approve(request.ID, adminID, req.Remark)
+	} else {
+		request, err = h.returnRequestService.Reject(request.ID, adminID, req.Remark)
+	}
+	if err != nil {
+		h.respondReturnRequestError(c, err, "Failed to review return request")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, action, "return_request", &request.ID, map[string]interface{}{
+		"rma_no":             request.RMANo,
+		"order_no":           request.OrderNo,
+		"return_tracking_no": request.ReturnTrackingNo,
+		"remark":             request.AdminRemark,
+	})
+	response.Success(c, request)
+}
+
+// ReceiveReturnItemsRequest 登记收货，restock 默认为 true
+type ReceiveReturnItemsRequest struct {
+	Restock           *bool `json:"restock"`
+	InvalidateSerials bool  `json:"invalidate_serials"`
+}
+
+// ReceiveReturnRequest 收到客户寄回的商品，按申请数量回补绑定库存
+func (h *ReturnRequestHandler) ReceiveReturnRequest(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	request, ok := h.loadReturnRequest(c)
+	if !ok {
+		return
+	}
+	var req ReceiveReturnItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	operator := "unknown"
+	if email, ok := c.Get("user_email"); ok {
+		if value, ok := email.(string); ok {
+			operator = value
+		}
+	}
+	restock := req.Restock == nil || *req.Restock
+	request, result, err := h.returnRequestService.Receive(request.ID, service.ReturnReceiveInput{
+		Restock:           restock,
+		InvalidateSerials: req.InvalidateSerials,
+		Operator:          operator,
+		OperatorID:        adminID,
+	})
+	if err != nil {
+		h.respondReturnRequestError(c, err, "Failed to record return")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "receive", "return_request", &request.ID, map[string]interface{}{
+		"rma_no":              request.RMANo,
+		"order_no":            request.OrderNo,
+		"restock":             restock,
+		"restocked_quantity":  result.RestockedQuantity,
+		"invalidated_serials": result.InvalidatedSerials,
+	})
+	response.Success(c, gin.H{
+		"return_request": request,
+		"result":         result,
+	})
+}
@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SavedViewHandler struct {
+	savedViewService *service.AdminSavedViewService
+}
+
+func NewSavedViewHandler(savedViewService *service.AdminSavedViewService) *SavedViewHandler {
+	return &SavedViewHandler{savedViewService: savedViewService}
+}
+
+func parseSavedViewID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *SavedViewHandler) respondSavedViewError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// GetFilterFields 高级筛选可用字段及运算符
+func (h *SavedViewHandler) GetFilterFields(c *gin.Context) {
+	schema, err := service.ValidateAdminSavedViewResource(strings.TrimSpace(c.Query("resource")))
+	if err != nil {
+		h.respondSavedViewError(c, err, "Failed to load filter fields")
+		return
+	}
+	response.Success(c, gin.H{"fields": schema.Fields()})
+}
+
+// ListSavedViews 当前管理员在某个列表下的视图
+func (h *SavedViewHandler) ListSavedViews(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	views, err := h.savedViewService.List(adminID, strings.TrimSpace(c.Query("resource")))
+	if err != nil {
+		h.respondSavedViewError(c, err, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": views})
+}
+
+// CreateSavedView 保存视图
+func (h *SavedViewHandler) CreateSavedView(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	var req service.AdminSavedViewInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	view, err := h.savedViewService.Create(adminID, req)
+	if err != nil {
+		h.respondSavedViewError(c, err, "Failed to save view")
+		return
+	}
+	response.Success(c, view)
+}
+
+// UpdateSavedView 更新视图
+func (h *SavedViewHandler) UpdateSavedView(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseSavedViewID(c)
+	if !ok {
+		return
+	}
+	var req service.AdminSavedViewInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	view, err := h.savedViewService.Update(adminID, id, req)
+	if err != nil {
+		h.respondSavedViewError(c, err, "Failed to update view")
+		return
+	}
+	response.Success(c, view)
+}
+
+// SetDefaultSavedView 设为默认视图
+func (h *SavedViewHandler) SetDefaultSavedView(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseSavedViewID(c)
+	if !ok {
+		return
+	}
+	view, err := h.savedViewService.SetDefault(adminID, id)
+	if err != nil {
+		h.respondSavedViewError(c, err, "Failed to set default view")
+		return
+	}
+	response.Success(c, view)
+}
+
+// DeleteSavedView 删除视图
+func (h *SavedViewHandler) DeleteSavedView(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseSavedViewID(c)
+	if !ok {
+		return
+	}
+	if err := h.savedViewService.Delete(adminID, id); err != nil {
+		h.respondSavedViewError(c, err, "Failed to delete view")
+		return
+	}
+	response.Success(c, gin.H{"message": "View deleted"})
+}
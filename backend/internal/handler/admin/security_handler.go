@@ -0,0 +1,283 @@
+package admin
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"auralogic/internal/config"
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/utils"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// maxCSPReportBodyBytes CSP 上报请求体上限
+const maxCSPReportBodyBytes = 64 << 10
+
+type SecurityHandler struct {
+	loginProtection   *service.LoginProtectionService
+	cspReports        *service.CSPReportService
+	scraperProtection *service.ScraperProtectionService
+}
+
+func NewSecurityHandler(loginProtection *service.LoginProtectionService, cspReports *service.CSPReportService, scraperProtection *service.ScraperProtectionService) *SecurityHandler {
+	return &SecurityHandler{loginProtection: loginProtection, cspReports: cspReports, scraperProtection: scraperProtection}
+}
+
+// ReviewSecurityEventRequest 确认安全事件
+type ReviewSecurityEventRequest struct {
+	Note string `json:"note"`
+}
+
+func parseSecurityID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ListEvents 安全事件列表
+func (h *SecurityHandler) ListEvents(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+	filter := service.SecurityEventFilter{
+		EventType:     strings.TrimSpace(c.Query("event_type")),
+		Severity:      strings.TrimSpace(c.Query("severity")),
+		IPAddress:     strings.TrimSpace(c.Query("ip_address")),
+		Email:         strings.TrimSpace(c.Query("email")),
+		PendingReview: c.Query("pending_review") == "true",
+	}
+	events, total, err := h.loginProtection.ListEvents(filter, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, events, page, limit, total)
+}
+
+// ReviewEvent 确认可疑登录等事件
+func (h *SecurityHandler) ReviewEvent(c *gin.Context) {
+	id, ok := parseSecurityID(c)
+	if !ok {
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	var req ReviewSecurityEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	event, err := h.loginProtection.ReviewEvent(id, adminID, req.Note)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to review security event", err)
+		return
+	}
+	logger.LogOperation(database.GetDB(), c, "review", "security_event", &event.ID, map[string]interface{}{
+		"event_type": event.EventType,
+		"note":       event.ReviewNote,
+	})
+	response.Success(c, event)
+}
+
+// ListIPBans IP 封禁列表
+func (h *SecurityHandler) ListIPBans(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+	bans, total, err := h.loginProtection.ListBans(page, limit, c.Query("include_expired") == "true")
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, bans, page, limit, total)
+}
+
+// CreateIPBan 手动封禁 IP
+func (h *SecurityHandler) CreateIPBan(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	var req service.IPBanInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	ban, err := h.loginProtection.BanIP(req, adminID)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to ban IP", err)
+		return
+	}
+	logger.LogOperation(database.GetDB(), c, "create", "ip_ban", &ban.ID, map[string]interface{}{
+		"ip_address": ban.IPAddress,
+		"reason":     ban.Reason,
+		"expires_at": ban.ExpiresAt,
+	})
+	response.Success(c, ban)
+}
+
+// DeleteIPBan 解除封禁
+func (h *SecurityHandler) DeleteIPBan(c *gin.Context) {
+	id, ok := parseSecurityID(c)
+	if !ok {
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	ban, err := h.loginProtection.UnbanIP(id, adminID)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to remove IP ban", err)
+		return
+	}
+	logger.LogOperation(database.GetDB(), c, "delete", "ip_ban", &ban.ID, map[string]interface{}{
+		"ip_address": ban.IPAddress,
+	})
+	response.Success(c, nil)
+}
+
+// ListScraperOffenders 触发目录防爬最多的 IP
+func (h *SecurityHandler) ListScraperOffenders(c *gin.Context) {
+	hours, _ := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offenders, err := h.scraperProtection.TopOffenders(hours, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": offenders})
+}
+
+// BanScraperOffender 一键封禁爬虫 IP
+func (h *SecurityHandler) BanScraperOffender(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		IPAddress string `json:"ip_address" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	ban, err := h.scraperProtection.BanOffender(req.IPAddress, adminID)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to ban IP", err)
+		return
+	}
+	logger.LogOperation(database.GetDB(), c, "create", "ip_ban", &ban.ID, map[string]interface{}{
+		"ip_address": ban.IPAddress,
+		"reason":     ban.Reason,
+		"expires_at": ban.ExpiresAt,
+		"source":     "scraper_offenders",
+	})
+	response.Success(c, ban)
+}
+
+// ListLockouts 当前被锁定的账户
+func (h *SecurityHandler) ListLockouts(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+	lockouts, total, err := h.loginProtection.ListLockouts(page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, lockouts, page, limit, total)
+}
+
+// UnlockAccount 解除账户锁定
+func (h *SecurityHandler) UnlockAccount(c *gin.Context) {
+	id, ok := parseSecurityID(c)
+	if !ok {
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	lockout, err := h.loginProtection.UnlockAccount(id, adminID)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to unlock account", err)
+		return
+	}
+	logger.LogOperation(database.GetDB(), c, "unlock", "account_lockout", &lockout.ID, map[string]interface{}{
+		"email": lockout.Email,
+	})
+	response.Success(c, nil)
+}
+
+// ReportCSPViolation 公开 POST /api/csp-report — 浏览器上报 CSP 违规
+func (h *SecurityHandler) ReportCSPViolation(c *gin.Context) {
+	cfg := config.GetConfig()
+	if cfg == nil || !cfg.Security.CSP.Enabled || !cfg.Security.CSP.CollectReports {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCSPReportBodyBytes+1))
+	if err != nil || len(body) > maxCSPReportBodyBytes {
+		response.BadRequest(c, "Invalid CSP report")
+		return
+	}
+	if _, err := h.cspReports.Record(body, utils.GetRealIP(c), c.Request.UserAgent()); err != nil {
+		response.BadRequest(c, "Invalid CSP report")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListCSPViolations CSP 违规汇总
+func (h *SecurityHandler) ListCSPViolations(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+	filter := service.CSPViolationFilter{
+		Directive:   strings.TrimSpace(c.Query("directive")),
+		DocumentURI: strings.TrimSpace(c.Query("document_uri")),
+	}
+	violations, total, err := h.cspReports.List(filter, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, violations, page, limit, total)
+}
+
+// ClearCSPViolations 清空 CSP 违规记录
+func (h *SecurityHandler) ClearCSPViolations(c *gin.Context) {
+	deleted, err := h.cspReports.Clear()
+	if err != nil {
+		response.InternalServerError(c, "Failed to clear CSP reports", err)
+		return
+	}
+	logger.LogOperation(database.GetDB(), c, "clear", "csp_violation", nil, map[string]interface{}{
+		"deleted": deleted,
+	})
+	response.Success(c, gin.H{"deleted": deleted})
+}
@@ -2,7 +2,9 @@ package admin
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -40,6 +42,8 @@ func buildSerialAdminHookPayload(serial *models.ProductSerial) map[string]interf
 		"view_count":            serial.ViewCount,
 		"first_viewed_at":       serial.FirstViewedAt,
 		"last_viewed_at":        serial.LastViewedAt,
+		"invalidated_at":        serial.InvalidatedAt,
+		"invalid_reason":        serial.InvalidReason,
 		"created_at":            serial.CreatedAt,
 		"updated_at":            serial.UpdatedAt,
 	}
@@ -327,11 +331,33 @@ func (h *SerialHandler) BatchDeleteSerials(c *gin.Context) {
 		response.InternalError(c, "Failed to query serial numbers")
 		return
 	}
+	var approval *adminApprovalGrant
+	if service.BulkDeleteRequiresApproval(len(req.IDs)) {
+		// 排序后参与审批校验，提交顺序不影响匹配
+		sortedIDs := slices.Clone(req.IDs)
+		slices.Sort(sortedIDs)
+		grant, ok := requireAdminApproval(c, service.AdminApprovalRequest{
+			ActionType:   models.AdminApprovalActionSerialBatchDelete,
+			ResourceType: "serial",
+			Summary:      fmt.Sprintf("Delete %d serial numbers", len(sortedIDs)),
+			Payload: map[string]interface{}{
+				"ids":   sortedIDs,
+				"count": len(sortedIDs),
+			},
+			RequestedBy: adminIDValue,
+		})
+		if !ok {
+			return
+		}
+		approval = grant
+		defer approval.release()
+	}
 
 	if err := h.serialService.BatchDeleteSerials(req.IDs); err != nil {
 		response.InternalError(c, "Failed to delete serial numbers")
 		return
 	}
+	approval.complete()
 
 	if h.pluginManager != nil {
 		afterPayload := map[string]interface{}{
@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"fmt"
+	"log"
+
+	"auralogic/internal/config"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type exportSettingsRequest struct {
+	Secrets    string `json:"secrets"`
+	Passphrase string `json:"passphrase"`
+}
+
+type importSettingsRequest struct {
+	Bundle       *service.SettingsBundle `json:"bundle" binding:"required"`
+	Passphrase   string                  `json:"passphrase"`
+	ExpectedHash string                  `json:"expected_hash"`
+}
+
+// ExportSettings 导出可迁移的配置包（不含环境相关配置，密钥脱敏或加密）
+func (h *SettingsHandler) ExportSettings(c *gin.Context) {
+	var req exportSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	switch req.Secrets {
+	case "", service.SettingsBundleSecretsRedacted, service.SettingsBundleSecretsEncrypted:
+	default:
+		response.BadRequest(c, "Invalid secrets mode")
+		return
+	}
+
+	currentConfig, err := readConfigFile(config.GetConfigPath())
+	if err != nil {
+		response.InternalError(c, "Failed to read config file")
+		return
+	}
+	bundle, err := service.BuildSettingsBundle(currentConfig, req.Secrets, req.Passphrase)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to export settings", err)
+		return
+	}
+
+	logger.LogOperation(h.db, c, "export", "system_config", nil, map[string]interface{}{
+		"secrets_mode": bundle.SecretsMode,
+	})
+	response.Success(c, bundle)
+}
+
+// PreviewSettingsImport 导入预检：返回变更差异、跳过的环境配置、密钥处理情况与校验结果
+func (h *SettingsHandler) PreviewSettingsImport(c *gin.Context) {
+	var req importSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	currentConfig, err := readConfigFile(config.GetConfigPath())
+	if err != nil {
+		response.InternalError(c, "Failed to read config file")
+		return
+	}
+	_, preview, err := service.ResolveSettingsImport(req.Bundle, currentConfig, req.Passphrase)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to preview settings import", err)
+		return
+	}
+	response.Success(c, preview)
+}
+
+// ImportSettings 应用配置包并热更新；expected_hash 与预检时不一致说明配置已被修改，需重新预检
+func (h *SettingsHandler) ImportSettings(c *gin.Context) {
+	var req importSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	configPath := config.GetConfigPath()
+	currentConfig, err := readConfigFile(configPath)
+	if err != nil {
+		response.InternalError(c, "Failed to read config file")
+		return
+	}
+	if req.ExpectedHash != "" && req.ExpectedHash != service.ConfigMapHash(currentConfig) {
+		respondAdminBizError(c, service.ErrSettingsImportConflict)
+		return
+	}
+	merged, preview, err := service.ResolveSettingsImport(req.Bundle, currentConfig, req.Passphrase)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to import settings", err)
+		return
+	}
+	if !preview.Valid {
+		response.BadRequest(c, fmt.Sprintf("Settings bundle cannot be applied: %s", preview.ValidationError))
+		return
+	}
+
+	previousPluginEnabled := false
+	var previousPluginConfig config.PluginPlatformConfig
+	if h.cfg != nil {
+		previousPluginEnabled = h.cfg.Plugin.Enabled
+		previousPluginConfig = h.cfg.Plugin
+	}
+
+	if err := writeConfigFile(configPath, merged); err != nil {
+		response.InternalError(c, "Failed to save config file")
+		return
+	}
+	if err := config.ReloadConfig(); err != nil {
+		if restoreErr := writeConfigFile(configPath, currentConfig); restoreErr != nil {
+			log.Printf("restore config file after failed import failed: admin=%d err=%v", adminID, restoreErr)
+		}
+		response.BadRequest(c, fmt.Sprintf("Settings bundle cannot be applied: %v", err))
+		return
+	}
+
+	revision, revisionErr := h.revisionService.Record(currentConfig, merged, models.ConfigRevisionActionImport, &adminID, nil)
+	if revisionErr != nil {
+		log.Printf("record config import revision failed: admin=%d err=%v", adminID, revisionErr)
+	}
+	applied := h.applyReloadedSettings(previousPluginEnabled, previousPluginConfig, true, false)
+
+	logPayload := configRevisionLogPayload(revision)
+	logPayload["source"] = preview.Source
+	logPayload["secrets_applied"] = len(preview.SecretsApplied)
+	logPayload["unresolved_secrets"] = preview.UnresolvedSecrets
+	logPayload["hot_reload"] = true
+	if applied.errorMessage != "" {
+		logPayload["reload_error"] = applied.errorMessage
+	}
+	logger.LogOperation(h.db, c, "import", "system_config", nil, logPayload)
+
+	resp := gin.H{
+		"message":            "Settings imported and applied. Some configurations (Database, Redis, JWT) require service restart to take effect",
+		"change_count":       len(preview.Changes),
+		"unresolved_secrets": preview.UnresolvedSecrets,
+	}
+	if revision != nil {
+		resp["revision_id"] = revision.ID
+	}
+	if applied.pluginRuntimeAction != "" {
+		resp["plugin_runtime_action"] = applied.pluginRuntimeAction
+	}
+	if applied.jsWorkerRestarted {
+		resp["js_worker_restarted"] = true
+	}
+	if applied.errorMessage != "" {
+		resp["reload_error"] = applied.errorMessage
+	}
+	response.Success(c, resp)
+}
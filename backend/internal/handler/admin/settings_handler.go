@@ -13,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -20,7 +21,10 @@ import (
 	"auralogic/internal/config"
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/authbiz"
 	"auralogic/internal/pkg/cache"
+	"auralogic/internal/pkg/constants"
+	"auralogic/internal/pkg/demomode"
 	"auralogic/internal/pkg/logger"
 	"auralogic/internal/pkg/pluginutil"
 	"auralogic/internal/pkg/response"
@@ -31,11 +35,13 @@ import (
 )
 
 type SettingsHandler struct {
-	db            *gorm.DB
-	cfg           *config.Config
-	smsService    *service.SMSService
-	emailService  *service.EmailService
-	pluginManager *service.PluginManagerService
+	db              *gorm.DB
+	cfg             *config.Config
+	smsService      *service.SMSService
+	emailService    *service.EmailService
+	themeService    *service.ThemeService
+	pluginManager   *service.PluginManagerService
+	revisionService *service.ConfigRevisionService
 }
 
 func NewSettingsHandler(
@@ -43,14 +49,17 @@ func NewSettingsHandler(
 	cfg *config.Config,
 	smsService *service.SMSService,
 	emailService *service.EmailService,
+	themeService *service.ThemeService,
 	pluginManager *service.PluginManagerService,
 ) *SettingsHandler {
 	return &SettingsHandler{
-		db:            db,
-		cfg:           cfg,
-		smsService:    smsService,
-		emailService:  emailService,
-		pluginManager: pluginManager,
+		db:              db,
+		cfg:             cfg,
+		smsService:      smsService,
+		emailService:    emailService,
+		themeService:    themeService,
+		pluginManager:   pluginManager,
+		revisionService: service.NewConfigRevisionService(db),
 	}
 }
 
@@ -177,6 +186,7 @@ func (h *SettingsHandler) GetPublicConfig(c *gin.Context) {
 		"max_order_items":            h.cfg.Order.MaxOrderItems,
 		"max_item_quantity":          h.cfg.Order.MaxItemQuantity,
 		"app_name":                   h.cfg.App.Name,
+		"demo_mode":                  h.cfg.App.DemoMode,
 		"default_theme":              defaultTheme,
 		"allow_registration":         h.cfg.Security.Login.AllowRegistration,
 		"allow_password_login":       h.cfg.Security.Login.AllowPasswordLogin,
@@ -187,6 +197,10 @@ func (h *SettingsHandler) GetPublicConfig(c *gin.Context) {
 		"allow_phone_login":          h.cfg.Security.Login.AllowPhoneLogin,
 		"allow_phone_register":       h.cfg.Security.Login.AllowPhoneRegister,
 		"allow_phone_password_reset": h.cfg.Security.Login.AllowPhonePasswordReset,
+		"session_cookie": gin.H{
+			"enabled":     h.cfg.Security.SessionCookie.Enabled,
+			"header_name": h.cfg.Security.SessionCookie.CSRFHeaderName,
+		},
 		"stock_display": gin.H{
 			"mode":                 h.cfg.Order.StockDisplay.Mode,
 			"low_stock_threshold":  h.cfg.Order.StockDisplay.LowStockThreshold,
@@ -197,6 +211,7 @@ func (h *SettingsHandler) GetPublicConfig(c *gin.Context) {
 			"logo_url":      h.cfg.Customization.LogoURL,
 			"favicon_url":   h.cfg.Customization.FaviconURL,
 			"auth_branding": h.renderAuthBranding(),
+			"theme":         h.themeService.PublicActiveTheme(),
 		},
 		"ticket": gin.H{
 			"enabled":            h.cfg.Ticket.Enabled,
@@ -208,6 +223,10 @@ func (h *SettingsHandler) GetPublicConfig(c *gin.Context) {
 		"serial": gin.H{
 			"enabled": h.cfg.Serial.Enabled,
 		},
+		"exchange_rate": gin.H{
+			"enabled":            h.cfg.ExchangeRate.Enabled,
+			"display_currencies": h.cfg.ExchangeRate.DisplayCurrencies,
+		},
 		"auto_cancel_hours":                  h.cfg.Order.AutoCancelHours,
 		"invoice_enabled":                    h.cfg.Order.Invoice.Enabled,
 		"show_virtual_stock_remark":          h.cfg.Order.ShowVirtualStockRemark,
@@ -220,6 +239,7 @@ func (h *SettingsHandler) GetPublicConfig(c *gin.Context) {
 			"enable_for_register":      h.cfg.Security.Captcha.EnableForRegister,
 			"enable_for_serial_verify": h.cfg.Security.Captcha.EnableForSerialVerify,
 			"enable_for_bind":          h.cfg.Security.Captcha.EnableForBind,
+			"scenes":                   buildPublicCaptchaScenes(h.cfg.Security.Captcha),
 		},
 		"plugin": gin.H{
 			"enabled": h.cfg.Plugin.Enabled,
@@ -228,9 +248,37 @@ func (h *SettingsHandler) GetPublicConfig(c *gin.Context) {
 			},
 		},
 	}
+	applyStorePublicConfig(c, publicConfig)
 	response.Success(c, publicConfig)
 }
 
+// applyStorePublicConfig 多店铺：用当前店铺的品牌覆盖全局配置（字段为空时保留全局值）
+func applyStorePublicConfig(c *gin.Context, publicConfig gin.H) {
+	store, ok := middleware.GetStore(c)
+	if !ok {
+		return
+	}
+	publicConfig["store"] = gin.H{
+		"id":   store.ID,
+		"code": store.Code,
+		"name": store.Name,
+	}
+	publicConfig["app_name"] = store.Name
+	customization, ok := publicConfig["customization"].(gin.H)
+	if !ok {
+		return
+	}
+	if store.PrimaryColor != "" {
+		customization["primary_color"] = store.PrimaryColor
+	}
+	if store.LogoURL != "" {
+		customization["logo_url"] = store.LogoURL
+	}
+	if store.FaviconURL != "" {
+		customization["favicon_url"] = store.FaviconURL
+	}
+}
+
 // GetSettings get系统设置
 func (h *SettingsHandler) GetSettings(c *gin.Context) {
 	defaultTheme := h.cfg.App.DefaultTheme
@@ -276,12 +324,17 @@ func (h *SettingsHandler) GetSettings(c *gin.Context) {
 			"custom_body_template":      h.cfg.SMS.CustomBodyTemplate,
 		},
 		"security": gin.H{
-			"password_policy": h.cfg.Security.PasswordPolicy,
-			"login":           h.cfg.Security.Login,
-			"cors":            h.cfg.Security.CORS,
-			"captcha":         buildSafeCaptchaSettingsResponse(h.cfg.Security.Captcha),
-			"ip_header":       h.cfg.Security.IPHeader,
-			"trusted_proxies": h.cfg.Security.TrustedProxies,
+			"password_policy":               h.cfg.Security.PasswordPolicy,
+			"login":                         h.cfg.Security.Login,
+			"cors":                          h.cfg.Security.CORS,
+			"captcha":                       buildSafeCaptchaSettingsResponse(h.cfg.Security.Captcha),
+			"ip_header":                     h.cfg.Security.IPHeader,
+			"trusted_proxies":               h.cfg.Security.TrustedProxies,
+			"login_protection":              h.cfg.Security.LoginProtection,
+			"scraper_protection":            h.cfg.Security.ScraperProtection,
+			"csp":                           h.cfg.Security.CSP,
+			"approval":                      h.cfg.Security.Approval,
+			"payment_http_strict_allowlist": h.cfg.Security.PaymentHTTPStrictAllowlist,
 		},
 		"rate_limit":       h.cfg.RateLimit,
 		"email_rate_limit": h.cfg.EmailRateLimit,
@@ -289,19 +342,35 @@ func (h *SettingsHandler) GetSettings(c *gin.Context) {
 		"order": gin.H{
 			"no_prefix":                          h.cfg.Order.NoPrefix,
 			"auto_cancel_hours":                  h.cfg.Order.AutoCancelHours,
+			"draft_expire_hours":                 h.cfg.Order.DraftExpireHours,
+			"payment_reminder_hours":             h.cfg.Order.PaymentReminderHours,
 			"currency":                           h.cfg.Order.Currency,
 			"max_order_items":                    h.cfg.Order.MaxOrderItems,
 			"max_item_quantity":                  h.cfg.Order.MaxItemQuantity,
 			"virtual_delivery_order":             h.cfg.Order.VirtualDeliveryOrder,
 			"virtual_script_timeout_max_ms":      h.cfg.Order.VirtualScriptTimeoutMaxMs,
+			"virtual_stock_expiry_warning_days":  h.cfg.Order.VirtualStockExpiryWarningDays,
+			"cart_reservation_ttl_seconds":       h.cfg.Order.CartReservationTTLSeconds,
+			"tier_pricing_promo_stacking":        h.cfg.Order.TierPricingPromoStacking,
 			"show_virtual_stock_remark":          h.cfg.Order.ShowVirtualStockRemark,
 			"enable_virtual_stock_inline_iframe": h.cfg.Order.EnableVirtualStockInlineIframe,
 			"high_concurrency_protection": gin.H{
-				"enabled":         h.cfg.Order.HighConcurrencyProtection.Enabled,
-				"mode":            h.cfg.Order.HighConcurrencyProtection.Mode,
-				"max_inflight":    h.cfg.Order.HighConcurrencyProtection.MaxInFlight,
-				"wait_timeout_ms": h.cfg.Order.HighConcurrencyProtection.WaitTimeoutMs,
-				"redis_lease_ms":  h.cfg.Order.HighConcurrencyProtection.RedisLeaseMs,
+				"enabled":                h.cfg.Order.HighConcurrencyProtection.Enabled,
+				"mode":                   h.cfg.Order.HighConcurrencyProtection.Mode,
+				"max_inflight":           h.cfg.Order.HighConcurrencyProtection.MaxInFlight,
+				"wait_timeout_ms":        h.cfg.Order.HighConcurrencyProtection.WaitTimeoutMs,
+				"redis_lease_ms":         h.cfg.Order.HighConcurrencyProtection.RedisLeaseMs,
+				"max_inflight_per_user":  h.cfg.Order.HighConcurrencyProtection.MaxInFlightPerUser,
+				"max_inflight_per_ip":    h.cfg.Order.HighConcurrencyProtection.MaxInFlightPerIP,
+				"blind_box_max_inflight": h.cfg.Order.HighConcurrencyProtection.BlindBoxMaxInFlight,
+			},
+			"waiting_room": gin.H{
+				"enabled":               h.cfg.Order.WaitingRoom.Enabled,
+				"all_products":          h.cfg.Order.WaitingRoom.AllProducts,
+				"admit_per_second":      h.cfg.Order.WaitingRoom.AdmitPerSecond,
+				"burst":                 h.cfg.Order.WaitingRoom.Burst,
+				"admission_ttl_seconds": h.cfg.Order.WaitingRoom.AdmissionTTLSeconds,
+				"abandon_after_seconds": h.cfg.Order.WaitingRoom.AbandonAfterSeconds,
 			},
 			"stock_display": gin.H{
 				"mode":                 h.cfg.Order.StockDisplay.Mode,
@@ -320,18 +389,33 @@ func (h *SettingsHandler) GetSettings(c *gin.Context) {
 				"tax_id":          h.cfg.Order.Invoice.TaxID,
 				"footer_text":     h.cfg.Order.Invoice.FooterText,
 			},
+			"timeline": gin.H{
+				"default_ship_within_days":  h.cfg.Order.Timeline.DefaultShipWithinDays,
+				"default_delivery_min_days": h.cfg.Order.Timeline.DefaultDeliveryMinDays,
+				"default_delivery_max_days": h.cfg.Order.Timeline.DefaultDeliveryMaxDays,
+				"delivery_estimates":        h.cfg.Order.Timeline.DeliveryEstimates,
+			},
+			"customs": gin.H{
+				"sender_country":         h.cfg.Order.Customs.SenderCountry,
+				"default_origin_country": h.cfg.Order.Customs.DefaultOriginCountry,
+				"content_category":       h.cfg.Order.Customs.ContentCategory,
+				"cn22_max_value_minor":   h.cfg.Order.Customs.CN22MaxValueMinor,
+			},
+			"package_boxes": h.cfg.Order.PackageBoxes,
 		},
 		"magic_link": gin.H{
 			"expire_minutes": h.cfg.MagicLink.ExpireMinutes,
 			"max_uses":       h.cfg.MagicLink.MaxUses,
 		},
 		"form": gin.H{
-			"expire_hours": h.cfg.Form.ExpireHours,
+			"expire_hours":       h.cfg.Form.ExpireHours,
+			"disabled_countries": h.cfg.Form.DisabledCountries,
 		},
 		"upload": gin.H{
-			"dir":           h.cfg.Upload.Dir,
-			"max_size":      h.cfg.Upload.MaxSize,
-			"allowed_types": h.cfg.Upload.AllowedTypes,
+			"dir":             h.cfg.Upload.Dir,
+			"max_size":        h.cfg.Upload.MaxSize,
+			"allowed_types":   h.cfg.Upload.AllowedTypes,
+			"product_hotlink": h.cfg.Upload.ProductHotlink,
 		},
 		"oauth": gin.H{
 			"google": gin.H{
@@ -428,6 +512,39 @@ func (h *SettingsHandler) GetSettings(c *gin.Context) {
 		"analytics": gin.H{
 			"enabled": h.cfg.Analytics.Enabled,
 		},
+		"acme": gin.H{
+			"enabled":                h.cfg.ACME.Enabled,
+			"email":                  h.cfg.ACME.Email,
+			"cache_dir":              h.cfg.ACME.CacheDir,
+			"https_port":             h.cfg.ACME.HTTPSPort,
+			"directory_url":          h.cfg.ACME.DirectoryURL,
+			"renew_before_days":      h.cfg.ACME.RenewBeforeDays,
+			"check_interval_minutes": h.cfg.ACME.CheckIntervalMinutes,
+		},
+		"seo": gin.H{
+			"sitemap_enabled":   h.cfg.SEO.SitemapEnabled,
+			"include_knowledge": h.cfg.SEO.IncludeKnowledge,
+			"default_og_image":  h.cfg.SEO.DefaultOGImage,
+			"robots_disallow":   h.cfg.SEO.RobotsDisallow,
+		},
+		"exchange_rate": gin.H{
+			"enabled":                   h.cfg.ExchangeRate.Enabled,
+			"fiat_provider":             h.cfg.ExchangeRate.FiatProvider,
+			"fiat_api_key_configured":   strings.TrimSpace(h.cfg.ExchangeRate.FiatAPIKey) != "",
+			"crypto_provider":           h.cfg.ExchangeRate.CryptoProvider,
+			"crypto_api_key_configured": strings.TrimSpace(h.cfg.ExchangeRate.CryptoAPIKey) != "",
+			"cache_minutes":             h.cfg.ExchangeRate.CacheMinutes,
+			"stale_alert_minutes":       h.cfg.ExchangeRate.StaleAlertMinutes,
+			"display_currencies":        h.cfg.ExchangeRate.DisplayCurrencies,
+		},
+		"shipment_tracking": gin.H{
+			"enabled":                   h.cfg.ShipmentTracking.Enabled,
+			"provider":                  h.cfg.ShipmentTracking.Provider,
+			"api_key_configured":        strings.TrimSpace(h.cfg.ShipmentTracking.APIKey) != "",
+			"webhook_secret_configured": strings.TrimSpace(h.cfg.ShipmentTracking.WebhookSecret) != "",
+			"poll_interval_minutes":     h.cfg.ShipmentTracking.PollIntervalMinutes,
+			"auto_complete_days":        h.cfg.ShipmentTracking.AutoCompleteDays,
+		},
 	}
 
 	response.Success(c, settings)
@@ -442,7 +559,34 @@ func buildSafeCaptchaSettingsResponse(captcha config.CaptchaConfig) gin.H {
 		"enable_for_register":      captcha.EnableForRegister,
 		"enable_for_serial_verify": captcha.EnableForSerialVerify,
 		"enable_for_bind":          captcha.EnableForBind,
+		"recaptcha_min_score":      captcha.RecaptchaMinScore,
+		"scene_providers":          captcha.SceneProviders,
+		"provider_keys":            buildSafeCaptchaProviderKeys(captcha.ProviderKeys),
+	}
+}
+
+func buildSafeCaptchaProviderKeys(providerKeys map[string]config.CaptchaProviderKeys) gin.H {
+	out := gin.H{}
+	for provider, keys := range providerKeys {
+		out[provider] = gin.H{
+			"site_key":              keys.SiteKey,
+			"secret_key_configured": strings.TrimSpace(keys.SecretKey) != "",
+		}
+	}
+	return out
+}
+
+// buildPublicCaptchaScenes 各场景实际使用的驱动与站点密钥，前端按场景渲染组件
+func buildPublicCaptchaScenes(captcha config.CaptchaConfig) gin.H {
+	scenes := gin.H{}
+	for _, scene := range service.CaptchaScenes {
+		provider, keys := captcha.ResolveScene(scene)
+		scenes[scene] = gin.H{
+			"provider": provider,
+			"site_key": keys.SiteKey,
+		}
 	}
+	return scenes
 }
 
 func sortedConfiguredHeaderKeys(headers map[string]string) []string {
@@ -458,12 +602,59 @@ func sortedConfiguredHeaderKeys(headers map[string]string) []string {
 }
 
 func captchaProviderRequiresSecret(provider string) bool {
-	switch strings.ToLower(strings.TrimSpace(provider)) {
-	case "cloudflare", "google":
-		return true
-	default:
-		return false
+	return service.CaptchaProviderRequiresKeys(strings.ToLower(strings.TrimSpace(provider)))
+}
+
+// normalizeCaptchaUpdate 校验驱动名称与场景覆盖，只保留场景覆盖实际用到的额外驱动密钥
+func normalizeCaptchaUpdate(existing map[string]interface{}, req *settingsCaptchaUpdateRequest) (map[string]string, map[string]interface{}, float64, error) {
+	if !service.IsCaptchaProvider(req.Provider) {
+		return nil, nil, 0, fmt.Errorf("unsupported captcha provider: %s", req.Provider)
+	}
+	if req.RecaptchaMinScore < 0 || req.RecaptchaMinScore > 1 {
+		return nil, nil, 0, fmt.Errorf("recaptcha_min_score must be between 0 and 1")
+	}
+	minScore := req.RecaptchaMinScore
+	if minScore == 0 {
+		minScore = 0.5
+	}
+
+	sceneProviders := map[string]string{}
+	for scene, provider := range req.SceneProviders {
+		provider = strings.TrimSpace(provider)
+		if provider == "" || provider == req.Provider {
+			continue
+		}
+		if !slices.Contains(service.CaptchaScenes, scene) {
+			return nil, nil, 0, fmt.Errorf("unsupported captcha scene: %s", scene)
+		}
+		if !service.IsCaptchaProvider(provider) {
+			return nil, nil, 0, fmt.Errorf("unsupported captcha provider: %s", provider)
+		}
+		sceneProviders[scene] = provider
 	}
+
+	existingKeys, _ := existing["provider_keys"].(map[string]interface{})
+	providerKeys := map[string]interface{}{}
+	for _, provider := range sceneProviders {
+		if !service.CaptchaProviderRequiresKeys(provider) {
+			continue
+		}
+		submitted := req.ProviderKeys[provider]
+		secretKey := submitted.SecretKey
+		if !submitted.SecretKeySubmitted {
+			if previous, ok := existingKeys[provider].(map[string]interface{}); ok {
+				secretKey, _ = previous["secret_key"].(string)
+			}
+		}
+		if strings.TrimSpace(submitted.SiteKey) == "" || strings.TrimSpace(secretKey) == "" {
+			return nil, nil, 0, fmt.Errorf("captcha provider %s requires site key and secret key", provider)
+		}
+		providerKeys[provider] = map[string]interface{}{
+			"site_key":   strings.TrimSpace(submitted.SiteKey),
+			"secret_key": strings.TrimSpace(secretKey),
+		}
+	}
+	return sceneProviders, providerKeys, minScore, nil
 }
 
 func resolveCaptchaSecretForUpdate(existing map[string]interface{}, req *settingsCaptchaUpdateRequest) string {
@@ -497,6 +688,16 @@ type settingsCaptchaUpdateRequest struct {
 	EnableForRegister     bool   `json:"enable_for_register"`
 	EnableForSerialVerify bool   `json:"enable_for_serial_verify"`
 	EnableForBind         bool   `json:"enable_for_bind"`
+
+	RecaptchaMinScore float64                                      `json:"recaptcha_min_score"`
+	SceneProviders    map[string]string                            `json:"scene_providers"`
+	ProviderKeys      map[string]settingsCaptchaProviderKeysUpdate `json:"provider_keys"`
+}
+
+type settingsCaptchaProviderKeysUpdate struct {
+	SiteKey            string `json:"site_key"`
+	SecretKey          string `json:"secret_key,omitempty"`
+	SecretKeySubmitted bool   `json:"secret_key_submitted,omitempty"`
 }
 
 // UpdateSettingsRequest Update设置请求
@@ -542,15 +743,20 @@ type UpdateSettingsRequest struct {
 	} `json:"sms,omitempty"`
 
 	Security struct {
-		PasswordPolicy          config.PasswordPolicyConfig   `json:"password_policy,omitempty"`
-		Login                   config.LoginConfig            `json:"login,omitempty"`
-		LoginSubmitted          bool                          `json:"login_submitted,omitempty"`
-		CORS                    config.CORSConfig             `json:"cors,omitempty"`
-		Captcha                 *settingsCaptchaUpdateRequest `json:"captcha,omitempty"`
-		IPHeader                string                        `json:"ip_header,omitempty"`
-		IPHeaderSubmitted       bool                          `json:"ip_header_submitted,omitempty"`
-		TrustedProxies          []string                      `json:"trusted_proxies,omitempty"`
-		TrustedProxiesSubmitted bool                          `json:"trusted_proxies_submitted,omitempty"`
+		PasswordPolicy             config.PasswordPolicyConfig     `json:"password_policy,omitempty"`
+		Login                      config.LoginConfig              `json:"login,omitempty"`
+		LoginSubmitted             bool                            `json:"login_submitted,omitempty"`
+		CORS                       config.CORSConfig               `json:"cors,omitempty"`
+		Captcha                    *settingsCaptchaUpdateRequest   `json:"captcha,omitempty"`
+		IPHeader                   string                          `json:"ip_header,omitempty"`
+		IPHeaderSubmitted          bool                            `json:"ip_header_submitted,omitempty"`
+		TrustedProxies             []string                        `json:"trusted_proxies,omitempty"`
+		TrustedProxiesSubmitted    bool                            `json:"trusted_proxies_submitted,omitempty"`
+		LoginProtection            *config.LoginProtectionConfig   `json:"login_protection,omitempty"`
+		ScraperProtection          *config.ScraperProtectionConfig `json:"scraper_protection,omitempty"`
+		CSP                        *config.CSPConfig               `json:"csp,omitempty"`
+		Approval                   *config.ApprovalConfig          `json:"approval,omitempty"`
+		PaymentHTTPStrictAllowlist *bool                           `json:"payment_http_strict_allowlist,omitempty"`
 	} `json:"security,omitempty"`
 
 	RateLimit config.RateLimitConfig `json:"rate_limit,omitempty"`
@@ -561,6 +767,8 @@ type UpdateSettingsRequest struct {
 	Order struct {
 		NoPrefix                       string                                      `json:"no_prefix"`
 		AutoCancelHours                int                                         `json:"auto_cancel_hours"`
+		DraftExpireHours               int                                         `json:"draft_expire_hours"`
+		PaymentReminderHours           int                                         `json:"payment_reminder_hours"`
 		MaxPendingPaymentOrdersPerUser int                                         `json:"max_pending_payment_orders_per_user"`
 		MaxPaymentPollingTasksPerUser  int                                         `json:"max_payment_polling_tasks_per_user"`
 		MaxPaymentPollingTasksGlobal   int                                         `json:"max_payment_polling_tasks_global"`
@@ -569,11 +777,18 @@ type UpdateSettingsRequest struct {
 		MaxItemQuantity                int                                         `json:"max_item_quantity"`
 		VirtualDeliveryOrder           string                                      `json:"virtual_delivery_order"`
 		VirtualScriptTimeoutMaxMs      int                                         `json:"virtual_script_timeout_max_ms"`
+		VirtualStockExpiryWarningDays  int                                         `json:"virtual_stock_expiry_warning_days"`
+		CartReservationTTLSeconds      int                                         `json:"cart_reservation_ttl_seconds"`
+		TierPricingPromoStacking       string                                      `json:"tier_pricing_promo_stacking" binding:"omitempty,oneof=stack exclusive best"`
 		ShowVirtualStockRemark         *bool                                       `json:"show_virtual_stock_remark"`
 		EnableVirtualStockInlineIframe *bool                                       `json:"enable_virtual_stock_inline_iframe"`
 		StockDisplay                   config.StockDisplayConfig                   `json:"stock_display"`
 		Invoice                        config.InvoiceConfig                        `json:"invoice"`
 		HighConcurrencyProtection      config.OrderHighConcurrencyProtectionConfig `json:"high_concurrency_protection"`
+		WaitingRoom                    config.OrderWaitingRoomConfig               `json:"waiting_room"`
+		Timeline                       *config.OrderTimelineConfig                 `json:"timeline"`
+		Customs                        *config.CustomsConfig                       `json:"customs"`
+		PackageBoxes                   *[]config.PackageBox                        `json:"package_boxes"`
 	} `json:"order,omitempty"`
 
 	MagicLink struct {
@@ -582,13 +797,16 @@ type UpdateSettingsRequest struct {
 	} `json:"magic_link,omitempty"`
 
 	Form struct {
-		ExpireHours int `json:"expire_hours"`
+		ExpireHours       int      `json:"expire_hours"`
+		DisabledCountries []string `json:"disabled_countries"`
 	} `json:"form,omitempty"`
 
 	Upload struct {
 		Dir          string   `json:"dir"`
 		MaxSize      int64    `json:"max_size"`
 		AllowedTypes []string `json:"allowed_types"`
+
+		ProductHotlink *config.HotlinkProtectionConfig `json:"product_hotlink,omitempty"`
 	} `json:"upload,omitempty"`
 
 	OAuth struct {
@@ -633,6 +851,46 @@ type UpdateSettingsRequest struct {
 		Enabled   bool `json:"enabled"`
 	} `json:"analytics,omitempty"`
 
+	ACME struct {
+		Submitted            bool   `json:"_submitted"`
+		Enabled              bool   `json:"enabled"`
+		Email                string `json:"email"`
+		HTTPSPort            int    `json:"https_port"`
+		DirectoryURL         string `json:"directory_url"`
+		RenewBeforeDays      int    `json:"renew_before_days"`
+		CheckIntervalMinutes int    `json:"check_interval_minutes"`
+	} `json:"acme,omitempty"`
+
+	SEO struct {
+		Submitted        bool     `json:"_submitted"`
+		SitemapEnabled   bool     `json:"sitemap_enabled"`
+		IncludeKnowledge bool     `json:"include_knowledge"`
+		DefaultOGImage   string   `json:"default_og_image"`
+		RobotsDisallow   []string `json:"robots_disallow"`
+	} `json:"seo,omitempty"`
+
+	ExchangeRate struct {
+		Submitted         bool     `json:"_submitted"`
+		Enabled           bool     `json:"enabled"`
+		FiatProvider      string   `json:"fiat_provider"`
+		FiatAPIKey        string   `json:"fiat_api_key"`
+		CryptoProvider    string   `json:"crypto_provider"`
+		CryptoAPIKey      string   `json:"crypto_api_key"`
+		CacheMinutes      int      `json:"cache_minutes"`
+		StaleAlertMinutes int      `json:"stale_alert_minutes"`
+		DisplayCurrencies []string `json:"display_currencies"`
+	} `json:"exchange_rate,omitempty"`
+
+	ShipmentTracking struct {
+		Submitted           bool   `json:"_submitted"`
+		Enabled             bool   `json:"enabled"`
+		Provider            string `json:"provider"`
+		APIKey              string `json:"api_key"`
+		WebhookSecret       string `json:"webhook_secret"`
+		PollIntervalMinutes int    `json:"poll_interval_minutes"`
+		AutoCompleteDays    int    `json:"auto_complete_days"`
+	} `json:"shipment_tracking,omitempty"`
+
 	Plugin struct {
 		Submitted              bool     `json:"_submitted"`
 		Enabled                bool     `json:"enabled"`
@@ -762,6 +1020,7 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 		response.InternalError(c, "Failed to read config file")
 		return
 	}
+	beforeConfig := service.CloneConfigMap(currentConfig)
 
 	// Update配置
 	if req.App.Name != "" {
@@ -883,17 +1142,35 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 			}
 		}
 
+		switch req.Security.Login.EmailVerificationMode {
+		case "":
+			req.Security.Login.EmailVerificationMode = authbiz.EmailVerificationModeLogin
+		case authbiz.EmailVerificationModeLogin, authbiz.EmailVerificationModeCheckout, authbiz.EmailVerificationModeGrace:
+		default:
+			response.BadRequest(c, "Invalid email verification mode")
+			return
+		}
+		if req.Security.Login.EmailVerificationGraceDays <= 0 {
+			req.Security.Login.EmailVerificationGraceDays = 7
+		}
+		if req.Security.Login.EmailVerificationGraceDays > 365 {
+			response.BadRequest(c, "Email verification grace period cannot exceed 365 days")
+			return
+		}
+
 		securityConfig := currentConfig["security"].(map[string]interface{})
 		securityConfig["login"] = map[string]interface{}{
-			"allow_password_login":       req.Security.Login.AllowPasswordLogin,
-			"allow_registration":         req.Security.Login.AllowRegistration,
-			"allow_guest_product_browse": req.Security.Login.AllowGuestProductBrowse,
-			"require_email_verification": req.Security.Login.RequireEmailVerification,
-			"allow_email_login":          req.Security.Login.AllowEmailLogin,
-			"allow_password_reset":       req.Security.Login.AllowPasswordReset,
-			"allow_phone_login":          req.Security.Login.AllowPhoneLogin,
-			"allow_phone_register":       req.Security.Login.AllowPhoneRegister,
-			"allow_phone_password_reset": req.Security.Login.AllowPhonePasswordReset,
+			"allow_password_login":          req.Security.Login.AllowPasswordLogin,
+			"allow_registration":            req.Security.Login.AllowRegistration,
+			"allow_guest_product_browse":    req.Security.Login.AllowGuestProductBrowse,
+			"require_email_verification":    req.Security.Login.RequireEmailVerification,
+			"email_verification_mode":       req.Security.Login.EmailVerificationMode,
+			"email_verification_grace_days": req.Security.Login.EmailVerificationGraceDays,
+			"allow_email_login":             req.Security.Login.AllowEmailLogin,
+			"allow_password_reset":          req.Security.Login.AllowPasswordReset,
+			"allow_phone_login":             req.Security.Login.AllowPhoneLogin,
+			"allow_phone_register":          req.Security.Login.AllowPhoneRegister,
+			"allow_phone_password_reset":    req.Security.Login.AllowPhonePasswordReset,
 		}
 	}
 
@@ -939,9 +1216,32 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 		if req.Order.EnableVirtualStockInlineIframe != nil {
 			enableVirtualStockInlineIframe = *req.Order.EnableVirtualStockInlineIframe
 		}
+		// 未提交时间线配置时保留原值
+		timeline := h.cfg.Order.Timeline
+		if req.Order.Timeline != nil {
+			timeline = *req.Order.Timeline
+		}
+		customs := h.cfg.Order.Customs
+		if req.Order.Customs != nil {
+			customs = *req.Order.Customs
+			if !validCustomsConfig(customs) {
+				response.BadRequest(c, "Invalid customs settings")
+				return
+			}
+		}
+		packageBoxes := h.cfg.Order.PackageBoxes
+		if req.Order.PackageBoxes != nil {
+			packageBoxes = *req.Order.PackageBoxes
+			if !validPackageBoxes(packageBoxes) {
+				response.BadRequest(c, "Invalid package box settings")
+				return
+			}
+		}
 		currentConfig["order"] = map[string]interface{}{
 			"no_prefix":                           req.Order.NoPrefix,
 			"auto_cancel_hours":                   req.Order.AutoCancelHours,
+			"draft_expire_hours":                  req.Order.DraftExpireHours,
+			"payment_reminder_hours":              req.Order.PaymentReminderHours,
 			"max_pending_payment_orders_per_user": req.Order.MaxPendingPaymentOrdersPerUser,
 			"max_payment_polling_tasks_per_user":  req.Order.MaxPaymentPollingTasksPerUser,
 			"max_payment_polling_tasks_global":    req.Order.MaxPaymentPollingTasksGlobal,
@@ -950,14 +1250,28 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 			"max_item_quantity":                   req.Order.MaxItemQuantity,
 			"virtual_delivery_order":              req.Order.VirtualDeliveryOrder,
 			"virtual_script_timeout_max_ms":       req.Order.VirtualScriptTimeoutMaxMs,
+			"virtual_stock_expiry_warning_days":   req.Order.VirtualStockExpiryWarningDays,
+			"cart_reservation_ttl_seconds":        req.Order.CartReservationTTLSeconds,
+			"tier_pricing_promo_stacking":         req.Order.TierPricingPromoStacking,
 			"show_virtual_stock_remark":           showVirtualStockRemark,
 			"enable_virtual_stock_inline_iframe":  enableVirtualStockInlineIframe,
 			"high_concurrency_protection": map[string]interface{}{
-				"enabled":         req.Order.HighConcurrencyProtection.Enabled,
-				"mode":            req.Order.HighConcurrencyProtection.Mode,
-				"max_inflight":    req.Order.HighConcurrencyProtection.MaxInFlight,
-				"wait_timeout_ms": req.Order.HighConcurrencyProtection.WaitTimeoutMs,
-				"redis_lease_ms":  req.Order.HighConcurrencyProtection.RedisLeaseMs,
+				"enabled":                req.Order.HighConcurrencyProtection.Enabled,
+				"mode":                   req.Order.HighConcurrencyProtection.Mode,
+				"max_inflight":           req.Order.HighConcurrencyProtection.MaxInFlight,
+				"wait_timeout_ms":        req.Order.HighConcurrencyProtection.WaitTimeoutMs,
+				"redis_lease_ms":         req.Order.HighConcurrencyProtection.RedisLeaseMs,
+				"max_inflight_per_user":  req.Order.HighConcurrencyProtection.MaxInFlightPerUser,
+				"max_inflight_per_ip":    req.Order.HighConcurrencyProtection.MaxInFlightPerIP,
+				"blind_box_max_inflight": req.Order.HighConcurrencyProtection.BlindBoxMaxInFlight,
+			},
+			"waiting_room": map[string]interface{}{
+				"enabled":               req.Order.WaitingRoom.Enabled,
+				"all_products":          req.Order.WaitingRoom.AllProducts,
+				"admit_per_second":      req.Order.WaitingRoom.AdmitPerSecond,
+				"burst":                 req.Order.WaitingRoom.Burst,
+				"admission_ttl_seconds": req.Order.WaitingRoom.AdmissionTTLSeconds,
+				"abandon_after_seconds": req.Order.WaitingRoom.AbandonAfterSeconds,
 			},
 			"stock_display": map[string]interface{}{
 				"mode":                 req.Order.StockDisplay.Mode,
@@ -976,6 +1290,9 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 				"tax_id":          req.Order.Invoice.TaxID,
 				"footer_text":     req.Order.Invoice.FooterText,
 			},
+			"timeline":      timeline,
+			"customs":       customs,
+			"package_boxes": packageBoxes,
 		}
 	}
 
@@ -989,18 +1306,36 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 
 	// Update表单配置
 	if req.Form.ExpireHours > 0 {
+		disabledCountries, ok := normalizeDisabledCountries(req.Form.DisabledCountries)
+		if !ok {
+			response.BadRequest(c, "Invalid disabled country code")
+			return
+		}
 		currentConfig["form"] = map[string]interface{}{
-			"expire_hours": req.Form.ExpireHours,
+			"expire_hours":       req.Form.ExpireHours,
+			"disabled_countries": disabledCountries,
 		}
 	}
 
 	// Update上传配置
 	if req.Upload.Dir != "" {
-		currentConfig["upload"] = map[string]interface{}{
+		uploadConfig := map[string]interface{}{
 			"dir":           req.Upload.Dir,
 			"max_size":      req.Upload.MaxSize,
 			"allowed_types": req.Upload.AllowedTypes,
 		}
+		if existing, ok := currentConfig["upload"].(map[string]interface{}); ok && existing["product_hotlink"] != nil {
+			uploadConfig["product_hotlink"] = existing["product_hotlink"]
+		}
+		currentConfig["upload"] = uploadConfig
+	}
+	if req.Upload.ProductHotlink != nil {
+		uploadConfig, ok := currentConfig["upload"].(map[string]interface{})
+		if !ok {
+			uploadConfig = make(map[string]interface{})
+			currentConfig["upload"] = uploadConfig
+		}
+		uploadConfig["product_hotlink"] = req.Upload.ProductHotlink
 	}
 
 	// UpdateOAuth配置
@@ -1055,10 +1390,18 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 	if req.Security.Captcha != nil {
 		securityConfig := currentConfig["security"].(map[string]interface{})
 		existingCaptchaConfig, _ := securityConfig["captcha"].(map[string]interface{})
+		sceneProviders, providerKeys, minScore, captchaErr := normalizeCaptchaUpdate(existingCaptchaConfig, req.Security.Captcha)
+		if captchaErr != nil {
+			response.BadRequest(c, captchaErr.Error())
+			return
+		}
 		securityConfig["captcha"] = map[string]interface{}{
 			"provider":                 req.Security.Captcha.Provider,
 			"site_key":                 req.Security.Captcha.SiteKey,
 			"secret_key":               resolveCaptchaSecretForUpdate(existingCaptchaConfig, req.Security.Captcha),
+			"recaptcha_min_score":      minScore,
+			"scene_providers":          sceneProviders,
+			"provider_keys":            providerKeys,
 			"enable_for_login":         req.Security.Captcha.EnableForLogin,
 			"enable_for_register":      req.Security.Captcha.EnableForRegister,
 			"enable_for_serial_verify": req.Security.Captcha.EnableForSerialVerify,
@@ -1082,6 +1425,87 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 		}
 	}
 
+	// Update登录保护配置
+	if req.Security.LoginProtection != nil {
+		protection := req.Security.LoginProtection
+		if protection.MaxFailedAttempts < 0 || protection.LockoutMinutes < 0 || protection.MaxLockoutMinutes < 0 ||
+			protection.StuffingDistinctEmails < 0 || protection.StuffingWindowMinutes < 0 || protection.AutoBanMinutes < 0 {
+			response.BadRequest(c, "Login protection values must not be negative")
+			return
+		}
+		securityConfig := currentConfig["security"].(map[string]interface{})
+		securityConfig["login_protection"] = map[string]interface{}{
+			"enabled":                  protection.Enabled,
+			"max_failed_attempts":      protection.MaxFailedAttempts,
+			"lockout_minutes":          protection.LockoutMinutes,
+			"max_lockout_minutes":      protection.MaxLockoutMinutes,
+			"stuffing_distinct_emails": protection.StuffingDistinctEmails,
+			"stuffing_window_minutes":  protection.StuffingWindowMinutes,
+			"auto_ban_minutes":         protection.AutoBanMinutes,
+		}
+	}
+
+	// Update目录防爬配置
+	if req.Security.ScraperProtection != nil {
+		protection := req.Security.ScraperProtection
+		if protection.BurstRequests < 0 || protection.BurstWindowSeconds < 0 || protection.AutoBanMinutes < 0 ||
+			protection.ChallengeTTLMinutes < 0 || protection.ChallengeDifficulty < 0 || protection.ChallengeDifficulty > 24 {
+			response.BadRequest(c, "Scraper protection values are out of range")
+			return
+		}
+		honeypotParams := make([]string, 0, len(protection.HoneypotParams))
+		for _, param := range protection.HoneypotParams {
+			if param = strings.TrimSpace(param); param != "" && !slices.Contains(honeypotParams, param) {
+				honeypotParams = append(honeypotParams, param)
+			}
+		}
+		securityConfig := currentConfig["security"].(map[string]interface{})
+		securityConfig["scraper_protection"] = map[string]interface{}{
+			"enabled":               protection.Enabled,
+			"burst_requests":        protection.BurstRequests,
+			"burst_window_seconds":  protection.BurstWindowSeconds,
+			"honeypot_params":       honeypotParams,
+			"honeypot_auto_ban":     protection.HoneypotAutoBan,
+			"auto_ban_minutes":      protection.AutoBanMinutes,
+			"challenge_enabled":     protection.ChallengeEnabled,
+			"challenge_difficulty":  protection.ChallengeDifficulty,
+			"challenge_ttl_minutes": protection.ChallengeTTLMinutes,
+		}
+	}
+
+	// Update内容安全策略
+	if req.Security.CSP != nil {
+		if err := config.ValidateCSP(*req.Security.CSP); err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		securityConfig := currentConfig["security"].(map[string]interface{})
+		securityConfig["csp"] = req.Security.CSP
+	}
+
+	// Update危险操作审批配置
+	if req.Security.Approval != nil {
+		approval := req.Security.Approval
+		if approval.RefundAmountThreshold < 0 || approval.BulkDeleteThreshold < 0 || approval.PriceChangePercent < 0 || approval.ExpireMinutes < 0 {
+			response.BadRequest(c, "Approval thresholds must not be negative")
+			return
+		}
+		securityConfig := currentConfig["security"].(map[string]interface{})
+		securityConfig["approval"] = map[string]interface{}{
+			"enabled":                 approval.Enabled,
+			"refund_amount_threshold": approval.RefundAmountThreshold,
+			"bulk_delete_threshold":   approval.BulkDeleteThreshold,
+			"price_change_percent":    approval.PriceChangePercent,
+			"expire_minutes":          approval.ExpireMinutes,
+		}
+	}
+
+	// Update付款脚本出站 HTTP 严格模式
+	if req.Security.PaymentHTTPStrictAllowlist != nil {
+		securityConfig := currentConfig["security"].(map[string]interface{})
+		securityConfig["payment_http_strict_allowlist"] = *req.Security.PaymentHTTPStrictAllowlist
+	}
+
 	// Update工单配置
 	if req.Ticket.Categories != nil || req.Ticket.Template != "" || req.Ticket.Attachment != nil {
 		ticketConfig, ok := currentConfig["ticket"].(map[string]interface{})
@@ -1108,6 +1532,7 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 				"max_voice_duration":  req.Ticket.Attachment.MaxVoiceDuration,
 				"allowed_image_types": req.Ticket.Attachment.AllowedImageTypes,
 				"retention_days":      req.Ticket.Attachment.RetentionDays,
+				"signed_url_ttl":      req.Ticket.Attachment.SignedURLTTL,
 			}
 		}
 	}
@@ -1143,17 +1568,19 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 	// Update邮件通知配置
 	if req.EmailNotifications != nil {
 		currentConfig["email_notifications"] = map[string]interface{}{
-			"user_register":      req.EmailNotifications.UserRegister,
-			"order_created":      req.EmailNotifications.OrderCreated,
-			"order_paid":         req.EmailNotifications.OrderPaid,
-			"order_shipped":      req.EmailNotifications.OrderShipped,
-			"order_completed":    req.EmailNotifications.OrderCompleted,
-			"order_cancelled":    req.EmailNotifications.OrderCancelled,
-			"order_resubmit":     req.EmailNotifications.OrderResubmit,
-			"ticket_created":     req.EmailNotifications.TicketCreated,
-			"ticket_admin_reply": req.EmailNotifications.TicketAdminReply,
-			"ticket_user_reply":  req.EmailNotifications.TicketUserReply,
-			"ticket_resolved":    req.EmailNotifications.TicketResolved,
+			"user_register":       req.EmailNotifications.UserRegister,
+			"order_created":       req.EmailNotifications.OrderCreated,
+			"order_paid":          req.EmailNotifications.OrderPaid,
+			"order_shipped":       req.EmailNotifications.OrderShipped,
+			"order_completed":     req.EmailNotifications.OrderCompleted,
+			"order_cancelled":     req.EmailNotifications.OrderCancelled,
+			"order_resubmit":      req.EmailNotifications.OrderResubmit,
+			"order_message_user":  req.EmailNotifications.OrderMessageUser,
+			"order_message_admin": req.EmailNotifications.OrderMessageAdmin,
+			"ticket_created":      req.EmailNotifications.TicketCreated,
+			"ticket_admin_reply":  req.EmailNotifications.TicketAdminReply,
+			"ticket_user_reply":   req.EmailNotifications.TicketUserReply,
+			"ticket_resolved":     req.EmailNotifications.TicketResolved,
 		}
 	}
 
@@ -1167,6 +1594,127 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 		analyticsConfig["enabled"] = req.Analytics.Enabled
 	}
 
+	// Update证书自动签发配置（启用/端口变更需重启生效）
+	if req.ACME.Submitted {
+		acmeConfig, ok := currentConfig["acme"].(map[string]interface{})
+		if !ok {
+			acmeConfig = make(map[string]interface{})
+			currentConfig["acme"] = acmeConfig
+		}
+		acmeConfig["enabled"] = req.ACME.Enabled
+		acmeConfig["email"] = strings.TrimSpace(req.ACME.Email)
+		acmeConfig["directory_url"] = strings.TrimSpace(req.ACME.DirectoryURL)
+		if req.ACME.HTTPSPort > 0 {
+			acmeConfig["https_port"] = req.ACME.HTTPSPort
+		}
+		if req.ACME.RenewBeforeDays > 0 {
+			acmeConfig["renew_before_days"] = req.ACME.RenewBeforeDays
+		}
+		if req.ACME.CheckIntervalMinutes > 0 {
+			acmeConfig["check_interval_minutes"] = req.ACME.CheckIntervalMinutes
+		}
+	}
+
+	// Update SEO 配置
+	if req.SEO.Submitted {
+		seoConfig, ok := currentConfig["seo"].(map[string]interface{})
+		if !ok {
+			seoConfig = make(map[string]interface{})
+			currentConfig["seo"] = seoConfig
+		}
+		robotsDisallow := make([]string, 0, len(req.SEO.RobotsDisallow))
+		for _, path := range req.SEO.RobotsDisallow {
+			path = strings.TrimSpace(path)
+			if strings.HasPrefix(path, "/") && !strings.ContainsAny(path, "\r\n") {
+				robotsDisallow = append(robotsDisallow, path)
+			}
+		}
+		seoConfig["sitemap_enabled"] = req.SEO.SitemapEnabled
+		seoConfig["include_knowledge"] = req.SEO.IncludeKnowledge
+		seoConfig["default_og_image"] = strings.TrimSpace(req.SEO.DefaultOGImage)
+		seoConfig["robots_disallow"] = robotsDisallow
+	}
+
+	// Update汇率服务配置（API Key 留空表示不修改）
+	if req.ExchangeRate.Submitted {
+		exchangeRateConfig, ok := currentConfig["exchange_rate"].(map[string]interface{})
+		if !ok {
+			exchangeRateConfig = make(map[string]interface{})
+			currentConfig["exchange_rate"] = exchangeRateConfig
+		}
+		fiatProvider := strings.ToLower(strings.TrimSpace(req.ExchangeRate.FiatProvider))
+		if fiatProvider != "" && fiatProvider != service.ExchangeRateProviderExchangeRateHost {
+			response.BadRequest(c, "Unsupported fiat exchange rate provider")
+			return
+		}
+		cryptoProvider := strings.ToLower(strings.TrimSpace(req.ExchangeRate.CryptoProvider))
+		if cryptoProvider != "" && cryptoProvider != service.ExchangeRateProviderCoinGecko {
+			response.BadRequest(c, "Unsupported crypto exchange rate provider")
+			return
+		}
+		displayCurrencies := make([]string, 0, len(req.ExchangeRate.DisplayCurrencies))
+		for _, code := range req.ExchangeRate.DisplayCurrencies {
+			code = strings.ToUpper(strings.TrimSpace(code))
+			if code == "" {
+				continue
+			}
+			if len(code) < 3 || len(code) > 5 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+				response.BadRequest(c, "Invalid display currency: "+code)
+				return
+			}
+			displayCurrencies = append(displayCurrencies, code)
+		}
+		exchangeRateConfig["enabled"] = req.ExchangeRate.Enabled
+		exchangeRateConfig["fiat_provider"] = fiatProvider
+		exchangeRateConfig["crypto_provider"] = cryptoProvider
+		if key := strings.TrimSpace(req.ExchangeRate.FiatAPIKey); key != "" {
+			exchangeRateConfig["fiat_api_key"] = key
+		}
+		if key := strings.TrimSpace(req.ExchangeRate.CryptoAPIKey); key != "" {
+			exchangeRateConfig["crypto_api_key"] = key
+		}
+		if req.ExchangeRate.CacheMinutes > 0 {
+			exchangeRateConfig["cache_minutes"] = req.ExchangeRate.CacheMinutes
+		}
+		if req.ExchangeRate.StaleAlertMinutes > 0 {
+			exchangeRateConfig["stale_alert_minutes"] = req.ExchangeRate.StaleAlertMinutes
+		}
+		exchangeRateConfig["display_currencies"] = displayCurrencies
+	}
+
+	// Update物流轨迹同步配置（API Key 与 Webhook 密钥留空表示不修改）
+	if req.ShipmentTracking.Submitted {
+		trackingConfig, ok := currentConfig["shipment_tracking"].(map[string]interface{})
+		if !ok {
+			trackingConfig = make(map[string]interface{})
+			currentConfig["shipment_tracking"] = trackingConfig
+		}
+		provider := strings.ToLower(strings.TrimSpace(req.ShipmentTracking.Provider))
+		if provider == "" {
+			provider = service.ShipmentTrackingProvider17Track
+		}
+		if !service.IsValidShipmentTrackingProvider(provider) {
+			response.BadRequest(c, "Unsupported shipment tracking provider")
+			return
+		}
+		if req.ShipmentTracking.AutoCompleteDays < 0 {
+			response.BadRequest(c, "Auto-complete days cannot be negative")
+			return
+		}
+		trackingConfig["enabled"] = req.ShipmentTracking.Enabled
+		trackingConfig["provider"] = provider
+		if key := strings.TrimSpace(req.ShipmentTracking.APIKey); key != "" {
+			trackingConfig["api_key"] = key
+		}
+		if secret := strings.TrimSpace(req.ShipmentTracking.WebhookSecret); secret != "" {
+			trackingConfig["webhook_secret"] = secret
+		}
+		if req.ShipmentTracking.PollIntervalMinutes > 0 {
+			trackingConfig["poll_interval_minutes"] = req.ShipmentTracking.PollIntervalMinutes
+		}
+		trackingConfig["auto_complete_days"] = req.ShipmentTracking.AutoCompleteDays
+	}
+
 	// Update插件平台配置
 	if req.Plugin.Submitted {
 		pluginConfig, ok := currentConfig["plugin"].(map[string]interface{})
@@ -1467,6 +2015,10 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 		response.InternalError(c, "Failed to save config file")
 		return
 	}
+	revision, revisionErr := h.revisionService.Record(beforeConfig, currentConfig, models.ConfigRevisionActionUpdate, &adminID, nil)
+	if revisionErr != nil {
+		log.Printf("record config revision failed: admin=%d err=%v", adminID, revisionErr)
+	}
 
 	hotReloaded := false
 	reloadErrorMessage := ""
@@ -1475,58 +2027,20 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 		reloadErrorMessage = err.Error()
 		// 记录错误但不阻止响应，配置文件已保存成功
 		// 某些配置（如数据库、Redis、JWT）仍需重启才能生效
-		logger.LogOperation(h.db, c, "update", "system_config", nil, map[string]interface{}{
-			"config_sections": []string{"app", "smtp", "security", "rate_limit", "order"},
-			"reload_error":    err.Error(),
-		})
+		logPayload := configRevisionLogPayload(revision)
+		logPayload["reload_error"] = err.Error()
+		logger.LogOperation(h.db, c, "update", "system_config", nil, logPayload)
 	} else {
 		hotReloaded = true
-		postReloadErrors := make([]string, 0, 4)
-
-		if err := config.ReloadLogger(); err != nil {
-			postReloadErrors = append(postReloadErrors, fmt.Sprintf("reload logger failed: %v", err))
-		}
-		if h.emailService != nil {
-			h.emailService.RefreshConfig()
-		}
-
-		if req.Plugin.Submitted && h.pluginManager != nil && h.cfg != nil && previousPluginEnabled != h.cfg.Plugin.Enabled {
-			if h.cfg.Plugin.Enabled {
-				h.pluginManager.Stop()
-				h.pluginManager.Start()
-				pluginRuntimeAction = "started"
-			} else {
-				h.pluginManager.Stop()
-				pluginRuntimeAction = "stopped"
-			}
-		}
-
-		if req.Plugin.Submitted && h.pluginManager != nil && h.cfg != nil && previousPluginEnabled == h.cfg.Plugin.Enabled && h.cfg.Plugin.Enabled {
-			if !jsWorkerRestarted && shouldAutoRestartManagedJSWorker(previousPluginConfig, h.cfg.Plugin) {
-				if err := h.pluginManager.RestartJSWorker(); err != nil {
-					postReloadErrors = append(postReloadErrors, fmt.Sprintf("restart js worker failed: %v", err))
-				} else {
-					jsWorkerRestarted = true
-				}
-			}
-			if shouldReloadGRPCPlugins(previousPluginConfig, h.cfg.Plugin) {
-				reloaded, err := h.reloadEnabledPluginsByRuntime(service.PluginRuntimeGRPC)
-				grpcPluginsReloaded = reloaded
-				if err != nil {
-					postReloadErrors = append(postReloadErrors, fmt.Sprintf("reload grpc plugins failed: %v", err))
-				}
-			}
-		}
-
-		if len(postReloadErrors) > 0 {
-			reloadErrorMessage = strings.Join(postReloadErrors, "; ")
-		}
+		applied := h.applyReloadedSettings(previousPluginEnabled, previousPluginConfig, req.Plugin.Submitted, jsWorkerRestarted)
+		pluginRuntimeAction = applied.pluginRuntimeAction
+		jsWorkerRestarted = applied.jsWorkerRestarted
+		grpcPluginsReloaded = applied.grpcPluginsReloaded
+		reloadErrorMessage = applied.errorMessage
 
 		// 记录操作日志
-		logPayload := map[string]interface{}{
-			"config_sections": []string{"app", "smtp", "security", "rate_limit", "order"},
-			"hot_reload":      true,
-		}
+		logPayload := configRevisionLogPayload(revision)
+		logPayload["hot_reload"] = true
 		if reloadErrorMessage != "" {
 			logPayload["reload_error"] = reloadErrorMessage
 		}
@@ -1542,6 +2056,9 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 	resp := gin.H{
 		"message": "Settings saved and applied. Some configurations (Database, Redis, JWT) require service restart to take effect",
 	}
+	if revision != nil {
+		resp["revision_id"] = revision.ID
+	}
 	if pluginRuntimeAction != "" {
 		resp["plugin_runtime_action"] = pluginRuntimeAction
 	}
@@ -1607,6 +2124,71 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 	response.Success(c, resp)
 }
 
+type settingsReloadResult struct {
+	pluginRuntimeAction string
+	jsWorkerRestarted   bool
+	grpcPluginsReloaded int
+	errorMessage        string
+}
+
+// applyReloadedSettings 配置热更新后同步依赖配置的运行时组件（日志、邮件、插件运行时）
+func (h *SettingsHandler) applyReloadedSettings(previousPluginEnabled bool, previousPluginConfig config.PluginPlatformConfig, pluginChanged bool, jsWorkerRestarted bool) settingsReloadResult {
+	result := settingsReloadResult{jsWorkerRestarted: jsWorkerRestarted}
+	postReloadErrors := make([]string, 0, 4)
+
+	if err := config.ReloadLogger(); err != nil {
+		postReloadErrors = append(postReloadErrors, fmt.Sprintf("reload logger failed: %v", err))
+	}
+	if h.emailService != nil {
+		h.emailService.RefreshConfig()
+	}
+
+	if pluginChanged && h.pluginManager != nil && h.cfg != nil && previousPluginEnabled != h.cfg.Plugin.Enabled {
+		if h.cfg.Plugin.Enabled {
+			h.pluginManager.Stop()
+			h.pluginManager.Start()
+			result.pluginRuntimeAction = "started"
+		} else {
+			h.pluginManager.Stop()
+			result.pluginRuntimeAction = "stopped"
+		}
+	}
+
+	if pluginChanged && h.pluginManager != nil && h.cfg != nil && previousPluginEnabled == h.cfg.Plugin.Enabled && h.cfg.Plugin.Enabled {
+		if !result.jsWorkerRestarted && shouldAutoRestartManagedJSWorker(previousPluginConfig, h.cfg.Plugin) {
+			if err := h.pluginManager.RestartJSWorker(); err != nil {
+				postReloadErrors = append(postReloadErrors, fmt.Sprintf("restart js worker failed: %v", err))
+			} else {
+				result.jsWorkerRestarted = true
+			}
+		}
+		if shouldReloadGRPCPlugins(previousPluginConfig, h.cfg.Plugin) {
+			reloaded, err := h.reloadEnabledPluginsByRuntime(service.PluginRuntimeGRPC)
+			result.grpcPluginsReloaded = reloaded
+			if err != nil {
+				postReloadErrors = append(postReloadErrors, fmt.Sprintf("reload grpc plugins failed: %v", err))
+			}
+		}
+	}
+
+	if len(postReloadErrors) > 0 {
+		result.errorMessage = strings.Join(postReloadErrors, "; ")
+	}
+	return result
+}
+
+// configRevisionLogPayload 操作日志只记录版本号与变更分组，完整差异见配置版本
+func configRevisionLogPayload(revision *models.ConfigRevision) map[string]interface{} {
+	if revision == nil {
+		return map[string]interface{}{"config_sections": []string{}}
+	}
+	return map[string]interface{}{
+		"config_sections": revision.Sections,
+		"revision_id":     revision.ID,
+		"change_count":    revision.ChangeCount,
+	}
+}
+
 // TestSMTP 测试SMTP配置
 func (h *SettingsHandler) TestSMTP(c *gin.Context) {
 	var req struct {
@@ -1621,6 +2203,10 @@ func (h *SettingsHandler) TestSMTP(c *gin.Context) {
 		response.BadRequest(c, "Invalid request parameters")
 		return
 	}
+	if demomode.Enabled() {
+		respondAdminBizError(c, demomode.ErrOutboundBlocked)
+		return
+	}
 
 	// 创建 SMTP 拨号器
 	dialer := gomail.NewDialer(req.Host, req.Port, req.User, req.Password)
@@ -2128,6 +2714,50 @@ func normalizeLowerStringList(values []string) []string {
 	return out
 }
 
+// validCustomsConfig 校验报关设置中的国家代码与内容类别
+func validCustomsConfig(customs config.CustomsConfig) bool {
+	for _, code := range []string{customs.SenderCountry, customs.DefaultOriginCountry} {
+		if code = strings.TrimSpace(code); code != "" && constants.GetCountryByCode(strings.ToUpper(code)) == nil {
+			return false
+		}
+	}
+	switch customs.ContentCategory {
+	case "", "sale_of_goods", "gift", "sample", "documents", "returned_goods", "other":
+	default:
+		return false
+	}
+	return customs.CN22MaxValueMinor >= 0
+}
+
+// validPackageBoxes 校验包装箱目录：编码唯一，尺寸为正数
+func validPackageBoxes(boxes []config.PackageBox) bool {
+	seen := make(map[string]bool, len(boxes))
+	for _, box := range boxes {
+		code := strings.TrimSpace(box.Code)
+		if code == "" || seen[code] {
+			return false
+		}
+		seen[code] = true
+		if box.LengthMM <= 0 || box.WidthMM <= 0 || box.HeightMM <= 0 || box.MaxWeightGrams < 0 || box.TareGrams < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeDisabledCountries 统一为大写国家代码并去重，未知代码视为无效
+func normalizeDisabledCountries(values []string) ([]string, bool) {
+	out := make([]string, 0, len(values))
+	for _, code := range normalizeTrimmedStringList(values) {
+		code = strings.ToUpper(code)
+		if constants.GetCountryByCode(code) == nil {
+			return nil, false
+		}
+		out = append(out, code)
+	}
+	return normalizeTrimmedStringList(out), true
+}
+
 func normalizeTrimmedStringList(values []string) []string {
 	seen := make(map[string]struct{}, len(values))
 	out := make([]string, 0, len(values))
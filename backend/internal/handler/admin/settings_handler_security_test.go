@@ -116,3 +116,36 @@ func TestResolveCaptchaSecretForUpdate(t *testing.T) {
 		}
 	})
 }
+
+func TestNormalizeCaptchaUpdateKeepsOverrideSecrets(t *testing.T) {
+	existing := map[string]interface{}{
+		"provider_keys": map[string]interface{}{
+			"hcaptcha": map[string]interface{}{"site_key": "hc-site", "secret_key": "hc-secret"},
+		},
+	}
+	req := &settingsCaptchaUpdateRequest{
+		Provider:       "cloudflare",
+		SceneProviders: map[string]string{"register": "hcaptcha", "login": "cloudflare", "serial_verify": "builtin"},
+		ProviderKeys:   map[string]settingsCaptchaProviderKeysUpdate{"hcaptcha": {SiteKey: "hc-site-2"}},
+	}
+	scenes, keys, minScore, err := normalizeCaptchaUpdate(existing, req)
+	if err != nil {
+		t.Fatalf("normalize captcha update: %v", err)
+	}
+	if len(scenes) != 2 || scenes["register"] != "hcaptcha" || scenes["serial_verify"] != "builtin" || minScore != 0.5 {
+		t.Fatalf("unexpected scene providers: %#v, min score %v", scenes, minScore)
+	}
+	hcaptcha, _ := keys["hcaptcha"].(map[string]interface{})
+	if len(keys) != 1 || hcaptcha["site_key"] != "hc-site-2" || hcaptcha["secret_key"] != "hc-secret" {
+		t.Fatalf("expected stored hcaptcha secret to be kept, got %#v", keys)
+	}
+
+	req.SceneProviders["bind"] = "recaptcha_v3"
+	if _, _, _, err := normalizeCaptchaUpdate(existing, req); err == nil {
+		t.Fatal("override provider without keys should be rejected")
+	}
+	req.SceneProviders = map[string]string{"login": "geetest"}
+	if _, _, _, err := normalizeCaptchaUpdate(existing, req); err == nil {
+		t.Fatal("unknown provider should be rejected")
+	}
+}
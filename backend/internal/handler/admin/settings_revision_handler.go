@@ -0,0 +1,130 @@
+package admin
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"auralogic/internal/config"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+func parseSettingsRevisionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid revision ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ListSettingsRevisions 系统配置变更版本列表
+func (h *SettingsHandler) ListSettingsRevisions(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+	revisions, total, err := h.revisionService.List(page, limit)
+	if err != nil {
+		response.InternalServerError(c, "Query failed", err)
+		return
+	}
+	response.Paginated(c, revisions, page, limit, total)
+}
+
+// GetSettingsRevision 版本详情（脱敏快照与差异）
+func (h *SettingsHandler) GetSettingsRevision(c *gin.Context) {
+	id, ok := parseSettingsRevisionID(c)
+	if !ok {
+		return
+	}
+	revision, err := h.revisionService.Get(id)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to load revision", err)
+		return
+	}
+	response.Success(c, revision)
+}
+
+// RollbackSettingsRevision 把配置文件恢复到指定版本并热更新，密钥保持当前值
+func (h *SettingsHandler) RollbackSettingsRevision(c *gin.Context) {
+	id, ok := parseSettingsRevisionID(c)
+	if !ok {
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	configPath := config.GetConfigPath()
+	currentConfig, err := readConfigFile(configPath)
+	if err != nil {
+		response.InternalError(c, "Failed to read config file")
+		return
+	}
+	target, restored, err := h.revisionService.BuildRollbackConfig(id, currentConfig)
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to load revision", err)
+		return
+	}
+
+	previousPluginEnabled := false
+	var previousPluginConfig config.PluginPlatformConfig
+	if h.cfg != nil {
+		previousPluginEnabled = h.cfg.Plugin.Enabled
+		previousPluginConfig = h.cfg.Plugin
+	}
+
+	if err := writeConfigFile(configPath, restored); err != nil {
+		response.InternalError(c, "Failed to save config file")
+		return
+	}
+	if err := config.ReloadConfig(); err != nil {
+		// 目标版本在当前程序中已无法通过校验时恢复原配置文件
+		if restoreErr := writeConfigFile(configPath, currentConfig); restoreErr != nil {
+			log.Printf("restore config file after failed rollback failed: admin=%d err=%v", adminID, restoreErr)
+		}
+		response.BadRequest(c, fmt.Sprintf("Revision #%d cannot be applied: %v", target.ID, err))
+		return
+	}
+
+	revision, revisionErr := h.revisionService.Record(currentConfig, restored, models.ConfigRevisionActionRollback, &adminID, &target.ID)
+	if revisionErr != nil {
+		log.Printf("record config rollback revision failed: admin=%d err=%v", adminID, revisionErr)
+	}
+	applied := h.applyReloadedSettings(previousPluginEnabled, previousPluginConfig, true, false)
+
+	logPayload := configRevisionLogPayload(revision)
+	logPayload["rollback_to"] = target.ID
+	logPayload["hot_reload"] = true
+	if applied.errorMessage != "" {
+		logPayload["reload_error"] = applied.errorMessage
+	}
+	logger.LogOperation(h.db, c, "rollback", "system_config", &target.ID, logPayload)
+
+	resp := gin.H{
+		"message":     "Settings rolled back and applied. Some configurations (Database, Redis, JWT) require service restart to take effect",
+		"rollback_to": target.ID,
+	}
+	if revision != nil {
+		resp["revision_id"] = revision.ID
+	}
+	if applied.pluginRuntimeAction != "" {
+		resp["plugin_runtime_action"] = applied.pluginRuntimeAction
+	}
+	if applied.jsWorkerRestarted {
+		resp["js_worker_restarted"] = true
+	}
+	if applied.errorMessage != "" {
+		resp["reload_error"] = applied.errorMessage
+	}
+	response.Success(c, resp)
+}
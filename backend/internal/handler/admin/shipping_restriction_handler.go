@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"strings"
+
+	"auralogic/internal/database"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ShippingRestrictionHandler struct {
+	shippingRestrictions *service.ShippingRestrictionService
+}
+
+func NewShippingRestrictionHandler(shippingRestrictions *service.ShippingRestrictionService) *ShippingRestrictionHandler {
+	return &ShippingRestrictionHandler{shippingRestrictions: shippingRestrictions}
+}
+
+// ListBlocks 配送限制拦截报表
+func (h *ShippingRestrictionHandler) ListBlocks(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+	filter := service.ShippingRestrictionBlockFilter{
+		Country: strings.TrimSpace(c.Query("country")),
+		SKU:     strings.TrimSpace(c.Query("sku")),
+		Source:  strings.TrimSpace(c.Query("source")),
+	}
+	blocks, total, err := h.shippingRestrictions.ListBlocks(filter, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, blocks, page, limit, total)
+}
+
+// ClearBlocks 清空拦截记录
+func (h *ShippingRestrictionHandler) ClearBlocks(c *gin.Context) {
+	deleted, err := h.shippingRestrictions.ClearBlocks()
+	if err != nil {
+		response.InternalServerError(c, "Failed to clear shipping restriction blocks", err)
+		return
+	}
+	logger.LogOperation(database.GetDB(), c, "clear", "shipping_restriction_block", nil, map[string]interface{}{
+		"deleted": deleted,
+	})
+	response.Success(c, gin.H{"deleted": deleted})
+}
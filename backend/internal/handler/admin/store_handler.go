@@ -0,0 +1,335 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/repository"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type StoreHandler struct {
+	storeService *service.StoreService
+}
+
+func NewStoreHandler(storeService *service.StoreService) *StoreHandler {
+	return &StoreHandler{storeService: storeService}
+}
+
+func parseAdminStoreID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid store ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// loadAdminStoreIDs 获取当前管理员被限制的店铺范围；返回 nil 表示不受限制
+func loadAdminStoreIDs(c *gin.Context) ([]uint, error) {
+	if role, _ := middleware.GetUserRole(c); role == "super_admin" {
+		return nil, nil
+	}
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return nil, nil
+	}
+	var storeIDs []uint
+	err := database.GetDB().Model(&models.StoreAdmin{}).
+		Where("user_id = ?", userID).
+		Pluck("store_id", &storeIDs).Error
+	return storeIDs, err
+}
+
+func containsStoreID(storeIDs []uint, storeID uint) bool {
+	for _, id := range storeIDs {
+		if id == storeID {
+			return true
+		}
+	}
+	return false
+}
+
+// adminStoreAllowed 批量操作时逐条校验，allowed 为空表示不限店铺
+func adminStoreAllowed(allowed []uint, storeID *uint) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	return storeID != nil && containsStoreID(allowed, *storeID)
+}
+
+// resolveAdminStoreScope 解析列表查询的店铺范围
+// 支持 store_id 参数筛选；绑定了店铺的管理员只能查看所绑定店铺的数据
+func resolveAdminStoreScope(c *gin.Context) (*repository.StoreScope, bool) {
+	allowed, err := loadAdminStoreIDs(c)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return nil, false
+	}
+
+	var requested uint
+	if raw := c.Query("store_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			response.BadRequest(c, "Invalid store ID")
+			return nil, false
+		}
+		requested = uint(id)
+	}
+
+	if len(allowed) == 0 {
+		if requested == 0 {
+			return nil, true
+		}
+		return &repository.StoreScope{StoreIDs: []uint{requested}}, true
+	}
+	if requested != 0 {
+		if !containsStoreID(allowed, requested) {
+			response.Forbidden(c, "No permission to access this store")
+			return nil, false
+		}
+		return &repository.StoreScope{StoreIDs: []uint{requested}}, true
+	}
+	return &repository.StoreScope{StoreIDs: allowed}, true
+}
+
+// ensureAdminStoreAccess 校验管理员是否可操作属于指定店铺的资源
+func ensureAdminStoreAccess(c *gin.Context, storeID *uint) bool {
+	allowed, err := loadAdminStoreIDs(c)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return false
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	if storeID == nil || !containsStoreID(allowed, *storeID) {
+		response.Forbidden(c, "No permission to access this store")
+		return false
+	}
+	return true
+}
+
+// ensureAdminProductStoreAccess 按商品 ID 校验店铺权限，用于未加载完整商品的接口
+func ensureAdminProductStoreAccess(c *gin.Context, productID uint) bool {
+	allowed, err := loadAdminStoreIDs(c)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return false
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	var product models.Product
+	if err := database.GetDB().Select("id", "store_id").First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.NotFound(c, "Product not found")
+			return false
+		}
+		response.InternalError(c, "Query failed")
+		return false
+	}
+	if !adminStoreAllowed(allowed, product.StoreID) {
+		response.Forbidden(c, "No permission to access this store")
+		return false
+	}
+	return true
+}
+
+// ensureAdminOrderStoreAccess 按订单 ID 校验店铺权限，用于未加载完整订单的接口
+func ensureAdminOrderStoreAccess(c *gin.Context, orderID uint) bool {
+	allowed, err := loadAdminStoreIDs(c)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return false
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	var order models.Order
+	if err := database.GetDB().Select("id", "store_id").First(&order, orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.NotFound(c, "Order not found")
+			return false
+		}
+		response.InternalError(c, "Query failed")
+		return false
+	}
+	if !adminStoreAllowed(allowed, order.StoreID) {
+		response.Forbidden(c, "No permission to access this store")
+		return false
+	}
+	return true
+}
+
+func (h *StoreHandler) respondStoreError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// ListStores 店铺列表
+func (h *StoreHandler) ListStores(c *gin.Context) {
+	stores, err := h.storeService.List()
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": stores})
+}
+
+// GetStore 店铺详情（含绑定的管理员）
+func (h *StoreHandler) GetStore(c *gin.Context) {
+	id, ok := parseAdminStoreID(c)
+	if !ok {
+		return
+	}
+	store, err := h.storeService.Get(id)
+	if err != nil {
+		h.respondStoreError(c, err, "Failed to load store")
+		return
+	}
+	adminIDs, err := h.storeService.ListAdminUserIDs(id)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{
+		"store":     store,
+		"admin_ids": adminIDs,
+	})
+}
+
+// CreateStore 创建店铺
+func (h *StoreHandler) CreateStore(c *gin.Context) {
+	var req service.StoreInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	store, err := h.storeService.Create(req)
+	if err != nil {
+		h.respondStoreError(c, err, "Failed to create store")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create", "store", &store.ID, map[string]interface{}{
+		"code":    store.Code,
+		"name":    store.Name,
+		"domains": req.Domains,
+	})
+	response.Success(c, store)
+}
+
+// UpdateStore 更新店铺
+func (h *StoreHandler) UpdateStore(c *gin.Context) {
+	id, ok := parseAdminStoreID(c)
+	if !ok {
+		return
+	}
+	var req service.StoreInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	store, err := h.storeService.Update(id, req)
+	if err != nil {
+		h.respondStoreError(c, err, "Failed to update store")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "update", "store", &store.ID, map[string]interface{}{
+		"code":      store.Code,
+		"name":      store.Name,
+		"is_active": store.IsActive,
+		"domains":   req.Domains,
+	})
+	response.Success(c, store)
+}
+
+// DeleteStore 删除店铺
+func (h *StoreHandler) DeleteStore(c *gin.Context) {
+	id, ok := parseAdminStoreID(c)
+	if !ok {
+		return
+	}
+	if err := h.storeService.Delete(id); err != nil {
+		h.respondStoreError(c, err, "Failed to delete store")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "delete", "store", &id, nil)
+	response.Success(c, gin.H{"message": "Store deleted"})
+}
+
+// UpdateStoreAdmins 设置店铺管理员（整体替换）
+func (h *StoreHandler) UpdateStoreAdmins(c *gin.Context) {
+	id, ok := parseAdminStoreID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		UserIDs []uint `json:"user_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	adminIDs, err := h.storeService.SetAdmins(id, req.UserIDs)
+	if err != nil {
+		h.respondStoreError(c, err, "Failed to update store admins")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "update_admins", "store", &id, map[string]interface{}{
+		"user_ids": adminIDs,
+	})
+	response.Success(c, gin.H{
+		"store_id":  id,
+		"admin_ids": adminIDs,
+	})
+}
+
+// UpdatePaymentMethodStore 设置付款方式所属店铺
+func (h *StoreHandler) UpdatePaymentMethodStore(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid payment method ID")
+		return
+	}
+	var req struct {
+		StoreID *uint `json:"store_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	if req.StoreID != nil && *req.StoreID == 0 {
+		req.StoreID = nil
+	}
+	if err := h.storeService.SetPaymentMethodStore(uint(id), req.StoreID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.NotFound(c, "Payment method not found")
+			return
+		}
+		h.respondStoreError(c, err, "Failed to update payment method store")
+		return
+	}
+
+	paymentMethodID := uint(id)
+	logger.LogOperation(database.GetDB(), c, "update_store", "payment_method", &paymentMethodID, map[string]interface{}{
+		"store_id": req.StoreID,
+	})
+	response.Success(c, gin.H{
+		"payment_method_id": paymentMethodID,
+		"store_id":          req.StoreID,
+	})
+}
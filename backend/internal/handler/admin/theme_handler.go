@@ -0,0 +1,221 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// maxThemeImportSize 主题文件大小上限
+const maxThemeImportSize = 1 << 20
+
+type ThemeHandler struct {
+	themeService *service.ThemeService
+}
+
+func NewThemeHandler(themeService *service.ThemeService) *ThemeHandler {
+	return &ThemeHandler{themeService: themeService}
+}
+
+func parseAdminThemeID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid theme ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *ThemeHandler) respondThemeError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// ListThemes 主题包列表
+func (h *ThemeHandler) ListThemes(c *gin.Context) {
+	themes, err := h.themeService.List()
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": themes})
+}
+
+// GetTheme 主题包详情
+func (h *ThemeHandler) GetTheme(c *gin.Context) {
+	id, ok := parseAdminThemeID(c)
+	if !ok {
+		return
+	}
+	theme, err := h.themeService.Get(id)
+	if err != nil {
+		h.respondThemeError(c, err, "Failed to load theme")
+		return
+	}
+	response.Success(c, theme)
+}
+
+// CreateTheme 创建主题包
+func (h *ThemeHandler) CreateTheme(c *gin.Context) {
+	var req service.ThemePackInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	theme, err := h.themeService.Create(req, adminID)
+	if err != nil {
+		h.respondThemeError(c, err, "Failed to create theme")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create", "theme_pack", &theme.ID, map[string]interface{}{
+		"slug": theme.Slug,
+		"name": theme.Name,
+	})
+	response.Success(c, theme)
+}
+
+// UpdateTheme 更新主题包
+func (h *ThemeHandler) UpdateTheme(c *gin.Context) {
+	id, ok := parseAdminThemeID(c)
+	if !ok {
+		return
+	}
+	var req service.ThemePackInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	theme, err := h.themeService.Update(id, req, adminID)
+	if err != nil {
+		h.respondThemeError(c, err, "Failed to update theme")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "update", "theme_pack", &theme.ID, map[string]interface{}{
+		"slug": theme.Slug,
+		"name": theme.Name,
+	})
+	response.Success(c, theme)
+}
+
+// DeleteTheme 删除主题包
+func (h *ThemeHandler) DeleteTheme(c *gin.Context) {
+	id, ok := parseAdminThemeID(c)
+	if !ok {
+		return
+	}
+	if err := h.themeService.Delete(id); err != nil {
+		h.respondThemeError(c, err, "Failed to delete theme")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "delete", "theme_pack", &id, nil)
+	response.Success(c, gin.H{"message": "Theme deleted"})
+}
+
+// ActivateTheme 启用主题包
+func (h *ThemeHandler) ActivateTheme(c *gin.Context) {
+	id, ok := parseAdminThemeID(c)
+	if !ok {
+		return
+	}
+	if err := h.themeService.Activate(id); err != nil {
+		h.respondThemeError(c, err, "Failed to activate theme")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "activate", "theme_pack", &id, nil)
+	response.Success(c, gin.H{"active_theme_id": id})
+}
+
+// DeactivateTheme 停用当前主题包，恢复默认主题
+func (h *ThemeHandler) DeactivateTheme(c *gin.Context) {
+	if err := h.themeService.Activate(0); err != nil {
+		h.respondThemeError(c, err, "Failed to deactivate theme")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "deactivate", "theme_pack", nil, nil)
+	response.Success(c, gin.H{"active_theme_id": nil})
+}
+
+// ExportTheme 导出主题包 JSON 文件
+func (h *ThemeHandler) ExportTheme(c *gin.Context) {
+	id, ok := parseAdminThemeID(c)
+	if !ok {
+		return
+	}
+	export, err := h.themeService.Export(id)
+	if err != nil {
+		h.respondThemeError(c, err, "Failed to export theme")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="theme-%s.json"`, export.Slug))
+	c.IndentedJSON(http.StatusOK, export)
+}
+
+// ImportTheme 导入主题包 JSON（请求体或 multipart 的 file 字段）
+func (h *ThemeHandler) ImportTheme(c *gin.Context) {
+	adminID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+
+	var reader io.Reader = c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		file, err := c.FormFile("file")
+		if err != nil {
+			response.BadRequest(c, "Theme file is required")
+			return
+		}
+		f, openErr := file.Open()
+		if openErr != nil {
+			response.BadRequest(c, "Failed to read theme file")
+			return
+		}
+		defer f.Close()
+		reader = f
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, maxThemeImportSize+1))
+	if err != nil {
+		response.BadRequest(c, "Failed to read theme file")
+		return
+	}
+	if len(data) > maxThemeImportSize {
+		response.BadRequest(c, "Theme file is too large")
+		return
+	}
+
+	overwrite := c.Query("overwrite") == "true"
+	theme, err := h.themeService.Import(data, overwrite, adminID)
+	if err != nil {
+		h.respondThemeError(c, err, "Failed to import theme")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "import", "theme_pack", &theme.ID, map[string]interface{}{
+		"slug":      theme.Slug,
+		"overwrite": overwrite,
+	})
+	response.Success(c, theme)
+}
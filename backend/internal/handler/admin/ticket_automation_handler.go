@@ -0,0 +1,130 @@
+package admin
+
+import (
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TicketAutomationHandler struct {
+	automationService *service.TicketAutomationService
+}
+
+func NewTicketAutomationHandler(automationService *service.TicketAutomationService) *TicketAutomationHandler {
+	return &TicketAutomationHandler{automationService: automationService}
+}
+
+func (h *TicketAutomationHandler) respondAutomationError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// GetTicketAutomationMeta 条件构建器元数据：触发事件、字段与运算符、动作类型、可选优先级
+func (h *TicketAutomationHandler) GetTicketAutomationMeta(c *gin.Context) {
+	response.Success(c, gin.H{
+		"triggers": service.TicketAutomationTriggers(),
+		"fields":   service.TicketAutomationFields(),
+		"actions":  service.TicketAutomationActionTypes(),
+		"priorities": []models.TicketPriority{
+			models.TicketPriorityLow,
+			models.TicketPriorityNormal,
+			models.TicketPriorityHigh,
+			models.TicketPriorityUrgent,
+		},
+	})
+}
+
+// ListTicketAutomationRules 规则列表（按执行顺序）
+func (h *TicketAutomationHandler) ListTicketAutomationRules(c *gin.Context) {
+	rules, err := h.automationService.ListRules(c.Query("trigger"))
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": rules})
+}
+
+// GetTicketAutomationRule 规则详情
+func (h *TicketAutomationHandler) GetTicketAutomationRule(c *gin.Context) {
+	id, ok := parseOrderAutomationRuleID(c)
+	if !ok {
+		return
+	}
+	rule, err := h.automationService.GetRule(id)
+	if err != nil {
+		h.respondAutomationError(c, err, "Failed to load automation rule")
+		return
+	}
+	response.Success(c, rule)
+}
+
+// CreateTicketAutomationRule 创建规则
+func (h *TicketAutomationHandler) CreateTicketAutomationRule(c *gin.Context) {
+	var req service.TicketAutomationRuleInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	var createdBy *uint
+	if adminID, ok := middleware.GetUserID(c); ok {
+		createdBy = &adminID
+	}
+	rule, err := h.automationService.CreateRule(req, createdBy)
+	if err != nil {
+		h.respondAutomationError(c, err, "Failed to create automation rule")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create", "ticket_automation_rule", &rule.ID, map[string]interface{}{
+		"name":    rule.Name,
+		"trigger": rule.Trigger,
+		"enabled": rule.Enabled,
+	})
+	response.Success(c, rule)
+}
+
+// UpdateTicketAutomationRule 更新规则
+func (h *TicketAutomationHandler) UpdateTicketAutomationRule(c *gin.Context) {
+	id, ok := parseOrderAutomationRuleID(c)
+	if !ok {
+		return
+	}
+	var req service.TicketAutomationRuleInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	rule, err := h.automationService.UpdateRule(id, req)
+	if err != nil {
+		h.respondAutomationError(c, err, "Failed to update automation rule")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "update", "ticket_automation_rule", &rule.ID, map[string]interface{}{
+		"name":    rule.Name,
+		"trigger": rule.Trigger,
+		"enabled": rule.Enabled,
+	})
+	response.Success(c, rule)
+}
+
+// DeleteTicketAutomationRule 删除规则
+func (h *TicketAutomationHandler) DeleteTicketAutomationRule(c *gin.Context) {
+	id, ok := parseOrderAutomationRuleID(c)
+	if !ok {
+		return
+	}
+	if err := h.automationService.DeleteRule(id); err != nil {
+		h.respondAutomationError(c, err, "Failed to delete automation rule")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "delete", "ticket_automation_rule", &id, nil)
+	response.Success(c, gin.H{"message": "Automation rule deleted"})
+}
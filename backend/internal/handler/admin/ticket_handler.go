@@ -14,10 +14,14 @@ import (
 	"auralogic/internal/config"
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
+	"auralogic/internal/pkg/listfilter"
+	"auralogic/internal/pkg/logger"
 	"auralogic/internal/pkg/response"
 	"auralogic/internal/pkg/ticketbiz"
 	"auralogic/internal/pkg/utils"
 	"auralogic/internal/pkg/validator"
+	"auralogic/internal/repository"
 	"auralogic/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -28,10 +32,16 @@ type TicketHandler struct {
 	db            *gorm.DB
 	emailService  *service.EmailService
 	pluginManager *service.PluginManagerService
+	attachments   *service.TicketAttachmentService
 }
 
 func NewTicketHandler(db *gorm.DB, emailService *service.EmailService, pluginManager *service.PluginManagerService) *TicketHandler {
-	return &TicketHandler{db: db, emailService: emailService, pluginManager: pluginManager}
+	return &TicketHandler{
+		db:            db,
+		emailService:  emailService,
+		pluginManager: pluginManager,
+		attachments:   service.NewTicketAttachmentService(db),
+	}
 }
 
 // ListTickets 获取工单列表
@@ -41,6 +51,12 @@ func (h *TicketHandler) ListTickets(c *gin.Context) {
 	excludeStatus := c.Query("exclude_status")
 	search := c.Query("search")
 	assignedTo := c.Query("assigned_to")
+	tags := splitTicketTagQuery(c.Query("tag"))
+	advanced, err := listfilter.Parse(c.Query("filter"), repository.TicketListFilterSchema)
+	if err != nil {
+		respondAdminBizError(c, err)
+		return
+	}
 
 	var tickets []models.Ticket
 	var total int64
@@ -53,7 +69,7 @@ func (h *TicketHandler) ListTickets(c *gin.Context) {
 		query = query.Where("status != ?", excludeStatus)
 	}
 	if search != "" {
-		query = query.Where("ticket_no LIKE ? OR subject LIKE ?", "%"+search+"%", "%"+search+"%")
+		query = dbutil.WhereContainsFold(query, search, "ticket_no", "subject")
 	}
 	if assignedTo == "me" {
 		adminID, adminIDOK := middleware.RequireUserID(c)
@@ -64,6 +80,8 @@ func (h *TicketHandler) ListTickets(c *gin.Context) {
 	} else if assignedTo == "unassigned" {
 		query = query.Where("assigned_to IS NULL")
 	}
+	query = service.FilterTicketsByTags(query, tags)
+	query = advanced.Apply(query)
 
 	query.Count(&total)
 
@@ -87,6 +105,17 @@ func (h *TicketHandler) ListTickets(c *gin.Context) {
 	response.Paginated(c, tickets, page, limit, total)
 }
 
+// splitTicketTagQuery 逗号分隔的标签筛选，需同时包含全部标签
+func splitTicketTagQuery(raw string) []string {
+	tags := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		if tag := strings.TrimSpace(part); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
 // GetTicket 获取工单详情
 func (h *TicketHandler) GetTicket(c *gin.Context) {
 	adminID, adminIDOK := middleware.RequireUserID(c)
@@ -132,6 +161,7 @@ func (h *TicketHandler) GetTicket(c *gin.Context) {
 		}(h.buildTicketHookExecutionContext(c, adminID, ticket.ID), hookPayload, adminID, ticket.ID)
 	}
 
+	h.attachments.SignTicket(&ticket)
 	response.Success(c, ticket)
 }
 
@@ -185,6 +215,7 @@ func (h *TicketHandler) GetTicketMessages(c *gin.Context) {
 		}(h.buildTicketHookExecutionContext(c, adminID, uint(ticketID)), hookPayload, adminID, uint(ticketID))
 	}
 
+	h.attachments.SignMessages(uint(ticketID), messages)
 	response.Success(c, messages)
 }
 
@@ -456,7 +487,7 @@ func (h *TicketHandler) SendMessage(c *gin.Context) {
 	}
 
 	// 清理消息内容，防止XSS
-	sanitizedContent := validator.SanitizeMarkdown(req.Content)
+	sanitizedContent := service.StripTicketAttachmentSignatures(validator.SanitizeMarkdown(req.Content))
 
 	// 检查内容长度限制
 	cfg := config.GetConfig()
@@ -531,7 +562,7 @@ func (h *TicketHandler) SendMessage(c *gin.Context) {
 		}(hookExecCtx, afterPayload, adminID, ticket.ID)
 	}
 
-	response.Success(c, message)
+	response.Success(c, h.attachments.SignedMessage(message))
 
 	// 发送管理员回复通知邮件（通知用户）
 	if h.emailService != nil {
@@ -541,12 +572,25 @@ func (h *TicketHandler) SendMessage(c *gin.Context) {
 
 // UpdateTicketRequest 更新工单请求
 type UpdateTicketRequest struct {
-	Status     string `json:"status"`
-	Priority   string `json:"priority"`
-	AssignedTo *uint  `json:"assigned_to"`
+	Status     string    `json:"status"`
+	Priority   string    `json:"priority"`
+	AssignedTo *uint     `json:"assigned_to"`
+	Tags       *[]string `json:"tags"` // 非空时整体替换标签
 }
 
 // UpdateTicket 更新工单
+// ticketStatusLogAction 工单状态变更对应的操作日志 action
+func ticketStatusLogAction(status models.TicketStatus) string {
+	switch status {
+	case models.TicketStatusResolved:
+		return "resolve"
+	case models.TicketStatusClosed:
+		return "close"
+	default:
+		return "update_status"
+	}
+}
+
 func (h *TicketHandler) UpdateTicket(c *gin.Context) {
 	adminID, adminIDOK := middleware.RequireUserID(c)
 	if !adminIDOK {
@@ -572,6 +616,7 @@ func (h *TicketHandler) UpdateTicket(c *gin.Context) {
 	beforeStatus := ticket.Status
 	beforePriority := ticket.Priority
 	beforeAssignedTo := ticket.AssignedTo
+	beforeTags := ticket.Tags
 	hookExecCtx := h.buildTicketHookExecutionContext(c, adminID, ticket.ID)
 
 	if h.pluginManager != nil {
@@ -679,8 +724,28 @@ func (h *TicketHandler) UpdateTicket(c *gin.Context) {
 		updates["assigned_to"] = req.AssignedTo
 	}
 
-	if len(updates) > 0 {
-		if err := h.db.Model(&ticket).Updates(updates).Error; err != nil {
+	var tags []string
+	if req.Tags != nil {
+		tags, err = service.NormalizeTicketTags(*req.Tags)
+		if err != nil {
+			respondAdminBizError(c, err)
+			return
+		}
+	}
+
+	if len(updates) > 0 || req.Tags != nil {
+		err := h.db.Transaction(func(tx *gorm.DB) error {
+			if len(updates) > 0 {
+				if err := tx.Model(&ticket).Updates(updates).Error; err != nil {
+					return err
+				}
+			}
+			if req.Tags != nil {
+				return tx.Model(&ticket).Select("tags").Updates(&models.Ticket{Tags: tags}).Error
+			}
+			return nil
+		})
+		if err != nil {
 			response.InternalError(c, "Update failed")
 			return
 		}
@@ -689,6 +754,15 @@ func (h *TicketHandler) UpdateTicket(c *gin.Context) {
 	// 重新加载工单
 	h.db.Preload("User").Preload("AssignedUser").First(&ticket, ticketID)
 
+	// 状态变更写入操作日志（管理员工作量统计依赖 resolve/close 记录）
+	if ticket.Status != beforeStatus {
+		logger.LogOperation(h.db, c, ticketStatusLogAction(ticket.Status), "ticket", &ticket.ID, map[string]interface{}{
+			"ticket_no":     ticket.TicketNo,
+			"status_before": beforeStatus,
+			"status_after":  ticket.Status,
+		})
+	}
+
 	if h.pluginManager != nil {
 		afterPayload := map[string]interface{}{
 			"ticket_id":          ticket.ID,
@@ -700,6 +774,8 @@ func (h *TicketHandler) UpdateTicket(c *gin.Context) {
 			"priority_after":     ticket.Priority,
 			"assigned_to_before": beforeAssignedTo,
 			"assigned_to_after":  ticket.AssignedTo,
+			"tags_before":        beforeTags,
+			"tags_after":         ticket.Tags,
 			"source":             "admin_api",
 		}
 		go func(execCtx *service.ExecutionContext, payload map[string]interface{}, aid uint, tid uint) {
@@ -794,22 +870,39 @@ func (h *TicketHandler) GetSharedOrder(c *gin.Context) {
 
 	// 用户主动分享订单到工单即视为同意客服查看所有信息，不再隐藏隐私信息
 	// 客服可以查看完整的订单详情，包括地址等
-
-	response.Success(c, gin.H{
+	payload := gin.H{
 		"order":  order,
 		"access": access,
-	})
+	}
+
+	// 交付上下文：卡密始终脱敏；隐私订单未授权查看隐私时，付款数据按隐私订单的打码策略处理
+	if access.CanView {
+		privacyProtected := order.PrivacyProtected && !access.CanViewPrivacy
+		deliveryContext, err := service.BuildSharedOrderContext(h.db, &order, resolveAdminFieldMask(c), privacyProtected)
+		if err != nil {
+			log.Printf("admin.get_shared_order failed to load delivery context: ticket=%d order=%d err=%v", ticketID, orderID, err)
+		} else {
+			payload["virtual_stocks"] = deliveryContext.VirtualStocks
+			payload["payment"] = deliveryContext.Payment
+			payload["shipment_history"] = deliveryContext.ShipmentHistory
+		}
+	}
+
+	response.Success(c, payload)
 }
 
+const ticketStatsTagLimit = 20
+
 // GetTicketStats 获取工单统计
 func (h *TicketHandler) GetTicketStats(c *gin.Context) {
 	var stats struct {
-		Total      int64 `json:"total"`
-		Open       int64 `json:"open"`
-		Processing int64 `json:"processing"`
-		Resolved   int64 `json:"resolved"`
-		Closed     int64 `json:"closed"`
-		Unread     int64 `json:"unread"`
+		Total      int64                    `json:"total"`
+		Open       int64                    `json:"open"`
+		Processing int64                    `json:"processing"`
+		Resolved   int64                    `json:"resolved"`
+		Closed     int64                    `json:"closed"`
+		Unread     int64                    `json:"unread"`
+		Tags       []service.TicketTagCount `json:"tags"` // 使用最多的标签
 	}
 
 	h.db.Model(&models.Ticket{}).Count(&stats.Total)
@@ -818,6 +911,12 @@ func (h *TicketHandler) GetTicketStats(c *gin.Context) {
 	h.db.Model(&models.Ticket{}).Where("status = ?", "resolved").Count(&stats.Resolved)
 	h.db.Model(&models.Ticket{}).Where("status = ?", "closed").Count(&stats.Closed)
 	h.db.Model(&models.Ticket{}).Where("unread_count_admin > 0").Count(&stats.Unread)
+	tags, err := service.CountTicketTags(h.db, ticketStatsTagLimit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	stats.Tags = tags
 
 	response.Success(c, stats)
 }
@@ -980,7 +1079,21 @@ func (h *TicketHandler) UploadFile(c *gin.Context) {
 		return
 	}
 
-	fileURL := fmt.Sprintf("%s/uploads/tickets/%s/%s", cfg.App.URL, dateDir, filename)
+	relPath := dateDir + "/" + filename
+	if err := h.attachments.Register(&models.TicketAttachment{
+		TicketID:     ticket.ID,
+		Path:         relPath,
+		UploaderType: "admin",
+		UploaderID:   adminID,
+		OriginalName: truncateString(file.Filename, 255),
+		Size:         file.Size,
+	}); err != nil {
+		_ = os.Remove(targetPath)
+		response.InternalError(c, "Failed to save file")
+		return
+	}
+
+	fileURL := fmt.Sprintf("%s/uploads/tickets/%s", cfg.App.URL, relPath)
 
 	if h.pluginManager != nil {
 		afterPayload := map[string]interface{}{
@@ -1006,9 +1119,10 @@ func (h *TicketHandler) UploadFile(c *gin.Context) {
 	}
 
 	response.Success(c, gin.H{
-		"url":      fileURL,
-		"filename": filename,
-		"size":     file.Size,
+		"url":         fileURL,
+		"preview_url": service.SignedTicketAttachmentURL(relPath, time.Now()),
+		"filename":    filename,
+		"size":        file.Size,
 	})
 }
 
@@ -1,13 +1,17 @@
 package admin
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"auralogic/internal/config"
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/listfilter"
 	"auralogic/internal/pkg/logger"
 	"auralogic/internal/pkg/password"
 	"auralogic/internal/pkg/response"
@@ -24,6 +28,7 @@ type UserHandler struct {
 	db            *gorm.DB
 	cfg           *config.Config
 	pluginManager *service.PluginManagerService
+	emailService  *service.EmailService
 }
 
 func NewUserHandler(userRepo *repository.UserRepository, db *gorm.DB, cfg *config.Config, pluginManager *service.PluginManagerService) *UserHandler {
@@ -36,6 +41,11 @@ func NewUserHandler(userRepo *repository.UserRepository, db *gorm.DB, cfg *confi
 	}
 }
 
+// SetEmailService 注入邮件服务（管理员修改用户邮箱后发送验证邮件）
+func (h *UserHandler) SetEmailService(emailService *service.EmailService) {
+	h.emailService = emailService
+}
+
 var userConsumptionStatuses = []models.OrderStatus{
 	models.OrderStatusDraft,
 	models.OrderStatusNeedResubmit,
@@ -67,23 +77,26 @@ func (h *UserHandler) enrichUserConsumptionStats(user *models.User) {
 // userToResponse converts a User model to a safe response map with explicit fields
 func userToResponse(user *models.User) gin.H {
 	resp := gin.H{
-		"id":                user.ID,
-		"uuid":              user.UUID,
-		"email":             user.Email,
-		"name":              user.Name,
-		"avatar":            user.Avatar,
-		"role":              user.Role,
-		"is_active":         user.IsActive,
-		"email_verified":    user.EmailVerified,
-		"locale":            user.Locale,
-		"last_login_ip":     user.LastLoginIP,
-		"register_ip":       user.RegisterIP,
-		"country":           user.Country,
-		"last_login_at":     user.LastLoginAt,
-		"total_spent_minor": user.TotalSpentMinor,
-		"total_order_count": user.TotalOrderCount,
-		"created_at":        user.CreatedAt,
-		"updated_at":        user.UpdatedAt,
+		"id":                        user.ID,
+		"uuid":                      user.UUID,
+		"email":                     user.Email,
+		"name":                      user.Name,
+		"avatar":                    user.Avatar,
+		"role":                      user.Role,
+		"is_active":                 user.IsActive,
+		"email_verified":            user.EmailVerified,
+		"email_verification_exempt": user.EmailVerificationExempt,
+		"email_changed_at":          user.EmailChangedAt,
+		"locale":                    user.Locale,
+		"customer_tier":             user.CustomerTier,
+		"last_login_ip":             user.LastLoginIP,
+		"register_ip":               user.RegisterIP,
+		"country":                   user.Country,
+		"last_login_at":             user.LastLoginAt,
+		"total_spent_minor":         user.TotalSpentMinor,
+		"total_order_count":         user.TotalOrderCount,
+		"created_at":                user.CreatedAt,
+		"updated_at":                user.UpdatedAt,
 	}
 	if user.Phone != nil {
 		resp["phone"] = user.Phone
@@ -120,6 +133,11 @@ func parseUserListFilters(c *gin.Context) (repository.UserListFilters, string, b
 	if !ok {
 		return filters, "Invalid has_phone parameter", false
 	}
+	advanced, err := listfilter.Parse(c.Query("filter"), repository.UserListFilterSchema)
+	if err != nil {
+		return filters, err.Error(), false
+	}
+	filters.Advanced = advanced
 
 	return filters, "", true
 }
@@ -500,10 +518,14 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	}
 
 	var req struct {
-		Name     string  `json:"name"`
-		Role     string  `json:"role"`
-		IsActive *bool   `json:"is_active"`
-		Password *string `json:"password" binding:"omitempty,min=8"`
+		Name                    string  `json:"name"`
+		Role                    string  `json:"role"`
+		IsActive                *bool   `json:"is_active"`
+		Password                *string `json:"password" binding:"omitempty,min=8"`
+		Email                   *string `json:"email" binding:"omitempty,email"`
+		EmailVerified           *bool   `json:"email_verified"`
+		EmailVerificationExempt *bool   `json:"email_verification_exempt"`
+		CustomerTier            *string `json:"customer_tier"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -627,11 +649,58 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		user.IsActive = *req.IsActive
 	}
 
+	// 更换邮箱后需要重新验证，宽限期从更换时刻重新计算
+	emailChanged := false
+	oldEmail := user.Email
+	if req.Email != nil {
+		newEmail := strings.ToLower(strings.TrimSpace(*req.Email))
+		if newEmail != "" && newEmail != user.Email {
+			if existing, err := h.userRepo.FindByEmail(newEmail); err == nil && existing.ID != user.ID {
+				response.Conflict(c, "Email already in use")
+				return
+			} else if err != nil && err != gorm.ErrRecordNotFound {
+				response.InternalError(c, "Query failed")
+				return
+			}
+			now := models.NowFunc()
+			user.Email = newEmail
+			user.EmailVerified = !h.cfg.Security.Login.RequireEmailVerification
+			user.EmailChangedAt = &now
+			emailChanged = true
+		}
+	}
+	if req.EmailVerified != nil {
+		user.EmailVerified = *req.EmailVerified
+	}
+	if req.EmailVerificationExempt != nil {
+		user.EmailVerificationExempt = *req.EmailVerificationExempt
+	}
+	if req.CustomerTier != nil {
+		tier, err := service.NormalizeCustomerTier(*req.CustomerTier)
+		if err != nil {
+			if respondAdminBizError(c, err) {
+				return
+			}
+			response.BadRequest(c, err.Error())
+			return
+		}
+		user.CustomerTier = tier
+	}
+
 	if err := h.userRepo.Update(user); err != nil {
 		response.InternalError(c, "UpdateFailed")
 		return
 	}
 
+	if emailChanged {
+		if err := service.SyncUserEmailReferences(h.db, user.ID, oldEmail, user.Email); err != nil {
+			log.Printf("sync user email references failed: user_id=%d err=%v", user.ID, err)
+		}
+		if !user.EmailVerified {
+			h.sendVerificationEmail(user)
+		}
+	}
+
 	// 角色变更时清除权限缓存
 	if req.Role != "" {
 		middleware.InvalidatePermissionCache(user.ID)
@@ -647,6 +716,19 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		// Never log plaintext password.
 		details["password_changed"] = true
 	}
+	if emailChanged {
+		details["old_email"] = oldEmail
+		details["email"] = user.Email
+	}
+	if req.EmailVerified != nil {
+		details["email_verified"] = *req.EmailVerified
+	}
+	if req.EmailVerificationExempt != nil {
+		details["email_verification_exempt"] = *req.EmailVerificationExempt
+	}
+	if req.CustomerTier != nil {
+		details["customer_tier"] = user.CustomerTier
+	}
 	logger.LogUserOperation(h.db, c, "update", user.ID, details)
 
 	if h.pluginManager != nil {
@@ -677,6 +759,32 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	response.Success(c, userToResponse(user))
 }
 
+// sendVerificationEmail 作废旧的验证链接并向新邮箱发送验证邮件
+func (h *UserHandler) sendVerificationEmail(user *models.User) {
+	if h.emailService == nil {
+		return
+	}
+	h.db.Model(&models.EmailVerificationToken{}).
+		Where("user_id = ? AND used = ?", user.ID, false).
+		Update("used", true)
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Printf("generate verification token failed: user_id=%d err=%v", user.ID, err)
+		return
+	}
+	token := hex.EncodeToString(b)
+	if err := h.db.Create(&models.EmailVerificationToken{
+		Token:     token,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	}).Error; err != nil {
+		log.Printf("create verification token failed: user_id=%d err=%v", user.ID, err)
+		return
+	}
+	go h.emailService.SendVerificationEmail(user.Email, user.Name, token, user.Locale)
+}
+
 // DeleteUser DeleteUser
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
@@ -23,6 +24,7 @@ type VirtualInventoryHandler struct {
 	service       *service.VirtualInventoryService
 	db            *gorm.DB
 	pluginManager *service.PluginManagerService
+	emailService  *service.EmailService
 }
 
 func NewVirtualInventoryHandler(service *service.VirtualInventoryService, db *gorm.DB, pluginManager *service.PluginManagerService) *VirtualInventoryHandler {
@@ -72,6 +74,7 @@ func buildVirtualInventoryHookPayload(inventory *models.VirtualInventory) map[st
 		"description":          inventory.Description,
 		"total_limit":          inventory.TotalLimit,
 		"allow_inline_iframe":  inventory.AllowInlineIframe,
+		"delivery_order":       inventory.DeliveryOrder,
 		"is_active":            inventory.IsActive,
 		"notes":                inventory.Notes,
 		"created_at":           inventory.CreatedAt,
@@ -92,6 +95,7 @@ func buildVirtualInventoryBindingHookPayload(binding *models.ProductVirtualInven
 		"attributes_hash":      binding.AttributesHash,
 		"is_random":            binding.IsRandom,
 		"priority":             binding.Priority,
+		"delivery_order":       binding.DeliveryOrder,
 		"notes":                binding.Notes,
 		"created_at":           binding.CreatedAt,
 		"updated_at":           binding.UpdatedAt,
@@ -172,6 +176,7 @@ func (h *VirtualInventoryHandler) CreateVirtualInventory(c *gin.Context) {
 		Description       string `json:"description"`
 		TotalLimit        int64  `json:"total_limit"`
 		AllowInlineIframe bool   `json:"allow_inline_iframe"`
+		DeliveryOrder     string `json:"delivery_order"`
 		IsActive          bool   `json:"is_active"`
 		Notes             string `json:"notes"`
 	}
@@ -239,6 +244,7 @@ func (h *VirtualInventoryHandler) CreateVirtualInventory(c *gin.Context) {
 		Description:       req.Description,
 		TotalLimit:        req.TotalLimit,
 		AllowInlineIframe: invType == models.VirtualInventoryTypeScript && req.AllowInlineIframe,
+		DeliveryOrder:     req.DeliveryOrder,
 		IsActive:          req.IsActive,
 		Notes:             req.Notes,
 	}
@@ -310,6 +316,7 @@ func (h *VirtualInventoryHandler) UpdateVirtualInventory(c *gin.Context) {
 		Description       string  `json:"description"`
 		TotalLimit        *int64  `json:"total_limit"`
 		AllowInlineIframe *bool   `json:"allow_inline_iframe"`
+		DeliveryOrder     *string `json:"delivery_order"`
 		IsActive          *bool   `json:"is_active"`
 		Notes             string  `json:"notes"`
 	}
@@ -406,6 +413,9 @@ func (h *VirtualInventoryHandler) UpdateVirtualInventory(c *gin.Context) {
 	} else if req.AllowInlineIframe != nil {
 		updates["allow_inline_iframe"] = *req.AllowInlineIframe
 	}
+	if req.DeliveryOrder != nil {
+		updates["delivery_order"] = *req.DeliveryOrder
+	}
 	if req.Notes != "" {
 		updates["notes"] = req.Notes
 	}
@@ -563,7 +573,7 @@ func (h *VirtualInventoryHandler) ImportStock(c *gin.Context) {
 }
 
 // handleFileImport 处理文件导入
-func (h *VirtualInventoryHandler) handleFileImport(virtualInventoryID uint, file *multipart.FileHeader, importedBy string) (int, error) {
+func (h *VirtualInventoryHandler) handleFileImport(virtualInventoryID uint, file *multipart.FileHeader, importedBy string, expiresAt *time.Time) (int, error) {
 	// 检查文件类型
 	ext := strings.ToLower(filepath.Ext(file.Filename))
 
@@ -596,7 +606,7 @@ func (h *VirtualInventoryHandler) handleFileImport(virtualInventoryID uint, file
 			return 0, fmt.Errorf("failed to save temp file: %w", err)
 		}
 
-		return h.service.ImportFromExcel(virtualInventoryID, tempPath, importedBy)
+		return h.service.ImportFromExcel(virtualInventoryID, tempPath, importedBy, expiresAt)
 
 	case ".txt":
 		// txt文件读取内容后调用ImportFromText
@@ -604,11 +614,11 @@ func (h *VirtualInventoryHandler) handleFileImport(virtualInventoryID uint, file
 		if err != nil {
 			return 0, fmt.Errorf("failed to read txt file: %w", err)
 		}
-		return h.service.ImportFromText(virtualInventoryID, string(content), importedBy)
+		return h.service.ImportFromText(virtualInventoryID, string(content), importedBy, expiresAt)
 
 	case ".csv":
 		// CSV导入
-		return h.service.ImportFromCSV(virtualInventoryID, src, importedBy)
+		return h.service.ImportFromCSV(virtualInventoryID, src, importedBy, expiresAt)
 
 	default:
 		return 0, bizerr.New("virtual_inventory.unsupportedFileType", "Unsupported file type").
@@ -698,6 +708,12 @@ func (h *VirtualInventoryHandler) handleImportStockRequest(c *gin.Context, virtu
 		}
 	}
 
+	expiresAt, err := parseVirtualStockExpiresAt(c.PostForm("expires_at"))
+	if err != nil {
+		respondAdminBizError(c, err)
+		return
+	}
+
 	importedBy := c.GetString("user_email")
 	var count int
 	var importErr error
@@ -709,13 +725,13 @@ func (h *VirtualInventoryHandler) handleImportStockRequest(c *gin.Context, virtu
 			response.BadRequest(c, "File upload failed")
 			return
 		}
-		count, importErr = h.handleFileImport(virtualInventoryID, file, importedBy)
+		count, importErr = h.handleFileImport(virtualInventoryID, file, importedBy, expiresAt)
 	case "text":
 		if content == "" {
 			response.BizError(c, "Content cannot be empty", "virtual_inventory.contentRequired", nil)
 			return
 		}
-		count, importErr = h.service.ImportFromText(virtualInventoryID, content, importedBy)
+		count, importErr = h.service.ImportFromText(virtualInventoryID, content, importedBy, expiresAt)
 	default:
 		response.BizError(c, "Invalid import type", "virtual_inventory.importTypeInvalid", nil)
 		return
@@ -854,8 +870,9 @@ func (h *VirtualInventoryHandler) CreateStockManually(c *gin.Context) {
 	}
 
 	var req struct {
-		Content string `json:"content" binding:"required"`
-		Remark  string `json:"remark"`
+		Content   string  `json:"content" binding:"required"`
+		Remark    string  `json:"remark"`
+		ExpiresAt *string `json:"expires_at"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -907,9 +924,16 @@ func (h *VirtualInventoryHandler) CreateStockManually(c *gin.Context) {
 		}
 	}
 
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		if expiresAt, err = parseVirtualStockExpiresAt(*req.ExpiresAt); err != nil {
+			respondAdminBizError(c, err)
+			return
+		}
+	}
 	importedBy := c.GetString("user_email")
 
-	stock, err := h.service.CreateStockManually(id, req.Content, req.Remark, importedBy)
+	stock, err := h.service.CreateStockManually(id, req.Content, req.Remark, importedBy, expiresAt)
 	if err != nil {
 		if respondAdminBizError(c, err) {
 			return
@@ -975,6 +999,11 @@ func (h *VirtualInventoryHandler) GetStockList(c *gin.Context) {
 		return
 	}
 
+	// 列表只返回脱敏内容，明文需调用 reveal 接口并留下查看记录
+	for i := range stocks {
+		stocks[i].MaskContent()
+	}
+
 	response.Paginated(c, stocks, pageInt, limitInt, total)
 }
 
@@ -1135,6 +1164,9 @@ func (h *VirtualInventoryHandler) CreateBinding(c *gin.Context) {
 		response.BadRequest(c, "Invalid product ID")
 		return
 	}
+	if !ensureAdminProductStoreAccess(c, productID) {
+		return
+	}
 
 	var req struct {
 		VirtualInventoryID uint   `json:"virtual_inventory_id" binding:"required"`
@@ -1241,9 +1273,10 @@ func (h *VirtualInventoryHandler) UpdateBinding(c *gin.Context) {
 	}
 
 	var req struct {
-		IsRandom bool   `json:"is_random"`
-		Priority int    `json:"priority"`
-		Notes    string `json:"notes"`
+		IsRandom      bool   `json:"is_random"`
+		Priority      int    `json:"priority"`
+		Notes         string `json:"notes"`
+		DeliveryOrder string `json:"delivery_order"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -1256,6 +1289,9 @@ func (h *VirtualInventoryHandler) UpdateBinding(c *gin.Context) {
 		adminIDValue = *adminID
 	}
 	beforeBinding, _ := h.loadVirtualBinding(bindingID)
+	if beforeBinding != nil && !ensureAdminProductStoreAccess(c, beforeBinding.ProductID) {
+		return
+	}
 	if h.pluginManager != nil {
 		originalReq := req
 		hookPayload, payloadErr := adminHookStructToPayload(req)
@@ -1299,7 +1335,7 @@ func (h *VirtualInventoryHandler) UpdateBinding(c *gin.Context) {
 		}
 	}
 
-	if err := h.service.UpdateBinding(bindingID, req.IsRandom, req.Priority, req.Notes); err != nil {
+	if err := h.service.UpdateBinding(bindingID, req.IsRandom, req.Priority, req.Notes, req.DeliveryOrder); err != nil {
 		if respondAdminBizError(c, err) {
 			return
 		}
@@ -1353,6 +1389,9 @@ func (h *VirtualInventoryHandler) DeleteBinding(c *gin.Context) {
 		adminIDValue = *adminID
 	}
 	beforeBinding, _ := h.loadVirtualBinding(bindingID)
+	if beforeBinding != nil && !ensureAdminProductStoreAccess(c, beforeBinding.ProductID) {
+		return
+	}
 	if h.pluginManager != nil && beforeBinding != nil {
 		hookPayload := buildVirtualInventoryBindingHookPayload(beforeBinding)
 		hookPayload["admin_id"] = adminIDValue
@@ -1414,6 +1453,9 @@ func (h *VirtualInventoryHandler) SaveVariantBindings(c *gin.Context) {
 		response.BadRequest(c, "Invalid product ID")
 		return
 	}
+	if !ensureAdminProductStoreAccess(c, productID) {
+		return
+	}
 
 	var req struct {
 		Bindings []service.VirtualVariantBindingInput `json:"bindings"`
@@ -1573,6 +1615,11 @@ func (h *VirtualInventoryHandler) GetStockListForProduct(c *gin.Context) {
 		return
 	}
 
+	// 列表只返回脱敏内容，明文需调用 reveal 接口并留下查看记录
+	for i := range stocks {
+		stocks[i].MaskContent()
+	}
+
 	response.Paginated(c, stocks, pageInt, limitInt, total)
 }
 
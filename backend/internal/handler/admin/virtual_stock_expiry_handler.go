@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// parseVirtualStockExpiresAt 解析库存有效期，空值表示不过期；只接受未来的时间
+func parseVirtualStockExpiresAt(raw string) (*time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	expiresAt, err := parsePromoCodeExpiryInput(&raw)
+	if err != nil {
+		return nil, bizerr.New("virtual_inventory.expiresAtInvalid", "Invalid expiry time")
+	}
+	if expiresAt != nil && !expiresAt.After(models.NowFunc()) {
+		return nil, bizerr.New("virtual_inventory.expiresAtPast", "Expiry time must be in the future")
+	}
+	return expiresAt, nil
+}
+
+// SetBatchExpiry 设置或清除批次内未售出库存项的有效期
+func (h *VirtualInventoryHandler) SetBatchExpiry(c *gin.Context) {
+	id, err := middleware.GetUintParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "Invalid inventory ID")
+		return
+	}
+	var req struct {
+		BatchNo   string `json:"batch_no" binding:"required"`
+		ExpiresAt string `json:"expires_at"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request data")
+		return
+	}
+	expiresAt, err := parseVirtualStockExpiresAt(req.ExpiresAt)
+	if err != nil {
+		respondAdminBizError(c, err)
+		return
+	}
+
+	updated, err := h.service.SetBatchExpiry(id, req.BatchNo, expiresAt, c.GetString("user_email"))
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to update batch expiry")
+		return
+	}
+	response.Success(c, gin.H{"updated": updated, "expires_at": expiresAt})
+}
+
+// GetExpiryReport 临期库存报表，days 为空时使用系统设置的提醒天数
+func (h *VirtualInventoryHandler) GetExpiryReport(c *gin.Context) {
+	days, _ := strconv.Atoi(c.Query("days"))
+	if days > 365 {
+		days = 365
+	}
+	report, err := h.service.GetExpiryReport(days)
+	if err != nil {
+		response.InternalError(c, "Failed to load expiry report")
+		return
+	}
+	response.Success(c, gin.H{"items": report})
+}
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// loadInventoryStock 加载属于指定虚拟库存的库存项，已关联订单的需校验店铺权限；失败时已写入响应
+func (h *VirtualInventoryHandler) loadInventoryStock(c *gin.Context) (*models.VirtualProductStock, bool) {
+	inventoryID, err := middleware.GetUintParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "Invalid inventory ID")
+		return nil, false
+	}
+	stockID, err := middleware.GetUintParam(c, "stock_id")
+	if err != nil {
+		response.BadRequest(c, "Invalid stock ID")
+		return nil, false
+	}
+	stock, err := h.loadVirtualStock(stockID)
+	if err != nil || stock.VirtualInventoryID != inventoryID {
+		response.NotFound(c, "Stock item not found")
+		return nil, false
+	}
+	if stock.OrderID != nil {
+		var order models.Order
+		if err := h.db.Select("id, store_id").First(&order, *stock.OrderID).Error; err == nil {
+			if !ensureAdminStoreAccess(c, order.StoreID) {
+				return nil, false
+			}
+		}
+	}
+	return stock, true
+}
+
+// RevealStock 管理员查看卡密明文，每次查看都会记录操作人
+func (h *VirtualInventoryHandler) RevealStock(c *gin.Context) {
+	adminID, adminIDOK := middleware.RequireUserID(c)
+	if !adminIDOK {
+		return
+	}
+	stock, ok := h.loadInventoryStock(c)
+	if !ok {
+		return
+	}
+	revealed, err := h.service.RevealStock(stock.ID, "", service.VirtualStockRevealViewer{
+		Type:  models.VirtualStockRevealViewerAdmin,
+		ID:    &adminID,
+		Email: c.GetString("user_email"),
+		IP:    c.ClientIP(),
+	})
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to reveal stock item")
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, gin.H{"content": revealed.Content})
+}
+
+// ListStockReveals 获取库存项的明文查看记录
+func (h *VirtualInventoryHandler) ListStockReveals(c *gin.Context) {
+	stock, ok := h.loadInventoryStock(c)
+	if !ok {
+		return
+	}
+	logs, err := h.service.ListStockReveals(stock.ID)
+	if err != nil {
+		response.InternalError(c, "Failed to get reveal records")
+		return
+	}
+	response.Success(c, gin.H{"items": logs})
+}
@@ -0,0 +1,165 @@
+package admin
+
+import (
+	"log"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/validator"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SetEmailService 设置邮件服务（撤销卡密时通知用户）
+func (h *VirtualInventoryHandler) SetEmailService(emailService *service.EmailService) {
+	h.emailService = emailService
+}
+
+// RevokeVirtualStockRequest 撤销已发货库存项，notify 默认为 true
+type RevokeVirtualStockRequest struct {
+	Reason  string `json:"reason" binding:"required"`
+	Reissue bool   `json:"reissue"`
+	Notify  *bool  `json:"notify"`
+}
+
+// RevokeStock 撤销已发货的库存项，可选从同一库存补发并通知用户
+func (h *VirtualInventoryHandler) RevokeStock(c *gin.Context) {
+	adminID, adminIDOK := middleware.RequireUserID(c)
+	if !adminIDOK {
+		return
+	}
+	stockID, err := middleware.GetUintParam(c, "stock_id")
+	if err != nil {
+		response.BadRequest(c, "Invalid stock ID")
+		return
+	}
+	var req RevokeVirtualStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	req.Reason = validator.SanitizeText(req.Reason)
+	if !validator.ValidateLength(req.Reason, 1, 500) {
+		response.BadRequest(c, "Reason must be 1-500 characters")
+		return
+	}
+
+	stock, err := h.loadVirtualStock(stockID)
+	if err != nil {
+		response.NotFound(c, "Stock item not found")
+		return
+	}
+	h.revokeLoadedStock(c, adminID, stock, req)
+}
+
+// RedeliverVirtualStockRequest 订单内替换泄露的卡密，reason 为空时使用默认原因
+type RedeliverVirtualStockRequest struct {
+	Reason string `json:"reason"`
+	Notify *bool  `json:"notify"`
+}
+
+// RedeliverOrderStock 撤销订单中泄露的卡密并从同一库存补发新卡密
+func (h *VirtualInventoryHandler) RedeliverOrderStock(c *gin.Context) {
+	adminID, adminIDOK := middleware.RequireUserID(c)
+	if !adminIDOK {
+		return
+	}
+	orderID, ok := parseAdminOrderID(c)
+	if !ok {
+		return
+	}
+	if !ensureAdminOrderStoreAccess(c, orderID) {
+		return
+	}
+	stockID, err := middleware.GetUintParam(c, "stock_id")
+	if err != nil {
+		response.BadRequest(c, "Invalid stock ID")
+		return
+	}
+	var req RedeliverVirtualStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		// 允许不传 body，使用默认原因并通知用户
+	}
+	req.Reason = validator.SanitizeText(req.Reason)
+	if req.Reason == "" {
+		req.Reason = "Compromised code replaced"
+	}
+	if !validator.ValidateLength(req.Reason, 1, 500) {
+		response.BadRequest(c, "Reason must be 1-500 characters")
+		return
+	}
+
+	stock, err := h.loadVirtualStock(stockID)
+	if err != nil || stock.OrderID == nil || *stock.OrderID != orderID {
+		response.NotFound(c, "Stock item not found")
+		return
+	}
+	h.revokeLoadedStock(c, adminID, stock, RevokeVirtualStockRequest{
+		Reason:  req.Reason,
+		Reissue: true,
+		Notify:  req.Notify,
+	})
+}
+
+func (h *VirtualInventoryHandler) revokeLoadedStock(c *gin.Context, adminID uint, stock *models.VirtualProductStock, req RevokeVirtualStockRequest) {
+	stockID := stock.ID
+	var order models.Order
+	if stock.OrderID != nil {
+		if err := h.db.First(&order, *stock.OrderID).Error; err != nil {
+			response.NotFound(c, "Order not found")
+			return
+		}
+		if !ensureAdminStoreAccess(c, order.StoreID) {
+			return
+		}
+	}
+
+	operator := "unknown"
+	if email, ok := c.Get("user_email"); ok {
+		if value, ok := email.(string); ok {
+			operator = value
+		}
+	}
+	result, err := h.service.RevokeStock(stockID, service.VirtualStockRevokeInput{
+		Reason:     req.Reason,
+		Reissue:    req.Reissue,
+		Operator:   operator,
+		OperatorID: &adminID,
+	})
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to revoke stock item")
+		return
+	}
+
+	logDetails := map[string]interface{}{
+		"order_no":             order.OrderNo,
+		"stock_id":             stockID,
+		"virtual_inventory_id": stock.VirtualInventoryID,
+		"reason":               req.Reason,
+		"reissue":              req.Reissue,
+	}
+	if result.Replacement != nil {
+		logDetails["replacement_id"] = result.Replacement.ID
+	}
+	logger.LogOrderOperation(database.GetDB(), c, "revoke_virtual_stock", order.ID, logDetails)
+
+	if h.emailService != nil && (req.Notify == nil || *req.Notify) {
+		go func(order models.Order, reason string, reissued bool) {
+			if err := h.emailService.SendVirtualStockRevokedEmail(&order, reason, reissued); err != nil {
+				log.Printf("Failed to send virtual stock revoked email: order=%s err=%v", order.OrderNo, err)
+			}
+		}(order, req.Reason, result.Replacement != nil)
+	}
+
+	result.Revoked.MaskContent()
+	if result.Replacement != nil {
+		result.Replacement.MaskContent()
+	}
+	response.Success(c, result)
+}
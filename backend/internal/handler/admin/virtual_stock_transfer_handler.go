@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/validator"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// TransferVirtualStockRequest 调拨筛选条件：批次号、备注关键字、库存项 ID，quantity 为 0 时转移全部匹配项
+type TransferVirtualStockRequest struct {
+	TargetInventoryID uint   `json:"target_inventory_id" binding:"required"`
+	BatchNo           string `json:"batch_no"`
+	RemarkKeyword     string `json:"remark_keyword"`
+	StockIDs          []uint `json:"stock_ids"`
+	Quantity          int    `json:"quantity"`
+	Reason            string `json:"reason"`
+}
+
+// TransferStock 批量转移可用库存项到另一个虚拟库存
+func (h *VirtualInventoryHandler) TransferStock(c *gin.Context) {
+	adminID, adminIDOK := middleware.RequireUserID(c)
+	if !adminIDOK {
+		return
+	}
+	id, err := middleware.GetUintParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "Invalid inventory ID")
+		return
+	}
+	var req TransferVirtualStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request data")
+		return
+	}
+	req.Reason = validator.SanitizeText(req.Reason)
+	if !validator.ValidateLength(req.Reason, 0, 500) {
+		response.BadRequest(c, "Reason must be at most 500 characters")
+		return
+	}
+
+	record, err := h.service.TransferStock(id, service.VirtualStockTransferInput{
+		TargetInventoryID: req.TargetInventoryID,
+		BatchNo:           req.BatchNo,
+		RemarkKeyword:     req.RemarkKeyword,
+		StockIDs:          req.StockIDs,
+		Quantity:          req.Quantity,
+		Reason:            req.Reason,
+		Operator:          c.GetString("user_email"),
+		OperatorID:        &adminID,
+	})
+	if err != nil {
+		if respondAdminBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to transfer stock items")
+		return
+	}
+	response.Success(c, record)
+}
+
+// ListStockTransfers 获取虚拟库存的调拨记录
+func (h *VirtualInventoryHandler) ListStockTransfers(c *gin.Context) {
+	id, err := middleware.GetUintParam(c, "id")
+	if err != nil {
+		response.BadRequest(c, "Invalid inventory ID")
+		return
+	}
+	page, limit := response.GetPagination(c)
+	items, total, err := h.service.ListStockTransfers(id, page, limit)
+	if err != nil {
+		response.InternalError(c, "Failed to get transfer records")
+		return
+	}
+	response.Paginated(c, items, page, limit, total)
+}
@@ -0,0 +1,20 @@
+package admin
+
+import (
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WaitingRoomHandler struct {
+	waitingRoomService *service.WaitingRoomService
+}
+
+func NewWaitingRoomHandler(waitingRoomService *service.WaitingRoomService) *WaitingRoomHandler {
+	return &WaitingRoomHandler{waitingRoomService: waitingRoomService}
+}
+
+// GetMetrics 等候室指标（排队人数、放行与放弃统计）
+func (h *WaitingRoomHandler) GetMetrics(c *gin.Context) {
+	response.Success(c, h.waitingRoomService.Metrics())
+}
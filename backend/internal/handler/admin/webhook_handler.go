@@ -0,0 +1,199 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+func parseWebhookID(c *gin.Context, param string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(param), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *WebhookHandler) respondWebhookError(c *gin.Context, err error, fallback string) {
+	if respondAdminBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// GetWebhookMeta 可订阅事件与签名说明
+func (h *WebhookHandler) GetWebhookMeta(c *gin.Context) {
+	response.Success(c, gin.H{
+		"events":           service.WebhookEvents(),
+		"signature_header": "X-AuraLogic-Signature",
+	})
+}
+
+// ListWebhookEndpoints Webhook 地址列表
+func (h *WebhookHandler) ListWebhookEndpoints(c *gin.Context) {
+	endpoints, err := h.webhookService.ListEndpoints()
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Success(c, gin.H{"items": endpoints})
+}
+
+// CreateWebhookEndpoint 创建 Webhook 地址，响应中包含仅显示一次的签名密钥
+func (h *WebhookHandler) CreateWebhookEndpoint(c *gin.Context) {
+	var req service.WebhookEndpointInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	var createdBy *uint
+	if adminID, ok := middleware.GetUserID(c); ok {
+		createdBy = &adminID
+	}
+	endpoint, err := h.webhookService.CreateEndpoint(req, createdBy)
+	if err != nil {
+		h.respondWebhookError(c, err, "Failed to create webhook")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create", "webhook_endpoint", &endpoint.ID, map[string]interface{}{
+		"name":     endpoint.Name,
+		"url":      endpoint.URL,
+		"events":   endpoint.Events,
+		"enabled":  endpoint.Enabled,
+		"platform": endpoint.Platform,
+	})
+	response.Success(c, endpoint)
+}
+
+// UpdateWebhookEndpoint 更新 Webhook 地址
+func (h *WebhookHandler) UpdateWebhookEndpoint(c *gin.Context) {
+	id, ok := parseWebhookID(c, "id")
+	if !ok {
+		return
+	}
+	var req service.WebhookEndpointInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	endpoint, err := h.webhookService.UpdateEndpoint(id, req)
+	if err != nil {
+		h.respondWebhookError(c, err, "Failed to update webhook")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "update", "webhook_endpoint", &endpoint.ID, map[string]interface{}{
+		"name":     endpoint.Name,
+		"url":      endpoint.URL,
+		"events":   endpoint.Events,
+		"enabled":  endpoint.Enabled,
+		"platform": endpoint.Platform,
+	})
+	response.Success(c, endpoint)
+}
+
+// DeleteWebhookEndpoint 删除 Webhook 地址
+func (h *WebhookHandler) DeleteWebhookEndpoint(c *gin.Context) {
+	id, ok := parseWebhookID(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.webhookService.DeleteEndpoint(id); err != nil {
+		h.respondWebhookError(c, err, "Failed to delete webhook")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "delete", "webhook_endpoint", &id, nil)
+	response.Success(c, gin.H{"message": "Webhook deleted"})
+}
+
+// RotateWebhookSecret 轮换签名密钥
+func (h *WebhookHandler) RotateWebhookSecret(c *gin.Context) {
+	id, ok := parseWebhookID(c, "id")
+	if !ok {
+		return
+	}
+	endpoint, err := h.webhookService.RotateSecret(id)
+	if err != nil {
+		h.respondWebhookError(c, err, "Failed to rotate webhook secret")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "rotate_secret", "webhook_endpoint", &id, nil)
+	response.Success(c, endpoint)
+}
+
+// TestWebhookEndpoint 同步发送 ping 事件并返回投递结果
+func (h *WebhookHandler) TestWebhookEndpoint(c *gin.Context) {
+	id, ok := parseWebhookID(c, "id")
+	if !ok {
+		return
+	}
+	delivery, err := h.webhookService.SendTest(id)
+	if err != nil {
+		h.respondWebhookError(c, err, "Failed to send test webhook")
+		return
+	}
+	response.Success(c, delivery)
+}
+
+// ListWebhookDeliveries 投递日志
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	page, limit := response.GetPagination(c)
+	filter := service.WebhookDeliveryFilter{
+		Event:  strings.TrimSpace(c.Query("event")),
+		Status: strings.TrimSpace(c.Query("status")),
+	}
+	if raw := strings.TrimSpace(c.Query("endpoint_id")); raw != "" {
+		endpointID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			response.BadRequest(c, "Invalid endpoint_id")
+			return
+		}
+		filter.EndpointID = uint(endpointID)
+	}
+
+	deliveries, total, err := h.webhookService.ListDeliveries(filter, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, deliveries, page, limit, total)
+}
+
+// RedeliverWebhook 以相同事件 ID 重新投递
+func (h *WebhookHandler) RedeliverWebhook(c *gin.Context) {
+	id, ok := parseWebhookID(c, "deliveryId")
+	if !ok {
+		return
+	}
+	delivery, err := h.webhookService.Redeliver(id)
+	if err != nil {
+		h.respondWebhookError(c, err, "Failed to redeliver webhook")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "redeliver", "webhook_delivery", &id, map[string]interface{}{
+		"event":           delivery.Event,
+		"event_id":        delivery.EventID,
+		"new_delivery_id": delivery.ID,
+		"status":          delivery.Status,
+	})
+	response.Success(c, delivery)
+}
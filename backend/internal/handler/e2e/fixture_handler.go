@@ -0,0 +1,89 @@
+//go:build e2e
+
+// Package e2e 端到端测试夹具接口，仅在 -tags e2e 构建中编译，生产构建不包含这些路由
+package e2e
+
+import (
+	"errors"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FixtureHandler 测试夹具处理器
+type FixtureHandler struct {
+	fixtureService *service.E2EFixtureService
+}
+
+// NewFixtureHandler 创建测试夹具处理器
+func NewFixtureHandler(fixtureService *service.E2EFixtureService) *FixtureHandler {
+	return &FixtureHandler{fixtureService: fixtureService}
+}
+
+// RegisterRoutes 注册 /api/test 路由
+func (h *FixtureHandler) RegisterRoutes(group *gin.RouterGroup) {
+	orders := group.Group("/orders/:order_no")
+	orders.POST("/expire-form", h.ExpireForm)
+	orders.POST("/expire-payment", h.ExpirePayment)
+	orders.POST("/confirm-payment", h.ConfirmPayment)
+	group.POST("/jobs/order-cancel", h.RunOrderCancel)
+	group.POST("/rate-limits/reset", h.ResetRateLimits)
+}
+
+func respondFixtureOrder(c *gin.Context, order *models.Order, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	response.Success(c, gin.H{
+		"order_no":            order.OrderNo,
+		"status":              order.Status,
+		"form_expires_at":     order.FormExpiresAt,
+		"payment_deadline_at": order.PaymentDeadlineAt,
+	})
+}
+
+// ExpireForm 使订单表单链接立即过期
+func (h *FixtureHandler) ExpireForm(c *gin.Context) {
+	order, err := h.fixtureService.ExpireForm(c.Param("order_no"))
+	respondFixtureOrder(c, order, err)
+}
+
+// ExpirePayment 使待付款订单立即超过付款截止时间
+func (h *FixtureHandler) ExpirePayment(c *gin.Context) {
+	order, err := h.fixtureService.ExpirePayment(c.Param("order_no"))
+	respondFixtureOrder(c, order, err)
+}
+
+// ConfirmPayment 强制确认订单付款
+func (h *FixtureHandler) ConfirmPayment(c *gin.Context) {
+	order, err := h.fixtureService.ConfirmPayment(c.Param("order_no"))
+	respondFixtureOrder(c, order, err)
+}
+
+// RunOrderCancel 立即执行自动取消与草稿过期任务
+func (h *FixtureHandler) RunOrderCancel(c *gin.Context) {
+	result, err := h.fixtureService.RunOrderCancel()
+	if err != nil {
+		response.InternalServerError(c, "Failed to run order cancel job", err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// ResetRateLimits 清空限流计数
+func (h *FixtureHandler) ResetRateLimits(c *gin.Context) {
+	deleted, err := h.fixtureService.ResetRateLimits()
+	if err != nil {
+		response.InternalServerError(c, "Failed to reset rate limits", err)
+		return
+	}
+	response.Success(c, gin.H{"deleted": deleted})
+}
@@ -8,8 +8,8 @@ import (
 	"auralogic/internal/database"
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
-	"auralogic/internal/pkg/constants"
 	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/orderbiz"
 	"auralogic/internal/pkg/response"
 	"auralogic/internal/pkg/validator"
 	"auralogic/internal/service"
@@ -18,17 +18,25 @@ import (
 )
 
 type ShippingHandler struct {
-	orderService *service.OrderService
-	cfg          *config.Config
+	orderService         *service.OrderService
+	regionService        *service.RegionService
+	shippingRestrictions *service.ShippingRestrictionService
+	cfg                  *config.Config
 }
 
 func NewShippingHandler(orderService *service.OrderService, cfg *config.Config) *ShippingHandler {
 	return &ShippingHandler{
-		orderService: orderService,
-		cfg:          cfg,
+		orderService:  orderService,
+		regionService: service.NewRegionService(cfg),
+		cfg:           cfg,
 	}
 }
 
+// SetShippingRestrictionService 注入商品配送限制校验
+func (h *ShippingHandler) SetShippingRestrictionService(shippingRestrictions *service.ShippingRestrictionService) {
+	h.shippingRestrictions = shippingRestrictions
+}
+
 // GetForm Get form information
 func (h *ShippingHandler) GetForm(c *gin.Context) {
 	token := c.Query("token")
@@ -169,6 +177,14 @@ func (h *ShippingHandler) SubmitForm(c *gin.Context) {
 		response.BadRequest(c, "Invalid postal code format")
 		return
 	}
+	if bizErr := orderbiz.ValidateReceiverRegion(req.ReceiverCountry, req.PhoneCode, req.ReceiverPhone, req.ReceiverPostcode); bizErr != nil {
+		response.BizError(c, bizErr.Message, bizErr.Key, bizErr.Params)
+		return
+	}
+	if err := h.regionService.ValidateShippingRegion(req.ReceiverCountry, req.ReceiverProvince, req.ReceiverCity, true); err != nil {
+		response.HandleError(c, "Invalid shipping region", err)
+		return
+	}
 
 	// 11. Sanitize user remark (max 1000 characters)
 	req.UserRemark = validator.SanitizeText(req.UserRemark)
@@ -209,6 +225,21 @@ func (h *ShippingHandler) SubmitForm(c *gin.Context) {
 		receiverCountry = "CN"
 	}
 
+	if h.shippingRestrictions != nil {
+		attemptUserID := h.currentUserID(c)
+		if attemptUserID == nil {
+			attemptUserID = order.UserID
+		}
+		if err := h.shippingRestrictions.Enforce(order.Items, receiverCountry, service.ShippingRestrictionAttempt{
+			Source:  service.ShippingRestrictionSourceForm,
+			OrderNo: order.OrderNo,
+			UserID:  attemptUserID,
+		}); err != nil {
+			response.HandleError(c, "Shipping restriction check failed", err)
+			return
+		}
+	}
+
 	// Default phone code is +86
 	phoneCode := req.PhoneCode
 	if phoneCode == "" {
@@ -313,7 +344,17 @@ func (h *ShippingHandler) respondFormOrderLoadError(c *gin.Context, err error) {
 	}
 }
 
-// GetCountries Get list of supported countries
+// GetCountries Get list of supported countries (disabled countries are hidden unless include_disabled=1)
 func (h *ShippingHandler) GetCountries(c *gin.Context) {
-	response.Success(c, constants.Countries)
+	response.Success(c, h.regionService.Countries(c.Query("include_disabled") == "1"))
+}
+
+// GetRegions Get provinces/states and cities of a country
+func (h *ShippingHandler) GetRegions(c *gin.Context) {
+	regions, err := h.regionService.Regions(c.Param("code"))
+	if err != nil {
+		response.HandleError(c, "Failed to load regions", err)
+		return
+	}
+	response.Success(c, regions)
 }
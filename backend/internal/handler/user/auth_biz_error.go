@@ -47,6 +47,10 @@ func respondAuthBizError(c *gin.Context, err error, extraData gin.H) bool {
 		response.ErrorWithData(c, http.StatusConflict, response.CodeConflict, bizErr.Message, data)
 	case "auth.emailLoginUnavailable", "auth.smsServiceUnavailable":
 		response.ErrorWithData(c, http.StatusServiceUnavailable, response.CodeServiceUnavailable, bizErr.Message, data)
+	case "auth.accountLocked":
+		response.ErrorWithData(c, http.StatusTooManyRequests, response.CodeTooManyRequests, bizErr.Message, data)
+	case "auth.ipBanned":
+		response.ErrorWithData(c, http.StatusForbidden, response.CodeForbidden, bizErr.Message, data)
 	case "auth.captchaRequired":
 		response.ErrorWithData(c, http.StatusBadRequest, response.CodeParamMissing, bizErr.Message, data)
 	case "auth.captchaFailed", "auth.invalidPhoneFormat":
@@ -134,7 +134,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			respondAuthBizError(c, authbiz.CaptchaRequired(), nil)
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(req.CaptchaToken, utils.GetRealIP(c)); err != nil {
+		if err := h.captchaService.VerifyCaptcha("login", req.CaptchaToken, utils.GetRealIP(c)); err != nil {
 			respondAuthBizError(c, authbiz.CaptchaFailed(), nil)
 			return
 		}
@@ -175,7 +175,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		}
 	}
 
-	token, user, err := h.authService.Login(req.Email, req.Password)
+	token, user, err := h.authService.LoginFromClient(req.Email, req.Password, utils.GetRealIP(c), c.Request.UserAgent())
 	if err != nil {
 		db := database.GetDB()
 		logger.LogLoginAttempt(db, c, req.Email, false, nil)
@@ -236,11 +236,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		}(cloneAuthExecutionContext(afterExecCtx), afterPayload, user.Email)
 	}
 
-	response.Success(c, gin.H{
-		"token":      token,
-		"token_type": "Bearer",
-		"user":       result,
-	})
+	response.Success(c, withAuthToken(c, token, gin.H{
+		"user": result,
+	}))
 }
 
 // RegisterRequest 注册请求
@@ -274,7 +272,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			respondAuthBizError(c, authbiz.CaptchaRequired(), nil)
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(req.CaptchaToken, utils.GetRealIP(c)); err != nil {
+		if err := h.captchaService.VerifyCaptcha("register", req.CaptchaToken, utils.GetRealIP(c)); err != nil {
 			respondAuthBizError(c, authbiz.CaptchaFailed(), nil)
 			return
 		}
@@ -397,21 +395,26 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 		// 发送验证邮件
 		go h.emailService.SendVerificationEmail(user.Email, user.Name, token, user.Locale)
-		emitRegisterAfter(true)
-
-		response.Success(c, gin.H{
-			"require_verification": true,
-			"message":              "Registration successful. Please check your email to verify your account.",
-			"email":                user.Email,
-		})
-		return
-	}
 
-	// 不需要邮箱验证，直接标记已验证并登录
-	user.EmailVerified = true
-	if err := db.Save(user).Error; err != nil {
-		response.InternalError(c, "Registration failed")
-		return
+		// checkout/grace 模式允许先登录，验证前仅限制下单
+		mode := cfg.Security.Login.EmailVerificationMode
+		if mode != authbiz.EmailVerificationModeCheckout && mode != authbiz.EmailVerificationModeGrace {
+			emitRegisterAfter(true)
+			response.Success(c, gin.H{
+				"require_verification": true,
+				"message":              "Registration successful. Please check your email to verify your account.",
+				"email":                user.Email,
+			})
+			return
+		}
+	} else {
+		// 不需要邮箱验证，直接标记已验证并登录
+		user.EmailVerified = true
+		if err := db.Save(user).Error; err != nil {
+			response.InternalError(c, "Registration failed")
+			return
+		}
+		h.claimGuestOrders(c, user)
 	}
 
 	// 生成JWT Token
@@ -429,11 +432,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	if h.emailService != nil {
 		go h.emailService.SendRegistrationWelcomeEmail(user.Email, user.Name, user.Locale)
 	}
-	emitRegisterAfter(false)
+	emitRegisterAfter(!user.EmailVerified)
 
-	response.Success(c, gin.H{
-		"token":      jwtToken,
-		"token_type": "Bearer",
+	response.Success(c, withAuthToken(c, jwtToken, gin.H{
 		"user": gin.H{
 			"id":                user.ID,
 			"user_id":           user.ID,
@@ -446,7 +447,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			"total_spent_minor": user.TotalSpentMinor,
 			"total_order_count": user.TotalOrderCount,
 		},
-	})
+	}))
 }
 
 // maskPhone masks a phone number, e.g. "13300003333" -> "13*******33"
@@ -502,6 +503,7 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 		"is_active":              user.IsActive,
 		"locale":                 user.Locale,
 		"country":                user.Country,
+		"display_currency":       user.DisplayCurrency,
 		"email_notify_order":     user.EmailNotifyOrder,
 		"email_notify_ticket":    user.EmailNotifyTicket,
 		"email_notify_marketing": user.EmailNotifyMarketing,
@@ -509,10 +511,16 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 		"total_spent_minor":      user.TotalSpentMinor,
 		"total_order_count":      user.TotalOrderCount,
 		"created_at":             user.CreatedAt,
+		"email_verified":         user.EmailVerified,
 	}
 	if user.Phone != nil && *user.Phone != "" {
 		result["phone"] = maskPhone(*user.Phone)
 	}
+	if cfg := config.GetConfig(); cfg != nil {
+		if status := authbiz.EvaluateEmailVerification(cfg.Security.Login, user, time.Now()); status.Required {
+			result["email_verification"] = status
+		}
+	}
 
 	// 如果是Admin，getPermission列表
 	if user.IsAdmin() {
@@ -524,8 +532,9 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	response.Success(c, result)
 }
 
-// Logout 用户登出（客户端清除token即可，服务端预留扩展）
+// Logout 用户登出（Bearer 模式由客户端清除token，Cookie 会话在此清除 Cookie）
 func (h *AuthHandler) Logout(c *gin.Context) {
+	middleware.ClearSessionCookie(c)
 	response.Success(c, gin.H{
 		"message": "Logged out successfully",
 	})
@@ -606,12 +615,13 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 
 // UpdatePreferencesRequest 更新用户偏好请求
 type UpdatePreferencesRequest struct {
-	Locale               string `json:"locale"`
-	Country              string `json:"country"`
-	EmailNotifyOrder     *bool  `json:"email_notify_order"`
-	EmailNotifyTicket    *bool  `json:"email_notify_ticket"`
-	EmailNotifyMarketing *bool  `json:"email_notify_marketing"`
-	SMSNotifyMarketing   *bool  `json:"sms_notify_marketing"`
+	Locale               string  `json:"locale"`
+	Country              string  `json:"country"`
+	DisplayCurrency      *string `json:"display_currency"`
+	EmailNotifyOrder     *bool   `json:"email_notify_order"`
+	EmailNotifyTicket    *bool   `json:"email_notify_ticket"`
+	EmailNotifyMarketing *bool   `json:"email_notify_marketing"`
+	SMSNotifyMarketing   *bool   `json:"sms_notify_marketing"`
 }
 
 // UpdatePreferences 更新用户偏好设置
@@ -664,6 +674,7 @@ func (h *AuthHandler) UpdatePreferences(c *gin.Context) {
 		userID,
 		req.Locale,
 		req.Country,
+		req.DisplayCurrency,
 		req.EmailNotifyOrder,
 		req.EmailNotifyTicket,
 		req.EmailNotifyMarketing,
@@ -680,6 +691,7 @@ func (h *AuthHandler) UpdatePreferences(c *gin.Context) {
 			"user_id":                userID,
 			"locale":                 req.Locale,
 			"country":                req.Country,
+			"display_currency":       req.DisplayCurrency,
 			"email_notify_order":     req.EmailNotifyOrder,
 			"email_notify_ticket":    req.EmailNotifyTicket,
 			"email_notify_marketing": req.EmailNotifyMarketing,
@@ -689,6 +701,7 @@ func (h *AuthHandler) UpdatePreferences(c *gin.Context) {
 		if user, lookupErr := h.authService.GetUserByID(userID); lookupErr == nil && user != nil {
 			afterPayload["locale"] = user.Locale
 			afterPayload["country"] = user.Country
+			afterPayload["display_currency"] = user.DisplayCurrency
 			afterPayload["email_notify_order"] = user.EmailNotifyOrder
 			afterPayload["email_notify_ticket"] = user.EmailNotifyTicket
 			afterPayload["email_notify_marketing"] = user.EmailNotifyMarketing
@@ -713,21 +726,19 @@ func (h *AuthHandler) UpdatePreferences(c *gin.Context) {
 // GetCaptcha 获取内置验证码
 func (h *AuthHandler) GetCaptcha(c *gin.Context) {
 	// Basic abuse protection for builtin captcha generation (even when global rate-limit is off).
-	// Best-effort: if Redis is unavailable, we fail open to avoid blocking login entirely.
-	if cache.RedisClient != nil {
-		ip := utils.GetRealIP(c)
-		window := int64(60)
-		bucket := time.Now().Unix() / window
-		key := fmt.Sprintf("captcha:gen:%s:%d", ip, bucket)
-		count, err := cache.Incr(key)
-		if err == nil {
-			if count == 1 {
-				_ = cache.Expire(key, time.Duration(window)*time.Second)
-			}
-			if count > 120 {
-				response.Error(c, 429, response.CodeTooManyRequests, "Too many requests, please try again later")
-				return
-			}
+	// Best-effort: if the counter store errors, we fail open to avoid blocking login entirely.
+	ip := utils.GetRealIP(c)
+	window := int64(60)
+	bucket := time.Now().Unix() / window
+	key := fmt.Sprintf("captcha:gen:%s:%d", ip, bucket)
+	count, err := cache.Incr(key)
+	if err == nil {
+		if count == 1 {
+			_ = cache.Expire(key, time.Duration(window)*time.Second)
+		}
+		if count > 120 {
+			response.Error(c, 429, response.CodeTooManyRequests, "Too many requests, please try again later")
+			return
 		}
 	}
 
@@ -806,6 +817,7 @@ func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 	logger.LogOperation(db, c, "verify_email", "user", &user.ID, map[string]interface{}{
 		"email": user.Email,
 	})
+	h.claimGuestOrders(c, &user)
 
 	// 生成 JWT Token 让用户直接登录
 	jwtToken, err := h.authService.GenerateToken(&user)
@@ -818,11 +830,9 @@ func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 		return
 	}
 
-	response.Success(c, gin.H{
-		"verified":   true,
-		"message":    "Email verified successfully",
-		"token":      jwtToken,
-		"token_type": "Bearer",
+	response.Success(c, withAuthToken(c, jwtToken, gin.H{
+		"verified": true,
+		"message":  "Email verified successfully",
 		"user": gin.H{
 			"user_id":           user.ID,
 			"uuid":              user.UUID,
@@ -834,7 +844,7 @@ func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 			"total_spent_minor": user.TotalSpentMinor,
 			"total_order_count": user.TotalOrderCount,
 		},
-	})
+	}))
 }
 
 // ResendVerification 重新发送验证邮件
@@ -944,7 +954,7 @@ func (h *AuthHandler) SendLoginCode(c *gin.Context) {
 			respondAuthBizError(c, authbiz.CaptchaRequired(), nil)
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(req.CaptchaToken, utils.GetRealIP(c)); err != nil {
+		if err := h.captchaService.VerifyCaptcha("login", req.CaptchaToken, utils.GetRealIP(c)); err != nil {
 			respondAuthBizError(c, authbiz.CaptchaFailed(), nil)
 			return
 		}
@@ -1056,7 +1066,7 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 			respondAuthBizError(c, authbiz.CaptchaRequired(), nil)
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(req.CaptchaToken, ip); err != nil {
+		if err := h.captchaService.VerifyCaptcha("login", req.CaptchaToken, ip); err != nil {
 			respondAuthBizError(c, authbiz.CaptchaFailed(), nil)
 			return
 		}
@@ -1245,6 +1255,7 @@ func (h *AuthHandler) LoginWithCode(c *gin.Context) {
 
 	db := database.GetDB()
 	logger.LogLoginAttempt(db, c, req.Email, true, &user.ID)
+	h.claimGuestOrders(c, user)
 
 	result := gin.H{
 		"id":                user.ID,
@@ -1283,11 +1294,9 @@ func (h *AuthHandler) LoginWithCode(c *gin.Context) {
 		}(cloneAuthExecutionContext(h.buildAuthHookExecutionContext(c, &uid)), afterPayload, user.Email)
 	}
 
-	response.Success(c, gin.H{
-		"token":      token,
-		"token_type": "Bearer",
-		"user":       result,
-	})
+	response.Success(c, withAuthToken(c, token, gin.H{
+		"user": result,
+	}))
 }
 
 // SendPhoneLoginCode 发送手机登录验证码
@@ -1333,7 +1342,7 @@ func (h *AuthHandler) SendPhoneLoginCode(c *gin.Context) {
 			respondAuthBizError(c, authbiz.CaptchaRequired(), nil)
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(req.CaptchaToken, ip); err != nil {
+		if err := h.captchaService.VerifyCaptcha("login", req.CaptchaToken, ip); err != nil {
 			respondAuthBizError(c, authbiz.CaptchaFailed(), nil)
 			return
 		}
@@ -1439,8 +1448,7 @@ func (h *AuthHandler) LoginWithPhoneCode(c *gin.Context) {
 		}(cloneAuthExecutionContext(h.buildAuthHookExecutionContext(c, &uid)), afterPayload, phone)
 	}
 
-	response.Success(c, gin.H{
-		"token": token, "token_type": "Bearer",
+	response.Success(c, withAuthToken(c, token, gin.H{
 		"user": gin.H{
 			"id":                user.ID,
 			"user_id":           user.ID,
@@ -1453,7 +1461,7 @@ func (h *AuthHandler) LoginWithPhoneCode(c *gin.Context) {
 			"total_spent_minor": user.TotalSpentMinor,
 			"total_order_count": user.TotalOrderCount,
 		},
-	})
+	}))
 }
 
 // PhoneRegister 手机号注册
@@ -1598,8 +1606,7 @@ func (h *AuthHandler) PhoneRegister(c *gin.Context) {
 		}(cloneAuthExecutionContext(h.buildAuthHookExecutionContext(c, &uid)), afterPayload, req.Phone)
 	}
 
-	response.Success(c, gin.H{
-		"token": jwtToken, "token_type": "Bearer",
+	response.Success(c, withAuthToken(c, jwtToken, gin.H{
 		"user": gin.H{
 			"id":                user.ID,
 			"user_id":           user.ID,
@@ -1612,7 +1619,7 @@ func (h *AuthHandler) PhoneRegister(c *gin.Context) {
 			"total_spent_minor": user.TotalSpentMinor,
 			"total_order_count": user.TotalOrderCount,
 		},
-	})
+	}))
 }
 
 // PhoneForgotPassword 手机号找回密码
@@ -1693,7 +1700,7 @@ func (h *AuthHandler) PhoneForgotPassword(c *gin.Context) {
 			respondAuthBizError(c, authbiz.CaptchaRequired(), nil)
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(req.CaptchaToken, ip); err != nil {
+		if err := h.captchaService.VerifyCaptcha("login", req.CaptchaToken, ip); err != nil {
 			respondAuthBizError(c, authbiz.CaptchaFailed(), nil)
 			return
 		}
@@ -1849,7 +1856,7 @@ func (h *AuthHandler) SendBindEmailCode(c *gin.Context) {
 			respondAuthBizError(c, authbiz.CaptchaRequired(), nil)
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(req.CaptchaToken, utils.GetRealIP(c)); err != nil {
+		if err := h.captchaService.VerifyCaptcha("bind", req.CaptchaToken, utils.GetRealIP(c)); err != nil {
 			respondAuthBizError(c, authbiz.CaptchaFailed(), nil)
 			return
 		}
@@ -1958,13 +1965,24 @@ func (h *AuthHandler) BindEmail(c *gin.Context) {
 			}
 		}
 	}
-	if err := h.authService.BindEmail(userID, req.Email, req.Code); err != nil {
+	change, err := h.authService.BindEmail(userID, req.Email, req.Code, utils.GetRealIP(c))
+	if err != nil {
 		if respondAuthBizError(c, err, nil) {
 			return
 		}
 		response.InternalServerError(c, "Failed to bind email", err)
 		return
 	}
+	if change != nil {
+		logger.LogOperation(database.GetDB(), c, "change_email", "user", &userID, map[string]interface{}{
+			"old_email": change.OldEmail,
+			"new_email": change.NewEmail,
+		})
+	}
+	var user models.User
+	if err := database.GetDB().First(&user, userID).Error; err == nil {
+		h.claimGuestOrders(c, &user)
+	}
 	if h.pluginManager != nil {
 		afterPayload := map[string]interface{}{
 			"user_id": userID,
@@ -1985,6 +2003,35 @@ func (h *AuthHandler) BindEmail(c *gin.Context) {
 	response.Success(c, gin.H{"message": "Email bound successfully"})
 }
 
+// RevertEmailChange 旧邮箱通过通知邮件中的链接撤销邮箱变更
+func (h *AuthHandler) RevertEmailChange(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	change, err := h.authService.RevertEmailChange(strings.TrimSpace(req.Token))
+	if err != nil {
+		if respondAuthBizError(c, err, nil) {
+			return
+		}
+		response.InternalServerError(c, "Failed to revert email change", err)
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "revert_email_change", "user", &change.UserID, map[string]interface{}{
+		"restored_email": change.OldEmail,
+		"reverted_email": change.NewEmail,
+	})
+	response.Success(c, gin.H{
+		"email":   change.OldEmail,
+		"message": "Email address restored. Please reset your password.",
+	})
+}
+
 // SendBindPhoneCode 发送绑定手机验证码
 func (h *AuthHandler) SendBindPhoneCode(c *gin.Context) {
 	userID, userIDOK := middleware.RequireUserID(c)
@@ -2058,7 +2105,7 @@ func (h *AuthHandler) SendBindPhoneCode(c *gin.Context) {
 			respondAuthBizError(c, authbiz.CaptchaRequired(), nil)
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(req.CaptchaToken, utils.GetRealIP(c)); err != nil {
+		if err := h.captchaService.VerifyCaptcha("bind", req.CaptchaToken, utils.GetRealIP(c)); err != nil {
 			respondAuthBizError(c, authbiz.CaptchaFailed(), nil)
 			return
 		}
@@ -2233,7 +2280,7 @@ func (h *AuthHandler) SendPhoneRegisterCode(c *gin.Context) {
 			respondAuthBizError(c, authbiz.CaptchaRequired(), nil)
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(req.CaptchaToken, ip); err != nil {
+		if err := h.captchaService.VerifyCaptcha("register", req.CaptchaToken, ip); err != nil {
 			respondAuthBizError(c, authbiz.CaptchaFailed(), nil)
 			return
 		}
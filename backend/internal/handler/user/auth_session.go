@@ -0,0 +1,46 @@
+package user
+
+import (
+	"log"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// withAuthToken 写入登录令牌。Web 前端选择 Cookie 会话时令牌只写入 HttpOnly Cookie，
+// 响应体返回 CSRF 令牌；其余客户端保持 Bearer Token
+func withAuthToken(c *gin.Context, token string, data gin.H) gin.H {
+	if middleware.WantsCookieSession(c) {
+		ttl := 24 * time.Hour
+		if cfg := config.GetConfig(); cfg != nil && cfg.JWT.ExpireHours > 0 {
+			ttl = time.Duration(cfg.JWT.ExpireHours) * time.Hour
+		}
+		csrfToken, err := middleware.IssueSessionCookie(c, token, ttl)
+		if err == nil {
+			data["token_type"] = "Cookie"
+			data["csrf_token"] = csrfToken
+			return data
+		}
+		log.Printf("issue session cookie failed, fallback to bearer token: %v", err)
+	}
+	data["token"] = token
+	data["token_type"] = "Bearer"
+	return data
+}
+
+// GetCSRFToken 获取当前 Cookie 会话的 CSRF 令牌（页面刷新后重新获取）
+func (h *AuthHandler) GetCSRFToken(c *gin.Context) {
+	csrfToken, ok := middleware.EnsureCSRFCookie(c)
+	if !ok {
+		response.Unauthorized(c, "No active cookie session")
+		return
+	}
+	cfg := config.GetConfig()
+	response.Success(c, gin.H{
+		"csrf_token":  csrfToken,
+		"header_name": cfg.Security.SessionCookie.CSRFHeaderName,
+	})
+}
@@ -48,8 +48,16 @@ func (h *CartHandler) GetCart(c *gin.Context) {
 		totalQuantity += item.Quantity
 	}
 
+	// 赠品按当前购物车实时计算，不满足条件时自动消失
+	gifts, err := h.cartService.GetCartGifts(items)
+	if err != nil {
+		log.Printf("resolve cart gifts failed: user=%d err=%v", userID, err)
+		gifts = []service.GiftLine{}
+	}
+
 	response.Success(c, gin.H{
 		"items":             items,
+		"gifts":             gifts,
 		"total_price_minor": totalPrice,
 		"total_quantity":    totalQuantity,
 		"item_count":        len(items),
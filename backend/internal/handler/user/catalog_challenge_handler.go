@@ -0,0 +1,26 @@
+package user
+
+import (
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/utils"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogChallengeHandler struct {
+	scraperProtection *service.ScraperProtectionService
+}
+
+func NewCatalogChallengeHandler(scraperProtection *service.ScraperProtectionService) *CatalogChallengeHandler {
+	return &CatalogChallengeHandler{scraperProtection: scraperProtection}
+}
+
+// GetChallenge 签发商品目录 JS 挑战，解出后通过 X-Catalog-Token 请求头携带
+func (h *CatalogChallengeHandler) GetChallenge(c *gin.Context) {
+	challenge, err := h.scraperProtection.IssueChallenge(utils.GetRealIP(c))
+	if err != nil {
+		response.InternalError(c, "Failed to issue challenge")
+		return
+	}
+	response.Success(c, challenge)
+}
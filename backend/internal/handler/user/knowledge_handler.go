@@ -6,6 +6,7 @@ import (
 
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
 	"auralogic/internal/pkg/response"
 	"auralogic/internal/service"
 	"github.com/gin-gonic/gin"
@@ -85,7 +86,7 @@ func (h *KnowledgeHandler) ListArticles(c *gin.Context) {
 		query = query.Where("category_id IN ?", ids)
 	}
 	if search != "" {
-		query = query.Where("title LIKE ?", "%"+search+"%")
+		query = dbutil.WhereContainsFold(query, search, "title")
 	}
 
 	var total int64
@@ -0,0 +1,79 @@
+package user
+
+import (
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// claimGuestOrders 邮箱验证通过后自动认领外部平台订单并记录日志
+func (h *AuthHandler) claimGuestOrders(c *gin.Context, user *models.User) {
+	orders := h.authService.ClaimGuestOrders(user)
+	if len(orders) == 0 {
+		return
+	}
+	orderNos := make([]string, 0, len(orders))
+	for _, order := range orders {
+		orderNos = append(orderNos, order.OrderNo)
+	}
+	logger.LogOperation(database.GetDB(), c, "claim_orders", "user", &user.ID, map[string]interface{}{
+		"email":     user.Email,
+		"order_nos": orderNos,
+		"source":    "email_verified",
+	})
+}
+
+func (h *OrderHandler) SetClaimService(claimService *service.OrderClaimService) {
+	h.claimService = claimService
+}
+
+// ClaimOrderRequest 按订单号认领订单请求
+type ClaimOrderRequest struct {
+	OrderNo string `json:"order_no" binding:"required"`
+}
+
+// ClaimOrder 按订单号认领外部平台创建、邮箱与当前账号一致的订单
+func (h *OrderHandler) ClaimOrder(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	if h.claimService == nil {
+		response.InternalError(c, "Order claim unavailable")
+		return
+	}
+	var req ClaimOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	db := database.GetDB()
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		response.NotFound(c, "User not found")
+		return
+	}
+	order, err := h.claimService.ClaimByOrderNo(&user, req.OrderNo)
+	if err != nil {
+		if respondUserBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to claim order", err)
+		return
+	}
+
+	logger.LogOperation(db, c, "claim_orders", "user", &user.ID, map[string]interface{}{
+		"email":     user.Email,
+		"order_nos": []string{order.OrderNo},
+		"source":    "manual",
+	})
+	response.Success(c, gin.H{
+		"order_no": order.OrderNo,
+		"status":   order.Status,
+	})
+}
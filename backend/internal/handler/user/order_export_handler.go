@@ -0,0 +1,284 @@
+package user
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/money"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	userOrderExportTimeFormat  = "2006-01-02 15:04:05"
+	userOrderExportContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+var (
+	userOrderExportOrderHeaders = []string{
+		"Order No", "Status", "Created At", "Shipped At", "Completed At",
+		"Currency", "Discount", "Total Amount", "Promo Code", "Tracking No", "Items",
+	}
+	userOrderExportItemHeaders = []string{
+		"Order No", "SKU", "Name", "Quantity", "Unit Price", "Discount", "Line Total",
+	}
+	// CSV 每个订单项一行，订单字段在各行重复
+	userOrderExportCSVHeaders = []string{
+		"Order No", "Status", "Created At", "Completed At", "Currency", "Order Total",
+		"SKU", "Name", "Quantity", "Unit Price", "Line Total",
+	}
+)
+
+// parseUserOrderExportRange 解析 start_date/end_date（YYYY-MM-DD，含首尾两天），默认最近 90 天
+func parseUserOrderExportRange(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if raw := c.Query("end_date"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			response.BadRequest(c, "Invalid end_date")
+			return time.Time{}, time.Time{}, false
+		}
+		end = parsed
+	}
+	start := end.AddDate(0, 0, -(service.UserOrderExportDefaultRangeDays - 1))
+	if raw := c.Query("start_date"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			response.BadRequest(c, "Invalid start_date")
+			return time.Time{}, time.Time{}, false
+		}
+		start = parsed
+	}
+	return start, end.AddDate(0, 0, 1), true
+}
+
+// ExportOrders 导出当前用户指定区间内的订单（含商品明细），format=csv|xlsx
+func (h *OrderHandler) ExportOrders(c *gin.Context) {
+	userID, userIDOK := middleware.RequireUserID(c)
+	if !userIDOK {
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "xlsx")))
+	if format != "csv" && format != "xlsx" {
+		response.BadRequest(c, "Invalid format")
+		return
+	}
+	from, to, ok := parseUserOrderExportRange(c)
+	if !ok {
+		return
+	}
+
+	orders, err := h.orderService.ListUserOrdersForExport(userID, from, to)
+	if err != nil {
+		if respondUserBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Export failed")
+		return
+	}
+
+	fileName := fmt.Sprintf("orders_%s_%s.%s", from.Format("20060102"), to.AddDate(0, 0, -1).Format("20060102"), format)
+	if format == "csv" {
+		writeUserOrdersCSV(c, fileName, orders)
+		return
+	}
+	writeUserOrdersXLSX(c, fileName, orders)
+}
+
+func userOrderExportTime(value *time.Time) string {
+	if value == nil || value.IsZero() {
+		return ""
+	}
+	return value.Format(userOrderExportTimeFormat)
+}
+
+// userOrderExportMinor 旧订单没有价格快照时金额为 0，导出留空
+func userOrderExportMinor(value int64) string {
+	if value == 0 {
+		return ""
+	}
+	return money.MinorToString(value)
+}
+
+func userOrderExportOrderRow(order models.Order) []string {
+	summaries := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		summaries = append(summaries, fmt.Sprintf("%s x%d", item.Name, item.Quantity))
+	}
+	return []string{
+		order.OrderNo,
+		string(order.Status),
+		userOrderExportTime(&order.CreatedAt),
+		userOrderExportTime(order.ShippedAt),
+		userOrderExportTime(order.CompletedAt),
+		order.Currency,
+		userOrderExportMinor(order.DiscountAmount),
+		money.MinorToString(order.TotalAmount),
+		order.PromoCodeStr,
+		order.TrackingNo,
+		strings.Join(summaries, "; "),
+	}
+}
+
+func userOrderExportItemRow(orderNo string, item models.OrderItem) []string {
+	return []string{
+		orderNo,
+		item.SKU,
+		item.Name,
+		strconv.Itoa(item.Quantity),
+		userOrderExportMinor(item.UnitPriceMinor),
+		userOrderExportMinor(item.DiscountMinor),
+		userOrderExportMinor(item.LineTotalMinor),
+	}
+}
+
+func writeUserOrdersCSV(c *gin.Context, fileName string, orders []models.Order) {
+	var buffer bytes.Buffer
+	buffer.Write([]byte{0xEF, 0xBB, 0xBF})
+
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(userOrderExportCSVHeaders); err != nil {
+		response.InternalError(c, "Export failed")
+		return
+	}
+	for _, order := range orders {
+		orderColumns := []string{
+			order.OrderNo,
+			string(order.Status),
+			userOrderExportTime(&order.CreatedAt),
+			userOrderExportTime(order.CompletedAt),
+			order.Currency,
+			money.MinorToString(order.TotalAmount),
+		}
+		if len(order.Items) == 0 {
+			if err := writer.Write(append(orderColumns, "", "", "", "", "")); err != nil {
+				response.InternalError(c, "Export failed")
+				return
+			}
+			continue
+		}
+		for _, item := range order.Items {
+			row := append(append([]string{}, orderColumns...),
+				item.SKU,
+				item.Name,
+				strconv.Itoa(item.Quantity),
+				userOrderExportMinor(item.UnitPriceMinor),
+				userOrderExportMinor(item.LineTotalMinor),
+			)
+			if err := writer.Write(row); err != nil {
+				response.InternalError(c, "Export failed")
+				return
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		response.InternalError(c, "Export failed")
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	c.Header("Cache-Control", "no-store")
+	c.Data(200, "text/csv; charset=utf-8", buffer.Bytes())
+}
+
+func writeUserOrdersXLSX(c *gin.Context, fileName string, orders []models.Order) {
+	f := excelize.NewFile()
+	defer func() {
+		_ = f.Close()
+	}()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Size: 11},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#E2E8F0"}, Pattern: 1},
+	})
+	if err != nil {
+		response.InternalError(c, "Export failed")
+		return
+	}
+
+	orderRows := make([][]string, 0, len(orders))
+	itemRows := make([][]string, 0, len(orders))
+	for _, order := range orders {
+		orderRows = append(orderRows, userOrderExportOrderRow(order))
+		for _, item := range order.Items {
+			itemRows = append(itemRows, userOrderExportItemRow(order.OrderNo, item))
+		}
+	}
+
+	if err := f.SetSheetName("Sheet1", "Orders"); err != nil {
+		response.InternalError(c, "Export failed")
+		return
+	}
+	if _, err := f.NewSheet("Order Items"); err != nil {
+		response.InternalError(c, "Export failed")
+		return
+	}
+	if err := writeUserOrderExportSheet(f, "Orders", userOrderExportOrderHeaders, orderRows, headerStyle); err != nil {
+		response.InternalError(c, "Export failed")
+		return
+	}
+	if err := writeUserOrderExportSheet(f, "Order Items", userOrderExportItemHeaders, itemRows, headerStyle); err != nil {
+		response.InternalError(c, "Export failed")
+		return
+	}
+	f.SetActiveSheet(0)
+
+	buffer, err := f.WriteToBuffer()
+	if err != nil {
+		response.InternalError(c, "Export failed")
+		return
+	}
+
+	c.Header("Content-Type", userOrderExportContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	c.Header("Cache-Control", "no-store")
+	c.Data(200, userOrderExportContentType, buffer.Bytes())
+}
+
+func writeUserOrderExportSheet(f *excelize.File, sheet string, headers []string, rows [][]string, headerStyle int) error {
+	for col, header := range headers {
+		cell, err := excelize.CoordinatesToCellName(col+1, 1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellValue(sheet, cell, header); err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(sheet, cell, cell, headerStyle); err != nil {
+			return err
+		}
+		columnName, err := excelize.ColumnNumberToName(col + 1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetColWidth(sheet, columnName, columnName, 18); err != nil {
+			return err
+		}
+	}
+	for rowIndex, row := range rows {
+		values := make([]interface{}, len(row))
+		for i, value := range row {
+			values[i] = value
+		}
+		cell, err := excelize.CoordinatesToCellName(1, rowIndex+2)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -30,6 +30,11 @@ type OrderHandler struct {
 	bindingService          *service.BindingService
 	virtualInventoryService *service.VirtualInventoryService
 	pluginManager           *service.PluginManagerService
+	timelineService         *service.OrderTimelineService
+	messageService          *service.OrderMessageService
+	claimService            *service.OrderClaimService
+	waitingRoomService      *service.WaitingRoomService
+	shipmentTrackingService *service.ShipmentTrackingService
 	cfg                     *config.Config
 }
 
@@ -49,11 +54,45 @@ func NewOrderHandler(
 	}
 }
 
+func (h *OrderHandler) SetTimelineService(timelineService *service.OrderTimelineService) {
+	h.timelineService = timelineService
+}
+
+// SetShipmentTrackingService 设置物流轨迹同步服务，订单详情附带物流轨迹
+func (h *OrderHandler) SetShipmentTrackingService(shipmentTrackingService *service.ShipmentTrackingService) {
+	h.shipmentTrackingService = shipmentTrackingService
+}
+
+// customerShipmentTracking 用户可见的物流轨迹（不含服务商与同步错误），运单号已变更的旧轨迹不返回
+func (h *OrderHandler) customerShipmentTracking(order *models.Order) gin.H {
+	if h.shipmentTrackingService == nil || order.TrackingNo == "" {
+		return nil
+	}
+	tracking, err := h.shipmentTrackingService.GetOrderTracking(order.ID)
+	if err != nil || tracking == nil || tracking.TrackingNo != order.TrackingNo {
+		return nil
+	}
+	return gin.H{
+		"tracking_no":    tracking.TrackingNo,
+		"carrier":        tracking.Carrier,
+		"status":         tracking.Status,
+		"events":         tracking.Events,
+		"delivered_at":   tracking.DeliveredAt,
+		"last_synced_at": tracking.LastSyncedAt,
+	}
+}
+
+// SetWaitingRoomService 设置抢购等候室服务，开启后需排队商品下单时校验放行凭证
+func (h *OrderHandler) SetWaitingRoomService(waitingRoomService *service.WaitingRoomService) {
+	h.waitingRoomService = waitingRoomService
+}
+
 // CreateOrderRequest - Create order request
 type CreateOrderRequest struct {
-	Items     []models.OrderItem `json:"items" binding:"required"`
-	Remark    string             `json:"remark"`
-	PromoCode string             `json:"promo_code"`
+	Items            []models.OrderItem `json:"items" binding:"required"`
+	Remark           string             `json:"remark"`
+	PromoCode        string             `json:"promo_code"`
+	WaitingRoomToken string             `json:"waiting_room_token"` // 等候室放行凭证，也可通过 X-Waiting-Room-Token 请求头传递
 }
 
 // CreateOrder CreateOrder
@@ -112,8 +151,27 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		}
 	}
 
+	// 插件可能改写商品列表，放行校验以最终商品为准
+	if h.waitingRoomService != nil {
+		token := req.WaitingRoomToken
+		if token == "" {
+			token = c.GetHeader("X-Waiting-Room-Token")
+		}
+		if err := h.waitingRoomService.CheckOrderAdmission(userID, req.Items, token); err != nil {
+			if respondUserBizError(c, err) {
+				return
+			}
+			response.InternalError(c, "Failed to create order")
+			return
+		}
+	}
+
 	// Create order draft (internal user)
-	order, err := h.orderService.CreateUserOrder(userID, req.Items, req.Remark, req.PromoCode)
+	var storeID *uint
+	if id := middleware.GetStoreID(c); id != 0 {
+		storeID = &id
+	}
+	order, err := h.orderService.CreateUserOrderInStore(userID, storeID, req.Items, req.Remark, req.PromoCode)
 	if err != nil {
 		var bizErr *bizerr.Error
 		if errors.As(err, &bizErr) {
@@ -324,8 +382,10 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 	// 构建带有shared_to_support标记的订单列表
 	type OrderWithShared struct {
 		models.Order
-		SharedToSupport bool `json:"shared_to_support"`
+		SharedToSupport bool                        `json:"shared_to_support"`
+		DisplayAmount   *service.OrderDisplayAmount `json:"display_amount,omitempty"`
 	}
+	displayAmounts := h.orderService.ConvertedListDisplayAmounts(orders, h.orderService.UserDisplayCurrency(userID))
 	result := make([]OrderWithShared, len(orders))
 	for i, order := range orders {
 		// 未付款订单隐藏盲盒分配结果
@@ -338,6 +398,7 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 		result[i] = OrderWithShared{
 			Order:           order,
 			SharedToSupport: sharedMap[order.ID],
+			DisplayAmount:   displayAmounts[order.ID],
 		}
 	}
 
@@ -422,17 +483,50 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		"shipped_at":                  order.ShippedAt,
 		"completed_at":                order.CompletedAt,
 		"form_submitted_at":           order.FormSubmittedAt,
+		"payment_deadline_at":         order.PaymentDeadlineAt,
 		"user_email":                  order.UserEmail,
 		"email_notifications_enabled": order.EmailNotificationsEnabled,
 		"total_amount_minor":          order.TotalAmount,
 		"currency":                    order.Currency,
+		"display_amounts":             h.orderService.ConvertedDisplayAmounts(order, h.orderService.UserDisplayCurrency(userID)),
 		"remark":                      order.Remark,
 		"created_at":                  order.CreatedAt,
 		"updated_at":                  order.UpdatedAt,
 		"shared_to_support":           sharedToSupport,
+		"shipment_tracking":           h.customerShipmentTracking(order),
+		"merged_into_order_no":        order.MergedIntoOrderNo,
 	})
 }
 
+// GetOrderTimeline - 用户侧订单时间线（含预计付款截止、发货与送达时间）
+func (h *OrderHandler) GetOrderTimeline(c *gin.Context) {
+	userID, userIDOK := middleware.RequireUserID(c)
+	if !userIDOK {
+		return
+	}
+	if h.timelineService == nil {
+		response.InternalError(c, "Order timeline unavailable")
+		return
+	}
+
+	order, err := h.orderService.GetOrderByNo(c.Param("order_no"))
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if order.UserID == nil || *order.UserID != userID {
+		response.Forbidden(c, "No permission to access this order")
+		return
+	}
+
+	timeline, err := h.timelineService.BuildCustomerTimeline(order)
+	if err != nil {
+		response.InternalServerError(c, "Failed to build order timeline", err)
+		return
+	}
+	response.Success(c, timeline)
+}
+
 // CompleteOrderRequest - Complete order request
 type CompleteOrderRequest struct {
 	Feedback string `json:"feedback"`
@@ -657,6 +751,16 @@ func (h *OrderHandler) GetVirtualProducts(c *gin.Context) {
 		return
 	}
 
+	// 已撤销的卡密只保留状态，不再向用户展示内容；其余默认脱敏，明文需调用 reveal 接口
+	for i := range stocks {
+		if stocks[i].Status == models.VirtualStockStatusRevoked {
+			stocks[i].Content = ""
+			stocks[i].Presentation = ""
+			continue
+		}
+		stocks[i].MaskContent()
+	}
+
 	// 根据配置决定是否向用户展示虚拟产品备注
 	if !h.cfg.Order.ShowVirtualStockRemark {
 		for i := range stocks {
@@ -701,9 +805,12 @@ func formatAmount(amount int64, currency string) string {
 
 // invoiceItem 账单行项目
 type invoiceItem struct {
-	Name     string
-	SKU      string
-	Quantity int
+	Name      string
+	SKU       string
+	Quantity  int
+	UnitPrice string // 下单单价快照，旧订单为空
+	Discount  string // 分摊优惠，无优惠时为空
+	LineTotal string
 }
 
 // invoiceData 账单模板数据
@@ -813,12 +920,21 @@ func (h *OrderHandler) buildInvoiceData(order *models.Order, invoiceCfg *config.
 
 	// 构建商品列表
 	var items []invoiceItem
+	hasItemPricing := order.HasItemPricing()
 	for _, item := range order.Items {
-		items = append(items, invoiceItem{
+		row := invoiceItem{
 			Name:     item.Name,
 			SKU:      item.SKU,
 			Quantity: item.Quantity,
-		})
+		}
+		if hasItemPricing {
+			row.UnitPrice = formatAmount(item.UnitPriceMinor, currency)
+			row.LineTotal = formatAmount(item.LineTotalMinor, currency)
+			if item.DiscountMinor != 0 {
+				row.Discount = formatAmount(-item.DiscountMinor, currency)
+			}
+		}
+		items = append(items, row)
 	}
 
 	discount := order.DiscountAmount
@@ -958,6 +1074,139 @@ func (h *OrderHandler) GetInvoiceToken(c *gin.Context) {
 	})
 }
 
+// loadDeliveredVirtualOrder 加载当前用户已付款的订单，失败时已写入响应
+func (h *OrderHandler) loadDeliveredVirtualOrder(c *gin.Context) (*models.Order, bool) {
+	userID, userIDOK := middleware.RequireUserID(c)
+	if !userIDOK {
+		return nil, false
+	}
+	order, err := h.orderService.GetOrderByNo(c.Param("order_no"))
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return nil, false
+	}
+	if order.UserID == nil || *order.UserID != userID {
+		response.Forbidden(c, "No permission to access this order")
+		return nil, false
+	}
+	if order.Status == models.OrderStatusPendingPayment || order.Status == models.OrderStatusDraft || order.Status == models.OrderStatusNeedResubmit {
+		response.BadRequest(c, "Virtual products are not available yet")
+		return nil, false
+	}
+	return order, true
+}
+
+// RevealVirtualProduct 显示卡密明文并记录查看日志，列表接口只返回脱敏内容
+func (h *OrderHandler) RevealVirtualProduct(c *gin.Context) {
+	if h == nil || h.virtualInventoryService == nil {
+		response.InternalError(c, "Virtual products are unavailable")
+		return
+	}
+	stockID, err := middleware.GetUintParam(c, "stock_id")
+	if err != nil {
+		response.BadRequest(c, "Invalid stock ID")
+		return
+	}
+	order, ok := h.loadDeliveredVirtualOrder(c)
+	if !ok {
+		return
+	}
+	viewer := service.VirtualStockRevealViewer{
+		Type:  models.VirtualStockRevealViewerUser,
+		ID:    order.UserID,
+		Email: c.GetString("user_email"),
+		IP:    c.ClientIP(),
+	}
+	stock, err := h.virtualInventoryService.RevealStock(stockID, order.OrderNo, viewer)
+	if err != nil {
+		if respondUserBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to reveal virtual product")
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, gin.H{
+		"content":         stock.Content,
+		"first_viewed_at": stock.FirstViewedAt,
+	})
+}
+
+// ResendVirtualProductsEmail 重发虚拟商品邮件，每个订单 10 分钟内只能重发一次
+func (h *OrderHandler) ResendVirtualProductsEmail(c *gin.Context) {
+	if h == nil || h.orderService == nil {
+		response.InternalError(c, "Virtual products are unavailable")
+		return
+	}
+	order, ok := h.loadDeliveredVirtualOrder(c)
+	if !ok {
+		return
+	}
+	cooldownKey := "virtual_email_resend:" + order.OrderNo
+	if n, _ := cache.Exists(cooldownKey); n > 0 {
+		response.Error(c, 429, response.CodeCooldown, "Please wait 10 minutes before requesting again")
+		return
+	}
+	if err := h.orderService.ResendVirtualItemsEmail(order); err != nil {
+		if respondUserBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to send email")
+		return
+	}
+	_ = cache.Set(cooldownKey, "1", 10*time.Minute)
+	response.Success(c, gin.H{"message": "Email sent"})
+}
+
+// GetVirtualDeliveryBySignedLink 通过邮件中的签名链接提取卡密（无需JWT认证）
+func (h *OrderHandler) GetVirtualDeliveryBySignedLink(c *gin.Context) {
+	if h == nil || h.orderService == nil || h.virtualInventoryService == nil {
+		response.InternalError(c, "Virtual delivery is unavailable")
+		return
+	}
+	orderNo := c.Param("order_no")
+	if !service.VerifyVirtualDeliverySignature(orderNo, c.Query("expires"), c.Query("sig"), models.NowFunc()) {
+		response.Unauthorized(c, "Invalid or expired delivery link")
+		return
+	}
+
+	order, err := h.orderService.GetOrderByNo(orderNo)
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	switch order.Status {
+	case models.OrderStatusPendingPayment, models.OrderStatusDraft, models.OrderStatusNeedResubmit,
+		models.OrderStatusCancelled, models.OrderStatusRefundPending, models.OrderStatusRefunded:
+		response.BadRequest(c, "Virtual products are not available")
+		return
+	}
+
+	stocks, err := h.virtualInventoryService.GetLinkedDeliveryStocks(order)
+	if err != nil {
+		response.InternalError(c, "Failed to get virtual products")
+		return
+	}
+	if h.cfg == nil || !h.cfg.Order.ShowVirtualStockRemark {
+		for i := range stocks {
+			stocks[i].Remark = ""
+		}
+	}
+	if stocks == nil {
+		stocks = []service.VirtualDeliveryStock{}
+	}
+	if err := h.virtualInventoryService.RecordLinkReveals(order.OrderNo, stocks, c.ClientIP()); err != nil {
+		log.Printf("Failed to record virtual stock reveal: order=%s err=%v", order.OrderNo, err)
+	}
+	expiresAt, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+	c.Header("Cache-Control", "no-store")
+	response.Success(c, gin.H{
+		"order_no":   order.OrderNo,
+		"stocks":     stocks,
+		"expires_at": time.Unix(expiresAt, 0),
+	})
+}
+
 // ViewInvoiceByToken 通过一次性令牌查看账单（无需JWT认证）
 func (h *OrderHandler) ViewInvoiceByToken(c *gin.Context) {
 	token := c.Param("token")
@@ -1126,7 +1375,7 @@ tbody td{padding:12px 16px;font-size:14px;border-bottom:1px solid #f0f0f0}
 
     <table>
       <thead>
-        <tr><th>Item</th><th>SKU</th><th style="text-align:center">Qty</th></tr>
+        <tr><th>Item</th><th>SKU</th><th style="text-align:center">Qty</th><th style="text-align:right">Unit Price</th><th style="text-align:right">Discount</th><th style="text-align:right">Amount</th></tr>
       </thead>
       <tbody>
         {{range .Items}}
@@ -1134,6 +1383,9 @@ tbody td{padding:12px 16px;font-size:14px;border-bottom:1px solid #f0f0f0}
           <td><div class="item-name">{{.Name}}</div></td>
           <td><span class="item-sku">{{.SKU}}</span></td>
           <td style="text-align:center">{{.Quantity}}</td>
+          <td style="text-align:right">{{if .UnitPrice}}{{.UnitPrice}}{{else}}-{{end}}</td>
+          <td style="text-align:right">{{if .Discount}}{{.Discount}}{{else}}-{{end}}</td>
+          <td style="text-align:right">{{if .LineTotal}}{{.LineTotal}}{{else}}-{{end}}</td>
         </tr>
         {{end}}
       </tbody>
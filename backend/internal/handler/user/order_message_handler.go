@@ -0,0 +1,96 @@
+package user
+
+import (
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *OrderHandler) SetMessageService(messageService *service.OrderMessageService) {
+	h.messageService = messageService
+}
+
+// SendOrderMessageRequest 订单留言请求
+type SendOrderMessageRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// loadOwnOrderForMessages 加载当前用户自己的订单
+func (h *OrderHandler) loadOwnOrderForMessages(c *gin.Context) (*models.Order, uint, bool) {
+	userID, userIDOK := middleware.RequireUserID(c)
+	if !userIDOK {
+		return nil, 0, false
+	}
+	if h.messageService == nil {
+		response.InternalError(c, "Order messages unavailable")
+		return nil, 0, false
+	}
+
+	order, err := h.orderService.GetOrderByNo(c.Param("order_no"))
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return nil, 0, false
+	}
+	if order.UserID == nil || *order.UserID != userID {
+		response.Forbidden(c, "No permission to access this order")
+		return nil, 0, false
+	}
+	return order, userID, true
+}
+
+// GetOrderMessages 获取订单留言（同时标记卖家回复为已读）
+func (h *OrderHandler) GetOrderMessages(c *gin.Context) {
+	order, _, ok := h.loadOwnOrderForMessages(c)
+	if !ok {
+		return
+	}
+	thread, err := h.messageService.Thread(order.ID, service.OrderMessageSenderUser)
+	if err != nil {
+		response.InternalServerError(c, "Failed to load order messages", err)
+		return
+	}
+	response.Success(c, thread)
+}
+
+// SendOrderMessage 就订单联系卖家
+func (h *OrderHandler) SendOrderMessage(c *gin.Context) {
+	order, userID, ok := h.loadOwnOrderForMessages(c)
+	if !ok {
+		return
+	}
+	var req SendOrderMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+
+	message, err := h.messageService.Send(order, service.OrderMessageSenderUser, userID, req.Content)
+	if err != nil {
+		if respondUserBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to send order message", err)
+		return
+	}
+	response.Success(c, message)
+}
+
+// EscalateOrderMessages 将订单留言升级为工单
+func (h *OrderHandler) EscalateOrderMessages(c *gin.Context) {
+	order, userID, ok := h.loadOwnOrderForMessages(c)
+	if !ok {
+		return
+	}
+
+	ticket, err := h.messageService.Escalate(order, service.OrderMessageSenderUser, userID)
+	if err != nil {
+		if respondUserBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to create ticket", err)
+		return
+	}
+	response.Success(c, ticket)
+}
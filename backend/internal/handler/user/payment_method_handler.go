@@ -2,6 +2,7 @@ package user
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -257,12 +258,137 @@ func (h *PaymentMethodHandler) HandleWebhook(c *gin.Context) {
 			}
 		}
 	}
+	result, err := h.service.ExecuteWebhook(uint(paymentMethodID), buildPaymentWebhookRequest(c, hookKey, rawBody, queryParams, headers))
+	if err != nil {
+		response.HandleError(c, "Payment webhook execution failed", err)
+		return
+	}
+	if !h.applyPaymentWebhookResult(c, pm.ID, result, "payment_webhook") {
+		return
+	}
+	writePaymentWebhookResponse(c, result)
+}
+
+// HandlePaymentNotify 处理网关服务端异步通知（server-to-server notify）
+// 清单中声明了 payment_notify webhook 时先按其鉴权方式校验；否则脚本必须返回 verified: true 才会确认付款
+func (h *PaymentMethodHandler) HandlePaymentNotify(c *gin.Context) {
+	paymentMethodID, err := strconv.ParseUint(c.Param("payment_method_id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid payment method ID")
+		return
+	}
+	pm, err := h.service.Get(uint(paymentMethodID))
+	if err != nil {
+		response.NotFound(c, "Payment method not found")
+		return
+	}
+	if !pm.Enabled {
+		response.BadRequest(c, "Payment method is disabled")
+		return
+	}
+
+	rawBody, err := readPaymentWebhookBody(c.Request.Body, maxPaymentWebhookBodyBytes)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	queryParams := normalizePaymentWebhookQueryParams(c.Request.URL.Query())
+	headers := normalizePaymentWebhookHeaders(c.Request.Header)
+	declaredWebhooks, err := service.ParseDeclaredWebhookManifests(pm.Manifest)
+	if err != nil {
+		response.InternalServerError(c, "Payment webhook manifest is invalid", err)
+		return
+	}
+	hostVerified := false
+	if declaredWebhook, exists := service.FindDeclaredWebhookManifest(declaredWebhooks, paymentNotifyHookKey); exists {
+		if !service.DeclaredWebhookAllowsMethod(declaredWebhook, c.Request.Method) {
+			response.Error(c, http.StatusMethodNotAllowed, response.CodeParamError, "Webhook method is not allowed")
+			return
+		}
+		if declaredWebhook.AuthMode != "none" {
+			secrets, secretErr := service.BuildWebhookSecretsFromConfig(pm.Config)
+			if secretErr != nil {
+				response.InternalServerError(c, "Payment webhook configuration is invalid", secretErr)
+				return
+			}
+			if authErr := service.AuthenticateDeclaredWebhookRequest(declaredWebhook, queryParams, headers, rawBody, secrets); authErr != nil {
+				log.Printf("payment notify authentication failed: payment_method=%d err=%v", pm.ID, authErr)
+				response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "Webhook authentication failed")
+				return
+			}
+			hostVerified = true
+		}
+	}
+
+	result, err := h.service.ExecutePaymentNotify(pm, buildPaymentWebhookRequest(c, paymentNotifyHookKey, rawBody, queryParams, headers))
+	if err != nil {
+		if errors.Is(err, service.ErrPaymentNotifyNotImplemented) {
+			response.NotFound(c, "Payment method does not accept notifications")
+			return
+		}
+		response.HandleError(c, "Payment notify execution failed", err)
+		return
+	}
+	if result != nil && result.Paid && !result.Verified && !hostVerified {
+		log.Printf("payment notify signature not verified: payment_method=%d order_no=%s", pm.ID, result.OrderNo)
+		response.Error(c, http.StatusUnauthorized, response.CodeUnauthorized, "Payment notify signature verification failed")
+		return
+	}
+	if !h.applyPaymentWebhookResult(c, pm.ID, result, "payment_notify") {
+		return
+	}
+	writePaymentWebhookResponse(c, result)
+}
+
+const paymentNotifyHookKey = "payment_notify"
+
+// HandleStripeWebhook 处理原生 Stripe 集成的 webhook；签名校验失败返回 400 以便 Stripe 重试
+func (h *PaymentMethodHandler) HandleStripeWebhook(c *gin.Context) {
+	paymentMethodID, err := strconv.ParseUint(c.Param("payment_method_id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, "Invalid payment method ID")
+		return
+	}
+	pm, err := h.service.Get(uint(paymentMethodID))
+	if err != nil || pm.Provider != models.PaymentProviderStripe {
+		response.NotFound(c, "Payment method not found")
+		return
+	}
+
+	rawBody, err := readPaymentWebhookBody(c.Request.Body, maxPaymentWebhookBodyBytes)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	outcome, err := h.service.HandleStripeWebhook(pm, rawBody, c.GetHeader("Stripe-Signature"))
+	if err != nil {
+		if errors.Is(err, service.ErrStripeWebhookSignature) {
+			log.Printf("stripe webhook signature verification failed: payment_method=%d", pm.ID)
+			response.BadRequest(c, "Webhook signature verification failed")
+			return
+		}
+		response.HandleError(c, "Stripe webhook processing failed", err)
+		return
+	}
+	if outcome.Payment != nil {
+		if h.pollingService == nil {
+			response.InternalError(c, "Payment webhook confirmation is unavailable")
+			return
+		}
+		if _, err := h.pollingService.ConfirmPaymentResult(outcome.OrderID, pm.ID, outcome.Payment, service.PaymentSourceStripeWebhook); err != nil {
+			response.HandleError(c, "Failed to confirm payment webhook", err)
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+func buildPaymentWebhookRequest(c *gin.Context, hookKey string, rawBody []byte, queryParams, headers map[string]string) *service.PaymentWebhookRequest {
 	bodyText := ""
 	if utf8.Valid(rawBody) {
 		bodyText = string(rawBody)
 	}
-
-	result, err := h.service.ExecuteWebhook(uint(paymentMethodID), &service.PaymentWebhookRequest{
+	return &service.PaymentWebhookRequest{
 		Key:         hookKey,
 		Method:      strings.ToUpper(strings.TrimSpace(c.Request.Method)),
 		Path:        strings.TrimSpace(c.Request.URL.Path),
@@ -273,50 +399,48 @@ func (h *PaymentMethodHandler) HandleWebhook(c *gin.Context) {
 		BodyBase64:  base64.StdEncoding.EncodeToString(rawBody),
 		ContentType: strings.TrimSpace(c.ContentType()),
 		RemoteAddr:  strings.TrimSpace(utils.GetRealIP(c)),
-	})
-	if err != nil {
-		response.HandleError(c, "Payment webhook execution failed", err)
-		return
 	}
+}
 
+// applyPaymentWebhookResult 按脚本结果确认付款或加入轮询队列；确认在订单行锁内完成，重复通知幂等
+func (h *PaymentMethodHandler) applyPaymentWebhookResult(c *gin.Context, paymentMethodID uint, result *service.PaymentWebhookResult, source string) bool {
 	orderID, err := h.resolveWebhookOrderID(result)
 	if err != nil {
 		response.HandleError(c, "Failed to resolve webhook order", err)
-		return
+		return false
 	}
 
 	if result != nil && result.Paid {
 		if h.pollingService == nil {
 			response.InternalError(c, "Payment webhook confirmation is unavailable")
-			return
+			return false
 		}
-		_, err = h.pollingService.ConfirmPaymentResult(orderID, uint(paymentMethodID), &service.PaymentCheckResult{
+		_, err = h.pollingService.ConfirmPaymentResult(orderID, paymentMethodID, &service.PaymentCheckResult{
 			Paid:          true,
 			TransactionID: result.TransactionID,
 			Message:       result.Message,
 			Data:          result.Data,
-		}, "payment_webhook")
+		}, source)
 		if err != nil {
 			response.HandleError(c, "Failed to confirm payment webhook", err)
-			return
+			return false
 		}
 	}
 
 	if result != nil && result.QueuePolling {
 		if h.pollingService == nil {
 			response.InternalError(c, "Payment polling service is unavailable")
-			return
+			return false
 		}
-		if err := h.pollingService.AddToQueue(orderID, uint(paymentMethodID)); err != nil {
+		if err := h.pollingService.AddToQueue(orderID, paymentMethodID); err != nil {
 			response.HandleError(c, "Failed to queue payment polling task", err)
-			return
+			return false
 		}
 	}
-
-	writePaymentWebhookResponse(c, result)
+	return true
 }
 
-// List 获取可用的付款方式列表
+// List 获取可用的付款方式列表；传入 order_no 时按订单标签过滤
 func (h *PaymentMethodHandler) List(c *gin.Context) {
 	methods, err := h.service.GetEnabledMethods()
 	if err != nil {
@@ -324,9 +448,30 @@ func (h *PaymentMethodHandler) List(c *gin.Context) {
 		return
 	}
 
+	var order *models.Order
+	if orderNo := strings.TrimSpace(c.Query("order_no")); orderNo != "" {
+		userID, userIDOK := middleware.RequireUserID(c)
+		if !userIDOK {
+			return
+		}
+		var found models.Order
+		if err := h.db.Select("id", "tags").Where("order_no = ? AND user_id = ?", orderNo, userID).First(&found).Error; err != nil {
+			response.NotFound(c, "Order not found")
+			return
+		}
+		order = &found
+	}
+
 	// 返回简化的付款方式信息（不包含脚本和配置详情）
+	storeID := middleware.GetStoreID(c)
 	var items []gin.H
 	for _, pm := range methods {
+		if storeID != 0 && !models.BelongsToStore(pm.StoreID, storeID) {
+			continue
+		}
+		if order != nil && !pm.AllowsOrderTags(order.Tags) {
+			continue
+		}
 		items = append(items, gin.H{
 			"id":          pm.ID,
 			"name":        pm.Name,
@@ -371,7 +516,7 @@ func (h *PaymentMethodHandler) GetPaymentCard(c *gin.Context) {
 	// 生成付款卡片
 	result, err := h.service.GeneratePaymentCard(uint(paymentMethodID), &order)
 	if err != nil {
-		response.InternalError(c, "Failed to generate payment info")
+		response.HandleError(c, "Failed to generate payment info", err)
 		return
 	}
 
@@ -449,8 +594,8 @@ func (h *PaymentMethodHandler) SelectPaymentMethod(c *gin.Context) {
 		return
 	}
 
-	// 将订单加入付款状态轮询队列
-	if h.pollingService != nil {
+	// 将订单加入付款状态轮询队列（货到付款订单已转为待发货，无需轮询）
+	if pm, _ := h.service.Get(req.PaymentMethodID); h.pollingService != nil && (pm == nil || !pm.CashOnDelivery) {
 		if err := h.pollingService.AddToQueue(order.ID, req.PaymentMethodID); err != nil {
 			response.HandleError(c, "Failed to queue payment polling task", err)
 			return
@@ -524,6 +669,9 @@ func (h *PaymentMethodHandler) GetOrderPaymentInfo(c *gin.Context) {
 		methods, _ := h.service.GetEnabledMethods()
 		var items []gin.H
 		for _, m := range methods {
+			if !m.AllowsOrderTags(order.Tags) {
+				continue
+			}
 			items = append(items, gin.H{
 				"id":          m.ID,
 				"name":        m.Name,
@@ -8,6 +8,7 @@ import (
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/response"
+	"auralogic/internal/repository"
 	"auralogic/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -18,6 +19,8 @@ type ProductHandler struct {
 	bindingService          *service.BindingService
 	virtualInventoryService *service.VirtualInventoryService
 	pluginManager           *service.PluginManagerService
+	seoService              *service.SEOService
+	quoteRequestService     *service.QuoteRequestService
 }
 
 func NewProductHandler(
@@ -26,6 +29,7 @@ func NewProductHandler(
 	bindingService *service.BindingService,
 	virtualInventoryService *service.VirtualInventoryService,
 	pluginManager *service.PluginManagerService,
+	seoService *service.SEOService,
 ) *ProductHandler {
 	return &ProductHandler{
 		productService:          productService,
@@ -33,9 +37,15 @@ func NewProductHandler(
 		bindingService:          bindingService,
 		virtualInventoryService: virtualInventoryService,
 		pluginManager:           pluginManager,
+		seoService:              seoService,
 	}
 }
 
+// SetQuoteRequestService 注入询价单服务，用于按客户等级隐藏询价商品价格
+func (h *ProductHandler) SetQuoteRequestService(quoteRequestService *service.QuoteRequestService) {
+	h.quoteRequestService = quoteRequestService
+}
+
 func productHookOptionalBoolValue(value *bool) interface{} {
 	if value == nil {
 		return nil
@@ -68,6 +78,11 @@ func buildUserProductHookPayload(product *models.Product) map[string]interface{}
 		"inventory_mode":       product.InventoryMode,
 		"auto_delivery":        product.AutoDelivery,
 		"max_purchase_limit":   product.MaxPurchaseLimit,
+		"price_tiers":          product.PriceTiers,
+		"min_order_quantity":   product.MinOrderQuantity,
+		"max_order_quantity":   product.MaxOrderQuantity,
+		"quantity_increment":   product.QuantityIncrement,
+		"price_on_request":     product.PriceOnRequest,
 		"created_at":           product.CreatedAt,
 		"updated_at":           product.UpdatedAt,
 	}
@@ -186,6 +201,16 @@ func emitProductListReadOnlyAfterHook(
 	})), payload, len(products))
 }
 
+// applyDisplayPrices 已登录用户设置了展示币种时附加换算参考价；询价商品对无权看价的访客隐藏价格
+func (h *ProductHandler) applyDisplayPrices(products []models.Product, userID *uint) {
+	if h.orderService != nil && userID != nil && len(products) > 0 {
+		h.orderService.ApplyProductDisplayPrices(products, h.orderService.UserDisplayCurrency(*userID))
+	}
+	if h.quoteRequestService != nil {
+		h.quoteRequestService.ApplyPriceVisibility(products, userID)
+	}
+}
+
 // ListProducts Product列表（User端，仅显示上架Product）
 func (h *ProductHandler) ListProducts(c *gin.Context) {
 	page, limit := response.GetPagination(c)
@@ -206,12 +231,13 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 	executeProductListReadOnlyBeforeHook(h.pluginManager, c, optionalUserID, page, limit, category, search, isFeatured, nil, "catalog")
 
 	// User端只显示上架Product
-	products, total, err := h.productService.ListProducts(page, limit, string(models.ProductStatusActive), category, search, isFeatured, nil, true)
+	products, total, err := h.productService.ListProducts(page, limit, string(models.ProductStatusActive), category, search, isFeatured, nil, true, repository.StorefrontScope(middleware.GetStoreID(c)))
 	if err != nil {
 		response.InternalError(c, "Query failed")
 		return
 	}
 	emitProductListReadOnlyAfterHook(h.pluginManager, c, optionalUserID, page, limit, category, search, isFeatured, nil, "catalog", products, total)
+	h.applyDisplayPrices(products, optionalUserID)
 
 	response.Paginated(c, products, page, limit, total)
 }
@@ -235,11 +261,15 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
-	// 只返回上架的Product
+	// 只返回上架且属于当前店铺的Product
 	if product.Status != models.ProductStatusActive {
 		response.NotFound(c, "Product not found")
 		return
 	}
+	if storeID := middleware.GetStoreID(c); storeID != 0 && !models.BelongsToStore(product.StoreID, storeID) {
+		response.NotFound(c, "Product not found")
+		return
+	}
 	if h.pluginManager != nil {
 		payload := buildUserProductHookPayload(product)
 		payload["user_id"] = optionalUserID
@@ -265,6 +295,13 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 			"product_id":    strconv.FormatUint(productID, 10),
 		})), payload, product.ID)
 	}
+	if h.seoService != nil {
+		store, _ := middleware.GetStore(c)
+		product.SEO = h.seoService.BuildProductSEO(product, h.seoService.BaseURL(store, c.Request.Host))
+	}
+	products := []models.Product{*product}
+	h.applyDisplayPrices(products, optionalUserID)
+	*product = products[0]
 
 	response.Success(c, product)
 }
@@ -384,12 +421,13 @@ func (h *ProductHandler) GetFeaturedProducts(c *gin.Context) {
 
 	isFeatured := true
 	executeProductListReadOnlyBeforeHook(h.pluginManager, c, optionalUserID, 1, limit, "", "", &isFeatured, nil, "featured")
-	products, total, err := h.productService.ListProducts(1, limit, string(models.ProductStatusActive), "", "", &isFeatured, nil, true)
+	products, total, err := h.productService.ListProducts(1, limit, string(models.ProductStatusActive), "", "", &isFeatured, nil, true, repository.StorefrontScope(middleware.GetStoreID(c)))
 	if err != nil {
 		response.InternalError(c, "Query failed")
 		return
 	}
 	emitProductListReadOnlyAfterHook(h.pluginManager, c, optionalUserID, 1, limit, "", "", &isFeatured, nil, "featured", products, total)
+	h.applyDisplayPrices(products, optionalUserID)
 
 	response.Success(c, gin.H{"products": products})
 }
@@ -408,12 +446,13 @@ func (h *ProductHandler) GetRecommendedProducts(c *gin.Context) {
 
 	isRecommended := true
 	executeProductListReadOnlyBeforeHook(h.pluginManager, c, optionalUserID, 1, limit, "", "", nil, &isRecommended, "recommended")
-	products, total, err := h.productService.ListProducts(1, limit, string(models.ProductStatusActive), "", "", nil, &isRecommended, true)
+	products, total, err := h.productService.ListProducts(1, limit, string(models.ProductStatusActive), "", "", nil, &isRecommended, true, repository.StorefrontScope(middleware.GetStoreID(c)))
 	if err != nil {
 		response.InternalError(c, "Query failed")
 		return
 	}
 	emitProductListReadOnlyAfterHook(h.pluginManager, c, optionalUserID, 1, limit, "", "", nil, &isRecommended, "recommended", products, total)
+	h.applyDisplayPrices(products, optionalUserID)
 
 	response.Success(c, gin.H{"products": products})
 }
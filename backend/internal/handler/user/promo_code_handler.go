@@ -16,6 +16,7 @@ import (
 type PromoCodeHandler struct {
 	promoCodeService *service.PromoCodeService
 	pluginManager    *service.PluginManagerService
+	cartService      *service.CartService
 }
 
 func NewPromoCodeHandler(promoCodeService *service.PromoCodeService, pluginManager *service.PluginManagerService) *PromoCodeHandler {
@@ -25,6 +26,11 @@ func NewPromoCodeHandler(promoCodeService *service.PromoCodeService, pluginManag
 	}
 }
 
+// SetCartService 注入购物车服务，结算推荐未传商品时使用购物车
+func (h *PromoCodeHandler) SetCartService(cartService *service.CartService) {
+	h.cartService = cartService
+}
+
 // ValidatePromoCodeRequest 验证优惠码请求
 type ValidatePromoCodeRequest struct {
 	Code        string `json:"code" binding:"required"`
@@ -32,6 +38,11 @@ type ValidatePromoCodeRequest struct {
 	AmountMinor int64  `json:"amount_minor"`
 }
 
+// SuggestPromoCodesRequest 结算优惠推荐请求，items 为空时使用当前购物车
+type SuggestPromoCodesRequest struct {
+	Items []service.CheckoutLine `json:"items"`
+}
+
 func (h *PromoCodeHandler) buildPromoHookExecutionContext(c *gin.Context, userID uint) *service.ExecutionContext {
 	if c == nil {
 		return nil
@@ -216,3 +227,41 @@ func (h *PromoCodeHandler) ValidatePromoCode(c *gin.Context) {
 		"discount_minor":         discount,
 	})
 }
+
+// SuggestPromoCodes 结算时推荐可用的优惠码和自动活动
+func (h *PromoCodeHandler) SuggestPromoCodes(c *gin.Context) {
+	userID, userIDOK := middleware.RequireUserID(c)
+	if !userIDOK {
+		return
+	}
+
+	var req SuggestPromoCodesRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request parameters")
+			return
+		}
+	}
+
+	lines := req.Items
+	if len(lines) == 0 && h.cartService != nil {
+		cartItems, err := h.cartService.GetCart(userID)
+		if err != nil {
+			response.InternalServerError(c, "Failed to load cart", err)
+			return
+		}
+		for _, item := range cartItems {
+			if !item.IsAvailable {
+				continue
+			}
+			lines = append(lines, service.CheckoutLine{ProductID: item.ProductID, Quantity: item.Quantity})
+		}
+	}
+
+	suggestions, err := h.promoCodeService.SuggestForCheckout(lines)
+	if err != nil {
+		response.InternalServerError(c, "Failed to load promo suggestions", err)
+		return
+	}
+	response.Success(c, suggestions)
+}
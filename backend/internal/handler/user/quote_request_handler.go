@@ -0,0 +1,152 @@
+package user
+
+import (
+	"strconv"
+
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type QuoteRequestHandler struct {
+	quoteRequestService *service.QuoteRequestService
+}
+
+func NewQuoteRequestHandler(quoteRequestService *service.QuoteRequestService) *QuoteRequestHandler {
+	return &QuoteRequestHandler{quoteRequestService: quoteRequestService}
+}
+
+func parseQuoteRequestID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondQuoteRequestError(c *gin.Context, err error, fallback string) {
+	if respondUserBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// CreateQuoteRequest 询价模式商品提交询价单
+func (h *QuoteRequestHandler) CreateQuoteRequest(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	var req service.QuoteRequestInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	quote, err := h.quoteRequestService.Create(userID, req)
+	if err != nil {
+		respondQuoteRequestError(c, err, "Failed to submit quote request")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create", "quote_request", &quote.ID, map[string]interface{}{
+		"quote_no":   quote.QuoteNo,
+		"product_id": quote.ProductID,
+		"quantity":   quote.Quantity,
+	})
+	response.Success(c, quote)
+}
+
+// ListQuoteRequests 我的询价单
+func (h *QuoteRequestHandler) ListQuoteRequests(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	page, limit := response.GetPagination(c)
+	items, total, err := h.quoteRequestService.ListForUser(userID, page, limit)
+	if err != nil {
+		response.InternalError(c, "Query failed")
+		return
+	}
+	response.Paginated(c, items, page, limit, total)
+}
+
+// GetQuoteRequest 询价单详情
+func (h *QuoteRequestHandler) GetQuoteRequest(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseQuoteRequestID(c)
+	if !ok {
+		return
+	}
+	quote, err := h.quoteRequestService.GetForUser(userID, id)
+	if err != nil {
+		respondQuoteRequestError(c, err, "Failed to load quote request")
+		return
+	}
+	response.Success(c, quote)
+}
+
+// AcceptQuote 接受报价
+func (h *QuoteRequestHandler) AcceptQuote(c *gin.Context) {
+	h.respondToQuote(c, true)
+}
+
+// DeclineQuote 拒绝报价
+func (h *QuoteRequestHandler) DeclineQuote(c *gin.Context) {
+	h.respondToQuote(c, false)
+}
+
+func (h *QuoteRequestHandler) respondToQuote(c *gin.Context, accept bool) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseQuoteRequestID(c)
+	if !ok {
+		return
+	}
+	quote, err := h.quoteRequestService.Respond(userID, id, accept)
+	if err != nil {
+		respondQuoteRequestError(c, err, "Failed to respond to quote")
+		return
+	}
+
+	action := "decline"
+	if accept {
+		action = "accept"
+	}
+	logger.LogOperation(database.GetDB(), c, action, "quote_request", &quote.ID, map[string]interface{}{
+		"quote_no":                quote.QuoteNo,
+		"quoted_unit_price_minor": quote.QuotedUnitPriceMinor,
+	})
+	response.Success(c, quote)
+}
+
+// CancelQuoteRequest 撤回询价单
+func (h *QuoteRequestHandler) CancelQuoteRequest(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseQuoteRequestID(c)
+	if !ok {
+		return
+	}
+	quote, err := h.quoteRequestService.Cancel(userID, id)
+	if err != nil {
+		respondQuoteRequestError(c, err, "Failed to cancel quote request")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "cancel", "quote_request", &quote.ID, map[string]interface{}{
+		"quote_no": quote.QuoteNo,
+	})
+	response.Success(c, quote)
+}
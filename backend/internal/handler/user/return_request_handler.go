@@ -0,0 +1,155 @@
+package user
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/database"
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReturnRequestHandler struct {
+	returnRequestService *service.ReturnRequestService
+}
+
+func NewReturnRequestHandler(returnRequestService *service.ReturnRequestService) *ReturnRequestHandler {
+	return &ReturnRequestHandler{returnRequestService: returnRequestService}
+}
+
+func respondReturnRequestError(c *gin.Context, err error, fallback string) {
+	if respondUserBizError(c, err) {
+		return
+	}
+	response.InternalServerError(c, fallback, err)
+}
+
+// CreateReturnRequest 对已发货订单申请退货
+func (h *ReturnRequestHandler) CreateReturnRequest(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	var req service.ReturnRequestInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request parameters")
+		return
+	}
+	request, err := h.returnRequestService.Create(userID, c.Param("order_no"), req)
+	if err != nil {
+		respondReturnRequestError(c, err, "Failed to submit return request")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "create", "return_request", &request.ID, map[string]interface{}{
+		"rma_no":   request.RMANo,
+		"order_no": request.OrderNo,
+		"items":    request.Items,
+	})
+	response.Success(c, request)
+}
+
+// ListReturnRequests 订单的退货申请
+func (h *ReturnRequestHandler) ListReturnRequests(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	items, err := h.returnRequestService.ListForOrder(userID, c.Param("order_no"))
+	if err != nil {
+		respondReturnRequestError(c, err, "Query failed")
+		return
+	}
+	response.Success(c, items)
+}
+
+// CancelReturnRequest 撤回尚未审核的退货申请
+func (h *ReturnRequestHandler) CancelReturnRequest(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		response.BadRequest(c, "Invalid ID")
+		return
+	}
+	request, err := h.returnRequestService.Cancel(userID, c.Param("order_no"), uint(id))
+	if err != nil {
+		respondReturnRequestError(c, err, "Failed to cancel return request")
+		return
+	}
+
+	logger.LogOperation(database.GetDB(), c, "cancel", "return_request", &request.ID, map[string]interface{}{
+		"rma_no": request.RMANo,
+	})
+	response.Success(c, request)
+}
+
+// UploadReturnPhoto 上传退货照片，返回的 path 在提交申请时放入 photos
+func (h *ReturnRequestHandler) UploadReturnPhoto(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	order, err := h.returnRequestService.FindUserOrder(userID, c.Param("order_no"))
+	if err != nil {
+		respondReturnRequestError(c, err, "Failed to upload photo")
+		return
+	}
+	if err := service.EnsureOrderAcceptsReturns(order); err != nil {
+		respondReturnRequestError(c, err, "Failed to upload photo")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		respondUserBizError(c, bizerr.New("return.photoRequired", "Please select a photo to upload"))
+		return
+	}
+	if file.Size > service.MaxReturnPhotoSize {
+		respondUserBizError(c, bizerr.Newf("return.photoTooLarge", "Photo must be at most %d MB", service.MaxReturnPhotoSize/1024/1024).
+			WithParams(map[string]interface{}{"max": service.MaxReturnPhotoSize / 1024 / 1024}))
+		return
+	}
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	allowed := false
+	for _, candidate := range service.ReturnPhotoExtensions {
+		if ext == candidate {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		respondUserBizError(c, bizerr.New("return.photoFormatUnsupported", "Unsupported photo format"))
+		return
+	}
+
+	cfg := config.GetConfig()
+	relDir := service.ReturnPhotoOwnerPrefix(userID) + time.Now().Format("2006/01/02")
+	targetDir := filepath.Join(cfg.Upload.Dir, "returns", filepath.FromSlash(relDir))
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		response.InternalError(c, "Failed to create directory")
+		return
+	}
+	filename := uuid.New().String() + ext
+	if err := c.SaveUploadedFile(file, filepath.Join(targetDir, filename)); err != nil {
+		response.InternalError(c, "Failed to save file")
+		return
+	}
+
+	relPath := relDir + "/" + filename
+	response.Success(c, gin.H{
+		"path":        relPath,
+		"preview_url": service.SignedReturnPhotoURL(relPath, time.Now()),
+	})
+}
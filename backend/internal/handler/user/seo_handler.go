@@ -0,0 +1,44 @@
+package user
+
+import (
+	"net/http"
+
+	"auralogic/internal/middleware"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SEOHandler struct {
+	seoService *service.SEOService
+}
+
+func NewSEOHandler(seoService *service.SEOService) *SEOHandler {
+	return &SEOHandler{seoService: seoService}
+}
+
+func (h *SEOHandler) requestBaseURL(c *gin.Context) string {
+	store, _ := middleware.GetStore(c)
+	return h.seoService.BaseURL(store, c.Request.Host)
+}
+
+// Sitemap 公开 GET /sitemap.xml
+func (h *SEOHandler) Sitemap(c *gin.Context) {
+	cfg := h.seoService.Config()
+	if cfg == nil || !cfg.SEO.SitemapEnabled {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	data, err := h.seoService.BuildSitemap(h.requestBaseURL(c), middleware.GetStoreID(c))
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", data)
+}
+
+// Robots 公开 GET /robots.txt
+func (h *SEOHandler) Robots(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(h.seoService.BuildRobots(h.requestBaseURL(c))))
+}
@@ -63,7 +63,7 @@ func (h *SerialHandler) VerifySerial(c *gin.Context) {
 			response.Error(c, 400, response.CodeParamMissing, "Captcha is required")
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(req.CaptchaToken, utils.GetRealIP(c)); err != nil {
+		if err := h.captchaService.VerifyCaptcha("serial_verify", req.CaptchaToken, utils.GetRealIP(c)); err != nil {
 			response.Error(c, 400, response.CodeParamError, "Captcha verification failed")
 			return
 		}
@@ -100,7 +100,7 @@ func (h *SerialHandler) GetSerialByNumber(c *gin.Context) {
 			response.Error(c, 400, response.CodeParamMissing, "Captcha is required")
 			return
 		}
-		if err := h.captchaService.VerifyCaptcha(captchaToken, utils.GetRealIP(c)); err != nil {
+		if err := h.captchaService.VerifyCaptcha("serial_verify", captchaToken, utils.GetRealIP(c)); err != nil {
 			response.Error(c, 400, response.CodeParamError, "Captcha verification failed")
 			return
 		}
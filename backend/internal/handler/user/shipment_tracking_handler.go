@@ -0,0 +1,48 @@
+package user
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const maxShipmentTrackingWebhookBodyBytes = int64(2 * 1024 * 1024)
+
+// ShipmentTrackingHandler 接收物流轨迹服务商的推送（公开接口，依靠签名校验）
+type ShipmentTrackingHandler struct {
+	service *service.ShipmentTrackingService
+}
+
+func NewShipmentTrackingHandler(trackingService *service.ShipmentTrackingService) *ShipmentTrackingHandler {
+	return &ShipmentTrackingHandler{service: trackingService}
+}
+
+// HandleWebhook 处理 17track / AfterShip 的轨迹更新推送
+func (h *ShipmentTrackingHandler) HandleWebhook(c *gin.Context) {
+	provider := c.Param("provider")
+	rawBody, err := io.ReadAll(io.LimitReader(c.Request.Body, maxShipmentTrackingWebhookBodyBytes+1))
+	if err != nil || int64(len(rawBody)) > maxShipmentTrackingWebhookBodyBytes {
+		response.BadRequest(c, "Invalid webhook body")
+		return
+	}
+
+	updated, err := h.service.HandleWebhook(provider, c.Request.Header, rawBody)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrShipmentTrackingDisabled):
+			response.NotFound(c, "Shipment tracking webhook not found")
+		case errors.Is(err, service.ErrShipmentTrackingSignature):
+			log.Printf("shipment tracking webhook signature verification failed: provider=%s", provider)
+			response.Unauthorized(c, "Webhook signature verification failed")
+		default:
+			response.HandleError(c, "Shipment tracking webhook processing failed", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"received": true, "updated": updated})
+}
@@ -0,0 +1,128 @@
+package user
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/utils"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ShortLinkHandler struct {
+	shortLinkService *service.ShortLinkService
+	orderService     *service.OrderService
+}
+
+func NewShortLinkHandler(shortLinkService *service.ShortLinkService, orderService *service.OrderService) *ShortLinkHandler {
+	return &ShortLinkHandler{shortLinkService: shortLinkService, orderService: orderService}
+}
+
+func (h *ShortLinkHandler) resolve(c *gin.Context) (*models.ShortLink, bool) {
+	link, err := h.shortLinkService.Resolve(c.Param("code"))
+	if err == nil {
+		return link, true
+	}
+	switch {
+	case errors.Is(err, service.ErrShortLinkNotFound):
+		c.String(http.StatusNotFound, "Link not found")
+	case errors.Is(err, service.ErrShortLinkExpired):
+		c.String(http.StatusGone, "Link has expired")
+	default:
+		log.Printf("short link resolve failed: code=%s err=%v", c.Param("code"), err)
+		c.String(http.StatusInternalServerError, "Internal server error")
+	}
+	return nil, false
+}
+
+// Redirect 公开 GET /s/:code — 记录点击并跳转到原始链接
+func (h *ShortLinkHandler) Redirect(c *gin.Context) {
+	link, ok := h.resolve(c)
+	if !ok {
+		return
+	}
+
+	linkID := link.ID
+	ip := utils.GetRealIP(c)
+	ua := c.GetHeader("User-Agent")
+	referer := c.GetHeader("Referer")
+	go func() {
+		if err := h.shortLinkService.RecordClick(linkID, ip, ua, referer); err != nil {
+			log.Printf("Warning: failed to record short link click: link=%d err=%v", linkID, err)
+		}
+	}()
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, link.TargetURL)
+}
+
+// QRCode 公开 GET /s/:code/qr — 短链二维码 PNG
+func (h *ShortLinkHandler) QRCode(c *gin.Context) {
+	link, ok := h.resolve(c)
+	if !ok {
+		return
+	}
+	size, _ := strconv.Atoi(c.Query("size"))
+	png, err := h.shortLinkService.QRCodePNG(link, size)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Failed to generate QR code")
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// GetOrderShortLink 用户获取自己订单的付款/收货表单短链
+func (h *ShortLinkHandler) GetOrderShortLink(c *gin.Context) {
+	userID, ok := middleware.RequireUserID(c)
+	if !ok {
+		return
+	}
+	order, err := h.orderService.GetOrderByNo(c.Param("order_no"))
+	if err != nil {
+		response.NotFound(c, "Order not found")
+		return
+	}
+	if order.UserID == nil || *order.UserID != userID {
+		response.Forbidden(c, "No permission to access this order")
+		return
+	}
+
+	var link *models.ShortLink
+	switch c.DefaultQuery("type", models.ShortLinkTypePayment) {
+	case models.ShortLinkTypePayment:
+		link, err = h.shortLinkService.ForPayment(order, &userID)
+	case models.ShortLinkTypeShippingForm:
+		if order.Status == models.OrderStatusDraft || order.Status == models.OrderStatusNeedResubmit {
+			// 与前端获取表单 Token 一致：按需生成或提前刷新
+			if _, _, err := h.orderService.GetOrRefreshFormToken(order); err != nil {
+				response.InternalError(c, "Failed to get form token")
+				return
+			}
+		}
+		link, err = h.shortLinkService.ForShippingForm(order, &userID)
+	default:
+		response.BadRequest(c, "Invalid short link type")
+		return
+	}
+	if err != nil {
+		if respondUserBizError(c, err) {
+			return
+		}
+		response.InternalServerError(c, "Failed to create short link", err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"code":        link.Code,
+		"target_type": link.TargetType,
+		"short_url":   h.shortLinkService.ShortURL(link),
+		"qr_code_url": h.shortLinkService.QRCodeURL(link),
+		"expires_at":  link.ExpiresAt,
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"auralogic/internal/config"
 	"auralogic/internal/middleware"
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
 	"auralogic/internal/pkg/response"
 	"auralogic/internal/pkg/ticketbiz"
 	"auralogic/internal/pkg/utils"
@@ -29,15 +30,21 @@ type TicketHandler struct {
 	db            *gorm.DB
 	emailService  *service.EmailService
 	pluginManager *service.PluginManagerService
+	attachments   *service.TicketAttachmentService
 }
 
 func NewTicketHandler(db *gorm.DB, emailService *service.EmailService, pluginManager *service.PluginManagerService) *TicketHandler {
-	return &TicketHandler{db: db, emailService: emailService, pluginManager: pluginManager}
+	return &TicketHandler{
+		db:            db,
+		emailService:  emailService,
+		pluginManager: pluginManager,
+		attachments:   service.NewTicketAttachmentService(db),
+	}
 }
 
 // generateTicketNo 生成工单号
 func (h *TicketHandler) generateTicketNo() string {
-	return fmt.Sprintf("TK%s%04d", time.Now().Format("20060102150405"), time.Now().UnixNano()%10000)
+	return service.GenerateTicketNo()
 }
 
 // CreateTicketRequest 创建工单请求
@@ -123,7 +130,7 @@ func (h *TicketHandler) CreateTicket(c *gin.Context) {
 
 	// 清理内容，防止XSS
 	sanitizedSubject := validator.SanitizeInput(req.Subject)
-	sanitizedContent := validator.SanitizeMarkdown(req.Content)
+	sanitizedContent := service.StripTicketAttachmentSignatures(validator.SanitizeMarkdown(req.Content))
 
 	// 检查内容长度限制
 	cfg := config.GetConfig()
@@ -250,6 +257,7 @@ func (h *TicketHandler) CreateTicket(c *gin.Context) {
 		response.InternalError(c, "Failed to load ticket")
 		return
 	}
+	ticket.Tags = nil
 
 	response.Success(c, ticket)
 
@@ -531,13 +539,14 @@ func (h *TicketHandler) ListTickets(c *gin.Context) {
 	}
 
 	if search != "" {
-		query = query.Where("subject ILIKE ? OR ticket_no ILIKE ?", "%"+search+"%", "%"+search+"%")
+		query = dbutil.WhereContainsFold(query, search, "subject", "ticket_no")
 	}
 
 	query.Count(&total)
 
 	offset := (page - 1) * limit
-	if err := query.Order("last_message_at DESC").Offset(offset).Limit(limit).Find(&tickets).Error; err != nil {
+	// 标签仅供客服内部使用，不返回给用户
+	if err := query.Omit("tags").Order("last_message_at DESC").Offset(offset).Limit(limit).Find(&tickets).Error; err != nil {
 		response.InternalError(c, "Query failed")
 		return
 	}
@@ -591,6 +600,8 @@ func (h *TicketHandler) GetTicket(c *gin.Context) {
 		}(h.buildTicketHookExecutionContext(c, userID, ticket.ID), hookPayload, userID, ticket.ID)
 	}
 
+	h.attachments.SignTicket(&ticket)
+	ticket.Tags = nil
 	response.Success(c, ticket)
 }
 
@@ -648,6 +659,7 @@ func (h *TicketHandler) GetTicketMessages(c *gin.Context) {
 		}(h.buildTicketHookExecutionContext(c, userID, ticket.ID), hookPayload, userID, ticket.ID)
 	}
 
+	h.attachments.SignMessages(uint(ticketID), messages)
 	response.Success(c, messages)
 }
 
@@ -737,7 +749,7 @@ func (h *TicketHandler) SendMessage(c *gin.Context) {
 	}
 
 	// 清理消息内容，防止XSS
-	sanitizedContent := validator.SanitizeMarkdown(req.Content)
+	sanitizedContent := service.StripTicketAttachmentSignatures(validator.SanitizeMarkdown(req.Content))
 
 	// 检查内容长度限制
 	cfg := config.GetConfig()
@@ -795,7 +807,7 @@ func (h *TicketHandler) SendMessage(c *gin.Context) {
 		}(hookExecCtx, afterPayload, userID, ticket.ID)
 	}
 
-	response.Success(c, message)
+	response.Success(c, h.attachments.SignedMessage(message))
 
 	// 发送用户回复通知邮件（通知管理员）
 	if h.emailService != nil {
@@ -1297,7 +1309,21 @@ func (h *TicketHandler) UploadFile(c *gin.Context) {
 		return
 	}
 
-	fileURL := fmt.Sprintf("%s/uploads/tickets/%s/%s", cfg.App.URL, dateDir, filename)
+	relPath := dateDir + "/" + filename
+	if err := h.attachments.Register(&models.TicketAttachment{
+		TicketID:     ticket.ID,
+		Path:         relPath,
+		UploaderType: "user",
+		UploaderID:   userID,
+		OriginalName: truncateString(file.Filename, 255),
+		Size:         file.Size,
+	}); err != nil {
+		_ = os.Remove(targetPath)
+		response.InternalError(c, "Failed to save file")
+		return
+	}
+
+	fileURL := fmt.Sprintf("%s/uploads/tickets/%s", cfg.App.URL, relPath)
 
 	if h.pluginManager != nil {
 		afterPayload := map[string]interface{}{
@@ -1323,9 +1349,10 @@ func (h *TicketHandler) UploadFile(c *gin.Context) {
 	}
 
 	response.Success(c, gin.H{
-		"url":      fileURL,
-		"filename": filename,
-		"size":     file.Size,
+		"url":         fileURL,
+		"preview_url": service.SignedTicketAttachmentURL(relPath, time.Now()),
+		"filename":    filename,
+		"size":        file.Size,
 	})
 }
 
@@ -0,0 +1,52 @@
+package user
+
+import (
+	"auralogic/internal/middleware"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WaitingRoomHandler struct {
+	waitingRoomService *service.WaitingRoomService
+}
+
+func NewWaitingRoomHandler(waitingRoomService *service.WaitingRoomService) *WaitingRoomHandler {
+	return &WaitingRoomHandler{waitingRoomService: waitingRoomService}
+}
+
+// Join 加入抢购等候室
+func (h *WaitingRoomHandler) Join(c *gin.Context) {
+	userID, userIDOK := middleware.RequireUserID(c)
+	if !userIDOK {
+		return
+	}
+
+	status, err := h.waitingRoomService.Join(userID)
+	if err != nil {
+		if respondUserBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to join waiting room")
+		return
+	}
+	response.Success(c, status)
+}
+
+// GetStatus 查询排队位置；放行后返回下单所需的放行凭证
+func (h *WaitingRoomHandler) GetStatus(c *gin.Context) {
+	userID, userIDOK := middleware.RequireUserID(c)
+	if !userIDOK {
+		return
+	}
+
+	status, err := h.waitingRoomService.Status(userID)
+	if err != nil {
+		if respondUserBizError(c, err) {
+			return
+		}
+		response.InternalError(c, "Failed to get waiting room status")
+		return
+	}
+	response.Success(c, status)
+}
@@ -13,12 +13,24 @@ var adminPermissionGroups = []AdminPermissionGroup{
 		Permissions: []string{
 			"order.view",
 			"order.view_privacy",
+			"order.view_email",
+			"order.view_phone",
+			"order.view_address",
+			"order.view_payment",
 			"order.edit",
 			"order.delete",
 			"order.status_update",
 			"order.refund",
 			"order.assign_tracking",
 			"order.request_resubmit",
+			"order.automation",
+		},
+	},
+	{
+		Name: "ReturnPermission",
+		Permissions: []string{
+			"return.view",
+			"return.manage",
 		},
 	},
 	{
@@ -51,6 +63,8 @@ var adminPermissionGroups = []AdminPermissionGroup{
 			"admin.edit",
 			"admin.delete",
 			"admin.permission",
+			"admin.activity",
+			"admin.approve",
 		},
 	},
 	{
@@ -59,6 +73,15 @@ var adminPermissionGroups = []AdminPermissionGroup{
 			"system.config",
 			"system.logs",
 			"api.manage",
+			"security.view",
+			"security.manage",
+		},
+	},
+	{
+		Name: "StorePermission",
+		Permissions: []string{
+			"store.view",
+			"store.edit",
 		},
 	},
 	{
@@ -116,6 +139,7 @@ var adminPermissionGroups = []AdminPermissionGroup{
 			"ticket.view",
 			"ticket.reply",
 			"ticket.status_update",
+			"ticket.automation",
 		},
 	},
 	{
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// API Key 用量明细保留时长
+const apiKeyUsageRetention = 30 * 24 * time.Hour
+
+// apiKeyBucket 单个 API Key 的令牌桶，状态仅保存在当前进程内
+type apiKeyBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	last     time.Time
+	rate     int
+	capacity int
+}
+
+// apiKeyBuckets key: API Key ID -> *apiKeyBucket
+var apiKeyBuckets sync.Map
+
+// apiKeyUsagePrunedAt key: API Key ID -> 上次清理用量明细的小时
+var apiKeyUsagePrunedAt sync.Map
+
+// APIKeyRateLimitState 令牌桶的当前状态
+type APIKeyRateLimitState struct {
+	Limit      int           // 每小时持续速率
+	Burst      int           // 令牌桶容量
+	Remaining  int           // 当前可立即发出的请求数
+	RetryAfter time.Duration // 被拒绝时距离下一个令牌的时间
+}
+
+// refillLocked 按持续速率补充令牌；限流配置变更时按新容量重建
+func (b *apiKeyBucket) refillLocked(key *models.APIKey, now time.Time) {
+	capacity := key.EffectiveBurstLimit()
+	if b.last.IsZero() || b.rate != key.RateLimit || b.capacity != capacity {
+		if b.last.IsZero() || b.tokens > float64(capacity) {
+			b.tokens = float64(capacity)
+		}
+		b.rate = key.RateLimit
+		b.capacity = capacity
+		b.last = now
+		return
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(capacity), b.tokens+elapsed.Hours()*float64(key.RateLimit))
+		b.last = now
+	}
+}
+
+func loadAPIKeyBucket(keyID uint) *apiKeyBucket {
+	bucket, _ := apiKeyBuckets.LoadOrStore(keyID, &apiKeyBucket{})
+	return bucket.(*apiKeyBucket)
+}
+
+// takeAPIKeyToken 尝试消耗一个令牌；RateLimit <= 0 的 Key 不限流
+func takeAPIKeyToken(key *models.APIKey, now time.Time) (bool, APIKeyRateLimitState) {
+	if key.RateLimit <= 0 {
+		return true, APIKeyRateLimitState{}
+	}
+	bucket := loadAPIKeyBucket(key.ID)
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	bucket.refillLocked(key, now)
+	state := APIKeyRateLimitState{Limit: key.RateLimit, Burst: bucket.capacity}
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / float64(key.RateLimit) * float64(time.Hour))
+		state.RetryAfter = wait
+		return false, state
+	}
+	bucket.tokens--
+	state.Remaining = int(bucket.tokens)
+	return true, state
+}
+
+// PeekAPIKeyRateLimit 返回令牌桶当前状态而不消耗令牌，供用量面板展示
+func PeekAPIKeyRateLimit(key *models.APIKey) APIKeyRateLimitState {
+	if key == nil || key.RateLimit <= 0 {
+		return APIKeyRateLimitState{}
+	}
+	bucket := loadAPIKeyBucket(key.ID)
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	bucket.refillLocked(key, time.Now())
+	return APIKeyRateLimitState{Limit: key.RateLimit, Burst: bucket.capacity, Remaining: int(bucket.tokens)}
+}
+
+// enforceAPIKeyRateLimit 执行令牌桶限流并写入限流响应头，被拒绝时返回 false
+func enforceAPIKeyRateLimit(c *gin.Context, db *gorm.DB, key *models.APIKey) bool {
+	// 同一请求多次经过认证中间件时只计一次
+	if c.GetBool("api_key_rate_limited") {
+		return true
+	}
+	c.Set("api_key_rate_limited", true)
+
+	allowed, state := takeAPIKeyToken(key, time.Now())
+	go recordAPIKeyUsage(db, key.ID, !allowed)
+	if state.Limit <= 0 {
+		return true
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(state.Limit))
+	c.Header("X-RateLimit-Burst", strconv.Itoa(state.Burst))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(state.Remaining))
+	if allowed {
+		return true
+	}
+	retryAfter := int(math.Ceil(state.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	response.Error(c, 429, response.CodeTooManyRequests, "API key rate limit exceeded, please try again later")
+	c.Abort()
+	return false
+}
+
+// recordAPIKeyUsage 累加当前小时的调用量，并每小时清理一次过期明细
+func recordAPIKeyUsage(db *gorm.DB, keyID uint, throttled bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	db = db.WithContext(ctx)
+
+	hour := models.NowFunc().Truncate(time.Hour)
+	usage := models.APIKeyUsage{APIKeyID: keyID, Hour: hour, RequestCount: 1}
+	column := "request_count"
+	// 被拒绝的请求只计入 throttled_count
+	if throttled {
+		usage.RequestCount = 0
+		usage.ThrottledCount = 1
+		column = "throttled_count"
+	}
+	if err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "api_key_id"}, {Name: "hour"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			column:       gorm.Expr(column + " + 1"),
+			"updated_at": models.NowFunc(),
+		}),
+	}).Create(&usage).Error; err != nil {
+		log.Printf("record api key usage failed: api_key=%d err=%v", keyID, err)
+		return
+	}
+
+	if last, ok := apiKeyUsagePrunedAt.Load(keyID); ok && last.(time.Time).Equal(hour) {
+		return
+	}
+	apiKeyUsagePrunedAt.Store(keyID, hour)
+	if err := db.Where("api_key_id = ? AND hour < ?", keyID, hour.Add(-apiKeyUsageRetention)).
+		Delete(&models.APIKeyUsage{}).Error; err != nil {
+		log.Printf("prune api key usage failed: api_key=%d err=%v", keyID, err)
+	}
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAPIKeyTokenBucketAllowsBurstThenRefills(t *testing.T) {
+	key := &models.APIKey{ID: 9001, RateLimit: 3600, BurstLimit: 3}
+	t.Cleanup(func() { apiKeyBuckets.Delete(key.ID) })
+
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		allowed, state := takeAPIKeyToken(key, now)
+		if !allowed || state.Remaining != 2-i || state.Burst != 3 {
+			t.Fatalf("burst request %d: allowed=%v state=%+v", i, allowed, state)
+		}
+	}
+	allowed, state := takeAPIKeyToken(key, now)
+	if allowed || state.RetryAfter != time.Second {
+		t.Fatalf("expected bucket to be empty with 1s retry, got allowed=%v state=%+v", allowed, state)
+	}
+
+	// 3600/小时即每秒补充一个令牌
+	if allowed, _ := takeAPIKeyToken(key, now.Add(time.Second)); !allowed {
+		t.Fatal("expected a token after one second")
+	}
+	if allowed, state := takeAPIKeyToken(key, now.Add(time.Hour)); !allowed || state.Remaining != 2 {
+		t.Fatalf("refill should be capped at burst, got allowed=%v state=%+v", allowed, state)
+	}
+
+	// 调低容量时已有令牌按新容量截断
+	key.BurstLimit = 1
+	if state := PeekAPIKeyRateLimit(key); state.Remaining != 1 || state.Burst != 1 {
+		t.Fatalf("expected tokens clamped to new burst, got %+v", state)
+	}
+	if allowed, _ := takeAPIKeyToken(&models.APIKey{ID: 9002}, now); !allowed {
+		t.Fatal("keys without rate limit should never be throttled")
+	}
+	if burst := (&models.APIKey{RateLimit: 1000}).EffectiveBurstLimit(); burst != 16 {
+		t.Fatalf("default burst should be one minute of sustained rate, got %d", burst)
+	}
+}
+
+func TestEnforceAPIKeyRateLimitSetsHeadersAndRecordsUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "api-key-usage.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// 请求中的用量记录是异步写入的，串行化连接避免 sqlite 写锁冲突
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.SetMaxOpenConns(1)
+	}
+	if err := db.AutoMigrate(&models.APIKeyUsage{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	key := &models.APIKey{ID: 9003, RateLimit: 60, BurstLimit: 1}
+	t.Cleanup(func() { apiKeyBuckets.Delete(key.ID) })
+
+	router := gin.New()
+	router.GET("/orders", func(c *gin.Context) {
+		if !enforceAPIKeyRateLimit(c, db, key) {
+			return
+		}
+		// 再次经过认证中间件不应重复扣减
+		if !enforceAPIKeyRateLimit(c, db, key) {
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	if first.Code != http.StatusOK || first.Header().Get("X-RateLimit-Limit") != "60" ||
+		first.Header().Get("X-RateLimit-Burst") != "1" || first.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("unexpected first response: %d %v", first.Code, first.Header())
+	}
+	second := httptest.NewRecorder()
+	router.ServeHTTP(second, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	if second.Code != http.StatusTooManyRequests || second.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected throttled response, got %d %v", second.Code, second.Header())
+	}
+
+	recordAPIKeyUsage(db, key.ID, false)
+	recordAPIKeyUsage(db, key.ID, true)
+	deadline := time.Now().Add(2 * time.Second)
+	var usage models.APIKeyUsage
+	for {
+		if err := db.Where("api_key_id = ?", key.ID).First(&usage).Error; err == nil && usage.RequestCount == 2 && usage.ThrottledCount == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 requests and 2 throttled in the current hour, got %+v", usage)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
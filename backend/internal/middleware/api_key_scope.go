@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyScopeAliases 面向集成方的精简 scope，认证时展开为后台权限
+var APIKeyScopeAliases = map[string][]string{
+	"orders:read": {"order.view"},
+	"orders:write": {
+		"order.view",
+		"order.edit",
+		"order.status_update",
+		"order.assign_tracking",
+		"order.request_resubmit",
+	},
+	"stock:read": {"product.view"},
+}
+
+// APIKeyScopeAliasNames 返回所有 scope 别名（已排序）
+func APIKeyScopeAliasNames() []string {
+	names := make([]string, 0, len(APIKeyScopeAliases))
+	for name := range APIKeyScopeAliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExpandAPIKeyScopes 将 scope 别名展开为后台权限，普通权限原样保留
+func ExpandAPIKeyScopes(scopes []string) []string {
+	expanded := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if permissions, ok := APIKeyScopeAliases[scope]; ok {
+			expanded = append(expanded, permissions...)
+			continue
+		}
+		expanded = append(expanded, scope)
+	}
+	return uniqueNormalizedPermissions(expanded)
+}
+
+// NormalizeAPIKeyScopes 去重并校验 scope，返回规范化结果和无法识别的 scope
+func NormalizeAPIKeyScopes(scopes []string) ([]string, []string) {
+	registered := buildPermissionSet(RegisteredAdminPermissions())
+	normalized := uniqueNormalizedPermissions(scopes)
+	unknown := make([]string, 0)
+	for _, scope := range normalized {
+		if _, ok := APIKeyScopeAliases[scope]; ok {
+			continue
+		}
+		if _, ok := registered[scope]; ok {
+			continue
+		}
+		unknown = append(unknown, scope)
+	}
+	return normalized, unknown
+}
+
+// CanGrantAPIKeyScopes 当前管理员是否拥有 scope 展开后的全部权限（不能创建越权的 API Key）
+func CanGrantAPIKeyScopes(c *gin.Context, scopes []string) bool {
+	expanded := ExpandAPIKeyScopes(scopes)
+	if len(expanded) == 0 {
+		return true
+	}
+	return HasAllPermissions(c, expanded...)
+}
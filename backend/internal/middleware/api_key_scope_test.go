@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"auralogic/internal/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestExpandAndNormalizeAPIKeyScopes(t *testing.T) {
+	expanded := ExpandAPIKeyScopes([]string{"orders:read", " stock:read ", "order.view", "serial.view"})
+	if !reflect.DeepEqual(expanded, []string{"order.view", "product.view", "serial.view"}) {
+		t.Fatalf("unexpected expanded scopes: %v", expanded)
+	}
+
+	normalized, unknown := NormalizeAPIKeyScopes([]string{"orders:write", "orders:write", "order.refund", "orders:delete", ""})
+	if !reflect.DeepEqual(normalized, []string{"orders:write", "order.refund", "orders:delete"}) {
+		t.Fatalf("unexpected normalized scopes: %v", normalized)
+	}
+	if !reflect.DeepEqual(unknown, []string{"orders:delete"}) {
+		t.Fatalf("expected unknown scope to be reported, got %v", unknown)
+	}
+
+	// 别名展开后的权限参与 API Key 权限校验
+	ctx, _ := gin.CreateTestContext(nil)
+	ctx.Set("auth_type", "api_key")
+	ctx.Set("api_scopes", ExpandAPIKeyScopes([]string{"orders:read"}))
+	if !apiKeyHasPermissions(ctx, []string{"order.view"}, permissionMatchAll) {
+		t.Fatal("expected orders:read to grant order.view")
+	}
+	if apiKeyHasPermissions(ctx, []string{"order.edit"}, permissionMatchAny) {
+		t.Fatal("expected orders:read to not grant order.edit")
+	}
+	if !CanGrantAPIKeyScopes(ctx, []string{"orders:read"}) || CanGrantAPIKeyScopes(ctx, []string{"orders:write"}) {
+		t.Fatal("expected api key to only grant scopes it holds")
+	}
+}
+
+func TestAPIKeyAllowsIP(t *testing.T) {
+	allowed, err := models.NormalizeAPIKeyAllowedIPs([]string{" 203.0.113.10 ", "198.51.100.7/24", "203.0.113.10", ""})
+	if err != nil {
+		t.Fatalf("normalize allowed ips: %v", err)
+	}
+	if !reflect.DeepEqual(allowed, []string{"203.0.113.10", "198.51.100.0/24"}) {
+		t.Fatalf("unexpected normalized allowlist: %v", allowed)
+	}
+	if _, err := models.NormalizeAPIKeyAllowedIPs([]string{"10.0.0.0/33"}); err == nil {
+		t.Fatal("expected invalid CIDR to be rejected")
+	}
+
+	key := &models.APIKey{AllowedIPs: allowed}
+	for ip, want := range map[string]bool{
+		"203.0.113.10":  true,
+		"198.51.100.42": true,
+		"203.0.113.11":  false,
+		"":              false,
+	} {
+		if got := key.AllowsIP(ip); got != want {
+			t.Fatalf("AllowsIP(%q) = %v, want %v", ip, got, want)
+		}
+	}
+	if !(&models.APIKey{}).AllowsIP("192.0.2.1") {
+		t.Fatal("expected key without allowlist to allow any ip")
+	}
+}
+
+func TestTrackAPIKeyUsageAlertsOnNewIP(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "api-key.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.APIKey{}, &models.SecurityEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	key := models.APIKey{KeyName: "erp", APIKey: "ak_test_usage", APISecretHash: "x", IsActive: true, CreatedBy: 1}
+	if err := db.Create(&key).Error; err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+
+	reload := func() models.APIKey {
+		var current models.APIKey
+		if err := db.First(&current, key.ID).Error; err != nil {
+			t.Fatalf("reload api key: %v", err)
+		}
+		return current
+	}
+	countEvents := func() int64 {
+		var count int64
+		db.Model(&models.SecurityEvent{}).Where("event_type = ?", models.SecurityEventAPIKeyNewIP).Count(&count)
+		return count
+	}
+
+	// 首次使用只记录来源
+	trackAPIKeyUsage(db, reload(), "192.0.2.1", "erp-client")
+	current := reload()
+	if current.LastUsedAt == nil || current.LastUsedIP != "192.0.2.1" || !reflect.DeepEqual(current.KnownIPs, []string{"192.0.2.1"}) {
+		t.Fatalf("unexpected usage tracking: %+v", current)
+	}
+	if countEvents() != 0 {
+		t.Fatal("expected no alert on first use")
+	}
+
+	trackAPIKeyUsage(db, reload(), "192.0.2.1", "erp-client")
+	trackAPIKeyUsage(db, reload(), "198.51.100.9", "curl")
+	if countEvents() != 1 {
+		t.Fatalf("expected one new ip alert, got %d", countEvents())
+	}
+	var event models.SecurityEvent
+	if err := db.Where("event_type = ?", models.SecurityEventAPIKeyNewIP).First(&event).Error; err != nil {
+		t.Fatalf("load event: %v", err)
+	}
+	if !event.RequiresReview || event.IPAddress != "198.51.100.9" || event.Details["key_name"] != "erp" {
+		t.Fatalf("unexpected security event: %+v", event)
+	}
+	if current := reload(); len(current.KnownIPs) != 2 || current.LastUsedIP != "198.51.100.9" {
+		t.Fatalf("expected new ip to be remembered, got %+v", current)
+	}
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	apiKeyKnownIPLimit      = 20
+	apiKeyAlertThrottleTime = 10 * time.Minute
+)
+
+// apiKeyAlertSent 同一 Key + IP 的告警节流，避免高频调用刷屏
+var apiKeyAlertSent sync.Map
+
+func apiKeyAlertAllowed(eventType string, keyID uint, ip string, now time.Time) bool {
+	key := fmt.Sprintf("%s|%d|%s", eventType, keyID, ip)
+	if last, ok := apiKeyAlertSent.Load(key); ok && now.Sub(last.(time.Time)) < apiKeyAlertThrottleTime {
+		return false
+	}
+	apiKeyAlertSent.Store(key, now)
+	return true
+}
+
+func recordAPIKeySecurityEvent(db *gorm.DB, eventType string, key *models.APIKey, ip, userAgent string) {
+	now := models.NowFunc()
+	if !apiKeyAlertAllowed(eventType, key.ID, ip, now) {
+		return
+	}
+	userID := key.CreatedBy
+	event := &models.SecurityEvent{
+		EventType:      eventType,
+		Severity:       models.SecuritySeverityWarning,
+		UserID:         &userID,
+		IPAddress:      ip,
+		UserAgent:      userAgent,
+		RequiresReview: true,
+		Details: map[string]interface{}{
+			"api_key_id": key.ID,
+			"key_name":   key.KeyName,
+			"api_key":    key.APIKey,
+		},
+	}
+	if err := db.Create(event).Error; err != nil {
+		log.Printf("record api key security event failed: type=%s api_key=%d ip=%s err=%v", eventType, key.ID, ip, err)
+	}
+}
+
+// trackAPIKeyUsage 更新最后使用时间/IP，并在出现未见过的来源 IP 时生成待审核的安全事件
+func trackAPIKeyUsage(db *gorm.DB, key models.APIKey, ip, userAgent string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	db = db.WithContext(ctx)
+
+	now := models.NowFunc()
+	knownIPs := key.KnownIPs
+	isNewIP := ip != ""
+	for _, known := range knownIPs {
+		if known == ip {
+			isNewIP = false
+			break
+		}
+	}
+	if isNewIP {
+		// 首次使用只记录来源；配置了白名单的 Key 来源已受限，无需告警
+		if len(knownIPs) > 0 && len(key.AllowedIPs) == 0 {
+			recordAPIKeySecurityEvent(db, models.SecurityEventAPIKeyNewIP, &key, ip, userAgent)
+		}
+		knownIPs = append(knownIPs, ip)
+		if len(knownIPs) > apiKeyKnownIPLimit {
+			knownIPs = knownIPs[len(knownIPs)-apiKeyKnownIPLimit:]
+		}
+	}
+
+	if err := db.Model(&models.APIKey{}).Where("id = ?", key.ID).
+		Select("last_used_at", "last_used_ip", "known_ips").
+		Updates(&models.APIKey{LastUsedAt: &now, LastUsedIP: ip, KnownIPs: knownIPs}).Error; err != nil {
+		log.Printf("update api key usage failed: api_key=%d err=%v", key.ID, err)
+	}
+}
@@ -1,16 +1,15 @@
 package middleware
 
 import (
-	"context"
 	"strconv"
 	"strings"
-	"time"
 
 	"auralogic/internal/config"
 	"auralogic/internal/database"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/jwt"
 	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,8 +18,12 @@ const websocketBearerTokenProtocolPrefix = "auralogic.auth.bearer."
 // AuthMiddleware 双认证中间件：优先JWT，回退API Key
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 优先尝试 JWT Bearer Token
-		if tokenString := extractBearerToken(c); tokenString != "" {
+		// 优先尝试 JWT Bearer Token，其次 Cookie 会话
+		if tokenString, fromCookie := extractAuthToken(c); tokenString != "" {
+			if fromCookie && !verifyCSRF(c) {
+				abortCSRF(c)
+				return
+			}
 			claims, err := jwt.ParseToken(tokenString)
 			if err != nil {
 				response.Error(c, 401, response.CodeTokenInvalid, "Invalid authentication token")
@@ -30,7 +33,8 @@ func AuthMiddleware() gin.HandlerFunc {
 
 			db := database.GetDB()
 			var user models.User
-			if err := db.Select("id", "email", "role", "is_active").First(&user, claims.UserID).Error; err != nil {
+			if err := db.Select("id", "email", "role", "is_active", "email_verified", "email_verification_exempt", "email_changed_at", "created_at").
+				First(&user, claims.UserID).Error; err != nil {
 				response.Unauthorized(c, "Invalid authentication token")
 				c.Abort()
 				return
@@ -40,8 +44,12 @@ func AuthMiddleware() gin.HandlerFunc {
 				c.Abort()
 				return
 			}
+			if !enforceEmailVerification(c, &user) {
+				return
+			}
 
 			c.Set("auth_type", "jwt")
+			c.Set("session_cookie", fromCookie)
 			c.Set("user_id", user.ID)
 			c.Set("user_email", user.Email)
 			c.Set("user_role", user.Role)
@@ -73,20 +81,27 @@ func AuthMiddleware() gin.HandlerFunc {
 				return
 			}
 
+			clientIP := utils.GetRealIP(c)
+			if !key.AllowsIP(clientIP) {
+				go recordAPIKeySecurityEvent(db, models.SecurityEventAPIKeyIPRejected, &key, clientIP, c.Request.UserAgent())
+				response.Error(c, 403, response.CodeAPIKeyInvalid, "API key is not allowed from this IP address")
+				c.Abort()
+				return
+			}
+
+			if !enforceAPIKeyRateLimit(c, db, &key) {
+				return
+			}
+
 			c.Set("auth_type", "api_key")
 			c.Set("user_id", key.CreatedBy)
 			c.Set("api_key_id", key.ID)
 			c.Set("api_key", apiKey)
-			c.Set("api_scopes", key.Scopes)
+			c.Set("api_scopes", ExpandAPIKeyScopes(key.Scopes))
 			c.Set("api_platform", key.Platform)
 
-			// 异步更新最后使用时间
-			go func() {
-				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-				defer cancel()
-				now := models.NowFunc()
-				db.WithContext(ctx).Model(&key).Update("last_used_at", now)
-			}()
+			// 异步更新最后使用时间和来源 IP
+			go trackAPIKeyUsage(db, key, clientIP, c.Request.UserAgent())
 
 			c.Next()
 			return
@@ -106,7 +121,7 @@ func IsAPIKeyAuth(c *gin.Context) bool {
 // OptionalAuthMiddleware 可选的认证中间件
 func OptionalAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if tokenString := extractBearerToken(c); tokenString != "" {
+		if tokenString, fromCookie := extractAuthToken(c); tokenString != "" && (!fromCookie || verifyCSRF(c)) {
 			claims, err := jwt.ParseToken(tokenString)
 			if err == nil {
 				db := database.GetDB()
@@ -124,6 +139,17 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// extractAuthToken 请求令牌：Bearer Token 优先，未携带时读取会话 Cookie
+func extractAuthToken(c *gin.Context) (string, bool) {
+	if token := extractBearerToken(c); token != "" {
+		return token, false
+	}
+	if token := extractSessionCookieToken(c); token != "" {
+		return token, true
+	}
+	return "", false
+}
+
 func extractBearerToken(c *gin.Context) string {
 	if c == nil {
 		return ""
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"auralogic/internal/pkg/cache"
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// 单个请求占用并发槽位的最长租约，防止进程异常退出后槽位无法释放
+const concurrencyLimitLease = time.Minute
+
+var (
+	concurrencyLimitAcquireScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[2])
+local expires = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now)
+if redis.call('ZCARD', key) >= tonumber(ARGV[4]) then
+  return 0
+end
+redis.call('ZADD', key, expires, ARGV[1])
+redis.call('PEXPIRE', key, expires - now)
+return 1
+`)
+	concurrencyLimitMemory = struct {
+		sync.Mutex
+		inFlight map[string]int
+	}{inFlight: make(map[string]int)}
+)
+
+// ConcurrencyLimitResolver 按请求解析每个用户、每个 IP 允许的最大并发请求数，0 表示不限制
+type ConcurrencyLimitResolver func(c *gin.Context) (perUser int, perIP int)
+
+// ConcurrencyLimitMiddleware 限制同一用户/IP 在某条路由上同时处理中的请求数
+// 与限流不同，请求结束后槽位立即释放；多实例部署时通过 Redis 共享计数，Redis 不可用时退化为进程内计数
+func ConcurrencyLimitMiddleware(route string, resolve ConcurrencyLimitResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		perUser, perIP := 0, 0
+		if resolve != nil {
+			perUser, perIP = resolve(c)
+		}
+
+		var releases []func()
+		releaseAll := func() {
+			for _, release := range releases {
+				release()
+			}
+		}
+
+		if userID, exists := GetUserID(c); exists && perUser > 0 {
+			release, ok := acquireConcurrencySlot(fmt.Sprintf("concurrency:%s:user:%d", route, userID), perUser)
+			if !ok {
+				abortConcurrencyLimited(c)
+				return
+			}
+			releases = append(releases, release)
+		}
+		if perIP > 0 {
+			release, ok := acquireConcurrencySlot(fmt.Sprintf("concurrency:%s:ip:%s", route, utils.GetRealIP(c)), perIP)
+			if !ok {
+				releaseAll()
+				abortConcurrencyLimited(c)
+				return
+			}
+			releases = append(releases, release)
+		}
+		defer releaseAll()
+
+		c.Next()
+	}
+}
+
+func abortConcurrencyLimited(c *gin.Context) {
+	c.Header("Retry-After", "1")
+	response.Error(c, 429, response.CodeTooManyRequests, "Too many concurrent requests, please wait for the previous request to finish")
+	c.Abort()
+}
+
+func acquireConcurrencySlot(key string, limit int) (func(), bool) {
+	if cache.RedisClient != nil {
+		token := uuid.NewString()
+		now := time.Now()
+		granted, err := concurrencyLimitAcquireScript.Run(
+			cache.RedisClient.Context(),
+			cache.RedisClient,
+			[]string{key},
+			token,
+			now.UnixMilli(),
+			now.Add(concurrencyLimitLease).UnixMilli(),
+			limit,
+		).Int()
+		if err == nil {
+			if granted != 1 {
+				return nil, false
+			}
+			return func() {
+				if releaseErr := cache.RedisClient.ZRem(cache.RedisClient.Context(), key, token).Err(); releaseErr != nil {
+					log.Printf("concurrency limit redis release failed: key=%s err=%v", key, releaseErr)
+				}
+			}, true
+		}
+		log.Printf("concurrency limit redis acquire failed, fallback to memory: key=%s err=%v", key, err)
+	}
+
+	concurrencyLimitMemory.Lock()
+	defer concurrencyLimitMemory.Unlock()
+	if concurrencyLimitMemory.inFlight[key] >= limit {
+		return nil, false
+	}
+	concurrencyLimitMemory.inFlight[key]++
+	return func() {
+		concurrencyLimitMemory.Lock()
+		defer concurrencyLimitMemory.Unlock()
+		if concurrencyLimitMemory.inFlight[key] <= 1 {
+			delete(concurrencyLimitMemory.inFlight, key)
+			return
+		}
+		concurrencyLimitMemory.inFlight[key]--
+	}, true
+}
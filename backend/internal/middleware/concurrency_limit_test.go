@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"auralogic/internal/pkg/cache"
+	"github.com/gin-gonic/gin"
+)
+
+func TestConcurrencyLimitMiddlewareLimitsInFlightPerUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	previousClient := cache.RedisClient
+	cache.RedisClient = nil
+	defer func() { cache.RedisClient = previousClient }()
+
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	router := gin.New()
+	router.POST("/orders",
+		func(c *gin.Context) {
+			c.Set("user_id", uint(7))
+			c.Next()
+		},
+		ConcurrencyLimitMiddleware("test_orders", func(*gin.Context) (int, int) { return 1, 0 }),
+		func(c *gin.Context) {
+			entered <- struct{}{}
+			<-unblock
+			c.Status(http.StatusOK)
+		},
+	)
+
+	var wg sync.WaitGroup
+	first := httptest.NewRecorder()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		router.ServeHTTP(first, httptest.NewRequest(http.MethodPost, "/orders", nil))
+	}()
+	<-entered
+
+	second := httptest.NewRecorder()
+	router.ServeHTTP(second, httptest.NewRequest(http.MethodPost, "/orders", nil))
+	if second.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected concurrent request to be rejected, got %d", second.Code)
+	}
+
+	close(unblock)
+	wg.Wait()
+	if first.Code != http.StatusOK {
+		t.Fatalf("expected first request to succeed, got %d", first.Code)
+	}
+
+	// 前一个请求结束后槽位释放
+	third := httptest.NewRecorder()
+	go func() { <-entered }()
+	router.ServeHTTP(third, httptest.NewRequest(http.MethodPost, "/orders", nil))
+	if third.Code != http.StatusOK {
+		t.Fatalf("expected request after release to succeed, got %d", third.Code)
+	}
+}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"auralogic/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// CSPReportPath 内置的 CSP 违规上报地址
+const CSPReportPath = "/api/csp-report"
+
+const cspNonceContextKey = "csp_nonce"
+
+// legacyContentSecurityPolicy 未启用 CSP 管理时的默认响应头
+const legacyContentSecurityPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval'; style-src 'self' 'unsafe-inline'; img-src 'self' data: https:; font-src 'self' data:; connect-src 'self' https:; frame-ancestors 'none'"
+
+var cspRouteRegexCache sync.Map
+
+// CSPNonce 当前请求的 CSP nonce，未启用 nonce 时为空
+func CSPNonce(c *gin.Context) string {
+	return c.GetString(cspNonceContextKey)
+}
+
+func newCSPNonce() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// setContentSecurityPolicy 按配置写入 CSP 响应头，需要时生成本次请求的 nonce
+func setContentSecurityPolicy(c *gin.Context) {
+	cfg := config.GetConfig()
+	if cfg == nil || !cfg.Security.CSP.Enabled {
+		c.Header("Content-Security-Policy", legacyContentSecurityPolicy)
+		return
+	}
+	csp := &cfg.Security.CSP
+	nonce := ""
+	if csp.Nonce {
+		nonce = newCSPNonce()
+		c.Set(cspNonceContextKey, nonce)
+	}
+	headerName := "Content-Security-Policy"
+	if csp.ReportOnly {
+		headerName = "Content-Security-Policy-Report-Only"
+	}
+	c.Header(headerName, BuildContentSecurityPolicy(csp, c.Request.URL.Path, nonce))
+}
+
+// BuildContentSecurityPolicy 生成指定路径的策略：默认指令 → 路由覆盖 → nonce → report-uri
+func BuildContentSecurityPolicy(csp *config.CSPConfig, path, nonce string) string {
+	directives := make(map[string]string, len(csp.Directives))
+	for name, value := range csp.Directives {
+		directives[name] = strings.TrimSpace(value)
+	}
+	if route := matchCSPRoute(csp.Routes, path); route != nil {
+		for name, value := range route.Directives {
+			if value = strings.TrimSpace(value); value == "" {
+				delete(directives, name)
+			} else {
+				directives[name] = value
+			}
+		}
+	}
+
+	if nonce != "" {
+		nonceSource := "'nonce-" + nonce + "'"
+		directives["script-src"] = appendCSPSource(cspDirectiveOrDefault(directives, "script-src"), nonceSource)
+		// 允许 'unsafe-inline' 时不追加 nonce，否则浏览器会忽略 'unsafe-inline' 导致内联 style 属性失效
+		if styleSrc := cspDirectiveOrDefault(directives, "style-src"); !strings.Contains(styleSrc, "'unsafe-inline'") {
+			directives["style-src"] = appendCSPSource(styleSrc, nonceSource)
+		}
+	}
+
+	reportURI := csp.ReportURI
+	if reportURI == "" && csp.CollectReports {
+		reportURI = CSPReportPath
+	}
+	if reportURI != "" {
+		directives["report-uri"] = reportURI
+	}
+
+	names := make([]string, 0, len(directives))
+	for name := range directives {
+		if name != "default-src" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := directives["default-src"]; ok {
+		names = append([]string{"default-src"}, names...)
+	}
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		if value := directives[name]; value != "" {
+			parts = append(parts, name+" "+value)
+		} else {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// cspDirectiveOrDefault 指令缺失时浏览器回退到 default-src
+func cspDirectiveOrDefault(directives map[string]string, name string) string {
+	if value, ok := directives[name]; ok {
+		return value
+	}
+	return directives["default-src"]
+}
+
+func appendCSPSource(value, source string) string {
+	// 'none' 不能与其他来源并存
+	value = strings.TrimSpace(strings.ReplaceAll(value, "'none'", ""))
+	if value == "" {
+		return source
+	}
+	return value + " " + source
+}
+
+func matchCSPRoute(routes []config.CSPRoutePolicy, path string) *config.CSPRoutePolicy {
+	for i := range routes {
+		route := &routes[i]
+		switch route.MatchType {
+		case "exact":
+			if path == route.Pattern {
+				return route
+			}
+		case "regex":
+			if re := cspRouteRegex(route.Pattern); re != nil && re.MatchString(path) {
+				return route
+			}
+		default:
+			if strings.HasPrefix(path, route.Pattern) {
+				return route
+			}
+		}
+	}
+	return nil
+}
+
+func cspRouteRegex(pattern string) *regexp.Regexp {
+	if cached, ok := cspRouteRegexCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	cspRouteRegexCache.Store(pattern, re)
+	return re
+}
+
+var cspInlineTagPattern = regexp.MustCompile(`(?i)<(script|style)(\s[^>]*)?>`)
+
+// ApplyCSPNonce 为服务端渲染 HTML 中的 <script>/<style> 标签补充 nonce 属性
+func ApplyCSPNonce(html, nonce string) string {
+	if nonce == "" {
+		return html
+	}
+	return cspInlineTagPattern.ReplaceAllStringFunc(html, func(tag string) string {
+		if strings.Contains(strings.ToLower(tag), "nonce=") {
+			return tag
+		}
+		nameEnd := strings.IndexAny(tag, " \t\r\n>")
+		return tag[:nameEnd] + ` nonce="` + nonce + `"` + tag[nameEnd:]
+	})
+}
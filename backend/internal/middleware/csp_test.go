@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+
+	"auralogic/internal/config"
+)
+
+func TestBuildContentSecurityPolicyRoutesAndNonce(t *testing.T) {
+	csp := &config.CSPConfig{
+		Enabled: true,
+		Nonce:   true,
+		Directives: map[string]string{
+			"default-src":     "'self'",
+			"script-src":      "'self'",
+			"style-src":       "'self' 'unsafe-inline'",
+			"frame-ancestors": "'none'",
+		},
+		Routes: []config.CSPRoutePolicy{
+			{Pattern: "/embed", MatchType: "prefix", Directives: map[string]string{"frame-ancestors": ""}},
+			{Pattern: `^/api/`, MatchType: "regex", Directives: map[string]string{"script-src": "'none'"}},
+		},
+		CollectReports: true,
+	}
+
+	policy := BuildContentSecurityPolicy(csp, "/", "abc")
+	if !strings.HasPrefix(policy, "default-src 'self'; ") {
+		t.Fatalf("expected default-src first, got %q", policy)
+	}
+	for _, want := range []string{"script-src 'self' 'nonce-abc'", "style-src 'self' 'unsafe-inline'", "frame-ancestors 'none'", "report-uri /api/csp-report"} {
+		if !strings.Contains(policy, want) {
+			t.Fatalf("expected %q in %q", want, policy)
+		}
+	}
+
+	// 路由覆盖：空值移除指令，'none' 追加 nonce 时被替换
+	if policy := BuildContentSecurityPolicy(csp, "/embed/widget", ""); strings.Contains(policy, "frame-ancestors") {
+		t.Fatalf("expected frame-ancestors to be removed, got %q", policy)
+	}
+	if policy := BuildContentSecurityPolicy(csp, "/api/orders", "abc"); !strings.HasSuffix(policy, "script-src 'nonce-abc'; style-src 'self' 'unsafe-inline'") {
+		t.Fatalf("expected nonce to replace 'none', got %q", policy)
+	}
+
+	// style-src 缺失时从 default-src 继承再追加 nonce
+	delete(csp.Directives, "style-src")
+	if policy := BuildContentSecurityPolicy(csp, "/", "abc"); !strings.Contains(policy, "style-src 'self' 'nonce-abc'") {
+		t.Fatalf("expected style-src derived from default-src, got %q", policy)
+	}
+}
+
+func TestApplyCSPNonce(t *testing.T) {
+	html := `<head><style>a{}</style><script src="/a.js"></script><script nonce="keep">1</script></head><body><scripts></scripts></body>`
+	got := ApplyCSPNonce(html, "n1")
+	want := `<head><style nonce="n1">a{}</style><script nonce="n1" src="/a.js"></script><script nonce="keep">1</script></head><body><scripts></scripts></body>`
+	if got != want {
+		t.Fatalf("unexpected html:\n%s", got)
+	}
+	if ApplyCSPNonce(html, "") != html {
+		t.Fatal("expected html to be unchanged without nonce")
+	}
+}
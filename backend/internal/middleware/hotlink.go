@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"auralogic/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// HotlinkProtection 商品图片防盗链：Referer 必须是本站、CORS 白名单或 allowed_domains 中的域名
+func HotlinkProtection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config.GetConfig()
+		if cfg == nil || !cfg.Upload.ProductHotlink.Enabled {
+			c.Next()
+			return
+		}
+		if !isAllowedHotlinkReferer(cfg, c.Request.Host, c.GetHeader("Referer")) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	}
+}
+
+func isAllowedHotlinkReferer(cfg *config.Config, requestHost, referer string) bool {
+	referer = strings.TrimSpace(referer)
+	if referer == "" {
+		return !cfg.Upload.ProductHotlink.BlockEmptyReferer
+	}
+	parsed, err := url.Parse(referer)
+	if err != nil || parsed.Hostname() == "" {
+		return false
+	}
+	refererHost := strings.ToLower(parsed.Hostname())
+
+	if host := hostWithoutPort(requestHost); host != "" && refererHost == host {
+		return true
+	}
+	allowed := make([]string, 0, len(cfg.Security.CORS.AllowedOrigins)+len(cfg.Upload.ProductHotlink.AllowedDomains)+1)
+	if appURL, err := url.Parse(cfg.App.URL); err == nil && appURL.Hostname() != "" {
+		allowed = append(allowed, appURL.Hostname())
+	}
+	for _, origin := range cfg.Security.CORS.AllowedOrigins {
+		if originURL, err := url.Parse(origin); err == nil && originURL.Hostname() != "" {
+			allowed = append(allowed, originURL.Hostname())
+		}
+	}
+	allowed = append(allowed, cfg.Upload.ProductHotlink.AllowedDomains...)
+
+	for _, domain := range allowed {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" {
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(domain, "*."); ok {
+			if strings.HasSuffix(refererHost, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if refererHost == domain {
+			return true
+		}
+	}
+	return false
+}
+
+func hostWithoutPort(host string) string {
+	parsed, err := url.Parse("//" + strings.TrimSpace(host))
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
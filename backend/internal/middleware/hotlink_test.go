@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"testing"
+
+	"auralogic/internal/config"
+)
+
+func TestIsAllowedHotlinkReferer(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.App.URL = "https://shop.example.com"
+	cfg.Security.CORS.AllowedOrigins = []string{"https://admin.example.com"}
+	cfg.Upload.ProductHotlink = config.HotlinkProtectionConfig{
+		Enabled:        true,
+		AllowedDomains: []string{"*.partner.example.org", "cdn.example.net"},
+	}
+
+	for _, tc := range []struct {
+		referer string
+		want    bool
+	}{
+		{"", true},
+		{"https://api.example.com/products/1", true},
+		{"https://shop.example.com/", true},
+		{"https://admin.example.com/admin/products", true},
+		{"https://blog.partner.example.org/post", true},
+		{"https://partner.example.org/", false},
+		{"https://cdn.example.net/page", true},
+		{"https://evil.example.net/", false},
+		{"https://shop.example.com.evil.net/", false},
+		{"not a url", false},
+	} {
+		if got := isAllowedHotlinkReferer(cfg, "api.example.com:8080", tc.referer); got != tc.want {
+			t.Fatalf("referer %q: expected %v, got %v", tc.referer, tc.want, got)
+		}
+	}
+
+	cfg.Upload.ProductHotlink.BlockEmptyReferer = true
+	if isAllowedHotlinkReferer(cfg, "api.example.com", "") {
+		t.Fatal("expected empty referer to be blocked")
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// IPBanChecker 判断 IP 是否被封禁
+type IPBanChecker interface {
+	IsIPBanned(ip string) bool
+}
+
+// IPBanMiddleware 拒绝被封禁 IP 的所有请求
+func IPBanMiddleware(checker IPBanChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if checker != nil && checker.IsIPBanned(utils.GetRealIP(c)) {
+			response.ErrorWithData(c, http.StatusForbidden, response.CodeForbidden, "Access from your IP address has been blocked", gin.H{
+				"error_key": "auth.ipBanned",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
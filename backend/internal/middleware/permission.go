@@ -26,6 +26,10 @@ type permCacheEntry struct {
 // 这些权限涉及敏感数据访问，必须经过明确授权
 var SpecialPermissions = map[string]bool{
 	"order.view_privacy": true, // 查看订单隐私保护信息
+	"order.view_email":   true, // 查看未打码的邮箱
+	"order.view_phone":   true, // 查看未打码的电话
+	"order.view_address": true, // 查看未打码的姓名和地址
+	"order.view_payment": true, // 查看未打码的付款数据
 }
 
 // IsSpecialPermission 检查是否为特殊权限
@@ -149,6 +153,23 @@ func requirePermissions(mode permissionMatchMode, permissions ...string) gin.Han
 	}
 }
 
+// HasAllPermissions 当前请求（JWT 或 API Key）是否拥有全部权限，用于 handler 内按权限调整行为
+func HasAllPermissions(c *gin.Context, permissions ...string) bool {
+	requiredPermissions := uniqueNormalizedPermissions(permissions)
+	if IsAPIKeyAuth(c) {
+		return apiKeyHasPermissions(c, requiredPermissions, permissionMatchAll)
+	}
+	userID, exists := GetUserID(c)
+	if !exists {
+		return false
+	}
+	entry, err := getPermCached(userID)
+	if err != nil {
+		return false
+	}
+	return jwtHasPermissions(entry, requiredPermissions, permissionMatchAll)
+}
+
 func apiKeyHasPermissions(c *gin.Context, requiredPermissions []string, mode permissionMatchMode) bool {
 	if c == nil {
 		return false
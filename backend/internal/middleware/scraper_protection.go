@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+
+	"auralogic/internal/pkg/response"
+	"auralogic/internal/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogTokenHeader 携带 JS 挑战令牌的请求头
+const CatalogTokenHeader = "X-Catalog-Token"
+
+// ScraperVerdict 目录接口防爬判定结果
+type ScraperVerdict int
+
+const (
+	ScraperAllow             ScraperVerdict = iota
+	ScraperHoneypot                         // 命中蜜罐参数
+	ScraperBurst                            // 突发请求超过阈值
+	ScraperChallengeRequired                // 缺少或无效的 JS 挑战令牌
+)
+
+// ScraperGuard 判断目录请求是否来自爬虫
+type ScraperGuard interface {
+	InspectCatalogRequest(ip, userAgent string, query url.Values, token string) ScraperVerdict
+}
+
+// ScraperProtectionMiddleware 保护公开的商品目录接口
+func ScraperProtectionMiddleware(guard ScraperGuard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if guard == nil {
+			c.Next()
+			return
+		}
+		switch guard.InspectCatalogRequest(utils.GetRealIP(c), c.Request.UserAgent(), c.Request.URL.Query(), c.GetHeader(CatalogTokenHeader)) {
+		case ScraperHoneypot:
+			// 不暴露命中原因
+			response.NotFound(c, "Resource not found")
+			c.Abort()
+		case ScraperBurst:
+			response.Error(c, http.StatusTooManyRequests, response.CodeTooManyRequests, "Too many requests, please try again later")
+			c.Abort()
+		case ScraperChallengeRequired:
+			response.ErrorWithData(c, http.StatusForbidden, response.CodeForbidden, "Browser verification required", gin.H{
+				"error_key": "scraper.challengeRequired",
+			})
+			c.Abort()
+		default:
+			c.Next()
+		}
+	}
+}
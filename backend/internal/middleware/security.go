@@ -22,8 +22,8 @@ func SecurityHeaders() gin.HandlerFunc {
 		// 限制Referer信息泄露
 		c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
 
-		// 内容安全策略 - 防止XSS和数据注入
-		c.Header("Content-Security-Policy", "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval'; style-src 'self' 'unsafe-inline'; img-src 'self' data: https:; font-src 'self' data:; connect-src 'self' https:; frame-ancestors 'none'")
+		// 内容安全策略 - 防止XSS和数据注入（security.csp 可配置）
+		setContentSecurityPolicy(c)
 
 		// 权限策略
 		c.Header("Permissions-Policy", "camera=(), microphone=(), geolocation=()")
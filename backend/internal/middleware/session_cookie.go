@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthModeHeader Web 前端登录时携带 "cookie" 选择 Cookie 会话
+const AuthModeHeader = "X-Auth-Mode"
+
+// sessionCookieConfig 已启用时返回 Cookie 会话配置
+func sessionCookieConfig() (*config.SessionCookieConfig, bool) {
+	cfg := config.GetConfig()
+	if cfg == nil || !cfg.Security.SessionCookie.Enabled {
+		return nil, false
+	}
+	return &cfg.Security.SessionCookie, true
+}
+
+func sessionSameSite(value string) http.SameSite {
+	switch value {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+func setSessionCookie(c *gin.Context, cfg *config.SessionCookieConfig, name, value string, maxAge int, httpOnly bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     cfg.Path,
+		Domain:   cfg.Domain,
+		MaxAge:   maxAge,
+		Secure:   cfg.Secure,
+		HttpOnly: httpOnly,
+		SameSite: sessionSameSite(cfg.SameSite),
+	})
+}
+
+func newCSRFToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// WantsCookieSession 是否应以 Cookie 会话响应本次登录
+func WantsCookieSession(c *gin.Context) bool {
+	_, enabled := sessionCookieConfig()
+	return enabled && strings.EqualFold(strings.TrimSpace(c.GetHeader(AuthModeHeader)), "cookie")
+}
+
+// IssueSessionCookie 写入 HttpOnly 会话 Cookie 和 CSRF Cookie，返回 CSRF 令牌
+func IssueSessionCookie(c *gin.Context, token string, ttl time.Duration) (string, error) {
+	cfg, enabled := sessionCookieConfig()
+	if !enabled {
+		return "", nil
+	}
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		return "", err
+	}
+	maxAge := int(ttl.Seconds())
+	setSessionCookie(c, cfg, cfg.Name, token, maxAge, true)
+	setSessionCookie(c, cfg, cfg.CSRFCookieName, csrfToken, maxAge, false)
+	return csrfToken, nil
+}
+
+// ClearSessionCookie 登出时清除会话 Cookie
+func ClearSessionCookie(c *gin.Context) {
+	cfg, enabled := sessionCookieConfig()
+	if !enabled {
+		return
+	}
+	setSessionCookie(c, cfg, cfg.Name, "", -1, true)
+	setSessionCookie(c, cfg, cfg.CSRFCookieName, "", -1, false)
+}
+
+// EnsureCSRFCookie 返回当前 CSRF 令牌，缺失时重新签发（仅在已有会话 Cookie 时）
+func EnsureCSRFCookie(c *gin.Context) (string, bool) {
+	cfg, enabled := sessionCookieConfig()
+	if !enabled {
+		return "", false
+	}
+	if current, err := c.Cookie(cfg.CSRFCookieName); err == nil && current != "" {
+		return current, true
+	}
+	if session, err := c.Cookie(cfg.Name); err != nil || session == "" {
+		return "", false
+	}
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		return "", false
+	}
+	setSessionCookie(c, cfg, cfg.CSRFCookieName, csrfToken, 0, false)
+	return csrfToken, true
+}
+
+// extractSessionCookieToken 从会话 Cookie 读取令牌
+func extractSessionCookieToken(c *gin.Context) string {
+	cfg, enabled := sessionCookieConfig()
+	if !enabled || c == nil || c.Request == nil {
+		return ""
+	}
+	token, err := c.Cookie(cfg.Name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// verifyCSRF 校验 Cookie 会话的写请求，Bearer Token 和 API Key 请求不受影响
+func verifyCSRF(c *gin.Context) bool {
+	if isSafeMethod(c.Request.Method) {
+		return true
+	}
+	cfg, enabled := sessionCookieConfig()
+	if !enabled {
+		return false
+	}
+	if cfg.CSRFStrategy == "same_site" {
+		return isTrustedOrigin(c)
+	}
+	cookieToken, err := c.Cookie(cfg.CSRFCookieName)
+	headerToken := strings.TrimSpace(c.GetHeader(cfg.CSRFHeaderName))
+	if err != nil || cookieToken == "" || headerToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookieToken), []byte(headerToken)) == 1
+}
+
+// isTrustedOrigin Origin（缺失时 Referer）必须与请求 Host 相同或在 CORS 白名单中
+func isTrustedOrigin(c *gin.Context) bool {
+	origin := strings.TrimSpace(c.GetHeader("Origin"))
+	if origin == "" {
+		referer := strings.TrimSpace(c.GetHeader("Referer"))
+		if referer == "" {
+			return false
+		}
+		parsed, err := url.Parse(referer)
+		if err != nil || parsed.Host == "" {
+			return false
+		}
+		origin = parsed.Scheme + "://" + parsed.Host
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	if strings.EqualFold(parsed.Host, c.Request.Host) {
+		return true
+	}
+	if cfg := config.GetConfig(); cfg != nil {
+		for _, allowed := range cfg.Security.CORS.AllowedOrigins {
+			if strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func abortCSRF(c *gin.Context) {
+	response.ErrorWithData(c, http.StatusForbidden, response.CodeForbidden, "CSRF token missing or invalid", gin.H{
+		"error_key": "auth.csrfInvalid",
+	})
+	c.Abort()
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+func loadSessionCookieTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	configJSON := `{
+  "app": {"name": "test", "port": 8080},
+  "database": {"driver": "sqlite", "name": "test.db"},
+  "jwt": {"secret": "12345678901234567890123456789012"},
+  "security": {
+    "cors": {"allowed_origins": ["https://shop.example.com"]},
+    "login": {},
+    "password_policy": {"min_length": 8},
+    "captcha": {},
+    "session_cookie": {"enabled": true}
+  }
+}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0o644); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	original := cfg.Security.SessionCookie
+	t.Cleanup(func() { cfg.Security.SessionCookie = original })
+	return cfg
+}
+
+func newSessionTestContext(method string, cookies map[string]string, headers map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	request := httptest.NewRequest(method, "http://api.example.com/api/user/orders", nil)
+	for name, value := range cookies {
+		request.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	ctx.Request = request
+	return ctx, recorder
+}
+
+func TestSessionCookieIssueAndDoubleSubmitCSRF(t *testing.T) {
+	cfg := loadSessionCookieTestConfig(t)
+	if cfg.Security.SessionCookie.CSRFStrategy != "double_submit" || cfg.Security.SessionCookie.SameSite != "lax" {
+		t.Fatalf("expected secure defaults, got %+v", cfg.Security.SessionCookie)
+	}
+
+	loginCtx, recorder := newSessionTestContext(http.MethodPost, nil, map[string]string{AuthModeHeader: "cookie"})
+	if !WantsCookieSession(loginCtx) {
+		t.Fatal("expected cookie session to be requested")
+	}
+	csrfToken, err := IssueSessionCookie(loginCtx, "jwt-token", time.Hour)
+	if err != nil || csrfToken == "" {
+		t.Fatalf("issue session cookie: %q, %v", csrfToken, err)
+	}
+	issued := map[string]*http.Cookie{}
+	for _, cookie := range recorder.Result().Cookies() {
+		issued[cookie.Name] = cookie
+	}
+	session := issued["auralogic_sid"]
+	if session == nil || session.Value != "jwt-token" || !session.HttpOnly || session.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("unexpected session cookie: %+v", session)
+	}
+	if csrf := issued["auralogic_csrf"]; csrf == nil || csrf.HttpOnly || csrf.Value != csrfToken {
+		t.Fatalf("expected readable csrf cookie, got %+v", csrf)
+	}
+
+	cookies := map[string]string{"auralogic_sid": "jwt-token", "auralogic_csrf": csrfToken}
+	ctx, _ := newSessionTestContext(http.MethodGet, cookies, nil)
+	if token, fromCookie := extractAuthToken(ctx); token != "jwt-token" || !fromCookie {
+		t.Fatalf("expected token from cookie, got %q %v", token, fromCookie)
+	}
+	if !verifyCSRF(ctx) {
+		t.Fatal("expected safe methods to skip csrf")
+	}
+
+	ctx, _ = newSessionTestContext(http.MethodPost, cookies, nil)
+	if verifyCSRF(ctx) {
+		t.Fatal("expected missing csrf header to be rejected")
+	}
+	ctx, _ = newSessionTestContext(http.MethodPost, cookies, map[string]string{"X-CSRF-Token": "forged"})
+	if verifyCSRF(ctx) {
+		t.Fatal("expected mismatched csrf header to be rejected")
+	}
+	ctx, _ = newSessionTestContext(http.MethodPost, cookies, map[string]string{"X-CSRF-Token": csrfToken})
+	if !verifyCSRF(ctx) {
+		t.Fatal("expected matching csrf header to pass")
+	}
+
+	// Bearer Token 优先，不受 CSRF 校验影响
+	ctx, _ = newSessionTestContext(http.MethodPost, cookies, map[string]string{"Authorization": "Bearer api-token"})
+	if token, fromCookie := extractAuthToken(ctx); token != "api-token" || fromCookie {
+		t.Fatalf("expected bearer token to win, got %q %v", token, fromCookie)
+	}
+
+	// CSRF 校验失败时在查询用户前中止
+	ctx, recorder = newSessionTestContext(http.MethodDelete, cookies, nil)
+	AuthMiddleware()(ctx)
+	if !ctx.IsAborted() || recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected csrf rejection, got aborted=%v code=%d", ctx.IsAborted(), recorder.Code)
+	}
+
+	cfg.Security.SessionCookie.Enabled = false
+	ctx, _ = newSessionTestContext(http.MethodGet, cookies, map[string]string{AuthModeHeader: "cookie"})
+	if token, _ := extractAuthToken(ctx); token != "" || WantsCookieSession(ctx) {
+		t.Fatal("expected cookies to be ignored when session cookies are disabled")
+	}
+}
+
+func TestSessionCookieSameSiteStrategyChecksOrigin(t *testing.T) {
+	cfg := loadSessionCookieTestConfig(t)
+	cfg.Security.SessionCookie.CSRFStrategy = "same_site"
+	cookies := map[string]string{"auralogic_sid": "jwt-token"}
+
+	for _, tc := range []struct {
+		headers map[string]string
+		want    bool
+	}{
+		{map[string]string{"Origin": "http://api.example.com"}, true},
+		{map[string]string{"Origin": "https://shop.example.com"}, true},
+		{map[string]string{"Referer": "https://shop.example.com/checkout"}, true},
+		{map[string]string{"Origin": "https://evil.example.net"}, false},
+		{nil, false},
+	} {
+		ctx, _ := newSessionTestContext(http.MethodPost, cookies, tc.headers)
+		if got := verifyCSRF(ctx); got != tc.want {
+			t.Fatalf("headers %v: expected %v, got %v", tc.headers, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"log"
+
+	"auralogic/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const storeContextKey = "store"
+
+// StoreResolver 根据请求 Host 解析当前店铺
+type StoreResolver interface {
+	ResolveStoreByHost(host string) (*models.Store, error)
+}
+
+// StoreContextMiddleware 多店铺解析中间件：按 Host 匹配店铺并写入上下文
+// 解析失败时不中断请求，按单店铺模式继续处理
+func StoreContextMiddleware(resolver StoreResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if resolver != nil {
+			store, err := resolver.ResolveStoreByHost(c.Request.Host)
+			if err != nil {
+				log.Printf("store resolve failed: host=%s err=%v", c.Request.Host, err)
+			} else if store != nil {
+				c.Set(storeContextKey, store)
+				c.Set("store_id", store.ID)
+			}
+		}
+		c.Next()
+	}
+}
+
+// GetStore 获取当前请求所属店铺
+func GetStore(c *gin.Context) (*models.Store, bool) {
+	value, exists := c.Get(storeContextKey)
+	if !exists {
+		return nil, false
+	}
+	store, ok := value.(*models.Store)
+	return store, ok && store != nil
+}
+
+// GetStoreID 获取当前请求所属店铺ID，0 表示单店铺模式
+func GetStoreID(c *gin.Context) uint {
+	if store, ok := GetStore(c); ok {
+		return store.ID
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// AdminApprovalStatus 审批状态
+const (
+	AdminApprovalStatusPending   = "pending"
+	AdminApprovalStatusApproved  = "approved"
+	AdminApprovalStatusRejected  = "rejected"
+	AdminApprovalStatusCancelled = "cancelled"
+	AdminApprovalStatusExecuting = "executing" // 发起人携带审批单执行中，操作成功后转为 executed，失败退回 approved
+	AdminApprovalStatusExecuted  = "executed"
+	AdminApprovalStatusExpired   = "expired"
+)
+
+// AdminApprovalAction 需要双人审批的操作类型
+const (
+	AdminApprovalActionOrderRefund       = "order.refund"
+	AdminApprovalActionOrderPriceUpdate  = "order.price_update"
+	AdminApprovalActionProductPrice      = "product.price_update"
+	AdminApprovalActionSerialBatchDelete = "serial.batch_delete"
+)
+
+// AdminApproval 危险操作的审批单（四眼原则）
+// 发起人提交操作时生成待审批记录，另一位拥有审批权限的管理员通过后，发起人携带审批单 ID 重新提交才会真正执行
+type AdminApproval struct {
+	ID           uint                   `gorm:"primaryKey" json:"id"`
+	ActionType   string                 `gorm:"type:varchar(50);index;not null" json:"action_type"`
+	ResourceType string                 `gorm:"type:varchar(50)" json:"resource_type"`
+	ResourceID   string                 `gorm:"type:varchar(100);index" json:"resource_id"`
+	Summary      string                 `gorm:"type:varchar(500)" json:"summary"`
+	Payload      map[string]interface{} `gorm:"type:text;serializer:json" json:"payload"`
+	PayloadHash  string                 `gorm:"type:varchar(64);index;not null" json:"-"` // 执行时校验请求内容与审批时一致
+	Status       string                 `gorm:"type:varchar(20);index;not null" json:"status"`
+	RequestedBy  uint                   `gorm:"index;not null" json:"requested_by"`
+	ReviewedBy   *uint                  `json:"reviewed_by,omitempty"`
+	ReviewNote   string                 `gorm:"type:varchar(500)" json:"review_note,omitempty"`
+	ReviewedAt   *time.Time             `json:"reviewed_at,omitempty"`
+	ExecutedAt   *time.Time             `json:"executed_at,omitempty"`
+	ExpiresAt    time.Time              `gorm:"index" json:"expires_at"`
+	CreatedAt    time.Time              `gorm:"index" json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+func (AdminApproval) TableName() string {
+	return "admin_approvals"
+}
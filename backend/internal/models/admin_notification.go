@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// AdminNotificationEventLowStock 库存低于安全库存；其余可订阅事件沿用出站 Webhook 的订单/工单事件名
+const AdminNotificationEventLowStock = "inventory.low_stock"
+
+// AdminNotificationChannel 通知渠道
+const (
+	AdminNotificationChannelEmail    = "email"
+	AdminNotificationChannelTelegram = "telegram"
+	AdminNotificationChannelInApp    = "in_app"
+)
+
+// AdminNotificationSubscription 管理员个人的事件订阅
+// Countries 仅对订单事件生效（匹配收货国家），Categories 对工单事件匹配工单分类、对低库存事件匹配绑定商品的分类；为空表示不限
+type AdminNotificationSubscription struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	AdminID        uint      `gorm:"index;not null" json:"admin_id"`
+	Event          string    `gorm:"type:varchar(50);index;not null" json:"event"`
+	Channel        string    `gorm:"type:varchar(20);not null" json:"channel"`
+	Countries      []string  `gorm:"type:text;serializer:json" json:"countries"`
+	Categories     []string  `gorm:"type:text;serializer:json" json:"categories"`
+	TelegramChatID string    `gorm:"type:varchar(64)" json:"telegram_chat_id,omitempty"`
+	Enabled        bool      `gorm:"index" json:"enabled"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (AdminNotificationSubscription) TableName() string {
+	return "admin_notification_subscriptions"
+}
+
+// AdminNotification 站内通知，由 in_app 渠道的订阅生成
+type AdminNotification struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	AdminID        uint       `gorm:"index:idx_admin_notification_inbox,priority:1;not null" json:"admin_id"`
+	SubscriptionID *uint      `json:"subscription_id,omitempty"`
+	Event          string     `gorm:"type:varchar(50);not null" json:"event"`
+	Title          string     `gorm:"type:varchar(255);not null" json:"title"`
+	Content        string     `gorm:"type:text" json:"content"`
+	Link           string     `gorm:"type:varchar(500)" json:"link,omitempty"`
+	ReadAt         *time.Time `gorm:"index:idx_admin_notification_inbox,priority:2" json:"read_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
+}
+
+func (AdminNotification) TableName() string {
+	return "admin_notifications"
+}
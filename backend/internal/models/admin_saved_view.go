@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"auralogic/internal/pkg/listfilter"
+)
+
+// AdminSavedView 管理员保存的列表视图（订单/用户/工单），每个管理员每种列表最多一个默认视图
+type AdminSavedView struct {
+	ID        uint               `gorm:"primaryKey" json:"id"`
+	AdminID   uint               `gorm:"not null;index:idx_admin_saved_view_owner" json:"admin_id"`
+	Resource  string             `gorm:"type:varchar(30);not null;index:idx_admin_saved_view_owner" json:"resource"` // orders / users / tickets
+	Name      string             `gorm:"type:varchar(100);not null" json:"name"`
+	Params    map[string]string  `gorm:"type:text;serializer:json" json:"params"` // 普通查询参数，如 status、search
+	Filter    *listfilter.Filter `gorm:"type:text;serializer:json" json:"filter,omitempty"`
+	IsDefault bool               `json:"is_default"`
+	SortOrder int                `gorm:"default:0" json:"sort_order"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+func (AdminSavedView) TableName() string {
+	return "admin_saved_views"
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -18,11 +21,24 @@ type APIKey struct {
 	// Permission范围
 	Scopes []string `gorm:"type:text;serializer:json" json:"scopes,omitempty"`
 
-	// 限流
-	RateLimit int `gorm:"default:1000" json:"rate_limit"`
+	// 限流：RateLimit 为每小时持续速率，BurstLimit 为令牌桶容量（允许的瞬时突发请求数）
+	// RateLimit <= 0 表示不限流；BurstLimit <= 0 时按一分钟的持续速率计算
+	RateLimit  int `gorm:"default:1000" json:"rate_limit"`
+	BurstLimit int `gorm:"not null;default:0" json:"burst_limit"`
+
+	// 来源 IP 白名单（单个 IP 或 CIDR），为空表示不限制
+	AllowedIPs []string `gorm:"type:text;serializer:json" json:"allowed_ips,omitempty"`
+	// 最近使用过的来源 IP，出现新来源时生成安全事件
+	KnownIPs []string `gorm:"type:text;serializer:json" json:"known_ips,omitempty"`
+
+	// 订单事件回调地址（如草稿过期取消），为空表示不通知
+	WebhookURL string `gorm:"type:varchar(500)" json:"webhook_url,omitempty"`
+	// 允许同一平台的外部订单号重复创建草稿；默认严格模式，重复提交返回已存在的订单
+	AllowDuplicateExternalOrders bool `gorm:"not null;default:false" json:"allow_duplicate_external_orders"`
 
 	IsActive   bool       `gorm:"default:true;index" json:"is_active"`
 	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `gorm:"type:varchar(50)" json:"last_used_ip,omitempty"`
 	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
 
 	CreatedBy uint           `json:"created_by"`
@@ -60,6 +76,18 @@ func (ak *APIKey) IsExpired() bool {
 	return time.Now().After(*ak.ExpiresAt)
 }
 
+// EffectiveBurstLimit 返回令牌桶容量，未配置时取一分钟的持续速率（至少 1）
+func (ak *APIKey) EffectiveBurstLimit() int {
+	if ak.BurstLimit > 0 {
+		return ak.BurstLimit
+	}
+	burst := ak.RateLimit / 60
+	if burst < 1 {
+		burst = 1
+	}
+	return burst
+}
+
 // HasScope 检查是否拥有指定Permission范围
 func (ak *APIKey) HasScope(scope string) bool {
 	for _, s := range ak.Scopes {
@@ -70,3 +98,56 @@ func (ak *APIKey) HasScope(scope string) bool {
 	return false
 }
 
+// AllowsIP 检查来源 IP 是否在白名单内，未配置白名单时始终允许
+func (ak *APIKey) AllowsIP(ip string) bool {
+	if len(ak.AllowedIPs) == 0 {
+		return true
+	}
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return false
+	}
+	for _, entry := range ak.AllowedIPs {
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(parsed) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeAPIKeyAllowedIPs 校验并规范化 IP/CIDR 白名单
+func NormalizeAPIKeyAllowedIPs(entries []string) ([]string, error) {
+	normalized := make([]string, 0, len(entries))
+	seen := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR: %s", entry)
+			}
+			entry = network.String()
+		} else {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address: %s", entry)
+			}
+			entry = ip.String()
+		}
+		if _, exists := seen[entry]; exists {
+			continue
+		}
+		seen[entry] = struct{}{}
+		normalized = append(normalized, entry)
+	}
+	return normalized, nil
+}
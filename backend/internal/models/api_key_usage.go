@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// APIKeyUsage API Key 按小时聚合的调用量，Throttled 为被令牌桶拒绝的请求数
+type APIKeyUsage struct {
+	ID             uint      `gorm:"primaryKey" json:"-"`
+	APIKeyID       uint      `gorm:"not null;uniqueIndex:idx_api_key_usage_key_hour,priority:1" json:"api_key_id"`
+	Hour           time.Time `gorm:"not null;uniqueIndex:idx_api_key_usage_key_hour,priority:2;index" json:"hour"`
+	RequestCount   int64     `gorm:"not null;default:0" json:"requests"`
+	ThrottledCount int64     `gorm:"not null;default:0" json:"throttled"`
+	UpdatedAt      time.Time `json:"-"`
+}
+
+func (APIKeyUsage) TableName() string {
+	return "api_key_usages"
+}
@@ -34,6 +34,9 @@ type CartItem struct {
 	// 不同属性的商品应该分开存储
 	Attributes     JSONMap `gorm:"type:text" json:"attributes"`
 	AttributesHash string  `gorm:"type:varchar(32);index" json:"-"` // 属性哈希，用于快速匹配
+
+	// 加购即预留的到期时间（仅运行时填充，预留记录不落库）
+	ReservedUntil *time.Time `gorm:"-" json:"reserved_until,omitempty"`
 }
 
 // BeforeCreate 创建前计算属性哈希
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type CODCollectionStatus string
+
+const (
+	CODCollectionStatusPending   CODCollectionStatus = "pending"   // 待派送收款
+	CODCollectionStatusCollected CODCollectionStatus = "collected" // 承运商已收款，待回款
+	CODCollectionStatusSettled   CODCollectionStatus = "settled"   // 承运商已回款
+	CODCollectionStatusCancelled CODCollectionStatus = "cancelled" // 订单取消
+)
+
+// CODCollection 货到付款代收记录，跟踪承运商/司机代收与回款
+type CODCollection struct {
+	ID              uint                `gorm:"primaryKey" json:"id"`
+	OrderID         uint                `gorm:"uniqueIndex;not null" json:"order_id"`
+	OrderNo         string              `gorm:"type:varchar(50);index" json:"order_no"`
+	PaymentMethodID uint                `gorm:"index" json:"payment_method_id"`
+	Currency        string              `gorm:"type:varchar(10)" json:"currency"`
+	AmountDue       int64               `gorm:"not null" json:"amount_due"` // 应收金额（分）
+	Status          CODCollectionStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+
+	Carrier         string     `gorm:"type:varchar(100);index" json:"carrier,omitempty"` // 承运商或司机
+	CollectedAmount int64      `gorm:"default:0" json:"collected_amount"`                // 实收金额（分）
+	CollectedAt     *time.Time `json:"collected_at,omitempty"`
+	CollectedBy     *uint      `json:"collected_by,omitempty"`
+	SettledAt       *time.Time `json:"settled_at,omitempty"`
+	SettledBy       *uint      `json:"settled_by,omitempty"`
+	Remark          string     `gorm:"type:varchar(500)" json:"remark,omitempty"`
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (CODCollection) TableName() string {
+	return "cod_collections"
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// 系统配置版本来源
+const (
+	ConfigRevisionActionBaseline = "baseline" // 首次变更前自动记录的原始配置
+	ConfigRevisionActionUpdate   = "update"
+	ConfigRevisionActionRollback = "rollback"
+	ConfigRevisionActionImport   = "import" // 从其他环境导出的配置包导入
+)
+
+// ConfigRevision 系统配置变更版本，快照与差异中的密钥均已脱敏
+type ConfigRevision struct {
+	ID           uint                   `gorm:"primaryKey" json:"id"`
+	Action       string                 `gorm:"type:varchar(20);not null;index" json:"action"`
+	AdminID      *uint                  `gorm:"index" json:"admin_id,omitempty"`
+	RollbackOfID *uint                  `json:"rollback_of_id,omitempty"` // 回滚到的目标版本
+	Sections     []string               `gorm:"type:text;serializer:json" json:"sections"`
+	Changes      []ConfigChange         `gorm:"type:text;serializer:json" json:"changes"`
+	ChangeCount  int                    `gorm:"default:0" json:"change_count"`
+	Snapshot     map[string]interface{} `gorm:"type:text;serializer:json" json:"snapshot,omitempty"`
+	CreatedAt    time.Time              `gorm:"index" json:"created_at"`
+}
+
+// ConfigChange 单个配置项的变更，Path 为点分隔的 JSON 路径
+type ConfigChange struct {
+	Path     string      `json:"path"`
+	Before   interface{} `json:"before"`
+	After    interface{} `json:"after"`
+	Redacted bool        `json:"redacted,omitempty"`
+}
+
+// TableName 指定表名
+func (ConfigRevision) TableName() string {
+	return "config_revisions"
+}
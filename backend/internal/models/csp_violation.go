@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// CSPViolation 浏览器上报的 CSP 违规，按 页面 + 指令 + 被拦截资源 聚合计数
+type CSPViolation struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	Fingerprint        string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	DocumentURI        string    `gorm:"type:varchar(512)" json:"document_uri"`
+	BlockedURI         string    `gorm:"type:varchar(512)" json:"blocked_uri"`
+	EffectiveDirective string    `gorm:"type:varchar(64);index" json:"effective_directive"`
+	Disposition        string    `gorm:"type:varchar(20)" json:"disposition"` // enforce | report
+	SourceFile         string    `gorm:"type:varchar(512)" json:"source_file,omitempty"`
+	LineNumber         int       `json:"line_number,omitempty"`
+	Sample             string    `gorm:"type:varchar(255)" json:"sample,omitempty"`
+	Count              int64     `gorm:"not null;default:1" json:"count"`
+	LastIP             string    `gorm:"type:varchar(50)" json:"last_ip,omitempty"`
+	LastUserAgent      string    `gorm:"type:varchar(255)" json:"last_user_agent,omitempty"`
+	FirstSeenAt        time.Time `json:"first_seen_at"`
+	LastSeenAt         time.Time `gorm:"index" json:"last_seen_at"`
+}
+
+func (CSPViolation) TableName() string {
+	return "csp_violations"
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// EmailChange 用户邮箱变更记录，旧邮箱可凭撤销链接在有效期内恢复
+type EmailChange struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	OldEmail    string     `gorm:"type:varchar(255);not null" json:"old_email"`
+	NewEmail    string     `gorm:"type:varchar(255);not null" json:"new_email"`
+	RevertToken string     `gorm:"type:varchar(255);uniqueIndex;not null" json:"-"`
+	ExpiresAt   time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevertedAt  *time.Time `json:"reverted_at,omitempty"`
+	IPAddress   string     `gorm:"type:varchar(50)" json:"ip_address,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (EmailChange) TableName() string {
+	return "email_changes"
+}
+
+// CanRevert 撤销链接是否仍然有效
+func (e *EmailChange) CanRevert(now time.Time) bool {
+	return e.RevertedAt == nil && now.Before(e.ExpiresAt)
+}
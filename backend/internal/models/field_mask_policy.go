@@ -0,0 +1,272 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// FieldMaskField 可配置打码的敏感字段分类
+type FieldMaskField string
+
+const (
+	FieldMaskEmail   FieldMaskField = "email"   // 收件邮箱、下单邮箱
+	FieldMaskPhone   FieldMaskField = "phone"   // 收件电话
+	FieldMaskAddress FieldMaskField = "address" // 收件人姓名、详细地址（full 时含区县和邮编）
+	FieldMaskPayment FieldMaskField = "payment" // 付款数据（交易号等）
+)
+
+// FieldMaskMode 打码方式
+type FieldMaskMode string
+
+const (
+	FieldMaskModeNone    FieldMaskMode = "none"
+	FieldMaskModePartial FieldMaskMode = "partial"
+	FieldMaskModeFull    FieldMaskMode = "full"
+)
+
+// FieldMaskScope 打码范围
+type FieldMaskScope string
+
+const (
+	FieldMaskScopePrivacyOnly FieldMaskScope = "privacy_only" // 仅隐私保护订单
+	FieldMaskScopeAll         FieldMaskScope = "all"          // 所有订单
+)
+
+// FieldMaskFields 全部可配置字段（按展示顺序）
+var FieldMaskFields = []FieldMaskField{FieldMaskEmail, FieldMaskPhone, FieldMaskAddress, FieldMaskPayment}
+
+// FieldMaskRoles 可配置策略的管理员角色
+var FieldMaskRoles = []string{"admin", "super_admin"}
+
+// FieldMaskRevealPermissions 单字段查看权限：拥有即不打码该字段
+var FieldMaskRevealPermissions = map[FieldMaskField]string{
+	FieldMaskEmail:   "order.view_email",
+	FieldMaskPhone:   "order.view_phone",
+	FieldMaskAddress: "order.view_address",
+	FieldMaskPayment: "order.view_payment",
+}
+
+// FieldMaskPolicy 某个角色对某个字段的打码策略，未配置时使用 DefaultFieldMaskPolicy
+type FieldMaskPolicy struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Role      string         `gorm:"type:varchar(20);not null;uniqueIndex:idx_field_mask_role_field" json:"role"`
+	Field     FieldMaskField `gorm:"type:varchar(20);not null;uniqueIndex:idx_field_mask_role_field" json:"field"`
+	Mode      FieldMaskMode  `gorm:"type:varchar(20);not null" json:"mode"`
+	Scope     FieldMaskScope `gorm:"type:varchar(20);not null" json:"scope"`
+	UpdatedBy *uint          `json:"updated_by,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (FieldMaskPolicy) TableName() string {
+	return "field_mask_policies"
+}
+
+// DefaultFieldMaskPolicy 内置默认策略，与原 order.view_privacy 行为一致：
+// 隐私保护订单隐藏姓名和详细地址、电话部分打码，邮箱和付款数据不打码
+func DefaultFieldMaskPolicy(role string, field FieldMaskField) FieldMaskPolicy {
+	policy := FieldMaskPolicy{Role: role, Field: field, Mode: FieldMaskModeNone, Scope: FieldMaskScopePrivacyOnly}
+	switch field {
+	case FieldMaskPhone, FieldMaskAddress:
+		policy.Mode = FieldMaskModePartial
+	}
+	return policy
+}
+
+// IsValidFieldMaskField 是否为可配置字段
+func IsValidFieldMaskField(field FieldMaskField) bool {
+	for _, item := range FieldMaskFields {
+		if item == field {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldMaskRules 某个管理员实际生效的打码规则（已排除其有权查看的字段）
+type FieldMaskRules map[FieldMaskField]FieldMaskPolicy
+
+// DefaultFieldMaskRules 无任何查看权限时的默认规则
+func DefaultFieldMaskRules() FieldMaskRules {
+	rules := make(FieldMaskRules, len(FieldMaskFields))
+	for _, field := range FieldMaskFields {
+		rules[field] = DefaultFieldMaskPolicy("", field)
+	}
+	return rules
+}
+
+// ModeFor 字段在给定订单上的打码方式
+func (r FieldMaskRules) ModeFor(field FieldMaskField, privacyProtected bool) FieldMaskMode {
+	policy, ok := r[field]
+	if !ok || policy.Mode == "" {
+		return FieldMaskModeNone
+	}
+	if policy.Scope != FieldMaskScopeAll && !privacyProtected {
+		return FieldMaskModeNone
+	}
+	return policy.Mode
+}
+
+// ApplyToOrder 按规则打码订单（含预加载的下单用户）
+func (r FieldMaskRules) ApplyToOrder(o *Order) {
+	if o == nil {
+		return
+	}
+	switch r.ModeFor(FieldMaskEmail, o.PrivacyProtected) {
+	case FieldMaskModePartial:
+		o.ReceiverEmail = MaskEmail(o.ReceiverEmail)
+		o.UserEmail = MaskEmail(o.UserEmail)
+		if o.User != nil {
+			o.User.Email = MaskEmail(o.User.Email)
+		}
+	case FieldMaskModeFull:
+		o.ReceiverEmail = maskAll(o.ReceiverEmail)
+		o.UserEmail = maskAll(o.UserEmail)
+		if o.User != nil {
+			o.User.Email = maskAll(o.User.Email)
+		}
+	}
+
+	switch r.ModeFor(FieldMaskPhone, o.PrivacyProtected) {
+	case FieldMaskModePartial:
+		o.ReceiverPhone = MaskPhone(o.ReceiverPhone)
+		if o.User != nil && o.User.Phone != nil {
+			masked := MaskPhone(*o.User.Phone)
+			o.User.Phone = &masked
+		}
+	case FieldMaskModeFull:
+		o.ReceiverPhone = maskAll(o.ReceiverPhone)
+		if o.User != nil && o.User.Phone != nil {
+			masked := maskAll(*o.User.Phone)
+			o.User.Phone = &masked
+		}
+	}
+
+	switch r.ModeFor(FieldMaskAddress, o.PrivacyProtected) {
+	case FieldMaskModePartial:
+		// 保留国家和省市区，便于统计和分单
+		o.ReceiverName = "***"
+		o.ReceiverAddress = "***"
+	case FieldMaskModeFull:
+		o.ReceiverName = "***"
+		o.ReceiverAddress = "***"
+		o.ReceiverDistrict = maskAll(o.ReceiverDistrict)
+		o.ReceiverPostcode = maskAll(o.ReceiverPostcode)
+	}
+}
+
+// MaskPaymentData 打码订单付款数据（JSON 对象时逐个打码字符串值）
+func (r FieldMaskRules) MaskPaymentData(data string, privacyProtected bool) string {
+	switch r.ModeFor(FieldMaskPayment, privacyProtected) {
+	case FieldMaskModePartial:
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return maskAll(data)
+		}
+		for key, value := range payload {
+			if text, ok := value.(string); ok {
+				payload[key] = MaskContent(text)
+			}
+		}
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return maskAll(data)
+		}
+		return string(encoded)
+	case FieldMaskModeFull:
+		return maskAll(data)
+	}
+	return data
+}
+
+var fieldMaskLogKeys = map[string]FieldMaskField{
+	"receiver_email":    FieldMaskEmail,
+	"user_email":        FieldMaskEmail,
+	"email":             FieldMaskEmail,
+	"receiver_phone":    FieldMaskPhone,
+	"phone":             FieldMaskPhone,
+	"receiver_name":     FieldMaskAddress,
+	"receiver_address":  FieldMaskAddress,
+	"receiver_district": FieldMaskAddress,
+	"receiver_postcode": FieldMaskAddress,
+	"payment_data":      FieldMaskPayment,
+}
+
+// MaskLogDetails 打码操作日志详情，返回副本；日志中带 privacy_protected=true 时按隐私订单处理
+func (r FieldMaskRules) MaskLogDetails(details map[string]interface{}) map[string]interface{} {
+	if len(details) == 0 || len(r) == 0 {
+		return details
+	}
+	privacyProtected, _ := details["privacy_protected"].(bool)
+	return r.maskLogMap(details, privacyProtected)
+}
+
+func (r FieldMaskRules) maskLogMap(details map[string]interface{}, privacyProtected bool) map[string]interface{} {
+	masked := make(map[string]interface{}, len(details))
+	for key, value := range details {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			masked[key] = r.maskLogMap(typed, privacyProtected)
+			continue
+		case string:
+			if field, ok := fieldMaskLogKeys[key]; ok {
+				masked[key] = r.maskLogValue(field, key, typed, privacyProtected)
+				continue
+			}
+		}
+		masked[key] = value
+	}
+	return masked
+}
+
+func (r FieldMaskRules) maskLogValue(field FieldMaskField, key, value string, privacyProtected bool) string {
+	mode := r.ModeFor(field, privacyProtected)
+	if mode == FieldMaskModeNone {
+		return value
+	}
+	switch field {
+	case FieldMaskEmail:
+		if mode == FieldMaskModePartial {
+			return MaskEmail(value)
+		}
+	case FieldMaskPhone:
+		if mode == FieldMaskModePartial {
+			return MaskPhone(value)
+		}
+	case FieldMaskAddress:
+		// partial 与订单一致，保留区县和邮编
+		if mode == FieldMaskModePartial && (key == "receiver_district" || key == "receiver_postcode") {
+			return value
+		}
+	case FieldMaskPayment:
+		return r.MaskPaymentData(value, privacyProtected)
+	}
+	return maskAll(value)
+}
+
+// MaskEmail 邮箱打码，保留首字符和域名：a***@example.com
+func MaskEmail(email string) string {
+	email = strings.TrimSpace(email)
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return maskAll(email)
+	}
+	local := []rune(email[:at])
+	return string(local[0]) + "***" + email[at:]
+}
+
+// MaskPhone 电话打码，保留前 3 位和后 4 位
+func MaskPhone(phone string) string {
+	if len(phone) > 7 {
+		return phone[:3] + "****" + phone[len(phone)-4:]
+	}
+	return maskAll(phone)
+}
+
+func maskAll(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "***"
+}
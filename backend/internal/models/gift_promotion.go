@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GiftPromotion 满赠活动：购物车达到金额门槛或包含指定商品时自动附赠赠品
+// 赠品从指定库存扣减，下单时预留，条件不再满足时自动移除
+type GiftPromotion struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"type:varchar(255);not null" json:"name"`
+	Description string `gorm:"type:text" json:"description,omitempty"`
+	IsActive    bool   `gorm:"index" json:"is_active"`
+	Priority    int    `gorm:"default:0" json:"priority"` // 越大越优先
+
+	// 触发条件，至少配置一项；同时配置时需同时满足
+	ThresholdMinor       int64  `gorm:"type:bigint;default:0" json:"threshold_minor"`                      // 商品原价小计门槛，0 表示不限
+	QualifyingProductIDs []uint `gorm:"type:text;serializer:json" json:"qualifying_product_ids,omitempty"` // 包含任一商品即满足
+
+	// 赠品
+	GiftProductID   uint `gorm:"not null;index" json:"gift_product_id"`
+	GiftInventoryID uint `gorm:"not null;index" json:"gift_inventory_id"` // 赠品扣减的库存
+	GiftQuantity    int  `gorm:"not null;default:1" json:"gift_quantity"`
+
+	StartsAt  *time.Time     `json:"starts_at,omitempty"`
+	EndsAt    *time.Time     `json:"ends_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (GiftPromotion) TableName() string {
+	return "gift_promotions"
+}
+
+// IsRunningAt 活动在指定时间是否生效
+func (p *GiftPromotion) IsRunningAt(now time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// IsQualified 购物车是否满足赠送条件
+func (p *GiftPromotion) IsQualified(subtotalMinor int64, productIDs []uint) bool {
+	if p.ThresholdMinor <= 0 && len(p.QualifyingProductIDs) == 0 {
+		return false
+	}
+	if p.ThresholdMinor > 0 && subtotalMinor < p.ThresholdMinor {
+		return false
+	}
+	if len(p.QualifyingProductIDs) == 0 {
+		return true
+	}
+	for _, id := range productIDs {
+		for _, qualifying := range p.QualifyingProductIDs {
+			if id == qualifying {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+type IncomingTransactionStatus string
+
+const (
+	IncomingTransactionStatusUnmatched IncomingTransactionStatus = "unmatched"
+	IncomingTransactionStatusMatched   IncomingTransactionStatus = "matched"
+	IncomingTransactionStatusIgnored   IncomingTransactionStatus = "ignored"
+)
+
+// 到账流水来源
+const (
+	IncomingTransactionSourceManual = "manual"
+	IncomingTransactionSourceCSV    = "csv"
+	IncomingTransactionSourceScript = "script"
+)
+
+// IncomingTransaction 待人工对账的到账流水（银行转账、链上转账等金额或附言与订单不一致的款项）
+type IncomingTransaction struct {
+	ID              uint                      `gorm:"primaryKey" json:"id"`
+	Channel         string                    `gorm:"type:varchar(50);not null;default:'';uniqueIndex:uidx_incoming_transactions_channel_txn,priority:1" json:"channel"`
+	TransactionID   string                    `gorm:"type:varchar(191);not null;uniqueIndex:uidx_incoming_transactions_channel_txn,priority:2" json:"transaction_id"`
+	PaymentMethodID *uint                     `gorm:"index" json:"payment_method_id,omitempty"`
+	Amount          string                    `gorm:"type:varchar(50);not null" json:"amount"` // 十进制文本，保留原始精度（如 USDT 6 位小数）
+	Currency        string                    `gorm:"type:varchar(10)" json:"currency"`
+	Memo            string                    `gorm:"type:varchar(500)" json:"memo,omitempty"`
+	Payer           string                    `gorm:"type:varchar(200)" json:"payer,omitempty"`
+	ReceivedAt      time.Time                 `gorm:"index" json:"received_at"`
+	Source          string                    `gorm:"type:varchar(20);not null" json:"source"`
+	Status          IncomingTransactionStatus `gorm:"type:varchar(20);not null;default:'unmatched';index" json:"status"`
+
+	MatchedOrderID *uint      `gorm:"index" json:"matched_order_id,omitempty"`
+	MatchedOrderNo string     `gorm:"type:varchar(50)" json:"matched_order_no,omitempty"`
+	HandledBy      *uint      `json:"handled_by,omitempty"` // 匹配或忽略的管理员
+	HandledAt      *time.Time `json:"handled_at,omitempty"`
+	Remark         string     `gorm:"type:varchar(500)" json:"remark,omitempty"`
+
+	CreatedBy *uint     `json:"created_by,omitempty"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (IncomingTransaction) TableName() string {
+	return "incoming_transactions"
+}
@@ -24,6 +24,10 @@ type Inventory struct {
 	AlertEmail        string         `gorm:"type:varchar(255)" json:"alert_email,omitempty"` // Inventory告警Email
 	IsActive          bool           `gorm:"default:true" json:"is_active"`                  // 是否启用
 	Notes             string         `gorm:"type:text" json:"notes,omitempty"`               // 备注
+	WeightGrams       int            `gorm:"default:0" json:"weight_grams"`                  // 规格单件重量（克），0 表示沿用商品
+	LengthMM          int            `gorm:"default:0" json:"length_mm"`                     // 规格尺寸（毫米），0 表示沿用商品
+	WidthMM           int            `gorm:"default:0" json:"width_mm"`
+	HeightMM          int            `gorm:"default:0" json:"height_mm"`
 	CreatedAt         time.Time      `json:"created_at"`
 	UpdatedAt         time.Time      `json:"updated_at"`
 	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
@@ -110,7 +114,7 @@ type InventoryLog struct {
 	Source      string         `gorm:"type:varchar(20);not null;default:'physical';index" json:"source"` // physical(实物库存), virtual(虚拟库存)
 	InventoryID uint           `gorm:"not null;index" json:"inventory_id"`
 	ProductID   uint           `gorm:"not null;index" json:"product_id"`
-	Type        string         `gorm:"type:varchar(20);not null" json:"type"`            // in, out, reserve, release, adjust, import, deliver, delete
+	Type        string         `gorm:"type:varchar(20);not null" json:"type"`            // in, out, reserve, release, adjust, import, deliver, delete, return, revoke, expire, transfer
 	Quantity    int            `gorm:"not null" json:"quantity"`                         // 变动数量（正数或负数）
 	BeforeStock int            `gorm:"not null" json:"before_stock"`                     // 变动前Inventory
 	AfterStock  int            `gorm:"not null" json:"after_stock"`                      // 变动后Inventory
@@ -136,12 +140,16 @@ const (
 
 // Inventory变动类型常量
 const (
-	InventoryLogTypeIn      = "in"      // 入库
-	InventoryLogTypeOut     = "out"     // 出库
-	InventoryLogTypeReserve = "reserve" // 预留
-	InventoryLogTypeRelease = "release" // 释放预留
-	InventoryLogTypeAdjust  = "adjust"  // 调整
-	InventoryLogTypeImport  = "import"  // 导入（虚拟库存）
-	InventoryLogTypeDeliver = "deliver" // 发货（虚拟库存）
-	InventoryLogTypeDelete  = "delete"  // 删除（虚拟库存）
+	InventoryLogTypeIn       = "in"       // 入库
+	InventoryLogTypeOut      = "out"      // 出库
+	InventoryLogTypeReserve  = "reserve"  // 预留
+	InventoryLogTypeRelease  = "release"  // 释放预留
+	InventoryLogTypeAdjust   = "adjust"   // 调整
+	InventoryLogTypeImport   = "import"   // 导入（虚拟库存）
+	InventoryLogTypeDeliver  = "deliver"  // 发货（虚拟库存）
+	InventoryLogTypeDelete   = "delete"   // 删除（虚拟库存）
+	InventoryLogTypeReturn   = "return"   // 退货入库（已售库存回补）
+	InventoryLogTypeRevoke   = "revoke"   // 撤销已发货卡密（虚拟库存）
+	InventoryLogTypeExpire   = "expire"   // 过期失效（虚拟库存）
+	InventoryLogTypeTransfer = "transfer" // 调拨（虚拟库存池之间转移、合并订单时转移预留）
 )
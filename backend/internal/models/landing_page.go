@@ -1,21 +1,59 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// 落地页内容模式
+const (
+	LandingPageModeHTML   = "html"   // 原始 HTML 模板
+	LandingPageModeBlocks = "blocks" // 区块搭建（渲染已发布版本）
+)
+
+// 落地页区块版本状态
+const (
+	LandingRevisionStatusDraft     = "draft"
+	LandingRevisionStatusPublished = "published"
+	LandingRevisionStatusArchived  = "archived"
+)
+
 // LandingPage 落地页
 type LandingPage struct {
+	ID                  uint           `gorm:"primaryKey" json:"id"`
+	Slug                string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"slug"`
+	HTMLContent         string         `gorm:"type:text" json:"html_content"`
+	Mode                string         `gorm:"type:varchar(20);default:'html'" json:"mode"`
+	PublishedRevisionID *uint          `json:"published_revision_id,omitempty"`
+	IsActive            bool           `gorm:"default:true" json:"is_active"`
+	UpdatedBy           uint           `gorm:"default:0" json:"updated_by"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// LandingBlock 落地页区块，Props 结构由 Type 决定
+type LandingBlock struct {
+	ID    string          `json:"id"`
+	Type  string          `json:"type"` // hero | product_grid | faq | announcement_bar
+	Props json.RawMessage `json:"props"`
+}
+
+// LandingPageRevision 区块落地页版本（草稿/已发布/已归档）
+type LandingPageRevision struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
-	Slug        string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"slug"`
-	HTMLContent string         `gorm:"type:text" json:"html_content"`
-	IsActive    bool           `gorm:"default:true" json:"is_active"`
-	UpdatedBy   uint           `gorm:"default:0" json:"updated_by"`
+	PageSlug    string         `gorm:"type:varchar(100);not null;uniqueIndex:idx_landing_revision_version" json:"page_slug"`
+	Version     int            `gorm:"not null;uniqueIndex:idx_landing_revision_version" json:"version"`
+	Blocks      []LandingBlock `gorm:"type:text;serializer:json" json:"blocks"`
+	Status      string         `gorm:"type:varchar(20);not null;default:'draft';index" json:"status"`
+	Note        string         `gorm:"type:varchar(255)" json:"note,omitempty"`
+	CreatedBy   uint           `gorm:"default:0" json:"created_by"`
+	PublishedBy *uint          `json:"published_by,omitempty"`
+	PublishedAt *time.Time     `json:"published_at,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // PageView 页面访问记录（仅追加）
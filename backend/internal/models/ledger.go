@@ -0,0 +1,64 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 财务事件类型
+const (
+	LedgerKindCharge     = "charge"     // 下单应收（优惠前金额）
+	LedgerKindDiscount   = "discount"   // 优惠抵扣
+	LedgerKindAdjustment = "adjustment" // 改价
+	LedgerKindPayment    = "payment"    // 收款
+	LedgerKindRefund     = "refund"     // 退款
+	LedgerKindCredit     = "credit"     // 补偿/赠送给用户的额度
+	LedgerKindVoid       = "void"       // 取消订单冲销未收款的应收
+)
+
+// 记账科目
+const (
+	LedgerAccountCustomer = "customer" // 用户应收（正数表示用户欠款，负数表示应退/应补偿用户）
+	LedgerAccountRevenue  = "revenue"  // 销售收入
+	LedgerAccountDiscount = "discount" // 优惠支出
+	LedgerAccountCash     = "cash"     // 实收资金
+	LedgerAccountRefund   = "refund"   // 退款支出
+	LedgerAccountCredit   = "credit"   // 补偿支出
+)
+
+// ErrLedgerEntryImmutable 账本只允许追加
+var ErrLedgerEntryImmutable = errors.New("ledger entries are append-only")
+
+// LedgerEntry 财务分录（借正贷负），同一 TxnNo 下的分录合计必须为 0
+type LedgerEntry struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	TxnNo       string    `gorm:"type:varchar(40);index;not null" json:"txn_no"`
+	Kind        string    `gorm:"type:varchar(20);index;not null" json:"kind"`
+	Account     string    `gorm:"type:varchar(20);index;not null" json:"account"`
+	AmountMinor int64     `gorm:"type:bigint;not null" json:"amount_minor"`
+	Currency    string    `gorm:"type:varchar(10)" json:"currency"`
+	OrderID     *uint     `gorm:"index" json:"order_id,omitempty"`
+	UserID      *uint     `gorm:"index" json:"user_id,omitempty"`
+	Source      string    `gorm:"type:varchar(50)" json:"source,omitempty"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	CreatedBy   *uint     `json:"created_by,omitempty"`
+	Checksum    string    `gorm:"type:varchar(64);not null" json:"checksum"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (LedgerEntry) TableName() string {
+	return "ledger_entries"
+}
+
+// BeforeUpdate 禁止修改已记账分录
+func (LedgerEntry) BeforeUpdate(tx *gorm.DB) error {
+	return ErrLedgerEntryImmutable
+}
+
+// BeforeDelete 禁止删除已记账分录
+func (LedgerEntry) BeforeDelete(tx *gorm.DB) error {
+	return ErrLedgerEntryImmutable
+}
@@ -50,7 +50,9 @@ type EmailLog struct {
 	Status       EmailLogStatus  `gorm:"type:varchar(20);default:'pending';index" json:"status"`
 	ErrorMessage string          `gorm:"type:text" json:"error_message,omitempty"`
 	RetryCount   int             `gorm:"default:0" json:"retry_count"`
+	Sensitive    bool            `gorm:"default:false" json:"sensitive,omitempty"` // 正文含卡密，发送成功后清空
 	ExpireAt     *time.Time      `gorm:"index" json:"expire_at,omitempty"`
+	AvailableAt  *time.Time      `json:"available_at,omitempty"` // 限流延迟发送的最早时间（无 Redis 时由数据库轮询读取）
 	SentAt       *time.Time      `json:"sent_at,omitempty"`
 	CreatedAt    time.Time       `gorm:"index" json:"created_at"`
 	UpdatedAt    time.Time       `json:"updated_at"`
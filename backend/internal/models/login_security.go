@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// IPBanSource 封禁来源
+const (
+	IPBanSourceManual = "manual"
+	IPBanSourceAuto   = "auto"
+)
+
+// IPBan IP 封禁，ExpiresAt 为空表示永久封禁
+type IPBan struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	IPAddress string     `gorm:"type:varchar(50);uniqueIndex;not null" json:"ip_address"`
+	Reason    string     `gorm:"type:varchar(255)" json:"reason"`
+	Source    string     `gorm:"type:varchar(20);not null" json:"source"`
+	ExpiresAt *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	CreatedBy *uint      `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (IPBan) TableName() string {
+	return "ip_bans"
+}
+
+// IsActive 封禁是否仍然生效
+func (b *IPBan) IsActive(now time.Time) bool {
+	return b.ExpiresAt == nil || b.ExpiresAt.After(now)
+}
+
+// AccountLockout 按邮箱记录的连续登录失败和锁定状态（不存在的账户同样记录，避免泄露账户是否存在）
+type AccountLockout struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Email        string     `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	FailedCount  int        `gorm:"not null;default:0" json:"failed_count"`
+	LockoutCount int        `gorm:"not null;default:0" json:"lockout_count"` // 累计锁定次数，用于递增锁定时长
+	LockedUntil  *time.Time `gorm:"index" json:"locked_until,omitempty"`
+	LastFailedAt *time.Time `json:"last_failed_at,omitempty"`
+	LastFailedIP string     `gorm:"type:varchar(50)" json:"last_failed_ip,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (AccountLockout) TableName() string {
+	return "account_lockouts"
+}
+
+// SecurityEventType 安全事件类型
+const (
+	SecurityEventLoginFailed        = "login_failed"
+	SecurityEventAccountLocked      = "account_locked"
+	SecurityEventAccountUnlocked    = "account_unlocked"
+	SecurityEventLockedLoginBlocked = "locked_login_blocked"
+	SecurityEventIPBanned           = "ip_banned"
+	SecurityEventIPUnbanned         = "ip_unbanned"
+	SecurityEventCredentialStuffing = "credential_stuffing"
+	SecurityEventSuspiciousLogin    = "suspicious_login"
+	SecurityEventAPIKeyIPRejected   = "api_key_ip_rejected" // API Key 从白名单外的 IP 调用
+	SecurityEventAPIKeyNewIP        = "api_key_new_ip"      // API Key 首次从新的 IP 调用
+	SecurityEventScraperBurst       = "scraper_burst"       // 目录接口突发请求超过阈值
+	SecurityEventScraperHoneypot    = "scraper_honeypot"    // 目录请求携带蜜罐参数
+	SecurityEventScraperChallenge   = "scraper_challenge"   // 目录请求反复缺少或伪造 JS 挑战令牌
+)
+
+// SecurityEventSeverity 事件级别
+const (
+	SecuritySeverityInfo     = "info"
+	SecuritySeverityWarning  = "warning"
+	SecuritySeverityCritical = "critical"
+)
+
+// SecurityEvent 安全事件；RequiresReview 的事件需要管理员确认
+type SecurityEvent struct {
+	ID             uint                   `gorm:"primaryKey" json:"id"`
+	EventType      string                 `gorm:"type:varchar(50);index;not null" json:"event_type"`
+	Severity       string                 `gorm:"type:varchar(20);index;not null" json:"severity"`
+	Email          string                 `gorm:"type:varchar(255);index" json:"email,omitempty"`
+	UserID         *uint                  `gorm:"index" json:"user_id,omitempty"`
+	IPAddress      string                 `gorm:"type:varchar(50);index" json:"ip_address,omitempty"`
+	UserAgent      string                 `gorm:"type:text" json:"user_agent,omitempty"`
+	Details        map[string]interface{} `gorm:"type:text;serializer:json" json:"details,omitempty"`
+	RequiresReview bool                   `gorm:"index" json:"requires_review"`
+	ReviewedAt     *time.Time             `json:"reviewed_at,omitempty"`
+	ReviewedBy     *uint                  `json:"reviewed_by,omitempty"`
+	ReviewNote     string                 `gorm:"type:varchar(500)" json:"review_note,omitempty"`
+	CreatedAt      time.Time              `gorm:"index" json:"created_at"`
+}
+
+func (SecurityEvent) TableName() string {
+	return "security_events"
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"auralogic/internal/pkg/piicrypt"
 	"gorm.io/gorm"
 )
 
@@ -22,6 +23,13 @@ const (
 	OrderStatusRefunded       OrderStatus = "refunded"        // 已退款
 )
 
+// 系统自动取消原因（手动取消不记录）
+const (
+	OrderCancelReasonPaymentTimeout = "payment_timeout" // 待付款超时
+	OrderCancelReasonDraftExpired   = "draft_expired"   // 草稿表单过期未填写
+	OrderCancelReasonMerged         = "merged"          // 已合并到同一买家的其他订单发货
+)
+
 type SerialGenerationStatus string
 
 const (
@@ -35,23 +43,40 @@ const (
 
 // OrderItem OrderProduct项
 type OrderItem struct {
-	SKU         string                 `json:"sku"`
-	Name        string                 `json:"name"`
-	Quantity    int                    `json:"quantity"`
-	ImageURL    string                 `json:"image_url,omitempty"`
-	Attributes  map[string]interface{} `json:"attributes,omitempty"`
-	ProductType ProductType            `json:"product_type,omitempty"` // physical(实物), virtual(虚拟)
+	SKU             string                 `json:"sku"`
+	Name            string                 `json:"name"`
+	Quantity        int                    `json:"quantity"`
+	ImageURL        string                 `json:"image_url,omitempty"`
+	Attributes      map[string]interface{} `json:"attributes,omitempty"`
+	ProductType     ProductType            `json:"product_type,omitempty"`     // physical(实物), virtual(虚拟)
+	UnitPriceMinor  int64                  `json:"unit_price_minor,omitempty"` // 下单时单价快照，旧订单为 0
+	DiscountMinor   int64                  `json:"discount_minor,omitempty"`   // 分摊到该项的优惠/改价金额
+	LineTotalMinor  int64                  `json:"line_total_minor,omitempty"` // 行实付金额 = 单价 × 数量 - 分摊优惠
+	IsGift          bool                   `json:"is_gift,omitempty"`          // 满赠活动赠品，由服务端添加
+	GiftPromotionID *uint                  `json:"gift_promotion_id,omitempty"`
+}
+
+// Subtotal 行原价小计（单价 × 数量）
+func (item OrderItem) Subtotal() int64 {
+	return item.UnitPriceMinor * int64(item.Quantity)
 }
 
 // Order Order模型
 type Order struct {
 	ID      uint   `gorm:"primaryKey" json:"id"`
 	OrderNo string `gorm:"type:varchar(50);uniqueIndex;not null" json:"order_no"`
-	UserID  *uint  `gorm:"index" json:"user_id,omitempty"`
+	UserID  *uint  `gorm:"index;index:idx_orders_user_status,priority:1" json:"user_id,omitempty"`
 	User    *User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
 
+	// 下单时所在店铺（为空表示默认店铺/单店铺部署）
+	StoreID *uint `gorm:"index" json:"store_id,omitempty"`
+
 	// OrderInfo
 	Items []OrderItem `gorm:"type:text;serializer:json;not null" json:"items"`
+	// 实物商品净重合计（克），下单时按规格/商品重量快照
+	TotalWeightGrams int `gorm:"default:0" json:"total_weight_grams,omitempty"`
+	// 已退货数量（Key: Order项索引），用于防止重复回补库存
+	ReturnedQuantities map[int]int `gorm:"type:text;serializer:json" json:"returned_quantities,omitempty"`
 
 	// 实际分配的属性（盲盒模式）
 	ActualAttributes JSON `gorm:"type:json" json:"actual_attributes,omitempty"` // 盲盒Product实际分配的属性
@@ -65,19 +90,28 @@ type Order struct {
 	VirtualInventoryBindings map[int]uint `gorm:"type:text;serializer:json" json:"-"`
 
 	// 状态
-	Status OrderStatus `gorm:"type:varchar(30);not null;default:'draft';index" json:"status"`
-
-	// 收货Info
-	ReceiverName     string `gorm:"type:varchar(100)" json:"receiver_name,omitempty"`
+	// (status, created_at) 用于后台按状态分页；(user_id, status) 用于用户订单列表
+	Status OrderStatus `gorm:"type:varchar(30);not null;default:'draft';index;index:idx_orders_status_created,priority:1;index:idx_orders_user_status,priority:2" json:"status"`
+	// 自定义子状态，仅在设置时的核心状态下有效，核心状态变化后自动失效
+	SubStatus    string      `gorm:"type:varchar(50);index" json:"sub_status,omitempty"`
+	SubStatusFor OrderStatus `gorm:"type:varchar(30)" json:"-"`
+	// 标签（管理员或自动化规则添加）
+	Tags []string `gorm:"type:text;serializer:json" json:"tags,omitempty"`
+
+	// 收货Info（姓名/电话/邮箱/详细地址在启用 PII 加密时以密文存储）
+	ReceiverName     string `gorm:"type:text;serializer:pii" json:"receiver_name,omitempty"`
 	PhoneCode        string `gorm:"type:varchar(10);default:'+86'" json:"phone_code,omitempty"` // 手机区号
-	ReceiverPhone    string `gorm:"type:varchar(50)" json:"receiver_phone,omitempty"`
-	ReceiverEmail    string `gorm:"type:varchar(255)" json:"receiver_email,omitempty"`
+	ReceiverPhone    string `gorm:"type:text;serializer:pii" json:"receiver_phone,omitempty"`
+	ReceiverEmail    string `gorm:"type:text;serializer:pii" json:"receiver_email,omitempty"`
 	ReceiverCountry  string `gorm:"type:varchar(100);default:'CN'" json:"receiver_country,omitempty"` // 收货国家代码
 	ReceiverProvince string `gorm:"type:varchar(50)" json:"receiver_province,omitempty"`
 	ReceiverCity     string `gorm:"type:varchar(50)" json:"receiver_city,omitempty"`
 	ReceiverDistrict string `gorm:"type:varchar(50)" json:"receiver_district,omitempty"`
-	ReceiverAddress  string `gorm:"type:text" json:"receiver_address,omitempty"`
+	ReceiverAddress  string `gorm:"type:text;serializer:pii" json:"receiver_address,omitempty"`
 	ReceiverPostcode string `gorm:"type:varchar(20)" json:"receiver_postcode,omitempty"`
+	// 邮箱/电话盲索引（HMAC），加密后仍可精确查找
+	ReceiverEmailIndex string `gorm:"column:receiver_email_bidx;type:varchar(64);index" json:"-"`
+	ReceiverPhoneIndex string `gorm:"column:receiver_phone_bidx;type:varchar(64);index" json:"-"`
 
 	// 隐私保护
 	PrivacyProtected bool `gorm:"default:false" json:"privacy_protected"`
@@ -87,6 +121,7 @@ type Order struct {
 	ShippedAt    *time.Time `json:"shipped_at,omitempty"`
 	CompletedAt  *time.Time `json:"completed_at,omitempty"`
 	CompletedBy  *uint      `json:"completed_by,omitempty"`
+	CancelReason string     `gorm:"type:varchar(50);index" json:"cancel_reason,omitempty"`
 	UserFeedback string     `gorm:"type:text" json:"user_feedback,omitempty"`
 
 	// 序列号异步生成状态
@@ -94,6 +129,12 @@ type Order struct {
 	SerialGenerationError  string                 `gorm:"type:text" json:"serial_generation_error,omitempty"`
 	SerialGeneratedAt      *time.Time             `json:"serial_generated_at,omitempty"`
 
+	// 待付款截止时间（按商品/付款方式的自动取消时限计算），为空时按全局 auto_cancel_hours
+	PaymentDeadlineAt *time.Time `gorm:"index" json:"payment_deadline_at,omitempty"`
+
+	// 通过沙箱付款方式下单/付款的测试订单，不计入营收统计
+	IsSandbox bool `gorm:"default:false;index" json:"is_sandbox"`
+
 	// 表单访问Token
 	FormToken       *string    `gorm:"type:varchar(255);uniqueIndex" json:"form_token,omitempty"`
 	FormSubmittedAt *time.Time `json:"form_submitted_at,omitempty"`
@@ -112,22 +153,25 @@ type Order struct {
 	TotalAmount int64  `gorm:"type:bigint;default:0" json:"-"`
 	Currency    string `gorm:"type:varchar(10);default:'CNY'" json:"currency"`
 
-	// 备注
-	Remark      string `gorm:"type:text" json:"remark,omitempty"`
-	AdminRemark string `gorm:"type:text" json:"admin_remark,omitempty"`
+	// 备注（管理员内部备注见 OrderNote）
+	Remark string `gorm:"type:text" json:"remark,omitempty"`
 
 	// 来源
 	Source           string `gorm:"type:varchar(50);default:'api'" json:"source"`
-	SourcePlatform   string `gorm:"type:varchar(100)" json:"source_platform,omitempty"`
+	SourcePlatform   string `gorm:"type:varchar(100);index:idx_orders_external_order,priority:1" json:"source_platform,omitempty"`
 	ExternalUserID   string `gorm:"type:varchar(100);index" json:"external_user_id,omitempty"`
 	ExternalUserName string `gorm:"type:varchar(100)" json:"external_user_name,omitempty"` // 第三方平台的User名
-	ExternalOrderID  string `gorm:"type:varchar(100)" json:"external_order_id,omitempty"`
+	ExternalOrderID  string `gorm:"type:varchar(100);index:idx_orders_external_order,priority:2" json:"external_order_id,omitempty"`
 
 	// 分配Info
 	AssignedTo *uint      `json:"assigned_to,omitempty"`
 	AssignedAt *time.Time `json:"assigned_at,omitempty"`
 
-	CreatedAt time.Time      `json:"created_at"`
+	// 合并发货：本单商品已并入的目标订单（本单随之关闭）
+	MergedIntoOrderID *uint  `gorm:"index" json:"merged_into_order_id,omitempty"`
+	MergedIntoOrderNo string `gorm:"type:varchar(50)" json:"merged_into_order_no,omitempty"`
+
+	CreatedAt time.Time      `gorm:"index:idx_orders_status_created,priority:2" json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
@@ -137,6 +181,21 @@ func (Order) TableName() string {
 	return "orders"
 }
 
+// BeforeSave 同步收件邮箱/电话的盲索引
+func (o *Order) BeforeSave(tx *gorm.DB) error {
+	o.ReceiverEmailIndex = piicrypt.EmailIndex(o.ReceiverEmail)
+	o.ReceiverPhoneIndex = piicrypt.PhoneIndex(o.ReceiverPhone)
+	return nil
+}
+
+// AfterFind 核心状态已变化的子状态不再返回
+func (o *Order) AfterFind(tx *gorm.DB) error {
+	if o.SubStatus != "" && o.SubStatusFor != o.Status {
+		o.SubStatus = ""
+	}
+	return nil
+}
+
 func (o Order) MarshalJSON() ([]byte, error) {
 	type Alias Order
 	return json.Marshal(&struct {
@@ -150,17 +209,17 @@ func (o Order) MarshalJSON() ([]byte, error) {
 	})
 }
 
-// MaskSensitiveInfo 打码敏感Info
+// MaskSensitiveInfo 按内置默认策略打码敏感Info（仅隐私保护订单）
 func (o *Order) MaskSensitiveInfo() {
-	if !o.PrivacyProtected {
-		return
-	}
+	DefaultFieldMaskRules().ApplyToOrder(o)
+}
 
-	o.ReceiverName = "***"
-	if len(o.ReceiverPhone) > 7 {
-		o.ReceiverPhone = o.ReceiverPhone[:3] + "****" + o.ReceiverPhone[len(o.ReceiverPhone)-4:]
+// HasItemPricing 订单项是否带有价格快照（旧订单没有）
+func (o *Order) HasItemPricing() bool {
+	for _, item := range o.Items {
+		if item.UnitPriceMinor != 0 || item.LineTotalMinor != 0 || item.DiscountMinor != 0 {
+			return true
+		}
 	}
-	// Email不再打码，保持原样显示
-	// 保留省市区，详细Address打码
-	o.ReceiverAddress = "***"
+	return false
 }
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// OrderAutomationTrigger 自动化规则触发事件
+type OrderAutomationTrigger string
+
+const (
+	OrderAutomationTriggerOrderCreated  OrderAutomationTrigger = "order.created"        // 用户下单
+	OrderAutomationTriggerOrderPaid     OrderAutomationTrigger = "order.paid"           // 待付款订单付款成功
+	OrderAutomationTriggerStatusChanged OrderAutomationTrigger = "order.status_changed" // 任意核心状态变化
+)
+
+// OrderAutomationActionType 自动化动作类型
+type OrderAutomationActionType string
+
+const (
+	OrderAutomationActionAddTag       OrderAutomationActionType = "add_tag"
+	OrderAutomationActionRemoveTag    OrderAutomationActionType = "remove_tag"
+	OrderAutomationActionSetSubStatus OrderAutomationActionType = "set_sub_status"      // 例如挂起：设置 on_hold 子状态
+	OrderAutomationActionAdminRemark  OrderAutomationActionType = "append_admin_remark" // 追加一条系统订单备注
+	OrderAutomationActionNotifySlack  OrderAutomationActionType = "notify_slack"
+	OrderAutomationActionWebhook      OrderAutomationActionType = "webhook"
+)
+
+// OrderAutomationCondition 单个条件：field operator value
+type OrderAutomationCondition struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// OrderAutomationAction 单个动作，按类型使用对应字段
+type OrderAutomationAction struct {
+	Type      OrderAutomationActionType `json:"type"`
+	Tag       string                    `json:"tag,omitempty"`        // add_tag / remove_tag
+	SubStatus string                    `json:"sub_status,omitempty"` // set_sub_status
+	URL       string                    `json:"url,omitempty"`        // notify_slack / webhook
+	Message   string                    `json:"message,omitempty"`    // append_admin_remark / notify_slack，支持 {{order_no}} 等占位符
+}
+
+// OrderAutomationRule 订单自动化规则：事件触发 → 条件匹配 → 依次执行动作
+type OrderAutomationRule struct {
+	ID             uint                       `gorm:"primaryKey" json:"id"`
+	Name           string                     `gorm:"type:varchar(100);not null" json:"name"`
+	Description    string                     `gorm:"type:text" json:"description,omitempty"`
+	Trigger        OrderAutomationTrigger     `gorm:"column:trigger_event;type:varchar(50);not null;index" json:"trigger"`
+	Enabled        bool                       `gorm:"index" json:"enabled"`
+	MatchType      string                     `gorm:"type:varchar(10);not null;default:'all'" json:"match_type"` // all / any
+	Conditions     []OrderAutomationCondition `gorm:"type:text;serializer:json" json:"conditions"`
+	Actions        []OrderAutomationAction    `gorm:"type:text;serializer:json" json:"actions"`
+	Priority       int                        `gorm:"default:0" json:"priority"` // 越大越先执行
+	StopProcessing bool                       `json:"stop_processing"`           // 命中后不再执行后续规则
+	RunCount       int64                      `gorm:"default:0" json:"run_count"`
+	LastRunAt      *time.Time                 `json:"last_run_at,omitempty"`
+	CreatedBy      *uint                      `json:"created_by,omitempty"`
+	CreatedAt      time.Time                  `json:"created_at"`
+	UpdatedAt      time.Time                  `json:"updated_at"`
+}
+
+func (OrderAutomationRule) TableName() string {
+	return "order_automation_rules"
+}
+
+// OrderAutomationActionResult 单个动作执行结果
+type OrderAutomationActionResult struct {
+	Type    OrderAutomationActionType `json:"type"`
+	Success bool                      `json:"success"`
+	Detail  string                    `json:"detail,omitempty"`
+	Error   string                    `json:"error,omitempty"`
+}
+
+// OrderAutomationRun 规则命中后的执行日志（未命中不记录）
+type OrderAutomationRun struct {
+	ID         uint                          `gorm:"primaryKey" json:"id"`
+	RuleID     uint                          `gorm:"not null;index" json:"rule_id"`
+	RuleName   string                        `gorm:"type:varchar(100)" json:"rule_name"`
+	OrderID    uint                          `gorm:"not null;index" json:"order_id"`
+	OrderNo    string                        `gorm:"type:varchar(50)" json:"order_no"`
+	Trigger    OrderAutomationTrigger        `gorm:"column:trigger_event;type:varchar(50)" json:"trigger"`
+	Status     string                        `gorm:"type:varchar(20);index" json:"status"` // success / partial / failed
+	Results    []OrderAutomationActionResult `gorm:"type:text;serializer:json" json:"results"`
+	DurationMs int64                         `json:"duration_ms"`
+	CreatedAt  time.Time                     `gorm:"index" json:"created_at"`
+}
+
+func (OrderAutomationRun) TableName() string {
+	return "order_automation_runs"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// OrderExternalRef 外部平台订单号的占用记录，(source_platform, external_order_id) 唯一，
+// 用于拒绝同一外部订单重复创建草稿；允许重复建单的平台不写入
+type OrderExternalRef struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	SourcePlatform  string    `gorm:"type:varchar(100);not null;default:'';uniqueIndex:idx_order_external_refs_platform_order,priority:1" json:"source_platform"`
+	ExternalOrderID string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_order_external_refs_platform_order,priority:2" json:"external_order_id"`
+	OrderID         uint      `gorm:"not null;index" json:"order_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (OrderExternalRef) TableName() string {
+	return "order_external_refs"
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+type OrderImportJobStatus string
+
+const (
+	OrderImportJobStatusQueued    OrderImportJobStatus = "queued"
+	OrderImportJobStatusRunning   OrderImportJobStatus = "running"
+	OrderImportJobStatusCompleted OrderImportJobStatus = "completed"
+	OrderImportJobStatusFailed    OrderImportJobStatus = "failed"
+)
+
+// OrderImportRowError 批量导入订单的行级错误
+type OrderImportRowError struct {
+	Row      int    `json:"row"`
+	OrderRef string `json:"order_ref,omitempty"`
+	OrderNo  string `json:"order_no,omitempty"` // 订单已创建但后续付款/发货步骤失败时返回
+	Message  string `json:"message"`
+}
+
+// OrderImportJob 后台批量导入订单任务（CSV/XLSX 每行一个订单项，按 order_ref 分组成订单）
+type OrderImportJob struct {
+	ID       uint                 `gorm:"primaryKey" json:"id"`
+	JobNo    string               `gorm:"type:varchar(64);uniqueIndex;not null" json:"job_no"`
+	Filename string               `gorm:"type:varchar(255)" json:"filename"`
+	Status   OrderImportJobStatus `gorm:"type:varchar(20);not null;default:'queued';index" json:"status"`
+
+	// 原始表格（含表头），任务执行时解析，重启后可继续
+	Header []string   `gorm:"type:text;serializer:json" json:"-"`
+	Rows   [][]string `gorm:"type:text;serializer:json" json:"-"`
+
+	NotifyCustomers bool `gorm:"default:false" json:"notify_customers"`
+
+	TotalRows       int                   `gorm:"default:0" json:"total_rows"`
+	TotalOrders     int                   `gorm:"default:0" json:"total_orders"`
+	ProcessedOrders int                   `gorm:"default:0" json:"processed_orders"`
+	CreatedCount    int                   `gorm:"default:0" json:"created_count"`
+	SkippedCount    int                   `gorm:"default:0" json:"skipped_count"`
+	ErrorCount      int                   `gorm:"default:0" json:"error_count"`
+	RowErrors       []OrderImportRowError `gorm:"type:text;serializer:json" json:"row_errors,omitempty"`
+	FailedReason    string                `gorm:"type:text" json:"failed_reason,omitempty"`
+
+	OperatorID   *uint  `gorm:"index" json:"operator_id,omitempty"`
+	OperatorName string `gorm:"type:varchar(100)" json:"operator_name,omitempty"`
+
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (OrderImportJob) TableName() string {
+	return "order_import_jobs"
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// OrderMessage 订单留言（用户就某个订单联系卖家，无需正式工单）
+type OrderMessage struct {
+	ID      uint `gorm:"primaryKey" json:"id"`
+	OrderID uint `gorm:"index;not null" json:"order_id"`
+
+	// 发送者信息
+	SenderType string `gorm:"type:varchar(20);not null" json:"sender_type"` // user/admin/system
+	SenderID   uint   `gorm:"index" json:"sender_id"`
+	SenderName string `gorm:"type:varchar(100)" json:"sender_name"`
+
+	Content string `gorm:"type:text;not null" json:"content"`
+
+	// 升级为工单后记录的系统消息会关联工单，此后留言只读
+	TicketID *uint   `gorm:"index" json:"ticket_id,omitempty"`
+	Ticket   *Ticket `gorm:"foreignKey:TicketID" json:"ticket,omitempty"`
+
+	// 已读状态
+	IsReadByUser  bool `gorm:"default:false" json:"is_read_by_user"`
+	IsReadByAdmin bool `gorm:"default:false" json:"is_read_by_admin"`
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+func (OrderMessage) TableName() string {
+	return "order_messages"
+}
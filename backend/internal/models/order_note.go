@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// OrderNoteSource 订单备注来源（manual 为管理员手写，其余为系统在对应操作时自动记录）
+const (
+	OrderNoteSourceManual        = "manual"
+	OrderNoteSourceCreate        = "create"
+	OrderNoteSourceMarkPaid      = "mark_paid"
+	OrderNoteSourceComplete      = "complete"
+	OrderNoteSourceResubmit      = "resubmit"
+	OrderNoteSourceCancel        = "cancel"
+	OrderNoteSourceAutoCancel    = "auto_cancel"
+	OrderNoteSourceRefund        = "refund"
+	OrderNoteSourceRefundConfirm = "refund_confirm"
+	OrderNoteSourceReturn        = "return"
+	OrderNoteSourceVirtualRevoke = "virtual_revoke"
+	OrderNoteSourceAutomation    = "automation"
+	OrderNoteSourceCODCollection = "cod_collection"
+	OrderNoteSourceAutoComplete  = "auto_complete"
+	OrderNoteSourceMerge         = "merge"
+	OrderNoteSourceLegacy        = "legacy" // 迁移自旧的 orders.admin_remark 字段
+)
+
+// OrderNote 订单内部备注（仅管理员可见），一个订单可以有多条备注，支持置顶和 @ 提及其他管理员
+type OrderNote struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	OrderID    uint      `gorm:"index;not null" json:"order_id"`
+	AuthorID   *uint     `gorm:"index" json:"author_id,omitempty"` // 为空表示系统自动记录
+	Source     string    `gorm:"type:varchar(30);default:'manual'" json:"source"`
+	Content    string    `gorm:"type:text;not null" json:"content"`
+	Pinned     bool      `gorm:"default:false;index" json:"pinned"`
+	MentionIDs []uint    `gorm:"type:text;serializer:json" json:"mention_ids,omitempty"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	Author *User `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+}
+
+func (OrderNote) TableName() string {
+	return "order_notes"
+}
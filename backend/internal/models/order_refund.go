@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+type OrderRefundStatus string
+
+const (
+	OrderRefundStatusProcessing OrderRefundStatus = "processing" // 已占用额度，正在调用付款方式退款
+	OrderRefundStatusPending    OrderRefundStatus = "pending"    // 付款方式已受理，待人工确认到账
+	OrderRefundStatusSucceeded  OrderRefundStatus = "succeeded"
+	OrderRefundStatusFailed     OrderRefundStatus = "failed"
+)
+
+// OrderRefundItem 退款明细行，按订单项下标对应
+type OrderRefundItem struct {
+	ItemIndex   int    `json:"item_index"`
+	SKU         string `json:"sku,omitempty"`
+	Quantity    int    `json:"quantity"`
+	AmountMinor int64  `json:"amount_minor"`
+}
+
+// OrderRefund 订单退款记录，一个订单可有多笔部分退款，累计金额不超过订单实付金额
+type OrderRefund struct {
+	ID            uint              `gorm:"primaryKey" json:"id"`
+	OrderID       uint              `gorm:"index;not null" json:"order_id"`
+	OrderNo       string            `gorm:"type:varchar(50);index" json:"order_no"`
+	Status        OrderRefundStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	AmountMinor   int64             `gorm:"not null" json:"amount_minor"`
+	Currency      string            `gorm:"type:varchar(10)" json:"currency"`
+	Items         []OrderRefundItem `gorm:"type:text;serializer:json" json:"items,omitempty"`
+	Full          bool              `json:"full"` // 本笔退款后订单已全额退款
+	Reason        string            `gorm:"type:varchar(500)" json:"reason,omitempty"`
+	Source        string            `gorm:"type:varchar(50)" json:"source,omitempty"`
+	TransactionID string            `gorm:"type:varchar(255)" json:"transaction_id,omitempty"`
+	Message       string            `gorm:"type:text" json:"message,omitempty"`
+	AdminID       *uint             `json:"admin_id,omitempty"`
+	CompletedAt   *time.Time        `json:"completed_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (OrderRefund) TableName() string {
+	return "order_refunds"
+}
+
+// CountsTowardRefunded 处理中、待确认与已成功的退款都占用可退额度
+func (r *OrderRefund) CountsTowardRefunded() bool {
+	return r.Status != OrderRefundStatusFailed
+}
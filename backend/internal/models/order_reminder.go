@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// 订单提醒类型
+const (
+	OrderReminderKindPaymentDue = "payment_due" // 待付款即将自动取消
+)
+
+// OrderReminder 已发送的订单提醒记录，(order_id, kind) 唯一以避免重复提醒
+type OrderReminder struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	OrderID     uint      `gorm:"not null;uniqueIndex:idx_order_reminders_order_kind,priority:1" json:"order_id"`
+	Kind        string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_order_reminders_order_kind,priority:2" json:"kind"`
+	DeadlineAt  time.Time `json:"deadline_at"`  // 发送时订单的截止时间
+	EmailQueued bool      `json:"email_queued"` // 是否已加入邮件队列（未开启邮件或用户关闭通知时为 false）
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
+
+func (OrderReminder) TableName() string {
+	return "order_reminders"
+}
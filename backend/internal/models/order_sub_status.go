@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// OrderSubStatus 管理员自定义的订单子状态（如 awaiting_engraving、customs_hold），挂在某个核心状态下
+type OrderSubStatus struct {
+	ID             uint        `gorm:"primaryKey" json:"id"`
+	Code           string      `gorm:"type:varchar(50);uniqueIndex;not null" json:"code"`
+	Status         OrderStatus `gorm:"type:varchar(30);not null;index" json:"status"` // 所属核心状态
+	Name           string      `gorm:"type:varchar(100);not null" json:"name"`
+	Description    string      `gorm:"type:text" json:"description,omitempty"`
+	Color          string      `gorm:"type:varchar(20)" json:"color,omitempty"`
+	SortOrder      int         `gorm:"default:0" json:"sort_order"`
+	NotifyCustomer bool        `json:"notify_customer"` // 设置该子状态时发送 order_sub_status 邮件
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+func (OrderSubStatus) TableName() string {
+	return "order_sub_statuses"
+}
+
+// IsKnownOrderStatus 是否为系统内置的订单核心状态
+func IsKnownOrderStatus(status OrderStatus) bool {
+	switch status {
+	case OrderStatusPendingPayment, OrderStatusDraft, OrderStatusPending, OrderStatusNeedResubmit,
+		OrderStatusShipped, OrderStatusCompleted, OrderStatusCancelled, OrderStatusRefundPending, OrderStatusRefunded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// PaymentAmountReservation 待付款订单占用的唯一付款金额，用于链上转账等按金额匹配到账的付款方式。
+// 金额以 10^-decimals 为单位存储，(scope, amount_units) 唯一；订单取消、确认付款或过期后释放。
+type PaymentAmountReservation struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	OrderID         uint      `gorm:"not null;uniqueIndex:uidx_payment_amount_reservations_order_pm,priority:1" json:"order_id"`
+	PaymentMethodID uint      `gorm:"not null;uniqueIndex:uidx_payment_amount_reservations_order_pm,priority:2" json:"payment_method_id"`
+	Scope           string    `gorm:"type:varchar(191);not null;uniqueIndex:uidx_payment_amount_reservations_scope_amount,priority:1" json:"scope"` // 唯一性范围，如网络+收款地址
+	AmountUnits     int64     `gorm:"not null;uniqueIndex:uidx_payment_amount_reservations_scope_amount,priority:2" json:"amount_units"`
+	Decimals        int       `gorm:"not null" json:"decimals"`
+	Offset          int64     `gorm:"not null" json:"offset"` // 相对基础金额的尾数偏移
+	ExpiresAt       time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (PaymentAmountReservation) TableName() string {
+	return "payment_amount_reservations"
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,6 +17,9 @@ const (
 	PaymentMethodTypeCustom  PaymentMethodType = "custom"  // 自定义JS付款方式
 )
 
+// PaymentProviderStripe 原生 Stripe 集成（不经过 JS 脚本）
+const PaymentProviderStripe = "stripe"
+
 // PaymentMethod 付款方式模型
 type PaymentMethod struct {
 	ID              uint              `gorm:"primaryKey" json:"id"`
@@ -31,8 +37,18 @@ type PaymentMethod struct {
 	Manifest        string            `gorm:"type:text" json:"manifest"`                    // 导入包 manifest.json 原文
 	SortOrder       int               `gorm:"default:0" json:"sort_order"`                  // 排序顺序
 	PollInterval    int               `gorm:"default:30" json:"poll_interval"`              // 轮询检查间隔(秒)，默认30秒
-	CreatedAt       time.Time         `json:"created_at"`
-	UpdatedAt       time.Time         `json:"updated_at"`
+	AutoCancelHours int               `gorm:"default:0" json:"auto_cancel_hours"`           // 选择该方式后的未付款自动取消时限(小时)，0表示不覆盖
+	StoreID         *uint             `gorm:"index" json:"store_id,omitempty"`              // 所属店铺(为空表示所有店铺可用)
+	Sandbox         bool              `gorm:"default:false" json:"sandbox"`                 // 沙箱/测试模式：订单标记为测试单，可由管理员模拟付款
+	CashOnDelivery  bool              `gorm:"default:false" json:"cash_on_delivery"`        // 货到付款：选择后订单直接转为待发货，由承运商代收
+	Provider        string            `gorm:"size:30;index" json:"provider"`                // 原生集成提供方(stripe)，为空表示由 JS 脚本处理
+	// 脚本出站 HTTP 允许访问的主机（支持 *.example.com），为空时由全局严格模式决定是否放行
+	AllowedHosts []string `gorm:"type:text;serializer:json" json:"allowed_hosts"`
+	// 订单标签规则：RequiredOrderTags 需全部命中、ExcludedOrderTags 命中任一即不可用，均为空表示不限制
+	RequiredOrderTags []string  `gorm:"type:text;serializer:json" json:"required_order_tags"`
+	ExcludedOrderTags []string  `gorm:"type:text;serializer:json" json:"excluded_order_tags"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 // TableName 指定表名
@@ -53,6 +69,74 @@ func (pm *PaymentMethod) BeforeUpdate(tx *gorm.DB) error {
 	return nil
 }
 
+// NormalizePaymentMethodAllowedHosts 校验并规范化出站主机白名单（小写、去重，不含协议/端口/路径）
+func NormalizePaymentMethodAllowedHosts(entries []string) ([]string, error) {
+	normalized := make([]string, 0, len(entries))
+	seen := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		host := strings.TrimPrefix(entry, "*.")
+		if host == "" || strings.ContainsAny(host, "/:*?#@ ") || strings.HasPrefix(host, ".") || strings.HasSuffix(host, ".") {
+			return nil, fmt.Errorf("invalid host: %s", entry)
+		}
+		if host != entry && net.ParseIP(host) != nil {
+			return nil, fmt.Errorf("invalid host: %s", entry)
+		}
+		if _, exists := seen[entry]; exists {
+			continue
+		}
+		seen[entry] = struct{}{}
+		normalized = append(normalized, entry)
+	}
+	return normalized, nil
+}
+
+// AllowsHost 主机是否命中白名单，*.example.com 仅匹配子域名
+func (pm *PaymentMethod) AllowsHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if host == "" {
+		return false
+	}
+	for _, entry := range pm.AllowedHosts {
+		if suffix, ok := strings.CutPrefix(entry, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+			continue
+		}
+		if host == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsOrderTags 订单标签是否满足付款方式的标签规则（不区分大小写）
+func (pm *PaymentMethod) AllowsOrderTags(tags []string) bool {
+	hasTag := func(target string) bool {
+		for _, tag := range tags {
+			if strings.EqualFold(tag, target) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, required := range pm.RequiredOrderTags {
+		if !hasTag(required) {
+			return false
+		}
+	}
+	for _, excluded := range pm.ExcludedOrderTags {
+		if hasTag(excluded) {
+			return false
+		}
+	}
+	return true
+}
+
 // OrderPaymentMethod 订单选择的付款方式
 type OrderPaymentMethod struct {
 	ID               uint       `gorm:"primaryKey" json:"id"`
@@ -103,17 +187,47 @@ var BuiltinPaymentMethods = []PaymentMethod{
  * - wallet_address: 收款钱包地址 (必填)
  * - trongrid_api_key: TronGrid API Key (推荐，提高查询频率限制)
  * - usdt_contract: USDT合约地址 (默认: TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t)
- * - cny_rate: CNY兑USDT汇率 (默认: 7.2)
+ * - cny_rate: CNY兑USDT汇率，仅在汇率服务未启用或不可用时使用 (默认: 7.2)
  * - amount_tolerance: 金额容差 (默认: 0.0000005 USDT，需小于订单最小差值0.000001)
  * - auto_confirm: 是否自动确认 (默认: true)
  */
 
+/**
+ * 订单金额换算为USDT：优先使用汇率服务，不可用时回退到 cny_rate 配置
+ */
+function toUsdtAmount(order, config) {
+    var amount = (order.total_amount_minor || 0) / 100;
+    if (order.currency && order.currency !== 'USD' && order.currency !== 'USDT' && AuraLogic.rates) {
+        var quote = AuraLogic.rates.get(order.currency, 'USDT');
+        if (quote && !quote.error && quote.rate > 0) {
+            return amount * quote.rate;
+        }
+    }
+    if (order.currency === 'CNY') {
+        return amount / (parseFloat(config.cny_rate) || 7.2);
+    }
+    return amount;
+}
+
+/**
+ * 分配唯一付款金额：优先使用服务端分配，失败时回退到 order.id % 10000 的尾数
+ */
+function reserveUsdtAmount(order, usdtAmount, scope) {
+    if (AuraLogic.payment) {
+        var reserved = AuraLogic.payment.reserveAmount(usdtAmount, { scope: scope, decimals: 6 });
+        if (reserved && !reserved.error) {
+            return reserved.amount;
+        }
+    }
+    var randomCents = (parseInt(order.id) % 10000) / 1000000;
+    return ((Math.floor(usdtAmount * 100) / 100) + randomCents).toFixed(6);
+}
+
 /**
  * 生成付款卡片
  */
 function onGeneratePaymentCard(order, config) {
     var walletAddress = config.wallet_address || '';
-    var cnyRate = parseFloat(config.cny_rate) || 7.2;
 
     if (!walletAddress) {
         return {
@@ -131,23 +245,15 @@ function onGeneratePaymentCard(order, config) {
         };
     }
 
-    // 计算USDT金额
-    var usdtAmount = (order.total_amount_minor || 0) / 100;
-    if (order.currency === 'CNY') {
-        usdtAmount = ((order.total_amount_minor || 0) / 100) / cnyRate;
-    } else if (order.currency === 'USD') {
-        usdtAmount = (order.total_amount_minor || 0) / 100;
-    }
-    // 保留2位小数，加上基于订单ID的偏移量区分并发订单（USDT支持6位小数）
-    // order.id % 10000 产生 0~9999 的偏移值，除以 1000000 得到 0.000000~0.009999 的尾数
-    // 最大支持 10000 笔同金额订单同时待支付
-    var randomCents = (parseInt(order.id) % 10000) / 1000000;
-    usdtAmount = (Math.floor(usdtAmount * 100) / 100) + randomCents;
-    usdtAmount = usdtAmount.toFixed(6);
+    // 计算USDT金额，保留2位小数并由服务端分配同一收款地址内唯一的4位尾数区分并发订单
+    var usdtAmount = reserveUsdtAmount(order, toUsdtAmount(order, config), 'usdt-trc20:' + walletAddress);
 
-    // 保存订单信息到storage
+    // 保存订单信息到storage，已生成过的订单沿用首次报价，避免汇率波动导致应付金额变化
     var orderKey = 'order_' + order.id;
-    if (!AuraLogic.storage.get(orderKey + '_amount')) {
+    var savedAmount = AuraLogic.storage.get(orderKey + '_amount');
+    if (savedAmount) {
+        usdtAmount = savedAmount;
+    } else {
         AuraLogic.storage.set(orderKey + '_amount', usdtAmount);
         AuraLogic.storage.set(orderKey + '_time', AuraLogic.system.getTimestamp().toString());
         AuraLogic.storage.set(orderKey + '_address', walletAddress);
@@ -371,22 +477,23 @@ function onCheckPaymentStatus(order, config) {
  * 退款处理
  * USDT为去中心化加密货币，无法自动退款，需要管理员手动转账
  */
-function onRefund(order, config) {
+function onRefund(order, config, refund) {
     var orderKey = 'order_' + order.id;
     var savedAmount = AuraLogic.storage.get(orderKey + '_amount') || '';
 
     // If storage was cleaned up after payment confirmation, recalculate USDT amount
     if (!savedAmount) {
-        var cnyRate = parseFloat(config.cny_rate) || 7.2;
-        var usdtAmount = (order.total_amount_minor || 0) / 100;
-        if (order.currency === 'CNY') {
-            usdtAmount = ((order.total_amount_minor || 0) / 100) / cnyRate;
-        }
+        var usdtAmount = toUsdtAmount(order, config);
         var randomCents = (parseInt(order.id) % 10000) / 1000000;
         usdtAmount = (Math.floor(usdtAmount * 100) / 100) + randomCents;
         savedAmount = usdtAmount.toFixed(6);
     }
 
+    // 部分退款按金额比例折算 USDT
+    if (refund && order.total_amount_minor > 0 && refund.amount_minor < order.total_amount_minor) {
+        savedAmount = (parseFloat(savedAmount) * refund.amount_minor / order.total_amount_minor).toFixed(6);
+    }
+
     return {
         success: true,
         pending: true,
@@ -417,17 +524,47 @@ function onRefund(order, config) {
  * - wallet_address: 收款钱包地址 (必填)
  * - bscscan_api_key: BSCScan API Key (必填，免费申请: https://bscscan.com/myapikey)
  * - usdt_contract: BSC USDT合约地址 (默认: 0x55d398326f99059fF775485246999027B3197955)
- * - cny_rate: CNY兑USDT汇率 (默认: 7.2)
+ * - cny_rate: CNY兑USDT汇率，仅在汇率服务未启用或不可用时使用 (默认: 7.2)
  * - amount_tolerance: 金额容差 (默认: 0.0000005 USDT，需小于订单最小差值0.000001)
  * - auto_confirm: 是否自动确认 (默认: true)
  */
 
+/**
+ * 订单金额换算为USDT：优先使用汇率服务，不可用时回退到 cny_rate 配置
+ */
+function toUsdtAmount(order, config) {
+    var amount = (order.total_amount_minor || 0) / 100;
+    if (order.currency && order.currency !== 'USD' && order.currency !== 'USDT' && AuraLogic.rates) {
+        var quote = AuraLogic.rates.get(order.currency, 'USDT');
+        if (quote && !quote.error && quote.rate > 0) {
+            return amount * quote.rate;
+        }
+    }
+    if (order.currency === 'CNY') {
+        return amount / (parseFloat(config.cny_rate) || 7.2);
+    }
+    return amount;
+}
+
+/**
+ * 分配唯一付款金额：优先使用服务端分配，失败时回退到 order.id % 10000 的尾数
+ */
+function reserveUsdtAmount(order, usdtAmount, scope) {
+    if (AuraLogic.payment) {
+        var reserved = AuraLogic.payment.reserveAmount(usdtAmount, { scope: scope, decimals: 6 });
+        if (reserved && !reserved.error) {
+            return reserved.amount;
+        }
+    }
+    var randomCents = (parseInt(order.id) % 10000) / 1000000;
+    return ((Math.floor(usdtAmount * 100) / 100) + randomCents).toFixed(6);
+}
+
 /**
  * 生成付款卡片
  */
 function onGeneratePaymentCard(order, config) {
     var walletAddress = config.wallet_address || '';
-    var cnyRate = parseFloat(config.cny_rate) || 7.2;
 
     if (!walletAddress) {
         return {
@@ -445,21 +582,15 @@ function onGeneratePaymentCard(order, config) {
         };
     }
 
-    // 计算USDT金额
-    var usdtAmount = (order.total_amount_minor || 0) / 100;
-    if (order.currency === 'CNY') {
-        usdtAmount = ((order.total_amount_minor || 0) / 100) / cnyRate;
-    } else if (order.currency === 'USD') {
-        usdtAmount = (order.total_amount_minor || 0) / 100;
-    }
-    // 保留2位小数，加上基于订单ID的偏移量区分并发订单（USDT支持18位小数）
-    var randomCents = (parseInt(order.id) % 10000) / 1000000;
-    usdtAmount = (Math.floor(usdtAmount * 100) / 100) + randomCents;
-    usdtAmount = usdtAmount.toFixed(6);
+    // 计算USDT金额，保留2位小数并由服务端分配同一收款地址内唯一的4位尾数区分并发订单
+    var usdtAmount = reserveUsdtAmount(order, toUsdtAmount(order, config), 'usdt-bep20:' + walletAddress.toLowerCase());
 
-    // 保存订单信息到storage
+    // 保存订单信息到storage，已生成过的订单沿用首次报价，避免汇率波动导致应付金额变化
     var orderKey = 'order_' + order.id;
-    if (!AuraLogic.storage.get(orderKey + '_amount')) {
+    var savedAmount = AuraLogic.storage.get(orderKey + '_amount');
+    if (savedAmount) {
+        usdtAmount = savedAmount;
+    } else {
         AuraLogic.storage.set(orderKey + '_amount', usdtAmount);
         AuraLogic.storage.set(orderKey + '_time', AuraLogic.system.getTimestamp().toString());
         AuraLogic.storage.set(orderKey + '_address', walletAddress);
@@ -706,22 +837,23 @@ function onCheckPaymentStatus(order, config) {
  * 退款处理
  * USDT为去中心化加密货币，无法自动退款，需要管理员手动转账
  */
-function onRefund(order, config) {
+function onRefund(order, config, refund) {
     var orderKey = 'order_' + order.id;
     var savedAmount = AuraLogic.storage.get(orderKey + '_amount') || '';
 
     // If storage was cleaned up after payment confirmation, recalculate USDT amount
     if (!savedAmount) {
-        var cnyRate = parseFloat(config.cny_rate) || 7.2;
-        var usdtAmount = (order.total_amount_minor || 0) / 100;
-        if (order.currency === 'CNY') {
-            usdtAmount = ((order.total_amount_minor || 0) / 100) / cnyRate;
-        }
+        var usdtAmount = toUsdtAmount(order, config);
         var randomCents = (parseInt(order.id) % 10000) / 1000000;
         usdtAmount = (Math.floor(usdtAmount * 100) / 100) + randomCents;
         savedAmount = usdtAmount.toFixed(6);
     }
 
+    // 部分退款按金额比例折算 USDT
+    if (refund && order.total_amount_minor > 0 && refund.amount_minor < order.total_amount_minor) {
+        savedAmount = (parseFloat(savedAmount) * refund.amount_minor / order.total_amount_minor).toFixed(6);
+    }
+
     return {
         success: true,
         pending: true,
@@ -733,6 +865,43 @@ function onRefund(order, config) {
         }
     };
 }
+`,
+	},
+	{
+		Name:           "Cash on Delivery",
+		Description:    "Pay in cash when the parcel is delivered",
+		Type:           PaymentMethodTypeCustom,
+		Icon:           "Banknote",
+		SortOrder:      3,
+		Enabled:        false,
+		CashOnDelivery: true,
+		Config:         `{"instructions":""}`,
+		Script: `
+/**
+ * 货到付款脚本
+ * 选择后订单直接转为待发货，款项由承运商派送时代收，管理员在后台登记代收与回款
+ */
+function onGeneratePaymentCard(order, config) {
+    var amount = AuraLogic.utils.formatPrice(order.total_amount_minor || 0, order.currency);
+    var instructions = (config && config.instructions) || '';
+    var html = '<div class="space-y-3">' +
+        '<div class="p-4 bg-muted rounded-lg">' +
+        '<p class="text-sm text-muted-foreground">Pay on delivery / 货到付款</p>' +
+        '<p class="text-2xl font-bold mt-1">' + amount + '</p>' +
+        '</div>' +
+        '<p class="text-sm text-muted-foreground">Please prepare the exact amount in cash for the courier. 请在签收时向快递员支付现金。</p>' +
+        (instructions ? '<p class="text-sm">' + instructions + '</p>' : '') +
+        '</div>';
+
+    return {
+        html: html,
+        title: 'Cash on Delivery'
+    };
+}
+
+function onCheckPaymentStatus(order, config) {
+    return { paid: false, message: 'Collected on delivery' };
+}
 `,
 	},
 }
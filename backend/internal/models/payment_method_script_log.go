@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// PaymentScriptLogEntry 单条 console 输出
+type PaymentScriptLogEntry struct {
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// PaymentMethodScriptLog 付款方式脚本单次执行的 console 输出与结果
+// 仅在脚本产生输出或执行失败时记录，每个付款方式只保留最近若干条
+type PaymentMethodScriptLog struct {
+	ID              uint                    `gorm:"primaryKey" json:"id"`
+	PaymentMethodID uint                    `gorm:"not null;index:idx_payment_method_script_logs_pm_created_at,priority:1" json:"payment_method_id"`
+	Callback        string                  `gorm:"size:50;not null" json:"callback"`
+	OrderID         *uint                   `gorm:"index" json:"order_id,omitempty"`
+	Success         bool                    `gorm:"not null" json:"success"`
+	Error           string                  `gorm:"type:text" json:"error,omitempty"`
+	Entries         []PaymentScriptLogEntry `gorm:"type:text;serializer:json" json:"entries"`
+	Truncated       bool                    `gorm:"not null;default:false" json:"truncated"`
+	DurationMs      int64                   `json:"duration_ms"`
+	CreatedAt       time.Time               `gorm:"index:idx_payment_method_script_logs_pm_created_at,priority:2" json:"created_at"`
+}
+
+func (PaymentMethodScriptLog) TableName() string {
+	return "payment_method_script_logs"
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"auralogic/internal/pkg/piicrypt"
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("pii", PIISerializer{})
+}
+
+// PIISerializer 字符串字段的透明加解密（gorm:"serializer:pii"）。
+// 注意：Updates(map) 不经过序列化器，加密字段必须通过结构体写入
+type PIISerializer struct{}
+
+func (PIISerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var raw string
+	switch value := dbValue.(type) {
+	case nil:
+	case string:
+		raw = value
+	case []byte:
+		raw = string(value)
+	default:
+		return fmt.Errorf("unsupported pii column value %T", dbValue)
+	}
+	plain, err := piicrypt.Decrypt(raw)
+	if err != nil {
+		return fmt.Errorf("decrypt %s: %w", field.DBName, err)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plain)
+	return nil
+}
+
+func (PIISerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plain, _ := fieldValue.(string)
+	if piicrypt.IsEncrypted(plain) {
+		return plain, nil
+	}
+	return piicrypt.Encrypt(plain)
+}
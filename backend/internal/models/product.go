@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -40,6 +41,15 @@ const (
 	InventoryModeRandom InventoryMode = "random" // 盲盒模式：系统随机分配
 )
 
+// EmailDeliveryMode 虚拟商品发货后付款/发货邮件中的交付方式
+type EmailDeliveryMode string
+
+const (
+	EmailDeliveryModeNone   EmailDeliveryMode = "none"   // 邮件仅提示登录查看（默认）
+	EmailDeliveryModeInline EmailDeliveryMode = "inline" // 邮件直接附带卡密内容
+	EmailDeliveryModeLink   EmailDeliveryMode = "link"   // 邮件附带有时效的签名取件链接
+)
+
 // ProductImage Product图片
 type ProductImage struct {
 	URL       string `json:"url"`
@@ -47,6 +57,12 @@ type ProductImage struct {
 	IsPrimary bool   `json:"is_primary"`
 }
 
+// ProductPriceTier 阶梯价档位：购买数量达到 MinQuantity 时单价为 PriceMinor
+type ProductPriceTier struct {
+	MinQuantity int   `json:"min_quantity"`
+	PriceMinor  int64 `json:"price_minor"`
+}
+
 // AttributeMode 属性模式
 type AttributeMode string
 
@@ -72,6 +88,9 @@ type Product struct {
 	Name        string `gorm:"type:varchar(255);not null" json:"name"`
 	ProductCode string `gorm:"type:varchar(20);index" json:"product_code,omitempty"` // 产品码，用于生成防伪序列号
 
+	// 所属店铺（为空表示所有店铺共享）
+	StoreID *uint `gorm:"index" json:"store_id,omitempty"`
+
 	// 商品类型
 	ProductType ProductType `gorm:"type:varchar(20);not null;default:'physical';index" json:"product_type"` // physical(实物), virtual(虚拟)
 
@@ -91,6 +110,19 @@ type Product struct {
 	// 购买限制
 	MaxPurchaseLimit int `gorm:"default:0" json:"max_purchase_limit,omitempty"` // 每个账户最大购买数量，0表示不限制
 
+	// 单笔订单数量规则（0 表示不限制）：每个订单项至少 MinOrderQuantity 件且为 QuantityIncrement 的整数倍，
+	// 同一订单内该商品合计不超过 MaxOrderQuantity
+	MinOrderQuantity  int `gorm:"default:0" json:"min_order_quantity,omitempty"`
+	MaxOrderQuantity  int `gorm:"default:0" json:"max_order_quantity,omitempty"`
+	QuantityIncrement int `gorm:"default:0" json:"quantity_increment,omitempty"`
+
+	// 阶梯批发价（按单笔订单中该商品总数量取档），为空表示统一按 Price 计价
+	PriceTiers []ProductPriceTier `gorm:"type:text;serializer:json" json:"price_tiers,omitempty"`
+
+	// 询价模式（B2B）：隐藏价格，购买改为提交询价单；PriceVisibleTiers 内的客户等级仍可见价并直接下单
+	PriceOnRequest    bool     `gorm:"default:false" json:"price_on_request"`
+	PriceVisibleTiers []string `gorm:"type:text;serializer:json" json:"price_visible_tiers,omitempty"`
+
 	// 图片
 	Images []ProductImage `gorm:"type:text;serializer:json" json:"images,omitempty"`
 
@@ -113,18 +145,82 @@ type Product struct {
 	// 备注
 	Remark string `gorm:"type:text" json:"remark,omitempty"`
 
+	// SEO（为空时回退到名称/简介/主图）
+	MetaTitle       string `gorm:"type:varchar(255)" json:"meta_title,omitempty"`
+	MetaDescription string `gorm:"type:varchar(500)" json:"meta_description,omitempty"`
+	OGImage         string `gorm:"type:varchar(500)" json:"og_image,omitempty"`
+
 	// Inventory模式
 	InventoryMode string `gorm:"type:varchar(20);default:'fixed'" json:"inventory_mode"` // fixed(固定), random(盲盒/随机)
 
 	// 虚拟商品自动发货
 	AutoDelivery bool `gorm:"default:false" json:"auto_delivery"` // 虚拟商品是否自动发货
 
+	// 邮件交付方式：敏感商品保持 none，仅在站内查看
+	EmailDeliveryMode EmailDeliveryMode `gorm:"type:varchar(20);default:'none'" json:"email_delivery_mode"`
+
+	// 加购即预留：加入购物车时短时占用库存，下单时转为订单预留，超时自动释放（热门抢购用）
+	ReserveOnCart bool `gorm:"default:false" json:"reserve_on_cart"`
+
+	// 抢购排队：开启等候室时，下单前需排队取得放行凭证
+	WaitingRoom bool `gorm:"default:false" json:"waiting_room"`
+
+	// 发货时效（付款/填写收货信息后多少天内发货，0 表示使用全局默认值）
+	ShipWithinDays int `gorm:"default:0" json:"ship_within_days"`
+
+	// 未付款自动取消时限（小时，0 表示使用全局 auto_cancel_hours），订单含多个商品时取最短
+	AutoCancelHours int `gorm:"default:0" json:"auto_cancel_hours"`
+
+	// 配送限制（ISO 国家代码）：允许列表非空时只可寄往列表内国家，禁运列表优先
+	ShippingAllowedCountries []string `gorm:"type:text;serializer:json" json:"shipping_allowed_countries,omitempty"`
+	ShippingBlockedCountries []string `gorm:"type:text;serializer:json" json:"shipping_blocked_countries,omitempty"`
+
+	// 报关信息（国际件 CN22/CN23）
+	HSCode             string `gorm:"type:varchar(20)" json:"hs_code,omitempty"`
+	DeclaredValueMinor int64  `gorm:"type:bigint;default:0" json:"declared_value_minor"` // 单件申报价值，0 表示按成交价申报
+	WeightGrams        int    `gorm:"default:0" json:"weight_grams"`                     // 单件净重（克）
+	OriginCountry      string `gorm:"type:varchar(2)" json:"origin_country,omitempty"`   // 原产国 ISO 代码
+
+	// 包装尺寸（毫米），用于装箱建议；0 表示未设置
+	LengthMM int `gorm:"default:0" json:"length_mm"`
+	WidthMM  int `gorm:"default:0" json:"width_mm"`
+	HeightMM int `gorm:"default:0" json:"height_mm"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// 关联
 	InventoryBindings []ProductInventoryBinding `gorm:"foreignKey:ProductID" json:"inventory_bindings,omitempty"`
+
+	// 公开商品详情附带的 SEO 元数据（不落库）
+	SEO *ProductSEO `gorm:"-" json:"seo,omitempty"`
+
+	// 按用户展示币种换算的参考价（不落库）
+	DisplayPrice *ProductDisplayPrice `gorm:"-" json:"display_price,omitempty"`
+
+	// 当前访客看不到价格（询价模式），前台改为展示询价入口（不落库）
+	PriceHidden bool `gorm:"-" json:"price_hidden,omitempty"`
+}
+
+// ProductDisplayPrice 商品价格按汇率换算的约数，仅供展示，下单仍按原币种结算
+type ProductDisplayPrice struct {
+	Currency           string    `json:"currency"`
+	PriceMinor         int64     `json:"price_minor"`
+	OriginalPriceMinor int64     `json:"original_price_minor"`
+	Rate               float64   `json:"rate"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	Stale              bool      `json:"stale"`
+	Approximate        bool      `json:"approximate"`
+}
+
+// ProductSEO 商品页 SEO 元数据与 JSON-LD 结构化数据
+type ProductSEO struct {
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description,omitempty"`
+	Image        string                 `json:"image,omitempty"`
+	CanonicalURL string                 `json:"canonical_url"`
+	JSONLD       map[string]interface{} `json:"json_ld"`
 }
 
 // TableName 指定表名
@@ -158,6 +254,48 @@ func (p *Product) GetPrimaryImage() string {
 	return ""
 }
 
+// UnitPriceForQuantity 按购买数量返回适用单价：取满足数量的最高档位，未达到任何档位时使用基础价；
+// 定时调价可能让基础价低于档位价，此时以基础价为准
+func (p *Product) UnitPriceForQuantity(quantity int) int64 {
+	price := p.Price
+	bestMin := 0
+	for _, tier := range p.PriceTiers {
+		if tier.MinQuantity > bestMin && quantity >= tier.MinQuantity {
+			bestMin = tier.MinQuantity
+			price = tier.PriceMinor
+		}
+	}
+	if price > p.Price {
+		return p.Price
+	}
+	return price
+}
+
+// PriceHiddenFor 询价模式下，不在可见价等级内的客户（含未登录访客，tier 为空）看不到价格也不能直接下单
+func (p *Product) PriceHiddenFor(customerTier string) bool {
+	if !p.PriceOnRequest {
+		return false
+	}
+	if customerTier == "" {
+		return true
+	}
+	for _, tier := range p.PriceVisibleTiers {
+		if strings.EqualFold(tier, customerTier) {
+			return false
+		}
+	}
+	return true
+}
+
+// HidePrice 清空面向客户的价格信息（价格、阶梯价、换算参考价）并标记为询价
+func (p *Product) HidePrice() {
+	p.Price = 0
+	p.OriginalPrice = 0
+	p.PriceTiers = nil
+	p.DisplayPrice = nil
+	p.PriceHidden = true
+}
+
 // IsAvailable 判断Product是否可购买
 func (p *Product) IsAvailable() bool {
 	return p.Status == ProductStatusActive && p.Stock > 0
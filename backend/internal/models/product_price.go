@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// 价格变更来源
+const (
+	PriceChangeSourceManual    = "manual"    // 后台编辑商品
+	PriceChangeSourceScheduled = "scheduled" // 定时调价生效
+)
+
+// ProductPriceHistory 商品价格变更记录
+type ProductPriceHistory struct {
+	ID                    uint      `gorm:"primaryKey" json:"id"`
+	ProductID             uint      `gorm:"index;not null" json:"product_id"`
+	OldPriceMinor         int64     `gorm:"type:bigint;not null" json:"old_price_minor"`
+	NewPriceMinor         int64     `gorm:"type:bigint;not null" json:"new_price_minor"`
+	OldOriginalPriceMinor int64     `gorm:"type:bigint;default:0" json:"old_original_price_minor"`
+	NewOriginalPriceMinor int64     `gorm:"type:bigint;default:0" json:"new_original_price_minor"`
+	Source                string    `gorm:"type:varchar(20);not null" json:"source"`
+	ChangedBy             *uint     `gorm:"index" json:"changed_by,omitempty"`
+	PriceScheduleID       *uint     `json:"price_schedule_id,omitempty"`
+	CreatedAt             time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ProductPriceHistory) TableName() string {
+	return "product_price_histories"
+}
+
+// PriceScheduleStatus 定时调价状态
+type PriceScheduleStatus string
+
+const (
+	PriceScheduleStatusPending   PriceScheduleStatus = "pending"   // 等待生效
+	PriceScheduleStatusApplied   PriceScheduleStatus = "applied"   // 已生效
+	PriceScheduleStatusCancelled PriceScheduleStatus = "cancelled" // 已取消
+	PriceScheduleStatusFailed    PriceScheduleStatus = "failed"    // 生效失败（如商品已删除）
+)
+
+// ProductPriceSchedule 商品定时调价
+type ProductPriceSchedule struct {
+	ID                 uint                `gorm:"primaryKey" json:"id"`
+	ProductID          uint                `gorm:"index;not null" json:"product_id"`
+	PriceMinor         int64               `gorm:"type:bigint;not null" json:"price_minor"`
+	OriginalPriceMinor *int64              `gorm:"type:bigint" json:"original_price_minor,omitempty"` // 为空表示不修改划线价
+	EffectiveAt        time.Time           `gorm:"index;not null" json:"effective_at"`
+	Status             PriceScheduleStatus `gorm:"type:varchar(20);index;not null;default:'pending'" json:"status"`
+	AppliedAt          *time.Time          `json:"applied_at,omitempty"`
+	Error              string              `gorm:"type:text" json:"error,omitempty"`
+	CreatedBy          *uint               `json:"created_by,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ProductPriceSchedule) TableName() string {
+	return "product_price_schedules"
+}
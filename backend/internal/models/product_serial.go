@@ -18,6 +18,8 @@ type ProductSerial struct {
 	ViewCount      int       `gorm:"default:0" json:"view_count"`                         // 查看次数
 	FirstViewedAt  *time.Time `json:"first_viewed_at,omitempty"`                          // 首次查看时间
 	LastViewedAt   *time.Time `json:"last_viewed_at,omitempty"`                           // 最后查看时间
+	InvalidatedAt  *time.Time `gorm:"index" json:"invalidated_at,omitempty"`               // 退货作废时间，作废后仍可查询但提示失效
+	InvalidReason  string     `gorm:"size:255" json:"invalid_reason,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
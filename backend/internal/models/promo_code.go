@@ -45,6 +45,12 @@ type PromoCode struct {
 	Status    PromoCodeStatus `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
 	ExpiresAt *time.Time      `json:"expires_at,omitempty"`
 
+	// 批量生成的一次性优惠码所属活动
+	CampaignID *uint `gorm:"index" json:"campaign_id,omitempty"`
+
+	// 公开优惠码：结算时可推荐给用户或自动应用
+	AutoApply bool `gorm:"default:false;index" json:"auto_apply"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -87,6 +93,11 @@ func (p *PromoCode) GetAvailableQuantity() int {
 	return available
 }
 
+// IsExhausted 限量优惠码是否已无剩余名额（含预留）
+func (p *PromoCode) IsExhausted() bool {
+	return p.TotalQuantity > 0 && p.GetAvailableQuantity() <= 0
+}
+
 func (p *PromoCode) IsAvailable() bool {
 	if p.Status != PromoCodeStatusActive {
 		return false
@@ -94,7 +105,7 @@ func (p *PromoCode) IsAvailable() bool {
 	if p.IsExpired() {
 		return false
 	}
-	if p.TotalQuantity > 0 && p.GetAvailableQuantity() <= 0 {
+	if p.IsExhausted() {
 		return false
 	}
 	return true
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PromoCodeCampaign 批量生成的一次性优惠码活动（赠品、达人推广等）
+// 活动下的每个优惠码都是 TotalQuantity=1 的独立 PromoCode
+type PromoCodeCampaign struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"type:varchar(255);not null" json:"name"`
+	Description string `gorm:"type:text" json:"description,omitempty"`
+	Prefix      string `gorm:"type:varchar(20)" json:"prefix,omitempty"`
+	Pattern     string `gorm:"type:varchar(50)" json:"pattern,omitempty"` // # 数字, ? 字母, * 字母或数字
+	CodeCount   int    `gorm:"not null;default:0" json:"code_count"`
+
+	DiscountType   DiscountType `gorm:"type:varchar(20);not null" json:"discount_type"`
+	DiscountValue  int64        `gorm:"type:bigint;not null;default:0" json:"-"`
+	MaxDiscount    int64        `gorm:"type:bigint;default:0" json:"-"`
+	MinOrderAmount int64        `gorm:"type:bigint;default:0" json:"-"`
+	ProductIDs     []uint       `gorm:"type:text;serializer:json" json:"product_ids,omitempty"`
+	ProductScope   string       `gorm:"type:varchar(20);default:'all'" json:"product_scope"`
+	ExpiresAt      *time.Time   `json:"expires_at,omitempty"`
+
+	CreatedBy *uint     `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (PromoCodeCampaign) TableName() string {
+	return "promo_code_campaigns"
+}
+
+func (p PromoCodeCampaign) MarshalJSON() ([]byte, error) {
+	type Alias PromoCodeCampaign
+	return json.Marshal(&struct {
+		Alias
+		DiscountValueMinor  int64 `json:"discount_value_minor"`
+		MaxDiscountMinor    int64 `json:"max_discount_minor"`
+		MinOrderAmountMinor int64 `json:"min_order_amount_minor"`
+	}{
+		Alias:               Alias(p),
+		DiscountValueMinor:  p.DiscountValue,
+		MaxDiscountMinor:    p.MaxDiscount,
+		MinOrderAmountMinor: p.MinOrderAmount,
+	})
+}
+
+type PromoCodeRedemptionStatus string
+
+const (
+	PromoCodeRedemptionReserved PromoCodeRedemptionStatus = "reserved" // 下单预留
+	PromoCodeRedemptionRedeemed PromoCodeRedemptionStatus = "redeemed" // 付款后核销
+	PromoCodeRedemptionReleased PromoCodeRedemptionStatus = "released" // 订单取消释放
+)
+
+// PromoCodeRedemption 优惠码使用记录（按订单）
+type PromoCodeRedemption struct {
+	ID          uint                      `gorm:"primaryKey" json:"id"`
+	PromoCodeID uint                      `gorm:"index;not null" json:"promo_code_id"`
+	OrderNo     string                    `gorm:"type:varchar(50);index" json:"order_no"`
+	Status      PromoCodeRedemptionStatus `gorm:"type:varchar(20);index;not null" json:"status"`
+	RedeemedAt  *time.Time                `json:"redeemed_at,omitempty"`
+	ReleasedAt  *time.Time                `json:"released_at,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+func (PromoCodeRedemption) TableName() string {
+	return "promo_code_redemptions"
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// QuoteRequestStatus 询价单状态
+type QuoteRequestStatus string
+
+const (
+	QuoteRequestStatusPending  QuoteRequestStatus = "pending"  // 待报价
+	QuoteRequestStatusQuoted   QuoteRequestStatus = "quoted"   // 已报价，等待客户确认
+	QuoteRequestStatusAccepted QuoteRequestStatus = "accepted" // 客户已接受报价
+	QuoteRequestStatusDeclined QuoteRequestStatus = "declined" // 客户拒绝报价
+	QuoteRequestStatusClosed   QuoteRequestStatus = "closed"   // 已关闭（客户撤回或商家婉拒）
+)
+
+// QuoteRequest 询价单：询价模式商品由客户提交数量与需求，商家报价后客户确认
+type QuoteRequest struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	QuoteNo string `gorm:"type:varchar(50);uniqueIndex;not null" json:"quote_no"`
+	UserID  uint   `gorm:"index;not null" json:"user_id"`
+	User    *User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+
+	// 商品快照（商品改名或删除后仍可追溯）
+	ProductID   uint    `gorm:"index;not null" json:"product_id"`
+	ProductSKU  string  `gorm:"type:varchar(100)" json:"product_sku"`
+	ProductName string  `gorm:"type:varchar(255)" json:"product_name"`
+	Quantity    int     `gorm:"not null" json:"quantity"`
+	Attributes  JSONMap `gorm:"type:text" json:"attributes,omitempty"`
+
+	// 客户需求与联系方式
+	Message      string `gorm:"type:text" json:"message,omitempty"`
+	Company      string `gorm:"type:varchar(200)" json:"company,omitempty"`
+	ContactName  string `gorm:"type:varchar(100)" json:"contact_name,omitempty"`
+	ContactEmail string `gorm:"type:varchar(255)" json:"contact_email,omitempty"`
+	ContactPhone string `gorm:"type:varchar(50)" json:"contact_phone,omitempty"`
+
+	Status QuoteRequestStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+
+	// 商家报价
+	QuotedUnitPriceMinor int64      `gorm:"type:bigint;default:0" json:"quoted_unit_price_minor"`
+	Currency             string     `gorm:"type:varchar(10)" json:"currency,omitempty"`
+	AdminReply           string     `gorm:"type:text" json:"admin_reply,omitempty"`
+	ValidUntil           *time.Time `json:"valid_until,omitempty"` // 报价有效期，过期后客户不能再接受
+	QuotedBy             *uint      `json:"quoted_by,omitempty"`
+	QuotedAt             *time.Time `json:"quoted_at,omitempty"`
+	RespondedAt          *time.Time `json:"responded_at,omitempty"` // 客户接受/拒绝时间
+
+	CreatedAt time.Time      `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (QuoteRequest) TableName() string {
+	return "quote_requests"
+}
+
+// QuotedTotalMinor 报价总额
+func (q *QuoteRequest) QuotedTotalMinor() int64 {
+	return q.QuotedUnitPriceMinor * int64(q.Quantity)
+}
+
+// IsQuoteExpiredAt 报价是否已过有效期
+func (q *QuoteRequest) IsQuoteExpiredAt(now time.Time) bool {
+	return q.ValidUntil != nil && now.After(*q.ValidUntil)
+}
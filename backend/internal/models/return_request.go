@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReturnRequestStatus 退货申请（RMA）状态
+type ReturnRequestStatus string
+
+const (
+	ReturnRequestStatusPending   ReturnRequestStatus = "pending"   // 待审核
+	ReturnRequestStatusApproved  ReturnRequestStatus = "approved"  // 已同意，等待客户寄回
+	ReturnRequestStatusRejected  ReturnRequestStatus = "rejected"  // 已拒绝
+	ReturnRequestStatusReceived  ReturnRequestStatus = "received"  // 已收到退货并回补库存
+	ReturnRequestStatusCancelled ReturnRequestStatus = "cancelled" // 客户撤回
+)
+
+// ReturnRequestItem 申请退货的订单项（含商品快照）
+type ReturnRequestItem struct {
+	ItemIndex int    `json:"item_index"`
+	SKU       string `json:"sku"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+}
+
+// ReturnRequest 退货申请：客户对已发货订单发起，管理员审核后生成退货单号，收货后回补库存
+type ReturnRequest struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	RMANo   string `gorm:"column:rma_no;type:varchar(50);uniqueIndex;not null" json:"rma_no"`
+	OrderID uint   `gorm:"index;not null" json:"order_id"`
+	OrderNo string `gorm:"type:varchar(50);index" json:"order_no"`
+	UserID  uint   `gorm:"index;not null" json:"user_id"`
+	User    *User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	StoreID *uint  `gorm:"index" json:"store_id,omitempty"`
+
+	Items  []ReturnRequestItem `gorm:"type:text;serializer:json;not null" json:"items"`
+	Reason string              `gorm:"type:text" json:"reason"`
+	// 照片保存为 uploads/returns 下的相对路径，对外通过签名链接访问
+	Photos    []string `gorm:"type:text;serializer:json" json:"-"`
+	PhotoURLs []string `gorm:"-" json:"photo_urls,omitempty"`
+
+	Status            ReturnRequestStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	AdminRemark       string              `gorm:"type:text" json:"admin_remark,omitempty"`
+	ReturnTrackingNo  string              `gorm:"type:varchar(50);index" json:"return_tracking_no,omitempty"` // 审核通过后生成，客户寄回时注明
+	ReviewedBy        *uint               `json:"reviewed_by,omitempty"`
+	ReviewedAt        *time.Time          `json:"reviewed_at,omitempty"`
+	ReceivedBy        *uint               `json:"received_by,omitempty"`
+	ReceivedAt        *time.Time          `json:"received_at,omitempty"`
+	RestockedQuantity int                 `gorm:"default:0" json:"restocked_quantity"`
+
+	CreatedAt time.Time      `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (ReturnRequest) TableName() string {
+	return "return_requests"
+}
+
+// IsOpen 待审核或已同意（尚未收货）的申请仍占用订单的退货名额
+func (r *ReturnRequest) IsOpen() bool {
+	return r.Status == ReturnRequestStatusPending || r.Status == ReturnRequestStatusApproved
+}
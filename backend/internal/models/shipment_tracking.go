@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// ShipmentTrackingStatus 物流轨迹状态（由各服务商的状态归一化而来）
+type ShipmentTrackingStatus string
+
+const (
+	ShipmentTrackingStatusPending        ShipmentTrackingStatus = "pending"          // 已注册，暂无轨迹
+	ShipmentTrackingStatusInTransit      ShipmentTrackingStatus = "in_transit"       // 运输中
+	ShipmentTrackingStatusOutForDelivery ShipmentTrackingStatus = "out_for_delivery" // 派送中或待自提
+	ShipmentTrackingStatusDelivered      ShipmentTrackingStatus = "delivered"        // 已签收
+	ShipmentTrackingStatusException      ShipmentTrackingStatus = "exception"        // 派送失败、退回或过期
+)
+
+// ShipmentTrackingEvent 单条物流轨迹
+type ShipmentTrackingEvent struct {
+	Time        time.Time `json:"time"`
+	Status      string    `json:"status,omitempty"`
+	Description string    `json:"description"`
+	Location    string    `json:"location,omitempty"`
+}
+
+// ShipmentTracking 订单物流轨迹，每个订单一条，运单号变更时重置
+type ShipmentTracking struct {
+	ID           uint                    `gorm:"primaryKey" json:"id"`
+	OrderID      uint                    `gorm:"not null;uniqueIndex" json:"order_id"`
+	TrackingNo   string                  `gorm:"type:varchar(100);not null;index" json:"tracking_no"`
+	Carrier      string                  `gorm:"type:varchar(100)" json:"carrier,omitempty"` // 服务商识别出的承运商
+	Provider     string                  `gorm:"type:varchar(30);not null" json:"provider"`
+	Status       ShipmentTrackingStatus  `gorm:"type:varchar(30);not null;default:'pending';index" json:"status"`
+	Events       []ShipmentTrackingEvent `gorm:"type:text;serializer:json" json:"events"`
+	Registered   bool                    `gorm:"not null;default:false" json:"registered"`
+	LastError    string                  `gorm:"type:text" json:"last_error,omitempty"`
+	LastSyncedAt *time.Time              `json:"last_synced_at,omitempty"`
+	NextSyncAt   *time.Time              `gorm:"index" json:"next_sync_at,omitempty"`
+	DeliveredAt  *time.Time              `gorm:"index" json:"delivered_at,omitempty"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+func (ShipmentTracking) TableName() string {
+	return "shipment_trackings"
+}
+
+// IsFinal 已签收的轨迹不再轮询
+func (t *ShipmentTracking) IsFinal() bool {
+	return t.Status == ShipmentTrackingStatusDelivered
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ShippingRestrictionBlock 因商品配送限制被拦截的下单/填单尝试，按 商品 + 国家 + 来源 聚合计数
+type ShippingRestrictionBlock struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ProductID   uint      `gorm:"uniqueIndex:idx_shipping_block_key;not null" json:"product_id"`
+	SKU         string    `gorm:"type:varchar(100);index" json:"sku"`
+	ProductName string    `gorm:"type:varchar(255)" json:"product_name"`
+	Country     string    `gorm:"type:varchar(10);uniqueIndex:idx_shipping_block_key;not null" json:"country"`
+	Source      string    `gorm:"type:varchar(20);uniqueIndex:idx_shipping_block_key;not null" json:"source"` // form | admin
+	Count       int64     `gorm:"not null;default:1" json:"count"`
+	LastOrderNo string    `gorm:"type:varchar(50)" json:"last_order_no,omitempty"`
+	LastUserID  *uint     `json:"last_user_id,omitempty"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `gorm:"index" json:"last_seen_at"`
+}
+
+func (ShippingRestrictionBlock) TableName() string {
+	return "shipping_restriction_blocks"
+}
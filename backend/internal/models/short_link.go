@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// 短链目标类型
+const (
+	ShortLinkTypeShippingForm = "shipping_form" // 收货信息表单（有效期跟随表单 Token）
+	ShortLinkTypePayment      = "payment"       // 订单付款页（有效期跟随自动取消时间）
+)
+
+// ShortLink 短链接：/s/:code 跳转到原始长链接
+type ShortLink struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Code          string     `gorm:"type:varchar(16);uniqueIndex;not null" json:"code"`
+	TargetType    string     `gorm:"type:varchar(30);index;not null" json:"target_type"`
+	TargetURL     string     `gorm:"type:text;not null" json:"target_url"`
+	OrderID       *uint      `gorm:"index" json:"order_id,omitempty"`
+	ExpiresAt     *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	Revoked       bool       `gorm:"default:false" json:"revoked"`
+	ClickCount    int64      `gorm:"default:0" json:"click_count"`
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty"`
+	CreatedBy     *uint      `json:"created_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ShortLink) TableName() string {
+	return "short_links"
+}
+
+// IsUsable 是否仍可跳转
+func (l *ShortLink) IsUsable(now time.Time) bool {
+	if l.Revoked {
+		return false
+	}
+	return l.ExpiresAt == nil || now.Before(*l.ExpiresAt)
+}
+
+// ShortLinkClick 短链点击记录
+type ShortLinkClick struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ShortLinkID uint      `gorm:"index;not null" json:"short_link_id"`
+	IP          string    `gorm:"type:varchar(45)" json:"ip"`
+	UserAgent   string    `gorm:"type:text" json:"user_agent"`
+	Referer     string    `gorm:"type:text" json:"referer"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ShortLinkClick) TableName() string {
+	return "short_link_clicks"
+}
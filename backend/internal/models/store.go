@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Store 店铺（同一部署下的独立店面）
+type Store struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Code        string `gorm:"type:varchar(50);uniqueIndex;not null" json:"code"` // 店铺标识（小写字母/数字/中划线）
+	Name        string `gorm:"type:varchar(100);not null" json:"name"`
+	Description string `gorm:"type:varchar(500)" json:"description,omitempty"`
+
+	// 品牌（为空时回退到全局配置）
+	LogoURL      string `gorm:"type:varchar(500)" json:"logo_url,omitempty"`
+	FaviconURL   string `gorm:"type:varchar(500)" json:"favicon_url,omitempty"`
+	PrimaryColor string `gorm:"type:varchar(50)" json:"primary_color,omitempty"`
+
+	IsDefault bool `gorm:"default:false;index" json:"is_default"` // Host 未匹配时使用的默认店铺
+	IsActive  bool `gorm:"default:true;index" json:"is_active"`
+
+	Domains []StoreDomain `gorm:"foreignKey:StoreID" json:"domains,omitempty"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName 指定表名
+func (Store) TableName() string {
+	return "stores"
+}
+
+// 域名所有权验证状态
+const (
+	DomainVerificationPending  = "pending"
+	DomainVerificationVerified = "verified"
+	DomainVerificationFailed   = "failed"
+)
+
+// 域名证书状态
+const (
+	DomainCertNone    = "none"
+	DomainCertPending = "pending"
+	DomainCertIssued  = "issued"
+	DomainCertFailed  = "failed"
+)
+
+// StoreDomain 店铺绑定的访问域名 / 自定义域名
+// StoreID 为 0 表示未绑定店铺（单店铺部署下的自定义域名）
+type StoreDomain struct {
+	ID              uint   `gorm:"primaryKey" json:"id"`
+	StoreID         uint   `gorm:"not null;default:0;index" json:"store_id"`
+	Host            string `gorm:"type:varchar(255);uniqueIndex;not null" json:"host"` // 小写且不含端口
+	IsPrimary       bool   `gorm:"default:false" json:"is_primary"`
+	LandingPageSlug string `gorm:"type:varchar(100)" json:"landing_page_slug,omitempty"` // 为空使用默认落地页 home
+
+	// 所有权验证：在 _auralogic-challenge.<host> 添加 TXT 记录，值为 VerificationToken
+	VerificationToken     string     `gorm:"type:varchar(64)" json:"verification_token"`
+	VerificationStatus    string     `gorm:"type:varchar(20);default:'pending';index" json:"verification_status"`
+	VerificationError     string     `gorm:"type:varchar(500)" json:"verification_error,omitempty"`
+	VerificationCheckedAt *time.Time `json:"verification_checked_at,omitempty"`
+	VerifiedAt            *time.Time `json:"verified_at,omitempty"`
+
+	// ACME 证书状态（仅已验证域名会签发）
+	CertStatus    string     `gorm:"type:varchar(20);default:'none'" json:"cert_status"`
+	CertError     string     `gorm:"type:varchar(500)" json:"cert_error,omitempty"`
+	CertExpiresAt *time.Time `json:"cert_expires_at,omitempty"`
+	CertCheckedAt *time.Time `json:"cert_checked_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// VerificationRecordName 返回所有权验证 TXT 记录名
+func (d StoreDomain) VerificationRecordName() string {
+	return "_auralogic-challenge." + d.Host
+}
+
+// TableName 指定表名
+func (StoreDomain) TableName() string {
+	return "store_domains"
+}
+
+// StoreAdmin 管理员与店铺的绑定关系
+// 绑定了店铺的普通管理员只能管理所绑定店铺的数据；未绑定任何店铺的管理员不受限制
+type StoreAdmin struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	StoreID   uint      `gorm:"not null;uniqueIndex:idx_store_admin" json:"store_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_store_admin;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (StoreAdmin) TableName() string {
+	return "store_admins"
+}
+
+// BelongsToStore 判断带有 store_id 的资源是否对指定店铺可见
+// 未绑定店铺（store_id 为空）的资源视为所有店铺共享
+func BelongsToStore(resourceStoreID *uint, storeID uint) bool {
+	return resourceStoreID == nil || *resourceStoreID == storeID
+}
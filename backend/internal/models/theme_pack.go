@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// ThemePalette 主题调色板（HSL 三元组如 "217.2 91% 60%"，或 #RRGGBB），为空表示使用前端默认值
+type ThemePalette struct {
+	Background          string `json:"background,omitempty"`
+	Foreground          string `json:"foreground,omitempty"`
+	Card                string `json:"card,omitempty"`
+	CardForeground      string `json:"card_foreground,omitempty"`
+	Primary             string `json:"primary,omitempty"`
+	PrimaryForeground   string `json:"primary_foreground,omitempty"`
+	Secondary           string `json:"secondary,omitempty"`
+	SecondaryForeground string `json:"secondary_foreground,omitempty"`
+	Accent              string `json:"accent,omitempty"`
+	AccentForeground    string `json:"accent_foreground,omitempty"`
+	Muted               string `json:"muted,omitempty"`
+	MutedForeground     string `json:"muted_foreground,omitempty"`
+	Destructive         string `json:"destructive,omitempty"`
+	Border              string `json:"border,omitempty"`
+	Ring                string `json:"ring,omitempty"`
+}
+
+// ThemeTypography 主题字体与圆角
+type ThemeTypography struct {
+	FontFamily        string `json:"font_family,omitempty"`
+	HeadingFontFamily string `json:"heading_font_family,omitempty"`
+	FontURL           string `json:"font_url,omitempty"`       // Web 字体样式表地址
+	BaseFontSize      string `json:"base_font_size,omitempty"` // 如 16px / 1rem
+	Radius            string `json:"radius,omitempty"`         // 如 0.5rem
+}
+
+// ThemeEmailBranding 邮件品牌：页眉/页脚 HTML 会包裹在所有事务邮件正文外
+type ThemeEmailBranding struct {
+	LogoURL     string `json:"logo_url,omitempty"`
+	AccentColor string `json:"accent_color,omitempty"`
+	HeaderHTML  string `json:"header_html,omitempty"`
+	FooterHTML  string `json:"footer_html,omitempty"`
+}
+
+// ThemePack 主题包：亮/暗两套调色板、字体与邮件品牌，同一时间最多一个启用
+type ThemePack struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Slug        string `gorm:"type:varchar(100);uniqueIndex;not null" json:"slug"`
+	Name        string `gorm:"type:varchar(100);not null" json:"name"`
+	Description string `gorm:"type:varchar(500)" json:"description,omitempty"`
+
+	Light      ThemePalette       `gorm:"type:text;serializer:json" json:"light"`
+	Dark       ThemePalette       `gorm:"type:text;serializer:json" json:"dark"`
+	Typography ThemeTypography    `gorm:"type:text;serializer:json" json:"typography"`
+	Email      ThemeEmailBranding `gorm:"type:text;serializer:json" json:"email"`
+
+	IsActive  bool      `gorm:"default:false;index" json:"is_active"`
+	UpdatedBy uint      `gorm:"default:0" json:"updated_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ThemePack) TableName() string {
+	return "theme_packs"
+}
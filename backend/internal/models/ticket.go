@@ -39,6 +39,7 @@ type Ticket struct {
 	Category    string         `gorm:"type:varchar(50)" json:"category,omitempty"`
 	Priority    TicketPriority `gorm:"type:varchar(20);default:'normal'" json:"priority"`
 	Status      TicketStatus   `gorm:"type:varchar(20);default:'open';index" json:"status"`
+	Tags        []string       `gorm:"type:text;serializer:json" json:"tags,omitempty"` // 自由标签，统一小写，例如 refund-request
 
 	// 处理人
 	AssignedTo   *uint  `gorm:"index" json:"assigned_to,omitempty"`
@@ -129,3 +130,19 @@ func (a *TicketOrderAccess) IsExpired() bool {
 	}
 	return time.Now().After(*a.ExpiresAt)
 }
+
+// TicketAttachment 工单附件归属，附件链接只对所属工单的参与者签名
+type TicketAttachment struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	TicketID     uint      `gorm:"index;not null" json:"ticket_id"`
+	Path         string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"path"` // uploads/tickets 下的相对路径
+	UploaderType string    `gorm:"type:varchar(20)" json:"uploader_type"`              // user/admin
+	UploaderID   uint      `json:"uploader_id"`
+	OriginalName string    `gorm:"type:varchar(255)" json:"original_name"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+}
+
+func (TicketAttachment) TableName() string {
+	return "ticket_attachments"
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// TicketAutomationTrigger 工单自动化规则触发事件
+type TicketAutomationTrigger string
+
+const (
+	TicketAutomationTriggerCreated     TicketAutomationTrigger = "ticket.created"      // 用户创建工单
+	TicketAutomationTriggerUserReplied TicketAutomationTrigger = "ticket.user_replied" // 用户在工单中回复
+)
+
+// TicketAutomationActionType 工单自动化动作类型
+type TicketAutomationActionType string
+
+const (
+	TicketAutomationActionAddTag      TicketAutomationActionType = "add_tag"
+	TicketAutomationActionRemoveTag   TicketAutomationActionType = "remove_tag"
+	TicketAutomationActionSetPriority TicketAutomationActionType = "set_priority"
+)
+
+// TicketAutomationCondition 单个条件：field operator value
+type TicketAutomationCondition struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// TicketAutomationAction 单个动作，按类型使用对应字段
+type TicketAutomationAction struct {
+	Type     TicketAutomationActionType `json:"type"`
+	Tag      string                     `json:"tag,omitempty"`      // add_tag / remove_tag
+	Priority TicketPriority             `json:"priority,omitempty"` // set_priority
+}
+
+// TicketAutomationRule 工单自动化规则：事件触发 → 条件匹配 → 依次执行动作
+type TicketAutomationRule struct {
+	ID             uint                        `gorm:"primaryKey" json:"id"`
+	Name           string                      `gorm:"type:varchar(100);not null" json:"name"`
+	Description    string                      `gorm:"type:text" json:"description,omitempty"`
+	Trigger        TicketAutomationTrigger     `gorm:"column:trigger_event;type:varchar(50);not null;index" json:"trigger"`
+	Enabled        bool                        `gorm:"index" json:"enabled"`
+	MatchType      string                      `gorm:"type:varchar(10);not null;default:'all'" json:"match_type"` // all / any
+	Conditions     []TicketAutomationCondition `gorm:"type:text;serializer:json" json:"conditions"`
+	Actions        []TicketAutomationAction    `gorm:"type:text;serializer:json" json:"actions"`
+	Priority       int                         `gorm:"default:0" json:"priority"` // 越大越先执行
+	StopProcessing bool                        `json:"stop_processing"`           // 命中后不再执行后续规则
+	RunCount       int64                       `gorm:"default:0" json:"run_count"`
+	LastRunAt      *time.Time                  `json:"last_run_at,omitempty"`
+	CreatedBy      *uint                       `json:"created_by,omitempty"`
+	CreatedAt      time.Time                   `json:"created_at"`
+	UpdatedAt      time.Time                   `json:"updated_at"`
+}
+
+func (TicketAutomationRule) TableName() string {
+	return "ticket_automation_rules"
+}
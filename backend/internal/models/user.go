@@ -19,9 +19,18 @@ type User struct {
 	Role         string  `gorm:"type:varchar(20);default:'user'" json:"role"` // user/admin/super_admin
 	IsActive     bool    `gorm:"default:true" json:"is_active"`
 
-	EmailVerified bool   `gorm:"default:false" json:"email_verified"`
-	Locale        string `gorm:"type:varchar(10)" json:"locale,omitempty"`
-	Country       string `gorm:"type:varchar(100)" json:"country,omitempty"`
+	EmailVerified bool `gorm:"default:false" json:"email_verified"`
+	// 管理员豁免：不受邮箱验证强制策略限制
+	EmailVerificationExempt bool `gorm:"default:false" json:"email_verification_exempt"`
+	// 最近一次更换邮箱的时间，宽限期从此刻起算（为空时取注册时间）
+	EmailChangedAt *time.Time `json:"email_changed_at,omitempty"`
+	Locale         string     `gorm:"type:varchar(10)" json:"locale,omitempty"`
+	Country        string     `gorm:"type:varchar(100)" json:"country,omitempty"`
+	// 展示币种偏好：价格按汇率换算的参考金额，实际结算币种不变
+	DisplayCurrency string `gorm:"type:varchar(10)" json:"display_currency,omitempty"`
+
+	// 客户等级（如 wholesale），由管理员设置；询价商品可对指定等级开放价格
+	CustomerTier string `gorm:"type:varchar(50);index" json:"customer_tier,omitempty"`
 
 	// 用户消费统计（金额单位：minor，例：分）
 	TotalSpentMinor int64 `gorm:"type:bigint;default:0" json:"total_spent_minor"`
@@ -14,6 +14,23 @@ const (
 	VirtualInventoryTypeScript VirtualInventoryType = "script" // JS脚本动态发货
 )
 
+// 虚拟库存发货顺序，可在绑定、虚拟库存、全局设置三级配置，空值表示继承上一级
+const (
+	VirtualDeliveryOrderRandom   = "random"   // 随机
+	VirtualDeliveryOrderNewest   = "newest"   // 新导入的先发
+	VirtualDeliveryOrderOldest   = "oldest"   // 先进先出
+	VirtualDeliveryOrderExpiring = "expiring" // 最早过期的先发，无有效期的排在最后
+)
+
+// IsValidVirtualDeliveryOrder 检查发货顺序取值是否合法（不含空值）
+func IsValidVirtualDeliveryOrder(order string) bool {
+	switch order {
+	case VirtualDeliveryOrderRandom, VirtualDeliveryOrderNewest, VirtualDeliveryOrderOldest, VirtualDeliveryOrderExpiring:
+		return true
+	}
+	return false
+}
+
 // VirtualInventory 虚拟库存表（存储卡密/激活码等虚拟商品的库存池）
 // 类似于实体库存 Inventory，可以独立创建，然后绑定到商品
 type VirtualInventory struct {
@@ -26,8 +43,9 @@ type VirtualInventory struct {
 	Description       string               `gorm:"type:text" json:"description,omitempty"`                 // 描述
 	TotalLimit        int64                `gorm:"default:0" json:"total_limit"`                           // 脚本类型总发货次数限制（0=无限制）
 	AllowInlineIframe bool                 `gorm:"default:false" json:"allow_inline_iframe"`
-	IsActive          bool                 `gorm:"default:true" json:"is_active"`    // 是否启用
-	Notes             string               `gorm:"type:text" json:"notes,omitempty"` // 备注
+	DeliveryOrder     string               `gorm:"type:varchar(20)" json:"delivery_order"` // 发货顺序（空=使用全局设置）
+	IsActive          bool                 `gorm:"default:true" json:"is_active"`          // 是否启用
+	Notes             string               `gorm:"type:text" json:"notes,omitempty"`       // 备注
 	CreatedAt         time.Time            `json:"created_at"`
 	UpdatedAt         time.Time            `json:"updated_at"`
 	DeletedAt         gorm.DeletedAt       `gorm:"index" json:"-"`
@@ -52,6 +70,7 @@ type ProductVirtualInventoryBinding struct {
 	AttributesHash     string    `gorm:"type:varchar(64);uniqueIndex:idx_pvib_product_attrs" json:"attributes_hash"` // 规格组合哈希，唯一性约束：同一商品的同一规格组合只能绑定一次
 	IsRandom           bool      `gorm:"default:false" json:"is_random"`                                             // 是否参与盲盒随机分配
 	Priority           int       `gorm:"default:1" json:"priority"`                                                  // 权重
+	DeliveryOrder      string    `gorm:"type:varchar(20)" json:"delivery_order"`                                     // 发货顺序（空=使用虚拟库存设置）
 	Notes              string    `gorm:"type:text" json:"notes,omitempty"`                                           // 备注
 	CreatedAt          time.Time `json:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at"`
@@ -77,12 +96,14 @@ type VirtualInventoryWithStats struct {
 	Description       string               `json:"description"`
 	TotalLimit        int64                `json:"total_limit"`
 	AllowInlineIframe bool                 `json:"allow_inline_iframe"`
+	DeliveryOrder     string               `json:"delivery_order"`
 	IsActive          bool                 `json:"is_active"`
 	Notes             string               `json:"notes"`
 	Total             int64                `json:"total"`
 	Available         int64                `json:"available"`
 	Reserved          int64                `json:"reserved"`
 	Sold              int64                `json:"sold"`
+	Revoked           int64                `json:"revoked"`
 	CreatedAt         time.Time            `json:"created_at"`
 }
 
@@ -95,6 +116,7 @@ type BindingWithVirtualInventoryInfo struct {
 	AttributesHash     string                     `json:"attributes_hash"`
 	IsRandom           bool                       `json:"is_random"`
 	Priority           int                        `json:"priority"`
+	DeliveryOrder      string                     `json:"delivery_order"`
 	Notes              string                     `json:"notes,omitempty"`
 	VirtualInventory   *VirtualInventoryWithStats `json:"virtual_inventory"`
 	CreatedAt          time.Time                  `json:"created_at"`
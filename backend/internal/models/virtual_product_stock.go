@@ -1,6 +1,10 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,37 +18,53 @@ const (
 	VirtualStockStatusSold      VirtualProductStockStatus = "sold"      // 已售出
 	VirtualStockStatusReserved  VirtualProductStockStatus = "reserved"  // 已预留
 	VirtualStockStatusInvalid   VirtualProductStockStatus = "invalid"   // 已失效
+	VirtualStockStatusRevoked   VirtualProductStockStatus = "revoked"   // 已撤销（发货后作废，如拒付、发错库存）
 )
 
 // VirtualProductStock 虚拟产品库存表（存储卡密、激活码等）
 type VirtualProductStock struct {
 	ID                 uint              `gorm:"primaryKey" json:"id"`
-	VirtualInventoryID uint              `gorm:"not null;index:idx_virtual_inventory_status" json:"virtual_inventory_id"`
+	VirtualInventoryID uint              `gorm:"not null;index:idx_virtual_stock_stats,priority:1;index:idx_virtual_stock_sampling,priority:1" json:"virtual_inventory_id"`
 	VirtualInventory   *VirtualInventory `gorm:"foreignKey:VirtualInventoryID" json:"virtual_inventory,omitempty"`
 
 	// 虚拟商品内容
 	Content      string `gorm:"type:text;not null" json:"content"`         // 卡密/激活码内容
 	Remark       string `gorm:"type:varchar(500)" json:"remark,omitempty"` // 备注信息
 	Presentation JSON   `gorm:"type:text" json:"presentation,omitempty"`
+	ContentHash  string `gorm:"type:varchar(64);index" json:"-"` // 卡密内容哈希，客服按卡密反查订单
 
 	// 状态
-	Status VirtualProductStockStatus `gorm:"type:varchar(20);not null;default:'available';index:idx_virtual_inventory_status" json:"status"`
+	Status VirtualProductStockStatus `gorm:"type:varchar(20);not null;default:'available';index:idx_virtual_stock_stats,priority:2;index:idx_virtual_stock_sampling,priority:2;index:idx_virtual_stock_order_status,priority:2" json:"status"`
+
+	// 随机抽样键：随机发货时从随机起点沿索引顺序取，替代 ORDER BY RANDOM() 的全量排序
+	RandomKey int `gorm:"not null;default:0;index:idx_virtual_stock_sampling,priority:3" json:"-"`
 
 	// 订单关联
 	OrderID *uint  `gorm:"index" json:"order_id,omitempty"`
-	OrderNo string `gorm:"type:varchar(50);index" json:"order_no,omitempty"`
+	OrderNo string `gorm:"type:varchar(50);index;index:idx_virtual_stock_order_status,priority:1" json:"order_no,omitempty"`
 
 	// 发货信息
-	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
-	DeliveredBy *uint      `json:"delivered_by,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	DeliveredBy   *uint      `json:"delivered_by,omitempty"`
+	FirstViewedAt *time.Time `json:"first_viewed_at,omitempty"` // 买家首次查看卡密的时间
+
+	// 撤销信息
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy     *uint      `json:"revoked_by,omitempty"`
+	RevokeReason  string     `gorm:"type:varchar(500)" json:"revoke_reason,omitempty"`
+	ReplacementID *uint      `json:"replacement_id,omitempty"` // 补发的库存项
 
 	// 导入批次
 	BatchNo    string `gorm:"type:varchar(100);index" json:"batch_no,omitempty"` // 批次记录，用于追踪导入批次
 	ImportedBy string `gorm:"type:varchar(100)" json:"imported_by,omitempty"`    // 导入人
 
+	// 有效期：过期的库存项不再参与分配，由定时任务标记为失效
+	ExpiresAt      *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	ExpiryWarnedAt *time.Time `json:"expiry_warned_at,omitempty"` // 临期提醒时间
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	DeletedAt gorm.DeletedAt `gorm:"index;index:idx_virtual_stock_stats,priority:3" json:"-"` // 与库存ID、状态组成统计覆盖索引
 }
 
 // TableName 指定表名
@@ -52,6 +72,35 @@ func (VirtualProductStock) TableName() string {
 	return "virtual_product_stocks"
 }
 
+// VirtualStockRandomKeyMax 随机抽样键取值上限（不含），取值范围 [1, VirtualStockRandomKeyMax)
+const VirtualStockRandomKeyMax = 1 << 31
+
+// NewVirtualStockRandomKey 生成随机抽样键
+func NewVirtualStockRandomKey() int {
+	return 1 + rand.Intn(VirtualStockRandomKeyMax-1)
+}
+
+// BeforeCreate 新库存项自动分配随机抽样键并计算内容哈希
+func (v *VirtualProductStock) BeforeCreate(tx *gorm.DB) error {
+	if v.RandomKey <= 0 {
+		v.RandomKey = NewVirtualStockRandomKey()
+	}
+	if v.ContentHash == "" {
+		v.ContentHash = HashVirtualStockContent(v.Content)
+	}
+	return nil
+}
+
+// HashVirtualStockContent 卡密内容的 SHA-256（去除首尾空白），空内容返回空串
+func HashVirtualStockContent(content string) string {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
 // IsAvailable 是否可用
 func (v *VirtualProductStock) IsAvailable() bool {
 	return v.Status == VirtualStockStatusAvailable
@@ -72,6 +121,23 @@ func (v *VirtualProductStock) MarkAsReserved(orderNo string) {
 	v.OrderNo = orderNo
 }
 
+// MaskVirtualStockContent 卡密默认只展示末 4 位，其余以 * 代替
+func MaskVirtualStockContent(content string) string {
+	runes := []rune(content)
+	if len(runes) == 0 {
+		return ""
+	}
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", min(len(runes)-4, 8)) + string(runes[len(runes)-4:])
+}
+
+// MaskContent 将卡密内容替换为遮罩形式，明文需通过查看接口获取
+func (v *VirtualProductStock) MaskContent() {
+	v.Content = MaskVirtualStockContent(v.Content)
+}
+
 // Release 释放预留（取消订单时）
 func (v *VirtualProductStock) Release() {
 	v.Status = VirtualStockStatusAvailable
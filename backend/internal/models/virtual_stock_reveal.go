@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// 卡密明文查看者类型
+const (
+	VirtualStockRevealViewerUser  = "user"  // 买家在订单页查看
+	VirtualStockRevealViewerAdmin = "admin" // 管理员在后台查看
+	VirtualStockRevealViewerLink  = "link"  // 通过邮件签名链接提取
+)
+
+// VirtualStockRevealLog 卡密明文查看记录（谁在何时查看了哪个卡密）
+type VirtualStockRevealLog struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	StockID            uint      `gorm:"index;not null" json:"stock_id"`
+	VirtualInventoryID uint      `gorm:"index" json:"virtual_inventory_id"`
+	OrderNo            string    `gorm:"type:varchar(50);index" json:"order_no,omitempty"`
+	ViewerType         string    `gorm:"type:varchar(20);not null" json:"viewer_type"`
+	ViewerID           *uint     `gorm:"index" json:"viewer_id,omitempty"`
+	ViewerEmail        string    `gorm:"type:varchar(255)" json:"viewer_email,omitempty"`
+	IP                 string    `gorm:"type:varchar(64)" json:"ip,omitempty"`
+	CreatedAt          time.Time `gorm:"index" json:"created_at"`
+}
+
+func (VirtualStockRevealLog) TableName() string {
+	return "virtual_stock_reveal_logs"
+}
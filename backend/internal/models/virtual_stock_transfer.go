@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// VirtualStockTransfer 虚拟库存项调拨记录（拆分/合并库存池），库存项的批次号保持不变
+type VirtualStockTransfer struct {
+	ID                uint                   `gorm:"primaryKey" json:"id"`
+	SourceInventoryID uint                   `gorm:"index;not null" json:"source_inventory_id"`
+	TargetInventoryID uint                   `gorm:"index;not null" json:"target_inventory_id"`
+	Quantity          int                    `gorm:"not null" json:"quantity"`
+	BatchNos          []string               `gorm:"type:text;serializer:json" json:"batch_nos"`
+	StockIDs          []uint                 `gorm:"type:text;serializer:json" json:"stock_ids"`
+	Filters           map[string]interface{} `gorm:"type:text;serializer:json" json:"filters,omitempty"`
+	Reason            string                 `gorm:"type:varchar(500)" json:"reason,omitempty"`
+	Operator          string                 `gorm:"type:varchar(100)" json:"operator"`
+	OperatorID        *uint                  `json:"operator_id,omitempty"`
+	CreatedAt         time.Time              `gorm:"index" json:"created_at"`
+}
+
+func (VirtualStockTransfer) TableName() string {
+	return "virtual_stock_transfers"
+}
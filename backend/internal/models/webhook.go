@@ -0,0 +1,97 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// WebhookEvent 出站 Webhook 事件名
+type WebhookEvent string
+
+const (
+	WebhookEventOrderCreated   WebhookEvent = "order.created"
+	WebhookEventOrderPaid      WebhookEvent = "order.paid"
+	WebhookEventOrderShipped   WebhookEvent = "order.shipped"
+	WebhookEventOrderCompleted WebhookEvent = "order.completed"
+	WebhookEventOrderCancelled WebhookEvent = "order.cancelled"
+	WebhookEventOrderRefunded  WebhookEvent = "order.refunded"
+	WebhookEventOrderStatus    WebhookEvent = "order.status_changed" // 任意状态变更，携带 status_before
+	WebhookEventTicketCreated  WebhookEvent = "ticket.created"
+	WebhookEventTicketReplied  WebhookEvent = "ticket.replied" // 用户回复工单
+	WebhookEventPing           WebhookEvent = "ping"           // 管理员手动测试
+	WebhookEventAll            WebhookEvent = "*"
+)
+
+// WebhookDeliveryStatus 投递状态
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending" // 等待首次投递或重试
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed" // 重试次数用尽
+)
+
+// WebhookEndpoint 管理员配置的出站 Webhook 地址
+type WebhookEndpoint struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Name        string     `gorm:"type:varchar(100);not null" json:"name"`
+	URL         string     `gorm:"type:varchar(500);not null" json:"url"`
+	Secret      string     `gorm:"type:varchar(100);not null" json:"-"` // HMAC-SHA256 签名密钥，仅创建与轮换时返回
+	Events      []string   `gorm:"type:text;serializer:json" json:"events"`
+	Enabled     bool       `gorm:"index" json:"enabled"`
+	Description string     `gorm:"type:text" json:"description,omitempty"`
+	Platform    string     `gorm:"type:varchar(100);index" json:"platform,omitempty"` // 订单来源平台；设置后只接收该平台的订单事件
+	LastStatus  string     `gorm:"type:varchar(20)" json:"last_status,omitempty"`
+	LastSentAt  *time.Time `json:"last_sent_at,omitempty"`
+	CreatedBy   *uint      `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// Subscribes 是否订阅了该事件；ping 总是投递
+func (e *WebhookEndpoint) Subscribes(event WebhookEvent) bool {
+	if event == WebhookEventPing {
+		return true
+	}
+	for _, item := range e.Events {
+		if WebhookEvent(item) == event || WebhookEvent(item) == WebhookEventAll {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsPlatform 平台回调地址只接收来源平台匹配的订单事件
+func (e *WebhookEndpoint) AcceptsPlatform(event WebhookEvent, platform string) bool {
+	if e.Platform == "" || event == WebhookEventPing {
+		return true
+	}
+	return strings.HasPrefix(string(event), "order.") && strings.EqualFold(e.Platform, platform)
+}
+
+// WebhookDelivery 单次事件投递记录，失败后按指数退避重试
+type WebhookDelivery struct {
+	ID             uint                  `gorm:"primaryKey" json:"id"`
+	EndpointID     uint                  `gorm:"not null;index" json:"endpoint_id"`
+	EventID        string                `gorm:"type:varchar(64);not null;index" json:"event_id"`
+	Event          WebhookEvent          `gorm:"type:varchar(50);not null;index" json:"event"`
+	Payload        string                `gorm:"type:text" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	Attempts       int                   `gorm:"default:0" json:"attempts"`
+	NextAttemptAt  *time.Time            `gorm:"index" json:"next_attempt_at,omitempty"`
+	ResponseStatus int                   `json:"response_status,omitempty"`
+	ResponseBody   string                `gorm:"type:text" json:"response_body,omitempty"` // 截断保存
+	Error          string                `gorm:"type:text" json:"error,omitempty"`
+	DurationMs     int64                 `json:"duration_ms"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `gorm:"index" json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
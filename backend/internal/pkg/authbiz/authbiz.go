@@ -1,6 +1,10 @@
 package authbiz
 
-import "auralogic/internal/pkg/bizerr"
+import (
+	"time"
+
+	"auralogic/internal/pkg/bizerr"
+)
 
 func InvalidEmailOrPassword() *bizerr.Error {
 	return bizerr.New("auth.invalidEmailOrPassword", "Invalid email or password")
@@ -89,3 +93,27 @@ func CaptchaRequired() *bizerr.Error {
 func CaptchaFailed() *bizerr.Error {
 	return bizerr.New("auth.captchaFailed", "Captcha verification failed")
 }
+
+func AccountLocked(lockedUntil time.Time) *bizerr.Error {
+	retryAfter := int(time.Until(lockedUntil).Seconds())
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	return bizerr.New("auth.accountLocked", "Too many failed login attempts, account is temporarily locked").
+		WithParams(map[string]interface{}{
+			"locked_until":        lockedUntil.UTC().Format(time.RFC3339),
+			"retry_after_seconds": retryAfter,
+		})
+}
+
+func IPBanned() *bizerr.Error {
+	return bizerr.New("auth.ipBanned", "Access from your IP address has been blocked")
+}
+
+func EmailChangeLinkInvalid() *bizerr.Error {
+	return bizerr.New("auth.emailChangeLinkInvalid", "Email change revert link is invalid or has expired")
+}
+
+func InvalidDisplayCurrency() *bizerr.Error {
+	return bizerr.New("auth.invalidDisplayCurrency", "Invalid display currency code")
+}
@@ -0,0 +1,58 @@
+package authbiz
+
+import (
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+)
+
+// 邮箱验证强制方式
+const (
+	EmailVerificationModeLogin    = "login"    // 未验证禁止登录
+	EmailVerificationModeCheckout = "checkout" // 可登录，但禁止下单
+	EmailVerificationModeGrace    = "grace"    // 宽限期内仅禁止下单，过期后禁止登录
+)
+
+func EmailVerificationRequired() *bizerr.Error {
+	return bizerr.New("auth.emailVerificationRequired", "Please verify your email before placing orders")
+}
+
+// EmailVerificationStatus 用户当前受到的邮箱验证限制
+type EmailVerificationStatus struct {
+	Required      bool       `json:"required"`
+	BlockLogin    bool       `json:"block_login"`
+	BlockCheckout bool       `json:"block_checkout"`
+	GraceEndsAt   *time.Time `json:"grace_ends_at,omitempty"`
+}
+
+// EvaluateEmailVerification 按登录配置判断未验证邮箱的用户是否应被限制；
+// 管理员、被豁免用户、无邮箱用户（手机号注册）与已验证用户不受限制
+func EvaluateEmailVerification(cfg config.LoginConfig, user *models.User, now time.Time) EmailVerificationStatus {
+	if !cfg.RequireEmailVerification || user == nil || user.EmailVerified || user.EmailVerificationExempt ||
+		user.IsAdmin() || strings.TrimSpace(user.Email) == "" {
+		return EmailVerificationStatus{}
+	}
+
+	status := EmailVerificationStatus{Required: true, BlockCheckout: true}
+	switch cfg.EmailVerificationMode {
+	case EmailVerificationModeCheckout:
+	case EmailVerificationModeGrace:
+		start := user.CreatedAt
+		if user.EmailChangedAt != nil {
+			start = *user.EmailChangedAt
+		}
+		days := cfg.EmailVerificationGraceDays
+		if days <= 0 {
+			days = 7
+		}
+		endsAt := start.Add(time.Duration(days) * 24 * time.Hour)
+		status.GraceEndsAt = &endsAt
+		status.BlockLogin = !now.Before(endsAt)
+	default:
+		status.BlockLogin = true
+	}
+	return status
+}
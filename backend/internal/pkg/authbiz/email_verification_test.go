@@ -0,0 +1,54 @@
+package authbiz
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func TestEvaluateEmailVerification(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	changedAt := now.Add(-time.Hour)
+	cfg := config.LoginConfig{RequireEmailVerification: true, EmailVerificationGraceDays: 7}
+	unverified := func() *models.User {
+		return &models.User{Email: "a@example.com", Role: "user", CreatedAt: now.AddDate(0, 0, -10)}
+	}
+
+	cases := []struct {
+		name          string
+		mode          string
+		user          *models.User
+		required      bool
+		blockLogin    bool
+		blockCheckout bool
+	}{
+		{"default mode blocks login", "", unverified(), true, true, true},
+		{"checkout mode only blocks checkout", EmailVerificationModeCheckout, unverified(), true, false, true},
+		{"grace expired", EmailVerificationModeGrace, unverified(), true, true, true},
+		{"grace restarts after email change", EmailVerificationModeGrace, func() *models.User {
+			u := unverified()
+			u.EmailChangedAt = &changedAt
+			return u
+		}(), true, false, true},
+		{"verified", EmailVerificationModeLogin, &models.User{Email: "a@example.com", EmailVerified: true}, false, false, false},
+		{"exempt", EmailVerificationModeLogin, &models.User{Email: "a@example.com", EmailVerificationExempt: true}, false, false, false},
+		{"admin", EmailVerificationModeLogin, &models.User{Email: "a@example.com", Role: "admin"}, false, false, false},
+		{"phone only", EmailVerificationModeLogin, &models.User{Role: "user"}, false, false, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			modeCfg := cfg
+			modeCfg.EmailVerificationMode = tc.mode
+			status := EvaluateEmailVerification(modeCfg, tc.user, now)
+			if status.Required != tc.required || status.BlockLogin != tc.blockLogin || status.BlockCheckout != tc.blockCheckout {
+				t.Fatalf("unexpected status: %+v", status)
+			}
+		})
+	}
+
+	if status := EvaluateEmailVerification(config.LoginConfig{}, unverified(), now); status.Required {
+		t.Fatalf("expected no enforcement when verification is disabled, got %+v", status)
+	}
+}
@@ -0,0 +1,218 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// memorySweepEvery 每写入多少次清理一遍过期键
+const memorySweepEvery = 1024
+
+type memoryEntry struct {
+	value    string
+	expireAt time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+// memoryStore 未启用 Redis 时的进程内键值存储，语义与 Redis 对应命令保持一致
+type memoryStore struct {
+	mu     sync.Mutex
+	items  map[string]memoryEntry
+	writes int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{items: make(map[string]memoryEntry)}
+}
+
+var memory = newMemoryStore()
+
+func (m *memoryStore) lookupLocked(key string, now time.Time) (memoryEntry, bool) {
+	entry, ok := m.items[key]
+	if !ok {
+		return entry, false
+	}
+	if entry.expired(now) {
+		delete(m.items, key)
+		return entry, false
+	}
+	return entry, true
+}
+
+func (m *memoryStore) storeLocked(key string, entry memoryEntry, now time.Time) {
+	m.items[key] = entry
+	m.writes++
+	if m.writes%memorySweepEvery != 0 {
+		return
+	}
+	for k, e := range m.items {
+		if e.expired(now) {
+			delete(m.items, k)
+		}
+	}
+}
+
+func expireAtFor(now time.Time, expiration time.Duration) time.Time {
+	if expiration <= 0 {
+		return time.Time{}
+	}
+	return now.Add(expiration)
+}
+
+// formatMemoryValue 按 go-redis 写入参数的方式把值格式化为字符串
+func formatMemoryValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (m *memoryStore) get(key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.lookupLocked(key, time.Now())
+	if !ok {
+		return "", redis.Nil
+	}
+	return entry.value, nil
+}
+
+func (m *memoryStore) set(key string, value interface{}, expiration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.storeLocked(key, memoryEntry{value: formatMemoryValue(value), expireAt: expireAtFor(now, expiration)}, now)
+}
+
+func (m *memoryStore) setNX(key string, value interface{}, expiration time.Duration) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if _, ok := m.lookupLocked(key, now); ok {
+		return false
+	}
+	m.storeLocked(key, memoryEntry{value: formatMemoryValue(value), expireAt: expireAtFor(now, expiration)}, now)
+	return true
+}
+
+func (m *memoryStore) del(keys ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.items, key)
+	}
+}
+
+func (m *memoryStore) exists(keys ...string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var count int64
+	for _, key := range keys {
+		if _, ok := m.lookupLocked(key, now); ok {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *memoryStore) expire(key string, expiration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	entry, ok := m.lookupLocked(key, now)
+	if !ok {
+		return
+	}
+	if expiration <= 0 {
+		delete(m.items, key)
+		return
+	}
+	entry.expireAt = now.Add(expiration)
+	m.items[key] = entry
+}
+
+// incr 与 Redis INCR 一致：键不存在时从0开始，保留原有过期时间
+func (m *memoryStore) incr(key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	entry, ok := m.lookupLocked(key, now)
+	var current int64
+	if ok {
+		n, err := strconv.ParseInt(entry.value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value is not an integer or out of range")
+		}
+		current = n
+	}
+	current++
+	entry.value = strconv.FormatInt(current, 10)
+	m.storeLocked(key, entry, now)
+	return current, nil
+}
+
+func (m *memoryStore) deleteByPattern(pattern string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for key := range m.items {
+		if matchGlob(pattern, key) {
+			delete(m.items, key)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// matchGlob 支持 Redis SCAN MATCH 常用的 * 与 ? 通配符
+func matchGlob(pattern, value string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(value); i++ {
+				if matchGlob(pattern, value[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if value == "" {
+				return false
+			}
+		default:
+			if value == "" || value[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern = pattern[1:]
+		value = value[1:]
+	}
+	return value == ""
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func TestMemoryFallbackMatchesRedisSemantics(t *testing.T) {
+	previous := RedisClient
+	RedisClient = nil
+	memory = newMemoryStore()
+	defer func() { RedisClient = previous }()
+
+	if _, err := Get("missing"); err != redis.Nil {
+		t.Fatalf("missing key should return redis.Nil, got %v", err)
+	}
+
+	if ok, _ := SetNX("lock", "a", time.Minute); !ok {
+		t.Fatal("first SetNX should succeed")
+	}
+	if ok, _ := SetNX("lock", "b", time.Minute); ok {
+		t.Fatal("second SetNX should fail while key exists")
+	}
+
+	for i := 1; i <= 3; i++ {
+		if n, err := Incr("counter"); err != nil || n != int64(i) {
+			t.Fatalf("incr #%d: got %d, %v", i, n, err)
+		}
+	}
+	_ = Set("text", "abc", 0)
+	if _, err := Incr("text"); err == nil {
+		t.Fatal("incr on non-integer value must fail")
+	}
+
+	_ = Set("short", true, 20*time.Millisecond)
+	if v, _ := Get("short"); v != "1" {
+		t.Fatalf("bool should be stored as 1, got %q", v)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if n, _ := Exists("short", "counter"); n != 1 {
+		t.Fatalf("expired key must not exist, got %d", n)
+	}
+
+	_ = Set("perm:user:1", "x", 0)
+	_ = Set("perm:user:2", "y", 0)
+	_ = Set("perm:role:1", "z", 0)
+	if deleted, err := DeleteByPatterns("perm:user:*"); err != nil || deleted != 2 {
+		t.Fatalf("expected 2 keys deleted, got %d, %v", deleted, err)
+	}
+	if n, _ := Exists("perm:role:1"); n != 1 {
+		t.Fatal("non-matching key should be kept")
+	}
+}
@@ -34,45 +34,93 @@ func InitRedis(cfg *config.RedisConfig) error {
 	return nil
 }
 
+// InitMemory 未启用 Redis 时使用进程内缓存，并提示降级的能力
+func InitMemory() {
+	RedisClient = nil
+	log.Println("Warning: redis is disabled, falling back to in-process cache. Degraded capabilities:")
+	for _, warning := range []string{
+		"rate limits, verification codes, captcha and permission cache are per-process and reset on restart",
+		"email queue is served by database polling",
+		"marketing batches are picked up by database polling",
+		"delayed SMS (sms_rate_limit.exceed_action=delay) is rejected instead of delayed",
+		"background job locks are no-ops; run a single backend process (app.mode=all)",
+	} {
+		log.Printf("  - %s", warning)
+	}
+}
+
+// Enabled 是否连接了 Redis；为 false 时读写落在进程内缓存
+func Enabled() bool {
+	return RedisClient != nil
+}
+
 // Get get缓存
 func Get(key string) (string, error) {
+	if RedisClient == nil {
+		return memory.get(key)
+	}
 	return RedisClient.Get(ctx, key).Result()
 }
 
 // Set 设置缓存
 func Set(key string, value interface{}, expiration time.Duration) error {
+	if RedisClient == nil {
+		memory.set(key, value, expiration)
+		return nil
+	}
 	return RedisClient.Set(ctx, key, value, expiration).Err()
 }
 
 // Del Delete缓存
 func Del(keys ...string) error {
+	if RedisClient == nil {
+		memory.del(keys...)
+		return nil
+	}
 	return RedisClient.Del(ctx, keys...).Err()
 }
 
 // Exists 检查键是否存在
 func Exists(keys ...string) (int64, error) {
+	if RedisClient == nil {
+		return memory.exists(keys...), nil
+	}
 	return RedisClient.Exists(ctx, keys...).Result()
 }
 
 // Expire 设置过期时间
 func Expire(key string, expiration time.Duration) error {
+	if RedisClient == nil {
+		memory.expire(key, expiration)
+		return nil
+	}
 	return RedisClient.Expire(ctx, key, expiration).Err()
 }
 
 // Incr 增加计数
 func Incr(key string) (int64, error) {
+	if RedisClient == nil {
+		return memory.incr(key)
+	}
 	return RedisClient.Incr(ctx, key).Result()
 }
 
 // SetNX 仅当key不存在时设置，返回是否设置成功
 func SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	if RedisClient == nil {
+		return memory.setNX(key, value, expiration), nil
+	}
 	return RedisClient.SetNX(ctx, key, value, expiration).Result()
 }
 
 // DeleteByPatterns 按通配符模式删除缓存键，返回删除数量
 func DeleteByPatterns(patterns ...string) (int64, error) {
 	if RedisClient == nil {
-		return 0, fmt.Errorf("redis client not initialized")
+		var deleted int64
+		for _, pattern := range patterns {
+			deleted += memory.deleteByPattern(pattern)
+		}
+		return deleted, nil
 	}
 
 	var deleted int64
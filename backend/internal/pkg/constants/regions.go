@@ -0,0 +1,278 @@
+package constants
+
+// Region 省/州与城市信息；Aliases 为常见简称（如 "广西"、"恩施州"），校验时同样视为有效
+type Region struct {
+	Code    string   `json:"code,omitempty"`
+	NameZH  string   `json:"name_zh"`
+	NameEN  string   `json:"name_en"`
+	Aliases []string `json:"aliases,omitempty"`
+	Cities  []Region `json:"cities,omitempty"`
+}
+
+func province(code, nameZH, nameEN string, aliases []string, cities ...Region) Region {
+	return Region{Code: code, NameZH: nameZH, NameEN: nameEN, Aliases: aliases, Cities: cities}
+}
+
+func city(nameZH, nameEN string, aliases ...string) Region {
+	return Region{NameZH: nameZH, NameEN: nameEN, Aliases: aliases}
+}
+
+func state(code, nameZH, nameEN string) Region {
+	return Region{Code: code, NameZH: nameZH, NameEN: nameEN}
+}
+
+// Regions 国家 → 省/州 → 城市 数据集；未收录的国家省市为自由填写。
+// 中国大陆收录省级行政区及地级（含省直辖县级）行政区，美国、加拿大、澳大利亚仅收录州/省
+var Regions = map[string][]Region{
+	"CN": {
+		province("BJ", "北京市", "Beijing", nil, city("北京市", "Beijing", "市辖区")),
+		province("TJ", "天津市", "Tianjin", nil, city("天津市", "Tianjin", "市辖区")),
+		province("HE", "河北省", "Hebei", nil,
+			city("石家庄市", "Shijiazhuang"), city("唐山市", "Tangshan"), city("秦皇岛市", "Qinhuangdao"),
+			city("邯郸市", "Handan"), city("邢台市", "Xingtai"), city("保定市", "Baoding"),
+			city("张家口市", "Zhangjiakou"), city("承德市", "Chengde"), city("沧州市", "Cangzhou"),
+			city("廊坊市", "Langfang"), city("衡水市", "Hengshui"),
+		),
+		province("SX", "山西省", "Shanxi", nil,
+			city("太原市", "Taiyuan"), city("大同市", "Datong"), city("阳泉市", "Yangquan"),
+			city("长治市", "Changzhi"), city("晋城市", "Jincheng"), city("朔州市", "Shuozhou"),
+			city("晋中市", "Jinzhong"), city("运城市", "Yuncheng"), city("忻州市", "Xinzhou"),
+			city("临汾市", "Linfen"), city("吕梁市", "Lvliang"),
+		),
+		province("NM", "内蒙古自治区", "Inner Mongolia", []string{"内蒙古"},
+			city("呼和浩特市", "Hohhot"), city("包头市", "Baotou"), city("乌海市", "Wuhai"),
+			city("赤峰市", "Chifeng"), city("通辽市", "Tongliao"), city("鄂尔多斯市", "Ordos"),
+			city("呼伦贝尔市", "Hulunbuir"), city("巴彦淖尔市", "Bayannur"), city("乌兰察布市", "Ulanqab"),
+			city("兴安盟", "Hinggan"), city("锡林郭勒盟", "Xilingol"), city("阿拉善盟", "Alxa"),
+		),
+		province("LN", "辽宁省", "Liaoning", nil,
+			city("沈阳市", "Shenyang"), city("大连市", "Dalian"), city("鞍山市", "Anshan"),
+			city("抚顺市", "Fushun"), city("本溪市", "Benxi"), city("丹东市", "Dandong"),
+			city("锦州市", "Jinzhou"), city("营口市", "Yingkou"), city("阜新市", "Fuxin"),
+			city("辽阳市", "Liaoyang"), city("盘锦市", "Panjin"), city("铁岭市", "Tieling"),
+			city("朝阳市", "Chaoyang"), city("葫芦岛市", "Huludao"),
+		),
+		province("JL", "吉林省", "Jilin", nil,
+			city("长春市", "Changchun"), city("吉林市", "Jilin"), city("四平市", "Siping"),
+			city("辽源市", "Liaoyuan"), city("通化市", "Tonghua"), city("白山市", "Baishan"),
+			city("松原市", "Songyuan"), city("白城市", "Baicheng"),
+			city("延边朝鲜族自治州", "Yanbian", "延边", "延边州"),
+		),
+		province("HL", "黑龙江省", "Heilongjiang", nil,
+			city("哈尔滨市", "Harbin"), city("齐齐哈尔市", "Qiqihar"), city("鸡西市", "Jixi"),
+			city("鹤岗市", "Hegang"), city("双鸭山市", "Shuangyashan"), city("大庆市", "Daqing"),
+			city("伊春市", "Yichun"), city("佳木斯市", "Jiamusi"), city("七台河市", "Qitaihe"),
+			city("牡丹江市", "Mudanjiang"), city("黑河市", "Heihe"), city("绥化市", "Suihua"),
+			city("大兴安岭地区", "Da Hinggan Ling"),
+		),
+		province("SH", "上海市", "Shanghai", nil, city("上海市", "Shanghai", "市辖区")),
+		province("JS", "江苏省", "Jiangsu", nil,
+			city("南京市", "Nanjing"), city("无锡市", "Wuxi"), city("徐州市", "Xuzhou"),
+			city("常州市", "Changzhou"), city("苏州市", "Suzhou"), city("南通市", "Nantong"),
+			city("连云港市", "Lianyungang"), city("淮安市", "Huai'an"), city("盐城市", "Yancheng"),
+			city("扬州市", "Yangzhou"), city("镇江市", "Zhenjiang"), city("泰州市", "Taizhou"),
+			city("宿迁市", "Suqian"),
+		),
+		province("ZJ", "浙江省", "Zhejiang", nil,
+			city("杭州市", "Hangzhou"), city("宁波市", "Ningbo"), city("温州市", "Wenzhou"),
+			city("嘉兴市", "Jiaxing"), city("湖州市", "Huzhou"), city("绍兴市", "Shaoxing"),
+			city("金华市", "Jinhua"), city("衢州市", "Quzhou"), city("舟山市", "Zhoushan"),
+			city("台州市", "Taizhou"), city("丽水市", "Lishui"),
+		),
+		province("AH", "安徽省", "Anhui", nil,
+			city("合肥市", "Hefei"), city("芜湖市", "Wuhu"), city("蚌埠市", "Bengbu"),
+			city("淮南市", "Huainan"), city("马鞍山市", "Ma'anshan"), city("淮北市", "Huaibei"),
+			city("铜陵市", "Tongling"), city("安庆市", "Anqing"), city("黄山市", "Huangshan"),
+			city("滁州市", "Chuzhou"), city("阜阳市", "Fuyang"), city("宿州市", "Suzhou"),
+			city("六安市", "Lu'an"), city("亳州市", "Bozhou"), city("池州市", "Chizhou"),
+			city("宣城市", "Xuancheng"),
+		),
+		province("FJ", "福建省", "Fujian", nil,
+			city("福州市", "Fuzhou"), city("厦门市", "Xiamen"), city("莆田市", "Putian"),
+			city("三明市", "Sanming"), city("泉州市", "Quanzhou"), city("漳州市", "Zhangzhou"),
+			city("南平市", "Nanping"), city("龙岩市", "Longyan"), city("宁德市", "Ningde"),
+		),
+		province("JX", "江西省", "Jiangxi", nil,
+			city("南昌市", "Nanchang"), city("景德镇市", "Jingdezhen"), city("萍乡市", "Pingxiang"),
+			city("九江市", "Jiujiang"), city("新余市", "Xinyu"), city("鹰潭市", "Yingtan"),
+			city("赣州市", "Ganzhou"), city("吉安市", "Ji'an"), city("宜春市", "Yichun"),
+			city("抚州市", "Fuzhou"), city("上饶市", "Shangrao"),
+		),
+		province("SD", "山东省", "Shandong", nil,
+			city("济南市", "Jinan"), city("青岛市", "Qingdao"), city("淄博市", "Zibo"),
+			city("枣庄市", "Zaozhuang"), city("东营市", "Dongying"), city("烟台市", "Yantai"),
+			city("潍坊市", "Weifang"), city("济宁市", "Jining"), city("泰安市", "Tai'an"),
+			city("威海市", "Weihai"), city("日照市", "Rizhao"), city("临沂市", "Linyi"),
+			city("德州市", "Dezhou"), city("聊城市", "Liaocheng"), city("滨州市", "Binzhou"),
+			city("菏泽市", "Heze"),
+		),
+		province("HA", "河南省", "Henan", nil,
+			city("郑州市", "Zhengzhou"), city("开封市", "Kaifeng"), city("洛阳市", "Luoyang"),
+			city("平顶山市", "Pingdingshan"), city("安阳市", "Anyang"), city("鹤壁市", "Hebi"),
+			city("新乡市", "Xinxiang"), city("焦作市", "Jiaozuo"), city("濮阳市", "Puyang"),
+			city("许昌市", "Xuchang"), city("漯河市", "Luohe"), city("三门峡市", "Sanmenxia"),
+			city("南阳市", "Nanyang"), city("商丘市", "Shangqiu"), city("信阳市", "Xinyang"),
+			city("周口市", "Zhoukou"), city("驻马店市", "Zhumadian"), city("济源市", "Jiyuan"),
+		),
+		province("HB", "湖北省", "Hubei", nil,
+			city("武汉市", "Wuhan"), city("黄石市", "Huangshi"), city("十堰市", "Shiyan"),
+			city("宜昌市", "Yichang"), city("襄阳市", "Xiangyang"), city("鄂州市", "Ezhou"),
+			city("荆门市", "Jingmen"), city("孝感市", "Xiaogan"), city("荆州市", "Jingzhou"),
+			city("黄冈市", "Huanggang"), city("咸宁市", "Xianning"), city("随州市", "Suizhou"),
+			city("恩施土家族苗族自治州", "Enshi", "恩施", "恩施州"),
+			city("仙桃市", "Xiantao"), city("潜江市", "Qianjiang"), city("天门市", "Tianmen"),
+			city("神农架林区", "Shennongjia", "神农架"),
+		),
+		province("HN", "湖南省", "Hunan", nil,
+			city("长沙市", "Changsha"), city("株洲市", "Zhuzhou"), city("湘潭市", "Xiangtan"),
+			city("衡阳市", "Hengyang"), city("邵阳市", "Shaoyang"), city("岳阳市", "Yueyang"),
+			city("常德市", "Changde"), city("张家界市", "Zhangjiajie"), city("益阳市", "Yiyang"),
+			city("郴州市", "Chenzhou"), city("永州市", "Yongzhou"), city("怀化市", "Huaihua"),
+			city("娄底市", "Loudi"), city("湘西土家族苗族自治州", "Xiangxi", "湘西", "湘西州"),
+		),
+		province("GD", "广东省", "Guangdong", nil,
+			city("广州市", "Guangzhou"), city("韶关市", "Shaoguan"), city("深圳市", "Shenzhen"),
+			city("珠海市", "Zhuhai"), city("汕头市", "Shantou"), city("佛山市", "Foshan"),
+			city("江门市", "Jiangmen"), city("湛江市", "Zhanjiang"), city("茂名市", "Maoming"),
+			city("肇庆市", "Zhaoqing"), city("惠州市", "Huizhou"), city("梅州市", "Meizhou"),
+			city("汕尾市", "Shanwei"), city("河源市", "Heyuan"), city("阳江市", "Yangjiang"),
+			city("清远市", "Qingyuan"), city("东莞市", "Dongguan"), city("中山市", "Zhongshan"),
+			city("潮州市", "Chaozhou"), city("揭阳市", "Jieyang"), city("云浮市", "Yunfu"),
+		),
+		province("GX", "广西壮族自治区", "Guangxi", []string{"广西"},
+			city("南宁市", "Nanning"), city("柳州市", "Liuzhou"), city("桂林市", "Guilin"),
+			city("梧州市", "Wuzhou"), city("北海市", "Beihai"), city("防城港市", "Fangchenggang"),
+			city("钦州市", "Qinzhou"), city("贵港市", "Guigang"), city("玉林市", "Yulin"),
+			city("百色市", "Baise"), city("贺州市", "Hezhou"), city("河池市", "Hechi"),
+			city("来宾市", "Laibin"), city("崇左市", "Chongzuo"),
+		),
+		province("HI", "海南省", "Hainan", nil,
+			city("海口市", "Haikou"), city("三亚市", "Sanya"), city("三沙市", "Sansha"),
+			city("儋州市", "Danzhou"), city("五指山市", "Wuzhishan"), city("琼海市", "Qionghai"),
+			city("文昌市", "Wenchang"), city("万宁市", "Wanning"), city("东方市", "Dongfang"),
+			city("定安县", "Ding'an"), city("屯昌县", "Tunchang"), city("澄迈县", "Chengmai"),
+			city("临高县", "Lingao"), city("白沙黎族自治县", "Baisha", "白沙"),
+			city("昌江黎族自治县", "Changjiang", "昌江"), city("乐东黎族自治县", "Ledong", "乐东"),
+			city("陵水黎族自治县", "Lingshui", "陵水"), city("保亭黎族苗族自治县", "Baoting", "保亭"),
+			city("琼中黎族苗族自治县", "Qiongzhong", "琼中"),
+		),
+		province("CQ", "重庆市", "Chongqing", nil, city("重庆市", "Chongqing", "市辖区", "县")),
+		province("SC", "四川省", "Sichuan", nil,
+			city("成都市", "Chengdu"), city("自贡市", "Zigong"), city("攀枝花市", "Panzhihua"),
+			city("泸州市", "Luzhou"), city("德阳市", "Deyang"), city("绵阳市", "Mianyang"),
+			city("广元市", "Guangyuan"), city("遂宁市", "Suining"), city("内江市", "Neijiang"),
+			city("乐山市", "Leshan"), city("南充市", "Nanchong"), city("眉山市", "Meishan"),
+			city("宜宾市", "Yibin"), city("广安市", "Guang'an"), city("达州市", "Dazhou"),
+			city("雅安市", "Ya'an"), city("巴中市", "Bazhong"), city("资阳市", "Ziyang"),
+			city("阿坝藏族羌族自治州", "Ngawa", "阿坝", "阿坝州"),
+			city("甘孜藏族自治州", "Garze", "甘孜", "甘孜州"),
+			city("凉山彝族自治州", "Liangshan", "凉山", "凉山州"),
+		),
+		province("GZ", "贵州省", "Guizhou", nil,
+			city("贵阳市", "Guiyang"), city("六盘水市", "Liupanshui"), city("遵义市", "Zunyi"),
+			city("安顺市", "Anshun"), city("毕节市", "Bijie"), city("铜仁市", "Tongren"),
+			city("黔西南布依族苗族自治州", "Qianxinan", "黔西南", "黔西南州"),
+			city("黔东南苗族侗族自治州", "Qiandongnan", "黔东南", "黔东南州"),
+			city("黔南布依族苗族自治州", "Qiannan", "黔南", "黔南州"),
+		),
+		province("YN", "云南省", "Yunnan", nil,
+			city("昆明市", "Kunming"), city("曲靖市", "Qujing"), city("玉溪市", "Yuxi"),
+			city("保山市", "Baoshan"), city("昭通市", "Zhaotong"), city("丽江市", "Lijiang"),
+			city("普洱市", "Pu'er"), city("临沧市", "Lincang"),
+			city("楚雄彝族自治州", "Chuxiong", "楚雄", "楚雄州"),
+			city("红河哈尼族彝族自治州", "Honghe", "红河", "红河州"),
+			city("文山壮族苗族自治州", "Wenshan", "文山", "文山州"),
+			city("西双版纳傣族自治州", "Xishuangbanna", "西双版纳", "版纳"),
+			city("大理白族自治州", "Dali", "大理", "大理州"),
+			city("德宏傣族景颇族自治州", "Dehong", "德宏", "德宏州"),
+			city("怒江傈僳族自治州", "Nujiang", "怒江", "怒江州"),
+			city("迪庆藏族自治州", "Diqing", "迪庆", "迪庆州"),
+		),
+		province("XZ", "西藏自治区", "Tibet", []string{"西藏"},
+			city("拉萨市", "Lhasa"), city("日喀则市", "Shigatse"), city("昌都市", "Qamdo"),
+			city("林芝市", "Nyingchi"), city("山南市", "Shannan"), city("那曲市", "Nagqu"),
+			city("阿里地区", "Ngari"),
+		),
+		province("SN", "陕西省", "Shaanxi", nil,
+			city("西安市", "Xi'an"), city("铜川市", "Tongchuan"), city("宝鸡市", "Baoji"),
+			city("咸阳市", "Xianyang"), city("渭南市", "Weinan"), city("延安市", "Yan'an"),
+			city("汉中市", "Hanzhong"), city("榆林市", "Yulin"), city("安康市", "Ankang"),
+			city("商洛市", "Shangluo"),
+		),
+		province("GS", "甘肃省", "Gansu", nil,
+			city("兰州市", "Lanzhou"), city("嘉峪关市", "Jiayuguan"), city("金昌市", "Jinchang"),
+			city("白银市", "Baiyin"), city("天水市", "Tianshui"), city("武威市", "Wuwei"),
+			city("张掖市", "Zhangye"), city("平凉市", "Pingliang"), city("酒泉市", "Jiuquan"),
+			city("庆阳市", "Qingyang"), city("定西市", "Dingxi"), city("陇南市", "Longnan"),
+			city("临夏回族自治州", "Linxia", "临夏", "临夏州"),
+			city("甘南藏族自治州", "Gannan", "甘南", "甘南州"),
+		),
+		province("QH", "青海省", "Qinghai", nil,
+			city("西宁市", "Xining"), city("海东市", "Haidong"),
+			city("海北藏族自治州", "Haibei", "海北", "海北州"),
+			city("黄南藏族自治州", "Huangnan", "黄南", "黄南州"),
+			city("海南藏族自治州", "Hainan Prefecture", "海南州"),
+			city("果洛藏族自治州", "Golog", "果洛", "果洛州"),
+			city("玉树藏族自治州", "Yushu", "玉树", "玉树州"),
+			city("海西蒙古族藏族自治州", "Haixi", "海西", "海西州"),
+		),
+		province("NX", "宁夏回族自治区", "Ningxia", []string{"宁夏"},
+			city("银川市", "Yinchuan"), city("石嘴山市", "Shizuishan"), city("吴忠市", "Wuzhong"),
+			city("固原市", "Guyuan"), city("中卫市", "Zhongwei"),
+		),
+		province("XJ", "新疆维吾尔自治区", "Xinjiang", []string{"新疆"},
+			city("乌鲁木齐市", "Urumqi"), city("克拉玛依市", "Karamay"), city("吐鲁番市", "Turpan"),
+			city("哈密市", "Hami"), city("昌吉回族自治州", "Changji", "昌吉", "昌吉州"),
+			city("博尔塔拉蒙古自治州", "Bortala", "博尔塔拉", "博州"),
+			city("巴音郭楞蒙古自治州", "Bayingolin", "巴音郭楞", "巴州"),
+			city("阿克苏地区", "Aksu"),
+			city("克孜勒苏柯尔克孜自治州", "Kizilsu", "克孜勒苏", "克州"),
+			city("喀什地区", "Kashgar"), city("和田地区", "Hotan"),
+			city("伊犁哈萨克自治州", "Ili", "伊犁", "伊犁州"),
+			city("塔城地区", "Tacheng"), city("阿勒泰地区", "Altay"),
+			city("石河子市", "Shihezi"), city("阿拉尔市", "Aral"), city("图木舒克市", "Tumxuk"),
+			city("五家渠市", "Wujiaqu"), city("北屯市", "Beitun"), city("铁门关市", "Tiemenguan"),
+			city("双河市", "Shuanghe"), city("可克达拉市", "Kokdala"), city("昆玉市", "Kunyu"),
+			city("胡杨河市", "Huyanghe"), city("新星市", "Xinxing"), city("白杨市", "Baiyang"),
+		),
+	},
+	"US": {
+		state("AL", "阿拉巴马州", "Alabama"), state("AK", "阿拉斯加州", "Alaska"), state("AZ", "亚利桑那州", "Arizona"),
+		state("AR", "阿肯色州", "Arkansas"), state("CA", "加利福尼亚州", "California"), state("CO", "科罗拉多州", "Colorado"),
+		state("CT", "康涅狄格州", "Connecticut"), state("DE", "特拉华州", "Delaware"), state("DC", "哥伦比亚特区", "District of Columbia"),
+		state("FL", "佛罗里达州", "Florida"), state("GA", "佐治亚州", "Georgia"), state("HI", "夏威夷州", "Hawaii"),
+		state("ID", "爱达荷州", "Idaho"), state("IL", "伊利诺伊州", "Illinois"), state("IN", "印第安纳州", "Indiana"),
+		state("IA", "艾奥瓦州", "Iowa"), state("KS", "堪萨斯州", "Kansas"), state("KY", "肯塔基州", "Kentucky"),
+		state("LA", "路易斯安那州", "Louisiana"), state("ME", "缅因州", "Maine"), state("MD", "马里兰州", "Maryland"),
+		state("MA", "马萨诸塞州", "Massachusetts"), state("MI", "密歇根州", "Michigan"), state("MN", "明尼苏达州", "Minnesota"),
+		state("MS", "密西西比州", "Mississippi"), state("MO", "密苏里州", "Missouri"), state("MT", "蒙大拿州", "Montana"),
+		state("NE", "内布拉斯加州", "Nebraska"), state("NV", "内华达州", "Nevada"), state("NH", "新罕布什尔州", "New Hampshire"),
+		state("NJ", "新泽西州", "New Jersey"), state("NM", "新墨西哥州", "New Mexico"), state("NY", "纽约州", "New York"),
+		state("NC", "北卡罗来纳州", "North Carolina"), state("ND", "北达科他州", "North Dakota"), state("OH", "俄亥俄州", "Ohio"),
+		state("OK", "俄克拉何马州", "Oklahoma"), state("OR", "俄勒冈州", "Oregon"), state("PA", "宾夕法尼亚州", "Pennsylvania"),
+		state("RI", "罗得岛州", "Rhode Island"), state("SC", "南卡罗来纳州", "South Carolina"), state("SD", "南达科他州", "South Dakota"),
+		state("TN", "田纳西州", "Tennessee"), state("TX", "得克萨斯州", "Texas"), state("UT", "犹他州", "Utah"),
+		state("VT", "佛蒙特州", "Vermont"), state("VA", "弗吉尼亚州", "Virginia"), state("WA", "华盛顿州", "Washington"),
+		state("WV", "西弗吉尼亚州", "West Virginia"), state("WI", "威斯康星州", "Wisconsin"), state("WY", "怀俄明州", "Wyoming"),
+		state("AS", "美属萨摩亚", "American Samoa"), state("GU", "关岛", "Guam"), state("MP", "北马里亚纳群岛", "Northern Mariana Islands"),
+		state("PR", "波多黎各", "Puerto Rico"), state("VI", "美属维尔京群岛", "U.S. Virgin Islands"), state("AA", "美洲武装部队", "Armed Forces Americas"),
+		state("AE", "欧洲武装部队", "Armed Forces Europe"), state("AP", "太平洋武装部队", "Armed Forces Pacific"),
+	},
+	"CA": {
+		state("AB", "艾伯塔省", "Alberta"), state("BC", "不列颠哥伦比亚省", "British Columbia"), state("MB", "马尼托巴省", "Manitoba"),
+		state("NB", "新不伦瑞克省", "New Brunswick"), state("NL", "纽芬兰与拉布拉多省", "Newfoundland and Labrador"), state("NS", "新斯科舍省", "Nova Scotia"),
+		state("NT", "西北地区", "Northwest Territories"), state("NU", "努纳武特地区", "Nunavut"), state("ON", "安大略省", "Ontario"),
+		state("PE", "爱德华王子岛省", "Prince Edward Island"), state("QC", "魁北克省", "Quebec"), state("SK", "萨斯喀彻温省", "Saskatchewan"),
+		state("YT", "育空地区", "Yukon"),
+	},
+	"AU": {
+		state("ACT", "澳大利亚首都领地", "Australian Capital Territory"), state("NSW", "新南威尔士州", "New South Wales"), state("NT", "北领地", "Northern Territory"),
+		state("QLD", "昆士兰州", "Queensland"), state("SA", "南澳大利亚州", "South Australia"), state("TAS", "塔斯马尼亚州", "Tasmania"),
+		state("VIC", "维多利亚州", "Victoria"), state("WA", "西澳大利亚州", "Western Australia"),
+	},
+}
+
+// GetRegions 返回国家的省/州列表，未收录时返回 nil
+func GetRegions(countryCode string) []Region {
+	return Regions[countryCode]
+}
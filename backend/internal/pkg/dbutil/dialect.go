@@ -0,0 +1,90 @@
+package dbutil
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 支持的数据库方言（与 database.driver 配置一致）
+const (
+	DialectSQLite   = "sqlite"
+	DialectMySQL    = "mysql"
+	DialectPostgres = "postgres"
+)
+
+// likeEscapeChar LIKE 转义字符；不用反斜杠，避免 MySQL 字符串字面量再转义一次
+const likeEscapeChar = "!"
+
+// Dialect 返回连接使用的方言名称
+func Dialect(db *gorm.DB) string {
+	if db == nil || db.Dialector == nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(db.Dialector.Name()))
+}
+
+// RandomOrderExpr 随机排序表达式（MySQL 为 RAND()，其余为 RANDOM()）
+// 仅适用于小结果集；大表随机抽样请使用索引随机键
+func RandomOrderExpr(dialect string) string {
+	if dialect == DialectMySQL {
+		return "RAND()"
+	}
+	return "RANDOM()"
+}
+
+// OrderRandom 按方言追加随机排序
+func OrderRandom(query *gorm.DB) *gorm.DB {
+	return query.Order(RandomOrderExpr(Dialect(query)))
+}
+
+// SumAsInt64Expr 对整数列求和并转成 64 位整数，空集合返回 0
+// Postgres SUM(bigint) 返回 numeric，MySQL 返回 DECIMAL，统一转换避免扫描到 int64 时出错
+func SumAsInt64Expr(dialect, column string) string {
+	switch dialect {
+	case DialectPostgres:
+		return fmt.Sprintf("COALESCE(SUM(%s)::bigint, 0)", column)
+	case DialectMySQL:
+		return fmt.Sprintf("COALESCE(CAST(SUM(%s) AS SIGNED), 0)", column)
+	default:
+		return fmt.Sprintf("COALESCE(CAST(SUM(%s) AS INTEGER), 0)", column)
+	}
+}
+
+// EscapeLike 转义 LIKE 通配符，配合 ESCAPE '!' 使用
+func EscapeLike(value string) string {
+	replacer := strings.NewReplacer(likeEscapeChar, likeEscapeChar+likeEscapeChar, "%", likeEscapeChar+"%", "_", likeEscapeChar+"_")
+	return replacer.Replace(value)
+}
+
+// WhereContainsFold 大小写不敏感的包含匹配，任一列命中即可
+// Postgres 的 LIKE 区分大小写、ILIKE 在其他库不可用，统一用 LOWER() LIKE 并转义用户输入中的通配符
+func WhereContainsFold(query *gorm.DB, term string, columns ...string) *gorm.DB {
+	term = strings.TrimSpace(term)
+	if term == "" || len(columns) == 0 {
+		return query
+	}
+	pattern := "%" + EscapeLike(strings.ToLower(term)) + "%"
+	conditions := make([]string, 0, len(columns))
+	args := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		conditions = append(conditions, fmt.Sprintf("LOWER(%s) LIKE ? ESCAPE '%s'", column, likeEscapeChar))
+		args = append(args, pattern)
+	}
+	return query.Where(strings.Join(conditions, " OR "), args...)
+}
+
+// UpsertOnConflict 按唯一键冲突时更新指定字段
+// GORM 会按方言生成 ON CONFLICT / ON DUPLICATE KEY UPDATE，MySQL 下冲突列由唯一索引决定
+func UpsertOnConflict(conflictColumns []string, updates map[string]interface{}) clause.OnConflict {
+	columns := make([]clause.Column, 0, len(conflictColumns))
+	for _, name := range conflictColumns {
+		columns = append(columns, clause.Column{Name: name})
+	}
+	return clause.OnConflict{
+		Columns:   columns,
+		DoUpdates: clause.Assignments(updates),
+	}
+}
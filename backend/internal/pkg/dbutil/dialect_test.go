@@ -0,0 +1,141 @@
+package dbutil
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type dialectTestRow struct {
+	ID     uint   `gorm:"primaryKey"`
+	Code   string `gorm:"type:varchar(50);uniqueIndex"`
+	Name   string `gorm:"type:varchar(100)"`
+	Amount int64
+}
+
+func (dialectTestRow) TableName() string {
+	return "dbutil_dialect_test_rows"
+}
+
+// openDialectTestDB 默认使用内存 SQLite；CI 通过 AURALOGIC_TEST_DB_DRIVER/DSN 切换到 MySQL、Postgres
+func openDialectTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	driver := os.Getenv("AURALOGIC_TEST_DB_DRIVER")
+	dsn := os.Getenv("AURALOGIC_TEST_DB_DSN")
+	var dialector gorm.Dialector
+	switch driver {
+	case "", DialectSQLite:
+		if dsn == "" {
+			dsn = "file:dbutil-dialect?mode=memory&cache=shared"
+		}
+		dialector = sqlite.Open(dsn)
+	case DialectMySQL:
+		dialector = mysql.Open(dsn)
+	case DialectPostgres:
+		dialector = postgres.Open(dsn)
+	default:
+		t.Fatalf("unsupported AURALOGIC_TEST_DB_DRIVER %q", driver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open %s failed: %v", driver, err)
+	}
+	_ = db.Migrator().DropTable(&dialectTestRow{})
+	if err := db.AutoMigrate(&dialectTestRow{}); err != nil {
+		t.Fatalf("auto migrate failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Migrator().DropTable(&dialectTestRow{})
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	rows := []dialectTestRow{
+		{Code: "A1", Name: "Alpha_One", Amount: 3000000000},
+		{Code: "A2", Name: "ALPHA two", Amount: 5},
+		{Code: "B1", Name: "beta 100%", Amount: 7},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatalf("seed rows failed: %v", err)
+	}
+	return db
+}
+
+func TestWhereContainsFoldIsCaseInsensitiveAndEscapesWildcards(t *testing.T) {
+	db := openDialectTestDB(t)
+
+	for _, tc := range []struct {
+		term string
+		want []string
+	}{
+		{"alpha", []string{"A1", "A2"}},
+		{"Alpha_", []string{"A1"}},
+		{"_", []string{"A1"}},
+		{"100%", []string{"B1"}},
+		{"%", []string{"B1"}},
+		{"", []string{"A1", "A2", "B1"}},
+	} {
+		var codes []string
+		query := WhereContainsFold(db.Model(&dialectTestRow{}), tc.term, "name", "code")
+		if err := query.Pluck("code", &codes).Error; err != nil {
+			t.Fatalf("search %q failed: %v", tc.term, err)
+		}
+		sort.Strings(codes)
+		if strings.Join(codes, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("search %q: expected %v, got %v", tc.term, tc.want, codes)
+		}
+	}
+}
+
+func TestOrderRandomAndSumAsInt64(t *testing.T) {
+	db := openDialectTestDB(t)
+
+	var rows []dialectTestRow
+	if err := OrderRandom(db.Model(&dialectTestRow{})).Find(&rows).Error; err != nil || len(rows) != 3 {
+		t.Fatalf("random order failed: rows=%d err=%v", len(rows), err)
+	}
+
+	var total int64
+	if err := db.Model(&dialectTestRow{}).Select(SumAsInt64Expr(Dialect(db), "amount")).Scan(&total).Error; err != nil {
+		t.Fatalf("sum failed: %v", err)
+	}
+	if total != 3000000012 {
+		t.Fatalf("expected int64 sum 3000000012, got %d", total)
+	}
+
+	total = -1
+	if err := db.Model(&dialectTestRow{}).Where("code = ?", "missing").Select(SumAsInt64Expr(Dialect(db), "amount")).Scan(&total).Error; err != nil || total != 0 {
+		t.Fatalf("empty sum should be 0, got %d (%v)", total, err)
+	}
+}
+
+func TestUpsertOnConflictUpdatesExistingRow(t *testing.T) {
+	db := openDialectTestDB(t)
+
+	row := dialectTestRow{Code: "A2", Name: "replaced", Amount: 9}
+	if err := db.Clauses(UpsertOnConflict([]string{"code"}, map[string]interface{}{
+		"name":   row.Name,
+		"amount": row.Amount,
+	})).Create(&row).Error; err != nil {
+		t.Fatalf("upsert failed: %v", err)
+	}
+
+	var count int64
+	db.Model(&dialectTestRow{}).Count(&count)
+	var stored dialectTestRow
+	if err := db.Where("code = ?", "A2").First(&stored).Error; err != nil {
+		t.Fatalf("load upserted row failed: %v", err)
+	}
+	if count != 3 || stored.Name != "replaced" || stored.Amount != 9 {
+		t.Fatalf("expected in-place update, got count=%d row=%+v", count, stored)
+	}
+}
@@ -0,0 +1,39 @@
+package demomode
+
+import (
+	"net/http"
+
+	"auralogic/internal/config"
+	"auralogic/internal/pkg/bizerr"
+)
+
+// ErrOutboundBlocked 演示模式下拒绝对外请求
+var ErrOutboundBlocked = bizerr.New("demo.outboundBlocked", "Outbound requests are disabled in demo mode")
+
+// Enabled 是否处于演示模式（随配置热更新）
+func Enabled() bool {
+	cfg := config.GetConfig()
+	return cfg != nil && cfg.App.DemoMode
+}
+
+type blockingTransport struct {
+	base http.RoundTripper
+}
+
+func (t blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if Enabled() {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, ErrOutboundBlocked
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Transport 包装 base（为 nil 时使用 http.DefaultTransport），演示模式下请求直接失败而不发出
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return blockingTransport{base: base}
+}
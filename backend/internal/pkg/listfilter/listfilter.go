@@ -0,0 +1,485 @@
+// Package listfilter 管理后台列表的高级筛选 DSL：
+// 条件按字段白名单编译为参数化 SQL，支持 AND/OR 分组嵌套、日期/金额区间和标签匹配。
+package listfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+const (
+	MaxDepth      = 3
+	MaxConditions = 30
+	MaxListValues = 100
+)
+
+// Kind 字段类型，决定可用运算符和值的解析方式
+type Kind string
+
+const (
+	KindString Kind = "string"
+	KindNumber Kind = "number"
+	KindDate   Kind = "date"
+	KindBool   Kind = "bool"
+	KindTags   Kind = "tags" // JSON 数组文本列
+)
+
+var operatorsByKind = map[Kind][]string{
+	KindString: {"eq", "neq", "contains", "not_contains", "starts_with", "in", "not_in", "empty", "not_empty"},
+	KindNumber: {"eq", "neq", "gt", "gte", "lt", "lte", "between", "empty", "not_empty"},
+	KindDate:   {"before", "after", "between", "on", "last_days", "empty", "not_empty"},
+	KindBool:   {"eq"},
+	KindTags:   {"contains", "not_contains", "empty", "not_empty"},
+}
+
+// Field 可筛选字段，Column 必须是可信的列名
+type Field struct {
+	Column string
+	Kind   Kind
+}
+
+// Schema 字段名 → 列定义
+type Schema map[string]Field
+
+// FieldMeta 供前端筛选构建器使用的字段描述
+type FieldMeta struct {
+	Field     string   `json:"field"`
+	Kind      Kind     `json:"kind"`
+	Operators []string `json:"operators"`
+}
+
+// Fields 按字段名排序返回字段描述
+func (s Schema) Fields() []FieldMeta {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]FieldMeta, 0, len(names))
+	for _, name := range names {
+		field := s[name]
+		fields = append(fields, FieldMeta{Field: name, Kind: field.Kind, Operators: operatorsByKind[field.Kind]})
+	}
+	return fields
+}
+
+// Filter 条件组：match 为 all(AND) 或 any(OR)，groups 为嵌套子组
+type Filter struct {
+	Match      string      `json:"match"`
+	Conditions []Condition `json:"conditions"`
+	Groups     []Filter    `json:"groups,omitempty"`
+}
+
+// Condition 单个条件
+type Condition struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value,omitempty"`
+}
+
+// Expr 编译后的 WHERE 片段，nil 表示不过滤
+type Expr struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Apply 将条件追加到查询
+func (e *Expr) Apply(query *gorm.DB) *gorm.DB {
+	if e == nil || e.SQL == "" {
+		return query
+	}
+	return query.Where(e.SQL, e.Args...)
+}
+
+// Parse 解析 JSON 形式的筛选条件，空字符串返回 nil
+func Parse(raw string, schema Schema) (*Expr, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	var filter Filter
+	if err := json.Unmarshal([]byte(raw), &filter); err != nil {
+		return nil, newInvalidError("filter must be valid JSON")
+	}
+	return Compile(&filter, schema)
+}
+
+// Compile 校验并编译筛选条件
+func Compile(filter *Filter, schema Schema) (*Expr, error) {
+	if filter == nil {
+		return nil, nil
+	}
+	c := &compiler{schema: schema, now: time.Now()}
+	sql, args, err := c.group(filter, 1)
+	if err != nil {
+		return nil, err
+	}
+	if sql == "" {
+		return nil, nil
+	}
+	return &Expr{SQL: sql, Args: args}, nil
+}
+
+// Validate 仅校验（保存视图时使用）
+func Validate(filter *Filter, schema Schema) error {
+	_, err := Compile(filter, schema)
+	return err
+}
+
+type compiler struct {
+	schema     Schema
+	now        time.Time
+	conditions int
+}
+
+func (c *compiler) group(filter *Filter, depth int) (string, []interface{}, error) {
+	if depth > MaxDepth {
+		return "", nil, newInvalidError(fmt.Sprintf("groups can be nested at most %d levels", MaxDepth))
+	}
+	joiner := " AND "
+	switch strings.ToLower(strings.TrimSpace(filter.Match)) {
+	case "", "all":
+	case "any":
+		joiner = " OR "
+	default:
+		return "", nil, newInvalidError("match must be all or any")
+	}
+
+	parts := make([]string, 0, len(filter.Conditions)+len(filter.Groups))
+	var args []interface{}
+	for i := range filter.Conditions {
+		c.conditions++
+		if c.conditions > MaxConditions {
+			return "", nil, newInvalidError(fmt.Sprintf("at most %d conditions are allowed", MaxConditions))
+		}
+		sql, condArgs, err := c.condition(filter.Conditions[i])
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, sql)
+		args = append(args, condArgs...)
+	}
+	for i := range filter.Groups {
+		sql, groupArgs, err := c.group(&filter.Groups[i], depth+1)
+		if err != nil {
+			return "", nil, err
+		}
+		if sql == "" {
+			continue
+		}
+		parts = append(parts, sql)
+		args = append(args, groupArgs...)
+	}
+	if len(parts) == 0 {
+		return "", nil, nil
+	}
+	return "(" + strings.Join(parts, joiner) + ")", args, nil
+}
+
+func (c *compiler) condition(cond Condition) (string, []interface{}, error) {
+	name := strings.TrimSpace(cond.Field)
+	field, ok := c.schema[name]
+	if !ok {
+		return "", nil, newInvalidError(fmt.Sprintf("unknown field %q", name))
+	}
+	operator := strings.ToLower(strings.TrimSpace(cond.Operator))
+	if !containsString(operatorsByKind[field.Kind], operator) {
+		return "", nil, newInvalidError(fmt.Sprintf("operator %q is not supported for field %q", operator, name))
+	}
+	col := field.Column
+
+	switch operator {
+	case "empty":
+		switch field.Kind {
+		case KindString:
+			return fmt.Sprintf("(%s IS NULL OR %s = '')", col, col), nil, nil
+		case KindTags:
+			return fmt.Sprintf("(%s IS NULL OR %s IN ('', '[]', 'null'))", col, col), nil, nil
+		}
+		return col + " IS NULL", nil, nil
+	case "not_empty":
+		switch field.Kind {
+		case KindString:
+			return fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", col, col), nil, nil
+		case KindTags:
+			return fmt.Sprintf("(%s IS NOT NULL AND %s NOT IN ('', '[]', 'null'))", col, col), nil, nil
+		}
+		return col + " IS NOT NULL", nil, nil
+	}
+
+	switch field.Kind {
+	case KindString:
+		return stringCondition(name, col, operator, cond.Value)
+	case KindNumber:
+		return numberCondition(name, col, operator, cond.Value)
+	case KindDate:
+		return c.dateCondition(name, col, operator, cond.Value)
+	case KindBool:
+		value, ok := toBool(cond.Value)
+		if !ok {
+			return "", nil, newInvalidError(fmt.Sprintf("field %q requires a boolean value", name))
+		}
+		return col + " = ?", []interface{}{value}, nil
+	case KindTags:
+		tag, ok := toString(cond.Value)
+		if !ok || tag == "" {
+			return "", nil, newInvalidError(fmt.Sprintf("field %q requires a tag", name))
+		}
+		encoded, _ := json.Marshal(tag)
+		like := "%" + string(encoded) + "%"
+		if operator == "contains" {
+			return col + " LIKE ?", []interface{}{like}, nil
+		}
+		return fmt.Sprintf("(%s IS NULL OR %s NOT LIKE ?)", col, col), []interface{}{like}, nil
+	}
+	return "", nil, newInvalidError(fmt.Sprintf("field %q cannot be filtered", name))
+}
+
+func stringCondition(name, col, operator string, raw interface{}) (string, []interface{}, error) {
+	if operator == "in" || operator == "not_in" {
+		values := toStringList(raw)
+		if len(values) == 0 || len(values) > MaxListValues {
+			return "", nil, newInvalidError(fmt.Sprintf("field %q requires 1-%d values", name, MaxListValues))
+		}
+		if operator == "in" {
+			return col + " IN ?", []interface{}{values}, nil
+		}
+		return col + " NOT IN ?", []interface{}{values}, nil
+	}
+	value, ok := toString(raw)
+	if !ok {
+		return "", nil, newInvalidError(fmt.Sprintf("field %q requires a string value", name))
+	}
+	switch operator {
+	case "eq":
+		return col + " = ?", []interface{}{value}, nil
+	case "neq":
+		return col + " <> ?", []interface{}{value}, nil
+	case "contains":
+		return col + " LIKE ?", []interface{}{"%" + value + "%"}, nil
+	case "not_contains":
+		return col + " NOT LIKE ?", []interface{}{"%" + value + "%"}, nil
+	case "starts_with":
+		return col + " LIKE ?", []interface{}{value + "%"}, nil
+	}
+	return "", nil, newInvalidError(fmt.Sprintf("operator %q is not supported for field %q", operator, name))
+}
+
+func numberCondition(name, col, operator string, raw interface{}) (string, []interface{}, error) {
+	if operator == "between" {
+		lower, upper, err := rangeBounds(name, raw, func(v interface{}) (interface{}, bool) {
+			n, ok := toNumber(v)
+			return n, ok
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		return rangeSQL(col, lower, upper)
+	}
+	value, ok := toNumber(raw)
+	if !ok {
+		return "", nil, newInvalidError(fmt.Sprintf("field %q requires a numeric value", name))
+	}
+	return comparisonSQL(col, operator, value)
+}
+
+func (c *compiler) dateCondition(name, col, operator string, raw interface{}) (string, []interface{}, error) {
+	switch operator {
+	case "last_days":
+		days, ok := toNumber(raw)
+		if !ok || days <= 0 || days > 3660 {
+			return "", nil, newInvalidError(fmt.Sprintf("field %q requires a day count between 1 and 3660", name))
+		}
+		return col + " >= ?", []interface{}{c.now.AddDate(0, 0, -int(days))}, nil
+	case "between":
+		values, ok := raw.([]interface{})
+		if !ok || len(values) != 2 {
+			return "", nil, newInvalidError(fmt.Sprintf("field %q requires a [from, to] range", name))
+		}
+		parts := make([]string, 0, 2)
+		args := make([]interface{}, 0, 2)
+		for i, bound := range values {
+			if text, isText := bound.(string); bound == nil || (isText && strings.TrimSpace(text) == "") {
+				continue
+			}
+			value, dateOnly, ok := toTime(bound)
+			if !ok {
+				return "", nil, newInvalidError(fmt.Sprintf("field %q has an invalid range bound", name))
+			}
+			switch {
+			case i == 0:
+				parts = append(parts, col+" >= ?")
+			case dateOnly:
+				// 纯日期的上界包含当天
+				parts = append(parts, col+" < ?")
+				value = value.AddDate(0, 0, 1)
+			default:
+				parts = append(parts, col+" <= ?")
+			}
+			args = append(args, value)
+		}
+		if len(parts) == 0 {
+			return "", nil, newInvalidError(fmt.Sprintf("field %q requires at least one range bound", name))
+		}
+		return "(" + strings.Join(parts, " AND ") + ")", args, nil
+	}
+
+	value, dateOnly, ok := toTime(raw)
+	if !ok {
+		return "", nil, newInvalidError(fmt.Sprintf("field %q requires a date (YYYY-MM-DD or RFC3339)", name))
+	}
+	switch operator {
+	case "before":
+		return col + " < ?", []interface{}{value}, nil
+	case "after":
+		if dateOnly {
+			return col + " >= ?", []interface{}{value.AddDate(0, 0, 1)}, nil
+		}
+		return col + " > ?", []interface{}{value}, nil
+	case "on":
+		if !dateOnly {
+			return "", nil, newInvalidError(fmt.Sprintf("field %q requires a date (YYYY-MM-DD) for on", name))
+		}
+		return fmt.Sprintf("(%s >= ? AND %s < ?)", col, col), []interface{}{value, value.AddDate(0, 0, 1)}, nil
+	}
+	return "", nil, newInvalidError(fmt.Sprintf("operator %q is not supported for field %q", operator, name))
+}
+
+// rangeBounds 解析 [min, max]，任一端可为 null 表示不限
+func rangeBounds(name string, raw interface{}, parse func(interface{}) (interface{}, bool)) (interface{}, interface{}, error) {
+	values, ok := raw.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil, nil, newInvalidError(fmt.Sprintf("field %q requires a [min, max] range", name))
+	}
+	bounds := make([]interface{}, 2)
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		if text, isText := value.(string); isText && strings.TrimSpace(text) == "" {
+			continue
+		}
+		parsed, ok := parse(value)
+		if !ok {
+			return nil, nil, newInvalidError(fmt.Sprintf("field %q has an invalid range bound", name))
+		}
+		bounds[i] = parsed
+	}
+	if bounds[0] == nil && bounds[1] == nil {
+		return nil, nil, newInvalidError(fmt.Sprintf("field %q requires at least one range bound", name))
+	}
+	return bounds[0], bounds[1], nil
+}
+
+func rangeSQL(col string, lower, upper interface{}) (string, []interface{}, error) {
+	switch {
+	case lower != nil && upper != nil:
+		return fmt.Sprintf("(%s >= ? AND %s <= ?)", col, col), []interface{}{lower, upper}, nil
+	case lower != nil:
+		return col + " >= ?", []interface{}{lower}, nil
+	default:
+		return col + " <= ?", []interface{}{upper}, nil
+	}
+}
+
+func comparisonSQL(col, operator string, value interface{}) (string, []interface{}, error) {
+	symbols := map[string]string{"eq": "=", "neq": "<>", "gt": ">", "gte": ">=", "lt": "<", "lte": "<="}
+	symbol, ok := symbols[operator]
+	if !ok {
+		return "", nil, newInvalidError(fmt.Sprintf("operator %q is not supported", operator))
+	}
+	return col + " " + symbol + " ?", []interface{}{value}, nil
+}
+
+func newInvalidError(reason string) error {
+	return bizerr.Newf("listFilter.invalid", "Invalid filter: %s", reason).
+		WithParams(map[string]interface{}{"reason": reason})
+}
+
+func toString(value interface{}) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return strings.TrimSpace(typed), true
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(typed), true
+	}
+	return "", false
+}
+
+// toStringList 支持数组或逗号分隔字符串
+func toStringList(value interface{}) []string {
+	var raw []string
+	switch typed := value.(type) {
+	case []interface{}:
+		for _, item := range typed {
+			if text, ok := toString(item); ok {
+				raw = append(raw, text)
+			}
+		}
+	case string:
+		raw = strings.Split(typed, ",")
+	}
+	values := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func toBool(value interface{}) (bool, bool) {
+	switch typed := value.(type) {
+	case bool:
+		return typed, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(typed))
+		return b, err == nil
+	}
+	return false, false
+}
+
+// toTime 支持 YYYY-MM-DD（按 UTC 当天 0 点）和 RFC3339
+func toTime(value interface{}) (time.Time, bool, bool) {
+	text, ok := value.(string)
+	if !ok {
+		return time.Time{}, false, false
+	}
+	text = strings.TrimSpace(text)
+	if t, err := time.Parse("2006-01-02", text); err == nil {
+		return t, true, true
+	}
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t, false, true
+	}
+	return time.Time{}, false, false
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
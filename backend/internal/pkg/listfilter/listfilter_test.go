@@ -0,0 +1,79 @@
+package listfilter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"auralogic/internal/pkg/bizerr"
+)
+
+var testSchema = Schema{
+	"status":     {Column: "orders.status", Kind: KindString},
+	"amount":     {Column: "orders.total_amount", Kind: KindNumber},
+	"created_at": {Column: "orders.created_at", Kind: KindDate},
+	"tags":       {Column: "orders.tags", Kind: KindTags},
+}
+
+func requireInvalidFilter(t *testing.T, err error) {
+	t.Helper()
+	var bizErr *bizerr.Error
+	if !errors.As(err, &bizErr) || bizErr.Key != "listFilter.invalid" {
+		t.Fatalf("expected listFilter.invalid, got %v", err)
+	}
+}
+
+func TestParseCompilesNestedGroups(t *testing.T) {
+	expr, err := Parse(`{"match":"all","conditions":[
+		{"field":"amount","operator":"between","value":[100,null]},
+		{"field":"tags","operator":"contains","value":"vip"}
+	],"groups":[{"match":"any","conditions":[
+		{"field":"status","operator":"in","value":["paid","shipped"]},
+		{"field":"created_at","operator":"between","value":["2026-01-01","2026-01-31"]}
+	]}]}`, testSchema)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	want := "(orders.total_amount >= ? AND orders.tags LIKE ? AND (orders.status IN ? OR (orders.created_at >= ? AND orders.created_at < ?)))"
+	if expr.SQL != want {
+		t.Fatalf("unexpected sql:\n got %s\nwant %s", expr.SQL, want)
+	}
+	if len(expr.Args) != 5 {
+		t.Fatalf("expected 5 args, got %d", len(expr.Args))
+	}
+	if got := expr.Args[1]; got != `%"vip"%` {
+		t.Fatalf("expected json-encoded tag pattern, got %#v", got)
+	}
+
+	empty, err := Parse("  ", testSchema)
+	if err != nil || empty != nil {
+		t.Fatalf("expected nil expr for empty filter, got %#v, %v", empty, err)
+	}
+}
+
+func TestCompileRejectsInvalidFilters(t *testing.T) {
+	cases := []string{
+		`not json`,
+		`{"match":"xor"}`,
+		`{"conditions":[{"field":"password","operator":"eq","value":"x"}]}`,
+		`{"conditions":[{"field":"amount","operator":"contains","value":"1"}]}`,
+		`{"conditions":[{"field":"amount","operator":"gt","value":"abc"}]}`,
+		`{"conditions":[{"field":"created_at","operator":"on","value":"2026-01-01T10:00:00Z"}]}`,
+		`{"conditions":[{"field":"status","operator":"in","value":[]}]}`,
+		`{"groups":[{"groups":[{"groups":[{"conditions":[{"field":"status","operator":"empty"}]}]}]}]}`,
+	}
+	for _, raw := range cases {
+		_, err := Parse(raw, testSchema)
+		requireInvalidFilter(t, err)
+	}
+
+	var many []Condition
+	for i := 0; i <= MaxConditions; i++ {
+		many = append(many, Condition{Field: "status", Operator: "not_empty"})
+	}
+	err := Validate(&Filter{Conditions: many}, testSchema)
+	requireInvalidFilter(t, err)
+	if !strings.Contains(err.Error(), "conditions") {
+		t.Fatalf("expected condition limit error, got %v", err)
+	}
+}
@@ -34,3 +34,35 @@ func FormatWithSymbol(amountMinor int64, currency string, symbols map[string]str
 	}
 	return symbol + MinorToString(amountMinor)
 }
+
+// Allocate splits amountMinor across weights proportionally. The rounding
+// remainder goes to the last positive weight (or the last slot when all
+// weights are zero), so the parts always sum to amountMinor.
+func Allocate(amountMinor int64, weights []int64) []int64 {
+	parts := make([]int64, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	var totalWeight int64
+	last := len(weights) - 1
+	for i, w := range weights {
+		if w > 0 {
+			totalWeight += w
+			last = i
+		}
+	}
+
+	var allocated int64
+	if totalWeight > 0 {
+		for i, w := range weights {
+			if w <= 0 || i == last {
+				continue
+			}
+			parts[i] = amountMinor * w / totalWeight
+			allocated += parts[i]
+		}
+	}
+	parts[last] = amountMinor - allocated
+	return parts
+}
@@ -0,0 +1,289 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode"
+
+	"auralogic/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+var operationIDCleaner = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Param 查询参数描述，Type 为 JSON Schema 基础类型（string/integer/number/boolean），默认 string
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// Route 路由的补充描述；未登记的路由仍会出现在文档中，只是没有请求/响应结构
+type Route struct {
+	Summary   string
+	Query     []Param
+	Request   interface{} // JSON 请求体，传入零值即可（如 CreateOrderRequest{}）
+	Multipart bool        // 请求体为 multipart/form-data（文件上传）
+	Response  interface{} // 统一响应中 data 字段的结构
+	Paginated bool        // data 为 {items, pagination}，Response 为单个条目的结构；自动附加 page/limit
+	Public    bool        // 受保护前缀下无需登录的接口
+}
+
+// Registry 路由描述登记表，按处理函数登记，同一处理函数挂在多条路由上时共用描述
+type Registry struct {
+	routes map[string]Route
+}
+
+func NewRegistry() *Registry {
+	return &Registry{routes: map[string]Route{}}
+}
+
+// Describe 登记处理函数的描述，handler 可以是方法表达式（(*admin.OrderHandler).GetOrder）或方法值
+func (r *Registry) Describe(handler interface{}, route Route) {
+	r.routes[HandlerName(handler)] = route
+}
+
+func (r *Registry) lookup(handlerName string) (Route, bool) {
+	if r == nil {
+		return Route{}, false
+	}
+	route, ok := r.routes[strings.TrimSuffix(handlerName, "-fm")]
+	return route, ok
+}
+
+// HandlerName 处理函数的完整名称，与 gin.RouteInfo.Handler 一致（去掉方法值的 -fm 后缀）
+func HandlerName(handler interface{}) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(handler).Pointer())
+	if fn == nil {
+		return ""
+	}
+	return strings.TrimSuffix(fn.Name(), "-fm")
+}
+
+// Options 文档元信息；PathPrefixes 限定收录的路径，ProtectedPrefixes 下的接口默认需要登录
+type Options struct {
+	Title             string
+	Description       string
+	Version           string
+	PathPrefixes      []string
+	ProtectedPrefixes []string
+}
+
+// Build 根据已注册的 gin 路由生成 OpenAPI 文档：每条路由都会收录路径参数、鉴权要求和统一响应，
+// 在 registry 中登记过的路由额外带上摘要、查询参数和请求/响应结构
+func Build(routes []gin.RouteInfo, registry *Registry, opts Options) *Document {
+	schemas := newSchemaRegistry()
+	envelope := schemas.schemaOf(response.Response{})
+	pagination := schemas.schemaOf(response.Pagination{})
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       opts.Title,
+			Description: opts.Description,
+			Version:     opts.Version,
+		},
+		Paths: map[string]PathItem{},
+		Components: Components{
+			Schemas: schemas.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "Token returned by the login endpoints; browser sessions may use the session cookie plus X-CSRF-Token instead"},
+				"apiKey":     {Type: "apiKey", In: "header", Name: "X-API-Key"},
+				"apiSecret":  {Type: "apiKey", In: "header", Name: "X-API-Secret"},
+			},
+		},
+	}
+
+	tagSet := map[string]bool{}
+	for _, info := range routes {
+		if !hasAnyPrefix(info.Path, opts.PathPrefixes) || !documentedMethod(info.Method) {
+			continue
+		}
+		route, _ := registry.lookup(info.Handler)
+		summary := route.Summary
+		if summary == "" {
+			summary = handlerSummary(info.Handler)
+		}
+		openAPIPath, pathParams := convertPath(info.Path)
+		tag := routeTag(info.Path)
+		tagSet[tag] = true
+
+		op := &Operation{
+			Tags:        []string{tag},
+			Summary:     summary,
+			OperationID: operationID(info.Method, info.Path),
+			Parameters:  pathParams,
+			Responses: map[string]Response{
+				"default": jsonResponse("Error", envelope),
+			},
+		}
+		if route.Paginated {
+			op.Parameters = append(op.Parameters,
+				Parameter{Name: "page", In: "query", Schema: &Schema{Type: "integer", Format: "int32"}},
+				Parameter{Name: "limit", In: "query", Schema: &Schema{Type: "integer", Format: "int32"}},
+			)
+		}
+		for _, param := range route.Query {
+			paramType := param.Type
+			if paramType == "" {
+				paramType = "string"
+			}
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:        param.Name,
+				In:          "query",
+				Required:    param.Required,
+				Description: param.Description,
+				Schema:      &Schema{Type: paramType},
+			})
+		}
+		if route.Multipart {
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+				"multipart/form-data": {Schema: &Schema{Type: "object", Properties: map[string]*Schema{
+					"file": {Type: "string", Format: "binary"},
+				}}},
+			}}
+		} else if body := schemas.schemaOf(route.Request); body != nil {
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+				"application/json": {Schema: body},
+			}}
+		}
+
+		data := schemas.schemaOf(route.Response)
+		if route.Paginated {
+			items := data
+			if items == nil {
+				items = &Schema{}
+			}
+			data = &Schema{Type: "object", Properties: map[string]*Schema{
+				"items":      {Type: "array", Items: items},
+				"pagination": pagination,
+			}}
+		}
+		if data != nil {
+			op.Responses["200"] = jsonResponse("Success", &Schema{AllOf: []*Schema{
+				envelope,
+				{Type: "object", Properties: map[string]*Schema{"data": data}},
+			}})
+		} else {
+			op.Responses["200"] = jsonResponse("Success", envelope)
+		}
+
+		if hasAnyPrefix(info.Path, opts.ProtectedPrefixes) && !route.Public {
+			op.Security = []map[string][]string{
+				{"bearerAuth": {}},
+				{"apiKey": {}, "apiSecret": {}},
+			}
+		}
+
+		item := doc.Paths[openAPIPath]
+		if item == nil {
+			item = PathItem{}
+			doc.Paths[openAPIPath] = item
+		}
+		item[strings.ToLower(info.Method)] = op
+	}
+
+	tags := make([]string, 0, len(tagSet))
+	for tag := range tagSet {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	return doc
+}
+
+func jsonResponse(description string, schema *Schema) Response {
+	return Response{Description: description, Content: map[string]MediaType{
+		"application/json": {Schema: schema},
+	}}
+}
+
+func hasAnyPrefix(path string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// documentedMethod router.Any 会注册 CONNECT/TRACE，OpenAPI 不描述这两种方法
+func documentedMethod(method string) bool {
+	return method != http.MethodConnect && method != http.MethodTrace
+}
+
+// convertPath 把 gin 路径参数（:id、*filepath）转换为 OpenAPI 写法并生成参数列表
+func convertPath(path string) (string, []Parameter) {
+	segments := strings.Split(path, "/")
+	var params []Parameter
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// routeTag 按模块分组：/api/admin/orders/... → admin/orders，/api/config/... → config
+func routeTag(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 0 && segments[0] == "api" {
+		segments = segments[1:]
+	}
+	if len(segments) == 0 || segments[0] == "" {
+		return "default"
+	}
+	if (segments[0] == "admin" || segments[0] == "user") && len(segments) > 1 &&
+		segments[1] != "" && segments[1][0] != ':' && segments[1][0] != '*' {
+		return segments[0] + "/" + segments[1]
+	}
+	return segments[0]
+}
+
+func operationID(method, path string) string {
+	return strings.ToLower(method) + strings.TrimRight(operationIDCleaner.ReplaceAllString(strings.TrimPrefix(path, "/api"), "_"), "_")
+}
+
+// handlerSummary 未登记摘要时由方法名生成，如 (*OrderHandler).ExportOrdersCSV → Export orders CSV；匿名函数不生成
+func handlerSummary(handlerName string) string {
+	handlerName = strings.TrimSuffix(handlerName, "-fm")
+	idx := strings.LastIndex(handlerName, ").")
+	if idx < 0 {
+		return ""
+	}
+	runes := []rune(handlerName[idx+2:])
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		prevLower := !unicode.IsUpper(runes[i-1])
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+	for i, word := range words {
+		if i > 0 && strings.ToUpper(word) != word {
+			words[i] = strings.ToLower(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,137 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testNode struct {
+	ID       uint        `json:"id"`
+	Children []*testNode `json:"children"`
+}
+
+type testCreateRequest struct {
+	Name     string     `json:"name" binding:"required"`
+	Mode     string     `json:"mode" binding:"omitempty,oneof=fast slow"`
+	Note     *string    `json:"note"`
+	Secret   string     `json:"-"`
+	Deadline *time.Time `json:"deadline"`
+	Tree     testNode   `json:"tree"`
+}
+
+type testHandler struct{}
+
+func (h *testHandler) CreateWidget(c *gin.Context)    {}
+func (h *testHandler) ListWidgets(c *gin.Context)     {}
+func (h *testHandler) GetSEOSettings(c *gin.Context)  {}
+func (h *testHandler) HandleWebhook(c *gin.Context)   {}
+func (h *testHandler) GetPublicWidget(c *gin.Context) {}
+
+func buildTestDocument(t *testing.T) *Document {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	h := &testHandler{}
+	r := gin.New()
+	r.POST("/api/admin/widgets", h.CreateWidget)
+	r.GET("/api/admin/widgets", h.ListWidgets)
+	r.GET("/api/admin/widgets/:id/seo", h.GetSEOSettings)
+	r.GET("/api/user/widgets/:id", h.GetPublicWidget)
+	r.Any("/api/hooks/:name/*path", h.HandleWebhook)
+	r.GET("/health", func(c *gin.Context) {})
+
+	reg := NewRegistry()
+	reg.Describe((*testHandler).CreateWidget, Route{Request: testCreateRequest{}, Response: gin.H{"id": uint(0), "node": testNode{}}})
+	reg.Describe(h.ListWidgets, Route{Query: []Param{{Name: "status"}}, Response: testNode{}, Paginated: true})
+	reg.Describe((*testHandler).GetPublicWidget, Route{Public: true})
+
+	return Build(r.Routes(), reg, Options{
+		Title:             "Test",
+		Version:           "1.0",
+		PathPrefixes:      []string{"/api"},
+		ProtectedPrefixes: []string{"/api/admin", "/api/user"},
+	})
+}
+
+func TestBuildCoversRoutesWithParamsAndSecurity(t *testing.T) {
+	doc := buildTestDocument(t)
+
+	if _, ok := doc.Paths["/health"]; ok {
+		t.Fatalf("paths outside PathPrefixes must be skipped")
+	}
+	seo := doc.Paths["/api/admin/widgets/{id}/seo"]["get"]
+	if seo == nil || seo.Summary != "Get SEO settings" || len(seo.Parameters) != 1 || seo.Parameters[0].In != "path" {
+		t.Fatalf("unexpected undescribed route: %+v", seo)
+	}
+	if len(seo.Security) == 0 || seo.Tags[0] != "admin/widgets" {
+		t.Fatalf("admin routes must require auth and be tagged by module: %+v", seo)
+	}
+	if public := doc.Paths["/api/user/widgets/{id}"]["get"]; public == nil || len(public.Security) != 0 {
+		t.Fatalf("public routes must not declare security: %+v", public)
+	}
+
+	hooks := doc.Paths["/api/hooks/{name}/{path}"]
+	if hooks == nil || hooks["post"] == nil || hooks["connect"] != nil || hooks["trace"] != nil {
+		t.Fatalf("Any() routes must skip CONNECT/TRACE: %+v", hooks)
+	}
+	if len(hooks["post"].Security) != 0 {
+		t.Fatalf("routes outside protected prefixes must be public")
+	}
+
+	seen := map[string]bool{}
+	for _, item := range doc.Paths {
+		for _, op := range item {
+			if seen[op.OperationID] {
+				t.Fatalf("duplicate operationId %s", op.OperationID)
+			}
+			seen[op.OperationID] = true
+		}
+	}
+}
+
+func TestBuildGeneratesRequestAndResponseSchemas(t *testing.T) {
+	doc := buildTestDocument(t)
+
+	create := doc.Paths["/api/admin/widgets"]["post"]
+	if create.RequestBody == nil || create.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/openapi.testCreateRequest" {
+		t.Fatalf("expected request body ref, got %+v", create.RequestBody)
+	}
+	req := doc.Components.Schemas["openapi.testCreateRequest"]
+	if req == nil || len(req.Required) != 1 || req.Required[0] != "name" {
+		t.Fatalf("binding:required must map to required: %+v", req)
+	}
+	if _, ok := req.Properties["Secret"]; ok {
+		t.Fatalf(`json:"-" fields must be skipped`)
+	}
+	if mode := req.Properties["mode"]; len(mode.Enum) != 2 {
+		t.Fatalf("oneof must map to enum: %+v", mode)
+	}
+	if note, deadline := req.Properties["note"], req.Properties["deadline"]; !note.Nullable || deadline.Format != "date-time" || !deadline.Nullable {
+		t.Fatalf("pointer fields must be nullable: note=%+v deadline=%+v", note, deadline)
+	}
+	node := doc.Components.Schemas["openapi.testNode"]
+	if node == nil || node.Properties["children"].Items.AllOf[0].Ref != "#/components/schemas/openapi.testNode" {
+		t.Fatalf("self-referencing types must use $ref: %+v", node)
+	}
+
+	data := create.Responses["200"].Content["application/json"].Schema.AllOf[1].Properties["data"]
+	if data.Properties["id"].Type != "integer" || data.Properties["node"].Ref == "" {
+		t.Fatalf("gin.H responses must expand to object properties: %+v", data)
+	}
+
+	list := doc.Paths["/api/admin/widgets"]["get"]
+	if len(list.Parameters) != 3 {
+		t.Fatalf("paginated routes must add page/limit next to declared query params: %+v", list.Parameters)
+	}
+	listData := list.Responses["200"].Content["application/json"].Schema.AllOf[1].Properties["data"]
+	if listData.Properties["items"].Items.Ref != "#/components/schemas/openapi.testNode" ||
+		listData.Properties["pagination"].Ref != "#/components/schemas/response.Pagination" {
+		t.Fatalf("unexpected paginated data schema: %+v", listData)
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("document must be serializable: %v", err)
+	}
+}
@@ -0,0 +1,235 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	schemaNameCleaner = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// schemaRegistry 把 Go 类型转换为 JSON Schema，具名结构体放入 components 并以 $ref 引用
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// schemaOf 返回值 v 对应的 Schema；v 为 nil 时返回 nil。
+// gin.H 等 map[string]interface{} 按键展开为对象，值用示例零值描述字段类型
+func (r *schemaRegistry) schemaOf(v interface{}) *Schema {
+	if v == nil {
+		return nil
+	}
+	if schema, ok := v.(*Schema); ok {
+		return schema
+	}
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String &&
+		value.Type().Elem().Kind() == reflect.Interface && value.Len() > 0 {
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		iter := value.MapRange()
+		for iter.Next() {
+			field := r.schemaOf(iter.Value().Interface())
+			if field == nil {
+				field = &Schema{}
+			}
+			schema.Properties[iter.Key().String()] = field
+		}
+		return schema
+	}
+	return r.schemaForType(value.Type())
+}
+
+func (r *schemaRegistry) schemaForType(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		schema := r.schemaForType(t.Elem())
+		if schema.Ref != "" {
+			return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+		}
+		copied := *schema
+		copied.Nullable = true
+		return &copied
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			if t.Implements(jsonMarshalerType) {
+				return &Schema{}
+			}
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaForType(t.Elem())}
+	case reflect.Map:
+		if t.Implements(jsonMarshalerType) {
+			return &Schema{Type: "object"}
+		}
+		return &Schema{Type: "object", AdditionalProperties: r.schemaForType(t.Elem())}
+	case reflect.Struct:
+		return r.structSchema(t)
+	}
+	// interface{} 等无法静态确定的类型
+	return &Schema{}
+}
+
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	// 第三方类型自定义了序列化（如 gorm.DeletedAt），字段结构不代表实际输出
+	if t.Implements(jsonMarshalerType) && !strings.HasPrefix(t.PkgPath(), "auralogic/") {
+		return &Schema{}
+	}
+	if t.Name() == "" {
+		return r.buildStructSchema(t)
+	}
+	if name, ok := r.names[t]; ok {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	name := r.uniqueName(t)
+	r.names[t] = name
+	// 先占位，避免自引用类型无限递归
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.buildStructSchema(t)
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// uniqueName 组件名使用 包名.类型名，不同包的同名类型（如 admin.LoginRequest / user.LoginRequest）互不覆盖
+func (r *schemaRegistry) uniqueName(t reflect.Type) string {
+	base := schemaNameCleaner.ReplaceAllString(path.Base(t.PkgPath())+"."+t.Name(), "_")
+	name := base
+	for i := 2; ; i++ {
+		if _, exists := r.schemas[name]; !exists {
+			return name
+		}
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+}
+
+func (r *schemaRegistry) buildStructSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	r.collectFields(t, schema)
+	return schema
+}
+
+// collectFields 按 encoding/json 规则收集字段：匿名结构体字段展开，json:"-" 跳过
+func (r *schemaRegistry) collectFields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			embedded := fieldType
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && !embedded.Implements(jsonMarshalerType) {
+				r.collectFields(embedded, schema)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := r.schemaForType(fieldType)
+		if strings.Contains(opts, "string") && fieldSchema.Type != "" && fieldSchema.Type != "object" && fieldSchema.Type != "array" {
+			fieldSchema = &Schema{Type: "string", Nullable: fieldSchema.Nullable}
+		}
+		if values := oneOfValues(field.Tag.Get("binding")); len(values) > 0 && fieldSchema.Type == "string" {
+			fieldSchema.Enum = values
+		}
+		schema.Properties[name] = fieldSchema
+		if bindingRequired(field.Tag.Get("binding")) {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func bindingRequired(binding string) bool {
+	for _, rule := range strings.Split(binding, ",") {
+		if strings.TrimSpace(rule) == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+func oneOfValues(binding string) []string {
+	for _, rule := range strings.Split(binding, ",") {
+		if values, ok := strings.CutPrefix(strings.TrimSpace(rule), "oneof="); ok {
+			return strings.Fields(values)
+		}
+	}
+	return nil
+}
+
+// queryParameters 从带 form 标签的结构体生成查询参数
+func (r *schemaRegistry) queryParameters(v interface{}) []Parameter {
+	if v == nil {
+		return nil
+	}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fieldSchema := r.schemaForType(field.Type)
+		fieldSchema.Nullable = false
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "query",
+			Required: bindingRequired(field.Tag.Get("binding")),
+			Schema:   fieldSchema,
+		})
+	}
+	return params
+}
@@ -3,6 +3,7 @@ package orderbiz
 import (
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/validator"
 )
 
 func InvalidOrderID() *bizerr.Error {
@@ -107,6 +108,41 @@ func PostcodeInvalid() *bizerr.Error {
 	return bizerr.New("order.postcodeInvalid", "Invalid postal code format or length")
 }
 
+// PhoneInvalidForCountry 号码不符合所选国家/地区格式，附带示例号码作为提示
+func PhoneInvalidForCountry(country, example string) *bizerr.Error {
+	return bizerr.Newf("order.receiverPhoneInvalidForCountry", "Invalid phone number for %s, e.g. %s", country, example).
+		WithParams(map[string]interface{}{"country": country, "example": example})
+}
+
+// PostcodeInvalidForCountry 邮编不符合所选国家/地区格式，附带示例邮编作为提示
+func PostcodeInvalidForCountry(country, example string) *bizerr.Error {
+	return bizerr.Newf("order.postcodeInvalidForCountry", "Invalid postal code for %s, e.g. %s", country, example).
+		WithParams(map[string]interface{}{"country": country, "example": example})
+}
+
+// ValidateReceiverRegion 按收件国家（号码优先按区号）校验收件电话与邮编
+func ValidateReceiverRegion(country, phoneCode, phone, postcode string) *bizerr.Error {
+	label := country
+	if label == "" {
+		label = phoneCode
+	}
+	if phone != "" {
+		if ok, example := validator.ValidatePhoneForCountry(country, phoneCode, phone); !ok {
+			if example == "" {
+				return ReceiverPhoneInvalid()
+			}
+			return PhoneInvalidForCountry(label, example)
+		}
+	}
+	if ok, example := validator.ValidatePostcodeForCountry(country, postcode); !ok {
+		if example == "" {
+			return PostcodeInvalid()
+		}
+		return PostcodeInvalidForCountry(country, example)
+	}
+	return nil
+}
+
 func ResubmitReasonLengthInvalid(min, max int) *bizerr.Error {
 	return bizerr.Newf("order.resubmitReasonLengthInvalid", "Resubmit reason length must be between %d-%d characters", min, max).
 		WithParams(map[string]interface{}{"min": min, "max": max})
@@ -0,0 +1,229 @@
+// Package piicrypt 敏感个人信息的应用层加密（AES-256-GCM）和可检索的盲索引（HMAC-SHA256）。
+// 密文格式为 enc:v1:<key id>:<base64(nonce|ciphertext)>，不带前缀的值视为旧的明文数据。
+package piicrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"auralogic/internal/config"
+)
+
+const prefix = "enc:v1:"
+
+var ErrKeyUnavailable = errors.New("pii encryption key is not configured")
+
+// Cipher 一组加密密钥和盲索引密钥
+type Cipher struct {
+	keys     map[string]cipher.AEAD
+	activeID string
+	indexKey []byte
+}
+
+var current atomic.Pointer[Cipher]
+
+// Init 按配置初始化全局加密器；未启用时新数据以明文写入，已有密文无法读取
+func Init(cfg *config.PIIEncryptionConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		current.Store(nil)
+		return nil
+	}
+	c, err := New(cfg.ActiveKeyID, cfg.Keys, cfg.BlindIndexKey)
+	if err != nil {
+		return err
+	}
+	current.Store(c)
+	return nil
+}
+
+// Current 当前全局加密器，未启用时为 nil
+func Current() *Cipher {
+	return current.Load()
+}
+
+// SetCurrent 替换全局加密器（测试和密钥轮换使用）
+func SetCurrent(c *Cipher) {
+	current.Store(c)
+}
+
+// New 解析 base64 密钥并创建加密器
+func New(activeID string, keys map[string]string, blindIndexKey string) (*Cipher, error) {
+	c := &Cipher{keys: make(map[string]cipher.AEAD, len(keys)), activeID: activeID}
+	for id, encoded := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid pii key id %q", id)
+		}
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("pii key %q must be 32 bytes encoded as base64", id)
+		}
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		c.keys[id] = aead
+	}
+	if _, ok := c.keys[activeID]; !ok {
+		return nil, fmt.Errorf("active pii key %q is not configured", activeID)
+	}
+	indexKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(blindIndexKey))
+	if err != nil || len(indexKey) < 32 {
+		return nil, errors.New("pii blind index key must be at least 32 bytes encoded as base64")
+	}
+	c.indexKey = indexKey
+	return c, nil
+}
+
+// ActiveKeyID 新数据使用的密钥 ID
+func (c *Cipher) ActiveKeyID() string {
+	return c.activeID
+}
+
+// Encrypt 使用当前密钥加密，空字符串保持为空
+func (c *Cipher) Encrypt(plain string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+	aead := c.keys[c.activeID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plain), []byte(c.activeID))
+	return prefix + c.activeID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密密文；明文（旧数据）原样返回
+func (c *Cipher) Decrypt(value string) (string, error) {
+	keyID, payload, ok := splitCiphertext(value)
+	if !ok {
+		return value, nil
+	}
+	aead, exists := c.keys[keyID]
+	if !exists {
+		return "", fmt.Errorf("pii key %q is not configured", keyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed pii ciphertext")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("decrypt pii value with key %q: %w", keyID, err)
+	}
+	return string(plain), nil
+}
+
+// BlindIndex 计算规范化后的盲索引；kind 用于区分不同字段，避免跨字段碰撞
+func (c *Cipher) BlindIndex(kind, normalized string) string {
+	if normalized == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(kind + ":" + normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted 值是否为密文
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// KeyIDOf 密文使用的密钥 ID，明文返回空
+func KeyIDOf(value string) string {
+	keyID, _, ok := splitCiphertext(value)
+	if !ok {
+		return ""
+	}
+	return keyID
+}
+
+func splitCiphertext(value string) (string, string, bool) {
+	if !strings.HasPrefix(value, prefix) {
+		return "", "", false
+	}
+	keyID, payload, found := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !found {
+		return "", "", false
+	}
+	return keyID, payload, true
+}
+
+// Encrypt 使用全局加密器加密，未启用时返回明文
+func Encrypt(plain string) (string, error) {
+	c := Current()
+	if c == nil {
+		return plain, nil
+	}
+	return c.Encrypt(plain)
+}
+
+// Decrypt 使用全局加密器解密；遇到密文但未配置密钥时报错
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	c := Current()
+	if c == nil {
+		return "", ErrKeyUnavailable
+	}
+	return c.Decrypt(value)
+}
+
+// NormalizeEmail 邮箱盲索引的规范化：去空白并小写
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePhone 电话盲索引的规范化：仅保留数字
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// EmailIndex 邮箱盲索引
+func (c *Cipher) EmailIndex(email string) string {
+	return c.BlindIndex("email", NormalizeEmail(email))
+}
+
+// PhoneIndex 电话盲索引
+func (c *Cipher) PhoneIndex(phone string) string {
+	return c.BlindIndex("phone", NormalizePhone(phone))
+}
+
+// EmailIndex 使用全局加密器计算邮箱盲索引，未启用加密时为空
+func EmailIndex(email string) string {
+	c := Current()
+	if c == nil {
+		return ""
+	}
+	return c.EmailIndex(email)
+}
+
+// PhoneIndex 使用全局加密器计算电话盲索引，未启用加密时为空
+func PhoneIndex(phone string) string {
+	c := Current()
+	if c == nil {
+		return ""
+	}
+	return c.PhoneIndex(phone)
+}
@@ -0,0 +1,81 @@
+package piicrypt
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(seed byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune('a'+seed)), 32)))
+}
+
+func TestCipherRoundTripAndKeyRotation(t *testing.T) {
+	oldCipher, err := New("k1", map[string]string{"k1": testKey(1)}, testKey(9))
+	if err != nil {
+		t.Fatalf("new cipher failed: %v", err)
+	}
+	sealed, err := oldCipher.Encrypt("张三 138-0000-1111")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if !IsEncrypted(sealed) || KeyIDOf(sealed) != "k1" || strings.Contains(sealed, "138") {
+		t.Fatalf("unexpected ciphertext %q", sealed)
+	}
+	again, _ := oldCipher.Encrypt("张三 138-0000-1111")
+	if again == sealed {
+		t.Fatal("expected random nonce to produce different ciphertexts")
+	}
+
+	rotated, err := New("k2", map[string]string{"k1": testKey(1), "k2": testKey(2)}, testKey(9))
+	if err != nil {
+		t.Fatalf("new cipher failed: %v", err)
+	}
+	plain, err := rotated.Decrypt(sealed)
+	if err != nil || plain != "张三 138-0000-1111" {
+		t.Fatalf("expected old ciphertext to decrypt after rotation, got %q, %v", plain, err)
+	}
+	if plain, err := rotated.Decrypt("legacy plaintext"); err != nil || plain != "legacy plaintext" {
+		t.Fatalf("expected plaintext passthrough, got %q, %v", plain, err)
+	}
+
+	withoutOld, _ := New("k2", map[string]string{"k2": testKey(2)}, testKey(9))
+	if _, err := withoutOld.Decrypt(sealed); err == nil {
+		t.Fatal("expected decrypt to fail once the old key is removed")
+	}
+	tampered := sealed[:len(sealed)-4] + "AAAA"
+	if _, err := rotated.Decrypt(tampered); err == nil {
+		t.Fatal("expected tampered ciphertext to fail authentication")
+	}
+
+	if _, err := New("k3", map[string]string{"k1": testKey(1)}, testKey(9)); err == nil {
+		t.Fatal("expected missing active key to be rejected")
+	}
+	if _, err := New("k1", map[string]string{"k1": "short"}, testKey(9)); err == nil {
+		t.Fatal("expected invalid key length to be rejected")
+	}
+}
+
+func TestBlindIndexNormalization(t *testing.T) {
+	c, err := New("k1", map[string]string{"k1": testKey(1)}, testKey(9))
+	if err != nil {
+		t.Fatalf("new cipher failed: %v", err)
+	}
+	if c.EmailIndex(" Alice@Example.com ") != c.EmailIndex("alice@example.com") {
+		t.Fatal("expected email index to ignore case and whitespace")
+	}
+	if c.PhoneIndex("+86 138-0000-1111") != c.PhoneIndex("8613800001111") {
+		t.Fatal("expected phone index to ignore formatting")
+	}
+	if c.EmailIndex("13800001111") == c.PhoneIndex("13800001111") {
+		t.Fatal("expected email and phone indexes to be domain separated")
+	}
+	if c.PhoneIndex("n/a") != "" {
+		t.Fatal("expected empty index for values without digits")
+	}
+
+	other, _ := New("k1", map[string]string{"k1": testKey(1)}, testKey(8))
+	if other.EmailIndex("alice@example.com") == c.EmailIndex("alice@example.com") {
+		t.Fatal("expected index to depend on the blind index key")
+	}
+}
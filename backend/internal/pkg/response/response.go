@@ -47,6 +47,7 @@ const (
 	CodeForbidden          = 30001
 	CodePasswordDisabled   = 30002
 	CodeEmailNotVerified   = 30003
+	CodeApprovalRequired   = 30004 // 危险操作需另一位管理员审批
 	CodeNotFound           = 40001
 	CodeOrderNotFound      = 40002
 	CodeUserNotFound       = 40003
@@ -0,0 +1,157 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// phoneRule 国家/地区号码规则，取自 libphonenumber 元数据的 general desc（仅保留常用国家，按国内号码匹配）
+type phoneRule struct {
+	CallingCode string         // 国际区号，如 +86
+	TrunkPrefix string         // 国内长途前缀，匹配前去除
+	Pattern     *regexp.Regexp // 国内号码（不含区号与长途前缀）
+	Example     string
+}
+
+// postcodeRule 国家/地区邮编规则
+type postcodeRule struct {
+	Pattern *regexp.Regexp
+	Example string
+}
+
+func anchored(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`^(?:` + pattern + `)$`)
+}
+
+var phoneRules = map[string]phoneRule{
+	"CN": {"+86", "0", anchored(`1[3-9]\d{9}|[1-9]\d{8,10}`), "13812345678"},
+	"HK": {"+852", "", anchored(`[2-9]\d{7}`), "51234567"},
+	"MO": {"+853", "", anchored(`[268]\d{7}`), "66123456"},
+	"TW": {"+886", "0", anchored(`[2-9]\d{7,8}`), "912345678"},
+	"JP": {"+81", "0", anchored(`[1-9]\d{8,9}`), "9012345678"},
+	"KR": {"+82", "0", anchored(`[1-9]\d{7,9}`), "1020000000"},
+	"SG": {"+65", "", anchored(`[3689]\d{7}`), "81234567"},
+	"MY": {"+60", "0", anchored(`1\d{8,9}|[3-9]\d{7,8}`), "123456789"},
+	"TH": {"+66", "0", anchored(`[2-9]\d{7,8}`), "812345678"},
+	"VN": {"+84", "0", anchored(`[1-9]\d{8,9}`), "912345678"},
+	"PH": {"+63", "0", anchored(`[2-9]\d{7,9}`), "9051234567"},
+	"ID": {"+62", "0", anchored(`[1-9]\d{6,11}`), "812345678"},
+	"IN": {"+91", "0", anchored(`[1-9]\d{9}`), "8123456789"},
+	"AU": {"+61", "0", anchored(`[2-478]\d{8}`), "412345678"},
+	"NZ": {"+64", "0", anchored(`[2-9]\d{7,9}`), "211234567"},
+	"US": {"+1", "1", anchored(`[2-9]\d{2}[2-9]\d{6}`), "2015550123"},
+	"CA": {"+1", "1", anchored(`[2-9]\d{2}[2-9]\d{6}`), "5062345678"},
+	"MX": {"+52", "", anchored(`[1-9]\d{9}`), "2221234567"},
+	"BR": {"+55", "0", anchored(`[1-9]{2}\d{8,9}`), "11961234567"},
+	"GB": {"+44", "0", anchored(`[1-9]\d{8,9}`), "7400123456"},
+	"DE": {"+49", "0", anchored(`[1-9]\d{5,13}`), "15123456789"},
+	"FR": {"+33", "0", anchored(`[1-9]\d{8}`), "612345678"},
+	"IT": {"+39", "", anchored(`0\d{5,10}|3\d{8,9}`), "3123456789"},
+	"ES": {"+34", "", anchored(`[5-9]\d{8}`), "612345678"},
+	"NL": {"+31", "0", anchored(`[1-9]\d{8}`), "612345678"},
+	"RU": {"+7", "8", anchored(`[3489]\d{9}`), "9123456789"},
+	"AE": {"+971", "0", anchored(`[2-79]\d{7,8}`), "501234567"},
+	"SA": {"+966", "0", anchored(`[1-9]\d{7,8}`), "512345678"},
+}
+
+var postcodeRules = map[string]postcodeRule{
+	"CN": {anchored(`\d{6}`), "100000"},
+	"TW": {anchored(`\d{3}(?:\d{2,3})?`), "100"},
+	"JP": {anchored(`\d{3}-?\d{4}`), "100-0001"},
+	"KR": {anchored(`\d{5}`), "03051"},
+	"SG": {anchored(`\d{6}`), "238823"},
+	"MY": {anchored(`\d{5}`), "50050"},
+	"TH": {anchored(`\d{5}`), "10100"},
+	"VN": {anchored(`\d{6}`), "100000"},
+	"PH": {anchored(`\d{4}`), "1000"},
+	"ID": {anchored(`\d{5}`), "10110"},
+	"IN": {anchored(`\d{3} ?\d{3}`), "110001"},
+	"AU": {anchored(`\d{4}`), "2000"},
+	"NZ": {anchored(`\d{4}`), "6011"},
+	"US": {anchored(`\d{5}(?:-\d{4})?`), "95014"},
+	"CA": {anchored(`[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] ?\d[ABCEGHJ-NPRSTV-Z]\d`), "K1A 0B1"},
+	"MX": {anchored(`\d{5}`), "01000"},
+	"BR": {anchored(`\d{5}-?\d{3}`), "01310-100"},
+	"GB": {anchored(`GIR ?0AA|[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}`), "SW1A 1AA"},
+	"DE": {anchored(`\d{5}`), "10115"},
+	"FR": {anchored(`\d{5}`), "75001"},
+	"IT": {anchored(`\d{5}`), "00144"},
+	"ES": {anchored(`\d{5}`), "28001"},
+	"NL": {anchored(`\d{4} ?[A-Z]{2}`), "1012 AB"},
+	"RU": {anchored(`\d{6}`), "101000"},
+	"SA": {anchored(`\d{5}(?:-\d{4})?`), "11564"},
+}
+
+var phoneSeparatorRe = regexp.MustCompile(`[\s\-\(\)\.]`)
+
+// phoneRuleFor 优先使用收件国家的规则；区号与国家不一致时按区号查找（如海外收件人留国内号码）
+func phoneRuleFor(country, phoneCode string) (phoneRule, bool) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	phoneCode = strings.TrimSpace(phoneCode)
+	if rule, ok := phoneRules[country]; ok && (phoneCode == "" || phoneCode == rule.CallingCode) {
+		return rule, true
+	}
+	if phoneCode == "" {
+		return phoneRule{}, false
+	}
+	// +1/+7 为多国共用区号，默认取主要国家
+	for _, code := range []string{"US", "RU"} {
+		if rule := phoneRules[code]; rule.CallingCode == phoneCode {
+			return rule, true
+		}
+	}
+	for _, rule := range phoneRules {
+		if rule.CallingCode == phoneCode {
+			return rule, true
+		}
+	}
+	return phoneRule{}, false
+}
+
+// ValidatePhoneForCountry 按国家/区号校验号码；号码可带区号或长途前缀。
+// 返回 ok 与带区号的示例号码，未收录的国家只做通用格式校验
+func ValidatePhoneForCountry(country, phoneCode, phone string) (bool, string) {
+	if !ValidatePhone(phone) {
+		return false, ""
+	}
+	rule, known := phoneRuleFor(country, phoneCode)
+	if !known {
+		return true, ""
+	}
+	example := rule.CallingCode + " " + rule.Example
+
+	digits := phoneSeparatorRe.ReplaceAllString(strings.TrimSpace(phone), "")
+	switch {
+	case strings.HasPrefix(digits, rule.CallingCode):
+		digits = strings.TrimPrefix(digits, rule.CallingCode)
+	case strings.HasPrefix(digits, "00"+strings.TrimPrefix(rule.CallingCode, "+")):
+		digits = strings.TrimPrefix(digits, "00"+strings.TrimPrefix(rule.CallingCode, "+"))
+	case strings.HasPrefix(digits, "+"):
+		// 带了其他国家的区号
+		return false, example
+	}
+	if rule.Pattern.MatchString(digits) {
+		return true, example
+	}
+	if rule.TrunkPrefix != "" && strings.HasPrefix(digits, rule.TrunkPrefix) &&
+		rule.Pattern.MatchString(strings.TrimPrefix(digits, rule.TrunkPrefix)) {
+		return true, example
+	}
+	return false, example
+}
+
+// ValidatePostcodeForCountry 按国家校验邮编格式（空邮编视为合法），返回 ok 与示例邮编
+func ValidatePostcodeForCountry(country, postcode string) (bool, string) {
+	postcode = strings.TrimSpace(postcode)
+	if postcode == "" {
+		return true, ""
+	}
+	if !ValidatePostcode(postcode) {
+		return false, ""
+	}
+	rule, known := postcodeRules[strings.ToUpper(strings.TrimSpace(country))]
+	if !known {
+		return true, ""
+	}
+	return rule.Pattern.MatchString(strings.ToUpper(postcode)), rule.Example
+}
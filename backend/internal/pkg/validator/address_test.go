@@ -0,0 +1,57 @@
+package validator
+
+import "testing"
+
+func TestValidatePhoneForCountry(t *testing.T) {
+	cases := []struct {
+		country, phoneCode, phone string
+		want                      bool
+	}{
+		{"CN", "+86", "138 1234 5678", true},
+		{"CN", "+86", "+86 13812345678", true},
+		{"CN", "+86", "010-12345678", true},
+		{"CN", "+86", "12345", false},
+		{"US", "+1", "(201) 555-0123", true},
+		{"US", "+1", "1 201 555 0123", true},
+		{"US", "+1", "011 555 0123", false},
+		{"GB", "+44", "07400 123456", true},
+		{"DE", "", "0044 7400 123456", false},
+		// 海外收件人使用国内号码时按区号校验
+		{"US", "+86", "13812345678", true},
+		{"JP", "+81", "+86 13812345678", false},
+		// 未收录的国家只做通用格式校验
+		{"ZW", "+263", "771234567", true},
+		{"ZW", "+263", "abc", false},
+	}
+	for _, tc := range cases {
+		if got, _ := ValidatePhoneForCountry(tc.country, tc.phoneCode, tc.phone); got != tc.want {
+			t.Errorf("ValidatePhoneForCountry(%q, %q, %q) = %v, want %v", tc.country, tc.phoneCode, tc.phone, got, tc.want)
+		}
+	}
+	if _, example := ValidatePhoneForCountry("CN", "+86", "1"); example != "+86 13812345678" {
+		t.Errorf("expected example hint with calling code, got %q", example)
+	}
+}
+
+func TestValidatePostcodeForCountry(t *testing.T) {
+	cases := []struct {
+		country, postcode string
+		want              bool
+	}{
+		{"CN", "", true},
+		{"CN", "100000", true},
+		{"CN", "10000", false},
+		{"US", "95014-1234", true},
+		{"CA", "k1a 0b1", true},
+		{"GB", "SW1A 1AA", true},
+		{"GB", "12345", false},
+		{"NL", "1012AB", true},
+		{"ZW", "ABC-1", true},
+		{"ZW", "<b>", false},
+	}
+	for _, tc := range cases {
+		if got, _ := ValidatePostcodeForCountry(tc.country, tc.postcode); got != tc.want {
+			t.Errorf("ValidatePostcodeForCountry(%q, %q) = %v, want %v", tc.country, tc.postcode, got, tc.want)
+		}
+	}
+}
@@ -247,6 +247,46 @@ func (r *InventoryRepository) Deduct(inventoryID uint, quantity int, orderNo str
 	})
 }
 
+// Restock 退货回补库存（Deduct 的逆操作）：减少已售数量，增加总库存与可购买数
+func (r *InventoryRepository) Restock(inventoryID uint, quantity int, orderNo, operator, reason string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := dbutil.LockForUpdate(tx, &models.Inventory{}, "id = ?", inventoryID); err != nil {
+			return err
+		}
+
+		var inventory models.Inventory
+		if err := tx.First(&inventory, inventoryID).Error; err != nil {
+			return err
+		}
+
+		beforeStock := inventory.Stock
+		inventory.SoldQuantity -= quantity
+		if inventory.SoldQuantity < 0 {
+			inventory.SoldQuantity = 0
+		}
+		inventory.Stock += quantity
+		inventory.AvailableQuantity += quantity
+
+		if err := tx.Save(&inventory).Error; err != nil {
+			return err
+		}
+
+		log := &models.InventoryLog{
+			InventoryID: inventoryID,
+			ProductID:   0,
+			Type:        models.InventoryLogTypeReturn,
+			Quantity:    quantity,
+			BeforeStock: beforeStock,
+			AfterStock:  inventory.Stock,
+			OrderNo:     orderNo,
+			Operator:    operator,
+			Reason:      reason,
+		}
+
+		return tx.Create(log).Error
+	})
+}
+
 // Adjust 调整库存（入库、盘点等）- 旧方法保留用于兼容
 func (r *InventoryRepository) Adjust(inventoryID uint, newStock, newAvailable int, operator, reason string) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
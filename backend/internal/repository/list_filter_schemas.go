@@ -0,0 +1,76 @@
+package repository
+
+import "auralogic/internal/pkg/listfilter"
+
+// 管理后台列表的高级筛选字段白名单（字段名 → 列）
+var (
+	OrderListFilterSchema = listfilter.Schema{
+		"order_no":              {Column: "orders.order_no", Kind: listfilter.KindString},
+		"status":                {Column: "orders.status", Kind: listfilter.KindString},
+		"sub_status":            {Column: "(CASE WHEN orders.sub_status_for = orders.status THEN orders.sub_status ELSE '' END)", Kind: listfilter.KindString},
+		"country":               {Column: "orders.receiver_country", Kind: listfilter.KindString},
+		"province":              {Column: "orders.receiver_province", Kind: listfilter.KindString},
+		"city":                  {Column: "orders.receiver_city", Kind: listfilter.KindString},
+		"currency":              {Column: "orders.currency", Kind: listfilter.KindString},
+		"source":                {Column: "orders.source", Kind: listfilter.KindString},
+		"promo_code":            {Column: "orders.promo_code_str", Kind: listfilter.KindString},
+		"user_email":            {Column: "orders.user_email", Kind: listfilter.KindString},
+		"tracking_no":           {Column: "orders.tracking_no", Kind: listfilter.KindString},
+		"user_id":               {Column: "orders.user_id", Kind: listfilter.KindNumber},
+		"store_id":              {Column: "orders.store_id", Kind: listfilter.KindNumber},
+		"total_amount_minor":    {Column: "orders.total_amount", Kind: listfilter.KindNumber},
+		"discount_amount_minor": {Column: "orders.discount_amount", Kind: listfilter.KindNumber},
+		"privacy_protected":     {Column: "orders.privacy_protected", Kind: listfilter.KindBool},
+		"tags":                  {Column: "orders.tags", Kind: listfilter.KindTags},
+		"created_at":            {Column: "orders.created_at", Kind: listfilter.KindDate},
+		"updated_at":            {Column: "orders.updated_at", Kind: listfilter.KindDate},
+		"shipped_at":            {Column: "orders.shipped_at", Kind: listfilter.KindDate},
+		"completed_at":          {Column: "orders.completed_at", Kind: listfilter.KindDate},
+	}
+
+	UserListFilterSchema = listfilter.Schema{
+		"email":                  {Column: "users.email", Kind: listfilter.KindString},
+		"name":                   {Column: "users.name", Kind: listfilter.KindString},
+		"phone":                  {Column: "users.phone", Kind: listfilter.KindString},
+		"role":                   {Column: "users.role", Kind: listfilter.KindString},
+		"locale":                 {Column: "users.locale", Kind: listfilter.KindString},
+		"country":                {Column: "users.country", Kind: listfilter.KindString},
+		"is_active":              {Column: "users.is_active", Kind: listfilter.KindBool},
+		"email_verified":         {Column: "users.email_verified", Kind: listfilter.KindBool},
+		"email_notify_marketing": {Column: "users.email_notify_marketing", Kind: listfilter.KindBool},
+		"sms_notify_marketing":   {Column: "users.sms_notify_marketing", Kind: listfilter.KindBool},
+		"total_spent_minor":      {Column: "users.total_spent_minor", Kind: listfilter.KindNumber},
+		"total_order_count":      {Column: "users.total_order_count", Kind: listfilter.KindNumber},
+		"created_at":             {Column: "users.created_at", Kind: listfilter.KindDate},
+		"last_login_at":          {Column: "users.last_login_at", Kind: listfilter.KindDate},
+	}
+
+	TicketListFilterSchema = listfilter.Schema{
+		"ticket_no":          {Column: "tickets.ticket_no", Kind: listfilter.KindString},
+		"subject":            {Column: "tickets.subject", Kind: listfilter.KindString},
+		"category":           {Column: "tickets.category", Kind: listfilter.KindString},
+		"priority":           {Column: "tickets.priority", Kind: listfilter.KindString},
+		"status":             {Column: "tickets.status", Kind: listfilter.KindString},
+		"last_message_by":    {Column: "tickets.last_message_by", Kind: listfilter.KindString},
+		"user_id":            {Column: "tickets.user_id", Kind: listfilter.KindNumber},
+		"assigned_to":        {Column: "tickets.assigned_to", Kind: listfilter.KindNumber},
+		"unread_count_admin": {Column: "tickets.unread_count_admin", Kind: listfilter.KindNumber},
+		"tags":               {Column: "tickets.tags", Kind: listfilter.KindTags},
+		"created_at":         {Column: "tickets.created_at", Kind: listfilter.KindDate},
+		"last_message_at":    {Column: "tickets.last_message_at", Kind: listfilter.KindDate},
+		"closed_at":          {Column: "tickets.closed_at", Kind: listfilter.KindDate},
+	}
+)
+
+// ListFilterSchemaFor 按列表资源名获取筛选字段；未知资源返回 nil
+func ListFilterSchemaFor(resource string) listfilter.Schema {
+	switch resource {
+	case "orders":
+		return OrderListFilterSchema
+	case "users":
+		return UserListFilterSchema
+	case "tickets":
+		return TicketListFilterSchema
+	}
+	return nil
+}
@@ -91,7 +91,7 @@ func scanUserPurchaseQuantitiesFromOrders(db *gorm.DB, userID uint, targetSKUs m
 	for _, order := range orders {
 		for _, item := range order.Items {
 			sku := strings.TrimSpace(item.SKU)
-			if sku == "" || item.Quantity <= 0 {
+			if sku == "" || item.Quantity <= 0 || item.IsGift {
 				continue
 			}
 			if len(targetSKUs) > 0 {
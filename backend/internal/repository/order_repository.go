@@ -3,9 +3,14 @@ package repository
 import (
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/dbutil"
+	"auralogic/internal/pkg/listfilter"
+	"auralogic/internal/pkg/piicrypt"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"gorm.io/gorm"
 	"strings"
+	"time"
 )
 
 type OrderRepository struct {
@@ -83,6 +88,59 @@ func (r *OrderRepository) FindByExternalUserID(externalUserID string, page, limi
 	return orders, total, err
 }
 
+// ExternalOrderDedupEnabled 外部订单号默认在同一平台内唯一；
+// 发起请求的 API Key 开启 allow_duplicate_external_orders 时，仅该 Key 的请求允许重复建单
+func (r *OrderRepository) ExternalOrderDedupEnabled(apiKeyID uint) (bool, error) {
+	if apiKeyID == 0 {
+		return true, nil
+	}
+	var key models.APIKey
+	err := r.db.Select("id, allow_duplicate_external_orders").Where("id = ?", apiKeyID).Take(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !key.AllowDuplicateExternalOrders, nil
+}
+
+// FindByExternalOrderID 返回占用 (platform, externalOrderID) 的订单，没有时返回 nil。
+// 占用记录指向的订单已删除时释放该记录；没有占用记录时回退查找启用去重前创建的订单
+func (r *OrderRepository) FindByExternalOrderID(platform, externalOrderID string) (*models.Order, error) {
+	if externalOrderID == "" {
+		return nil, nil
+	}
+
+	var ref models.OrderExternalRef
+	err := r.db.Where("source_platform = ? AND external_order_id = ?", platform, externalOrderID).Take(&ref).Error
+	if err == nil {
+		var order models.Order
+		orderErr := r.db.First(&order, ref.OrderID).Error
+		if orderErr == nil {
+			return &order, nil
+		}
+		if !errors.Is(orderErr, gorm.ErrRecordNotFound) {
+			return nil, orderErr
+		}
+		return nil, r.db.Delete(&ref).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var order models.Order
+	err = r.db.Where("source_platform = ? AND external_order_id = ?", platform, externalOrderID).
+		Order("id ASC").First(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
 // FindByUserID 根据UserID查找订单列表
 func (r *OrderRepository) FindByUserID(userID uint, page, limit int, status string) ([]models.Order, int64, error) {
 	var orders []models.Order
@@ -106,6 +164,25 @@ func (r *OrderRepository) FindByUserID(userID uint, page, limit int, status stri
 	return orders, total, err
 }
 
+// FindByUserIDInRange 查询用户在 [from, to) 内创建的订单（按下单时间倒序），同时返回区间内总数；
+// 总数超过 limit 时不加载订单
+func (r *OrderRepository) FindByUserIDInRange(userID uint, from, to time.Time, limit int) ([]models.Order, int64, error) {
+	var orders []models.Order
+	var total int64
+
+	query := r.db.Model(&models.Order{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total > int64(limit) {
+		return nil, total, nil
+	}
+
+	err := query.Order("created_at DESC").Find(&orders).Error
+	return orders, total, err
+}
+
 // CountByUserAndStatus returns the number of orders for a user with the specified status.
 func (r *OrderRepository) CountByUserAndStatus(userID uint, status models.OrderStatus) (int64, error) {
 	var total int64
@@ -123,6 +200,41 @@ func (r *OrderRepository) CountByUserAndStatusTx(tx *gorm.DB, userID uint, statu
 	return total, err
 }
 
+// FindPendingByBuyer 同一买家在同一店铺的其他待发货订单（合并发货候选，按下单时间倒序）
+// 登录用户按 user_id 匹配；外部平台订单按平台用户 ID 匹配，否则按下单邮箱匹配
+func (r *OrderRepository) FindPendingByBuyer(order *models.Order, limit int) ([]models.Order, error) {
+	query := r.db.Model(&models.Order{}).
+		Where("id <> ? AND status = ?", order.ID, models.OrderStatusPending)
+	if order.StoreID != nil {
+		query = query.Where("store_id = ?", *order.StoreID)
+	} else {
+		query = query.Where("store_id IS NULL")
+	}
+	switch {
+	case order.UserID != nil:
+		query = query.Where("user_id = ?", *order.UserID)
+	case order.ExternalUserID != "":
+		query = query.Where("user_id IS NULL AND source_platform = ? AND external_user_id = ?", order.SourcePlatform, order.ExternalUserID)
+	case order.UserEmail != "":
+		query = query.Where("user_id IS NULL AND LOWER(user_email) = ?", strings.ToLower(order.UserEmail))
+	default:
+		return nil, nil
+	}
+
+	var orders []models.Order
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&orders).Error
+	return orders, err
+}
+
+// FindMergedInto 已合并到指定订单的订单
+func (r *OrderRepository) FindMergedInto(orderID uint) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.Select("id", "order_no", "created_at").
+		Where("merged_into_order_id = ?", orderID).
+		Order("id ASC").Find(&orders).Error
+	return orders, err
+}
+
 // GetUserConsumptionSummary 获取用户消费汇总（基于指定订单状态）
 func (r *OrderRepository) GetUserConsumptionSummary(userID uint, statuses []models.OrderStatus) (int64, int64, error) {
 	var result struct {
@@ -130,7 +242,7 @@ func (r *OrderRepository) GetUserConsumptionSummary(userID uint, statuses []mode
 		TotalSpent int64
 	}
 
-	sumExpr := orderTotalAmountSumExpr(r.db.Dialector.Name())
+	sumExpr := dbutil.SumAsInt64Expr(dbutil.Dialect(r.db), "total_amount")
 	query := r.db.Model(&models.Order{}).
 		Select(fmt.Sprintf("COUNT(*) as order_count, %s as total_spent", sumExpr)).
 		Where("user_id = ?", userID)
@@ -146,31 +258,24 @@ func (r *OrderRepository) GetUserConsumptionSummary(userID uint, statuses []mode
 	return result.OrderCount, result.TotalSpent, nil
 }
 
-func orderTotalAmountSumExpr(dialect string) string {
-	switch dialect {
-	case "postgres":
-		return "COALESCE(SUM(total_amount)::bigint, 0)"
-	case "mysql":
-		return "COALESCE(CAST(SUM(total_amount) AS SIGNED), 0)"
-	default: // sqlite and others
-		return "COALESCE(CAST(SUM(total_amount) AS INTEGER), 0)"
-	}
-}
-
 // List 获取订单列表
-func (r *OrderRepository) List(page, limit int, status, search, country, productSearch string, promoCodeID *uint, promoCode string, userID *uint) ([]models.Order, int64, error) {
+func (r *OrderRepository) List(page, limit int, status, subStatus string, tags []string, search, country, productSearch string, promoCodeID *uint, promoCode string, userID *uint, storeScope *StoreScope, advanced *listfilter.Expr) ([]models.Order, int64, error) {
 	var orders []models.Order
 	var total int64
 
-	query := r.db.Model(&models.Order{}).Preload("User")
+	query := storeScope.Apply(r.db.Model(&models.Order{}).Preload("User"))
 
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
+	if subStatus != "" {
+		// 核心状态变化后子状态失效，不再匹配
+		query = query.Where("sub_status = ? AND sub_status_for = status", subStatus)
+	}
+	query = ApplyOrderTagFilter(query, tags)
 
 	if search != "" {
-		query = query.Where("order_no LIKE ? OR receiver_name LIKE ? OR receiver_email LIKE ?",
-			"%"+search+"%", "%"+search+"%", "%"+search+"%")
+		query = applyOrderSearch(query, search)
 	}
 
 	// 按商品SKU/名称筛选（搜索订单项的JSON字段）
@@ -200,6 +305,9 @@ func (r *OrderRepository) List(page, limit int, status, search, country, product
 		query = query.Where("promo_code_str = ?", promoCode)
 	}
 
+	// 高级筛选
+	query = advanced.Apply(query)
+
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -212,6 +320,45 @@ func (r *OrderRepository) List(page, limit int, status, search, country, product
 	return orders, total, err
 }
 
+// ApplyOrderTagFilter 只保留同时带有全部标签的订单
+// tags 列为 JSON 数组文本，按带引号的编码值匹配以避免前缀误命中
+func ApplyOrderTagFilter(query *gorm.DB, tags []string) *gorm.DB {
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		encoded, _ := json.Marshal(tag)
+		query = query.Where("orders.tags LIKE ?", "%"+string(encoded)+"%")
+	}
+	return query
+}
+
+// applyOrderSearch 订单号模糊匹配；收件信息加密后姓名不可检索，邮箱/电话按盲索引精确匹配
+func applyOrderSearch(query *gorm.DB, search string) *gorm.DB {
+	condition, args := OrderSearchCondition(search)
+	return query.Where(condition, args...)
+}
+
+// OrderSearchCondition 订单号/收件人关键词匹配条件；启用 PII 加密时收件人只能按盲索引精确匹配
+func OrderSearchCondition(search string) (string, []interface{}) {
+	like := "%" + search + "%"
+	if piicrypt.Current() == nil {
+		return "order_no LIKE ? OR receiver_name LIKE ? OR receiver_email LIKE ?", []interface{}{like, like, like}
+	}
+	conditions := []string{"order_no LIKE ?"}
+	args := []interface{}{like}
+	if index := piicrypt.EmailIndex(search); index != "" {
+		conditions = append(conditions, "receiver_email_bidx = ?")
+		args = append(args, index)
+	}
+	if index := piicrypt.PhoneIndex(search); index != "" {
+		conditions = append(conditions, "receiver_phone_bidx = ?")
+		args = append(args, index)
+	}
+	return strings.Join(conditions, " OR "), args
+}
+
 // Update 更新订单
 func (r *OrderRepository) Update(order *models.Order) error {
 	return r.db.Save(order).Error
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
 	"gorm.io/gorm"
 )
 
@@ -22,6 +23,10 @@ func (r *ProductRepository) Create(product *models.Product) error {
 	return r.db.Create(product).Error
 }
 
+func (r *ProductRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
 // Update UpdateProduct
 func (r *ProductRepository) Update(product *models.Product) error {
 	return r.db.Save(product).Error
@@ -85,17 +90,33 @@ func (r *ProductRepository) FindBySKUs(skus []string) (map[string]*models.Produc
 	return productBySKU, nil
 }
 
+// FindByIDs 批量按ID查找商品，不存在的ID不会出现在结果中
+func (r *ProductRepository) FindByIDs(ids []uint) (map[uint]*models.Product, error) {
+	productByID := make(map[uint]*models.Product, len(ids))
+	if len(ids) == 0 {
+		return productByID, nil
+	}
+	var products []models.Product
+	if err := r.db.Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	for i := range products {
+		productByID[products[i].ID] = &products[i]
+	}
+	return productByID, nil
+}
+
 // Delete 删除商品（软删除）
 func (r *ProductRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Product{}, id).Error
 }
 
 // List 获取商品列表
-func (r *ProductRepository) List(page, limit int, status, category, search string, isFeatured *bool, isRecommended *bool, isActive bool) ([]models.Product, int64, error) {
+func (r *ProductRepository) List(page, limit int, status, category, search string, isFeatured *bool, isRecommended *bool, isActive bool, storeScope *StoreScope) ([]models.Product, int64, error) {
 	var products []models.Product
 	var total int64
 
-	query := r.db.Model(&models.Product{})
+	query := storeScope.Apply(r.db.Model(&models.Product{}))
 
 	// 筛选条件（状态、分类、搜索、是否精选、是否上架）
 	if status != "" {
@@ -105,8 +126,7 @@ func (r *ProductRepository) List(page, limit int, status, category, search strin
 		query = query.Where("category = ?", category)
 	}
 	if search != "" {
-		query = query.Where("name LIKE ? OR sku LIKE ? OR description LIKE ?",
-			"%"+search+"%", "%"+search+"%", "%"+search+"%")
+		query = dbutil.WhereContainsFold(query, search, "name", "sku", "description")
 	}
 	if isFeatured != nil {
 		query = query.Where("is_featured = ?", *isFeatured)
@@ -1,13 +1,20 @@
 package repository
 
 import (
-	"fmt"
+	"errors"
 
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/dbutil"
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrPromoCodeUnavailable 优惠码已停用或过期
+	ErrPromoCodeUnavailable = errors.New("promo code is not available")
+	// ErrPromoCodeExhausted 优惠码可用次数已用完
+	ErrPromoCodeExhausted = errors.New("promo code usage limit reached")
+)
+
 type PromoCodeRepository struct {
 	db *gorm.DB
 }
@@ -51,7 +58,7 @@ func (r *PromoCodeRepository) List(page, limit int, status string, search string
 		query = query.Where("status = ?", status)
 	}
 	if search != "" {
-		query = query.Where("code LIKE ? OR name LIKE ?", "%"+search+"%", "%"+search+"%")
+		query = dbutil.WhereContainsFold(query, search, "code", "name")
 	}
 
 	err := query.Count(&total).Error
@@ -74,66 +81,228 @@ func (r *PromoCodeRepository) Delete(id uint) error {
 }
 
 // Reserve 预留优惠码（下单时）
+// 通过带条件的原子 UPDATE 占用名额，并发下也不会超出总量
 func (r *PromoCodeRepository) Reserve(promoCodeID uint, orderNo string) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := dbutil.LockForUpdate(tx, &models.PromoCode{}, "id = ?", promoCodeID); err != nil {
-			return err
+		result := tx.Model(&models.PromoCode{}).
+			Where("id = ? AND status = ?", promoCodeID, models.PromoCodeStatusActive).
+			Where("expires_at IS NULL OR expires_at > ?", models.NowFunc()).
+			Where("total_quantity = 0 OR used_quantity + reserved_quantity < total_quantity").
+			Updates(map[string]interface{}{
+				"reserved_quantity": gorm.Expr("CASE WHEN total_quantity > 0 THEN reserved_quantity + 1 ELSE reserved_quantity END"),
+			})
+		if result.Error != nil {
+			return result.Error
 		}
-
-		var promoCode models.PromoCode
-		if err := tx.First(&promoCode, promoCodeID).Error; err != nil {
-			return err
-		}
-
-		if !promoCode.IsAvailable() {
-			return fmt.Errorf("promo code is not available")
+		if result.RowsAffected == 0 {
+			// 未命中时区分原因
+			var promoCode models.PromoCode
+			if err := tx.First(&promoCode, promoCodeID).Error; err != nil {
+				return err
+			}
+			if promoCode.IsExhausted() {
+				return ErrPromoCodeExhausted
+			}
+			return ErrPromoCodeUnavailable
 		}
 
-		if promoCode.TotalQuantity > 0 {
-			promoCode.ReservedQuantity++
+		if orderNo == "" {
+			return nil
 		}
-
-		return tx.Save(&promoCode).Error
+		return tx.Create(&models.PromoCodeRedemption{
+			PromoCodeID: promoCodeID,
+			OrderNo:     orderNo,
+			Status:      models.PromoCodeRedemptionReserved,
+		}).Error
 	})
 }
 
 // ReleaseReserve 释放预留优惠码（取消订单）
 func (r *PromoCodeRepository) ReleaseReserve(promoCodeID uint, orderNo string) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := dbutil.LockForUpdate(tx, &models.PromoCode{}, "id = ?", promoCodeID); err != nil {
+		if err := tx.Model(&models.PromoCode{}).
+			Where("id = ? AND total_quantity > 0 AND reserved_quantity > 0", promoCodeID).
+			Updates(map[string]interface{}{
+				"reserved_quantity": gorm.Expr("reserved_quantity - 1"),
+			}).Error; err != nil {
 			return err
 		}
+		if orderNo == "" {
+			return nil
+		}
+		now := models.NowFunc()
+		return tx.Model(&models.PromoCodeRedemption{}).
+			Where("promo_code_id = ? AND order_no = ? AND status = ?", promoCodeID, orderNo, models.PromoCodeRedemptionReserved).
+			Updates(map[string]interface{}{
+				"status":      models.PromoCodeRedemptionReleased,
+				"released_at": now,
+			}).Error
+	})
+}
 
-		var promoCode models.PromoCode
-		if err := tx.First(&promoCode, promoCodeID).Error; err != nil {
+// Deduct 扣减优惠码（订单完成），预留转为已使用
+func (r *PromoCodeRepository) Deduct(promoCodeID uint, orderNo string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PromoCode{}).
+			Where("id = ?", promoCodeID).
+			Updates(map[string]interface{}{
+				"used_quantity":     gorm.Expr("used_quantity + 1"),
+				"reserved_quantity": gorm.Expr("CASE WHEN total_quantity > 0 AND reserved_quantity > 0 THEN reserved_quantity - 1 ELSE reserved_quantity END"),
+			}).Error; err != nil {
 			return err
 		}
-
-		if promoCode.TotalQuantity > 0 && promoCode.ReservedQuantity > 0 {
-			promoCode.ReservedQuantity--
+		if orderNo == "" {
+			return nil
 		}
 
-		return tx.Save(&promoCode).Error
+		now := models.NowFunc()
+		result := tx.Model(&models.PromoCodeRedemption{}).
+			Where("promo_code_id = ? AND order_no = ? AND status = ?", promoCodeID, orderNo, models.PromoCodeRedemptionReserved).
+			Updates(map[string]interface{}{
+				"status":      models.PromoCodeRedemptionRedeemed,
+				"redeemed_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+		// 记录功能上线前预留的订单没有预留记录
+		return tx.Create(&models.PromoCodeRedemption{
+			PromoCodeID: promoCodeID,
+			OrderNo:     orderNo,
+			Status:      models.PromoCodeRedemptionRedeemed,
+			RedeemedAt:  &now,
+		}).Error
 	})
 }
 
-// Deduct 扣减优惠码（订单完成）
-func (r *PromoCodeRepository) Deduct(promoCodeID uint, orderNo string) error {
+// ListAutoApplyCandidates 可在结算时推荐的公开优惠码（有效、未过期、有剩余名额，不含活动一次性码）
+func (r *PromoCodeRepository) ListAutoApplyCandidates() ([]models.PromoCode, error) {
+	var promoCodes []models.PromoCode
+	err := r.db.Where("auto_apply = ? AND status = ? AND campaign_id IS NULL", true, models.PromoCodeStatusActive).
+		Where("expires_at IS NULL OR expires_at > ?", models.NowFunc()).
+		Where("total_quantity = 0 OR used_quantity + reserved_quantity < total_quantity").
+		Order("id ASC").
+		Find(&promoCodes).Error
+	return promoCodes, err
+}
+
+// ListRedemptions 优惠码使用记录（新到旧）
+func (r *PromoCodeRepository) ListRedemptions(promoCodeID uint, page, limit int) ([]models.PromoCodeRedemption, int64, error) {
+	var (
+		items []models.PromoCodeRedemption
+		total int64
+	)
+	query := r.db.Model(&models.PromoCodeRedemption{}).Where("promo_code_id = ?", promoCodeID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&items).Error
+	return items, total, err
+}
+
+// FindExistingCodes 返回已被占用的优惠码（含已软删除）
+func (r *PromoCodeRepository) FindExistingCodes(codes []string) ([]string, error) {
+	var existing []string
+	if len(codes) == 0 {
+		return existing, nil
+	}
+	err := r.db.Unscoped().Model(&models.PromoCode{}).Where("code IN ?", codes).Pluck("code", &existing).Error
+	return existing, err
+}
+
+// CreateCampaign 创建活动并批量写入优惠码
+func (r *PromoCodeRepository) CreateCampaign(campaign *models.PromoCodeCampaign, promoCodes []models.PromoCode) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := dbutil.LockForUpdate(tx, &models.PromoCode{}, "id = ?", promoCodeID); err != nil {
+		if err := tx.Create(campaign).Error; err != nil {
 			return err
 		}
-
-		var promoCode models.PromoCode
-		if err := tx.First(&promoCode, promoCodeID).Error; err != nil {
-			return err
+		for i := range promoCodes {
+			promoCodes[i].CampaignID = &campaign.ID
 		}
+		return tx.CreateInBatches(promoCodes, 200).Error
+	})
+}
 
-		promoCode.UsedQuantity++
-		if promoCode.TotalQuantity > 0 && promoCode.ReservedQuantity > 0 {
-			promoCode.ReservedQuantity--
-		}
+// FindCampaignByID 根据ID查找活动
+func (r *PromoCodeRepository) FindCampaignByID(id uint) (*models.PromoCodeCampaign, error) {
+	var campaign models.PromoCodeCampaign
+	err := r.db.First(&campaign, id).Error
+	return &campaign, err
+}
 
-		return tx.Save(&promoCode).Error
-	})
+// PromoCodeCampaignStats 活动优惠码使用统计
+type PromoCodeCampaignStats struct {
+	CampaignID    uint  `json:"campaign_id"`
+	RedeemedCount int64 `json:"redeemed_count"`
+	ReservedCount int64 `json:"reserved_count"`
+}
+
+// ListCampaigns 活动分页列表（附带使用统计）
+func (r *PromoCodeRepository) ListCampaigns(page, limit int) ([]models.PromoCodeCampaign, map[uint]PromoCodeCampaignStats, int64, error) {
+	var (
+		campaigns []models.PromoCodeCampaign
+		total     int64
+	)
+	query := r.db.Model(&models.PromoCodeCampaign{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, 0, err
+	}
+	if err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&campaigns).Error; err != nil {
+		return nil, nil, 0, err
+	}
+
+	stats := make(map[uint]PromoCodeCampaignStats, len(campaigns))
+	if len(campaigns) == 0 {
+		return campaigns, stats, total, nil
+	}
+	ids := make([]uint, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		ids = append(ids, campaign.ID)
+	}
+	var rows []PromoCodeCampaignStats
+	if err := r.db.Model(&models.PromoCode{}).
+		Select("campaign_id, "+
+			"SUM(CASE WHEN used_quantity > 0 THEN 1 ELSE 0 END) AS redeemed_count, "+
+			"SUM(CASE WHEN reserved_quantity > 0 THEN 1 ELSE 0 END) AS reserved_count").
+		Where("campaign_id IN ?", ids).
+		Group("campaign_id").
+		Scan(&rows).Error; err != nil {
+		return nil, nil, 0, err
+	}
+	for _, row := range rows {
+		stats[row.CampaignID] = row
+	}
+	return campaigns, stats, total, nil
+}
+
+// ListByCampaign 活动下的全部优惠码
+func (r *PromoCodeRepository) ListByCampaign(campaignID uint) ([]models.PromoCode, error) {
+	var promoCodes []models.PromoCode
+	err := r.db.Where("campaign_id = ?", campaignID).Order("id ASC").Find(&promoCodes).Error
+	return promoCodes, err
+}
+
+// LatestRedemptions 每个优惠码最近一次使用记录
+func (r *PromoCodeRepository) LatestRedemptions(promoCodeIDs []uint) (map[uint]models.PromoCodeRedemption, error) {
+	result := make(map[uint]models.PromoCodeRedemption, len(promoCodeIDs))
+	if len(promoCodeIDs) == 0 {
+		return result, nil
+	}
+	for start := 0; start < len(promoCodeIDs); start += 500 {
+		end := start + 500
+		if end > len(promoCodeIDs) {
+			end = len(promoCodeIDs)
+		}
+		var items []models.PromoCodeRedemption
+		if err := r.db.Where("promo_code_id IN ?", promoCodeIDs[start:end]).Order("id ASC").Find(&items).Error; err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			result[item.PromoCodeID] = item
+		}
+	}
+	return result, nil
 }
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) *RefundRepository {
+	return &RefundRepository{db: db}
+}
+
+// Create 创建退款记录
+func (r *RefundRepository) Create(refund *models.OrderRefund) error {
+	return r.db.Create(refund).Error
+}
+
+// Save 更新退款记录
+func (r *RefundRepository) Save(refund *models.OrderRefund) error {
+	return r.db.Save(refund).Error
+}
+
+// FindByID 查找订单下的退款记录
+func (r *RefundRepository) FindByID(orderID, refundID uint) (*models.OrderRefund, error) {
+	var refund models.OrderRefund
+	if err := r.db.Where("order_id = ?", orderID).First(&refund, refundID).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// ListByOrder 按创建时间列出订单的全部退款记录
+func (r *RefundRepository) ListByOrder(orderID uint) ([]models.OrderRefund, error) {
+	var refunds []models.OrderRefund
+	err := r.db.Where("order_id = ?", orderID).Order("created_at ASC, id ASC").Find(&refunds).Error
+	return refunds, err
+}
+
+// ListByOrderAndStatus 列出订单下指定状态的退款记录
+func (r *RefundRepository) ListByOrderAndStatus(orderID uint, status models.OrderRefundStatus) ([]models.OrderRefund, error) {
+	var refunds []models.OrderRefund
+	err := r.db.Where("order_id = ? AND status = ?", orderID, status).Order("id ASC").Find(&refunds).Error
+	return refunds, err
+}
@@ -191,6 +191,13 @@ func (r *SerialRepository) GetStatistics() (map[string]interface{}, error) {
 	}
 	stats["total_views"] = totalViews
 
+	// 退货作废的序列号数
+	var invalidatedCount int64
+	if err := r.db.Model(&models.ProductSerial{}).Where("invalidated_at IS NOT NULL").Count(&invalidatedCount).Error; err != nil {
+		return nil, err
+	}
+	stats["invalidated_count"] = invalidatedCount
+
 	return stats, nil
 }
 
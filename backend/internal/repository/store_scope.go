@@ -0,0 +1,29 @@
+package repository
+
+import "gorm.io/gorm"
+
+// StoreScope 多店铺查询范围
+// StoreIDs 为空时不做限制；IncludeShared 为 true 时额外包含未绑定店铺（store_id 为空）的共享数据
+type StoreScope struct {
+	StoreIDs      []uint
+	IncludeShared bool
+}
+
+// StorefrontScope 店面侧查询范围：当前店铺数据 + 共享数据
+func StorefrontScope(storeID uint) *StoreScope {
+	if storeID == 0 {
+		return nil
+	}
+	return &StoreScope{StoreIDs: []uint{storeID}, IncludeShared: true}
+}
+
+// Apply 将店铺范围应用到查询（nil 安全）
+func (s *StoreScope) Apply(query *gorm.DB) *gorm.DB {
+	if s == nil || len(s.StoreIDs) == 0 {
+		return query
+	}
+	if s.IncludeShared {
+		return query.Where("(store_id IN ? OR store_id IS NULL)", s.StoreIDs)
+	}
+	return query.Where("store_id IN ?", s.StoreIDs)
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
+	"auralogic/internal/pkg/listfilter"
 	"gorm.io/gorm"
 	"strings"
 )
@@ -20,6 +22,7 @@ type UserListFilters struct {
 	HasPhone             *bool
 	Locale               string
 	Country              string
+	Advanced             *listfilter.Expr // 高级筛选
 }
 
 // ListCountries returns distinct, normalized country codes that exist on users.
@@ -150,8 +153,7 @@ func (r *UserRepository) List(page, limit int, filters UserListFilters) ([]model
 
 	search := strings.TrimSpace(filters.Search)
 	if search != "" {
-		like := "%" + search + "%"
-		query = query.Where("email LIKE ? OR name LIKE ? OR phone LIKE ?", like, like, like)
+		query = dbutil.WhereContainsFold(query, search, "email", "name", "phone")
 	}
 
 	switch strings.ToLower(strings.TrimSpace(filters.Role)) {
@@ -194,6 +196,7 @@ func (r *UserRepository) List(page, limit int, filters UserListFilters) ([]model
 	if country != "" {
 		query = query.Where("LOWER(country) = LOWER(?)", country)
 	}
+	query = filters.Advanced.Apply(query)
 
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
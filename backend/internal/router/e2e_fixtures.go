@@ -0,0 +1,24 @@
+//go:build e2e
+
+package router
+
+import (
+	"log"
+
+	"auralogic/internal/config"
+	e2eHandler "auralogic/internal/handler/e2e"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// registerE2EFixtureRoutes 注册端到端测试夹具接口；生产环境即使带 e2e 标签构建也不注册
+func registerE2EFixtureRoutes(r *gin.Engine, cfg *config.Config, db *gorm.DB, orderService *service.OrderService, serialService *service.SerialService, pluginManager *service.PluginManagerService) {
+	if cfg.App.Env == "production" || orderService == nil {
+		log.Println("E2E fixture API disabled")
+		return
+	}
+	handler := e2eHandler.NewFixtureHandler(service.NewE2EFixtureService(db, cfg, orderService, serialService, pluginManager))
+	handler.RegisterRoutes(r.Group("/api/test"))
+	log.Println("WARNING: E2E fixture API enabled at /api/test, never expose this build publicly")
+}
@@ -0,0 +1,14 @@
+//go:build !e2e
+
+package router
+
+import (
+	"auralogic/internal/config"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// registerE2EFixtureRoutes 默认构建不包含测试夹具接口
+func registerE2EFixtureRoutes(*gin.Engine, *config.Config, *gorm.DB, *service.OrderService, *service.SerialService, *service.PluginManagerService) {
+}
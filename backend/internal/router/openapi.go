@@ -0,0 +1,294 @@
+package router
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	adminHandler "auralogic/internal/handler/admin"
+	formHandler "auralogic/internal/handler/form"
+	userHandler "auralogic/internal/handler/user"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/openapi"
+	"auralogic/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// registerOpenAPIRoute 提供 /api/openapi.json；文档在首次请求时由已注册的全部路由生成并缓存
+func registerOpenAPIRoute(r *gin.Engine, version string) {
+	var (
+		once sync.Once
+		doc  *openapi.Document
+	)
+	r.GET("/api/openapi.json", func(c *gin.Context) {
+		once.Do(func() {
+			doc = openapi.Build(r.Routes(), newOpenAPIRegistry(), openapi.Options{
+				Title:             "AuraLogic API",
+				Description:       "All responses use the {code, message, data} envelope; code 0 means success. See docs/API.md for business rules.",
+				Version:           version,
+				PathPrefixes:      []string{"/api"},
+				ProtectedPrefixes: []string{"/api/admin", "/api/user"},
+			})
+		})
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, doc)
+	})
+}
+
+// userSummary 登录和 /auth/me 返回的用户信息（不含敏感字段）
+var userSummary = gin.H{
+	"id":                uint(0),
+	"uuid":              "",
+	"email":             "",
+	"name":              "",
+	"role":              "",
+	"avatar":            "",
+	"is_active":         false,
+	"locale":            "",
+	"country":           "",
+	"display_currency":  "",
+	"email_verified":    false,
+	"total_spent_minor": int64(0),
+	"total_order_count": 0,
+	"created_at":        time.Time{},
+	"permissions":       []string{},
+}
+
+// authTokenResponse 登录类接口的返回；Cookie 会话模式下 token 为空，改为返回 csrf_token
+var authTokenResponse = gin.H{
+	"user":       userSummary,
+	"token":      "",
+	"token_type": "",
+	"csrf_token": "",
+}
+
+var messageResponse = gin.H{"message": ""}
+
+// newOpenAPIRegistry 登记处理函数的请求/响应结构；未登记的路由仍会收录路径、参数和鉴权要求
+func newOpenAPIRegistry() *openapi.Registry {
+	reg := openapi.NewRegistry()
+
+	// 用户认证（公开）
+	reg.Describe((*userHandler.AuthHandler).Login, openapi.Route{Request: userHandler.LoginRequest{}, Response: authTokenResponse, Public: true})
+	reg.Describe((*userHandler.AuthHandler).Register, openapi.Route{Request: userHandler.RegisterRequest{}, Response: authTokenResponse, Public: true})
+	reg.Describe((*userHandler.AuthHandler).LoginWithCode, openapi.Route{Request: userHandler.LoginWithCodeRequest{}, Response: authTokenResponse, Public: true})
+	reg.Describe((*userHandler.AuthHandler).SendLoginCode, openapi.Route{Request: userHandler.SendLoginCodeRequest{}, Response: messageResponse, Public: true})
+	reg.Describe((*userHandler.AuthHandler).ForgotPassword, openapi.Route{Request: userHandler.ForgotPasswordRequest{}, Response: messageResponse, Public: true})
+	reg.Describe((*userHandler.AuthHandler).ResetPassword, openapi.Route{Request: userHandler.ResetPasswordRequest{}, Response: messageResponse, Public: true})
+	reg.Describe((*userHandler.AuthHandler).LoginWithPhoneCode, openapi.Route{Response: authTokenResponse, Public: true})
+	reg.Describe((*userHandler.AuthHandler).PhoneRegister, openapi.Route{Response: authTokenResponse, Public: true})
+	reg.Describe((*userHandler.AuthHandler).VerifyEmail, openapi.Route{Query: []openapi.Param{{Name: "token", Required: true}}, Public: true})
+	for _, handler := range []interface{}{
+		(*userHandler.AuthHandler).GetCaptcha,
+		(*userHandler.AuthHandler).GetCSRFToken,
+		(*userHandler.AuthHandler).ResendVerification,
+		(*userHandler.AuthHandler).RevertEmailChange,
+		(*userHandler.AuthHandler).SendPhoneLoginCode,
+		(*userHandler.AuthHandler).SendPhoneRegisterCode,
+		(*userHandler.AuthHandler).PhoneForgotPassword,
+		(*userHandler.AuthHandler).PhoneResetPassword,
+	} {
+		reg.Describe(handler, openapi.Route{Public: true})
+	}
+	reg.Describe((*userHandler.AuthHandler).GetMe, openapi.Route{Response: userSummary})
+	reg.Describe((*userHandler.AuthHandler).ChangePassword, openapi.Route{Request: userHandler.ChangePasswordRequest{}, Response: messageResponse})
+	reg.Describe((*userHandler.AuthHandler).UpdatePreferences, openapi.Route{Request: userHandler.UpdatePreferencesRequest{}})
+
+	// 用户订单
+	reg.Describe((*userHandler.OrderHandler).CreateOrder, openapi.Route{
+		Request:  userHandler.CreateOrderRequest{},
+		Response: gin.H{"order_id": uint(0), "order_no": "", "status": models.OrderStatus(""), "created_at": time.Time{}},
+	})
+	reg.Describe((*userHandler.OrderHandler).ListOrders, openapi.Route{
+		Query:     []openapi.Param{{Name: "status"}, {Name: "search"}},
+		Response:  models.Order{},
+		Paginated: true,
+	})
+	reg.Describe((*userHandler.OrderHandler).ExportOrders, openapi.Route{
+		Summary: "Export own orders as CSV or XLSX file",
+		Query:   []openapi.Param{{Name: "format", Description: "csv or xlsx (default)"}, {Name: "start_date", Description: "YYYY-MM-DD"}, {Name: "end_date", Description: "YYYY-MM-DD"}},
+	})
+	reg.Describe((*userHandler.OrderHandler).CompleteOrder, openapi.Route{Request: userHandler.CompleteOrderRequest{}})
+	reg.Describe((*userHandler.OrderHandler).ClaimOrder, openapi.Route{Request: userHandler.ClaimOrderRequest{}})
+	reg.Describe((*userHandler.OrderHandler).SendOrderMessage, openapi.Route{Request: userHandler.SendOrderMessageRequest{}})
+	reg.Describe((*userHandler.OrderHandler).ViewInvoiceByToken, openapi.Route{Public: true})
+	reg.Describe((*userHandler.OrderHandler).GetVirtualDeliveryBySignedLink, openapi.Route{
+		Query:  []openapi.Param{{Name: "expires", Type: "integer", Required: true}, {Name: "sig", Required: true}},
+		Public: true,
+	})
+
+	// 商品目录（是否需要登录由配置决定）
+	reg.Describe((*userHandler.CatalogChallengeHandler).GetChallenge, openapi.Route{Public: true})
+	reg.Describe((*userHandler.ProductHandler).ListProducts, openapi.Route{
+		Query:     []openapi.Param{{Name: "category"}, {Name: "search"}, {Name: "is_featured", Type: "boolean"}},
+		Response:  models.Product{},
+		Paginated: true,
+		Public:    true,
+	})
+	reg.Describe((*userHandler.ProductHandler).GetProduct, openapi.Route{Response: models.Product{}, Public: true})
+	reg.Describe((*userHandler.ProductHandler).GetProductAvailableStock, openapi.Route{Public: true})
+	reg.Describe((*userHandler.ProductHandler).GetCategories, openapi.Route{Response: gin.H{"categories": []string{}}, Public: true})
+	reg.Describe((*userHandler.ProductHandler).GetFeaturedProducts, openapi.Route{Response: gin.H{"products": []models.Product{}}, Public: true})
+	reg.Describe((*userHandler.ProductHandler).GetRecommendedProducts, openapi.Route{Response: gin.H{"products": []models.Product{}}, Public: true})
+
+	// 购物车与优惠码
+	reg.Describe((*userHandler.CartHandler).GetCart, openapi.Route{Response: gin.H{
+		"items":             []models.CartItemWithStock{},
+		"gifts":             []service.GiftLine{},
+		"total_price_minor": int64(0),
+		"total_quantity":    0,
+		"item_count":        0,
+	}})
+	reg.Describe((*userHandler.CartHandler).AddToCart, openapi.Route{Request: userHandler.AddToCartRequest{}})
+	reg.Describe((*userHandler.CartHandler).UpdateQuantity, openapi.Route{Request: userHandler.UpdateQuantityRequest{}})
+	reg.Describe((*userHandler.PromoCodeHandler).ValidatePromoCode, openapi.Route{Request: userHandler.ValidatePromoCodeRequest{}})
+	reg.Describe((*userHandler.PromoCodeHandler).SuggestPromoCodes, openapi.Route{Request: userHandler.SuggestPromoCodesRequest{}})
+
+	// 用户工单
+	reg.Describe((*userHandler.TicketHandler).CreateTicket, openapi.Route{Request: userHandler.CreateTicketRequest{}, Response: models.Ticket{}})
+	reg.Describe((*userHandler.TicketHandler).ListTickets, openapi.Route{
+		Query:     []openapi.Param{{Name: "status"}, {Name: "search"}},
+		Response:  models.Ticket{},
+		Paginated: true,
+	})
+	reg.Describe((*userHandler.TicketHandler).GetTicket, openapi.Route{Response: models.Ticket{}})
+	reg.Describe((*userHandler.TicketHandler).GetTicketMessages, openapi.Route{Response: []models.TicketMessage{}})
+	reg.Describe((*userHandler.TicketHandler).SendMessage, openapi.Route{Request: userHandler.SendMessageRequest{}, Response: models.TicketMessage{}})
+	reg.Describe((*userHandler.TicketHandler).UpdateTicketStatus, openapi.Route{Request: userHandler.UpdateTicketStatusRequest{}, Response: messageResponse})
+	reg.Describe((*userHandler.TicketHandler).ShareOrder, openapi.Route{Request: userHandler.ShareOrderRequest{}, Response: messageResponse})
+	reg.Describe((*userHandler.TicketHandler).GetSharedOrders, openapi.Route{Response: []models.TicketOrderAccess{}})
+	reg.Describe((*userHandler.TicketHandler).UploadFile, openapi.Route{Multipart: true})
+
+	// 收货信息表单
+	reg.Describe((*formHandler.ShippingHandler).GetForm, openapi.Route{Query: []openapi.Param{{Name: "token", Required: true}}})
+	reg.Describe((*formHandler.ShippingHandler).SubmitForm, openapi.Route{Request: formHandler.SubmitFormRequest{}})
+
+	// 管理端订单
+	reg.Describe((*adminHandler.OrderHandler).ListOrders, openapi.Route{
+		Query: []openapi.Param{
+			{Name: "status"}, {Name: "sub_status"}, {Name: "search"}, {Name: "country"}, {Name: "product_search"},
+			{Name: "promo_code_id", Type: "integer"}, {Name: "promo_code"}, {Name: "user_id", Type: "integer"},
+			{Name: "store_id", Type: "integer"}, {Name: "filter", Description: "Advanced filter expression"},
+			{Name: "tag", Description: "Comma-separated tags; orders must carry all of them"},
+		},
+		Response:  models.Order{},
+		Paginated: true,
+	})
+	reg.Describe((*adminHandler.OrderHandler).CreateDraft, openapi.Route{Request: adminHandler.CreateDraftRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).CreateOrderForUser, openapi.Route{Request: adminHandler.CreateOrderForUserRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).AssignTracking, openapi.Route{Request: adminHandler.AssignTrackingRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).UpdateShippingInfo, openapi.Route{Request: adminHandler.UpdateShippingInfoRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).UpdateOrderPrice, openapi.Route{Request: adminHandler.UpdateOrderPriceRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).UpdateOrderSubStatus, openapi.Route{Request: adminHandler.UpdateOrderSubStatusRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).UpdateOrderTags, openapi.Route{Request: adminHandler.UpdateOrderTagsRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).RequestResubmit, openapi.Route{Request: adminHandler.RequestResubmitRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).CompleteOrder, openapi.Route{Request: adminHandler.CompleteOrderRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).CancelOrder, openapi.Route{Request: adminHandler.CancelOrderRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).RefundOrder, openapi.Route{Request: adminHandler.RefundOrderRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).ConfirmRefund, openapi.Route{Request: adminHandler.ConfirmRefundRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).ConfirmOrderRefund, openapi.Route{Request: adminHandler.ConfirmRefundRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).CreateOrderRefund, openapi.Route{Request: adminHandler.CreateOrderRefundRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).BatchUpdateOrders, openapi.Route{Request: adminHandler.BatchUpdateOrdersRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).ExportOrders, openapi.Route{Request: adminHandler.ExportOrdersRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).ExportCustomsDeclarations, openapi.Route{Request: adminHandler.ExportOrdersRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).CreateOrderNote, openapi.Route{Request: adminHandler.CreateOrderNoteRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).UpdateOrderNote, openapi.Route{Request: adminHandler.UpdateOrderNoteRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).PinOrderNote, openapi.Route{Request: adminHandler.PinOrderNoteRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).ReplyOrderMessage, openapi.Route{Request: adminHandler.ReplyOrderMessageRequest{}})
+	reg.Describe((*adminHandler.OrderHandler).ReceiveOrderReturn, openapi.Route{Request: adminHandler.ReceiveOrderReturnRequest{}})
+	reg.Describe((*adminHandler.OrderCodeLookupHandler).LookupByCode, openapi.Route{Request: adminHandler.OrderCodeLookupRequest{}})
+	reg.Describe((*adminHandler.OrderAutomationHandler).DryRunAutomationRule, openapi.Route{Request: adminHandler.OrderAutomationDryRunRequest{}})
+	reg.Describe((*adminHandler.ReturnRequestHandler).RejectReturnRequest, openapi.Route{Request: adminHandler.ReviewReturnRequest{}})
+	reg.Describe((*adminHandler.ReturnRequestHandler).ReceiveReturnRequest, openapi.Route{Request: adminHandler.ReceiveReturnItemsRequest{}})
+
+	// 管理端商品与库存
+	reg.Describe((*adminHandler.ProductHandler).ListProducts, openapi.Route{Response: models.Product{}, Paginated: true})
+	reg.Describe((*adminHandler.ProductHandler).CreateProduct, openapi.Route{Request: adminHandler.CreateProductRequest{}, Response: models.Product{}})
+	reg.Describe((*adminHandler.ProductHandler).UpdateProduct, openapi.Route{Request: adminHandler.UpdateProductRequest{}, Response: models.Product{}})
+	reg.Describe((*adminHandler.ProductHandler).UpdateProductStatus, openapi.Route{Request: adminHandler.UpdateStatusRequest{}, Response: models.Product{}})
+	reg.Describe((*adminHandler.ProductHandler).UpdateStock, openapi.Route{Request: adminHandler.UpdateStockRequest{}, Response: models.Product{}})
+	reg.Describe((*adminHandler.ProductHandler).UpdateInventoryMode, openapi.Route{Request: adminHandler.UpdateInventoryModeRequest{}})
+	reg.Describe((*adminHandler.ProductHandler).ToggleFeatured, openapi.Route{Response: models.Product{}})
+	reg.Describe((*adminHandler.ProductHandler).GetCategories, openapi.Route{Response: gin.H{"categories": []string{}}})
+	reg.Describe((*adminHandler.InventoryHandler).ListInventories, openapi.Route{Response: models.Inventory{}, Paginated: true})
+	reg.Describe((*adminHandler.InventoryHandler).GetInventory, openapi.Route{Response: models.Inventory{}})
+	reg.Describe((*adminHandler.InventoryHandler).CreateInventory, openapi.Route{Request: adminHandler.CreateInventoryRequest{}, Response: models.Inventory{}})
+	reg.Describe((*adminHandler.InventoryHandler).UpdateInventory, openapi.Route{Request: adminHandler.UpdateInventoryRequest{}, Response: models.Inventory{}})
+	reg.Describe((*adminHandler.InventoryHandler).AdjustStock, openapi.Route{Request: adminHandler.AdjustStockRequest{}, Response: models.Inventory{}})
+	reg.Describe((*adminHandler.InventoryHandler).GetProductInventories, openapi.Route{Response: []models.Inventory{}})
+	reg.Describe((*adminHandler.InventoryHandler).GetLowStockList, openapi.Route{Response: []models.Inventory{}})
+	reg.Describe((*adminHandler.BindingHandler).CreateBinding, openapi.Route{Request: adminHandler.CreateBindingRequest{}})
+	reg.Describe((*adminHandler.BindingHandler).UpdateBinding, openapi.Route{Request: adminHandler.UpdateBindingRequest{}})
+	reg.Describe((*adminHandler.BindingHandler).BatchCreateBindings, openapi.Route{Request: adminHandler.BatchCreateBindingsRequest{}})
+	reg.Describe((*adminHandler.BindingHandler).ReplaceProductBindings, openapi.Route{Request: adminHandler.BatchCreateBindingsRequest{}})
+	reg.Describe((*adminHandler.VirtualInventoryHandler).TransferStock, openapi.Route{Request: adminHandler.TransferVirtualStockRequest{}})
+	reg.Describe((*adminHandler.VirtualInventoryHandler).RevokeStock, openapi.Route{Request: adminHandler.RevokeVirtualStockRequest{}})
+	reg.Describe((*adminHandler.VirtualInventoryHandler).RedeliverOrderStock, openapi.Route{Request: adminHandler.RedeliverVirtualStockRequest{}})
+
+	// 管理端优惠码与支付方式
+	reg.Describe((*adminHandler.PromoCodeHandler).ListPromoCodes, openapi.Route{Response: models.PromoCode{}, Paginated: true})
+	reg.Describe((*adminHandler.PromoCodeHandler).GetPromoCode, openapi.Route{Response: models.PromoCode{}})
+	reg.Describe((*adminHandler.PromoCodeHandler).CreatePromoCode, openapi.Route{Request: adminHandler.CreatePromoCodeRequest{}, Response: models.PromoCode{}})
+	reg.Describe((*adminHandler.PromoCodeHandler).UpdatePromoCode, openapi.Route{Request: adminHandler.UpdatePromoCodeRequest{}, Response: messageResponse})
+	reg.Describe((*adminHandler.PromoCodeHandler).CreatePromoCodeCampaign, openapi.Route{Request: adminHandler.CreatePromoCodeCampaignRequest{}})
+	reg.Describe((*adminHandler.PaymentMethodHandler).List, openapi.Route{Response: gin.H{"items": []models.PaymentMethod{}}})
+	reg.Describe((*adminHandler.PaymentMethodHandler).Get, openapi.Route{Response: models.PaymentMethod{}})
+	reg.Describe((*adminHandler.PaymentMethodHandler).Create, openapi.Route{Request: adminHandler.CreatePaymentMethodRequest{}, Response: models.PaymentMethod{}})
+	reg.Describe((*adminHandler.PaymentMethodHandler).Update, openapi.Route{Request: adminHandler.UpdatePaymentMethodRequest{}, Response: models.PaymentMethod{}})
+	reg.Describe((*adminHandler.PaymentMethodHandler).ToggleEnabled, openapi.Route{Response: models.PaymentMethod{}})
+	reg.Describe((*adminHandler.PaymentMethodHandler).Reorder, openapi.Route{Request: adminHandler.ReorderPaymentMethodRequest{}})
+	reg.Describe((*adminHandler.PaymentMethodHandler).TestScript, openapi.Route{Request: adminHandler.TestScriptRequest{}, Response: service.PaymentScriptTestResult{}})
+	reg.Describe((*adminHandler.PaymentMethodHandler).ListScriptLogs, openapi.Route{
+		Query:     []openapi.Param{{Name: "callback", Description: "onGeneratePaymentCard, onCheckPaymentStatus, onWebhook, onPaymentNotify or onRefund"}},
+		Response:  models.PaymentMethodScriptLog{},
+		Paginated: true,
+	})
+
+	// 管理端工单、账号与系统
+	reg.Describe((*adminHandler.TicketHandler).ListTickets, openapi.Route{
+		Query:     []openapi.Param{{Name: "status"}, {Name: "exclude_status"}, {Name: "search"}, {Name: "assigned_to", Type: "integer"}, {Name: "tag"}, {Name: "filter"}},
+		Response:  models.Ticket{},
+		Paginated: true,
+	})
+	reg.Describe((*adminHandler.TicketHandler).GetTicket, openapi.Route{Response: models.Ticket{}})
+	reg.Describe((*adminHandler.TicketHandler).GetTicketMessages, openapi.Route{Response: []models.TicketMessage{}})
+	reg.Describe((*adminHandler.TicketHandler).SendMessage, openapi.Route{Request: adminHandler.AdminSendMessageRequest{}, Response: models.TicketMessage{}})
+	reg.Describe((*adminHandler.TicketHandler).UpdateTicket, openapi.Route{Request: adminHandler.UpdateTicketRequest{}, Response: models.Ticket{}})
+	reg.Describe((*adminHandler.TicketHandler).GetSharedOrders, openapi.Route{Response: []models.TicketOrderAccess{}})
+	reg.Describe((*adminHandler.TicketHandler).UploadFile, openapi.Route{Multipart: true})
+	reg.Describe((*adminHandler.TicketAutomationHandler).ListTicketAutomationRules, openapi.Route{
+		Query:    []openapi.Param{{Name: "trigger"}},
+		Response: gin.H{"items": []models.TicketAutomationRule{}},
+	})
+	reg.Describe((*adminHandler.TicketAutomationHandler).GetTicketAutomationRule, openapi.Route{Response: models.TicketAutomationRule{}})
+	reg.Describe((*adminHandler.TicketAutomationHandler).CreateTicketAutomationRule, openapi.Route{Request: service.TicketAutomationRuleInput{}, Response: models.TicketAutomationRule{}})
+	reg.Describe((*adminHandler.TicketAutomationHandler).UpdateTicketAutomationRule, openapi.Route{Request: service.TicketAutomationRuleInput{}, Response: models.TicketAutomationRule{}})
+	reg.Describe((*adminHandler.AdminHandler).CreateAdmin, openapi.Route{Request: adminHandler.CreateAdminRequest{}})
+	reg.Describe((*adminHandler.AdminHandler).UpdateAdmin, openapi.Route{Request: adminHandler.UpdateAdminRequest{}})
+	reg.Describe((*adminHandler.ApprovalHandler).RejectApproval, openapi.Route{Request: adminHandler.ReviewApprovalRequest{}})
+	reg.Describe((*adminHandler.SecurityHandler).ReviewEvent, openapi.Route{Request: adminHandler.ReviewSecurityEventRequest{}})
+	reg.Describe((*adminHandler.FieldMaskHandler).UpdateFieldMaskPolicies, openapi.Route{Request: adminHandler.UpdateFieldMaskPoliciesRequest{}})
+	reg.Describe((*adminHandler.SettingsHandler).UpdateSettings, openapi.Route{Request: adminHandler.UpdateSettingsRequest{}})
+	reg.Describe((*adminHandler.LogHandler).RetryFailedEmails, openapi.Route{Request: adminHandler.RetryEmailRequest{}})
+	reg.Describe((*adminHandler.UploadHandler).UploadImage, openapi.Route{Multipart: true})
+	reg.Describe((*adminHandler.AdminSearchHandler).Search, openapi.Route{
+		Query:    []openapi.Param{{Name: "q", Required: true}, {Name: "types", Description: "Comma-separated subset of order,user,ticket,product,serial"}, {Name: "limit", Type: "integer"}, {Name: "store_id", Type: "integer"}},
+		Response: gin.H{"query": "", "items": []service.AdminSearchResult{}},
+	})
+	reg.Describe((*adminHandler.AdminSearchHandler).GetPalette, openapi.Route{Response: gin.H{
+		"actions":          []service.AdminQuickAction{},
+		"recent":           []service.AdminSearchResult{},
+		"assigned_tickets": []service.AdminSearchResult{},
+	}})
+	reg.Describe((*adminHandler.NotificationHandler).ListNotifications, openapi.Route{
+		Query:     []openapi.Param{{Name: "unread", Type: "boolean"}},
+		Response:  models.AdminNotification{},
+		Paginated: true,
+	})
+	reg.Describe((*adminHandler.NotificationHandler).GetUnreadNotificationCount, openapi.Route{Response: gin.H{"count": int64(0)}})
+	reg.Describe((*adminHandler.NotificationHandler).ListNotificationSubscriptions, openapi.Route{Response: gin.H{"items": []models.AdminNotificationSubscription{}}})
+	reg.Describe((*adminHandler.NotificationHandler).CreateNotificationSubscription, openapi.Route{Request: service.AdminNotificationSubscriptionInput{}, Response: models.AdminNotificationSubscription{}})
+	reg.Describe((*adminHandler.NotificationHandler).UpdateNotificationSubscription, openapi.Route{Request: service.AdminNotificationSubscriptionInput{}, Response: models.AdminNotificationSubscription{}})
+
+	return reg
+}
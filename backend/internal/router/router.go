@@ -9,11 +9,13 @@ import (
 	"auralogic/internal/pluginobs"
 	"auralogic/internal/repository"
 	"auralogic/internal/service"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -29,6 +31,9 @@ func SetupRouter(
 	db *gorm.DB,
 	paymentPollingService *service.PaymentPollingService,
 	pluginManagerService *service.PluginManagerService,
+	storeService *service.StoreService,
+	domainService *service.DomainService,
+	productPriceService *service.ProductPriceService,
 	version string,
 ) *gin.Engine {
 	// 设置Gin模式
@@ -44,6 +49,21 @@ func SetupRouter(
 	r.Use(middleware.CORS(&cfg.Security.CORS))
 	r.Use(middleware.SecurityHeaders()) // 添加安全响应头
 
+	// 多店铺：按 Host 解析当前店铺
+	r.Use(middleware.StoreContextMiddleware(storeService))
+
+	// 登录保护：IP 封禁对所有请求生效
+	loginProtectionService := service.NewLoginProtectionService(db, cfg)
+	// 订单认领：邮箱验证后关联外部平台创建的无账号订单
+	orderClaimService := service.NewOrderClaimService(db)
+	if authService != nil {
+		authService.SetLoginProtection(loginProtectionService)
+		authService.SetOrderClaimService(orderClaimService)
+	}
+	r.Use(middleware.IPBanMiddleware(loginProtectionService))
+	// 商品目录防爬：突发检测、蜜罐参数和 JS 挑战，封禁复用 IP 封禁
+	scraperProtectionService := service.NewScraperProtectionService(db, cfg, loginProtectionService)
+
 	// CreateRepository
 	inventoryRepo := repository.NewInventoryRepository(db)
 	productRepo := repository.NewProductRepository(db)
@@ -59,7 +79,15 @@ func SetupRouter(
 	serialService := service.NewSerialService(serialRepo, productRepo, orderRepo)
 	virtualInventoryService := service.NewVirtualInventoryService(db)
 	cartService := service.NewCartService(cartRepo, productRepo, bindingService, virtualInventoryService)
+	giftPromotionService := service.NewGiftPromotionService(db)
+	cartService.SetGiftPromotionService(giftPromotionService)
+	cartReservationService := service.NewCartReservationService(cfg)
+	cartService.SetReservationService(cartReservationService)
+	if orderService != nil {
+		orderService.SetCartReservationService(cartReservationService)
+	}
 	promoCodeService := service.NewPromoCodeService(promoCodeRepo, productRepo)
+	promoCodeService.SetGiftPromotionService(giftPromotionService)
 
 	// CreateService - SMS
 	smsService := service.NewSMSService(cfg, db)
@@ -67,26 +95,101 @@ func SetupRouter(
 	smsService.SetPluginManager(pluginManagerService)
 	marketingService.SetPluginManager(pluginManagerService)
 	serialService.SetPluginManager(pluginManagerService)
+	themeService := service.NewThemeService(db)
 	if emailService != nil {
 		emailService.SetPluginManager(pluginManagerService)
+		emailService.SetThemeService(themeService)
 	}
 
 	// CreateHandler
 	userAuthHandler := userHandler.NewAuthHandler(authService, emailService, smsService, pluginManagerService)
 	userOrderHandler := userHandler.NewOrderHandler(orderService, bindingService, virtualInventoryService, pluginManagerService, cfg)
-	userProductHandler := userHandler.NewProductHandler(productService, orderService, bindingService, virtualInventoryService, pluginManagerService)
+	userOrderHandler.SetTimelineService(service.NewOrderTimelineService(db, cfg))
+	orderMessageService := service.NewOrderMessageService(db, emailService)
+	userOrderHandler.SetMessageService(orderMessageService)
+	userOrderHandler.SetClaimService(orderClaimService)
+	waitingRoomService := service.NewWaitingRoomService(db, cfg)
+	userOrderHandler.SetWaitingRoomService(waitingRoomService)
+	userWaitingRoomHandler := userHandler.NewWaitingRoomHandler(waitingRoomService)
+	adminWaitingRoomHandler := adminHandler.NewWaitingRoomHandler(waitingRoomService)
+	seoService := service.NewSEOService(db, cfg)
+	userProductHandler := userHandler.NewProductHandler(productService, orderService, bindingService, virtualInventoryService, pluginManagerService, seoService)
+	userSEOHandler := userHandler.NewSEOHandler(seoService)
+	quoteRequestService := service.NewQuoteRequestService(db, cfg)
+	cartService.SetUserRepository(userRepo)
+	userProductHandler.SetQuoteRequestService(quoteRequestService)
+	userQuoteRequestHandler := userHandler.NewQuoteRequestHandler(quoteRequestService)
+	adminQuoteRequestHandler := adminHandler.NewQuoteRequestHandler(quoteRequestService)
+	returnRequestService := service.NewReturnRequestService(db, orderService)
+	userReturnRequestHandler := userHandler.NewReturnRequestHandler(returnRequestService)
+	adminReturnRequestHandler := adminHandler.NewReturnRequestHandler(returnRequestService)
+	shippingRestrictionService := service.NewShippingRestrictionService(db)
 	formShippingHandler := formHandler.NewShippingHandler(orderService, cfg)
+	formShippingHandler.SetShippingRestrictionService(shippingRestrictionService)
 	jsRuntimeService := service.NewJSRuntimeService(db, cfg)
 	adminOrderHandler := adminHandler.NewOrderHandler(orderService, serialService, virtualInventoryService, jsRuntimeService, pluginManagerService, cfg)
+	adminOrderHandler.SetShippingRestrictionService(shippingRestrictionService)
+	adminOrderHandler.SetCustomsService(service.NewCustomsDeclarationService(db, cfg))
+	adminOrderHandler.SetPackagePlanService(service.NewPackagePlanService(db, cfg))
+	orderImportService := service.NewOrderImportService(db, orderService)
+	if orderService != nil {
+		go orderImportService.ResumePendingJobs()
+	}
+	adminOrderImportHandler := adminHandler.NewOrderImportHandler(db, orderImportService)
+	adminPaymentMatchHandler := adminHandler.NewPaymentMatchHandler(db, service.NewPaymentMatchService(db, orderService))
+	adminOrderCodeLookupHandler := adminHandler.NewOrderCodeLookupHandler(db, service.NewOrderCodeLookupService(db))
+	adminCODHandler := adminHandler.NewCODHandler(db, service.NewCODService(db))
+	adminShippingRestrictionHandler := adminHandler.NewShippingRestrictionHandler(shippingRestrictionService)
+	shortLinkService := service.NewShortLinkService(db, cfg)
+	adminOrderHandler.SetShortLinkService(shortLinkService)
+	adminOrderHandler.SetLedgerService(service.NewLedgerService(db))
+	adminOrderHandler.SetRefundService(service.NewOrderRefundService(db, orderService, jsRuntimeService))
+	orderSubStatusService := service.NewOrderSubStatusService(db, emailService)
+	adminOrderHandler.SetSubStatusService(orderSubStatusService)
+	adminOrderSubStatusHandler := adminHandler.NewOrderSubStatusHandler(orderSubStatusService)
+	adminOrderHandler.SetNoteService(service.NewOrderNoteService(db, emailService))
+	adminOrderHandler.SetMessageService(orderMessageService)
+	orderAutomationService := service.NewOrderAutomationService(db, orderSubStatusService)
+	if pluginManagerService != nil {
+		pluginManagerService.AddHookObserver(orderAutomationService)
+	}
+	adminOrderAutomationHandler := adminHandler.NewOrderAutomationHandler(orderAutomationService)
+	ticketAutomationService := service.NewTicketAutomationService(db)
+	if pluginManagerService != nil {
+		pluginManagerService.AddHookObserver(ticketAutomationService)
+	}
+	adminTicketAutomationHandler := adminHandler.NewTicketAutomationHandler(ticketAutomationService)
+	webhookService := service.NewWebhookService(db)
+	if pluginManagerService != nil {
+		pluginManagerService.AddHookObserver(webhookService)
+	}
+	adminWebhookHandler := adminHandler.NewWebhookHandler(webhookService)
+	shipmentTrackingService := service.NewShipmentTrackingService(db, cfg, orderService)
+	if pluginManagerService != nil {
+		pluginManagerService.AddHookObserver(shipmentTrackingService)
+	}
+	adminOrderHandler.SetShipmentTrackingService(shipmentTrackingService)
+	userOrderHandler.SetShipmentTrackingService(shipmentTrackingService)
+	userShipmentTrackingHandler := userHandler.NewShipmentTrackingHandler(shipmentTrackingService)
+	adminNotificationService := service.NewAdminNotificationService(db, emailService, cfg.App.URL)
+	if pluginManagerService != nil {
+		pluginManagerService.AddHookObserver(adminNotificationService)
+	}
+	adminNotificationHandler := adminHandler.NewNotificationHandler(adminNotificationService)
+	adminSavedViewHandler := adminHandler.NewSavedViewHandler(service.NewAdminSavedViewService(db))
+	adminFieldMaskHandler := adminHandler.NewFieldMaskHandler(service.NewFieldMaskService(db))
+	userShortLinkHandler := userHandler.NewShortLinkHandler(shortLinkService, orderService)
 	adminProductHandler := adminHandler.NewProductHandler(productService, virtualInventoryService, pluginManagerService)
+	adminProductHandler.SetPriceService(productPriceService)
 	adminUserHandler := adminHandler.NewUserHandler(userRepo, db, cfg, pluginManagerService)
+	adminUserHandler.SetEmailService(emailService)
 	adminPermissionHandler := adminHandler.NewPermissionHandler(db, pluginManagerService)
 	adminAPIKeyHandler := adminHandler.NewAPIKeyHandler(db, pluginManagerService)
 	adminAdminHandler := adminHandler.NewAdminHandler(userRepo, db, cfg)
 	adminLogHandler := adminHandler.NewLogHandler(db, pluginManagerService)
 	adminDashboardHandler := adminHandler.NewDashboardHandler(db, cfg, version)
 	adminAnalyticsHandler := adminHandler.NewAnalyticsHandler(db, cfg)
-	adminSettingsHandler := adminHandler.NewSettingsHandler(db, cfg, smsService, emailService, pluginManagerService)
+	adminSettingsHandler := adminHandler.NewSettingsHandler(db, cfg, smsService, emailService, themeService, pluginManagerService)
 	adminUploadHandler := adminHandler.NewUploadHandler(cfg.Upload.Dir, cfg.App.URL, pluginManagerService)
 	adminInventoryHandler := adminHandler.NewInventoryHandler(inventoryService, db, pluginManagerService)
 	adminBindingHandler := adminHandler.NewBindingHandler(bindingService, db, pluginManagerService)
@@ -95,12 +198,21 @@ func SetupRouter(
 	userSerialHandler := userHandler.NewSerialHandler(serialService)
 	userCartHandler := userHandler.NewCartHandler(cartService, pluginManagerService)
 	adminVirtualInventoryHandler := adminHandler.NewVirtualInventoryHandler(virtualInventoryService, db, pluginManagerService)
+	adminVirtualInventoryHandler.SetEmailService(emailService)
 	adminPaymentMethodHandler := adminHandler.NewPaymentMethodHandler(db, cfg, pluginManagerService)
+	adminPaymentMethodHandler.SetPaymentPollingService(paymentPollingService)
 	userPaymentMethodHandler := userHandler.NewPaymentMethodHandler(db, paymentPollingService, pluginManagerService, cfg)
 	userTicketHandler := userHandler.NewTicketHandler(db, emailService, pluginManagerService)
 	adminTicketHandler := adminHandler.NewTicketHandler(db, emailService, pluginManagerService)
 	adminPromoCodeHandler := adminHandler.NewPromoCodeHandler(promoCodeService, pluginManagerService, db)
 	userPromoCodeHandler := userHandler.NewPromoCodeHandler(promoCodeService, pluginManagerService)
+	userPromoCodeHandler.SetCartService(cartService)
+	adminGiftPromotionHandler := adminHandler.NewGiftPromotionHandler(giftPromotionService)
+	adminActivityHandler := adminHandler.NewAdminActivityHandler(service.NewAdminActivityService(db))
+	adminSearchHandler := adminHandler.NewAdminSearchHandler(service.NewAdminSearchService(db))
+	userCatalogChallengeHandler := userHandler.NewCatalogChallengeHandler(scraperProtectionService)
+	adminSecurityHandler := adminHandler.NewSecurityHandler(loginProtectionService, service.NewCSPReportService(db), scraperProtectionService)
+	adminApprovalHandler := adminHandler.NewApprovalHandler(service.NewAdminApprovalService(db))
 	adminKnowledgeHandler := adminHandler.NewKnowledgeHandler(db, pluginManagerService)
 	adminAnnouncementHandler := adminHandler.NewAnnouncementHandler(db, emailService, smsService, pluginManagerService)
 	adminMarketingHandler := adminHandler.NewMarketingHandler(db, marketingService, pluginManagerService)
@@ -108,6 +220,10 @@ func SetupRouter(
 	userKnowledgeHandler := userHandler.NewKnowledgeHandler(db, pluginManagerService)
 	userAnnouncementHandler := userHandler.NewAnnouncementHandler(db, pluginManagerService)
 	adminPluginHandler := adminHandler.NewPluginHandler(db, pluginManagerService, cfg.Plugin.ArtifactDir)
+	adminStoreHandler := adminHandler.NewStoreHandler(storeService)
+	adminDomainHandler := adminHandler.NewDomainHandler(domainService, cfg)
+	adminExchangeRateHandler := adminHandler.NewExchangeRateHandler(service.GlobalExchangeRateService())
+	adminThemeHandler := adminHandler.NewThemeHandler(themeService)
 
 	// ========== 表单API（支持匿名 token 访问，登录态会附带所有权校验） ==========
 	form := r.Group("/api/form")
@@ -116,6 +232,7 @@ func SetupRouter(
 		form.GET("/shipping", formShippingHandler.GetForm)
 		form.POST("/shipping", formShippingHandler.SubmitForm)
 		form.GET("/countries", formShippingHandler.GetCountries) // get国家列表
+		form.GET("/countries/:code/regions", formShippingHandler.GetRegions)
 	}
 
 	// ========== 序列号查询API（公开，无需登录） ==========
@@ -166,6 +283,9 @@ func SetupRouter(
 		configAPI.POST("/plugins/:id/execute", append(publicPluginMiddlewares, adminPluginHandler.ExecutePublicPlugin)...)
 		configAPI.POST("/plugins/:id/execute/stream", append(publicPluginMiddlewares, adminPluginHandler.ExecutePublicPluginStream)...)
 	}
+	// ========== CSP 违规上报（浏览器发送，公开） ==========
+	r.POST(middleware.CSPReportPath, middleware.RateLimitMiddleware(60, time.Minute), adminSecurityHandler.ReportCSPViolation)
+
 	pluginPublicAPI := r.Group("/api/plugins")
 	{
 		pluginPublicAPI.Any("/:name/webhooks/:hook", append(publicPluginMiddlewares, adminPluginHandler.HandlePluginWebhook)...)
@@ -179,6 +299,14 @@ func SetupRouter(
 	{
 		paymentPublicAPI.Any("/:id/webhooks/:hook", append(paymentWebhookMiddlewares, userPaymentMethodHandler.HandleWebhook)...)
 	}
+	// ========== 物流轨迹服务商推送（公开，依靠签名校验） ==========
+	r.POST("/api/tracking/webhook/:provider", middleware.RateLimitMiddleware(600, time.Minute), userShipmentTrackingHandler.HandleWebhook)
+
+	paymentCallbackAPI := r.Group("/api/payments")
+	{
+		paymentCallbackAPI.Any("/callback/:payment_method_id", append(paymentWebhookMiddlewares, userPaymentMethodHandler.HandlePaymentNotify)...)
+		paymentCallbackAPI.POST("/stripe/webhook/:payment_method_id", append(paymentWebhookMiddlewares, userPaymentMethodHandler.HandleStripeWebhook)...)
+	}
 
 	// ========== User端API ==========
 	userAPI := r.Group("/api/user")
@@ -201,6 +329,7 @@ func SetupRouter(
 			auth.POST("/login-with-code", userAuthHandler.LoginWithCode)
 			auth.POST("/forgot-password", userAuthHandler.ForgotPassword)
 			auth.POST("/reset-password", userAuthHandler.ResetPassword)
+			auth.POST("/revert-email-change", userAuthHandler.RevertEmailChange)
 			auth.POST("/send-phone-code", userAuthHandler.SendPhoneLoginCode)
 			auth.POST("/login-with-phone-code", userAuthHandler.LoginWithPhoneCode)
 			auth.POST("/send-phone-register-code", userAuthHandler.SendPhoneRegisterCode)
@@ -208,6 +337,7 @@ func SetupRouter(
 			auth.POST("/phone-forgot-password", userAuthHandler.PhoneForgotPassword)
 			auth.POST("/phone-reset-password", userAuthHandler.PhoneResetPassword)
 			auth.POST("/logout", middleware.AuthMiddleware(), userAuthHandler.Logout)
+			auth.GET("/csrf-token", userAuthHandler.GetCSRFToken)
 			auth.GET("/me", middleware.AuthMiddleware(), userAuthHandler.GetMe)
 			auth.POST("/change-password", middleware.AuthMiddleware(), userAuthHandler.ChangePassword)
 			auth.PUT("/preferences", middleware.AuthMiddleware(), userAuthHandler.UpdatePreferences)
@@ -221,30 +351,48 @@ func SetupRouter(
 		orders := userAPI.Group("/orders")
 		orders.Use(middleware.AuthMiddleware())
 		{
-			orders.POST("", middleware.DynamicRateLimitMiddleware(resolveRateLimit(func(runtimeCfg *config.Config) int {
+			orders.POST("", middleware.RequireEmailVerifiedForCheckout(), middleware.DynamicRateLimitMiddleware(resolveRateLimit(func(runtimeCfg *config.Config) int {
 				return runtimeCfg.RateLimit.OrderCreate
-			}, 30), time.Minute), userOrderHandler.CreateOrder)
+			}, 30), time.Minute), middleware.ConcurrencyLimitMiddleware("order_create", resolveOrderCreateConcurrencyLimit), userOrderHandler.CreateOrder)
 			orders.GET("", userOrderHandler.ListOrders)
+			orders.GET("/export", middleware.RateLimitMiddleware(5, time.Minute), userOrderHandler.ExportOrders)
+			orders.POST("/claim", middleware.RateLimitMiddleware(10, time.Minute), userOrderHandler.ClaimOrder)
 			orders.GET("/:order_no", userOrderHandler.GetOrder)
 			orders.GET("/:order_no/form-token", userOrderHandler.GetOrRefreshFormToken)
+			orders.GET("/:order_no/timeline", userOrderHandler.GetOrderTimeline)
+			orders.GET("/:order_no/messages", userOrderHandler.GetOrderMessages)
+			orders.POST("/:order_no/messages", middleware.RateLimitMiddleware(20, time.Minute), userOrderHandler.SendOrderMessage)
+			orders.POST("/:order_no/messages/escalate", userOrderHandler.EscalateOrderMessages)
+			orders.GET("/:order_no/short-link", userShortLinkHandler.GetOrderShortLink)
 			orders.GET("/:order_no/virtual-products", userOrderHandler.GetVirtualProducts)
+			orders.POST("/:order_no/virtual-products/:stock_id/reveal", middleware.RateLimitMiddleware(30, time.Minute), userOrderHandler.RevealVirtualProduct)
+			orders.POST("/:order_no/virtual-products/resend-email", middleware.RateLimitMiddleware(5, time.Minute), userOrderHandler.ResendVirtualProductsEmail)
 			orders.POST("/:order_no/complete", userOrderHandler.CompleteOrder)
 			orders.GET("/:order_no/invoice", userOrderHandler.DownloadInvoice)
 			orders.GET("/:order_no/invoice-token", userOrderHandler.GetInvoiceToken)
+			orders.GET("/:order_no/returns", userReturnRequestHandler.ListReturnRequests)
+			orders.POST("/:order_no/returns", middleware.RateLimitMiddleware(10, time.Minute), userReturnRequestHandler.CreateReturnRequest)
+			orders.POST("/:order_no/returns/photos", middleware.RateLimitMiddleware(20, time.Minute), userReturnRequestHandler.UploadReturnPhoto)
+			orders.POST("/:order_no/returns/:id/cancel", userReturnRequestHandler.CancelReturnRequest)
 		}
 
 		// 账单公开访问（通过一次性令牌认证）
 		userAPI.GET("/invoice/:token", userOrderHandler.ViewInvoiceByToken)
 
+		// 邮件签名链接提取卡密（签名 + 过期时间认证）
+		userAPI.GET("/virtual-delivery/:order_no", middleware.RateLimitMiddleware(30, time.Minute), userOrderHandler.GetVirtualDeliveryBySignedLink)
+
 		// Product（推荐商品公开访问；列表/详情按配置动态控制是否需要登录）
+		scraperGuard := middleware.ScraperProtectionMiddleware(scraperProtectionService)
 		productsPublic := userAPI.Group("/products")
 		{
-			productsPublic.GET("/featured", userProductHandler.GetFeaturedProducts)
-			productsPublic.GET("/recommended", userProductHandler.GetRecommendedProducts)
+			productsPublic.GET("/challenge", middleware.RateLimitMiddleware(30, time.Minute), userCatalogChallengeHandler.GetChallenge)
+			productsPublic.GET("/featured", scraperGuard, userProductHandler.GetFeaturedProducts)
+			productsPublic.GET("/recommended", scraperGuard, userProductHandler.GetRecommendedProducts)
 		}
 
 		products := userAPI.Group("/products")
-		products.Use(middleware.ProductBrowseAuthMiddleware(cfg))
+		products.Use(middleware.ProductBrowseAuthMiddleware(cfg), scraperGuard)
 		{
 			products.GET("", userProductHandler.ListProducts)
 			products.GET("/categories", userProductHandler.GetCategories)
@@ -252,6 +400,18 @@ func SetupRouter(
 			products.GET("/:id/available-stock", userProductHandler.GetProductAvailableStock)
 		}
 
+		// 询价单
+		quoteRequests := userAPI.Group("/quote-requests")
+		quoteRequests.Use(middleware.AuthMiddleware())
+		{
+			quoteRequests.GET("", userQuoteRequestHandler.ListQuoteRequests)
+			quoteRequests.POST("", middleware.RateLimitMiddleware(10, time.Minute), userQuoteRequestHandler.CreateQuoteRequest)
+			quoteRequests.GET("/:id", userQuoteRequestHandler.GetQuoteRequest)
+			quoteRequests.POST("/:id/accept", userQuoteRequestHandler.AcceptQuote)
+			quoteRequests.POST("/:id/decline", userQuoteRequestHandler.DeclineQuote)
+			quoteRequests.POST("/:id/cancel", userQuoteRequestHandler.CancelQuoteRequest)
+		}
+
 		// 购物车
 		cart := userAPI.Group("/cart")
 		cart.Use(middleware.AuthMiddleware())
@@ -264,11 +424,20 @@ func SetupRouter(
 			cart.DELETE("", userCartHandler.ClearCart)
 		}
 
+		// 抢购等候室
+		waitingRoom := userAPI.Group("/waiting-room")
+		waitingRoom.Use(middleware.AuthMiddleware())
+		{
+			waitingRoom.POST("/join", middleware.RateLimitMiddleware(30, time.Minute), userWaitingRoomHandler.Join)
+			waitingRoom.GET("/status", userWaitingRoomHandler.GetStatus)
+		}
+
 		// 优惠码验证
 		promoCodes := userAPI.Group("/promo-codes")
 		promoCodes.Use(middleware.AuthMiddleware())
 		{
 			promoCodes.POST("/validate", userPromoCodeHandler.ValidatePromoCode)
+			promoCodes.POST("/suggestions", userPromoCodeHandler.SuggestPromoCodes)
 		}
 
 		// 付款方式（需要登录）
@@ -356,6 +525,13 @@ func SetupRouter(
 			analytics.GET("/pageviews", adminAnalyticsHandler.GetPageViewAnalytics)
 		}
 
+		// 抢购等候室指标
+		adminWaitingRoom := adminAPI.Group("/waiting-room")
+		adminWaitingRoom.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			adminWaitingRoom.GET("/metrics", middleware.RequirePermission("order.view"), adminWaitingRoomHandler.GetMetrics)
+		}
+
 		// Order管理（needAdminPermission）
 		orders := adminAPI.Group("/orders")
 		orders.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
@@ -363,6 +539,10 @@ func SetupRouter(
 			orders.GET("", middleware.RequirePermission("order.view"), adminOrderHandler.ListOrders)
 			orders.GET("/countries", middleware.RequirePermission("order.view"), adminOrderHandler.GetOrderCountries)
 			orders.GET("/:id", middleware.RequirePermission("order.view"), adminOrderHandler.GetOrder)
+			orders.GET("/:id/customs-declaration", middleware.RequirePermission("order.view"), adminOrderHandler.GetCustomsDeclaration)
+			orders.GET("/:id/package-plan", middleware.RequirePermission("order.view"), adminOrderHandler.GetPackagePlan)
+			orders.GET("/:id/tracking", middleware.RequirePermission("order.view"), adminOrderHandler.GetOrderTracking)
+			orders.POST("/:id/tracking/sync", middleware.RequirePermission("order.assign_tracking"), adminOrderHandler.SyncOrderTracking)
 			orders.POST("/draft", middleware.RequirePermission("order.edit"), adminOrderHandler.CreateDraft)
 			orders.POST("", middleware.RequirePermission("order.edit"), adminOrderHandler.CreateOrderForUser)
 			orders.POST("/:id/assign-shipping", middleware.RequirePermission("order.assign_tracking"), adminOrderHandler.AssignTracking)
@@ -372,9 +552,33 @@ func SetupRouter(
 			orders.POST("/:id/cancel", middleware.RequirePermission("order.status_update"), adminOrderHandler.CancelOrder)
 			orders.POST("/:id/refund", middleware.RequirePermission("order.refund"), adminOrderHandler.RefundOrder)
 			orders.POST("/:id/confirm-refund", middleware.RequirePermission("order.refund"), adminOrderHandler.ConfirmRefund)
+			orders.GET("/:id/refunds", middleware.RequirePermission("order.view"), adminOrderHandler.ListOrderRefunds)
+			orders.POST("/:id/refunds", middleware.RequirePermission("order.refund"), adminOrderHandler.CreateOrderRefund)
+			orders.POST("/:id/refunds/:refundId/confirm", middleware.RequirePermission("order.refund"), adminOrderHandler.ConfirmOrderRefund)
+			orders.POST("/:id/returns", middleware.RequirePermission("order.refund"), adminOrderHandler.ReceiveOrderReturn)
+			orders.GET("/:id/merge-candidates", middleware.RequirePermission("order.edit"), adminOrderHandler.ListOrderMergeCandidates)
+			orders.POST("/:id/merge", middleware.RequirePermission("order.edit"), adminOrderHandler.MergeOrders)
 			orders.POST("/:id/mark-paid", middleware.RequirePermission("order.status_update"), adminOrderHandler.MarkAsPaid)
+			orders.POST("/:id/simulate-payment", middleware.RequirePermission("order.status_update"), adminPaymentMethodHandler.SimulatePayment)
 			orders.POST("/:id/deliver-virtual", middleware.RequirePermission("order.status_update"), adminOrderHandler.DeliverVirtualStock)
+			orders.POST("/:id/virtual-stocks/:stock_id/redeliver", middleware.RequirePermission("order.status_update"), adminVirtualInventoryHandler.RedeliverOrderStock)
 			orders.PUT("/:id/price", middleware.RequirePermission("order.edit"), adminOrderHandler.UpdateOrderPrice)
+			orders.PUT("/:id/sub-status", middleware.RequirePermission("order.status_update"), adminOrderHandler.UpdateOrderSubStatus)
+			orders.PUT("/:id/tags", middleware.RequirePermission("order.edit"), adminOrderHandler.UpdateOrderTags)
+			orders.GET("/notes/mentionable-admins", middleware.RequirePermission("order.edit"), adminOrderHandler.ListOrderNoteMentionableAdmins)
+			orders.GET("/:id/notes", middleware.RequirePermission("order.view"), adminOrderHandler.ListOrderNotes)
+			orders.POST("/:id/notes", middleware.RequirePermission("order.edit"), adminOrderHandler.CreateOrderNote)
+			orders.PUT("/:id/notes/:noteId", middleware.RequirePermission("order.edit"), adminOrderHandler.UpdateOrderNote)
+			orders.DELETE("/:id/notes/:noteId", middleware.RequirePermission("order.edit"), adminOrderHandler.DeleteOrderNote)
+			orders.POST("/:id/notes/:noteId/pin", middleware.RequirePermission("order.edit"), adminOrderHandler.PinOrderNote)
+			orders.GET("/:id/messages", middleware.RequirePermission("order.view"), adminOrderHandler.GetOrderMessages)
+			orders.POST("/:id/messages", middleware.RequirePermission("order.edit"), adminOrderHandler.ReplyOrderMessage)
+			orders.POST("/:id/messages/escalate", middleware.RequirePermission("order.edit"), adminOrderHandler.EscalateOrderMessages)
+			orders.GET("/:id/short-links", middleware.RequirePermission("order.view"), adminOrderHandler.ListOrderShortLinks)
+			orders.POST("/:id/short-links", middleware.RequirePermission("order.edit"), adminOrderHandler.CreateOrderShortLink)
+			orders.GET("/:id/financial-summary", middleware.RequirePermission("order.view"), adminOrderHandler.GetOrderFinancialSummary)
+			orders.POST("/:id/credits", middleware.RequirePermission("order.refund"), adminOrderHandler.CreateOrderCredit)
+			orders.GET("/ledger/verify", middleware.RequirePermission("order.view"), adminOrderHandler.VerifyLedger)
 			orders.DELETE("/:id", middleware.RequirePermission("order.delete"), adminOrderHandler.DeleteOrder)
 
 			// 批量操作
@@ -383,8 +587,108 @@ func SetupRouter(
 
 			// Excel导出导入
 			orders.GET("/export", middleware.RequirePermission("order.view"), adminOrderHandler.ExportOrders)
+			orders.GET("/customs-declarations/export", middleware.RequirePermission("order.view"), adminOrderHandler.ExportCustomsDeclarations)
 			orders.POST("/import", middleware.RequirePermission("order.assign_tracking"), adminOrderHandler.ImportOrders)
 			orders.GET("/import-template", middleware.RequirePermission("order.view"), adminOrderHandler.DownloadTemplate)
+
+			// 批量导入订单（平台迁移，后台任务执行）
+			orders.POST("/bulk-import", middleware.RequirePermission("order.edit"), adminOrderImportHandler.CreateImportJob)
+			orders.GET("/bulk-import/jobs", middleware.RequirePermission("order.view"), adminOrderImportHandler.ListImportJobs)
+			orders.GET("/bulk-import/jobs/:jobId", middleware.RequirePermission("order.view"), adminOrderImportHandler.GetImportJob)
+			orders.GET("/bulk-import/template", middleware.RequirePermission("order.edit"), adminOrderImportHandler.DownloadImportTemplate)
+
+			// 客服按卡密/序列号反查订单
+			orders.POST("/code-lookup", middleware.RequirePermission("order.view"), middleware.RateLimitMiddleware(30, time.Minute), adminOrderCodeLookupHandler.LookupByCode)
+		}
+
+		// 人工对账（到账流水匹配待付款订单）
+		paymentMatching := adminAPI.Group("/payment-matching")
+		paymentMatching.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			paymentMatching.GET("/transactions", middleware.RequirePermission("order.view"), adminPaymentMatchHandler.ListTransactions)
+			paymentMatching.POST("/transactions", middleware.RequirePermission("order.status_update"), adminPaymentMatchHandler.CreateTransaction)
+			paymentMatching.POST("/transactions/import", middleware.RequirePermission("order.status_update"), adminPaymentMatchHandler.ImportTransactions)
+			paymentMatching.GET("/transactions/import-template", middleware.RequirePermission("order.view"), adminPaymentMatchHandler.DownloadImportTemplate)
+			paymentMatching.GET("/transactions/:id/suggestions", middleware.RequirePermission("order.view"), adminPaymentMatchHandler.GetSuggestions)
+			paymentMatching.POST("/transactions/:id/match", middleware.RequirePermission("order.status_update"), adminPaymentMatchHandler.MatchTransaction)
+			paymentMatching.POST("/transactions/:id/ignore", middleware.RequirePermission("order.status_update"), adminPaymentMatchHandler.IgnoreTransaction)
+			paymentMatching.GET("/pending-orders", middleware.RequirePermission("order.view"), adminPaymentMatchHandler.ListPendingOrders)
+		}
+
+		// 货到付款代收对账
+		cod := adminAPI.Group("/cod")
+		cod.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			cod.GET("/collections", middleware.RequirePermission("order.view"), adminCODHandler.ListCollections)
+			cod.POST("/collections/settle", middleware.RequirePermission("order.status_update"), adminCODHandler.SettleCollections)
+			cod.POST("/orders/:id/collect", middleware.RequirePermission("order.status_update"), adminCODHandler.RecordCollection)
+			cod.GET("/report", middleware.RequirePermission("order.view"), adminCODHandler.Report)
+		}
+
+		// 订单子状态定义
+		orderSubStatuses := adminAPI.Group("/order-sub-statuses")
+		orderSubStatuses.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			orderSubStatuses.GET("", middleware.RequirePermission("order.view"), adminOrderSubStatusHandler.ListOrderSubStatuses)
+			orderSubStatuses.POST("", middleware.RequirePermission("order.edit"), adminOrderSubStatusHandler.CreateOrderSubStatus)
+			orderSubStatuses.PUT("/:id", middleware.RequirePermission("order.edit"), adminOrderSubStatusHandler.UpdateOrderSubStatusDefinition)
+			orderSubStatuses.DELETE("/:id", middleware.RequirePermission("order.delete"), adminOrderSubStatusHandler.DeleteOrderSubStatus)
+		}
+
+		// 订单自动化规则
+		automation := adminAPI.Group("/order-automation")
+		automation.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			automation.GET("/meta", middleware.RequirePermission("order.automation"), adminOrderAutomationHandler.GetAutomationMeta)
+			automation.GET("/rules", middleware.RequirePermission("order.automation"), adminOrderAutomationHandler.ListAutomationRules)
+			automation.POST("/rules", middleware.RequirePermission("order.automation"), adminOrderAutomationHandler.CreateAutomationRule)
+			automation.GET("/rules/:id", middleware.RequirePermission("order.automation"), adminOrderAutomationHandler.GetAutomationRule)
+			automation.PUT("/rules/:id", middleware.RequirePermission("order.automation"), adminOrderAutomationHandler.UpdateAutomationRule)
+			automation.DELETE("/rules/:id", middleware.RequirePermission("order.automation"), adminOrderAutomationHandler.DeleteAutomationRule)
+			automation.POST("/dry-run", middleware.RequirePermission("order.automation"), adminOrderAutomationHandler.DryRunAutomationRule)
+			automation.GET("/runs", middleware.RequirePermission("order.automation"), adminOrderAutomationHandler.ListAutomationRuns)
+		}
+
+		// 出站 Webhook（对接 ERP 等外部系统）
+		webhooks := adminAPI.Group("/webhooks")
+		webhooks.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			webhooks.GET("/meta", middleware.RequirePermission("api.manage"), adminWebhookHandler.GetWebhookMeta)
+			webhooks.GET("", middleware.RequirePermission("api.manage"), adminWebhookHandler.ListWebhookEndpoints)
+			webhooks.POST("", middleware.RequirePermission("api.manage"), adminWebhookHandler.CreateWebhookEndpoint)
+			webhooks.PUT("/:id", middleware.RequirePermission("api.manage"), adminWebhookHandler.UpdateWebhookEndpoint)
+			webhooks.DELETE("/:id", middleware.RequirePermission("api.manage"), adminWebhookHandler.DeleteWebhookEndpoint)
+			webhooks.POST("/:id/rotate-secret", middleware.RequirePermission("api.manage"), adminWebhookHandler.RotateWebhookSecret)
+			webhooks.POST("/:id/test", middleware.RequirePermission("api.manage"), adminWebhookHandler.TestWebhookEndpoint)
+			webhooks.GET("/deliveries", middleware.RequirePermission("api.manage"), adminWebhookHandler.ListWebhookDeliveries)
+			webhooks.POST("/deliveries/:deliveryId/redeliver", middleware.RequirePermission("api.manage"), adminWebhookHandler.RedeliverWebhook)
+		}
+
+		// 管理员个人通知订阅与站内通知（按管理员隔离，订阅时校验事件对应的查看权限）
+		notifications := adminAPI.Group("/notifications")
+		notifications.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			notifications.GET("", adminNotificationHandler.ListNotifications)
+			notifications.GET("/unread-count", adminNotificationHandler.GetUnreadNotificationCount)
+			notifications.POST("/read-all", adminNotificationHandler.MarkAllNotificationsRead)
+			notifications.POST("/:id/read", adminNotificationHandler.MarkNotificationRead)
+			notifications.GET("/meta", adminNotificationHandler.GetNotificationMeta)
+			notifications.GET("/subscriptions", adminNotificationHandler.ListNotificationSubscriptions)
+			notifications.POST("/subscriptions", adminNotificationHandler.CreateNotificationSubscription)
+			notifications.PUT("/subscriptions/:id", adminNotificationHandler.UpdateNotificationSubscription)
+			notifications.DELETE("/subscriptions/:id", adminNotificationHandler.DeleteNotificationSubscription)
+		}
+
+		// 管理员列表视图与高级筛选（视图按管理员隔离）
+		savedViews := adminAPI.Group("/saved-views")
+		savedViews.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			savedViews.GET("/fields", adminSavedViewHandler.GetFilterFields)
+			savedViews.GET("", adminSavedViewHandler.ListSavedViews)
+			savedViews.POST("", adminSavedViewHandler.CreateSavedView)
+			savedViews.PUT("/:id", adminSavedViewHandler.UpdateSavedView)
+			savedViews.POST("/:id/default", adminSavedViewHandler.SetDefaultSavedView)
+			savedViews.DELETE("/:id", adminSavedViewHandler.DeleteSavedView)
 		}
 
 		// User管理
@@ -411,6 +715,8 @@ func SetupRouter(
 			products.POST("/import", middleware.RequirePermission("product.edit"), adminProductHandler.ImportProducts)
 			products.POST("", middleware.RequirePermission("product.edit"), adminProductHandler.CreateProduct)
 			products.GET("/categories", middleware.RequirePermission("product.view"), adminProductHandler.GetCategories)
+			products.GET("/shipping-blocks", middleware.RequirePermission("product.view"), adminShippingRestrictionHandler.ListBlocks)
+			products.DELETE("/shipping-blocks", middleware.RequirePermission("product.edit"), adminShippingRestrictionHandler.ClearBlocks)
 			products.GET("/:id", middleware.RequirePermission("product.view"), adminProductHandler.GetProduct)
 			products.PUT("/:id", middleware.RequirePermission("product.edit"), adminProductHandler.UpdateProduct)
 			products.DELETE("/:id", middleware.RequirePermission("product.delete"), adminProductHandler.DeleteProduct)
@@ -418,6 +724,10 @@ func SetupRouter(
 			products.PUT("/:id/stock", middleware.RequirePermission("product.edit"), adminProductHandler.UpdateStock)
 			products.POST("/:id/toggle-featured", middleware.RequirePermission("product.edit"), adminProductHandler.ToggleFeatured)
 			products.PUT("/:id/inventory-mode", middleware.RequirePermission("product.edit"), adminProductHandler.UpdateInventoryMode)
+			products.GET("/:id/price-history", middleware.RequirePermission("product.view"), adminProductHandler.ListPriceHistory)
+			products.GET("/:id/price-schedules", middleware.RequirePermission("product.view"), adminProductHandler.ListPriceSchedules)
+			products.POST("/:id/price-schedules", middleware.RequirePermission("product.edit"), adminProductHandler.CreatePriceSchedule)
+			products.DELETE("/:id/price-schedules/:scheduleId", middleware.RequirePermission("product.edit"), adminProductHandler.CancelPriceSchedule)
 
 			// Product-Inventory绑定管理
 			products.GET("/:id/inventory-bindings", middleware.RequirePermission("product.view"), adminBindingHandler.GetProductBindings)
@@ -452,6 +762,8 @@ func SetupRouter(
 			permissions.GET("/all", adminPermissionHandler.ListAllPermissions)
 			permissions.GET("/users/:id", adminPermissionHandler.GetUserPermissions)
 			permissions.PUT("/users/:id", middleware.RequirePermission("admin.permission"), adminPermissionHandler.UpdateUserPermissions)
+			permissions.GET("/field-masks", adminFieldMaskHandler.ListFieldMaskPolicies)
+			permissions.PUT("/field-masks/:role", middleware.RequirePermission("admin.permission"), adminFieldMaskHandler.UpdateFieldMaskPolicies)
 		}
 
 		// API密钥管理
@@ -460,6 +772,7 @@ func SetupRouter(
 		{
 			apiKeys.GET("", middleware.RequirePermission("api.manage"), adminAPIKeyHandler.ListAPIKeys)
 			apiKeys.POST("", middleware.RequirePermission("api.manage"), adminAPIKeyHandler.CreateAPIKey)
+			apiKeys.GET("/:id/usage", middleware.RequirePermission("api.manage"), adminAPIKeyHandler.GetAPIKeyUsage)
 			apiKeys.PUT("/:id", middleware.RequirePermission("api.manage"), adminAPIKeyHandler.UpdateAPIKey)
 			apiKeys.DELETE("/:id", middleware.RequirePermission("api.manage"), adminAPIKeyHandler.DeleteAPIKey)
 		}
@@ -475,6 +788,50 @@ func SetupRouter(
 			admins.DELETE("/:id", middleware.RequirePermission("admin.delete"), adminAdminHandler.DeleteAdmin)
 		}
 
+		// 全局搜索与命令面板：按权限过滤结果类型
+		search := adminAPI.Group("/search")
+		search.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			search.GET("", middleware.RateLimitMiddleware(60, time.Minute), adminSearchHandler.Search)
+			search.GET("/palette", adminSearchHandler.GetPalette)
+		}
+
+		// 管理员工作量统计
+		adminActivity := adminAPI.Group("/admin-activity")
+		adminActivity.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			adminActivity.GET("/me", adminActivityHandler.GetMyActivity)
+			adminActivity.GET("/leaderboard", middleware.RequirePermission("admin.activity"), adminActivityHandler.GetLeaderboard)
+			adminActivity.GET("/:id", middleware.RequirePermission("admin.activity"), adminActivityHandler.GetAdminActivity)
+		}
+
+		// 登录安全：安全事件、IP 封禁、账户锁定
+		security := adminAPI.Group("/security")
+		security.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			security.GET("/events", middleware.RequirePermission("security.view"), adminSecurityHandler.ListEvents)
+			security.POST("/events/:id/review", middleware.RequirePermission("security.manage"), adminSecurityHandler.ReviewEvent)
+			security.GET("/ip-bans", middleware.RequirePermission("security.view"), adminSecurityHandler.ListIPBans)
+			security.POST("/ip-bans", middleware.RequirePermission("security.manage"), adminSecurityHandler.CreateIPBan)
+			security.DELETE("/ip-bans/:id", middleware.RequirePermission("security.manage"), adminSecurityHandler.DeleteIPBan)
+			security.GET("/scraper-offenders", middleware.RequirePermission("security.view"), adminSecurityHandler.ListScraperOffenders)
+			security.POST("/scraper-offenders/ban", middleware.RequirePermission("security.manage"), adminSecurityHandler.BanScraperOffender)
+			security.GET("/lockouts", middleware.RequirePermission("security.view"), adminSecurityHandler.ListLockouts)
+			security.DELETE("/lockouts/:id", middleware.RequirePermission("security.manage"), adminSecurityHandler.UnlockAccount)
+			security.GET("/csp-reports", middleware.RequirePermission("security.view"), adminSecurityHandler.ListCSPViolations)
+			security.DELETE("/csp-reports", middleware.RequirePermission("security.manage"), adminSecurityHandler.ClearCSPViolations)
+		}
+
+		// 危险操作审批（四眼原则）
+		approvals := adminAPI.Group("/approvals")
+		approvals.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			approvals.GET("", adminApprovalHandler.ListApprovals)
+			approvals.POST("/:id/approve", middleware.RequirePermission("admin.approve"), adminApprovalHandler.ApproveApproval)
+			approvals.POST("/:id/reject", middleware.RequirePermission("admin.approve"), adminApprovalHandler.RejectApproval)
+			approvals.POST("/:id/cancel", adminApprovalHandler.CancelApproval)
+		}
+
 		// 日志管理
 		logs := adminAPI.Group("/logs")
 		logs.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
@@ -486,6 +843,9 @@ func SetupRouter(
 			logs.GET("/sms", middleware.RequirePermission("system.logs"), adminLogHandler.ListSmsLogs)
 			logs.GET("/sms/export", middleware.RequirePermission("system.logs"), adminLogHandler.ExportSmsLogs)
 			logs.GET("/statistics", middleware.RequirePermission("system.logs"), adminLogHandler.GetLogStatistics)
+			logs.GET("/slow-queries", middleware.RequirePermission("system.logs"), adminLogHandler.ListSlowQueries)
+			logs.DELETE("/slow-queries", middleware.RequirePermission("system.logs"), adminLogHandler.ResetSlowQueries)
+			logs.GET("/background-jobs", middleware.RequirePermission("system.logs"), adminLogHandler.ListBackgroundJobs)
 			logs.POST("/emails/retry", middleware.RequirePermission("system.logs"), adminLogHandler.RetryFailedEmails)
 			logs.GET("/inventories", middleware.RequirePermission("system.logs"), adminInventoryLogHandler.ListInventoryLogs)
 			logs.GET("/inventories/export", middleware.RequirePermission("system.logs"), adminInventoryLogHandler.ExportInventoryLogs)
@@ -498,6 +858,12 @@ func SetupRouter(
 		{
 			settings.GET("", middleware.RequirePermission("system.config"), adminSettingsHandler.GetSettings)
 			settings.PUT("", middleware.RequirePermission("system.config"), adminSettingsHandler.UpdateSettings)
+			settings.GET("/revisions", middleware.RequirePermission("system.config"), adminSettingsHandler.ListSettingsRevisions)
+			settings.GET("/revisions/:id", middleware.RequirePermission("system.config"), adminSettingsHandler.GetSettingsRevision)
+			settings.POST("/revisions/:id/rollback", middleware.RequirePermission("system.config"), adminSettingsHandler.RollbackSettingsRevision)
+			settings.POST("/export", middleware.RequirePermission("system.config"), adminSettingsHandler.ExportSettings)
+			settings.POST("/import/preview", middleware.RequirePermission("system.config"), adminSettingsHandler.PreviewSettingsImport)
+			settings.POST("/import", middleware.RequirePermission("system.config"), adminSettingsHandler.ImportSettings)
 			settings.POST("/smtp/test", middleware.RequirePermission("system.config"), adminSettingsHandler.TestSMTP)
 			settings.POST("/sms/test", middleware.RequirePermission("system.config"), adminSettingsHandler.TestSMS)
 			settings.GET("/email-templates", middleware.RequirePermission("system.config"), adminSettingsHandler.ListEmailTemplates)
@@ -507,6 +873,36 @@ func SetupRouter(
 			settings.GET("/landing-page", middleware.RequirePermission("system.config"), adminLandingPageHandler.GetLandingPage)
 			settings.PUT("/landing-page", middleware.RequirePermission("system.config"), adminLandingPageHandler.UpdateLandingPage)
 			settings.POST("/landing-page/reset", middleware.RequirePermission("system.config"), adminLandingPageHandler.ResetLandingPage)
+			settings.GET("/landing-pages", middleware.RequirePermission("system.config"), adminLandingPageHandler.ListLandingPages)
+			settings.GET("/landing-page/blocks/schema", middleware.RequirePermission("system.config"), adminLandingPageHandler.GetBlockSchema)
+			settings.POST("/landing-page/blocks/render", middleware.RequirePermission("system.config"), adminLandingPageHandler.PreviewBlocks)
+			settings.GET("/landing-page/revisions", middleware.RequirePermission("system.config"), adminLandingPageHandler.ListRevisions)
+			settings.POST("/landing-page/revisions", middleware.RequirePermission("system.config"), adminLandingPageHandler.SaveDraft)
+			settings.GET("/landing-page/revisions/:id", middleware.RequirePermission("system.config"), adminLandingPageHandler.GetRevision)
+			settings.POST("/landing-page/revisions/:id/publish", middleware.RequirePermission("system.config"), adminLandingPageHandler.PublishRevision)
+			settings.GET("/landing-page/revisions/:id/render", middleware.RequirePermission("system.config"), adminLandingPageHandler.PreviewRevision)
+
+			// 自定义域名（所有权验证 + 证书自动签发）
+			settings.GET("/domains", middleware.RequirePermission("system.config"), adminDomainHandler.ListDomains)
+			settings.POST("/domains", middleware.RequirePermission("system.config"), adminDomainHandler.RegisterDomain)
+			settings.PUT("/domains/:id", middleware.RequirePermission("system.config"), adminDomainHandler.UpdateDomain)
+			settings.DELETE("/domains/:id", middleware.RequirePermission("system.config"), adminDomainHandler.DeleteDomain)
+			settings.POST("/domains/:id/verify", middleware.RequirePermission("system.config"), adminDomainHandler.VerifyDomain)
+
+			// 汇率服务状态
+			settings.GET("/exchange-rates", middleware.RequirePermission("system.config"), adminExchangeRateHandler.ListRates)
+			settings.GET("/exchange-rates/quote", middleware.RequirePermission("system.config"), adminExchangeRateHandler.GetRate)
+
+			// 主题包（调色板/字体/邮件品牌，支持导入导出）
+			settings.GET("/themes", middleware.RequirePermission("system.config"), adminThemeHandler.ListThemes)
+			settings.POST("/themes", middleware.RequirePermission("system.config"), adminThemeHandler.CreateTheme)
+			settings.POST("/themes/import", middleware.RequirePermission("system.config"), adminThemeHandler.ImportTheme)
+			settings.POST("/themes/deactivate", middleware.RequirePermission("system.config"), adminThemeHandler.DeactivateTheme)
+			settings.GET("/themes/:id", middleware.RequirePermission("system.config"), adminThemeHandler.GetTheme)
+			settings.PUT("/themes/:id", middleware.RequirePermission("system.config"), adminThemeHandler.UpdateTheme)
+			settings.DELETE("/themes/:id", middleware.RequirePermission("system.config"), adminThemeHandler.DeleteTheme)
+			settings.POST("/themes/:id/activate", middleware.RequirePermission("system.config"), adminThemeHandler.ActivateTheme)
+			settings.GET("/themes/:id/export", middleware.RequirePermission("system.config"), adminThemeHandler.ExportTheme)
 		}
 
 		// 付款方式管理
@@ -522,13 +918,29 @@ func SetupRouter(
 			paymentMethods.POST("/market/import", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.ImportPackageFromMarket)
 			paymentMethods.POST("/preview-package", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.PreviewPackage)
 			paymentMethods.POST("/upload-package", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.UploadPackage)
+			paymentMethods.GET("/breakers", middleware.RequireAnyPermission("payment_method.view", "system.config"), adminPaymentMethodHandler.ListBreakers)
 			paymentMethods.GET("/:id", middleware.RequireAnyPermission("payment_method.view", "system.config"), adminPaymentMethodHandler.Get)
+			paymentMethods.POST("/:id/breakers/reset", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.ResetBreakers)
+			paymentMethods.GET("/:id/logs", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.ListScriptLogs)
 			paymentMethods.PUT("/:id", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.Update)
 			paymentMethods.DELETE("/:id", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.Delete)
 			paymentMethods.POST("/:id/toggle", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.ToggleEnabled)
 			paymentMethods.POST("/reorder", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.Reorder)
 			paymentMethods.POST("/test-script", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.TestScript)
 			paymentMethods.POST("/init-builtin", middleware.RequirePermission("system.config"), adminPaymentMethodHandler.InitBuiltinMethods)
+			paymentMethods.PUT("/:id/store", middleware.RequirePermission("system.config"), adminStoreHandler.UpdatePaymentMethodStore)
+		}
+
+		// 店铺管理（仅超级Admin）
+		stores := adminAPI.Group("/stores")
+		stores.Use(middleware.AuthMiddleware(), middleware.RequireSuperAdmin())
+		{
+			stores.GET("", middleware.RequirePermission("store.view"), adminStoreHandler.ListStores)
+			stores.POST("", middleware.RequirePermission("store.edit"), adminStoreHandler.CreateStore)
+			stores.GET("/:id", middleware.RequirePermission("store.view"), adminStoreHandler.GetStore)
+			stores.PUT("/:id", middleware.RequirePermission("store.edit"), adminStoreHandler.UpdateStore)
+			stores.DELETE("/:id", middleware.RequirePermission("store.edit"), adminStoreHandler.DeleteStore)
+			stores.PUT("/:id/admins", middleware.RequirePermission("store.edit"), adminStoreHandler.UpdateStoreAdmins)
 		}
 
 		// 优惠码管理
@@ -539,11 +951,47 @@ func SetupRouter(
 			promoCodesAdmin.GET("/export", middleware.RequirePermission("product.view"), adminPromoCodeHandler.ExportPromoCodes)
 			promoCodesAdmin.POST("/import", middleware.RequirePermission("product.edit"), adminPromoCodeHandler.ImportPromoCodes)
 			promoCodesAdmin.POST("", middleware.RequirePermission("product.edit"), adminPromoCodeHandler.CreatePromoCode)
+			promoCodesAdmin.GET("/campaigns", middleware.RequirePermission("product.view"), adminPromoCodeHandler.ListPromoCodeCampaigns)
+			promoCodesAdmin.POST("/campaigns", middleware.RequirePermission("product.edit"), adminPromoCodeHandler.CreatePromoCodeCampaign)
+			promoCodesAdmin.GET("/campaigns/:id/export", middleware.RequirePermission("product.view"), adminPromoCodeHandler.ExportPromoCodeCampaign)
+			promoCodesAdmin.GET("/:id/redemptions", middleware.RequirePermission("product.view"), adminPromoCodeHandler.ListPromoCodeRedemptions)
 			promoCodesAdmin.GET("/:id", middleware.RequirePermission("product.view"), adminPromoCodeHandler.GetPromoCode)
 			promoCodesAdmin.PUT("/:id", middleware.RequirePermission("product.edit"), adminPromoCodeHandler.UpdatePromoCode)
 			promoCodesAdmin.DELETE("/:id", middleware.RequirePermission("product.delete"), adminPromoCodeHandler.DeletePromoCode)
 		}
 
+		// 满赠活动管理
+		giftPromotions := adminAPI.Group("/gift-promotions")
+		giftPromotions.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			giftPromotions.GET("", middleware.RequirePermission("product.view"), adminGiftPromotionHandler.ListGiftPromotions)
+			giftPromotions.POST("", middleware.RequirePermission("product.edit"), adminGiftPromotionHandler.CreateGiftPromotion)
+			giftPromotions.GET("/:id", middleware.RequirePermission("product.view"), adminGiftPromotionHandler.GetGiftPromotion)
+			giftPromotions.PUT("/:id", middleware.RequirePermission("product.edit"), adminGiftPromotionHandler.UpdateGiftPromotion)
+			giftPromotions.DELETE("/:id", middleware.RequirePermission("product.delete"), adminGiftPromotionHandler.DeleteGiftPromotion)
+		}
+
+		// 询价单管理
+		quoteRequestsAdmin := adminAPI.Group("/quote-requests")
+		quoteRequestsAdmin.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			quoteRequestsAdmin.GET("", middleware.RequirePermission("order.view"), adminQuoteRequestHandler.ListQuoteRequests)
+			quoteRequestsAdmin.GET("/:id", middleware.RequirePermission("order.view"), adminQuoteRequestHandler.GetQuoteRequest)
+			quoteRequestsAdmin.POST("/:id/quote", middleware.RequirePermission("order.edit"), adminQuoteRequestHandler.QuoteRequest)
+			quoteRequestsAdmin.POST("/:id/close", middleware.RequirePermission("order.edit"), adminQuoteRequestHandler.CloseQuoteRequest)
+		}
+
+		// 退货申请（RMA）
+		returnRequests := adminAPI.Group("/returns")
+		returnRequests.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			returnRequests.GET("", middleware.RequirePermission("return.view"), adminReturnRequestHandler.ListReturnRequests)
+			returnRequests.GET("/:id", middleware.RequirePermission("return.view"), adminReturnRequestHandler.GetReturnRequest)
+			returnRequests.POST("/:id/approve", middleware.RequirePermission("return.manage"), adminReturnRequestHandler.ApproveReturnRequest)
+			returnRequests.POST("/:id/reject", middleware.RequirePermission("return.manage"), adminReturnRequestHandler.RejectReturnRequest)
+			returnRequests.POST("/:id/receive", middleware.RequirePermission("return.manage"), adminReturnRequestHandler.ReceiveReturnRequest)
+		}
+
 		// 序列号管理
 		serials := adminAPI.Group("/serials")
 		serials.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
@@ -568,6 +1016,9 @@ func SetupRouter(
 			virtualInventories.PUT("/:id", middleware.RequirePermission("product.edit"), adminVirtualInventoryHandler.UpdateVirtualInventory)
 			virtualInventories.DELETE("/:id", middleware.RequirePermission("product.delete"), adminVirtualInventoryHandler.DeleteVirtualInventory)
 
+			// 临期库存报表
+			virtualInventories.GET("/expiry-report", middleware.RequirePermission("product.view"), adminVirtualInventoryHandler.GetExpiryReport)
+
 			// 脚本测试
 			virtualInventories.POST("/test-script", middleware.RequirePermission("product.edit"), adminVirtualInventoryHandler.TestDeliveryScript)
 
@@ -579,6 +1030,12 @@ func SetupRouter(
 			virtualInventories.DELETE("/:id/stocks/:stock_id", middleware.RequirePermission("product.edit"), adminVirtualInventoryHandler.DeleteStock)
 			virtualInventories.POST("/:id/stocks/:stock_id/reserve", middleware.RequirePermission("product.edit"), adminVirtualInventoryHandler.ReserveStock)
 			virtualInventories.POST("/:id/stocks/:stock_id/release", middleware.RequirePermission("product.edit"), adminVirtualInventoryHandler.ReleaseStockItem)
+			virtualInventories.POST("/:id/stocks/:stock_id/revoke", middleware.RequirePermission("product.edit"), adminVirtualInventoryHandler.RevokeStock)
+			virtualInventories.POST("/:id/stocks/:stock_id/reveal", middleware.RequirePermission("product.view"), middleware.RateLimitMiddleware(60, time.Minute), adminVirtualInventoryHandler.RevealStock)
+			virtualInventories.GET("/:id/stocks/:stock_id/reveals", middleware.RequirePermission("product.view"), adminVirtualInventoryHandler.ListStockReveals)
+			virtualInventories.PUT("/:id/batches/expiry", middleware.RequirePermission("product.edit"), adminVirtualInventoryHandler.SetBatchExpiry)
+			virtualInventories.POST("/:id/transfer", middleware.RequirePermission("product.edit"), adminVirtualInventoryHandler.TransferStock)
+			virtualInventories.GET("/:id/transfers", middleware.RequirePermission("product.view"), adminVirtualInventoryHandler.ListStockTransfers)
 			virtualInventories.DELETE("/batch", middleware.RequirePermission("product.edit"), adminVirtualInventoryHandler.DeleteBatch)
 
 			// 获取虚拟库存绑定的商品
@@ -626,6 +1083,18 @@ func SetupRouter(
 			tickets.POST("/:id/upload", middleware.RequirePermission("ticket.reply"), adminTicketHandler.UploadFile)
 		}
 
+		// 工单自动化规则（自动打标签、调整优先级）
+		ticketAutomation := adminAPI.Group("/ticket-automation")
+		ticketAutomation.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
+		{
+			ticketAutomation.GET("/meta", middleware.RequirePermission("ticket.automation"), adminTicketAutomationHandler.GetTicketAutomationMeta)
+			ticketAutomation.GET("/rules", middleware.RequirePermission("ticket.automation"), adminTicketAutomationHandler.ListTicketAutomationRules)
+			ticketAutomation.POST("/rules", middleware.RequirePermission("ticket.automation"), adminTicketAutomationHandler.CreateTicketAutomationRule)
+			ticketAutomation.GET("/rules/:id", middleware.RequirePermission("ticket.automation"), adminTicketAutomationHandler.GetTicketAutomationRule)
+			ticketAutomation.PUT("/rules/:id", middleware.RequirePermission("ticket.automation"), adminTicketAutomationHandler.UpdateTicketAutomationRule)
+			ticketAutomation.DELETE("/rules/:id", middleware.RequirePermission("ticket.automation"), adminTicketAutomationHandler.DeleteTicketAutomationRule)
+		}
+
 		// 知识库管理
 		knowledgeAdmin := adminAPI.Group("/knowledge")
 		knowledgeAdmin.Use(middleware.AuthMiddleware(), middleware.RequireAdmin())
@@ -719,16 +1188,35 @@ func SetupRouter(
 	uploadsGroup := r.Group("/uploads")
 	{
 		productUploadHandler := buildDynamicUploadFileHandler("products", cfg.Upload.Dir)
-		ticketUploadHandler := buildDynamicUploadFileHandler("tickets", cfg.Upload.Dir)
-		uploadsGroup.GET("/products/*filepath", productUploadHandler)
-		uploadsGroup.HEAD("/products/*filepath", productUploadHandler)
+		// 工单附件只能通过工单消息接口签发的短期链接访问
+		ticketUploadHandler := buildSignedTicketUploadHandler(buildDynamicUploadFileHandler("tickets", cfg.Upload.Dir))
+		uploadsGroup.GET("/products/*filepath", middleware.HotlinkProtection(), productUploadHandler)
+		uploadsGroup.HEAD("/products/*filepath", middleware.HotlinkProtection(), productUploadHandler)
 		uploadsGroup.GET("/tickets/*filepath", ticketUploadHandler)
 		uploadsGroup.HEAD("/tickets/*filepath", ticketUploadHandler)
+		// 退货照片同样只能通过退货申请接口签发的短期链接访问
+		returnUploadHandler := buildSignedUploadHandler(service.VerifyReturnPhotoSignature, buildDynamicUploadFileHandler("returns", cfg.Upload.Dir))
+		uploadsGroup.GET("/returns/*filepath", returnUploadHandler)
+		uploadsGroup.HEAD("/returns/*filepath", returnUploadHandler)
 	}
 
 	// 落地页（公开）
 	r.GET("/", adminLandingPageHandler.ServeLandingPage)
 
+	// 短链接（公开）
+	r.GET("/s/:code", userShortLinkHandler.Redirect)
+	r.GET("/s/:code/qr", userShortLinkHandler.QRCode)
+
+	// SEO（公开）
+	r.GET("/sitemap.xml", userSEOHandler.Sitemap)
+	r.GET("/robots.txt", userSEOHandler.Robots)
+
+	// 端到端测试夹具（仅 -tags e2e 构建）
+	registerE2EFixtureRoutes(r, cfg, db, orderService, serialService, pluginManagerService)
+
+	// OpenAPI 文档（公开，供客户端生成 SDK）
+	registerOpenAPIRoute(r, version)
+
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
@@ -737,6 +1225,16 @@ func SetupRouter(
 	return r
 }
 
+// resolveOrderCreateConcurrencyLimit 下单接口的单用户/单IP并发上限，跟随高并发保护开关
+func resolveOrderCreateConcurrencyLimit(c *gin.Context) (int, int) {
+	runtimeCfg := config.GetConfig()
+	if runtimeCfg == nil || !runtimeCfg.Order.HighConcurrencyProtection.Enabled {
+		return 0, 0
+	}
+	protection := runtimeCfg.Order.HighConcurrencyProtection
+	return protection.MaxInFlightPerUser, protection.MaxInFlightPerIP
+}
+
 func resolveRateLimit(limitSelector func(*config.Config) int, fallback int) middleware.RateLimitResolver {
 	return func(c *gin.Context) (int, bool) {
 		runtimeCfg := config.GetConfig()
@@ -755,6 +1253,30 @@ func resolveRateLimit(limitSelector func(*config.Config) int, fallback int) midd
 	}
 }
 
+// buildSignedTicketUploadHandler 校验附件签名后再读取文件
+func buildSignedTicketUploadHandler(next gin.HandlerFunc) gin.HandlerFunc {
+	return buildSignedUploadHandler(service.VerifyTicketAttachmentSignature, next)
+}
+
+// buildSignedUploadHandler 按给定的签名校验函数保护私有上传文件
+func buildSignedUploadHandler(verify func(relPath, expiresRaw, sig string, now time.Time) bool, next gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		relPath := strings.TrimPrefix(c.Param("filepath"), "/")
+		now := time.Now()
+		if !verify(relPath, c.Query("expires"), c.Query("sig"), now) {
+			c.Status(http.StatusForbidden)
+			return
+		}
+		maxAge := 0
+		if expires, err := strconv.ParseInt(c.Query("expires"), 10, 64); err == nil && expires > now.Unix() {
+			maxAge = int(expires - now.Unix())
+		}
+		c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+		c.Header("X-Robots-Tag", "noindex")
+		next(c)
+	}
+}
+
 func buildDynamicUploadFileHandler(area string, fallbackUploadDir string) gin.HandlerFunc {
 	normalizedArea := strings.TrimSpace(area)
 	return func(c *gin.Context) {
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+const maxAdminActivityRangeDays = 366
+
+// 计入“处理订单”的管理员操作
+var adminActivityOrderActions = map[string]bool{
+	"assign_tracking":       true,
+	"complete":              true,
+	"cancel":                true,
+	"refund":                true,
+	"confirm_refund":        true,
+	"mark_paid":             true,
+	"deliver_virtual_stock": true,
+	"request_resubmit":      true,
+	"update_price":          true,
+}
+
+// 批量操作日志只记录成功数量，按 success_count 计入处理订单数
+var adminActivityBatchOrderActions = map[string]bool{
+	"batch_complete_orders": true,
+	"batch_cancel_orders":   true,
+}
+
+// AdminActivitySummary 单个管理员在统计区间内的工作量
+type AdminActivitySummary struct {
+	AdminID                    uint       `json:"admin_id"`
+	Name                       string     `json:"name"`
+	Email                      string     `json:"email"`
+	Role                       string     `json:"role"`
+	OrdersProcessed            int64      `json:"orders_processed"`
+	OrdersShipped              int64      `json:"orders_shipped"`
+	ShippedPerDay              float64    `json:"shipped_per_day"`
+	TicketsResolved            int64      `json:"tickets_resolved"`
+	TicketReplies              int64      `json:"ticket_replies"`
+	AvgShipHandlingSeconds     int64      `json:"avg_ship_handling_seconds"`     // 下单到发货
+	AvgTicketResolutionSeconds int64      `json:"avg_ticket_resolution_seconds"` // 工单创建到解决/关闭
+	TotalActions               int64      `json:"total_actions"`
+	LastActiveAt               *time.Time `json:"last_active_at,omitempty"`
+
+	shipHandlingTotal      time.Duration
+	shipHandlingSample     int64
+	ticketResolutionTotal  time.Duration
+	ticketResolutionSample int64
+}
+
+// AdminActivityDay 单日工作量
+type AdminActivityDay struct {
+	Date            string `json:"date"`
+	OrdersProcessed int64  `json:"orders_processed"`
+	OrdersShipped   int64  `json:"orders_shipped"`
+	TicketsResolved int64  `json:"tickets_resolved"`
+	TicketReplies   int64  `json:"ticket_replies"`
+}
+
+// AdminActivityDetail 单个管理员工作量详情
+type AdminActivityDetail struct {
+	AdminActivitySummary
+	Daily   []AdminActivityDay `json:"daily"`
+	Actions map[string]int64   `json:"actions"` // resource_type.action -> 次数
+}
+
+// AdminActivityService 管理员工作量统计，数据来自操作日志和工单消息
+type AdminActivityService struct {
+	db *gorm.DB
+}
+
+func NewAdminActivityService(db *gorm.DB) *AdminActivityService {
+	return &AdminActivityService{db: db}
+}
+
+// ValidateAdminActivityRange 校验统计区间 [from, to)
+func ValidateAdminActivityRange(from, to time.Time) error {
+	if !to.After(from) || to.Sub(from) > maxAdminActivityRangeDays*24*time.Hour {
+		return bizerr.Newf("adminActivity.rangeInvalid", "Date range must be between 1 and %d days", maxAdminActivityRangeDays).
+			WithParams(map[string]interface{}{"max": maxAdminActivityRangeDays})
+	}
+	return nil
+}
+
+type adminActivityLogRow struct {
+	UserID       uint
+	Action       string
+	ResourceType string
+	ResourceID   *uint
+	Details      map[string]interface{} `gorm:"serializer:json"`
+	CreatedAt    time.Time
+}
+
+type adminActivityCollector struct {
+	summaries map[uint]*AdminActivitySummary
+	daily     map[uint]map[string]*AdminActivityDay
+	actions   map[uint]map[string]int64
+	withDaily bool
+}
+
+func (c *adminActivityCollector) summary(adminID uint) *AdminActivitySummary {
+	summary, ok := c.summaries[adminID]
+	if !ok {
+		summary = &AdminActivitySummary{AdminID: adminID}
+		c.summaries[adminID] = summary
+	}
+	return summary
+}
+
+func (c *adminActivityCollector) day(adminID uint, at time.Time) *AdminActivityDay {
+	if !c.withDaily {
+		return &AdminActivityDay{}
+	}
+	days, ok := c.daily[adminID]
+	if !ok {
+		days = make(map[string]*AdminActivityDay)
+		c.daily[adminID] = days
+	}
+	key := at.UTC().Format("2006-01-02")
+	day, ok := days[key]
+	if !ok {
+		day = &AdminActivityDay{Date: key}
+		days[key] = day
+	}
+	return day
+}
+
+func (c *adminActivityCollector) touch(summary *AdminActivitySummary, at time.Time) {
+	if summary.LastActiveAt == nil || at.After(*summary.LastActiveAt) {
+		t := at
+		summary.LastActiveAt = &t
+	}
+}
+
+// collect 汇总区间内的操作日志和工单回复，adminID 为 nil 时统计全部管理员
+func (s *AdminActivityService) collect(adminID *uint, from, to time.Time, withDaily bool) (*adminActivityCollector, error) {
+	collector := &adminActivityCollector{
+		summaries: make(map[uint]*AdminActivitySummary),
+		daily:     make(map[uint]map[string]*AdminActivityDay),
+		actions:   make(map[uint]map[string]int64),
+		withDaily: withDaily,
+	}
+
+	adminQuery := s.db.Model(&models.User{}).Where("role IN ?", []string{"admin", "super_admin"})
+	if adminID != nil {
+		adminQuery = adminQuery.Where("id = ?", *adminID)
+	}
+	var admins []models.User
+	if err := adminQuery.Select("id", "name", "email", "role").Order("id ASC").Find(&admins).Error; err != nil {
+		return nil, err
+	}
+	adminIDs := make([]uint, 0, len(admins))
+	for _, admin := range admins {
+		summary := collector.summary(admin.ID)
+		summary.Name = admin.Name
+		summary.Email = admin.Email
+		summary.Role = admin.Role
+		adminIDs = append(adminIDs, admin.ID)
+	}
+	if len(adminIDs) == 0 {
+		return collector, nil
+	}
+
+	var rows []adminActivityLogRow
+	if err := s.db.Model(&models.OperationLog{}).
+		Select("user_id", "action", "resource_type", "resource_id", "details", "created_at").
+		Where("user_id IN ? AND created_at >= ? AND created_at < ?", adminIDs, from, to).
+		Order("id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	shippedOrderAt := make(map[uint]map[uint]time.Time)
+	resolvedTicketAt := make(map[uint]map[uint]time.Time)
+	for _, row := range rows {
+		summary := collector.summary(row.UserID)
+		day := collector.day(row.UserID, row.CreatedAt)
+		summary.TotalActions++
+		collector.touch(summary, row.CreatedAt)
+		if withDaily {
+			actions, ok := collector.actions[row.UserID]
+			if !ok {
+				actions = make(map[string]int64)
+				collector.actions[row.UserID] = actions
+			}
+			actions[row.ResourceType+"."+row.Action]++
+		}
+
+		switch row.ResourceType {
+		case "order":
+			if adminActivityOrderActions[row.Action] {
+				summary.OrdersProcessed++
+				day.OrdersProcessed++
+			} else if adminActivityBatchOrderActions[row.Action] {
+				count := adminActivityDetailInt(row.Details, "success_count")
+				summary.OrdersProcessed += count
+				day.OrdersProcessed += count
+			}
+			if row.Action == "assign_tracking" && row.ResourceID != nil {
+				summary.OrdersShipped++
+				day.OrdersShipped++
+				if shippedOrderAt[row.UserID] == nil {
+					shippedOrderAt[row.UserID] = make(map[uint]time.Time)
+				}
+				shippedOrderAt[row.UserID][*row.ResourceID] = row.CreatedAt
+			}
+		case "ticket":
+			if (row.Action == "resolve" || row.Action == "close") && row.ResourceID != nil {
+				summary.TicketsResolved++
+				day.TicketsResolved++
+				if resolvedTicketAt[row.UserID] == nil {
+					resolvedTicketAt[row.UserID] = make(map[uint]time.Time)
+				}
+				resolvedTicketAt[row.UserID][*row.ResourceID] = row.CreatedAt
+			}
+		}
+	}
+
+	if err := s.collectHandlingTimes(collector, shippedOrderAt, resolvedTicketAt); err != nil {
+		return nil, err
+	}
+
+	var replies []models.TicketMessage
+	if err := s.db.Model(&models.TicketMessage{}).
+		Select("sender_id", "created_at").
+		Where("sender_type = ? AND sender_id IN ? AND created_at >= ? AND created_at < ?", "admin", adminIDs, from, to).
+		Find(&replies).Error; err != nil {
+		return nil, err
+	}
+	for _, reply := range replies {
+		summary := collector.summary(reply.SenderID)
+		summary.TicketReplies++
+		collector.day(reply.SenderID, reply.CreatedAt).TicketReplies++
+		collector.touch(summary, reply.CreatedAt)
+	}
+
+	days := to.Sub(from).Hours() / 24
+	for _, summary := range collector.summaries {
+		if days > 0 {
+			summary.ShippedPerDay = float64(int64(float64(summary.OrdersShipped)/days*100+0.5)) / 100
+		}
+		if summary.shipHandlingSample > 0 {
+			summary.AvgShipHandlingSeconds = int64(summary.shipHandlingTotal.Seconds()) / summary.shipHandlingSample
+		}
+		if summary.ticketResolutionSample > 0 {
+			summary.AvgTicketResolutionSeconds = int64(summary.ticketResolutionTotal.Seconds()) / summary.ticketResolutionSample
+		}
+	}
+	return collector, nil
+}
+
+// collectHandlingTimes 计算下单到发货、工单创建到解决的耗时
+func (s *AdminActivityService) collectHandlingTimes(collector *adminActivityCollector, shippedOrderAt, resolvedTicketAt map[uint]map[uint]time.Time) error {
+	orderIDs := make([]uint, 0)
+	for _, orders := range shippedOrderAt {
+		for id := range orders {
+			orderIDs = append(orderIDs, id)
+		}
+	}
+	if len(orderIDs) > 0 {
+		var orders []models.Order
+		if err := s.db.Unscoped().Select("id", "created_at").Where("id IN ?", orderIDs).Find(&orders).Error; err != nil {
+			return err
+		}
+		createdAt := make(map[uint]time.Time, len(orders))
+		for _, order := range orders {
+			createdAt[order.ID] = order.CreatedAt
+		}
+		for adminID, shipped := range shippedOrderAt {
+			summary := collector.summary(adminID)
+			for orderID, at := range shipped {
+				if created, ok := createdAt[orderID]; ok && at.After(created) {
+					summary.shipHandlingTotal += at.Sub(created)
+					summary.shipHandlingSample++
+				}
+			}
+		}
+	}
+
+	ticketIDs := make([]uint, 0)
+	for _, tickets := range resolvedTicketAt {
+		for id := range tickets {
+			ticketIDs = append(ticketIDs, id)
+		}
+	}
+	if len(ticketIDs) > 0 {
+		var tickets []models.Ticket
+		if err := s.db.Unscoped().Select("id", "created_at").Where("id IN ?", ticketIDs).Find(&tickets).Error; err != nil {
+			return err
+		}
+		createdAt := make(map[uint]time.Time, len(tickets))
+		for _, ticket := range tickets {
+			createdAt[ticket.ID] = ticket.CreatedAt
+		}
+		for adminID, resolved := range resolvedTicketAt {
+			summary := collector.summary(adminID)
+			for ticketID, at := range resolved {
+				if created, ok := createdAt[ticketID]; ok && at.After(created) {
+					summary.ticketResolutionTotal += at.Sub(created)
+					summary.ticketResolutionSample++
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Leaderboard 管理员工作量排行
+// sortBy 支持 orders_processed / orders_shipped / tickets_resolved / ticket_replies / total_actions，默认按处理订单数
+func (s *AdminActivityService) Leaderboard(from, to time.Time, sortBy string) ([]AdminActivitySummary, error) {
+	if err := ValidateAdminActivityRange(from, to); err != nil {
+		return nil, err
+	}
+	collector, err := s.collect(nil, from, to, false)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]AdminActivitySummary, 0, len(collector.summaries))
+	for _, summary := range collector.summaries {
+		items = append(items, *summary)
+	}
+	metric := adminActivitySortMetric(sortBy)
+	sort.Slice(items, func(i, j int) bool {
+		a, b := metric(&items[i]), metric(&items[j])
+		if a != b {
+			return a > b
+		}
+		if items[i].TotalActions != items[j].TotalActions {
+			return items[i].TotalActions > items[j].TotalActions
+		}
+		return items[i].AdminID < items[j].AdminID
+	})
+	return items, nil
+}
+
+// GetAdminActivity 单个管理员的工作量和每日明细
+func (s *AdminActivityService) GetAdminActivity(adminID uint, from, to time.Time) (*AdminActivityDetail, error) {
+	if err := ValidateAdminActivityRange(from, to); err != nil {
+		return nil, err
+	}
+	collector, err := s.collect(&adminID, from, to, true)
+	if err != nil {
+		return nil, err
+	}
+	summary, ok := collector.summaries[adminID]
+	if !ok {
+		return nil, bizerr.New("adminActivity.adminNotFound", "Admin not found")
+	}
+
+	detail := &AdminActivityDetail{
+		AdminActivitySummary: *summary,
+		Daily:                make([]AdminActivityDay, 0),
+		Actions:              collector.actions[adminID],
+	}
+	if detail.Actions == nil {
+		detail.Actions = map[string]int64{}
+	}
+	// 补齐没有活动的日期，便于前端直接绘图
+	days := collector.daily[adminID]
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		key := day.UTC().Format("2006-01-02")
+		if item, ok := days[key]; ok {
+			detail.Daily = append(detail.Daily, *item)
+		} else {
+			detail.Daily = append(detail.Daily, AdminActivityDay{Date: key})
+		}
+	}
+	return detail, nil
+}
+
+func adminActivitySortMetric(sortBy string) func(*AdminActivitySummary) int64 {
+	switch sortBy {
+	case "orders_shipped":
+		return func(s *AdminActivitySummary) int64 { return s.OrdersShipped }
+	case "tickets_resolved":
+		return func(s *AdminActivitySummary) int64 { return s.TicketsResolved }
+	case "ticket_replies":
+		return func(s *AdminActivitySummary) int64 { return s.TicketReplies }
+	case "total_actions":
+		return func(s *AdminActivitySummary) int64 { return s.TotalActions }
+	default:
+		return func(s *AdminActivitySummary) int64 { return s.OrdersProcessed }
+	}
+}
+
+func adminActivityDetailInt(details map[string]interface{}, key string) int64 {
+	switch value := details[key].(type) {
+	case float64:
+		return int64(value)
+	case int:
+		return int64(value)
+	case int64:
+		return value
+	case string:
+		parsed, _ := strconv.ParseInt(value, 10, 64)
+		return parsed
+	}
+	return 0
+}
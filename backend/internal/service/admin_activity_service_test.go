@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+func TestAdminActivityLeaderboardAggregatesLogsAndTicketReplies(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.OperationLog{}, &models.Order{}, &models.Ticket{}, &models.TicketMessage{})
+
+	alice := models.User{UUID: "act-alice", Email: "alice@example.com", Name: "Alice", Role: "admin"}
+	bob := models.User{UUID: "act-bob", Email: "bob@example.com", Name: "Bob", Role: "super_admin"}
+	customer := models.User{UUID: "act-customer", Email: "customer@example.com", Name: "Customer", Role: "user"}
+	for _, user := range []*models.User{&alice, &bob, &customer} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user failed: %v", err)
+		}
+	}
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+	day1 := from.Add(10 * time.Hour)
+	day2 := from.Add(34 * time.Hour)
+
+	order := models.Order{OrderNo: "ACT-1", UserID: &customer.ID, Status: models.OrderStatusShipped, CreatedAt: day1.Add(-2 * time.Hour)}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+	ticket := models.Ticket{TicketNo: "T-ACT-1", UserID: customer.ID, Subject: "Help", Content: "Help", CreatedAt: day2.Add(-time.Hour)}
+	if err := db.Create(&ticket).Error; err != nil {
+		t.Fatalf("create ticket failed: %v", err)
+	}
+
+	logs := []models.OperationLog{
+		{UserID: &alice.ID, Action: "assign_tracking", ResourceType: "order", ResourceID: &order.ID, CreatedAt: day1},
+		{UserID: &alice.ID, Action: "batch_complete_orders", ResourceType: "order", Details: map[string]interface{}{"success_count": 3}, CreatedAt: day2},
+		{UserID: &alice.ID, Action: "update", ResourceType: "product", CreatedAt: day2},
+		{UserID: &bob.ID, Action: "resolve", ResourceType: "ticket", ResourceID: &ticket.ID, CreatedAt: day2},
+		{UserID: &bob.ID, Action: "cancel", ResourceType: "order", ResourceID: &order.ID, CreatedAt: to.Add(time.Hour)},
+		{UserID: &customer.ID, Action: "cancel", ResourceType: "order", ResourceID: &order.ID, CreatedAt: day1},
+	}
+	for i := range logs {
+		if err := db.Create(&logs[i]).Error; err != nil {
+			t.Fatalf("create log failed: %v", err)
+		}
+	}
+	messages := []models.TicketMessage{
+		{TicketID: ticket.ID, SenderType: "admin", SenderID: bob.ID, Content: "On it", CreatedAt: day2},
+		{TicketID: ticket.ID, SenderType: "admin", SenderID: bob.ID, Content: "Fixed", CreatedAt: day2},
+		{TicketID: ticket.ID, SenderType: "user", SenderID: customer.ID, Content: "Thanks", CreatedAt: day2},
+	}
+	for i := range messages {
+		if err := db.Create(&messages[i]).Error; err != nil {
+			t.Fatalf("create message failed: %v", err)
+		}
+	}
+
+	svc := NewAdminActivityService(db)
+	items, err := svc.Leaderboard(from, to, "")
+	if err != nil {
+		t.Fatalf("leaderboard failed: %v", err)
+	}
+	if len(items) != 2 || items[0].AdminID != alice.ID || items[1].AdminID != bob.ID {
+		t.Fatalf("expected admins only ordered by orders processed, got %+v", items)
+	}
+	first := items[0]
+	if first.OrdersProcessed != 4 || first.OrdersShipped != 1 || first.ShippedPerDay != 0.5 || first.TotalActions != 3 {
+		t.Fatalf("unexpected alice summary: %+v", first)
+	}
+	if first.AvgShipHandlingSeconds != int64((2 * time.Hour).Seconds()) {
+		t.Fatalf("expected 2h ship handling time, got %d", first.AvgShipHandlingSeconds)
+	}
+	second := items[1]
+	if second.OrdersProcessed != 0 || second.TicketsResolved != 1 || second.TicketReplies != 2 || second.AvgTicketResolutionSeconds != int64(time.Hour.Seconds()) {
+		t.Fatalf("unexpected bob summary: %+v", second)
+	}
+
+	items, err = svc.Leaderboard(from, to, "ticket_replies")
+	if err != nil {
+		t.Fatalf("leaderboard failed: %v", err)
+	}
+	if items[0].AdminID != bob.ID {
+		t.Fatalf("expected bob first when sorting by ticket replies, got %+v", items)
+	}
+
+	detail, err := svc.GetAdminActivity(alice.ID, from, to)
+	if err != nil {
+		t.Fatalf("get activity failed: %v", err)
+	}
+	if len(detail.Daily) != 2 || detail.Daily[0].Date != "2025-03-01" || detail.Daily[0].OrdersShipped != 1 || detail.Daily[1].OrdersProcessed != 3 {
+		t.Fatalf("unexpected daily breakdown: %+v", detail.Daily)
+	}
+	if detail.Actions["order.assign_tracking"] != 1 || detail.Actions["product.update"] != 1 {
+		t.Fatalf("unexpected action breakdown: %+v", detail.Actions)
+	}
+
+	_, err = svc.GetAdminActivity(customer.ID, from, to)
+	requireProductBizErr(t, err, "adminActivity.adminNotFound")
+	_, err = svc.Leaderboard(to, from, "")
+	requireProductBizErr(t, err, "adminActivity.rangeInvalid")
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+// ErrAdminApprovalRequired 操作需要第二位管理员审批，调用方应返回已创建的审批单
+var ErrAdminApprovalRequired = errors.New("admin approval required")
+
+var (
+	ErrAdminApprovalNotFound    = bizerr.New("approval.notFound", "Approval request not found")
+	ErrAdminApprovalNotPending  = bizerr.New("approval.notPending", "Approval request has already been processed")
+	ErrAdminApprovalSelfReview  = bizerr.New("approval.selfReview", "You cannot review your own approval request")
+	ErrAdminApprovalNotApproved = bizerr.New("approval.notApproved", "Approval request has not been approved")
+	ErrAdminApprovalExpired     = bizerr.New("approval.expired", "Approval request has expired, please submit again")
+	ErrAdminApprovalMismatch    = bizerr.New("approval.mismatch", "Approval request does not match this operation")
+)
+
+// AdminApprovalRequest 待审批的操作，Payload 为决定操作结果的参数（执行时需完全一致）
+type AdminApprovalRequest struct {
+	ActionType   string
+	ResourceType string
+	ResourceID   string
+	Summary      string
+	Payload      map[string]interface{}
+	RequestedBy  uint
+}
+
+// AdminApprovalFilter 审批单列表筛选
+type AdminApprovalFilter struct {
+	Status      string
+	ActionType  string
+	RequestedBy uint
+}
+
+// AdminApprovalService 危险操作双人审批
+type AdminApprovalService struct {
+	db *gorm.DB
+}
+
+func NewAdminApprovalService(db *gorm.DB) *AdminApprovalService {
+	return &AdminApprovalService{db: db}
+}
+
+func approvalSettings() config.ApprovalConfig {
+	if cfg := config.GetConfig(); cfg != nil {
+		return cfg.Security.Approval
+	}
+	return config.ApprovalConfig{}
+}
+
+// RefundRequiresApproval 退款金额是否达到审批阈值
+func RefundRequiresApproval(amountMinor int64) bool {
+	settings := approvalSettings()
+	return settings.Enabled && settings.RefundAmountThreshold > 0 && amountMinor >= settings.RefundAmountThreshold
+}
+
+// BulkDeleteRequiresApproval 批量删除条数是否达到审批阈值
+func BulkDeleteRequiresApproval(count int) bool {
+	settings := approvalSettings()
+	return settings.Enabled && settings.BulkDeleteThreshold > 0 && count >= settings.BulkDeleteThreshold
+}
+
+// PriceChangeRequiresApproval 改价幅度是否超过审批阈值（原价为 0 时任何改价都视为超过）
+func PriceChangeRequiresApproval(beforeMinor, afterMinor int64) bool {
+	settings := approvalSettings()
+	if !settings.Enabled || settings.PriceChangePercent <= 0 || beforeMinor == afterMinor {
+		return false
+	}
+	if beforeMinor <= 0 {
+		return true
+	}
+	diff := afterMinor - beforeMinor
+	if diff < 0 {
+		diff = -diff
+	}
+	return float64(diff)*100/float64(beforeMinor) > settings.PriceChangePercent
+}
+
+func truncateApprovalText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
+
+func adminApprovalPayloadHash(actionType, resourceID string, payload map[string]interface{}) (string, error) {
+	// encoding/json 对 map 键排序，相同参数得到相同哈希
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(actionType + "\x00" + resourceID + "\x00" + string(data)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// expireStale 将过期的待审批/已通过审批单标记为过期
+func (s *AdminApprovalService) expireStale(now time.Time) {
+	s.db.Model(&models.AdminApproval{}).
+		Where("status IN ? AND expires_at < ?", []string{models.AdminApprovalStatusPending, models.AdminApprovalStatusApproved}, now).
+		Update("status", models.AdminApprovalStatusExpired)
+}
+
+// Authorize 校验操作是否可以执行
+// approvalID 为 0 时创建（或复用相同参数的）待审批记录并返回 ErrAdminApprovalRequired；
+// 否则校验审批单已通过且与本次操作一致，并将其标记为执行中（同一时间只能被一个请求占用），
+// 调用方在操作成功后调用 Complete，失败时调用 Release
+func (s *AdminApprovalService) Authorize(req AdminApprovalRequest, approvalID uint) (*models.AdminApproval, error) {
+	hash, err := adminApprovalPayloadHash(req.ActionType, req.ResourceID, req.Payload)
+	if err != nil {
+		return nil, err
+	}
+	now := models.NowFunc()
+	s.expireStale(now)
+
+	if approvalID == 0 {
+		var existing models.AdminApproval
+		err := s.db.Where("action_type = ? AND payload_hash = ? AND requested_by = ? AND status = ?",
+			req.ActionType, hash, req.RequestedBy, models.AdminApprovalStatusPending).
+			Order("id DESC").First(&existing).Error
+		if err == nil {
+			return &existing, ErrAdminApprovalRequired
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		approval := &models.AdminApproval{
+			ActionType:   req.ActionType,
+			ResourceType: req.ResourceType,
+			ResourceID:   req.ResourceID,
+			Summary:      truncateApprovalText(req.Summary, 500),
+			Payload:      req.Payload,
+			PayloadHash:  hash,
+			Status:       models.AdminApprovalStatusPending,
+			RequestedBy:  req.RequestedBy,
+			ExpiresAt:    now.Add(time.Duration(approvalSettings().ExpireMinutes) * time.Minute),
+		}
+		if err := s.db.Create(approval).Error; err != nil {
+			return nil, err
+		}
+		return approval, ErrAdminApprovalRequired
+	}
+
+	approval, err := s.Get(approvalID)
+	if err != nil {
+		return nil, err
+	}
+	if approval.ActionType != req.ActionType || approval.PayloadHash != hash || approval.RequestedBy != req.RequestedBy {
+		return nil, ErrAdminApprovalMismatch
+	}
+	switch approval.Status {
+	case models.AdminApprovalStatusApproved:
+	case models.AdminApprovalStatusExpired:
+		return nil, ErrAdminApprovalExpired
+	case models.AdminApprovalStatusPending:
+		return nil, ErrAdminApprovalNotApproved
+	default:
+		return nil, ErrAdminApprovalNotPending
+	}
+
+	// 条件更新保证审批单同一时间只被一个请求占用
+	result := s.db.Model(&models.AdminApproval{}).
+		Where("id = ? AND status = ?", approval.ID, models.AdminApprovalStatusApproved).
+		Update("status", models.AdminApprovalStatusExecuting)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrAdminApprovalNotPending
+	}
+	approval.Status = models.AdminApprovalStatusExecuting
+	return approval, nil
+}
+
+// Complete 受审批的操作执行成功，将审批单标记为已执行（只能使用一次）
+func (s *AdminApprovalService) Complete(id uint) error {
+	return s.db.Model(&models.AdminApproval{}).
+		Where("id = ? AND status = ?", id, models.AdminApprovalStatusExecuting).
+		Updates(map[string]interface{}{"status": models.AdminApprovalStatusExecuted, "executed_at": models.NowFunc()}).Error
+}
+
+// Release 受审批的操作执行失败，审批单退回已通过状态，发起人可修正后重试
+func (s *AdminApprovalService) Release(id uint) error {
+	return s.db.Model(&models.AdminApproval{}).
+		Where("id = ? AND status = ?", id, models.AdminApprovalStatusExecuting).
+		Update("status", models.AdminApprovalStatusApproved).Error
+}
+
+// Get 获取审批单
+func (s *AdminApprovalService) Get(id uint) (*models.AdminApproval, error) {
+	var approval models.AdminApproval
+	if err := s.db.First(&approval, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAdminApprovalNotFound
+		}
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// List 审批单列表，最新的在前
+func (s *AdminApprovalService) List(filter AdminApprovalFilter, page, limit int) ([]models.AdminApproval, int64, error) {
+	s.expireStale(models.NowFunc())
+
+	query := s.db.Model(&models.AdminApproval{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ActionType != "" {
+		query = query.Where("action_type = ?", filter.ActionType)
+	}
+	if filter.RequestedBy != 0 {
+		query = query.Where("requested_by = ?", filter.RequestedBy)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var approvals []models.AdminApproval
+	if err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&approvals).Error; err != nil {
+		return nil, 0, err
+	}
+	return approvals, total, nil
+}
+
+// Review 审批：通过或拒绝，审批人不能是发起人
+func (s *AdminApprovalService) Review(id, reviewerID uint, approve bool, note string) (*models.AdminApproval, error) {
+	now := models.NowFunc()
+	s.expireStale(now)
+
+	approval, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if approval.RequestedBy == reviewerID {
+		return nil, ErrAdminApprovalSelfReview
+	}
+	if approval.Status == models.AdminApprovalStatusExpired {
+		return nil, ErrAdminApprovalExpired
+	}
+	if approval.Status != models.AdminApprovalStatusPending {
+		return nil, ErrAdminApprovalNotPending
+	}
+
+	status := models.AdminApprovalStatusRejected
+	if approve {
+		status = models.AdminApprovalStatusApproved
+	}
+	note = truncateApprovalText(strings.TrimSpace(note), 500)
+	result := s.db.Model(&models.AdminApproval{}).
+		Where("id = ? AND status = ?", id, models.AdminApprovalStatusPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"reviewed_by": reviewerID,
+			"review_note": note,
+			"reviewed_at": now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrAdminApprovalNotPending
+	}
+	return s.Get(id)
+}
+
+// Cancel 发起人撤回尚未执行的审批单
+func (s *AdminApprovalService) Cancel(id, requesterID uint) (*models.AdminApproval, error) {
+	approval, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if approval.RequestedBy != requesterID {
+		return nil, ErrAdminApprovalNotFound
+	}
+	result := s.db.Model(&models.AdminApproval{}).
+		Where("id = ? AND status IN ?", id, []string{models.AdminApprovalStatusPending, models.AdminApprovalStatusApproved}).
+		Update("status", models.AdminApprovalStatusCancelled)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrAdminApprovalNotPending
+	}
+	return s.Get(id)
+}
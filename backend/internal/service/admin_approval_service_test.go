@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func enableApprovalForTest(t *testing.T, settings config.ApprovalConfig) {
+	t.Helper()
+	cfg := loadTicketAttachmentTestConfig(t)
+	original := cfg.Security.Approval
+	cfg.Security.Approval = settings
+	t.Cleanup(func() { cfg.Security.Approval = original })
+}
+
+func TestApprovalThresholds(t *testing.T) {
+	enableApprovalForTest(t, config.ApprovalConfig{Enabled: true, RefundAmountThreshold: 10000, BulkDeleteThreshold: 20, PriceChangePercent: 50, ExpireMinutes: 60})
+
+	if RefundRequiresApproval(9999) || !RefundRequiresApproval(10000) {
+		t.Fatal("unexpected refund threshold result")
+	}
+	if BulkDeleteRequiresApproval(19) || !BulkDeleteRequiresApproval(20) {
+		t.Fatal("unexpected bulk delete threshold result")
+	}
+	for _, tc := range []struct {
+		before, after int64
+		want          bool
+	}{
+		{1000, 1500, false},
+		{1000, 1501, true},
+		{1000, 499, true},
+		{1000, 1000, false},
+		{0, 100, true},
+	} {
+		if got := PriceChangeRequiresApproval(tc.before, tc.after); got != tc.want {
+			t.Fatalf("PriceChangeRequiresApproval(%d, %d) = %v, want %v", tc.before, tc.after, got, tc.want)
+		}
+	}
+
+	enableApprovalForTest(t, config.ApprovalConfig{Enabled: true})
+	if RefundRequiresApproval(1<<40) || BulkDeleteRequiresApproval(1000) || PriceChangeRequiresApproval(1, 1000) {
+		t.Fatal("expected zero thresholds to disable approval rules")
+	}
+}
+
+func TestAdminApprovalFourEyesFlow(t *testing.T) {
+	enableApprovalForTest(t, config.ApprovalConfig{Enabled: true, RefundAmountThreshold: 1, ExpireMinutes: 60})
+	db := openConcurrentServiceTestDB(t, &models.AdminApproval{})
+	svc := NewAdminApprovalService(db)
+
+	req := AdminApprovalRequest{
+		ActionType:   models.AdminApprovalActionOrderRefund,
+		ResourceType: "order",
+		ResourceID:   "42",
+		Summary:      "Refund order A42",
+		Payload:      map[string]interface{}{"total_amount_minor": 50000, "reason": "damaged"},
+		RequestedBy:  1,
+	}
+
+	pending, err := svc.Authorize(req, 0)
+	if !errors.Is(err, ErrAdminApprovalRequired) || pending == nil || pending.Status != models.AdminApprovalStatusPending {
+		t.Fatalf("expected pending approval, got %+v, %v", pending, err)
+	}
+	// 重复提交复用同一审批单
+	if again, err := svc.Authorize(req, 0); !errors.Is(err, ErrAdminApprovalRequired) || again.ID != pending.ID {
+		t.Fatalf("expected pending approval to be reused, got %+v, %v", again, err)
+	}
+
+	if _, err := svc.Authorize(req, pending.ID); !errors.Is(err, ErrAdminApprovalNotApproved) {
+		t.Fatalf("expected unapproved request to be rejected, got %v", err)
+	}
+	if _, err := svc.Review(pending.ID, 1, true, ""); !errors.Is(err, ErrAdminApprovalSelfReview) {
+		t.Fatalf("expected self review to be rejected, got %v", err)
+	}
+	approved, err := svc.Review(pending.ID, 2, true, "ok")
+	if err != nil || approved.Status != models.AdminApprovalStatusApproved || approved.ReviewedBy == nil || *approved.ReviewedBy != 2 {
+		t.Fatalf("approve: %+v, %v", approved, err)
+	}
+	if _, err := svc.Review(pending.ID, 3, false, ""); !errors.Is(err, ErrAdminApprovalNotPending) {
+		t.Fatalf("expected second review to fail, got %v", err)
+	}
+
+	changed := req
+	changed.Payload = map[string]interface{}{"total_amount_minor": 90000, "reason": "damaged"}
+	if _, err := svc.Authorize(changed, pending.ID); !errors.Is(err, ErrAdminApprovalMismatch) {
+		t.Fatalf("expected changed payload to mismatch, got %v", err)
+	}
+	otherAdmin := req
+	otherAdmin.RequestedBy = 2
+	if _, err := svc.Authorize(otherAdmin, pending.ID); !errors.Is(err, ErrAdminApprovalMismatch) {
+		t.Fatalf("expected other admin to be unable to use approval, got %v", err)
+	}
+
+	reserved, err := svc.Authorize(req, pending.ID)
+	if err != nil || reserved.Status != models.AdminApprovalStatusExecuting || reserved.ExecutedAt != nil {
+		t.Fatalf("reserve: %+v, %v", reserved, err)
+	}
+	if _, err := svc.Authorize(req, pending.ID); !errors.Is(err, ErrAdminApprovalNotPending) {
+		t.Fatalf("expected reserved approval to be unavailable to concurrent requests, got %v", err)
+	}
+	// 操作失败后审批单退回已通过，可重新提交
+	if err := svc.Release(pending.ID); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if released, _ := svc.Get(pending.ID); released.Status != models.AdminApprovalStatusApproved || released.ExecutedAt != nil {
+		t.Fatalf("expected released approval to be approved again, got %+v", released)
+	}
+	if _, err := svc.Authorize(req, pending.ID); err != nil {
+		t.Fatalf("retry after release: %v", err)
+	}
+	if err := svc.Complete(pending.ID); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	if executed, _ := svc.Get(pending.ID); executed.Status != models.AdminApprovalStatusExecuted || executed.ExecutedAt == nil {
+		t.Fatalf("expected executed approval, got %+v", executed)
+	}
+	if err := svc.Release(pending.ID); err != nil {
+		t.Fatalf("release after complete: %v", err)
+	}
+	if _, err := svc.Authorize(req, pending.ID); !errors.Is(err, ErrAdminApprovalNotPending) {
+		t.Fatalf("expected approval to be single use, got %v", err)
+	}
+
+	// 过期的审批单不能再执行
+	expiring, _ := svc.Authorize(req, 0)
+	if _, err := svc.Review(expiring.ID, 2, true, ""); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	db.Model(&models.AdminApproval{}).Where("id = ?", expiring.ID).Update("expires_at", time.Now().Add(-time.Minute))
+	if _, err := svc.Authorize(req, expiring.ID); !errors.Is(err, ErrAdminApprovalExpired) {
+		t.Fatalf("expected expired approval, got %v", err)
+	}
+
+	cancelled, _ := svc.Authorize(req, 0)
+	if _, err := svc.Cancel(cancelled.ID, 2); !errors.Is(err, ErrAdminApprovalNotFound) {
+		t.Fatalf("expected only requester to cancel, got %v", err)
+	}
+	if result, err := svc.Cancel(cancelled.ID, 1); err != nil || result.Status != models.AdminApprovalStatusCancelled {
+		t.Fatalf("cancel: %+v, %v", result, err)
+	}
+
+	approvals, total, err := svc.List(AdminApprovalFilter{RequestedBy: 1}, 1, 20)
+	if err != nil || total != 3 || approvals[0].ID != cancelled.ID {
+		t.Fatalf("list approvals: %d, %v", total, err)
+	}
+	if _, total, _ := svc.List(AdminApprovalFilter{Status: models.AdminApprovalStatusExpired}, 1, 20); total != 1 {
+		t.Fatalf("expected one expired approval, got %d", total)
+	}
+}
@@ -0,0 +1,561 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/demomode"
+	"auralogic/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+const (
+	maxAdminNotificationSubscriptions = 50
+	maxAdminNotificationFilterValues  = 50
+	adminNotificationLowStockCooldown = 6 * time.Hour // 同一库存在冷却期内只通知一次
+	telegramAPIBaseURL                = "https://api.telegram.org"
+	telegramHTTPTimeout               = 10 * time.Second
+	telegramResponseBodyLimit         = 1024
+)
+
+var (
+	ErrAdminNotificationSubscriptionNotFound = bizerr.New("notification.subscriptionNotFound", "Notification subscription not found")
+	ErrAdminNotificationNotFound             = bizerr.New("notification.notFound", "Notification not found")
+)
+
+// AdminNotificationSubscriptionInput 创建/更新订阅参数
+type AdminNotificationSubscriptionInput struct {
+	Event          string   `json:"event"`
+	Channel        string   `json:"channel"`
+	Countries      []string `json:"countries"`
+	Categories     []string `json:"categories"`
+	TelegramChatID string   `json:"telegram_chat_id"`
+	Enabled        bool     `json:"enabled"`
+}
+
+// AdminNotificationMessage 待分发的事件通知；Country/Categories 用于匹配订阅的筛选条件
+type AdminNotificationMessage struct {
+	Event      string
+	Country    string
+	Categories []string
+	Link       string // 管理后台相对路径
+	OrderID    *uint
+	// Render 按管理员语言生成标题与正文
+	Render func(locale string) (title string, content string)
+}
+
+// AdminNotificationService 管理员个人通知订阅：按事件、国家、分类筛选后通过邮件、Telegram 或站内信送达
+type AdminNotificationService struct {
+	db              *gorm.DB
+	emailService    *EmailService
+	appURL          string
+	httpClient      *http.Client
+	telegramAPIBase string
+	telegramToken   func() string
+
+	lowStockMu       sync.Mutex
+	lowStockNotified map[uint]time.Time
+}
+
+func NewAdminNotificationService(db *gorm.DB, emailService *EmailService, appURL string) *AdminNotificationService {
+	return &AdminNotificationService{
+		db:              db,
+		emailService:    emailService,
+		appURL:          strings.TrimRight(appURL, "/"),
+		httpClient:      &http.Client{Timeout: telegramHTTPTimeout, Transport: demomode.Transport(nil)},
+		telegramAPIBase: telegramAPIBaseURL,
+		telegramToken: func() string {
+			if cfg := config.GetConfig(); cfg != nil {
+				return strings.TrimSpace(cfg.Telegram.BotToken)
+			}
+			return ""
+		},
+		lowStockNotified: make(map[uint]time.Time),
+	}
+}
+
+// AdminNotificationEvents 可订阅的事件：出站 Webhook 的订单/工单事件加上低库存告警
+func AdminNotificationEvents() []string {
+	events := make([]string, 0, len(WebhookEvents())+1)
+	for _, event := range WebhookEvents() {
+		events = append(events, string(event))
+	}
+	return append(events, models.AdminNotificationEventLowStock)
+}
+
+// AdminNotificationChannels 可选的通知渠道
+func AdminNotificationChannels() []string {
+	return []string{
+		models.AdminNotificationChannelEmail,
+		models.AdminNotificationChannelTelegram,
+		models.AdminNotificationChannelInApp,
+	}
+}
+
+// AdminNotificationEventPermission 订阅事件所需的查看权限
+func AdminNotificationEventPermission(event string) string {
+	switch {
+	case strings.HasPrefix(event, "order."):
+		return "order.view"
+	case strings.HasPrefix(event, "ticket."):
+		return "ticket.view"
+	case event == models.AdminNotificationEventLowStock:
+		return "product.view"
+	}
+	return ""
+}
+
+// TelegramConfigured 是否配置了 Telegram 机器人
+func (s *AdminNotificationService) TelegramConfigured() bool {
+	return s.telegramToken() != ""
+}
+
+func normalizeNotificationFilterValues(values []string, upper bool) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, raw := range values {
+		value := strings.TrimSpace(raw)
+		if upper {
+			value = strings.ToUpper(value)
+		}
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
+
+func (s *AdminNotificationService) validateInput(input *AdminNotificationSubscriptionInput) error {
+	input.Event = strings.TrimSpace(input.Event)
+	input.Channel = strings.TrimSpace(input.Channel)
+	input.TelegramChatID = strings.TrimSpace(input.TelegramChatID)
+	if AdminNotificationEventPermission(input.Event) == "" || !containsString(AdminNotificationEvents(), input.Event) {
+		return bizerr.Newf("notification.eventInvalid", "Unknown notification event: %s", input.Event).
+			WithParams(map[string]interface{}{"event": input.Event})
+	}
+	if !containsString(AdminNotificationChannels(), input.Channel) {
+		return bizerr.Newf("notification.channelInvalid", "Unknown notification channel: %s", input.Channel).
+			WithParams(map[string]interface{}{"channel": input.Channel})
+	}
+	if input.Channel == models.AdminNotificationChannelTelegram {
+		if !s.TelegramConfigured() {
+			return bizerr.New("notification.telegramNotConfigured", "Telegram bot token is not configured")
+		}
+		if input.TelegramChatID == "" || len(input.TelegramChatID) > 64 {
+			return bizerr.New("notification.telegramChatIDRequired", "Telegram chat ID is required")
+		}
+	} else {
+		input.TelegramChatID = ""
+	}
+
+	input.Countries = normalizeNotificationFilterValues(input.Countries, true)
+	input.Categories = normalizeNotificationFilterValues(input.Categories, false)
+	if len(input.Countries) > maxAdminNotificationFilterValues || len(input.Categories) > maxAdminNotificationFilterValues {
+		return bizerr.Newf("notification.filterTooLarge", "At most %d filter values are allowed", maxAdminNotificationFilterValues).
+			WithParams(map[string]interface{}{"max": maxAdminNotificationFilterValues})
+	}
+	isOrderEvent := strings.HasPrefix(input.Event, "order.")
+	if len(input.Countries) > 0 && !isOrderEvent {
+		return bizerr.New("notification.countryFilterInvalid", "Country filter only applies to order events")
+	}
+	if len(input.Categories) > 0 && isOrderEvent {
+		return bizerr.New("notification.categoryFilterInvalid", "Category filter only applies to ticket and low stock events")
+	}
+	return nil
+}
+
+// ListSubscriptions 当前管理员的订阅
+func (s *AdminNotificationService) ListSubscriptions(adminID uint) ([]models.AdminNotificationSubscription, error) {
+	var subscriptions []models.AdminNotificationSubscription
+	err := s.db.Where("admin_id = ?", adminID).Order("id ASC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (s *AdminNotificationService) getSubscription(adminID, id uint) (*models.AdminNotificationSubscription, error) {
+	var subscription models.AdminNotificationSubscription
+	if err := s.db.Where("id = ? AND admin_id = ?", id, adminID).First(&subscription).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAdminNotificationSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// CreateSubscription 新建订阅
+func (s *AdminNotificationService) CreateSubscription(adminID uint, input AdminNotificationSubscriptionInput) (*models.AdminNotificationSubscription, error) {
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	var count int64
+	if err := s.db.Model(&models.AdminNotificationSubscription{}).Where("admin_id = ?", adminID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count >= maxAdminNotificationSubscriptions {
+		return nil, bizerr.Newf("notification.tooManySubscriptions", "At most %d notification subscriptions are allowed", maxAdminNotificationSubscriptions).
+			WithParams(map[string]interface{}{"max": maxAdminNotificationSubscriptions})
+	}
+	subscription := &models.AdminNotificationSubscription{
+		AdminID:        adminID,
+		Event:          input.Event,
+		Channel:        input.Channel,
+		Countries:      input.Countries,
+		Categories:     input.Categories,
+		TelegramChatID: input.TelegramChatID,
+		Enabled:        input.Enabled,
+	}
+	if err := s.db.Create(subscription).Error; err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// UpdateSubscription 更新订阅
+func (s *AdminNotificationService) UpdateSubscription(adminID, id uint, input AdminNotificationSubscriptionInput) (*models.AdminNotificationSubscription, error) {
+	subscription, err := s.getSubscription(adminID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	subscription.Event = input.Event
+	subscription.Channel = input.Channel
+	subscription.Countries = input.Countries
+	subscription.Categories = input.Categories
+	subscription.TelegramChatID = input.TelegramChatID
+	subscription.Enabled = input.Enabled
+	if err := s.db.Model(subscription).
+		Select("event", "channel", "countries", "categories", "telegram_chat_id", "enabled").
+		Updates(subscription).Error; err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// DeleteSubscription 删除订阅
+func (s *AdminNotificationService) DeleteSubscription(adminID, id uint) error {
+	result := s.db.Where("id = ? AND admin_id = ?", id, adminID).Delete(&models.AdminNotificationSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAdminNotificationSubscriptionNotFound
+	}
+	return nil
+}
+
+// ListNotifications 站内通知，按时间倒序
+func (s *AdminNotificationService) ListNotifications(adminID uint, unreadOnly bool, page, limit int) ([]models.AdminNotification, int64, error) {
+	query := s.db.Model(&models.AdminNotification{}).Where("admin_id = ?", adminID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var notifications []models.AdminNotification
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&notifications).Error
+	return notifications, total, err
+}
+
+// UnreadCount 未读站内通知数
+func (s *AdminNotificationService) UnreadCount(adminID uint) (int64, error) {
+	var count int64
+	err := s.db.Model(&models.AdminNotification{}).Where("admin_id = ? AND read_at IS NULL", adminID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead 标记单条站内通知为已读
+func (s *AdminNotificationService) MarkRead(adminID, id uint) error {
+	var notification models.AdminNotification
+	if err := s.db.Where("id = ? AND admin_id = ?", id, adminID).First(&notification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAdminNotificationNotFound
+		}
+		return err
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return s.db.Model(&notification).Update("read_at", models.NowFunc()).Error
+}
+
+// MarkAllRead 全部标记为已读，返回更新条数
+func (s *AdminNotificationService) MarkAllRead(adminID uint) (int64, error) {
+	result := s.db.Model(&models.AdminNotification{}).
+		Where("admin_id = ? AND read_at IS NULL", adminID).
+		Update("read_at", models.NowFunc())
+	return result.RowsAffected, result.Error
+}
+
+// ObserveHook 订单/工单/库存 Hook 事件转换为管理员通知，异步分发
+func (s *AdminNotificationService) ObserveHook(hook string, payload map[string]interface{}) {
+	for _, event := range orderEventsForHook(hook, payload) {
+		if orderID, ok := orderAutomationPayloadOrderID(payload["order_id"]); ok {
+			s.dispatchAsync(func() (*AdminNotificationMessage, error) { return s.buildOrderMessage(string(event), orderID) })
+		}
+	}
+	switch hook {
+	case "ticket.create.after", "ticket.message.user.after":
+		event := string(models.WebhookEventTicketCreated)
+		if hook == "ticket.message.user.after" {
+			event = string(models.WebhookEventTicketReplied)
+		}
+		if ticketID, ok := orderAutomationPayloadOrderID(payload["ticket_id"]); ok {
+			s.dispatchAsync(func() (*AdminNotificationMessage, error) { return s.buildTicketMessage(event, ticketID) })
+		}
+	case "inventory.reserve.after", "inventory.adjust.after", "inventory.update.after":
+		if success, exists := payload["success"]; exists && success != true {
+			return
+		}
+		if inventoryID, ok := orderAutomationPayloadOrderID(payload["inventory_id"]); ok {
+			s.dispatchAsync(func() (*AdminNotificationMessage, error) { return s.buildLowStockMessage(inventoryID) })
+		}
+	}
+}
+
+func (s *AdminNotificationService) dispatchAsync(build func() (*AdminNotificationMessage, error)) {
+	go func() {
+		defer recoverBackgroundServicePanic("admin-notification")
+		message, err := build()
+		if err != nil {
+			log.Printf("admin notification build failed: %v", err)
+			return
+		}
+		if message == nil {
+			return
+		}
+		if _, err := s.Notify(message); err != nil {
+			log.Printf("admin notification dispatch failed: event=%s err=%v", message.Event, err)
+		}
+	}()
+}
+
+func (s *AdminNotificationService) buildOrderMessage(event string, orderID uint) (*AdminNotificationMessage, error) {
+	var order models.Order
+	if err := s.db.First(&order, orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	amount := money.MinorToString(order.TotalAmount) + " " + order.Currency
+	return &AdminNotificationMessage{
+		Event:   event,
+		Country: strings.ToUpper(strings.TrimSpace(order.ReceiverCountry)),
+		Link:    fmt.Sprintf("/admin/orders/%d", order.ID),
+		OrderID: &order.ID,
+		Render: func(locale string) (string, string) {
+			if locale == "zh" {
+				return fmt.Sprintf("[订单 %s] %s", event, order.OrderNo),
+					fmt.Sprintf("订单号: %s\n状态: %s\n金额: %s\n收货国家: %s", order.OrderNo, order.Status, amount, order.ReceiverCountry)
+			}
+			return fmt.Sprintf("[Order %s] %s", event, order.OrderNo),
+				fmt.Sprintf("Order: %s\nStatus: %s\nAmount: %s\nCountry: %s", order.OrderNo, order.Status, amount, order.ReceiverCountry)
+		},
+	}, nil
+}
+
+func (s *AdminNotificationService) buildTicketMessage(event string, ticketID uint) (*AdminNotificationMessage, error) {
+	var ticket models.Ticket
+	if err := s.db.First(&ticket, ticketID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &AdminNotificationMessage{
+		Event:      event,
+		Categories: []string{ticket.Category},
+		Link:       fmt.Sprintf("/admin/tickets?ticket=%d", ticket.ID),
+		Render: func(locale string) (string, string) {
+			if locale == "zh" {
+				return fmt.Sprintf("[工单 %s] %s", event, ticket.TicketNo),
+					fmt.Sprintf("工单号: %s\n标题: %s\n分类: %s", ticket.TicketNo, ticket.Subject, ticket.Category)
+			}
+			return fmt.Sprintf("[Ticket %s] %s", event, ticket.TicketNo),
+				fmt.Sprintf("Ticket: %s\nSubject: %s\nCategory: %s", ticket.TicketNo, ticket.Subject, ticket.Category)
+		},
+	}, nil
+}
+
+// buildLowStockMessage 库存低于安全库存时生成通知，冷却期内重复触发返回 nil
+func (s *AdminNotificationService) buildLowStockMessage(inventoryID uint) (*AdminNotificationMessage, error) {
+	var inventory models.Inventory
+	if err := s.db.Preload("ProductBindings.Product").First(&inventory, inventoryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !inventory.IsActive || !inventory.IsLowStock() {
+		s.lowStockMu.Lock()
+		delete(s.lowStockNotified, inventory.ID)
+		s.lowStockMu.Unlock()
+		return nil, nil
+	}
+	now := models.NowFunc()
+	s.lowStockMu.Lock()
+	if last, ok := s.lowStockNotified[inventory.ID]; ok && now.Sub(last) < adminNotificationLowStockCooldown {
+		s.lowStockMu.Unlock()
+		return nil, nil
+	}
+	s.lowStockNotified[inventory.ID] = now
+	s.lowStockMu.Unlock()
+
+	var categories, products []string
+	for _, binding := range inventory.ProductBindings {
+		if binding.Product == nil {
+			continue
+		}
+		products = append(products, binding.Product.Name)
+		if binding.Product.Category != "" {
+			categories = append(categories, binding.Product.Category)
+		}
+	}
+	remaining := inventory.GetRemainingStock()
+	return &AdminNotificationMessage{
+		Event:      models.AdminNotificationEventLowStock,
+		Categories: categories,
+		Link:       fmt.Sprintf("/admin/inventories/%d", inventory.ID),
+		Render: func(locale string) (string, string) {
+			if locale == "zh" {
+				return fmt.Sprintf("[低库存] %s", inventory.Name),
+					fmt.Sprintf("库存: %s (SKU %s)\n剩余: %d，安全库存: %d\n商品: %s", inventory.Name, inventory.SKU, remaining, inventory.SafetyStock, strings.Join(products, ", "))
+			}
+			return fmt.Sprintf("[Low stock] %s", inventory.Name),
+				fmt.Sprintf("Inventory: %s (SKU %s)\nRemaining: %d, safety stock: %d\nProducts: %s", inventory.Name, inventory.SKU, remaining, inventory.SafetyStock, strings.Join(products, ", "))
+		},
+	}, nil
+}
+
+// adminNotificationMatches 订阅的筛选条件是否命中该消息
+func adminNotificationMatches(subscription *models.AdminNotificationSubscription, message *AdminNotificationMessage) bool {
+	if len(subscription.Countries) > 0 && !containsString(subscription.Countries, message.Country) {
+		return false
+	}
+	if len(subscription.Categories) > 0 {
+		for _, category := range message.Categories {
+			if containsString(subscription.Categories, category) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Notify 将消息送达所有命中的订阅，返回成功送达的订阅数；单个渠道失败只记录日志
+func (s *AdminNotificationService) Notify(message *AdminNotificationMessage) (int, error) {
+	var subscriptions []models.AdminNotificationSubscription
+	if err := s.db.Where("event = ? AND enabled = ?", message.Event, true).Order("id ASC").Find(&subscriptions).Error; err != nil {
+		return 0, err
+	}
+	matched := make([]models.AdminNotificationSubscription, 0, len(subscriptions))
+	adminIDs := make([]uint, 0, len(subscriptions))
+	for i := range subscriptions {
+		if adminNotificationMatches(&subscriptions[i], message) {
+			matched = append(matched, subscriptions[i])
+			adminIDs = append(adminIDs, subscriptions[i].AdminID)
+		}
+	}
+	if len(matched) == 0 {
+		return 0, nil
+	}
+
+	// 已停用或被降级的管理员不再接收通知
+	var admins []models.User
+	if err := s.db.Where("id IN ? AND role IN ? AND is_active = ?", adminIDs, []string{"admin", "super_admin"}, true).
+		Find(&admins).Error; err != nil {
+		return 0, err
+	}
+	adminByID := make(map[uint]*models.User, len(admins))
+	for i := range admins {
+		adminByID[admins[i].ID] = &admins[i]
+	}
+
+	delivered := 0
+	for i := range matched {
+		admin := adminByID[matched[i].AdminID]
+		if admin == nil {
+			continue
+		}
+		if err := s.deliver(&matched[i], admin, message); err != nil {
+			log.Printf("admin notification delivery failed: subscription=%d channel=%s err=%v", matched[i].ID, matched[i].Channel, err)
+			continue
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+func (s *AdminNotificationService) deliver(subscription *models.AdminNotificationSubscription, admin *models.User, message *AdminNotificationMessage) error {
+	title, content := message.Render(resolveLocale(admin.Locale))
+	switch subscription.Channel {
+	case models.AdminNotificationChannelInApp:
+		return s.db.Create(&models.AdminNotification{
+			AdminID:        admin.ID,
+			SubscriptionID: &subscription.ID,
+			Event:          message.Event,
+			Title:          title,
+			Content:        content,
+			Link:           message.Link,
+		}).Error
+	case models.AdminNotificationChannelEmail:
+		if s.emailService == nil || admin.Email == "" {
+			return errors.New("email is not available")
+		}
+		adminID := admin.ID
+		return s.emailService.QueueEmail(admin.Email, title, content+"\n\n"+s.appURL+message.Link, "admin.notification", message.OrderID, &adminID)
+	case models.AdminNotificationChannelTelegram:
+		return s.sendTelegram(subscription.TelegramChatID, title+"\n\n"+content+"\n\n"+s.appURL+message.Link)
+	}
+	return fmt.Errorf("unknown channel %s", subscription.Channel)
+}
+
+// sendTelegram 通过 Bot API sendMessage 发送纯文本消息
+func (s *AdminNotificationService) sendTelegram(chatID, text string) error {
+	token := s.telegramToken()
+	if token == "" {
+		return errors.New("telegram bot token is not configured")
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := s.httpClient.Post(s.telegramAPIBase+"/bot"+token+"/sendMessage", "application/json", bytes.NewReader(body))
+	if err != nil {
+		// url.Error 会带上包含 Token 的完整地址，只保留底层错误
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, telegramResponseBodyLimit))
+		return fmt.Errorf("telegram responded %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
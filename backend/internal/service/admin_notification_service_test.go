@@ -0,0 +1,150 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestAdminNotificationRoutesByFiltersAndChannel(t *testing.T) {
+	var mu sync.Mutex
+	var telegramMessages []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bottest-token/sendMessage" {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		telegramMessages = append(telegramMessages, body)
+		mu.Unlock()
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.AdminNotificationSubscription{}, &models.AdminNotification{})
+	svc := NewAdminNotificationService(db, nil, "https://shop.example.com/")
+	svc.httpClient = server.Client()
+	svc.telegramAPIBase = server.URL
+	svc.telegramToken = func() string { return "" }
+
+	zhAdmin := models.User{UUID: "admin-zh", Email: "zh@example.com", Role: "admin", IsActive: true, Locale: "zh"}
+	enAdmin := models.User{UUID: "admin-en", Email: "en@example.com", Role: "super_admin", IsActive: true}
+	disabled := models.User{UUID: "admin-off", Email: "off@example.com", Role: "admin", IsActive: true}
+	for _, user := range []*models.User{&zhAdmin, &enAdmin, &disabled} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	db.Model(&disabled).Update("is_active", false)
+
+	if _, err := svc.CreateSubscription(enAdmin.ID, AdminNotificationSubscriptionInput{Event: "order.created", Channel: "telegram", TelegramChatID: "42"}); refundErrorKey(err) != "notification.telegramNotConfigured" {
+		t.Fatalf("telegram requires a bot token, got %v", err)
+	}
+	svc.telegramToken = func() string { return "test-token" }
+	if _, err := svc.CreateSubscription(enAdmin.ID, AdminNotificationSubscriptionInput{Event: "order.created", Channel: "telegram"}); refundErrorKey(err) != "notification.telegramChatIDRequired" {
+		t.Fatalf("telegram requires a chat id, got %v", err)
+	}
+	if _, err := svc.CreateSubscription(enAdmin.ID, AdminNotificationSubscriptionInput{Event: "ticket.created", Channel: "in_app", Countries: []string{"US"}}); refundErrorKey(err) != "notification.countryFilterInvalid" {
+		t.Fatalf("country filter only applies to orders, got %v", err)
+	}
+	if _, err := svc.CreateSubscription(enAdmin.ID, AdminNotificationSubscriptionInput{Event: "order.exploded", Channel: "in_app"}); refundErrorKey(err) != "notification.eventInvalid" {
+		t.Fatalf("unknown events should be rejected, got %v", err)
+	}
+
+	inApp, err := svc.CreateSubscription(zhAdmin.ID, AdminNotificationSubscriptionInput{Event: "order.created", Channel: "in_app", Countries: []string{" us ", "US"}, Enabled: true})
+	if err != nil || len(inApp.Countries) != 1 || inApp.Countries[0] != "US" {
+		t.Fatalf("create in-app subscription: %+v, %v", inApp, err)
+	}
+	subscriptions := []AdminNotificationSubscriptionInput{
+		{Event: "order.created", Channel: "telegram", TelegramChatID: "42", Countries: []string{"cn"}, Enabled: true},
+		{Event: "ticket.created", Channel: "in_app", Categories: []string{"billing"}, Enabled: true},
+		{Event: "order.created", Channel: "in_app", Enabled: false},
+	}
+	for _, input := range subscriptions {
+		if _, err := svc.CreateSubscription(enAdmin.ID, input); err != nil {
+			t.Fatalf("create subscription %+v: %v", input, err)
+		}
+	}
+	if _, err := svc.CreateSubscription(disabled.ID, AdminNotificationSubscriptionInput{Event: "order.created", Channel: "in_app", Enabled: true}); err != nil {
+		t.Fatalf("create subscription for disabled admin: %v", err)
+	}
+
+	render := func(locale string) (string, string) { return "title-" + locale, "body" }
+	if delivered, err := svc.Notify(&AdminNotificationMessage{Event: "order.created", Country: "US", Link: "/admin/orders/1", Render: render}); err != nil || delivered != 1 {
+		t.Fatalf("US order should reach only the zh admin in-app, got %d, %v", delivered, err)
+	}
+	if delivered, err := svc.Notify(&AdminNotificationMessage{Event: "order.created", Country: "CN", Link: "/admin/orders/2", Render: render}); err != nil || delivered != 1 {
+		t.Fatalf("CN order should go to telegram, got %d, %v", delivered, err)
+	}
+	if len(telegramMessages) != 1 || telegramMessages[0]["chat_id"] != "42" ||
+		!strings.Contains(telegramMessages[0]["text"].(string), "https://shop.example.com/admin/orders/2") {
+		t.Fatalf("unexpected telegram messages: %+v", telegramMessages)
+	}
+	if delivered, _ := svc.Notify(&AdminNotificationMessage{Event: "ticket.created", Categories: []string{"shipping"}, Render: render}); delivered != 0 {
+		t.Fatalf("ticket outside subscribed categories must be skipped, got %d", delivered)
+	}
+	if delivered, _ := svc.Notify(&AdminNotificationMessage{Event: "ticket.created", Categories: []string{"billing"}, Render: render}); delivered != 1 {
+		t.Fatalf("ticket in subscribed category should be delivered, got %d", delivered)
+	}
+
+	items, total, err := svc.ListNotifications(zhAdmin.ID, true, 1, 20)
+	if err != nil || total != 1 || items[0].Title != "title-zh" || items[0].Link != "/admin/orders/1" {
+		t.Fatalf("unexpected inbox: %+v total=%d err=%v", items, total, err)
+	}
+	if err := svc.MarkRead(enAdmin.ID, items[0].ID); err != ErrAdminNotificationNotFound {
+		t.Fatalf("admins must not read each other's notifications, got %v", err)
+	}
+	if err := svc.MarkRead(zhAdmin.ID, items[0].ID); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if count, _ := svc.UnreadCount(zhAdmin.ID); count != 0 {
+		t.Fatalf("expected no unread notifications, got %d", count)
+	}
+	if updated, err := svc.MarkAllRead(enAdmin.ID); err != nil || updated != 1 {
+		t.Fatalf("mark all read: %d, %v", updated, err)
+	}
+	if err := svc.DeleteSubscription(enAdmin.ID, inApp.ID); err != ErrAdminNotificationSubscriptionNotFound {
+		t.Fatalf("admins must not delete each other's subscriptions, got %v", err)
+	}
+}
+
+func TestAdminNotificationLowStockUsesProductCategoryAndCooldown(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Product{}, &models.Inventory{}, &models.ProductInventoryBinding{})
+	svc := NewAdminNotificationService(db, nil, "")
+
+	product := models.Product{SKU: "KB-1", Name: "Keyboard", Category: "Keyboards"}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	inventory := models.Inventory{Name: "Keyboard / Black", Stock: 3, AvailableQuantity: 3, SafetyStock: 5, IsActive: true}
+	if err := db.Create(&inventory).Error; err != nil {
+		t.Fatalf("create inventory: %v", err)
+	}
+	if err := db.Create(&models.ProductInventoryBinding{ProductID: product.ID, InventoryID: inventory.ID, AttributesHash: "black"}).Error; err != nil {
+		t.Fatalf("create binding: %v", err)
+	}
+
+	message, err := svc.buildLowStockMessage(inventory.ID)
+	if err != nil || message == nil || len(message.Categories) != 1 || message.Categories[0] != "Keyboards" {
+		t.Fatalf("expected low stock message with product category, got %+v, %v", message, err)
+	}
+	if message, _ := svc.buildLowStockMessage(inventory.ID); message != nil {
+		t.Fatalf("repeated low stock alerts must respect the cooldown")
+	}
+
+	db.Model(&inventory).Update("stock", 100)
+	if message, _ := svc.buildLowStockMessage(inventory.ID); message != nil {
+		t.Fatalf("restocked inventory must not alert")
+	}
+	db.Model(&inventory).Update("stock", 2)
+	if message, _ := svc.buildLowStockMessage(inventory.ID); message == nil {
+		t.Fatalf("restocking should reset the cooldown")
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+)
+
+const (
+	adminRecentEntityLogScan     = 200
+	adminRecentEntityDefaultSize = 8
+	adminAssignedTicketLimit     = 10
+)
+
+// AdminQuickAction 命令面板中的快捷操作，文案由前端按 ID 翻译
+type AdminQuickAction struct {
+	ID             string `json:"id"`
+	Link           string `json:"link"`
+	Permission     string `json:"-"`
+	SuperAdminOnly bool   `json:"-"`
+}
+
+// adminQuickActions 快捷操作目录，按展示顺序排列
+var adminQuickActions = []AdminQuickAction{
+	{ID: "pending_shipment", Link: "/admin/orders?status=pending", Permission: "order.view"},
+	{ID: "create_product", Link: "/admin/products/new", Permission: "product.edit"},
+	{ID: "tickets", Link: "/admin/tickets", Permission: "ticket.view"},
+	{ID: "return_requests", Link: "/admin/returns", Permission: "return.view"},
+	{ID: "users", Link: "/admin/users", Permission: "user.view"},
+	{ID: "serials", Link: "/admin/serials", Permission: "serial.view"},
+	{ID: "announcements", Link: "/admin/announcements", Permission: "announcement.edit"},
+	{ID: "operation_logs", Link: "/admin/logs", Permission: "system.logs"},
+	{ID: "dashboard", Link: "/admin/dashboard", SuperAdminOnly: true},
+}
+
+// AdminQuickActionsFor 过滤出当前管理员可用的快捷操作
+func AdminQuickActionsFor(hasPermission func(permission string) bool, superAdmin bool) []AdminQuickAction {
+	actions := make([]AdminQuickAction, 0, len(adminQuickActions))
+	for _, action := range adminQuickActions {
+		if action.SuperAdminOnly && !superAdmin {
+			continue
+		}
+		if action.Permission != "" && !hasPermission(action.Permission) {
+			continue
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+type adminRecentEntityRef struct {
+	Type string
+	ID   uint
+}
+
+// RecentEntities 根据操作日志返回管理员最近处理过的实体（按最近一次操作倒序、去重）。
+// types 为有查看权限的类型，只支持订单/用户/商品；已删除或不在店铺范围内的实体不返回
+func (s *AdminSearchService) RecentEntities(adminID uint, types []string, scope *repository.StoreScope, limit int) ([]AdminSearchResult, error) {
+	results := []AdminSearchResult{}
+	if limit <= 0 {
+		limit = adminRecentEntityDefaultSize
+	}
+	resourceTypes := make([]string, 0, len(types))
+	for _, searchType := range types {
+		switch searchType {
+		case AdminSearchTypeOrder, AdminSearchTypeUser, AdminSearchTypeProduct:
+			resourceTypes = append(resourceTypes, searchType)
+		}
+	}
+	if len(resourceTypes) == 0 {
+		return results, nil
+	}
+
+	var logs []models.OperationLog
+	if err := s.db.Select("resource_type", "resource_id").
+		Where("user_id = ? AND resource_type IN ? AND resource_id IS NOT NULL", adminID, resourceTypes).
+		Order("id DESC").
+		Limit(adminRecentEntityLogScan).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	refs := make([]adminRecentEntityRef, 0, limit)
+	seen := map[adminRecentEntityRef]bool{}
+	idsByType := map[string][]uint{}
+	for _, log := range logs {
+		ref := adminRecentEntityRef{Type: log.ResourceType, ID: *log.ResourceID}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+		idsByType[ref.Type] = append(idsByType[ref.Type], ref.ID)
+	}
+
+	resolved := map[adminRecentEntityRef]AdminSearchResult{}
+	if ids := idsByType[AdminSearchTypeOrder]; len(ids) > 0 {
+		var orders []models.Order
+		if err := scope.Apply(s.db.Model(&models.Order{})).
+			Select("id", "order_no", "status", "tracking_no", "created_at").
+			Where("id IN ?", ids).
+			Find(&orders).Error; err != nil {
+			return nil, err
+		}
+		for _, order := range orders {
+			resolved[adminRecentEntityRef{Type: AdminSearchTypeOrder, ID: order.ID}] = orderSearchResult(order)
+		}
+	}
+	if ids := idsByType[AdminSearchTypeUser]; len(ids) > 0 {
+		var users []models.User
+		if err := s.db.Select("id", "email", "name", "role", "created_at").Where("id IN ?", ids).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			resolved[adminRecentEntityRef{Type: AdminSearchTypeUser, ID: user.ID}] = userSearchResult(user)
+		}
+	}
+	if ids := idsByType[AdminSearchTypeProduct]; len(ids) > 0 {
+		var products []models.Product
+		if err := scope.Apply(s.db.Model(&models.Product{})).
+			Select("id", "name", "sku", "status", "created_at").
+			Where("id IN ?", ids).
+			Find(&products).Error; err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			resolved[adminRecentEntityRef{Type: AdminSearchTypeProduct, ID: product.ID}] = productSearchResult(product)
+		}
+	}
+
+	for _, ref := range refs {
+		result, ok := resolved[ref]
+		if !ok {
+			continue
+		}
+		results = append(results, result)
+		if len(results) >= limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+// AssignedTickets 分配给该管理员且未解决/关闭的工单，最近有消息的优先
+func (s *AdminSearchService) AssignedTickets(adminID uint) ([]AdminSearchResult, error) {
+	var tickets []models.Ticket
+	if err := s.db.Model(&models.Ticket{}).
+		Select("id", "ticket_no", "subject", "status", "created_at").
+		Where("assigned_to = ? AND status IN ?", adminID, []models.TicketStatus{models.TicketStatusOpen, models.TicketStatusProcessing}).
+		Order("last_message_at IS NULL, last_message_at DESC, id DESC").
+		Limit(adminAssignedTicketLimit).
+		Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	results := make([]AdminSearchResult, 0, len(tickets))
+	for _, ticket := range tickets {
+		results = append(results, ticketSearchResult(ticket))
+	}
+	return results, nil
+}
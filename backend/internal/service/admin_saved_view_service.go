@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/listfilter"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	maxAdminSavedViewsPerResource = 50
+	maxAdminSavedViewParams       = 30
+	maxAdminSavedViewParamLength  = 500
+)
+
+var ErrAdminSavedViewNotFound = bizerr.New("savedView.notFound", "Saved view not found")
+
+// AdminSavedViewInput 创建/更新视图参数（resource 创建后不可修改）
+type AdminSavedViewInput struct {
+	Resource  string             `json:"resource"`
+	Name      string             `json:"name"`
+	Params    map[string]string  `json:"params"`
+	Filter    *listfilter.Filter `json:"filter"`
+	IsDefault bool               `json:"is_default"`
+	SortOrder int                `json:"sort_order"`
+}
+
+// AdminSavedViewService 管理员个人的列表视图
+type AdminSavedViewService struct {
+	db *gorm.DB
+}
+
+func NewAdminSavedViewService(db *gorm.DB) *AdminSavedViewService {
+	return &AdminSavedViewService{db: db}
+}
+
+// ValidateAdminSavedViewResource 校验列表资源名并返回其筛选字段
+func ValidateAdminSavedViewResource(resource string) (listfilter.Schema, error) {
+	schema := repository.ListFilterSchemaFor(resource)
+	if schema == nil {
+		return nil, bizerr.Newf("savedView.resourceInvalid", "Unsupported list: %s", resource).
+			WithParams(map[string]interface{}{"resource": resource})
+	}
+	return schema, nil
+}
+
+func (s *AdminSavedViewService) validateInput(input *AdminSavedViewInput, schema listfilter.Schema) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" || len([]rune(input.Name)) > 100 {
+		return bizerr.New("savedView.nameRequired", "View name is required (max 100 characters)")
+	}
+	if len(input.Params) > maxAdminSavedViewParams {
+		return bizerr.New("savedView.paramsInvalid", "Too many view parameters")
+	}
+	params := make(map[string]string, len(input.Params))
+	for key, value := range input.Params {
+		key = strings.TrimSpace(key)
+		if key == "" || len(key) > 50 || len(value) > maxAdminSavedViewParamLength {
+			return bizerr.New("savedView.paramsInvalid", "Invalid view parameters")
+		}
+		// 分页和筛选条件不作为普通参数保存
+		if key == "page" || key == "limit" || key == "filter" {
+			continue
+		}
+		params[key] = value
+	}
+	input.Params = params
+	return listfilter.Validate(input.Filter, schema)
+}
+
+// List 当前管理员在某个列表下的视图
+func (s *AdminSavedViewService) List(adminID uint, resource string) ([]models.AdminSavedView, error) {
+	if _, err := ValidateAdminSavedViewResource(resource); err != nil {
+		return nil, err
+	}
+	var views []models.AdminSavedView
+	err := s.db.Where("admin_id = ? AND resource = ?", adminID, resource).
+		Order("sort_order ASC, id ASC").
+		Find(&views).Error
+	return views, err
+}
+
+func (s *AdminSavedViewService) get(adminID, id uint) (*models.AdminSavedView, error) {
+	var view models.AdminSavedView
+	if err := s.db.Where("id = ? AND admin_id = ?", id, adminID).First(&view).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAdminSavedViewNotFound
+		}
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (s *AdminSavedViewService) Create(adminID uint, input AdminSavedViewInput) (*models.AdminSavedView, error) {
+	input.Resource = strings.TrimSpace(input.Resource)
+	schema, err := ValidateAdminSavedViewResource(input.Resource)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(&input, schema); err != nil {
+		return nil, err
+	}
+
+	view := models.AdminSavedView{
+		AdminID:   adminID,
+		Resource:  input.Resource,
+		Name:      input.Name,
+		Params:    input.Params,
+		Filter:    input.Filter,
+		IsDefault: input.IsDefault,
+		SortOrder: input.SortOrder,
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.AdminSavedView{}).Where("admin_id = ? AND resource = ?", adminID, input.Resource).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxAdminSavedViewsPerResource {
+			return bizerr.Newf("savedView.limitReached", "At most %d views per list", maxAdminSavedViewsPerResource).
+				WithParams(map[string]interface{}{"max": maxAdminSavedViewsPerResource})
+		}
+		if err := tx.Create(&view).Error; err != nil {
+			return err
+		}
+		if view.IsDefault {
+			return clearOtherDefaultViews(tx, &view)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (s *AdminSavedViewService) Update(adminID, id uint, input AdminSavedViewInput) (*models.AdminSavedView, error) {
+	view, err := s.get(adminID, id)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := ValidateAdminSavedViewResource(view.Resource)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(&input, schema); err != nil {
+		return nil, err
+	}
+
+	view.Name = input.Name
+	view.Params = input.Params
+	view.Filter = input.Filter
+	view.IsDefault = input.IsDefault
+	view.SortOrder = input.SortOrder
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(view).Select("name", "params", "filter", "is_default", "sort_order").Updates(view).Error; err != nil {
+			return err
+		}
+		if view.IsDefault {
+			return clearOtherDefaultViews(tx, view)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// SetDefault 设为默认视图，同一列表下的其他默认视图自动取消
+func (s *AdminSavedViewService) SetDefault(adminID, id uint) (*models.AdminSavedView, error) {
+	view, err := s.get(adminID, id)
+	if err != nil {
+		return nil, err
+	}
+	view.IsDefault = true
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(view).Update("is_default", true).Error; err != nil {
+			return err
+		}
+		return clearOtherDefaultViews(tx, view)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (s *AdminSavedViewService) Delete(adminID, id uint) error {
+	result := s.db.Where("id = ? AND admin_id = ?", id, adminID).Delete(&models.AdminSavedView{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAdminSavedViewNotFound
+	}
+	return nil
+}
+
+func clearOtherDefaultViews(tx *gorm.DB, view *models.AdminSavedView) error {
+	return tx.Model(&models.AdminSavedView{}).
+		Where("admin_id = ? AND resource = ? AND id <> ? AND is_default = ?", view.AdminID, view.Resource, view.ID, true).
+		Update("is_default", false).Error
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/listfilter"
+	"auralogic/internal/repository"
+)
+
+func TestAdminSavedViewServiceDefaultSwitchingAndOwnership(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.AdminSavedView{})
+	svc := NewAdminSavedViewService(db)
+
+	first, err := svc.Create(1, AdminSavedViewInput{
+		Resource:  "orders",
+		Name:      " VIP ",
+		Params:    map[string]string{"status": "paid", "page": "3"},
+		Filter:    &listfilter.Filter{Conditions: []listfilter.Condition{{Field: "tags", Operator: "contains", Value: "vip"}}},
+		IsDefault: true,
+	})
+	if err != nil {
+		t.Fatalf("create view failed: %v", err)
+	}
+	if first.Name != "VIP" || first.Params["page"] != "" || first.Params["status"] != "paid" {
+		t.Fatalf("unexpected normalized view: %+v", first)
+	}
+	second, err := svc.Create(1, AdminSavedViewInput{Resource: "orders", Name: "Pending", IsDefault: true})
+	if err != nil {
+		t.Fatalf("create view failed: %v", err)
+	}
+	if _, err := svc.Create(2, AdminSavedViewInput{Resource: "orders", Name: "Other admin", IsDefault: true}); err != nil {
+		t.Fatalf("create view failed: %v", err)
+	}
+
+	views, err := svc.List(1, "orders")
+	if err != nil {
+		t.Fatalf("list views failed: %v", err)
+	}
+	defaults := 0
+	for _, view := range views {
+		if view.IsDefault {
+			defaults++
+			if view.ID != second.ID {
+				t.Fatalf("expected latest view to be default, got %d", view.ID)
+			}
+		}
+	}
+	if len(views) != 2 || defaults != 1 {
+		t.Fatalf("expected 2 views with one default, got %d views and %d defaults", len(views), defaults)
+	}
+	if views[0].Filter == nil || len(views[0].Filter.Conditions) != 1 {
+		t.Fatalf("expected filter to round-trip, got %+v", views[0].Filter)
+	}
+
+	if _, err := svc.SetDefault(1, first.ID); err != nil {
+		t.Fatalf("set default failed: %v", err)
+	}
+	var reloaded models.AdminSavedView
+	if err := db.First(&reloaded, second.ID).Error; err != nil {
+		t.Fatalf("reload view failed: %v", err)
+	}
+	if reloaded.IsDefault {
+		t.Fatal("expected previous default to be cleared")
+	}
+	var otherDefaults int64
+	db.Model(&models.AdminSavedView{}).Where("admin_id = ? AND is_default = ?", 2, true).Count(&otherDefaults)
+	if otherDefaults != 1 {
+		t.Fatalf("expected other admin's default to be untouched, got %d", otherDefaults)
+	}
+
+	_, err = svc.SetDefault(2, first.ID)
+	requireProductBizErr(t, err, "savedView.notFound")
+	requireProductBizErr(t, svc.Delete(2, first.ID), "savedView.notFound")
+	_, err = svc.Update(2, first.ID, AdminSavedViewInput{Name: "Hijack"})
+	requireProductBizErr(t, err, "savedView.notFound")
+}
+
+func TestAdminSavedViewServiceValidation(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.AdminSavedView{})
+	svc := NewAdminSavedViewService(db)
+
+	_, err := svc.Create(1, AdminSavedViewInput{Resource: "payments", Name: "x"})
+	requireProductBizErr(t, err, "savedView.resourceInvalid")
+	_, err = svc.Create(1, AdminSavedViewInput{Resource: "users", Name: "  "})
+	requireProductBizErr(t, err, "savedView.nameRequired")
+	_, err = svc.Create(1, AdminSavedViewInput{
+		Resource: "users",
+		Name:     "Bad",
+		Filter:   &listfilter.Filter{Conditions: []listfilter.Condition{{Field: "password_hash", Operator: "eq", Value: "x"}}},
+	})
+	requireProductBizErr(t, err, "listFilter.invalid")
+}
+
+func TestOrderListAdvancedFilter(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{})
+	orderRepo := repository.NewOrderRepository(db)
+
+	now := time.Now()
+	orders := []models.Order{
+		{OrderNo: "LF-1", Status: models.OrderStatusPending, TotalAmount: 5000, ReceiverCountry: "US", Tags: []string{"vip"}},
+		{OrderNo: "LF-2", Status: models.OrderStatusPending, TotalAmount: 20000, ReceiverCountry: "US", Tags: []string{"vipx"}},
+		{OrderNo: "LF-3", Status: models.OrderStatusShipped, TotalAmount: 30000, ReceiverCountry: "CA"},
+	}
+	for i := range orders {
+		if err := db.Create(&orders[i]).Error; err != nil {
+			t.Fatalf("create order failed: %v", err)
+		}
+	}
+	db.Model(&orders[2]).Update("created_at", now.AddDate(0, 0, -40))
+
+	list := func(raw string) []string {
+		t.Helper()
+		expr, err := listfilter.Parse(raw, repository.OrderListFilterSchema)
+		if err != nil {
+			t.Fatalf("parse filter failed: %v", err)
+		}
+		items, total, err := orderRepo.List(1, 20, "", "", nil, "", "", "", nil, "", nil, nil, expr)
+		if err != nil {
+			t.Fatalf("list orders failed: %v", err)
+		}
+		if int(total) != len(items) {
+			t.Fatalf("total %d does not match items %d", total, len(items))
+		}
+		var nos []string
+		for _, item := range items {
+			nos = append(nos, item.OrderNo)
+		}
+		return nos
+	}
+
+	if got := list(`{"conditions":[{"field":"tags","operator":"contains","value":"vip"}]}`); len(got) != 1 || got[0] != "LF-1" {
+		t.Fatalf("expected exact tag match LF-1, got %v", got)
+	}
+	if got := list(`{"match":"any","conditions":[{"field":"total_amount_minor","operator":"lt","value":10000},{"field":"country","operator":"eq","value":"CA"}]}`); len(got) != 2 {
+		t.Fatalf("expected 2 orders for OR filter, got %v", got)
+	}
+	if got := list(`{"conditions":[{"field":"created_at","operator":"last_days","value":30},{"field":"total_amount_minor","operator":"between","value":[10000,null]}]}`); len(got) != 1 || got[0] != "LF-2" {
+		t.Fatalf("expected LF-2 for date and amount range, got %v", got)
+	}
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	AdminSearchTypeOrder   = "order"
+	AdminSearchTypeUser    = "user"
+	AdminSearchTypeTicket  = "ticket"
+	AdminSearchTypeProduct = "product"
+	AdminSearchTypeSerial  = "serial"
+
+	AdminSearchMinQueryLength = 2
+	AdminSearchMaxQueryLength = 100
+	adminSearchDefaultLimit   = 5
+	adminSearchMaxLimit       = 20
+)
+
+// AdminSearchTypes 搜索结果的类型顺序，前端按此顺序分组展示
+var AdminSearchTypes = []string{
+	AdminSearchTypeOrder,
+	AdminSearchTypeUser,
+	AdminSearchTypeTicket,
+	AdminSearchTypeProduct,
+	AdminSearchTypeSerial,
+}
+
+// AdminSearchResult 全局搜索的单条结果，Link 为管理端页面路径
+type AdminSearchResult struct {
+	Type      string    `json:"type"`
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Subtitle  string    `json:"subtitle,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	Link      string    `json:"link"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdminSearchOptions 搜索范围：Types 为调用方有权限查看的类型，StoreScope 只作用于订单/商品/序列号
+type AdminSearchOptions struct {
+	Types      []string
+	StoreScope *repository.StoreScope
+	Limit      int // 每种类型最多返回条数
+}
+
+// AdminSearchService 管理端跨实体搜索：订单、用户、工单、商品、序列号
+type AdminSearchService struct {
+	db *gorm.DB
+}
+
+func NewAdminSearchService(db *gorm.DB) *AdminSearchService {
+	return &AdminSearchService{db: db}
+}
+
+// Search 各类型分别查询后按 AdminSearchTypes 顺序拼接，每类按创建时间倒序
+func (s *AdminSearchService) Search(query string, opts AdminSearchOptions) ([]AdminSearchResult, error) {
+	results := []AdminSearchResult{}
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < AdminSearchMinQueryLength {
+		return results, nil
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = adminSearchDefaultLimit
+	}
+	if limit > adminSearchMaxLimit {
+		limit = adminSearchMaxLimit
+	}
+
+	enabled := make(map[string]bool, len(opts.Types))
+	for _, searchType := range opts.Types {
+		enabled[searchType] = true
+	}
+	searchers := map[string]func(string, *repository.StoreScope, int) ([]AdminSearchResult, error){
+		AdminSearchTypeOrder:   s.searchOrders,
+		AdminSearchTypeUser:    s.searchUsers,
+		AdminSearchTypeTicket:  s.searchTickets,
+		AdminSearchTypeProduct: s.searchProducts,
+		AdminSearchTypeSerial:  s.searchSerials,
+	}
+	for _, searchType := range AdminSearchTypes {
+		if !enabled[searchType] {
+			continue
+		}
+		matches, err := searchers[searchType](query, opts.StoreScope, limit)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, matches...)
+	}
+	return results, nil
+}
+
+// searchOrders 订单号、物流单号、收件人；结果不带收件人信息，避免绕过隐私权限
+func (s *AdminSearchService) searchOrders(query string, scope *repository.StoreScope, limit int) ([]AdminSearchResult, error) {
+	condition, args := repository.OrderSearchCondition(query)
+	// 物流单号通常为大写，按原样和大写两种形式精确匹配以命中索引
+	args = append(args, []string{query, strings.ToUpper(query)})
+	var orders []models.Order
+	if err := scope.Apply(s.db.Model(&models.Order{})).
+		Select("id", "order_no", "status", "tracking_no", "created_at").
+		Where("("+condition+" OR tracking_no IN ?)", args...).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	results := make([]AdminSearchResult, 0, len(orders))
+	for _, order := range orders {
+		results = append(results, orderSearchResult(order))
+	}
+	return results, nil
+}
+
+func (s *AdminSearchService) searchUsers(query string, _ *repository.StoreScope, limit int) ([]AdminSearchResult, error) {
+	var users []models.User
+	if err := dbutil.WhereContainsFold(s.db.Model(&models.User{}), query, "email", "name").
+		Select("id", "email", "name", "role", "created_at").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	results := make([]AdminSearchResult, 0, len(users))
+	for _, user := range users {
+		results = append(results, userSearchResult(user))
+	}
+	return results, nil
+}
+
+func (s *AdminSearchService) searchTickets(query string, _ *repository.StoreScope, limit int) ([]AdminSearchResult, error) {
+	var tickets []models.Ticket
+	if err := dbutil.WhereContainsFold(s.db.Model(&models.Ticket{}), query, "ticket_no", "subject").
+		Select("id", "ticket_no", "subject", "status", "created_at").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	results := make([]AdminSearchResult, 0, len(tickets))
+	for _, ticket := range tickets {
+		results = append(results, ticketSearchResult(ticket))
+	}
+	return results, nil
+}
+
+func (s *AdminSearchService) searchProducts(query string, scope *repository.StoreScope, limit int) ([]AdminSearchResult, error) {
+	var products []models.Product
+	if err := dbutil.WhereContainsFold(scope.Apply(s.db.Model(&models.Product{})), query, "name", "sku").
+		Select("id", "name", "sku", "status", "created_at").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+	results := make([]AdminSearchResult, 0, len(products))
+	for _, product := range products {
+		results = append(results, productSearchResult(product))
+	}
+	return results, nil
+}
+
+// searchSerials 序列号按前缀匹配（大写存储），链接到所属订单；店铺范围按所属订单过滤
+func (s *AdminSearchService) searchSerials(query string, scope *repository.StoreScope, limit int) ([]AdminSearchResult, error) {
+	serialQuery := s.db.Model(&models.ProductSerial{}).
+		Preload("Product", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name") }).
+		Where("serial_number LIKE ? ESCAPE '!'", dbutil.EscapeLike(strings.ToUpper(query))+"%")
+	if scope != nil && len(scope.StoreIDs) > 0 {
+		serialQuery = serialQuery.Where("order_id IN (?)", scope.Apply(s.db.Model(&models.Order{}).Select("id")))
+	}
+	var serials []models.ProductSerial
+	if err := serialQuery.Order("created_at DESC").Limit(limit).Find(&serials).Error; err != nil {
+		return nil, err
+	}
+	results := make([]AdminSearchResult, 0, len(serials))
+	for _, serial := range serials {
+		status := "valid"
+		if serial.InvalidatedAt != nil {
+			status = "invalidated"
+		}
+		result := AdminSearchResult{
+			Type:      AdminSearchTypeSerial,
+			ID:        serial.ID,
+			Title:     serial.SerialNumber,
+			Status:    status,
+			Link:      fmt.Sprintf("/admin/orders/%d", serial.OrderID),
+			CreatedAt: serial.CreatedAt,
+		}
+		if serial.Product != nil {
+			result.Subtitle = serial.Product.Name
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func orderSearchResult(order models.Order) AdminSearchResult {
+	return AdminSearchResult{
+		Type:      AdminSearchTypeOrder,
+		ID:        order.ID,
+		Title:     order.OrderNo,
+		Subtitle:  order.TrackingNo,
+		Status:    string(order.Status),
+		Link:      fmt.Sprintf("/admin/orders/%d", order.ID),
+		CreatedAt: order.CreatedAt,
+	}
+}
+
+func userSearchResult(user models.User) AdminSearchResult {
+	return AdminSearchResult{
+		Type:      AdminSearchTypeUser,
+		ID:        user.ID,
+		Title:     user.Email,
+		Subtitle:  user.Name,
+		Status:    user.Role,
+		Link:      fmt.Sprintf("/admin/users/%d", user.ID),
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func ticketSearchResult(ticket models.Ticket) AdminSearchResult {
+	return AdminSearchResult{
+		Type:      AdminSearchTypeTicket,
+		ID:        ticket.ID,
+		Title:     ticket.Subject,
+		Subtitle:  ticket.TicketNo,
+		Status:    string(ticket.Status),
+		Link:      fmt.Sprintf("/admin/tickets?ticket=%d", ticket.ID),
+		CreatedAt: ticket.CreatedAt,
+	}
+}
+
+func productSearchResult(product models.Product) AdminSearchResult {
+	return AdminSearchResult{
+		Type:      AdminSearchTypeProduct,
+		ID:        product.ID,
+		Title:     product.Name,
+		Subtitle:  product.SKU,
+		Status:    string(product.Status),
+		Link:      fmt.Sprintf("/admin/products/%d", product.ID),
+		CreatedAt: product.CreatedAt,
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+)
+
+func TestAdminSearchAcrossEntitiesRespectsTypesAndStoreScope(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.User{}, &models.Ticket{}, &models.Product{}, &models.ProductSerial{})
+	storeA, storeB := uint(1), uint(2)
+
+	user := models.User{Email: "alice.findme@example.com", Name: "Alice", Role: "user"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	orderA := models.Order{OrderNo: "ORD-FINDME-A", Status: models.OrderStatusShipped, StoreID: &storeA}
+	orderB := models.Order{OrderNo: "ORD-OTHER-B", Status: models.OrderStatusShipped, StoreID: &storeB, TrackingNo: "FINDME"}
+	for _, order := range []*models.Order{&orderA, &orderB} {
+		if err := db.Create(order).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+	}
+	ticket := models.Ticket{TicketNo: "T0001", UserID: user.ID, Subject: "Where is findme parcel", Content: "?"}
+	if err := db.Create(&ticket).Error; err != nil {
+		t.Fatalf("create ticket: %v", err)
+	}
+	product := models.Product{SKU: "SKU-FINDME", Name: "Mug", StoreID: &storeB}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	serials := []models.ProductSerial{
+		{SerialNumber: "FINDME0001AAAA", ProductID: product.ID, OrderID: orderA.ID, ProductCode: "FINDME", SequenceNumber: 1, AntiCounterfeitCode: "AAAA"},
+		{SerialNumber: "FINDME0002BBBB", ProductID: product.ID, OrderID: orderB.ID, ProductCode: "FINDME", SequenceNumber: 2, AntiCounterfeitCode: "BBBB"},
+	}
+	for i := range serials {
+		if err := db.Create(&serials[i]).Error; err != nil {
+			t.Fatalf("create serial: %v", err)
+		}
+	}
+
+	svc := NewAdminSearchService(db)
+	results, err := svc.Search("findme", AdminSearchOptions{Types: AdminSearchTypes})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Type]++
+	}
+	if counts[AdminSearchTypeOrder] != 2 || counts[AdminSearchTypeUser] != 1 || counts[AdminSearchTypeTicket] != 1 ||
+		counts[AdminSearchTypeProduct] != 1 || counts[AdminSearchTypeSerial] != 2 {
+		t.Fatalf("unexpected result counts: %+v", counts)
+	}
+	if results[0].Type != AdminSearchTypeOrder {
+		t.Fatalf("expected orders first, got %+v", results[0])
+	}
+	for _, result := range results {
+		if result.Type == AdminSearchTypeSerial && result.Link == "" {
+			t.Fatalf("serial result must link to its order: %+v", result)
+		}
+	}
+
+	scoped, err := svc.Search("FINDME", AdminSearchOptions{
+		Types:      []string{AdminSearchTypeOrder, AdminSearchTypeProduct, AdminSearchTypeSerial},
+		StoreScope: &repository.StoreScope{StoreIDs: []uint{storeA}},
+	})
+	if err != nil {
+		t.Fatalf("scoped search: %v", err)
+	}
+	if len(scoped) != 2 || scoped[0].Title != "ORD-FINDME-A" || scoped[1].Title != "FINDME0001AAAA" {
+		t.Fatalf("expected only store A order and serial, got %+v", scoped)
+	}
+
+	if short, err := svc.Search("f", AdminSearchOptions{Types: AdminSearchTypes}); err != nil || len(short) != 0 {
+		t.Fatalf("expected no results for short query, got %+v err=%v", short, err)
+	}
+}
+
+func TestAdminPaletteRecentEntitiesAndAssignedTickets(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.User{}, &models.Ticket{}, &models.Product{}, &models.OperationLog{})
+	adminID, otherAdminID := uint(100), uint(101)
+	storeA, storeB := uint(1), uint(2)
+
+	customer := models.User{Email: "buyer@example.com", Name: "Buyer", Role: "user"}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	orderA := models.Order{OrderNo: "ORD-RECENT-A", Status: models.OrderStatusPending, StoreID: &storeA}
+	orderB := models.Order{OrderNo: "ORD-RECENT-B", Status: models.OrderStatusPending, StoreID: &storeB}
+	for _, order := range []*models.Order{&orderA, &orderB} {
+		if err := db.Create(order).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+	}
+	logEntry := func(userID uint, resourceType string, resourceID uint) models.OperationLog {
+		return models.OperationLog{UserID: &userID, Action: "update", ResourceType: resourceType, ResourceID: &resourceID}
+	}
+	logs := []models.OperationLog{
+		logEntry(adminID, "order", orderA.ID),
+		logEntry(adminID, "user", customer.ID),
+		logEntry(adminID, "order", orderB.ID),
+		logEntry(adminID, "order", orderA.ID),
+		logEntry(adminID, "system_config", 1),
+		logEntry(otherAdminID, "order", orderB.ID),
+	}
+	for i := range logs {
+		if err := db.Create(&logs[i]).Error; err != nil {
+			t.Fatalf("create log: %v", err)
+		}
+	}
+
+	svc := NewAdminSearchService(db)
+	recent, err := svc.RecentEntities(adminID, AdminSearchTypes, nil, 0)
+	if err != nil {
+		t.Fatalf("recent entities: %v", err)
+	}
+	if len(recent) != 3 || recent[0].Title != "ORD-RECENT-A" || recent[1].Title != "ORD-RECENT-B" || recent[2].Title != "buyer@example.com" {
+		t.Fatalf("expected deduplicated recent entities newest first, got %+v", recent)
+	}
+
+	scoped, err := svc.RecentEntities(adminID, []string{AdminSearchTypeOrder}, &repository.StoreScope{StoreIDs: []uint{storeB}}, 0)
+	if err != nil || len(scoped) != 1 || scoped[0].Title != "ORD-RECENT-B" {
+		t.Fatalf("expected only permitted, in-scope entities, got %+v err=%v", scoped, err)
+	}
+
+	tickets := []models.Ticket{
+		{TicketNo: "T-1", UserID: customer.ID, Subject: "Mine open", Content: "-", Status: models.TicketStatusOpen, AssignedTo: &adminID},
+		{TicketNo: "T-2", UserID: customer.ID, Subject: "Mine closed", Content: "-", Status: models.TicketStatusClosed, AssignedTo: &adminID},
+		{TicketNo: "T-3", UserID: customer.ID, Subject: "Someone else", Content: "-", Status: models.TicketStatusOpen, AssignedTo: &otherAdminID},
+	}
+	for i := range tickets {
+		if err := db.Create(&tickets[i]).Error; err != nil {
+			t.Fatalf("create ticket: %v", err)
+		}
+	}
+	assigned, err := svc.AssignedTickets(adminID)
+	if err != nil || len(assigned) != 1 || assigned[0].Subtitle != "T-1" {
+		t.Fatalf("expected only open ticket assigned to admin, got %+v err=%v", assigned, err)
+	}
+
+	actions := AdminQuickActionsFor(func(permission string) bool { return permission == "order.view" }, false)
+	if len(actions) != 1 || actions[0].ID != "pending_shipment" {
+		t.Fatalf("expected only permitted actions, got %+v", actions)
+	}
+	if all := AdminQuickActionsFor(func(string) bool { return true }, true); all[len(all)-1].ID != "dashboard" {
+		t.Fatalf("expected super admin actions to include dashboard, got %+v", all)
+	}
+}
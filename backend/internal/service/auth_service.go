@@ -20,8 +20,11 @@ import (
 )
 
 type AuthService struct {
-	userRepo *repository.UserRepository
-	cfg      *config.Config
+	userRepo        *repository.UserRepository
+	cfg             *config.Config
+	loginProtection *LoginProtectionService
+	emailChanges    *EmailChangeService
+	orderClaims     *OrderClaimService
 }
 
 var (
@@ -36,13 +39,55 @@ func NewAuthService(userRepo *repository.UserRepository, cfg *config.Config) *Au
 	}
 }
 
+// SetLoginProtection 设置登录保护（账户锁定、IP 封禁）
+func (s *AuthService) SetLoginProtection(loginProtection *LoginProtectionService) {
+	s.loginProtection = loginProtection
+}
+
+// SetEmailChangeService 设置邮箱变更服务（通知旧邮箱、同步订单关联）
+func (s *AuthService) SetEmailChangeService(emailChanges *EmailChangeService) {
+	s.emailChanges = emailChanges
+}
+
+// SetOrderClaimService 设置订单认领服务（邮箱验证后自动关联外部平台订单）
+func (s *AuthService) SetOrderClaimService(orderClaims *OrderClaimService) {
+	s.orderClaims = orderClaims
+}
+
+// ClaimGuestOrders 尽力认领与用户已验证邮箱匹配的无主订单，失败不影响主流程
+func (s *AuthService) ClaimGuestOrders(user *models.User) []models.Order {
+	if s.orderClaims == nil {
+		return nil
+	}
+	orders, err := s.orderClaims.ClaimByEmail(user)
+	if err != nil {
+		fmt.Printf("Warning: failed to claim guest orders for user %d: %v\n", user.ID, err)
+		return nil
+	}
+	return orders
+}
+
 // Login 用户登录
 func (s *AuthService) Login(email, pwd string) (string, *models.User, error) {
+	return s.LoginFromClient(email, pwd, "", "")
+}
+
+// LoginFromClient 带客户端信息的密码登录，启用登录保护时记录失败次数并检查锁定和封禁
+func (s *AuthService) LoginFromClient(email, pwd, clientIP, userAgent string) (string, *models.User, error) {
 	email = normalizeEmail(email)
+	if s.loginProtection != nil {
+		if err := s.loginProtection.CheckLogin(email, clientIP, userAgent); err != nil {
+			return "", nil, err
+		}
+	}
+
 	// 查找用户
 	user, err := s.userRepo.FindByEmail(email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if s.loginProtection != nil {
+				s.loginProtection.RecordFailure(email, nil, clientIP, userAgent)
+			}
 			return "", nil, authbiz.InvalidEmailOrPassword()
 		}
 		return "", nil, err
@@ -55,6 +100,9 @@ func (s *AuthService) Login(email, pwd string) (string, *models.User, error) {
 
 	// 验证密码
 	if !password.CheckPassword(pwd, user.PasswordHash) {
+		if s.loginProtection != nil {
+			s.loginProtection.RecordFailure(email, &user.ID, clientIP, userAgent)
+		}
 		return "", nil, authbiz.InvalidEmailOrPassword()
 	}
 
@@ -63,8 +111,8 @@ func (s *AuthService) Login(email, pwd string) (string, *models.User, error) {
 		return "", nil, authbiz.AccountDisabled()
 	}
 
-	// 检查邮箱是否已验证（管理员跳过）
-	if !user.EmailVerified && !user.IsAdmin() && s.cfg.Security.Login.RequireEmailVerification {
+	// 检查邮箱是否已验证（管理员与豁免用户跳过；checkout/grace 模式下仅在下单时限制）
+	if authbiz.EvaluateEmailVerification(s.cfg.Security.Login, user, models.NowFunc()).BlockLogin {
 		return "", nil, authbiz.EmailNotVerified()
 	}
 
@@ -74,6 +122,10 @@ func (s *AuthService) Login(email, pwd string) (string, *models.User, error) {
 		return "", nil, err
 	}
 
+	if s.loginProtection != nil {
+		s.loginProtection.RecordSuccess(user, clientIP, userAgent)
+	}
+
 	// 更新最后登录时间
 	now := models.NowFunc()
 	user.LastLoginAt = &now
@@ -234,10 +286,12 @@ func (s *AuthService) UpdateLoginIP(user *models.User) {
 	s.userRepo.Update(user)
 }
 
-// UpdatePreferences updates user preferences (locale/country/notification switches).
+// UpdatePreferences updates user preferences (locale/country/display currency/notification switches).
+// displayCurrency 为 nil 时不修改，空字符串表示清除偏好。
 func (s *AuthService) UpdatePreferences(
 	userID uint,
 	locale, country string,
+	displayCurrency *string,
 	emailNotifyOrder, emailNotifyTicket, emailNotifyMarketing, smsNotifyMarketing *bool,
 ) error {
 	user, err := s.userRepo.FindByID(userID)
@@ -251,6 +305,17 @@ func (s *AuthService) UpdatePreferences(
 	if country != "" {
 		user.Country = country
 	}
+	if displayCurrency != nil {
+		currency := strings.TrimSpace(*displayCurrency)
+		if currency != "" {
+			normalized, err := normalizeCurrencyCode(currency)
+			if err != nil {
+				return authbiz.InvalidDisplayCurrency()
+			}
+			currency = normalized
+		}
+		user.DisplayCurrency = currency
+	}
 	if emailNotifyOrder != nil {
 		user.EmailNotifyOrder = *emailNotifyOrder
 	}
@@ -412,6 +477,8 @@ func (s *AuthService) LoginWithCode(email, code string) (string, *models.User, e
 	}
 
 	now := models.NowFunc()
+	// 能收到邮箱验证码即证明邮箱归属
+	user.EmailVerified = true
 	user.LastLoginAt = &now
 	s.userRepo.Update(user)
 
@@ -463,6 +530,9 @@ func (s *AuthService) LoginWithPhoneCode(phone, code string) (string, *models.Us
 	if !user.IsActive {
 		return "", nil, authbiz.AccountDisabled()
 	}
+	if authbiz.EvaluateEmailVerification(s.cfg.Security.Login, user, models.NowFunc()).BlockLogin {
+		return "", nil, authbiz.EmailNotVerified()
+	}
 	token, err := jwt.GenerateToken(user.ID, user.Email, user.Role, s.cfg.JWT.ExpireHours)
 	if err != nil {
 		return "", nil, err
@@ -558,25 +628,39 @@ func (s *AuthService) SendBindEmailCode(userID uint, email string) (string, erro
 	return code, nil
 }
 
-// BindEmail verifies code and binds email to user
-func (s *AuthService) BindEmail(userID uint, email string, code string) error {
+// BindEmail verifies code and binds email to user.
+// 已有邮箱时视为更换邮箱，返回的变更记录包含旧邮箱的撤销链接
+func (s *AuthService) BindEmail(userID uint, email, code, clientIP string) (*models.EmailChange, error) {
 	email = normalizeEmail(email)
 	key := fmt.Sprintf("bind_email_code:%d:%s", userID, email)
 	stored, err := cache.Get(key)
 	if err != nil || stored != code {
-		return authbiz.CodeExpired()
+		return nil, authbiz.CodeExpired()
 	}
 	_ = cache.Del(key)
 	if _, err := s.userRepo.FindByEmail(email); err == nil {
-		return authbiz.EmailAlreadyInUse()
+		return nil, authbiz.EmailAlreadyInUse()
 	}
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return normalizeAuthLookupError(err)
+		return nil, normalizeAuthLookupError(err)
 	}
+	if s.emailChanges != nil {
+		return s.emailChanges.Apply(user, email, clientIP)
+	}
+	now := models.NowFunc()
 	user.Email = email
 	user.EmailVerified = true
-	return s.userRepo.Update(user)
+	user.EmailChangedAt = &now
+	return nil, s.userRepo.Update(user)
+}
+
+// RevertEmailChange 通过旧邮箱收到的链接撤销邮箱变更
+func (s *AuthService) RevertEmailChange(token string) (*models.EmailChange, error) {
+	if s.emailChanges == nil {
+		return nil, authbiz.EmailChangeLinkInvalid()
+	}
+	return s.emailChanges.Revert(token)
 }
 
 // SendBindPhoneCode generates a code for binding phone to an existing account
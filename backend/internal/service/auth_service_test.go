@@ -237,3 +237,79 @@ func TestLoginWithPhoneCodeRespectsPhoneLoginConfig(t *testing.T) {
 		return err
 	}(), "auth.phoneLoginDisabled")
 }
+
+func TestAuthLoginFollowsEmailVerificationMode(t *testing.T) {
+	svc, db := newAuthServiceTestDB(t)
+
+	hash, err := password.HashPassword("Password1!")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	user := models.User{
+		UUID:         "grace-user",
+		Email:        "grace@example.com",
+		PasswordHash: hash,
+		Role:         "user",
+		IsActive:     true,
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	svc.cfg.Security.Login.EmailVerificationMode = "checkout"
+	if _, _, err := svc.Login(user.Email, "Password1!"); err != nil {
+		t.Fatalf("expected checkout mode to allow login, got %v", err)
+	}
+
+	svc.cfg.Security.Login.EmailVerificationMode = "grace"
+	svc.cfg.Security.Login.EmailVerificationGraceDays = 3
+	if _, _, err := svc.Login(user.Email, "Password1!"); err != nil {
+		t.Fatalf("expected login within grace period, got %v", err)
+	}
+	if err := db.Model(&models.User{}).Where("id = ?", user.ID).
+		Update("created_at", models.NowFunc().AddDate(0, 0, -4)).Error; err != nil {
+		t.Fatalf("age user: %v", err)
+	}
+	requireAuthBizErr(t, func() error {
+		_, _, err := svc.Login(user.Email, "Password1!")
+		return err
+	}(), "auth.emailNotVerified")
+
+	if err := db.Model(&models.User{}).Where("id = ?", user.ID).
+		Update("email_verification_exempt", true).Error; err != nil {
+		t.Fatalf("exempt user: %v", err)
+	}
+	if _, _, err := svc.Login(user.Email, "Password1!"); err != nil {
+		t.Fatalf("expected exempt user to log in, got %v", err)
+	}
+}
+
+func TestUpdatePreferencesNormalizesDisplayCurrency(t *testing.T) {
+	svc, db := newAuthServiceTestDB(t)
+	user := &models.User{Email: "display@example.com", Name: "Display", IsActive: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	currency := " usd "
+	if err := svc.UpdatePreferences(user.ID, "", "", &currency, nil, nil, nil, nil); err != nil {
+		t.Fatalf("update preferences: %v", err)
+	}
+	var reloaded models.User
+	db.First(&reloaded, user.ID)
+	if reloaded.DisplayCurrency != "USD" {
+		t.Fatalf("expected normalized USD, got %q", reloaded.DisplayCurrency)
+	}
+
+	invalid := "US1"
+	requireAuthBizErr(t, svc.UpdatePreferences(user.ID, "", "", &invalid, nil, nil, nil, nil), "auth.invalidDisplayCurrency")
+
+	cleared := ""
+	if err := svc.UpdatePreferences(user.ID, "", "", &cleared, nil, nil, nil, nil); err != nil {
+		t.Fatalf("clear display currency: %v", err)
+	}
+	db.First(&reloaded, user.ID)
+	if reloaded.DisplayCurrency != "" {
+		t.Fatalf("expected display currency cleared, got %q", reloaded.DisplayCurrency)
+	}
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	BackgroundJobKindInterval = "interval"
+	BackgroundJobKindCron     = "cron"
+	BackgroundJobKindLoop     = "loop"
+)
+
+// BackgroundJob 后台任务定义：Interval / Cron 二选一配合 Run；Loop 为自行调度的常驻任务
+type BackgroundJob struct {
+	Name       string
+	Interval   time.Duration
+	Cron       string
+	RunOnStart bool
+	Run        func() error
+	Loop       func(stopChan <-chan struct{})
+	// LockTTL > 0 时通过分布式锁协调多进程：定时任务每次运行前抢锁，常驻任务仅由持锁进程运行并定期续期
+	LockTTL time.Duration
+	// Jitter > 0 时每次定时触发额外随机延后 [0, Jitter)，错开多副本同时抢锁和同一时刻扎堆的任务
+	Jitter time.Duration
+}
+
+// BackgroundJobStatus 后台任务运行状态
+type BackgroundJobStatus struct {
+	Name           string     `json:"name"`
+	Kind           string     `json:"kind"`
+	Schedule       string     `json:"schedule"`
+	JitterMs       int64      `json:"jitter_ms,omitempty"`
+	Running        bool       `json:"running"`
+	Healthy        bool       `json:"healthy"`
+	RegisteredAt   time.Time  `json:"registered_at"`
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+	RunCount       int64      `json:"run_count"`
+	FailureCount   int64      `json:"failure_count"`
+	SkippedCount   int64      `json:"skipped_count"`
+	// 因其他进程持有分布式锁而跳过的次数
+	LockSkippedCount int64 `json:"lock_skipped_count"`
+}
+
+type backgroundJobEntry struct {
+	job      BackgroundJob
+	cron     *cronSchedule
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	// 以下字段由 BackgroundScheduler.mu 保护
+	status  BackgroundJobStatus
+	overrun bool
+}
+
+// BackgroundScheduler 统一调度后台任务：panic 恢复、防重入、记录最近一次运行状态
+type BackgroundScheduler struct {
+	mu   sync.Mutex
+	jobs map[string]*backgroundJobEntry
+}
+
+// NewBackgroundScheduler 创建后台任务调度器
+func NewBackgroundScheduler() *BackgroundScheduler {
+	return &BackgroundScheduler{jobs: make(map[string]*backgroundJobEntry)}
+}
+
+var globalBackgroundScheduler atomic.Pointer[BackgroundScheduler]
+
+// SetGlobalBackgroundScheduler 设置状态接口读取的调度器
+func SetGlobalBackgroundScheduler(scheduler *BackgroundScheduler) {
+	globalBackgroundScheduler.Store(scheduler)
+}
+
+// GlobalBackgroundScheduler 当前生效的调度器，未设置时返回 nil
+func GlobalBackgroundScheduler() *BackgroundScheduler {
+	return globalBackgroundScheduler.Load()
+}
+
+// backgroundSchedulerForService 服务启动时优先使用全局调度器，未设置时（如单元测试）使用独立调度器
+func backgroundSchedulerForService() *BackgroundScheduler {
+	if scheduler := GlobalBackgroundScheduler(); scheduler != nil {
+		return scheduler
+	}
+	return NewBackgroundScheduler()
+}
+
+// Register 注册并立即启动任务，同名任务已存在时返回错误
+func (s *BackgroundScheduler) Register(job BackgroundJob) error {
+	entry := &backgroundJobEntry{job: job, stopChan: make(chan struct{})}
+	switch {
+	case job.Name == "":
+		return errors.New("background job name is required")
+	case job.Loop != nil:
+		entry.status.Kind = BackgroundJobKindLoop
+	case job.Run == nil:
+		return fmt.Errorf("background job %s has no Run function", job.Name)
+	case job.Cron != "":
+		schedule, err := parseCronSchedule(job.Cron)
+		if err != nil {
+			return fmt.Errorf("background job %s: %w", job.Name, err)
+		}
+		entry.cron = schedule
+		entry.status.Kind = BackgroundJobKindCron
+		entry.status.Schedule = job.Cron
+	case job.Interval > 0:
+		entry.status.Kind = BackgroundJobKindInterval
+		entry.status.Schedule = job.Interval.String()
+	default:
+		return fmt.Errorf("background job %s needs an interval or cron expression", job.Name)
+	}
+	if entry.status.Kind != BackgroundJobKindLoop {
+		entry.status.JitterMs = job.Jitter.Milliseconds()
+	}
+	entry.status.Name = job.Name
+	entry.status.RegisteredAt = time.Now()
+
+	s.mu.Lock()
+	if _, exists := s.jobs[job.Name]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("background job %s already registered", job.Name)
+	}
+	s.jobs[job.Name] = entry
+	s.mu.Unlock()
+
+	entry.wg.Add(1)
+	if entry.status.Kind == BackgroundJobKindLoop {
+		go s.runLoopJob(entry)
+	} else {
+		go s.runScheduledJob(entry)
+	}
+	return nil
+}
+
+// Remove 停止并移除任务，等待正在执行的一次运行结束
+func (s *BackgroundScheduler) Remove(name string) {
+	s.mu.Lock()
+	entry := s.jobs[name]
+	delete(s.jobs, name)
+	s.mu.Unlock()
+	if entry == nil {
+		return
+	}
+	close(entry.stopChan)
+	entry.wg.Wait()
+}
+
+// Stop 停止全部任务
+func (s *BackgroundScheduler) Stop() {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	s.mu.Unlock()
+	for _, name := range names {
+		s.Remove(name)
+	}
+}
+
+// Statuses 按名称排序返回全部任务状态
+func (s *BackgroundScheduler) Statuses() []BackgroundJobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]BackgroundJobStatus, 0, len(s.jobs))
+	for _, entry := range s.jobs {
+		status := entry.status
+		// 常驻任务 panic 后自动重启，LastError 保留最近一次 panic 信息
+		status.Healthy = status.LastError == "" && !entry.overrun
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+func (s *BackgroundScheduler) nextRunAt(entry *backgroundJobEntry, now time.Time) time.Time {
+	var next time.Time
+	if entry.cron != nil {
+		next = entry.cron.Next(now)
+	} else {
+		next = now.Add(entry.job.Interval)
+	}
+	if next.IsZero() || entry.job.Jitter <= 0 {
+		return next
+	}
+	return next.Add(time.Duration(rand.Int63n(int64(entry.job.Jitter))))
+}
+
+func (s *BackgroundScheduler) runScheduledJob(entry *backgroundJobEntry) {
+	defer entry.wg.Done()
+	if entry.job.RunOnStart {
+		s.dispatch(entry)
+	}
+	for {
+		next := s.nextRunAt(entry, time.Now())
+		if next.IsZero() {
+			log.Printf("[scheduler] %s has no upcoming run for cron %q", entry.job.Name, entry.job.Cron)
+			<-entry.stopChan
+			return
+		}
+		s.mu.Lock()
+		entry.status.NextRunAt = &next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-entry.stopChan:
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.dispatch(entry)
+		}
+	}
+}
+
+// dispatch 上一次运行尚未结束时跳过本次触发，避免同一任务并发执行
+func (s *BackgroundScheduler) dispatch(entry *backgroundJobEntry) {
+	s.mu.Lock()
+	if entry.status.Running {
+		entry.status.SkippedCount++
+		entry.overrun = true
+		s.mu.Unlock()
+		return
+	}
+	entry.status.Running = true
+	s.mu.Unlock()
+
+	entry.wg.Add(1)
+	go func() {
+		defer entry.wg.Done()
+		var lock *backgroundJobLock
+		var lockErr error
+		if entry.job.LockTTL > 0 {
+			lock, lockErr = tryBackgroundJobLock(entry.job.Name, entry.job.LockTTL)
+			if lockErr == nil && lock == nil {
+				s.mu.Lock()
+				entry.status.Running = false
+				entry.status.LockSkippedCount++
+				s.mu.Unlock()
+				return
+			}
+		}
+
+		startedAt := time.Now()
+		s.mu.Lock()
+		entry.status.LastStartedAt = &startedAt
+		s.mu.Unlock()
+
+		err := lockErr
+		if err != nil {
+			err = fmt.Errorf("acquire lock: %w", err)
+		} else {
+			err = runBackgroundJobOnce(entry.job.Name, entry.job.Run)
+		}
+		if lock != nil {
+			if releaseErr := lock.Release(); releaseErr != nil {
+				log.Printf("[scheduler] %s release lock failed: %v", entry.job.Name, releaseErr)
+			}
+		}
+		finishedAt := time.Now()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		entry.status.Running = false
+		entry.overrun = false
+		entry.status.LastFinishedAt = &finishedAt
+		entry.status.LastDurationMs = finishedAt.Sub(startedAt).Milliseconds()
+		entry.status.RunCount++
+		entry.status.LastError = ""
+		if err != nil {
+			entry.status.FailureCount++
+			entry.status.LastError = err.Error()
+		}
+	}()
+}
+
+func runBackgroundJobOnce(name string, run func() error) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("[panic-guard] %s panic recovered: %v\n%s", name, recovered, debug.Stack())
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return run()
+}
+
+func (s *BackgroundScheduler) runLoopJob(entry *backgroundJobEntry) {
+	defer entry.wg.Done()
+	if entry.job.LockTTL <= 0 {
+		s.runLoopBody(entry, entry.stopChan)
+		return
+	}
+
+	// 未持锁的进程作为备用，定期重试抢锁
+	retryInterval := entry.job.LockTTL / 3
+	for {
+		lock, err := tryBackgroundJobLock(entry.job.Name, entry.job.LockTTL)
+		if err != nil {
+			log.Printf("[scheduler] %s acquire lock failed: %v", entry.job.Name, err)
+		} else if lock != nil {
+			s.runLockedLoop(entry, lock, retryInterval)
+			if err := lock.Release(); err != nil {
+				log.Printf("[scheduler] %s release lock failed: %v", entry.job.Name, err)
+			}
+		}
+		if waitBackgroundServiceStopChan(entry.stopChan, retryInterval) {
+			return
+		}
+	}
+}
+
+// runLockedLoop 持锁期间运行常驻任务，续期失败时停止本进程的任务交由其他进程接管
+func (s *BackgroundScheduler) runLockedLoop(entry *backgroundJobEntry, lock *backgroundJobLock, refreshInterval time.Duration) {
+	loopStop := make(chan struct{})
+	loopDone := make(chan struct{})
+	go func() {
+		defer close(loopDone)
+		s.runLoopBody(entry, loopStop)
+	}()
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-entry.stopChan:
+			close(loopStop)
+			<-loopDone
+			return
+		case <-loopDone:
+			return
+		case <-ticker.C:
+			refreshed, err := lock.Refresh()
+			if err == nil && refreshed {
+				continue
+			}
+			log.Printf("[scheduler] %s lost lock (err=%v), stopping local loop", entry.job.Name, err)
+			close(loopStop)
+			<-loopDone
+			return
+		}
+	}
+}
+
+func (s *BackgroundScheduler) runLoopBody(entry *backgroundJobEntry, stopChan chan struct{}) {
+	startedAt := time.Now()
+	s.mu.Lock()
+	entry.status.Running = true
+	entry.status.LastStartedAt = &startedAt
+	s.mu.Unlock()
+
+	runBackgroundServiceWithStopChan(entry.job.Name, stopChan, func(stopChan <-chan struct{}) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				s.mu.Lock()
+				entry.status.FailureCount++
+				entry.status.LastError = fmt.Sprintf("panic: %v", recovered)
+				s.mu.Unlock()
+				panic(recovered)
+			}
+		}()
+		entry.job.Loop(stopChan)
+	})
+
+	finishedAt := time.Now()
+	s.mu.Lock()
+	entry.status.Running = false
+	entry.status.LastFinishedAt = &finishedAt
+	s.mu.Unlock()
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule 标准5段 cron 表达式（分 时 日 月 周），按本地时区计算
+type cronSchedule struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// 日与周同时受限时按 cron 惯例取并集
+	dayRestricted     bool
+	weekdayRestricted bool
+}
+
+type cronFieldRange struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFieldRanges = []cronFieldRange{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "weekday", min: 0, max: 7},
+}
+
+// parseCronSchedule 解析 cron 表达式，支持 *、数字、a-b、列表和 /n 步长
+func parseCronSchedule(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFieldRanges) {
+		return nil, fmt.Errorf("cron expression must have 5 fields, got %d", len(fields))
+	}
+	masks := make([]uint64, len(fields))
+	for i, field := range fields {
+		mask, err := parseCronField(field, cronFieldRanges[i])
+		if err != nil {
+			return nil, err
+		}
+		masks[i] = mask
+	}
+	// 周日既可写 0 也可写 7
+	if masks[4]&(1<<7) != 0 {
+		masks[4] |= 1
+	}
+	return &cronSchedule{
+		minutes:           masks[0],
+		hours:             masks[1],
+		days:              masks[2],
+		months:            masks[3],
+		weekdays:          masks[4],
+		dayRestricted:     fields[2] != "*",
+		weekdayRestricted: fields[4] != "*",
+	}, nil
+}
+
+func parseCronField(field string, bounds cronFieldRange) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart = part[:idx]
+			n, err := strconv.Atoi(part[idx+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid %s step %q", bounds.name, part)
+			}
+			step = n
+		}
+
+		start, end := bounds.min, bounds.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			pieces := strings.SplitN(rangePart, "-", 2)
+			lo, errLo := strconv.Atoi(pieces[0])
+			hi, errHi := strconv.Atoi(pieces[1])
+			if errLo != nil || errHi != nil || lo > hi {
+				return 0, fmt.Errorf("invalid %s range %q", bounds.name, part)
+			}
+			start, end = lo, hi
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s value %q", bounds.name, part)
+			}
+			start = n
+			if step == 1 {
+				end = n
+			}
+		}
+		if start < bounds.min || end > bounds.max {
+			return 0, fmt.Errorf("%s value %q out of range %d-%d", bounds.name, part, bounds.min, bounds.max)
+		}
+		for v := start; v <= end; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func (c *cronSchedule) matchDay(t time.Time) bool {
+	dayMatch := c.days&(1<<uint(t.Day())) != 0
+	weekdayMatch := c.weekdays&(1<<uint(t.Weekday())) != 0
+	if c.dayRestricted && c.weekdayRestricted {
+		return dayMatch || weekdayMatch
+	}
+	return dayMatch && weekdayMatch
+}
+
+// Next 返回严格晚于 after 的下一次触发时间，找不到时（如 2月30日）返回零值
+func (c *cronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	// 最多向后查找5年，覆盖闰年的 2月29日
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
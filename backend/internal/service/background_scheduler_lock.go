@@ -0,0 +1,69 @@
+package service
+
+import (
+	"time"
+
+	"auralogic/internal/pkg/cache"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const backgroundJobLockKeyPrefix = "background_job:lock:"
+
+// 仅持有者可续期 / 释放，避免锁过期后误删其他进程的锁
+var backgroundJobLockReleaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+var backgroundJobLockRefreshScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// backgroundJobLock 多进程（多个 worker / 多副本）之间协调同一任务的分布式锁
+type backgroundJobLock struct {
+	key   string
+	token string
+	ttl   time.Duration
+}
+
+// tryBackgroundJobLock 尝试获取任务锁，被其他进程持有时返回 nil；未初始化 Redis 时（单进程/测试）直接放行
+func tryBackgroundJobLock(name string, ttl time.Duration) (*backgroundJobLock, error) {
+	lock := &backgroundJobLock{key: backgroundJobLockKeyPrefix + name, token: uuid.New().String(), ttl: ttl}
+	if cache.RedisClient == nil {
+		return lock, nil
+	}
+	acquired, err := cache.SetNX(lock.key, lock.token, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, nil
+	}
+	return lock, nil
+}
+
+// Refresh 续期，返回 false 表示锁已过期并被其他进程取得
+func (l *backgroundJobLock) Refresh() (bool, error) {
+	if cache.RedisClient == nil {
+		return true, nil
+	}
+	result, err := backgroundJobLockRefreshScript.Run(cache.RedisClient.Context(), cache.RedisClient, []string{l.key}, l.token, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// Release 释放锁
+func (l *backgroundJobLock) Release() error {
+	if cache.RedisClient == nil {
+		return nil
+	}
+	return backgroundJobLockReleaseScript.Run(cache.RedisClient.Context(), cache.RedisClient, []string{l.key}, l.token).Err()
+}
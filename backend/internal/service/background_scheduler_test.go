@@ -0,0 +1,224 @@
+package service
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"auralogic/internal/pkg/cache"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func waitBackgroundJobStatus(t *testing.T, scheduler *BackgroundScheduler, name string, ok func(BackgroundJobStatus) bool) BackgroundJobStatus {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		for _, status := range scheduler.Statuses() {
+			if status.Name == name && ok(status) {
+				return status
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for job %s, statuses: %+v", name, scheduler.Statuses())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBackgroundSchedulerRecordsRunsAndRecoversPanics(t *testing.T) {
+	scheduler := NewBackgroundScheduler()
+	defer scheduler.Stop()
+
+	var calls atomic.Int32
+	if err := scheduler.Register(BackgroundJob{
+		Name:       "flaky",
+		Interval:   10 * time.Millisecond,
+		RunOnStart: true,
+		Run: func() error {
+			switch calls.Add(1) {
+			case 1:
+				panic("boom")
+			case 2:
+				return errors.New("upstream unavailable")
+			}
+			return nil
+		},
+	}); err != nil {
+		t.Fatalf("register job: %v", err)
+	}
+	if err := scheduler.Register(BackgroundJob{Name: "flaky", Interval: time.Minute, Run: func() error { return nil }}); err == nil {
+		t.Fatal("duplicate job name must be rejected")
+	}
+
+	status := waitBackgroundJobStatus(t, scheduler, "flaky", func(s BackgroundJobStatus) bool { return s.RunCount >= 3 })
+	if status.FailureCount != 2 || status.LastError != "" || !status.Healthy {
+		t.Fatalf("unexpected status after recovery: %+v", status)
+	}
+	if status.Kind != BackgroundJobKindInterval || status.NextRunAt == nil {
+		t.Fatalf("expected interval job with next run, got %+v", status)
+	}
+
+	scheduler.Remove("flaky")
+	if len(scheduler.Statuses()) != 0 {
+		t.Fatal("removed job should not be listed")
+	}
+}
+
+func TestBackgroundSchedulerAppliesJitterToScheduledRuns(t *testing.T) {
+	scheduler := NewBackgroundScheduler()
+	defer scheduler.Stop()
+
+	registeredAt := time.Now()
+	if err := scheduler.Register(BackgroundJob{
+		Name:     "jittered",
+		Interval: time.Hour,
+		Jitter:   30 * time.Minute,
+		Run:      func() error { return nil },
+	}); err != nil {
+		t.Fatalf("register job: %v", err)
+	}
+	status := waitBackgroundJobStatus(t, scheduler, "jittered", func(s BackgroundJobStatus) bool { return s.NextRunAt != nil })
+	earliest, latest := registeredAt.Add(time.Hour), time.Now().Add(90*time.Minute)
+	if status.NextRunAt.Before(earliest) || status.NextRunAt.After(latest) || status.JitterMs != (30*time.Minute).Milliseconds() {
+		t.Fatalf("next run must fall within interval + jitter window, got %+v", status)
+	}
+}
+
+func TestBackgroundSchedulerSkipsOverlappingRuns(t *testing.T) {
+	scheduler := NewBackgroundScheduler()
+	release := make(chan struct{})
+	var active, maxActive atomic.Int32
+	if err := scheduler.Register(BackgroundJob{
+		Name:       "slow",
+		Interval:   5 * time.Millisecond,
+		RunOnStart: true,
+		Run: func() error {
+			n := active.Add(1)
+			defer active.Add(-1)
+			if n > maxActive.Load() {
+				maxActive.Store(n)
+			}
+			<-release
+			return nil
+		},
+	}); err != nil {
+		t.Fatalf("register job: %v", err)
+	}
+
+	status := waitBackgroundJobStatus(t, scheduler, "slow", func(s BackgroundJobStatus) bool { return s.SkippedCount >= 2 })
+	if !status.Running || status.Healthy {
+		t.Fatalf("overrunning job should be reported as unhealthy: %+v", status)
+	}
+	close(release)
+	scheduler.Stop()
+	if maxActive.Load() != 1 {
+		t.Fatalf("expected no concurrent runs, got %d", maxActive.Load())
+	}
+}
+
+func TestBackgroundSchedulerCoordinatesProcessesViaLock(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("start miniredis failed: %v", err)
+	}
+	defer mr.Close()
+
+	previousClient := cache.RedisClient
+	cache.RedisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer func() {
+		if cache.RedisClient != nil {
+			_ = cache.RedisClient.Close()
+		}
+		cache.RedisClient = previousClient
+	}()
+
+	// 模拟另一个 worker 持有锁
+	if err := mr.Set(backgroundJobLockKeyPrefix+"locked", "other-worker"); err != nil {
+		t.Fatalf("seed lock: %v", err)
+	}
+	scheduler := NewBackgroundScheduler()
+	defer scheduler.Stop()
+
+	var runs atomic.Int32
+	if err := scheduler.Register(BackgroundJob{
+		Name:       "locked",
+		Interval:   10 * time.Millisecond,
+		RunOnStart: true,
+		LockTTL:    time.Minute,
+		Run: func() error {
+			runs.Add(1)
+			return nil
+		},
+	}); err != nil {
+		t.Fatalf("register job: %v", err)
+	}
+	status := waitBackgroundJobStatus(t, scheduler, "locked", func(s BackgroundJobStatus) bool { return s.LockSkippedCount >= 2 })
+	if runs.Load() != 0 || status.RunCount != 0 || !status.Healthy {
+		t.Fatalf("job must not run while another process holds the lock: runs=%d status=%+v", runs.Load(), status)
+	}
+
+	mr.Del(backgroundJobLockKeyPrefix + "locked")
+	waitBackgroundJobStatus(t, scheduler, "locked", func(s BackgroundJobStatus) bool { return s.RunCount >= 1 })
+	scheduler.Remove("locked")
+	if mr.Exists(backgroundJobLockKeyPrefix + "locked") {
+		t.Fatal("lock should be released after each run")
+	}
+
+	// 常驻任务仅由持锁进程运行
+	var loops atomic.Int32
+	leader := NewBackgroundScheduler()
+	standby := NewBackgroundScheduler()
+	pollerJob := BackgroundJob{
+		Name:    "poller",
+		LockTTL: 150 * time.Millisecond,
+		Loop: func(stopChan <-chan struct{}) {
+			loops.Add(1)
+			<-stopChan
+		},
+	}
+	if err := leader.Register(pollerJob); err != nil {
+		t.Fatalf("register leader loop: %v", err)
+	}
+	waitBackgroundJobStatus(t, leader, "poller", func(s BackgroundJobStatus) bool { return s.Running })
+	if err := standby.Register(pollerJob); err != nil {
+		t.Fatalf("register standby loop: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if running := standby.Statuses(); len(running) == 0 || running[0].Running {
+		t.Fatalf("standby must not run while leader holds the lock: %+v", running)
+	}
+	leader.Stop()
+	waitBackgroundJobStatus(t, standby, "poller", func(s BackgroundJobStatus) bool { return s.Running })
+	standby.Stop()
+	if loops.Load() != 2 {
+		t.Fatalf("expected leader then standby to run the loop once each, got %d", loops.Load())
+	}
+}
+
+func TestParseCronSchedule(t *testing.T) {
+	schedule, err := parseCronSchedule("30 2 * * 1-5")
+	if err != nil {
+		t.Fatalf("parse cron: %v", err)
+	}
+	// 2026-10-16 是周五
+	from := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	if next := schedule.Next(from); !next.Equal(time.Date(2026, 10, 19, 2, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected next monday 02:30, got %v", next)
+	}
+
+	every15, err := parseCronSchedule("*/15 * * * *")
+	if err != nil {
+		t.Fatalf("parse step cron: %v", err)
+	}
+	if next := every15.Next(time.Date(2026, 1, 1, 10, 15, 0, 0, time.UTC)); !next.Equal(time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC)) {
+		t.Fatalf("next must be strictly after the given time, got %v", next)
+	}
+
+	for _, expr := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *"} {
+		if _, err := parseCronSchedule(expr); err == nil {
+			t.Fatalf("expected %q to be rejected", expr)
+		}
+	}
+}
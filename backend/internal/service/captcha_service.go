@@ -40,31 +40,67 @@ func init() {
 	}
 }
 
-// VerifyCaptcha 验证验证码token
-func (s *CaptchaService) VerifyCaptcha(token string, remoteIP string) error {
-	cfg := config.GetConfig()
-	captchaCfg := cfg.Security.Captcha
+// 验证码驱动名称
+const (
+	CaptchaProviderNone        = "none"
+	CaptchaProviderTurnstile   = "cloudflare"
+	CaptchaProviderRecaptchaV2 = "google"
+	CaptchaProviderRecaptchaV3 = "recaptcha_v3"
+	CaptchaProviderHCaptcha    = "hcaptcha"
+	CaptchaProviderBuiltin     = "builtin"
+)
+
+// CaptchaScenes 可受验证码保护的场景
+var CaptchaScenes = []string{"login", "register", "serial_verify", "bind"}
+
+// captchaDriver 验证码校验驱动
+type captchaDriver interface {
+	verify(keys config.CaptchaProviderKeys, token, remoteIP, scene string, captchaCfg config.CaptchaConfig) error
+}
+
+var captchaHTTPClient = &http.Client{Timeout: 10 * time.Second}
 
-	switch captchaCfg.Provider {
-	case "none", "":
+var captchaDrivers = map[string]captchaDriver{
+	CaptchaProviderTurnstile:   siteverifyCaptchaDriver{endpoint: "https://challenges.cloudflare.com/turnstile/v0/siteverify"},
+	CaptchaProviderRecaptchaV2: siteverifyCaptchaDriver{endpoint: "https://www.google.com/recaptcha/api/siteverify"},
+	CaptchaProviderRecaptchaV3: siteverifyCaptchaDriver{endpoint: "https://www.google.com/recaptcha/api/siteverify", scored: true},
+	CaptchaProviderHCaptcha:    siteverifyCaptchaDriver{endpoint: "https://api.hcaptcha.com/siteverify", sendSiteKey: true},
+	CaptchaProviderBuiltin:     builtinCaptchaDriver{},
+}
+
+// IsCaptchaProvider 是否为支持的验证码驱动（含 none）
+func IsCaptchaProvider(provider string) bool {
+	if provider == CaptchaProviderNone {
+		return true
+	}
+	_, ok := captchaDrivers[provider]
+	return ok
+}
+
+// CaptchaProviderRequiresKeys 驱动是否需要站点/服务端密钥
+func CaptchaProviderRequiresKeys(provider string) bool {
+	_, ok := captchaDrivers[provider].(siteverifyCaptchaDriver)
+	return ok
+}
+
+// VerifyCaptcha 按场景实际使用的驱动验证验证码token
+func (s *CaptchaService) VerifyCaptcha(scene, token, remoteIP string) error {
+	captchaCfg := config.GetConfig().Security.Captcha
+	provider, keys := captchaCfg.ResolveScene(scene)
+	if provider == CaptchaProviderNone || provider == "" {
 		return nil
-	case "cloudflare":
-		return s.verifyCloudflare(captchaCfg.SecretKey, token, remoteIP)
-	case "google":
-		return s.verifyGoogle(captchaCfg.SecretKey, token, remoteIP)
-	case "builtin":
-		return s.verifyBuiltin(token)
-	default:
-		return fmt.Errorf("unknown captcha provider: %s", captchaCfg.Provider)
 	}
+	driver, ok := captchaDrivers[provider]
+	if !ok {
+		return fmt.Errorf("unknown captcha provider: %s", provider)
+	}
+	return driver.verify(keys, token, remoteIP, scene, captchaCfg)
 }
 
 // NeedCaptcha 判断指定场景是否需要验证码
 func (s *CaptchaService) NeedCaptcha(scene string) bool {
-	cfg := config.GetConfig()
-	captchaCfg := cfg.Security.Captcha
-
-	if captchaCfg.Provider == "none" || captchaCfg.Provider == "" {
+	captchaCfg := config.GetConfig().Security.Captcha
+	if provider, _ := captchaCfg.ResolveScene(scene); provider == CaptchaProviderNone || provider == "" {
 		return false
 	}
 
@@ -110,8 +146,11 @@ func (s *CaptchaService) GenerateBuiltinCaptcha() (string, string, error) {
 	return captchaID, imgBase64, nil
 }
 
-// verifyBuiltin 验证内置验证码
-func (s *CaptchaService) verifyBuiltin(token string) error {
+// builtinCaptchaDriver 自托管图片验证码，作为第三方驱动不可用时的回退
+type builtinCaptchaDriver struct{}
+
+// verify 验证内置验证码
+func (builtinCaptchaDriver) verify(_ config.CaptchaProviderKeys, token, _ string, _ string, _ config.CaptchaConfig) error {
 	var captchaID, userCode string
 	for i := len(token) - 1; i >= 0; i-- {
 		if token[i] == ':' {
@@ -150,55 +189,63 @@ func (s *CaptchaService) verifyBuiltin(token string) error {
 	return nil
 }
 
-// verifyCloudflare 验证Cloudflare Turnstile
-func (s *CaptchaService) verifyCloudflare(secret, token, remoteIP string) error {
-	resp, err := http.PostForm("https://challenges.cloudflare.com/turnstile/v0/siteverify", url.Values{
-		"secret":   {secret},
-		"response": {token},
-		"remoteip": {remoteIP},
-	})
-	if err != nil {
-		return fmt.Errorf("failed to verify captcha: %w", err)
-	}
-	defer resp.Body.Close()
+// siteverifyCaptchaDriver Turnstile / reCAPTCHA / hCaptcha 通用的 siteverify 校验
+type siteverifyCaptchaDriver struct {
+	endpoint    string
+	scored      bool // reCAPTCHA v3：校验分数与 action
+	sendSiteKey bool // hCaptcha：附带 sitekey 防止跨站点 token 复用
+}
+
+type captchaSiteverifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score"`
+	Action     string   `json:"action"`
+	ErrorCodes []string `json:"error-codes"`
+}
 
-	var result struct {
-		Success bool `json:"success"`
+func (d siteverifyCaptchaDriver) verify(keys config.CaptchaProviderKeys, token, remoteIP, scene string, captchaCfg config.CaptchaConfig) error {
+	if strings.TrimSpace(token) == "" {
+		return fmt.Errorf("captcha token is required")
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return fmt.Errorf("failed to parse captcha response: %w", err)
+	if strings.TrimSpace(keys.SecretKey) == "" {
+		return fmt.Errorf("captcha secret key is not configured")
 	}
-
-	if !result.Success {
-		return fmt.Errorf("captcha verification failed")
+	form := url.Values{
+		"secret":   {keys.SecretKey},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	if d.sendSiteKey && keys.SiteKey != "" {
+		form.Set("sitekey", keys.SiteKey)
 	}
 
-	return nil
-}
-
-// verifyGoogle 验证Google reCAPTCHA
-func (s *CaptchaService) verifyGoogle(secret, token, remoteIP string) error {
-	resp, err := http.PostForm("https://www.google.com/recaptcha/api/siteverify", url.Values{
-		"secret":   {secret},
-		"response": {token},
-		"remoteip": {remoteIP},
-	})
+	resp, err := captchaHTTPClient.PostForm(d.endpoint, form)
 	if err != nil {
 		return fmt.Errorf("failed to verify captcha: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var result struct {
-		Success bool `json:"success"`
-	}
+	var result captchaSiteverifyResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return fmt.Errorf("failed to parse captcha response: %w", err)
 	}
-
 	if !result.Success {
-		return fmt.Errorf("captcha verification failed")
+		return fmt.Errorf("captcha verification failed: %s", strings.Join(result.ErrorCodes, ","))
+	}
+	if d.scored {
+		minScore := captchaCfg.RecaptchaMinScore
+		if minScore <= 0 {
+			minScore = 0.5
+		}
+		if result.Score == nil || *result.Score < minScore {
+			return fmt.Errorf("captcha score below threshold")
+		}
+		if result.Action != "" && result.Action != scene {
+			return fmt.Errorf("captcha action mismatch: %s", result.Action)
+		}
 	}
-
 	return nil
 }
 
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"auralogic/internal/config"
+)
+
+func TestSiteverifyCaptchaDriverChecksScoreActionAndSiteKey(t *testing.T) {
+	var lastForm map[string]string
+	score := 0.9
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		lastForm = map[string]string{"secret": r.PostForm.Get("secret"), "response": r.PostForm.Get("response"), "sitekey": r.PostForm.Get("sitekey")}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": r.PostForm.Get("response") != "bad", "score": score, "action": "login"})
+	}))
+	defer server.Close()
+
+	keys := config.CaptchaProviderKeys{SiteKey: "site", SecretKey: "secret"}
+	captchaCfg := config.CaptchaConfig{RecaptchaMinScore: 0.5}
+
+	v3 := siteverifyCaptchaDriver{endpoint: server.URL, scored: true}
+	if err := v3.verify(keys, "token", "203.0.113.5", "login", captchaCfg); err != nil {
+		t.Fatalf("expected high score to pass: %v", err)
+	}
+	if err := v3.verify(keys, "token", "", "register", captchaCfg); err == nil {
+		t.Fatal("token issued for another action must be rejected")
+	}
+	score = 0.3
+	if err := v3.verify(keys, "token", "", "login", captchaCfg); err == nil {
+		t.Fatal("score below threshold must be rejected")
+	}
+
+	hcaptcha := siteverifyCaptchaDriver{endpoint: server.URL, sendSiteKey: true}
+	if err := hcaptcha.verify(keys, "token", "", "register", captchaCfg); err != nil {
+		t.Fatalf("hcaptcha ignores score: %v", err)
+	}
+	if lastForm["sitekey"] != "site" || lastForm["secret"] != "secret" {
+		t.Fatalf("hcaptcha should send secret and sitekey, got %+v", lastForm)
+	}
+	if err := hcaptcha.verify(keys, "bad", "", "register", captchaCfg); err == nil {
+		t.Fatal("unsuccessful siteverify must be rejected")
+	}
+	if err := hcaptcha.verify(config.CaptchaProviderKeys{}, "token", "", "register", captchaCfg); err == nil {
+		t.Fatal("missing secret must be rejected")
+	}
+}
+
+func TestCaptchaProviderRegistry(t *testing.T) {
+	for _, provider := range []string{"none", "cloudflare", "google", "recaptcha_v3", "hcaptcha", "builtin"} {
+		if !IsCaptchaProvider(provider) {
+			t.Fatalf("%s should be a supported provider", provider)
+		}
+	}
+	if IsCaptchaProvider("geetest") {
+		t.Fatal("unknown providers must be rejected")
+	}
+	if CaptchaProviderRequiresKeys("builtin") || !CaptchaProviderRequiresKeys("hcaptcha") {
+		t.Fatal("only third-party providers require keys")
+	}
+}
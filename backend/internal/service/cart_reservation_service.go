@@ -0,0 +1,231 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/cache"
+	"github.com/go-redis/redis/v8"
+)
+
+// 购物车预留记录：每个商品规格一个 Hash，field 为用户ID，value 为 "数量:到期毫秒时间戳"
+var cartReservationReserveScript = redis.NewScript(`
+local key = KEYS[1]
+local user = ARGV[1]
+local qty = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local expires = tonumber(ARGV[4])
+local stock = tonumber(ARGV[5])
+local others = 0
+local fields = redis.call('HGETALL', key)
+for i = 1, #fields, 2 do
+  local q, exp = string.match(fields[i + 1], '^(%d+):(%d+)$')
+  if not q or tonumber(exp) <= now then
+    redis.call('HDEL', key, fields[i])
+  elseif fields[i] ~= user then
+    others = others + tonumber(q)
+  end
+end
+local available = stock - others
+if available < 0 then
+  available = 0
+end
+if qty > available then
+  return {0, available}
+end
+redis.call('HSET', key, user, ARGV[2] .. ':' .. ARGV[4])
+if redis.call('PTTL', key) < expires - now then
+  redis.call('PEXPIRE', key, expires - now)
+end
+return {1, available}
+`)
+
+type cartReservationEntry struct {
+	Quantity  int
+	ExpiresAt time.Time
+}
+
+// CartReservationService 购物车短时预留（加购即预留）
+// 预留只在 Redis（未配置时为进程内存）中记账，不改动数据库库存；到期自动失效，
+// 下单成功后由订单预留接管并释放购物车预留
+type CartReservationService struct {
+	cfg    *config.Config
+	mu     sync.Mutex
+	memory map[string]map[uint]cartReservationEntry
+	now    func() time.Time
+}
+
+func NewCartReservationService(cfg *config.Config) *CartReservationService {
+	return &CartReservationService{
+		cfg:    cfg,
+		memory: make(map[string]map[uint]cartReservationEntry),
+		now:    time.Now,
+	}
+}
+
+// TTL 预留有效时长
+func (s *CartReservationService) TTL() time.Duration {
+	if s.cfg != nil && s.cfg.Order.CartReservationTTLSeconds > 0 {
+		return time.Duration(s.cfg.Order.CartReservationTTLSeconds) * time.Second
+	}
+	return 10 * time.Minute
+}
+
+func cartReservationKey(productID uint, attributesHash string) string {
+	return fmt.Sprintf("cart:reserve:%d:%s", productID, attributesHash)
+}
+
+// Reserve 将用户在该规格上的预留设为 quantity 件并刷新有效期（覆盖此前的预留）
+// stock 为当前实际可用库存，扣除其他用户的有效预留后不足时返回 cart.stockInsufficient
+func (s *CartReservationService) Reserve(productID uint, attributesHash string, userID uint, quantity, stock int) (time.Time, error) {
+	now := s.now()
+	expiresAt := now.Add(s.TTL())
+	key := cartReservationKey(productID, attributesHash)
+
+	if cache.RedisClient != nil {
+		result, err := cartReservationReserveScript.Run(
+			cache.RedisClient.Context(),
+			cache.RedisClient,
+			[]string{key},
+			userID,
+			quantity,
+			now.UnixMilli(),
+			expiresAt.UnixMilli(),
+			stock,
+		).Int64Slice()
+		if err == nil && len(result) == 2 {
+			if result[0] != 1 {
+				return time.Time{}, newCartReservationInsufficientError(int(result[1]))
+			}
+			return expiresAt, nil
+		}
+		log.Printf("cart reservation redis reserve failed, fallback to memory: key=%s err=%v", key, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.memoryEntriesLocked(key, now)
+	available := stock
+	for holder, entry := range entries {
+		if holder != userID {
+			available -= entry.Quantity
+		}
+	}
+	if available < 0 {
+		available = 0
+	}
+	if quantity > available {
+		return time.Time{}, newCartReservationInsufficientError(available)
+	}
+	entries[userID] = cartReservationEntry{Quantity: quantity, ExpiresAt: expiresAt}
+	return expiresAt, nil
+}
+
+// ReservedByOthers 其他用户在该规格上仍有效的预留数量
+func (s *CartReservationService) ReservedByOthers(productID uint, attributesHash string, userID uint) int {
+	total := 0
+	for holder, entry := range s.liveEntries(productID, attributesHash) {
+		if holder != userID {
+			total += entry.Quantity
+		}
+	}
+	return total
+}
+
+// Reservation 用户在该规格上的有效预留数量及到期时间，不存在或已过期时返回 0, nil
+func (s *CartReservationService) Reservation(productID uint, attributesHash string, userID uint) (int, *time.Time) {
+	entry, ok := s.liveEntries(productID, attributesHash)[userID]
+	if !ok {
+		return 0, nil
+	}
+	return entry.Quantity, &entry.ExpiresAt
+}
+
+// Release 释放用户在该规格上的预留
+func (s *CartReservationService) Release(productID uint, attributesHash string, userID uint) {
+	key := cartReservationKey(productID, attributesHash)
+	if cache.RedisClient != nil {
+		if err := cache.RedisClient.HDel(cache.RedisClient.Context(), key, strconv.FormatUint(uint64(userID), 10)).Err(); err != nil {
+			log.Printf("cart reservation redis release failed: key=%s err=%v", key, err)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entries, ok := s.memory[key]; ok {
+		delete(entries, userID)
+		if len(entries) == 0 {
+			delete(s.memory, key)
+		}
+	}
+}
+
+func (s *CartReservationService) liveEntries(productID uint, attributesHash string) map[uint]cartReservationEntry {
+	now := s.now()
+	key := cartReservationKey(productID, attributesHash)
+	result := make(map[uint]cartReservationEntry)
+
+	if cache.RedisClient != nil {
+		fields, err := cache.RedisClient.HGetAll(cache.RedisClient.Context(), key).Result()
+		if err == nil {
+			for field, value := range fields {
+				holder, parseErr := strconv.ParseUint(field, 10, 64)
+				entry, ok := parseCartReservationValue(value)
+				if parseErr != nil || !ok || !entry.ExpiresAt.After(now) {
+					continue
+				}
+				result[uint(holder)] = entry
+			}
+			return result
+		}
+		log.Printf("cart reservation redis read failed, fallback to memory: key=%s err=%v", key, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for holder, entry := range s.memoryEntriesLocked(key, now) {
+		result[holder] = entry
+	}
+	return result
+}
+
+// memoryEntriesLocked 返回内存中该规格的预留并清理过期项，调用方需持有 s.mu
+func (s *CartReservationService) memoryEntriesLocked(key string, now time.Time) map[uint]cartReservationEntry {
+	entries, ok := s.memory[key]
+	if !ok {
+		entries = make(map[uint]cartReservationEntry)
+		s.memory[key] = entries
+	}
+	for holder, entry := range entries {
+		if !entry.ExpiresAt.After(now) {
+			delete(entries, holder)
+		}
+	}
+	return entries
+}
+
+func parseCartReservationValue(value string) (cartReservationEntry, bool) {
+	quantityText, expiresText, found := strings.Cut(value, ":")
+	if !found {
+		return cartReservationEntry{}, false
+	}
+	quantity, err := strconv.Atoi(quantityText)
+	if err != nil {
+		return cartReservationEntry{}, false
+	}
+	expiresMs, err := strconv.ParseInt(expiresText, 10, 64)
+	if err != nil {
+		return cartReservationEntry{}, false
+	}
+	return cartReservationEntry{Quantity: quantity, ExpiresAt: time.UnixMilli(expiresMs)}, true
+}
+
+func newCartReservationInsufficientError(available int) error {
+	return bizerr.Newf("cart.stockInsufficient", "Insufficient stock, available: %d", available).
+		WithParams(map[string]interface{}{"available": available})
+}
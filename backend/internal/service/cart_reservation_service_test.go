@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/pkg/cache"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func exerciseCartReservation(t *testing.T, svc *CartReservationService, advance func(time.Duration)) {
+	t.Helper()
+
+	if _, err := svc.Reserve(1, "red", 10, 3, 5); err != nil {
+		t.Fatalf("reserve for user 10: %v", err)
+	}
+	err := func() error {
+		_, err := svc.Reserve(1, "red", 20, 3, 5)
+		return err
+	}()
+	if bizErr := requireBizErr(t, err, "cart.stockInsufficient"); bizErr.Params["available"] != 2 {
+		t.Fatalf("expected 2 available for user 20, got %v", bizErr.Params)
+	}
+	if _, err := svc.Reserve(1, "red", 20, 2, 5); err != nil {
+		t.Fatalf("reserve remaining stock: %v", err)
+	}
+	// 调整自己的预留数量不受自身已有预留影响
+	if _, err := svc.Reserve(1, "red", 10, 3, 5); err != nil {
+		t.Fatalf("re-reserve for user 10: %v", err)
+	}
+	if others := svc.ReservedByOthers(1, "red", 20); others != 3 {
+		t.Fatalf("expected 3 reserved by others, got %d", others)
+	}
+	if others := svc.ReservedByOthers(1, "blue", 20); others != 0 {
+		t.Fatalf("other variants must not be affected, got %d", others)
+	}
+
+	svc.Release(1, "red", 10)
+	if quantity, until := svc.Reservation(1, "red", 10); quantity != 0 || until != nil {
+		t.Fatalf("expected released reservation, got %d", quantity)
+	}
+
+	advance(svc.TTL() + time.Second)
+	if quantity, until := svc.Reservation(1, "red", 20); quantity != 0 || until != nil {
+		t.Fatalf("expected reservation to expire, got %d", quantity)
+	}
+	if _, err := svc.Reserve(1, "red", 30, 5, 5); err != nil {
+		t.Fatalf("expired reservations should be released: %v", err)
+	}
+}
+
+func TestCartReservationMemoryMode(t *testing.T) {
+	previousClient := cache.RedisClient
+	cache.RedisClient = nil
+	defer func() { cache.RedisClient = previousClient }()
+
+	cfg := &config.Config{}
+	cfg.Order.CartReservationTTLSeconds = 60
+	svc := NewCartReservationService(cfg)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	exerciseCartReservation(t, svc, func(d time.Duration) { now = now.Add(d) })
+}
+
+func TestCartReservationRedisMode(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("start miniredis failed: %v", err)
+	}
+	defer mr.Close()
+
+	previousClient := cache.RedisClient
+	cache.RedisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer func() {
+		_ = cache.RedisClient.Close()
+		cache.RedisClient = previousClient
+	}()
+
+	cfg := &config.Config{}
+	cfg.Order.CartReservationTTLSeconds = 60
+	svc := NewCartReservationService(cfg)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	exerciseCartReservation(t, svc, func(d time.Duration) {
+		now = now.Add(d)
+		mr.FastForward(d)
+	})
+	if len(svc.memory) != 0 {
+		t.Fatalf("redis mode should not fall back to memory")
+	}
+}
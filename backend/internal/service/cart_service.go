@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"time"
 
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/bizerr"
@@ -14,6 +15,9 @@ type CartService struct {
 	productRepo             *repository.ProductRepository
 	bindingService          *BindingService
 	virtualInventoryService *VirtualInventoryService
+	giftPromotionService    *GiftPromotionService
+	reservationService      *CartReservationService
+	userRepo                *repository.UserRepository
 }
 
 func NewCartService(cartRepo *repository.CartRepository, productRepo *repository.ProductRepository, bindingService *BindingService, virtualInventoryService *VirtualInventoryService) *CartService {
@@ -25,6 +29,76 @@ func NewCartService(cartRepo *repository.CartRepository, productRepo *repository
 	}
 }
 
+// SetGiftPromotionService 注入满赠活动服务
+func (s *CartService) SetGiftPromotionService(giftPromotionService *GiftPromotionService) {
+	s.giftPromotionService = giftPromotionService
+}
+
+// SetReservationService 注入购物车预留服务（加购即预留）
+func (s *CartService) SetReservationService(reservationService *CartReservationService) {
+	s.reservationService = reservationService
+}
+
+// SetUserRepository 注入用户仓储，用于按客户等级判断询价商品能否加购
+func (s *CartService) SetUserRepository(userRepo *repository.UserRepository) {
+	s.userRepo = userRepo
+}
+
+// priceHiddenForUser 询价商品对该用户隐藏价格时返回 true
+func (s *CartService) priceHiddenForUser(product *models.Product, userID uint) bool {
+	if product == nil || !product.PriceOnRequest {
+		return false
+	}
+	tier := ""
+	if s.userRepo != nil {
+		if user, err := s.userRepo.FindByID(userID); err == nil && user != nil {
+			tier = user.CustomerTier
+		}
+	}
+	return product.PriceHiddenFor(tier)
+}
+
+// reserveForCart 开启加购即预留的商品占用库存，返回预留到期时间；未开启时返回 nil
+func (s *CartService) reserveForCart(product *models.Product, userID uint, attributes models.JSONMap, quantity, stock int) (*time.Time, error) {
+	if s.reservationService == nil || product == nil || !product.ReserveOnCart {
+		return nil, nil
+	}
+	expiresAt, err := s.reservationService.Reserve(product.ID, models.GenerateAttributesHash(attributes), userID, quantity, stock)
+	if err != nil {
+		return nil, err
+	}
+	return &expiresAt, nil
+}
+
+// releaseCartReservation 释放购物车项的预留
+func (s *CartService) releaseCartReservation(item *models.CartItem) {
+	if s.reservationService == nil || item == nil {
+		return
+	}
+	s.reservationService.Release(item.ProductID, models.GenerateAttributesHash(item.Attributes), item.UserID)
+}
+
+// checkCartQuantityRules 校验商品数量规则：起订量与包装倍数按单个购物车项，单笔上限按购物车内该商品所有规格合计
+func (s *CartService) checkCartQuantityRules(product *models.Product, userID, itemID uint, quantity int) error {
+	if err := checkProductLineQuantity(product, quantity); err != nil {
+		return err
+	}
+	if product == nil || product.MaxOrderQuantity <= 0 {
+		return nil
+	}
+	items, err := s.cartRepo.GetUserCart(userID)
+	if err != nil {
+		return err
+	}
+	total := quantity
+	for _, item := range items {
+		if item.ProductID == product.ID && item.ID != itemID {
+			total += item.Quantity
+		}
+	}
+	return checkProductOrderQuantity(product, total)
+}
+
 // AddToCartRequest 添加到购物车请求
 type AddToCartRequest struct {
 	ProductID  uint              `json:"product_id" binding:"required"`
@@ -51,6 +125,12 @@ func (s *CartService) GetCart(userID uint) ([]models.CartItemWithStock, error) {
 		if item.Product == nil || item.Product.Status != models.ProductStatusActive {
 			itemWithStock.IsAvailable = false
 			itemWithStock.AvailableStock = 0
+		} else if s.priceHiddenForUser(item.Product, userID) {
+			// 加购后商品改为询价模式：不可结算，也不再展示价格
+			itemWithStock.IsAvailable = false
+			itemWithStock.AvailableStock = 0
+			itemWithStock.Price = 0
+			itemWithStock.Product.HidePrice()
 		} else {
 			// 获取可用库存
 			stock, err := s.getAvailableStock(item.ProductID, item.Attributes)
@@ -58,6 +138,15 @@ func (s *CartService) GetCart(userID uint) ([]models.CartItemWithStock, error) {
 				itemWithStock.AvailableStock = 0
 				itemWithStock.IsAvailable = false
 			} else {
+				if s.reservationService != nil && item.Product.ReserveOnCart {
+					// 扣除其他用户的购物车预留
+					attributesHash := models.GenerateAttributesHash(item.Attributes)
+					stock -= s.reservationService.ReservedByOthers(item.ProductID, attributesHash, userID)
+					if stock < 0 {
+						stock = 0
+					}
+					_, itemWithStock.ReservedUntil = s.reservationService.Reservation(item.ProductID, attributesHash, userID)
+				}
 				itemWithStock.AvailableStock = stock
 				itemWithStock.IsAvailable = stock >= item.Quantity
 			}
@@ -65,10 +154,33 @@ func (s *CartService) GetCart(userID uint) ([]models.CartItemWithStock, error) {
 
 		result = append(result, itemWithStock)
 	}
+	applyCartTierPricing(result)
 
 	return result, nil
 }
 
+// GetCartGifts 按当前购物车计算可获得的赠品
+// 只统计可购买的商品，按商品当前价格（含阶梯价）计算小计，与下单时一致；不满足条件的赠品不会返回
+func (s *CartService) GetCartGifts(items []models.CartItemWithStock) ([]GiftLine, error) {
+	if s.giftPromotionService == nil {
+		return []GiftLine{}, nil
+	}
+	var subtotal int64
+	quantities := cartQuantitiesByProduct(items)
+	productIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		if !item.IsAvailable || item.Product == nil {
+			continue
+		}
+		subtotal += item.Product.UnitPriceForQuantity(quantities[item.ProductID]) * int64(item.Quantity)
+		productIDs = append(productIDs, item.ProductID)
+	}
+	if len(productIDs) == 0 {
+		return []GiftLine{}, nil
+	}
+	return s.giftPromotionService.ResolveGifts(subtotal, productIDs)
+}
+
 // getAvailableStock 获取商品可用库存
 func (s *CartService) getAvailableStock(productID uint, attributes models.JSONMap) (int, error) {
 	product, err := s.productRepo.FindByID(productID)
@@ -111,6 +223,9 @@ func (s *CartService) AddToCart(userID uint, req AddToCartRequest) (*models.Cart
 	if product.Status != models.ProductStatusActive {
 		return nil, bizerr.New("cart.productUnavailable", "Product is no longer available")
 	}
+	if s.priceHiddenForUser(product, userID) {
+		return nil, newProductPriceOnRequestError(product.Name)
+	}
 
 	// 验证属性是否有效（对于需要选择属性的商品）
 	if len(product.Attributes) > 0 {
@@ -165,11 +280,20 @@ func (s *CartService) AddToCart(userID uint, req AddToCartRequest) (*models.Cart
 			return nil, bizerr.Newf("cart.purchaseLimitExceeded", "Exceeds purchase limit, maximum allowed: %d", product.MaxPurchaseLimit).
 				WithParams(map[string]interface{}{"limit": product.MaxPurchaseLimit})
 		}
+		if err := s.checkCartQuantityRules(product, userID, existingItem.ID, newQuantity); err != nil {
+			return nil, err
+		}
+
+		reservedUntil, err := s.reserveForCart(product, userID, attributes, newQuantity, stock)
+		if err != nil {
+			return nil, err
+		}
 
 		existingItem.Quantity = newQuantity
 		if err := s.cartRepo.UpdateCartItem(existingItem); err != nil {
 			return nil, err
 		}
+		existingItem.ReservedUntil = reservedUntil
 		return existingItem, nil
 	}
 
@@ -190,6 +314,9 @@ func (s *CartService) AddToCart(userID uint, req AddToCartRequest) (*models.Cart
 		return nil, bizerr.Newf("cart.purchaseLimitExceeded", "Exceeds purchase limit, maximum allowed: %d", product.MaxPurchaseLimit).
 			WithParams(map[string]interface{}{"limit": product.MaxPurchaseLimit})
 	}
+	if err := s.checkCartQuantityRules(product, userID, 0, req.Quantity); err != nil {
+		return nil, err
+	}
 
 	// 获取商品主图
 	imageURL := ""
@@ -205,6 +332,11 @@ func (s *CartService) AddToCart(userID uint, req AddToCartRequest) (*models.Cart
 		}
 	}
 
+	reservedUntil, err := s.reserveForCart(product, userID, attributes, req.Quantity, stock)
+	if err != nil {
+		return nil, err
+	}
+
 	newItem := &models.CartItem{
 		UserID:      userID,
 		ProductID:   req.ProductID,
@@ -218,8 +350,10 @@ func (s *CartService) AddToCart(userID uint, req AddToCartRequest) (*models.Cart
 	}
 
 	if err := s.cartRepo.CreateCartItem(newItem); err != nil {
+		s.releaseCartReservation(newItem)
 		return nil, err
 	}
+	newItem.ReservedUntil = reservedUntil
 
 	return newItem, nil
 }
@@ -259,11 +393,20 @@ func (s *CartService) UpdateQuantity(userID, itemID uint, quantity int) (*models
 		return nil, bizerr.Newf("cart.purchaseLimitExceeded", "Exceeds purchase limit, maximum allowed: %d", product.MaxPurchaseLimit).
 			WithParams(map[string]interface{}{"limit": product.MaxPurchaseLimit})
 	}
+	if err := s.checkCartQuantityRules(product, userID, item.ID, quantity); err != nil {
+		return nil, err
+	}
+
+	reservedUntil, err := s.reserveForCart(product, userID, item.Attributes, quantity, stock)
+	if err != nil {
+		return nil, err
+	}
 
 	item.Quantity = quantity
 	if err := s.cartRepo.UpdateCartItem(item); err != nil {
 		return nil, err
 	}
+	item.ReservedUntil = reservedUntil
 
 	return item, nil
 }
@@ -283,12 +426,26 @@ func (s *CartService) RemoveFromCart(userID, itemID uint) error {
 		return errors.New("No permission to modify this cart item")
 	}
 
-	return s.cartRepo.DeleteCartItem(itemID)
+	if err := s.cartRepo.DeleteCartItem(itemID); err != nil {
+		return err
+	}
+	s.releaseCartReservation(item)
+	return nil
 }
 
 // ClearCart 清空购物车
 func (s *CartService) ClearCart(userID uint) error {
-	return s.cartRepo.ClearUserCart(userID)
+	var items []models.CartItem
+	if s.reservationService != nil {
+		items, _ = s.cartRepo.GetUserCart(userID)
+	}
+	if err := s.cartRepo.ClearUserCart(userID); err != nil {
+		return err
+	}
+	for i := range items {
+		s.releaseCartReservation(&items[i])
+	}
+	return nil
 }
 
 // GetCartCount 获取购物车商品总件数
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+// PaymentSourceCODCollection 货到付款代收入账来源
+const PaymentSourceCODCollection = "cod_collection"
+
+const codCollectionMaxSettleBatch = 500
+
+// CODService 货到付款代收登记与对账
+type CODService struct {
+	db *gorm.DB
+}
+
+func NewCODService(db *gorm.DB) *CODService {
+	return &CODService{db: db}
+}
+
+// CODCarrierSummary 按承运商与币种汇总的代收对账数据
+type CODCarrierSummary struct {
+	Carrier  string `json:"carrier"`
+	Currency string `json:"currency"`
+	// 待派送收款
+	PendingCount  int64 `json:"pending_count"`
+	PendingAmount int64 `json:"pending_amount"`
+	// 已代收未回款（承运商欠款）
+	OutstandingCount  int64 `json:"outstanding_count"`
+	OutstandingAmount int64 `json:"outstanding_amount"`
+	// 已回款
+	SettledCount  int64 `json:"settled_count"`
+	SettledAmount int64 `json:"settled_amount"`
+	// 实收少于应收的差额合计
+	ShortfallAmount int64 `json:"shortfall_amount"`
+}
+
+// startCashOnDeliveryTx 选择货到付款：订单跳过付款直接进入待发货（缺收货信息时为草稿），并建立代收记录
+func startCashOnDeliveryTx(tx *gorm.DB, order *models.Order, pm *models.PaymentMethod) error {
+	for _, item := range order.Items {
+		if item.ProductType == models.ProductTypeVirtual {
+			return bizerr.New("payment.codVirtualNotSupported", "Cash on delivery is not available for orders with digital items")
+		}
+	}
+
+	nextStatus := models.OrderStatusDraft
+	if strings.TrimSpace(order.ReceiverName) != "" && strings.TrimSpace(order.ReceiverAddress) != "" {
+		nextStatus = models.OrderStatusPending
+	}
+	result := tx.Model(&models.Order{}).
+		Where("id = ? AND status = ?", order.ID, models.OrderStatusPendingPayment).
+		Updates(map[string]interface{}{
+			"status":              nextStatus,
+			"payment_deadline_at": nil,
+			"is_sandbox":          pm.Sandbox,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("order is not in pending payment status")
+	}
+
+	collection := models.CODCollection{
+		OrderID:         order.ID,
+		OrderNo:         order.OrderNo,
+		PaymentMethodID: pm.ID,
+		Currency:        order.Currency,
+		AmountDue:       order.TotalAmount,
+		Status:          models.CODCollectionStatusPending,
+	}
+	if err := tx.Where("order_id = ?", order.ID).
+		Assign(map[string]interface{}{
+			"payment_method_id": pm.ID,
+			"currency":          order.Currency,
+			"amount_due":        order.TotalAmount,
+			"status":            models.CODCollectionStatusPending,
+		}).
+		FirstOrCreate(&collection).Error; err != nil {
+		return err
+	}
+	if err := ReleasePaymentAmountReservationsTx(tx, order.ID); err != nil {
+		return err
+	}
+
+	order.Status = nextStatus
+	order.PaymentDeadlineAt = nil
+	order.IsSandbox = pm.Sandbox
+	return nil
+}
+
+// CancelCODCollectionTx 订单取消时作废尚未代收的记录
+func CancelCODCollectionTx(tx *gorm.DB, orderID uint) error {
+	return tx.Model(&models.CODCollection{}).
+		Where("order_id = ? AND status = ?", orderID, models.CODCollectionStatusPending).
+		Update("status", models.CODCollectionStatusCancelled).Error
+}
+
+// scopedCollections 代收记录没有店铺字段，按所属订单的店铺过滤
+func (s *CODService) scopedCollections(scope *repository.StoreScope) *gorm.DB {
+	query := s.db.Model(&models.CODCollection{})
+	if scope == nil || len(scope.StoreIDs) == 0 {
+		return query
+	}
+	return query.Where("order_id IN (?)", scope.Apply(s.db.Unscoped().Model(&models.Order{}).Select("id")))
+}
+
+// CollectionOrderStoreIDs 查询代收记录所属订单的店铺，用于批量操作前逐条校验店铺权限
+func (s *CODService) CollectionOrderStoreIDs(ids []uint) (map[uint]*uint, error) {
+	var rows []struct {
+		ID      uint
+		StoreID *uint
+	}
+	if err := s.db.Table("cod_collections").
+		Select("cod_collections.id, orders.store_id").
+		Joins("LEFT JOIN orders ON orders.id = cod_collections.order_id").
+		Where("cod_collections.id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	storeIDs := make(map[uint]*uint, len(rows))
+	for _, row := range rows {
+		storeIDs[row.ID] = row.StoreID
+	}
+	return storeIDs, nil
+}
+
+// ListCollections 代收记录列表，scope 限定订单所属店铺
+func (s *CODService) ListCollections(status, carrier string, scope *repository.StoreScope, page, limit int) ([]models.CODCollection, int64, error) {
+	query := s.scopedCollections(scope)
+	if status = strings.TrimSpace(status); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if carrier = strings.TrimSpace(carrier); carrier != "" {
+		query = query.Where("carrier = ?", carrier)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var items []models.CODCollection
+	if err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// RecordCollection 登记派送时承运商/司机的实收金额，并按实收金额入账
+func (s *CODService) RecordCollection(orderID uint, carrier string, collectedAmount int64, operatorID *uint, remark string) (*models.CODCollection, error) {
+	carrier = strings.TrimSpace(carrier)
+	if carrier == "" {
+		return nil, bizerr.New("cod.carrierRequired", "Carrier is required")
+	}
+	if len([]rune(carrier)) > 100 {
+		carrier = string([]rune(carrier)[:100])
+	}
+	remark = strings.TrimSpace(remark)
+	if len([]rune(remark)) > 500 {
+		remark = string([]rune(remark)[:500])
+	}
+
+	var collection models.CODCollection
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("order_id = ?", orderID).First(&collection).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return bizerr.New("cod.collectionNotFound", "Order is not a cash on delivery order")
+			}
+			return err
+		}
+		if collection.Status != models.CODCollectionStatusPending {
+			return bizerr.Newf("cod.collectionNotPending", "Collection status %s cannot be recorded again", collection.Status).
+				WithParams(map[string]interface{}{"status": collection.Status})
+		}
+		if collectedAmount <= 0 || collectedAmount > collection.AmountDue {
+			return bizerr.New("cod.invalidCollectedAmount", "Collected amount must be greater than 0 and not exceed the amount due").
+				WithParams(map[string]interface{}{"max": collection.AmountDue})
+		}
+
+		var order models.Order
+		if err := tx.First(&order, orderID).Error; err != nil {
+			return err
+		}
+		if order.Status != models.OrderStatusShipped && order.Status != models.OrderStatusCompleted {
+			return bizerr.Newf("cod.orderNotShipped", "Order status %s does not allow recording a collection", order.Status).
+				WithParams(map[string]interface{}{"status": order.Status})
+		}
+
+		now := models.NowFunc()
+		result := tx.Model(&models.CODCollection{}).
+			Where("id = ? AND status = ?", collection.ID, models.CODCollectionStatusPending).
+			Updates(map[string]interface{}{
+				"status":           models.CODCollectionStatusCollected,
+				"carrier":          carrier,
+				"collected_amount": collectedAmount,
+				"collected_at":     now,
+				"collected_by":     operatorID,
+				"remark":           remark,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return bizerr.New("cod.collectionNotPending", "Collection has already been recorded").
+				WithParams(map[string]interface{}{"status": models.CODCollectionStatusCollected})
+		}
+
+		outstanding, err := ledgerAccountBalanceTx(tx, order.ID, models.LedgerAccountCustomer)
+		if err != nil {
+			return err
+		}
+		if amount := min(collectedAmount, outstanding); amount > 0 {
+			if err := recordLedgerEventTx(tx, ledgerEventInput{
+				Kind:      models.LedgerKindPayment,
+				Order:     &order,
+				Source:    PaymentSourceCODCollection,
+				CreatedBy: operatorID,
+				Postings: []ledgerPosting{
+					{Account: models.LedgerAccountCash, Amount: amount},
+					{Account: models.LedgerAccountCustomer, Amount: -amount},
+				},
+			}); err != nil {
+				return err
+			}
+		}
+		if err := AddOrderNoteTx(tx, order.ID, operatorID, models.OrderNoteSourceCODCollection, remark); err != nil {
+			return err
+		}
+		return tx.First(&collection, collection.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	logger.LogPaymentOperation(s.db, "cod_collected", orderID, map[string]interface{}{
+		"order_no":         collection.OrderNo,
+		"carrier":          carrier,
+		"amount_due":       collection.AmountDue,
+		"collected_amount": collectedAmount,
+	})
+	return &collection, nil
+}
+
+// SettleCollections 承运商回款后将已代收记录标记为已回款，返回实际结清条数
+func (s *CODService) SettleCollections(ids []uint, operatorID *uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, bizerr.New("cod.settleEmpty", "Select at least one collection to settle")
+	}
+	if len(ids) > codCollectionMaxSettleBatch {
+		return 0, bizerr.Newf("cod.settleTooMany", "At most %d collections can be settled at once", codCollectionMaxSettleBatch).
+			WithParams(map[string]interface{}{"max": codCollectionMaxSettleBatch})
+	}
+	result := s.db.Model(&models.CODCollection{}).
+		Where("id IN ? AND status = ?", ids, models.CODCollectionStatusCollected).
+		Updates(map[string]interface{}{
+			"status":     models.CODCollectionStatusSettled,
+			"settled_at": models.NowFunc(),
+			"settled_by": operatorID,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// Report 按承运商汇总待收、未回款与已回款金额，scope 限定订单所属店铺
+func (s *CODService) Report(scope *repository.StoreScope) ([]CODCarrierSummary, error) {
+	var rows []struct {
+		Carrier         string
+		Currency        string
+		Status          models.CODCollectionStatus
+		Count           int64
+		AmountDue       int64
+		CollectedAmount int64
+	}
+	if err := s.scopedCollections(scope).
+		Select("carrier, currency, status, COUNT(*) AS count, COALESCE(SUM(amount_due), 0) AS amount_due, COALESCE(SUM(collected_amount), 0) AS collected_amount").
+		Where("status <> ?", models.CODCollectionStatusCancelled).
+		Group("carrier, currency, status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	type summaryKey struct{ carrier, currency string }
+	summaries := make(map[summaryKey]*CODCarrierSummary)
+	for _, row := range rows {
+		key := summaryKey{row.Carrier, row.Currency}
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &CODCarrierSummary{Carrier: row.Carrier, Currency: row.Currency}
+			summaries[key] = summary
+		}
+		switch row.Status {
+		case models.CODCollectionStatusPending:
+			summary.PendingCount += row.Count
+			summary.PendingAmount += row.AmountDue
+		case models.CODCollectionStatusCollected:
+			summary.OutstandingCount += row.Count
+			summary.OutstandingAmount += row.CollectedAmount
+			summary.ShortfallAmount += row.AmountDue - row.CollectedAmount
+		case models.CODCollectionStatusSettled:
+			summary.SettledCount += row.Count
+			summary.SettledAmount += row.CollectedAmount
+			summary.ShortfallAmount += row.AmountDue - row.CollectedAmount
+		}
+	}
+
+	report := make([]CODCarrierSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		report = append(report, *summary)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].OutstandingAmount != report[j].OutstandingAmount {
+			return report[i].OutstandingAmount > report[j].OutstandingAmount
+		}
+		if report[i].Carrier != report[j].Carrier {
+			return report[i].Carrier < report[j].Carrier
+		}
+		return report[i].Currency < report[j].Currency
+	})
+	return report, nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func newCODTestServices(t *testing.T) (*PaymentMethodService, *CODService, *models.PaymentMethod) {
+	t.Helper()
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.OrderNote{}, &models.OrderPaymentMethod{}, &models.PaymentMethod{})
+	pm := &models.PaymentMethod{Name: "COD", Enabled: true, CashOnDelivery: true}
+	if err := db.Create(pm).Error; err != nil {
+		t.Fatalf("create payment method: %v", err)
+	}
+	return &PaymentMethodService{db: db, cfg: &config.Config{}}, NewCODService(db), pm
+}
+
+func createCODTestOrder(t *testing.T, svc *CODService, orderNo string, productType models.ProductType) *models.Order {
+	t.Helper()
+	order := &models.Order{
+		OrderNo:         orderNo,
+		Status:          models.OrderStatusPendingPayment,
+		TotalAmount:     5000,
+		Currency:        "CNY",
+		ReceiverName:    "Alice",
+		ReceiverAddress: "1 Main St",
+		Items: []models.OrderItem{{
+			SKU:         "SKU-1",
+			Name:        "Item 1",
+			Quantity:    1,
+			ProductType: productType,
+		}},
+	}
+	if err := svc.db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if err := RecordOrderCreatedLedgerTx(svc.db, order, false, "test"); err != nil {
+		t.Fatalf("record order ledger: %v", err)
+	}
+	return order
+}
+
+func TestSelectCashOnDeliveryMovesOrderToPendingShipment(t *testing.T) {
+	pmSvc, codSvc, pm := newCODTestServices(t)
+	order := createCODTestOrder(t, codSvc, "ORDER-COD-1", models.ProductTypePhysical)
+
+	if err := pmSvc.SelectPaymentMethod(order.ID, pm.ID); err != nil {
+		t.Fatalf("select cod: %v", err)
+	}
+	var reloaded models.Order
+	codSvc.db.First(&reloaded, order.ID)
+	if reloaded.Status != models.OrderStatusPending {
+		t.Fatalf("expected pending shipment, got %s", reloaded.Status)
+	}
+	var collection models.CODCollection
+	if err := codSvc.db.Where("order_id = ?", order.ID).First(&collection).Error; err != nil {
+		t.Fatalf("load collection: %v", err)
+	}
+	if collection.Status != models.CODCollectionStatusPending || collection.AmountDue != 5000 {
+		t.Fatalf("unexpected collection: %+v", collection)
+	}
+
+	digital := createCODTestOrder(t, codSvc, "ORDER-COD-DIGITAL", models.ProductTypeVirtual)
+	requireOrderBizErr(t, pmSvc.SelectPaymentMethod(digital.ID, pm.ID), "payment.codVirtualNotSupported")
+}
+
+func TestRecordCODCollectionAndReport(t *testing.T) {
+	pmSvc, codSvc, pm := newCODTestServices(t)
+	first := createCODTestOrder(t, codSvc, "ORDER-COD-A", models.ProductTypePhysical)
+	second := createCODTestOrder(t, codSvc, "ORDER-COD-B", models.ProductTypePhysical)
+	third := createCODTestOrder(t, codSvc, "ORDER-COD-C", models.ProductTypePhysical)
+	for _, order := range []*models.Order{first, second, third} {
+		if err := pmSvc.SelectPaymentMethod(order.ID, pm.ID); err != nil {
+			t.Fatalf("select cod: %v", err)
+		}
+	}
+
+	_, err := codSvc.RecordCollection(first.ID, "SF", 5000, nil, "")
+	requireOrderBizErr(t, err, "cod.orderNotShipped")
+
+	codSvc.db.Model(&models.Order{}).Where("id IN ?", []uint{first.ID, second.ID}).Update("status", models.OrderStatusShipped)
+	_, err = codSvc.RecordCollection(first.ID, "SF", 6000, nil, "")
+	requireOrderBizErr(t, err, "cod.invalidCollectedAmount")
+
+	operatorID := uint(3)
+	collected, err := codSvc.RecordCollection(first.ID, "SF", 5000, &operatorID, "")
+	if err != nil {
+		t.Fatalf("record collection: %v", err)
+	}
+	if _, err := codSvc.RecordCollection(second.ID, "SF", 4800, &operatorID, "short 2.00"); err != nil {
+		t.Fatalf("record short collection: %v", err)
+	}
+	_, err = codSvc.RecordCollection(first.ID, "SF", 5000, nil, "")
+	requireOrderBizErr(t, err, "cod.collectionNotPending")
+
+	balance, err := ledgerAccountBalanceTx(codSvc.db, first.ID, models.LedgerAccountCash)
+	if err != nil || balance != 5000 {
+		t.Fatalf("expected collected cash in ledger, got %d err=%v", balance, err)
+	}
+
+	if settled, err := codSvc.SettleCollections([]uint{collected.ID}, &operatorID); err != nil || settled != 1 {
+		t.Fatalf("settle: settled=%d err=%v", settled, err)
+	}
+
+	report, err := codSvc.Report(nil)
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	byCarrier := make(map[string]CODCarrierSummary, len(report))
+	for _, summary := range report {
+		byCarrier[summary.Carrier] = summary
+	}
+	sf := byCarrier["SF"]
+	if sf.OutstandingCount != 1 || sf.OutstandingAmount != 4800 || sf.SettledAmount != 5000 || sf.ShortfallAmount != 200 {
+		t.Fatalf("unexpected carrier summary: %+v", sf)
+	}
+	if pending := byCarrier[""]; pending.PendingCount != 1 || pending.PendingAmount != 5000 {
+		t.Fatalf("unexpected pending summary: %+v", pending)
+	}
+}
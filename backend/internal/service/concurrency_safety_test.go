@@ -45,6 +45,9 @@ func openConcurrentServiceTestDB(t *testing.T, migrations ...interface{}) *gorm.
 		&models.Inventory{},
 		&models.ProductInventoryBinding{},
 		&models.UserPurchaseStat{},
+		&models.LedgerEntry{},
+		&models.PaymentAmountReservation{},
+		&models.CODCollection{},
 	}
 	allMigrations = append(allMigrations, migrations...)
 
@@ -123,6 +126,39 @@ func TestReserveMessageRateLimitSlotIsAtomic(t *testing.T) {
 	}
 }
 
+func TestReserveMessageRateLimitSlotFallsBackToMemory(t *testing.T) {
+	previousClient := cache.RedisClient
+	cache.RedisClient = nil
+	defer func() { cache.RedisClient = previousClient }()
+
+	rl := config.MessageRateLimit{Hourly: 2, Daily: 10}
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, _, err := reserveMessageRateLimitSlot("email", "memory-fallback@example.com", rl)
+			if err != nil {
+				t.Errorf("reserve rate limit slot failed: %v", err)
+				return
+			}
+			if ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := allowed.Load(); got != 2 {
+		t.Fatalf("expected 2 allowed requests without redis, got %d", got)
+	}
+	ok, availableAt, _ := reserveMessageRateLimitSlot("email", "memory-fallback@example.com", rl)
+	if ok || availableAt.IsZero() {
+		t.Fatalf("expected hourly limit with availability time, got ok=%v availableAt=%v", ok, availableAt)
+	}
+}
+
 func TestCreateUserOrderPendingLimitEnforcedAcrossServiceInstances(t *testing.T) {
 	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Product{}, &models.Order{})
 
@@ -382,7 +418,7 @@ func TestInventoryReserveLimitHoldsUnderConcurrency(t *testing.T) {
 }
 
 func TestPromoCodeReserveLimitHoldsUnderConcurrency(t *testing.T) {
-	db := openConcurrentServiceTestDB(t, &models.PromoCode{})
+	db := openConcurrentServiceTestDB(t, &models.PromoCode{}, &models.PromoCodeRedemption{})
 
 	promo := models.PromoCode{
 		Code:             "PROMO-CONCURRENCY",
@@ -443,6 +479,78 @@ func TestPromoCodeReserveLimitHoldsUnderConcurrency(t *testing.T) {
 	}
 }
 
+func TestPromoCodeRedemptionNeverExceedsCapUnderStress(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.PromoCode{}, &models.PromoCodeRedemption{})
+
+	promo := models.PromoCode{
+		Code:          "PROMO-STRESS",
+		Name:          "Stress Promo",
+		DiscountType:  models.DiscountTypeFixed,
+		DiscountValue: 100,
+		Status:        models.PromoCodeStatusActive,
+		TotalQuantity: 5,
+	}
+	if err := db.Create(&promo).Error; err != nil {
+		t.Fatalf("create promo code failed: %v", err)
+	}
+
+	svc := NewPromoCodeService(repository.NewPromoCodeRepository(db), nil)
+	const workers = 40
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	results := make(chan error, workers)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			<-start
+			orderNo := fmt.Sprintf("ORD-STRESS-%d", index+1)
+			if err := svc.Reserve(promo.ID, orderNo); err != nil {
+				results <- err
+				return
+			}
+			// 一部分订单取消，释放的名额可被其他请求重新占用
+			if index%4 == 0 {
+				results <- svc.ReleaseReserve(promo.ID, orderNo)
+				return
+			}
+			results <- svc.Deduct(promo.ID, orderNo)
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+	close(results)
+
+	for err := range results {
+		if err == nil {
+			continue
+		}
+		requireProductBizErr(t, err, "promo_code.exhausted")
+	}
+
+	var refreshed models.PromoCode
+	if err := db.First(&refreshed, promo.ID).Error; err != nil {
+		t.Fatalf("reload promo code failed: %v", err)
+	}
+	if refreshed.UsedQuantity > promo.TotalQuantity || refreshed.UsedQuantity+refreshed.ReservedQuantity > promo.TotalQuantity {
+		t.Fatalf("promo code over-redeemed: used=%d reserved=%d total=%d", refreshed.UsedQuantity, refreshed.ReservedQuantity, promo.TotalQuantity)
+	}
+	if refreshed.ReservedQuantity != 0 {
+		t.Fatalf("expected no leftover reservations, got %d", refreshed.ReservedQuantity)
+	}
+
+	// 剩余名额占满后必须明确返回已领完
+	remaining := promo.TotalQuantity - refreshed.UsedQuantity
+	for i := 0; i < remaining; i++ {
+		if err := svc.Reserve(promo.ID, fmt.Sprintf("ORD-STRESS-FILL-%d", i+1)); err != nil {
+			t.Fatalf("reserve remaining slot %d failed: %v", i+1, err)
+		}
+	}
+	requireProductBizErr(t, svc.Reserve(promo.ID, "ORD-STRESS-LATE"), "promo_code.exhausted")
+}
+
 func TestPaymentPollingPerUserQueueLimitHoldsUnderConcurrency(t *testing.T) {
 	db := openConcurrentServiceTestDB(t,
 		&models.User{},
@@ -1065,3 +1173,99 @@ func TestPaymentPollingConfirmPaymentResultIsIdempotentUnderConcurrency(t *testi
 		t.Fatalf("expected exactly 1 payment_success log, got %d", successLogs)
 	}
 }
+
+func TestCreateUserOrderRollsBackReservationsWhenOrderInsertFails(t *testing.T) {
+	db := openConcurrentServiceTestDB(t,
+		&models.User{},
+		&models.Product{},
+		&models.Order{},
+		&models.InventoryLog{},
+		&models.GiftPromotion{},
+		&models.PromoCode{},
+		&models.PromoCodeRedemption{},
+	)
+
+	cfg := &config.Config{}
+	cfg.Order.MaxOrderItems = 20
+	cfg.Order.MaxItemQuantity = 10
+	cfg.Order.Currency = "CNY"
+	cfg.Form.ExpireHours = 24
+
+	user := models.User{
+		UUID:         "order-tx-rollback-user",
+		Email:        "order-tx-rollback@example.com",
+		Name:         "order-tx-rollback",
+		Role:         "user",
+		IsActive:     true,
+		PasswordHash: "hash",
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user failed: %v", err)
+	}
+	product := models.Product{SKU: "SKU-TX-MAIN", Name: "Main", ProductType: models.ProductTypeVirtual, Status: models.ProductStatusActive, Price: 1000}
+	giftProduct := models.Product{SKU: "SKU-TX-GIFT", Name: "Gift", ProductType: models.ProductTypePhysical, Status: models.ProductStatusInactive}
+	for _, p := range []*models.Product{&product, &giftProduct} {
+		if err := db.Create(p).Error; err != nil {
+			t.Fatalf("create product failed: %v", err)
+		}
+	}
+	giftInventory := models.Inventory{Name: "Gift stock", Stock: 5, AvailableQuantity: 5, IsActive: true}
+	if err := db.Create(&giftInventory).Error; err != nil {
+		t.Fatalf("create gift inventory failed: %v", err)
+	}
+	promo := models.PromoCode{Code: "TXROLLBACK", Name: "Rollback", DiscountType: models.DiscountTypeFixed, DiscountValue: 100, Status: models.PromoCodeStatusActive, TotalQuantity: 1}
+	if err := db.Create(&promo).Error; err != nil {
+		t.Fatalf("create promo code failed: %v", err)
+	}
+
+	giftSvc := NewGiftPromotionService(db)
+	if _, err := giftSvc.Create(GiftPromotionInput{Name: "Gift", ThresholdMinor: 500, GiftProductID: giftProduct.ID, GiftInventoryID: giftInventory.ID}); err != nil {
+		t.Fatalf("create promotion failed: %v", err)
+	}
+	svc := newConcurrentOrderService(db, cfg, nil)
+	svc.promoCodeRepo = repository.NewPromoCodeRepository(db)
+	svc.SetGiftPromotionService(giftSvc)
+
+	// 模拟库存与优惠码均已预留后写订单失败（等同于中途崩溃）
+	var failOrderInsert atomic.Bool
+	failOrderInsert.Store(true)
+	if err := db.Callback().Create().Before("gorm:create").Register("test:fail_order_insert", func(tx *gorm.DB) {
+		if failOrderInsert.Load() && tx.Statement.Table == "orders" {
+			_ = tx.AddError(errors.New("simulated order insert failure"))
+		}
+	}); err != nil {
+		t.Fatalf("register callback failed: %v", err)
+	}
+
+	items := []models.OrderItem{{SKU: product.SKU, Name: product.Name, Quantity: 1, ProductType: models.ProductTypeVirtual}}
+	if _, err := svc.CreateUserOrder(user.ID, items, "", promo.Code); err == nil {
+		t.Fatalf("expected order creation to fail")
+	}
+
+	var reloadedInventory models.Inventory
+	db.First(&reloadedInventory, giftInventory.ID)
+	var reloadedPromo models.PromoCode
+	db.First(&reloadedPromo, promo.ID)
+	var orderCount, redemptionCount, logCount int64
+	db.Model(&models.Order{}).Count(&orderCount)
+	db.Model(&models.PromoCodeRedemption{}).Count(&redemptionCount)
+	db.Model(&models.InventoryLog{}).Count(&logCount)
+	if reloadedInventory.ReservedQuantity != 0 || reloadedPromo.ReservedQuantity != 0 ||
+		orderCount != 0 || redemptionCount != 0 || logCount != 0 {
+		t.Fatalf("expected no dangling reservations, got inventory_reserved=%d promo_reserved=%d orders=%d redemptions=%d logs=%d",
+			reloadedInventory.ReservedQuantity, reloadedPromo.ReservedQuantity, orderCount, redemptionCount, logCount)
+	}
+
+	// 回滚后名额完整保留，下一次下单可正常使用
+	failOrderInsert.Store(false)
+	order, err := svc.CreateUserOrder(user.ID, []models.OrderItem{{SKU: product.SKU, Name: product.Name, Quantity: 1, ProductType: models.ProductTypeVirtual}}, "", promo.Code)
+	if err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+	db.First(&reloadedInventory, giftInventory.ID)
+	db.First(&reloadedPromo, promo.ID)
+	if len(order.Items) != 2 || reloadedInventory.ReservedQuantity != 1 || reloadedPromo.ReservedQuantity != 1 || order.DiscountAmount != 100 {
+		t.Fatalf("expected gift and promo reserved with the order, got items=%d inventory_reserved=%d promo_reserved=%d discount=%d",
+			len(order.Items), reloadedInventory.ReservedQuantity, reloadedPromo.ReservedQuantity, order.DiscountAmount)
+	}
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	SettingsBundleFormat  = "auralogic-settings"
+	SettingsBundleVersion = 1
+
+	SettingsBundleSecretsRedacted  = "redacted"
+	SettingsBundleSecretsEncrypted = "encrypted"
+
+	settingsBundleKDF = "scrypt-aes256gcm"
+)
+
+var (
+	ErrSettingsBundleInvalid            = bizerr.New("settings.bundleInvalid", "Not a valid settings bundle")
+	ErrSettingsBundlePassphraseRequired = bizerr.New("settings.bundlePassphraseRequired", "A passphrase is required to encrypt or decrypt secrets")
+	ErrSettingsBundleDecryptFailed      = bizerr.New("settings.bundleDecryptFailed", "Failed to decrypt secrets, check the passphrase")
+	ErrSettingsImportConflict           = bizerr.New("settings.importConflict", "Configuration changed since the preview, please preview again")
+)
+
+// settingsEnvironmentPaths 与部署环境绑定的配置，不导出且导入时保持目标环境的值
+var settingsEnvironmentPaths = []string{
+	"acme",
+	"app.debug",
+	"app.demo_mode",
+	"app.env",
+	"app.mode",
+	"app.port",
+	"app.url",
+	"database",
+	"jwt",
+	"log",
+	"oauth.github.redirect_url",
+	"oauth.google.redirect_url",
+	"redis",
+	"security.cors.allowed_origins",
+	"security.ip_header",
+	"security.pii_encryption",
+	"security.trusted_proxies",
+	"upload.dir",
+}
+
+// SettingsBundle 可在环境之间迁移的配置包
+type SettingsBundle struct {
+	Format           string                 `json:"format"`
+	Version          int                    `json:"version"`
+	ExportedAt       time.Time              `json:"exported_at"`
+	Source           SettingsBundleSource   `json:"source"`
+	SecretsMode      string                 `json:"secrets_mode"`
+	Config           map[string]interface{} `json:"config"`
+	EncryptedSecrets *SettingsBundleCipher  `json:"encrypted_secrets,omitempty"`
+}
+
+// SettingsBundleSource 导出来源，仅用于展示
+type SettingsBundleSource struct {
+	AppName string `json:"app_name,omitempty"`
+	AppURL  string `json:"app_url,omitempty"`
+	Env     string `json:"env,omitempty"`
+}
+
+// SettingsBundleCipher 加密后的密钥集合（按配置路径索引）
+type SettingsBundleCipher struct {
+	KDF        string `json:"kdf"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// SettingsImportPreview 导入预检结果
+type SettingsImportPreview struct {
+	ConfigHash        string                `json:"config_hash"`
+	Source            SettingsBundleSource  `json:"source"`
+	ExportedAt        time.Time             `json:"exported_at"`
+	Sections          []string              `json:"sections"`
+	Changes           []models.ConfigChange `json:"changes"`
+	SkippedPaths      []string              `json:"skipped_paths"`
+	SecretsApplied    []string              `json:"secrets_applied"`
+	UnresolvedSecrets []string              `json:"unresolved_secrets"`
+	Valid             bool                  `json:"valid"`
+	ValidationError   string                `json:"validation_error,omitempty"`
+}
+
+// ConfigMapHash 配置内容指纹，用于检测预检后配置是否被他人修改
+func ConfigMapHash(m map[string]interface{}) string {
+	data, _ := json.Marshal(m)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func splitConfigPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// lookupConfigPath 按点分路径读取配置值
+func lookupConfigPath(m map[string]interface{}, path string) (interface{}, bool) {
+	parts := splitConfigPath(path)
+	current := m
+	for i, part := range parts {
+		value, ok := current[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return value, true
+		}
+		next, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return nil, false
+}
+
+// setConfigPath 按点分路径写入配置值，缺失的中间层自动创建
+func setConfigPath(m map[string]interface{}, path string, value interface{}) {
+	parts := splitConfigPath(path)
+	current := m
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
+
+func deleteConfigPath(m map[string]interface{}, path string) {
+	parts := splitConfigPath(path)
+	current := m
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			return
+		}
+		current = next
+	}
+	delete(current, parts[len(parts)-1])
+}
+
+// collectConfigSecrets 收集已配置的密钥，按路径索引
+func collectConfigSecrets(m map[string]interface{}, prefix string, out map[string]interface{}) {
+	for key, value := range m {
+		path := joinConfigPath(prefix, key)
+		if isConfigSecretPath(path) {
+			if !isEmptyConfigValue(value) {
+				out[path] = value
+			}
+			continue
+		}
+		if child, ok := value.(map[string]interface{}); ok {
+			collectConfigSecrets(child, path, out)
+		}
+	}
+}
+
+// mergeConfigMaps 把 overlay 深度合并到 base：对象逐项合并，其余值整体覆盖
+func mergeConfigMaps(base, overlay map[string]interface{}) {
+	for key, value := range overlay {
+		overlayChild, overlayIsMap := value.(map[string]interface{})
+		baseChild, baseIsMap := base[key].(map[string]interface{})
+		if overlayIsMap && baseIsMap {
+			mergeConfigMaps(baseChild, overlayChild)
+			continue
+		}
+		base[key] = value
+	}
+}
+
+func deriveSettingsBundleKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+}
+
+func encryptSettingsSecrets(secrets map[string]interface{}, passphrase string) (*SettingsBundleCipher, error) {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := deriveSettingsBundleKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &SettingsBundleCipher{
+		KDF:        settingsBundleKDF,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext, nil)),
+	}, nil
+}
+
+func decryptSettingsSecrets(encrypted *SettingsBundleCipher, passphrase string) (map[string]interface{}, error) {
+	if encrypted.KDF != settingsBundleKDF {
+		return nil, ErrSettingsBundleInvalid
+	}
+	salt, errSalt := base64.StdEncoding.DecodeString(encrypted.Salt)
+	nonce, errNonce := base64.StdEncoding.DecodeString(encrypted.Nonce)
+	ciphertext, errCipher := base64.StdEncoding.DecodeString(encrypted.Ciphertext)
+	if errSalt != nil || errNonce != nil || errCipher != nil {
+		return nil, ErrSettingsBundleInvalid
+	}
+	key, err := deriveSettingsBundleKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrSettingsBundleInvalid
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrSettingsBundleDecryptFailed
+	}
+	secrets := map[string]interface{}{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, ErrSettingsBundleInvalid
+	}
+	return secrets, nil
+}
+
+// BuildSettingsBundle 导出配置包：去除环境相关配置，密钥脱敏或以口令加密
+func BuildSettingsBundle(current map[string]interface{}, secretsMode, passphrase string) (*SettingsBundle, error) {
+	exportable := CloneConfigMap(current)
+	for _, path := range settingsEnvironmentPaths {
+		deleteConfigPath(exportable, path)
+	}
+
+	bundle := &SettingsBundle{
+		Format:      SettingsBundleFormat,
+		Version:     SettingsBundleVersion,
+		ExportedAt:  time.Now(),
+		SecretsMode: SettingsBundleSecretsRedacted,
+		Config:      RedactConfigMap(exportable),
+	}
+	if name, ok := lookupConfigPath(current, "app.name"); ok {
+		bundle.Source.AppName, _ = name.(string)
+	}
+	if url, ok := lookupConfigPath(current, "app.url"); ok {
+		bundle.Source.AppURL, _ = url.(string)
+	}
+	if env, ok := lookupConfigPath(current, "app.env"); ok {
+		bundle.Source.Env, _ = env.(string)
+	}
+
+	if secretsMode == SettingsBundleSecretsEncrypted {
+		if strings.TrimSpace(passphrase) == "" {
+			return nil, ErrSettingsBundlePassphraseRequired
+		}
+		secrets := map[string]interface{}{}
+		collectConfigSecrets(exportable, "", secrets)
+		encrypted, err := encryptSettingsSecrets(secrets, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		bundle.SecretsMode = SettingsBundleSecretsEncrypted
+		bundle.EncryptedSecrets = encrypted
+	}
+	return bundle, nil
+}
+
+// ResolveSettingsImport 计算导入后的完整配置与预检报告：
+// 配置包覆盖当前配置，环境相关配置保持不变，密钥优先取包内加密值，否则沿用当前值
+func ResolveSettingsImport(bundle *SettingsBundle, current map[string]interface{}, passphrase string) (map[string]interface{}, *SettingsImportPreview, error) {
+	if bundle == nil || bundle.Format != SettingsBundleFormat || bundle.Version != SettingsBundleVersion || bundle.Config == nil {
+		return nil, nil, ErrSettingsBundleInvalid
+	}
+	importedSecrets := map[string]interface{}{}
+	if bundle.SecretsMode == SettingsBundleSecretsEncrypted {
+		if bundle.EncryptedSecrets == nil {
+			return nil, nil, ErrSettingsBundleInvalid
+		}
+		if strings.TrimSpace(passphrase) == "" {
+			return nil, nil, ErrSettingsBundlePassphraseRequired
+		}
+		secrets, err := decryptSettingsSecrets(bundle.EncryptedSecrets, passphrase)
+		if err != nil {
+			return nil, nil, err
+		}
+		importedSecrets = secrets
+	}
+
+	merged := CloneConfigMap(current)
+	mergeConfigMaps(merged, CloneConfigMap(bundle.Config))
+
+	preview := &SettingsImportPreview{
+		ConfigHash:        ConfigMapHash(current),
+		Source:            bundle.Source,
+		ExportedAt:        bundle.ExportedAt,
+		SkippedPaths:      []string{},
+		SecretsApplied:    []string{},
+		UnresolvedSecrets: []string{},
+	}
+	for _, path := range settingsEnvironmentPaths {
+		_, inBundle := lookupConfigPath(bundle.Config, path)
+		if value, ok := lookupConfigPath(current, path); ok {
+			setConfigPath(merged, path, value)
+		} else {
+			deleteConfigPath(merged, path)
+		}
+		if inBundle {
+			preview.SkippedPaths = append(preview.SkippedPaths, path)
+		}
+	}
+
+	bundleSecrets := map[string]interface{}{}
+	collectConfigSecrets(bundle.Config, "", bundleSecrets)
+	for path, value := range importedSecrets {
+		setConfigPath(merged, path, value)
+		preview.SecretsApplied = append(preview.SecretsApplied, path)
+	}
+	for path := range bundleSecrets {
+		if _, ok := importedSecrets[path]; ok {
+			continue
+		}
+		if value, ok := lookupConfigPath(current, path); ok && !isEmptyConfigValue(value) {
+			setConfigPath(merged, path, value)
+			continue
+		}
+		// 来源环境配置了该密钥，但包内未携带且目标环境也未配置
+		deleteConfigPath(merged, path)
+		preview.UnresolvedSecrets = append(preview.UnresolvedSecrets, path)
+	}
+	sort.Strings(preview.SecretsApplied)
+	sort.Strings(preview.UnresolvedSecrets)
+
+	preview.Changes = DiffConfigMaps(current, merged)
+	preview.Sections = configChangeSections(preview.Changes)
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := config.ValidateConfigJSON(data); err != nil {
+		preview.ValidationError = err.Error()
+	} else {
+		preview.Valid = true
+	}
+	return merged, preview, nil
+}
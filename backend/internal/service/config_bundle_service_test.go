@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSettingsBundlePromotionKeepsEnvironmentAndSecrets(t *testing.T) {
+	staging := map[string]interface{}{
+		"app":      map[string]interface{}{"name": "Shop Staging", "url": "https://staging.example.com", "env": "staging"},
+		"database": map[string]interface{}{"driver": "sqlite", "name": "staging.db"},
+		"jwt":      map[string]interface{}{"secret": "staging-secret-staging-secret-staging"},
+		"smtp":     map[string]interface{}{"host": "smtp.example.com", "password": "smtp-pass"},
+	}
+	production := map[string]interface{}{
+		"app":      map[string]interface{}{"name": "Shop", "url": "https://shop.example.com", "env": "production"},
+		"database": map[string]interface{}{"driver": "sqlite", "name": "prod.db"},
+		"jwt":      map[string]interface{}{"secret": "production-secret-production-secret"},
+	}
+
+	redacted, err := BuildSettingsBundle(staging, SettingsBundleSecretsRedacted, "")
+	if err != nil {
+		t.Fatalf("export redacted bundle: %v", err)
+	}
+	if redacted.Config["database"] != nil || redacted.Config["jwt"] != nil || redacted.Source.Env != "staging" {
+		t.Fatalf("environment settings must not be exported: %+v", redacted)
+	}
+	if redacted.Config["smtp"].(map[string]interface{})["password"] != configRedactedValue {
+		t.Fatalf("secrets must be redacted: %+v", redacted.Config["smtp"])
+	}
+
+	merged, preview, err := ResolveSettingsImport(redacted, production, "")
+	if err != nil || !preview.Valid {
+		t.Fatalf("preview redacted import: %+v, %v", preview, err)
+	}
+	if merged["app"].(map[string]interface{})["name"] != "Shop Staging" ||
+		merged["app"].(map[string]interface{})["url"] != "https://shop.example.com" ||
+		merged["database"].(map[string]interface{})["name"] != "prod.db" {
+		t.Fatalf("import must apply portable settings and keep environment ones, got %+v", merged)
+	}
+	if _, ok := merged["smtp"].(map[string]interface{})["password"]; ok {
+		t.Fatal("redacted placeholder must not be written to the config")
+	}
+	if len(preview.UnresolvedSecrets) != 1 || preview.UnresolvedSecrets[0] != "smtp.password" {
+		t.Fatalf("missing secret should be reported, got %+v", preview.UnresolvedSecrets)
+	}
+	if preview.ConfigHash != ConfigMapHash(production) {
+		t.Fatal("preview hash must fingerprint the current config")
+	}
+
+	if _, err := BuildSettingsBundle(staging, SettingsBundleSecretsEncrypted, " "); !errors.Is(err, ErrSettingsBundlePassphraseRequired) {
+		t.Fatalf("encrypted export without passphrase should fail, got %v", err)
+	}
+	encrypted, err := BuildSettingsBundle(staging, SettingsBundleSecretsEncrypted, "promote")
+	if err != nil || encrypted.EncryptedSecrets == nil {
+		t.Fatalf("export encrypted bundle: %+v, %v", encrypted, err)
+	}
+	if _, _, err := ResolveSettingsImport(encrypted, production, "wrong"); !errors.Is(err, ErrSettingsBundleDecryptFailed) {
+		t.Fatalf("wrong passphrase should fail, got %v", err)
+	}
+	merged, preview, err = ResolveSettingsImport(encrypted, production, "promote")
+	if err != nil || !preview.Valid || len(preview.UnresolvedSecrets) != 0 {
+		t.Fatalf("import encrypted bundle: %+v, %v", preview, err)
+	}
+	if merged["smtp"].(map[string]interface{})["password"] != "smtp-pass" ||
+		merged["jwt"].(map[string]interface{})["secret"] != "production-secret-production-secret" {
+		t.Fatalf("encrypted secrets must be restored without touching environment secrets, got %+v", merged)
+	}
+
+	if _, _, err := ResolveSettingsImport(&SettingsBundle{Format: "other"}, production, ""); !errors.Is(err, ErrSettingsBundleInvalid) {
+		t.Fatalf("unknown bundle format should be rejected, got %v", err)
+	}
+}
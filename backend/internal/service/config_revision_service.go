@@ -0,0 +1,288 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+var ErrConfigRevisionNotFound = bizerr.New("settings.revisionNotFound", "Configuration revision not found")
+
+// configRedactedValue 快照与差异中密钥的占位值
+const configRedactedValue = "******"
+
+// configSecretKeys 按键名脱敏的配置项（不区分所在分组）
+var configSecretKeys = map[string]bool{
+	"password":             true,
+	"secret":               true,
+	"client_secret":        true,
+	"secret_key":           true,
+	"aliyun_access_secret": true,
+	"twilio_auth_token":    true,
+	"fiat_api_key":         true,
+	"crypto_api_key":       true,
+	"bot_token":            true,
+	"blind_index_key":      true,
+	"api_key":              true,
+	"webhook_secret":       true,
+}
+
+// configSecretPaths 按完整路径脱敏的配置项
+var configSecretPaths = map[string]bool{
+	"security.pii_encryption.keys": true,
+}
+
+func isConfigSecretPath(path string) bool {
+	if configSecretPaths[path] {
+		return true
+	}
+	key := path
+	if idx := strings.LastIndex(path, "."); idx >= 0 {
+		key = path[idx+1:]
+	}
+	return configSecretKeys[key]
+}
+
+func joinConfigPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// CloneConfigMap 深拷贝配置文件内容，用于记录修改前的快照
+func CloneConfigMap(src map[string]interface{}) map[string]interface{} {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	var dst map[string]interface{}
+	if err := json.Unmarshal(data, &dst); err != nil || dst == nil {
+		return map[string]interface{}{}
+	}
+	return dst
+}
+
+// RedactConfigMap 返回把密钥替换为占位值的副本，空值保持为空以便区分"未配置"
+func RedactConfigMap(src map[string]interface{}) map[string]interface{} {
+	return redactConfigMap(CloneConfigMap(src), "")
+}
+
+func redactConfigMap(m map[string]interface{}, prefix string) map[string]interface{} {
+	for key, value := range m {
+		path := joinConfigPath(prefix, key)
+		if isConfigSecretPath(path) {
+			if !isEmptyConfigValue(value) {
+				m[key] = configRedactedValue
+			}
+			continue
+		}
+		if child, ok := value.(map[string]interface{}); ok {
+			redactConfigMap(child, path)
+		}
+	}
+	return m
+}
+
+func isEmptyConfigValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// DiffConfigMaps 逐项比较两份配置（对象递归展开、数组整体比较），结果按路径排序，密钥只标记变更不输出值
+func DiffConfigMaps(before, after map[string]interface{}) []models.ConfigChange {
+	before, after = CloneConfigMap(before), CloneConfigMap(after)
+	changes := make([]models.ConfigChange, 0)
+	diffConfigValues("", before, after, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func diffConfigValues(prefix string, before, after map[string]interface{}, changes *[]models.ConfigChange) {
+	keys := make(map[string]struct{}, len(before)+len(after))
+	for key := range before {
+		keys[key] = struct{}{}
+	}
+	for key := range after {
+		keys[key] = struct{}{}
+	}
+	for key := range keys {
+		path := joinConfigPath(prefix, key)
+		oldValue, hadOld := before[key]
+		newValue, hasNew := after[key]
+		if isConfigSecretPath(path) {
+			if !reflect.DeepEqual(oldValue, newValue) {
+				*changes = append(*changes, models.ConfigChange{
+					Path:     path,
+					Before:   redactedConfigValue(oldValue),
+					After:    redactedConfigValue(newValue),
+					Redacted: true,
+				})
+			}
+			continue
+		}
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			diffConfigValues(path, oldMap, newMap, changes)
+			continue
+		}
+		if hadOld && hasNew && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		change := models.ConfigChange{Path: path, Before: oldValue, After: newValue}
+		if oldIsMap {
+			change.Before = redactConfigMap(oldMap, path)
+		}
+		if newIsMap {
+			change.After = redactConfigMap(newMap, path)
+		}
+		*changes = append(*changes, change)
+	}
+}
+
+func redactedConfigValue(value interface{}) interface{} {
+	if isEmptyConfigValue(value) {
+		return value
+	}
+	return configRedactedValue
+}
+
+// configChangeSections 变更涉及的顶层分组
+func configChangeSections(changes []models.ConfigChange) []string {
+	seen := make(map[string]bool)
+	sections := make([]string, 0)
+	for _, change := range changes {
+		section := change.Path
+		if idx := strings.Index(section, "."); idx >= 0 {
+			section = section[:idx]
+		}
+		if !seen[section] {
+			seen[section] = true
+			sections = append(sections, section)
+		}
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// ConfigRevisionService 系统配置版本记录与回滚
+type ConfigRevisionService struct {
+	db *gorm.DB
+}
+
+func NewConfigRevisionService(db *gorm.DB) *ConfigRevisionService {
+	return &ConfigRevisionService{db: db}
+}
+
+// Record 记录一次配置变更，无实际差异时返回 nil；首次记录前先保存修改前的配置作为基线
+func (s *ConfigRevisionService) Record(before, after map[string]interface{}, action string, adminID, rollbackOfID *uint) (*models.ConfigRevision, error) {
+	changes := DiffConfigMaps(before, after)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	revision := &models.ConfigRevision{
+		Action:       action,
+		AdminID:      adminID,
+		RollbackOfID: rollbackOfID,
+		Sections:     configChangeSections(changes),
+		Changes:      changes,
+		ChangeCount:  len(changes),
+		Snapshot:     RedactConfigMap(after),
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.ConfigRevision{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			baseline := &models.ConfigRevision{
+				Action:   models.ConfigRevisionActionBaseline,
+				Sections: []string{},
+				Changes:  []models.ConfigChange{},
+				Snapshot: RedactConfigMap(before),
+			}
+			if err := tx.Create(baseline).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(revision).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// List 版本列表（新版本在前，不含快照）
+func (s *ConfigRevisionService) List(page, limit int) ([]models.ConfigRevision, int64, error) {
+	var total int64
+	if err := s.db.Model(&models.ConfigRevision{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	revisions := make([]models.ConfigRevision, 0)
+	err := s.db.Omit("snapshot").
+		Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&revisions).Error
+	return revisions, total, err
+}
+
+// Get 版本详情（含脱敏快照）
+func (s *ConfigRevisionService) Get(id uint) (*models.ConfigRevision, error) {
+	var revision models.ConfigRevision
+	if err := s.db.First(&revision, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrConfigRevisionNotFound
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// BuildRollbackConfig 以目标版本快照为准生成回滚后的配置；快照中脱敏的密钥沿用当前配置，不随回滚改变
+func (s *ConfigRevisionService) BuildRollbackConfig(id uint, current map[string]interface{}) (*models.ConfigRevision, map[string]interface{}, error) {
+	revision, err := s.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	restored := CloneConfigMap(revision.Snapshot)
+	restoreConfigSecrets(restored, current, "")
+	return revision, restored, nil
+}
+
+func restoreConfigSecrets(target, current map[string]interface{}, prefix string) {
+	for key, value := range target {
+		path := joinConfigPath(prefix, key)
+		if isConfigSecretPath(path) {
+			if currentValue, ok := current[key]; ok {
+				target[key] = currentValue
+			} else if value == configRedactedValue {
+				delete(target, key)
+			}
+			continue
+		}
+		child, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		currentChild, _ := current[key].(map[string]interface{})
+		restoreConfigSecrets(child, currentChild, path)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestConfigRevisionDiffRedactsSecretsAndRollbackKeepsThem(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.ConfigRevision{})
+	svc := NewConfigRevisionService(db)
+
+	before := map[string]interface{}{
+		"app":  map[string]interface{}{"name": "Shop", "port": 8080},
+		"smtp": map[string]interface{}{"host": "smtp.old", "password": "old-pass"},
+	}
+	after := CloneConfigMap(before)
+	after["app"].(map[string]interface{})["name"] = "Shop 2"
+	after["smtp"].(map[string]interface{})["password"] = "new-pass"
+	after["rate_limit"] = map[string]interface{}{"enabled": true}
+
+	adminID := uint(7)
+	revision, err := svc.Record(before, after, models.ConfigRevisionActionUpdate, &adminID, nil)
+	if err != nil || revision == nil {
+		t.Fatalf("record revision: %v", err)
+	}
+	if revision.ChangeCount != 3 || len(revision.Sections) != 3 {
+		t.Fatalf("unexpected changes: %+v", revision.Changes)
+	}
+	for _, change := range revision.Changes {
+		if change.Path == "smtp.password" && (!change.Redacted || change.Before != configRedactedValue || change.After != configRedactedValue) {
+			t.Fatalf("secret change must be redacted: %+v", change)
+		}
+	}
+	if revision.Snapshot["smtp"].(map[string]interface{})["password"] != configRedactedValue {
+		t.Fatal("snapshot must not store secrets")
+	}
+
+	if again, err := svc.Record(after, CloneConfigMap(after), models.ConfigRevisionActionUpdate, &adminID, nil); err != nil || again != nil {
+		t.Fatalf("unchanged config must not create a revision: %+v, %v", again, err)
+	}
+
+	revisions, total, err := svc.List(1, 20)
+	if err != nil || total != 2 || revisions[1].Action != models.ConfigRevisionActionBaseline {
+		t.Fatalf("expected baseline plus update, got total=%d revisions=%+v err=%v", total, revisions, err)
+	}
+	if revisions[0].Snapshot != nil {
+		t.Fatal("list must omit snapshots")
+	}
+
+	// 回滚到基线：普通配置恢复，密钥保持当前值
+	target, restored, err := svc.BuildRollbackConfig(revisions[1].ID, after)
+	if err != nil || target.Action != models.ConfigRevisionActionBaseline {
+		t.Fatalf("build rollback: %+v, %v", target, err)
+	}
+	if restored["app"].(map[string]interface{})["name"] != "Shop" || restored["rate_limit"] != nil {
+		t.Fatalf("rollback should restore baseline values, got %+v", restored)
+	}
+	if restored["smtp"].(map[string]interface{})["password"] != "new-pass" {
+		t.Fatalf("rollback must keep current secret, got %+v", restored["smtp"])
+	}
+
+	if _, err := svc.Get(9999); err != ErrConfigRevisionNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
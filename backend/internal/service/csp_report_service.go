@@ -0,0 +1,251 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxCSPReportsPerRequest 单次上报最多处理的报告数（Reporting API 可能批量发送）
+const maxCSPReportsPerRequest = 20
+
+var errCSPReportInvalid = errors.New("invalid csp report")
+
+// CSPReportService 收集浏览器上报的 CSP 违规
+type CSPReportService struct {
+	db *gorm.DB
+}
+
+func NewCSPReportService(db *gorm.DB) *CSPReportService {
+	return &CSPReportService{db: db}
+}
+
+// CSPViolationFilter 违规列表筛选
+type CSPViolationFilter struct {
+	Directive   string
+	DocumentURI string
+}
+
+// legacyCSPReport report-uri 格式（application/csp-report）
+type legacyCSPReport struct {
+	Report struct {
+		DocumentURI        string      `json:"document-uri"`
+		ViolatedDirective  string      `json:"violated-directive"`
+		EffectiveDirective string      `json:"effective-directive"`
+		Disposition        string      `json:"disposition"`
+		BlockedURI         string      `json:"blocked-uri"`
+		SourceFile         string      `json:"source-file"`
+		LineNumber         json.Number `json:"line-number"`
+		ScriptSample       string      `json:"script-sample"`
+	} `json:"csp-report"`
+}
+
+// reportingAPIReport Reporting API 格式（application/reports+json）
+type reportingAPIReport struct {
+	Type string `json:"type"`
+	Body struct {
+		DocumentURL        string      `json:"documentURL"`
+		BlockedURL         string      `json:"blockedURL"`
+		EffectiveDirective string      `json:"effectiveDirective"`
+		Disposition        string      `json:"disposition"`
+		SourceFile         string      `json:"sourceFile"`
+		LineNumber         json.Number `json:"lineNumber"`
+		Sample             string      `json:"sample"`
+	} `json:"body"`
+}
+
+// ParseCSPReports 解析两种上报格式，返回规范化后的违规记录
+func ParseCSPReports(body []byte) ([]models.CSPViolation, error) {
+	trimmed := strings.TrimSpace(string(body))
+	if trimmed == "" {
+		return nil, errCSPReportInvalid
+	}
+
+	violations := make([]models.CSPViolation, 0, 1)
+	if strings.HasPrefix(trimmed, "[") {
+		var reports []reportingAPIReport
+		if err := json.Unmarshal([]byte(trimmed), &reports); err != nil {
+			return nil, errCSPReportInvalid
+		}
+		for _, report := range reports {
+			if report.Type != "csp-violation" {
+				continue
+			}
+			violations = append(violations, models.CSPViolation{
+				DocumentURI:        report.Body.DocumentURL,
+				BlockedURI:         report.Body.BlockedURL,
+				EffectiveDirective: report.Body.EffectiveDirective,
+				Disposition:        report.Body.Disposition,
+				SourceFile:         report.Body.SourceFile,
+				LineNumber:         parseCSPLineNumber(report.Body.LineNumber),
+				Sample:             report.Body.Sample,
+			})
+			if len(violations) >= maxCSPReportsPerRequest {
+				break
+			}
+		}
+	} else {
+		var report legacyCSPReport
+		if err := json.Unmarshal([]byte(trimmed), &report); err != nil {
+			return nil, errCSPReportInvalid
+		}
+		directive := report.Report.EffectiveDirective
+		if directive == "" {
+			// 旧版浏览器只有 violated-directive，形如 "script-src 'self'"
+			directive, _, _ = strings.Cut(strings.TrimSpace(report.Report.ViolatedDirective), " ")
+		}
+		violations = append(violations, models.CSPViolation{
+			DocumentURI:        report.Report.DocumentURI,
+			BlockedURI:         report.Report.BlockedURI,
+			EffectiveDirective: directive,
+			Disposition:        report.Report.Disposition,
+			SourceFile:         report.Report.SourceFile,
+			LineNumber:         parseCSPLineNumber(report.Report.LineNumber),
+			Sample:             report.Report.ScriptSample,
+		})
+	}
+
+	result := violations[:0]
+	for _, violation := range violations {
+		if normalizeCSPViolation(&violation) {
+			result = append(result, violation)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errCSPReportInvalid
+	}
+	return result, nil
+}
+
+func parseCSPLineNumber(value json.Number) int {
+	line, err := strconv.Atoi(value.String())
+	if err != nil || line < 0 {
+		return 0
+	}
+	return line
+}
+
+// stripCSPReportURL 去掉查询参数和片段，避免令牌等敏感信息入库
+func stripCSPReportURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme == "" {
+		// inline / eval / data 等关键字原样保留
+		return raw
+	}
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+	parsed.User = nil
+	return parsed.String()
+}
+
+func truncateCSPField(value string, max int) string {
+	value = strings.TrimSpace(value)
+	if len(value) <= max {
+		return value
+	}
+	return strings.ToValidUTF8(value[:max], "")
+}
+
+func normalizeCSPViolation(violation *models.CSPViolation) bool {
+	violation.DocumentURI = truncateCSPField(stripCSPReportURL(violation.DocumentURI), 512)
+	violation.BlockedURI = truncateCSPField(stripCSPReportURL(violation.BlockedURI), 512)
+	violation.SourceFile = truncateCSPField(stripCSPReportURL(violation.SourceFile), 512)
+	violation.EffectiveDirective = truncateCSPField(strings.ToLower(violation.EffectiveDirective), 64)
+	violation.Disposition = truncateCSPField(strings.ToLower(violation.Disposition), 20)
+	violation.Sample = truncateCSPField(violation.Sample, 255)
+	if violation.Disposition == "" {
+		violation.Disposition = "enforce"
+	}
+	if violation.DocumentURI == "" || violation.EffectiveDirective == "" {
+		return false
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		violation.DocumentURI,
+		violation.EffectiveDirective,
+		violation.BlockedURI,
+		violation.SourceFile,
+		strconv.Itoa(violation.LineNumber),
+		violation.Disposition,
+	}, "\x00")))
+	violation.Fingerprint = hex.EncodeToString(sum[:])
+	return true
+}
+
+// Record 解析并保存上报内容，相同违规只累加计数
+func (s *CSPReportService) Record(body []byte, ip, userAgent string) (int, error) {
+	violations, err := ParseCSPReports(body)
+	if err != nil {
+		return 0, err
+	}
+	userAgent = truncateCSPField(userAgent, 255)
+	for i := range violations {
+		if err := s.upsert(&violations[i], ip, userAgent); err != nil {
+			return i, err
+		}
+	}
+	return len(violations), nil
+}
+
+func (s *CSPReportService) upsert(violation *models.CSPViolation, ip, userAgent string) error {
+	now := models.NowFunc()
+	increment := func() (bool, error) {
+		result := s.db.Model(&models.CSPViolation{}).
+			Where("fingerprint = ?", violation.Fingerprint).
+			Updates(map[string]interface{}{
+				"count":           gorm.Expr("count + 1"),
+				"last_seen_at":    now,
+				"last_ip":         ip,
+				"last_user_agent": userAgent,
+			})
+		return result.RowsAffected > 0, result.Error
+	}
+
+	if updated, err := increment(); err != nil || updated {
+		return err
+	}
+	violation.Count = 1
+	violation.LastIP = ip
+	violation.LastUserAgent = userAgent
+	violation.FirstSeenAt = now
+	violation.LastSeenAt = now
+	if err := s.db.Create(violation).Error; err != nil {
+		// 并发上报同一违规时唯一索引冲突，改为累加
+		if updated, retryErr := increment(); retryErr == nil && updated {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// List 按最近发生时间倒序列出违规
+func (s *CSPReportService) List(filter CSPViolationFilter, page, limit int) ([]models.CSPViolation, int64, error) {
+	query := s.db.Model(&models.CSPViolation{})
+	if filter.Directive != "" {
+		query = query.Where("effective_directive = ?", strings.ToLower(filter.Directive))
+	}
+	if filter.DocumentURI != "" {
+		query = query.Where("document_uri LIKE ?", "%"+filter.DocumentURI+"%")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var violations []models.CSPViolation
+	err := query.Order("last_seen_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&violations).Error
+	return violations, total, err
+}
+
+// Clear 清空违规记录（调整策略后重新观察）
+func (s *CSPReportService) Clear() (int64, error) {
+	result := s.db.Where("1 = 1").Delete(&models.CSPViolation{})
+	return result.RowsAffected, result.Error
+}
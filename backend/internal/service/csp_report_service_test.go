@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestCSPReportServiceAggregatesViolations(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.CSPViolation{})
+	svc := NewCSPReportService(db)
+
+	legacy := []byte(`{"csp-report": {
+		"document-uri": "https://shop.example.com/?token=secret",
+		"violated-directive": "script-src-elem 'self'",
+		"blocked-uri": "https://evil.example.net/x.js?id=1",
+		"line-number": 12,
+		"disposition": "report"
+	}}`)
+	for i := 0; i < 3; i++ {
+		if n, err := svc.Record(legacy, "192.0.2.1", "test-agent"); err != nil || n != 1 {
+			t.Fatalf("record legacy report: %d, %v", n, err)
+		}
+	}
+
+	reportingAPI := []byte(`[
+		{"type": "csp-violation", "body": {"documentURL": "https://shop.example.com/", "blockedURL": "inline", "effectiveDirective": "style-src-elem", "lineNumber": 3}},
+		{"type": "deprecation", "body": {}}
+	]`)
+	if n, err := svc.Record(reportingAPI, "192.0.2.2", ""); err != nil || n != 1 {
+		t.Fatalf("record reporting api report: %d, %v", n, err)
+	}
+
+	for _, body := range []string{"", "not json", `{"csp-report": {}}`, `[{"type": "deprecation"}]`} {
+		if _, err := svc.Record([]byte(body), "192.0.2.3", ""); err == nil {
+			t.Fatalf("expected %q to be rejected", body)
+		}
+	}
+
+	violations, total, err := svc.List(CSPViolationFilter{Directive: "script-src-elem"}, 1, 20)
+	if err != nil || total != 1 {
+		t.Fatalf("expected one script violation, got %d, %v", total, err)
+	}
+	violation := violations[0]
+	if violation.Count != 3 || violation.DocumentURI != "https://shop.example.com/" || violation.BlockedURI != "https://evil.example.net/x.js" {
+		t.Fatalf("expected aggregated violation without query strings, got %+v", violation)
+	}
+	if violation.Disposition != "report" || violation.LineNumber != 12 || violation.LastIP != "192.0.2.1" {
+		t.Fatalf("unexpected violation fields: %+v", violation)
+	}
+
+	if _, total, _ := svc.List(CSPViolationFilter{}, 1, 20); total != 2 {
+		t.Fatalf("expected two distinct violations, got %d", total)
+	}
+	if deleted, err := svc.Clear(); err != nil || deleted != 2 {
+		t.Fatalf("clear violations: %d, %v", deleted, err)
+	}
+}
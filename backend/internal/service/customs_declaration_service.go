@@ -0,0 +1,192 @@
+package service
+
+import (
+	"strings"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+// 报关单类型
+const (
+	CustomsFormCN22 = "CN22"
+	CustomsFormCN23 = "CN23"
+)
+
+// CustomsDeclarationItem 报关单行，重量与价值为整行合计
+type CustomsDeclarationItem struct {
+	SKU           string `json:"sku"`
+	Description   string `json:"description"`
+	HSCode        string `json:"hs_code"`
+	OriginCountry string `json:"origin_country"`
+	Quantity      int    `json:"quantity"`
+	WeightGrams   int    `json:"weight_grams"`
+	ValueMinor    int64  `json:"value_minor"`
+}
+
+// CustomsDeclaration 单个订单的报关单（CN22/CN23）
+type CustomsDeclaration struct {
+	OrderNo          string                   `json:"order_no"`
+	FormType         string                   `json:"form_type"`
+	ContentCategory  string                   `json:"content_category"`
+	Currency         string                   `json:"currency"`
+	SenderName       string                   `json:"sender_name"`
+	SenderAddress    string                   `json:"sender_address"`
+	SenderPhone      string                   `json:"sender_phone"`
+	SenderCountry    string                   `json:"sender_country"`
+	ReceiverName     string                   `json:"receiver_name"`
+	ReceiverPhone    string                   `json:"receiver_phone"`
+	ReceiverAddress  string                   `json:"receiver_address"`
+	ReceiverPostcode string                   `json:"receiver_postcode"`
+	ReceiverCountry  string                   `json:"receiver_country"`
+	Items            []CustomsDeclarationItem `json:"items"`
+	TotalWeightGrams int                      `json:"total_weight_grams"`
+	TotalValueMinor  int64                    `json:"total_value_minor"`
+	MissingHSCodes   []string                 `json:"missing_hs_codes,omitempty"` // 未设置 HS 编码的 SKU
+}
+
+// CustomsDeclarationService 按订单汇总商品报关信息
+type CustomsDeclarationService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewCustomsDeclarationService(db *gorm.DB, cfg *config.Config) *CustomsDeclarationService {
+	return &CustomsDeclarationService{db: db, cfg: cfg}
+}
+
+// IsInternational 收件国家与发件国家不同即为国际件
+func (s *CustomsDeclarationService) IsInternational(order *models.Order) bool {
+	country := strings.ToUpper(strings.TrimSpace(order.ReceiverCountry))
+	return country != "" && country != s.cfg.Order.Customs.SenderCountry
+}
+
+// Build 生成订单报关单；虚拟商品不申报，订单没有实物商品时返回 nil
+func (s *CustomsDeclarationService) Build(order *models.Order) (*CustomsDeclaration, error) {
+	declarations, err := s.BuildForOrders([]models.Order{*order})
+	if err != nil || len(declarations) == 0 {
+		return nil, err
+	}
+	return &declarations[0], nil
+}
+
+// BuildForOrders 批量生成报关单，商品信息一次查询；跳过没有实物商品的订单
+func (s *CustomsDeclarationService) BuildForOrders(orders []models.Order) ([]CustomsDeclaration, error) {
+	skuSet := make(map[string]bool)
+	for i := range orders {
+		for _, item := range orders[i].Items {
+			if item.SKU != "" {
+				skuSet[item.SKU] = true
+			}
+		}
+	}
+	products := make(map[string]models.Product, len(skuSet))
+	if len(skuSet) > 0 {
+		skus := make([]string, 0, len(skuSet))
+		for sku := range skuSet {
+			skus = append(skus, sku)
+		}
+		// 已删除商品的历史订单仍需报关
+		var rows []models.Product
+		if err := s.db.Unscoped().
+			Select("id", "sku", "product_type", "hs_code", "declared_value_minor", "weight_grams", "origin_country").
+			Where("sku IN ?", skus).Order("deleted_at IS NOT NULL, id DESC").Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, product := range rows {
+			if _, exists := products[product.SKU]; !exists {
+				products[product.SKU] = product
+			}
+		}
+	}
+
+	declarations := make([]CustomsDeclaration, 0, len(orders))
+	for i := range orders {
+		if declaration := s.buildDeclaration(&orders[i], products); declaration != nil {
+			declarations = append(declarations, *declaration)
+		}
+	}
+	return declarations, nil
+}
+
+func (s *CustomsDeclarationService) buildDeclaration(order *models.Order, products map[string]models.Product) *CustomsDeclaration {
+	customsCfg := s.cfg.Order.Customs
+	invoiceCfg := s.cfg.Order.Invoice
+	currency := order.Currency
+	if currency == "" {
+		currency = s.cfg.Order.Currency
+	}
+	declaration := &CustomsDeclaration{
+		OrderNo:          order.OrderNo,
+		ContentCategory:  customsCfg.ContentCategory,
+		Currency:         currency,
+		SenderName:       invoiceCfg.CompanyName,
+		SenderAddress:    invoiceCfg.CompanyAddress,
+		SenderPhone:      invoiceCfg.CompanyPhone,
+		SenderCountry:    customsCfg.SenderCountry,
+		ReceiverName:     order.ReceiverName,
+		ReceiverPhone:    strings.TrimSpace(order.PhoneCode + " " + order.ReceiverPhone),
+		ReceiverAddress:  joinCustomsAddress(order.ReceiverAddress, order.ReceiverDistrict, order.ReceiverCity, order.ReceiverProvince),
+		ReceiverPostcode: order.ReceiverPostcode,
+		ReceiverCountry:  strings.ToUpper(order.ReceiverCountry),
+	}
+	defaultOrigin := customsCfg.DefaultOriginCountry
+	if defaultOrigin == "" {
+		defaultOrigin = customsCfg.SenderCountry
+	}
+	hasItemPricing := order.HasItemPricing()
+
+	for _, item := range order.Items {
+		if item.ProductType == models.ProductTypeVirtual {
+			continue
+		}
+		product, known := products[item.SKU]
+		if known && product.ProductType == models.ProductTypeVirtual && item.ProductType == "" {
+			continue
+		}
+		line := CustomsDeclarationItem{
+			SKU:           item.SKU,
+			Description:   item.Name,
+			HSCode:        product.HSCode,
+			OriginCountry: product.OriginCountry,
+			Quantity:      item.Quantity,
+			WeightGrams:   product.WeightGrams * item.Quantity,
+		}
+		if line.OriginCountry == "" {
+			line.OriginCountry = defaultOrigin
+		}
+		switch {
+		case product.DeclaredValueMinor > 0:
+			line.ValueMinor = product.DeclaredValueMinor * int64(item.Quantity)
+		case hasItemPricing && item.LineTotalMinor > 0:
+			line.ValueMinor = item.LineTotalMinor
+		default:
+			line.ValueMinor = item.UnitPriceMinor * int64(item.Quantity)
+		}
+		if line.HSCode == "" {
+			declaration.MissingHSCodes = append(declaration.MissingHSCodes, item.SKU)
+		}
+		declaration.Items = append(declaration.Items, line)
+		declaration.TotalWeightGrams += line.WeightGrams
+		declaration.TotalValueMinor += line.ValueMinor
+	}
+	if len(declaration.Items) == 0 {
+		return nil
+	}
+	declaration.FormType = CustomsFormCN22
+	if declaration.TotalValueMinor > customsCfg.CN22MaxValueMinor {
+		declaration.FormType = CustomsFormCN23
+	}
+	return declaration
+}
+
+func joinCustomsAddress(parts ...string) string {
+	values := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return strings.Join(values, ", ")
+}
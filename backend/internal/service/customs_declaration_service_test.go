@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func TestCustomsDeclarationBuild(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Product{})
+	cfg := &config.Config{}
+	cfg.Order.Currency = "USD"
+	cfg.Order.Customs = config.CustomsConfig{SenderCountry: "CN", ContentCategory: "sale_of_goods", CN22MaxValueMinor: 30000}
+	svc := NewCustomsDeclarationService(db, cfg)
+
+	products := []*models.Product{
+		{SKU: "MUG-1", Name: "Mug", HSCode: "691200", WeightGrams: 350, OriginCountry: "DE", DeclaredValueMinor: 800},
+		{SKU: "TEE-1", Name: "Tee", WeightGrams: 200},
+	}
+	for _, product := range products {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("create product failed: %v", err)
+		}
+	}
+
+	order := &models.Order{
+		OrderNo:         "CUS-1",
+		ReceiverCountry: "us",
+		Items: []models.OrderItem{
+			{SKU: "MUG-1", Name: "Mug", Quantity: 2, UnitPriceMinor: 1500, LineTotalMinor: 3000},
+			{SKU: "TEE-1", Name: "Tee", Quantity: 1, UnitPriceMinor: 2500, LineTotalMinor: 2500},
+			{SKU: "CODE-1", Name: "Gift code", Quantity: 1, ProductType: models.ProductTypeVirtual, UnitPriceMinor: 900},
+		},
+	}
+	if !svc.IsInternational(order) {
+		t.Fatalf("expected US order to be international")
+	}
+	declaration, err := svc.Build(order)
+	if err != nil || declaration == nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if len(declaration.Items) != 2 {
+		t.Fatalf("expected virtual item to be skipped, got %d items", len(declaration.Items))
+	}
+	if declaration.TotalWeightGrams != 900 || declaration.TotalValueMinor != 1600+2500 {
+		t.Fatalf("unexpected totals: weight=%d value=%d", declaration.TotalWeightGrams, declaration.TotalValueMinor)
+	}
+	if declaration.Items[1].OriginCountry != "CN" {
+		t.Fatalf("expected origin to fall back to sender country, got %q", declaration.Items[1].OriginCountry)
+	}
+	if declaration.FormType != CustomsFormCN22 || len(declaration.MissingHSCodes) != 1 || declaration.MissingHSCodes[0] != "TEE-1" {
+		t.Fatalf("unexpected declaration: %+v", declaration)
+	}
+
+	order.Items[1].Quantity = 20
+	order.Items[1].LineTotalMinor = 50000
+	if declaration, _ = svc.Build(order); declaration.FormType != CustomsFormCN23 {
+		t.Fatalf("expected CN23 above the CN22 value limit, got %s", declaration.FormType)
+	}
+
+	domestic := &models.Order{ReceiverCountry: "CN"}
+	if svc.IsInternational(domestic) {
+		t.Fatalf("expected CN order to be domestic")
+	}
+}
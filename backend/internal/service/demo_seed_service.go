@@ -0,0 +1,372 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/password"
+	"auralogic/internal/pkg/utils"
+	"auralogic/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	SeedSetDemo = "demo"
+
+	// DemoSeedPassword 示例账号的统一登录密码
+	DemoSeedPassword = "Demo@123456"
+
+	demoSeedOperator  = "seed:demo"
+	demoSeedMarkerSKU = "DEMO-TEE"
+)
+
+// SeedResult 一次种子数据写入的统计
+type SeedResult struct {
+	Set           string
+	Skipped       bool // 已写入过，本次未做任何修改
+	Users         int
+	Products      int
+	Inventories   int
+	VirtualStocks int
+	Orders        int
+}
+
+// SeedData 按名称写入示例数据（目前仅支持 demo）。已写入过时直接跳过，可重复执行；
+// 生产环境（app.env=production）仅在开启演示模式时允许写入
+func SeedData(db *gorm.DB, cfg *config.Config, set string) (*SeedResult, error) {
+	set = strings.ToLower(strings.TrimSpace(set))
+	if set != SeedSetDemo {
+		return nil, fmt.Errorf("unknown seed set %q (supported: %s)", set, SeedSetDemo)
+	}
+	if cfg != nil && strings.EqualFold(cfg.App.Env, "production") && !cfg.App.DemoMode {
+		return nil, errors.New("refusing to seed demo data into a production environment without app.demo_mode")
+	}
+
+	result := &SeedResult{Set: set}
+	var existing int64
+	if err := db.Model(&models.Product{}).Where("sku = ?", demoSeedMarkerSKU).Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		result.Skipped = true
+		return result, nil
+	}
+
+	currency := "CNY"
+	if cfg != nil && cfg.Order.Currency != "" {
+		currency = cfg.Order.Currency
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return seedDemoData(tx, currency, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type demoSeedVariant struct {
+	attrs map[string]string
+	stock int
+}
+
+type demoSeedProduct struct {
+	product  models.Product
+	variants []demoSeedVariant
+	// 虚拟商品的卡密
+	virtualCodes []string
+}
+
+func demoSeedProducts() []demoSeedProduct {
+	giftCodes := make([]string, 0, 20)
+	for i := 1; i <= 20; i++ {
+		giftCodes = append(giftCodes, fmt.Sprintf("DEMO-GIFT-%04d", i))
+	}
+	return []demoSeedProduct{
+		{
+			product: models.Product{
+				SKU:              demoSeedMarkerSKU,
+				Name:             "AuraLogic Demo T-Shirt",
+				ShortDescription: "Soft cotton tee for trying out size selection",
+				Description:      "<p>Sample physical product created by the demo seed.</p>",
+				Category:         "Apparel",
+				Tags:             []string{"demo"},
+				Price:            9900,
+				OriginalPrice:    12900,
+				Attributes: []models.ProductAttribute{
+					{Name: "Size", Values: []string{"S", "M", "L"}, Mode: models.AttributeModeUserSelect},
+				},
+				Status:     models.ProductStatusActive,
+				IsFeatured: true,
+				SortOrder:  30,
+			},
+			variants: []demoSeedVariant{
+				{attrs: map[string]string{"Size": "S"}, stock: 30},
+				{attrs: map[string]string{"Size": "M"}, stock: 50},
+				{attrs: map[string]string{"Size": "L"}, stock: 20},
+			},
+		},
+		{
+			product: models.Product{
+				SKU:              "DEMO-MUG",
+				Name:             "AuraLogic Demo Mug",
+				ShortDescription: "Ceramic mug with a single shared inventory",
+				Description:      "<p>Sample physical product without specifications.</p>",
+				Category:         "Home",
+				Tags:             []string{"demo"},
+				Price:            4900,
+				Status:           models.ProductStatusActive,
+				SortOrder:        20,
+			},
+			variants: []demoSeedVariant{{attrs: map[string]string{}, stock: 100}},
+		},
+		{
+			product: models.Product{
+				SKU:              "DEMO-GIFTCARD",
+				Name:             "AuraLogic Demo Gift Card",
+				ShortDescription: "Virtual product delivered from imported card codes",
+				Description:      "<p>Sample virtual product. Codes are delivered automatically after payment.</p>",
+				Category:         "Gift Cards",
+				Tags:             []string{"demo"},
+				ProductType:      models.ProductTypeVirtual,
+				Price:            5000,
+				Status:           models.ProductStatusActive,
+				IsRecommended:    true,
+				SortOrder:        10,
+			},
+			virtualCodes: giftCodes,
+		},
+	}
+}
+
+func seedDemoData(tx *gorm.DB, currency string, result *SeedResult) error {
+	productRepo := repository.NewProductRepository(tx)
+	inventoryRepo := repository.NewInventoryRepository(tx)
+	productService := NewProductService(productRepo, inventoryRepo)
+	inventoryService := NewInventoryService(inventoryRepo, productRepo)
+	bindingService := NewBindingService(repository.NewBindingRepository(tx), inventoryRepo, productRepo)
+	virtualInventoryService := NewVirtualInventoryService(tx)
+
+	users, err := seedDemoUsers(tx)
+	if err != nil {
+		return err
+	}
+	result.Users = len(users)
+
+	products := make(map[string]*models.Product)
+	// SKU + 规格 -> 库存，用于生成订单时记录库存绑定
+	inventories := make(map[string]*models.Inventory)
+	for _, spec := range demoSeedProducts() {
+		product := spec.product
+		if err := productService.CreateProduct(&product); err != nil {
+			return fmt.Errorf("create product %s: %w", product.SKU, err)
+		}
+		products[product.SKU] = &product
+		result.Products++
+
+		if len(spec.virtualCodes) > 0 {
+			virtualInventory := &models.VirtualInventory{
+				Name:     product.Name + " Codes",
+				SKU:      product.SKU,
+				Type:     models.VirtualInventoryTypeStatic,
+				IsActive: true,
+			}
+			if err := virtualInventoryService.CreateVirtualInventory(virtualInventory); err != nil {
+				return fmt.Errorf("create virtual inventory %s: %w", product.SKU, err)
+			}
+			count, err := virtualInventoryService.ImportFromText(virtualInventory.ID, strings.Join(spec.virtualCodes, "\n"), demoSeedOperator, nil)
+			if err != nil {
+				return fmt.Errorf("import virtual stock %s: %w", product.SKU, err)
+			}
+			if _, err := virtualInventoryService.CreateBinding(product.ID, virtualInventory.ID, false, 1, ""); err != nil {
+				return fmt.Errorf("bind virtual inventory %s: %w", product.SKU, err)
+			}
+			result.Inventories++
+			result.VirtualStocks += count
+			continue
+		}
+
+		totalStock := 0
+		for _, variant := range spec.variants {
+			name := product.Name
+			sku := product.SKU
+			if size := variant.attrs["Size"]; size != "" {
+				name += " / " + size
+				sku += "-" + size
+			}
+			inventory, err := inventoryService.CreateInventory(name, sku, variant.attrs, variant.stock, variant.stock, 5)
+			if err != nil {
+				return fmt.Errorf("create inventory %s: %w", sku, err)
+			}
+			notes := ""
+			if len(variant.attrs) > 0 {
+				notes = fmt.Sprintf(`{"Size":%q}`, variant.attrs["Size"])
+			}
+			if _, err := bindingService.CreateBinding(product.ID, inventory.ID, false, 1, notes); err != nil {
+				return fmt.Errorf("bind inventory %s: %w", sku, err)
+			}
+			inventories[product.SKU+"|"+variant.attrs["Size"]] = inventory
+			totalStock += variant.stock
+			result.Inventories++
+		}
+		if err := tx.Model(&models.Product{}).Where("id = ?", product.ID).Update("stock", totalStock).Error; err != nil {
+			return err
+		}
+	}
+
+	orders, err := seedDemoOrders(tx, currency, users, products, inventories)
+	if err != nil {
+		return err
+	}
+	result.Orders = orders
+	return nil
+}
+
+func seedDemoUsers(tx *gorm.DB) (map[string]*models.User, error) {
+	hash, err := password.HashPassword(DemoSeedPassword)
+	if err != nil {
+		return nil, err
+	}
+	specs := []struct {
+		key   string
+		email string
+		name  string
+		role  string
+	}{
+		{key: "admin", email: "admin@demo.local", name: "Demo Admin", role: "super_admin"},
+		{key: "alice", email: "alice@demo.local", name: "Alice Demo", role: "user"},
+		{key: "bob", email: "bob@demo.local", name: "Bob Demo", role: "user"},
+	}
+	users := make(map[string]*models.User, len(specs))
+	for _, spec := range specs {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("email = ?", spec.email).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("demo user %s already exists", spec.email)
+		}
+		user := &models.User{
+			UUID:                    uuid.New().String(),
+			Email:                   spec.email,
+			PasswordHash:            hash,
+			Name:                    spec.name,
+			Role:                    spec.role,
+			IsActive:                true,
+			EmailVerified:           true,
+			EmailVerificationExempt: spec.role != "user",
+			EmailNotifyOrder:        true,
+			EmailNotifyTicket:       true,
+		}
+		if err := tx.Create(user).Error; err != nil {
+			return nil, fmt.Errorf("create demo user %s: %w", spec.email, err)
+		}
+		users[spec.key] = user
+	}
+	return users, nil
+}
+
+func seedDemoOrders(tx *gorm.DB, currency string, users map[string]*models.User, products map[string]*models.Product, inventories map[string]*models.Inventory) (int, error) {
+	now := time.Now()
+	specs := []struct {
+		user     string
+		sku      string
+		size     string
+		quantity int
+		status   models.OrderStatus
+		age      time.Duration
+	}{
+		{user: "alice", sku: demoSeedMarkerSKU, size: "M", quantity: 1, status: models.OrderStatusCompleted, age: 72 * time.Hour},
+		{user: "alice", sku: "DEMO-MUG", quantity: 2, status: models.OrderStatusShipped, age: 24 * time.Hour},
+		{user: "bob", sku: demoSeedMarkerSKU, size: "L", quantity: 1, status: models.OrderStatusPending, age: 6 * time.Hour},
+		{user: "bob", sku: "DEMO-MUG", quantity: 1, status: models.OrderStatusPendingPayment, age: time.Hour},
+	}
+
+	for _, spec := range specs {
+		user := users[spec.user]
+		product := products[spec.sku]
+		inventory := inventories[spec.sku+"|"+spec.size]
+		total := product.Price * int64(spec.quantity)
+		createdAt := now.Add(-spec.age)
+
+		item := models.OrderItem{
+			SKU:            product.SKU,
+			Name:           product.Name,
+			Quantity:       spec.quantity,
+			ProductType:    product.ProductType,
+			UnitPriceMinor: product.Price,
+			LineTotalMinor: total,
+		}
+		if spec.size != "" {
+			item.Attributes = map[string]interface{}{"Size": spec.size}
+		}
+		order := &models.Order{
+			OrderNo:                   utils.GenerateOrderNo("DEMO"),
+			UserID:                    &user.ID,
+			Items:                     []models.OrderItem{item},
+			InventoryBindings:         map[int]uint{0: inventory.ID},
+			Status:                    spec.status,
+			ReceiverName:              user.Name,
+			PhoneCode:                 "+1",
+			ReceiverPhone:             "5550100",
+			ReceiverEmail:             user.Email,
+			ReceiverCountry:           "US",
+			ReceiverProvince:          "CA",
+			ReceiverCity:              "San Francisco",
+			ReceiverAddress:           "1 Demo Street",
+			ReceiverPostcode:          "94105",
+			UserEmail:                 user.Email,
+			EmailNotificationsEnabled: true,
+			TotalAmount:               total,
+			Currency:                  currency,
+			Source:                    "seed",
+			CreatedAt:                 createdAt,
+		}
+
+		inventoryColumn := "sold_quantity"
+		switch spec.status {
+		case models.OrderStatusPendingPayment:
+			deadline := createdAt.Add(24 * time.Hour)
+			order.PaymentDeadlineAt = &deadline
+			inventoryColumn = "reserved_quantity"
+		case models.OrderStatusShipped:
+			shippedAt := createdAt.Add(2 * time.Hour)
+			order.ShippedAt = &shippedAt
+			order.TrackingNo = "DEMO" + order.OrderNo[len(order.OrderNo)-8:]
+		case models.OrderStatusCompleted:
+			shippedAt := createdAt.Add(2 * time.Hour)
+			completedAt := createdAt.Add(48 * time.Hour)
+			order.ShippedAt = &shippedAt
+			order.CompletedAt = &completedAt
+			order.TrackingNo = "DEMO" + order.OrderNo[len(order.OrderNo)-8:]
+		}
+		if err := tx.Create(order).Error; err != nil {
+			return 0, fmt.Errorf("create demo order: %w", err)
+		}
+
+		if err := tx.Model(&models.Inventory{}).Where("id = ?", inventory.ID).
+			Update(inventoryColumn, gorm.Expr(inventoryColumn+" + ?", spec.quantity)).Error; err != nil {
+			return 0, err
+		}
+		if spec.status == models.OrderStatusPendingPayment {
+			continue
+		}
+		// 已付款订单计入销量与用户消费统计
+		if err := tx.Model(&models.Product{}).Where("id = ?", product.ID).
+			Update("sale_count", gorm.Expr("sale_count + ?", spec.quantity)).Error; err != nil {
+			return 0, err
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"total_spent_minor": gorm.Expr("total_spent_minor + ?", total),
+			"total_order_count": gorm.Expr("total_order_count + 1"),
+		}).Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(specs), nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func TestSeedDemoDataIsIdempotentAndConsistent(t *testing.T) {
+	db := openConcurrentServiceTestDB(t,
+		&models.User{},
+		&models.Product{},
+		&models.VirtualInventory{},
+		&models.VirtualProductStock{},
+		&models.ProductVirtualInventoryBinding{},
+		&models.InventoryLog{},
+		&models.Order{},
+	)
+	cfg := &config.Config{}
+	cfg.Order.Currency = "USD"
+
+	if _, err := SeedData(db, cfg, "unknown"); err == nil {
+		t.Fatal("unknown seed set should be rejected")
+	}
+	cfg.App.Env = "production"
+	if _, err := SeedData(db, cfg, SeedSetDemo); err == nil {
+		t.Fatal("demo data must not be seeded into production unless demo mode is on")
+	}
+	cfg.App.DemoMode = true
+
+	result, err := SeedData(db, cfg, SeedSetDemo)
+	if err != nil {
+		t.Fatalf("seed demo data: %v", err)
+	}
+	if result.Skipped || result.Users != 3 || result.Products != 3 || result.Orders != 4 || result.VirtualStocks != 20 {
+		t.Fatalf("unexpected seed result: %+v", result)
+	}
+
+	again, err := SeedData(db, cfg, SeedSetDemo)
+	if err != nil || !again.Skipped {
+		t.Fatalf("second seed should be skipped, got %+v, %v", again, err)
+	}
+	var products int64
+	db.Model(&models.Product{}).Count(&products)
+	if products != 3 {
+		t.Fatalf("expected 3 products after reseeding, got %d", products)
+	}
+
+	var tee models.Product
+	if err := db.Where("sku = ?", demoSeedMarkerSKU).First(&tee).Error; err != nil {
+		t.Fatalf("load demo product: %v", err)
+	}
+	if tee.Stock != 100 || tee.SaleCount != 2 {
+		t.Fatalf("unexpected demo product stock/sales: stock=%d sales=%d", tee.Stock, tee.SaleCount)
+	}
+	var mugInventory models.Inventory
+	if err := db.Where("sku = ?", "DEMO-MUG").First(&mugInventory).Error; err != nil {
+		t.Fatalf("load mug inventory: %v", err)
+	}
+	if mugInventory.SoldQuantity != 2 || mugInventory.ReservedQuantity != 1 {
+		t.Fatalf("inventory must reflect seeded orders, got sold=%d reserved=%d", mugInventory.SoldQuantity, mugInventory.ReservedQuantity)
+	}
+
+	var alice models.User
+	if err := db.Where("email = ?", "alice@demo.local").First(&alice).Error; err != nil {
+		t.Fatalf("load demo user: %v", err)
+	}
+	if alice.TotalOrderCount != 2 || alice.TotalSpentMinor != 9900+2*4900 {
+		t.Fatalf("unexpected demo user stats: %+v", alice)
+	}
+	var order models.Order
+	if err := db.Where("user_id = ?", alice.ID).First(&order).Error; err != nil || order.Currency != "USD" {
+		t.Fatalf("demo orders should use the configured currency: %+v, %v", order, err)
+	}
+}
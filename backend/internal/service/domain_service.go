@@ -0,0 +1,494 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/logger"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+	"gorm.io/gorm"
+)
+
+const (
+	domainVerificationLookupTimeout = 10 * time.Second
+	defaultLandingPageSlug          = "home"
+)
+
+var (
+	landingPageSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,99}$`)
+
+	ErrDomainNotFound = bizerr.New("domain.notFound", "Domain not found")
+)
+
+// DomainInput 注册/更新自定义域名参数
+type DomainInput struct {
+	Host            string `json:"host"`
+	StoreID         *uint  `json:"store_id"`
+	LandingPageSlug string `json:"landing_page_slug"`
+}
+
+// DomainService 自定义域名：所有权验证、落地页选择与 ACME 证书自动签发/续期
+type DomainService struct {
+	db           *gorm.DB
+	cfg          *config.Config
+	storeService *StoreService
+
+	// lookupTXT 可在测试中替换
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+
+	managerMu sync.Mutex
+	manager   *autocert.Manager
+
+	lifecycleMu sync.Mutex
+	running     bool
+	stopChan    chan struct{}
+	doneChan    chan struct{}
+}
+
+func NewDomainService(db *gorm.DB, cfg *config.Config, storeService *StoreService) *DomainService {
+	return &DomainService{
+		db:           db,
+		cfg:          cfg,
+		storeService: storeService,
+		lookupTXT:    net.DefaultResolver.LookupTXT,
+	}
+}
+
+// NormalizeLandingPageSlug 规范化落地页 slug，空值返回默认 home
+func NormalizeLandingPageSlug(slug string) (string, bool) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if slug == "" {
+		return defaultLandingPageSlug, true
+	}
+	return slug, landingPageSlugPattern.MatchString(slug)
+}
+
+func newStoreDomain(host string) (*models.StoreDomain, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("generate domain verification token: %w", err)
+	}
+	return &models.StoreDomain{
+		Host:               host,
+		VerificationToken:  hex.EncodeToString(buf),
+		VerificationStatus: models.DomainVerificationPending,
+		CertStatus:         models.DomainCertNone,
+	}, nil
+}
+
+func (s *DomainService) invalidateStoreCache() {
+	if s.storeService != nil {
+		s.storeService.InvalidateCache()
+	}
+}
+
+func (s *DomainService) validateInput(input *DomainInput) error {
+	if input.StoreID != nil && *input.StoreID == 0 {
+		input.StoreID = nil
+	}
+	if input.StoreID != nil && s.storeService != nil {
+		if _, err := s.storeService.Get(*input.StoreID); err != nil {
+			return err
+		}
+	}
+
+	input.LandingPageSlug = strings.TrimSpace(input.LandingPageSlug)
+	if input.LandingPageSlug == "" {
+		return nil
+	}
+	slug, ok := NormalizeLandingPageSlug(input.LandingPageSlug)
+	if ok {
+		var count int64
+		if err := s.db.Model(&models.LandingPage{}).Where("slug = ?", slug).Count(&count).Error; err != nil {
+			return err
+		}
+		ok = count > 0
+	}
+	if !ok {
+		return bizerr.Newf("domain.landingPageNotFound", "Landing page %s not found", input.LandingPageSlug).
+			WithParams(map[string]interface{}{"slug": input.LandingPageSlug})
+	}
+	input.LandingPageSlug = slug
+	return nil
+}
+
+// List 获取全部自定义域名（含店铺域名）
+func (s *DomainService) List() ([]models.StoreDomain, error) {
+	var domains []models.StoreDomain
+	err := s.db.Order("store_id ASC, is_primary DESC, host ASC").Find(&domains).Error
+	return domains, err
+}
+
+// Get 获取域名详情
+func (s *DomainService) Get(id uint) (*models.StoreDomain, error) {
+	var domain models.StoreDomain
+	if err := s.db.First(&domain, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDomainNotFound
+		}
+		return nil, err
+	}
+	return &domain, nil
+}
+
+// Register 注册自定义域名，新域名处于待验证状态
+func (s *DomainService) Register(input DomainInput) (*models.StoreDomain, error) {
+	hosts, err := normalizeStoreDomains([]string{input.Host})
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) == 0 {
+		return nil, bizerr.Newf("store.domainInvalid", "Invalid domain: %s", input.Host).
+			WithParams(map[string]interface{}{"domain": input.Host})
+	}
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := s.db.Model(&models.StoreDomain{}).Where("host = ?", hosts[0]).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, bizerr.Newf("domain.alreadyExists", "Domain %s is already registered", hosts[0]).
+			WithParams(map[string]interface{}{"domain": hosts[0]})
+	}
+
+	domain, err := newStoreDomain(hosts[0])
+	if err != nil {
+		return nil, err
+	}
+	if input.StoreID != nil {
+		domain.StoreID = *input.StoreID
+	}
+	domain.LandingPageSlug = input.LandingPageSlug
+	if err := s.db.Create(domain).Error; err != nil {
+		return nil, err
+	}
+	s.invalidateStoreCache()
+	return domain, nil
+}
+
+// Update 更新域名所属店铺与落地页（Host 不可修改）
+func (s *DomainService) Update(id uint, input DomainInput) (*models.StoreDomain, error) {
+	domain, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+
+	var storeID uint
+	if input.StoreID != nil {
+		storeID = *input.StoreID
+	}
+	updates := map[string]interface{}{
+		"store_id":          storeID,
+		"landing_page_slug": input.LandingPageSlug,
+	}
+	if storeID != domain.StoreID {
+		updates["is_primary"] = false
+	}
+	if err := s.db.Model(&models.StoreDomain{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	s.invalidateStoreCache()
+	return s.Get(id)
+}
+
+// Delete 删除域名
+func (s *DomainService) Delete(id uint) error {
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+	if err := s.db.Delete(&models.StoreDomain{}, id).Error; err != nil {
+		return err
+	}
+	s.invalidateStoreCache()
+	return nil
+}
+
+// ResolveDomainLandingPageSlug 根据请求 Host 查找域名配置的落地页，未配置时返回 home
+func ResolveDomainLandingPageSlug(db *gorm.DB, host string) string {
+	host = NormalizeStoreHost(host)
+	if host == "" || db == nil {
+		return defaultLandingPageSlug
+	}
+	var domains []models.StoreDomain
+	if err := db.Select("landing_page_slug").Where("host = ?", host).Limit(1).Find(&domains).Error; err != nil || len(domains) == 0 {
+		return defaultLandingPageSlug
+	}
+	if slug, ok := NormalizeLandingPageSlug(domains[0].LandingPageSlug); ok {
+		return slug
+	}
+	return defaultLandingPageSlug
+}
+
+// Verify 通过 DNS TXT 记录验证域名所有权，验证通过且启用 ACME 时异步签发证书
+func (s *DomainService) Verify(id uint) (*models.StoreDomain, error) {
+	domain, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	verified := s.checkOwnership(domain)
+	if verified && s.ACMEEnabled() && domain.CertStatus != models.DomainCertIssued {
+		go s.ensureCertificate(*domain)
+	}
+	return s.Get(id)
+}
+
+func (s *DomainService) checkOwnership(domain *models.StoreDomain) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), domainVerificationLookupTimeout)
+	defer cancel()
+
+	now := time.Now()
+	updates := map[string]interface{}{"verification_checked_at": now}
+
+	records, err := s.lookupTXT(ctx, domain.VerificationRecordName())
+	matched := false
+	for _, record := range records {
+		if strings.TrimSpace(record) == domain.VerificationToken {
+			matched = true
+			break
+		}
+	}
+	switch {
+	case matched:
+		updates["verification_status"] = models.DomainVerificationVerified
+		updates["verification_error"] = ""
+		if domain.VerifiedAt == nil {
+			updates["verified_at"] = now
+		}
+	case err != nil:
+		updates["verification_status"] = models.DomainVerificationFailed
+		updates["verification_error"] = truncateDomainError(fmt.Sprintf("TXT lookup failed: %v", err))
+	default:
+		updates["verification_status"] = models.DomainVerificationFailed
+		updates["verification_error"] = fmt.Sprintf("TXT record %s does not contain the verification token", domain.VerificationRecordName())
+	}
+
+	if err := s.db.Model(&models.StoreDomain{}).Where("id = ?", domain.ID).Updates(updates).Error; err != nil {
+		log.Printf("domain verification status update failed: host=%s err=%v", domain.Host, err)
+	}
+	if matched && domain.VerificationStatus != models.DomainVerificationVerified {
+		logger.LogSystemOperation(s.db, "domain_verified", "store_domain", &domain.ID, map[string]interface{}{
+			"host": domain.Host,
+		})
+	}
+	return matched
+}
+
+func truncateDomainError(msg string) string {
+	if len(msg) > 500 {
+		return msg[:500]
+	}
+	return msg
+}
+
+// ACMEEnabled 是否启用证书自动签发
+func (s *DomainService) ACMEEnabled() bool {
+	return s.cfg != nil && s.cfg.ACME.Enabled
+}
+
+// hostPolicy 仅允许为已验证的域名签发证书
+func (s *DomainService) hostPolicy(_ context.Context, host string) error {
+	host = NormalizeStoreHost(host)
+	var count int64
+	if err := s.db.Model(&models.StoreDomain{}).
+		Where("host = ? AND verification_status = ?", host, models.DomainVerificationVerified).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("acme: host %q is not a verified custom domain", host)
+	}
+	return nil
+}
+
+// Manager 获取 ACME 证书管理器（懒加载）
+func (s *DomainService) Manager() *autocert.Manager {
+	s.managerMu.Lock()
+	defer s.managerMu.Unlock()
+	if s.manager != nil {
+		return s.manager
+	}
+
+	acmeCfg := s.cfg.ACME
+	manager := &autocert.Manager{
+		Prompt:      autocert.AcceptTOS,
+		Cache:       autocert.DirCache(acmeCfg.CacheDir),
+		HostPolicy:  s.hostPolicy,
+		Email:       strings.TrimSpace(acmeCfg.Email),
+		RenewBefore: time.Duration(acmeCfg.RenewBeforeDays) * 24 * time.Hour,
+	}
+	if directoryURL := strings.TrimSpace(acmeCfg.DirectoryURL); directoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: directoryURL}
+	}
+	s.manager = manager
+	return manager
+}
+
+// TLSConfig HTTPS 监听使用的 TLS 配置
+func (s *DomainService) TLSConfig() *tls.Config {
+	return s.Manager().TLSConfig()
+}
+
+// HTTPHandler 处理 HTTP-01 验证请求，其余请求交给 fallback
+func (s *DomainService) HTTPHandler(fallback http.Handler) http.Handler {
+	return s.Manager().HTTPHandler(fallback)
+}
+
+// ensureCertificate 主动签发/续期证书并记录状态
+func (s *DomainService) ensureCertificate(domain models.StoreDomain) {
+	now := time.Now()
+	s.db.Model(&models.StoreDomain{}).Where("id = ?", domain.ID).Updates(map[string]interface{}{
+		"cert_status":     models.DomainCertPending,
+		"cert_checked_at": now,
+	})
+
+	// 模拟支持 ECDSA 的握手，命中缓存时直接返回，否则通过 ACME 签发（autocert 内部有超时控制）
+	cert, err := s.Manager().GetCertificate(&tls.ClientHelloInfo{
+		ServerName:   domain.Host,
+		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+	})
+
+	updates := map[string]interface{}{"cert_checked_at": time.Now()}
+	if err == nil {
+		var expiresAt time.Time
+		expiresAt, err = certificateNotAfter(cert)
+		if err == nil {
+			updates["cert_status"] = models.DomainCertIssued
+			updates["cert_error"] = ""
+			updates["cert_expires_at"] = expiresAt
+		}
+	}
+	if err != nil {
+		log.Printf("domain certificate issue failed: host=%s err=%v", domain.Host, err)
+		updates["cert_status"] = models.DomainCertFailed
+		updates["cert_error"] = truncateDomainError(err.Error())
+	}
+	if dbErr := s.db.Model(&models.StoreDomain{}).Where("id = ?", domain.ID).Updates(updates).Error; dbErr != nil {
+		log.Printf("domain certificate status update failed: host=%s err=%v", domain.Host, dbErr)
+	}
+}
+
+func certificateNotAfter(cert *tls.Certificate) (time.Time, error) {
+	if cert == nil || len(cert.Certificate) == 0 {
+		return time.Time{}, fmt.Errorf("empty certificate")
+	}
+	if cert.Leaf != nil {
+		return cert.Leaf.NotAfter, nil
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return leaf.NotAfter, nil
+}
+
+// Start 启动域名巡检服务：重试待验证域名，并在启用 ACME 时签发/续期证书
+func (s *DomainService) Start() {
+	s.lifecycleMu.Lock()
+	if s.running {
+		s.lifecycleMu.Unlock()
+		return
+	}
+	stopChan := make(chan struct{})
+	doneChan := make(chan struct{})
+	s.stopChan = stopChan
+	s.doneChan = doneChan
+	s.running = true
+	s.lifecycleMu.Unlock()
+
+	go func() {
+		defer close(doneChan)
+		runBackgroundServiceWithStopChan("domain.checkLoop", stopChan, s.checkLoop)
+	}()
+}
+
+// Stop 停止域名巡检服务
+func (s *DomainService) Stop() {
+	s.lifecycleMu.Lock()
+	if !s.running {
+		s.lifecycleMu.Unlock()
+		return
+	}
+	stopChan := s.stopChan
+	doneChan := s.doneChan
+	s.stopChan = nil
+	s.doneChan = nil
+	s.running = false
+
+	close(stopChan)
+	<-doneChan
+	s.lifecycleMu.Unlock()
+}
+
+func (s *DomainService) checkInterval() time.Duration {
+	minutes := s.cfg.ACME.CheckIntervalMinutes
+	if minutes <= 0 {
+		minutes = 60
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func (s *DomainService) checkLoop(stopChan <-chan struct{}) {
+	s.checkDomains()
+
+	ticker := time.NewTicker(s.checkInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			s.checkDomains()
+		}
+	}
+}
+
+// checkDomains 巡检一次全部域名
+func (s *DomainService) checkDomains() {
+	var pending []models.StoreDomain
+	if err := s.db.Where("verification_status <> ?", models.DomainVerificationVerified).
+		Order("id ASC").Limit(100).Find(&pending).Error; err != nil {
+		log.Printf("domain check: query pending domains failed: %v", err)
+		return
+	}
+	for i := range pending {
+		s.checkOwnership(&pending[i])
+	}
+
+	if !s.ACMEEnabled() {
+		return
+	}
+	renewBefore := time.Now().Add(time.Duration(s.cfg.ACME.RenewBeforeDays) * 24 * time.Hour)
+	var due []models.StoreDomain
+	if err := s.db.Where("verification_status = ?", models.DomainVerificationVerified).
+		Where("cert_status <> ? OR cert_expires_at IS NULL OR cert_expires_at < ?", models.DomainCertIssued, renewBefore).
+		Order("id ASC").Limit(20).Find(&due).Error; err != nil {
+		log.Printf("domain check: query certificate renewals failed: %v", err)
+		return
+	}
+	for _, domain := range due {
+		s.ensureCertificate(domain)
+	}
+}
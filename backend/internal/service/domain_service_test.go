@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func newDomainServiceForTest(t *testing.T) (*DomainService, *StoreService) {
+	t.Helper()
+
+	storeService, db := newStoreServiceTestDB(t)
+	if err := db.AutoMigrate(&models.LandingPage{}, &models.OperationLog{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	if err := db.Create(&models.LandingPage{Slug: "home", HTMLContent: "home", IsActive: true}).Error; err != nil {
+		t.Fatalf("create home page: %v", err)
+	}
+	if err := db.Create(&models.LandingPage{Slug: "promo", HTMLContent: "promo", IsActive: true}).Error; err != nil {
+		t.Fatalf("create promo page: %v", err)
+	}
+	return NewDomainService(db, &config.Config{}, storeService), storeService
+}
+
+func TestDomainServiceVerifiesOwnershipViaTXTRecord(t *testing.T) {
+	svc, _ := newDomainServiceForTest(t)
+
+	domain, err := svc.Register(DomainInput{Host: "Shop.Example.com", LandingPageSlug: "promo"})
+	if err != nil {
+		t.Fatalf("register domain: %v", err)
+	}
+	if domain.Host != "shop.example.com" || domain.VerificationStatus != models.DomainVerificationPending || domain.VerificationToken == "" {
+		t.Fatalf("unexpected registered domain: %+v", domain)
+	}
+	if slug := ResolveDomainLandingPageSlug(svc.db, "SHOP.example.com:443"); slug != "promo" {
+		t.Fatalf("expected promo landing page, got %q", slug)
+	}
+	if slug := ResolveDomainLandingPageSlug(svc.db, "other.example.com"); slug != "home" {
+		t.Fatalf("expected home landing page for unknown host, got %q", slug)
+	}
+
+	records := map[string][]string{}
+	svc.lookupTXT = func(_ context.Context, name string) ([]string, error) {
+		if values, ok := records[name]; ok {
+			return values, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	checked, err := svc.Verify(domain.ID)
+	if err != nil {
+		t.Fatalf("verify domain: %v", err)
+	}
+	if checked.VerificationStatus != models.DomainVerificationFailed || checked.VerificationError == "" {
+		t.Fatalf("expected failed verification without TXT record, got %+v", checked)
+	}
+	if err := svc.hostPolicy(context.Background(), domain.Host); err == nil {
+		t.Fatalf("expected unverified domain to be rejected by ACME host policy")
+	}
+
+	records["_auralogic-challenge.shop.example.com"] = []string{"unrelated", domain.VerificationToken}
+	checked, err = svc.Verify(domain.ID)
+	if err != nil {
+		t.Fatalf("verify domain: %v", err)
+	}
+	if checked.VerificationStatus != models.DomainVerificationVerified || checked.VerifiedAt == nil || checked.VerificationError != "" {
+		t.Fatalf("expected verified domain, got %+v", checked)
+	}
+	if err := svc.hostPolicy(context.Background(), "shop.example.com"); err != nil {
+		t.Fatalf("expected verified domain to pass ACME host policy: %v", err)
+	}
+}
+
+func TestDomainServiceRejectsInvalidInput(t *testing.T) {
+	svc, _ := newDomainServiceForTest(t)
+
+	if _, err := svc.Register(DomainInput{Host: "shop.example.com"}); err != nil {
+		t.Fatalf("register domain: %v", err)
+	}
+	requireProductBizErr(t, func() error {
+		_, err := svc.Register(DomainInput{Host: "shop.example.com."})
+		return err
+	}(), "domain.alreadyExists")
+	requireProductBizErr(t, func() error {
+		_, err := svc.Register(DomainInput{Host: "other.example.com", LandingPageSlug: "missing"})
+		return err
+	}(), "domain.landingPageNotFound")
+	missingStore := uint(99)
+	requireProductBizErr(t, func() error {
+		_, err := svc.Register(DomainInput{Host: "other.example.com", StoreID: &missingStore})
+		return err
+	}(), "store.notFound")
+}
+
+func TestStoreUpdateKeepsDomainVerificationState(t *testing.T) {
+	svc, storeService := newDomainServiceForTest(t)
+
+	domain, err := svc.Register(DomainInput{Host: "outlet.example.com"})
+	if err != nil {
+		t.Fatalf("register domain: %v", err)
+	}
+	if err := svc.db.Model(&models.StoreDomain{}).Where("id = ?", domain.ID).
+		Update("verification_status", models.DomainVerificationVerified).Error; err != nil {
+		t.Fatalf("mark verified: %v", err)
+	}
+
+	// 未绑定店铺的自定义域名可被店铺认领，且保留验证状态
+	store, err := storeService.Create(StoreInput{Code: "outlet", Name: "Outlet", Domains: []string{"outlet.example.com", "www.outlet.example.com"}})
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	if _, err := storeService.Update(store.ID, StoreInput{Code: "outlet", Name: "Outlet", Domains: []string{"outlet.example.com"}}); err != nil {
+		t.Fatalf("update store: %v", err)
+	}
+
+	claimed, err := svc.Get(domain.ID)
+	if err != nil {
+		t.Fatalf("get domain: %v", err)
+	}
+	if claimed.StoreID != store.ID || !claimed.IsPrimary || claimed.VerificationStatus != models.DomainVerificationVerified {
+		t.Fatalf("expected claimed verified primary domain, got %+v", claimed)
+	}
+	domains, err := svc.List()
+	if err != nil {
+		t.Fatalf("list domains: %v", err)
+	}
+	if len(domains) != 1 {
+		t.Fatalf("expected removed store domain to be deleted, got %+v", domains)
+	}
+
+	if err := storeService.Delete(store.ID); err != nil {
+		t.Fatalf("delete store: %v", err)
+	}
+	released, err := svc.Get(domain.ID)
+	if err != nil {
+		t.Fatalf("expected domain to survive store deletion: %v", err)
+	}
+	if released.StoreID != 0 || released.VerificationStatus != models.DomainVerificationVerified {
+		t.Fatalf("expected released verified domain, got %+v", released)
+	}
+}
@@ -0,0 +1,138 @@
+//go:build e2e
+
+package service
+
+import (
+	"errors"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/cache"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+// E2EFixtureService 端到端测试专用的数据操作，仅在 -tags e2e 构建中存在
+type E2EFixtureService struct {
+	db            *gorm.DB
+	cfg           *config.Config
+	orderService  *OrderService
+	cancelService *OrderCancelService
+}
+
+// E2EOrderCancelRunResult 手动触发自动取消任务的结果
+type E2EOrderCancelRunResult struct {
+	CancelledOrders []string `json:"cancelled_orders"`
+	ExpiredDrafts   []string `json:"expired_drafts"`
+}
+
+// NewE2EFixtureService 创建测试夹具服务
+func NewE2EFixtureService(db *gorm.DB, cfg *config.Config, orderService *OrderService, serialService *SerialService, pluginManager *PluginManagerService) *E2EFixtureService {
+	cancelService := NewOrderCancelService(
+		db,
+		cfg,
+		repository.NewInventoryRepository(db),
+		repository.NewPromoCodeRepository(db),
+		NewVirtualInventoryService(db),
+		serialService,
+	)
+	cancelService.SetPluginManager(pluginManager)
+	return &E2EFixtureService{
+		db:            db,
+		cfg:           cfg,
+		orderService:  orderService,
+		cancelService: cancelService,
+	}
+}
+
+func (s *E2EFixtureService) findOrder(orderNo string) (*models.Order, error) {
+	var order models.Order
+	if err := s.db.Where("order_no = ?", orderNo).First(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// ExpireForm 将草稿订单的表单链接过期时间回拨到宽限期之前，下次自动取消任务即可将其取消
+func (s *E2EFixtureService) ExpireForm(orderNo string) (*models.Order, error) {
+	order, err := s.findOrder(orderNo)
+	if err != nil {
+		return nil, err
+	}
+	if order.FormToken == nil {
+		return nil, errors.New("order has no shipping form")
+	}
+	expiredAt := models.NowFunc().Add(-time.Duration(s.cancelService.getDraftExpireHours()+1) * time.Hour)
+	if err := s.db.Model(order).Update("form_expires_at", expiredAt).Error; err != nil {
+		return nil, err
+	}
+	order.FormExpiresAt = &expiredAt
+	return order, nil
+}
+
+// ExpirePayment 将待付款订单的付款截止时间回拨到当前时间之前
+func (s *E2EFixtureService) ExpirePayment(orderNo string) (*models.Order, error) {
+	order, err := s.findOrder(orderNo)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPendingPayment {
+		return nil, errors.New("order is not pending payment")
+	}
+	deadline := time.Now().Add(-time.Minute)
+	if err := s.db.Model(order).Update("payment_deadline_at", deadline).Error; err != nil {
+		return nil, err
+	}
+	order.PaymentDeadlineAt = &deadline
+	return order, nil
+}
+
+// RunOrderCancel 立即执行一次待付款超时取消与草稿过期任务，不发送付款提醒
+func (s *E2EFixtureService) RunOrderCancel() (*E2EOrderCancelRunResult, error) {
+	var pendingBefore, draftsBefore []string
+	if err := s.db.Model(&models.Order{}).Where("status = ?", models.OrderStatusPendingPayment).Pluck("order_no", &pendingBefore).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(&models.Order{}).Where("status = ?", models.OrderStatusDraft).Pluck("order_no", &draftsBefore).Error; err != nil {
+		return nil, err
+	}
+
+	s.cancelService.cancelExpiredOrders()
+	s.cancelService.expireStaleDrafts()
+
+	result := &E2EOrderCancelRunResult{CancelledOrders: []string{}, ExpiredDrafts: []string{}}
+	if len(pendingBefore) > 0 {
+		if err := s.db.Model(&models.Order{}).Where("order_no IN ? AND status = ?", pendingBefore, models.OrderStatusCancelled).
+			Pluck("order_no", &result.CancelledOrders).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(draftsBefore) > 0 {
+		if err := s.db.Model(&models.Order{}).Where("order_no IN ? AND status = ?", draftsBefore, models.OrderStatusCancelled).
+			Pluck("order_no", &result.ExpiredDrafts).Error; err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// ConfirmPayment 跳过支付网关直接将待付款订单标记为已付款
+func (s *E2EFixtureService) ConfirmPayment(orderNo string) (*models.Order, error) {
+	order, err := s.findOrder(orderNo)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.orderService.MarkAsPaidWithOptions(order.ID, MarkAsPaidOptions{
+		AdminRemark:   "E2E fixture: payment confirmed",
+		PaymentSource: "e2e_fixture",
+	}); err != nil {
+		return nil, err
+	}
+	return s.findOrder(orderNo)
+}
+
+// ResetRateLimits 清空接口限流计数与验证码发送冷却
+func (s *E2EFixtureService) ResetRateLimits() (int64, error) {
+	return cache.DeleteByPatterns("rate:*", "*_cooldown:*")
+}
@@ -0,0 +1,79 @@
+//go:build e2e
+
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/cache"
+	"auralogic/internal/repository"
+)
+
+func TestE2EFixtureExpirePaymentAndConfirm(t *testing.T) {
+	db := openConcurrentServiceTestDB(t,
+		&models.InventoryLog{},
+		&models.PromoCode{},
+		&models.PromoCodeRedemption{},
+		&models.VirtualInventory{},
+		&models.VirtualProductStock{},
+		&models.Order{},
+		&models.OrderNote{},
+	)
+	cfg := &config.Config{}
+	orderService := NewOrderService(
+		repository.NewOrderRepository(db),
+		repository.NewUserRepository(db),
+		nil,
+		repository.NewInventoryRepository(db),
+		nil,
+		nil,
+		nil,
+		repository.NewPromoCodeRepository(db),
+		cfg,
+		nil,
+	)
+	svc := NewE2EFixtureService(db, cfg, orderService, nil, nil)
+
+	for _, orderNo := range []string{"E2E-EXPIRE", "E2E-PAY", "E2E-KEEP"} {
+		order := models.Order{
+			OrderNo:     orderNo,
+			Status:      models.OrderStatusPendingPayment,
+			Items:       []models.OrderItem{{SKU: "TEE", Name: "Tee", Quantity: 1}},
+			TotalAmount: 1000,
+			Currency:    "USD",
+		}
+		if err := db.Create(&order).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+	}
+
+	if _, err := svc.ExpirePayment("E2E-EXPIRE"); err != nil {
+		t.Fatalf("expire payment: %v", err)
+	}
+	result, err := svc.RunOrderCancel()
+	if err != nil || len(result.CancelledOrders) != 1 || result.CancelledOrders[0] != "E2E-EXPIRE" {
+		t.Fatalf("only the expired order should be cancelled: %+v, %v", result, err)
+	}
+	if _, err := svc.ExpirePayment("E2E-EXPIRE"); err == nil {
+		t.Fatal("cancelled order should not accept payment expiry")
+	}
+
+	paid, err := svc.ConfirmPayment("E2E-PAY")
+	if err != nil || paid.Status == models.OrderStatusPendingPayment {
+		t.Fatalf("confirm payment should move the order out of pending payment: %+v, %v", paid, err)
+	}
+	if _, err := svc.ExpireForm("E2E-KEEP"); err == nil {
+		t.Fatal("orders without a shipping form cannot expire their form")
+	}
+
+	cache.InitMemory()
+	cache.Incr("rate:ip:127.0.0.1:1")
+	cache.Set("email_login_cooldown:ip:127.0.0.1", "1", 0)
+	cache.Set("unrelated", "1", 0)
+	deleted, err := svc.ResetRateLimits()
+	if err != nil || deleted != 2 {
+		t.Fatalf("expected rate limit and cooldown keys to be cleared, got %d, %v", deleted, err)
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/authbiz"
+	"gorm.io/gorm"
+)
+
+// EmailChangeRevertTTL 旧邮箱撤销链接有效期
+const EmailChangeRevertTTL = 7 * 24 * time.Hour
+
+// EmailChangeService 邮箱变更：新邮箱验证通过后生效，旧邮箱收到可撤销的通知
+type EmailChangeService struct {
+	db           *gorm.DB
+	emailService *EmailService
+}
+
+func NewEmailChangeService(db *gorm.DB, emailService *EmailService) *EmailChangeService {
+	return &EmailChangeService{db: db, emailService: emailService}
+}
+
+// SyncUserEmailReferences 将订单通知邮箱、留言/工单中的发送者名称从旧邮箱迁移到新邮箱
+func SyncUserEmailReferences(tx *gorm.DB, userID uint, oldEmail, newEmail string) error {
+	if oldEmail == "" || oldEmail == newEmail {
+		return nil
+	}
+	if err := tx.Model(&models.Order{}).
+		Where("user_id = ? AND user_email = ?", userID, oldEmail).
+		Update("user_email", newEmail).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.TicketMessage{}).
+		Where("sender_type = ? AND sender_id = ? AND sender_name = ?", "user", userID, oldEmail).
+		Update("sender_name", newEmail).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.OrderMessage{}).
+		Where("sender_type = ? AND sender_id = ? AND sender_name = ?", OrderMessageSenderUser, userID, oldEmail).
+		Update("sender_name", newEmail).Error
+}
+
+// Apply 将用户邮箱改为已验证的新邮箱；原先有邮箱时生成撤销链接并通知旧邮箱
+func (s *EmailChangeService) Apply(user *models.User, newEmail, clientIP string) (*models.EmailChange, error) {
+	oldEmail := user.Email
+	now := models.NowFunc()
+
+	var change *models.EmailChange
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"email":            newEmail,
+			"email_verified":   true,
+			"email_changed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		if err := SyncUserEmailReferences(tx, user.ID, oldEmail, newEmail); err != nil {
+			return err
+		}
+		if oldEmail == "" {
+			return nil
+		}
+
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		change = &models.EmailChange{
+			UserID:      user.ID,
+			OldEmail:    oldEmail,
+			NewEmail:    newEmail,
+			RevertToken: hex.EncodeToString(b),
+			ExpiresAt:   now.Add(EmailChangeRevertTTL),
+			IPAddress:   clientIP,
+		}
+		return tx.Create(change).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.Email = newEmail
+	user.EmailVerified = true
+	user.EmailChangedAt = &now
+	if change != nil && s.emailService != nil {
+		go s.emailService.SendEmailChangedEmail(change, user.Name, user.Locale)
+	}
+	return change, nil
+}
+
+// Revert 凭旧邮箱收到的链接恢复邮箱；该记录之后的变更一并作废，防止被连续修改后无法找回
+func (s *EmailChangeService) Revert(token string) (*models.EmailChange, error) {
+	if token == "" {
+		return nil, authbiz.EmailChangeLinkInvalid()
+	}
+	now := models.NowFunc()
+
+	var change models.EmailChange
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("revert_token = ?", token).First(&change).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return authbiz.EmailChangeLinkInvalid()
+			}
+			return err
+		}
+		if !change.CanRevert(now) {
+			return authbiz.EmailChangeLinkInvalid()
+		}
+
+		var user models.User
+		if err := tx.First(&user, change.UserID).Error; err != nil {
+			return normalizeAuthLookupError(err)
+		}
+		var taken int64
+		if err := tx.Model(&models.User{}).
+			Where("email = ? AND id <> ?", change.OldEmail, user.ID).
+			Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return authbiz.EmailAlreadyInUse()
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"email":            change.OldEmail,
+			"email_verified":   true,
+			"email_changed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		if err := SyncUserEmailReferences(tx, user.ID, user.Email, change.OldEmail); err != nil {
+			return err
+		}
+		res := tx.Model(&models.EmailChange{}).
+			Where("user_id = ? AND id >= ? AND reverted_at IS NULL", user.ID, change.ID).
+			Update("reverted_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return authbiz.EmailChangeLinkInvalid()
+		}
+		change.RevertedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+func TestEmailChangeApplyAndRevert(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Order{}, &models.OrderMessage{}, &models.TicketMessage{}, &models.EmailChange{})
+	svc := NewEmailChangeService(db, nil)
+
+	user := &models.User{UUID: "email-change-user", Email: "old@example.com", Role: "user", IsActive: true, EmailVerified: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user failed: %v", err)
+	}
+	order := &models.Order{OrderNo: "EMAIL-CHANGE-1", UserID: &user.ID, UserEmail: "old@example.com", Status: models.OrderStatusPending}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+	message := &models.OrderMessage{OrderID: order.ID, SenderType: OrderMessageSenderUser, SenderID: user.ID, SenderName: "old@example.com", Content: "hi"}
+	if err := db.Create(message).Error; err != nil {
+		t.Fatalf("create message failed: %v", err)
+	}
+
+	first, err := svc.Apply(user, "new@example.com", "127.0.0.1")
+	if err != nil || first == nil || first.OldEmail != "old@example.com" || first.RevertToken == "" {
+		t.Fatalf("apply change: %+v, %v", first, err)
+	}
+	if first.ExpiresAt.Sub(first.CreatedAt) < EmailChangeRevertTTL-time.Minute {
+		t.Fatalf("expected revert link to be valid for 7 days, got %v", first.ExpiresAt.Sub(first.CreatedAt))
+	}
+	var reloaded models.Order
+	db.First(&reloaded, order.ID)
+	if reloaded.UserEmail != "new@example.com" {
+		t.Fatalf("expected order email to follow the account, got %q", reloaded.UserEmail)
+	}
+
+	// 连续修改后，最早的撤销链接仍可恢复原邮箱并作废之后的链接
+	second, err := svc.Apply(user, "third@example.com", "127.0.0.1")
+	if err != nil || second == nil {
+		t.Fatalf("apply second change: %+v, %v", second, err)
+	}
+	if _, err := svc.Revert(first.RevertToken); err != nil {
+		t.Fatalf("revert failed: %v", err)
+	}
+	var restored models.User
+	db.First(&restored, user.ID)
+	if restored.Email != "old@example.com" || !restored.EmailVerified {
+		t.Fatalf("expected original email restored, got %+v", restored)
+	}
+	db.First(&reloaded, order.ID)
+	var reloadedMessage models.OrderMessage
+	db.First(&reloadedMessage, message.ID)
+	if reloaded.UserEmail != "old@example.com" || reloadedMessage.SenderName != "old@example.com" {
+		t.Fatalf("expected references restored, got order=%q message=%q", reloaded.UserEmail, reloadedMessage.SenderName)
+	}
+
+	for _, token := range []string{first.RevertToken, second.RevertToken, "missing"} {
+		_, err := svc.Revert(token)
+		requireAuthBizErr(t, err, "auth.emailChangeLinkInvalid")
+	}
+
+	expired, err := svc.Apply(&restored, "later@example.com", "")
+	if err != nil {
+		t.Fatalf("apply change failed: %v", err)
+	}
+	db.Model(&models.EmailChange{}).Where("id = ?", expired.ID).Update("expires_at", time.Now().Add(-time.Minute))
+	_, err = svc.Revert(expired.RevertToken)
+	requireAuthBizErr(t, err, "auth.emailChangeLinkInvalid")
+}
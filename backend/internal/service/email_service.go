@@ -16,6 +16,7 @@ import (
 	"auralogic/internal/config"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/cache"
+	"auralogic/internal/pkg/demomode"
 	"auralogic/internal/pkg/money"
 	"github.com/go-redis/redis/v8"
 	"gopkg.in/gomail.v2"
@@ -28,6 +29,7 @@ type EmailService struct {
 	appURL              string
 	dialer              *gomail.Dialer
 	pluginManager       *PluginManagerService
+	themeService        *ThemeService
 	mu                  sync.RWMutex
 	workerMu            sync.Mutex
 	workersRunning      bool
@@ -100,6 +102,13 @@ func (s *EmailService) SetPluginManager(pluginManager *PluginManagerService) {
 	s.pluginManager = pluginManager
 }
 
+// SetThemeService 注入主题服务，用于在邮件中应用主题页眉/页脚
+func (s *EmailService) SetThemeService(themeService *ThemeService) {
+	s.mu.Lock()
+	s.themeService = themeService
+	s.mu.Unlock()
+}
+
 func (s *EmailService) IsEnabled() bool {
 	if s == nil {
 		return false
@@ -362,6 +371,7 @@ func (s *EmailService) renderTemplate(event, locale string, data interface{}) (s
 		// 回退到 en
 		tmpl, ok = s.templates[event+"_en"]
 	}
+	themeService := s.themeService
 	s.mu.RUnlock()
 	if !ok {
 		return "", fmt.Errorf("template %s not found", key)
@@ -372,9 +382,37 @@ func (s *EmailService) renderTemplate(event, locale string, data interface{}) (s
 		return "", fmt.Errorf("failed to render template: %w", err)
 	}
 
+	if branding, ok := themeService.ActiveEmailBranding(); ok {
+		return s.applyThemeEmailBranding(buf.String(), branding), nil
+	}
 	return buf.String(), nil
 }
 
+// applyThemeEmailBranding 渲染主题页眉/页脚（支持 AppName/AppURL/LogoURL/AccentColor 变量）并插入邮件正文
+func (s *EmailService) applyThemeEmailBranding(content string, branding models.ThemeEmailBranding) string {
+	data := map[string]interface{}{
+		"AppName":     getAppName(),
+		"AppURL":      s.appURL,
+		"LogoURL":     branding.LogoURL,
+		"AccentColor": branding.AccentColor,
+	}
+	render := func(fragment string) string {
+		if fragment == "" {
+			return ""
+		}
+		tmpl, err := template.New("email_branding").Parse(fragment)
+		if err != nil {
+			return fragment
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fragment
+		}
+		return buf.String()
+	}
+	return applyEmailBranding(content, render(branding.HeaderHTML), render(branding.FooterHTML))
+}
+
 // getEmailNotifyConfig 获取邮件通知配置
 func getEmailNotifyConfig() *config.EmailNotificationsConfig {
 	cfg := config.GetConfig()
@@ -442,7 +480,7 @@ func (s *EmailService) SendMarketingAnnouncementEmailWithBatch(user *models.User
 	}
 
 	userID := user.ID
-	return s.queueEmail(user.Email, subject, content, "marketing.announcement", nil, &userID, batchID)
+	return s.queueEmail(user.Email, subject, content, "marketing.announcement", nil, &userID, batchID, false)
 }
 
 // SendEmail 发送邮件
@@ -461,6 +499,10 @@ func (s *EmailService) SendEmail(to, subject, content string) error {
 		log.Printf("Email service is disabled, skipping email to %s", to)
 		return nil
 	}
+	if demomode.Enabled() {
+		log.Printf("Demo mode is enabled, skipping email to %s", to)
+		return nil
+	}
 
 	m := gomail.NewMessage()
 	m.SetHeader("From", fromEmail)
@@ -579,19 +621,21 @@ func (s *EmailService) emitEmailSendAfterHook(emailLog *models.EmailLog) {
 
 // QueueEmail 将邮件加入队列
 func (s *EmailService) QueueEmail(to, subject, content, eventType string, orderID, userID *uint) error {
-	return s.queueEmail(to, subject, content, eventType, orderID, userID, nil)
+	return s.queueEmail(to, subject, content, eventType, orderID, userID, nil, false)
 }
 
-func (s *EmailService) queueEmail(to, subject, content, eventType string, orderID, userID, batchID *uint) error {
+// QueueSensitiveEmail 正文含卡密等敏感内容的邮件，发送成功后不保留正文
+func (s *EmailService) QueueSensitiveEmail(to, subject, content, eventType string, orderID, userID *uint) error {
+	return s.queueEmail(to, subject, content, eventType, orderID, userID, nil, true)
+}
+
+func (s *EmailService) queueEmail(to, subject, content, eventType string, orderID, userID, batchID *uint, sensitive bool) error {
 	if !s.IsEnabled() {
 		return nil
 	}
 	if s.db == nil {
 		return fmt.Errorf("email log database is not initialized")
 	}
-	if cache.RedisClient == nil {
-		return fmt.Errorf("email queue redis client is not initialized")
-	}
 
 	rl := config.GetConfig().EmailRateLimit
 
@@ -615,10 +659,18 @@ func (s *EmailService) queueEmail(to, subject, content, eventType string, orderI
 				BatchID:   batchID,
 				Status:    models.EmailLogStatusPending,
 				ExpireAt:  &expireAt,
+				Sensitive: sensitive,
+			}
+			if cache.RedisClient == nil {
+				// 无 Redis 时由数据库轮询在 AvailableAt 之后重新检查限额
+				emailLog.AvailableAt = &availableAt
 			}
 			if err := s.db.Create(emailLog).Error; err != nil {
 				return err
 			}
+			if cache.RedisClient == nil {
+				return nil
+			}
 			ctx := cache.RedisClient.Context()
 			cache.RedisClient.ZAdd(ctx, "email:delayed", &redis.Z{
 				Score:  float64(availableAt.Unix()),
@@ -642,13 +694,17 @@ func (s *EmailService) queueEmail(to, subject, content, eventType string, orderI
 		BatchID:   batchID,
 		Status:    models.EmailLogStatusPending,
 		ExpireAt:  &expireAt,
+		Sensitive: sensitive,
 	}
 
 	if err := s.db.Create(emailLog).Error; err != nil {
 		return err
 	}
 
-	// 将邮件ID加入Redis队列
+	// 将邮件ID加入Redis队列，未启用 Redis 时由数据库轮询发送
+	if cache.RedisClient == nil {
+		return nil
+	}
 	if err := cache.RedisClient.RPush(cache.RedisClient.Context(), "email:queue", emailLog.ID).Err(); err != nil {
 		log.Printf("Failed to queue email: %v", err)
 	}
@@ -715,10 +771,14 @@ func (s *EmailService) ProcessEmailQueue() {
 }
 
 func (s *EmailService) processEmailQueueLoop(stopChan <-chan struct{}) {
-	if !s.IsEnabled() || cache.RedisClient == nil {
+	if !s.IsEnabled() {
 		log.Println("Email service is disabled")
 		return
 	}
+	if cache.RedisClient == nil {
+		s.pollEmailQueueLoop(stopChan)
+		return
+	}
 
 	ctx := cache.RedisClient.Context()
 	for {
@@ -751,61 +811,131 @@ func (s *EmailService) processEmailQueueLoop(stopChan <-chan struct{}) {
 			log.Printf("Failed to find email log %s: %v", emailID, err)
 			continue
 		}
+		s.deliverQueuedEmail(&emailLog)
+	}
+}
 
-		// TTL检查：如果邮件已过期则跳过发送
-		if emailLog.ExpireAt != nil && time.Now().After(*emailLog.ExpireAt) {
-			emailLog.Status = models.EmailLogStatusExpired
-			if err := s.db.Save(&emailLog).Error; err != nil {
-				log.Printf("Failed to update expired email log %s: %v", emailID, err)
-				continue
-			}
-			s.emitEmailSendAfterHook(&emailLog)
-			s.syncMarketingTaskStatus(&emailLog)
-			continue
+// pollEmailQueueLoop 未启用 Redis 时轮询数据库中待发送的邮件
+func (s *EmailService) pollEmailQueueLoop(stopChan <-chan struct{}) {
+	for {
+		emails, err := s.loadPollableEmails(time.Now(), emailQueuePollBatchSize)
+		if err != nil {
+			log.Printf("Failed to poll email queue: %v", err)
 		}
-
-		if err := s.applyEmailSendBeforeHook(&emailLog); err != nil {
-			emailLog.Status = models.EmailLogStatusFailed
-			emailLog.ErrorMessage = err.Error()
-			if isHookBlockedError(err) {
-				emailLog.RetryCount = 3
-			} else {
-				emailLog.RetryCount++
+		for i := range emails {
+			select {
+			case <-stopChan:
+				return
+			default:
 			}
-			if saveErr := s.db.Save(&emailLog).Error; saveErr != nil {
-				log.Printf("Failed to save blocked email log %s: %v", emailID, saveErr)
+			if !s.reserveDelayedEmailSlot(&emails[i]) {
 				continue
 			}
-			s.emitEmailSendAfterHook(&emailLog)
-			s.syncMarketingTaskStatus(&emailLog)
-			continue
+			s.deliverQueuedEmail(&emails[i])
+		}
+		// 本批未取满时等待下一轮，否则立即继续
+		if len(emails) < emailQueuePollBatchSize && waitBackgroundServiceStopChan(stopChan, emailQueuePollInterval) {
+			return
 		}
+	}
+}
 
-		// 发送邮件
-		if err := s.SendEmail(emailLog.ToEmail, emailLog.Subject, emailLog.Content); err != nil {
-			// 发送失败
-			emailLog.Status = models.EmailLogStatusFailed
-			emailLog.ErrorMessage = err.Error()
-			emailLog.RetryCount++
+const (
+	emailQueuePollInterval  = 5 * time.Second
+	emailQueuePollBatchSize = 50
+)
 
-			// 如果重试次数小于3，重新加入队列
-			if emailLog.RetryCount < 3 {
-				cache.RedisClient.RPush(ctx, "email:queue", emailLog.ID)
-			}
+// loadPollableEmails 待发送或可重试、且已到可发送时间的邮件
+func (s *EmailService) loadPollableEmails(now time.Time, limit int) ([]models.EmailLog, error) {
+	var emails []models.EmailLog
+	err := s.db.
+		Where("status = ? OR (status = ? AND retry_count < ?)", models.EmailLogStatusPending, models.EmailLogStatusFailed, 3).
+		Where("available_at IS NULL OR available_at <= ?", now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&emails).Error
+	return emails, err
+}
+
+// reserveDelayedEmailSlot 被限流延迟的邮件到期后重新占用限额，仍超限时顺延 AvailableAt
+func (s *EmailService) reserveDelayedEmailSlot(emailLog *models.EmailLog) bool {
+	if emailLog.AvailableAt == nil {
+		return true
+	}
+	allowed, availableAt, err := reserveMessageRateLimitSlot("email", emailLog.ToEmail, config.GetConfig().EmailRateLimit)
+	if err != nil {
+		log.Printf("Warning: delayed email rate limit reservation failed for email=%s id=%d: %v", emailLog.ToEmail, emailLog.ID, err)
+		allowed = true
+	}
+	next := &availableAt
+	if allowed {
+		next = nil
+	}
+	if err := s.db.Model(emailLog).Update("available_at", next).Error; err != nil {
+		log.Printf("Failed to update email log %d available_at: %v", emailLog.ID, err)
+		return false
+	}
+	return allowed
+}
+
+// deliverQueuedEmail 发送一封队列中的邮件并更新记录
+func (s *EmailService) deliverQueuedEmail(emailLog *models.EmailLog) {
+	// TTL检查：如果邮件已过期则跳过发送
+	if emailLog.ExpireAt != nil && time.Now().After(*emailLog.ExpireAt) {
+		emailLog.Status = models.EmailLogStatusExpired
+		if err := s.db.Save(emailLog).Error; err != nil {
+			log.Printf("Failed to update expired email log %d: %v", emailLog.ID, err)
+			return
+		}
+		s.emitEmailSendAfterHook(emailLog)
+		s.syncMarketingTaskStatus(emailLog)
+		return
+	}
+
+	if err := s.applyEmailSendBeforeHook(emailLog); err != nil {
+		emailLog.Status = models.EmailLogStatusFailed
+		emailLog.ErrorMessage = err.Error()
+		if isHookBlockedError(err) {
+			emailLog.RetryCount = 3
 		} else {
-			// 发送成功
-			emailLog.Status = models.EmailLogStatusSent
-			now := models.NowFunc()
-			emailLog.SentAt = &now
+			emailLog.RetryCount++
+		}
+		if saveErr := s.db.Save(emailLog).Error; saveErr != nil {
+			log.Printf("Failed to save blocked email log %d: %v", emailLog.ID, saveErr)
+			return
 		}
+		s.emitEmailSendAfterHook(emailLog)
+		s.syncMarketingTaskStatus(emailLog)
+		return
+	}
 
-		if err := s.db.Save(&emailLog).Error; err != nil {
-			log.Printf("Failed to save email log %s: %v", emailID, err)
-			continue
+	// 发送邮件
+	if err := s.SendEmail(emailLog.ToEmail, emailLog.Subject, emailLog.Content); err != nil {
+		// 发送失败
+		emailLog.Status = models.EmailLogStatusFailed
+		emailLog.ErrorMessage = err.Error()
+		emailLog.RetryCount++
+
+		// 如果重试次数小于3，重新加入队列（数据库轮询模式下按状态自动重试）
+		if emailLog.RetryCount < 3 && cache.RedisClient != nil {
+			cache.RedisClient.RPush(cache.RedisClient.Context(), "email:queue", emailLog.ID)
 		}
-		s.emitEmailSendAfterHook(&emailLog)
-		s.syncMarketingTaskStatus(&emailLog)
+	} else {
+		// 发送成功
+		emailLog.Status = models.EmailLogStatusSent
+		now := models.NowFunc()
+		emailLog.SentAt = &now
+		if emailLog.Sensitive {
+			emailLog.Content = ""
+		}
+	}
+
+	if err := s.db.Save(emailLog).Error; err != nil {
+		log.Printf("Failed to save email log %d: %v", emailLog.ID, err)
+		return
 	}
+	s.emitEmailSendAfterHook(emailLog)
+	s.syncMarketingTaskStatus(emailLog)
 }
 
 func (s *EmailService) syncMarketingTaskStatus(emailLog *models.EmailLog) {
@@ -1152,10 +1282,73 @@ func (s *EmailService) SendPasswordResetEmail(email, token, locale string) error
 	return s.QueueEmail(email, subject, content, "user.password_reset", nil, nil)
 }
 
+// SendEmailChangedEmail 通知旧邮箱账户邮箱已更改，附带撤销链接
+func (s *EmailService) SendEmailChangedEmail(change *models.EmailChange, name, locale string) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+
+	appName := getAppName()
+	locale = resolveLocale(locale)
+
+	var subject string
+	if locale == "zh" {
+		subject = fmt.Sprintf("账户邮箱已更改 - %s", appName)
+	} else {
+		subject = fmt.Sprintf("Your email address was changed - %s", appName)
+	}
+
+	revertURL := fmt.Sprintf("%s/revert-email-change?token=%s", s.appURL, change.RevertToken)
+	data := map[string]interface{}{
+		"Name":      name,
+		"OldEmail":  change.OldEmail,
+		"NewEmail":  change.NewEmail,
+		"ChangedAt": change.CreatedAt.Format("2006-01-02 15:04:05"),
+		"RevertURL": revertURL,
+		"AppName":   appName,
+		"AppURL":    s.appURL,
+	}
+
+	content, err := s.renderTemplate("email_changed", locale, data)
+	if err != nil {
+		if locale == "zh" {
+			content = fmt.Sprintf("<h2>账户邮箱已更改</h2><p>您的账户邮箱已从 %s 更改为 %s。</p><p>如果不是您本人操作，请点击以下链接恢复原邮箱：</p><p><a href=\"%s\">恢复邮箱</a></p><p>此链接 7 天内有效。</p>",
+				template.HTMLEscapeString(change.OldEmail), template.HTMLEscapeString(change.NewEmail), revertURL)
+		} else {
+			content = fmt.Sprintf("<h2>Email Address Changed</h2><p>Your account email was changed from %s to %s.</p><p>If you did not make this change, restore your email with the link below:</p><p><a href=\"%s\">Restore Email</a></p><p>This link expires in 7 days.</p>",
+				template.HTMLEscapeString(change.OldEmail), template.HTMLEscapeString(change.NewEmail), revertURL)
+		}
+	}
+
+	return s.QueueEmail(change.OldEmail, subject, content, "user.email_changed", nil, &change.UserID)
+}
+
 // ========================
 // 订单相关
 // ========================
 
+// buildOrderEmailItems 邮件模板中的订单项（金额取下单时的快照，旧订单不含金额）
+func buildOrderEmailItems(order *models.Order) []map[string]interface{} {
+	hasItemPricing := order.HasItemPricing()
+	items := make([]map[string]interface{}, 0, len(order.Items))
+	for _, item := range order.Items {
+		row := map[string]interface{}{
+			"Name":     item.Name,
+			"SKU":      item.SKU,
+			"Quantity": item.Quantity,
+		}
+		if hasItemPricing {
+			row["UnitPrice"] = money.MinorToString(item.UnitPriceMinor)
+			row["LineTotal"] = money.MinorToString(item.LineTotalMinor)
+			if item.DiscountMinor != 0 {
+				row["Discount"] = money.MinorToString(item.DiscountMinor)
+			}
+		}
+		items = append(items, row)
+	}
+	return items
+}
+
 // SendOrderCreatedEmail 发送订单创建成功邮件
 func (s *EmailService) SendOrderCreatedEmail(order *models.Order) error {
 	if !getEmailNotifyConfig().OrderCreated {
@@ -1176,10 +1369,13 @@ func (s *EmailService) SendOrderCreatedEmail(order *models.Order) error {
 	}
 
 	data := map[string]interface{}{
-		"OrderNo":   order.OrderNo,
-		"CreatedAt": order.CreatedAt.Format("2006-01-02 15:04:05"),
-		"AppURL":    s.appURL,
-		"AppName":   appName,
+		"OrderNo":     order.OrderNo,
+		"CreatedAt":   order.CreatedAt.Format("2006-01-02 15:04:05"),
+		"Items":       buildOrderEmailItems(order),
+		"TotalAmount": money.MinorToString(order.TotalAmount),
+		"Currency":    order.Currency,
+		"AppURL":      s.appURL,
+		"AppName":     appName,
 	}
 
 	content, err := s.renderTemplate("order_created", locale, data)
@@ -1220,11 +1416,13 @@ func (s *EmailService) SendOrderPaidEmail(order *models.Order, isVirtualOnly boo
 		"OrderNo":       order.OrderNo,
 		"TotalAmount":   money.MinorToString(order.TotalAmount),
 		"Currency":      order.Currency,
+		"Items":         buildOrderEmailItems(order),
 		"IsVirtualOnly": isVirtualOnly,
 		"AppURL":        s.appURL,
 		"AppName":       appName,
 		"PaidAt":        models.NowFunc().Format("2006-01-02 15:04:05"),
 	}
+	sensitive := s.applyVirtualDeliveryEmailData(order, data)
 
 	content, err := s.renderTemplate("order_paid", locale, data)
 	if err != nil {
@@ -1244,6 +1442,9 @@ func (s *EmailService) SendOrderPaidEmail(order *models.Order, isVirtualOnly boo
 		}
 	}
 
+	if sensitive {
+		return s.QueueSensitiveEmail(order.UserEmail, subject, content, "order.paid", &order.ID, order.UserID)
+	}
 	return s.QueueEmail(order.UserEmail, subject, content, "order.paid", &order.ID, order.UserID)
 }
 
@@ -1279,6 +1480,7 @@ func (s *EmailService) SendOrderShippedEmail(order *models.Order) error {
 		"AppURL":       s.appURL,
 		"AppName":      appName,
 	}
+	sensitive := s.applyVirtualDeliveryEmailData(order, data)
 
 	content, err := s.renderTemplate("order_shipped", locale, data)
 	if err != nil {
@@ -1292,6 +1494,9 @@ func (s *EmailService) SendOrderShippedEmail(order *models.Order) error {
 		}
 	}
 
+	if sensitive {
+		return s.QueueSensitiveEmail(order.UserEmail, subject, content, "order.shipped", &order.ID, order.UserID)
+	}
 	return s.QueueEmail(order.UserEmail, subject, content, "order.shipped", &order.ID, order.UserID)
 }
 
@@ -1340,8 +1545,8 @@ func (s *EmailService) SendOrderCompletedEmail(order *models.Order) error {
 	return s.QueueEmail(order.UserEmail, subject, content, "order.completed", &order.ID, order.UserID)
 }
 
-// SendOrderResubmitEmail 发送需要重填信息邮件
-func (s *EmailService) SendOrderResubmitEmail(order *models.Order, formURL string) error {
+// SendOrderResubmitEmail 发送需要重填信息邮件，reason 会展示给用户
+func (s *EmailService) SendOrderResubmitEmail(order *models.Order, formURL, reason string) error {
 	if !getEmailNotifyConfig().OrderResubmit {
 		return nil
 	}
@@ -1361,7 +1566,7 @@ func (s *EmailService) SendOrderResubmitEmail(order *models.Order, formURL strin
 
 	data := map[string]interface{}{
 		"OrderNo": order.OrderNo,
-		"Reason":  order.AdminRemark,
+		"Reason":  reason,
 		"FormURL": formURL,
 		"AppURL":  s.appURL,
 		"AppName": appName,
@@ -1371,17 +1576,17 @@ func (s *EmailService) SendOrderResubmitEmail(order *models.Order, formURL strin
 	if err != nil {
 		log.Printf("Failed to render template, using fallback: %v", err)
 		if locale == "zh" {
-			content = fmt.Sprintf("订单信息需要更正\n\n订单号: %s\n原因: %s\n\n重新填写: %s", order.OrderNo, order.AdminRemark, formURL)
+			content = fmt.Sprintf("订单信息需要更正\n\n订单号: %s\n原因: %s\n\n重新填写: %s", order.OrderNo, reason, formURL)
 		} else {
-			content = fmt.Sprintf("Order Information Needs Correction\n\nOrder No: %s\nReason: %s\n\nResubmit: %s", order.OrderNo, order.AdminRemark, formURL)
+			content = fmt.Sprintf("Order Information Needs Correction\n\nOrder No: %s\nReason: %s\n\nResubmit: %s", order.OrderNo, reason, formURL)
 		}
 	}
 
 	return s.QueueEmail(order.UserEmail, subject, content, "order.need_resubmit", &order.ID, order.UserID)
 }
 
-// SendOrderCancelledEmail 发送订单取消邮件
-func (s *EmailService) SendOrderCancelledEmail(order *models.Order) error {
+// SendOrderCancelledEmail 发送订单取消邮件，reason 会展示给用户
+func (s *EmailService) SendOrderCancelledEmail(order *models.Order, reason string) error {
 	if !getEmailNotifyConfig().OrderCancelled {
 		return nil
 	}
@@ -1402,7 +1607,7 @@ func (s *EmailService) SendOrderCancelledEmail(order *models.Order) error {
 	data := map[string]interface{}{
 		"OrderNo":     order.OrderNo,
 		"CancelledAt": models.NowFunc().Format("2006-01-02 15:04:05"),
-		"Reason":      order.AdminRemark,
+		"Reason":      reason,
 		"AppURL":      s.appURL,
 		"AppName":     appName,
 	}
@@ -1412,16 +1617,325 @@ func (s *EmailService) SendOrderCancelledEmail(order *models.Order) error {
 		log.Printf("Failed to render template, using fallback: %v", err)
 		if locale == "zh" {
 			content = fmt.Sprintf("订单已取消\n\n订单号: %s\n取消时间: %s\n原因: %s\n\n如有疑问请联系客服。",
-				order.OrderNo, data["CancelledAt"], order.AdminRemark)
+				order.OrderNo, data["CancelledAt"], reason)
 		} else {
 			content = fmt.Sprintf("Order Cancelled\n\nOrder No: %s\nCancelled At: %s\nReason: %s\n\nPlease contact support if you have questions.",
-				order.OrderNo, data["CancelledAt"], order.AdminRemark)
+				order.OrderNo, data["CancelledAt"], reason)
 		}
 	}
 
 	return s.QueueEmail(order.UserEmail, subject, content, "order.cancelled", &order.ID, order.UserID)
 }
 
+// SendOrderPaymentReminderEmail 待付款订单即将自动取消时提醒用户付款
+func (s *EmailService) SendOrderPaymentReminderEmail(order *models.Order, deadline time.Time, paymentURL string) error {
+	if !s.canSendOrderEmail(order) {
+		return nil
+	}
+
+	locale := s.getOrderLocale(order)
+	appName := getAppName()
+	if paymentURL == "" {
+		paymentURL = fmt.Sprintf("%s/orders/%s", s.appURL, order.OrderNo)
+	}
+
+	var subject string
+	if locale == "zh" {
+		subject = fmt.Sprintf("订单即将因未付款取消 - %s", order.OrderNo)
+	} else {
+		subject = fmt.Sprintf("Payment Reminder - %s", order.OrderNo)
+	}
+
+	data := map[string]interface{}{
+		"OrderNo":     order.OrderNo,
+		"Items":       buildOrderEmailItems(order),
+		"TotalAmount": money.MinorToString(order.TotalAmount),
+		"Currency":    order.Currency,
+		"DeadlineAt":  deadline.Format("2006-01-02 15:04:05"),
+		"PaymentURL":  paymentURL,
+		"AppURL":      s.appURL,
+		"AppName":     appName,
+	}
+
+	content, err := s.renderTemplate("order_payment_reminder", locale, data)
+	if err != nil {
+		log.Printf("Failed to render order_payment_reminder template, using fallback: %v", err)
+		if locale == "zh" {
+			content = fmt.Sprintf("订单待付款提醒\n\n订单号: %s\n应付金额: %s %s\n请在 %s 前完成付款，逾期订单将自动取消。\n\n去付款: %s",
+				order.OrderNo, data["TotalAmount"], order.Currency, data["DeadlineAt"], paymentURL)
+		} else {
+			content = fmt.Sprintf("Payment Reminder\n\nOrder No: %s\nAmount due: %s %s\nPlease pay before %s, otherwise the order will be cancelled automatically.\n\nPay now: %s",
+				order.OrderNo, data["TotalAmount"], order.Currency, data["DeadlineAt"], paymentURL)
+		}
+	}
+
+	return s.QueueEmail(order.UserEmail, subject, content, "order.payment_reminder", &order.ID, order.UserID)
+}
+
+// SendOrderSubStatusEmail 订单进入自定义子状态时通知用户（仅子状态开启 notify_customer 时调用）
+func (s *EmailService) SendOrderSubStatusEmail(order *models.Order, subStatus *models.OrderSubStatus, note string) error {
+	if subStatus == nil || !s.canSendOrderEmail(order) {
+		return nil
+	}
+
+	locale := s.getOrderLocale(order)
+	appName := getAppName()
+
+	var subject string
+	if locale == "zh" {
+		subject = fmt.Sprintf("订单状态更新：%s - %s", subStatus.Name, order.OrderNo)
+	} else {
+		subject = fmt.Sprintf("Order Update: %s - %s", subStatus.Name, order.OrderNo)
+	}
+
+	data := map[string]interface{}{
+		"OrderNo":              order.OrderNo,
+		"Status":               string(order.Status),
+		"SubStatus":            subStatus.Code,
+		"SubStatusName":        subStatus.Name,
+		"SubStatusDescription": subStatus.Description,
+		"Note":                 note,
+		"UpdatedAt":            models.NowFunc().Format("2006-01-02 15:04:05"),
+		"AppURL":               s.appURL,
+		"AppName":              appName,
+	}
+
+	content, err := s.renderTemplate("order_sub_status", locale, data)
+	if err != nil {
+		log.Printf("Failed to render order_sub_status template, using fallback: %v", err)
+		if locale == "zh" {
+			content = fmt.Sprintf("订单状态更新\n\n订单号: %s\n当前状态: %s\n%s\n\n查看: %s/orders/%s",
+				order.OrderNo, subStatus.Name, note, s.appURL, order.OrderNo)
+		} else {
+			content = fmt.Sprintf("Order Update\n\nOrder No: %s\nCurrent status: %s\n%s\n\nView: %s/orders/%s",
+				order.OrderNo, subStatus.Name, note, s.appURL, order.OrderNo)
+		}
+	}
+
+	return s.QueueEmail(order.UserEmail, subject, content, "order.sub_status", &order.ID, order.UserID)
+}
+
+// SendVirtualStockRevokedEmail 已发货卡密被撤销时通知用户，reissued 表示已补发新的卡密
+func (s *EmailService) SendVirtualStockRevokedEmail(order *models.Order, reason string, reissued bool) error {
+	if !s.canSendOrderEmail(order) {
+		return nil
+	}
+
+	locale := s.getOrderLocale(order)
+	appName := getAppName()
+
+	var subject string
+	if locale == "zh" {
+		subject = fmt.Sprintf("虚拟商品已撤销 - %s", order.OrderNo)
+	} else {
+		subject = fmt.Sprintf("Digital Item Revoked - %s", order.OrderNo)
+	}
+
+	data := map[string]interface{}{
+		"OrderNo":   order.OrderNo,
+		"Reason":    reason,
+		"Reissued":  reissued,
+		"RevokedAt": models.NowFunc().Format("2006-01-02 15:04:05"),
+		"AppURL":    s.appURL,
+		"AppName":   appName,
+	}
+
+	content, err := s.renderTemplate("virtual_stock_revoked", locale, data)
+	if err != nil {
+		log.Printf("Failed to render virtual_stock_revoked template, using fallback: %v", err)
+		if locale == "zh" {
+			content = fmt.Sprintf("虚拟商品已撤销\n\n订单号: %s\n原因: %s\n\n查看: %s/orders/%s",
+				order.OrderNo, reason, s.appURL, order.OrderNo)
+		} else {
+			content = fmt.Sprintf("Digital Item Revoked\n\nOrder No: %s\nReason: %s\n\nView: %s/orders/%s",
+				order.OrderNo, reason, s.appURL, order.OrderNo)
+		}
+	}
+
+	return s.QueueEmail(order.UserEmail, subject, content, "order.virtual_stock_revoked", &order.ID, order.UserID)
+}
+
+// SendVirtualItemsEmail 买家主动重发的虚拟商品邮件，卡密按商品邮件交付方式附带
+func (s *EmailService) SendVirtualItemsEmail(order *models.Order, itemCount int) error {
+	locale := s.getOrderLocale(order)
+	appName := getAppName()
+
+	var subject string
+	if locale == "zh" {
+		subject = fmt.Sprintf("您的虚拟商品 - %s", order.OrderNo)
+	} else {
+		subject = fmt.Sprintf("Your Digital Items - %s", order.OrderNo)
+	}
+
+	data := map[string]interface{}{
+		"OrderNo":   order.OrderNo,
+		"ItemCount": itemCount,
+		"AppURL":    s.appURL,
+		"AppName":   appName,
+	}
+	sensitive := s.applyVirtualDeliveryEmailData(order, data)
+
+	content, err := s.renderTemplate("virtual_items", locale, data)
+	if err != nil {
+		log.Printf("Failed to render virtual_items template, using fallback: %v", err)
+		if locale == "zh" {
+			content = fmt.Sprintf("您的虚拟商品\n\n订单号: %s\n已发放 %d 件虚拟商品，请登录查看。\n\n查看: %s/orders/%s",
+				order.OrderNo, itemCount, s.appURL, order.OrderNo)
+		} else {
+			content = fmt.Sprintf("Your Digital Items\n\nOrder No: %s\n%d item(s) delivered. Please login to view.\n\nView: %s/orders/%s",
+				order.OrderNo, itemCount, s.appURL, order.OrderNo)
+		}
+	}
+
+	if sensitive {
+		return s.QueueSensitiveEmail(order.UserEmail, subject, content, "order.virtual_items_resent", &order.ID, order.UserID)
+	}
+	return s.QueueEmail(order.UserEmail, subject, content, "order.virtual_items_resent", &order.ID, order.UserID)
+}
+
+// SendOrderNoteMentionEmail 管理员在订单备注中被提及时发送通知
+func (s *EmailService) SendOrderNoteMentionEmail(order *models.Order, admin models.User, authorName, content string) error {
+	if admin.Email == "" {
+		return nil
+	}
+
+	locale := resolveLocale(admin.Locale)
+	appName := getAppName()
+	orderURL := fmt.Sprintf("%s/admin/orders/%d", s.appURL, order.ID)
+
+	var subject string
+	if locale == "zh" {
+		subject = fmt.Sprintf("[订单备注] %s 在订单 %s 中提到了你", authorName, order.OrderNo)
+	} else {
+		subject = fmt.Sprintf("[Order Note] %s mentioned you on order %s", authorName, order.OrderNo)
+	}
+
+	data := map[string]interface{}{
+		"OrderNo":    order.OrderNo,
+		"AuthorName": authorName,
+		"Content":    content,
+		"OrderURL":   orderURL,
+		"AppURL":     s.appURL,
+		"AppName":    appName,
+	}
+
+	body, err := s.renderTemplate("order_note_mention", locale, data)
+	if err != nil {
+		log.Printf("Failed to render order_note_mention template, using fallback: %v", err)
+		if locale == "zh" {
+			body = fmt.Sprintf("%s 在订单备注中提到了你\n\n订单号: %s\n备注: %s\n\n查看: %s", authorName, order.OrderNo, content, orderURL)
+		} else {
+			body = fmt.Sprintf("%s mentioned you in an order note\n\nOrder No: %s\nNote: %s\n\nView: %s", authorName, order.OrderNo, content, orderURL)
+		}
+	}
+
+	adminID := admin.ID
+	return s.QueueEmail(admin.Email, subject, body, "order.note_mention", &order.ID, &adminID)
+}
+
+// SendOrderMessageUserEmail 用户发送订单留言后通知管理员
+func (s *EmailService) SendOrderMessageUserEmail(order *models.Order, userName, messagePreview string) error {
+	if !getEmailNotifyConfig().OrderMessageUser {
+		return nil
+	}
+
+	// 防抖：同一订单5分钟内只发一次用户留言通知
+	debounceKey := fmt.Sprintf("order_message_notify:user:%d", order.ID)
+	if ok, err := cache.SetNX(debounceKey, 1, 5*time.Minute); err == nil && !ok {
+		return nil
+	}
+
+	appName := getAppName()
+	orderURL := fmt.Sprintf("%s/admin/orders/%d", s.appURL, order.ID)
+
+	for _, admin := range s.getAdminsWithTicketPermission() {
+		if admin.Email == "" || !admin.EmailNotifyTicket {
+			continue
+		}
+
+		locale := resolveLocale(admin.Locale)
+		var subject string
+		if locale == "zh" {
+			subject = fmt.Sprintf("[订单留言] %s - %s", order.OrderNo, userName)
+		} else {
+			subject = fmt.Sprintf("[Order Message] %s - %s", order.OrderNo, userName)
+		}
+
+		data := map[string]interface{}{
+			"OrderNo":        order.OrderNo,
+			"SenderName":     userName,
+			"MessagePreview": messagePreview,
+			"ViewURL":        orderURL,
+			"AppURL":         s.appURL,
+			"AppName":        appName,
+		}
+
+		content, err := s.renderTemplate("order_message", locale, data)
+		if err != nil {
+			log.Printf("Failed to render order_message template, using fallback: %v", err)
+			if locale == "zh" {
+				content = fmt.Sprintf("订单有新的用户留言\n\n订单号: %s\n用户: %s\n\n消息:\n%s\n\n查看: %s",
+					order.OrderNo, userName, messagePreview, orderURL)
+			} else {
+				content = fmt.Sprintf("New customer message on order\n\nOrder No: %s\nCustomer: %s\n\nMessage:\n%s\n\nView: %s",
+					order.OrderNo, userName, messagePreview, orderURL)
+			}
+		}
+
+		adminID := admin.ID
+		s.QueueEmail(admin.Email, subject, content, "order.message_user", &order.ID, &adminID)
+	}
+
+	return nil
+}
+
+// SendOrderMessageAdminReplyEmail 卖家回复订单留言后通知用户
+func (s *EmailService) SendOrderMessageAdminReplyEmail(order *models.Order, adminName, messagePreview string) error {
+	if !getEmailNotifyConfig().OrderMessageAdmin || !s.canSendOrderEmail(order) {
+		return nil
+	}
+
+	// 防抖：同一订单5分钟内只发一次卖家回复通知
+	debounceKey := fmt.Sprintf("order_message_notify:admin:%d", order.ID)
+	if ok, err := cache.SetNX(debounceKey, 1, 5*time.Minute); err == nil && !ok {
+		return nil
+	}
+
+	locale := s.getOrderLocale(order)
+	appName := getAppName()
+	orderURL := fmt.Sprintf("%s/orders/%s", s.appURL, order.OrderNo)
+
+	var subject string
+	if locale == "zh" {
+		subject = fmt.Sprintf("[订单留言回复] %s", order.OrderNo)
+	} else {
+		subject = fmt.Sprintf("[Order Message Reply] %s", order.OrderNo)
+	}
+
+	data := map[string]interface{}{
+		"OrderNo":        order.OrderNo,
+		"SenderName":     adminName,
+		"MessagePreview": messagePreview,
+		"ViewURL":        orderURL,
+		"AppURL":         s.appURL,
+		"AppName":        appName,
+	}
+
+	content, err := s.renderTemplate("order_message", locale, data)
+	if err != nil {
+		log.Printf("Failed to render order_message template, using fallback: %v", err)
+		if locale == "zh" {
+			content = fmt.Sprintf("卖家回复了您的订单留言\n\n订单号: %s\n\n消息:\n%s\n\n查看: %s",
+				order.OrderNo, messagePreview, orderURL)
+		} else {
+			content = fmt.Sprintf("The seller replied to your order message\n\nOrder No: %s\n\nMessage:\n%s\n\nView: %s",
+				order.OrderNo, messagePreview, orderURL)
+		}
+	}
+
+	return s.QueueEmail(order.UserEmail, subject, content, "order.message_admin", &order.ID, order.UserID)
+}
+
 // ========================
 // 工单相关
 // ========================
@@ -0,0 +1,546 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/pkg/demomode"
+	"auralogic/internal/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	ExchangeRateProviderExchangeRateHost = "exchangerate_host"
+	ExchangeRateProviderCoinGecko        = "coingecko"
+
+	defaultExchangeRateCacheMinutes      = 10
+	defaultExchangeRateStaleAlertMinutes = 60
+	exchangeRateFetchTimeout             = 10 * time.Second
+	exchangeRateMaxResponseBytes         = 1 << 20
+	exchangeRateCheckInterval            = time.Minute
+)
+
+// exchangeRateCryptoIDs 支持的加密货币与 CoinGecko 资产 ID
+var exchangeRateCryptoIDs = map[string]string{
+	"USDT": "tether",
+	"USDC": "usd-coin",
+	"BTC":  "bitcoin",
+	"ETH":  "ethereum",
+	"TRX":  "tron",
+	"BNB":  "binancecoin",
+}
+
+// IsCryptoCurrency 是否为汇率服务支持的加密货币代码
+func IsCryptoCurrency(code string) bool {
+	_, ok := exchangeRateCryptoIDs[strings.ToUpper(strings.TrimSpace(code))]
+	return ok
+}
+
+// ExchangeRateProvider 汇率数据源，返回 1 单位 from 可兑换的 to 数量
+type ExchangeRateProvider interface {
+	Name() string
+	FetchRate(ctx context.Context, from, to string) (float64, error)
+}
+
+// ExchangeRateQuote 汇率报价
+type ExchangeRateQuote struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Rate      float64   `json:"rate"`
+	Provider  string    `json:"provider"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Stale     bool      `json:"stale"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+type exchangeRateCacheEntry struct {
+	rate      float64
+	provider  string
+	fetchedAt time.Time
+	lastError string
+	alerted   bool
+}
+
+// ExchangeRateService 汇率服务：按币种路由到法币/加密货币数据源，缓存结果并在刷新失败过久时告警
+type ExchangeRateService struct {
+	db        *gorm.DB
+	cfg       *config.Config
+	providers map[string]ExchangeRateProvider
+
+	mu    sync.Mutex
+	cache map[string]*exchangeRateCacheEntry
+
+	// now 可在测试中替换
+	now func() time.Time
+
+	lifecycleMu sync.Mutex
+	running     bool
+	stopChan    chan struct{}
+	doneChan    chan struct{}
+}
+
+func NewExchangeRateService(db *gorm.DB, cfg *config.Config) *ExchangeRateService {
+	client := &http.Client{Timeout: exchangeRateFetchTimeout, Transport: demomode.Transport(nil)}
+	return &ExchangeRateService{
+		db:  db,
+		cfg: cfg,
+		providers: map[string]ExchangeRateProvider{
+			ExchangeRateProviderExchangeRateHost: &exchangeRateHostProvider{
+				client:  client,
+				baseURL: "https://api.exchangerate.host",
+				apiKey:  func() string { return cfg.ExchangeRate.FiatAPIKey },
+			},
+			ExchangeRateProviderCoinGecko: &coinGeckoProvider{
+				client:  client,
+				baseURL: "https://api.coingecko.com/api/v3",
+				apiKey:  func() string { return cfg.ExchangeRate.CryptoAPIKey },
+			},
+		},
+		cache: make(map[string]*exchangeRateCacheEntry),
+		now:   time.Now,
+	}
+}
+
+// RegisterProvider 注册或替换汇率数据源
+func (s *ExchangeRateService) RegisterProvider(provider ExchangeRateProvider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providers[provider.Name()] = provider
+}
+
+// Enabled 是否启用汇率服务
+func (s *ExchangeRateService) Enabled() bool {
+	return s != nil && s.cfg != nil && s.cfg.ExchangeRate.Enabled
+}
+
+// DisplayCurrencies 订单详情额外展示的换算币种
+func (s *ExchangeRateService) DisplayCurrencies() []string {
+	if !s.Enabled() {
+		return nil
+	}
+	currencies := make([]string, 0, len(s.cfg.ExchangeRate.DisplayCurrencies))
+	seen := make(map[string]bool)
+	for _, code := range s.cfg.ExchangeRate.DisplayCurrencies {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		currencies = append(currencies, code)
+	}
+	return currencies
+}
+
+func (s *ExchangeRateService) cacheTTL() time.Duration {
+	minutes := s.cfg.ExchangeRate.CacheMinutes
+	if minutes <= 0 {
+		minutes = defaultExchangeRateCacheMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func (s *ExchangeRateService) staleAfter() time.Duration {
+	minutes := s.cfg.ExchangeRate.StaleAlertMinutes
+	if minutes <= 0 {
+		minutes = defaultExchangeRateStaleAlertMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// providerFor 涉及加密货币的币对使用加密货币数据源，其余使用法币数据源
+func (s *ExchangeRateService) providerFor(from, to string) (ExchangeRateProvider, error) {
+	name := s.cfg.ExchangeRate.FiatProvider
+	if name == "" {
+		name = ExchangeRateProviderExchangeRateHost
+	}
+	if IsCryptoCurrency(from) || IsCryptoCurrency(to) {
+		name = s.cfg.ExchangeRate.CryptoProvider
+		if name == "" {
+			name = ExchangeRateProviderCoinGecko
+		}
+	}
+	s.mu.Lock()
+	provider, ok := s.providers[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange rate provider %q", name)
+	}
+	return provider, nil
+}
+
+func exchangeRatePairKey(from, to string) string {
+	return from + "/" + to
+}
+
+func normalizeCurrencyCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) < 3 || len(code) > 5 {
+		return "", fmt.Errorf("invalid currency code %q", code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency code %q", code)
+		}
+	}
+	return code, nil
+}
+
+// GetRate 获取汇率：缓存未过期直接返回；刷新失败时回退到上次成功的汇率并标记 stale
+func (s *ExchangeRateService) GetRate(ctx context.Context, from, to string) (*ExchangeRateQuote, error) {
+	if !s.Enabled() {
+		return nil, fmt.Errorf("exchange rate service is disabled")
+	}
+	from, err := normalizeCurrencyCode(from)
+	if err != nil {
+		return nil, err
+	}
+	to, err = normalizeCurrencyCode(to)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if from == to {
+		return &ExchangeRateQuote{From: from, To: to, Rate: 1, Provider: "identity", UpdatedAt: now}, nil
+	}
+
+	key := exchangeRatePairKey(from, to)
+	s.mu.Lock()
+	entry := s.cache[key]
+	if entry != nil && now.Sub(entry.fetchedAt) < s.cacheTTL() {
+		quote := s.quoteLocked(from, to, entry, now)
+		s.mu.Unlock()
+		return quote, nil
+	}
+	s.mu.Unlock()
+
+	return s.refresh(ctx, from, to)
+}
+
+func (s *ExchangeRateService) refresh(ctx context.Context, from, to string) (*ExchangeRateQuote, error) {
+	provider, err := s.providerFor(from, to)
+	if err != nil {
+		return nil, err
+	}
+	rate, fetchErr := provider.FetchRate(ctx, from, to)
+	if fetchErr == nil && (rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0)) {
+		fetchErr = fmt.Errorf("provider returned invalid rate %v", rate)
+	}
+
+	key := exchangeRatePairKey(from, to)
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := s.cache[key]
+	if fetchErr != nil {
+		log.Printf("[ExchangeRate] Failed to fetch %s via %s: %v", key, provider.Name(), fetchErr)
+		if entry == nil {
+			return nil, fetchErr
+		}
+		entry.lastError = fetchErr.Error()
+		return s.quoteLocked(from, to, entry, now), nil
+	}
+	if entry != nil && entry.alerted {
+		log.Printf("[ExchangeRate] %s recovered via %s", key, provider.Name())
+	}
+	entry = &exchangeRateCacheEntry{rate: rate, provider: provider.Name(), fetchedAt: now}
+	s.cache[key] = entry
+	return s.quoteLocked(from, to, entry, now), nil
+}
+
+func (s *ExchangeRateService) quoteLocked(from, to string, entry *exchangeRateCacheEntry, now time.Time) *ExchangeRateQuote {
+	return &ExchangeRateQuote{
+		From:      from,
+		To:        to,
+		Rate:      entry.rate,
+		Provider:  entry.provider,
+		UpdatedAt: entry.fetchedAt,
+		Stale:     now.Sub(entry.fetchedAt) >= s.staleAfter(),
+		LastError: entry.lastError,
+	}
+}
+
+// ConvertMinor 按汇率换算最小货币单位金额（两位小数币种）
+func (s *ExchangeRateService) ConvertMinor(ctx context.Context, amountMinor int64, from, to string) (int64, *ExchangeRateQuote, error) {
+	quote, err := s.GetRate(ctx, from, to)
+	if err != nil {
+		return 0, nil, err
+	}
+	return int64(math.Round(float64(amountMinor) * quote.Rate)), quote, nil
+}
+
+// ListQuotes 当前缓存的全部汇率（管理端状态页）
+func (s *ExchangeRateService) ListQuotes() []ExchangeRateQuote {
+	if s == nil {
+		return nil
+	}
+	now := s.now()
+	s.mu.Lock()
+	quotes := make([]ExchangeRateQuote, 0, len(s.cache))
+	for key, entry := range s.cache {
+		from, to, _ := strings.Cut(key, "/")
+		quotes = append(quotes, *s.quoteLocked(from, to, entry, now))
+	}
+	s.mu.Unlock()
+	sort.Slice(quotes, func(i, j int) bool {
+		if quotes[i].From != quotes[j].From {
+			return quotes[i].From < quotes[j].From
+		}
+		return quotes[i].To < quotes[j].To
+	})
+	return quotes
+}
+
+// Start 启动后台刷新：定期刷新已缓存的币对并对过期汇率告警
+func (s *ExchangeRateService) Start() {
+	s.lifecycleMu.Lock()
+	if s.running {
+		s.lifecycleMu.Unlock()
+		return
+	}
+	stopChan := make(chan struct{})
+	doneChan := make(chan struct{})
+	s.stopChan = stopChan
+	s.doneChan = doneChan
+	s.running = true
+	s.lifecycleMu.Unlock()
+
+	go func() {
+		defer close(doneChan)
+		runBackgroundServiceWithStopChan("exchangeRate.refreshLoop", stopChan, s.refreshLoop)
+	}()
+}
+
+// Stop 停止后台刷新
+func (s *ExchangeRateService) Stop() {
+	s.lifecycleMu.Lock()
+	if !s.running {
+		s.lifecycleMu.Unlock()
+		return
+	}
+	stopChan := s.stopChan
+	doneChan := s.doneChan
+	s.stopChan = nil
+	s.doneChan = nil
+	s.running = false
+
+	close(stopChan)
+	<-doneChan
+	s.lifecycleMu.Unlock()
+}
+
+func (s *ExchangeRateService) refreshLoop(stopChan <-chan struct{}) {
+	ticker := time.NewTicker(exchangeRateCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			s.refreshExpired()
+			s.checkStale()
+		}
+	}
+}
+
+// refreshExpired 刷新已超过缓存时长的币对，避免下单时同步等待数据源
+func (s *ExchangeRateService) refreshExpired() {
+	if !s.Enabled() {
+		return
+	}
+	now := s.now()
+	s.mu.Lock()
+	pairs := make([]string, 0, len(s.cache))
+	for key, entry := range s.cache {
+		if now.Sub(entry.fetchedAt) >= s.cacheTTL() {
+			pairs = append(pairs, key)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, key := range pairs {
+		from, to, _ := strings.Cut(key, "/")
+		ctx, cancel := context.WithTimeout(context.Background(), exchangeRateFetchTimeout)
+		_, _ = s.refresh(ctx, from, to)
+		cancel()
+	}
+}
+
+// checkStale 汇率超过告警时长未成功刷新时记录系统日志，每次过期只告警一次
+func (s *ExchangeRateService) checkStale() {
+	if !s.Enabled() {
+		return
+	}
+	now := s.now()
+	type staleAlert struct {
+		pair      string
+		provider  string
+		fetchedAt time.Time
+		lastError string
+	}
+	var alerts []staleAlert
+	s.mu.Lock()
+	for key, entry := range s.cache {
+		if entry.alerted || now.Sub(entry.fetchedAt) < s.staleAfter() {
+			continue
+		}
+		entry.alerted = true
+		alerts = append(alerts, staleAlert{pair: key, provider: entry.provider, fetchedAt: entry.fetchedAt, lastError: entry.lastError})
+	}
+	s.mu.Unlock()
+
+	for _, alert := range alerts {
+		log.Printf("[ExchangeRate] Rate %s is stale: last updated %s via %s, last error: %s",
+			alert.pair, alert.fetchedAt.Format(time.RFC3339), alert.provider, alert.lastError)
+		if s.db != nil {
+			logger.LogSystemOperation(s.db, "exchange_rate_stale", "exchange_rate", nil, map[string]interface{}{
+				"pair":       alert.pair,
+				"provider":   alert.provider,
+				"updated_at": alert.fetchedAt.Format(time.RFC3339),
+				"last_error": alert.lastError,
+			})
+		}
+	}
+}
+
+// 付款脚本运行时在多处创建，通过全局实例访问汇率服务
+var globalExchangeRateService atomic.Pointer[ExchangeRateService]
+
+// SetGlobalExchangeRateService 设置付款脚本使用的汇率服务
+func SetGlobalExchangeRateService(svc *ExchangeRateService) {
+	globalExchangeRateService.Store(svc)
+}
+
+// GlobalExchangeRateService 当前生效的汇率服务，未设置时返回 nil
+func GlobalExchangeRateService() *ExchangeRateService {
+	return globalExchangeRateService.Load()
+}
+
+func fetchExchangeRateJSON(ctx context.Context, client *http.Client, target string, headers map[string]string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "AuraLogic-ExchangeRate/1.0")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, exchangeRateMaxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// exchangeRateHostProvider 法币汇率（exchangerate.host）
+type exchangeRateHostProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  func() string
+}
+
+func (p *exchangeRateHostProvider) Name() string {
+	return ExchangeRateProviderExchangeRateHost
+}
+
+func (p *exchangeRateHostProvider) FetchRate(ctx context.Context, from, to string) (float64, error) {
+	query := url.Values{}
+	query.Set("from", from)
+	query.Set("to", to)
+	query.Set("amount", "1")
+	if key := strings.TrimSpace(p.apiKey()); key != "" {
+		query.Set("access_key", key)
+	}
+	var payload struct {
+		Success *bool   `json:"success"`
+		Result  float64 `json:"result"`
+		Error   struct {
+			Info string `json:"info"`
+		} `json:"error"`
+	}
+	if err := fetchExchangeRateJSON(ctx, p.client, p.baseURL+"/convert?"+query.Encode(), nil, &payload); err != nil {
+		return 0, err
+	}
+	if payload.Success != nil && !*payload.Success {
+		return 0, fmt.Errorf("exchangerate.host error: %s", payload.Error.Info)
+	}
+	return payload.Result, nil
+}
+
+// coinGeckoProvider 加密货币汇率（CoinGecko simple/price）
+type coinGeckoProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  func() string
+}
+
+func (p *coinGeckoProvider) Name() string {
+	return ExchangeRateProviderCoinGecko
+}
+
+func (p *coinGeckoProvider) FetchRate(ctx context.Context, from, to string) (float64, error) {
+	// 法币兑加密货币取倒数；两种加密货币之间经 USD 换算
+	fromID, fromCrypto := exchangeRateCryptoIDs[from]
+	if !fromCrypto {
+		if !IsCryptoCurrency(to) {
+			return 0, fmt.Errorf("coingecko does not support fiat pair %s/%s", from, to)
+		}
+		rate, err := p.FetchRate(ctx, to, from)
+		if err != nil || rate == 0 {
+			return 0, err
+		}
+		return 1 / rate, nil
+	}
+	if IsCryptoCurrency(to) {
+		fromUSD, err := p.fetchPrice(ctx, fromID, "usd")
+		if err != nil {
+			return 0, err
+		}
+		toUSD, err := p.fetchPrice(ctx, exchangeRateCryptoIDs[to], "usd")
+		if err != nil || toUSD == 0 {
+			return 0, err
+		}
+		return fromUSD / toUSD, nil
+	}
+	return p.fetchPrice(ctx, fromID, strings.ToLower(to))
+}
+
+func (p *coinGeckoProvider) fetchPrice(ctx context.Context, id, vsCurrency string) (float64, error) {
+	query := url.Values{}
+	query.Set("ids", id)
+	query.Set("vs_currencies", vsCurrency)
+	headers := map[string]string{}
+	if key := strings.TrimSpace(p.apiKey()); key != "" {
+		headers["x-cg-demo-api-key"] = key
+	}
+	var payload map[string]map[string]float64
+	if err := fetchExchangeRateJSON(ctx, p.client, p.baseURL+"/simple/price?"+query.Encode(), headers, &payload); err != nil {
+		return 0, err
+	}
+	price, ok := payload[id][vsCurrency]
+	if !ok {
+		return 0, fmt.Errorf("coingecko has no %s price for %s", vsCurrency, id)
+	}
+	return price, nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type fakeExchangeRateProvider struct {
+	name  string
+	rate  float64
+	err   error
+	calls int
+}
+
+func (p *fakeExchangeRateProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeExchangeRateProvider) FetchRate(ctx context.Context, from, to string) (float64, error) {
+	p.calls++
+	return p.rate, p.err
+}
+
+func newExchangeRateTestService(t *testing.T) (*ExchangeRateService, *fakeExchangeRateProvider, *fakeExchangeRateProvider, *time.Time) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	if err := db.AutoMigrate(&models.OperationLog{}); err != nil {
+		t.Fatalf("auto migrate failed: %v", err)
+	}
+
+	cfg := &config.Config{ExchangeRate: config.ExchangeRateConfig{
+		Enabled:           true,
+		CacheMinutes:      10,
+		StaleAlertMinutes: 60,
+	}}
+	svc := NewExchangeRateService(db, cfg)
+	fiat := &fakeExchangeRateProvider{name: ExchangeRateProviderExchangeRateHost, rate: 0.14}
+	crypto := &fakeExchangeRateProvider{name: ExchangeRateProviderCoinGecko, rate: 7.2}
+	svc.RegisterProvider(fiat)
+	svc.RegisterProvider(crypto)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	return svc, fiat, crypto, &now
+}
+
+func TestExchangeRateServiceCachesAndRoutesByCurrency(t *testing.T) {
+	svc, fiat, crypto, now := newExchangeRateTestService(t)
+
+	quote, err := svc.GetRate(context.Background(), "usdt", "cny")
+	if err != nil {
+		t.Fatalf("get rate: %v", err)
+	}
+	if quote.Rate != 7.2 || quote.Provider != ExchangeRateProviderCoinGecko || quote.From != "USDT" || quote.To != "CNY" {
+		t.Fatalf("unexpected crypto quote: %+v", quote)
+	}
+	if _, err := svc.GetRate(context.Background(), "CNY", "USD"); err != nil {
+		t.Fatalf("get fiat rate: %v", err)
+	}
+	if fiat.calls != 1 || crypto.calls != 1 {
+		t.Fatalf("expected one call per provider, got fiat=%d crypto=%d", fiat.calls, crypto.calls)
+	}
+
+	*now = now.Add(5 * time.Minute)
+	if _, err := svc.GetRate(context.Background(), "USDT", "CNY"); err != nil {
+		t.Fatalf("cached rate: %v", err)
+	}
+	if crypto.calls != 1 {
+		t.Fatalf("expected cached rate within cache window, got %d fetches", crypto.calls)
+	}
+
+	*now = now.Add(6 * time.Minute)
+	if _, err := svc.GetRate(context.Background(), "USDT", "CNY"); err != nil {
+		t.Fatalf("refreshed rate: %v", err)
+	}
+	if crypto.calls != 2 {
+		t.Fatalf("expected refresh after cache window, got %d fetches", crypto.calls)
+	}
+}
+
+func TestExchangeRateServiceServesStaleRateAndAlertsOnce(t *testing.T) {
+	svc, _, crypto, now := newExchangeRateTestService(t)
+
+	if _, err := svc.GetRate(context.Background(), "USDT", "CNY"); err != nil {
+		t.Fatalf("get rate: %v", err)
+	}
+	crypto.err = errors.New("upstream down")
+
+	*now = now.Add(30 * time.Minute)
+	quote, err := svc.GetRate(context.Background(), "USDT", "CNY")
+	if err != nil {
+		t.Fatalf("expected cached fallback, got %v", err)
+	}
+	if quote.Rate != 7.2 || quote.Stale || quote.LastError == "" {
+		t.Fatalf("expected fresh-enough fallback with last error, got %+v", quote)
+	}
+
+	*now = now.Add(31 * time.Minute)
+	svc.refreshExpired()
+	quote, err = svc.GetRate(context.Background(), "USDT", "CNY")
+	if err != nil || !quote.Stale {
+		t.Fatalf("expected stale quote after alert window, got %+v err=%v", quote, err)
+	}
+
+	svc.checkStale()
+	svc.checkStale()
+	var alerts int64
+	svc.db.Model(&models.OperationLog{}).Where("action = ?", "exchange_rate_stale").Count(&alerts)
+	if alerts != 1 {
+		t.Fatalf("expected a single stale alert, got %d", alerts)
+	}
+
+	crypto.err = nil
+	*now = now.Add(time.Minute)
+	svc.refreshExpired()
+	if quotes := svc.ListQuotes(); len(quotes) != 1 || quotes[0].Stale || quotes[0].LastError != "" {
+		t.Fatalf("expected recovered quote, got %+v", quotes)
+	}
+}
+
+func TestExchangeRateServiceRejectsWhenDisabledOrUnknown(t *testing.T) {
+	svc, fiat, _, _ := newExchangeRateTestService(t)
+
+	fiat.err = errors.New("no data")
+	if _, err := svc.GetRate(context.Background(), "CNY", "EUR"); err == nil {
+		t.Fatalf("expected error without cached fallback")
+	}
+	if _, err := svc.GetRate(context.Background(), "C1", "EUR"); err == nil {
+		t.Fatalf("expected invalid currency code to be rejected")
+	}
+
+	svc.cfg.ExchangeRate.Enabled = false
+	if _, err := svc.GetRate(context.Background(), "USDT", "CNY"); err == nil {
+		t.Fatalf("expected disabled service to reject lookups")
+	}
+}
+
+func TestCoinGeckoProviderInvertsFiatToCrypto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/simple/price" || r.URL.Query().Get("ids") != "tether" || r.URL.Query().Get("vs_currencies") != "cny" {
+			http.Error(w, "unexpected query", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"tether":{"cny":8}}`))
+	}))
+	defer server.Close()
+
+	provider := &coinGeckoProvider{client: server.Client(), baseURL: server.URL, apiKey: func() string { return "" }}
+	rate, err := provider.FetchRate(context.Background(), "USDT", "CNY")
+	if err != nil || rate != 8 {
+		t.Fatalf("expected USDT/CNY 8, got %v err=%v", rate, err)
+	}
+	rate, err = provider.FetchRate(context.Background(), "CNY", "USDT")
+	if err != nil || math.Abs(rate-0.125) > 1e-9 {
+		t.Fatalf("expected inverted CNY/USDT 0.125, got %v err=%v", rate, err)
+	}
+	if _, err := provider.FetchRate(context.Background(), "CNY", "USD"); err == nil {
+		t.Fatalf("expected fiat pair to be rejected")
+	}
+}
+
+func TestOrderConvertedDisplayAmounts(t *testing.T) {
+	svc, fiat, _, _ := newExchangeRateTestService(t)
+	svc.cfg.ExchangeRate.DisplayCurrencies = []string{"usd", "CNY", "USD"}
+
+	orderSvc := &OrderService{cfg: svc.cfg}
+	order := &models.Order{OrderNo: "O-1", Currency: "CNY", TotalAmount: 10000}
+	if amounts := orderSvc.ConvertedDisplayAmounts(order, ""); amounts != nil {
+		t.Fatalf("expected no display amounts without exchange rate service, got %+v", amounts)
+	}
+
+	orderSvc.SetExchangeRateService(svc)
+	amounts := orderSvc.ConvertedDisplayAmounts(order, "")
+	if len(amounts) != 1 || amounts[0].Currency != "USD" || amounts[0].AmountMinor != 1400 || !amounts[0].Approximate {
+		t.Fatalf("expected single approximate USD amount of 1400, got %+v", amounts)
+	}
+	amounts = orderSvc.ConvertedDisplayAmounts(order, "eur")
+	if len(amounts) != 2 || amounts[0].Currency != "EUR" || amounts[1].Currency != "USD" {
+		t.Fatalf("expected preferred EUR listed first, got %+v", amounts)
+	}
+
+	svc.cfg.Order.Currency = "CNY"
+	products := []models.Product{{Price: 1000, OriginalPrice: 1500}}
+	orderSvc.ApplyProductDisplayPrices(products, "CNY")
+	if products[0].DisplayPrice != nil {
+		t.Fatalf("expected no display price for base currency, got %+v", products[0].DisplayPrice)
+	}
+	orderSvc.ApplyProductDisplayPrices(products, "usd")
+	if price := products[0].DisplayPrice; price == nil || price.PriceMinor != 140 || price.OriginalPriceMinor != 210 || !price.Approximate {
+		t.Fatalf("expected approximate USD product price, got %+v", price)
+	}
+
+	fiat.err = errors.New("upstream down")
+	svc.cache = map[string]*exchangeRateCacheEntry{}
+	if amounts := orderSvc.ConvertedDisplayAmounts(order, ""); len(amounts) != 0 {
+		t.Fatalf("expected unavailable rates to be skipped, got %+v", amounts)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FieldMaskPolicyInput 单个字段的策略
+type FieldMaskPolicyInput struct {
+	Field models.FieldMaskField `json:"field"`
+	Mode  models.FieldMaskMode  `json:"mode"`
+	Scope models.FieldMaskScope `json:"scope"`
+}
+
+// FieldMaskRolePolicies 某个角色的完整策略（未配置的字段为默认值）
+type FieldMaskRolePolicies struct {
+	Role     string                   `json:"role"`
+	Policies []models.FieldMaskPolicy `json:"policies"`
+}
+
+// FieldMaskService 按角色配置的敏感字段打码策略
+type FieldMaskService struct {
+	db *gorm.DB
+}
+
+func NewFieldMaskService(db *gorm.DB) *FieldMaskService {
+	return &FieldMaskService{db: db}
+}
+
+func isFieldMaskRole(role string) bool {
+	for _, item := range models.FieldMaskRoles {
+		if item == role {
+			return true
+		}
+	}
+	return false
+}
+
+// rolePolicies 角色策略：数据库配置覆盖默认值，按 FieldMaskFields 顺序返回
+func (s *FieldMaskService) rolePolicies(role string) ([]models.FieldMaskPolicy, error) {
+	var stored []models.FieldMaskPolicy
+	if err := s.db.Where("role = ?", role).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	byField := make(map[models.FieldMaskField]models.FieldMaskPolicy, len(stored))
+	for _, policy := range stored {
+		byField[policy.Field] = policy
+	}
+	policies := make([]models.FieldMaskPolicy, 0, len(models.FieldMaskFields))
+	for _, field := range models.FieldMaskFields {
+		if policy, ok := byField[field]; ok {
+			policies = append(policies, policy)
+			continue
+		}
+		policies = append(policies, models.DefaultFieldMaskPolicy(role, field))
+	}
+	return policies, nil
+}
+
+// ListPolicies 所有角色的策略
+func (s *FieldMaskService) ListPolicies() ([]FieldMaskRolePolicies, error) {
+	result := make([]FieldMaskRolePolicies, 0, len(models.FieldMaskRoles))
+	for _, role := range models.FieldMaskRoles {
+		policies, err := s.rolePolicies(role)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, FieldMaskRolePolicies{Role: role, Policies: policies})
+	}
+	return result, nil
+}
+
+// UpdateRolePolicies 更新角色策略，未传入的字段保持不变
+func (s *FieldMaskService) UpdateRolePolicies(role string, inputs []FieldMaskPolicyInput, updatedBy *uint) ([]models.FieldMaskPolicy, error) {
+	if !isFieldMaskRole(role) {
+		return nil, bizerr.Newf("fieldMask.roleInvalid", "Unsupported role: %s", role).
+			WithParams(map[string]interface{}{"role": role})
+	}
+	for _, input := range inputs {
+		validMode := input.Mode == models.FieldMaskModeNone || input.Mode == models.FieldMaskModePartial || input.Mode == models.FieldMaskModeFull
+		validScope := input.Scope == models.FieldMaskScopePrivacyOnly || input.Scope == models.FieldMaskScopeAll
+		if !models.IsValidFieldMaskField(input.Field) || !validMode || !validScope {
+			return nil, bizerr.Newf("fieldMask.policyInvalid", "Invalid masking policy for field %s", input.Field).
+				WithParams(map[string]interface{}{"field": string(input.Field)})
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, input := range inputs {
+			policy := models.FieldMaskPolicy{
+				Role:      role,
+				Field:     input.Field,
+				Mode:      input.Mode,
+				Scope:     input.Scope,
+				UpdatedBy: updatedBy,
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "role"}, {Name: "field"}},
+				DoUpdates: clause.AssignmentColumns([]string{"mode", "scope", "updated_by", "updated_at"}),
+			}).Create(&policy).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.rolePolicies(role)
+}
+
+// RulesFor 计算管理员实际生效的规则：单字段查看权限直接放行该字段，
+// order.view_privacy 放行所有仅针对隐私订单的策略（兼容原有权限）
+func (s *FieldMaskService) RulesFor(role string, permissions []string) (models.FieldMaskRules, error) {
+	policies, err := s.rolePolicies(role)
+	if err != nil {
+		return nil, err
+	}
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		granted[permission] = true
+	}
+
+	rules := make(models.FieldMaskRules, len(policies))
+	for _, policy := range policies {
+		if policy.Mode == models.FieldMaskModeNone || granted[models.FieldMaskRevealPermissions[policy.Field]] {
+			continue
+		}
+		if policy.Scope == models.FieldMaskScopePrivacyOnly && granted["order.view_privacy"] {
+			continue
+		}
+		rules[policy.Field] = policy
+	}
+	return rules, nil
+}
+
+// ResolveAdminFieldMaskRules 按管理员角色和显式权限计算打码规则。
+// 特殊权限即使超级管理员也需显式授予；查询失败时退回默认规则
+func ResolveAdminFieldMaskRules(db *gorm.DB, userID uint) models.FieldMaskRules {
+	var user models.User
+	if err := db.Select("id", "role").First(&user, userID).Error; err != nil {
+		return models.DefaultFieldMaskRules()
+	}
+	var permissions []string
+	var perm models.AdminPermission
+	if err := db.Where("user_id = ?", userID).First(&perm).Error; err == nil {
+		permissions = perm.Permissions
+	}
+	rules, err := NewFieldMaskService(db).RulesFor(user.Role, permissions)
+	if err != nil {
+		return models.DefaultFieldMaskRules()
+	}
+	return rules
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestFieldMaskServiceRulesFollowRolePolicyAndRevealPermissions(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.FieldMaskPolicy{})
+	svc := NewFieldMaskService(db)
+
+	_, err := svc.UpdateRolePolicies("admin", []FieldMaskPolicyInput{
+		{Field: models.FieldMaskEmail, Mode: models.FieldMaskModePartial, Scope: models.FieldMaskScopeAll},
+		{Field: models.FieldMaskPayment, Mode: models.FieldMaskModeFull, Scope: models.FieldMaskScopePrivacyOnly},
+	}, nil)
+	if err != nil {
+		t.Fatalf("update policies failed: %v", err)
+	}
+	_, err = svc.UpdateRolePolicies("user", nil, nil)
+	requireProductBizErr(t, err, "fieldMask.roleInvalid")
+	_, err = svc.UpdateRolePolicies("admin", []FieldMaskPolicyInput{{Field: "ssn", Mode: models.FieldMaskModeFull, Scope: models.FieldMaskScopeAll}}, nil)
+	requireProductBizErr(t, err, "fieldMask.policyInvalid")
+
+	rules, err := svc.RulesFor("admin", nil)
+	if err != nil {
+		t.Fatalf("resolve rules failed: %v", err)
+	}
+	order := models.Order{
+		ReceiverName:    "Alice",
+		ReceiverEmail:   "alice@example.com",
+		ReceiverPhone:   "13812345678",
+		ReceiverAddress: "1 Main St",
+	}
+	rules.ApplyToOrder(&order)
+	if order.ReceiverEmail != "a***@example.com" {
+		t.Fatalf("expected email masked on regular orders, got %q", order.ReceiverEmail)
+	}
+	if order.ReceiverName != "Alice" || order.ReceiverPhone != "13812345678" {
+		t.Fatalf("expected privacy-only policies to skip regular orders, got %+v", order)
+	}
+	if got := rules.MaskPaymentData(`{"txn":"ABC123456"}`, true); got != "***" {
+		t.Fatalf("expected payment data fully masked, got %q", got)
+	}
+
+	privateOrder := models.Order{ReceiverName: "Bob", ReceiverPhone: "13812345678", ReceiverAddress: "2 Main St", PrivacyProtected: true}
+	rules.ApplyToOrder(&privateOrder)
+	if privateOrder.ReceiverName != "***" || privateOrder.ReceiverAddress != "***" || privateOrder.ReceiverPhone != "138****5678" {
+		t.Fatalf("expected default privacy masking, got %+v", privateOrder)
+	}
+
+	// super_admin 未配置时使用默认策略
+	superRules, err := svc.RulesFor("super_admin", nil)
+	if err != nil {
+		t.Fatalf("resolve rules failed: %v", err)
+	}
+	if _, ok := superRules[models.FieldMaskEmail]; ok {
+		t.Fatal("expected default super_admin policy not to mask email")
+	}
+
+	revealed, err := svc.RulesFor("admin", []string{"order.view_email", "order.view_privacy"})
+	if err != nil {
+		t.Fatalf("resolve rules failed: %v", err)
+	}
+	if len(revealed) != 0 {
+		t.Fatalf("expected reveal permissions to lift every policy, got %+v", revealed)
+	}
+}
+
+func TestFieldMaskRulesMaskLogDetails(t *testing.T) {
+	rules := models.DefaultFieldMaskRules()
+	details := map[string]interface{}{
+		"privacy_protected": true,
+		"receiver_name":     "Alice",
+		"receiver_phone":    "13812345678",
+		"receiver_email":    "alice@example.com",
+		"changes":           map[string]interface{}{"receiver_address": "1 Main St"},
+	}
+	masked := rules.MaskLogDetails(details)
+	if masked["receiver_name"] != "***" || masked["receiver_phone"] != "138****5678" || masked["receiver_email"] != "alice@example.com" {
+		t.Fatalf("unexpected masked details: %+v", masked)
+	}
+	if nested := masked["changes"].(map[string]interface{}); nested["receiver_address"] != "***" {
+		t.Fatalf("expected nested address masked, got %+v", nested)
+	}
+	if details["receiver_name"] != "Alice" {
+		t.Fatal("expected original details to be untouched")
+	}
+
+	plain := rules.MaskLogDetails(map[string]interface{}{"receiver_name": "Alice"})
+	if plain["receiver_name"] != "Alice" {
+		t.Fatalf("expected non-privacy log untouched, got %+v", plain)
+	}
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+const maxGiftPromotionQuantity = 100
+
+var ErrGiftPromotionNotFound = bizerr.New("giftPromotion.notFound", "Gift promotion not found")
+
+// GiftPromotionInput 创建/更新满赠活动参数
+type GiftPromotionInput struct {
+	Name                 string     `json:"name"`
+	Description          string     `json:"description"`
+	IsActive             *bool      `json:"is_active"`
+	Priority             int        `json:"priority"`
+	ThresholdMinor       int64      `json:"threshold_minor"`
+	QualifyingProductIDs []uint     `json:"qualifying_product_ids"`
+	GiftProductID        uint       `json:"gift_product_id"`
+	GiftInventoryID      uint       `json:"gift_inventory_id"`
+	GiftQuantity         int        `json:"gift_quantity"`
+	StartsAt             *time.Time `json:"starts_at"`
+	EndsAt               *time.Time `json:"ends_at"`
+}
+
+// GiftLine 满足条件的赠品（购物车预览和下单共用）
+type GiftLine struct {
+	PromotionID   uint              `json:"promotion_id"`
+	PromotionName string            `json:"promotion_name"`
+	ProductID     uint              `json:"product_id"`
+	InventoryID   uint              `json:"-"`
+	SKU           string            `json:"sku"`
+	Name          string            `json:"name"`
+	ImageURL      string            `json:"image_url,omitempty"`
+	Quantity      int               `json:"quantity"`
+	ValueMinor    int64             `json:"value_minor"` // 赠品按当前售价计算的价值
+	Attributes    map[string]string `json:"attributes,omitempty"`
+	Available     bool              `json:"available"` // 赠品库存不足时为 false，下单时不会附赠
+}
+
+// GiftPromotionService 满赠活动
+type GiftPromotionService struct {
+	db *gorm.DB
+}
+
+func NewGiftPromotionService(db *gorm.DB) *GiftPromotionService {
+	return &GiftPromotionService{db: db}
+}
+
+func (s *GiftPromotionService) validateInput(input *GiftPromotionInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return bizerr.New("giftPromotion.nameRequired", "Promotion name is required")
+	}
+	if input.ThresholdMinor < 0 {
+		return bizerr.New("giftPromotion.thresholdNegative", "Spend threshold must be greater than or equal to 0")
+	}
+	if input.ThresholdMinor == 0 && len(input.QualifyingProductIDs) == 0 {
+		return bizerr.New("giftPromotion.conditionRequired", "Set a spend threshold or qualifying products")
+	}
+	if input.GiftQuantity == 0 {
+		input.GiftQuantity = 1
+	}
+	if input.GiftQuantity < 1 || input.GiftQuantity > maxGiftPromotionQuantity {
+		return bizerr.Newf("giftPromotion.quantityInvalid", "Gift quantity must be between 1 and %d", maxGiftPromotionQuantity).
+			WithParams(map[string]interface{}{"max": maxGiftPromotionQuantity})
+	}
+	if input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt) {
+		return bizerr.New("giftPromotion.timeRangeInvalid", "End time must be later than start time")
+	}
+
+	var product models.Product
+	if err := s.db.Select("id", "product_type").First(&product, input.GiftProductID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return bizerr.New("giftPromotion.giftProductNotFound", "Gift product not found")
+		}
+		return err
+	}
+	// 赠品走实物库存预留，不支持虚拟商品
+	if product.ProductType == models.ProductTypeVirtual {
+		return bizerr.New("giftPromotion.giftProductVirtual", "Virtual products cannot be used as gifts")
+	}
+	var inventoryCount int64
+	if err := s.db.Model(&models.Inventory{}).Where("id = ?", input.GiftInventoryID).Count(&inventoryCount).Error; err != nil {
+		return err
+	}
+	if inventoryCount == 0 {
+		return bizerr.New("giftPromotion.giftInventoryNotFound", "Gift inventory not found")
+	}
+	return nil
+}
+
+func applyGiftPromotionInput(promotion *models.GiftPromotion, input GiftPromotionInput) {
+	promotion.Name = input.Name
+	promotion.Description = input.Description
+	promotion.Priority = input.Priority
+	promotion.ThresholdMinor = input.ThresholdMinor
+	promotion.QualifyingProductIDs = input.QualifyingProductIDs
+	promotion.GiftProductID = input.GiftProductID
+	promotion.GiftInventoryID = input.GiftInventoryID
+	promotion.GiftQuantity = input.GiftQuantity
+	promotion.StartsAt = input.StartsAt
+	promotion.EndsAt = input.EndsAt
+	if input.IsActive != nil {
+		promotion.IsActive = *input.IsActive
+	}
+}
+
+// List 满赠活动分页列表
+func (s *GiftPromotionService) List(page, limit int) ([]models.GiftPromotion, int64, error) {
+	var (
+		items []models.GiftPromotion
+		total int64
+	)
+	query := s.db.Model(&models.GiftPromotion{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("priority DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&items).Error
+	return items, total, err
+}
+
+// Get 满赠活动详情
+func (s *GiftPromotionService) Get(id uint) (*models.GiftPromotion, error) {
+	var promotion models.GiftPromotion
+	if err := s.db.First(&promotion, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGiftPromotionNotFound
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// Create 创建满赠活动
+func (s *GiftPromotionService) Create(input GiftPromotionInput) (*models.GiftPromotion, error) {
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	promotion := &models.GiftPromotion{IsActive: true}
+	applyGiftPromotionInput(promotion, input)
+	if err := s.db.Create(promotion).Error; err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// Update 更新满赠活动
+func (s *GiftPromotionService) Update(id uint, input GiftPromotionInput) (*models.GiftPromotion, error) {
+	promotion, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	applyGiftPromotionInput(promotion, input)
+	if err := s.db.Save(promotion).Error; err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// Delete 删除满赠活动（已下单的赠品不受影响）
+func (s *GiftPromotionService) Delete(id uint) error {
+	result := s.db.Delete(&models.GiftPromotion{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrGiftPromotionNotFound
+	}
+	return nil
+}
+
+// ResolveGifts 根据商品原价小计和商品列表计算可获得的赠品
+// 每个满足条件的活动赠送一份，按优先级排序；赠品商品已删除的活动会被跳过
+func (s *GiftPromotionService) ResolveGifts(subtotalMinor int64, productIDs []uint) ([]GiftLine, error) {
+	var promotions []models.GiftPromotion
+	if err := s.db.Where("is_active = ?", true).Order("priority DESC, id ASC").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+
+	now := models.NowFunc()
+	gifts := make([]GiftLine, 0)
+	for i := range promotions {
+		promotion := &promotions[i]
+		if !promotion.IsRunningAt(now) || !promotion.IsQualified(subtotalMinor, productIDs) {
+			continue
+		}
+
+		var product models.Product
+		if err := s.db.First(&product, promotion.GiftProductID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		var inventory models.Inventory
+		if err := s.db.First(&inventory, promotion.GiftInventoryID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		canPurchase, _ := inventory.CanPurchase(promotion.GiftQuantity)
+		gifts = append(gifts, GiftLine{
+			PromotionID:   promotion.ID,
+			PromotionName: promotion.Name,
+			ProductID:     product.ID,
+			InventoryID:   inventory.ID,
+			SKU:           product.SKU,
+			Name:          product.Name,
+			ImageURL:      product.GetPrimaryImage(),
+			Quantity:      promotion.GiftQuantity,
+			ValueMinor:    product.Price * int64(promotion.GiftQuantity),
+			Attributes:    inventory.AttributesMap(),
+			Available:     canPurchase,
+		})
+	}
+	return gifts, nil
+}
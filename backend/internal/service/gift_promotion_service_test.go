@@ -0,0 +1,177 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func TestGiftPromotionQualification(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+
+	threshold := models.GiftPromotion{IsActive: true, ThresholdMinor: 10000}
+	if threshold.IsQualified(9999, []uint{1}) || !threshold.IsQualified(10000, nil) {
+		t.Fatalf("threshold promotion should require subtotal >= 10000")
+	}
+
+	sku := models.GiftPromotion{IsActive: true, QualifyingProductIDs: []uint{5, 6}}
+	if sku.IsQualified(100000, []uint{1, 2}) || !sku.IsQualified(1, []uint{2, 6}) {
+		t.Fatalf("qualifying product promotion should require one of the products")
+	}
+
+	both := models.GiftPromotion{IsActive: true, ThresholdMinor: 500, QualifyingProductIDs: []uint{5}}
+	if both.IsQualified(400, []uint{5}) || both.IsQualified(600, []uint{1}) || !both.IsQualified(600, []uint{5}) {
+		t.Fatalf("promotion with both conditions should require both")
+	}
+
+	if (&models.GiftPromotion{IsActive: true}).IsQualified(100000, []uint{1}) {
+		t.Fatalf("promotion without conditions should never qualify")
+	}
+
+	scheduled := models.GiftPromotion{IsActive: true, StartsAt: &later}
+	if scheduled.IsRunningAt(now) || !scheduled.IsRunningAt(later) {
+		t.Fatalf("promotion should start at starts_at")
+	}
+	ended := models.GiftPromotion{IsActive: true, EndsAt: &now}
+	if ended.IsRunningAt(now) {
+		t.Fatalf("promotion should end at ends_at")
+	}
+}
+
+func TestGiftPromotionValidation(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Product{}, &models.GiftPromotion{})
+	svc := NewGiftPromotionService(db)
+
+	virtual := models.Product{SKU: "GIFT-VIRTUAL", Name: "Virtual", ProductType: models.ProductTypeVirtual, Status: models.ProductStatusActive}
+	if err := db.Create(&virtual).Error; err != nil {
+		t.Fatalf("create product failed: %v", err)
+	}
+
+	_, err := svc.Create(GiftPromotionInput{Name: "No condition", GiftProductID: virtual.ID})
+	requireProductBizErr(t, err, "giftPromotion.conditionRequired")
+	_, err = svc.Create(GiftPromotionInput{Name: "Missing", ThresholdMinor: 100, GiftProductID: 999})
+	requireProductBizErr(t, err, "giftPromotion.giftProductNotFound")
+	_, err = svc.Create(GiftPromotionInput{Name: "Virtual", ThresholdMinor: 100, GiftProductID: virtual.ID})
+	requireProductBizErr(t, err, "giftPromotion.giftProductVirtual")
+	_, err = svc.Create(GiftPromotionInput{Name: "Too many", ThresholdMinor: 100, GiftProductID: virtual.ID, GiftQuantity: 1000})
+	requireProductBizErr(t, err, "giftPromotion.quantityInvalid")
+	_, err = svc.Get(999)
+	requireProductBizErr(t, err, "giftPromotion.notFound")
+}
+
+func TestCreateUserOrderAddsGiftAndReservesGiftInventory(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Product{}, &models.Order{}, &models.OrderNote{}, &models.InventoryLog{}, &models.GiftPromotion{})
+
+	cfg := &config.Config{}
+	cfg.Order.MaxOrderItems = 20
+	cfg.Order.MaxItemQuantity = 10
+	cfg.Order.Currency = "CNY"
+	cfg.Form.ExpireHours = 24
+
+	user := models.User{
+		UUID:         "gift-promotion-user",
+		Email:        "gift-promotion@example.com",
+		Name:         "gift-promotion",
+		Role:         "user",
+		IsActive:     true,
+		PasswordHash: "hash",
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user failed: %v", err)
+	}
+	product := models.Product{
+		SKU:         "SKU-GIFT-MAIN",
+		Name:        "Main Product",
+		ProductType: models.ProductTypeVirtual,
+		Status:      models.ProductStatusActive,
+		Price:       600,
+	}
+	giftProduct := models.Product{
+		SKU:         "SKU-GIFT-TOTE",
+		Name:        "Tote Bag",
+		ProductType: models.ProductTypePhysical,
+		Status:      models.ProductStatusInactive,
+	}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product failed: %v", err)
+	}
+	if err := db.Create(&giftProduct).Error; err != nil {
+		t.Fatalf("create gift product failed: %v", err)
+	}
+	giftInventory := models.Inventory{Name: "Tote stock", Stock: 1, AvailableQuantity: 1, IsActive: true}
+	if err := db.Create(&giftInventory).Error; err != nil {
+		t.Fatalf("create gift inventory failed: %v", err)
+	}
+
+	giftSvc := NewGiftPromotionService(db)
+	promotion, err := giftSvc.Create(GiftPromotionInput{
+		Name:            "Spend 1000 get a tote",
+		ThresholdMinor:  1000,
+		GiftProductID:   giftProduct.ID,
+		GiftInventoryID: giftInventory.ID,
+	})
+	if err != nil {
+		t.Fatalf("create promotion failed: %v", err)
+	}
+
+	svc := newConcurrentOrderService(db, cfg, nil)
+	svc.SetGiftPromotionService(giftSvc)
+	orderItem := func(quantity int) []models.OrderItem {
+		return []models.OrderItem{
+			{SKU: product.SKU, Name: product.Name, Quantity: quantity, ProductType: models.ProductTypeVirtual},
+			// 客户端伪造的赠品项会被忽略
+			{SKU: giftProduct.SKU, Name: giftProduct.Name, Quantity: 5, IsGift: true},
+		}
+	}
+
+	// 未达门槛不附赠
+	small, err := svc.CreateUserOrder(user.ID, orderItem(1), "", "")
+	if err != nil {
+		t.Fatalf("create small order failed: %v", err)
+	}
+	if len(small.Items) != 1 || small.Items[0].IsGift {
+		t.Fatalf("expected no gift below threshold, got %+v", small.Items)
+	}
+
+	order, err := svc.CreateUserOrder(user.ID, orderItem(2), "", "")
+	if err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+	if len(order.Items) != 2 {
+		t.Fatalf("expected main item and gift, got %+v", order.Items)
+	}
+	gift := order.Items[1]
+	if !gift.IsGift || gift.SKU != giftProduct.SKU || gift.Quantity != 1 || gift.LineTotalMinor != 0 ||
+		gift.GiftPromotionID == nil || *gift.GiftPromotionID != promotion.ID {
+		t.Fatalf("unexpected gift item: %+v", gift)
+	}
+	if order.TotalAmount != 1200 || order.Items[0].LineTotalMinor != 1200 {
+		t.Fatalf("gift should not change order total, got total=%d items=%+v", order.TotalAmount, order.Items)
+	}
+
+	var reloaded models.Inventory
+	db.First(&reloaded, giftInventory.ID)
+	if reloaded.ReservedQuantity != 1 {
+		t.Fatalf("expected gift inventory reserved, got %d", reloaded.ReservedQuantity)
+	}
+
+	// 赠品库存用完后照常下单，只是不再附赠
+	second, err := svc.CreateUserOrder(user.ID, orderItem(2), "", "")
+	if err != nil {
+		t.Fatalf("create second order failed: %v", err)
+	}
+	if len(second.Items) != 1 {
+		t.Fatalf("expected no gift when gift inventory is exhausted, got %+v", second.Items)
+	}
+
+	if err := svc.CancelOrder(order.ID, "test cancel"); err != nil {
+		t.Fatalf("cancel order failed: %v", err)
+	}
+	db.First(&reloaded, giftInventory.ID)
+	if reloaded.ReservedQuantity != 0 {
+		t.Fatalf("expected gift inventory released after cancel, got %d", reloaded.ReservedQuantity)
+	}
+}
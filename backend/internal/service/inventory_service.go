@@ -83,6 +83,30 @@ func (s *InventoryService) UpdateInventory(id uint, stock, availableQuantity, sa
 	return s.inventoryRepo.Update(inventory)
 }
 
+// InventoryDimensions 规格重量（克）与尺寸（毫米）
+type InventoryDimensions struct {
+	WeightGrams int
+	LengthMM    int
+	WidthMM     int
+	HeightMM    int
+}
+
+// UpdateInventoryDimensions 更新规格重量与尺寸，0 表示沿用商品设置
+func (s *InventoryService) UpdateInventoryDimensions(id uint, dims InventoryDimensions) error {
+	if dims.WeightGrams < 0 || dims.LengthMM < 0 || dims.WidthMM < 0 || dims.HeightMM < 0 {
+		return bizerr.New("product.dimensionsInvalid", "Dimensions cannot be negative")
+	}
+	inventory, err := s.inventoryRepo.FindByID(id)
+	if err != nil {
+		return translateInventoryLookupError(err)
+	}
+	inventory.WeightGrams = dims.WeightGrams
+	inventory.LengthMM = dims.LengthMM
+	inventory.WidthMM = dims.WidthMM
+	inventory.HeightMM = dims.HeightMM
+	return s.inventoryRepo.Update(inventory)
+}
+
 // GetInventory 获取Inventory详情
 func (s *InventoryService) GetInventory(id uint) (*models.Inventory, error) {
 	return s.inventoryRepo.FindByID(id)
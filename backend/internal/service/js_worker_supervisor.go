@@ -19,6 +19,7 @@ import (
 
 	"auralogic/internal/config"
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/demomode"
 	"auralogic/internal/pkg/pluginutil"
 	"auralogic/internal/pluginipc"
 	"gorm.io/gorm"
@@ -908,7 +909,7 @@ func (s *JSWorkerSupervisor) toSandboxConfigWithTimeout(timeout time.Duration) p
 		TimeoutMs:            timeoutMs,
 		MaxMemoryMB:          pluginCfg.Sandbox.MaxMemoryMB,
 		MaxConcurrency:       pluginCfg.Sandbox.MaxConcurrency,
-		AllowNetwork:         pluginCfg.Sandbox.JSAllowNetwork,
+		AllowNetwork:         pluginCfg.Sandbox.JSAllowNetwork && !demomode.Enabled(),
 		AllowFileSystem:      pluginCfg.Sandbox.JSAllowFileSystem,
 		FSMaxFiles:           pluginCfg.JSFSMaxFiles,
 		FSMaxTotalBytes:      pluginCfg.JSFSMaxTotalBytes,
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"auralogic/internal/models"
+
+	"github.com/dop251/goja"
+)
+
+const (
+	// 单次执行最多保留的 console 条数与单条长度，超出部分丢弃并标记 truncated
+	maxJSConsoleEntries       = 200
+	maxJSConsoleMessageLength = 2000
+	// 每个付款方式保留的脚本日志条数
+	paymentScriptLogRetention = 100
+)
+
+// jsConsoleBuffer 收集单次脚本执行的 console 输出
+type jsConsoleBuffer struct {
+	mu        sync.Mutex
+	entries   []models.PaymentScriptLogEntry
+	truncated bool
+}
+
+func (b *jsConsoleBuffer) write(level, message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.entries) >= maxJSConsoleEntries {
+		b.truncated = true
+		return
+	}
+	if utf8.RuneCountInString(message) > maxJSConsoleMessageLength {
+		message = truncateRunes(message, maxJSConsoleMessageLength) + "..."
+		b.truncated = true
+	}
+	b.entries = append(b.entries, models.PaymentScriptLogEntry{
+		Level:   level,
+		Message: message,
+		Time:    time.Now(),
+	})
+}
+
+// Entries 返回已收集的输出副本
+func (b *jsConsoleBuffer) Entries() []models.PaymentScriptLogEntry {
+	if b == nil {
+		return []models.PaymentScriptLogEntry{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	entries := make([]models.PaymentScriptLogEntry, len(b.entries))
+	copy(entries, b.entries)
+	return entries
+}
+
+func (b *jsConsoleBuffer) Truncated() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.truncated
+}
+
+// registerConsole 注册 console.log/info/warn/error/debug，输出写入执行上下文
+func (s *JSRuntimeService) registerConsole(vm *goja.Runtime, ctx *JSContext) {
+	console := vm.NewObject()
+	register := func(name, level string) {
+		console.Set(name, func(call goja.FunctionCall) goja.Value {
+			if ctx.Console != nil {
+				ctx.Console.write(level, formatJSConsoleArgs(call.Arguments))
+			}
+			return goja.Undefined()
+		})
+	}
+	register("log", "info")
+	register("info", "info")
+	register("warn", "warn")
+	register("error", "error")
+	register("debug", "debug")
+	vm.Set("console", console)
+}
+
+func formatJSConsoleArgs(args []goja.Value) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		parts = append(parts, formatJSConsoleValue(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatJSConsoleValue(value goja.Value) string {
+	if value == nil || goja.IsUndefined(value) {
+		return "undefined"
+	}
+	if goja.IsNull(value) {
+		return "null"
+	}
+	// Error 的 message/stack 不可枚举，直接导出会得到 {}
+	if obj, ok := value.(*goja.Object); ok && obj.ClassName() == "Error" {
+		return obj.String()
+	}
+	switch exported := value.Export().(type) {
+	case string:
+		return exported
+	case error:
+		return exported.Error()
+	case map[string]interface{}, []interface{}:
+		if encoded, err := json.Marshal(exported); err == nil {
+			return string(encoded)
+		}
+	}
+	return value.String()
+}
+
+// wrapJSAsync 将同步 API 包装为返回 Promise 的版本，供 async 回调 await 使用
+// 运行时没有事件循环：Promise 在返回前即已 resolve，await 之后的代码在本次回调返回前执行完毕
+// 请求失败时与同步版本一致，resolve 为 { error, status: 0 } 而不是 reject
+func wrapJSAsync(vm *goja.Runtime, fn func(call goja.FunctionCall) goja.Value) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		promise, resolve, _ := vm.NewPromise()
+		_ = resolve(fn(call))
+		return vm.ToValue(promise)
+	}
+}
+
+// settleJSResult 展开脚本回调返回的 Promise（async function）
+func settleJSResult(callback string, value goja.Value) (goja.Value, error) {
+	if value == nil {
+		return value, nil
+	}
+	promise, ok := value.Export().(*goja.Promise)
+	if !ok {
+		return value, nil
+	}
+	switch promise.State() {
+	case goja.PromiseStateFulfilled:
+		return promise.Result(), nil
+	case goja.PromiseStateRejected:
+		return nil, fmt.Errorf("%s rejected: %s", callback, formatJSConsoleValue(promise.Result()))
+	default:
+		return nil, fmt.Errorf("%s returned a promise that never settled", callback)
+	}
+}
+
+// recordScriptLog 记录付款方式脚本的一次执行；测试脚本（ID=0）不落库
+func (s *JSRuntimeService) recordScriptLog(ctx *JSContext, callback string, startedAt time.Time, execErr error) {
+	if s == nil || s.db == nil || ctx == nil || ctx.PaymentMethodID == 0 {
+		return
+	}
+	entries := ctx.Console.Entries()
+	if len(entries) == 0 && execErr == nil {
+		return
+	}
+
+	entry := models.PaymentMethodScriptLog{
+		PaymentMethodID: ctx.PaymentMethodID,
+		Callback:        callback,
+		Success:         execErr == nil,
+		Entries:         entries,
+		Truncated:       ctx.Console.Truncated(),
+		DurationMs:      time.Since(startedAt).Milliseconds(),
+	}
+	if ctx.OrderID != 0 {
+		orderID := ctx.OrderID
+		entry.OrderID = &orderID
+	}
+	if execErr != nil {
+		entry.Error = truncateRunes(execErr.Error(), maxJSConsoleMessageLength)
+	}
+	if err := s.db.Create(&entry).Error; err != nil {
+		log.Printf("Warning: failed to save payment script log for payment method %d: %v", ctx.PaymentMethodID, err)
+		return
+	}
+	s.prunePaymentScriptLogs(ctx.PaymentMethodID)
+}
+
+func (s *JSRuntimeService) prunePaymentScriptLogs(paymentMethodID uint) {
+	var boundary models.PaymentMethodScriptLog
+	err := s.db.Select("id").
+		Where("payment_method_id = ?", paymentMethodID).
+		Order("id DESC").
+		Offset(paymentScriptLogRetention - 1).
+		Limit(1).
+		Find(&boundary).Error
+	if err != nil || boundary.ID == 0 {
+		return
+	}
+	if err := s.db.Where("payment_method_id = ? AND id < ?", paymentMethodID, boundary.ID).
+		Delete(&models.PaymentMethodScriptLog{}).Error; err != nil {
+		log.Printf("Warning: failed to prune payment script logs for payment method %d: %v", paymentMethodID, err)
+	}
+}
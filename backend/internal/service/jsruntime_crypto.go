@@ -0,0 +1,270 @@
+package service
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"regexp"
+	"strings"
+
+	"github.com/dop251/goja"
+)
+
+// 单次 randomBytes 最多生成的字节数
+const maxJSRandomBytes = 1024
+
+var jsKeyWhitespacePattern = regexp.MustCompile(`\s+`)
+
+// encodeJSDigest 按脚本指定的编码输出摘要，默认十六进制
+func encodeJSDigest(sum []byte, encoding string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "hex":
+		return hex.EncodeToString(sum), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(sum), nil
+	default:
+		return "", fmt.Errorf("unsupported encoding %q, expected hex or base64", encoding)
+	}
+}
+
+// jsOptionalString 读取可选的字符串参数，undefined/null 视为空
+func jsOptionalString(call goja.FunctionCall, index int) string {
+	arg := call.Argument(index)
+	if goja.IsUndefined(arg) || goja.IsNull(arg) {
+		return ""
+	}
+	return arg.String()
+}
+
+// createDigest 计算 data 的摘要：(data, encoding?)
+func (s *JSRuntimeService) createDigest(vm *goja.Runtime, newHash func() hash.Hash) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		if len(call.Arguments) < 1 {
+			return vm.ToValue("")
+		}
+		h := newHash()
+		_, _ = h.Write([]byte(call.Arguments[0].String()))
+		encoded, err := encodeJSDigest(h.Sum(nil), jsOptionalString(call, 1))
+		if err != nil {
+			panic(vm.ToValue(err.Error()))
+		}
+		return vm.ToValue(encoded)
+	}
+}
+
+// createHMACSHA256 计算 HMAC-SHA256 摘要：(data, secret, encoding?)，默认十六进制
+func (s *JSRuntimeService) createHMACSHA256(vm *goja.Runtime) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		if len(call.Arguments) < 2 {
+			return vm.ToValue("")
+		}
+		payload := call.Arguments[0].String()
+		secret := call.Arguments[1].String()
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write([]byte(payload))
+		encoded, err := encodeJSDigest(mac.Sum(nil), jsOptionalString(call, 2))
+		if err != nil {
+			panic(vm.ToValue(err.Error()))
+		}
+		return vm.ToValue(encoded)
+	}
+}
+
+// createRandomBytes 生成加密安全的随机字节：(length, encoding?)，默认十六进制
+func (s *JSRuntimeService) createRandomBytes(vm *goja.Runtime) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		length := call.Argument(0).ToInteger()
+		if length <= 0 || length > maxJSRandomBytes {
+			panic(vm.ToValue(fmt.Sprintf("randomBytes length must be between 1 and %d", maxJSRandomBytes)))
+		}
+		buf := make([]byte, length)
+		if _, err := rand.Read(buf); err != nil {
+			panic(vm.ToValue(fmt.Sprintf("randomBytes failed: %v", err)))
+		}
+		encoded, err := encodeJSDigest(buf, jsOptionalString(call, 1))
+		if err != nil {
+			panic(vm.ToValue(err.Error()))
+		}
+		return vm.ToValue(encoded)
+	}
+}
+
+// jsRSAOptions rsaSign/rsaVerify 的可选参数，可传对象或直接传哈希算法字符串
+type jsRSAOptions struct {
+	Hash     crypto.Hash
+	Encoding string
+}
+
+func parseJSRSAOptions(value goja.Value) (jsRSAOptions, error) {
+	opts := jsRSAOptions{Hash: crypto.SHA256, Encoding: "base64"}
+	if value == nil || goja.IsUndefined(value) || goja.IsNull(value) {
+		return opts, nil
+	}
+	hashName := ""
+	switch exported := value.Export().(type) {
+	case string:
+		hashName = exported
+	case map[string]interface{}:
+		if raw, ok := exported["hash"].(string); ok {
+			hashName = raw
+		}
+		if raw, ok := exported["encoding"].(string); ok && strings.TrimSpace(raw) != "" {
+			opts.Encoding = strings.ToLower(strings.TrimSpace(raw))
+		}
+	default:
+		return opts, errors.New("options must be an object or a hash name")
+	}
+
+	switch strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(hashName), "-", "")) {
+	case "", "SHA256", "RSA2", "RSASHA256":
+		opts.Hash = crypto.SHA256
+	case "SHA1", "RSA", "RSASHA1":
+		opts.Hash = crypto.SHA1
+	default:
+		return opts, fmt.Errorf("unsupported hash %q, expected sha256 or sha1", hashName)
+	}
+	if opts.Encoding != "base64" && opts.Encoding != "hex" {
+		return opts, fmt.Errorf("unsupported encoding %q, expected hex or base64", opts.Encoding)
+	}
+	return opts, nil
+}
+
+func (o jsRSAOptions) digest(data string) []byte {
+	if o.Hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(data))
+		return sum[:]
+	}
+	sum := sha256.Sum256([]byte(data))
+	return sum[:]
+}
+
+// decodeJSKeyDER 解析 PEM；网关后台常直接给出不带头尾的 Base64 密钥，同样接受
+func decodeJSKeyDER(raw string) (string, []byte, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil, errors.New("key is empty")
+	}
+	if block, _ := pem.Decode([]byte(raw)); block != nil {
+		return block.Type, block.Bytes, nil
+	}
+	der, err := base64.StdEncoding.DecodeString(jsKeyWhitespacePattern.ReplaceAllString(raw, ""))
+	if err != nil {
+		return "", nil, errors.New("key must be PEM or base64 DER")
+	}
+	return "", der, nil
+}
+
+// parseJSRSAPrivateKey 支持 PKCS#1 与 PKCS#8
+func parseJSRSAPrivateKey(raw string) (*rsa.PrivateKey, error) {
+	_, der, err := decodeJSKeyDER(raw)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.New("unsupported private key, expected PKCS#1 or PKCS#8 RSA key")
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// parseJSRSAPublicKey 支持 PKIX、PKCS#1 公钥与 X.509 证书
+func parseJSRSAPublicKey(raw string) (*rsa.PublicKey, error) {
+	blockType, der, err := decodeJSKeyDER(raw)
+	if err != nil {
+		return nil, err
+	}
+	if blockType == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %v", err)
+		}
+		key, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("certificate does not contain an RSA key")
+		}
+		return key, nil
+	}
+	if parsed, err := x509.ParsePKIXPublicKey(der); err == nil {
+		key, ok := parsed.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("public key is not an RSA key")
+		}
+		return key, nil
+	}
+	key, err := x509.ParsePKCS1PublicKey(der)
+	if err != nil {
+		return nil, errors.New("unsupported public key, expected PKIX, PKCS#1 or certificate")
+	}
+	return key, nil
+}
+
+// createRSASign PKCS#1 v1.5 签名：(data, privateKey, options?)，默认 SHA256 + Base64
+// 密钥或参数错误时抛出异常，避免脚本带着空签名继续请求网关
+func (s *JSRuntimeService) createRSASign(vm *goja.Runtime) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		if len(call.Arguments) < 2 {
+			panic(vm.ToValue("rsaSign requires data and privateKey"))
+		}
+		opts, err := parseJSRSAOptions(call.Argument(2))
+		if err != nil {
+			panic(vm.ToValue("rsaSign: " + err.Error()))
+		}
+		key, err := parseJSRSAPrivateKey(call.Arguments[1].String())
+		if err != nil {
+			panic(vm.ToValue("rsaSign: " + err.Error()))
+		}
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, opts.Hash, opts.digest(call.Arguments[0].String()))
+		if err != nil {
+			panic(vm.ToValue("rsaSign: " + err.Error()))
+		}
+		encoded, _ := encodeJSDigest(signature, opts.Encoding)
+		return vm.ToValue(encoded)
+	}
+}
+
+// createRSAVerify 校验 PKCS#1 v1.5 签名：(data, signature, publicKey, options?)
+// 签名不匹配或无法解码时返回 false；公钥无效时抛出异常
+func (s *JSRuntimeService) createRSAVerify(vm *goja.Runtime) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		if len(call.Arguments) < 3 {
+			panic(vm.ToValue("rsaVerify requires data, signature and publicKey"))
+		}
+		opts, err := parseJSRSAOptions(call.Argument(3))
+		if err != nil {
+			panic(vm.ToValue("rsaVerify: " + err.Error()))
+		}
+		key, err := parseJSRSAPublicKey(call.Arguments[2].String())
+		if err != nil {
+			panic(vm.ToValue("rsaVerify: " + err.Error()))
+		}
+
+		rawSignature := jsKeyWhitespacePattern.ReplaceAllString(call.Arguments[1].String(), "")
+		var signature []byte
+		if opts.Encoding == "hex" {
+			signature, err = hex.DecodeString(rawSignature)
+		} else {
+			signature, err = base64.StdEncoding.DecodeString(rawSignature)
+		}
+		if err != nil || len(signature) == 0 {
+			return vm.ToValue(false)
+		}
+		return vm.ToValue(rsa.VerifyPKCS1v15(key, opts.Hash, opts.digest(call.Arguments[0].String()), signature) == nil)
+	}
+}
@@ -3,12 +3,13 @@ package service
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
 	"crypto/md5"
+	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image/color"
 	"io"
@@ -25,6 +26,7 @@ import (
 
 	"auralogic/internal/config"
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/demomode"
 	"auralogic/internal/pkg/money"
 
 	"github.com/dop251/goja"
@@ -85,7 +87,7 @@ func newPaymentHTTPClient() *http.Client {
 
 	return &http.Client{
 		Timeout:   30 * time.Second,
-		Transport: transport,
+		Transport: demomode.Transport(transport),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 5 {
 				return fmt.Errorf("stopped after too many redirects")
@@ -193,6 +195,7 @@ type JSContext struct {
 	DB              *gorm.DB
 	TestStorage     map[string]string
 	Webhook         *PaymentWebhookRequest
+	Console         *jsConsoleBuffer
 }
 
 type PaymentWebhookRequest struct {
@@ -214,6 +217,7 @@ func (s *JSRuntimeService) newJSContext(pmID uint, order *models.Order) *JSConte
 		OrderID:         0,
 		Order:           order,
 		DB:              s.db,
+		Console:         &jsConsoleBuffer{},
 	}
 	if order != nil {
 		ctx.OrderID = order.ID
@@ -240,9 +244,25 @@ func (s *JSRuntimeService) ExecutePaymentCard(pm *models.PaymentMethod, order *m
 	if pm.Script == "" {
 		return s.generateDefaultCard(pm, order)
 	}
+	return s.executePaymentCard(pm, order, s.newJSContext(pm.ID, order))
+}
 
-	vm := goja.New()
+// TestPaymentCard 执行 onGeneratePaymentCard 并返回本次执行的 console 输出（脚本测试用）
+func (s *JSRuntimeService) TestPaymentCard(pm *models.PaymentMethod, order *models.Order) (*PaymentCardResult, []models.PaymentScriptLogEntry, error) {
+	if pm.Script == "" {
+		result, err := s.generateDefaultCard(pm, order)
+		return result, []models.PaymentScriptLogEntry{}, err
+	}
 	ctx := s.newJSContext(pm.ID, order)
+	result, err := s.executePaymentCard(pm, order, ctx)
+	return result, ctx.Console.Entries(), err
+}
+
+func (s *JSRuntimeService) executePaymentCard(pm *models.PaymentMethod, order *models.Order, ctx *JSContext) (_ *PaymentCardResult, execErr error) {
+	startedAt := time.Now()
+	defer func() { s.recordScriptLog(ctx, "onGeneratePaymentCard", startedAt, execErr) }()
+
+	vm := goja.New()
 
 	// 设置超时
 	timer := time.AfterFunc(5*time.Second, func() {
@@ -277,6 +297,9 @@ func (s *JSRuntimeService) ExecutePaymentCard(pm *models.PaymentMethod, order *m
 	if err != nil {
 		return nil, fmt.Errorf("onGeneratePaymentCard error: %w", err)
 	}
+	if result, err = settleJSResult("onGeneratePaymentCard", result); err != nil {
+		return nil, err
+	}
 
 	// 解析结果
 	return s.parseCardResult(result)
@@ -301,6 +324,9 @@ func (s *JSRuntimeService) generateDefaultCard(pm *models.PaymentMethod, order *
 
 // registerAPIs 注册系统API到JS虚拟机
 func (s *JSRuntimeService) registerAPIs(vm *goja.Runtime, ctx *JSContext, pm *models.PaymentMethod) {
+	// console 输出写入执行日志
+	s.registerConsole(vm, ctx)
+
 	// 创建 AuraLogic 命名空间
 	auralogic := vm.NewObject()
 	vm.Set("AuraLogic", auralogic)
@@ -329,7 +355,14 @@ func (s *JSRuntimeService) registerAPIs(vm *goja.Runtime, ctx *JSContext, pm *mo
 	utils.Set("formatDate", s.createFormatDate(vm))
 	utils.Set("generateId", s.createGenerateId(vm))
 	utils.Set("md5", s.createMD5(vm))
+	utils.Set("sha1", s.createDigest(vm, sha1.New))
+	utils.Set("sha256", s.createDigest(vm, sha256.New))
+	utils.Set("hmacSha256", s.createHMACSHA256(vm))
+	// 早期脚本使用的名称
 	utils.Set("hmacSHA256", s.createHMACSHA256(vm))
+	utils.Set("rsaSign", s.createRSASign(vm))
+	utils.Set("rsaVerify", s.createRSAVerify(vm))
+	utils.Set("randomBytes", s.createRandomBytes(vm))
 	utils.Set("base64Encode", s.createBase64Encode(vm))
 	utils.Set("base64Decode", s.createBase64Decode(vm))
 	utils.Set("jsonEncode", s.createJSONEncode(vm))
@@ -344,21 +377,39 @@ func (s *JSRuntimeService) registerAPIs(vm *goja.Runtime, ctx *JSContext, pm *mo
 	// HTTP API
 	httpObj := vm.NewObject()
 	auralogic.Set("http", httpObj)
-	httpObj.Set("get", s.createHTTPGet(vm, ctx, pm.Name))
-	httpObj.Set("post", s.createHTTPPost(vm, ctx, pm.Name))
-	httpObj.Set("request", s.createHTTPRequest(vm, ctx, pm.Name))
+	httpObj.Set("get", s.createHTTPGet(vm, pm))
+	httpObj.Set("post", s.createHTTPPost(vm, pm))
+	httpObj.Set("request", s.createHTTPRequest(vm, pm))
+	// 返回 Promise 的版本，供 async 回调中 await
+	httpObj.Set("getAsync", wrapJSAsync(vm, s.createHTTPGet(vm, pm)))
+	httpObj.Set("postAsync", wrapJSAsync(vm, s.createHTTPPost(vm, pm)))
+	httpObj.Set("requestAsync", wrapJSAsync(vm, s.createHTTPRequest(vm, pm)))
 
 	// 配置API
 	config := vm.NewObject()
 	auralogic.Set("config", config)
 	config.Set("get", s.createConfigGet(vm, pm))
 
+	// 汇率API
+	rates := vm.NewObject()
+	auralogic.Set("rates", rates)
+	rates.Set("get", s.createRatesGet(vm))
+
+	// 付款API
+	payment := vm.NewObject()
+	auralogic.Set("payment", payment)
+	payment.Set("reserveAmount", s.createPaymentReserveAmount(vm, ctx))
+	payment.Set("reportTransaction", s.createPaymentReportTransaction(vm, ctx))
+
 	// 系统信息
 	system := vm.NewObject()
 	auralogic.Set("system", system)
 	system.Set("getTimestamp", s.createGetTimestamp(vm))
 	system.Set("getPaymentMethodInfo", s.createGetPaymentMethodInfo(vm, pm))
 	system.Set("getWebhookUrl", s.createGetWebhookURL(vm, pm))
+	system.Set("getNotifyUrl", func(call goja.FunctionCall) goja.Value {
+		return vm.ToValue(s.buildPaymentNotifyURL(pm.ID))
+	})
 }
 
 func (s *JSRuntimeService) registerWebhookAPI(vm *goja.Runtime, webhook *goja.Object, ctx *JSContext) {
@@ -758,8 +809,138 @@ func (s *JSRuntimeService) createConfigGet(vm *goja.Runtime, pm *models.PaymentM
 	}
 }
 
+// createRatesGet AuraLogic.rates.get(from, to)：返回 1 单位 from 可兑换的 to 数量
+func (s *JSRuntimeService) createRatesGet(vm *goja.Runtime) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		if len(call.Arguments) < 2 {
+			return vm.ToValue(map[string]interface{}{"error": "from and to currencies are required"})
+		}
+		ratesService := GlobalExchangeRateService()
+		if !ratesService.Enabled() {
+			return vm.ToValue(map[string]interface{}{"error": "exchange rate service is disabled"})
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), exchangeRateFetchTimeout)
+		defer cancel()
+		quote, err := ratesService.GetRate(ctx, call.Arguments[0].String(), call.Arguments[1].String())
+		if err != nil {
+			return vm.ToValue(map[string]interface{}{"error": err.Error()})
+		}
+		return vm.ToValue(map[string]interface{}{
+			"from":       quote.From,
+			"to":         quote.To,
+			"rate":       quote.Rate,
+			"provider":   quote.Provider,
+			"updated_at": quote.UpdatedAt.Format(time.RFC3339),
+			"stale":      quote.Stale,
+		})
+	}
+}
+
+// createPaymentReserveAmount 为当前订单分配唯一付款金额：reserveAmount(amount, {scope, decimals, base_decimals})
+func (s *JSRuntimeService) createPaymentReserveAmount(vm *goja.Runtime, ctx *JSContext) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		if len(call.Arguments) < 1 {
+			return vm.ToValue(map[string]interface{}{"error": "amount is required"})
+		}
+		options := PaymentAmountReserveOptions{Amount: call.Arguments[0].ToFloat()}
+		if len(call.Arguments) > 1 {
+			if raw, ok := call.Arguments[1].Export().(map[string]interface{}); ok {
+				if scope, ok := raw["scope"].(string); ok {
+					options.Scope = scope
+				}
+				if decimals, ok := toInt(raw["decimals"]); ok {
+					options.Decimals = decimals
+				}
+				if baseDecimals, ok := toInt(raw["base_decimals"]); ok {
+					options.BaseDecimals = baseDecimals
+				}
+			}
+		}
+
+		var (
+			result *PaymentAmountReservationResult
+			err    error
+		)
+		if ctx.PaymentMethodID == 0 {
+			// 脚本测试不落库，仅按订单 ID 计算尾数
+			result, err = PreviewPaymentAmount(ctx.Order, ctx.PaymentMethodID, options)
+		} else if ctx.OrderID == 0 {
+			err = fmt.Errorf("order is required")
+		} else {
+			// 重新读取订单，确保状态与付款截止时间为最新
+			var order models.Order
+			if err = s.db.First(&order, ctx.OrderID).Error; err == nil {
+				result, err = ReservePaymentAmount(s.db, &order, ctx.PaymentMethodID, options)
+			}
+		}
+		if err != nil {
+			return vm.ToValue(map[string]interface{}{"error": err.Error()})
+		}
+		return vm.ToValue(map[string]interface{}{
+			"amount":       result.Amount,
+			"amount_units": result.AmountUnits,
+			"offset":       result.Offset,
+			"decimals":     result.Decimals,
+			"scope":        result.Scope,
+			"expires_at":   result.ExpiresAt.Format(time.RFC3339),
+		})
+	}
+}
+
+// createPaymentReportTransaction 上报无法自动匹配订单的到账流水，进入后台人工对账
+func (s *JSRuntimeService) createPaymentReportTransaction(vm *goja.Runtime, ctx *JSContext) func(call goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		if len(call.Arguments) < 1 {
+			return vm.ToValue(map[string]interface{}{"error": "transaction is required"})
+		}
+		raw, ok := call.Arguments[0].Export().(map[string]interface{})
+		if !ok {
+			return vm.ToValue(map[string]interface{}{"error": "transaction must be an object"})
+		}
+		text := func(key string) string {
+			if value, exists := raw[key]; exists && value != nil {
+				return strings.TrimSpace(fmt.Sprint(value))
+			}
+			return ""
+		}
+		input := IncomingTransactionInput{
+			TransactionID: text("transaction_id"),
+			Channel:       text("channel"),
+			Amount:        text("amount"),
+			Currency:      text("currency"),
+			Memo:          text("memo"),
+			Payer:         text("payer"),
+		}
+		if receivedAt := text("received_at"); receivedAt != "" {
+			if parsed, ok := parsePaymentMatchTime(receivedAt); ok {
+				input.ReceivedAt = &parsed
+			}
+		}
+		if ctx.PaymentMethodID == 0 {
+			// 脚本测试仅校验参数，不落库
+			matchService := NewPaymentMatchService(s.db, nil)
+			if _, err := matchService.buildTransaction(input, models.IncomingTransactionSourceScript, nil); err != nil {
+				return vm.ToValue(map[string]interface{}{"error": err.Error()})
+			}
+			return vm.ToValue(map[string]interface{}{"created": false, "test": true})
+		}
+
+		pmID := ctx.PaymentMethodID
+		input.PaymentMethodID = &pmID
+		txn, created, err := NewPaymentMatchService(s.db, nil).RecordTransaction(input, models.IncomingTransactionSourceScript, nil)
+		if err != nil {
+			return vm.ToValue(map[string]interface{}{"error": err.Error()})
+		}
+		return vm.ToValue(map[string]interface{}{
+			"id":      txn.ID,
+			"created": created,
+			"status":  string(txn.Status),
+		})
+	}
+}
+
 // HTTP APIs - 支持外部网络请求
-func (s *JSRuntimeService) createHTTPGet(vm *goja.Runtime, ctx *JSContext, pmName string) func(call goja.FunctionCall) goja.Value {
+func (s *JSRuntimeService) createHTTPGet(vm *goja.Runtime, pm *models.PaymentMethod) func(call goja.FunctionCall) goja.Value {
 	return func(call goja.FunctionCall) goja.Value {
 		if len(call.Arguments) < 1 {
 			return vm.ToValue(map[string]interface{}{
@@ -792,11 +973,11 @@ func (s *JSRuntimeService) createHTTPGet(vm *goja.Runtime, ctx *JSContext, pmNam
 			}
 		}
 
-		return s.doHTTPRequest(vm, "GET", url, nil, headers, pmName)
+		return s.doHTTPRequest(vm, "GET", url, nil, headers, pm)
 	}
 }
 
-func (s *JSRuntimeService) createHTTPPost(vm *goja.Runtime, ctx *JSContext, pmName string) func(call goja.FunctionCall) goja.Value {
+func (s *JSRuntimeService) createHTTPPost(vm *goja.Runtime, pm *models.PaymentMethod) func(call goja.FunctionCall) goja.Value {
 	return func(call goja.FunctionCall) goja.Value {
 		if len(call.Arguments) < 1 {
 			return vm.ToValue(map[string]interface{}{
@@ -835,12 +1016,12 @@ func (s *JSRuntimeService) createHTTPPost(vm *goja.Runtime, ctx *JSContext, pmNa
 			}
 		}
 
-		return s.doHTTPRequest(vm, "POST", url, body, headers, pmName)
+		return s.doHTTPRequest(vm, "POST", url, body, headers, pm)
 	}
 }
 
 // createHTTPRequest 创建通用HTTP请求方法
-func (s *JSRuntimeService) createHTTPRequest(vm *goja.Runtime, ctx *JSContext, pmName string) func(call goja.FunctionCall) goja.Value {
+func (s *JSRuntimeService) createHTTPRequest(vm *goja.Runtime, pm *models.PaymentMethod) func(call goja.FunctionCall) goja.Value {
 	return func(call goja.FunctionCall) goja.Value {
 		if len(call.Arguments) < 1 {
 			return vm.ToValue(map[string]interface{}{
@@ -887,13 +1068,15 @@ func (s *JSRuntimeService) createHTTPRequest(vm *goja.Runtime, ctx *JSContext, p
 		// 解析body
 		body := optsMap["body"]
 
-		return s.doHTTPRequest(vm, method, url, body, headers, pmName)
+		return s.doHTTPRequest(vm, method, url, body, headers, pm)
 	}
 }
 
-// doHTTPRequest 执行HTTP请求
-func (s *JSRuntimeService) doHTTPRequest(vm *goja.Runtime, method, urlStr string, body interface{}, headers map[string]string, pmName string) goja.Value {
+// doHTTPRequest 执行HTTP请求（校验主机白名单，按付款方式 + 主机熔断，网关故障时快速失败）
+func (s *JSRuntimeService) doHTTPRequest(vm *goja.Runtime, method, urlStr string, body interface{}, headers map[string]string, pm *models.PaymentMethod) goja.Value {
 	start := time.Now()
+	pmName := pm.Name
+	paymentMethodID := pm.ID
 
 	parsedURL, err := url.Parse(urlStr)
 	if err != nil {
@@ -908,6 +1091,13 @@ func (s *JSRuntimeService) doHTTPRequest(vm *goja.Runtime, method, urlStr string
 			"status": 0,
 		})
 	}
+	if !paymentHTTPHostAllowed(pm, parsedURL.Hostname()) {
+		log.Printf("[%s] [%s] %s - blocked: host %q is not in the payment method allowlist", pmName, method, urlStr, parsedURL.Hostname())
+		return vm.ToValue(map[string]interface{}{
+			"error":  fmt.Sprintf("Host %s is not in the allowlist of this payment method", parsedURL.Hostname()),
+			"status": 0,
+		})
+	}
 
 	client := getPaymentHTTPClient()
 
@@ -973,20 +1163,43 @@ func (s *JSRuntimeService) doHTTPRequest(vm *goja.Runtime, method, urlStr string
 		req.Header.Set(k, v)
 	}
 
+	// 熔断检查：测试脚本（无付款方式 ID）不参与熔断
+	var breakerFailure error
+	if paymentMethodID != 0 {
+		breakerHost := parsedURL.Hostname()
+		probe, err := paymentHTTPBreakers.acquire(paymentMethodID, breakerHost)
+		if err != nil {
+			log.Printf("[%s] [%s] %s - %v", pmName, method, urlStr, err)
+			return vm.ToValue(map[string]interface{}{
+				"error":        err.Error(),
+				"status":       0,
+				"circuit_open": true,
+			})
+		}
+		defer func() {
+			paymentHTTPBreakers.complete(paymentMethodID, breakerHost, probe, breakerFailure)
+		}()
+	}
+
 	// 执行请求
 	resp, err := client.Do(req)
 	if err != nil {
+		breakerFailure = err
 		return vm.ToValue(map[string]interface{}{
 			"error":  fmt.Sprintf("Request failed: %v", err),
 			"status": 0,
 		})
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		breakerFailure = fmt.Errorf("upstream returned status %d", resp.StatusCode)
+	}
 
 	// 限制响应体大小 (最大 10MB)
 	limitedReader := io.LimitReader(resp.Body, 10*1024*1024)
 	respBody, err := io.ReadAll(limitedReader)
 	if err != nil {
+		breakerFailure = err
 		return vm.ToValue(map[string]interface{}{
 			"error":  fmt.Sprintf("Failed to read response: %v", err),
 			"status": resp.StatusCode,
@@ -1106,7 +1319,7 @@ func getConfigString(config map[string]interface{}, key, defaultVal string) stri
 }
 
 // CheckPaymentStatus 检查付款状态
-func (s *JSRuntimeService) CheckPaymentStatus(pm *models.PaymentMethod, order *models.Order) (*PaymentCheckResult, error) {
+func (s *JSRuntimeService) CheckPaymentStatus(pm *models.PaymentMethod, order *models.Order) (_ *PaymentCheckResult, execErr error) {
 	// 没有脚本时返回需要人工确认
 	if pm.Script == "" {
 		return &PaymentCheckResult{Paid: false, Message: "Payment method requires manual confirmation"}, nil
@@ -1114,6 +1327,8 @@ func (s *JSRuntimeService) CheckPaymentStatus(pm *models.PaymentMethod, order *m
 
 	vm := goja.New()
 	ctx := s.newJSContext(pm.ID, order)
+	startedAt := time.Now()
+	defer func() { s.recordScriptLog(ctx, "onCheckPaymentStatus", startedAt, execErr) }()
 
 	// 设置超时
 	timer := time.AfterFunc(10*time.Second, func() {
@@ -1149,12 +1364,15 @@ func (s *JSRuntimeService) CheckPaymentStatus(pm *models.PaymentMethod, order *m
 	if err != nil {
 		return nil, fmt.Errorf("onCheckPaymentStatus error: %w", err)
 	}
+	if result, err = settleJSResult("onCheckPaymentStatus", result); err != nil {
+		return nil, err
+	}
 
 	// 解析结果
 	return s.parseCheckResult(result)
 }
 
-func (s *JSRuntimeService) ExecuteWebhook(pm *models.PaymentMethod, req *PaymentWebhookRequest) (*PaymentWebhookResult, error) {
+func (s *JSRuntimeService) ExecuteWebhook(pm *models.PaymentMethod, req *PaymentWebhookRequest) (_ *PaymentWebhookResult, execErr error) {
 	if pm == nil {
 		return nil, fmt.Errorf("payment method is required")
 	}
@@ -1165,6 +1383,8 @@ func (s *JSRuntimeService) ExecuteWebhook(pm *models.PaymentMethod, req *Payment
 	vm := goja.New()
 	ctx := s.newJSContext(pm.ID, nil)
 	ctx.Webhook = clonePaymentWebhookRequest(req)
+	startedAt := time.Now()
+	defer func() { s.recordScriptLog(ctx, "onWebhook", startedAt, execErr) }()
 
 	timer := time.AfterFunc(10*time.Second, func() {
 		vm.Interrupt("execution timeout")
@@ -1196,6 +1416,67 @@ func (s *JSRuntimeService) ExecuteWebhook(pm *models.PaymentMethod, req *Payment
 	if err != nil {
 		return nil, fmt.Errorf("onWebhook error: %w", err)
 	}
+	if result, err = settleJSResult("onWebhook", result); err != nil {
+		return nil, err
+	}
+	return s.parseWebhookResult(result)
+}
+
+// ErrPaymentNotifyNotImplemented 付款方式脚本未实现 onPaymentNotify
+var ErrPaymentNotifyNotImplemented = errors.New("onPaymentNotify function not found")
+
+// ExecutePaymentNotify 执行网关服务端异步通知回调 onPaymentNotify(request, config)
+// request 与 AuraLogic.webhook 字段一致；只有返回 verified: true 的结果才会被视为已验签
+func (s *JSRuntimeService) ExecutePaymentNotify(pm *models.PaymentMethod, req *PaymentWebhookRequest) (_ *PaymentWebhookResult, execErr error) {
+	if pm == nil {
+		return nil, fmt.Errorf("payment method is required")
+	}
+	if pm.Script == "" {
+		return nil, fmt.Errorf("payment method has no script configured")
+	}
+
+	vm := goja.New()
+	ctx := s.newJSContext(pm.ID, nil)
+	ctx.Webhook = clonePaymentWebhookRequest(req)
+	startedAt := time.Now()
+	defer func() {
+		// 未实现 onPaymentNotify 由调用方回退处理，不算执行失败
+		if !errors.Is(execErr, ErrPaymentNotifyNotImplemented) {
+			s.recordScriptLog(ctx, "onPaymentNotify", startedAt, execErr)
+		}
+	}()
+
+	timer := time.AfterFunc(10*time.Second, func() {
+		vm.Interrupt("execution timeout")
+	})
+	defer timer.Stop()
+
+	s.registerAPIs(vm, ctx, pm)
+
+	program, err := getOrCompileJSProgram("payment_method", pm.Script)
+	if err != nil {
+		return nil, fmt.Errorf("script compile error: %w", err)
+	}
+	_, err = vm.RunProgram(program)
+	if err != nil {
+		return nil, fmt.Errorf("script execution error: %w", err)
+	}
+
+	fn, ok := goja.AssertFunction(vm.Get("onPaymentNotify"))
+	if !ok {
+		return nil, ErrPaymentNotifyNotImplemented
+	}
+
+	request := vm.NewObject()
+	s.registerWebhookAPI(vm, request, ctx)
+	configData := s.parseConfig(pm.Config)
+	result, err := fn(goja.Undefined(), request, vm.ToValue(configData))
+	if err != nil {
+		return nil, fmt.Errorf("onPaymentNotify error: %w", err)
+	}
+	if result, err = settleJSResult("onPaymentNotify", result); err != nil {
+		return nil, err
+	}
 	return s.parseWebhookResult(result)
 }
 
@@ -1234,6 +1515,7 @@ type PaymentWebhookResult struct {
 	AckHeaders    map[string]string      `json:"ack_headers,omitempty"`
 	AckBody       string                 `json:"ack_body,omitempty"`
 	Paid          bool                   `json:"paid"`
+	Verified      bool                   `json:"verified,omitempty"`
 	OrderID       uint                   `json:"order_id,omitempty"`
 	OrderNo       string                 `json:"order_no,omitempty"`
 	TransactionID string                 `json:"transaction_id,omitempty"`
@@ -1297,6 +1579,9 @@ func (s *JSRuntimeService) parseWebhookResult(result goja.Value) (*PaymentWebhoo
 		if paid, ok := v["paid"].(bool); ok {
 			out.Paid = paid
 		}
+		if verified, ok := v["verified"].(bool); ok {
+			out.Verified = verified
+		}
 		if queuePolling, ok := v["queue_polling"].(bool); ok {
 			out.QueuePolling = queuePolling
 		} else if queuePolling, ok := v["queuePolling"].(bool); ok {
@@ -1470,14 +1755,30 @@ type RefundResult struct {
 	Data          map[string]interface{} `json:"data,omitempty"`
 }
 
+// RefundRequest 部分退款参数，作为 onRefund 的第三个参数传给脚本
+type RefundRequest struct {
+	RefundID    uint // 退款记录 ID，原生网关用作幂等键
+	AmountMinor int64
+	Full        bool
+	Reason      string
+	Items       []models.OrderRefundItem
+}
+
 // ExecuteRefund 执行退款
 func (s *JSRuntimeService) ExecuteRefund(pm *models.PaymentMethod, order *models.Order) (*RefundResult, error) {
+	return s.ExecuteRefundAmount(pm, order, nil)
+}
+
+// ExecuteRefundAmount 按指定金额执行退款；request 为空时脚本收到 undefined，表示全额退款
+func (s *JSRuntimeService) ExecuteRefundAmount(pm *models.PaymentMethod, order *models.Order, request *RefundRequest) (_ *RefundResult, execErr error) {
 	if pm.Script == "" {
 		return &RefundResult{Success: false, Message: "Payment method has no script configured"}, nil
 	}
 
 	vm := goja.New()
 	ctx := s.newJSContext(pm.ID, order)
+	startedAt := time.Now()
+	defer func() { s.recordScriptLog(ctx, "onRefund", startedAt, execErr) }()
 
 	timer := time.AfterFunc(10*time.Second, func() {
 		vm.Interrupt("execution timeout")
@@ -1503,14 +1804,49 @@ func (s *JSRuntimeService) ExecuteRefund(pm *models.PaymentMethod, order *models
 	orderData := s.orderToJS(order)
 	configData := s.parseConfig(pm.Config)
 
-	result, err := fn(goja.Undefined(), vm.ToValue(orderData), vm.ToValue(configData))
+	refundData := goja.Undefined()
+	if request != nil {
+		refundData = vm.ToValue(s.refundRequestToJS(request))
+	}
+
+	result, err := fn(goja.Undefined(), vm.ToValue(orderData), vm.ToValue(configData), refundData)
 	if err != nil {
 		return nil, fmt.Errorf("onRefund error: %w", err)
 	}
+	if result, err = settleJSResult("onRefund", result); err != nil {
+		return nil, err
+	}
 
 	return s.parseRefundResult(result)
 }
 
+func (s *JSRuntimeService) refundRequestToJS(request *RefundRequest) map[string]interface{} {
+	amountMinor := request.AmountMinor
+	if !s.moneyMinorUnits {
+		amountMinor = request.AmountMinor * money.CurrencyScale
+	}
+	items := make([]map[string]interface{}, 0, len(request.Items))
+	for _, item := range request.Items {
+		itemAmountMinor := item.AmountMinor
+		if !s.moneyMinorUnits {
+			itemAmountMinor = item.AmountMinor * money.CurrencyScale
+		}
+		items = append(items, map[string]interface{}{
+			"item_index":   item.ItemIndex,
+			"sku":          item.SKU,
+			"quantity":     item.Quantity,
+			"amount_minor": itemAmountMinor,
+		})
+	}
+	return map[string]interface{}{
+		"amount_minor": amountMinor,
+		"amount":       float64(amountMinor) / float64(money.CurrencyScale),
+		"full":         request.Full,
+		"reason":       request.Reason,
+		"items":        items,
+	}
+}
+
 // parseRefundResult 解析退款结果
 func (s *JSRuntimeService) parseRefundResult(result goja.Value) (*RefundResult, error) {
 	if result == nil || goja.IsUndefined(result) || goja.IsNull(result) {
@@ -1559,13 +1895,16 @@ func (s *JSRuntimeService) createOrderGetItems(vm *goja.Runtime, ctx *JSContext)
 		var result []map[string]interface{}
 		for i, item := range ctx.Order.Items {
 			result = append(result, map[string]interface{}{
-				"index":        i,
-				"name":         item.Name,
-				"sku":          item.SKU,
-				"quantity":     item.Quantity,
-				"image_url":    item.ImageURL,
-				"product_type": item.ProductType,
-				"attributes":   item.Attributes,
+				"index":            i,
+				"name":             item.Name,
+				"sku":              item.SKU,
+				"quantity":         item.Quantity,
+				"image_url":        item.ImageURL,
+				"product_type":     item.ProductType,
+				"attributes":       item.Attributes,
+				"unit_price_minor": item.UnitPriceMinor,
+				"discount_minor":   item.DiscountMinor,
+				"line_total_minor": item.LineTotalMinor,
 			})
 		}
 		return vm.ToValue(result)
@@ -1645,20 +1984,6 @@ func (s *JSRuntimeService) createMD5(vm *goja.Runtime) func(call goja.FunctionCa
 	}
 }
 
-// createHMACSHA256 计算 HMAC-SHA256 十六进制摘要
-func (s *JSRuntimeService) createHMACSHA256(vm *goja.Runtime) func(call goja.FunctionCall) goja.Value {
-	return func(call goja.FunctionCall) goja.Value {
-		if len(call.Arguments) < 2 {
-			return vm.ToValue("")
-		}
-		payload := call.Arguments[0].String()
-		secret := call.Arguments[1].String()
-		mac := hmac.New(sha256.New, []byte(secret))
-		_, _ = mac.Write([]byte(payload))
-		return vm.ToValue(hex.EncodeToString(mac.Sum(nil)))
-	}
-}
-
 // createBase64Encode Base64编码
 func (s *JSRuntimeService) createBase64Encode(vm *goja.Runtime) func(call goja.FunctionCall) goja.Value {
 	return func(call goja.FunctionCall) goja.Value {
@@ -1865,6 +2190,7 @@ func (s *JSRuntimeService) createGetPaymentMethodInfo(vm *goja.Runtime, pm *mode
 			"icon":              pm.Icon,
 			"webhook_base_path": buildPaymentWebhookPath(pm.ID, ""),
 			"webhook_base_url":  s.buildPaymentWebhookURL(pm.ID, ""),
+			"notify_url":        s.buildPaymentNotifyURL(pm.ID),
 		})
 	}
 }
@@ -1887,6 +2213,15 @@ func (s *JSRuntimeService) buildPaymentWebhookURL(paymentMethodID uint, hook str
 	return s.publicBaseURL + path
 }
 
+// buildPaymentNotifyURL 网关服务端异步通知地址，对应 onPaymentNotify
+func (s *JSRuntimeService) buildPaymentNotifyURL(paymentMethodID uint) string {
+	path := fmt.Sprintf("/api/payments/callback/%d", paymentMethodID)
+	if s == nil || s.publicBaseURL == "" {
+		return path
+	}
+	return s.publicBaseURL + path
+}
+
 func buildPaymentWebhookPath(paymentMethodID uint, hook string) string {
 	path := fmt.Sprintf("/api/payment-methods/%d/webhooks", paymentMethodID)
 	normalizedHook := strings.Trim(strings.TrimSpace(hook), "/")
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+const paymentNotifyTestScript = `
+function onPaymentNotify(request, config) {
+  var sign = AuraLogic.utils.hmacSHA256(request.body_text, config.api_secret);
+  if (sign !== request.header('X-Sign')) {
+    return { verified: false, ack_status: 400, ack_body: 'fail' };
+  }
+  var body = request.json();
+  return {
+    verified: true,
+    paid: body.status === 'SUCCESS',
+    order_no: body.out_trade_no,
+    transaction_id: body.trade_no,
+    ack_body: 'success'
+  };
+}
+`
+
+func TestExecutePaymentNotifyVerifiesSignature(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.PaymentMethod{}, &models.PaymentMethodStorageEntry{})
+	runtime := NewJSRuntimeService(db, &config.Config{})
+	pm := &models.PaymentMethod{ID: 7, Enabled: true, Script: paymentNotifyTestScript, Config: `{"api_secret":"s3cret"}`}
+
+	body := `{"out_trade_no":"ORD-1","trade_no":"GW-9","status":"SUCCESS"}`
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(body))
+	request := &PaymentWebhookRequest{
+		Method:   "POST",
+		Headers:  map[string]string{"x-sign": hex.EncodeToString(mac.Sum(nil))},
+		BodyText: body,
+	}
+
+	result, err := runtime.ExecutePaymentNotify(pm, request)
+	if err != nil {
+		t.Fatalf("execute notify: %v", err)
+	}
+	if !result.Verified || !result.Paid || result.OrderNo != "ORD-1" || result.TransactionID != "GW-9" || result.AckBody != "success" {
+		t.Fatalf("unexpected verified result: %+v", result)
+	}
+
+	request.Headers["x-sign"] = "forged"
+	result, err = runtime.ExecutePaymentNotify(pm, request)
+	if err != nil {
+		t.Fatalf("execute notify: %v", err)
+	}
+	if result.Verified || result.Paid || result.AckStatus != 400 || result.AckBody != "fail" {
+		t.Fatalf("forged signature should not verify: %+v", result)
+	}
+
+	pm.Script = `function onCheckPaymentStatus(order, config) { return false; }`
+	if _, err := runtime.ExecutePaymentNotify(pm, request); !errors.Is(err, ErrPaymentNotifyNotImplemented) {
+		t.Fatalf("expected ErrPaymentNotifyNotImplemented, got %v", err)
+	}
+}
+
+const asyncConsoleTestScript = `
+async function onGeneratePaymentCard(order, config) {
+  console.log('generating', order.order_no, { currency: order.currency });
+  var res = await AuraLogic.http.getAsync('ftp://gateway.example.com/qr');
+  console.warn('gateway error:', res.error);
+  return { html: '<p>' + res.status + '</p>', title: 'Async' };
+}
+
+async function onCheckPaymentStatus(order, config) {
+  console.error('checking', order.order_no);
+  throw new Error('gateway down');
+}
+`
+
+func TestPaymentScriptConsoleAndAsyncCallbacks(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.PaymentMethod{}, &models.PaymentMethodStorageEntry{}, &models.PaymentMethodScriptLog{})
+	runtime := NewJSRuntimeService(db, &config.Config{})
+	order := &models.Order{ID: 3, OrderNo: "ORD-3", TotalAmount: 1000, Currency: "CNY"}
+
+	// 测试脚本（ID=0）返回输出但不落库
+	card, logs, err := runtime.TestPaymentCard(&models.PaymentMethod{Script: asyncConsoleTestScript}, order)
+	if err != nil {
+		t.Fatalf("test payment card: %v", err)
+	}
+	if card.Title != "Async" || card.HTML != "<p>0</p>" {
+		t.Fatalf("async result should be awaited, got %+v", card)
+	}
+	if len(logs) != 2 || logs[0].Level != "info" || logs[1].Level != "warn" {
+		t.Fatalf("unexpected console entries: %+v", logs)
+	}
+	if logs[0].Message != `generating ORD-3 {"currency":"CNY"}` || logs[1].Message != "gateway error: URL must start with http:// or https://" {
+		t.Fatalf("unexpected console messages: %q, %q", logs[0].Message, logs[1].Message)
+	}
+	var count int64
+	db.Model(&models.PaymentMethodScriptLog{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("test executions should not be persisted, got %d", count)
+	}
+
+	pm := &models.PaymentMethod{ID: 5, Enabled: true, Script: asyncConsoleTestScript}
+	if _, err := runtime.ExecutePaymentCard(pm, order); err != nil {
+		t.Fatalf("execute payment card: %v", err)
+	}
+	if _, err := runtime.CheckPaymentStatus(pm, order); err == nil {
+		t.Fatalf("rejected promise should surface as an error")
+	}
+
+	svc := &PaymentMethodService{db: db}
+	stored, total, err := svc.ListScriptLogs(pm.ID, "", 1, 20)
+	if err != nil || total != 2 {
+		t.Fatalf("expected two persisted logs, got %d, %v", total, err)
+	}
+	failed := stored[0]
+	if failed.Callback != "onCheckPaymentStatus" || failed.Success || failed.OrderID == nil || *failed.OrderID != order.ID {
+		t.Fatalf("unexpected failed log: %+v", failed)
+	}
+	if len(failed.Entries) != 1 || failed.Entries[0].Level != "error" || failed.Error != "onCheckPaymentStatus rejected: Error: gateway down" {
+		t.Fatalf("unexpected failed log entries: %+v, %q", failed.Entries, failed.Error)
+	}
+	if !stored[1].Success || len(stored[1].Entries) != 2 {
+		t.Fatalf("unexpected card log: %+v", stored[1])
+	}
+	if filtered, total, _ := svc.ListScriptLogs(pm.ID, "onGeneratePaymentCard", 1, 20); total != 1 || filtered[0].Callback != "onGeneratePaymentCard" {
+		t.Fatalf("callback filter should apply, got %d", total)
+	}
+}
+
+const cryptoUtilsTestScript = `
+function onGeneratePaymentCard(order, config) {
+  var u = AuraLogic.utils;
+  var sign = u.rsaSign(order.order_no, config.private_key);
+  var sha1Sign = u.rsaSign(order.order_no, config.private_key, { hash: 'sha1', encoding: 'hex' });
+  var invalidKey = '';
+  try {
+    u.rsaSign('x', 'not a key');
+  } catch (e) {
+    invalidKey = String(e);
+  }
+  return {
+    title: 'Crypto',
+    html: '',
+    data: {
+      sha256: u.sha256('hello'),
+      sha1: u.sha1('hello', 'base64'),
+      hmac: u.hmacSha256('hello', 'key'),
+      hmac_legacy: u.hmacSHA256('hello', 'key'),
+      nonce_length: u.randomBytes(16).length,
+      verified: u.rsaVerify(order.order_no, sign, config.public_key),
+      verified_sha1: u.rsaVerify(order.order_no, sha1Sign, config.public_key, { hash: 'sha1', encoding: 'hex' }),
+      tampered: u.rsaVerify(order.order_no + 'x', sign, config.public_key),
+      invalid_key: invalidKey
+    }
+  };
+}
+`
+
+func TestPaymentScriptCryptoUtils(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	// 公钥使用不带 PEM 头尾的 Base64 形式
+	cfg, _ := json.Marshal(map[string]string{
+		"private_key": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		"public_key":  base64.StdEncoding.EncodeToString(publicDER),
+	})
+
+	db := openConcurrentServiceTestDB(t, &models.PaymentMethod{}, &models.PaymentMethodStorageEntry{})
+	runtime := NewJSRuntimeService(db, &config.Config{})
+	order := &models.Order{ID: 9, OrderNo: "ORD-9", TotalAmount: 100, Currency: "CNY"}
+	card, _, err := runtime.TestPaymentCard(&models.PaymentMethod{Script: cryptoUtilsTestScript, Config: string(cfg)}, order)
+	if err != nil {
+		t.Fatalf("test payment card: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte("hello"))
+	wantHMAC := hex.EncodeToString(mac.Sum(nil))
+	data := card.Data
+	if data["sha256"] != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" || data["sha1"] != "qvTGHdzF6KLavt4PO0gs2a6pQ00=" {
+		t.Fatalf("unexpected digests: %v, %v", data["sha256"], data["sha1"])
+	}
+	if data["hmac"] != wantHMAC || data["hmac_legacy"] != wantHMAC {
+		t.Fatalf("unexpected hmac: %v, %v", data["hmac"], data["hmac_legacy"])
+	}
+	if data["nonce_length"] != int64(32) {
+		t.Fatalf("randomBytes(16) should return 32 hex chars, got %v", data["nonce_length"])
+	}
+	if data["verified"] != true || data["verified_sha1"] != true || data["tampered"] != false {
+		t.Fatalf("unexpected verify results: %v", data)
+	}
+	if !strings.Contains(fmt.Sprint(data["invalid_key"]), "rsaSign: key must be PEM or base64 DER") {
+		t.Fatalf("invalid key should throw, got %v", data["invalid_key"])
+	}
+}
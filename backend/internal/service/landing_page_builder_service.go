@@ -0,0 +1,640 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/money"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	LandingBlockHero            = "hero"
+	LandingBlockProductGrid     = "product_grid"
+	LandingBlockFAQ             = "faq"
+	LandingBlockAnnouncementBar = "announcement_bar"
+
+	maxLandingBlocks          = 50
+	maxLandingGridProducts    = 24
+	defaultLandingGridLimit   = 8
+	maxLandingFAQItems        = 50
+	maxLandingBlockTextLength = 2000
+)
+
+var ErrLandingRevisionNotFound = bizerr.New("landingPage.revisionNotFound", "Landing page revision not found")
+
+// LandingHeroProps 首屏横幅
+type LandingHeroProps struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	CTAText  string `json:"cta_text,omitempty"`
+	CTAURL   string `json:"cta_url,omitempty"`
+	Align    string `json:"align,omitempty"` // left | center
+}
+
+// LandingProductGridProps 商品网格，source=featured/recommended 时绑定精选/推荐商品
+type LandingProductGridProps struct {
+	Title      string `json:"title,omitempty"`
+	Source     string `json:"source,omitempty"` // featured | recommended | manual
+	ProductIDs []uint `json:"product_ids,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	ShowPrice  *bool  `json:"show_price,omitempty"`
+}
+
+// LandingFAQItem 常见问题条目
+type LandingFAQItem struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// LandingFAQProps 常见问题
+type LandingFAQProps struct {
+	Title string           `json:"title,omitempty"`
+	Items []LandingFAQItem `json:"items"`
+}
+
+// LandingAnnouncementBarProps 顶部公告条
+type LandingAnnouncementBarProps struct {
+	Text     string `json:"text"`
+	LinkText string `json:"link_text,omitempty"`
+	LinkURL  string `json:"link_url,omitempty"`
+	Tone     string `json:"tone,omitempty"` // info | success | warning
+}
+
+// LandingRenderContext 渲染所需的站点信息
+type LandingRenderContext struct {
+	AppName      string
+	AppURL       string
+	LogoURL      string
+	PrimaryColor string
+	Currency     string
+	StoreID      uint
+}
+
+type landingRenderedProduct struct {
+	ID       uint
+	Name     string
+	Summary  string
+	ImageURL string
+	Price    string
+	URL      string
+}
+
+type landingRenderedBlock struct {
+	ID              string
+	Type            string
+	Hero            *LandingHeroProps
+	ProductGrid     *LandingProductGridProps
+	Products        []landingRenderedProduct
+	ShowPrice       bool
+	FAQ             *LandingFAQProps
+	AnnouncementBar *LandingAnnouncementBarProps
+}
+
+// LandingPageBuilderService 区块式落地页：草稿版本、发布与服务端渲染
+type LandingPageBuilderService struct {
+	db *gorm.DB
+}
+
+func NewLandingPageBuilderService(db *gorm.DB) *LandingPageBuilderService {
+	return &LandingPageBuilderService{db: db}
+}
+
+func landingBlockError(key, message string, index int, blockType, field string) error {
+	return bizerr.New(key, message).WithParams(map[string]interface{}{
+		"index": index + 1,
+		"type":  blockType,
+		"field": field,
+	})
+}
+
+func isSafeLandingURL(raw string) bool {
+	if raw == "" {
+		return true
+	}
+	lower := strings.ToLower(raw)
+	if strings.HasPrefix(lower, "//") {
+		return false
+	}
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "/")
+}
+
+func decodeLandingProps(raw json.RawMessage, target interface{}) error {
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
+
+func newLandingBlockID() string {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("b%d", time.Now().UnixNano())
+	}
+	return "b" + hex.EncodeToString(buf)
+}
+
+// NormalizeLandingBlocks 校验区块结构并规范化 Props（补全 ID、默认值）
+func NormalizeLandingBlocks(blocks []models.LandingBlock) ([]models.LandingBlock, error) {
+	if len(blocks) > maxLandingBlocks {
+		return nil, bizerr.Newf("landingPage.tooManyBlocks", "A landing page can contain at most %d blocks", maxLandingBlocks).
+			WithParams(map[string]interface{}{"max": maxLandingBlocks})
+	}
+
+	result := make([]models.LandingBlock, 0, len(blocks))
+	seenIDs := make(map[string]struct{}, len(blocks))
+	for i, block := range blocks {
+		block.Type = strings.TrimSpace(block.Type)
+		block.ID = strings.TrimSpace(block.ID)
+		if block.ID == "" || len(block.ID) > 64 {
+			block.ID = newLandingBlockID()
+		}
+		if _, exists := seenIDs[block.ID]; exists {
+			block.ID = newLandingBlockID()
+		}
+		seenIDs[block.ID] = struct{}{}
+
+		props, err := normalizeLandingBlockProps(i, block)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(props)
+		if err != nil {
+			return nil, err
+		}
+		block.Props = encoded
+		result = append(result, block)
+	}
+	return result, nil
+}
+
+func normalizeLandingBlockProps(index int, block models.LandingBlock) (interface{}, error) {
+	invalidProps := func() error {
+		return landingBlockError("landingPage.blockPropsInvalid", "Invalid block properties", index, block.Type, "")
+	}
+	required := func(field string) error {
+		return landingBlockError("landingPage.blockFieldRequired", "Block field is required", index, block.Type, field)
+	}
+	invalidURL := func(field string) error {
+		return landingBlockError("landingPage.blockURLInvalid", "Block URL must be http(s) or a site-relative path", index, block.Type, field)
+	}
+	tooLong := func(field string) error {
+		return landingBlockError("landingPage.blockFieldTooLong", "Block field is too long", index, block.Type, field)
+	}
+
+	switch block.Type {
+	case LandingBlockHero:
+		var props LandingHeroProps
+		if err := decodeLandingProps(block.Props, &props); err != nil {
+			return nil, invalidProps()
+		}
+		props.Title = strings.TrimSpace(props.Title)
+		if props.Title == "" {
+			return nil, required("title")
+		}
+		if len(props.Title) > maxLandingBlockTextLength || len(props.Subtitle) > maxLandingBlockTextLength {
+			return nil, tooLong("subtitle")
+		}
+		props.ImageURL = strings.TrimSpace(props.ImageURL)
+		props.CTAURL = strings.TrimSpace(props.CTAURL)
+		if !isSafeLandingURL(props.ImageURL) {
+			return nil, invalidURL("image_url")
+		}
+		if !isSafeLandingURL(props.CTAURL) {
+			return nil, invalidURL("cta_url")
+		}
+		if props.Align != "left" {
+			props.Align = "center"
+		}
+		return props, nil
+	case LandingBlockProductGrid:
+		var props LandingProductGridProps
+		if err := decodeLandingProps(block.Props, &props); err != nil {
+			return nil, invalidProps()
+		}
+		switch props.Source {
+		case "":
+			props.Source = "featured"
+		case "featured", "recommended":
+		case "manual":
+			if len(props.ProductIDs) == 0 {
+				return nil, required("product_ids")
+			}
+		default:
+			return nil, invalidProps()
+		}
+		if props.Source != "manual" {
+			props.ProductIDs = nil
+		}
+		if len(props.ProductIDs) > maxLandingGridProducts {
+			props.ProductIDs = props.ProductIDs[:maxLandingGridProducts]
+		}
+		if props.Limit <= 0 {
+			props.Limit = defaultLandingGridLimit
+		}
+		if props.Limit > maxLandingGridProducts {
+			props.Limit = maxLandingGridProducts
+		}
+		return props, nil
+	case LandingBlockFAQ:
+		var props LandingFAQProps
+		if err := decodeLandingProps(block.Props, &props); err != nil {
+			return nil, invalidProps()
+		}
+		if len(props.Items) == 0 {
+			return nil, required("items")
+		}
+		if len(props.Items) > maxLandingFAQItems {
+			return nil, tooLong("items")
+		}
+		for i := range props.Items {
+			props.Items[i].Question = strings.TrimSpace(props.Items[i].Question)
+			props.Items[i].Answer = strings.TrimSpace(props.Items[i].Answer)
+			if props.Items[i].Question == "" || props.Items[i].Answer == "" {
+				return nil, required(fmt.Sprintf("items[%d]", i))
+			}
+			if len(props.Items[i].Answer) > maxLandingBlockTextLength {
+				return nil, tooLong(fmt.Sprintf("items[%d].answer", i))
+			}
+		}
+		return props, nil
+	case LandingBlockAnnouncementBar:
+		var props LandingAnnouncementBarProps
+		if err := decodeLandingProps(block.Props, &props); err != nil {
+			return nil, invalidProps()
+		}
+		props.Text = strings.TrimSpace(props.Text)
+		if props.Text == "" {
+			return nil, required("text")
+		}
+		if len(props.Text) > maxLandingBlockTextLength {
+			return nil, tooLong("text")
+		}
+		props.LinkURL = strings.TrimSpace(props.LinkURL)
+		if !isSafeLandingURL(props.LinkURL) {
+			return nil, invalidURL("link_url")
+		}
+		switch props.Tone {
+		case "success", "warning":
+		default:
+			props.Tone = "info"
+		}
+		return props, nil
+	default:
+		return nil, landingBlockError("landingPage.blockTypeInvalid", "Unsupported block type", index, block.Type, "type")
+	}
+}
+
+// LandingBlockSchema 区块 JSON Schema（供编辑器校验与表单生成）
+func LandingBlockSchema() map[string]interface{} {
+	str := map[string]interface{}{"type": "string"}
+	url := map[string]interface{}{"type": "string", "description": "http(s) URL or site-relative path"}
+	blockVariant := func(blockType string, props map[string]interface{}, required []string) map[string]interface{} {
+		propsSchema := map[string]interface{}{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+		if len(required) > 0 {
+			propsSchema["required"] = required
+		}
+		return map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id":    map[string]interface{}{"type": "string", "maxLength": 64},
+				"type":  map[string]interface{}{"const": blockType},
+				"props": propsSchema,
+			},
+			"required": []string{"type", "props"},
+		}
+	}
+
+	return map[string]interface{}{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"$id":      "auralogic:landing-page-blocks",
+		"type":     "array",
+		"maxItems": maxLandingBlocks,
+		"items": map[string]interface{}{
+			"oneOf": []interface{}{
+				blockVariant(LandingBlockHero, map[string]interface{}{
+					"title":     str,
+					"subtitle":  str,
+					"image_url": url,
+					"cta_text":  str,
+					"cta_url":   url,
+					"align":     map[string]interface{}{"enum": []string{"left", "center"}},
+				}, []string{"title"}),
+				blockVariant(LandingBlockProductGrid, map[string]interface{}{
+					"title":       str,
+					"source":      map[string]interface{}{"enum": []string{"featured", "recommended", "manual"}},
+					"product_ids": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer", "minimum": 1}, "maxItems": maxLandingGridProducts},
+					"limit":       map[string]interface{}{"type": "integer", "minimum": 1, "maximum": maxLandingGridProducts},
+					"show_price":  map[string]interface{}{"type": "boolean"},
+				}, nil),
+				blockVariant(LandingBlockFAQ, map[string]interface{}{
+					"title": str,
+					"items": map[string]interface{}{
+						"type":     "array",
+						"minItems": 1,
+						"maxItems": maxLandingFAQItems,
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"question": str,
+								"answer":   str,
+							},
+							"required": []string{"question", "answer"},
+						},
+					},
+				}, []string{"items"}),
+				blockVariant(LandingBlockAnnouncementBar, map[string]interface{}{
+					"text":      str,
+					"link_text": str,
+					"link_url":  url,
+					"tone":      map[string]interface{}{"enum": []string{"info", "success", "warning"}},
+				}, []string{"text"}),
+			},
+		},
+	}
+}
+
+// ListRevisions 获取落地页版本列表（新版本在前）
+func (s *LandingPageBuilderService) ListRevisions(slug string) ([]models.LandingPageRevision, error) {
+	var revisions []models.LandingPageRevision
+	err := s.db.Where("page_slug = ?", slug).Order("version DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+// GetRevision 获取版本详情
+func (s *LandingPageBuilderService) GetRevision(id uint) (*models.LandingPageRevision, error) {
+	var revision models.LandingPageRevision
+	if err := s.db.First(&revision, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLandingRevisionNotFound
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// SaveDraft 保存区块草稿，每次保存生成新版本号
+func (s *LandingPageBuilderService) SaveDraft(slug string, blocks []models.LandingBlock, note string, adminID uint) (*models.LandingPageRevision, error) {
+	normalized, err := NormalizeLandingBlocks(blocks)
+	if err != nil {
+		return nil, err
+	}
+	note = strings.TrimSpace(note)
+	if len(note) > 255 {
+		note = note[:255]
+	}
+
+	revision := &models.LandingPageRevision{
+		PageSlug:  slug,
+		Blocks:    normalized,
+		Status:    models.LandingRevisionStatusDraft,
+		Note:      note,
+		CreatedBy: adminID,
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var maxVersion int
+		if err := tx.Model(&models.LandingPageRevision{}).
+			Where("page_slug = ?", slug).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&maxVersion).Error; err != nil {
+			return err
+		}
+		revision.Version = maxVersion + 1
+		return tx.Create(revision).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// Publish 发布指定版本：旧的已发布版本归档，落地页切换为区块模式
+func (s *LandingPageBuilderService) Publish(revisionID uint, adminID uint) (*models.LandingPageRevision, error) {
+	revision, err := s.GetRevision(revisionID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.LandingPageRevision{}).
+			Where("page_slug = ? AND status = ? AND id <> ?", revision.PageSlug, models.LandingRevisionStatusPublished, revision.ID).
+			Update("status", models.LandingRevisionStatusArchived).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.LandingPageRevision{}).Where("id = ?", revision.ID).Updates(map[string]interface{}{
+			"status":       models.LandingRevisionStatusPublished,
+			"published_by": adminID,
+			"published_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		var page models.LandingPage
+		err := tx.Where("slug = ?", revision.PageSlug).First(&page).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			page = models.LandingPage{
+				Slug:                revision.PageSlug,
+				Mode:                models.LandingPageModeBlocks,
+				PublishedRevisionID: &revision.ID,
+				IsActive:            true,
+				UpdatedBy:           adminID,
+			}
+			return tx.Create(&page).Error
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.LandingPage{}).Where("id = ?", page.ID).Updates(map[string]interface{}{
+			"mode":                  models.LandingPageModeBlocks,
+			"published_revision_id": revision.ID,
+			"updated_by":            adminID,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetRevision(revision.ID)
+}
+
+// loadGridProducts 加载商品网格数据，仅展示上架商品并遵循店铺范围
+func (s *LandingPageBuilderService) loadGridProducts(props *LandingProductGridProps, storeID uint) ([]models.Product, error) {
+	query := repository.StorefrontScope(storeID).Apply(s.db.Model(&models.Product{})).
+		Where("status = ?", models.ProductStatusActive)
+
+	var products []models.Product
+	switch props.Source {
+	case "manual":
+		if err := query.Where("id IN ?", props.ProductIDs).Find(&products).Error; err != nil {
+			return nil, err
+		}
+		// 按配置顺序排列
+		byID := make(map[uint]models.Product, len(products))
+		for _, product := range products {
+			byID[product.ID] = product
+		}
+		ordered := make([]models.Product, 0, len(products))
+		for _, id := range props.ProductIDs {
+			if product, ok := byID[id]; ok {
+				ordered = append(ordered, product)
+			}
+		}
+		if len(ordered) > props.Limit {
+			ordered = ordered[:props.Limit]
+		}
+		return ordered, nil
+	case "recommended":
+		query = query.Where("is_recommended = ?", true)
+	default:
+		query = query.Where("is_featured = ?", true)
+	}
+	err := query.Order("sort_order DESC, created_at DESC").Limit(props.Limit).Find(&products).Error
+	return products, err
+}
+
+func buildLandingRenderedProduct(product models.Product, currency string) landingRenderedProduct {
+	rendered := landingRenderedProduct{
+		ID:       product.ID,
+		Name:     product.Name,
+		Summary:  product.ShortDescription,
+		ImageURL: product.GetPrimaryImage(),
+		URL:      fmt.Sprintf("/products/%d", product.ID),
+	}
+	// 落地页为公开静态内容，询价商品不展示价格
+	if !product.PriceOnRequest {
+		rendered.Price = strings.TrimSpace(currency + " " + money.MinorToString(product.Price))
+	}
+	return rendered
+}
+
+// Render 服务端渲染区块为完整 HTML 页面
+func (s *LandingPageBuilderService) Render(blocks []models.LandingBlock, ctx LandingRenderContext) (string, error) {
+	normalized, err := NormalizeLandingBlocks(blocks)
+	if err != nil {
+		return "", err
+	}
+
+	rendered := make([]landingRenderedBlock, 0, len(normalized))
+	for _, block := range normalized {
+		item := landingRenderedBlock{ID: block.ID, Type: block.Type}
+		switch block.Type {
+		case LandingBlockHero:
+			item.Hero = &LandingHeroProps{}
+			_ = json.Unmarshal(block.Props, item.Hero)
+		case LandingBlockProductGrid:
+			item.ProductGrid = &LandingProductGridProps{}
+			_ = json.Unmarshal(block.Props, item.ProductGrid)
+			item.ShowPrice = item.ProductGrid.ShowPrice == nil || *item.ProductGrid.ShowPrice
+			products, err := s.loadGridProducts(item.ProductGrid, ctx.StoreID)
+			if err != nil {
+				return "", err
+			}
+			for _, product := range products {
+				item.Products = append(item.Products, buildLandingRenderedProduct(product, ctx.Currency))
+			}
+		case LandingBlockFAQ:
+			item.FAQ = &LandingFAQProps{}
+			_ = json.Unmarshal(block.Props, item.FAQ)
+		case LandingBlockAnnouncementBar:
+			item.AnnouncementBar = &LandingAnnouncementBarProps{}
+			_ = json.Unmarshal(block.Props, item.AnnouncementBar)
+		}
+		rendered = append(rendered, item)
+	}
+
+	var buf bytes.Buffer
+	if err := landingBlocksTemplate.Execute(&buf, map[string]interface{}{
+		"AppName":      ctx.AppName,
+		"AppURL":       ctx.AppURL,
+		"LogoURL":      ctx.LogoURL,
+		"PrimaryColor": ctx.PrimaryColor,
+		"Year":         time.Now().Year(),
+		"Blocks":       rendered,
+	}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderPublished 渲染落地页当前已发布的区块版本
+func (s *LandingPageBuilderService) RenderPublished(page *models.LandingPage, ctx LandingRenderContext) (string, error) {
+	if page == nil || page.PublishedRevisionID == nil {
+		return "", ErrLandingRevisionNotFound
+	}
+	revision, err := s.GetRevision(*page.PublishedRevisionID)
+	if err != nil {
+		return "", err
+	}
+	return s.Render(revision.Blocks, ctx)
+}
+
+var landingBlocksTemplate = template.Must(template.New("landing_blocks").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.AppName}}</title>
+<style>
+:root{--primary:{{.PrimaryColor}}}
+*{box-sizing:border-box}
+body{margin:0;font-family:system-ui,-apple-system,"Segoe UI",sans-serif;color:#111827;background:#fff;line-height:1.6}
+a{color:inherit}
+.al-container{max-width:1120px;margin:0 auto;padding:0 20px}
+.al-header{display:flex;align-items:center;justify-content:space-between;padding:16px 20px;max-width:1120px;margin:0 auto}
+.al-header img{height:36px}
+.al-btn{display:inline-block;padding:12px 24px;border-radius:8px;background:var(--primary);color:#fff;text-decoration:none;font-weight:600}
+.al-bar{padding:10px 20px;text-align:center;font-size:14px}
+.al-bar-info{background:#eff6ff;color:#1e3a8a}.al-bar-success{background:#ecfdf5;color:#065f46}.al-bar-warning{background:#fffbeb;color:#92400e}
+.al-hero{padding:72px 0}
+.al-hero-center{text-align:center}
+.al-hero h1{font-size:40px;line-height:1.2;margin:0 0 16px}
+.al-hero p{font-size:18px;color:#4b5563;margin:0 0 24px}
+.al-hero img{max-width:100%;border-radius:12px;margin-top:32px}
+.al-section{padding:48px 0}
+.al-section h2{font-size:28px;margin:0 0 24px}
+.al-grid{display:grid;grid-template-columns:repeat(auto-fill,minmax(220px,1fr));gap:20px}
+.al-card{border:1px solid #e5e7eb;border-radius:12px;overflow:hidden;text-decoration:none;display:block}
+.al-card img{width:100%;aspect-ratio:1/1;object-fit:cover;background:#f3f4f6}
+.al-card-body{padding:12px 16px}
+.al-card-body h3{font-size:16px;margin:0 0 4px}
+.al-card-body p{font-size:13px;color:#6b7280;margin:0 0 8px}
+.al-price{font-weight:700}
+.al-faq details{border-bottom:1px solid #e5e7eb;padding:16px 0}
+.al-faq summary{cursor:pointer;font-weight:600}
+.al-footer{padding:32px 20px;text-align:center;color:#6b7280;font-size:13px}
+</style>
+</head>
+<body>
+{{range .Blocks}}{{if .AnnouncementBar}}<div class="al-bar al-bar-{{.AnnouncementBar.Tone}}" data-block-id="{{.ID}}">{{.AnnouncementBar.Text}}{{if .AnnouncementBar.LinkURL}} <a href="{{.AnnouncementBar.LinkURL}}">{{if .AnnouncementBar.LinkText}}{{.AnnouncementBar.LinkText}}{{else}}&rarr;{{end}}</a>{{end}}</div>
+{{end}}{{end}}<header class="al-header">{{if .LogoURL}}<img src="{{.LogoURL}}" alt="{{.AppName}}">{{else}}<strong>{{.AppName}}</strong>{{end}}<a href="/login">Login</a></header>
+<main>
+{{range .Blocks}}{{if .Hero}}<section class="al-hero{{if eq .Hero.Align "center"}} al-hero-center{{end}}" data-block-id="{{.ID}}"><div class="al-container">
+<h1>{{.Hero.Title}}</h1>{{if .Hero.Subtitle}}<p>{{.Hero.Subtitle}}</p>{{end}}{{if .Hero.CTAURL}}<a class="al-btn" href="{{.Hero.CTAURL}}">{{if .Hero.CTAText}}{{.Hero.CTAText}}{{else}}Shop now{{end}}</a>{{end}}{{if .Hero.ImageURL}}<img src="{{.Hero.ImageURL}}" alt="">{{end}}
+</div></section>
+{{else if .ProductGrid}}<section class="al-section" data-block-id="{{.ID}}"><div class="al-container">{{if .ProductGrid.Title}}<h2>{{.ProductGrid.Title}}</h2>{{end}}<div class="al-grid">
+{{$showPrice := .ShowPrice}}{{range .Products}}<a class="al-card" href="{{.URL}}">{{if .ImageURL}}<img src="{{.ImageURL}}" alt="{{.Name}}" loading="lazy">{{end}}<div class="al-card-body"><h3>{{.Name}}</h3>{{if .Summary}}<p>{{.Summary}}</p>{{end}}{{if and $showPrice .Price}}<span class="al-price">{{.Price}}</span>{{end}}</div></a>
+{{end}}</div></div></section>
+{{else if .FAQ}}<section class="al-section al-faq" data-block-id="{{.ID}}"><div class="al-container">{{if .FAQ.Title}}<h2>{{.FAQ.Title}}</h2>{{end}}
+{{range .FAQ.Items}}<details><summary>{{.Question}}</summary><p>{{.Answer}}</p></details>
+{{end}}</div></section>
+{{end}}{{end}}</main>
+<footer class="al-footer">&copy; {{.Year}} {{.AppName}}</footer>
+</body>
+</html>
+`))
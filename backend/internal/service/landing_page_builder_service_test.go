@@ -0,0 +1,149 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"auralogic/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newLandingPageBuilderServiceForTest(t *testing.T) *LandingPageBuilderService {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.LandingPage{}, &models.LandingPageRevision{}, &models.Product{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	return NewLandingPageBuilderService(db)
+}
+
+func landingBlock(blockType string, props string) models.LandingBlock {
+	return models.LandingBlock{Type: blockType, Props: json.RawMessage(props)}
+}
+
+func TestNormalizeLandingBlocksValidation(t *testing.T) {
+	blocks, err := NormalizeLandingBlocks([]models.LandingBlock{
+		landingBlock(LandingBlockHero, `{"title":"  Hello  "}`),
+		landingBlock(LandingBlockProductGrid, `{}`),
+	})
+	if err != nil {
+		t.Fatalf("normalize blocks: %v", err)
+	}
+	if blocks[0].ID == "" || blocks[0].ID == blocks[1].ID {
+		t.Fatalf("expected unique generated block ids, got %q and %q", blocks[0].ID, blocks[1].ID)
+	}
+	var grid LandingProductGridProps
+	if err := json.Unmarshal(blocks[1].Props, &grid); err != nil {
+		t.Fatalf("decode grid props: %v", err)
+	}
+	if grid.Source != "featured" || grid.Limit != defaultLandingGridLimit {
+		t.Fatalf("expected grid defaults, got %+v", grid)
+	}
+
+	_, err = NormalizeLandingBlocks([]models.LandingBlock{landingBlock("carousel", `{}`)})
+	requireProductBizErr(t, err, "landingPage.blockTypeInvalid")
+	_, err = NormalizeLandingBlocks([]models.LandingBlock{landingBlock(LandingBlockHero, `{"subtitle":"x"}`)})
+	requireProductBizErr(t, err, "landingPage.blockFieldRequired")
+	_, err = NormalizeLandingBlocks([]models.LandingBlock{landingBlock(LandingBlockHero, `{"title":"x","color":"red"}`)})
+	requireProductBizErr(t, err, "landingPage.blockPropsInvalid")
+	_, err = NormalizeLandingBlocks([]models.LandingBlock{landingBlock(LandingBlockAnnouncementBar, `{"text":"x","link_url":"javascript:alert(1)"}`)})
+	requireProductBizErr(t, err, "landingPage.blockURLInvalid")
+	_, err = NormalizeLandingBlocks([]models.LandingBlock{landingBlock(LandingBlockFAQ, `{"items":[]}`)})
+	requireProductBizErr(t, err, "landingPage.blockFieldRequired")
+	_, err = NormalizeLandingBlocks(make([]models.LandingBlock, maxLandingBlocks+1))
+	requireProductBizErr(t, err, "landingPage.tooManyBlocks")
+}
+
+func TestLandingPageBuilderDraftPublishWorkflow(t *testing.T) {
+	svc := newLandingPageBuilderServiceForTest(t)
+
+	first, err := svc.SaveDraft("home", []models.LandingBlock{landingBlock(LandingBlockHero, `{"title":"v1"}`)}, "first", 1)
+	if err != nil {
+		t.Fatalf("save draft: %v", err)
+	}
+	second, err := svc.SaveDraft("home", []models.LandingBlock{landingBlock(LandingBlockHero, `{"title":"v2"}`)}, "", 1)
+	if err != nil {
+		t.Fatalf("save draft: %v", err)
+	}
+	if first.Version != 1 || second.Version != 2 || second.Status != models.LandingRevisionStatusDraft {
+		t.Fatalf("unexpected revisions: v%d v%d status=%s", first.Version, second.Version, second.Status)
+	}
+
+	if _, err := svc.Publish(first.ID, 1); err != nil {
+		t.Fatalf("publish first: %v", err)
+	}
+	published, err := svc.Publish(second.ID, 2)
+	if err != nil {
+		t.Fatalf("publish second: %v", err)
+	}
+	if published.Status != models.LandingRevisionStatusPublished || published.PublishedBy == nil || *published.PublishedBy != 2 {
+		t.Fatalf("expected published revision, got %+v", published)
+	}
+	archived, err := svc.GetRevision(first.ID)
+	if err != nil {
+		t.Fatalf("get first revision: %v", err)
+	}
+	if archived.Status != models.LandingRevisionStatusArchived {
+		t.Fatalf("expected previous revision archived, got %s", archived.Status)
+	}
+
+	var page models.LandingPage
+	if err := svc.db.Where("slug = ?", "home").First(&page).Error; err != nil {
+		t.Fatalf("load landing page: %v", err)
+	}
+	if page.Mode != models.LandingPageModeBlocks || page.PublishedRevisionID == nil || *page.PublishedRevisionID != second.ID {
+		t.Fatalf("expected page in block mode pointing to v2, got mode=%s revision=%v", page.Mode, page.PublishedRevisionID)
+	}
+
+	html, err := svc.RenderPublished(&page, LandingRenderContext{AppName: "Shop", PrimaryColor: "#112233"})
+	if err != nil {
+		t.Fatalf("render published: %v", err)
+	}
+	if !strings.Contains(html, "<h1>v2</h1>") {
+		t.Fatalf("expected rendered v2 hero, got:\n%s", html)
+	}
+
+	_, err = svc.Publish(9999, 1)
+	requireProductBizErr(t, err, "landingPage.revisionNotFound")
+}
+
+func TestLandingPageBuilderRenderProductGridAndEscaping(t *testing.T) {
+	svc := newLandingPageBuilderServiceForTest(t)
+
+	products := []models.Product{
+		{SKU: "F-1", Name: "Featured One", Price: 1999, Status: models.ProductStatusActive, IsFeatured: true},
+		{SKU: "F-2", Name: "Hidden Draft", Price: 500, Status: models.ProductStatusDraft, IsFeatured: true},
+		{SKU: "N-1", Name: "Plain", Price: 100, Status: models.ProductStatusActive},
+	}
+	for i := range products {
+		if err := svc.db.Create(&products[i]).Error; err != nil {
+			t.Fatalf("create product: %v", err)
+		}
+	}
+
+	html, err := svc.Render([]models.LandingBlock{
+		landingBlock(LandingBlockProductGrid, `{"title":"Picks"}`),
+		landingBlock(LandingBlockFAQ, `{"items":[{"question":"<script>x</script>","answer":"ok"}]}`),
+	}, LandingRenderContext{AppName: "Shop", Currency: "USD"})
+	if err != nil {
+		t.Fatalf("render blocks: %v", err)
+	}
+	if !strings.Contains(html, "Featured One") || !strings.Contains(html, "USD 19.99") {
+		t.Fatalf("expected featured product with price, got:\n%s", html)
+	}
+	if strings.Contains(html, "Hidden Draft") || strings.Contains(html, ">Plain<") {
+		t.Fatalf("expected only active featured products, got:\n%s", html)
+	}
+	if strings.Contains(html, "<script>x</script>") {
+		t.Fatalf("expected block text to be escaped")
+	}
+}
@@ -0,0 +1,467 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxLedgerDescriptionLength = 500
+	ledgerVerifyBatchSize      = 500
+)
+
+type ledgerPosting struct {
+	Account string
+	Amount  int64
+}
+
+type ledgerEventInput struct {
+	Kind        string
+	Order       *models.Order
+	Source      string
+	Description string
+	CreatedBy   *uint
+	Postings    []ledgerPosting
+}
+
+// computeLedgerChecksum 分录内容摘要，用于发现被绕过 ORM 直接篡改的记录
+func computeLedgerChecksum(entry *models.LedgerEntry) string {
+	orderID := uint(0)
+	if entry.OrderID != nil {
+		orderID = *entry.OrderID
+	}
+	userID := uint(0)
+	if entry.UserID != nil {
+		userID = *entry.UserID
+	}
+	raw := fmt.Sprintf("%s|%s|%s|%d|%s|%d|%d|%s",
+		entry.TxnNo, entry.Kind, entry.Account, entry.AmountMinor, entry.Currency, orderID, userID, entry.Source)
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// recordLedgerEventTx 写入一组借贷平衡的分录，金额为 0 的分录会被跳过
+func recordLedgerEventTx(tx *gorm.DB, input ledgerEventInput) error {
+	var total int64
+	postings := make([]ledgerPosting, 0, len(input.Postings))
+	for _, posting := range input.Postings {
+		if posting.Amount == 0 {
+			continue
+		}
+		total += posting.Amount
+		postings = append(postings, posting)
+	}
+	if len(postings) == 0 {
+		return nil
+	}
+	if total != 0 {
+		return fmt.Errorf("ledger event %s is unbalanced: %d", input.Kind, total)
+	}
+
+	txnNo := uuid.New().String()
+	now := models.NowFunc()
+	entries := make([]models.LedgerEntry, 0, len(postings))
+	for _, posting := range postings {
+		entry := models.LedgerEntry{
+			TxnNo:       txnNo,
+			Kind:        input.Kind,
+			Account:     posting.Account,
+			AmountMinor: posting.Amount,
+			Source:      input.Source,
+			Description: input.Description,
+			CreatedBy:   input.CreatedBy,
+			CreatedAt:   now,
+		}
+		if input.Order != nil {
+			orderID := input.Order.ID
+			entry.OrderID = &orderID
+			entry.UserID = input.Order.UserID
+			entry.Currency = input.Order.Currency
+		}
+		entry.Checksum = computeLedgerChecksum(&entry)
+		entries = append(entries, entry)
+	}
+	return tx.Create(&entries).Error
+}
+
+// RecordOrderCreatedLedgerTx 下单记账：应收（优惠前）与优惠；settled 表示订单已在外部完成付款
+func RecordOrderCreatedLedgerTx(tx *gorm.DB, order *models.Order, settled bool, source string) error {
+	gross := order.TotalAmount + order.DiscountAmount
+	if err := recordLedgerEventTx(tx, ledgerEventInput{
+		Kind:   models.LedgerKindCharge,
+		Order:  order,
+		Source: source,
+		Postings: []ledgerPosting{
+			{Account: models.LedgerAccountCustomer, Amount: gross},
+			{Account: models.LedgerAccountRevenue, Amount: -gross},
+		},
+	}); err != nil {
+		return err
+	}
+	if err := recordLedgerEventTx(tx, ledgerEventInput{
+		Kind:        models.LedgerKindDiscount,
+		Order:       order,
+		Source:      source,
+		Description: order.PromoCodeStr,
+		Postings: []ledgerPosting{
+			{Account: models.LedgerAccountDiscount, Amount: order.DiscountAmount},
+			{Account: models.LedgerAccountCustomer, Amount: -order.DiscountAmount},
+		},
+	}); err != nil {
+		return err
+	}
+	if !settled {
+		return nil
+	}
+	return RecordOrderPaymentLedgerTx(tx, order, source, nil)
+}
+
+// RecordOrderPaymentLedgerTx 收款记账，按当前应收余额入账
+func RecordOrderPaymentLedgerTx(tx *gorm.DB, order *models.Order, source string, createdBy *uint) error {
+	outstanding, err := ledgerAccountBalanceTx(tx, order.ID, models.LedgerAccountCustomer)
+	if err != nil {
+		return err
+	}
+	if outstanding <= 0 {
+		return nil
+	}
+	return recordLedgerEventTx(tx, ledgerEventInput{
+		Kind:      models.LedgerKindPayment,
+		Order:     order,
+		Source:    source,
+		CreatedBy: createdBy,
+		Postings: []ledgerPosting{
+			{Account: models.LedgerAccountCash, Amount: outstanding},
+			{Account: models.LedgerAccountCustomer, Amount: -outstanding},
+		},
+	})
+}
+
+// RecordOrderAdjustmentLedgerTx 改价记账
+func RecordOrderAdjustmentLedgerTx(tx *gorm.DB, order *models.Order, oldAmount, newAmount int64, source string, createdBy *uint) error {
+	delta := newAmount - oldAmount
+	return recordLedgerEventTx(tx, ledgerEventInput{
+		Kind:        models.LedgerKindAdjustment,
+		Order:       order,
+		Source:      source,
+		Description: fmt.Sprintf("%d -> %d", oldAmount, newAmount),
+		CreatedBy:   createdBy,
+		Postings: []ledgerPosting{
+			{Account: models.LedgerAccountCustomer, Amount: delta},
+			{Account: models.LedgerAccountRevenue, Amount: -delta},
+		},
+	})
+}
+
+// RecordOrderRefundLedgerTx 退款记账，退还该订单全部实收金额
+func RecordOrderRefundLedgerTx(tx *gorm.DB, order *models.Order, source, description string, createdBy *uint) error {
+	received, err := ledgerAccountBalanceTx(tx, order.ID, models.LedgerAccountCash)
+	if err != nil {
+		return err
+	}
+	return recordRefundLedgerEventTx(tx, order, received, source, description, createdBy)
+}
+
+// RecordOrderPartialRefundLedgerTx 部分退款记账，金额不超过该订单剩余实收金额
+func RecordOrderPartialRefundLedgerTx(tx *gorm.DB, order *models.Order, amountMinor int64, source, description string, createdBy *uint) error {
+	received, err := ledgerAccountBalanceTx(tx, order.ID, models.LedgerAccountCash)
+	if err != nil {
+		return err
+	}
+	if amountMinor > received {
+		amountMinor = received
+	}
+	return recordRefundLedgerEventTx(tx, order, amountMinor, source, description, createdBy)
+}
+
+func recordRefundLedgerEventTx(tx *gorm.DB, order *models.Order, amountMinor int64, source, description string, createdBy *uint) error {
+	if amountMinor <= 0 {
+		return nil
+	}
+	return recordLedgerEventTx(tx, ledgerEventInput{
+		Kind:        models.LedgerKindRefund,
+		Order:       order,
+		Source:      source,
+		Description: description,
+		CreatedBy:   createdBy,
+		Postings: []ledgerPosting{
+			{Account: models.LedgerAccountRefund, Amount: amountMinor},
+			{Account: models.LedgerAccountCash, Amount: -amountMinor},
+		},
+	})
+}
+
+// RecordOrderVoidLedgerTx 取消订单时冲销尚未收款的应收
+func RecordOrderVoidLedgerTx(tx *gorm.DB, order *models.Order, source string) error {
+	outstanding, err := ledgerAccountBalanceTx(tx, order.ID, models.LedgerAccountCustomer)
+	if err != nil {
+		return err
+	}
+	if outstanding <= 0 {
+		return nil
+	}
+	return recordLedgerEventTx(tx, ledgerEventInput{
+		Kind:   models.LedgerKindVoid,
+		Order:  order,
+		Source: source,
+		Postings: []ledgerPosting{
+			{Account: models.LedgerAccountRevenue, Amount: outstanding},
+			{Account: models.LedgerAccountCustomer, Amount: -outstanding},
+		},
+	})
+}
+
+// RecordOrderMergeLedgerTx 合并订单时把被合并订单的全部余额按原事件类型转入目标订单：
+// 被合并订单逐类冲销归零，目标订单记入相同金额，两边分别借贷平衡
+func RecordOrderMergeLedgerTx(tx *gorm.DB, source, target *models.Order, createdBy *uint) error {
+	var rows []struct {
+		Kind    string
+		Account string
+		Amount  int64
+	}
+	if err := tx.Model(&models.LedgerEntry{}).
+		Select("kind, account, COALESCE(SUM(amount_minor), 0) AS amount").
+		Where("order_id = ?", source.ID).
+		Group("kind, account").
+		Order("kind, account").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	kinds := make([]string, 0)
+	postings := make(map[string][]ledgerPosting)
+	for _, row := range rows {
+		if row.Amount == 0 {
+			continue
+		}
+		if _, exists := postings[row.Kind]; !exists {
+			kinds = append(kinds, row.Kind)
+		}
+		postings[row.Kind] = append(postings[row.Kind], ledgerPosting{Account: row.Account, Amount: row.Amount})
+	}
+	for _, kind := range kinds {
+		reversed := make([]ledgerPosting, 0, len(postings[kind]))
+		for _, posting := range postings[kind] {
+			reversed = append(reversed, ledgerPosting{Account: posting.Account, Amount: -posting.Amount})
+		}
+		if err := recordLedgerEventTx(tx, ledgerEventInput{
+			Kind:        kind,
+			Order:       source,
+			Source:      "merge_order",
+			Description: "merged into " + target.OrderNo,
+			CreatedBy:   createdBy,
+			Postings:    reversed,
+		}); err != nil {
+			return err
+		}
+		if err := recordLedgerEventTx(tx, ledgerEventInput{
+			Kind:        kind,
+			Order:       target,
+			Source:      "merge_order",
+			Description: "merged from " + source.OrderNo,
+			CreatedBy:   createdBy,
+			Postings:    postings[kind],
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ledgerAccountBalanceTx(tx *gorm.DB, orderID uint, account string) (int64, error) {
+	var balance int64
+	err := tx.Model(&models.LedgerEntry{}).
+		Where("order_id = ? AND account = ?", orderID, account).
+		Select("COALESCE(SUM(amount_minor), 0)").
+		Scan(&balance).Error
+	return balance, err
+}
+
+// OrderFinancialSummary 订单财务汇总（全部由账本分录计算）
+type OrderFinancialSummary struct {
+	OrderID          uint                 `json:"order_id"`
+	OrderNo          string               `json:"order_no"`
+	Currency         string               `json:"currency"`
+	OrderTotalMinor  int64                `json:"order_total_minor"`
+	ChargedMinor     int64                `json:"charged_minor"`
+	DiscountMinor    int64                `json:"discount_minor"`
+	AdjustmentMinor  int64                `json:"adjustment_minor"`
+	NetDueMinor      int64                `json:"net_due_minor"`
+	PaidMinor        int64                `json:"paid_minor"`
+	RefundedMinor    int64                `json:"refunded_minor"`
+	CreditedMinor    int64                `json:"credited_minor"`
+	VoidedMinor      int64                `json:"voided_minor"`
+	OutstandingMinor int64                `json:"outstanding_minor"`
+	NetRevenueMinor  int64                `json:"net_revenue_minor"`
+	Consistent       bool                 `json:"consistent"`
+	Issues           []string             `json:"issues"`
+	Entries          []models.LedgerEntry `json:"entries"`
+}
+
+// LedgerIntegrityReport 账本完整性校验结果
+type LedgerIntegrityReport struct {
+	CheckedEntries     int64    `json:"checked_entries"`
+	UnbalancedTxns     []string `json:"unbalanced_txns"`
+	ChecksumMismatches []uint   `json:"checksum_mismatches"`
+	OK                 bool     `json:"ok"`
+}
+
+// LedgerService 财务账本查询、校验与手工补偿记账
+type LedgerService struct {
+	db *gorm.DB
+}
+
+func NewLedgerService(db *gorm.DB) *LedgerService {
+	return &LedgerService{db: db}
+}
+
+// ListOrderEntries 订单全部分录（按记账顺序）
+func (s *LedgerService) ListOrderEntries(orderID uint) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := s.db.Where("order_id = ?", orderID).Order("id ASC").Find(&entries).Error
+	return entries, err
+}
+
+// OrderSummary 汇总订单资金流水并与订单金额对账
+func (s *LedgerService) OrderSummary(order *models.Order) (*OrderFinancialSummary, error) {
+	entries, err := s.ListOrderEntries(order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &OrderFinancialSummary{
+		OrderID:         order.ID,
+		OrderNo:         order.OrderNo,
+		Currency:        order.Currency,
+		OrderTotalMinor: order.TotalAmount,
+		Issues:          []string{},
+		Entries:         entries,
+	}
+	txnTotals := make(map[string]int64)
+	txnOrder := make([]string, 0)
+	for i := range entries {
+		entry := &entries[i]
+		if _, exists := txnTotals[entry.TxnNo]; !exists {
+			txnOrder = append(txnOrder, entry.TxnNo)
+		}
+		txnTotals[entry.TxnNo] += entry.AmountMinor
+		if entry.Checksum != computeLedgerChecksum(entry) {
+			summary.Issues = append(summary.Issues, fmt.Sprintf("entry %d checksum mismatch", entry.ID))
+		}
+
+		switch entry.Account {
+		case models.LedgerAccountCustomer:
+			summary.OutstandingMinor += entry.AmountMinor
+			switch entry.Kind {
+			case models.LedgerKindCharge:
+				summary.ChargedMinor += entry.AmountMinor
+			case models.LedgerKindDiscount:
+				summary.DiscountMinor -= entry.AmountMinor
+			case models.LedgerKindAdjustment:
+				summary.AdjustmentMinor += entry.AmountMinor
+			case models.LedgerKindPayment:
+				summary.PaidMinor -= entry.AmountMinor
+			case models.LedgerKindCredit:
+				summary.CreditedMinor -= entry.AmountMinor
+			case models.LedgerKindVoid:
+				summary.VoidedMinor -= entry.AmountMinor
+			}
+		case models.LedgerAccountCash:
+			if entry.Kind == models.LedgerKindRefund {
+				summary.RefundedMinor -= entry.AmountMinor
+			}
+		case models.LedgerAccountRevenue, models.LedgerAccountDiscount, models.LedgerAccountRefund, models.LedgerAccountCredit:
+			summary.NetRevenueMinor -= entry.AmountMinor
+		}
+	}
+	for _, txnNo := range txnOrder {
+		if txnTotals[txnNo] != 0 {
+			summary.Issues = append(summary.Issues, fmt.Sprintf("transaction %s is unbalanced by %d", txnNo, txnTotals[txnNo]))
+		}
+	}
+
+	summary.NetDueMinor = summary.ChargedMinor - summary.DiscountMinor + summary.AdjustmentMinor
+	if len(entries) == 0 {
+		summary.Issues = append(summary.Issues, "order has no ledger entries")
+	} else if summary.NetDueMinor != order.TotalAmount {
+		summary.Issues = append(summary.Issues, fmt.Sprintf("ledger net due %d does not match order total %d", summary.NetDueMinor, order.TotalAmount))
+	}
+	summary.Consistent = len(summary.Issues) == 0
+	return summary, nil
+}
+
+// RecordCredit 手工记录对用户的补偿额度
+func (s *LedgerService) RecordCredit(order *models.Order, amountMinor int64, reason string, createdBy *uint) error {
+	if amountMinor <= 0 {
+		return bizerr.New("ledger.creditAmountInvalid", "Credit amount must be greater than 0")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return bizerr.New("ledger.creditReasonRequired", "Credit reason is required")
+	}
+	if len([]rune(reason)) > maxLedgerDescriptionLength {
+		return bizerr.Newf("ledger.creditReasonTooLong", "Credit reason cannot exceed %d characters", maxLedgerDescriptionLength).
+			WithParams(map[string]interface{}{"max": maxLedgerDescriptionLength})
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return recordLedgerEventTx(tx, ledgerEventInput{
+			Kind:        models.LedgerKindCredit,
+			Order:       order,
+			Source:      "admin_api",
+			Description: reason,
+			CreatedBy:   createdBy,
+			Postings: []ledgerPosting{
+				{Account: models.LedgerAccountCredit, Amount: amountMinor},
+				{Account: models.LedgerAccountCustomer, Amount: -amountMinor},
+			},
+		})
+	})
+}
+
+// VerifyIntegrity 全量校验：每笔交易借贷平衡且分录摘要未被篡改
+func (s *LedgerService) VerifyIntegrity() (*LedgerIntegrityReport, error) {
+	report := &LedgerIntegrityReport{
+		UnbalancedTxns:     []string{},
+		ChecksumMismatches: []uint{},
+	}
+
+	type txnTotal struct {
+		TxnNo string
+		Total int64
+	}
+	var unbalanced []txnTotal
+	if err := s.db.Model(&models.LedgerEntry{}).
+		Select("txn_no, SUM(amount_minor) AS total").
+		Group("txn_no").
+		Having("SUM(amount_minor) <> 0").
+		Scan(&unbalanced).Error; err != nil {
+		return nil, err
+	}
+	for _, item := range unbalanced {
+		report.UnbalancedTxns = append(report.UnbalancedTxns, item.TxnNo)
+	}
+
+	var batch []models.LedgerEntry
+	result := s.db.Model(&models.LedgerEntry{}).Order("id ASC").FindInBatches(&batch, ledgerVerifyBatchSize, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			report.CheckedEntries++
+			if batch[i].Checksum != computeLedgerChecksum(&batch[i]) {
+				report.ChecksumMismatches = append(report.ChecksumMismatches, batch[i].ID)
+			}
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	report.OK = len(report.UnbalancedTxns) == 0 && len(report.ChecksumMismatches) == 0
+	return report, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"auralogic/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newLedgerServiceForTest(t *testing.T) (*LedgerService, *gorm.DB) {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.LedgerEntry{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	return NewLedgerService(db), db
+}
+
+func TestLedgerOrderLifecycleSummary(t *testing.T) {
+	svc, db := newLedgerServiceForTest(t)
+
+	userID := uint(7)
+	order := &models.Order{ID: 1, OrderNo: "ORD1", UserID: &userID, Currency: "CNY", TotalAmount: 9000, DiscountAmount: 1000, PromoCodeStr: "SAVE10"}
+	if err := RecordOrderCreatedLedgerTx(db, order, false, "web"); err != nil {
+		t.Fatalf("record created: %v", err)
+	}
+	if err := RecordOrderAdjustmentLedgerTx(db, order, 9000, 8500, "admin_api", nil); err != nil {
+		t.Fatalf("record adjustment: %v", err)
+	}
+	order.TotalAmount = 8500
+	if err := RecordOrderPaymentLedgerTx(db, order, "payment_polling", nil); err != nil {
+		t.Fatalf("record payment: %v", err)
+	}
+	if err := svc.RecordCredit(order, 300, "late delivery", nil); err != nil {
+		t.Fatalf("record credit: %v", err)
+	}
+	if err := RecordOrderRefundLedgerTx(db, order, "admin_refund", "", nil); err != nil {
+		t.Fatalf("record refund: %v", err)
+	}
+
+	summary, err := svc.OrderSummary(order)
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	if !summary.Consistent {
+		t.Fatalf("expected consistent ledger, issues=%v", summary.Issues)
+	}
+	if summary.ChargedMinor != 10000 || summary.DiscountMinor != 1000 || summary.AdjustmentMinor != -500 || summary.NetDueMinor != 8500 {
+		t.Fatalf("unexpected charge figures: %+v", summary)
+	}
+	if summary.PaidMinor != 8500 || summary.RefundedMinor != 8500 || summary.CreditedMinor != 300 {
+		t.Fatalf("unexpected money movement figures: %+v", summary)
+	}
+	if summary.OutstandingMinor != -300 || summary.NetRevenueMinor != -300 {
+		t.Fatalf("unexpected balances: outstanding=%d revenue=%d", summary.OutstandingMinor, summary.NetRevenueMinor)
+	}
+
+	report, err := svc.VerifyIntegrity()
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !report.OK || report.CheckedEntries != int64(len(summary.Entries)) {
+		t.Fatalf("unexpected integrity report: %+v", report)
+	}
+
+	err = svc.RecordCredit(order, 0, "noop", nil)
+	requireProductBizErr(t, err, "ledger.creditAmountInvalid")
+}
+
+func TestLedgerEntriesAreAppendOnlyAndTamperEvident(t *testing.T) {
+	svc, db := newLedgerServiceForTest(t)
+
+	order := &models.Order{ID: 2, OrderNo: "ORD2", Currency: "CNY", TotalAmount: 500}
+	if err := RecordOrderCreatedLedgerTx(db, order, false, "web"); err != nil {
+		t.Fatalf("record created: %v", err)
+	}
+	if err := RecordOrderVoidLedgerTx(db, order, "cancel_order"); err != nil {
+		t.Fatalf("record void: %v", err)
+	}
+
+	var entry models.LedgerEntry
+	if err := db.Where("order_id = ? AND account = ?", order.ID, models.LedgerAccountRevenue).First(&entry).Error; err != nil {
+		t.Fatalf("load entry: %v", err)
+	}
+	if err := db.Model(&entry).Update("amount_minor", 1).Error; !errors.Is(err, models.ErrLedgerEntryImmutable) {
+		t.Fatalf("expected update to be rejected, got %v", err)
+	}
+	if err := db.Delete(&entry).Error; !errors.Is(err, models.ErrLedgerEntryImmutable) {
+		t.Fatalf("expected delete to be rejected, got %v", err)
+	}
+
+	// 绕过 ORM 直接改库
+	if err := db.Exec("UPDATE ledger_entries SET amount_minor = ? WHERE id = ?", -400, entry.ID).Error; err != nil {
+		t.Fatalf("raw update: %v", err)
+	}
+	report, err := svc.VerifyIntegrity()
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if report.OK || len(report.UnbalancedTxns) != 1 || len(report.ChecksumMismatches) != 1 {
+		t.Fatalf("expected tampering to be detected: %+v", report)
+	}
+
+	summary, err := svc.OrderSummary(order)
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	if summary.Consistent || summary.VoidedMinor != 500 {
+		t.Fatalf("expected inconsistent summary after tampering: %+v", summary)
+	}
+}
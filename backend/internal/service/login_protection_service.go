@@ -0,0 +1,498 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/authbiz"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+const ipBanCacheTTL = 30 * time.Second
+
+var (
+	ErrSecurityEventNotFound  = bizerr.New("security.eventNotFound", "Security event not found")
+	ErrIPBanNotFound          = bizerr.New("security.banNotFound", "IP ban not found")
+	ErrAccountLockoutNotFound = bizerr.New("security.lockoutNotFound", "Account lockout not found")
+)
+
+// IPBanInput 手动封禁参数，DurationMinutes 为 0 表示永久
+type IPBanInput struct {
+	IPAddress       string `json:"ip_address"`
+	Reason          string `json:"reason"`
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+// SecurityEventFilter 安全事件筛选
+type SecurityEventFilter struct {
+	EventType     string
+	Severity      string
+	IPAddress     string
+	Email         string
+	PendingReview bool
+}
+
+// LoginProtectionService 登录保护：账户锁定、IP 封禁、撞库检测和安全事件
+type LoginProtectionService struct {
+	db  *gorm.DB
+	cfg *config.Config
+
+	banMu        sync.RWMutex
+	bans         map[string]*time.Time
+	bansLoadedAt time.Time
+}
+
+func NewLoginProtectionService(db *gorm.DB, cfg *config.Config) *LoginProtectionService {
+	return &LoginProtectionService{db: db, cfg: cfg}
+}
+
+// settings 当前配置，未配置的阈值使用默认值
+func (s *LoginProtectionService) settings() config.LoginProtectionConfig {
+	var settings config.LoginProtectionConfig
+	if s.cfg != nil {
+		settings = s.cfg.Security.LoginProtection
+	}
+	if settings.MaxFailedAttempts <= 0 {
+		settings.MaxFailedAttempts = 5
+	}
+	if settings.LockoutMinutes <= 0 {
+		settings.LockoutMinutes = 15
+	}
+	if settings.MaxLockoutMinutes < settings.LockoutMinutes {
+		settings.MaxLockoutMinutes = 24 * 60
+	}
+	if settings.StuffingDistinctEmails <= 0 {
+		settings.StuffingDistinctEmails = 10
+	}
+	if settings.StuffingWindowMinutes <= 0 {
+		settings.StuffingWindowMinutes = 10
+	}
+	return settings
+}
+
+// lockoutDuration 第 n 次锁定（从 0 开始）的时长，每次翻倍直到上限
+func lockoutDuration(settings config.LoginProtectionConfig, lockoutCount int) time.Duration {
+	minutes := settings.LockoutMinutes
+	for i := 0; i < lockoutCount && minutes < settings.MaxLockoutMinutes; i++ {
+		minutes *= 2
+	}
+	if minutes > settings.MaxLockoutMinutes {
+		minutes = settings.MaxLockoutMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func (s *LoginProtectionService) recordEvent(event *models.SecurityEvent) {
+	if err := s.db.Create(event).Error; err != nil {
+		log.Printf("record security event failed: type=%s ip=%s err=%v", event.EventType, event.IPAddress, err)
+	}
+}
+
+// IsIPBanned IP 是否被封禁（带短时缓存，供全局中间件使用）
+func (s *LoginProtectionService) IsIPBanned(ip string) bool {
+	ip = strings.TrimSpace(ip)
+	if ip == "" {
+		return false
+	}
+	now := models.NowFunc()
+
+	s.banMu.RLock()
+	bans, loadedAt := s.bans, s.bansLoadedAt
+	s.banMu.RUnlock()
+	if bans == nil || now.Sub(loadedAt) > ipBanCacheTTL {
+		if err := s.reloadBans(); err != nil {
+			log.Printf("load ip bans failed: %v", err)
+		}
+		s.banMu.RLock()
+		bans = s.bans
+		s.banMu.RUnlock()
+	}
+
+	expiresAt, exists := bans[ip]
+	return exists && (expiresAt == nil || expiresAt.After(now))
+}
+
+func (s *LoginProtectionService) reloadBans() error {
+	now := models.NowFunc()
+	var items []models.IPBan
+	if err := s.db.Where("expires_at IS NULL OR expires_at > ?", now).Find(&items).Error; err != nil {
+		return err
+	}
+	bans := make(map[string]*time.Time, len(items))
+	for _, item := range items {
+		bans[item.IPAddress] = item.ExpiresAt
+	}
+	s.banMu.Lock()
+	s.bans = bans
+	s.bansLoadedAt = now
+	s.banMu.Unlock()
+	return nil
+}
+
+// CheckLogin 登录前检查 IP 封禁和账户锁定
+func (s *LoginProtectionService) CheckLogin(email, ip, userAgent string) error {
+	if s.IsIPBanned(ip) {
+		return authbiz.IPBanned()
+	}
+	if !s.settings().Enabled || email == "" {
+		return nil
+	}
+
+	var lockout models.AccountLockout
+	if err := s.db.Where("email = ?", email).First(&lockout).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if lockout.LockedUntil != nil && lockout.LockedUntil.After(models.NowFunc()) {
+		s.recordEvent(&models.SecurityEvent{
+			EventType: models.SecurityEventLockedLoginBlocked,
+			Severity:  models.SecuritySeverityInfo,
+			Email:     email,
+			IPAddress: ip,
+			UserAgent: userAgent,
+		})
+		return authbiz.AccountLocked(*lockout.LockedUntil)
+	}
+	return nil
+}
+
+// RecordFailure 记录一次失败登录：累计失败次数、必要时锁定账户，并检测撞库
+func (s *LoginProtectionService) RecordFailure(email string, userID *uint, ip, userAgent string) {
+	s.recordEvent(&models.SecurityEvent{
+		EventType: models.SecurityEventLoginFailed,
+		Severity:  models.SecuritySeverityInfo,
+		Email:     email,
+		UserID:    userID,
+		IPAddress: ip,
+		UserAgent: userAgent,
+	})
+
+	settings := s.settings()
+	if !settings.Enabled {
+		return
+	}
+	if email != "" {
+		if err := s.recordAccountFailure(settings, email, userID, ip, userAgent); err != nil {
+			log.Printf("record login failure failed: email=%s err=%v", email, err)
+		}
+	}
+	if ip != "" {
+		if err := s.detectCredentialStuffing(settings, ip, userAgent); err != nil {
+			log.Printf("credential stuffing detection failed: ip=%s err=%v", ip, err)
+		}
+	}
+}
+
+func (s *LoginProtectionService) recordAccountFailure(settings config.LoginProtectionConfig, email string, userID *uint, ip, userAgent string) error {
+	var lockedEvent *models.SecurityEvent
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var lockout models.AccountLockout
+		if err := tx.Where(models.AccountLockout{Email: email}).FirstOrCreate(&lockout).Error; err != nil {
+			return err
+		}
+		now := models.NowFunc()
+		lockout.FailedCount++
+		lockout.LastFailedAt = &now
+		lockout.LastFailedIP = ip
+		if lockout.FailedCount >= settings.MaxFailedAttempts {
+			duration := lockoutDuration(settings, lockout.LockoutCount)
+			lockedUntil := now.Add(duration)
+			lockout.LockedUntil = &lockedUntil
+			lockout.LockoutCount++
+			lockout.FailedCount = 0
+			lockedEvent = &models.SecurityEvent{
+				EventType: models.SecurityEventAccountLocked,
+				Severity:  models.SecuritySeverityWarning,
+				Email:     email,
+				UserID:    userID,
+				IPAddress: ip,
+				UserAgent: userAgent,
+				Details: map[string]interface{}{
+					"locked_until":     lockedUntil,
+					"lockout_count":    lockout.LockoutCount,
+					"duration_minutes": int(duration / time.Minute),
+				},
+			}
+		}
+		return tx.Save(&lockout).Error
+	})
+	if err == nil && lockedEvent != nil {
+		s.recordEvent(lockedEvent)
+	}
+	return err
+}
+
+// detectCredentialStuffing 同一 IP 在窗口内对多个不同账户登录失败时自动封禁
+func (s *LoginProtectionService) detectCredentialStuffing(settings config.LoginProtectionConfig, ip, userAgent string) error {
+	if s.IsIPBanned(ip) {
+		return nil
+	}
+	since := models.NowFunc().Add(-time.Duration(settings.StuffingWindowMinutes) * time.Minute)
+	var distinctEmails int64
+	if err := s.db.Model(&models.SecurityEvent{}).
+		Where("ip_address = ? AND created_at >= ? AND email <> ''", ip, since).
+		Where("event_type IN ?", []string{models.SecurityEventLoginFailed, models.SecurityEventLockedLoginBlocked}).
+		Distinct("email").
+		Count(&distinctEmails).Error; err != nil {
+		return err
+	}
+	if distinctEmails < int64(settings.StuffingDistinctEmails) {
+		return nil
+	}
+
+	ban := &models.IPBan{
+		IPAddress: ip,
+		Reason:    "Automatic ban: credential stuffing detected",
+		Source:    models.IPBanSourceAuto,
+	}
+	if settings.AutoBanMinutes > 0 {
+		expiresAt := models.NowFunc().Add(time.Duration(settings.AutoBanMinutes) * time.Minute)
+		ban.ExpiresAt = &expiresAt
+	}
+	if err := s.saveBan(ban); err != nil {
+		return err
+	}
+	s.recordEvent(&models.SecurityEvent{
+		EventType:      models.SecurityEventCredentialStuffing,
+		Severity:       models.SecuritySeverityCritical,
+		IPAddress:      ip,
+		UserAgent:      userAgent,
+		RequiresReview: true,
+		Details: map[string]interface{}{
+			"distinct_emails": distinctEmails,
+			"window_minutes":  settings.StuffingWindowMinutes,
+			"ban_id":          ban.ID,
+			"expires_at":      ban.ExpiresAt,
+		},
+	})
+	return nil
+}
+
+// RecordSuccess 登录成功后清除失败计数；失败后成功、管理员从新 IP 登录记录为待确认的可疑登录
+func (s *LoginProtectionService) RecordSuccess(user *models.User, ip, userAgent string) {
+	if user == nil || !s.settings().Enabled {
+		return
+	}
+
+	reasons := make([]string, 0, 2)
+	var lockout models.AccountLockout
+	if err := s.db.Where("email = ?", user.Email).First(&lockout).Error; err == nil {
+		if lockout.FailedCount > 0 || lockout.LockoutCount > 0 {
+			reasons = append(reasons, "after_failed_attempts")
+		}
+		if err := s.db.Delete(&lockout).Error; err != nil {
+			log.Printf("reset login failures failed: email=%s err=%v", user.Email, err)
+		}
+	}
+	if user.IsAdmin() && user.LastLoginIP != "" && ip != "" && user.LastLoginIP != ip {
+		reasons = append(reasons, "admin_new_ip")
+	}
+	if len(reasons) == 0 {
+		return
+	}
+
+	userID := user.ID
+	s.recordEvent(&models.SecurityEvent{
+		EventType:      models.SecurityEventSuspiciousLogin,
+		Severity:       models.SecuritySeverityWarning,
+		Email:          user.Email,
+		UserID:         &userID,
+		IPAddress:      ip,
+		UserAgent:      userAgent,
+		RequiresReview: true,
+		Details: map[string]interface{}{
+			"reasons":       reasons,
+			"failed_count":  lockout.FailedCount,
+			"lockout_count": lockout.LockoutCount,
+			"previous_ip":   user.LastLoginIP,
+		},
+	})
+}
+
+// ListEvents 安全事件列表（最新在前）
+func (s *LoginProtectionService) ListEvents(filter SecurityEventFilter, page, limit int) ([]models.SecurityEvent, int64, error) {
+	query := s.db.Model(&models.SecurityEvent{})
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.Severity != "" {
+		query = query.Where("severity = ?", filter.Severity)
+	}
+	if filter.IPAddress != "" {
+		query = query.Where("ip_address = ?", filter.IPAddress)
+	}
+	if filter.Email != "" {
+		query = query.Where("email = ?", strings.ToLower(filter.Email))
+	}
+	if filter.PendingReview {
+		query = query.Where("requires_review = ? AND reviewed_at IS NULL", true)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var events []models.SecurityEvent
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&events).Error
+	return events, total, err
+}
+
+// ReviewEvent 确认安全事件
+func (s *LoginProtectionService) ReviewEvent(id, adminID uint, note string) (*models.SecurityEvent, error) {
+	var event models.SecurityEvent
+	if err := s.db.First(&event, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSecurityEventNotFound
+		}
+		return nil, err
+	}
+	now := models.NowFunc()
+	event.ReviewedAt = &now
+	event.ReviewedBy = &adminID
+	event.ReviewNote = strings.TrimSpace(note)
+	if err := s.db.Model(&event).Select("reviewed_at", "reviewed_by", "review_note").Updates(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ListBans IP 封禁列表，includeExpired 为 false 时只返回生效中的封禁
+func (s *LoginProtectionService) ListBans(page, limit int, includeExpired bool) ([]models.IPBan, int64, error) {
+	query := s.db.Model(&models.IPBan{})
+	if !includeExpired {
+		query = query.Where("expires_at IS NULL OR expires_at > ?", models.NowFunc())
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var bans []models.IPBan
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&bans).Error
+	return bans, total, err
+}
+
+// saveBan 按 IP 新建或覆盖封禁并刷新缓存
+func (s *LoginProtectionService) saveBan(ban *models.IPBan) error {
+	var existing models.IPBan
+	err := s.db.Where("ip_address = ?", ban.IPAddress).First(&existing).Error
+	switch {
+	case err == nil:
+		ban.ID = existing.ID
+		ban.CreatedAt = existing.CreatedAt
+		err = s.db.Save(ban).Error
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		err = s.db.Create(ban).Error
+	}
+	if err != nil {
+		return err
+	}
+	return s.reloadBans()
+}
+
+// BanIP 手动封禁 IP（已存在的封禁会被覆盖）
+func (s *LoginProtectionService) BanIP(input IPBanInput, adminID uint) (*models.IPBan, error) {
+	ip := net.ParseIP(strings.TrimSpace(input.IPAddress))
+	if ip == nil {
+		return nil, bizerr.Newf("security.ipInvalid", "Invalid IP address: %s", input.IPAddress).
+			WithParams(map[string]interface{}{"ip": input.IPAddress})
+	}
+	if input.DurationMinutes < 0 {
+		return nil, bizerr.New("security.durationInvalid", "Ban duration must not be negative")
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if len([]rune(reason)) > 255 {
+		reason = string([]rune(reason)[:255])
+	}
+
+	ban := &models.IPBan{
+		IPAddress: ip.String(),
+		Reason:    reason,
+		Source:    models.IPBanSourceManual,
+		CreatedBy: &adminID,
+	}
+	if input.DurationMinutes > 0 {
+		expiresAt := models.NowFunc().Add(time.Duration(input.DurationMinutes) * time.Minute)
+		ban.ExpiresAt = &expiresAt
+	}
+	if err := s.saveBan(ban); err != nil {
+		return nil, err
+	}
+	s.recordEvent(&models.SecurityEvent{
+		EventType: models.SecurityEventIPBanned,
+		Severity:  models.SecuritySeverityWarning,
+		IPAddress: ban.IPAddress,
+		Details: map[string]interface{}{
+			"admin_id":   adminID,
+			"ban_id":     ban.ID,
+			"reason":     ban.Reason,
+			"expires_at": ban.ExpiresAt,
+		},
+	})
+	return ban, nil
+}
+
+// UnbanIP 解除封禁
+func (s *LoginProtectionService) UnbanIP(id, adminID uint) (*models.IPBan, error) {
+	var ban models.IPBan
+	if err := s.db.First(&ban, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIPBanNotFound
+		}
+		return nil, err
+	}
+	if err := s.db.Delete(&ban).Error; err != nil {
+		return nil, err
+	}
+	if err := s.reloadBans(); err != nil {
+		log.Printf("reload ip bans failed: %v", err)
+	}
+	s.recordEvent(&models.SecurityEvent{
+		EventType: models.SecurityEventIPUnbanned,
+		Severity:  models.SecuritySeverityInfo,
+		IPAddress: ban.IPAddress,
+		Details:   map[string]interface{}{"admin_id": adminID, "ban_id": ban.ID, "source": ban.Source},
+	})
+	return &ban, nil
+}
+
+// ListLockouts 当前被锁定的账户
+func (s *LoginProtectionService) ListLockouts(page, limit int) ([]models.AccountLockout, int64, error) {
+	query := s.db.Model(&models.AccountLockout{}).Where("locked_until > ?", models.NowFunc())
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var lockouts []models.AccountLockout
+	err := query.Order("locked_until DESC").Offset((page - 1) * limit).Limit(limit).Find(&lockouts).Error
+	return lockouts, total, err
+}
+
+// UnlockAccount 解除账户锁定并清空失败计数
+func (s *LoginProtectionService) UnlockAccount(id, adminID uint) (*models.AccountLockout, error) {
+	var lockout models.AccountLockout
+	if err := s.db.First(&lockout, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountLockoutNotFound
+		}
+		return nil, err
+	}
+	if err := s.db.Delete(&lockout).Error; err != nil {
+		return nil, err
+	}
+	s.recordEvent(&models.SecurityEvent{
+		EventType: models.SecurityEventAccountUnlocked,
+		Severity:  models.SecuritySeverityInfo,
+		Email:     lockout.Email,
+		Details:   map[string]interface{}{"admin_id": adminID, "lockout_count": lockout.LockoutCount},
+	})
+	return &lockout, nil
+}
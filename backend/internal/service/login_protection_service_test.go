@@ -0,0 +1,191 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/password"
+	"gorm.io/gorm"
+)
+
+func newLoginProtectionTestService(t *testing.T, protection config.LoginProtectionConfig) (*AuthService, *LoginProtectionService, *gorm.DB) {
+	t.Helper()
+
+	svc, db := newAuthServiceTestDB(t)
+	if err := db.AutoMigrate(&models.IPBan{}, &models.AccountLockout{}, &models.SecurityEvent{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	svc.cfg.Security.LoginProtection = protection
+	protectionService := NewLoginProtectionService(db, svc.cfg)
+	svc.SetLoginProtection(protectionService)
+
+	hash, err := password.HashPassword("Password1!")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	user := models.User{
+		UUID:          "protected-user",
+		Email:         "protected@example.com",
+		PasswordHash:  hash,
+		Name:          "Protected",
+		Role:          "user",
+		IsActive:      true,
+		EmailVerified: true,
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return svc, protectionService, db
+}
+
+func expireLockout(t *testing.T, db *gorm.DB, email string) {
+	t.Helper()
+	past := time.Now().UTC().Add(-time.Minute)
+	if err := db.Model(&models.AccountLockout{}).Where("email = ?", email).Update("locked_until", past).Error; err != nil {
+		t.Fatalf("expire lockout: %v", err)
+	}
+}
+
+func TestLoginProtectionLocksAccountProgressively(t *testing.T) {
+	svc, protection, db := newLoginProtectionTestService(t, config.LoginProtectionConfig{
+		Enabled:                true,
+		MaxFailedAttempts:      3,
+		LockoutMinutes:         10,
+		MaxLockoutMinutes:      15,
+		StuffingDistinctEmails: 100,
+	})
+	const email = "protected@example.com"
+
+	failTimes := func(n int) {
+		for i := 0; i < n; i++ {
+			_, _, err := svc.LoginFromClient(email, "wrong", "192.0.2.10", "test-agent")
+			requireAuthBizErr(t, err, "auth.invalidEmailOrPassword")
+		}
+	}
+
+	failTimes(3)
+	_, _, err := svc.LoginFromClient(email, "Password1!", "192.0.2.10", "test-agent")
+	locked := requireAuthBizErr(t, err, "auth.accountLocked")
+	if locked.Params["retry_after_seconds"].(int) <= 9*60 {
+		t.Fatalf("expected first lockout to last about 10 minutes, got %+v", locked.Params)
+	}
+
+	// 第二次锁定时长翻倍，但不超过上限
+	expireLockout(t, db, email)
+	failTimes(3)
+	var lockout models.AccountLockout
+	if err := db.Where("email = ?", email).First(&lockout).Error; err != nil {
+		t.Fatalf("load lockout: %v", err)
+	}
+	if lockout.LockoutCount != 2 || lockout.LockedUntil == nil {
+		t.Fatalf("expected second lockout, got %+v", lockout)
+	}
+	if remaining := time.Until(*lockout.LockedUntil); remaining < 14*time.Minute || remaining > 15*time.Minute {
+		t.Fatalf("expected lockout capped at 15 minutes, got %v", remaining)
+	}
+	lockouts, total, err := protection.ListLockouts(1, 20)
+	if err != nil || total != 1 || len(lockouts) != 1 {
+		t.Fatalf("expected one active lockout, got %d, %v", total, err)
+	}
+
+	// 管理员解锁后可以登录，失败后成功的登录需要确认
+	if _, err := protection.UnlockAccount(lockouts[0].ID, 1); err != nil {
+		t.Fatalf("unlock account: %v", err)
+	}
+	failTimes(1)
+	if _, _, err := svc.LoginFromClient(email, "Password1!", "192.0.2.10", "test-agent"); err != nil {
+		t.Fatalf("expected login after unlock, got %v", err)
+	}
+	var remaining int64
+	db.Model(&models.AccountLockout{}).Where("email = ?", email).Count(&remaining)
+	if remaining != 0 {
+		t.Fatalf("expected failure counter to be reset after successful login")
+	}
+	events, total, err := protection.ListEvents(SecurityEventFilter{PendingReview: true}, 1, 20)
+	if err != nil || total != 1 || events[0].EventType != models.SecurityEventSuspiciousLogin {
+		t.Fatalf("expected one suspicious login pending review, got %d, %v", total, err)
+	}
+	if _, err := protection.ReviewEvent(events[0].ID, 1, "verified with user"); err != nil {
+		t.Fatalf("review event: %v", err)
+	}
+	if _, total, _ := protection.ListEvents(SecurityEventFilter{PendingReview: true}, 1, 20); total != 0 {
+		t.Fatalf("expected no pending events after review, got %d", total)
+	}
+
+	// 不存在的账户同样会被锁定，避免通过响应差异枚举账户
+	for i := 0; i < 3; i++ {
+		_, _, err := svc.LoginFromClient("ghost@example.com", "wrong", "192.0.2.11", "")
+		requireAuthBizErr(t, err, "auth.invalidEmailOrPassword")
+	}
+	_, _, err = svc.LoginFromClient("ghost@example.com", "wrong", "192.0.2.11", "")
+	requireAuthBizErr(t, err, "auth.accountLocked")
+}
+
+func TestLoginProtectionBansIPs(t *testing.T) {
+	svc, protection, db := newLoginProtectionTestService(t, config.LoginProtectionConfig{
+		Enabled:                true,
+		MaxFailedAttempts:      10,
+		StuffingDistinctEmails: 3,
+		StuffingWindowMinutes:  10,
+		AutoBanMinutes:         60,
+	})
+	const attackerIP = "203.0.113.7"
+
+	for _, email := range []string{"a@example.com", "b@example.com", "protected@example.com"} {
+		_, _, err := svc.LoginFromClient(email, "guess", attackerIP, "stuffer")
+		requireAuthBizErr(t, err, "auth.invalidEmailOrPassword")
+	}
+	if !protection.IsIPBanned(attackerIP) {
+		t.Fatal("expected credential stuffing IP to be banned automatically")
+	}
+	_, _, err := svc.LoginFromClient("protected@example.com", "Password1!", attackerIP, "stuffer")
+	requireAuthBizErr(t, err, "auth.ipBanned")
+	if _, _, err := svc.LoginFromClient("protected@example.com", "Password1!", "192.0.2.20", ""); err != nil {
+		t.Fatalf("expected other IPs to log in, got %v", err)
+	}
+
+	var ban models.IPBan
+	if err := db.Where("ip_address = ?", attackerIP).First(&ban).Error; err != nil {
+		t.Fatalf("load ban: %v", err)
+	}
+	if ban.Source != models.IPBanSourceAuto || ban.ExpiresAt == nil {
+		t.Fatalf("expected temporary automatic ban, got %+v", ban)
+	}
+	events, _, err := protection.ListEvents(SecurityEventFilter{EventType: models.SecurityEventCredentialStuffing}, 1, 20)
+	if err != nil || len(events) != 1 || !events[0].RequiresReview {
+		t.Fatalf("expected credential stuffing event pending review, got %d, %v", len(events), err)
+	}
+
+	requireAuthBizErr(t, func() error {
+		_, err := protection.BanIP(IPBanInput{IPAddress: "not-an-ip"}, 1)
+		return err
+	}(), "security.ipInvalid")
+
+	manual, err := protection.BanIP(IPBanInput{IPAddress: " 198.51.100.1 ", Reason: "abuse"}, 1)
+	if err != nil {
+		t.Fatalf("ban ip: %v", err)
+	}
+	if manual.ExpiresAt != nil || !protection.IsIPBanned("198.51.100.1") {
+		t.Fatalf("expected permanent manual ban, got %+v", manual)
+	}
+	if _, err := protection.UnbanIP(manual.ID, 1); err != nil {
+		t.Fatalf("unban ip: %v", err)
+	}
+	if protection.IsIPBanned("198.51.100.1") {
+		t.Fatal("expected ip to be unbanned")
+	}
+	requireAuthBizErr(t, func() error {
+		_, err := protection.UnbanIP(manual.ID, 1)
+		return err
+	}(), "security.banNotFound")
+
+	// 手动封禁不受 enabled 开关影响
+	svc.cfg.Security.LoginProtection.Enabled = false
+	if _, err := protection.BanIP(IPBanInput{IPAddress: "198.51.100.2", DurationMinutes: 30}, 1); err != nil {
+		t.Fatalf("ban ip: %v", err)
+	}
+	_, _, err = svc.LoginFromClient("protected@example.com", "Password1!", "198.51.100.2", "")
+	requireAuthBizErr(t, err, "auth.ipBanned")
+}
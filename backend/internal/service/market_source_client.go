@@ -12,6 +12,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"auralogic/internal/pkg/demomode"
 )
 
 const pluginHostMarketBridgeVersion = "1.0.0"
@@ -332,7 +334,8 @@ func newPluginMarketSameOriginHTTPClient(timeout time.Duration, origin *url.URL)
 		timeout = 12 * time.Second
 	}
 	return &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: demomode.Transport(nil),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) == 0 {
 				return nil
@@ -19,6 +19,8 @@ const (
 	marketingQueueKey     = "marketing:queue"
 	marketingLockKeyFmt   = "marketing:batch:lock:%d"
 	marketingLockDuration = 2 * time.Hour
+	// 未启用 Redis 时轮询排队批次的间隔
+	marketingQueuePollInterval = 5 * time.Second
 )
 
 type MarketingService struct {
@@ -45,11 +47,6 @@ func (s *MarketingService) SetPluginManager(pluginManager *PluginManagerService)
 }
 
 func (s *MarketingService) Start() {
-	if cache.RedisClient == nil {
-		log.Println("Marketing queue worker not started: redis client is not initialized")
-		return
-	}
-
 	s.workerMu.Lock()
 	defer s.workerMu.Unlock()
 
@@ -153,8 +150,9 @@ func (s *MarketingService) EnqueueBatch(batchID uint) error {
 	if batchID == 0 {
 		return fmt.Errorf("invalid batch id")
 	}
+	// 未启用 Redis 时批次以 queued 状态留在数据库中，由轮询取出
 	if cache.RedisClient == nil {
-		return fmt.Errorf("redis client is not initialized")
+		return nil
 	}
 	return cache.RedisClient.RPush(cache.RedisClient.Context(), marketingQueueKey, batchID).Err()
 }
@@ -165,7 +163,7 @@ func (s *MarketingService) ProcessQueue() {
 
 func (s *MarketingService) processQueueLoop(stopChan <-chan struct{}) {
 	if cache.RedisClient == nil {
-		log.Println("Marketing queue worker not started: redis client is not initialized")
+		s.pollQueuedBatchesLoop(stopChan)
 		return
 	}
 
@@ -208,6 +206,34 @@ func (s *MarketingService) processQueueLoop(stopChan <-chan struct{}) {
 	}
 }
 
+// pollQueuedBatchesLoop 未启用 Redis 时轮询数据库中排队的批次
+func (s *MarketingService) pollQueuedBatchesLoop(stopChan <-chan struct{}) {
+	for {
+		var batchIDs []uint
+		if err := s.db.Model(&models.MarketingBatch{}).
+			Where("status = ?", models.MarketingBatchStatusQueued).
+			Order("id ASC").
+			Limit(20).
+			Pluck("id", &batchIDs).Error; err != nil {
+			log.Printf("poll marketing batches failed: %v", err)
+		}
+		for _, batchID := range batchIDs {
+			select {
+			case <-stopChan:
+				return
+			default:
+			}
+			if err := s.processBatch(batchID); err != nil {
+				log.Printf("process marketing batch failed, batch=%d: %v", batchID, err)
+				s.failBatch(batchID, err.Error())
+			}
+		}
+		if waitBackgroundServiceStopChan(stopChan, marketingQueuePollInterval) {
+			return
+		}
+	}
+}
+
 func (s *MarketingService) processBatch(batchID uint) error {
 	lockKey := fmt.Sprintf(marketingLockKeyFmt, batchID)
 	locked, err := cache.SetNX(lockKey, "1", marketingLockDuration)
@@ -525,7 +551,7 @@ func (s *MarketingService) processEmailTask(batch *models.MarketingBatch, user *
 	}
 
 	batchID := batch.ID
-	if err := s.emailService.queueEmail(user.Email, emailSubject, emailHTML, "marketing.announcement", nil, &user.ID, &batchID); err != nil {
+	if err := s.emailService.queueEmail(user.Email, emailSubject, emailHTML, "marketing.announcement", nil, &user.ID, &batchID, false); err != nil {
 		status := models.MarketingTaskStatusFailed
 		errMessage := err.Error()
 		if updateErr := s.updateTaskResult(taskID, status, errMessage); updateErr != nil {
@@ -2,6 +2,8 @@ package service
 
 import (
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"auralogic/internal/config"
@@ -87,18 +89,67 @@ func messageRateLimitTTLSeconds(now time.Time, scope string) int {
 	return ttl
 }
 
+// messageRateLimitMemoryMu 未启用 Redis 时保证进程内"检查+计数"的原子性
+var messageRateLimitMemoryMu sync.Mutex
+
+// reserveMessageRateLimitMemorySlot 与 reserveMessageRateLimitScript 语义一致的进程内实现
+func reserveMessageRateLimitMemorySlot(hourKey, dayKey string, rl config.MessageRateLimit, now time.Time) (bool, string, error) {
+	messageRateLimitMemoryMu.Lock()
+	defer messageRateLimitMemoryMu.Unlock()
+
+	current := func(key string) int {
+		value, err := cache.Get(key)
+		if err != nil {
+			return 0
+		}
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+	if rl.Hourly > 0 && current(hourKey) >= rl.Hourly {
+		return false, "hour", nil
+	}
+	if rl.Daily > 0 && current(dayKey) >= rl.Daily {
+		return false, "day", nil
+	}
+
+	consume := func(key, scope string) error {
+		count, err := cache.Incr(key)
+		if err != nil {
+			return err
+		}
+		if count == 1 {
+			return cache.Expire(key, time.Duration(messageRateLimitTTLSeconds(now, scope))*time.Second)
+		}
+		return nil
+	}
+	if rl.Hourly > 0 {
+		if err := consume(hourKey, "hour"); err != nil {
+			return true, "", err
+		}
+	}
+	if rl.Daily > 0 {
+		if err := consume(dayKey, "day"); err != nil {
+			return true, "", err
+		}
+	}
+	return true, "", nil
+}
+
 // reserveMessageRateLimitSlot atomically checks and consumes one rate-limit slot.
-// When Redis is unavailable, it fails open to avoid breaking transactional flows.
+// Without Redis the counters live in process memory; on errors it fails open to avoid breaking transactional flows.
 func reserveMessageRateLimitSlot(prefix, recipient string, rl config.MessageRateLimit) (bool, time.Time, error) {
 	if rl.Hourly <= 0 && rl.Daily <= 0 {
 		return true, time.Time{}, nil
 	}
-	if cache.RedisClient == nil {
-		return true, time.Time{}, nil
-	}
-
 	now := time.Now()
 	hourKey, dayKey := messageRateLimitKeys(prefix, recipient, now)
+	if cache.RedisClient == nil {
+		allowed, scope, err := reserveMessageRateLimitMemorySlot(hourKey, dayKey, rl, now)
+		if err != nil || allowed {
+			return true, time.Time{}, err
+		}
+		return false, messageRateLimitAvailableAt(now, scope), nil
+	}
 	values, err := reserveMessageRateLimitScript.Run(
 		cache.RedisClient.Context(),
 		cache.RedisClient,
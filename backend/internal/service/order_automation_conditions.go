@@ -0,0 +1,330 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"auralogic/internal/models"
+)
+
+type orderAutomationFieldKind string
+
+const (
+	orderAutomationFieldString orderAutomationFieldKind = "string"
+	orderAutomationFieldNumber orderAutomationFieldKind = "number"
+	orderAutomationFieldList   orderAutomationFieldKind = "list"
+)
+
+// OrderAutomationFieldMeta 条件可用字段，供前端条件构建器使用
+type OrderAutomationFieldMeta struct {
+	Field     string   `json:"field"`
+	Kind      string   `json:"kind"`
+	Operators []string `json:"operators"`
+}
+
+var orderAutomationOperatorsByKind = map[orderAutomationFieldKind][]string{
+	orderAutomationFieldString: {"eq", "neq", "in", "not_in", "contains", "not_contains"},
+	orderAutomationFieldNumber: {"eq", "neq", "gt", "gte", "lt", "lte"},
+	orderAutomationFieldList:   {"contains", "not_contains", "in", "not_in"},
+}
+
+// 字段顺序即前端展示顺序
+var orderAutomationFields = []struct {
+	name string
+	kind orderAutomationFieldKind
+}{
+	{"status", orderAutomationFieldString},
+	{"sub_status", orderAutomationFieldString},
+	{"country", orderAutomationFieldString},
+	{"province", orderAutomationFieldString},
+	{"city", orderAutomationFieldString},
+	{"currency", orderAutomationFieldString},
+	{"source", orderAutomationFieldString},
+	{"promo_code", orderAutomationFieldString},
+	{"user_email", orderAutomationFieldString},
+	{"total_amount_minor", orderAutomationFieldNumber},
+	{"discount_amount_minor", orderAutomationFieldNumber},
+	{"item_quantity", orderAutomationFieldNumber},
+	{"line_count", orderAutomationFieldNumber},
+	{"total_weight_grams", orderAutomationFieldNumber},
+	{"sku", orderAutomationFieldList},
+	{"product_type", orderAutomationFieldList},
+	{"tags", orderAutomationFieldList},
+}
+
+// OrderAutomationFields 条件字段及各自支持的运算符
+func OrderAutomationFields() []OrderAutomationFieldMeta {
+	fields := make([]OrderAutomationFieldMeta, 0, len(orderAutomationFields))
+	for _, field := range orderAutomationFields {
+		fields = append(fields, OrderAutomationFieldMeta{
+			Field:     field.name,
+			Kind:      string(field.kind),
+			Operators: orderAutomationOperatorsByKind[field.kind],
+		})
+	}
+	return fields
+}
+
+func lookupOrderAutomationField(name string) (orderAutomationFieldKind, bool) {
+	for _, field := range orderAutomationFields {
+		if field.name == name {
+			return field.kind, true
+		}
+	}
+	return "", false
+}
+
+// orderAutomationFieldValue 取订单字段值：string / float64 / []string
+func orderAutomationFieldValue(order *models.Order, field string) interface{} {
+	switch field {
+	case "status":
+		return string(order.Status)
+	case "sub_status":
+		return order.SubStatus
+	case "country":
+		return order.ReceiverCountry
+	case "province":
+		return order.ReceiverProvince
+	case "city":
+		return order.ReceiverCity
+	case "currency":
+		return order.Currency
+	case "source":
+		return order.Source
+	case "promo_code":
+		return order.PromoCodeStr
+	case "user_email":
+		return order.UserEmail
+	case "total_amount_minor":
+		return float64(order.TotalAmount)
+	case "discount_amount_minor":
+		return float64(order.DiscountAmount)
+	case "item_quantity":
+		total := 0
+		for _, item := range order.Items {
+			total += item.Quantity
+		}
+		return float64(total)
+	case "line_count":
+		return float64(len(order.Items))
+	case "total_weight_grams":
+		return float64(order.TotalWeightGrams)
+	case "sku":
+		values := make([]string, 0, len(order.Items))
+		for _, item := range order.Items {
+			values = append(values, item.SKU)
+		}
+		return values
+	case "product_type":
+		values := make([]string, 0, len(order.Items))
+		for _, item := range order.Items {
+			productType := string(item.ProductType)
+			if productType == "" {
+				productType = string(models.ProductTypePhysical)
+			}
+			values = append(values, productType)
+		}
+		return values
+	case "tags":
+		return append([]string(nil), order.Tags...)
+	}
+	return nil
+}
+
+// validateOrderAutomationCondition 校验条件并规范化 value（in/not_in 统一为字符串数组）
+func validateOrderAutomationCondition(condition *models.OrderAutomationCondition) error {
+	condition.Field = strings.TrimSpace(condition.Field)
+	condition.Operator = strings.ToLower(strings.TrimSpace(condition.Operator))
+	kind, ok := lookupOrderAutomationField(condition.Field)
+	if !ok {
+		return newOrderAutomationConditionError(condition, "unknown field")
+	}
+	value, reason := normalizeAutomationConditionValue(kind, condition.Operator, condition.Value)
+	if reason != "" {
+		return newOrderAutomationConditionError(condition, reason)
+	}
+	condition.Value = value
+	return nil
+}
+
+// normalizeAutomationConditionValue 订单/工单自动化共用：校验运算符并规范化 value，失败时返回原因
+func normalizeAutomationConditionValue(kind orderAutomationFieldKind, operator string, value interface{}) (interface{}, string) {
+	supported := false
+	for _, candidate := range orderAutomationOperatorsByKind[kind] {
+		if candidate == operator {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, "operator not supported for this field"
+	}
+
+	switch {
+	case kind == orderAutomationFieldNumber:
+		number, ok := orderAutomationNumber(value)
+		if !ok {
+			return nil, "value must be a number"
+		}
+		return number, ""
+	case operator == "in" || operator == "not_in":
+		values := orderAutomationStringList(value)
+		if len(values) == 0 {
+			return nil, "value must be a non-empty list"
+		}
+		return values, ""
+	default:
+		text, ok := orderAutomationScalarString(value)
+		if !ok {
+			return nil, "value must be a string"
+		}
+		return text, ""
+	}
+}
+
+// evaluateOrderAutomationCondition 条件是否成立，同时返回订单实际值用于试运行展示
+func evaluateOrderAutomationCondition(order *models.Order, condition models.OrderAutomationCondition) (bool, interface{}) {
+	actual := orderAutomationFieldValue(order, condition.Field)
+	return matchAutomationValue(actual, condition.Operator, condition.Value), actual
+}
+
+// matchAutomationValue 按实际值类型（float64 / string / []string）判断条件是否成立
+func matchAutomationValue(actual interface{}, operator string, expected interface{}) bool {
+	switch typed := actual.(type) {
+	case float64:
+		number, ok := orderAutomationNumber(expected)
+		if !ok {
+			return false
+		}
+		switch operator {
+		case "eq":
+			return typed == number
+		case "neq":
+			return typed != number
+		case "gt":
+			return typed > number
+		case "gte":
+			return typed >= number
+		case "lt":
+			return typed < number
+		case "lte":
+			return typed <= number
+		}
+	case string:
+		switch operator {
+		case "eq", "neq":
+			text, _ := orderAutomationScalarString(expected)
+			return strings.EqualFold(strings.TrimSpace(typed), text) == (operator == "eq")
+		case "in", "not_in":
+			return orderAutomationContainsFold(orderAutomationStringList(expected), typed) == (operator == "in")
+		case "contains", "not_contains":
+			text, _ := orderAutomationScalarString(expected)
+			found := strings.Contains(strings.ToLower(typed), strings.ToLower(text))
+			return found == (operator == "contains")
+		}
+	case []string:
+		switch operator {
+		case "contains", "not_contains":
+			text, _ := orderAutomationScalarString(expected)
+			return orderAutomationContainsFold(typed, text) == (operator == "contains")
+		case "in", "not_in":
+			found := false
+			for _, value := range orderAutomationStringList(expected) {
+				if orderAutomationContainsFold(typed, value) {
+					found = true
+					break
+				}
+			}
+			return found == (operator == "in")
+		}
+	}
+	return false
+}
+
+// matchOrderAutomationRule 按 match_type 组合条件；没有条件时总是命中
+func matchOrderAutomationRule(rule *models.OrderAutomationRule, order *models.Order) bool {
+	if len(rule.Conditions) == 0 {
+		return true
+	}
+	matchAny := rule.MatchType == "any"
+	for _, condition := range rule.Conditions {
+		matched, _ := evaluateOrderAutomationCondition(order, condition)
+		if matchAny && matched {
+			return true
+		}
+		if !matchAny && !matched {
+			return false
+		}
+	}
+	return !matchAny
+}
+
+func orderAutomationNumber(value interface{}) (float64, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, true
+	case float32:
+		return float64(typed), true
+	case int:
+		return float64(typed), true
+	case int64:
+		return float64(typed), true
+	case uint:
+		return float64(typed), true
+	case json.Number:
+		number, err := typed.Float64()
+		return number, err == nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+func orderAutomationScalarString(value interface{}) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return strings.TrimSpace(typed), true
+	case float64, int, int64, json.Number, bool:
+		return fmt.Sprint(typed), true
+	case nil:
+		return "", true
+	}
+	return "", false
+}
+
+// orderAutomationStringList 支持数组或逗号分隔字符串
+func orderAutomationStringList(value interface{}) []string {
+	var raw []string
+	switch typed := value.(type) {
+	case []string:
+		raw = typed
+	case []interface{}:
+		for _, item := range typed {
+			if text, ok := orderAutomationScalarString(item); ok {
+				raw = append(raw, text)
+			}
+		}
+	case string:
+		raw = strings.Split(typed, ",")
+	}
+	values := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func orderAutomationContainsFold(values []string, target string) bool {
+	target = strings.TrimSpace(target)
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), target) {
+			return true
+		}
+	}
+	return false
+}
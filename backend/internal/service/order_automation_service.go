@@ -0,0 +1,626 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+const (
+	maxOrderAutomationConditions = 20
+	maxOrderAutomationActions    = 10
+	maxOrderTagLength            = 50
+	orderAutomationHTTPTimeout   = 10 * time.Second
+)
+
+var ErrOrderAutomationRuleNotFound = bizerr.New("orderAutomation.ruleNotFound", "Automation rule not found")
+
+// OrderAutomationRuleInput 创建/更新/试运行规则参数
+type OrderAutomationRuleInput struct {
+	Name           string                            `json:"name"`
+	Description    string                            `json:"description"`
+	Trigger        models.OrderAutomationTrigger     `json:"trigger"`
+	Enabled        bool                              `json:"enabled"`
+	MatchType      string                            `json:"match_type"`
+	Conditions     []models.OrderAutomationCondition `json:"conditions"`
+	Actions        []models.OrderAutomationAction    `json:"actions"`
+	Priority       int                               `json:"priority"`
+	StopProcessing bool                              `json:"stop_processing"`
+}
+
+// OrderAutomationConditionResult 试运行中单个条件的判断结果
+type OrderAutomationConditionResult struct {
+	models.OrderAutomationCondition
+	Actual  interface{} `json:"actual"`
+	Matched bool        `json:"matched"`
+}
+
+// OrderAutomationDryRunResult 试运行结果：不会修改订单，也不会发出通知
+type OrderAutomationDryRunResult struct {
+	OrderID    uint                                 `json:"order_id"`
+	OrderNo    string                               `json:"order_no"`
+	Matched    bool                                 `json:"matched"`
+	Conditions []OrderAutomationConditionResult     `json:"conditions"`
+	Actions    []models.OrderAutomationActionResult `json:"actions"` // 命中时将执行的动作，Error 为可预见的失败原因
+}
+
+// OrderAutomationRunFilter 执行日志查询条件
+type OrderAutomationRunFilter struct {
+	RuleID  uint
+	OrderNo string
+	Status  string
+}
+
+// OrderAutomationService 订单自动化规则：订阅订单事件，匹配条件后执行打标签、挂起、通知等动作
+type OrderAutomationService struct {
+	db               *gorm.DB
+	subStatusService *OrderSubStatusService
+	httpClient       *http.Client
+}
+
+func NewOrderAutomationService(db *gorm.DB, subStatusService *OrderSubStatusService) *OrderAutomationService {
+	return &OrderAutomationService{
+		db:               db,
+		subStatusService: subStatusService,
+		httpClient:       getPaymentHTTPClient(),
+	}
+}
+
+// OrderAutomationTriggers 支持的触发事件
+func OrderAutomationTriggers() []models.OrderAutomationTrigger {
+	return []models.OrderAutomationTrigger{
+		models.OrderAutomationTriggerOrderCreated,
+		models.OrderAutomationTriggerOrderPaid,
+		models.OrderAutomationTriggerStatusChanged,
+	}
+}
+
+// OrderAutomationActionTypes 支持的动作类型
+func OrderAutomationActionTypes() []models.OrderAutomationActionType {
+	return []models.OrderAutomationActionType{
+		models.OrderAutomationActionAddTag,
+		models.OrderAutomationActionRemoveTag,
+		models.OrderAutomationActionSetSubStatus,
+		models.OrderAutomationActionAdminRemark,
+		models.OrderAutomationActionNotifySlack,
+		models.OrderAutomationActionWebhook,
+	}
+}
+
+// SupportedOrderAutomationPlaceholders 通知/备注消息支持的占位符
+func SupportedOrderAutomationPlaceholders() []string {
+	return []string{
+		"{{order_no}}",
+		"{{status}}",
+		"{{sub_status}}",
+		"{{total}}",
+		"{{currency}}",
+		"{{country}}",
+		"{{user_email}}",
+		"{{rule_name}}",
+		"{{trigger}}",
+	}
+}
+
+func isKnownOrderAutomationTrigger(trigger models.OrderAutomationTrigger) bool {
+	for _, item := range OrderAutomationTriggers() {
+		if item == trigger {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *OrderAutomationService) validateInput(input *OrderAutomationRuleInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Description = strings.TrimSpace(input.Description)
+	input.MatchType = strings.ToLower(strings.TrimSpace(input.MatchType))
+	if input.Name == "" {
+		return bizerr.New("orderAutomation.nameRequired", "Rule name is required")
+	}
+	if !isKnownOrderAutomationTrigger(input.Trigger) {
+		return bizerr.Newf("orderAutomation.triggerInvalid", "Invalid trigger: %s", input.Trigger).
+			WithParams(map[string]interface{}{"trigger": input.Trigger})
+	}
+	if input.MatchType == "" {
+		input.MatchType = "all"
+	}
+	if input.MatchType != "all" && input.MatchType != "any" {
+		return bizerr.New("orderAutomation.matchTypeInvalid", "Match type must be all or any")
+	}
+	if len(input.Conditions) > maxOrderAutomationConditions {
+		return bizerr.Newf("orderAutomation.tooManyConditions", "At most %d conditions are allowed", maxOrderAutomationConditions).
+			WithParams(map[string]interface{}{"max": maxOrderAutomationConditions})
+	}
+	for i := range input.Conditions {
+		if err := validateOrderAutomationCondition(&input.Conditions[i]); err != nil {
+			return err
+		}
+	}
+	if len(input.Actions) == 0 {
+		return bizerr.New("orderAutomation.actionsRequired", "At least one action is required")
+	}
+	if len(input.Actions) > maxOrderAutomationActions {
+		return bizerr.Newf("orderAutomation.tooManyActions", "At most %d actions are allowed", maxOrderAutomationActions).
+			WithParams(map[string]interface{}{"max": maxOrderAutomationActions})
+	}
+	for i := range input.Actions {
+		if err := s.validateAction(&input.Actions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *OrderAutomationService) validateAction(action *models.OrderAutomationAction) error {
+	action.Tag = strings.TrimSpace(action.Tag)
+	action.SubStatus = strings.ToLower(strings.TrimSpace(action.SubStatus))
+	action.URL = strings.TrimSpace(action.URL)
+	action.Message = strings.TrimSpace(action.Message)
+
+	switch action.Type {
+	case models.OrderAutomationActionAddTag, models.OrderAutomationActionRemoveTag:
+		tag, err := normalizeOrderTag(action.Tag)
+		if err != nil || tag == "" {
+			return newOrderAutomationActionError(action, "tag is required (max 50 characters, no commas)")
+		}
+		action.Tag = tag
+	case models.OrderAutomationActionSetSubStatus:
+		if action.SubStatus == "" {
+			return newOrderAutomationActionError(action, "sub_status is required")
+		}
+		if s.subStatusService != nil {
+			if _, err := s.subStatusService.GetByCode(action.SubStatus); err != nil {
+				return err
+			}
+		}
+	case models.OrderAutomationActionAdminRemark:
+		if action.Message == "" {
+			return newOrderAutomationActionError(action, "message is required")
+		}
+	case models.OrderAutomationActionNotifySlack, models.OrderAutomationActionWebhook:
+		parsed, err := url.Parse(action.URL)
+		if action.URL == "" || err != nil || validateExternalURL(parsed) != nil {
+			return newOrderAutomationActionError(action, "url must be a public http(s) address")
+		}
+	default:
+		return newOrderAutomationActionError(action, "unknown action type")
+	}
+	return nil
+}
+
+func newOrderAutomationConditionError(condition *models.OrderAutomationCondition, reason string) error {
+	return bizerr.Newf("orderAutomation.conditionInvalid", "Invalid condition on %s: %s", condition.Field, reason).
+		WithParams(map[string]interface{}{"field": condition.Field, "reason": reason})
+}
+
+func newOrderAutomationActionError(action *models.OrderAutomationAction, reason string) error {
+	return bizerr.Newf("orderAutomation.actionInvalid", "Invalid %s action: %s", action.Type, reason).
+		WithParams(map[string]interface{}{"type": action.Type, "reason": reason})
+}
+
+func applyOrderAutomationRuleInput(rule *models.OrderAutomationRule, input OrderAutomationRuleInput) {
+	rule.Name = input.Name
+	rule.Description = input.Description
+	rule.Trigger = input.Trigger
+	rule.Enabled = input.Enabled
+	rule.MatchType = input.MatchType
+	rule.Conditions = input.Conditions
+	if rule.Conditions == nil {
+		rule.Conditions = []models.OrderAutomationCondition{}
+	}
+	rule.Actions = input.Actions
+	rule.Priority = input.Priority
+	rule.StopProcessing = input.StopProcessing
+}
+
+// ListRules 规则列表，按执行顺序排列
+func (s *OrderAutomationService) ListRules(trigger string) ([]models.OrderAutomationRule, error) {
+	query := s.db.Model(&models.OrderAutomationRule{})
+	if trigger = strings.TrimSpace(trigger); trigger != "" {
+		query = query.Where("trigger_event = ?", trigger)
+	}
+	var rules []models.OrderAutomationRule
+	err := query.Order("priority DESC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (s *OrderAutomationService) GetRule(id uint) (*models.OrderAutomationRule, error) {
+	var rule models.OrderAutomationRule
+	if err := s.db.First(&rule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderAutomationRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *OrderAutomationService) CreateRule(input OrderAutomationRuleInput, createdBy *uint) (*models.OrderAutomationRule, error) {
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	rule := models.OrderAutomationRule{CreatedBy: createdBy}
+	applyOrderAutomationRuleInput(&rule, input)
+	if err := s.db.Create(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *OrderAutomationService) UpdateRule(id uint, input OrderAutomationRuleInput) (*models.OrderAutomationRule, error) {
+	rule, err := s.GetRule(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	applyOrderAutomationRuleInput(rule, input)
+	if err := s.db.Model(rule).
+		Select("name", "description", "trigger_event", "enabled", "match_type", "conditions", "actions", "priority", "stop_processing").
+		Updates(rule).Error; err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule 删除规则，执行日志保留
+func (s *OrderAutomationService) DeleteRule(id uint) error {
+	result := s.db.Delete(&models.OrderAutomationRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderAutomationRuleNotFound
+	}
+	return nil
+}
+
+// ListRuns 执行日志
+func (s *OrderAutomationService) ListRuns(filter OrderAutomationRunFilter, page, limit int) ([]models.OrderAutomationRun, int64, error) {
+	query := s.db.Model(&models.OrderAutomationRun{})
+	if filter.RuleID > 0 {
+		query = query.Where("rule_id = ?", filter.RuleID)
+	}
+	if orderNo := strings.TrimSpace(filter.OrderNo); orderNo != "" {
+		query = query.Where("order_no = ?", orderNo)
+	}
+	if status := strings.TrimSpace(filter.Status); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var runs []models.OrderAutomationRun
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&runs).Error
+	return runs, total, err
+}
+
+// ObserveHook 将订单 Hook 事件映射为自动化触发事件，异步执行
+func (s *OrderAutomationService) ObserveHook(hook string, payload map[string]interface{}) {
+	var triggers []models.OrderAutomationTrigger
+	switch hook {
+	case "order.create.after":
+		triggers = append(triggers, models.OrderAutomationTriggerOrderCreated)
+	case "order.status.changed.after":
+		triggers = append(triggers, models.OrderAutomationTriggerStatusChanged)
+		if isOrderPaidTransition(fmt.Sprint(payload["status_before"]), fmt.Sprint(payload["status_after"])) {
+			triggers = append(triggers, models.OrderAutomationTriggerOrderPaid)
+		}
+	default:
+		return
+	}
+	orderID, ok := orderAutomationPayloadOrderID(payload["order_id"])
+	if !ok {
+		return
+	}
+
+	go func() {
+		defer recoverBackgroundServicePanic("order-automation")
+		for _, trigger := range triggers {
+			if _, err := s.RunTrigger(trigger, orderID); err != nil {
+				log.Printf("order automation failed: trigger=%s order=%d err=%v", trigger, orderID, err)
+			}
+		}
+	}()
+}
+
+func isOrderPaidTransition(before, after string) bool {
+	if models.OrderStatus(before) != models.OrderStatusPendingPayment {
+		return false
+	}
+	switch models.OrderStatus(after) {
+	case models.OrderStatusPendingPayment, models.OrderStatusCancelled, models.OrderStatusRefundPending, models.OrderStatusRefunded:
+		return false
+	}
+	return models.IsKnownOrderStatus(models.OrderStatus(after))
+}
+
+func orderAutomationPayloadOrderID(value interface{}) (uint, bool) {
+	switch typed := value.(type) {
+	case uint:
+		return typed, typed > 0
+	case int:
+		return uint(typed), typed > 0
+	case int64:
+		return uint(typed), typed > 0
+	case float64:
+		return uint(typed), typed > 0
+	case json.Number:
+		id, err := strconv.ParseUint(typed.String(), 10, 64)
+		return uint(id), err == nil && id > 0
+	}
+	return 0, false
+}
+
+// RunTrigger 按优先级依次执行该事件下已启用且命中的规则，返回命中规则的执行日志
+func (s *OrderAutomationService) RunTrigger(trigger models.OrderAutomationTrigger, orderID uint) ([]models.OrderAutomationRun, error) {
+	var rules []models.OrderAutomationRule
+	if err := s.db.Where("trigger_event = ? AND enabled = ?", trigger, true).Order("priority DESC, id ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	var order models.Order
+	if err := s.db.First(&order, orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	runs := make([]models.OrderAutomationRun, 0)
+	for i := range rules {
+		rule := &rules[i]
+		if !matchOrderAutomationRule(rule, &order) {
+			continue
+		}
+		run := s.executeRule(rule, &order, trigger)
+		if err := s.db.Create(&run).Error; err != nil {
+			log.Printf("order automation run log failed: rule=%d order=%s err=%v", rule.ID, order.OrderNo, err)
+		}
+		now := models.NowFunc()
+		s.db.Model(&models.OrderAutomationRule{}).Where("id = ?", rule.ID).UpdateColumns(map[string]interface{}{
+			"run_count":   gorm.Expr("run_count + 1"),
+			"last_run_at": now,
+		})
+		runs = append(runs, run)
+		if rule.StopProcessing {
+			break
+		}
+	}
+	return runs, nil
+}
+
+// executeRule 依次执行动作，单个动作失败不影响后续动作；成功的修改同步到内存中的 order 供后续规则判断
+func (s *OrderAutomationService) executeRule(rule *models.OrderAutomationRule, order *models.Order, trigger models.OrderAutomationTrigger) models.OrderAutomationRun {
+	startedAt := time.Now()
+	run := models.OrderAutomationRun{
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		OrderID:  order.ID,
+		OrderNo:  order.OrderNo,
+		Trigger:  trigger,
+		Results:  make([]models.OrderAutomationActionResult, 0, len(rule.Actions)),
+	}
+	failed := 0
+	for _, action := range rule.Actions {
+		result := models.OrderAutomationActionResult{Type: action.Type}
+		detail, err := s.executeAction(rule, order, trigger, action)
+		result.Detail = detail
+		if err != nil {
+			result.Error = err.Error()
+			failed++
+		} else {
+			result.Success = true
+		}
+		run.Results = append(run.Results, result)
+	}
+	switch {
+	case failed == 0:
+		run.Status = "success"
+	case failed == len(rule.Actions):
+		run.Status = "failed"
+	default:
+		run.Status = "partial"
+	}
+	run.DurationMs = time.Since(startedAt).Milliseconds()
+	return run
+}
+
+func (s *OrderAutomationService) executeAction(rule *models.OrderAutomationRule, order *models.Order, trigger models.OrderAutomationTrigger, action models.OrderAutomationAction) (string, error) {
+	switch action.Type {
+	case models.OrderAutomationActionAddTag, models.OrderAutomationActionRemoveTag:
+		return s.updateOrderTags(order, action.Tag, action.Type == models.OrderAutomationActionAddTag)
+	case models.OrderAutomationActionSetSubStatus:
+		if s.subStatusService == nil {
+			return "", fmt.Errorf("sub-status service is not available")
+		}
+		change, err := s.subStatusService.SetOrderSubStatus(order.ID, action.SubStatus, "automation: "+rule.Name)
+		if err != nil {
+			return "", err
+		}
+		order.SubStatus = change.Order.SubStatus
+		order.SubStatusFor = change.Order.SubStatusFor
+		return action.SubStatus, nil
+	case models.OrderAutomationActionAdminRemark:
+		return s.addOrderNote(order, fmt.Sprintf("[automation:%s] %s", rule.Name, renderOrderAutomationMessage(action.Message, rule, order, trigger)))
+	case models.OrderAutomationActionNotifySlack:
+		message := action.Message
+		if message == "" {
+			message = "[{{rule_name}}] Order {{order_no}} ({{status}}, {{total}} {{currency}})"
+		}
+		text := renderOrderAutomationMessage(message, rule, order, trigger)
+		return text, s.postJSON(action.URL, map[string]interface{}{"text": text})
+	case models.OrderAutomationActionWebhook:
+		body := map[string]interface{}{
+			"event":     "order.automation",
+			"rule_id":   rule.ID,
+			"rule_name": rule.Name,
+			"trigger":   trigger,
+			"order":     buildOrderAutomationOrderSummary(order),
+			"sent_at":   models.NowFunc().UTC().Format(time.RFC3339),
+		}
+		if action.Message != "" {
+			body["message"] = renderOrderAutomationMessage(action.Message, rule, order, trigger)
+		}
+		return action.URL, s.postJSON(action.URL, body)
+	}
+	return "", fmt.Errorf("unknown action type %q", action.Type)
+}
+
+func (s *OrderAutomationService) updateOrderTags(order *models.Order, tag string, add bool) (string, error) {
+	tags, err := UpdateOrderTag(s.db, order.ID, tag, add)
+	if err != nil {
+		return "", err
+	}
+	order.Tags = tags
+	return tag, nil
+}
+
+// addOrderNote 以系统备注的形式记录自动化规则的消息
+func (s *OrderAutomationService) addOrderNote(order *models.Order, line string) (string, error) {
+	if err := AddOrderNoteTx(s.db, order.ID, nil, models.OrderNoteSourceAutomation, line); err != nil {
+		return "", err
+	}
+	return line, nil
+}
+
+func (s *OrderAutomationService) postJSON(target string, body interface{}) error {
+	parsed, err := url.Parse(target)
+	if err != nil || validateExternalURL(parsed) != nil {
+		return fmt.Errorf("url is not allowed")
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), orderAutomationHTTPTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, parsed.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AuraLogic-Automation/1.0")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func buildOrderAutomationOrderSummary(order *models.Order) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                 order.ID,
+		"order_no":           order.OrderNo,
+		"status":             order.Status,
+		"sub_status":         order.SubStatus,
+		"total_amount_minor": order.TotalAmount,
+		"currency":           order.Currency,
+		"country":            order.ReceiverCountry,
+		"total_weight_grams": order.TotalWeightGrams,
+		"tags":               order.Tags,
+		"created_at":         order.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func renderOrderAutomationMessage(message string, rule *models.OrderAutomationRule, order *models.Order, trigger models.OrderAutomationTrigger) string {
+	replacer := strings.NewReplacer(
+		"{{order_no}}", order.OrderNo,
+		"{{status}}", string(order.Status),
+		"{{sub_status}}", order.SubStatus,
+		"{{total}}", money.MinorToString(order.TotalAmount),
+		"{{currency}}", order.Currency,
+		"{{country}}", order.ReceiverCountry,
+		"{{user_email}}", order.UserEmail,
+		"{{rule_name}}", rule.Name,
+		"{{trigger}}", string(trigger),
+	)
+	return replacer.Replace(message)
+}
+
+// DryRun 用指定订单试运行规则，只计算条件和将执行的动作，不产生任何副作用
+func (s *OrderAutomationService) DryRun(input OrderAutomationRuleInput, orderNo string) (*OrderAutomationDryRunResult, error) {
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	var order models.Order
+	if err := s.db.Where("order_no = ?", strings.TrimSpace(orderNo)).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newOrderNotFoundError()
+		}
+		return nil, err
+	}
+
+	rule := &models.OrderAutomationRule{}
+	applyOrderAutomationRuleInput(rule, input)
+	result := &OrderAutomationDryRunResult{
+		OrderID:    order.ID,
+		OrderNo:    order.OrderNo,
+		Matched:    matchOrderAutomationRule(rule, &order),
+		Conditions: make([]OrderAutomationConditionResult, 0, len(rule.Conditions)),
+		Actions:    []models.OrderAutomationActionResult{},
+	}
+	for _, condition := range rule.Conditions {
+		matched, actual := evaluateOrderAutomationCondition(&order, condition)
+		result.Conditions = append(result.Conditions, OrderAutomationConditionResult{
+			OrderAutomationCondition: condition,
+			Actual:                   actual,
+			Matched:                  matched,
+		})
+	}
+	if !result.Matched {
+		return result, nil
+	}
+	for _, action := range rule.Actions {
+		result.Actions = append(result.Actions, s.planAction(rule, &order, input.Trigger, action))
+	}
+	return result, nil
+}
+
+func (s *OrderAutomationService) planAction(rule *models.OrderAutomationRule, order *models.Order, trigger models.OrderAutomationTrigger, action models.OrderAutomationAction) models.OrderAutomationActionResult {
+	planned := models.OrderAutomationActionResult{Type: action.Type, Success: true}
+	switch action.Type {
+	case models.OrderAutomationActionAddTag, models.OrderAutomationActionRemoveTag:
+		planned.Detail = action.Tag
+	case models.OrderAutomationActionSetSubStatus:
+		planned.Detail = action.SubStatus
+		if s.subStatusService != nil {
+			subStatus, err := s.subStatusService.GetByCode(action.SubStatus)
+			if err == nil && subStatus.Status != order.Status {
+				planned.Success = false
+				planned.Error = fmt.Sprintf("sub-status %s requires order status %s", subStatus.Code, subStatus.Status)
+			}
+		}
+	case models.OrderAutomationActionAdminRemark:
+		planned.Detail = renderOrderAutomationMessage(action.Message, rule, order, trigger)
+	case models.OrderAutomationActionNotifySlack, models.OrderAutomationActionWebhook:
+		planned.Detail = action.URL
+		if action.Message != "" {
+			planned.Detail += " " + renderOrderAutomationMessage(action.Message, rule, order, trigger)
+		}
+	}
+	return planned
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestOrderAutomationRunTriggerAppliesMatchingRules(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.OrderNote{}, &models.OrderSubStatus{}, &models.OrderAutomationRule{}, &models.OrderAutomationRun{})
+	subStatusSvc := NewOrderSubStatusService(db, nil)
+	if _, err := subStatusSvc.Create(OrderSubStatusInput{Code: "on_hold", Status: models.OrderStatusPending, Name: "On hold"}); err != nil {
+		t.Fatalf("create sub-status failed: %v", err)
+	}
+	svc := NewOrderAutomationService(db, subStatusSvc)
+
+	highValue, err := svc.CreateRule(OrderAutomationRuleInput{
+		Name:     "High value US",
+		Trigger:  models.OrderAutomationTriggerOrderPaid,
+		Enabled:  true,
+		Priority: 10,
+		Conditions: []models.OrderAutomationCondition{
+			{Field: "country", Operator: "eq", Value: "us"},
+			{Field: "total_amount_minor", Operator: "gt", Value: "10000"},
+		},
+		Actions: []models.OrderAutomationAction{
+			{Type: models.OrderAutomationActionAddTag, Tag: "high-value"},
+			{Type: models.OrderAutomationActionSetSubStatus, SubStatus: "on_hold"},
+			{Type: models.OrderAutomationActionAdminRemark, Message: "Review {{order_no}} ({{total}} {{currency}})"},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("create rule failed: %v", err)
+	}
+	// 依赖上一条规则添加的标签
+	followUp, err := svc.CreateRule(OrderAutomationRuleInput{
+		Name:           "Tagged follow-up",
+		Trigger:        models.OrderAutomationTriggerOrderPaid,
+		Enabled:        true,
+		StopProcessing: true,
+		Conditions:     []models.OrderAutomationCondition{{Field: "tags", Operator: "contains", Value: "HIGH-VALUE"}},
+		Actions:        []models.OrderAutomationAction{{Type: models.OrderAutomationActionAddTag, Tag: "reviewed"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("create rule failed: %v", err)
+	}
+	if _, err := svc.CreateRule(OrderAutomationRuleInput{
+		Name:    "Never reached",
+		Trigger: models.OrderAutomationTriggerOrderPaid,
+		Enabled: true,
+		Actions: []models.OrderAutomationAction{{Type: models.OrderAutomationActionAddTag, Tag: "unreachable"}},
+	}, nil); err != nil {
+		t.Fatalf("create rule failed: %v", err)
+	}
+	if _, err := svc.CreateRule(OrderAutomationRuleInput{
+		Name:     "Disabled",
+		Trigger:  models.OrderAutomationTriggerOrderPaid,
+		Priority: 100,
+		Actions:  []models.OrderAutomationAction{{Type: models.OrderAutomationActionAddTag, Tag: "disabled"}},
+	}, nil); err != nil {
+		t.Fatalf("create rule failed: %v", err)
+	}
+
+	order := models.Order{OrderNo: "AUTO-1", Status: models.OrderStatusPending, ReceiverCountry: "US", TotalAmount: 15000, Currency: "USD"}
+	small := models.Order{OrderNo: "AUTO-2", Status: models.OrderStatusPending, ReceiverCountry: "US", TotalAmount: 5000, Currency: "USD"}
+	for _, o := range []*models.Order{&order, &small} {
+		if err := db.Create(o).Error; err != nil {
+			t.Fatalf("create order failed: %v", err)
+		}
+	}
+
+	runs, err := svc.RunTrigger(models.OrderAutomationTriggerOrderPaid, order.ID)
+	if err != nil {
+		t.Fatalf("run trigger failed: %v", err)
+	}
+	if len(runs) != 2 || runs[0].RuleID != highValue.ID || runs[1].RuleID != followUp.ID {
+		t.Fatalf("expected high value and follow-up rules to run, got %+v", runs)
+	}
+	if runs[0].Status != "success" || len(runs[0].Results) != 3 {
+		t.Fatalf("unexpected run result: %+v", runs[0])
+	}
+
+	var reloaded models.Order
+	if err := db.First(&reloaded, order.ID).Error; err != nil {
+		t.Fatalf("reload order failed: %v", err)
+	}
+	if len(reloaded.Tags) != 2 || reloaded.Tags[0] != "high-value" || reloaded.Tags[1] != "reviewed" {
+		t.Fatalf("unexpected tags: %v", reloaded.Tags)
+	}
+	if reloaded.SubStatus != "on_hold" {
+		t.Fatalf("expected order on hold, got %q", reloaded.SubStatus)
+	}
+	var note models.OrderNote
+	if err := db.Where("order_id = ?", order.ID).First(&note).Error; err != nil {
+		t.Fatalf("load order note failed: %v", err)
+	}
+	if note.Content != "[automation:High value US] Review AUTO-1 (150.00 USD)" || note.Source != models.OrderNoteSourceAutomation {
+		t.Fatalf("unexpected order note: %+v", note)
+	}
+
+	runs, err = svc.RunTrigger(models.OrderAutomationTriggerOrderPaid, small.ID)
+	if err != nil {
+		t.Fatalf("run trigger failed: %v", err)
+	}
+	if len(runs) != 1 || runs[0].RuleName != "Never reached" {
+		t.Fatalf("expected only the unconditional rule for small order, got %+v", runs)
+	}
+
+	logged, total, err := svc.ListRuns(OrderAutomationRunFilter{OrderNo: "AUTO-1"}, 1, 20)
+	if err != nil {
+		t.Fatalf("list runs failed: %v", err)
+	}
+	if total != 2 || len(logged) != 2 {
+		t.Fatalf("expected 2 logged runs, got %d", total)
+	}
+	rule, err := svc.GetRule(highValue.ID)
+	if err != nil {
+		t.Fatalf("get rule failed: %v", err)
+	}
+	if rule.RunCount != 1 || rule.LastRunAt == nil {
+		t.Fatalf("expected run stats to be updated, got %+v", rule)
+	}
+}
+
+func TestOrderAutomationDryRunHasNoSideEffects(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.OrderNote{}, &models.OrderSubStatus{}, &models.OrderAutomationRule{}, &models.OrderAutomationRun{})
+	subStatusSvc := NewOrderSubStatusService(db, nil)
+	if _, err := subStatusSvc.Create(OrderSubStatusInput{Code: "on_hold", Status: models.OrderStatusPending, Name: "On hold"}); err != nil {
+		t.Fatalf("create sub-status failed: %v", err)
+	}
+	svc := NewOrderAutomationService(db, subStatusSvc)
+
+	order := models.Order{
+		OrderNo:         "DRY-1",
+		Status:          models.OrderStatusShipped,
+		ReceiverCountry: "DE",
+		TotalAmount:     2000,
+		Items:           []models.OrderItem{{SKU: "MUG", Quantity: 2}, {SKU: "TEE", Quantity: 1}},
+	}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+
+	result, err := svc.DryRun(OrderAutomationRuleInput{
+		Name:      "EU mugs",
+		Trigger:   models.OrderAutomationTriggerStatusChanged,
+		MatchType: "any",
+		Conditions: []models.OrderAutomationCondition{
+			{Field: "country", Operator: "in", Value: "FR, DE"},
+			{Field: "item_quantity", Operator: "gte", Value: 10},
+		},
+		Actions: []models.OrderAutomationAction{
+			{Type: models.OrderAutomationActionAddTag, Tag: "eu"},
+			{Type: models.OrderAutomationActionSetSubStatus, SubStatus: "on_hold"},
+		},
+	}, "DRY-1")
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if !result.Matched || len(result.Conditions) != 2 || !result.Conditions[0].Matched || result.Conditions[1].Matched {
+		t.Fatalf("unexpected condition results: %+v", result)
+	}
+	if len(result.Actions) != 2 || !result.Actions[0].Success || result.Actions[1].Success {
+		t.Fatalf("expected sub-status action to be reported as failing for shipped order, got %+v", result.Actions)
+	}
+
+	var reloaded models.Order
+	if err := db.First(&reloaded, order.ID).Error; err != nil {
+		t.Fatalf("reload order failed: %v", err)
+	}
+	if len(reloaded.Tags) != 0 {
+		t.Fatalf("dry run must not modify the order, got tags %v", reloaded.Tags)
+	}
+	var runCount int64
+	db.Model(&models.OrderAutomationRun{}).Count(&runCount)
+	if runCount != 0 {
+		t.Fatalf("dry run must not write run logs, got %d", runCount)
+	}
+
+	_, err = svc.DryRun(OrderAutomationRuleInput{Name: "x", Trigger: models.OrderAutomationTriggerOrderCreated, Actions: []models.OrderAutomationAction{{Type: models.OrderAutomationActionAddTag, Tag: "x"}}}, "MISSING")
+	requireOrderBizErr(t, err, "order.notFound")
+}
+
+func TestOrderAutomationRuleValidation(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.OrderSubStatus{}, &models.OrderAutomationRule{})
+	svc := NewOrderAutomationService(db, NewOrderSubStatusService(db, nil))
+	tag := []models.OrderAutomationAction{{Type: models.OrderAutomationActionAddTag, Tag: "x"}}
+
+	_, err := svc.CreateRule(OrderAutomationRuleInput{Name: "x", Trigger: "order.unknown", Actions: tag}, nil)
+	requireProductBizErr(t, err, "orderAutomation.triggerInvalid")
+	_, err = svc.CreateRule(OrderAutomationRuleInput{Name: "x", Trigger: models.OrderAutomationTriggerOrderCreated}, nil)
+	requireProductBizErr(t, err, "orderAutomation.actionsRequired")
+	_, err = svc.CreateRule(OrderAutomationRuleInput{Name: "x", Trigger: models.OrderAutomationTriggerOrderCreated, Actions: tag,
+		Conditions: []models.OrderAutomationCondition{{Field: "total_amount_minor", Operator: "contains", Value: "1"}}}, nil)
+	requireProductBizErr(t, err, "orderAutomation.conditionInvalid")
+	_, err = svc.CreateRule(OrderAutomationRuleInput{Name: "x", Trigger: models.OrderAutomationTriggerOrderCreated, Actions: tag,
+		Conditions: []models.OrderAutomationCondition{{Field: "total_amount_minor", Operator: "gt", Value: "abc"}}}, nil)
+	requireProductBizErr(t, err, "orderAutomation.conditionInvalid")
+	_, err = svc.CreateRule(OrderAutomationRuleInput{Name: "x", Trigger: models.OrderAutomationTriggerOrderCreated,
+		Actions: []models.OrderAutomationAction{{Type: models.OrderAutomationActionWebhook, URL: "http://127.0.0.1:8080/hook"}}}, nil)
+	requireProductBizErr(t, err, "orderAutomation.actionInvalid")
+	_, err = svc.CreateRule(OrderAutomationRuleInput{Name: "x", Trigger: models.OrderAutomationTriggerOrderCreated,
+		Actions: []models.OrderAutomationAction{{Type: models.OrderAutomationActionSetSubStatus, SubStatus: "missing"}}}, nil)
+	requireProductBizErr(t, err, "orderSubStatus.notFound")
+
+	if !isOrderPaidTransition("pending_payment", "pending") || isOrderPaidTransition("pending_payment", "cancelled") || isOrderPaidTransition("draft", "pending") {
+		t.Fatalf("unexpected paid transition detection")
+	}
+}
@@ -26,10 +26,10 @@ type OrderCancelService struct {
 	virtualInventorySvc *VirtualInventoryService
 	serialService       *SerialService
 	pluginManager       *PluginManagerService
+	emailService        *EmailService
 	lifecycleMu         sync.Mutex
 	running             bool
-	stopChan            chan struct{}
-	doneChan            chan struct{}
+	scheduler           *BackgroundScheduler
 	checkInterval       time.Duration // 检查间隔
 }
 
@@ -143,27 +143,18 @@ func (s *OrderCancelService) releaseReservedInventoryWithHook(order *models.Orde
 	return releaseErr
 }
 
-// getAutoCancelHours 获取自动取消小时数，未配置时使用默认值
+// getAutoCancelHours 获取全局自动取消小时数，未配置时使用默认值
 func (s *OrderCancelService) getAutoCancelHours() int {
-	if h := s.cfg.Order.AutoCancelHours; h > 0 {
-		return h
-	}
-	return defaultAutoCancelHours
+	return globalAutoCancelHours(s.cfg)
 }
 
 // Start 启动自动取消服务
 func (s *OrderCancelService) Start() {
 	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
 	if s.running {
-		s.lifecycleMu.Unlock()
 		return
 	}
-	stopChan := make(chan struct{})
-	doneChan := make(chan struct{})
-	s.stopChan = stopChan
-	s.doneChan = doneChan
-	s.running = true
-	s.lifecycleMu.Unlock()
 
 	autoCancelHours := s.getAutoCancelHours()
 
@@ -172,59 +163,53 @@ func (s *OrderCancelService) Start() {
 		"check_interval":    s.checkInterval.String(),
 	})
 
-	go func() {
-		defer close(doneChan)
-		runBackgroundServiceWithStopChan("order_cancel.cancelLoop", stopChan, s.cancelLoop)
-	}()
+	scheduler := backgroundSchedulerForService()
+	if err := scheduler.Register(BackgroundJob{
+		Name:       "order_cancel",
+		Interval:   s.checkInterval,
+		RunOnStart: true,
+		LockTTL:    s.checkInterval,
+		Jitter:     s.checkInterval / 10,
+		Run: func() error {
+			s.sendPaymentReminders()
+			s.cancelExpiredOrders()
+			s.expireStaleDrafts()
+			return nil
+		},
+	}); err != nil {
+		log.Printf("[OrderCancel] Failed to register background job: %v", err)
+		return
+	}
+	s.scheduler = scheduler
+	s.running = true
 }
 
 // Stop 停止自动取消服务
 func (s *OrderCancelService) Stop() {
 	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
 	if !s.running {
-		s.lifecycleMu.Unlock()
 		return
 	}
-	stopChan := s.stopChan
-	doneChan := s.doneChan
-	s.stopChan = nil
-	s.doneChan = nil
 	s.running = false
 
 	logger.LogSystemOperation(s.db, "order_cancel_service_stop", "system", nil, nil)
-	close(stopChan)
-	<-doneChan
-	s.lifecycleMu.Unlock()
-}
-
-// cancelLoop 取消循环
-func (s *OrderCancelService) cancelLoop(stopChan <-chan struct{}) {
-	// 启动时立即执行一次
-	s.cancelExpiredOrders()
-
-	ticker := time.NewTicker(s.checkInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-stopChan:
-			return
-		case <-ticker.C:
-			s.cancelExpiredOrders()
-		}
-	}
+	s.scheduler.Remove("order_cancel")
+	s.scheduler = nil
 }
 
 // cancelExpiredOrders 取消过期订单
 func (s *OrderCancelService) cancelExpiredOrders() {
 	autoCancelHours := s.getAutoCancelHours()
 
-	// 计算截止时间
-	cutoffTime := time.Now().Add(-time.Duration(autoCancelHours) * time.Hour)
+	// 计算截止时间：已保存截止时间的订单按各自截止时间，旧订单按全局时限
+	now := time.Now()
+	cutoffTime := now.Add(-time.Duration(autoCancelHours) * time.Hour)
 
 	// 分批查询需要取消的待付款订单，每次最多处理100条
 	var orders []models.Order
-	if err := s.db.Where("status = ? AND created_at < ?", models.OrderStatusPendingPayment, cutoffTime).
+	if err := s.db.Where("status = ?", models.OrderStatusPendingPayment).
+		Where("(payment_deadline_at IS NOT NULL AND payment_deadline_at < ?) OR (payment_deadline_at IS NULL AND created_at < ?)", now, cutoffTime).
 		Limit(100).Find(&orders).Error; err != nil {
 		log.Printf("[OrderCancel] Error querying expired orders: %v", err)
 		return
@@ -236,7 +221,7 @@ func (s *OrderCancelService) cancelExpiredOrders() {
 
 	cancelledCount := 0
 	for _, order := range orders {
-		cancelled, err := s.cancelOrder(&order, autoCancelHours)
+		cancelled, err := s.cancelOrder(&order, orderAutoCancelHours(s.cfg, &order))
 		if err != nil {
 			log.Printf("[OrderCancel] Error cancelling order %s: %v", order.OrderNo, err)
 			continue
@@ -313,19 +298,32 @@ func (s *OrderCancelService) cancelOrder(order *models.Order, autoCancelHours in
 		}
 	}
 
-	result := s.db.Model(order).
-		Where("status = ?", models.OrderStatusPendingPayment).
-		Updates(map[string]interface{}{
-			"status":       models.OrderStatusCancelled,
-			"admin_remark": adminRemark,
-		})
-	if result.Error != nil {
-		return false, result.Error
+	var cancelled bool
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(order).
+			Where("status = ?", models.OrderStatusPendingPayment).
+			Updates(map[string]interface{}{
+				"status":        models.OrderStatusCancelled,
+				"cancel_reason": models.OrderCancelReasonPaymentTimeout,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		cancelled = true
+		return AddOrderNoteTx(tx, order.ID, nil, models.OrderNoteSourceAutoCancel, adminRemark)
+	}); err != nil {
+		return false, err
 	}
-	if result.RowsAffected == 0 {
+	if !cancelled {
 		// 订单状态已被其他流程修改，跳过
 		return false, nil
 	}
+	if err := RecordOrderVoidLedgerTx(s.db, order, "auto_cancel_order"); err != nil {
+		log.Printf("[OrderCancel] Order %s failed to void ledger: %v", order.OrderNo, err)
+	}
 
 	syncUserPurchaseStatsTransitionBestEffort(
 		repository.NewOrderRepository(s.db),
@@ -338,37 +336,7 @@ func (s *OrderCancelService) cancelOrder(order *models.Order, autoCancelHours in
 	)
 
 	// 状态已更新，开始释放资源（即使部分失败也不影响订单状态）
-
-	// 释放物理商品库存
-	for i := range order.Items {
-		item := &order.Items[i]
-		if inventoryID, exists := order.InventoryBindings[i]; exists && inventoryID > 0 {
-			if err := s.releaseReservedInventoryWithHook(order, inventoryID, item.Quantity); err != nil {
-				log.Printf("[OrderCancel] Order %s failed to release inventory %d: %v", order.OrderNo, inventoryID, err)
-			}
-		}
-	}
-
-	// 释放虚拟商品库存
-	if s.virtualInventorySvc != nil {
-		if err := s.virtualInventorySvc.ReleaseStock(order.OrderNo); err != nil {
-			log.Printf("[OrderCancel] Order %s failed to release virtual stock: %v", order.OrderNo, err)
-		}
-	}
-
-	// 释放优惠码
-	if order.PromoCodeID != nil && s.promoCodeRepo != nil {
-		if err := s.promoCodeRepo.ReleaseReserve(*order.PromoCodeID, order.OrderNo); err != nil {
-			log.Printf("[OrderCancel] Order %s failed to release promo code: %v", order.OrderNo, err)
-		}
-	}
-
-	// 删除关联的序列号
-	if s.serialService != nil {
-		if err := s.serialService.DeleteSerialsByOrderID(order.ID); err != nil {
-			log.Printf("[OrderCancel] Order %s failed to delete serials: %v", order.OrderNo, err)
-		}
-	}
+	s.releaseCancelledOrderResources(order)
 
 	logger.LogPaymentOperation(s.db, "order_auto_cancelled", order.ID, map[string]interface{}{
 		"order_no":   order.OrderNo,
@@ -406,3 +374,47 @@ func (s *OrderCancelService) cancelOrder(order *models.Order, autoCancelHours in
 
 	return true, nil
 }
+
+// releaseCancelledOrderResources 释放已取消订单占用的库存、优惠码、序列号与付款金额，失败仅记录日志
+func (s *OrderCancelService) releaseCancelledOrderResources(order *models.Order) {
+	// 释放物理商品库存
+	for i := range order.Items {
+		item := &order.Items[i]
+		if inventoryID, exists := order.InventoryBindings[i]; exists && inventoryID > 0 {
+			if err := s.releaseReservedInventoryWithHook(order, inventoryID, item.Quantity); err != nil {
+				log.Printf("[OrderCancel] Order %s failed to release inventory %d: %v", order.OrderNo, inventoryID, err)
+			}
+		}
+	}
+
+	// 释放虚拟商品库存
+	if s.virtualInventorySvc != nil {
+		if err := s.virtualInventorySvc.ReleaseStock(order.OrderNo); err != nil {
+			log.Printf("[OrderCancel] Order %s failed to release virtual stock: %v", order.OrderNo, err)
+		}
+	}
+
+	// 释放优惠码
+	if order.PromoCodeID != nil && s.promoCodeRepo != nil {
+		if err := s.promoCodeRepo.ReleaseReserve(*order.PromoCodeID, order.OrderNo); err != nil {
+			log.Printf("[OrderCancel] Order %s failed to release promo code: %v", order.OrderNo, err)
+		}
+	}
+
+	// 删除关联的序列号
+	if s.serialService != nil {
+		if err := s.serialService.DeleteSerialsByOrderID(order.ID); err != nil {
+			log.Printf("[OrderCancel] Order %s failed to delete serials: %v", order.OrderNo, err)
+		}
+	}
+
+	// 释放占用的唯一付款金额
+	if err := ReleasePaymentAmountReservationsTx(s.db, order.ID); err != nil {
+		log.Printf("[OrderCancel] Order %s failed to release payment amount: %v", order.OrderNo, err)
+	}
+
+	// 作废未代收的货到付款记录
+	if err := CancelCODCollectionTx(s.db, order.ID); err != nil {
+		log.Printf("[OrderCancel] Order %s failed to cancel COD collection: %v", order.OrderNo, err)
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrderClaimEmailNotVerified = bizerr.New("orderClaim.emailNotVerified", "Please verify your email before claiming orders")
+	ErrOrderClaimNotFound         = bizerr.New("orderClaim.notFound", "No claimable order matches this order number and your email")
+	ErrOrderClaimAlreadyOwned     = bizerr.New("orderClaim.alreadyOwned", "This order already belongs to an account")
+)
+
+// OrderClaimService 将外部平台创建的无账号订单（仅有 user_email）关联到邮箱已验证的账号
+type OrderClaimService struct {
+	db *gorm.DB
+}
+
+func NewOrderClaimService(db *gorm.DB) *OrderClaimService {
+	return &OrderClaimService{db: db}
+}
+
+// claimableEmail 仅已验证的邮箱可认领订单，避免冒用他人邮箱注册后接管订单
+func claimableEmail(user *models.User) (string, bool) {
+	if user == nil || !user.EmailVerified {
+		return "", false
+	}
+	email := normalizeEmail(user.Email)
+	return email, email != ""
+}
+
+// attachOrderTx 将订单归属到用户并同步购买统计
+func attachOrderTx(tx *gorm.DB, order *models.Order, userID uint) error {
+	res := tx.Model(&models.Order{}).
+		Where("id = ? AND user_id IS NULL", order.ID).
+		Update("user_id", userID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrOrderClaimAlreadyOwned
+	}
+	order.UserID = &userID
+	return applyUserPurchaseStatsTransitionTx(tx, nil, order.UserID, order.Status, order.Status, order.Items)
+}
+
+// ClaimByEmail 认领所有 user_email 与用户已验证邮箱一致的无主订单，返回被认领的订单
+func (s *OrderClaimService) ClaimByEmail(user *models.User) ([]models.Order, error) {
+	email, ok := claimableEmail(user)
+	if !ok {
+		return nil, nil
+	}
+
+	var claimed []models.Order
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var orders []models.Order
+		if err := tx.Where("user_id IS NULL AND LOWER(user_email) = ?", email).
+			Order("id ASC").Find(&orders).Error; err != nil {
+			return err
+		}
+		for i := range orders {
+			err := attachOrderTx(tx, &orders[i], user.ID)
+			if errors.Is(err, ErrOrderClaimAlreadyOwned) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			claimed = append(claimed, orders[i])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// ClaimByOrderNo 按订单号手动认领；订单的 user_email 必须与用户已验证邮箱一致
+func (s *OrderClaimService) ClaimByOrderNo(user *models.User, orderNo string) (*models.Order, error) {
+	email, ok := claimableEmail(user)
+	if !ok {
+		return nil, ErrOrderClaimEmailNotVerified
+	}
+	orderNo = strings.TrimSpace(orderNo)
+	if orderNo == "" {
+		return nil, ErrOrderClaimNotFound
+	}
+
+	var order models.Order
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("order_no = ?", orderNo).First(&order).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrderClaimNotFound
+			}
+			return err
+		}
+		// 邮箱不一致时与不存在返回相同错误，避免探测他人订单
+		if normalizeEmail(order.UserEmail) != email {
+			return ErrOrderClaimNotFound
+		}
+		if order.UserID != nil {
+			if *order.UserID == user.ID {
+				return nil
+			}
+			return ErrOrderClaimAlreadyOwned
+		}
+		return attachOrderTx(tx, &order, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestOrderClaimByEmailAndOrderNo(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Order{}, &models.UserPurchaseStat{})
+	svc := NewOrderClaimService(db)
+
+	user := &models.User{UUID: "claim-user", Email: "buyer@example.com", Role: "user", IsActive: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user failed: %v", err)
+	}
+	other := &models.User{UUID: "claim-other", Email: "other@example.com", Role: "user", IsActive: true, EmailVerified: true}
+	if err := db.Create(other).Error; err != nil {
+		t.Fatalf("create other user failed: %v", err)
+	}
+	items := []models.OrderItem{{SKU: "SKU-1", Name: "Item", Quantity: 2}}
+	orders := []*models.Order{
+		{OrderNo: "CLAIM-1", UserEmail: "Buyer@Example.com", Status: models.OrderStatusDraft, Items: items},
+		{OrderNo: "CLAIM-2", UserEmail: "buyer@example.com", Status: models.OrderStatusPending, Items: items},
+		{OrderNo: "CLAIM-3", UserEmail: "buyer@example.com", UserID: &other.ID, Status: models.OrderStatusPending, Items: items},
+		{OrderNo: "CLAIM-4", UserEmail: "stranger@example.com", Status: models.OrderStatusPending, Items: items},
+	}
+	for _, order := range orders {
+		if err := db.Create(order).Error; err != nil {
+			t.Fatalf("create order failed: %v", err)
+		}
+	}
+
+	// 未验证邮箱不自动认领
+	claimed, err := svc.ClaimByEmail(user)
+	if err != nil || len(claimed) != 0 {
+		t.Fatalf("expected no claim before verification, got %d, %v", len(claimed), err)
+	}
+	if _, err := svc.ClaimByOrderNo(user, "CLAIM-1"); !errors.Is(err, ErrOrderClaimEmailNotVerified) {
+		t.Fatalf("expected email not verified error, got %v", err)
+	}
+
+	user.EmailVerified = true
+	claimed, err = svc.ClaimByEmail(user)
+	if err != nil || len(claimed) != 2 {
+		t.Fatalf("expected 2 claimed orders, got %d, %v", len(claimed), err)
+	}
+	var reloaded models.Order
+	db.Where("order_no = ?", "CLAIM-3").First(&reloaded)
+	if reloaded.UserID == nil || *reloaded.UserID != other.ID {
+		t.Fatalf("orders owned by another account must not move, got %+v", reloaded.UserID)
+	}
+	var stat models.UserPurchaseStat
+	if err := db.Where("user_id = ? AND sku = ?", user.ID, "SKU-1").First(&stat).Error; err != nil || stat.Quantity != 4 {
+		t.Fatalf("expected purchase stats to include claimed orders, got %+v, %v", stat, err)
+	}
+
+	// 手动认领：已属于自己的订单幂等，他人订单与邮箱不符的订单被拒绝
+	if _, err := svc.ClaimByOrderNo(user, "CLAIM-2"); err != nil {
+		t.Fatalf("claiming own order should succeed, got %v", err)
+	}
+	if _, err := svc.ClaimByOrderNo(user, "CLAIM-3"); !errors.Is(err, ErrOrderClaimAlreadyOwned) {
+		t.Fatalf("expected already owned error, got %v", err)
+	}
+	if _, err := svc.ClaimByOrderNo(user, "CLAIM-4"); !errors.Is(err, ErrOrderClaimNotFound) {
+		t.Fatalf("expected not found for mismatched email, got %v", err)
+	}
+
+	db.Model(&models.Order{}).Where("order_no = ?", "CLAIM-1").Update("user_id", nil)
+	order, err := svc.ClaimByOrderNo(user, " CLAIM-1 ")
+	if err != nil || order.UserID == nil || *order.UserID != user.ID {
+		t.Fatalf("manual claim failed: %+v, %v", order, err)
+	}
+}
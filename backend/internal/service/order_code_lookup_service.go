@@ -0,0 +1,151 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	OrderCodeLookupSourceVirtualStock = "virtual_stock"
+	OrderCodeLookupSourceSerial       = "serial"
+
+	orderCodeLookupMaxMatches = 50
+)
+
+// OrderCodeLookupMatch 按卡密或序列号反查到的订单
+type OrderCodeLookupMatch struct {
+	Source        string             `json:"source"`
+	ItemID        uint               `json:"item_id"`
+	Code          string             `json:"code"` // 卡密只返回脱敏内容，序列号原样返回
+	ItemStatus    string             `json:"item_status"`
+	ItemName      string             `json:"item_name,omitempty"`
+	DeliveredAt   *time.Time         `json:"delivered_at,omitempty"`
+	FirstViewedAt *time.Time         `json:"first_viewed_at,omitempty"`
+	OrderID       uint               `json:"order_id,omitempty"`
+	OrderNo       string             `json:"order_no,omitempty"`
+	OrderStatus   models.OrderStatus `json:"order_status,omitempty"`
+	UserID        *uint              `json:"user_id,omitempty"`
+	UserEmail     string             `json:"user_email,omitempty"`
+	StoreID       *uint              `json:"store_id,omitempty"`
+}
+
+// OrderCodeLookupService 客服按买家提供的卡密/序列号定位订单和买家
+type OrderCodeLookupService struct {
+	db *gorm.DB
+}
+
+func NewOrderCodeLookupService(db *gorm.DB) *OrderCodeLookupService {
+	return &OrderCodeLookupService{db: db}
+}
+
+// Lookup 卡密按内容哈希精确匹配，序列号按大写精确匹配。
+// storeIDs 非空时只返回这些店铺的订单，未关联订单的库存项也不返回
+func (s *OrderCodeLookupService) Lookup(code string, storeIDs []uint) ([]OrderCodeLookupMatch, error) {
+	matches := []OrderCodeLookupMatch{}
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return matches, nil
+	}
+
+	var stocks []models.VirtualProductStock
+	if err := s.db.Preload("VirtualInventory", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name") }).
+		Where("content_hash = ?", models.HashVirtualStockContent(code)).
+		Order("id DESC").
+		Limit(orderCodeLookupMaxMatches).
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	var serials []models.ProductSerial
+	if err := s.db.Preload("Product", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name") }).
+		Where("serial_number = ?", strings.ToUpper(code)).
+		Limit(orderCodeLookupMaxMatches).
+		Find(&serials).Error; err != nil {
+		return nil, err
+	}
+
+	orderIDs := make([]uint, 0, len(stocks)+len(serials))
+	for _, stock := range stocks {
+		if stock.OrderID != nil {
+			orderIDs = append(orderIDs, *stock.OrderID)
+		}
+	}
+	for _, serial := range serials {
+		orderIDs = append(orderIDs, serial.OrderID)
+	}
+	orders := make(map[uint]models.Order, len(orderIDs))
+	if len(orderIDs) > 0 {
+		var loaded []models.Order
+		if err := s.db.Select("id", "order_no", "status", "user_id", "user_email", "store_id").
+			Where("id IN ?", orderIDs).
+			Find(&loaded).Error; err != nil {
+			return nil, err
+		}
+		for _, order := range loaded {
+			orders[order.ID] = order
+		}
+	}
+	// attach 关联订单并按店铺范围过滤，返回是否保留
+	attach := func(match *OrderCodeLookupMatch, orderID *uint) bool {
+		if orderID == nil {
+			return len(storeIDs) == 0
+		}
+		order, ok := orders[*orderID]
+		if !ok {
+			return len(storeIDs) == 0
+		}
+		if len(storeIDs) > 0 && (order.StoreID == nil || !slices.Contains(storeIDs, *order.StoreID)) {
+			return false
+		}
+		match.OrderID = order.ID
+		match.OrderNo = order.OrderNo
+		match.OrderStatus = order.Status
+		match.UserID = order.UserID
+		match.UserEmail = order.UserEmail
+		match.StoreID = order.StoreID
+		return true
+	}
+
+	for _, stock := range stocks {
+		match := OrderCodeLookupMatch{
+			Source:        OrderCodeLookupSourceVirtualStock,
+			ItemID:        stock.ID,
+			Code:          models.MaskVirtualStockContent(stock.Content),
+			ItemStatus:    string(stock.Status),
+			DeliveredAt:   stock.DeliveredAt,
+			FirstViewedAt: stock.FirstViewedAt,
+		}
+		if stock.VirtualInventory != nil {
+			match.ItemName = stock.VirtualInventory.Name
+		}
+		if attach(&match, stock.OrderID) {
+			matches = append(matches, match)
+		}
+	}
+	for _, serial := range serials {
+		status := "valid"
+		if serial.InvalidatedAt != nil {
+			status = "invalidated"
+		}
+		createdAt := serial.CreatedAt
+		match := OrderCodeLookupMatch{
+			Source:        OrderCodeLookupSourceSerial,
+			ItemID:        serial.ID,
+			Code:          serial.SerialNumber,
+			ItemStatus:    status,
+			DeliveredAt:   &createdAt,
+			FirstViewedAt: serial.FirstViewedAt,
+		}
+		if serial.Product != nil {
+			match.ItemName = serial.Product.Name
+		}
+		orderID := serial.OrderID
+		if attach(&match, &orderID) {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+func TestOrderCodeLookupMatchesStockAndSerialWithinStoreScope(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.VirtualInventory{}, &models.VirtualProductStock{}, &models.ProductSerial{}, &models.Product{}, &models.Order{})
+	storeA, storeB := uint(1), uint(2)
+	userID := uint(5)
+	orderA := models.Order{OrderNo: "ORDER-A", Status: models.OrderStatusCompleted, UserID: &userID, UserEmail: "buyer@example.com", StoreID: &storeA}
+	orderB := models.Order{OrderNo: "ORDER-B", Status: models.OrderStatusCompleted, StoreID: &storeB}
+	for _, order := range []*models.Order{&orderA, &orderB} {
+		if err := db.Create(order).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+	}
+	inventory := models.VirtualInventory{Name: "Game Keys", SKU: "VI-1"}
+	if err := db.Create(&inventory).Error; err != nil {
+		t.Fatalf("create inventory: %v", err)
+	}
+	delivered := time.Now()
+	stocks := []models.VirtualProductStock{
+		{VirtualInventoryID: inventory.ID, Content: "ABC-123-XYZ", Status: models.VirtualStockStatusSold, OrderID: &orderA.ID, OrderNo: orderA.OrderNo, DeliveredAt: &delivered},
+		{VirtualInventoryID: inventory.ID, Content: "ABC-123-XYZ", Status: models.VirtualStockStatusSold, OrderID: &orderB.ID, OrderNo: orderB.OrderNo, DeliveredAt: &delivered},
+		{VirtualInventoryID: inventory.ID, Content: "OTHER-CODE", Status: models.VirtualStockStatusAvailable},
+	}
+	for i := range stocks {
+		if err := db.Create(&stocks[i]).Error; err != nil {
+			t.Fatalf("create stock: %v", err)
+		}
+	}
+	serial := models.ProductSerial{SerialNumber: "PRD0001ABCD", ProductID: 1, OrderID: orderA.ID, ProductCode: "PRD", SequenceNumber: 1, AntiCounterfeitCode: "ABCD"}
+	if err := db.Create(&serial).Error; err != nil {
+		t.Fatalf("create serial: %v", err)
+	}
+
+	svc := NewOrderCodeLookupService(db)
+	matches, err := svc.Lookup("  ABC-123-XYZ ", nil)
+	if err != nil || len(matches) != 2 {
+		t.Fatalf("expected 2 stock matches, got %d err=%v", len(matches), err)
+	}
+	if matches[0].Code != "*******-XYZ" || matches[0].ItemName != "Game Keys" {
+		t.Fatalf("stock code must be masked: %+v", matches[0])
+	}
+
+	scoped, err := svc.Lookup("ABC-123-XYZ", []uint{storeA})
+	if err != nil || len(scoped) != 1 || scoped[0].OrderNo != "ORDER-A" || scoped[0].UserEmail != "buyer@example.com" {
+		t.Fatalf("expected only store A match, got %+v err=%v", scoped, err)
+	}
+
+	serialMatches, err := svc.Lookup("prd0001abcd", nil)
+	if err != nil || len(serialMatches) != 1 || serialMatches[0].Source != OrderCodeLookupSourceSerial || serialMatches[0].OrderNo != "ORDER-A" {
+		t.Fatalf("expected serial match, got %+v err=%v", serialMatches, err)
+	}
+
+	if none, err := svc.Lookup("OTHER-CODE", []uint{storeA}); err != nil || len(none) != 0 {
+		t.Fatalf("unsold stock must be hidden from store-scoped admins, got %+v err=%v", none, err)
+	}
+}
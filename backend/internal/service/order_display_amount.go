@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+// OrderDisplayAmount 订单总额按汇率换算后的参考金额，仅用于展示，不影响结算
+type OrderDisplayAmount struct {
+	Currency    string    `json:"currency"`
+	AmountMinor int64     `json:"amount_minor"`
+	Rate        float64   `json:"rate"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Stale       bool      `json:"stale"`
+	// 换算金额均为约数，前端需标注“≈”
+	Approximate bool `json:"approximate"`
+}
+
+// SetExchangeRateService 注入汇率服务，未注入或未启用时订单不返回换算金额
+func (s *OrderService) SetExchangeRateService(exchangeRates *ExchangeRateService) {
+	s.exchangeRates = exchangeRates
+}
+
+// UserDisplayCurrency 用户设置的展示币种，未设置或查询失败时返回空
+func (s *OrderService) UserDisplayCurrency(userID uint) string {
+	if s == nil || s.userRepo == nil || userID == 0 || !s.exchangeRates.Enabled() {
+		return ""
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user == nil {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(user.DisplayCurrency))
+}
+
+func (s *OrderService) displayBaseCurrency(currency string) string {
+	base := strings.ToUpper(strings.TrimSpace(currency))
+	if base == "" && s.cfg != nil {
+		base = strings.ToUpper(strings.TrimSpace(s.cfg.Order.Currency))
+	}
+	return base
+}
+
+// ConvertedDisplayAmounts 按用户偏好币种（排在最前）与配置的展示币种换算订单总额，汇率不可用的币种跳过
+func (s *OrderService) ConvertedDisplayAmounts(order *models.Order, preferredCurrency string) []OrderDisplayAmount {
+	if s == nil || order == nil || !s.exchangeRates.Enabled() {
+		return nil
+	}
+	currencies := s.exchangeRates.DisplayCurrencies()
+	if preferred := strings.ToUpper(strings.TrimSpace(preferredCurrency)); preferred != "" {
+		ordered := []string{preferred}
+		for _, currency := range currencies {
+			if currency != preferred {
+				ordered = append(ordered, currency)
+			}
+		}
+		currencies = ordered
+	}
+	if len(currencies) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), exchangeRateFetchTimeout)
+	defer cancel()
+	amounts := make([]OrderDisplayAmount, 0, len(currencies))
+	for _, currency := range currencies {
+		if amount := s.convertedDisplayAmount(ctx, order, currency); amount != nil {
+			amounts = append(amounts, *amount)
+		}
+	}
+	return amounts
+}
+
+// ConvertedListDisplayAmounts 订单列表按用户展示币种换算总额，按订单 ID 返回
+func (s *OrderService) ConvertedListDisplayAmounts(orders []models.Order, currency string) map[uint]*OrderDisplayAmount {
+	amounts := make(map[uint]*OrderDisplayAmount)
+	if s == nil || strings.TrimSpace(currency) == "" || !s.exchangeRates.Enabled() {
+		return amounts
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), exchangeRateFetchTimeout)
+	defer cancel()
+	for i := range orders {
+		if amount := s.convertedDisplayAmount(ctx, &orders[i], currency); amount != nil {
+			amounts[orders[i].ID] = amount
+		}
+	}
+	return amounts
+}
+
+// convertedDisplayAmount 按单一币种换算订单总额，同币种或汇率不可用时返回 nil
+func (s *OrderService) convertedDisplayAmount(ctx context.Context, order *models.Order, currency string) *OrderDisplayAmount {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if s == nil || order == nil || currency == "" || !s.exchangeRates.Enabled() {
+		return nil
+	}
+	base := s.displayBaseCurrency(order.Currency)
+	if base == "" || base == currency {
+		return nil
+	}
+	amountMinor, quote, err := s.exchangeRates.ConvertMinor(ctx, order.TotalAmount, base, currency)
+	if err != nil {
+		log.Printf("[Order] Order %s skipped %s display amount: %v", order.OrderNo, currency, err)
+		return nil
+	}
+	return &OrderDisplayAmount{
+		Currency:    currency,
+		AmountMinor: amountMinor,
+		Rate:        quote.Rate,
+		UpdatedAt:   quote.UpdatedAt,
+		Stale:       quote.Stale,
+		Approximate: true,
+	}
+}
+
+// ApplyProductDisplayPrices 按用户展示币种为商品附加换算参考价，下单仍按商品原币种结算
+func (s *OrderService) ApplyProductDisplayPrices(products []models.Product, currency string) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if s == nil || len(products) == 0 || currency == "" || !s.exchangeRates.Enabled() {
+		return
+	}
+	base := s.displayBaseCurrency("")
+	if base == "" || base == currency {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exchangeRateFetchTimeout)
+	defer cancel()
+	quote, err := s.exchangeRates.GetRate(ctx, base, currency)
+	if err != nil {
+		log.Printf("[Order] Skipped %s product display prices: %v", currency, err)
+		return
+	}
+	for i := range products {
+		products[i].DisplayPrice = &models.ProductDisplayPrice{
+			Currency:           currency,
+			PriceMinor:         int64(math.Round(float64(products[i].Price) * quote.Rate)),
+			OriginalPriceMinor: int64(math.Round(float64(products[i].OriginalPrice) * quote.Rate)),
+			Rate:               quote.Rate,
+			UpdatedAt:          quote.UpdatedAt,
+			Stale:              quote.Stale,
+			Approximate:        true,
+		}
+	}
+}
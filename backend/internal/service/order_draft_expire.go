@@ -0,0 +1,192 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+const orderPlatformWebhookTimeout = 10 * time.Second
+
+// OrderPlatformWebhookEventDraftExpired 草稿订单过期取消时回调来源平台的事件名
+const OrderPlatformWebhookEventDraftExpired = "order.draft_expired"
+
+// getDraftExpireHours 草稿表单过期后的宽限小时数，<=0 表示不自动取消草稿
+func (s *OrderCancelService) getDraftExpireHours() int {
+	if s.cfg == nil {
+		return 0
+	}
+	return s.cfg.Order.DraftExpireHours
+}
+
+// expireStaleDrafts 取消表单链接过期且超过宽限期仍未填写的 API 草稿订单
+// 与待付款超时分开统计（cancel_reason=draft_expired）
+func (s *OrderCancelService) expireStaleDrafts() {
+	expireHours := s.getDraftExpireHours()
+	if expireHours <= 0 {
+		return
+	}
+	cutoffTime := models.NowFunc().Add(-time.Duration(expireHours) * time.Hour)
+
+	var orders []models.Order
+	if err := s.db.Where("status = ? AND source = ? AND form_expires_at IS NOT NULL AND form_expires_at < ?",
+		models.OrderStatusDraft, "api", cutoffTime).
+		Limit(100).Find(&orders).Error; err != nil {
+		log.Printf("[OrderCancel] Error querying stale drafts: %v", err)
+		return
+	}
+
+	expiredCount := 0
+	for i := range orders {
+		expired, err := s.expireDraft(&orders[i], expireHours)
+		if err != nil {
+			log.Printf("[OrderCancel] Error expiring draft %s: %v", orders[i].OrderNo, err)
+			continue
+		}
+		if expired {
+			expiredCount++
+		}
+	}
+
+	if expiredCount > 0 {
+		logger.LogSystemOperation(s.db, "order_draft_expire", "system", nil, map[string]interface{}{
+			"expired_count":      expiredCount,
+			"draft_expire_hours": expireHours,
+			"cutoff_time":        cutoffTime.Format(time.RFC3339),
+		})
+	}
+}
+
+// expireDraft 取消单个过期草稿并通知来源平台
+func (s *OrderCancelService) expireDraft(order *models.Order, expireHours int) (bool, error) {
+	beforeStatus := order.Status
+	adminRemark := fmt.Sprintf("System auto-cancelled: shipping form not submitted within %d hours after the link expired", expireHours)
+
+	var expired bool
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(order).
+			Where("status = ?", models.OrderStatusDraft).
+			Updates(map[string]interface{}{
+				"status":        models.OrderStatusCancelled,
+				"cancel_reason": models.OrderCancelReasonDraftExpired,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		expired = true
+		return AddOrderNoteTx(tx, order.ID, nil, models.OrderNoteSourceAutoCancel, adminRemark)
+	}); err != nil {
+		return false, err
+	}
+	if !expired {
+		// 用户已提交表单或订单被其他流程修改
+		return false, nil
+	}
+	if err := RecordOrderVoidLedgerTx(s.db, order, "draft_expired"); err != nil {
+		log.Printf("[OrderCancel] Order %s failed to void ledger: %v", order.OrderNo, err)
+	}
+
+	syncUserPurchaseStatsTransitionBestEffort(
+		repository.NewOrderRepository(s.db),
+		order.UserID,
+		order.UserID,
+		beforeStatus,
+		models.OrderStatusCancelled,
+		order.Items,
+		"draft_expired",
+	)
+	s.releaseCancelledOrderResources(order)
+
+	logger.LogSystemOperation(s.db, "draft_expired", "order", &order.ID, map[string]interface{}{
+		"order_no":        order.OrderNo,
+		"source_platform": order.SourcePlatform,
+		"form_expires_at": order.FormExpiresAt,
+	})
+	EmitOrderStatusChangedAfterHookAsync(s.pluginManager, s.buildInventoryHookExecutionContext(order), order, beforeStatus, models.OrderStatusCancelled, map[string]interface{}{
+		"source":             "order_draft_expire",
+		"trigger_action":     "order.draft_expire",
+		"draft_expire_hours": expireHours,
+		"admin_remark":       adminRemark,
+	})
+
+	go s.notifySourcePlatform(order, OrderPlatformWebhookEventDraftExpired)
+	return true, nil
+}
+
+// notifySourcePlatform 向订单来源平台 API 密钥配置的回调地址推送订单事件
+func (s *OrderCancelService) notifySourcePlatform(order *models.Order, event string) {
+	if order.SourcePlatform == "" {
+		return
+	}
+	var key models.APIKey
+	err := s.db.Select("id", "platform", "webhook_url").
+		Where("platform = ? AND is_active = ? AND webhook_url <> ''", order.SourcePlatform, true).
+		Order("id ASC").
+		First(&key).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[OrderCancel] Order %s failed to load platform webhook: %v", order.OrderNo, err)
+		}
+		return
+	}
+
+	body := map[string]interface{}{
+		"event": event,
+		"order": map[string]interface{}{
+			"order_no":          order.OrderNo,
+			"external_order_id": order.ExternalOrderID,
+			"external_user_id":  order.ExternalUserID,
+			"status":            models.OrderStatusCancelled,
+			"cancel_reason":     models.OrderCancelReasonDraftExpired,
+			"form_expires_at":   order.FormExpiresAt,
+			"created_at":        order.CreatedAt.UTC().Format(time.RFC3339),
+		},
+		"sent_at": models.NowFunc().UTC().Format(time.RFC3339),
+	}
+	if err := postOrderPlatformWebhook(key.WebhookURL, event, body); err != nil {
+		log.Printf("[OrderCancel] Order %s platform webhook failed: platform=%s err=%v", order.OrderNo, order.SourcePlatform, err)
+	}
+}
+
+func postOrderPlatformWebhook(target, event string, body interface{}) error {
+	parsed, err := url.Parse(target)
+	if err != nil || validateExternalURL(parsed) != nil {
+		return fmt.Errorf("url is not allowed")
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), orderPlatformWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, parsed.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AuraLogic-Webhook/1.0")
+	req.Header.Set("X-AuraLogic-Event", event)
+	resp, err := getPaymentHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
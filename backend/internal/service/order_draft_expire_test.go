@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+)
+
+func TestExpireStaleDraftsCancelsOnlyExpiredAPIDrafts(t *testing.T) {
+	_, db := newOrderServiceTestDB(t)
+	if err := db.AutoMigrate(&models.OrderNote{}, &models.LedgerEntry{}, &models.PaymentAmountReservation{}, &models.APIKey{}, &models.OperationLog{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	cfg := &config.Config{Order: config.OrderConfig{DraftExpireHours: 24}}
+	svc := NewOrderCancelService(db, cfg, repository.NewInventoryRepository(db), repository.NewPromoCodeRepository(db), nil, nil)
+
+	now := models.NowFunc()
+	stale := now.Add(-48 * time.Hour)
+	recent := now.Add(-time.Hour)
+	newDraft := func(orderNo, source string, formExpiresAt time.Time) *models.Order {
+		token := "token-" + orderNo
+		order := &models.Order{
+			OrderNo:       orderNo,
+			Status:        models.OrderStatusDraft,
+			Source:        source,
+			FormToken:     &token,
+			FormExpiresAt: &formExpiresAt,
+			Items:         []models.OrderItem{{SKU: "SKU-1", Name: "Mug", Quantity: 1}},
+		}
+		if err := db.Create(order).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+		return order
+	}
+	expired := newDraft("D-EXPIRED", "api", stale)
+	withinGrace := newDraft("D-GRACE", "api", recent)
+	adminDraft := newDraft("D-ADMIN", "admin", stale)
+
+	svc.expireStaleDrafts()
+
+	var reloaded models.Order
+	if err := db.First(&reloaded, expired.ID).Error; err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if reloaded.Status != models.OrderStatusCancelled || reloaded.CancelReason != models.OrderCancelReasonDraftExpired {
+		t.Fatalf("expected expired draft cancelled with draft_expired, got %s/%q", reloaded.Status, reloaded.CancelReason)
+	}
+	for _, order := range []*models.Order{withinGrace, adminDraft} {
+		var kept models.Order
+		if err := db.First(&kept, order.ID).Error; err != nil {
+			t.Fatalf("reload: %v", err)
+		}
+		if kept.Status != models.OrderStatusDraft {
+			t.Fatalf("expected %s to remain draft, got %s", order.OrderNo, kept.Status)
+		}
+	}
+
+	var notes int64
+	db.Model(&models.OrderNote{}).Where("order_id = ?", expired.ID).Count(&notes)
+	if notes != 1 {
+		t.Fatalf("expected one cancel note, got %d", notes)
+	}
+}
+
+func TestExpireStaleDraftsDisabledByDefault(t *testing.T) {
+	_, db := newOrderServiceTestDB(t)
+	svc := NewOrderCancelService(db, &config.Config{}, nil, nil, nil, nil)
+
+	formExpiresAt := models.NowFunc().Add(-30 * 24 * time.Hour)
+	token := "token-disabled"
+	order := &models.Order{OrderNo: "D-DISABLED", Status: models.OrderStatusDraft, Source: "api", FormToken: &token, FormExpiresAt: &formExpiresAt}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	svc.expireStaleDrafts()
+
+	var reloaded models.Order
+	db.First(&reloaded, order.ID)
+	if reloaded.Status != models.OrderStatusDraft {
+		t.Fatalf("expected draft to be kept when draft_expire_hours is 0, got %s", reloaded.Status)
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"log"
+
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+// dropClientGiftItems 去掉客户端提交的赠品项
+func dropClientGiftItems(items []models.OrderItem) []models.OrderItem {
+	result := make([]models.OrderItem, 0, len(items))
+	for _, item := range items {
+		if item.IsGift {
+			continue
+		}
+		item.GiftPromotionID = nil
+		result = append(result, item)
+	}
+	return result
+}
+
+// appendGiftItems 追加满足条件的赠品项，并从赠品库存预留
+// 赠品库存不足或预留失败时跳过该赠品，不影响下单；预留成功的库存写入 inventoryBindings，随订单一起确认或释放
+// 预留在下单事务 tx 内进行，订单回滚时赠品库存一并回滚
+func (s *OrderService) appendGiftItems(tx *gorm.DB, afterCommit *[]func(), items []models.OrderItem, subtotalMinor int64, productBySKU map[string]*models.Product, inventoryBindings map[int]uint, userID *uint, orderNo string) []models.OrderItem {
+	if s.giftPromotionSvc == nil {
+		return items
+	}
+
+	productIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		if product := productBySKU[item.SKU]; product != nil {
+			productIDs = append(productIDs, product.ID)
+		}
+	}
+	gifts, err := s.giftPromotionSvc.ResolveGifts(subtotalMinor, productIDs)
+	if err != nil {
+		log.Printf("resolve gift promotions failed: order_no=%s err=%v", orderNo, err)
+		return items
+	}
+
+	for _, gift := range gifts {
+		if !gift.Available {
+			continue
+		}
+		reservedInventoryID, err := s.reserveInventoryWithHookTx(tx, afterCommit, nil, userID, orderNo, gift.InventoryID, gift.Quantity, "user_create_order_gift")
+		if err != nil {
+			log.Printf("reserve gift inventory failed: order_no=%s promotion=%d inventory=%d err=%v", orderNo, gift.PromotionID, gift.InventoryID, err)
+			continue
+		}
+
+		attributes := make(map[string]interface{}, len(gift.Attributes))
+		for k, v := range gift.Attributes {
+			attributes[k] = v
+		}
+		promotionID := gift.PromotionID
+		items = append(items, models.OrderItem{
+			SKU:             gift.SKU,
+			Name:            gift.Name,
+			Quantity:        gift.Quantity,
+			ImageURL:        gift.ImageURL,
+			Attributes:      attributes,
+			ProductType:     models.ProductTypePhysical,
+			IsGift:          true,
+			GiftPromotionID: &promotionID,
+		})
+		inventoryBindings[len(items)-1] = reservedInventoryID
+	}
+	return items
+}
@@ -18,6 +18,7 @@ const (
 	orderHotPathCreateUserOrder      = "create_user_order"
 	orderHotPathSubmitShippingForm   = "submit_shipping_form"
 	orderHotPathConfirmPaymentResult = "confirm_payment_result"
+	orderHotPathBlindBoxAllocation   = "blind_box_allocation"
 )
 
 var (
@@ -90,7 +91,27 @@ func acquireOrderHighConcurrencyProtection(cfg *config.Config, hotPath string) (
 	}
 
 	protection := cfg.Order.HighConcurrencyProtection
-	if !protection.Enabled || protection.MaxInFlight <= 0 {
+	if !protection.Enabled {
+		return func() {}, nil
+	}
+	return acquireOrderHotPathGate(protection, hotPath, protection.MaxInFlight)
+}
+
+// acquireBlindBoxAllocationGate 盲盒随机分配的全局并发槽位，分配与库存预留完成后释放
+func acquireBlindBoxAllocationGate(cfg *config.Config) (func(), error) {
+	if cfg == nil {
+		return func() {}, nil
+	}
+
+	protection := cfg.Order.HighConcurrencyProtection
+	if !protection.Enabled {
+		return func() {}, nil
+	}
+	return acquireOrderHotPathGate(protection, orderHotPathBlindBoxAllocation, protection.BlindBoxMaxInFlight)
+}
+
+func acquireOrderHotPathGate(protection config.OrderHighConcurrencyProtectionConfig, hotPath string, maxInFlight int) (func(), error) {
+	if maxInFlight <= 0 {
 		return func() {}, nil
 	}
 
@@ -110,23 +131,23 @@ func acquireOrderHighConcurrencyProtection(cfg *config.Config, hotPath string) (
 
 	switch mode {
 	case "memory":
-		return acquireOrderHighConcurrencyMemoryGate(hotPath, protection.MaxInFlight, waitTimeout)
+		return acquireOrderHighConcurrencyMemoryGate(hotPath, maxInFlight, waitTimeout)
 	case "redis":
-		release, err := acquireOrderHighConcurrencyRedisGate(hotPath, protection.MaxInFlight, waitTimeout, redisLease)
+		release, err := acquireOrderHighConcurrencyRedisGate(hotPath, maxInFlight, waitTimeout, redisLease)
 		if err == nil {
 			return release, nil
 		}
 		log.Printf("order high concurrency protection redis mode fallback to memory: path=%s err=%v", hotPath, err)
-		return acquireOrderHighConcurrencyMemoryGate(hotPath, protection.MaxInFlight, waitTimeout)
+		return acquireOrderHighConcurrencyMemoryGate(hotPath, maxInFlight, waitTimeout)
 	default:
 		if cache.RedisClient != nil {
-			release, err := acquireOrderHighConcurrencyRedisGate(hotPath, protection.MaxInFlight, waitTimeout, redisLease)
+			release, err := acquireOrderHighConcurrencyRedisGate(hotPath, maxInFlight, waitTimeout, redisLease)
 			if err == nil {
 				return release, nil
 			}
 			log.Printf("order high concurrency protection auto mode fallback to memory: path=%s err=%v", hotPath, err)
 		}
-		return acquireOrderHighConcurrencyMemoryGate(hotPath, protection.MaxInFlight, waitTimeout)
+		return acquireOrderHighConcurrencyMemoryGate(hotPath, maxInFlight, waitTimeout)
 	}
 }
 
@@ -159,3 +159,43 @@ func TestOrderHighConcurrencyProtectionReturnsBusyOnTimeout(t *testing.T) {
 		t.Fatalf("expected busy error type, got %v", err)
 	}
 }
+
+func TestBlindBoxAllocationGateUsesOwnLimit(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Order.HighConcurrencyProtection = config.OrderHighConcurrencyProtectionConfig{
+		Enabled:             true,
+		Mode:                "memory",
+		MaxInFlight:         8,
+		WaitTimeoutMs:       25,
+		RedisLeaseMs:        5000,
+		BlindBoxMaxInFlight: 1,
+	}
+
+	release, err := acquireBlindBoxAllocationGate(cfg)
+	if err != nil {
+		t.Fatalf("first blind box acquire failed: %v", err)
+	}
+	if _, err := acquireBlindBoxAllocationGate(cfg); !isOrderHighConcurrencyBusyError(err) {
+		t.Fatalf("expected blind box gate to be busy, got %v", err)
+	}
+	// 盲盒槽位与下单热点链路相互独立
+	releaseOrder, err := acquireOrderHighConcurrencyProtection(cfg, orderHotPathCreateUserOrder)
+	if err != nil {
+		t.Fatalf("order hot path should not share blind box slots: %v", err)
+	}
+	releaseOrder()
+	release()
+
+	release, err = acquireBlindBoxAllocationGate(cfg)
+	if err != nil {
+		t.Fatalf("acquire after release failed: %v", err)
+	}
+	release()
+
+	cfg.Order.HighConcurrencyProtection.BlindBoxMaxInFlight = 0
+	for i := 0; i < 3; i++ {
+		if _, err := acquireBlindBoxAllocationGate(cfg); err != nil {
+			t.Fatalf("zero limit should not restrict blind box allocation: %v", err)
+		}
+	}
+}
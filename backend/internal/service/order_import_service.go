@@ -0,0 +1,479 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// OrderImportSource 批量导入订单的 Order.Source，配合 ExternalOrderID 去重
+	OrderImportSource = "import"
+
+	orderImportMaxRows      = 20000
+	orderImportMaxRowErrors = 1000
+	// 每处理若干个订单落库一次进度
+	orderImportProgressEvery = 10
+)
+
+// 导入表头（规范化后）到字段的映射，支持常见中英文列名
+var orderImportHeaderAliases = map[string]string{
+	"orderref": "order_ref", "externalorderid": "order_ref", "orderid": "order_ref", "原订单号": "order_ref", "订单引用": "order_ref",
+	"useremail": "user_email", "customeremail": "user_email", "用户邮箱": "user_email",
+	"receivername": "receiver_name", "收货人": "receiver_name",
+	"phonecode": "phone_code", "区号": "phone_code",
+	"receiverphone": "receiver_phone", "收货电话": "receiver_phone",
+	"receiveremail": "receiver_email", "收货邮箱": "receiver_email",
+	"receivercountry": "receiver_country", "country": "receiver_country", "国家": "receiver_country",
+	"receiverprovince": "receiver_province", "province": "receiver_province", "省份": "receiver_province",
+	"receivercity": "receiver_city", "city": "receiver_city", "城市": "receiver_city",
+	"receiverdistrict": "receiver_district", "district": "receiver_district", "区县": "receiver_district",
+	"receiveraddress": "receiver_address", "address": "receiver_address", "详细地址": "receiver_address",
+	"receiverpostcode": "receiver_postcode", "postcode": "receiver_postcode", "邮编": "receiver_postcode",
+	"remark": "remark", "备注": "remark",
+	"status": "status", "状态": "status",
+	"trackingno": "tracking_no", "物流单号": "tracking_no",
+	"sku":      "sku",
+	"itemname": "item_name", "name": "item_name", "商品名称": "item_name",
+	"quantity": "quantity", "qty": "quantity", "数量": "quantity",
+	"unitpriceminor": "unit_price_minor", "unitprice": "unit_price_minor", "单价分": "unit_price_minor",
+	"attributesjson": "attributes_json", "attributes": "attributes_json", "规格json": "attributes_json",
+	"producttype": "product_type", "商品类型": "product_type",
+	"virtualinventoryid": "virtual_inventory_id", "虚拟库存id": "virtual_inventory_id",
+}
+
+// OrderImportTemplateHeaders 导入模板表头
+var OrderImportTemplateHeaders = []string{
+	"Order Ref", "User Email", "Receiver Name", "Phone Code", "Receiver Phone", "Receiver Email",
+	"Receiver Country", "Receiver Province", "Receiver City", "Receiver District", "Receiver Address",
+	"Receiver Postcode", "Remark", "Status", "Tracking No", "SKU", "Item Name", "Quantity",
+	"Unit Price Minor", "Attributes JSON", "Product Type", "Virtual Inventory ID",
+}
+
+// 导入后的订单状态：待付款 / 标记已付款 / 已付款并发货
+const (
+	orderImportStatusPendingPayment = "pending_payment"
+	orderImportStatusPaid           = "paid"
+	orderImportStatusShipped        = "shipped"
+)
+
+// OrderImportService 后台执行 CSV/XLSX 批量订单导入
+type OrderImportService struct {
+	db           *gorm.DB
+	orderService *OrderService
+	running      sync.Map // jobID -> struct{}，防止同一任务并发执行
+}
+
+func NewOrderImportService(db *gorm.DB, orderService *OrderService) *OrderImportService {
+	return &OrderImportService{db: db, orderService: orderService}
+}
+
+type orderImportLine struct {
+	row    int
+	fields map[string]string
+}
+
+type orderImportGroup struct {
+	ref   string
+	lines []orderImportLine
+}
+
+func normalizeOrderImportHeader(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(value, "\ufeff")))
+	return strings.NewReplacer(" ", "", "_", "", "-", "", ".", "", "/", "", "(", "", ")", "").Replace(normalized)
+}
+
+func buildOrderImportHeaderMap(header []string) (map[string]int, error) {
+	headerMap := make(map[string]int, len(header))
+	for idx, raw := range header {
+		key := orderImportHeaderAliases[normalizeOrderImportHeader(raw)]
+		if key == "" {
+			continue
+		}
+		if _, exists := headerMap[key]; exists {
+			return nil, bizerr.Newf("order_import.duplicateHeader", "Duplicate header: %s", strings.TrimSpace(raw)).
+				WithParams(map[string]interface{}{"header": strings.TrimSpace(raw)})
+		}
+		headerMap[key] = idx
+	}
+	for _, required := range []string{"order_ref", "sku", "quantity"} {
+		if _, ok := headerMap[required]; !ok {
+			return nil, bizerr.Newf("order_import.missingHeader", "Missing required header: %s", required).
+				WithParams(map[string]interface{}{"header": required})
+		}
+	}
+	return headerMap, nil
+}
+
+// CreateJob 校验表头并保存导入任务，随后在后台执行
+func (s *OrderImportService) CreateJob(filename string, table [][]string, notifyCustomers bool, operatorID *uint, operatorName string) (*models.OrderImportJob, error) {
+	if len(table) == 0 {
+		return nil, bizerr.New("order_import.empty", "Import file is empty")
+	}
+	if _, err := buildOrderImportHeaderMap(table[0]); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(table)-1)
+	for _, record := range table[1:] {
+		rows = append(rows, record)
+	}
+	dataRows := 0
+	for _, record := range rows {
+		if !isBlankOrderImportRecord(record) {
+			dataRows++
+		}
+	}
+	if dataRows == 0 {
+		return nil, bizerr.New("order_import.empty", "No data rows found in import file")
+	}
+	if dataRows > orderImportMaxRows {
+		return nil, bizerr.Newf("order_import.tooManyRows", "Too many rows to import (max %d). Please split the file.", orderImportMaxRows).
+			WithParams(map[string]interface{}{"max": orderImportMaxRows})
+	}
+
+	job := &models.OrderImportJob{
+		JobNo:           "OI" + strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", ""))[:16],
+		Filename:        strings.TrimSpace(filename),
+		Status:          models.OrderImportJobStatusQueued,
+		Header:          table[0],
+		Rows:            rows,
+		NotifyCustomers: notifyCustomers,
+		TotalRows:       dataRows,
+		OperatorID:      operatorID,
+		OperatorName:    operatorName,
+	}
+	if err := s.db.Create(job).Error; err != nil {
+		return nil, err
+	}
+	s.startJob(job.ID)
+	return job, nil
+}
+
+// ResumePendingJobs 服务重启后继续未完成的任务（已导入的订单按原订单号跳过）
+func (s *OrderImportService) ResumePendingJobs() {
+	var ids []uint
+	if err := s.db.Model(&models.OrderImportJob{}).
+		Where("status IN ?", []models.OrderImportJobStatus{models.OrderImportJobStatusQueued, models.OrderImportJobStatusRunning}).
+		Pluck("id", &ids).Error; err != nil {
+		log.Printf("order import: failed to load pending jobs: %v", err)
+		return
+	}
+	for _, id := range ids {
+		s.startJob(id)
+	}
+}
+
+func (s *OrderImportService) startJob(jobID uint) {
+	if _, loaded := s.running.LoadOrStore(jobID, struct{}{}); loaded {
+		return
+	}
+	go func() {
+		defer s.running.Delete(jobID)
+		if err := s.RunJob(jobID); err != nil {
+			log.Printf("order import job %d failed: %v", jobID, err)
+		}
+	}()
+}
+
+// GetJob 获取导入任务（含行级错误）
+func (s *OrderImportService) GetJob(jobID uint) (*models.OrderImportJob, error) {
+	var job models.OrderImportJob
+	if err := s.db.Omit("header", "rows").First(&job, jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, bizerr.New("order_import.jobNotFound", "Import job not found")
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListJobs 导入任务列表（不返回行级错误明细）
+func (s *OrderImportService) ListJobs(page, limit int) ([]models.OrderImportJob, int64, error) {
+	var total int64
+	if err := s.db.Model(&models.OrderImportJob{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var jobs []models.OrderImportJob
+	err := s.db.Omit("header", "rows", "row_errors").
+		Order("id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, total, err
+}
+
+// RunJob 执行导入任务：按 order_ref 分组创建订单，可选标记已付款/发货
+func (s *OrderImportService) RunJob(jobID uint) error {
+	var job models.OrderImportJob
+	if err := s.db.First(&job, jobID).Error; err != nil {
+		return err
+	}
+	if job.Status == models.OrderImportJobStatusCompleted || job.Status == models.OrderImportJobStatusFailed {
+		return nil
+	}
+
+	headerMap, err := buildOrderImportHeaderMap(job.Header)
+	if err != nil {
+		s.failJob(&job, err.Error())
+		return err
+	}
+	groups := groupOrderImportRows(job.Rows, headerMap)
+
+	now := models.NowFunc()
+	job.Status = models.OrderImportJobStatusRunning
+	job.TotalOrders = len(groups)
+	if job.StartedAt == nil {
+		job.StartedAt = &now
+	}
+	if err := s.saveProgress(&job); err != nil {
+		return err
+	}
+
+	// 从上次中断的位置继续；已创建的订单通过原订单号去重
+	for idx := job.ProcessedOrders; idx < len(groups); idx++ {
+		s.importGroup(&job, groups[idx])
+		job.ProcessedOrders = idx + 1
+		if job.ProcessedOrders%orderImportProgressEvery == 0 {
+			if err := s.saveProgress(&job); err != nil {
+				log.Printf("order import job %d: failed to save progress: %v", job.ID, err)
+			}
+		}
+	}
+
+	completedAt := models.NowFunc()
+	job.Status = models.OrderImportJobStatusCompleted
+	job.CompletedAt = &completedAt
+	return s.saveProgress(&job)
+}
+
+func (s *OrderImportService) saveProgress(job *models.OrderImportJob) error {
+	// 使用结构体更新以便 row_errors 走 JSON 序列化；Select 保证零值也会写入
+	return s.db.Model(job).
+		Select("status", "total_orders", "processed_orders", "created_count", "skipped_count", "error_count", "row_errors", "started_at", "completed_at").
+		Updates(job).Error
+}
+
+func (s *OrderImportService) failJob(job *models.OrderImportJob, reason string) {
+	now := models.NowFunc()
+	if err := s.db.Model(&models.OrderImportJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"status":        models.OrderImportJobStatusFailed,
+		"failed_reason": reason,
+		"completed_at":  &now,
+	}).Error; err != nil {
+		log.Printf("order import job %d: failed to mark failed: %v", job.ID, err)
+	}
+}
+
+func (s *OrderImportService) addRowError(job *models.OrderImportJob, row int, ref, orderNo string, err error) {
+	job.ErrorCount++
+	if len(job.RowErrors) >= orderImportMaxRowErrors {
+		return
+	}
+	message := err.Error()
+	var bizErr *bizerr.Error
+	if errors.As(err, &bizErr) {
+		message = bizErr.Message
+	}
+	job.RowErrors = append(job.RowErrors, models.OrderImportRowError{Row: row, OrderRef: ref, OrderNo: orderNo, Message: message})
+}
+
+func isBlankOrderImportRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// groupOrderImportRows 按 order_ref 分组，保持首次出现的顺序；行号从 2 开始（第 1 行为表头）
+func groupOrderImportRows(rows [][]string, headerMap map[string]int) []orderImportGroup {
+	groups := make([]orderImportGroup, 0)
+	indexByRef := make(map[string]int)
+	for idx, record := range rows {
+		if isBlankOrderImportRecord(record) {
+			continue
+		}
+		fields := make(map[string]string, len(headerMap))
+		for key, col := range headerMap {
+			if col < len(record) {
+				fields[key] = strings.TrimSpace(strings.TrimPrefix(record[col], "\ufeff"))
+			}
+		}
+		line := orderImportLine{row: idx + 2, fields: fields}
+		ref := fields["order_ref"]
+		if ref == "" {
+			// 缺少订单引用的行单独成组，执行时报错
+			groups = append(groups, orderImportGroup{lines: []orderImportLine{line}})
+			continue
+		}
+		if groupIdx, exists := indexByRef[ref]; exists {
+			groups[groupIdx].lines = append(groups[groupIdx].lines, line)
+			continue
+		}
+		indexByRef[ref] = len(groups)
+		groups = append(groups, orderImportGroup{ref: ref, lines: []orderImportLine{line}})
+	}
+	return groups
+}
+
+func (s *OrderImportService) importGroup(job *models.OrderImportJob, group orderImportGroup) {
+	firstRow := group.lines[0].row
+	if group.ref == "" {
+		s.addRowError(job, firstRow, "", "", errors.New("order_ref is required"))
+		return
+	}
+	if len(group.ref) > 100 {
+		s.addRowError(job, firstRow, group.ref, "", errors.New("order_ref cannot exceed 100 characters"))
+		return
+	}
+
+	var existing int64
+	if err := s.db.Model(&models.Order{}).
+		Where("source = ? AND external_order_id = ?", OrderImportSource, group.ref).
+		Count(&existing).Error; err != nil {
+		s.addRowError(job, firstRow, group.ref, "", err)
+		return
+	}
+	if existing > 0 {
+		job.SkippedCount++
+		return
+	}
+
+	req, status, trackingNo, row, err := s.buildOrderImportRequest(job, group)
+	if err != nil {
+		s.addRowError(job, row, group.ref, "", err)
+		return
+	}
+
+	order, err := s.orderService.CreateAdminOrder(req)
+	if err != nil {
+		s.addRowError(job, firstRow, group.ref, "", err)
+		return
+	}
+	job.CreatedCount++
+
+	if status == orderImportStatusPaid || status == orderImportStatusShipped {
+		// 迁移的历史订单已在原平台履约，不自动发放虚拟库存
+		if err := s.orderService.MarkAsPaidWithOptions(order.ID, MarkAsPaidOptions{SkipAutoDelivery: true, OperatorID: job.OperatorID}); err != nil {
+			s.addRowError(job, firstRow, group.ref, order.OrderNo, fmt.Errorf("order created but marking paid failed: %w", err))
+			return
+		}
+	}
+	if status == orderImportStatusShipped {
+		if err := s.orderService.AssignTracking(order.ID, trackingNo); err != nil {
+			s.addRowError(job, firstRow, group.ref, order.OrderNo, fmt.Errorf("order created and paid but shipping failed: %w", err))
+		}
+	}
+}
+
+// buildOrderImportRequest 订单级字段取分组首行，每行贡献一个订单项；出错时返回出错的行号
+func (s *OrderImportService) buildOrderImportRequest(job *models.OrderImportJob, group orderImportGroup) (AdminOrderRequest, string, string, int, error) {
+	head := group.lines[0]
+	fields := head.fields
+
+	status := strings.ToLower(fields["status"])
+	if status == "" {
+		status = orderImportStatusPendingPayment
+	}
+	switch status {
+	case orderImportStatusPendingPayment, orderImportStatusPaid, orderImportStatusShipped:
+	default:
+		return AdminOrderRequest{}, "", "", head.row, fmt.Errorf("invalid status %q (expected pending_payment, paid or shipped)", status)
+	}
+	trackingNo := fields["tracking_no"]
+	if status == orderImportStatusShipped {
+		if trackingNo == "" {
+			return AdminOrderRequest{}, "", "", head.row, errors.New("tracking_no is required for shipped orders")
+		}
+		if fields["receiver_name"] == "" || fields["receiver_address"] == "" {
+			return AdminOrderRequest{}, "", "", head.row, errors.New("receiver_name and receiver_address are required for shipped orders")
+		}
+	}
+
+	req := AdminOrderRequest{
+		ReceiverName:              fields["receiver_name"],
+		PhoneCode:                 fields["phone_code"],
+		ReceiverPhone:             fields["receiver_phone"],
+		ReceiverEmail:             fields["receiver_email"],
+		ReceiverCountry:           fields["receiver_country"],
+		ReceiverProvince:          fields["receiver_province"],
+		ReceiverCity:              fields["receiver_city"],
+		ReceiverDistrict:          fields["receiver_district"],
+		ReceiverAddress:           fields["receiver_address"],
+		ReceiverPostcode:          fields["receiver_postcode"],
+		Remark:                    fields["remark"],
+		CreatedBy:                 job.OperatorID,
+		Source:                    OrderImportSource,
+		ExternalOrderID:           group.ref,
+		DisableEmailNotifications: !job.NotifyCustomers,
+	}
+
+	if email := strings.ToLower(fields["user_email"]); email != "" {
+		req.UserEmail = email
+		var user models.User
+		err := s.db.Select("id").Where("email = ?", email).First(&user).Error
+		switch {
+		case err == nil:
+			req.UserID = &user.ID
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return AdminOrderRequest{}, "", "", head.row, err
+		}
+	}
+
+	for _, line := range group.lines {
+		item, err := buildOrderImportItem(line.fields)
+		if err != nil {
+			return AdminOrderRequest{}, "", "", line.row, err
+		}
+		req.Items = append(req.Items, item)
+	}
+	return req, status, trackingNo, head.row, nil
+}
+
+func buildOrderImportItem(fields map[string]string) (AdminOrderItem, error) {
+	item := AdminOrderItem{
+		SKU:         fields["sku"],
+		Name:        fields["item_name"],
+		ProductType: strings.ToLower(fields["product_type"]),
+	}
+	if item.SKU == "" {
+		return item, errors.New("sku is required")
+	}
+	quantity, err := strconv.Atoi(fields["quantity"])
+	if err != nil || quantity <= 0 {
+		return item, fmt.Errorf("invalid quantity %q", fields["quantity"])
+	}
+	item.Quantity = quantity
+	if raw := fields["unit_price_minor"]; raw != "" {
+		price, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || price < 0 {
+			return item, fmt.Errorf("invalid unit_price_minor %q", raw)
+		}
+		item.UnitPrice = price
+	}
+	if raw := fields["attributes_json"]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &item.Attributes); err != nil {
+			return item, fmt.Errorf("invalid attributes_json: %v", err)
+		}
+	}
+	if raw := fields["virtual_inventory_id"]; raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			return item, fmt.Errorf("invalid virtual_inventory_id %q", raw)
+		}
+		virtualInventoryID := uint(id)
+		item.VirtualInventoryID = &virtualInventoryID
+	}
+	return item, nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func newOrderImportTestService(t *testing.T) (*OrderImportService, *OrderService) {
+	t.Helper()
+
+	orderSvc, db := newOrderServiceTestDB(t)
+	if err := db.AutoMigrate(&models.OrderImportJob{}, &models.OrderNote{}, &models.LedgerEntry{}, &models.PaymentAmountReservation{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	return NewOrderImportService(db, orderSvc), orderSvc
+}
+
+func runOrderImportJob(t *testing.T, svc *OrderImportService, table [][]string) *models.OrderImportJob {
+	t.Helper()
+
+	job := &models.OrderImportJob{
+		JobNo:     "OI-" + t.Name(),
+		Status:    models.OrderImportJobStatusQueued,
+		Header:    table[0],
+		Rows:      table[1:],
+		TotalRows: len(table) - 1,
+	}
+	if err := svc.db.Create(job).Error; err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	if err := svc.RunJob(job.ID); err != nil {
+		t.Fatalf("run job: %v", err)
+	}
+	loaded, err := svc.GetJob(job.ID)
+	if err != nil {
+		t.Fatalf("get job: %v", err)
+	}
+	return loaded
+}
+
+func TestOrderImportGroupsRowsAndReportsRowErrors(t *testing.T) {
+	svc, _ := newOrderImportTestService(t)
+
+	job := runOrderImportJob(t, svc, [][]string{
+		{"Order Ref", "User Email", "收货人", "Address", "SKU", "Item Name", "Quantity", "Unit Price Minor"},
+		{"A-1", "buyer@example.com", "Alice", "1 Market St", "SKU-1", "Mug", "2", "500"},
+		{"A-1", "", "", "", "SKU-2", "Cup", "1", "300"},
+		{"A-2", "", "Bob", "2 Main St", "SKU-1", "Mug", "zero", "500"},
+		{"", "", "", "", "", "", "", ""},
+		{"A-3", "", "Carol", "3 Main St", "SKU-3", "Plate", "1", "100"},
+	})
+
+	if job.Status != models.OrderImportJobStatusCompleted {
+		t.Fatalf("expected completed job, got %s", job.Status)
+	}
+	if job.TotalOrders != 3 || job.CreatedCount != 2 || job.ErrorCount != 1 {
+		t.Fatalf("unexpected counters: total=%d created=%d errors=%d", job.TotalOrders, job.CreatedCount, job.ErrorCount)
+	}
+	if len(job.RowErrors) != 1 || job.RowErrors[0].Row != 4 || job.RowErrors[0].OrderRef != "A-2" {
+		t.Fatalf("unexpected row errors: %+v", job.RowErrors)
+	}
+
+	var order models.Order
+	if err := svc.db.Where("source = ? AND external_order_id = ?", OrderImportSource, "A-1").First(&order).Error; err != nil {
+		t.Fatalf("load imported order: %v", err)
+	}
+	if len(order.Items) != 2 || order.TotalAmount != 1300 {
+		t.Fatalf("expected 2 items totalling 1300, got %d items total=%d", len(order.Items), order.TotalAmount)
+	}
+	if order.UserEmail != "buyer@example.com" || order.EmailNotificationsEnabled {
+		t.Fatalf("expected customer notifications disabled by default, got email=%q enabled=%v", order.UserEmail, order.EmailNotificationsEnabled)
+	}
+}
+
+func TestOrderImportSkipsAlreadyImportedOrders(t *testing.T) {
+	svc, _ := newOrderImportTestService(t)
+	table := [][]string{
+		{"order_ref", "sku", "item_name", "quantity"},
+		{"B-1", "SKU-1", "Mug", "1"},
+	}
+
+	first := runOrderImportJob(t, svc, table)
+	if first.CreatedCount != 1 {
+		t.Fatalf("expected first import to create the order, got %d", first.CreatedCount)
+	}
+
+	svc.db.Model(&models.OrderImportJob{}).Where("id = ?", first.ID).Update("job_no", "OI-first")
+	second := runOrderImportJob(t, svc, table)
+	if second.CreatedCount != 0 || second.SkippedCount != 1 {
+		t.Fatalf("expected re-import to skip, got created=%d skipped=%d", second.CreatedCount, second.SkippedCount)
+	}
+
+	var count int64
+	svc.db.Model(&models.Order{}).Where("external_order_id = ?", "B-1").Count(&count)
+	if count != 1 {
+		t.Fatalf("expected a single imported order, got %d", count)
+	}
+}
+
+func TestOrderImportMarksPaid(t *testing.T) {
+	svc, _ := newOrderImportTestService(t)
+
+	job := runOrderImportJob(t, svc, [][]string{
+		{"order_ref", "status", "receiver_name", "receiver_address", "sku", "item_name", "quantity", "unit_price_minor"},
+		{"C-1", "paid", "Alice", "1 Market St", "SKU-1", "Mug", "1", "900"},
+		{"C-2", "shipped", "Alice", "1 Market St", "SKU-1", "Mug", "1", "900"},
+	})
+
+	if job.CreatedCount != 1 || job.ErrorCount != 1 {
+		t.Fatalf("expected one created and one invalid shipped row, got created=%d errors=%d", job.CreatedCount, job.ErrorCount)
+	}
+
+	var order models.Order
+	if err := svc.db.Where("external_order_id = ?", "C-1").First(&order).Error; err != nil {
+		t.Fatalf("load imported order: %v", err)
+	}
+	if order.Status != models.OrderStatusPending {
+		t.Fatalf("expected paid order to await shipment, got %s", order.Status)
+	}
+}
+
+func TestOrderImportCreateJobRejectsMissingHeaders(t *testing.T) {
+	svc, _ := newOrderImportTestService(t)
+
+	_, err := svc.CreateJob("orders.csv", [][]string{{"order_ref", "quantity"}, {"A", "1"}}, false, nil, "")
+	requireOrderBizErr(t, err, "order_import.missingHeader")
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/money"
+)
+
+// allocateOrderItemTotals 按单价快照把订单应付金额分摊到各订单项
+// 差额（优惠或改价）按行小计比例分摊，DiscountMinor 为负表示加价
+func allocateOrderItemTotals(items []models.OrderItem, totalMinor int64) {
+	if len(items) == 0 {
+		return
+	}
+	weights := make([]int64, len(items))
+	var subtotal int64
+	for i := range items {
+		weights[i] = items[i].Subtotal()
+		subtotal += weights[i]
+	}
+	discounts := money.Allocate(subtotal-totalMinor, weights)
+	for i := range items {
+		items[i].DiscountMinor = discounts[i]
+		items[i].LineTotalMinor = weights[i] - discounts[i]
+	}
+}
+
+// ReallocateOrderItemTotals 订单改价后重新分摊订单项金额，旧订单保持不变
+func ReallocateOrderItemTotals(order *models.Order) {
+	if order == nil || !order.HasItemPricing() {
+		return
+	}
+	allocateOrderItemTotals(order.Items, order.TotalAmount)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestAllocateOrderItemTotalsSplitsDiscountByLineSubtotal(t *testing.T) {
+	items := []models.OrderItem{
+		{SKU: "A", Quantity: 1, UnitPriceMinor: 1000},
+		{SKU: "B", Quantity: 2, UnitPriceMinor: 1000},
+		{SKU: "C", Quantity: 1, UnitPriceMinor: 0},
+	}
+	// 优惠 100，按 1000:2000 分摊，余数归最后一个有金额的行
+	allocateOrderItemTotals(items, 2900)
+
+	if items[0].DiscountMinor != 33 || items[0].LineTotalMinor != 967 {
+		t.Fatalf("unexpected first line: %+v", items[0])
+	}
+	if items[1].DiscountMinor != 67 || items[1].LineTotalMinor != 1933 {
+		t.Fatalf("unexpected second line: %+v", items[1])
+	}
+	if items[2].DiscountMinor != 0 || items[2].LineTotalMinor != 0 {
+		t.Fatalf("free line must not absorb discount: %+v", items[2])
+	}
+
+	var sum int64
+	for _, item := range items {
+		sum += item.LineTotalMinor
+	}
+	if sum != 2900 {
+		t.Fatalf("line totals must sum to order total, got %d", sum)
+	}
+}
+
+func TestReallocateOrderItemTotalsAfterPriceChange(t *testing.T) {
+	order := &models.Order{
+		TotalAmount: 2500,
+		Items: []models.OrderItem{
+			{SKU: "A", Quantity: 1, UnitPriceMinor: 1000, LineTotalMinor: 1000},
+			{SKU: "B", Quantity: 1, UnitPriceMinor: 1000, LineTotalMinor: 1000},
+		},
+	}
+	// 改价后高于原价，分摊为负优惠
+	ReallocateOrderItemTotals(order)
+	if order.Items[0].DiscountMinor != -250 || order.Items[0].LineTotalMinor != 1250 || order.Items[1].LineTotalMinor != 1250 {
+		t.Fatalf("unexpected reallocation: %+v", order.Items)
+	}
+
+	legacy := &models.Order{
+		TotalAmount: 500,
+		Items:       []models.OrderItem{{SKU: "A", Quantity: 1}},
+	}
+	ReallocateOrderItemTotals(legacy)
+	if legacy.Items[0].LineTotalMinor != 0 || legacy.Items[0].DiscountMinor != 0 {
+		t.Fatalf("legacy order items must stay untouched: %+v", legacy.Items[0])
+	}
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	maxOrderMergeSources    = 10
+	maxOrderMergeCandidates = 20
+)
+
+// OrderMergeRef 合并关系中的订单
+type OrderMergeRef struct {
+	ID      uint   `json:"id"`
+	OrderNo string `json:"order_no"`
+}
+
+// OrderMergeCandidate 可并入当前订单的待发货订单（不含收货人信息）
+type OrderMergeCandidate struct {
+	ID               uint               `json:"id"`
+	OrderNo          string             `json:"order_no"`
+	Items            []models.OrderItem `json:"items"`
+	TotalAmountMinor int64              `json:"total_amount_minor"`
+	Currency         string             `json:"currency"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
+// OrderMergeInput 合并发货：把同一买家、同一收货人的待发货订单并入目标订单
+type OrderMergeInput struct {
+	SourceOrderIDs []uint
+	OperatorID     *uint
+}
+
+func orderMergeError(key, format string, order *models.Order) error {
+	return bizerr.Newf(key, format, order.OrderNo).
+		WithParams(map[string]interface{}{"order_no": order.OrderNo})
+}
+
+// orderMergeSerialBusy 序列号仍在生成或生成失败待重试时不能合并，避免生成任务按旧订单项重复出号
+func orderMergeSerialBusy(order *models.Order) bool {
+	switch order.SerialGenerationStatus {
+	case models.SerialGenerationStatusQueued, models.SerialGenerationStatusProcessing, models.SerialGenerationStatusFailed:
+		return true
+	}
+	return false
+}
+
+func sameOrderBuyer(a, b *models.Order) bool {
+	switch {
+	case a.UserID != nil || b.UserID != nil:
+		return a.UserID != nil && b.UserID != nil && *a.UserID == *b.UserID
+	case a.ExternalUserID != "" || b.ExternalUserID != "":
+		return a.SourcePlatform == b.SourcePlatform && a.ExternalUserID == b.ExternalUserID
+	default:
+		return a.UserEmail != "" && strings.EqualFold(a.UserEmail, b.UserEmail)
+	}
+}
+
+func normalizeOrderMergeField(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
+
+func sameOrderReceiver(a, b *models.Order) bool {
+	pairs := [][2]string{
+		{a.ReceiverName, b.ReceiverName},
+		{a.PhoneCode, b.PhoneCode},
+		{a.ReceiverPhone, b.ReceiverPhone},
+		{a.ReceiverCountry, b.ReceiverCountry},
+		{a.ReceiverProvince, b.ReceiverProvince},
+		{a.ReceiverCity, b.ReceiverCity},
+		{a.ReceiverDistrict, b.ReceiverDistrict},
+		{a.ReceiverAddress, b.ReceiverAddress},
+		{a.ReceiverPostcode, b.ReceiverPostcode},
+	}
+	for _, pair := range pairs {
+		if normalizeOrderMergeField(pair[0]) != normalizeOrderMergeField(pair[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func orderPaymentMethodIDTx(tx *gorm.DB, orderID uint) (uint, error) {
+	var mappings []models.OrderPaymentMethod
+	if err := tx.Where("order_id = ?", orderID).Limit(1).Find(&mappings).Error; err != nil {
+		return 0, err
+	}
+	if len(mappings) == 0 {
+		return 0, nil
+	}
+	return mappings[0].PaymentMethodID, nil
+}
+
+// validateOrderMergeTx 校验 source 能否并入 target（两者均需已加载最新状态）
+func validateOrderMergeTx(tx *gorm.DB, target, source *models.Order) error {
+	if source.Status != models.OrderStatusPending {
+		return bizerr.Newf("order.mergeStatusInvalid", "Order %s is not pending shipment and cannot be merged", source.OrderNo).
+			WithParams(map[string]interface{}{"order_no": source.OrderNo, "status": source.Status})
+	}
+	if !sameOrderBuyer(target, source) {
+		return orderMergeError("order.mergeBuyerMismatch", "Order %s belongs to a different buyer", source)
+	}
+	if !sameOrderReceiver(target, source) {
+		return orderMergeError("order.mergeReceiverMismatch", "Order %s ships to a different receiver", source)
+	}
+	if !sameOptionalUint(target.StoreID, source.StoreID) || target.Currency != source.Currency || target.IsSandbox != source.IsSandbox {
+		return orderMergeError("order.mergeIncompatible", "Order %s has a different store, currency or sandbox mode", source)
+	}
+	for _, item := range source.Items {
+		if item.ProductType == models.ProductTypeVirtual {
+			return orderMergeError("order.mergeVirtualItems", "Order %s contains virtual items and cannot be merged", source)
+		}
+	}
+	if source.PromoCodeID != nil {
+		return orderMergeError("order.mergePromoCode", "Order %s used a promo code. Merge into that order instead", source)
+	}
+	if orderMergeSerialBusy(source) {
+		return orderMergeError("order.mergeSerialGenerationBusy", "Serial numbers of order %s are not ready yet", source)
+	}
+
+	var refunds int64
+	if err := tx.Model(&models.OrderRefund{}).Where("order_id = ?", source.ID).Count(&refunds).Error; err != nil {
+		return err
+	}
+	if refunds > 0 {
+		return orderMergeError("order.mergeRefunded", "Order %s has refunds and cannot be merged", source)
+	}
+
+	targetMethodID, err := orderPaymentMethodIDTx(tx, target.ID)
+	if err != nil {
+		return err
+	}
+	sourceMethodID, err := orderPaymentMethodIDTx(tx, source.ID)
+	if err != nil {
+		return err
+	}
+	if targetMethodID != sourceMethodID {
+		return orderMergeError("order.mergePaymentMismatch", "Order %s was paid with a different payment method", source)
+	}
+	return nil
+}
+
+func sameOptionalUint(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// mergeActualAttributes 盲盒分配结果以订单项索引为 key，被合并订单的索引整体后移 offset
+func mergeActualAttributes(target, source models.JSON, offset int) (models.JSON, error) {
+	if source == "" || source == "null" {
+		return target, nil
+	}
+	merged := make(map[string]json.RawMessage)
+	if target != "" && target != "null" {
+		if err := json.Unmarshal([]byte(target), &merged); err != nil {
+			return "", err
+		}
+	}
+	var sourceAttrs map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(source), &sourceAttrs); err != nil {
+		return "", err
+	}
+	for key, value := range sourceAttrs {
+		idx, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+		merged[strconv.Itoa(idx+offset)] = value
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return models.JSON(data), nil
+}
+
+// ListMergeCandidates 可并入该订单的同一买家、同一收货人的其他待发货订单
+func (s *OrderService) ListMergeCandidates(orderID uint) ([]OrderMergeCandidate, error) {
+	target, err := s.OrderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, normalizeOrderLookupError(err)
+	}
+	candidates := []OrderMergeCandidate{}
+	if target.Status != models.OrderStatusPending || orderMergeSerialBusy(target) {
+		return candidates, nil
+	}
+	orders, err := s.OrderRepo.FindPendingByBuyer(target, maxOrderMergeCandidates)
+	if err != nil {
+		return nil, err
+	}
+	err = s.OrderRepo.WithTransaction(func(tx *gorm.DB) error {
+		for i := range orders {
+			source := &orders[i]
+			if err := validateOrderMergeTx(tx, target, source); err != nil {
+				var bizErr *bizerr.Error
+				if errors.As(err, &bizErr) {
+					continue
+				}
+				return err
+			}
+			candidates = append(candidates, OrderMergeCandidate{
+				ID:               source.ID,
+				OrderNo:          source.OrderNo,
+				Items:            source.Items,
+				TotalAmountMinor: source.TotalAmount,
+				Currency:         source.Currency,
+				CreatedAt:        source.CreatedAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// ListMergedOrders 已并入该订单的订单
+func (s *OrderService) ListMergedOrders(orderID uint) ([]OrderMergeRef, error) {
+	orders, err := s.OrderRepo.FindMergedInto(orderID)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]OrderMergeRef, 0, len(orders))
+	for _, order := range orders {
+		refs = append(refs, OrderMergeRef{ID: order.ID, OrderNo: order.OrderNo})
+	}
+	return refs, nil
+}
+
+// MergeOrders 将同一买家、同一收货人的待发货订单并入目标订单一起发货：
+// 商品追加到目标订单并顺延库存绑定索引（预留数量随之转移，不重复预留/释放），金额与账本余额合并，
+// 序列号与货到付款应收转到目标订单；被合并订单以 merged 原因关闭并记录目标订单
+func (s *OrderService) MergeOrders(targetID uint, input OrderMergeInput) (*models.Order, []OrderMergeRef, error) {
+	sourceIDs := make([]uint, 0, len(input.SourceOrderIDs))
+	for _, id := range input.SourceOrderIDs {
+		if id == 0 || id == targetID || slices.Contains(sourceIDs, id) {
+			continue
+		}
+		sourceIDs = append(sourceIDs, id)
+	}
+	if len(sourceIDs) == 0 {
+		return nil, nil, bizerr.New("order.mergeSourcesRequired", "Select at least one other order to merge")
+	}
+	if len(sourceIDs) > maxOrderMergeSources {
+		return nil, nil, bizerr.Newf("order.mergeTooManySources", "At most %d orders can be merged at once", maxOrderMergeSources).
+			WithParams(map[string]interface{}{"max": maxOrderMergeSources})
+	}
+
+	var (
+		target      *models.Order
+		merged      []*models.Order
+		beforeItems int
+	)
+	err := s.OrderRepo.WithTransaction(func(tx *gorm.DB) error {
+		// 按 ID 顺序加锁，避免两个方向相反的合并互相等待
+		lockIDs := append([]uint{targetID}, sourceIDs...)
+		sort.Slice(lockIDs, func(i, j int) bool { return lockIDs[i] < lockIDs[j] })
+		orderRepo := repository.NewOrderRepository(tx)
+		locked := make(map[uint]*models.Order, len(lockIDs))
+		for _, id := range lockIDs {
+			order, err := orderRepo.FindByIDForUpdate(tx, id)
+			if err != nil {
+				return normalizeOrderLookupError(err)
+			}
+			order.User = nil
+			locked[id] = order
+		}
+
+		target = locked[targetID]
+		if target.Status != models.OrderStatusPending {
+			return bizerr.Newf("order.mergeStatusInvalid", "Order %s is not pending shipment and cannot be merged", target.OrderNo).
+				WithParams(map[string]interface{}{"order_no": target.OrderNo, "status": target.Status})
+		}
+		if orderMergeSerialBusy(target) {
+			return orderMergeError("order.mergeSerialGenerationBusy", "Serial numbers of order %s are not ready yet", target)
+		}
+		for _, id := range sourceIDs {
+			if err := validateOrderMergeTx(tx, target, locked[id]); err != nil {
+				return err
+			}
+		}
+
+		beforeItems = len(target.Items)
+		merged = make([]*models.Order, 0, len(sourceIDs))
+		for _, id := range sourceIDs {
+			if err := mergeOrderIntoTx(tx, target, locked[id], input.OperatorID); err != nil {
+				return err
+			}
+			merged = append(merged, locked[id])
+		}
+
+		if err := tx.Model(&models.Order{ID: target.ID}).
+			Select("items", "inventory_bindings", "actual_attributes", "total_amount", "discount_amount", "total_weight_grams",
+				"tags", "remark", "serial_generation_status", "serial_generated_at").
+			Updates(target).Error; err != nil {
+			return err
+		}
+
+		lines := make([]string, 0, len(merged)+len(target.Items)-beforeItems)
+		for _, source := range merged {
+			lines = append(lines, "Merged order "+source.OrderNo)
+		}
+		for _, item := range target.Items[beforeItems:] {
+			lines = append(lines, fmt.Sprintf("+ %s × %d", item.SKU, item.Quantity))
+		}
+		return AddOrderNoteTx(tx, target.ID, input.OperatorID, models.OrderNoteSourceMerge, strings.Join(lines, "\n"))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refs := make([]OrderMergeRef, 0, len(merged))
+	for _, source := range merged {
+		refs = append(refs, OrderMergeRef{ID: source.ID, OrderNo: source.OrderNo})
+		EmitOrderStatusChangedAfterHookAsync(s.pluginManager, nil, source, models.OrderStatusPending, source.Status, map[string]interface{}{
+			"source":               "merge_order",
+			"trigger_action":       "order.merge",
+			"merged_into_order_id": target.ID,
+			"merged_into_order_no": target.OrderNo,
+		})
+	}
+	syncUserPurchaseStatsBestEffort(s.OrderRepo, target.UserID, "merge_order")
+	s.syncUserConsumptionStatsBestEffort(target.UserID, "merge_order")
+
+	order, err := s.OrderRepo.FindByID(target.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, refs, nil
+}
+
+// mergeOrderIntoTx 把 source 的商品、库存绑定、金额等并入内存中的 target，并关闭 source
+func mergeOrderIntoTx(tx *gorm.DB, target, source *models.Order, operatorID *uint) error {
+	offset := len(target.Items)
+	target.Items = append(target.Items, source.Items...)
+
+	// 预留数量已在 source 下单时占用，这里只把绑定移到顺延后的索引并记录转移流水
+	indexes := make([]int, 0, len(source.InventoryBindings))
+	for idx := range source.InventoryBindings {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+	for _, idx := range indexes {
+		inventoryID := source.InventoryBindings[idx]
+		if inventoryID == 0 || idx < 0 || idx >= len(source.Items) {
+			continue
+		}
+		if target.InventoryBindings == nil {
+			target.InventoryBindings = make(map[int]uint)
+		}
+		target.InventoryBindings[offset+idx] = inventoryID
+
+		var inventory models.Inventory
+		if err := tx.Select("id", "reserved_quantity").First(&inventory, inventoryID).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.InventoryLog{
+			InventoryID: inventoryID,
+			Type:        models.InventoryLogTypeTransfer,
+			Quantity:    source.Items[idx].Quantity,
+			BeforeStock: inventory.ReservedQuantity,
+			AfterStock:  inventory.ReservedQuantity,
+			OrderNo:     target.OrderNo,
+			Operator:    "system",
+			Reason:      fmt.Sprintf("Transfer reserved inventory from merged order %s", source.OrderNo),
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	attrs, err := mergeActualAttributes(target.ActualAttributes, source.ActualAttributes, offset)
+	if err != nil {
+		return err
+	}
+	target.ActualAttributes = attrs
+	target.TotalAmount += source.TotalAmount
+	target.DiscountAmount += source.DiscountAmount
+	target.TotalWeightGrams += source.TotalWeightGrams
+	for _, tag := range source.Tags {
+		if len(target.Tags) < maxOrderTags && !containsString(target.Tags, tag) {
+			target.Tags = append(target.Tags, tag)
+		}
+	}
+	if remark := strings.TrimSpace(source.Remark); remark != "" {
+		target.Remark = strings.TrimSpace(target.Remark + "\n" + remark)
+	}
+
+	if err := RecordOrderMergeLedgerTx(tx, source, target, operatorID); err != nil {
+		return err
+	}
+
+	// 货到付款：两单付款方式相同，应收合并到目标订单
+	var sourceCOD []models.CODCollection
+	if err := tx.Where("order_id = ? AND status = ?", source.ID, models.CODCollectionStatusPending).Limit(1).Find(&sourceCOD).Error; err != nil {
+		return err
+	}
+	if len(sourceCOD) > 0 {
+		if err := tx.Model(&models.CODCollection{}).
+			Where("order_id = ? AND status = ?", target.ID, models.CODCollectionStatusPending).
+			Update("amount_due", gorm.Expr("amount_due + ?", sourceCOD[0].AmountDue)).Error; err != nil {
+			return err
+		}
+		if err := CancelCODCollectionTx(tx, source.ID); err != nil {
+			return err
+		}
+	}
+	if err := ReleasePaymentAmountReservationsTx(tx, source.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.ProductSerial{}).Where("order_id = ?", source.ID).Update("order_id", target.ID).Error; err != nil {
+		return err
+	}
+	if source.SerialGenerationStatus == models.SerialGenerationStatusCompleted &&
+		target.SerialGenerationStatus != models.SerialGenerationStatusCompleted {
+		target.SerialGenerationStatus = models.SerialGenerationStatusCompleted
+		target.SerialGeneratedAt = source.SerialGeneratedAt
+	}
+
+	source.Status = models.OrderStatusCancelled
+	source.CancelReason = models.OrderCancelReasonMerged
+	source.MergedIntoOrderID = &target.ID
+	source.MergedIntoOrderNo = target.OrderNo
+	source.InventoryBindings = map[int]uint{}
+	source.TotalAmount = 0
+	source.DiscountAmount = 0
+	if err := tx.Model(&models.Order{ID: source.ID}).
+		Select("status", "cancel_reason", "merged_into_order_id", "merged_into_order_no", "inventory_bindings", "total_amount", "discount_amount").
+		Updates(source).Error; err != nil {
+		return err
+	}
+	return AddOrderNoteTx(tx, source.ID, operatorID, models.OrderNoteSourceMerge, "Merged into order "+target.OrderNo)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+func createMergeTestOrder(t *testing.T, db *gorm.DB, order *models.Order) *models.Order {
+	t.Helper()
+	userID := uint(7)
+	order.UserID = &userID
+	order.Status = models.OrderStatusPending
+	order.Currency = "CNY"
+	order.ReceiverName = "Alice"
+	order.ReceiverPhone = "13800000000"
+	order.ReceiverCountry = "CN"
+	order.ReceiverCity = "Shanghai"
+	order.ReceiverAddress = "1 Test Road"
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return RecordOrderCreatedLedgerTx(tx, order, true, "test")
+	}); err != nil {
+		t.Fatalf("record ledger failed: %v", err)
+	}
+	return order
+}
+
+func TestMergeOrdersTransfersItemsReservationsAndLedger(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.OrderNote{}, &models.InventoryLog{}, &models.ProductSerial{}, &models.OrderRefund{}, &models.OrderPaymentMethod{})
+	svc := newConcurrentOrderService(db, &config.Config{}, nil)
+
+	cup := &models.Inventory{Name: "Cup", Stock: 10, AvailableQuantity: 10, ReservedQuantity: 3, IsActive: true}
+	plate := &models.Inventory{Name: "Plate", Stock: 10, AvailableQuantity: 10, ReservedQuantity: 2, IsActive: true}
+	if err := db.Create(cup).Error; err != nil {
+		t.Fatalf("create inventory failed: %v", err)
+	}
+	if err := db.Create(plate).Error; err != nil {
+		t.Fatalf("create inventory failed: %v", err)
+	}
+
+	target := createMergeTestOrder(t, db, &models.Order{
+		OrderNo:           "MRG-1",
+		Items:             []models.OrderItem{{SKU: "CUP", Name: "Cup", Quantity: 3, ProductType: models.ProductTypePhysical}},
+		InventoryBindings: map[int]uint{0: cup.ID},
+		TotalAmount:       3000,
+	})
+	source := createMergeTestOrder(t, db, &models.Order{
+		OrderNo:           "MRG-2",
+		Items:             []models.OrderItem{{SKU: "PLATE", Name: "Plate", Quantity: 2, ProductType: models.ProductTypePhysical}},
+		InventoryBindings: map[int]uint{0: plate.ID},
+		TotalAmount:       1600,
+		Tags:              []string{"gift"},
+	})
+
+	merged, refs, err := svc.MergeOrders(target.ID, OrderMergeInput{SourceOrderIDs: []uint{source.ID, source.ID, target.ID}})
+	if err != nil {
+		t.Fatalf("merge orders failed: %v", err)
+	}
+	if len(refs) != 1 || refs[0].OrderNo != "MRG-2" {
+		t.Fatalf("unexpected merged refs: %+v", refs)
+	}
+	if len(merged.Items) != 2 || merged.Items[1].SKU != "PLATE" || merged.InventoryBindings[1] != plate.ID {
+		t.Fatalf("expected items appended with reindexed bindings, got items=%+v bindings=%+v", merged.Items, merged.InventoryBindings)
+	}
+	if merged.TotalAmount != 4600 || !containsString(merged.Tags, "gift") {
+		t.Fatalf("unexpected merged totals: total=%d tags=%v", merged.TotalAmount, merged.Tags)
+	}
+
+	var closed models.Order
+	if err := db.First(&closed, source.ID).Error; err != nil {
+		t.Fatalf("reload source failed: %v", err)
+	}
+	if closed.Status != models.OrderStatusCancelled || closed.CancelReason != models.OrderCancelReasonMerged ||
+		closed.MergedIntoOrderID == nil || *closed.MergedIntoOrderID != target.ID || closed.TotalAmount != 0 || len(closed.InventoryBindings) != 0 {
+		t.Fatalf("unexpected merged source: %+v", closed)
+	}
+
+	// 预留数量随绑定转移，不重复预留也不释放
+	var reloadedPlate models.Inventory
+	db.First(&reloadedPlate, plate.ID)
+	if reloadedPlate.ReservedQuantity != 2 {
+		t.Fatalf("expected reservation kept, got %d", reloadedPlate.ReservedQuantity)
+	}
+	var transferLogs int64
+	db.Model(&models.InventoryLog{}).Where("type = ? AND order_no = ?", models.InventoryLogTypeTransfer, "MRG-1").Count(&transferLogs)
+	if transferLogs != 1 {
+		t.Fatalf("expected one transfer log, got %d", transferLogs)
+	}
+
+	ledger := NewLedgerService(db)
+	for _, order := range []*models.Order{merged, &closed} {
+		summary, err := ledger.OrderSummary(order)
+		if err != nil {
+			t.Fatalf("ledger summary failed: %v", err)
+		}
+		if !summary.Consistent {
+			t.Fatalf("expected consistent ledger for %s, got %v", order.OrderNo, summary.Issues)
+		}
+	}
+
+	mergedOrders, err := svc.ListMergedOrders(target.ID)
+	if err != nil || len(mergedOrders) != 1 || mergedOrders[0].ID != source.ID {
+		t.Fatalf("unexpected merged orders: %+v err=%v", mergedOrders, err)
+	}
+}
+
+func TestMergeOrdersRejectsIncompatibleSources(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.OrderNote{}, &models.InventoryLog{}, &models.ProductSerial{}, &models.OrderRefund{}, &models.OrderPaymentMethod{})
+	svc := newConcurrentOrderService(db, &config.Config{}, nil)
+
+	target := createMergeTestOrder(t, db, &models.Order{OrderNo: "MRG-10", Items: []models.OrderItem{{SKU: "CUP", Quantity: 1}}, TotalAmount: 100})
+	otherReceiver := createMergeTestOrder(t, db, &models.Order{OrderNo: "MRG-11", Items: []models.OrderItem{{SKU: "CUP", Quantity: 1}}, TotalAmount: 100})
+	if err := db.Model(otherReceiver).Update("receiver_address", "2 Other Road").Error; err != nil {
+		t.Fatalf("update receiver failed: %v", err)
+	}
+	shipped := createMergeTestOrder(t, db, &models.Order{OrderNo: "MRG-12", Items: []models.OrderItem{{SKU: "CUP", Quantity: 1}}, TotalAmount: 100})
+	if err := db.Model(shipped).Update("status", models.OrderStatusShipped).Error; err != nil {
+		t.Fatalf("update status failed: %v", err)
+	}
+	candidate := createMergeTestOrder(t, db, &models.Order{OrderNo: "MRG-13", Items: []models.OrderItem{{SKU: "CUP", Quantity: 1}}, TotalAmount: 100})
+
+	var bizErr *bizerr.Error
+	if _, _, err := svc.MergeOrders(target.ID, OrderMergeInput{SourceOrderIDs: []uint{otherReceiver.ID}}); !errors.As(err, &bizErr) || bizErr.Key != "order.mergeReceiverMismatch" {
+		t.Fatalf("expected mergeReceiverMismatch, got %v", err)
+	}
+	if _, _, err := svc.MergeOrders(target.ID, OrderMergeInput{SourceOrderIDs: []uint{candidate.ID, shipped.ID}}); !errors.As(err, &bizErr) || bizErr.Key != "order.mergeStatusInvalid" {
+		t.Fatalf("expected mergeStatusInvalid, got %v", err)
+	}
+	// 整体校验失败时不应部分合并
+	var untouched models.Order
+	db.First(&untouched, candidate.ID)
+	if untouched.Status != models.OrderStatusPending || untouched.MergedIntoOrderID != nil {
+		t.Fatalf("expected candidate untouched after failed merge, got %+v", untouched)
+	}
+
+	candidates, err := svc.ListMergeCandidates(target.ID)
+	if err != nil {
+		t.Fatalf("list candidates failed: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].ID != candidate.ID {
+		t.Fatalf("expected only compatible candidate, got %+v", candidates)
+	}
+}
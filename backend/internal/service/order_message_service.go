@@ -0,0 +1,309 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/validator"
+	"gorm.io/gorm"
+)
+
+const (
+	orderMessageMaxLength   = 2000
+	orderMessagePreviewSize = 200
+
+	OrderMessageSenderUser   = "user"
+	OrderMessageSenderAdmin  = "admin"
+	OrderMessageSenderSystem = "system"
+)
+
+var (
+	ErrOrderMessageContentRequired = bizerr.New("orderMessage.contentRequired", "Message content is required")
+	ErrOrderMessageGuestOrder      = bizerr.New("orderMessage.guestOrder", "Messages are only available for orders placed by registered users")
+	ErrOrderMessageEscalated       = bizerr.New("orderMessage.escalated", "This conversation has moved to a support ticket")
+	ErrOrderMessageTicketDisabled  = bizerr.New("orderMessage.ticketDisabled", "Ticket system is disabled")
+	ErrOrderMessageEmptyThread     = bizerr.New("orderMessage.emptyThread", "There are no messages to escalate")
+)
+
+// GenerateTicketNo 生成工单号
+func GenerateTicketNo() string {
+	return fmt.Sprintf("TK%s%04d", time.Now().Format("20060102150405"), time.Now().UnixNano()%10000)
+}
+
+// OrderMessageThread 订单留言会话
+type OrderMessageThread struct {
+	Messages    []models.OrderMessage `json:"messages"`
+	Ticket      *models.Ticket        `json:"ticket,omitempty"` // 已升级的工单
+	CanEscalate bool                  `json:"can_escalate"`
+}
+
+// OrderMessageService 订单留言：用户与卖家围绕订单的轻量沟通，可升级为正式工单
+type OrderMessageService struct {
+	db           *gorm.DB
+	emailService *EmailService
+}
+
+func NewOrderMessageService(db *gorm.DB, emailService *EmailService) *OrderMessageService {
+	return &OrderMessageService{db: db, emailService: emailService}
+}
+
+func orderMessagePreview(content string) string {
+	runes := []rune(strings.TrimSpace(content))
+	if len(runes) <= orderMessagePreviewSize {
+		return string(runes)
+	}
+	return string(runes[:orderMessagePreviewSize-3]) + "..."
+}
+
+func orderMessageTicketEnabled() bool {
+	cfg := config.GetConfig()
+	return cfg != nil && cfg.Ticket.Enabled
+}
+
+func orderMessageSenderName(user *models.User) string {
+	if name := strings.TrimSpace(user.Name); name != "" {
+		return name
+	}
+	return user.Email
+}
+
+// escalatedTicket 返回留言已升级的工单（工单被删除后视为未升级）
+func (s *OrderMessageService) escalatedTicket(orderID uint) (*models.Ticket, error) {
+	var message models.OrderMessage
+	err := s.db.Where("order_id = ? AND ticket_id IS NOT NULL", orderID).Order("id DESC").First(&message).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ticket models.Ticket
+	err = s.db.Select("id", "ticket_no", "subject", "status", "created_at").First(&ticket, *message.TicketID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// Thread 获取订单留言，并将对方发送的消息标记为已读（viewer 为 user/admin）
+func (s *OrderMessageService) Thread(orderID uint, viewer string) (*OrderMessageThread, error) {
+	var messages []models.OrderMessage
+	if err := s.db.Where("order_id = ?", orderID).Order("created_at ASC, id ASC").Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	ticket, err := s.escalatedTicket(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	readColumn := "is_read_by_user"
+	if viewer == OrderMessageSenderAdmin {
+		readColumn = "is_read_by_admin"
+	}
+	if err := s.db.Model(&models.OrderMessage{}).
+		Where("order_id = ? AND "+readColumn+" = ?", orderID, false).
+		Update(readColumn, true).Error; err != nil {
+		return nil, err
+	}
+
+	return &OrderMessageThread{
+		Messages:    messages,
+		Ticket:      ticket,
+		CanEscalate: ticket == nil && len(messages) > 0 && orderMessageTicketEnabled(),
+	}, nil
+}
+
+// Send 发送留言并邮件通知对方；留言升级为工单后只能在工单中继续沟通
+func (s *OrderMessageService) Send(order *models.Order, senderType string, senderID uint, content string) (*models.OrderMessage, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, ErrOrderMessageContentRequired
+	}
+	if len([]rune(content)) > orderMessageMaxLength {
+		return nil, bizerr.Newf("orderMessage.contentTooLong", "Message cannot exceed %d characters", orderMessageMaxLength).
+			WithParams(map[string]interface{}{"max": orderMessageMaxLength})
+	}
+	if order.UserID == nil {
+		return nil, ErrOrderMessageGuestOrder
+	}
+	ticket, err := s.escalatedTicket(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket != nil {
+		return nil, ErrOrderMessageEscalated
+	}
+	var sender models.User
+	if err := s.db.First(&sender, senderID).Error; err != nil {
+		return nil, err
+	}
+
+	message := &models.OrderMessage{
+		OrderID:       order.ID,
+		SenderType:    senderType,
+		SenderID:      sender.ID,
+		SenderName:    orderMessageSenderName(&sender),
+		Content:       content,
+		IsReadByUser:  senderType == OrderMessageSenderUser,
+		IsReadByAdmin: senderType == OrderMessageSenderAdmin,
+	}
+	if err := s.db.Create(message).Error; err != nil {
+		return nil, err
+	}
+
+	if s.emailService != nil {
+		preview := orderMessagePreview(content)
+		if senderType == OrderMessageSenderUser {
+			go s.emailService.SendOrderMessageUserEmail(order, message.SenderName, preview)
+		} else {
+			go s.emailService.SendOrderMessageAdminReplyEmail(order, message.SenderName, preview)
+		}
+	}
+	return message, nil
+}
+
+// buildEscalationTranscript 将留言整理为工单正文
+func buildEscalationTranscript(order *models.Order, messages []models.OrderMessage) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Conversation about order %s:\n", order.OrderNo)
+	for _, message := range messages {
+		if message.SenderType == OrderMessageSenderSystem {
+			continue
+		}
+		fmt.Fprintf(&b, "\n**%s** (%s, %s):\n%s\n",
+			message.SenderName, message.SenderType, message.CreatedAt.Format("2006-01-02 15:04"), message.Content)
+	}
+	return validator.SanitizeMarkdown(b.String())
+}
+
+// Escalate 将订单留言升级为工单：工单正文为留言记录，并自动向客服分享该订单
+func (s *OrderMessageService) Escalate(order *models.Order, actorType string, actorID uint) (*models.Ticket, error) {
+	if !orderMessageTicketEnabled() {
+		return nil, ErrOrderMessageTicketDisabled
+	}
+	if order.UserID == nil {
+		return nil, ErrOrderMessageGuestOrder
+	}
+	existing, err := s.escalatedTicket(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrOrderMessageEscalated
+	}
+	var messages []models.OrderMessage
+	if err := s.db.Where("order_id = ? AND sender_type <> ?", order.ID, OrderMessageSenderSystem).
+		Order("created_at ASC, id ASC").Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, ErrOrderMessageEmptyThread
+	}
+
+	var customer, actor models.User
+	if err := s.db.First(&customer, *order.UserID).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.First(&actor, actorID).Error; err != nil {
+		return nil, err
+	}
+	transcript := buildEscalationTranscript(order, messages)
+	byAdmin := actorType == OrderMessageSenderAdmin
+	actorName := orderMessageSenderName(&actor)
+	now := time.Now()
+
+	ticket := &models.Ticket{
+		TicketNo:           GenerateTicketNo(),
+		UserID:             customer.ID,
+		Subject:            fmt.Sprintf("Order %s", order.OrderNo),
+		Content:            transcript,
+		Priority:           models.TicketPriorityNormal,
+		Status:             models.TicketStatusOpen,
+		LastMessageAt:      &now,
+		LastMessagePreview: orderMessagePreview(transcript),
+		LastMessageBy:      actorType,
+	}
+	if byAdmin {
+		ticket.AssignedTo = &actor.ID
+		ticket.UnreadCountUser = 1
+	} else {
+		ticket.UnreadCountAdmin = 1
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ticket).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.TicketMessage{
+			TicketID:      ticket.ID,
+			SenderType:    actorType,
+			SenderID:      actor.ID,
+			SenderName:    actorName,
+			Content:       transcript,
+			ContentType:   "text",
+			IsReadByUser:  !byAdmin,
+			IsReadByAdmin: byAdmin,
+		}).Error; err != nil {
+			return err
+		}
+
+		// 与用户在工单中绑定订单的行为一致：自动授权客服查看订单
+		if err := tx.Create(&models.TicketOrderAccess{
+			TicketID:  ticket.ID,
+			OrderID:   order.ID,
+			GrantedBy: customer.ID,
+			CanView:   true,
+		}).Error; err != nil {
+			return err
+		}
+		metadataBytes, _ := json.Marshal(map[string]interface{}{
+			"order_id": order.ID,
+			"order_no": order.OrderNo,
+		})
+		if err := tx.Create(&models.TicketMessage{
+			TicketID:      ticket.ID,
+			SenderType:    "user",
+			SenderID:      customer.ID,
+			SenderName:    customer.Name,
+			Content:       fmt.Sprintf("Shared order %s", order.OrderNo),
+			ContentType:   "order",
+			Metadata:      models.JSON(metadataBytes),
+			IsReadByUser:  true,
+			IsReadByAdmin: byAdmin,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.OrderMessage{
+			OrderID:       order.ID,
+			SenderType:    OrderMessageSenderSystem,
+			SenderID:      actor.ID,
+			SenderName:    actorName,
+			Content:       fmt.Sprintf("Escalated to ticket %s", ticket.TicketNo),
+			TicketID:      &ticket.ID,
+			IsReadByUser:  !byAdmin,
+			IsReadByAdmin: byAdmin,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.emailService != nil {
+		if byAdmin {
+			go s.emailService.SendTicketAdminReplyEmail(ticket, actorName, ticket.LastMessagePreview)
+		} else {
+			go s.emailService.SendTicketCreatedEmail(ticket, customer.Email)
+		}
+	}
+	return ticket, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestOrderMessageThreadAndEscalation(t *testing.T) {
+	cfg := loadTicketAttachmentTestConfig(t)
+	ticketEnabled := cfg.Ticket.Enabled
+	t.Cleanup(func() { cfg.Ticket.Enabled = ticketEnabled })
+	cfg.Ticket.Enabled = false
+
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Order{}, &models.OrderMessage{},
+		&models.Ticket{}, &models.TicketMessage{}, &models.TicketOrderAccess{})
+	svc := NewOrderMessageService(db, nil)
+
+	customer := models.User{UUID: "msg-customer", Email: "buyer@example.com", Name: "Buyer", Role: "user", IsActive: true}
+	admin := models.User{UUID: "msg-admin", Email: "seller@example.com", Name: "Seller", Role: "admin", IsActive: true}
+	for _, user := range []*models.User{&customer, &admin} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user failed: %v", err)
+		}
+	}
+	order := &models.Order{OrderNo: "MSG-1", UserID: &customer.ID, Status: models.OrderStatusShipped}
+	guestOrder := &models.Order{OrderNo: "MSG-GUEST", Status: models.OrderStatusShipped}
+	for _, o := range []*models.Order{order, guestOrder} {
+		if err := db.Create(o).Error; err != nil {
+			t.Fatalf("create order failed: %v", err)
+		}
+	}
+
+	_, err := svc.Send(order, OrderMessageSenderUser, customer.ID, "  ")
+	requireProductBizErr(t, err, "orderMessage.contentRequired")
+	_, err = svc.Send(guestOrder, OrderMessageSenderAdmin, admin.ID, "hello")
+	requireProductBizErr(t, err, "orderMessage.guestOrder")
+	_, err = svc.Escalate(order, OrderMessageSenderUser, customer.ID)
+	requireProductBizErr(t, err, "orderMessage.ticketDisabled")
+
+	cfg.Ticket.Enabled = true
+	_, err = svc.Escalate(order, OrderMessageSenderUser, customer.ID)
+	requireProductBizErr(t, err, "orderMessage.emptyThread")
+
+	if _, err := svc.Send(order, OrderMessageSenderUser, customer.ID, "Where is my parcel?"); err != nil {
+		t.Fatalf("send user message failed: %v", err)
+	}
+	reply, err := svc.Send(order, OrderMessageSenderAdmin, admin.ID, "It left the warehouse today.")
+	if err != nil {
+		t.Fatalf("send admin reply failed: %v", err)
+	}
+	if reply.SenderName != "Seller" || !reply.IsReadByAdmin || reply.IsReadByUser {
+		t.Fatalf("unexpected reply: %+v", reply)
+	}
+
+	thread, err := svc.Thread(order.ID, OrderMessageSenderUser)
+	if err != nil || len(thread.Messages) != 2 || !thread.CanEscalate || thread.Ticket != nil {
+		t.Fatalf("unexpected thread: %+v, %v", thread, err)
+	}
+	var unread int64
+	db.Model(&models.OrderMessage{}).Where("order_id = ? AND is_read_by_user = ?", order.ID, false).Count(&unread)
+	if unread != 0 {
+		t.Fatalf("expected viewing the thread to mark seller replies read, %d unread", unread)
+	}
+
+	ticket, err := svc.Escalate(order, OrderMessageSenderUser, customer.ID)
+	if err != nil {
+		t.Fatalf("escalate failed: %v", err)
+	}
+	if ticket.UserID != customer.ID || ticket.Subject != "Order MSG-1" ||
+		!strings.Contains(ticket.Content, "Where is my parcel?") || !strings.Contains(ticket.Content, "It left the warehouse today.") {
+		t.Fatalf("unexpected ticket: %+v", ticket)
+	}
+	var access models.TicketOrderAccess
+	if err := db.Where("ticket_id = ? AND order_id = ?", ticket.ID, order.ID).First(&access).Error; err != nil || !access.CanView {
+		t.Fatalf("expected order to be shared with the ticket: %+v, %v", access, err)
+	}
+	var ticketMessages int64
+	db.Model(&models.TicketMessage{}).Where("ticket_id = ?", ticket.ID).Count(&ticketMessages)
+	if ticketMessages != 2 {
+		t.Fatalf("expected transcript and shared-order messages, got %d", ticketMessages)
+	}
+
+	thread, err = svc.Thread(order.ID, OrderMessageSenderAdmin)
+	if err != nil || thread.Ticket == nil || thread.Ticket.ID != ticket.ID || thread.CanEscalate || len(thread.Messages) != 3 {
+		t.Fatalf("expected escalated thread, got %+v, %v", thread, err)
+	}
+	if _, err := svc.Send(order, OrderMessageSenderUser, customer.ID, "any news?"); !errors.Is(err, ErrOrderMessageEscalated) {
+		t.Fatalf("expected escalated thread to be read-only, got %v", err)
+	}
+	if _, err := svc.Escalate(order, OrderMessageSenderAdmin, admin.ID); !errors.Is(err, ErrOrderMessageEscalated) {
+		t.Fatalf("expected a thread to be escalated only once, got %v", err)
+	}
+}
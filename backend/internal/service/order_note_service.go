@@ -0,0 +1,261 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+const (
+	orderNoteMaxLength   = 2000
+	orderNoteMaxMentions = 10
+)
+
+var orderNoteAdminRoles = []string{"admin", "super_admin"}
+
+var (
+	ErrOrderNoteNotFound        = bizerr.New("orderNote.notFound", "Order note not found")
+	ErrOrderNoteContentRequired = bizerr.New("orderNote.contentRequired", "Note content is required")
+	ErrOrderNoteNotAuthor       = bizerr.New("orderNote.notAuthor", "Only the author can modify this note")
+	ErrOrderNoteMentionInvalid  = bizerr.New("orderNote.mentionInvalid", "Mentioned users must be active administrators with order access")
+)
+
+func truncateOrderNoteContent(content string) string {
+	runes := []rune(content)
+	if len(runes) <= orderNoteMaxLength {
+		return content
+	}
+	return string(runes[:orderNoteMaxLength])
+}
+
+// AddOrderNoteTx 在事务内追加一条订单备注（content 为空时忽略），用于下单/完成/取消/退款等流程自动记录
+func AddOrderNoteTx(tx *gorm.DB, orderID uint, authorID *uint, source, content string) error {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil
+	}
+	note := &models.OrderNote{
+		OrderID:  orderID,
+		AuthorID: authorID,
+		Source:   source,
+		Content:  truncateOrderNoteContent(content),
+	}
+	return tx.Create(note).Error
+}
+
+// OrderNoteService 订单内部备注
+type OrderNoteService struct {
+	db           *gorm.DB
+	emailService *EmailService
+}
+
+func NewOrderNoteService(db *gorm.DB, emailService *EmailService) *OrderNoteService {
+	return &OrderNoteService{db: db, emailService: emailService}
+}
+
+func preloadOrderNoteAuthor(db *gorm.DB) *gorm.DB {
+	return db.Preload("Author", func(tx *gorm.DB) *gorm.DB {
+		return tx.Unscoped().Select("id", "uuid", "name", "email", "avatar", "role")
+	})
+}
+
+func validateOrderNoteContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", ErrOrderNoteContentRequired
+	}
+	if len([]rune(content)) > orderNoteMaxLength {
+		return "", bizerr.Newf("orderNote.contentTooLong", "Note content cannot exceed %d characters", orderNoteMaxLength).
+			WithParams(map[string]interface{}{"max": orderNoteMaxLength})
+	}
+	return content, nil
+}
+
+// List 订单备注列表：置顶在前，其余按时间先后
+func (s *OrderNoteService) List(orderID uint) ([]models.OrderNote, error) {
+	var notes []models.OrderNote
+	err := preloadOrderNoteAuthor(s.db).
+		Where("order_id = ?", orderID).
+		Order("pinned DESC, created_at ASC, id ASC").
+		Find(&notes).Error
+	return notes, err
+}
+
+// Get 获取属于指定订单的备注
+func (s *OrderNoteService) Get(orderID, noteID uint) (*models.OrderNote, error) {
+	var note models.OrderNote
+	if err := preloadOrderNoteAuthor(s.db).Where("id = ? AND order_id = ?", noteID, orderID).First(&note).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNoteNotFound
+		}
+		return nil, err
+	}
+	return &note, nil
+}
+
+// resolveMentions 校验被提及的管理员（需为启用状态且能查看订单），忽略作者本人
+func (s *OrderNoteService) resolveMentions(authorID uint, mentionIDs []uint) ([]models.User, error) {
+	ids := make([]uint, 0, len(mentionIDs))
+	seen := make(map[uint]struct{}, len(mentionIDs))
+	for _, id := range mentionIDs {
+		if id == 0 || id == authorID {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if len(ids) > orderNoteMaxMentions {
+		return nil, bizerr.Newf("orderNote.tooManyMentions", "A note can mention at most %d administrators", orderNoteMaxMentions).
+			WithParams(map[string]interface{}{"max": orderNoteMaxMentions})
+	}
+
+	var admins []models.User
+	if err := s.db.Where("id IN ? AND is_active = ? AND role IN ?", ids, true, orderNoteAdminRoles).
+		Find(&admins).Error; err != nil {
+		return nil, err
+	}
+	admins, err := s.filterOrderViewers(admins)
+	if err != nil {
+		return nil, err
+	}
+	if len(admins) != len(ids) {
+		return nil, ErrOrderNoteMentionInvalid
+	}
+	return admins, nil
+}
+
+// filterOrderViewers 过滤出有订单查看权限的管理员（超级管理员拥有全部权限）
+func (s *OrderNoteService) filterOrderViewers(admins []models.User) ([]models.User, error) {
+	adminIDs := make([]uint, 0, len(admins))
+	for _, admin := range admins {
+		if admin.Role != "super_admin" {
+			adminIDs = append(adminIDs, admin.ID)
+		}
+	}
+	allowed := make(map[uint]bool, len(adminIDs))
+	if len(adminIDs) > 0 {
+		var perms []models.AdminPermission
+		if err := s.db.Where("user_id IN ?", adminIDs).Find(&perms).Error; err != nil {
+			return nil, err
+		}
+		for i := range perms {
+			allowed[perms[i].UserID] = perms[i].HasPermission("order.view")
+		}
+	}
+
+	result := make([]models.User, 0, len(admins))
+	for _, admin := range admins {
+		if admin.Role == "super_admin" || allowed[admin.ID] {
+			result = append(result, admin)
+		}
+	}
+	return result, nil
+}
+
+// MentionableAdmins 可在订单备注中提及的管理员
+func (s *OrderNoteService) MentionableAdmins() ([]models.User, error) {
+	var admins []models.User
+	if err := s.db.Select("id", "uuid", "name", "email", "avatar", "role").
+		Where("is_active = ? AND role IN ?", true, orderNoteAdminRoles).
+		Order("id ASC").
+		Find(&admins).Error; err != nil {
+		return nil, err
+	}
+	return s.filterOrderViewers(admins)
+}
+
+// Create 添加备注，被提及的管理员会收到邮件通知
+func (s *OrderNoteService) Create(order *models.Order, authorID uint, content string, mentionIDs []uint) (*models.OrderNote, error) {
+	content, err := validateOrderNoteContent(content)
+	if err != nil {
+		return nil, err
+	}
+	mentioned, err := s.resolveMentions(authorID, mentionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &models.OrderNote{
+		OrderID:  order.ID,
+		AuthorID: &authorID,
+		Source:   models.OrderNoteSourceManual,
+		Content:  content,
+	}
+	for _, admin := range mentioned {
+		note.MentionIDs = append(note.MentionIDs, admin.ID)
+	}
+	if err := s.db.Create(note).Error; err != nil {
+		return nil, err
+	}
+
+	if s.emailService != nil && len(mentioned) > 0 {
+		var author models.User
+		s.db.Select("id", "name", "email").First(&author, authorID)
+		authorName := strings.TrimSpace(author.Name)
+		if authorName == "" {
+			authorName = author.Email
+		}
+		for _, admin := range mentioned {
+			go s.emailService.SendOrderNoteMentionEmail(order, admin, authorName, content)
+		}
+	}
+	return s.Get(order.ID, note.ID)
+}
+
+// getOwnNote 获取可由 authorID 修改的备注（系统备注和他人备注不可修改）
+func (s *OrderNoteService) getOwnNote(orderID, noteID, authorID uint) (*models.OrderNote, error) {
+	note, err := s.Get(orderID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.AuthorID == nil || *note.AuthorID != authorID || note.Source != models.OrderNoteSourceManual {
+		return nil, ErrOrderNoteNotAuthor
+	}
+	return note, nil
+}
+
+// Update 作者修改自己的备注内容
+func (s *OrderNoteService) Update(orderID, noteID, authorID uint, content string) (*models.OrderNote, error) {
+	content, err := validateOrderNoteContent(content)
+	if err != nil {
+		return nil, err
+	}
+	note, err := s.getOwnNote(orderID, noteID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(note).Update("content", content).Error; err != nil {
+		return nil, err
+	}
+	return s.Get(orderID, noteID)
+}
+
+// Delete 作者删除自己的备注
+func (s *OrderNoteService) Delete(orderID, noteID, authorID uint) error {
+	note, err := s.getOwnNote(orderID, noteID, authorID)
+	if err != nil {
+		return err
+	}
+	return s.db.Delete(note).Error
+}
+
+// SetPinned 置顶/取消置顶备注（任何可编辑订单的管理员均可操作）
+func (s *OrderNoteService) SetPinned(orderID, noteID uint, pinned bool) (*models.OrderNote, error) {
+	note, err := s.Get(orderID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(note).Update("pinned", pinned).Error; err != nil {
+		return nil, err
+	}
+	return s.Get(orderID, noteID)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestOrderNoteThread(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.AdminPermission{}, &models.Order{}, &models.OrderNote{})
+	svc := NewOrderNoteService(db, nil)
+
+	users := make([]models.User, 0, 4)
+	for i, role := range []string{"admin", "super_admin", "admin", "user"} {
+		user := models.User{UUID: fmt.Sprintf("note-user-%d", i), Email: fmt.Sprintf("note%d@example.com", i), Role: role, IsActive: true}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("create user failed: %v", err)
+		}
+		users = append(users, user)
+	}
+	author, superAdmin, limitedAdmin, customer := users[0], users[1], users[2], users[3]
+	for userID, perms := range map[uint][]string{author.ID: {"order.view", "order.edit"}, limitedAdmin.ID: {"product.view"}} {
+		if err := db.Create(&models.AdminPermission{UserID: userID, Permissions: perms}).Error; err != nil {
+			t.Fatalf("create permission failed: %v", err)
+		}
+	}
+
+	mentionable, err := svc.MentionableAdmins()
+	if err != nil || len(mentionable) != 2 || mentionable[0].ID != author.ID || mentionable[1].ID != superAdmin.ID {
+		t.Fatalf("expected admins with order access to be mentionable, got %+v, %v", mentionable, err)
+	}
+
+	order := &models.Order{OrderNo: "NOTE-1", Status: models.OrderStatusPending}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+	if err := AddOrderNoteTx(db, order.ID, nil, models.OrderNoteSourceCancel, "  "); err != nil {
+		t.Fatalf("add empty system note failed: %v", err)
+	}
+	if err := AddOrderNoteTx(db, order.ID, nil, models.OrderNoteSourceAutomation, "[automation:VIP] check address"); err != nil {
+		t.Fatalf("add system note failed: %v", err)
+	}
+
+	_, err = svc.Create(order, author.ID, "   ", nil)
+	requireProductBizErr(t, err, "orderNote.contentRequired")
+	for _, invalid := range []uint{limitedAdmin.ID, customer.ID, 9999} {
+		if _, err := svc.Create(order, author.ID, "please check", []uint{invalid}); !errors.Is(err, ErrOrderNoteMentionInvalid) {
+			t.Fatalf("expected mention of user %d to be rejected, got %v", invalid, err)
+		}
+	}
+
+	first, err := svc.Create(order, author.ID, "customer called about delivery", []uint{superAdmin.ID, author.ID, superAdmin.ID})
+	if err != nil {
+		t.Fatalf("create note failed: %v", err)
+	}
+	if len(first.MentionIDs) != 1 || first.MentionIDs[0] != superAdmin.ID || first.Author == nil || first.Author.ID != author.ID {
+		t.Fatalf("unexpected note: %+v", first)
+	}
+	second, err := svc.Create(order, superAdmin.ID, "refund approved", nil)
+	if err != nil {
+		t.Fatalf("create note failed: %v", err)
+	}
+	if _, err := svc.SetPinned(order.ID, second.ID, true); err != nil {
+		t.Fatalf("pin note failed: %v", err)
+	}
+
+	notes, err := svc.List(order.ID)
+	if err != nil {
+		t.Fatalf("list notes failed: %v", err)
+	}
+	if len(notes) != 3 || notes[0].ID != second.ID || notes[1].Source != models.OrderNoteSourceAutomation || notes[2].ID != first.ID {
+		t.Fatalf("expected pinned note first then chronological order, got %+v", notes)
+	}
+
+	if _, err := svc.Update(order.ID, first.ID, superAdmin.ID, "edited"); !errors.Is(err, ErrOrderNoteNotAuthor) {
+		t.Fatalf("expected only author to edit, got %v", err)
+	}
+	if err := svc.Delete(order.ID, notes[1].ID, author.ID); !errors.Is(err, ErrOrderNoteNotAuthor) {
+		t.Fatalf("expected system note to be read-only, got %v", err)
+	}
+	updated, err := svc.Update(order.ID, first.ID, author.ID, "customer called twice")
+	if err != nil || updated.Content != "customer called twice" {
+		t.Fatalf("update note: %+v, %v", updated, err)
+	}
+	if _, err := svc.Get(order.ID+1, first.ID); !errors.Is(err, ErrOrderNoteNotFound) {
+		t.Fatalf("expected note lookup to be scoped to its order, got %v", err)
+	}
+	if err := svc.Delete(order.ID, first.ID, author.ID); err != nil {
+		t.Fatalf("delete note failed: %v", err)
+	}
+	if notes, _ := svc.List(order.ID); len(notes) != 2 {
+		t.Fatalf("expected 2 notes after delete, got %d", len(notes))
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxAutoCancelOverrideHours 商品/付款方式可覆盖的最长未付款时限（30 天）
+const maxAutoCancelOverrideHours = 720
+
+// globalAutoCancelHours 全局未付款自动取消小时数，未配置时使用默认值
+func globalAutoCancelHours(cfg *config.Config) int {
+	if cfg != nil && cfg.Order.AutoCancelHours > 0 {
+		return cfg.Order.AutoCancelHours
+	}
+	return defaultAutoCancelHours
+}
+
+// resolveAutoCancelHours 计算订单实际的未付款时限：
+// 已选付款方式有覆盖时优先使用，否则取订单内商品覆盖值中最短的，均未设置时使用全局值
+func resolveAutoCancelHours(cfg *config.Config, products []*models.Product, paymentMethod *models.PaymentMethod) int {
+	if paymentMethod != nil && paymentMethod.AutoCancelHours > 0 {
+		return paymentMethod.AutoCancelHours
+	}
+	hours := 0
+	for _, product := range products {
+		if product == nil || product.AutoCancelHours <= 0 {
+			continue
+		}
+		if hours == 0 || product.AutoCancelHours < hours {
+			hours = product.AutoCancelHours
+		}
+	}
+	if hours > 0 {
+		return hours
+	}
+	return globalAutoCancelHours(cfg)
+}
+
+// OrderPaymentDeadline 待付款订单的截止时间，旧订单未保存时按全局时限推算
+func OrderPaymentDeadline(cfg *config.Config, order *models.Order) time.Time {
+	if order.PaymentDeadlineAt != nil {
+		return *order.PaymentDeadlineAt
+	}
+	return order.CreatedAt.Add(time.Duration(globalAutoCancelHours(cfg)) * time.Hour)
+}
+
+// orderAutoCancelHours 订单适用的未付款时限（小时），用于取消备注与插件载荷
+func orderAutoCancelHours(cfg *config.Config, order *models.Order) int {
+	if order.PaymentDeadlineAt == nil {
+		return globalAutoCancelHours(cfg)
+	}
+	hours := int(order.PaymentDeadlineAt.Sub(order.CreatedAt).Round(time.Hour) / time.Hour)
+	if hours <= 0 {
+		return globalAutoCancelHours(cfg)
+	}
+	return hours
+}
+
+// loadOrderProductsBySKU 按订单项 SKU 加载商品（含已删除），用于计算商品级覆盖
+func loadOrderProductsBySKU(db *gorm.DB, order *models.Order) ([]*models.Product, error) {
+	skus := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.SKU != "" {
+			skus = append(skus, item.SKU)
+		}
+	}
+	if len(skus) == 0 {
+		return nil, nil
+	}
+	var products []models.Product
+	query := db.Unscoped().Select("id", "sku", "store_id", "auto_cancel_hours").Where("sku IN ?", skus)
+	if order.StoreID != nil {
+		query = query.Where("store_id IS NULL OR store_id = ?", *order.StoreID)
+	}
+	if err := query.Find(&products).Error; err != nil {
+		return nil, err
+	}
+	result := make([]*models.Product, 0, len(products))
+	for i := range products {
+		result = append(result, &products[i])
+	}
+	return result, nil
+}
+
+// refreshOrderPaymentDeadline 选择付款方式后按新的覆盖规则重算截止时间
+func refreshOrderPaymentDeadline(db *gorm.DB, cfg *config.Config, order *models.Order, paymentMethod *models.PaymentMethod) error {
+	products, err := loadOrderProductsBySKU(db, order)
+	if err != nil {
+		return err
+	}
+	hours := resolveAutoCancelHours(cfg, products, paymentMethod)
+	deadline := order.CreatedAt.Add(time.Duration(hours) * time.Hour)
+	if err := db.Model(&models.Order{}).Where("id = ? AND status = ?", order.ID, models.OrderStatusPendingPayment).
+		Update("payment_deadline_at", deadline).Error; err != nil {
+		return err
+	}
+	order.PaymentDeadlineAt = &deadline
+	return nil
+}
+
+// assignOrderPaymentDeadlineTx 创建待付款订单前写入截止时间（按商品覆盖或全局时限）
+func assignOrderPaymentDeadlineTx(tx *gorm.DB, cfg *config.Config, order *models.Order) error {
+	if order.Status != models.OrderStatusPendingPayment {
+		return nil
+	}
+	products, err := loadOrderProductsBySKU(tx, order)
+	if err != nil {
+		return err
+	}
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = models.NowFunc()
+	}
+	deadline := order.CreatedAt.Add(time.Duration(resolveAutoCancelHours(cfg, products, nil)) * time.Hour)
+	order.PaymentDeadlineAt = &deadline
+	return nil
+}
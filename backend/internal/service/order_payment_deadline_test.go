@@ -0,0 +1,144 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+)
+
+func TestResolveAutoCancelHoursPrecedence(t *testing.T) {
+	cfg := &config.Config{Order: config.OrderConfig{AutoCancelHours: 48}}
+	products := []*models.Product{{AutoCancelHours: 0}, {AutoCancelHours: 24}, {AutoCancelHours: 6}}
+
+	if got := resolveAutoCancelHours(cfg, nil, nil); got != 48 {
+		t.Fatalf("expected global hours, got %d", got)
+	}
+	if got := resolveAutoCancelHours(&config.Config{}, nil, nil); got != defaultAutoCancelHours {
+		t.Fatalf("expected default hours, got %d", got)
+	}
+	if got := resolveAutoCancelHours(cfg, products, nil); got != 6 {
+		t.Fatalf("expected shortest product override, got %d", got)
+	}
+	if got := resolveAutoCancelHours(cfg, products, &models.PaymentMethod{AutoCancelHours: 168}); got != 168 {
+		t.Fatalf("expected payment method override, got %d", got)
+	}
+	if got := resolveAutoCancelHours(cfg, products, &models.PaymentMethod{}); got != 6 {
+		t.Fatalf("expected product override when method has none, got %d", got)
+	}
+}
+
+func TestCancelExpiredOrdersHonorsPaymentDeadline(t *testing.T) {
+	_, db := newOrderServiceTestDB(t)
+	if err := db.AutoMigrate(&models.OrderNote{}, &models.LedgerEntry{}, &models.PaymentAmountReservation{}, &models.OperationLog{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	cfg := &config.Config{Order: config.OrderConfig{AutoCancelHours: 72}}
+	svc := NewOrderCancelService(db, cfg, repository.NewInventoryRepository(db), repository.NewPromoCodeRepository(db), nil, nil)
+
+	now := time.Now()
+	newPending := func(orderNo string, createdAt time.Time, deadline *time.Time) *models.Order {
+		order := &models.Order{
+			OrderNo:           orderNo,
+			Status:            models.OrderStatusPendingPayment,
+			PaymentDeadlineAt: deadline,
+			CreatedAt:         createdAt,
+			Items:             []models.OrderItem{{SKU: "SKU-1", Name: "Mug", Quantity: 1}},
+		}
+		if err := db.Create(order).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+		return order
+	}
+	pastDeadline := now.Add(-time.Minute)
+	futureDeadline := now.Add(24 * time.Hour)
+	shortWindow := newPending("P-SHORT", now.Add(-2*time.Hour), &pastDeadline)
+	longWindow := newPending("P-LONG", now.Add(-100*time.Hour), &futureDeadline)
+	legacyExpired := newPending("P-LEGACY-OLD", now.Add(-100*time.Hour), nil)
+	legacyRecent := newPending("P-LEGACY-NEW", now.Add(-time.Hour), nil)
+
+	svc.cancelExpiredOrders()
+
+	expect := map[*models.Order]models.OrderStatus{
+		shortWindow:   models.OrderStatusCancelled,
+		longWindow:    models.OrderStatusPendingPayment,
+		legacyExpired: models.OrderStatusCancelled,
+		legacyRecent:  models.OrderStatusPendingPayment,
+	}
+	for order, status := range expect {
+		var reloaded models.Order
+		if err := db.First(&reloaded, order.ID).Error; err != nil {
+			t.Fatalf("reload: %v", err)
+		}
+		if reloaded.Status != status {
+			t.Fatalf("expected %s to be %s, got %s", order.OrderNo, status, reloaded.Status)
+		}
+	}
+
+	var note models.OrderNote
+	if err := db.Where("order_id = ?", shortWindow.ID).First(&note).Error; err != nil {
+		t.Fatalf("load cancel note: %v", err)
+	}
+	if note.Content != "System auto-cancelled: order unpaid after 2 hours" {
+		t.Fatalf("expected note to use the order's own window, got %q", note.Content)
+	}
+}
+
+func TestSelectPaymentMethodRefreshesPaymentDeadline(t *testing.T) {
+	_, db := newOrderServiceTestDB(t)
+	if err := db.AutoMigrate(&models.PaymentMethod{}, &models.OrderPaymentMethod{}, &models.OperationLog{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	cfg := &config.Config{Order: config.OrderConfig{AutoCancelHours: 72}}
+
+	if err := db.Create(&models.Product{SKU: "SKU-SLOW", Name: "Invoice item", AutoCancelHours: 24}).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	crypto := &models.PaymentMethod{Name: "Crypto", Type: models.PaymentMethodTypeCustom, Enabled: true, AutoCancelHours: 1}
+	plain := &models.PaymentMethod{Name: "Card", Type: models.PaymentMethodTypeCustom, Enabled: true}
+	if err := db.Create(crypto).Error; err != nil {
+		t.Fatalf("create payment method: %v", err)
+	}
+	if err := db.Create(plain).Error; err != nil {
+		t.Fatalf("create payment method: %v", err)
+	}
+
+	order := &models.Order{
+		OrderNo: "P-SELECT",
+		Status:  models.OrderStatusPendingPayment,
+		Items:   []models.OrderItem{{SKU: "SKU-SLOW", Name: "Invoice item", Quantity: 1}},
+	}
+	if err := assignOrderPaymentDeadlineTx(db, cfg, order); err != nil {
+		t.Fatalf("assign deadline: %v", err)
+	}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if got := order.PaymentDeadlineAt.Sub(order.CreatedAt); got != 24*time.Hour {
+		t.Fatalf("expected product window on creation, got %s", got)
+	}
+
+	pmSvc := NewPaymentMethodService(db, cfg)
+	assertWindow := func(want time.Duration) {
+		t.Helper()
+		var reloaded models.Order
+		if err := db.First(&reloaded, order.ID).Error; err != nil {
+			t.Fatalf("reload: %v", err)
+		}
+		if reloaded.PaymentDeadlineAt == nil || reloaded.PaymentDeadlineAt.Sub(reloaded.CreatedAt) != want {
+			t.Fatalf("expected window %s, got deadline %v created %v", want, reloaded.PaymentDeadlineAt, reloaded.CreatedAt)
+		}
+	}
+
+	if err := pmSvc.SelectPaymentMethod(order.ID, crypto.ID); err != nil {
+		t.Fatalf("select crypto: %v", err)
+	}
+	assertWindow(time.Hour)
+
+	if err := pmSvc.SelectPaymentMethod(order.ID, plain.ID); err != nil {
+		t.Fatalf("select card: %v", err)
+	}
+	assertWindow(24 * time.Hour)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/logger"
+)
+
+// SetEmailService 注入邮件服务，用于发送待付款提醒
+func (s *OrderCancelService) SetEmailService(emailService *EmailService) {
+	s.emailService = emailService
+}
+
+// getPaymentReminderHours 自动取消前多少小时发送付款提醒，<=0 表示不提醒
+func (s *OrderCancelService) getPaymentReminderHours() int {
+	if s.cfg == nil {
+		return 0
+	}
+	return s.cfg.Order.PaymentReminderHours
+}
+
+// sendPaymentReminders 向即将因超时未付款被取消的订单发送提醒，每单只提醒一次（见 order_reminders）
+func (s *OrderCancelService) sendPaymentReminders() {
+	reminderHours := s.getPaymentReminderHours()
+	if reminderHours <= 0 {
+		return
+	}
+	now := models.NowFunc()
+	remindBefore := now.Add(time.Duration(reminderHours) * time.Hour)
+	globalWindow := time.Duration(s.getAutoCancelHours()) * time.Hour
+
+	var orders []models.Order
+	if err := s.db.Where("status = ?", models.OrderStatusPendingPayment).
+		Where("(payment_deadline_at IS NOT NULL AND payment_deadline_at > ? AND payment_deadline_at <= ?) OR (payment_deadline_at IS NULL AND created_at > ? AND created_at <= ?)",
+			now, remindBefore, now.Add(-globalWindow), remindBefore.Add(-globalWindow)).
+		Where("NOT EXISTS (SELECT 1 FROM order_reminders WHERE order_reminders.order_id = orders.id AND order_reminders.kind = ?)", models.OrderReminderKindPaymentDue).
+		Limit(100).Find(&orders).Error; err != nil {
+		log.Printf("[OrderCancel] Error querying orders for payment reminder: %v", err)
+		return
+	}
+
+	remindedCount := 0
+	for i := range orders {
+		sent, err := s.sendPaymentReminder(&orders[i])
+		if err != nil {
+			log.Printf("[OrderCancel] Error sending payment reminder for order %s: %v", orders[i].OrderNo, err)
+			continue
+		}
+		if sent {
+			remindedCount++
+		}
+	}
+
+	if remindedCount > 0 {
+		logger.LogSystemOperation(s.db, "order_payment_reminder", "system", nil, map[string]interface{}{
+			"reminded_count":         remindedCount,
+			"payment_reminder_hours": reminderHours,
+		})
+	}
+}
+
+// sendPaymentReminder 先写入提醒记录再发送，唯一索引冲突说明已由其他实例提醒过
+func (s *OrderCancelService) sendPaymentReminder(order *models.Order) (bool, error) {
+	deadline := OrderPaymentDeadline(s.cfg, order)
+	reminder := models.OrderReminder{
+		OrderID:    order.ID,
+		Kind:       models.OrderReminderKindPaymentDue,
+		DeadlineAt: deadline,
+	}
+	if err := s.db.Create(&reminder).Error; err != nil {
+		if isUniqueConstraintError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	paymentURL := s.orderPaymentURL(order)
+	if s.emailService != nil && s.emailService.canSendOrderEmail(order) {
+		if err := s.emailService.SendOrderPaymentReminderEmail(order, deadline, paymentURL); err != nil {
+			log.Printf("[OrderCancel] Order %s failed to queue payment reminder email: %v", order.OrderNo, err)
+		} else {
+			reminder.EmailQueued = true
+			s.db.Model(&reminder).Update("email_queued", true)
+		}
+	}
+
+	if s.pluginManager != nil {
+		payload := map[string]interface{}{
+			"order_id":            order.ID,
+			"order_no":            order.OrderNo,
+			"user_id":             order.UserID,
+			"user_email":          order.UserEmail,
+			"total_amount_minor":  order.TotalAmount,
+			"currency":            order.Currency,
+			"payment_deadline_at": deadline.UTC().Format(time.RFC3339),
+			"payment_url":         paymentURL,
+			"email_queued":        reminder.EmailQueued,
+			"source":              "order_payment_reminder",
+		}
+		go func(execCtx *ExecutionContext, hookPayload map[string]interface{}, orderNo string) {
+			_, hookErr := s.pluginManager.ExecuteHook(HookExecutionRequest{
+				Hook:    "order.payment_reminder.after",
+				Payload: hookPayload,
+			}, execCtx)
+			if hookErr != nil {
+				log.Printf("order.payment_reminder.after hook execution failed: order=%s err=%v", orderNo, hookErr)
+			}
+		}(cloneOrderCancelExecutionContext(s.buildInventoryHookExecutionContext(order)), payload, order.OrderNo)
+	}
+	return true, nil
+}
+
+// orderPaymentURL 订单付款页地址（站点地址未配置时为空）
+func (s *OrderCancelService) orderPaymentURL(order *models.Order) string {
+	if s.cfg == nil {
+		return ""
+	}
+	baseURL := strings.TrimRight(strings.TrimSpace(s.cfg.App.URL), "/")
+	if baseURL == "" {
+		return ""
+	}
+	return baseURL + "/orders/" + order.OrderNo
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func TestSendPaymentRemindersOncePerOrder(t *testing.T) {
+	_, db := newOrderServiceTestDB(t)
+	if err := db.AutoMigrate(&models.OrderReminder{}, &models.OperationLog{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	cfg := &config.Config{Order: config.OrderConfig{AutoCancelHours: 72, PaymentReminderHours: 12}}
+	svc := NewOrderCancelService(db, cfg, nil, nil, nil, nil)
+
+	now := models.NowFunc()
+	newPending := func(orderNo string, createdAt time.Time, deadline *time.Time) *models.Order {
+		order := &models.Order{
+			OrderNo:           orderNo,
+			Status:            models.OrderStatusPendingPayment,
+			PaymentDeadlineAt: deadline,
+			CreatedAt:         createdAt,
+		}
+		if err := db.Create(order).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+		return order
+	}
+	soon := now.Add(2 * time.Hour)
+	later := now.Add(48 * time.Hour)
+	dueSoon := newPending("R-SOON", now.Add(-time.Hour), &soon)
+	dueLater := newPending("R-LATER", now.Add(-time.Hour), &later)
+	legacyDueSoon := newPending("R-LEGACY", now.Add(-65*time.Hour), nil)
+	legacyFresh := newPending("R-FRESH", now.Add(-time.Hour), nil)
+
+	svc.sendPaymentReminders()
+	svc.sendPaymentReminders()
+
+	expect := map[*models.Order]int64{dueSoon: 1, dueLater: 0, legacyDueSoon: 1, legacyFresh: 0}
+	for order, want := range expect {
+		var count int64
+		db.Model(&models.OrderReminder{}).Where("order_id = ? AND kind = ?", order.ID, models.OrderReminderKindPaymentDue).Count(&count)
+		if count != want {
+			t.Fatalf("expected %d reminders for %s, got %d", want, order.OrderNo, count)
+		}
+	}
+
+	var reminder models.OrderReminder
+	if err := db.Where("order_id = ?", dueSoon.ID).First(&reminder).Error; err != nil {
+		t.Fatalf("load reminder: %v", err)
+	}
+	if !reminder.DeadlineAt.Equal(soon) || reminder.EmailQueued {
+		t.Fatalf("unexpected reminder record: %+v", reminder)
+	}
+}
+
+func TestSendPaymentRemindersDisabledByDefault(t *testing.T) {
+	_, db := newOrderServiceTestDB(t)
+	if err := db.AutoMigrate(&models.OrderReminder{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	svc := NewOrderCancelService(db, &config.Config{}, nil, nil, nil, nil)
+
+	deadline := models.NowFunc().Add(time.Hour)
+	if err := db.Create(&models.Order{OrderNo: "R-OFF", Status: models.OrderStatusPendingPayment, PaymentDeadlineAt: &deadline}).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	svc.sendPaymentReminders()
+
+	var count int64
+	db.Model(&models.OrderReminder{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected no reminders when payment_reminder_hours is 0, got %d", count)
+	}
+}
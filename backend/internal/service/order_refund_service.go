@@ -0,0 +1,494 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/money"
+	"auralogic/internal/pkg/orderbiz"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrderRefundNothingRefundable = bizerr.New("order.refundNothingRefundable", "Order has no refundable amount left")
+	ErrOrderRefundItemInvalid       = bizerr.New("order.refundItemInvalid", "Invalid refund item")
+	ErrOrderRefundNotFound          = bizerr.New("order.refundNotFound", "Refund not found")
+)
+
+func newOrderRefundAmountInvalidError(refundableMinor int64) error {
+	refundable := money.MinorToString(refundableMinor)
+	return bizerr.Newf("order.refundAmountInvalid", "Refund amount must be greater than 0 and not exceed %s", refundable).
+		WithParams(map[string]interface{}{"refundable": refundable})
+}
+
+func newOrderRefundItemExceededError(sku string, remainingQuantity int, remainingMinor int64) error {
+	remaining := money.MinorToString(remainingMinor)
+	return bizerr.Newf("order.refundItemExceeded", "Refund for %s exceeds what was paid (remaining quantity: %d, amount: %s)", sku, remainingQuantity, remaining).
+		WithParams(map[string]interface{}{"sku": sku, "quantity": remainingQuantity, "amount": remaining})
+}
+
+func newOrderRefundConfirmStatusInvalidError(status models.OrderRefundStatus) error {
+	return bizerr.Newf("order.refundConfirmStatusInvalid", "Only pending refunds can be confirmed (current status: %s)", status).
+		WithParams(map[string]interface{}{"status": status})
+}
+
+// OrderAcceptsRefund 只允许已付款后的订单退款（草稿表示已付款但用户尚未填写收货信息）
+func OrderAcceptsRefund(status models.OrderStatus) bool {
+	switch status {
+	case models.OrderStatusDraft, models.OrderStatusPending, models.OrderStatusNeedResubmit,
+		models.OrderStatusShipped, models.OrderStatusCompleted:
+		return true
+	}
+	return false
+}
+
+// 未发货的订单全额退款时需释放预留库存与优惠码
+func orderRefundReleasesReserves(status models.OrderStatus) bool {
+	return status == models.OrderStatusDraft || status == models.OrderStatusPending || status == models.OrderStatusNeedResubmit
+}
+
+// OrderRefundInput 退款请求；金额为 0 且未选择商品行时退还全部剩余可退金额
+type OrderRefundInput struct {
+	AmountMinor int64
+	Items       []models.OrderRefundItem
+	Reason      string
+	Source      string
+	AdminID     *uint
+}
+
+// OrderRefundSummary 订单退款汇总
+type OrderRefundSummary struct {
+	Currency            string               `json:"currency"`
+	TotalMinor          int64                `json:"total_minor"`
+	RefundedMinor       int64                `json:"refunded_minor"` // 已完成
+	PendingMinor        int64                `json:"pending_minor"`  // 处理中或待确认
+	RefundableMinor     int64                `json:"refundable_minor"`
+	RefundedQuantities  map[int]int          `json:"refunded_quantities"`
+	RefundedItemAmounts map[int]int64        `json:"refunded_item_amounts"`
+	Refunds             []models.OrderRefund `json:"refunds"`
+}
+
+// OrderRefundOutcome 一次退款的执行结果
+type OrderRefundOutcome struct {
+	Refund           *models.OrderRefund `json:"refund"`
+	Result           *RefundResult       `json:"result"`
+	StatusBefore     models.OrderStatus  `json:"status_before"`
+	StatusAfter      models.OrderStatus  `json:"status_after"`
+	ReleasedReserves bool                `json:"released_reserves"`
+}
+
+// OrderRefundService 订单退款：支持全额、部分与按商品行退款，每笔退款单独留档并同步账本与订单状态
+type OrderRefundService struct {
+	db           *gorm.DB
+	orderService *OrderService
+	jsRuntime    *JSRuntimeService
+}
+
+func NewOrderRefundService(db *gorm.DB, orderService *OrderService, jsRuntime *JSRuntimeService) *OrderRefundService {
+	return &OrderRefundService{db: db, orderService: orderService, jsRuntime: jsRuntime}
+}
+
+func summarizeOrderRefunds(order *models.Order, refunds []models.OrderRefund) *OrderRefundSummary {
+	summary := &OrderRefundSummary{
+		Currency:            order.Currency,
+		TotalMinor:          order.TotalAmount,
+		RefundedQuantities:  map[int]int{},
+		RefundedItemAmounts: map[int]int64{},
+		Refunds:             refunds,
+	}
+	for i := range refunds {
+		refund := &refunds[i]
+		if !refund.CountsTowardRefunded() {
+			continue
+		}
+		if refund.Status == models.OrderRefundStatusSucceeded {
+			summary.RefundedMinor += refund.AmountMinor
+		} else {
+			summary.PendingMinor += refund.AmountMinor
+		}
+		for _, item := range refund.Items {
+			summary.RefundedQuantities[item.ItemIndex] += item.Quantity
+			summary.RefundedItemAmounts[item.ItemIndex] += item.AmountMinor
+		}
+	}
+	summary.RefundableMinor = summary.TotalMinor - summary.RefundedMinor - summary.PendingMinor
+	if summary.RefundableMinor < 0 {
+		summary.RefundableMinor = 0
+	}
+	return summary
+}
+
+// 订单行实付金额；旧订单没有行金额时按单价估算，均缺失时返回 -1 表示不限制
+func orderItemPaidMinor(item models.OrderItem) int64 {
+	if item.LineTotalMinor > 0 {
+		return item.LineTotalMinor
+	}
+	if item.UnitPriceMinor > 0 {
+		return item.UnitPriceMinor * int64(item.Quantity)
+	}
+	return -1
+}
+
+// buildOrderRefund 校验退款金额与商品行并生成待执行的退款记录
+func buildOrderRefund(order *models.Order, summary *OrderRefundSummary, input OrderRefundInput) (*models.OrderRefund, error) {
+	requested := make(map[int]*models.OrderRefundItem, len(input.Items))
+	for _, item := range input.Items {
+		if item.ItemIndex < 0 || item.ItemIndex >= len(order.Items) || item.Quantity < 0 || item.AmountMinor < 0 ||
+			(item.Quantity == 0 && item.AmountMinor == 0) {
+			return nil, ErrOrderRefundItemInvalid
+		}
+		merged, ok := requested[item.ItemIndex]
+		if !ok {
+			merged = &models.OrderRefundItem{ItemIndex: item.ItemIndex, SKU: order.Items[item.ItemIndex].SKU}
+			requested[item.ItemIndex] = merged
+		}
+		merged.Quantity += item.Quantity
+		merged.AmountMinor += item.AmountMinor
+	}
+	indexes := make([]int, 0, len(requested))
+	for idx := range requested {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	items := make([]models.OrderRefundItem, 0, len(indexes))
+	var itemsTotal int64
+	for _, idx := range indexes {
+		item := requested[idx]
+		orderItem := order.Items[idx]
+		remainingQuantity := orderItem.Quantity - summary.RefundedQuantities[idx]
+		paid := orderItemPaidMinor(orderItem)
+		remainingMinor := paid - summary.RefundedItemAmounts[idx]
+		if item.AmountMinor == 0 {
+			// 未指定金额时按数量折算行实付金额
+			if paid < 0 || orderItem.Quantity <= 0 {
+				return nil, ErrOrderRefundItemInvalid
+			}
+			item.AmountMinor = paid * int64(item.Quantity) / int64(orderItem.Quantity)
+			if item.AmountMinor > remainingMinor {
+				item.AmountMinor = remainingMinor
+			}
+		}
+		if item.Quantity > remainingQuantity || (paid >= 0 && item.AmountMinor > remainingMinor) || item.AmountMinor <= 0 {
+			if remainingMinor < 0 {
+				remainingMinor = 0
+			}
+			return nil, newOrderRefundItemExceededError(orderItem.SKU, remainingQuantity, remainingMinor)
+		}
+		itemsTotal += item.AmountMinor
+		items = append(items, *item)
+	}
+
+	amount := input.AmountMinor
+	if amount == 0 {
+		amount = itemsTotal
+		if len(items) == 0 {
+			amount = summary.RefundableMinor
+		}
+	}
+	if summary.RefundableMinor <= 0 && order.TotalAmount > 0 {
+		return nil, ErrOrderRefundNothingRefundable
+	}
+	// 零元订单允许"退款"以取消订单并释放预留
+	zeroAmountOrder := order.TotalAmount == 0 && amount == 0 && len(items) == 0
+	if !zeroAmountOrder && (amount <= 0 || amount > summary.RefundableMinor || amount < itemsTotal) {
+		return nil, newOrderRefundAmountInvalidError(summary.RefundableMinor)
+	}
+
+	source := strings.TrimSpace(input.Source)
+	if source == "" {
+		source = "admin_refund"
+	}
+	return &models.OrderRefund{
+		OrderID:     order.ID,
+		OrderNo:     order.OrderNo,
+		Status:      models.OrderRefundStatusProcessing,
+		AmountMinor: amount,
+		Currency:    order.Currency,
+		Items:       items,
+		Full:        amount >= summary.RefundableMinor,
+		Reason:      input.Reason,
+		Source:      source,
+		AdminID:     input.AdminID,
+	}, nil
+}
+
+// Summary 订单退款汇总与记录列表
+func (s *OrderRefundService) Summary(order *models.Order) (*OrderRefundSummary, error) {
+	refunds, err := repository.NewRefundRepository(s.db).ListByOrder(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeOrderRefunds(order, refunds), nil
+}
+
+// OrderRefundQuote 本次退款预估及订单累计退款额
+type OrderRefundQuote struct {
+	*models.OrderRefund
+	// 已退款 + 处理中 + 本次
+	CumulativeMinor int64
+}
+
+// RequiresApproval 按订单累计退款额判断审批阈值，拆分成多笔小额退款同样需要审批
+func (q *OrderRefundQuote) RequiresApproval() bool {
+	return RefundRequiresApproval(q.CumulativeMinor)
+}
+
+// Quote 预估本次退款（不落库），用于审批阈值判断
+func (s *OrderRefundService) Quote(order *models.Order, input OrderRefundInput) (*OrderRefundQuote, error) {
+	if !OrderAcceptsRefund(order.Status) {
+		return nil, orderbiz.RefundStatusInvalid(order.Status)
+	}
+	summary, err := s.Summary(order)
+	if err != nil {
+		return nil, err
+	}
+	refund, err := buildOrderRefund(order, summary, input)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderRefundQuote{
+		OrderRefund:     refund,
+		CumulativeMinor: summary.RefundedMinor + summary.PendingMinor + refund.AmountMinor,
+	}, nil
+}
+
+func (s *OrderRefundService) loadPaymentMethod(orderID uint) (*models.PaymentMethod, error) {
+	var opm models.OrderPaymentMethod
+	if err := s.db.Where("order_id = ?", orderID).First(&opm).Error; err != nil {
+		return nil, orderbiz.OrderPaymentMethodNotFound()
+	}
+	var pm models.PaymentMethod
+	if err := s.db.First(&pm, opm.PaymentMethodID).Error; err != nil {
+		return nil, orderbiz.PaymentMethodNotFound()
+	}
+	return &pm, nil
+}
+
+// CreateRefund 执行一笔退款。先在订单行锁内占用额度，再调用付款方式脚本，最后按结果记账并推进订单状态；
+// 累计退款覆盖订单实付金额时订单转为已退款，未发货订单同时释放预留库存与优惠码
+func (s *OrderRefundService) CreateRefund(orderID uint, input OrderRefundInput) (*OrderRefundOutcome, error) {
+	pm, err := s.loadPaymentMethod(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if s.jsRuntime == nil && pm.Provider == "" {
+		return nil, errors.New("payment runtime unavailable")
+	}
+
+	var (
+		order  *models.Order
+		refund *models.OrderRefund
+	)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		lockedOrder, err := repository.NewOrderRepository(tx).FindByIDForUpdate(tx, orderID)
+		if err != nil {
+			return normalizeOrderLookupError(err)
+		}
+		if !OrderAcceptsRefund(lockedOrder.Status) {
+			return orderbiz.RefundStatusInvalid(lockedOrder.Status)
+		}
+		refunds, err := repository.NewRefundRepository(tx).ListByOrder(orderID)
+		if err != nil {
+			return err
+		}
+		refund, err = buildOrderRefund(lockedOrder, summarizeOrderRefunds(lockedOrder, refunds), input)
+		if err != nil {
+			return err
+		}
+		order = lockedOrder
+		return repository.NewRefundRepository(tx).Create(refund)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := &OrderRefundOutcome{Refund: refund, StatusBefore: order.Status, StatusAfter: order.Status}
+	result, execErr := s.executeRefund(pm, order, &RefundRequest{
+		RefundID:    refund.ID,
+		AmountMinor: refund.AmountMinor,
+		Full:        refund.Full,
+		Reason:      refund.Reason,
+		Items:       refund.Items,
+	})
+	outcome.Result = result
+	if execErr != nil || result == nil || !result.Success {
+		refund.Status = models.OrderRefundStatusFailed
+		switch {
+		case execErr != nil:
+			refund.Message = execErr.Error()
+		case result != nil:
+			refund.Message = result.Message
+		}
+		if saveErr := repository.NewRefundRepository(s.db).Save(refund); saveErr != nil {
+			return nil, saveErr
+		}
+		if execErr != nil {
+			return nil, execErr
+		}
+		if outcome.Result == nil {
+			outcome.Result = &RefundResult{}
+		}
+		return outcome, nil
+	}
+
+	refund.TransactionID = truncateRefundField(result.TransactionID, 255)
+	refund.Message = result.Message
+	refund.Status = models.OrderRefundStatusSucceeded
+	if result.Pending {
+		refund.Status = models.OrderRefundStatusPending
+	} else {
+		now := time.Now().UTC()
+		refund.CompletedAt = &now
+	}
+
+	nextStatus := order.Status
+	if refund.Full {
+		nextStatus = models.OrderStatusRefunded
+		if result.Pending {
+			nextStatus = models.OrderStatusRefundPending
+		}
+	}
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewRefundRepository(tx).Save(refund); err != nil {
+			return err
+		}
+		if nextStatus != order.Status {
+			if err := tx.Model(order).Update("status", nextStatus).Error; err != nil {
+				return err
+			}
+		}
+		if err := AddOrderNoteTx(tx, order.ID, input.AdminID, models.OrderNoteSourceRefund, orderRefundNoteContent(refund)); err != nil {
+			return err
+		}
+		// 待确认的退款在确认后记账
+		if refund.Status != models.OrderRefundStatusSucceeded {
+			return nil
+		}
+		return RecordOrderPartialRefundLedgerTx(tx, order, refund.AmountMinor, refund.Source, refund.Reason, input.AdminID)
+	}); err != nil {
+		return nil, err
+	}
+	outcome.StatusAfter = nextStatus
+
+	if refund.Full && orderRefundReleasesReserves(outcome.StatusBefore) && s.orderService != nil {
+		s.orderService.ReleaseOrderReserves(order)
+		outcome.ReleasedReserves = true
+	}
+	return outcome, nil
+}
+
+// executeRefund 原生网关直接调用其退款接口，其余付款方式交给脚本 onRefund
+func (s *OrderRefundService) executeRefund(pm *models.PaymentMethod, order *models.Order, request *RefundRequest) (*RefundResult, error) {
+	if pm.Provider == models.PaymentProviderStripe {
+		return executeStripeRefund(s.db, pm, order, request)
+	}
+	return s.jsRuntime.ExecuteRefundAmount(pm, order, request)
+}
+
+// ConfirmRefund 人工确认待确认的退款已到账；该订单最后一笔待确认退款完成后订单转为已退款
+func (s *OrderRefundService) ConfirmRefund(orderID, refundID uint, transactionID string, adminID *uint) (*OrderRefundOutcome, error) {
+	var outcome *OrderRefundOutcome
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		order, err := repository.NewOrderRepository(tx).FindByIDForUpdate(tx, orderID)
+		if err != nil {
+			return normalizeOrderLookupError(err)
+		}
+		refundRepo := repository.NewRefundRepository(tx)
+		refund, err := refundRepo.FindByID(orderID, refundID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrderRefundNotFound
+			}
+			return err
+		}
+		if refund.Status != models.OrderRefundStatusPending {
+			return newOrderRefundConfirmStatusInvalidError(refund.Status)
+		}
+		outcome = &OrderRefundOutcome{Refund: refund, StatusBefore: order.Status, StatusAfter: order.Status}
+		if err := confirmOrderRefundTx(tx, order, refund, transactionID, adminID); err != nil {
+			return err
+		}
+
+		remaining, err := refundRepo.ListByOrderAndStatus(orderID, models.OrderRefundStatusPending)
+		if err != nil {
+			return err
+		}
+		if order.Status == models.OrderStatusRefundPending && len(remaining) == 0 {
+			if err := tx.Model(order).Update("status", models.OrderStatusRefunded).Error; err != nil {
+				return err
+			}
+			outcome.StatusAfter = models.OrderStatusRefunded
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outcome, nil
+}
+
+func confirmOrderRefundTx(tx *gorm.DB, order *models.Order, refund *models.OrderRefund, transactionID string, adminID *uint) error {
+	now := time.Now().UTC()
+	refund.Status = models.OrderRefundStatusSucceeded
+	refund.CompletedAt = &now
+	if transactionID = strings.TrimSpace(transactionID); transactionID != "" {
+		refund.TransactionID = truncateRefundField(transactionID, 255)
+	}
+	if err := repository.NewRefundRepository(tx).Save(refund); err != nil {
+		return err
+	}
+	return RecordOrderPartialRefundLedgerTx(tx, order, refund.AmountMinor, refund.Source, refund.Reason, adminID)
+}
+
+// CompletePendingOrderRefundsTx 订单级确认退款时将待确认的退款记录一并标记完成（账本由调用方按剩余实收统一冲销）
+func CompletePendingOrderRefundsTx(tx *gorm.DB, orderID uint, transactionID string) error {
+	refunds, err := repository.NewRefundRepository(tx).ListByOrderAndStatus(orderID, models.OrderRefundStatusPending)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for i := range refunds {
+		refunds[i].Status = models.OrderRefundStatusSucceeded
+		refunds[i].CompletedAt = &now
+		if transactionID != "" {
+			refunds[i].TransactionID = truncateRefundField(transactionID, 255)
+		}
+		if err := tx.Save(&refunds[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func orderRefundNoteContent(refund *models.OrderRefund) string {
+	if refund.Full && len(refund.Items) == 0 {
+		return refund.Reason
+	}
+	label := "Partial refund"
+	if refund.Full {
+		label = "Refund"
+	}
+	lines := []string{fmt.Sprintf("%s %s %s", label, money.MinorToString(refund.AmountMinor), refund.Currency)}
+	for _, item := range refund.Items {
+		lines = append(lines, fmt.Sprintf("- %s x%d: %s", item.SKU, item.Quantity, money.MinorToString(item.AmountMinor)))
+	}
+	if refund.Reason != "" {
+		lines = append(lines, refund.Reason)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func truncateRefundField(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+func newOrderRefundTestService(t *testing.T, script string) (*OrderRefundService, *gorm.DB) {
+	t.Helper()
+	db := openConcurrentServiceTestDB(t,
+		&models.InventoryLog{},
+		&models.PromoCode{},
+		&models.PromoCodeRedemption{},
+		&models.Order{},
+		&models.OrderRefund{},
+		&models.OrderNote{},
+		&models.OrderPaymentMethod{},
+		&models.PaymentMethod{},
+		&models.PaymentMethodStorageEntry{},
+	)
+	cfg := &config.Config{}
+	orderService := NewOrderService(
+		repository.NewOrderRepository(db),
+		repository.NewUserRepository(db),
+		nil,
+		repository.NewInventoryRepository(db),
+		nil,
+		nil,
+		nil,
+		repository.NewPromoCodeRepository(db),
+		cfg,
+		nil,
+	)
+	paymentMethod := models.PaymentMethod{Name: "Refund Test", Type: models.PaymentMethodTypeCustom, Enabled: true, Script: script}
+	if err := db.Create(&paymentMethod).Error; err != nil {
+		t.Fatalf("create payment method: %v", err)
+	}
+	return NewOrderRefundService(db, orderService, NewJSRuntimeService(db, cfg)), db
+}
+
+func createRefundTestOrder(t *testing.T, db *gorm.DB, order *models.Order) {
+	t.Helper()
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if err := RecordOrderCreatedLedgerTx(db, order, true, "test"); err != nil {
+		t.Fatalf("record order ledger: %v", err)
+	}
+	var pm models.PaymentMethod
+	db.First(&pm)
+	if err := db.Create(&models.OrderPaymentMethod{OrderID: order.ID, PaymentMethodID: pm.ID}).Error; err != nil {
+		t.Fatalf("create order payment method: %v", err)
+	}
+}
+
+func refundErrorKey(err error) string {
+	if bizErr, ok := err.(*bizerr.Error); ok {
+		return bizErr.Key
+	}
+	return ""
+}
+
+func TestOrderRefundPartialThenFullReleasesReserves(t *testing.T) {
+	svc, db := newOrderRefundTestService(t, `
+function onRefund(order, config, refund) {
+  return { success: true, transaction_id: refund.full ? "RF-FULL" : "RF-PART" };
+}
+`)
+	inventory := models.Inventory{Name: "Tee", SKU: "TEE", Stock: 10, AvailableQuantity: 10, ReservedQuantity: 2, IsActive: true}
+	promo := models.PromoCode{Code: "SAVE", DiscountType: models.DiscountTypeFixed, TotalQuantity: 10, ReservedQuantity: 1, Status: models.PromoCodeStatusActive}
+	if err := db.Create(&inventory).Error; err != nil {
+		t.Fatalf("create inventory: %v", err)
+	}
+	if err := db.Create(&promo).Error; err != nil {
+		t.Fatalf("create promo code: %v", err)
+	}
+	order := models.Order{
+		OrderNo: "ORD-REFUND-1",
+		Status:  models.OrderStatusPending,
+		Items: []models.OrderItem{
+			{SKU: "TEE", Name: "Tee", Quantity: 2, LineTotalMinor: 2000},
+			{SKU: "MUG", Name: "Mug", Quantity: 1, LineTotalMinor: 1000},
+		},
+		InventoryBindings: map[int]uint{0: inventory.ID},
+		PromoCodeID:       &promo.ID,
+		TotalAmount:       3000,
+		Currency:          "USD",
+	}
+	createRefundTestOrder(t, db, &order)
+
+	partial, err := svc.CreateRefund(order.ID, OrderRefundInput{
+		Items: []models.OrderRefundItem{{ItemIndex: 0, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("partial refund: %v", err)
+	}
+	if partial.Refund.AmountMinor != 1000 || partial.Refund.Full || partial.StatusAfter != models.OrderStatusPending ||
+		partial.Refund.Status != models.OrderRefundStatusSucceeded || partial.Refund.TransactionID != "RF-PART" {
+		t.Fatalf("unexpected partial refund outcome: %+v, refund %+v", partial, partial.Refund)
+	}
+
+	if _, err := svc.CreateRefund(order.ID, OrderRefundInput{
+		Items: []models.OrderRefundItem{{ItemIndex: 0, Quantity: 2}},
+	}); refundErrorKey(err) != "order.refundItemExceeded" {
+		t.Fatalf("refunding more items than bought should fail, got %v", err)
+	}
+	if _, err := svc.CreateRefund(order.ID, OrderRefundInput{AmountMinor: 2500}); refundErrorKey(err) != "order.refundAmountInvalid" {
+		t.Fatalf("refunding more than the remainder should fail, got %v", err)
+	}
+
+	full, err := svc.CreateRefund(order.ID, OrderRefundInput{Reason: "cancelled"})
+	if err != nil {
+		t.Fatalf("refund remainder: %v", err)
+	}
+	if full.Refund.AmountMinor != 2000 || !full.Refund.Full || full.Refund.TransactionID != "RF-FULL" || full.StatusAfter != models.OrderStatusRefunded || !full.ReleasedReserves {
+		t.Fatalf("unexpected full refund outcome: %+v, refund %+v", full, full.Refund)
+	}
+
+	db.First(&inventory, inventory.ID)
+	db.First(&promo, promo.ID)
+	if inventory.ReservedQuantity != 0 || promo.ReservedQuantity != 0 {
+		t.Fatalf("full refund of an unshipped order must release reserves, got inventory=%d promo=%d", inventory.ReservedQuantity, promo.ReservedQuantity)
+	}
+	var updated models.Order
+	db.First(&updated, order.ID)
+	summary, err := svc.Summary(&updated)
+	if err != nil || summary.RefundedMinor != 3000 || summary.RefundableMinor != 0 || len(summary.Refunds) != 2 || summary.RefundedQuantities[0] != 1 {
+		t.Fatalf("unexpected refund summary: %+v, %v", summary, err)
+	}
+	financial, err := NewLedgerService(db).OrderSummary(&updated)
+	if err != nil || financial.RefundedMinor != 3000 {
+		t.Fatalf("ledger must record both refunds: %+v, %v", financial, err)
+	}
+	if _, err := svc.CreateRefund(order.ID, OrderRefundInput{AmountMinor: 1}); refundErrorKey(err) != "order.refundStatusInvalid" {
+		t.Fatalf("refunded order should not accept more refunds, got %v", err)
+	}
+}
+
+func TestOrderRefundPendingUntilConfirmed(t *testing.T) {
+	svc, db := newOrderRefundTestService(t, `
+function onRefund(order, config, refund) {
+  return { success: true, manual_required: true, message: "send manually" };
+}
+`)
+	order := models.Order{
+		OrderNo:     "ORD-REFUND-2",
+		Status:      models.OrderStatusShipped,
+		Items:       []models.OrderItem{{SKU: "TEE", Name: "Tee", Quantity: 1, LineTotalMinor: 1500}},
+		TotalAmount: 1500,
+		Currency:    "USD",
+	}
+	createRefundTestOrder(t, db, &order)
+
+	partial, err := svc.CreateRefund(order.ID, OrderRefundInput{AmountMinor: 500})
+	if err != nil || partial.Refund.Status != models.OrderRefundStatusPending || partial.StatusAfter != models.OrderStatusShipped {
+		t.Fatalf("manual refund should stay pending: %+v, %v", partial, err)
+	}
+	rest, err := svc.CreateRefund(order.ID, OrderRefundInput{})
+	if err != nil || rest.Refund.AmountMinor != 1000 || rest.StatusAfter != models.OrderStatusRefundPending || rest.ReleasedReserves {
+		t.Fatalf("pending full refund should mark the order refund_pending: %+v, %v", rest, err)
+	}
+
+	if _, err := svc.ConfirmRefund(order.ID, partial.Refund.ID, "TX-1", nil); err != nil {
+		t.Fatalf("confirm first refund: %v", err)
+	}
+	if _, err := svc.ConfirmRefund(order.ID, partial.Refund.ID, "", nil); refundErrorKey(err) != "order.refundConfirmStatusInvalid" {
+		t.Fatalf("confirming twice should fail, got %v", err)
+	}
+	confirmed, err := svc.ConfirmRefund(order.ID, rest.Refund.ID, "TX-2", nil)
+	if err != nil || confirmed.StatusAfter != models.OrderStatusRefunded {
+		t.Fatalf("confirming the last pending refund should finish the order: %+v, %v", confirmed, err)
+	}
+	var updated models.Order
+	db.First(&updated, order.ID)
+	financial, err := NewLedgerService(db).OrderSummary(&updated)
+	if err != nil || financial.RefundedMinor != 1500 {
+		t.Fatalf("confirmed refunds must be booked: %+v, %v", financial, err)
+	}
+}
+
+func TestOrderRefundApprovalCountsPriorRefunds(t *testing.T) {
+	enableApprovalForTest(t, config.ApprovalConfig{Enabled: true, RefundAmountThreshold: 2000, ExpireMinutes: 60})
+	svc, db := newOrderRefundTestService(t, `
+function onRefund(order, config, refund) {
+  return { success: true, transaction_id: "RF-SPLIT" };
+}
+`)
+	order := models.Order{
+		OrderNo:     "ORD-REFUND-SPLIT",
+		Status:      models.OrderStatusPending,
+		Items:       []models.OrderItem{{SKU: "TEE", Name: "Tee", Quantity: 3, LineTotalMinor: 3000}},
+		TotalAmount: 3000,
+		Currency:    "USD",
+	}
+	createRefundTestOrder(t, db, &order)
+
+	first, err := svc.Quote(&order, OrderRefundInput{AmountMinor: 1500})
+	if err != nil {
+		t.Fatalf("quote first refund: %v", err)
+	}
+	if first.RequiresApproval() {
+		t.Fatalf("first refund below threshold should not need approval: %+v", first)
+	}
+	if _, err := svc.CreateRefund(order.ID, OrderRefundInput{AmountMinor: 1500}); err != nil {
+		t.Fatalf("first refund: %v", err)
+	}
+
+	// 第二笔本身低于阈值，但与已退款合计超过阈值
+	second, err := svc.Quote(&order, OrderRefundInput{AmountMinor: 1000})
+	if err != nil {
+		t.Fatalf("quote second refund: %v", err)
+	}
+	if second.AmountMinor >= 2000 || second.CumulativeMinor != 2500 || !second.RequiresApproval() {
+		t.Fatalf("split refunds exceeding the threshold must need approval, got amount=%d cumulative=%d", second.AmountMinor, second.CumulativeMinor)
+	}
+}
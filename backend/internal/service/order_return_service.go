@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/dbutil"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+// OrderReturnItem 退货商品行
+type OrderReturnItem struct {
+	ItemIndex int `json:"item_index"`
+	Quantity  int `json:"quantity"`
+}
+
+// OrderReturnInput 登记退货：可选回补实物库存、作废已发放序列号
+type OrderReturnInput struct {
+	Items             []OrderReturnItem
+	Restock           bool
+	InvalidateSerials bool
+	Reason            string
+	Operator          string
+	OperatorID        *uint
+}
+
+// OrderReturnResult 退货处理结果
+type OrderReturnResult struct {
+	ReturnedQuantities map[int]int `json:"returned_quantities"`
+	RestockedQuantity  int         `json:"restocked_quantity"`
+	InvalidatedSerials []string    `json:"invalidated_serials"`
+}
+
+// 已发货（库存已扣减）后才可登记退货
+func orderAcceptsReturns(order *models.Order) bool {
+	if order.ShippedAt == nil {
+		return false
+	}
+	switch order.Status {
+	case models.OrderStatusShipped, models.OrderStatusCompleted, models.OrderStatusRefundPending, models.OrderStatusRefunded:
+		return true
+	}
+	return false
+}
+
+// ReceiveReturn 登记退货商品。已售库存按退货数量回补（独立的 return 变动类型），
+// 序列号按出厂序号顺序作废；同一订单项累计退货数量不能超过购买数量
+func (s *OrderService) ReceiveReturn(orderID uint, input OrderReturnInput) (*OrderReturnResult, error) {
+	order, err := s.OrderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, normalizeOrderLookupError(err)
+	}
+	if !orderAcceptsReturns(order) {
+		return nil, bizerr.Newf("order.returnStatusInvalid", "Order status %s does not accept returns", order.Status).
+			WithParams(map[string]interface{}{"status": order.Status})
+	}
+	if len(input.Items) == 0 {
+		return nil, bizerr.New("order.returnItemsRequired", "Select at least one item to return")
+	}
+
+	requested := make(map[int]int, len(input.Items))
+	for _, item := range input.Items {
+		if item.ItemIndex < 0 || item.ItemIndex >= len(order.Items) || item.Quantity <= 0 ||
+			order.Items[item.ItemIndex].ProductType == models.ProductTypeVirtual {
+			return nil, bizerr.New("order.returnItemInvalid", "Invalid return item")
+		}
+		requested[item.ItemIndex] += item.Quantity
+	}
+	indexes := make([]int, 0, len(requested))
+	for idx := range requested {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	operator := input.Operator
+	if operator == "" {
+		operator = "system"
+	}
+	reason := "Restock returned items"
+	if strings.TrimSpace(input.Reason) != "" {
+		reason = "Restock returned items: " + strings.TrimSpace(input.Reason)
+	}
+	result := &OrderReturnResult{InvalidatedSerials: []string{}}
+	noteLines := make([]string, 0, len(indexes)+1)
+
+	returned := make(map[int]int)
+	err = s.OrderRepo.WithTransaction(func(tx *gorm.DB) error {
+		// 锁定订单后重新读取已退货数量，避免并发登记重复回补
+		if err := dbutil.LockForUpdate(tx, &models.Order{}, "id = ?", order.ID); err != nil {
+			return err
+		}
+		var current models.Order
+		if err := tx.Select("id", "returned_quantities").First(&current, order.ID).Error; err != nil {
+			return err
+		}
+		for idx, qty := range current.ReturnedQuantities {
+			returned[idx] = qty
+		}
+		for _, idx := range indexes {
+			orderItem := order.Items[idx]
+			if returned[idx]+requested[idx] > orderItem.Quantity {
+				return bizerr.Newf("order.returnQuantityExceeded", "Return quantity for %s exceeds the purchased quantity", orderItem.SKU).
+					WithParams(map[string]interface{}{"sku": orderItem.SKU, "remaining": orderItem.Quantity - returned[idx]})
+			}
+		}
+
+		inventoryRepo := repository.NewInventoryRepository(tx)
+		for _, idx := range indexes {
+			qty := requested[idx]
+			orderItem := order.Items[idx]
+			returned[idx] += qty
+			line := fmt.Sprintf("Returned %s × %d", orderItem.SKU, qty)
+
+			if inventoryID, ok := order.InventoryBindings[idx]; ok && inventoryID > 0 && input.Restock {
+				if err := inventoryRepo.Restock(inventoryID, qty, order.OrderNo, operator, reason); err != nil {
+					return err
+				}
+				result.RestockedQuantity += qty
+				line += " (restocked)"
+			}
+			if input.InvalidateSerials {
+				serials, err := invalidateOrderItemSerialsTx(tx, order.ID, orderItem.SKU, qty, input.Reason)
+				if err != nil {
+					return err
+				}
+				result.InvalidatedSerials = append(result.InvalidatedSerials, serials...)
+			}
+			noteLines = append(noteLines, line)
+		}
+		if len(result.InvalidatedSerials) > 0 {
+			noteLines = append(noteLines, "Invalidated serials: "+strings.Join(result.InvalidatedSerials, ", "))
+		}
+		if strings.TrimSpace(input.Reason) != "" {
+			noteLines = append(noteLines, strings.TrimSpace(input.Reason))
+		}
+
+		if err := tx.Model(&current).Select("returned_quantities").Updates(&models.Order{ReturnedQuantities: returned}).Error; err != nil {
+			return err
+		}
+		return AddOrderNoteTx(tx, order.ID, input.OperatorID, models.OrderNoteSourceReturn, strings.Join(noteLines, "\n"))
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.ReturnedQuantities = returned
+	return result, nil
+}
+
+// invalidateOrderItemSerialsTx 按出厂序号顺序作废订单中该商品尚未作废的序列号
+func invalidateOrderItemSerialsTx(tx *gorm.DB, orderID uint, sku string, quantity int, reason string) ([]string, error) {
+	var product models.Product
+	if err := tx.Unscoped().Select("id").Where("sku = ?", sku).Order("deleted_at IS NOT NULL, id DESC").First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var serials []models.ProductSerial
+	if err := tx.Where("order_id = ? AND product_id = ? AND invalidated_at IS NULL", orderID, product.ID).
+		Order("sequence_number ASC").Limit(quantity).Find(&serials).Error; err != nil {
+		return nil, err
+	}
+	if len(serials) == 0 {
+		return nil, nil
+	}
+	ids := make([]uint, 0, len(serials))
+	numbers := make([]string, 0, len(serials))
+	for _, serial := range serials {
+		ids = append(ids, serial.ID)
+		numbers = append(numbers, serial.SerialNumber)
+	}
+	if reason = strings.TrimSpace(reason); reason == "" {
+		reason = "returned"
+	}
+	if err := tx.Model(&models.ProductSerial{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"invalidated_at": models.NowFunc(),
+		"invalid_reason": reason,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return numbers, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+)
+
+func TestReceiveReturnRestocksAndInvalidatesSerials(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.Product{}, &models.ProductSerial{}, &models.InventoryLog{}, &models.OrderNote{})
+	svc := newConcurrentOrderService(db, &config.Config{}, nil)
+
+	product := &models.Product{SKU: "WATCH-1", Name: "Watch"}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("create product failed: %v", err)
+	}
+	// 发货后状态：已售 3，库存已扣减
+	inventory := &models.Inventory{Name: "Watch", Stock: 7, AvailableQuantity: 7, SoldQuantity: 3, IsActive: true}
+	if err := db.Create(inventory).Error; err != nil {
+		t.Fatalf("create inventory failed: %v", err)
+	}
+	shippedAt := time.Now()
+	order := &models.Order{
+		OrderNo:           "RET-1",
+		Status:            models.OrderStatusShipped,
+		ShippedAt:         &shippedAt,
+		Items:             []models.OrderItem{{SKU: "WATCH-1", Name: "Watch", Quantity: 3}},
+		InventoryBindings: map[int]uint{0: inventory.ID},
+	}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+	for seq := 1; seq <= 3; seq++ {
+		serial := &models.ProductSerial{SerialNumber: fmt.Sprintf("SN%03d", seq), ProductID: product.ID, OrderID: order.ID, ProductCode: "W", SequenceNumber: seq, AntiCounterfeitCode: "ABCD"}
+		if err := db.Create(serial).Error; err != nil {
+			t.Fatalf("create serial failed: %v", err)
+		}
+	}
+
+	result, err := svc.ReceiveReturn(order.ID, OrderReturnInput{
+		Items:             []OrderReturnItem{{ItemIndex: 0, Quantity: 2}},
+		Restock:           true,
+		InvalidateSerials: true,
+		Operator:          "admin@example.com",
+	})
+	if err != nil {
+		t.Fatalf("receive return failed: %v", err)
+	}
+	if result.RestockedQuantity != 2 || len(result.InvalidatedSerials) != 2 || result.InvalidatedSerials[0] != "SN001" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	var reloaded models.Inventory
+	db.First(&reloaded, inventory.ID)
+	if reloaded.Stock != 9 || reloaded.AvailableQuantity != 9 || reloaded.SoldQuantity != 1 {
+		t.Fatalf("unexpected inventory after restock: %+v", reloaded)
+	}
+	var returnLogs int64
+	db.Model(&models.InventoryLog{}).Where("type = ? AND order_no = ?", models.InventoryLogTypeReturn, "RET-1").Count(&returnLogs)
+	if returnLogs != 1 {
+		t.Fatalf("expected one return movement, got %d", returnLogs)
+	}
+
+	_, err = svc.ReceiveReturn(order.ID, OrderReturnInput{Items: []OrderReturnItem{{ItemIndex: 0, Quantity: 2}}, Restock: true})
+	var bizErr *bizerr.Error
+	if !errors.As(err, &bizErr) || bizErr.Key != "order.returnQuantityExceeded" {
+		t.Fatalf("expected returnQuantityExceeded, got %v", err)
+	}
+
+	pending := &models.Order{OrderNo: "RET-2", Status: models.OrderStatusPending, Items: []models.OrderItem{{SKU: "WATCH-1", Quantity: 1}}}
+	if err := db.Create(pending).Error; err != nil {
+		t.Fatalf("create pending order failed: %v", err)
+	}
+	if _, err := svc.ReceiveReturn(pending.ID, OrderReturnInput{Items: []OrderReturnItem{{ItemIndex: 0, Quantity: 1}}}); !errors.As(err, &bizErr) || bizErr.Key != "order.returnStatusInvalid" {
+		t.Fatalf("expected returnStatusInvalid for unshipped order, got %v", err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"auralogic/internal/config"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/listfilter"
 	"auralogic/internal/pkg/password"
 	"auralogic/internal/pkg/utils"
 	"auralogic/internal/repository"
@@ -30,6 +31,9 @@ type OrderService struct {
 	serialTaskService *SerialGenerationService
 	virtualProductSvc *VirtualInventoryService
 	promoCodeRepo     *repository.PromoCodeRepository
+	giftPromotionSvc  *GiftPromotionService
+	cartReservation   *CartReservationService
+	exchangeRates     *ExchangeRateService
 	cfg               *config.Config
 	emailService      *EmailService
 	pluginManager     *PluginManagerService
@@ -39,6 +43,8 @@ type OrderService struct {
 type MarkAsPaidOptions struct {
 	AdminRemark      string
 	SkipAutoDelivery bool
+	OperatorID       *uint  // 备注作者，为空表示系统/插件操作
+	PaymentSource    string // 记账来源，默认 mark_paid
 }
 
 const (
@@ -237,6 +243,24 @@ func (s *OrderService) SetPluginManager(pluginManager *PluginManagerService) {
 	s.pluginManager = pluginManager
 }
 
+// SetGiftPromotionService 注入满赠活动服务，未注入时下单不附赠赠品
+func (s *OrderService) SetGiftPromotionService(giftPromotionSvc *GiftPromotionService) {
+	s.giftPromotionSvc = giftPromotionSvc
+}
+
+// SetCartReservationService 注入购物车预留服务：下单时扣除他人的购物车预留，成功后释放本人的预留
+func (s *OrderService) SetCartReservationService(cartReservation *CartReservationService) {
+	s.cartReservation = cartReservation
+}
+
+// cartReservedByOthers 其他用户对该商品规格的有效购物车预留数量（仅加购即预留的商品）
+func (s *OrderService) cartReservedByOthers(product *models.Product, userID uint, attributes map[string]string) int {
+	if s.cartReservation == nil || product == nil || !product.ReserveOnCart {
+		return 0
+	}
+	return s.cartReservation.ReservedByOthers(product.ID, models.GenerateAttributesHash(attributes), userID)
+}
+
 func (s *OrderService) SetSerialGenerationService(serialTaskService *SerialGenerationService) {
 	s.serialTaskService = serialTaskService
 }
@@ -343,6 +367,16 @@ func applyInventoryReserveHookPayload(inventoryID uint, payload map[string]inter
 }
 
 func (s *OrderService) reserveInventoryWithHook(orderID *uint, userID *uint, orderNo string, inventoryID uint, quantity int, source string) (uint, error) {
+	return s.reserveInventoryWithHookTx(nil, nil, orderID, userID, orderNo, inventoryID, quantity, source)
+}
+
+// reserveInventoryWithHookTx 在给定事务内预留库存（行锁随事务持有至提交）
+// afterCommit 非空时 after 钩子延后到事务提交后再派发，避免回滚的预留被插件误认为成功
+func (s *OrderService) reserveInventoryWithHookTx(tx *gorm.DB, afterCommit *[]func(), orderID *uint, userID *uint, orderNo string, inventoryID uint, quantity int, source string) (uint, error) {
+	inventoryRepo := s.inventoryRepo
+	if tx != nil {
+		inventoryRepo = repository.NewInventoryRepository(tx)
+	}
 	execCtx := s.buildInventoryHookExecutionContext(orderID, userID, source, orderNo)
 	reservedInventoryID := inventoryID
 	if s.pluginManager != nil {
@@ -379,7 +413,7 @@ func (s *OrderService) reserveInventoryWithHook(orderID *uint, userID *uint, ord
 		}
 	}
 
-	reserveErr := s.inventoryRepo.Reserve(reservedInventoryID, quantity, orderNo)
+	reserveErr := inventoryRepo.Reserve(reservedInventoryID, quantity, orderNo)
 	if s.pluginManager != nil {
 		afterPayload := map[string]interface{}{
 			"order_id":     orderID,
@@ -393,15 +427,24 @@ func (s *OrderService) reserveInventoryWithHook(orderID *uint, userID *uint, ord
 		if reserveErr != nil {
 			afterPayload["error"] = reserveErr.Error()
 		}
-		go func(execCtx *ExecutionContext, payload map[string]interface{}) {
-			_, hookErr := s.pluginManager.ExecuteHook(HookExecutionRequest{
-				Hook:    "inventory.reserve.after",
-				Payload: payload,
-			}, execCtx)
-			if hookErr != nil {
-				log.Printf("inventory.reserve.after hook execution failed: order_no=%s inventory=%d err=%v", orderNo, reservedInventoryID, hookErr)
+		dispatch := func(execCtx *ExecutionContext, payload map[string]interface{}) func() {
+			return func() {
+				go func() {
+					_, hookErr := s.pluginManager.ExecuteHook(HookExecutionRequest{
+						Hook:    "inventory.reserve.after",
+						Payload: payload,
+					}, execCtx)
+					if hookErr != nil {
+						log.Printf("inventory.reserve.after hook execution failed: order_no=%s inventory=%d err=%v", orderNo, reservedInventoryID, hookErr)
+					}
+				}()
 			}
 		}(cloneOrderHookExecutionContext(execCtx), afterPayload)
+		if afterCommit != nil {
+			*afterCommit = append(*afterCommit, dispatch)
+		} else {
+			dispatch()
+		}
 	}
 
 	if reserveErr != nil {
@@ -482,7 +525,30 @@ func (s *OrderService) lockUserOrderCreation(userID uint) func() {
 }
 
 // CreateDraft CreateOrder草稿
-func (s *OrderService) CreateDraft(items []models.OrderItem, externalUserID, externalOrderID, platform, userEmail, userName, remark string) (*models.Order, error) {
+// 同一平台重复提交同一外部订单号时不再建单，返回已存在的订单且 duplicate 为 true；
+// apiKeyID 为发起请求的 API Key，按该 Key 的设置决定是否允许重复建单
+func (s *OrderService) CreateDraft(apiKeyID uint, items []models.OrderItem, externalUserID, externalOrderID, platform, userEmail, userName, remark string, tags []string) (*models.Order, bool, error) {
+	tags, err := NormalizeOrderTags(tags)
+	if err != nil {
+		return nil, false, err
+	}
+
+	dedup := false
+	if externalOrderID != "" {
+		if dedup, err = s.OrderRepo.ExternalOrderDedupEnabled(apiKeyID); err != nil {
+			return nil, false, err
+		}
+	}
+	if dedup {
+		existing, err := s.OrderRepo.FindByExternalOrderID(platform, externalOrderID)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing != nil {
+			return existing, true, nil
+		}
+	}
+
 	// generateOrder号
 	orderNo := utils.GenerateOrderNo(s.cfg.Order.NoPrefix)
 
@@ -492,27 +558,32 @@ func (s *OrderService) CreateDraft(items []models.OrderItem, externalUserID, ext
 
 	// 校验订单商品项
 	if err := s.validateOrderItems(items); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	productBySKU, err := s.loadProductsForOrderItems(items)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	// 计算订单总金额
-	var totalAmount int64
-	for _, item := range items {
+	// 校验订单商品
+	for i := range items {
+		item := &items[i]
 		product, exists := productBySKU[item.SKU]
 		if !exists || product == nil {
-			return nil, bizerr.Newf("order.productNotFound", "Product %s does not exist", item.SKU).
+			return nil, false, bizerr.Newf("order.productNotFound", "Product %s does not exist", item.SKU).
 				WithParams(map[string]interface{}{"sku": item.SKU})
 		}
 		if product.Status != models.ProductStatusActive {
-			return nil, ErrProductNotAvailable
+			return nil, false, ErrProductNotAvailable
 		}
-		totalAmount += product.Price * int64(item.Quantity)
 	}
+	if err := validateOrderItemQuantityRules(items, productBySKU); err != nil {
+		return nil, false, err
+	}
+	// 快照下单时单价（含阶梯价），后续调价不影响历史订单
+	totalAmount, _ := applyTierPricing(items, productBySKU)
+	allocateOrderItemTotals(items, totalAmount)
 
 	// 获取货币单位
 	currency := s.cfg.Order.Currency
@@ -537,13 +608,40 @@ func (s *OrderService) CreateDraft(items []models.OrderItem, externalUserID, ext
 		UserEmail:                 userEmail,
 		EmailNotificationsEnabled: true,
 		Remark:                    remark,
+		Tags:                      tags,
 	}
 
-	if err := s.OrderRepo.Create(order); err != nil {
-		return nil, err
+	// 第三方平台订单已在外部完成付款
+	if err := s.OrderRepo.WithTransaction(func(tx *gorm.DB) error {
+		weight, err := ResolveOrderWeightGrams(tx, order)
+		if err != nil {
+			return err
+		}
+		order.TotalWeightGrams = weight
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		if dedup {
+			// 唯一索引兜底并发提交：后到的请求回滚并返回先创建的订单
+			if err := tx.Create(&models.OrderExternalRef{
+				SourcePlatform:  platform,
+				ExternalOrderID: externalOrderID,
+				OrderID:         order.ID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return RecordOrderCreatedLedgerTx(tx, order, true, "api")
+	}); err != nil {
+		if dedup && isUniqueConstraintError(err) {
+			if existing, findErr := s.OrderRepo.FindByExternalOrderID(platform, externalOrderID); findErr == nil && existing != nil {
+				return existing, true, nil
+			}
+		}
+		return nil, false, err
 	}
 
-	return order, nil
+	return order, false, nil
 }
 
 // AdminOrderRequest 管理员创建订单请求
@@ -561,10 +659,16 @@ type AdminOrderRequest struct {
 	ReceiverAddress  string
 	ReceiverPostcode string
 	Remark           string
-	AdminRemark      string
+	AdminRemark      string // 写入订单内部备注
 	Status           string
 	TotalAmount      *int64
 	UserEmail        string
+	CreatedBy        *uint
+	// 批量导入时记录来源与原平台订单号，并可关闭客户邮件通知
+	Source                    string
+	ExternalOrderID           string
+	DisableEmailNotifications bool
+	Tags                      []string
 }
 
 // AdminOrderItem 管理员订单商品项
@@ -592,6 +696,10 @@ func (s *OrderService) CreateAdminOrder(req AdminOrderRequest) (*models.Order, e
 	if req.TotalAmount != nil && *req.TotalAmount < 0 {
 		return nil, newOrderTotalAmountNegativeError()
 	}
+	tags, err := NormalizeOrderTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
 
 	// 验证用户
 	if req.UserID != nil {
@@ -655,12 +763,13 @@ func (s *OrderService) CreateAdminOrder(req AdminOrderRequest) (*models.Order, e
 		}
 
 		orderItems = append(orderItems, models.OrderItem{
-			SKU:         sku,
-			Name:        name,
-			Quantity:    item.Quantity,
-			Attributes:  item.Attributes,
-			ProductType: productType,
-			ImageURL:    imageURL,
+			SKU:            sku,
+			Name:           name,
+			Quantity:       item.Quantity,
+			Attributes:     item.Attributes,
+			ProductType:    productType,
+			ImageURL:       imageURL,
+			UnitPriceMinor: item.UnitPrice,
 		})
 		totalAmount += item.UnitPrice * int64(item.Quantity)
 		// 保存管理员指定的虚拟库存ID
@@ -673,6 +782,7 @@ func (s *OrderService) CreateAdminOrder(req AdminOrderRequest) (*models.Order, e
 	if req.TotalAmount != nil {
 		totalAmount = *req.TotalAmount
 	}
+	allocateOrderItemTotals(orderItems, totalAmount)
 
 	// 获取货币单位
 	currency := s.cfg.Order.Currency
@@ -789,6 +899,11 @@ func (s *OrderService) CreateAdminOrder(req AdminOrderRequest) (*models.Order, e
 		formExpiresAt = &expires
 	}
 
+	source := req.Source
+	if source == "" {
+		source = "admin"
+	}
+
 	order := &models.Order{
 		OrderNo:                   orderNo,
 		UserID:                    req.UserID,
@@ -799,7 +914,8 @@ func (s *OrderService) CreateAdminOrder(req AdminOrderRequest) (*models.Order, e
 		Currency:                  currency,
 		FormToken:                 formToken,
 		FormExpiresAt:             formExpiresAt,
-		Source:                    "admin",
+		Source:                    source,
+		ExternalOrderID:           req.ExternalOrderID,
 		ReceiverName:              req.ReceiverName,
 		PhoneCode:                 req.PhoneCode,
 		ReceiverPhone:             req.ReceiverPhone,
@@ -811,12 +927,35 @@ func (s *OrderService) CreateAdminOrder(req AdminOrderRequest) (*models.Order, e
 		ReceiverAddress:           req.ReceiverAddress,
 		ReceiverPostcode:          req.ReceiverPostcode,
 		UserEmail:                 req.UserEmail,
-		EmailNotificationsEnabled: req.UserEmail != "",
+		EmailNotificationsEnabled: req.UserEmail != "" && !req.DisableEmailNotifications,
 		Remark:                    req.Remark,
-		AdminRemark:               req.AdminRemark,
+		Tags:                      tags,
 	}
 
-	if err := s.OrderRepo.Create(order); err != nil {
+	if err := s.OrderRepo.WithTransaction(func(tx *gorm.DB) error {
+		weight, err := ResolveOrderWeightGrams(tx, order)
+		if err != nil {
+			return err
+		}
+		order.TotalWeightGrams = weight
+		if err := assignOrderPaymentDeadlineTx(tx, s.cfg, order); err != nil {
+			return err
+		}
+		notificationsEnabled := order.EmailNotificationsEnabled
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		// 列默认值为 true，GORM 插入时会忽略 false，需显式更新
+		if !notificationsEnabled {
+			if err := tx.Model(order).Update("email_notifications_enabled", false).Error; err != nil {
+				return err
+			}
+		}
+		if err := AddOrderNoteTx(tx, order.ID, req.CreatedBy, models.OrderNoteSourceCreate, req.AdminRemark); err != nil {
+			return err
+		}
+		return RecordOrderCreatedLedgerTx(tx, order, order.Status != models.OrderStatusPendingPayment, "admin")
+	}); err != nil {
 		// 释放已预留的物理库存
 		for i, inventoryID := range inventoryBindings {
 			_ = s.releaseReservedInventoryWithHook(nil, req.UserID, orderNo, inventoryID, orderItems[i].Quantity, "admin_create_order_rollback")
@@ -910,6 +1049,11 @@ func (s *OrderService) CreateAdminOrder(req AdminOrderRequest) (*models.Order, e
 
 // CreateUserOrder User直接CreateOrder（无需表单流程）
 func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, remark string, promoCode string) (*models.Order, error) {
+	return s.CreateUserOrderInStore(userID, nil, items, remark, promoCode)
+}
+
+// CreateUserOrderInStore 在指定店铺下创建User订单，storeID 为空时不校验商品所属店铺
+func (s *OrderService) CreateUserOrderInStore(userID uint, storeID *uint, items []models.OrderItem, remark string, promoCode string) (*models.Order, error) {
 	releaseHotPath, err := acquireOrderHighConcurrencyProtection(s.cfg, orderHotPathCreateUserOrder)
 	if err != nil {
 		if isOrderHighConcurrencyBusyError(err) {
@@ -935,6 +1079,9 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 		return nil, err
 	}
 
+	// 赠品只能由服务端按满赠活动添加，忽略客户端提交的赠品项
+	items = dropClientGiftItems(items)
+
 	// 校验订单商品项
 	if err := s.validateOrderItems(items); err != nil {
 		return nil, err
@@ -948,7 +1095,31 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 	// 盲盒属性跟踪：记录每个订单项中盲盒随机分配的属性名
 	// key: 订单项索引, value: 盲盒属性名列表
 	blindBoxAttrNames := make(map[int][]string)
+
+	// 盲盒分配会锁定多个库存行，抢购时限制全局并发以降低锁竞争；槽位持有到库存预留完成
+	var releaseBlindBox func()
+	defer func() {
+		if releaseBlindBox != nil {
+			releaseBlindBox()
+		}
+	}()
+	acquireBlindBox := func() error {
+		if releaseBlindBox != nil {
+			return nil
+		}
+		release, err := acquireBlindBoxAllocationGate(s.cfg)
+		if err != nil {
+			if isOrderHighConcurrencyBusyError(err) {
+				return newOrderHighConcurrencyBusyError()
+			}
+			return err
+		}
+		releaseBlindBox = release
+		return nil
+	}
 	saleCountAdjustments := make(map[uint]int)
+	// 下单成功后需释放的购物车预留（商品ID -> 规格哈希）
+	cartReservationHashes := make(map[uint][]string)
 
 	// 购买限制：同一SKU可能以多条订单项出现（不同属性/规格）
 	// 需要累计本次订单中该SKU的总数量，避免“拆成多行”绕过限购。
@@ -962,10 +1133,20 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 		if product.Status != models.ProductStatusActive {
 			return nil, ErrProductNotAvailable
 		}
+		if storeID != nil && !models.BelongsToStore(product.StoreID, *storeID) {
+			return nil, ErrProductNotAvailable
+		}
+		// 询价商品只能走询价单，不能按目录价直接下单
+		if product.PriceHiddenFor(user.CustomerTier) {
+			return nil, newProductPriceOnRequestError(product.Name)
+		}
 		if product.MaxPurchaseLimit > 0 {
 			requestedQtyBySKU[item.SKU] += item.Quantity
 		}
 	}
+	if err := validateOrderItemQuantityRules(items, productBySKU); err != nil {
+		return nil, err
+	}
 
 	purchasedQtyBySKU, err := s.OrderRepo.GetUserPurchaseQuantityBySKUs(userID, collectRequestedSKUs(requestedQtyBySKU))
 	if err != nil {
@@ -1043,6 +1224,9 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 			// 根据盲盒模式处理虚拟商品
 			if s.virtualProductSvc != nil {
 				if hasBlindBox {
+					if err := acquireBlindBox(); err != nil {
+						return nil, err
+					}
 					// 盲盒模式 或 混合模式
 					if hasUserSelect && len(attrStrMap) > 0 {
 						// 混合模式：部分属性用户选择，部分属性盲盒随机
@@ -1080,6 +1264,7 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 					if err != nil {
 						return nil, fmt.Errorf("Failed to check virtual product stock: %v", err)
 					}
+					availableCount -= int64(s.cartReservedByOthers(product, userID, attrStrMap))
 					if availableCount < int64(item.Quantity) {
 						return nil, bizerr.Newf("order.stockInsufficient",
 							"Virtual product %s stock insufficient, only %d available", product.Name, availableCount).
@@ -1088,6 +1273,9 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 				}
 			}
 
+			if product.ReserveOnCart {
+				cartReservationHashes[product.ID] = append(cartReservationHashes[product.ID], models.GenerateAttributesHash(attrStrMap))
+			}
 			saleCountAdjustments[product.ID] += item.Quantity
 			// 虚拟商品不需要处理物理库存绑定
 			continue
@@ -1116,6 +1304,9 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 		}
 
 		if product.InventoryMode == string(models.InventoryModeRandom) || hasBlindBox {
+			if err := acquireBlindBox(); err != nil {
+				return nil, err
+			}
 			// 盲盒模式 或 混合模式（有盲盒属性）
 			if hasUserSelect && len(attributesMap) > 0 {
 				// 混合模式：部分属性User选择，部分属性盲盒随机
@@ -1161,10 +1352,22 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 			}
 			return nil, fmt.Errorf("product %s %s", product.Name, msg)
 		}
+		// 加购即预留：其他用户购物车中占用的数量不可售
+		if reservedByOthers := s.cartReservedByOthers(product, userID, attributesMap); reservedByOthers > 0 {
+			if available := inventory.GetAvailableStock() - reservedByOthers; available < item.Quantity {
+				if available < 0 {
+					available = 0
+				}
+				return nil, bizerr.Newf("order.stockInsufficient", "Product %s stock insufficient, only %d available", product.Name, available).
+					WithParams(map[string]interface{}{"product": product.Name, "available": available})
+			}
+		}
+		if product.ReserveOnCart {
+			cartReservationHashes[product.ID] = append(cartReservationHashes[product.ID], models.GenerateAttributesHash(attributesMap))
+		}
 
-		// 预留Inventory（generateOrder号后Update）
-		// 注意：这里先记录need预留的InventoryID，CreateOrder后再调用预留
-		// 使用 _inventory_id 作为临时标记，预留Success后会改为 inventory_id 永久保存
+		// 预留Inventory（generateOrder号后在下单事务内预留）
+		// 使用 _inventory_id 作为临时标记，预留Success后写入订单的 InventoryBindings
 		item.Attributes["_inventory_id"] = inventory.ID
 
 		saleCountAdjustments[product.ID] += item.Quantity
@@ -1173,31 +1376,15 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 	// generateOrder号
 	orderNo := utils.GenerateOrderNo(s.cfg.Order.NoPrefix)
 
-	// Inventory绑定映射（Order项索引 -> InventoryID）
-	inventoryBindings := make(map[int]uint)
-
-	// 预留Inventory（在CreateOrder前）
+	// 待预留的物理库存（Order项索引 -> InventoryID），预留在下单事务内进行
+	pendingInventoryIDs := make(map[int]uint)
 	for i := range items {
-		item := &items[i]
-
-		if inventoryIDVal, ok := item.Attributes["_inventory_id"]; ok {
+		if inventoryIDVal, ok := items[i].Attributes["_inventory_id"]; ok {
 			if inventoryID, ok := inventoryIDVal.(uint); ok {
-				// 预留Inventory
-				reservedInventoryID, err := s.reserveInventoryWithHook(nil, &userID, orderNo, inventoryID, item.Quantity, "user_create_order")
-				if err != nil {
-					// 预留Failed，need回滚之前已预留的Inventory
-					for j := 0; j < i; j++ {
-						if prevInvID, exists := inventoryBindings[j]; exists {
-							_ = s.releaseReservedInventoryWithHook(nil, &userID, orderNo, prevInvID, items[j].Quantity, "user_create_order_rollback")
-						}
-					}
-					return nil, normalizeOrderInventoryOperationError(item.Name, err)
-				}
-				// 保存Inventory绑定关系（使用独立的映射表，不污染Product属性）
-				inventoryBindings[i] = reservedInventoryID
-				// 从属性中移除临时标记
-				delete(item.Attributes, "_inventory_id")
+				pendingInventoryIDs[i] = inventoryID
 			}
+			// 从属性中移除临时标记
+			delete(items[i].Attributes, "_inventory_id")
 		}
 	}
 
@@ -1229,13 +1416,8 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 	// 所有订单创建时都是待付款状态
 	orderStatus := models.OrderStatusPendingPayment
 
-	// 计算订单总金额
-	var totalAmount int64
-	for _, item := range items {
-		if product := productBySKU[item.SKU]; product != nil {
-			totalAmount += product.Price * int64(item.Quantity)
-		}
-	}
+	// 计算订单总金额；baseAmount 为不计阶梯价的原价小计，供优惠码叠加规则比较
+	totalAmount, baseAmount := applyTierPricing(items, productBySKU)
 
 	// 获取货币单位
 	currency := s.cfg.Order.Currency
@@ -1243,25 +1425,20 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 		currency = "CNY"
 	}
 
-	// 处理优惠码
+	// 处理优惠码：此处只校验，名额在下单事务内预留
 	var promoCodeID *uint
 	var promoCodeStr string
 	var discountAmount int64
 	if promoCode != "" && s.promoCodeRepo != nil {
-		promoCodeRepo := s.promoCodeRepo
-		pc, err := promoCodeRepo.FindByCode(strings.ToUpper(strings.TrimSpace(promoCode)))
+		pc, err := s.promoCodeRepo.FindByCode(strings.ToUpper(strings.TrimSpace(promoCode)))
 		if err != nil {
-			// 释放已预留的库存
-			for i, inventoryID := range inventoryBindings {
-				_ = s.releaseReservedInventoryWithHook(nil, &userID, orderNo, inventoryID, items[i].Quantity, "user_create_order_rollback")
-			}
-			return nil, fmt.Errorf("Promo code not found")
+			return nil, translatePromoCodeLookupError(err)
 		}
 		if !pc.IsAvailable() {
-			for i, inventoryID := range inventoryBindings {
-				_ = s.releaseReservedInventoryWithHook(nil, &userID, orderNo, inventoryID, items[i].Quantity, "user_create_order_rollback")
+			if pc.IsExhausted() {
+				return nil, newPromoCodeExhaustedError()
 			}
-			return nil, fmt.Errorf("Promo code is not available")
+			return nil, bizerr.New("promo_code.unavailable", "Promo code is not available")
 		}
 		// 收集订单中的商品ID
 		var productIDs []uint
@@ -1280,43 +1457,25 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 				}
 			}
 			if !applicable {
-				for i, inventoryID := range inventoryBindings {
-					_ = s.releaseReservedInventoryWithHook(nil, &userID, orderNo, inventoryID, items[i].Quantity, "user_create_order_rollback")
-				}
-				return nil, fmt.Errorf("Promo code is not applicable to the selected products")
+				return nil, bizerr.New("promo_code.notApplicable", "Promo code is not applicable to the selected products")
 			}
 		}
-		discountAmount = pc.CalculateDiscount(totalAmount)
-		// 预留优惠码
-		if err := promoCodeRepo.Reserve(pc.ID, orderNo); err != nil {
-			for i, inventoryID := range inventoryBindings {
-				_ = s.releaseReservedInventoryWithHook(nil, &userID, orderNo, inventoryID, items[i].Quantity, "user_create_order_rollback")
-			}
-			return nil, fmt.Errorf("Failed to reserve promo code: %v", err)
+		usePromo, err := s.resolveTierPromoStacking(items, productBySKU, pc, &totalAmount, baseAmount)
+		if err != nil {
+			return nil, err
+		}
+		if usePromo {
+			discountAmount = pc.CalculateDiscount(totalAmount)
+			promoCodeID = &pc.ID
+			promoCodeStr = pc.Code
 		}
-		promoCodeID = &pc.ID
-		promoCodeStr = pc.Code
-	}
-
-	order := &models.Order{
-		OrderNo:                   orderNo,
-		UserID:                    &userID,
-		Items:                     items,
-		ActualAttributes:          actualAttrsJSON,
-		InventoryBindings:         inventoryBindings, // 保存Inventory绑定关系（内部使用）
-		Status:                    orderStatus,
-		TotalAmount:               totalAmount - discountAmount,
-		Currency:                  currency,
-		PromoCodeID:               promoCodeID,
-		PromoCodeStr:              promoCodeStr,
-		DiscountAmount:            discountAmount,
-		Source:                    "web",
-		UserEmail:                 user.Email,
-		EmailNotificationsEnabled: true,
-		Remark:                    remark,
-		// FormToken 和 FormExpiresAt 在User点击填写时动态generate（仅非虚拟商品订单需要）
 	}
 
+	// 物理库存、优惠码、赠品与虚拟库存的预留和订单写入在同一事务内完成：
+	// 任一步失败（或进程中途崩溃）都整体回滚，不会留下悬空的预留
+	inventoryBindings := make(map[int]uint)
+	var order *models.Order
+	var afterCommitHooks []func()
 	if err := s.OrderRepo.WithTransaction(func(tx *gorm.DB) error {
 		if err := s.ensurePendingPaymentLimitTx(tx, userID); err != nil {
 			return err
@@ -1324,29 +1483,42 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 		if err := s.ensurePurchaseLimitsTx(tx, userID, requestedQtyBySKU); err != nil {
 			return err
 		}
-		if err := tx.Create(order).Error; err != nil {
-			return err
-		}
-		return applyUserPurchaseStatsTransitionTx(tx, nil, order.UserID, "", order.Status, order.Items)
-	}); err != nil {
-		// CreateOrderFailed，释放已预留的Inventory
-		for i, inventoryID := range inventoryBindings {
-			_ = s.releaseReservedInventoryWithHook(nil, &userID, orderNo, inventoryID, items[i].Quantity, "user_create_order_rollback")
+
+		// 预留Inventory（库存行 FOR UPDATE 锁定至事务结束）
+		for i := range items {
+			inventoryID, ok := pendingInventoryIDs[i]
+			if !ok {
+				continue
+			}
+			reservedInventoryID, err := s.reserveInventoryWithHookTx(tx, &afterCommitHooks, nil, &userID, orderNo, inventoryID, items[i].Quantity, "user_create_order")
+			if err != nil {
+				return normalizeOrderInventoryOperationError(items[i].Name, err)
+			}
+			// 保存Inventory绑定关系（使用独立的映射表，不污染Product属性）
+			inventoryBindings[i] = reservedInventoryID
 		}
-		// 释放优惠码
-		if promoCodeID != nil && s.promoCodeRepo != nil {
-			s.promoCodeRepo.ReleaseReserve(*promoCodeID, orderNo)
+
+		// 预留优惠码
+		if promoCodeID != nil {
+			if err := repository.NewPromoCodeRepository(tx).Reserve(*promoCodeID, orderNo); err != nil {
+				return translatePromoCodeReserveError(err)
+			}
 		}
-		return nil, err
-	}
 
-	// 虚拟产品预留库存（待付款状态，付款后才发货）
-	userVirtualInventoryBindings := make(map[int]uint)
-	if s.virtualProductSvc != nil {
-		for i := range items {
-			item := &items[i]
-			product := productBySKU[item.SKU]
-			if product != nil && product.ProductType == models.ProductTypeVirtual {
+		// 满赠：按商品原价小计追加赠品并预留赠品库存
+		orderItems := s.appendGiftItems(tx, &afterCommitHooks, items, totalAmount, productBySKU, inventoryBindings, &userID, orderNo)
+		// 优惠按行小计比例分摊到订单项
+		allocateOrderItemTotals(orderItems, totalAmount-discountAmount)
+
+		// 虚拟产品预留库存（待付款状态，付款后才发货）
+		virtualInventoryBindings := make(map[int]uint)
+		if s.virtualProductSvc != nil {
+			for i := range orderItems {
+				item := &orderItems[i]
+				product := productBySKU[item.SKU]
+				if item.IsGift || product == nil || product.ProductType != models.ProductTypeVirtual {
+					continue
+				}
 				// 为虚拟产品分配库存（预留状态），传入完整规格属性
 				// 需要从 ActualAttributes 中合并盲盒属性回来用于库存匹配
 				allocAttrs := make(map[string]interface{})
@@ -1365,36 +1537,72 @@ func (s *OrderService) CreateUserOrder(userID uint, items []models.OrderItem, re
 						}
 					}
 				}
-				_, scriptInvID, err := s.virtualProductSvc.AllocateStockForProductByAttributes(product.ID, item.Quantity, orderNo, allocAttrs)
+				_, scriptInvID, err := s.virtualProductSvc.AllocateStockForProductByAttributesWithTx(tx, product.ID, item.Quantity, orderNo, allocAttrs)
 				if err != nil {
-					// 分配失败，需要回滚订单和已分配的物理库存
-					if releaseErr := s.virtualProductSvc.ReleaseStock(orderNo); releaseErr != nil {
-						fmt.Printf("Warning: Failed to rollback virtual stock for order %s: %v\n", orderNo, releaseErr)
-					}
-					for j, inventoryID := range inventoryBindings {
-						orderIDForHook := order.ID
-						_ = s.releaseReservedInventoryWithHook(&orderIDForHook, &userID, orderNo, inventoryID, items[j].Quantity, "user_create_order_rollback")
-					}
-					if promoCodeID != nil && s.promoCodeRepo != nil {
-						if releaseErr := s.promoCodeRepo.ReleaseReserve(*promoCodeID, orderNo); releaseErr != nil {
-							fmt.Printf("Warning: Failed to rollback promo code reserve for order %s: %v\n", orderNo, releaseErr)
-						}
-					}
-					s.OrderRepo.Delete(order.ID)
-					return nil, fmt.Errorf("failed to allocate virtual product stock: %w", err)
+					return fmt.Errorf("failed to allocate virtual product stock: %w", err)
 				}
 				if scriptInvID != nil {
-					userVirtualInventoryBindings[i] = *scriptInvID
+					virtualInventoryBindings[i] = *scriptInvID
 				}
 			}
+			// 注意：待付款状态不自动发货，需要管理员标记付款后才发货
+		}
+
+		order = &models.Order{
+			OrderNo:                   orderNo,
+			UserID:                    &userID,
+			StoreID:                   storeID,
+			Items:                     orderItems,
+			ActualAttributes:          actualAttrsJSON,
+			InventoryBindings:         inventoryBindings, // 保存Inventory绑定关系（内部使用）
+			Status:                    orderStatus,
+			TotalAmount:               totalAmount - discountAmount,
+			Currency:                  currency,
+			PromoCodeID:               promoCodeID,
+			PromoCodeStr:              promoCodeStr,
+			DiscountAmount:            discountAmount,
+			Source:                    "web",
+			UserEmail:                 user.Email,
+			EmailNotificationsEnabled: true,
+			Remark:                    remark,
+			// FormToken 和 FormExpiresAt 在User点击填写时动态generate（仅非虚拟商品订单需要）
+		}
+		if len(virtualInventoryBindings) > 0 {
+			// 保存脚本类型虚拟库存绑定
+			order.VirtualInventoryBindings = virtualInventoryBindings
+		}
+
+		weight, err := ResolveOrderWeightGrams(tx, order)
+		if err != nil {
+			return err
+		}
+		order.TotalWeightGrams = weight
+		if err := assignOrderPaymentDeadlineTx(tx, s.cfg, order); err != nil {
+			return err
+		}
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		if err := RecordOrderCreatedLedgerTx(tx, order, false, "web"); err != nil {
+			return err
 		}
-		// 注意：待付款状态不自动发货，需要管理员标记付款后才发货
+		return applyUserPurchaseStatsTransitionTx(tx, nil, order.UserID, "", order.Status, order.Items)
+	}); err != nil {
+		return nil, err
 	}
 
-	// 保存脚本类型虚拟库存绑定
-	if len(userVirtualInventoryBindings) > 0 {
-		order.VirtualInventoryBindings = userVirtualInventoryBindings
-		s.OrderRepo.Update(order)
+	// 事务已提交，再派发库存预留的 after 钩子
+	for _, dispatch := range afterCommitHooks {
+		dispatch()
+	}
+
+	// 订单已预留库存，释放对应的购物车预留
+	if s.cartReservation != nil {
+		for productID, hashes := range cartReservationHashes {
+			for _, attributesHash := range hashes {
+				s.cartReservation.Release(productID, attributesHash, userID)
+			}
+		}
 	}
 
 	for productID, quantity := range saleCountAdjustments {
@@ -1563,9 +1771,14 @@ func (s *OrderService) SubmitShippingForm(formToken string, receiverInfo map[str
 			user = &existingUser
 			isNewUser = false
 		} else {
-			if actorUserID != nil {
+			// 已被认领的订单保持归属，不再按收件邮箱重新匹配用户
+			ownerUserID := actorUserID
+			if ownerUserID == nil {
+				ownerUserID = lockedOrder.UserID
+			}
+			if ownerUserID != nil {
 				var existingUser models.User
-				if err := tx.First(&existingUser, *actorUserID).Error; err != nil {
+				if err := tx.First(&existingUser, *ownerUserID).Error; err != nil {
 					if errors.Is(err, gorm.ErrRecordNotFound) {
 						return newOrderUserNotFoundError()
 					}
@@ -1645,29 +1858,13 @@ func (s *OrderService) SubmitShippingForm(formToken string, receiverInfo map[str
 			lockedOrder.UserID = &user.ID
 		}
 
-		userUpdateValue := interface{}(nil)
-		if lockedOrder.UserID != nil {
-			userUpdateValue = *lockedOrder.UserID
-		}
-
-		orderUpdates := map[string]interface{}{
-			"receiver_name":     lockedOrder.ReceiverName,
-			"phone_code":        lockedOrder.PhoneCode,
-			"receiver_phone":    lockedOrder.ReceiverPhone,
-			"receiver_email":    lockedOrder.ReceiverEmail,
-			"receiver_country":  lockedOrder.ReceiverCountry,
-			"receiver_province": lockedOrder.ReceiverProvince,
-			"receiver_city":     lockedOrder.ReceiverCity,
-			"receiver_district": lockedOrder.ReceiverDistrict,
-			"receiver_address":  lockedOrder.ReceiverAddress,
-			"receiver_postcode": lockedOrder.ReceiverPostcode,
-			"privacy_protected": lockedOrder.PrivacyProtected,
-			"remark":            lockedOrder.Remark,
-			"status":            lockedOrder.Status,
-			"form_submitted_at": lockedOrder.FormSubmittedAt,
-			"user_id":           userUpdateValue,
-		}
-		if err := tx.Model(lockedOrder).Updates(orderUpdates).Error; err != nil {
+		// 通过结构体更新，收件信息才会经过 PII 加密序列化器，盲索引由 BeforeSave 同步
+		if err := tx.Model(lockedOrder).Select(
+			"receiver_name", "phone_code", "receiver_phone", "receiver_email",
+			"receiver_country", "receiver_province", "receiver_city", "receiver_district",
+			"receiver_address", "receiver_postcode", "receiver_email_bidx", "receiver_phone_bidx",
+			"privacy_protected", "remark", "status", "form_submitted_at", "user_id",
+		).Updates(lockedOrder).Error; err != nil {
 			return err
 		}
 		if err := applyUserPurchaseStatsTransitionTx(tx, beforeUserID, lockedOrder.UserID, beforeStatus, lockedOrder.Status, lockedOrder.Items); err != nil {
@@ -1794,8 +1991,8 @@ func (s *OrderService) GetOrderByID(id uint) (*models.Order, error) {
 }
 
 // ListOrders getOrder List
-func (s *OrderService) ListOrders(page, limit int, status, search, country, productSearch string, promoCodeID *uint, promoCode string, userID *uint) ([]models.Order, int64, error) {
-	return s.OrderRepo.List(page, limit, status, search, country, productSearch, promoCodeID, promoCode, userID)
+func (s *OrderService) ListOrders(page, limit int, status, subStatus string, tags []string, search, country, productSearch string, promoCodeID *uint, promoCode string, userID *uint, storeScope *repository.StoreScope, advanced *listfilter.Expr) ([]models.Order, int64, error) {
+	return s.OrderRepo.List(page, limit, status, subStatus, tags, search, country, productSearch, promoCodeID, promoCode, userID, storeScope, advanced)
 }
 
 // GetOrderCountries get所有有Order的国家列表
@@ -1943,6 +2140,19 @@ func (s *OrderService) DeliverVirtualStock(orderID uint, deliveredBy uint, markO
 
 // CompleteOrder 完成Order
 func (s *OrderService) CompleteOrder(orderID uint, completedBy uint, feedback, adminRemark string) error {
+	return s.completeOrder(orderID, &completedBy, feedback, models.OrderNoteSourceComplete, adminRemark, map[string]interface{}{
+		"source":         "complete_order",
+		"trigger_action": "order.complete",
+		"completed_by":   completedBy,
+	})
+}
+
+// AutoCompleteOrder 系统自动完成订单（如物流签收满 N 天），completed_by 为空
+func (s *OrderService) AutoCompleteOrder(orderID uint, remark string, hookExtra map[string]interface{}) error {
+	return s.completeOrder(orderID, nil, "", models.OrderNoteSourceAutoComplete, remark, hookExtra)
+}
+
+func (s *OrderService) completeOrder(orderID uint, completedBy *uint, feedback, noteSource, remark string, hookExtra map[string]interface{}) error {
 	order, err := s.OrderRepo.FindByID(orderID)
 	if err != nil {
 		return normalizeOrderLookupError(err)
@@ -1956,16 +2166,10 @@ func (s *OrderService) CompleteOrder(orderID uint, completedBy uint, feedback, a
 	order.Status = models.OrderStatusCompleted
 	now := models.NowFunc()
 	order.CompletedAt = &now
-	order.CompletedBy = &completedBy
+	order.CompletedBy = completedBy
 	if feedback != "" {
 		order.UserFeedback = feedback
 	}
-	if adminRemark != "" {
-		if order.AdminRemark != "" {
-			order.AdminRemark += "\n"
-		}
-		order.AdminRemark += "[Complete] " + adminRemark
-	}
 
 	// 扣减优惠码（从预留转为已使用）
 	if order.PromoCodeID != nil && s.promoCodeRepo != nil {
@@ -1974,14 +2178,15 @@ func (s *OrderService) CompleteOrder(orderID uint, completedBy uint, feedback, a
 		}
 	}
 
-	if err := s.OrderRepo.Update(order); err != nil {
+	if err := s.OrderRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := tx.Save(order).Error; err != nil {
+			return err
+		}
+		return AddOrderNoteTx(tx, order.ID, completedBy, noteSource, remark)
+	}); err != nil {
 		return err
 	}
-	EmitOrderStatusChangedAfterHookAsync(s.pluginManager, nil, order, beforeStatus, order.Status, map[string]interface{}{
-		"source":         "complete_order",
-		"trigger_action": "order.complete",
-		"completed_by":   completedBy,
-	})
+	EmitOrderStatusChangedAfterHookAsync(s.pluginManager, nil, order, beforeStatus, order.Status, hookExtra)
 
 	// 发送完成邮件通知
 	if s.emailService != nil {
@@ -2007,14 +2212,13 @@ func (s *OrderService) RequestResubmit(orderID uint, reason string) (string, err
 	order.FormToken = &formToken
 	order.FormExpiresAt = &formExpiresAt
 	order.FormSubmittedAt = nil // 清空提交时间，允许重新提交
-	if reason != "" {
-		if order.AdminRemark != "" {
-			order.AdminRemark += "\n"
-		}
-		order.AdminRemark += "[Resubmit] " + reason
-	}
 
-	if err := s.OrderRepo.Update(order); err != nil {
+	if err := s.OrderRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := tx.Save(order).Error; err != nil {
+			return err
+		}
+		return AddOrderNoteTx(tx, order.ID, nil, models.OrderNoteSourceResubmit, reason)
+	}); err != nil {
 		return "", err
 	}
 	EmitOrderStatusChangedAfterHookAsync(s.pluginManager, nil, order, beforeStatus, order.Status, map[string]interface{}{
@@ -2026,7 +2230,7 @@ func (s *OrderService) RequestResubmit(orderID uint, reason string) (string, err
 	// 发送重填通知邮件
 	if s.emailService != nil {
 		formURL := s.cfg.App.URL + "/form/shipping?token=" + formToken
-		go s.emailService.SendOrderResubmitEmail(order, formURL)
+		go s.emailService.SendOrderResubmitEmail(order, formURL, reason)
 	}
 
 	return formToken, nil
@@ -2153,14 +2357,22 @@ func (s *OrderService) CancelOrder(orderID uint, reason string) error {
 	}
 
 	order.Status = models.OrderStatusCancelled
-	if reason != "" {
-		if order.AdminRemark != "" {
-			order.AdminRemark += "\n"
-		}
-		order.AdminRemark += "[Cancel] " + reason
-	}
 
-	if err := s.OrderRepo.Update(order); err != nil {
+	if err := s.OrderRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := tx.Save(order).Error; err != nil {
+			return err
+		}
+		if err := AddOrderNoteTx(tx, order.ID, nil, models.OrderNoteSourceCancel, reason); err != nil {
+			return err
+		}
+		if err := ReleasePaymentAmountReservationsTx(tx, order.ID); err != nil {
+			return err
+		}
+		if err := CancelCODCollectionTx(tx, order.ID); err != nil {
+			return err
+		}
+		return RecordOrderVoidLedgerTx(tx, order, "cancel_order")
+	}); err != nil {
 		return err
 	}
 	if s.serialTaskService != nil {
@@ -2178,7 +2390,7 @@ func (s *OrderService) CancelOrder(orderID uint, reason string) error {
 
 	// 发送Order取消邮件
 	if s.emailService != nil {
-		go s.emailService.SendOrderCancelledEmail(order)
+		go s.emailService.SendOrderCancelledEmail(order, reason)
 	}
 
 	return nil
@@ -2232,6 +2444,8 @@ func (s *OrderService) MarkAsPaidWithOptions(orderID uint, options MarkAsPaidOpt
 		order = lockedOrder
 		finalizeResult, err = finalizePendingPaymentOrderTx(tx, lockedOrder, s.virtualProductSvc, paidOrderFinalizeOptions{
 			AdminRemark:             options.AdminRemark,
+			OperatorID:              options.OperatorID,
+			PaymentSource:           options.PaymentSource,
 			SkipAutoDelivery:        options.SkipAutoDelivery,
 			StrictAutoDeliveryCheck: true,
 		})
@@ -2273,13 +2487,6 @@ func (s *OrderService) MarkAsPaidWithOptions(orderID uint, options MarkAsPaidOpt
 	return nil
 }
 
-// MaskOrderIfNeeded 如果need，打码Order敏感Info
-func (s *OrderService) MaskOrderIfNeeded(order *models.Order, hasPrivacyPermission bool) {
-	if order.PrivacyProtected && !hasPrivacyPermission {
-		order.MaskSensitiveInfo()
-	}
-}
-
 // GetOrRefreshFormToken - Get or refresh form token
 // 如果Tokendoes not exist或已过期，则generate新的Token
 func (s *OrderService) GetOrRefreshFormToken(order *models.Order) (string, *time.Time, error) {
@@ -15,7 +15,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func newOrderServiceTestDB(t *testing.T) (*OrderService, *gorm.DB) {
+func newOrderServiceTestDB(t testing.TB) (*OrderService, *gorm.DB) {
 	t.Helper()
 
 	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
@@ -139,15 +139,89 @@ func TestCreateDraftAttributesTooManyReturnsBizError(t *testing.T) {
 	}
 
 	requireOrderBizErr(t, func() error {
-		_, err := svc.CreateDraft([]models.OrderItem{{
+		_, _, err := svc.CreateDraft(0, []models.OrderItem{{
 			SKU:        "SKU-ATTR",
 			Quantity:   1,
 			Attributes: attrs,
-		}}, "", "", "", "", "", "")
+		}}, "", "", "", "", "", "", nil)
 		return err
 	}(), "order.attributesTooMany")
 }
 
+func TestCreateDraftDeduplicatesExternalOrderPerPlatform(t *testing.T) {
+	svc, db := newOrderServiceTestDB(t)
+	if err := db.AutoMigrate(&models.APIKey{}, &models.OrderExternalRef{}, &models.LedgerEntry{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	product := models.Product{SKU: "SKU-EXT", Name: "Demo", Price: 500, Status: models.ProductStatusActive}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	items := func() []models.OrderItem {
+		return []models.OrderItem{{SKU: "SKU-EXT", Name: "Demo", Quantity: 1}}
+	}
+
+	first, duplicate, err := svc.CreateDraft(0, items(), "u-1", "SP-1", "shopify", "", "", "", nil)
+	if err != nil || duplicate {
+		t.Fatalf("first draft: duplicate=%v err=%v", duplicate, err)
+	}
+	again, duplicate, err := svc.CreateDraft(0, items(), "u-1", "SP-1", "shopify", "", "", "", nil)
+	if err != nil || !duplicate || again.ID != first.ID {
+		t.Fatalf("repeated external order should return the existing order: %+v duplicate=%v err=%v", again, duplicate, err)
+	}
+	if other, duplicate, err := svc.CreateDraft(0, items(), "u-1", "SP-1", "woocommerce", "", "", "", nil); err != nil || duplicate || other.ID == first.ID {
+		t.Fatalf("same external ID on another platform is a different order: duplicate=%v err=%v", duplicate, err)
+	}
+
+	// 订单删除后释放外部订单号
+	if err := db.Delete(&models.Order{}, first.ID).Error; err != nil {
+		t.Fatalf("delete order: %v", err)
+	}
+	recreated, duplicate, err := svc.CreateDraft(0, items(), "u-1", "SP-1", "shopify", "", "", "", nil)
+	if err != nil || duplicate || recreated.ID == first.ID {
+		t.Fatalf("deleted order should release the external ID: duplicate=%v err=%v", duplicate, err)
+	}
+
+}
+
+func TestCreateDraftAllowsDuplicateExternalOrdersPerAPIKey(t *testing.T) {
+	svc, db := newOrderServiceTestDB(t)
+	if err := db.AutoMigrate(&models.APIKey{}, &models.OrderExternalRef{}, &models.LedgerEntry{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	product := models.Product{SKU: "SKU-EXT", Name: "Demo", Price: 500, Status: models.ProductStatusActive}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	items := func() []models.OrderItem {
+		return []models.OrderItem{{SKU: "SKU-EXT", Name: "Demo", Quantity: 1}}
+	}
+
+	strict := models.APIKey{KeyName: "Strict", APIKey: "ak_strict", APISecretHash: "x", Platform: "shopify", IsActive: true}
+	lenient := models.APIKey{KeyName: "Lenient", APIKey: "ak_lenient", APISecretHash: "x", Platform: "shopify", IsActive: true, AllowDuplicateExternalOrders: true}
+	if err := db.Create(&strict).Error; err != nil {
+		t.Fatalf("create strict api key: %v", err)
+	}
+	if err := db.Create(&lenient).Error; err != nil {
+		t.Fatalf("create lenient api key: %v", err)
+	}
+
+	first, duplicate, err := svc.CreateDraft(strict.ID, items(), "u-1", "SP-1", "shopify", "", "", "", nil)
+	if err != nil || duplicate {
+		t.Fatalf("first draft: duplicate=%v err=%v", duplicate, err)
+	}
+	for i := 0; i < 2; i++ {
+		order, duplicate, err := svc.CreateDraft(lenient.ID, items(), "u-1", "SP-1", "shopify", "", "", "", nil)
+		if err != nil || duplicate || order.ID == first.ID {
+			t.Fatalf("key allowing duplicates should always create: duplicate=%v err=%v", duplicate, err)
+		}
+	}
+	again, duplicate, err := svc.CreateDraft(strict.ID, items(), "u-1", "SP-1", "shopify", "", "", "", nil)
+	if err != nil || !duplicate || again.ID != first.ID {
+		t.Fatalf("strict key on the same platform should still deduplicate: %+v duplicate=%v err=%v", again, duplicate, err)
+	}
+}
+
 func createOrderServiceTestOrder(t *testing.T, db *gorm.DB, orderNo string, status models.OrderStatus) models.Order {
 	t.Helper()
 
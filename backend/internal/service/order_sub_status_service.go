@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+var orderSubStatusCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+var ErrOrderSubStatusNotFound = bizerr.New("orderSubStatus.notFound", "Order sub-status not found")
+
+// OrderSubStatusInput 创建/更新子状态参数（code 创建后不可修改）
+type OrderSubStatusInput struct {
+	Code           string             `json:"code"`
+	Status         models.OrderStatus `json:"status"`
+	Name           string             `json:"name"`
+	Description    string             `json:"description"`
+	Color          string             `json:"color"`
+	SortOrder      int                `json:"sort_order"`
+	NotifyCustomer bool               `json:"notify_customer"`
+}
+
+// OrderSubStatusChange 设置订单子状态的结果
+type OrderSubStatusChange struct {
+	Order     *models.Order
+	Previous  string
+	SubStatus *models.OrderSubStatus // 清除子状态时为 nil
+}
+
+// OrderSubStatusService 订单自定义子状态
+type OrderSubStatusService struct {
+	db           *gorm.DB
+	emailService *EmailService
+}
+
+func NewOrderSubStatusService(db *gorm.DB, emailService *EmailService) *OrderSubStatusService {
+	return &OrderSubStatusService{db: db, emailService: emailService}
+}
+
+func (s *OrderSubStatusService) validateInput(input *OrderSubStatusInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return bizerr.New("orderSubStatus.nameRequired", "Sub-status name is required")
+	}
+	if !models.IsKnownOrderStatus(input.Status) {
+		return bizerr.Newf("orderSubStatus.statusInvalid", "Invalid order status: %s", input.Status).
+			WithParams(map[string]interface{}{"status": input.Status})
+	}
+	return nil
+}
+
+// List 子状态列表，status 非空时只返回该核心状态下的子状态
+func (s *OrderSubStatusService) List(status string) ([]models.OrderSubStatus, error) {
+	var items []models.OrderSubStatus
+	query := s.db.Model(&models.OrderSubStatus{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("status ASC, sort_order ASC, id ASC").Find(&items).Error
+	return items, err
+}
+
+// Get 子状态详情
+func (s *OrderSubStatusService) Get(id uint) (*models.OrderSubStatus, error) {
+	var item models.OrderSubStatus
+	if err := s.db.First(&item, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderSubStatusNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetByCode 按 code 查找子状态
+func (s *OrderSubStatusService) GetByCode(code string) (*models.OrderSubStatus, error) {
+	var item models.OrderSubStatus
+	if err := s.db.Where("code = ?", code).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderSubStatusNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Create 创建子状态
+func (s *OrderSubStatusService) Create(input OrderSubStatusInput) (*models.OrderSubStatus, error) {
+	input.Code = strings.ToLower(strings.TrimSpace(input.Code))
+	if !orderSubStatusCodePattern.MatchString(input.Code) {
+		return nil, bizerr.New("orderSubStatus.codeInvalid", "Code must start with a letter and contain only lowercase letters, digits and underscores (2-50 characters)")
+	}
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	var count int64
+	if err := s.db.Model(&models.OrderSubStatus{}).Where("code = ?", input.Code).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, bizerr.Newf("orderSubStatus.codeExists", "Sub-status %s already exists", input.Code).
+			WithParams(map[string]interface{}{"code": input.Code})
+	}
+
+	item := &models.OrderSubStatus{Code: input.Code}
+	applyOrderSubStatusInput(item, input)
+	if err := s.db.Create(item).Error; err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Update 更新子状态，已被订单使用时不允许修改所属核心状态
+func (s *OrderSubStatusService) Update(id uint, input OrderSubStatusInput) (*models.OrderSubStatus, error) {
+	item, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	if input.Status != item.Status {
+		count, err := s.countOrders(item.Code)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, newOrderSubStatusInUseError(count)
+		}
+	}
+
+	applyOrderSubStatusInput(item, input)
+	if err := s.db.Save(item).Error; err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Delete 删除子状态，仍有订单处于该子状态时不允许删除
+func (s *OrderSubStatusService) Delete(id uint) error {
+	item, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	count, err := s.countOrders(item.Code)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return newOrderSubStatusInUseError(count)
+	}
+	return s.db.Delete(item).Error
+}
+
+// countOrders 当前处于该子状态的订单数（核心状态已变化的不计）
+func (s *OrderSubStatusService) countOrders(code string) (int64, error) {
+	var count int64
+	err := s.db.Model(&models.Order{}).Where("sub_status = ? AND sub_status_for = status", code).Count(&count).Error
+	return count, err
+}
+
+// SetOrderSubStatus 设置订单子状态，code 为空表示清除
+// 子状态必须属于订单当前的核心状态；子状态开启 notify_customer 且发生变化时给用户发送邮件，note 会写入邮件
+func (s *OrderSubStatusService) SetOrderSubStatus(orderID uint, code, note string) (*OrderSubStatusChange, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+
+	var order models.Order
+	if err := s.db.First(&order, orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newOrderNotFoundError()
+		}
+		return nil, err
+	}
+	change := &OrderSubStatusChange{Order: &order, Previous: order.SubStatus}
+
+	updates := map[string]interface{}{"sub_status": "", "sub_status_for": ""}
+	if code != "" {
+		subStatus, err := s.GetByCode(code)
+		if err != nil {
+			return nil, err
+		}
+		if subStatus.Status != order.Status {
+			return nil, bizerr.Newf("orderSubStatus.statusMismatch", "Sub-status %s requires order status %s (current status: %s)", subStatus.Code, subStatus.Status, order.Status).
+				WithParams(map[string]interface{}{"code": subStatus.Code, "required": subStatus.Status, "status": order.Status})
+		}
+		change.SubStatus = subStatus
+		updates["sub_status"] = subStatus.Code
+		updates["sub_status_for"] = order.Status
+	}
+
+	// 带上核心状态条件，避免与并发的状态流转相互覆盖
+	result := s.db.Model(&models.Order{}).Where("id = ? AND status = ?", order.ID, order.Status).Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, bizerr.New("orderSubStatus.orderChanged", "Order status changed, please refresh and try again")
+	}
+	order.SubStatus = code
+	order.SubStatusFor = ""
+	if code != "" {
+		order.SubStatusFor = order.Status
+	}
+
+	if s.emailService != nil && change.SubStatus != nil && change.SubStatus.NotifyCustomer && change.Previous != code {
+		go s.emailService.SendOrderSubStatusEmail(&order, change.SubStatus, note)
+	}
+	return change, nil
+}
+
+func applyOrderSubStatusInput(item *models.OrderSubStatus, input OrderSubStatusInput) {
+	item.Status = input.Status
+	item.Name = input.Name
+	item.Description = input.Description
+	item.Color = strings.TrimSpace(input.Color)
+	item.SortOrder = input.SortOrder
+	item.NotifyCustomer = input.NotifyCustomer
+}
+
+func newOrderSubStatusInUseError(count int64) error {
+	return bizerr.Newf("orderSubStatus.inUse", "%d orders are currently in this sub-status", count).
+		WithParams(map[string]interface{}{"count": count})
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+)
+
+func TestOrderSubStatusCreateValidation(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.OrderSubStatus{})
+	svc := NewOrderSubStatusService(db, nil)
+
+	item, err := svc.Create(OrderSubStatusInput{Code: " Awaiting_Stock ", Status: models.OrderStatusPending, Name: "Awaiting stock"})
+	if err != nil {
+		t.Fatalf("create sub-status failed: %v", err)
+	}
+	if item.Code != "awaiting_stock" {
+		t.Fatalf("expected normalized code, got %q", item.Code)
+	}
+
+	_, err = svc.Create(OrderSubStatusInput{Code: "awaiting_stock", Status: models.OrderStatusPending, Name: "Dup"})
+	requireProductBizErr(t, err, "orderSubStatus.codeExists")
+	_, err = svc.Create(OrderSubStatusInput{Code: "1bad", Status: models.OrderStatusPending, Name: "Bad"})
+	requireProductBizErr(t, err, "orderSubStatus.codeInvalid")
+	_, err = svc.Create(OrderSubStatusInput{Code: "on_hold", Status: "unknown", Name: "Hold"})
+	requireProductBizErr(t, err, "orderSubStatus.statusInvalid")
+	_, err = svc.Create(OrderSubStatusInput{Code: "on_hold", Status: models.OrderStatusPending})
+	requireProductBizErr(t, err, "orderSubStatus.nameRequired")
+}
+
+func TestSetOrderSubStatusTracksCoreStatus(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Order{}, &models.OrderSubStatus{})
+	svc := NewOrderSubStatusService(db, nil)
+
+	awaiting, err := svc.Create(OrderSubStatusInput{Code: "awaiting_stock", Status: models.OrderStatusPending, Name: "Awaiting stock"})
+	if err != nil {
+		t.Fatalf("create sub-status failed: %v", err)
+	}
+	if _, err := svc.Create(OrderSubStatusInput{Code: "carrier_delay", Status: models.OrderStatusShipped, Name: "Carrier delay"}); err != nil {
+		t.Fatalf("create sub-status failed: %v", err)
+	}
+
+	order := models.Order{OrderNo: "SUB-1", Status: models.OrderStatusPending}
+	other := models.Order{OrderNo: "SUB-2", Status: models.OrderStatusPending}
+	for _, o := range []*models.Order{&order, &other} {
+		if err := db.Create(o).Error; err != nil {
+			t.Fatalf("create order failed: %v", err)
+		}
+	}
+
+	_, err = svc.SetOrderSubStatus(order.ID, "carrier_delay", "")
+	requireProductBizErr(t, err, "orderSubStatus.statusMismatch")
+	_, err = svc.SetOrderSubStatus(order.ID, "missing", "")
+	requireProductBizErr(t, err, "orderSubStatus.notFound")
+
+	change, err := svc.SetOrderSubStatus(order.ID, "awaiting_stock", "restock next week")
+	if err != nil {
+		t.Fatalf("set sub-status failed: %v", err)
+	}
+	if change.Previous != "" || change.Order.SubStatus != "awaiting_stock" || change.SubStatus == nil || change.SubStatus.ID != awaiting.ID {
+		t.Fatalf("unexpected change: %+v", change)
+	}
+
+	repo := repository.NewOrderRepository(db)
+	orders, total, err := repo.List(1, 20, "", "awaiting_stock", nil, "", "", "", nil, "", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("list orders failed: %v", err)
+	}
+	if total != 1 || len(orders) != 1 || orders[0].ID != order.ID || orders[0].SubStatus != "awaiting_stock" {
+		t.Fatalf("expected only the flagged order, got total=%d orders=%+v", total, orders)
+	}
+
+	err = svc.Delete(awaiting.ID)
+	requireProductBizErr(t, err, "orderSubStatus.inUse")
+
+	// 核心状态变化后子状态自动失效
+	if err := db.Model(&models.Order{}).Where("id = ?", order.ID).Update("status", models.OrderStatusShipped).Error; err != nil {
+		t.Fatalf("update order status failed: %v", err)
+	}
+	var reloaded models.Order
+	if err := db.First(&reloaded, order.ID).Error; err != nil {
+		t.Fatalf("reload order failed: %v", err)
+	}
+	if reloaded.SubStatus != "" {
+		t.Fatalf("expected stale sub-status to be hidden, got %q", reloaded.SubStatus)
+	}
+	_, total, err = repo.List(1, 20, "", "awaiting_stock", nil, "", "", "", nil, "", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("list orders failed: %v", err)
+	}
+	if total != 0 {
+		t.Fatalf("expected stale sub-status to be excluded from filter, got %d", total)
+	}
+	if err := svc.Delete(awaiting.ID); err != nil {
+		t.Fatalf("delete sub-status after status change failed: %v", err)
+	}
+
+	if _, err := svc.SetOrderSubStatus(other.ID, "", ""); err != nil {
+		t.Fatalf("clear sub-status failed: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/dbutil"
+	"gorm.io/gorm"
+)
+
+const maxOrderTags = 20
+
+// OrderTagStat 标签维度的订单统计；已付款金额不含沙箱订单
+type OrderTagStat struct {
+	Tag             string `json:"tag"`
+	Count           int64  `json:"count"`
+	PaidCount       int64  `json:"paid_count"`
+	PaidAmountMinor int64  `json:"paid_amount_minor"`
+}
+
+func newOrderTooManyTagsError() error {
+	return bizerr.Newf("order.tooManyTags", "An order can have at most %d tags", maxOrderTags).
+		WithParams(map[string]interface{}{"max": maxOrderTags})
+}
+
+// NormalizeOrderTags 去除首尾空白并统一小写，按出现顺序去重；空字符串忽略
+func NormalizeOrderTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, raw := range tags {
+		tag, err := normalizeOrderTag(raw)
+		if err != nil {
+			return nil, err
+		}
+		if tag == "" || containsString(normalized, tag) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxOrderTags {
+		return nil, newOrderTooManyTagsError()
+	}
+	return normalized, nil
+}
+
+func normalizeOrderTag(raw string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	if len([]rune(tag)) > maxOrderTagLength || strings.Contains(tag, ",") {
+		return "", bizerr.Newf("order.tagInvalid", "Tags must be at most %d characters and cannot contain commas", maxOrderTagLength).
+			WithParams(map[string]interface{}{"max": maxOrderTagLength})
+	}
+	return tag, nil
+}
+
+// SplitOrderTagQuery 解析逗号分隔的标签查询参数
+func SplitOrderTagQuery(raw string) []string {
+	tags := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		if tag := strings.ToLower(strings.TrimSpace(part)); tag != "" && !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// SetOrderTags 加锁后整体替换订单标签，返回替换前后的标签
+func SetOrderTags(db *gorm.DB, orderID uint, tags []string) ([]string, []string, error) {
+	normalized, err := NormalizeOrderTags(tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	var before []string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := dbutil.LockForUpdate(tx, &models.Order{}, "id = ?", orderID); err != nil {
+			return err
+		}
+		var current models.Order
+		if err := tx.Select("id", "tags").First(&current, orderID).Error; err != nil {
+			return err
+		}
+		before = current.Tags
+		return tx.Model(&current).Select("tags").Updates(&models.Order{Tags: normalized}).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, normalized, nil
+}
+
+// UpdateOrderTag 加锁后添加或移除单个标签，返回更新后的标签列表
+func UpdateOrderTag(db *gorm.DB, orderID uint, tag string, add bool) ([]string, error) {
+	tag, err := normalizeOrderTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := dbutil.LockForUpdate(tx, &models.Order{}, "id = ?", orderID); err != nil {
+			return err
+		}
+		var current models.Order
+		if err := tx.Select("id", "tags").First(&current, orderID).Error; err != nil {
+			return err
+		}
+		tags = make([]string, 0, len(current.Tags)+1)
+		found := false
+		for _, existing := range current.Tags {
+			// 兼容早期未统一大小写的标签
+			if strings.EqualFold(existing, tag) {
+				found = true
+				if !add {
+					continue
+				}
+			}
+			tags = append(tags, existing)
+		}
+		if add && !found {
+			if len(tags) >= maxOrderTags {
+				return newOrderTooManyTagsError()
+			}
+			tags = append(tags, tag)
+		}
+		if found == add {
+			return nil
+		}
+		return tx.Model(&current).Select("tags").Updates(&models.Order{Tags: tags}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// OrderTagBreakdown 按标签统计订单数与已付款金额，按订单数降序；limit <= 0 时返回全部
+func OrderTagBreakdown(db *gorm.DB, limit int) ([]OrderTagStat, error) {
+	paidStatuses := map[models.OrderStatus]bool{
+		models.OrderStatusPending:   true,
+		models.OrderStatusShipped:   true,
+		models.OrderStatusCompleted: true,
+	}
+	stats := make(map[string]*OrderTagStat)
+	var batch []models.Order
+	err := db.Model(&models.Order{}).
+		Select("id", "tags", "status", "total_amount", "is_sandbox").
+		Where("tags IS NOT NULL AND tags NOT IN ('', '[]', 'null')").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for _, order := range batch {
+				paid := paidStatuses[order.Status] && !order.IsSandbox
+				for _, tag := range order.Tags {
+					tag = strings.ToLower(tag)
+					stat, ok := stats[tag]
+					if !ok {
+						stat = &OrderTagStat{Tag: tag}
+						stats[tag] = stat
+					}
+					stat.Count++
+					if paid {
+						stat.PaidCount++
+						stat.PaidAmountMinor += order.TotalAmount
+					}
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]OrderTagStat, 0, len(stats))
+	for _, stat := range stats {
+		items = append(items, *stat)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Tag < items[j].Tag
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
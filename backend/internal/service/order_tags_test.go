@@ -0,0 +1,128 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+)
+
+func TestNormalizeOrderTags(t *testing.T) {
+	tags, err := NormalizeOrderTags([]string{" VIP ", "vip", "", "Wholesale"})
+	if err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"vip", "wholesale"}) {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+
+	_, err = NormalizeOrderTags([]string{"a,b"})
+	requireOrderBizErr(t, err, "order.tagInvalid")
+
+	tooMany := make([]string, 0, maxOrderTags+1)
+	for i := 0; i <= maxOrderTags; i++ {
+		tooMany = append(tooMany, fmt.Sprintf("tag-%d", i))
+	}
+	_, err = NormalizeOrderTags(tooMany)
+	requireOrderBizErr(t, err, "order.tooManyTags")
+}
+
+func TestOrderTagsFilterAndBreakdown(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Order{})
+
+	create := func(orderNo string, status models.OrderStatus, amount int64, sandbox bool) *models.Order {
+		t.Helper()
+		order := &models.Order{OrderNo: orderNo, Status: status, TotalAmount: amount, IsSandbox: sandbox}
+		if err := db.Create(order).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+		return order
+	}
+	vip := create("T-VIP", models.OrderStatusCompleted, 1000, false)
+	gold := create("T-GOLD", models.OrderStatusPendingPayment, 500, false)
+	test := create("T-TEST", models.OrderStatusShipped, 700, true)
+
+	if _, _, err := SetOrderTags(db, vip.ID, []string{"VIP", "wholesale"}); err != nil {
+		t.Fatalf("set tags: %v", err)
+	}
+	if _, _, err := SetOrderTags(db, gold.ID, []string{"vip-gold"}); err != nil {
+		t.Fatalf("set tags: %v", err)
+	}
+	if tags, err := UpdateOrderTag(db, test.ID, "Vip", true); err != nil || !reflect.DeepEqual(tags, []string{"vip"}) {
+		t.Fatalf("add tag: tags=%v err=%v", tags, err)
+	}
+	if tags, err := UpdateOrderTag(db, vip.ID, "WHOLESALE", false); err != nil || !reflect.DeepEqual(tags, []string{"vip"}) {
+		t.Fatalf("remove tag: tags=%v err=%v", tags, err)
+	}
+
+	repo := repository.NewOrderRepository(db)
+	// vip 不应按前缀命中 vip-gold
+	orders, total, err := repo.List(1, 20, "", "", []string{"VIP"}, "", "", "", nil, "", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if total != 2 || len(orders) != 2 {
+		t.Fatalf("expected 2 vip orders, got %d", total)
+	}
+	for _, order := range orders {
+		if order.ID == gold.ID {
+			t.Fatalf("vip-gold order should not match tag vip")
+		}
+	}
+
+	stats, err := OrderTagBreakdown(db, 0)
+	if err != nil {
+		t.Fatalf("breakdown failed: %v", err)
+	}
+	want := []OrderTagStat{
+		{Tag: "vip", Count: 2, PaidCount: 1, PaidAmountMinor: 1000},
+		{Tag: "vip-gold", Count: 1},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Fatalf("unexpected breakdown: %+v", stats)
+	}
+}
+
+func TestPaymentMethodOrderTagRules(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Order{}, &models.PaymentMethod{}, &models.OrderPaymentMethod{}, &models.OperationLog{})
+	cfg := &config.Config{}
+
+	wholesale := &models.PaymentMethod{Name: "Invoice", Type: models.PaymentMethodTypeCustom, Enabled: true, RequiredOrderTags: []string{"wholesale"}}
+	card := &models.PaymentMethod{Name: "Card", Type: models.PaymentMethodTypeCustom, Enabled: true, ExcludedOrderTags: []string{"high-risk"}}
+	for _, pm := range []*models.PaymentMethod{wholesale, card} {
+		if err := db.Create(pm).Error; err != nil {
+			t.Fatalf("create payment method: %v", err)
+		}
+	}
+
+	order := &models.Order{OrderNo: "T-PAY", Status: models.OrderStatusPendingPayment, Tags: []string{"High-Risk"}}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	pmSvc := NewPaymentMethodService(db, cfg)
+	requireOrderBizErr(t, pmSvc.SelectPaymentMethod(order.ID, wholesale.ID), "payment.methodNotAllowedForOrder")
+	requireOrderBizErr(t, pmSvc.SelectPaymentMethod(order.ID, card.ID), "payment.methodNotAllowedForOrder")
+
+	if _, _, err := SetOrderTags(db, order.ID, []string{"wholesale"}); err != nil {
+		t.Fatalf("set tags: %v", err)
+	}
+	if err := pmSvc.SelectPaymentMethod(order.ID, wholesale.ID); err != nil {
+		t.Fatalf("wholesale order should be allowed to select invoice: %v", err)
+	}
+
+	empty := []string{}
+	if err := pmSvc.UpdateOrderTagRules(wholesale.ID, &empty, nil); err != nil {
+		t.Fatalf("update rules: %v", err)
+	}
+	reloaded, err := pmSvc.Get(wholesale.ID)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if len(reloaded.RequiredOrderTags) != 0 || !reloaded.AllowsOrderTags(nil) {
+		t.Fatalf("required tags should be cleared, got %v", reloaded.RequiredOrderTags)
+	}
+}
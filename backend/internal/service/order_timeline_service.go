@@ -0,0 +1,318 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+// 用户侧时间线事件类型
+const (
+	OrderTimelineEventCreated           = "created"
+	OrderTimelineEventPaid              = "paid"
+	OrderTimelineEventFormSubmitted     = "form_submitted"
+	OrderTimelineEventResubmitRequested = "resubmit_requested"
+	OrderTimelineEventShipped           = "shipped"
+	OrderTimelineEventTracking          = "tracking"
+	OrderTimelineEventDelivered         = "delivered"
+	OrderTimelineEventVirtualDelivered  = "virtual_delivered"
+	OrderTimelineEventCompleted         = "completed"
+	OrderTimelineEventCancelled         = "cancelled"
+	OrderTimelineEventMerged            = "merged"
+	OrderTimelineEventRefundRequested   = "refund_requested"
+	OrderTimelineEventRefunded          = "refunded"
+)
+
+// OrderTimelineEvent 时间线事件（不包含操作人、备注等内部信息）
+type OrderTimelineEvent struct {
+	Type string                 `json:"type"`
+	At   time.Time              `json:"at"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// OrderTimelineEstimates 预计时间，已发生的节点不再预估
+type OrderTimelineEstimates struct {
+	PaymentExpiresAt *time.Time `json:"payment_expires_at,omitempty"`
+	ShipBy           *time.Time `json:"ship_by,omitempty"`
+	DeliveryEarliest *time.Time `json:"delivery_earliest,omitempty"`
+	DeliveryLatest   *time.Time `json:"delivery_latest,omitempty"`
+	DeliveryCountry  string     `json:"delivery_country,omitempty"`
+}
+
+// OrderTimeline 用户侧订单时间线
+type OrderTimeline struct {
+	OrderNo   string                 `json:"order_no"`
+	Status    models.OrderStatus     `json:"status"`
+	Events    []OrderTimelineEvent   `json:"events"`
+	Estimates OrderTimelineEstimates `json:"estimates"`
+}
+
+// orderTimelineLogEvents 可对用户展示的操作日志 resource_type -> action -> 事件类型
+var orderTimelineLogEvents = map[string]map[string]string{
+	"order": {
+		"mark_paid":             OrderTimelineEventPaid,
+		"request_resubmit":      OrderTimelineEventResubmitRequested,
+		"deliver_virtual_stock": OrderTimelineEventVirtualDelivered,
+		"cancel":                OrderTimelineEventCancelled,
+		"refund":                OrderTimelineEventRefunded,
+		"confirm_refund":        OrderTimelineEventRefunded,
+		"draft_expired":         OrderTimelineEventCancelled,
+		"merged_into":           OrderTimelineEventMerged,
+	},
+	"payment": {
+		"payment_success":      OrderTimelineEventPaid,
+		"order_auto_cancelled": OrderTimelineEventCancelled,
+	},
+}
+
+type OrderTimelineService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewOrderTimelineService(db *gorm.DB, cfg *config.Config) *OrderTimelineService {
+	return &OrderTimelineService{db: db, cfg: cfg}
+}
+
+func (s *OrderTimelineService) timelineConfig() config.OrderTimelineConfig {
+	cfg := s.cfg
+	if cfg == nil {
+		cfg = config.GetConfig()
+	}
+	if cfg == nil {
+		return config.OrderTimelineConfig{}
+	}
+	return cfg.Order.Timeline
+}
+
+// BuildCustomerTimeline 构建用户可见的订单时间线与预计时间
+func (s *OrderTimelineService) BuildCustomerTimeline(order *models.Order) (*OrderTimeline, error) {
+	events, err := s.collectEvents(order)
+	if err != nil {
+		return nil, err
+	}
+	estimates, err := s.buildEstimates(order, events, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &OrderTimeline{
+		OrderNo:   order.OrderNo,
+		Status:    order.Status,
+		Events:    events,
+		Estimates: estimates,
+	}, nil
+}
+
+func (s *OrderTimelineService) collectEvents(order *models.Order) ([]OrderTimelineEvent, error) {
+	events := []OrderTimelineEvent{{Type: OrderTimelineEventCreated, At: order.CreatedAt}}
+	if order.FormSubmittedAt != nil {
+		events = append(events, OrderTimelineEvent{Type: OrderTimelineEventFormSubmitted, At: *order.FormSubmittedAt})
+	}
+	if order.ShippedAt != nil {
+		event := OrderTimelineEvent{Type: OrderTimelineEventShipped, At: *order.ShippedAt}
+		if order.TrackingNo != "" {
+			event.Data = map[string]interface{}{"tracking_no": order.TrackingNo}
+		}
+		events = append(events, event)
+	}
+	if order.CompletedAt != nil {
+		events = append(events, OrderTimelineEvent{Type: OrderTimelineEventCompleted, At: *order.CompletedAt})
+	}
+	trackingEvents, err := s.collectTrackingEvents(order)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, trackingEvents...)
+
+	var logs []models.OperationLog
+	if err := s.db.Where("resource_id = ? AND resource_type IN ?", order.ID, []string{"order", "payment"}).
+		Order("created_at ASC, id ASC").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	paidRecorded := false
+	for _, entry := range logs {
+		eventType, ok := orderTimelineLogEvents[entry.ResourceType][entry.Action]
+		if !ok {
+			continue
+		}
+		switch eventType {
+		case OrderTimelineEventPaid:
+			// 手动标记与轮询确认可能同时存在，只保留第一次
+			if paidRecorded {
+				continue
+			}
+			paidRecorded = true
+		case OrderTimelineEventRefunded:
+			if entry.Action == "refund" && orderTimelineLogStatusAfter(entry) == string(models.OrderStatusRefundPending) {
+				eventType = OrderTimelineEventRefundRequested
+			}
+		}
+		event := OrderTimelineEvent{Type: eventType, At: entry.CreatedAt}
+		if eventType == OrderTimelineEventMerged && entry.Details != nil {
+			// 只暴露目标订单号，便于用户跳转查看合并后的订单
+			if orderNo, _ := entry.Details["merged_into_order_no"].(string); orderNo != "" {
+				event.Data = map[string]interface{}{"order_no": orderNo}
+			}
+		}
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At)
+	})
+	return events, nil
+}
+
+// collectTrackingEvents 物流轨迹同步得到的运输节点与签收时间，运单号已变更的旧轨迹不展示
+func (s *OrderTimelineService) collectTrackingEvents(order *models.Order) ([]OrderTimelineEvent, error) {
+	if order.TrackingNo == "" {
+		return nil, nil
+	}
+	var trackings []models.ShipmentTracking
+	if err := s.db.Where("order_id = ? AND tracking_no = ?", order.ID, order.TrackingNo).Limit(1).Find(&trackings).Error; err != nil {
+		return nil, err
+	}
+	if len(trackings) == 0 {
+		return nil, nil
+	}
+	tracking := trackings[0]
+	events := make([]OrderTimelineEvent, 0, len(tracking.Events)+1)
+	for _, item := range tracking.Events {
+		if item.Time.IsZero() {
+			continue
+		}
+		data := map[string]interface{}{"description": item.Description}
+		if item.Location != "" {
+			data["location"] = item.Location
+		}
+		if item.Status != "" {
+			data["status"] = item.Status
+		}
+		events = append(events, OrderTimelineEvent{Type: OrderTimelineEventTracking, At: item.Time, Data: data})
+	}
+	if tracking.DeliveredAt != nil {
+		events = append(events, OrderTimelineEvent{Type: OrderTimelineEventDelivered, At: *tracking.DeliveredAt})
+	}
+	return events, nil
+}
+
+func orderTimelineLogStatusAfter(entry models.OperationLog) string {
+	if entry.Details == nil {
+		return ""
+	}
+	value, _ := entry.Details["status_after"].(string)
+	return value
+}
+
+func (s *OrderTimelineService) buildEstimates(order *models.Order, events []OrderTimelineEvent, now time.Time) (OrderTimelineEstimates, error) {
+	var estimates OrderTimelineEstimates
+	switch order.Status {
+	case models.OrderStatusCancelled, models.OrderStatusRefundPending, models.OrderStatusRefunded, models.OrderStatusCompleted:
+		return estimates, nil
+	case models.OrderStatusPendingPayment:
+		expiresAt := OrderPaymentDeadline(s.cfg, order)
+		estimates.PaymentExpiresAt = &expiresAt
+	}
+	if !orderHasPhysicalItems(order) || orderTimelineEventTime(events, OrderTimelineEventDelivered) != nil {
+		return estimates, nil
+	}
+
+	timelineCfg := s.timelineConfig()
+	shippedAt := order.ShippedAt
+	if shippedAt == nil {
+		days, err := s.shipWithinDays(order, timelineCfg.DefaultShipWithinDays)
+		if err != nil {
+			return estimates, err
+		}
+		// 发货时效从填写收货信息后开始计算，尚未填写时按当前时间预估
+		base := now
+		if order.FormSubmittedAt != nil {
+			base = *order.FormSubmittedAt
+		} else if paidAt := orderTimelineEventTime(events, OrderTimelineEventPaid); paidAt != nil && order.Status == models.OrderStatusPending {
+			base = *paidAt
+		}
+		shipBy := base.AddDate(0, 0, days)
+		estimates.ShipBy = &shipBy
+		shippedAt = &shipBy
+	}
+
+	country := strings.ToUpper(strings.TrimSpace(order.ReceiverCountry))
+	minDays, maxDays := resolveDeliveryEstimateDays(timelineCfg, country)
+	earliest := shippedAt.AddDate(0, 0, minDays)
+	latest := shippedAt.AddDate(0, 0, maxDays)
+	estimates.DeliveryEarliest = &earliest
+	estimates.DeliveryLatest = &latest
+	estimates.DeliveryCountry = country
+	return estimates, nil
+}
+
+// shipWithinDays 订单内实物商品发货时效的最大值
+func (s *OrderTimelineService) shipWithinDays(order *models.Order, defaultDays int) (int, error) {
+	skus := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.ProductType != models.ProductTypeVirtual && item.SKU != "" {
+			skus = append(skus, item.SKU)
+		}
+	}
+	var products []models.Product
+	if len(skus) > 0 {
+		query := s.db.Unscoped().Select("id", "sku", "store_id", "ship_within_days").Where("sku IN ?", skus)
+		if order.StoreID != nil {
+			query = query.Where("store_id IS NULL OR store_id = ?", *order.StoreID)
+		}
+		if err := query.Find(&products).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	days := 0
+	covered := make(map[string]bool, len(products))
+	for _, product := range products {
+		productDays := product.ShipWithinDays
+		if productDays <= 0 {
+			productDays = defaultDays
+		}
+		if productDays > days {
+			days = productDays
+		}
+		covered[product.SKU] = true
+	}
+	// 商品已不存在时按默认时效
+	for _, sku := range skus {
+		if !covered[sku] && defaultDays > days {
+			days = defaultDays
+		}
+	}
+	return days, nil
+}
+
+func resolveDeliveryEstimateDays(cfg config.OrderTimelineConfig, country string) (int, int) {
+	for _, rule := range cfg.DeliveryEstimates {
+		if country != "" && strings.EqualFold(rule.Country, country) {
+			return rule.MinDays, rule.MaxDays
+		}
+	}
+	return cfg.DefaultDeliveryMinDays, cfg.DefaultDeliveryMaxDays
+}
+
+func orderHasPhysicalItems(order *models.Order) bool {
+	for _, item := range order.Items {
+		if item.ProductType != models.ProductTypeVirtual {
+			return true
+		}
+	}
+	return false
+}
+
+func orderTimelineEventTime(events []OrderTimelineEvent, eventType string) *time.Time {
+	for i := range events {
+		if events[i].Type == eventType {
+			return &events[i].At
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newOrderTimelineServiceForTest(t *testing.T) (*OrderTimelineService, *gorm.DB) {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Product{}, &models.OperationLog{}, &models.ShipmentTracking{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	cfg := &config.Config{}
+	cfg.Order.AutoCancelHours = 24
+	cfg.Order.Timeline = config.OrderTimelineConfig{
+		DefaultShipWithinDays:  3,
+		DefaultDeliveryMinDays: 5,
+		DefaultDeliveryMaxDays: 10,
+		DeliveryEstimates:      []config.DeliveryEstimateRule{{Country: "CN", MinDays: 1, MaxDays: 2}},
+	}
+	return NewOrderTimelineService(db, cfg), db
+}
+
+func TestOrderTimelineEstimatesUseProductSLAAndCountry(t *testing.T) {
+	svc, db := newOrderTimelineServiceForTest(t)
+
+	if err := db.Create(&models.Product{SKU: "SLOW", Name: "Slow", ShipWithinDays: 7}).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	if err := db.Create(&models.Product{SKU: "FAST", Name: "Fast"}).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	createdAt := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	submittedAt := createdAt.Add(time.Hour)
+	order := &models.Order{
+		ID:              1,
+		OrderNo:         "ORD1",
+		Status:          models.OrderStatusPending,
+		Items:           []models.OrderItem{{SKU: "SLOW", Quantity: 1}, {SKU: "FAST", Quantity: 1}},
+		ReceiverCountry: "cn",
+		FormSubmittedAt: &submittedAt,
+		CreatedAt:       createdAt,
+	}
+	db.Create(&models.OperationLog{Action: "payment_success", ResourceType: "payment", ResourceID: &order.ID, CreatedAt: createdAt.Add(30 * time.Minute)})
+	db.Create(&models.OperationLog{Action: "mark_paid", ResourceType: "order", ResourceID: &order.ID, CreatedAt: createdAt.Add(40 * time.Minute)})
+	db.Create(&models.OperationLog{Action: "update_price", ResourceType: "order", ResourceID: &order.ID, CreatedAt: createdAt.Add(50 * time.Minute)})
+
+	timeline, err := svc.BuildCustomerTimeline(order)
+	if err != nil {
+		t.Fatalf("build timeline: %v", err)
+	}
+
+	types := make([]string, 0, len(timeline.Events))
+	for _, event := range timeline.Events {
+		types = append(types, event.Type)
+	}
+	if got := strings.Join(types, ","); got != "created,paid,form_submitted" {
+		t.Fatalf("unexpected events: %s", got)
+	}
+
+	estimates := timeline.Estimates
+	if estimates.PaymentExpiresAt != nil {
+		t.Fatalf("paid order should not expose payment expiry")
+	}
+	if estimates.ShipBy == nil || !estimates.ShipBy.Equal(submittedAt.AddDate(0, 0, 7)) {
+		t.Fatalf("expected ship-by to use the slowest product SLA, got %v", estimates.ShipBy)
+	}
+	if estimates.DeliveryCountry != "CN" || !estimates.DeliveryLatest.Equal(estimates.ShipBy.AddDate(0, 0, 2)) {
+		t.Fatalf("expected CN delivery estimate, got %+v", estimates)
+	}
+}
+
+func TestOrderTimelinePendingPaymentAndVirtualOrders(t *testing.T) {
+	svc, _ := newOrderTimelineServiceForTest(t)
+
+	createdAt := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	order := &models.Order{
+		ID:              2,
+		OrderNo:         "ORD2",
+		Status:          models.OrderStatusPendingPayment,
+		Items:           []models.OrderItem{{SKU: "GONE", Quantity: 1}},
+		ReceiverCountry: "DE",
+		CreatedAt:       createdAt,
+	}
+	timeline, err := svc.BuildCustomerTimeline(order)
+	if err != nil {
+		t.Fatalf("build timeline: %v", err)
+	}
+	if timeline.Estimates.PaymentExpiresAt == nil || !timeline.Estimates.PaymentExpiresAt.Equal(createdAt.Add(24*time.Hour)) {
+		t.Fatalf("unexpected payment expiry: %v", timeline.Estimates.PaymentExpiresAt)
+	}
+	if timeline.Estimates.ShipBy == nil {
+		t.Fatalf("expected default ship-by for missing product")
+	}
+	gap := timeline.Estimates.DeliveryLatest.Sub(*timeline.Estimates.DeliveryEarliest)
+	if gap != 5*24*time.Hour {
+		t.Fatalf("expected default delivery range, got %v", gap)
+	}
+
+	order.Items = []models.OrderItem{{SKU: "KEY", Quantity: 1, ProductType: models.ProductTypeVirtual}}
+	timeline, err = svc.BuildCustomerTimeline(order)
+	if err != nil {
+		t.Fatalf("build timeline: %v", err)
+	}
+	if timeline.Estimates.ShipBy != nil || timeline.Estimates.DeliveryEarliest != nil {
+		t.Fatalf("virtual order should not have shipping estimates: %+v", timeline.Estimates)
+	}
+}
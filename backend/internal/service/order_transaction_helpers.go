@@ -15,6 +15,8 @@ import (
 
 type paidOrderFinalizeOptions struct {
 	AdminRemark             string
+	OperatorID              *uint
+	PaymentSource           string
 	SkipAutoDelivery        bool
 	StrictAutoDeliveryCheck bool
 }
@@ -189,17 +191,22 @@ func finalizePendingPaymentOrderTx(tx *gorm.DB, order *models.Order, virtualSvc
 		}
 	}
 
-	if trimmed := strings.TrimSpace(options.AdminRemark); trimmed != "" {
-		if strings.TrimSpace(order.AdminRemark) != "" {
-			order.AdminRemark += "\n"
-		}
-		order.AdminRemark += trimmed
-		txUpdates["admin_remark"] = order.AdminRemark
-	}
-
 	if err := tx.Model(order).Updates(txUpdates).Error; err != nil {
 		return nil, err
 	}
+	if err := AddOrderNoteTx(tx, order.ID, options.OperatorID, models.OrderNoteSourceMarkPaid, options.AdminRemark); err != nil {
+		return nil, err
+	}
+	paymentSource := strings.TrimSpace(options.PaymentSource)
+	if paymentSource == "" {
+		paymentSource = "mark_paid"
+	}
+	if err := RecordOrderPaymentLedgerTx(tx, order, paymentSource, nil); err != nil {
+		return nil, err
+	}
+	if err := ReleasePaymentAmountReservationsTx(tx, order.ID); err != nil {
+		return nil, err
+	}
 
 	result.Updated = true
 	if status, ok := txUpdates["status"].(models.OrderStatus); ok {
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+)
+
+const (
+	// UserOrderExportDefaultRangeDays 未指定区间时默认导出最近 90 天
+	UserOrderExportDefaultRangeDays = 90
+	userOrderExportMaxRangeDays     = 366
+	userOrderExportMaxRows          = 5000
+)
+
+// ValidateUserOrderExportRange 校验用户导出区间 [from, to)
+func ValidateUserOrderExportRange(from, to time.Time) error {
+	if !to.After(from) || to.Sub(from) > userOrderExportMaxRangeDays*24*time.Hour {
+		return bizerr.Newf("order.exportRangeInvalid", "Date range must be between 1 and %d days", userOrderExportMaxRangeDays).
+			WithParams(map[string]interface{}{"max": userOrderExportMaxRangeDays})
+	}
+	return nil
+}
+
+// ListUserOrdersForExport 查询用户在 [from, to) 内创建的订单，按下单时间倒序。
+// 超过导出上限时要求用户缩小区间，而不是返回截断的数据
+func (s *OrderService) ListUserOrdersForExport(userID uint, from, to time.Time) ([]models.Order, error) {
+	if err := ValidateUserOrderExportRange(from, to); err != nil {
+		return nil, err
+	}
+
+	orders, total, err := s.OrderRepo.FindByUserIDInRange(userID, from, to, userOrderExportMaxRows)
+	if err != nil {
+		return nil, err
+	}
+	if total > userOrderExportMaxRows {
+		return nil, bizerr.Newf("order.exportTooManyRows", "Too many orders in range, at most %d per export", userOrderExportMaxRows).
+			WithParams(map[string]interface{}{"max": userOrderExportMaxRows})
+	}
+	return orders, nil
+}
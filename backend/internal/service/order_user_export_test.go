@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func TestListUserOrdersForExportScopesToUserAndRange(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{})
+	svc := newConcurrentOrderService(db, &config.Config{}, nil)
+
+	userID, otherUserID := uint(7), uint(8)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	orders := []models.Order{
+		{OrderNo: "EXP-OLD", UserID: &userID, Status: models.OrderStatusCompleted, CreatedAt: from.Add(-time.Hour)},
+		{OrderNo: "EXP-A", UserID: &userID, Status: models.OrderStatusCompleted, CreatedAt: from.Add(time.Hour), TotalAmount: 1200,
+			Items: []models.OrderItem{{SKU: "SKU-1", Name: "Mug", Quantity: 2, UnitPriceMinor: 600, LineTotalMinor: 1200}}},
+		{OrderNo: "EXP-B", UserID: &userID, Status: models.OrderStatusShipped, CreatedAt: to.Add(-time.Hour)},
+		{OrderNo: "EXP-END", UserID: &userID, Status: models.OrderStatusShipped, CreatedAt: to},
+		{OrderNo: "EXP-OTHER", UserID: &otherUserID, Status: models.OrderStatusCompleted, CreatedAt: from.Add(time.Hour)},
+	}
+	for i := range orders {
+		if err := db.Create(&orders[i]).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+	}
+
+	result, err := svc.ListUserOrdersForExport(userID, from, to)
+	if err != nil {
+		t.Fatalf("export orders: %v", err)
+	}
+	if len(result) != 2 || result[0].OrderNo != "EXP-B" || result[1].OrderNo != "EXP-A" {
+		t.Fatalf("expected [EXP-B EXP-A], got %+v", result)
+	}
+	if len(result[1].Items) != 1 || result[1].Items[0].LineTotalMinor != 1200 {
+		t.Fatalf("expected items to be loaded, got %+v", result[1].Items)
+	}
+
+	_, err = svc.ListUserOrdersForExport(userID, to, from)
+	requireOrderBizErr(t, err, "order.exportRangeInvalid")
+	_, err = svc.ListUserOrdersForExport(userID, from, from.AddDate(0, 0, userOrderExportMaxRangeDays+1))
+	requireOrderBizErr(t, err, "order.exportRangeInvalid")
+}
+
+func TestListUserOrdersForExportRejectsTooManyRows(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{})
+	svc := newConcurrentOrderService(db, &config.Config{}, nil)
+
+	userID := uint(9)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	orders := make([]models.Order, 0, userOrderExportMaxRows+1)
+	for i := 0; i <= userOrderExportMaxRows; i++ {
+		orders = append(orders, models.Order{
+			OrderNo:   fmt.Sprintf("EXP-BULK-%05d", i),
+			UserID:    &userID,
+			Status:    models.OrderStatusCompleted,
+			CreatedAt: from.Add(time.Duration(i) * time.Second),
+		})
+	}
+	if err := db.CreateInBatches(&orders, 500).Error; err != nil {
+		t.Fatalf("create orders: %v", err)
+	}
+
+	_, err := svc.ListUserOrdersForExport(userID, from, from.AddDate(0, 0, 1))
+	bizErr := requireOrderBizErr(t, err, "order.exportTooManyRows")
+	if bizErr.Params["max"] != userOrderExportMaxRows {
+		t.Fatalf("expected max param, got %+v", bizErr.Params)
+	}
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"sort"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+// PackagePlanItem 订单实物商品的重量与尺寸（规格设置优先于商品设置）
+type PackagePlanItem struct {
+	SKU             string `json:"sku"`
+	Name            string `json:"name"`
+	Quantity        int    `json:"quantity"`
+	UnitWeightGrams int    `json:"unit_weight_grams"`
+	WeightGrams     int    `json:"weight_grams"`
+	LengthMM        int    `json:"length_mm"`
+	WidthMM         int    `json:"width_mm"`
+	HeightMM        int    `json:"height_mm"`
+}
+
+// PackagePlanParcelItem 包裹内的商品数量
+type PackagePlanParcelItem struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// PackagePlanParcel 建议包裹：箱型与装入的商品，重量含箱子自重
+type PackagePlanParcel struct {
+	BoxCode     string                  `json:"box_code"`
+	BoxName     string                  `json:"box_name"`
+	Items       []PackagePlanParcelItem `json:"items"`
+	WeightGrams int                     `json:"weight_grams"`
+}
+
+// PackagePlan 订单装箱建议
+type PackagePlan struct {
+	TotalWeightGrams     int                 `json:"total_weight_grams"` // 商品净重合计
+	TotalVolumeCM3       int64               `json:"total_volume_cm3"`
+	Items                []PackagePlanItem   `json:"items"`
+	Parcels              []PackagePlanParcel `json:"parcels"`
+	MissingWeightSKUs    []string            `json:"missing_weight_skus,omitempty"`
+	MissingDimensionSKUs []string            `json:"missing_dimension_skus,omitempty"`
+	UnpackableSKUs       []string            `json:"unpackable_skus,omitempty"` // 超出所有箱型的商品
+}
+
+// PackagePlanService 根据商品重量尺寸与包装箱目录生成装箱建议
+type PackagePlanService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewPackagePlanService(db *gorm.DB, cfg *config.Config) *PackagePlanService {
+	return &PackagePlanService{db: db, cfg: cfg}
+}
+
+// ResolveOrderWeightGrams 计算订单实物商品净重合计（克），下单时写入订单快照
+func ResolveOrderWeightGrams(db *gorm.DB, order *models.Order) (int, error) {
+	items, err := resolvePackagePlanItems(db, order)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, item := range items {
+		total += item.WeightGrams
+	}
+	return total, nil
+}
+
+// resolvePackagePlanItems 解析订单实物商品的重量尺寸，虚拟商品不参与
+func resolvePackagePlanItems(db *gorm.DB, order *models.Order) ([]PackagePlanItem, error) {
+	skus := make([]string, 0, len(order.Items))
+	inventoryIDs := make([]uint, 0, len(order.InventoryBindings))
+	for idx, item := range order.Items {
+		if item.ProductType == models.ProductTypeVirtual || item.SKU == "" {
+			continue
+		}
+		skus = append(skus, item.SKU)
+		if inventoryID, ok := order.InventoryBindings[idx]; ok {
+			inventoryIDs = append(inventoryIDs, inventoryID)
+		}
+	}
+	if len(skus) == 0 {
+		return nil, nil
+	}
+
+	products := make(map[string]models.Product, len(skus))
+	var productRows []models.Product
+	// 已删除商品的历史订单仍需计算
+	if err := db.Unscoped().
+		Select("id", "sku", "product_type", "weight_grams", "length_mm", "width_mm", "height_mm").
+		Where("sku IN ?", skus).Order("deleted_at IS NOT NULL, id DESC").Find(&productRows).Error; err != nil {
+		return nil, err
+	}
+	for _, product := range productRows {
+		if _, exists := products[product.SKU]; !exists {
+			products[product.SKU] = product
+		}
+	}
+	inventories := make(map[uint]models.Inventory, len(inventoryIDs))
+	if len(inventoryIDs) > 0 {
+		var inventoryRows []models.Inventory
+		if err := db.Unscoped().
+			Select("id", "weight_grams", "length_mm", "width_mm", "height_mm").
+			Where("id IN ?", inventoryIDs).Find(&inventoryRows).Error; err != nil {
+			return nil, err
+		}
+		for _, inventory := range inventoryRows {
+			inventories[inventory.ID] = inventory
+		}
+	}
+
+	items := make([]PackagePlanItem, 0, len(skus))
+	for idx, item := range order.Items {
+		if item.ProductType == models.ProductTypeVirtual || item.SKU == "" {
+			continue
+		}
+		product := products[item.SKU]
+		if product.ProductType == models.ProductTypeVirtual && item.ProductType == "" {
+			continue
+		}
+		line := PackagePlanItem{
+			SKU:             item.SKU,
+			Name:            item.Name,
+			Quantity:        item.Quantity,
+			UnitWeightGrams: product.WeightGrams,
+			LengthMM:        product.LengthMM,
+			WidthMM:         product.WidthMM,
+			HeightMM:        product.HeightMM,
+		}
+		if inventoryID, ok := order.InventoryBindings[idx]; ok {
+			inventory := inventories[inventoryID]
+			if inventory.WeightGrams > 0 {
+				line.UnitWeightGrams = inventory.WeightGrams
+			}
+			if inventory.LengthMM > 0 && inventory.WidthMM > 0 && inventory.HeightMM > 0 {
+				line.LengthMM, line.WidthMM, line.HeightMM = inventory.LengthMM, inventory.WidthMM, inventory.HeightMM
+			}
+		}
+		line.WeightGrams = line.UnitWeightGrams * item.Quantity
+		items = append(items, line)
+	}
+	return items, nil
+}
+
+// Plan 生成订单装箱建议；未配置包装箱目录时只返回重量与体积
+func (s *PackagePlanService) Plan(order *models.Order) (*PackagePlan, error) {
+	items, err := resolvePackagePlanItems(s.db, order)
+	if err != nil {
+		return nil, err
+	}
+	plan := &PackagePlan{Items: items, Parcels: []PackagePlanParcel{}}
+	for _, item := range items {
+		plan.TotalWeightGrams += item.WeightGrams
+		plan.TotalVolumeCM3 += packageVolume(item.LengthMM, item.WidthMM, item.HeightMM) * int64(item.Quantity) / 1000
+		if item.UnitWeightGrams == 0 {
+			plan.MissingWeightSKUs = append(plan.MissingWeightSKUs, item.SKU)
+		}
+		if packageVolume(item.LengthMM, item.WidthMM, item.HeightMM) == 0 {
+			plan.MissingDimensionSKUs = append(plan.MissingDimensionSKUs, item.SKU)
+		}
+	}
+	if len(s.cfg.Order.PackageBoxes) > 0 {
+		plan.Parcels, plan.UnpackableSKUs = planPackageParcels(items, s.cfg.Order.PackageBoxes)
+	}
+	return plan, nil
+}
+
+type packageUnit struct {
+	sku    string
+	dims   [3]int
+	volume int64
+	weight int
+}
+
+type packageParcelState struct {
+	box    config.PackageBox
+	units  []packageUnit
+	volume int64
+	weight int
+}
+
+// planPackageParcels 按体积降序首次适应装箱，最后为每个包裹换用能装下的最小箱型。
+// 未设置尺寸的商品只受箱子承重限制
+func planPackageParcels(items []PackagePlanItem, catalog []config.PackageBox) ([]PackagePlanParcel, []string) {
+	boxes := append([]config.PackageBox(nil), catalog...)
+	sort.SliceStable(boxes, func(i, j int) bool {
+		return packageVolume(boxes[i].LengthMM, boxes[i].WidthMM, boxes[i].HeightMM) <
+			packageVolume(boxes[j].LengthMM, boxes[j].WidthMM, boxes[j].HeightMM)
+	})
+
+	var units []packageUnit
+	for _, item := range items {
+		dims := sortedPackageDims(item.LengthMM, item.WidthMM, item.HeightMM)
+		for i := 0; i < item.Quantity; i++ {
+			units = append(units, packageUnit{
+				sku:    item.SKU,
+				dims:   dims,
+				volume: packageVolume(item.LengthMM, item.WidthMM, item.HeightMM),
+				weight: item.UnitWeightGrams,
+			})
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool { return units[i].volume > units[j].volume })
+
+	var parcels []*packageParcelState
+	var unpackable []string
+	unpackableSeen := make(map[string]bool)
+	for _, unit := range units {
+		placed := false
+		for _, parcel := range parcels {
+			if packageBoxAccepts(parcel.box, unit, parcel.volume, parcel.weight) {
+				parcel.units = append(parcel.units, unit)
+				parcel.volume += unit.volume
+				parcel.weight += unit.weight
+				placed = true
+				break
+			}
+		}
+		if placed {
+			continue
+		}
+		for _, box := range boxes {
+			if packageBoxAccepts(box, unit, 0, 0) {
+				parcels = append(parcels, &packageParcelState{box: box, units: []packageUnit{unit}, volume: unit.volume, weight: unit.weight})
+				placed = true
+				break
+			}
+		}
+		if !placed && !unpackableSeen[unit.sku] {
+			unpackableSeen[unit.sku] = true
+			unpackable = append(unpackable, unit.sku)
+		}
+	}
+
+	result := make([]PackagePlanParcel, 0, len(parcels))
+	for _, parcel := range parcels {
+		for _, box := range boxes {
+			if packageBoxFitsAll(box, parcel) {
+				parcel.box = box
+				break
+			}
+		}
+		out := PackagePlanParcel{BoxCode: parcel.box.Code, BoxName: parcel.box.Name, WeightGrams: parcel.weight + parcel.box.TareGrams}
+		counts := make(map[string]int)
+		for _, unit := range parcel.units {
+			if counts[unit.sku] == 0 {
+				out.Items = append(out.Items, PackagePlanParcelItem{SKU: unit.sku})
+			}
+			counts[unit.sku]++
+		}
+		for i := range out.Items {
+			out.Items[i].Quantity = counts[out.Items[i].SKU]
+		}
+		result = append(result, out)
+	}
+	return result, unpackable
+}
+
+func packageBoxAccepts(box config.PackageBox, unit packageUnit, usedVolume int64, usedWeight int) bool {
+	if box.MaxWeightGrams > 0 && usedWeight+unit.weight > box.MaxWeightGrams {
+		return false
+	}
+	if unit.volume == 0 {
+		return true
+	}
+	boxDims := sortedPackageDims(box.LengthMM, box.WidthMM, box.HeightMM)
+	for i := range boxDims {
+		if unit.dims[i] > boxDims[i] {
+			return false
+		}
+	}
+	return usedVolume+unit.volume <= packageVolume(box.LengthMM, box.WidthMM, box.HeightMM)
+}
+
+func packageBoxFitsAll(box config.PackageBox, parcel *packageParcelState) bool {
+	volume, weight := int64(0), 0
+	for _, unit := range parcel.units {
+		if !packageBoxAccepts(box, unit, volume, weight) {
+			return false
+		}
+		volume += unit.volume
+		weight += unit.weight
+	}
+	return true
+}
+
+func sortedPackageDims(length, width, height int) [3]int {
+	dims := [3]int{length, width, height}
+	sort.Sort(sort.Reverse(sort.IntSlice(dims[:])))
+	return dims
+}
+
+func packageVolume(length, width, height int) int64 {
+	if length <= 0 || width <= 0 || height <= 0 {
+		return 0
+	}
+	return int64(length) * int64(width) * int64(height)
+}
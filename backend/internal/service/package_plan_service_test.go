@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func TestPackagePlanUsesInventoryOverridesAndSplitsParcels(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Product{}, &models.Inventory{})
+	cfg := &config.Config{}
+	cfg.Order.PackageBoxes = []config.PackageBox{
+		{Code: "L", Name: "Large", LengthMM: 400, WidthMM: 300, HeightMM: 200, MaxWeightGrams: 5000, TareGrams: 300},
+		{Code: "S", Name: "Small", LengthMM: 200, WidthMM: 150, HeightMM: 100, MaxWeightGrams: 2000, TareGrams: 100},
+	}
+	svc := NewPackagePlanService(db, cfg)
+
+	products := []*models.Product{
+		{SKU: "MUG", Name: "Mug", WeightGrams: 400, LengthMM: 120, WidthMM: 100, HeightMM: 100},
+		{SKU: "POSTER", Name: "Poster", WeightGrams: 200, LengthMM: 600, WidthMM: 80, HeightMM: 80},
+	}
+	for _, product := range products {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("create product failed: %v", err)
+		}
+	}
+	heavyVariant := &models.Inventory{Name: "Mug XL", WeightGrams: 900}
+	if err := db.Create(heavyVariant).Error; err != nil {
+		t.Fatalf("create inventory failed: %v", err)
+	}
+
+	order := &models.Order{
+		Items: []models.OrderItem{
+			{SKU: "MUG", Name: "Mug", Quantity: 1},
+			{SKU: "MUG", Name: "Mug XL", Quantity: 2},
+			{SKU: "POSTER", Name: "Poster", Quantity: 1},
+			{SKU: "CODE", Name: "Gift code", Quantity: 1, ProductType: models.ProductTypeVirtual},
+		},
+		InventoryBindings: map[int]uint{1: heavyVariant.ID},
+	}
+	weight, err := ResolveOrderWeightGrams(db, order)
+	if err != nil || weight != 400+1800+200 {
+		t.Fatalf("unexpected order weight %d, %v", weight, err)
+	}
+
+	plan, err := svc.Plan(order)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if len(plan.UnpackableSKUs) != 1 || plan.UnpackableSKUs[0] != "POSTER" {
+		t.Fatalf("expected poster to exceed every box, got %v", plan.UnpackableSKUs)
+	}
+	packed := 0
+	for _, parcel := range plan.Parcels {
+		if parcel.BoxCode != "S" {
+			t.Fatalf("expected each parcel to be downsized to the small box, got %+v", parcel)
+		}
+		for _, item := range parcel.Items {
+			packed += item.Quantity
+		}
+	}
+	// 小箱承重 2000g：1 个 400g + 2 个 900g 需要两个小箱
+	if len(plan.Parcels) != 2 || packed != 3 {
+		t.Fatalf("unexpected parcels: %+v", plan.Parcels)
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPaymentAmountDecimals     = 6
+	defaultPaymentAmountBaseDecimals = 2
+	defaultPaymentAmountReserveTTL   = 24 * time.Hour
+	paymentAmountReserveMaxAttempts  = 3
+)
+
+// PaymentAmountReserveOptions 唯一付款金额分配参数
+type PaymentAmountReserveOptions struct {
+	Scope        string  // 唯一性范围，如 "usdt-trc20:<收款地址>"；为空时按付款方式隔离
+	Amount       float64 // 基础金额，按 BaseDecimals 向下取整后叠加尾数
+	Decimals     int     // 最终金额精度，默认 6
+	BaseDecimals int     // 基础金额保留的小数位，默认 2，尾数占用其余位数
+}
+
+// PaymentAmountReservationResult 分配结果
+type PaymentAmountReservationResult struct {
+	Amount      string    `json:"amount"`
+	AmountUnits int64     `json:"amount_units"`
+	Offset      int64     `json:"offset"`
+	Decimals    int       `json:"decimals"`
+	Scope       string    `json:"scope"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func newPaymentAmountReservationResult(r *models.PaymentAmountReservation) *PaymentAmountReservationResult {
+	return &PaymentAmountReservationResult{
+		Amount:      formatPaymentAmountUnits(r.AmountUnits, r.Decimals),
+		AmountUnits: r.AmountUnits,
+		Offset:      r.Offset,
+		Decimals:    r.Decimals,
+		Scope:       r.Scope,
+		ExpiresAt:   r.ExpiresAt,
+	}
+}
+
+func formatPaymentAmountUnits(units int64, decimals int) string {
+	if decimals <= 0 {
+		return fmt.Sprintf("%d", units)
+	}
+	scale := int64(math.Pow10(decimals))
+	return fmt.Sprintf("%d.%0*d", units/scale, decimals, units%scale)
+}
+
+// normalize 校验参数并计算基础金额单位与可用尾数个数
+func (o PaymentAmountReserveOptions) normalize(paymentMethodID uint) (PaymentAmountReserveOptions, int64, int64, error) {
+	if o.Decimals == 0 {
+		o.Decimals = defaultPaymentAmountDecimals
+	}
+	if o.BaseDecimals == 0 {
+		o.BaseDecimals = defaultPaymentAmountBaseDecimals
+	}
+	if o.Decimals > 12 || o.BaseDecimals < 0 || o.BaseDecimals >= o.Decimals {
+		return o, 0, 0, fmt.Errorf("decimals must be greater than base decimals and at most 12")
+	}
+	if o.Amount <= 0 || math.IsNaN(o.Amount) || math.IsInf(o.Amount, 0) {
+		return o, 0, 0, fmt.Errorf("amount must be positive")
+	}
+	o.Scope = strings.TrimSpace(o.Scope)
+	if o.Scope == "" {
+		o.Scope = fmt.Sprintf("pm:%d", paymentMethodID)
+	}
+	if len(o.Scope) > 191 {
+		return o, 0, 0, fmt.Errorf("scope is too long")
+	}
+
+	offsetSlots := int64(math.Pow10(o.Decimals - o.BaseDecimals))
+	// 加入极小容差，避免 12.34*100 = 1233.999... 被向下取整
+	baseUnits := int64(math.Floor(o.Amount*math.Pow10(o.BaseDecimals)+1e-9)) * offsetSlots
+	return o, baseUnits, offsetSlots, nil
+}
+
+// paymentAmountReservationExpiry 跟随订单付款截止时间，无截止时间时默认保留 24 小时
+func paymentAmountReservationExpiry(order *models.Order, now time.Time) time.Time {
+	if order != nil && order.PaymentDeadlineAt != nil && order.PaymentDeadlineAt.After(now) {
+		return *order.PaymentDeadlineAt
+	}
+	return now.Add(defaultPaymentAmountReserveTTL)
+}
+
+// ReservePaymentAmount 为待付款订单分配在 scope 内唯一的付款金额（基础金额 + 尾数）。
+// 同一订单与付款方式重复调用返回已分配的金额，避免重新报价导致应付金额变化。
+func ReservePaymentAmount(db *gorm.DB, order *models.Order, paymentMethodID uint, options PaymentAmountReserveOptions) (*PaymentAmountReservationResult, error) {
+	if order == nil || order.ID == 0 || paymentMethodID == 0 {
+		return nil, fmt.Errorf("order and payment method are required")
+	}
+	if order.Status != models.OrderStatusPendingPayment {
+		return nil, bizerr.Newf("payment.amountReserveInvalidOrderStatus", "Order status %s does not accept payment", order.Status).
+			WithParams(map[string]interface{}{"status": order.Status})
+	}
+	options, baseUnits, offsetSlots, err := options.normalize(paymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reservation *models.PaymentAmountReservation
+	for attempt := 0; attempt < paymentAmountReserveMaxAttempts; attempt++ {
+		reservation, err = reservePaymentAmountOnce(db, order, paymentMethodID, options, baseUnits, offsetSlots)
+		if err == nil || !isUniqueConstraintError(err) {
+			break
+		}
+	}
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return nil, newPaymentAmountOffsetExhaustedError(options.Scope)
+		}
+		return nil, err
+	}
+	return newPaymentAmountReservationResult(reservation), nil
+}
+
+func newPaymentAmountOffsetExhaustedError(scope string) error {
+	return bizerr.New("payment.amountOffsetExhausted", "Too many pending payments with the same amount, please try again later").
+		WithParams(map[string]interface{}{"scope": scope})
+}
+
+func reservePaymentAmountOnce(db *gorm.DB, order *models.Order, paymentMethodID uint, options PaymentAmountReserveOptions, baseUnits, offsetSlots int64) (*models.PaymentAmountReservation, error) {
+	now := models.NowFunc()
+	var reservation *models.PaymentAmountReservation
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("scope = ? AND expires_at < ?", options.Scope, now).
+			Delete(&models.PaymentAmountReservation{}).Error; err != nil {
+			return err
+		}
+
+		var existing models.PaymentAmountReservation
+		err := tx.Where("order_id = ? AND payment_method_id = ?", order.ID, paymentMethodID).First(&existing).Error
+		switch {
+		case err == nil && existing.Scope == options.Scope && existing.Decimals == options.Decimals && existing.ExpiresAt.After(now):
+			reservation = &existing
+			return nil
+		case err == nil:
+			if err := tx.Delete(&existing).Error; err != nil {
+				return err
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		var used []int64
+		if err := tx.Model(&models.PaymentAmountReservation{}).
+			Where("scope = ? AND amount_units >= ? AND amount_units < ?", options.Scope, baseUnits, baseUnits+offsetSlots).
+			Pluck("amount_units", &used).Error; err != nil {
+			return err
+		}
+		usedSet := make(map[int64]bool, len(used))
+		for _, units := range used {
+			usedSet[units-baseUnits] = true
+		}
+
+		// 从按订单 ID 计算的尾数开始查找空闲位置，保持与旧脚本相同的分布
+		preferred := int64(order.ID) % offsetSlots
+		for i := int64(0); i < offsetSlots; i++ {
+			offset := (preferred + i) % offsetSlots
+			if usedSet[offset] {
+				continue
+			}
+			reservation = &models.PaymentAmountReservation{
+				OrderID:         order.ID,
+				PaymentMethodID: paymentMethodID,
+				Scope:           options.Scope,
+				AmountUnits:     baseUnits + offset,
+				Decimals:        options.Decimals,
+				Offset:          offset,
+				ExpiresAt:       paymentAmountReservationExpiry(order, now),
+			}
+			return tx.Create(reservation).Error
+		}
+		return newPaymentAmountOffsetExhaustedError(options.Scope)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// ReleasePaymentAmountReservationsTx 释放订单占用的全部付款金额（订单取消或确认付款时调用）
+func ReleasePaymentAmountReservationsTx(tx *gorm.DB, orderID uint) error {
+	return tx.Where("order_id = ?", orderID).Delete(&models.PaymentAmountReservation{}).Error
+}
+
+// releaseOtherPaymentAmountReservationsTx 切换付款方式时释放其他付款方式占用的金额
+func releaseOtherPaymentAmountReservationsTx(tx *gorm.DB, orderID, paymentMethodID uint) error {
+	return tx.Where("order_id = ? AND payment_method_id <> ?", orderID, paymentMethodID).
+		Delete(&models.PaymentAmountReservation{}).Error
+}
+
+// PreviewPaymentAmount 按订单 ID 计算尾数但不占用，用于脚本测试
+func PreviewPaymentAmount(order *models.Order, paymentMethodID uint, options PaymentAmountReserveOptions) (*PaymentAmountReservationResult, error) {
+	options, baseUnits, offsetSlots, err := options.normalize(paymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+	var orderID uint
+	if order != nil {
+		orderID = order.ID
+	}
+	now := models.NowFunc()
+	offset := int64(orderID) % offsetSlots
+	return newPaymentAmountReservationResult(&models.PaymentAmountReservation{
+		OrderID:         orderID,
+		PaymentMethodID: paymentMethodID,
+		Scope:           options.Scope,
+		AmountUnits:     baseUnits + offset,
+		Decimals:        options.Decimals,
+		Offset:          offset,
+		ExpiresAt:       paymentAmountReservationExpiry(order, now),
+	}), nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+func newPaymentAmountReservationTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	return openConcurrentServiceTestDB(t, &models.Order{}, &models.OrderNote{})
+}
+
+func createPendingPaymentTestOrder(t *testing.T, db *gorm.DB, orderNo string) *models.Order {
+	t.Helper()
+	order := &models.Order{OrderNo: orderNo, Status: models.OrderStatusPendingPayment, Currency: "CNY", TotalAmount: 10000}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	return order
+}
+
+func TestReservePaymentAmountIsUniqueWithinScopeAndIdempotent(t *testing.T) {
+	db := newPaymentAmountReservationTestDB(t)
+	first := createPendingPaymentTestOrder(t, db, "PAR-1")
+	second := createPendingPaymentTestOrder(t, db, "PAR-2")
+	options := PaymentAmountReserveOptions{Scope: "usdt-trc20:TAddr", Amount: 13.889}
+
+	a, err := ReservePaymentAmount(db, first, 1, options)
+	if err != nil {
+		t.Fatalf("reserve first: %v", err)
+	}
+	if a.Amount != fmt.Sprintf("13.88%04d", a.Offset) || a.Decimals != 6 {
+		t.Fatalf("unexpected amount: %+v", a)
+	}
+
+	// 不同付款方式共用同一收款地址时不能得到相同金额
+	b, err := ReservePaymentAmount(db, second, 2, options)
+	if err != nil {
+		t.Fatalf("reserve second: %v", err)
+	}
+	if a.AmountUnits == b.AmountUnits {
+		t.Fatalf("expected unique amounts across payment methods, got %s twice", a.Amount)
+	}
+
+	// 重新报价沿用已分配的金额
+	again, err := ReservePaymentAmount(db, first, 1, options)
+	if err != nil || again.AmountUnits != a.AmountUnits {
+		t.Fatalf("expected idempotent reservation %s, got %+v err=%v", a.Amount, again, err)
+	}
+
+	var count int64
+	db.Model(&models.PaymentAmountReservation{}).Count(&count)
+	if count != 2 {
+		t.Fatalf("expected 2 reservations, got %d", count)
+	}
+}
+
+func TestReservePaymentAmountReportsExhaustion(t *testing.T) {
+	db := newPaymentAmountReservationTestDB(t)
+	options := PaymentAmountReserveOptions{Scope: "small", Amount: 1, Decimals: 3}
+
+	for i := 0; i < 10; i++ {
+		order := createPendingPaymentTestOrder(t, db, fmt.Sprintf("PAR-EX-%d", i))
+		if _, err := ReservePaymentAmount(db, order, 1, options); err != nil {
+			t.Fatalf("reserve %d: %v", i, err)
+		}
+	}
+	order := createPendingPaymentTestOrder(t, db, "PAR-EX-LAST")
+	_, err := ReservePaymentAmount(db, order, 1, options)
+	var bizErr *bizerr.Error
+	if !errors.As(err, &bizErr) || bizErr.Key != "payment.amountOffsetExhausted" {
+		t.Fatalf("expected offset exhausted error, got %v", err)
+	}
+
+	// 已过期的占用可被回收
+	db.Model(&models.PaymentAmountReservation{}).Where("1 = 1").Update("expires_at", models.NowFunc().Add(-1))
+	if _, err := ReservePaymentAmount(db, order, 1, options); err != nil {
+		t.Fatalf("expected expired reservations to be reclaimed, got %v", err)
+	}
+}
+
+func TestReservePaymentAmountRejectsNonPendingOrder(t *testing.T) {
+	db := newPaymentAmountReservationTestDB(t)
+	order := createPendingPaymentTestOrder(t, db, "PAR-PAID")
+	order.Status = models.OrderStatusPending
+
+	_, err := ReservePaymentAmount(db, order, 1, PaymentAmountReserveOptions{Amount: 1})
+	var bizErr *bizerr.Error
+	if !errors.As(err, &bizErr) || bizErr.Key != "payment.amountReserveInvalidOrderStatus" {
+		t.Fatalf("expected invalid order status error, got %v", err)
+	}
+}
+
+func TestCancelOrderReleasesPaymentAmountReservation(t *testing.T) {
+	db := newPaymentAmountReservationTestDB(t)
+	svc := newConcurrentOrderService(db, &config.Config{}, nil)
+	order := createPendingPaymentTestOrder(t, db, "PAR-CANCEL")
+
+	if _, err := ReservePaymentAmount(db, order, 1, PaymentAmountReserveOptions{Amount: 5}); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+	if err := svc.CancelOrder(order.ID, "test cancel"); err != nil {
+		t.Fatalf("cancel order: %v", err)
+	}
+
+	var count int64
+	db.Model(&models.PaymentAmountReservation{}).Where("order_id = ?", order.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected reservation to be released on cancel, got %d", count)
+	}
+}
+
+func TestSimulatedPaymentReleasesPaymentAmountReservation(t *testing.T) {
+	svc, db := newPaymentPollingServiceTestDB(t)
+	if err := db.AutoMigrate(&models.Product{}, &models.UserPurchaseStat{}, &models.LedgerEntry{}, &models.PaymentAmountReservation{}, &models.OrderNote{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	order, pm := createSandboxTestOrder(t, svc, "PAR-PAID-1", true)
+
+	if _, err := ReservePaymentAmount(db, order, pm.ID, PaymentAmountReserveOptions{Amount: 1}); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+	if _, err := svc.SimulateSandboxPayment(order.ID, nil); err != nil {
+		t.Fatalf("simulate payment: %v", err)
+	}
+
+	var count int64
+	db.Model(&models.PaymentAmountReservation{}).Where("order_id = ?", order.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected reservation to be released once paid, got %d", count)
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 连续失败（网络错误/超时/5xx）达到阈值后熔断，冷却结束后放行一次探测请求
+	paymentHTTPBreakerFailureThreshold = 5
+	paymentHTTPBreakerCooldown         = 60 * time.Second
+)
+
+// PaymentHTTPBreakerStatus 付款方式出站 HTTP 熔断状态（按付款方式 + 目标主机）
+type PaymentHTTPBreakerStatus struct {
+	PaymentMethodID     uint       `json:"payment_method_id"`
+	Host                string     `json:"host"`
+	State               string     `json:"state"` // closed / open / half_open
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	FailureThreshold    int        `json:"failure_threshold"`
+	OpenUntil           *time.Time `json:"open_until,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	ProbeInFlight       bool       `json:"probe_in_flight"`
+	TripCount           int        `json:"trip_count"`
+}
+
+type paymentHTTPBreakerKey struct {
+	PaymentMethodID uint
+	Host            string
+}
+
+type paymentHTTPBreakerEntry struct {
+	ConsecutiveFailures int
+	LastFailureAt       time.Time
+	LastError           string
+	OpenUntil           time.Time
+	ProbeInFlight       bool
+	TripCount           int
+}
+
+type paymentHTTPBreakerRegistry struct {
+	mu               sync.Mutex
+	entries          map[paymentHTTPBreakerKey]*paymentHTTPBreakerEntry
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+}
+
+var paymentHTTPBreakers = newPaymentHTTPBreakerRegistry()
+
+func newPaymentHTTPBreakerRegistry() *paymentHTTPBreakerRegistry {
+	return &paymentHTTPBreakerRegistry{
+		entries:          make(map[paymentHTTPBreakerKey]*paymentHTTPBreakerEntry),
+		failureThreshold: paymentHTTPBreakerFailureThreshold,
+		cooldown:         paymentHTTPBreakerCooldown,
+		now:              time.Now,
+	}
+}
+
+func paymentHTTPBreakerKeyFor(paymentMethodID uint, host string) paymentHTTPBreakerKey {
+	return paymentHTTPBreakerKey{PaymentMethodID: paymentMethodID, Host: strings.ToLower(strings.TrimSpace(host))}
+}
+
+func (r *paymentHTTPBreakerRegistry) stateLocked(entry *paymentHTTPBreakerEntry, now time.Time) string {
+	if entry == nil || entry.OpenUntil.IsZero() {
+		return pluginBreakerStateClosed
+	}
+	if now.Before(entry.OpenUntil) {
+		return pluginBreakerStateOpen
+	}
+	return pluginBreakerStateHalfOpen
+}
+
+// acquire 熔断打开时立即返回错误；半开状态仅放行一个探测请求
+func (r *paymentHTTPBreakerRegistry) acquire(paymentMethodID uint, host string) (bool, error) {
+	key := paymentHTTPBreakerKeyFor(paymentMethodID, host)
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry := r.entries[key]
+	switch r.stateLocked(entry, now) {
+	case pluginBreakerStateOpen:
+		retryAfter := entry.OpenUntil.Sub(now).Round(time.Second)
+		return false, fmt.Errorf("circuit breaker open for %s after %d consecutive failures, retry after %s", key.Host, entry.ConsecutiveFailures, retryAfter)
+	case pluginBreakerStateHalfOpen:
+		if entry.ProbeInFlight {
+			return false, fmt.Errorf("circuit breaker half-open for %s, probe request in progress", key.Host)
+		}
+		entry.ProbeInFlight = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// complete 记录请求结果：成功即关闭熔断，失败累计到阈值或探测失败时重新打开
+func (r *paymentHTTPBreakerRegistry) complete(paymentMethodID uint, host string, probe bool, failure error) {
+	key := paymentHTTPBreakerKeyFor(paymentMethodID, host)
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry := r.entries[key]
+	if failure == nil {
+		if entry != nil {
+			delete(r.entries, key)
+		}
+		return
+	}
+	if entry == nil {
+		entry = &paymentHTTPBreakerEntry{}
+		r.entries[key] = entry
+	}
+	if probe {
+		entry.ProbeInFlight = false
+	}
+	entry.ConsecutiveFailures++
+	entry.LastFailureAt = now
+	entry.LastError = failure.Error()
+	if probe || entry.ConsecutiveFailures >= r.failureThreshold {
+		if entry.OpenUntil.IsZero() || probe {
+			entry.TripCount++
+		}
+		entry.OpenUntil = now.Add(r.cooldown)
+	}
+}
+
+// list 返回熔断状态，paymentMethodID 为 0 时返回全部
+func (r *paymentHTTPBreakerRegistry) list(paymentMethodID uint) []PaymentHTTPBreakerStatus {
+	now := r.now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]PaymentHTTPBreakerStatus, 0, len(r.entries))
+	for key, entry := range r.entries {
+		if paymentMethodID != 0 && key.PaymentMethodID != paymentMethodID {
+			continue
+		}
+		item := PaymentHTTPBreakerStatus{
+			PaymentMethodID:     key.PaymentMethodID,
+			Host:                key.Host,
+			State:               r.stateLocked(entry, now),
+			ConsecutiveFailures: entry.ConsecutiveFailures,
+			FailureThreshold:    r.failureThreshold,
+			LastError:           entry.LastError,
+			ProbeInFlight:       entry.ProbeInFlight,
+			TripCount:           entry.TripCount,
+		}
+		if !entry.OpenUntil.IsZero() {
+			openUntil := entry.OpenUntil.UTC()
+			item.OpenUntil = &openUntil
+		}
+		if !entry.LastFailureAt.IsZero() {
+			lastFailureAt := entry.LastFailureAt.UTC()
+			item.LastFailureAt = &lastFailureAt
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].PaymentMethodID != items[j].PaymentMethodID {
+			return items[i].PaymentMethodID < items[j].PaymentMethodID
+		}
+		return items[i].Host < items[j].Host
+	})
+	return items
+}
+
+// reset 手动关闭熔断（如确认网关已恢复），返回清除的条目数
+func (r *paymentHTTPBreakerRegistry) reset(paymentMethodID uint) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	removed := 0
+	for key := range r.entries {
+		if key.PaymentMethodID == paymentMethodID {
+			delete(r.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// ListPaymentHTTPBreakers 当前进程内的付款方式出站 HTTP 熔断状态
+func ListPaymentHTTPBreakers(paymentMethodID uint) []PaymentHTTPBreakerStatus {
+	return paymentHTTPBreakers.list(paymentMethodID)
+}
+
+// ResetPaymentHTTPBreakers 重置指定付款方式的全部熔断
+func ResetPaymentHTTPBreakers(paymentMethodID uint) int {
+	return paymentHTTPBreakers.reset(paymentMethodID)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newPaymentHTTPBreakerTestRegistry(now *time.Time) *paymentHTTPBreakerRegistry {
+	registry := newPaymentHTTPBreakerRegistry()
+	registry.failureThreshold = 3
+	registry.cooldown = 30 * time.Second
+	registry.now = func() time.Time { return *now }
+	return registry
+}
+
+func TestPaymentHTTPBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	registry := newPaymentHTTPBreakerTestRegistry(&now)
+	failure := errors.New("dial tcp: i/o timeout")
+
+	for i := 0; i < 3; i++ {
+		probe, err := registry.acquire(1, "api.gateway.test")
+		if err != nil || probe {
+			t.Fatalf("attempt %d: expected closed breaker, got probe=%v err=%v", i, probe, err)
+		}
+		registry.complete(1, "api.gateway.test", probe, failure)
+	}
+
+	if _, err := registry.acquire(1, "API.Gateway.test"); err == nil {
+		t.Fatalf("expected breaker to fail fast once threshold is reached")
+	}
+	if _, err := registry.acquire(2, "api.gateway.test"); err != nil {
+		t.Fatalf("expected other payment methods to be unaffected, got %v", err)
+	}
+	if _, err := registry.acquire(1, "other.gateway.test"); err != nil {
+		t.Fatalf("expected other hosts to be unaffected, got %v", err)
+	}
+
+	statuses := registry.list(1)
+	if len(statuses) != 1 || statuses[0].State != pluginBreakerStateOpen || statuses[0].ConsecutiveFailures != 3 {
+		t.Fatalf("unexpected breaker status: %+v", statuses)
+	}
+}
+
+func TestPaymentHTTPBreakerHalfOpenProbe(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	registry := newPaymentHTTPBreakerTestRegistry(&now)
+	failure := errors.New("upstream returned status 503")
+	for i := 0; i < 3; i++ {
+		registry.complete(1, "pay.test", false, failure)
+	}
+
+	now = now.Add(31 * time.Second)
+	probe, err := registry.acquire(1, "pay.test")
+	if err != nil || !probe {
+		t.Fatalf("expected a half-open probe after cooldown, got probe=%v err=%v", probe, err)
+	}
+	if _, err := registry.acquire(1, "pay.test"); err == nil {
+		t.Fatalf("expected concurrent requests to fail fast while the probe is in flight")
+	}
+
+	// 探测失败重新打开
+	registry.complete(1, "pay.test", true, failure)
+	if _, err := registry.acquire(1, "pay.test"); err == nil {
+		t.Fatalf("expected breaker to reopen after a failed probe")
+	}
+	if status := registry.list(1)[0]; status.TripCount != 2 {
+		t.Fatalf("expected trip count 2, got %d", status.TripCount)
+	}
+
+	// 探测成功关闭
+	now = now.Add(31 * time.Second)
+	probe, err = registry.acquire(1, "pay.test")
+	if err != nil || !probe {
+		t.Fatalf("expected a second probe, got probe=%v err=%v", probe, err)
+	}
+	registry.complete(1, "pay.test", true, nil)
+	if len(registry.list(0)) != 0 {
+		t.Fatalf("expected breaker entry to be cleared after a successful probe")
+	}
+}
+
+func TestPaymentHTTPBreakerReset(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	registry := newPaymentHTTPBreakerTestRegistry(&now)
+	for i := 0; i < 3; i++ {
+		registry.complete(1, "a.test", false, errors.New("boom"))
+		registry.complete(2, "b.test", false, errors.New("boom"))
+	}
+
+	if removed := registry.reset(1); removed != 1 {
+		t.Fatalf("expected one entry reset, got %d", removed)
+	}
+	if _, err := registry.acquire(1, "a.test"); err != nil {
+		t.Fatalf("expected reset breaker to allow requests, got %v", err)
+	}
+	if _, err := registry.acquire(2, "b.test"); err == nil {
+		t.Fatalf("expected other payment method breaker to stay open")
+	}
+}
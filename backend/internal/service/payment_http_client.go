@@ -3,6 +3,9 @@ package service
 import (
 	"net/http"
 	"sync"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
 )
 
 var (
@@ -16,3 +19,22 @@ func getPaymentHTTPClient() *http.Client {
 	})
 	return paymentHTTPClient
 }
+
+func paymentHTTPStrictAllowlist() bool {
+	if cfg := config.GetConfig(); cfg != nil {
+		return cfg.Security.PaymentHTTPStrictAllowlist
+	}
+	return false
+}
+
+// paymentHTTPHostAllowed 付款脚本出站主机白名单校验
+// 脚本测试（无付款方式 ID）不受限制；未配置白名单时仅在严格模式下拒绝
+func paymentHTTPHostAllowed(pm *models.PaymentMethod, host string) bool {
+	if pm == nil || pm.ID == 0 {
+		return true
+	}
+	if len(pm.AllowedHosts) == 0 {
+		return !paymentHTTPStrictAllowlist()
+	}
+	return pm.AllowsHost(host)
+}
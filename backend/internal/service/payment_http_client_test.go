@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestNormalizePaymentMethodAllowedHosts(t *testing.T) {
+	hosts, err := models.NormalizePaymentMethodAllowedHosts([]string{" API.Stripe.com ", "*.paypal.com", "api.stripe.com", ""})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if len(hosts) != 2 || hosts[0] != "api.stripe.com" || hosts[1] != "*.paypal.com" {
+		t.Fatalf("unexpected normalized hosts: %v", hosts)
+	}
+
+	for _, invalid := range []string{"https://api.stripe.com", "api.stripe.com:443", "api.*.com", "*.", "*.10.0.0.1"} {
+		if _, err := models.NormalizePaymentMethodAllowedHosts([]string{invalid}); err == nil {
+			t.Fatalf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestPaymentHTTPHostAllowed(t *testing.T) {
+	pm := &models.PaymentMethod{ID: 1, AllowedHosts: []string{"api.stripe.com", "*.paypal.com"}}
+
+	cases := map[string]bool{
+		"api.stripe.com":     true,
+		"API.STRIPE.COM":     true,
+		"evil.stripe.com":    false,
+		"api-m.paypal.com":   true,
+		"paypal.com":         false,
+		"paypal.com.evil.io": false,
+	}
+	for host, want := range cases {
+		if got := paymentHTTPHostAllowed(pm, host); got != want {
+			t.Fatalf("host %q: expected %v, got %v", host, want, got)
+		}
+	}
+
+	// 未配置白名单且非严格模式时放行；脚本测试不受白名单限制
+	if !paymentHTTPHostAllowed(&models.PaymentMethod{ID: 2}, "any.example.com") {
+		t.Fatalf("expected empty allowlist to allow requests outside strict mode")
+	}
+	if !paymentHTTPHostAllowed(&models.PaymentMethod{AllowedHosts: []string{"api.stripe.com"}}, "any.example.com") {
+		t.Fatalf("expected test scripts without payment method id to bypass the allowlist")
+	}
+}
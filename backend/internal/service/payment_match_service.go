@@ -0,0 +1,536 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// PaymentSourceManualMatch 人工对账确认收款的记账来源
+	PaymentSourceManualMatch = "manual_match"
+
+	paymentMatchImportMaxRows       = 5000
+	paymentMatchImportMaxRowErrors  = 200
+	paymentMatchCandidateOrderLimit = 500
+	paymentMatchDefaultSuggestions  = 10
+)
+
+// 到账流水导入表头（规范化后）到字段的映射
+var paymentMatchImportHeaderAliases = map[string]string{
+	"transactionid": "transaction_id", "txid": "transaction_id", "txhash": "transaction_id", "reference": "transaction_id", "交易号": "transaction_id", "流水号": "transaction_id",
+	"amount": "amount", "金额": "amount",
+	"currency": "currency", "币种": "currency",
+	"memo": "memo", "remark": "memo", "附言": "memo", "备注": "memo",
+	"payer": "payer", "from": "payer", "付款人": "payer", "付款账户": "payer",
+	"receivedat": "received_at", "time": "received_at", "date": "received_at", "到账时间": "received_at",
+	"channel": "channel", "渠道": "channel",
+}
+
+// PaymentMatchImportTemplateHeaders 到账流水导入模板表头
+var PaymentMatchImportTemplateHeaders = []string{"Transaction ID", "Amount", "Currency", "Memo", "Payer", "Received At", "Channel"}
+
+var paymentMatchTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02", "2006/01/02 15:04:05", "2006/01/02"}
+
+// IncomingTransactionInput 录入到账流水
+type IncomingTransactionInput struct {
+	TransactionID   string     `json:"transaction_id"`
+	Channel         string     `json:"channel"`
+	PaymentMethodID *uint      `json:"payment_method_id"`
+	Amount          string     `json:"amount"`
+	Currency        string     `json:"currency"`
+	Memo            string     `json:"memo"`
+	Payer           string     `json:"payer"`
+	ReceivedAt      *time.Time `json:"received_at"`
+}
+
+// PaymentMatchImportError 导入行级错误
+type PaymentMatchImportError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// PaymentMatchImportResult 导入结果
+type PaymentMatchImportResult struct {
+	Created    int                       `json:"created"`
+	Duplicates int                       `json:"duplicates"`
+	Errors     []PaymentMatchImportError `json:"errors"`
+}
+
+// PaymentMatchOrder 待付款订单摘要
+type PaymentMatchOrder struct {
+	ID                uint       `json:"id"`
+	OrderNo           string     `json:"order_no"`
+	Currency          string     `json:"currency"`
+	TotalAmount       int64      `json:"total_amount_minor"`
+	PaymentMethodID   uint       `json:"payment_method_id,omitempty"`
+	ReservedAmount    string     `json:"reserved_amount,omitempty"`
+	PaymentDeadlineAt *time.Time `json:"payment_deadline_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// PaymentMatchSuggestion 匹配建议，Score 越高越可能是同一笔付款
+type PaymentMatchSuggestion struct {
+	Order   PaymentMatchOrder `json:"order"`
+	Score   int               `json:"score"`
+	Reasons []string          `json:"reasons"`
+}
+
+// PaymentMatchService 到账流水与待付款订单的人工对账
+type PaymentMatchService struct {
+	db           *gorm.DB
+	orderService *OrderService
+}
+
+func NewPaymentMatchService(db *gorm.DB, orderService *OrderService) *PaymentMatchService {
+	return &PaymentMatchService{db: db, orderService: orderService}
+}
+
+// normalizePaymentMatchAmount 去除千分位与空白，返回规范化十进制文本与数值
+func normalizePaymentMatchAmount(raw string) (string, float64, error) {
+	cleaned := strings.NewReplacer(",", "", " ", "", "\u00a0", "").Replace(strings.TrimSpace(raw))
+	value, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || value <= 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return "", 0, bizerr.New("payment_match.invalidAmount", "Amount must be a positive number")
+	}
+	return cleaned, value, nil
+}
+
+func truncatePaymentMatchField(value string, max int) string {
+	value = strings.TrimSpace(value)
+	if len(value) <= max {
+		return value
+	}
+	return strings.ToValidUTF8(value[:max], "")
+}
+
+func parsePaymentMatchTime(raw string) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range paymentMatchTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (s *PaymentMatchService) buildTransaction(input IncomingTransactionInput, source string, createdBy *uint) (*models.IncomingTransaction, error) {
+	transactionID := strings.TrimSpace(input.TransactionID)
+	if transactionID == "" {
+		return nil, bizerr.New("payment_match.transactionIdRequired", "Transaction ID is required")
+	}
+	if len(transactionID) > 191 {
+		return nil, bizerr.New("payment_match.transactionIdTooLong", "Transaction ID is too long")
+	}
+	amount, _, err := normalizePaymentMatchAmount(input.Amount)
+	if err != nil {
+		return nil, err
+	}
+	receivedAt := models.NowFunc()
+	if input.ReceivedAt != nil && !input.ReceivedAt.IsZero() {
+		receivedAt = *input.ReceivedAt
+	}
+	return &models.IncomingTransaction{
+		Channel:         truncatePaymentMatchField(input.Channel, 50),
+		TransactionID:   transactionID,
+		PaymentMethodID: input.PaymentMethodID,
+		Amount:          amount,
+		Currency:        strings.ToUpper(truncatePaymentMatchField(input.Currency, 10)),
+		Memo:            truncatePaymentMatchField(input.Memo, 500),
+		Payer:           truncatePaymentMatchField(input.Payer, 200),
+		ReceivedAt:      receivedAt,
+		Source:          source,
+		Status:          models.IncomingTransactionStatusUnmatched,
+		CreatedBy:       createdBy,
+	}, nil
+}
+
+// RecordTransaction 录入到账流水；相同渠道与交易号已存在时返回已有记录且 created 为 false
+func (s *PaymentMatchService) RecordTransaction(input IncomingTransactionInput, source string, createdBy *uint) (*models.IncomingTransaction, bool, error) {
+	txn, err := s.buildTransaction(input, source, createdBy)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.db.Create(txn).Error; err != nil {
+		if !isUniqueConstraintError(err) {
+			return nil, false, err
+		}
+		var existing models.IncomingTransaction
+		if findErr := s.db.Where("channel = ? AND transaction_id = ?", txn.Channel, txn.TransactionID).First(&existing).Error; findErr != nil {
+			return nil, false, findErr
+		}
+		return &existing, false, nil
+	}
+	return txn, true, nil
+}
+
+// ImportTransactions 从 CSV/XLSX 表格批量导入到账流水，首行为表头
+func (s *PaymentMatchService) ImportTransactions(table [][]string, createdBy *uint) (*PaymentMatchImportResult, error) {
+	if len(table) < 2 {
+		return nil, bizerr.New("payment_match.importEmpty", "Import file has no data rows")
+	}
+	if len(table)-1 > paymentMatchImportMaxRows {
+		return nil, bizerr.Newf("payment_match.importTooManyRows", "Import file exceeds %d rows", paymentMatchImportMaxRows).
+			WithParams(map[string]interface{}{"max": paymentMatchImportMaxRows})
+	}
+	headerMap := make(map[string]int)
+	for idx, raw := range table[0] {
+		if key := paymentMatchImportHeaderAliases[normalizeOrderImportHeader(raw)]; key != "" {
+			if _, exists := headerMap[key]; !exists {
+				headerMap[key] = idx
+			}
+		}
+	}
+	for _, required := range []string{"transaction_id", "amount"} {
+		if _, ok := headerMap[required]; !ok {
+			return nil, bizerr.Newf("payment_match.importMissingHeader", "Missing required header: %s", required).
+				WithParams(map[string]interface{}{"header": required})
+		}
+	}
+
+	result := &PaymentMatchImportResult{Errors: []PaymentMatchImportError{}}
+	for i, record := range table[1:] {
+		row := i + 2
+		field := func(key string) string {
+			idx, ok := headerMap[key]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		input := IncomingTransactionInput{
+			TransactionID: field("transaction_id"),
+			Channel:       field("channel"),
+			Amount:        field("amount"),
+			Currency:      field("currency"),
+			Memo:          field("memo"),
+			Payer:         field("payer"),
+		}
+		if raw := field("received_at"); raw != "" {
+			parsed, ok := parsePaymentMatchTime(raw)
+			if !ok {
+				result.appendError(row, fmt.Sprintf("invalid received_at: %s", raw))
+				continue
+			}
+			input.ReceivedAt = &parsed
+		}
+		_, created, err := s.RecordTransaction(input, models.IncomingTransactionSourceCSV, createdBy)
+		switch {
+		case err != nil:
+			result.appendError(row, err.Error())
+		case created:
+			result.Created++
+		default:
+			result.Duplicates++
+		}
+	}
+	return result, nil
+}
+
+func (r *PaymentMatchImportResult) appendError(row int, message string) {
+	if len(r.Errors) < paymentMatchImportMaxRowErrors {
+		r.Errors = append(r.Errors, PaymentMatchImportError{Row: row, Message: message})
+	}
+}
+
+// ListTransactions 到账流水列表，status 为空时返回全部
+func (s *PaymentMatchService) ListTransactions(status string, page, limit int) ([]models.IncomingTransaction, int64, error) {
+	query := s.db.Model(&models.IncomingTransaction{})
+	if status = strings.TrimSpace(status); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var txns []models.IncomingTransaction
+	err := query.Order("received_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&txns).Error
+	return txns, total, err
+}
+
+// ListPendingOrders 待付款订单列表，keyword 按订单号模糊搜索，scope 限定店铺范围
+func (s *PaymentMatchService) ListPendingOrders(keyword string, scope *repository.StoreScope, page, limit int) ([]PaymentMatchOrder, int64, error) {
+	query := scope.Apply(s.db.Model(&models.Order{})).Where("status = ?", models.OrderStatusPendingPayment)
+	if keyword = strings.TrimSpace(keyword); keyword != "" {
+		query = query.Where("order_no LIKE ?", "%"+keyword+"%")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var orders []models.Order
+	if err := query.Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+	summaries, err := s.buildMatchOrders(orders)
+	return summaries, total, err
+}
+
+func (s *PaymentMatchService) buildMatchOrders(orders []models.Order) ([]PaymentMatchOrder, error) {
+	summaries := make([]PaymentMatchOrder, 0, len(orders))
+	if len(orders) == 0 {
+		return summaries, nil
+	}
+	orderIDs := make([]uint, 0, len(orders))
+	for _, order := range orders {
+		orderIDs = append(orderIDs, order.ID)
+	}
+
+	var opms []models.OrderPaymentMethod
+	if err := s.db.Where("order_id IN ?", orderIDs).Find(&opms).Error; err != nil {
+		return nil, err
+	}
+	paymentMethods := make(map[uint]uint, len(opms))
+	for _, opm := range opms {
+		paymentMethods[opm.OrderID] = opm.PaymentMethodID
+	}
+	var reservations []models.PaymentAmountReservation
+	if err := s.db.Where("order_id IN ? AND expires_at > ?", orderIDs, models.NowFunc()).Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	reserved := make(map[uint]string, len(reservations))
+	for _, reservation := range reservations {
+		if reservation.PaymentMethodID == paymentMethods[reservation.OrderID] || reserved[reservation.OrderID] == "" {
+			reserved[reservation.OrderID] = formatPaymentAmountUnits(reservation.AmountUnits, reservation.Decimals)
+		}
+	}
+
+	for _, order := range orders {
+		summaries = append(summaries, PaymentMatchOrder{
+			ID:                order.ID,
+			OrderNo:           order.OrderNo,
+			Currency:          order.Currency,
+			TotalAmount:       order.TotalAmount,
+			PaymentMethodID:   paymentMethods[order.ID],
+			ReservedAmount:    reserved[order.ID],
+			PaymentDeadlineAt: order.PaymentDeadlineAt,
+			CreatedAt:         order.CreatedAt,
+		})
+	}
+	return summaries, nil
+}
+
+func (s *PaymentMatchService) getTransaction(id uint) (*models.IncomingTransaction, error) {
+	var txn models.IncomingTransaction
+	if err := s.db.First(&txn, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, bizerr.New("payment_match.transactionNotFound", "Transaction not found")
+		}
+		return nil, err
+	}
+	return &txn, nil
+}
+
+// SuggestMatches 按金额、预留唯一金额、附言中的订单号等线索为到账流水推荐待付款订单，scope 限定候选订单的店铺范围
+func (s *PaymentMatchService) SuggestMatches(id uint, scope *repository.StoreScope, limit int) ([]PaymentMatchSuggestion, error) {
+	txn, err := s.getTransaction(id)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = paymentMatchDefaultSuggestions
+	}
+	var orders []models.Order
+	if err := scope.Apply(s.db.Model(&models.Order{})).Where("status = ?", models.OrderStatusPendingPayment).
+		Order("created_at DESC, id DESC").
+		Limit(paymentMatchCandidateOrderLimit).
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	candidates, err := s.buildMatchOrders(orders)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]PaymentMatchSuggestion, 0)
+	for _, candidate := range candidates {
+		score, reasons := scorePaymentMatch(txn, candidate)
+		if score < 20 {
+			continue
+		}
+		suggestions = append(suggestions, PaymentMatchSuggestion{Order: candidate, Score: score, Reasons: reasons})
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// scorePaymentMatch 计算匹配分数与命中的线索
+func scorePaymentMatch(txn *models.IncomingTransaction, order PaymentMatchOrder) (int, []string) {
+	score := 0
+	reasons := make([]string, 0, 4)
+	_, amount, err := normalizePaymentMatchAmount(txn.Amount)
+	if err != nil {
+		return 0, nil
+	}
+
+	if order.ReservedAmount != "" {
+		if reserved, parseErr := strconv.ParseFloat(order.ReservedAmount, 64); parseErr == nil && math.Abs(reserved-amount) < 5e-7 {
+			score += 60
+			reasons = append(reasons, "reserved_amount")
+		}
+	}
+	if txn.Currency == "" || strings.EqualFold(txn.Currency, order.Currency) {
+		expected := float64(order.TotalAmount) / 100
+		diff := math.Abs(amount - expected)
+		switch {
+		case int64(math.Round(amount*100)) == order.TotalAmount:
+			score += 50
+			reasons = append(reasons, "exact_amount")
+		case expected > 0 && diff/expected <= 0.01:
+			score += 25
+			reasons = append(reasons, "close_amount")
+		case expected > 0 && diff/expected <= 0.05:
+			score += 10
+			reasons = append(reasons, "near_amount")
+		}
+	}
+
+	memo := strings.ToUpper(txn.Memo)
+	orderNo := strings.ToUpper(order.OrderNo)
+	if memo != "" && orderNo != "" {
+		if strings.Contains(memo, orderNo) {
+			score += 40
+			reasons = append(reasons, "memo_order_no")
+		} else if len(orderNo) > 6 && strings.Contains(memo, orderNo[len(orderNo)-6:]) {
+			score += 15
+			reasons = append(reasons, "memo_partial_order_no")
+		}
+	}
+	if txn.PaymentMethodID != nil && *txn.PaymentMethodID == order.PaymentMethodID {
+		score += 10
+		reasons = append(reasons, "payment_method")
+	}
+	// 订单创建晚于到账时间的不太可能是同一笔付款
+	if !txn.ReceivedAt.IsZero() && order.CreatedAt.After(txn.ReceivedAt) {
+		score -= 20
+	}
+	return score, reasons
+}
+
+// MatchTransaction 将到账流水匹配到待付款订单并标记已付款，订单付款数据记录交易号
+func (s *PaymentMatchService) MatchTransaction(id, orderID uint, operatorID *uint, remark string) (*models.IncomingTransaction, error) {
+	if s.orderService == nil {
+		return nil, fmt.Errorf("order service is unavailable")
+	}
+	txn, err := s.getTransaction(id)
+	if err != nil {
+		return nil, err
+	}
+	var order models.Order
+	if err := s.db.First(&order, orderID).Error; err != nil {
+		return nil, normalizeOrderLookupError(err)
+	}
+
+	// 先认领流水，避免同一笔款项被并发匹配到多个订单
+	now := models.NowFunc()
+	claim := s.db.Model(&models.IncomingTransaction{}).
+		Where("id = ? AND status = ?", txn.ID, models.IncomingTransactionStatusUnmatched).
+		Updates(map[string]interface{}{
+			"status":           models.IncomingTransactionStatusMatched,
+			"matched_order_id": order.ID,
+			"matched_order_no": order.OrderNo,
+			"handled_by":       operatorID,
+			"handled_at":       now,
+			"remark":           truncatePaymentMatchField(remark, 500),
+		})
+	if claim.Error != nil {
+		return nil, claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return nil, bizerr.Newf("payment_match.transactionNotUnmatched", "Transaction is already %s", txn.Status).
+			WithParams(map[string]interface{}{"status": txn.Status})
+	}
+
+	adminRemark := fmt.Sprintf("Matched incoming transaction %s (%s %s)", txn.TransactionID, txn.Amount, txn.Currency)
+	if remark = strings.TrimSpace(remark); remark != "" {
+		adminRemark += ": " + remark
+	}
+	if err := s.orderService.MarkAsPaidWithOptions(order.ID, MarkAsPaidOptions{
+		AdminRemark:   adminRemark,
+		OperatorID:    operatorID,
+		PaymentSource: PaymentSourceManualMatch,
+	}); err != nil {
+		if revertErr := s.db.Model(&models.IncomingTransaction{}).Where("id = ?", txn.ID).Updates(map[string]interface{}{
+			"status":           models.IncomingTransactionStatusUnmatched,
+			"matched_order_id": nil,
+			"matched_order_no": "",
+			"handled_by":       nil,
+			"handled_at":       nil,
+		}).Error; revertErr != nil {
+			return nil, fmt.Errorf("%w (revert transaction claim failed: %v)", err, revertErr)
+		}
+		return nil, err
+	}
+
+	if err := s.recordMatchedPaymentData(order.ID, txn); err != nil {
+		return nil, err
+	}
+	return s.getTransaction(txn.ID)
+}
+
+// recordMatchedPaymentData 将交易号写入订单付款数据，与自动确认的付款保持一致
+func (s *PaymentMatchService) recordMatchedPaymentData(orderID uint, txn *models.IncomingTransaction) error {
+	var opm models.OrderPaymentMethod
+	if err := s.db.Where("order_id = ?", orderID).First(&opm).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	data := map[string]interface{}{}
+	if strings.TrimSpace(opm.PaymentData) != "" {
+		_ = json.Unmarshal([]byte(opm.PaymentData), &data)
+	}
+	data["transaction_id"] = txn.TransactionID
+	data["paid_amount"] = txn.Amount
+	data["paid_currency"] = txn.Currency
+	data["paid_at"] = txn.ReceivedAt.Format(time.RFC3339)
+	data["source"] = PaymentSourceManualMatch
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.db.Model(&models.OrderPaymentMethod{}).Where("id = ?", opm.ID).Update("payment_data", string(encoded)).Error
+}
+
+// IgnoreTransaction 忽略与订单无关的到账流水（如退款、内部转账）
+func (s *PaymentMatchService) IgnoreTransaction(id uint, operatorID *uint, remark string) (*models.IncomingTransaction, error) {
+	txn, err := s.getTransaction(id)
+	if err != nil {
+		return nil, err
+	}
+	result := s.db.Model(&models.IncomingTransaction{}).
+		Where("id = ? AND status = ?", txn.ID, models.IncomingTransactionStatusUnmatched).
+		Updates(map[string]interface{}{
+			"status":     models.IncomingTransactionStatusIgnored,
+			"handled_by": operatorID,
+			"handled_at": models.NowFunc(),
+			"remark":     truncatePaymentMatchField(remark, 500),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, bizerr.Newf("payment_match.transactionNotUnmatched", "Transaction is already %s", txn.Status).
+			WithParams(map[string]interface{}{"status": txn.Status})
+	}
+	return s.getTransaction(txn.ID)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+)
+
+func newPaymentMatchTestService(t *testing.T) *PaymentMatchService {
+	t.Helper()
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.OrderNote{}, &models.OrderPaymentMethod{}, &models.IncomingTransaction{})
+	return NewPaymentMatchService(db, newConcurrentOrderService(db, &config.Config{}, nil))
+}
+
+func createPaymentMatchTestOrder(t *testing.T, svc *PaymentMatchService, orderNo string, totalAmount int64, createdAt time.Time) *models.Order {
+	t.Helper()
+	order := &models.Order{
+		OrderNo:     orderNo,
+		Status:      models.OrderStatusPendingPayment,
+		Currency:    "CNY",
+		TotalAmount: totalAmount,
+		CreatedAt:   createdAt,
+		Items: []models.OrderItem{{
+			SKU:         "SKU-1",
+			Name:        "Item 1",
+			Quantity:    1,
+			ProductType: models.ProductTypePhysical,
+		}},
+	}
+	if err := svc.db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	return order
+}
+
+func TestPaymentMatchRecordAndImportDeduplicateTransactions(t *testing.T) {
+	svc := newPaymentMatchTestService(t)
+
+	txn, created, err := svc.RecordTransaction(IncomingTransactionInput{TransactionID: "BANK-1", Channel: "bank", Amount: "1,990.00", Currency: "cny"}, models.IncomingTransactionSourceManual, nil)
+	if err != nil || !created {
+		t.Fatalf("record transaction: created=%v err=%v", created, err)
+	}
+	if txn.Amount != "1990.00" || txn.Currency != "CNY" || txn.Status != models.IncomingTransactionStatusUnmatched {
+		t.Fatalf("unexpected normalized transaction: %+v", txn)
+	}
+
+	result, err := svc.ImportTransactions([][]string{
+		{"Transaction ID", "Amount", "Memo", "Channel", "Received At"},
+		{"BANK-1", "1990.00", "", "bank", ""},
+		{"BANK-2", "50", "ORDER-2", "bank", "2026-01-02 10:00:00"},
+		{"BANK-3", "-1", "", "bank", ""},
+		{"BANK-4", "10", "", "bank", "yesterday"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Created != 1 || result.Duplicates != 1 || len(result.Errors) != 2 || result.Errors[0].Row != 4 {
+		t.Fatalf("unexpected import result: %+v", result)
+	}
+
+	if _, err := svc.ImportTransactions([][]string{{"Memo"}, {"x"}}, nil); err == nil {
+		t.Fatalf("expected missing header error")
+	}
+}
+
+func TestPaymentMatchSuggestsOrdersByAmountAndMemo(t *testing.T) {
+	svc := newPaymentMatchTestService(t)
+	receivedAt := time.Now()
+	exact := createPaymentMatchTestOrder(t, svc, "ORD-EXACT-000001", 19900, receivedAt.Add(-time.Hour))
+	memo := createPaymentMatchTestOrder(t, svc, "ORD-MEMO-000002", 20000, receivedAt.Add(-time.Hour))
+	createPaymentMatchTestOrder(t, svc, "ORD-OTHER-000003", 50000, receivedAt.Add(-time.Hour))
+
+	txn, _, err := svc.RecordTransaction(IncomingTransactionInput{
+		TransactionID: "BANK-10",
+		Amount:        "199",
+		Currency:      "CNY",
+		Memo:          "pay for ord-memo-000002",
+		ReceivedAt:    &receivedAt,
+	}, models.IncomingTransactionSourceManual, nil)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	suggestions, err := svc.SuggestMatches(txn.ID, nil, 0)
+	if err != nil {
+		t.Fatalf("suggest: %v", err)
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("expected exact and memo candidates only, got %+v", suggestions)
+	}
+	if suggestions[0].Order.ID != memo.ID || suggestions[1].Order.ID != exact.ID {
+		t.Fatalf("expected memo+close amount to outrank exact amount, got %+v", suggestions)
+	}
+}
+
+func TestPaymentMatchMarksOrderPaidOnce(t *testing.T) {
+	svc := newPaymentMatchTestService(t)
+	order := createPaymentMatchTestOrder(t, svc, "ORD-MATCH-1", 10000, time.Now().Add(-time.Hour))
+	other := createPaymentMatchTestOrder(t, svc, "ORD-MATCH-2", 10000, time.Now().Add(-time.Hour))
+	if err := svc.db.Create(&models.OrderPaymentMethod{OrderID: order.ID, PaymentMethodID: 1}).Error; err != nil {
+		t.Fatalf("create order payment method: %v", err)
+	}
+	txn, _, err := svc.RecordTransaction(IncomingTransactionInput{TransactionID: "0xabc", Channel: "usdt-trc20", Amount: "13.880042", Currency: "USDT"}, models.IncomingTransactionSourceManual, nil)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	operatorID := uint(9)
+	matched, err := svc.MatchTransaction(txn.ID, order.ID, &operatorID, "wrong amount")
+	if err != nil {
+		t.Fatalf("match: %v", err)
+	}
+	if matched.Status != models.IncomingTransactionStatusMatched || matched.MatchedOrderNo != order.OrderNo {
+		t.Fatalf("unexpected matched transaction: %+v", matched)
+	}
+
+	var paid models.Order
+	svc.db.First(&paid, order.ID)
+	if paid.Status == models.OrderStatusPendingPayment {
+		t.Fatalf("expected order to be marked paid")
+	}
+	var opm models.OrderPaymentMethod
+	svc.db.Where("order_id = ?", order.ID).First(&opm)
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(opm.PaymentData), &data); err != nil || data["transaction_id"] != "0xabc" {
+		t.Fatalf("expected transaction id in payment data, got %q err=%v", opm.PaymentData, err)
+	}
+
+	_, err = svc.MatchTransaction(txn.ID, other.ID, &operatorID, "")
+	var bizErr *bizerr.Error
+	if !errors.As(err, &bizErr) || bizErr.Key != "payment_match.transactionNotUnmatched" {
+		t.Fatalf("expected already matched error, got %v", err)
+	}
+}
+
+func TestPaymentMatchReleasesClaimWhenOrderCannotBePaid(t *testing.T) {
+	svc := newPaymentMatchTestService(t)
+	order := createPaymentMatchTestOrder(t, svc, "ORD-CANCELLED", 10000, time.Now())
+	svc.db.Model(order).Update("status", models.OrderStatusCancelled)
+	txn, _, _ := svc.RecordTransaction(IncomingTransactionInput{TransactionID: "BANK-20", Amount: "100"}, models.IncomingTransactionSourceManual, nil)
+
+	if _, err := svc.MatchTransaction(txn.ID, order.ID, nil, ""); err == nil {
+		t.Fatalf("expected cancelled order to be rejected")
+	}
+	var reloaded models.IncomingTransaction
+	svc.db.First(&reloaded, txn.ID)
+	if reloaded.Status != models.IncomingTransactionStatusUnmatched || reloaded.MatchedOrderID != nil {
+		t.Fatalf("expected claim to be reverted, got %+v", reloaded)
+	}
+
+	if ignored, err := svc.IgnoreTransaction(txn.ID, nil, "refund"); err != nil || ignored.Status != models.IncomingTransactionStatusIgnored {
+		t.Fatalf("ignore: %+v err=%v", ignored, err)
+	}
+}
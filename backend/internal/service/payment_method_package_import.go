@@ -41,6 +41,8 @@ type generatedPaymentMethodPackageDefinition struct {
 	Script        string
 	PackageName   string
 	EntryFileName string
+	// CashOnDelivery 内置货到付款方式：导入后标记 cash_on_delivery，首次创建时默认停用
+	CashOnDelivery bool
 }
 
 type builtinPaymentPackageDefinition = generatedPaymentMethodPackageDefinition
@@ -374,6 +376,9 @@ func initBuiltinPaymentMethodPackages(runtime *PluginHostRuntime) error {
 		err = func() error {
 			defer cleanup()
 			if shouldSkipBuiltinPaymentPackageImport(existing, definition, checksum) {
+				if err := applyBuiltinPaymentMethodFlags(db, existing, definition, false); err != nil {
+					return err
+				}
 				logger.LogSystemOperation(db, "payment_method_init", "payment_method", optionalPaymentMethodID(existing), map[string]interface{}{
 					"name":          definition.DisplayName,
 					"package_name":  definition.PackageName,
@@ -415,10 +420,14 @@ func initBuiltinPaymentMethodPackages(runtime *PluginHostRuntime) error {
 
 			method, _ := result["item"].(*models.PaymentMethod)
 			methodID := optionalPaymentMethodID(method)
+			created, _ := result["created"].(bool)
 			action := "updated"
-			if created, _ := result["created"].(bool); created {
+			if created {
 				action = "created"
 			}
+			if err := applyBuiltinPaymentMethodFlags(db, method, definition, created); err != nil {
+				return err
+			}
 			logger.LogSystemOperation(db, "payment_method_init", "payment_method", methodID, map[string]interface{}{
 				"name":          definition.DisplayName,
 				"package_name":  definition.PackageName,
@@ -506,16 +515,17 @@ func builtinPaymentPackageDefinitions() ([]builtinPaymentPackageDefinition, erro
 		}
 		version := builtinPaymentPackageVersion(artifactName)
 		definitions = append(definitions, builtinPaymentPackageDefinition{
-			ArtifactName:  artifactName,
-			DisplayName:   strings.TrimSpace(method.Name),
-			Description:   strings.TrimSpace(method.Description),
-			Icon:          strings.TrimSpace(method.Icon),
-			Version:       version,
-			PollInterval:  pollInterval,
-			Config:        configValues,
-			Script:        method.Script,
-			PackageName:   fmt.Sprintf("%s-%s.zip", artifactName, version),
-			EntryFileName: "index.js",
+			ArtifactName:   artifactName,
+			DisplayName:    strings.TrimSpace(method.Name),
+			Description:    strings.TrimSpace(method.Description),
+			Icon:           strings.TrimSpace(method.Icon),
+			Version:        version,
+			PollInterval:   pollInterval,
+			Config:         configValues,
+			Script:         method.Script,
+			PackageName:    fmt.Sprintf("%s-%s.zip", artifactName, version),
+			EntryFileName:  "index.js",
+			CashOnDelivery: method.CashOnDelivery,
 		})
 	}
 	return definitions, nil
@@ -527,11 +537,39 @@ func builtinPaymentPackageArtifactName(name string) string {
 		return "builtin-usdt-trc20"
 	case "USDT BEP20 (BSC)":
 		return "builtin-usdt-bep20-bsc"
+	case "Cash on Delivery":
+		return "builtin-cash-on-delivery"
 	default:
 		return ""
 	}
 }
 
+// applyBuiltinPaymentMethodFlags 同步包导入不携带的付款方式标记
+func applyBuiltinPaymentMethodFlags(db *gorm.DB, method *models.PaymentMethod, definition builtinPaymentPackageDefinition, created bool) error {
+	if method == nil || method.ID == 0 || !definition.CashOnDelivery {
+		return nil
+	}
+	updates := map[string]interface{}{}
+	if !method.CashOnDelivery {
+		updates["cash_on_delivery"] = true
+	}
+	if created {
+		// 货到付款需商家确认承运商支持代收后再启用
+		updates["enabled"] = false
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := db.Model(&models.PaymentMethod{}).Where("id = ?", method.ID).Updates(updates).Error; err != nil {
+		return err
+	}
+	method.CashOnDelivery = true
+	if created {
+		method.Enabled = false
+	}
+	return nil
+}
+
 func builtinPaymentPackageVersion(_ string) string {
 	return "1.0.0"
 }
@@ -3,16 +3,26 @@ package service
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"auralogic/internal/config"
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
 	"auralogic/internal/pkg/logger"
 	"gorm.io/gorm"
 )
 
+// ErrStripeWebhookSignature Stripe webhook 签名校验失败
+var ErrStripeWebhookSignature = errors.New("stripe webhook signature verification failed")
+
+// ErrPaymentMethodNotAllowedForOrder 订单标签不满足付款方式的标签规则
+var ErrPaymentMethodNotAllowedForOrder = bizerr.New("payment.methodNotAllowedForOrder", "This payment method is not available for this order")
+
 // PaymentMethodService 付款方式服务
 type PaymentMethodService struct {
 	db        *gorm.DB
@@ -28,6 +38,13 @@ type LegacyPaymentMethodUpsertInput struct {
 	Config       *string
 	PollInterval *int
 	Enabled      *bool
+	// AutoCancelHours / AllowedHosts / Sandbox / 订单标签规则直接写入付款方式记录，不参与包导入
+	AutoCancelHours *int
+	AllowedHosts    *[]string
+	Sandbox         *bool
+	// 订单标签规则（调用方负责规范化）
+	RequiredOrderTags *[]string
+	ExcludedOrderTags *[]string
 }
 
 // NewPaymentMethodService 创建付款方式服务
@@ -42,7 +59,10 @@ func NewPaymentMethodService(db *gorm.DB, cfg *config.Config) *PaymentMethodServ
 // InitBuiltinPaymentMethods 初始化内置付款方式
 func (s *PaymentMethodService) InitBuiltinPaymentMethods() error {
 	runtime := NewPluginHostRuntime(s.db, s.cfg, nil)
-	return initBuiltinPaymentMethodPackages(runtime)
+	if err := initBuiltinPaymentMethodPackages(runtime); err != nil {
+		return err
+	}
+	return ensureStripePaymentMethod(s.db)
 }
 
 func (s *PaymentMethodService) CreateLegacyPaymentMethod(input LegacyPaymentMethodUpsertInput) (*models.PaymentMethod, error) {
@@ -62,6 +82,27 @@ func (s *PaymentMethodService) CreateLegacyPaymentMethod(input LegacyPaymentMeth
 	if method == nil || method.ID == 0 {
 		return nil, &PluginHostActionError{Status: http.StatusInternalServerError, Message: "load payment method failed"}
 	}
+	if input.AutoCancelHours != nil && *input.AutoCancelHours != method.AutoCancelHours {
+		if err := s.Update(method.ID, map[string]interface{}{"auto_cancel_hours": *input.AutoCancelHours}); err != nil {
+			return nil, err
+		}
+		method.AutoCancelHours = *input.AutoCancelHours
+	}
+	if input.Sandbox != nil && *input.Sandbox != method.Sandbox {
+		if err := s.Update(method.ID, map[string]interface{}{"sandbox": *input.Sandbox}); err != nil {
+			return nil, err
+		}
+		method.Sandbox = *input.Sandbox
+	}
+	if input.AllowedHosts != nil {
+		if err := s.UpdateAllowedHosts(method.ID, *input.AllowedHosts); err != nil {
+			return nil, err
+		}
+		method.AllowedHosts = *input.AllowedHosts
+	}
+	if err := s.applyOrderTagRules(method, input); err != nil {
+		return nil, err
+	}
 	return method, nil
 }
 
@@ -73,17 +114,34 @@ func (s *PaymentMethodService) UpdateLegacyPaymentMethod(id uint, input LegacyPa
 		}
 		return nil, err
 	}
+	if existing.Provider != "" {
+		return s.updateProviderPaymentMethod(existing, input)
+	}
 
 	if !legacyPaymentMethodNeedsPackageImport(input) {
 		updates := map[string]interface{}{}
 		if input.Enabled != nil {
 			updates["enabled"] = *input.Enabled
 		}
+		if input.AutoCancelHours != nil {
+			updates["auto_cancel_hours"] = *input.AutoCancelHours
+		}
+		if input.Sandbox != nil {
+			updates["sandbox"] = *input.Sandbox
+		}
 		if len(updates) > 0 {
 			if err := s.Update(id, updates); err != nil {
 				return nil, err
 			}
 		}
+		if input.AllowedHosts != nil {
+			if err := s.UpdateAllowedHosts(id, *input.AllowedHosts); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.applyOrderTagRules(existing, input); err != nil {
+			return nil, err
+		}
 		return s.Get(id)
 	}
 
@@ -114,10 +172,123 @@ func (s *PaymentMethodService) UpdateLegacyPaymentMethod(id uint, input LegacyPa
 		}
 		method.Enabled = *input.Enabled
 	}
+	if input.AutoCancelHours != nil && method.AutoCancelHours != *input.AutoCancelHours {
+		if err := s.Update(id, map[string]interface{}{"auto_cancel_hours": *input.AutoCancelHours}); err != nil {
+			return nil, err
+		}
+		method.AutoCancelHours = *input.AutoCancelHours
+	}
+	if input.Sandbox != nil && method.Sandbox != *input.Sandbox {
+		if err := s.Update(id, map[string]interface{}{"sandbox": *input.Sandbox}); err != nil {
+			return nil, err
+		}
+		method.Sandbox = *input.Sandbox
+	}
+	if input.AllowedHosts != nil {
+		if err := s.UpdateAllowedHosts(id, *input.AllowedHosts); err != nil {
+			return nil, err
+		}
+		method.AllowedHosts = *input.AllowedHosts
+	}
+	if err := s.applyOrderTagRules(method, input); err != nil {
+		return nil, err
+	}
 
 	return method, nil
 }
 
+// updateProviderPaymentMethod 原生集成的付款方式没有脚本包，直接更新字段
+func (s *PaymentMethodService) updateProviderPaymentMethod(existing *models.PaymentMethod, input LegacyPaymentMethodUpsertInput) (*models.PaymentMethod, error) {
+	if input.Script != nil && strings.TrimSpace(*input.Script) != "" {
+		return nil, &PluginHostActionError{Status: http.StatusBadRequest, Message: "native payment methods do not accept scripts"}
+	}
+	updates := map[string]interface{}{}
+	if input.Name != nil && strings.TrimSpace(*input.Name) != "" {
+		updates["name"] = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		updates["description"] = strings.TrimSpace(*input.Description)
+	}
+	if input.Icon != nil {
+		updates["icon"] = strings.TrimSpace(*input.Icon)
+	}
+	if input.Config != nil {
+		var parsed map[string]interface{}
+		if err := json.Unmarshal([]byte(*input.Config), &parsed); err != nil {
+			return nil, &PluginHostActionError{Status: http.StatusBadRequest, Message: "config must be a JSON object"}
+		}
+		updates["config"] = *input.Config
+	}
+	if input.PollInterval != nil && *input.PollInterval > 0 {
+		updates["poll_interval"] = *input.PollInterval
+	}
+	if input.Enabled != nil {
+		updates["enabled"] = *input.Enabled
+	}
+	if input.AutoCancelHours != nil {
+		updates["auto_cancel_hours"] = *input.AutoCancelHours
+	}
+	if input.Sandbox != nil {
+		updates["sandbox"] = *input.Sandbox
+	}
+	if len(updates) > 0 {
+		if err := s.Update(existing.ID, updates); err != nil {
+			return nil, err
+		}
+	}
+	if input.AllowedHosts != nil {
+		if err := s.UpdateAllowedHosts(existing.ID, *input.AllowedHosts); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.applyOrderTagRules(existing, input); err != nil {
+		return nil, err
+	}
+	return s.Get(existing.ID)
+}
+
+// applyOrderTagRules 写入输入中给出的订单标签规则
+func (s *PaymentMethodService) applyOrderTagRules(method *models.PaymentMethod, input LegacyPaymentMethodUpsertInput) error {
+	if err := s.UpdateOrderTagRules(method.ID, input.RequiredOrderTags, input.ExcludedOrderTags); err != nil {
+		return err
+	}
+	if input.RequiredOrderTags != nil {
+		method.RequiredOrderTags = *input.RequiredOrderTags
+	}
+	if input.ExcludedOrderTags != nil {
+		method.ExcludedOrderTags = *input.ExcludedOrderTags
+	}
+	return nil
+}
+
+// UpdateOrderTagRules 更新订单标签规则，nil 表示保持不变（调用方负责规范化）
+func (s *PaymentMethodService) UpdateOrderTagRules(id uint, required, excluded *[]string) error {
+	columns := []string{"updated_at"}
+	values := models.PaymentMethod{}
+	if required != nil {
+		columns = append(columns, "required_order_tags")
+		values.RequiredOrderTags = append([]string{}, (*required)...)
+	}
+	if excluded != nil {
+		columns = append(columns, "excluded_order_tags")
+		values.ExcludedOrderTags = append([]string{}, (*excluded)...)
+	}
+	if len(columns) == 1 {
+		return nil
+	}
+	return s.db.Model(&models.PaymentMethod{ID: id}).Select(columns).Updates(&values).Error
+}
+
+// UpdateAllowedHosts 更新脚本出站主机白名单（调用方负责规范化）
+func (s *PaymentMethodService) UpdateAllowedHosts(id uint, hosts []string) error {
+	if hosts == nil {
+		hosts = []string{}
+	}
+	return s.db.Model(&models.PaymentMethod{ID: id}).
+		Select("allowed_hosts", "updated_at").
+		Updates(&models.PaymentMethod{AllowedHosts: hosts}).Error
+}
+
 // List 获取所有付款方式
 func (s *PaymentMethodService) List(enabledOnly bool) ([]models.PaymentMethod, error) {
 	var methods []models.PaymentMethod
@@ -162,6 +333,10 @@ func (s *PaymentMethodService) Delete(id uint) error {
 			Delete(&models.PaymentMethodStorageEntry{}).Error; err != nil {
 			return err
 		}
+		if err := tx.Where("payment_method_id = ?", id).
+			Delete(&models.PaymentMethodScriptLog{}).Error; err != nil {
+			return err
+		}
 		return tx.Delete(&models.PaymentMethod{}, id).Error
 	})
 	if err != nil {
@@ -202,6 +377,15 @@ func (s *PaymentMethodService) GeneratePaymentCard(paymentMethodID uint, order *
 	if !pm.Enabled {
 		return nil, errors.New("payment method is disabled")
 	}
+	if order != nil && order.StoreID != nil && !models.BelongsToStore(pm.StoreID, *order.StoreID) {
+		return nil, errors.New("payment method is not available for this store")
+	}
+	if order != nil && !pm.AllowsOrderTags(order.Tags) {
+		return nil, ErrPaymentMethodNotAllowedForOrder
+	}
+	if pm.Provider == models.PaymentProviderStripe {
+		return buildStripePaymentCard(s.db, pm, order)
+	}
 	return s.jsRuntime.ExecutePaymentCard(pm, order)
 }
 
@@ -274,28 +458,83 @@ func (s *PaymentMethodService) SelectPaymentMethod(orderID, paymentMethodID uint
 	if order.Status != models.OrderStatusPendingPayment {
 		return errors.New("order is not in pending payment status")
 	}
+	if order.StoreID != nil && !models.BelongsToStore(pm.StoreID, *order.StoreID) {
+		return errors.New("payment method is not available for this store")
+	}
+	if !pm.AllowsOrderTags(order.Tags) {
+		return ErrPaymentMethodNotAllowedForOrder
+	}
+	if pm.CashOnDelivery {
+		return s.selectCashOnDelivery(&order, pm)
+	}
 
 	// 创建或更新订单付款方式
 	opm := models.OrderPaymentMethod{
 		OrderID:         orderID,
 		PaymentMethodID: paymentMethodID,
 	}
+	assign := map[string]interface{}{"payment_method_id": paymentMethodID}
+	if pm.Provider == models.PaymentProviderStripe {
+		// 选择时即创建 PaymentIntent，前端凭 client secret 完成支付
+		intent, intentErr := createStripePaymentIntent(pm, &order)
+		if intentErr != nil {
+			return intentErr
+		}
+		assign["payment_data"] = encodeStripeOrderPaymentData(intent)
+	}
 
 	err = s.db.Where("order_id = ?", orderID).
-		Assign(models.OrderPaymentMethod{PaymentMethodID: paymentMethodID}).
+		Assign(assign).
 		FirstOrCreate(&opm).Error
 
 	if err == nil {
+		// 付款方式可覆盖未付款时限（如加密货币更短、对公转账更长）
+		if deadlineErr := refreshOrderPaymentDeadline(s.db, s.cfg, &order, pm); deadlineErr != nil {
+			log.Printf("Failed to refresh payment deadline: order=%s err=%v", order.OrderNo, deadlineErr)
+		}
+		if releaseErr := releaseOtherPaymentAmountReservationsTx(s.db, orderID, paymentMethodID); releaseErr != nil {
+			log.Printf("Failed to release payment amount reservations: order=%s err=%v", order.OrderNo, releaseErr)
+		}
+		if order.IsSandbox != pm.Sandbox {
+			if sandboxErr := s.db.Model(&order).Update("is_sandbox", pm.Sandbox).Error; sandboxErr != nil {
+				log.Printf("Failed to update sandbox flag: order=%s err=%v", order.OrderNo, sandboxErr)
+			}
+		}
 		logger.LogPaymentOperation(s.db, "payment_method_selected", orderID, map[string]interface{}{
-			"order_no":          order.OrderNo,
-			"payment_method_id": paymentMethodID,
-			"payment_method":    pm.Name,
+			"order_no":            order.OrderNo,
+			"payment_method_id":   paymentMethodID,
+			"payment_method":      pm.Name,
+			"payment_deadline_at": order.PaymentDeadlineAt,
+			"sandbox":             pm.Sandbox,
 		})
 	}
 
 	return err
 }
 
+// selectCashOnDelivery 货到付款：记录付款方式并直接转为待发货
+func (s *PaymentMethodService) selectCashOnDelivery(order *models.Order, pm *models.PaymentMethod) error {
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		opm := models.OrderPaymentMethod{OrderID: order.ID, PaymentMethodID: pm.ID}
+		if err := tx.Where("order_id = ?", order.ID).
+			Assign(models.OrderPaymentMethod{PaymentMethodID: pm.ID}).
+			FirstOrCreate(&opm).Error; err != nil {
+			return err
+		}
+		return startCashOnDeliveryTx(tx, order, pm)
+	}); err != nil {
+		return err
+	}
+	logger.LogPaymentOperation(s.db, "payment_method_selected", order.ID, map[string]interface{}{
+		"order_no":          order.OrderNo,
+		"payment_method_id": pm.ID,
+		"payment_method":    pm.Name,
+		"cash_on_delivery":  true,
+		"status":            order.Status,
+	})
+	return nil
+}
+
 // GetOrderPaymentMethod 获取订单选择的付款方式
 func (s *PaymentMethodService) GetOrderPaymentMethod(orderID uint) (*models.PaymentMethod, *models.OrderPaymentMethod, error) {
 	var opm models.OrderPaymentMethod
@@ -328,8 +567,14 @@ func (s *PaymentMethodService) UpdatePaymentConfig(id uint, config map[string]in
 	return s.db.Model(&models.PaymentMethod{}).Where("id = ?", id).Update("config", string(configJSON)).Error
 }
 
-// TestScript 测试JS脚本
-func (s *PaymentMethodService) TestScript(script string, config map[string]interface{}) (*PaymentCardResult, error) {
+// PaymentScriptTestResult 脚本测试结果：付款卡片字段 + 本次执行的 console 输出
+type PaymentScriptTestResult struct {
+	*PaymentCardResult
+	Logs []models.PaymentScriptLogEntry `json:"logs"`
+}
+
+// TestScript 测试JS脚本；执行失败时仍返回已收集的 console 输出
+func (s *PaymentMethodService) TestScript(script string, config map[string]interface{}) (*PaymentScriptTestResult, error) {
 	configJSON, _ := json.Marshal(config)
 	pm := &models.PaymentMethod{
 		Type:   models.PaymentMethodTypeCustom,
@@ -345,7 +590,25 @@ func (s *PaymentMethodService) TestScript(script string, config map[string]inter
 		Currency:    "CNY",
 	}
 
-	return s.jsRuntime.ExecutePaymentCard(pm, testOrder)
+	card, logs, err := s.jsRuntime.TestPaymentCard(pm, testOrder)
+	return &PaymentScriptTestResult{PaymentCardResult: card, Logs: logs}, err
+}
+
+// ListScriptLogs 付款方式脚本执行日志（最新在前），callback 为空时不过滤
+func (s *PaymentMethodService) ListScriptLogs(paymentMethodID uint, callback string, page, limit int) ([]models.PaymentMethodScriptLog, int64, error) {
+	query := s.db.Model(&models.PaymentMethodScriptLog{}).Where("payment_method_id = ?", paymentMethodID)
+	if callback = strings.TrimSpace(callback); callback != "" {
+		query = query.Where("callback = ?", callback)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	logs := make([]models.PaymentMethodScriptLog, 0, limit)
+	if err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
 }
 
 func (s *PaymentMethodService) ExecuteWebhook(paymentMethodID uint, req *PaymentWebhookRequest) (*PaymentWebhookResult, error) {
@@ -359,6 +622,122 @@ func (s *PaymentMethodService) ExecuteWebhook(paymentMethodID uint, req *Payment
 	return s.jsRuntime.ExecuteWebhook(pm, req)
 }
 
+// ExecutePaymentNotify 执行网关服务端异步通知
+func (s *PaymentMethodService) ExecutePaymentNotify(pm *models.PaymentMethod, req *PaymentWebhookRequest) (*PaymentWebhookResult, error) {
+	if pm == nil || !pm.Enabled {
+		return nil, errors.New("payment method is disabled")
+	}
+	return s.jsRuntime.ExecutePaymentNotify(pm, req)
+}
+
+// HandleStripeWebhook 校验签名并处理 Stripe 事件：payment_intent.succeeded 返回待确认的付款，refund.updated 确认待到账的退款
+func (s *PaymentMethodService) HandleStripeWebhook(pm *models.PaymentMethod, payload []byte, signature string) (*StripeWebhookOutcome, error) {
+	if pm == nil || pm.Provider != models.PaymentProviderStripe {
+		return nil, errors.New("payment method is not a Stripe integration")
+	}
+	cfg, err := parseStripeConfig(pm)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyStripeSignature(payload, signature, cfg.WebhookSecret, time.Now()); err != nil {
+		return nil, ErrStripeWebhookSignature
+	}
+
+	var event stripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid stripe event: %w", err)
+	}
+	outcome := &StripeWebhookOutcome{EventID: event.ID, EventType: event.Type}
+	switch event.Type {
+	case "payment_intent.succeeded":
+		var intent stripePaymentIntent
+		if err := json.Unmarshal(event.Data.Object, &intent); err != nil {
+			return nil, fmt.Errorf("invalid stripe payment intent: %w", err)
+		}
+		return outcome, s.applyStripePaymentIntent(pm, &intent, outcome)
+	case "refund.updated", "refund.created":
+		var refund stripeRefund
+		if err := json.Unmarshal(event.Data.Object, &refund); err != nil {
+			return nil, fmt.Errorf("invalid stripe refund: %w", err)
+		}
+		return outcome, s.applyStripeRefund(&refund, outcome)
+	default:
+		outcome.Ignored = true
+		return outcome, nil
+	}
+}
+
+func (s *PaymentMethodService) applyStripePaymentIntent(pm *models.PaymentMethod, intent *stripePaymentIntent, outcome *StripeWebhookOutcome) error {
+	orderID, _ := strconv.ParseUint(intent.Metadata["order_id"], 10, 32)
+	if orderID == 0 {
+		// 非本站创建的 PaymentIntent
+		outcome.Ignored = true
+		return nil
+	}
+	var order models.Order
+	if err := s.db.First(&order, orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			outcome.Ignored = true
+			return nil
+		}
+		return err
+	}
+	var opm models.OrderPaymentMethod
+	if err := s.db.Where("order_id = ?", order.ID).First(&opm).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			outcome.Ignored = true
+			return nil
+		}
+		return err
+	}
+	intentID, err := loadStripePaymentIntentID(s.db, order.ID)
+	if err != nil {
+		return err
+	}
+	if opm.PaymentMethodID != pm.ID || intentID != intent.ID {
+		// 用户已改选其他付款方式或重新创建了 PaymentIntent
+		logger.LogPaymentOperation(s.db, "stripe_webhook_intent_mismatch", order.ID, map[string]interface{}{
+			"order_no":          order.OrderNo,
+			"payment_intent_id": intent.ID,
+		})
+		outcome.Ignored = true
+		return nil
+	}
+	result := stripeIntentCheckResult(intent, &order)
+	if !result.Paid {
+		logger.LogPaymentOperation(s.db, "stripe_webhook_payment_rejected", order.ID, map[string]interface{}{
+			"order_no":          order.OrderNo,
+			"payment_intent_id": intent.ID,
+			"message":           result.Message,
+		})
+		outcome.Ignored = true
+		return nil
+	}
+	outcome.OrderID = order.ID
+	outcome.Payment = result
+	return nil
+}
+
+func (s *PaymentMethodService) applyStripeRefund(refund *stripeRefund, outcome *StripeWebhookOutcome) error {
+	if refund.ID == "" || refund.Status != "succeeded" {
+		outcome.Ignored = true
+		return nil
+	}
+	var record models.OrderRefund
+	if err := s.db.Where("transaction_id = ? AND status = ?", refund.ID, models.OrderRefundStatusPending).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			outcome.Ignored = true
+			return nil
+		}
+		return err
+	}
+	if _, err := NewOrderRefundService(s.db, nil, nil).ConfirmRefund(record.OrderID, record.ID, refund.ID, nil); err != nil {
+		return err
+	}
+	outcome.OrderID = record.OrderID
+	return nil
+}
+
 func legacyPaymentMethodNeedsPackageImport(input LegacyPaymentMethodUpsertInput) bool {
 	return input.Name != nil ||
 		input.Description != nil ||
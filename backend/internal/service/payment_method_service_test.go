@@ -20,16 +20,25 @@ func TestPaymentMethodServiceInitBuiltinPaymentMethodsImportsPackageBackedMethod
 	}
 
 	var methods []models.PaymentMethod
-	if err := db.Order("id ASC").Find(&methods).Error; err != nil {
+	if err := db.Where("provider = ?", "").Order("id ASC").Find(&methods).Error; err != nil {
 		t.Fatalf("query payment methods failed: %v", err)
 	}
-	if len(methods) != 2 {
-		t.Fatalf("expected 2 builtin payment methods, got %d", len(methods))
+	if len(methods) != 3 {
+		t.Fatalf("expected 3 builtin payment methods, got %d", len(methods))
+	}
+
+	var stripe models.PaymentMethod
+	if err := db.Where("provider = ?", models.PaymentProviderStripe).First(&stripe).Error; err != nil {
+		t.Fatalf("expected native stripe payment method: %v", err)
+	}
+	if stripe.Enabled || stripe.Type != models.PaymentMethodTypeBuiltin || stripe.Script != "" {
+		t.Fatalf("expected disabled script-less stripe method, got %+v", stripe)
 	}
 
 	expectedArtifacts := map[string]string{
 		"USDT TRC20":       "builtin-usdt-trc20",
 		"USDT BEP20 (BSC)": "builtin-usdt-bep20-bsc",
+		"Cash on Delivery": "builtin-cash-on-delivery",
 	}
 	methodByID := make(map[uint]models.PaymentMethod, len(methods))
 	for _, method := range methods {
@@ -56,14 +65,18 @@ func TestPaymentMethodServiceInitBuiltinPaymentMethodsImportsPackageBackedMethod
 		if !strings.Contains(method.Manifest, `"runtime":"payment_js"`) {
 			t.Fatalf("expected builtin method %q manifest runtime payment_js, got %q", method.Name, method.Manifest)
 		}
+		isCOD := method.Name == "Cash on Delivery"
+		if method.CashOnDelivery != isCOD || method.Enabled == isCOD {
+			t.Fatalf("expected builtin method %q cash_on_delivery=%v enabled=%v, got %+v", method.Name, isCOD, !isCOD, method)
+		}
 	}
 
 	var versions []models.PaymentMethodVersion
 	if err := db.Order("id ASC").Find(&versions).Error; err != nil {
 		t.Fatalf("query payment method versions failed: %v", err)
 	}
-	if len(versions) != 2 {
-		t.Fatalf("expected 2 payment method versions after builtin init, got %d", len(versions))
+	if len(versions) != 3 {
+		t.Fatalf("expected 3 payment method versions after builtin init, got %d", len(versions))
 	}
 	for _, version := range versions {
 		method, ok := methodByID[version.PaymentMethodID]
@@ -99,16 +112,16 @@ func TestPaymentMethodServiceInitBuiltinPaymentMethodsImportsPackageBackedMethod
 	if err := db.Model(&models.PaymentMethod{}).Count(&methodCount).Error; err != nil {
 		t.Fatalf("count payment methods failed: %v", err)
 	}
-	if methodCount != 2 {
-		t.Fatalf("expected builtin init to stay idempotent with 2 payment methods, got %d", methodCount)
+	if methodCount != 4 {
+		t.Fatalf("expected builtin init to stay idempotent with 4 payment methods, got %d", methodCount)
 	}
 
 	var versionCount int64
 	if err := db.Model(&models.PaymentMethodVersion{}).Count(&versionCount).Error; err != nil {
 		t.Fatalf("count payment method versions failed: %v", err)
 	}
-	if versionCount != 2 {
-		t.Fatalf("expected builtin init to stay idempotent with 2 version snapshots, got %d", versionCount)
+	if versionCount != 3 {
+		t.Fatalf("expected builtin init to stay idempotent with 3 version snapshots, got %d", versionCount)
 	}
 }
 
@@ -247,7 +260,7 @@ func TestPaymentMethodServiceInitBuiltinPaymentMethodsAutoMigratesLegacyRecords(
 	if err := db.Model(&models.PaymentMethod{}).Count(&methodCount).Error; err != nil {
 		t.Fatalf("count payment methods failed: %v", err)
 	}
-	if methodCount != 3 {
-		t.Fatalf("expected 3 payment methods after builtin init plus legacy migration, got %d", methodCount)
+	if methodCount != 5 {
+		t.Fatalf("expected 5 payment methods after builtin init (including stripe) plus legacy migration, got %d", methodCount)
 	}
 }
@@ -23,6 +23,12 @@ import (
 const paymentPollingStartupBatchSize = 200
 const paymentPollingPersistBatchSize = 200
 
+// worker 模式下轮询由持锁的单个 worker 执行，并定期同步 API 进程落库的任务
+const (
+	paymentPollingWorkerLockTTL      = 30 * time.Second
+	paymentPollingWorkerSyncInterval = 15 * time.Second
+)
+
 // PollingTask 轮询任务
 type PollingTask struct {
 	OrderID         uint      `json:"order_id"`
@@ -80,8 +86,7 @@ type PaymentPollingService struct {
 	lifecycleMu         sync.Mutex
 	running             bool
 	mutex               sync.Mutex
-	stopChan            chan struct{}
-	doneChan            chan struct{}
+	scheduler           *BackgroundScheduler
 	wakeupChan          chan struct{} // 用于唤醒主循环
 	defaultInterval     int           // 默认检查间隔(秒)
 	maxRetries          int           // 最大重试次数
@@ -156,10 +161,8 @@ func (s *PaymentPollingService) Start() {
 		s.lifecycleMu.Unlock()
 		return
 	}
-	stopChan := make(chan struct{})
-	doneChan := make(chan struct{})
-	s.stopChan = stopChan
-	s.doneChan = doneChan
+	scheduler := backgroundSchedulerForService()
+	s.scheduler = scheduler
 	s.running = true
 	s.lifecycleMu.Unlock()
 
@@ -172,13 +175,20 @@ func (s *PaymentPollingService) Start() {
 		"algorithm":        "time_wheel",
 	})
 
-	s.resetQueueState()
-	// 从数据库恢复未完成的轮询任务
-	s.recoverTasks()
-	go func() {
-		defer close(doneChan)
-		runBackgroundServiceWithStopChan("payment_polling.timeWheelLoop", stopChan, s.timeWheelLoop)
-	}()
+	job := BackgroundJob{Name: "payment_polling", Loop: s.timeWheelLoop}
+	if s.runsAsWorker() {
+		// 多个 worker 进程中只有持锁者恢复队列并轮询
+		job.Loop = s.workerLoop
+		job.LockTTL = paymentPollingWorkerLockTTL
+	} else {
+		s.resetQueueState()
+		// 从数据库恢复未完成的轮询任务
+		s.recoverTasks()
+	}
+	// 时间轮自行计算唤醒时间，以常驻任务方式交给调度器托管
+	if err := scheduler.Register(job); err != nil {
+		log.Printf("payment polling background job register failed: err=%v", err)
+	}
 }
 
 // Stop 停止轮询服务
@@ -188,18 +198,55 @@ func (s *PaymentPollingService) Stop() {
 		s.lifecycleMu.Unlock()
 		return
 	}
-	stopChan := s.stopChan
-	doneChan := s.doneChan
-	s.stopChan = nil
-	s.doneChan = nil
+	scheduler := s.scheduler
+	s.scheduler = nil
 	s.running = false
 
 	logger.LogSystemOperation(s.db, "payment_polling_stop", "system", nil, nil)
-	close(stopChan)
-	<-doneChan
+	scheduler.Remove("payment_polling")
 	s.lifecycleMu.Unlock()
 }
 
+func (s *PaymentPollingService) runsAsWorker() bool {
+	return s.cfg != nil && s.cfg.App.Mode == config.AppModeWorker
+}
+
+// enqueueOnly api 模式下本进程不运行时间轮，任务只落库由 worker 同步执行
+func (s *PaymentPollingService) enqueueOnly() bool {
+	return s.cfg != nil && s.cfg.App.Mode == config.AppModeAPI
+}
+
+// workerLoop 恢复队列后运行时间轮，并定期同步 API 进程新落库的任务
+func (s *PaymentPollingService) workerLoop(stopChan <-chan struct{}) {
+	s.resetQueueState()
+	s.recoverTasks()
+
+	syncStop := make(chan struct{})
+	syncDone := make(chan struct{})
+	defer func() {
+		close(syncStop)
+		<-syncDone
+	}()
+	go func() {
+		defer close(syncDone)
+		ticker := time.NewTicker(paymentPollingWorkerSyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopChan:
+				return
+			case <-syncStop:
+				return
+			case <-ticker.C:
+				s.recoverTasks()
+				s.wakeup()
+			}
+		}
+	}()
+
+	s.timeWheelLoop(stopChan)
+}
+
 // AddToQueue 添加订单到轮询队列
 func (s *PaymentPollingService) AddToQueue(orderID, paymentMethodID uint) error {
 	var order models.Order
@@ -229,6 +276,25 @@ func (s *PaymentPollingService) AddToQueue(orderID, paymentMethodID uint) error
 		interval = pm.PollInterval
 	}
 
+	if s.enqueueOnly() {
+		s.saveTaskToDB(&PollingTask{
+			OrderID:         orderID,
+			UserID:          queueUserID,
+			PaymentMethodID: paymentMethodID,
+			AddedAt:         time.Now(),
+			NextCheckAt:     time.Now(),
+			CheckInterval:   interval,
+			index:           -1,
+		})
+		logger.LogPaymentOperation(s.db, "payment_polling_add", orderID, map[string]interface{}{
+			"payment_method_id": paymentMethodID,
+			"check_interval":    interval,
+			"user_id":           queueUserID,
+			"enqueue_only":      true,
+		})
+		return nil
+	}
+
 	s.mutex.Lock()
 	now := time.Now()
 	if task, exists := s.taskMap[orderID]; exists {
@@ -602,6 +668,10 @@ func (s *PaymentPollingService) ConfirmPaymentResult(
 
 // RemoveFromQueue 从队列中移除订单
 func (s *PaymentPollingService) RemoveFromQueue(orderID uint) {
+	if s.enqueueOnly() {
+		s.removeTaskFromDB(orderID)
+		return
+	}
 	s.removeFromQueue(orderID)
 }
 
@@ -856,7 +926,15 @@ func (s *PaymentPollingService) checkPaymentStatus(task *PollingTask) (bool, int
 	}
 
 	// 检查付款状态
-	result, err := s.jsRuntime.CheckPaymentStatus(&pm, &order)
+	var (
+		result *PaymentCheckResult
+		err    error
+	)
+	if pm.Provider == models.PaymentProviderStripe {
+		result, err = checkStripePaymentStatus(s.db, &pm, &order)
+	} else {
+		result, err = s.jsRuntime.CheckPaymentStatus(&pm, &order)
+	}
 	if err != nil {
 		logger.LogPaymentOperation(s.db, "payment_polling_check_failed", task.OrderID, map[string]interface{}{
 			"error":             err.Error(),
@@ -941,13 +1019,21 @@ func (s *PaymentPollingService) handlePaymentSuccess(task *PollingTask, order *m
 			return err
 		}
 		lockedOrder = currentOrder
-		finalizeResult, err = finalizePendingPaymentOrderTx(tx, currentOrder, s.virtualInventorySvc, paidOrderFinalizeOptions{})
+		finalizeResult, err = finalizePendingPaymentOrderTx(tx, currentOrder, s.virtualInventorySvc, paidOrderFinalizeOptions{
+			PaymentSource: normalizedSource,
+		})
 		if err != nil {
 			return err
 		}
 		if !finalizeResult.Updated {
 			return nil
 		}
+		if currentOrder.IsSandbox != pm.Sandbox {
+			if err := tx.Model(&models.Order{}).Where("id = ?", task.OrderID).Update("is_sandbox", pm.Sandbox).Error; err != nil {
+				return err
+			}
+			currentOrder.IsSandbox = pm.Sandbox
+		}
 		return tx.Model(&models.OrderPaymentMethod{}).
 			Where("order_id = ?", task.OrderID).
 			Update("payment_data", string(paymentDataJSON)).Error
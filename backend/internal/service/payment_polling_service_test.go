@@ -257,3 +257,48 @@ func TestShouldRetryPaymentFinalizeError(t *testing.T) {
 		t.Fatalf("expected deterministic script failure to stop retrying")
 	}
 }
+
+func TestPaymentPollingAPIModeOnlyPersistsTasksForWorker(t *testing.T) {
+	apiSvc, db := newPaymentPollingServiceTestDB(t)
+	apiSvc.cfg.App.Mode = config.AppModeAPI
+
+	order := &models.Order{
+		OrderNo:     "ORDER-POLL-API-MODE",
+		Status:      models.OrderStatusPendingPayment,
+		TotalAmount: 100,
+		Currency:    "CNY",
+		Items:       []models.OrderItem{{SKU: "SKU-1", Name: "Item 1", Quantity: 1, ProductType: models.ProductTypePhysical}},
+	}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	pm := &models.PaymentMethod{Name: "USDT", Enabled: true, PollInterval: 20}
+	if err := db.Create(pm).Error; err != nil {
+		t.Fatalf("create payment method: %v", err)
+	}
+
+	if err := apiSvc.AddToQueue(order.ID, pm.ID); err != nil {
+		t.Fatalf("add task: %v", err)
+	}
+	if len(apiSvc.GetQueueStatus()) != 0 {
+		t.Fatal("api process must not keep polling tasks in memory")
+	}
+	var persisted int64
+	db.Model(&models.PaymentPollingTask{}).Where("order_id = ?", order.ID).Count(&persisted)
+	if persisted != 1 {
+		t.Fatalf("expected task persisted for worker, got %d rows", persisted)
+	}
+
+	workerSvc := NewPaymentPollingService(db, nil, nil, &config.Config{App: config.AppConfig{Mode: config.AppModeWorker}})
+	workerSvc.recoverTasks()
+	tasks := workerSvc.GetQueueStatus()
+	if len(tasks) != 1 || tasks[0].PaymentMethodID != pm.ID || tasks[0].CheckInterval != pm.PollInterval {
+		t.Fatalf("worker should pick up persisted task, got %+v", tasks)
+	}
+
+	apiSvc.RemoveFromQueue(order.ID)
+	db.Model(&models.PaymentPollingTask{}).Where("order_id = ?", order.ID).Count(&persisted)
+	if persisted != 0 {
+		t.Fatal("api process should remove persisted task directly")
+	}
+}
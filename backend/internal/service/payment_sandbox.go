@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+// PaymentSourceSandboxSimulation 管理员模拟沙箱付款的确认来源
+const PaymentSourceSandboxSimulation = "sandbox_simulation"
+
+// SimulateSandboxPayment 沙箱付款方式下由管理员模拟付款成功，走与网关回调相同的确认流程
+func (s *PaymentPollingService) SimulateSandboxPayment(orderID uint, operatorID *uint) (*models.Order, error) {
+	var order models.Order
+	if err := s.db.First(&order, orderID).Error; err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPendingPayment {
+		return nil, bizerr.Newf("payment.sandboxInvalidOrderStatus", "Order status %s does not support simulated payment", order.Status).
+			WithParams(map[string]interface{}{"status": order.Status})
+	}
+
+	var opm models.OrderPaymentMethod
+	if err := s.db.Where("order_id = ?", orderID).First(&opm).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, bizerr.New("payment.sandboxMethodRequired", "Order has not selected a sandbox payment method")
+		}
+		return nil, err
+	}
+	var pm models.PaymentMethod
+	if err := s.db.First(&pm, opm.PaymentMethodID).Error; err != nil {
+		return nil, err
+	}
+	if !pm.Sandbox {
+		return nil, bizerr.New("payment.sandboxMethodRequired", "Order has not selected a sandbox payment method")
+	}
+
+	data := map[string]interface{}{
+		"sandbox":   true,
+		"simulated": true,
+	}
+	if operatorID != nil {
+		data["simulated_by"] = *operatorID
+	}
+	if _, err := s.ConfirmPaymentResult(orderID, pm.ID, &PaymentCheckResult{
+		Paid:          true,
+		TransactionID: "SANDBOX-" + order.OrderNo,
+		Message:       "Sandbox payment simulated by admin",
+		Data:          data,
+	}, PaymentSourceSandboxSimulation); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.First(&order, orderID).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func createSandboxTestOrder(t *testing.T, svc *PaymentPollingService, orderNo string, sandbox bool) (*models.Order, *models.PaymentMethod) {
+	t.Helper()
+
+	order := &models.Order{
+		OrderNo:     orderNo,
+		Status:      models.OrderStatusPendingPayment,
+		TotalAmount: 100,
+		Currency:    "CNY",
+		Items: []models.OrderItem{{
+			SKU:         "SKU-1",
+			Name:        "Item 1",
+			Quantity:    1,
+			ProductType: models.ProductTypePhysical,
+		}},
+	}
+	if err := svc.db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	pm := &models.PaymentMethod{Name: "Test Gateway " + orderNo, Enabled: true, PollInterval: 30, Sandbox: sandbox}
+	if err := svc.db.Create(pm).Error; err != nil {
+		t.Fatalf("create payment method: %v", err)
+	}
+	if err := svc.db.Create(&models.OrderPaymentMethod{OrderID: order.ID, PaymentMethodID: pm.ID}).Error; err != nil {
+		t.Fatalf("create order payment method: %v", err)
+	}
+	return order, pm
+}
+
+func TestSimulateSandboxPaymentMarksOrderPaid(t *testing.T) {
+	svc, db := newPaymentPollingServiceTestDB(t)
+	if err := db.AutoMigrate(&models.Product{}, &models.UserPurchaseStat{}, &models.LedgerEntry{}, &models.PaymentAmountReservation{}, &models.OrderNote{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	order, _ := createSandboxTestOrder(t, svc, "ORDER-SANDBOX-1", true)
+
+	operatorID := uint(7)
+	paid, err := svc.SimulateSandboxPayment(order.ID, &operatorID)
+	if err != nil {
+		t.Fatalf("simulate payment: %v", err)
+	}
+	if paid.Status == models.OrderStatusPendingPayment {
+		t.Fatalf("expected order to leave pending_payment, got %s", paid.Status)
+	}
+	if !paid.IsSandbox {
+		t.Fatalf("expected paid order to be marked as sandbox")
+	}
+
+	var opm models.OrderPaymentMethod
+	if err := db.Where("order_id = ?", order.ID).First(&opm).Error; err != nil {
+		t.Fatalf("load order payment method: %v", err)
+	}
+	if opm.PaymentData == "" {
+		t.Fatalf("expected simulated payment data to be recorded")
+	}
+}
+
+func TestSimulateSandboxPaymentRejectsLiveMethod(t *testing.T) {
+	svc, _ := newPaymentPollingServiceTestDB(t)
+	order, _ := createSandboxTestOrder(t, svc, "ORDER-SANDBOX-LIVE", false)
+
+	_, err := svc.SimulateSandboxPayment(order.ID, nil)
+	requireOrderBizErr(t, err, "payment.sandboxMethodRequired")
+}
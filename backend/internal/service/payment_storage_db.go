@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -160,16 +161,10 @@ func (s *JSRuntimeService) storageSetValue(paymentMethodID uint, key, value stri
 		UpdatedAt:       now,
 	}
 
-	return s.db.Clauses(clause.OnConflict{
-		Columns: []clause.Column{
-			{Name: "payment_method_id"},
-			{Name: "key"},
-		},
-		DoUpdates: clause.Assignments(map[string]interface{}{
-			"value":      value,
-			"updated_at": now,
-		}),
-	}).Create(&entry).Error
+	return s.db.Clauses(dbutil.UpsertOnConflict([]string{"payment_method_id", "key"}, map[string]interface{}{
+		"value":      value,
+		"updated_at": now,
+	})).Create(&entry).Error
 }
 
 func (s *JSRuntimeService) storageDeleteKey(paymentMethodID uint, key string) error {
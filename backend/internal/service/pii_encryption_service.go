@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+
+	"auralogic/internal/pkg/piicrypt"
+	"gorm.io/gorm"
+)
+
+// orderPIIRow 订单收件信息的原始列（不经过序列化器，读取到的是库中实际存储的值）
+type orderPIIRow struct {
+	ID                 uint
+	ReceiverName       string
+	ReceiverPhone      string
+	ReceiverEmail      string
+	ReceiverAddress    string
+	ReceiverEmailIndex string `gorm:"column:receiver_email_bidx"`
+	ReceiverPhoneIndex string `gorm:"column:receiver_phone_bidx"`
+}
+
+// PIIRotationResult 重新加密结果
+type PIIRotationResult struct {
+	ActiveKeyID string `json:"active_key_id"`
+	Scanned     int    `json:"scanned"`
+	Updated     int    `json:"updated"`
+}
+
+// RotateOrderPII 用当前密钥重新加密订单收件信息并重建盲索引。
+// 启用加密前的明文、旧密钥加密的数据、盲索引密钥变化后的索引都会被更新；可重复执行
+func RotateOrderPII(db *gorm.DB, batchSize int) (*PIIRotationResult, error) {
+	c := piicrypt.Current()
+	if c == nil {
+		return nil, errors.New("pii encryption is not enabled")
+	}
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+
+	result := &PIIRotationResult{ActiveKeyID: c.ActiveKeyID()}
+	var lastID uint
+	for {
+		var rows []orderPIIRow
+		if err := db.Table("orders").
+			Select("id", "receiver_name", "receiver_phone", "receiver_email", "receiver_address", "receiver_email_bidx", "receiver_phone_bidx").
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&rows).Error; err != nil {
+			return result, err
+		}
+		if len(rows) == 0 {
+			return result, nil
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			result.Scanned++
+			updates, err := rotateOrderPIIRow(c, row)
+			if err != nil {
+				return result, err
+			}
+			if len(updates) == 0 {
+				continue
+			}
+			if err := db.Table("orders").Where("id = ?", row.ID).Updates(updates).Error; err != nil {
+				return result, err
+			}
+			result.Updated++
+		}
+	}
+}
+
+// rotateOrderPIIRow 计算需要写回的列，已是最新状态时返回空
+func rotateOrderPIIRow(c *piicrypt.Cipher, row orderPIIRow) (map[string]interface{}, error) {
+	updates := make(map[string]interface{})
+	plain := make(map[string]string, 4)
+	for column, stored := range map[string]string{
+		"receiver_name":    row.ReceiverName,
+		"receiver_phone":   row.ReceiverPhone,
+		"receiver_email":   row.ReceiverEmail,
+		"receiver_address": row.ReceiverAddress,
+	} {
+		value, err := c.Decrypt(stored)
+		if err != nil {
+			return nil, err
+		}
+		plain[column] = value
+		if stored == "" || piicrypt.KeyIDOf(stored) == c.ActiveKeyID() {
+			continue
+		}
+		encrypted, err := c.Encrypt(value)
+		if err != nil {
+			return nil, err
+		}
+		updates[column] = encrypted
+	}
+
+	if index := c.EmailIndex(plain["receiver_email"]); index != row.ReceiverEmailIndex {
+		updates["receiver_email_bidx"] = index
+	}
+	if index := c.PhoneIndex(plain["receiver_phone"]); index != row.ReceiverPhoneIndex {
+		updates["receiver_phone_bidx"] = index
+	}
+	return updates, nil
+}
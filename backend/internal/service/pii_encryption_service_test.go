@@ -0,0 +1,107 @@
+package service
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/piicrypt"
+	"auralogic/internal/repository"
+)
+
+func newTestPIICipher(t *testing.T, activeID string, keyIDs ...string) *piicrypt.Cipher {
+	t.Helper()
+	keys := make(map[string]string, len(keyIDs))
+	for _, id := range keyIDs {
+		keys[id] = base64.StdEncoding.EncodeToString([]byte(strings.Repeat(id[len(id)-1:], 32)))
+	}
+	c, err := piicrypt.New(activeID, keys, base64.StdEncoding.EncodeToString([]byte(strings.Repeat("i", 32))))
+	if err != nil {
+		t.Fatalf("create cipher failed: %v", err)
+	}
+	return c
+}
+
+func TestOrderReceiverPIIEncryptedAtRestAndSearchable(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{})
+	t.Cleanup(func() { piicrypt.SetCurrent(nil) })
+
+	// 启用加密前的旧数据
+	legacy := models.Order{OrderNo: "PII-LEGACY", Status: models.OrderStatusPending, ReceiverName: "Old Name", ReceiverEmail: "old@example.com", ReceiverPhone: "13900001111"}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+
+	piicrypt.SetCurrent(newTestPIICipher(t, "k1", "k1"))
+	order := models.Order{
+		OrderNo:         "PII-1",
+		Status:          models.OrderStatusPending,
+		ReceiverName:    "Alice",
+		ReceiverEmail:   "Alice@Example.com",
+		ReceiverPhone:   "138-0000-1111",
+		ReceiverAddress: "1 Main St",
+	}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+
+	var raw orderPIIRow
+	if err := db.Table("orders").Where("id = ?", order.ID).Take(&raw).Error; err != nil {
+		t.Fatalf("load raw row failed: %v", err)
+	}
+	for _, value := range []string{raw.ReceiverName, raw.ReceiverEmail, raw.ReceiverPhone, raw.ReceiverAddress} {
+		if !piicrypt.IsEncrypted(value) {
+			t.Fatalf("expected ciphertext at rest, got %q", value)
+		}
+	}
+	if raw.ReceiverEmailIndex == "" || raw.ReceiverPhoneIndex == "" {
+		t.Fatal("expected blind indexes to be stored")
+	}
+
+	var loaded models.Order
+	if err := db.First(&loaded, order.ID).Error; err != nil {
+		t.Fatalf("load order failed: %v", err)
+	}
+	if loaded.ReceiverName != "Alice" || loaded.ReceiverEmail != "Alice@Example.com" || loaded.ReceiverAddress != "1 Main St" {
+		t.Fatalf("expected decrypted receiver fields, got %+v", loaded)
+	}
+
+	orderRepo := repository.NewOrderRepository(db)
+	for _, search := range []string{"alice@example.com", "13800001111", "PII-1"} {
+		items, _, err := orderRepo.List(1, 20, "", "", nil, search, "", "", nil, "", nil, nil, nil)
+		if err != nil {
+			t.Fatalf("search orders failed: %v", err)
+		}
+		if len(items) != 1 || items[0].ID != order.ID {
+			t.Fatalf("expected search %q to find order, got %d items", search, len(items))
+		}
+	}
+
+	// 轮换：旧明文和 k1 密文都改用 k2 加密
+	piicrypt.SetCurrent(newTestPIICipher(t, "k2", "k1", "k2"))
+	result, err := RotateOrderPII(db, 1)
+	if err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	if result.Scanned != 2 || result.Updated != 2 {
+		t.Fatalf("unexpected rotation result: %+v", result)
+	}
+	raw = orderPIIRow{}
+	if err := db.Table("orders").Where("id = ?", legacy.ID).Take(&raw).Error; err != nil {
+		t.Fatalf("load raw row failed: %v", err)
+	}
+	if piicrypt.KeyIDOf(raw.ReceiverName) != "k2" || raw.ReceiverEmailIndex == "" {
+		t.Fatalf("expected legacy row encrypted with k2, got %+v", raw)
+	}
+	again, err := RotateOrderPII(db, 10)
+	if err != nil || again.Updated != 0 {
+		t.Fatalf("expected rotation to be idempotent, got %+v, %v", again, err)
+	}
+
+	// 移除旧密钥后仍可读取
+	piicrypt.SetCurrent(newTestPIICipher(t, "k2", "k2"))
+	if err := db.First(&loaded, order.ID).Error; err != nil || loaded.ReceiverPhone != "138-0000-1111" {
+		t.Fatalf("expected order readable with new key only, got %q, %v", loaded.ReceiverPhone, err)
+	}
+}
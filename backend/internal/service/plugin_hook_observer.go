@@ -0,0 +1,29 @@
+package service
+
+// HookObserver 系统内部的 Hook 订阅者（如订单自动化规则）
+// 只接收 after 阶段事件，不受插件平台开关影响；实现需自行异步处理，不能阻塞调用方
+type HookObserver interface {
+	ObserveHook(hook string, payload map[string]interface{})
+}
+
+// AddHookObserver 注册内部 Hook 订阅者
+func (s *PluginManagerService) AddHookObserver(observer HookObserver) {
+	if s == nil || observer == nil {
+		return
+	}
+	s.hookObserverMu.Lock()
+	s.hookObservers = append(s.hookObservers, observer)
+	s.hookObserverMu.Unlock()
+}
+
+func (s *PluginManagerService) notifyHookObservers(hook string, payload map[string]interface{}) {
+	s.hookObserverMu.RLock()
+	observers := s.hookObservers
+	s.hookObserverMu.RUnlock()
+	if len(observers) == 0 || resolveHookDefinition(hook).Phase != hookPhaseAfter {
+		return
+	}
+	for _, observer := range observers {
+		observer.ObserveHook(hook, clonePayloadMap(payload))
+	}
+}
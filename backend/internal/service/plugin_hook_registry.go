@@ -54,11 +54,11 @@ var hookDefinitions = map[string]hookDefinition{
 	"auth.register.before": newRestrictedHookDefinition("auth.register.before", hookPhaseBefore, "email", "phone", "name", "password"),
 
 	"apikey.create.after":  newReadOnlyHookDefinition("apikey.create.after", hookPhaseAfter),
-	"apikey.create.before": newRestrictedHookDefinition("apikey.create.before", hookPhaseBefore, "key_name", "platform", "scopes", "rate_limit", "expires_at"),
+	"apikey.create.before": newRestrictedHookDefinition("apikey.create.before", hookPhaseBefore, "key_name", "platform", "scopes", "rate_limit", "burst_limit", "allow_duplicate_external_orders", "expires_at"),
 	"apikey.delete.after":  newReadOnlyHookDefinition("apikey.delete.after", hookPhaseAfter),
 	"apikey.delete.before": newReadOnlyHookDefinition("apikey.delete.before", hookPhaseBefore),
 	"apikey.update.after":  newReadOnlyHookDefinition("apikey.update.after", hookPhaseAfter),
-	"apikey.update.before": newRestrictedHookDefinition("apikey.update.before", hookPhaseBefore, "is_active", "rate_limit", "key_name"),
+	"apikey.update.before": newRestrictedHookDefinition("apikey.update.before", hookPhaseBefore, "is_active", "rate_limit", "burst_limit", "allow_duplicate_external_orders", "key_name"),
 
 	"announcement.create.after":  newReadOnlyHookDefinition("announcement.create.after", hookPhaseAfter),
 	"announcement.create.before": newRestrictedHookDefinition("announcement.create.before", hookPhaseBefore, "title", "content", "category", "send_email", "send_sms", "is_mandatory", "require_full_read"),
@@ -197,6 +197,10 @@ var hookDefinitions = map[string]hookDefinition{
 	"order.admin.refund_finalize.before": newRestrictedHookDefinition("order.admin.refund_finalize.before", hookPhaseBefore, "remark", "transaction_id"),
 	"order.admin.refund.after":           newReadOnlyHookDefinition("order.admin.refund.after", hookPhaseAfter),
 	"order.admin.refund.before":          newRestrictedHookDefinition("order.admin.refund.before", hookPhaseBefore, "reason"),
+	"order.admin.sub_status.after":       newReadOnlyHookDefinition("order.admin.sub_status.after", hookPhaseAfter),
+	"order.admin.sub_status.before":      newRestrictedHookDefinition("order.admin.sub_status.before", hookPhaseBefore, "sub_status", "note"),
+	"order.admin.tags.after":             newReadOnlyHookDefinition("order.admin.tags.after", hookPhaseAfter),
+	"order.admin.tags.before":            newRestrictedHookDefinition("order.admin.tags.before", hookPhaseBefore, "tags"),
 	"order.admin.update_price.after":     newReadOnlyHookDefinition("order.admin.update_price.after", hookPhaseAfter),
 	"order.admin.update_price.before":    newRestrictedHookDefinition("order.admin.update_price.before", hookPhaseBefore, "total_amount_minor"),
 	"order.admin.update_shipping.after":  newReadOnlyHookDefinition("order.admin.update_shipping.after", hookPhaseAfter),
@@ -219,6 +223,7 @@ var hookDefinitions = map[string]hookDefinition{
 	"order.complete.after":         newReadOnlyHookDefinition("order.complete.after", hookPhaseAfter),
 	"order.complete.before":        newRestrictedHookDefinition("order.complete.before", hookPhaseBefore, "feedback"),
 	"order.create.after":           newReadOnlyHookDefinition("order.create.after", hookPhaseAfter),
+	"order.payment_reminder.after": newReadOnlyHookDefinition("order.payment_reminder.after", hookPhaseAfter),
 	"order.create.before":          newRestrictedHookDefinition("order.create.before", hookPhaseBefore, "items", "remark", "promo_code"),
 	"order.status.changed.after":   newReadOnlyHookDefinition("order.status.changed.after", hookPhaseAfter),
 	"payment.confirm.after":        newReadOnlyHookDefinition("payment.confirm.after", hookPhaseAfter),
@@ -387,6 +392,12 @@ var hookDefinitions = map[string]hookDefinition{
 		"original_price_minor",
 		"stock",
 		"max_purchase_limit",
+		"price_tiers",
+		"min_order_quantity",
+		"max_order_quantity",
+		"quantity_increment",
+		"price_on_request",
+		"price_visible_tiers",
 		"images",
 		"attributes",
 		"status",
@@ -420,6 +431,12 @@ var hookDefinitions = map[string]hookDefinition{
 		"original_price_minor",
 		"stock",
 		"max_purchase_limit",
+		"price_tiers",
+		"min_order_quantity",
+		"max_order_quantity",
+		"quantity_increment",
+		"price_on_request",
+		"price_visible_tiers",
 		"images",
 		"attributes",
 		"status",
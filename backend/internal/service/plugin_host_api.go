@@ -521,6 +521,8 @@ func executePluginHostOrderList(
 	country := parsePluginHostOptionalString(params, "country")
 	productSearch := parsePluginHostOptionalString(params, "product_search", "productSearch")
 	promoCode := strings.ToUpper(parsePluginHostOptionalString(params, "promo_code", "promoCode"))
+	subStatus := parsePluginHostOptionalString(params, "sub_status", "subStatus")
+	tags := SplitOrderTagQuery(parsePluginHostOptionalString(params, "tag", "tags"))
 
 	var promoCodeID *uint
 	if parsed, ok, err := parsePluginHostOptionalUint(params, "promo_code_id", "promoCodeId"); err != nil {
@@ -536,7 +538,14 @@ func executePluginHostOrderList(
 		userID = &parsed
 	}
 
-	orders, total, err := orderRepo.List(page, pageSize, status, search, country, productSearch, promoCodeID, promoCode, userID)
+	var storeScope *repository.StoreScope
+	if parsed, ok, err := parsePluginHostOptionalUint(params, "store_id", "storeId"); err != nil {
+		return nil, &PluginHostActionError{Status: http.StatusBadRequest, Message: err.Error()}
+	} else if ok && parsed > 0 {
+		storeScope = &repository.StoreScope{StoreIDs: []uint{parsed}}
+	}
+
+	orders, total, err := orderRepo.List(page, pageSize, status, subStatus, tags, search, country, productSearch, promoCodeID, promoCode, userID, storeScope, nil)
 	if err != nil {
 		return nil, &PluginHostActionError{Status: http.StatusInternalServerError, Message: "query orders failed"}
 	}
@@ -841,8 +850,15 @@ func executePluginHostOrderUpdatePrice(
 		return nil, &PluginHostActionError{Status: http.StatusBadRequest, Message: orderbiz.UpdatePriceStatusInvalid(order.Status).Message}
 	}
 
+	oldAmount := order.TotalAmount
 	order.TotalAmount = totalAmountMinor
-	if err := orderRepo.Update(order); err != nil {
+	ReallocateOrderItemTotals(order)
+	if err := orderRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := tx.Save(order).Error; err != nil {
+			return err
+		}
+		return RecordOrderAdjustmentLedgerTx(tx, order, oldAmount, totalAmountMinor, "plugin_host", nil)
+	}); err != nil {
 		return nil, &PluginHostActionError{Status: http.StatusInternalServerError, Message: "update order price failed"}
 	}
 
@@ -1022,6 +1038,13 @@ func executePluginHostProductList(
 		return nil, &PluginHostActionError{Status: http.StatusBadRequest, Message: err.Error()}
 	}
 
+	var storeScope *repository.StoreScope
+	if parsed, ok, err := parsePluginHostOptionalUint(params, "store_id", "storeId"); err != nil {
+		return nil, &PluginHostActionError{Status: http.StatusBadRequest, Message: err.Error()}
+	} else if ok && parsed > 0 {
+		storeScope = &repository.StoreScope{StoreIDs: []uint{parsed}, IncludeShared: true}
+	}
+
 	products, total, err := productRepo.List(
 		page,
 		pageSize,
@@ -1031,6 +1054,7 @@ func executePluginHostProductList(
 		isFeatured,
 		isRecommended,
 		isActive != nil && *isActive,
+		storeScope,
 	)
 	if err != nil {
 		return nil, &PluginHostActionError{Status: http.StatusInternalServerError, Message: "query products failed"}
@@ -2520,6 +2544,7 @@ func buildPluginHostOrderSummaryResponse(order *models.Order, hasPrivacyPermissi
 		"order_no":              order.OrderNo,
 		"user_id":               order.UserID,
 		"status":                string(order.Status),
+		"sub_status":            order.SubStatus,
 		"items":                 order.Items,
 		"privacy_protected":     order.PrivacyProtected,
 		"privacy_masked":        order.PrivacyProtected && !hasPrivacyPermission,
@@ -2543,7 +2568,6 @@ func buildPluginHostOrderSummaryResponse(order *models.Order, hasPrivacyPermissi
 		"external_user_name":    order.ExternalUserName,
 		"external_order_id":     order.ExternalOrderID,
 		"remark":                order.Remark,
-		"admin_remark":          order.AdminRemark,
 		"assigned_to":           order.AssignedTo,
 		"assigned_at":           order.AssignedAt,
 		"shipped_at":            order.ShippedAt,
@@ -265,7 +265,7 @@ func TestExecutePluginHostActionAssignsTrackingByOrderNo(t *testing.T) {
 
 func TestExecutePluginHostActionRequestsResubmitByID(t *testing.T) {
 	db := openPluginManagerE2ETestDB(t)
-	if err := db.AutoMigrate(&models.User{}, &models.AdminPermission{}, &models.Order{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.AdminPermission{}, &models.Order{}, &models.OrderNote{}); err != nil {
 		t.Fatalf("auto migrate host api models failed: %v", err)
 	}
 
@@ -337,7 +337,7 @@ func TestExecutePluginHostActionRejectsResubmitForInvalidStatus(t *testing.T) {
 
 func TestExecutePluginHostActionMarksOrderPaidByOrderNo(t *testing.T) {
 	db := openPluginManagerE2ETestDB(t)
-	if err := db.AutoMigrate(&models.User{}, &models.AdminPermission{}, &models.Order{}, &models.VirtualProductStock{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.AdminPermission{}, &models.Order{}, &models.OrderNote{}, &models.VirtualProductStock{}, &models.LedgerEntry{}, &models.PaymentAmountReservation{}); err != nil {
 		t.Fatalf("auto migrate host api models failed: %v", err)
 	}
 
@@ -386,14 +386,18 @@ func TestExecutePluginHostActionMarksOrderPaidByOrderNo(t *testing.T) {
 	if updated.Status != models.OrderStatusPending {
 		t.Fatalf("expected persisted status=pending, got %s", updated.Status)
 	}
-	if updated.AdminRemark != "Paid via plugin reconciliation" {
-		t.Fatalf("expected admin remark to persist, got %#v", updated.AdminRemark)
+	var note models.OrderNote
+	if err := db.Where("order_id = ? AND source = ?", order.ID, models.OrderNoteSourceMarkPaid).First(&note).Error; err != nil {
+		t.Fatalf("load mark paid note failed: %v", err)
+	}
+	if note.Content != "Paid via plugin reconciliation" {
+		t.Fatalf("expected admin remark to be recorded as order note, got %#v", note.Content)
 	}
 }
 
 func TestExecutePluginHostActionUpdatesOrderPriceByOrderNo(t *testing.T) {
 	db := openPluginManagerE2ETestDB(t)
-	if err := db.AutoMigrate(&models.User{}, &models.AdminPermission{}, &models.Order{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.AdminPermission{}, &models.Order{}, &models.LedgerEntry{}, &models.PaymentAmountReservation{}); err != nil {
 		t.Fatalf("auto migrate host api models failed: %v", err)
 	}
 
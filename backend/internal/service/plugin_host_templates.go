@@ -205,6 +205,7 @@ func executePluginHostLandingPageSave(db *gorm.DB, params map[string]interface{}
 			return nil, conflictErr
 		}
 		page.HTMLContent = htmlContent
+		page.Mode = models.LandingPageModeHTML
 		if saveErr := db.Save(&page).Error; saveErr != nil {
 			return nil, &PluginHostActionError{Status: http.StatusInternalServerError, Message: "save landing page failed"}
 		}
@@ -343,6 +344,7 @@ func buildPluginHostLandingPageResponse(page *models.LandingPage) map[string]int
 		"page_key":     page.Slug,
 		"slug":         page.Slug,
 		"html_content": page.HTMLContent,
+		"mode":         page.Mode,
 		"is_active":    page.IsActive,
 		"updated_by":   page.UpdatedBy,
 		"digest":       pluginHostDigestString(page.HTMLContent),
@@ -50,6 +50,8 @@ type PluginManagerService struct {
 	hookLimiterMu            sync.Mutex
 	hookLimiter              chan struct{}
 	hookLimiterCap           int
+	hookObserverMu           sync.RWMutex
+	hookObservers            []HookObserver
 	auditLogQueue            chan pluginExecutionAuditEntry
 	auditLogWorkerWG         sync.WaitGroup
 	auditLogDropped          atomic.Uint64
@@ -768,6 +770,7 @@ func (s *PluginManagerService) ExecuteHook(req HookExecutionRequest, execCtx *Ex
 		FrontendExtensions: make([]FrontendExtension, 0),
 		PluginResults:      make([]HookPluginResult, 0),
 	}
+	s.notifyHookObservers(hook, payload)
 	if !s.isPluginPlatformEnabled() {
 		return result, nil
 	}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+)
+
+// normalizeProductOrderQuantityRules 校验商品的起订量、单笔上限与包装倍数配置是否自洽
+func normalizeProductOrderQuantityRules(product *models.Product) error {
+	if product.QuantityIncrement == 1 {
+		product.QuantityIncrement = 0
+	}
+	minQty, maxQty, increment := product.MinOrderQuantity, product.MaxOrderQuantity, product.QuantityIncrement
+	invalid := minQty < 0 || maxQty < 0 || increment < 0 ||
+		(maxQty > 0 && maxQty < minQty) ||
+		(increment > 0 && minQty > 0 && minQty%increment != 0) ||
+		(increment > 0 && maxQty > 0 && maxQty < increment)
+	if invalid {
+		return bizerr.New("product.orderQuantityRulesInvalid",
+			"Order quantity rules are invalid: maximum must not be below minimum, and minimum must be a multiple of the increment")
+	}
+	return nil
+}
+
+// checkProductLineQuantity 校验单个订单项或购物车项的数量是否满足起订量与包装倍数
+func checkProductLineQuantity(product *models.Product, quantity int) error {
+	if product == nil {
+		return nil
+	}
+	if product.MinOrderQuantity > 0 && quantity < product.MinOrderQuantity {
+		return bizerr.Newf("product.orderQuantityBelowMin",
+			"Product %s requires a minimum quantity of %d", product.Name, product.MinOrderQuantity).
+			WithParams(map[string]interface{}{"product": product.Name, "min": product.MinOrderQuantity, "quantity": quantity})
+	}
+	if product.QuantityIncrement > 1 && quantity%product.QuantityIncrement != 0 {
+		return bizerr.Newf("product.orderQuantityIncrement",
+			"Product %s is sold in multiples of %d", product.Name, product.QuantityIncrement).
+			WithParams(map[string]interface{}{"product": product.Name, "increment": product.QuantityIncrement, "quantity": quantity})
+	}
+	return nil
+}
+
+// checkProductOrderQuantity 校验同一订单（或购物车）内该商品所有规格合计数量是否超过单笔上限
+func checkProductOrderQuantity(product *models.Product, total int) error {
+	if product == nil || product.MaxOrderQuantity <= 0 || total <= product.MaxOrderQuantity {
+		return nil
+	}
+	return bizerr.Newf("product.orderQuantityAboveMax",
+		"Product %s allows at most %d per order", product.Name, product.MaxOrderQuantity).
+		WithParams(map[string]interface{}{"product": product.Name, "max": product.MaxOrderQuantity, "quantity": total})
+}
+
+// validateOrderItemQuantityRules 按商品数量规则校验订单项，单笔上限按同一商品合计计算
+func validateOrderItemQuantityRules(items []models.OrderItem, productBySKU map[string]*models.Product) error {
+	for _, item := range items {
+		if err := checkProductLineQuantity(productBySKU[item.SKU], item.Quantity); err != nil {
+			return err
+		}
+	}
+	for sku, total := range sumQuantitiesBySKU(items) {
+		if err := checkProductOrderQuantity(productBySKU[sku], total); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+)
+
+func TestNormalizeProductOrderQuantityRules(t *testing.T) {
+	valid := &models.Product{MinOrderQuantity: 10, MaxOrderQuantity: 100, QuantityIncrement: 5}
+	if err := normalizeProductOrderQuantityRules(valid); err != nil {
+		t.Fatalf("expected valid rules, got %v", err)
+	}
+	single := &models.Product{QuantityIncrement: 1}
+	if err := normalizeProductOrderQuantityRules(single); err != nil || single.QuantityIncrement != 0 {
+		t.Fatalf("expected increment 1 normalized to 0, got %d err=%v", single.QuantityIncrement, err)
+	}
+
+	for _, product := range []*models.Product{
+		{MinOrderQuantity: -1},
+		{MinOrderQuantity: 10, MaxOrderQuantity: 5},
+		{MinOrderQuantity: 7, QuantityIncrement: 5},
+		{MaxOrderQuantity: 3, QuantityIncrement: 5},
+	} {
+		requireOrderBizErr(t, normalizeProductOrderQuantityRules(product), "product.orderQuantityRulesInvalid")
+	}
+}
+
+func TestValidateOrderItemQuantityRules(t *testing.T) {
+	productBySKU := map[string]*models.Product{
+		"PACK-5": {SKU: "PACK-5", Name: "Pack", MinOrderQuantity: 5, MaxOrderQuantity: 20, QuantityIncrement: 5},
+	}
+	check := func(quantities ...int) error {
+		items := make([]models.OrderItem, 0, len(quantities))
+		for _, quantity := range quantities {
+			items = append(items, models.OrderItem{SKU: "PACK-5", Quantity: quantity})
+		}
+		return validateOrderItemQuantityRules(items, productBySKU)
+	}
+
+	if err := check(5, 15); err != nil {
+		t.Fatalf("expected 5+15 to pass, got %v", err)
+	}
+	bizErr := requireOrderBizErr(t, check(3), "product.orderQuantityBelowMin")
+	if bizErr.Params["min"] != 5 {
+		t.Fatalf("expected min param 5, got %+v", bizErr.Params)
+	}
+	bizErr = requireOrderBizErr(t, check(5, 12), "product.orderQuantityIncrement")
+	if bizErr.Params["increment"] != 5 || bizErr.Params["quantity"] != 12 {
+		t.Fatalf("unexpected increment params: %+v", bizErr.Params)
+	}
+	// 单笔上限按同一商品所有订单项合计
+	bizErr = requireOrderBizErr(t, check(10, 15), "product.orderQuantityAboveMax")
+	if bizErr.Params["max"] != 20 || bizErr.Params["quantity"] != 25 {
+		t.Fatalf("unexpected max params: %+v", bizErr.Params)
+	}
+}
+
+func TestCartQuantityRulesCountOtherVariants(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Product{}, &models.CartItem{})
+	product := &models.Product{SKU: "PACK-CART", Name: "Pack", Status: models.ProductStatusActive, MaxOrderQuantity: 10, QuantityIncrement: 2}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	existing := &models.CartItem{UserID: 1, ProductID: product.ID, SKU: product.SKU, Quantity: 6, Attributes: models.JSONMap{"color": "red"}}
+	if err := db.Create(existing).Error; err != nil {
+		t.Fatalf("create cart item: %v", err)
+	}
+	svc := NewCartService(repository.NewCartRepository(db), repository.NewProductRepository(db), nil, nil)
+
+	if err := svc.checkCartQuantityRules(product, 1, 0, 4); err != nil {
+		t.Fatalf("expected 6+4 within max, got %v", err)
+	}
+	requireOrderBizErr(t, svc.checkCartQuantityRules(product, 1, 0, 6), "product.orderQuantityAboveMax")
+	requireOrderBizErr(t, svc.checkCartQuantityRules(product, 1, 0, 3), "product.orderQuantityIncrement")
+	// 修改已有购物车项时不重复计入自身
+	if err := svc.checkCartQuantityRules(product, 1, existing.ID, 10); err != nil {
+		t.Fatalf("expected updating the existing item to 10 to pass, got %v", err)
+	}
+}
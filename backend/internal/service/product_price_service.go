@@ -0,0 +1,237 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/dbutil"
+	"gorm.io/gorm"
+)
+
+const (
+	productPriceScheduleCheckInterval = time.Minute
+	productPriceScheduleBatchSize     = 100
+)
+
+var ErrProductPriceScheduleNotFound = bizerr.New("productPrice.scheduleNotFound", "Price schedule not found")
+
+// recordProductPriceChangeTx 价格或划线价发生变化时写入历史
+func recordProductPriceChangeTx(tx *gorm.DB, product *models.Product, oldPrice, oldOriginalPrice int64, source string, changedBy *uint, scheduleID *uint) error {
+	if product.Price == oldPrice && product.OriginalPrice == oldOriginalPrice {
+		return nil
+	}
+	return tx.Create(&models.ProductPriceHistory{
+		ProductID:             product.ID,
+		OldPriceMinor:         oldPrice,
+		NewPriceMinor:         product.Price,
+		OldOriginalPriceMinor: oldOriginalPrice,
+		NewOriginalPriceMinor: product.OriginalPrice,
+		Source:                source,
+		ChangedBy:             changedBy,
+		PriceScheduleID:       scheduleID,
+	}).Error
+}
+
+// ProductPriceService 商品价格历史与定时调价
+type ProductPriceService struct {
+	db *gorm.DB
+
+	lifecycleMu sync.Mutex
+	running     bool
+	scheduler   *BackgroundScheduler
+}
+
+func NewProductPriceService(db *gorm.DB) *ProductPriceService {
+	return &ProductPriceService{db: db}
+}
+
+// ListHistory 商品价格变更记录（新到旧）
+func (s *ProductPriceService) ListHistory(productID uint, page, limit int) ([]models.ProductPriceHistory, int64, error) {
+	var (
+		items []models.ProductPriceHistory
+		total int64
+	)
+	query := s.db.Model(&models.ProductPriceHistory{}).Where("product_id = ?", productID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&items).Error
+	return items, total, err
+}
+
+// ListSchedules 商品定时调价（按生效时间排序）
+func (s *ProductPriceService) ListSchedules(productID uint) ([]models.ProductPriceSchedule, error) {
+	var items []models.ProductPriceSchedule
+	err := s.db.Where("product_id = ?", productID).Order("effective_at ASC, id ASC").Find(&items).Error
+	return items, err
+}
+
+// CreateSchedule 创建定时调价，生效时间必须晚于当前时间
+func (s *ProductPriceService) CreateSchedule(productID uint, priceMinor int64, originalPriceMinor *int64, effectiveAt time.Time, createdBy *uint) (*models.ProductPriceSchedule, error) {
+	if priceMinor < 0 {
+		return nil, bizerr.New("product.priceNegative", "Product price must be greater than or equal to 0")
+	}
+	if originalPriceMinor != nil && *originalPriceMinor < 0 {
+		return nil, bizerr.New("productPrice.originalPriceNegative", "Original price must be greater than or equal to 0")
+	}
+	if !effectiveAt.After(models.NowFunc()) {
+		return nil, bizerr.New("productPrice.effectiveAtInPast", "Effective time must be in the future")
+	}
+	var product models.Product
+	if err := s.db.Select("id").First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	schedule := &models.ProductPriceSchedule{
+		ProductID:          productID,
+		PriceMinor:         priceMinor,
+		OriginalPriceMinor: originalPriceMinor,
+		EffectiveAt:        effectiveAt,
+		Status:             models.PriceScheduleStatusPending,
+		CreatedBy:          createdBy,
+	}
+	if err := s.db.Create(schedule).Error; err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// CancelSchedule 取消尚未生效的定时调价
+func (s *ProductPriceService) CancelSchedule(productID, scheduleID uint) (*models.ProductPriceSchedule, error) {
+	var schedule models.ProductPriceSchedule
+	if err := s.db.Where("id = ? AND product_id = ?", scheduleID, productID).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductPriceScheduleNotFound
+		}
+		return nil, err
+	}
+	result := s.db.Model(&models.ProductPriceSchedule{}).
+		Where("id = ? AND status = ?", schedule.ID, models.PriceScheduleStatusPending).
+		Update("status", models.PriceScheduleStatusCancelled)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, bizerr.New("productPrice.scheduleNotPending", "Only pending price schedules can be cancelled").
+			WithParams(map[string]interface{}{"status": schedule.Status})
+	}
+	schedule.Status = models.PriceScheduleStatusCancelled
+	return &schedule, nil
+}
+
+// ApplyDueSchedules 应用已到期的定时调价，返回成功生效的数量
+func (s *ProductPriceService) ApplyDueSchedules(now time.Time) (int, error) {
+	var due []models.ProductPriceSchedule
+	if err := s.db.Where("status = ? AND effective_at <= ?", models.PriceScheduleStatusPending, now).
+		Order("effective_at ASC, id ASC").Limit(productPriceScheduleBatchSize).Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for i := range due {
+		if err := s.applySchedule(&due[i], now); err != nil {
+			log.Printf("product price schedule %d failed: %v", due[i].ID, err)
+			s.db.Model(&models.ProductPriceSchedule{}).
+				Where("id = ? AND status = ?", due[i].ID, models.PriceScheduleStatusPending).
+				Updates(map[string]interface{}{
+					"status": models.PriceScheduleStatusFailed,
+					"error":  err.Error(),
+				})
+			continue
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+func (s *ProductPriceService) applySchedule(schedule *models.ProductPriceSchedule, now time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// 抢占调度，避免多实例重复生效
+		claim := tx.Model(&models.ProductPriceSchedule{}).
+			Where("id = ? AND status = ?", schedule.ID, models.PriceScheduleStatusPending).
+			Updates(map[string]interface{}{
+				"status":     models.PriceScheduleStatusApplied,
+				"applied_at": now,
+			})
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := dbutil.LockForUpdate(tx, &models.Product{}, "id = ?", schedule.ProductID); err != nil {
+			return err
+		}
+		var product models.Product
+		if err := tx.Select("id", "price", "original_price").First(&product, schedule.ProductID).Error; err != nil {
+			return err
+		}
+		oldPrice := product.Price
+		oldOriginalPrice := product.OriginalPrice
+		product.Price = schedule.PriceMinor
+		if schedule.OriginalPriceMinor != nil {
+			product.OriginalPrice = *schedule.OriginalPriceMinor
+		}
+		if err := tx.Model(&models.Product{}).Where("id = ?", product.ID).Updates(map[string]interface{}{
+			"price":          product.Price,
+			"original_price": product.OriginalPrice,
+		}).Error; err != nil {
+			return err
+		}
+		scheduleID := schedule.ID
+		return recordProductPriceChangeTx(tx, &product, oldPrice, oldOriginalPrice, models.PriceChangeSourceScheduled, schedule.CreatedBy, &scheduleID)
+	})
+}
+
+// Start 启动定时调价服务
+func (s *ProductPriceService) Start() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	if s.running {
+		return
+	}
+
+	scheduler := backgroundSchedulerForService()
+	if err := scheduler.Register(BackgroundJob{
+		Name:       "product_price_schedule",
+		Interval:   productPriceScheduleCheckInterval,
+		RunOnStart: true,
+		LockTTL:    productPriceScheduleCheckInterval,
+		Jitter:     productPriceScheduleCheckInterval / 10,
+		Run: func() error {
+			s.runDueSchedules()
+			return nil
+		},
+	}); err != nil {
+		log.Printf("product price schedule background job register failed: %v", err)
+		return
+	}
+	s.scheduler = scheduler
+	s.running = true
+}
+
+// Stop 停止定时调价服务
+func (s *ProductPriceService) Stop() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	if !s.running {
+		return
+	}
+	s.running = false
+	s.scheduler.Remove("product_price_schedule")
+	s.scheduler = nil
+}
+
+func (s *ProductPriceService) runDueSchedules() {
+	if _, err := s.ApplyDueSchedules(models.NowFunc()); err != nil {
+		log.Printf("product price schedule check failed: %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func TestUpdateProductRecordsPriceHistory(t *testing.T) {
+	svc, db := newProductServiceTestDB(t)
+
+	product := &models.Product{
+		SKU:           "price-history",
+		Name:          "Price History",
+		Price:         1000,
+		OriginalPrice: 1500,
+		Status:        models.ProductStatusActive,
+		ProductType:   models.ProductTypePhysical,
+	}
+	if err := svc.CreateProduct(product); err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	adminID := uint(9)
+	updates := *product
+	updates.Price = 800
+	if err := svc.UpdateProductWithOptions(product.ID, &updates, UpdateProductOptions{ChangedBy: &adminID}); err != nil {
+		t.Fatalf("update price: %v", err)
+	}
+	// 未改价的更新不产生记录
+	updates.Name = "Renamed"
+	if err := svc.UpdateProduct(product.ID, &updates); err != nil {
+		t.Fatalf("update name: %v", err)
+	}
+
+	items, total, err := NewProductPriceService(db).ListHistory(product.ID, 1, 20)
+	if err != nil {
+		t.Fatalf("list history: %v", err)
+	}
+	if total != 1 || len(items) != 1 {
+		t.Fatalf("expected 1 history record, got %d", total)
+	}
+	record := items[0]
+	if record.OldPriceMinor != 1000 || record.NewPriceMinor != 800 || record.Source != models.PriceChangeSourceManual {
+		t.Fatalf("unexpected history record: %+v", record)
+	}
+	if record.ChangedBy == nil || *record.ChangedBy != adminID {
+		t.Fatalf("expected changed_by=%d, got %v", adminID, record.ChangedBy)
+	}
+}
+
+func TestProductPriceScheduleApplyAndCancel(t *testing.T) {
+	svc, db := newProductServiceTestDB(t)
+	priceService := NewProductPriceService(db)
+
+	product := &models.Product{
+		SKU:         "price-schedule",
+		Name:        "Price Schedule",
+		Price:       2000,
+		Status:      models.ProductStatusActive,
+		ProductType: models.ProductTypePhysical,
+	}
+	if err := svc.CreateProduct(product); err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	_, err := priceService.CreateSchedule(product.ID, 1500, nil, time.Now().Add(-time.Minute), nil)
+	requireProductBizErr(t, err, "productPrice.effectiveAtInPast")
+
+	original := int64(2500)
+	due, err := priceService.CreateSchedule(product.ID, 1500, &original, time.Now().Add(time.Hour), nil)
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	later, err := priceService.CreateSchedule(product.ID, 1200, nil, time.Now().Add(48*time.Hour), nil)
+	if err != nil {
+		t.Fatalf("create later schedule: %v", err)
+	}
+
+	applied, err := priceService.ApplyDueSchedules(time.Now().Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("apply schedules: %v", err)
+	}
+	if applied != 1 {
+		t.Fatalf("expected 1 applied schedule, got %d", applied)
+	}
+
+	var reloaded models.Product
+	if err := db.First(&reloaded, product.ID).Error; err != nil {
+		t.Fatalf("reload product: %v", err)
+	}
+	if reloaded.Price != 1500 || reloaded.OriginalPrice != 2500 {
+		t.Fatalf("unexpected price after schedule: price=%d original=%d", reloaded.Price, reloaded.OriginalPrice)
+	}
+
+	var history models.ProductPriceHistory
+	if err := db.Where("product_id = ?", product.ID).First(&history).Error; err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if history.Source != models.PriceChangeSourceScheduled || history.PriceScheduleID == nil || *history.PriceScheduleID != due.ID {
+		t.Fatalf("unexpected scheduled history: %+v", history)
+	}
+
+	// 重复执行不会再次生效
+	if applied, err := priceService.ApplyDueSchedules(time.Now().Add(2 * time.Hour)); err != nil || applied != 0 {
+		t.Fatalf("expected no further schedules, applied=%d err=%v", applied, err)
+	}
+
+	_, err = priceService.CancelSchedule(product.ID, due.ID)
+	requireProductBizErr(t, err, "productPrice.scheduleNotPending")
+
+	cancelled, err := priceService.CancelSchedule(product.ID, later.ID)
+	if err != nil {
+		t.Fatalf("cancel schedule: %v", err)
+	}
+	if cancelled.Status != models.PriceScheduleStatusCancelled {
+		t.Fatalf("expected cancelled status, got %s", cancelled.Status)
+	}
+	if applied, _ := priceService.ApplyDueSchedules(time.Now().Add(72 * time.Hour)); applied != 0 {
+		t.Fatalf("cancelled schedule must not apply, applied=%d", applied)
+	}
+}
+
+func TestProductPriceScheduleFailsForDeletedProduct(t *testing.T) {
+	svc, db := newProductServiceTestDB(t)
+	priceService := NewProductPriceService(db)
+
+	product := &models.Product{
+		SKU:         "price-schedule-deleted",
+		Name:        "Deleted",
+		Price:       100,
+		Status:      models.ProductStatusActive,
+		ProductType: models.ProductTypePhysical,
+	}
+	if err := svc.CreateProduct(product); err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	schedule, err := priceService.CreateSchedule(product.ID, 50, nil, time.Now().Add(time.Minute), nil)
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	if err := db.Delete(&models.Product{}, product.ID).Error; err != nil {
+		t.Fatalf("delete product: %v", err)
+	}
+
+	if applied, err := priceService.ApplyDueSchedules(time.Now().Add(time.Hour)); err != nil || applied != 0 {
+		t.Fatalf("expected no applied schedules, applied=%d err=%v", applied, err)
+	}
+	var reloaded models.ProductPriceSchedule
+	if err := db.First(&reloaded, schedule.ID).Error; err != nil {
+		t.Fatalf("reload schedule: %v", err)
+	}
+	if reloaded.Status != models.PriceScheduleStatusFailed || reloaded.Error == "" {
+		t.Fatalf("expected failed schedule, got %+v", reloaded)
+	}
+}
+
+func TestCreateUserOrderSnapshotsUnitPrice(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Product{}, &models.Order{})
+
+	cfg := &config.Config{}
+	cfg.Order.MaxOrderItems = 20
+	cfg.Order.MaxItemQuantity = 10
+	cfg.Order.Currency = "CNY"
+	cfg.Form.ExpireHours = 24
+
+	user := models.User{
+		UUID:         "price-snapshot-user",
+		Email:        "price-snapshot@example.com",
+		Name:         "price-snapshot",
+		Role:         "user",
+		IsActive:     true,
+		PasswordHash: "hash",
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user failed: %v", err)
+	}
+	product := models.Product{
+		SKU:              "SKU-PRICE-SNAPSHOT",
+		Name:             "Snapshot Product",
+		ProductType:      models.ProductTypeVirtual,
+		Status:           models.ProductStatusActive,
+		Price:            300,
+		MaxPurchaseLimit: 10,
+	}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product failed: %v", err)
+	}
+
+	svc := newConcurrentOrderService(db, cfg, nil)
+	order, err := svc.CreateUserOrder(user.ID, []models.OrderItem{{
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Quantity:    2,
+		ProductType: models.ProductTypeVirtual,
+	}}, "", "")
+	if err != nil {
+		t.Fatalf("create user order failed: %v", err)
+	}
+
+	// 下单后调价不影响订单项单价
+	if err := db.Model(&models.Product{}).Where("id = ?", product.ID).Update("price", 999).Error; err != nil {
+		t.Fatalf("update price failed: %v", err)
+	}
+	var reloaded models.Order
+	if err := db.First(&reloaded, order.ID).Error; err != nil {
+		t.Fatalf("reload order failed: %v", err)
+	}
+	if len(reloaded.Items) != 1 || reloaded.Items[0].UnitPriceMinor != 300 || reloaded.Items[0].LineTotalMinor != 600 {
+		t.Fatalf("expected unit price snapshot 300 and line total 600, got %+v", reloaded.Items)
+	}
+	if reloaded.TotalAmount != 600 {
+		t.Fatalf("expected total 600, got %d", reloaded.TotalAmount)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+)
+
+// 阶梯价与优惠码的叠加规则（order.tier_pricing_promo_stacking）
+const (
+	TierPromoStackingStack     = "stack"     // 优惠码按阶梯价后的小计计算
+	TierPromoStackingExclusive = "exclusive" // 已享受阶梯价时拒绝优惠码
+	TierPromoStackingBest      = "best"      // 阶梯价与优惠码取更优惠的一种
+)
+
+// sumQuantitiesBySKU 汇总订单内同一商品的数量，不同规格合并计档
+func sumQuantitiesBySKU(items []models.OrderItem) map[string]int {
+	quantities := make(map[string]int, len(items))
+	for _, item := range items {
+		quantities[item.SKU] += item.Quantity
+	}
+	return quantities
+}
+
+// applyTierPricing 以服务端商品价格为订单项定价（忽略客户端传入的值），按商品总数量套用阶梯价；
+// 返回阶梯价后的小计与原价小计
+func applyTierPricing(items []models.OrderItem, productBySKU map[string]*models.Product) (int64, int64) {
+	quantities := sumQuantitiesBySKU(items)
+	var totalAmount, baseAmount int64
+	for i := range items {
+		var unitPrice, basePrice int64
+		if product := productBySKU[items[i].SKU]; product != nil {
+			unitPrice = product.UnitPriceForQuantity(quantities[items[i].SKU])
+			basePrice = product.Price
+		}
+		items[i].UnitPriceMinor = unitPrice
+		totalAmount += items[i].Subtotal()
+		baseAmount += basePrice * int64(items[i].Quantity)
+	}
+	return totalAmount, baseAmount
+}
+
+// resolveTierPromoStacking 按配置的叠加规则决定优惠码是否生效；
+// best 规则下若原价使用优惠码更便宜，会把订单项改回原价并更新 totalAmount
+func (s *OrderService) resolveTierPromoStacking(items []models.OrderItem, productBySKU map[string]*models.Product, pc *models.PromoCode, totalAmount *int64, baseAmount int64) (bool, error) {
+	if *totalAmount >= baseAmount {
+		// 未触发任何阶梯价
+		return true, nil
+	}
+	switch s.cfg.Order.TierPricingPromoStacking {
+	case TierPromoStackingExclusive:
+		return false, bizerr.New("promo_code.notStackableWithTierPricing", "Promo codes cannot be combined with bulk pricing")
+	case TierPromoStackingBest:
+		if baseAmount-pc.CalculateDiscount(baseAmount) >= *totalAmount {
+			return false, nil
+		}
+		for i := range items {
+			if product := productBySKU[items[i].SKU]; product != nil {
+				items[i].UnitPriceMinor = product.Price
+			}
+		}
+		*totalAmount = baseAmount
+		return true, nil
+	default:
+		return true, nil
+	}
+}
+
+// cartQuantitiesByProduct 汇总购物车内同一商品的可购买总数量，不同规格合并计档
+func cartQuantitiesByProduct(items []models.CartItemWithStock) map[uint]int {
+	quantities := make(map[uint]int, len(items))
+	for _, item := range items {
+		if item.IsAvailable && item.Product != nil {
+			quantities[item.ProductID] += item.Quantity
+		}
+	}
+	return quantities
+}
+
+// applyCartTierPricing 为设置了阶梯价的购物车项刷新单价，与下单时计价一致
+func applyCartTierPricing(items []models.CartItemWithStock) {
+	quantities := cartQuantitiesByProduct(items)
+	for i := range items {
+		if !items[i].IsAvailable || items[i].Product == nil || len(items[i].Product.PriceTiers) == 0 {
+			continue
+		}
+		items[i].Price = items[i].Product.UnitPriceForQuantity(quantities[items[i].ProductID])
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func tieredTestProduct() *models.Product {
+	return &models.Product{
+		ID:    1,
+		SKU:   "BULK-1",
+		Price: 1000,
+		PriceTiers: []models.ProductPriceTier{
+			{MinQuantity: 10, PriceMinor: 900},
+			{MinQuantity: 50, PriceMinor: 800},
+		},
+	}
+}
+
+func TestUnitPriceForQuantityPicksHighestReachedTier(t *testing.T) {
+	product := tieredTestProduct()
+	cases := map[int]int64{1: 1000, 9: 1000, 10: 900, 49: 900, 50: 800, 500: 800}
+	for quantity, want := range cases {
+		if got := product.UnitPriceForQuantity(quantity); got != want {
+			t.Fatalf("quantity %d: expected %d, got %d", quantity, want, got)
+		}
+	}
+
+	// 定时调价把基础价降到档位价以下时，不应反而更贵
+	product.Price = 850
+	if got := product.UnitPriceForQuantity(10); got != 850 {
+		t.Fatalf("expected base price cap 850, got %d", got)
+	}
+}
+
+func TestNormalizeProductPriceTiers(t *testing.T) {
+	product := &models.Product{Price: 1000, PriceTiers: []models.ProductPriceTier{
+		{MinQuantity: 50, PriceMinor: 800},
+		{MinQuantity: 10, PriceMinor: 900},
+	}}
+	if err := normalizeProductPriceTiers(product); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if product.PriceTiers[0].MinQuantity != 10 || product.PriceTiers[1].MinQuantity != 50 {
+		t.Fatalf("expected tiers sorted by quantity, got %+v", product.PriceTiers)
+	}
+
+	invalid := []struct {
+		tiers []models.ProductPriceTier
+		key   string
+	}{
+		{[]models.ProductPriceTier{{MinQuantity: 1, PriceMinor: 900}}, "product.priceTierQuantityInvalid"},
+		{[]models.ProductPriceTier{{MinQuantity: 10, PriceMinor: 900}, {MinQuantity: 10, PriceMinor: 800}}, "product.priceTierQuantityInvalid"},
+		{[]models.ProductPriceTier{{MinQuantity: 10, PriceMinor: 1100}}, "product.priceTierPriceInvalid"},
+		{[]models.ProductPriceTier{{MinQuantity: 10, PriceMinor: 800}, {MinQuantity: 50, PriceMinor: 900}}, "product.priceTierPriceInvalid"},
+	}
+	for _, tc := range invalid {
+		err := normalizeProductPriceTiers(&models.Product{Price: 1000, PriceTiers: tc.tiers})
+		requireOrderBizErr(t, err, tc.key)
+	}
+}
+
+func TestApplyTierPricingMergesQuantityAcrossVariants(t *testing.T) {
+	productBySKU := map[string]*models.Product{"BULK-1": tieredTestProduct()}
+	items := []models.OrderItem{
+		{SKU: "BULK-1", Quantity: 6, Attributes: map[string]interface{}{"color": "red"}},
+		{SKU: "BULK-1", Quantity: 4, Attributes: map[string]interface{}{"color": "blue"}},
+	}
+	total, base := applyTierPricing(items, productBySKU)
+	if total != 9000 || base != 10000 {
+		t.Fatalf("expected total 9000 base 10000, got %d %d", total, base)
+	}
+	if items[0].UnitPriceMinor != 900 || items[1].UnitPriceMinor != 900 {
+		t.Fatalf("expected tier unit price on every variant, got %+v", items)
+	}
+}
+
+func TestResolveTierPromoStacking(t *testing.T) {
+	productBySKU := map[string]*models.Product{"BULK-1": tieredTestProduct()}
+	// 20% 折扣：原价 10000 用码后 8000，比阶梯价 9000 更便宜
+	percent := &models.PromoCode{DiscountType: models.DiscountTypePercentage, DiscountValue: 2000}
+	// 固定减 500：原价用码后 9500，不如阶梯价
+	fixed := &models.PromoCode{DiscountType: models.DiscountTypeFixed, DiscountValue: 500}
+
+	run := func(mode string, pc *models.PromoCode) (bool, int64, []models.OrderItem, error) {
+		svc := &OrderService{cfg: &config.Config{Order: config.OrderConfig{TierPricingPromoStacking: mode}}}
+		items := []models.OrderItem{{SKU: "BULK-1", Quantity: 10}}
+		total, base := applyTierPricing(items, productBySKU)
+		usePromo, err := svc.resolveTierPromoStacking(items, productBySKU, pc, &total, base)
+		return usePromo, total, items, err
+	}
+
+	if usePromo, total, _, err := run(TierPromoStackingStack, percent); err != nil || !usePromo || total != 9000 {
+		t.Fatalf("stack: expected promo on tier total 9000, got use=%v total=%d err=%v", usePromo, total, err)
+	}
+
+	_, _, _, err := run(TierPromoStackingExclusive, percent)
+	requireOrderBizErr(t, err, "promo_code.notStackableWithTierPricing")
+
+	usePromo, total, items, err := run(TierPromoStackingBest, percent)
+	if err != nil || !usePromo || total != 10000 || items[0].UnitPriceMinor != 1000 {
+		t.Fatalf("best/percent: expected base price with promo, got use=%v total=%d items=%+v err=%v", usePromo, total, items, err)
+	}
+
+	usePromo, total, items, err = run(TierPromoStackingBest, fixed)
+	if err != nil || usePromo || total != 9000 || items[0].UnitPriceMinor != 900 {
+		t.Fatalf("best/fixed: expected tier price without promo, got use=%v total=%d items=%+v err=%v", usePromo, total, items, err)
+	}
+
+	// 未达到任何档位时优惠码始终可用
+	svc := &OrderService{cfg: &config.Config{Order: config.OrderConfig{TierPricingPromoStacking: TierPromoStackingExclusive}}}
+	small := []models.OrderItem{{SKU: "BULK-1", Quantity: 2}}
+	total, base := applyTierPricing(small, productBySKU)
+	if usePromo, err := svc.resolveTierPromoStacking(small, productBySKU, percent, &total, base); err != nil || !usePromo {
+		t.Fatalf("expected promo allowed without tier pricing, got use=%v err=%v", usePromo, err)
+	}
+}
@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"auralogic/internal/config"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/constants"
 	"auralogic/internal/repository"
 	"gorm.io/gorm"
 )
@@ -25,6 +29,10 @@ type DeleteProductOptions struct {
 	DeleteImages bool
 }
 
+type UpdateProductOptions struct {
+	ChangedBy *uint // 记录价格变更操作人
+}
+
 var (
 	ErrProductNotFound = errors.New("Product not found")
 )
@@ -135,6 +143,28 @@ func (s *ProductService) CreateProduct(product *models.Product) error {
 	if product.Price < 0 {
 		return bizerr.New("product.priceNegative", "Product price must be greater than or equal to 0")
 	}
+	if err := normalizeProductPriceTiers(product); err != nil {
+		return err
+	}
+	if err := normalizeProductOrderQuantityRules(product); err != nil {
+		return err
+	}
+	product.PriceVisibleTiers = normalizeCustomerTiers(product.PriceVisibleTiers)
+	if err := normalizeProductSEOFields(product); err != nil {
+		return err
+	}
+	if product.ShipWithinDays < 0 || product.ShipWithinDays > 365 {
+		return newProductShipWithinDaysInvalidError()
+	}
+	if product.AutoCancelHours < 0 || product.AutoCancelHours > maxAutoCancelOverrideHours {
+		return newProductAutoCancelHoursInvalidError()
+	}
+	if err := normalizeProductShippingCountries(product); err != nil {
+		return err
+	}
+	if err := normalizeProductCustomsFields(product); err != nil {
+		return err
+	}
 
 	// 设置默认状态
 	if product.Status == "" {
@@ -150,6 +180,9 @@ func (s *ProductService) CreateProduct(product *models.Product) error {
 	if product.InventoryMode == "" {
 		product.InventoryMode = string(models.InventoryModeFixed)
 	}
+	if product.EmailDeliveryMode == "" {
+		product.EmailDeliveryMode = models.EmailDeliveryModeNone
+	}
 
 	// CreateProduct
 	if err := s.productRepo.Create(product); err != nil {
@@ -164,10 +197,17 @@ func (s *ProductService) CreateProduct(product *models.Product) error {
 
 // UpdateProduct UpdateProduct
 func (s *ProductService) UpdateProduct(id uint, updates *models.Product) error {
+	return s.UpdateProductWithOptions(id, updates, UpdateProductOptions{})
+}
+
+// UpdateProductWithOptions 更新商品，价格变动时记录价格历史
+func (s *ProductService) UpdateProductWithOptions(id uint, updates *models.Product, options UpdateProductOptions) error {
 	product, err := s.productRepo.FindByID(id)
 	if err != nil {
 		return ErrProductNotFound
 	}
+	oldPrice := product.Price
+	oldOriginalPrice := product.OriginalPrice
 
 	// 如果UpdateSKU，检查唯一性
 	if updates.SKU != "" && updates.SKU != product.SKU {
@@ -191,6 +231,36 @@ func (s *ProductService) UpdateProduct(id uint, updates *models.Product) error {
 	product.ShortDescription = updates.ShortDescription
 	product.Category = updates.Category
 	product.Remark = updates.Remark
+	product.StoreID = updates.StoreID
+	product.MetaTitle = updates.MetaTitle
+	product.MetaDescription = updates.MetaDescription
+	product.OGImage = updates.OGImage
+	if err := normalizeProductSEOFields(product); err != nil {
+		return err
+	}
+	if updates.ShipWithinDays < 0 || updates.ShipWithinDays > 365 {
+		return newProductShipWithinDaysInvalidError()
+	}
+	product.ShipWithinDays = updates.ShipWithinDays
+	if updates.AutoCancelHours < 0 || updates.AutoCancelHours > maxAutoCancelOverrideHours {
+		return newProductAutoCancelHoursInvalidError()
+	}
+	product.AutoCancelHours = updates.AutoCancelHours
+	product.ShippingAllowedCountries = updates.ShippingAllowedCountries
+	product.ShippingBlockedCountries = updates.ShippingBlockedCountries
+	product.HSCode = updates.HSCode
+	product.DeclaredValueMinor = updates.DeclaredValueMinor
+	product.WeightGrams = updates.WeightGrams
+	product.LengthMM = updates.LengthMM
+	product.WidthMM = updates.WidthMM
+	product.HeightMM = updates.HeightMM
+	product.OriginCountry = updates.OriginCountry
+	if err := normalizeProductShippingCountries(product); err != nil {
+		return err
+	}
+	if err := normalizeProductCustomsFields(product); err != nil {
+		return err
+	}
 
 	// 更新商品类型（允许在 physical 和 virtual 之间切换）
 	if updates.ProductType != "" {
@@ -202,8 +272,18 @@ func (s *ProductService) UpdateProduct(id uint, updates *models.Product) error {
 	}
 	product.Price = updates.Price
 	product.OriginalPrice = updates.OriginalPrice
+	product.PriceTiers = updates.PriceTiers
+	if err := normalizeProductPriceTiers(product); err != nil {
+		return err
+	}
 	product.Stock = updates.Stock
 	product.MaxPurchaseLimit = updates.MaxPurchaseLimit
+	product.MinOrderQuantity = updates.MinOrderQuantity
+	product.MaxOrderQuantity = updates.MaxOrderQuantity
+	product.QuantityIncrement = updates.QuantityIncrement
+	if err := normalizeProductOrderQuantityRules(product); err != nil {
+		return err
+	}
 
 	if updates.Images != nil {
 		product.Images = updates.Images
@@ -218,8 +298,20 @@ func (s *ProductService) UpdateProduct(id uint, updates *models.Product) error {
 	product.IsFeatured = updates.IsFeatured
 	product.IsRecommended = updates.IsRecommended
 	product.AutoDelivery = updates.AutoDelivery
+	product.ReserveOnCart = updates.ReserveOnCart
+	product.WaitingRoom = updates.WaitingRoom
+	product.PriceOnRequest = updates.PriceOnRequest
+	product.PriceVisibleTiers = normalizeCustomerTiers(updates.PriceVisibleTiers)
+	if updates.EmailDeliveryMode != "" {
+		product.EmailDeliveryMode = updates.EmailDeliveryMode
+	}
 
-	if err := s.productRepo.Update(product); err != nil {
+	if err := s.productRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := tx.Save(product).Error; err != nil {
+			return err
+		}
+		return recordProductPriceChangeTx(tx, product, oldPrice, oldOriginalPrice, models.PriceChangeSourceManual, options.ChangedBy, nil)
+	}); err != nil {
 		if isUniqueConstraintError(err) {
 			return newProductSKUAlreadyExistsError()
 		}
@@ -319,7 +411,7 @@ func (s *ProductService) GetProductBySKU(sku string) (*models.Product, error) {
 }
 
 // ListProducts getProduct列表
-func (s *ProductService) ListProducts(page, limit int, status, category, search string, isFeatured *bool, isRecommended *bool, isActive bool) ([]models.Product, int64, error) {
+func (s *ProductService) ListProducts(page, limit int, status, category, search string, isFeatured *bool, isRecommended *bool, isActive bool, storeScope *repository.StoreScope) ([]models.Product, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -327,7 +419,7 @@ func (s *ProductService) ListProducts(page, limit int, status, category, search
 		limit = 20
 	}
 
-	products, total, err := s.productRepo.List(page, limit, status, category, search, isFeatured, isRecommended, isActive)
+	products, total, err := s.productRepo.List(page, limit, status, category, search, isFeatured, isRecommended, isActive, storeScope)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -422,6 +514,118 @@ func (s *ProductService) ToggleFeatured(id uint) error {
 	return s.productRepo.Update(product)
 }
 
+// normalizeProductSEOFields 校验商品 SEO 字段
+func normalizeProductSEOFields(product *models.Product) error {
+	product.MetaTitle = strings.TrimSpace(product.MetaTitle)
+	product.MetaDescription = strings.TrimSpace(product.MetaDescription)
+	product.OGImage = strings.TrimSpace(product.OGImage)
+	if utf8.RuneCountInString(product.MetaTitle) > 255 {
+		return bizerr.New("product.metaTitleTooLong", "Meta title cannot exceed 255 characters")
+	}
+	if utf8.RuneCountInString(product.MetaDescription) > 500 {
+		return bizerr.New("product.metaDescriptionTooLong", "Meta description cannot exceed 500 characters")
+	}
+	if product.OGImage != "" {
+		lower := strings.ToLower(product.OGImage)
+		if len(product.OGImage) > 500 || strings.HasPrefix(lower, "//") ||
+			!(strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "/")) {
+			return bizerr.New("product.ogImageInvalid", "OG image must be an http(s) URL or a site-relative path")
+		}
+	}
+	return nil
+}
+
+// normalizeProductShippingCountries 配送限制国家统一为大写代码并去重，未知代码报错
+func normalizeProductShippingCountries(product *models.Product) error {
+	normalize := func(values []string) ([]string, error) {
+		if len(values) == 0 {
+			return nil, nil
+		}
+		seen := make(map[string]bool, len(values))
+		result := make([]string, 0, len(values))
+		for _, value := range values {
+			code := strings.ToUpper(strings.TrimSpace(value))
+			if code == "" || seen[code] {
+				continue
+			}
+			if constants.GetCountryByCode(code) == nil {
+				return nil, bizerr.Newf("product.shippingCountryInvalid", "Unknown country code %s", code).
+					WithParams(map[string]interface{}{"country": code})
+			}
+			seen[code] = true
+			result = append(result, code)
+		}
+		return result, nil
+	}
+	var err error
+	if product.ShippingAllowedCountries, err = normalize(product.ShippingAllowedCountries); err != nil {
+		return err
+	}
+	product.ShippingBlockedCountries, err = normalize(product.ShippingBlockedCountries)
+	return err
+}
+
+// maxProductPriceTiers 单个商品阶梯价档位上限
+const maxProductPriceTiers = 20
+
+// normalizeProductPriceTiers 按起订量排序并校验阶梯价：起订量须大于 1 且不重复，单价不高于基础价且随数量递减
+func normalizeProductPriceTiers(product *models.Product) error {
+	if len(product.PriceTiers) == 0 {
+		product.PriceTiers = nil
+		return nil
+	}
+	if len(product.PriceTiers) > maxProductPriceTiers {
+		return bizerr.Newf("product.priceTiersTooMany", "At most %d price tiers are allowed", maxProductPriceTiers).
+			WithParams(map[string]interface{}{"max": maxProductPriceTiers})
+	}
+	tiers := append([]models.ProductPriceTier(nil), product.PriceTiers...)
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinQuantity < tiers[j].MinQuantity })
+	previousPrice := product.Price
+	for i, tier := range tiers {
+		if tier.MinQuantity < 2 || (i > 0 && tier.MinQuantity == tiers[i-1].MinQuantity) {
+			return bizerr.New("product.priceTierQuantityInvalid", "Price tier quantities must be greater than 1 and unique")
+		}
+		if tier.PriceMinor < 0 || tier.PriceMinor > previousPrice {
+			return bizerr.New("product.priceTierPriceInvalid", "Price tier prices cannot exceed the base price or a lower-quantity tier")
+		}
+		previousPrice = tier.PriceMinor
+	}
+	product.PriceTiers = tiers
+	return nil
+}
+
+// hsCodePattern HS 编码：6 位国际通用部分，可附加各国细分至 10 位
+var hsCodePattern = regexp.MustCompile(`^\d{6}(\d{2}){0,2}$`)
+
+// normalizeProductCustomsFields 去除 HS 编码中的分隔符并校验报关与重量尺寸字段
+func normalizeProductCustomsFields(product *models.Product) error {
+	product.HSCode = strings.NewReplacer(".", "", " ", "", "-", "").Replace(strings.TrimSpace(product.HSCode))
+	if product.HSCode != "" && !hsCodePattern.MatchString(product.HSCode) {
+		return bizerr.New("product.hsCodeInvalid", "HS code must be 6, 8 or 10 digits")
+	}
+	product.OriginCountry = strings.ToUpper(strings.TrimSpace(product.OriginCountry))
+	if product.OriginCountry != "" && constants.GetCountryByCode(product.OriginCountry) == nil {
+		return bizerr.Newf("product.shippingCountryInvalid", "Unknown country code %s", product.OriginCountry).
+			WithParams(map[string]interface{}{"country": product.OriginCountry})
+	}
+	if product.DeclaredValueMinor < 0 || product.WeightGrams < 0 {
+		return bizerr.New("product.customsValueInvalid", "Declared value and weight cannot be negative")
+	}
+	if product.LengthMM < 0 || product.WidthMM < 0 || product.HeightMM < 0 {
+		return bizerr.New("product.dimensionsInvalid", "Dimensions cannot be negative")
+	}
+	return nil
+}
+
+func newProductShipWithinDaysInvalidError() error {
+	return bizerr.New("product.shipWithinDaysInvalid", "Ship-within days must be between 0 and 365")
+}
+
+func newProductAutoCancelHoursInvalidError() error {
+	return bizerr.Newf("product.autoCancelHoursInvalid", "Auto-cancel hours must be between 0 and %d", maxAutoCancelOverrideHours).
+		WithParams(map[string]interface{}{"max": maxAutoCancelOverrideHours})
+}
+
 func newProductSKUAlreadyExistsError() error {
 	return bizerr.New("product.skuAlreadyExists", "SKU already exists")
 }
@@ -27,6 +27,8 @@ func newProductServiceTestDB(t *testing.T) (*ProductService, *gorm.DB) {
 		&models.Product{},
 		&models.Inventory{},
 		&models.ProductInventoryBinding{},
+		&models.ProductPriceHistory{},
+		&models.ProductPriceSchedule{},
 	); err != nil {
 		t.Fatalf("auto migrate: %v", err)
 	}
@@ -132,3 +134,47 @@ func TestProductLookupReturnsSentinelError(t *testing.T) {
 		t.Fatalf("expected ErrProductNotFound, got %v", err)
 	}
 }
+
+func TestProductSEOFieldsValidation(t *testing.T) {
+	svc, db := newProductServiceTestDB(t)
+
+	requireProductBizErr(t, svc.CreateProduct(&models.Product{
+		SKU:     "sku-og-invalid",
+		Name:    "OG Invalid",
+		OGImage: "javascript:alert(1)",
+	}), "product.ogImageInvalid")
+	requireProductBizErr(t, svc.CreateProduct(&models.Product{
+		SKU:       "sku-meta-title",
+		Name:      "Meta Title",
+		MetaTitle: strings.Repeat("标", 256),
+	}), "product.metaTitleTooLong")
+
+	product := &models.Product{
+		SKU:             "sku-seo",
+		Name:            "SEO",
+		MetaTitle:       "  SEO title  ",
+		MetaDescription: "desc",
+		OGImage:         "/uploads/products/og.png",
+	}
+	if err := svc.CreateProduct(product); err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	if product.MetaTitle != "SEO title" {
+		t.Fatalf("expected trimmed meta title, got %q", product.MetaTitle)
+	}
+
+	requireProductBizErr(t, svc.UpdateProduct(product.ID, &models.Product{
+		Name:            "SEO",
+		MetaDescription: strings.Repeat("d", 501),
+	}), "product.metaDescriptionTooLong")
+	if err := svc.UpdateProduct(product.ID, &models.Product{Name: "SEO"}); err != nil {
+		t.Fatalf("clear seo fields: %v", err)
+	}
+	var stored models.Product
+	if err := db.First(&stored, product.ID).Error; err != nil {
+		t.Fatalf("load product: %v", err)
+	}
+	if stored.MetaTitle != "" || stored.OGImage != "" {
+		t.Fatalf("expected cleared seo fields, got %+v", stored)
+	}
+}
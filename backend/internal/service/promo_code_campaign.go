@@ -0,0 +1,228 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	maxPromoCodeCampaignSize     = 10000
+	maxPromoCodeLength           = 50
+	maxPromoCodePrefixLength     = 20
+	minPromoCodeRandomChars      = 6
+	defaultPromoCodeCampaignPart = "********"
+	promoCodeGenerateMaxRounds   = 5
+	promoCodeExistingCheckChunk  = 500
+)
+
+// 去掉易混淆的 0/O/1/I
+const (
+	promoCodeDigits   = "23456789"
+	promoCodeLetters  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	promoCodeAlphaNum = promoCodeLetters + promoCodeDigits
+)
+
+// normalizePromoCodeCampaignPattern 校验前缀和模式，返回规范化后的值
+func normalizePromoCodeCampaignPattern(prefix, pattern string) (string, string, error) {
+	prefix = strings.ToUpper(strings.TrimSpace(prefix))
+	pattern = strings.ToUpper(strings.TrimSpace(pattern))
+	if pattern == "" {
+		pattern = defaultPromoCodeCampaignPart
+	}
+
+	if len(prefix) > maxPromoCodePrefixLength || !isPromoCodeLiteral(prefix, "") {
+		return "", "", bizerr.Newf("promo_code.campaignPrefixInvalid", "Prefix may only contain letters, digits, '-' or '_' and at most %d characters", maxPromoCodePrefixLength).
+			WithParams(map[string]interface{}{"max": maxPromoCodePrefixLength})
+	}
+
+	placeholders := strings.Count(pattern, "#") + strings.Count(pattern, "?") + strings.Count(pattern, "*")
+	if !isPromoCodeLiteral(pattern, "#?*") || placeholders < minPromoCodeRandomChars || len(prefix)+len(pattern) > maxPromoCodeLength {
+		return "", "", bizerr.Newf("promo_code.campaignPatternInvalid", "Pattern must contain at least %d placeholders (# digit, ? letter, * letter or digit) and the code cannot exceed %d characters", minPromoCodeRandomChars, maxPromoCodeLength).
+			WithParams(map[string]interface{}{"min": minPromoCodeRandomChars, "max": maxPromoCodeLength})
+	}
+	return prefix, pattern, nil
+}
+
+func isPromoCodeLiteral(value, extra string) bool {
+	for _, r := range value {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		case strings.ContainsRune(extra, r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func randomPromoCodeChar(alphabet string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+	if err != nil {
+		return 0, err
+	}
+	return alphabet[n.Int64()], nil
+}
+
+// renderPromoCodePattern 按模式生成一个优惠码
+func renderPromoCodePattern(prefix, pattern string) (string, error) {
+	code := make([]byte, 0, len(prefix)+len(pattern))
+	code = append(code, prefix...)
+	for i := 0; i < len(pattern); i++ {
+		var alphabet string
+		switch pattern[i] {
+		case '#':
+			alphabet = promoCodeDigits
+		case '?':
+			alphabet = promoCodeLetters
+		case '*':
+			alphabet = promoCodeAlphaNum
+		default:
+			code = append(code, pattern[i])
+			continue
+		}
+		ch, err := randomPromoCodeChar(alphabet)
+		if err != nil {
+			return "", err
+		}
+		code = append(code, ch)
+	}
+	return string(code), nil
+}
+
+// generateUniquePromoCodes 生成 quantity 个互不重复且未被占用的优惠码
+func (s *PromoCodeService) generateUniquePromoCodes(prefix, pattern string, quantity int) ([]string, error) {
+	codes := make([]string, 0, quantity)
+	seen := make(map[string]struct{}, quantity)
+
+	for round := 0; round < promoCodeGenerateMaxRounds && len(codes) < quantity; round++ {
+		missing := quantity - len(codes)
+		candidates := make([]string, 0, missing)
+		// 限制尝试次数，模式空间过小时不会死循环
+		for attempts := 0; len(candidates) < missing && attempts < missing*10; attempts++ {
+			code, err := renderPromoCodePattern(prefix, pattern)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := seen[code]; ok {
+				continue
+			}
+			seen[code] = struct{}{}
+			candidates = append(candidates, code)
+		}
+
+		taken := make(map[string]struct{})
+		for start := 0; start < len(candidates); start += promoCodeExistingCheckChunk {
+			end := start + promoCodeExistingCheckChunk
+			if end > len(candidates) {
+				end = len(candidates)
+			}
+			existing, err := s.repo.FindExistingCodes(candidates[start:end])
+			if err != nil {
+				return nil, err
+			}
+			for _, code := range existing {
+				taken[code] = struct{}{}
+			}
+		}
+		for _, code := range candidates {
+			if _, ok := taken[code]; !ok {
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	if len(codes) < quantity {
+		return nil, bizerr.New("promo_code.campaignCodeSpaceExhausted", "Not enough unique codes for this pattern, use a longer pattern")
+	}
+	return codes, nil
+}
+
+// CreateCampaign 创建活动并批量生成一次性优惠码
+func (s *PromoCodeService) CreateCampaign(campaign *models.PromoCodeCampaign, quantity int) ([]models.PromoCode, error) {
+	campaign.Name = strings.TrimSpace(campaign.Name)
+	if campaign.Name == "" {
+		return nil, bizerr.New("promo_code.campaignNameRequired", "Campaign name cannot be empty")
+	}
+	if quantity < 1 || quantity > maxPromoCodeCampaignSize {
+		return nil, bizerr.Newf("promo_code.campaignQuantityInvalid", "Code quantity must be between 1 and %d", maxPromoCodeCampaignSize).
+			WithParams(map[string]interface{}{"max": maxPromoCodeCampaignSize})
+	}
+	prefix, pattern, err := normalizePromoCodeCampaignPattern(campaign.Prefix, campaign.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	campaign.Prefix = prefix
+	campaign.Pattern = pattern
+
+	codes, err := s.generateUniquePromoCodes(prefix, pattern, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign.CodeCount = len(codes)
+	promoCodes := make([]models.PromoCode, 0, len(codes))
+	for _, code := range codes {
+		promoCodes = append(promoCodes, models.PromoCode{
+			Code:           code,
+			Name:           campaign.Name,
+			Description:    campaign.Description,
+			DiscountType:   campaign.DiscountType,
+			DiscountValue:  campaign.DiscountValue,
+			MaxDiscount:    campaign.MaxDiscount,
+			MinOrderAmount: campaign.MinOrderAmount,
+			TotalQuantity:  1,
+			ProductIDs:     campaign.ProductIDs,
+			ProductScope:   campaign.ProductScope,
+			Status:         models.PromoCodeStatusActive,
+			ExpiresAt:      campaign.ExpiresAt,
+		})
+	}
+	if err := s.repo.CreateCampaign(campaign, promoCodes); err != nil {
+		return nil, err
+	}
+	return promoCodes, nil
+}
+
+// ListCampaigns 活动列表（附带核销统计）
+func (s *PromoCodeService) ListCampaigns(page, limit int) ([]models.PromoCodeCampaign, map[uint]repository.PromoCodeCampaignStats, int64, error) {
+	return s.repo.ListCampaigns(page, limit)
+}
+
+// GetCampaignCodes 活动下的优惠码及每个码最近一次使用记录
+func (s *PromoCodeService) GetCampaignCodes(campaignID uint) (*models.PromoCodeCampaign, []models.PromoCode, map[uint]models.PromoCodeRedemption, error) {
+	campaign, err := s.repo.FindCampaignByID(campaignID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, bizerr.New("promo_code.campaignNotFound", "Promo code campaign not found")
+		}
+		return nil, nil, nil, err
+	}
+	promoCodes, err := s.repo.ListByCampaign(campaign.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ids := make([]uint, 0, len(promoCodes))
+	for _, promoCode := range promoCodes {
+		ids = append(ids, promoCode.ID)
+	}
+	redemptions, err := s.repo.LatestRedemptions(ids)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return campaign, promoCodes, redemptions, nil
+}
+
+// ListRedemptions 单个优惠码的使用记录
+func (s *PromoCodeService) ListRedemptions(promoCodeID uint, page, limit int) ([]models.PromoCodeRedemption, int64, error) {
+	if _, err := s.repo.FindByID(promoCodeID); err != nil {
+		return nil, 0, translatePromoCodeLookupError(err)
+	}
+	return s.repo.ListRedemptions(promoCodeID, page, limit)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newPromoCodeCampaignServiceForTest(t *testing.T) (*PromoCodeService, *gorm.DB) {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.PromoCode{}, &models.PromoCodeCampaign{}, &models.PromoCodeRedemption{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	return NewPromoCodeService(repository.NewPromoCodeRepository(db), nil), db
+}
+
+func TestCreatePromoCodeCampaignGeneratesUniqueSingleUseCodes(t *testing.T) {
+	svc, db := newPromoCodeCampaignServiceForTest(t)
+
+	if err := db.Create(&models.PromoCode{Code: "VIP-AAAA", Name: "existing", DiscountType: models.DiscountTypeFixed, DiscountValue: 100, Status: models.PromoCodeStatusActive}).Error; err != nil {
+		t.Fatalf("create existing code: %v", err)
+	}
+
+	campaign := &models.PromoCodeCampaign{
+		Name:          "Influencer",
+		Prefix:        "vip-",
+		Pattern:       "??##-**",
+		DiscountType:  models.DiscountTypeFixed,
+		DiscountValue: 500,
+	}
+	codes, err := svc.CreateCampaign(campaign, 300)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	if len(codes) != 300 || campaign.CodeCount != 300 || campaign.Prefix != "VIP-" {
+		t.Fatalf("unexpected campaign result: codes=%d campaign=%+v", len(codes), campaign)
+	}
+
+	seen := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		if _, ok := seen[code.Code]; ok {
+			t.Fatalf("duplicate code generated: %s", code.Code)
+		}
+		seen[code.Code] = struct{}{}
+		if len(code.Code) != len("VIP-AA22-A2") || !strings.HasPrefix(code.Code, "VIP-") || code.Code[8] != '-' {
+			t.Fatalf("code does not follow pattern: %s", code.Code)
+		}
+		if strings.ContainsAny(code.Code[4:], "01IO") {
+			t.Fatalf("code contains ambiguous characters: %s", code.Code)
+		}
+		if code.TotalQuantity != 1 || code.CampaignID == nil || *code.CampaignID != campaign.ID {
+			t.Fatalf("expected single-use campaign code, got %+v", code)
+		}
+	}
+
+	var stored int64
+	db.Model(&models.PromoCode{}).Where("campaign_id = ?", campaign.ID).Count(&stored)
+	if stored != 300 {
+		t.Fatalf("expected 300 stored codes, got %d", stored)
+	}
+}
+
+func TestCreatePromoCodeCampaignValidatesInput(t *testing.T) {
+	svc, _ := newPromoCodeCampaignServiceForTest(t)
+
+	newCampaign := func(prefix, pattern string) *models.PromoCodeCampaign {
+		return &models.PromoCodeCampaign{Name: "Giveaway", Prefix: prefix, Pattern: pattern, DiscountType: models.DiscountTypeFixed, DiscountValue: 100}
+	}
+
+	_, err := svc.CreateCampaign(&models.PromoCodeCampaign{Name: " "}, 1)
+	requireProductBizErr(t, err, "promo_code.campaignNameRequired")
+	_, err = svc.CreateCampaign(newCampaign("", ""), 0)
+	requireProductBizErr(t, err, "promo_code.campaignQuantityInvalid")
+	_, err = svc.CreateCampaign(newCampaign("BAD PREFIX", ""), 1)
+	requireProductBizErr(t, err, "promo_code.campaignPrefixInvalid")
+	_, err = svc.CreateCampaign(newCampaign("GIFT", "###"), 1)
+	requireProductBizErr(t, err, "promo_code.campaignPatternInvalid")
+	_, err = svc.CreateCampaign(newCampaign("GIFT", "##%###"), 1)
+	requireProductBizErr(t, err, "promo_code.campaignPatternInvalid")
+
+	_, _, _, err = svc.GetCampaignCodes(999)
+	requireProductBizErr(t, err, "promo_code.campaignNotFound")
+}
+
+func TestPromoCodeCampaignTracksRedemptionPerCode(t *testing.T) {
+	svc, _ := newPromoCodeCampaignServiceForTest(t)
+
+	campaign := &models.PromoCodeCampaign{Name: "Giveaway", DiscountType: models.DiscountTypeFixed, DiscountValue: 100}
+	codes, err := svc.CreateCampaign(campaign, 3)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+
+	if err := svc.Reserve(codes[0].ID, "ORD-A"); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+	if err := svc.Deduct(codes[0].ID, "ORD-A"); err != nil {
+		t.Fatalf("deduct: %v", err)
+	}
+	// 一次性优惠码用过后不能再用
+	requireProductBizErr(t, svc.Reserve(codes[0].ID, "ORD-B"), "promo_code.exhausted")
+
+	if err := svc.Reserve(codes[1].ID, "ORD-C"); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+	if err := svc.ReleaseReserve(codes[1].ID, "ORD-C"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	_, promoCodes, latest, err := svc.GetCampaignCodes(campaign.ID)
+	if err != nil {
+		t.Fatalf("get campaign codes: %v", err)
+	}
+	if len(promoCodes) != 3 {
+		t.Fatalf("expected 3 campaign codes, got %d", len(promoCodes))
+	}
+	if r := latest[codes[0].ID]; r.Status != models.PromoCodeRedemptionRedeemed || r.OrderNo != "ORD-A" || r.RedeemedAt == nil {
+		t.Fatalf("unexpected redemption for redeemed code: %+v", r)
+	}
+	if r := latest[codes[1].ID]; r.Status != models.PromoCodeRedemptionReleased || r.ReleasedAt == nil {
+		t.Fatalf("unexpected redemption for released code: %+v", r)
+	}
+	if _, ok := latest[codes[2].ID]; ok {
+		t.Fatalf("unused code should have no redemption")
+	}
+
+	_, stats, _, err := svc.ListCampaigns(1, 20)
+	if err != nil {
+		t.Fatalf("list campaigns: %v", err)
+	}
+	if stats[campaign.ID].RedeemedCount != 1 || stats[campaign.ID].ReservedCount != 0 {
+		t.Fatalf("unexpected campaign stats: %+v", stats[campaign.ID])
+	}
+
+	items, total, err := svc.ListRedemptions(codes[1].ID, 1, 20)
+	if err != nil || total != 1 || len(items) != 1 || items[0].OrderNo != "ORD-C" {
+		t.Fatalf("unexpected redemptions: items=%+v total=%d err=%v", items, total, err)
+	}
+}
@@ -11,8 +11,9 @@ import (
 )
 
 type PromoCodeService struct {
-	repo        *repository.PromoCodeRepository
-	productRepo *repository.ProductRepository
+	repo                 *repository.PromoCodeRepository
+	productRepo          *repository.ProductRepository
+	giftPromotionService *GiftPromotionService
 }
 
 func NewPromoCodeService(repo *repository.PromoCodeRepository, productRepo *repository.ProductRepository) *PromoCodeService {
@@ -59,6 +60,7 @@ func (s *PromoCodeService) Update(id uint, updates *models.PromoCode) error {
 	existing.ProductScope = updates.ProductScope
 	existing.Status = updates.Status
 	existing.ExpiresAt = updates.ExpiresAt
+	existing.AutoApply = updates.AutoApply
 
 	return s.repo.Update(existing)
 }
@@ -118,6 +120,9 @@ func (s *PromoCodeService) ValidateCode(code string, productIDs []uint, orderAmo
 		return nil, 0, translatePromoCodeLookupError(err)
 	}
 
+	if promoCode.IsExhausted() {
+		return nil, 0, newPromoCodeExhaustedError()
+	}
 	if !promoCode.IsAvailable() {
 		return nil, 0, bizerr.New("promo_code.unavailable", "Promo code is not available")
 	}
@@ -148,7 +153,7 @@ func (s *PromoCodeService) ValidateCode(code string, productIDs []uint, orderAmo
 
 // Reserve 预留优惠码
 func (s *PromoCodeService) Reserve(promoCodeID uint, orderNo string) error {
-	return s.repo.Reserve(promoCodeID, orderNo)
+	return translatePromoCodeReserveError(s.repo.Reserve(promoCodeID, orderNo))
 }
 
 // ReleaseReserve 释放优惠码预留
@@ -170,3 +175,20 @@ func translatePromoCodeLookupError(err error) error {
 	}
 	return err
 }
+
+func newPromoCodeExhaustedError() error {
+	return bizerr.New("promo_code.exhausted", "Promo code usage limit has been reached")
+}
+
+// translatePromoCodeReserveError 预留失败转换为业务错误
+func translatePromoCodeReserveError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrPromoCodeExhausted):
+		return newPromoCodeExhaustedError()
+	case errors.Is(err, repository.ErrPromoCodeUnavailable):
+		return bizerr.New("promo_code.unavailable", "Promo code is not available")
+	}
+	return translatePromoCodeLookupError(err)
+}
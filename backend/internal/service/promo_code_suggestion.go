@@ -0,0 +1,185 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+const maxPromoCodeSuggestions = 10
+
+// CheckoutLine 结算推荐使用的商品行
+type CheckoutLine struct {
+	ProductID uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+// PromoCodeSuggestion 推荐的优惠码
+// 未达到最低订单金额时 Eligible 为 false，ShortfallMinor 为还差的金额，可用于“再买 X 元可用”提示
+type PromoCodeSuggestion struct {
+	PromoCodeID         uint                `json:"promo_code_id"`
+	Code                string              `json:"code"`
+	Name                string              `json:"name"`
+	Description         string              `json:"description,omitempty"`
+	DiscountType        models.DiscountType `json:"discount_type"`
+	DiscountValueMinor  int64               `json:"discount_value_minor"`
+	MaxDiscountMinor    int64               `json:"max_discount_minor"`
+	MinOrderAmountMinor int64               `json:"min_order_amount_minor"`
+	SavingsMinor        int64               `json:"savings_minor"`
+	Eligible            bool                `json:"eligible"`
+	ShortfallMinor      int64               `json:"shortfall_minor"`
+	ExpiresAt           *time.Time          `json:"expires_at,omitempty"`
+}
+
+// AutomaticPromotionSuggestion 无需输入优惠码、下单时自动生效的活动
+type AutomaticPromotionSuggestion struct {
+	Type         string    `json:"type"` // gift
+	PromotionID  uint      `json:"promotion_id"`
+	Name         string    `json:"name"`
+	SavingsMinor int64     `json:"savings_minor"`
+	Gift         *GiftLine `json:"gift,omitempty"`
+}
+
+// CheckoutSuggestions 结算优惠推荐结果
+type CheckoutSuggestions struct {
+	SubtotalMinor       int64                          `json:"subtotal_minor"`
+	Best                *PromoCodeSuggestion           `json:"best"`
+	PromoCodes          []PromoCodeSuggestion          `json:"promo_codes"`
+	AutomaticPromotions []AutomaticPromotionSuggestion `json:"automatic_promotions"`
+	TotalSavingsMinor   int64                          `json:"total_savings_minor"` // 最佳优惠码 + 自动活动
+}
+
+// SetGiftPromotionService 注入满赠活动服务，用于结算推荐中的自动活动
+func (s *PromoCodeService) SetGiftPromotionService(giftPromotionService *GiftPromotionService) {
+	s.giftPromotionService = giftPromotionService
+}
+
+// SuggestForCheckout 按结算商品计算可推荐的公开优惠码和自动活动
+// 小计按商品当前价格（含阶梯价）计算，商品范围、最低金额和剩余名额的判断与下单一致；订单只能使用一个优惠码，Best 为节省最多的可用码
+func (s *PromoCodeService) SuggestForCheckout(lines []CheckoutLine) (*CheckoutSuggestions, error) {
+	result := &CheckoutSuggestions{
+		PromoCodes:          []PromoCodeSuggestion{},
+		AutomaticPromotions: []AutomaticPromotionSuggestion{},
+	}
+
+	ids := make([]uint, 0, len(lines))
+	for _, line := range lines {
+		if line.ProductID > 0 && line.Quantity > 0 {
+			ids = append(ids, line.ProductID)
+		}
+	}
+	productByID, err := s.productRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同一商品多行合并数量计算阶梯价
+	quantities := make(map[uint]int, len(lines))
+	for _, line := range lines {
+		if line.Quantity > 0 {
+			quantities[line.ProductID] += line.Quantity
+		}
+	}
+	productIDs := make([]uint, 0, len(productByID))
+	seen := make(map[uint]bool, len(productByID))
+	for _, line := range lines {
+		product := productByID[line.ProductID]
+		if product == nil || line.Quantity <= 0 || product.Status != models.ProductStatusActive {
+			continue
+		}
+		result.SubtotalMinor += product.UnitPriceForQuantity(quantities[line.ProductID]) * int64(line.Quantity)
+		if !seen[product.ID] {
+			seen[product.ID] = true
+			productIDs = append(productIDs, product.ID)
+		}
+	}
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+
+	candidates, err := s.repo.ListAutoApplyCandidates()
+	if err != nil {
+		return nil, err
+	}
+	for i := range candidates {
+		promoCode := &candidates[i]
+		if !promoCode.IsAvailable() || !promoCodeApplicableToAny(promoCode, productIDs) {
+			continue
+		}
+		suggestion := PromoCodeSuggestion{
+			PromoCodeID:         promoCode.ID,
+			Code:                promoCode.Code,
+			Name:                promoCode.Name,
+			Description:         promoCode.Description,
+			DiscountType:        promoCode.DiscountType,
+			DiscountValueMinor:  promoCode.DiscountValue,
+			MaxDiscountMinor:    promoCode.MaxDiscount,
+			MinOrderAmountMinor: promoCode.MinOrderAmount,
+			ExpiresAt:           promoCode.ExpiresAt,
+		}
+		if result.SubtotalMinor < promoCode.MinOrderAmount {
+			suggestion.ShortfallMinor = promoCode.MinOrderAmount - result.SubtotalMinor
+		} else {
+			suggestion.SavingsMinor = promoCode.CalculateDiscount(result.SubtotalMinor)
+			suggestion.Eligible = suggestion.SavingsMinor > 0
+		}
+		result.PromoCodes = append(result.PromoCodes, suggestion)
+	}
+
+	// 可用的按节省金额降序，不可用的排在后面并按差额升序
+	sort.SliceStable(result.PromoCodes, func(i, j int) bool {
+		a, b := result.PromoCodes[i], result.PromoCodes[j]
+		if a.Eligible != b.Eligible {
+			return a.Eligible
+		}
+		if a.Eligible {
+			return a.SavingsMinor > b.SavingsMinor
+		}
+		return a.ShortfallMinor < b.ShortfallMinor
+	})
+	if len(result.PromoCodes) > maxPromoCodeSuggestions {
+		result.PromoCodes = result.PromoCodes[:maxPromoCodeSuggestions]
+	}
+	if len(result.PromoCodes) > 0 && result.PromoCodes[0].Eligible {
+		best := result.PromoCodes[0]
+		result.Best = &best
+		result.TotalSavingsMinor += best.SavingsMinor
+	}
+
+	if s.giftPromotionService != nil {
+		gifts, err := s.giftPromotionService.ResolveGifts(result.SubtotalMinor, productIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range gifts {
+			gift := gifts[i]
+			if !gift.Available {
+				continue
+			}
+			result.AutomaticPromotions = append(result.AutomaticPromotions, AutomaticPromotionSuggestion{
+				Type:         "gift",
+				PromotionID:  gift.PromotionID,
+				Name:         gift.PromotionName,
+				SavingsMinor: gift.ValueMinor,
+				Gift:         &gift,
+			})
+			result.TotalSavingsMinor += gift.ValueMinor
+		}
+	}
+
+	return result, nil
+}
+
+// promoCodeApplicableToAny 优惠码是否适用于任一商品（与下单校验一致）
+func promoCodeApplicableToAny(promoCode *models.PromoCode, productIDs []uint) bool {
+	if len(promoCode.ProductIDs) == 0 {
+		return true
+	}
+	for _, id := range productIDs {
+		if promoCode.IsApplicableToProduct(id) {
+			return true
+		}
+	}
+	return false
+}
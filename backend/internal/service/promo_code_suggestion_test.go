@@ -0,0 +1,122 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+)
+
+func TestSuggestForCheckoutRanksApplicablePromoCodes(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Product{}, &models.PromoCode{}, &models.GiftPromotion{})
+
+	shirt := models.Product{SKU: "SUG-SHIRT", Name: "Shirt", Price: 5000, Status: models.ProductStatusActive}
+	mug := models.Product{SKU: "SUG-MUG", Name: "Mug", Price: 2000, Status: models.ProductStatusActive}
+	draft := models.Product{SKU: "SUG-DRAFT", Name: "Draft", Price: 90000, Status: models.ProductStatusDraft}
+	for _, product := range []*models.Product{&shirt, &mug, &draft} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("create product failed: %v", err)
+		}
+	}
+
+	campaignID := uint(1)
+	promoCodes := []models.PromoCode{
+		{Code: "SAVE10", Name: "10%", DiscountType: models.DiscountTypePercentage, DiscountValue: 1000, AutoApply: true, Status: models.PromoCodeStatusActive},
+		{Code: "FLAT1500", Name: "Flat", DiscountType: models.DiscountTypeFixed, DiscountValue: 1500, AutoApply: true, Status: models.PromoCodeStatusActive},
+		{Code: "BIG", Name: "Big spender", DiscountType: models.DiscountTypeFixed, DiscountValue: 5000, MinOrderAmount: 20000, AutoApply: true, Status: models.PromoCodeStatusActive},
+		{Code: "MUGONLY", Name: "Mugs", DiscountType: models.DiscountTypeFixed, DiscountValue: 9000, ProductIDs: []uint{9999}, ProductScope: "include", AutoApply: true, Status: models.PromoCodeStatusActive},
+		{Code: "PRIVATE", Name: "Private", DiscountType: models.DiscountTypeFixed, DiscountValue: 9000, Status: models.PromoCodeStatusActive},
+		{Code: "SOLDOUT", Name: "Sold out", DiscountType: models.DiscountTypeFixed, DiscountValue: 9000, TotalQuantity: 1, UsedQuantity: 1, AutoApply: true, Status: models.PromoCodeStatusActive},
+		{Code: "CAMPAIGN", Name: "Campaign", DiscountType: models.DiscountTypeFixed, DiscountValue: 9000, CampaignID: &campaignID, AutoApply: true, Status: models.PromoCodeStatusActive},
+	}
+	for i := range promoCodes {
+		if err := db.Create(&promoCodes[i]).Error; err != nil {
+			t.Fatalf("create promo code failed: %v", err)
+		}
+	}
+
+	svc := NewPromoCodeService(repository.NewPromoCodeRepository(db), repository.NewProductRepository(db))
+	result, err := svc.SuggestForCheckout([]CheckoutLine{
+		{ProductID: shirt.ID, Quantity: 2},
+		{ProductID: mug.ID, Quantity: 1},
+		{ProductID: draft.ID, Quantity: 1},
+	})
+	if err != nil {
+		t.Fatalf("suggest failed: %v", err)
+	}
+
+	if result.SubtotalMinor != 12000 {
+		t.Fatalf("expected subtotal 12000 excluding inactive product, got %d", result.SubtotalMinor)
+	}
+	codes := make([]string, 0, len(result.PromoCodes))
+	for _, suggestion := range result.PromoCodes {
+		codes = append(codes, suggestion.Code)
+	}
+	if len(codes) != 3 || codes[0] != "FLAT1500" || codes[1] != "SAVE10" || codes[2] != "BIG" {
+		t.Fatalf("unexpected suggestion order: %v", codes)
+	}
+	if result.Best == nil || result.Best.Code != "FLAT1500" || result.Best.SavingsMinor != 1500 {
+		t.Fatalf("expected FLAT1500 as best, got %+v", result.Best)
+	}
+	big := result.PromoCodes[2]
+	if big.Eligible || big.SavingsMinor != 0 || big.ShortfallMinor != 8000 {
+		t.Fatalf("expected BIG to be ineligible with 8000 shortfall, got %+v", big)
+	}
+	if result.TotalSavingsMinor != 1500 {
+		t.Fatalf("expected total savings 1500, got %d", result.TotalSavingsMinor)
+	}
+}
+
+func TestSuggestForCheckoutIncludesGiftPromotions(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Product{}, &models.PromoCode{}, &models.GiftPromotion{})
+
+	shirt := models.Product{SKU: "SUG-GIFT-SHIRT", Name: "Shirt", Price: 5000, Status: models.ProductStatusActive}
+	tote := models.Product{SKU: "SUG-GIFT-TOTE", Name: "Tote", Price: 800, ProductType: models.ProductTypePhysical, Status: models.ProductStatusActive}
+	for _, product := range []*models.Product{&shirt, &tote} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("create product failed: %v", err)
+		}
+	}
+	giftInventory := models.Inventory{Name: "Tote stock", Stock: 5, AvailableQuantity: 5, IsActive: true}
+	if err := db.Create(&giftInventory).Error; err != nil {
+		t.Fatalf("create gift inventory failed: %v", err)
+	}
+
+	giftSvc := NewGiftPromotionService(db)
+	promotion, err := giftSvc.Create(GiftPromotionInput{
+		Name:            "Free tote over 100",
+		ThresholdMinor:  10000,
+		GiftProductID:   tote.ID,
+		GiftInventoryID: giftInventory.ID,
+		GiftQuantity:    2,
+	})
+	if err != nil {
+		t.Fatalf("create gift promotion failed: %v", err)
+	}
+
+	svc := NewPromoCodeService(repository.NewPromoCodeRepository(db), repository.NewProductRepository(db))
+	svc.SetGiftPromotionService(giftSvc)
+
+	result, err := svc.SuggestForCheckout([]CheckoutLine{{ProductID: shirt.ID, Quantity: 1}})
+	if err != nil {
+		t.Fatalf("suggest failed: %v", err)
+	}
+	if len(result.AutomaticPromotions) != 0 || result.Best != nil {
+		t.Fatalf("expected no promotions below threshold, got %+v", result)
+	}
+
+	result, err = svc.SuggestForCheckout([]CheckoutLine{{ProductID: shirt.ID, Quantity: 2}})
+	if err != nil {
+		t.Fatalf("suggest failed: %v", err)
+	}
+	if len(result.AutomaticPromotions) != 1 {
+		t.Fatalf("expected one automatic promotion, got %+v", result.AutomaticPromotions)
+	}
+	auto := result.AutomaticPromotions[0]
+	if auto.Type != "gift" || auto.PromotionID != promotion.ID || auto.SavingsMinor != 1600 || auto.Gift == nil || auto.Gift.SKU != tote.SKU {
+		t.Fatalf("unexpected gift suggestion: %+v", auto)
+	}
+	if result.TotalSavingsMinor != 1600 {
+		t.Fatalf("expected total savings 1600, got %d", result.TotalSavingsMinor)
+	}
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	maxQuoteRequestQuantity      = 1000000
+	maxQuoteRequestMessageLength = 2000
+	maxQuoteValidDays            = 365
+	// 每个用户同时处于待报价/已报价状态的询价单上限，防止刷单
+	maxOpenQuoteRequestsPerUser = 20
+	maxCustomerTiers            = 20
+	maxCustomerTierLength       = 50
+)
+
+var ErrQuoteRequestNotFound = bizerr.New("quote.notFound", "Quote request not found")
+
+func newProductPriceOnRequestError(productName string) error {
+	return bizerr.Newf("product.priceOnRequest", "Product %s is price on request, please submit a quote request", productName).
+		WithParams(map[string]interface{}{"product": productName})
+}
+
+func newQuoteStatusInvalidError(status models.QuoteRequestStatus) error {
+	return bizerr.Newf("quote.statusInvalid", "Quote request in status %s cannot be changed this way", status).
+		WithParams(map[string]interface{}{"status": status})
+}
+
+// NormalizeCustomerTier 客户等级统一去空格并转小写，超长时返回错误
+func NormalizeCustomerTier(tier string) (string, error) {
+	tier = strings.ToLower(strings.TrimSpace(tier))
+	if utf8.RuneCountInString(tier) > maxCustomerTierLength {
+		return "", bizerr.Newf("user.customerTierInvalid", "Customer tier must be at most %d characters", maxCustomerTierLength).
+			WithParams(map[string]interface{}{"max": maxCustomerTierLength})
+	}
+	return tier, nil
+}
+
+// normalizeCustomerTiers 去重并丢弃空值与超长值，最多保留 maxCustomerTiers 个
+func normalizeCustomerTiers(tiers []string) []string {
+	result := make([]string, 0, len(tiers))
+	seen := make(map[string]bool, len(tiers))
+	for _, tier := range tiers {
+		normalized, err := NormalizeCustomerTier(tier)
+		if err != nil || normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+		if len(result) >= maxCustomerTiers {
+			break
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// QuoteRequestInput 客户提交询价单参数
+type QuoteRequestInput struct {
+	ProductID    uint              `json:"product_id"`
+	Quantity     int               `json:"quantity"`
+	Attributes   map[string]string `json:"attributes"`
+	Message      string            `json:"message"`
+	Company      string            `json:"company"`
+	ContactName  string            `json:"contact_name"`
+	ContactEmail string            `json:"contact_email"`
+	ContactPhone string            `json:"contact_phone"`
+}
+
+// QuoteOfferInput 商家报价参数，ValidDays 为 0 表示报价长期有效
+type QuoteOfferInput struct {
+	UnitPriceMinor int64  `json:"unit_price_minor"`
+	ValidDays      int    `json:"valid_days"`
+	Reply          string `json:"reply"`
+}
+
+// QuoteRequestListFilter 后台询价单筛选
+type QuoteRequestListFilter struct {
+	Status string
+	Search string
+}
+
+// QuoteRequestService 询价模式商品的询价单：客户提交 -> 商家报价 -> 客户接受/拒绝
+// 客户接受后由商家按报价手动创建订单
+type QuoteRequestService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewQuoteRequestService(db *gorm.DB, cfg *config.Config) *QuoteRequestService {
+	return &QuoteRequestService{db: db, cfg: cfg}
+}
+
+// CustomerTier 用户的客户等级，未登录或查询失败时为空
+func (s *QuoteRequestService) CustomerTier(userID *uint) string {
+	if s == nil || userID == nil || *userID == 0 {
+		return ""
+	}
+	var user models.User
+	if err := s.db.Select("id", "customer_tier").First(&user, *userID).Error; err != nil {
+		return ""
+	}
+	return user.CustomerTier
+}
+
+// ApplyPriceVisibility 对当前访客隐藏询价商品的价格
+func (s *QuoteRequestService) ApplyPriceVisibility(products []models.Product, userID *uint) {
+	tierLoaded := false
+	tier := ""
+	for i := range products {
+		if !products[i].PriceOnRequest {
+			continue
+		}
+		if !tierLoaded {
+			tier = s.CustomerTier(userID)
+			tierLoaded = true
+		}
+		if products[i].PriceHiddenFor(tier) {
+			products[i].HidePrice()
+		}
+		// 可见价等级属于后台配置，不下发到前台
+		products[i].PriceVisibleTiers = nil
+	}
+}
+
+func validateQuoteAttributes(product *models.Product, attributes map[string]string) (models.JSONMap, error) {
+	result := make(models.JSONMap)
+	for _, attr := range product.Attributes {
+		if attr.Mode == models.AttributeModeBlindBox {
+			continue
+		}
+		value := strings.TrimSpace(attributes[attr.Name])
+		if value == "" {
+			continue
+		}
+		valid := false
+		for _, option := range attr.Values {
+			if option == value {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, bizerr.Newf("quote.attributeInvalid", "Invalid %s option", attr.Name).
+				WithParams(map[string]interface{}{"attribute": attr.Name})
+		}
+		result[attr.Name] = value
+	}
+	return result, nil
+}
+
+// Create 客户提交询价单，仅询价模式商品可用
+func (s *QuoteRequestService) Create(userID uint, input QuoteRequestInput) (*models.QuoteRequest, error) {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	var product models.Product
+	if err := s.db.First(&product, input.ProductID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, bizerr.New("quote.productNotFound", "Product not found")
+		}
+		return nil, err
+	}
+	if product.Status != models.ProductStatusActive {
+		return nil, ErrProductNotAvailable
+	}
+	if !product.PriceOnRequest {
+		return nil, bizerr.New("quote.notRequired", "This product can be ordered directly")
+	}
+	if input.Quantity < 1 || input.Quantity > maxQuoteRequestQuantity {
+		return nil, bizerr.Newf("quote.quantityInvalid", "Quantity must be between 1 and %d", maxQuoteRequestQuantity).
+			WithParams(map[string]interface{}{"max": maxQuoteRequestQuantity})
+	}
+	message := strings.TrimSpace(input.Message)
+	if utf8.RuneCountInString(message) > maxQuoteRequestMessageLength {
+		return nil, bizerr.Newf("quote.messageTooLong", "Message must be at most %d characters", maxQuoteRequestMessageLength).
+			WithParams(map[string]interface{}{"max": maxQuoteRequestMessageLength})
+	}
+	attributes, err := validateQuoteAttributes(&product, input.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var openCount int64
+	if err := s.db.Model(&models.QuoteRequest{}).
+		Where("user_id = ? AND status IN ?", userID, []models.QuoteRequestStatus{models.QuoteRequestStatusPending, models.QuoteRequestStatusQuoted}).
+		Count(&openCount).Error; err != nil {
+		return nil, err
+	}
+	if openCount >= maxOpenQuoteRequestsPerUser {
+		return nil, bizerr.Newf("quote.tooManyOpen", "You can have at most %d open quote requests", maxOpenQuoteRequestsPerUser).
+			WithParams(map[string]interface{}{"max": maxOpenQuoteRequestsPerUser})
+	}
+
+	contactName := strings.TrimSpace(input.ContactName)
+	if contactName == "" {
+		contactName = user.Name
+	}
+	contactEmail := strings.TrimSpace(input.ContactEmail)
+	if contactEmail == "" {
+		contactEmail = user.Email
+	}
+	quote := &models.QuoteRequest{
+		QuoteNo:      utils.GenerateOrderNo("QT"),
+		UserID:       userID,
+		ProductID:    product.ID,
+		ProductSKU:   product.SKU,
+		ProductName:  product.Name,
+		Quantity:     input.Quantity,
+		Attributes:   attributes,
+		Message:      message,
+		Company:      truncateRunes(strings.TrimSpace(input.Company), 200),
+		ContactName:  truncateRunes(contactName, 100),
+		ContactEmail: truncateRunes(contactEmail, 255),
+		ContactPhone: truncateRunes(strings.TrimSpace(input.ContactPhone), 50),
+		Status:       models.QuoteRequestStatusPending,
+	}
+	if err := s.db.Create(quote).Error; err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+func truncateRunes(value string, max int) string {
+	if utf8.RuneCountInString(value) <= max {
+		return value
+	}
+	return string([]rune(value)[:max])
+}
+
+// ListForUser 客户自己的询价单
+func (s *QuoteRequestService) ListForUser(userID uint, page, limit int) ([]models.QuoteRequest, int64, error) {
+	return s.list(s.db.Model(&models.QuoteRequest{}).Where("user_id = ?", userID), page, limit)
+}
+
+// List 后台询价单列表
+func (s *QuoteRequestService) List(filter QuoteRequestListFilter, page, limit int) ([]models.QuoteRequest, int64, error) {
+	query := s.db.Model(&models.QuoteRequest{})
+	if status := strings.TrimSpace(filter.Status); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		like := "%" + search + "%"
+		query = query.Where("quote_no LIKE ? OR product_name LIKE ? OR product_sku LIKE ? OR contact_email LIKE ? OR company LIKE ?",
+			like, like, like, like, like)
+	}
+	return s.list(query, page, limit)
+}
+
+func (s *QuoteRequestService) list(query *gorm.DB, page, limit int) ([]models.QuoteRequest, int64, error) {
+	var (
+		items []models.QuoteRequest
+		total int64
+	)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&items).Error
+	return items, total, err
+}
+
+// Get 询价单详情（后台）
+func (s *QuoteRequestService) Get(id uint) (*models.QuoteRequest, error) {
+	var quote models.QuoteRequest
+	if err := s.db.Preload("User").First(&quote, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuoteRequestNotFound
+		}
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// GetForUser 询价单详情（客户只能查看自己的）
+func (s *QuoteRequestService) GetForUser(userID, id uint) (*models.QuoteRequest, error) {
+	var quote models.QuoteRequest
+	if err := s.db.Where("user_id = ?", userID).First(&quote, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuoteRequestNotFound
+		}
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// Quote 商家报价；已报价但客户未确认时可重新报价
+func (s *QuoteRequestService) Quote(id uint, adminID uint, input QuoteOfferInput) (*models.QuoteRequest, error) {
+	if input.UnitPriceMinor <= 0 {
+		return nil, bizerr.New("quote.priceInvalid", "Quoted unit price must be greater than 0")
+	}
+	if input.ValidDays < 0 || input.ValidDays > maxQuoteValidDays {
+		return nil, bizerr.Newf("quote.validDaysInvalid", "Validity must be between 0 and %d days", maxQuoteValidDays).
+			WithParams(map[string]interface{}{"max": maxQuoteValidDays})
+	}
+	quote, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status != models.QuoteRequestStatusPending && quote.Status != models.QuoteRequestStatusQuoted {
+		return nil, newQuoteStatusInvalidError(quote.Status)
+	}
+
+	now := models.NowFunc()
+	quote.Status = models.QuoteRequestStatusQuoted
+	quote.QuotedUnitPriceMinor = input.UnitPriceMinor
+	quote.Currency = s.currency()
+	quote.AdminReply = strings.TrimSpace(input.Reply)
+	quote.QuotedBy = &adminID
+	quote.QuotedAt = &now
+	quote.ValidUntil = nil
+	if input.ValidDays > 0 {
+		validUntil := now.Add(time.Duration(input.ValidDays) * 24 * time.Hour)
+		quote.ValidUntil = &validUntil
+	}
+	if err := s.db.Omit("User").Save(quote).Error; err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// Close 商家关闭询价单（婉拒或线下已处理）
+func (s *QuoteRequestService) Close(id uint, reply string) (*models.QuoteRequest, error) {
+	quote, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status != models.QuoteRequestStatusPending && quote.Status != models.QuoteRequestStatusQuoted {
+		return nil, newQuoteStatusInvalidError(quote.Status)
+	}
+	quote.Status = models.QuoteRequestStatusClosed
+	if reply = strings.TrimSpace(reply); reply != "" {
+		quote.AdminReply = reply
+	}
+	if err := s.db.Omit("User").Save(quote).Error; err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// Respond 客户接受或拒绝报价，过期报价不能接受
+func (s *QuoteRequestService) Respond(userID, id uint, accept bool) (*models.QuoteRequest, error) {
+	quote, err := s.GetForUser(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status != models.QuoteRequestStatusQuoted {
+		return nil, newQuoteStatusInvalidError(quote.Status)
+	}
+	now := models.NowFunc()
+	if accept && quote.IsQuoteExpiredAt(now) {
+		return nil, bizerr.New("quote.expired", "This quote has expired, please request a new one")
+	}
+	quote.Status = models.QuoteRequestStatusDeclined
+	if accept {
+		quote.Status = models.QuoteRequestStatusAccepted
+	}
+	quote.RespondedAt = &now
+	if err := s.db.Save(quote).Error; err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// Cancel 客户撤回尚未确认的询价单
+func (s *QuoteRequestService) Cancel(userID, id uint) (*models.QuoteRequest, error) {
+	quote, err := s.GetForUser(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status != models.QuoteRequestStatusPending && quote.Status != models.QuoteRequestStatusQuoted {
+		return nil, newQuoteStatusInvalidError(quote.Status)
+	}
+	quote.Status = models.QuoteRequestStatusClosed
+	if err := s.db.Save(quote).Error; err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+func (s *QuoteRequestService) currency() string {
+	if s.cfg != nil && s.cfg.Order.Currency != "" {
+		return s.cfg.Order.Currency
+	}
+	return "CNY"
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+)
+
+func TestProductPriceHiddenForCustomerTier(t *testing.T) {
+	product := &models.Product{PriceOnRequest: true, PriceVisibleTiers: normalizeCustomerTiers([]string{" Wholesale ", "VIP", "vip", ""})}
+	if len(product.PriceVisibleTiers) != 2 || product.PriceVisibleTiers[0] != "wholesale" || product.PriceVisibleTiers[1] != "vip" {
+		t.Fatalf("unexpected normalized tiers: %v", product.PriceVisibleTiers)
+	}
+	if !product.PriceHiddenFor("") || !product.PriceHiddenFor("retail") {
+		t.Fatal("expected price hidden for guests and unlisted tiers")
+	}
+	if product.PriceHiddenFor("Wholesale") {
+		t.Fatal("expected price visible for listed tier")
+	}
+	if (&models.Product{}).PriceHiddenFor("") {
+		t.Fatal("expected regular product price visible")
+	}
+
+	if _, err := NormalizeCustomerTier(string(make([]byte, maxCustomerTierLength+1))); err == nil {
+		t.Fatal("expected overlong tier rejected")
+	}
+}
+
+func TestQuoteRequestWorkflow(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Product{}, &models.QuoteRequest{})
+	cfg := &config.Config{}
+	cfg.Order.Currency = "USD"
+
+	user := models.User{UUID: "quote-user", Email: "buyer@example.com", Name: "Buyer", Role: "user", IsActive: true, PasswordHash: "hash"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user failed: %v", err)
+	}
+	product := models.Product{
+		SKU:            "B2B-PALLET",
+		Name:           "Pallet",
+		Status:         models.ProductStatusActive,
+		Price:          5000,
+		PriceOnRequest: true,
+		Attributes:     []models.ProductAttribute{{Name: "Color", Values: []string{"Black", "White"}}},
+	}
+	regular := models.Product{SKU: "RETAIL", Name: "Retail", Status: models.ProductStatusActive, Price: 100}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product failed: %v", err)
+	}
+	if err := db.Create(&regular).Error; err != nil {
+		t.Fatalf("create regular product failed: %v", err)
+	}
+	svc := NewQuoteRequestService(db, cfg)
+
+	// 目录对无等级访客隐藏价格，且不下发可见等级
+	products := []models.Product{product, regular}
+	products[0].PriceVisibleTiers = []string{"wholesale"}
+	svc.ApplyPriceVisibility(products, &user.ID)
+	if !products[0].PriceHidden || products[0].Price != 0 || products[0].PriceVisibleTiers != nil {
+		t.Fatalf("expected price hidden, got %+v", products[0])
+	}
+	if products[1].PriceHidden || products[1].Price != 100 {
+		t.Fatalf("expected regular product untouched, got %+v", products[1])
+	}
+
+	_, err := svc.Create(user.ID, QuoteRequestInput{ProductID: regular.ID, Quantity: 10})
+	requireOrderBizErr(t, err, "quote.notRequired")
+	_, err = svc.Create(user.ID, QuoteRequestInput{ProductID: product.ID, Quantity: 0})
+	requireOrderBizErr(t, err, "quote.quantityInvalid")
+	_, err = svc.Create(user.ID, QuoteRequestInput{ProductID: product.ID, Quantity: 10, Attributes: map[string]string{"Color": "Red"}})
+	requireOrderBizErr(t, err, "quote.attributeInvalid")
+
+	quote, err := svc.Create(user.ID, QuoteRequestInput{ProductID: product.ID, Quantity: 200, Attributes: map[string]string{"Color": "Black"}, Company: "Acme"})
+	if err != nil {
+		t.Fatalf("create quote request failed: %v", err)
+	}
+	if quote.Status != models.QuoteRequestStatusPending || quote.ContactEmail != user.Email || quote.Attributes["Color"] != "Black" {
+		t.Fatalf("unexpected quote request: %+v", quote)
+	}
+
+	// 未报价前不能接受
+	_, err = svc.Respond(user.ID, quote.ID, true)
+	requireOrderBizErr(t, err, "quote.statusInvalid")
+	_, err = svc.Quote(quote.ID, 1, QuoteOfferInput{UnitPriceMinor: 0})
+	requireOrderBizErr(t, err, "quote.priceInvalid")
+
+	quoted, err := svc.Quote(quote.ID, 1, QuoteOfferInput{UnitPriceMinor: 4200, ValidDays: 7, Reply: "Includes freight"})
+	if err != nil {
+		t.Fatalf("quote failed: %v", err)
+	}
+	if quoted.Status != models.QuoteRequestStatusQuoted || quoted.Currency != "USD" || quoted.QuotedTotalMinor() != 840000 || quoted.ValidUntil == nil {
+		t.Fatalf("unexpected quoted request: %+v", quoted)
+	}
+
+	// 其他用户看不到
+	_, err = svc.GetForUser(user.ID+1, quote.ID)
+	requireOrderBizErr(t, err, "quote.notFound")
+
+	// 过期报价不能接受，重新报价后可接受
+	expired := time.Now().Add(-time.Hour)
+	if err := db.Model(&models.QuoteRequest{}).Where("id = ?", quote.ID).Update("valid_until", expired).Error; err != nil {
+		t.Fatalf("expire quote failed: %v", err)
+	}
+	_, err = svc.Respond(user.ID, quote.ID, true)
+	requireOrderBizErr(t, err, "quote.expired")
+
+	if _, err := svc.Quote(quote.ID, 1, QuoteOfferInput{UnitPriceMinor: 4100}); err != nil {
+		t.Fatalf("requote failed: %v", err)
+	}
+	accepted, err := svc.Respond(user.ID, quote.ID, true)
+	if err != nil {
+		t.Fatalf("accept failed: %v", err)
+	}
+	if accepted.Status != models.QuoteRequestStatusAccepted || accepted.RespondedAt == nil {
+		t.Fatalf("unexpected accepted quote: %+v", accepted)
+	}
+	_, err = svc.Cancel(user.ID, quote.ID)
+	requireOrderBizErr(t, err, "quote.statusInvalid")
+}
+
+func TestPriceOnRequestBlocksCartAndOrderForHiddenTiers(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Product{}, &models.Order{}, &models.OrderNote{}, &models.InventoryLog{})
+	cfg := &config.Config{}
+	cfg.Order.MaxOrderItems = 20
+	cfg.Order.MaxItemQuantity = 10
+	cfg.Order.Currency = "CNY"
+	cfg.Form.ExpireHours = 24
+
+	retail := models.User{UUID: "poa-retail", Email: "retail@example.com", Name: "retail", Role: "user", IsActive: true, PasswordHash: "hash"}
+	wholesale := models.User{UUID: "poa-wholesale", Email: "wholesale@example.com", Name: "wholesale", Role: "user", IsActive: true, PasswordHash: "hash", CustomerTier: "wholesale"}
+	for _, user := range []*models.User{&retail, &wholesale} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user failed: %v", err)
+		}
+	}
+	product := models.Product{
+		SKU:               "POA-VIRTUAL",
+		Name:              "Licence Pack",
+		ProductType:       models.ProductTypeVirtual,
+		Status:            models.ProductStatusActive,
+		Price:             900,
+		PriceOnRequest:    true,
+		PriceVisibleTiers: []string{"wholesale"},
+	}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product failed: %v", err)
+	}
+
+	cartSvc := NewCartService(repository.NewCartRepository(db), repository.NewProductRepository(db), nil, nil)
+	cartSvc.SetUserRepository(repository.NewUserRepository(db))
+	_, err := cartSvc.AddToCart(retail.ID, AddToCartRequest{ProductID: product.ID, Quantity: 1})
+	requireOrderBizErr(t, err, "product.priceOnRequest")
+	if cartSvc.priceHiddenForUser(&product, wholesale.ID) {
+		t.Fatal("expected price visible to wholesale customer in cart")
+	}
+
+	orderSvc := newConcurrentOrderService(db, cfg, nil)
+	items := []models.OrderItem{{SKU: product.SKU, Name: product.Name, Quantity: 1, ProductType: models.ProductTypeVirtual}}
+	_, err = orderSvc.CreateUserOrder(retail.ID, items, "", "")
+	requireOrderBizErr(t, err, "product.priceOnRequest")
+	order, err := orderSvc.CreateUserOrder(wholesale.ID, items, "", "")
+	if err != nil {
+		t.Fatalf("expected wholesale customer to order, got %v", err)
+	}
+	if order.TotalAmount != 900 {
+		t.Fatalf("expected list price for wholesale order, got %d", order.TotalAmount)
+	}
+}
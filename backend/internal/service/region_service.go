@@ -0,0 +1,125 @@
+package service
+
+import (
+	"strings"
+
+	"auralogic/internal/config"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/constants"
+)
+
+// ShippingCountry 收货国家选项
+type ShippingCountry struct {
+	constants.Country
+	HasRegions      bool `json:"has_regions"`
+	ShippingEnabled bool `json:"shipping_enabled"`
+}
+
+// RegionService 国家/省/市数据集，叠加后台配置的停运国家
+type RegionService struct {
+	cfg *config.Config
+}
+
+func NewRegionService(cfg *config.Config) *RegionService {
+	return &RegionService{cfg: cfg}
+}
+
+func (s *RegionService) isCountryDisabled(code string) bool {
+	if s.cfg == nil {
+		return false
+	}
+	for _, disabled := range s.cfg.Form.DisabledCountries {
+		if strings.EqualFold(disabled, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// Countries 返回国家列表；includeDisabled 为 false 时过滤停运国家
+func (s *RegionService) Countries(includeDisabled bool) []ShippingCountry {
+	countries := make([]ShippingCountry, 0, len(constants.Countries))
+	for _, country := range constants.Countries {
+		enabled := !s.isCountryDisabled(country.Code)
+		if !enabled && !includeDisabled {
+			continue
+		}
+		countries = append(countries, ShippingCountry{
+			Country:         country,
+			HasRegions:      len(constants.GetRegions(country.Code)) > 0,
+			ShippingEnabled: enabled,
+		})
+	}
+	return countries
+}
+
+// Regions 返回国家的省/州及城市，未收录的国家返回空列表
+func (s *RegionService) Regions(countryCode string) ([]constants.Region, error) {
+	countryCode = strings.ToUpper(strings.TrimSpace(countryCode))
+	if constants.GetCountryByCode(countryCode) == nil {
+		return nil, bizerr.New("region.countryNotFound", "Country not found")
+	}
+	regions := constants.GetRegions(countryCode)
+	if regions == nil {
+		regions = []constants.Region{}
+	}
+	return regions, nil
+}
+
+// 省市常见后缀，比较时去除以兼容 "广东" 与 "广东省"
+var regionNameSuffixes = []string{"特别行政区", "自治区", "省", "市", "县", "地区", "盟"}
+
+func normalizeRegionName(name string) string {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	for _, suffix := range regionNameSuffixes {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != name && trimmed != "" {
+			return trimmed
+		}
+	}
+	return name
+}
+
+func matchRegion(regions []constants.Region, name string) *constants.Region {
+	target := normalizeRegionName(name)
+	for i := range regions {
+		region := &regions[i]
+		candidates := append([]string{region.Code, region.NameZH, region.NameEN}, region.Aliases...)
+		for _, candidate := range candidates {
+			if candidate != "" && normalizeRegionName(candidate) == target {
+				return region
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateShippingRegion 校验收货国家是否可配送，以及省/市是否存在于数据集；
+// 未收录省市的国家不做限制，空省市交由必填校验处理。enforceDisabled 为 false 时（后台修改）忽略停运设置
+func (s *RegionService) ValidateShippingRegion(countryCode, province, city string, enforceDisabled bool) error {
+	countryCode = strings.ToUpper(strings.TrimSpace(countryCode))
+	if countryCode == "" {
+		return nil
+	}
+	if enforceDisabled && s.isCountryDisabled(countryCode) {
+		return bizerr.Newf("region.countryDisabled", "Shipping to %s is not available", constants.GetCountryNameEN(countryCode)).
+			WithParams(map[string]interface{}{"country": countryCode})
+	}
+
+	regions := constants.GetRegions(countryCode)
+	if len(regions) == 0 || strings.TrimSpace(province) == "" {
+		return nil
+	}
+	matched := matchRegion(regions, province)
+	if matched == nil {
+		return bizerr.Newf("region.provinceInvalid", "Province/state %s does not exist in %s", province, countryCode).
+			WithParams(map[string]interface{}{"province": province, "country": countryCode})
+	}
+	if len(matched.Cities) == 0 || strings.TrimSpace(city) == "" {
+		return nil
+	}
+	if matchRegion(matched.Cities, city) == nil {
+		return bizerr.Newf("region.cityInvalid", "City %s does not exist in %s", city, matched.NameEN).
+			WithParams(map[string]interface{}{"city": city, "province": province})
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/pkg/bizerr"
+)
+
+func TestRegionServiceValidateShippingRegion(t *testing.T) {
+	svc := NewRegionService(&config.Config{Form: config.FormConfig{DisabledCountries: []string{"RU"}}})
+
+	cases := []struct {
+		name                    string
+		country, province, city string
+		enforceDisabled         bool
+		wantKey                 string
+	}{
+		{"suffix omitted", "CN", "广东", "深圳", true, ""},
+		{"full name", "cn", "广东省", "深圳市", true, ""},
+		{"english name", "CN", "guangdong", "Shenzhen", true, ""},
+		{"municipality alias", "CN", "北京市", "市辖区", true, ""},
+		{"state code", "US", "ny", "New York City", true, ""},
+		{"country without dataset", "JP", "东京都", "新宿区", true, ""},
+		{"empty province", "CN", "", "", true, ""},
+		{"unknown province", "CN", "火星省", "", true, "region.provinceInvalid"},
+		{"city outside province", "CN", "广东省", "杭州市", true, "region.cityInvalid"},
+		{"disabled country", "RU", "", "", true, "region.countryDisabled"},
+		{"disabled country ignored by admin", "RU", "", "", false, ""},
+	}
+	for _, tc := range cases {
+		err := svc.ValidateShippingRegion(tc.country, tc.province, tc.city, tc.enforceDisabled)
+		if tc.wantKey == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		var bizErr *bizerr.Error
+		if !errors.As(err, &bizErr) || bizErr.Key != tc.wantKey {
+			t.Errorf("%s: expected %s, got %v", tc.name, tc.wantKey, err)
+		}
+	}
+}
+
+func TestRegionServiceCountries(t *testing.T) {
+	svc := NewRegionService(&config.Config{Form: config.FormConfig{DisabledCountries: []string{"RU"}}})
+
+	for _, country := range svc.Countries(false) {
+		if country.Code == "RU" {
+			t.Fatalf("disabled country should be hidden")
+		}
+	}
+	found := false
+	for _, country := range svc.Countries(true) {
+		if country.Code == "RU" {
+			found = true
+			if country.ShippingEnabled {
+				t.Fatalf("expected RU to be marked as disabled")
+			}
+		}
+		if country.Code == "CN" && !country.HasRegions {
+			t.Fatalf("expected CN to have regions")
+		}
+	}
+	if !found {
+		t.Fatalf("expected disabled country when includeDisabled is set")
+	}
+
+	if _, err := svc.Regions("XX"); err == nil {
+		t.Fatalf("expected error for unknown country")
+	}
+	if regions, err := svc.Regions("jp"); err != nil || regions == nil || len(regions) != 0 {
+		t.Fatalf("expected empty region list for JP, got %v, %v", regions, err)
+	}
+}
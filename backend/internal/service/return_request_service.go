@@ -0,0 +1,466 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/utils"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	maxReturnReasonLength = 1000
+	maxReturnRemarkLength = 1000
+	// MaxReturnPhotos 每个退货申请最多附带的照片数
+	MaxReturnPhotos = 6
+	// MaxReturnPhotoSize 单张退货照片大小上限
+	MaxReturnPhotoSize = 5 * 1024 * 1024
+)
+
+// ReturnPhotoExtensions 允许上传的退货照片格式
+var ReturnPhotoExtensions = []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
+
+var ErrReturnRequestNotFound = bizerr.New("return.notFound", "Return request not found")
+
+func newReturnStatusInvalidError(status models.ReturnRequestStatus) error {
+	return bizerr.Newf("return.statusInvalid", "Return request in status %s cannot be changed this way", status).
+		WithParams(map[string]interface{}{"status": status})
+}
+
+// ReturnRequestInput 客户提交退货申请参数，Photos 为上传接口返回的相对路径
+type ReturnRequestInput struct {
+	Items  []OrderReturnItem `json:"items"`
+	Reason string            `json:"reason"`
+	Photos []string          `json:"photos"`
+}
+
+// ReturnReceiveInput 管理员登记收到退货
+type ReturnReceiveInput struct {
+	Restock           bool
+	InvalidateSerials bool
+	Operator          string
+	OperatorID        uint
+}
+
+// ReturnRequestListFilter 后台退货申请筛选
+type ReturnRequestListFilter struct {
+	Status     string
+	Search     string
+	StoreScope *repository.StoreScope
+}
+
+// ReturnRequestService 退货申请（RMA）：客户申请 -> 管理员审核并生成退货单号 -> 收货后回补库存
+// 收货登记复用 OrderService.ReceiveReturn，累计退货数量与库存变动都记在订单上
+type ReturnRequestService struct {
+	db           *gorm.DB
+	orderService *OrderService
+}
+
+func NewReturnRequestService(db *gorm.DB, orderService *OrderService) *ReturnRequestService {
+	return &ReturnRequestService{db: db, orderService: orderService}
+}
+
+// ReturnPhotoOwnerPrefix 退货照片按用户分目录保存，提交申请时据此校验照片归属
+func ReturnPhotoOwnerPrefix(userID uint) string {
+	return strconv.FormatUint(uint64(userID), 10) + "/"
+}
+
+func returnPhotoSignature(secret, relPath string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("return-photo\x00" + relPath + "\x00" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignedReturnPhotoURL 生成退货照片的短期访问链接，有效期与工单附件一致
+func SignedReturnPhotoURL(relPath string, now time.Time) string {
+	cfg := config.GetConfig()
+	relPath = NormalizeTicketAttachmentPath(relPath)
+	ttl := ticketAttachmentURLTTL(cfg)
+	expires := now.Truncate(ttl).Add(2 * ttl).Unix()
+	baseURL := ""
+	secret := ""
+	if cfg != nil {
+		baseURL = strings.TrimRight(cfg.App.URL, "/")
+		secret = cfg.JWT.Secret
+	}
+	return baseURL + "/uploads/returns/" + relPath +
+		"?expires=" + strconv.FormatInt(expires, 10) +
+		"&sig=" + returnPhotoSignature(secret, relPath, expires)
+}
+
+// VerifyReturnPhotoSignature 校验退货照片签名链接
+func VerifyReturnPhotoSignature(relPath, expiresRaw, sig string, now time.Time) bool {
+	cfg := config.GetConfig()
+	relPath = NormalizeTicketAttachmentPath(relPath)
+	if cfg == nil || relPath == "" || sig == "" {
+		return false
+	}
+	expires, err := strconv.ParseInt(expiresRaw, 10, 64)
+	if err != nil || now.Unix() > expires {
+		return false
+	}
+	if time.Unix(expires, 0).Sub(now) > 2*ticketAttachmentURLTTL(cfg) {
+		return false
+	}
+	expected := returnPhotoSignature(cfg.JWT.Secret, relPath, expires)
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
+
+// withPhotoURLs 为照片路径签发访问链接
+func withPhotoURLs(requests ...*models.ReturnRequest) {
+	now := time.Now()
+	for _, request := range requests {
+		request.PhotoURLs = make([]string, 0, len(request.Photos))
+		for _, photo := range request.Photos {
+			request.PhotoURLs = append(request.PhotoURLs, SignedReturnPhotoURL(photo, now))
+		}
+	}
+}
+
+// FindUserOrder 查找属于该用户的订单，不属于时按不存在处理
+func (s *ReturnRequestService) FindUserOrder(userID uint, orderNo string) (*models.Order, error) {
+	order, err := s.orderService.OrderRepo.FindByOrderNo(orderNo)
+	if err != nil {
+		return nil, normalizeOrderLookupError(err)
+	}
+	if order.UserID == nil || *order.UserID != userID {
+		return nil, newOrderNotFoundError()
+	}
+	return order, nil
+}
+
+// EnsureOrderAcceptsReturns 订单已发货才可申请退货
+func EnsureOrderAcceptsReturns(order *models.Order) error {
+	if !orderAcceptsReturns(order) {
+		return bizerr.Newf("order.returnStatusInvalid", "Order status %s does not accept returns", order.Status).
+			WithParams(map[string]interface{}{"status": order.Status})
+	}
+	return nil
+}
+
+func normalizeReturnPhotos(userID uint, photos []string) ([]string, error) {
+	if len(photos) > MaxReturnPhotos {
+		return nil, bizerr.Newf("return.tooManyPhotos", "At most %d photos can be attached", MaxReturnPhotos).
+			WithParams(map[string]interface{}{"max": MaxReturnPhotos})
+	}
+	prefix := ReturnPhotoOwnerPrefix(userID)
+	result := make([]string, 0, len(photos))
+	seen := make(map[string]bool, len(photos))
+	for _, photo := range photos {
+		relPath := NormalizeTicketAttachmentPath(photo)
+		if relPath == "" || !strings.HasPrefix(relPath, prefix) {
+			return nil, bizerr.New("return.photoInvalid", "Invalid return photo")
+		}
+		if seen[relPath] {
+			continue
+		}
+		seen[relPath] = true
+		result = append(result, relPath)
+	}
+	return result, nil
+}
+
+// Create 客户对已发货订单提交退货申请；同一订单同时只能有一个未完结的申请
+func (s *ReturnRequestService) Create(userID uint, orderNo string, input ReturnRequestInput) (*models.ReturnRequest, error) {
+	order, err := s.FindUserOrder(userID, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	if err := EnsureOrderAcceptsReturns(order); err != nil {
+		return nil, err
+	}
+	if len(input.Items) == 0 {
+		return nil, bizerr.New("order.returnItemsRequired", "Select at least one item to return")
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, bizerr.New("return.reasonRequired", "Please describe the reason for the return")
+	}
+	if utf8.RuneCountInString(reason) > maxReturnReasonLength {
+		return nil, bizerr.Newf("return.reasonTooLong", "Reason must be at most %d characters", maxReturnReasonLength).
+			WithParams(map[string]interface{}{"max": maxReturnReasonLength})
+	}
+	photos, err := normalizeReturnPhotos(userID, input.Photos)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := make(map[int]int, len(input.Items))
+	for _, item := range input.Items {
+		if item.ItemIndex < 0 || item.ItemIndex >= len(order.Items) || item.Quantity <= 0 ||
+			order.Items[item.ItemIndex].ProductType == models.ProductTypeVirtual {
+			return nil, bizerr.New("order.returnItemInvalid", "Invalid return item")
+		}
+		requested[item.ItemIndex] += item.Quantity
+	}
+	indexes := make([]int, 0, len(requested))
+	for idx := range requested {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+	items := make([]models.ReturnRequestItem, 0, len(indexes))
+	for _, idx := range indexes {
+		orderItem := order.Items[idx]
+		if order.ReturnedQuantities[idx]+requested[idx] > orderItem.Quantity {
+			return nil, bizerr.Newf("order.returnQuantityExceeded", "Return quantity for %s exceeds the purchased quantity", orderItem.SKU).
+				WithParams(map[string]interface{}{"sku": orderItem.SKU, "remaining": orderItem.Quantity - order.ReturnedQuantities[idx]})
+		}
+		items = append(items, models.ReturnRequestItem{
+			ItemIndex: idx,
+			SKU:       orderItem.SKU,
+			Name:      orderItem.Name,
+			Quantity:  requested[idx],
+		})
+	}
+
+	request := &models.ReturnRequest{
+		RMANo:   utils.GenerateOrderNo("RMA"),
+		OrderID: order.ID,
+		OrderNo: order.OrderNo,
+		UserID:  userID,
+		StoreID: order.StoreID,
+		Items:   items,
+		Reason:  reason,
+		Photos:  photos,
+		Status:  models.ReturnRequestStatusPending,
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var openCount int64
+		if err := tx.Model(&models.ReturnRequest{}).
+			Where("order_id = ? AND status IN ?", order.ID, []models.ReturnRequestStatus{models.ReturnRequestStatusPending, models.ReturnRequestStatusApproved}).
+			Count(&openCount).Error; err != nil {
+			return err
+		}
+		if openCount > 0 {
+			return bizerr.New("return.alreadyOpen", "This order already has a return request in progress")
+		}
+		return tx.Create(request).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	withPhotoURLs(request)
+	return request, nil
+}
+
+// ListForOrder 客户查看某订单的退货申请
+func (s *ReturnRequestService) ListForOrder(userID uint, orderNo string) ([]models.ReturnRequest, error) {
+	order, err := s.FindUserOrder(userID, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	var items []models.ReturnRequest
+	if err := s.db.Where("order_id = ? AND user_id = ?", order.ID, userID).Order("id DESC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	for i := range items {
+		withPhotoURLs(&items[i])
+	}
+	return items, nil
+}
+
+// List 后台退货申请列表
+func (s *ReturnRequestService) List(filter ReturnRequestListFilter, page, limit int) ([]models.ReturnRequest, int64, error) {
+	query := filter.StoreScope.Apply(s.db.Model(&models.ReturnRequest{}))
+	if status := strings.TrimSpace(filter.Status); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		like := "%" + search + "%"
+		query = query.Where("rma_no LIKE ? OR order_no LIKE ? OR return_tracking_no LIKE ?", like, like, like)
+	}
+
+	var (
+		items []models.ReturnRequest
+		total int64
+	)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("User").Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	for i := range items {
+		withPhotoURLs(&items[i])
+	}
+	return items, total, nil
+}
+
+// Get 退货申请详情（后台）
+func (s *ReturnRequestService) Get(id uint) (*models.ReturnRequest, error) {
+	var request models.ReturnRequest
+	if err := s.db.Preload("User").First(&request, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReturnRequestNotFound
+		}
+		return nil, err
+	}
+	withPhotoURLs(&request)
+	return &request, nil
+}
+
+func normalizeReturnRemark(remark string) (string, error) {
+	remark = strings.TrimSpace(remark)
+	if utf8.RuneCountInString(remark) > maxReturnRemarkLength {
+		return "", bizerr.Newf("return.remarkTooLong", "Remark must be at most %d characters", maxReturnRemarkLength).
+			WithParams(map[string]interface{}{"max": maxReturnRemarkLength})
+	}
+	return remark, nil
+}
+
+// transition 按当前状态条件更新，避免并发审核互相覆盖
+func (s *ReturnRequestService) transition(request *models.ReturnRequest, from []models.ReturnRequestStatus, updates map[string]interface{}) error {
+	allowed := false
+	for _, status := range from {
+		if request.Status == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return newReturnStatusInvalidError(request.Status)
+	}
+	result := s.db.Model(&models.ReturnRequest{}).
+		Where("id = ? AND status = ?", request.ID, request.Status).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return newReturnStatusInvalidError(request.Status)
+	}
+	return nil
+}
+
+// Approve 同意退货并生成退货单号，客户寄回时注明该单号
+func (s *ReturnRequestService) Approve(id, adminID uint, remark string) (*models.ReturnRequest, error) {
+	remark, err := normalizeReturnRemark(remark)
+	if err != nil {
+		return nil, err
+	}
+	request, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	now := models.NowFunc()
+	if err := s.transition(request, []models.ReturnRequestStatus{models.ReturnRequestStatusPending}, map[string]interface{}{
+		"status":             models.ReturnRequestStatusApproved,
+		"return_tracking_no": utils.GenerateOrderNo("RT"),
+		"admin_remark":       remark,
+		"reviewed_by":        adminID,
+		"reviewed_at":        now,
+	}); err != nil {
+		return nil, err
+	}
+	return s.Get(id)
+}
+
+// Reject 拒绝退货申请；已同意但尚未收货的申请也可拒绝
+func (s *ReturnRequestService) Reject(id, adminID uint, remark string) (*models.ReturnRequest, error) {
+	remark, err := normalizeReturnRemark(remark)
+	if err != nil {
+		return nil, err
+	}
+	if remark == "" {
+		return nil, bizerr.New("return.remarkRequired", "Please provide a reason for rejecting the return")
+	}
+	request, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	now := models.NowFunc()
+	if err := s.transition(request, []models.ReturnRequestStatus{models.ReturnRequestStatusPending, models.ReturnRequestStatusApproved}, map[string]interface{}{
+		"status":       models.ReturnRequestStatusRejected,
+		"admin_remark": remark,
+		"reviewed_by":  adminID,
+		"reviewed_at":  now,
+	}); err != nil {
+		return nil, err
+	}
+	return s.Get(id)
+}
+
+// Receive 登记收到退货：先占用状态防止重复登记，再按申请的商品回补库存；
+// 回补失败时恢复为已同意，管理员可重试
+func (s *ReturnRequestService) Receive(id uint, input ReturnReceiveInput) (*models.ReturnRequest, *OrderReturnResult, error) {
+	request, err := s.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := models.NowFunc()
+	if err := s.transition(request, []models.ReturnRequestStatus{models.ReturnRequestStatusApproved}, map[string]interface{}{
+		"status":      models.ReturnRequestStatusReceived,
+		"received_by": input.OperatorID,
+		"received_at": now,
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]OrderReturnItem, 0, len(request.Items))
+	for _, item := range request.Items {
+		items = append(items, OrderReturnItem{ItemIndex: item.ItemIndex, Quantity: item.Quantity})
+	}
+	operatorID := input.OperatorID
+	result, err := s.orderService.ReceiveReturn(request.OrderID, OrderReturnInput{
+		Items:             items,
+		Restock:           input.Restock,
+		InvalidateSerials: input.InvalidateSerials,
+		Reason:            fmt.Sprintf("RMA %s: %s", request.RMANo, request.Reason),
+		Operator:          input.Operator,
+		OperatorID:        &operatorID,
+	})
+	if err != nil {
+		if rollbackErr := s.db.Model(&models.ReturnRequest{}).Where("id = ?", request.ID).Updates(map[string]interface{}{
+			"status":      models.ReturnRequestStatusApproved,
+			"received_by": nil,
+			"received_at": nil,
+		}).Error; rollbackErr != nil {
+			return nil, nil, rollbackErr
+		}
+		return nil, nil, err
+	}
+	if err := s.db.Model(&models.ReturnRequest{}).Where("id = ?", request.ID).
+		Update("restocked_quantity", result.RestockedQuantity).Error; err != nil {
+		return nil, nil, err
+	}
+	request, err = s.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return request, result, nil
+}
+
+// Cancel 客户撤回尚未审核的退货申请
+func (s *ReturnRequestService) Cancel(userID uint, orderNo string, id uint) (*models.ReturnRequest, error) {
+	order, err := s.FindUserOrder(userID, orderNo)
+	if err != nil {
+		return nil, err
+	}
+	var request models.ReturnRequest
+	if err := s.db.Where("order_id = ? AND user_id = ?", order.ID, userID).First(&request, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReturnRequestNotFound
+		}
+		return nil, err
+	}
+	if err := s.transition(&request, []models.ReturnRequestStatus{models.ReturnRequestStatusPending}, map[string]interface{}{
+		"status": models.ReturnRequestStatusCancelled,
+	}); err != nil {
+		return nil, err
+	}
+	request.Status = models.ReturnRequestStatusCancelled
+	withPhotoURLs(&request)
+	return &request, nil
+}
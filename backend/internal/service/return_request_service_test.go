@@ -0,0 +1,145 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func TestReturnRequestWorkflowRestocksOnReceipt(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.Product{}, &models.InventoryLog{}, &models.OrderNote{}, &models.User{}, &models.ReturnRequest{})
+	orderSvc := newConcurrentOrderService(db, &config.Config{}, nil)
+	svc := NewReturnRequestService(db, orderSvc)
+
+	userID := uint(7)
+	inventory := &models.Inventory{Name: "Lamp", Stock: 5, AvailableQuantity: 5, SoldQuantity: 2, IsActive: true}
+	if err := db.Create(inventory).Error; err != nil {
+		t.Fatalf("create inventory failed: %v", err)
+	}
+	shippedAt := time.Now()
+	order := &models.Order{
+		OrderNo:   "RMA-ORDER-1",
+		UserID:    &userID,
+		Status:    models.OrderStatusShipped,
+		ShippedAt: &shippedAt,
+		Items: []models.OrderItem{
+			{SKU: "LAMP", Name: "Lamp", Quantity: 2},
+			{SKU: "EBOOK", Name: "E-book", Quantity: 1, ProductType: models.ProductTypeVirtual},
+		},
+		InventoryBindings: map[int]uint{0: inventory.ID},
+	}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order failed: %v", err)
+	}
+
+	// 其他用户的订单按不存在处理
+	_, err := svc.Create(userID+1, order.OrderNo, ReturnRequestInput{Items: []OrderReturnItem{{ItemIndex: 0, Quantity: 1}}, Reason: "broken"})
+	requireOrderBizErr(t, err, "order.notFound")
+	_, err = svc.Create(userID, order.OrderNo, ReturnRequestInput{Items: []OrderReturnItem{{ItemIndex: 0, Quantity: 1}}})
+	requireOrderBizErr(t, err, "return.reasonRequired")
+	_, err = svc.Create(userID, order.OrderNo, ReturnRequestInput{Items: []OrderReturnItem{{ItemIndex: 1, Quantity: 1}}, Reason: "broken"})
+	requireOrderBizErr(t, err, "order.returnItemInvalid")
+	_, err = svc.Create(userID, order.OrderNo, ReturnRequestInput{Items: []OrderReturnItem{{ItemIndex: 0, Quantity: 3}}, Reason: "broken"})
+	requireOrderBizErr(t, err, "order.returnQuantityExceeded")
+	_, err = svc.Create(userID, order.OrderNo, ReturnRequestInput{Items: []OrderReturnItem{{ItemIndex: 0, Quantity: 1}}, Reason: "broken", Photos: []string{"8/2026/01/01/a.jpg"}})
+	requireOrderBizErr(t, err, "return.photoInvalid")
+
+	request, err := svc.Create(userID, order.OrderNo, ReturnRequestInput{
+		Items:  []OrderReturnItem{{ItemIndex: 0, Quantity: 2}},
+		Reason: "Arrived broken",
+		Photos: []string{"7/2026/01/01/a.jpg", "/7/2026/01/01/a.jpg"},
+	})
+	if err != nil {
+		t.Fatalf("create return request failed: %v", err)
+	}
+	if request.Status != models.ReturnRequestStatusPending || len(request.Photos) != 1 || request.Items[0].SKU != "LAMP" {
+		t.Fatalf("unexpected return request: %+v", request)
+	}
+	_, err = svc.Create(userID, order.OrderNo, ReturnRequestInput{Items: []OrderReturnItem{{ItemIndex: 0, Quantity: 1}}, Reason: "again"})
+	requireOrderBizErr(t, err, "return.alreadyOpen")
+
+	// 未审核前不能收货
+	_, _, err = svc.Receive(request.ID, ReturnReceiveInput{Restock: true, OperatorID: 1})
+	requireOrderBizErr(t, err, "return.statusInvalid")
+
+	approved, err := svc.Approve(request.ID, 1, "Please ship it back")
+	if err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+	if approved.Status != models.ReturnRequestStatusApproved || approved.ReturnTrackingNo == "" || approved.ReviewedAt == nil {
+		t.Fatalf("unexpected approved request: %+v", approved)
+	}
+	_, err = svc.Cancel(userID, order.OrderNo, request.ID)
+	requireOrderBizErr(t, err, "return.statusInvalid")
+
+	received, result, err := svc.Receive(request.ID, ReturnReceiveInput{Restock: true, Operator: "admin@example.com", OperatorID: 1})
+	if err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+	if received.Status != models.ReturnRequestStatusReceived || received.RestockedQuantity != 2 || result.RestockedQuantity != 2 {
+		t.Fatalf("unexpected received request: %+v result %+v", received, result)
+	}
+	var reloaded models.Inventory
+	db.First(&reloaded, inventory.ID)
+	if reloaded.Stock != 7 || reloaded.SoldQuantity != 0 {
+		t.Fatalf("unexpected inventory after receipt: %+v", reloaded)
+	}
+	_, _, err = svc.Receive(request.ID, ReturnReceiveInput{Restock: true, OperatorID: 1})
+	requireOrderBizErr(t, err, "return.statusInvalid")
+
+	// 全部退回后不能再申请
+	_, err = svc.Create(userID, order.OrderNo, ReturnRequestInput{Items: []OrderReturnItem{{ItemIndex: 0, Quantity: 1}}, Reason: "again"})
+	requireOrderBizErr(t, err, "order.returnQuantityExceeded")
+}
+
+func TestReturnRequestRejectAndCancel(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.User{}, &models.ReturnRequest{})
+	svc := NewReturnRequestService(db, newConcurrentOrderService(db, &config.Config{}, nil))
+
+	userID := uint(3)
+	pending := &models.Order{OrderNo: "RMA-PENDING", UserID: &userID, Status: models.OrderStatusPending, Items: []models.OrderItem{{SKU: "A", Quantity: 1}}}
+	shippedAt := time.Now()
+	completed := &models.Order{OrderNo: "RMA-DONE", UserID: &userID, Status: models.OrderStatusCompleted, ShippedAt: &shippedAt, Items: []models.OrderItem{{SKU: "A", Quantity: 1}}}
+	for _, order := range []*models.Order{pending, completed} {
+		if err := db.Create(order).Error; err != nil {
+			t.Fatalf("create order failed: %v", err)
+		}
+	}
+
+	_, err := svc.Create(userID, pending.OrderNo, ReturnRequestInput{Items: []OrderReturnItem{{ItemIndex: 0, Quantity: 1}}, Reason: "changed mind"})
+	requireOrderBizErr(t, err, "order.returnStatusInvalid")
+
+	input := ReturnRequestInput{Items: []OrderReturnItem{{ItemIndex: 0, Quantity: 1}}, Reason: "changed mind"}
+	first, err := svc.Create(userID, completed.OrderNo, input)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	_, err = svc.Reject(first.ID, 1, "")
+	requireOrderBizErr(t, err, "return.remarkRequired")
+	rejected, err := svc.Reject(first.ID, 1, "Outside return window")
+	if err != nil {
+		t.Fatalf("reject failed: %v", err)
+	}
+	if rejected.Status != models.ReturnRequestStatusRejected || rejected.AdminRemark != "Outside return window" {
+		t.Fatalf("unexpected rejected request: %+v", rejected)
+	}
+
+	// 被拒绝后可重新申请，待审核时客户可撤回
+	second, err := svc.Create(userID, completed.OrderNo, input)
+	if err != nil {
+		t.Fatalf("create after reject failed: %v", err)
+	}
+	cancelled, err := svc.Cancel(userID, completed.OrderNo, second.ID)
+	if err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+	if cancelled.Status != models.ReturnRequestStatusCancelled {
+		t.Fatalf("unexpected cancelled request: %+v", cancelled)
+	}
+	items, err := svc.ListForOrder(userID, completed.OrderNo)
+	if err != nil || len(items) != 2 {
+		t.Fatalf("expected 2 return requests, got %d err %v", len(items), err)
+	}
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/bits"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/cache"
+	"gorm.io/gorm"
+)
+
+const (
+	// 同一 IP 同类防爬事件的最短记录间隔，避免持续爬取时刷爆安全事件表
+	scraperEventInterval = time.Minute
+	// 窗口内被要求挑战的次数超过该值才记为事件，正常浏览器首次请求也会被要求挑战
+	scraperChallengeEventThreshold = 10
+)
+
+var scraperEventTypes = []string{
+	models.SecurityEventScraperBurst,
+	models.SecurityEventScraperHoneypot,
+	models.SecurityEventScraperChallenge,
+}
+
+// CatalogChallenge JS 挑战：客户端需找到 nonce，使 sha256(challenge + ":" + nonce) 至少有 Difficulty 个前导零比特
+type CatalogChallenge struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// ScraperOffender 时间窗口内触发防爬事件的 IP
+type ScraperOffender struct {
+	IPAddress       string    `json:"ip_address"`
+	Events          int64     `json:"events"`
+	BurstEvents     int64     `json:"burst_events"`
+	HoneypotEvents  int64     `json:"honeypot_events"`
+	ChallengeEvents int64     `json:"challenge_events"`
+	LastSeenAt      time.Time `json:"last_seen_at" gorm:"-"`
+	LastUserAgent   string    `json:"last_user_agent,omitempty" gorm:"-"`
+	Banned          bool      `json:"banned" gorm:"-"`
+}
+
+// ScraperProtectionService 商品目录防爬：突发检测、蜜罐参数和 JS 挑战令牌，封禁复用登录保护的 IP 封禁
+type ScraperProtectionService struct {
+	db              *gorm.DB
+	cfg             *config.Config
+	loginProtection *LoginProtectionService
+
+	eventMu    sync.Mutex
+	lastEvents map[string]time.Time
+}
+
+func NewScraperProtectionService(db *gorm.DB, cfg *config.Config, loginProtection *LoginProtectionService) *ScraperProtectionService {
+	return &ScraperProtectionService{
+		db:              db,
+		cfg:             cfg,
+		loginProtection: loginProtection,
+		lastEvents:      make(map[string]time.Time),
+	}
+}
+
+// settings 当前配置，未配置的阈值使用默认值
+func (s *ScraperProtectionService) settings() config.ScraperProtectionConfig {
+	var settings config.ScraperProtectionConfig
+	if s.cfg != nil {
+		settings = s.cfg.Security.ScraperProtection
+	}
+	if settings.BurstRequests <= 0 {
+		settings.BurstRequests = 120
+	}
+	if settings.BurstWindowSeconds <= 0 {
+		settings.BurstWindowSeconds = 60
+	}
+	if settings.ChallengeDifficulty <= 0 || settings.ChallengeDifficulty > 24 {
+		settings.ChallengeDifficulty = 16
+	}
+	if settings.ChallengeTTLMinutes <= 0 {
+		settings.ChallengeTTLMinutes = 30
+	}
+	return settings
+}
+
+// InspectCatalogRequest 依次检查蜜罐参数、突发请求和挑战令牌
+func (s *ScraperProtectionService) InspectCatalogRequest(ip, userAgent string, query url.Values, token string) middleware.ScraperVerdict {
+	settings := s.settings()
+	ip = strings.TrimSpace(ip)
+	if !settings.Enabled || ip == "" {
+		return middleware.ScraperAllow
+	}
+
+	for _, param := range settings.HoneypotParams {
+		if param = strings.TrimSpace(param); param != "" && query.Has(param) {
+			s.handleHoneypot(settings, ip, userAgent, param)
+			return middleware.ScraperHoneypot
+		}
+	}
+
+	window := time.Duration(settings.BurstWindowSeconds) * time.Second
+	if count := s.incrWindowCounter("scraper:burst:"+ip, window); count > int64(settings.BurstRequests) {
+		if count == int64(settings.BurstRequests)+1 {
+			s.recordEvent(models.SecurityEventScraperBurst, models.SecuritySeverityWarning, ip, userAgent, map[string]interface{}{
+				"burst_requests": settings.BurstRequests,
+				"window_seconds": settings.BurstWindowSeconds,
+			})
+		}
+		return middleware.ScraperBurst
+	}
+
+	if settings.ChallengeEnabled && !s.verifyChallengeToken(settings, ip, token) {
+		if count := s.incrWindowCounter("scraper:challenge:"+ip, window); count > scraperChallengeEventThreshold {
+			s.recordEvent(models.SecurityEventScraperChallenge, models.SecuritySeverityWarning, ip, userAgent, map[string]interface{}{
+				"rejected_requests": count,
+				"token_present":     token != "",
+			})
+		}
+		return middleware.ScraperChallengeRequired
+	}
+	return middleware.ScraperAllow
+}
+
+func (s *ScraperProtectionService) handleHoneypot(settings config.ScraperProtectionConfig, ip, userAgent, param string) {
+	details := map[string]interface{}{"param": param}
+	if settings.HoneypotAutoBan && s.loginProtection != nil && !s.loginProtection.IsIPBanned(ip) {
+		ban := &models.IPBan{
+			IPAddress: ip,
+			Reason:    "Automatic ban: catalog honeypot triggered",
+			Source:    models.IPBanSourceAuto,
+		}
+		if settings.AutoBanMinutes > 0 {
+			expiresAt := models.NowFunc().Add(time.Duration(settings.AutoBanMinutes) * time.Minute)
+			ban.ExpiresAt = &expiresAt
+		}
+		if err := s.loginProtection.saveBan(ban); err != nil {
+			log.Printf("scraper honeypot auto ban failed: ip=%s err=%v", ip, err)
+		} else {
+			details["ban_id"] = ban.ID
+			details["expires_at"] = ban.ExpiresAt
+		}
+	}
+	s.recordEvent(models.SecurityEventScraperHoneypot, models.SecuritySeverityCritical, ip, userAgent, details)
+}
+
+// incrWindowCounter 按固定窗口计数，缓存不可用时返回 0（放行）
+func (s *ScraperProtectionService) incrWindowCounter(prefix string, window time.Duration) int64 {
+	key := fmt.Sprintf("%s:%d", prefix, models.NowFunc().Unix()/int64(window.Seconds()))
+	count, err := cache.Incr(key)
+	if err != nil {
+		return 0
+	}
+	if count == 1 {
+		cache.Expire(key, window)
+	}
+	return count
+}
+
+// recordEvent 记录防爬事件，同一 IP 同类事件每分钟最多一条
+func (s *ScraperProtectionService) recordEvent(eventType, severity, ip, userAgent string, details map[string]interface{}) {
+	now := models.NowFunc()
+	key := eventType + "|" + ip
+	s.eventMu.Lock()
+	if last, ok := s.lastEvents[key]; ok && now.Sub(last) < scraperEventInterval {
+		s.eventMu.Unlock()
+		return
+	}
+	s.lastEvents[key] = now
+	for k, last := range s.lastEvents {
+		if now.Sub(last) >= scraperEventInterval {
+			delete(s.lastEvents, k)
+		}
+	}
+	s.eventMu.Unlock()
+
+	event := &models.SecurityEvent{
+		EventType: eventType,
+		Severity:  severity,
+		IPAddress: ip,
+		UserAgent: userAgent,
+		Details:   details,
+	}
+	if err := s.db.Create(event).Error; err != nil {
+		log.Printf("record scraper event failed: type=%s ip=%s err=%v", eventType, ip, err)
+	}
+}
+
+// IssueChallenge 签发绑定 IP 的挑战，无需服务端存储
+func (s *ScraperProtectionService) IssueChallenge(ip string) (*CatalogChallenge, error) {
+	settings := s.settings()
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	expiresAt := models.NowFunc().Add(time.Duration(settings.ChallengeTTLMinutes) * time.Minute)
+	payload := fmt.Sprintf("%d.%s", expiresAt.Unix(), hex.EncodeToString(nonce))
+	return &CatalogChallenge{
+		Challenge:  payload + "." + s.challengeSignature(payload, strings.TrimSpace(ip)),
+		Difficulty: settings.ChallengeDifficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// verifyChallengeToken 令牌格式为 challenge:nonce，校验签名、有效期和工作量证明
+func (s *ScraperProtectionService) verifyChallengeToken(settings config.ScraperProtectionConfig, ip, token string) bool {
+	challenge, nonce, ok := strings.Cut(strings.TrimSpace(token), ":")
+	if !ok || nonce == "" {
+		return false
+	}
+	if _, err := strconv.ParseUint(nonce, 10, 64); err != nil {
+		return false
+	}
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	expiresUnix, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || !models.NowFunc().Before(time.Unix(expiresUnix, 0)) {
+		return false
+	}
+	expected := s.challengeSignature(parts[0]+"."+parts[1], ip)
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return false
+	}
+	return leadingZeroBits(sha256.Sum256([]byte(challenge+":"+nonce))) >= settings.ChallengeDifficulty
+}
+
+func (s *ScraperProtectionService) challengeSignature(payload, ip string) string {
+	secret := ""
+	if s.cfg != nil {
+		secret = s.cfg.JWT.Secret
+	}
+	mac := hmac.New(sha256.New, []byte("catalog-challenge:"+secret))
+	mac.Write([]byte(payload + "|" + ip))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+func leadingZeroBits(sum [sha256.Size]byte) int {
+	count := 0
+	for _, b := range sum {
+		if b != 0 {
+			return count + bits.LeadingZeros8(b)
+		}
+		count += 8
+	}
+	return count
+}
+
+// TopOffenders 最近 hours 小时内触发防爬事件最多的 IP
+func (s *ScraperProtectionService) TopOffenders(hours, limit int) ([]ScraperOffender, error) {
+	if hours <= 0 {
+		hours = 24
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	since := models.NowFunc().Add(-time.Duration(hours) * time.Hour)
+	var offenders []ScraperOffender
+	err := s.db.Model(&models.SecurityEvent{}).
+		Select("ip_address, COUNT(*) AS events, "+
+			"SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS burst_events, "+
+			"SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS honeypot_events, "+
+			"SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS challenge_events",
+			models.SecurityEventScraperBurst, models.SecurityEventScraperHoneypot, models.SecurityEventScraperChallenge).
+		Where("event_type IN ? AND created_at >= ? AND ip_address <> ''", scraperEventTypes, since).
+		Group("ip_address").
+		Order("events DESC, ip_address").
+		Limit(limit).
+		Scan(&offenders).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range offenders {
+		var latest models.SecurityEvent
+		if err := s.db.Where("ip_address = ? AND event_type IN ?", offenders[i].IPAddress, scraperEventTypes).
+			Order("id DESC").First(&latest).Error; err == nil {
+			offenders[i].LastSeenAt = latest.CreatedAt
+			offenders[i].LastUserAgent = latest.UserAgent
+		}
+		if s.loginProtection != nil {
+			offenders[i].Banned = s.loginProtection.IsIPBanned(offenders[i].IPAddress)
+		}
+	}
+	return offenders, nil
+}
+
+// BanOffender 一键封禁爬虫 IP，时长使用 auto_ban_minutes
+func (s *ScraperProtectionService) BanOffender(ip string, adminID uint) (*models.IPBan, error) {
+	return s.loginProtection.BanIP(IPBanInput{
+		IPAddress:       ip,
+		Reason:          "Catalog scraping",
+		DurationMinutes: s.settings().AutoBanMinutes,
+	}, adminID)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"crypto/sha256"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/middleware"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/cache"
+)
+
+func newScraperProtectionTestService(t *testing.T, protection config.ScraperProtectionConfig) *ScraperProtectionService {
+	t.Helper()
+	cache.InitMemory()
+	db := openConcurrentServiceTestDB(t, &models.IPBan{}, &models.SecurityEvent{})
+	cfg := &config.Config{}
+	cfg.JWT.Secret = "scraper-test-secret-0123456789abcdef"
+	cfg.Security.ScraperProtection = protection
+	return NewScraperProtectionService(db, cfg, NewLoginProtectionService(db, cfg))
+}
+
+func solveCatalogChallenge(challenge *CatalogChallenge) string {
+	for nonce := 0; ; nonce++ {
+		token := challenge.Challenge + ":" + strconv.Itoa(nonce)
+		if leadingZeroBits(sha256.Sum256([]byte(token))) >= challenge.Difficulty {
+			return token
+		}
+	}
+}
+
+func TestScraperProtectionHoneypotBurstAndOffenders(t *testing.T) {
+	svc := newScraperProtectionTestService(t, config.ScraperProtectionConfig{
+		Enabled:            true,
+		BurstRequests:      3,
+		BurstWindowSeconds: 3600,
+		HoneypotParams:     []string{"ref_code"},
+		HoneypotAutoBan:    true,
+		AutoBanMinutes:     30,
+	})
+
+	if verdict := svc.InspectCatalogRequest("198.51.100.9", "curl/8", url.Values{"ref_code": {"1"}}, ""); verdict != middleware.ScraperHoneypot {
+		t.Fatalf("honeypot parameter should be flagged, got %v", verdict)
+	}
+	if !svc.loginProtection.IsIPBanned("198.51.100.9") {
+		t.Fatal("honeypot hit should auto-ban the IP")
+	}
+
+	for i := 0; i < 3; i++ {
+		if verdict := svc.InspectCatalogRequest("203.0.113.5", "bot", url.Values{}, ""); verdict != middleware.ScraperAllow {
+			t.Fatalf("request %d within the burst limit was blocked: %v", i+1, verdict)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if verdict := svc.InspectCatalogRequest("203.0.113.5", "bot", url.Values{}, ""); verdict != middleware.ScraperBurst {
+			t.Fatalf("requests over the burst limit should be rejected, got %v", verdict)
+		}
+	}
+
+	offenders, err := svc.TopOffenders(24, 10)
+	if err != nil || len(offenders) != 2 {
+		t.Fatalf("expected two offenders: %+v, %v", offenders, err)
+	}
+	byIP := map[string]ScraperOffender{}
+	for _, offender := range offenders {
+		byIP[offender.IPAddress] = offender
+	}
+	if o := byIP["198.51.100.9"]; o.HoneypotEvents != 1 || !o.Banned || o.LastUserAgent != "curl/8" {
+		t.Fatalf("unexpected honeypot offender: %+v", o)
+	}
+	if o := byIP["203.0.113.5"]; o.BurstEvents != 1 || o.Events != 1 || o.Banned {
+		t.Fatalf("burst should be recorded once per window: %+v", o)
+	}
+
+	ban, err := svc.BanOffender("203.0.113.5", 1)
+	if err != nil || ban.ExpiresAt == nil || !svc.loginProtection.IsIPBanned("203.0.113.5") {
+		t.Fatalf("one-click ban should use auto_ban_minutes: %+v, %v", ban, err)
+	}
+}
+
+func TestScraperProtectionChallengeToken(t *testing.T) {
+	svc := newScraperProtectionTestService(t, config.ScraperProtectionConfig{
+		Enabled:             true,
+		ChallengeEnabled:    true,
+		ChallengeDifficulty: 8,
+	})
+
+	if verdict := svc.InspectCatalogRequest("192.0.2.1", "", url.Values{}, ""); verdict != middleware.ScraperChallengeRequired {
+		t.Fatalf("requests without a token should be challenged, got %v", verdict)
+	}
+	challenge, err := svc.IssueChallenge("192.0.2.1")
+	if err != nil || challenge.Difficulty != 8 {
+		t.Fatalf("issue challenge: %+v, %v", challenge, err)
+	}
+	token := solveCatalogChallenge(challenge)
+	if verdict := svc.InspectCatalogRequest("192.0.2.1", "", url.Values{}, token); verdict != middleware.ScraperAllow {
+		t.Fatalf("solved token should be accepted, got %v", verdict)
+	}
+	if verdict := svc.InspectCatalogRequest("192.0.2.2", "", url.Values{}, token); verdict != middleware.ScraperChallengeRequired {
+		t.Fatal("tokens are bound to the IP they were issued for")
+	}
+	if verdict := svc.InspectCatalogRequest("192.0.2.1", "", url.Values{}, challenge.Challenge+":abc"); verdict != middleware.ScraperChallengeRequired {
+		t.Fatal("malformed nonce should be rejected")
+	}
+}
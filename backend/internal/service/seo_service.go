@@ -0,0 +1,275 @@
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/money"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+// maxSitemapURLs sitemap 协议单文件 URL 上限
+const maxSitemapURLs = 50000
+
+// robotsDefaultDisallow 默认禁止抓取的非公开路径
+var robotsDefaultDisallow = []string{"/admin", "/api/", "/form/", "/orders", "/cart", "/profile", "/tickets", "/login", "/register"}
+
+type sitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// SEOService 生成 sitemap.xml、robots.txt 与商品结构化数据
+type SEOService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewSEOService(db *gorm.DB, cfg *config.Config) *SEOService {
+	return &SEOService{db: db, cfg: cfg}
+}
+
+// Config 当前运行时配置
+func (s *SEOService) Config() *config.Config {
+	if s.cfg != nil {
+		return s.cfg
+	}
+	return config.GetConfig()
+}
+
+// BaseURL 站点根地址：请求 Host 属于当前店铺的自定义域名时使用该域名，否则使用 app.url
+func (s *SEOService) BaseURL(store *models.Store, host string) string {
+	cfg := s.Config()
+	baseURL := ""
+	if cfg != nil {
+		baseURL = strings.TrimRight(strings.TrimSpace(cfg.App.URL), "/")
+		if baseURL == "" {
+			baseURL = fmt.Sprintf("http://localhost:%d", cfg.App.Port)
+		}
+	}
+	if store == nil {
+		return baseURL
+	}
+
+	normalizedHost := NormalizeStoreHost(host)
+	for _, domain := range store.Domains {
+		if domain.Host != normalizedHost {
+			continue
+		}
+		scheme := "https"
+		if parsed, err := url.Parse(baseURL); err == nil && parsed.Scheme != "" {
+			scheme = parsed.Scheme
+		}
+		return scheme + "://" + domain.Host
+	}
+	return baseURL
+}
+
+func absoluteSEOURL(baseURL, raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.HasPrefix(raw, "http://") || strings.HasPrefix(raw, "https://") {
+		return raw
+	}
+	if !strings.HasPrefix(raw, "/") {
+		raw = "/" + raw
+	}
+	return baseURL + raw
+}
+
+func truncateSEOText(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
+
+// BuildProductSEO 生成商品 SEO 元数据与 JSON-LD Product
+func (s *SEOService) BuildProductSEO(product *models.Product, baseURL string) *models.ProductSEO {
+	if product == nil {
+		return nil
+	}
+	cfg := s.Config()
+
+	title := product.MetaTitle
+	if title == "" {
+		title = product.Name
+	}
+	description := product.MetaDescription
+	if description == "" {
+		description = truncateSEOText(product.ShortDescription, 160)
+	}
+	image := product.OGImage
+	if image == "" {
+		image = product.GetPrimaryImage()
+	}
+	if image == "" && cfg != nil {
+		image = cfg.SEO.DefaultOGImage
+	}
+	image = absoluteSEOURL(baseURL, image)
+	canonicalURL := fmt.Sprintf("%s/products/%d", baseURL, product.ID)
+
+	currency := "CNY"
+	if cfg != nil && cfg.Order.Currency != "" {
+		currency = cfg.Order.Currency
+	}
+	availability := "https://schema.org/OutOfStock"
+	if product.IsAvailable() {
+		availability = "https://schema.org/InStock"
+	}
+
+	offers := map[string]interface{}{
+		"@type":        "Offer",
+		"availability": availability,
+		"url":          canonicalURL,
+	}
+	// 询价商品的结构化数据不公开价格
+	if !product.PriceOnRequest {
+		offers["price"] = money.MinorToString(product.Price)
+		offers["priceCurrency"] = currency
+	}
+	jsonLD := map[string]interface{}{
+		"@context": "https://schema.org",
+		"@type":    "Product",
+		"name":     product.Name,
+		"sku":      product.SKU,
+		"url":      canonicalURL,
+		"offers":   offers,
+	}
+	if description != "" {
+		jsonLD["description"] = description
+	}
+	if product.Category != "" {
+		jsonLD["category"] = product.Category
+	}
+	images := make([]string, 0, len(product.Images)+1)
+	for _, img := range product.Images {
+		if img.URL != "" {
+			images = append(images, absoluteSEOURL(baseURL, img.URL))
+		}
+	}
+	if len(images) == 0 && image != "" {
+		images = append(images, image)
+	}
+	if len(images) > 0 {
+		jsonLD["image"] = images
+	}
+
+	return &models.ProductSEO{
+		Title:        title,
+		Description:  description,
+		Image:        image,
+		CanonicalURL: canonicalURL,
+		JSONLD:       jsonLD,
+	}
+}
+
+// BuildSitemap 生成 sitemap.xml：首页、商品列表与详情（需允许访客浏览）、知识库文章（需开启收录）
+func (s *SEOService) BuildSitemap(baseURL string, storeID uint) ([]byte, error) {
+	cfg := s.Config()
+	urls := []sitemapURL{{Loc: baseURL + "/", ChangeFreq: "daily", Priority: "1.0"}}
+
+	if cfg != nil && cfg.Security.Login.AllowGuestProductBrowse {
+		urls = append(urls, sitemapURL{Loc: baseURL + "/products", ChangeFreq: "daily", Priority: "0.8"})
+
+		var products []models.Product
+		err := repository.StorefrontScope(storeID).Apply(s.db.Model(&models.Product{})).
+			Select("id", "updated_at").
+			Where("status = ?", models.ProductStatusActive).
+			Order("sort_order DESC, id ASC").
+			Limit(maxSitemapURLs).
+			Find(&products).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			urls = append(urls, sitemapURL{
+				Loc:        fmt.Sprintf("%s/products/%d", baseURL, product.ID),
+				LastMod:    product.UpdatedAt.UTC().Format(time.RFC3339),
+				ChangeFreq: "weekly",
+				Priority:   "0.7",
+			})
+		}
+	}
+
+	if cfg != nil && cfg.SEO.IncludeKnowledge {
+		var articles []models.KnowledgeArticle
+		if err := s.db.Model(&models.KnowledgeArticle{}).
+			Select("id", "updated_at").
+			Order("id ASC").
+			Limit(maxSitemapURLs).
+			Find(&articles).Error; err != nil {
+			return nil, err
+		}
+		for _, article := range articles {
+			urls = append(urls, sitemapURL{
+				Loc:        fmt.Sprintf("%s/knowledge/%d", baseURL, article.ID),
+				LastMod:    article.UpdatedAt.UTC().Format(time.RFC3339),
+				ChangeFreq: "monthly",
+				Priority:   "0.5",
+			})
+		}
+	}
+
+	if len(urls) > maxSitemapURLs {
+		urls = urls[:maxSitemapURLs]
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(sitemapURLSet{
+		XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		URLs:  urls,
+	}); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// BuildRobots 生成 robots.txt
+func (s *SEOService) BuildRobots(baseURL string) string {
+	cfg := s.Config()
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	disallow := append([]string{}, robotsDefaultDisallow...)
+	if cfg != nil {
+		disallow = append(disallow, cfg.SEO.RobotsDisallow...)
+	}
+	seen := make(map[string]struct{}, len(disallow))
+	for _, path := range disallow {
+		path = strings.TrimSpace(path)
+		if path == "" || strings.ContainsAny(path, "\r\n") {
+			continue
+		}
+		if _, exists := seen[path]; exists {
+			continue
+		}
+		seen[path] = struct{}{}
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("Allow: /\n")
+	if cfg != nil && cfg.SEO.SitemapEnabled {
+		b.WriteString("\nSitemap: " + baseURL + "/sitemap.xml\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newSEOServiceForTest(t *testing.T, cfg *config.Config) *SEOService {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Product{}, &models.KnowledgeArticle{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	return NewSEOService(db, cfg)
+}
+
+func TestSEOServiceSitemapRespectsVisibility(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.SEO.SitemapEnabled = true
+	svc := newSEOServiceForTest(t, cfg)
+
+	storeID := uint(2)
+	otherStoreID := uint(3)
+	products := []models.Product{
+		{SKU: "A", Name: "Active", Status: models.ProductStatusActive},
+		{SKU: "B", Name: "Draft", Status: models.ProductStatusDraft},
+		{SKU: "C", Name: "Store", Status: models.ProductStatusActive, StoreID: &storeID},
+		{SKU: "D", Name: "Other store", Status: models.ProductStatusActive, StoreID: &otherStoreID},
+	}
+	for i := range products {
+		if err := svc.db.Create(&products[i]).Error; err != nil {
+			t.Fatalf("create product: %v", err)
+		}
+	}
+	if err := svc.db.Create(&models.KnowledgeArticle{Title: "FAQ"}).Error; err != nil {
+		t.Fatalf("create article: %v", err)
+	}
+
+	data, err := svc.BuildSitemap("https://shop.example.com", storeID)
+	if err != nil {
+		t.Fatalf("build sitemap: %v", err)
+	}
+	if strings.Contains(string(data), "/products/") || strings.Contains(string(data), "/knowledge/") {
+		t.Fatalf("expected no product or knowledge urls without guest browse, got:\n%s", data)
+	}
+
+	cfg.Security.Login.AllowGuestProductBrowse = true
+	cfg.SEO.IncludeKnowledge = true
+	data, err = svc.BuildSitemap("https://shop.example.com", storeID)
+	if err != nil {
+		t.Fatalf("build sitemap: %v", err)
+	}
+	sitemap := string(data)
+	for _, expected := range []string{
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+		fmt.Sprintf("<loc>https://shop.example.com/products/%d</loc>", products[0].ID),
+		fmt.Sprintf("<loc>https://shop.example.com/products/%d</loc>", products[2].ID),
+		"<loc>https://shop.example.com/knowledge/1</loc>",
+	} {
+		if !strings.Contains(sitemap, expected) {
+			t.Fatalf("expected sitemap to contain %s, got:\n%s", expected, sitemap)
+		}
+	}
+	for _, unexpected := range []*models.Product{&products[1], &products[3]} {
+		if strings.Contains(sitemap, fmt.Sprintf("/products/%d<", unexpected.ID)) {
+			t.Fatalf("unexpected product %q in sitemap:\n%s", unexpected.Name, sitemap)
+		}
+	}
+}
+
+func TestSEOServiceProductSEOFallbacks(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Order.Currency = "USD"
+	svc := newSEOServiceForTest(t, cfg)
+
+	product := &models.Product{
+		ID:               7,
+		SKU:              "SKU-7",
+		Name:             "Lamp",
+		ShortDescription: "A   warm\nlamp",
+		Price:            1999,
+		Stock:            3,
+		Status:           models.ProductStatusActive,
+		Images:           []models.ProductImage{{URL: "/uploads/products/lamp.jpg", IsPrimary: true}},
+	}
+	seo := svc.BuildProductSEO(product, "https://shop.example.com")
+	if seo.Title != "Lamp" || seo.Description != "A warm lamp" {
+		t.Fatalf("expected fallback title/description, got %+v", seo)
+	}
+	if seo.Image != "https://shop.example.com/uploads/products/lamp.jpg" || seo.CanonicalURL != "https://shop.example.com/products/7" {
+		t.Fatalf("expected absolute urls, got image=%q canonical=%q", seo.Image, seo.CanonicalURL)
+	}
+	offers, _ := seo.JSONLD["offers"].(map[string]interface{})
+	if seo.JSONLD["@type"] != "Product" || offers["price"] != "19.99" || offers["priceCurrency"] != "USD" || offers["availability"] != "https://schema.org/InStock" {
+		t.Fatalf("unexpected json-ld: %+v", seo.JSONLD)
+	}
+
+	product.MetaTitle = "Best Lamp"
+	product.OGImage = "https://cdn.example.com/og.png"
+	product.Stock = 0
+	seo = svc.BuildProductSEO(product, "https://shop.example.com")
+	offers, _ = seo.JSONLD["offers"].(map[string]interface{})
+	if seo.Title != "Best Lamp" || seo.Image != "https://cdn.example.com/og.png" || offers["availability"] != "https://schema.org/OutOfStock" {
+		t.Fatalf("expected explicit SEO fields, got %+v", seo)
+	}
+}
+
+func TestSEOServiceRobotsAndBaseURL(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.App.URL = "https://shop.example.com/"
+	cfg.SEO.RobotsDisallow = []string{"/private", "/admin"}
+	svc := newSEOServiceForTest(t, cfg)
+
+	robots := svc.BuildRobots(svc.BaseURL(nil, "evil.example.com"))
+	if strings.Count(robots, "Disallow: /admin\n") != 1 || !strings.Contains(robots, "Disallow: /private\n") {
+		t.Fatalf("unexpected robots.txt:\n%s", robots)
+	}
+	if strings.Contains(robots, "Sitemap:") {
+		t.Fatalf("expected no sitemap line when disabled:\n%s", robots)
+	}
+
+	cfg.SEO.SitemapEnabled = true
+	store := &models.Store{Domains: []models.StoreDomain{{Host: "brand.example.com"}}}
+	if baseURL := svc.BaseURL(store, "Brand.Example.com:443"); baseURL != "https://brand.example.com" {
+		t.Fatalf("expected store domain base url, got %q", baseURL)
+	}
+	if baseURL := svc.BaseURL(store, "evil.example.com"); baseURL != "https://shop.example.com" {
+		t.Fatalf("expected app url for unknown host, got %q", baseURL)
+	}
+	robots = svc.BuildRobots("https://brand.example.com")
+	if !strings.Contains(robots, "Sitemap: https://brand.example.com/sitemap.xml") {
+		t.Fatalf("expected sitemap line, got:\n%s", robots)
+	}
+}
@@ -0,0 +1,470 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+const (
+	ShipmentTrackingProvider17Track   = "17track"
+	ShipmentTrackingProviderAfterShip = "aftership"
+
+	shipmentTrackingMaxResponseBytes = 2 << 20
+	// 17track 重复注册的错误码，视为注册成功
+	seventeenTrackAlreadyRegisteredCode = -18019901
+	// AfterShip 重复创建运单的错误码，视为注册成功
+	afterShipTrackingExistsCode = 4003
+)
+
+// ErrShipmentTrackingSignature Webhook 签名校验失败
+var ErrShipmentTrackingSignature = errors.New("invalid shipment tracking webhook signature")
+
+// ShipmentTrackingUpdate 服务商返回的运单最新状态与完整轨迹
+type ShipmentTrackingUpdate struct {
+	TrackingNo  string
+	Carrier     string
+	Status      models.ShipmentTrackingStatus
+	Events      []models.ShipmentTrackingEvent
+	DeliveredAt *time.Time
+}
+
+// ShipmentTrackingProvider 物流轨迹服务商
+type ShipmentTrackingProvider interface {
+	Name() string
+	// Register 注册运单号，服务商开始追踪并推送更新；重复注册不视为错误
+	Register(ctx context.Context, trackingNo string) error
+	// Fetch 拉取运单最新轨迹，服务商尚无数据时返回 nil
+	Fetch(ctx context.Context, trackingNo string) (*ShipmentTrackingUpdate, error)
+	// ParseWebhook 校验推送签名并解析运单更新
+	ParseWebhook(header http.Header, body []byte) ([]ShipmentTrackingUpdate, error)
+}
+
+func requestShipmentTrackingJSON(ctx context.Context, client *http.Client, method, target string, headers map[string]string, payload interface{}, out interface{}) error {
+	var reader io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "AuraLogic-ShipmentTracking/1.0")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, shipmentTrackingMaxResponseBytes))
+	if err != nil {
+		return err
+	}
+	// AfterShip 的业务错误同样以非 2xx 返回，响应体中带有错误码，先尝试解析
+	if err := json.Unmarshal(body, out); err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+		}
+		return err
+	}
+	return nil
+}
+
+// sortShipmentTrackingEvents 轨迹按时间倒序，最新的在最前
+func sortShipmentTrackingEvents(events []models.ShipmentTrackingEvent) []models.ShipmentTrackingEvent {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.After(events[j].Time)
+	})
+	return events
+}
+
+func parseShipmentTrackingTime(values ...string) time.Time {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05"} {
+			if parsed, err := time.Parse(layout, value); err == nil {
+				return parsed.UTC()
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// shipmentTrackingDeliveredAt 签收时间取轨迹中最近一条签收记录，没有时取最新轨迹时间
+func shipmentTrackingDeliveredAt(status models.ShipmentTrackingStatus, events []models.ShipmentTrackingEvent) *time.Time {
+	if status != models.ShipmentTrackingStatusDelivered {
+		return nil
+	}
+	for _, event := range events {
+		if event.Status == string(models.ShipmentTrackingStatusDelivered) && !event.Time.IsZero() {
+			at := event.Time
+			return &at
+		}
+	}
+	if len(events) > 0 && !events[0].Time.IsZero() {
+		at := events[0].Time
+		return &at
+	}
+	return nil
+}
+
+// seventeenTrackProvider 17track 轨迹 API v2.2
+type seventeenTrackProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  func() string
+}
+
+func (p *seventeenTrackProvider) Name() string {
+	return ShipmentTrackingProvider17Track
+}
+
+type seventeenTrackError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type seventeenTrackInfo struct {
+	Number    string `json:"number"`
+	Carrier   int    `json:"carrier"`
+	TrackInfo *struct {
+		LatestStatus struct {
+			Status string `json:"status"`
+		} `json:"latest_status"`
+		Tracking struct {
+			Providers []struct {
+				Provider struct {
+					Name string `json:"name"`
+				} `json:"provider"`
+				Events []struct {
+					TimeISO     string `json:"time_iso"`
+					TimeUTC     string `json:"time_utc"`
+					Description string `json:"description"`
+					Location    string `json:"location"`
+					Stage       string `json:"stage"`
+				} `json:"events"`
+			} `json:"providers"`
+		} `json:"tracking"`
+	} `json:"track_info"`
+}
+
+type seventeenTrackResponse struct {
+	Code int `json:"code"`
+	Data struct {
+		Accepted []seventeenTrackInfo `json:"accepted"`
+		Rejected []struct {
+			Number string              `json:"number"`
+			Error  seventeenTrackError `json:"error"`
+		} `json:"rejected"`
+		Errors []seventeenTrackError `json:"errors"`
+	} `json:"data"`
+}
+
+// map17TrackStatus 17track 主状态 -> 归一化状态
+func map17TrackStatus(status string) models.ShipmentTrackingStatus {
+	switch status {
+	case "InTransit":
+		return models.ShipmentTrackingStatusInTransit
+	case "OutForDelivery", "AvailableForPickup":
+		return models.ShipmentTrackingStatusOutForDelivery
+	case "Delivered":
+		return models.ShipmentTrackingStatusDelivered
+	case "DeliveryFailure", "Exception", "Expired":
+		return models.ShipmentTrackingStatusException
+	default:
+		// NotFound、InfoReceived
+		return models.ShipmentTrackingStatusPending
+	}
+}
+
+func (p *seventeenTrackProvider) post(ctx context.Context, path, trackingNo string) (*seventeenTrackResponse, error) {
+	key := strings.TrimSpace(p.apiKey())
+	if key == "" {
+		return nil, errors.New("17track api key is not configured")
+	}
+	var resp seventeenTrackResponse
+	body := []map[string]string{{"number": trackingNo}}
+	if err := requestShipmentTrackingJSON(ctx, p.client, http.MethodPost, p.baseURL+path, map[string]string{"17token": key}, body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != 0 {
+		if len(resp.Data.Errors) > 0 {
+			return nil, fmt.Errorf("17track error %d: %s", resp.Data.Errors[0].Code, resp.Data.Errors[0].Message)
+		}
+		return nil, fmt.Errorf("17track error %d", resp.Code)
+	}
+	return &resp, nil
+}
+
+func (p *seventeenTrackProvider) Register(ctx context.Context, trackingNo string) error {
+	resp, err := p.post(ctx, "/register", trackingNo)
+	if err != nil {
+		return err
+	}
+	for _, rejected := range resp.Data.Rejected {
+		if rejected.Error.Code == seventeenTrackAlreadyRegisteredCode {
+			continue
+		}
+		return fmt.Errorf("17track rejected %s: %s", rejected.Number, rejected.Error.Message)
+	}
+	return nil
+}
+
+func (p *seventeenTrackProvider) Fetch(ctx context.Context, trackingNo string) (*ShipmentTrackingUpdate, error) {
+	resp, err := p.post(ctx, "/gettrackinfo", trackingNo)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data.Rejected) > 0 {
+		rejected := resp.Data.Rejected[0]
+		return nil, fmt.Errorf("17track rejected %s: %s", rejected.Number, rejected.Error.Message)
+	}
+	for _, info := range resp.Data.Accepted {
+		if update := p.convert(info); update != nil {
+			return update, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *seventeenTrackProvider) convert(info seventeenTrackInfo) *ShipmentTrackingUpdate {
+	if info.TrackInfo == nil || strings.TrimSpace(info.Number) == "" {
+		return nil
+	}
+	update := &ShipmentTrackingUpdate{
+		TrackingNo: strings.TrimSpace(info.Number),
+		Status:     map17TrackStatus(info.TrackInfo.LatestStatus.Status),
+	}
+	for _, provider := range info.TrackInfo.Tracking.Providers {
+		if update.Carrier == "" {
+			update.Carrier = strings.TrimSpace(provider.Provider.Name)
+		}
+		for _, event := range provider.Events {
+			update.Events = append(update.Events, models.ShipmentTrackingEvent{
+				Time:        parseShipmentTrackingTime(event.TimeUTC, event.TimeISO),
+				Status:      string(map17TrackStatus(event.Stage)),
+				Description: strings.TrimSpace(event.Description),
+				Location:    strings.TrimSpace(event.Location),
+			})
+		}
+	}
+	// 没有承运商名称时保留 17track 的承运商代码
+	if update.Carrier == "" && info.Carrier > 0 {
+		update.Carrier = strconv.Itoa(info.Carrier)
+	}
+	update.Events = sortShipmentTrackingEvents(update.Events)
+	update.DeliveredAt = shipmentTrackingDeliveredAt(update.Status, update.Events)
+	return update
+}
+
+// ParseWebhook 17track 推送签名为 sha256(原始报文 + "/" + API Key) 的十六进制
+func (p *seventeenTrackProvider) ParseWebhook(header http.Header, body []byte) ([]ShipmentTrackingUpdate, error) {
+	key := strings.TrimSpace(p.apiKey())
+	sign := strings.ToLower(strings.TrimSpace(header.Get("sign")))
+	if key == "" || sign == "" {
+		return nil, ErrShipmentTrackingSignature
+	}
+	sum := sha256.Sum256([]byte(string(body) + "/" + key))
+	if !hmac.Equal([]byte(hex.EncodeToString(sum[:])), []byte(sign)) {
+		return nil, ErrShipmentTrackingSignature
+	}
+
+	var payload struct {
+		Event string             `json:"event"`
+		Data  seventeenTrackInfo `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Event != "TRACKING_UPDATED" {
+		return nil, nil
+	}
+	if update := p.convert(payload.Data); update != nil {
+		return []ShipmentTrackingUpdate{*update}, nil
+	}
+	return nil, nil
+}
+
+// afterShipProvider AfterShip Tracking API v4
+type afterShipProvider struct {
+	client        *http.Client
+	baseURL       string
+	apiKey        func() string
+	webhookSecret func() string
+}
+
+func (p *afterShipProvider) Name() string {
+	return ShipmentTrackingProviderAfterShip
+}
+
+type afterShipTracking struct {
+	TrackingNumber       string `json:"tracking_number"`
+	Slug                 string `json:"slug"`
+	Tag                  string `json:"tag"`
+	ShipmentDeliveryDate string `json:"shipment_delivery_date"`
+	Checkpoints          []struct {
+		CheckpointTime string `json:"checkpoint_time"`
+		Message        string `json:"message"`
+		Location       string `json:"location"`
+		City           string `json:"city"`
+		CountryName    string `json:"country_name"`
+		Tag            string `json:"tag"`
+	} `json:"checkpoints"`
+}
+
+type afterShipResponse struct {
+	Meta struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"meta"`
+	Data struct {
+		Tracking  *afterShipTracking  `json:"tracking"`
+		Trackings []afterShipTracking `json:"trackings"`
+	} `json:"data"`
+}
+
+// mapAfterShipTag AfterShip tag -> 归一化状态
+func mapAfterShipTag(tag string) models.ShipmentTrackingStatus {
+	switch tag {
+	case "InTransit":
+		return models.ShipmentTrackingStatusInTransit
+	case "OutForDelivery", "AvailableForPickup":
+		return models.ShipmentTrackingStatusOutForDelivery
+	case "Delivered":
+		return models.ShipmentTrackingStatusDelivered
+	case "AttemptFail", "Exception", "Expired":
+		return models.ShipmentTrackingStatusException
+	default:
+		// Pending、InfoReceived
+		return models.ShipmentTrackingStatusPending
+	}
+}
+
+func (p *afterShipProvider) request(ctx context.Context, method, target string, payload interface{}) (*afterShipResponse, error) {
+	key := strings.TrimSpace(p.apiKey())
+	if key == "" {
+		return nil, errors.New("aftership api key is not configured")
+	}
+	var resp afterShipResponse
+	if err := requestShipmentTrackingJSON(ctx, p.client, method, target, map[string]string{"as-api-key": key}, payload, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (p *afterShipProvider) Register(ctx context.Context, trackingNo string) error {
+	payload := map[string]interface{}{"tracking": map[string]string{"tracking_number": trackingNo}}
+	resp, err := p.request(ctx, http.MethodPost, p.baseURL+"/trackings", payload)
+	if err != nil {
+		return err
+	}
+	if resp.Meta.Code >= 200 && resp.Meta.Code < 300 || resp.Meta.Code == afterShipTrackingExistsCode {
+		return nil
+	}
+	return fmt.Errorf("aftership error %d: %s", resp.Meta.Code, resp.Meta.Message)
+}
+
+func (p *afterShipProvider) Fetch(ctx context.Context, trackingNo string) (*ShipmentTrackingUpdate, error) {
+	query := url.Values{}
+	query.Set("keyword", trackingNo)
+	resp, err := p.request(ctx, http.MethodGet, p.baseURL+"/trackings?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Meta.Code < 200 || resp.Meta.Code >= 300 {
+		return nil, fmt.Errorf("aftership error %d: %s", resp.Meta.Code, resp.Meta.Message)
+	}
+	// keyword 为模糊匹配，只取运单号完全一致的记录
+	for _, tracking := range resp.Data.Trackings {
+		if strings.EqualFold(strings.TrimSpace(tracking.TrackingNumber), trackingNo) {
+			return p.convert(tracking), nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *afterShipProvider) convert(tracking afterShipTracking) *ShipmentTrackingUpdate {
+	update := &ShipmentTrackingUpdate{
+		TrackingNo: strings.TrimSpace(tracking.TrackingNumber),
+		Carrier:    strings.TrimSpace(tracking.Slug),
+		Status:     mapAfterShipTag(tracking.Tag),
+	}
+	for _, checkpoint := range tracking.Checkpoints {
+		location := strings.TrimSpace(checkpoint.Location)
+		if location == "" {
+			parts := make([]string, 0, 2)
+			for _, part := range []string{checkpoint.City, checkpoint.CountryName} {
+				if part = strings.TrimSpace(part); part != "" {
+					parts = append(parts, part)
+				}
+			}
+			location = strings.Join(parts, ", ")
+		}
+		update.Events = append(update.Events, models.ShipmentTrackingEvent{
+			Time:        parseShipmentTrackingTime(checkpoint.CheckpointTime),
+			Status:      string(mapAfterShipTag(checkpoint.Tag)),
+			Description: strings.TrimSpace(checkpoint.Message),
+			Location:    location,
+		})
+	}
+	update.Events = sortShipmentTrackingEvents(update.Events)
+	if deliveredAt := parseShipmentTrackingTime(tracking.ShipmentDeliveryDate); update.Status == models.ShipmentTrackingStatusDelivered && !deliveredAt.IsZero() {
+		update.DeliveredAt = &deliveredAt
+	} else {
+		update.DeliveredAt = shipmentTrackingDeliveredAt(update.Status, update.Events)
+	}
+	return update
+}
+
+// ParseWebhook AfterShip 推送签名为 Base64(HMAC-SHA256(原始报文, Webhook Secret))
+func (p *afterShipProvider) ParseWebhook(header http.Header, body []byte) ([]ShipmentTrackingUpdate, error) {
+	secret := strings.TrimSpace(p.webhookSecret())
+	sign := strings.TrimSpace(header.Get("aftership-hmac-sha256"))
+	if secret == "" || sign == "" {
+		return nil, ErrShipmentTrackingSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	if !hmac.Equal([]byte(base64.StdEncoding.EncodeToString(mac.Sum(nil))), []byte(sign)) {
+		return nil, ErrShipmentTrackingSignature
+	}
+
+	var payload struct {
+		Msg *afterShipTracking `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Msg == nil || strings.TrimSpace(payload.Msg.TrackingNumber) == "" {
+		return nil, nil
+	}
+	return []ShipmentTrackingUpdate{*p.convert(*payload.Msg)}, nil
+}
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/demomode"
+	"auralogic/internal/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultShipmentTrackingPollMinutes = 360
+	shipmentTrackingRequestTimeout     = 15 * time.Second
+	shipmentTrackingCheckInterval      = 15 * time.Minute
+	shipmentTrackingBatchSize          = 50
+)
+
+// ErrShipmentTrackingDisabled 未启用物流轨迹同步或服务商不匹配
+var ErrShipmentTrackingDisabled = errors.New("shipment tracking is disabled")
+
+// ShipmentTrackingService 物流轨迹同步：发货后向服务商注册运单号，通过轮询与 Webhook 更新轨迹，
+// 签收满配置天数后自动完成订单
+type ShipmentTrackingService struct {
+	db           *gorm.DB
+	cfg          *config.Config
+	orderService *OrderService
+
+	mu        sync.Mutex
+	providers map[string]ShipmentTrackingProvider
+
+	// now 可在测试中替换
+	now func() time.Time
+
+	lifecycleMu sync.Mutex
+	running     bool
+	scheduler   *BackgroundScheduler
+}
+
+func NewShipmentTrackingService(db *gorm.DB, cfg *config.Config, orderService *OrderService) *ShipmentTrackingService {
+	client := &http.Client{Timeout: shipmentTrackingRequestTimeout, Transport: demomode.Transport(nil)}
+	return &ShipmentTrackingService{
+		db:           db,
+		cfg:          cfg,
+		orderService: orderService,
+		providers: map[string]ShipmentTrackingProvider{
+			ShipmentTrackingProvider17Track: &seventeenTrackProvider{
+				client:  client,
+				baseURL: "https://api.17track.net/track/v2.2",
+				apiKey:  func() string { return cfg.ShipmentTracking.APIKey },
+			},
+			ShipmentTrackingProviderAfterShip: &afterShipProvider{
+				client:        client,
+				baseURL:       "https://api.aftership.com/v4",
+				apiKey:        func() string { return cfg.ShipmentTracking.APIKey },
+				webhookSecret: func() string { return cfg.ShipmentTracking.WebhookSecret },
+			},
+		},
+		now: models.NowFunc,
+	}
+}
+
+// RegisterProvider 注册或替换轨迹服务商
+func (s *ShipmentTrackingService) RegisterProvider(provider ShipmentTrackingProvider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providers[provider.Name()] = provider
+}
+
+// IsValidShipmentTrackingProvider 是否为支持的轨迹服务商
+func IsValidShipmentTrackingProvider(name string) bool {
+	return name == ShipmentTrackingProvider17Track || name == ShipmentTrackingProviderAfterShip
+}
+
+// Enabled 是否启用物流轨迹同步
+func (s *ShipmentTrackingService) Enabled() bool {
+	return s != nil && s.cfg != nil && s.cfg.ShipmentTracking.Enabled
+}
+
+func (s *ShipmentTrackingService) providerName() string {
+	name := strings.TrimSpace(s.cfg.ShipmentTracking.Provider)
+	if name == "" {
+		name = ShipmentTrackingProvider17Track
+	}
+	return name
+}
+
+func (s *ShipmentTrackingService) provider() (ShipmentTrackingProvider, error) {
+	if !s.Enabled() {
+		return nil, ErrShipmentTrackingDisabled
+	}
+	name := s.providerName()
+	s.mu.Lock()
+	provider, ok := s.providers[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown shipment tracking provider %q", name)
+	}
+	return provider, nil
+}
+
+func (s *ShipmentTrackingService) pollInterval() time.Duration {
+	minutes := s.cfg.ShipmentTracking.PollIntervalMinutes
+	if minutes <= 0 {
+		minutes = defaultShipmentTrackingPollMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// ObserveHook 订单发货后异步注册运单号，不阻塞 Hook 分发
+func (s *ShipmentTrackingService) ObserveHook(hook string, payload map[string]interface{}) {
+	if hook != "order.status.changed.after" || !s.Enabled() {
+		return
+	}
+	if fmt.Sprint(payload["status_after"]) != string(models.OrderStatusShipped) {
+		return
+	}
+	orderID, ok := orderAutomationPayloadOrderID(payload["order_id"])
+	if !ok {
+		return
+	}
+	go func() {
+		defer recoverBackgroundServicePanic("shipment-tracking-register")
+		if _, err := s.TrackOrder(orderID); err != nil && !errors.Is(err, ErrShipmentTrackingDisabled) {
+			log.Printf("[ShipmentTracking] Failed to register order %d: %v", orderID, err)
+		}
+	}()
+}
+
+// GetOrderTracking 订单当前的物流轨迹，未追踪时返回 nil
+func (s *ShipmentTrackingService) GetOrderTracking(orderID uint) (*models.ShipmentTracking, error) {
+	var trackings []models.ShipmentTracking
+	if err := s.db.Where("order_id = ?", orderID).Limit(1).Find(&trackings).Error; err != nil {
+		return nil, err
+	}
+	if len(trackings) == 0 {
+		return nil, nil
+	}
+	return &trackings[0], nil
+}
+
+// TrackOrder 确保订单当前运单号已在服务商注册；运单号变更时重置轨迹。订单没有运单号时返回 nil
+func (s *ShipmentTrackingService) TrackOrder(orderID uint) (*models.ShipmentTracking, error) {
+	provider, err := s.provider()
+	if err != nil {
+		return nil, err
+	}
+	var order models.Order
+	if err := s.db.Select("id", "order_no", "tracking_no", "status").First(&order, orderID).Error; err != nil {
+		return nil, err
+	}
+	trackingNo := strings.TrimSpace(order.TrackingNo)
+	if trackingNo == "" {
+		return nil, nil
+	}
+
+	tracking, err := s.GetOrderTracking(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tracking == nil {
+		tracking = &models.ShipmentTracking{OrderID: order.ID}
+	}
+	if tracking.ID != 0 && tracking.Registered && tracking.TrackingNo == trackingNo && tracking.Provider == provider.Name() {
+		return tracking, nil
+	}
+	if tracking.TrackingNo != trackingNo || tracking.Provider != provider.Name() {
+		tracking.TrackingNo = trackingNo
+		tracking.Provider = provider.Name()
+		tracking.Carrier = ""
+		tracking.Status = models.ShipmentTrackingStatusPending
+		tracking.Events = nil
+		tracking.Registered = false
+		tracking.DeliveredAt = nil
+		tracking.LastSyncedAt = nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shipmentTrackingRequestTimeout)
+	registerErr := provider.Register(ctx, trackingNo)
+	cancel()
+	if registerErr != nil {
+		tracking.LastError = registerErr.Error()
+	} else {
+		tracking.Registered = true
+		tracking.LastError = ""
+	}
+	// 注册后服务商需要时间抓取轨迹，下次轮询时再拉取；注册失败同样按轮询间隔重试
+	next := s.now().Add(s.pollInterval())
+	tracking.NextSyncAt = &next
+	if err := s.db.Save(tracking).Error; err != nil {
+		return nil, err
+	}
+	return tracking, nil
+}
+
+// SyncOrder 立即注册并拉取订单轨迹（管理端手动刷新）
+func (s *ShipmentTrackingService) SyncOrder(orderID uint) (*models.ShipmentTracking, error) {
+	tracking, err := s.TrackOrder(orderID)
+	if err != nil || tracking == nil {
+		return tracking, err
+	}
+	if err := s.refresh(tracking); err != nil {
+		return nil, err
+	}
+	return tracking, nil
+}
+
+// refresh 向服务商拉取轨迹；失败时记录错误并按轮询间隔重试
+func (s *ShipmentTrackingService) refresh(tracking *models.ShipmentTracking) error {
+	provider, err := s.provider()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shipmentTrackingRequestTimeout)
+	update, fetchErr := provider.Fetch(ctx, tracking.TrackingNo)
+	cancel()
+
+	now := s.now()
+	next := now.Add(s.pollInterval())
+	tracking.LastSyncedAt = &now
+	tracking.NextSyncAt = &next
+	if fetchErr != nil {
+		tracking.LastError = fetchErr.Error()
+	} else {
+		tracking.LastError = ""
+		if update != nil {
+			applyShipmentTrackingUpdate(tracking, update, now)
+		}
+	}
+	return s.db.Save(tracking).Error
+}
+
+// applyShipmentTrackingUpdate 服务商每次返回完整轨迹，直接覆盖
+func applyShipmentTrackingUpdate(tracking *models.ShipmentTracking, update *ShipmentTrackingUpdate, now time.Time) {
+	if update.Carrier != "" {
+		tracking.Carrier = update.Carrier
+	}
+	if len(update.Events) > 0 || len(tracking.Events) == 0 {
+		tracking.Events = update.Events
+	}
+	tracking.Status = update.Status
+	if !tracking.IsFinal() {
+		tracking.DeliveredAt = nil
+		return
+	}
+	tracking.NextSyncAt = nil
+	// 服务商未给出签收时间时沿用首次发现签收的时间，避免每次推送都推迟自动完成
+	if update.DeliveredAt != nil {
+		tracking.DeliveredAt = update.DeliveredAt
+	} else if tracking.DeliveredAt == nil {
+		tracking.DeliveredAt = &now
+	}
+}
+
+// HandleWebhook 处理服务商推送，返回更新的运单数；providerName 须与当前配置的服务商一致
+func (s *ShipmentTrackingService) HandleWebhook(providerName string, header http.Header, body []byte) (int, error) {
+	provider, err := s.provider()
+	if err != nil {
+		return 0, err
+	}
+	if provider.Name() != strings.ToLower(strings.TrimSpace(providerName)) {
+		return 0, ErrShipmentTrackingDisabled
+	}
+	updates, err := provider.ParseWebhook(header, body)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	now := s.now()
+	for i := range updates {
+		update := &updates[i]
+		var trackings []models.ShipmentTracking
+		if err := s.db.Where("provider = ? AND tracking_no = ?", provider.Name(), update.TrackingNo).Find(&trackings).Error; err != nil {
+			return updated, err
+		}
+		for j := range trackings {
+			tracking := &trackings[j]
+			tracking.Registered = true
+			tracking.LastError = ""
+			tracking.LastSyncedAt = &now
+			applyShipmentTrackingUpdate(tracking, update, now)
+			if err := s.db.Save(tracking).Error; err != nil {
+				return updated, err
+			}
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// Start 启动后台任务：补注册、轮询未签收运单并自动完成签收已满天数的订单
+func (s *ShipmentTrackingService) Start() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	if s.running {
+		return
+	}
+
+	scheduler := backgroundSchedulerForService()
+	if err := scheduler.Register(BackgroundJob{
+		Name:       "shipment_tracking_sync",
+		Interval:   shipmentTrackingCheckInterval,
+		RunOnStart: true,
+		LockTTL:    shipmentTrackingCheckInterval,
+		Jitter:     shipmentTrackingCheckInterval / 10,
+		Run: func() error {
+			s.RunSync()
+			return nil
+		},
+	}); err != nil {
+		log.Printf("[ShipmentTracking] Failed to register background job: %v", err)
+		return
+	}
+	s.scheduler = scheduler
+	s.running = true
+}
+
+// Stop 停止后台任务
+func (s *ShipmentTrackingService) Stop() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	if !s.running {
+		return
+	}
+	s.running = false
+	s.scheduler.Remove("shipment_tracking_sync")
+	s.scheduler = nil
+}
+
+// RunSync 执行一轮同步，每次执行时读取最新配置，支持热更新
+func (s *ShipmentTrackingService) RunSync() {
+	if !s.Enabled() {
+		return
+	}
+	s.registerUntracked()
+	s.pollDue()
+	s.autoCompleteDelivered()
+}
+
+func shippedOrderIDs(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Order{}).Select("id").Where("status = ?", models.OrderStatusShipped)
+}
+
+// registerUntracked 补注册启用前已发货或通过导入等途径发货、尚未追踪的订单
+func (s *ShipmentTrackingService) registerUntracked() {
+	var orderIDs []uint
+	if err := shippedOrderIDs(s.db).
+		Where("tracking_no <> ''").
+		Where("id NOT IN (?)", s.db.Model(&models.ShipmentTracking{}).Select("order_id")).
+		Order("id ASC").Limit(shipmentTrackingBatchSize).
+		Pluck("id", &orderIDs).Error; err != nil {
+		log.Printf("[ShipmentTracking] Error querying untracked orders: %v", err)
+		return
+	}
+	for _, orderID := range orderIDs {
+		if _, err := s.TrackOrder(orderID); err != nil {
+			log.Printf("[ShipmentTracking] Failed to register order %d: %v", orderID, err)
+		}
+	}
+}
+
+// pollDue 轮询已到同步时间且未签收的运单
+func (s *ShipmentTrackingService) pollDue() {
+	var trackings []models.ShipmentTracking
+	if err := s.db.Where("status <> ? AND next_sync_at IS NOT NULL AND next_sync_at <= ?", models.ShipmentTrackingStatusDelivered, s.now()).
+		Where("order_id IN (?)", shippedOrderIDs(s.db)).
+		Order("next_sync_at ASC").Limit(shipmentTrackingBatchSize).
+		Find(&trackings).Error; err != nil {
+		log.Printf("[ShipmentTracking] Error querying due trackings: %v", err)
+		return
+	}
+	for i := range trackings {
+		// 运单号可能已被修改，先确认注册的是当前运单号
+		tracking, err := s.TrackOrder(trackings[i].OrderID)
+		if err != nil || tracking == nil {
+			if err != nil {
+				log.Printf("[ShipmentTracking] Failed to register order %d: %v", trackings[i].OrderID, err)
+			}
+			continue
+		}
+		if !tracking.Registered {
+			continue
+		}
+		if err := s.refresh(tracking); err != nil {
+			log.Printf("[ShipmentTracking] Failed to refresh tracking %s: %v", tracking.TrackingNo, err)
+		}
+	}
+}
+
+// autoCompleteDelivered 签收满 auto_complete_days 天仍处于已发货状态的订单自动完成
+func (s *ShipmentTrackingService) autoCompleteDelivered() {
+	days := s.cfg.ShipmentTracking.AutoCompleteDays
+	if days <= 0 || s.orderService == nil {
+		return
+	}
+	cutoff := s.now().Add(-time.Duration(days) * 24 * time.Hour)
+	var trackings []models.ShipmentTracking
+	if err := s.db.Where("status = ? AND delivered_at IS NOT NULL AND delivered_at <= ?", models.ShipmentTrackingStatusDelivered, cutoff).
+		Where("order_id IN (?)", shippedOrderIDs(s.db)).
+		Order("delivered_at ASC").Limit(shipmentTrackingBatchSize).
+		Find(&trackings).Error; err != nil {
+		log.Printf("[ShipmentTracking] Error querying delivered trackings: %v", err)
+		return
+	}
+
+	completed := 0
+	for _, tracking := range trackings {
+		remark := fmt.Sprintf("System auto-completed: shipment %s delivered on %s, %d days ago",
+			tracking.TrackingNo, tracking.DeliveredAt.Format("2006-01-02"), days)
+		err := s.orderService.AutoCompleteOrder(tracking.OrderID, remark, map[string]interface{}{
+			"source":             "shipment_tracking",
+			"trigger_action":     "order.auto_complete",
+			"tracking_no":        tracking.TrackingNo,
+			"delivered_at":       tracking.DeliveredAt.Format(time.RFC3339),
+			"auto_complete_days": days,
+		})
+		if err != nil {
+			log.Printf("[ShipmentTracking] Failed to auto-complete order %d: %v", tracking.OrderID, err)
+			continue
+		}
+		completed++
+	}
+	if completed > 0 {
+		logger.LogSystemOperation(s.db, "shipment_tracking_auto_complete", "system", nil, map[string]interface{}{
+			"completed_count":    completed,
+			"auto_complete_days": days,
+			"cutoff_time":        cutoff.Format(time.RFC3339),
+		})
+	}
+}
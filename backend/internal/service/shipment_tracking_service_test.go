@@ -0,0 +1,182 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func TestShipmentTrackingSyncsAndAutoCompletesDeliveredOrder(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Order{}, &models.OrderNote{}, &models.ShipmentTracking{}, &models.OperationLog{})
+	cfg := &config.Config{ShipmentTracking: config.ShipmentTrackingConfig{
+		Enabled:             true,
+		Provider:            ShipmentTrackingProvider17Track,
+		APIKey:              "17-key",
+		PollIntervalMinutes: 60,
+		AutoCompleteDays:    3,
+	}}
+
+	registered := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("17token") != "17-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body []map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/register":
+			registered++
+			// 第二次注册返回"已注册"，应视为成功
+			if registered > 1 {
+				_, _ = w.Write([]byte(`{"code":0,"data":{"accepted":[],"rejected":[{"number":"` + body[0]["number"] + `","error":{"code":-18019901,"message":"already registered"}}]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"code":0,"data":{"accepted":[{"number":"` + body[0]["number"] + `"}],"rejected":[]}}`))
+		case "/gettrackinfo":
+			_, _ = w.Write([]byte(`{"code":0,"data":{"accepted":[{"number":"` + body[0]["number"] + `","carrier":3011,"track_info":{
+				"latest_status":{"status":"Delivered"},
+				"tracking":{"providers":[{"provider":{"name":"China Post"},"events":[
+					{"time_utc":"2026-10-01T08:00:00Z","description":"Accepted","location":"Shenzhen","stage":"InfoReceived"},
+					{"time_utc":"2026-10-03T09:30:00Z","description":"Delivered to recipient","location":"Berlin","stage":"Delivered"},
+					{"time_utc":"2026-10-02T12:00:00Z","description":"Departed facility","stage":"InTransit"}
+				]}]}}}],"rejected":[]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	orderService := newConcurrentOrderService(db, cfg, nil)
+	svc := NewShipmentTrackingService(db, cfg, orderService)
+	svc.RegisterProvider(&seventeenTrackProvider{client: server.Client(), baseURL: server.URL, apiKey: func() string { return cfg.ShipmentTracking.APIKey }})
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	order := &models.Order{OrderNo: "T-TRACK", Status: models.OrderStatusShipped, TrackingNo: "RR123456789CN"}
+	untracked := &models.Order{OrderNo: "T-NO-TRACK", Status: models.OrderStatusShipped}
+	for _, o := range []*models.Order{order, untracked} {
+		if err := db.Create(o).Error; err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+	}
+
+	// 首轮只补注册，未到轮询时间不拉取
+	svc.RunSync()
+	tracking, err := svc.GetOrderTracking(order.ID)
+	if err != nil || tracking == nil || !tracking.Registered || tracking.Status != models.ShipmentTrackingStatusPending {
+		t.Fatalf("expected registered pending tracking, got %+v err=%v", tracking, err)
+	}
+	if other, _ := svc.GetOrderTracking(untracked.ID); other != nil {
+		t.Fatalf("orders without tracking number should not be tracked, got %+v", other)
+	}
+
+	now = now.Add(2 * time.Hour)
+	svc.RunSync()
+	tracking, _ = svc.GetOrderTracking(order.ID)
+	if tracking.Status != models.ShipmentTrackingStatusDelivered || tracking.Carrier != "China Post" || len(tracking.Events) != 3 {
+		t.Fatalf("expected delivered tracking with 3 events, got %+v", tracking)
+	}
+	if tracking.Events[0].Description != "Delivered to recipient" || tracking.NextSyncAt != nil {
+		t.Fatalf("events should be newest first and polling should stop, got %+v", tracking)
+	}
+	if tracking.DeliveredAt == nil || !tracking.DeliveredAt.Equal(time.Date(2026, 10, 3, 9, 30, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected delivered_at %v", tracking.DeliveredAt)
+	}
+
+	// 签收未满 3 天不自动完成
+	now = time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	svc.RunSync()
+	var reloaded models.Order
+	if err := db.First(&reloaded, order.ID).Error; err != nil || reloaded.Status != models.OrderStatusShipped {
+		t.Fatalf("order should still be shipped, got %s err=%v", reloaded.Status, err)
+	}
+
+	now = time.Date(2026, 10, 6, 10, 0, 0, 0, time.UTC)
+	svc.RunSync()
+	if err := db.First(&reloaded, order.ID).Error; err != nil {
+		t.Fatalf("reload order: %v", err)
+	}
+	if reloaded.Status != models.OrderStatusCompleted || reloaded.CompletedAt == nil || reloaded.CompletedBy != nil {
+		t.Fatalf("expected system auto-completed order, got status=%s completed_by=%v", reloaded.Status, reloaded.CompletedBy)
+	}
+	var note models.OrderNote
+	if err := db.Where("order_id = ? AND source = ?", order.ID, models.OrderNoteSourceAutoComplete).First(&note).Error; err != nil || note.AuthorID != nil {
+		t.Fatalf("expected auto_complete system note, got %+v err=%v", note, err)
+	}
+
+	// 运单号变更后重新注册并清空旧轨迹
+	if err := db.Model(&models.Order{}).Where("id = ?", order.ID).Updates(map[string]interface{}{"status": models.OrderStatusShipped, "tracking_no": "LX000000001CN"}).Error; err != nil {
+		t.Fatalf("update tracking no: %v", err)
+	}
+	tracking, err = svc.TrackOrder(order.ID)
+	if err != nil || tracking.TrackingNo != "LX000000001CN" || len(tracking.Events) != 0 || tracking.DeliveredAt != nil || !tracking.Registered {
+		t.Fatalf("expected reset tracking for new number, got %+v err=%v", tracking, err)
+	}
+}
+
+func TestShipmentTrackingWebhookVerifiesSignature(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Order{}, &models.ShipmentTracking{})
+	cfg := &config.Config{ShipmentTracking: config.ShipmentTrackingConfig{Enabled: true, Provider: ShipmentTrackingProvider17Track, APIKey: "17-key"}}
+	svc := NewShipmentTrackingService(db, cfg, nil)
+
+	tracking := &models.ShipmentTracking{OrderID: 1, TrackingNo: "RR123456789CN", Provider: ShipmentTrackingProvider17Track, Status: models.ShipmentTrackingStatusPending}
+	if err := db.Create(tracking).Error; err != nil {
+		t.Fatalf("create tracking: %v", err)
+	}
+
+	body := []byte(`{"event":"TRACKING_UPDATED","data":{"number":"RR123456789CN","carrier":3011,"track_info":{"latest_status":{"status":"OutForDelivery"},"tracking":{"providers":[{"provider":{"name":"China Post"},"events":[{"time_utc":"2026-10-03T07:00:00Z","description":"Out for delivery","stage":"OutForDelivery"}]}]}}}}`)
+	header := http.Header{}
+	header.Set("sign", "deadbeef")
+	if _, err := svc.HandleWebhook(ShipmentTrackingProvider17Track, header, body); !errors.Is(err, ErrShipmentTrackingSignature) {
+		t.Fatalf("expected signature error, got %v", err)
+	}
+	if _, err := svc.HandleWebhook(ShipmentTrackingProviderAfterShip, header, body); !errors.Is(err, ErrShipmentTrackingDisabled) {
+		t.Fatalf("webhook for a provider that is not configured should be rejected, got %v", err)
+	}
+
+	sum := sha256.Sum256([]byte(string(body) + "/17-key"))
+	header.Set("sign", hex.EncodeToString(sum[:]))
+	updated, err := svc.HandleWebhook(ShipmentTrackingProvider17Track, header, body)
+	if err != nil || updated != 1 {
+		t.Fatalf("expected 1 tracking updated, got %d err=%v", updated, err)
+	}
+	if err := db.First(tracking, tracking.ID).Error; err != nil {
+		t.Fatalf("reload tracking: %v", err)
+	}
+	if tracking.Status != models.ShipmentTrackingStatusOutForDelivery || tracking.Carrier != "China Post" || len(tracking.Events) != 1 {
+		t.Fatalf("unexpected tracking after webhook: %+v", tracking)
+	}
+
+	// AfterShip 使用 Webhook 密钥的 HMAC-SHA256（Base64）
+	aftership := &afterShipProvider{apiKey: func() string { return "" }, webhookSecret: func() string { return "as-secret" }}
+	asBody := []byte(`{"event":"tracking_update","msg":{"tracking_number":"1Z999","slug":"ups","tag":"Delivered","shipment_delivery_date":"2026-10-04T10:00:00+02:00","checkpoints":[{"checkpoint_time":"2026-10-04T10:00:00+02:00","message":"Delivered","city":"Paris","country_name":"France","tag":"Delivered"}]}}`)
+	mac := hmac.New(sha256.New, []byte("as-secret"))
+	_, _ = mac.Write(asBody)
+	asHeader := http.Header{}
+	asHeader.Set("aftership-hmac-sha256", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	updates, err := aftership.ParseWebhook(asHeader, asBody)
+	if err != nil || len(updates) != 1 {
+		t.Fatalf("parse aftership webhook: updates=%v err=%v", updates, err)
+	}
+	update := updates[0]
+	if update.Status != models.ShipmentTrackingStatusDelivered || update.Carrier != "ups" || update.DeliveredAt == nil ||
+		!update.DeliveredAt.Equal(time.Date(2026, 10, 4, 8, 0, 0, 0, time.UTC)) || !strings.Contains(update.Events[0].Location, "Paris") {
+		t.Fatalf("unexpected aftership update: %+v", update)
+	}
+	asHeader.Set("aftership-hmac-sha256", "invalid")
+	if _, err := aftership.ParseWebhook(asHeader, asBody); !errors.Is(err, ErrShipmentTrackingSignature) {
+		t.Fatalf("expected aftership signature error, got %v", err)
+	}
+}
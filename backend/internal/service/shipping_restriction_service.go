@@ -0,0 +1,180 @@
+package service
+
+import (
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/constants"
+	"auralogic/internal/repository"
+	"gorm.io/gorm"
+)
+
+// 配送限制拦截来源
+const (
+	ShippingRestrictionSourceForm  = "form"
+	ShippingRestrictionSourceAdmin = "admin"
+)
+
+// ShippingRestrictionService 商品配送国家限制（允许列表/禁运列表）与拦截记录
+type ShippingRestrictionService struct {
+	db          *gorm.DB
+	productRepo *repository.ProductRepository
+}
+
+func NewShippingRestrictionService(db *gorm.DB) *ShippingRestrictionService {
+	return &ShippingRestrictionService{db: db, productRepo: repository.NewProductRepository(db)}
+}
+
+// ShippingRestrictionAttempt 被拦截尝试的上下文
+type ShippingRestrictionAttempt struct {
+	Source  string
+	OrderNo string
+	UserID  *uint
+}
+
+// ShippingRestrictionBlockFilter 拦截记录筛选
+type ShippingRestrictionBlockFilter struct {
+	Country string
+	SKU     string
+	Source  string
+}
+
+// ProductShipsTo 判断商品是否可寄往指定国家；禁运列表优先，允许列表为空表示不限制
+func ProductShipsTo(product *models.Product, country string) bool {
+	if product == nil {
+		return true
+	}
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		return true
+	}
+	for _, code := range product.ShippingBlockedCountries {
+		if strings.EqualFold(code, country) {
+			return false
+		}
+	}
+	if len(product.ShippingAllowedCountries) == 0 {
+		return true
+	}
+	for _, code := range product.ShippingAllowedCountries {
+		if strings.EqualFold(code, country) {
+			return true
+		}
+	}
+	return false
+}
+
+// Enforce 校验订单商品能否寄往 country；不可配送时记录拦截并返回 order.shippingRestricted。
+// 系统中不存在的 SKU（如外部平台商品）不受限制
+func (s *ShippingRestrictionService) Enforce(items []models.OrderItem, country string, attempt ShippingRestrictionAttempt) error {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" || len(items) == 0 {
+		return nil
+	}
+	skus := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if sku := strings.TrimSpace(item.SKU); sku != "" && !seen[sku] {
+			seen[sku] = true
+			skus = append(skus, sku)
+		}
+	}
+	if len(skus) == 0 {
+		return nil
+	}
+	products, err := s.productRepo.FindBySKUs(skus)
+	if err != nil {
+		return err
+	}
+
+	var blocked *models.Product
+	for _, sku := range skus {
+		product := products[sku]
+		if product == nil || ProductShipsTo(product, country) {
+			continue
+		}
+		if blocked == nil {
+			blocked = product
+		}
+		// 记录失败不影响拦截结果
+		_ = s.recordBlock(product, country, attempt)
+	}
+	if blocked == nil {
+		return nil
+	}
+	return bizerr.Newf("order.shippingRestricted", "%s cannot be shipped to %s", blocked.Name, constants.GetCountryNameEN(country)).
+		WithParams(map[string]interface{}{"sku": blocked.SKU, "name": blocked.Name, "country": country})
+}
+
+func (s *ShippingRestrictionService) recordBlock(product *models.Product, country string, attempt ShippingRestrictionAttempt) error {
+	now := models.NowFunc()
+	source := attempt.Source
+	if source == "" {
+		source = ShippingRestrictionSourceForm
+	}
+	increment := func() (bool, error) {
+		result := s.db.Model(&models.ShippingRestrictionBlock{}).
+			Where("product_id = ? AND country = ? AND source = ?", product.ID, country, source).
+			Updates(map[string]interface{}{
+				"count":         gorm.Expr("count + 1"),
+				"sku":           product.SKU,
+				"product_name":  product.Name,
+				"last_order_no": attempt.OrderNo,
+				"last_user_id":  attempt.UserID,
+				"last_seen_at":  now,
+			})
+		return result.RowsAffected > 0, result.Error
+	}
+
+	if updated, err := increment(); err != nil || updated {
+		return err
+	}
+	block := &models.ShippingRestrictionBlock{
+		ProductID:   product.ID,
+		SKU:         product.SKU,
+		ProductName: product.Name,
+		Country:     country,
+		Source:      source,
+		Count:       1,
+		LastOrderNo: attempt.OrderNo,
+		LastUserID:  attempt.UserID,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+	if err := s.db.Create(block).Error; err != nil {
+		// 并发拦截同一商品时唯一索引冲突，改为累加
+		if updated, retryErr := increment(); retryErr == nil && updated {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// ListBlocks 按最近拦截时间倒序列出拦截记录
+func (s *ShippingRestrictionService) ListBlocks(filter ShippingRestrictionBlockFilter, page, limit int) ([]models.ShippingRestrictionBlock, int64, error) {
+	query := s.db.Model(&models.ShippingRestrictionBlock{})
+	if filter.Country != "" {
+		query = query.Where("country = ?", strings.ToUpper(filter.Country))
+	}
+	if filter.SKU != "" {
+		query = query.Where("sku LIKE ?", "%"+filter.SKU+"%")
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var blocks []models.ShippingRestrictionBlock
+	err := query.Order("last_seen_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&blocks).Error
+	return blocks, total, err
+}
+
+// ClearBlocks 清空拦截记录
+func (s *ShippingRestrictionService) ClearBlocks() (int64, error) {
+	result := s.db.Where("1 = 1").Delete(&models.ShippingRestrictionBlock{})
+	return result.RowsAffected, result.Error
+}
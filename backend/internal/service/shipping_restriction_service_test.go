@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+)
+
+func TestProductShipsTo(t *testing.T) {
+	product := &models.Product{ShippingAllowedCountries: []string{"US", "CA"}, ShippingBlockedCountries: []string{"CA"}}
+	cases := map[string]bool{"US": true, "us": true, "CA": false, "CN": false, "": true}
+	for country, want := range cases {
+		if got := ProductShipsTo(product, country); got != want {
+			t.Errorf("ProductShipsTo(%q) = %v, want %v", country, got, want)
+		}
+	}
+	if !ProductShipsTo(&models.Product{}, "RU") {
+		t.Errorf("product without restrictions should ship anywhere")
+	}
+}
+
+func TestShippingRestrictionEnforceRecordsBlocks(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Product{}, &models.ShippingRestrictionBlock{})
+	svc := NewShippingRestrictionService(db)
+
+	restricted := &models.Product{SKU: "BATTERY-1", Name: "Battery", ShippingBlockedCountries: []string{"JP"}}
+	free := &models.Product{SKU: "SHIRT-1", Name: "Shirt"}
+	for _, product := range []*models.Product{restricted, free} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("create product failed: %v", err)
+		}
+	}
+	items := []models.OrderItem{{SKU: "SHIRT-1"}, {SKU: "BATTERY-1"}, {SKU: "EXTERNAL-1"}}
+
+	if err := svc.Enforce(items, "US", ShippingRestrictionAttempt{Source: ShippingRestrictionSourceForm}); err != nil {
+		t.Fatalf("expected US to be allowed, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		err := svc.Enforce(items, "jp", ShippingRestrictionAttempt{Source: ShippingRestrictionSourceForm, OrderNo: "ORD-1"})
+		var bizErr *bizerr.Error
+		if !errors.As(err, &bizErr) || bizErr.Key != "order.shippingRestricted" || bizErr.Params["sku"] != "BATTERY-1" {
+			t.Fatalf("expected shippingRestricted for BATTERY-1, got %v", err)
+		}
+	}
+	if err := svc.Enforce(items, "JP", ShippingRestrictionAttempt{Source: ShippingRestrictionSourceAdmin}); err == nil {
+		t.Fatalf("expected admin creation to be blocked too")
+	}
+
+	blocks, total, err := svc.ListBlocks(ShippingRestrictionBlockFilter{Country: "jp"}, 1, 20)
+	if err != nil || total != 2 {
+		t.Fatalf("expected 2 aggregated blocks, got %d, %v", total, err)
+	}
+	for _, block := range blocks {
+		if block.Source == ShippingRestrictionSourceForm && (block.Count != 2 || block.LastOrderNo != "ORD-1") {
+			t.Fatalf("unexpected form block aggregation: %+v", block)
+		}
+	}
+}
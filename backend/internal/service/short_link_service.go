@@ -0,0 +1,242 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	qrcode "github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+const (
+	shortLinkCodeLength   = 8
+	shortLinkCodeAlphabet = "23456789abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+	shortLinkMaxAttempts  = 5
+
+	MinShortLinkQRSize     = 128
+	MaxShortLinkQRSize     = 1024
+	DefaultShortLinkQRSize = 256
+)
+
+var (
+	ErrShortLinkNotFound = bizerr.New("shortLink.notFound", "Short link not found")
+	ErrShortLinkExpired  = bizerr.New("shortLink.expired", "Short link has expired")
+)
+
+// ShortLinkService 订单表单/付款短链接与二维码
+type ShortLinkService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewShortLinkService(db *gorm.DB, cfg *config.Config) *ShortLinkService {
+	return &ShortLinkService{db: db, cfg: cfg}
+}
+
+func (s *ShortLinkService) baseURL() string {
+	if s.cfg == nil {
+		return ""
+	}
+	return strings.TrimRight(strings.TrimSpace(s.cfg.App.URL), "/")
+}
+
+// ShortURL 短链完整地址
+func (s *ShortLinkService) ShortURL(link *models.ShortLink) string {
+	if link == nil {
+		return ""
+	}
+	return s.baseURL() + "/s/" + link.Code
+}
+
+// QRCodeURL 短链二维码图片地址
+func (s *ShortLinkService) QRCodeURL(link *models.ShortLink) string {
+	if link == nil {
+		return ""
+	}
+	return s.ShortURL(link) + "/qr"
+}
+
+func generateShortLinkCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(shortLinkCodeAlphabet)))
+	code := make([]byte, shortLinkCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = shortLinkCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// ensure 返回指向同一目标且仍有效的短链，不存在时创建；同订单同类型的旧短链会被吊销
+func (s *ShortLinkService) ensure(targetType, targetURL string, orderID *uint, expiresAt *time.Time, createdBy *uint) (*models.ShortLink, error) {
+	now := time.Now()
+	if expiresAt != nil && !now.Before(*expiresAt) {
+		return nil, ErrShortLinkExpired
+	}
+
+	var link *models.ShortLink
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.ShortLink
+		err := tx.Where("target_type = ? AND target_url = ? AND revoked = ?", targetType, targetURL, false).
+			Order("id DESC").First(&existing).Error
+		if err == nil && existing.IsUsable(now) {
+			// 目标有效期可能已延长（如表单 Token 刷新）
+			if !sameShortLinkExpiry(existing.ExpiresAt, expiresAt) {
+				existing.ExpiresAt = expiresAt
+				if err := tx.Model(&existing).Update("expires_at", expiresAt).Error; err != nil {
+					return err
+				}
+			}
+			link = &existing
+			return nil
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if orderID != nil {
+			if err := tx.Model(&models.ShortLink{}).
+				Where("order_id = ? AND target_type = ? AND revoked = ?", *orderID, targetType, false).
+				Update("revoked", true).Error; err != nil {
+				return err
+			}
+		}
+
+		for attempt := 0; attempt < shortLinkMaxAttempts; attempt++ {
+			code, err := generateShortLinkCode()
+			if err != nil {
+				return err
+			}
+			var count int64
+			if err := tx.Model(&models.ShortLink{}).Where("code = ?", code).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+			created := &models.ShortLink{
+				Code:       code,
+				TargetType: targetType,
+				TargetURL:  targetURL,
+				OrderID:    orderID,
+				ExpiresAt:  expiresAt,
+				CreatedBy:  createdBy,
+			}
+			if err := tx.Create(created).Error; err != nil {
+				return err
+			}
+			link = created
+			return nil
+		}
+		return fmt.Errorf("failed to allocate short link code")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func sameShortLinkExpiry(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// ForShippingForm 收货表单短链，有效期与表单 Token 一致
+func (s *ShortLinkService) ForShippingForm(order *models.Order, createdBy *uint) (*models.ShortLink, error) {
+	if order == nil || order.FormToken == nil || strings.TrimSpace(*order.FormToken) == "" {
+		return nil, bizerr.New("shortLink.formUnavailable", "Shipping form is not available for this order")
+	}
+	if order.Status != models.OrderStatusDraft && order.Status != models.OrderStatusNeedResubmit {
+		return nil, bizerr.New("shortLink.formUnavailable", "Shipping form is not available for this order")
+	}
+	baseURL := s.baseURL()
+	if baseURL == "" {
+		return nil, bizerr.New("shortLink.appURLRequired", "Site URL is not configured")
+	}
+	orderID := order.ID
+	return s.ensure(models.ShortLinkTypeShippingForm, baseURL+"/form/shipping?token="+*order.FormToken, &orderID, order.FormExpiresAt, createdBy)
+}
+
+// ForPayment 订单付款页短链，有效期与待付款订单自动取消时间一致
+func (s *ShortLinkService) ForPayment(order *models.Order, createdBy *uint) (*models.ShortLink, error) {
+	if order == nil || order.Status != models.OrderStatusPendingPayment {
+		return nil, bizerr.New("shortLink.paymentUnavailable", "Order is not awaiting payment")
+	}
+	baseURL := s.baseURL()
+	if baseURL == "" {
+		return nil, bizerr.New("shortLink.appURLRequired", "Site URL is not configured")
+	}
+	expiresAt := OrderPaymentDeadline(s.cfg, order)
+	orderID := order.ID
+	return s.ensure(models.ShortLinkTypePayment, baseURL+"/orders/"+order.OrderNo, &orderID, &expiresAt, createdBy)
+}
+
+// ListByOrder 订单下的短链（含已吊销/过期）
+func (s *ShortLinkService) ListByOrder(orderID uint) ([]models.ShortLink, error) {
+	var links []models.ShortLink
+	err := s.db.Where("order_id = ?", orderID).Order("id DESC").Find(&links).Error
+	return links, err
+}
+
+// Resolve 解析短码，过期或已吊销返回 ErrShortLinkExpired
+func (s *ShortLinkService) Resolve(code string) (*models.ShortLink, error) {
+	code = strings.TrimSpace(code)
+	if code == "" || len(code) > 16 {
+		return nil, ErrShortLinkNotFound
+	}
+	var link models.ShortLink
+	if err := s.db.Where("code = ?", code).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShortLinkNotFound
+		}
+		return nil, err
+	}
+	if !link.IsUsable(time.Now()) {
+		return &link, ErrShortLinkExpired
+	}
+	return &link, nil
+}
+
+// RecordClick 记录点击
+func (s *ShortLinkService) RecordClick(linkID uint, ip, userAgent, referer string) error {
+	now := time.Now()
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ShortLink{}).Where("id = ?", linkID).Updates(map[string]interface{}{
+			"click_count":     gorm.Expr("click_count + ?", 1),
+			"last_clicked_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ShortLinkClick{
+			ShortLinkID: linkID,
+			IP:          ip,
+			UserAgent:   userAgent,
+			Referer:     referer,
+		}).Error
+	})
+}
+
+// QRCodePNG 生成短链二维码 PNG
+func (s *ShortLinkService) QRCodePNG(link *models.ShortLink, size int) ([]byte, error) {
+	if size <= 0 {
+		size = DefaultShortLinkQRSize
+	}
+	if size < MinShortLinkQRSize {
+		size = MinShortLinkQRSize
+	}
+	if size > MaxShortLinkQRSize {
+		size = MaxShortLinkQRSize
+	}
+	return qrcode.Encode(s.ShortURL(link), qrcode.Medium, size)
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newShortLinkServiceForTest(t *testing.T) *ShortLinkService {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.ShortLink{}, &models.ShortLinkClick{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	cfg := &config.Config{}
+	cfg.App.URL = "https://shop.example.com/"
+	cfg.Order.AutoCancelHours = 24
+	return NewShortLinkService(db, cfg)
+}
+
+func TestShortLinkServiceShippingFormLifecycle(t *testing.T) {
+	svc := newShortLinkServiceForTest(t)
+
+	token := "11111111-2222-3333-4444-555555555555"
+	expiresAt := time.Now().Add(2 * time.Hour)
+	order := &models.Order{ID: 10, OrderNo: "ORD10", Status: models.OrderStatusDraft, FormToken: &token, FormExpiresAt: &expiresAt}
+
+	first, err := svc.ForShippingForm(order, nil)
+	if err != nil {
+		t.Fatalf("create shipping form link: %v", err)
+	}
+	if first.TargetURL != "https://shop.example.com/form/shipping?token="+token || len(first.Code) != shortLinkCodeLength {
+		t.Fatalf("unexpected link: %+v", first)
+	}
+	if svc.ShortURL(first) != "https://shop.example.com/s/"+first.Code {
+		t.Fatalf("unexpected short url: %s", svc.ShortURL(first))
+	}
+
+	again, err := svc.ForShippingForm(order, nil)
+	if err != nil || again.ID != first.ID {
+		t.Fatalf("expected existing link to be reused, got %+v err=%v", again, err)
+	}
+
+	// 刷新 Token 后旧短链失效
+	newToken := "66666666-7777-8888-9999-000000000000"
+	order.FormToken = &newToken
+	second, err := svc.ForShippingForm(order, nil)
+	if err != nil {
+		t.Fatalf("create refreshed link: %v", err)
+	}
+	if second.ID == first.ID {
+		t.Fatalf("expected a new link for the refreshed token")
+	}
+	_, err = svc.Resolve(first.Code)
+	requireProductBizErr(t, err, "shortLink.expired")
+
+	resolved, err := svc.Resolve(second.Code)
+	if err != nil {
+		t.Fatalf("resolve link: %v", err)
+	}
+	if err := svc.RecordClick(resolved.ID, "127.0.0.1", "test", ""); err != nil {
+		t.Fatalf("record click: %v", err)
+	}
+	resolved, _ = svc.Resolve(second.Code)
+	if resolved.ClickCount != 1 || resolved.LastClickedAt == nil {
+		t.Fatalf("expected click to be tracked, got %+v", resolved)
+	}
+
+	_, err = svc.Resolve("missing")
+	requireProductBizErr(t, err, "shortLink.notFound")
+
+	order.Status = models.OrderStatusPending
+	_, err = svc.ForShippingForm(order, nil)
+	requireProductBizErr(t, err, "shortLink.formUnavailable")
+}
+
+func TestShortLinkServicePaymentExpiryAndQRCode(t *testing.T) {
+	svc := newShortLinkServiceForTest(t)
+
+	createdAt := time.Now().Add(-time.Hour)
+	order := &models.Order{ID: 20, OrderNo: "ORD20", Status: models.OrderStatusPendingPayment, CreatedAt: createdAt}
+	link, err := svc.ForPayment(order, nil)
+	if err != nil {
+		t.Fatalf("create payment link: %v", err)
+	}
+	if link.TargetURL != "https://shop.example.com/orders/ORD20" {
+		t.Fatalf("unexpected payment target: %s", link.TargetURL)
+	}
+	if link.ExpiresAt == nil || !link.ExpiresAt.Equal(createdAt.Add(24*time.Hour)) {
+		t.Fatalf("expected expiry to follow auto cancel deadline, got %v", link.ExpiresAt)
+	}
+
+	png, err := svc.QRCodePNG(link, 10)
+	if err != nil {
+		t.Fatalf("generate qr code: %v", err)
+	}
+	if !bytes.HasPrefix(png, []byte("\x89PNG")) {
+		t.Fatalf("expected png output")
+	}
+
+	order.CreatedAt = time.Now().Add(-48 * time.Hour)
+	order.ID = 21
+	order.OrderNo = "ORD21"
+	_, err = svc.ForPayment(order, nil)
+	requireProductBizErr(t, err, "shortLink.expired")
+
+	order.Status = models.OrderStatusPending
+	_, err = svc.ForPayment(order, nil)
+	requireProductBizErr(t, err, "shortLink.paymentUnavailable")
+}
@@ -19,10 +19,14 @@ import (
 	"auralogic/internal/config"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/cache"
+	"auralogic/internal/pkg/demomode"
 	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
 )
 
+// smsHTTPClient 短信服务商接口调用，演示模式下不发出请求
+var smsHTTPClient = &http.Client{Transport: demomode.Transport(nil)}
+
 type SMSService struct {
 	cfg           *config.Config
 	db            *gorm.DB
@@ -183,7 +187,7 @@ func (s *SMSService) SendVerificationCode(phone, phoneCode, code, eventType stri
 		allowed = true
 	}
 	if !allowed {
-		if rl.ExceedAction == "delay" {
+		if rl.ExceedAction == "delay" && cache.RedisClient != nil {
 			// Store in delayed sorted set and re-check the quota when it becomes ready.
 			ctx := cache.RedisClient.Context()
 			payload, _ := json.Marshal(map[string]interface{}{
@@ -463,7 +467,7 @@ func (s *SMSService) sendAliyun(phone, countryCode, code, eventType string) erro
 
 	params.Set("Signature", s.signAliyunParams(params))
 
-	resp, err := http.Get("https://dysmsapi.aliyuncs.com/?" + params.Encode())
+	resp, err := smsHTTPClient.Get("https://dysmsapi.aliyuncs.com/?" + params.Encode())
 	if err != nil {
 		return fmt.Errorf("aliyun SMS request failed: %w", err)
 	}
@@ -523,7 +527,7 @@ func (s *SMSService) sendAliyunDYPNS(phone, countryCode, code, eventType string)
 
 	params.Set("Signature", s.signAliyunParams(params))
 
-	resp, err := http.Get("https://dypnsapi.aliyuncs.com/?" + params.Encode())
+	resp, err := smsHTTPClient.Get("https://dypnsapi.aliyuncs.com/?" + params.Encode())
 	if err != nil {
 		return fmt.Errorf("aliyun DYPNS request failed: %w", err)
 	}
@@ -569,7 +573,7 @@ func (s *SMSService) sendTwilioMessage(phone, body string) error {
 	req.SetBasicAuth(smsCfg.TwilioAccountSID, smsCfg.TwilioAuthToken)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := smsHTTPClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("twilio SMS request failed: %w", err)
 	}
@@ -610,7 +614,7 @@ func (s *SMSService) sendCustomHTTPMessage(phone, phoneCode, message string) err
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := smsHTTPClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("custom SMS request failed: %w", err)
 	}
@@ -0,0 +1,462 @@
+package service
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+const storeResolverCacheTTL = 30 * time.Second
+
+var (
+	storeCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+	storeHostPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+	ErrStoreNotFound = bizerr.New("store.notFound", "Store not found")
+)
+
+// StoreInput 创建/更新店铺参数
+type StoreInput struct {
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	LogoURL      string   `json:"logo_url"`
+	FaviconURL   string   `json:"favicon_url"`
+	PrimaryColor string   `json:"primary_color"`
+	IsDefault    bool     `json:"is_default"`
+	IsActive     *bool    `json:"is_active"`
+	Domains      []string `json:"domains"`
+}
+
+type storeResolverSnapshot struct {
+	byHost    map[string]models.Store
+	fallback  *models.Store
+	loadedAt  time.Time
+	hasStores bool
+}
+
+// StoreService 多店铺管理与 Host 解析
+type StoreService struct {
+	db *gorm.DB
+
+	cacheMu sync.RWMutex
+	cache   *storeResolverSnapshot
+}
+
+func NewStoreService(db *gorm.DB) *StoreService {
+	return &StoreService{db: db}
+}
+
+// NormalizeStoreHost 规范化 Host：转小写、去端口与末尾的点
+func NormalizeStoreHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	return strings.TrimSuffix(host, ".")
+}
+
+func normalizeStoreDomains(domains []string) ([]string, error) {
+	result := make([]string, 0, len(domains))
+	seen := make(map[string]struct{}, len(domains))
+	for _, raw := range domains {
+		host := NormalizeStoreHost(raw)
+		if host == "" {
+			continue
+		}
+		if len(host) > 255 || !storeHostPattern.MatchString(host) {
+			return nil, bizerr.Newf("store.domainInvalid", "Invalid domain: %s", raw).
+				WithParams(map[string]interface{}{"domain": raw})
+		}
+		if _, exists := seen[host]; exists {
+			continue
+		}
+		seen[host] = struct{}{}
+		result = append(result, host)
+	}
+	return result, nil
+}
+
+func (s *StoreService) validateInput(input *StoreInput) ([]string, error) {
+	input.Code = strings.ToLower(strings.TrimSpace(input.Code))
+	input.Name = strings.TrimSpace(input.Name)
+	if !storeCodePattern.MatchString(input.Code) {
+		return nil, bizerr.New("store.codeInvalid", "Store code may only contain lowercase letters, digits and hyphens")
+	}
+	if input.Name == "" {
+		return nil, bizerr.New("store.nameRequired", "Store name is required")
+	}
+	return normalizeStoreDomains(input.Domains)
+}
+
+func (s *StoreService) ensureUniqueTx(tx *gorm.DB, storeID uint, code string, domains []string) error {
+	var count int64
+	if err := tx.Unscoped().Model(&models.Store{}).Where("code = ? AND id <> ?", code, storeID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return bizerr.New("store.codeAlreadyExists", "Store code already exists")
+	}
+	if len(domains) == 0 {
+		return nil
+	}
+	var taken []models.StoreDomain
+	// 未绑定店铺的自定义域名（store_id = 0）可以被店铺认领
+	if err := tx.Where("host IN ? AND store_id <> ? AND store_id <> 0", domains, storeID).Limit(1).Find(&taken).Error; err != nil {
+		return err
+	}
+	if len(taken) > 0 {
+		return bizerr.Newf("store.domainAlreadyBound", "Domain %s is already bound to another store", taken[0].Host).
+			WithParams(map[string]interface{}{"domain": taken[0].Host})
+	}
+	return nil
+}
+
+// replaceDomainsTx 按新列表同步店铺域名，保留已有域名的验证与证书状态
+func (s *StoreService) replaceDomainsTx(tx *gorm.DB, storeID uint, domains []string) error {
+	var existing []models.StoreDomain
+	if err := tx.Where("store_id = ?", storeID).Find(&existing).Error; err != nil {
+		return err
+	}
+	keep := make(map[string]struct{}, len(domains))
+	for _, host := range domains {
+		keep[host] = struct{}{}
+	}
+	for _, domain := range existing {
+		if _, ok := keep[domain.Host]; ok {
+			continue
+		}
+		if err := tx.Delete(&models.StoreDomain{}, domain.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	for i, host := range domains {
+		var found []models.StoreDomain
+		if err := tx.Where("host = ?", host).Limit(1).Find(&found).Error; err != nil {
+			return err
+		}
+		if len(found) > 0 {
+			if err := tx.Model(&models.StoreDomain{}).Where("id = ?", found[0].ID).Updates(map[string]interface{}{
+				"store_id":   storeID,
+				"is_primary": i == 0,
+			}).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		domain, err := newStoreDomain(host)
+		if err != nil {
+			return err
+		}
+		domain.StoreID = storeID
+		domain.IsPrimary = i == 0
+		if err := tx.Create(domain).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StoreService) clearOtherDefaultsTx(tx *gorm.DB, storeID uint) error {
+	return tx.Model(&models.Store{}).
+		Where("id <> ? AND is_default = ?", storeID, true).
+		Update("is_default", false).Error
+}
+
+// List 获取店铺列表（含域名）
+func (s *StoreService) List() ([]models.Store, error) {
+	var stores []models.Store
+	err := s.db.Preload("Domains").Order("is_default DESC, id ASC").Find(&stores).Error
+	return stores, err
+}
+
+// Get 获取店铺详情
+func (s *StoreService) Get(id uint) (*models.Store, error) {
+	var store models.Store
+	if err := s.db.Preload("Domains").First(&store, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStoreNotFound
+		}
+		return nil, err
+	}
+	return &store, nil
+}
+
+// Create 创建店铺
+func (s *StoreService) Create(input StoreInput) (*models.Store, error) {
+	domains, err := s.validateInput(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &models.Store{
+		Code:         input.Code,
+		Name:         input.Name,
+		Description:  strings.TrimSpace(input.Description),
+		LogoURL:      strings.TrimSpace(input.LogoURL),
+		FaviconURL:   strings.TrimSpace(input.FaviconURL),
+		PrimaryColor: strings.TrimSpace(input.PrimaryColor),
+		IsDefault:    input.IsDefault,
+		IsActive:     input.IsActive == nil || *input.IsActive,
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.ensureUniqueTx(tx, 0, store.Code, domains); err != nil {
+			return err
+		}
+		if err := tx.Create(store).Error; err != nil {
+			return err
+		}
+		// GORM 对 bool 零值使用 default 标签，显式写回停用状态
+		if !store.IsActive {
+			if err := tx.Model(store).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+		if store.IsDefault {
+			if err := s.clearOtherDefaultsTx(tx, store.ID); err != nil {
+				return err
+			}
+		}
+		return s.replaceDomainsTx(tx, store.ID, domains)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.InvalidateCache()
+	return s.Get(store.ID)
+}
+
+// Update 更新店铺（域名列表整体替换）
+func (s *StoreService) Update(id uint, input StoreInput) (*models.Store, error) {
+	domains, err := s.validateInput(&input)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	isActive := existing.IsActive
+	if input.IsActive != nil {
+		isActive = *input.IsActive
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.ensureUniqueTx(tx, id, input.Code, domains); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Store{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"code":          input.Code,
+			"name":          input.Name,
+			"description":   strings.TrimSpace(input.Description),
+			"logo_url":      strings.TrimSpace(input.LogoURL),
+			"favicon_url":   strings.TrimSpace(input.FaviconURL),
+			"primary_color": strings.TrimSpace(input.PrimaryColor),
+			"is_default":    input.IsDefault,
+			"is_active":     isActive,
+		}).Error; err != nil {
+			return err
+		}
+		if input.IsDefault {
+			if err := s.clearOtherDefaultsTx(tx, id); err != nil {
+				return err
+			}
+		}
+		return s.replaceDomainsTx(tx, id, domains)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.InvalidateCache()
+	return s.Get(id)
+}
+
+// Delete 删除店铺（软删除），同时解除域名与管理员绑定
+// 域名保留为未绑定的自定义域名以保留验证与证书状态；
+// 已绑定到该店铺的商品/订单/付款方式保留 store_id 以便追溯
+func (s *StoreService) Delete(id uint) error {
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.StoreDomain{}).Where("store_id = ?", id).Updates(map[string]interface{}{
+			"store_id":   0,
+			"is_primary": false,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("store_id = ?", id).Delete(&models.StoreAdmin{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Store{}, id).Error
+	})
+	if err != nil {
+		return err
+	}
+	s.InvalidateCache()
+	return nil
+}
+
+// ListAdminUserIDs 获取店铺绑定的管理员ID
+func (s *StoreService) ListAdminUserIDs(storeID uint) ([]uint, error) {
+	var userIDs []uint
+	err := s.db.Model(&models.StoreAdmin{}).
+		Where("store_id = ?", storeID).
+		Order("user_id ASC").
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// SetAdmins 整体替换店铺绑定的管理员
+func (s *StoreService) SetAdmins(storeID uint, userIDs []uint) ([]uint, error) {
+	if _, err := s.Get(storeID); err != nil {
+		return nil, err
+	}
+
+	unique := make([]uint, 0, len(userIDs))
+	seen := make(map[uint]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if id == 0 {
+			continue
+		}
+		if _, exists := seen[id]; exists {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	if len(unique) > 0 {
+		var count int64
+		if err := s.db.Model(&models.User{}).
+			Where("id IN ? AND role IN ?", unique, []string{"admin", "super_admin"}).
+			Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if int(count) != len(unique) {
+			return nil, bizerr.New("store.adminInvalid", "Only existing administrators can be assigned to a store")
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("store_id = ?", storeID).Delete(&models.StoreAdmin{}).Error; err != nil {
+			return err
+		}
+		for _, userID := range unique {
+			if err := tx.Create(&models.StoreAdmin{StoreID: storeID, UserID: userID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unique, nil
+}
+
+// SetPaymentMethodStore 设置付款方式所属店铺，storeID 为空表示所有店铺可用
+func (s *StoreService) SetPaymentMethodStore(paymentMethodID uint, storeID *uint) error {
+	if storeID != nil {
+		if _, err := s.Get(*storeID); err != nil {
+			return err
+		}
+	}
+	result := s.db.Model(&models.PaymentMethod{}).
+		Where("id = ?", paymentMethodID).
+		Update("store_id", storeID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// AdminStoreIDs 获取管理员可管理的店铺ID；返回空表示不受店铺限制
+func (s *StoreService) AdminStoreIDs(userID uint) ([]uint, error) {
+	var storeIDs []uint
+	err := s.db.Model(&models.StoreAdmin{}).
+		Where("user_id = ?", userID).
+		Order("store_id ASC").
+		Pluck("store_id", &storeIDs).Error
+	return storeIDs, err
+}
+
+// InvalidateCache 使 Host 解析缓存失效
+func (s *StoreService) InvalidateCache() {
+	s.cacheMu.Lock()
+	s.cache = nil
+	s.cacheMu.Unlock()
+}
+
+func (s *StoreService) loadSnapshot() (*storeResolverSnapshot, error) {
+	s.cacheMu.RLock()
+	snapshot := s.cache
+	s.cacheMu.RUnlock()
+	if snapshot != nil && time.Since(snapshot.loadedAt) < storeResolverCacheTTL {
+		return snapshot, nil
+	}
+
+	var stores []models.Store
+	if err := s.db.Preload("Domains").Where("is_active = ?", true).Order("id ASC").Find(&stores).Error; err != nil {
+		return nil, err
+	}
+
+	snapshot = &storeResolverSnapshot{
+		byHost:    make(map[string]models.Store),
+		loadedAt:  time.Now(),
+		hasStores: len(stores) > 0,
+	}
+	for i := range stores {
+		store := stores[i]
+		domains := store.Domains
+		store.Domains = nil
+		for _, domain := range domains {
+			snapshot.byHost[domain.Host] = store
+		}
+		if store.IsDefault && snapshot.fallback == nil {
+			fallback := store
+			snapshot.fallback = &fallback
+		}
+	}
+
+	s.cacheMu.Lock()
+	s.cache = snapshot
+	s.cacheMu.Unlock()
+	return snapshot, nil
+}
+
+// ResolveStoreByHost 根据请求 Host 解析店铺，未匹配时回退到默认店铺
+// 未配置任何店铺或无默认店铺时返回 nil（单店铺模式）
+func (s *StoreService) ResolveStoreByHost(host string) (*models.Store, error) {
+	snapshot, err := s.loadSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	if !snapshot.hasStores {
+		return nil, nil
+	}
+	if store, ok := snapshot.byHost[NormalizeStoreHost(host)]; ok {
+		resolved := store
+		return &resolved, nil
+	}
+	if snapshot.fallback != nil {
+		fallback := *snapshot.fallback
+		return &fallback, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"auralogic/internal/models"
+	"auralogic/internal/repository"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newStoreServiceTestDB(t *testing.T) (*StoreService, *gorm.DB) {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.Product{},
+		&models.Store{},
+		&models.StoreDomain{},
+		&models.StoreAdmin{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	return NewStoreService(db), db
+}
+
+func TestStoreServiceResolvesStoreByHost(t *testing.T) {
+	svc, _ := newStoreServiceTestDB(t)
+
+	if store, err := svc.ResolveStoreByHost("shop.example.com"); err != nil || store != nil {
+		t.Fatalf("expected single-store mode without stores, got store=%v err=%v", store, err)
+	}
+
+	mainStore, err := svc.Create(StoreInput{Code: "main", Name: "Main", IsDefault: true, Domains: []string{"shop.example.com"}})
+	if err != nil {
+		t.Fatalf("create main store: %v", err)
+	}
+	outlet, err := svc.Create(StoreInput{Code: "outlet", Name: "Outlet", Domains: []string{"Outlet.Example.com:8443", "outlet.example.com."}})
+	if err != nil {
+		t.Fatalf("create outlet store: %v", err)
+	}
+	if len(outlet.Domains) != 1 || outlet.Domains[0].Host != "outlet.example.com" {
+		t.Fatalf("expected normalized single domain, got %+v", outlet.Domains)
+	}
+
+	store, err := svc.ResolveStoreByHost("OUTLET.example.com:443")
+	if err != nil || store == nil || store.ID != outlet.ID {
+		t.Fatalf("expected outlet store, got store=%v err=%v", store, err)
+	}
+	store, err = svc.ResolveStoreByHost("unknown.example.com")
+	if err != nil || store == nil || store.ID != mainStore.ID {
+		t.Fatalf("expected fallback to default store, got store=%v err=%v", store, err)
+	}
+
+	inactive := false
+	if _, err := svc.Update(outlet.ID, StoreInput{Code: "outlet", Name: "Outlet", IsActive: &inactive, Domains: []string{"outlet.example.com"}}); err != nil {
+		t.Fatalf("deactivate outlet: %v", err)
+	}
+	store, err = svc.ResolveStoreByHost("outlet.example.com")
+	if err != nil || store == nil || store.ID != mainStore.ID {
+		t.Fatalf("expected inactive store to be skipped, got store=%v err=%v", store, err)
+	}
+}
+
+func TestStoreServiceRejectsDuplicateCodeAndDomain(t *testing.T) {
+	svc, _ := newStoreServiceTestDB(t)
+
+	if _, err := svc.Create(StoreInput{Code: "main", Name: "Main", Domains: []string{"shop.example.com"}}); err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	requireProductBizErr(t, func() error {
+		_, err := svc.Create(StoreInput{Code: "main", Name: "Copy"})
+		return err
+	}(), "store.codeAlreadyExists")
+	requireProductBizErr(t, func() error {
+		_, err := svc.Create(StoreInput{Code: "other", Name: "Other", Domains: []string{"SHOP.example.com"}})
+		return err
+	}(), "store.domainAlreadyBound")
+	requireProductBizErr(t, func() error {
+		_, err := svc.Create(StoreInput{Code: "Bad Code", Name: "Bad"})
+		return err
+	}(), "store.codeInvalid")
+	requireProductBizErr(t, func() error {
+		_, err := svc.Create(StoreInput{Code: "bad-domain", Name: "Bad", Domains: []string{"exa mple.com"}})
+		return err
+	}(), "store.domainInvalid")
+}
+
+func TestStoreServiceSetAdminsRequiresAdministrators(t *testing.T) {
+	svc, db := newStoreServiceTestDB(t)
+
+	store, err := svc.Create(StoreInput{Code: "main", Name: "Main"})
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	admin := models.User{UUID: "store-admin", Email: "admin@example.com", Role: "admin", IsActive: true}
+	customer := models.User{UUID: "store-customer", Email: "customer@example.com", Role: "user", IsActive: true}
+	if err := db.Create(&admin).Error; err != nil {
+		t.Fatalf("create admin: %v", err)
+	}
+	if err := db.Create(&customer).Error; err != nil {
+		t.Fatalf("create customer: %v", err)
+	}
+
+	requireProductBizErr(t, func() error {
+		_, err := svc.SetAdmins(store.ID, []uint{admin.ID, customer.ID})
+		return err
+	}(), "store.adminInvalid")
+
+	if _, err := svc.SetAdmins(store.ID, []uint{admin.ID, admin.ID}); err != nil {
+		t.Fatalf("set admins: %v", err)
+	}
+	storeIDs, err := svc.AdminStoreIDs(admin.ID)
+	if err != nil {
+		t.Fatalf("admin store ids: %v", err)
+	}
+	if len(storeIDs) != 1 || storeIDs[0] != store.ID {
+		t.Fatalf("expected admin bound to store %d, got %v", store.ID, storeIDs)
+	}
+}
+
+func TestStoreScopeIncludesSharedProductsOnStorefront(t *testing.T) {
+	_, db := newStoreServiceTestDB(t)
+
+	storeA, storeB := uint(1), uint(2)
+	for _, product := range []models.Product{
+		{SKU: "shared", Name: "Shared", Status: models.ProductStatusActive},
+		{SKU: "store-a", Name: "A", Status: models.ProductStatusActive, StoreID: &storeA},
+		{SKU: "store-b", Name: "B", Status: models.ProductStatusActive, StoreID: &storeB},
+	} {
+		p := product
+		if err := db.Create(&p).Error; err != nil {
+			t.Fatalf("create product: %v", err)
+		}
+	}
+
+	repo := repository.NewProductRepository(db)
+	products, total, err := repo.List(1, 20, "", "", "", nil, nil, true, repository.StorefrontScope(storeA))
+	if err != nil {
+		t.Fatalf("list products: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected shared + store A products, got %d (%v)", total, products)
+	}
+	for _, product := range products {
+		if product.SKU == "store-b" {
+			t.Fatalf("store B product leaked into store A storefront")
+		}
+	}
+
+	_, total, err = repo.List(1, 20, "", "", "", nil, nil, true, &repository.StoreScope{StoreIDs: []uint{storeB}})
+	if err != nil {
+		t.Fatalf("list products: %v", err)
+	}
+	if total != 1 {
+		t.Fatalf("expected only store B products for admin scope, got %d", total)
+	}
+}
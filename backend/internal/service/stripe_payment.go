@@ -0,0 +1,449 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+// PaymentSourceStripeWebhook Stripe webhook 确认付款的来源标识
+const PaymentSourceStripeWebhook = "stripe_webhook"
+
+const (
+	stripeWebhookTolerance   = 5 * time.Minute
+	stripeMaxResponseBytes   = 1 << 20
+	stripeCardCacheTTL       = 300
+	stripeDefaultPollSeconds = 60
+)
+
+var (
+	// 测试中替换为本地服务
+	stripeAPIBaseURL = "https://api.stripe.com"
+	stripeHTTPClient = &http.Client{Timeout: 20 * time.Second}
+)
+
+// Stripe 零小数位币种：金额按主单位提交
+var stripeZeroDecimalCurrencies = map[string]struct{}{
+	"BIF": {}, "CLP": {}, "DJF": {}, "GNF": {}, "JPY": {}, "KMF": {}, "KRW": {}, "MGA": {},
+	"PYG": {}, "RWF": {}, "UGX": {}, "VND": {}, "VUV": {}, "XAF": {}, "XOF": {}, "XPF": {},
+}
+
+// StripeConfig Stripe 付款方式配置（保存在 PaymentMethod.Config）
+type StripeConfig struct {
+	SecretKey      string `json:"secret_key"`
+	PublishableKey string `json:"publishable_key"`
+	WebhookSecret  string `json:"webhook_secret"`
+}
+
+// StripeWebhookOutcome webhook 处理结果；Payment 非空时由调用方确认付款
+type StripeWebhookOutcome struct {
+	EventID   string
+	EventType string
+	OrderID   uint
+	Payment   *PaymentCheckResult
+	Ignored   bool
+}
+
+type stripePaymentIntent struct {
+	ID             string            `json:"id"`
+	Status         string            `json:"status"`
+	ClientSecret   string            `json:"client_secret"`
+	Amount         int64             `json:"amount"`
+	AmountReceived int64             `json:"amount_received"`
+	Currency       string            `json:"currency"`
+	LatestCharge   string            `json:"latest_charge"`
+	Metadata       map[string]string `json:"metadata"`
+}
+
+type stripeRefund struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	Amount        int64  `json:"amount"`
+	PaymentIntent string `json:"payment_intent"`
+	FailureReason string `json:"failure_reason"`
+}
+
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// stripeOrderPaymentData 订单付款数据中与 Stripe 相关的字段
+type stripeOrderPaymentData struct {
+	Provider        string `json:"provider"`
+	PaymentIntentID string `json:"payment_intent_id"`
+	TransactionID   string `json:"transaction_id"`
+}
+
+func parseStripeConfig(pm *models.PaymentMethod) (*StripeConfig, error) {
+	var cfg StripeConfig
+	if raw := strings.TrimSpace(pm.Config); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+			return nil, fmt.Errorf("stripe config is invalid: %w", err)
+		}
+	}
+	cfg.SecretKey = strings.TrimSpace(cfg.SecretKey)
+	cfg.PublishableKey = strings.TrimSpace(cfg.PublishableKey)
+	cfg.WebhookSecret = strings.TrimSpace(cfg.WebhookSecret)
+	if cfg.SecretKey == "" {
+		return nil, bizerr.New("payment.stripeNotConfigured", "Stripe secret key is not configured")
+	}
+	return &cfg, nil
+}
+
+// stripeAmount 订单最小单位金额换算为 Stripe 金额
+func stripeAmount(amountMinor int64, currency string) int64 {
+	if _, ok := stripeZeroDecimalCurrencies[strings.ToUpper(strings.TrimSpace(currency))]; ok {
+		return amountMinor / money.CurrencyScale
+	}
+	return amountMinor
+}
+
+func stripeRequest(cfg *StripeConfig, method, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(stripeAPIBaseURL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(cfg.SecretKey, "")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := stripeHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, stripeMaxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("stripe response read failed: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(raw, &apiErr)
+		message := strings.TrimSpace(apiErr.Error.Message)
+		if message == "" {
+			message = resp.Status
+		}
+		return fmt.Errorf("stripe api error (%d): %s", resp.StatusCode, message)
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// createStripePaymentIntent 为订单创建 PaymentIntent；同一订单金额下重复选择复用同一 PaymentIntent
+func createStripePaymentIntent(pm *models.PaymentMethod, order *models.Order) (*stripePaymentIntent, error) {
+	cfg, err := parseStripeConfig(pm)
+	if err != nil {
+		return nil, err
+	}
+	amount := stripeAmount(order.TotalAmount, order.Currency)
+	if amount <= 0 {
+		return nil, bizerr.New("payment.stripeAmountInvalid", "Order amount is too small for Stripe")
+	}
+	currency := strings.ToLower(strings.TrimSpace(order.Currency))
+
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(amount, 10))
+	form.Set("currency", currency)
+	form.Set("automatic_payment_methods[enabled]", "true")
+	form.Set("description", "Order "+order.OrderNo)
+	form.Set("metadata[order_id]", strconv.FormatUint(uint64(order.ID), 10))
+	form.Set("metadata[order_no]", order.OrderNo)
+	form.Set("metadata[payment_method_id]", strconv.FormatUint(uint64(pm.ID), 10))
+	idempotencyKey := fmt.Sprintf("auralogic-order-%d-pm-%d-%d-%s", order.ID, pm.ID, amount, currency)
+
+	var intent stripePaymentIntent
+	if err := stripeRequest(cfg, http.MethodPost, "/v1/payment_intents", form, idempotencyKey, &intent); err != nil {
+		return nil, err
+	}
+	if intent.ID == "" {
+		return nil, errors.New("stripe returned an empty payment intent")
+	}
+	return &intent, nil
+}
+
+func retrieveStripePaymentIntent(cfg *StripeConfig, intentID string) (*stripePaymentIntent, error) {
+	var intent stripePaymentIntent
+	if err := stripeRequest(cfg, http.MethodGet, "/v1/payment_intents/"+url.PathEscape(intentID), nil, "", &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+func encodeStripeOrderPaymentData(intent *stripePaymentIntent) string {
+	encoded, _ := json.Marshal(map[string]interface{}{
+		"provider":          models.PaymentProviderStripe,
+		"payment_intent_id": intent.ID,
+	})
+	return string(encoded)
+}
+
+// loadStripePaymentIntentID 读取订单关联的 PaymentIntent；付款确认后 payment_data 被覆盖时回退到交易号
+func loadStripePaymentIntentID(db *gorm.DB, orderID uint) (string, error) {
+	var opm models.OrderPaymentMethod
+	if err := db.Where("order_id = ?", orderID).First(&opm).Error; err != nil {
+		return "", err
+	}
+	var data stripeOrderPaymentData
+	if strings.TrimSpace(opm.PaymentData) != "" {
+		_ = json.Unmarshal([]byte(opm.PaymentData), &data)
+	}
+	if data.PaymentIntentID != "" {
+		return data.PaymentIntentID, nil
+	}
+	if strings.HasPrefix(data.TransactionID, "pi_") {
+		return data.TransactionID, nil
+	}
+	return "", nil
+}
+
+// stripeIntentCheckResult PaymentIntent 成功且金额币种与订单一致时视为已付款
+func stripeIntentCheckResult(intent *stripePaymentIntent, order *models.Order) *PaymentCheckResult {
+	if intent.Status != "succeeded" {
+		return &PaymentCheckResult{Paid: false, Message: "Stripe payment status: " + intent.Status}
+	}
+	received := intent.AmountReceived
+	if received == 0 {
+		received = intent.Amount
+	}
+	if !strings.EqualFold(intent.Currency, order.Currency) || received < stripeAmount(order.TotalAmount, order.Currency) {
+		return &PaymentCheckResult{Paid: false, Message: "Stripe payment amount does not match the order"}
+	}
+	return &PaymentCheckResult{
+		Paid:          true,
+		TransactionID: intent.ID,
+		Message:       "Stripe payment succeeded",
+		Data: map[string]interface{}{
+			"provider":          models.PaymentProviderStripe,
+			"payment_intent_id": intent.ID,
+			"charge_id":         intent.LatestCharge,
+		},
+	}
+}
+
+// checkStripePaymentStatus 轮询兜底：webhook 未送达时按 PaymentIntent 状态确认付款
+func checkStripePaymentStatus(db *gorm.DB, pm *models.PaymentMethod, order *models.Order) (*PaymentCheckResult, error) {
+	cfg, err := parseStripeConfig(pm)
+	if err != nil {
+		return nil, err
+	}
+	intentID, err := loadStripePaymentIntentID(db, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if intentID == "" {
+		return &PaymentCheckResult{Paid: false, Message: "Stripe payment has not been started"}, nil
+	}
+	intent, err := retrieveStripePaymentIntent(cfg, intentID)
+	if err != nil {
+		return nil, err
+	}
+	return stripeIntentCheckResult(intent, order), nil
+}
+
+// buildStripePaymentCard 付款卡片携带 publishable key 与 client secret，由前端挂载 Stripe Payment Element
+func buildStripePaymentCard(db *gorm.DB, pm *models.PaymentMethod, order *models.Order) (*PaymentCardResult, error) {
+	cfg, err := parseStripeConfig(pm)
+	if err != nil {
+		return nil, err
+	}
+	amount := ""
+	if order != nil {
+		amount = money.MinorToString(order.TotalAmount) + " " + order.Currency
+	}
+	data := map[string]interface{}{
+		"provider":        models.PaymentProviderStripe,
+		"publishable_key": cfg.PublishableKey,
+	}
+	result := &PaymentCardResult{
+		Title:    "Stripe",
+		Data:     data,
+		CacheTTL: 0,
+	}
+
+	if order != nil && order.ID > 0 {
+		intentID, err := loadStripePaymentIntentID(db, order.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if intentID != "" {
+			intent, err := retrieveStripePaymentIntent(cfg, intentID)
+			if err != nil {
+				return nil, err
+			}
+			data["payment_intent_id"] = intent.ID
+			data["client_secret"] = intent.ClientSecret
+			data["status"] = intent.Status
+			result.CacheTTL = stripeCardCacheTTL
+		}
+	}
+
+	result.HTML = `<div class="space-y-3">` +
+		`<div class="p-4 bg-muted rounded-lg">` +
+		`<p class="text-sm text-muted-foreground"><span class="lang-en">Pay by card</span><span class="lang-zh">银行卡支付</span></p>` +
+		`<p class="text-2xl font-bold mt-1">` + html.EscapeString(amount) + `</p>` +
+		`</div>` +
+		`<p class="text-sm text-muted-foreground"><span class="lang-en">Payments are processed securely by Stripe.</span><span class="lang-zh">付款由 Stripe 安全处理。</span></p>` +
+		`</div>`
+	return result, nil
+}
+
+// executeStripeRefund 通过 Stripe Refund API 退回指定金额，以退款记录 ID 作为幂等键
+func executeStripeRefund(db *gorm.DB, pm *models.PaymentMethod, order *models.Order, request *RefundRequest) (*RefundResult, error) {
+	cfg, err := parseStripeConfig(pm)
+	if err != nil {
+		return nil, err
+	}
+	intentID, err := loadStripePaymentIntentID(db, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if intentID == "" {
+		return &RefundResult{Success: false, Message: "Order has no Stripe payment to refund"}, nil
+	}
+
+	amountMinor := order.TotalAmount
+	reason := ""
+	var refundID uint
+	if request != nil {
+		amountMinor = request.AmountMinor
+		reason = request.Reason
+		refundID = request.RefundID
+	}
+	form := url.Values{}
+	form.Set("payment_intent", intentID)
+	form.Set("amount", strconv.FormatInt(stripeAmount(amountMinor, order.Currency), 10))
+	form.Set("reason", "requested_by_customer")
+	form.Set("metadata[order_no]", order.OrderNo)
+	if reason = strings.TrimSpace(reason); reason != "" {
+		form.Set("metadata[reason]", truncateRefundField(reason, 500))
+	}
+	idempotencyKey := ""
+	if refundID > 0 {
+		form.Set("metadata[refund_id]", strconv.FormatUint(uint64(refundID), 10))
+		idempotencyKey = fmt.Sprintf("auralogic-refund-%d", refundID)
+	}
+
+	var refund stripeRefund
+	if err := stripeRequest(cfg, http.MethodPost, "/v1/refunds", form, idempotencyKey, &refund); err != nil {
+		return &RefundResult{Success: false, Message: err.Error()}, nil
+	}
+	data := map[string]interface{}{
+		"provider":          models.PaymentProviderStripe,
+		"payment_intent_id": intentID,
+		"refund_status":     refund.Status,
+	}
+	switch refund.Status {
+	case "succeeded":
+		return &RefundResult{Success: true, TransactionID: refund.ID, Message: "Stripe refund succeeded", Data: data}, nil
+	case "pending", "requires_action":
+		// 到账后由 refund.updated webhook 自动确认
+		return &RefundResult{Success: true, Pending: true, TransactionID: refund.ID, Message: "Stripe refund is pending", Data: data}, nil
+	default:
+		message := "Stripe refund " + refund.Status
+		if refund.FailureReason != "" {
+			message += ": " + refund.FailureReason
+		}
+		return &RefundResult{Success: false, TransactionID: refund.ID, Message: message, Data: data}, nil
+	}
+}
+
+// verifyStripeSignature 校验 Stripe-Signature 头（t=时间戳,v1=HMAC-SHA256("t.payload")）
+func verifyStripeSignature(payload []byte, header, secret string, now time.Time) error {
+	if secret == "" {
+		return errors.New("stripe webhook secret is not configured")
+	}
+	var (
+		timestamp  int64
+		signatures []string
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return errors.New("invalid stripe signature timestamp")
+			}
+			timestamp = parsed
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return errors.New("stripe signature header is malformed")
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > stripeWebhookTolerance || age < -stripeWebhookTolerance {
+		return errors.New("stripe signature timestamp is outside the tolerance window")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, signature := range signatures {
+		decoded, err := hex.DecodeString(signature)
+		if err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return errors.New("stripe signature mismatch")
+}
+
+// ensureStripePaymentMethod 创建原生 Stripe 付款方式（默认停用，填写密钥后由管理员启用）
+func ensureStripePaymentMethod(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.PaymentMethod{}).Where("provider = ?", models.PaymentProviderStripe).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	method := models.PaymentMethod{
+		Name:         "Stripe",
+		Description:  "Pay by card via Stripe",
+		Type:         models.PaymentMethodTypeBuiltin,
+		Provider:     models.PaymentProviderStripe,
+		Icon:         "CreditCard",
+		SortOrder:    4,
+		PollInterval: stripeDefaultPollSeconds,
+		Config:       `{"secret_key":"","publishable_key":"","webhook_secret":""}`,
+	}
+	if err := db.Create(&method).Error; err != nil {
+		return err
+	}
+	return db.Model(&models.PaymentMethod{}).Where("id = ?", method.ID).Update("enabled", false).Error
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+type fakeStripeAPI struct {
+	intents  map[string]*stripePaymentIntent
+	refunds  []map[string]string
+	idemKeys []string
+}
+
+func newFakeStripeServer(t *testing.T) *fakeStripeAPI {
+	t.Helper()
+	api := &fakeStripeAPI{intents: map[string]*stripePaymentIntent{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user != "sk_test_123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"Invalid API Key"}}`))
+			return
+		}
+		_ = r.ParseForm()
+		api.idemKeys = append(api.idemKeys, r.Header.Get("Idempotency-Key"))
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/payment_intents":
+			id := fmt.Sprintf("pi_%d", len(api.intents)+1)
+			var amount int64
+			fmt.Sscan(r.PostForm.Get("amount"), &amount)
+			intent := &stripePaymentIntent{
+				ID:           id,
+				Status:       "requires_payment_method",
+				ClientSecret: id + "_secret_abc",
+				Amount:       amount,
+				Currency:     r.PostForm.Get("currency"),
+				Metadata:     map[string]string{"order_id": r.PostForm.Get("metadata[order_id]")},
+			}
+			api.intents[id] = intent
+			_ = json.NewEncoder(w).Encode(intent)
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/payment_intents/"):
+			intent, ok := api.intents[strings.TrimPrefix(r.URL.Path, "/v1/payment_intents/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(intent)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/refunds":
+			api.refunds = append(api.refunds, map[string]string{
+				"payment_intent": r.PostForm.Get("payment_intent"),
+				"amount":         r.PostForm.Get("amount"),
+			})
+			_ = json.NewEncoder(w).Encode(stripeRefund{ID: fmt.Sprintf("re_%d", len(api.refunds)), Status: "succeeded"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	previous := stripeAPIBaseURL
+	stripeAPIBaseURL = server.URL
+	t.Cleanup(func() {
+		stripeAPIBaseURL = previous
+		server.Close()
+	})
+	return api
+}
+
+func newStripeTestService(t *testing.T) (*PaymentMethodService, *gorm.DB, *models.PaymentMethod) {
+	t.Helper()
+	db := openConcurrentServiceTestDB(t,
+		&models.Order{},
+		&models.OrderNote{},
+		&models.OrderRefund{},
+		&models.OrderPaymentMethod{},
+		&models.PaymentMethod{},
+		&models.PaymentAmountReservation{},
+	)
+	pm := &models.PaymentMethod{
+		Name:     "Stripe",
+		Type:     models.PaymentMethodTypeBuiltin,
+		Provider: models.PaymentProviderStripe,
+		Enabled:  true,
+		Config:   `{"secret_key":"sk_test_123","publishable_key":"pk_test_123","webhook_secret":"whsec_test"}`,
+	}
+	if err := db.Create(pm).Error; err != nil {
+		t.Fatalf("create payment method: %v", err)
+	}
+	return &PaymentMethodService{db: db, cfg: &config.Config{}}, db, pm
+}
+
+func signStripePayload(payload []byte, secret string, at time.Time) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", at.Unix(), payload)
+	return fmt.Sprintf("t=%d,v1=%s", at.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
+
+func stripeTestEvent(t *testing.T, eventType string, object interface{}) []byte {
+	t.Helper()
+	raw, err := json.Marshal(map[string]interface{}{
+		"id":   "evt_1",
+		"type": eventType,
+		"data": map[string]interface{}{"object": object},
+	})
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	return raw
+}
+
+func TestSelectStripeCreatesIntentAndWebhookConfirmsPayment(t *testing.T) {
+	api := newFakeStripeServer(t)
+	svc, db, pm := newStripeTestService(t)
+	order := &models.Order{OrderNo: "ORDER-STRIPE-1", Status: models.OrderStatusPendingPayment, TotalAmount: 2599, Currency: "USD"}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	if err := svc.SelectPaymentMethod(order.ID, pm.ID); err != nil {
+		t.Fatalf("select stripe: %v", err)
+	}
+	intentID, err := loadStripePaymentIntentID(db, order.ID)
+	if err != nil || intentID != "pi_1" {
+		t.Fatalf("expected stored payment intent pi_1, got %q err=%v", intentID, err)
+	}
+	if api.intents["pi_1"].Amount != 2599 || api.intents["pi_1"].Currency != "usd" {
+		t.Fatalf("unexpected intent: %+v", api.intents["pi_1"])
+	}
+
+	card, err := svc.GeneratePaymentCard(pm.ID, order)
+	if err != nil {
+		t.Fatalf("generate card: %v", err)
+	}
+	if card.Data["client_secret"] != "pi_1_secret_abc" || card.Data["publishable_key"] != "pk_test_123" {
+		t.Fatalf("unexpected card data: %+v", card.Data)
+	}
+
+	succeeded := *api.intents["pi_1"]
+	succeeded.Status = "succeeded"
+	succeeded.AmountReceived = 2599
+	payload := stripeTestEvent(t, "payment_intent.succeeded", succeeded)
+
+	if _, err := svc.HandleStripeWebhook(pm, payload, signStripePayload(payload, "whsec_wrong", time.Now())); !errors.Is(err, ErrStripeWebhookSignature) {
+		t.Fatalf("expected signature error, got %v", err)
+	}
+	if _, err := svc.HandleStripeWebhook(pm, payload, signStripePayload(payload, "whsec_test", time.Now().Add(-time.Hour))); !errors.Is(err, ErrStripeWebhookSignature) {
+		t.Fatalf("expected stale signature error, got %v", err)
+	}
+
+	outcome, err := svc.HandleStripeWebhook(pm, payload, signStripePayload(payload, "whsec_test", time.Now()))
+	if err != nil {
+		t.Fatalf("handle webhook: %v", err)
+	}
+	if outcome.OrderID != order.ID || outcome.Payment == nil || !outcome.Payment.Paid || outcome.Payment.TransactionID != "pi_1" {
+		t.Fatalf("unexpected outcome: %+v", outcome)
+	}
+
+	underpaid := succeeded
+	underpaid.AmountReceived = 100
+	payload = stripeTestEvent(t, "payment_intent.succeeded", underpaid)
+	outcome, err = svc.HandleStripeWebhook(pm, payload, signStripePayload(payload, "whsec_test", time.Now()))
+	if err != nil || outcome.Payment != nil || !outcome.Ignored {
+		t.Fatalf("expected underpaid intent to be ignored, got %+v err=%v", outcome, err)
+	}
+}
+
+func TestStripeRefundCallsRefundAPI(t *testing.T) {
+	api := newFakeStripeServer(t)
+	_, db, pm := newStripeTestService(t)
+	order := &models.Order{OrderNo: "ORDER-STRIPE-2", Status: models.OrderStatusPending, TotalAmount: 5000, Currency: "JPY"}
+	if err := db.Create(order).Error; err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if err := db.Create(&models.OrderPaymentMethod{
+		OrderID:         order.ID,
+		PaymentMethodID: pm.ID,
+		PaymentData:     `{"transaction_id":"pi_paid","provider":"stripe"}`,
+	}).Error; err != nil {
+		t.Fatalf("create order payment method: %v", err)
+	}
+
+	result, err := executeStripeRefund(db, pm, order, &RefundRequest{RefundID: 7, AmountMinor: 2000})
+	if err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+	if !result.Success || result.Pending || result.TransactionID != "re_1" {
+		t.Fatalf("unexpected refund result: %+v", result)
+	}
+	// JPY 为零小数位币种，按主单位提交
+	if len(api.refunds) != 1 || api.refunds[0]["payment_intent"] != "pi_paid" || api.refunds[0]["amount"] != "20" {
+		t.Fatalf("unexpected refund request: %+v", api.refunds)
+	}
+	if api.idemKeys[len(api.idemKeys)-1] != "auralogic-refund-7" {
+		t.Fatalf("expected refund idempotency key, got %v", api.idemKeys)
+	}
+}
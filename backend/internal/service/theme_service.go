@@ -0,0 +1,420 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+const (
+	ThemePackExportFormat  = "auralogic-theme"
+	ThemePackExportVersion = 1
+
+	themeActiveCacheTTL = 30 * time.Second
+)
+
+var (
+	themeSlugPattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,99}$`)
+	themeColorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6}|#[0-9a-fA-F]{8}|\d{1,3}(\.\d+)?(deg)? \d{1,3}(\.\d+)?% \d{1,3}(\.\d+)?%)$`)
+	themeSizePattern  = regexp.MustCompile(`^\d{1,3}(\.\d+)?(px|rem|em)$`)
+	themeFontPattern  = regexp.MustCompile(`^[A-Za-z0-9 ,'"\-_]{1,200}$`)
+
+	ErrThemeNotFound = bizerr.New("theme.notFound", "Theme not found")
+)
+
+// ThemePackInput 创建/更新主题包参数
+type ThemePackInput struct {
+	Slug        string                    `json:"slug"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Light       models.ThemePalette       `json:"light"`
+	Dark        models.ThemePalette       `json:"dark"`
+	Typography  models.ThemeTypography    `json:"typography"`
+	Email       models.ThemeEmailBranding `json:"email"`
+}
+
+// ThemePackExport 主题包导出文件格式
+type ThemePackExport struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	ThemePackInput
+}
+
+// PublicTheme 公开配置中下发的主题（不含邮件品牌）
+type PublicTheme struct {
+	Slug       string                 `json:"slug"`
+	Name       string                 `json:"name"`
+	Light      models.ThemePalette    `json:"light"`
+	Dark       models.ThemePalette    `json:"dark"`
+	Typography models.ThemeTypography `json:"typography"`
+}
+
+type themeActiveSnapshot struct {
+	theme    *models.ThemePack
+	loadedAt time.Time
+}
+
+// ThemeService 主题包管理
+type ThemeService struct {
+	db *gorm.DB
+
+	cacheMu sync.RWMutex
+	cache   *themeActiveSnapshot
+}
+
+func NewThemeService(db *gorm.DB) *ThemeService {
+	return &ThemeService{db: db}
+}
+
+func themeFieldError(key, field, value string) error {
+	return bizerr.Newf(key, "Invalid theme value for %s: %s", field, value).
+		WithParams(map[string]interface{}{"field": field, "value": value})
+}
+
+func normalizeThemePalette(prefix string, palette *models.ThemePalette) error {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"background", &palette.Background},
+		{"foreground", &palette.Foreground},
+		{"card", &palette.Card},
+		{"card_foreground", &palette.CardForeground},
+		{"primary", &palette.Primary},
+		{"primary_foreground", &palette.PrimaryForeground},
+		{"secondary", &palette.Secondary},
+		{"secondary_foreground", &palette.SecondaryForeground},
+		{"accent", &palette.Accent},
+		{"accent_foreground", &palette.AccentForeground},
+		{"muted", &palette.Muted},
+		{"muted_foreground", &palette.MutedForeground},
+		{"destructive", &palette.Destructive},
+		{"border", &palette.Border},
+		{"ring", &palette.Ring},
+	}
+	for _, field := range fields {
+		*field.value = strings.Join(strings.Fields(*field.value), " ")
+		if *field.value != "" && !themeColorPattern.MatchString(*field.value) {
+			return themeFieldError("theme.colorInvalid", prefix+"."+field.name, *field.value)
+		}
+	}
+	return nil
+}
+
+func normalizeThemeTypography(typography *models.ThemeTypography) error {
+	typography.FontFamily = strings.TrimSpace(typography.FontFamily)
+	typography.HeadingFontFamily = strings.TrimSpace(typography.HeadingFontFamily)
+	typography.FontURL = strings.TrimSpace(typography.FontURL)
+	typography.BaseFontSize = strings.TrimSpace(typography.BaseFontSize)
+	typography.Radius = strings.TrimSpace(typography.Radius)
+
+	if typography.FontFamily != "" && !themeFontPattern.MatchString(typography.FontFamily) {
+		return themeFieldError("theme.typographyInvalid", "typography.font_family", typography.FontFamily)
+	}
+	if typography.HeadingFontFamily != "" && !themeFontPattern.MatchString(typography.HeadingFontFamily) {
+		return themeFieldError("theme.typographyInvalid", "typography.heading_font_family", typography.HeadingFontFamily)
+	}
+	if typography.FontURL != "" && !strings.HasPrefix(typography.FontURL, "https://") && !strings.HasPrefix(typography.FontURL, "/") {
+		return themeFieldError("theme.typographyInvalid", "typography.font_url", typography.FontURL)
+	}
+	if typography.BaseFontSize != "" && !themeSizePattern.MatchString(typography.BaseFontSize) {
+		return themeFieldError("theme.typographyInvalid", "typography.base_font_size", typography.BaseFontSize)
+	}
+	if typography.Radius != "" && !themeSizePattern.MatchString(typography.Radius) {
+		return themeFieldError("theme.typographyInvalid", "typography.radius", typography.Radius)
+	}
+	return nil
+}
+
+func (s *ThemeService) validateInput(input *ThemePackInput) error {
+	input.Slug = strings.ToLower(strings.TrimSpace(input.Slug))
+	input.Name = strings.TrimSpace(input.Name)
+	input.Description = strings.TrimSpace(input.Description)
+	if !themeSlugPattern.MatchString(input.Slug) {
+		return bizerr.New("theme.slugInvalid", "Theme slug may only contain lowercase letters, digits and hyphens")
+	}
+	if input.Name == "" {
+		return bizerr.New("theme.nameRequired", "Theme name is required")
+	}
+	if err := normalizeThemePalette("light", &input.Light); err != nil {
+		return err
+	}
+	if err := normalizeThemePalette("dark", &input.Dark); err != nil {
+		return err
+	}
+	if err := normalizeThemeTypography(&input.Typography); err != nil {
+		return err
+	}
+	input.Email.LogoURL = strings.TrimSpace(input.Email.LogoURL)
+	input.Email.AccentColor = strings.TrimSpace(input.Email.AccentColor)
+	if input.Email.AccentColor != "" && !themeColorPattern.MatchString(input.Email.AccentColor) {
+		return themeFieldError("theme.colorInvalid", "email.accent_color", input.Email.AccentColor)
+	}
+	for field, fragment := range map[string]string{"email.header_html": input.Email.HeaderHTML, "email.footer_html": input.Email.FooterHTML} {
+		if _, err := template.New("validate").Parse(fragment); err != nil {
+			return bizerr.Newf("theme.emailTemplateInvalid", "Invalid template syntax in %s", field).
+				WithParams(map[string]interface{}{"field": field})
+		}
+	}
+	return nil
+}
+
+func (s *ThemeService) ensureSlugAvailable(slug string, excludeID uint) error {
+	var count int64
+	if err := s.db.Model(&models.ThemePack{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return bizerr.Newf("theme.slugAlreadyExists", "Theme slug %s already exists", slug).
+			WithParams(map[string]interface{}{"slug": slug})
+	}
+	return nil
+}
+
+// List 获取主题包列表
+func (s *ThemeService) List() ([]models.ThemePack, error) {
+	var themes []models.ThemePack
+	err := s.db.Order("is_active DESC, id ASC").Find(&themes).Error
+	return themes, err
+}
+
+// Get 获取主题包详情
+func (s *ThemeService) Get(id uint) (*models.ThemePack, error) {
+	var theme models.ThemePack
+	if err := s.db.First(&theme, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrThemeNotFound
+		}
+		return nil, err
+	}
+	return &theme, nil
+}
+
+// Create 创建主题包
+func (s *ThemeService) Create(input ThemePackInput, adminID uint) (*models.ThemePack, error) {
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	if err := s.ensureSlugAvailable(input.Slug, 0); err != nil {
+		return nil, err
+	}
+	theme := &models.ThemePack{
+		Slug:        input.Slug,
+		Name:        input.Name,
+		Description: input.Description,
+		Light:       input.Light,
+		Dark:        input.Dark,
+		Typography:  input.Typography,
+		Email:       input.Email,
+		UpdatedBy:   adminID,
+	}
+	if err := s.db.Create(theme).Error; err != nil {
+		return nil, err
+	}
+	return theme, nil
+}
+
+// Update 更新主题包
+func (s *ThemeService) Update(id uint, input ThemePackInput, adminID uint) (*models.ThemePack, error) {
+	theme, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	if err := s.ensureSlugAvailable(input.Slug, id); err != nil {
+		return nil, err
+	}
+	theme.Slug = input.Slug
+	theme.Name = input.Name
+	theme.Description = input.Description
+	theme.Light = input.Light
+	theme.Dark = input.Dark
+	theme.Typography = input.Typography
+	theme.Email = input.Email
+	theme.UpdatedBy = adminID
+	if err := s.db.Save(theme).Error; err != nil {
+		return nil, err
+	}
+	s.InvalidateCache()
+	return theme, nil
+}
+
+// Delete 删除主题包
+func (s *ThemeService) Delete(id uint) error {
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+	if err := s.db.Delete(&models.ThemePack{}, id).Error; err != nil {
+		return err
+	}
+	s.InvalidateCache()
+	return nil
+}
+
+// Activate 启用主题包（其余主题包自动停用），id 为 0 表示恢复默认主题
+func (s *ThemeService) Activate(id uint) error {
+	if id != 0 {
+		if _, err := s.Get(id); err != nil {
+			return err
+		}
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ThemePack{}).Where("is_active = ? AND id <> ?", true, id).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		if id == 0 {
+			return nil
+		}
+		return tx.Model(&models.ThemePack{}).Where("id = ?", id).Update("is_active", true).Error
+	})
+	if err != nil {
+		return err
+	}
+	s.InvalidateCache()
+	return nil
+}
+
+// Export 导出主题包
+func (s *ThemeService) Export(id uint) (*ThemePackExport, error) {
+	theme, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return &ThemePackExport{
+		Format:  ThemePackExportFormat,
+		Version: ThemePackExportVersion,
+		ThemePackInput: ThemePackInput{
+			Slug:        theme.Slug,
+			Name:        theme.Name,
+			Description: theme.Description,
+			Light:       theme.Light,
+			Dark:        theme.Dark,
+			Typography:  theme.Typography,
+			Email:       theme.Email,
+		},
+	}, nil
+}
+
+// Import 导入主题包 JSON；slug 已存在时 overwrite=true 覆盖，否则返回冲突
+func (s *ThemeService) Import(data []byte, overwrite bool, adminID uint) (*models.ThemePack, error) {
+	var payload ThemePackExport
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, bizerr.New("theme.importInvalid", "Invalid theme file")
+	}
+	if payload.Format != ThemePackExportFormat {
+		return nil, bizerr.New("theme.importInvalid", "Invalid theme file")
+	}
+	if payload.Version <= 0 || payload.Version > ThemePackExportVersion {
+		return nil, bizerr.Newf("theme.importVersionUnsupported", "Unsupported theme file version: %d", payload.Version).
+			WithParams(map[string]interface{}{"version": payload.Version})
+	}
+
+	input := payload.ThemePackInput
+	if overwrite {
+		var existing models.ThemePack
+		err := s.db.Where("slug = ?", strings.ToLower(strings.TrimSpace(input.Slug))).First(&existing).Error
+		if err == nil {
+			return s.Update(existing.ID, input, adminID)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return s.Create(input, adminID)
+}
+
+// InvalidateCache 使启用主题缓存失效
+func (s *ThemeService) InvalidateCache() {
+	s.cacheMu.Lock()
+	s.cache = nil
+	s.cacheMu.Unlock()
+}
+
+// Active 获取当前启用的主题包，未启用时返回 nil
+func (s *ThemeService) Active() (*models.ThemePack, error) {
+	s.cacheMu.RLock()
+	snapshot := s.cache
+	s.cacheMu.RUnlock()
+	if snapshot != nil && time.Since(snapshot.loadedAt) < themeActiveCacheTTL {
+		return snapshot.theme, nil
+	}
+
+	var themes []models.ThemePack
+	if err := s.db.Where("is_active = ?", true).Order("id ASC").Limit(1).Find(&themes).Error; err != nil {
+		return nil, err
+	}
+	snapshot = &themeActiveSnapshot{loadedAt: time.Now()}
+	if len(themes) > 0 {
+		snapshot.theme = &themes[0]
+	}
+
+	s.cacheMu.Lock()
+	s.cache = snapshot
+	s.cacheMu.Unlock()
+	return snapshot.theme, nil
+}
+
+// PublicActiveTheme 公开配置使用的启用主题
+func (s *ThemeService) PublicActiveTheme() *PublicTheme {
+	if s == nil {
+		return nil
+	}
+	theme, err := s.Active()
+	if err != nil || theme == nil {
+		return nil
+	}
+	return &PublicTheme{
+		Slug:       theme.Slug,
+		Name:       theme.Name,
+		Light:      theme.Light,
+		Dark:       theme.Dark,
+		Typography: theme.Typography,
+	}
+}
+
+// ActiveEmailBranding 当前启用主题的邮件品牌
+func (s *ThemeService) ActiveEmailBranding() (models.ThemeEmailBranding, bool) {
+	if s == nil {
+		return models.ThemeEmailBranding{}, false
+	}
+	theme, err := s.Active()
+	if err != nil || theme == nil {
+		return models.ThemeEmailBranding{}, false
+	}
+	branding := theme.Email
+	if branding.HeaderHTML == "" && branding.FooterHTML == "" {
+		return branding, false
+	}
+	return branding, true
+}
+
+var emailBodyOpenTagPattern = regexp.MustCompile(`(?i)<body[^>]*>`)
+
+// applyEmailBranding 将页眉插入 <body> 之后、页脚插入 </body> 之前
+func applyEmailBranding(content, header, footer string) string {
+	if header != "" {
+		if loc := emailBodyOpenTagPattern.FindStringIndex(content); loc != nil {
+			content = content[:loc[1]] + header + content[loc[1]:]
+		} else {
+			content = header + content
+		}
+	}
+	if footer != "" {
+		if idx := strings.LastIndex(strings.ToLower(content), "</body>"); idx >= 0 {
+			content = content[:idx] + footer + content[idx:]
+		} else {
+			content = content + footer
+		}
+	}
+	return content
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"auralogic/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newThemeServiceForTest(t *testing.T) *ThemeService {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&models.ThemePack{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	return NewThemeService(db)
+}
+
+func TestThemeServiceActivateAndPublicTheme(t *testing.T) {
+	svc := newThemeServiceForTest(t)
+
+	if theme := svc.PublicActiveTheme(); theme != nil {
+		t.Fatalf("expected no active theme, got %+v", theme)
+	}
+
+	ocean, err := svc.Create(ThemePackInput{
+		Slug:       "Ocean",
+		Name:       "Ocean",
+		Light:      models.ThemePalette{Primary: "199  89%  48%", Background: "#ffffff"},
+		Dark:       models.ThemePalette{Primary: "199 89% 60%", Background: "#0b1120"},
+		Typography: models.ThemeTypography{FontFamily: "Inter, sans-serif", Radius: "0.75rem"},
+		Email:      models.ThemeEmailBranding{HeaderHTML: `<div>{{.AppName}}</div>`},
+	}, 1)
+	if err != nil {
+		t.Fatalf("create theme: %v", err)
+	}
+	if ocean.Slug != "ocean" || ocean.Light.Primary != "199 89% 48%" {
+		t.Fatalf("expected normalized theme, got slug=%q primary=%q", ocean.Slug, ocean.Light.Primary)
+	}
+	forest, err := svc.Create(ThemePackInput{Slug: "forest", Name: "Forest"}, 1)
+	if err != nil {
+		t.Fatalf("create theme: %v", err)
+	}
+
+	if err := svc.Activate(ocean.ID); err != nil {
+		t.Fatalf("activate ocean: %v", err)
+	}
+	if err := svc.Activate(forest.ID); err != nil {
+		t.Fatalf("activate forest: %v", err)
+	}
+	theme := svc.PublicActiveTheme()
+	if theme == nil || theme.Slug != "forest" {
+		t.Fatalf("expected forest to be the only active theme, got %+v", theme)
+	}
+	var activeCount int64
+	svc.db.Model(&models.ThemePack{}).Where("is_active = ?", true).Count(&activeCount)
+	if activeCount != 1 {
+		t.Fatalf("expected exactly one active theme, got %d", activeCount)
+	}
+
+	if err := svc.Activate(0); err != nil {
+		t.Fatalf("deactivate: %v", err)
+	}
+	if theme := svc.PublicActiveTheme(); theme != nil {
+		t.Fatalf("expected default theme after deactivation, got %+v", theme)
+	}
+}
+
+func TestThemeServiceRejectsInvalidValues(t *testing.T) {
+	svc := newThemeServiceForTest(t)
+
+	requireProductBizErr(t, func() error {
+		_, err := svc.Create(ThemePackInput{Slug: "bad", Name: "Bad", Dark: models.ThemePalette{Accent: "red; background:url(x)"}}, 1)
+		return err
+	}(), "theme.colorInvalid")
+	requireProductBizErr(t, func() error {
+		_, err := svc.Create(ThemePackInput{Slug: "bad", Name: "Bad", Typography: models.ThemeTypography{FontFamily: "Inter; } body {"}}, 1)
+		return err
+	}(), "theme.typographyInvalid")
+	requireProductBizErr(t, func() error {
+		_, err := svc.Create(ThemePackInput{Slug: "bad", Name: "Bad", Typography: models.ThemeTypography{FontURL: "http://fonts.example.com/a.css"}}, 1)
+		return err
+	}(), "theme.typographyInvalid")
+	requireProductBizErr(t, func() error {
+		_, err := svc.Create(ThemePackInput{Slug: "bad", Name: "Bad", Email: models.ThemeEmailBranding{FooterHTML: "{{.AppName"}}, 1)
+		return err
+	}(), "theme.emailTemplateInvalid")
+	requireProductBizErr(t, func() error {
+		_, err := svc.Create(ThemePackInput{Slug: "Bad Slug", Name: "Bad"}, 1)
+		return err
+	}(), "theme.slugInvalid")
+}
+
+func TestThemeServiceExportImportRoundTrip(t *testing.T) {
+	svc := newThemeServiceForTest(t)
+
+	original, err := svc.Create(ThemePackInput{
+		Slug:  "brand",
+		Name:  "Brand",
+		Light: models.ThemePalette{Primary: "#112233"},
+		Email: models.ThemeEmailBranding{FooterHTML: "<p>footer</p>"},
+	}, 1)
+	if err != nil {
+		t.Fatalf("create theme: %v", err)
+	}
+	export, err := svc.Export(original.ID)
+	if err != nil {
+		t.Fatalf("export theme: %v", err)
+	}
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("marshal export: %v", err)
+	}
+
+	requireProductBizErr(t, func() error {
+		_, err := svc.Import(data, false, 1)
+		return err
+	}(), "theme.slugAlreadyExists")
+
+	export.Name = "Brand v2"
+	data, _ = json.Marshal(export)
+	updated, err := svc.Import(data, true, 1)
+	if err != nil {
+		t.Fatalf("import with overwrite: %v", err)
+	}
+	if updated.ID != original.ID || updated.Name != "Brand v2" || updated.Email.FooterHTML != "<p>footer</p>" {
+		t.Fatalf("expected overwrite of existing theme, got %+v", updated)
+	}
+
+	requireProductBizErr(t, func() error {
+		_, err := svc.Import([]byte(`{"format":"other","version":1}`), false, 1)
+		return err
+	}(), "theme.importInvalid")
+	requireProductBizErr(t, func() error {
+		_, err := svc.Import([]byte(`{"format":"auralogic-theme","version":99,"slug":"x","name":"X"}`), false, 1)
+		return err
+	}(), "theme.importVersionUnsupported")
+}
+
+func TestApplyEmailBrandingWrapsBody(t *testing.T) {
+	content := applyEmailBranding(`<html><BODY class="x"><p>hi</p></body></html>`, "<header>H</header>", "<footer>F</footer>")
+	expected := `<html><BODY class="x"><header>H</header><p>hi</p><footer>F</footer></body></html>`
+	if content != expected {
+		t.Fatalf("unexpected branding result:\n%s", content)
+	}
+	if plain := applyEmailBranding("plain", "H", "F"); plain != "HplainF" {
+		t.Fatalf("unexpected plain branding result: %s", plain)
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"sync"
@@ -18,8 +19,7 @@ type TicketAttachmentCleanupService struct {
 	cfg           *config.Config
 	lifecycleMu   sync.Mutex
 	running       bool
-	stopChan      chan struct{}
-	doneChan      chan struct{}
+	scheduler     *BackgroundScheduler
 	checkInterval time.Duration
 }
 
@@ -35,16 +35,10 @@ func NewTicketAttachmentCleanupService(db *gorm.DB, cfg *config.Config) *TicketA
 // Start 启动清理服务
 func (s *TicketAttachmentCleanupService) Start() {
 	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
 	if s.running {
-		s.lifecycleMu.Unlock()
 		return
 	}
-	stopChan := make(chan struct{})
-	doneChan := make(chan struct{})
-	s.stopChan = stopChan
-	s.doneChan = doneChan
-	s.running = true
-	s.lifecycleMu.Unlock()
 
 	retentionDays := 0
 	if s.cfg.Ticket.Attachment != nil {
@@ -56,47 +50,37 @@ func (s *TicketAttachmentCleanupService) Start() {
 		"check_interval": s.checkInterval.String(),
 	})
 
-	go func() {
-		defer close(doneChan)
-		runBackgroundServiceWithStopChan("ticket_attachment_cleanup.cleanupLoop", stopChan, s.cleanupLoop)
-	}()
+	scheduler := backgroundSchedulerForService()
+	if err := scheduler.Register(BackgroundJob{
+		Name:       "ticket_attachment_cleanup",
+		Interval:   s.checkInterval,
+		RunOnStart: true,
+		LockTTL:    s.checkInterval,
+		Jitter:     s.checkInterval / 10,
+		Run: func() error {
+			s.cleanExpiredAttachments()
+			return nil
+		},
+	}); err != nil {
+		log.Printf("[TicketAttachmentCleanup] Failed to register background job: %v", err)
+		return
+	}
+	s.scheduler = scheduler
+	s.running = true
 }
 
 // Stop 停止清理服务
 func (s *TicketAttachmentCleanupService) Stop() {
 	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
 	if !s.running {
-		s.lifecycleMu.Unlock()
 		return
 	}
-	stopChan := s.stopChan
-	doneChan := s.doneChan
-	s.stopChan = nil
-	s.doneChan = nil
 	s.running = false
 
 	logger.LogSystemOperation(s.db, "ticket_attachment_cleanup_stop", "system", nil, nil)
-	close(stopChan)
-	<-doneChan
-	s.lifecycleMu.Unlock()
-}
-
-// cleanupLoop 清理循环
-func (s *TicketAttachmentCleanupService) cleanupLoop(stopChan <-chan struct{}) {
-	// 启动时执行一次
-	s.cleanExpiredAttachments()
-
-	ticker := time.NewTicker(s.checkInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-stopChan:
-			return
-		case <-ticker.C:
-			s.cleanExpiredAttachments()
-		}
-	}
+	s.scheduler.Remove("ticket_attachment_cleanup")
+	s.scheduler = nil
 }
 
 // cleanExpiredAttachments 清理过期附件
@@ -145,6 +129,11 @@ func (s *TicketAttachmentCleanupService) cleanExpiredAttachments() {
 	// 清理空的日期目录
 	s.cleanEmptyDirs(ticketsDir)
 
+	// 文件已删除的附件不再需要归属记录
+	if _, err := NewTicketAttachmentService(s.db).CleanupBefore(cutoff); err != nil {
+		fmt.Printf("Error cleaning ticket attachment records: %v\n", err)
+	}
+
 	if deletedCount > 0 {
 		logger.LogSystemOperation(s.db, "ticket_attachment_cleanup", "system", nil, map[string]interface{}{
 			"deleted_count":  deletedCount,
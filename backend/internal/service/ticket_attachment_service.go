@@ -0,0 +1,192 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+// ticketAttachmentURLPattern 匹配消息中的工单附件链接（可带域名和旧的签名参数）
+var ticketAttachmentURLPattern = regexp.MustCompile(`(https?://[^\s()<>"'\[\]]*?)?/uploads/tickets/([A-Za-z0-9][A-Za-z0-9._/-]*)(\?[^\s()<>"'\[\]]*)?`)
+
+// TicketAttachmentService 工单附件归属与签名链接
+type TicketAttachmentService struct {
+	db *gorm.DB
+}
+
+func NewTicketAttachmentService(db *gorm.DB) *TicketAttachmentService {
+	return &TicketAttachmentService{db: db}
+}
+
+// NormalizeTicketAttachmentPath 规范化 uploads/tickets 下的相对路径，非法路径返回空
+func NormalizeTicketAttachmentPath(raw string) string {
+	cleaned := path.Clean("/" + strings.TrimSpace(raw))
+	cleaned = strings.TrimPrefix(cleaned, "/")
+	if cleaned == "" || cleaned == "." || strings.HasPrefix(cleaned, "..") {
+		return ""
+	}
+	return cleaned
+}
+
+// Register 记录附件所属工单
+func (s *TicketAttachmentService) Register(attachment *models.TicketAttachment) error {
+	attachment.Path = NormalizeTicketAttachmentPath(attachment.Path)
+	return s.db.Create(attachment).Error
+}
+
+func ticketAttachmentSignature(secret, relPath string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("ticket-attachment\x00" + relPath + "\x00" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func ticketAttachmentURLTTL(cfg *config.Config) time.Duration {
+	minutes := 60
+	if cfg != nil && cfg.Ticket.Attachment != nil && cfg.Ticket.Attachment.SignedURLTTL > 0 {
+		minutes = cfg.Ticket.Attachment.SignedURLTTL
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// SignedTicketAttachmentURL 生成带过期时间的附件链接
+// 过期时间按有效期对齐，同一时间窗口内链接不变，便于浏览器缓存
+func SignedTicketAttachmentURL(relPath string, now time.Time) string {
+	cfg := config.GetConfig()
+	relPath = NormalizeTicketAttachmentPath(relPath)
+	ttl := ticketAttachmentURLTTL(cfg)
+	expires := now.Truncate(ttl).Add(2 * ttl).Unix()
+	baseURL := ""
+	secret := ""
+	if cfg != nil {
+		baseURL = strings.TrimRight(cfg.App.URL, "/")
+		secret = cfg.JWT.Secret
+	}
+	return baseURL + "/uploads/tickets/" + relPath +
+		"?expires=" + strconv.FormatInt(expires, 10) +
+		"&sig=" + ticketAttachmentSignature(secret, relPath, expires)
+}
+
+// VerifyTicketAttachmentSignature 校验附件签名链接
+func VerifyTicketAttachmentSignature(relPath, expiresRaw, sig string, now time.Time) bool {
+	cfg := config.GetConfig()
+	relPath = NormalizeTicketAttachmentPath(relPath)
+	if cfg == nil || relPath == "" || sig == "" {
+		return false
+	}
+	expires, err := strconv.ParseInt(expiresRaw, 10, 64)
+	if err != nil || now.Unix() > expires {
+		return false
+	}
+	// 拒绝超出最长有效期的链接（有效期调短后旧链接随之失效）
+	if time.Unix(expires, 0).Sub(now) > 2*ticketAttachmentURLTTL(cfg) {
+		return false
+	}
+	expected := ticketAttachmentSignature(cfg.JWT.Secret, relPath, expires)
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
+
+// StripTicketAttachmentSignatures 去掉附件链接上的签名参数，消息中只保存原始链接
+func StripTicketAttachmentSignatures(content string) string {
+	if !strings.Contains(content, "/uploads/tickets/") {
+		return content
+	}
+	return ticketAttachmentURLPattern.ReplaceAllString(content, "$1/uploads/tickets/$2")
+}
+
+// ExtractTicketAttachmentPaths 提取内容中引用的附件路径
+func ExtractTicketAttachmentPaths(content string) []string {
+	if !strings.Contains(content, "/uploads/tickets/") {
+		return nil
+	}
+	matches := ticketAttachmentURLPattern.FindAllStringSubmatch(content, -1)
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if relPath := NormalizeTicketAttachmentPath(match[2]); relPath != "" {
+			paths = append(paths, relPath)
+		}
+	}
+	return paths
+}
+
+// ownedPaths 返回属于该工单的附件路径
+func (s *TicketAttachmentService) ownedPaths(ticketID uint, contents []string) map[string]bool {
+	candidates := make([]string, 0)
+	for _, content := range contents {
+		candidates = append(candidates, ExtractTicketAttachmentPaths(content)...)
+	}
+	owned := make(map[string]bool, len(candidates))
+	if len(candidates) == 0 {
+		return owned
+	}
+	var paths []string
+	if err := s.db.Model(&models.TicketAttachment{}).
+		Where("ticket_id = ? AND path IN ?", ticketID, candidates).
+		Pluck("path", &paths).Error; err != nil {
+		return owned
+	}
+	for _, relPath := range paths {
+		owned[relPath] = true
+	}
+	return owned
+}
+
+// signTicketAttachmentContent 为属于该工单的附件链接签名，其他工单的附件链接保持原样（无法访问）
+func signTicketAttachmentContent(content string, owned map[string]bool, now time.Time) string {
+	if len(owned) == 0 || !strings.Contains(content, "/uploads/tickets/") {
+		return content
+	}
+	return ticketAttachmentURLPattern.ReplaceAllStringFunc(content, func(match string) string {
+		parts := ticketAttachmentURLPattern.FindStringSubmatch(match)
+		relPath := NormalizeTicketAttachmentPath(parts[2])
+		if !owned[relPath] {
+			return match
+		}
+		signed := SignedTicketAttachmentURL(relPath, now)
+		if parts[1] == "" {
+			// 保持相对链接
+			signed = signed[strings.Index(signed, "/uploads/tickets/"):]
+		}
+		return signed
+	})
+}
+
+// SignMessages 为工单消息中的附件生成签名链接（调用方需已校验访问权限）
+func (s *TicketAttachmentService) SignMessages(ticketID uint, messages []models.TicketMessage) {
+	contents := make([]string, len(messages))
+	for i := range messages {
+		contents[i] = messages[i].Content
+	}
+	owned := s.ownedPaths(ticketID, contents)
+	now := time.Now()
+	for i := range messages {
+		messages[i].Content = signTicketAttachmentContent(messages[i].Content, owned, now)
+	}
+}
+
+// SignedMessage 返回附件链接已签名的消息副本，不修改原消息
+func (s *TicketAttachmentService) SignedMessage(message *models.TicketMessage) *models.TicketMessage {
+	signed := []models.TicketMessage{*message}
+	s.SignMessages(message.TicketID, signed)
+	return &signed[0]
+}
+
+// SignTicket 为工单正文中的附件生成签名链接
+func (s *TicketAttachmentService) SignTicket(ticket *models.Ticket) {
+	owned := s.ownedPaths(ticket.ID, []string{ticket.Content})
+	ticket.Content = signTicketAttachmentContent(ticket.Content, owned, time.Now())
+}
+
+// CleanupBefore 删除早于 cutoff 的附件记录（文件已按保留期清理）
+func (s *TicketAttachmentService) CleanupBefore(cutoff time.Time) (int64, error) {
+	result := s.db.Where("created_at < ?", cutoff).Delete(&models.TicketAttachment{})
+	return result.RowsAffected, result.Error
+}
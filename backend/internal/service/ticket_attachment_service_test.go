@@ -0,0 +1,105 @@
+package service
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+)
+
+func loadTicketAttachmentTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	configJSON := `{
+  "app": {"name": "test", "port": 8080, "url": "https://shop.example.com"},
+  "database": {"driver": "sqlite", "name": "test.db"},
+  "jwt": {"secret": "12345678901234567890123456789012"},
+  "security": {"login": {}, "password_policy": {"min_length": 8}, "captcha": {}}
+}`
+	if err := os.WriteFile(configPath, []byte(configJSON), 0o644); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	return cfg
+}
+
+func signedURLQuery(t *testing.T, signedURL string) (string, url.Values) {
+	t.Helper()
+	parsed, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("parse signed url: %v", err)
+	}
+	return strings.TrimPrefix(parsed.Path, "/uploads/tickets/"), parsed.Query()
+}
+
+func TestTicketAttachmentSignedURLs(t *testing.T) {
+	cfg := loadTicketAttachmentTestConfig(t)
+	if cfg.Ticket.Attachment == nil || cfg.Ticket.Attachment.SignedURLTTL != 60 {
+		t.Fatalf("expected default signed url ttl, got %+v", cfg.Ticket.Attachment)
+	}
+	db := openConcurrentServiceTestDB(t, &models.TicketAttachment{})
+	svc := NewTicketAttachmentService(db)
+
+	const ownPath = "2026/01/02/own.png"
+	const otherPath = "2026/01/02/other.png"
+	if err := svc.Register(&models.TicketAttachment{TicketID: 1, Path: ownPath, UploaderType: "user", UploaderID: 7}); err != nil {
+		t.Fatalf("register attachment: %v", err)
+	}
+	if err := svc.Register(&models.TicketAttachment{TicketID: 2, Path: "/" + otherPath}); err != nil {
+		t.Fatalf("register attachment: %v", err)
+	}
+
+	messages := []models.TicketMessage{
+		{TicketID: 1, Content: "![a](https://shop.example.com/uploads/tickets/" + ownPath + ")"},
+		{TicketID: 1, Content: "[voice](/uploads/tickets/" + ownPath + "?expires=1&sig=stale) ![b](/uploads/tickets/" + otherPath + ")"},
+	}
+	svc.SignMessages(1, messages)
+
+	signedURL := strings.TrimSuffix(strings.TrimPrefix(messages[0].Content, "![a]("), ")")
+	if !strings.HasPrefix(signedURL, "https://shop.example.com/uploads/tickets/"+ownPath+"?expires=") {
+		t.Fatalf("expected absolute signed url, got %q", messages[0].Content)
+	}
+	relPath, query := signedURLQuery(t, signedURL)
+	now := time.Now()
+	if !VerifyTicketAttachmentSignature(relPath, query.Get("expires"), query.Get("sig"), now) {
+		t.Fatal("expected signed url to verify")
+	}
+	if !strings.Contains(messages[1].Content, "[voice](/uploads/tickets/"+ownPath+"?expires=") || strings.Contains(messages[1].Content, "sig=stale") {
+		t.Fatalf("expected relative link to be re-signed, got %q", messages[1].Content)
+	}
+	// 其他工单的附件不会被签名
+	if !strings.Contains(messages[1].Content, "![b](/uploads/tickets/"+otherPath+")") {
+		t.Fatalf("expected attachment of another ticket to stay unsigned, got %q", messages[1].Content)
+	}
+
+	for name, ok := range map[string]bool{
+		"other path":  VerifyTicketAttachmentSignature(otherPath, query.Get("expires"), query.Get("sig"), now),
+		"tampered":    VerifyTicketAttachmentSignature(relPath, query.Get("expires"), query.Get("sig")+"x", now),
+		"expired":     VerifyTicketAttachmentSignature(relPath, query.Get("expires"), query.Get("sig"), now.Add(3*time.Hour)),
+		"traversal":   VerifyTicketAttachmentSignature("../config.json", query.Get("expires"), query.Get("sig"), now),
+		"missing sig": VerifyTicketAttachmentSignature(relPath, query.Get("expires"), "", now),
+	} {
+		if ok {
+			t.Fatalf("expected %s to be rejected", name)
+		}
+	}
+
+	stripped := StripTicketAttachmentSignatures(messages[1].Content)
+	if stripped != "[voice](/uploads/tickets/"+ownPath+") ![b](/uploads/tickets/"+otherPath+")" {
+		t.Fatalf("expected signatures to be stripped, got %q", stripped)
+	}
+
+	signed := svc.SignedMessage(&models.TicketMessage{TicketID: 2, Content: "/uploads/tickets/" + otherPath})
+	if !strings.Contains(signed.Content, "sig=") {
+		t.Fatalf("expected owning ticket to get a signed link, got %q", signed.Content)
+	}
+}
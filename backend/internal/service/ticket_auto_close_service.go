@@ -21,8 +21,7 @@ type TicketAutoCloseService struct {
 	pluginManager *PluginManagerService
 	lifecycleMu   sync.Mutex
 	running       bool
-	stopChan      chan struct{}
-	doneChan      chan struct{}
+	scheduler     *BackgroundScheduler
 	checkInterval time.Duration
 }
 
@@ -94,63 +93,47 @@ func ticketAutoCloseHookValueToOptionalString(value interface{}) (string, error)
 // Start 启动自动关闭服务
 func (s *TicketAutoCloseService) Start() {
 	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
 	if s.running {
-		s.lifecycleMu.Unlock()
 		return
 	}
-	stopChan := make(chan struct{})
-	doneChan := make(chan struct{})
-	s.stopChan = stopChan
-	s.doneChan = doneChan
-	s.running = true
-	s.lifecycleMu.Unlock()
 
 	logger.LogSystemOperation(s.db, "ticket_auto_close_start", "system", nil, map[string]interface{}{
 		"auto_close_hours": s.cfg.Ticket.AutoCloseHours,
 		"check_interval":   s.checkInterval.String(),
 	})
 
-	go func() {
-		defer close(doneChan)
-		runBackgroundServiceWithStopChan("ticket_auto_close.closeLoop", stopChan, s.closeLoop)
-	}()
+	scheduler := backgroundSchedulerForService()
+	if err := scheduler.Register(BackgroundJob{
+		Name:       "ticket_auto_close",
+		Interval:   s.checkInterval,
+		RunOnStart: true,
+		LockTTL:    s.checkInterval,
+		Jitter:     s.checkInterval / 10,
+		Run: func() error {
+			s.closeInactiveTickets()
+			return nil
+		},
+	}); err != nil {
+		log.Printf("[TicketAutoClose] Failed to register background job: %v", err)
+		return
+	}
+	s.scheduler = scheduler
+	s.running = true
 }
 
 // Stop 停止自动关闭服务
 func (s *TicketAutoCloseService) Stop() {
 	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
 	if !s.running {
-		s.lifecycleMu.Unlock()
 		return
 	}
-	stopChan := s.stopChan
-	doneChan := s.doneChan
-	s.stopChan = nil
-	s.doneChan = nil
 	s.running = false
 
 	logger.LogSystemOperation(s.db, "ticket_auto_close_stop", "system", nil, nil)
-	close(stopChan)
-	<-doneChan
-	s.lifecycleMu.Unlock()
-}
-
-// closeLoop 自动关闭循环
-func (s *TicketAutoCloseService) closeLoop(stopChan <-chan struct{}) {
-	// 启动时执行一次
-	s.closeInactiveTickets()
-
-	ticker := time.NewTicker(s.checkInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-stopChan:
-			return
-		case <-ticker.C:
-			s.closeInactiveTickets()
-		}
-	}
+	s.scheduler.Remove("ticket_auto_close")
+	s.scheduler = nil
 }
 
 // closeInactiveTickets 关闭超时无回复的工单
@@ -0,0 +1,520 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/dbutil"
+	"auralogic/internal/pkg/ticketbiz"
+	"gorm.io/gorm"
+)
+
+const (
+	maxTicketTags                 = 20
+	maxTicketTagLength            = 50
+	maxTicketAutomationConditions = 20
+	maxTicketAutomationActions    = 10
+)
+
+var ErrTicketAutomationRuleNotFound = bizerr.New("ticketAutomation.ruleNotFound", "Automation rule not found")
+
+// TicketTagCount 标签聚合统计
+type TicketTagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// TicketAutomationRuleInput 创建/更新规则参数
+type TicketAutomationRuleInput struct {
+	Name           string                             `json:"name"`
+	Description    string                             `json:"description"`
+	Trigger        models.TicketAutomationTrigger     `json:"trigger"`
+	Enabled        bool                               `json:"enabled"`
+	MatchType      string                             `json:"match_type"`
+	Conditions     []models.TicketAutomationCondition `json:"conditions"`
+	Actions        []models.TicketAutomationAction    `json:"actions"`
+	Priority       int                                `json:"priority"`
+	StopProcessing bool                               `json:"stop_processing"`
+}
+
+// NormalizeTicketTags 去除首尾空白并统一小写，按出现顺序去重；空字符串忽略
+func NormalizeTicketTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, raw := range tags {
+		tag, err := normalizeTicketTag(raw)
+		if err != nil {
+			return nil, err
+		}
+		if tag == "" || containsString(normalized, tag) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTicketTags {
+		return nil, bizerr.Newf("ticket.tooManyTags", "A ticket can have at most %d tags", maxTicketTags).
+			WithParams(map[string]interface{}{"max": maxTicketTags})
+	}
+	return normalized, nil
+}
+
+func normalizeTicketTag(raw string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	if len([]rune(tag)) > maxTicketTagLength || strings.Contains(tag, ",") {
+		return "", bizerr.Newf("ticket.tagInvalid", "Tags must be at most %d characters and cannot contain commas", maxTicketTagLength).
+			WithParams(map[string]interface{}{"max": maxTicketTagLength})
+	}
+	return tag, nil
+}
+
+// FilterTicketsByTags 只保留同时带有全部标签的工单（tags 为 JSON 数组文本列）
+func FilterTicketsByTags(query *gorm.DB, tags []string) *gorm.DB {
+	for _, tag := range tags {
+		encoded, _ := json.Marshal(strings.ToLower(strings.TrimSpace(tag)))
+		query = query.Where("tickets.tags LIKE ?", "%"+string(encoded)+"%")
+	}
+	return query
+}
+
+// CountTicketTags 统计各标签下的工单数量，按数量降序；limit <= 0 时返回全部
+func CountTicketTags(db *gorm.DB, limit int) ([]TicketTagCount, error) {
+	counts := make(map[string]int64)
+	var batch []models.Ticket
+	err := db.Model(&models.Ticket{}).
+		Select("id", "tags").
+		Where("tags IS NOT NULL AND tags NOT IN ('', '[]', 'null')").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for _, ticket := range batch {
+				for _, tag := range ticket.Tags {
+					counts[tag]++
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]TicketTagCount, 0, len(counts))
+	for tag, count := range counts {
+		items = append(items, TicketTagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Tag < items[j].Tag
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// TicketAutomationService 工单自动化规则：订阅工单事件，匹配条件后打标签、调整优先级
+type TicketAutomationService struct {
+	db *gorm.DB
+}
+
+func NewTicketAutomationService(db *gorm.DB) *TicketAutomationService {
+	return &TicketAutomationService{db: db}
+}
+
+// TicketAutomationTriggers 支持的触发事件
+func TicketAutomationTriggers() []models.TicketAutomationTrigger {
+	return []models.TicketAutomationTrigger{
+		models.TicketAutomationTriggerCreated,
+		models.TicketAutomationTriggerUserReplied,
+	}
+}
+
+// TicketAutomationActionTypes 支持的动作类型
+func TicketAutomationActionTypes() []models.TicketAutomationActionType {
+	return []models.TicketAutomationActionType{
+		models.TicketAutomationActionAddTag,
+		models.TicketAutomationActionRemoveTag,
+		models.TicketAutomationActionSetPriority,
+	}
+}
+
+// content 为触发事件对应的正文：创建时是工单内容，回复时是本次回复内容
+var ticketAutomationFields = []struct {
+	name string
+	kind orderAutomationFieldKind
+}{
+	{"subject", orderAutomationFieldString},
+	{"content", orderAutomationFieldString},
+	{"category", orderAutomationFieldString},
+	{"priority", orderAutomationFieldString},
+	{"status", orderAutomationFieldString},
+	{"user_email", orderAutomationFieldString},
+	{"tags", orderAutomationFieldList},
+}
+
+// TicketAutomationFields 条件字段及各自支持的运算符
+func TicketAutomationFields() []OrderAutomationFieldMeta {
+	fields := make([]OrderAutomationFieldMeta, 0, len(ticketAutomationFields))
+	for _, field := range ticketAutomationFields {
+		fields = append(fields, OrderAutomationFieldMeta{
+			Field:     field.name,
+			Kind:      string(field.kind),
+			Operators: orderAutomationOperatorsByKind[field.kind],
+		})
+	}
+	return fields
+}
+
+func lookupTicketAutomationField(name string) (orderAutomationFieldKind, bool) {
+	for _, field := range ticketAutomationFields {
+		if field.name == name {
+			return field.kind, true
+		}
+	}
+	return "", false
+}
+
+func ticketAutomationFieldValue(ticket *models.Ticket, content string, field string) interface{} {
+	switch field {
+	case "subject":
+		return ticket.Subject
+	case "content":
+		return content
+	case "category":
+		return ticket.Category
+	case "priority":
+		return string(ticket.Priority)
+	case "status":
+		return string(ticket.Status)
+	case "user_email":
+		if ticket.User != nil {
+			return ticket.User.Email
+		}
+		return ""
+	case "tags":
+		return append([]string(nil), ticket.Tags...)
+	}
+	return nil
+}
+
+// matchTicketAutomationRule 按 match_type 组合条件；没有条件时总是命中
+func matchTicketAutomationRule(rule *models.TicketAutomationRule, ticket *models.Ticket, content string) bool {
+	if len(rule.Conditions) == 0 {
+		return true
+	}
+	matchAny := rule.MatchType == "any"
+	for _, condition := range rule.Conditions {
+		matched := matchAutomationValue(ticketAutomationFieldValue(ticket, content, condition.Field), condition.Operator, condition.Value)
+		if matchAny && matched {
+			return true
+		}
+		if !matchAny && !matched {
+			return false
+		}
+	}
+	return !matchAny
+}
+
+func (s *TicketAutomationService) validateInput(input *TicketAutomationRuleInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Description = strings.TrimSpace(input.Description)
+	input.MatchType = strings.ToLower(strings.TrimSpace(input.MatchType))
+	if input.Name == "" {
+		return bizerr.New("ticketAutomation.nameRequired", "Rule name is required")
+	}
+	knownTrigger := false
+	for _, trigger := range TicketAutomationTriggers() {
+		if trigger == input.Trigger {
+			knownTrigger = true
+			break
+		}
+	}
+	if !knownTrigger {
+		return bizerr.Newf("ticketAutomation.triggerInvalid", "Invalid trigger: %s", input.Trigger).
+			WithParams(map[string]interface{}{"trigger": input.Trigger})
+	}
+	if input.MatchType == "" {
+		input.MatchType = "all"
+	}
+	if input.MatchType != "all" && input.MatchType != "any" {
+		return bizerr.New("ticketAutomation.matchTypeInvalid", "Match type must be all or any")
+	}
+	if len(input.Conditions) > maxTicketAutomationConditions {
+		return bizerr.Newf("ticketAutomation.tooManyConditions", "At most %d conditions are allowed", maxTicketAutomationConditions).
+			WithParams(map[string]interface{}{"max": maxTicketAutomationConditions})
+	}
+	for i := range input.Conditions {
+		condition := &input.Conditions[i]
+		condition.Field = strings.TrimSpace(condition.Field)
+		condition.Operator = strings.ToLower(strings.TrimSpace(condition.Operator))
+		kind, ok := lookupTicketAutomationField(condition.Field)
+		if !ok {
+			return newTicketAutomationConditionError(condition, "unknown field")
+		}
+		value, reason := normalizeAutomationConditionValue(kind, condition.Operator, condition.Value)
+		if reason != "" {
+			return newTicketAutomationConditionError(condition, reason)
+		}
+		condition.Value = value
+	}
+	if len(input.Actions) == 0 {
+		return bizerr.New("ticketAutomation.actionsRequired", "At least one action is required")
+	}
+	if len(input.Actions) > maxTicketAutomationActions {
+		return bizerr.Newf("ticketAutomation.tooManyActions", "At most %d actions are allowed", maxTicketAutomationActions).
+			WithParams(map[string]interface{}{"max": maxTicketAutomationActions})
+	}
+	for i := range input.Actions {
+		if err := validateTicketAutomationAction(&input.Actions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateTicketAutomationAction(action *models.TicketAutomationAction) error {
+	switch action.Type {
+	case models.TicketAutomationActionAddTag, models.TicketAutomationActionRemoveTag:
+		tag, err := normalizeTicketTag(action.Tag)
+		if err != nil || tag == "" {
+			return newTicketAutomationActionError(action, "tag is required (max 50 characters, no commas)")
+		}
+		action.Tag = tag
+		action.Priority = ""
+	case models.TicketAutomationActionSetPriority:
+		priority, ok := ticketbiz.ParsePriority(string(action.Priority))
+		if !ok {
+			return newTicketAutomationActionError(action, "priority must be low, normal, high or urgent")
+		}
+		action.Priority = priority
+		action.Tag = ""
+	default:
+		return newTicketAutomationActionError(action, "unknown action type")
+	}
+	return nil
+}
+
+func newTicketAutomationConditionError(condition *models.TicketAutomationCondition, reason string) error {
+	return bizerr.Newf("ticketAutomation.conditionInvalid", "Invalid condition on %s: %s", condition.Field, reason).
+		WithParams(map[string]interface{}{"field": condition.Field, "reason": reason})
+}
+
+func newTicketAutomationActionError(action *models.TicketAutomationAction, reason string) error {
+	return bizerr.Newf("ticketAutomation.actionInvalid", "Invalid %s action: %s", action.Type, reason).
+		WithParams(map[string]interface{}{"type": action.Type, "reason": reason})
+}
+
+func applyTicketAutomationRuleInput(rule *models.TicketAutomationRule, input TicketAutomationRuleInput) {
+	rule.Name = input.Name
+	rule.Description = input.Description
+	rule.Trigger = input.Trigger
+	rule.Enabled = input.Enabled
+	rule.MatchType = input.MatchType
+	rule.Conditions = input.Conditions
+	if rule.Conditions == nil {
+		rule.Conditions = []models.TicketAutomationCondition{}
+	}
+	rule.Actions = input.Actions
+	rule.Priority = input.Priority
+	rule.StopProcessing = input.StopProcessing
+}
+
+// ListRules 规则列表，按执行顺序排列
+func (s *TicketAutomationService) ListRules(trigger string) ([]models.TicketAutomationRule, error) {
+	query := s.db.Model(&models.TicketAutomationRule{})
+	if trigger = strings.TrimSpace(trigger); trigger != "" {
+		query = query.Where("trigger_event = ?", trigger)
+	}
+	var rules []models.TicketAutomationRule
+	err := query.Order("priority DESC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (s *TicketAutomationService) GetRule(id uint) (*models.TicketAutomationRule, error) {
+	var rule models.TicketAutomationRule
+	if err := s.db.First(&rule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketAutomationRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *TicketAutomationService) CreateRule(input TicketAutomationRuleInput, createdBy *uint) (*models.TicketAutomationRule, error) {
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	rule := models.TicketAutomationRule{CreatedBy: createdBy}
+	applyTicketAutomationRuleInput(&rule, input)
+	if err := s.db.Create(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *TicketAutomationService) UpdateRule(id uint, input TicketAutomationRuleInput) (*models.TicketAutomationRule, error) {
+	rule, err := s.GetRule(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	applyTicketAutomationRuleInput(rule, input)
+	if err := s.db.Model(rule).
+		Select("name", "description", "trigger_event", "enabled", "match_type", "conditions", "actions", "priority", "stop_processing").
+		Updates(rule).Error; err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *TicketAutomationService) DeleteRule(id uint) error {
+	result := s.db.Delete(&models.TicketAutomationRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTicketAutomationRuleNotFound
+	}
+	return nil
+}
+
+// ObserveHook 将工单 Hook 事件映射为自动化触发事件，异步执行
+func (s *TicketAutomationService) ObserveHook(hook string, payload map[string]interface{}) {
+	var trigger models.TicketAutomationTrigger
+	content := ""
+	switch hook {
+	case "ticket.create.after":
+		trigger = models.TicketAutomationTriggerCreated
+	case "ticket.message.user.after":
+		trigger = models.TicketAutomationTriggerUserReplied
+		content, _ = payload["content"].(string)
+	default:
+		return
+	}
+	ticketID, ok := orderAutomationPayloadOrderID(payload["ticket_id"])
+	if !ok {
+		return
+	}
+
+	go func() {
+		defer recoverBackgroundServicePanic("ticket-automation")
+		if _, err := s.RunTrigger(trigger, ticketID, content); err != nil {
+			log.Printf("ticket automation failed: trigger=%s ticket=%d err=%v", trigger, ticketID, err)
+		}
+	}()
+}
+
+// RunTrigger 按优先级依次执行该事件下已启用且命中的规则，返回命中的规则数；
+// content 为空时使用工单内容
+func (s *TicketAutomationService) RunTrigger(trigger models.TicketAutomationTrigger, ticketID uint, content string) (int, error) {
+	var rules []models.TicketAutomationRule
+	if err := s.db.Where("trigger_event = ? AND enabled = ?", trigger, true).Order("priority DESC, id ASC").Find(&rules).Error; err != nil {
+		return 0, err
+	}
+	if len(rules) == 0 {
+		return 0, nil
+	}
+	var ticket models.Ticket
+	if err := s.db.Preload("User").First(&ticket, ticketID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if content == "" {
+		content = ticket.Content
+	}
+
+	matched := 0
+	for i := range rules {
+		rule := &rules[i]
+		if !matchTicketAutomationRule(rule, &ticket, content) {
+			continue
+		}
+		matched++
+		for _, action := range rule.Actions {
+			if err := s.executeAction(&ticket, action); err != nil {
+				log.Printf("ticket automation action failed: rule=%d ticket=%s action=%s err=%v", rule.ID, ticket.TicketNo, action.Type, err)
+			}
+		}
+		s.db.Model(&models.TicketAutomationRule{}).Where("id = ?", rule.ID).UpdateColumns(map[string]interface{}{
+			"run_count":   gorm.Expr("run_count + 1"),
+			"last_run_at": models.NowFunc(),
+		})
+		if rule.StopProcessing {
+			break
+		}
+	}
+	return matched, nil
+}
+
+// executeAction 执行单个动作，成功的修改同步到内存中的 ticket 供后续规则判断
+func (s *TicketAutomationService) executeAction(ticket *models.Ticket, action models.TicketAutomationAction) error {
+	switch action.Type {
+	case models.TicketAutomationActionAddTag, models.TicketAutomationActionRemoveTag:
+		tags, err := UpdateTicketTag(s.db, ticket.ID, action.Tag, action.Type == models.TicketAutomationActionAddTag)
+		if err != nil {
+			return err
+		}
+		ticket.Tags = tags
+		return nil
+	case models.TicketAutomationActionSetPriority:
+		if err := s.db.Model(&models.Ticket{}).Where("id = ?", ticket.ID).Update("priority", action.Priority).Error; err != nil {
+			return err
+		}
+		ticket.Priority = action.Priority
+		return nil
+	}
+	return fmt.Errorf("unknown action type %q", action.Type)
+}
+
+// UpdateTicketTag 加锁后添加或移除单个标签，返回更新后的标签列表
+func UpdateTicketTag(db *gorm.DB, ticketID uint, tag string, add bool) ([]string, error) {
+	tag, err := normalizeTicketTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := dbutil.LockForUpdate(tx, &models.Ticket{}, "id = ?", ticketID); err != nil {
+			return err
+		}
+		var current models.Ticket
+		if err := tx.Select("id", "tags").First(&current, ticketID).Error; err != nil {
+			return err
+		}
+		tags = make([]string, 0, len(current.Tags)+1)
+		found := false
+		for _, existing := range current.Tags {
+			if existing == tag {
+				found = true
+				if !add {
+					continue
+				}
+			}
+			tags = append(tags, existing)
+		}
+		if add && !found {
+			if len(tags) >= maxTicketTags {
+				return bizerr.Newf("ticket.tooManyTags", "A ticket can have at most %d tags", maxTicketTags).
+					WithParams(map[string]interface{}{"max": maxTicketTags})
+			}
+			tags = append(tags, tag)
+		}
+		if found == add {
+			return nil
+		}
+		return tx.Model(&current).Select("tags").Updates(&models.Ticket{Tags: tags}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
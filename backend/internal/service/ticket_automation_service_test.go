@@ -0,0 +1,131 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestTicketAutomationRunTriggerTagsMatchingTickets(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Ticket{}, &models.TicketAutomationRule{})
+	svc := NewTicketAutomationService(db)
+
+	if _, err := svc.CreateRule(TicketAutomationRuleInput{
+		Name:    "Bad tag",
+		Trigger: models.TicketAutomationTriggerCreated,
+		Actions: []models.TicketAutomationAction{{Type: models.TicketAutomationActionAddTag, Tag: "a,b"}},
+	}, nil); refundErrorKey(err) != "ticketAutomation.actionInvalid" {
+		t.Fatalf("tags with commas should be rejected, got %v", err)
+	}
+	if _, err := svc.CreateRule(TicketAutomationRuleInput{
+		Name:       "Bad field",
+		Trigger:    models.TicketAutomationTriggerCreated,
+		Conditions: []models.TicketAutomationCondition{{Field: "total_amount_minor", Operator: "gt", Value: 1}},
+		Actions:    []models.TicketAutomationAction{{Type: models.TicketAutomationActionAddTag, Tag: "x"}},
+	}, nil); refundErrorKey(err) != "ticketAutomation.conditionInvalid" {
+		t.Fatalf("order fields should be rejected, got %v", err)
+	}
+
+	refund, err := svc.CreateRule(TicketAutomationRuleInput{
+		Name:      "Refund requests",
+		Trigger:   models.TicketAutomationTriggerCreated,
+		Enabled:   true,
+		Priority:  10,
+		MatchType: "any",
+		Conditions: []models.TicketAutomationCondition{
+			{Field: "subject", Operator: "contains", Value: "refund"},
+			{Field: "content", Operator: "contains", Value: "money back"},
+		},
+		Actions: []models.TicketAutomationAction{
+			{Type: models.TicketAutomationActionAddTag, Tag: " Refund-Request "},
+			{Type: models.TicketAutomationActionSetPriority, Priority: "HIGH"},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("create rule failed: %v", err)
+	}
+	if refund.Actions[0].Tag != "refund-request" || refund.Actions[1].Priority != models.TicketPriorityHigh {
+		t.Fatalf("actions should be normalized, got %+v", refund.Actions)
+	}
+	// 依赖上一条规则添加的标签
+	if _, err := svc.CreateRule(TicketAutomationRuleInput{
+		Name:       "Escalate",
+		Trigger:    models.TicketAutomationTriggerCreated,
+		Enabled:    true,
+		Conditions: []models.TicketAutomationCondition{{Field: "tags", Operator: "contains", Value: "REFUND-REQUEST"}},
+		Actions:    []models.TicketAutomationAction{{Type: models.TicketAutomationActionAddTag, Tag: "finance"}},
+	}, nil); err != nil {
+		t.Fatalf("create rule failed: %v", err)
+	}
+	if _, err := svc.CreateRule(TicketAutomationRuleInput{
+		Name:       "Login on reply",
+		Trigger:    models.TicketAutomationTriggerUserReplied,
+		Enabled:    true,
+		Conditions: []models.TicketAutomationCondition{{Field: "content", Operator: "contains", Value: "cannot log in"}},
+		Actions: []models.TicketAutomationAction{
+			{Type: models.TicketAutomationActionAddTag, Tag: "login-issue"},
+			{Type: models.TicketAutomationActionRemoveTag, Tag: "finance"},
+		},
+	}, nil); err != nil {
+		t.Fatalf("create rule failed: %v", err)
+	}
+
+	user := models.User{UUID: "ticket-user", Email: "buyer@example.com", Role: "user", IsActive: true}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	ticket := models.Ticket{TicketNo: "T-1", UserID: user.ID, Subject: "Order question", Content: "I want my money back", Priority: models.TicketPriorityNormal, Status: models.TicketStatusOpen}
+	other := models.Ticket{TicketNo: "T-2", UserID: user.ID, Subject: "Shipping", Content: "Where is my parcel?", Priority: models.TicketPriorityNormal, Status: models.TicketStatusOpen}
+	for _, item := range []*models.Ticket{&ticket, &other} {
+		if err := db.Create(item).Error; err != nil {
+			t.Fatalf("create ticket: %v", err)
+		}
+	}
+
+	if matched, err := svc.RunTrigger(models.TicketAutomationTriggerCreated, ticket.ID, ""); err != nil || matched != 2 {
+		t.Fatalf("expected both creation rules to match, got %d, %v", matched, err)
+	}
+	if matched, _ := svc.RunTrigger(models.TicketAutomationTriggerCreated, other.ID, ""); matched != 0 {
+		t.Fatalf("unrelated ticket should not match, got %d", matched)
+	}
+	var stored models.Ticket
+	db.First(&stored, ticket.ID)
+	if len(stored.Tags) != 2 || stored.Tags[0] != "refund-request" || stored.Tags[1] != "finance" || stored.Priority != models.TicketPriorityHigh {
+		t.Fatalf("unexpected ticket after creation rules: tags=%v priority=%s", stored.Tags, stored.Priority)
+	}
+
+	if matched, _ := svc.RunTrigger(models.TicketAutomationTriggerUserReplied, ticket.ID, "I still cannot log in"); matched != 1 {
+		t.Fatalf("reply rule should match the reply content, got %d", matched)
+	}
+	db.First(&stored, ticket.ID)
+	if len(stored.Tags) != 2 || stored.Tags[0] != "refund-request" || stored.Tags[1] != "login-issue" {
+		t.Fatalf("unexpected tags after reply rule: %v", stored.Tags)
+	}
+
+	var tagged []models.Ticket
+	FilterTicketsByTags(db.Model(&models.Ticket{}), []string{"Login-Issue", "refund-request"}).Find(&tagged)
+	if len(tagged) != 1 || tagged[0].ID != ticket.ID {
+		t.Fatalf("tag filter should require all tags, got %+v", tagged)
+	}
+	if _, err := UpdateTicketTag(db, other.ID, "login-issue", true); err != nil {
+		t.Fatalf("add tag: %v", err)
+	}
+	counts, err := CountTicketTags(db, 0)
+	if err != nil || len(counts) != 2 || counts[0] != (TicketTagCount{Tag: "login-issue", Count: 2}) || counts[1] != (TicketTagCount{Tag: "refund-request", Count: 1}) {
+		t.Fatalf("unexpected tag counts: %+v, %v", counts, err)
+	}
+}
+
+func TestNormalizeTicketTags(t *testing.T) {
+	tags, err := NormalizeTicketTags([]string{" Refund-Request", "refund-request", "", "LOGIN-issue"})
+	if err != nil || len(tags) != 2 || tags[0] != "refund-request" || tags[1] != "login-issue" {
+		t.Fatalf("unexpected normalized tags: %v, %v", tags, err)
+	}
+	tooMany := make([]string, 0, maxTicketTags+1)
+	for i := 0; i <= maxTicketTags; i++ {
+		tooMany = append(tooMany, string(rune('a'+i)))
+	}
+	if _, err := NormalizeTicketTags(tooMany); refundErrorKey(err) != "ticket.tooManyTags" {
+		t.Fatalf("expected too many tags error, got %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+// sharedOrderShipmentActions 分享订单中展示给客服的发货相关操作
+var sharedOrderShipmentActions = []string{
+	"assign_tracking",
+	"deliver_virtual_stock",
+	"revoke_virtual_stock",
+	"complete",
+	"cancel",
+	"receive_return",
+}
+
+// SharedOrderVirtualStock 分享订单中的已发货卡密，内容始终脱敏
+type SharedOrderVirtualStock struct {
+	ID            uint                             `json:"id"`
+	Content       string                           `json:"content"`
+	Status        models.VirtualProductStockStatus `json:"status"`
+	DeliveredAt   *time.Time                       `json:"delivered_at,omitempty"`
+	FirstViewedAt *time.Time                       `json:"first_viewed_at,omitempty"`
+	RevokedAt     *time.Time                       `json:"revoked_at,omitempty"`
+	RevokeReason  string                           `json:"revoke_reason,omitempty"`
+}
+
+// SharedOrderPaymentRecord 收款/退款流水
+type SharedOrderPaymentRecord struct {
+	Kind        string    `json:"kind"`
+	AmountMinor int64     `json:"amount_minor"`
+	Currency    string    `json:"currency"`
+	Source      string    `json:"source,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SharedOrderPayment 分享订单的付款信息
+type SharedOrderPayment struct {
+	MethodName  string                     `json:"method_name,omitempty"`
+	MethodType  string                     `json:"method_type,omitempty"`
+	SelectedAt  *time.Time                 `json:"selected_at,omitempty"`
+	PaymentData string                     `json:"payment_data,omitempty"`
+	Records     []SharedOrderPaymentRecord `json:"records"`
+}
+
+// SharedOrderShipmentEvent 发货记录
+type SharedOrderShipmentEvent struct {
+	Action     string    `json:"action"`
+	TrackingNo string    `json:"tracking_no,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SharedOrderContext 工单分享订单时附带给客服的交付上下文
+type SharedOrderContext struct {
+	VirtualStocks   []SharedOrderVirtualStock  `json:"virtual_stocks"`
+	Payment         *SharedOrderPayment        `json:"payment,omitempty"`
+	ShipmentHistory []SharedOrderShipmentEvent `json:"shipment_history"`
+}
+
+// BuildSharedOrderContext 加载分享订单的卡密、付款与发货记录。
+// privacyProtected 为 true 时按字段打码策略的隐私订单规则处理付款数据
+func BuildSharedOrderContext(db *gorm.DB, order *models.Order, rules models.FieldMaskRules, privacyProtected bool) (*SharedOrderContext, error) {
+	result := &SharedOrderContext{
+		VirtualStocks:   []SharedOrderVirtualStock{},
+		ShipmentHistory: []SharedOrderShipmentEvent{},
+	}
+	if db == nil || order == nil {
+		return result, nil
+	}
+
+	var stocks []models.VirtualProductStock
+	if err := db.Where("order_no = ? AND status IN ?", order.OrderNo, []models.VirtualProductStockStatus{models.VirtualStockStatusSold, models.VirtualStockStatusRevoked}).
+		Order("id ASC").
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	for _, stock := range stocks {
+		result.VirtualStocks = append(result.VirtualStocks, SharedOrderVirtualStock{
+			ID:            stock.ID,
+			Content:       models.MaskVirtualStockContent(stock.Content),
+			Status:        stock.Status,
+			DeliveredAt:   stock.DeliveredAt,
+			FirstViewedAt: stock.FirstViewedAt,
+			RevokedAt:     stock.RevokedAt,
+			RevokeReason:  stock.RevokeReason,
+		})
+	}
+
+	payment := &SharedOrderPayment{Records: []SharedOrderPaymentRecord{}}
+	var opm models.OrderPaymentMethod
+	if err := db.Where("order_id = ?", order.ID).First(&opm).Error; err == nil {
+		var pm models.PaymentMethod
+		if err := db.Select("id, name, type").First(&pm, opm.PaymentMethodID).Error; err == nil {
+			payment.MethodName = pm.Name
+			payment.MethodType = string(pm.Type)
+		}
+		selectedAt := opm.CreatedAt
+		payment.SelectedAt = &selectedAt
+		payment.PaymentData = rules.MaskPaymentData(opm.PaymentData, privacyProtected)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	var entries []models.LedgerEntry
+	if err := db.Where("order_id = ? AND account = ?", order.ID, models.LedgerAccountCash).
+		Order("id ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		payment.Records = append(payment.Records, SharedOrderPaymentRecord{
+			Kind:        entry.Kind,
+			AmountMinor: entry.AmountMinor,
+			Currency:    entry.Currency,
+			Source:      entry.Source,
+			CreatedAt:   entry.CreatedAt,
+		})
+	}
+	if payment.SelectedAt != nil || len(payment.Records) > 0 {
+		result.Payment = payment
+	}
+
+	var logs []models.OperationLog
+	if err := db.Select("id, action, details, created_at").
+		Where("resource_type = ? AND resource_id = ? AND action IN ?", "order", order.ID, sharedOrderShipmentActions).
+		Order("id ASC").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	hasTracking := false
+	for _, entry := range logs {
+		event := SharedOrderShipmentEvent{Action: entry.Action, CreatedAt: entry.CreatedAt}
+		if trackingNo, ok := entry.Details["tracking_no"].(string); ok {
+			event.TrackingNo = trackingNo
+		}
+		if entry.Action == "assign_tracking" {
+			hasTracking = true
+		}
+		result.ShipmentHistory = append(result.ShipmentHistory, event)
+	}
+	// 物流导入、API 发货等不写订单操作日志的途径，用订单上的发货时间补一条
+	if !hasTracking && order.ShippedAt != nil {
+		result.ShipmentHistory = append(result.ShipmentHistory, SharedOrderShipmentEvent{
+			Action:     "shipped",
+			TrackingNo: order.TrackingNo,
+			CreatedAt:  *order.ShippedAt,
+		})
+	}
+	sort.SliceStable(result.ShipmentHistory, func(i, j int) bool {
+		return result.ShipmentHistory[i].CreatedAt.Before(result.ShipmentHistory[j].CreatedAt)
+	})
+	return result, nil
+}
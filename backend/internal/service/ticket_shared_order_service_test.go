@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+func TestBuildSharedOrderContextMasksCodesAndPaymentData(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.VirtualProductStock{}, &models.OrderPaymentMethod{}, &models.PaymentMethod{}, &models.LedgerEntry{}, &models.OperationLog{})
+	shippedAt := time.Now().Add(-time.Hour)
+	order := &models.Order{ID: 1, OrderNo: "ORDER-1", ShippedAt: &shippedAt, TrackingNo: "SF100"}
+	delivered := time.Now()
+	stocks := []models.VirtualProductStock{
+		{VirtualInventoryID: 1, Content: "KEY-ABCD-1234", Status: models.VirtualStockStatusSold, OrderNo: "ORDER-1", DeliveredAt: &delivered},
+		{VirtualInventoryID: 1, Content: "KEY-RESERVED", Status: models.VirtualStockStatusReserved, OrderNo: "ORDER-1"},
+	}
+	for i := range stocks {
+		if err := db.Create(&stocks[i]).Error; err != nil {
+			t.Fatalf("create stock: %v", err)
+		}
+	}
+	method := models.PaymentMethod{Name: "Bank Transfer"}
+	if err := db.Create(&method).Error; err != nil {
+		t.Fatalf("create payment method: %v", err)
+	}
+	if err := db.Create(&models.OrderPaymentMethod{OrderID: order.ID, PaymentMethodID: method.ID, PaymentData: `{"txid":"TX-123456"}`}).Error; err != nil {
+		t.Fatalf("create order payment method: %v", err)
+	}
+	orderID := order.ID
+	if err := db.Create(&models.LedgerEntry{TxnNo: "TXN-1", Kind: models.LedgerKindPayment, Account: models.LedgerAccountCash, AmountMinor: 1000, Currency: "USD", OrderID: &orderID, Checksum: "x"}).Error; err != nil {
+		t.Fatalf("create ledger entry: %v", err)
+	}
+	if err := db.Create(&models.OperationLog{Action: "complete", ResourceType: "order", ResourceID: &orderID}).Error; err != nil {
+		t.Fatalf("create operation log: %v", err)
+	}
+
+	rules := models.FieldMaskRules{models.FieldMaskPayment: {Mode: models.FieldMaskModeFull}}
+	result, err := BuildSharedOrderContext(db, order, rules, true)
+	if err != nil {
+		t.Fatalf("build context: %v", err)
+	}
+	if len(result.VirtualStocks) != 1 || result.VirtualStocks[0].Content != "********1234" {
+		t.Fatalf("expected one masked delivered code, got %+v", result.VirtualStocks)
+	}
+	if result.Payment == nil || result.Payment.MethodName != "Bank Transfer" || len(result.Payment.Records) != 1 {
+		t.Fatalf("unexpected payment: %+v", result.Payment)
+	}
+	if result.Payment.PaymentData == `{"txid":"TX-123456"}` {
+		t.Fatal("payment data must follow the field mask policy")
+	}
+	if len(result.ShipmentHistory) != 2 || result.ShipmentHistory[0].Action != "shipped" || result.ShipmentHistory[0].TrackingNo != "SF100" || result.ShipmentHistory[1].Action != "complete" {
+		t.Fatalf("unexpected shipment history: %+v", result.ShipmentHistory)
+	}
+}
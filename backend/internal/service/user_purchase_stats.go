@@ -39,7 +39,8 @@ func buildOrderItemQuantityBySKU(items []models.OrderItem) map[string]int64 {
 	quantities := make(map[string]int64)
 	for _, item := range items {
 		sku := strings.TrimSpace(item.SKU)
-		if sku == "" || item.Quantity <= 0 {
+		// 赠品不计入限购
+		if sku == "" || item.Quantity <= 0 || item.IsGift {
 			continue
 		}
 		quantities[sku] += int64(item.Quantity)
@@ -10,7 +10,7 @@ import (
 )
 
 func TestCreateUserOrderAndCancelKeepPurchaseStatsInSync(t *testing.T) {
-	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Product{}, &models.Order{})
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Product{}, &models.Order{}, &models.OrderNote{})
 
 	cfg := &config.Config{}
 	cfg.Order.MaxOrderItems = 20
@@ -77,7 +77,7 @@ func TestCreateUserOrderAndCancelKeepPurchaseStatsInSync(t *testing.T) {
 }
 
 func TestSubmitShippingFormTracksPurchaseStatsOnInitialUserBinding(t *testing.T) {
-	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Product{}, &models.Order{})
+	db := openConcurrentServiceTestDB(t, &models.User{}, &models.Product{}, &models.Order{}, &models.OrderNote{})
 
 	cfg := &config.Config{}
 	cfg.Order.MaxOrderItems = 20
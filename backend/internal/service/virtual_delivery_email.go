@@ -0,0 +1,178 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+// virtualDeliveryLinkTTL 邮件取件链接有效期
+const virtualDeliveryLinkTTL = 72 * time.Hour
+
+// VirtualDeliveryStock 邮件或取件链接中可见的已发货卡密
+type VirtualDeliveryStock struct {
+	ID          uint       `json:"id"`
+	ProductName string     `json:"product_name"`
+	Content     string     `json:"content"`
+	Remark      string     `json:"remark,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+}
+
+// VirtualDeliveryEmailStocks 按商品邮件交付方式划分的已发货卡密
+type VirtualDeliveryEmailStocks struct {
+	Inline []VirtualDeliveryStock // 直接写入邮件
+	Linked []VirtualDeliveryStock // 通过签名链接取件
+}
+
+// loadVirtualDeliveryEmailStocks 查询订单已发货的卡密，并按所属商品的邮件交付方式分类
+// 同一虚拟库存被多个商品绑定且方式不同时取更严格的 link
+func loadVirtualDeliveryEmailStocks(db *gorm.DB, order *models.Order) (*VirtualDeliveryEmailStocks, error) {
+	result := &VirtualDeliveryEmailStocks{}
+	if db == nil || order == nil || order.OrderNo == "" {
+		return result, nil
+	}
+	skus := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.ProductType == models.ProductTypeVirtual && item.SKU != "" {
+			skus = append(skus, item.SKU)
+		}
+	}
+	if len(skus) == 0 {
+		return result, nil
+	}
+
+	var products []models.Product
+	if err := db.Select("id, name, email_delivery_mode").
+		Where("sku IN ? AND email_delivery_mode IN ?", skus, []models.EmailDeliveryMode{models.EmailDeliveryModeInline, models.EmailDeliveryModeLink}).
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return result, nil
+	}
+	productByID := make(map[uint]models.Product, len(products))
+	productIDs := make([]uint, 0, len(products))
+	for _, product := range products {
+		productByID[product.ID] = product
+		productIDs = append(productIDs, product.ID)
+	}
+
+	var bindings []models.ProductVirtualInventoryBinding
+	if err := db.Select("product_id, virtual_inventory_id").Where("product_id IN ?", productIDs).Find(&bindings).Error; err != nil {
+		return nil, err
+	}
+	productByInventory := make(map[uint]models.Product, len(bindings))
+	for _, binding := range bindings {
+		product := productByID[binding.ProductID]
+		if existing, ok := productByInventory[binding.VirtualInventoryID]; ok && existing.EmailDeliveryMode == models.EmailDeliveryModeLink {
+			continue
+		}
+		productByInventory[binding.VirtualInventoryID] = product
+	}
+
+	var stocks []models.VirtualProductStock
+	if err := db.Where("order_no = ? AND status = ? AND delivered_at IS NOT NULL", order.OrderNo, models.VirtualStockStatusSold).
+		Order("id ASC").
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	for _, stock := range stocks {
+		product, ok := productByInventory[stock.VirtualInventoryID]
+		if !ok || strings.TrimSpace(stock.Content) == "" {
+			continue
+		}
+		item := VirtualDeliveryStock{
+			ID:          stock.ID,
+			ProductName: product.Name,
+			Content:     stock.Content,
+			Remark:      stock.Remark,
+			DeliveredAt: stock.DeliveredAt,
+		}
+		if product.EmailDeliveryMode == models.EmailDeliveryModeInline {
+			result.Inline = append(result.Inline, item)
+		} else {
+			result.Linked = append(result.Linked, item)
+		}
+	}
+	return result, nil
+}
+
+// GetLinkedDeliveryStocks 签名取件链接可查看的卡密（邮件交付方式为 inline 或 link 的商品）
+func (s *VirtualInventoryService) GetLinkedDeliveryStocks(order *models.Order) ([]VirtualDeliveryStock, error) {
+	stocks, err := loadVirtualDeliveryEmailStocks(s.db, order)
+	if err != nil {
+		return nil, err
+	}
+	return append(stocks.Inline, stocks.Linked...), nil
+}
+
+func virtualDeliverySignature(secret, orderNo string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("virtual-delivery\x00" + orderNo + "\x00" + strconv.FormatInt(expires, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignedVirtualDeliveryURL 生成带过期时间的取件页面链接
+func SignedVirtualDeliveryURL(appURL, orderNo string, now time.Time) (string, time.Time) {
+	secret := ""
+	if cfg := config.GetConfig(); cfg != nil {
+		secret = cfg.JWT.Secret
+	}
+	expiresAt := now.Add(virtualDeliveryLinkTTL)
+	expires := expiresAt.Unix()
+	return strings.TrimRight(appURL, "/") + "/delivery/" + url.PathEscape(orderNo) +
+		"?expires=" + strconv.FormatInt(expires, 10) +
+		"&sig=" + virtualDeliverySignature(secret, orderNo, expires), expiresAt
+}
+
+// VerifyVirtualDeliverySignature 校验取件链接签名
+func VerifyVirtualDeliverySignature(orderNo, expiresRaw, sig string, now time.Time) bool {
+	cfg := config.GetConfig()
+	if cfg == nil || orderNo == "" || sig == "" {
+		return false
+	}
+	expires, err := strconv.ParseInt(expiresRaw, 10, 64)
+	if err != nil || now.Unix() > expires {
+		return false
+	}
+	if time.Unix(expires, 0).Sub(now) > virtualDeliveryLinkTTL {
+		return false
+	}
+	expected := virtualDeliverySignature(cfg.JWT.Secret, orderNo, expires)
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
+
+// applyVirtualDeliveryEmailData 向付款/发货邮件写入卡密或取件链接，返回邮件是否含敏感内容
+func (s *EmailService) applyVirtualDeliveryEmailData(order *models.Order, data map[string]interface{}) bool {
+	stocks, err := loadVirtualDeliveryEmailStocks(s.db, order)
+	if err != nil {
+		log.Printf("Failed to load virtual delivery stocks for email: order=%s err=%v", order.OrderNo, err)
+		return false
+	}
+	if len(stocks.Linked) > 0 {
+		deliveryURL, expiresAt := SignedVirtualDeliveryURL(s.appURL, order.OrderNo, models.NowFunc())
+		data["DeliveryURL"] = deliveryURL
+		data["DeliveryURLExpiresAt"] = expiresAt.Format("2006-01-02 15:04:05")
+	}
+	if len(stocks.Inline) == 0 {
+		return false
+	}
+	items := make([]map[string]interface{}, 0, len(stocks.Inline))
+	for _, stock := range stocks.Inline {
+		items = append(items, map[string]interface{}{
+			"Name":    stock.ProductName,
+			"Content": stock.Content,
+		})
+	}
+	data["DeliveredItems"] = items
+	return true
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+func TestLoadVirtualDeliveryEmailStocksSplitsByProductMode(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.Product{}, &models.ProductVirtualInventoryBinding{}, &models.VirtualProductStock{})
+	products := []models.Product{
+		{SKU: "SKU-INLINE", Name: "Game Key", ProductType: models.ProductTypeVirtual, EmailDeliveryMode: models.EmailDeliveryModeInline},
+		{SKU: "SKU-LINK", Name: "Gift Card", ProductType: models.ProductTypeVirtual, EmailDeliveryMode: models.EmailDeliveryModeLink},
+		{SKU: "SKU-NONE", Name: "License", ProductType: models.ProductTypeVirtual, EmailDeliveryMode: models.EmailDeliveryModeNone},
+	}
+	for i := range products {
+		if err := db.Create(&products[i]).Error; err != nil {
+			t.Fatalf("create product: %v", err)
+		}
+		binding := models.ProductVirtualInventoryBinding{ProductID: products[i].ID, VirtualInventoryID: uint(i + 1), AttributesHash: "default"}
+		if err := db.Create(&binding).Error; err != nil {
+			t.Fatalf("create binding: %v", err)
+		}
+	}
+	delivered := time.Now()
+	stocks := []models.VirtualProductStock{
+		{VirtualInventoryID: 1, Content: "KEY-1", Status: models.VirtualStockStatusSold, OrderNo: "ORDER-1", DeliveredAt: &delivered},
+		{VirtualInventoryID: 2, Content: "CARD-1", Status: models.VirtualStockStatusSold, OrderNo: "ORDER-1", DeliveredAt: &delivered},
+		{VirtualInventoryID: 3, Content: "LIC-1", Status: models.VirtualStockStatusSold, OrderNo: "ORDER-1", DeliveredAt: &delivered},
+		{VirtualInventoryID: 1, Content: "KEY-REVOKED", Status: models.VirtualStockStatusRevoked, OrderNo: "ORDER-1", DeliveredAt: &delivered},
+		{VirtualInventoryID: 1, Content: "KEY-PENDING", Status: models.VirtualStockStatusSold, OrderNo: "ORDER-1"},
+	}
+	for i := range stocks {
+		if err := db.Create(&stocks[i]).Error; err != nil {
+			t.Fatalf("create stock: %v", err)
+		}
+	}
+
+	order := &models.Order{OrderNo: "ORDER-1", Items: []models.OrderItem{
+		{SKU: "SKU-INLINE", ProductType: models.ProductTypeVirtual},
+		{SKU: "SKU-LINK", ProductType: models.ProductTypeVirtual},
+		{SKU: "SKU-NONE", ProductType: models.ProductTypeVirtual},
+	}}
+	result, err := loadVirtualDeliveryEmailStocks(db, order)
+	if err != nil {
+		t.Fatalf("load stocks: %v", err)
+	}
+	if len(result.Inline) != 1 || result.Inline[0].Content != "KEY-1" || result.Inline[0].ProductName != "Game Key" {
+		t.Fatalf("unexpected inline stocks: %+v", result.Inline)
+	}
+	if len(result.Linked) != 1 || result.Linked[0].Content != "CARD-1" {
+		t.Fatalf("unexpected linked stocks: %+v", result.Linked)
+	}
+}
+
+func TestVirtualDeliverySignedURL(t *testing.T) {
+	loadTicketAttachmentTestConfig(t)
+	now := time.Unix(1_800_000_000, 0)
+
+	signedURL, expiresAt := SignedVirtualDeliveryURL("https://shop.example.com/", "ORDER-1", now)
+	if !strings.HasPrefix(signedURL, "https://shop.example.com/delivery/ORDER-1?") {
+		t.Fatalf("unexpected signed url: %s", signedURL)
+	}
+	if !expiresAt.Equal(now.Add(virtualDeliveryLinkTTL)) {
+		t.Fatalf("unexpected expiry: %v", expiresAt)
+	}
+	parsed, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatalf("parse signed url: %v", err)
+	}
+	query := parsed.Query()
+	expires, sig := query.Get("expires"), query.Get("sig")
+
+	if !VerifyVirtualDeliverySignature("ORDER-1", expires, sig, now.Add(time.Hour)) {
+		t.Fatal("expected signature to verify")
+	}
+	if VerifyVirtualDeliverySignature("ORDER-2", expires, sig, now) {
+		t.Fatal("signature must be bound to the order number")
+	}
+	if VerifyVirtualDeliverySignature("ORDER-1", expires, sig, expiresAt.Add(time.Second)) {
+		t.Fatal("expired link must be rejected")
+	}
+	if VerifyVirtualDeliverySignature("ORDER-1", "9999999999", sig, now) {
+		t.Fatal("tampered expiry must be rejected")
+	}
+}
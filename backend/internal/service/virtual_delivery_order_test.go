@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+func TestVirtualDeliveryOrderBindingOverridesInventory(t *testing.T) {
+	svc, db := newVirtualInventoryServiceTestDB(t)
+
+	requireBizErr(t, svc.CreateVirtualInventory(&models.VirtualInventory{Name: "Bad", DeliveryOrder: "fifo"}), "virtual_inventory.deliveryOrderInvalid")
+
+	inventory := &models.VirtualInventory{Name: "Gift cards", Type: models.VirtualInventoryTypeStatic, IsActive: true, DeliveryOrder: models.VirtualDeliveryOrderOldest}
+	if err := svc.CreateVirtualInventory(inventory); err != nil {
+		t.Fatalf("create inventory: %v", err)
+	}
+	product := &models.Product{SKU: "GC-20", Name: "Gift card", Price: 2000, ProductType: models.ProductTypeVirtual}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	binding, err := svc.CreateBinding(product.ID, inventory.ID, false, 1, "")
+	if err != nil {
+		t.Fatalf("create binding: %v", err)
+	}
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, content := range []string{"FIRST", "SECOND", "THIRD"} {
+		stock := &models.VirtualProductStock{VirtualInventoryID: inventory.ID, Content: content, Status: models.VirtualStockStatusAvailable, CreatedAt: base.Add(time.Duration(i) * time.Hour)}
+		if err := db.Create(stock).Error; err != nil {
+			t.Fatalf("create stock: %v", err)
+		}
+	}
+
+	// 绑定未设置时使用虚拟库存的先进先出
+	stocks, _, err := svc.AllocateStockForProductByAttributes(product.ID, 1, "ORDER-1", nil)
+	if err != nil || len(stocks) != 1 || stocks[0].Content != "FIRST" {
+		t.Fatalf("expected oldest stock, got %+v (%v)", stocks, err)
+	}
+
+	requireBizErr(t, svc.UpdateBinding(binding.ID, false, 1, "", "latest"), "virtual_inventory.deliveryOrderInvalid")
+	if err := svc.UpdateBinding(binding.ID, false, 1, "", models.VirtualDeliveryOrderNewest); err != nil {
+		t.Fatalf("update binding: %v", err)
+	}
+	stocks, _, err = svc.AllocateStockForProductByAttributes(product.ID, 1, "ORDER-2", nil)
+	if err != nil || len(stocks) != 1 || stocks[0].Content != "THIRD" {
+		t.Fatalf("expected binding override to pick newest stock, got %+v (%v)", stocks, err)
+	}
+}
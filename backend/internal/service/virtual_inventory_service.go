@@ -13,6 +13,7 @@ import (
 	"auralogic/internal/config"
 	"auralogic/internal/models"
 	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/dbutil"
 
 	"github.com/xuri/excelize/v2"
 	"gorm.io/gorm"
@@ -71,6 +72,7 @@ type VirtualVariantBindingInput struct {
 	VirtualInventoryID *uint             `json:"virtual_inventory_id"`
 	IsRandom           bool              `json:"is_random"`
 	Priority           int               `json:"priority"`
+	DeliveryOrder      string            `json:"delivery_order"`
 }
 
 type VirtualVariantBindingBatchError struct {
@@ -105,6 +107,11 @@ func newVirtualBindingInsufficientAvailableError(requested int, available int64)
 	})
 }
 
+func newVirtualDeliveryOrderInvalidError(order string) error {
+	return bizerr.Newf("virtual_inventory.deliveryOrderInvalid", "Invalid delivery order: %s", order).
+		WithParams(map[string]interface{}{"order": order})
+}
+
 // getScriptPendingItems 获取订单中脚本类型虚拟库存的待发货条目
 // 通过 VirtualInventoryBindings 记录的绑定关系，减去已有的 sold 记录数
 func (s *VirtualInventoryService) getScriptPendingItems(orderNo string) ([]scriptPendingItem, error) {
@@ -149,8 +156,8 @@ func (s *VirtualInventoryService) getScriptPendingItemsForLoadedOrderWithDB(db *
 		var soldRows []inventorySoldCountRow
 		if err := db.Model(&models.VirtualProductStock{}).
 			Select("virtual_inventory_id, COUNT(*) as sold").
-			Where("order_no = ? AND status = ? AND virtual_inventory_id IN ?",
-				order.OrderNo, models.VirtualStockStatusSold, inventoryIDs).
+			Where("order_no = ? AND status IN ? AND virtual_inventory_id IN ?",
+				order.OrderNo, []models.VirtualProductStockStatus{models.VirtualStockStatusSold, models.VirtualStockStatusRevoked}, inventoryIDs).
 			Group("virtual_inventory_id").
 			Scan(&soldRows).Error; err != nil {
 			return nil, err
@@ -162,7 +169,7 @@ func (s *VirtualInventoryService) getScriptPendingItemsForLoadedOrderWithDB(db *
 
 	var result []scriptPendingItem
 	for invID, totalQty := range inventoryQty {
-		// 减去已有的 sold 记录数
+		// 减去已有的 sold 记录数（撤销的记录视为已发货，不会重新执行脚本）
 		soldCount := soldCountMap[invID]
 		pending := totalQty - int(soldCount)
 		if pending > 0 {
@@ -176,12 +183,62 @@ func (s *VirtualInventoryService) getScriptPendingItemsForLoadedOrderWithDB(db *
 	return result, nil
 }
 
-// getRandomOrderClause 根据数据库类型返回正确的随机排序SQL
-func (s *VirtualInventoryService) getRandomOrderClause() string {
-	if s.cfg != nil && s.cfg.Database.Driver == "mysql" {
-		return "RAND()"
+// resolveDeliveryOrder 发货顺序优先级：绑定 > 虚拟库存 > 全局设置，传入的空值表示继承
+func (s *VirtualInventoryService) resolveDeliveryOrder(overrides ...string) string {
+	for _, order := range overrides {
+		if order != "" {
+			return order
+		}
+	}
+	if s.cfg != nil {
+		return s.cfg.Order.VirtualDeliveryOrder
+	}
+	return ""
+}
+
+// findDeliverableStock 按发货顺序（newest/oldest/expiring/随机）取最多 limit 个可用库存
+func (s *VirtualInventoryService) findDeliverableStock(query *gorm.DB, deliveryOrder string, limit int) ([]models.VirtualProductStock, error) {
+	var stocks []models.VirtualProductStock
+	switch deliveryOrder {
+	case models.VirtualDeliveryOrderNewest:
+		query = query.Order("created_at DESC")
+	case models.VirtualDeliveryOrderOldest:
+		query = query.Order("created_at ASC").Order("id ASC")
+	case models.VirtualDeliveryOrderExpiring:
+		query = query.Order("CASE WHEN expires_at IS NULL THEN 1 ELSE 0 END").Order("expires_at ASC").Order("id ASC")
+	default:
+		return sampleVirtualStock(query, models.NewVirtualStockRandomKey(), limit)
+	}
+	if err := query.Limit(limit).Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// sampleVirtualStock 随机抽样：从随机起点 pivot 沿 random_key 索引取，不足时从头回绕
+// 只扫描需要的行，不会像 ORDER BY RANDOM() 那样对全部可用库存排序
+func sampleVirtualStock(query *gorm.DB, pivot, limit int) ([]models.VirtualProductStock, error) {
+	query = query.Session(&gorm.Session{})
+	var stocks []models.VirtualProductStock
+	if err := query.Where("random_key >= ?", pivot).Order("random_key ASC").Limit(limit).Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	if len(stocks) >= limit {
+		return stocks, nil
+	}
+	var wrapped []models.VirtualProductStock
+	if err := query.Where("random_key < ?", pivot).Order("random_key ASC").Limit(limit - len(stocks)).Find(&wrapped).Error; err != nil {
+		return nil, err
+	}
+	return append(stocks, wrapped...), nil
+}
+
+// allocatableVirtualStock 可分配的库存项：可用且未过期
+func allocatableVirtualStock(virtualInventoryID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("virtual_inventory_id = ? AND status = ? AND (expires_at IS NULL OR expires_at > ?)",
+			virtualInventoryID, models.VirtualStockStatusAvailable, models.NowFunc())
 	}
-	return "RANDOM()"
 }
 
 // getStockStatsForInventories 批量获取库存统计，避免 N+1 查询
@@ -198,9 +255,19 @@ func (s *VirtualInventoryService) getStockStatsForInventories(inventoryIDs []uin
 		return nil, err
 	}
 
+	return s.stockStatsForLoadedInventories(inventories)
+}
+
+// stockStatsForLoadedInventories 对已加载的虚拟库存做一次分组聚合统计（依赖 idx_virtual_stock_stats 覆盖索引）
+func (s *VirtualInventoryService) stockStatsForLoadedInventories(inventories []models.VirtualInventory) (map[uint]map[string]int64, error) {
+	statsByInventory := make(map[uint]map[string]int64, len(inventories))
 	if len(inventories) == 0 {
 		return statsByInventory, nil
 	}
+	inventoryIDs := make([]uint, 0, len(inventories))
+	for _, inv := range inventories {
+		inventoryIDs = append(inventoryIDs, inv.ID)
+	}
 
 	var countRows []inventoryStatusCountRow
 	if err := s.db.Model(&models.VirtualProductStock{}).
@@ -230,6 +297,7 @@ func (s *VirtualInventoryService) getStockStatsForInventories(inventoryIDs []uin
 			"available": 0,
 			"reserved":  0,
 			"sold":      0,
+			"revoked":   counts[string(models.VirtualStockStatusRevoked)],
 		}
 
 		if inv.Type == models.VirtualInventoryTypeScript {
@@ -263,6 +331,9 @@ func (s *VirtualInventoryService) getStockStatsForInventories(inventoryIDs []uin
 
 // CreateVirtualInventory 创建虚拟库存
 func (s *VirtualInventoryService) CreateVirtualInventory(inventory *models.VirtualInventory) error {
+	if inventory.DeliveryOrder != "" && !models.IsValidVirtualDeliveryOrder(inventory.DeliveryOrder) {
+		return newVirtualDeliveryOrderInvalidError(inventory.DeliveryOrder)
+	}
 	return s.db.Create(inventory).Error
 }
 
@@ -277,6 +348,9 @@ func (s *VirtualInventoryService) GetVirtualInventory(id uint) (*models.VirtualI
 
 // UpdateVirtualInventory 更新虚拟库存
 func (s *VirtualInventoryService) UpdateVirtualInventory(id uint, updates map[string]interface{}) error {
+	if order, ok := updates["delivery_order"].(string); ok && order != "" && !models.IsValidVirtualDeliveryOrder(order) {
+		return newVirtualDeliveryOrderInvalidError(order)
+	}
 	return s.db.Model(&models.VirtualInventory{}).Where("id = ?", id).Updates(updates).Error
 }
 
@@ -318,7 +392,7 @@ func (s *VirtualInventoryService) ListVirtualInventories(page, limit int, search
 	query := s.db.Model(&models.VirtualInventory{})
 
 	if search != "" {
-		query = query.Where("name LIKE ? OR sku LIKE ?", "%"+search+"%", "%"+search+"%")
+		query = dbutil.WhereContainsFold(query, search, "name", "sku")
 	}
 
 	if err := query.Count(&total).Error; err != nil {
@@ -330,11 +404,8 @@ func (s *VirtualInventoryService) ListVirtualInventories(page, limit int, search
 		return nil, 0, err
 	}
 
-	inventoryIDs := make([]uint, 0, len(inventories))
-	for _, inv := range inventories {
-		inventoryIDs = append(inventoryIDs, inv.ID)
-	}
-	statsByInventory, err := s.getStockStatsForInventories(inventoryIDs)
+	// 当前页的库存统计只需一次分组聚合查询
+	statsByInventory, err := s.stockStatsForLoadedInventories(inventories)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -361,12 +432,14 @@ func (s *VirtualInventoryService) ListVirtualInventories(page, limit int, search
 			Description:       inv.Description,
 			TotalLimit:        inv.TotalLimit,
 			AllowInlineIframe: inv.AllowInlineIframe,
+			DeliveryOrder:     inv.DeliveryOrder,
 			IsActive:          inv.IsActive,
 			Notes:             inv.Notes,
 			Total:             stats["total"],
 			Available:         stats["available"],
 			Reserved:          stats["reserved"],
 			Sold:              stats["sold"],
+			Revoked:           stats["revoked"],
 			CreatedAt:         inv.CreatedAt,
 		})
 	}
@@ -388,11 +461,12 @@ func (s *VirtualInventoryService) GetStockStats(virtualInventoryID uint) (map[st
 		"available": 0,
 		"reserved":  0,
 		"sold":      0,
+		"revoked":   0,
 	}, nil
 }
 
 // ImportFromExcel 从Excel导入虚拟产品库存
-func (s *VirtualInventoryService) ImportFromExcel(virtualInventoryID uint, filePath string, importedBy string) (int, error) {
+func (s *VirtualInventoryService) ImportFromExcel(virtualInventoryID uint, filePath string, importedBy string, expiresAt *time.Time) (int, error) {
 	f, err := excelize.OpenFile(filePath)
 	if err != nil {
 		return 0, fmt.Errorf("failed to open excel file: %w", err)
@@ -440,6 +514,7 @@ func (s *VirtualInventoryService) ImportFromExcel(virtualInventoryID uint, fileP
 			Status:             models.VirtualStockStatusAvailable,
 			BatchNo:            batchNo,
 			ImportedBy:         importedBy,
+			ExpiresAt:          expiresAt,
 		}
 		stocks = append(stocks, stock)
 	}
@@ -460,7 +535,7 @@ func (s *VirtualInventoryService) ImportFromExcel(virtualInventoryID uint, fileP
 }
 
 // ImportFromText 从文本文件导入（每行一个卡密）
-func (s *VirtualInventoryService) ImportFromText(virtualInventoryID uint, content string, importedBy string) (int, error) {
+func (s *VirtualInventoryService) ImportFromText(virtualInventoryID uint, content string, importedBy string, expiresAt *time.Time) (int, error) {
 	lines := strings.Split(content, "\n")
 
 	// 生成批次号
@@ -489,6 +564,7 @@ func (s *VirtualInventoryService) ImportFromText(virtualInventoryID uint, conten
 			Status:             models.VirtualStockStatusAvailable,
 			BatchNo:            batchNo,
 			ImportedBy:         importedBy,
+			ExpiresAt:          expiresAt,
 		}
 		stocks = append(stocks, stock)
 	}
@@ -509,7 +585,7 @@ func (s *VirtualInventoryService) ImportFromText(virtualInventoryID uint, conten
 }
 
 // ImportFromCSV 从CSV导入
-func (s *VirtualInventoryService) ImportFromCSV(virtualInventoryID uint, reader io.Reader, importedBy string) (int, error) {
+func (s *VirtualInventoryService) ImportFromCSV(virtualInventoryID uint, reader io.Reader, importedBy string, expiresAt *time.Time) (int, error) {
 	csvReader := csv.NewReader(reader)
 
 	// 生成批次号
@@ -552,6 +628,7 @@ func (s *VirtualInventoryService) ImportFromCSV(virtualInventoryID uint, reader
 			Status:             models.VirtualStockStatusAvailable,
 			BatchNo:            batchNo,
 			ImportedBy:         importedBy,
+			ExpiresAt:          expiresAt,
 		}
 		stocks = append(stocks, stock)
 	}
@@ -572,7 +649,7 @@ func (s *VirtualInventoryService) ImportFromCSV(virtualInventoryID uint, reader
 }
 
 // CreateStockManually 手动创建单个库存项
-func (s *VirtualInventoryService) CreateStockManually(virtualInventoryID uint, content, remark, importedBy string) (*models.VirtualProductStock, error) {
+func (s *VirtualInventoryService) CreateStockManually(virtualInventoryID uint, content, remark, importedBy string, expiresAt *time.Time) (*models.VirtualProductStock, error) {
 	stock := &models.VirtualProductStock{
 		VirtualInventoryID: virtualInventoryID,
 		Content:            content,
@@ -580,6 +657,7 @@ func (s *VirtualInventoryService) CreateStockManually(virtualInventoryID uint, c
 		Status:             models.VirtualStockStatusAvailable,
 		BatchNo:            fmt.Sprintf("MANUAL-%s", time.Now().Format("20060102150405")),
 		ImportedBy:         importedBy,
+		ExpiresAt:          expiresAt,
 	}
 
 	if err := s.db.Create(stock).Error; err != nil {
@@ -680,12 +758,14 @@ func (s *VirtualInventoryService) GetVirtualInventoryWithStats(id uint) (*models
 		Description:       inventory.Description,
 		TotalLimit:        inventory.TotalLimit,
 		AllowInlineIframe: inventory.AllowInlineIframe,
+		DeliveryOrder:     inventory.DeliveryOrder,
 		IsActive:          inventory.IsActive,
 		Notes:             inventory.Notes,
 		Total:             stats["total"],
 		Available:         stats["available"],
 		Reserved:          stats["reserved"],
 		Sold:              stats["sold"],
+		Revoked:           stats["revoked"],
 		CreatedAt:         inventory.CreatedAt,
 	}, nil
 }
@@ -750,14 +830,18 @@ func (s *VirtualInventoryService) createBindingWithDB(db *gorm.DB, productID, vi
 	return binding, nil
 }
 
-// UpdateBinding 更新绑定
-func (s *VirtualInventoryService) UpdateBinding(bindingID uint, isRandom bool, priority int, notes string) error {
+// UpdateBinding 更新绑定，deliveryOrder 为空表示使用虚拟库存的发货顺序
+func (s *VirtualInventoryService) UpdateBinding(bindingID uint, isRandom bool, priority int, notes, deliveryOrder string) error {
+	if deliveryOrder != "" && !models.IsValidVirtualDeliveryOrder(deliveryOrder) {
+		return newVirtualDeliveryOrderInvalidError(deliveryOrder)
+	}
 	return s.db.Model(&models.ProductVirtualInventoryBinding{}).
 		Where("id = ?", bindingID).
 		Updates(map[string]interface{}{
-			"is_random": isRandom,
-			"priority":  priority,
-			"notes":     notes,
+			"is_random":      isRandom,
+			"priority":       priority,
+			"notes":          notes,
+			"delivery_order": deliveryOrder,
 		}).Error
 }
 
@@ -813,12 +897,14 @@ func (s *VirtualInventoryService) GetProductBindings(productID uint) ([]models.B
 				Description:       binding.VirtualInventory.Description,
 				TotalLimit:        binding.VirtualInventory.TotalLimit,
 				AllowInlineIframe: binding.VirtualInventory.AllowInlineIframe,
+				DeliveryOrder:     binding.VirtualInventory.DeliveryOrder,
 				IsActive:          binding.VirtualInventory.IsActive,
 				Notes:             binding.VirtualInventory.Notes,
 				Total:             stats["total"],
 				Available:         stats["available"],
 				Reserved:          stats["reserved"],
 				Sold:              stats["sold"],
+				Revoked:           stats["revoked"],
 				CreatedAt:         binding.VirtualInventory.CreatedAt,
 			}
 		}
@@ -831,6 +917,7 @@ func (s *VirtualInventoryService) GetProductBindings(productID uint) ([]models.B
 			AttributesHash:     binding.AttributesHash,
 			IsRandom:           binding.IsRandom,
 			Priority:           binding.Priority,
+			DeliveryOrder:      binding.DeliveryOrder,
 			Notes:              binding.Notes,
 			VirtualInventory:   invWithStats,
 			CreatedAt:          binding.CreatedAt,
@@ -859,6 +946,15 @@ func (s *VirtualInventoryService) SaveVariantBindings(productID uint, bindings [
 			continue
 		}
 
+		if bindingInput.DeliveryOrder != "" && !models.IsValidVirtualDeliveryOrder(bindingInput.DeliveryOrder) {
+			result.Errors = append(result.Errors, VirtualVariantBindingBatchError{
+				Index:              index + 1,
+				VirtualInventoryID: *bindingInput.VirtualInventoryID,
+				Err:                newVirtualDeliveryOrderInvalidError(bindingInput.DeliveryOrder),
+			})
+			continue
+		}
+
 		binding, err := s.createBindingWithDB(
 			s.db,
 			productID,
@@ -868,6 +964,9 @@ func (s *VirtualInventoryService) SaveVariantBindings(productID uint, bindings [
 			bindingInput.Priority,
 			"",
 		)
+		if err == nil && bindingInput.DeliveryOrder != "" {
+			err = s.db.Model(binding).Update("delivery_order", bindingInput.DeliveryOrder).Error
+		}
 		if err != nil {
 			result.Errors = append(result.Errors, VirtualVariantBindingBatchError{
 				Index:              index + 1,
@@ -953,6 +1052,7 @@ func (s *VirtualInventoryService) AllocateStockForProductByAttributes(productID
 					AttributesHash:     binding.AttributesHash,
 					IsRandom:           binding.IsRandom,
 					Priority:           binding.Priority,
+					DeliveryOrder:      binding.DeliveryOrder,
 					VirtualInventory: &models.VirtualInventoryWithStats{
 						Available: stats["available"],
 					},
@@ -988,7 +1088,7 @@ func (s *VirtualInventoryService) AllocateStockForProductByAttributes(productID
 
 			// 检查是否为脚本类型库存
 			var inv models.VirtualInventory
-			if err := tx.Select("id, type, total_limit").First(&inv, binding.VirtualInventoryID).Error; err != nil {
+			if err := tx.Select("id, type, total_limit, delivery_order").First(&inv, binding.VirtualInventoryID).Error; err != nil {
 				continue
 			}
 
@@ -1011,25 +1111,12 @@ func (s *VirtualInventoryService) AllocateStockForProductByAttributes(productID
 			}
 
 			// 静态类型：从已有库存中分配
-			var stocks []models.VirtualProductStock
 			// 使用 FOR UPDATE 行锁防止并发超售
 			query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-				Where("virtual_inventory_id = ? AND status = ?", binding.VirtualInventoryID, models.VirtualStockStatusAvailable)
+				Scopes(allocatableVirtualStock(binding.VirtualInventoryID))
 
-			// 根据配置决定发货顺序
-			deliveryOrder := ""
-			if s.cfg != nil {
-				deliveryOrder = s.cfg.Order.VirtualDeliveryOrder
-			}
-			if deliveryOrder == "newest" {
-				query = query.Order("created_at DESC")
-			} else if deliveryOrder == "oldest" {
-				query = query.Order("created_at ASC")
-			} else {
-				query = query.Order(s.getRandomOrderClause())
-			}
-
-			err := query.Limit(remainingQuantity).Find(&stocks).Error
+			// 发货顺序：绑定 > 虚拟库存 > 全局设置
+			stocks, err := s.findDeliverableStock(query, s.resolveDeliveryOrder(binding.DeliveryOrder, inv.DeliveryOrder), remainingQuantity)
 
 			if err != nil {
 				continue
@@ -1077,6 +1164,17 @@ func (s *VirtualInventoryService) AllocateStockForProductByAttributes(productID
 	return allocatedStocks, scriptInventoryID, nil
 }
 
+// AllocateStockForProductByAttributesWithTx 在外部事务中为商品分配虚拟库存
+// 绑定查询与库存行锁都走 tx，分配结果随外部事务一起提交或回滚
+func (s *VirtualInventoryService) AllocateStockForProductByAttributesWithTx(tx *gorm.DB, productID uint, quantity int, orderNo string, attributes map[string]interface{}) ([]models.VirtualProductStock, *uint, error) {
+	if tx == nil {
+		return nil, nil, errors.New("transaction is required")
+	}
+	txSvc := *s
+	txSvc.db = tx
+	return txSvc.AllocateStockForProductByAttributes(productID, quantity, orderNo, attributes)
+}
+
 // AllocateStockFromInventory 从指定虚拟库存池直接分配库存（管理员创建订单时使用）
 // 返回值: (分配的库存项, 脚本类型时选中的virtualInventoryID, error)
 func (s *VirtualInventoryService) AllocateStockFromInventory(virtualInventoryID uint, quantity int, orderNo string) ([]models.VirtualProductStock, *uint, error) {
@@ -1085,7 +1183,7 @@ func (s *VirtualInventoryService) AllocateStockFromInventory(virtualInventoryID
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		// 检查是否为脚本类型库存
 		var inv models.VirtualInventory
-		if err := tx.Select("id, type, total_limit").First(&inv, virtualInventoryID).Error; err != nil {
+		if err := tx.Select("id, type, total_limit, delivery_order").First(&inv, virtualInventoryID).Error; err != nil {
 			return err
 		}
 
@@ -1105,24 +1203,12 @@ func (s *VirtualInventoryService) AllocateStockFromInventory(virtualInventoryID
 		}
 
 		// 静态类型：从已有库存中分配
-		var stocks []models.VirtualProductStock
 		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("virtual_inventory_id = ? AND status = ?", virtualInventoryID, models.VirtualStockStatusAvailable)
+			Scopes(allocatableVirtualStock(virtualInventoryID))
 
-		// 根据配置决定发货顺序
-		deliveryOrder := ""
-		if s.cfg != nil {
-			deliveryOrder = s.cfg.Order.VirtualDeliveryOrder
-		}
-		if deliveryOrder == "newest" {
-			query = query.Order("created_at DESC")
-		} else if deliveryOrder == "oldest" {
-			query = query.Order("created_at ASC")
-		} else {
-			query = query.Order(s.getRandomOrderClause())
-		}
-
-		if err := query.Limit(quantity).Find(&stocks).Error; err != nil {
+		// 发货顺序：虚拟库存 > 全局设置
+		stocks, err := s.findDeliverableStock(query, s.resolveDeliveryOrder(inv.DeliveryOrder), quantity)
+		if err != nil {
 			return err
 		}
 
@@ -1205,7 +1291,7 @@ func (s *VirtualInventoryService) GetAvailableCountForProductByAttributes(produc
 
 			var count int64
 			if err := s.db.Model(&models.VirtualProductStock{}).
-				Where("virtual_inventory_id = ? AND status = ?", binding.VirtualInventoryID, models.VirtualStockStatusAvailable).
+				Scopes(allocatableVirtualStock(binding.VirtualInventoryID)).
 				Count(&count).Error; err != nil {
 				return 0, err
 			}
@@ -1254,7 +1340,7 @@ func (s *VirtualInventoryService) GetAvailableCountForProductByAttributes(produc
 
 				var count int64
 				if err := s.db.Model(&models.VirtualProductStock{}).
-					Where("virtual_inventory_id = ? AND status = ?", binding.VirtualInventoryID, models.VirtualStockStatusAvailable).
+					Scopes(allocatableVirtualStock(binding.VirtualInventoryID)).
 					Count(&count).Error; err != nil {
 					continue
 				}
@@ -1724,7 +1810,8 @@ func (s *VirtualInventoryService) executeScriptDeliveryWithDB(db *gorm.DB, order
 			for i, stock := range stocks {
 				if i < len(result.Items) {
 					updates := map[string]interface{}{
-						"content": result.Items[i].Content,
+						"content":      result.Items[i].Content,
+						"content_hash": models.HashVirtualStockContent(result.Items[i].Content),
 					}
 					if result.Items[i].Remark != "" {
 						updates["remark"] = result.Items[i].Remark
@@ -2026,7 +2113,7 @@ func (s *VirtualInventoryService) GetAvailableCountForProduct(productID uint) (i
 
 		var count int64
 		if err := s.db.Model(&models.VirtualProductStock{}).
-			Where("virtual_inventory_id = ? AND status = ?", binding.VirtualInventoryID, models.VirtualStockStatusAvailable).
+			Scopes(allocatableVirtualStock(binding.VirtualInventoryID)).
 			Count(&count).Error; err != nil {
 			continue
 		}
@@ -2048,6 +2135,7 @@ func (s *VirtualInventoryService) GetStockStatsForProduct(productID uint) (map[s
 		"available": 0,
 		"reserved":  0,
 		"sold":      0,
+		"revoked":   0,
 	}
 
 	for _, binding := range bindings {
@@ -2058,6 +2146,7 @@ func (s *VirtualInventoryService) GetStockStatsForProduct(productID uint) (map[s
 		stats["available"] += binding.VirtualInventory.Available
 		stats["reserved"] += binding.VirtualInventory.Reserved
 		stats["sold"] += binding.VirtualInventory.Sold
+		stats["revoked"] += binding.VirtualInventory.Revoked
 	}
 
 	return stats, nil
@@ -2113,7 +2202,7 @@ func (s *VirtualInventoryService) ImportStockForProduct(productID uint, content
 	}
 
 	// 使用第一个绑定的虚拟库存
-	return s.ImportFromText(bindings[0].VirtualInventoryID, content, importedBy)
+	return s.ImportFromText(bindings[0].VirtualInventoryID, content, importedBy, nil)
 }
 
 // ImportStockFromFileForProduct 为商品从文件导入虚拟库存
@@ -2128,7 +2217,7 @@ func (s *VirtualInventoryService) ImportStockFromFileForProduct(productID uint,
 	}
 
 	// 使用第一个绑定的虚拟库存
-	return s.ImportFromExcel(bindings[0].VirtualInventoryID, filePath, importedBy)
+	return s.ImportFromExcel(bindings[0].VirtualInventoryID, filePath, importedBy, nil)
 }
 
 // GetFirstBindingForProduct 获取商品的第一个虚拟库存绑定
@@ -14,7 +14,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func newVirtualInventoryServiceTestDB(t *testing.T) (*VirtualInventoryService, *gorm.DB) {
+func newVirtualInventoryServiceTestDB(t testing.TB) (*VirtualInventoryService, *gorm.DB) {
 	t.Helper()
 
 	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
@@ -182,7 +182,7 @@ func TestManualStockOperationsReturnBizErrors(t *testing.T) {
 func TestVirtualInventoryValidationReturnsBizErrors(t *testing.T) {
 	svc, _ := newVirtualInventoryServiceTestDB(t)
 
-	_, err := svc.ImportFromText(1, strings.Repeat(" \n", 2), "tester")
+	_, err := svc.ImportFromText(1, strings.Repeat(" \n", 2), "tester", nil)
 	importErr := requireBizErr(t, err, "virtual_inventory.importNoValidData")
 	if got := importErr.Params["source"]; got != "text" {
 		t.Fatalf("expected source=text, got %#v", importErr.Params)
@@ -388,3 +388,58 @@ func TestGetStockByOrderNoHidesPresentationWhenInventoryDisablesIt(t *testing.T)
 		}
 	}
 }
+
+func TestListVirtualInventoriesUsesConstantQueries(t *testing.T) {
+	svc, db := newVirtualInventoryServiceTestDB(t)
+
+	queries, counting := 0, false
+	counter := func(*gorm.DB) {
+		if counting {
+			queries++
+		}
+	}
+	_ = db.Callback().Query().After("gorm:query").Register("test:count_queries", counter)
+	_ = db.Callback().Row().After("gorm:row").Register("test:count_queries", counter)
+
+	countListQueries := func() int {
+		queries, counting = 0, true
+		defer func() { counting = false }()
+		if _, _, err := svc.ListVirtualInventories(1, 50, ""); err != nil {
+			t.Fatalf("list virtual inventories: %v", err)
+		}
+		return queries
+	}
+
+	createInventories := func(from, to int) {
+		for i := from; i < to; i++ {
+			inv := models.VirtualInventory{Name: fmt.Sprintf("inv-%d", i), SKU: fmt.Sprintf("INV-%d", i), IsActive: true}
+			if err := db.Create(&inv).Error; err != nil {
+				t.Fatalf("create inventory: %v", err)
+			}
+			for _, status := range []models.VirtualProductStockStatus{models.VirtualStockStatusAvailable, models.VirtualStockStatusSold} {
+				stock := models.VirtualProductStock{VirtualInventoryID: inv.ID, Content: fmt.Sprintf("code-%d-%s", i, status), Status: status}
+				if err := db.Create(&stock).Error; err != nil {
+					t.Fatalf("create stock: %v", err)
+				}
+			}
+		}
+	}
+
+	createInventories(0, 2)
+	small := countListQueries()
+	createInventories(2, 20)
+	large := countListQueries()
+	if small != large {
+		t.Fatalf("query count should not grow with page size: %d vs %d", small, large)
+	}
+
+	list, total, err := svc.ListVirtualInventories(1, 50, "")
+	if err != nil || total != 20 || len(list) != 20 {
+		t.Fatalf("unexpected list result: total=%d len=%d err=%v", total, len(list), err)
+	}
+	for _, item := range list {
+		if item.Total != 2 || item.Available != 1 || item.Sold != 1 {
+			t.Fatalf("unexpected stats for %s: %+v", item.SKU, item)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"auralogic/internal/models"
+	"auralogic/internal/pkg/dbutil"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -59,16 +60,10 @@ func (s *ScriptDeliveryService) storageSetValue(virtualInventoryID uint, key, va
 		UpdatedAt:          now,
 	}
 
-	return s.db.Clauses(clause.OnConflict{
-		Columns: []clause.Column{
-			{Name: "virtual_inventory_id"},
-			{Name: "key"},
-		},
-		DoUpdates: clause.Assignments(map[string]interface{}{
-			"value":      value,
-			"updated_at": now,
-		}),
-	}).Create(&entry).Error
+	return s.db.Clauses(dbutil.UpsertOnConflict([]string{"virtual_inventory_id", "key"}, map[string]interface{}{
+		"value":      value,
+		"updated_at": now,
+	})).Create(&entry).Error
 }
 
 func (s *ScriptDeliveryService) storageDeleteKey(virtualInventoryID uint, key string) error {
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+)
+
+// ResendVirtualItemsEmail 买家主动重发虚拟商品邮件，不受订单邮件通知偏好限制
+func (s *OrderService) ResendVirtualItemsEmail(order *models.Order) error {
+	if s.emailService == nil || order == nil || order.UserEmail == "" {
+		return bizerr.New("order.virtualEmailUnavailable", "Virtual item email is unavailable for this order")
+	}
+	var delivered int64
+	if err := s.emailService.db.Model(&models.VirtualProductStock{}).
+		Where("order_no = ? AND status = ? AND delivered_at IS NOT NULL", order.OrderNo, models.VirtualStockStatusSold).
+		Count(&delivered).Error; err != nil {
+		return err
+	}
+	if delivered == 0 {
+		return bizerr.New("order.virtualProductsNotDelivered", "Virtual products have not been delivered yet")
+	}
+	return s.emailService.SendVirtualItemsEmail(order, int(delivered))
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestResendVirtualItemsEmailRequiresDeliveredStock(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.VirtualProductStock{})
+	svc := &OrderService{emailService: &EmailService{db: db}}
+	order := &models.Order{OrderNo: "ORDER-1", UserEmail: "buyer@example.com"}
+
+	requireOrderBizErr(t, svc.ResendVirtualItemsEmail(order), "order.virtualProductsNotDelivered")
+	requireOrderBizErr(t, (&OrderService{}).ResendVirtualItemsEmail(order), "order.virtualEmailUnavailable")
+}
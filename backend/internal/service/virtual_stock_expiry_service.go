@@ -0,0 +1,259 @@
+package service
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+const virtualStockExpiryCheckInterval = time.Hour
+
+// VirtualStockExpiryReportRow 按虚拟库存汇总的临期/过期库存及估算价值
+type VirtualStockExpiryReportRow struct {
+	VirtualInventoryID uint       `json:"virtual_inventory_id"`
+	Name               string     `json:"name"`
+	SKU                string     `json:"sku"`
+	ExpiringCount      int64      `json:"expiring_count"`
+	ExpiredCount       int64      `json:"expired_count"` // 已过期但尚未被定时任务标记失效
+	EarliestExpiresAt  *time.Time `json:"earliest_expires_at,omitempty"`
+	UnitPriceMinor     int64      `json:"unit_price_minor"` // 绑定商品中的最低售价
+	ExpiringValueMinor int64      `json:"expiring_value_minor"`
+	BoundProductCount  int        `json:"bound_product_count"`
+	ExpiryWarnedCount  int64      `json:"expiry_warned_count"`
+	WarningDays        int        `json:"warning_days"`
+}
+
+// expiryWarningDays 临期提醒天数，未配置时为 7 天
+func (s *VirtualInventoryService) expiryWarningDays() int {
+	if s.cfg != nil && s.cfg.Order.VirtualStockExpiryWarningDays > 0 {
+		return s.cfg.Order.VirtualStockExpiryWarningDays
+	}
+	return 7
+}
+
+// SetBatchExpiry 设置批次内未售出库存项的有效期，expiresAt 为 nil 时清除有效期
+func (s *VirtualInventoryService) SetBatchExpiry(virtualInventoryID uint, batchNo string, expiresAt *time.Time, operator string) (int64, error) {
+	batchNo = strings.TrimSpace(batchNo)
+	if batchNo == "" {
+		return 0, bizerr.New("virtual_inventory.batchNoRequired", "Batch number is required")
+	}
+	result := s.db.Model(&models.VirtualProductStock{}).
+		Where("virtual_inventory_id = ? AND batch_no = ? AND status IN ?", virtualInventoryID, batchNo,
+			[]models.VirtualProductStockStatus{models.VirtualStockStatusAvailable, models.VirtualStockStatusReserved}).
+		Updates(map[string]interface{}{"expires_at": expiresAt, "expiry_warned_at": nil})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, bizerr.New("virtual_inventory.batchNotFound", "No unsold stock items found in this batch").
+			WithParams(map[string]interface{}{"batch_no": batchNo})
+	}
+
+	reason := "Clear batch expiry"
+	if expiresAt != nil {
+		reason = "Set batch expiry: " + expiresAt.Format(time.RFC3339)
+	}
+	s.createVirtualInventoryLog(s.db, virtualInventoryID, models.InventoryLogTypeAdjust, int(result.RowsAffected), "", batchNo, operator, reason)
+	return result.RowsAffected, nil
+}
+
+// ExpireStock 将已过期的可用库存项标记为失效，并按库存写入 expire 变动记录
+func (s *VirtualInventoryService) ExpireStock(now time.Time) (int64, error) {
+	var rows []struct {
+		VirtualInventoryID uint
+		Count              int
+	}
+	if err := s.db.Model(&models.VirtualProductStock{}).
+		Select("virtual_inventory_id, COUNT(*) as count").
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", models.VirtualStockStatusAvailable, now).
+		Group("virtual_inventory_id").
+		Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, row := range rows {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&models.VirtualProductStock{}).
+				Where("virtual_inventory_id = ? AND status = ? AND expires_at IS NOT NULL AND expires_at <= ?",
+					row.VirtualInventoryID, models.VirtualStockStatusAvailable, now).
+				Update("status", models.VirtualStockStatusInvalid)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				s.createVirtualInventoryLog(tx, row.VirtualInventoryID, models.InventoryLogTypeExpire, int(result.RowsAffected), "", "", "system", "Stock items expired")
+				total += result.RowsAffected
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// FlagExpiringStock 标记在提醒窗口内即将过期的可用库存项（每项只标记一次）
+func (s *VirtualInventoryService) FlagExpiringStock(now time.Time) (int64, error) {
+	deadline := now.AddDate(0, 0, s.expiryWarningDays())
+	result := s.db.Model(&models.VirtualProductStock{}).
+		Where("status = ? AND expiry_warned_at IS NULL AND expires_at > ? AND expires_at <= ?",
+			models.VirtualStockStatusAvailable, now, deadline).
+		Update("expiry_warned_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// GetExpiryReport 临期库存报表：提醒窗口内即将过期（及已过期未处理）的可用库存，
+// 按虚拟库存汇总数量并以绑定商品的最低售价估算价值
+func (s *VirtualInventoryService) GetExpiryReport(days int) ([]VirtualStockExpiryReportRow, error) {
+	if days <= 0 {
+		days = s.expiryWarningDays()
+	}
+	now := models.NowFunc()
+	deadline := now.AddDate(0, 0, days)
+
+	var stocks []models.VirtualProductStock
+	if err := s.db.Select("id", "virtual_inventory_id", "expires_at", "expiry_warned_at").
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", models.VirtualStockStatusAvailable, deadline).
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	if len(stocks) == 0 {
+		return []VirtualStockExpiryReportRow{}, nil
+	}
+
+	rowsByInventory := make(map[uint]*VirtualStockExpiryReportRow)
+	inventoryIDs := make([]uint, 0)
+	for _, stock := range stocks {
+		row, ok := rowsByInventory[stock.VirtualInventoryID]
+		if !ok {
+			row = &VirtualStockExpiryReportRow{VirtualInventoryID: stock.VirtualInventoryID, WarningDays: days}
+			rowsByInventory[stock.VirtualInventoryID] = row
+			inventoryIDs = append(inventoryIDs, stock.VirtualInventoryID)
+		}
+		if stock.ExpiresAt.After(now) {
+			row.ExpiringCount++
+		} else {
+			row.ExpiredCount++
+		}
+		if stock.ExpiryWarnedAt != nil {
+			row.ExpiryWarnedCount++
+		}
+		if row.EarliestExpiresAt == nil || stock.ExpiresAt.Before(*row.EarliestExpiresAt) {
+			expiresAt := *stock.ExpiresAt
+			row.EarliestExpiresAt = &expiresAt
+		}
+	}
+
+	var inventories []models.VirtualInventory
+	if err := s.db.Unscoped().Select("id", "name", "sku").Where("id IN ?", inventoryIDs).Find(&inventories).Error; err != nil {
+		return nil, err
+	}
+	for _, inventory := range inventories {
+		rowsByInventory[inventory.ID].Name = inventory.Name
+		rowsByInventory[inventory.ID].SKU = inventory.SKU
+	}
+
+	var prices []struct {
+		VirtualInventoryID uint
+		Price              int64
+	}
+	if err := s.db.Table("product_virtual_inventory_bindings AS b").
+		Select("b.virtual_inventory_id, p.price").
+		Joins("JOIN products p ON p.id = b.product_id AND p.deleted_at IS NULL").
+		Where("b.virtual_inventory_id IN ?", inventoryIDs).
+		Scan(&prices).Error; err != nil {
+		return nil, err
+	}
+	for _, price := range prices {
+		row := rowsByInventory[price.VirtualInventoryID]
+		if row.BoundProductCount == 0 || price.Price < row.UnitPriceMinor {
+			row.UnitPriceMinor = price.Price
+		}
+		row.BoundProductCount++
+	}
+
+	report := make([]VirtualStockExpiryReportRow, 0, len(rowsByInventory))
+	for _, id := range inventoryIDs {
+		row := rowsByInventory[id]
+		row.ExpiringValueMinor = row.UnitPriceMinor * (row.ExpiringCount + row.ExpiredCount)
+		report = append(report, *row)
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].EarliestExpiresAt.Before(*report[j].EarliestExpiresAt)
+	})
+	return report, nil
+}
+
+// VirtualStockExpiryService 定时处理虚拟库存有效期：过期失效、临期标记
+type VirtualStockExpiryService struct {
+	inventory *VirtualInventoryService
+
+	lifecycleMu sync.Mutex
+	running     bool
+	scheduler   *BackgroundScheduler
+}
+
+func NewVirtualStockExpiryService(inventory *VirtualInventoryService) *VirtualStockExpiryService {
+	return &VirtualStockExpiryService{inventory: inventory}
+}
+
+// Start 启动有效期检查服务
+func (s *VirtualStockExpiryService) Start() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	if s.running {
+		return
+	}
+
+	scheduler := backgroundSchedulerForService()
+	if err := scheduler.Register(BackgroundJob{
+		Name:       "virtual_stock_expiry",
+		Interval:   virtualStockExpiryCheckInterval,
+		RunOnStart: true,
+		LockTTL:    virtualStockExpiryCheckInterval,
+		Jitter:     virtualStockExpiryCheckInterval / 10,
+		Run: func() error {
+			s.runCheck()
+			return nil
+		},
+	}); err != nil {
+		log.Printf("virtual stock expiry background job register failed: %v", err)
+		return
+	}
+	s.scheduler = scheduler
+	s.running = true
+}
+
+// Stop 停止有效期检查服务
+func (s *VirtualStockExpiryService) Stop() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	if !s.running {
+		return
+	}
+	s.running = false
+	s.scheduler.Remove("virtual_stock_expiry")
+	s.scheduler = nil
+}
+
+func (s *VirtualStockExpiryService) runCheck() {
+	now := models.NowFunc()
+	if expired, err := s.inventory.ExpireStock(now); err != nil {
+		log.Printf("virtual stock expiry check failed: %v", err)
+	} else if expired > 0 {
+		log.Printf("virtual stock expiry: %d items marked invalid", expired)
+	}
+	if flagged, err := s.inventory.FlagExpiringStock(now); err != nil {
+		log.Printf("virtual stock expiry warning check failed: %v", err)
+	} else if flagged > 0 {
+		log.Printf("virtual stock expiry: %d items expire within %d days", flagged, s.inventory.expiryWarningDays())
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+func TestVirtualStockExpirySkipsAllocationAndReportsValue(t *testing.T) {
+	svc, db := newVirtualInventoryServiceTestDB(t)
+
+	inventory := &models.VirtualInventory{Name: "Gift cards", SKU: "GC", Type: models.VirtualInventoryTypeStatic, IsActive: true}
+	if err := db.Create(inventory).Error; err != nil {
+		t.Fatalf("create inventory: %v", err)
+	}
+	product := &models.Product{SKU: "GC-10", Name: "Gift card", Price: 1000, ProductType: models.ProductTypeVirtual}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	if err := db.Create(&models.ProductVirtualInventoryBinding{ProductID: product.ID, VirtualInventoryID: inventory.ID, AttributesHash: "default"}).Error; err != nil {
+		t.Fatalf("create binding: %v", err)
+	}
+
+	now := models.NowFunc()
+	expired := now.Add(-time.Hour)
+	soon := now.Add(48 * time.Hour)
+	for _, stock := range []*models.VirtualProductStock{
+		{VirtualInventoryID: inventory.ID, Content: "OLD", Status: models.VirtualStockStatusAvailable, ExpiresAt: &expired},
+		{VirtualInventoryID: inventory.ID, Content: "SOON", Status: models.VirtualStockStatusAvailable, ExpiresAt: &soon, BatchNo: "B1"},
+	} {
+		if err := db.Create(stock).Error; err != nil {
+			t.Fatalf("create stock: %v", err)
+		}
+	}
+
+	// 已过期的库存项即使尚未被定时任务处理也不能分配
+	count, err := svc.GetAvailableCountForProduct(product.ID)
+	if err != nil || count != 1 {
+		t.Fatalf("expected 1 allocatable item, got %d (%v)", count, err)
+	}
+
+	report, err := svc.GetExpiryReport(7)
+	if err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+	if len(report) != 1 || report[0].ExpiringCount != 1 || report[0].ExpiredCount != 1 || report[0].ExpiringValueMinor != 2000 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	if n, err := svc.ExpireStock(now); err != nil || n != 1 {
+		t.Fatalf("expected 1 expired item, got %d (%v)", n, err)
+	}
+	if n, err := svc.FlagExpiringStock(now); err != nil || n != 1 {
+		t.Fatalf("expected 1 flagged item, got %d (%v)", n, err)
+	}
+	if n, _ := svc.FlagExpiringStock(now); n != 0 {
+		t.Fatalf("expected flagged items to be skipped, got %d", n)
+	}
+	var invalid int64
+	db.Model(&models.VirtualProductStock{}).Where("content = ? AND status = ?", "OLD", models.VirtualStockStatusInvalid).Count(&invalid)
+	if invalid != 1 {
+		t.Fatalf("expected expired stock to be invalidated")
+	}
+	var logs int64
+	db.Model(&models.InventoryLog{}).Where("type = ?", models.InventoryLogTypeExpire).Count(&logs)
+	if logs != 1 {
+		t.Fatalf("expected one expire log, got %d", logs)
+	}
+
+	// 清除批次有效期后重置临期标记
+	if n, err := svc.SetBatchExpiry(inventory.ID, "B1", nil, "tester"); err != nil || n != 1 {
+		t.Fatalf("clear batch expiry: %d (%v)", n, err)
+	}
+	var cleared models.VirtualProductStock
+	db.Where("batch_no = ?", "B1").First(&cleared)
+	if cleared.ExpiresAt != nil || cleared.ExpiryWarnedAt != nil {
+		t.Fatalf("expected expiry to be cleared: %+v", cleared)
+	}
+	requireBizErr(t, func() error {
+		_, err := svc.SetBatchExpiry(inventory.ID, "missing", nil, "tester")
+		return err
+	}(), "virtual_inventory.batchNotFound")
+}
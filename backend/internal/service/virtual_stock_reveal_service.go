@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+)
+
+// VirtualStockRevealViewer 查看卡密明文的操作者
+type VirtualStockRevealViewer struct {
+	Type  string
+	ID    *uint
+	Email string
+	IP    string
+}
+
+// markStocksFirstViewed 记录买家首次查看卡密的时间，已记录的不覆盖
+func markStocksFirstViewed(db *gorm.DB, stockIDs []uint, now time.Time) error {
+	if db == nil || len(stockIDs) == 0 {
+		return nil
+	}
+	return db.Model(&models.VirtualProductStock{}).
+		Where("id IN ? AND status = ? AND first_viewed_at IS NULL", stockIDs, models.VirtualStockStatusSold).
+		Update("first_viewed_at", now).Error
+}
+
+func recordVirtualStockReveals(db *gorm.DB, stocks []models.VirtualProductStock, viewer VirtualStockRevealViewer) error {
+	if len(stocks) == 0 {
+		return nil
+	}
+	logs := make([]models.VirtualStockRevealLog, 0, len(stocks))
+	buyerStockIDs := make([]uint, 0, len(stocks))
+	for _, stock := range stocks {
+		logs = append(logs, models.VirtualStockRevealLog{
+			StockID:            stock.ID,
+			VirtualInventoryID: stock.VirtualInventoryID,
+			OrderNo:            stock.OrderNo,
+			ViewerType:         viewer.Type,
+			ViewerID:           viewer.ID,
+			ViewerEmail:        viewer.Email,
+			IP:                 viewer.IP,
+		})
+		buyerStockIDs = append(buyerStockIDs, stock.ID)
+	}
+	if err := db.Create(&logs).Error; err != nil {
+		return err
+	}
+	// 管理员查看不计入买家首次查看
+	if viewer.Type == models.VirtualStockRevealViewerAdmin {
+		return nil
+	}
+	return markStocksFirstViewed(db, buyerStockIDs, models.NowFunc())
+}
+
+// RevealStock 返回卡密明文并记录查看日志。orderNo 非空时要求库存项属于该订单且未被撤销（买家侧）
+func (s *VirtualInventoryService) RevealStock(stockID uint, orderNo string, viewer VirtualStockRevealViewer) (*models.VirtualProductStock, error) {
+	query := s.db.Where("id = ?", stockID)
+	if orderNo != "" {
+		query = query.Where("order_no = ?", orderNo)
+	}
+	var stock models.VirtualProductStock
+	if err := query.First(&stock).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, bizerr.New("virtual_inventory.stockItemNotFound", "Stock item not found")
+		}
+		return nil, err
+	}
+	if orderNo != "" && stock.Status != models.VirtualStockStatusSold {
+		return nil, bizerr.New("virtual_inventory.stockItemNotFound", "Stock item not found")
+	}
+	if err := recordVirtualStockReveals(s.db, []models.VirtualProductStock{stock}, viewer); err != nil {
+		return nil, err
+	}
+	if viewer.Type != models.VirtualStockRevealViewerAdmin && stock.FirstViewedAt == nil {
+		if err := s.db.Model(&models.VirtualProductStock{}).Select("first_viewed_at").Where("id = ?", stock.ID).Scan(&stock.FirstViewedAt).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &stock, nil
+}
+
+// RecordLinkReveals 通过邮件签名链接提取卡密时记录查看日志
+func (s *VirtualInventoryService) RecordLinkReveals(orderNo string, stocks []VirtualDeliveryStock, ip string) error {
+	if len(stocks) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(stocks))
+	for _, stock := range stocks {
+		ids = append(ids, stock.ID)
+	}
+	var loaded []models.VirtualProductStock
+	if err := s.db.Select("id, virtual_inventory_id, order_no").Where("id IN ? AND order_no = ?", ids, orderNo).Find(&loaded).Error; err != nil {
+		return err
+	}
+	return recordVirtualStockReveals(s.db, loaded, VirtualStockRevealViewer{Type: models.VirtualStockRevealViewerLink, IP: ip})
+}
+
+// ListStockReveals 卡密明文查看记录，按时间倒序
+func (s *VirtualInventoryService) ListStockReveals(stockID uint) ([]models.VirtualStockRevealLog, error) {
+	var logs []models.VirtualStockRevealLog
+	err := s.db.Where("stock_id = ?", stockID).Order("id DESC").Limit(200).Find(&logs).Error
+	return logs, err
+}
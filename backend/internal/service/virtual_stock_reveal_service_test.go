@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+func TestRevealStockLogsViewerAndKeepsFirstView(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.VirtualProductStock{}, &models.VirtualStockRevealLog{})
+	svc := NewVirtualInventoryService(db)
+	delivered := time.Now()
+	sold := models.VirtualProductStock{VirtualInventoryID: 1, Content: "KEY-ABCD-1234", Status: models.VirtualStockStatusSold, OrderNo: "ORDER-1", DeliveredAt: &delivered}
+	revoked := models.VirtualProductStock{VirtualInventoryID: 1, Content: "KEY-2", Status: models.VirtualStockStatusRevoked, OrderNo: "ORDER-1", DeliveredAt: &delivered}
+	for _, stock := range []*models.VirtualProductStock{&sold, &revoked} {
+		if err := db.Create(stock).Error; err != nil {
+			t.Fatalf("create stock: %v", err)
+		}
+	}
+
+	adminID, userID := uint(7), uint(9)
+	if _, err := svc.RevealStock(sold.ID, "", VirtualStockRevealViewer{Type: models.VirtualStockRevealViewerAdmin, ID: &adminID}); err != nil {
+		t.Fatalf("admin reveal: %v", err)
+	}
+	var afterAdmin models.VirtualProductStock
+	db.First(&afterAdmin, sold.ID)
+	if afterAdmin.FirstViewedAt != nil {
+		t.Fatal("admin reveal must not count as buyer first view")
+	}
+
+	buyer := VirtualStockRevealViewer{Type: models.VirtualStockRevealViewerUser, ID: &userID, IP: "127.0.0.1"}
+	first, err := svc.RevealStock(sold.ID, "ORDER-1", buyer)
+	if err != nil || first.Content != "KEY-ABCD-1234" || first.FirstViewedAt == nil {
+		t.Fatalf("buyer reveal: stock=%+v err=%v", first, err)
+	}
+	if _, err := svc.RevealStock(sold.ID, "ORDER-1", buyer); err != nil {
+		t.Fatalf("second reveal: %v", err)
+	}
+	var stored models.VirtualProductStock
+	db.First(&stored, sold.ID)
+	if stored.FirstViewedAt == nil || !stored.FirstViewedAt.Equal(*first.FirstViewedAt) {
+		t.Fatalf("expected first view to be kept, got %v", stored.FirstViewedAt)
+	}
+
+	_, err = svc.RevealStock(sold.ID, "ORDER-2", buyer)
+	requireOrderBizErr(t, err, "virtual_inventory.stockItemNotFound")
+	_, err = svc.RevealStock(revoked.ID, "ORDER-1", buyer)
+	requireOrderBizErr(t, err, "virtual_inventory.stockItemNotFound")
+
+	logs, err := svc.ListStockReveals(sold.ID)
+	if err != nil || len(logs) != 3 {
+		t.Fatalf("expected 3 reveal logs, got %d err=%v", len(logs), err)
+	}
+	if logs[2].ViewerType != models.VirtualStockRevealViewerAdmin || logs[0].ViewerID == nil || *logs[0].ViewerID != userID {
+		t.Fatalf("unexpected reveal logs: %+v", logs)
+	}
+}
+
+func TestMaskVirtualStockContent(t *testing.T) {
+	cases := map[string]string{
+		"":                         "",
+		"AB":                       "**",
+		"ABCD-1234":                "*****1234",
+		"XXXX-XXXX-XXXX-XXXX-9876": "********9876",
+	}
+	for input, want := range cases {
+		if got := models.MaskVirtualStockContent(input); got != want {
+			t.Errorf("MaskVirtualStockContent(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/dbutil"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VirtualStockRevokeInput 撤销已发货库存项，可选从同一库存补发
+type VirtualStockRevokeInput struct {
+	Reason     string
+	Reissue    bool
+	Operator   string
+	OperatorID *uint
+}
+
+// VirtualStockRevokeResult 撤销结果，Replacement 为补发的库存项
+type VirtualStockRevokeResult struct {
+	Revoked     *models.VirtualProductStock `json:"revoked"`
+	Replacement *models.VirtualProductStock `json:"replacement,omitempty"`
+}
+
+// RevokeStock 撤销已发货的卡密（拒付、发错库存等）。
+// 撤销的库存项不会回到可用库存；补发只支持静态库存，按发货顺序配置选取
+func (s *VirtualInventoryService) RevokeStock(stockID uint, input VirtualStockRevokeInput) (*VirtualStockRevokeResult, error) {
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, bizerr.New("virtual_inventory.revokeReasonRequired", "Revoke reason is required")
+	}
+	operator := input.Operator
+	if operator == "" {
+		operator = "admin"
+	}
+
+	result := &VirtualStockRevokeResult{}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := dbutil.LockForUpdate(tx, &models.VirtualProductStock{}, "id = ?", stockID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return bizerr.New("virtual_inventory.stockItemNotFound", "Stock item not found")
+			}
+			return err
+		}
+		var stock models.VirtualProductStock
+		if err := tx.First(&stock, stockID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return bizerr.New("virtual_inventory.stockItemNotFound", "Stock item not found")
+			}
+			return err
+		}
+		if stock.Status != models.VirtualStockStatusSold || stock.OrderID == nil {
+			return bizerr.New("virtual_inventory.stockItemNotDelivered", "Only delivered stock items can be revoked").
+				WithParams(map[string]interface{}{"status": string(stock.Status)})
+		}
+
+		var inventory models.VirtualInventory
+		if err := tx.Unscoped().Select("id", "type", "delivery_order").First(&inventory, stock.VirtualInventoryID).Error; err != nil {
+			return err
+		}
+		if input.Reissue && inventory.Type == models.VirtualInventoryTypeScript {
+			return bizerr.New("virtual_inventory.reissueScriptUnsupported", "Script inventories cannot reissue a replacement")
+		}
+
+		var replacement *models.VirtualProductStock
+		if input.Reissue {
+			query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Scopes(allocatableVirtualStock(stock.VirtualInventoryID))
+			candidates, err := s.findDeliverableStock(query, s.resolveDeliveryOrder(inventory.DeliveryOrder), 1)
+			if err != nil {
+				return err
+			}
+			if len(candidates) == 0 {
+				return newVirtualBindingInsufficientAvailableError(1, 0)
+			}
+			replacement = &candidates[0]
+			replacement.MarkAsSold(*stock.OrderID, stock.OrderNo)
+			replacement.DeliveredBy = input.OperatorID
+			if err := tx.Model(replacement).Updates(map[string]interface{}{
+				"status":       replacement.Status,
+				"order_id":     replacement.OrderID,
+				"order_no":     replacement.OrderNo,
+				"delivered_at": replacement.DeliveredAt,
+				"delivered_by": replacement.DeliveredBy,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		now := models.NowFunc()
+		stock.Status = models.VirtualStockStatusRevoked
+		stock.RevokedAt = &now
+		stock.RevokedBy = input.OperatorID
+		stock.RevokeReason = reason
+		updates := map[string]interface{}{
+			"status":        stock.Status,
+			"revoked_at":    stock.RevokedAt,
+			"revoked_by":    stock.RevokedBy,
+			"revoke_reason": stock.RevokeReason,
+		}
+		if replacement != nil {
+			stock.ReplacementID = &replacement.ID
+			updates["replacement_id"] = replacement.ID
+		}
+		if err := tx.Model(&stock).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		s.createVirtualInventoryLog(tx, stock.VirtualInventoryID, models.InventoryLogTypeRevoke, 1, stock.OrderNo, "", operator, "Revoke delivered stock: "+reason)
+		note := "Revoked virtual stock #" + strconv.FormatUint(uint64(stock.ID), 10) + ": " + reason
+		if replacement != nil {
+			s.createVirtualInventoryLog(tx, stock.VirtualInventoryID, models.InventoryLogTypeDeliver, 1, stock.OrderNo, "", operator, "Reissue revoked stock")
+			note += "\nReissued as #" + strconv.FormatUint(uint64(replacement.ID), 10)
+		}
+		if err := AddOrderNoteTx(tx, *stock.OrderID, input.OperatorID, models.OrderNoteSourceVirtualRevoke, note); err != nil {
+			return err
+		}
+
+		result.Revoked = &stock
+		result.Replacement = replacement
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
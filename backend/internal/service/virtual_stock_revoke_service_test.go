@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestRevokeStockReissuesFromSameInventory(t *testing.T) {
+	svc, db := newVirtualInventoryServiceTestDB(t)
+	if err := db.AutoMigrate(&models.OrderNote{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	inventory := &models.VirtualInventory{Name: "Gift cards", Type: models.VirtualInventoryTypeStatic, IsActive: true}
+	if err := db.Create(inventory).Error; err != nil {
+		t.Fatalf("create inventory: %v", err)
+	}
+	orderID := uint(42)
+	sold := &models.VirtualProductStock{VirtualInventoryID: inventory.ID, Content: "CARD-1", Status: models.VirtualStockStatusAvailable}
+	spare := &models.VirtualProductStock{VirtualInventoryID: inventory.ID, Content: "CARD-2", Status: models.VirtualStockStatusAvailable}
+	for _, stock := range []*models.VirtualProductStock{sold, spare} {
+		if err := db.Create(stock).Error; err != nil {
+			t.Fatalf("create stock: %v", err)
+		}
+	}
+	if err := db.Model(sold).Updates(map[string]interface{}{
+		"status": models.VirtualStockStatusSold, "order_id": orderID, "order_no": "VR-1",
+	}).Error; err != nil {
+		t.Fatalf("mark sold: %v", err)
+	}
+
+	requireBizErr(t, func() error {
+		_, err := svc.RevokeStock(sold.ID, VirtualStockRevokeInput{})
+		return err
+	}(), "virtual_inventory.revokeReasonRequired")
+	requireBizErr(t, func() error {
+		_, err := svc.RevokeStock(spare.ID, VirtualStockRevokeInput{Reason: "chargeback"})
+		return err
+	}(), "virtual_inventory.stockItemNotDelivered")
+
+	result, err := svc.RevokeStock(sold.ID, VirtualStockRevokeInput{Reason: "chargeback", Reissue: true})
+	if err != nil {
+		t.Fatalf("revoke failed: %v", err)
+	}
+	if result.Replacement == nil || result.Replacement.ID != spare.ID {
+		t.Fatalf("expected CARD-2 to be reissued, got %+v", result.Replacement)
+	}
+
+	var revoked, replacement models.VirtualProductStock
+	db.First(&revoked, sold.ID)
+	db.First(&replacement, spare.ID)
+	if revoked.Status != models.VirtualStockStatusRevoked || revoked.RevokeReason != "chargeback" ||
+		revoked.ReplacementID == nil || *revoked.ReplacementID != spare.ID {
+		t.Fatalf("unexpected revoked stock: %+v", revoked)
+	}
+	if replacement.Status != models.VirtualStockStatusSold || replacement.OrderNo != "VR-1" ||
+		replacement.OrderID == nil || *replacement.OrderID != orderID {
+		t.Fatalf("unexpected replacement stock: %+v", replacement)
+	}
+
+	stats, err := svc.GetStockStats(inventory.ID)
+	if err != nil {
+		t.Fatalf("stats failed: %v", err)
+	}
+	if stats["revoked"] != 1 || stats["sold"] != 1 || stats["available"] != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// 没有可用库存时无法补发，撤销也不会生效
+	requireBizErr(t, func() error {
+		_, err := svc.RevokeStock(spare.ID, VirtualStockRevokeInput{Reason: "wrong pool", Reissue: true})
+		return err
+	}(), "virtual_binding.insufficientAvailable")
+
+	var notes int64
+	db.Model(&models.OrderNote{}).Where("order_id = ? AND source = ?", orderID, models.OrderNoteSourceVirtualRevoke).Count(&notes)
+	if notes != 1 {
+		t.Fatalf("expected one revoke note, got %d", notes)
+	}
+}
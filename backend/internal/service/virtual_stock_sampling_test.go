@@ -0,0 +1,101 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"auralogic/internal/models"
+	"gorm.io/gorm"
+)
+
+func seedSampledVirtualStock(t testing.TB, db *gorm.DB, inventoryID uint, count int) {
+	t.Helper()
+	stocks := make([]models.VirtualProductStock, 0, count)
+	for i := 0; i < count; i++ {
+		stocks = append(stocks, models.VirtualProductStock{
+			VirtualInventoryID: inventoryID,
+			Content:            fmt.Sprintf("CODE-%d", i),
+			Status:             models.VirtualStockStatusAvailable,
+		})
+	}
+	if err := db.CreateInBatches(stocks, 500).Error; err != nil {
+		t.Fatalf("seed stock: %v", err)
+	}
+}
+
+func TestSampleVirtualStockWrapsAroundPivot(t *testing.T) {
+	_, db := newVirtualInventoryServiceTestDB(t)
+	for _, key := range []int{10, 20, 30, 40} {
+		stock := &models.VirtualProductStock{VirtualInventoryID: 1, Content: fmt.Sprintf("K%d", key), Status: models.VirtualStockStatusAvailable, RandomKey: key}
+		if err := db.Create(stock).Error; err != nil {
+			t.Fatalf("create stock: %v", err)
+		}
+	}
+
+	stocks, err := sampleVirtualStock(db.Scopes(allocatableVirtualStock(1)), 25, 3)
+	if err != nil {
+		t.Fatalf("sample: %v", err)
+	}
+	var keys []int
+	for _, stock := range stocks {
+		keys = append(keys, stock.RandomKey)
+	}
+	if fmt.Sprint(keys) != "[30 40 10]" {
+		t.Fatalf("expected sampling from pivot with wrap-around, got %v", keys)
+	}
+}
+
+func TestRandomDeliveryUsesSamplingKeys(t *testing.T) {
+	svc, db := newVirtualInventoryServiceTestDB(t)
+	inventory := &models.VirtualInventory{Name: "Keys", Type: models.VirtualInventoryTypeStatic, IsActive: true, DeliveryOrder: models.VirtualDeliveryOrderRandom}
+	if err := db.Create(inventory).Error; err != nil {
+		t.Fatalf("create inventory: %v", err)
+	}
+	seedSampledVirtualStock(t, db, inventory.ID, 50)
+
+	var unkeyed int64
+	db.Model(&models.VirtualProductStock{}).Where("random_key <= 0").Count(&unkeyed)
+	if unkeyed != 0 {
+		t.Fatalf("new stock should get a sampling key, %d missing", unkeyed)
+	}
+
+	// 多次分配应覆盖不同的库存项，且全部分配完时不会遗漏
+	seen := make(map[uint]bool)
+	for i := 0; i < 10; i++ {
+		stocks, _, err := svc.AllocateStockFromInventory(inventory.ID, 5, fmt.Sprintf("ORDER-%d", i))
+		if err != nil || len(stocks) != 5 {
+			t.Fatalf("allocate round %d: %d items (%v)", i, len(stocks), err)
+		}
+		for _, stock := range stocks {
+			if seen[stock.ID] {
+				t.Fatalf("stock %d allocated twice", stock.ID)
+			}
+			seen[stock.ID] = true
+		}
+	}
+	if _, _, err := svc.AllocateStockFromInventory(inventory.ID, 1, "ORDER-X"); err == nil {
+		t.Fatalf("expected exhausted inventory")
+	}
+}
+
+// go test ./internal/service -run '^$' -bench VirtualStockRandom
+func BenchmarkVirtualStockRandomAllocation(b *testing.B) {
+	_, db := newVirtualInventoryServiceTestDB(b)
+	seedSampledVirtualStock(b, db, 1, 20000)
+
+	b.Run("order_by_random", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var stocks []models.VirtualProductStock
+			if err := db.Scopes(allocatableVirtualStock(1)).Order("RANDOM()").Limit(5).Find(&stocks).Error; err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("random_key_sampling", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := sampleVirtualStock(db.Scopes(allocatableVirtualStock(1)), models.NewVirtualStockRandomKey(), 5); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
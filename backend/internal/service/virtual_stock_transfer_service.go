@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 单次调拨的最大库存项数量
+const virtualStockTransferMaxItems = 10000
+
+// VirtualStockTransferInput 调拨源库存中的可用库存项，筛选条件可组合；Quantity 为 0 时转移全部匹配项
+type VirtualStockTransferInput struct {
+	TargetInventoryID uint
+	BatchNo           string
+	RemarkKeyword     string
+	StockIDs          []uint
+	Quantity          int
+	Reason            string
+	Operator          string
+	OperatorID        *uint
+}
+
+// TransferStock 将可用库存项转移到另一个静态虚拟库存（如按地区拆分库存池），
+// 库存项保留原批次号，两侧按批次写入 transfer 变动记录并保存调拨记录
+func (s *VirtualInventoryService) TransferStock(sourceInventoryID uint, input VirtualStockTransferInput) (*models.VirtualStockTransfer, error) {
+	if input.TargetInventoryID == 0 || input.TargetInventoryID == sourceInventoryID {
+		return nil, bizerr.New("virtual_inventory.transferTargetInvalid", "Choose a different target inventory")
+	}
+	if input.Quantity < 0 || input.Quantity > virtualStockTransferMaxItems || len(input.StockIDs) > virtualStockTransferMaxItems {
+		return nil, bizerr.Newf("virtual_inventory.transferQuantityInvalid", "Transfer quantity must be between 1 and %d", virtualStockTransferMaxItems).
+			WithParams(map[string]interface{}{"max": virtualStockTransferMaxItems})
+	}
+	operator := input.Operator
+	if operator == "" {
+		operator = "admin"
+	}
+
+	var inventories []models.VirtualInventory
+	if err := s.db.Select("id", "name", "type").Where("id IN ?", []uint{sourceInventoryID, input.TargetInventoryID}).Find(&inventories).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.VirtualInventory, len(inventories))
+	for _, inventory := range inventories {
+		if inventory.Type == models.VirtualInventoryTypeScript {
+			return nil, bizerr.New("virtual_inventory.transferScriptUnsupported", "Script inventories do not hold stock items")
+		}
+		byID[inventory.ID] = inventory
+	}
+	source, sourceOK := byID[sourceInventoryID]
+	target, targetOK := byID[input.TargetInventoryID]
+	if !sourceOK || !targetOK {
+		return nil, bizerr.New("virtual_inventory.notFound", "Virtual inventory not found")
+	}
+
+	filters := map[string]interface{}{}
+	record := &models.VirtualStockTransfer{
+		SourceInventoryID: sourceInventoryID,
+		TargetInventoryID: input.TargetInventoryID,
+		Reason:            strings.TrimSpace(input.Reason),
+		Operator:          operator,
+		OperatorID:        input.OperatorID,
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "batch_no").
+			Where("virtual_inventory_id = ? AND status = ?", sourceInventoryID, models.VirtualStockStatusAvailable)
+		if batchNo := strings.TrimSpace(input.BatchNo); batchNo != "" {
+			query = query.Where("batch_no = ?", batchNo)
+			filters["batch_no"] = batchNo
+		}
+		if keyword := strings.TrimSpace(input.RemarkKeyword); keyword != "" {
+			query = query.Where("remark LIKE ?", "%"+keyword+"%")
+			filters["remark_keyword"] = keyword
+		}
+		if len(input.StockIDs) > 0 {
+			query = query.Where("id IN ?", input.StockIDs)
+			filters["stock_ids"] = len(input.StockIDs)
+		}
+		limit := virtualStockTransferMaxItems
+		if input.Quantity > 0 {
+			limit = input.Quantity
+			filters["quantity"] = input.Quantity
+		}
+
+		var stocks []models.VirtualProductStock
+		if err := query.Order("id ASC").Limit(limit).Find(&stocks).Error; err != nil {
+			return err
+		}
+		if len(stocks) == 0 {
+			return bizerr.New("virtual_inventory.transferNoMatch", "No available stock items match the filters")
+		}
+		if input.Quantity > 0 && len(stocks) < input.Quantity {
+			return bizerr.Newf("virtual_inventory.transferInsufficient", "Only %d matching items are available", len(stocks)).
+				WithParams(map[string]interface{}{"requested": input.Quantity, "available": len(stocks)})
+		}
+
+		ids := make([]uint, 0, len(stocks))
+		batchCounts := make(map[string]int)
+		for _, stock := range stocks {
+			ids = append(ids, stock.ID)
+			batchCounts[stock.BatchNo]++
+		}
+		if err := tx.Model(&models.VirtualProductStock{}).Where("id IN ?", ids).
+			Update("virtual_inventory_id", input.TargetInventoryID).Error; err != nil {
+			return err
+		}
+
+		batchNos := make([]string, 0, len(batchCounts))
+		for batchNo := range batchCounts {
+			batchNos = append(batchNos, batchNo)
+		}
+		sort.Strings(batchNos)
+		suffix := ""
+		if record.Reason != "" {
+			suffix = ": " + record.Reason
+		}
+		for _, batchNo := range batchNos {
+			count := batchCounts[batchNo]
+			s.createVirtualInventoryLog(tx, sourceInventoryID, models.InventoryLogTypeTransfer, -count, "", batchNo, operator,
+				fmt.Sprintf("Transfer to %s (#%d)%s", target.Name, target.ID, suffix))
+			s.createVirtualInventoryLog(tx, input.TargetInventoryID, models.InventoryLogTypeTransfer, count, "", batchNo, operator,
+				fmt.Sprintf("Transfer from %s (#%d)%s", source.Name, source.ID, suffix))
+		}
+
+		record.Quantity = len(ids)
+		record.StockIDs = ids
+		record.BatchNos = batchNos
+		record.Filters = filters
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// ListStockTransfers 虚拟库存的调拨记录（调入与调出），新到旧
+func (s *VirtualInventoryService) ListStockTransfers(virtualInventoryID uint, page, limit int) ([]models.VirtualStockTransfer, int64, error) {
+	var (
+		items []models.VirtualStockTransfer
+		total int64
+	)
+	query := s.db.Model(&models.VirtualStockTransfer{}).
+		Where("source_inventory_id = ? OR target_inventory_id = ?", virtualInventoryID, virtualInventoryID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Omit("stock_ids").Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&items).Error
+	return items, total, err
+}
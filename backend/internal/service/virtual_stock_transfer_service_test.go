@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+
+	"auralogic/internal/models"
+)
+
+func TestTransferStockMovesFilteredItemsAndKeepsBatch(t *testing.T) {
+	svc, db := newVirtualInventoryServiceTestDB(t)
+	if err := db.AutoMigrate(&models.VirtualStockTransfer{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	source := &models.VirtualInventory{Name: "Global", Type: models.VirtualInventoryTypeStatic, IsActive: true}
+	target := &models.VirtualInventory{Name: "EU", Type: models.VirtualInventoryTypeStatic, IsActive: true}
+	script := &models.VirtualInventory{Name: "Script", Type: models.VirtualInventoryTypeScript, IsActive: true}
+	for _, inventory := range []*models.VirtualInventory{source, target, script} {
+		if err := db.Create(inventory).Error; err != nil {
+			t.Fatalf("create inventory: %v", err)
+		}
+	}
+	for _, stock := range []*models.VirtualProductStock{
+		{VirtualInventoryID: source.ID, Content: "A1", Remark: "EU", BatchNo: "B1", Status: models.VirtualStockStatusAvailable},
+		{VirtualInventoryID: source.ID, Content: "A2", Remark: "EU", BatchNo: "B1", Status: models.VirtualStockStatusAvailable},
+		{VirtualInventoryID: source.ID, Content: "A3", Remark: "US", BatchNo: "B1", Status: models.VirtualStockStatusAvailable},
+		{VirtualInventoryID: source.ID, Content: "A4", Remark: "EU", BatchNo: "B2", Status: models.VirtualStockStatusReserved},
+	} {
+		if err := db.Create(stock).Error; err != nil {
+			t.Fatalf("create stock: %v", err)
+		}
+	}
+
+	requireBizErr(t, func() error {
+		_, err := svc.TransferStock(source.ID, VirtualStockTransferInput{TargetInventoryID: source.ID})
+		return err
+	}(), "virtual_inventory.transferTargetInvalid")
+	requireBizErr(t, func() error {
+		_, err := svc.TransferStock(source.ID, VirtualStockTransferInput{TargetInventoryID: script.ID})
+		return err
+	}(), "virtual_inventory.transferScriptUnsupported")
+	requireBizErr(t, func() error {
+		_, err := svc.TransferStock(source.ID, VirtualStockTransferInput{TargetInventoryID: target.ID, RemarkKeyword: "EU", Quantity: 3})
+		return err
+	}(), "virtual_inventory.transferInsufficient")
+
+	record, err := svc.TransferStock(source.ID, VirtualStockTransferInput{TargetInventoryID: target.ID, RemarkKeyword: "EU", Operator: "tester"})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	if record.Quantity != 2 || len(record.BatchNos) != 1 || record.BatchNos[0] != "B1" {
+		t.Fatalf("unexpected transfer record: %+v", record)
+	}
+
+	var moved []models.VirtualProductStock
+	db.Where("virtual_inventory_id = ?", target.ID).Find(&moved)
+	if len(moved) != 2 || moved[0].BatchNo != "B1" {
+		t.Fatalf("expected two EU items with batch B1 in target, got %+v", moved)
+	}
+	// 已预留的库存项不参与调拨
+	var reserved models.VirtualProductStock
+	db.Where("content = ?", "A4").First(&reserved)
+	if reserved.VirtualInventoryID != source.ID {
+		t.Fatalf("reserved item should stay in source")
+	}
+
+	var logs []models.InventoryLog
+	db.Where("type = ?", models.InventoryLogTypeTransfer).Order("id ASC").Find(&logs)
+	if len(logs) != 2 || logs[0].Quantity != -2 || logs[1].Quantity != 2 || logs[1].BatchNo != "B1" {
+		t.Fatalf("unexpected transfer logs: %+v", logs)
+	}
+
+	items, total, err := svc.ListStockTransfers(target.ID, 1, 20)
+	if err != nil || total != 1 || len(items) != 1 {
+		t.Fatalf("expected one transfer record, got %d (%v)", total, err)
+	}
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/cache"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+const (
+	waitingRoomQueueKey    = "waiting_room:queue"    // ZSET 用户ID -> 入队序号
+	waitingRoomSeenKey     = "waiting_room:seen"     // ZSET 用户ID -> 最近一次轮询毫秒时间戳
+	waitingRoomAdmittedKey = "waiting_room:admitted" // ZSET 用户ID -> 放行到期毫秒时间戳
+	waitingRoomBucketKey   = "waiting_room:bucket"   // HASH tokens/ts 令牌桶
+	waitingRoomStatsKey    = "waiting_room:stats"    // HASH joined/admitted/abandoned 累计计数
+	waitingRoomSeqKey      = "waiting_room:seq"
+
+	WaitingRoomStatusWaiting  = "waiting"
+	WaitingRoomStatusAdmitted = "admitted"
+	WaitingRoomStatusNone     = "none"
+)
+
+var waitingRoomJoinScript = redis.NewScript(`
+local user = ARGV[1]
+local now = tonumber(ARGV[2])
+local admittedUntil = redis.call('ZSCORE', KEYS[3], user)
+if admittedUntil and tonumber(admittedUntil) > now then
+  return 0
+end
+if redis.call('ZSCORE', KEYS[1], user) then
+  redis.call('ZADD', KEYS[2], now, user)
+  return 0
+end
+redis.call('ZADD', KEYS[1], redis.call('INCR', KEYS[4]), user)
+redis.call('ZADD', KEYS[2], now, user)
+redis.call('HINCRBY', KEYS[5], 'joined', 1)
+return 1
+`)
+
+var waitingRoomStatusScript = redis.NewScript(`
+local user = ARGV[1]
+local now = tonumber(ARGV[2])
+local rate = tonumber(ARGV[3])
+local burst = tonumber(ARGV[4])
+local stale = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', now - tonumber(ARGV[5]))
+for _, member in ipairs(stale) do
+  redis.call('ZREM', KEYS[1], member)
+  redis.call('ZREM', KEYS[2], member)
+end
+if #stale > 0 then
+  redis.call('HINCRBY', KEYS[5], 'abandoned', #stale)
+end
+redis.call('ZREMRANGEBYSCORE', KEYS[3], '-inf', now)
+local tokens = tonumber(redis.call('HGET', KEYS[4], 'tokens') or burst)
+local last = tonumber(redis.call('HGET', KEYS[4], 'ts') or now)
+if now > last then
+  tokens = math.min(burst, tokens + (now - last) * rate / 1000)
+end
+local admit = math.floor(tokens)
+if admit > 0 then
+  local head = redis.call('ZRANGE', KEYS[1], 0, admit - 1)
+  local expires = now + tonumber(ARGV[6])
+  for _, member in ipairs(head) do
+    redis.call('ZREM', KEYS[1], member)
+    redis.call('ZREM', KEYS[2], member)
+    redis.call('ZADD', KEYS[3], expires, member)
+  end
+  if #head > 0 then
+    tokens = tokens - #head
+    redis.call('HINCRBY', KEYS[5], 'admitted', #head)
+  end
+end
+redis.call('HSET', KEYS[4], 'tokens', tostring(tokens), 'ts', ARGV[2])
+local queueLength = redis.call('ZCARD', KEYS[1])
+local admittedUntil = redis.call('ZSCORE', KEYS[3], user)
+if admittedUntil then
+  return {1, tonumber(admittedUntil), queueLength}
+end
+local rank = redis.call('ZRANK', KEYS[1], user)
+if not rank then
+  return {-1, 0, queueLength}
+end
+redis.call('ZADD', KEYS[2], now, user)
+return {0, rank + 1, queueLength}
+`)
+
+// WaitingRoomStatus 用户在等候室中的状态
+type WaitingRoomStatus struct {
+	Status               string     `json:"status"` // waiting, admitted, none
+	Position             int        `json:"position,omitempty"`
+	QueueLength          int        `json:"queue_length"`
+	EstimatedWaitSeconds int        `json:"estimated_wait_seconds,omitempty"`
+	PollAfterSeconds     int        `json:"poll_after_seconds"`
+	AdmissionToken       string     `json:"admission_token,omitempty"`
+	AdmittedUntil        *time.Time `json:"admitted_until,omitempty"`
+}
+
+// WaitingRoomMetrics 等候室运行指标
+type WaitingRoomMetrics struct {
+	Enabled        bool    `json:"enabled"`
+	AllProducts    bool    `json:"all_products"`
+	AdmitPerSecond float64 `json:"admit_per_second"`
+	Burst          int     `json:"burst"`
+	QueueLength    int64   `json:"queue_length"`
+	AdmittedActive int64   `json:"admitted_active"`
+	JoinedTotal    int64   `json:"joined_total"`
+	AdmittedTotal  int64   `json:"admitted_total"`
+	AbandonedTotal int64   `json:"abandoned_total"`
+	AbandonRate    float64 `json:"abandon_rate"`
+}
+
+type waitingRoomMemoryState struct {
+	queue      []uint
+	seen       map[uint]time.Time
+	admitted   map[uint]time.Time
+	tokens     float64
+	refilledAt time.Time
+	joined     int64
+	admittedN  int64
+	abandoned  int64
+}
+
+// WaitingRoomService 抢购虚拟等候室
+// 用户排队后按令牌桶速率放行，放行后获得带签名的凭证，凭证有效期内可提交需排队商品的订单；
+// 排队状态记录在 Redis（未配置时为进程内存），长时间未轮询的用户视为放弃
+type WaitingRoomService struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	mu     sync.Mutex
+	memory waitingRoomMemoryState
+	now    func() time.Time
+}
+
+func NewWaitingRoomService(db *gorm.DB, cfg *config.Config) *WaitingRoomService {
+	return &WaitingRoomService{
+		db:  db,
+		cfg: cfg,
+		memory: waitingRoomMemoryState{
+			seen:     make(map[uint]time.Time),
+			admitted: make(map[uint]time.Time),
+		},
+		now: time.Now,
+	}
+}
+
+func (s *WaitingRoomService) settings() config.OrderWaitingRoomConfig {
+	var room config.OrderWaitingRoomConfig
+	if s.cfg != nil {
+		room = s.cfg.Order.WaitingRoom
+	}
+	if room.AdmitPerSecond <= 0 {
+		room.AdmitPerSecond = 5
+	}
+	if room.Burst <= 0 {
+		room.Burst = 10
+	}
+	if room.AdmissionTTLSeconds <= 0 {
+		room.AdmissionTTLSeconds = 600
+	}
+	if room.AbandonAfterSeconds <= 0 {
+		room.AbandonAfterSeconds = 60
+	}
+	return room
+}
+
+// Enabled 是否开启等候室
+func (s *WaitingRoomService) Enabled() bool {
+	return s != nil && s.cfg != nil && s.cfg.Order.WaitingRoom.Enabled
+}
+
+// RequiresAdmission 订单商品是否需要排队放行：整站模式下全部需要，否则仅开启排队的商品需要
+func (s *WaitingRoomService) RequiresAdmission(items []models.OrderItem) (bool, error) {
+	if !s.Enabled() {
+		return false, nil
+	}
+	if s.settings().AllProducts {
+		return true, nil
+	}
+	skus := collectOrderItemSKUs(items)
+	if len(skus) == 0 || s.db == nil {
+		return false, nil
+	}
+	var count int64
+	if err := s.db.Model(&models.Product{}).
+		Where("sku IN ? AND waiting_room = ?", skus, true).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CheckOrderAdmission 校验下单请求携带的放行凭证
+func (s *WaitingRoomService) CheckOrderAdmission(userID uint, items []models.OrderItem, token string) error {
+	required, err := s.RequiresAdmission(items)
+	if err != nil {
+		return err
+	}
+	if !required {
+		return nil
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return bizerr.New("waiting_room.admissionRequired", "Please wait in the queue before placing this order")
+	}
+	if !s.verifyAdmissionToken(userID, token) {
+		return bizerr.New("waiting_room.tokenInvalid", "Queue admission has expired, please queue again")
+	}
+	return nil
+}
+
+// Join 加入等候室并返回当前状态；已在排队或已放行时不会重新排队
+func (s *WaitingRoomService) Join(userID uint) (*WaitingRoomStatus, error) {
+	if !s.Enabled() {
+		return nil, bizerr.New("waiting_room.disabled", "Waiting room is not enabled")
+	}
+	now := s.now()
+	if cache.RedisClient != nil {
+		err := waitingRoomJoinScript.Run(
+			cache.RedisClient.Context(),
+			cache.RedisClient,
+			[]string{waitingRoomQueueKey, waitingRoomSeenKey, waitingRoomAdmittedKey, waitingRoomSeqKey, waitingRoomStatsKey},
+			userID,
+			now.UnixMilli(),
+		).Err()
+		if err == nil {
+			return s.Status(userID)
+		}
+		log.Printf("waiting room redis join failed, fallback to memory: user=%d err=%v", userID, err)
+	}
+
+	s.mu.Lock()
+	state := &s.memory
+	if until, ok := state.admitted[userID]; !ok || !until.After(now) {
+		if _, waiting := state.seen[userID]; waiting {
+			state.seen[userID] = now
+		} else {
+			state.queue = append(state.queue, userID)
+			state.seen[userID] = now
+			state.joined++
+		}
+	}
+	s.mu.Unlock()
+	return s.Status(userID)
+}
+
+// Status 刷新令牌桶放行队首用户，并返回用户的排队位置或放行凭证
+func (s *WaitingRoomService) Status(userID uint) (*WaitingRoomStatus, error) {
+	if !s.Enabled() {
+		return nil, bizerr.New("waiting_room.disabled", "Waiting room is not enabled")
+	}
+	room := s.settings()
+	now := s.now()
+
+	state, value, queueLength, ok := s.redisStatus(userID, room, now)
+	if !ok {
+		state, value, queueLength = s.memoryStatus(userID, room, now)
+	}
+
+	status := &WaitingRoomStatus{QueueLength: queueLength, PollAfterSeconds: 3}
+	switch state {
+	case 1:
+		until := time.UnixMilli(value)
+		status.Status = WaitingRoomStatusAdmitted
+		status.AdmittedUntil = &until
+		status.AdmissionToken = s.signAdmissionToken(userID, until)
+		status.PollAfterSeconds = 0
+	case 0:
+		status.Status = WaitingRoomStatusWaiting
+		status.Position = int(value)
+		status.EstimatedWaitSeconds = int(math.Ceil(float64(value) / room.AdmitPerSecond))
+	default:
+		status.Status = WaitingRoomStatusNone
+		status.PollAfterSeconds = 0
+	}
+	return status, nil
+}
+
+func (s *WaitingRoomService) redisStatus(userID uint, room config.OrderWaitingRoomConfig, now time.Time) (int64, int64, int, bool) {
+	if cache.RedisClient == nil {
+		return 0, 0, 0, false
+	}
+	result, err := waitingRoomStatusScript.Run(
+		cache.RedisClient.Context(),
+		cache.RedisClient,
+		[]string{waitingRoomQueueKey, waitingRoomSeenKey, waitingRoomAdmittedKey, waitingRoomBucketKey, waitingRoomStatsKey},
+		userID,
+		now.UnixMilli(),
+		room.AdmitPerSecond,
+		room.Burst,
+		int64(room.AbandonAfterSeconds)*1000,
+		int64(room.AdmissionTTLSeconds)*1000,
+	).Int64Slice()
+	if err != nil || len(result) != 3 {
+		log.Printf("waiting room redis status failed, fallback to memory: user=%d err=%v", userID, err)
+		return 0, 0, 0, false
+	}
+	return result[0], result[1], int(result[2]), true
+}
+
+func (s *WaitingRoomService) memoryStatus(userID uint, room config.OrderWaitingRoomConfig, now time.Time) (int64, int64, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := &s.memory
+
+	abandonBefore := now.Add(-time.Duration(room.AbandonAfterSeconds) * time.Second)
+	queue := state.queue[:0]
+	for _, member := range state.queue {
+		if !state.seen[member].After(abandonBefore) {
+			delete(state.seen, member)
+			state.abandoned++
+			continue
+		}
+		queue = append(queue, member)
+	}
+	state.queue = queue
+	for member, until := range state.admitted {
+		if !until.After(now) {
+			delete(state.admitted, member)
+		}
+	}
+
+	if state.refilledAt.IsZero() {
+		state.tokens = float64(room.Burst)
+	} else if now.After(state.refilledAt) {
+		state.tokens = math.Min(float64(room.Burst), state.tokens+now.Sub(state.refilledAt).Seconds()*room.AdmitPerSecond)
+	}
+	state.refilledAt = now
+	admit := int(math.Floor(state.tokens))
+	if admit > len(state.queue) {
+		admit = len(state.queue)
+	}
+	if admit > 0 {
+		expiresAt := now.Add(time.Duration(room.AdmissionTTLSeconds) * time.Second)
+		for _, member := range state.queue[:admit] {
+			delete(state.seen, member)
+			state.admitted[member] = expiresAt
+		}
+		state.queue = append([]uint(nil), state.queue[admit:]...)
+		state.tokens -= float64(admit)
+		state.admittedN += int64(admit)
+	}
+
+	if until, ok := state.admitted[userID]; ok {
+		return 1, until.UnixMilli(), len(state.queue)
+	}
+	for index, member := range state.queue {
+		if member == userID {
+			state.seen[userID] = now
+			return 0, int64(index + 1), len(state.queue)
+		}
+	}
+	return -1, 0, len(state.queue)
+}
+
+// Metrics 等候室指标：当前排队人数、有效放行人数及累计排队/放行/放弃次数
+func (s *WaitingRoomService) Metrics() *WaitingRoomMetrics {
+	room := s.settings()
+	metrics := &WaitingRoomMetrics{
+		Enabled:        s.Enabled(),
+		AllProducts:    room.AllProducts,
+		AdmitPerSecond: room.AdmitPerSecond,
+		Burst:          room.Burst,
+	}
+	now := s.now()
+
+	loaded := false
+	if cache.RedisClient != nil {
+		ctx := cache.RedisClient.Context()
+		pipe := cache.RedisClient.Pipeline()
+		queueLength := pipe.ZCard(ctx, waitingRoomQueueKey)
+		admittedActive := pipe.ZCount(ctx, waitingRoomAdmittedKey, "("+strconv.FormatInt(now.UnixMilli(), 10), "+inf")
+		stats := pipe.HGetAll(ctx, waitingRoomStatsKey)
+		if _, err := pipe.Exec(ctx); err == nil {
+			metrics.QueueLength = queueLength.Val()
+			metrics.AdmittedActive = admittedActive.Val()
+			counters := stats.Val()
+			metrics.JoinedTotal, _ = strconv.ParseInt(counters["joined"], 10, 64)
+			metrics.AdmittedTotal, _ = strconv.ParseInt(counters["admitted"], 10, 64)
+			metrics.AbandonedTotal, _ = strconv.ParseInt(counters["abandoned"], 10, 64)
+			loaded = true
+		} else {
+			log.Printf("waiting room redis metrics failed, fallback to memory: err=%v", err)
+		}
+	}
+	if !loaded {
+		s.mu.Lock()
+		metrics.QueueLength = int64(len(s.memory.queue))
+		for _, until := range s.memory.admitted {
+			if until.After(now) {
+				metrics.AdmittedActive++
+			}
+		}
+		metrics.JoinedTotal = s.memory.joined
+		metrics.AdmittedTotal = s.memory.admittedN
+		metrics.AbandonedTotal = s.memory.abandoned
+		s.mu.Unlock()
+	}
+	if metrics.JoinedTotal > 0 {
+		metrics.AbandonRate = float64(metrics.AbandonedTotal) / float64(metrics.JoinedTotal)
+	}
+	return metrics
+}
+
+// 放行凭证格式：用户ID.到期秒级时间戳.HMAC-SHA256签名，无状态校验，可跨实例使用
+func (s *WaitingRoomService) signAdmissionToken(userID uint, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d.%d", userID, expiresAt.Unix())
+	return payload + "." + s.admissionSignature(payload)
+}
+
+func (s *WaitingRoomService) verifyAdmissionToken(userID uint, token string) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.admissionSignature(payload))) {
+		return false
+	}
+	tokenUserID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || uint(tokenUserID) != userID {
+		return false
+	}
+	expiresUnix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return false
+	}
+	return s.now().Before(time.Unix(expiresUnix, 0))
+}
+
+func (s *WaitingRoomService) admissionSignature(payload string) string {
+	secret := ""
+	if s.cfg != nil {
+		secret = s.cfg.JWT.Secret
+	}
+	mac := hmac.New(sha256.New, []byte("waiting-room:"+secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
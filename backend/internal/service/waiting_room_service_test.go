@@ -0,0 +1,124 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"auralogic/internal/config"
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/cache"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newWaitingRoomTestService() (*WaitingRoomService, *time.Time) {
+	cfg := &config.Config{}
+	cfg.JWT.Secret = "waiting-room-test-secret-0123456789"
+	cfg.Order.WaitingRoom = config.OrderWaitingRoomConfig{
+		Enabled:             true,
+		AllProducts:         true,
+		AdmitPerSecond:      1,
+		Burst:               1,
+		AdmissionTTLSeconds: 300,
+		AbandonAfterSeconds: 30,
+	}
+	svc := NewWaitingRoomService(nil, cfg)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+	return svc, &now
+}
+
+func exerciseWaitingRoom(t *testing.T, svc *WaitingRoomService, advance func(time.Duration)) {
+	t.Helper()
+	items := []models.OrderItem{{SKU: "DROP-1", Quantity: 1}}
+
+	first, err := svc.Join(1)
+	if err != nil || first.Status != WaitingRoomStatusAdmitted || first.AdmissionToken == "" {
+		t.Fatalf("first user should be admitted by burst, got %+v (%v)", first, err)
+	}
+	second, _ := svc.Join(2)
+	third, _ := svc.Join(3)
+	if second.Status != WaitingRoomStatusWaiting || second.Position != 1 || third.Position != 2 {
+		t.Fatalf("unexpected queue positions: %+v %+v", second, third)
+	}
+	// 重复加入不会改变排队位置
+	if again, _ := svc.Join(2); again.Position != 1 {
+		t.Fatalf("rejoin should keep position, got %+v", again)
+	}
+
+	requireBizErr(t, svc.CheckOrderAdmission(2, items, ""), "waiting_room.admissionRequired")
+	requireBizErr(t, svc.CheckOrderAdmission(2, items, first.AdmissionToken), "waiting_room.tokenInvalid")
+	if err := svc.CheckOrderAdmission(1, items, first.AdmissionToken); err != nil {
+		t.Fatalf("admitted user should pass: %v", err)
+	}
+
+	// 用户 3 不再轮询，超时后视为放弃
+	advance(20 * time.Second)
+	if status, _ := svc.Status(2); status.Status != WaitingRoomStatusAdmitted {
+		t.Fatalf("second user should be admitted after refill, got %+v", status)
+	}
+	advance(15 * time.Second)
+	if status, _ := svc.Status(3); status.Status != WaitingRoomStatusNone {
+		t.Fatalf("idle user should be dropped, got %+v", status)
+	}
+
+	metrics := svc.Metrics()
+	if metrics.JoinedTotal != 3 || metrics.AdmittedTotal != 2 || metrics.AbandonedTotal != 1 || metrics.QueueLength != 0 || metrics.AdmittedActive != 2 {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+
+	advance(10 * time.Minute)
+	requireBizErr(t, svc.CheckOrderAdmission(1, items, first.AdmissionToken), "waiting_room.tokenInvalid")
+}
+
+func TestWaitingRoomMemoryMode(t *testing.T) {
+	previousClient := cache.RedisClient
+	cache.RedisClient = nil
+	defer func() { cache.RedisClient = previousClient }()
+
+	svc, now := newWaitingRoomTestService()
+	exerciseWaitingRoom(t, svc, func(d time.Duration) { *now = now.Add(d) })
+}
+
+func TestWaitingRoomRedisMode(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("start miniredis failed: %v", err)
+	}
+	defer mr.Close()
+
+	previousClient := cache.RedisClient
+	cache.RedisClient = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer func() {
+		_ = cache.RedisClient.Close()
+		cache.RedisClient = previousClient
+	}()
+
+	svc, now := newWaitingRoomTestService()
+	exerciseWaitingRoom(t, svc, func(d time.Duration) { *now = now.Add(d) })
+	if len(svc.memory.queue) != 0 || svc.memory.joined != 0 {
+		t.Fatalf("redis mode should not fall back to memory")
+	}
+}
+
+func TestWaitingRoomOnlyGatesFlaggedProducts(t *testing.T) {
+	_, db := newVirtualInventoryServiceTestDB(t)
+	for _, product := range []*models.Product{
+		{SKU: "DROP-1", Name: "Drop", Price: 100, WaitingRoom: true},
+		{SKU: "BASIC-1", Name: "Basic", Price: 100},
+	} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("create product: %v", err)
+		}
+	}
+	svc, _ := newWaitingRoomTestService()
+	svc.db = db
+	svc.cfg.Order.WaitingRoom.AllProducts = false
+
+	if required, err := svc.RequiresAdmission([]models.OrderItem{{SKU: "BASIC-1", Quantity: 1}}); err != nil || required {
+		t.Fatalf("unflagged product should not require admission: %v %v", required, err)
+	}
+	if required, err := svc.RequiresAdmission([]models.OrderItem{{SKU: "BASIC-1", Quantity: 1}, {SKU: "DROP-1", Quantity: 1}}); err != nil || !required {
+		t.Fatalf("flagged product should require admission: %v %v", required, err)
+	}
+}
@@ -0,0 +1,701 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"auralogic/internal/models"
+	"auralogic/internal/pkg/bizerr"
+	"auralogic/internal/pkg/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	webhookHTTPTimeout        = 10 * time.Second
+	webhookMaxAttempts        = 8
+	webhookRetryBaseDelay     = 30 * time.Second
+	webhookRetryMaxDelay      = 6 * time.Hour
+	webhookClaimLease         = 2 * time.Minute // 投递中的记录在租约内不会被重试任务重复领取
+	webhookRetryCheckInterval = 30 * time.Second
+	webhookRetryBatchSize     = 50
+	webhookResponseBodyLimit  = 2048
+	webhookMaxEndpoints       = 20
+)
+
+var (
+	ErrWebhookEndpointNotFound = bizerr.New("webhook.endpointNotFound", "Webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = bizerr.New("webhook.deliveryNotFound", "Webhook delivery not found")
+)
+
+// WebhookEndpointInput 创建/更新 Webhook 参数
+type WebhookEndpointInput struct {
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Enabled     bool     `json:"enabled"`
+	Description string   `json:"description"`
+	Platform    string   `json:"platform"`
+}
+
+// WebhookEndpointWithSecret 创建或轮换密钥后返回一次明文密钥
+type WebhookEndpointWithSecret struct {
+	*models.WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveryFilter 投递日志查询条件
+type WebhookDeliveryFilter struct {
+	EndpointID uint
+	Event      string
+	Status     string
+}
+
+// WebhookService 出站 Webhook：订阅订单/工单 Hook 事件，签名后推送到管理员配置的地址，失败按指数退避重试
+type WebhookService struct {
+	db          *gorm.DB
+	httpClient  *http.Client
+	checkURL    func(*url.URL) error
+	lifecycleMu sync.Mutex
+	running     bool
+	scheduler   *BackgroundScheduler
+}
+
+func NewWebhookService(db *gorm.DB) *WebhookService {
+	return &WebhookService{
+		db:         db,
+		httpClient: getPaymentHTTPClient(),
+		checkURL:   validateExternalURL,
+	}
+}
+
+// WebhookEvents 可订阅的事件
+func WebhookEvents() []models.WebhookEvent {
+	return []models.WebhookEvent{
+		models.WebhookEventOrderCreated,
+		models.WebhookEventOrderPaid,
+		models.WebhookEventOrderShipped,
+		models.WebhookEventOrderCompleted,
+		models.WebhookEventOrderCancelled,
+		models.WebhookEventOrderRefunded,
+		models.WebhookEventOrderStatus,
+		models.WebhookEventTicketCreated,
+		models.WebhookEventTicketReplied,
+	}
+}
+
+func isKnownWebhookEvent(event models.WebhookEvent) bool {
+	if event == models.WebhookEventAll {
+		return true
+	}
+	for _, item := range WebhookEvents() {
+		if item == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *WebhookService) validateInput(input *WebhookEndpointInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	input.URL = strings.TrimSpace(input.URL)
+	input.Description = strings.TrimSpace(input.Description)
+	input.Platform = strings.TrimSpace(input.Platform)
+	if input.Name == "" {
+		return bizerr.New("webhook.nameRequired", "Webhook name is required")
+	}
+	if utf8.RuneCountInString(input.Name) > 100 {
+		return bizerr.New("webhook.nameTooLong", "Webhook name must be at most 100 characters")
+	}
+	if utf8.RuneCountInString(input.Platform) > 100 {
+		return bizerr.New("webhook.platformTooLong", "Source platform must be at most 100 characters")
+	}
+	parsed, err := url.Parse(input.URL)
+	if err != nil || len(input.URL) > 500 || s.checkURL(parsed) != nil {
+		return bizerr.New("webhook.urlInvalid", "Webhook URL must be a public http(s) address")
+	}
+	events := make([]string, 0, len(input.Events))
+	seen := make(map[string]bool, len(input.Events))
+	for _, raw := range input.Events {
+		event := strings.TrimSpace(raw)
+		if !isKnownWebhookEvent(models.WebhookEvent(event)) {
+			return bizerr.Newf("webhook.eventInvalid", "Unknown webhook event: %s", event).
+				WithParams(map[string]interface{}{"event": event})
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return bizerr.New("webhook.eventsRequired", "Select at least one event")
+	}
+	input.Events = events
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	token, err := utils.GenerateToken(40)
+	if err != nil {
+		return "", err
+	}
+	return "whsec_" + token, nil
+}
+
+func generateWebhookEventID() (string, error) {
+	token, err := utils.GenerateToken(24)
+	if err != nil {
+		return "", err
+	}
+	return "evt_" + token, nil
+}
+
+// ListEndpoints 全部 Webhook 地址
+func (s *WebhookService) ListEndpoints() ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := s.db.Order("id ASC").Find(&endpoints).Error
+	return endpoints, err
+}
+
+// GetEndpoint 获取单个 Webhook 地址
+func (s *WebhookService) GetEndpoint(id uint) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	if err := s.db.First(&endpoint, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookEndpointNotFound
+		}
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// CreateEndpoint 创建 Webhook 地址并生成签名密钥
+func (s *WebhookService) CreateEndpoint(input WebhookEndpointInput, createdBy *uint) (*WebhookEndpointWithSecret, error) {
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	var count int64
+	if err := s.db.Model(&models.WebhookEndpoint{}).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count >= webhookMaxEndpoints {
+		return nil, bizerr.Newf("webhook.tooManyEndpoints", "At most %d webhook endpoints are allowed", webhookMaxEndpoints).
+			WithParams(map[string]interface{}{"max": webhookMaxEndpoints})
+	}
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := &models.WebhookEndpoint{
+		Name:        input.Name,
+		URL:         input.URL,
+		Secret:      secret,
+		Events:      input.Events,
+		Enabled:     input.Enabled,
+		Description: input.Description,
+		Platform:    input.Platform,
+		CreatedBy:   createdBy,
+	}
+	if err := s.db.Create(endpoint).Error; err != nil {
+		return nil, err
+	}
+	return &WebhookEndpointWithSecret{WebhookEndpoint: endpoint, Secret: secret}, nil
+}
+
+// UpdateEndpoint 更新 Webhook 地址，不改变密钥
+func (s *WebhookService) UpdateEndpoint(id uint, input WebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.GetEndpoint(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateInput(&input); err != nil {
+		return nil, err
+	}
+	endpoint.Name = input.Name
+	endpoint.URL = input.URL
+	endpoint.Events = input.Events
+	endpoint.Enabled = input.Enabled
+	endpoint.Description = input.Description
+	endpoint.Platform = input.Platform
+	if err := s.db.Model(endpoint).Select("name", "url", "events", "enabled", "description", "platform").Updates(endpoint).Error; err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint 删除 Webhook 地址及其投递日志
+func (s *WebhookService) DeleteEndpoint(id uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.WebhookEndpoint{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrWebhookEndpointNotFound
+		}
+		return tx.Where("endpoint_id = ?", id).Delete(&models.WebhookDelivery{}).Error
+	})
+}
+
+// RotateSecret 重新生成签名密钥，旧密钥立即失效
+func (s *WebhookService) RotateSecret(id uint) (*WebhookEndpointWithSecret, error) {
+	endpoint, err := s.GetEndpoint(id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(endpoint).Update("secret", secret).Error; err != nil {
+		return nil, err
+	}
+	endpoint.Secret = secret
+	return &WebhookEndpointWithSecret{WebhookEndpoint: endpoint, Secret: secret}, nil
+}
+
+// ListDeliveries 投递日志
+func (s *WebhookService) ListDeliveries(filter WebhookDeliveryFilter, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	query := s.db.Model(&models.WebhookDelivery{})
+	if filter.EndpointID > 0 {
+		query = query.Where("endpoint_id = ?", filter.EndpointID)
+	}
+	if event := strings.TrimSpace(filter.Event); event != "" {
+		query = query.Where("event = ?", event)
+	}
+	if status := strings.TrimSpace(filter.Status); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var deliveries []models.WebhookDelivery
+	err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+// SendTest 向指定地址同步发送一次 ping 事件（即使地址已停用）
+func (s *WebhookService) SendTest(id uint) (*models.WebhookDelivery, error) {
+	endpoint, err := s.GetEndpoint(id)
+	if err != nil {
+		return nil, err
+	}
+	eventID, err := generateWebhookEventID()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := buildWebhookPayload(eventID, models.WebhookEventPing, map[string]interface{}{
+		"endpoint_id": endpoint.ID,
+		"message":     "AuraLogic webhook test",
+	})
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.createDelivery(endpoint.ID, eventID, models.WebhookEventPing, payload)
+	if err != nil {
+		return nil, err
+	}
+	return s.attemptDelivery(delivery.ID, true)
+}
+
+// Redeliver 以相同事件 ID 和内容重新投递一次，生成新的投递记录
+func (s *WebhookService) Redeliver(deliveryID uint) (*models.WebhookDelivery, error) {
+	var original models.WebhookDelivery
+	if err := s.db.First(&original, deliveryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+	if _, err := s.GetEndpoint(original.EndpointID); err != nil {
+		return nil, err
+	}
+	delivery, err := s.createDelivery(original.EndpointID, original.EventID, original.Event, original.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return s.attemptDelivery(delivery.ID, true)
+}
+
+// orderEventsForHook 订单 Hook 对应的业务事件，Webhook 与管理员通知共用
+func orderEventsForHook(hook string, payload map[string]interface{}) []models.WebhookEvent {
+	switch hook {
+	case "order.create.after":
+		return []models.WebhookEvent{models.WebhookEventOrderCreated}
+	case "order.status.changed.after":
+		var events []models.WebhookEvent
+		if isOrderPaidTransition(fmt.Sprint(payload["status_before"]), fmt.Sprint(payload["status_after"])) {
+			events = append(events, models.WebhookEventOrderPaid)
+		}
+		switch models.OrderStatus(fmt.Sprint(payload["status_after"])) {
+		case models.OrderStatusShipped:
+			events = append(events, models.WebhookEventOrderShipped)
+		case models.OrderStatusCompleted:
+			events = append(events, models.WebhookEventOrderCompleted)
+		case models.OrderStatusCancelled:
+			events = append(events, models.WebhookEventOrderCancelled)
+		case models.OrderStatusRefunded:
+			events = append(events, models.WebhookEventOrderRefunded)
+		}
+		return events
+	}
+	return nil
+}
+
+// ObserveHook 将订单/工单 Hook 事件映射为 Webhook 事件
+func (s *WebhookService) ObserveHook(hook string, payload map[string]interface{}) {
+	if hook == "order.status.changed.after" {
+		s.publishOrderEvent(models.WebhookEventOrderStatus, payload)
+	}
+	for _, event := range orderEventsForHook(hook, payload) {
+		s.publishOrderEvent(event, payload)
+	}
+	switch hook {
+	case "ticket.create.after":
+		s.publishAsync(models.WebhookEventTicketCreated, buildWebhookTicketData(payload, "ticket_id", "ticket_no", "user_id", "order_id", "subject", "category", "priority", "status", "created_at"))
+	case "ticket.message.user.after":
+		s.publishAsync(models.WebhookEventTicketReplied, buildWebhookTicketData(payload, "ticket_id", "ticket_no", "message_id", "user_id", "status", "created_at"))
+	}
+}
+
+func (s *WebhookService) publishOrderEvent(event models.WebhookEvent, payload map[string]interface{}) {
+	orderID, ok := orderAutomationPayloadOrderID(payload["order_id"])
+	if !ok {
+		return
+	}
+	go func() {
+		defer recoverBackgroundServicePanic("webhook-publish")
+		var order models.Order
+		if err := s.db.First(&order, orderID).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("webhook load order failed: event=%s order=%d err=%v", event, orderID, err)
+			}
+			return
+		}
+		data := buildWebhookOrderData(&order)
+		if before, exists := payload["status_before"]; exists {
+			data["status_before"] = before
+		}
+		if _, err := s.Publish(event, data); err != nil {
+			log.Printf("webhook publish failed: event=%s order=%s err=%v", event, order.OrderNo, err)
+		}
+	}()
+}
+
+func (s *WebhookService) publishAsync(event models.WebhookEvent, data map[string]interface{}) {
+	go func() {
+		defer recoverBackgroundServicePanic("webhook-publish")
+		if _, err := s.Publish(event, data); err != nil {
+			log.Printf("webhook publish failed: event=%s err=%v", event, err)
+		}
+	}()
+}
+
+// Publish 为订阅该事件的已启用地址创建投递记录并立即尝试投递，返回创建的记录数
+// 设置了 platform 的地址只接收 data.source_platform 匹配的订单事件
+func (s *WebhookService) Publish(event models.WebhookEvent, data map[string]interface{}) (int, error) {
+	var endpoints []models.WebhookEndpoint
+	if err := s.db.Where("enabled = ?", true).Order("id ASC").Find(&endpoints).Error; err != nil {
+		return 0, err
+	}
+	platform, _ := data["source_platform"].(string)
+	targets := make([]models.WebhookEndpoint, 0, len(endpoints))
+	for i := range endpoints {
+		if endpoints[i].Subscribes(event) && endpoints[i].AcceptsPlatform(event, platform) {
+			targets = append(targets, endpoints[i])
+		}
+	}
+	if len(targets) == 0 {
+		return 0, nil
+	}
+
+	eventID, err := generateWebhookEventID()
+	if err != nil {
+		return 0, err
+	}
+	payload, err := buildWebhookPayload(eventID, event, data)
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	for i := range targets {
+		delivery, err := s.createDelivery(targets[i].ID, eventID, event, payload)
+		if err != nil {
+			log.Printf("webhook delivery create failed: endpoint=%d event=%s err=%v", targets[i].ID, event, err)
+			continue
+		}
+		created++
+		if _, err := s.attemptDelivery(delivery.ID, false); err != nil {
+			log.Printf("webhook delivery failed: delivery=%d err=%v", delivery.ID, err)
+		}
+	}
+	return created, nil
+}
+
+func buildWebhookPayload(eventID string, event models.WebhookEvent, data map[string]interface{}) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         eventID,
+		"event":      event,
+		"created_at": models.NowFunc().UTC().Format(time.RFC3339),
+		"data":       data,
+	})
+	return string(body), err
+}
+
+func buildWebhookOrderData(order *models.Order) map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, map[string]interface{}{
+			"sku":              item.SKU,
+			"name":             item.Name,
+			"quantity":         item.Quantity,
+			"product_type":     item.ProductType,
+			"line_total_minor": item.LineTotalMinor,
+		})
+	}
+	return map[string]interface{}{
+		"id":                 order.ID,
+		"order_no":           order.OrderNo,
+		"status":             order.Status,
+		"user_id":            order.UserID,
+		"user_email":         order.UserEmail,
+		"items":              items,
+		"total_amount_minor": order.TotalAmount,
+		"currency":           order.Currency,
+		"source":             order.Source,
+		"source_platform":    order.SourcePlatform,
+		"external_order_id":  order.ExternalOrderID,
+		"external_user_id":   order.ExternalUserID,
+		"tracking_no":        order.TrackingNo,
+		"shipped_at":         order.ShippedAt,
+		"completed_at":       order.CompletedAt,
+		"created_at":         order.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// buildWebhookTicketData 只转发工单元数据，不包含消息正文
+func buildWebhookTicketData(payload map[string]interface{}, keys ...string) map[string]interface{} {
+	data := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		if value, exists := payload[key]; exists {
+			data[key] = value
+		}
+	}
+	return data
+}
+
+func (s *WebhookService) createDelivery(endpointID uint, eventID string, event models.WebhookEvent, payload string) (*models.WebhookDelivery, error) {
+	now := models.NowFunc()
+	delivery := &models.WebhookDelivery{
+		EndpointID:    endpointID,
+		EventID:       eventID,
+		Event:         event,
+		Payload:       payload,
+		Status:        models.WebhookDeliveryStatusPending,
+		NextAttemptAt: &now,
+	}
+	if err := s.db.Create(delivery).Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// webhookRetryDelay 第 n 次失败后的等待时间：30s、1m、2m……最长 6 小时
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// SignWebhookPayload 签名：HMAC-SHA256(secret, "<timestamp>.<body>")，十六进制
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// attemptDelivery 领取并投递一次；manual 为管理员手动触发，不因租约冲突放弃
+func (s *WebhookService) attemptDelivery(deliveryID uint, manual bool) (*models.WebhookDelivery, error) {
+	now := models.NowFunc()
+	claim := s.db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ?", deliveryID, models.WebhookDeliveryStatusPending)
+	if !manual {
+		claim = claim.Where("next_attempt_at IS NOT NULL AND next_attempt_at <= ?", now)
+	}
+	result := claim.Updates(map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"next_attempt_at": now.Add(webhookClaimLease),
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	var delivery models.WebhookDelivery
+	if err := s.db.First(&delivery, deliveryID).Error; err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		// 已被其他进程领取或已结束
+		return &delivery, nil
+	}
+
+	var endpoint models.WebhookEndpoint
+	var responseStatus int
+	var responseBody string
+	var sendErr error
+	startedAt := time.Now()
+	if err := s.db.First(&endpoint, delivery.EndpointID).Error; err != nil {
+		sendErr = fmt.Errorf("endpoint not found")
+	} else if !endpoint.Enabled && delivery.Event != models.WebhookEventPing && !manual {
+		sendErr = fmt.Errorf("endpoint disabled")
+	} else {
+		responseStatus, responseBody, sendErr = s.send(&endpoint, &delivery)
+	}
+
+	updates := map[string]interface{}{
+		"response_status": responseStatus,
+		"response_body":   responseBody,
+		"duration_ms":     time.Since(startedAt).Milliseconds(),
+	}
+	finishedAt := models.NowFunc()
+	switch {
+	case sendErr == nil:
+		updates["status"] = models.WebhookDeliveryStatusSucceeded
+		updates["error"] = ""
+		updates["next_attempt_at"] = nil
+		updates["delivered_at"] = finishedAt
+	case delivery.Attempts >= webhookMaxAttempts || manual || endpoint.ID == 0:
+		updates["status"] = models.WebhookDeliveryStatusFailed
+		updates["error"] = sendErr.Error()
+		updates["next_attempt_at"] = nil
+	default:
+		updates["error"] = sendErr.Error()
+		updates["next_attempt_at"] = finishedAt.Add(webhookRetryDelay(delivery.Attempts))
+	}
+	if err := s.db.Model(&delivery).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	if endpoint.ID > 0 {
+		lastStatus := string(models.WebhookDeliveryStatusSucceeded)
+		if sendErr != nil {
+			lastStatus = string(models.WebhookDeliveryStatusFailed)
+		}
+		s.db.Model(&models.WebhookEndpoint{}).Where("id = ?", endpoint.ID).UpdateColumns(map[string]interface{}{
+			"last_status":  lastStatus,
+			"last_sent_at": finishedAt,
+		})
+	}
+	var finished models.WebhookDelivery
+	if err := s.db.First(&finished, deliveryID).Error; err != nil {
+		return nil, err
+	}
+	return &finished, nil
+}
+
+func (s *WebhookService) send(endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) (int, string, error) {
+	parsed, err := url.Parse(endpoint.URL)
+	if err != nil || s.checkURL(parsed) != nil {
+		return 0, "", fmt.Errorf("url is not allowed")
+	}
+	body := []byte(delivery.Payload)
+	timestamp := models.NowFunc().Unix()
+	ctx, cancel := context.WithTimeout(context.Background(), webhookHTTPTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, parsed.String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AuraLogic-Webhook/1.0")
+	req.Header.Set("X-AuraLogic-Event", string(delivery.Event))
+	req.Header.Set("X-AuraLogic-Event-ID", delivery.EventID)
+	req.Header.Set("X-AuraLogic-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-AuraLogic-Signature", fmt.Sprintf("t=%d,v1=%s", timestamp, SignWebhookPayload(endpoint.Secret, timestamp, body)))
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(respBody), fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(respBody), nil
+}
+
+// RetryDue 投递到期的待重试记录
+func (s *WebhookService) RetryDue() int {
+	var ids []uint
+	if err := s.db.Model(&models.WebhookDelivery{}).
+		Where("status = ? AND next_attempt_at IS NOT NULL AND next_attempt_at <= ?", models.WebhookDeliveryStatusPending, models.NowFunc()).
+		Order("next_attempt_at ASC").Limit(webhookRetryBatchSize).Pluck("id", &ids).Error; err != nil {
+		log.Printf("[Webhook] Error querying due deliveries: %v", err)
+		return 0
+	}
+	for _, id := range ids {
+		if _, err := s.attemptDelivery(id, false); err != nil {
+			log.Printf("[Webhook] Retry delivery %d failed: %v", id, err)
+		}
+	}
+	return len(ids)
+}
+
+// Start 启动失败投递重试任务
+func (s *WebhookService) Start() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	if s.running {
+		return
+	}
+	scheduler := backgroundSchedulerForService()
+	if err := scheduler.Register(BackgroundJob{
+		Name:       "webhook_delivery",
+		Interval:   webhookRetryCheckInterval,
+		RunOnStart: true,
+		LockTTL:    webhookRetryCheckInterval,
+		Jitter:     webhookRetryCheckInterval / 10,
+		Run: func() error {
+			s.RetryDue()
+			return nil
+		},
+	}); err != nil {
+		log.Printf("[Webhook] Failed to register background job: %v", err)
+		return
+	}
+	s.scheduler = scheduler
+	s.running = true
+}
+
+// Stop 停止重试任务
+func (s *WebhookService) Stop() {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	if !s.running {
+		return
+	}
+	s.running = false
+	s.scheduler.Remove("webhook_delivery")
+	s.scheduler = nil
+}
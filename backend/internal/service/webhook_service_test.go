@@ -0,0 +1,168 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"auralogic/internal/models"
+)
+
+func newWebhookTestService(t *testing.T, server *httptest.Server) *WebhookService {
+	t.Helper()
+	db := openConcurrentServiceTestDB(t, &models.WebhookEndpoint{}, &models.WebhookDelivery{})
+	svc := NewWebhookService(db)
+	svc.httpClient = server.Client()
+	svc.checkURL = func(*url.URL) error { return nil }
+	return svc
+}
+
+func TestWebhookPublishSignsAndRetriesWithBackoff(t *testing.T) {
+	var failing atomic.Bool
+	var received atomic.Int32
+	var secret atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		var timestamp int64
+		var signature string
+		fmt.Sscanf(strings.Replace(r.Header.Get("X-AuraLogic-Signature"), ",v1=", " ", 1), "t=%d %s", &timestamp, &signature)
+		if signature != SignWebhookPayload(secret.Load().(string), timestamp, body) || r.Header.Get("X-AuraLogic-Event") != "order.paid" {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		if failing.Load() {
+			http.Error(w, "erp down", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	svc := newWebhookTestService(t, server)
+
+	if _, err := svc.CreateEndpoint(WebhookEndpointInput{Name: "ERP", URL: server.URL, Events: []string{"order.unknown"}, Enabled: true}, nil); refundErrorKey(err) != "webhook.eventInvalid" {
+		t.Fatalf("unknown events should be rejected, got %v", err)
+	}
+	created, err := svc.CreateEndpoint(WebhookEndpointInput{Name: "ERP", URL: server.URL, Events: []string{"order.paid", "order.paid"}, Enabled: true}, nil)
+	if err != nil || !strings.HasPrefix(created.Secret, "whsec_") || len(created.Events) != 1 {
+		t.Fatalf("create endpoint: %+v, %v", created, err)
+	}
+	secret.Store(created.Secret)
+	if _, err := svc.CreateEndpoint(WebhookEndpointInput{Name: "Other", URL: server.URL, Events: []string{"ticket.created"}, Enabled: true}, nil); err != nil {
+		t.Fatalf("create second endpoint: %v", err)
+	}
+
+	count, err := svc.Publish(models.WebhookEventOrderPaid, map[string]interface{}{"order_no": "ORD-1"})
+	if err != nil || count != 1 || received.Load() != 1 {
+		t.Fatalf("only the subscribed endpoint should receive the event: count=%d received=%d err=%v", count, received.Load(), err)
+	}
+	deliveries, _, _ := svc.ListDeliveries(WebhookDeliveryFilter{EndpointID: created.ID}, 1, 10)
+	if len(deliveries) != 1 || deliveries[0].Status != models.WebhookDeliveryStatusSucceeded || deliveries[0].ResponseStatus != http.StatusOK {
+		t.Fatalf("unexpected delivery log: %+v", deliveries)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(deliveries[0].Payload), &payload); err != nil || payload["event"] != "order.paid" || payload["id"] != deliveries[0].EventID {
+		t.Fatalf("unexpected payload: %s, %v", deliveries[0].Payload, err)
+	}
+
+	failing.Store(true)
+	if _, err := svc.Publish(models.WebhookEventOrderPaid, map[string]interface{}{"order_no": "ORD-2"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	deliveries, _, _ = svc.ListDeliveries(WebhookDeliveryFilter{Status: string(models.WebhookDeliveryStatusPending)}, 1, 10)
+	if len(deliveries) != 1 || deliveries[0].Attempts != 1 || deliveries[0].ResponseStatus != http.StatusServiceUnavailable || deliveries[0].NextAttemptAt == nil {
+		t.Fatalf("failed delivery should be scheduled for retry: %+v", deliveries)
+	}
+	pending := deliveries[0]
+	if delay := pending.NextAttemptAt.Sub(pending.UpdatedAt); delay < 25*time.Second || delay > 35*time.Second {
+		t.Fatalf("first retry should wait about 30s, got %s", delay)
+	}
+	if svc.RetryDue() != 0 {
+		t.Fatal("deliveries must not be retried before their backoff expires")
+	}
+
+	failing.Store(false)
+	svc.db.Model(&pending).Update("next_attempt_at", models.NowFunc().Add(-time.Second))
+	if svc.RetryDue() != 1 {
+		t.Fatal("due delivery should be retried")
+	}
+	pending = models.WebhookDelivery{}
+	svc.db.First(&pending, deliveries[0].ID)
+	if pending.Status != models.WebhookDeliveryStatusSucceeded || pending.Attempts != 2 || pending.NextAttemptAt != nil {
+		t.Fatalf("retry should succeed: %+v", pending)
+	}
+
+	rotated, err := svc.RotateSecret(created.ID)
+	if err != nil || rotated.Secret == created.Secret {
+		t.Fatalf("rotate secret: %+v, %v", rotated, err)
+	}
+	redelivered, err := svc.Redeliver(pending.ID)
+	if err != nil || redelivered.Status != models.WebhookDeliveryStatusFailed || redelivered.EventID != pending.EventID {
+		t.Fatalf("redelivery signed with the new secret should be rejected by the receiver still using the old one: %+v, %v", redelivered, err)
+	}
+}
+
+func TestWebhookRetryDelayIsExponentialAndCapped(t *testing.T) {
+	cases := map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 3: 2 * time.Minute, 20: webhookRetryMaxDelay}
+	for attempts, want := range cases {
+		if got := webhookRetryDelay(attempts); got != want {
+			t.Fatalf("attempt %d: expected %s, got %s", attempts, want, got)
+		}
+	}
+}
+
+func TestWebhookRejectsPrivateURLs(t *testing.T) {
+	db := openConcurrentServiceTestDB(t, &models.WebhookEndpoint{}, &models.WebhookDelivery{})
+	svc := NewWebhookService(db)
+	if _, err := svc.CreateEndpoint(WebhookEndpointInput{Name: "Local", URL: "http://127.0.0.1:8080/hook", Events: []string{"*"}}, nil); refundErrorKey(err) != "webhook.urlInvalid" {
+		t.Fatalf("private addresses should be rejected, got %v", err)
+	}
+}
+
+func TestWebhookPlatformEndpointsOnlyReceiveTheirOrders(t *testing.T) {
+	var received atomic.Value
+	received.Store([]string{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		json.Unmarshal(body, &payload)
+		received.Store(append(received.Load().([]string), fmt.Sprintf("%s:%v", r.URL.Path, payload.Data["external_order_id"])))
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	svc := newWebhookTestService(t, server)
+
+	events := []string{string(models.WebhookEventOrderStatus)}
+	if _, err := svc.CreateEndpoint(WebhookEndpointInput{Name: "Shop", URL: server.URL + "/shop", Events: events, Enabled: true, Platform: " Shopify "}, nil); err != nil {
+		t.Fatalf("create platform endpoint: %v", err)
+	}
+	if _, err := svc.CreateEndpoint(WebhookEndpointInput{Name: "ERP", URL: server.URL + "/erp", Events: events, Enabled: true}, nil); err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	if _, err := svc.CreateEndpoint(WebhookEndpointInput{Name: "Long", URL: server.URL, Events: events, Platform: strings.Repeat("p", 101)}, nil); refundErrorKey(err) != "webhook.platformTooLong" {
+		t.Fatalf("long platform should be rejected, got %v", err)
+	}
+
+	order := &models.Order{OrderNo: "ORD-EXT", Status: models.OrderStatusShipped, SourcePlatform: "shopify", ExternalOrderID: "SP-1001"}
+	if count, err := svc.Publish(models.WebhookEventOrderStatus, buildWebhookOrderData(order)); err != nil || count != 2 {
+		t.Fatalf("platform order should reach both endpoints: count=%d err=%v", count, err)
+	}
+	other := &models.Order{OrderNo: "ORD-WOO", Status: models.OrderStatusShipped, SourcePlatform: "woocommerce", ExternalOrderID: "WC-7"}
+	if count, err := svc.Publish(models.WebhookEventOrderStatus, buildWebhookOrderData(other)); err != nil || count != 1 {
+		t.Fatalf("other platform orders should skip the shopify endpoint: count=%d err=%v", count, err)
+	}
+	got := received.Load().([]string)
+	want := []string{"/shop:SP-1001", "/erp:SP-1001", "/erp:WC-7"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected deliveries: %v", got)
+	}
+}
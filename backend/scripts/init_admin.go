@@ -82,6 +82,9 @@ func main() {
 		"order.refund",
 		"order.assign_tracking",
 		"order.request_resubmit",
+		// Return
+		"return.view",
+		"return.manage",
 		// Product
 		"product.view",
 		"product.edit",
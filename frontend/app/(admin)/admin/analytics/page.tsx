@@ -148,6 +148,13 @@ export default function AnalyticsPage() {
     refunded: t.order.status.refunded,
   }
 
+  const cancelReasonLabels: Record<string, string> = {
+    payment_timeout: t.admin.cancelReasonPaymentTimeout,
+    draft_expired: t.admin.cancelReasonDraftExpired,
+    merged: t.admin.cancelReasonMerged,
+    manual: t.admin.cancelReasonManual,
+  }
+
   const tooltipStyle = {
     contentStyle: { backgroundColor: chart.tooltipBg, border: `1px solid ${chart.tooltipBorder}`, borderRadius: '8px' },
     labelStyle: { color: chart.textColor },
@@ -564,6 +571,70 @@ export default function AnalyticsPage() {
               </CardContent>
             </Card>
 
+            {/* Tag Distribution */}
+            <Card>
+              <CardHeader>
+                <CardTitle>{t.admin.orderTagDistribution}</CardTitle>
+              </CardHeader>
+              <CardContent>
+                {orders?.tag_distribution?.length ? (
+                  <div className="max-h-[300px] overflow-auto">
+                    <table className="w-full text-sm">
+                      <thead>
+                        <tr className="border-b">
+                          <th className="text-left py-2 px-3 font-medium">{t.admin.orderTag}</th>
+                          <th className="text-right py-2 px-3 font-medium">{t.admin.orderCount}</th>
+                          <th className="text-right py-2 px-3 font-medium">{t.admin.paidOrders}</th>
+                          <th className="text-right py-2 px-3 font-medium">{t.admin.revenue}</th>
+                        </tr>
+                      </thead>
+                      <tbody>
+                        {orders.tag_distribution.map((item: any) => (
+                          <tr key={item.tag} className="border-b last:border-0 hover:bg-accent/50">
+                            <td className="py-2 px-3 font-medium">#{item.tag}</td>
+                            <td className="py-2 px-3 text-right">{item.count}</td>
+                            <td className="py-2 px-3 text-right">{item.paid_count}</td>
+                            <td className="py-2 px-3 text-right font-medium">
+                              {formatCurrency(item.paid_amount_minor, orders?.overview?.currency || 'CNY')}
+                            </td>
+                          </tr>
+                        ))}
+                      </tbody>
+                    </table>
+                  </div>
+                ) : (
+                  <EmptyState text={t.admin.noAnalyticsData} />
+                )}
+              </CardContent>
+            </Card>
+
+            {/* Cancel Reason Distribution */}
+            <Card>
+              <CardHeader>
+                <CardTitle>{t.admin.cancelReasonDistribution}</CardTitle>
+              </CardHeader>
+              <CardContent>
+                {orders?.cancel_reason_distribution?.length ? (
+                  <ResponsiveContainer width="100%" height={300}>
+                    <BarChart
+                      data={orders.cancel_reason_distribution.map((item: any) => ({
+                        ...item,
+                        label: cancelReasonLabels[item.reason] || item.reason,
+                      }))}
+                    >
+                      <CartesianGrid strokeDasharray="3 3" stroke={chart.gridColor} />
+                      <XAxis dataKey="label" tick={{ fontSize: 12, fill: chart.tickColor }} stroke={chart.gridColor} />
+                      <YAxis tick={{ fontSize: 12, fill: chart.tickColor }} stroke={chart.gridColor} />
+                      <Tooltip {...tooltipStyle} />
+                      <Bar dataKey="count" fill="#ef4444" radius={[4, 4, 0, 0]} name={t.admin.count} />
+                    </BarChart>
+                  </ResponsiveContainer>
+                ) : (
+                  <EmptyState text={t.admin.noAnalyticsData} />
+                )}
+              </CardContent>
+            </Card>
+
             {/* Amount Distribution */}
             <Card>
               <CardHeader>
@@ -2,7 +2,7 @@
 
 import { useState } from 'react'
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
-import { getApiKeys, createApiKey, deleteApiKey } from '@/lib/api'
+import { getApiKeys, createApiKey, deleteApiKey, getApiKeyUsage, type ApiKeyUsage } from '@/lib/api'
 import { resolveApiErrorMessage } from '@/lib/api-error'
 import { DataTable } from '@/components/admin/data-table'
 import { Button } from '@/components/ui/button'
@@ -36,7 +36,7 @@ import {
 import { Checkbox } from '@/components/ui/checkbox'
 import { useForm } from 'react-hook-form'
 import { useToast } from '@/hooks/use-toast'
-import { Copy, Plus, Trash2 } from 'lucide-react'
+import { Activity, Copy, Plus, Trash2 } from 'lucide-react'
 import { formatDate } from '@/lib/utils'
 import { PERMISSIONS, PERMISSIONS_BY_CATEGORY, CATEGORY_LABEL_KEYS } from '@/lib/constants'
 import { useLocale } from '@/hooks/use-locale'
@@ -45,6 +45,7 @@ import { usePageTitle } from '@/hooks/use-page-title'
 import { PluginExtensionList } from '@/components/plugins/plugin-extension-list'
 import { PluginSlot } from '@/components/plugins/plugin-slot'
 import { usePluginExtensionBatch } from '@/lib/plugin-extension-batch'
+import { WebhooksCard } from '@/components/admin/webhooks-card'
 
 interface ApiKeyItem {
   id: number
@@ -53,6 +54,7 @@ interface ApiKeyItem {
   platform?: string
   scopes?: string[]
   rate_limit?: number
+  burst_limit?: number
   created_at?: string
 }
 
@@ -65,6 +67,7 @@ function buildAdminApiKeySummary(apiKey: ApiKeyItem) {
     scopes: Array.isArray(apiKey.scopes) ? apiKey.scopes : [],
     scopes_count: Array.isArray(apiKey.scopes) ? apiKey.scopes.length : 0,
     rate_limit: apiKey.rate_limit,
+    burst_limit: apiKey.burst_limit,
     created_at: apiKey.created_at,
   }
 }
@@ -74,6 +77,7 @@ export default function ApiKeysPage() {
   const [secretDialogOpen, setSecretDialogOpen] = useState(false)
   const [newSecret, setNewSecret] = useState('')
   const [deleteTarget, setDeleteTarget] = useState<any>(null)
+  const [usageTarget, setUsageTarget] = useState<ApiKeyItem | null>(null)
   const queryClient = useQueryClient()
   const toast = useToast()
   const { locale } = useLocale()
@@ -90,12 +94,26 @@ export default function ApiKeysPage() {
   })
   const apiKeys: ApiKeyItem[] = data?.data?.items || []
 
+  const { data: usageData, isLoading: usageLoading } = useQuery({
+    queryKey: ['apiKeyUsage', usageTarget?.id],
+    queryFn: () => getApiKeyUsage(usageTarget!.id),
+    enabled: !!usageTarget,
+  })
+  const usage: ApiKeyUsage | undefined = usageData?.data
+  const usagePeak = Math.max(
+    1,
+    ...(usage?.hourly || []).map((bucket) => bucket.requests + bucket.throttled)
+  )
+
   const form = useForm({
     defaultValues: {
       key_name: '',
       platform: '',
+      webhook_url: '',
       scopes: [] as string[],
       rate_limit: 1000,
+      burst_limit: 0,
+      allow_duplicate_external_orders: false,
     },
   })
 
@@ -167,6 +185,7 @@ export default function ApiKeysPage() {
       key_name: form.watch('key_name') || undefined,
       platform: form.watch('platform') || undefined,
       rate_limit: form.watch('rate_limit') || undefined,
+      burst_limit: form.watch('burst_limit') || undefined,
       selected_scopes: form.watch('scopes') || [],
     },
     summary: adminApiKeysPluginContext.summary,
@@ -194,7 +213,16 @@ export default function ApiKeysPage() {
     {
       header: t.admin.rateLimit,
       cell: ({ row }: { row: { original: any } }) => (
-        <span>{t.admin.rateLimitDisplay.replace('{count}', String(row.original.rate_limit))}</span>
+        <div className="flex flex-col">
+          <span>
+            {t.admin.rateLimitDisplay.replace('{count}', String(row.original.rate_limit))}
+          </span>
+          {row.original.burst_limit ? (
+            <span className="text-xs text-muted-foreground">
+              {t.admin.rateLimitBurstDisplay.replace('{count}', String(row.original.burst_limit))}
+            </span>
+          ) : null}
+        </div>
       ),
     },
     {
@@ -208,6 +236,14 @@ export default function ApiKeysPage() {
         const rowExtensions = adminApiKeyRowActionExtensions[String(row.original.id)] || []
         return (
           <div className="flex items-center gap-2">
+            <Button
+              size="sm"
+              variant="outline"
+              title={t.admin.apiKeyUsage}
+              onClick={() => setUsageTarget(row.original)}
+            >
+              <Activity className="h-4 w-4" />
+            </Button>
             <Button size="sm" variant="destructive" onClick={() => setDeleteTarget(row.original)}>
               <Trash2 className="h-4 w-4" />
             </Button>
@@ -272,6 +308,44 @@ export default function ApiKeysPage() {
                   )}
                 />
 
+                <FormField
+                  control={form.control}
+                  name="webhook_url"
+                  render={({ field }) => (
+                    <FormItem>
+                      <FormLabel>{t.admin.apiKeyWebhookUrl}</FormLabel>
+                      <FormControl>
+                        <Input placeholder="https://example.com/webhooks/auralogic" {...field} />
+                      </FormControl>
+                      <p className="text-xs text-muted-foreground">{t.admin.apiKeyWebhookUrlHint}</p>
+                      <FormMessage />
+                    </FormItem>
+                  )}
+                />
+
+                <FormField
+                  control={form.control}
+                  name="allow_duplicate_external_orders"
+                  render={({ field }) => (
+                    <FormItem>
+                      <div className="flex items-center gap-2">
+                        <FormControl>
+                          <Checkbox
+                            checked={field.value}
+                            onCheckedChange={(checked) => field.onChange(checked === true)}
+                          />
+                        </FormControl>
+                        <FormLabel className="font-normal">
+                          {t.admin.allowDuplicateExternalOrders}
+                        </FormLabel>
+                      </div>
+                      <p className="text-xs text-muted-foreground">
+                        {t.admin.allowDuplicateExternalOrdersHint}
+                      </p>
+                    </FormItem>
+                  )}
+                />
+
                 <FormField
                   control={form.control}
                   name="rate_limit"
@@ -290,6 +364,26 @@ export default function ApiKeysPage() {
                   )}
                 />
 
+                <FormField
+                  control={form.control}
+                  name="burst_limit"
+                  render={({ field }) => (
+                    <FormItem>
+                      <FormLabel>{t.admin.burstLimit}</FormLabel>
+                      <FormControl>
+                        <Input
+                          type="number"
+                          min={0}
+                          {...field}
+                          onChange={(e) => field.onChange(parseInt(e.target.value) || 0)}
+                        />
+                      </FormControl>
+                      <p className="text-xs text-muted-foreground">{t.admin.burstLimitHint}</p>
+                      <FormMessage />
+                    </FormItem>
+                  )}
+                />
+
                 <FormField
                   control={form.control}
                   name="scopes"
@@ -432,6 +526,82 @@ export default function ApiKeysPage() {
 
       <DataTable columns={columns} data={apiKeys} isLoading={isLoading} />
 
+      <WebhooksCard />
+
+      <Dialog
+        open={!!usageTarget}
+        onOpenChange={(open) => {
+          if (!open) {
+            setUsageTarget(null)
+          }
+        }}
+      >
+        <DialogContent className="max-w-2xl">
+          <DialogHeader>
+            <DialogTitle>
+              {t.admin.apiKeyUsageTitle.replace('{name}', usageTarget?.key_name || '')}
+            </DialogTitle>
+          </DialogHeader>
+          {usageLoading || !usage ? (
+            <p className="text-sm text-muted-foreground">{t.common.loading}</p>
+          ) : (
+            <div className="space-y-4">
+              <div className="grid grid-cols-3 gap-3">
+                <Card>
+                  <CardContent className="p-4">
+                    <p className="text-xs text-muted-foreground">{t.admin.apiKeyUsageRequests}</p>
+                    <p className="text-2xl font-semibold">{usage.total_requests}</p>
+                  </CardContent>
+                </Card>
+                <Card>
+                  <CardContent className="p-4">
+                    <p className="text-xs text-muted-foreground">{t.admin.apiKeyUsageThrottled}</p>
+                    <p className="text-2xl font-semibold">{usage.total_throttled}</p>
+                  </CardContent>
+                </Card>
+                <Card>
+                  <CardContent className="p-4">
+                    <p className="text-xs text-muted-foreground">{t.admin.apiKeyUsageRemaining}</p>
+                    <p className="text-2xl font-semibold">
+                      {usage.rate_limit > 0
+                        ? `${usage.remaining_tokens}/${usage.burst_limit}`
+                        : '∞'}
+                    </p>
+                  </CardContent>
+                </Card>
+              </div>
+              {usage.hourly.length === 0 ? (
+                <p className="text-sm text-muted-foreground">{t.admin.apiKeyUsageEmpty}</p>
+              ) : (
+                <div className="max-h-72 space-y-1 overflow-y-auto text-xs">
+                  {usage.hourly.map((bucket) => (
+                    <div key={bucket.hour} className="flex items-center gap-2">
+                      <span className="w-24 shrink-0 text-muted-foreground">
+                        {formatDate(bucket.hour, 'MM-dd HH:00')}
+                      </span>
+                      <div className="flex h-3 flex-1 overflow-hidden rounded bg-muted">
+                        <div
+                          className="bg-primary"
+                          style={{ width: `${(bucket.requests / usagePeak) * 100}%` }}
+                        />
+                        <div
+                          className="bg-red-500"
+                          style={{ width: `${(bucket.throttled / usagePeak) * 100}%` }}
+                        />
+                      </div>
+                      <span className="w-20 shrink-0 text-right tabular-nums">
+                        {bucket.requests}
+                        {bucket.throttled > 0 ? ` / ${bucket.throttled}` : ''}
+                      </span>
+                    </div>
+                  ))}
+                </div>
+              )}
+            </div>
+          )}
+        </DialogContent>
+      </Dialog>
+
       {/* Secret Key Dialog */}
       <Dialog
         open={secretDialogOpen}
@@ -0,0 +1,414 @@
+'use client'
+
+import { useState } from 'react'
+import Link from 'next/link'
+import { useMutation, useQuery } from '@tanstack/react-query'
+import { RefreshCw } from 'lucide-react'
+import toast from 'react-hot-toast'
+import {
+  getCODCollections,
+  getCODReport,
+  recordCODCollection,
+  settleCODCollections,
+  type CODCarrierSummary,
+  type CODCollection,
+} from '@/lib/api'
+import { DataTable } from '@/components/admin/data-table'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
+import { Checkbox } from '@/components/ui/checkbox'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Textarea } from '@/components/ui/textarea'
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow,
+} from '@/components/ui/table'
+import { useDebounce } from '@/hooks/use-debounce'
+import { useLocale } from '@/hooks/use-locale'
+import { usePageTitle } from '@/hooks/use-page-title'
+import { usePermission } from '@/hooks/use-permission'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatCurrency, formatDate, parseMajorToMinor } from '@/lib/utils'
+
+export default function AdminCODPage() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  usePageTitle(t.pageTitle.adminCODReconciliation)
+  const { hasPermission } = usePermission()
+  const canEdit = hasPermission('order.status_update')
+
+  const [page, setPage] = useState(1)
+  const [status, setStatus] = useState('all')
+  const [carrierFilter, setCarrierFilter] = useState('')
+  const debouncedCarrier = useDebounce(carrierFilter)
+  const [selectedIds, setSelectedIds] = useState<number[]>([])
+  const [recordTarget, setRecordTarget] = useState<CODCollection | null>(null)
+  const [carrier, setCarrier] = useState('')
+  const [collectedAmount, setCollectedAmount] = useState('')
+  const [remark, setRemark] = useState('')
+
+  const statusConfig: Record<string, { label: string; color: string }> = {
+    pending: {
+      label: t.admin.codStatusPending,
+      color: 'bg-yellow-500/20 text-yellow-700 dark:text-yellow-400',
+    },
+    collected: {
+      label: t.admin.codStatusCollected,
+      color: 'bg-blue-500/20 text-blue-700 dark:text-blue-400',
+    },
+    settled: {
+      label: t.admin.codStatusSettled,
+      color: 'bg-green-500/20 text-green-700 dark:text-green-400',
+    },
+    cancelled: {
+      label: t.admin.codStatusCancelled,
+      color: 'bg-gray-500/20 text-gray-700 dark:text-gray-400',
+    },
+  }
+
+  const { data: reportData, refetch: refetchReport } = useQuery({
+    queryKey: ['adminCODReport'],
+    queryFn: getCODReport,
+  })
+  const report: CODCarrierSummary[] = reportData?.data?.items || []
+
+  const { data, isLoading, refetch } = useQuery({
+    queryKey: ['adminCODCollections', page, status, debouncedCarrier],
+    queryFn: () =>
+      getCODCollections({
+        page,
+        limit: 20,
+        status: status === 'all' ? undefined : status,
+        carrier: debouncedCarrier || undefined,
+      }),
+  })
+  const collections: CODCollection[] = data?.data?.items || []
+
+  const refreshAll = () => {
+    refetch()
+    refetchReport()
+  }
+
+  const openRecordDialog = (collection: CODCollection) => {
+    setRecordTarget(collection)
+    setCarrier('')
+    setCollectedAmount((collection.amount_due / 100).toFixed(2))
+    setRemark('')
+  }
+
+  const recordMutation = useMutation({
+    mutationFn: () => {
+      const amount = parseMajorToMinor(collectedAmount)
+      if (amount === null || amount <= 0) {
+        return Promise.reject(new Error(t.admin.codCollectedAmount))
+      }
+      return recordCODCollection(recordTarget!.order_id, {
+        carrier: carrier.trim(),
+        collected_amount: amount,
+        remark: remark || undefined,
+      })
+    },
+    onSuccess: () => {
+      toast.success(t.admin.codRecordSuccess)
+      setRecordTarget(null)
+      refreshAll()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.operationFailed))
+    },
+  })
+
+  const settleMutation = useMutation({
+    mutationFn: () => settleCODCollections(selectedIds),
+    onSuccess: (response: any) => {
+      toast.success(t.admin.codSettleSuccess.replace('{count}', String(response.data.settled)))
+      setSelectedIds([])
+      refreshAll()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.operationFailed))
+    },
+  })
+
+  const toggleSelected = (id: number, checked: boolean) => {
+    setSelectedIds((prev) => (checked ? [...prev, id] : prev.filter((item) => item !== id)))
+  }
+
+  const columns = [
+    {
+      header: '',
+      cell: ({ row }: { row: { original: CODCollection } }) =>
+        canEdit && row.original.status === 'collected' ? (
+          <Checkbox
+            checked={selectedIds.includes(row.original.id)}
+            onCheckedChange={(checked) => toggleSelected(row.original.id, checked === true)}
+          />
+        ) : null,
+    },
+    {
+      header: t.admin.orderNo,
+      cell: ({ row }: { row: { original: CODCollection } }) => (
+        <Link
+          href={`/admin/orders/${row.original.order_id}`}
+          className="font-mono text-sm hover:underline"
+        >
+          {row.original.order_no}
+        </Link>
+      ),
+    },
+    {
+      header: t.admin.codCarrier,
+      cell: ({ row }: { row: { original: CODCollection } }) => row.original.carrier || '-',
+    },
+    {
+      header: t.admin.codAmountDue,
+      cell: ({ row }: { row: { original: CODCollection } }) => (
+        <span className="tabular-nums">
+          {formatCurrency(row.original.amount_due, row.original.currency)}
+        </span>
+      ),
+    },
+    {
+      header: t.admin.codCollectedAmount,
+      cell: ({ row }: { row: { original: CODCollection } }) =>
+        row.original.collected_at ? (
+          <span
+            className={
+              row.original.collected_amount < row.original.amount_due
+                ? 'tabular-nums text-red-600 dark:text-red-400'
+                : 'tabular-nums'
+            }
+          >
+            {formatCurrency(row.original.collected_amount, row.original.currency)}
+          </span>
+        ) : (
+          '-'
+        ),
+    },
+    {
+      header: t.admin.codCollectedAt,
+      cell: ({ row }: { row: { original: CODCollection } }) =>
+        row.original.collected_at ? (
+          <span className="text-xs">{formatDate(row.original.collected_at)}</span>
+        ) : (
+          '-'
+        ),
+    },
+    {
+      header: t.admin.status,
+      cell: ({ row }: { row: { original: CODCollection } }) => {
+        const config = statusConfig[row.original.status] || statusConfig.pending
+        return <Badge className={config.color}>{config.label}</Badge>
+      },
+    },
+    {
+      header: t.admin.actions,
+      cell: ({ row }: { row: { original: CODCollection } }) =>
+        canEdit && row.original.status === 'pending' ? (
+          <Button size="sm" variant="outline" onClick={() => openRecordDialog(row.original)}>
+            {t.admin.codRecordCollection}
+          </Button>
+        ) : null,
+    },
+  ]
+
+  return (
+    <div className="space-y-6">
+      <div className="flex flex-col gap-4 md:flex-row md:items-start md:justify-between">
+        <div>
+          <h1 className="text-3xl font-bold">{t.admin.codReconciliation}</h1>
+          <p className="mt-1 text-sm text-muted-foreground">{t.admin.codReconciliationDesc}</p>
+        </div>
+        <Button variant="outline" onClick={refreshAll}>
+          <RefreshCw className="mr-2 h-4 w-4" />
+          {t.admin.refresh}
+        </Button>
+      </div>
+
+      <Card>
+        <CardHeader>
+          <CardTitle>{t.admin.codReport}</CardTitle>
+        </CardHeader>
+        <CardContent>
+          {report.length === 0 ? (
+            <p className="text-sm text-muted-foreground">{t.admin.codNoCollections}</p>
+          ) : (
+            <Table>
+              <TableHeader>
+                <TableRow>
+                  <TableHead>{t.admin.codCarrier}</TableHead>
+                  <TableHead className="text-right">{t.admin.codPending}</TableHead>
+                  <TableHead className="text-right">{t.admin.codOutstanding}</TableHead>
+                  <TableHead className="text-right">{t.admin.codSettled}</TableHead>
+                  <TableHead className="text-right">{t.admin.codShortfall}</TableHead>
+                </TableRow>
+              </TableHeader>
+              <TableBody>
+                {report.map((summary) => {
+                  const format = (amount: number, count: number) =>
+                    `${formatCurrency(amount, summary.currency)} (${count})`
+                  return (
+                    <TableRow key={`${summary.carrier}-${summary.currency}`}>
+                      <TableCell className="font-medium">
+                        {summary.carrier || t.admin.codCarrierUnassigned}
+                      </TableCell>
+                      <TableCell className="text-right tabular-nums">
+                        {format(summary.pending_amount, summary.pending_count)}
+                      </TableCell>
+                      <TableCell className="text-right font-medium tabular-nums">
+                        {format(summary.outstanding_amount, summary.outstanding_count)}
+                      </TableCell>
+                      <TableCell className="text-right tabular-nums">
+                        {format(summary.settled_amount, summary.settled_count)}
+                      </TableCell>
+                      <TableCell className="text-right tabular-nums">
+                        {formatCurrency(summary.shortfall_amount, summary.currency)}
+                      </TableCell>
+                    </TableRow>
+                  )
+                })}
+              </TableBody>
+            </Table>
+          )}
+        </CardContent>
+      </Card>
+
+      <Card>
+        <CardHeader
+          className={
+            'flex flex-col gap-3 space-y-0 md:flex-row md:items-center md:justify-between'
+          }
+        >
+          <CardTitle>{t.admin.codCollections}</CardTitle>
+          <div className="flex flex-wrap gap-2">
+            <Input
+              value={carrierFilter}
+              onChange={(e) => {
+                setCarrierFilter(e.target.value)
+                setPage(1)
+              }}
+              placeholder={t.admin.codFilterCarrier}
+              className="w-[180px]"
+            />
+            <Select
+              value={status}
+              onValueChange={(value) => {
+                setStatus(value)
+                setPage(1)
+                setSelectedIds([])
+              }}
+            >
+              <SelectTrigger className="w-[160px]">
+                <SelectValue />
+              </SelectTrigger>
+              <SelectContent>
+                <SelectItem value="all">{t.admin.codStatusAll}</SelectItem>
+                <SelectItem value="pending">{t.admin.codStatusPending}</SelectItem>
+                <SelectItem value="collected">{t.admin.codStatusCollected}</SelectItem>
+                <SelectItem value="settled">{t.admin.codStatusSettled}</SelectItem>
+                <SelectItem value="cancelled">{t.admin.codStatusCancelled}</SelectItem>
+              </SelectContent>
+            </Select>
+            {canEdit ? (
+              <Button
+                onClick={() => settleMutation.mutate()}
+                disabled={selectedIds.length === 0 || settleMutation.isPending}
+              >
+                {t.admin.codSettleSelected.replace('{count}', String(selectedIds.length))}
+              </Button>
+            ) : null}
+          </div>
+        </CardHeader>
+        <CardContent>
+          <DataTable
+            columns={columns}
+            data={collections}
+            isLoading={isLoading}
+            pagination={{
+              page,
+              total_pages: data?.data?.pagination?.total_pages || 1,
+              onPageChange: setPage,
+            }}
+          />
+        </CardContent>
+      </Card>
+
+      <Dialog open={!!recordTarget} onOpenChange={(open) => !open && setRecordTarget(null)}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{t.admin.codRecordCollection}</DialogTitle>
+            <DialogDescription>
+              {t.admin.codRecordCollectionDesc
+                .replace('{orderNo}', recordTarget?.order_no || '')
+                .replace(
+                  '{amount}',
+                  recordTarget ? formatCurrency(recordTarget.amount_due, recordTarget.currency) : ''
+                )}
+            </DialogDescription>
+          </DialogHeader>
+          <div className="space-y-4">
+            <div className="space-y-2">
+              <Label htmlFor="cod-carrier">{t.admin.codCarrier}</Label>
+              <Input
+                id="cod-carrier"
+                value={carrier}
+                onChange={(e) => setCarrier(e.target.value)}
+              />
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="cod-amount">{t.admin.codCollectedAmount}</Label>
+              <Input
+                id="cod-amount"
+                inputMode="decimal"
+                value={collectedAmount}
+                onChange={(e) => setCollectedAmount(e.target.value)}
+              />
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="cod-remark">{t.admin.paymentMatchRemark}</Label>
+              <Textarea
+                id="cod-remark"
+                value={remark}
+                onChange={(e) => setRemark(e.target.value)}
+                rows={3}
+              />
+            </div>
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setRecordTarget(null)}>
+              {t.common.cancel}
+            </Button>
+            <Button
+              onClick={() => recordMutation.mutate()}
+              disabled={recordMutation.isPending || !carrier.trim() || !collectedAmount}
+            >
+              {t.common.confirm}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </div>
+  )
+}
@@ -21,6 +21,7 @@ import { getTranslations } from '@/lib/i18n'
 import { usePageTitle } from '@/hooks/use-page-title'
 import { resolveApiErrorMessage } from '@/lib/api-error'
 import { PluginSlot } from '@/components/plugins/plugin-slot'
+import { DimensionInputs, type DimensionValues } from '@/components/admin/dimension-inputs'
 
 export default function InventoryDetailPage({ params }: { params: Promise<{ id: string }> }) {
   const { id } = use(params)
@@ -46,6 +47,12 @@ export default function InventoryDetailPage({ params }: { params: Promise<{ id:
   const [isActive, setIsActive] = useState(true)
   const [alertEmail, setAlertEmail] = useState<string>('')
   const [notes, setNotes] = useState<string>('')
+  const [dimensions, setDimensions] = useState<DimensionValues>({
+    weight_grams: 0,
+    length_mm: 0,
+    width_mm: 0,
+    height_mm: 0,
+  })
 
   // 调整库存状态
   const [adjustStockValue, setAdjustStockValue] = useState<string>('')
@@ -62,6 +69,12 @@ export default function InventoryDetailPage({ params }: { params: Promise<{ id:
       setIsActive(inventory.is_active)
       setAlertEmail(inventory.alert_email || '')
       setNotes(inventory.notes || '')
+      setDimensions({
+        weight_grams: inventory.weight_grams ?? 0,
+        length_mm: inventory.length_mm ?? 0,
+        width_mm: inventory.width_mm ?? 0,
+        height_mm: inventory.height_mm ?? 0,
+      })
     }
   }, [inventory])
 
@@ -121,6 +134,7 @@ export default function InventoryDetailPage({ params }: { params: Promise<{ id:
       is_active: isActive,
       alert_email: alertEmail || undefined,
       notes: notes || undefined,
+      ...dimensions,
     })
   }
 
@@ -380,6 +394,12 @@ export default function InventoryDetailPage({ params }: { params: Promise<{ id:
                   />
                 </div>
 
+                <DimensionInputs
+                  value={dimensions}
+                  onChange={setDimensions}
+                  hint={t.admin.inventoryDimensionsHint}
+                />
+
                 <div className="space-y-2">
                   <Label htmlFor="notes">{t.admin.invNotes}</Label>
                   <Textarea
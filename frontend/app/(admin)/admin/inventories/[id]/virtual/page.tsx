@@ -12,6 +12,10 @@ import {
   createVirtualInventoryStockManually,
   reserveVirtualInventoryStock,
   releaseVirtualInventoryStock,
+  revokeVirtualInventoryStock,
+  revealVirtualInventoryStock,
+  getVirtualInventoryStockReveals,
+  setVirtualInventoryBatchExpiry,
   testDeliveryScript
 } from '@/lib/api'
 import { Card, CardContent, CardHeader, CardTitle, CardDescription } from '@/components/ui/card'
@@ -61,7 +65,7 @@ import {
   AlertDialogTitle,
   AlertDialogTrigger,
 } from '@/components/ui/alert-dialog'
-import { ArrowLeft, Save, Plus, Trash2, RefreshCw, Database, FileText, Upload, Loader2, Lock, Unlock, Code2, Play, BookOpen } from 'lucide-react'
+import { ArrowLeft, Save, Plus, Trash2, RefreshCw, Database, FileText, Upload, Loader2, Lock, Unlock, Code2, Play, BookOpen, Ban, CalendarClock, ArrowRightLeft, Eye, EyeOff, History } from 'lucide-react'
 import Link from 'next/link'
 import { useToast } from '@/hooks/use-toast'
 import {
@@ -78,6 +82,16 @@ import { ConfigEditor } from '@/components/admin/config-editor'
 import { resolveApiErrorMessage } from '@/lib/api-error'
 import { PluginSlot } from '@/components/plugins/plugin-slot'
 import { LazyCodeEditor } from '@/components/ui/lazy-code-editor'
+import { VirtualStockTransferDialog } from '@/components/admin/virtual-stock-transfer-dialog'
+
+// datetime-local 输入值与 ISO 时间互转（有效期留空表示不过期）
+const toExpiryISO = (value: string) => (value ? new Date(value).toISOString() : undefined)
+
+const toDateTimeLocal = (value?: string) => {
+  if (!value) return ''
+  const date = new Date(value)
+  return new Date(date.getTime() - date.getTimezoneOffset() * 60000).toISOString().slice(0, 16)
+}
 
 // Example delivery scripts
 const SCRIPT_EXAMPLE_BASIC = `// Generate random activation codes
@@ -588,6 +602,7 @@ export default function VirtualInventoryEditPage() {
     description: '',
     total_limit: 0,
     allow_inline_iframe: false,
+    delivery_order: '',
     is_active: true,
     notes: ''
   })
@@ -601,6 +616,17 @@ export default function VirtualInventoryEditPage() {
   const [manualDialogOpen, setManualDialogOpen] = useState(false)
   const [manualContent, setManualContent] = useState('')
   const [manualRemark, setManualRemark] = useState('')
+  const [importExpiresAt, setImportExpiresAt] = useState('')
+  const [manualExpiresAt, setManualExpiresAt] = useState('')
+  const [batchExpiryTarget, setBatchExpiryTarget] = useState<string | null>(null)
+  const [batchExpiresAt, setBatchExpiresAt] = useState('')
+  const [transferDialogOpen, setTransferDialogOpen] = useState(false)
+  const [revokeTarget, setRevokeTarget] = useState<any>(null)
+  const [revokeReason, setRevokeReason] = useState('')
+  const [revokeReissue, setRevokeReissue] = useState(true)
+  const [revokeNotify, setRevokeNotify] = useState(true)
+  const [revealedStocks, setRevealedStocks] = useState<Record<number, string>>({})
+  const [revealLogTarget, setRevealLogTarget] = useState<any>(null)
 
   const { data: inventoryData, isLoading: inventoryLoading, refetch: refetchInventory } = useQuery({
     queryKey: ['virtualInventory', inventoryId],
@@ -619,6 +645,7 @@ export default function VirtualInventoryEditPage() {
       description: inv.description || '',
       total_limit: inv.total_limit || 0,
       allow_inline_iframe: !!inv.allow_inline_iframe,
+      delivery_order: inv.delivery_order || '',
       is_active: inv.is_active ?? true,
       notes: inv.notes || ''
     })
@@ -648,12 +675,13 @@ export default function VirtualInventoryEditPage() {
 
   const importMutation = useMutation({
     mutationFn: (data: { import_type: 'file' | 'text'; file?: File; content?: string }) =>
-      importVirtualInventoryStock(inventoryId, data),
+      importVirtualInventoryStock(inventoryId, { ...data, expires_at: toExpiryISO(importExpiresAt) }),
     onSuccess: (response: any) => {
       toast.success(t.admin.importSuccessCount.replace('{count}', String(response?.data?.count || 0)))
       setImportDialogOpen(false)
       setTextContent('')
       setSelectedFile(null)
+      setImportExpiresAt('')
       refetchStocks()
       refetchInventory()
     },
@@ -664,12 +692,16 @@ export default function VirtualInventoryEditPage() {
 
   const manualCreateMutation = useMutation({
     mutationFn: (data: { content: string; remark?: string }) =>
-      createVirtualInventoryStockManually(inventoryId, data),
+      createVirtualInventoryStockManually(inventoryId, {
+        ...data,
+        expires_at: toExpiryISO(manualExpiresAt),
+      }),
     onSuccess: () => {
       toast.success(t.admin.addSuccess)
       setManualDialogOpen(false)
       setManualContent('')
       setManualRemark('')
+      setManualExpiresAt('')
       refetchStocks()
       refetchInventory()
     },
@@ -714,6 +746,80 @@ export default function VirtualInventoryEditPage() {
     },
   })
 
+  const revokeMutation = useMutation({
+    mutationFn: (stockId: number) =>
+      revokeVirtualInventoryStock(inventoryId, stockId, {
+        reason: revokeReason.trim(),
+        reissue: inventory?.type !== 'script' && revokeReissue,
+        notify: revokeNotify,
+      }),
+    onSuccess: () => {
+      toast.success(t.admin.revokeSuccess)
+      setRevokeTarget(null)
+      refetchStocks()
+      refetchInventory()
+    },
+    onError: (error: unknown) => {
+      toast.error(formatActionError(error, t.admin.revokeFailed))
+    },
+  })
+
+  // 列表中的卡密已脱敏，显示明文会在后端留下查看记录
+  const revealMutation = useMutation({
+    mutationFn: (stockId: number) => revealVirtualInventoryStock(inventoryId, stockId),
+    onSuccess: (response: any, stockId) => {
+      setRevealedStocks((prev) => ({ ...prev, [stockId]: response?.data?.content || '' }))
+    },
+    onError: (error: unknown) => {
+      toast.error(formatActionError(error, t.admin.revealStockFailed))
+    },
+  })
+
+  const hideStockContent = (stockId: number) => {
+    setRevealedStocks((prev) => {
+      const next = { ...prev }
+      delete next[stockId]
+      return next
+    })
+  }
+
+  const { data: revealLogsData, isLoading: revealLogsLoading } = useQuery({
+    queryKey: ['virtualStockReveals', inventoryId, revealLogTarget?.id],
+    queryFn: () => getVirtualInventoryStockReveals(inventoryId, revealLogTarget.id),
+    enabled: !!revealLogTarget,
+  })
+  const revealLogs: any[] = revealLogsData?.data?.items || []
+
+  const batchExpiryMutation = useMutation({
+    mutationFn: (batchNo: string) =>
+      setVirtualInventoryBatchExpiry(inventoryId, {
+        batch_no: batchNo,
+        expires_at: toExpiryISO(batchExpiresAt),
+      }),
+    onSuccess: (response: any) => {
+      toast.success(
+        t.admin.batchExpiryUpdated.replace('{count}', String(response?.data?.updated || 0))
+      )
+      setBatchExpiryTarget(null)
+      refetchStocks()
+    },
+    onError: (error: unknown) => {
+      toast.error(formatActionError(error, t.admin.batchExpiryFailed))
+    },
+  })
+
+  const openBatchExpiryDialog = (stock: any) => {
+    setBatchExpiryTarget(stock.batch_no)
+    setBatchExpiresAt(toDateTimeLocal(stock.expires_at))
+  }
+
+  const openRevokeDialog = (stock: any) => {
+    setRevokeTarget(stock)
+    setRevokeReason('')
+    setRevokeReissue(true)
+    setRevokeNotify(true)
+  }
+
   const testMutation = useMutation({
     mutationFn: ({ script, config, quantity }: { script: string; config: Record<string, any>; quantity: number }) =>
       testDeliveryScript(script, config, quantity),
@@ -855,6 +961,8 @@ export default function VirtualInventoryEditPage() {
         return <Badge variant="outline">{t.admin.statusSold}</Badge>
       case 'invalid':
         return <Badge variant="destructive">{t.admin.statusInvalid}</Badge>
+      case 'revoked':
+        return <Badge variant="destructive">{t.admin.statusRevoked}</Badge>
       default:
         return <Badge variant="outline">{status}</Badge>
     }
@@ -920,6 +1028,10 @@ export default function VirtualInventoryEditPage() {
                 <Upload className="mr-2 h-4 w-4" />
                 {t.admin.batchImportBtn}
               </Button>
+              <Button variant="outline" onClick={() => setTransferDialogOpen(true)}>
+                <ArrowRightLeft className="mr-2 h-4 w-4" />
+                {t.admin.transferStockBtn}
+              </Button>
             </>
           )}
         </div>
@@ -957,7 +1069,7 @@ export default function VirtualInventoryEditPage() {
           )}
         </div>
       ) : (
-        <div className="grid grid-cols-2 md:grid-cols-4 gap-4">
+        <div className="grid grid-cols-2 md:grid-cols-5 gap-4">
           <Card>
             <CardContent className="pt-6">
               <div className="text-2xl font-bold">{inventory.total || 0}</div>
@@ -982,6 +1094,12 @@ export default function VirtualInventoryEditPage() {
               <p className="text-sm text-muted-foreground">{t.admin.statusSold}</p>
             </CardContent>
           </Card>
+          <Card>
+            <CardContent className="pt-6">
+              <div className="text-2xl font-bold text-red-600 dark:text-red-400">{inventory.revoked || 0}</div>
+              <p className="text-sm text-muted-foreground">{t.admin.statusRevoked}</p>
+            </CardContent>
+          </Card>
         </div>
       )}
 
@@ -1039,6 +1157,29 @@ export default function VirtualInventoryEditPage() {
               />
             </div>
           )}
+          {editForm.type !== 'script' && (
+            <div className="space-y-2">
+              <Label htmlFor="delivery_order">{t.admin.virtualDeliveryOrder}</Label>
+              <Select
+                value={editForm.delivery_order || 'inherit'}
+                onValueChange={(value) =>
+                  setEditForm({ ...editForm, delivery_order: value === 'inherit' ? '' : value })
+                }
+              >
+                <SelectTrigger id="delivery_order">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="inherit">{t.admin.virtualDeliveryInheritGlobal}</SelectItem>
+                  <SelectItem value="random">{t.admin.virtualDeliveryRandom}</SelectItem>
+                  <SelectItem value="newest">{t.admin.virtualDeliveryNewest}</SelectItem>
+                  <SelectItem value="oldest">{t.admin.virtualDeliveryOldest}</SelectItem>
+                  <SelectItem value="expiring">{t.admin.virtualDeliveryExpiring}</SelectItem>
+                </SelectContent>
+              </Select>
+              <p className="text-xs text-muted-foreground">{t.admin.virtualDeliveryOverrideHint}</p>
+            </div>
+          )}
           <div className="space-y-2">
             <Label htmlFor="description">{t.admin.descriptionLabel}</Label>
             <Textarea
@@ -1303,6 +1444,7 @@ export default function VirtualInventoryEditPage() {
                   <SelectItem value="reserved">{t.admin.statusReserved}</SelectItem>
                   <SelectItem value="sold">{t.admin.statusSold}</SelectItem>
                   <SelectItem value="invalid">{t.admin.statusInvalid}</SelectItem>
+                  <SelectItem value="revoked">{t.admin.statusRevoked}</SelectItem>
                 </SelectContent>
               </Select>
               <Button variant="outline" onClick={() => refetchStocks()}>
@@ -1331,6 +1473,7 @@ export default function VirtualInventoryEditPage() {
                     <TableHead>{t.admin.statusColumn}</TableHead>
                     <TableHead>{t.admin.orderNoColumn}</TableHead>
                     <TableHead>{t.admin.batchNoColumn}</TableHead>
+                    <TableHead>{t.admin.expiresAtColumn}</TableHead>
                     <TableHead>{t.admin.createdAtColumn}</TableHead>
                     <TableHead>{t.admin.operationsColumn}</TableHead>
                   </TableRow>
@@ -1339,8 +1482,36 @@ export default function VirtualInventoryEditPage() {
                   {stocks.map((stock: any) => (
                     <TableRow key={stock.id}>
                       <TableCell className="font-mono">{stock.id}</TableCell>
-                      <TableCell className="font-mono max-w-xs truncate" title={stock.content}>
-                        {stock.content}
+                      <TableCell className="font-mono max-w-xs">
+                        <div className="flex items-center gap-1">
+                          <span className="truncate">
+                            {revealedStocks[stock.id] ?? stock.content}
+                          </span>
+                          {stock.content && (
+                            <Button
+                              size="icon"
+                              variant="ghost"
+                              className="h-6 w-6 shrink-0"
+                              onClick={() =>
+                                revealedStocks[stock.id] !== undefined
+                                  ? hideStockContent(stock.id)
+                                  : revealMutation.mutate(stock.id)
+                              }
+                              disabled={revealMutation.isPending}
+                              title={
+                                revealedStocks[stock.id] !== undefined
+                                  ? t.admin.hideStockContent
+                                  : t.admin.revealStockContent
+                              }
+                            >
+                              {revealedStocks[stock.id] !== undefined ? (
+                                <EyeOff className="h-3 w-3" />
+                              ) : (
+                                <Eye className="h-3 w-3" />
+                              )}
+                            </Button>
+                          )}
+                        </div>
                       </TableCell>
                       <TableCell className="text-sm text-muted-foreground max-w-xs truncate">
                         {stock.remark || '-'}
@@ -1350,7 +1521,46 @@ export default function VirtualInventoryEditPage() {
                         {stock.order_no || '-'}
                       </TableCell>
                       <TableCell className="text-sm text-muted-foreground">
-                        {stock.batch_no || '-'}
+                        <div className="flex items-center gap-1">
+                          <span>{stock.batch_no || '-'}</span>
+                          {stock.batch_no &&
+                            (stock.status === 'available' || stock.status === 'reserved') && (
+                              <Button
+                                size="icon"
+                                variant="ghost"
+                                className="h-6 w-6"
+                                onClick={() => openBatchExpiryDialog(stock)}
+                                title={t.admin.batchExpiryTitle}
+                              >
+                                <CalendarClock className="h-3 w-3" />
+                              </Button>
+                            )}
+                        </div>
+                      </TableCell>
+                      <TableCell className="text-sm text-muted-foreground">
+                        {stock.expires_at ? (
+                          <div className="flex flex-col gap-1">
+                            <span>{new Date(stock.expires_at).toLocaleString()}</span>
+                            {stock.status === 'available' &&
+                              new Date(stock.expires_at).getTime() <= Date.now() && (
+                                <Badge variant="destructive" className="w-fit">
+                                  {t.admin.stockExpired}
+                                </Badge>
+                              )}
+                            {stock.status === 'available' &&
+                              stock.expiry_warned_at &&
+                              new Date(stock.expires_at).getTime() > Date.now() && (
+                                <Badge
+                                  variant="outline"
+                                  className="w-fit border-amber-500 text-amber-600"
+                                >
+                                  {t.admin.stockExpiringSoon}
+                                </Badge>
+                              )}
+                          </div>
+                        ) : (
+                          '-'
+                        )}
                       </TableCell>
                       <TableCell className="text-sm text-muted-foreground">
                         {new Date(stock.created_at).toLocaleString()}
@@ -1425,6 +1635,24 @@ export default function VirtualInventoryEditPage() {
                               </AlertDialog>
                             </>
                           )}
+                          {stock.status === 'sold' && stock.order_id && (
+                            <Button
+                              size="sm"
+                              variant="outline"
+                              onClick={() => openRevokeDialog(stock)}
+                              title={t.admin.revokeStock}
+                            >
+                              <Ban className="h-3 w-3" />
+                            </Button>
+                          )}
+                          <Button
+                            size="sm"
+                            variant="outline"
+                            onClick={() => setRevealLogTarget(stock)}
+                            title={t.admin.stockRevealLogs}
+                          >
+                            <History className="h-3 w-3" />
+                          </Button>
                         </div>
                       </TableCell>
                     </TableRow>
@@ -1547,6 +1775,17 @@ export default function VirtualInventoryEditPage() {
             </TabsContent>
           </Tabs>
 
+          <div className="space-y-2">
+            <Label htmlFor="import_expires_at">{t.admin.expiresAtOptionalLabel}</Label>
+            <Input
+              id="import_expires_at"
+              type="datetime-local"
+              value={importExpiresAt}
+              onChange={(e) => setImportExpiresAt(e.target.value)}
+            />
+            <p className="text-xs text-muted-foreground">{t.admin.expiresAtHint}</p>
+          </div>
+
           <DialogFooter>
             <Button variant="outline" onClick={() => setImportDialogOpen(false)}>
               {t.common.cancel}
@@ -1594,6 +1833,16 @@ export default function VirtualInventoryEditPage() {
                 onChange={(e) => setManualRemark(e.target.value)}
               />
             </div>
+
+            <div className="space-y-2">
+              <Label htmlFor="manual_expires_at">{t.admin.expiresAtOptionalLabel}</Label>
+              <Input
+                id="manual_expires_at"
+                type="datetime-local"
+                value={manualExpiresAt}
+                onChange={(e) => setManualExpiresAt(e.target.value)}
+              />
+            </div>
           </div>
 
           <DialogFooter>
@@ -1606,6 +1855,150 @@ export default function VirtualInventoryEditPage() {
           </DialogFooter>
         </DialogContent>
       </Dialog>
+
+      <Dialog open={!!revokeTarget} onOpenChange={(open) => !open && setRevokeTarget(null)}>
+        <DialogContent className="max-w-md">
+          <DialogHeader>
+            <DialogTitle>{t.admin.revokeStockTitle}</DialogTitle>
+            <DialogDescription>
+              {t.admin.revokeStockDesc.replace('{orderNo}', revokeTarget?.order_no || '-')}
+            </DialogDescription>
+          </DialogHeader>
+
+          <div className="space-y-4">
+            <div className="space-y-2">
+              <Label htmlFor="revoke_reason">{t.admin.revokeReasonLabel}</Label>
+              <Textarea
+                id="revoke_reason"
+                placeholder={t.admin.revokeReasonPlaceholder}
+                value={revokeReason}
+                onChange={(e) => setRevokeReason(e.target.value)}
+                rows={2}
+              />
+            </div>
+            {inventory.type !== 'script' && (
+              <div className="flex items-center space-x-2">
+                <Switch id="revoke_reissue" checked={revokeReissue} onCheckedChange={setRevokeReissue} />
+                <Label htmlFor="revoke_reissue">{t.admin.revokeReissueLabel}</Label>
+              </div>
+            )}
+            <div className="flex items-center space-x-2">
+              <Switch id="revoke_notify" checked={revokeNotify} onCheckedChange={setRevokeNotify} />
+              <Label htmlFor="revoke_notify">{t.admin.revokeNotifyLabel}</Label>
+            </div>
+          </div>
+
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setRevokeTarget(null)}>
+              {t.common.cancel}
+            </Button>
+            <Button
+              variant="destructive"
+              onClick={() => revokeTarget && revokeMutation.mutate(revokeTarget.id)}
+              disabled={!revokeReason.trim() || revokeMutation.isPending}
+            >
+              {t.admin.revokeStock}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+
+      <Dialog open={!!revealLogTarget} onOpenChange={(open) => !open && setRevealLogTarget(null)}>
+        <DialogContent className="max-w-2xl">
+          <DialogHeader>
+            <DialogTitle>{t.admin.stockRevealLogs}</DialogTitle>
+            <DialogDescription>
+              {t.admin.stockRevealLogsDesc.replace('{id}', String(revealLogTarget?.id || ''))}
+            </DialogDescription>
+          </DialogHeader>
+          {revealLogsLoading ? (
+            <div className="flex justify-center py-6">
+              <Loader2 className="h-6 w-6 animate-spin text-muted-foreground" />
+            </div>
+          ) : revealLogs.length === 0 ? (
+            <p className="py-6 text-center text-sm text-muted-foreground">
+              {t.admin.noStockRevealLogs}
+            </p>
+          ) : (
+            <Table>
+              <TableHeader>
+                <TableRow>
+                  <TableHead>{t.admin.revealedAtColumn}</TableHead>
+                  <TableHead>{t.admin.revealViewerColumn}</TableHead>
+                  <TableHead>IP</TableHead>
+                </TableRow>
+              </TableHeader>
+              <TableBody>
+                {revealLogs.map((log: any) => (
+                  <TableRow key={log.id}>
+                    <TableCell className="text-sm">
+                      {new Date(log.created_at).toLocaleString()}
+                    </TableCell>
+                    <TableCell className="text-sm">
+                      <Badge variant="outline" className="mr-2">
+                        {log.viewer_type === 'admin'
+                          ? t.admin.revealViewerAdmin
+                          : log.viewer_type === 'link'
+                            ? t.admin.revealViewerLink
+                            : t.admin.revealViewerUser}
+                      </Badge>
+                      {log.viewer_email || (log.viewer_id ? `#${log.viewer_id}` : '-')}
+                    </TableCell>
+                    <TableCell className="font-mono text-sm">{log.ip || '-'}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          )}
+        </DialogContent>
+      </Dialog>
+
+      <VirtualStockTransferDialog
+        inventoryId={inventoryId}
+        open={transferDialogOpen}
+        onOpenChange={setTransferDialogOpen}
+        onTransferred={() => {
+          refetchStocks()
+          refetchInventory()
+        }}
+      />
+
+      <Dialog
+        open={!!batchExpiryTarget}
+        onOpenChange={(open) => !open && setBatchExpiryTarget(null)}
+      >
+        <DialogContent className="max-w-md">
+          <DialogHeader>
+            <DialogTitle>{t.admin.batchExpiryTitle}</DialogTitle>
+            <DialogDescription>
+              {t.admin.batchExpiryDesc.replace('{batchNo}', batchExpiryTarget || '-')}
+            </DialogDescription>
+          </DialogHeader>
+
+          <div className="space-y-2">
+            <Label htmlFor="batch_expires_at">{t.admin.expiresAtOptionalLabel}</Label>
+            <Input
+              id="batch_expires_at"
+              type="datetime-local"
+              value={batchExpiresAt}
+              onChange={(e) => setBatchExpiresAt(e.target.value)}
+            />
+            <p className="text-xs text-muted-foreground">{t.admin.expiresAtHint}</p>
+          </div>
+
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setBatchExpiryTarget(null)}>
+              {t.common.cancel}
+            </Button>
+            <Button
+              onClick={() => batchExpiryTarget && batchExpiryMutation.mutate(batchExpiryTarget)}
+              disabled={batchExpiryMutation.isPending}
+            >
+              {t.common.save}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
     </div>
   )
 }
@@ -17,6 +17,7 @@ import { getTranslations } from '@/lib/i18n'
 import { usePageTitle } from '@/hooks/use-page-title'
 import { resolveApiErrorMessage } from '@/lib/api-error'
 import { PluginSlot } from '@/components/plugins/plugin-slot'
+import { DimensionInputs, type DimensionValues } from '@/components/admin/dimension-inputs'
 
 export default function CreateInventoryPage() {
   const router = useRouter()
@@ -35,6 +36,12 @@ export default function CreateInventoryPage() {
   const [safetyStock, setSafetyStock] = useState<string>('0')
   const [alertEmail, setAlertEmail] = useState<string>('')
   const [notes, setNotes] = useState<string>('')
+  const [dimensions, setDimensions] = useState<DimensionValues>({
+    weight_grams: 0,
+    length_mm: 0,
+    width_mm: 0,
+    height_mm: 0,
+  })
   const validAttributeCount = attributes.filter((attr) => attr.key && attr.value).length
   const adminInventoryNewPluginContext = {
     view: 'admin_inventory_new',
@@ -120,6 +127,7 @@ export default function CreateInventoryPage() {
       safety_stock: parseInt(safetyStock) || 0,
       alert_email: alertEmail || undefined,
       notes: notes || undefined,
+      ...dimensions,
     })
   }
 
@@ -270,6 +278,13 @@ export default function CreateInventoryPage() {
               />
             </div>
 
+            {/* 重量与尺寸 */}
+            <DimensionInputs
+              value={dimensions}
+              onChange={setDimensions}
+              hint={t.admin.inventoryDimensionsHint}
+            />
+
             {/* 备注 */}
             <div className="space-y-2">
               <Label htmlFor="notes">{t.admin.notesLabel}</Label>
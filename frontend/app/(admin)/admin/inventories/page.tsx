@@ -76,6 +76,7 @@ import { getTranslations } from '@/lib/i18n'
 import { usePageTitle } from '@/hooks/use-page-title'
 import { resolveApiErrorMessage } from '@/lib/api-error'
 import { PluginSlot } from '@/components/plugins/plugin-slot'
+import { VirtualStockExpiryCard } from '@/components/admin/virtual-stock-expiry-card'
 
 export default function InventoriesPage() {
   return (
@@ -1002,6 +1003,8 @@ function InventoriesContent() {
               )}
             </CardContent>
           </Card>
+
+          <VirtualStockExpiryCard />
         </TabsContent>
       </Tabs>
 
@@ -10,6 +10,11 @@ import {
   getLogStatistics,
   retryFailedEmails,
   getInventoryLogs,
+  getSlowQueries,
+  resetSlowQueries,
+  getBackgroundJobs,
+  getScraperOffenders,
+  banScraperOffender,
 } from '@/lib/api'
 import { resolveClientAPIProxyURL } from '@/lib/api-base-url'
 import { DataTable } from '@/components/admin/data-table'
@@ -27,7 +32,17 @@ import {
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
 import { Tabs, TabsContent, TabsList, TabsTrigger } from '@/components/ui/tabs'
 import { useToast } from '@/hooks/use-toast'
-import { Download, FileText, Mail, RefreshCw, Package, Smartphone } from 'lucide-react'
+import {
+  Database,
+  Download,
+  FileText,
+  Mail,
+  RefreshCw,
+  Package,
+  ShieldAlert,
+  Smartphone,
+  Timer,
+} from 'lucide-react'
 import { formatDate } from '@/lib/utils'
 import { useLocale } from '@/hooks/use-locale'
 import { getTranslations } from '@/lib/i18n'
@@ -249,6 +264,39 @@ export default function LogsPage() {
     queryFn: getLogStatistics,
   })
 
+  // 慢查询报表（仅在切换到该标签时加载）
+  const {
+    data: slowQueries,
+    isLoading: slowQueriesLoading,
+    refetch: refetchSlowQueries,
+  } = useQuery({
+    queryKey: ['slowQueries'],
+    queryFn: () => getSlowQueries(),
+    enabled: activeTab === 'slow-queries',
+  })
+
+  // 后台任务状态（仅在切换到该标签时加载）
+  const {
+    data: backgroundJobs,
+    isLoading: backgroundJobsLoading,
+    refetch: refetchBackgroundJobs,
+  } = useQuery({
+    queryKey: ['backgroundJobs'],
+    queryFn: getBackgroundJobs,
+    enabled: activeTab === 'background-jobs',
+  })
+
+  // 目录防爬命中最多的 IP（仅在切换到该标签时加载）
+  const {
+    data: scraperOffenders,
+    isLoading: scraperOffendersLoading,
+    refetch: refetchScraperOffenders,
+  } = useQuery({
+    queryKey: ['scraperOffenders'],
+    queryFn: () => getScraperOffenders(),
+    enabled: activeTab === 'scrapers',
+  })
+
   // 库存日志查询
   const { data: inventoryLogs, isLoading: inventoryLoading } = useQuery({
     queryKey: ['inventoryLogs', inventoryPage, inventoryFilters],
@@ -271,7 +319,13 @@ export default function LogsPage() {
           ? t.admin.inventoryLogs
           : activeTab === 'sms'
             ? t.admin.smsLogs
-            : t.admin.systemLogs
+            : activeTab === 'slow-queries'
+              ? t.admin.slowQueries
+              : activeTab === 'background-jobs'
+                ? t.admin.backgroundJobs
+                : activeTab === 'scrapers'
+                  ? t.admin.scraperOffenders
+                  : t.admin.systemLogs
   const handleExport = useCallback(() => {
     let path = '/api/admin/logs/operations/export'
     let fileName = `operation_logs_${new Date().toISOString().slice(0, 10)}.xlsx`
@@ -381,13 +435,15 @@ export default function LogsPage() {
               inventoryFilters.start_date,
               inventoryFilters.end_date,
             ]
-          : [
-              smsFilters.status,
-              smsFilters.event_type,
-              smsFilters.phone,
-              smsFilters.start_date,
-              smsFilters.end_date,
-            ]
+          : activeTab === 'sms'
+            ? [
+                smsFilters.status,
+                smsFilters.event_type,
+                smsFilters.phone,
+                smsFilters.start_date,
+                smsFilters.end_date,
+              ]
+            : []
   ).filter(Boolean)
   const resolveOperationResourceLabel = useCallback(
     (resourceType: string) => {
@@ -620,6 +676,28 @@ export default function LogsPage() {
     },
   })
 
+  const resetSlowQueriesMutation = useMutation({
+    mutationFn: resetSlowQueries,
+    onSuccess: () => {
+      toast.success(t.admin.slowQueriesCleared)
+      queryClient.invalidateQueries({ queryKey: ['slowQueries'] })
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveLogError(error, t.admin.slowQueriesClearFailed))
+    },
+  })
+
+  const banScraperOffenderMutation = useMutation({
+    mutationFn: banScraperOffender,
+    onSuccess: () => {
+      toast.success(t.admin.scraperBanSuccess)
+      queryClient.invalidateQueries({ queryKey: ['scraperOffenders'] })
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveLogError(error, t.admin.scraperBanFailed))
+    },
+  })
+
   // 操作日志列定义
   const operationColumns = [
     {
@@ -810,10 +888,12 @@ export default function LogsPage() {
           </p>
         </div>
         <div className="flex gap-2">
-          <Button variant="outline" onClick={handleExport}>
-            <Download className="mr-2 h-4 w-4" />
-            {t.admin.exportCurrentLogs}
-          </Button>
+          {!['slow-queries', 'background-jobs', 'scrapers'].includes(activeTab) && (
+            <Button variant="outline" onClick={handleExport}>
+              <Download className="mr-2 h-4 w-4" />
+              {t.admin.exportCurrentLogs}
+            </Button>
+          )}
         </div>
       </div>
 
@@ -918,6 +998,18 @@ export default function LogsPage() {
             <Smartphone className="mr-2 h-4 w-4" />
             {t.admin.smsLogs}
           </TabsTrigger>
+          <TabsTrigger value="slow-queries">
+            <Database className="mr-2 h-4 w-4" />
+            {t.admin.slowQueries}
+          </TabsTrigger>
+          <TabsTrigger value="background-jobs">
+            <Timer className="mr-2 h-4 w-4" />
+            {t.admin.backgroundJobs}
+          </TabsTrigger>
+          <TabsTrigger value="scrapers">
+            <ShieldAlert className="mr-2 h-4 w-4" />
+            {t.admin.scraperOffenders}
+          </TabsTrigger>
         </TabsList>
 
         <TabsContent value="operations" className="space-y-4">
@@ -1231,6 +1323,10 @@ export default function LogsPage() {
                       <SelectItem value="import">{t.admin.stockImport}</SelectItem>
                       <SelectItem value="deliver">{t.admin.stockDeliver}</SelectItem>
                       <SelectItem value="delete">{t.admin.stockDelete}</SelectItem>
+                      <SelectItem value="return">{t.admin.stockReturn}</SelectItem>
+                      <SelectItem value="revoke">{t.admin.stockRevoke}</SelectItem>
+                      <SelectItem value="expire">{t.admin.stockExpire}</SelectItem>
+                      <SelectItem value="transfer">{t.admin.stockTransfer}</SelectItem>
                     </SelectContent>
                   </Select>
                 </div>
@@ -1322,6 +1418,10 @@ export default function LogsPage() {
                     import: { label: t.admin.stockImport, color: 'default' },
                     deliver: { label: t.admin.stockDeliver, color: 'default' },
                     delete: { label: t.admin.stockDelete, color: 'destructive' },
+                    return: { label: t.admin.stockReturn, color: 'default' },
+                    revoke: { label: t.admin.stockRevoke, color: 'destructive' },
+                    expire: { label: t.admin.stockExpire, color: 'secondary' },
+                    transfer: { label: t.admin.stockTransfer, color: 'secondary' },
                   }
                   const config = typeMap[row.original.type] || {
                     label: row.original.type,
@@ -1548,6 +1648,233 @@ export default function LogsPage() {
             }}
           />
         </TabsContent>
+
+        <TabsContent value="slow-queries" className="space-y-4">
+          <Card>
+            <CardHeader className="flex flex-row items-start justify-between space-y-0">
+              <div>
+                <CardTitle className="text-base">{t.admin.slowQueries}</CardTitle>
+                <CardDescription>
+                  {slowQueries?.data?.threshold_ms
+                    ? t.admin.slowQueriesDesc.replace(
+                        '{threshold}',
+                        String(slowQueries.data.threshold_ms)
+                      )
+                    : t.admin.slowQueriesDisabled}
+                </CardDescription>
+              </div>
+              <div className="flex gap-2">
+                <Button variant="outline" size="sm" onClick={() => refetchSlowQueries()}>
+                  <RefreshCw className="mr-2 h-4 w-4" />
+                  {t.admin.refresh}
+                </Button>
+                <Button
+                  variant="outline"
+                  size="sm"
+                  onClick={() => resetSlowQueriesMutation.mutate()}
+                  disabled={resetSlowQueriesMutation.isPending}
+                >
+                  {t.admin.slowQueriesClear}
+                </Button>
+              </div>
+            </CardHeader>
+          </Card>
+
+          <DataTable
+            columns={[
+              {
+                header: t.admin.slowQuerySql,
+                accessorKey: 'fingerprint',
+                cell: ({ row }: any) => (
+                  <code className="block max-w-xl whitespace-pre-wrap break-all text-xs">
+                    {row.original.fingerprint}
+                  </code>
+                ),
+              },
+              {
+                header: t.admin.slowQueryCount,
+                accessorKey: 'count',
+              },
+              {
+                header: t.admin.slowQueryAvgMs,
+                cell: ({ row }: any) => row.original.avg_ms.toFixed(1),
+              },
+              {
+                header: t.admin.slowQueryMaxMs,
+                cell: ({ row }: any) => row.original.max_ms.toFixed(1),
+              },
+              {
+                header: t.admin.slowQueryTotalMs,
+                cell: ({ row }: any) => row.original.total_ms.toFixed(1),
+              },
+              {
+                header: t.admin.slowQueryLastSeen,
+                cell: ({ row }: any) =>
+                  row.original.last_seen_at ? formatDate(row.original.last_seen_at) : '-',
+              },
+            ]}
+            data={slowQueries?.data?.items || []}
+            isLoading={slowQueriesLoading}
+          />
+        </TabsContent>
+
+        <TabsContent value="background-jobs" className="space-y-4">
+          <Card>
+            <CardHeader className="flex flex-row items-start justify-between space-y-0">
+              <div>
+                <CardTitle className="text-base">{t.admin.backgroundJobs}</CardTitle>
+                <CardDescription>{t.admin.backgroundJobsDesc}</CardDescription>
+              </div>
+              <Button variant="outline" size="sm" onClick={() => refetchBackgroundJobs()}>
+                <RefreshCw className="mr-2 h-4 w-4" />
+                {t.admin.refresh}
+              </Button>
+            </CardHeader>
+          </Card>
+
+          <DataTable
+            columns={[
+              {
+                header: t.admin.backgroundJobName,
+                accessorKey: 'name',
+                cell: ({ row }: any) => <code className="text-xs">{row.original.name}</code>,
+              },
+              {
+                header: t.admin.backgroundJobSchedule,
+                cell: ({ row }: any) =>
+                  row.original.kind === 'loop'
+                    ? t.admin.backgroundJobKindLoop
+                    : row.original.jitter_ms > 0
+                      ? `${row.original.schedule} ${t.admin.backgroundJobJitter.replace(
+                          '{seconds}',
+                          String(Math.round(row.original.jitter_ms / 1000))
+                        )}`
+                      : row.original.schedule,
+              },
+              {
+                header: t.admin.backgroundJobHealth,
+                cell: ({ row }: any) => (
+                  <div className="flex flex-wrap gap-1">
+                    <Badge variant={row.original.healthy ? 'secondary' : 'destructive'}>
+                      {row.original.healthy
+                        ? t.admin.backgroundJobHealthy
+                        : t.admin.backgroundJobUnhealthy}
+                    </Badge>
+                    {row.original.running && (
+                      <Badge variant="outline">{t.admin.backgroundJobRunning}</Badge>
+                    )}
+                  </div>
+                ),
+              },
+              {
+                header: t.admin.backgroundJobLastRun,
+                cell: ({ row }: any) =>
+                  row.original.last_started_at ? (
+                    <div className="text-xs">
+                      <div>{formatDate(row.original.last_started_at)}</div>
+                      {row.original.last_finished_at && (
+                        <div className="text-muted-foreground">
+                          {row.original.last_duration_ms} ms
+                        </div>
+                      )}
+                    </div>
+                  ) : (
+                    '-'
+                  ),
+              },
+              {
+                header: t.admin.backgroundJobNextRun,
+                cell: ({ row }: any) =>
+                  row.original.next_run_at ? formatDate(row.original.next_run_at) : '-',
+              },
+              {
+                header: t.admin.backgroundJobRuns,
+                cell: ({ row }: any) =>
+                  `${row.original.run_count} / ${row.original.failure_count} / ${row.original.skipped_count}`,
+              },
+              {
+                header: t.admin.backgroundJobLastError,
+                cell: ({ row }: any) =>
+                  row.original.last_error ? (
+                    <span className="block max-w-xs break-all text-xs text-destructive">
+                      {row.original.last_error}
+                    </span>
+                  ) : (
+                    '-'
+                  ),
+              },
+            ]}
+            data={backgroundJobs?.data?.items || []}
+            isLoading={backgroundJobsLoading}
+          />
+        </TabsContent>
+
+        <TabsContent value="scrapers" className="space-y-4">
+          <Card>
+            <CardHeader className="flex flex-row items-start justify-between space-y-0">
+              <div>
+                <CardTitle className="text-base">{t.admin.scraperOffenders}</CardTitle>
+                <CardDescription>{t.admin.scraperOffendersDesc}</CardDescription>
+              </div>
+              <Button variant="outline" size="sm" onClick={() => refetchScraperOffenders()}>
+                <RefreshCw className="mr-2 h-4 w-4" />
+                {t.admin.refresh}
+              </Button>
+            </CardHeader>
+          </Card>
+
+          <DataTable
+            columns={[
+              {
+                header: t.admin.scraperIP,
+                accessorKey: 'ip_address',
+                cell: ({ row }: any) => <code className="text-xs">{row.original.ip_address}</code>,
+              },
+              {
+                header: t.admin.scraperEvents,
+                accessorKey: 'events',
+              },
+              {
+                header: t.admin.scraperEventBreakdown,
+                cell: ({ row }: any) =>
+                  `${row.original.burst_events} / ${row.original.honeypot_events} / ${row.original.challenge_events}`,
+              },
+              {
+                header: t.admin.scraperLastSeen,
+                cell: ({ row }: any) => (
+                  <div className="text-xs">
+                    <div>
+                      {row.original.last_seen_at ? formatDate(row.original.last_seen_at) : '-'}
+                    </div>
+                    {row.original.last_user_agent && (
+                      <div className="max-w-xs truncate text-muted-foreground">
+                        {row.original.last_user_agent}
+                      </div>
+                    )}
+                  </div>
+                ),
+              },
+              {
+                header: t.admin.actions,
+                cell: ({ row }: any) =>
+                  row.original.banned ? (
+                    <Badge variant="destructive">{t.admin.scraperBanned}</Badge>
+                  ) : (
+                    <Button
+                      variant="outline"
+                      size="sm"
+                      disabled={banScraperOffenderMutation.isPending}
+                      onClick={() => banScraperOffenderMutation.mutate(row.original.ip_address)}
+                    >
+                      {t.admin.scraperBan}
+                    </Button>
+                  ),
+              },
+            ]}
+            data={scraperOffenders?.data?.items || []}
+            isLoading={scraperOffendersLoading}
+          />
+        </TabsContent>
       </Tabs>
     </div>
   )
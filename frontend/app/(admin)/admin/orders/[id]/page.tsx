@@ -16,13 +16,26 @@ import {
   requestOrderResubmit,
   getCountries,
   adminMarkOrderAsPaid,
+  adminSimulateOrderPayment,
   updateOrderPrice,
+  updateOrderTags,
   adminDeliverVirtualStock,
+  adminRedeliverVirtualStock,
+  revealVirtualInventoryStock,
   adminRefundOrder,
   adminConfirmRefund,
 } from '@/lib/api'
 import { resolveApiErrorMessage } from '@/lib/api-error'
+import { resolveClientAPIProxyURL } from '@/lib/api-base-url'
 import { OrderDetail } from '@/components/orders/order-detail'
+import { OrderNotesCard } from '@/components/admin/order-notes-card'
+import { OrderPackagePlanCard } from '@/components/admin/order-package-plan-card'
+import { OrderTrackingCard } from '@/components/admin/order-tracking-card'
+import { OrderRefundsCard } from '@/components/admin/order-refunds-card'
+import { OrderReturnDialog } from '@/components/admin/order-return-dialog'
+import { OrderMergeDialog } from '@/components/admin/order-merge-dialog'
+import { OrderMessagesCard } from '@/components/orders/order-messages-card'
+import { usePermission } from '@/hooks/use-permission'
 import { Button } from '@/components/ui/button'
 import { Input } from '@/components/ui/input'
 import { Textarea } from '@/components/ui/textarea'
@@ -78,6 +91,9 @@ import {
   DollarSign,
   Key,
   Undo2,
+  FileText,
+  GitMerge,
+  X,
 } from 'lucide-react'
 import Link from 'next/link'
 import { useToast } from '@/hooks/use-toast'
@@ -88,6 +104,8 @@ import { resolvePaymentMethodIcon } from '@/lib/payment-method-icons'
 import { Alert, AlertDescription, AlertTitle } from '@/components/ui/alert'
 import { Badge } from '@/components/ui/badge'
 import { OrderStatusBadge } from '@/components/orders/order-status-badge'
+import type { VirtualProductStock } from '@/types/product'
+import type { OrderMergeRef } from '@/types/order'
 
 export default function AdminOrderDetailPage({ params }: { params: Promise<{ id: string }> }) {
   const { id } = use(params)
@@ -96,6 +114,7 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
   const { locale } = useLocale()
   const t = getTranslations(locale)
   usePageTitle(t.pageTitle.adminOrderDetail)
+  const { hasPermission } = usePermission()
   const [trackingNo, setTrackingNo] = useState('')
   const [adminRemark, setAdminRemark] = useState('')
   const [cancelReason, setCancelReason] = useState('')
@@ -113,8 +132,13 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
   const [openResubmit, setOpenResubmit] = useState(false)
   const [openDeliverVirtual, setOpenDeliverVirtual] = useState(false)
   const [openUpdatePrice, setOpenUpdatePrice] = useState(false)
+  const [openReturn, setOpenReturn] = useState(false)
+  const [openMerge, setOpenMerge] = useState(false)
   const [markOnlyShipped, setMarkOnlyShipped] = useState(false)
+  const [redeliverStock, setRedeliverStock] = useState<VirtualProductStock | null>(null)
+  const [redeliverReason, setRedeliverReason] = useState('')
   const [newPrice, setNewPrice] = useState('')
+  const [newTag, setNewTag] = useState('')
   const [formAccess, setFormAccess] = useState<{
     form_url?: string
     form_token?: string
@@ -207,6 +231,7 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
           : t.order.orderRefunded
       )
       queryClient.invalidateQueries({ queryKey: ['adminOrderDetail', orderId] })
+      queryClient.invalidateQueries({ queryKey: ['adminOrderRefunds', orderId] })
       setOpenRefund(false)
       setRefundReason('')
     },
@@ -224,6 +249,7 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
     onSuccess: () => {
       toast.success(t.order.refundConfirmed)
       queryClient.invalidateQueries({ queryKey: ['adminOrderDetail', orderId] })
+      queryClient.invalidateQueries({ queryKey: ['adminOrderRefunds', orderId] })
       setOpenConfirmRefund(false)
       setConfirmRefundRemark('')
       setConfirmRefundTransactionId('')
@@ -285,6 +311,17 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
     },
   })
 
+  const simulatePaymentMutation = useMutation({
+    mutationFn: () => adminSimulateOrderPayment(orderId),
+    onSuccess: () => {
+      toast.success(t.order.sandboxPaymentSimulated)
+      queryClient.invalidateQueries({ queryKey: ['adminOrderDetail', orderId] })
+    },
+    onError: (error: any) => {
+      showOrderError(error, t.order.operationFailed)
+    },
+  })
+
   const updatePriceMutation = useMutation({
     mutationFn: (amountMinor: number) => updateOrderPrice(orderId, amountMinor),
     onSuccess: (response: any) => {
@@ -302,6 +339,18 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
     },
   })
 
+  const updateTagsMutation = useMutation({
+    mutationFn: (tags: string[]) => updateOrderTags(orderId, tags),
+    onSuccess: () => {
+      setNewTag('')
+      queryClient.invalidateQueries({ queryKey: ['adminOrderDetail', orderId] })
+      queryClient.invalidateQueries({ queryKey: ['adminOrders'] })
+    },
+    onError: (error: any) => {
+      showOrderError(error, t.order.updateFailed)
+    },
+  })
+
   const deliverVirtualMutation = useMutation({
     mutationFn: (onlyMarkShipped: boolean) =>
       adminDeliverVirtualStock(orderId, { mark_only_shipped: onlyMarkShipped }),
@@ -319,6 +368,30 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
     },
   })
 
+  const redeliverMutation = useMutation({
+    mutationFn: (stock: VirtualProductStock) =>
+      adminRedeliverVirtualStock(orderId, stock.id, { reason: redeliverReason.trim() }),
+    onSuccess: () => {
+      toast.success(t.order.redeliverVirtualStockSuccess)
+      queryClient.invalidateQueries({ queryKey: ['adminOrderDetail', orderId] })
+      setRedeliverStock(null)
+      setRedeliverReason('')
+    },
+    onError: (error: any) => {
+      showOrderError(error, t.order.redeliverVirtualStockFailed)
+    },
+  })
+
+  const revealStockContent = async (stock: VirtualProductStock) => {
+    try {
+      const res: any = await revealVirtualInventoryStock(stock.virtual_inventory_id, stock.id)
+      return String(res?.data?.content || '')
+    } catch (error: any) {
+      showOrderError(error, t.order.revealVirtualStockFailed)
+      throw error
+    }
+  }
+
   // 获取国家列表
   useEffect(() => {
     getCountries()
@@ -411,6 +484,16 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
     order.items?.some((item: any) => (item.product_type || item.productType) === 'virtual') ??
     false
   const canMarkPaid = order.status === 'pending_payment'
+  const orderTags: string[] = order.tags || []
+  const canEditTags = hasPermission('order.edit')
+  const addOrderTag = () => {
+    const tag = newTag.trim().toLowerCase()
+    if (!tag || orderTags.includes(tag)) {
+      setNewTag('')
+      return
+    }
+    updateTagsMutation.mutate([...orderTags, tag])
+  }
   const canUpdatePrice = canMarkPaid
   const canDeliverVirtual =
     hasVirtualItems &&
@@ -438,8 +521,45 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
     order.status === 'draft' ||
     order.status === 'cancelled' ||
     order.status === 'refunded'
+  const hasPhysicalItems = (order.items || []).some(
+    (item: any) => (item.product_type || item.productType) !== 'virtual'
+  )
+  // 已发货后才可登记退货（回补库存、作废序列号）
+  const canReceiveReturn =
+    hasPhysicalItems &&
+    !!order.shipped_at &&
+    ['shipped', 'completed', 'refund_pending', 'refunded'].includes(order.status) &&
+    hasPermission('order.refund')
+  // 待发货的实物订单可合并同一买家、同一收货人的其他待发货订单
+  const canMergeOrders =
+    order.status === 'pending' && hasPhysicalItems && hasPermission('order.edit')
+  const mergedOrders: OrderMergeRef[] = data.data.merged_orders || []
+  const mergedIntoOrderId: number | undefined = order.merged_into_order_id
   const secondaryActionCount =
-    Number(canCancel) + Number(canRefund) + Number(canConfirmRefund) + Number(canDelete)
+    Number(canMergeOrders) +
+    Number(canCancel) +
+    Number(canRefund) +
+    Number(canConfirmRefund) +
+    Number(canReceiveReturn) +
+    Number(canDelete)
+
+  // 报关单为后端渲染的可打印 HTML，先打开窗口避免被拦截
+  const handleOpenCustomsDeclaration = async () => {
+    const popup = window.open('', '_blank')
+    try {
+      const res = await fetch(
+        resolveClientAPIProxyURL(`/api/admin/orders/${orderId}/customs-declaration?format=html`)
+      )
+      if (!res.ok) {
+        throw await res.json().catch(() => null)
+      }
+      const blob = await res.blob()
+      if (popup) popup.location.href = window.URL.createObjectURL(blob)
+    } catch (error) {
+      popup?.close()
+      toast.error(resolveApiErrorMessage(error, t, t.admin.customsDeclarationFailed))
+    }
+  }
   const adminOrderDetailPluginContext = {
     view: 'admin_order_detail',
     order: {
@@ -615,6 +735,40 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
           </AlertDescription>
         </Alert>
       )}
+      {(mergedIntoOrderId || mergedOrders.length > 0) && (
+        <Alert>
+          <GitMerge className="h-4 w-4" />
+          <AlertDescription className="space-y-1">
+            {mergedIntoOrderId && (
+              <p>
+                {t.order.mergedIntoOrder}{' '}
+                <Link
+                  href={`/admin/orders/${mergedIntoOrderId}`}
+                  className="font-mono text-primary hover:underline"
+                >
+                  {order.merged_into_order_no || `#${mergedIntoOrderId}`}
+                </Link>
+              </p>
+            )}
+            {mergedOrders.length > 0 && (
+              <p>
+                {t.order.mergedFromOrders}{' '}
+                {mergedOrders.map((merged, index) => (
+                  <span key={merged.id}>
+                    {index > 0 && ', '}
+                    <Link
+                      href={`/admin/orders/${merged.id}`}
+                      className="font-mono text-primary hover:underline"
+                    >
+                      {merged.order_no}
+                    </Link>
+                  </span>
+                ))}
+              </p>
+            )}
+          </AlertDescription>
+        </Alert>
+      )}
       <div className="flex flex-col gap-3 xl:flex-row xl:items-start xl:justify-between">
         <div className="flex items-center gap-4">
           <Button asChild variant="outline" size="sm">
@@ -624,8 +778,55 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
             </Link>
           </Button>
           <h1 className="text-lg font-bold md:text-xl">{t.order.orderDetail}</h1>
+          {order.is_sandbox && (
+            <Badge
+              variant="outline"
+              className="border-amber-400 text-amber-700 dark:text-amber-300"
+            >
+              {t.order.sandboxOrder}
+            </Badge>
+          )}
         </div>
 
+        {/* 订单标签 */}
+        {(canEditTags || orderTags.length > 0) && (
+          <div className="flex flex-wrap items-center gap-1.5">
+            <span className="text-xs text-muted-foreground">{t.order.tags}:</span>
+            {orderTags.map((tag) => (
+              <Badge key={tag} variant="secondary" className="gap-1 text-xs">
+                #{tag}
+                {canEditTags && (
+                  <button
+                    type="button"
+                    aria-label={t.order.removeTag}
+                    onClick={() =>
+                      updateTagsMutation.mutate(orderTags.filter((item) => item !== tag))
+                    }
+                    disabled={updateTagsMutation.isPending}
+                  >
+                    <X className="h-3 w-3" />
+                  </button>
+                )}
+              </Badge>
+            ))}
+            {canEditTags && (
+              <Input
+                value={newTag}
+                onChange={(e) => setNewTag(e.target.value)}
+                onKeyDown={(e) => {
+                  if (e.key === 'Enter') {
+                    e.preventDefault()
+                    addOrderTag()
+                  }
+                }}
+                placeholder={t.order.addTagPlaceholder}
+                className="h-6 w-32 text-xs"
+                disabled={updateTagsMutation.isPending}
+              />
+            )}
+          </div>
+        )}
+
         <div className="xl:max-w-[60%]">
           <div className="flex flex-wrap gap-2 xl:justify-end">
             <PluginSlot
@@ -660,6 +861,20 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
               </AlertDialog>
             )}
 
+            {/* 沙箱付款方式：模拟付款成功 */}
+            {canMarkPaid && order.is_sandbox && (
+              <Button
+                variant="outline"
+                disabled={simulatePaymentMutation.isPending}
+                onClick={() => simulatePaymentMutation.mutate()}
+              >
+                <CreditCard className="mr-2 h-4 w-4" />
+                {simulatePaymentMutation.isPending
+                  ? t.admin.processing
+                  : t.order.simulateSandboxPayment}
+              </Button>
+            )}
+
             {/* 修改订单价格 */}
             {canUpdatePrice && (
               <Dialog open={openUpdatePrice} onOpenChange={setOpenUpdatePrice}>
@@ -1025,6 +1240,12 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
                 </DialogContent>
               </Dialog>
             )}
+            {hasPhysicalItems && order.receiver_country && (
+              <Button variant="outline" onClick={handleOpenCustomsDeclaration}>
+                <FileText className="mr-2 h-4 w-4" />
+                {t.admin.customsDeclaration}
+              </Button>
+            )}
             {secondaryActionCount > 0 ? (
               <DropdownMenu>
                 <DropdownMenuTrigger asChild>
@@ -1034,6 +1255,15 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
                   </Button>
                 </DropdownMenuTrigger>
                 <DropdownMenuContent align="end" className="w-52">
+                  {canMergeOrders && (
+                    <DropdownMenuItem
+                      className="cursor-pointer gap-2"
+                      onSelect={() => setOpenMerge(true)}
+                    >
+                      <GitMerge className="h-4 w-4" />
+                      {t.order.mergeOrders}
+                    </DropdownMenuItem>
+                  )}
                   {canCancel && (
                     <DropdownMenuItem
                       className="cursor-pointer gap-2"
@@ -1061,7 +1291,21 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
                       {t.order.confirmRefundPending}
                     </DropdownMenuItem>
                   )}
-                  {canDelete && (canCancel || canRefund || canConfirmRefund) ? (
+                  {canReceiveReturn && (
+                    <DropdownMenuItem
+                      className="cursor-pointer gap-2"
+                      onSelect={() => setOpenReturn(true)}
+                    >
+                      <RotateCcw className="h-4 w-4" />
+                      {t.order.recordReturn}
+                    </DropdownMenuItem>
+                  )}
+                  {canDelete &&
+                  (canMergeOrders ||
+                    canCancel ||
+                    canRefund ||
+                    canConfirmRefund ||
+                    canReceiveReturn) ? (
                     <DropdownMenuSeparator />
                   ) : null}
                   {canDelete && (
@@ -1189,6 +1433,19 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
             </Dialog>
           )}
 
+          {canMergeOrders && (
+            <OrderMergeDialog orderId={orderId} open={openMerge} onOpenChange={setOpenMerge} />
+          )}
+
+          {canReceiveReturn && (
+            <OrderReturnDialog
+              orderId={orderId}
+              order={order}
+              open={openReturn}
+              onOpenChange={setOpenReturn}
+            />
+          )}
+
           {canDelete && (
             <AlertDialog open={openDelete} onOpenChange={setOpenDelete}>
               <AlertDialogContent className="max-w-lg">
@@ -1235,15 +1492,98 @@ export default function AdminOrderDetailPage({ params }: { params: Promise<{ id:
         virtualStocks={virtualStocks}
         isVirtualOnly={isVirtualOnly}
         paymentCard={paymentCard}
+        packagePlanCard={isVirtualOnly ? undefined : <OrderPackagePlanCard orderId={orderId} />}
+        trackingCard={
+          isVirtualOnly ? undefined : (
+            <OrderTrackingCard
+              orderId={orderId}
+              trackingNo={order.tracking_no || order.trackingNo}
+              canSync={hasPermission('order.assign_tracking')}
+            />
+          )
+        }
+        refundsCard={
+          <OrderRefundsCard
+            orderId={orderId}
+            order={order}
+            canRefund={canRefund}
+            canManage={hasPermission('order.refund')}
+          />
+        }
+        notesCard={
+          <OrderNotesCard
+            orderId={orderId}
+            notes={Array.isArray(data?.data?.notes) ? data.data.notes : []}
+            canEdit={hasPermission('order.edit')}
+          />
+        }
+        messagesCard={
+          order.user_id || order.userId ? (
+            <OrderMessagesCard
+              mode="admin"
+              orderNo={orderNumber || ''}
+              orderId={orderId}
+              canReply={hasPermission('order.edit')}
+            />
+          ) : undefined
+        }
         shippingFormURL={orderFormURL || undefined}
         shippingFormToken={orderFormToken || undefined}
         shippingFormExpiresAt={orderFormExpiresAt || undefined}
         showVirtualStockRemark
         showOperationalMeta
+        revealVirtualStockContent={hasPermission('product.view') ? revealStockContent : undefined}
+        renderVirtualStockActions={
+          hasPermission('order.status_update')
+            ? (stock) => (
+                <Button variant="outline" size="sm" onClick={() => setRedeliverStock(stock)}>
+                  <RotateCcw className="mr-1 h-4 w-4" />
+                  {t.order.redeliverVirtualStock}
+                </Button>
+              )
+            : undefined
+        }
         pluginSlotNamespace="admin.order_detail"
         pluginSlotContext={adminOrderDetailPluginContext}
         pluginSlotPath={`/admin/orders/${orderId}`}
       />
+      <AlertDialog
+        open={!!redeliverStock}
+        onOpenChange={(open) => {
+          if (!open) {
+            setRedeliverStock(null)
+            setRedeliverReason('')
+          }
+        }}
+      >
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>{t.order.redeliverVirtualStockTitle}</AlertDialogTitle>
+            <AlertDialogDescription>{t.order.redeliverVirtualStockDesc}</AlertDialogDescription>
+          </AlertDialogHeader>
+          <div className="space-y-2">
+            <Label htmlFor="redeliver_reason">{t.order.redeliverVirtualStockReason}</Label>
+            <Input
+              id="redeliver_reason"
+              value={redeliverReason}
+              maxLength={500}
+              onChange={(e) => setRedeliverReason(e.target.value)}
+            />
+          </div>
+          <AlertDialogFooter>
+            <AlertDialogCancel>{t.order.cancel}</AlertDialogCancel>
+            <AlertDialogAction
+              onClick={(e) => {
+                e.preventDefault()
+                if (redeliverStock) redeliverMutation.mutate(redeliverStock)
+              }}
+              disabled={redeliverMutation.isPending}
+            >
+              {t.order.redeliverVirtualStock}
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
       <PluginSlot slot="admin.order_detail.bottom" context={adminOrderDetailPluginContext} />
     </div>
   )
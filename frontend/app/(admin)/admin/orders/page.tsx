@@ -7,6 +7,8 @@ import { getAdminOrders, batchUpdateOrders } from '@/lib/api'
 import { resolveApiErrorMessage } from '@/lib/api-error'
 import { resolveClientAPIProxyURL } from '@/lib/api-base-url'
 import { DataTable } from '@/components/admin/data-table'
+import { OrderImportDialog } from '@/components/admin/order-import-dialog'
+import { OrderCodeLookupDialog } from '@/components/admin/order-code-lookup-dialog'
 import { OrderStatusBadge } from '@/components/orders/order-status-badge'
 import { OrderFilter } from '@/components/orders/order-filter'
 import { Button } from '@/components/ui/button'
@@ -41,6 +43,7 @@ import {
   Trash2,
   ChevronDown,
   X,
+  FileText,
 } from 'lucide-react'
 import Link from 'next/link'
 import { getToken } from '@/lib/auth'
@@ -114,6 +117,7 @@ function AdminOrdersContent() {
   const [status, setStatus] = useState<string | undefined>()
   const [search, setSearch] = useState('')
   const [productSearch, setProductSearch] = useState('')
+  const [tag, setTag] = useState('')
   const [promoCode, setPromoCode] = useState('')
   const [promoCodeId, setPromoCodeId] = useState<number | undefined>()
   const [userId, setUserId] = useState<number | undefined>()
@@ -157,6 +161,14 @@ function AdminOrdersContent() {
     if (searchParam) {
       setSearch(searchParam)
     }
+    const statusParam = searchParams.get('status')
+    if (statusParam) {
+      setStatus(statusParam)
+    }
+    const tagParam = searchParams.get('tag')
+    if (tagParam) {
+      setTag(tagParam)
+    }
     const promoCodeParam = searchParams.get('promo_code')
     if (promoCodeParam) {
       setPromoCode(promoCodeParam)
@@ -175,6 +187,7 @@ function AdminOrdersContent() {
       status,
       search,
       productSearch,
+      tag,
       promoCodeId,
       promoCode,
       userId,
@@ -187,6 +200,7 @@ function AdminOrdersContent() {
         status: status === 'all' ? undefined : status,
         search: search || undefined,
         product_search: productSearch || undefined,
+        tag: tag.trim() || undefined,
         promo_code_id: promoCodeId,
         promo_code: promoCode || undefined,
         user_id: userId,
@@ -194,16 +208,17 @@ function AdminOrdersContent() {
       }),
   })
 
-  const handleExport = () => {
+  const downloadOrderExport = (endpoint: string, fileName: string) => {
     const params = new URLSearchParams()
     if (status && status !== 'all') params.append('status', status)
     if (search) params.append('search', search)
     if (productSearch) params.append('product_search', productSearch)
+    if (tag.trim()) params.append('tag', tag.trim())
     if (country && country !== 'all') params.append('country', country)
     if (promoCodeId) params.append('promo_code_id', String(promoCodeId))
     if (promoCode) params.append('promo_code', promoCode)
 
-    const url = resolveClientAPIProxyURL(`/api/admin/orders/export?${params.toString()}`)
+    const url = resolveClientAPIProxyURL(`${endpoint}?${params.toString()}`)
 
     fetch(url)
       .then(async (res) => {
@@ -216,7 +231,7 @@ function AdminOrdersContent() {
         const url = window.URL.createObjectURL(blob)
         const a = document.createElement('a')
         a.href = url
-        a.download = `orders_${new Date().toISOString().slice(0, 10)}.xlsx`
+        a.download = fileName
         document.body.appendChild(a)
         a.click()
         document.body.removeChild(a)
@@ -229,6 +244,19 @@ function AdminOrdersContent() {
       })
   }
 
+  const handleExport = () =>
+    downloadOrderExport(
+      '/api/admin/orders/export',
+      `orders_${new Date().toISOString().slice(0, 10)}.xlsx`
+    )
+
+  // 仅导出国际件（收件国家与发件国家不同）的报关明细
+  const handleExportCustoms = () =>
+    downloadOrderExport(
+      '/api/admin/orders/customs-declarations/export',
+      `customs_declarations_${new Date().toISOString().slice(0, 10)}.csv`
+    )
+
   const handleDownloadTemplate = () => {
     const url = resolveClientAPIProxyURL('/api/admin/orders/import-template')
 
@@ -377,6 +405,7 @@ function AdminOrdersContent() {
     status: status === 'all' ? undefined : status,
     search: search || undefined,
     product_search: productSearch || undefined,
+    tag: tag.trim() || undefined,
     promo_code: promoCode || undefined,
     promo_code_id: promoCodeId,
     user_id: userId,
@@ -473,7 +502,19 @@ function AdminOrdersContent() {
       header: t.admin.orderNo,
       accessorKey: 'orderNo',
       cell: ({ row }: { row: { original: any } }) => (
-        <span className="font-mono text-sm">{row.original.orderNo || row.original.order_no}</span>
+        <div className="flex flex-wrap items-center gap-1.5">
+          <span className="font-mono text-sm">{row.original.orderNo || row.original.order_no}</span>
+          {row.original.is_sandbox && (
+            <Badge variant="outline" className="border-amber-400 text-[10px] text-amber-700">
+              {t.order.sandboxOrder}
+            </Badge>
+          )}
+          {(row.original.tags || []).map((orderTag: string) => (
+            <Badge key={orderTag} variant="outline" className="text-[10px]">
+              #{orderTag}
+            </Badge>
+          ))}
+        </div>
       ),
     },
     {
@@ -607,10 +648,16 @@ function AdminOrdersContent() {
             onChange={handleFileChange}
             style={{ display: 'none' }}
           />
+          <OrderImportDialog onImported={() => refetch()} />
+          <OrderCodeLookupDialog />
           <Button variant="outline" size="sm" onClick={handleExport}>
             <Download className="mr-2 h-4 w-4" />
             {t.admin.exportOrders}
           </Button>
+          <Button variant="outline" size="sm" onClick={handleExportCustoms}>
+            <FileText className="mr-2 h-4 w-4" />
+            {t.admin.exportCustomsDeclarations}
+          </Button>
           <Button variant="outline" size="sm" onClick={() => refetch()}>
             <RefreshCw className="mr-2 h-4 w-4" />
             {t.admin.refresh}
@@ -627,6 +674,7 @@ function AdminOrdersContent() {
         status={status}
         search={search}
         productSearch={productSearch}
+        tag={tag}
         userId={userId}
         country={country}
         useSmartCountryFilter={true}
@@ -645,6 +693,11 @@ function AdminOrdersContent() {
           setPage(1)
           setSelectedIds(new Set())
         }}
+        onTagChange={(newTag) => {
+          setTag(newTag)
+          setPage(1)
+          setSelectedIds(new Set())
+        }}
         onUserChange={(newUserId) => {
           setUserId(newUserId)
           setPage(1)
@@ -0,0 +1,561 @@
+'use client'
+
+import { useRef, useState } from 'react'
+import { useMutation, useQuery } from '@tanstack/react-query'
+import { FileDown, Plus, RefreshCw, Upload } from 'lucide-react'
+import toast from 'react-hot-toast'
+import {
+  createIncomingTransaction,
+  getIncomingTransactions,
+  getPaymentMatchPendingOrders,
+  getPaymentMatchSuggestions,
+  ignoreIncomingTransaction,
+  importIncomingTransactions,
+  matchIncomingTransaction,
+  type IncomingTransaction,
+  type PaymentMatchOrder,
+  type PaymentMatchSuggestion,
+} from '@/lib/api'
+import { DataTable } from '@/components/admin/data-table'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Textarea } from '@/components/ui/textarea'
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select'
+import {
+  Dialog,
+  DialogContent,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle,
+} from '@/components/ui/alert-dialog'
+import { useDebounce } from '@/hooks/use-debounce'
+import { useLocale } from '@/hooks/use-locale'
+import { usePageTitle } from '@/hooks/use-page-title'
+import { usePermission } from '@/hooks/use-permission'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { resolveClientAPIProxyURL } from '@/lib/api-base-url'
+import { formatCurrency, formatDate } from '@/lib/utils'
+
+const emptyTransactionForm = {
+  transaction_id: '',
+  channel: '',
+  amount: '',
+  currency: '',
+  memo: '',
+  payer: '',
+}
+
+export default function AdminPaymentMatchingPage() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  usePageTitle(t.pageTitle.adminPaymentMatching)
+  const { hasPermission } = usePermission()
+  const canMatch = hasPermission('order.status_update')
+
+  const [page, setPage] = useState(1)
+  const [status, setStatus] = useState('unmatched')
+  const [selected, setSelected] = useState<IncomingTransaction | null>(null)
+  const [orderSearch, setOrderSearch] = useState('')
+  const debouncedOrderSearch = useDebounce(orderSearch)
+  const [matchTarget, setMatchTarget] = useState<PaymentMatchOrder | null>(null)
+  const [remark, setRemark] = useState('')
+  const [createOpen, setCreateOpen] = useState(false)
+  const [form, setForm] = useState(emptyTransactionForm)
+  const fileInputRef = useRef<HTMLInputElement>(null)
+
+  const statusConfig: Record<string, { label: string; color: string }> = {
+    unmatched: {
+      label: t.admin.paymentMatchStatusUnmatched,
+      color: 'bg-yellow-500/20 text-yellow-700 dark:text-yellow-400',
+    },
+    matched: {
+      label: t.admin.paymentMatchStatusMatched,
+      color: 'bg-green-500/20 text-green-700 dark:text-green-400',
+    },
+    ignored: {
+      label: t.admin.paymentMatchStatusIgnored,
+      color: 'bg-gray-500/20 text-gray-700 dark:text-gray-400',
+    },
+  }
+  const reasonLabels: Record<string, string> = {
+    reserved_amount: t.admin.paymentMatchReasonReservedAmount,
+    exact_amount: t.admin.paymentMatchReasonExactAmount,
+    close_amount: t.admin.paymentMatchReasonCloseAmount,
+    near_amount: t.admin.paymentMatchReasonNearAmount,
+    memo_order_no: t.admin.paymentMatchReasonMemoOrderNo,
+    memo_partial_order_no: t.admin.paymentMatchReasonMemoPartialOrderNo,
+    payment_method: t.admin.paymentMatchReasonPaymentMethod,
+  }
+
+  const { data, isLoading, refetch } = useQuery({
+    queryKey: ['adminIncomingTransactions', page, status],
+    queryFn: () =>
+      getIncomingTransactions({
+        page,
+        limit: 20,
+        status: status === 'all' ? undefined : status,
+      }),
+  })
+  const transactions: IncomingTransaction[] = data?.data?.items || []
+
+  const selectedUnmatched = selected?.status === 'unmatched'
+  const { data: suggestionsData, isLoading: suggestionsLoading } = useQuery({
+    queryKey: ['adminPaymentMatchSuggestions', selected?.id],
+    queryFn: () => getPaymentMatchSuggestions(selected!.id),
+    enabled: !!selected && selectedUnmatched,
+  })
+  const suggestions: PaymentMatchSuggestion[] = suggestionsData?.data || []
+
+  const { data: pendingData } = useQuery({
+    queryKey: ['adminPaymentMatchPendingOrders', debouncedOrderSearch],
+    queryFn: () =>
+      getPaymentMatchPendingOrders({
+        page: 1,
+        limit: 10,
+        search: debouncedOrderSearch || undefined,
+      }),
+    enabled: !!selected && selectedUnmatched,
+  })
+  const pendingOrders: PaymentMatchOrder[] = pendingData?.data?.items || []
+
+  const handleHandled = (txn: IncomingTransaction) => {
+    setSelected(txn)
+    setMatchTarget(null)
+    setRemark('')
+    refetch()
+  }
+
+  const matchMutation = useMutation({
+    mutationFn: () => matchIncomingTransaction(selected!.id, matchTarget!.id, remark || undefined),
+    onSuccess: (response: any) => {
+      toast.success(t.admin.paymentMatchSuccess)
+      handleHandled(response.data)
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.paymentMatchFailed))
+    },
+  })
+
+  const ignoreMutation = useMutation({
+    mutationFn: (id: number) => ignoreIncomingTransaction(id),
+    onSuccess: (response: any) => {
+      toast.success(t.admin.paymentMatchIgnoreSuccess)
+      handleHandled(response.data)
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.operationFailed))
+    },
+  })
+
+  const createMutation = useMutation({
+    mutationFn: () => createIncomingTransaction(form),
+    onSuccess: (response: any) => {
+      toast.success(t.admin.paymentMatchCreateSuccess)
+      setCreateOpen(false)
+      setForm(emptyTransactionForm)
+      setSelected(response.data)
+      refetch()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.operationFailed))
+    },
+  })
+
+  const importMutation = useMutation({
+    mutationFn: (file: File) => importIncomingTransactions(file),
+    onSuccess: (response: any) => {
+      const result = response.data
+      const message = t.admin.paymentMatchImportResult
+        .replace('{created}', String(result.created))
+        .replace('{duplicates}', String(result.duplicates))
+        .replace('{errors}', String(result.errors?.length || 0))
+      if (result.errors?.length > 0) {
+        toast.error(message, { duration: 6000 })
+        console.error('Incoming transaction import errors:', result.errors)
+      } else {
+        toast.success(message)
+      }
+      refetch()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.importFailed))
+    },
+  })
+
+  const handleFileChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0]
+    if (fileInputRef.current) {
+      fileInputRef.current.value = ''
+    }
+    if (!file) return
+    if (!/\.(csv|xlsx)$/i.test(file.name)) {
+      toast.error(t.admin.orderBulkImportFileFormatError)
+      return
+    }
+    importMutation.mutate(file)
+  }
+
+  const handleDownloadTemplate = () => {
+    fetch(resolveClientAPIProxyURL('/api/admin/payment-matching/transactions/import-template'))
+      .then((res) => {
+        if (!res.ok) throw new Error(t.admin.downloadFailed)
+        return res.blob()
+      })
+      .then((blob) => {
+        const url = window.URL.createObjectURL(blob)
+        const a = document.createElement('a')
+        a.href = url
+        a.download = 'incoming_transactions_template.xlsx'
+        document.body.appendChild(a)
+        a.click()
+        document.body.removeChild(a)
+        window.URL.revokeObjectURL(url)
+      })
+      .catch((err) => toast.error(err.message))
+  }
+
+  const columns = [
+    {
+      header: t.admin.paymentMatchTransactionId,
+      cell: ({ row }: { row: { original: IncomingTransaction } }) => (
+        <div className="max-w-[180px] space-y-0.5">
+          <div className="truncate font-mono text-xs" title={row.original.transaction_id}>
+            {row.original.transaction_id}
+          </div>
+          {row.original.channel ? (
+            <div className="text-xs text-muted-foreground">{row.original.channel}</div>
+          ) : null}
+        </div>
+      ),
+    },
+    {
+      header: t.admin.paymentMatchAmount,
+      cell: ({ row }: { row: { original: IncomingTransaction } }) => (
+        <span className="font-medium tabular-nums">
+          {row.original.amount} {row.original.currency}
+        </span>
+      ),
+    },
+    {
+      header: t.admin.paymentMatchMemo,
+      cell: ({ row }: { row: { original: IncomingTransaction } }) => (
+        <div className="max-w-[160px] truncate text-xs" title={row.original.memo}>
+          {row.original.memo || '-'}
+        </div>
+      ),
+    },
+    {
+      header: t.admin.paymentMatchReceivedAt,
+      cell: ({ row }: { row: { original: IncomingTransaction } }) => (
+        <span className="text-xs">{formatDate(row.original.received_at)}</span>
+      ),
+    },
+    {
+      header: t.admin.status,
+      cell: ({ row }: { row: { original: IncomingTransaction } }) => {
+        const config = statusConfig[row.original.status] || statusConfig.unmatched
+        return <Badge className={config.color}>{config.label}</Badge>
+      },
+    },
+    {
+      header: t.admin.actions,
+      cell: ({ row }: { row: { original: IncomingTransaction } }) => (
+        <Button
+          size="sm"
+          variant={selected?.id === row.original.id ? 'default' : 'outline'}
+          onClick={() => setSelected(row.original)}
+        >
+          {t.common.view}
+        </Button>
+      ),
+    },
+  ]
+
+  const renderOrderRow = (order: PaymentMatchOrder, suggestion?: PaymentMatchSuggestion) => (
+    <div
+      key={order.id}
+      className={
+        'flex flex-col gap-2 rounded-md border p-3 md:flex-row md:items-center md:justify-between'
+      }
+    >
+      <div className="space-y-1">
+        <div className="flex flex-wrap items-center gap-2">
+          <span className="font-mono text-sm font-medium">{order.order_no}</span>
+          <span className="text-sm tabular-nums">
+            {formatCurrency(order.total_amount_minor, order.currency)}
+          </span>
+          {suggestion ? (
+            <Badge variant="secondary">
+              {t.admin.paymentMatchScore.replace('{score}', String(suggestion.score))}
+            </Badge>
+          ) : null}
+        </div>
+        <div className="flex flex-wrap gap-x-3 gap-y-1 text-xs text-muted-foreground">
+          <span>{formatDate(order.created_at)}</span>
+          {order.reserved_amount ? (
+            <span>
+              {t.admin.paymentMatchReservedAmount}: {order.reserved_amount}
+            </span>
+          ) : null}
+          {suggestion?.reasons.map((reason) => (
+            <span key={reason}>{reasonLabels[reason] || reason}</span>
+          ))}
+        </div>
+      </div>
+      {canMatch ? (
+        <Button size="sm" onClick={() => setMatchTarget(order)}>
+          {t.admin.paymentMatchMatch}
+        </Button>
+      ) : null}
+    </div>
+  )
+
+  return (
+    <div className="space-y-6">
+      <div className="flex flex-col gap-4 md:flex-row md:items-start md:justify-between">
+        <div>
+          <h1 className="text-3xl font-bold">{t.admin.paymentMatching}</h1>
+          <p className="mt-1 text-sm text-muted-foreground">{t.admin.paymentMatchingDesc}</p>
+        </div>
+        <div className="flex flex-wrap gap-2">
+          {canMatch ? (
+            <>
+              <Button onClick={() => setCreateOpen(true)}>
+                <Plus className="mr-2 h-4 w-4" />
+                {t.admin.paymentMatchAddTransaction}
+              </Button>
+              <input
+                ref={fileInputRef}
+                type="file"
+                accept=".csv,.xlsx"
+                className="hidden"
+                onChange={handleFileChange}
+              />
+              <Button
+                variant="outline"
+                onClick={() => fileInputRef.current?.click()}
+                disabled={importMutation.isPending}
+              >
+                <Upload className="mr-2 h-4 w-4" />
+                {t.admin.paymentMatchImport}
+              </Button>
+            </>
+          ) : null}
+          <Button variant="outline" onClick={handleDownloadTemplate}>
+            <FileDown className="mr-2 h-4 w-4" />
+            {t.admin.downloadTemplate}
+          </Button>
+          <Button variant="outline" onClick={() => refetch()}>
+            <RefreshCw className="mr-2 h-4 w-4" />
+            {t.admin.refresh}
+          </Button>
+        </div>
+      </div>
+
+      <div className="grid gap-6 xl:grid-cols-2">
+        <Card>
+          <CardHeader className="flex flex-row items-center justify-between space-y-0">
+            <CardTitle>{t.admin.paymentMatchTransactions}</CardTitle>
+            <Select
+              value={status}
+              onValueChange={(value) => {
+                setStatus(value)
+                setPage(1)
+              }}
+            >
+              <SelectTrigger className="w-[140px]">
+                <SelectValue />
+              </SelectTrigger>
+              <SelectContent>
+                <SelectItem value="unmatched">{t.admin.paymentMatchStatusUnmatched}</SelectItem>
+                <SelectItem value="matched">{t.admin.paymentMatchStatusMatched}</SelectItem>
+                <SelectItem value="ignored">{t.admin.paymentMatchStatusIgnored}</SelectItem>
+                <SelectItem value="all">{t.admin.paymentMatchStatusAll}</SelectItem>
+              </SelectContent>
+            </Select>
+          </CardHeader>
+          <CardContent>
+            <DataTable
+              columns={columns}
+              data={transactions}
+              isLoading={isLoading}
+              pagination={{
+                page,
+                total_pages: data?.data?.pagination?.total_pages || 1,
+                onPageChange: setPage,
+              }}
+            />
+          </CardContent>
+        </Card>
+
+        <Card>
+          <CardHeader>
+            <CardTitle>{t.admin.paymentMatchSuggestions}</CardTitle>
+            {selected ? (
+              <CardDescription className="break-all">
+                {selected.transaction_id} · {selected.amount} {selected.currency}
+                {selected.payer ? ` · ${selected.payer}` : ''}
+              </CardDescription>
+            ) : null}
+          </CardHeader>
+          <CardContent className="space-y-4">
+            {!selected ? (
+              <p className="text-sm text-muted-foreground">{t.admin.paymentMatchSelectHint}</p>
+            ) : !selectedUnmatched ? (
+              <div className="space-y-2 text-sm">
+                <Badge className={statusConfig[selected.status]?.color}>
+                  {statusConfig[selected.status]?.label}
+                </Badge>
+                {selected.matched_order_no ? (
+                  <p>
+                    {t.admin.paymentMatchMatchedOrder.replace(
+                      '{orderNo}',
+                      selected.matched_order_no
+                    )}
+                  </p>
+                ) : null}
+                {selected.remark ? (
+                  <p className="text-muted-foreground">{selected.remark}</p>
+                ) : null}
+              </div>
+            ) : (
+              <>
+                {selected.memo ? (
+                  <p className="text-sm">
+                    {t.admin.paymentMatchMemo}: {selected.memo}
+                  </p>
+                ) : null}
+                {suggestionsLoading ? (
+                  <p className="text-sm text-muted-foreground">{t.common.loading}</p>
+                ) : suggestions.length > 0 ? (
+                  <div className="space-y-2">
+                    {suggestions.map((suggestion) => renderOrderRow(suggestion.order, suggestion))}
+                  </div>
+                ) : (
+                  <p className="text-sm text-muted-foreground">
+                    {t.admin.paymentMatchNoSuggestions}
+                  </p>
+                )}
+
+                <div className="space-y-2 border-t pt-4">
+                  <Label>{t.admin.paymentMatchPendingOrders}</Label>
+                  <Input
+                    value={orderSearch}
+                    onChange={(e) => setOrderSearch(e.target.value)}
+                    placeholder={t.admin.paymentMatchSearchOrder}
+                  />
+                  <div className="space-y-2">
+                    {pendingOrders.map((order) => renderOrderRow(order))}
+                  </div>
+                </div>
+
+                {canMatch ? (
+                  <div className="flex justify-end">
+                    <Button
+                      variant="outline"
+                      onClick={() => ignoreMutation.mutate(selected.id)}
+                      disabled={ignoreMutation.isPending}
+                    >
+                      {t.admin.paymentMatchIgnore}
+                    </Button>
+                  </div>
+                ) : null}
+              </>
+            )}
+          </CardContent>
+        </Card>
+      </div>
+
+      <AlertDialog open={!!matchTarget} onOpenChange={(open) => !open && setMatchTarget(null)}>
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>{t.admin.paymentMatchConfirmTitle}</AlertDialogTitle>
+            <AlertDialogDescription>
+              {t.admin.paymentMatchConfirm
+                .replace('{orderNo}', matchTarget?.order_no || '')
+                .replace('{transactionId}', selected?.transaction_id || '')}
+            </AlertDialogDescription>
+          </AlertDialogHeader>
+          <div className="space-y-2">
+            <Label htmlFor="payment-match-remark">{t.admin.paymentMatchRemark}</Label>
+            <Textarea
+              id="payment-match-remark"
+              value={remark}
+              onChange={(e) => setRemark(e.target.value)}
+              rows={3}
+            />
+          </div>
+          <AlertDialogFooter>
+            <AlertDialogCancel>{t.common.cancel}</AlertDialogCancel>
+            <AlertDialogAction
+              onClick={() => matchMutation.mutate()}
+              disabled={matchMutation.isPending}
+            >
+              {t.admin.paymentMatchMatch}
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+
+      <Dialog open={createOpen} onOpenChange={setCreateOpen}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{t.admin.paymentMatchAddTransaction}</DialogTitle>
+          </DialogHeader>
+          <div className="grid gap-4 md:grid-cols-2">
+            {(
+              [
+                ['transaction_id', t.admin.paymentMatchTransactionId],
+                ['amount', t.admin.paymentMatchAmount],
+                ['currency', t.admin.paymentMatchCurrency],
+                ['channel', t.admin.paymentMatchChannel],
+                ['payer', t.admin.paymentMatchPayer],
+                ['memo', t.admin.paymentMatchMemo],
+              ] as const
+            ).map(([field, label]) => (
+              <div key={field} className="space-y-2">
+                <Label htmlFor={`incoming-${field}`}>{label}</Label>
+                <Input
+                  id={`incoming-${field}`}
+                  value={form[field]}
+                  onChange={(e) => setForm({ ...form, [field]: e.target.value })}
+                />
+              </div>
+            ))}
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setCreateOpen(false)}>
+              {t.common.cancel}
+            </Button>
+            <Button
+              onClick={() => createMutation.mutate()}
+              disabled={createMutation.isPending || !form.transaction_id || !form.amount}
+            >
+              {t.common.save}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </div>
+  )
+}
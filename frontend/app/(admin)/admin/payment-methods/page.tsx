@@ -7,6 +7,8 @@ import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
 import {
   AdminPaymentMethodMarketPreviewRequest,
   getPaymentMethods,
+  getPaymentMethodBreakers,
+  resetPaymentMethodBreakers,
   createPaymentMethod,
   updatePaymentMethod,
   deletePaymentMethod,
@@ -15,8 +17,10 @@ import {
   testPaymentScript,
   initBuiltinPaymentMethods,
   PaymentMethod,
+  PaymentMethodBreaker,
   PaymentMethodMarketPreview,
   PaymentMethodPackagePreview,
+  PaymentScriptLogEntry,
   previewPaymentMethodMarketPackage,
   previewPaymentMethodPackage,
   importPaymentMethodPackageFromMarket,
@@ -72,6 +76,7 @@ import {
   FileUp,
   Loader2,
   Package,
+  ShieldAlert,
 } from 'lucide-react'
 import toast from 'react-hot-toast'
 import { useLocale } from '@/hooks/use-locale'
@@ -170,6 +175,14 @@ function normalizeUniqueTextList(value: unknown): string[] {
   return items
 }
 
+// 付款方式的订单标签规则输入为逗号分隔
+function splitOrderTagList(value: string): string[] {
+  return value
+    .split(/[\r\n,]+/)
+    .map((item) => item.trim().toLowerCase())
+    .filter((item, index, source) => !!item && source.indexOf(item) === index)
+}
+
 function resolvePaymentPackageImportModeLabel(
   locale: string,
   options: { installed?: boolean; updateAvailable?: boolean }
@@ -255,6 +268,7 @@ export default function PaymentMethodsPage() {
   const [isImportOpen, setIsImportOpen] = useState(false)
   const [deleteId, setDeleteId] = useState<number | null>(null)
   const [testResult, setTestResult] = useState<string | null>(null)
+  const [testLogs, setTestLogs] = useState<PaymentScriptLogEntry[]>([])
   const configFlushRef = useRef<(() => string | null) | null>(null)
   const packageFileInputRef = useRef<HTMLInputElement | null>(null)
   const [dragIndex, setDragIndex] = useState<number | null>(null)
@@ -302,6 +316,11 @@ export default function PaymentMethodsPage() {
     script: '',
     config: '{}',
     poll_interval: 30,
+    auto_cancel_hours: 0,
+    allowed_hosts: '',
+    required_order_tags: '',
+    excluded_order_tags: '',
+    sandbox: false,
   })
   const webhookExampleHook = 'payment.notify'
   const webhookExampleURL = editingMethod
@@ -329,6 +348,20 @@ export default function PaymentMethodsPage() {
   })
 
   const methods = data?.data?.items || []
+  const { data: breakersData } = useQuery({
+    queryKey: ['adminPaymentMethodBreakers'],
+    queryFn: () => getPaymentMethodBreakers(),
+    refetchInterval: 15000,
+  })
+  const breakersByMethod = useMemo(() => {
+    const grouped: Record<number, PaymentMethodBreaker[]> = {}
+    for (const breaker of (breakersData?.data?.items || []) as PaymentMethodBreaker[]) {
+      if (breaker.state === 'closed') continue
+      const id = breaker.payment_method_id
+      grouped[id] = [...(grouped[id] || []), breaker]
+    }
+    return grouped
+  }, [breakersData])
   const deleteMethod =
     deleteId !== null
       ? methods.find((method: PaymentMethod) => method.id === deleteId) || null
@@ -460,6 +493,17 @@ export default function PaymentMethodsPage() {
     },
   })
 
+  const resetBreakersMutation = useMutation({
+    mutationFn: resetPaymentMethodBreakers,
+    onSuccess: () => {
+      toast.success(t.admin.pmBreakerResetSuccess)
+      queryClient.invalidateQueries({ queryKey: ['adminPaymentMethodBreakers'] })
+    },
+    onError: (error: any) => {
+      showAdminErrorToast(error, t.admin.operationFailed)
+    },
+  })
+
   const initMutation = useMutation({
     mutationFn: initBuiltinPaymentMethods,
     onSuccess: () => {
@@ -486,10 +530,15 @@ export default function PaymentMethodsPage() {
     mutationFn: ({ script, config }: { script: string; config: Record<string, any> }) =>
       testPaymentScript(script, config),
     onSuccess: (data: any) => {
-      setTestResult(data?.data?.html || JSON.stringify(data?.data, null, 2))
+      const { logs, ...card } = data?.data || {}
+      setTestResult(card.html || JSON.stringify(card, null, 2))
+      setTestLogs(Array.isArray(logs) ? logs : [])
     },
-    onError: (error: unknown) => {
-      setTestResult(resolveAdminError(error, t.admin.operationFailed))
+    onError: (error: any) => {
+      const detail = error?.data?.error
+      const message = resolveAdminError(error, t.admin.operationFailed)
+      setTestResult(detail ? `${message}: ${detail}` : message)
+      setTestLogs(Array.isArray(error?.data?.logs) ? error.data.logs : [])
     },
   })
 
@@ -760,6 +809,11 @@ export default function PaymentMethodsPage() {
       script: '',
       config: '{}',
       poll_interval: 30,
+      auto_cancel_hours: 0,
+      allowed_hosts: '',
+      required_order_tags: '',
+      excluded_order_tags: '',
+      sandbox: false,
     })
   }
 
@@ -773,6 +827,11 @@ export default function PaymentMethodsPage() {
       script: method.script || '',
       config: method.config || '{}',
       poll_interval: method.poll_interval || 30,
+      auto_cancel_hours: method.auto_cancel_hours || 0,
+      allowed_hosts: (method.allowed_hosts || []).join('\n'),
+      required_order_tags: (method.required_order_tags || []).join(', '),
+      excluded_order_tags: (method.excluded_order_tags || []).join(', '),
+      sandbox: !!method.sandbox,
     })
   }
 
@@ -817,6 +876,14 @@ export default function PaymentMethodsPage() {
       script: formData.script,
       config: latestConfig,
       poll_interval: formData.poll_interval,
+      auto_cancel_hours: formData.auto_cancel_hours,
+      allowed_hosts: formData.allowed_hosts
+        .split(/[\r\n,]+/)
+        .map((host) => host.trim())
+        .filter(Boolean),
+      required_order_tags: splitOrderTagList(formData.required_order_tags),
+      excluded_order_tags: splitOrderTagList(formData.excluded_order_tags),
+      sandbox: formData.sandbox,
     }
 
     if (editingMethod) {
@@ -970,6 +1037,7 @@ export default function PaymentMethodsPage() {
         ) : (
           methods.map((method: PaymentMethod, index: number) => {
             const rowExtensions = adminPaymentMethodActionExtensions[String(method.id)] || []
+            const openBreakers = breakersByMethod[method.id] || []
             return (
               <Card
                 key={method.id}
@@ -1023,6 +1091,17 @@ export default function PaymentMethodsPage() {
                     <div className="min-w-0 flex-1">
                       <div className="flex flex-wrap items-center gap-2">
                         <h3 className="font-semibold">{method.name}</h3>
+                        {method.sandbox && (
+                          <Badge
+                            variant="outline"
+                            className="border-amber-400 text-amber-700 dark:text-amber-300"
+                          >
+                            {t.admin.pmSandboxBadge}
+                          </Badge>
+                        )}
+                        {method.cash_on_delivery && (
+                          <Badge variant="outline">{t.admin.pmCashOnDeliveryBadge}</Badge>
+                        )}
                       </div>
                       <p className="truncate text-sm text-muted-foreground">{method.description}</p>
                       <p className="mt-1 truncate text-xs text-muted-foreground">
@@ -1059,6 +1138,33 @@ export default function PaymentMethodsPage() {
                       </Button>
                     </div>
                   </div>
+                  {openBreakers.length > 0 ? (
+                    <div className="flex flex-wrap items-center gap-2 rounded-md border border-destructive/40 bg-destructive/5 p-2 text-xs">
+                      <ShieldAlert className="h-4 w-4 text-destructive" />
+                      {openBreakers.map((breaker) => (
+                        <Badge
+                          key={breaker.host}
+                          variant={breaker.state === 'open' ? 'destructive' : 'secondary'}
+                          title={breaker.last_error || undefined}
+                        >
+                          {breaker.host} ·{' '}
+                          {breaker.state === 'open'
+                            ? t.admin.pmBreakerOpen
+                            : t.admin.pmBreakerHalfOpen}{' '}
+                          · {breaker.consecutive_failures}/{breaker.failure_threshold}
+                        </Badge>
+                      ))}
+                      <Button
+                        variant="outline"
+                        size="sm"
+                        className="ml-auto h-7"
+                        disabled={resetBreakersMutation.isPending}
+                        onClick={() => resetBreakersMutation.mutate(method.id)}
+                      >
+                        {t.admin.pmBreakerReset}
+                      </Button>
+                    </div>
+                  ) : null}
                   {rowExtensions.length > 0 ? (
                     <div
                       className="flex justify-end"
@@ -1084,6 +1190,7 @@ export default function PaymentMethodsPage() {
             setIsCreateOpen(false)
             setEditingMethod(null)
             setTestResult(null)
+            setTestLogs([])
           }
         }}
       >
@@ -1167,6 +1274,65 @@ export default function PaymentMethodsPage() {
                 />
                 <p className="text-xs text-muted-foreground">{t.admin.pmPollIntervalHint}</p>
               </div>
+              <div className="space-y-2">
+                <Label>{t.admin.pmAutoCancelHours}</Label>
+                <Input
+                  type="number"
+                  min={0}
+                  max={720}
+                  value={formData.auto_cancel_hours}
+                  onChange={(e) =>
+                    setFormData({ ...formData, auto_cancel_hours: parseInt(e.target.value) || 0 })
+                  }
+                  placeholder="0"
+                />
+                <p className="text-xs text-muted-foreground">{t.admin.pmAutoCancelHoursHint}</p>
+              </div>
+              <div className="flex items-center justify-between rounded-md border p-3">
+                <div className="space-y-1">
+                  <Label htmlFor="pm-sandbox">{t.admin.pmSandbox}</Label>
+                  <p className="text-xs text-muted-foreground">{t.admin.pmSandboxHint}</p>
+                </div>
+                <Switch
+                  id="pm-sandbox"
+                  checked={formData.sandbox}
+                  onCheckedChange={(checked) => setFormData({ ...formData, sandbox: checked })}
+                />
+              </div>
+              <div className="space-y-2">
+                <Label>{t.admin.pmAllowedHosts}</Label>
+                <Textarea
+                  rows={3}
+                  className="font-mono text-sm"
+                  value={formData.allowed_hosts}
+                  onChange={(e) => setFormData({ ...formData, allowed_hosts: e.target.value })}
+                  placeholder={'api.example.com\n*.example-pay.com'}
+                />
+                <p className="text-xs text-muted-foreground">{t.admin.pmAllowedHostsHint}</p>
+              </div>
+              <div className="grid grid-cols-2 gap-4">
+                <div className="space-y-2">
+                  <Label>{t.admin.pmRequiredOrderTags}</Label>
+                  <Input
+                    value={formData.required_order_tags}
+                    onChange={(e) =>
+                      setFormData({ ...formData, required_order_tags: e.target.value })
+                    }
+                    placeholder="wholesale"
+                  />
+                </div>
+                <div className="space-y-2">
+                  <Label>{t.admin.pmExcludedOrderTags}</Label>
+                  <Input
+                    value={formData.excluded_order_tags}
+                    onChange={(e) =>
+                      setFormData({ ...formData, excluded_order_tags: e.target.value })
+                    }
+                    placeholder="high-risk"
+                  />
+                </div>
+              </div>
+              <p className="text-xs text-muted-foreground">{t.admin.pmOrderTagsHint}</p>
               {editingMethod?.package_name ? (
                 <Card className="bg-muted/40">
                   <CardHeader className="pb-3">
@@ -1257,6 +1423,27 @@ export default function PaymentMethodsPage() {
                       className="max-h-64"
                       locale={locale}
                     />
+                    {testLogs.length > 0 && (
+                      <div className="mt-3 space-y-1 text-xs">
+                        <p className="font-semibold">{t.admin.pmTestConsole}</p>
+                        <div className="max-h-48 overflow-auto rounded-md bg-muted p-2 font-mono">
+                          {testLogs.map((entry, index) => (
+                            <div
+                              key={index}
+                              className={
+                                entry.level === 'error'
+                                  ? 'text-red-600 dark:text-red-400'
+                                  : entry.level === 'warn'
+                                    ? 'text-yellow-600 dark:text-yellow-400'
+                                    : 'text-muted-foreground'
+                              }
+                            >
+                              [{entry.level}] {entry.message}
+                            </div>
+                          ))}
+                        </div>
+                      </div>
+                    )}
                   </CardContent>
                 </Card>
               )}
@@ -1346,7 +1533,19 @@ export default function PaymentMethodsPage() {
                       <code>generateId()</code> - {t.admin.pmGenerateUuid}
                     </p>
                     <p>
-                      <code>md5(data)</code> / <code>hmacSHA256(data, secret)</code>
+                      <code>md5(data)</code> / <code>sha1(data, encoding?)</code> /{' '}
+                      <code>sha256(data, encoding?)</code>
+                    </p>
+                    <p>
+                      <code>hmacSha256(data, secret, encoding?)</code>
+                    </p>
+                    <p>
+                      <code>rsaSign(data, privateKey, options?)</code> /{' '}
+                      <code>rsaVerify(data, signature, publicKey, options?)</code> -{' '}
+                      {t.admin.pmRsaSign}
+                    </p>
+                    <p>
+                      <code>randomBytes(length, encoding?)</code> - {t.admin.pmRandomBytes}
                     </p>
                     <p>
                       <code>base64Encode(data)</code> / <code>base64Decode(data)</code>
@@ -1366,8 +1565,19 @@ export default function PaymentMethodsPage() {
                     <p>
                       <code>request(options)</code> - {t.admin.pmGeneralRequest}
                     </p>
+                    <p>
+                      <code>getAsync</code> / <code>postAsync</code> /{' '}
+                      <code>requestAsync</code> - {t.admin.pmHttpAsync}
+                    </p>
                     <p className="text-muted-foreground">{t.admin.pmHttpReturns}</p>
                   </div>
+                  <div>
+                    <p className="mb-1 font-semibold">console</p>
+                    <p>
+                      <code>log</code> / <code>info</code> / <code>warn</code> /{' '}
+                      <code>error</code> / <code>debug</code> - {t.admin.pmConsoleDesc}
+                    </p>
+                  </div>
                   <div>
                     <p className="mb-1 font-semibold">AuraLogic.system</p>
                     <p>
@@ -1441,6 +1651,7 @@ export default function PaymentMethodsPage() {
                 setIsCreateOpen(false)
                 setEditingMethod(null)
                 setTestResult(null)
+                setTestLogs([])
               }}
             >
               {t.common.cancel}
@@ -209,6 +209,7 @@ const DEFAULT_PLUGIN_HOOK_GROUPS: Record<PluginHookGroupKey, string[]> = {
     'order.admin.delete.after',
     'order.auto_cancel.before',
     'order.auto_cancel.after',
+    'order.payment_reminder.after',
   ],
   payment: [
     'payment.method.select.before',
@@ -43,6 +43,8 @@ import {
   ProductVirtualVariantInventory,
   VirtualVariantInventoryBinding,
 } from '@/components/admin/product-virtual-variant-inventory'
+import { DimensionInputs } from '@/components/admin/dimension-inputs'
+import { PriceTierInputs, type PriceTierValue } from '@/components/admin/price-tier-inputs'
 import toast from 'react-hot-toast'
 import {
   ArrowLeft,
@@ -376,6 +378,25 @@ interface ProductForm {
   original_price: string
   stock: number
   max_purchase_limit: number
+  price_tiers: PriceTierValue[]
+  min_order_quantity: number
+  max_order_quantity: number
+  quantity_increment: number
+  // 询价模式：可见价格的客户等级，逗号分隔（提交时转为数组）
+  price_on_request: boolean
+  price_visible_tiers: string
+  auto_cancel_hours: number
+  // 配送限制国家，逗号分隔的国家代码（提交时转为数组）
+  shipping_allowed_countries: string
+  shipping_blocked_countries: string
+  // 报关信息（仅实物商品），申报价值为主单位
+  hs_code: string
+  declared_value: string
+  weight_grams: number
+  origin_country: string
+  length_mm: number
+  width_mm: number
+  height_mm: number
   images: Array<{ url: string; alt: string; is_primary: boolean }>
   attributes: Array<{
     name: string
@@ -388,6 +409,9 @@ interface ProductForm {
   is_featured: boolean
   is_recommended: boolean
   auto_delivery: boolean
+  reserve_on_cart: boolean
+  waiting_room: boolean
+  email_delivery_mode: 'none' | 'inline' | 'link'
   remark: string
   // 规格与库存配置
   variant_mode: 'user_select' | 'blind_box' // 规格模式
@@ -428,6 +452,22 @@ export default function ProductEditPage() {
     original_price: '',
     stock: 0,
     max_purchase_limit: 0,
+    price_tiers: [],
+    min_order_quantity: 0,
+    max_order_quantity: 0,
+    quantity_increment: 0,
+    price_on_request: false,
+    price_visible_tiers: '',
+    auto_cancel_hours: 0,
+    shipping_allowed_countries: '',
+    shipping_blocked_countries: '',
+    hs_code: '',
+    declared_value: '',
+    weight_grams: 0,
+    origin_country: '',
+    length_mm: 0,
+    width_mm: 0,
+    height_mm: 0,
     images: [],
     attributes: [],
     status: 'draft',
@@ -435,6 +475,9 @@ export default function ProductEditPage() {
     is_featured: false,
     is_recommended: false,
     auto_delivery: false,
+    reserve_on_cart: false,
+    waiting_room: false,
+    email_delivery_mode: 'none',
     remark: '',
     // 规格与库存配置
     variant_mode: 'user_select',
@@ -497,6 +540,27 @@ export default function ProductEditPage() {
         original_price: minorToMajor(product.original_price_minor ?? 0).toString(),
         stock: product.stock ?? 0,
         max_purchase_limit: product.max_purchase_limit ?? product.maxPurchaseLimit ?? 0,
+        min_order_quantity: product.min_order_quantity ?? 0,
+        max_order_quantity: product.max_order_quantity ?? 0,
+        quantity_increment: product.quantity_increment ?? 0,
+        price_on_request: product.price_on_request ?? false,
+        price_visible_tiers: (product.price_visible_tiers || []).join(', '),
+        price_tiers: (product.price_tiers || []).map((tier: any) => ({
+          min_quantity: tier.min_quantity,
+          price: minorToMajor(tier.price_minor ?? 0).toString(),
+        })),
+        auto_cancel_hours: product.auto_cancel_hours ?? 0,
+        shipping_allowed_countries: (product.shipping_allowed_countries || []).join(', '),
+        shipping_blocked_countries: (product.shipping_blocked_countries || []).join(', '),
+        hs_code: product.hs_code || '',
+        declared_value: product.declared_value_minor
+          ? minorToMajor(product.declared_value_minor).toString()
+          : '',
+        weight_grams: product.weight_grams ?? 0,
+        origin_country: product.origin_country || '',
+        length_mm: product.length_mm ?? 0,
+        width_mm: product.width_mm ?? 0,
+        height_mm: product.height_mm ?? 0,
         images: product.images || [],
         attributes: (product.attributes || []).map((attr: any) => {
           // 确保 values 是字符串数组
@@ -515,6 +579,9 @@ export default function ProductEditPage() {
         is_featured: product.is_featured ?? product.isFeatured ?? false,
         is_recommended: product.is_recommended ?? product.isRecommended ?? false,
         auto_delivery: product.auto_delivery ?? false,
+        reserve_on_cart: product.reserve_on_cart ?? false,
+        waiting_room: product.waiting_room ?? false,
+        email_delivery_mode: product.email_delivery_mode || 'none',
         remark: product.remark || '',
         // 规格与库存配置
         variant_mode: (product.inventory_mode === 'random' ? 'blind_box' : 'user_select') as
@@ -632,6 +699,7 @@ export default function ProductEditPage() {
               virtual_inventory_id: binding.virtual_inventory_id,
               is_random: binding.is_random ?? false,
               priority: binding.priority ?? 1,
+              delivery_order: binding.delivery_order || '',
             })
           }
 
@@ -810,6 +878,7 @@ export default function ProductEditPage() {
               virtual_inventory_id: b.virtual_inventory_id,
               is_random: b.is_random || false,
               priority: b.priority || 1,
+              delivery_order: b.delivery_order || undefined,
             }))
 
           if (virtualBindings.length > 0) {
@@ -880,6 +949,7 @@ export default function ProductEditPage() {
               virtual_inventory_id: b.virtual_inventory_id,
               is_random: b.is_random || false,
               priority: b.priority || 1,
+              delivery_order: b.delivery_order || undefined,
             }))
 
           // 总是调用保存API来更新绑定（会自动删除旧的并创建新的）
@@ -928,6 +998,19 @@ export default function ProductEditPage() {
       toast.error(t.admin.priceMustBePositive)
       return
     }
+    const priceTiers = form.price_tiers.map((tier) => ({
+      min_quantity: tier.min_quantity,
+      price_minor: parseMajorToMinor(tier.price),
+    }))
+    if (priceTiers.some((tier) => tier.price_minor === null || tier.price_minor < 0)) {
+      toast.error(t.admin.priceMustBePositive)
+      return
+    }
+    const declaredValueMinor = parseMajorToMinor(form.declared_value || '0')
+    if (declaredValueMinor === null || declaredValueMinor < 0) {
+      toast.error(t.admin.priceMustBePositive)
+      return
+    }
 
     // 实体商品：验证规格库存配置
     if (
@@ -958,11 +1041,27 @@ export default function ProductEditPage() {
     }
 
     // 提交数据（不包含variant_inventory_bindings，因为绑定会在创建/更新商品后单独处理）
+    const parseCountryCodes = (value: string) =>
+      value
+        .split(/[\s,，]+/)
+        .map((code) => code.trim().toUpperCase())
+        .filter(Boolean)
     const submitData = {
       ...form,
       price_minor: priceMinor,
       original_price_minor: originalPriceMinor,
+      price_tiers: priceTiers,
+      shipping_allowed_countries: parseCountryCodes(form.shipping_allowed_countries),
+      shipping_blocked_countries: parseCountryCodes(form.shipping_blocked_countries),
+      price_visible_tiers: form.price_visible_tiers
+        .split(/[,，\s]+/)
+        .map((tier) => tier.trim().toLowerCase())
+        .filter(Boolean),
+      hs_code: form.hs_code.replace(/[\s.]/g, ''),
+      declared_value_minor: declaredValueMinor,
+      origin_country: form.origin_country.trim().toUpperCase(),
     }
+    delete (submitData as any).declared_value
     delete (submitData as any).variant_inventory_bindings
     delete (submitData as any).virtual_variant_inventory_bindings
     delete (submitData as any).variant_mode
@@ -1131,9 +1230,17 @@ export default function ProductEditPage() {
       original_price_major: form.original_price || undefined,
       stock: Number(form.stock || 0),
       max_purchase_limit: Number(form.max_purchase_limit || 0),
+      min_order_quantity: Number(form.min_order_quantity || 0),
+      max_order_quantity: Number(form.max_order_quantity || 0),
+      quantity_increment: Number(form.quantity_increment || 0),
+      price_on_request: Boolean(form.price_on_request),
+      auto_cancel_hours: Number(form.auto_cancel_hours || 0),
       is_featured: Boolean(form.is_featured),
       is_recommended: Boolean(form.is_recommended),
       auto_delivery: Boolean(form.auto_delivery),
+      reserve_on_cart: Boolean(form.reserve_on_cart),
+      waiting_room: Boolean(form.waiting_room),
+      email_delivery_mode: form.email_delivery_mode,
     },
     summary: {
       image_count: form.images.length,
@@ -1406,6 +1513,175 @@ export default function ProductEditPage() {
               />
               <p className="text-xs text-muted-foreground">{t.admin.maxPurchaseLimitHint}</p>
             </div>
+            <div className="space-y-2">
+              <div className="grid grid-cols-1 gap-4 md:grid-cols-3">
+                <div className="space-y-2">
+                  <Label htmlFor="min_order_quantity">{t.admin.minOrderQuantity}</Label>
+                  <Input
+                    id="min_order_quantity"
+                    type="number"
+                    min="0"
+                    value={form.min_order_quantity}
+                    onChange={(e) =>
+                      setForm({ ...form, min_order_quantity: parseInt(e.target.value) || 0 })
+                    }
+                    placeholder="0"
+                  />
+                </div>
+                <div className="space-y-2">
+                  <Label htmlFor="max_order_quantity">{t.admin.maxOrderQuantity}</Label>
+                  <Input
+                    id="max_order_quantity"
+                    type="number"
+                    min="0"
+                    value={form.max_order_quantity}
+                    onChange={(e) =>
+                      setForm({ ...form, max_order_quantity: parseInt(e.target.value) || 0 })
+                    }
+                    placeholder="0"
+                  />
+                </div>
+                <div className="space-y-2">
+                  <Label htmlFor="quantity_increment">{t.admin.quantityIncrement}</Label>
+                  <Input
+                    id="quantity_increment"
+                    type="number"
+                    min="0"
+                    value={form.quantity_increment}
+                    onChange={(e) =>
+                      setForm({ ...form, quantity_increment: parseInt(e.target.value) || 0 })
+                    }
+                    placeholder="0"
+                  />
+                </div>
+              </div>
+              <p className="text-xs text-muted-foreground">{t.admin.orderQuantityRulesHint}</p>
+            </div>
+            <PriceTierInputs
+              value={form.price_tiers}
+              onChange={(price_tiers) => setForm({ ...form, price_tiers })}
+            />
+            <div className="space-y-2">
+              <div className="flex items-center space-x-2">
+                <Switch
+                  id="price_on_request"
+                  checked={form.price_on_request}
+                  onCheckedChange={(checked) => setForm({ ...form, price_on_request: checked })}
+                />
+                <Label htmlFor="price_on_request">{t.admin.priceOnRequest}</Label>
+              </div>
+              {form.price_on_request && (
+                <Input
+                  id="price_visible_tiers"
+                  value={form.price_visible_tiers}
+                  onChange={(e) => setForm({ ...form, price_visible_tiers: e.target.value })}
+                  placeholder={t.admin.priceVisibleTiersPlaceholder}
+                />
+              )}
+              <p className="text-xs text-muted-foreground">{t.admin.priceOnRequestHint}</p>
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="auto_cancel_hours">{t.admin.productAutoCancelHours}</Label>
+              <Input
+                id="auto_cancel_hours"
+                type="number"
+                min="0"
+                max="720"
+                value={form.auto_cancel_hours}
+                onChange={(e) =>
+                  setForm({ ...form, auto_cancel_hours: parseInt(e.target.value) || 0 })
+                }
+                placeholder="0"
+                className="w-64"
+              />
+              <p className="text-xs text-muted-foreground">{t.admin.productAutoCancelHoursHint}</p>
+            </div>
+            <div className="grid grid-cols-1 gap-4 md:grid-cols-2">
+              <div className="space-y-2">
+                <Label htmlFor="shipping_allowed_countries">
+                  {t.admin.shippingAllowedCountries}
+                </Label>
+                <Input
+                  id="shipping_allowed_countries"
+                  value={form.shipping_allowed_countries}
+                  onChange={(e) =>
+                    setForm({ ...form, shipping_allowed_countries: e.target.value })
+                  }
+                  placeholder="US, CA"
+                />
+                <p className="text-xs text-muted-foreground">
+                  {t.admin.shippingAllowedCountriesHint}
+                </p>
+              </div>
+              <div className="space-y-2">
+                <Label htmlFor="shipping_blocked_countries">
+                  {t.admin.shippingBlockedCountries}
+                </Label>
+                <Input
+                  id="shipping_blocked_countries"
+                  value={form.shipping_blocked_countries}
+                  onChange={(e) =>
+                    setForm({ ...form, shipping_blocked_countries: e.target.value })
+                  }
+                  placeholder="JP, KR"
+                />
+                <p className="text-xs text-muted-foreground">
+                  {t.admin.shippingBlockedCountriesHint}
+                </p>
+              </div>
+            </div>
+            {form.product_type !== 'virtual' && (
+              <div className="space-y-2">
+                <Label>{t.admin.customsInfo}</Label>
+                <div className="grid grid-cols-2 gap-4 md:grid-cols-4">
+                  <Input
+                    id="hs_code"
+                    value={form.hs_code}
+                    onChange={(e) => setForm({ ...form, hs_code: e.target.value })}
+                    placeholder={t.admin.hsCodePlaceholder}
+                  />
+                  <Input
+                    id="declared_value"
+                    type="number"
+                    step="0.01"
+                    min="0"
+                    value={form.declared_value}
+                    onChange={(e) => setForm({ ...form, declared_value: e.target.value })}
+                    placeholder={t.admin.declaredValuePlaceholder}
+                  />
+                  <Input
+                    id="weight_grams"
+                    type="number"
+                    min="0"
+                    value={form.weight_grams || ''}
+                    onChange={(e) =>
+                      setForm({ ...form, weight_grams: parseInt(e.target.value) || 0 })
+                    }
+                    placeholder={t.admin.weightGramsPlaceholder}
+                  />
+                  <Input
+                    id="origin_country"
+                    value={form.origin_country}
+                    onChange={(e) => setForm({ ...form, origin_country: e.target.value })}
+                    placeholder={t.admin.originCountryPlaceholder}
+                    maxLength={2}
+                  />
+                </div>
+                <p className="text-xs text-muted-foreground">{t.admin.customsInfoHint}</p>
+              </div>
+            )}
+            {form.product_type !== 'virtual' && (
+              <DimensionInputs
+                withWeight={false}
+                value={{
+                  length_mm: form.length_mm,
+                  width_mm: form.width_mm,
+                  height_mm: form.height_mm,
+                }}
+                onChange={(dims) => setForm({ ...form, ...dims })}
+                hint={t.admin.packageDimensionsHint}
+              />
+            )}
           </CardContent>
         </Card>
 
@@ -1649,6 +1925,26 @@ export default function ProductEditPage() {
                   <Label htmlFor="auto_delivery">{t.admin.autoDelivery}</Label>
                 </div>
               )}
+              <div className="flex items-center space-x-2">
+                <Switch
+                  id="reserve_on_cart"
+                  checked={form.reserve_on_cart}
+                  onCheckedChange={(checked) => setForm({ ...form, reserve_on_cart: checked })}
+                />
+                <Label htmlFor="reserve_on_cart" title={t.admin.reserveOnCartHint}>
+                  {t.admin.reserveOnCart}
+                </Label>
+              </div>
+              <div className="flex items-center space-x-2">
+                <Switch
+                  id="waiting_room"
+                  checked={form.waiting_room}
+                  onCheckedChange={(checked) => setForm({ ...form, waiting_room: checked })}
+                />
+                <Label htmlFor="waiting_room" title={t.admin.waitingRoomHint}>
+                  {t.admin.waitingRoom}
+                </Label>
+              </div>
               <div className="space-y-2">
                 <Label htmlFor="sort_order">{t.admin.sortOrder}</Label>
                 <Input
@@ -1660,6 +1956,29 @@ export default function ProductEditPage() {
               </div>
             </div>
 
+            {form.product_type === 'virtual' && (
+              <div className="space-y-2">
+                <Label htmlFor="email_delivery_mode">{t.admin.emailDeliveryMode}</Label>
+                <Select
+                  key={`email-delivery-${selectKey}`}
+                  value={form.email_delivery_mode}
+                  onValueChange={(value: 'none' | 'inline' | 'link') =>
+                    setForm({ ...form, email_delivery_mode: value })
+                  }
+                >
+                  <SelectTrigger id="email_delivery_mode">
+                    <SelectValue />
+                  </SelectTrigger>
+                  <SelectContent>
+                    <SelectItem value="none">{t.admin.emailDeliveryModeNone}</SelectItem>
+                    <SelectItem value="inline">{t.admin.emailDeliveryModeInline}</SelectItem>
+                    <SelectItem value="link">{t.admin.emailDeliveryModeLink}</SelectItem>
+                  </SelectContent>
+                </Select>
+                <p className="text-xs text-muted-foreground">{t.admin.emailDeliveryModeHint}</p>
+              </div>
+            )}
+
             <div className="space-y-2">
               <Label htmlFor="remark">{t.admin.remarkLabel}</Label>
               <Textarea
@@ -10,6 +10,7 @@ import {
   deleteVirtualStock,
   deleteStockBatch,
   getProductVirtualInventoryBindings,
+  revealVirtualInventoryStock,
 } from '@/lib/api'
 import { Button } from '@/components/ui/button'
 import { Badge } from '@/components/ui/badge'
@@ -86,7 +87,7 @@ export default function VirtualStockPage() {
   const [statusFilter, setStatusFilter] = useState<string>('all')
   const [deleteId, setDeleteId] = useState<number | null>(null)
   const [deleteBatchNo, setDeleteBatchNo] = useState<string | null>(null)
-  const [showContent, setShowContent] = useState<Record<number, boolean>>({})
+  const [revealedContent, setRevealedContent] = useState<Record<number, string>>({})
   const formatDeleteError = (error: unknown) =>
     t.virtualStock.deleteFailed.replace(
       '{msg}',
@@ -161,8 +162,35 @@ export default function VirtualStockPage() {
     toast.success(t.virtualStock.copiedToClipboard)
   }
 
-  const toggleContentVisibility = (id: number) => {
-    setShowContent(prev => ({ ...prev, [id]: !prev[id] }))
+  // 列表返回的是脱敏内容，明文需单独请求并记录查看日志
+  const revealContent = async (stock: VirtualProductStock) => {
+    if (revealedContent[stock.id] !== undefined) return revealedContent[stock.id]
+    try {
+      const res: any = await revealVirtualInventoryStock(stock.virtual_inventory_id, stock.id)
+      const content = String(res?.data?.content || '')
+      setRevealedContent(prev => ({ ...prev, [stock.id]: content }))
+      return content
+    } catch (error: unknown) {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.revealStockFailed))
+      return null
+    }
+  }
+
+  const toggleContentVisibility = (stock: VirtualProductStock) => {
+    if (revealedContent[stock.id] === undefined) {
+      void revealContent(stock)
+      return
+    }
+    setRevealedContent(prev => {
+      const next = { ...prev }
+      delete next[stock.id]
+      return next
+    })
+  }
+
+  const copyStockContent = async (stock: VirtualProductStock) => {
+    const content = await revealContent(stock)
+    if (content) copyToClipboard(content)
   }
 
   const product = productData?.data
@@ -386,21 +414,21 @@ export default function VirtualStockPage() {
                     <TableCell>
                       <div className="flex items-center gap-2">
                         <code className="bg-muted px-2 py-1 rounded text-sm max-w-[200px] truncate">
-                          {showContent[stock.id] ? stock.content : '••••••••••'}
+                          {revealedContent[stock.id] ?? (stock.content || '••••••••••')}
                         </code>
                         <Button
                           variant="ghost"
                           size="sm"
                           className="h-6 w-6 p-0"
-                          onClick={() => toggleContentVisibility(stock.id)}
+                          onClick={() => toggleContentVisibility(stock)}
                         >
-                          {showContent[stock.id] ? <EyeOff className="w-3 h-3" /> : <Eye className="w-3 h-3" />}
+                          {revealedContent[stock.id] !== undefined ? <EyeOff className="w-3 h-3" /> : <Eye className="w-3 h-3" />}
                         </Button>
                         <Button
                           variant="ghost"
                           size="sm"
                           className="h-6 w-6 p-0"
-                          onClick={() => copyToClipboard(stock.content)}
+                          onClick={() => copyStockContent(stock)}
                         >
                           <Copy className="w-3 h-3" />
                         </Button>
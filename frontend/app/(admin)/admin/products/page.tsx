@@ -10,6 +10,7 @@ import {
   updateProductStatus,
 } from '@/lib/api'
 import { DataTable } from '@/components/admin/data-table'
+import { ShippingBlocksDialog } from '@/components/admin/shipping-blocks-dialog'
 import { Button } from '@/components/ui/button'
 import { Badge } from '@/components/ui/badge'
 import {
@@ -515,6 +516,7 @@ export default function AdminProductsPage() {
             <Download className="mr-2 h-4 w-4" />
             {t.admin.exportProducts}
           </Button>
+          <ShippingBlocksDialog />
           <Button variant="outline" onClick={() => refetch()}>
             <RefreshCw className="mr-2 h-4 w-4" />
             {t.admin.refresh}
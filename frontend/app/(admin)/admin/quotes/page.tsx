@@ -0,0 +1,370 @@
+'use client'
+
+import { useState } from 'react'
+import Link from 'next/link'
+import { useMutation, useQuery } from '@tanstack/react-query'
+import { RefreshCw } from 'lucide-react'
+import toast from 'react-hot-toast'
+import {
+  closeAdminQuoteRequest,
+  getAdminQuoteRequests,
+  quoteAdminQuoteRequest,
+  type QuoteRequest,
+} from '@/lib/api'
+import { DataTable } from '@/components/admin/data-table'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Textarea } from '@/components/ui/textarea'
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useDebounce } from '@/hooks/use-debounce'
+import { useLocale } from '@/hooks/use-locale'
+import { usePageTitle } from '@/hooks/use-page-title'
+import { usePermission } from '@/hooks/use-permission'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatCurrency, formatDate, parseMajorToMinor } from '@/lib/utils'
+import { getQuoteStatusConfig } from '@/components/orders/quote-status'
+
+export default function AdminQuoteRequestsPage() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  usePageTitle(t.pageTitle.adminQuoteRequests)
+  const { hasPermission } = usePermission()
+  const canEdit = hasPermission('order.edit')
+  const statusConfig = getQuoteStatusConfig(t)
+
+  const [page, setPage] = useState(1)
+  const [status, setStatus] = useState('all')
+  const [search, setSearch] = useState('')
+  const debouncedSearch = useDebounce(search)
+  const [quoteTarget, setQuoteTarget] = useState<QuoteRequest | null>(null)
+  const [closeTarget, setCloseTarget] = useState<QuoteRequest | null>(null)
+  const [unitPrice, setUnitPrice] = useState('')
+  const [validDays, setValidDays] = useState('30')
+  const [reply, setReply] = useState('')
+
+  const { data, isLoading, refetch } = useQuery({
+    queryKey: ['adminQuoteRequests', page, status, debouncedSearch],
+    queryFn: () =>
+      getAdminQuoteRequests({
+        page,
+        limit: 20,
+        status: status === 'all' ? undefined : status,
+        search: debouncedSearch || undefined,
+      }),
+  })
+  const quotes: QuoteRequest[] = data?.data?.items || []
+
+  const openQuoteDialog = (quote: QuoteRequest) => {
+    setQuoteTarget(quote)
+    setUnitPrice(
+      quote.quoted_unit_price_minor ? (quote.quoted_unit_price_minor / 100).toFixed(2) : ''
+    )
+    setValidDays('30')
+    setReply(quote.admin_reply || '')
+  }
+
+  const openCloseDialog = (quote: QuoteRequest) => {
+    setCloseTarget(quote)
+    setReply('')
+  }
+
+  const quoteMutation = useMutation({
+    mutationFn: () => {
+      const amount = parseMajorToMinor(unitPrice)
+      if (amount === null || amount <= 0) {
+        return Promise.reject(new Error(t.quoteRequest.unitPrice))
+      }
+      return quoteAdminQuoteRequest(quoteTarget!.id, {
+        unit_price_minor: amount,
+        valid_days: Number(validDays || 0),
+        reply: reply || undefined,
+      })
+    },
+    onSuccess: () => {
+      toast.success(t.quoteRequest.quoteSent)
+      setQuoteTarget(null)
+      refetch()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.operationFailed))
+    },
+  })
+
+  const closeMutation = useMutation({
+    mutationFn: () => closeAdminQuoteRequest(closeTarget!.id, reply),
+    onSuccess: () => {
+      toast.success(t.quoteRequest.closed)
+      setCloseTarget(null)
+      refetch()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.operationFailed))
+    },
+  })
+
+  const columns = [
+    {
+      header: t.quoteRequest.quoteNo,
+      cell: ({ row }: { row: { original: QuoteRequest } }) => (
+        <div>
+          <div className="font-mono text-sm">{row.original.quote_no}</div>
+          <div className="text-xs text-muted-foreground">
+            {formatDate(row.original.created_at)}
+          </div>
+        </div>
+      ),
+    },
+    {
+      header: t.quoteRequest.customer,
+      cell: ({ row }: { row: { original: QuoteRequest } }) => (
+        <div className="text-sm">
+          <div>{row.original.company || row.original.contact_name || '-'}</div>
+          <div className="text-xs text-muted-foreground">
+            {row.original.contact_email || row.original.user?.email}
+            {row.original.contact_phone ? ` · ${row.original.contact_phone}` : ''}
+          </div>
+        </div>
+      ),
+    },
+    {
+      header: t.quoteRequest.product,
+      cell: ({ row }: { row: { original: QuoteRequest } }) => (
+        <div className="max-w-[260px] text-sm">
+          <Link
+            href={`/admin/products/${row.original.product_id}`}
+            className="font-medium hover:underline"
+          >
+            {row.original.product_name}
+          </Link>
+          <div className="text-xs text-muted-foreground">
+            {row.original.product_sku} × {row.original.quantity}
+            {row.original.attributes && Object.keys(row.original.attributes).length > 0
+              ? ` · ${Object.entries(row.original.attributes)
+                  .map(([key, value]) => `${key}: ${value}`)
+                  .join(', ')}`
+              : ''}
+          </div>
+          {row.original.message && (
+            <p className="mt-1 line-clamp-2 text-xs text-muted-foreground">
+              {row.original.message}
+            </p>
+          )}
+        </div>
+      ),
+    },
+    {
+      header: t.quoteRequest.quotedPrice,
+      cell: ({ row }: { row: { original: QuoteRequest } }) =>
+        row.original.quoted_unit_price_minor > 0 ? (
+          <div className="tabular-nums">
+            <div>{formatCurrency(row.original.quoted_unit_price_minor, row.original.currency)}</div>
+            <div className="text-xs text-muted-foreground">
+              {t.quoteRequest.total}{' '}
+              {formatCurrency(
+                row.original.quoted_unit_price_minor * row.original.quantity,
+                row.original.currency
+              )}
+            </div>
+          </div>
+        ) : (
+          '-'
+        ),
+    },
+    {
+      header: t.admin.status,
+      cell: ({ row }: { row: { original: QuoteRequest } }) => {
+        const config = statusConfig[row.original.status] || statusConfig.pending
+        return <Badge className={config.color}>{config.label}</Badge>
+      },
+    },
+    {
+      header: t.admin.actions,
+      cell: ({ row }: { row: { original: QuoteRequest } }) =>
+        canEdit && ['pending', 'quoted'].includes(row.original.status) ? (
+          <div className="flex gap-2">
+            <Button size="sm" variant="outline" onClick={() => openQuoteDialog(row.original)}>
+              {row.original.status === 'quoted'
+                ? t.quoteRequest.requote
+                : t.quoteRequest.sendQuote}
+            </Button>
+            <Button size="sm" variant="ghost" onClick={() => openCloseDialog(row.original)}>
+              {t.quoteRequest.close}
+            </Button>
+          </div>
+        ) : null,
+    },
+  ]
+
+  return (
+    <div className="space-y-6">
+      <div className="flex flex-col gap-4 md:flex-row md:items-start md:justify-between">
+        <div>
+          <h1 className="text-3xl font-bold">{t.admin.quoteRequests}</h1>
+          <p className="mt-1 text-sm text-muted-foreground">{t.admin.quoteRequestsDesc}</p>
+        </div>
+        <Button variant="outline" onClick={() => refetch()}>
+          <RefreshCw className="mr-2 h-4 w-4" />
+          {t.admin.refresh}
+        </Button>
+      </div>
+
+      <Card>
+        <CardHeader
+          className={
+            'flex flex-col gap-3 space-y-0 md:flex-row md:items-center md:justify-between'
+          }
+        >
+          <CardTitle>{t.admin.quoteRequests}</CardTitle>
+          <div className="flex flex-wrap gap-2">
+            <Input
+              value={search}
+              onChange={(e) => {
+                setSearch(e.target.value)
+                setPage(1)
+              }}
+              placeholder={t.quoteRequest.searchPlaceholder}
+              className="w-[220px]"
+            />
+            <Select
+              value={status}
+              onValueChange={(value) => {
+                setStatus(value)
+                setPage(1)
+              }}
+            >
+              <SelectTrigger className="w-[160px]">
+                <SelectValue />
+              </SelectTrigger>
+              <SelectContent>
+                <SelectItem value="all">{t.quoteRequest.statusAll}</SelectItem>
+                {Object.entries(statusConfig).map(([value, config]) => (
+                  <SelectItem key={value} value={value}>
+                    {config.label}
+                  </SelectItem>
+                ))}
+              </SelectContent>
+            </Select>
+          </div>
+        </CardHeader>
+        <CardContent>
+          <DataTable
+            columns={columns}
+            data={quotes}
+            isLoading={isLoading}
+            pagination={{
+              page,
+              total_pages: data?.data?.pagination?.total_pages || 1,
+              onPageChange: setPage,
+            }}
+          />
+        </CardContent>
+      </Card>
+
+      <Dialog open={!!quoteTarget} onOpenChange={(open) => !open && setQuoteTarget(null)}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{t.quoteRequest.sendQuote}</DialogTitle>
+            <DialogDescription>
+              {t.quoteRequest.sendQuoteDesc
+                .replace('{product}', quoteTarget?.product_name || '')
+                .replace('{quantity}', String(quoteTarget?.quantity || 0))}
+            </DialogDescription>
+          </DialogHeader>
+          <div className="space-y-4">
+            <div className="grid grid-cols-2 gap-4">
+              <div className="space-y-2">
+                <Label htmlFor="quote-unit-price">{t.quoteRequest.unitPrice}</Label>
+                <Input
+                  id="quote-unit-price"
+                  inputMode="decimal"
+                  value={unitPrice}
+                  onChange={(e) => setUnitPrice(e.target.value)}
+                />
+              </div>
+              <div className="space-y-2">
+                <Label htmlFor="quote-valid-days">{t.quoteRequest.validDays}</Label>
+                <Input
+                  id="quote-valid-days"
+                  type="number"
+                  min="0"
+                  max="365"
+                  value={validDays}
+                  onChange={(e) => setValidDays(e.target.value)}
+                />
+              </div>
+            </div>
+            <p className="text-xs text-muted-foreground">{t.quoteRequest.validDaysHint}</p>
+            <div className="space-y-2">
+              <Label htmlFor="quote-reply">{t.quoteRequest.reply}</Label>
+              <Textarea
+                id="quote-reply"
+                value={reply}
+                onChange={(e) => setReply(e.target.value)}
+                rows={3}
+              />
+            </div>
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setQuoteTarget(null)}>
+              {t.common.cancel}
+            </Button>
+            <Button
+              onClick={() => quoteMutation.mutate()}
+              disabled={quoteMutation.isPending || !unitPrice}
+            >
+              {t.common.confirm}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+
+      <Dialog open={!!closeTarget} onOpenChange={(open) => !open && setCloseTarget(null)}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{t.quoteRequest.close}</DialogTitle>
+            <DialogDescription>{closeTarget?.quote_no}</DialogDescription>
+          </DialogHeader>
+          <div className="space-y-2">
+            <Label htmlFor="quote-close-reply">{t.quoteRequest.reply}</Label>
+            <Textarea
+              id="quote-close-reply"
+              value={reply}
+              onChange={(e) => setReply(e.target.value)}
+              rows={3}
+            />
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setCloseTarget(null)}>
+              {t.common.cancel}
+            </Button>
+            <Button
+              variant="destructive"
+              onClick={() => closeMutation.mutate()}
+              disabled={closeMutation.isPending}
+            >
+              {t.common.confirm}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </div>
+  )
+}
@@ -0,0 +1,373 @@
+'use client'
+
+import { useState } from 'react'
+import Link from 'next/link'
+import { useMutation, useQuery } from '@tanstack/react-query'
+import { RefreshCw } from 'lucide-react'
+import toast from 'react-hot-toast'
+import {
+  approveAdminReturnRequest,
+  getAdminReturnRequests,
+  receiveAdminReturnRequest,
+  rejectAdminReturnRequest,
+  type ReturnRequest,
+} from '@/lib/api'
+import { DataTable } from '@/components/admin/data-table'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Switch } from '@/components/ui/switch'
+import { Textarea } from '@/components/ui/textarea'
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useDebounce } from '@/hooks/use-debounce'
+import { useLocale } from '@/hooks/use-locale'
+import { usePageTitle } from '@/hooks/use-page-title'
+import { usePermission } from '@/hooks/use-permission'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatDate } from '@/lib/utils'
+import { getReturnStatusConfig } from '@/components/orders/return-status'
+
+type ReviewAction = 'approve' | 'reject'
+
+export default function AdminReturnRequestsPage() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  usePageTitle(t.pageTitle.adminReturnRequests)
+  const { hasPermission } = usePermission()
+  const canManage = hasPermission('return.manage')
+  const statusConfig = getReturnStatusConfig(t)
+
+  const [page, setPage] = useState(1)
+  const [status, setStatus] = useState('all')
+  const [search, setSearch] = useState('')
+  const debouncedSearch = useDebounce(search)
+  const [reviewTarget, setReviewTarget] = useState<{
+    request: ReturnRequest
+    action: ReviewAction
+  } | null>(null)
+  const [receiveTarget, setReceiveTarget] = useState<ReturnRequest | null>(null)
+  const [remark, setRemark] = useState('')
+  const [restock, setRestock] = useState(true)
+  const [invalidateSerials, setInvalidateSerials] = useState(false)
+
+  const { data, isLoading, refetch } = useQuery({
+    queryKey: ['adminReturnRequests', page, status, debouncedSearch],
+    queryFn: () =>
+      getAdminReturnRequests({
+        page,
+        limit: 20,
+        status: status === 'all' ? undefined : status,
+        search: debouncedSearch || undefined,
+      }),
+  })
+  const requests: ReturnRequest[] = data?.data?.items || []
+
+  const openReviewDialog = (request: ReturnRequest, action: ReviewAction) => {
+    setReviewTarget({ request, action })
+    setRemark('')
+  }
+
+  const openReceiveDialog = (request: ReturnRequest) => {
+    setReceiveTarget(request)
+    setRestock(true)
+    setInvalidateSerials(false)
+  }
+
+  const reviewMutation = useMutation({
+    mutationFn: () =>
+      reviewTarget!.action === 'approve'
+        ? approveAdminReturnRequest(reviewTarget!.request.id, remark)
+        : rejectAdminReturnRequest(reviewTarget!.request.id, remark),
+    onSuccess: () => {
+      toast.success(
+        reviewTarget?.action === 'approve' ? t.returnRequest.approved : t.returnRequest.rejected
+      )
+      setReviewTarget(null)
+      refetch()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.operationFailed))
+    },
+  })
+
+  const receiveMutation = useMutation({
+    mutationFn: () =>
+      receiveAdminReturnRequest(receiveTarget!.id, {
+        restock,
+        invalidate_serials: invalidateSerials,
+      }),
+    onSuccess: () => {
+      toast.success(t.returnRequest.receivedToast)
+      setReceiveTarget(null)
+      refetch()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.operationFailed))
+    },
+  })
+
+  const columns = [
+    {
+      header: t.returnRequest.rmaNo,
+      cell: ({ row }: { row: { original: ReturnRequest } }) => (
+        <div>
+          <div className="font-mono text-sm">{row.original.rma_no}</div>
+          <div className="text-xs text-muted-foreground">
+            {formatDate(row.original.created_at)}
+          </div>
+        </div>
+      ),
+    },
+    {
+      header: t.returnRequest.order,
+      cell: ({ row }: { row: { original: ReturnRequest } }) => (
+        <div className="text-sm">
+          <Link
+            href={`/admin/orders/${row.original.order_id}`}
+            className="font-mono hover:underline"
+          >
+            {row.original.order_no}
+          </Link>
+          <div className="text-xs text-muted-foreground">{row.original.user?.email}</div>
+        </div>
+      ),
+    },
+    {
+      header: t.returnRequest.items,
+      cell: ({ row }: { row: { original: ReturnRequest } }) => (
+        <div className="max-w-[280px] text-sm">
+          {row.original.items.map((item) => (
+            <div key={item.item_index}>
+              {item.name} <span className="text-muted-foreground">({item.sku})</span> ×{' '}
+              {item.quantity}
+            </div>
+          ))}
+          <p className="mt-1 line-clamp-2 text-xs text-muted-foreground">{row.original.reason}</p>
+          {row.original.photo_urls && row.original.photo_urls.length > 0 && (
+            <div className="mt-1 flex flex-wrap gap-1">
+              {row.original.photo_urls.map((url) => (
+                <a key={url} href={url} target="_blank" rel="noopener noreferrer">
+                  <img src={url} alt="" className="h-10 w-10 rounded border object-cover" />
+                </a>
+              ))}
+            </div>
+          )}
+        </div>
+      ),
+    },
+    {
+      header: t.admin.status,
+      cell: ({ row }: { row: { original: ReturnRequest } }) => {
+        const config = statusConfig[row.original.status] || statusConfig.pending
+        return (
+          <div className="space-y-1">
+            <Badge className={config.color}>{config.label}</Badge>
+            {row.original.return_tracking_no && (
+              <div className="font-mono text-xs text-muted-foreground">
+                {row.original.return_tracking_no}
+              </div>
+            )}
+            {row.original.status === 'received' && (
+              <div className="text-xs text-muted-foreground">
+                {t.returnRequest.restockedQuantity.replace(
+                  '{count}',
+                  String(row.original.restocked_quantity)
+                )}
+              </div>
+            )}
+          </div>
+        )
+      },
+    },
+    {
+      header: t.admin.actions,
+      cell: ({ row }: { row: { original: ReturnRequest } }) => {
+        if (!canManage) return null
+        const request = row.original
+        return (
+          <div className="flex flex-wrap gap-2">
+            {request.status === 'pending' && (
+              <Button
+                size="sm"
+                variant="outline"
+                onClick={() => openReviewDialog(request, 'approve')}
+              >
+                {t.returnRequest.approve}
+              </Button>
+            )}
+            {request.status === 'approved' && (
+              <Button size="sm" variant="outline" onClick={() => openReceiveDialog(request)}>
+                {t.returnRequest.receive}
+              </Button>
+            )}
+            {(request.status === 'pending' || request.status === 'approved') && (
+              <Button size="sm" variant="ghost" onClick={() => openReviewDialog(request, 'reject')}>
+                {t.returnRequest.reject}
+              </Button>
+            )}
+          </div>
+        )
+      },
+    },
+  ]
+
+  return (
+    <div className="space-y-6">
+      <div className="flex flex-col gap-4 md:flex-row md:items-start md:justify-between">
+        <div>
+          <h1 className="text-3xl font-bold">{t.admin.returnRequests}</h1>
+          <p className="mt-1 text-sm text-muted-foreground">{t.admin.returnRequestsDesc}</p>
+        </div>
+        <Button variant="outline" onClick={() => refetch()}>
+          <RefreshCw className="mr-2 h-4 w-4" />
+          {t.admin.refresh}
+        </Button>
+      </div>
+
+      <Card>
+        <CardHeader
+          className={
+            'flex flex-col gap-3 space-y-0 md:flex-row md:items-center md:justify-between'
+          }
+        >
+          <CardTitle>{t.admin.returnRequests}</CardTitle>
+          <div className="flex flex-wrap gap-2">
+            <Input
+              value={search}
+              onChange={(e) => {
+                setSearch(e.target.value)
+                setPage(1)
+              }}
+              placeholder={t.returnRequest.searchPlaceholder}
+              className="w-[220px]"
+            />
+            <Select
+              value={status}
+              onValueChange={(value) => {
+                setStatus(value)
+                setPage(1)
+              }}
+            >
+              <SelectTrigger className="w-[160px]">
+                <SelectValue />
+              </SelectTrigger>
+              <SelectContent>
+                <SelectItem value="all">{t.returnRequest.statusAll}</SelectItem>
+                {Object.entries(statusConfig).map(([value, config]) => (
+                  <SelectItem key={value} value={value}>
+                    {config.label}
+                  </SelectItem>
+                ))}
+              </SelectContent>
+            </Select>
+          </div>
+        </CardHeader>
+        <CardContent>
+          <DataTable
+            columns={columns}
+            data={requests}
+            isLoading={isLoading}
+            pagination={{
+              page,
+              total_pages: data?.data?.pagination?.total_pages || 1,
+              onPageChange: setPage,
+            }}
+          />
+        </CardContent>
+      </Card>
+
+      <Dialog open={!!reviewTarget} onOpenChange={(open) => !open && setReviewTarget(null)}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>
+              {reviewTarget?.action === 'approve'
+                ? t.returnRequest.approve
+                : t.returnRequest.reject}
+            </DialogTitle>
+            <DialogDescription>
+              {reviewTarget?.action === 'approve'
+                ? t.returnRequest.approveDesc
+                : t.returnRequest.rejectDesc}
+            </DialogDescription>
+          </DialogHeader>
+          <div className="space-y-2">
+            <Label htmlFor="return-remark">{t.returnRequest.adminRemark}</Label>
+            <Textarea
+              id="return-remark"
+              value={remark}
+              onChange={(e) => setRemark(e.target.value)}
+              maxLength={1000}
+              rows={3}
+            />
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setReviewTarget(null)}>
+              {t.common.cancel}
+            </Button>
+            <Button
+              variant={reviewTarget?.action === 'reject' ? 'destructive' : 'default'}
+              onClick={() => reviewMutation.mutate()}
+              disabled={
+                reviewMutation.isPending || (reviewTarget?.action === 'reject' && !remark.trim())
+              }
+            >
+              {t.common.confirm}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+
+      <Dialog open={!!receiveTarget} onOpenChange={(open) => !open && setReceiveTarget(null)}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{t.returnRequest.receive}</DialogTitle>
+            <DialogDescription>
+              {t.returnRequest.receiveDesc.replace('{rmaNo}', receiveTarget?.rma_no || '')}
+            </DialogDescription>
+          </DialogHeader>
+          <div className="space-y-4">
+            <div className="flex items-center justify-between gap-4">
+              <Label htmlFor="return-restock">{t.returnRequest.restock}</Label>
+              <Switch id="return-restock" checked={restock} onCheckedChange={setRestock} />
+            </div>
+            <div className="flex items-center justify-between gap-4">
+              <Label htmlFor="return-invalidate-serials">
+                {t.returnRequest.invalidateSerials}
+              </Label>
+              <Switch
+                id="return-invalidate-serials"
+                checked={invalidateSerials}
+                onCheckedChange={setInvalidateSerials}
+              />
+            </div>
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setReceiveTarget(null)}>
+              {t.common.cancel}
+            </Button>
+            <Button onClick={() => receiveMutation.mutate()} disabled={receiveMutation.isPending}>
+              {t.common.confirm}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </div>
+  )
+}
@@ -16,6 +16,10 @@ import {
   getLandingPage,
   updateLandingPage,
   resetLandingPage,
+  getCountries,
+  getWaitingRoomMetrics,
+  getExchangeRates,
+  type ExchangeRateQuote,
 } from '@/lib/api'
 import { Card, CardHeader, CardTitle, CardContent, CardDescription } from '@/components/ui/card'
 import { Button } from '@/components/ui/button'
@@ -23,6 +27,7 @@ import { Input } from '@/components/ui/input'
 import { Label } from '@/components/ui/label'
 import { Badge } from '@/components/ui/badge'
 import { Switch } from '@/components/ui/switch'
+import { Checkbox } from '@/components/ui/checkbox'
 import { Tabs, TabsContent, TabsList, TabsTrigger } from '@/components/ui/tabs'
 import {
   AlertDialog,
@@ -66,6 +71,7 @@ import {
   Layout,
   RotateCcw,
   BarChart3,
+  History,
 } from 'lucide-react'
 import { useForm } from 'react-hook-form'
 import {
@@ -85,6 +91,8 @@ import {
   findAdminMarketPluginBasePath,
 } from '@/lib/plugin-market-route'
 import { PluginSlot } from '@/components/plugins/plugin-slot'
+import { SettingsRevisionsPanel } from '@/components/admin/settings-revisions-panel'
+import { SettingsBundlePanel } from '@/components/admin/settings-bundle-panel'
 
 // 单独的页面规则编辑卡片组件，使用本地state避免每次输入都重渲染整个设置页面
 interface PageRule {
@@ -108,6 +116,14 @@ const PRIMARY_COLOR_PRESETS = [
   { value: '24.6 95% 53.1%', labelKey: 'orange', hex: '#f97316' },
   { value: '0 72.2% 50.6%', labelKey: 'red', hex: '#dc2626' },
 ] as const
+// 需要 Site Key / Secret Key 的第三方验证码驱动
+const CAPTCHA_KEY_PROVIDER_LABELS: Record<string, string> = {
+  cloudflare: 'Turnstile',
+  google: 'reCAPTCHA',
+  recaptcha_v3: 'reCAPTCHA v3',
+  hcaptcha: 'hCaptcha',
+}
+const CAPTCHA_SCENES = ['login', 'register', 'serial_verify', 'bind'] as const
 
 function formatBytes(bytes: number) {
   if (!Number.isFinite(bytes) || bytes <= 0) return '0 B'
@@ -614,6 +630,7 @@ export default function SettingsPage() {
   const [pageRules, setPageRules] = useState<PageRule[]>([])
   const [emailNotifications, setEmailNotifications] = useState<Record<string, boolean>>({})
   const [captchaProvider, setCaptchaProvider] = useState('none')
+  const [captchaSceneProviders, setCaptchaSceneProviders] = useState<Record<string, string>>({})
   const [smsProvider, setSmsProvider] = useState('aliyun')
   const [invoiceEnabled, setInvoiceEnabled] = useState(false)
   const [showVirtualStockRemark, setShowVirtualStockRemark] = useState(false)
@@ -721,6 +738,36 @@ export default function SettingsPage() {
   const [templatePreview, setTemplatePreview] = useState(false)
   const emailTemplatePackageInputRef = useRef<HTMLInputElement>(null)
 
+  // 停运国家（表单设置）
+  const [disabledCountries, setDisabledCountries] = useState<string[]>([])
+  const [countryFilter, setCountryFilter] = useState('')
+  const { data: countriesData } = useQuery({
+    queryKey: ['shippingCountries', 'all'],
+    queryFn: () => getCountries(true),
+    enabled: activeTab === 'order',
+  })
+
+  useEffect(() => {
+    const saved = settings?.data?.form?.disabled_countries
+    setDisabledCountries(Array.isArray(saved) ? saved : [])
+  }, [settings])
+
+  const { data: waitingRoomMetricsData } = useQuery({
+    queryKey: ['waitingRoomMetrics'],
+    queryFn: getWaitingRoomMetrics,
+    enabled: activeTab === 'order',
+    refetchInterval: activeTab === 'order' ? 10000 : false,
+  })
+  const waitingRoomMetrics = waitingRoomMetricsData?.data
+
+  const { data: exchangeRatesData } = useQuery({
+    queryKey: ['exchangeRates'],
+    queryFn: getExchangeRates,
+    enabled: activeTab === 'order',
+    refetchInterval: activeTab === 'order' ? 60000 : false,
+  })
+  const exchangeRates: ExchangeRateQuote[] = exchangeRatesData?.data?.rates || []
+
   const { data: emailTemplatesData } = useQuery({
     queryKey: ['emailTemplates'],
     queryFn: getEmailTemplates,
@@ -924,12 +971,19 @@ export default function SettingsPage() {
   const templateEventLabels: Record<string, string> = {
     welcome: t.admin.templateEventWelcome,
     email_verification: t.admin.templateEventEmailVerification,
+    email_changed: t.admin.templateEventEmailChanged,
     marketing: t.admin.templateEventMarketing,
     order_created: t.admin.templateEventOrderCreated,
     order_paid: t.admin.templateEventOrderPaid,
     order_shipped: t.admin.templateEventOrderShipped,
     order_completed: t.admin.templateEventOrderCompleted,
     order_cancelled: t.admin.templateEventOrderCancelled,
+    order_sub_status: t.admin.templateEventOrderSubStatus,
+    order_payment_reminder: t.admin.templateEventOrderPaymentReminder,
+    virtual_stock_revoked: t.admin.templateEventVirtualStockRevoked,
+    virtual_items: t.admin.templateEventVirtualItems,
+    order_note_mention: t.admin.templateEventOrderNoteMention,
+    order_message: t.admin.templateEventOrderMessage,
     order_resubmit: t.admin.templateEventOrderResubmit,
     ticket_created: t.admin.templateEventTicketCreated,
     ticket_reply: t.admin.templateEventTicketReply,
@@ -946,6 +1000,19 @@ export default function SettingsPage() {
     : []
   const customHeadersConfigured = Boolean(settingsData?.sms?.custom_headers_configured)
   const captchaSecretConfigured = Boolean(settingsData?.security?.captcha?.secret_key_configured)
+  const savedCaptchaProviderKeys = settingsData?.security?.captcha?.provider_keys || {}
+  const captchaOverrideProviders = Array.from(new Set(Object.values(captchaSceneProviders))).filter(
+    (provider) => provider !== captchaProvider && CAPTCHA_KEY_PROVIDER_LABELS[provider]
+  )
+  const captchaUsesScore =
+    captchaProvider === 'recaptcha_v3' ||
+    Object.values(captchaSceneProviders).includes('recaptcha_v3')
+  const captchaSceneLabels: Record<string, string> = {
+    login: t.admin.loginCaptcha,
+    register: t.admin.registerCaptcha,
+    serial_verify: t.admin.serialVerifyCaptcha,
+    bind: t.admin.bindCaptcha,
+  }
   const resolvedPrimaryColor = primaryColor || DEFAULT_PRIMARY_COLOR
   const resolvedPrimaryColorHex =
     hslTripletToHex(resolvedPrimaryColor) || DEFAULT_PRIMARY_COLOR_HEX
@@ -1035,6 +1102,9 @@ export default function SettingsPage() {
     if (settingsData?.security?.captcha?.provider) {
       setCaptchaProvider(settingsData.security.captcha.provider)
     }
+    if (settingsData?.security?.captcha) {
+      setCaptchaSceneProviders(settingsData.security.captcha.scene_providers || {})
+    }
     if (settingsData?.sms?.provider) {
       setSmsProvider(settingsData.sms.provider)
     }
@@ -1185,6 +1255,10 @@ export default function SettingsPage() {
             <Database className="h-4 w-4 shrink-0" />
             {t.admin.tabAdvanced}
           </TabsTrigger>
+          <TabsTrigger value="history" className="gap-1.5 px-3">
+            <History className="h-4 w-4 shrink-0" />
+            {t.admin.tabHistory}
+          </TabsTrigger>
         </TabsList>
 
         {/* 常规设置 */}
@@ -1537,6 +1611,34 @@ export default function SettingsPage() {
                       }
                     />
                   </div>
+                  <div className="flex items-center justify-between">
+                    <div>
+                      <Label>{t.admin.orderMessageUserNotify}</Label>
+                      <p className="mt-0.5 text-xs text-muted-foreground">
+                        {t.admin.orderMessageUserNotifyDesc}
+                      </p>
+                    </div>
+                    <Switch
+                      checked={emailNotifications.order_message_user || false}
+                      onCheckedChange={(v) =>
+                        setEmailNotifications((prev) => ({ ...prev, order_message_user: v }))
+                      }
+                    />
+                  </div>
+                  <div className="flex items-center justify-between">
+                    <div>
+                      <Label>{t.admin.orderMessageAdminNotify}</Label>
+                      <p className="mt-0.5 text-xs text-muted-foreground">
+                        {t.admin.orderMessageAdminNotifyDesc}
+                      </p>
+                    </div>
+                    <Switch
+                      checked={emailNotifications.order_message_admin || false}
+                      onCheckedChange={(v) =>
+                        setEmailNotifications((prev) => ({ ...prev, order_message_admin: v }))
+                      }
+                    />
+                  </div>
                 </div>
               </div>
 
@@ -2035,6 +2137,10 @@ export default function SettingsPage() {
                           formData.get('allow_guest_product_browse') === 'on',
                         require_email_verification:
                           formData.get('require_email_verification') === 'on',
+                        email_verification_mode:
+                          (formData.get('email_verification_mode') as string) || 'login',
+                        email_verification_grace_days:
+                          parseInt(formData.get('email_verification_grace_days') as string) || 7,
                         allow_email_login: formData.get('allow_email_login') === 'on',
                         allow_password_reset: formData.get('allow_password_reset') === 'on',
                         allow_phone_login: formData.get('allow_phone_login') === 'on',
@@ -2116,6 +2222,55 @@ export default function SettingsPage() {
                     />
                   </div>
 
+                  <div className="grid gap-4 sm:grid-cols-2">
+                    <div>
+                      <Label htmlFor="email_verification_mode">{t.admin.emailVerificationMode}</Label>
+                      <Select
+                        name="email_verification_mode"
+                        defaultValue={
+                          settingsData?.security?.login?.email_verification_mode || 'login'
+                        }
+                      >
+                        <SelectTrigger id="email_verification_mode" className="mt-1.5">
+                          <SelectValue />
+                        </SelectTrigger>
+                        <SelectContent>
+                          <SelectItem value="login">
+                            {t.admin.emailVerificationModeLogin}
+                          </SelectItem>
+                          <SelectItem value="checkout">
+                            {t.admin.emailVerificationModeCheckout}
+                          </SelectItem>
+                          <SelectItem value="grace">
+                            {t.admin.emailVerificationModeGrace}
+                          </SelectItem>
+                        </SelectContent>
+                      </Select>
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.emailVerificationModeHint}
+                      </p>
+                    </div>
+                    <div>
+                      <Label htmlFor="email_verification_grace_days">
+                        {t.admin.emailVerificationGraceDays}
+                      </Label>
+                      <Input
+                        id="email_verification_grace_days"
+                        name="email_verification_grace_days"
+                        type="number"
+                        min="1"
+                        max="365"
+                        defaultValue={
+                          settingsData?.security?.login?.email_verification_grace_days || 7
+                        }
+                        className="mt-1.5"
+                      />
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.emailVerificationGraceDaysHint}
+                      </p>
+                    </div>
+                  </div>
+
                   <div className="flex items-center justify-between">
                     <div>
                       <Label htmlFor="allow_email_login">{t.admin.allowEmailLogin}</Label>
@@ -2300,12 +2455,27 @@ export default function SettingsPage() {
                     e.preventDefault()
                     const formData = new FormData(e.currentTarget)
                     const captchaSecretKey = String(formData.get('captcha_secret_key') || '').trim()
+                    const providerKeys: Record<string, any> = {}
+                    captchaOverrideProviders.forEach((provider) => {
+                      const secretKey = String(
+                        formData.get(`captcha_${provider}_secret_key`) || ''
+                      ).trim()
+                      providerKeys[provider] = {
+                        site_key: formData.get(`captcha_${provider}_site_key`) || '',
+                        secret_key: secretKey,
+                        secret_key_submitted: secretKey !== '',
+                      }
+                    })
                     handleSubmit('security', {
                       captcha: {
                         provider: captchaProvider,
                         site_key: formData.get('captcha_site_key') || '',
                         secret_key: captchaSecretKey,
                         secret_key_submitted: captchaSecretKey !== '',
+                        recaptcha_min_score:
+                          Number(formData.get('captcha_recaptcha_min_score')) || 0.5,
+                        scene_providers: captchaSceneProviders,
+                        provider_keys: providerKeys,
                         enable_for_login: formData.get('captcha_enable_login') === 'on',
                         enable_for_register: formData.get('captcha_enable_register') === 'on',
                         enable_for_serial_verify:
@@ -2325,13 +2495,15 @@ export default function SettingsPage() {
                       <SelectContent>
                         <SelectItem value="none">{t.admin.captchaDisabled}</SelectItem>
                         <SelectItem value="cloudflare">Cloudflare Turnstile</SelectItem>
-                        <SelectItem value="google">Google reCAPTCHA</SelectItem>
+                        <SelectItem value="google">Google reCAPTCHA v2</SelectItem>
+                        <SelectItem value="recaptcha_v3">Google reCAPTCHA v3</SelectItem>
+                        <SelectItem value="hcaptcha">hCaptcha</SelectItem>
                         <SelectItem value="builtin">{t.admin.builtinCaptcha}</SelectItem>
                       </SelectContent>
                     </Select>
                   </div>
 
-                  {(captchaProvider === 'cloudflare' || captchaProvider === 'google') && (
+                  {CAPTCHA_KEY_PROVIDER_LABELS[captchaProvider] && (
                     <div key={captchaProvider} className="space-y-4">
                       <div>
                         <Label htmlFor="captcha_site_key">Site Key</Label>
@@ -2343,11 +2515,7 @@ export default function SettingsPage() {
                               ? ''
                               : settingsData?.security?.captcha?.site_key || ''
                           }
-                          placeholder={
-                            captchaProvider === 'cloudflare'
-                              ? 'Turnstile Site Key'
-                              : 'reCAPTCHA Site Key'
-                          }
+                          placeholder={`${CAPTCHA_KEY_PROVIDER_LABELS[captchaProvider]} Site Key`}
                           className="mt-1.5"
                         />
                       </div>
@@ -2360,9 +2528,7 @@ export default function SettingsPage() {
                           placeholder={
                             !captchaProviderChanged && captchaSecretConfigured
                               ? t.admin.passwordPlaceholder
-                              : captchaProvider === 'cloudflare'
-                                ? 'Turnstile Secret Key'
-                                : 'reCAPTCHA Secret Key'
+                              : `${CAPTCHA_KEY_PROVIDER_LABELS[captchaProvider]} Secret Key`
                           }
                           className="mt-1.5"
                         />
@@ -2437,6 +2603,109 @@ export default function SettingsPage() {
                     </div>
                   )}
 
+                  {captchaProvider !== 'none' && (
+                    <div className="space-y-3">
+                      <div>
+                        <Label>{t.admin.captchaSceneProviders}</Label>
+                        <p className="mt-1 text-xs text-muted-foreground">
+                          {t.admin.captchaSceneProvidersHint}
+                        </p>
+                      </div>
+                      {CAPTCHA_SCENES.map((scene) => (
+                        <div key={scene} className="flex items-center justify-between gap-4">
+                          <span className="text-sm">{captchaSceneLabels[scene]}</span>
+                          <Select
+                            value={captchaSceneProviders[scene] || 'default'}
+                            onValueChange={(value) =>
+                              setCaptchaSceneProviders((prev) => {
+                                const next = { ...prev }
+                                if (value === 'default') {
+                                  delete next[scene]
+                                } else {
+                                  next[scene] = value
+                                }
+                                return next
+                              })
+                            }
+                          >
+                            <SelectTrigger className="w-56">
+                              <SelectValue />
+                            </SelectTrigger>
+                            <SelectContent>
+                              <SelectItem value="default">{t.admin.captchaSceneDefault}</SelectItem>
+                              <SelectItem value="cloudflare">Cloudflare Turnstile</SelectItem>
+                              <SelectItem value="google">Google reCAPTCHA v2</SelectItem>
+                              <SelectItem value="recaptcha_v3">Google reCAPTCHA v3</SelectItem>
+                              <SelectItem value="hcaptcha">hCaptcha</SelectItem>
+                              <SelectItem value="builtin">{t.admin.builtinCaptcha}</SelectItem>
+                            </SelectContent>
+                          </Select>
+                        </div>
+                      ))}
+                    </div>
+                  )}
+
+                  {captchaProvider !== 'none' &&
+                    captchaOverrideProviders.map((provider) => {
+                      const savedKeys = savedCaptchaProviderKeys[provider] || {}
+                      const label = CAPTCHA_KEY_PROVIDER_LABELS[provider]
+                      return (
+                        <div key={provider} className="space-y-4 rounded-md border p-4">
+                          <Label>{t.admin.captchaOverrideKeys.replace('{provider}', label)}</Label>
+                          <div>
+                            <Label htmlFor={`captcha_${provider}_site_key`}>Site Key</Label>
+                            <Input
+                              id={`captcha_${provider}_site_key`}
+                              name={`captcha_${provider}_site_key`}
+                              defaultValue={savedKeys.site_key || ''}
+                              placeholder={`${label} Site Key`}
+                              className="mt-1.5"
+                            />
+                          </div>
+                          <div>
+                            <Label htmlFor={`captcha_${provider}_secret_key`}>Secret Key</Label>
+                            <Input
+                              id={`captcha_${provider}_secret_key`}
+                              name={`captcha_${provider}_secret_key`}
+                              type="password"
+                              placeholder={
+                                savedKeys.secret_key_configured
+                                  ? t.admin.passwordPlaceholder
+                                  : `${label} Secret Key`
+                              }
+                              className="mt-1.5"
+                            />
+                            {savedKeys.secret_key_configured ? (
+                              <p className="mt-1 text-xs text-muted-foreground">
+                                {t.admin.captchaSecretKeepHint}
+                              </p>
+                            ) : null}
+                          </div>
+                        </div>
+                      )
+                    })}
+
+                  {captchaProvider !== 'none' && captchaUsesScore && (
+                    <div>
+                      <Label htmlFor="captcha_recaptcha_min_score">
+                        {t.admin.recaptchaMinScore}
+                      </Label>
+                      <Input
+                        id="captcha_recaptcha_min_score"
+                        name="captcha_recaptcha_min_score"
+                        type="number"
+                        min={0}
+                        max={1}
+                        step={0.1}
+                        defaultValue={settingsData?.security?.captcha?.recaptcha_min_score || 0.5}
+                        className="mt-1.5"
+                      />
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.recaptchaMinScoreHint}
+                      </p>
+                    </div>
+                  )}
+
                   <Button type="submit" disabled={updateMutation.isPending}>
                     <Save className="mr-2 h-4 w-4" />
                     {t.admin.saveSettings}
@@ -3148,6 +3417,50 @@ export default function SettingsPage() {
               </CardContent>
             </Card>
 
+            <Card>
+              <CardHeader>
+                <CardTitle className="flex items-center gap-2">
+                  <ShieldCheck className="h-5 w-5" />
+                  {t.admin.paymentHttpAllowlist}
+                </CardTitle>
+                <CardDescription>{t.admin.paymentHttpAllowlistDesc}</CardDescription>
+              </CardHeader>
+              <CardContent>
+                <form
+                  onSubmit={(e) => {
+                    e.preventDefault()
+                    const formData = new FormData(e.currentTarget)
+                    handleSubmit('security', {
+                      payment_http_strict_allowlist:
+                        formData.get('payment_http_strict_allowlist') === 'on',
+                    })
+                  }}
+                  className="space-y-4"
+                >
+                  <div className="flex items-center justify-between">
+                    <div>
+                      <Label htmlFor="payment_http_strict_allowlist">
+                        {t.admin.paymentHttpStrictAllowlist}
+                      </Label>
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.paymentHttpStrictAllowlistHint}
+                      </p>
+                    </div>
+                    <Switch
+                      id="payment_http_strict_allowlist"
+                      name="payment_http_strict_allowlist"
+                      defaultChecked={settingsData?.security?.payment_http_strict_allowlist}
+                    />
+                  </div>
+
+                  <Button type="submit" disabled={updateMutation.isPending}>
+                    <Save className="mr-2 h-4 w-4" />
+                    {t.admin.saveSettings}
+                  </Button>
+                </form>
+              </CardContent>
+            </Card>
+
             <Card>
               <CardHeader>
                 <CardTitle>{t.admin.redisConfigReadonly}</CardTitle>
@@ -4349,6 +4662,10 @@ export default function SettingsPage() {
                   handleSubmit('order', {
                     no_prefix: formData.get('no_prefix'),
                     auto_cancel_hours: parseInt(formData.get('auto_cancel_hours') as string),
+                    draft_expire_hours:
+                      parseInt(formData.get('draft_expire_hours') as string) || 0,
+                    payment_reminder_hours:
+                      parseInt(formData.get('payment_reminder_hours') as string) || 0,
                     max_pending_payment_orders_per_user:
                       parseInt(formData.get('max_pending_payment_orders_per_user') as string) || 10,
                     max_payment_polling_tasks_per_user:
@@ -4362,6 +4679,12 @@ export default function SettingsPage() {
                     virtual_delivery_order: formData.get('virtual_delivery_order'),
                     virtual_script_timeout_max_ms:
                       parseInt(formData.get('virtual_script_timeout_max_ms') as string) || 10000,
+                    virtual_stock_expiry_warning_days:
+                      parseInt(formData.get('virtual_stock_expiry_warning_days') as string) || 7,
+                    cart_reservation_ttl_seconds:
+                      parseInt(formData.get('cart_reservation_ttl_seconds') as string) || 600,
+                    tier_pricing_promo_stacking:
+                      formData.get('tier_pricing_promo_stacking') || 'stack',
                     show_virtual_stock_remark: showVirtualStockRemark,
                     enable_virtual_stock_inline_iframe: enableVirtualStockInlineIframe,
                     high_concurrency_protection: {
@@ -4373,6 +4696,25 @@ export default function SettingsPage() {
                         parseInt(formData.get('high_concurrency_wait_timeout_ms') as string) || 5000,
                       redis_lease_ms:
                         parseInt(formData.get('high_concurrency_redis_lease_ms') as string) || 30000,
+                      max_inflight_per_user:
+                        parseInt(formData.get('order_max_inflight_per_user') as string) || 0,
+                      max_inflight_per_ip:
+                        parseInt(formData.get('order_max_inflight_per_ip') as string) || 0,
+                      blind_box_max_inflight:
+                        parseInt(formData.get('blind_box_max_inflight') as string) || 0,
+                    },
+                    waiting_room: {
+                      enabled: formData.get('waiting_room_enabled') === 'on',
+                      all_products: formData.get('waiting_room_all_products') === 'on',
+                      admit_per_second:
+                        parseFloat(formData.get('waiting_room_admit_per_second') as string) || 5,
+                      burst: parseInt(formData.get('waiting_room_burst') as string) || 10,
+                      admission_ttl_seconds:
+                        parseInt(formData.get('waiting_room_admission_ttl_seconds') as string) ||
+                        600,
+                      abandon_after_seconds:
+                        parseInt(formData.get('waiting_room_abandon_after_seconds') as string) ||
+                        60,
                     },
                     stock_display: {
                       mode: formData.get('stock_display_mode'),
@@ -4448,6 +4790,36 @@ export default function SettingsPage() {
                   </p>
                 </div>
 
+                <div>
+                  <Label htmlFor="draft_expire_hours">{t.admin.draftExpireHours}</Label>
+                  <Input
+                    id="draft_expire_hours"
+                    name="draft_expire_hours"
+                    type="number"
+                    min={0}
+                    defaultValue={settingsData?.order?.draft_expire_hours ?? 0}
+                    className="mt-1.5"
+                  />
+                  <p className="mt-1 text-xs text-muted-foreground">
+                    {t.admin.draftExpireHoursHint}
+                  </p>
+                </div>
+
+                <div>
+                  <Label htmlFor="payment_reminder_hours">{t.admin.paymentReminderHours}</Label>
+                  <Input
+                    id="payment_reminder_hours"
+                    name="payment_reminder_hours"
+                    type="number"
+                    min={0}
+                    defaultValue={settingsData?.order?.payment_reminder_hours ?? 0}
+                    className="mt-1.5"
+                  />
+                  <p className="mt-1 text-xs text-muted-foreground">
+                    {t.admin.paymentReminderHoursHint}
+                  </p>
+                </div>
+
                 <div className="grid grid-cols-1 gap-4 md:grid-cols-3">
                   <div>
                     <Label htmlFor="max_pending_payment_orders_per_user">
@@ -4637,9 +5009,181 @@ export default function SettingsPage() {
                         {t.admin.highConcurrencyProtectionRedisLeaseHint}
                       </p>
                     </div>
+                    <div>
+                      <Label htmlFor="order_max_inflight_per_user">
+                        {t.admin.highConcurrencyMaxInFlightPerUser}
+                      </Label>
+                      <Input
+                        id="order_max_inflight_per_user"
+                        name="order_max_inflight_per_user"
+                        type="number"
+                        min="0"
+                        defaultValue={
+                          settingsData?.order?.high_concurrency_protection?.max_inflight_per_user ?? 0
+                        }
+                        className="mt-1.5"
+                      />
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.highConcurrencyMaxInFlightPerUserHint}
+                      </p>
+                    </div>
+                    <div>
+                      <Label htmlFor="order_max_inflight_per_ip">
+                        {t.admin.highConcurrencyMaxInFlightPerIp}
+                      </Label>
+                      <Input
+                        id="order_max_inflight_per_ip"
+                        name="order_max_inflight_per_ip"
+                        type="number"
+                        min="0"
+                        defaultValue={
+                          settingsData?.order?.high_concurrency_protection?.max_inflight_per_ip ?? 0
+                        }
+                        className="mt-1.5"
+                      />
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.highConcurrencyMaxInFlightPerIpHint}
+                      </p>
+                    </div>
+                    <div>
+                      <Label htmlFor="blind_box_max_inflight">
+                        {t.admin.highConcurrencyBlindBoxMaxInFlight}
+                      </Label>
+                      <Input
+                        id="blind_box_max_inflight"
+                        name="blind_box_max_inflight"
+                        type="number"
+                        min="0"
+                        defaultValue={
+                          settingsData?.order?.high_concurrency_protection?.blind_box_max_inflight ?? 0
+                        }
+                        className="mt-1.5"
+                      />
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.highConcurrencyBlindBoxMaxInFlightHint}
+                      </p>
+                    </div>
                   </div>
                 </div>
 
+                <div className="mt-4 border-t border-border pt-4">
+                  <h4 className="mb-3 font-medium">{t.admin.waitingRoomSettings}</h4>
+                  <div className="flex items-center justify-between rounded-lg border border-border/70 bg-muted/20 px-4 py-3">
+                    <div>
+                      <Label htmlFor="waiting_room_enabled">{t.admin.waitingRoomEnabled}</Label>
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.waitingRoomEnabledHint}
+                      </p>
+                    </div>
+                    <Switch
+                      id="waiting_room_enabled"
+                      name="waiting_room_enabled"
+                      defaultChecked={settingsData?.order?.waiting_room?.enabled || false}
+                    />
+                  </div>
+                  <div className="mt-4 flex items-center justify-between">
+                    <div>
+                      <Label htmlFor="waiting_room_all_products">
+                        {t.admin.waitingRoomAllProducts}
+                      </Label>
+                      <p className="text-xs text-muted-foreground">
+                        {t.admin.waitingRoomAllProductsHint}
+                      </p>
+                    </div>
+                    <Switch
+                      id="waiting_room_all_products"
+                      name="waiting_room_all_products"
+                      defaultChecked={settingsData?.order?.waiting_room?.all_products || false}
+                    />
+                  </div>
+                  <div className="mt-4 grid grid-cols-1 gap-4 md:grid-cols-2">
+                    <div>
+                      <Label htmlFor="waiting_room_admit_per_second">
+                        {t.admin.waitingRoomAdmitPerSecond}
+                      </Label>
+                      <Input
+                        id="waiting_room_admit_per_second"
+                        name="waiting_room_admit_per_second"
+                        type="number"
+                        min="0.1"
+                        step="0.1"
+                        defaultValue={settingsData?.order?.waiting_room?.admit_per_second || 5}
+                        className="mt-1.5"
+                      />
+                    </div>
+                    <div>
+                      <Label htmlFor="waiting_room_burst">{t.admin.waitingRoomBurst}</Label>
+                      <Input
+                        id="waiting_room_burst"
+                        name="waiting_room_burst"
+                        type="number"
+                        min="1"
+                        defaultValue={settingsData?.order?.waiting_room?.burst || 10}
+                        className="mt-1.5"
+                      />
+                    </div>
+                    <div>
+                      <Label htmlFor="waiting_room_admission_ttl_seconds">
+                        {t.admin.waitingRoomAdmissionTtl}
+                      </Label>
+                      <Input
+                        id="waiting_room_admission_ttl_seconds"
+                        name="waiting_room_admission_ttl_seconds"
+                        type="number"
+                        min="30"
+                        defaultValue={
+                          settingsData?.order?.waiting_room?.admission_ttl_seconds || 600
+                        }
+                        className="mt-1.5"
+                      />
+                    </div>
+                    <div>
+                      <Label htmlFor="waiting_room_abandon_after_seconds">
+                        {t.admin.waitingRoomAbandonAfter}
+                      </Label>
+                      <Input
+                        id="waiting_room_abandon_after_seconds"
+                        name="waiting_room_abandon_after_seconds"
+                        type="number"
+                        min="10"
+                        defaultValue={
+                          settingsData?.order?.waiting_room?.abandon_after_seconds || 60
+                        }
+                        className="mt-1.5"
+                      />
+                    </div>
+                  </div>
+                  {waitingRoomMetrics && (
+                    <div className="mt-4 rounded-lg border border-border/70 px-4 py-3">
+                      <p className="mb-2 text-sm font-medium">{t.admin.waitingRoomMetrics}</p>
+                      <div className="grid grid-cols-2 gap-3 md:grid-cols-6">
+                        {(
+                          [
+                            ['queue_length', t.admin.waitingRoomQueueLength],
+                            ['admitted_active', t.admin.waitingRoomAdmittedActive],
+                            ['joined_total', t.admin.waitingRoomJoinedTotal],
+                            ['admitted_total', t.admin.waitingRoomAdmittedTotal],
+                            ['abandoned_total', t.admin.waitingRoomAbandonedTotal],
+                          ] as const
+                        ).map(([key, label]) => (
+                          <div key={key}>
+                            <p className="text-xs text-muted-foreground">{label}</p>
+                            <p className="text-lg font-semibold">{waitingRoomMetrics[key] ?? 0}</p>
+                          </div>
+                        ))}
+                        <div>
+                          <p className="text-xs text-muted-foreground">
+                            {t.admin.waitingRoomAbandonRate}
+                          </p>
+                          <p className="text-lg font-semibold">
+                            {((waitingRoomMetrics.abandon_rate || 0) * 100).toFixed(1)}%
+                          </p>
+                        </div>
+                      </div>
+                    </div>
+                  )}
+                </div>
+
                 <div className="mt-4 border-t border-border pt-4">
                   <h4 className="mb-3 font-medium">{t.admin.virtualDeliveryOrderTitle}</h4>
                   <div className="grid grid-cols-1 gap-4 md:grid-cols-2">
@@ -4656,6 +5200,9 @@ export default function SettingsPage() {
                           <SelectItem value="random">{t.admin.virtualDeliveryRandom}</SelectItem>
                           <SelectItem value="newest">{t.admin.virtualDeliveryNewest}</SelectItem>
                           <SelectItem value="oldest">{t.admin.virtualDeliveryOldest}</SelectItem>
+                          <SelectItem value="expiring">
+                            {t.admin.virtualDeliveryExpiring}
+                          </SelectItem>
                         </SelectContent>
                       </Select>
                       <p className="mt-1 text-xs text-muted-foreground">
@@ -4678,6 +5225,61 @@ export default function SettingsPage() {
                         {t.admin.virtualScriptTimeoutMaxMsHint}
                       </p>
                     </div>
+                    <div>
+                      <Label htmlFor="virtual_stock_expiry_warning_days">
+                        {t.admin.virtualStockExpiryWarningDays}
+                      </Label>
+                      <Input
+                        id="virtual_stock_expiry_warning_days"
+                        name="virtual_stock_expiry_warning_days"
+                        type="number"
+                        min="1"
+                        defaultValue={settingsData?.order?.virtual_stock_expiry_warning_days || 7}
+                        className="mt-1.5"
+                      />
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.virtualStockExpiryWarningDaysHint}
+                      </p>
+                    </div>
+                    <div>
+                      <Label htmlFor="cart_reservation_ttl_seconds">
+                        {t.admin.cartReservationTtlSeconds}
+                      </Label>
+                      <Input
+                        id="cart_reservation_ttl_seconds"
+                        name="cart_reservation_ttl_seconds"
+                        type="number"
+                        min="30"
+                        defaultValue={settingsData?.order?.cart_reservation_ttl_seconds || 600}
+                        className="mt-1.5"
+                      />
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.cartReservationTtlSecondsHint}
+                      </p>
+                    </div>
+                    <div>
+                      <Label htmlFor="tier_pricing_promo_stacking">
+                        {t.admin.tierPricingPromoStacking}
+                      </Label>
+                      <Select
+                        name="tier_pricing_promo_stacking"
+                        defaultValue={settingsData?.order?.tier_pricing_promo_stacking || 'stack'}
+                      >
+                        <SelectTrigger id="tier_pricing_promo_stacking" className="mt-1.5">
+                          <SelectValue />
+                        </SelectTrigger>
+                        <SelectContent>
+                          <SelectItem value="stack">{t.admin.tierPricingPromoStack}</SelectItem>
+                          <SelectItem value="exclusive">
+                            {t.admin.tierPricingPromoExclusive}
+                          </SelectItem>
+                          <SelectItem value="best">{t.admin.tierPricingPromoBest}</SelectItem>
+                        </SelectContent>
+                      </Select>
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.tierPricingPromoStackingHint}
+                      </p>
+                    </div>
                   </div>
                   <div className="mt-4 flex items-center justify-between">
                     <div>
@@ -5030,6 +5632,7 @@ export default function SettingsPage() {
                   }
                   const formConfigData = {
                     expire_hours: parseInt(formData.get('form_expire_hours') as string),
+                    disabled_countries: disabledCountries,
                   }
 
                   // 分别提交
@@ -5073,6 +5676,363 @@ export default function SettingsPage() {
                   />
                 </div>
 
+                <div>
+                  <Label htmlFor="disabled_countries_filter">{t.admin.disabledCountries}</Label>
+                  <p className="mt-1 text-xs text-muted-foreground">
+                    {t.admin.disabledCountriesDesc}
+                  </p>
+                  <Input
+                    id="disabled_countries_filter"
+                    value={countryFilter}
+                    onChange={(e) => setCountryFilter(e.target.value)}
+                    placeholder={t.admin.disabledCountriesFilterPlaceholder}
+                    className="mt-1.5"
+                  />
+                  {disabledCountries.length > 0 && (
+                    <div className="mt-2 flex flex-wrap gap-1">
+                      {disabledCountries.map((code) => (
+                        <Badge key={code} variant="secondary">
+                          {code}
+                        </Badge>
+                      ))}
+                    </div>
+                  )}
+                  <div className="mt-2 grid max-h-56 grid-cols-2 gap-2 overflow-y-auto rounded-md border p-2 sm:grid-cols-3">
+                    {(countriesData?.data || [])
+                      .filter((country: any) => {
+                        const keyword = countryFilter.trim().toLowerCase()
+                        if (!keyword) return true
+                        return [country.code, country.name_en, country.name_zh].some((value) =>
+                          String(value || '').toLowerCase().includes(keyword)
+                        )
+                      })
+                      .map((country: any) => (
+                        <label key={country.code} className="flex items-center gap-2 text-sm">
+                          <Checkbox
+                            checked={disabledCountries.includes(country.code)}
+                            onCheckedChange={(checked) =>
+                              setDisabledCountries((prev) =>
+                                checked
+                                  ? [...prev, country.code]
+                                  : prev.filter((code) => code !== country.code)
+                              )
+                            }
+                          />
+                          <span>
+                            {locale === 'zh' ? country.name_zh : country.name_en} ({country.code})
+                          </span>
+                        </label>
+                      ))}
+                  </div>
+                </div>
+
+                <Button type="submit" disabled={updateMutation.isPending}>
+                  <Save className="mr-2 h-4 w-4" />
+                  {t.admin.saveSettings}
+                </Button>
+              </form>
+            </CardContent>
+          </Card>
+
+          <Card className="mt-4">
+            <CardHeader>
+              <CardTitle>{t.admin.exchangeRateSettings}</CardTitle>
+              <CardDescription>{t.admin.exchangeRateSettingsDesc}</CardDescription>
+            </CardHeader>
+            <CardContent className="space-y-6">
+              <form
+                onSubmit={(e) => {
+                  e.preventDefault()
+                  const formData = new FormData(e.currentTarget)
+                  handleSubmit('exchange_rate', {
+                    _submitted: true,
+                    enabled: formData.get('exchange_rate_enabled') === 'on',
+                    fiat_provider: 'exchangerate_host',
+                    fiat_api_key: formData.get('exchange_rate_fiat_api_key') || '',
+                    crypto_provider: 'coingecko',
+                    crypto_api_key: formData.get('exchange_rate_crypto_api_key') || '',
+                    cache_minutes:
+                      parseInt(formData.get('exchange_rate_cache_minutes') as string) || 10,
+                    stale_alert_minutes:
+                      parseInt(formData.get('exchange_rate_stale_alert_minutes') as string) || 60,
+                    display_currencies: String(
+                      formData.get('exchange_rate_display_currencies') || ''
+                    )
+                      .split(/[\s,]+/)
+                      .map((code) => code.trim().toUpperCase())
+                      .filter(Boolean),
+                  })
+                }}
+                className="space-y-4"
+              >
+                <div className="flex items-center justify-between">
+                  <div>
+                    <Label htmlFor="exchange_rate_enabled">{t.admin.exchangeRateEnabled}</Label>
+                    <p className="mt-1 text-xs text-muted-foreground">
+                      {t.admin.exchangeRateEnabledHint}
+                    </p>
+                  </div>
+                  <Switch
+                    id="exchange_rate_enabled"
+                    name="exchange_rate_enabled"
+                    defaultChecked={settingsData?.exchange_rate?.enabled}
+                  />
+                </div>
+
+                <div className="grid gap-4 md:grid-cols-2">
+                  <div>
+                    <Label htmlFor="exchange_rate_fiat_api_key">
+                      {t.admin.exchangeRateFiatApiKey}
+                    </Label>
+                    <Input
+                      id="exchange_rate_fiat_api_key"
+                      name="exchange_rate_fiat_api_key"
+                      type="password"
+                      placeholder={
+                        settingsData?.exchange_rate?.fiat_api_key_configured
+                          ? t.admin.passwordPlaceholder
+                          : 'exchangerate.host access_key'
+                      }
+                      className="mt-1.5"
+                    />
+                  </div>
+                  <div>
+                    <Label htmlFor="exchange_rate_crypto_api_key">
+                      {t.admin.exchangeRateCryptoApiKey}
+                    </Label>
+                    <Input
+                      id="exchange_rate_crypto_api_key"
+                      name="exchange_rate_crypto_api_key"
+                      type="password"
+                      placeholder={
+                        settingsData?.exchange_rate?.crypto_api_key_configured
+                          ? t.admin.passwordPlaceholder
+                          : 'CoinGecko Demo API Key'
+                      }
+                      className="mt-1.5"
+                    />
+                  </div>
+                  <div>
+                    <Label htmlFor="exchange_rate_cache_minutes">
+                      {t.admin.exchangeRateCacheMinutes}
+                    </Label>
+                    <Input
+                      id="exchange_rate_cache_minutes"
+                      name="exchange_rate_cache_minutes"
+                      type="number"
+                      min={1}
+                      defaultValue={settingsData?.exchange_rate?.cache_minutes || 10}
+                      className="mt-1.5"
+                    />
+                  </div>
+                  <div>
+                    <Label htmlFor="exchange_rate_stale_alert_minutes">
+                      {t.admin.exchangeRateStaleAlertMinutes}
+                    </Label>
+                    <Input
+                      id="exchange_rate_stale_alert_minutes"
+                      name="exchange_rate_stale_alert_minutes"
+                      type="number"
+                      min={1}
+                      defaultValue={settingsData?.exchange_rate?.stale_alert_minutes || 60}
+                      className="mt-1.5"
+                    />
+                  </div>
+                </div>
+                <p className="text-xs text-muted-foreground">
+                  {t.admin.exchangeRateApiKeyKeepHint}
+                </p>
+
+                <div>
+                  <Label htmlFor="exchange_rate_display_currencies">
+                    {t.admin.exchangeRateDisplayCurrencies}
+                  </Label>
+                  <Input
+                    id="exchange_rate_display_currencies"
+                    name="exchange_rate_display_currencies"
+                    defaultValue={(settingsData?.exchange_rate?.display_currencies || []).join(
+                      ', '
+                    )}
+                    placeholder="USD, EUR"
+                    className="mt-1.5"
+                  />
+                  <p className="mt-1 text-xs text-muted-foreground">
+                    {t.admin.exchangeRateDisplayCurrenciesHint}
+                  </p>
+                </div>
+
+                <Button type="submit" disabled={updateMutation.isPending}>
+                  <Save className="mr-2 h-4 w-4" />
+                  {t.admin.saveSettings}
+                </Button>
+              </form>
+
+              {exchangeRates.length > 0 && (
+                <div className="overflow-x-auto rounded-md border">
+                  <table className="w-full text-sm">
+                    <thead className="bg-muted/50 text-left">
+                      <tr>
+                        <th className="p-2">{t.admin.exchangeRatePair}</th>
+                        <th className="p-2">{t.admin.exchangeRateRate}</th>
+                        <th className="p-2">{t.admin.exchangeRateProvider}</th>
+                        <th className="p-2">{t.admin.exchangeRateUpdatedAt}</th>
+                      </tr>
+                    </thead>
+                    <tbody>
+                      {exchangeRates.map((quote) => (
+                        <tr key={`${quote.from}/${quote.to}`} className="border-t">
+                          <td className="p-2 font-mono">
+                            {quote.from}/{quote.to}
+                          </td>
+                          <td className="p-2 font-mono">{quote.rate}</td>
+                          <td className="p-2">{quote.provider}</td>
+                          <td className="p-2">
+                            <div className="flex items-center gap-2">
+                              {new Date(quote.updated_at).toLocaleString(locale)}
+                              {quote.stale && (
+                                <Badge variant="destructive">{t.admin.exchangeRateStale}</Badge>
+                              )}
+                            </div>
+                            {quote.last_error && (
+                              <p className="text-xs text-destructive">{quote.last_error}</p>
+                            )}
+                          </td>
+                        </tr>
+                      ))}
+                    </tbody>
+                  </table>
+                </div>
+              )}
+            </CardContent>
+          </Card>
+
+          <Card className="mt-4">
+            <CardHeader>
+              <CardTitle>{t.admin.shipmentTrackingSettings}</CardTitle>
+              <CardDescription>{t.admin.shipmentTrackingSettingsDesc}</CardDescription>
+            </CardHeader>
+            <CardContent>
+              <form
+                onSubmit={(e) => {
+                  e.preventDefault()
+                  const formData = new FormData(e.currentTarget)
+                  handleSubmit('shipment_tracking', {
+                    _submitted: true,
+                    enabled: formData.get('shipment_tracking_enabled') === 'on',
+                    provider: formData.get('shipment_tracking_provider') || '17track',
+                    api_key: formData.get('shipment_tracking_api_key') || '',
+                    webhook_secret: formData.get('shipment_tracking_webhook_secret') || '',
+                    poll_interval_minutes:
+                      parseInt(formData.get('shipment_tracking_poll_interval') as string) || 360,
+                    auto_complete_days:
+                      parseInt(formData.get('shipment_tracking_auto_complete_days') as string) || 0,
+                  })
+                }}
+                className="space-y-4"
+              >
+                <div className="flex items-center justify-between">
+                  <Label htmlFor="shipment_tracking_enabled">
+                    {t.admin.shipmentTrackingEnabled}
+                  </Label>
+                  <Switch
+                    id="shipment_tracking_enabled"
+                    name="shipment_tracking_enabled"
+                    defaultChecked={settingsData?.shipment_tracking?.enabled}
+                  />
+                </div>
+
+                <div className="grid gap-4 md:grid-cols-2">
+                  <div>
+                    <Label htmlFor="shipment_tracking_provider">
+                      {t.admin.shipmentTrackingProvider}
+                    </Label>
+                    <Select
+                      name="shipment_tracking_provider"
+                      defaultValue={settingsData?.shipment_tracking?.provider || '17track'}
+                    >
+                      <SelectTrigger id="shipment_tracking_provider" className="mt-1.5">
+                        <SelectValue />
+                      </SelectTrigger>
+                      <SelectContent>
+                        <SelectItem value="17track">17TRACK</SelectItem>
+                        <SelectItem value="aftership">AfterShip</SelectItem>
+                      </SelectContent>
+                    </Select>
+                  </div>
+                  <div>
+                    <Label htmlFor="shipment_tracking_api_key">
+                      {t.admin.shipmentTrackingApiKey}
+                    </Label>
+                    <Input
+                      id="shipment_tracking_api_key"
+                      name="shipment_tracking_api_key"
+                      type="password"
+                      placeholder={
+                        settingsData?.shipment_tracking?.api_key_configured
+                          ? t.admin.passwordPlaceholder
+                          : ''
+                      }
+                      className="mt-1.5"
+                    />
+                  </div>
+                  <div>
+                    <Label htmlFor="shipment_tracking_webhook_secret">
+                      {t.admin.shipmentTrackingWebhookSecret}
+                    </Label>
+                    <Input
+                      id="shipment_tracking_webhook_secret"
+                      name="shipment_tracking_webhook_secret"
+                      type="password"
+                      placeholder={
+                        settingsData?.shipment_tracking?.webhook_secret_configured
+                          ? t.admin.passwordPlaceholder
+                          : ''
+                      }
+                      className="mt-1.5"
+                    />
+                    <p className="mt-1 text-xs text-muted-foreground">
+                      {t.admin.shipmentTrackingWebhookSecretHint}
+                    </p>
+                  </div>
+                  <div>
+                    <Label htmlFor="shipment_tracking_poll_interval">
+                      {t.admin.shipmentTrackingPollInterval}
+                    </Label>
+                    <Input
+                      id="shipment_tracking_poll_interval"
+                      name="shipment_tracking_poll_interval"
+                      type="number"
+                      min={15}
+                      defaultValue={settingsData?.shipment_tracking?.poll_interval_minutes || 360}
+                      className="mt-1.5"
+                    />
+                  </div>
+                  <div>
+                    <Label htmlFor="shipment_tracking_auto_complete_days">
+                      {t.admin.shipmentTrackingAutoCompleteDays}
+                    </Label>
+                    <Input
+                      id="shipment_tracking_auto_complete_days"
+                      name="shipment_tracking_auto_complete_days"
+                      type="number"
+                      min={0}
+                      defaultValue={settingsData?.shipment_tracking?.auto_complete_days || 0}
+                      className="mt-1.5"
+                    />
+                    <p className="mt-1 text-xs text-muted-foreground">
+                      {t.admin.shipmentTrackingAutoCompleteDaysHint}
+                    </p>
+                  </div>
+                </div>
+                <p className="break-all text-xs text-muted-foreground">
+                  {t.admin.shipmentTrackingWebhookUrlHint.replace(
+                    '{url}',
+                    `${settingsData?.app?.url || ''}/api/tracking/webhook/${
+                      settingsData?.shipment_tracking?.provider || '17track'
+                    }`
+                  )}
+                </p>
+
                 <Button type="submit" disabled={updateMutation.isPending}>
                   <Save className="mr-2 h-4 w-4" />
                   {t.admin.saveSettings}
@@ -5711,6 +6671,14 @@ export default function SettingsPage() {
             </Card>
           </div>
         </TabsContent>
+
+        {/* 配置导入导出与变更历史 */}
+        <TabsContent value="history">
+          <div className="space-y-6">
+            <SettingsBundlePanel />
+            <SettingsRevisionsPanel enabled={activeTab === 'history'} />
+          </div>
+        </TabsContent>
       </Tabs>
 
       {/* 重要提示 */}
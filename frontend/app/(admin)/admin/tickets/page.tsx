@@ -10,6 +10,7 @@ import {
   useCallback,
 } from 'react'
 import { useQuery, useInfiniteQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import { useSearchParams } from 'next/navigation'
 import {
   getAdminTickets,
   getAdminTicket,
@@ -23,6 +24,7 @@ import {
   getPublicConfig,
   Ticket,
   TicketMessage,
+  TicketTagCount,
 } from '@/lib/api'
 import { Button } from '@/components/ui/button'
 import { Input } from '@/components/ui/input'
@@ -46,6 +48,10 @@ import {
   MapPin,
   Truck,
   MessageSquare,
+  KeyRound,
+  CreditCard,
+  History,
+  X,
 } from 'lucide-react'
 import { useToast } from '@/hooks/use-toast'
 import { TICKET_STATUS_CONFIG, TICKET_PRIORITY_CONFIG } from '@/lib/constants'
@@ -85,6 +91,7 @@ function buildAdminTicketSummary(ticket: Ticket | null | undefined) {
     content: ticket.content,
     category: ticket.category,
     priority: ticket.priority,
+    tags: ticket.tags,
     status: ticket.status,
     assigned_to: ticket.assigned_to,
     unread_count_user: ticket.unread_count_user,
@@ -134,6 +141,8 @@ function AdminTicketsPageContent() {
   const [status, setStatus] = useState('')
   const [search, setSearch] = useState('')
   const [assignedTo, setAssignedTo] = useState('')
+  const [tagFilter, setTagFilter] = useState('')
+  const [newTag, setNewTag] = useState('')
   const [message, setMessage] = useState('')
   const [viewingOrderId, setViewingOrderId] = useState<number | null>(null)
   const messagesEndRef = useRef<HTMLDivElement>(null)
@@ -146,7 +155,24 @@ function AdminTicketsPageContent() {
   usePageTitle(t.pageTitle.adminTickets)
   const resolveTicketError = (error: unknown, fallback: string) =>
     resolveApiErrorMessage(error, t, fallback)
+  const shipmentActionLabels: Record<string, string> = {
+    assign_tracking: t.ticket.shipmentActionAssignTracking,
+    shipped: t.ticket.shipmentActionShipped,
+    deliver_virtual_stock: t.ticket.shipmentActionDeliverVirtualStock,
+    revoke_virtual_stock: t.ticket.shipmentActionRevokeVirtualStock,
+    complete: t.ticket.shipmentActionComplete,
+    cancel: t.ticket.shipmentActionCancel,
+    receive_return: t.ticket.shipmentActionReceiveReturn,
+  }
   const deferredSearch = useDeferredValue(search)
+  const deferredTagFilter = useDeferredValue(tagFilter.trim().toLowerCase())
+  const searchParams = useSearchParams()
+  const linkedTicketId = Number(searchParams.get('ticket')) || 0
+
+  // 全局搜索等入口通过 ?ticket=<id> 直接打开工单
+  useEffect(() => {
+    if (linkedTicketId > 0) setSelectedTicketId(linkedTicketId)
+  }, [linkedTicketId])
 
   // 获取工单列表 - 无状态筛选时: 自动加载所有非关闭工单 + 分页加载关闭工单
   const {
@@ -156,7 +182,7 @@ function AdminTicketsPageContent() {
     isFetchingNextPage: loadingMoreNonClosed,
     isLoading: nonClosedInitialLoading,
   } = useInfiniteQuery({
-    queryKey: ['adminTickets', 'nonClosed', deferredSearch, assignedTo],
+    queryKey: ['adminTickets', 'nonClosed', deferredSearch, assignedTo, deferredTagFilter],
     queryFn: ({ pageParam }) =>
       getAdminTickets({
         exclude_status: 'closed',
@@ -164,6 +190,7 @@ function AdminTicketsPageContent() {
         limit: 100,
         search: deferredSearch || undefined,
         assigned_to: assignedTo || undefined,
+        tag: deferredTagFilter || undefined,
       }),
     getNextPageParam: (lastPage: any) => {
       const pagination = lastPage?.data?.pagination
@@ -190,7 +217,7 @@ function AdminTicketsPageContent() {
     isFetchingNextPage: loadingMoreClosed,
     isLoading: closedInitialLoading,
   } = useInfiniteQuery({
-    queryKey: ['adminTickets', 'closed', deferredSearch, assignedTo],
+    queryKey: ['adminTickets', 'closed', deferredSearch, assignedTo, deferredTagFilter],
     queryFn: ({ pageParam }) =>
       getAdminTickets({
         status: 'closed',
@@ -198,6 +225,7 @@ function AdminTicketsPageContent() {
         limit: 20,
         search: deferredSearch || undefined,
         assigned_to: assignedTo || undefined,
+        tag: deferredTagFilter || undefined,
       }),
     getNextPageParam: (lastPage: any) => {
       const pagination = lastPage?.data?.pagination
@@ -215,7 +243,14 @@ function AdminTicketsPageContent() {
     isFetchingNextPage: loadingMoreFiltered,
     isLoading: filteredInitialLoading,
   } = useInfiniteQuery({
-    queryKey: ['adminTickets', 'filtered', status, deferredSearch, assignedTo],
+    queryKey: [
+      'adminTickets',
+      'filtered',
+      status,
+      deferredSearch,
+      assignedTo,
+      deferredTagFilter,
+    ],
     queryFn: ({ pageParam }) =>
       getAdminTickets({
         status: status || undefined,
@@ -223,6 +258,7 @@ function AdminTicketsPageContent() {
         limit: 20,
         search: deferredSearch || undefined,
         assigned_to: assignedTo || undefined,
+        tag: deferredTagFilter || undefined,
       }),
     getNextPageParam: (lastPage: any) => {
       const pagination = lastPage?.data?.pagination
@@ -296,9 +332,10 @@ function AdminTicketsPageContent() {
 
   // 更新工单
   const updateTicketMutation = useMutation({
-    mutationFn: (data: { status?: string; priority?: string }) =>
+    mutationFn: (data: { status?: string; priority?: string; tags?: string[] }) =>
       updateAdminTicket(selectedTicketId!, data),
     onSuccess: () => {
+      setNewTag('')
       toast.success(t.ticket.updateSuccess)
       queryClient.invalidateQueries({ queryKey: ['adminTicket', selectedTicketId] })
       queryClient.invalidateQueries({ queryKey: ['adminTickets'] })
@@ -356,12 +393,28 @@ function AdminTicketsPageContent() {
       }`
     )
   }
+  if (deferredTagFilter) {
+    activeTicketFilters.push(`${t.ticket.tags}: ${deferredTagFilter}`)
+  }
+  const selectedTicketTags: string[] = selectedTicket?.tags || []
+  const addSelectedTicketTag = () => {
+    const tag = newTag.trim().toLowerCase()
+    if (!tag || selectedTicketTags.includes(tag)) {
+      setNewTag('')
+      return
+    }
+    updateTicketMutation.mutate({ tags: [...selectedTicketTags, tag] })
+  }
+  const removeSelectedTicketTag = (tag: string) => {
+    updateTicketMutation.mutate({ tags: selectedTicketTags.filter((item) => item !== tag) })
+  }
   const adminTicketsPluginContext = {
     view: 'admin_tickets',
     filters: {
       search: deferredSearch || undefined,
       status: status || undefined,
       assigned_to: assignedTo || undefined,
+      tag: deferredTagFilter || undefined,
     },
     selection: {
       selected_ticket_id: selectedTicketId || undefined,
@@ -566,6 +619,17 @@ function AdminTicketsPageContent() {
             <span className="text-red-600">
               {t.ticket.unread}: <strong>{stats.unread}</strong>
             </span>
+            {(stats.tags || []).slice(0, 8).map((item: TicketTagCount) => (
+              <button
+                key={item.tag}
+                type="button"
+                onClick={() => setTagFilter(deferredTagFilter === item.tag ? '' : item.tag)}
+              >
+                <Badge variant={deferredTagFilter === item.tag ? 'default' : 'outline'}>
+                  #{item.tag} · {item.count}
+                </Badge>
+              </button>
+            ))}
           </div>
         )}
       </div>
@@ -616,6 +680,11 @@ function AdminTicketsPageContent() {
                 </SelectContent>
               </Select>
             </div>
+            <Input
+              placeholder={t.ticket.tagFilterPlaceholder}
+              value={tagFilter}
+              onChange={(e) => setTagFilter(e.target.value)}
+            />
             {activeTicketFilters.length > 0 ? (
               <p className="text-xs text-muted-foreground">{activeTicketFilters.join(' · ')}</p>
             ) : null}
@@ -667,6 +736,15 @@ function AdminTicketsPageContent() {
                         <p className="mt-1 truncate text-xs text-muted-foreground">
                           {ticket.last_message_preview}
                         </p>
+                        {ticket.tags && ticket.tags.length > 0 && (
+                          <div className="mt-1 flex flex-wrap gap-1">
+                            {ticket.tags.map((tag) => (
+                              <Badge key={tag} variant="outline" className="h-5 text-xs">
+                                #{tag}
+                              </Badge>
+                            ))}
+                          </div>
+                        )}
                         <div className="mt-1 flex items-center gap-2">
                           <span className="flex items-center gap-1 text-xs text-muted-foreground">
                             <User className="h-3 w-3" />
@@ -766,6 +844,36 @@ function AdminTicketsPageContent() {
                       .filter(Boolean)
                       .join(' · ')}
                   </p>
+                  {/* 标签 */}
+                  <div className="mt-1.5 flex flex-wrap items-center gap-1.5">
+                    <span className="text-xs text-muted-foreground">{t.ticket.tags}:</span>
+                    {selectedTicketTags.map((tag) => (
+                      <Badge key={tag} variant="secondary" className="gap-1 text-xs">
+                        #{tag}
+                        <button
+                          type="button"
+                          aria-label={t.ticket.removeTag}
+                          onClick={() => removeSelectedTicketTag(tag)}
+                          disabled={updateTicketMutation.isPending}
+                        >
+                          <X className="h-3 w-3" />
+                        </button>
+                      </Badge>
+                    ))}
+                    <Input
+                      value={newTag}
+                      onChange={(e) => setNewTag(e.target.value)}
+                      onKeyDown={(e) => {
+                        if (e.key === 'Enter') {
+                          e.preventDefault()
+                          addSelectedTicketTag()
+                        }
+                      }}
+                      placeholder={t.ticket.addTagPlaceholder}
+                      className="h-6 w-32 text-xs"
+                      disabled={updateTicketMutation.isPending}
+                    />
+                  </div>
                   {/* 分享的订单 */}
                   {sharedOrders.length > 0 && (
                     <div className="mt-1.5 flex flex-wrap items-center gap-1.5">
@@ -994,6 +1102,9 @@ function AdminTicketsPageContent() {
           ) : sharedOrderDetailData?.data?.order ? (
             (() => {
               const order = sharedOrderDetailData.data.order
+              const virtualStocks: any[] = sharedOrderDetailData.data.virtual_stocks || []
+              const payment = sharedOrderDetailData.data.payment
+              const shipmentHistory: any[] = sharedOrderDetailData.data.shipment_history || []
               return (
                 <div className="space-y-4">
                   {/* 订单基本信息 */}
@@ -1173,6 +1284,137 @@ function AdminTicketsPageContent() {
                     </Card>
                   )}
 
+                  {/* 已发货卡密（脱敏） */}
+                  {virtualStocks.length > 0 && (
+                    <Card>
+                      <CardHeader className="py-3">
+                        <CardTitle className="flex items-center gap-2 text-base">
+                          <KeyRound className="h-4 w-4" />
+                          {t.ticket.virtualItems}
+                        </CardTitle>
+                      </CardHeader>
+                      <CardContent className="space-y-2 py-2">
+                        <p className="text-xs text-muted-foreground">
+                          {t.ticket.virtualItemsMaskedHint}
+                        </p>
+                        {virtualStocks.map((stock) => (
+                          <div key={stock.id} className="space-y-1 rounded border p-2 text-sm">
+                            <div className="flex flex-wrap items-center gap-2">
+                              <code
+                                className={cn(
+                                  'break-all font-mono',
+                                  stock.status === 'revoked' && 'line-through'
+                                )}
+                              >
+                                {stock.content}
+                              </code>
+                              {stock.status === 'revoked' && (
+                                <Badge variant="destructive">{t.ticket.virtualItemRevoked}</Badge>
+                              )}
+                            </div>
+                            <div className="text-xs text-muted-foreground">
+                              {stock.delivered_at && `${formatDate(stock.delivered_at)} · `}
+                              {stock.first_viewed_at
+                                ? `${t.ticket.virtualItemFirstViewed}: ${formatDate(stock.first_viewed_at)}`
+                                : t.ticket.virtualItemNotViewed}
+                            </div>
+                            {stock.revoke_reason && (
+                              <p className="text-xs text-muted-foreground">{stock.revoke_reason}</p>
+                            )}
+                          </div>
+                        ))}
+                      </CardContent>
+                    </Card>
+                  )}
+
+                  {/* 付款信息 */}
+                  {payment && (
+                    <Card>
+                      <CardHeader className="py-3">
+                        <CardTitle className="flex items-center gap-2 text-base">
+                          <CreditCard className="h-4 w-4" />
+                          {t.ticket.paymentInfo}
+                        </CardTitle>
+                      </CardHeader>
+                      <CardContent className="py-2">
+                        <dl className="space-y-2 text-sm">
+                          {payment.method_name && (
+                            <div className="flex">
+                              <dt className="w-20 shrink-0 text-muted-foreground">
+                                {t.ticket.paymentMethod}
+                              </dt>
+                              <dd>{payment.method_name}</dd>
+                            </div>
+                          )}
+                          {payment.selected_at && (
+                            <div className="flex">
+                              <dt className="w-20 shrink-0 text-muted-foreground">
+                                {t.ticket.paymentSelectedAt}
+                              </dt>
+                              <dd>{formatDate(payment.selected_at)}</dd>
+                            </div>
+                          )}
+                          {payment.payment_data && (
+                            <div className="flex">
+                              <dt className="w-20 shrink-0 text-muted-foreground">
+                                {t.ticket.paymentData}
+                              </dt>
+                              <dd className="break-all font-mono text-xs">
+                                {payment.payment_data}
+                              </dd>
+                            </div>
+                          )}
+                        </dl>
+                        {payment.records?.length > 0 && (
+                          <div className="mt-3 space-y-1 border-t pt-2 text-sm">
+                            {payment.records.map((record: any, index: number) => (
+                              <div key={index} className="flex justify-between gap-2">
+                                <span>
+                                  {record.kind === 'refund'
+                                    ? t.ticket.paymentRecordRefund
+                                    : t.ticket.paymentRecordPayment}
+                                  <span className="ml-2 text-xs text-muted-foreground">
+                                    {formatDate(record.created_at)}
+                                  </span>
+                                </span>
+                                <span className="font-medium">
+                                  {formatCurrency(record.amount_minor, record.currency)}
+                                </span>
+                              </div>
+                            ))}
+                          </div>
+                        )}
+                      </CardContent>
+                    </Card>
+                  )}
+
+                  {/* 发货记录 */}
+                  {shipmentHistory.length > 0 && (
+                    <Card>
+                      <CardHeader className="py-3">
+                        <CardTitle className="flex items-center gap-2 text-base">
+                          <History className="h-4 w-4" />
+                          {t.ticket.shipmentHistory}
+                        </CardTitle>
+                      </CardHeader>
+                      <CardContent className="space-y-2 py-2 text-sm">
+                        {shipmentHistory.map((event, index) => (
+                          <div key={index} className="flex justify-between gap-2">
+                            <span>
+                              {shipmentActionLabels[event.action] || event.action}
+                              {event.tracking_no && (
+                                <span className="ml-2 font-mono text-xs">{event.tracking_no}</span>
+                              )}
+                            </span>
+                            <span className="shrink-0 text-xs text-muted-foreground">
+                              {formatDate(event.created_at)}
+                            </span>
+                          </div>
+                        ))}
+                      </CardContent>
+                    </Card>
+                  )}
+
                   {/* 备注 */}
                   {order.remark && (
                     <Card>
@@ -55,6 +55,7 @@ import {
   SelectValue,
 } from '@/components/ui/select'
 import { Checkbox } from '@/components/ui/checkbox'
+import { Switch } from '@/components/ui/switch'
 import { Textarea } from '@/components/ui/textarea'
 import {
   Search,
@@ -1369,6 +1370,13 @@ export default function AdminUsersPage() {
                 if (pwd) data.password = pwd
                 if (editingUser.role !== 'user') {
                   data.permissions = editingPermissions
+                } else {
+                  const email = ((formData.get('email') as string) || '').trim()
+                  if (email && email !== editingUser.email) data.email = email
+                  data.email_verified = formData.get('email_verified') === 'on'
+                  data.email_verification_exempt =
+                    formData.get('email_verification_exempt') === 'on'
+                  data.customer_tier = ((formData.get('customer_tier') as string) || '').trim()
                 }
                 handleUpdate(data)
               }}
@@ -1376,9 +1384,66 @@ export default function AdminUsersPage() {
             >
               <div>
                 <label className="text-sm font-medium">{t.admin.email}</label>
-                <Input value={editingUser.email} disabled className="mt-1.5" />
+                {editingUser.role === 'user' ? (
+                  <>
+                    <Input
+                      name="email"
+                      type="email"
+                      defaultValue={editingUser.email}
+                      className="mt-1.5"
+                    />
+                    <p className="mt-1 text-xs text-muted-foreground">
+                      {t.admin.emailChangeReverifyHint}
+                    </p>
+                  </>
+                ) : (
+                  <Input value={editingUser.email} disabled className="mt-1.5" />
+                )}
               </div>
 
+              {editingUser.role === 'user' && (
+                <div className="space-y-3 rounded-md border p-3">
+                  <div className="flex items-center justify-between">
+                    <label htmlFor="email_verified" className="text-sm font-medium">
+                      {t.admin.emailVerified}
+                    </label>
+                    <Switch
+                      id="email_verified"
+                      name="email_verified"
+                      defaultChecked={Boolean(editingUser.email_verified)}
+                    />
+                  </div>
+                  <div className="flex items-center justify-between gap-4">
+                    <div>
+                      <label htmlFor="email_verification_exempt" className="text-sm font-medium">
+                        {t.admin.emailVerificationExempt}
+                      </label>
+                      <p className="mt-1 text-xs text-muted-foreground">
+                        {t.admin.emailVerificationExemptHint}
+                      </p>
+                    </div>
+                    <Switch
+                      id="email_verification_exempt"
+                      name="email_verification_exempt"
+                      defaultChecked={Boolean(editingUser.email_verification_exempt)}
+                    />
+                  </div>
+                  <div>
+                    <label htmlFor="customer_tier" className="text-sm font-medium">
+                      {t.admin.customerTier}
+                    </label>
+                    <Input
+                      id="customer_tier"
+                      name="customer_tier"
+                      defaultValue={editingUser.customer_tier || ''}
+                      maxLength={50}
+                      className="mt-1.5"
+                    />
+                    <p className="mt-1 text-xs text-muted-foreground">{t.admin.customerTierHint}</p>
+                  </div>
+                </div>
+              )}
+
               <div>
                 <label className="text-sm font-medium">{t.admin.name}</label>
                 <Input name="name" defaultValue={editingUser.name} className="mt-1.5" required />
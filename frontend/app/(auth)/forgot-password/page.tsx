@@ -8,6 +8,14 @@ import { Input } from '@/components/ui/input'
 import { useLocale } from '@/hooks/use-locale'
 import { usePageTitle } from '@/hooks/use-page-title'
 import { getTranslations } from '@/lib/i18n'
+import {
+  isInteractiveCaptchaProvider,
+  isWidgetCaptchaProvider,
+  loadCaptchaScript,
+  renderCaptchaWidget,
+  resetCaptchaWidget,
+  resolveCaptchaScene,
+} from '@/lib/captcha'
 import { Loader2, Mail, ArrowLeft, Phone, Lock, KeyRound, Eye, EyeOff } from 'lucide-react'
 import Link from 'next/link'
 import { useQuery } from '@tanstack/react-query'
@@ -80,7 +88,7 @@ export default function ForgotPasswordPage() {
     }
   }, [publicConfig, allowPasswordReset, phoneResetAvailable])
 
-  const captchaConfig = publicConfig?.data?.captcha
+  const captchaConfig = resolveCaptchaScene(publicConfig?.data?.captcha, 'login')
   const needCaptcha =
     captchaConfig?.provider && captchaConfig.provider !== 'none' && captchaConfig.enable_for_login
 
@@ -126,76 +134,27 @@ export default function ForgotPasswordPage() {
     return () => clearTimeout(timer)
   }, [phoneCountdown])
 
-  // Load third-party captcha scripts
-  useEffect(() => {
-    if (!needCaptcha) return
-    if (
-      captchaConfig.provider === 'cloudflare' &&
-      !document.getElementById('cf-turnstile-script')
-    ) {
-      const script = document.createElement('script')
-      script.id = 'cf-turnstile-script'
-      script.src = 'https://challenges.cloudflare.com/turnstile/v0/api.js?onload=onTurnstileLoad'
-      script.async = true
-      ;(window as any).onTurnstileLoad = () => {
-        if (captchaContainerRef.current && !widgetRendered.current) {
-          widgetRendered.current = true
-          widgetIdRef.current = (window as any).turnstile.render(captchaContainerRef.current, {
-            sitekey: captchaConfig.site_key,
-            theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-            callback: (token: string) => setCaptchaToken(token),
-            'expired-callback': () => setCaptchaToken(''),
-          })
-        }
-      }
-      document.head.appendChild(script)
-    } else if (
-      captchaConfig.provider === 'google' &&
-      !document.getElementById('recaptcha-script')
-    ) {
-      const script = document.createElement('script')
-      script.id = 'recaptcha-script'
-      script.src = 'https://www.google.com/recaptcha/api.js?onload=onRecaptchaLoad&render=explicit'
-      script.async = true
-      ;(window as any).onRecaptchaLoad = () => {
-        if (captchaContainerRef.current && !widgetRendered.current) {
-          widgetRendered.current = true
-          widgetIdRef.current = (window as any).grecaptcha.render(captchaContainerRef.current, {
-            sitekey: captchaConfig.site_key,
-            theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-            callback: (token: string) => setCaptchaToken(token),
-            'expired-callback': () => setCaptchaToken(''),
-          })
-        }
-      }
-      document.head.appendChild(script)
-    }
-  }, [needCaptcha, captchaConfig, resolvedTheme])
-
+  // Load captcha script and render widget
   useEffect(() => {
-    if (!needCaptcha || widgetRendered.current || !captchaContainerRef.current) return
-    if (captchaConfig.provider === 'cloudflare' && (window as any).turnstile) {
+    if (!needCaptcha || !isWidgetCaptchaProvider(captchaConfig?.provider)) return
+    const provider = captchaConfig.provider
+    loadCaptchaScript(provider, captchaConfig.site_key, () => {
+      if (!captchaContainerRef.current || widgetRendered.current) return
       widgetRendered.current = true
-      widgetIdRef.current = (window as any).turnstile.render(captchaContainerRef.current, {
-        sitekey: captchaConfig.site_key,
+      widgetIdRef.current = renderCaptchaWidget(provider, captchaContainerRef.current, {
+        siteKey: captchaConfig.site_key,
         theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-        callback: (token: string) => setCaptchaToken(token),
-        'expired-callback': () => setCaptchaToken(''),
+        action: 'login',
+        onToken: (token) => setCaptchaToken(token),
+        onExpire: () => setCaptchaToken(''),
       })
-    } else if (captchaConfig.provider === 'google' && (window as any).grecaptcha?.render) {
-      widgetRendered.current = true
-      widgetIdRef.current = (window as any).grecaptcha.render(captchaContainerRef.current, {
-        sitekey: captchaConfig.site_key,
-        theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-        callback: (token: string) => setCaptchaToken(token),
-        'expired-callback': () => setCaptchaToken(''),
-      })
-    }
+    })
   }, [needCaptcha, captchaConfig, resetMode, resolvedTheme])
 
   // Auto-submit/send when CF/Google captcha completes
   useEffect(() => {
-    if (!captchaToken || !needCaptcha || captchaConfig?.provider === 'builtin') return
+    if (!captchaToken || !needCaptcha || !isInteractiveCaptchaProvider(captchaConfig?.provider))
+      return
     if (resetMode === 'email' && email && !sent && !isSubmitting && countdown <= 0) {
       handleSubmit({ preventDefault: () => {} } as React.FormEvent)
     } else if (
@@ -215,11 +174,8 @@ export default function ForgotPasswordPage() {
     if (captchaConfig.provider === 'builtin') {
       refetchCaptcha()
       setBuiltinCode('')
-    } else if (captchaConfig.provider === 'cloudflare' && (window as any).turnstile) {
-      ;(window as any).turnstile.reset(widgetIdRef.current)
-      setCaptchaToken('')
-    } else if (captchaConfig.provider === 'google' && (window as any).grecaptcha) {
-      ;(window as any).grecaptcha.reset(widgetIdRef.current)
+    } else if (isWidgetCaptchaProvider(captchaConfig?.provider)) {
+      resetCaptchaWidget(captchaConfig.provider, widgetIdRef.current)
       setCaptchaToken('')
     }
   }
@@ -481,8 +437,9 @@ export default function ForgotPasswordPage() {
 
                 {needCaptcha && !sent && (
                   <div className="space-y-2">
-                    {(captchaConfig.provider === 'cloudflare' ||
-                      captchaConfig.provider === 'google') && <div ref={captchaContainerRef} />}
+                    {isWidgetCaptchaProvider(captchaConfig.provider) && (
+                      <div ref={captchaContainerRef} />
+                    )}
                     {captchaConfig.provider === 'builtin' && builtinCaptcha?.data && (
                       <>
                         <label className="text-sm font-medium">{t.auth.captcha}</label>
@@ -598,8 +555,9 @@ export default function ForgotPasswordPage() {
 
                 {needCaptcha && !phoneCodeSent && (
                   <div className="space-y-2">
-                    {(captchaConfig.provider === 'cloudflare' ||
-                      captchaConfig.provider === 'google') && <div ref={captchaContainerRef} />}
+                    {isWidgetCaptchaProvider(captchaConfig.provider) && (
+                      <div ref={captchaContainerRef} />
+                    )}
                     {captchaConfig.provider === 'builtin' && builtinCaptcha?.data && (
                       <>
                         <label className="text-sm font-medium">{t.auth.captcha}</label>
@@ -19,6 +19,14 @@ import { createRegisterSchema, registerSchema } from '@/lib/validators'
 import { useLocale } from '@/hooks/use-locale'
 import { usePageTitle } from '@/hooks/use-page-title'
 import { getTranslations } from '@/lib/i18n'
+import {
+  isInteractiveCaptchaProvider,
+  isWidgetCaptchaProvider,
+  loadCaptchaScript,
+  renderCaptchaWidget,
+  resetCaptchaWidget,
+  resolveCaptchaScene,
+} from '@/lib/captcha'
 import { Loader2, Mail, Lock, ArrowRight, User, Phone, KeyRound, Eye, EyeOff } from 'lucide-react'
 import Link from 'next/link'
 import { useQuery } from '@tanstack/react-query'
@@ -68,7 +76,7 @@ export default function RegisterPage() {
 
   const allowRegistration = publicConfig?.data?.allow_registration
   const allowPhoneRegister = publicConfig?.data?.allow_phone_register
-  const captchaConfig = publicConfig?.data?.captcha
+  const captchaConfig = resolveCaptchaScene(publicConfig?.data?.captcha, 'register')
   const needCaptcha =
     captchaConfig?.provider &&
     captchaConfig.provider !== 'none' &&
@@ -108,11 +116,8 @@ export default function RegisterPage() {
     if (captchaConfig.provider === 'builtin') {
       refetchCaptcha()
       setBuiltinCode('')
-    } else if (captchaConfig.provider === 'cloudflare' && (window as any).turnstile) {
-      ;(window as any).turnstile.reset(widgetIdRef.current)
-      setCaptchaToken('')
-    } else if (captchaConfig.provider === 'google' && (window as any).grecaptcha) {
-      ;(window as any).grecaptcha.reset(widgetIdRef.current)
+    } else if (isWidgetCaptchaProvider(captchaConfig?.provider)) {
+      resetCaptchaWidget(captchaConfig.provider, widgetIdRef.current)
       setCaptchaToken('')
     }
   }, [captchaConfig?.provider, needCaptcha, refetchCaptcha])
@@ -179,79 +184,27 @@ export default function RegisterPage() {
     )
   }
 
-  // Load Turnstile/reCAPTCHA scripts
-  useEffect(() => {
-    if (!needCaptcha) return
-
-    if (
-      captchaConfig.provider === 'cloudflare' &&
-      !document.getElementById('cf-turnstile-script')
-    ) {
-      const script = document.createElement('script')
-      script.id = 'cf-turnstile-script'
-      script.src = 'https://challenges.cloudflare.com/turnstile/v0/api.js?onload=onTurnstileLoad'
-      script.async = true
-      ;(window as any).onTurnstileLoad = () => {
-        if (captchaContainerRef.current && !widgetRendered.current) {
-          widgetRendered.current = true
-          widgetIdRef.current = (window as any).turnstile.render(captchaContainerRef.current, {
-            sitekey: captchaConfig.site_key,
-            theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-            callback: (token: string) => setCaptchaToken(token),
-            'expired-callback': () => setCaptchaToken(''),
-          })
-        }
-      }
-      document.head.appendChild(script)
-    } else if (
-      captchaConfig.provider === 'google' &&
-      !document.getElementById('recaptcha-script')
-    ) {
-      const script = document.createElement('script')
-      script.id = 'recaptcha-script'
-      script.src = 'https://www.google.com/recaptcha/api.js?onload=onRecaptchaLoad&render=explicit'
-      script.async = true
-      ;(window as any).onRecaptchaLoad = () => {
-        if (captchaContainerRef.current && !widgetRendered.current) {
-          widgetRendered.current = true
-          widgetIdRef.current = (window as any).grecaptcha.render(captchaContainerRef.current, {
-            sitekey: captchaConfig.site_key,
-            theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-            callback: (token: string) => setCaptchaToken(token),
-            'expired-callback': () => setCaptchaToken(''),
-          })
-        }
-      }
-      document.head.appendChild(script)
-    }
-  }, [needCaptcha, captchaConfig, resolvedTheme])
-
-  // Render widget if script already loaded
+  // Load captcha script and render widget
   useEffect(() => {
-    if (!needCaptcha || widgetRendered.current || !captchaContainerRef.current) return
-
-    if (captchaConfig.provider === 'cloudflare' && (window as any).turnstile) {
-      widgetRendered.current = true
-      widgetIdRef.current = (window as any).turnstile.render(captchaContainerRef.current, {
-        sitekey: captchaConfig.site_key,
-        theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-        callback: (token: string) => setCaptchaToken(token),
-        'expired-callback': () => setCaptchaToken(''),
-      })
-    } else if (captchaConfig.provider === 'google' && (window as any).grecaptcha?.render) {
+    if (!needCaptcha || !isWidgetCaptchaProvider(captchaConfig?.provider)) return
+    const provider = captchaConfig.provider
+    loadCaptchaScript(provider, captchaConfig.site_key, () => {
+      if (!captchaContainerRef.current || widgetRendered.current) return
       widgetRendered.current = true
-      widgetIdRef.current = (window as any).grecaptcha.render(captchaContainerRef.current, {
-        sitekey: captchaConfig.site_key,
+      widgetIdRef.current = renderCaptchaWidget(provider, captchaContainerRef.current, {
+        siteKey: captchaConfig.site_key,
         theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-        callback: (token: string) => setCaptchaToken(token),
-        'expired-callback': () => setCaptchaToken(''),
+        action: 'register',
+        onToken: (token) => setCaptchaToken(token),
+        onExpire: () => setCaptchaToken(''),
       })
-    }
+    })
   }, [needCaptcha, captchaConfig, resolvedTheme])
 
   // Auto-send phone code when CF/Google captcha completes
   useEffect(() => {
-    if (!captchaToken || !needCaptcha || captchaConfig?.provider === 'builtin') return
+    if (!captchaToken || !needCaptcha || !isInteractiveCaptchaProvider(captchaConfig?.provider))
+      return
     if (mode === 'phone' && phoneNumber && !sendingCode && countdown <= 0) {
       handleSendPhoneCode()
     }
@@ -459,8 +412,9 @@ export default function RegisterPage() {
               {/* Captcha - must complete before requesting SMS code */}
               {needCaptcha && (
                 <div className="space-y-1.5">
-                  {(captchaConfig.provider === 'cloudflare' ||
-                    captchaConfig.provider === 'google') && <div ref={captchaContainerRef} />}
+                  {isWidgetCaptchaProvider(captchaConfig.provider) && (
+                    <div ref={captchaContainerRef} />
+                  )}
                   {captchaConfig.provider === 'builtin' && builtinCaptcha?.data && (
                     <>
                       <label className="text-sm font-medium">{t.auth.captcha}</label>
@@ -699,8 +653,9 @@ export default function RegisterPage() {
                 {/* Captcha */}
                 {needCaptcha && (
                   <div className="space-y-1.5">
-                    {(captchaConfig.provider === 'cloudflare' ||
-                      captchaConfig.provider === 'google') && <div ref={captchaContainerRef} />}
+                    {isWidgetCaptchaProvider(captchaConfig.provider) && (
+                      <div ref={captchaContainerRef} />
+                    )}
                     {captchaConfig.provider === 'builtin' && builtinCaptcha?.data && (
                       <>
                         <label className="text-sm font-medium">{t.auth.captcha}</label>
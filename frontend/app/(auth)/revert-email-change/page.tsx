@@ -0,0 +1,101 @@
+'use client'
+
+import { Suspense, useState } from 'react'
+import { useRouter, useSearchParams } from 'next/navigation'
+import { useMutation } from '@tanstack/react-query'
+import { CheckCircle2, Loader2, ShieldAlert, XCircle } from 'lucide-react'
+import toast from 'react-hot-toast'
+import { revertEmailChange } from '@/lib/api'
+import { resolveAuthApiErrorMessage } from '@/lib/api-error'
+import { Button } from '@/components/ui/button'
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
+import { useLocale } from '@/hooks/use-locale'
+import { usePageTitle } from '@/hooks/use-page-title'
+import { getTranslations } from '@/lib/i18n'
+
+export default function RevertEmailChangePage() {
+  return (
+    <Suspense
+      fallback={
+        <div className="flex min-h-screen items-center justify-center bg-background p-6">
+          <Loader2 className="h-8 w-8 animate-spin text-primary" />
+        </div>
+      }
+    >
+      <RevertEmailChangeContent />
+    </Suspense>
+  )
+}
+
+function RevertEmailChangeContent() {
+  const searchParams = useSearchParams()
+  const router = useRouter()
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  usePageTitle(t.pageTitle.revertEmailChange)
+
+  const token = searchParams.get('token') || ''
+  const [restoredEmail, setRestoredEmail] = useState('')
+  const [errorMessage, setErrorMessage] = useState(token ? '' : t.auth.revertEmailMissingLink)
+
+  // 需用户手动确认，避免邮件安全扫描预取链接时误触发
+  const revertMutation = useMutation({
+    mutationFn: () => revertEmailChange(token),
+    onSuccess: (data: any) => {
+      setErrorMessage('')
+      setRestoredEmail(data.data?.email || '')
+    },
+    onError: (error) => {
+      const message = resolveAuthApiErrorMessage(error, t, t.auth.revertEmailFailed)
+      setErrorMessage(message)
+      toast.error(message)
+    },
+  })
+
+  const succeeded = revertMutation.isSuccess
+
+  return (
+    <div className="flex min-h-screen items-center justify-center bg-background p-6">
+      <Card className="w-full max-w-md">
+        <CardHeader className="text-center">
+          <div className="mx-auto mb-4 flex h-16 w-16 items-center justify-center rounded-full bg-primary/10">
+            {succeeded ? (
+              <CheckCircle2 className="h-8 w-8 text-green-500" />
+            ) : errorMessage ? (
+              <XCircle className="h-8 w-8 text-destructive" />
+            ) : (
+              <ShieldAlert className="h-8 w-8 text-primary" />
+            )}
+          </div>
+          <CardTitle>{succeeded ? t.auth.revertEmailSuccess : t.auth.revertEmailTitle}</CardTitle>
+          <CardDescription>
+            {succeeded
+              ? (t.auth.revertEmailSuccessDesc as string).replace('{email}', restoredEmail)
+              : errorMessage || t.auth.revertEmailDesc}
+          </CardDescription>
+        </CardHeader>
+        <CardContent className="space-y-2">
+          {succeeded ? (
+            <Button className="w-full" onClick={() => router.push('/forgot-password')}>
+              {t.auth.revertEmailResetPassword}
+            </Button>
+          ) : (
+            token && (
+              <Button
+                className="w-full"
+                onClick={() => revertMutation.mutate()}
+                disabled={revertMutation.isPending}
+              >
+                {revertMutation.isPending && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+                {t.auth.revertEmailConfirm}
+              </Button>
+            )
+          )}
+          <Button variant="ghost" className="w-full" onClick={() => router.push('/login')}>
+            {t.auth.backToLogin}
+          </Button>
+        </CardContent>
+      </Card>
+    </div>
+  )
+}
@@ -34,6 +34,7 @@ import {
   ShoppingCart,
   Package,
   AlertCircle,
+  Clock,
   RefreshCw,
   LayoutGrid,
   LayoutList,
@@ -49,6 +50,11 @@ import toast from 'react-hot-toast'
 import { Checkbox } from '@/components/ui/checkbox'
 import { useCurrency, formatPrice } from '@/contexts/currency-context'
 import { PluginSlot } from '@/components/plugins/plugin-slot'
+import {
+  WaitingRoomDialog,
+  getStoredWaitingRoomToken,
+  isWaitingRoomError,
+} from '@/components/orders/waiting-room-dialog'
 import {
   getGuestCart,
   getGuestCartItemKey,
@@ -82,6 +88,8 @@ type CartConfirmAction =
     }
   | null
 
+type CreateOrderPayload = Parameters<typeof createOrder>[0]
+
 export default function CartPage() {
   const router = useRouter()
   const queryClient = useQueryClient()
@@ -373,6 +381,9 @@ export default function CartPage() {
     setPromoCodeInput('')
   }
 
+  // 抢购等候室：下单被拦截时排队，放行后自动重新提交
+  const [waitingRoomOrder, setWaitingRoomOrder] = useState<CreateOrderPayload | null>(null)
+
   // 创建订单
   const createOrderMutation = useMutation({
     mutationFn: createOrder,
@@ -389,7 +400,11 @@ export default function CartPage() {
       queryClient.invalidateQueries({ queryKey: ['orders'] })
       router.push(`/orders/${orderNo}`)
     },
-    onError: (error: any) => {
+    onError: (error: any, variables) => {
+      if (isWaitingRoomError(error)) {
+        setWaitingRoomOrder({ ...variables, waiting_room_token: undefined })
+        return
+      }
       toast.error(resolveApiErrorMessage(error, t, t.cart.orderFailed))
     },
   })
@@ -404,11 +419,16 @@ export default function CartPage() {
     )
   }
 
+  // 商品数量规则：按购买倍数增减，不低于起订量
+  const getItemQuantityStep = (item: any) => Math.max(1, item?.product?.quantity_increment ?? 0)
+  const getItemMinQuantity = (item: any) =>
+    Math.max(getItemQuantityStep(item), item?.product?.min_order_quantity ?? 0, 1)
+
   // 处理数量变化
   const handleQuantityChange = async (itemId: number, newQuantity: number) => {
     const item = items.find((i) => i.id === itemId)
     const itemMaxQuantity = item ? getItemMaxQuantity(item) : maxItemQuantity
-    if (newQuantity < 1 || newQuantity > itemMaxQuantity) return
+    if (newQuantity < (item ? getItemMinQuantity(item) : 1) || newQuantity > itemMaxQuantity) return
     if (isGuestMode) {
       const guestItem = item as GuestCartDisplayItem | undefined
       if (!guestItem) return
@@ -681,6 +701,7 @@ export default function CartPage() {
     createOrderMutation.mutate({
       items: orderItems,
       ...(appliedPromo ? { promo_code: appliedPromo.code } : {}),
+      waiting_room_token: getStoredWaitingRoomToken(),
     })
   }
 
@@ -942,6 +963,15 @@ export default function CartPage() {
                             {t.cart.outOfStock}
                           </div>
                         )}
+                        {item.is_available && item.reserved_until && (
+                          <div className="mt-1 flex items-center gap-1 text-xs text-muted-foreground">
+                            <Clock className="h-3 w-3" />
+                            {t.cart.reservedUntil.replace(
+                              '{time}',
+                              new Date(item.reserved_until).toLocaleTimeString()
+                            )}
+                          </div>
+                        )}
                       </div>
                     </div>
                     {/* 第二行：价格 + 数量控制 */}
@@ -954,8 +984,12 @@ export default function CartPage() {
                           variant="outline"
                           size="icon"
                           className="h-7 w-7"
-                          onClick={() => handleQuantityChange(item.id, item.quantity - 1)}
-                          disabled={item.quantity <= 1}
+                          onClick={() =>
+                            handleQuantityChange(item.id, item.quantity - getItemQuantityStep(item))
+                          }
+                          disabled={
+                            item.quantity - getItemQuantityStep(item) < getItemMinQuantity(item)
+                          }
                           aria-label={t.cart.decreaseQuantity}
                           title={t.cart.decreaseQuantity}
                         >
@@ -979,8 +1013,12 @@ export default function CartPage() {
                           variant="outline"
                           size="icon"
                           className="h-7 w-7"
-                          onClick={() => handleQuantityChange(item.id, item.quantity + 1)}
-                          disabled={item.quantity >= getItemMaxQuantity(item)}
+                          onClick={() =>
+                            handleQuantityChange(item.id, item.quantity + getItemQuantityStep(item))
+                          }
+                          disabled={
+                            item.quantity + getItemQuantityStep(item) > getItemMaxQuantity(item)
+                          }
                           aria-label={t.cart.increaseQuantity}
                           title={t.cart.increaseQuantity}
                         >
@@ -1040,6 +1078,15 @@ export default function CartPage() {
                           {t.cart.outOfStock}
                         </div>
                       )}
+                      {item.is_available && item.reserved_until && (
+                        <div className="mt-1 flex items-center gap-1 text-xs text-muted-foreground">
+                          <Clock className="h-3 w-3" />
+                          {t.cart.reservedUntil.replace(
+                            '{time}',
+                            new Date(item.reserved_until).toLocaleTimeString()
+                          )}
+                        </div>
+                      )}
                       <div className="mt-2 flex items-center justify-between">
                         <span className="font-bold text-red-600">
                           {formatPrice(item.price_minor, currency)}
@@ -1049,8 +1096,15 @@ export default function CartPage() {
                             variant="outline"
                             size="icon"
                             className="h-8 w-8"
-                            onClick={() => handleQuantityChange(item.id, item.quantity - 1)}
-                            disabled={item.quantity <= 1}
+                            onClick={() =>
+                              handleQuantityChange(
+                                item.id,
+                                item.quantity - getItemQuantityStep(item)
+                              )
+                            }
+                            disabled={
+                              item.quantity - getItemQuantityStep(item) < getItemMinQuantity(item)
+                            }
                             aria-label={t.cart.decreaseQuantity}
                             title={t.cart.decreaseQuantity}
                           >
@@ -1074,8 +1128,15 @@ export default function CartPage() {
                             variant="outline"
                             size="icon"
                             className="h-8 w-8"
-                            onClick={() => handleQuantityChange(item.id, item.quantity + 1)}
-                            disabled={item.quantity >= getItemMaxQuantity(item)}
+                            onClick={() =>
+                              handleQuantityChange(
+                                item.id,
+                                item.quantity + getItemQuantityStep(item)
+                              )
+                            }
+                            disabled={
+                              item.quantity + getItemQuantityStep(item) > getItemMaxQuantity(item)
+                            }
                             aria-label={t.cart.increaseQuantity}
                             title={t.cart.increaseQuantity}
                           >
@@ -1212,8 +1273,12 @@ export default function CartPage() {
                       variant="outline"
                       size="icon"
                       className="h-7 w-7"
-                      onClick={() => handleQuantityChange(item.id, item.quantity - 1)}
-                      disabled={item.quantity <= 1}
+                      onClick={() =>
+                        handleQuantityChange(item.id, item.quantity - getItemQuantityStep(item))
+                      }
+                      disabled={
+                        item.quantity - getItemQuantityStep(item) < getItemMinQuantity(item)
+                      }
                       aria-label={t.cart.decreaseQuantity}
                       title={t.cart.decreaseQuantity}
                     >
@@ -1237,8 +1302,12 @@ export default function CartPage() {
                       variant="outline"
                       size="icon"
                       className="h-7 w-7"
-                      onClick={() => handleQuantityChange(item.id, item.quantity + 1)}
-                      disabled={item.quantity >= getItemMaxQuantity(item)}
+                      onClick={() =>
+                        handleQuantityChange(item.id, item.quantity + getItemQuantityStep(item))
+                      }
+                      disabled={
+                        item.quantity + getItemQuantityStep(item) > getItemMaxQuantity(item)
+                      }
                       aria-label={t.cart.increaseQuantity}
                       title={t.cart.increaseQuantity}
                     >
@@ -1512,6 +1581,19 @@ export default function CartPage() {
           </AlertDialogFooter>
         </AlertDialogContent>
       </AlertDialog>
+
+      <WaitingRoomDialog
+        open={!!waitingRoomOrder}
+        onOpenChange={(open) => {
+          if (!open) setWaitingRoomOrder(null)
+        }}
+        onAdmitted={(token) => {
+          if (waitingRoomOrder) {
+            createOrderMutation.mutate({ ...waitingRoomOrder, waiting_room_token: token })
+          }
+          setWaitingRoomOrder(null)
+        }}
+      />
     </div>
   )
 }
@@ -6,13 +6,22 @@ import { useQuery } from '@tanstack/react-query'
 import { useOrderDetail } from '@/hooks/use-orders'
 import { OrderDetail } from '@/components/orders/order-detail'
 import { PaymentMethodCard } from '@/components/orders/payment-method-card'
+import { OrderMessagesCard } from '@/components/orders/order-messages-card'
+import { OrderReturnsCard, orderAcceptsReturns } from '@/components/orders/order-returns-card'
+import { ShipmentTrackingCard } from '@/components/orders/shipment-tracking-card'
 import { ShippingForm } from '@/components/forms/shipping-form'
 import { Button } from '@/components/ui/button'
 import { Card, CardContent } from '@/components/ui/card'
 import { PageLoading } from '@/components/ui/page-loading'
-import { ArrowLeft, Loader2, FileText, AlertTriangle, Clock, RefreshCw } from 'lucide-react'
+import { ArrowLeft, Loader2, FileText, AlertTriangle, Clock, RefreshCw, Mail } from 'lucide-react'
 import Link from 'next/link'
-import { getOrRefreshFormToken, getFormInfo, getInvoiceToken } from '@/lib/api'
+import {
+  getOrRefreshFormToken,
+  getFormInfo,
+  getInvoiceToken,
+  resendOrderVirtualProductsEmail,
+  revealOrderVirtualProduct,
+} from '@/lib/api'
 import { useLocale } from '@/hooks/use-locale'
 import { useIsMobile } from '@/hooks/use-mobile'
 import { usePageTitle } from '@/hooks/use-page-title'
@@ -27,8 +36,20 @@ import {
   shouldFetchOrderVirtualProducts,
 } from '@/lib/order-detail-queries'
 import { getPublicConfigQueryOptions } from '@/lib/product-detail-queries'
+import type { VirtualProductStock } from '@/types/product'
+
+// resolvePaymentDeadline 优先使用订单保存的截止时间（商品/付款方式可覆盖），旧订单按全局时限推算
+function resolvePaymentDeadline(
+  deadlineAt: string | undefined,
+  createdAt: string | undefined,
+  autoCancelHours: number
+): number | null {
+  if (deadlineAt) return new Date(deadlineAt).getTime()
+  if (!createdAt || !autoCancelHours || autoCancelHours <= 0) return null
+  return new Date(createdAt).getTime() + autoCancelHours * 60 * 60 * 1000
+}
 
-function usePaymentCountdown(createdAt: string | undefined, autoCancelHours: number) {
+function usePaymentCountdown(deadline: number | null) {
   const [remaining, setRemaining] = useState<{
     hours: number
     minutes: number
@@ -36,13 +57,12 @@ function usePaymentCountdown(createdAt: string | undefined, autoCancelHours: num
   } | null>(null)
 
   useEffect(() => {
-    if (!createdAt || !autoCancelHours || autoCancelHours <= 0) {
+    if (deadline === null || Number.isNaN(deadline)) {
       setRemaining(null)
       return
     }
 
     const calc = () => {
-      const deadline = new Date(createdAt).getTime() + autoCancelHours * 60 * 60 * 1000
       const diff = deadline - Date.now()
       if (diff <= 0) {
         setRemaining({ hours: 0, minutes: 0, expired: true })
@@ -56,7 +76,7 @@ function usePaymentCountdown(createdAt: string | undefined, autoCancelHours: num
     calc()
     const timer = setInterval(calc, 60_000)
     return () => clearInterval(timer)
-  }, [createdAt, autoCancelHours])
+  }, [deadline])
 
   return remaining
 }
@@ -74,6 +94,7 @@ function OrderDetailContent({ orderNo }: { orderNo: string }) {
   const [formLoading, setFormLoading] = useState(false)
   const [formError, setFormError] = useState<string | null>(null)
   const [invoiceLoading, setInvoiceLoading] = useState(false)
+  const [resendingVirtualEmail, setResendingVirtualEmail] = useState(false)
   const [orderListBackHref, setOrderListBackHref] = useState('/orders')
 
   const {
@@ -154,8 +175,13 @@ function OrderDetailContent({ orderNo }: { orderNo: string }) {
 
   const autoCancelHours = publicConfig?.data?.auto_cancel_hours || 0
   const countdown = usePaymentCountdown(
-    order?.status === 'pending_payment' ? order?.created_at || order?.createdAt : undefined,
-    autoCancelHours
+    order?.status === 'pending_payment'
+      ? resolvePaymentDeadline(
+          order?.payment_deadline_at,
+          order?.created_at || order?.createdAt,
+          autoCancelHours
+        )
+      : null
   )
   const isPendingPayment = order?.status === 'pending_payment'
   const virtualStocks = virtualStocksData?.data?.stocks || []
@@ -261,6 +287,29 @@ function OrderDetailContent({ orderNo }: { orderNo: string }) {
     refetch()
   }
 
+  const handleVirtualStockReveal = async (stock: VirtualProductStock) => {
+    try {
+      const res: any = await revealOrderVirtualProduct(orderNo, stock.id)
+      return String(res?.data?.content || '')
+    } catch (error: any) {
+      toast.error(resolveApiErrorMessage(error, t, t.order.revealVirtualStockFailed))
+      throw error
+    }
+  }
+
+  const handleResendVirtualEmail = async () => {
+    if (resendingVirtualEmail) return
+    setResendingVirtualEmail(true)
+    try {
+      await resendOrderVirtualProductsEmail(orderNo)
+      toast.success(t.order.resendVirtualEmailSuccess)
+    } catch (error: any) {
+      toast.error(resolveApiErrorMessage(error, t, t.order.resendVirtualEmailFailed))
+    } finally {
+      setResendingVirtualEmail(false)
+    }
+  }
+
   const handleDownloadInvoice = async () => {
     if (invoiceLoading) return
     setInvoiceLoading(true)
@@ -428,12 +477,37 @@ function OrderDetailContent({ orderNo }: { orderNo: string }) {
         </div>
       )}
 
+      {order.merged_into_order_no && (
+        <div className="flex flex-wrap items-center justify-between gap-3 rounded-lg border p-4 text-sm">
+          <p>{t.order.mergedIntoNotice.replace('{order_no}', order.merged_into_order_no)}</p>
+          <Button asChild variant="outline" size="sm">
+            <Link href={`/orders/${order.merged_into_order_no}`}>{t.order.viewMergedOrder}</Link>
+          </Button>
+        </div>
+      )}
+
       <OrderDetail
         order={order}
         virtualStocks={virtualStocks}
         isVirtualOnly={isVirtualOnly}
         compactLayout={isCompactLayout}
         showVirtualStockRemark={showVirtualStockRemark}
+        revealVirtualStockContent={handleVirtualStockReveal}
+        virtualStockHeaderAction={
+          <Button
+            variant="outline"
+            size="sm"
+            onClick={handleResendVirtualEmail}
+            disabled={resendingVirtualEmail}
+          >
+            {resendingVirtualEmail ? (
+              <Loader2 className="mr-1 h-4 w-4 animate-spin" />
+            ) : (
+              <Mail className="mr-1 h-4 w-4" />
+            )}
+            {t.order.resendVirtualEmail}
+          </Button>
+        }
         pluginSlotNamespace="user.order_detail"
         pluginSlotContext={userOrderDetailPluginContext}
         pluginSlotPath={`/orders/${orderNo}`}
@@ -451,7 +525,14 @@ function OrderDetailContent({ orderNo }: { orderNo: string }) {
           ) : undefined
         }
         shippingForm={shippingFormNode}
+        trackingCard={
+          order.shipment_tracking ? (
+            <ShipmentTrackingCard tracking={order.shipment_tracking} />
+          ) : undefined
+        }
+        messagesCard={<OrderMessagesCard mode="user" orderNo={orderNo} />}
       />
+      {orderAcceptsReturns(order) && <OrderReturnsCard order={order} orderNo={orderNo} />}
       <PluginSlot slot="user.order_detail.bottom" context={userOrderDetailPluginContext} />
     </div>
   )
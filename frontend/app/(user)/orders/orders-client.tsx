@@ -5,6 +5,8 @@ import { Suspense, useState, useEffect, useCallback, useRef, useMemo } from 'rea
 import { useOrders } from '@/hooks/use-orders'
 import { OrderList } from '@/components/orders/order-list'
 import { OrderFilter } from '@/components/orders/order-filter'
+import { ClaimOrderDialog } from '@/components/orders/claim-order-dialog'
+import { ExportOrdersDialog } from '@/components/orders/export-orders-dialog'
 import { Button } from '@/components/ui/button'
 import { Card, CardContent } from '@/components/ui/card'
 import { RefreshCw } from 'lucide-react'
@@ -431,24 +433,28 @@ function OrdersPageContent() {
         <div>
           <h1 className="text-3xl font-bold">{t.order.myOrders}</h1>
         </div>
-        <Button
-          variant="outline"
-          size="sm"
-          onClick={handleRefresh}
-          disabled={isFetching}
-          aria-label={t.common.refresh}
-          title={t.common.refresh}
-          className="shrink-0"
-        >
-          <RefreshCw
-            className={`h-4 w-4 ${!isMobile ? 'mr-2' : ''} ${isFetching ? 'animate-spin' : ''}`}
-          />
-          {isMobile ? (
-            <span className="sr-only">{t.common.refresh}</span>
-          ) : (
-            <span>{t.common.refresh}</span>
-          )}
-        </Button>
+        <div className="flex items-center gap-2">
+          <ClaimOrderDialog compact={isMobile} onClaimed={handleRefresh} />
+          <ExportOrdersDialog compact={isMobile} />
+          <Button
+            variant="outline"
+            size="sm"
+            onClick={handleRefresh}
+            disabled={isFetching}
+            aria-label={t.common.refresh}
+            title={t.common.refresh}
+            className="shrink-0"
+          >
+            <RefreshCw
+              className={`h-4 w-4 ${!isMobile ? 'mr-2' : ''} ${isFetching ? 'animate-spin' : ''}`}
+            />
+            {isMobile ? (
+              <span className="sr-only">{t.common.refresh}</span>
+            ) : (
+              <span>{t.common.refresh}</span>
+            )}
+          </Button>
+        </div>
       </div>
 
       <OrderFilter
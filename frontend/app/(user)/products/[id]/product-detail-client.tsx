@@ -24,6 +24,7 @@ import {
   Plus,
   Tag,
   AlertCircle,
+  FileText,
 } from 'lucide-react'
 import { useState, useRef, useCallback, useMemo, useEffect } from 'react'
 import { Input } from '@/components/ui/input'
@@ -44,6 +45,12 @@ import { buildProductListReturnPath, readProductBrowseState } from '@/lib/produc
 import { MarkdownMessage } from '@/components/ui/markdown-message'
 import { Alert, AlertDescription, AlertTitle } from '@/components/ui/alert'
 import { PluginSlot } from '@/components/plugins/plugin-slot'
+import {
+  WaitingRoomDialog,
+  getStoredWaitingRoomToken,
+  isWaitingRoomError,
+} from '@/components/orders/waiting-room-dialog'
+import { QuoteRequestDialog } from '@/components/orders/quote-request-dialog'
 import { useIsMobile } from '@/hooks/use-mobile'
 import { cn } from '@/lib/utils'
 import {
@@ -54,6 +61,8 @@ import {
 
 type GuestActionHint = 'cart_added' | 'login_for_checkout' | 'login_for_promo' | null
 
+type CreateOrderPayload = Parameters<typeof createOrder>[0]
+
 export default function ProductDetailClient({ productId }: { productId: number }) {
   const router = useRouter()
   const queryClient = useQueryClient()
@@ -66,6 +75,7 @@ export default function ProductDetailClient({ productId }: { productId: number }
   const [isAddingToCart, setIsAddingToCart] = useState(false)
   const [productListBackHref, setProductListBackHref] = useState('/products')
   const [guestActionHint, setGuestActionHint] = useState<GuestActionHint>(null)
+  const [quoteDialogOpen, setQuoteDialogOpen] = useState(false)
   const hasRestoredAuthReturnStateRef = useRef(false)
   const toast = useToast()
   const { user, isAuthenticated, isLoading: authLoading } = useAuth()
@@ -103,6 +113,8 @@ export default function ProductDetailClient({ productId }: { productId: number }
   const { data: publicConfig } = useQuery(getPublicConfigQueryOptions())
   const maxItemQuantity = publicConfig?.data?.max_item_quantity || 9999
 
+  const [waitingRoomOrder, setWaitingRoomOrder] = useState<CreateOrderPayload | null>(null)
+
   const createOrderMutation = useMutation({
     mutationFn: createOrder,
     onSuccess: (response) => {
@@ -114,7 +126,11 @@ export default function ProductDetailClient({ productId }: { productId: number }
       queryClient.invalidateQueries({ queryKey: ['orders'] })
       router.push(orderNo ? `/orders/${orderNo}` : '/orders')
     },
-    onError: (error: any) => {
+    onError: (error: any, variables) => {
+      if (isWaitingRoomError(error)) {
+        setWaitingRoomOrder({ ...variables, waiting_room_token: undefined })
+        return
+      }
       toast.error(resolveApiErrorMessage(error, t, t.product.orderCreateFailed))
     },
   })
@@ -258,7 +274,15 @@ export default function ProductDetailClient({ productId }: { productId: number }
   }, [productId])
 
   // 实时计算优惠码折扣（基于当前数量和单价）
-  const subtotal = data?.data ? (data.data.price_minor || 0) * quantity : 0
+  // 阶梯价按当前数量取档（与服务端一致：取已达到档位中的最低单价，不高于基础价）
+  const priceTiers: Array<{ min_quantity: number; price_minor: number }> =
+    data?.data?.price_tiers || []
+  const unitPriceMinor = priceTiers.reduce(
+    (price, tier) =>
+      quantity >= tier.min_quantity && tier.price_minor < price ? tier.price_minor : price,
+    data?.data?.price_minor || 0
+  )
+  const subtotal = data?.data ? unitPriceMinor * quantity : 0
   const promoDiscount = useMemo(() => {
     if (!appliedPromo || subtotal <= 0) return 0
 
@@ -281,23 +305,38 @@ export default function ProductDetailClient({ productId }: { productId: number }
 
   const isFeatured = Boolean(product?.is_featured || product?.isFeatured)
   const hasDiscount = Number(product?.original_price_minor || 0) > Number(product?.price_minor || 0)
+  const displayPrice = product?.display_price
 
   const availableStock = stockData?.data?.available_stock ?? 0
   const isUnlimitedStock = !!stockData?.data?.is_unlimited
   const isAvailable = availableStock > 0
   const isGuestMode = !authLoading && !isAuthenticated
+  // 询价模式：当前访问者看不到价格，购买按钮替换为询价
+  const priceHidden = Boolean(product?.price_hidden)
   const productMaxPurchaseLimit = product?.max_purchase_limit ?? product?.maxPurchaseLimit ?? 0
+  // 商品数量规则：起订量与购买倍数（每个订单项），单笔上限
+  const minOrderQuantity = product?.min_order_quantity ?? 0
+  const maxOrderQuantity = product?.max_order_quantity ?? 0
+  const quantityStep = Math.max(1, product?.quantity_increment ?? 0)
+  const minSelectableQuantity = Math.max(quantityStep, minOrderQuantity, 1)
   const maxSelectableQuantity = Math.min(
     availableStock,
     maxItemQuantity,
-    productMaxPurchaseLimit > 0 ? productMaxPurchaseLimit : Number.MAX_SAFE_INTEGER
+    productMaxPurchaseLimit > 0 ? productMaxPurchaseLimit : Number.MAX_SAFE_INTEGER,
+    maxOrderQuantity > 0 ? maxOrderQuantity : Number.MAX_SAFE_INTEGER
   )
 
   useEffect(() => {
+    if (quantity < minSelectableQuantity) {
+      setQuantity(minSelectableQuantity)
+      return
+    }
     if (maxSelectableQuantity > 0 && quantity > maxSelectableQuantity) {
-      setQuantity(maxSelectableQuantity)
+      // 回落到不超过上限的最大倍数
+      const steppedMax = maxSelectableQuantity - (maxSelectableQuantity % quantityStep)
+      setQuantity(Math.max(minSelectableQuantity, steppedMax))
     }
-  }, [maxSelectableQuantity, quantity])
+  }, [maxSelectableQuantity, minSelectableQuantity, quantityStep, quantity])
 
   // Stock display settings
   const stockDisplayMode = publicConfig?.data?.stock_display?.mode || 'exact'
@@ -524,9 +563,28 @@ export default function ProductDetailClient({ productId }: { productId: number }
         },
       ],
       ...(appliedPromo ? { promo_code: appliedPromo.code } : {}),
+      waiting_room_token: getStoredWaitingRoomToken(),
     })
   }
 
+  const handleRequestQuote = () => {
+    if (authLoading) {
+      return
+    }
+
+    if (!isAuthenticated) {
+      setGuestActionHint('login_for_checkout')
+      return
+    }
+
+    if (!allAttributesSelected) {
+      toast.error(t.product.pleaseSelectAllAttributes)
+      return
+    }
+
+    setQuoteDialogOpen(true)
+  }
+
   const handleAddToCart = async () => {
     if (authLoading) {
       return
@@ -570,7 +628,11 @@ export default function ProductDetailClient({ productId }: { productId: number }
   }
 
   const handleQuantityChange = (newQuantity: number) => {
-    if (newQuantity >= 1 && newQuantity <= maxSelectableQuantity) {
+    if (
+      newQuantity >= minSelectableQuantity &&
+      newQuantity <= maxSelectableQuantity &&
+      newQuantity % quantityStep === 0
+    ) {
       setQuantity(newQuantity)
     }
   }
@@ -937,22 +999,38 @@ export default function ProductDetailClient({ productId }: { productId: number }
               <div className={cn('space-y-4 p-5', !isMobile && 'md:p-6')}>
                 {/* Price card */}
                 <div className="space-y-2 rounded-xl border border-border bg-muted/40 p-4">
-                  <div className="flex flex-col gap-1 sm:flex-row sm:items-baseline sm:gap-3">
-                    <span className="text-3xl font-bold text-red-500">
-                      {formatPrice(product.price_minor, currency)}
-                    </span>
-                    {hasDiscount && (
-                      <div className="flex items-center gap-2 text-sm text-muted-foreground">
-                        <span className="text-base text-muted-foreground line-through">
-                          {formatPrice(product.original_price_minor, currency)}
-                        </span>
-                        <span>
-                          {t.product.save} {getCurrencySymbol(currency)}
-                          {discountAmount}
-                        </span>
-                      </div>
-                    )}
-                  </div>
+                  {priceHidden ? (
+                    <div className="space-y-1">
+                      <span className="text-2xl font-bold">{t.product.priceOnRequest}</span>
+                      <p className="text-sm text-muted-foreground">
+                        {t.product.priceOnRequestHint}
+                      </p>
+                    </div>
+                  ) : (
+                    <div className="flex flex-col gap-1 sm:flex-row sm:items-baseline sm:gap-3">
+                      <span className="text-3xl font-bold text-red-500">
+                        {formatPrice(product.price_minor, currency)}
+                      </span>
+                      {hasDiscount && (
+                        <div className="flex items-center gap-2 text-sm text-muted-foreground">
+                          <span className="text-base text-muted-foreground line-through">
+                            {formatPrice(product.original_price_minor, currency)}
+                          </span>
+                          <span>
+                            {t.product.save} {getCurrencySymbol(currency)}
+                            {discountAmount}
+                          </span>
+                        </div>
+                      )}
+                    </div>
+                  )}
+                  {displayPrice && (
+                    <p className="text-sm text-muted-foreground">
+                      ≈ {formatPrice(displayPrice.price_minor, displayPrice.currency)}
+                      {' · '}
+                      {t.product.approximatePriceHint}
+                    </p>
+                  )}
                   {appliedPromo && promoDiscount > 0 && (
                     <div className="mt-2 flex items-baseline gap-3 border-t border-border/50 pt-1">
                       <div className="flex items-center gap-2">
@@ -969,6 +1047,27 @@ export default function ProductDetailClient({ productId }: { productId: number }
                       </span>
                     </div>
                   )}
+                  {priceTiers.length > 0 && (
+                    <div className="space-y-1 border-t border-border/50 pt-2 text-sm">
+                      <div className="font-medium">{t.product.bulkPricing}</div>
+                      {priceTiers.map((tier) => (
+                        <div
+                          key={tier.min_quantity}
+                          className={cn(
+                            'flex justify-between text-muted-foreground',
+                            unitPriceMinor === tier.price_minor &&
+                              quantity >= tier.min_quantity &&
+                              'font-medium text-foreground'
+                          )}
+                        >
+                          <span>
+                            {t.product.bulkPricingFrom.replace('{quantity}', String(tier.min_quantity))}
+                          </span>
+                          <span>{formatPrice(tier.price_minor, currency)}</span>
+                        </div>
+                      ))}
+                    </div>
+                  )}
                   <div className="text-sm text-muted-foreground">
                     {t.product.sku}: {product.sku}
                   </div>
@@ -1013,6 +1112,32 @@ export default function ProductDetailClient({ productId }: { productId: number }
                       </div>
                     </div>
                   )}
+                  {(minOrderQuantity > 0 || quantityStep > 1 || maxOrderQuantity > 0) && (
+                    <div className="space-y-1.5 rounded-xl border border-border p-3">
+                      <div className="text-xs text-muted-foreground">{t.product.quantity}</div>
+                      <div className="text-sm font-medium">
+                        {[
+                          minOrderQuantity > 0 &&
+                            t.product.minOrderQuantityHint.replace(
+                              '{min}',
+                              String(minOrderQuantity)
+                            ),
+                          quantityStep > 1 &&
+                            t.product.quantityIncrementHint.replace(
+                              '{increment}',
+                              String(quantityStep)
+                            ),
+                          maxOrderQuantity > 0 &&
+                            t.product.maxOrderQuantityHint.replace(
+                              '{max}',
+                              String(maxOrderQuantity)
+                            ),
+                        ]
+                          .filter(Boolean)
+                          .join(' · ')}
+                      </div>
+                    </div>
+                  )}
                   {product.tags && product.tags.length > 0 && (
                     <div className="space-y-1.5 rounded-xl border border-border p-3 sm:col-span-2">
                       <div className="text-xs text-muted-foreground">{t.product.tagsLabel}</div>
@@ -1138,8 +1263,8 @@ export default function ProductDetailClient({ productId }: { productId: number }
                         variant="outline"
                         size="icon"
                         className="h-9 w-9 rounded-r-none"
-                        onClick={() => handleQuantityChange(quantity - 1)}
-                        disabled={quantity <= 1}
+                        onClick={() => handleQuantityChange(quantity - quantityStep)}
+                        disabled={quantity - quantityStep < minSelectableQuantity}
                         aria-label={t.product.decreaseQuantity}
                         title={t.product.decreaseQuantity}
                       >
@@ -1156,16 +1281,17 @@ export default function ProductDetailClient({ productId }: { productId: number }
                           }
                         }}
                         className="h-9 w-16 rounded-none border-x-0 text-center focus-visible:ring-0 focus-visible:ring-offset-0"
-                        min={1}
+                        min={minSelectableQuantity}
                         max={maxSelectableQuantity}
+                        step={quantityStep}
                         aria-label={t.product.quantity}
                       />
                       <Button
                         variant="outline"
                         size="icon"
                         className="h-9 w-9 rounded-l-none"
-                        onClick={() => handleQuantityChange(quantity + 1)}
-                        disabled={quantity >= maxSelectableQuantity}
+                        onClick={() => handleQuantityChange(quantity + quantityStep)}
+                        disabled={quantity + quantityStep > maxSelectableQuantity}
                         aria-label={t.product.increaseQuantity}
                         title={t.product.increaseQuantity}
                       >
@@ -1187,67 +1313,74 @@ export default function ProductDetailClient({ productId }: { productId: number }
                 />
 
                 {/* Promo code */}
-                <div className="space-y-3 rounded-xl border border-border bg-muted/10 p-4">
-                  <div className="flex items-center gap-2 text-sm font-medium">
-                    <Tag className="h-4 w-4" />
-                    {t.promoCode.enterPromoCode}
-                  </div>
-                  {!appliedPromo ? (
-                    <>
-                      <div className="flex gap-2">
-                        <Input
-                          value={promoCodeInput}
-                          onChange={(e) => setPromoCodeInput(e.target.value)}
-                          placeholder={t.promoCode.promoCodePlaceholder}
-                          className="flex-1"
-                          maxLength={50}
-                          onKeyDown={(e) => {
-                            if (e.key === 'Enter') handleApplyPromoCode()
-                          }}
-                        />
-                        <Button
-                          onClick={handleApplyPromoCode}
-                          disabled={authLoading || !isAuthenticated || !promoCodeInput.trim() || isValidatingPromo}
-                          size="default"
-                        >
-                          {isValidatingPromo ? t.promoCode.applying : t.promoCode.apply}
-                        </Button>
-                      </div>
-                      {isGuestMode && (
-                        <p className="text-xs text-muted-foreground">
-                          {t.product.loginForPromoCode}
-                        </p>
-                      )}
-                    </>
-                  ) : (
-                    <div className="space-y-2">
-                      <div className="flex items-center justify-between rounded-lg border border-green-500/20 bg-green-500/10 p-3 dark:border-green-500/30 dark:bg-green-500/20">
-                        <div>
-                          <div className="text-sm font-medium text-green-700 dark:text-green-400">
-                            {appliedPromo.name}
-                          </div>
-                          <div className="mt-0.5 text-xs text-green-600 dark:text-green-500">
-                            {t.promoCode.applied} &mdash; {appliedPromo.code}
+                {!priceHidden && (
+                  <div className="space-y-3 rounded-xl border border-border bg-muted/10 p-4">
+                    <div className="flex items-center gap-2 text-sm font-medium">
+                      <Tag className="h-4 w-4" />
+                      {t.promoCode.enterPromoCode}
+                    </div>
+                    {!appliedPromo ? (
+                      <>
+                        <div className="flex gap-2">
+                          <Input
+                            value={promoCodeInput}
+                            onChange={(e) => setPromoCodeInput(e.target.value)}
+                            placeholder={t.promoCode.promoCodePlaceholder}
+                            className="flex-1"
+                            maxLength={50}
+                            onKeyDown={(e) => {
+                              if (e.key === 'Enter') handleApplyPromoCode()
+                            }}
+                          />
+                          <Button
+                            onClick={handleApplyPromoCode}
+                            disabled={
+                              authLoading ||
+                              !isAuthenticated ||
+                              !promoCodeInput.trim() ||
+                              isValidatingPromo
+                            }
+                            size="default"
+                          >
+                            {isValidatingPromo ? t.promoCode.applying : t.promoCode.apply}
+                          </Button>
+                        </div>
+                        {isGuestMode && (
+                          <p className="text-xs text-muted-foreground">
+                            {t.product.loginForPromoCode}
+                          </p>
+                        )}
+                      </>
+                    ) : (
+                      <div className="space-y-2">
+                        <div className="flex items-center justify-between rounded-lg border border-green-500/20 bg-green-500/10 p-3 dark:border-green-500/30 dark:bg-green-500/20">
+                          <div>
+                            <div className="text-sm font-medium text-green-700 dark:text-green-400">
+                              {appliedPromo.name}
+                            </div>
+                            <div className="mt-0.5 text-xs text-green-600 dark:text-green-500">
+                              {t.promoCode.applied} &mdash; {appliedPromo.code}
+                            </div>
                           </div>
+                          <Button
+                            variant="ghost"
+                            size="sm"
+                            className="text-red-500 hover:bg-red-500/10 hover:text-red-600"
+                            onClick={handleRemovePromoCode}
+                          >
+                            {t.promoCode.remove}
+                          </Button>
+                        </div>
+                        <div className="flex items-center justify-between text-sm">
+                          <span className="text-muted-foreground">{t.promoCode.discount}</span>
+                          <span className="font-medium text-green-600 dark:text-green-400">
+                            -{formatPrice(promoDiscount, currency)}
+                          </span>
                         </div>
-                        <Button
-                          variant="ghost"
-                          size="sm"
-                          className="text-red-500 hover:bg-red-500/10 hover:text-red-600"
-                          onClick={handleRemovePromoCode}
-                        >
-                          {t.promoCode.remove}
-                        </Button>
-                      </div>
-                      <div className="flex items-center justify-between text-sm">
-                        <span className="text-muted-foreground">{t.promoCode.discount}</span>
-                        <span className="font-medium text-green-600 dark:text-green-400">
-                          -{formatPrice(promoDiscount, currency)}
-                        </span>
                       </div>
-                    </div>
-                  )}
-                </div>
+                    )}
+                  </div>
+                )}
                 <PluginSlot
                   slot="user.product_detail.promo.after"
                   context={{ ...userProductDetailPluginContext, section: 'promo' }}
@@ -1288,53 +1421,70 @@ export default function ProductDetailClient({ productId }: { productId: number }
                 />
 
                 {/* Action buttons */}
-                <div className="flex flex-col gap-3 sm:flex-row">
-                  <Button
-                    variant="outline"
-                    className="h-11 min-w-0 flex-1"
-                    disabled={authLoading || !isAvailable || !allAttributesSelected || isAddingToCart}
-                    onClick={handleAddToCart}
-                  >
-                    <span className="inline-flex items-center truncate">
-                      {isAddingToCart ? (
-                        <>
-                          <Loader2 className="mr-2 h-4 w-4 shrink-0 animate-spin" />
-                          {t.product.addingToCart}
-                        </>
-                      ) : (
-                        <>
-                          <ShoppingCart className="mr-2 h-4 w-4 shrink-0" />
-                          {t.product.addToCart}
-                        </>
-                      )}
-                    </span>
-                  </Button>
+                {priceHidden ? (
                   <Button
-                    className="h-11 min-w-0 flex-1"
-                    disabled={
-                      authLoading ||
-                      !isAvailable ||
-                      !allAttributesSelected ||
-                      createOrderMutation.isPending
-                    }
-                    onClick={handleBuyNow}
+                    className="h-11 w-full"
+                    disabled={authLoading || !allAttributesSelected}
+                    onClick={handleRequestQuote}
                   >
                     <span className="inline-flex items-center truncate">
-                      {createOrderMutation.isPending ? (
-                        <>
-                          <Loader2 className="mr-2 h-4 w-4 shrink-0 animate-spin" />
-                          {t.product.creatingOrder}
-                        </>
-                      ) : !isAvailable ? (
-                        t.product.soldOut
-                      ) : !allAttributesSelected ? (
-                        t.product.pleaseSelectSpec
-                      ) : (
-                        t.product.buyNow
-                      )}
+                      <FileText className="mr-2 h-4 w-4 shrink-0" />
+                      {allAttributesSelected
+                        ? t.quoteRequest.requestQuote
+                        : t.product.pleaseSelectSpec}
                     </span>
                   </Button>
-                </div>
+                ) : (
+                  <div className="flex flex-col gap-3 sm:flex-row">
+                    <Button
+                      variant="outline"
+                      className="h-11 min-w-0 flex-1"
+                      disabled={
+                        authLoading || !isAvailable || !allAttributesSelected || isAddingToCart
+                      }
+                      onClick={handleAddToCart}
+                    >
+                      <span className="inline-flex items-center truncate">
+                        {isAddingToCart ? (
+                          <>
+                            <Loader2 className="mr-2 h-4 w-4 shrink-0 animate-spin" />
+                            {t.product.addingToCart}
+                          </>
+                        ) : (
+                          <>
+                            <ShoppingCart className="mr-2 h-4 w-4 shrink-0" />
+                            {t.product.addToCart}
+                          </>
+                        )}
+                      </span>
+                    </Button>
+                    <Button
+                      className="h-11 min-w-0 flex-1"
+                      disabled={
+                        authLoading ||
+                        !isAvailable ||
+                        !allAttributesSelected ||
+                        createOrderMutation.isPending
+                      }
+                      onClick={handleBuyNow}
+                    >
+                      <span className="inline-flex items-center truncate">
+                        {createOrderMutation.isPending ? (
+                          <>
+                            <Loader2 className="mr-2 h-4 w-4 shrink-0 animate-spin" />
+                            {t.product.creatingOrder}
+                          </>
+                        ) : !isAvailable ? (
+                          t.product.soldOut
+                        ) : !allAttributesSelected ? (
+                          t.product.pleaseSelectSpec
+                        ) : (
+                          t.product.buyNow
+                        )}
+                      </span>
+                    </Button>
+                  </div>
+                )}
               </div>
             </div>
             <PluginSlot
@@ -1371,6 +1521,28 @@ export default function ProductDetailClient({ productId }: { productId: number }
         </div>
       </div>
       <PluginSlot slot="user.product_detail.bottom" context={userProductDetailPluginContext} />
+      <QuoteRequestDialog
+        open={quoteDialogOpen}
+        onOpenChange={setQuoteDialogOpen}
+        productId={product.id}
+        productName={product.name}
+        quantity={quantity}
+        attributes={selectedAttributes}
+        defaultContactName={user?.name}
+        defaultContactEmail={user?.email}
+      />
+      <WaitingRoomDialog
+        open={!!waitingRoomOrder}
+        onOpenChange={(open) => {
+          if (!open) setWaitingRoomOrder(null)
+        }}
+        onAdmitted={(token) => {
+          if (waitingRoomOrder) {
+            createOrderMutation.mutate({ ...waitingRoomOrder, waiting_room_token: token })
+          }
+          setWaitingRoomOrder(null)
+        }}
+      />
     </div>
   )
 }
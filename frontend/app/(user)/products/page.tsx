@@ -696,7 +696,9 @@ function ProductsPageContent() {
                               : 'text-base font-bold text-red-600 md:text-xl'
                           }
                         >
-                          {formatPrice(product.price_minor, currency)}
+                          {product.price_hidden
+                            ? t.product.priceOnRequest
+                            : formatPrice(product.price_minor, currency)}
                         </span>
                         {/* 原价：移动端隐藏，桌面端显示 */}
                         {!isMobile && hasDiscount && (
@@ -705,6 +707,18 @@ function ProductsPageContent() {
                           </span>
                         )}
                       </div>
+                      {product.display_price && (
+                        <p
+                          className="text-xs text-muted-foreground"
+                          title={t.product.approximatePriceHint}
+                        >
+                          ≈{' '}
+                          {formatPrice(
+                            product.display_price.price_minor,
+                            product.display_price.currency
+                          )}
+                        </p>
+                      )}
                       {!isMobile && product.category ? (
                         <p className="pt-1 text-xs text-muted-foreground">
                           {product.category}
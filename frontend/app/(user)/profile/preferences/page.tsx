@@ -78,6 +78,10 @@ export default function PreferencesPage() {
 
   const smtpEnabled = Boolean(publicConfig?.data?.smtp_enabled)
   const smsEnabled = Boolean(publicConfig?.data?.sms_enabled)
+  const exchangeRateEnabled = Boolean(publicConfig?.data?.exchange_rate?.enabled)
+  const configuredDisplayCurrencies: string[] | undefined =
+    publicConfig?.data?.exchange_rate?.display_currencies
+  const currentDisplayCurrency: string = user?.display_currency || ''
   const hasServiceConfig = typeof publicConfig !== 'undefined'
   const emailServiceStatus = hasServiceConfig
     ? smtpEnabled
@@ -129,6 +133,15 @@ export default function PreferencesPage() {
     ]
   )
 
+  // 展示币种选项：空字符串表示按结算币种显示，已保存但不在配置列表中的币种也保留
+  const displayCurrencyOptions = useMemo(() => {
+    const codes = (configuredDisplayCurrencies || [])
+      .map((code) => code.trim().toUpperCase())
+      .filter(Boolean)
+    if (currentDisplayCurrency) codes.push(currentDisplayCurrency)
+    return ['', ...Array.from(new Set(codes))]
+  }, [configuredDisplayCurrencies, currentDisplayCurrency])
+
   const currentLanguageLabel =
     languageOptions.find((option) => option.value === locale)?.label || locale
   const currentThemeLabel = themeOptions.find((option) => option.value === theme)?.label || theme
@@ -193,6 +206,18 @@ export default function PreferencesPage() {
     },
   })
 
+  const saveDisplayCurrencyMutation = useMutation({
+    mutationFn: (displayCurrency: string) =>
+      updateUserPreferences({ display_currency: displayCurrency }),
+    onSuccess: () => {
+      toast.success(t.profile.displayCurrencySaveSuccess)
+      queryClient.invalidateQueries({ queryKey: ['currentUser'] })
+    },
+    onError: (error: any) => {
+      toast.error(resolveApiErrorMessage(error, t, t.profile.displayCurrencySaveFailed))
+    },
+  })
+
   const handleSaveNotificationPrefs = useCallback(() => {
     saveNotificationPrefsMutation.mutate(notificationPrefs)
   }, [saveNotificationPrefsMutation, notificationPrefs])
@@ -205,6 +230,7 @@ export default function PreferencesPage() {
         is_guest: isGuest,
         locale,
         theme,
+        display_currency: currentDisplayCurrency,
         smtp_enabled: smtpEnabled,
         sms_enabled: smsEnabled,
       },
@@ -218,6 +244,7 @@ export default function PreferencesPage() {
       },
     }),
     [
+      currentDisplayCurrency,
       isGuest,
       locale,
       notificationPrefs,
@@ -365,6 +392,33 @@ export default function PreferencesPage() {
                   })}
                 </div>
               </div>
+              {!isGuest && exchangeRateEnabled && (
+                <div className="space-y-3">
+                  <div className="flex items-center justify-between">
+                    <p className="text-sm font-medium">{t.profile.displayCurrencyPreference}</p>
+                    <span className="text-sm text-muted-foreground">
+                      {currentDisplayCurrency || t.profile.displayCurrencyDefault}
+                    </span>
+                  </div>
+                  <p className="text-xs text-muted-foreground">
+                    {t.profile.displayCurrencyPreferenceDesc}
+                  </p>
+                  <div className="grid grid-cols-3 gap-2">
+                    {displayCurrencyOptions.map((code) => (
+                      <Button
+                        key={code || 'default'}
+                        variant={currentDisplayCurrency === code ? 'default' : 'outline'}
+                        onClick={() => saveDisplayCurrencyMutation.mutate(code)}
+                        disabled={saveDisplayCurrencyMutation.isPending}
+                        className="h-10"
+                        aria-pressed={currentDisplayCurrency === code}
+                      >
+                        {code || t.profile.displayCurrencyDefault}
+                      </Button>
+                    ))}
+                  </div>
+                </div>
+              )}
               <PluginSlot
                 slot="user.profile.preferences.display.after"
                 context={{ ...userProfilePreferencesPluginContext, section: 'display' }}
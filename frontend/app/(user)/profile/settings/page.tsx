@@ -5,7 +5,7 @@ import { useState, useEffect, useCallback, useRef } from 'react'
 import { useForm } from 'react-hook-form'
 import { zodResolver } from '@hookform/resolvers/zod'
 import { useAuth } from '@/hooks/use-auth'
-import { Card, CardHeader, CardTitle, CardContent } from '@/components/ui/card'
+import { Card, CardHeader, CardTitle, CardContent, CardDescription } from '@/components/ui/card'
 import { Button } from '@/components/ui/button'
 import { Input } from '@/components/ui/input'
 import {
@@ -35,6 +35,14 @@ import { useLocale } from '@/hooks/use-locale'
 import { useIsMobile } from '@/hooks/use-mobile'
 import { usePageTitle } from '@/hooks/use-page-title'
 import { getTranslations } from '@/lib/i18n'
+import {
+  isInteractiveCaptchaProvider,
+  isWidgetCaptchaProvider,
+  loadCaptchaScript,
+  renderCaptchaWidget,
+  resetCaptchaWidget,
+  resolveCaptchaScene,
+} from '@/lib/captcha'
 import Link from 'next/link'
 import { useQuery, useQueryClient } from '@tanstack/react-query'
 import { useTheme } from '@/contexts/theme-context'
@@ -59,7 +67,7 @@ export default function SettingsPage() {
   const smtpEnabled = publicConfig?.data?.smtp_enabled
   const smsEnabled = publicConfig?.data?.sms_enabled
   const hasServiceConfig = typeof publicConfig !== 'undefined'
-  const captchaConfig = publicConfig?.data?.captcha
+  const captchaConfig = resolveCaptchaScene(publicConfig?.data?.captcha, 'bind')
   const needBindCaptcha =
     captchaConfig?.provider && captchaConfig.provider !== 'none' && captchaConfig.enable_for_bind
   const { resolvedTheme } = useTheme()
@@ -106,78 +114,28 @@ export default function SettingsPage() {
     widgetRendered.current = false
   }, [user?.email, user?.phone])
 
-  // Load captcha scripts for cloudflare/google
+  // Load captcha script and render widget
   useEffect(() => {
-    if (!needBindCaptcha) return
-    if (
-      captchaConfig.provider === 'cloudflare' &&
-      !document.getElementById('cf-turnstile-script')
-    ) {
-      const script = document.createElement('script')
-      script.id = 'cf-turnstile-script'
-      script.src = 'https://challenges.cloudflare.com/turnstile/v0/api.js?onload=onTurnstileLoad'
-      script.async = true
-      ;(window as any).onTurnstileLoad = () => {
-        if (captchaContainerRef.current && !widgetRendered.current) {
-          widgetRendered.current = true
-          widgetIdRef.current = (window as any).turnstile.render(captchaContainerRef.current, {
-            sitekey: captchaConfig.site_key,
-            theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-            callback: (token: string) => setCaptchaToken(token),
-            'expired-callback': () => setCaptchaToken(''),
-          })
-        }
-      }
-      document.head.appendChild(script)
-    } else if (
-      captchaConfig.provider === 'google' &&
-      !document.getElementById('recaptcha-script')
-    ) {
-      const script = document.createElement('script')
-      script.id = 'recaptcha-script'
-      script.src = 'https://www.google.com/recaptcha/api.js?onload=onRecaptchaLoad&render=explicit'
-      script.async = true
-      ;(window as any).onRecaptchaLoad = () => {
-        if (captchaContainerRef.current && !widgetRendered.current) {
-          widgetRendered.current = true
-          widgetIdRef.current = (window as any).grecaptcha.render(captchaContainerRef.current, {
-            sitekey: captchaConfig.site_key,
-            theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-            callback: (token: string) => setCaptchaToken(token),
-            'expired-callback': () => setCaptchaToken(''),
-          })
-        }
-      }
-      document.head.appendChild(script)
-    }
-  }, [needBindCaptcha, captchaConfig, resolvedTheme])
-
-  // Render widget if script already loaded
-  useEffect(() => {
-    if (!needBindCaptcha || widgetRendered.current || !captchaContainerRef.current) return
-    if (captchaConfig.provider === 'cloudflare' && (window as any).turnstile) {
+    if (!needBindCaptcha || !isWidgetCaptchaProvider(captchaConfig?.provider)) return
+    const provider = captchaConfig.provider
+    loadCaptchaScript(provider, captchaConfig.site_key, () => {
+      if (!captchaContainerRef.current || widgetRendered.current) return
       widgetRendered.current = true
-      widgetIdRef.current = (window as any).turnstile.render(captchaContainerRef.current, {
-        sitekey: captchaConfig.site_key,
+      widgetIdRef.current = renderCaptchaWidget(provider, captchaContainerRef.current, {
+        siteKey: captchaConfig.site_key,
         theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-        callback: (token: string) => setCaptchaToken(token),
-        'expired-callback': () => setCaptchaToken(''),
+        action: 'bind',
+        onToken: (token) => setCaptchaToken(token),
+        onExpire: () => setCaptchaToken(''),
       })
-    } else if (captchaConfig.provider === 'google' && (window as any).grecaptcha?.render) {
-      widgetRendered.current = true
-      widgetIdRef.current = (window as any).grecaptcha.render(captchaContainerRef.current, {
-        sitekey: captchaConfig.site_key,
-        theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-        callback: (token: string) => setCaptchaToken(token),
-        'expired-callback': () => setCaptchaToken(''),
-      })
-    }
+    })
   }, [needBindCaptcha, captchaConfig, resolvedTheme, user?.email, user?.phone])
 
   // Auto-send bind code when CF/Google captcha completes
   useEffect(() => {
-    if (!captchaToken || !needBindCaptcha || captchaConfig?.provider === 'builtin') return
-    const emailBindVisible = !user?.email && smtpEnabled
+    if (!captchaToken || !needBindCaptcha || !isInteractiveCaptchaProvider(captchaConfig?.provider))
+      return
+    const emailBindVisible = smtpEnabled
     const phoneBindVisible = !user?.phone && smsEnabled
     if (emailBindVisible && bindEmailAddr && !emailSending && emailCooldown <= 0) {
       handleSendBindEmailCode()
@@ -214,11 +172,8 @@ export default function SettingsPage() {
     if (captchaConfig.provider === 'builtin') {
       refetchCaptcha()
       setBuiltinCode('')
-    } else if (captchaConfig.provider === 'cloudflare' && (window as any).turnstile) {
-      ;(window as any).turnstile.reset(widgetIdRef.current)
-      setCaptchaToken('')
-    } else if (captchaConfig.provider === 'google' && (window as any).grecaptcha) {
-      ;(window as any).grecaptcha.reset(widgetIdRef.current)
+    } else if (isWidgetCaptchaProvider(captchaConfig?.provider)) {
+      resetCaptchaWidget(captchaConfig.provider, widgetIdRef.current)
       setCaptchaToken('')
     }
   }, [captchaConfig?.provider, needBindCaptcha, refetchCaptcha])
@@ -256,14 +211,16 @@ export default function SettingsPage() {
     setEmailBinding(true)
     try {
       await bindEmail(bindEmailAddr, bindEmailCode)
-      toast.success(t.profile.bindSuccess)
+      toast.success(user?.email ? t.profile.changeEmailSuccess : t.profile.bindSuccess)
+      setBindEmailAddr('')
+      setBindEmailCode('')
       queryClient.invalidateQueries({ queryKey: ['currentUser'] })
     } catch (e: any) {
       toast.error(resolveApiErrorMessage(e, t, t.profile.bindFailed))
     } finally {
       setEmailBinding(false)
     }
-  }, [bindEmailAddr, bindEmailCode, t, toast, queryClient])
+  }, [bindEmailAddr, bindEmailCode, t, toast, queryClient, user?.email])
 
   const handleSendBindPhoneCode = useCallback(async () => {
     if (!bindPhoneNum) return
@@ -398,18 +355,21 @@ export default function SettingsPage() {
         context={{ ...userProfileSettingsPluginContext, section: 'account_info' }}
       />
 
-      {/* Bind Email */}
-      {!user?.email && smtpEnabled && (
+      {/* Bind / Change Email */}
+      {smtpEnabled && (
         <Card>
           <CardHeader>
             <CardTitle className="flex items-center gap-2">
               <Mail className="h-5 w-5" />
-              {t.profile.bindEmail}
+              {user?.email ? t.profile.changeEmail : t.profile.bindEmail}
             </CardTitle>
+            {user?.email && <CardDescription>{t.profile.changeEmailDesc}</CardDescription>}
           </CardHeader>
           <CardContent className="space-y-4">
             <div>
-              <label className="text-sm font-medium">{t.profile.email}</label>
+              <label className="text-sm font-medium">
+                {user?.email ? t.profile.newEmail : t.profile.email}
+              </label>
               <Input
                 type="email"
                 className="mt-2"
@@ -420,8 +380,9 @@ export default function SettingsPage() {
             </div>
             {needBindCaptcha && (
               <div className="space-y-2">
-                {(captchaConfig.provider === 'cloudflare' ||
-                  captchaConfig.provider === 'google') && <div ref={captchaContainerRef} />}
+                {isWidgetCaptchaProvider(captchaConfig.provider) && (
+                  <div ref={captchaContainerRef} />
+                )}
                 {captchaConfig.provider === 'builtin' && builtinCaptcha?.data && (
                   <>
                     <label className="text-sm font-medium">{t.auth.captcha}</label>
@@ -494,7 +455,11 @@ export default function SettingsPage() {
               disabled={!bindEmailAddr || !bindEmailCode || emailBinding}
               onClick={handleBindEmail}
             >
-              {emailBinding ? t.profile.binding : t.profile.bind}
+              {emailBinding
+                ? t.profile.binding
+                : user?.email
+                  ? t.profile.changeEmailConfirm
+                  : t.profile.bind}
             </Button>
           </CardContent>
         </Card>
@@ -547,8 +512,9 @@ export default function SettingsPage() {
             </div>
             {needBindCaptcha && !(!user?.email && smtpEnabled) && (
               <div className="space-y-2">
-                {(captchaConfig.provider === 'cloudflare' ||
-                  captchaConfig.provider === 'google') && <div ref={captchaContainerRef} />}
+                {isWidgetCaptchaProvider(captchaConfig.provider) && (
+                  <div ref={captchaContainerRef} />
+                )}
                 {captchaConfig.provider === 'builtin' && builtinCaptcha?.data && (
                   <>
                     <label className="text-sm font-medium">{t.auth.captcha}</label>
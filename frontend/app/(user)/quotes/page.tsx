@@ -0,0 +1,229 @@
+'use client'
+
+import { useState } from 'react'
+import Link from 'next/link'
+import { useMutation, useQuery } from '@tanstack/react-query'
+import { ChevronLeft, ChevronRight, FileText } from 'lucide-react'
+import { format } from 'date-fns'
+import { zhCN } from 'date-fns/locale'
+import {
+  acceptQuoteRequest,
+  cancelQuoteRequest,
+  declineQuoteRequest,
+  getQuoteRequests,
+  type QuoteRequest,
+} from '@/lib/api'
+import { Card, CardContent } from '@/components/ui/card'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Skeleton } from '@/components/ui/page-loading'
+import { useLocale } from '@/hooks/use-locale'
+import { usePageTitle } from '@/hooks/use-page-title'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatCurrency } from '@/lib/utils'
+import { getQuoteStatusConfig } from '@/components/orders/quote-status'
+
+type QuoteAction = 'accept' | 'decline' | 'cancel'
+
+export default function QuoteRequestsPage() {
+  const [page, setPage] = useState(1)
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  usePageTitle(t.pageTitle.quoteRequests)
+  const toast = useToast()
+  const statusConfig = getQuoteStatusConfig(t)
+  const limit = 10
+
+  const { data, isLoading, refetch } = useQuery({
+    queryKey: ['quoteRequests', page],
+    queryFn: () => getQuoteRequests({ page, limit }),
+  })
+  const quotes: QuoteRequest[] = data?.data?.items || []
+  const totalPages = Number(data?.data?.pagination?.total_pages || 0)
+
+  const formatTime = (value: string) =>
+    format(new Date(value), 'yyyy-MM-dd HH:mm', {
+      locale: locale === 'zh' ? zhCN : undefined,
+    })
+
+  const actionMutation = useMutation({
+    mutationFn: ({ id, action }: { id: number; action: QuoteAction }) => {
+      if (action === 'accept') return acceptQuoteRequest(id)
+      if (action === 'decline') return declineQuoteRequest(id)
+      return cancelQuoteRequest(id)
+    },
+    onSuccess: (_, { action }) => {
+      toast.success(action === 'accept' ? t.quoteRequest.acceptedHint : t.quoteRequest.updated)
+      refetch()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.quoteRequest.updateFailed))
+    },
+  })
+
+  return (
+    <div className="space-y-6">
+      <div>
+        <h1 className="text-3xl font-bold">{t.quoteRequest.myQuotes}</h1>
+        <p className="mt-1 text-sm text-muted-foreground">{t.quoteRequest.myQuotesDesc}</p>
+      </div>
+
+      {isLoading ? (
+        <div className="space-y-4">
+          {[...Array(3)].map((_, index) => (
+            <Card key={index}>
+              <CardContent className="space-y-2 p-4">
+                <Skeleton className="h-4 w-2/3" />
+                <Skeleton className="h-3 w-32" />
+              </CardContent>
+            </Card>
+          ))}
+        </div>
+      ) : quotes.length === 0 ? (
+        <Card>
+          <CardContent className="py-12 text-center">
+            <FileText className="mx-auto mb-4 h-12 w-12 text-muted-foreground" />
+            <p className="text-base font-medium">{t.quoteRequest.noQuotes}</p>
+          </CardContent>
+        </Card>
+      ) : (
+        <>
+          <div className="space-y-4">
+            {quotes.map((quote) => {
+              const config = statusConfig[quote.status] || statusConfig.pending
+              const expired =
+                quote.status === 'quoted' &&
+                !!quote.valid_until &&
+                new Date(quote.valid_until) < new Date()
+              return (
+                <Card key={quote.id}>
+                  <CardContent className="space-y-3 p-4">
+                    <div className="flex items-start justify-between gap-2">
+                      <div className="min-w-0">
+                        <Link
+                          href={`/products/${quote.product_id}`}
+                          className="font-medium hover:underline"
+                        >
+                          {quote.product_name}
+                        </Link>
+                        <p className="mt-1 text-xs text-muted-foreground">
+                          {quote.quote_no} · {formatTime(quote.created_at)} · ×{quote.quantity}
+                        </p>
+                      </div>
+                      <Badge className={config.color}>{config.label}</Badge>
+                    </div>
+                    {quote.quoted_unit_price_minor > 0 && (
+                      <div className="rounded-lg border border-border bg-muted/30 p-3 text-sm">
+                        <div className="flex flex-wrap items-baseline gap-x-4 gap-y-1">
+                          <span>
+                            {t.quoteRequest.unitPrice}:{' '}
+                            <span className="font-semibold">
+                              {formatCurrency(quote.quoted_unit_price_minor, quote.currency)}
+                            </span>
+                          </span>
+                          <span>
+                            {t.quoteRequest.total}:{' '}
+                            <span className="font-semibold">
+                              {formatCurrency(
+                                quote.quoted_unit_price_minor * quote.quantity,
+                                quote.currency
+                              )}
+                            </span>
+                          </span>
+                          {quote.valid_until && (
+                            <span className={expired ? 'text-red-600' : 'text-muted-foreground'}>
+                              {t.quoteRequest.validUntil}: {formatTime(quote.valid_until)}
+                            </span>
+                          )}
+                        </div>
+                        {quote.admin_reply && (
+                          <p className="mt-2 whitespace-pre-wrap text-muted-foreground">
+                            {quote.admin_reply}
+                          </p>
+                        )}
+                      </div>
+                    )}
+                    {quote.quoted_unit_price_minor === 0 && quote.admin_reply && (
+                      <p className="whitespace-pre-wrap text-sm text-muted-foreground">
+                        {quote.admin_reply}
+                      </p>
+                    )}
+                    {(quote.status === 'pending' || quote.status === 'quoted') && (
+                      <div className="flex flex-wrap gap-2">
+                        {quote.status === 'quoted' && !expired && (
+                          <Button
+                            size="sm"
+                            disabled={actionMutation.isPending}
+                            onClick={() =>
+                              actionMutation.mutate({ id: quote.id, action: 'accept' })
+                            }
+                          >
+                            {t.quoteRequest.accept}
+                          </Button>
+                        )}
+                        {quote.status === 'quoted' && (
+                          <Button
+                            size="sm"
+                            variant="outline"
+                            disabled={actionMutation.isPending}
+                            onClick={() =>
+                              actionMutation.mutate({ id: quote.id, action: 'decline' })
+                            }
+                          >
+                            {t.quoteRequest.decline}
+                          </Button>
+                        )}
+                        <Button
+                          size="sm"
+                          variant="ghost"
+                          disabled={actionMutation.isPending}
+                          onClick={() => actionMutation.mutate({ id: quote.id, action: 'cancel' })}
+                        >
+                          {t.quoteRequest.withdraw}
+                        </Button>
+                      </div>
+                    )}
+                  </CardContent>
+                </Card>
+              )
+            })}
+          </div>
+
+          {totalPages > 1 && (
+            <div className="flex items-center justify-center gap-2 pt-2">
+              <Button
+                variant="outline"
+                size="sm"
+                onClick={() => setPage((p) => Math.max(1, p - 1))}
+                disabled={page === 1}
+                aria-label={t.common.prevPage}
+                title={t.common.prevPage}
+              >
+                <ChevronLeft className="h-4 w-4" />
+                <span className="sr-only">{t.common.prevPage}</span>
+              </Button>
+              <span className="px-2 text-sm text-muted-foreground">
+                {t.common.pageInfo
+                  .replace('{page}', String(page))
+                  .replace('{totalPages}', String(totalPages))}
+              </span>
+              <Button
+                variant="outline"
+                size="sm"
+                onClick={() => setPage((p) => Math.min(totalPages, p + 1))}
+                disabled={page === totalPages}
+                aria-label={t.common.nextPage}
+                title={t.common.nextPage}
+              >
+                <ChevronRight className="h-4 w-4" />
+                <span className="sr-only">{t.common.nextPage}</span>
+              </Button>
+            </div>
+          )}
+        </>
+      )}
+    </div>
+  )
+}
@@ -156,4 +156,53 @@ describe('backend proxy route', () => {
       data: { id: 9, email: 'legacy@example.com' },
     })
   })
+
+  test('forwards backend session cookies and relays set-cookie for cookie sessions', async () => {
+    fetchMock.mockResolvedValueOnce(
+      new Response(JSON.stringify({ data: { token_type: 'Cookie', csrf_token: 'csrf-1' } }), {
+        status: 200,
+        headers: [
+          ['content-type', 'application/json'],
+          ['set-cookie', 'auralogic_sid=jwt; Path=/; HttpOnly; SameSite=Lax'],
+          ['set-cookie', 'auralogic_csrf=csrf-1; Path=/; SameSite=Lax'],
+        ],
+      })
+    )
+
+    const request = new Request('https://frontend.example.com/api/_backend/api/user/auth/login', {
+      method: 'POST',
+      headers: {
+        'content-type': 'application/json',
+        cookie: `${AUTH_TOKEN_COOKIE_NAME}=stale-token; auralogic_csrf=old`,
+        origin: 'https://frontend.example.com',
+        'x-auth-mode': 'cookie',
+        'x-csrf-token': 'old',
+        'x-forwarded-proto': 'https',
+      },
+      body: JSON.stringify({ email: 'user@example.com', password: 'secret' }),
+    })
+
+    const response = await POST(request, {
+      params: Promise.resolve({ path: ['api', 'user', 'auth', 'login'] }),
+    })
+
+    const upstreamHeaders = (fetchMock.mock.calls[0][1] as RequestInit).headers as Headers
+    expect(upstreamHeaders.get('authorization')).toBeNull()
+    expect(upstreamHeaders.get('x-auth-mode')).toBe('cookie')
+    expect(upstreamHeaders.get('x-csrf-token')).toBe('old')
+    expect(upstreamHeaders.get('origin')).toBe('https://frontend.example.com')
+    expect(upstreamHeaders.get('cookie')).toContain('auralogic_csrf=old')
+
+    const setCookies = response.headers.getSetCookie()
+    expect(setCookies).toEqual(
+      expect.arrayContaining([
+        expect.stringContaining('auralogic_sid=jwt'),
+        expect.stringContaining('auralogic_csrf=csrf-1'),
+      ])
+    )
+    expect(response.cookies.get(AUTH_TOKEN_COOKIE_NAME)?.value).toBe('')
+    await expect(response.json()).resolves.toEqual({
+      data: { token_type: 'Cookie', csrf_token: 'csrf-1' },
+    })
+  })
 })
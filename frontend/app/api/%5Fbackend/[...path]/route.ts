@@ -6,6 +6,7 @@ import {
   copyProxyResponseHeaders,
   deriveCookieMaxAge,
   getBearerToken,
+  isCookieSessionRequest,
   isSecureRequest,
   joinBaseURL,
   readPayloadToken,
@@ -39,17 +40,21 @@ function applySessionCookies(response: NextResponse, token: string, request: Req
   })
 }
 
-function clearSessionCookies(response: NextResponse, request: Request) {
-  const secure = isSecureRequest(request)
+function clearAuthTokenCookie(response: NextResponse, request: Request) {
   response.cookies.set({
     name: AUTH_TOKEN_COOKIE_NAME,
     value: '',
     httpOnly: true,
     sameSite: 'lax',
-    secure,
+    secure: isSecureRequest(request),
     path: '/',
     maxAge: 0,
   })
+}
+
+function clearSessionCookies(response: NextResponse, request: Request) {
+  const secure = isSecureRequest(request)
+  clearAuthTokenCookie(response, request)
   response.cookies.set({
     name: AUTH_SESSION_HINT_COOKIE_NAME,
     value: '',
@@ -75,7 +80,9 @@ async function handleProxyRequest(
 
   const authCookieToken = readRequestCookie(request, AUTH_TOKEN_COOKIE_NAME)
   const legacyBearerToken = getBearerToken(request.headers.get('authorization'))
-  const bearerToken = authCookieToken || legacyBearerToken
+  // Cookie 会话由后端会话 Cookie 鉴权，不再附加代理保存的 Bearer Token
+  const cookieSession = isCookieSessionRequest(request)
+  const bearerToken = cookieSession ? undefined : authCookieToken || legacyBearerToken
   const hasBody = request.method !== 'GET' && request.method !== 'HEAD'
   const upstreamResponse = await fetch(upstreamURL, {
     method: request.method,
@@ -103,15 +110,22 @@ async function handleProxyRequest(
     const tokenToPersist = issuedToken || adoptedLegacyToken
     response = NextResponse.json(issuedToken ? stripPayloadToken(payload) : (payload ?? {}), {
       status: upstreamResponse.status,
-      headers: copyProxyResponseHeaders(upstreamResponse.headers),
+      headers: copyProxyResponseHeaders(upstreamResponse.headers, cookieSession),
     })
     if (tokenToPersist) {
       applySessionCookies(response, tokenToPersist, request)
+    } else if (
+      cookieSession &&
+      TOKEN_ISSUING_PATHS.has(normalizedPath) &&
+      upstreamResponse.status < 400
+    ) {
+      // 以 Cookie 会话登录后，清除此前代理保存的 Bearer Token
+      clearAuthTokenCookie(response, request)
     }
   } else {
     response = new NextResponse(upstreamResponse.body, {
       status: upstreamResponse.status,
-      headers: copyProxyResponseHeaders(upstreamResponse.headers),
+      headers: copyProxyResponseHeaders(upstreamResponse.headers, cookieSession),
     })
     if (
       shouldAdoptLegacyBearer(
@@ -0,0 +1,134 @@
+'use client'
+
+import { Suspense } from 'react'
+import { useParams, useRouter, useSearchParams } from 'next/navigation'
+import { useQuery } from '@tanstack/react-query'
+import { Copy, KeyRound, Loader2, XCircle } from 'lucide-react'
+import toast from 'react-hot-toast'
+import { getVirtualDeliveryBySignedLink } from '@/lib/api'
+import { formatDate } from '@/lib/utils'
+import { Button } from '@/components/ui/button'
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
+import { useLocale } from '@/hooks/use-locale'
+import { usePageTitle } from '@/hooks/use-page-title'
+import { getTranslations } from '@/lib/i18n'
+
+interface DeliveryStock {
+  id: number
+  product_name: string
+  content: string
+  remark?: string
+  delivered_at?: string
+}
+
+export default function VirtualDeliveryPage() {
+  return (
+    <Suspense
+      fallback={
+        <div className="flex min-h-screen items-center justify-center bg-background p-6">
+          <Loader2 className="h-8 w-8 animate-spin text-primary" />
+        </div>
+      }
+    >
+      <VirtualDeliveryContent />
+    </Suspense>
+  )
+}
+
+function VirtualDeliveryContent() {
+  const params = useParams()
+  const searchParams = useSearchParams()
+  const router = useRouter()
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  usePageTitle(t.pageTitle.virtualDelivery)
+
+  const orderNo = decodeURIComponent(String(params.orderNo || ''))
+  const expires = searchParams.get('expires') || ''
+  const sig = searchParams.get('sig') || ''
+  const hasLink = Boolean(orderNo && expires && sig)
+
+  const { data, isLoading, isError } = useQuery({
+    queryKey: ['virtualDelivery', orderNo, expires, sig],
+    queryFn: () => getVirtualDeliveryBySignedLink(orderNo, { expires, sig }),
+    enabled: hasLink,
+    retry: false,
+  })
+
+  const stocks: DeliveryStock[] = data?.data?.stocks || []
+  const expiresAt: string | undefined = data?.data?.expires_at
+
+  const copyContent = async (content: string) => {
+    try {
+      await navigator.clipboard.writeText(content)
+      toast.success(t.virtualDelivery.copied)
+    } catch {
+      // ignore
+    }
+  }
+
+  if (hasLink && isLoading) {
+    return (
+      <div className="flex min-h-screen items-center justify-center bg-background p-6">
+        <Loader2 className="h-8 w-8 animate-spin text-primary" />
+      </div>
+    )
+  }
+
+  const invalid = !hasLink || isError
+
+  return (
+    <div className="flex min-h-screen items-center justify-center bg-background p-6">
+      <Card className="w-full max-w-xl">
+        <CardHeader className="text-center">
+          <div className="mx-auto mb-4 flex h-16 w-16 items-center justify-center rounded-full bg-primary/10">
+            {invalid ? (
+              <XCircle className="h-8 w-8 text-destructive" />
+            ) : (
+              <KeyRound className="h-8 w-8 text-primary" />
+            )}
+          </div>
+          <CardTitle>{t.virtualDelivery.title}</CardTitle>
+          <CardDescription>
+            {invalid
+              ? t.virtualDelivery.invalidLink
+              : t.virtualDelivery.description.replace('{orderNo}', orderNo)}
+          </CardDescription>
+        </CardHeader>
+        <CardContent className="space-y-3">
+          {!invalid && stocks.length === 0 && (
+            <p className="text-center text-sm text-muted-foreground">{t.virtualDelivery.empty}</p>
+          )}
+          {!invalid &&
+            stocks.map((stock) => (
+              <div key={stock.id} className="space-y-2 rounded-md border p-3">
+                <div className="flex items-center justify-between gap-2">
+                  <span className="text-sm font-medium">{stock.product_name}</span>
+                  <Button variant="ghost" size="sm" onClick={() => copyContent(stock.content)}>
+                    <Copy className="mr-1 h-4 w-4" />
+                    {t.virtualDelivery.copy}
+                  </Button>
+                </div>
+                <pre className="whitespace-pre-wrap break-all rounded bg-muted p-2 font-mono text-sm">
+                  {stock.content}
+                </pre>
+                {stock.remark && <p className="text-xs text-muted-foreground">{stock.remark}</p>}
+              </div>
+            ))}
+          {!invalid && expiresAt && (
+            <p className="text-center text-xs text-muted-foreground">
+              {t.virtualDelivery.expiresAt.replace('{time}', formatDate(expiresAt))}
+            </p>
+          )}
+          <Button
+            variant="outline"
+            className="w-full"
+            onClick={() => router.push(`/orders/${encodeURIComponent(orderNo)}`)}
+          >
+            {t.virtualDelivery.viewOrder}
+          </Button>
+        </CardContent>
+      </Card>
+    </div>
+  )
+}
@@ -5,6 +5,7 @@ import { useState } from 'react'
 import { LocaleProvider } from '@/contexts/locale-context'
 import { CurrencyProvider } from '@/contexts/currency-context'
 import { ThemeProvider } from '@/contexts/theme-context'
+import { DemoModeWatermark } from '@/components/demo-mode-watermark'
 
 export function Providers({ children }: { children: React.ReactNode }) {
   const [queryClient] = useState(
@@ -25,6 +26,7 @@ export function Providers({ children }: { children: React.ReactNode }) {
         <LocaleProvider>
           <CurrencyProvider>
             {children}
+            <DemoModeWatermark />
           </CurrencyProvider>
         </LocaleProvider>
       </ThemeProvider>
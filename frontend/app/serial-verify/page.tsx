@@ -26,6 +26,14 @@ import { useLocale } from '@/hooks/use-locale'
 import { useAuth } from '@/hooks/use-auth'
 import { useResponsiveLayout } from '@/hooks/use-mobile'
 import { getTranslations } from '@/lib/i18n'
+import {
+  isInteractiveCaptchaProvider,
+  isWidgetCaptchaProvider,
+  loadCaptchaScript,
+  renderCaptchaWidget,
+  resetCaptchaWidget,
+  resolveCaptchaScene,
+} from '@/lib/captcha'
 import { usePageTitle } from '@/hooks/use-page-title'
 import { UserSidebar } from '@/components/layout/user-sidebar'
 import { MobileBottomNav } from '@/components/layout/mobile-bottom-nav'
@@ -44,6 +52,7 @@ interface SerialInfo {
   view_count: number
   first_viewed_at?: string
   last_viewed_at?: string
+  invalidated_at?: string
   created_at: string
   product?: {
     id: number
@@ -93,7 +102,7 @@ function SerialVerifyContent() {
   const resolvedLocale = lang === 'en' ? 'en' : 'zh'
   const i18n = getTranslations(resolvedLocale)
   const t = i18n.serialVerify
-  const captchaCfg = publicConfig?.captcha
+  const captchaCfg = resolveCaptchaScene(publicConfig?.captcha, 'serial_verify')
   const needCaptcha =
     captchaCfg?.provider && captchaCfg.provider !== 'none' && !!captchaCfg.enable_for_serial_verify
 
@@ -142,68 +151,24 @@ function SerialVerifyContent() {
     return () => clearInterval(timer)
   }, [needCaptcha, captchaCfg?.provider])
 
+  // Load captcha script and render widget
   useEffect(() => {
-    if (!needCaptcha) return
-    if (captchaCfg?.provider === 'cloudflare' && !document.getElementById('cf-turnstile-script')) {
-      const script = document.createElement('script')
-      script.id = 'cf-turnstile-script'
-      script.src = 'https://challenges.cloudflare.com/turnstile/v0/api.js?onload=onTurnstileLoad'
-      script.async = true
-      ;(window as any).onTurnstileLoad = () => {
-        if (captchaContainerRef.current && !widgetRendered.current) {
-          widgetRendered.current = true
-          widgetIdRef.current = (window as any).turnstile.render(captchaContainerRef.current, {
-            sitekey: captchaCfg?.site_key,
-            theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-            callback: (token: string) => setCaptchaToken(token),
-            'expired-callback': () => setCaptchaToken(''),
-          })
-        }
-      }
-      document.body.appendChild(script)
-    } else if (captchaCfg?.provider === 'google' && !document.getElementById('recaptcha-script')) {
-      const script = document.createElement('script')
-      script.id = 'recaptcha-script'
-      script.src = 'https://www.google.com/recaptcha/api.js?onload=onRecaptchaLoad&render=explicit'
-      script.async = true
-      ;(window as any).onRecaptchaLoad = () => {
-        if (captchaContainerRef.current && !widgetRendered.current) {
-          widgetRendered.current = true
-          widgetIdRef.current = (window as any).grecaptcha.render(captchaContainerRef.current, {
-            sitekey: captchaCfg?.site_key,
-            theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-            callback: (token: string) => setCaptchaToken(token),
-            'expired-callback': () => setCaptchaToken(''),
-          })
-        }
-      }
-      document.body.appendChild(script)
-    }
-  }, [needCaptcha, captchaCfg, resolvedTheme])
-
-  useEffect(() => {
-    if (!needCaptcha || widgetRendered.current || !captchaContainerRef.current) return
-    if (captchaCfg?.provider === 'cloudflare' && (window as any).turnstile) {
+    if (!needCaptcha || !isWidgetCaptchaProvider(captchaCfg?.provider)) return
+    loadCaptchaScript(captchaCfg.provider, captchaCfg.site_key, () => {
+      if (!captchaContainerRef.current || widgetRendered.current) return
       widgetRendered.current = true
-      widgetIdRef.current = (window as any).turnstile.render(captchaContainerRef.current, {
-        sitekey: captchaCfg?.site_key,
+      widgetIdRef.current = renderCaptchaWidget(captchaCfg.provider, captchaContainerRef.current, {
+        siteKey: captchaCfg.site_key,
         theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-        callback: (token: string) => setCaptchaToken(token),
-        'expired-callback': () => setCaptchaToken(''),
+        action: 'serial_verify',
+        onToken: (token) => setCaptchaToken(token),
+        onExpire: () => setCaptchaToken(''),
       })
-    } else if (captchaCfg?.provider === 'google' && (window as any).grecaptcha?.render) {
-      widgetRendered.current = true
-      widgetIdRef.current = (window as any).grecaptcha.render(captchaContainerRef.current, {
-        sitekey: captchaCfg?.site_key,
-        theme: resolvedTheme === 'dark' ? 'dark' : 'light',
-        callback: (token: string) => setCaptchaToken(token),
-        'expired-callback': () => setCaptchaToken(''),
-      })
-    }
+    })
   }, [needCaptcha, captchaCfg, resolvedTheme])
 
   useEffect(() => {
-    if (!captchaToken || !needCaptcha || captchaCfg?.provider === 'builtin') return
+    if (!captchaToken || !needCaptcha || !isInteractiveCaptchaProvider(captchaCfg?.provider)) return
     if (serialNumber.trim() && !isLoading) {
       handleVerify()
     }
@@ -228,19 +193,8 @@ function SerialVerifyContent() {
     if (!needCaptcha) return
     if (captchaCfg?.provider === 'builtin') {
       refreshBuiltinCaptcha()
-    } else if (captchaCfg?.provider === 'cloudflare' && (window as any).turnstile) {
-      try {
-        ;(window as any).turnstile.reset(widgetIdRef.current)
-      } catch {
-        // ignore
-      }
-      setCaptchaToken('')
-    } else if (captchaCfg?.provider === 'google' && (window as any).grecaptcha) {
-      try {
-        ;(window as any).grecaptcha.reset(widgetIdRef.current)
-      } catch {
-        // ignore
-      }
+    } else if (isWidgetCaptchaProvider(captchaCfg?.provider)) {
+      resetCaptchaWidget(captchaCfg.provider, widgetIdRef.current)
       setCaptchaToken('')
     }
   }
@@ -482,7 +436,7 @@ function SerialVerifyContent() {
 
           {needCaptcha ? (
             <div className="space-y-2">
-              {(captchaCfg?.provider === 'cloudflare' || captchaCfg?.provider === 'google') && (
+              {isWidgetCaptchaProvider(captchaCfg?.provider) && (
                 <div ref={captchaContainerRef} />
               )}
               {captchaCfg?.provider === 'builtin' && builtinCaptcha?.image ? (
@@ -707,6 +661,17 @@ function SerialVerifyContent() {
                 </div>
               ) : null}
 
+              {serialInfo.invalidated_at ? (
+                <Alert variant="destructive">
+                  <AlertTriangle className="h-4 w-4" />
+                  <AlertDescription className="text-sm">
+                    <p className="font-medium">{t.invalidatedTitle}</p>
+                    <p>
+                      {t.invalidatedDesc.replace('{date}', formatDate(serialInfo.invalidated_at))}
+                    </p>
+                  </AlertDescription>
+                </Alert>
+              ) : null}
               {serialInfo.view_count > 5 ? (
                 <Alert>
                   <AlertTriangle className="h-4 w-4" />
@@ -0,0 +1,61 @@
+'use client'
+
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+
+export interface DimensionValues {
+  weight_grams?: number
+  length_mm: number
+  width_mm: number
+  height_mm: number
+}
+
+interface DimensionInputsProps {
+  value: DimensionValues
+  onChange: (value: DimensionValues) => void
+  // 商品表单的重量在报关信息中填写
+  withWeight?: boolean
+  hint?: string
+}
+
+// DimensionInputs 重量（克）与长宽高（毫米）输入，0 表示未设置
+export function DimensionInputs({
+  value,
+  onChange,
+  withWeight = true,
+  hint,
+}: DimensionInputsProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const fields: Array<{ key: keyof DimensionValues; placeholder: string }> = [
+    ...(withWeight
+      ? [{ key: 'weight_grams' as const, placeholder: t.admin.weightGramsPlaceholder }]
+      : []),
+    { key: 'length_mm', placeholder: t.admin.lengthMmPlaceholder },
+    { key: 'width_mm', placeholder: t.admin.widthMmPlaceholder },
+    { key: 'height_mm', placeholder: t.admin.heightMmPlaceholder },
+  ]
+
+  return (
+    <div className="space-y-2">
+      <Label>{withWeight ? t.admin.weightAndDimensions : t.admin.packageDimensions}</Label>
+      <div
+        className={`grid grid-cols-2 gap-4 ${withWeight ? 'md:grid-cols-4' : 'md:grid-cols-3'}`}
+      >
+        {fields.map((field) => (
+          <Input
+            key={field.key}
+            type="number"
+            min="0"
+            value={value[field.key] || ''}
+            onChange={(e) => onChange({ ...value, [field.key]: parseInt(e.target.value, 10) || 0 })}
+            placeholder={field.placeholder}
+          />
+        ))}
+      </div>
+      {hint && <p className="text-xs text-muted-foreground">{hint}</p>}
+    </div>
+  )
+}
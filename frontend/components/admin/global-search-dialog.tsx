@@ -0,0 +1,241 @@
+'use client'
+
+import { useEffect, useState, type ReactNode } from 'react'
+import { useRouter } from 'next/navigation'
+import { useQuery } from '@tanstack/react-query'
+import { Loader2, Search, Zap } from 'lucide-react'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Input } from '@/components/ui/input'
+import { Dialog, DialogContent, DialogHeader, DialogTitle } from '@/components/ui/dialog'
+import { useDebounce } from '@/hooks/use-debounce'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations, type Translations } from '@/lib/i18n'
+import {
+  getAdminPalette,
+  searchAdmin,
+  type AdminPalette,
+  type AdminSearchResult,
+  type AdminSearchResultType,
+} from '@/lib/api'
+
+const RESULT_TYPES: AdminSearchResultType[] = ['order', 'user', 'ticket', 'product', 'serial']
+
+function typeLabel(t: Translations, type: AdminSearchResultType) {
+  const labels: Record<AdminSearchResultType, string> = {
+    order: t.admin.globalSearchTypeOrder,
+    user: t.admin.globalSearchTypeUser,
+    ticket: t.admin.globalSearchTypeTicket,
+    product: t.admin.globalSearchTypeProduct,
+    serial: t.admin.globalSearchTypeSerial,
+  }
+  return labels[type]
+}
+
+function quickActionLabel(t: Translations, id: string) {
+  const labels: Record<string, string> = {
+    pending_shipment: t.admin.quickActionPendingShipment,
+    create_product: t.admin.quickActionCreateProduct,
+    tickets: t.admin.quickActionTickets,
+    return_requests: t.admin.quickActionReturnRequests,
+    users: t.admin.quickActionUsers,
+    serials: t.admin.quickActionSerials,
+    announcements: t.admin.quickActionAnnouncements,
+    operation_logs: t.admin.quickActionOperationLogs,
+    dashboard: t.admin.quickActionDashboard,
+  }
+  return labels[id] || id
+}
+
+function ResultRow({
+  result,
+  onSelect,
+}: {
+  result: AdminSearchResult
+  onSelect: (link: string) => void
+}) {
+  return (
+    <button
+      type="button"
+      onClick={() => onSelect(result.link)}
+      className="flex w-full items-center justify-between gap-3 rounded-md px-2 py-2 text-left text-sm hover:bg-accent"
+    >
+      <div className="min-w-0">
+        <div className="truncate font-medium">{result.title}</div>
+        {result.subtitle && (
+          <div className="truncate text-xs text-muted-foreground">{result.subtitle}</div>
+        )}
+      </div>
+      {result.status && (
+        <Badge variant="outline" className="shrink-0">
+          {result.status}
+        </Badge>
+      )}
+    </button>
+  )
+}
+
+function Section({ title, children }: { title: string; children: ReactNode }) {
+  return (
+    <div className="space-y-1">
+      <div className="px-1 text-xs font-medium uppercase text-muted-foreground">{title}</div>
+      {children}
+    </div>
+  )
+}
+
+// GlobalSearchDialog 管理端全局搜索与命令面板，支持 Ctrl/⌘ + K 打开；
+// 未输入关键词时展示快捷操作、分配给自己的工单和最近处理的实体
+export function GlobalSearchDialog() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const router = useRouter()
+  const [open, setOpen] = useState(false)
+  const [query, setQuery] = useState('')
+  const debouncedQuery = useDebounce(query.trim(), 300)
+  const enabled = open && debouncedQuery.length >= 2
+
+  useEffect(() => {
+    const handleKeyDown = (event: KeyboardEvent) => {
+      if ((event.metaKey || event.ctrlKey) && event.key.toLowerCase() === 'k') {
+        event.preventDefault()
+        setOpen(true)
+      }
+    }
+    window.addEventListener('keydown', handleKeyDown)
+    return () => window.removeEventListener('keydown', handleKeyDown)
+  }, [])
+
+  const { data, isFetching, isError } = useQuery({
+    queryKey: ['adminGlobalSearch', debouncedQuery],
+    queryFn: () => searchAdmin(debouncedQuery),
+    enabled,
+    staleTime: 30_000,
+  })
+  const results: AdminSearchResult[] = enabled ? data?.data?.items || [] : []
+
+  const { data: paletteData } = useQuery({
+    queryKey: ['adminPalette'],
+    queryFn: getAdminPalette,
+    enabled: open,
+    staleTime: 60_000,
+  })
+  const palette: AdminPalette | undefined = paletteData?.data
+  const showPalette = query.trim().length === 0
+
+  const openLink = (link: string) => {
+    setOpen(false)
+    setQuery('')
+    router.push(link)
+  }
+
+  return (
+    <>
+      <Button
+        variant="outline"
+        size="sm"
+        className="mt-3 w-full justify-between text-muted-foreground"
+        onClick={() => setOpen(true)}
+      >
+        <span className="flex items-center gap-2">
+          <Search className="h-4 w-4" />
+          {t.admin.globalSearch}
+        </span>
+        <kbd className="rounded border px-1.5 text-[10px]">Ctrl K</kbd>
+      </Button>
+      <Dialog open={open} onOpenChange={setOpen}>
+        <DialogContent className="max-w-xl">
+          <DialogHeader>
+            <DialogTitle>{t.admin.globalSearch}</DialogTitle>
+          </DialogHeader>
+          <div className="relative">
+            <Search className="absolute left-3 top-1/2 h-4 w-4 -translate-y-1/2 text-muted-foreground" />
+            <Input
+              autoFocus
+              value={query}
+              onChange={(e) => setQuery(e.target.value)}
+              onKeyDown={(e) => {
+                if (e.key === 'Enter' && results.length > 0) openLink(results[0].link)
+              }}
+              placeholder={t.admin.globalSearchPlaceholder}
+              maxLength={100}
+              className="pl-9"
+            />
+            {isFetching && (
+              <Loader2 className="absolute right-3 top-1/2 h-4 w-4 -translate-y-1/2 animate-spin text-muted-foreground" />
+            )}
+          </div>
+          <div className="max-h-[60vh] space-y-4 overflow-y-auto">
+            {showPalette && palette && (
+              <>
+                {palette.actions.length > 0 && (
+                  <Section title={t.admin.paletteQuickActions}>
+                    {palette.actions.map((action) => (
+                      <button
+                        key={action.id}
+                        type="button"
+                        onClick={() => openLink(action.link)}
+                        className="flex w-full items-center gap-2 rounded-md px-2 py-2 text-left text-sm hover:bg-accent"
+                      >
+                        <Zap className="h-4 w-4 text-muted-foreground" />
+                        {quickActionLabel(t, action.id)}
+                      </button>
+                    ))}
+                  </Section>
+                )}
+                {palette.assigned_tickets.length > 0 && (
+                  <Section title={t.admin.paletteAssignedTickets}>
+                    {palette.assigned_tickets.map((result) => (
+                      <ResultRow key={`ticket-${result.id}`} result={result} onSelect={openLink} />
+                    ))}
+                  </Section>
+                )}
+                {palette.recent.length > 0 && (
+                  <Section title={t.admin.paletteRecent}>
+                    {palette.recent.map((result) => (
+                      <ResultRow
+                        key={`${result.type}-${result.id}`}
+                        result={result}
+                        onSelect={openLink}
+                      />
+                    ))}
+                  </Section>
+                )}
+              </>
+            )}
+            {!showPalette && !enabled && (
+              <p className="py-6 text-center text-sm text-muted-foreground">
+                {t.admin.globalSearchHint}
+              </p>
+            )}
+            {enabled && isError && (
+              <p className="py-6 text-center text-sm text-destructive">
+                {t.admin.globalSearchFailed}
+              </p>
+            )}
+            {enabled && !isError && !isFetching && results.length === 0 && (
+              <p className="py-6 text-center text-sm text-muted-foreground">
+                {t.admin.globalSearchEmpty}
+              </p>
+            )}
+            {RESULT_TYPES.map((type) => {
+              const group = results.filter((result) => result.type === type)
+              if (group.length === 0) return null
+              return (
+                <Section key={type} title={typeLabel(t, type)}>
+                  {group.map((result) => (
+                    <ResultRow
+                      key={`${result.type}-${result.id}`}
+                      result={result}
+                      onSelect={openLink}
+                    />
+                  ))}
+                </Section>
+              )
+            })}
+          </div>
+        </DialogContent>
+      </Dialog>
+    </>
+  )
+}
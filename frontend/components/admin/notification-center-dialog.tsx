@@ -0,0 +1,427 @@
+'use client'
+
+import { useState } from 'react'
+import { useRouter } from 'next/navigation'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
+import { Bell, CheckCheck, Pencil, Plus, Trash2 } from 'lucide-react'
+import { Badge } from '@/components/ui/badge'
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Switch } from '@/components/ui/switch'
+import {
+  Dialog,
+  DialogContent,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select'
+import { Tabs, TabsContent, TabsList, TabsTrigger } from '@/components/ui/tabs'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations, type Translations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatDate } from '@/lib/utils'
+import {
+  createAdminNotificationSubscription,
+  deleteAdminNotificationSubscription,
+  getAdminNotificationMeta,
+  getAdminNotifications,
+  getAdminNotificationSubscriptions,
+  getAdminNotificationUnreadCount,
+  markAdminNotificationRead,
+  markAllAdminNotificationsRead,
+  updateAdminNotificationSubscription,
+  type AdminNotification,
+  type AdminNotificationChannel,
+  type AdminNotificationMeta,
+  type AdminNotificationSubscription,
+  type AdminNotificationSubscriptionInput,
+} from '@/lib/api'
+
+const emptyInput: AdminNotificationSubscriptionInput = {
+  event: 'order.created',
+  channel: 'in_app',
+  countries: [],
+  categories: [],
+  telegram_chat_id: '',
+  enabled: true,
+}
+
+function channelLabel(t: Translations, channel: AdminNotificationChannel) {
+  const labels: Record<AdminNotificationChannel, string> = {
+    email: t.admin.notificationChannelEmail,
+    telegram: t.admin.notificationChannelTelegram,
+    in_app: t.admin.notificationChannelInApp,
+  }
+  return labels[channel] || channel
+}
+
+function splitList(value: string) {
+  return value
+    .split(',')
+    .map((item) => item.trim())
+    .filter(Boolean)
+}
+
+// NotificationCenterDialog 侧栏通知入口：站内通知收件箱与个人事件订阅
+export function NotificationCenterDialog() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const router = useRouter()
+  const queryClient = useQueryClient()
+  const [open, setOpen] = useState(false)
+  const [editing, setEditing] = useState<AdminNotificationSubscription | null>(null)
+  const [formOpen, setFormOpen] = useState(false)
+  const [input, setInput] = useState<AdminNotificationSubscriptionInput>(emptyInput)
+  // 国家/分类以逗号分隔输入，保存时再拆分，避免输入过程中逗号被吞掉
+  const [filterText, setFilterText] = useState('')
+
+  const { data: unreadData } = useQuery({
+    queryKey: ['adminNotificationUnread'],
+    queryFn: getAdminNotificationUnreadCount,
+    refetchInterval: 60 * 1000,
+  })
+  const { data: inboxData } = useQuery({
+    queryKey: ['adminNotifications'],
+    queryFn: () => getAdminNotifications({ page: 1, limit: 30 }),
+    enabled: open,
+  })
+  const { data: metaData } = useQuery({
+    queryKey: ['adminNotificationMeta'],
+    queryFn: getAdminNotificationMeta,
+    enabled: open,
+  })
+  const { data: subscriptionsData } = useQuery({
+    queryKey: ['adminNotificationSubscriptions'],
+    queryFn: getAdminNotificationSubscriptions,
+    enabled: open,
+  })
+  const unread: number = unreadData?.data?.count || 0
+  const notifications: AdminNotification[] = inboxData?.data?.items || []
+  const meta: AdminNotificationMeta | undefined = metaData?.data
+  const subscriptions: AdminNotificationSubscription[] = subscriptionsData?.data?.items || []
+  const allowedEvents = (meta?.events || [])
+    .filter((item) => item.allowed)
+    .map((item) => item.event)
+  const isOrderEvent = input.event.startsWith('order.')
+
+  const onError = (error: unknown) => {
+    toast.error(resolveApiErrorMessage(error, t, t.common.failed))
+  }
+  const invalidateInbox = () => {
+    queryClient.invalidateQueries({ queryKey: ['adminNotifications'] })
+    queryClient.invalidateQueries({ queryKey: ['adminNotificationUnread'] })
+  }
+
+  const readMutation = useMutation({
+    mutationFn: (id: number) => markAdminNotificationRead(id),
+    onSuccess: invalidateInbox,
+    onError,
+  })
+  const readAllMutation = useMutation({
+    mutationFn: markAllAdminNotificationsRead,
+    onSuccess: invalidateInbox,
+    onError,
+  })
+  const saveMutation = useMutation({
+    mutationFn: () => {
+      const data = {
+        ...input,
+        countries: isOrderEvent ? splitList(filterText) : [],
+        categories: isOrderEvent ? [] : splitList(filterText),
+      }
+      return editing
+        ? updateAdminNotificationSubscription(editing.id, data)
+        : createAdminNotificationSubscription(data)
+    },
+    onSuccess: () => {
+      toast.success(t.admin.notificationSaved)
+      queryClient.invalidateQueries({ queryKey: ['adminNotificationSubscriptions'] })
+      setFormOpen(false)
+    },
+    onError,
+  })
+  const deleteMutation = useMutation({
+    mutationFn: (id: number) => deleteAdminNotificationSubscription(id),
+    onSuccess: () => {
+      toast.success(t.admin.notificationDeleted)
+      queryClient.invalidateQueries({ queryKey: ['adminNotificationSubscriptions'] })
+    },
+    onError,
+  })
+
+  const openForm = (subscription: AdminNotificationSubscription | null) => {
+    setEditing(subscription)
+    setInput(
+      subscription
+        ? {
+            event: subscription.event,
+            channel: subscription.channel,
+            countries: subscription.countries || [],
+            categories: subscription.categories || [],
+            telegram_chat_id: subscription.telegram_chat_id || '',
+            enabled: subscription.enabled,
+          }
+        : { ...emptyInput, event: allowedEvents[0] || emptyInput.event }
+    )
+    setFilterText(
+      [...(subscription?.countries || []), ...(subscription?.categories || [])].join(', ')
+    )
+    setFormOpen(true)
+  }
+
+  const openNotification = (notification: AdminNotification) => {
+    if (!notification.read_at) {
+      readMutation.mutate(notification.id)
+    }
+    if (notification.link) {
+      setOpen(false)
+      router.push(notification.link)
+    }
+  }
+
+  return (
+    <>
+      <Button
+        variant="outline"
+        size="sm"
+        className="mt-2 w-full justify-start text-muted-foreground"
+        onClick={() => setOpen(true)}
+      >
+        <Bell className="mr-2 h-4 w-4" />
+        {t.admin.notifications}
+        {unread > 0 && (
+          <Badge variant="destructive" className="ml-auto px-1.5 py-0 text-[10px]">
+            {unread > 99 ? '99+' : unread}
+          </Badge>
+        )}
+      </Button>
+
+      <Dialog open={open} onOpenChange={setOpen}>
+        <DialogContent className="max-w-2xl">
+          <DialogHeader>
+            <DialogTitle>{t.admin.notifications}</DialogTitle>
+          </DialogHeader>
+          <Tabs defaultValue="inbox">
+            <TabsList>
+              <TabsTrigger value="inbox">{t.admin.notificationInbox}</TabsTrigger>
+              <TabsTrigger value="subscriptions">{t.admin.notificationSubscriptions}</TabsTrigger>
+            </TabsList>
+
+            <TabsContent value="inbox" className="space-y-2">
+              <div className="flex justify-end">
+                <Button
+                  variant="ghost"
+                  size="sm"
+                  disabled={unread === 0 || readAllMutation.isPending}
+                  onClick={() => readAllMutation.mutate()}
+                >
+                  <CheckCheck className="mr-1 h-4 w-4" />
+                  {t.admin.notificationMarkAllRead}
+                </Button>
+              </div>
+              <div className="max-h-[50vh] space-y-1 overflow-y-auto">
+                {notifications.length === 0 ? (
+                  <p className="py-6 text-center text-sm text-muted-foreground">
+                    {t.admin.notificationNone}
+                  </p>
+                ) : (
+                  notifications.map((notification) => (
+                    <button
+                      key={notification.id}
+                      type="button"
+                      onClick={() => openNotification(notification)}
+                      className="w-full rounded-md border px-3 py-2 text-left text-sm hover:bg-accent"
+                    >
+                      <div className="flex items-center gap-2">
+                        {!notification.read_at && (
+                          <span className="h-2 w-2 shrink-0 rounded-full bg-primary" />
+                        )}
+                        <span className="truncate font-medium">{notification.title}</span>
+                        <span className="ml-auto shrink-0 text-xs text-muted-foreground">
+                          {formatDate(notification.created_at)}
+                        </span>
+                      </div>
+                      <p className="mt-1 whitespace-pre-line text-xs text-muted-foreground">
+                        {notification.content}
+                      </p>
+                    </button>
+                  ))
+                )}
+              </div>
+            </TabsContent>
+
+            <TabsContent value="subscriptions" className="space-y-3">
+              <div className="flex items-start justify-between gap-4">
+                <p className="text-sm text-muted-foreground">
+                  {t.admin.notificationSubscriptionsDesc}
+                </p>
+                <Button size="sm" onClick={() => openForm(null)}>
+                  <Plus className="mr-1 h-4 w-4" />
+                  {t.admin.notificationSubscriptionCreate}
+                </Button>
+              </div>
+              {subscriptions.length === 0 ? (
+                <p className="py-6 text-center text-sm text-muted-foreground">
+                  {t.admin.notificationSubscriptionNone}
+                </p>
+              ) : (
+                subscriptions.map((subscription) => (
+                  <div
+                    key={subscription.id}
+                    className="flex flex-wrap items-center gap-2 rounded-md border p-2 text-sm"
+                  >
+                    <span className="font-mono text-xs">{subscription.event}</span>
+                    <Badge variant="secondary">{channelLabel(t, subscription.channel)}</Badge>
+                    {!subscription.enabled && (
+                      <Badge variant="outline">{t.admin.notificationDisabled}</Badge>
+                    )}
+                    {[...(subscription.countries || []), ...(subscription.categories || [])].map(
+                      (value) => (
+                        <Badge key={value} variant="outline" className="text-xs">
+                          {value}
+                        </Badge>
+                      )
+                    )}
+                    <div className="ml-auto flex gap-1">
+                      <Button
+                        variant="ghost"
+                        size="icon"
+                        title={t.common.edit}
+                        onClick={() => openForm(subscription)}
+                      >
+                        <Pencil className="h-4 w-4" />
+                      </Button>
+                      <Button
+                        variant="ghost"
+                        size="icon"
+                        title={t.common.delete}
+                        disabled={deleteMutation.isPending}
+                        onClick={() => deleteMutation.mutate(subscription.id)}
+                      >
+                        <Trash2 className="h-4 w-4" />
+                      </Button>
+                    </div>
+                  </div>
+                ))
+              )}
+            </TabsContent>
+          </Tabs>
+        </DialogContent>
+      </Dialog>
+
+      <Dialog open={formOpen} onOpenChange={setFormOpen}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>
+              {editing
+                ? t.admin.notificationSubscriptionEdit
+                : t.admin.notificationSubscriptionCreate}
+            </DialogTitle>
+          </DialogHeader>
+          <div className="space-y-4 py-2">
+            <div className="space-y-2">
+              <Label>{t.admin.notificationEvent}</Label>
+              <Select
+                value={input.event}
+                onValueChange={(event) => {
+                  setInput({ ...input, event })
+                  setFilterText('')
+                }}
+              >
+                <SelectTrigger>
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  {allowedEvents.map((event) => (
+                    <SelectItem key={event} value={event} className="font-mono text-xs">
+                      {event}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="space-y-2">
+              <Label>{t.admin.notificationChannel}</Label>
+              <Select
+                value={input.channel}
+                onValueChange={(channel) =>
+                  setInput({ ...input, channel: channel as AdminNotificationChannel })
+                }
+              >
+                <SelectTrigger>
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  {(meta?.channels || []).map((channel) => (
+                    <SelectItem
+                      key={channel}
+                      value={channel}
+                      disabled={channel === 'telegram' && !meta?.telegram_configured}
+                    >
+                      {channelLabel(t, channel)}
+                    </SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+              {!meta?.telegram_configured && (
+                <p className="text-xs text-muted-foreground">
+                  {t.admin.notificationTelegramUnavailable}
+                </p>
+              )}
+            </div>
+            {input.channel === 'telegram' && (
+              <div className="space-y-2">
+                <Label>{t.admin.notificationTelegramChatId}</Label>
+                <Input
+                  value={input.telegram_chat_id || ''}
+                  maxLength={64}
+                  onChange={(e) => setInput({ ...input, telegram_chat_id: e.target.value })}
+                />
+              </div>
+            )}
+            <div className="space-y-2">
+              <Label>
+                {isOrderEvent ? t.admin.notificationCountries : t.admin.notificationCategories}
+              </Label>
+              <Input
+                value={filterText}
+                placeholder={isOrderEvent ? 'US, DE' : ''}
+                onChange={(e) => setFilterText(e.target.value)}
+              />
+              <p className="text-xs text-muted-foreground">
+                {isOrderEvent
+                  ? t.admin.notificationCountriesHint
+                  : t.admin.notificationCategoriesHint}
+              </p>
+            </div>
+            <div className="flex items-center gap-2">
+              <Switch
+                checked={input.enabled}
+                onCheckedChange={(checked) => setInput({ ...input, enabled: checked })}
+              />
+              <Label>{t.admin.notificationEnabled}</Label>
+            </div>
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setFormOpen(false)}>
+              {t.common.cancel}
+            </Button>
+            <Button onClick={() => saveMutation.mutate()} disabled={saveMutation.isPending}>
+              {t.common.save}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </>
+  )
+}
@@ -0,0 +1,142 @@
+'use client'
+
+import { useState } from 'react'
+import Link from 'next/link'
+import { useMutation } from '@tanstack/react-query'
+import { Loader2, Search } from 'lucide-react'
+import toast from 'react-hot-toast'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Input } from '@/components/ui/input'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { OrderStatusBadge } from '@/components/orders/order-status-badge'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatDate } from '@/lib/utils'
+import { lookupOrdersByCode, type OrderCodeLookupMatch } from '@/lib/api'
+import type { OrderStatus } from '@/types/order'
+
+// OrderCodeLookupDialog 客服按买家提供的卡密/序列号反查订单
+export function OrderCodeLookupDialog() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const [open, setOpen] = useState(false)
+  const [code, setCode] = useState('')
+  const [matches, setMatches] = useState<OrderCodeLookupMatch[] | null>(null)
+
+  const lookupMutation = useMutation({
+    mutationFn: (value: string) => lookupOrdersByCode(value),
+    onSuccess: (response: any) => {
+      setMatches(response?.data?.items || [])
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.orderCodeLookupFailed))
+    },
+  })
+
+  const handleSubmit = (e: React.FormEvent) => {
+    e.preventDefault()
+    const value = code.trim()
+    if (!value) return
+    lookupMutation.mutate(value)
+  }
+
+  return (
+    <>
+      <Button variant="outline" size="sm" onClick={() => setOpen(true)}>
+        <Search className="mr-2 h-4 w-4" />
+        {t.admin.orderCodeLookup}
+      </Button>
+      <Dialog
+        open={open}
+        onOpenChange={(next) => {
+          setOpen(next)
+          if (!next) {
+            setCode('')
+            setMatches(null)
+          }
+        }}
+      >
+        <DialogContent className="max-w-2xl">
+          <DialogHeader>
+            <DialogTitle>{t.admin.orderCodeLookup}</DialogTitle>
+            <DialogDescription>{t.admin.orderCodeLookupDesc}</DialogDescription>
+          </DialogHeader>
+          <form onSubmit={handleSubmit} className="flex gap-2">
+            <Input
+              value={code}
+              onChange={(e) => setCode(e.target.value)}
+              placeholder={t.admin.orderCodeLookupPlaceholder}
+              className="font-mono"
+              autoComplete="off"
+            />
+            <Button type="submit" disabled={!code.trim() || lookupMutation.isPending}>
+              {lookupMutation.isPending && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+              {t.admin.orderCodeLookupSearch}
+            </Button>
+          </form>
+          {matches && matches.length === 0 && (
+            <p className="py-4 text-center text-sm text-muted-foreground">
+              {t.admin.orderCodeLookupNoMatch}
+            </p>
+          )}
+          {matches && matches.length > 0 && (
+            <div className="max-h-[50vh] space-y-2 overflow-y-auto">
+              {matches.map((match) => (
+                <div
+                  key={`${match.source}-${match.item_id}`}
+                  className="space-y-1 rounded-md border p-3"
+                >
+                  <div className="flex flex-wrap items-center gap-2">
+                    <Badge variant="outline">
+                      {match.source === 'serial'
+                        ? t.admin.orderCodeLookupSourceSerial
+                        : t.admin.orderCodeLookupSourceStock}
+                    </Badge>
+                    <code className="break-all font-mono text-sm">{match.code}</code>
+                    {match.item_name && (
+                      <span className="text-sm text-muted-foreground">{match.item_name}</span>
+                    )}
+                  </div>
+                  <div className="text-xs text-muted-foreground">
+                    {t.admin.orderCodeLookupItemStatus.replace('{status}', match.item_status)}
+                    {match.delivered_at && ` · ${formatDate(match.delivered_at)}`}
+                    {match.first_viewed_at &&
+                      ` · ${t.order.virtualStockFirstViewedAt}: ${formatDate(match.first_viewed_at)}`}
+                  </div>
+                  {match.order_id ? (
+                    <div className="flex flex-wrap items-center gap-2 text-sm">
+                      <Link
+                        href={`/admin/orders/${match.order_id}`}
+                        className="font-medium text-primary hover:underline"
+                      >
+                        {match.order_no}
+                      </Link>
+                      {match.order_status && (
+                        <OrderStatusBadge status={match.order_status as OrderStatus} />
+                      )}
+                      {match.user_email && (
+                        <span className="text-muted-foreground">{match.user_email}</span>
+                      )}
+                    </div>
+                  ) : (
+                    <p className="text-sm text-muted-foreground">
+                      {t.admin.orderCodeLookupNotDelivered}
+                    </p>
+                  )}
+                </div>
+              ))}
+            </div>
+          )}
+        </DialogContent>
+      </Dialog>
+    </>
+  )
+}
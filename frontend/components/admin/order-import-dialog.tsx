@@ -0,0 +1,226 @@
+'use client'
+
+import { useEffect, useRef, useState } from 'react'
+import { useMutation, useQuery } from '@tanstack/react-query'
+import { FileDown, FileUp, Loader2 } from 'lucide-react'
+import toast from 'react-hot-toast'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Label } from '@/components/ui/label'
+import { Switch } from '@/components/ui/switch'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { resolveClientAPIProxyURL } from '@/lib/api-base-url'
+import { createOrderImportJob, getOrderImportJob, type OrderImportJob } from '@/lib/api'
+
+interface OrderImportDialogProps {
+  onImported?: () => void
+}
+
+// OrderImportDialog 批量导入订单（平台迁移）：上传后轮询后台任务并展示行级错误
+export function OrderImportDialog({ onImported }: OrderImportDialogProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const [open, setOpen] = useState(false)
+  const [notifyCustomers, setNotifyCustomers] = useState(false)
+  const [jobId, setJobId] = useState<number | null>(null)
+  const fileInputRef = useRef<HTMLInputElement>(null)
+  const onImportedRef = useRef(onImported)
+  onImportedRef.current = onImported
+
+  const { data } = useQuery({
+    queryKey: ['orderImportJob', jobId],
+    queryFn: () => getOrderImportJob(jobId as number),
+    enabled: open && jobId !== null,
+    refetchInterval: (query) => {
+      const status = (query.state.data as any)?.data?.status
+      return status === 'completed' || status === 'failed' ? false : 2000
+    },
+  })
+  const job: OrderImportJob | undefined = data?.data
+  const finished = job?.status === 'completed' || job?.status === 'failed'
+
+  useEffect(() => {
+    if (job?.status === 'completed') {
+      onImportedRef.current?.()
+    }
+  }, [job?.status])
+
+  const uploadMutation = useMutation({
+    mutationFn: (file: File) => createOrderImportJob(file, notifyCustomers),
+    onSuccess: (response: any) => {
+      setJobId(response.data.id)
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.importFailed))
+    },
+  })
+
+  const handleFileChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0]
+    if (fileInputRef.current) {
+      fileInputRef.current.value = ''
+    }
+    if (!file) return
+    if (!/\.(csv|xlsx)$/i.test(file.name)) {
+      toast.error(t.admin.orderBulkImportFileFormatError)
+      return
+    }
+    uploadMutation.mutate(file)
+  }
+
+  const handleDownloadTemplate = () => {
+    fetch(resolveClientAPIProxyURL('/api/admin/orders/bulk-import/template'))
+      .then((res) => {
+        if (!res.ok) throw new Error(t.admin.downloadFailed)
+        return res.blob()
+      })
+      .then((blob) => {
+        const url = window.URL.createObjectURL(blob)
+        const a = document.createElement('a')
+        a.href = url
+        a.download = 'order_bulk_import_template.xlsx'
+        document.body.appendChild(a)
+        a.click()
+        document.body.removeChild(a)
+        window.URL.revokeObjectURL(url)
+      })
+      .catch((err) => toast.error(err.message))
+  }
+
+  const statusLabel = (status: OrderImportJob['status']) =>
+    ({
+      queued: t.admin.orderBulkImportStatusQueued,
+      running: t.admin.orderBulkImportStatusRunning,
+      completed: t.admin.orderBulkImportStatusCompleted,
+      failed: t.admin.orderBulkImportStatusFailed,
+    })[status]
+
+  return (
+    <>
+      <Button variant="outline" size="sm" onClick={() => setOpen(true)}>
+        <FileUp className="mr-2 h-4 w-4" />
+        {t.admin.orderBulkImport}
+      </Button>
+      <Dialog
+        open={open}
+        onOpenChange={(next) => {
+          setOpen(next)
+          if (!next && finished) setJobId(null)
+        }}
+      >
+        <DialogContent className="max-w-3xl">
+          <DialogHeader>
+            <DialogTitle>{t.admin.orderBulkImport}</DialogTitle>
+            <DialogDescription>{t.admin.orderBulkImportDesc}</DialogDescription>
+          </DialogHeader>
+
+          {!job ? (
+            <div className="space-y-4">
+              <div className="flex items-center justify-between rounded-md border p-3">
+                <div className="space-y-1">
+                  <Label htmlFor="order-import-notify">{t.admin.orderBulkImportNotify}</Label>
+                  <p className="text-xs text-muted-foreground">
+                    {t.admin.orderBulkImportNotifyHint}
+                  </p>
+                </div>
+                <Switch
+                  id="order-import-notify"
+                  checked={notifyCustomers}
+                  onCheckedChange={setNotifyCustomers}
+                />
+              </div>
+              <div className="flex gap-2">
+                <Button variant="outline" onClick={handleDownloadTemplate}>
+                  <FileDown className="mr-2 h-4 w-4" />
+                  {t.admin.downloadTemplate}
+                </Button>
+                <Button
+                  onClick={() => fileInputRef.current?.click()}
+                  disabled={uploadMutation.isPending}
+                >
+                  {uploadMutation.isPending ? (
+                    <Loader2 className="mr-2 h-4 w-4 animate-spin" />
+                  ) : (
+                    <FileUp className="mr-2 h-4 w-4" />
+                  )}
+                  {t.admin.orderBulkImportUpload}
+                </Button>
+                <input
+                  ref={fileInputRef}
+                  type="file"
+                  accept=".csv,.xlsx"
+                  onChange={handleFileChange}
+                  style={{ display: 'none' }}
+                />
+              </div>
+            </div>
+          ) : (
+            <div className="space-y-4">
+              <div className="flex flex-wrap items-center gap-2 text-sm">
+                <Badge variant={job.status === 'failed' ? 'destructive' : 'secondary'}>
+                  {statusLabel(job.status)}
+                </Badge>
+                <span className="font-mono text-xs text-muted-foreground">{job.job_no}</span>
+                {!finished && <Loader2 className="h-4 w-4 animate-spin text-muted-foreground" />}
+              </div>
+              <p className="text-sm">
+                {t.admin.orderBulkImportProgress
+                  .replace('{processed}', String(job.processed_orders))
+                  .replace('{total}', String(job.total_orders))
+                  .replace('{created}', String(job.created_count))
+                  .replace('{skipped}', String(job.skipped_count))
+                  .replace('{errors}', String(job.error_count))}
+              </p>
+              {job.failed_reason && (
+                <p className="text-sm text-destructive">{job.failed_reason}</p>
+              )}
+              {!!job.row_errors?.length && (
+                <div className="max-h-80 overflow-y-auto rounded-md border">
+                  <table className="w-full text-sm">
+                    <thead className="bg-muted/50 text-left">
+                      <tr>
+                        <th className="p-2">{t.admin.orderBulkImportRow}</th>
+                        <th className="p-2">{t.admin.orderBulkImportOrderRef}</th>
+                        <th className="p-2">{t.admin.orderBulkImportError}</th>
+                      </tr>
+                    </thead>
+                    <tbody>
+                      {job.row_errors.map((rowError, index) => (
+                        <tr key={`${rowError.row}-${index}`} className="border-t">
+                          <td className="p-2">{rowError.row}</td>
+                          <td className="p-2">
+                            <div>{rowError.order_ref || '-'}</div>
+                            {rowError.order_no && (
+                              <div className="font-mono text-xs text-muted-foreground">
+                                {rowError.order_no}
+                              </div>
+                            )}
+                          </td>
+                          <td className="p-2 text-destructive">{rowError.message}</td>
+                        </tr>
+                      ))}
+                    </tbody>
+                  </table>
+                </div>
+              )}
+              {finished && (
+                <Button variant="outline" onClick={() => setJobId(null)}>
+                  {t.admin.orderBulkImportAnother}
+                </Button>
+              )}
+            </div>
+          )}
+        </DialogContent>
+      </Dialog>
+    </>
+  )
+}
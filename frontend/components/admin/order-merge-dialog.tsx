@@ -0,0 +1,123 @@
+'use client'
+
+import { useEffect, useState } from 'react'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
+import { Button } from '@/components/ui/button'
+import { Checkbox } from '@/components/ui/checkbox'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatCurrency, formatDate } from '@/lib/utils'
+import { getAdminOrderMergeCandidates, mergeAdminOrders } from '@/lib/api'
+import type { OrderMergeCandidate } from '@/types/order'
+
+interface OrderMergeDialogProps {
+  orderId: number
+  open: boolean
+  onOpenChange: (open: boolean) => void
+}
+
+// OrderMergeDialog 合并发货：选择同一买家、同一收货人的待发货订单并入当前订单
+export function OrderMergeDialog({ orderId, open, onOpenChange }: OrderMergeDialogProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [selected, setSelected] = useState<number[]>([])
+
+  useEffect(() => {
+    if (open) setSelected([])
+  }, [open])
+
+  const { data, isLoading } = useQuery({
+    queryKey: ['adminOrderMergeCandidates', orderId],
+    queryFn: () => getAdminOrderMergeCandidates(orderId),
+    enabled: open && !!orderId,
+    staleTime: 0,
+  })
+  const candidates: OrderMergeCandidate[] = data?.data?.items || []
+
+  const mutation = useMutation({
+    mutationFn: () => mergeAdminOrders(orderId, selected),
+    onSuccess: () => {
+      toast.success(t.order.mergeOrdersDone.replace('{n}', String(selected.length)))
+      queryClient.invalidateQueries({ queryKey: ['adminOrderDetail', orderId] })
+      queryClient.invalidateQueries({ queryKey: ['adminOrderMergeCandidates', orderId] })
+      onOpenChange(false)
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.order.operationFailed))
+    },
+  })
+
+  const toggle = (id: number, checked: boolean) => {
+    setSelected(checked ? [...selected, id] : selected.filter((item) => item !== id))
+  }
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-w-lg">
+        <DialogHeader>
+          <DialogTitle>{t.order.mergeOrdersTitle}</DialogTitle>
+          <DialogDescription>{t.order.mergeOrdersDesc}</DialogDescription>
+        </DialogHeader>
+        <div className="max-h-80 space-y-2 overflow-y-auto py-4">
+          {isLoading ? (
+            <p className="text-sm text-muted-foreground">{t.common.loading}</p>
+          ) : candidates.length === 0 ? (
+            <p className="text-sm text-muted-foreground">{t.order.mergeOrdersEmpty}</p>
+          ) : (
+            candidates.map((candidate) => (
+              <label
+                key={candidate.id}
+                className="flex cursor-pointer items-start gap-3 rounded-md border p-3 text-sm"
+              >
+                <Checkbox
+                  className="mt-0.5"
+                  checked={selected.includes(candidate.id)}
+                  onCheckedChange={(checked) => toggle(candidate.id, checked === true)}
+                />
+                <div className="min-w-0 flex-1 space-y-1">
+                  <div className="flex items-center justify-between gap-2">
+                    <span className="font-mono font-medium">{candidate.order_no}</span>
+                    <span>
+                      {formatCurrency(candidate.total_amount_minor, candidate.currency || 'CNY')}
+                    </span>
+                  </div>
+                  <p className="truncate text-xs text-muted-foreground">
+                    {(candidate.items || [])
+                      .map((item) => `${item.name || item.sku} × ${item.quantity}`)
+                      .join(', ')}
+                  </p>
+                  <p className="text-xs text-muted-foreground">
+                    {formatDate(candidate.created_at)}
+                  </p>
+                </div>
+              </label>
+            ))
+          )}
+        </div>
+        <DialogFooter>
+          <Button variant="outline" onClick={() => onOpenChange(false)}>
+            {t.order.back}
+          </Button>
+          <Button
+            onClick={() => mutation.mutate()}
+            disabled={mutation.isPending || selected.length === 0}
+          >
+            {mutation.isPending ? t.admin.processing : t.order.mergeOrdersConfirm}
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  )
+}
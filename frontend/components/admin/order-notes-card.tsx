@@ -0,0 +1,266 @@
+'use client'
+
+import { useState } from 'react'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
+import { MessageSquare, Pin, PinOff, Pencil, Trash2 } from 'lucide-react'
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
+import { Badge } from '@/components/ui/badge'
+import { Button } from '@/components/ui/button'
+import { Textarea } from '@/components/ui/textarea'
+import { Checkbox } from '@/components/ui/checkbox'
+import { useLocale } from '@/hooks/use-locale'
+import { useAuth } from '@/hooks/use-auth'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { cn, formatDate } from '@/lib/utils'
+import {
+  createAdminOrderNote,
+  deleteAdminOrderNote,
+  getAdminOrderNoteMentionableAdmins,
+  pinAdminOrderNote,
+  updateAdminOrderNote,
+} from '@/lib/api'
+import type { OrderNote } from '@/types/order'
+
+interface OrderNotesCardProps {
+  orderId: number
+  notes: OrderNote[]
+  canEdit?: boolean
+}
+
+export function OrderNotesCard({ orderId, notes, canEdit = false }: OrderNotesCardProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const { user } = useAuth()
+  const [content, setContent] = useState('')
+  const [mentionIds, setMentionIds] = useState<number[]>([])
+  const [editingId, setEditingId] = useState<number | null>(null)
+  const [editingContent, setEditingContent] = useState('')
+
+  const { data: mentionableData } = useQuery({
+    queryKey: ['adminOrderNoteMentionableAdmins'],
+    queryFn: () => getAdminOrderNoteMentionableAdmins(),
+    enabled: canEdit,
+    staleTime: 5 * 60 * 1000,
+  })
+  const mentionable: Array<{ id: number; name?: string; email?: string }> = (
+    Array.isArray(mentionableData?.data?.items) ? mentionableData.data.items : []
+  ).filter((admin: { id: number }) => admin.id !== user?.id)
+
+  const refresh = () => queryClient.invalidateQueries({ queryKey: ['adminOrderDetail', orderId] })
+  const onError = (error: unknown) => {
+    toast.error(resolveApiErrorMessage(error, t, t.order.orderNoteSaveFailed))
+  }
+
+  const createMutation = useMutation({
+    mutationFn: () => createAdminOrderNote(orderId, { content, mention_ids: mentionIds }),
+    onSuccess: () => {
+      setContent('')
+      setMentionIds([])
+      refresh()
+    },
+    onError,
+  })
+  const updateMutation = useMutation({
+    mutationFn: (noteId: number) => updateAdminOrderNote(orderId, noteId, editingContent),
+    onSuccess: () => {
+      setEditingId(null)
+      refresh()
+    },
+    onError,
+  })
+  const deleteMutation = useMutation({
+    mutationFn: (noteId: number) => deleteAdminOrderNote(orderId, noteId),
+    onSuccess: refresh,
+    onError,
+  })
+  const pinMutation = useMutation({
+    mutationFn: (note: OrderNote) => pinAdminOrderNote(orderId, note.id, !note.pinned),
+    onSuccess: refresh,
+    onError,
+  })
+
+  const sourceLabels: Record<string, string> = {
+    create: t.order.orderNoteSourceCreate,
+    mark_paid: t.order.orderNoteSourceMarkPaid,
+    complete: t.order.orderNoteSourceComplete,
+    resubmit: t.order.orderNoteSourceResubmit,
+    cancel: t.order.orderNoteSourceCancel,
+    auto_cancel: t.order.orderNoteSourceAutoCancel,
+    refund: t.order.orderNoteSourceRefund,
+    refund_confirm: t.order.orderNoteSourceRefundConfirm,
+    return: t.order.orderNoteSourceReturn,
+    virtual_revoke: t.order.orderNoteSourceVirtualRevoke,
+    automation: t.order.orderNoteSourceAutomation,
+    cod_collection: t.order.orderNoteSourceCODCollection,
+    auto_complete: t.order.orderNoteSourceAutoComplete,
+    merge: t.order.orderNoteSourceMerge,
+    legacy: t.order.orderNoteSourceLegacy,
+  }
+  const mentionName = (id: number) => {
+    const admin = mentionable.find((item) => item.id === id)
+    return admin ? admin.name || admin.email : `#${id}`
+  }
+
+  if (!canEdit && notes.length === 0) {
+    return null
+  }
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <MessageSquare className="h-5 w-5" />
+          {t.order.orderNotes}
+        </CardTitle>
+      </CardHeader>
+      <CardContent className="space-y-3">
+        {notes.length === 0 && (
+          <p className="text-sm text-muted-foreground">{t.order.orderNotesEmpty}</p>
+        )}
+        {notes.map((note) => {
+          const isOwn = note.source === 'manual' && !!user?.id && note.author_id === user.id
+          return (
+            <div
+              key={note.id}
+              className={cn(
+                'rounded-md p-3',
+                note.pinned ? 'border border-primary/40 bg-primary/5' : 'bg-muted/50'
+              )}
+            >
+              <div className="mb-1 flex flex-wrap items-center gap-2 text-xs text-muted-foreground">
+                <span className="font-medium text-foreground">
+                  {note.author ? note.author.name || note.author.email : t.order.orderNoteSystem}
+                </span>
+                <span>{formatDate(note.created_at)}</span>
+                {note.source !== 'manual' && (
+                  <Badge variant="outline">{sourceLabels[note.source] || note.source}</Badge>
+                )}
+                {note.pinned && <Badge variant="secondary">{t.order.orderNotePinned}</Badge>}
+                {canEdit && (
+                  <div className="ml-auto flex items-center gap-1">
+                    <Button
+                      variant="ghost"
+                      size="icon"
+                      className="h-7 w-7"
+                      title={note.pinned ? t.order.orderNoteUnpin : t.order.orderNotePin}
+                      onClick={() => pinMutation.mutate(note)}
+                      disabled={pinMutation.isPending}
+                    >
+                      {note.pinned ? (
+                        <PinOff className="h-3.5 w-3.5" />
+                      ) : (
+                        <Pin className="h-3.5 w-3.5" />
+                      )}
+                    </Button>
+                    {isOwn && (
+                      <>
+                        <Button
+                          variant="ghost"
+                          size="icon"
+                          className="h-7 w-7"
+                          title={t.common.edit}
+                          onClick={() => {
+                            setEditingId(note.id)
+                            setEditingContent(note.content)
+                          }}
+                        >
+                          <Pencil className="h-3.5 w-3.5" />
+                        </Button>
+                        <Button
+                          variant="ghost"
+                          size="icon"
+                          className="h-7 w-7"
+                          title={t.common.delete}
+                          onClick={() => {
+                            if (window.confirm(t.order.orderNoteDeleteConfirm)) {
+                              deleteMutation.mutate(note.id)
+                            }
+                          }}
+                          disabled={deleteMutation.isPending}
+                        >
+                          <Trash2 className="h-3.5 w-3.5" />
+                        </Button>
+                      </>
+                    )}
+                  </div>
+                )}
+              </div>
+              {editingId === note.id ? (
+                <div className="space-y-2">
+                  <Textarea
+                    value={editingContent}
+                    onChange={(e) => setEditingContent(e.target.value)}
+                    rows={3}
+                  />
+                  <div className="flex justify-end gap-2">
+                    <Button variant="outline" size="sm" onClick={() => setEditingId(null)}>
+                      {t.common.cancel}
+                    </Button>
+                    <Button
+                      size="sm"
+                      onClick={() => updateMutation.mutate(note.id)}
+                      disabled={updateMutation.isPending || !editingContent.trim()}
+                    >
+                      {t.common.save}
+                    </Button>
+                  </div>
+                </div>
+              ) : (
+                <p className="whitespace-pre-wrap text-sm">{note.content}</p>
+              )}
+              {note.mention_ids && note.mention_ids.length > 0 && (
+                <p className="mt-1 text-xs text-muted-foreground">
+                  {t.order.orderNoteMentioned}: {note.mention_ids.map(mentionName).join(', ')}
+                </p>
+              )}
+            </div>
+          )
+        })}
+
+        {canEdit && (
+          <div className="space-y-2 border-t pt-3">
+            <Textarea
+              placeholder={t.order.orderNotePlaceholder}
+              value={content}
+              onChange={(e) => setContent(e.target.value)}
+              rows={3}
+            />
+            {mentionable.length > 0 && (
+              <div className="space-y-1">
+                <p className="text-xs text-muted-foreground">{t.order.orderNoteMention}</p>
+                <div className="flex flex-wrap gap-3">
+                  {mentionable.map((admin) => (
+                    <label key={admin.id} className="flex items-center gap-1.5 text-sm">
+                      <Checkbox
+                        checked={mentionIds.includes(admin.id)}
+                        onCheckedChange={(checked) =>
+                          setMentionIds((prev) =>
+                            checked ? [...prev, admin.id] : prev.filter((id) => id !== admin.id)
+                          )
+                        }
+                      />
+                      {admin.name || admin.email}
+                    </label>
+                  ))}
+                </div>
+              </div>
+            )}
+            <div className="flex justify-end">
+              <Button
+                size="sm"
+                onClick={() => createMutation.mutate()}
+                disabled={createMutation.isPending || !content.trim()}
+              >
+                {t.order.orderNoteAdd}
+              </Button>
+            </div>
+          </div>
+        )}
+      </CardContent>
+    </Card>
+  )
+}
@@ -0,0 +1,84 @@
+'use client'
+
+import { useQuery } from '@tanstack/react-query'
+import { Package } from 'lucide-react'
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
+import { Badge } from '@/components/ui/badge'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+import { getAdminOrderPackagePlan } from '@/lib/api'
+
+interface OrderPackagePlanCardProps {
+  orderId: number
+}
+
+const formatKg = (grams: number) => `${(grams / 1000).toFixed(3)} kg`
+
+// OrderPackagePlanCard 订单重量与建议箱型
+export function OrderPackagePlanCard({ orderId }: OrderPackagePlanCardProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const { data } = useQuery({
+    queryKey: ['adminOrderPackagePlan', orderId],
+    queryFn: () => getAdminOrderPackagePlan(orderId),
+    enabled: !!orderId,
+  })
+  const plan = data?.data
+  if (!plan || !Array.isArray(plan.items) || plan.items.length === 0) return null
+
+  const parcels: any[] = plan.parcels || []
+  const missing: string[] = Array.from(
+    new Set([...(plan.missing_weight_skus || []), ...(plan.missing_dimension_skus || [])])
+  )
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <Package className="h-5 w-5" />
+          {t.admin.packagePlan}
+        </CardTitle>
+      </CardHeader>
+      <CardContent className="space-y-3 text-sm">
+        <div className="flex flex-wrap gap-x-6 gap-y-1">
+          <span>
+            {t.admin.packagePlanWeight}: {formatKg(plan.total_weight_grams || 0)}
+          </span>
+          <span>
+            {t.admin.packagePlanVolume}: {plan.total_volume_cm3 || 0} cm³
+          </span>
+        </div>
+        {parcels.length > 0 ? (
+          <div className="space-y-2">
+            {parcels.map((parcel, index) => (
+              <div
+                key={`${parcel.box_code}-${index}`}
+                className="flex flex-wrap items-center gap-2 rounded-md border p-2"
+              >
+                <Badge variant="secondary">{parcel.box_name || parcel.box_code}</Badge>
+                <span className="text-muted-foreground">{formatKg(parcel.weight_grams || 0)}</span>
+                <span>
+                  {(parcel.items || [])
+                    .map((item: any) => `${item.sku} × ${item.quantity}`)
+                    .join(', ')}
+                </span>
+              </div>
+            ))}
+          </div>
+        ) : (
+          <p className="text-muted-foreground">{t.admin.packagePlanNoBoxes}</p>
+        )}
+        {(plan.unpackable_skus || []).length > 0 && (
+          <p className="text-amber-600">
+            {t.admin.packagePlanUnpackable}: {plan.unpackable_skus.join(', ')}
+          </p>
+        )}
+        {missing.length > 0 && (
+          <p className="text-xs text-muted-foreground">
+            {t.admin.packagePlanMissingData}: {missing.join(', ')}
+          </p>
+        )}
+      </CardContent>
+    </Card>
+  )
+}
@@ -0,0 +1,322 @@
+'use client'
+
+import { useEffect, useState } from 'react'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
+import { Undo2 } from 'lucide-react'
+import { Badge } from '@/components/ui/badge'
+import { Button } from '@/components/ui/button'
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Textarea } from '@/components/ui/textarea'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatCurrency, formatDate, parseMajorToMinor } from '@/lib/utils'
+import {
+  confirmOrderRefund,
+  createOrderRefund,
+  getOrderRefunds,
+  type OrderRefund,
+  type OrderRefundItem,
+  type OrderRefundSummary,
+} from '@/lib/api'
+
+interface OrderRefundsCardProps {
+  orderId: number
+  order: any
+  canRefund: boolean
+  canManage: boolean
+}
+
+const statusVariant: Record<OrderRefund['status'], 'default' | 'secondary' | 'destructive'> = {
+  processing: 'secondary',
+  pending: 'secondary',
+  succeeded: 'default',
+  failed: 'destructive',
+}
+
+// OrderRefundsCard 订单退款记录，支持按商品行与金额部分退款、确认待到账的退款
+export function OrderRefundsCard({ orderId, order, canRefund, canManage }: OrderRefundsCardProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [open, setOpen] = useState(false)
+  const [quantities, setQuantities] = useState<Record<number, string>>({})
+  const [amounts, setAmounts] = useState<Record<number, string>>({})
+  const [totalAmount, setTotalAmount] = useState('')
+  const [reason, setReason] = useState('')
+  const [confirmTarget, setConfirmTarget] = useState<OrderRefund | null>(null)
+  const [transactionId, setTransactionId] = useState('')
+
+  const { data } = useQuery({
+    queryKey: ['adminOrderRefunds', orderId],
+    queryFn: () => getOrderRefunds(orderId),
+    enabled: !!orderId && canManage,
+  })
+  const summary: OrderRefundSummary | undefined = data?.data
+
+  useEffect(() => {
+    if (open) {
+      setQuantities({})
+      setAmounts({})
+      setTotalAmount('')
+      setReason('')
+    }
+  }, [open])
+
+  const invalidate = () => {
+    queryClient.invalidateQueries({ queryKey: ['adminOrderRefunds', orderId] })
+    queryClient.invalidateQueries({ queryKey: ['adminOrderDetail', orderId] })
+  }
+
+  const createMutation = useMutation({
+    mutationFn: () => {
+      const items: OrderRefundItem[] = []
+      for (const index of Object.keys({ ...quantities, ...amounts })) {
+        const quantity = parseInt(quantities[Number(index)] || '', 10) || 0
+        const amount = parseMajorToMinor(amounts[Number(index)] || '0') || 0
+        if (quantity > 0 || amount > 0) {
+          items.push({ item_index: Number(index), quantity, amount_minor: amount })
+        }
+      }
+      return createOrderRefund(orderId, {
+        amount_minor: totalAmount.trim() ? parseMajorToMinor(totalAmount) || 0 : undefined,
+        items,
+        reason: reason.trim() || undefined,
+      })
+    },
+    onSuccess: () => {
+      toast.success(t.order.refundCreated)
+      invalidate()
+      setOpen(false)
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.order.refundFailed))
+    },
+  })
+
+  const confirmMutation = useMutation({
+    mutationFn: (refund: OrderRefund) =>
+      confirmOrderRefund(orderId, refund.id, {
+        transaction_id: transactionId.trim() || undefined,
+      }),
+    onSuccess: () => {
+      toast.success(t.order.refundConfirmed)
+      invalidate()
+      setConfirmTarget(null)
+      setTransactionId('')
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.order.refundConfirmFailed))
+    },
+  })
+
+  if (!canManage || !summary) return null
+  const refunds = summary.refunds || []
+  if (refunds.length === 0 && !canRefund) return null
+
+  const currency = summary.currency || order?.currency
+  const items: any[] = order?.items || []
+  const remainingOf = (index: number) =>
+    Math.max(
+      (items[index]?.quantity || 0) - (summary.refunded_quantities?.[String(index)] || 0),
+      0
+    )
+  const statusLabels: Record<OrderRefund['status'], string> = {
+    processing: t.order.refundStatusProcessing,
+    pending: t.order.refundStatusPending,
+    succeeded: t.order.refundStatusSucceeded,
+    failed: t.order.refundStatusFailed,
+  }
+
+  return (
+    <Card>
+      <CardHeader className="flex flex-row items-center justify-between space-y-0">
+        <CardTitle className="flex items-center gap-2">
+          <Undo2 className="h-5 w-5" />
+          {t.order.refunds}
+        </CardTitle>
+        {canRefund && summary.refundable_minor > 0 && (
+          <Button variant="outline" size="sm" onClick={() => setOpen(true)}>
+            {t.order.partialRefund}
+          </Button>
+        )}
+      </CardHeader>
+      <CardContent className="space-y-3 text-sm">
+        <div className="flex flex-wrap gap-x-6 gap-y-1">
+          <span>
+            {t.order.refundedAmount}: {formatCurrency(summary.refunded_minor, currency)}
+          </span>
+          {summary.pending_minor > 0 && (
+            <span>
+              {t.order.refundPendingAmount}: {formatCurrency(summary.pending_minor, currency)}
+            </span>
+          )}
+          <span>
+            {t.order.refundableAmount}: {formatCurrency(summary.refundable_minor, currency)}
+          </span>
+        </div>
+        {refunds.length === 0 ? (
+          <p className="text-muted-foreground">{t.order.noRefunds}</p>
+        ) : (
+          <div className="space-y-2">
+            {refunds.map((refund) => (
+              <div key={refund.id} className="space-y-1 rounded-md border p-2">
+                <div className="flex flex-wrap items-center gap-2">
+                  <span className="font-medium">
+                    {formatCurrency(refund.amount_minor, refund.currency || currency)}
+                  </span>
+                  <Badge variant={statusVariant[refund.status]}>
+                    {statusLabels[refund.status]}
+                  </Badge>
+                  {refund.full && <Badge variant="outline">{t.order.refundFull}</Badge>}
+                  <span className="text-xs text-muted-foreground">
+                    {formatDate(refund.created_at)}
+                  </span>
+                  {refund.status === 'pending' && (
+                    <Button
+                      variant="outline"
+                      size="sm"
+                      className="ml-auto"
+                      onClick={() => setConfirmTarget(refund)}
+                    >
+                      {t.order.confirmRefundRecord}
+                    </Button>
+                  )}
+                </div>
+                {(refund.items || []).map((item) => (
+                  <div key={item.item_index} className="text-xs text-muted-foreground">
+                    {item.sku || items[item.item_index]?.sku} × {item.quantity} ·{' '}
+                    {formatCurrency(item.amount_minor, refund.currency || currency)}
+                  </div>
+                ))}
+                {(refund.transaction_id || refund.reason || refund.message) && (
+                  <div className="text-xs text-muted-foreground">
+                    {[refund.transaction_id, refund.reason, refund.message]
+                      .filter(Boolean)
+                      .join(' · ')}
+                  </div>
+                )}
+              </div>
+            ))}
+          </div>
+        )}
+      </CardContent>
+
+      <Dialog open={open} onOpenChange={setOpen}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{t.order.partialRefund}</DialogTitle>
+            <DialogDescription>{t.order.partialRefundDesc}</DialogDescription>
+          </DialogHeader>
+          <div className="space-y-4 py-4">
+            <div className="space-y-2">
+              {items.map((item, index) => (
+                <div key={index} className="flex items-center justify-between gap-3">
+                  <div className="min-w-0 text-sm">
+                    <p className="truncate font-medium">{item.name}</p>
+                    <p className="text-xs text-muted-foreground">
+                      {item.sku} ·{' '}
+                      {t.order.refundItemRemaining.replace('{n}', String(remainingOf(index)))}
+                    </p>
+                  </div>
+                  <div className="flex gap-2">
+                    <Input
+                      type="number"
+                      min="0"
+                      max={remainingOf(index)}
+                      className="w-20"
+                      placeholder={t.order.refundItemQuantity}
+                      value={quantities[index] ?? ''}
+                      disabled={remainingOf(index) === 0}
+                      onChange={(e) => setQuantities({ ...quantities, [index]: e.target.value })}
+                    />
+                    <Input
+                      type="number"
+                      min="0"
+                      step="0.01"
+                      className="w-28"
+                      placeholder={t.order.refundItemAmount}
+                      value={amounts[index] ?? ''}
+                      disabled={remainingOf(index) === 0}
+                      onChange={(e) => setAmounts({ ...amounts, [index]: e.target.value })}
+                    />
+                  </div>
+                </div>
+              ))}
+            </div>
+            <div className="space-y-2">
+              <Label>{t.order.refundTotalAmount}</Label>
+              <Input
+                type="number"
+                min="0"
+                step="0.01"
+                placeholder={formatCurrency(summary.refundable_minor, currency)}
+                value={totalAmount}
+                onChange={(e) => setTotalAmount(e.target.value)}
+              />
+            </div>
+            <div className="space-y-2">
+              <Label>{t.order.refundReasonLabel}</Label>
+              <Textarea value={reason} onChange={(e) => setReason(e.target.value)} rows={2} />
+            </div>
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setOpen(false)}>
+              {t.order.back}
+            </Button>
+            <Button
+              variant="destructive"
+              onClick={() => createMutation.mutate()}
+              disabled={createMutation.isPending}
+            >
+              {createMutation.isPending ? t.order.refunding : t.order.confirmRefund}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+
+      <Dialog open={!!confirmTarget} onOpenChange={(value) => !value && setConfirmTarget(null)}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{t.order.confirmRefundRecord}</DialogTitle>
+            <DialogDescription>
+              {confirmTarget &&
+                formatCurrency(confirmTarget.amount_minor, confirmTarget.currency || currency)}
+            </DialogDescription>
+          </DialogHeader>
+          <div className="space-y-2 py-4">
+            <Label>{t.order.refundTransactionIdLabel}</Label>
+            <Input
+              placeholder={t.order.refundTransactionIdPlaceholder}
+              value={transactionId}
+              onChange={(e) => setTransactionId(e.target.value)}
+            />
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setConfirmTarget(null)}>
+              {t.common.cancel}
+            </Button>
+            <Button
+              onClick={() => confirmTarget && confirmMutation.mutate(confirmTarget)}
+              disabled={confirmMutation.isPending}
+            >
+              {t.order.confirmRefundRecord}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </Card>
+  )
+}
@@ -0,0 +1,139 @@
+'use client'
+
+import { useEffect, useState } from 'react'
+import { useMutation, useQueryClient } from '@tanstack/react-query'
+import { Button } from '@/components/ui/button'
+import { Checkbox } from '@/components/ui/checkbox'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Textarea } from '@/components/ui/textarea'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { receiveOrderReturn } from '@/lib/api'
+
+interface OrderReturnDialogProps {
+  orderId: number
+  order: any
+  open: boolean
+  onOpenChange: (open: boolean) => void
+}
+
+// OrderReturnDialog 登记退货：按订单项填写数量，可回补库存并作废序列号
+export function OrderReturnDialog({ orderId, order, open, onOpenChange }: OrderReturnDialogProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [quantities, setQuantities] = useState<Record<number, string>>({})
+  const [restock, setRestock] = useState(true)
+  const [invalidateSerials, setInvalidateSerials] = useState(true)
+  const [reason, setReason] = useState('')
+
+  useEffect(() => {
+    if (open) {
+      setQuantities({})
+      setReason('')
+    }
+  }, [open])
+
+  const items: any[] = order?.items || []
+  const returned: Record<string, number> = order?.returned_quantities || {}
+  const remainingOf = (index: number) =>
+    Math.max((items[index]?.quantity || 0) - (returned[String(index)] || 0), 0)
+
+  const mutation = useMutation({
+    mutationFn: () =>
+      receiveOrderReturn(orderId, {
+        items: Object.entries(quantities)
+          .map(([index, value]) => ({
+            item_index: Number(index),
+            quantity: parseInt(value, 10) || 0,
+          }))
+          .filter((item) => item.quantity > 0),
+        restock,
+        invalidate_serials: invalidateSerials,
+        reason: reason.trim() || undefined,
+      }),
+    onSuccess: () => {
+      toast.success(t.order.returnRecorded)
+      queryClient.invalidateQueries({ queryKey: ['adminOrderDetail', orderId] })
+      onOpenChange(false)
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.order.operationFailed))
+    },
+  })
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent>
+        <DialogHeader>
+          <DialogTitle>{t.order.returnTitle}</DialogTitle>
+          <DialogDescription>{t.order.returnDesc}</DialogDescription>
+        </DialogHeader>
+        <div className="space-y-4 py-4">
+          <div className="space-y-2">
+            {items.map((item, index) =>
+              (item.product_type || item.productType) === 'virtual' ? null : (
+                <div key={index} className="flex items-center justify-between gap-3">
+                  <div className="min-w-0 text-sm">
+                    <p className="truncate font-medium">{item.name}</p>
+                    <p className="text-xs text-muted-foreground">
+                      {item.sku} ·{' '}
+                      {t.order.returnRemaining.replace('{n}', String(remainingOf(index)))}
+                    </p>
+                  </div>
+                  <Input
+                    type="number"
+                    min="0"
+                    max={remainingOf(index)}
+                    className="w-24"
+                    value={quantities[index] ?? ''}
+                    disabled={remainingOf(index) === 0}
+                    onChange={(e) => setQuantities({ ...quantities, [index]: e.target.value })}
+                  />
+                </div>
+              )
+            )}
+          </div>
+          <label className="flex items-center gap-2 text-sm">
+            <Checkbox
+              checked={restock}
+              onCheckedChange={(checked) => setRestock(checked === true)}
+            />
+            {t.order.returnRestock}
+          </label>
+          <label className="flex items-center gap-2 text-sm">
+            <Checkbox
+              checked={invalidateSerials}
+              onCheckedChange={(checked) => setInvalidateSerials(checked === true)}
+            />
+            {t.order.returnInvalidateSerials}
+          </label>
+          <div className="space-y-2">
+            <Label>{t.order.remarkOptional}</Label>
+            <Textarea value={reason} onChange={(e) => setReason(e.target.value)} rows={2} />
+          </div>
+        </div>
+        <DialogFooter>
+          <Button variant="outline" onClick={() => onOpenChange(false)}>
+            {t.order.back}
+          </Button>
+          <Button onClick={() => mutation.mutate()} disabled={mutation.isPending}>
+            {t.order.returnConfirm}
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  )
+}
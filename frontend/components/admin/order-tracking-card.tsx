@@ -0,0 +1,91 @@
+'use client'
+
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
+import { RefreshCw } from 'lucide-react'
+import { Button } from '@/components/ui/button'
+import { ShipmentTrackingCard } from '@/components/orders/shipment-tracking-card'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatDate } from '@/lib/utils'
+import { getAdminOrderTracking, syncAdminOrderTracking } from '@/lib/api'
+import type { ShipmentTracking } from '@/types/order'
+
+interface OrderTrackingCardProps {
+  orderId: number
+  trackingNo?: string
+  canSync?: boolean
+}
+
+// OrderTrackingCard 管理端订单物流轨迹，未启用物流轨迹服务时不展示
+export function OrderTrackingCard({
+  orderId,
+  trackingNo,
+  canSync = false,
+}: OrderTrackingCardProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const { data } = useQuery({
+    queryKey: ['adminOrderTracking', orderId],
+    queryFn: () => getAdminOrderTracking(orderId),
+    enabled: !!orderId,
+  })
+
+  const syncMutation = useMutation({
+    mutationFn: () => syncAdminOrderTracking(orderId),
+    onSuccess: () => {
+      toast.success(t.admin.shipmentTrackingSynced)
+      queryClient.invalidateQueries({ queryKey: ['adminOrderTracking', orderId] })
+      queryClient.invalidateQueries({ queryKey: ['adminOrderDetail', orderId] })
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.shipmentTrackingSyncFailed))
+    },
+  })
+
+  const loaded: ShipmentTracking | null = data?.data?.tracking || null
+  if (!data?.data?.enabled && !loaded) return null
+  if (!loaded && !trackingNo) return null
+  // 已发货但尚未注册时展示占位，便于手动同步
+  const tracking: ShipmentTracking = loaded || {
+    tracking_no: trackingNo || '',
+    status: 'pending',
+    events: [],
+  }
+
+  return (
+    <ShipmentTrackingCard
+      tracking={tracking}
+      action={
+        canSync && data?.data?.enabled && trackingNo ? (
+          <Button
+            size="sm"
+            variant="outline"
+            onClick={() => syncMutation.mutate()}
+            disabled={syncMutation.isPending}
+          >
+            <RefreshCw className={`mr-1 h-4 w-4 ${syncMutation.isPending ? 'animate-spin' : ''}`} />
+            {t.admin.shipmentTrackingSync}
+          </Button>
+        ) : undefined
+      }
+    >
+      <div className="flex flex-wrap gap-x-6 gap-y-1 text-xs text-muted-foreground">
+        {!tracking.registered && <span>{t.admin.shipmentTrackingNotRegistered}</span>}
+        {tracking.last_synced_at && (
+          <span>
+            {t.admin.shipmentTrackingLastSynced}: {formatDate(tracking.last_synced_at)}
+          </span>
+        )}
+        {tracking.last_error && (
+          <span className="text-destructive">
+            {t.admin.shipmentTrackingLastError}: {tracking.last_error}
+          </span>
+        )}
+      </div>
+    </ShipmentTrackingCard>
+  )
+}
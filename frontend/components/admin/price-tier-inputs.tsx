@@ -0,0 +1,73 @@
+'use client'
+
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Plus, Trash2 } from 'lucide-react'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+
+// 表单中的阶梯价，价格为主单位字符串，提交时再转为 price_minor
+export interface PriceTierValue {
+  min_quantity: number
+  price: string
+}
+
+interface PriceTierInputsProps {
+  value: PriceTierValue[]
+  onChange: (value: PriceTierValue[]) => void
+}
+
+// PriceTierInputs 阶梯批发价编辑：购买数量达到起订量时使用对应单价
+export function PriceTierInputs({ value, onChange }: PriceTierInputsProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+
+  const update = (index: number, patch: Partial<PriceTierValue>) =>
+    onChange(value.map((tier, i) => (i === index ? { ...tier, ...patch } : tier)))
+
+  return (
+    <div className="space-y-2">
+      <Label>{t.admin.priceTiersLabel}</Label>
+      {value.map((tier, index) => (
+        <div key={index} className="flex items-center gap-2">
+          <Input
+            type="number"
+            min="2"
+            value={tier.min_quantity || ''}
+            onChange={(e) => update(index, { min_quantity: parseInt(e.target.value, 10) || 0 })}
+            placeholder={t.admin.priceTierMinQuantity}
+            className="w-40"
+          />
+          <Input
+            type="number"
+            step="0.01"
+            min="0"
+            value={tier.price}
+            onChange={(e) => update(index, { price: e.target.value })}
+            placeholder={t.admin.priceTierUnitPrice}
+            className="w-40"
+          />
+          <Button
+            type="button"
+            variant="ghost"
+            size="icon"
+            onClick={() => onChange(value.filter((_, i) => i !== index))}
+          >
+            <Trash2 className="h-4 w-4" />
+          </Button>
+        </div>
+      ))}
+      <Button
+        type="button"
+        variant="outline"
+        size="sm"
+        onClick={() => onChange([...value, { min_quantity: 0, price: '' }])}
+      >
+        <Plus className="mr-1 h-4 w-4" />
+        {t.admin.addPriceTier}
+      </Button>
+      <p className="text-xs text-muted-foreground">{t.admin.priceTiersHint}</p>
+    </div>
+  )
+}
@@ -29,6 +29,7 @@ export interface VirtualVariantInventoryBinding {
     virtual_inventory_id: number | null
     is_random: boolean
     priority: number
+    delivery_order?: string
 }
 
 interface ProductVirtualVariantInventoryProps {
@@ -134,6 +135,8 @@ export function ProductVirtualVariantInventory({
             updated[index].priority = value
         } else if (field === 'is_random') {
             updated[index].is_random = value
+        } else if (field === 'delivery_order') {
+            updated[index].delivery_order = value === 'inherit' ? '' : value
         }
         onBindingsChange(updated)
     }, [bindings, onBindingsChange])
@@ -226,6 +229,7 @@ export function ProductVirtualVariantInventory({
                                         <TableHead>{t.admin.variantType}</TableHead>
                                         <TableHead>{t.admin.virtualInventoryConfig}</TableHead>
                                         <TableHead>{t.admin.inventoryInfo}</TableHead>
+                                        <TableHead>{t.admin.virtualDeliveryOrder}</TableHead>
                                         <TableHead>{t.admin.weight}</TableHead>
                                         <TableHead>{t.admin.probability}</TableHead>
                                     </TableRow>
@@ -306,6 +310,29 @@ export function ProductVirtualVariantInventory({
                                                         <span className="text-sm text-muted-foreground">{t.admin.notConfigured}</span>
                                                     )}
                                                 </TableCell>
+                                                <TableCell>
+                                                    {inventory && inventory.type !== 'script' ? (
+                                                        <Select
+                                                            value={binding.delivery_order || 'inherit'}
+                                                            onValueChange={(value) =>
+                                                                updateBinding(index, 'delivery_order', value)
+                                                            }
+                                                        >
+                                                            <SelectTrigger className="w-[160px]">
+                                                                <SelectValue />
+                                                            </SelectTrigger>
+                                                            <SelectContent>
+                                                                <SelectItem value="inherit">{t.admin.virtualDeliveryInheritInventory}</SelectItem>
+                                                                <SelectItem value="random">{t.admin.virtualDeliveryRandom}</SelectItem>
+                                                                <SelectItem value="newest">{t.admin.virtualDeliveryNewest}</SelectItem>
+                                                                <SelectItem value="oldest">{t.admin.virtualDeliveryOldest}</SelectItem>
+                                                                <SelectItem value="expiring">{t.admin.virtualDeliveryExpiring}</SelectItem>
+                                                            </SelectContent>
+                                                        </Select>
+                                                    ) : (
+                                                        <span className="text-sm text-muted-foreground">-</span>
+                                                    )}
+                                                </TableCell>
                                                 <TableCell>
                                                     {isBlindBox ? (
                                                         <Input
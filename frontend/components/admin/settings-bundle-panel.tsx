@@ -0,0 +1,270 @@
+'use client'
+
+import { useRef, useState } from 'react'
+import { useMutation, useQueryClient } from '@tanstack/react-query'
+import { Download, Loader2, Upload } from 'lucide-react'
+import { Badge } from '@/components/ui/badge'
+import { Button } from '@/components/ui/button'
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Switch } from '@/components/ui/switch'
+import { ChangeRow } from '@/components/admin/settings-revisions-panel'
+import { useToast } from '@/hooks/use-toast'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatDate } from '@/lib/utils'
+import {
+  exportSettingsBundle,
+  importSettingsBundle,
+  previewSettingsImport,
+  type SettingsBundle,
+  type SettingsImportPreview,
+} from '@/lib/api'
+
+function PathList({ title, paths }: { title: string; paths: string[] }) {
+  if (paths.length === 0) return null
+  return (
+    <div className="space-y-1">
+      <div className="text-xs font-medium text-muted-foreground">{title}</div>
+      <div className="flex flex-wrap gap-1">
+        {paths.map((path) => (
+          <Badge key={path} variant="outline" className="font-mono text-xs">
+            {path}
+          </Badge>
+        ))}
+      </div>
+    </div>
+  )
+}
+
+// SettingsBundlePanel 配置包导出与导入，导入前需预检差异
+export function SettingsBundlePanel() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const fileInputRef = useRef<HTMLInputElement>(null)
+  const [encryptSecrets, setEncryptSecrets] = useState(false)
+  const [exportPassphrase, setExportPassphrase] = useState('')
+  const [bundle, setBundle] = useState<SettingsBundle | null>(null)
+  const [importPassphrase, setImportPassphrase] = useState('')
+  const [preview, setPreview] = useState<SettingsImportPreview | null>(null)
+
+  const exportMutation = useMutation({
+    mutationFn: () =>
+      exportSettingsBundle({
+        secrets: encryptSecrets ? 'encrypted' : 'redacted',
+        passphrase: encryptSecrets ? exportPassphrase : undefined,
+      }),
+    onSuccess: (response: any) => {
+      const data = JSON.stringify(response?.data, null, 2)
+      const url = window.URL.createObjectURL(new Blob([data], { type: 'application/json' }))
+      const a = document.createElement('a')
+      a.href = url
+      a.download = `settings-bundle-${new Date().toISOString().slice(0, 10)}.json`
+      document.body.appendChild(a)
+      a.click()
+      document.body.removeChild(a)
+      window.URL.revokeObjectURL(url)
+      toast.success(t.admin.settingsBundleExported)
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.settingsBundleExportFailed))
+    },
+  })
+
+  const previewMutation = useMutation({
+    mutationFn: (target: SettingsBundle) =>
+      previewSettingsImport({ bundle: target, passphrase: importPassphrase || undefined }),
+    onSuccess: (response: any) => setPreview(response?.data || null),
+    onError: (error: unknown) => {
+      setPreview(null)
+      toast.error(resolveApiErrorMessage(error, t, t.admin.settingsBundlePreviewFailed))
+    },
+  })
+
+  const importMutation = useMutation({
+    mutationFn: () =>
+      importSettingsBundle({
+        bundle: bundle as SettingsBundle,
+        passphrase: importPassphrase || undefined,
+        expected_hash: preview?.config_hash || '',
+      }),
+    onSuccess: (response: any) => {
+      queryClient.invalidateQueries({ queryKey: ['settings'] })
+      queryClient.invalidateQueries({ queryKey: ['settingsRevisions'] })
+      if (response?.data?.reload_error) {
+        toast.error(`${t.admin.settingsBundleImported}: ${response.data.reload_error}`)
+      } else {
+        toast.success(t.admin.settingsBundleImported)
+      }
+      setBundle(null)
+      setPreview(null)
+      setImportPassphrase('')
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.settingsBundleImportFailed))
+    },
+  })
+
+  const handleFileChange = async (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0]
+    e.target.value = ''
+    if (!file) return
+    try {
+      setBundle(JSON.parse(await file.text()))
+      setPreview(null)
+    } catch {
+      toast.error(t.admin.settingsBundleInvalidFile)
+    }
+  }
+
+  const needsImportPassphrase = bundle?.secrets_mode === 'encrypted'
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle>{t.admin.settingsBundle}</CardTitle>
+        <CardDescription>{t.admin.settingsBundleDesc}</CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-6">
+        <div className="space-y-3">
+          <div className="flex items-center justify-between gap-4">
+            <div className="space-y-0.5">
+              <Label>{t.admin.settingsBundleEncryptSecrets}</Label>
+              <p className="text-xs text-muted-foreground">
+                {t.admin.settingsBundleEncryptSecretsDesc}
+              </p>
+            </div>
+            <Switch checked={encryptSecrets} onCheckedChange={setEncryptSecrets} />
+          </div>
+          {encryptSecrets && (
+            <Input
+              type="password"
+              autoComplete="new-password"
+              placeholder={t.admin.settingsBundlePassphrase}
+              value={exportPassphrase}
+              onChange={(e) => setExportPassphrase(e.target.value)}
+            />
+          )}
+          <Button
+            type="button"
+            variant="outline"
+            onClick={() => exportMutation.mutate()}
+            disabled={exportMutation.isPending || (encryptSecrets && !exportPassphrase)}
+          >
+            {exportMutation.isPending ? (
+              <Loader2 className="mr-2 h-4 w-4 animate-spin" />
+            ) : (
+              <Download className="mr-2 h-4 w-4" />
+            )}
+            {t.admin.settingsBundleExport}
+          </Button>
+        </div>
+
+        <div className="space-y-3 border-t pt-6">
+          <input
+            ref={fileInputRef}
+            type="file"
+            accept="application/json,.json"
+            className="hidden"
+            onChange={handleFileChange}
+          />
+          <div className="flex flex-wrap items-center gap-2">
+            <Button type="button" variant="outline" onClick={() => fileInputRef.current?.click()}>
+              <Upload className="mr-2 h-4 w-4" />
+              {t.admin.settingsBundleChooseFile}
+            </Button>
+            {bundle && (
+              <span className="text-xs text-muted-foreground">
+                {[bundle.source?.app_name, bundle.source?.env, formatDate(bundle.exported_at)]
+                  .filter(Boolean)
+                  .join(' · ')}
+              </span>
+            )}
+          </div>
+          {needsImportPassphrase && (
+            <Input
+              type="password"
+              autoComplete="off"
+              placeholder={t.admin.settingsBundlePassphrase}
+              value={importPassphrase}
+              onChange={(e) => setImportPassphrase(e.target.value)}
+            />
+          )}
+          {bundle && (
+            <Button
+              type="button"
+              variant="outline"
+              onClick={() => previewMutation.mutate(bundle)}
+              disabled={previewMutation.isPending || (needsImportPassphrase && !importPassphrase)}
+            >
+              {previewMutation.isPending && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+              {t.admin.settingsBundlePreview}
+            </Button>
+          )}
+
+          {preview && (
+            <div className="space-y-3 rounded-md border p-3">
+              {preview.valid ? (
+                <div className="text-sm">
+                  {preview.changes.length === 0
+                    ? t.admin.settingsBundleNoChanges
+                    : t.admin.settingsRevisionChangeCount.replace(
+                        '{count}',
+                        String(preview.changes.length)
+                      )}
+                </div>
+              ) : (
+                <div className="text-sm text-red-600 dark:text-red-400">
+                  {t.admin.settingsBundleInvalidConfig}: {preview.validation_error}
+                </div>
+              )}
+              <PathList
+                title={t.admin.settingsBundleUnresolvedSecrets}
+                paths={preview.unresolved_secrets}
+              />
+              <PathList
+                title={t.admin.settingsBundleSecretsApplied}
+                paths={preview.secrets_applied}
+              />
+              <PathList title={t.admin.settingsBundleSkippedPaths} paths={preview.skipped_paths} />
+              {preview.changes.length > 0 && (
+                <div className="max-h-80 overflow-auto">
+                  <table className="w-full text-left">
+                    <thead>
+                      <tr className="text-xs text-muted-foreground">
+                        <th className="py-1.5 pr-3 font-medium">{t.admin.settingsRevisionPath}</th>
+                        <th className="py-1.5 pr-3 font-medium">
+                          {t.admin.settingsRevisionBefore}
+                        </th>
+                        <th className="py-1.5 font-medium">{t.admin.settingsRevisionAfter}</th>
+                      </tr>
+                    </thead>
+                    <tbody>
+                      {preview.changes.map((change) => (
+                        <ChangeRow key={change.path} change={change} />
+                      ))}
+                    </tbody>
+                  </table>
+                </div>
+              )}
+              <Button
+                type="button"
+                onClick={() => importMutation.mutate()}
+                disabled={
+                  !preview.valid || preview.changes.length === 0 || importMutation.isPending
+                }
+              >
+                {importMutation.isPending && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+                {t.admin.settingsBundleApply}
+              </Button>
+            </div>
+          )}
+        </div>
+      </CardContent>
+    </Card>
+  )
+}
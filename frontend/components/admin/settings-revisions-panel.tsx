@@ -0,0 +1,247 @@
+'use client'
+
+import { useState } from 'react'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
+import { ChevronDown, ChevronRight, Loader2, RotateCcw } from 'lucide-react'
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle,
+} from '@/components/ui/alert-dialog'
+import { Badge } from '@/components/ui/badge'
+import { Button } from '@/components/ui/button'
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
+import { useToast } from '@/hooks/use-toast'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatDate } from '@/lib/utils'
+import {
+  getSettingsRevisions,
+  rollbackSettingsRevision,
+  type SettingsConfigChange,
+  type SettingsRevision,
+} from '@/lib/api'
+
+function formatChangeValue(value: unknown): string {
+  if (value === undefined || value === null) return '—'
+  if (typeof value === 'string') return value === '' ? '""' : value
+  return JSON.stringify(value)
+}
+
+export function ChangeRow({ change }: { change: SettingsConfigChange }) {
+  return (
+    <tr className="border-t align-top">
+      <td className="break-all py-1.5 pr-3 font-mono text-xs">{change.path}</td>
+      <td className="break-all py-1.5 pr-3 font-mono text-xs text-red-600 dark:text-red-400">
+        {formatChangeValue(change.before)}
+      </td>
+      <td className="break-all py-1.5 font-mono text-xs text-green-600 dark:text-green-400">
+        {formatChangeValue(change.after)}
+      </td>
+    </tr>
+  )
+}
+
+// SettingsRevisionsPanel 系统配置变更历史（脱敏差异）与一键回滚
+export function SettingsRevisionsPanel({ enabled }: { enabled: boolean }) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [page, setPage] = useState(1)
+  const [expanded, setExpanded] = useState<number | null>(null)
+  const [rollbackTarget, setRollbackTarget] = useState<SettingsRevision | null>(null)
+
+  const { data, isLoading } = useQuery({
+    queryKey: ['settingsRevisions', page],
+    queryFn: () => getSettingsRevisions({ page, limit: 20 }),
+    enabled,
+  })
+  const revisions: SettingsRevision[] = data?.data?.items || []
+  const pagination = data?.data?.pagination
+
+  const rollbackMutation = useMutation({
+    mutationFn: (id: number) => rollbackSettingsRevision(id),
+    onSuccess: (response: any) => {
+      queryClient.invalidateQueries({ queryKey: ['settings'] })
+      queryClient.invalidateQueries({ queryKey: ['settingsRevisions'] })
+      if (response?.data?.reload_error) {
+        toast.error(`${t.admin.settingsRevisionRolledBack}: ${response.data.reload_error}`)
+      } else {
+        toast.success(t.admin.settingsRevisionRolledBack)
+      }
+      setRollbackTarget(null)
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.settingsRevisionRollbackFailed))
+    },
+  })
+
+  const actionLabel = (action: SettingsRevision['action']) => {
+    switch (action) {
+      case 'baseline':
+        return t.admin.settingsRevisionActionBaseline
+      case 'rollback':
+        return t.admin.settingsRevisionActionRollback
+      case 'import':
+        return t.admin.settingsRevisionActionImport
+      default:
+        return t.admin.settingsRevisionActionUpdate
+    }
+  }
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle>{t.admin.settingsRevisions}</CardTitle>
+        <CardDescription>{t.admin.settingsRevisionsDesc}</CardDescription>
+      </CardHeader>
+      <CardContent className="space-y-3">
+        {isLoading && (
+          <div className="py-6 text-center text-sm text-muted-foreground">{t.common.loading}</div>
+        )}
+        {!isLoading && revisions.length === 0 && (
+          <div className="py-6 text-center text-sm text-muted-foreground">
+            {t.admin.settingsRevisionsEmpty}
+          </div>
+        )}
+        {revisions.map((revision) => {
+          const isExpanded = expanded === revision.id
+          return (
+            <div key={revision.id} className="rounded-md border">
+              <div className="flex flex-wrap items-center gap-2 p-3">
+                <button
+                  type="button"
+                  className="flex min-w-0 flex-1 items-center gap-2 text-left"
+                  onClick={() => setExpanded(isExpanded ? null : revision.id)}
+                  disabled={revision.changes.length === 0}
+                >
+                  {isExpanded ? (
+                    <ChevronDown className="h-4 w-4 shrink-0" />
+                  ) : (
+                    <ChevronRight className="h-4 w-4 shrink-0" />
+                  )}
+                  <span className="font-mono text-sm">#{revision.id}</span>
+                  <Badge variant={revision.action === 'rollback' ? 'secondary' : 'outline'}>
+                    {actionLabel(revision.action)}
+                  </Badge>
+                  {revision.rollback_of_id && (
+                    <span className="text-xs text-muted-foreground">
+                      {t.admin.settingsRevisionRollbackOf.replace(
+                        '{id}',
+                        String(revision.rollback_of_id)
+                      )}
+                    </span>
+                  )}
+                  <span className="truncate text-xs text-muted-foreground">
+                    {revision.sections.join(', ')}
+                  </span>
+                </button>
+                <span className="text-xs text-muted-foreground">
+                  {revision.admin_id ? `#${revision.admin_id} · ` : ''}
+                  {formatDate(revision.created_at)}
+                  {revision.change_count > 0 &&
+                    ` · ${t.admin.settingsRevisionChangeCount.replace(
+                      '{count}',
+                      String(revision.change_count)
+                    )}`}
+                </span>
+                <Button
+                  type="button"
+                  variant="outline"
+                  size="sm"
+                  onClick={() => setRollbackTarget(revision)}
+                  disabled={rollbackMutation.isPending}
+                >
+                  <RotateCcw className="mr-1.5 h-3.5 w-3.5" />
+                  {t.admin.settingsRevisionRollback}
+                </Button>
+              </div>
+              {isExpanded && revision.changes.length > 0 && (
+                <div className="overflow-x-auto border-t px-3 pb-3">
+                  <table className="w-full text-left">
+                    <thead>
+                      <tr className="text-xs text-muted-foreground">
+                        <th className="py-1.5 pr-3 font-medium">{t.admin.settingsRevisionPath}</th>
+                        <th className="py-1.5 pr-3 font-medium">
+                          {t.admin.settingsRevisionBefore}
+                        </th>
+                        <th className="py-1.5 font-medium">{t.admin.settingsRevisionAfter}</th>
+                      </tr>
+                    </thead>
+                    <tbody>
+                      {revision.changes.map((change) => (
+                        <ChangeRow key={change.path} change={change} />
+                      ))}
+                    </tbody>
+                  </table>
+                </div>
+              )}
+            </div>
+          )
+        })}
+        {pagination && pagination.total_pages > 1 && (
+          <div className="flex items-center justify-end gap-2">
+            <Button
+              variant="outline"
+              size="sm"
+              disabled={!pagination.has_prev}
+              onClick={() => setPage((p) => p - 1)}
+            >
+              {t.common.prevPage}
+            </Button>
+            <span className="text-sm text-muted-foreground">
+              {pagination.page} / {pagination.total_pages}
+            </span>
+            <Button
+              variant="outline"
+              size="sm"
+              disabled={!pagination.has_next}
+              onClick={() => setPage((p) => p + 1)}
+            >
+              {t.common.nextPage}
+            </Button>
+          </div>
+        )}
+      </CardContent>
+
+      <AlertDialog
+        open={!!rollbackTarget}
+        onOpenChange={(open) => !open && setRollbackTarget(null)}
+      >
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>
+              {t.admin.settingsRevisionRollbackTitle.replace(
+                '{id}',
+                String(rollbackTarget?.id ?? '')
+              )}
+            </AlertDialogTitle>
+            <AlertDialogDescription>
+              {t.admin.settingsRevisionRollbackConfirm}
+            </AlertDialogDescription>
+          </AlertDialogHeader>
+          <AlertDialogFooter>
+            <AlertDialogCancel>{t.common.cancel}</AlertDialogCancel>
+            <AlertDialogAction
+              onClick={(e) => {
+                e.preventDefault()
+                if (rollbackTarget) rollbackMutation.mutate(rollbackTarget.id)
+              }}
+              disabled={rollbackMutation.isPending}
+            >
+              {rollbackMutation.isPending && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+              {t.admin.settingsRevisionRollback}
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+    </Card>
+  )
+}
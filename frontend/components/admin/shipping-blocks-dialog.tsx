@@ -0,0 +1,123 @@
+'use client'
+
+import { useState } from 'react'
+import { useMutation, useQuery } from '@tanstack/react-query'
+import { Ban } from 'lucide-react'
+import toast from 'react-hot-toast'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Input } from '@/components/ui/input'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { clearShippingRestrictionBlocks, getShippingRestrictionBlocks } from '@/lib/api'
+
+// ShippingBlocksDialog 商品配送限制拦截报表
+export function ShippingBlocksDialog() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const [open, setOpen] = useState(false)
+  const [country, setCountry] = useState('')
+
+  const { data, refetch, isLoading } = useQuery({
+    queryKey: ['shippingRestrictionBlocks', country],
+    queryFn: () =>
+      getShippingRestrictionBlocks({ limit: 50, country: country.trim() || undefined }),
+    enabled: open,
+  })
+  const blocks: any[] = data?.data?.items || []
+
+  const clearMutation = useMutation({
+    mutationFn: clearShippingRestrictionBlocks,
+    onSuccess: () => {
+      toast.success(t.admin.shippingBlocksCleared)
+      refetch()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.common.failed))
+    },
+  })
+
+  return (
+    <>
+      <Button variant="outline" onClick={() => setOpen(true)}>
+        <Ban className="mr-2 h-4 w-4" />
+        {t.admin.shippingBlocks}
+      </Button>
+      <Dialog open={open} onOpenChange={setOpen}>
+        <DialogContent className="max-w-3xl">
+          <DialogHeader>
+            <DialogTitle>{t.admin.shippingBlocks}</DialogTitle>
+            <DialogDescription>{t.admin.shippingBlocksDesc}</DialogDescription>
+          </DialogHeader>
+          <div className="flex items-center gap-2">
+            <Input
+              value={country}
+              onChange={(e) => setCountry(e.target.value.toUpperCase())}
+              placeholder={t.admin.shippingBlocksCountryFilter}
+              className="w-48"
+            />
+            <Button
+              variant="outline"
+              className="ml-auto"
+              onClick={() => clearMutation.mutate()}
+              disabled={clearMutation.isPending || blocks.length === 0}
+            >
+              {t.admin.shippingBlocksClear}
+            </Button>
+          </div>
+          <div className="max-h-96 overflow-y-auto rounded-md border">
+            <table className="w-full text-sm">
+              <thead className="bg-muted/50 text-left">
+                <tr>
+                  <th className="p-2">{t.admin.sku}</th>
+                  <th className="p-2">{t.admin.country}</th>
+                  <th className="p-2">{t.admin.shippingBlocksSource}</th>
+                  <th className="p-2 text-right">{t.admin.shippingBlocksCount}</th>
+                  <th className="p-2">{t.admin.shippingBlocksLastSeen}</th>
+                </tr>
+              </thead>
+              <tbody>
+                {blocks.map((block) => (
+                  <tr key={block.id} className="border-t">
+                    <td className="p-2">
+                      <div className="font-medium">{block.sku}</div>
+                      <div className="text-xs text-muted-foreground">{block.product_name}</div>
+                    </td>
+                    <td className="p-2">{block.country}</td>
+                    <td className="p-2">
+                      <Badge variant="secondary">
+                        {block.source === 'admin'
+                          ? t.admin.shippingBlocksSourceAdmin
+                          : t.admin.shippingBlocksSourceForm}
+                      </Badge>
+                    </td>
+                    <td className="p-2 text-right">{block.count}</td>
+                    <td className="p-2 text-xs text-muted-foreground">
+                      {new Date(block.last_seen_at).toLocaleString(locale)}
+                      {block.last_order_no ? ` · ${block.last_order_no}` : ''}
+                    </td>
+                  </tr>
+                ))}
+                {!isLoading && blocks.length === 0 && (
+                  <tr>
+                    <td colSpan={5} className="p-6 text-center text-muted-foreground">
+                      {t.admin.shippingBlocksEmpty}
+                    </td>
+                  </tr>
+                )}
+              </tbody>
+            </table>
+          </div>
+        </DialogContent>
+      </Dialog>
+    </>
+  )
+}
@@ -0,0 +1,99 @@
+'use client'
+
+import Link from 'next/link'
+import { useQuery } from '@tanstack/react-query'
+import { CalendarClock } from 'lucide-react'
+import { Badge } from '@/components/ui/badge'
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow,
+} from '@/components/ui/table'
+import { useCurrency, formatPrice } from '@/contexts/currency-context'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+import { getVirtualInventoryExpiryReport } from '@/lib/api'
+
+// VirtualStockExpiryCard 临期库存报表：按虚拟库存汇总即将过期的库存数量与估算价值，无数据时不显示
+export function VirtualStockExpiryCard() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const { currency } = useCurrency()
+
+  const { data } = useQuery({
+    queryKey: ['virtualInventoryExpiryReport'],
+    queryFn: () => getVirtualInventoryExpiryReport(),
+  })
+  const items: any[] = data?.data?.items || []
+  if (items.length === 0) return null
+
+  const totalValue = items.reduce((sum, item) => sum + (item.expiring_value_minor || 0), 0)
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <CalendarClock className="h-5 w-5" />
+          {t.admin.expiryReportTitle}
+        </CardTitle>
+        <CardDescription>
+          {t.admin.expiryReportDesc
+            .replace('{days}', String(items[0].warning_days))
+            .replace('{value}', formatPrice(totalValue, currency))}
+        </CardDescription>
+      </CardHeader>
+      <CardContent>
+        <Table>
+          <TableHeader>
+            <TableRow>
+              <TableHead>{t.admin.inventoryName}</TableHead>
+              <TableHead className="text-right">{t.admin.expiryReportExpiring}</TableHead>
+              <TableHead className="text-right">{t.admin.expiryReportExpired}</TableHead>
+              <TableHead>{t.admin.expiryReportEarliest}</TableHead>
+              <TableHead className="text-right">{t.admin.expiryReportValue}</TableHead>
+            </TableRow>
+          </TableHeader>
+          <TableBody>
+            {items.map((item) => (
+              <TableRow key={item.virtual_inventory_id}>
+                <TableCell>
+                  <Link
+                    href={`/admin/inventories/${item.virtual_inventory_id}/virtual`}
+                    className="font-medium hover:underline"
+                  >
+                    {item.name || `#${item.virtual_inventory_id}`}
+                  </Link>
+                  {item.sku && (
+                    <div className="font-mono text-xs text-muted-foreground">{item.sku}</div>
+                  )}
+                </TableCell>
+                <TableCell className="text-right">{item.expiring_count}</TableCell>
+                <TableCell className="text-right">
+                  {item.expired_count > 0 ? (
+                    <Badge variant="destructive">{item.expired_count}</Badge>
+                  ) : (
+                    0
+                  )}
+                </TableCell>
+                <TableCell className="text-sm text-muted-foreground">
+                  {item.earliest_expires_at
+                    ? new Date(item.earliest_expires_at).toLocaleString()
+                    : '-'}
+                </TableCell>
+                <TableCell className="text-right">
+                  {item.bound_product_count > 0
+                    ? formatPrice(item.expiring_value_minor, currency)
+                    : '-'}
+                </TableCell>
+              </TableRow>
+            ))}
+          </TableBody>
+        </Table>
+      </CardContent>
+    </Card>
+  )
+}
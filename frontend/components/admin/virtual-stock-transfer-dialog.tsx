@@ -0,0 +1,197 @@
+'use client'
+
+import { useState } from 'react'
+import { useMutation, useQuery } from '@tanstack/react-query'
+import { ArrowRight } from 'lucide-react'
+import { Badge } from '@/components/ui/badge'
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import {
+  getVirtualInventories,
+  getVirtualInventoryTransfers,
+  transferVirtualInventoryStock,
+} from '@/lib/api'
+
+interface VirtualStockTransferDialogProps {
+  inventoryId: number
+  open: boolean
+  onOpenChange: (open: boolean) => void
+  onTransferred: () => void
+}
+
+// VirtualStockTransferDialog 按批次/备注筛选，将可用库存项批量转移到另一个静态虚拟库存
+export function VirtualStockTransferDialog({
+  inventoryId,
+  open,
+  onOpenChange,
+  onTransferred,
+}: VirtualStockTransferDialogProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const [targetId, setTargetId] = useState('')
+  const [batchNo, setBatchNo] = useState('')
+  const [remarkKeyword, setRemarkKeyword] = useState('')
+  const [quantity, setQuantity] = useState('')
+  const [reason, setReason] = useState('')
+
+  const { data: inventoriesData } = useQuery({
+    queryKey: ['virtualInventories', 'transferTargets'],
+    queryFn: () => getVirtualInventories({ page: 1, limit: 100 }),
+    enabled: open,
+  })
+  const targets = (inventoriesData?.data?.items || []).filter(
+    (item: any) => item.id !== inventoryId && item.type !== 'script'
+  )
+  const inventoryNames = new Map<number, string>(
+    (inventoriesData?.data?.items || []).map((item: any) => [item.id, item.name])
+  )
+
+  const { data: transfersData, refetch: refetchTransfers } = useQuery({
+    queryKey: ['virtualInventoryTransfers', inventoryId],
+    queryFn: () => getVirtualInventoryTransfers(inventoryId, { page: 1, limit: 10 }),
+    enabled: open,
+  })
+  const transfers: any[] = transfersData?.data?.items || []
+
+  const transferMutation = useMutation({
+    mutationFn: () =>
+      transferVirtualInventoryStock(inventoryId, {
+        target_inventory_id: Number(targetId),
+        batch_no: batchNo.trim() || undefined,
+        remark_keyword: remarkKeyword.trim() || undefined,
+        quantity: Number(quantity) || undefined,
+        reason: reason.trim() || undefined,
+      }),
+    onSuccess: (response: any) => {
+      toast.success(
+        t.admin.transferSuccess.replace('{count}', String(response?.data?.quantity || 0))
+      )
+      setBatchNo('')
+      setRemarkKeyword('')
+      setQuantity('')
+      setReason('')
+      refetchTransfers()
+      onTransferred()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.admin.transferFailed))
+    },
+  })
+
+  const inventoryLabel = (id: number) => inventoryNames.get(id) || `#${id}`
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-w-lg">
+        <DialogHeader>
+          <DialogTitle>{t.admin.transferStockTitle}</DialogTitle>
+          <DialogDescription>{t.admin.transferStockDesc}</DialogDescription>
+        </DialogHeader>
+
+        <div className="space-y-4">
+          <div className="space-y-2">
+            <Label>{t.admin.transferTargetLabel}</Label>
+            <Select value={targetId} onValueChange={setTargetId}>
+              <SelectTrigger>
+                <SelectValue placeholder={t.admin.transferTargetPlaceholder} />
+              </SelectTrigger>
+              <SelectContent>
+                {targets.map((item: any) => (
+                  <SelectItem key={item.id} value={String(item.id)}>
+                    {item.name}
+                    {item.sku ? ` (${item.sku})` : ''}
+                  </SelectItem>
+                ))}
+              </SelectContent>
+            </Select>
+          </div>
+          <div className="grid grid-cols-2 gap-4">
+            <div className="space-y-2">
+              <Label htmlFor="transfer_batch_no">{t.admin.batchNoColumn}</Label>
+              <Input
+                id="transfer_batch_no"
+                value={batchNo}
+                onChange={(e) => setBatchNo(e.target.value)}
+              />
+            </div>
+            <div className="space-y-2">
+              <Label htmlFor="transfer_remark">{t.admin.transferRemarkKeywordLabel}</Label>
+              <Input
+                id="transfer_remark"
+                value={remarkKeyword}
+                onChange={(e) => setRemarkKeyword(e.target.value)}
+              />
+            </div>
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="transfer_quantity">{t.admin.transferQuantityLabel}</Label>
+            <Input
+              id="transfer_quantity"
+              type="number"
+              min="0"
+              value={quantity}
+              onChange={(e) => setQuantity(e.target.value)}
+            />
+            <p className="text-xs text-muted-foreground">{t.admin.transferQuantityHint}</p>
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="transfer_reason">{t.admin.transferReasonLabel}</Label>
+            <Input id="transfer_reason" value={reason} onChange={(e) => setReason(e.target.value)} />
+          </div>
+
+          {transfers.length > 0 && (
+            <div className="space-y-2">
+              <Label>{t.admin.transferHistoryTitle}</Label>
+              <div className="max-h-40 space-y-1 overflow-y-auto text-sm">
+                {transfers.map((record) => (
+                  <div key={record.id} className="flex items-center gap-2">
+                    <span className="truncate">{inventoryLabel(record.source_inventory_id)}</span>
+                    <ArrowRight className="h-3 w-3 shrink-0" />
+                    <span className="truncate">{inventoryLabel(record.target_inventory_id)}</span>
+                    <Badge variant="secondary">{record.quantity}</Badge>
+                    <span className="ml-auto shrink-0 text-xs text-muted-foreground">
+                      {new Date(record.created_at).toLocaleString()}
+                    </span>
+                  </div>
+                ))}
+              </div>
+            </div>
+          )}
+        </div>
+
+        <DialogFooter>
+          <Button variant="outline" onClick={() => onOpenChange(false)}>
+            {t.common.cancel}
+          </Button>
+          <Button
+            onClick={() => transferMutation.mutate()}
+            disabled={!targetId || transferMutation.isPending}
+          >
+            {t.admin.transferStockBtn}
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  )
+}
@@ -0,0 +1,459 @@
+'use client'
+
+import { useState } from 'react'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
+import { Copy, KeyRound, Pencil, Plus, RotateCw, Send, Trash2, Webhook } from 'lucide-react'
+import { Badge } from '@/components/ui/badge'
+import { Button } from '@/components/ui/button'
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
+import { Checkbox } from '@/components/ui/checkbox'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Switch } from '@/components/ui/switch'
+import { Textarea } from '@/components/ui/textarea'
+import {
+  AlertDialog,
+  AlertDialogAction,
+  AlertDialogCancel,
+  AlertDialogContent,
+  AlertDialogDescription,
+  AlertDialogFooter,
+  AlertDialogHeader,
+  AlertDialogTitle,
+} from '@/components/ui/alert-dialog'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatDate } from '@/lib/utils'
+import {
+  createWebhook,
+  deleteWebhook,
+  getWebhookDeliveries,
+  getWebhookMeta,
+  getWebhooks,
+  redeliverWebhook,
+  rotateWebhookSecret,
+  testWebhook,
+  updateWebhook,
+  type WebhookDelivery,
+  type WebhookEndpoint,
+  type WebhookEndpointInput,
+} from '@/lib/api'
+
+const emptyInput: WebhookEndpointInput = {
+  name: '',
+  url: '',
+  events: [],
+  enabled: true,
+  description: '',
+  platform: '',
+}
+
+const statusVariant: Record<WebhookDelivery['status'], 'default' | 'secondary' | 'destructive'> = {
+  pending: 'secondary',
+  succeeded: 'default',
+  failed: 'destructive',
+}
+
+// WebhooksCard 出站 Webhook 地址管理与投递日志
+export function WebhooksCard() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [editing, setEditing] = useState<WebhookEndpoint | null>(null)
+  const [formOpen, setFormOpen] = useState(false)
+  const [input, setInput] = useState<WebhookEndpointInput>(emptyInput)
+  const [secret, setSecret] = useState('')
+  const [deleteTarget, setDeleteTarget] = useState<WebhookEndpoint | null>(null)
+  const [rotateTarget, setRotateTarget] = useState<WebhookEndpoint | null>(null)
+
+  const { data: metaData } = useQuery({ queryKey: ['webhookMeta'], queryFn: getWebhookMeta })
+  const { data } = useQuery({ queryKey: ['webhooks'], queryFn: getWebhooks })
+  const { data: deliveriesData } = useQuery({
+    queryKey: ['webhookDeliveries'],
+    queryFn: () => getWebhookDeliveries({ page: 1, limit: 20 }),
+  })
+  const events: string[] = metaData?.data?.events || []
+  const endpoints: WebhookEndpoint[] = data?.data?.items || []
+  const deliveries: WebhookDelivery[] = deliveriesData?.data?.items || []
+  const endpointNames = new Map(endpoints.map((endpoint) => [endpoint.id, endpoint.name]))
+  const statusLabels: Record<WebhookDelivery['status'], string> = {
+    pending: t.admin.webhookStatusPending,
+    succeeded: t.admin.webhookStatusSucceeded,
+    failed: t.admin.webhookStatusFailed,
+  }
+
+  const invalidate = () => {
+    queryClient.invalidateQueries({ queryKey: ['webhooks'] })
+    queryClient.invalidateQueries({ queryKey: ['webhookDeliveries'] })
+  }
+  const onError = (error: unknown) => {
+    toast.error(resolveApiErrorMessage(error, t, t.common.failed))
+  }
+
+  const openForm = (endpoint: WebhookEndpoint | null) => {
+    setEditing(endpoint)
+    setInput(
+      endpoint
+        ? {
+            name: endpoint.name,
+            url: endpoint.url,
+            events: endpoint.events || [],
+            enabled: endpoint.enabled,
+            description: endpoint.description || '',
+            platform: endpoint.platform || '',
+          }
+        : emptyInput
+    )
+    setFormOpen(true)
+  }
+
+  const saveMutation = useMutation({
+    mutationFn: () => (editing ? updateWebhook(editing.id, input) : createWebhook(input)),
+    onSuccess: (result: any) => {
+      toast.success(t.admin.webhookSaved)
+      invalidate()
+      setFormOpen(false)
+      if (result?.data?.secret) {
+        setSecret(result.data.secret)
+      }
+    },
+    onError,
+  })
+
+  const deleteMutation = useMutation({
+    mutationFn: (id: number) => deleteWebhook(id),
+    onSuccess: () => {
+      toast.success(t.admin.webhookDeleted)
+      invalidate()
+      setDeleteTarget(null)
+    },
+    onError,
+  })
+
+  const rotateMutation = useMutation({
+    mutationFn: (id: number) => rotateWebhookSecret(id),
+    onSuccess: (result: any) => {
+      setRotateTarget(null)
+      setSecret(result?.data?.secret || '')
+    },
+    onError,
+  })
+
+  const testMutation = useMutation({
+    mutationFn: (id: number) => testWebhook(id),
+    onSuccess: (result: any) => {
+      const delivery: WebhookDelivery | undefined = result?.data
+      if (delivery?.status === 'succeeded') {
+        toast.success(t.admin.webhookTestSucceeded)
+      } else {
+        toast.error(t.admin.webhookTestFailed.replace('{error}', delivery?.error || '-'))
+      }
+      invalidate()
+    },
+    onError,
+  })
+
+  const redeliverMutation = useMutation({
+    mutationFn: (id: number) => redeliverWebhook(id),
+    onSuccess: () => {
+      toast.success(t.admin.webhookRedelivered)
+      invalidate()
+    },
+    onError,
+  })
+
+  const toggleEvent = (event: string, checked: boolean) => {
+    setInput({
+      ...input,
+      events: checked ? [...input.events, event] : input.events.filter((item) => item !== event),
+    })
+  }
+
+  return (
+    <Card>
+      <CardHeader className="flex flex-row items-start justify-between space-y-0">
+        <div className="space-y-1">
+          <CardTitle className="flex items-center gap-2">
+            <Webhook className="h-5 w-5" />
+            {t.admin.webhooks}
+          </CardTitle>
+          <CardDescription>{t.admin.webhooksDesc}</CardDescription>
+        </div>
+        <Button size="sm" onClick={() => openForm(null)}>
+          <Plus className="mr-2 h-4 w-4" />
+          {t.admin.webhookCreate}
+        </Button>
+      </CardHeader>
+      <CardContent className="space-y-6 text-sm">
+        {endpoints.length === 0 ? (
+          <p className="text-muted-foreground">{t.admin.webhookNone}</p>
+        ) : (
+          <div className="space-y-2">
+            {endpoints.map((endpoint) => (
+              <div key={endpoint.id} className="space-y-1 rounded-md border p-3">
+                <div className="flex flex-wrap items-center gap-2">
+                  <span className="font-medium">{endpoint.name}</span>
+                  {!endpoint.enabled && <Badge variant="outline">{t.admin.webhookDisabled}</Badge>}
+                  {endpoint.platform && (
+                    <Badge variant="outline">
+                      {t.admin.webhookPlatform}: {endpoint.platform}
+                    </Badge>
+                  )}
+                  {endpoint.last_status && (
+                    <Badge variant={statusVariant[endpoint.last_status]}>
+                      {t.admin.webhookLastDelivery}: {statusLabels[endpoint.last_status]}
+                    </Badge>
+                  )}
+                  <div className="ml-auto flex gap-1">
+                    <Button
+                      variant="ghost"
+                      size="icon"
+                      title={t.admin.webhookTest}
+                      disabled={testMutation.isPending}
+                      onClick={() => testMutation.mutate(endpoint.id)}
+                    >
+                      <Send className="h-4 w-4" />
+                    </Button>
+                    <Button
+                      variant="ghost"
+                      size="icon"
+                      title={t.common.edit}
+                      onClick={() => openForm(endpoint)}
+                    >
+                      <Pencil className="h-4 w-4" />
+                    </Button>
+                    <Button
+                      variant="ghost"
+                      size="icon"
+                      title={t.admin.webhookRotateSecret}
+                      onClick={() => setRotateTarget(endpoint)}
+                    >
+                      <KeyRound className="h-4 w-4" />
+                    </Button>
+                    <Button
+                      variant="ghost"
+                      size="icon"
+                      title={t.common.delete}
+                      onClick={() => setDeleteTarget(endpoint)}
+                    >
+                      <Trash2 className="h-4 w-4" />
+                    </Button>
+                  </div>
+                </div>
+                <p className="break-all font-mono text-xs text-muted-foreground">{endpoint.url}</p>
+                <div className="flex flex-wrap gap-1">
+                  {(endpoint.events || []).map((event) => (
+                    <Badge key={event} variant="secondary" className="font-mono text-xs">
+                      {event === '*' ? t.admin.webhookAllEvents : event}
+                    </Badge>
+                  ))}
+                </div>
+              </div>
+            ))}
+          </div>
+        )}
+
+        <div className="space-y-2">
+          <h3 className="font-medium">{t.admin.webhookDeliveries}</h3>
+          {deliveries.length === 0 ? (
+            <p className="text-muted-foreground">{t.admin.webhookNoDeliveries}</p>
+          ) : (
+            deliveries.map((delivery) => (
+              <div
+                key={delivery.id}
+                className="flex flex-wrap items-center gap-2 rounded-md border p-2"
+              >
+                <Badge variant={statusVariant[delivery.status]}>
+                  {statusLabels[delivery.status]}
+                </Badge>
+                <span className="font-mono text-xs">{delivery.event}</span>
+                <span className="text-xs text-muted-foreground">
+                  {endpointNames.get(delivery.endpoint_id) || `#${delivery.endpoint_id}`}
+                </span>
+                {delivery.response_status ? (
+                  <span className="font-mono text-xs">HTTP {delivery.response_status}</span>
+                ) : null}
+                <span className="text-xs text-muted-foreground">
+                  {t.admin.webhookAttempts}: {delivery.attempts}
+                </span>
+                {delivery.status === 'pending' && delivery.next_attempt_at && (
+                  <span className="text-xs text-muted-foreground">
+                    {t.admin.webhookNextAttempt}: {formatDate(delivery.next_attempt_at)}
+                  </span>
+                )}
+                {delivery.error && (
+                  <span
+                    className="max-w-xs truncate text-xs text-destructive"
+                    title={delivery.error}
+                  >
+                    {delivery.error}
+                  </span>
+                )}
+                <span className="ml-auto text-xs text-muted-foreground">
+                  {formatDate(delivery.created_at)}
+                </span>
+                <Button
+                  variant="ghost"
+                  size="sm"
+                  disabled={redeliverMutation.isPending}
+                  onClick={() => redeliverMutation.mutate(delivery.id)}
+                >
+                  <RotateCw className="mr-1 h-3 w-3" />
+                  {t.admin.webhookRedeliver}
+                </Button>
+              </div>
+            ))
+          )}
+        </div>
+      </CardContent>
+
+      <Dialog open={formOpen} onOpenChange={setFormOpen}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{editing ? t.admin.webhookEdit : t.admin.webhookCreate}</DialogTitle>
+            <DialogDescription>{t.admin.webhooksDesc}</DialogDescription>
+          </DialogHeader>
+          <div className="space-y-4 py-2">
+            <div className="space-y-2">
+              <Label>{t.admin.webhookName}</Label>
+              <Input
+                value={input.name}
+                maxLength={100}
+                onChange={(e) => setInput({ ...input, name: e.target.value })}
+              />
+            </div>
+            <div className="space-y-2">
+              <Label>{t.admin.webhookUrl}</Label>
+              <Input
+                value={input.url}
+                placeholder="https://erp.example.com/hooks/auralogic"
+                onChange={(e) => setInput({ ...input, url: e.target.value })}
+              />
+            </div>
+            <div className="space-y-2">
+              <Label>{t.admin.webhookPlatform}</Label>
+              <Input
+                value={input.platform}
+                placeholder="shopify"
+                onChange={(e) => setInput({ ...input, platform: e.target.value })}
+              />
+              <p className="text-xs text-muted-foreground">{t.admin.webhookPlatformHint}</p>
+            </div>
+            <div className="space-y-2">
+              <Label>{t.admin.webhookEvents}</Label>
+              <div className="grid grid-cols-2 gap-2">
+                {['*', ...events].map((event) => (
+                  <label key={event} className="flex items-center gap-2 text-sm">
+                    <Checkbox
+                      checked={input.events.includes(event)}
+                      onCheckedChange={(checked) => toggleEvent(event, checked === true)}
+                    />
+                    <span className={event === '*' ? '' : 'font-mono text-xs'}>
+                      {event === '*' ? t.admin.webhookAllEvents : event}
+                    </span>
+                  </label>
+                ))}
+              </div>
+            </div>
+            <div className="space-y-2">
+              <Label>{t.admin.webhookDescription}</Label>
+              <Textarea
+                rows={2}
+                value={input.description}
+                onChange={(e) => setInput({ ...input, description: e.target.value })}
+              />
+            </div>
+            <div className="flex items-center gap-2">
+              <Switch
+                checked={input.enabled}
+                onCheckedChange={(checked) => setInput({ ...input, enabled: checked })}
+              />
+              <Label>{t.admin.webhookEnabled}</Label>
+            </div>
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setFormOpen(false)}>
+              {t.common.cancel}
+            </Button>
+            <Button onClick={() => saveMutation.mutate()} disabled={saveMutation.isPending}>
+              {t.common.save}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+
+      <Dialog open={!!secret} onOpenChange={(open) => !open && setSecret('')}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{t.admin.webhookSecretOnce}</DialogTitle>
+            <DialogDescription>{t.admin.webhookSecretHint}</DialogDescription>
+          </DialogHeader>
+          <code className="block select-all break-all rounded-md bg-muted p-3 font-mono text-sm">
+            {secret}
+          </code>
+          <DialogFooter>
+            <Button
+              variant="outline"
+              onClick={async () => {
+                if (typeof navigator === 'undefined' || !navigator.clipboard) {
+                  return
+                }
+                await navigator.clipboard.writeText(secret)
+                toast.success(t.common.copiedToClipboard)
+              }}
+            >
+              <Copy className="mr-2 h-4 w-4" />
+              {t.common.copy}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+
+      <AlertDialog open={!!rotateTarget} onOpenChange={(open) => !open && setRotateTarget(null)}>
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>{t.admin.webhookRotateSecret}</AlertDialogTitle>
+            <AlertDialogDescription>{t.admin.webhookConfirmRotate}</AlertDialogDescription>
+          </AlertDialogHeader>
+          <AlertDialogFooter>
+            <AlertDialogCancel>{t.common.cancel}</AlertDialogCancel>
+            <AlertDialogAction
+              onClick={() => rotateTarget && rotateMutation.mutate(rotateTarget.id)}
+            >
+              {t.common.confirm}
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+
+      <AlertDialog open={!!deleteTarget} onOpenChange={(open) => !open && setDeleteTarget(null)}>
+        <AlertDialogContent>
+          <AlertDialogHeader>
+            <AlertDialogTitle>{t.admin.confirmDelete}</AlertDialogTitle>
+            <AlertDialogDescription>{t.admin.webhookConfirmDelete}</AlertDialogDescription>
+          </AlertDialogHeader>
+          <AlertDialogFooter>
+            <AlertDialogCancel>{t.common.cancel}</AlertDialogCancel>
+            <AlertDialogAction
+              onClick={() => deleteTarget && deleteMutation.mutate(deleteTarget.id)}
+              className="bg-red-600 hover:bg-red-700"
+            >
+              {t.common.delete}
+            </AlertDialogAction>
+          </AlertDialogFooter>
+        </AlertDialogContent>
+      </AlertDialog>
+    </Card>
+  )
+}
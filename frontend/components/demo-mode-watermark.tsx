@@ -0,0 +1,31 @@
+'use client'
+
+import { useQuery } from '@tanstack/react-query'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+import { getPublicConfig } from '@/lib/api'
+
+// DemoModeWatermark 演示模式下在页面角落显示不可交互的水印
+export function DemoModeWatermark() {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const { data } = useQuery({
+    queryKey: ['publicConfig'],
+    queryFn: getPublicConfig,
+    staleTime: 5 * 60 * 1000,
+  })
+
+  if (!data?.data?.demo_mode) return null
+
+  return (
+    <div
+      className="pointer-events-none fixed bottom-3 left-1/2 z-[100] -translate-x-1/2 select-none"
+      aria-live="polite"
+    >
+      <div className="flex items-center gap-2 rounded-full border border-amber-500/40 bg-amber-50/90 px-3 py-1 text-xs text-amber-800 shadow-sm backdrop-blur dark:bg-amber-950/80 dark:text-amber-200">
+        <span className="font-semibold uppercase tracking-wide">{t.common.demoModeBadge}</span>
+        <span className="hidden sm:inline">{t.common.demoModeNotice}</span>
+      </div>
+    </div>
+  )
+}
@@ -19,19 +19,16 @@ import { useAuth } from '@/hooks/use-auth'
 import { createLoginSchema, loginSchema } from '@/lib/validators'
 import { useLocale } from '@/hooks/use-locale'
 import { getTranslations } from '@/lib/i18n'
+import {
+  isWidgetCaptchaProvider,
+  loadCaptchaScript,
+  renderCaptchaWidget,
+  resolveCaptchaScene,
+} from '@/lib/captcha'
 import { useQuery } from '@tanstack/react-query'
 import { getPublicConfig, getCaptcha } from '@/lib/api'
 import { useState, useEffect, useRef } from 'react'
 
-declare global {
-  interface Window {
-    turnstile?: any
-    grecaptcha?: any
-    onTurnstileLoad?: () => void
-    onRecaptchaLoad?: () => void
-  }
-}
-
 export function LoginForm() {
   const { login, isLoggingIn } = useAuth()
   const { locale } = useLocale()
@@ -46,7 +43,7 @@ export function LoginForm() {
     queryFn: getPublicConfig,
   })
 
-  const captchaConfig = publicConfig?.data?.captcha
+  const captchaConfig = resolveCaptchaScene(publicConfig?.data?.captcha, 'login')
   const needCaptcha = captchaConfig?.provider && captchaConfig.provider !== 'none' && captchaConfig.enable_for_login
 
   const { data: builtinCaptcha, refetch: refetchCaptcha } = useQuery({
@@ -55,60 +52,20 @@ export function LoginForm() {
     enabled: needCaptcha && captchaConfig?.provider === 'builtin',
   })
 
-  // Load Turnstile/reCAPTCHA scripts
+  // Load captcha script and render widget
   useEffect(() => {
-    if (!needCaptcha) return
-
-    if (captchaConfig.provider === 'cloudflare' && !document.getElementById('cf-turnstile-script')) {
-      const script = document.createElement('script')
-      script.id = 'cf-turnstile-script'
-      script.src = 'https://challenges.cloudflare.com/turnstile/v0/api.js?onload=onTurnstileLoad'
-      script.async = true
-      window.onTurnstileLoad = () => {
-        if (captchaContainerRef.current && !widgetRendered.current) {
-          widgetRendered.current = true
-          window.turnstile.render(captchaContainerRef.current, {
-            sitekey: captchaConfig.site_key,
-            callback: (token: string) => setCaptchaToken(token),
-          })
-        }
-      }
-      document.head.appendChild(script)
-    } else if (captchaConfig.provider === 'google' && !document.getElementById('recaptcha-script')) {
-      const script = document.createElement('script')
-      script.id = 'recaptcha-script'
-      script.src = 'https://www.google.com/recaptcha/api.js?onload=onRecaptchaLoad&render=explicit'
-      script.async = true
-      window.onRecaptchaLoad = () => {
-        if (captchaContainerRef.current && !widgetRendered.current) {
-          widgetRendered.current = true
-          window.grecaptcha.render(captchaContainerRef.current, {
-            sitekey: captchaConfig.site_key,
-            callback: (token: string) => setCaptchaToken(token),
-          })
-        }
-      }
-      document.head.appendChild(script)
-    }
-  }, [needCaptcha, captchaConfig])
-
-  // Render widget if script already loaded
-  useEffect(() => {
-    if (!needCaptcha || widgetRendered.current || !captchaContainerRef.current) return
-
-    if (captchaConfig.provider === 'cloudflare' && window.turnstile) {
+    if (!needCaptcha || !isWidgetCaptchaProvider(captchaConfig?.provider)) return
+    const provider = captchaConfig.provider
+    loadCaptchaScript(provider, captchaConfig.site_key, () => {
+      if (!captchaContainerRef.current || widgetRendered.current) return
       widgetRendered.current = true
-      window.turnstile.render(captchaContainerRef.current, {
-        sitekey: captchaConfig.site_key,
-        callback: (token: string) => setCaptchaToken(token),
+      renderCaptchaWidget(provider, captchaContainerRef.current, {
+        siteKey: captchaConfig.site_key,
+        action: 'login',
+        onToken: (token) => setCaptchaToken(token),
+        onExpire: () => setCaptchaToken(''),
       })
-    } else if (captchaConfig.provider === 'google' && window.grecaptcha?.render) {
-      widgetRendered.current = true
-      window.grecaptcha.render(captchaContainerRef.current, {
-        sitekey: captchaConfig.site_key,
-        callback: (token: string) => setCaptchaToken(token),
-      })
-    }
+    })
   }, [needCaptcha, captchaConfig])
 
   const schema = createLoginSchema({
@@ -180,7 +137,7 @@ export function LoginForm() {
             {/* Captcha */}
             {needCaptcha && (
               <div className="space-y-2">
-                {(captchaConfig.provider === 'cloudflare' || captchaConfig.provider === 'google') && (
+                {isWidgetCaptchaProvider(captchaConfig.provider) && (
                   <div ref={captchaContainerRef} />
                 )}
                 {captchaConfig.provider === 'builtin' && builtinCaptcha?.data && (
@@ -26,7 +26,7 @@ import {
   SelectTrigger,
   SelectValue,
 } from '@/components/ui/select'
-import { submitShippingForm, getCountries } from '@/lib/api'
+import { submitShippingForm, getCountries, getCountryRegions } from '@/lib/api'
 import { resolveApiErrorMessage } from '@/lib/api-error'
 import { shippingFormSchema } from '@/lib/validators'
 import toast from 'react-hot-toast'
@@ -72,6 +72,7 @@ export function ShippingForm({
   const t = translations.shippingForm
   const [isSubmitting, setIsSubmitting] = useState(false)
   const [countries, setCountries] = useState<any[]>([])
+  const [regions, setRegions] = useState<any[]>([])
 
   // 从 localStorage 读取上次填写的收货信息
   const savedShipping = (() => {
@@ -134,6 +135,25 @@ export function ShippingForm({
       })
   }, [activeLocale])
 
+  // 获取所选国家的省/州与城市，用于输入建议
+  useEffect(() => {
+    if (!selectedCountry) {
+      setRegions([])
+      return
+    }
+    let cancelled = false
+    getCountryRegions(selectedCountry)
+      .then((response: any) => {
+        if (!cancelled) setRegions(response.data || [])
+      })
+      .catch(() => {
+        if (!cancelled) setRegions([])
+      })
+    return () => {
+      cancelled = true
+    }
+  }, [selectedCountry])
+
   // 为区号选择器生成选项列表
   const phoneCodeOptions = countries.map((country) => {
     const phoneCode = phoneCodeMap[country.code] || `+${country.code}`
@@ -186,6 +206,15 @@ export function ShippingForm({
 
   // 判断是否是中国（需要填写省市区）
   const isChina = selectedCountry === 'CN'
+  const regionLabel = (item: any) => (isEnglish ? item.name_en : item.name_zh) || item.name_zh
+  const watchedProvince = form.watch('receiver_province')
+  const provinceKey = String(watchedProvince || '').trim().toLowerCase()
+  const matchedRegion = regions.find((region: any) =>
+    [region.code, region.name_zh, region.name_en, ...(region.aliases || [])].some(
+      (name: string) => name && name.toLowerCase() === provinceKey
+    )
+  )
+  const cityOptions: any[] = matchedRegion?.cities || []
   const shippingFormPluginContext = pluginSlotNamespace
     ? {
         ...(pluginSlotContext || {}),
@@ -402,7 +431,11 @@ export function ShippingForm({
                 <FormItem>
                   <FormLabel>{t.province} *</FormLabel>
                   <FormControl>
-                    <Input placeholder={t.provincePlaceholderCn} {...field} />
+                    <Input
+                      placeholder={t.provincePlaceholderCn}
+                      list="shipping-province-options"
+                      {...field}
+                    />
                   </FormControl>
                   <FormMessage />
                 </FormItem>
@@ -415,7 +448,11 @@ export function ShippingForm({
                 <FormItem>
                   <FormLabel>{t.city} *</FormLabel>
                   <FormControl>
-                    <Input placeholder={t.cityPlaceholderCn} {...field} />
+                    <Input
+                      placeholder={t.cityPlaceholderCn}
+                      list="shipping-city-options"
+                      {...field}
+                    />
                   </FormControl>
                   <FormMessage />
                 </FormItem>
@@ -446,7 +483,11 @@ export function ShippingForm({
                 <FormItem>
                   <FormLabel>{t.cityOptional}</FormLabel>
                   <FormControl>
-                    <Input placeholder={t.cityPlaceholder} {...field} />
+                    <Input
+                      placeholder={t.cityPlaceholder}
+                      list="shipping-city-options"
+                      {...field}
+                    />
                   </FormControl>
                   <FormMessage />
                 </FormItem>
@@ -459,7 +500,11 @@ export function ShippingForm({
                 <FormItem>
                   <FormLabel>{t.provinceOptional}</FormLabel>
                   <FormControl>
-                    <Input placeholder={t.provincePlaceholder} {...field} />
+                    <Input
+                      placeholder={t.provincePlaceholder}
+                      list="shipping-province-options"
+                      {...field}
+                    />
                   </FormControl>
                   <FormMessage />
                 </FormItem>
@@ -468,6 +513,17 @@ export function ShippingForm({
           </div>
         )}
 
+        <datalist id="shipping-province-options">
+          {regions.map((region: any) => (
+            <option key={region.code || region.name_zh} value={regionLabel(region)} />
+          ))}
+        </datalist>
+        <datalist id="shipping-city-options">
+          {cityOptions.map((city: any) => (
+            <option key={city.name_zh} value={regionLabel(city)} />
+          ))}
+        </datalist>
+
         <div className="grid grid-cols-2 gap-4">
           <FormField
             control={form.control}
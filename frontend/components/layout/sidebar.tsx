@@ -8,6 +8,7 @@ import { cn } from '@/lib/utils'
 import {
   LayoutDashboard,
   Package,
+  PackageOpen,
   Users,
   Settings,
   Key,
@@ -24,6 +25,8 @@ import {
   Megaphone,
   Send,
   Puzzle,
+  Receipt,
+  Banknote,
 } from 'lucide-react'
 import { Button } from '@/components/ui/button'
 import { usePermission } from '@/hooks/use-permission'
@@ -37,6 +40,8 @@ import {
   readPluginSearchParams,
 } from '@/lib/plugin-frontend-routing'
 import { LanguageSwitcher } from '@/components/layout/language-switcher'
+import { GlobalSearchDialog } from '@/components/admin/global-search-dialog'
+import { NotificationCenterDialog } from '@/components/admin/notification-center-dialog'
 import { PluginSlot } from '@/components/plugins/plugin-slot'
 import { getPublicConfig, type PluginFrontendBootstrapMenuItem } from '@/lib/api'
 import { manifestString } from '@/lib/package-manifest-schema'
@@ -86,6 +91,30 @@ const menuItems = [
     icon: Package,
     permission: 'order.view',
   },
+  {
+    titleKey: 'quoteRequests' as const,
+    href: '/admin/quotes',
+    icon: FileText,
+    permission: 'order.view',
+  },
+  {
+    titleKey: 'returnRequests' as const,
+    href: '/admin/returns',
+    icon: PackageOpen,
+    permission: 'return.view',
+  },
+  {
+    titleKey: 'paymentMatching' as const,
+    href: '/admin/payment-matching',
+    icon: Receipt,
+    permission: 'order.view',
+  },
+  {
+    titleKey: 'codReconciliation' as const,
+    href: '/admin/cod',
+    icon: Banknote,
+    permission: 'order.view',
+  },
   {
     titleKey: 'serialManagement' as const,
     href: '/admin/serials',
@@ -338,6 +367,8 @@ export function Sidebar() {
     <div className="flex w-64 flex-col border-r bg-card">
       <div className="p-6">
         <h2 className="text-lg font-bold">{t.admin.adminPanel}</h2>
+        <GlobalSearchDialog />
+        <NotificationCenterDialog />
         <Suspense fallback={null}>
           <PluginSlot slot="admin.layout.sidebar.top" context={adminSidebarPluginContext} />
         </Suspense>
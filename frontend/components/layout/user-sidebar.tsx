@@ -21,6 +21,7 @@ import {
   MessageSquare,
   BookOpen,
   Megaphone,
+  FileText,
 } from 'lucide-react'
 import { Button } from '@/components/ui/button'
 import { useAuth } from '@/hooks/use-auth'
@@ -53,6 +54,7 @@ const getUserMenuItems = (t: any) => [
   { title: t.sidebar.productCenter, href: '/products', icon: ShoppingBag, matchDescendants: true },
   { title: t.sidebar.cart || 'Cart', href: '/cart', icon: ShoppingCart },
   { title: t.sidebar.myOrders, href: '/orders', icon: Package, matchDescendants: true },
+  { title: t.sidebar.myQuotes, href: '/quotes', icon: FileText, matchDescendants: true },
   { title: t.sidebar.serialVerify, href: '/serial-verify', icon: ShieldCheck, matchDescendants: true },
   { title: t.sidebar.supportCenter || 'Support', href: '/tickets', icon: MessageSquare, matchDescendants: true },
   { title: t.sidebar.knowledgeBase || 'Knowledge', href: '/knowledge', icon: BookOpen, matchDescendants: true },
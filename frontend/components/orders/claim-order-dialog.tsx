@@ -0,0 +1,102 @@
+'use client'
+
+import { useState } from 'react'
+import { useMutation } from '@tanstack/react-query'
+import { Link2 } from 'lucide-react'
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { claimOrder } from '@/lib/api'
+
+interface ClaimOrderDialogProps {
+  compact?: boolean
+  onClaimed?: () => void
+}
+
+export function ClaimOrderDialog({ compact = false, onClaimed }: ClaimOrderDialogProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const [open, setOpen] = useState(false)
+  const [orderNo, setOrderNo] = useState('')
+
+  const claimMutation = useMutation({
+    mutationFn: () => claimOrder(orderNo.trim()),
+    onSuccess: () => {
+      toast.success(t.order.claimOrderSuccess)
+      setOpen(false)
+      setOrderNo('')
+      onClaimed?.()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.order.claimOrderFailed))
+    },
+  })
+
+  return (
+    <>
+      <Button
+        variant="outline"
+        size="sm"
+        onClick={() => setOpen(true)}
+        aria-label={t.order.claimOrder}
+        title={t.order.claimOrder}
+        className="shrink-0"
+      >
+        <Link2 className={`h-4 w-4 ${!compact ? 'mr-2' : ''}`} />
+        {compact ? (
+          <span className="sr-only">{t.order.claimOrder}</span>
+        ) : (
+          <span>{t.order.claimOrder}</span>
+        )}
+      </Button>
+      <Dialog open={open} onOpenChange={setOpen}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{t.order.claimOrder}</DialogTitle>
+            <DialogDescription>{t.order.claimOrderDesc}</DialogDescription>
+          </DialogHeader>
+          <form
+            className="space-y-2"
+            onSubmit={(e) => {
+              e.preventDefault()
+              if (orderNo.trim()) claimMutation.mutate()
+            }}
+          >
+            <Label htmlFor="claim-order-no">{t.order.orderNo}</Label>
+            <Input
+              id="claim-order-no"
+              value={orderNo}
+              onChange={(e) => setOrderNo(e.target.value)}
+              placeholder={t.order.claimOrderPlaceholder}
+              autoComplete="off"
+            />
+          </form>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setOpen(false)}>
+              {t.common.cancel}
+            </Button>
+            <Button
+              onClick={() => claimMutation.mutate()}
+              disabled={claimMutation.isPending || !orderNo.trim()}
+            >
+              {t.order.claimOrderConfirm}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </>
+  )
+}
@@ -0,0 +1,174 @@
+'use client'
+
+import { useState } from 'react'
+import { Download, Loader2 } from 'lucide-react'
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+} from '@/components/ui/select'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { resolveClientAPIProxyURL } from '@/lib/api-base-url'
+import { applyFetchSessionHeaders } from '@/lib/api'
+
+type ExportFormat = 'xlsx' | 'csv'
+
+function formatDateInput(date: Date) {
+  return date.toISOString().slice(0, 10)
+}
+
+// 与后端默认区间一致：最近 90 天（含今天）
+function defaultRange() {
+  const end = new Date()
+  const start = new Date(end.getTime() - 89 * 24 * 60 * 60 * 1000)
+  return { start: formatDateInput(start), end: formatDateInput(end) }
+}
+
+interface ExportOrdersDialogProps {
+  compact?: boolean
+}
+
+// ExportOrdersDialog 用户按日期范围导出自己的订单（CSV/XLSX）
+export function ExportOrdersDialog({ compact = false }: ExportOrdersDialogProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const [open, setOpen] = useState(false)
+  const [startDate, setStartDate] = useState('')
+  const [endDate, setEndDate] = useState('')
+  const [format, setFormat] = useState<ExportFormat>('xlsx')
+  const [downloading, setDownloading] = useState(false)
+
+  const openDialog = () => {
+    const range = defaultRange()
+    setStartDate(range.start)
+    setEndDate(range.end)
+    setOpen(true)
+  }
+
+  const handleDownload = async () => {
+    const params = new URLSearchParams({ format, start_date: startDate, end_date: endDate })
+    setDownloading(true)
+    try {
+      const headers = new Headers()
+      await applyFetchSessionHeaders(headers, 'GET')
+      const res = await fetch(resolveClientAPIProxyURL(`/api/user/orders/export?${params}`), {
+        headers,
+      })
+      if (!res.ok) {
+        let payload: unknown = null
+        try {
+          payload = await res.json()
+        } catch {
+          // 非 JSON 错误响应使用默认提示
+        }
+        throw payload
+      }
+      const blob = await res.blob()
+      const url = window.URL.createObjectURL(blob)
+      const a = document.createElement('a')
+      a.href = url
+      a.download = `orders_${startDate}_${endDate}.${format}`
+      document.body.appendChild(a)
+      a.click()
+      document.body.removeChild(a)
+      window.URL.revokeObjectURL(url)
+      toast.success(t.order.exportOrdersSuccess)
+      setOpen(false)
+    } catch (error: unknown) {
+      toast.error(resolveApiErrorMessage(error, t, t.order.exportOrdersFailed))
+    } finally {
+      setDownloading(false)
+    }
+  }
+
+  return (
+    <>
+      <Button
+        variant="outline"
+        size="sm"
+        onClick={openDialog}
+        aria-label={t.order.exportOrders}
+        title={t.order.exportOrders}
+        className="shrink-0"
+      >
+        <Download className={`h-4 w-4 ${!compact ? 'mr-2' : ''}`} />
+        {compact ? (
+          <span className="sr-only">{t.order.exportOrders}</span>
+        ) : (
+          <span>{t.order.exportOrders}</span>
+        )}
+      </Button>
+      <Dialog open={open} onOpenChange={setOpen}>
+        <DialogContent>
+          <DialogHeader>
+            <DialogTitle>{t.order.exportOrders}</DialogTitle>
+            <DialogDescription>{t.order.exportOrdersDesc}</DialogDescription>
+          </DialogHeader>
+          <div className="space-y-4">
+            <div className="grid gap-4 sm:grid-cols-2">
+              <div className="space-y-1.5">
+                <Label htmlFor="export-start-date">{t.order.exportStartDate}</Label>
+                <Input
+                  id="export-start-date"
+                  type="date"
+                  value={startDate}
+                  max={endDate || undefined}
+                  onChange={(e) => setStartDate(e.target.value)}
+                />
+              </div>
+              <div className="space-y-1.5">
+                <Label htmlFor="export-end-date">{t.order.exportEndDate}</Label>
+                <Input
+                  id="export-end-date"
+                  type="date"
+                  value={endDate}
+                  min={startDate || undefined}
+                  onChange={(e) => setEndDate(e.target.value)}
+                />
+              </div>
+            </div>
+            <div className="space-y-1.5">
+              <Label>{t.order.exportFormat}</Label>
+              <Select value={format} onValueChange={(value) => setFormat(value as ExportFormat)}>
+                <SelectTrigger>
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="xlsx">Excel (.xlsx)</SelectItem>
+                  <SelectItem value="csv">CSV (.csv)</SelectItem>
+                </SelectContent>
+              </Select>
+            </div>
+            <p className="text-xs text-muted-foreground">{t.order.exportRangeHint}</p>
+          </div>
+          <DialogFooter>
+            <Button variant="outline" onClick={() => setOpen(false)}>
+              {t.common.cancel}
+            </Button>
+            <Button onClick={handleDownload} disabled={downloading || !startDate || !endDate}>
+              {downloading && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+              {t.order.exportOrdersConfirm}
+            </Button>
+          </DialogFooter>
+        </DialogContent>
+      </Dialog>
+    </>
+  )
+}
@@ -394,6 +394,15 @@ export function OrderCard({
             {formatCurrency(order.total_amount_minor ?? 0, order.currency)}
           </span>
         </div>
+        {order.display_amount && (
+          <p
+            className="text-right text-xs text-muted-foreground"
+            title={t.order.displayAmountApproximate}
+          >
+            ≈ {formatCurrency(order.display_amount.amount_minor, order.display_amount.currency)}
+            {order.display_amount.stale ? ` (${t.order.exchangeRateStale})` : ''}
+          </p>
+        )}
         {pluginSlotNamespace ? (
           <PluginSlot
             slot={`${pluginSlotNamespace}.card.summary.after`}
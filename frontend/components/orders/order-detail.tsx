@@ -56,12 +56,20 @@ interface OrderDetailProps {
   isVirtualOnly?: boolean
   compactLayout?: boolean
   paymentCard?: ReactNode
+  notesCard?: ReactNode
+  packagePlanCard?: ReactNode
+  refundsCard?: ReactNode
+  messagesCard?: ReactNode
+  trackingCard?: ReactNode
   shippingForm?: ReactNode
   shippingFormURL?: string
   shippingFormToken?: string
   shippingFormExpiresAt?: string
   showVirtualStockRemark?: boolean
   showOperationalMeta?: boolean
+  virtualStockHeaderAction?: ReactNode
+  renderVirtualStockActions?: (stock: VirtualProductStock) => ReactNode
+  revealVirtualStockContent?: (stock: VirtualProductStock) => Promise<string>
   pluginSlotNamespace?: string
   pluginSlotContext?: Record<string, any>
   pluginSlotPath?: string
@@ -166,12 +174,20 @@ export function OrderDetail({
   isVirtualOnly = false,
   compactLayout = false,
   paymentCard,
+  notesCard,
+  packagePlanCard,
+  refundsCard,
+  messagesCard,
+  trackingCard,
   shippingForm,
   shippingFormURL,
   shippingFormToken,
   shippingFormExpiresAt,
   showVirtualStockRemark = false,
   showOperationalMeta = false,
+  virtualStockHeaderAction,
+  renderVirtualStockActions,
+  revealVirtualStockContent,
   pluginSlotNamespace,
   pluginSlotContext,
   pluginSlotPath,
@@ -181,6 +197,7 @@ export function OrderDetail({
   const isDraft = order.status === 'draft'
   const isNeedResubmit = order.status === 'need_resubmit'
   const [showContent, setShowContent] = useState<Record<number, boolean>>({})
+  const [revealedContent, setRevealedContent] = useState<Record<number, string>>({})
   const [showInlineIframe, setShowInlineIframe] = useState<Record<number, boolean>>({})
   const orderItems = Array.isArray(order.items) ? order.items : []
   const serialGenerationStatus = String(
@@ -212,7 +229,6 @@ export function OrderDetail({
   const sourcePlatform = String(
     order.sourcePlatform || order.source_platform || order.platform || ''
   ).trim()
-  const adminRemark = String(order.adminRemark || order.admin_remark || '').trim()
   const buildSectionPluginContext = useCallback(
     (section: string, extra?: Record<string, any>) => ({
       ...(pluginSlotContext || {}),
@@ -236,8 +252,34 @@ export function OrderDetail({
       />
     ) : null
 
-  const toggleContentVisibility = (id: number) => {
-    setShowContent((prev) => ({ ...prev, [id]: !prev[id] }))
+  // 列表中的卡密默认脱敏，明文通过 reveal 接口获取（会记录查看日志）
+  const resolveStockContent = async (stock: VirtualProductStock) => {
+    if (revealedContent[stock.id] !== undefined) return revealedContent[stock.id]
+    if (!revealVirtualStockContent) return stock.content
+    const content = await revealVirtualStockContent(stock)
+    setRevealedContent((prev) => ({ ...prev, [stock.id]: content }))
+    return content
+  }
+
+  const toggleContentVisibility = async (stock: VirtualProductStock) => {
+    if (showContent[stock.id]) {
+      setShowContent((prev) => ({ ...prev, [stock.id]: false }))
+      return
+    }
+    try {
+      await resolveStockContent(stock)
+      setShowContent((prev) => ({ ...prev, [stock.id]: true }))
+    } catch {
+      // 错误提示由调用方处理
+    }
+  }
+
+  const copyStockContent = async (stock: VirtualProductStock) => {
+    try {
+      copyToClipboard(await resolveStockContent(stock))
+    } catch {
+      // 错误提示由调用方处理
+    }
   }
 
   const toggleInlineIframeVisibility = (id: number) => {
@@ -558,6 +600,21 @@ export function OrderDetail({
               <dd className="font-semibold text-foreground">
                 {formatCurrency(order.total_amount_minor ?? 0, order.currency)}
               </dd>
+              {!!order.display_amounts?.length && (
+                <dd
+                  className="text-xs text-muted-foreground"
+                  title={t.order.displayAmountApproximate}
+                >
+                  {order.display_amounts
+                    .map(
+                      (amount) =>
+                        `≈ ${formatCurrency(amount.amount_minor, amount.currency)}${
+                          amount.stale ? ` (${t.order.exchangeRateStale})` : ''
+                        }`
+                    )
+                    .join(' · ')}
+                </dd>
+              )}
             </div>
             {showOperationalMeta && source && (
               <div>
@@ -753,16 +810,19 @@ export function OrderDetail({
                     {virtualStocks.length}
                   </Badge>
                 </div>
-                {pluginSlotNamespace ? (
-                  <PluginSlot
-                    slot={`${pluginSlotNamespace}.virtual_stock_actions`}
-                    path={pluginSlotPath}
-                    context={buildSectionPluginContext('virtual_stocks', {
-                      virtual_stock_count: virtualStocks.length,
-                    })}
-                    display="inline"
-                  />
-                ) : null}
+                <div className="flex items-center gap-2">
+                  {virtualStockHeaderAction}
+                  {pluginSlotNamespace ? (
+                    <PluginSlot
+                      slot={`${pluginSlotNamespace}.virtual_stock_actions`}
+                      path={pluginSlotPath}
+                      context={buildSectionPluginContext('virtual_stocks', {
+                        virtual_stock_count: virtualStocks.length,
+                      })}
+                      display="inline"
+                    />
+                  ) : null}
+                </div>
               </div>
             </CardHeader>
 
@@ -774,6 +834,27 @@ export function OrderDetail({
                 </div>
 
                 {virtualStocks.map((stock) => {
+                  if (stock.status === 'revoked') {
+                    return (
+                      <div
+                        key={stock.id}
+                        className="space-y-1 rounded-lg border border-dashed p-3 text-sm text-muted-foreground"
+                      >
+                        <div className="flex flex-wrap items-center gap-2">
+                          <Badge variant="destructive">{t.order.virtualStockRevoked}</Badge>
+                          {stock.content ? (
+                            <code className="break-all font-mono line-through">{stock.content}</code>
+                          ) : null}
+                        </div>
+                        {stock.revoke_reason && <p>{stock.revoke_reason}</p>}
+                        {stock.revoked_at && (
+                          <div className="text-xs">
+                            {t.order.virtualStockRevokedAt}: {formatDate(stock.revoked_at)}
+                          </div>
+                        )}
+                      </div>
+                    )
+                  }
                   const inlineIframe = resolveVirtualStockInlineIframe(stock, slotScope)
                   return (
                     <div
@@ -788,7 +869,9 @@ export function OrderDetail({
                               !compactLayout && 'md:px-3 md:py-2 md:text-lg'
                             )}
                           >
-                            {showContent[stock.id] ? stock.content : '************'}
+                            {showContent[stock.id]
+                              ? (revealedContent[stock.id] ?? stock.content)
+                              : stock.content || '************'}
                           </code>
                           <div className="flex shrink-0 items-center">
                             <Button
@@ -796,7 +879,7 @@ export function OrderDetail({
                               variant="ghost"
                               size="sm"
                               className={cn('h-7 w-7 p-0', !compactLayout && 'md:h-8 md:w-8')}
-                              onClick={() => toggleContentVisibility(stock.id)}
+                              onClick={() => toggleContentVisibility(stock)}
                               aria-label={`${showContent[stock.id] ? t.common.collapse : t.common.expand} ${t.order.virtualProductContent}`}
                               title={`${showContent[stock.id] ? t.common.collapse : t.common.expand} ${t.order.virtualProductContent}`}
                             >
@@ -811,7 +894,7 @@ export function OrderDetail({
                               variant="ghost"
                               size="sm"
                               className={cn('h-7 w-7 p-0', !compactLayout && 'md:h-8 md:w-8')}
-                              onClick={() => copyToClipboard(stock.content)}
+                              onClick={() => copyStockContent(stock)}
                               aria-label={`${t.common.copy} ${t.order.virtualProductContent}`}
                               title={`${t.common.copy} ${t.order.virtualProductContent}`}
                             >
@@ -819,6 +902,7 @@ export function OrderDetail({
                             </Button>
                           </div>
                         </div>
+                        {renderVirtualStockActions?.(stock)}
                       </div>
                       {showVirtualStockRemark && stock.remark && (
                         <p className="text-sm text-muted-foreground">{stock.remark}</p>
@@ -838,6 +922,14 @@ export function OrderDetail({
                           {t.order.deliveryTime}: {formatDate(stock.delivered_at)}
                         </div>
                       )}
+                      {showOperationalMeta && (
+                        <div className="text-xs text-muted-foreground">
+                          {t.order.virtualStockFirstViewedAt}:{' '}
+                          {stock.first_viewed_at
+                            ? formatDate(stock.first_viewed_at)
+                            : t.order.virtualStockNotViewed}
+                        </div>
+                      )}
                     </div>
                   )
                 })}
@@ -1087,22 +1179,15 @@ export function OrderDetail({
         </Card>
       )}
 
-      {showOperationalMeta && adminRemark && (
-        <Card>
-          <CardHeader>
-            <CardTitle className="flex items-center gap-2">
-              <MessageSquare className="h-5 w-5" />
-              {t.order.adminRemark}
-            </CardTitle>
-          </CardHeader>
+      {trackingCard}
 
-          <CardContent>
-            <div className="rounded-md bg-muted/50 p-4">
-              <p className="whitespace-pre-wrap text-sm">{adminRemark}</p>
-            </div>
-          </CardContent>
-        </Card>
-      )}
+      {messagesCard}
+
+      {showOperationalMeta && packagePlanCard}
+
+      {showOperationalMeta && refundsCard}
+
+      {showOperationalMeta && notesCard}
 
       {showSerialGenerationState && serialGenerationMeta ? (
         <Card>
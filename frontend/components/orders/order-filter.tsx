@@ -13,7 +13,7 @@ import {
   SelectValue,
 } from '@/components/ui/select'
 import { Input } from '@/components/ui/input'
-import { Search, X, User } from 'lucide-react'
+import { Search, X, User, Tag } from 'lucide-react'
 import { Button } from '@/components/ui/button'
 import { Badge } from '@/components/ui/badge'
 import { useLocale } from '@/hooks/use-locale'
@@ -77,11 +77,13 @@ interface OrderFilterProps {
   userId?: number
   country?: string
   productSearch?: string
+  tag?: string
   onStatusChange: (status: string | undefined) => void
   onSearchChange?: (search: string) => void
   onUserChange?: (userId: number | undefined) => void
   onCountryChange?: (country: string | undefined) => void
   onProductSearchChange?: (productSearch: string) => void
+  onTagChange?: (tag: string) => void // 标签筛选（仅管理员，逗号分隔需同时包含）
   useSmartCountryFilter?: boolean // 是否使用智能国家筛选（仅管理员）
   pluginSlotNamespace?: string
   pluginSlotContext?: Record<string, any>
@@ -94,11 +96,13 @@ export function OrderFilter({
   userId,
   country,
   productSearch,
+  tag,
   onStatusChange,
   onSearchChange,
   onUserChange,
   onCountryChange,
   onProductSearchChange,
+  onTagChange,
   useSmartCountryFilter = false,
   pluginSlotNamespace,
   pluginSlotContext,
@@ -164,11 +168,13 @@ export function OrderFilter({
       user_id: userId,
       country: country || undefined,
       product_search: productSearch || undefined,
+      tag: tag || undefined,
     },
     capabilities: {
       user_filter_enabled: Boolean(onUserChange),
       country_filter_enabled: Boolean(onCountryChange),
       product_filter_enabled: Boolean(onProductSearchChange),
+      tag_filter_enabled: Boolean(onTagChange),
       smart_country_filter: useSmartCountryFilter,
     },
     summary: {
@@ -180,7 +186,9 @@ export function OrderFilter({
       user_list_open: showUserList,
       user_list_loading: Boolean(onUserChange && showUserList && !usersData),
       user_list_empty: Boolean(showUserList && userSearch && usersData && !usersData?.data?.items?.length),
-      has_active_filters: Boolean(status || search || userId || country || productSearch),
+      has_active_filters: Boolean(
+        status || search || userId || country || productSearch || tag
+      ),
     },
   }
   const filterGridClassName = isMobile
@@ -249,6 +257,36 @@ export function OrderFilter({
             </div>
           )}
 
+          {/* 标签筛选 */}
+          {onTagChange && (
+            <div>
+              <label className="mb-2 block text-sm font-medium">{t.order.filterTag}</label>
+              <div className="relative">
+                <Tag className="absolute left-3 top-1/2 h-4 w-4 -translate-y-1/2 text-muted-foreground" />
+                <Input
+                  placeholder={t.order.tagFilterPlaceholder}
+                  value={tag}
+                  onChange={(e) => onTagChange(e.target.value)}
+                  className="pl-10 pr-10"
+                />
+                {tag ? (
+                  <Button
+                    type="button"
+                    variant="ghost"
+                    size="sm"
+                    onClick={() => onTagChange('')}
+                    className="absolute right-1 top-1/2 h-7 w-7 -translate-y-1/2 p-0"
+                    aria-label={t.common.clear}
+                    title={t.common.clear}
+                  >
+                    <X className="h-4 w-4" />
+                    <span className="sr-only">{t.common.clear}</span>
+                  </Button>
+                ) : null}
+              </div>
+            </div>
+          )}
+
           {/* 用户筛选 */}
           {onUserChange && (
             <div className="relative">
@@ -0,0 +1,175 @@
+'use client'
+
+import { useState } from 'react'
+import Link from 'next/link'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
+import { MessagesSquare, Ticket } from 'lucide-react'
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
+import { Button } from '@/components/ui/button'
+import { Textarea } from '@/components/ui/textarea'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { cn, formatDate } from '@/lib/utils'
+import {
+  escalateAdminOrderMessages,
+  escalateOrderMessages,
+  getAdminOrderMessages,
+  getOrderMessages,
+  replyAdminOrderMessage,
+  sendOrderMessage,
+} from '@/lib/api'
+import type { OrderMessage, OrderMessageThread } from '@/types/order'
+
+interface OrderMessagesCardProps {
+  mode: 'user' | 'admin'
+  orderNo: string
+  orderId?: number
+  canReply?: boolean
+}
+
+export function OrderMessagesCard({
+  mode,
+  orderNo,
+  orderId,
+  canReply = true,
+}: OrderMessagesCardProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [content, setContent] = useState('')
+  const isAdmin = mode === 'admin'
+  const queryKey = ['orderMessages', mode, isAdmin ? orderId : orderNo]
+
+  const { data } = useQuery({
+    queryKey,
+    queryFn: () => (isAdmin ? getAdminOrderMessages(orderId!) : getOrderMessages(orderNo)),
+    enabled: isAdmin ? !!orderId : !!orderNo,
+    refetchInterval: 30000,
+  })
+  const thread: OrderMessageThread | undefined = data?.data
+  const messages = thread?.messages || []
+
+  const refresh = () => queryClient.invalidateQueries({ queryKey })
+
+  const sendMutation = useMutation({
+    mutationFn: () =>
+      isAdmin ? replyAdminOrderMessage(orderId!, content) : sendOrderMessage(orderNo, content),
+    onSuccess: () => {
+      setContent('')
+      refresh()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.order.orderMessageSendFailed))
+    },
+  })
+  const escalateMutation = useMutation({
+    mutationFn: () =>
+      isAdmin ? escalateAdminOrderMessages(orderId!) : escalateOrderMessages(orderNo),
+    onSuccess: refresh,
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.order.orderMessageEscalateFailed))
+    },
+  })
+
+  const isOwn = (message: OrderMessage) => message.sender_type === mode
+  const senderLabel = (message: OrderMessage) => {
+    if (isOwn(message)) return t.order.orderMessageYou
+    if (isAdmin) return message.sender_name || t.order.orderMessageCustomer
+    return t.order.orderMessageSeller
+  }
+
+  const ticketHref = thread?.ticket
+    ? isAdmin
+      ? '/admin/tickets'
+      : `/tickets/${thread.ticket.id}`
+    : ''
+
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle className="flex items-center gap-2">
+          <MessagesSquare className="h-5 w-5" />
+          {t.order.orderMessages}
+        </CardTitle>
+        {!isAdmin && <CardDescription>{t.order.orderMessagesDesc}</CardDescription>}
+      </CardHeader>
+      <CardContent className="space-y-3">
+        {messages.length === 0 && (
+          <p className="text-sm text-muted-foreground">{t.order.orderMessagesEmpty}</p>
+        )}
+        {messages
+          .filter((message) => message.sender_type !== 'system')
+          .map((message) => (
+            <div
+              key={message.id}
+              className={cn('flex', isOwn(message) ? 'justify-end' : 'justify-start')}
+            >
+              <div
+                className={cn(
+                  'max-w-[85%] rounded-lg px-3 py-2',
+                  isOwn(message) ? 'bg-primary/10' : 'bg-muted/60'
+                )}
+              >
+                <div className="mb-1 flex items-center gap-2 text-xs text-muted-foreground">
+                  <span className="font-medium text-foreground">{senderLabel(message)}</span>
+                  <span>{formatDate(message.created_at)}</span>
+                </div>
+                <p className="whitespace-pre-wrap break-words text-sm">{message.content}</p>
+              </div>
+            </div>
+          ))}
+
+        {thread?.ticket ? (
+          <div className="flex flex-wrap items-center justify-between gap-2 rounded-md border border-dashed p-3 text-sm">
+            <span className="flex items-center gap-2 text-muted-foreground">
+              <Ticket className="h-4 w-4" />
+              {t.order.orderMessageEscalated.replace('{ticketNo}', thread.ticket.ticket_no)}
+            </span>
+            <Button asChild variant="outline" size="sm">
+              <Link href={ticketHref}>{t.order.orderMessageViewTicket}</Link>
+            </Button>
+          </div>
+        ) : (
+          canReply && (
+            <div className="space-y-2 border-t pt-3">
+              <Textarea
+                placeholder={t.order.orderMessagePlaceholder}
+                value={content}
+                onChange={(e) => setContent(e.target.value)}
+                rows={3}
+                maxLength={2000}
+              />
+              <div className="flex flex-wrap justify-end gap-2">
+                {thread?.can_escalate && (
+                  <Button
+                    variant="outline"
+                    size="sm"
+                    onClick={() => {
+                      if (window.confirm(t.order.orderMessageEscalateConfirm)) {
+                        escalateMutation.mutate()
+                      }
+                    }}
+                    disabled={escalateMutation.isPending}
+                  >
+                    <Ticket className="mr-1.5 h-4 w-4" />
+                    {t.order.orderMessageEscalate}
+                  </Button>
+                )}
+                <Button
+                  size="sm"
+                  onClick={() => sendMutation.mutate()}
+                  disabled={sendMutation.isPending || !content.trim()}
+                >
+                  {t.order.orderMessageSend}
+                </Button>
+              </div>
+            </div>
+          )
+        )}
+      </CardContent>
+    </Card>
+  )
+}
@@ -0,0 +1,165 @@
+'use client'
+
+import { useState } from 'react'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
+import { PackageOpen } from 'lucide-react'
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
+import { Badge } from '@/components/ui/badge'
+import { Button } from '@/components/ui/button'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { formatDate } from '@/lib/utils'
+import { cancelReturnRequest, getOrderReturnRequests, type ReturnRequest } from '@/lib/api'
+import type { Order } from '@/types/order'
+import { ReturnRequestDialog, type ReturnableItem } from './return-request-dialog'
+import { getReturnStatusConfig } from './return-status'
+
+const RETURNABLE_STATUSES = ['shipped', 'completed', 'refund_pending', 'refunded']
+
+// orderAcceptsReturns 与后端一致：已发货后才可申请退货
+export function orderAcceptsReturns(order: Order) {
+  return !!(order.shipped_at || order.shippedAt) && RETURNABLE_STATUSES.includes(order.status)
+}
+
+interface OrderReturnsCardProps {
+  order: Order
+  orderNo: string
+}
+
+// OrderReturnsCard 用户订单详情中的退货申请列表与申请入口
+export function OrderReturnsCard({ order, orderNo }: OrderReturnsCardProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const statusConfig = getReturnStatusConfig(t)
+  const [dialogOpen, setDialogOpen] = useState(false)
+  const queryKey = ['orderReturnRequests', orderNo]
+
+  const { data } = useQuery({
+    queryKey,
+    queryFn: () => getOrderReturnRequests(orderNo),
+  })
+  const requests: ReturnRequest[] = data?.data || []
+  const hasOpenRequest = requests.some((r) => r.status === 'pending' || r.status === 'approved')
+
+  const returnableItems: ReturnableItem[] = (order.items || [])
+    .map((item, index) => ({
+      index,
+      name: item.name,
+      sku: item.sku,
+      remaining: item.quantity - (order.returned_quantities?.[String(index)] || 0),
+      virtual: (item.product_type || item.productType) === 'virtual',
+    }))
+    .filter((item) => !item.virtual && item.remaining > 0)
+
+  const refresh = () => queryClient.invalidateQueries({ queryKey })
+
+  const cancelMutation = useMutation({
+    mutationFn: (id: number) => cancelReturnRequest(orderNo, id),
+    onSuccess: () => {
+      toast.success(t.returnRequest.cancelled)
+      refresh()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.returnRequest.cancelFailed))
+    },
+  })
+
+  if (requests.length === 0 && returnableItems.length === 0) {
+    return null
+  }
+
+  return (
+    <Card>
+      <CardHeader className="flex flex-row items-start justify-between gap-2 space-y-0">
+        <div className="space-y-1.5">
+          <CardTitle className="flex items-center gap-2">
+            <PackageOpen className="h-5 w-5" />
+            {t.returnRequest.title}
+          </CardTitle>
+          <CardDescription>{t.returnRequest.cardDesc}</CardDescription>
+        </div>
+        {!hasOpenRequest && returnableItems.length > 0 && (
+          <Button size="sm" variant="outline" onClick={() => setDialogOpen(true)}>
+            {t.returnRequest.requestReturn}
+          </Button>
+        )}
+      </CardHeader>
+      <CardContent className="space-y-3">
+        {requests.length === 0 && (
+          <p className="text-sm text-muted-foreground">{t.returnRequest.empty}</p>
+        )}
+        {requests.map((request) => {
+          const config = statusConfig[request.status] || statusConfig.pending
+          return (
+            <div key={request.id} className="space-y-2 rounded-md border p-3 text-sm">
+              <div className="flex items-start justify-between gap-2">
+                <div>
+                  <div className="font-mono">{request.rma_no}</div>
+                  <div className="text-xs text-muted-foreground">
+                    {formatDate(request.created_at)}
+                  </div>
+                </div>
+                <Badge className={config.color}>{config.label}</Badge>
+              </div>
+              <ul className="text-muted-foreground">
+                {request.items.map((item) => (
+                  <li key={item.item_index}>
+                    {item.name} × {item.quantity}
+                  </li>
+                ))}
+              </ul>
+              <p className="whitespace-pre-wrap">{request.reason}</p>
+              {request.photo_urls && request.photo_urls.length > 0 && (
+                <div className="flex flex-wrap gap-2">
+                  {request.photo_urls.map((url) => (
+                    <a key={url} href={url} target="_blank" rel="noopener noreferrer">
+                      <img src={url} alt="" className="h-14 w-14 rounded-md border object-cover" />
+                    </a>
+                  ))}
+                </div>
+              )}
+              {request.status === 'approved' && request.return_tracking_no && (
+                <div className="rounded-md bg-blue-500/10 p-2 text-blue-800 dark:text-blue-300">
+                  <div className="font-medium">
+                    {t.returnRequest.returnTrackingNo}:{' '}
+                    <span className="font-mono">{request.return_tracking_no}</span>
+                  </div>
+                  <div className="text-xs">{t.returnRequest.shipBackHint}</div>
+                </div>
+              )}
+              {request.admin_remark && (
+                <p className="whitespace-pre-wrap text-muted-foreground">
+                  {t.returnRequest.adminRemark}: {request.admin_remark}
+                </p>
+              )}
+              {request.status === 'pending' && (
+                <div className="flex justify-end">
+                  <Button
+                    size="sm"
+                    variant="ghost"
+                    disabled={cancelMutation.isPending}
+                    onClick={() => cancelMutation.mutate(request.id)}
+                  >
+                    {t.returnRequest.withdraw}
+                  </Button>
+                </div>
+              )}
+            </div>
+          )
+        })}
+      </CardContent>
+
+      <ReturnRequestDialog
+        open={dialogOpen}
+        onOpenChange={setDialogOpen}
+        orderNo={orderNo}
+        items={returnableItems}
+        onSubmitted={refresh}
+      />
+    </Card>
+  )
+}
@@ -15,6 +15,7 @@ import { Alert, AlertDescription, AlertTitle } from '@/components/ui/alert'
 import { Button } from '@/components/ui/button'
 import { Badge } from '@/components/ui/badge'
 import { SandboxedHtmlFrame } from '@/components/ui/sandboxed-html-frame'
+import { StripePaymentForm } from '@/components/orders/stripe-payment-form'
 import { resolvePaymentMethodIcon } from '@/lib/payment-method-icons'
 import { CreditCard, Check, Loader2, ChevronDown, ChevronUp, AlertTriangle } from 'lucide-react'
 import { useLocale } from '@/hooks/use-locale'
@@ -87,7 +88,7 @@ export function PaymentMethodCard({
 
   // 获取可用付款方式列表（用于更换时）
   const { data: methodsData, isLoading: methodsLoading } = useQuery({
-    ...getUserPaymentMethodsQueryOptions(),
+    ...getUserPaymentMethodsQueryOptions(orderNo),
     enabled: isChanging,
   })
 
@@ -252,6 +253,20 @@ export function PaymentMethodCard({
                 </Alert>
               )}
 
+              {paymentCard?.data?.provider === 'stripe' &&
+              paymentCard.data.client_secret &&
+              paymentCard.data.publishable_key &&
+              paymentCard.data.status !== 'succeeded' ? (
+                <StripePaymentForm
+                  publishableKey={paymentCard.data.publishable_key}
+                  clientSecret={paymentCard.data.client_secret}
+                  onSubmitted={() => {
+                    onPaymentSelected?.()
+                    refetch()
+                  }}
+                />
+              ) : null}
+
               <div className="flex flex-wrap gap-2">
                 <Button
                   variant="outline"
@@ -0,0 +1,166 @@
+'use client'
+
+import { useEffect, useState } from 'react'
+import { useMutation } from '@tanstack/react-query'
+import { useRouter } from 'next/navigation'
+import { FileText, Loader2 } from 'lucide-react'
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Textarea } from '@/components/ui/textarea'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { createQuoteRequest } from '@/lib/api'
+
+interface QuoteRequestDialogProps {
+  open: boolean
+  onOpenChange: (open: boolean) => void
+  productId: number
+  productName: string
+  quantity: number
+  attributes: Record<string, string>
+  defaultContactName?: string
+  defaultContactEmail?: string
+}
+
+// QuoteRequestDialog 询价模式商品提交询价单，提交后跳转到我的询价单
+export function QuoteRequestDialog({
+  open,
+  onOpenChange,
+  productId,
+  productName,
+  quantity,
+  attributes,
+  defaultContactName,
+  defaultContactEmail,
+}: QuoteRequestDialogProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const router = useRouter()
+  const [form, setForm] = useState({
+    message: '',
+    company: '',
+    contact_name: '',
+    contact_email: '',
+    contact_phone: '',
+  })
+
+  useEffect(() => {
+    if (!open) return
+    setForm((prev) => ({
+      ...prev,
+      contact_name: prev.contact_name || defaultContactName || '',
+      contact_email: prev.contact_email || defaultContactEmail || '',
+    }))
+  }, [open, defaultContactName, defaultContactEmail])
+
+  const submitMutation = useMutation({
+    mutationFn: () =>
+      createQuoteRequest({
+        product_id: productId,
+        quantity,
+        attributes,
+        ...form,
+      }),
+    onSuccess: () => {
+      toast.success(t.quoteRequest.submitted)
+      onOpenChange(false)
+      setForm({ message: '', company: '', contact_name: '', contact_email: '', contact_phone: '' })
+      router.push('/quotes')
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.quoteRequest.submitFailed))
+    },
+  })
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-w-lg">
+        <DialogHeader>
+          <DialogTitle className="flex items-center gap-2">
+            <FileText className="h-5 w-5" />
+            {t.quoteRequest.requestQuote}
+          </DialogTitle>
+          <DialogDescription>
+            {t.quoteRequest.requestQuoteDesc
+              .replace('{product}', productName)
+              .replace('{quantity}', String(quantity))}
+          </DialogDescription>
+        </DialogHeader>
+
+        <div className="space-y-3">
+          <div className="space-y-1.5">
+            <Label htmlFor="quote_message">{t.quoteRequest.message}</Label>
+            <Textarea
+              id="quote_message"
+              value={form.message}
+              maxLength={2000}
+              placeholder={t.quoteRequest.messagePlaceholder}
+              onChange={(e) => setForm({ ...form, message: e.target.value })}
+            />
+          </div>
+          <div className="grid grid-cols-1 gap-3 sm:grid-cols-2">
+            <div className="space-y-1.5">
+              <Label htmlFor="quote_company">{t.quoteRequest.company}</Label>
+              <Input
+                id="quote_company"
+                value={form.company}
+                maxLength={200}
+                onChange={(e) => setForm({ ...form, company: e.target.value })}
+              />
+            </div>
+            <div className="space-y-1.5">
+              <Label htmlFor="quote_contact_name">{t.quoteRequest.contactName}</Label>
+              <Input
+                id="quote_contact_name"
+                value={form.contact_name}
+                maxLength={100}
+                onChange={(e) => setForm({ ...form, contact_name: e.target.value })}
+              />
+            </div>
+            <div className="space-y-1.5">
+              <Label htmlFor="quote_contact_email">{t.quoteRequest.contactEmail}</Label>
+              <Input
+                id="quote_contact_email"
+                type="email"
+                value={form.contact_email}
+                maxLength={255}
+                onChange={(e) => setForm({ ...form, contact_email: e.target.value })}
+              />
+            </div>
+            <div className="space-y-1.5">
+              <Label htmlFor="quote_contact_phone">{t.quoteRequest.contactPhone}</Label>
+              <Input
+                id="quote_contact_phone"
+                value={form.contact_phone}
+                maxLength={50}
+                onChange={(e) => setForm({ ...form, contact_phone: e.target.value })}
+              />
+            </div>
+          </div>
+        </div>
+
+        <DialogFooter>
+          <Button variant="outline" onClick={() => onOpenChange(false)}>
+            {t.common.cancel}
+          </Button>
+          <Button onClick={() => submitMutation.mutate()} disabled={submitMutation.isPending}>
+            {submitMutation.isPending && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+            {t.quoteRequest.submit}
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  )
+}
@@ -0,0 +1,30 @@
+import type { QuoteRequestStatus } from '@/lib/api'
+import type { Translations } from '@/lib/i18n'
+
+// getQuoteStatusConfig 询价单状态的文案与徽章颜色，用户端与管理端共用
+export function getQuoteStatusConfig(
+  t: Translations
+): Record<QuoteRequestStatus, { label: string; color: string }> {
+  return {
+    pending: {
+      label: t.quoteRequest.statusPending,
+      color: 'bg-yellow-500/20 text-yellow-700 dark:text-yellow-400',
+    },
+    quoted: {
+      label: t.quoteRequest.statusQuoted,
+      color: 'bg-blue-500/20 text-blue-700 dark:text-blue-400',
+    },
+    accepted: {
+      label: t.quoteRequest.statusAccepted,
+      color: 'bg-green-500/20 text-green-700 dark:text-green-400',
+    },
+    declined: {
+      label: t.quoteRequest.statusDeclined,
+      color: 'bg-red-500/20 text-red-700 dark:text-red-400',
+    },
+    closed: {
+      label: t.quoteRequest.statusClosed,
+      color: 'bg-gray-500/20 text-gray-700 dark:text-gray-400',
+    },
+  }
+}
@@ -0,0 +1,225 @@
+'use client'
+
+import { useEffect, useRef, useState } from 'react'
+import { useMutation } from '@tanstack/react-query'
+import { ImagePlus, Loader2, PackageOpen, X } from 'lucide-react'
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Label } from '@/components/ui/label'
+import { Textarea } from '@/components/ui/textarea'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import { createReturnRequest, uploadReturnPhoto } from '@/lib/api'
+
+const MAX_RETURN_PHOTOS = 6
+
+export interface ReturnableItem {
+  index: number
+  name: string
+  sku: string
+  remaining: number
+}
+
+interface ReturnRequestDialogProps {
+  open: boolean
+  onOpenChange: (open: boolean) => void
+  orderNo: string
+  items: ReturnableItem[]
+  onSubmitted: () => void
+}
+
+// ReturnRequestDialog 选择退货商品与数量、填写原因并附照片，提交退货申请
+export function ReturnRequestDialog({
+  open,
+  onOpenChange,
+  orderNo,
+  items,
+  onSubmitted,
+}: ReturnRequestDialogProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const fileInputRef = useRef<HTMLInputElement>(null)
+  const [quantities, setQuantities] = useState<Record<number, number>>({})
+  const [reason, setReason] = useState('')
+  const [photos, setPhotos] = useState<{ path: string; preview_url: string }[]>([])
+  const [uploading, setUploading] = useState(false)
+
+  useEffect(() => {
+    if (!open) return
+    setQuantities({})
+    setReason('')
+    setPhotos([])
+  }, [open])
+
+  const selectedItems = items
+    .filter((item) => (quantities[item.index] || 0) > 0)
+    .map((item) => ({ item_index: item.index, quantity: quantities[item.index] }))
+
+  const handleUpload = async (files: FileList | null) => {
+    if (!files || files.length === 0) return
+    setUploading(true)
+    try {
+      const uploaded: { path: string; preview_url: string }[] = []
+      for (const file of Array.from(files).slice(0, MAX_RETURN_PHOTOS - photos.length)) {
+        const res: any = await uploadReturnPhoto(orderNo, file)
+        if (res?.data?.path) uploaded.push(res.data)
+      }
+      setPhotos((prev) => [...prev, ...uploaded])
+    } catch (error: unknown) {
+      toast.error(resolveApiErrorMessage(error, t, t.returnRequest.photoUploadFailed))
+    } finally {
+      setUploading(false)
+      if (fileInputRef.current) fileInputRef.current.value = ''
+    }
+  }
+
+  const submitMutation = useMutation({
+    mutationFn: () =>
+      createReturnRequest(orderNo, {
+        items: selectedItems,
+        reason,
+        photos: photos.map((photo) => photo.path),
+      }),
+    onSuccess: () => {
+      toast.success(t.returnRequest.submitted)
+      onOpenChange(false)
+      onSubmitted()
+    },
+    onError: (error: unknown) => {
+      toast.error(resolveApiErrorMessage(error, t, t.returnRequest.submitFailed))
+    },
+  })
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-w-lg">
+        <DialogHeader>
+          <DialogTitle className="flex items-center gap-2">
+            <PackageOpen className="h-5 w-5" />
+            {t.returnRequest.requestReturn}
+          </DialogTitle>
+          <DialogDescription>{t.returnRequest.requestReturnDesc}</DialogDescription>
+        </DialogHeader>
+
+        <div className="space-y-4">
+          <div className="space-y-2">
+            <Label>{t.returnRequest.items}</Label>
+            {items.map((item) => (
+              <div
+                key={item.index}
+                className="flex items-center justify-between gap-3 rounded-md border p-2"
+              >
+                <div className="min-w-0 text-sm">
+                  <div className="truncate font-medium">{item.name}</div>
+                  <div className="text-xs text-muted-foreground">
+                    {item.sku} ·{' '}
+                    {t.returnRequest.returnableQuantity.replace('{count}', String(item.remaining))}
+                  </div>
+                </div>
+                <Input
+                  type="number"
+                  min={0}
+                  max={item.remaining}
+                  value={quantities[item.index] || 0}
+                  onChange={(e) => {
+                    const value = Math.max(0, Math.min(item.remaining, Number(e.target.value) || 0))
+                    setQuantities((prev) => ({ ...prev, [item.index]: value }))
+                  }}
+                  className="w-20"
+                  aria-label={t.returnRequest.quantity}
+                />
+              </div>
+            ))}
+          </div>
+
+          <div className="space-y-1.5">
+            <Label htmlFor="return_reason">{t.returnRequest.reason}</Label>
+            <Textarea
+              id="return_reason"
+              value={reason}
+              maxLength={1000}
+              placeholder={t.returnRequest.reasonPlaceholder}
+              onChange={(e) => setReason(e.target.value)}
+            />
+          </div>
+
+          <div className="space-y-1.5">
+            <Label>{t.returnRequest.photos}</Label>
+            <div className="flex flex-wrap gap-2">
+              {photos.map((photo) => (
+                <div
+                  key={photo.path}
+                  className="relative h-16 w-16 overflow-hidden rounded-md border"
+                >
+                  <img src={photo.preview_url} alt="" className="h-full w-full object-cover" />
+                  <button
+                    type="button"
+                    className="absolute right-0.5 top-0.5 rounded-full bg-background/80 p-0.5"
+                    onClick={() => setPhotos((prev) => prev.filter((p) => p.path !== photo.path))}
+                    aria-label={t.common.delete}
+                  >
+                    <X className="h-3 w-3" />
+                  </button>
+                </div>
+              ))}
+              {photos.length < MAX_RETURN_PHOTOS && (
+                <Button
+                  type="button"
+                  variant="outline"
+                  className="h-16 w-16"
+                  disabled={uploading}
+                  onClick={() => fileInputRef.current?.click()}
+                  aria-label={t.returnRequest.addPhoto}
+                  title={t.returnRequest.addPhoto}
+                >
+                  {uploading ? (
+                    <Loader2 className="h-5 w-5 animate-spin" />
+                  ) : (
+                    <ImagePlus className="h-5 w-5" />
+                  )}
+                </Button>
+              )}
+            </div>
+            <input
+              ref={fileInputRef}
+              type="file"
+              accept="image/jpeg,image/png,image/gif,image/webp"
+              multiple
+              className="hidden"
+              onChange={(e) => handleUpload(e.target.files)}
+            />
+            <p className="text-xs text-muted-foreground">
+              {t.returnRequest.photosHint.replace('{max}', String(MAX_RETURN_PHOTOS))}
+            </p>
+          </div>
+        </div>
+
+        <DialogFooter>
+          <Button variant="outline" onClick={() => onOpenChange(false)}>
+            {t.common.cancel}
+          </Button>
+          <Button
+            onClick={() => submitMutation.mutate()}
+            disabled={
+              submitMutation.isPending || uploading || selectedItems.length === 0 || !reason.trim()
+            }
+          >
+            {submitMutation.isPending && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+            {t.returnRequest.submit}
+          </Button>
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  )
+}
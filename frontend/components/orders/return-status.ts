@@ -0,0 +1,30 @@
+import type { ReturnRequestStatus } from '@/lib/api'
+import type { Translations } from '@/lib/i18n'
+
+// getReturnStatusConfig 退货申请状态的文案与徽章颜色，用户端与管理端共用
+export function getReturnStatusConfig(
+  t: Translations
+): Record<ReturnRequestStatus, { label: string; color: string }> {
+  return {
+    pending: {
+      label: t.returnRequest.statusPending,
+      color: 'bg-yellow-500/20 text-yellow-700 dark:text-yellow-400',
+    },
+    approved: {
+      label: t.returnRequest.statusApproved,
+      color: 'bg-blue-500/20 text-blue-700 dark:text-blue-400',
+    },
+    rejected: {
+      label: t.returnRequest.statusRejected,
+      color: 'bg-red-500/20 text-red-700 dark:text-red-400',
+    },
+    received: {
+      label: t.returnRequest.statusReceived,
+      color: 'bg-green-500/20 text-green-700 dark:text-green-400',
+    },
+    cancelled: {
+      label: t.returnRequest.statusCancelled,
+      color: 'bg-gray-500/20 text-gray-700 dark:text-gray-400',
+    },
+  }
+}
@@ -0,0 +1,94 @@
+'use client'
+
+import type { ReactNode } from 'react'
+import { Truck } from 'lucide-react'
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card'
+import { Badge } from '@/components/ui/badge'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+import { cn, formatDate } from '@/lib/utils'
+import type { ShipmentTracking, ShipmentTrackingStatus } from '@/types/order'
+
+interface ShipmentTrackingCardProps {
+  tracking: ShipmentTracking
+  action?: ReactNode
+  children?: ReactNode
+}
+
+const statusVariants: Record<ShipmentTrackingStatus, 'default' | 'secondary' | 'destructive'> = {
+  pending: 'secondary',
+  in_transit: 'secondary',
+  out_for_delivery: 'default',
+  delivered: 'default',
+  exception: 'destructive',
+}
+
+// ShipmentTrackingCard 物流轨迹（用户与管理端共用）
+export function ShipmentTrackingCard({ tracking, action, children }: ShipmentTrackingCardProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const statusLabels: Record<ShipmentTrackingStatus, string> = {
+    pending: t.order.shipmentTrackingStatusPending,
+    in_transit: t.order.shipmentTrackingStatusInTransit,
+    out_for_delivery: t.order.shipmentTrackingStatusOutForDelivery,
+    delivered: t.order.shipmentTrackingStatusDelivered,
+    exception: t.order.shipmentTrackingStatusException,
+  }
+  const events = Array.isArray(tracking.events) ? tracking.events : []
+
+  return (
+    <Card>
+      <CardHeader className="flex flex-row items-center justify-between space-y-0">
+        <CardTitle className="flex items-center gap-2">
+          <Truck className="h-5 w-5" />
+          {t.order.shipmentTracking}
+          <Badge variant={statusVariants[tracking.status] || 'secondary'}>
+            {statusLabels[tracking.status] || tracking.status}
+          </Badge>
+        </CardTitle>
+        {action}
+      </CardHeader>
+      <CardContent className="space-y-3 text-sm">
+        <div className="flex flex-wrap gap-x-6 gap-y-1 text-muted-foreground">
+          <span>
+            {t.order.trackingNo}: <span className="font-mono">{tracking.tracking_no}</span>
+          </span>
+          {tracking.carrier && (
+            <span>
+              {t.order.shipmentTrackingCarrier}: {tracking.carrier}
+            </span>
+          )}
+          {tracking.delivered_at && (
+            <span>
+              {t.order.shipmentTrackingDeliveredAt}: {formatDate(tracking.delivered_at)}
+            </span>
+          )}
+        </div>
+        {children}
+        {events.length > 0 ? (
+          <ol className="space-y-3 border-l pl-4">
+            {events.map((event, index) => (
+              <li key={`${event.time}-${index}`} className="relative">
+                <span
+                  className={cn(
+                    'absolute -left-[21px] top-1.5 h-2 w-2 rounded-full',
+                    index === 0 ? 'bg-primary' : 'bg-muted-foreground/40'
+                  )}
+                />
+                <p className={cn(index === 0 ? 'font-medium' : 'text-muted-foreground')}>
+                  {event.description}
+                </p>
+                <p className="text-xs text-muted-foreground">
+                  {formatDate(event.time)}
+                  {event.location ? ` · ${event.location}` : ''}
+                </p>
+              </li>
+            ))}
+          </ol>
+        ) : (
+          <p className="text-muted-foreground">{t.order.shipmentTrackingNoEvents}</p>
+        )}
+      </CardContent>
+    </Card>
+  )
+}
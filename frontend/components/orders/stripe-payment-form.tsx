@@ -0,0 +1,113 @@
+'use client'
+
+import { useEffect, useRef, useState } from 'react'
+import { Button } from '@/components/ui/button'
+import { Loader2 } from 'lucide-react'
+import { useLocale } from '@/hooks/use-locale'
+import { getTranslations } from '@/lib/i18n'
+import toast from 'react-hot-toast'
+
+const STRIPE_JS_URL = 'https://js.stripe.com/v3'
+
+let stripeScriptPromise: Promise<any> | null = null
+
+// 只加载一次 Stripe.js，失败后允许下次重试
+function loadStripeJs(): Promise<any> {
+  if (typeof window === 'undefined') {
+    return Promise.reject(new Error('Stripe.js requires a browser'))
+  }
+  if ((window as any).Stripe) {
+    return Promise.resolve((window as any).Stripe)
+  }
+  if (!stripeScriptPromise) {
+    stripeScriptPromise = new Promise((resolve, reject) => {
+      const script = document.createElement('script')
+      script.src = STRIPE_JS_URL
+      script.async = true
+      script.onload = () => resolve((window as any).Stripe)
+      script.onerror = () => {
+        stripeScriptPromise = null
+        reject(new Error('Failed to load Stripe.js'))
+      }
+      document.head.appendChild(script)
+    })
+  }
+  return stripeScriptPromise
+}
+
+interface StripePaymentFormProps {
+  publishableKey: string
+  clientSecret: string
+  onSubmitted?: () => void
+}
+
+export function StripePaymentForm({ publishableKey, clientSecret, onSubmitted }: StripePaymentFormProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const containerRef = useRef<HTMLDivElement>(null)
+  const stripeRef = useRef<any>(null)
+  const elementsRef = useRef<any>(null)
+  const [ready, setReady] = useState(false)
+  const [loadFailed, setLoadFailed] = useState(false)
+  const [submitting, setSubmitting] = useState(false)
+
+  useEffect(() => {
+    let cancelled = false
+    let paymentElement: any = null
+    setReady(false)
+    setLoadFailed(false)
+    loadStripeJs()
+      .then((StripeCtor) => {
+        if (cancelled || !containerRef.current) return
+        const stripe = StripeCtor(publishableKey, { locale: locale.startsWith('zh') ? 'zh' : 'en' })
+        const elements = stripe.elements({ clientSecret })
+        paymentElement = elements.create('payment')
+        paymentElement.mount(containerRef.current)
+        paymentElement.on('ready', () => !cancelled && setReady(true))
+        stripeRef.current = stripe
+        elementsRef.current = elements
+      })
+      .catch(() => {
+        if (!cancelled) setLoadFailed(true)
+      })
+    return () => {
+      cancelled = true
+      paymentElement?.destroy()
+    }
+  }, [publishableKey, clientSecret, locale])
+
+  const handleSubmit = async () => {
+    if (!stripeRef.current || !elementsRef.current) return
+    setSubmitting(true)
+    try {
+      // 需要 3DS 等跳转时 Stripe 会带回当前页面；最终以服务端 webhook 确认付款
+      const { error } = await stripeRef.current.confirmPayment({
+        elements: elementsRef.current,
+        confirmParams: { return_url: window.location.href },
+        redirect: 'if_required',
+      })
+      if (error) {
+        toast.error(error.message || t.order.stripePaymentFailed)
+        return
+      }
+      toast.success(t.order.stripePaymentSubmitted)
+      onSubmitted?.()
+    } finally {
+      setSubmitting(false)
+    }
+  }
+
+  if (loadFailed) {
+    return <p className="text-sm text-destructive">{t.order.stripeLoadFailed}</p>
+  }
+
+  return (
+    <div className="space-y-3">
+      <div ref={containerRef} />
+      <Button className="w-full" onClick={handleSubmit} disabled={!ready || submitting}>
+        {submitting && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+        {t.order.stripePayNow}
+      </Button>
+    </div>
+  )
+}
@@ -0,0 +1,172 @@
+'use client'
+
+import { useEffect, useRef, useState } from 'react'
+import { Loader2, Users } from 'lucide-react'
+import { Button } from '@/components/ui/button'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogFooter,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import { useLocale } from '@/hooks/use-locale'
+import { useToast } from '@/hooks/use-toast'
+import { getTranslations } from '@/lib/i18n'
+import { resolveApiErrorMessage } from '@/lib/api-error'
+import {
+  extractApiErrorInfo,
+  getWaitingRoomStatus,
+  joinWaitingRoom,
+  type WaitingRoomStatus,
+} from '@/lib/api'
+
+const WAITING_ROOM_TOKEN_STORAGE_KEY = 'auralogic_waiting_room_token'
+
+// getStoredWaitingRoomToken 读取本会话中尚未过期的放行凭证
+export function getStoredWaitingRoomToken(): string | undefined {
+  if (typeof window === 'undefined') return undefined
+  try {
+    const raw = window.sessionStorage.getItem(WAITING_ROOM_TOKEN_STORAGE_KEY)
+    if (!raw) return undefined
+    const stored = JSON.parse(raw) as { token?: string; expires_at?: string }
+    if (!stored.token || !stored.expires_at || new Date(stored.expires_at) <= new Date()) {
+      window.sessionStorage.removeItem(WAITING_ROOM_TOKEN_STORAGE_KEY)
+      return undefined
+    }
+    return stored.token
+  } catch {
+    return undefined
+  }
+}
+
+function storeWaitingRoomToken(token: string, expiresAt?: string) {
+  if (typeof window === 'undefined' || !expiresAt) return
+  window.sessionStorage.setItem(
+    WAITING_ROOM_TOKEN_STORAGE_KEY,
+    JSON.stringify({ token, expires_at: expiresAt })
+  )
+}
+
+// isWaitingRoomError 下单被等候室拦截（未排队或放行已过期）
+export function isWaitingRoomError(error: unknown): boolean {
+  const key = extractApiErrorInfo(error).errorKey || ''
+  if (key === 'waiting_room.tokenInvalid' && typeof window !== 'undefined') {
+    window.sessionStorage.removeItem(WAITING_ROOM_TOKEN_STORAGE_KEY)
+  }
+  return key === 'waiting_room.admissionRequired' || key === 'waiting_room.tokenInvalid'
+}
+
+interface WaitingRoomDialogProps {
+  open: boolean
+  onOpenChange: (open: boolean) => void
+  onAdmitted: (token: string) => void
+}
+
+// WaitingRoomDialog 加入抢购等候室并轮询排队位置，放行后回调放行凭证
+export function WaitingRoomDialog({ open, onOpenChange, onAdmitted }: WaitingRoomDialogProps) {
+  const { locale } = useLocale()
+  const t = getTranslations(locale)
+  const toast = useToast()
+  const [status, setStatus] = useState<WaitingRoomStatus | null>(null)
+  const [attempt, setAttempt] = useState(0)
+  const onAdmittedRef = useRef(onAdmitted)
+  onAdmittedRef.current = onAdmitted
+
+  useEffect(() => {
+    if (!open) {
+      setStatus(null)
+      return
+    }
+    let cancelled = false
+    let timer: ReturnType<typeof setTimeout> | undefined
+
+    const handle = (next: WaitingRoomStatus) => {
+      if (cancelled) return
+      setStatus(next)
+      if (next.status === 'admitted' && next.admission_token) {
+        storeWaitingRoomToken(next.admission_token, next.admitted_until)
+        onAdmittedRef.current(next.admission_token)
+        return
+      }
+      if (next.status === 'waiting') {
+        timer = setTimeout(poll, Math.max(next.poll_after_seconds || 3, 1) * 1000)
+      }
+    }
+    const fail = (error: unknown) => {
+      if (cancelled) return
+      toast.error(resolveApiErrorMessage(error, t, t.cart.orderFailed))
+      onOpenChange(false)
+    }
+    const poll = () => {
+      getWaitingRoomStatus()
+        .then((response: any) => handle(response.data))
+        .catch(fail)
+    }
+
+    joinWaitingRoom()
+      .then((response: any) => handle(response.data))
+      .catch(fail)
+
+    return () => {
+      cancelled = true
+      if (timer) clearTimeout(timer)
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [open, attempt])
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-w-sm">
+        <DialogHeader>
+          <DialogTitle className="flex items-center gap-2">
+            <Users className="h-5 w-5" />
+            {t.cart.waitingRoomTitle}
+          </DialogTitle>
+          <DialogDescription>{t.cart.waitingRoomDesc}</DialogDescription>
+        </DialogHeader>
+
+        <div className="flex flex-col items-center gap-2 py-4 text-center text-sm">
+          {status?.status === 'none' ? (
+            <p className="text-muted-foreground">{t.cart.waitingRoomDropped}</p>
+          ) : (
+            <>
+              <Loader2 className="h-6 w-6 animate-spin text-muted-foreground" />
+              {!status && <p>{t.cart.waitingRoomJoining}</p>}
+              {status?.status === 'waiting' && (
+                <>
+                  <p className="font-medium">
+                    {t.cart.waitingRoomPosition
+                      .replace('{position}', String(status.position || 0))
+                      .replace('{total}', String(status.queue_length))}
+                  </p>
+                  {!!status.estimated_wait_seconds && (
+                    <p className="text-muted-foreground">
+                      {t.cart.waitingRoomEstimatedWait.replace(
+                        '{seconds}',
+                        String(status.estimated_wait_seconds)
+                      )}
+                    </p>
+                  )}
+                </>
+              )}
+              {status?.status === 'admitted' && <p>{t.cart.waitingRoomAdmitted}</p>}
+            </>
+          )}
+        </div>
+
+        <DialogFooter>
+          <Button variant="outline" onClick={() => onOpenChange(false)}>
+            {t.cart.waitingRoomLeave}
+          </Button>
+          {status?.status === 'none' && (
+            <Button onClick={() => setAttempt((value) => value + 1)}>
+              {t.cart.waitingRoomRejoin}
+            </Button>
+          )}
+        </DialogFooter>
+      </DialogContent>
+    </Dialog>
+  )
+}
@@ -1,5 +1,14 @@
 import axios, { AxiosInstance } from 'axios'
-import { getToken, clearToken } from './auth'
+import {
+  AUTH_MODE_HEADER,
+  clearCookieSession,
+  clearToken,
+  getToken,
+  isCookieSession,
+  setCookieSession,
+  setCSRFToken,
+  setSessionCookieConfig,
+} from './auth'
 import {
   getClientAPIProxyBaseURL,
   getConfiguredPublicAPIBaseURL,
@@ -7,6 +16,23 @@ import {
   resolvePublicAPIURL,
 } from './api-base-url'
 import { stringifyPluginHostContext } from './plugin-frontend-routing'
+import {
+  CATALOG_CHALLENGE_ERROR_KEY,
+  CATALOG_TOKEN_HEADER,
+  getCatalogToken,
+  isCatalogRequest,
+  refreshCatalogToken,
+} from './catalog-challenge'
+import {
+  COOKIE_AUTH_MODE,
+  CSRF_INVALID_ERROR_KEY,
+  CSRF_TOKEN_PATH,
+  applyCookieSessionHeaders,
+  ensureCSRFToken,
+  isUnsafeMethod,
+  readIssuedCSRFToken,
+  shouldUseCookieSession,
+} from './cookie-session'
 
 const PROXY_API_BASE_URL =
   typeof window === 'undefined' ? getConfiguredPublicAPIBaseURL() : getClientAPIProxyBaseURL()
@@ -249,11 +275,23 @@ function createAPIClient(baseURL: string, options?: APIClientOptions): AxiosInst
   })
 
   client.interceptors.request.use(
-    (config) => {
+    async (config) => {
       const token = getToken()
       if (token) {
         config.headers.Authorization = `Bearer ${token}`
       }
+      if (shouldUseCookieSession(config.url)) {
+        config.headers[AUTH_MODE_HEADER] = COOKIE_AUTH_MODE
+        config.withCredentials = true
+        if (isCookieSession() && isUnsafeMethod(config.method)) {
+          const csrf = await ensureCSRFToken(() =>
+            client.get(CSRF_TOKEN_PATH).then((res: any) => res.data)
+          )
+          if (csrf) {
+            config.headers[csrf.headerName] = csrf.token
+          }
+        }
+      }
       const locale = resolveClientLocaleHeaderValue()
       if (locale) {
         config.headers[APP_LOCALE_HEADER] = locale
@@ -262,6 +300,10 @@ function createAPIClient(baseURL: string, options?: APIClientOptions): AxiosInst
       if (sessionID) {
         config.headers[APP_SESSION_HEADER] = sessionID
       }
+      const catalogToken = isCatalogRequest(config.url) ? getCatalogToken() : undefined
+      if (catalogToken) {
+        config.headers[CATALOG_TOKEN_HEADER] = catalogToken
+      }
       return config
     },
     (error) => {
@@ -271,6 +313,13 @@ function createAPIClient(baseURL: string, options?: APIClientOptions): AxiosInst
 
   client.interceptors.response.use(
     (response) => {
+      // 登录类响应：Cookie 会话记录 CSRF 令牌，Bearer 响应退出 Cookie 会话
+      const issuedCSRFToken = readIssuedCSRFToken(response.config?.url, response.data)
+      if (issuedCSRFToken !== undefined) {
+        setCookieSession(issuedCSRFToken)
+      } else if (response.data?.data?.token_type === 'Bearer') {
+        clearCookieSession()
+      }
       return response.data
     },
     (error) => {
@@ -281,6 +330,44 @@ function createAPIClient(baseURL: string, options?: APIClientOptions): AxiosInst
       }
 
       const parsed = parseApiErrorPayload(error.response?.data)
+      // CSRF 令牌失效（如 Cookie 被重新签发）时重新获取后重试一次
+      if (
+        parsed.errorKey === CSRF_INVALID_ERROR_KEY &&
+        error.config &&
+        !error.config._csrfRetried &&
+        isCookieSession()
+      ) {
+        error.config._csrfRetried = true
+        setCSRFToken()
+        return client.request(error.config)
+      }
+      // 目录防爬要求 JS 挑战时，解出令牌后重试一次
+      if (
+        parsed.errorKey === CATALOG_CHALLENGE_ERROR_KEY &&
+        error.config &&
+        !error.config._catalogRetried &&
+        typeof window !== 'undefined'
+      ) {
+        error.config._catalogRetried = true
+        return refreshCatalogToken(() =>
+          client.get('/api/user/products/challenge').then((res: any) => res.data)
+        ).then((token) => {
+          error.config.headers[CATALOG_TOKEN_HEADER] = token
+          return client.request(error.config)
+        })
+      }
+      // 会话因邮箱验证宽限期结束而失效时，引导用户前往验证页
+      if (
+        options?.clearTokenOnUnauthorized &&
+        parsed.errorKey === 'auth.emailNotVerified' &&
+        typeof window !== 'undefined' &&
+        !asString(error.config?.url).includes('/auth/') &&
+        !window.location.pathname.startsWith('/verify-email')
+      ) {
+        clearToken()
+        const email = asString(parsed.data?.email)
+        window.location.assign(`/verify-email?email=${encodeURIComponent(email)}&pending=true`)
+      }
       const fallback = asString(error?.message) || parsed.message || 'Request failed'
       const message = parsed.message || fallback
       const apiError: any = new Error(message)
@@ -306,6 +393,13 @@ export const apiClient: AxiosInstance = createAPIClient(PROXY_API_BASE_URL, {
 // 公开接口同样走 Next 代理，保留可选鉴权、会话转发与统一语义。
 export const publicApiClient: AxiosInstance = createAPIClient(PROXY_API_BASE_URL)
 
+// Cookie 会话下直接 fetch 的请求同样声明会话模式，写请求附带 CSRF 令牌
+export function applyFetchSessionHeaders(headers: Headers, method: string) {
+  return applyCookieSessionHeaders(headers, method, () =>
+    apiClient.get(CSRF_TOKEN_PATH).then((res: any) => res.data)
+  )
+}
+
 // ==========================================
 // 库存管理API
 // ==========================================
@@ -515,8 +609,14 @@ export async function submitShippingForm(data: ShippingFormData) {
 }
 
 // 获取国家列表
-export async function getCountries() {
-  return publicApiClient.get('/api/form/countries')
+export async function getCountries(includeDisabled = false) {
+  return publicApiClient.get('/api/form/countries', {
+    params: includeDisabled ? { include_disabled: 1 } : undefined,
+  })
+}
+
+export async function getCountryRegions(code: string) {
+  return publicApiClient.get(`/api/form/countries/${encodeURIComponent(code)}/regions`)
 }
 
 // ==========================================
@@ -638,6 +738,7 @@ export async function changePassword(oldPassword: string, newPassword: string) {
 export async function updateUserPreferences(data: {
   locale?: string
   country?: string
+  display_currency?: string
   email_notify_order?: boolean
   email_notify_ticket?: boolean
   email_notify_marketing?: boolean
@@ -654,6 +755,10 @@ export async function bindEmail(email: string, code: string) {
   return apiClient.post('/api/user/auth/bind-email', { email, code })
 }
 
+export async function revertEmailChange(token: string) {
+  return publicApiClient.post('/api/user/auth/revert-email-change', { token })
+}
+
 export async function sendBindPhoneCode(
   phone: string,
   phone_code?: string,
@@ -680,6 +785,7 @@ export interface OrderQueryParams {
   status?: string
   search?: string
   product_search?: string // 新增：按商品SKU/名称搜索
+  tag?: string // 标签筛选（仅管理员），逗号分隔需同时包含
   promo_code_id?: number
   promo_code?: string
   user_id?: number
@@ -702,23 +808,84 @@ export async function getOrder(orderNo: string) {
   return apiClient.get(`/api/user/orders/${orderNo}`)
 }
 
-export async function createOrder(data: { items: any[]; promo_code?: string }) {
+export async function createOrder(data: {
+  items: any[]
+  promo_code?: string
+  waiting_room_token?: string
+}) {
   return apiClient.post('/api/user/orders', data)
 }
 
+export interface WaitingRoomStatus {
+  status: 'waiting' | 'admitted' | 'none'
+  position?: number
+  queue_length: number
+  estimated_wait_seconds?: number
+  poll_after_seconds: number
+  admission_token?: string
+  admitted_until?: string
+}
+
+export async function joinWaitingRoom() {
+  return apiClient.post('/api/user/waiting-room/join')
+}
+
+export async function getWaitingRoomStatus() {
+  return apiClient.get('/api/user/waiting-room/status')
+}
+
+export async function getWaitingRoomMetrics() {
+  return apiClient.get('/api/admin/waiting-room/metrics')
+}
+
+export async function claimOrder(orderNo: string) {
+  return apiClient.post('/api/user/orders/claim', { order_no: orderNo })
+}
+
 export async function getOrRefreshFormToken(orderNo: string) {
   return apiClient.get(`/api/user/orders/${orderNo}/form-token`)
 }
 
+export async function getOrderMessages(orderNo: string) {
+  return apiClient.get(`/api/user/orders/${orderNo}/messages`)
+}
+
+export async function sendOrderMessage(orderNo: string, content: string) {
+  return apiClient.post(`/api/user/orders/${orderNo}/messages`, { content })
+}
+
+export async function escalateOrderMessages(orderNo: string) {
+  return apiClient.post(`/api/user/orders/${orderNo}/messages/escalate`)
+}
+
 // Get virtual products for an order
 export async function getOrderVirtualProducts(orderNo: string) {
   return apiClient.get(`/api/user/orders/${orderNo}/virtual-products`)
 }
 
+// Record when the buyer first revealed a virtual product code
+export async function revealOrderVirtualProduct(orderNo: string, stockId: number) {
+  return apiClient.post(`/api/user/orders/${orderNo}/virtual-products/${stockId}/reveal`)
+}
+
+export async function resendOrderVirtualProductsEmail(orderNo: string) {
+  return apiClient.post(`/api/user/orders/${orderNo}/virtual-products/resend-email`)
+}
+
 export async function getInvoiceToken(orderNo: string) {
   return apiClient.get(`/api/user/orders/${orderNo}/invoice-token`)
 }
 
+// 通过邮件中的签名链接提取卡密（无需登录）
+export async function getVirtualDeliveryBySignedLink(
+  orderNo: string,
+  params: { expires: string; sig: string }
+) {
+  return publicApiClient.get(`/api/user/virtual-delivery/${encodeURIComponent(orderNo)}`, {
+    params,
+  })
+}
+
 // ==========================================
 // 商品API
 // ==========================================
@@ -782,6 +949,7 @@ export interface CartItem {
   attributes: Record<string, string>
   available_stock: number
   is_available: boolean
+  reserved_until?: string
   product?: any
 }
 
@@ -827,6 +995,206 @@ export async function clearCart() {
   return apiClient.delete('/api/user/cart')
 }
 
+// ==========================================
+// 询价单 API（询价模式商品）
+// ==========================================
+
+export type QuoteRequestStatus = 'pending' | 'quoted' | 'accepted' | 'declined' | 'closed'
+
+export interface QuoteRequest {
+  id: number
+  quote_no: string
+  user_id: number
+  user?: { id: number; email: string; name?: string }
+  product_id: number
+  product_sku: string
+  product_name: string
+  quantity: number
+  attributes?: Record<string, string>
+  message?: string
+  company?: string
+  contact_name?: string
+  contact_email?: string
+  contact_phone?: string
+  status: QuoteRequestStatus
+  // Minor units (e.g. cents)
+  quoted_unit_price_minor: number
+  currency?: string
+  admin_reply?: string
+  valid_until?: string
+  quoted_at?: string
+  responded_at?: string
+  created_at: string
+  updated_at: string
+}
+
+// 提交询价单
+export async function createQuoteRequest(data: {
+  product_id: number
+  quantity: number
+  attributes?: Record<string, string>
+  message?: string
+  company?: string
+  contact_name?: string
+  contact_email?: string
+  contact_phone?: string
+}) {
+  return apiClient.post('/api/user/quote-requests', data)
+}
+
+// 我的询价单
+export async function getQuoteRequests(params?: { page?: number; limit?: number }) {
+  const query = new URLSearchParams()
+  if (params?.page) query.append('page', params.page.toString())
+  if (params?.limit) query.append('limit', params.limit.toString())
+
+  return apiClient.get(`/api/user/quote-requests?${query}`)
+}
+
+// 接受报价
+export async function acceptQuoteRequest(id: number) {
+  return apiClient.post(`/api/user/quote-requests/${id}/accept`)
+}
+
+// 拒绝报价
+export async function declineQuoteRequest(id: number) {
+  return apiClient.post(`/api/user/quote-requests/${id}/decline`)
+}
+
+// 撤回询价单
+export async function cancelQuoteRequest(id: number) {
+  return apiClient.post(`/api/user/quote-requests/${id}/cancel`)
+}
+
+// 管理端 - 询价单列表
+export async function getAdminQuoteRequests(params?: {
+  page?: number
+  limit?: number
+  status?: string
+  search?: string
+}) {
+  const query = new URLSearchParams()
+  if (params?.page) query.append('page', params.page.toString())
+  if (params?.limit) query.append('limit', params.limit.toString())
+  if (params?.status) query.append('status', params.status)
+  if (params?.search) query.append('search', params.search)
+
+  return apiClient.get(`/api/admin/quote-requests?${query}`)
+}
+
+// 管理端 - 报价（unit_price_minor 为最小货币单位，valid_days 为 0 表示长期有效）
+export async function quoteAdminQuoteRequest(
+  id: number,
+  data: { unit_price_minor: number; valid_days?: number; reply?: string }
+) {
+  return apiClient.post(`/api/admin/quote-requests/${id}/quote`, data)
+}
+
+// 管理端 - 关闭询价单
+export async function closeAdminQuoteRequest(id: number, reply?: string) {
+  return apiClient.post(`/api/admin/quote-requests/${id}/close`, { reply: reply || '' })
+}
+
+// ==========================================
+// 退货申请 API（RMA）
+// ==========================================
+
+export type ReturnRequestStatus = 'pending' | 'approved' | 'rejected' | 'received' | 'cancelled'
+
+export interface ReturnRequestItem {
+  item_index: number
+  sku: string
+  name: string
+  quantity: number
+}
+
+export interface ReturnRequest {
+  id: number
+  rma_no: string
+  order_id: number
+  order_no: string
+  user_id: number
+  user?: { id: number; email: string; name?: string }
+  items: ReturnRequestItem[]
+  reason: string
+  photo_urls?: string[]
+  status: ReturnRequestStatus
+  admin_remark?: string
+  return_tracking_no?: string
+  reviewed_at?: string
+  received_at?: string
+  restocked_quantity: number
+  created_at: string
+  updated_at: string
+}
+
+// 订单的退货申请
+export async function getOrderReturnRequests(orderNo: string) {
+  return apiClient.get(`/api/user/orders/${orderNo}/returns`)
+}
+
+// 提交退货申请（photos 为上传接口返回的 path）
+export async function createReturnRequest(
+  orderNo: string,
+  data: {
+    items: { item_index: number; quantity: number }[]
+    reason: string
+    photos?: string[]
+  }
+) {
+  return apiClient.post(`/api/user/orders/${orderNo}/returns`, data)
+}
+
+// 上传退货照片
+export async function uploadReturnPhoto(orderNo: string, file: File) {
+  const formData = new FormData()
+  formData.append('file', file)
+  return apiClient.post(`/api/user/orders/${orderNo}/returns/photos`, formData, {
+    headers: {
+      'Content-Type': 'multipart/form-data',
+    },
+  })
+}
+
+// 撤回退货申请
+export async function cancelReturnRequest(orderNo: string, id: number) {
+  return apiClient.post(`/api/user/orders/${orderNo}/returns/${id}/cancel`)
+}
+
+// 管理端 - 退货申请列表
+export async function getAdminReturnRequests(params?: {
+  page?: number
+  limit?: number
+  status?: string
+  search?: string
+}) {
+  const query = new URLSearchParams()
+  if (params?.page) query.append('page', params.page.toString())
+  if (params?.limit) query.append('limit', params.limit.toString())
+  if (params?.status) query.append('status', params.status)
+  if (params?.search) query.append('search', params.search)
+
+  return apiClient.get(`/api/admin/returns?${query}`)
+}
+
+// 管理端 - 同意退货（生成退货单号）
+export async function approveAdminReturnRequest(id: number, remark?: string) {
+  return apiClient.post(`/api/admin/returns/${id}/approve`, { remark: remark || '' })
+}
+
+// 管理端 - 拒绝退货
+export async function rejectAdminReturnRequest(id: number, remark: string) {
+  return apiClient.post(`/api/admin/returns/${id}/reject`, { remark })
+}
+
+// 管理端 - 登记收到退货（restock 默认回补库存）
+export async function receiveAdminReturnRequest(
+  id: number,
+  data: { restock?: boolean; invalidate_serials?: boolean }
+) {
+  return apiClient.post(`/api/admin/returns/${id}/receive`, data)
+}
+
 // ==========================================
 // 管理员API
 // ==========================================
@@ -839,6 +1207,7 @@ export async function getAdminOrders(params?: OrderQueryParams) {
   if (params?.status) query.append('status', params.status)
   if (params?.search) query.append('search', params.search)
   if (params?.product_search) query.append('product_search', params.product_search) // 新增
+  if (params?.tag) query.append('tag', params.tag)
   if (params?.promo_code_id) query.append('promo_code_id', params.promo_code_id.toString())
   if (params?.promo_code) query.append('promo_code', params.promo_code)
   if (params?.user_id) query.append('user_id', params.user_id.toString())
@@ -876,103 +1245,509 @@ export async function getAdminProducts(params?: {
   return apiClient.get(`/api/admin/products?${query}`)
 }
 
+export async function getShippingRestrictionBlocks(params?: {
+  page?: number
+  limit?: number
+  country?: string
+  sku?: string
+  source?: string
+}) {
+  return apiClient.get('/api/admin/products/shipping-blocks', { params })
+}
+
+export async function clearShippingRestrictionBlocks() {
+  return apiClient.delete('/api/admin/products/shipping-blocks')
+}
+
 export async function getAdminProductCategories() {
   return apiClient.get('/api/admin/products/categories')
 }
 
-export async function getAdminProduct(id: number) {
-  return apiClient.get(`/api/admin/products/${id}`)
+export async function getAdminProduct(id: number) {
+  return apiClient.get(`/api/admin/products/${id}`)
+}
+
+export async function createProduct(data: any) {
+  return apiClient.post('/api/admin/products', data)
+}
+
+export async function updateProduct(id: number, data: any) {
+  return apiClient.put(`/api/admin/products/${id}`, data)
+}
+
+export async function deleteProduct(id: number) {
+  return apiClient.delete(`/api/admin/products/${id}`)
+}
+
+export async function toggleProductFeatured(id: number) {
+  return apiClient.post(`/api/admin/products/${id}/toggle-featured`)
+}
+
+export async function updateProductStatus(id: number, status: string) {
+  return apiClient.put(`/api/admin/products/${id}/status`, { status })
+}
+
+export async function uploadImage(file: File) {
+  const formData = new FormData()
+  formData.append('file', file)
+  return apiClient.post('/api/admin/upload/image', formData, {
+    headers: {
+      'Content-Type': 'multipart/form-data',
+    },
+  })
+}
+
+// 管理员订单详情
+export async function getAdminOrderDetail(id: number) {
+  return apiClient.get(`/api/admin/orders/${id}`)
+}
+
+// 管理员创建订单
+export async function createAdminOrder(data: any) {
+  return apiClient.post('/api/admin/orders', data)
+}
+
+export async function assignTracking(id: number, data: any) {
+  return apiClient.post(`/api/admin/orders/${id}/assign-shipping`, data)
+}
+
+export async function adminCompleteOrder(id: number, remark?: string) {
+  return apiClient.post(`/api/admin/orders/${id}/complete`, { remark })
+}
+
+export async function adminCancelOrder(id: number, reason?: string) {
+  return apiClient.post(`/api/admin/orders/${id}/cancel`, { reason })
+}
+
+export async function adminDeleteOrder(id: number) {
+  return apiClient.delete(`/api/admin/orders/${id}`)
+}
+
+export async function adminRefundOrder(id: number, reason?: string) {
+  return apiClient.post(`/api/admin/orders/${id}/refund`, { reason })
+}
+
+export async function adminConfirmRefund(
+  id: number,
+  data?: { transaction_id?: string; remark?: string }
+) {
+  return apiClient.post(`/api/admin/orders/${id}/confirm-refund`, data || {})
+}
+
+export interface OrderRefundItem {
+  item_index: number
+  sku?: string
+  quantity: number
+  amount_minor: number
+}
+
+export interface OrderRefund {
+  id: number
+  order_id: number
+  status: 'processing' | 'pending' | 'succeeded' | 'failed'
+  amount_minor: number
+  currency: string
+  items?: OrderRefundItem[]
+  full: boolean
+  reason?: string
+  transaction_id?: string
+  message?: string
+  admin_id?: number
+  completed_at?: string
+  created_at: string
+}
+
+export interface OrderRefundSummary {
+  currency: string
+  total_minor: number
+  refunded_minor: number
+  pending_minor: number
+  refundable_minor: number
+  refunded_quantities: Record<string, number>
+  refunded_item_amounts: Record<string, number>
+  refunds: OrderRefund[]
+}
+
+export async function getOrderRefunds(id: number) {
+  return apiClient.get(`/api/admin/orders/${id}/refunds`)
+}
+
+export async function createOrderRefund(
+  id: number,
+  data: { amount_minor?: number; items?: OrderRefundItem[]; reason?: string }
+) {
+  return apiClient.post(`/api/admin/orders/${id}/refunds`, data)
+}
+
+export async function confirmOrderRefund(
+  id: number,
+  refundId: number,
+  data?: { transaction_id?: string }
+) {
+  return apiClient.post(`/api/admin/orders/${id}/refunds/${refundId}/confirm`, data || {})
+}
+
+export async function receiveOrderReturn(
+  id: number,
+  data: {
+    items: { item_index: number; quantity: number }[]
+    restock?: boolean
+    invalidate_serials?: boolean
+    reason?: string
+  }
+) {
+  return apiClient.post(`/api/admin/orders/${id}/returns`, data)
+}
+
+export async function batchUpdateOrders(orderIds: number[], action: string) {
+  return apiClient.post('/api/admin/orders/batch/update', { order_ids: orderIds, action })
+}
+
+export interface OrderCodeLookupMatch {
+  source: 'virtual_stock' | 'serial'
+  item_id: number
+  code: string
+  item_status: string
+  item_name?: string
+  delivered_at?: string
+  first_viewed_at?: string
+  order_id?: number
+  order_no?: string
+  order_status?: string
+  user_id?: number
+  user_email?: string
+  store_id?: number
+}
+
+// 按买家提供的卡密或序列号反查订单（查询会记录操作日志）
+export async function lookupOrdersByCode(code: string) {
+  return apiClient.post('/api/admin/orders/code-lookup', { code })
+}
+
+export type AdminSearchResultType = 'order' | 'user' | 'ticket' | 'product' | 'serial'
+
+export interface AdminSearchResult {
+  type: AdminSearchResultType
+  id: number
+  title: string
+  subtitle?: string
+  status?: string
+  link: string
+  created_at: string
+}
+
+// 管理端全局搜索，结果只包含当前管理员有查看权限的类型
+export async function searchAdmin(q: string, types?: AdminSearchResultType[]) {
+  const query = new URLSearchParams({ q })
+  if (types && types.length > 0) query.append('types', types.join(','))
+  return apiClient.get(`/api/admin/search?${query}`)
+}
+
+export interface AdminQuickAction {
+  id: string
+  link: string
+}
+
+export interface AdminPalette {
+  actions: AdminQuickAction[]
+  recent: AdminSearchResult[]
+  assigned_tickets: AdminSearchResult[]
+}
+
+// 命令面板：可用快捷操作、最近处理的实体与分配给自己的工单
+export async function getAdminPalette() {
+  return apiClient.get('/api/admin/search/palette')
+}
+
+export interface OrderImportRowError {
+  row: number
+  order_ref?: string
+  order_no?: string
+  message: string
+}
+
+export interface OrderImportJob {
+  id: number
+  job_no: string
+  filename: string
+  status: 'queued' | 'running' | 'completed' | 'failed'
+  notify_customers: boolean
+  total_rows: number
+  total_orders: number
+  processed_orders: number
+  created_count: number
+  skipped_count: number
+  error_count: number
+  row_errors?: OrderImportRowError[]
+  failed_reason?: string
+  operator_name?: string
+  started_at?: string
+  completed_at?: string
+  created_at: string
+}
+
+// 批量导入订单（后台任务），返回任务信息
+export async function createOrderImportJob(file: File, notifyCustomers: boolean) {
+  const formData = new FormData()
+  formData.append('file', file)
+  formData.append('notify_customers', notifyCustomers ? 'true' : 'false')
+  return apiClient.post('/api/admin/orders/bulk-import', formData, {
+    headers: {
+      'Content-Type': 'multipart/form-data',
+    },
+  })
+}
+
+export async function getOrderImportJobs(params?: { page?: number; limit?: number }) {
+  return apiClient.get('/api/admin/orders/bulk-import/jobs', { params })
+}
+
+export async function getOrderImportJob(id: number) {
+  return apiClient.get(`/api/admin/orders/bulk-import/jobs/${id}`)
+}
+
+export interface IncomingTransaction {
+  id: number
+  channel: string
+  transaction_id: string
+  payment_method_id?: number
+  amount: string
+  currency: string
+  memo?: string
+  payer?: string
+  received_at: string
+  source: 'manual' | 'csv' | 'script'
+  status: 'unmatched' | 'matched' | 'ignored'
+  matched_order_id?: number
+  matched_order_no?: string
+  handled_at?: string
+  remark?: string
+  created_at: string
+}
+
+export interface PaymentMatchOrder {
+  id: number
+  order_no: string
+  currency: string
+  total_amount_minor: number
+  payment_method_id?: number
+  reserved_amount?: string
+  payment_deadline_at?: string
+  created_at: string
+}
+
+export interface PaymentMatchSuggestion {
+  order: PaymentMatchOrder
+  score: number
+  reasons: string[]
+}
+
+// 人工对账：到账流水与待付款订单
+export async function getIncomingTransactions(params?: {
+  page?: number
+  limit?: number
+  status?: string
+}) {
+  return apiClient.get('/api/admin/payment-matching/transactions', { params })
+}
+
+export async function createIncomingTransaction(data: {
+  transaction_id: string
+  channel?: string
+  amount: string
+  currency?: string
+  memo?: string
+  payer?: string
+  received_at?: string
+}) {
+  return apiClient.post('/api/admin/payment-matching/transactions', data)
+}
+
+export async function importIncomingTransactions(file: File) {
+  const formData = new FormData()
+  formData.append('file', file)
+  return apiClient.post('/api/admin/payment-matching/transactions/import', formData, {
+    headers: {
+      'Content-Type': 'multipart/form-data',
+    },
+  })
+}
+
+export async function getPaymentMatchSuggestions(id: number) {
+  return apiClient.get(`/api/admin/payment-matching/transactions/${id}/suggestions`)
+}
+
+export async function matchIncomingTransaction(id: number, orderId: number, remark?: string) {
+  return apiClient.post(`/api/admin/payment-matching/transactions/${id}/match`, {
+    order_id: orderId,
+    remark,
+  })
+}
+
+export async function ignoreIncomingTransaction(id: number, remark?: string) {
+  return apiClient.post(`/api/admin/payment-matching/transactions/${id}/ignore`, { remark })
+}
+
+export async function getPaymentMatchPendingOrders(params?: {
+  page?: number
+  limit?: number
+  search?: string
+}) {
+  return apiClient.get('/api/admin/payment-matching/pending-orders', { params })
+}
+
+export interface CODCollection {
+  id: number
+  order_id: number
+  order_no: string
+  payment_method_id: number
+  currency: string
+  amount_due: number
+  status: 'pending' | 'collected' | 'settled' | 'cancelled'
+  carrier?: string
+  collected_amount: number
+  collected_at?: string
+  settled_at?: string
+  remark?: string
+  created_at: string
+}
+
+export interface CODCarrierSummary {
+  carrier: string
+  currency: string
+  pending_count: number
+  pending_amount: number
+  outstanding_count: number
+  outstanding_amount: number
+  settled_count: number
+  settled_amount: number
+  shortfall_amount: number
+}
+
+export async function getCODCollections(params?: {
+  page?: number
+  limit?: number
+  status?: string
+  carrier?: string
+}) {
+  return apiClient.get('/api/admin/cod/collections', { params })
+}
+
+export async function recordCODCollection(
+  orderId: number,
+  data: { carrier: string; collected_amount: number; remark?: string }
+) {
+  return apiClient.post(`/api/admin/cod/orders/${orderId}/collect`, data)
+}
+
+export async function settleCODCollections(ids: number[]) {
+  return apiClient.post('/api/admin/cod/collections/settle', { ids })
+}
+
+export async function getCODReport() {
+  return apiClient.get('/api/admin/cod/report')
+}
+
+export async function updateOrderShippingInfo(id: number, data: any) {
+  return apiClient.put(`/api/admin/orders/${id}/shipping-info`, data)
+}
+
+export async function requestOrderResubmit(id: number, reason: string) {
+  return apiClient.post(`/api/admin/orders/${id}/request-resubmit`, { reason })
 }
 
-export async function createProduct(data: any) {
-  return apiClient.post('/api/admin/products', data)
+export async function adminMarkOrderAsPaid(id: number) {
+  return apiClient.post(`/api/admin/orders/${id}/mark-paid`)
 }
 
-export async function updateProduct(id: number, data: any) {
-  return apiClient.put(`/api/admin/products/${id}`, data)
+export async function adminSimulateOrderPayment(id: number) {
+  return apiClient.post(`/api/admin/orders/${id}/simulate-payment`)
 }
 
-export async function deleteProduct(id: number) {
-  return apiClient.delete(`/api/admin/products/${id}`)
+export async function adminDeliverVirtualStock(id: number, data?: { mark_only_shipped?: boolean }) {
+  return apiClient.post(`/api/admin/orders/${id}/deliver-virtual`, data || {})
 }
 
-export async function toggleProductFeatured(id: number) {
-  return apiClient.post(`/api/admin/products/${id}/toggle-featured`)
+// 撤销订单中泄露的卡密并从同一库存补发
+export async function adminRedeliverVirtualStock(
+  orderId: number,
+  stockId: number,
+  data?: { reason?: string; notify?: boolean }
+) {
+  return apiClient.post(
+    `/api/admin/orders/${orderId}/virtual-stocks/${stockId}/redeliver`,
+    data || {}
+  )
 }
 
-export async function updateProductStatus(id: number, status: string) {
-  return apiClient.put(`/api/admin/products/${id}/status`, { status })
+export async function updateOrderPrice(id: number, totalAmountMinor: number) {
+  return apiClient.put(`/api/admin/orders/${id}/price`, { total_amount_minor: totalAmountMinor })
 }
 
-export async function uploadImage(file: File) {
-  const formData = new FormData()
-  formData.append('file', file)
-  return apiClient.post('/api/admin/upload/image', formData, {
-    headers: {
-      'Content-Type': 'multipart/form-data',
-    },
-  })
+export async function updateOrderTags(id: number, tags: string[]) {
+  return apiClient.put(`/api/admin/orders/${id}/tags`, { tags })
 }
 
-// 管理员订单详情
-export async function getAdminOrderDetail(id: number) {
-  return apiClient.get(`/api/admin/orders/${id}`)
+// 订单重量与装箱建议
+export async function getAdminOrderPackagePlan(orderId: number) {
+  return apiClient.get(`/api/admin/orders/${orderId}/package-plan`)
 }
 
-// 管理员创建订单
-export async function createAdminOrder(data: any) {
-  return apiClient.post('/api/admin/orders', data)
+export async function getAdminOrderTracking(orderId: number) {
+  return apiClient.get(`/api/admin/orders/${orderId}/tracking`)
 }
 
-export async function assignTracking(id: number, data: any) {
-  return apiClient.post(`/api/admin/orders/${id}/assign-shipping`, data)
+export async function syncAdminOrderTracking(orderId: number) {
+  return apiClient.post(`/api/admin/orders/${orderId}/tracking/sync`)
 }
 
-export async function adminCompleteOrder(id: number, remark?: string) {
-  return apiClient.post(`/api/admin/orders/${id}/complete`, { remark })
+// 合并发货：将同一买家、同一收货人的其他待发货订单并入当前订单
+export async function getAdminOrderMergeCandidates(orderId: number) {
+  return apiClient.get(`/api/admin/orders/${orderId}/merge-candidates`)
 }
 
-export async function adminCancelOrder(id: number, reason?: string) {
-  return apiClient.post(`/api/admin/orders/${id}/cancel`, { reason })
+export async function mergeAdminOrders(orderId: number, orderIds: number[]) {
+  return apiClient.post(`/api/admin/orders/${orderId}/merge`, { order_ids: orderIds })
 }
 
-export async function adminDeleteOrder(id: number) {
-  return apiClient.delete(`/api/admin/orders/${id}`)
+// 订单内部备注
+export async function getAdminOrderNotes(orderId: number) {
+  return apiClient.get(`/api/admin/orders/${orderId}/notes`)
 }
 
-export async function adminRefundOrder(id: number, reason?: string) {
-  return apiClient.post(`/api/admin/orders/${id}/refund`, { reason })
+export async function getAdminOrderNoteMentionableAdmins() {
+  return apiClient.get('/api/admin/orders/notes/mentionable-admins')
 }
 
-export async function adminConfirmRefund(
-  id: number,
-  data?: { transaction_id?: string; remark?: string }
+export async function createAdminOrderNote(
+  orderId: number,
+  data: { content: string; mention_ids?: number[] }
 ) {
-  return apiClient.post(`/api/admin/orders/${id}/confirm-refund`, data || {})
+  return apiClient.post(`/api/admin/orders/${orderId}/notes`, data)
 }
 
-export async function batchUpdateOrders(orderIds: number[], action: string) {
-  return apiClient.post('/api/admin/orders/batch/update', { order_ids: orderIds, action })
+export async function updateAdminOrderNote(orderId: number, noteId: number, content: string) {
+  return apiClient.put(`/api/admin/orders/${orderId}/notes/${noteId}`, { content })
 }
 
-export async function updateOrderShippingInfo(id: number, data: any) {
-  return apiClient.put(`/api/admin/orders/${id}/shipping-info`, data)
+export async function deleteAdminOrderNote(orderId: number, noteId: number) {
+  return apiClient.delete(`/api/admin/orders/${orderId}/notes/${noteId}`)
 }
 
-export async function requestOrderResubmit(id: number, reason: string) {
-  return apiClient.post(`/api/admin/orders/${id}/request-resubmit`, { reason })
+export async function pinAdminOrderNote(orderId: number, noteId: number, pinned: boolean) {
+  return apiClient.post(`/api/admin/orders/${orderId}/notes/${noteId}/pin`, { pinned })
 }
 
-export async function adminMarkOrderAsPaid(id: number) {
-  return apiClient.post(`/api/admin/orders/${id}/mark-paid`)
+export async function getAdminOrderMessages(orderId: number) {
+  return apiClient.get(`/api/admin/orders/${orderId}/messages`)
 }
 
-export async function adminDeliverVirtualStock(id: number, data?: { mark_only_shipped?: boolean }) {
-  return apiClient.post(`/api/admin/orders/${id}/deliver-virtual`, data || {})
+export async function replyAdminOrderMessage(orderId: number, content: string) {
+  return apiClient.post(`/api/admin/orders/${orderId}/messages`, { content })
 }
 
-export async function updateOrderPrice(id: number, totalAmountMinor: number) {
-  return apiClient.put(`/api/admin/orders/${id}/price`, { total_amount_minor: totalAmountMinor })
+export async function escalateAdminOrderMessages(orderId: number) {
+  return apiClient.post(`/api/admin/orders/${orderId}/messages/escalate`)
 }
 
 // 用户管理
@@ -1060,16 +1835,210 @@ export async function createApiKey(data: {
   key_name: string
   platform: string
   scopes: string[]
+  webhook_url?: string
   rate_limit?: number
+  burst_limit?: number
+  allow_duplicate_external_orders?: boolean
   expires_at?: string
 }) {
   return apiClient.post('/api/admin/api-keys', data)
 }
 
+export interface ApiKeyUsageBucket {
+  api_key_id: number
+  hour: string
+  requests: number
+  throttled: number
+}
+
+export interface ApiKeyUsage {
+  api_key_id: number
+  rate_limit: number
+  burst_limit: number
+  remaining_tokens: number
+  window_hours: number
+  total_requests: number
+  total_throttled: number
+  hourly: ApiKeyUsageBucket[]
+}
+
+export async function getApiKeyUsage(id: number, hours = 24) {
+  return apiClient.get(`/api/admin/api-keys/${id}/usage`, { params: { hours } })
+}
+
 export async function deleteApiKey(id: number) {
   return apiClient.delete(`/api/admin/api-keys/${id}`)
 }
 
+// 出站 Webhook
+export interface WebhookEndpoint {
+  id: number
+  name: string
+  url: string
+  events: string[]
+  enabled: boolean
+  description?: string
+  platform?: string
+  last_status?: 'succeeded' | 'failed'
+  last_sent_at?: string
+  created_at: string
+  secret?: string
+}
+
+export interface WebhookDelivery {
+  id: number
+  endpoint_id: number
+  event_id: string
+  event: string
+  payload: string
+  status: 'pending' | 'succeeded' | 'failed'
+  attempts: number
+  next_attempt_at?: string
+  response_status?: number
+  response_body?: string
+  error?: string
+  duration_ms: number
+  delivered_at?: string
+  created_at: string
+}
+
+export interface WebhookEndpointInput {
+  name: string
+  url: string
+  events: string[]
+  enabled: boolean
+  description?: string
+  platform?: string
+}
+
+export async function getWebhookMeta() {
+  return apiClient.get('/api/admin/webhooks/meta')
+}
+
+export async function getWebhooks() {
+  return apiClient.get('/api/admin/webhooks')
+}
+
+export async function createWebhook(data: WebhookEndpointInput) {
+  return apiClient.post('/api/admin/webhooks', data)
+}
+
+export async function updateWebhook(id: number, data: WebhookEndpointInput) {
+  return apiClient.put(`/api/admin/webhooks/${id}`, data)
+}
+
+export async function deleteWebhook(id: number) {
+  return apiClient.delete(`/api/admin/webhooks/${id}`)
+}
+
+export async function rotateWebhookSecret(id: number) {
+  return apiClient.post(`/api/admin/webhooks/${id}/rotate-secret`)
+}
+
+export async function testWebhook(id: number) {
+  return apiClient.post(`/api/admin/webhooks/${id}/test`)
+}
+
+export async function getWebhookDeliveries(params: {
+  page?: number
+  limit?: number
+  endpoint_id?: number
+  event?: string
+  status?: string
+}) {
+  return apiClient.get('/api/admin/webhooks/deliveries', { params })
+}
+
+export async function redeliverWebhook(deliveryId: number) {
+  return apiClient.post(`/api/admin/webhooks/deliveries/${deliveryId}/redeliver`)
+}
+
+// 管理员个人通知订阅与站内通知
+export type AdminNotificationChannel = 'email' | 'telegram' | 'in_app'
+
+export interface AdminNotificationSubscription {
+  id: number
+  admin_id: number
+  event: string
+  channel: AdminNotificationChannel
+  countries: string[] | null
+  categories: string[] | null
+  telegram_chat_id?: string
+  enabled: boolean
+  created_at: string
+  updated_at: string
+}
+
+export interface AdminNotificationSubscriptionInput {
+  event: string
+  channel: AdminNotificationChannel
+  countries: string[]
+  categories: string[]
+  telegram_chat_id?: string
+  enabled: boolean
+}
+
+export interface AdminNotificationMeta {
+  events: { event: string; permission: string; allowed: boolean }[]
+  channels: AdminNotificationChannel[]
+  telegram_configured: boolean
+}
+
+export interface AdminNotification {
+  id: number
+  event: string
+  title: string
+  content: string
+  link?: string
+  read_at?: string
+  created_at: string
+}
+
+export async function getAdminNotificationMeta() {
+  return apiClient.get('/api/admin/notifications/meta')
+}
+
+export async function getAdminNotificationSubscriptions() {
+  return apiClient.get('/api/admin/notifications/subscriptions')
+}
+
+export async function createAdminNotificationSubscription(
+  data: AdminNotificationSubscriptionInput
+) {
+  return apiClient.post('/api/admin/notifications/subscriptions', data)
+}
+
+export async function updateAdminNotificationSubscription(
+  id: number,
+  data: AdminNotificationSubscriptionInput
+) {
+  return apiClient.put(`/api/admin/notifications/subscriptions/${id}`, data)
+}
+
+export async function deleteAdminNotificationSubscription(id: number) {
+  return apiClient.delete(`/api/admin/notifications/subscriptions/${id}`)
+}
+
+export async function getAdminNotifications(params: {
+  page?: number
+  limit?: number
+  unread?: boolean
+}) {
+  return apiClient.get('/api/admin/notifications', { params })
+}
+
+export async function getAdminNotificationUnreadCount() {
+  return apiClient.get('/api/admin/notifications/unread-count')
+}
+
+export async function markAdminNotificationRead(id: number) {
+  return apiClient.post(`/api/admin/notifications/${id}/read`)
+}
+
+export async function markAllAdminNotificationsRead() {
+  return apiClient.post('/api/admin/notifications/read-all')
+}
+
 // 插件管理
 export interface AdminPluginEffectiveCapabilityPolicy {
   hooks: string[]
@@ -1918,6 +2887,7 @@ export async function streamAdminPluginWorkspace(
   if (locale) {
     headers.set(APP_LOCALE_HEADER, locale)
   }
+  await applyFetchSessionHeaders(headers, 'GET')
 
   const response = await fetch(
     resolveFetchAPIURL(
@@ -2226,6 +3196,7 @@ export async function executePluginRouteActionStream(
   if (requestedLocale) {
     headers.set(APP_LOCALE_HEADER, requestedLocale)
   }
+  await applyFetchSessionHeaders(headers, 'POST')
 
   const response = await fetch(resolveFetchAPIURL(url, { direct: useDirectPublicAPI }), {
     method: 'POST',
@@ -2471,6 +3442,26 @@ export async function getLogStatistics() {
   return apiClient.get('/api/admin/logs/statistics')
 }
 
+export async function getSlowQueries(limit?: number) {
+  return apiClient.get('/api/admin/logs/slow-queries', { params: { limit } })
+}
+
+export async function resetSlowQueries() {
+  return apiClient.delete('/api/admin/logs/slow-queries')
+}
+
+export async function getBackgroundJobs() {
+  return apiClient.get('/api/admin/logs/background-jobs')
+}
+
+export async function getScraperOffenders(params?: { hours?: number; limit?: number }) {
+  return apiClient.get('/api/admin/security/scraper-offenders', { params })
+}
+
+export async function banScraperOffender(ipAddress: string) {
+  return apiClient.post('/api/admin/security/scraper-offenders/ban', { ip_address: ipAddress })
+}
+
 export async function retryFailedEmails(emailIds?: number[]) {
   if (emailIds && emailIds.length > 0) {
     return apiClient.post('/api/admin/logs/emails/retry', { email_ids: emailIds })
@@ -2633,6 +3624,7 @@ export async function updateVirtualInventory(
     description?: string
     total_limit?: number
     allow_inline_iframe?: boolean
+    delivery_order?: string
     is_active?: boolean
     notes?: string
   }
@@ -2652,10 +3644,12 @@ export async function importVirtualInventoryStock(
     import_type: 'file' | 'text'
     file?: File
     content?: string
+    expires_at?: string
   }
 ) {
   const formData = new FormData()
   formData.append('import_type', data.import_type)
+  if (data.expires_at) formData.append('expires_at', data.expires_at)
 
   if (data.import_type === 'file' && data.file) {
     formData.append('file', data.file)
@@ -2674,6 +3668,7 @@ export async function createVirtualInventoryStockManually(
   data: {
     content: string
     remark?: string
+    expires_at?: string
   }
 ) {
   return apiClient.post(`/api/admin/virtual-inventories/${virtualInventoryId}/stocks`, data)
@@ -2723,6 +3718,71 @@ export async function releaseVirtualInventoryStock(virtualInventoryId: number, s
   )
 }
 
+// Revoke a delivered virtual inventory stock item, optionally reissuing a replacement
+export async function revokeVirtualInventoryStock(
+  virtualInventoryId: number,
+  stockId: number,
+  data: { reason: string; reissue?: boolean; notify?: boolean }
+) {
+  return apiClient.post(
+    `/api/admin/virtual-inventories/${virtualInventoryId}/stocks/${stockId}/revoke`,
+    data
+  )
+}
+
+// Reveal the plain content of a stock item; every call is written to the reveal log
+export async function revealVirtualInventoryStock(virtualInventoryId: number, stockId: number) {
+  return apiClient.post(
+    `/api/admin/virtual-inventories/${virtualInventoryId}/stocks/${stockId}/reveal`
+  )
+}
+
+export async function getVirtualInventoryStockReveals(virtualInventoryId: number, stockId: number) {
+  return apiClient.get(
+    `/api/admin/virtual-inventories/${virtualInventoryId}/stocks/${stockId}/reveals`
+  )
+}
+
+// Set or clear the expiry of unsold stock items in a batch
+export async function setVirtualInventoryBatchExpiry(
+  virtualInventoryId: number,
+  data: { batch_no: string; expires_at?: string }
+) {
+  return apiClient.put(`/api/admin/virtual-inventories/${virtualInventoryId}/batches/expiry`, data)
+}
+
+// Move available stock items to another virtual inventory
+export async function transferVirtualInventoryStock(
+  virtualInventoryId: number,
+  data: {
+    target_inventory_id: number
+    batch_no?: string
+    remark_keyword?: string
+    stock_ids?: number[]
+    quantity?: number
+    reason?: string
+  }
+) {
+  return apiClient.post(`/api/admin/virtual-inventories/${virtualInventoryId}/transfer`, data)
+}
+
+// Transfer records of a virtual inventory (incoming and outgoing)
+export async function getVirtualInventoryTransfers(
+  virtualInventoryId: number,
+  params?: { page?: number; limit?: number }
+) {
+  const query = new URLSearchParams()
+  if (params?.page) query.append('page', params.page.toString())
+  if (params?.limit) query.append('limit', params.limit.toString())
+  return apiClient.get(`/api/admin/virtual-inventories/${virtualInventoryId}/transfers?${query}`)
+}
+
+// Expiring virtual stock grouped by inventory
+export async function getVirtualInventoryExpiryReport(days?: number) {
+  const query = days ? `?days=${days}` : ''
+  return apiClient.get(`/api/admin/virtual-inventories/expiry-report${query}`)
+}
+
 // Test delivery script
 export async function testDeliveryScript(
   script: string,
@@ -2767,6 +3827,7 @@ export async function saveProductVirtualVariantBindings(
     virtual_inventory_id: number | null
     is_random?: boolean
     priority?: number
+    delivery_order?: string
   }>
 ) {
   return apiClient.put(`/api/admin/products/${productId}/virtual-inventory-bindings`, { bindings })
@@ -2781,6 +3842,74 @@ export async function updateSettings(data: any) {
   return apiClient.put('/api/admin/settings', data)
 }
 
+export interface SettingsConfigChange {
+  path: string
+  before: unknown
+  after: unknown
+  redacted?: boolean
+}
+
+export interface SettingsRevision {
+  id: number
+  action: 'baseline' | 'update' | 'rollback' | 'import'
+  admin_id?: number
+  rollback_of_id?: number
+  sections: string[]
+  changes: SettingsConfigChange[]
+  change_count: number
+  created_at: string
+}
+
+export async function getSettingsRevisions(params?: { page?: number; limit?: number }) {
+  return apiClient.get('/api/admin/settings/revisions', { params })
+}
+
+export async function rollbackSettingsRevision(id: number) {
+  return apiClient.post(`/api/admin/settings/revisions/${id}/rollback`)
+}
+
+export interface SettingsBundle {
+  format: string
+  version: number
+  exported_at: string
+  source: { app_name?: string; app_url?: string; env?: string }
+  secrets_mode: 'redacted' | 'encrypted'
+  config: Record<string, unknown>
+  encrypted_secrets?: { kdf: string; salt: string; nonce: string; ciphertext: string }
+}
+
+export interface SettingsImportPreview {
+  config_hash: string
+  source: SettingsBundle['source']
+  exported_at: string
+  sections: string[]
+  changes: SettingsConfigChange[]
+  skipped_paths: string[]
+  secrets_applied: string[]
+  unresolved_secrets: string[]
+  valid: boolean
+  validation_error?: string
+}
+
+export async function exportSettingsBundle(data: {
+  secrets: 'redacted' | 'encrypted'
+  passphrase?: string
+}) {
+  return apiClient.post('/api/admin/settings/export', data)
+}
+
+export async function previewSettingsImport(data: { bundle: SettingsBundle; passphrase?: string }) {
+  return apiClient.post('/api/admin/settings/import/preview', data)
+}
+
+export async function importSettingsBundle(data: {
+  bundle: SettingsBundle
+  passphrase?: string
+  expected_hash: string
+}) {
+  return apiClient.post('/api/admin/settings/import', data)
+}
+
 export async function testSMTP(data: any) {
   return apiClient.post('/api/admin/settings/smtp/test', data)
 }
@@ -2789,6 +3918,20 @@ export async function testSMS(data: { phone: string }) {
   return apiClient.post('/api/admin/settings/sms/test', data)
 }
 
+export interface ExchangeRateQuote {
+  from: string
+  to: string
+  rate: number
+  provider: string
+  updated_at: string
+  stale: boolean
+  last_error?: string
+}
+
+export async function getExchangeRates() {
+  return apiClient.get('/api/admin/settings/exchange-rates')
+}
+
 // 邮件模板管理
 export async function getEmailTemplates() {
   return apiClient.get('/api/admin/settings/email-templates')
@@ -2837,7 +3980,9 @@ export async function resetLandingPage() {
 
 // 公开配置（无需登录）
 export async function getPublicConfig() {
-  return publicApiClient.get('/api/config/public')
+  const response: any = await publicApiClient.get('/api/config/public')
+  setSessionCookieConfig(response?.data?.session_cookie)
+  return response
 }
 
 // 获取页面注入脚本/样式（无需登录，通过path参数穿透CDN）
@@ -3051,6 +4196,12 @@ export interface PaymentMethod {
   manifest?: string
   sort_order: number
   poll_interval: number
+  auto_cancel_hours?: number
+  allowed_hosts?: string[]
+  required_order_tags?: string[]
+  excluded_order_tags?: string[]
+  sandbox?: boolean
+  cash_on_delivery?: boolean
   created_at: string
   updated_at: string
 }
@@ -3143,6 +4294,19 @@ export interface PaymentMethodMarketArtifact {
   governance?: Record<string, any>
 }
 
+export interface PaymentMethodBreaker {
+  payment_method_id: number
+  host: string
+  state: 'closed' | 'open' | 'half_open'
+  consecutive_failures: number
+  failure_threshold: number
+  open_until?: string
+  last_failure_at?: string
+  last_error?: string
+  probe_in_flight: boolean
+  trip_count: number
+}
+
 export interface PaymentCardResult {
   html: string
   title?: LocalizedTextValue
@@ -3150,6 +4314,25 @@ export interface PaymentCardResult {
   data?: Record<string, any>
 }
 
+export interface PaymentScriptLogEntry {
+  level: 'info' | 'warn' | 'error' | 'debug'
+  message: string
+  time: string
+}
+
+export interface PaymentMethodScriptLog {
+  id: number
+  payment_method_id: number
+  callback: string
+  order_id?: number
+  success: boolean
+  error?: string
+  entries: PaymentScriptLogEntry[]
+  truncated: boolean
+  duration_ms: number
+  created_at: string
+}
+
 // 管理端API
 export async function getPaymentMethods(enabledOnly?: boolean) {
   return apiClient.get('/api/admin/payment-methods', { params: { enabled_only: enabledOnly } })
@@ -3232,6 +4415,23 @@ export async function reorderPaymentMethods(ids: number[]) {
   return apiClient.post('/api/admin/payment-methods/reorder', { ids })
 }
 
+export async function getPaymentMethodBreakers(paymentMethodId?: number) {
+  return apiClient.get('/api/admin/payment-methods/breakers', {
+    params: paymentMethodId ? { payment_method_id: paymentMethodId } : undefined,
+  })
+}
+
+export async function resetPaymentMethodBreakers(id: number) {
+  return apiClient.post(`/api/admin/payment-methods/${id}/breakers/reset`)
+}
+
+export async function getPaymentMethodScriptLogs(
+  id: number,
+  params?: { page?: number; limit?: number; callback?: string }
+) {
+  return apiClient.get(`/api/admin/payment-methods/${id}/logs`, { params })
+}
+
 export async function testPaymentScript(script: string, config?: Record<string, any>) {
   return apiClient.post('/api/admin/payment-methods/test-script', { script, config })
 }
@@ -3241,8 +4441,11 @@ export async function initBuiltinPaymentMethods() {
 }
 
 // 用户端API
-export async function getUserPaymentMethods() {
-  return apiClient.get('/api/user/payment-methods')
+// 传入订单号时按订单标签过滤付款方式
+export async function getUserPaymentMethods(orderNo?: string) {
+  return apiClient.get('/api/user/payment-methods', {
+    params: orderNo ? { order_no: orderNo } : undefined,
+  })
 }
 
 export async function getOrderPaymentInfo(orderNo: string) {
@@ -3281,6 +4484,7 @@ export interface Ticket {
   category?: string
   priority: string
   status: string
+  tags?: string[]
   assigned_to?: number
   last_message_at?: string
   last_message_preview?: string
@@ -3294,6 +4498,11 @@ export interface Ticket {
   assigned_user?: any
 }
 
+export interface TicketTagCount {
+  tag: string
+  count: number
+}
+
 export interface TicketMessage {
   id: number
   ticket_id: number
@@ -3379,6 +4588,7 @@ export async function getAdminTickets(params?: {
   exclude_status?: string
   search?: string
   assigned_to?: string
+  tag?: string
 }) {
   const query = new URLSearchParams()
   if (params?.page) query.append('page', params.page.toString())
@@ -3387,6 +4597,7 @@ export async function getAdminTickets(params?: {
   if (params?.exclude_status) query.append('exclude_status', params.exclude_status)
   if (params?.search) query.append('search', params.search)
   if (params?.assigned_to) query.append('assigned_to', params.assigned_to)
+  if (params?.tag) query.append('tag', params.tag)
 
   return apiClient.get(`/api/admin/tickets?${query}`)
 }
@@ -3415,6 +4626,7 @@ export async function updateAdminTicket(
     status?: string
     priority?: string
     assigned_to?: number
+    tags?: string[]
   }
 ) {
   return apiClient.put(`/api/admin/tickets/${id}`, data)
@@ -2,9 +2,21 @@ import { normalizeAuthUser } from '@/lib/auth-user'
 
 const TOKEN_KEY = 'auth_token'
 const USER_KEY = 'user_info'
+const SESSION_MODE_KEY = 'auth_session_mode'
 export const AUTH_TOKEN_COOKIE_NAME = 'auralogic_auth_token'
 export const AUTH_SESSION_HINT_COOKIE_NAME = 'auralogic_session'
 export const AUTH_COOKIE_DEFAULT_MAX_AGE_SECONDS = 60 * 60 * 24 * 30
+export const AUTH_MODE_HEADER = 'X-Auth-Mode'
+export const DEFAULT_CSRF_HEADER_NAME = 'X-CSRF-Token'
+
+export interface CSRFToken {
+  token: string
+  headerName: string
+}
+
+let sessionCookieEnabled = false
+let csrfHeaderName = DEFAULT_CSRF_HEADER_NAME
+let csrfToken: CSRFToken | null = null
 
 function isBrowser(): boolean {
   return typeof window !== 'undefined'
@@ -86,6 +98,48 @@ export function clearToken(): void {
   clearLegacyToken()
   localStorage.removeItem(USER_KEY)
   clearSessionHint()
+  clearCookieSession()
+}
+
+// 记录公开配置中的 Cookie 会话开关，登录请求据此选择会话模式
+export function setSessionCookieConfig(config?: { enabled?: boolean; header_name?: string }): void {
+  sessionCookieEnabled = config?.enabled === true
+  csrfHeaderName = config?.header_name?.trim() || DEFAULT_CSRF_HEADER_NAME
+}
+
+export function isSessionCookieEnabled(): boolean {
+  return sessionCookieEnabled
+}
+
+// Cookie 会话：JWT 由后端写入 HttpOnly Cookie，写请求需回传 CSRF 令牌
+export function setCookieSession(token?: string, headerName?: string): void {
+  if (!isBrowser()) return
+  localStorage.setItem(SESSION_MODE_KEY, 'cookie')
+  setCSRFToken(token, headerName)
+  markSessionActive()
+}
+
+export function isCookieSession(): boolean {
+  if (!isBrowser()) return false
+  return localStorage.getItem(SESSION_MODE_KEY) === 'cookie'
+}
+
+export function clearCookieSession(): void {
+  csrfToken = null
+  if (!isBrowser()) return
+  localStorage.removeItem(SESSION_MODE_KEY)
+}
+
+// CSRF 令牌只保存在内存中，页面刷新后重新获取
+export function getCSRFToken(): CSRFToken | null {
+  return csrfToken
+}
+
+export function setCSRFToken(token?: string, headerName?: string): void {
+  const normalized = token?.trim()
+  csrfToken = normalized
+    ? { token: normalized, headerName: headerName?.trim() || csrfHeaderName }
+    : null
 }
 
 export function isAuthenticated(): boolean {
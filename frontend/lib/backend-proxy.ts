@@ -23,6 +23,9 @@ export const FORWARDED_REQUEST_HEADERS = [
   'x-session-id',
 ]
 
+// Cookie 会话请求额外转发浏览器 Cookie 与 same_site 策略校验所需的来源头
+export const COOKIE_SESSION_REQUEST_HEADERS = ['cookie', 'origin', 'referer', 'x-auth-mode']
+
 export const HOP_BY_HOP_RESPONSE_HEADERS = new Set([
   'connection',
   'content-length',
@@ -118,6 +121,14 @@ export function isSecureRequest(request: Pick<Request, 'headers' | 'url'>): bool
   return request.headers.get('x-forwarded-proto') === 'https' || request.url.startsWith('https://')
 }
 
+export function isCookieSessionRequest(request: Pick<Request, 'headers'>): boolean {
+  return (
+    String(request.headers.get('x-auth-mode') || '')
+      .trim()
+      .toLowerCase() === 'cookie'
+  )
+}
+
 export function copyProxyRequestHeaders(
   request: Pick<Request, 'headers'>,
   bearerToken?: string
@@ -129,19 +140,39 @@ export function copyProxyRequestHeaders(
       headers.set(name, value)
     }
   }
+  if (isCookieSessionRequest(request)) {
+    for (const name of COOKIE_SESSION_REQUEST_HEADERS) {
+      const value = request.headers.get(name)
+      if (value) {
+        headers.set(name, value)
+      }
+    }
+    // CSRF 请求头名称可配置，按名称包含 csrf 转发
+    request.headers.forEach((value, key) => {
+      if (key.toLowerCase().includes('csrf')) {
+        headers.set(key, value)
+      }
+    })
+  }
   if (bearerToken) {
     headers.set('authorization', `Bearer ${bearerToken}`)
   }
   return headers
 }
 
-export function copyProxyResponseHeaders(source: Headers): Headers {
+export function copyProxyResponseHeaders(source: Headers, relayCookies = false): Headers {
   const headers = new Headers()
   source.forEach((value, key) => {
     if (!HOP_BY_HOP_RESPONSE_HEADERS.has(key.toLowerCase())) {
       headers.set(key, value)
     }
   })
+  // Cookie 会话由后端签发和清除 Cookie，需要透传给浏览器
+  if (relayCookies) {
+    for (const value of source.getSetCookie()) {
+      headers.append('set-cookie', value)
+    }
+  }
   return headers
 }
 
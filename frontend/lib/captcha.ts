@@ -0,0 +1,166 @@
+// 第三方验证码驱动的脚本加载与组件渲染
+// 支持 Turnstile、reCAPTCHA v2/v3 与 hCaptcha
+
+export type CaptchaScene = 'login' | 'register' | 'serial_verify' | 'bind'
+
+export interface CaptchaWidgetOptions {
+  siteKey: string
+  theme?: 'light' | 'dark'
+  // reCAPTCHA v3 的 action，后端会校验与场景一致
+  action: CaptchaScene
+  onToken: (token: string) => void
+  onExpire: () => void
+}
+
+interface CaptchaScript {
+  id: string
+  src: (siteKey: string) => string
+  ready: () => boolean
+}
+
+// v3 token 有效期 2 分钟，提前刷新
+const RECAPTCHA_V3_REFRESH_MS = 100000
+const SCRIPT_READY_TIMEOUT_MS = 15000
+
+const captchaWindow = () => window as any
+
+const captchaScripts: Record<string, CaptchaScript> = {
+  cloudflare: {
+    id: 'cf-turnstile-script',
+    src: () => 'https://challenges.cloudflare.com/turnstile/v0/api.js?render=explicit',
+    ready: () => Boolean(captchaWindow().turnstile?.render),
+  },
+  google: {
+    id: 'recaptcha-script',
+    src: () => 'https://www.google.com/recaptcha/api.js?render=explicit',
+    ready: () => Boolean(captchaWindow().grecaptcha?.render),
+  },
+  recaptcha_v3: {
+    id: 'recaptcha-v3-script',
+    src: (siteKey) =>
+      `https://www.google.com/recaptcha/api.js?render=${encodeURIComponent(siteKey)}`,
+    ready: () => Boolean(captchaWindow().grecaptcha?.execute),
+  },
+  hcaptcha: {
+    id: 'hcaptcha-script',
+    src: () => 'https://js.hcaptcha.com/1/api.js?render=explicit',
+    ready: () => Boolean(captchaWindow().hcaptcha?.render),
+  },
+}
+
+const recaptchaV3Widgets: CaptchaWidgetOptions[] = []
+// 缓存场景解析结果，保持引用稳定，避免 effect 依赖每次渲染都变化
+const resolvedScenes = new WeakMap<object, Partial<Record<CaptchaScene, any>>>()
+
+// resolveCaptchaScene 使用场景覆盖的驱动与站点密钥（后端 captcha.scenes）
+export function resolveCaptchaScene(captcha: any, scene: CaptchaScene) {
+  if (!captcha) return captcha
+  const override = captcha.scenes?.[scene]
+  if (!override?.provider) return captcha
+  const cached = resolvedScenes.get(captcha) || {}
+  if (!cached[scene]) {
+    cached[scene] = { ...captcha, provider: override.provider, site_key: override.site_key }
+    resolvedScenes.set(captcha, cached)
+  }
+  return cached[scene]
+}
+
+// isWidgetCaptchaProvider 需要加载第三方脚本的驱动
+export function isWidgetCaptchaProvider(provider?: string) {
+  return Boolean(provider && captchaScripts[provider])
+}
+
+// isInteractiveCaptchaProvider 需要用户完成挑战的驱动，完成时可自动提交
+export function isInteractiveCaptchaProvider(provider?: string) {
+  return provider === 'cloudflare' || provider === 'google' || provider === 'hcaptcha'
+}
+
+export function loadCaptchaScript(provider: string, siteKey: string, onReady: () => void) {
+  const script = captchaScripts[provider]
+  if (!script || typeof window === 'undefined') return
+  if (script.ready()) {
+    onReady()
+    return
+  }
+  if (!document.getElementById(script.id)) {
+    const element = document.createElement('script')
+    element.id = script.id
+    element.src = script.src(siteKey)
+    element.async = true
+    document.head.appendChild(element)
+  }
+  const startedAt = Date.now()
+  const timer = setInterval(() => {
+    if (script.ready()) {
+      clearInterval(timer)
+      onReady()
+    } else if (Date.now() - startedAt > SCRIPT_READY_TIMEOUT_MS) {
+      clearInterval(timer)
+    }
+  }, 100)
+}
+
+function executeRecaptchaV3(options: CaptchaWidgetOptions) {
+  const grecaptcha = captchaWindow().grecaptcha
+  grecaptcha.ready(() => {
+    grecaptcha
+      .execute(options.siteKey, { action: options.action })
+      .then(options.onToken, options.onExpire)
+  })
+}
+
+export function renderCaptchaWidget(
+  provider: string,
+  container: HTMLElement,
+  options: CaptchaWidgetOptions
+): any {
+  const params = {
+    sitekey: options.siteKey,
+    theme: options.theme || 'light',
+    callback: options.onToken,
+    'expired-callback': options.onExpire,
+  }
+  switch (provider) {
+    case 'cloudflare':
+      return captchaWindow().turnstile.render(container, params)
+    case 'google':
+      return captchaWindow().grecaptcha.render(container, params)
+    case 'hcaptcha':
+      return captchaWindow().hcaptcha.render(container, params)
+    case 'recaptcha_v3': {
+      // v3 无可见组件，定时刷新 token；容器卸载后停止
+      executeRecaptchaV3(options)
+      const timer = setInterval(() => {
+        if (!container.isConnected) {
+          clearInterval(timer)
+          return
+        }
+        executeRecaptchaV3(options)
+      }, RECAPTCHA_V3_REFRESH_MS)
+      recaptchaV3Widgets.push(options)
+      return recaptchaV3Widgets.length - 1
+    }
+    default:
+      return undefined
+  }
+}
+
+export function resetCaptchaWidget(provider: string, widgetId: any) {
+  const win = captchaWindow()
+  switch (provider) {
+    case 'cloudflare':
+      win.turnstile?.reset(widgetId)
+      break
+    case 'google':
+      win.grecaptcha?.reset(widgetId)
+      break
+    case 'hcaptcha':
+      win.hcaptcha?.reset(widgetId)
+      break
+    case 'recaptcha_v3': {
+      const options = recaptchaV3Widgets[widgetId]
+      if (options && win.grecaptcha?.execute) executeRecaptchaV3(options)
+      break
+    }
+  }
+}
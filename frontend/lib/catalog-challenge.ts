@@ -0,0 +1,76 @@
+// 商品目录防爬 JS 挑战：后端要求时解出工作量证明，之后通过 X-Catalog-Token 请求头携带
+
+export const CATALOG_TOKEN_HEADER = 'X-Catalog-Token'
+export const CATALOG_CHALLENGE_ERROR_KEY = 'scraper.challengeRequired'
+const CATALOG_TOKEN_STORAGE_KEY = 'auralogic_catalog_token'
+const CATALOG_PATH_PREFIX = '/api/user/products'
+
+export interface CatalogChallenge {
+  challenge: string
+  difficulty: number
+  expires_at: string
+}
+
+let pendingSolve: Promise<string> | null = null
+
+// isCatalogRequest 只给目录接口附加令牌，避免跨域部署时其他请求的预检失败
+export function isCatalogRequest(url?: string) {
+  return Boolean(url && url.includes(CATALOG_PATH_PREFIX))
+}
+
+export function getCatalogToken(): string | undefined {
+  if (typeof window === 'undefined') return undefined
+  try {
+    const stored = JSON.parse(window.sessionStorage?.getItem(CATALOG_TOKEN_STORAGE_KEY) || 'null')
+    if (!stored?.token || Date.parse(stored.expiresAt) <= Date.now()) return undefined
+    return stored.token
+  } catch {
+    return undefined
+  }
+}
+
+function leadingZeroBits(bytes: Uint8Array) {
+  let count = 0
+  for (const byte of bytes) {
+    if (byte === 0) {
+      count += 8
+      continue
+    }
+    return count + Math.clz32(byte) - 24
+  }
+  return count
+}
+
+async function solveCatalogChallenge(challenge: CatalogChallenge) {
+  const encoder = new TextEncoder()
+  for (let nonce = 0; ; nonce++) {
+    const token = `${challenge.challenge}:${nonce}`
+    const digest = await crypto.subtle.digest('SHA-256', encoder.encode(token))
+    if (leadingZeroBits(new Uint8Array(digest)) >= challenge.difficulty) {
+      return token
+    }
+  }
+}
+
+// refreshCatalogToken 获取并解出新挑战，并发请求共用同一次求解
+export function refreshCatalogToken(fetchChallenge: () => Promise<CatalogChallenge>) {
+  if (!pendingSolve) {
+    pendingSolve = fetchChallenge()
+      .then(async (challenge) => {
+        const token = await solveCatalogChallenge(challenge)
+        try {
+          window.sessionStorage?.setItem(
+            CATALOG_TOKEN_STORAGE_KEY,
+            JSON.stringify({ token, expiresAt: challenge.expires_at })
+          )
+        } catch {
+          // ignore storage access failures
+        }
+        return token
+      })
+      .finally(() => {
+        pendingSolve = null
+      })
+  }
+  return pendingSolve
+}
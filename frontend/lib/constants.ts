@@ -15,12 +15,17 @@ export const PERMISSIONS = [
   // 订单权限
   { value: 'order.view', labelKey: 'permOrderView' as const, category: 'order' },
   { value: 'order.view_privacy', labelKey: 'permOrderViewPrivacy' as const, category: 'order' },
+  { value: 'order.view_email', labelKey: 'permOrderViewEmail' as const, category: 'order' },
+  { value: 'order.view_phone', labelKey: 'permOrderViewPhone' as const, category: 'order' },
+  { value: 'order.view_address', labelKey: 'permOrderViewAddress' as const, category: 'order' },
+  { value: 'order.view_payment', labelKey: 'permOrderViewPayment' as const, category: 'order' },
   { value: 'order.edit', labelKey: 'permOrderEdit' as const, category: 'order' },
   { value: 'order.delete', labelKey: 'permOrderDelete' as const, category: 'order' },
   { value: 'order.status_update', labelKey: 'permOrderStatusUpdate' as const, category: 'order' },
   { value: 'order.refund', labelKey: 'permOrderRefund' as const, category: 'order' },
   { value: 'order.assign_tracking', labelKey: 'permOrderAssignTracking' as const, category: 'order' },
   { value: 'order.request_resubmit', labelKey: 'permOrderRequestResubmit' as const, category: 'order' },
+  { value: 'order.automation', labelKey: 'permOrderAutomation' as const, category: 'order' },
 
   // 商品权限
   { value: 'product.view', labelKey: 'permProductView' as const, category: 'product' },
@@ -36,6 +41,11 @@ export const PERMISSIONS = [
   { value: 'ticket.view', labelKey: 'permTicketView' as const, category: 'ticket' },
   { value: 'ticket.reply', labelKey: 'permTicketReply' as const, category: 'ticket' },
   { value: 'ticket.status_update', labelKey: 'permTicketStatusUpdate' as const, category: 'ticket' },
+  { value: 'ticket.automation', labelKey: 'permTicketAutomation' as const, category: 'ticket' },
+
+  // 退货权限
+  { value: 'return.view', labelKey: 'permReturnView' as const, category: 'return' },
+  { value: 'return.manage', labelKey: 'permReturnManage' as const, category: 'return' },
 
   // 序列号权限
   { value: 'serial.view', labelKey: 'permSerialView' as const, category: 'serial' },
@@ -58,11 +68,15 @@ export const PERMISSIONS = [
   { value: 'admin.edit', labelKey: 'permAdminEdit' as const, category: 'admin' },
   { value: 'admin.delete', labelKey: 'permAdminDelete' as const, category: 'admin' },
   { value: 'admin.permission', labelKey: 'permAdminPermission' as const, category: 'admin' },
+  { value: 'admin.activity', labelKey: 'permAdminActivity' as const, category: 'admin' },
+  { value: 'admin.approve', labelKey: 'permAdminApprove' as const, category: 'admin' },
 
   // 系统权限
   { value: 'system.config', labelKey: 'permSystemConfig' as const, category: 'system' },
   { value: 'system.logs', labelKey: 'permSystemLogs' as const, category: 'system' },
   { value: 'api.manage', labelKey: 'permApiManage' as const, category: 'system' },
+  { value: 'security.view', labelKey: 'permSecurityView' as const, category: 'system' },
+  { value: 'security.manage', labelKey: 'permSecurityManage' as const, category: 'system' },
 
   // 支付方式权限
   { value: 'payment_method.view', labelKey: 'permPaymentMethodView' as const, category: 'payment' },
@@ -83,6 +97,7 @@ export const PERMISSION_CATEGORIES = ['order', 'product', 'serial', 'user', 'tic
 export const CATEGORY_LABEL_KEYS: Record<string, string> = {
   order: 'permCategoryOrder',
   product: 'permCategoryProduct',
+  return: 'permCategoryReturn',
   serial: 'permCategorySerial',
   user: 'permCategoryUser',
   ticket: 'permCategoryTicket',
@@ -99,6 +114,7 @@ export const CATEGORY_LABEL_KEYS: Record<string, string> = {
 export const PERMISSIONS_BY_CATEGORY: Record<string, typeof PERMISSIONS> = {
   order: PERMISSIONS.filter(p => p.category === 'order'),
   product: PERMISSIONS.filter(p => p.category === 'product'),
+  return: PERMISSIONS.filter(p => p.category === 'return'),
   serial: PERMISSIONS.filter(p => p.category === 'serial'),
   user: PERMISSIONS.filter(p => p.category === 'user'),
   ticket: PERMISSIONS.filter(p => p.category === 'ticket'),
@@ -0,0 +1,77 @@
+// Cookie 会话：后端开启 security.session_cookie 时，Web 前端以 X-Auth-Mode: cookie 登录，
+// JWT 写入 HttpOnly Cookie，写请求通过 CSRF 请求头回传令牌
+
+import {
+  AUTH_MODE_HEADER,
+  type CSRFToken,
+  getCSRFToken,
+  isCookieSession,
+  isSessionCookieEnabled,
+  setCSRFToken,
+} from './auth'
+import { TOKEN_ISSUING_PATHS } from './backend-proxy'
+
+export const COOKIE_AUTH_MODE = 'cookie'
+export const CSRF_INVALID_ERROR_KEY = 'auth.csrfInvalid'
+export const CSRF_TOKEN_PATH = '/api/user/auth/csrf-token'
+
+let pendingFetch: Promise<CSRFToken | null> | null = null
+
+function requestPath(url?: string) {
+  return String(url || '').split('?')[0]
+}
+
+export function isUnsafeMethod(method?: string) {
+  const normalized = String(method || 'get').toUpperCase()
+  return normalized !== 'GET' && normalized !== 'HEAD' && normalized !== 'OPTIONS'
+}
+
+// shouldUseCookieSession 已处于 Cookie 会话，或开启 Cookie 会话时发起登录类请求
+export function shouldUseCookieSession(url?: string) {
+  if (typeof window === 'undefined') return false
+  if (isCookieSession()) return true
+  return isSessionCookieEnabled() && TOKEN_ISSUING_PATHS.has(requestPath(url))
+}
+
+// readIssuedCSRFToken 登录类响应以 Cookie 会话签发时返回 CSRF 令牌，否则返回 undefined
+export function readIssuedCSRFToken(url: string | undefined, payload: any): string | undefined {
+  if (!TOKEN_ISSUING_PATHS.has(requestPath(url))) return undefined
+  const data = payload?.data
+  if (data?.token_type !== 'Cookie') return undefined
+  return typeof data.csrf_token === 'string' ? data.csrf_token : ''
+}
+
+// ensureCSRFToken 返回内存中的 CSRF 令牌，页面刷新后从后端重新获取，并发请求共用同一次获取
+export function ensureCSRFToken(
+  fetchToken: () => Promise<{ csrf_token?: string; header_name?: string }>
+): Promise<CSRFToken | null> {
+  const current = getCSRFToken()
+  if (current) return Promise.resolve(current)
+  if (!pendingFetch) {
+    pendingFetch = fetchToken()
+      .then((data) => {
+        setCSRFToken(data?.csrf_token, data?.header_name)
+        return getCSRFToken()
+      })
+      .catch(() => null)
+      .finally(() => {
+        pendingFetch = null
+      })
+  }
+  return pendingFetch
+}
+
+// applyCookieSessionHeaders 给 fetch 请求附加会话模式与 CSRF 请求头
+export async function applyCookieSessionHeaders(
+  headers: Headers,
+  method: string,
+  fetchToken: () => Promise<{ csrf_token?: string; header_name?: string }>
+) {
+  if (!isCookieSession()) return
+  headers.set(AUTH_MODE_HEADER, COOKIE_AUTH_MODE)
+  if (!isUnsafeMethod(method)) return
+  const csrf = await ensureCSRFToken(fetchToken)
+  if (csrf) {
+    headers.set(csrf.headerName, csrf.token)
+  }
+}
@@ -36,6 +36,8 @@ export const enTranslations = {
     noData: 'No data',
     prevPage: 'Previous',
     nextPage: 'Next',
+    demoModeBadge: 'Demo',
+    demoModeNotice: 'Demo mode: emails, SMS and external requests are disabled',
     pageInfo: 'Page {page} of {totalPages}',
     refresh: 'Refresh',
     verifying: 'Verifying...',
@@ -95,6 +97,7 @@ export const enTranslations = {
     productCenter: 'Products',
     cart: 'Cart',
     myOrders: 'My Orders',
+    myQuotes: 'My Quotes',
     serialVerify: 'Serial Verify',
     profile: 'Profile',
     accountSettings: 'Account Settings',
@@ -120,6 +123,17 @@ export const enTranslations = {
     dark: 'Dark',
     system: 'System',
     toggleTheme: 'Toggle theme',
+    bizError: {
+      'theme.notFound': 'Theme not found',
+      'theme.slugInvalid': 'Theme slug may only contain lowercase letters, digits and hyphens',
+      'theme.nameRequired': 'Theme name is required',
+      'theme.slugAlreadyExists': 'Theme slug {slug} already exists',
+      'theme.colorInvalid': 'Invalid theme value for {field}: {value}',
+      'theme.typographyInvalid': 'Invalid theme value for {field}: {value}',
+      'theme.emailTemplateInvalid': 'Invalid template syntax in {field}',
+      'theme.importInvalid': 'Invalid theme file',
+      'theme.importVersionUnsupported': 'Unsupported theme file version: {version}',
+    },
   },
 
   language: {
@@ -218,6 +232,13 @@ export const enTranslations = {
       'auth.invalidPhoneFormat': 'Invalid phone number format',
       'auth.captchaRequired': 'Captcha is required',
       'auth.captchaFailed': 'Captcha verification failed',
+      'auth.accountLocked': 'Too many failed login attempts, account is temporarily locked',
+      'auth.ipBanned': 'Access from your IP address has been blocked',
+      'scraper.challengeRequired': 'Browser verification failed, please refresh the page and try again',
+      'auth.csrfInvalid': 'Security token expired, please refresh the page and try again',
+      'auth.emailVerificationRequired': 'Please verify your email before placing orders',
+      'auth.emailChangeLinkInvalid': 'Email change link is invalid or has expired',
+      'auth.invalidDisplayCurrency': 'Invalid display currency code',
     },
     // Form validation
     invalidEmail: 'Invalid email format',
@@ -236,6 +257,16 @@ export const enTranslations = {
       'We have sent a verification email to {email}. Please check your inbox and click the link to activate your account.',
     backToLogin: 'Back to Login',
     retryVerification: 'Retry Verification',
+    revertEmailTitle: 'Restore Your Email Address',
+    revertEmailDesc:
+      'If you did not change your account email, restore the previous address now.',
+    revertEmailConfirm: 'Restore my email',
+    revertEmailSuccess: 'Email Restored',
+    revertEmailSuccessDesc:
+      'Your account email is {email} again. Reset your password now to secure your account.',
+    revertEmailResetPassword: 'Reset Password',
+    revertEmailFailed: 'Failed to restore email',
+    revertEmailMissingLink: 'The restore link is incomplete. Open it again from the email.',
     resendVerification: 'Resend Verification Email',
     resend: 'Resend',
     sending: 'Sending...',
@@ -305,6 +336,11 @@ export const enTranslations = {
   },
 
   product: {
+    bulkPricing: 'Bulk pricing',
+    minOrderQuantityHint: 'Minimum {min} pcs',
+    quantityIncrementHint: 'Sold in packs of {increment}',
+    maxOrderQuantityHint: 'Up to {max} pcs per order',
+    bulkPricingFrom: '{quantity}+ pcs',
     products: 'Products',
     productList: 'Product List',
     productDetail: 'Product Detail',
@@ -360,6 +396,9 @@ export const enTranslations = {
     views: 'Views',
     sales: 'Sales',
     save: 'Save',
+    approximatePriceHint: 'Approximate price, charged in the original currency',
+    priceOnRequest: 'Price on request',
+    priceOnRequestHint: 'Submit a quote request and we will reply with pricing for your quantity',
     blindBoxAttribute: 'Blind Box Random Attribute',
     blindBoxDesc:
       'This product contains random attributes. After purchase, the system will randomly assign them, adding a surprise!',
@@ -412,6 +451,29 @@ export const enTranslations = {
       'product.stockNegative': 'Stock cannot be negative',
       'product.quantityInvalid': 'Quantity must be greater than 0',
       'product.stockInsufficient': 'Insufficient product stock, available: {available}',
+      'product.metaTitleTooLong': 'Meta title cannot exceed 255 characters',
+      'product.metaDescriptionTooLong': 'Meta description cannot exceed 500 characters',
+      'product.ogImageInvalid': 'OG image must be an http(s) URL or a site-relative path',
+      'product.shipWithinDaysInvalid': 'Ship-within days must be between 0 and 365',
+      'product.autoCancelHoursInvalid': 'Auto-cancel hours must be between 0 and {max}',
+      'productPrice.scheduleNotFound': 'Price schedule not found',
+      'productPrice.originalPriceNegative': 'Original price cannot be less than 0',
+      'productPrice.effectiveAtInPast': 'Effective time must be in the future',
+      'productPrice.scheduleNotPending': 'Only pending price schedules can be cancelled (current status: {status})',
+      'product.shippingCountryInvalid': 'Unknown country code: {country}',
+      'product.hsCodeInvalid': 'HS code must be 6, 8 or 10 digits',
+      'product.dimensionsInvalid': 'Weight and dimensions cannot be negative',
+      'product.customsValueInvalid': 'Declared value and weight cannot be negative',
+      'product.priceTiersTooMany': 'At most {max} price tiers are allowed',
+      'product.orderQuantityRulesInvalid':
+        'Order quantity rules are invalid: the maximum cannot be below the minimum, and the minimum must be a multiple of the increment',
+      'product.orderQuantityBelowMin': '{product} requires a minimum quantity of {min}',
+      'product.orderQuantityIncrement': '{product} is sold in multiples of {increment}',
+      'product.orderQuantityAboveMax': '{product} allows at most {max} per order',
+      'product.priceTierQuantityInvalid': 'Tier quantities must be greater than 1 and unique',
+      'product.priceTierPriceInvalid':
+        'Tier prices cannot exceed the base price or the price of a lower-quantity tier',
+      'product.priceOnRequest': '{product} is price on request, please submit a quote request',
     },
   },
 
@@ -423,6 +485,8 @@ export const enTranslations = {
     orderStatus: 'Order Status',
     orderTime: 'Order Time',
     orderAmount: 'Order Amount',
+    displayAmountApproximate: 'Approximate amount, charged in the original currency',
+    exchangeRateStale: 'rate may be outdated',
     createOrder: 'Create Order',
     cancelOrder: 'Cancel Order',
     refundOrder: 'Refund',
@@ -518,7 +582,74 @@ export const enTranslations = {
     updatedAt: 'Updated At',
     remarks: 'Remarks',
     userRemark: 'User Remark',
-    adminRemark: 'Admin Remark',
+    orderNotes: 'Internal Notes',
+    orderNotesEmpty: 'No internal notes yet',
+    orderNotePlaceholder: 'Add an internal note (only visible to administrators)',
+    orderNoteAdd: 'Add Note',
+    orderNoteSaveFailed: 'Failed to save note',
+    orderNoteSystem: 'System',
+    orderNotePinned: 'Pinned',
+    orderNotePin: 'Pin',
+    orderNoteUnpin: 'Unpin',
+    orderNoteMention: 'Notify administrators',
+    orderNoteMentioned: 'Mentioned',
+    orderNoteDeleteConfirm: 'Delete this note?',
+    orderNoteSourceCreate: 'Order created',
+    orderNoteSourceMarkPaid: 'Marked as paid',
+    orderNoteSourceComplete: 'Completed',
+    orderNoteSourceResubmit: 'Resubmit requested',
+    orderNoteSourceCancel: 'Cancelled',
+    orderNoteSourceAutoCancel: 'Auto-cancelled',
+    orderNoteSourceRefund: 'Refund',
+    orderNoteSourceRefundConfirm: 'Refund confirmed',
+    orderNoteSourceReturn: 'Return received',
+    orderNoteSourceVirtualRevoke: 'Digital item revoked',
+    orderNoteSourceAutomation: 'Automation',
+    orderNoteSourceCODCollection: 'COD collection',
+    orderNoteSourceLegacy: 'Legacy remark',
+    orderNoteSourceAutoComplete: 'Auto-completed',
+    orderNoteSourceMerge: 'Merged orders',
+    shipmentTracking: 'Shipment Tracking',
+    shipmentTrackingCarrier: 'Carrier',
+    shipmentTrackingDeliveredAt: 'Delivered at',
+    shipmentTrackingNoEvents: 'No tracking events yet',
+    shipmentTrackingStatusPending: 'Awaiting carrier info',
+    shipmentTrackingStatusInTransit: 'In transit',
+    shipmentTrackingStatusOutForDelivery: 'Out for delivery',
+    shipmentTrackingStatusDelivered: 'Delivered',
+    shipmentTrackingStatusException: 'Exception',
+    orderMessages: 'Messages',
+    orderMessagesDesc: 'Questions about this order? Message the seller here.',
+    orderMessagesEmpty: 'No messages yet',
+    orderMessagePlaceholder: 'Write a message...',
+    orderMessageSend: 'Send',
+    orderMessageSendFailed: 'Failed to send message',
+    orderMessageYou: 'You',
+    orderMessageSeller: 'Seller',
+    orderMessageCustomer: 'Customer',
+    orderMessageEscalate: 'Open a ticket',
+    orderMessageEscalateConfirm:
+      'Move this conversation to a support ticket? The order will be shared with the ticket and the conversation continues there.',
+    orderMessageEscalateFailed: 'Failed to create ticket',
+    orderMessageEscalated: 'This conversation continues in ticket #{ticketNo}',
+    orderMessageViewTicket: 'View ticket',
+    claimOrder: 'Claim Order',
+    claimOrderDesc:
+      'Link an order placed with your verified email on another platform to this account.',
+    claimOrderPlaceholder: 'Enter the order number',
+    claimOrderConfirm: 'Claim',
+    claimOrderSuccess: 'Order added to your account',
+    claimOrderFailed: 'Failed to claim order',
+    exportOrders: 'Export',
+    exportOrdersDesc:
+      'Download your orders placed in the selected date range, including items, amounts and statuses.',
+    exportStartDate: 'From',
+    exportEndDate: 'To',
+    exportFormat: 'Format',
+    exportRangeHint: 'Up to 366 days per export. Dates are in UTC.',
+    exportOrdersConfirm: 'Download',
+    exportOrdersSuccess: 'Export downloaded',
+    exportOrdersFailed: 'Failed to export orders',
     trackingInfo: 'Tracking Info',
     trackingNo: 'Tracking No.',
     privacyProtected: 'Privacy Protected',
@@ -553,6 +684,10 @@ export const enTranslations = {
     paymentMethodListLoading: 'Loading available payment methods. Please wait.',
     paymentCardPendingHint:
       'A payment method is selected, but no payment card content is available to display yet.',
+    stripePayNow: 'Pay now',
+    stripePaymentSubmitted: 'Payment submitted. The order will update once Stripe confirms it.',
+    stripePaymentFailed: 'Payment failed. Please check your card details and try again.',
+    stripeLoadFailed: 'Failed to load the Stripe payment form. Please refresh and try again.',
     noPaymentMethods: 'No payment methods available',
     noPaymentMethodsHint:
       'No payment methods are currently available for this order. Please try again later or contact the admin.',
@@ -578,6 +713,21 @@ export const enTranslations = {
     virtualProductShipped: 'Virtual product shipped, click to view',
     delivered: 'Delivered',
     deliveryTime: 'Delivery Time',
+    virtualStockRevoked: 'Revoked',
+    virtualStockRevokedAt: 'Revoked At',
+    virtualStockFirstViewedAt: 'First Viewed',
+    virtualStockNotViewed: 'Not viewed yet',
+    resendVirtualEmail: 'Email Me the Codes',
+    resendVirtualEmailSuccess: 'Email sent. Please check your inbox.',
+    resendVirtualEmailFailed: 'Failed to send email',
+    redeliverVirtualStock: 'Replace Code',
+    redeliverVirtualStockTitle: 'Replace this code?',
+    redeliverVirtualStockDesc:
+      'The current code is revoked as compromised and a new code from the same inventory is delivered to this order. The buyer is notified by email.',
+    redeliverVirtualStockReason: 'Reason (optional)',
+    redeliverVirtualStockSuccess: 'Code replaced',
+    redeliverVirtualStockFailed: 'Failed to replace code',
+    revealVirtualStockFailed: 'Failed to show code',
     totalCodes: '{count} codes in total',
     copiedToClipboard: 'Copied to clipboard',
 
@@ -632,6 +782,45 @@ export const enTranslations = {
     markComplete: 'Mark Complete',
     markCompleteTitle: 'Mark Order as Complete',
     remarkOptional: 'Remark (Optional)',
+    recordReturn: 'Record Return',
+    returnTitle: 'Record Returned Items',
+    returnDesc:
+      'Enter the quantity received back for each item. Restocked units are added back to inventory.',
+    returnRemaining: '{n} returnable',
+    returnRestock: 'Restock returned items to inventory',
+    returnInvalidateSerials: 'Invalidate serial numbers of returned items',
+    returnConfirm: 'Record Return',
+    returnRecorded: 'Return recorded',
+    mergeOrders: 'Merge Orders',
+    mergeOrdersTitle: 'Merge Orders for Shipment',
+    mergeOrdersDesc:
+      'Pending orders from the same buyer and receiver are merged into this order and ship together. Merged orders are closed.',
+    mergeOrdersEmpty: 'No other pending orders can be merged into this order',
+    mergeOrdersConfirm: 'Merge',
+    mergeOrdersDone: '{n} order(s) merged',
+    mergedIntoOrder: 'This order was merged into',
+    mergedFromOrders: 'Merged from',
+    mergedIntoNotice: 'This order was merged into order {order_no} and ships with it.',
+    viewMergedOrder: 'View order',
+    refunds: 'Refunds',
+    partialRefund: 'Partial Refund',
+    partialRefundDesc:
+      'Choose items and amounts to refund. Leave an item amount empty to refund its paid price by quantity; leave the total empty to use the item subtotal.',
+    refundableAmount: 'Refundable',
+    refundedAmount: 'Refunded',
+    refundPendingAmount: 'Pending',
+    refundTotalAmount: 'Refund Amount',
+    refundItemQuantity: 'Qty',
+    refundItemAmount: 'Amount',
+    refundItemRemaining: '{n} refundable',
+    refundFull: 'Full',
+    refundCreated: 'Refund submitted',
+    confirmRefundRecord: 'Confirm Received',
+    noRefunds: 'No refunds yet',
+    refundStatusProcessing: 'Processing',
+    refundStatusPending: 'Pending',
+    refundStatusSucceeded: 'Succeeded',
+    refundStatusFailed: 'Failed',
     remarkPlaceholder: 'Order processing remark',
     confirmComplete: 'Confirm Complete',
     cancelOrderTitle: 'Cancel Order',
@@ -674,6 +863,14 @@ export const enTranslations = {
     shippingUpdated: 'Shipping info updated',
     resubmitRequested: 'Resubmission requested',
     orderMarkedPaid: 'Order marked as paid',
+    sandboxOrder: 'Sandbox',
+    tags: 'Tags',
+    filterTag: 'Tags',
+    tagFilterPlaceholder: 'e.g. vip, wholesale',
+    addTagPlaceholder: 'Add tag',
+    removeTag: 'Remove tag',
+    simulateSandboxPayment: 'Simulate payment',
+    sandboxPaymentSimulated: 'Sandbox payment simulated',
     priceUpdated: 'Order price updated',
     priceUpdatedAndPaymentReset: 'Order price updated and payment cache/context reset',
     virtualDelivered: 'Virtual products delivered',
@@ -703,6 +900,8 @@ export const enTranslations = {
     paymentCacheEmpty: 'Not Cached',
     bizError: {
       'order.invalidOrderID': 'Invalid order ID format',
+      'order.virtualEmailUnavailable': 'Digital item email is unavailable for this order',
+      'order.virtualProductsNotDelivered': 'Digital items have not been delivered yet',
       'order.invalidRequestParameters': 'Invalid request parameters',
       'order.trackingNumberLengthInvalid':
         'Tracking number length must be between {min} and {max} characters',
@@ -741,9 +940,16 @@ export const enTranslations = {
       'order.batchLimitExceeded': 'You can process at most {max} orders at once',
       'order.itemsEmpty': 'Order items cannot be empty',
       'order.tooManyItems': 'Order items cannot exceed {max}',
+      'order.tagInvalid': 'Tags must be at most {max} characters and cannot contain commas',
+      'order.tooManyTags': 'An order can have at most {max} tags',
       'order.skuEmpty': 'Product SKU cannot be empty',
       'order.quantityInvalid': 'Quantity must be greater than 0',
       'order.quantityExceeded': 'Quantity cannot exceed {max}',
+      'order_import.duplicateHeader': 'Duplicate header: {header}',
+      'order_import.missingHeader': 'Missing required header: {header}',
+      'order_import.empty': 'No data rows found in import file',
+      'order_import.tooManyRows': 'Too many rows to import (max {max}). Please split the file.',
+      'order_import.jobNotFound': 'Import job not found',
       'order.attributesTooMany': 'Product attributes cannot exceed {max} keys',
       'order.productNotAvailable': 'Product is not available',
       'order.productNotFound': 'Product {sku} does not exist',
@@ -779,6 +985,36 @@ export const enTranslations = {
       'order.externalOrderIDTooLong': 'External order ID length cannot exceed {max} characters',
       'order.platformNameTooLong': 'Platform name length cannot exceed {max} characters',
       'order.orderRemarkTooLong': 'Order remark length cannot exceed {max} characters',
+      'order.receiverPhoneInvalidForCountry': 'Invalid phone number for {country}, e.g. {example}',
+      'order.postcodeInvalidForCountry': 'Invalid postal code for {country}, e.g. {example}',
+      'order.shippingRestricted': '{name} cannot be shipped to {country}',
+      'order.returnStatusInvalid': 'Only shipped orders can record returns',
+      'order.returnItemsRequired': 'Select at least one item to return',
+      'order.returnItemInvalid': 'Invalid return item',
+      'order.returnQuantityExceeded': 'Return quantity for {sku} exceeds the purchased quantity (remaining: {remaining})',
+      'order.mergeSourcesRequired': 'Select at least one other order to merge',
+      'order.mergeTooManySources': 'At most {max} orders can be merged at once',
+      'order.mergeStatusInvalid': 'Order {order_no} is not pending shipment and cannot be merged',
+      'order.mergeBuyerMismatch': 'Order {order_no} belongs to a different buyer',
+      'order.mergeReceiverMismatch': 'Order {order_no} ships to a different receiver',
+      'order.mergeIncompatible': 'Order {order_no} has a different store, currency or sandbox mode',
+      'order.mergeVirtualItems': 'Order {order_no} contains virtual items and cannot be merged',
+      'order.mergePromoCode': 'Order {order_no} used a promo code. Merge into that order instead',
+      'order.mergeSerialGenerationBusy': 'Serial numbers of order {order_no} are not ready yet',
+      'order.mergeRefunded': 'Order {order_no} has refunds and cannot be merged',
+      'order.mergePaymentMismatch': 'Order {order_no} was paid with a different payment method',
+      'order.refundNothingRefundable': 'This order has no refundable amount left',
+      'order.refundItemInvalid': 'Invalid refund item',
+      'order.refundNotFound': 'Refund not found',
+      'order.exportRangeInvalid': 'Date range must be between 1 and {max} days',
+      'order.exportTooManyRows':
+        'Too many orders in this range (at most {max} per export), please narrow the dates',
+      'order.refundAmountInvalid':
+        'Refund amount must be greater than 0 and not exceed {refundable}',
+      'order.refundItemExceeded':
+        'Refund for {sku} exceeds what was paid (remaining quantity: {quantity}, amount: {amount})',
+      'order.refundConfirmStatusInvalid':
+        'Only pending refunds can be confirmed (current status: {status})',
     },
   },
 
@@ -790,6 +1026,36 @@ export const enTranslations = {
         'You already have {current} payment polling tasks (limit: {max})',
       'payment.pollingGlobalQueueLimitExceeded':
         'Payment polling queue is full (limit: {max}). Please try again later.',
+      'payment.sandboxInvalidOrderStatus':
+        'Only pending payment orders can simulate payment (status: {status})',
+      'payment.sandboxMethodRequired': 'The order has not selected a sandbox payment method',
+      'payment.methodNotAllowedForOrder': 'This payment method is not available for this order',
+      'payment.amountReserveInvalidOrderStatus':
+        'Only pending payment orders can reserve a payment amount (status: {status})',
+      'payment.amountOffsetExhausted':
+        'Too many pending payments with the same amount. Please try again later.',
+      'payment.codVirtualNotSupported':
+        'Cash on delivery is not available for orders with digital items',
+      'payment.stripeNotConfigured': 'Stripe is not configured yet. Please choose another payment method.',
+      'payment.stripeAmountInvalid': 'Order amount is too small to pay with Stripe',
+      'payment_match.importEmpty': 'Import file has no data rows',
+      'payment_match.importMissingHeader': 'Missing required column: {header}',
+      'payment_match.importTooManyRows': 'Import file exceeds {max} rows',
+      'payment_match.invalidAmount': 'Amount must be a positive number',
+      'payment_match.transactionIdRequired': 'Transaction ID is required',
+      'payment_match.transactionIdTooLong': 'Transaction ID is too long',
+      'payment_match.transactionNotFound': 'Transaction not found',
+      'payment_match.transactionNotUnmatched':
+        'This transaction has already been handled (status: {status})',
+      'cod.carrierRequired': 'Carrier is required',
+      'cod.collectionNotFound': 'This order is not a cash on delivery order',
+      'cod.collectionNotPending': 'The collection has already been recorded (status: {status})',
+      'cod.invalidCollectedAmount':
+        'Collected amount must be greater than 0 and must not exceed the amount due',
+      'cod.orderNotShipped':
+        'Collections can only be recorded for shipped orders (status: {status})',
+      'cod.settleEmpty': 'Select at least one collection to settle',
+      'cod.settleTooMany': 'At most {max} collections can be settled at once',
     },
   },
 
@@ -827,6 +1093,12 @@ export const enTranslations = {
     languagePreferenceDesc: 'Choose the interface language.',
     themePreference: 'Theme',
     themePreferenceDesc: 'Choose how the interface looks.',
+    displayCurrencyPreference: 'Display currency',
+    displayCurrencyPreferenceDesc:
+      'Show approximate prices in another currency. You are still charged in the original currency.',
+    displayCurrencyDefault: 'Original',
+    displayCurrencySaveSuccess: 'Display currency updated',
+    displayCurrencySaveFailed: 'Failed to update display currency',
     themeLightDesc: 'Bright and clear look',
     themeDarkDesc: 'Dark look for low-light environments',
     themeSystemDesc: 'Follow your operating system setting',
@@ -876,6 +1148,12 @@ export const enTranslations = {
     securityTip4:
       '• If you suspect your account has been compromised, change your password immediately and contact an administrator',
     bindEmail: 'Bind Email',
+    changeEmail: 'Change Email',
+    changeEmailDesc:
+      'Verify the new address with a code. Your current address can undo the change for 7 days.',
+    newEmail: 'New Email',
+    changeEmailConfirm: 'Change Email',
+    changeEmailSuccess: 'Email changed. A notice was sent to your previous address.',
     bindEmailDesc: 'Bind an email address to your account for email login and notifications',
     emailBindUnavailableHint:
       'Email service is currently unavailable, so email binding cannot be completed right now.',
@@ -1048,6 +1326,8 @@ export const enTranslations = {
       'ticket.imageUploadDisabled': 'Image upload is currently disabled',
       'ticket.imageFileTooLarge': 'Image size cannot exceed {max}MB',
       'ticket.imageFormatUnsupported': 'Unsupported image format',
+      'ticket.tagInvalid': 'Tags must be at most {max} characters and cannot contain commas',
+      'ticket.tooManyTags': 'A ticket can have at most {max} tags',
     },
     items: 'items',
     ticketStatus: {
@@ -1072,6 +1352,10 @@ export const enTranslations = {
     assignAll: 'All',
     assignMe: 'Mine',
     assignUnassigned: 'Unassigned',
+    tags: 'Tags',
+    tagFilterPlaceholder: 'Filter by tag...',
+    addTagPlaceholder: 'Add tag',
+    removeTag: 'Remove tag',
     selectTicket: 'Please select a ticket from the left',
     selectionHint:
       'The list prioritizes open tickets. Select one on the left to continue handling it.',
@@ -1104,6 +1388,25 @@ export const enTranslations = {
     shippedAt: 'Shipped At',
     userRemark: 'User Remark',
     orderNotAccessible: 'Order not found or no access',
+    virtualItems: 'Delivered Codes',
+    virtualItemsMaskedHint: 'Codes are masked. Ask the buyer for the full code if needed.',
+    virtualItemRevoked: 'Revoked',
+    virtualItemFirstViewed: 'First viewed',
+    virtualItemNotViewed: 'Not viewed yet',
+    paymentInfo: 'Payment',
+    paymentMethod: 'Method',
+    paymentSelectedAt: 'Selected At',
+    paymentData: 'Payment Data',
+    paymentRecordPayment: 'Payment received',
+    paymentRecordRefund: 'Refund',
+    shipmentHistory: 'Shipment History',
+    shipmentActionAssignTracking: 'Tracking number assigned',
+    shipmentActionShipped: 'Shipped',
+    shipmentActionDeliverVirtualStock: 'Virtual items delivered',
+    shipmentActionRevokeVirtualStock: 'Code revoked',
+    shipmentActionComplete: 'Completed',
+    shipmentActionCancel: 'Cancelled',
+    shipmentActionReceiveReturn: 'Return received',
     updateSuccess: 'Updated successfully',
     updateFailed: 'Failed to update',
     adminMessagePlaceholder:
@@ -1114,6 +1417,53 @@ export const enTranslations = {
     // Sidebar
     adminPanel: 'Admin Panel',
     backToUser: 'Back to User',
+    globalSearch: 'Search',
+    globalSearchPlaceholder: 'Order no., tracking, email, ticket, SKU or serial...',
+    globalSearchHint: 'Type at least 2 characters',
+    globalSearchEmpty: 'No matches',
+    globalSearchFailed: 'Search failed',
+    globalSearchTypeOrder: 'Orders',
+    globalSearchTypeUser: 'Users',
+    globalSearchTypeTicket: 'Tickets',
+    globalSearchTypeProduct: 'Products',
+    globalSearchTypeSerial: 'Serials',
+    paletteQuickActions: 'Quick actions',
+    paletteRecent: 'Recently handled',
+    paletteAssignedTickets: 'Assigned to me',
+    quickActionPendingShipment: 'Orders awaiting shipment',
+    quickActionCreateProduct: 'Create product',
+    quickActionTickets: 'Open tickets',
+    quickActionReturnRequests: 'Review return requests',
+    quickActionUsers: 'Manage users',
+    quickActionSerials: 'Look up serials',
+    quickActionAnnouncements: 'Manage announcements',
+    quickActionOperationLogs: 'Operation logs',
+    quickActionDashboard: 'Dashboard',
+    notifications: 'Notifications',
+    notificationInbox: 'Inbox',
+    notificationSubscriptions: 'Subscriptions',
+    notificationNone: 'No notifications',
+    notificationMarkAllRead: 'Mark all as read',
+    notificationSubscriptionsDesc:
+      'Pick the events you want to hear about and how. Country filters apply to order events; category filters match the ticket category or, for low stock, the product category.',
+    notificationSubscriptionNone: 'No subscriptions yet',
+    notificationSubscriptionCreate: 'Add subscription',
+    notificationSubscriptionEdit: 'Edit subscription',
+    notificationEvent: 'Event',
+    notificationChannel: 'Channel',
+    notificationChannelEmail: 'Email',
+    notificationChannelTelegram: 'Telegram',
+    notificationChannelInApp: 'In-app',
+    notificationCountries: 'Countries',
+    notificationCountriesHint: 'Comma-separated country codes, e.g. US, DE. Leave empty for all.',
+    notificationCategories: 'Categories',
+    notificationCategoriesHint: 'Comma-separated categories. Leave empty for all.',
+    notificationTelegramChatId: 'Telegram chat ID',
+    notificationTelegramUnavailable: 'Telegram is unavailable until a bot token is configured',
+    notificationEnabled: 'Enabled',
+    notificationDisabled: 'Disabled',
+    notificationSaved: 'Subscription saved',
+    notificationDeleted: 'Subscription deleted',
     dashboard: 'Dashboard',
     productManagement: 'Products',
     inventoryManagement: 'Inventory',
@@ -2553,8 +2903,16 @@ export const enTranslations = {
     orderTrend: 'Order Trend',
     statusDistribution: 'Status Distribution',
     sourceDistribution: 'Source Distribution',
+    cancelReasonDistribution: 'Cancellation Reasons',
+    cancelReasonPaymentTimeout: 'Payment timeout',
+    cancelReasonDraftExpired: 'Draft expired',
+    cancelReasonMerged: 'Merged into another order',
+    cancelReasonManual: 'Manual / other',
     platformDistribution: 'Platform Distribution',
     orderCountryDistribution: 'Order Country Distribution',
+    orderTagDistribution: 'Order Tags',
+    orderTag: 'Tag',
+    paidOrders: 'Paid',
     amountDistribution: 'Amount Distribution',
     topProducts: 'Top Products',
     salesCount: 'Sales',
@@ -2710,6 +3068,9 @@ export const enTranslations = {
     moreProducts: '+{count} more',
     exportSuccess: 'Orders exported to Excel file',
     exportFailed: 'Export failed',
+    exportCustomsDeclarations: 'Export customs declarations',
+    customsDeclaration: 'Customs declaration',
+    customsDeclarationFailed: 'Failed to generate customs declaration',
     usersExportSuccess: 'Users exported successfully',
     productsImportSuccess: 'Product import completed',
     productsExportSuccess: 'Products exported successfully',
@@ -2762,6 +3123,10 @@ export const enTranslations = {
     userFilterCountry: 'Filter: Country (e.g. CN)',
     verified: 'Verified',
     unverified: 'Unverified',
+    emailVerified: 'Email verified',
+    emailVerificationExempt: 'Exempt from email verification',
+    emailVerificationExemptHint: 'This user can log in and place orders without verifying email',
+    emailChangeReverifyHint: 'Changing the email requires the user to verify the new address',
     withPhone: 'With Phone',
     withoutPhone: 'Without Phone',
     viewOrders: 'View Orders',
@@ -2831,12 +3196,17 @@ export const enTranslations = {
     permCategoryPayment: 'Payment Method Permissions',
     permOrderView: 'View Orders',
     permOrderViewPrivacy: 'View Privacy-protected Orders',
+    permOrderViewEmail: 'View Unmasked Email',
+    permOrderViewPhone: 'View Unmasked Phone',
+    permOrderViewAddress: 'View Unmasked Name & Address',
+    permOrderViewPayment: 'View Unmasked Payment Data',
     permOrderEdit: 'Edit Orders',
     permOrderDelete: 'Delete Orders',
     permOrderStatusUpdate: 'Update Order Status',
     permOrderRefund: 'Refund Orders',
     permOrderAssignTracking: 'Assign Tracking Number',
     permOrderRequestResubmit: 'Request Info Resubmission',
+    permOrderAutomation: 'Manage Order Automation',
     permProductView: 'View Products',
     permProductEdit: 'Edit Products',
     permProductDelete: 'Delete Products',
@@ -2846,13 +3216,18 @@ export const enTranslations = {
     permTicketView: 'View Tickets',
     permTicketReply: 'Reply to Tickets',
     permTicketStatusUpdate: 'Update Ticket Status',
+    permTicketAutomation: 'Manage Ticket Automation',
     permAdminCreate: 'Create Admin',
     permAdminEdit: 'Edit Admin',
     permAdminDelete: 'Delete Admin',
     permAdminPermission: 'Assign Admin Permissions',
+    permAdminActivity: 'View Admin Activity',
+    permAdminApprove: 'Approve Dangerous Actions',
     permSystemConfig: 'System Config',
     permSystemLogs: 'View Logs',
     permApiManage: 'API Key Management',
+    permSecurityView: 'View Login Security',
+    permSecurityManage: 'Manage IP Bans & Lockouts',
     permPaymentMethodView: 'View Payment Methods',
     permCategoryPlugin: 'Plugin Permissions',
     permPluginView: 'View Plugins',
@@ -2870,7 +3245,10 @@ export const enTranslations = {
     permAnnouncementEdit: 'Edit Announcements',
     permMarketingView: 'View Marketing',
     permMarketingSend: 'Send Marketing Messages',
+    permCategoryReturn: 'Return Permissions',
     permCategorySerial: 'Serial Permissions',
+    permReturnView: 'View Return Requests',
+    permReturnManage: 'Manage Return Requests',
     permSerialView: 'View Serials',
     permSerialManage: 'Manage Serials',
     permSelectAll: 'Select All',
@@ -3085,6 +3463,58 @@ export const enTranslations = {
     reserveFailed: 'Failed to reserve',
     releaseSuccess: 'Released successfully',
     releaseFailed: 'Failed to release',
+    statusRevoked: 'Revoked',
+    revokeStock: 'Revoke',
+    revokeStockTitle: 'Revoke Delivered Item',
+    revokeStockDesc:
+      'The buyer of order {orderNo} will no longer see this content. Revoked items do not return to stock.',
+    revokeReasonLabel: 'Reason',
+    revokeReasonPlaceholder: 'e.g. chargeback, delivered from the wrong pool',
+    revokeReissueLabel: 'Reissue a replacement from this inventory',
+    revokeNotifyLabel: 'Notify the buyer by email',
+    revealStockContent: 'Show content',
+    hideStockContent: 'Hide content',
+    revealStockFailed: 'Failed to show content',
+    stockRevealLogs: 'Reveal history',
+    stockRevealLogsDesc:
+      'Every time the plain content of stock #{id} is shown, the viewer is recorded here.',
+    noStockRevealLogs: 'This code has not been revealed yet',
+    revealViewerUser: 'Buyer',
+    revealViewerAdmin: 'Admin',
+    revealViewerLink: 'Email link',
+    revealedAtColumn: 'Time',
+    revealViewerColumn: 'Viewer',
+    revokeSuccess: 'Item revoked',
+    revokeFailed: 'Failed to revoke',
+    expiresAtColumn: 'Expires',
+    expiresAtOptionalLabel: 'Expiry (optional)',
+    expiresAtHint:
+      'Leave empty for no expiry. Expired items are skipped during delivery and marked invalid automatically.',
+    stockExpired: 'Expired',
+    stockExpiringSoon: 'Expiring soon',
+    batchExpiryTitle: 'Batch Expiry',
+    batchExpiryDesc: 'Set the expiry for all unsold items in batch {batchNo}',
+    batchExpiryUpdated: 'Expiry updated for {count} items',
+    batchExpiryFailed: 'Failed to update batch expiry',
+    expiryReportTitle: 'Expiring Stock',
+    expiryReportDesc: 'Unsold items expiring within {days} days, estimated value {value}',
+    expiryReportExpiring: 'Expiring',
+    expiryReportExpired: 'Expired',
+    expiryReportEarliest: 'Earliest Expiry',
+    expiryReportValue: 'Estimated Value',
+    transferStockBtn: 'Transfer Stock',
+    transferStockTitle: 'Transfer Stock',
+    transferStockDesc:
+      'Move available items to another inventory. Items keep their batch numbers. Leave filters empty to match all available items.',
+    transferTargetLabel: 'Target Inventory',
+    transferTargetPlaceholder: 'Select a static inventory',
+    transferRemarkKeywordLabel: 'Remark Contains',
+    transferQuantityLabel: 'Quantity',
+    transferQuantityHint: 'Leave empty or 0 to move all matching items (up to 10000)',
+    transferReasonLabel: 'Reason (optional)',
+    transferHistoryTitle: 'Recent Transfers',
+    transferSuccess: 'Transferred {count} items',
+    transferFailed: 'Transfer failed',
     importSuccessCount: 'Successfully imported {count} items',
 
     // Serial Management
@@ -3151,13 +3581,66 @@ export const enTranslations = {
     keyNamePlaceholder: 'Third-party Platform A',
     platformId: 'Platform ID *',
     platformIdPlaceholder: 'platform_a',
+    apiKeyWebhookUrl: 'Webhook URL (optional)',
+    apiKeyWebhookUrlHint:
+      'Receives order events such as order.draft_expired for orders created by this platform.',
+    allowDuplicateExternalOrders: 'Allow duplicate external order IDs',
+    allowDuplicateExternalOrdersHint:
+      'By default, creating a draft with an external_order_id already used on this platform returns the existing order. Enable to always create a new order.',
     rateLimit: 'Rate Limit (per hour)',
     scopesRequired: 'Scopes *',
     platform: 'Platform',
     rateLimitDisplay: '{count}/hr',
+    burstLimit: 'Burst',
+    burstLimitHint:
+      'Requests that may be sent at once before the hourly rate applies. Leave 0 to allow one minute of the hourly rate.',
+    rateLimitBurstDisplay: 'burst {count}',
+    apiKeyUsage: 'Usage',
+    apiKeyUsageTitle: 'API Key Usage · {name}',
+    apiKeyUsageRequests: 'Requests (24h)',
+    apiKeyUsageThrottled: 'Throttled (24h)',
+    apiKeyUsageRemaining: 'Available now',
+    apiKeyUsageHour: 'Hour',
+    apiKeyUsageEmpty: 'No requests in the last 24 hours',
     apiKeyCreated: 'API key created, please keep it safe',
     apiSecretOnce: 'API Secret (shown only once)',
     confirmDeleteApiKey: 'Are you sure you want to delete this API key?',
+    webhooks: 'Webhooks',
+    webhooksDesc: 'Push signed order and ticket events to external systems such as an ERP',
+    webhookCreate: 'Add Webhook',
+    webhookEdit: 'Edit Webhook',
+    webhookName: 'Name',
+    webhookUrl: 'URL',
+    webhookEvents: 'Events',
+    webhookAllEvents: 'All events',
+    webhookDescription: 'Description',
+    webhookPlatform: 'Source platform',
+    webhookPlatformHint:
+      'Optional. When set, only order events for orders created via the API from this platform (source_platform) are delivered; use it to push status changes back to a partner platform.',
+    webhookEnabled: 'Enabled',
+    webhookDisabled: 'Disabled',
+    webhookNone: 'No webhooks yet',
+    webhookSaved: 'Webhook saved',
+    webhookDeleted: 'Webhook deleted',
+    webhookConfirmDelete: 'Delete this webhook and its delivery log?',
+    webhookSecretOnce: 'Signing secret (shown only once)',
+    webhookSecretHint:
+      'Verify the X-AuraLogic-Signature header: t is the timestamp, v1 is the HMAC-SHA256 of "timestamp.body" signed with this secret',
+    webhookRotateSecret: 'Rotate secret',
+    webhookConfirmRotate: 'The current secret stops working immediately. Continue?',
+    webhookTest: 'Send test',
+    webhookTestSucceeded: 'Test event delivered',
+    webhookTestFailed: 'Test event failed: {error}',
+    webhookDeliveries: 'Delivery log',
+    webhookNoDeliveries: 'No deliveries yet',
+    webhookAttempts: 'Attempts',
+    webhookNextAttempt: 'Next retry',
+    webhookRedeliver: 'Redeliver',
+    webhookRedelivered: 'Redelivered',
+    webhookStatusPending: 'Retrying',
+    webhookStatusSucceeded: 'Delivered',
+    webhookStatusFailed: 'Failed',
+    webhookLastDelivery: 'Last delivery',
     apiKeyDeleted: 'API key deleted',
 
     // System Logs
@@ -3181,6 +3664,157 @@ export const enTranslations = {
     failedSms: 'Failed SMS',
     smsPhone: 'Phone',
     smsLogProvider: 'Provider',
+    orderBulkImport: 'Bulk Import Orders',
+    orderCodeLookup: 'Find by code',
+    orderCodeLookupDesc:
+      'Paste a code or serial number a customer sent to find the order and buyer. Every lookup is logged.',
+    orderCodeLookupPlaceholder: 'Code or serial number',
+    orderCodeLookupSearch: 'Search',
+    orderCodeLookupNoMatch: 'No order uses this code',
+    orderCodeLookupFailed: 'Lookup failed',
+    orderCodeLookupSourceStock: 'Virtual code',
+    orderCodeLookupSourceSerial: 'Serial number',
+    orderCodeLookupNotDelivered: 'Not delivered to any order',
+    orderCodeLookupItemStatus: 'Item status: {status}',
+    orderBulkImportDesc:
+      'Create orders from a CSV or Excel file, one row per item. Rows sharing an Order Ref become one order; orders already imported with the same ref are skipped.',
+    orderBulkImportNotify: 'Notify customers',
+    orderBulkImportNotifyHint:
+      'Send order emails to customers for imported orders. Usually off when migrating history.',
+    orderBulkImportUpload: 'Upload File',
+    orderBulkImportFileFormatError: 'Only .csv or .xlsx files are supported',
+    orderBulkImportStatusQueued: 'Queued',
+    orderBulkImportStatusRunning: 'Importing',
+    orderBulkImportStatusCompleted: 'Completed',
+    orderBulkImportStatusFailed: 'Failed',
+    orderBulkImportProgress:
+      'Processed {processed}/{total} orders: {created} created, {skipped} skipped, {errors} errors',
+    orderBulkImportRow: 'Row',
+    orderBulkImportOrderRef: 'Order Ref',
+    orderBulkImportError: 'Error',
+    orderBulkImportAnother: 'Import Another File',
+    paymentMatching: 'Payment Matching',
+    paymentMatchingDesc:
+      'Match bank or crypto transfers that arrived with a wrong amount or memo to pending orders, then mark the order paid.',
+    paymentMatchAddTransaction: 'Add Transaction',
+    paymentMatchImport: 'Import CSV',
+    paymentMatchImportResult:
+      'Imported {created}, skipped {duplicates} duplicates, {errors} errors',
+    paymentMatchTransactions: 'Incoming Transactions',
+    paymentMatchTransactionId: 'Transaction ID',
+    paymentMatchChannel: 'Channel',
+    paymentMatchAmount: 'Amount',
+    paymentMatchCurrency: 'Currency',
+    paymentMatchMemo: 'Memo',
+    paymentMatchPayer: 'Payer',
+    paymentMatchReceivedAt: 'Received At',
+    paymentMatchStatusAll: 'All',
+    paymentMatchStatusUnmatched: 'Unmatched',
+    paymentMatchStatusMatched: 'Matched',
+    paymentMatchStatusIgnored: 'Ignored',
+    paymentMatchSelectHint: 'Select a transaction to see suggested orders',
+    paymentMatchSuggestions: 'Suggested Orders',
+    paymentMatchNoSuggestions: 'No likely matches. Search pending orders below.',
+    paymentMatchPendingOrders: 'Pending Orders',
+    paymentMatchSearchOrder: 'Search order number',
+    paymentMatchScore: 'Score {score}',
+    paymentMatchReservedAmount: 'Reserved amount',
+    paymentMatchMatch: 'Match & Mark Paid',
+    paymentMatchConfirmTitle: 'Confirm Match',
+    paymentMatchConfirm: 'Mark order {orderNo} as paid with transaction {transactionId}?',
+    paymentMatchRemark: 'Remark (optional)',
+    paymentMatchSuccess: 'Order marked as paid',
+    paymentMatchFailed: 'Failed to match transaction',
+    paymentMatchIgnore: 'Ignore',
+    paymentMatchIgnoreSuccess: 'Transaction ignored',
+    paymentMatchCreateSuccess: 'Transaction added',
+    paymentMatchMatchedOrder: 'Matched order {orderNo}',
+    paymentMatchReasonReservedAmount: 'Reserved unique amount',
+    paymentMatchReasonExactAmount: 'Exact amount',
+    paymentMatchReasonCloseAmount: 'Amount within 1%',
+    paymentMatchReasonNearAmount: 'Amount within 5%',
+    paymentMatchReasonMemoOrderNo: 'Memo has order number',
+    paymentMatchReasonMemoPartialOrderNo: 'Memo has part of order number',
+    paymentMatchReasonPaymentMethod: 'Same payment method',
+    quoteRequests: 'Quote Requests',
+    quoteRequestsDesc:
+      'Reply to quote requests for price-on-request products. Create the order manually once the customer accepts.',
+    returnRequests: 'Return Requests',
+    returnRequestsDesc:
+      'Review customer return requests (RMA), issue return numbers and restock received items',
+    customerTier: 'Customer Tier',
+    customerTierHint:
+      'Price-on-request products show their price to customers whose tier is listed on the product. Leave empty for none',
+    priceOnRequest: 'Price on Request',
+    priceOnRequestHint:
+      'Hide the price and replace purchase with a quote request. Customers in the listed tiers still see the price and can order directly',
+    priceVisibleTiersPlaceholder:
+      'Tiers that can see the price, comma separated, e.g. wholesale, vip',
+    codReconciliation: 'COD Reconciliation',
+    codReconciliationDesc:
+      'Record cash collected by carriers on delivery and track what each carrier still owes.',
+    codReport: 'Collections by Carrier',
+    codCarrier: 'Carrier',
+    codCarrierUnassigned: 'Awaiting delivery',
+    codPending: 'Awaiting Collection',
+    codOutstanding: 'Outstanding',
+    codSettled: 'Settled',
+    codShortfall: 'Shortfall',
+    codCollections: 'Collections',
+    codAmountDue: 'Amount Due',
+    codCollectedAmount: 'Collected',
+    codCollectedAt: 'Collected At',
+    codStatusAll: 'All',
+    codStatusPending: 'Awaiting collection',
+    codStatusCollected: 'Collected',
+    codStatusSettled: 'Settled',
+    codStatusCancelled: 'Cancelled',
+    codFilterCarrier: 'Filter by carrier',
+    codRecordCollection: 'Record Collection',
+    codRecordCollectionDesc: 'Order {orderNo}, amount due {amount}',
+    codRecordSuccess: 'Collection recorded',
+    codSettleSelected: 'Mark Settled ({count})',
+    codSettleSuccess: '{count} collections marked as settled',
+    codNoCollections: 'No COD collections yet',
+    slowQueries: 'Slow Queries',
+    slowQueriesDesc:
+      'Queries slower than {threshold} ms since the last restart, grouped by SQL shape. Literal values are stripped.',
+    slowQueriesDisabled: 'Slow query recording is disabled (database.slow_query_threshold_ms < 0).',
+    slowQueriesClear: 'Clear',
+    slowQueriesCleared: 'Slow query statistics cleared',
+    slowQueriesClearFailed: 'Failed to clear slow query statistics',
+    slowQuerySql: 'SQL',
+    slowQueryCount: 'Count',
+    slowQueryAvgMs: 'Avg (ms)',
+    slowQueryMaxMs: 'Max (ms)',
+    slowQueryTotalMs: 'Total (ms)',
+    slowQueryLastSeen: 'Last Seen',
+    backgroundJobs: 'Background Jobs',
+    backgroundJobsDesc:
+      'Scheduled jobs running in this server process. Statistics reset on restart.',
+    backgroundJobName: 'Job',
+    backgroundJobSchedule: 'Schedule',
+    backgroundJobKindLoop: 'Long-running',
+    backgroundJobHealth: 'Health',
+    backgroundJobHealthy: 'Healthy',
+    backgroundJobUnhealthy: 'Unhealthy',
+    backgroundJobRunning: 'Running',
+    backgroundJobLastRun: 'Last Run',
+    backgroundJobNextRun: 'Next Run',
+    backgroundJobRuns: 'Runs / Failures / Skipped',
+    backgroundJobLastError: 'Last Error',
+    backgroundJobJitter: '(+ up to {seconds}s jitter)',
+    scraperOffenders: 'Scraper IPs',
+    scraperOffendersDesc:
+      'IPs flagged by catalog scraper protection in the last 24 hours. Each IP records at most one event of each type per minute.',
+    scraperIP: 'IP',
+    scraperEvents: 'Events',
+    scraperEventBreakdown: 'Burst / Honeypot / Challenge',
+    scraperLastSeen: 'Last Seen',
+    scraperBan: 'Ban',
+    scraperBanned: 'Banned',
+    scraperBanSuccess: 'IP banned',
+    scraperBanFailed: 'Failed to ban IP',
     smsContent: 'Content',
     smsEventType: 'Event Type',
     filterConditions: 'Filter Conditions',
@@ -3222,6 +3856,10 @@ export const enTranslations = {
     stockImport: 'Import',
     stockDeliver: 'Deliver',
     stockDelete: 'Delete',
+    stockReturn: 'Return',
+    stockRevoke: 'Revoke',
+    stockExpire: 'Expire',
+    stockTransfer: 'Transfer',
     batchNo: 'Batch No.',
     order: 'Order',
     user: 'User',
@@ -3246,6 +3884,49 @@ export const enTranslations = {
     tabUpload: 'Upload',
     tabPersonalization: 'Personalization',
     tabAdvanced: 'Advanced',
+    tabHistory: 'History',
+    settingsRevisions: 'Configuration history',
+    settingsRevisionsDesc:
+      'Every saved change records a before/after diff with secrets redacted. Rolling back restores that revision; secrets (passwords, keys, tokens) keep their current values.',
+    settingsRevisionsEmpty: 'No configuration changes recorded yet',
+    settingsRevisionActionBaseline: 'Baseline',
+    settingsRevisionActionUpdate: 'Update',
+    settingsRevisionActionRollback: 'Rollback',
+    settingsRevisionActionImport: 'Import',
+    settingsRevisionRollbackOf: 'to #{id}',
+    settingsRevisionChangeCount: '{count} changes',
+    settingsRevisionPath: 'Setting',
+    settingsRevisionBefore: 'Before',
+    settingsRevisionAfter: 'After',
+    settingsRevisionRollback: 'Roll back',
+    settingsRevisionRollbackTitle: 'Roll back to revision #{id}?',
+    settingsRevisionRollbackConfirm:
+      'The configuration file will be replaced with this revision and hot-reloaded. The rollback itself is recorded as a new revision.',
+    settingsRevisionRolledBack: 'Configuration rolled back',
+    settingsRevisionRollbackFailed: 'Rollback failed',
+    settingsBundle: 'Import / export',
+    settingsBundleDesc:
+      'Export portable settings as a bundle and import bundles from another environment. Database, Redis, JWT, URLs and other environment-specific settings are never exported or overwritten.',
+    settingsBundleEncryptSecrets: 'Include encrypted secrets',
+    settingsBundleEncryptSecretsDesc:
+      'Secrets are redacted by default. When enabled they are encrypted with the passphrase.',
+    settingsBundlePassphrase: 'Passphrase',
+    settingsBundleExport: 'Export bundle',
+    settingsBundleExported: 'Settings bundle exported',
+    settingsBundleExportFailed: 'Export failed',
+    settingsBundleChooseFile: 'Choose bundle file',
+    settingsBundleInvalidFile: 'The file is not valid JSON',
+    settingsBundlePreview: 'Preview import',
+    settingsBundlePreviewFailed: 'Preview failed',
+    settingsBundleNoChanges: 'The bundle matches the current settings',
+    settingsBundleInvalidConfig: 'Resulting configuration is invalid',
+    settingsBundleUnresolvedSecrets:
+      'Secrets missing in this environment (configure them after import)',
+    settingsBundleSecretsApplied: 'Secrets imported from the bundle',
+    settingsBundleSkippedPaths: 'Environment settings kept unchanged',
+    settingsBundleApply: 'Apply import',
+    settingsBundleImported: 'Settings imported',
+    settingsBundleImportFailed: 'Import failed',
     pluginPlatformSettings: 'Plugin Platform Settings',
     pluginPlatformSettingsDesc:
       'Configure allowed runtimes, plugin type allow-list, and sandbox policies.',
@@ -3403,6 +4084,10 @@ export const enTranslations = {
     orderCancelledDesc: 'Notify user when order is cancelled',
     resubmitRequired: 'Resubmit Required',
     resubmitRequiredDesc: 'Notify user when info resubmission is required',
+    orderMessageUserNotify: 'Customer Order Message',
+    orderMessageUserNotifyDesc: 'Notify admins when a customer messages about an order',
+    orderMessageAdminNotify: 'Order Message Reply',
+    orderMessageAdminNotifyDesc: 'Notify user when the seller replies to an order message',
     ticketSection: 'Ticket',
     ticketCreatedNotify: 'Ticket Created',
     ticketCreatedNotifyDesc: 'Notify admin when a new ticket is created',
@@ -3453,12 +4138,19 @@ export const enTranslations = {
     // Template event names
     templateEventWelcome: 'Welcome',
     templateEventEmailVerification: 'Email Verification',
+    templateEventEmailChanged: 'Email Changed',
     templateEventMarketing: 'Marketing Email',
     templateEventOrderCreated: 'Order Created',
     templateEventOrderPaid: 'Order Paid',
     templateEventOrderShipped: 'Order Shipped',
     templateEventOrderCompleted: 'Order Completed',
     templateEventOrderCancelled: 'Order Cancelled',
+    templateEventOrderSubStatus: 'Order Sub-status Update',
+    templateEventOrderPaymentReminder: 'Payment Reminder',
+    templateEventVirtualStockRevoked: 'Digital Item Revoked',
+    templateEventVirtualItems: 'Digital Items Resent',
+    templateEventOrderNoteMention: 'Order Note Mention',
+    templateEventOrderMessage: 'Order Message',
     templateEventOrderResubmit: 'Resubmit',
     templateEventTicketCreated: 'Ticket Created',
     templateEventTicketReply: 'Ticket Reply',
@@ -3478,6 +4170,14 @@ export const enTranslations = {
     requireEmailVerification: 'Require Email Verification',
     requireEmailVerificationHint:
       'Users must verify email after registration to log in. Requires SMTP configured.',
+    emailVerificationMode: 'Enforcement Mode',
+    emailVerificationModeLogin: 'Block login until verified',
+    emailVerificationModeCheckout: 'Block checkout only',
+    emailVerificationModeGrace: 'Grace period, then block login',
+    emailVerificationModeHint: 'Admins and users exempted by an admin are never blocked',
+    emailVerificationGraceDays: 'Grace Period (days)',
+    emailVerificationGraceDaysHint:
+      'Days after registration or an email change before unverified users are locked out',
     allowEmailLogin: 'Allow Email Login',
     allowEmailLoginHint:
       'Allow users to log in with email verification code. Requires SMTP enabled.',
@@ -3518,6 +4218,12 @@ export const enTranslations = {
     serialVerifyCaptchaHint: 'Require captcha for serial verification',
     bindCaptcha: 'Bind Captcha',
     bindCaptchaHint: 'Require captcha when binding email or phone',
+    recaptchaMinScore: 'reCAPTCHA v3 Minimum Score',
+    recaptchaMinScoreHint: 'Requests scoring below this value (0-1) are rejected, default 0.5',
+    captchaSceneProviders: 'Per-scene Provider',
+    captchaSceneProvidersHint: 'Use a different provider for a specific scene, e.g. invisible reCAPTCHA v3 for login',
+    captchaSceneDefault: 'Same as default',
+    captchaOverrideKeys: '{provider} Keys',
     // OAuth
     googleOAuthDesc: 'Configure Google third-party login',
     enableGoogleLogin: 'Enable Google Login',
@@ -3583,6 +4289,12 @@ export const enTranslations = {
     trustedProxiesPlaceholder: '127.0.0.1\n::1\n10.0.0.0/8\n172.16.0.0/12\n192.168.0.0/16',
     trustedProxiesHint:
       'Empty = trust IP headers from local loopback proxies only (127.0.0.1 / ::1), which suits local reverse proxy setups. When set, only trust headers when the TCP peer IP matches these IPs/CIDRs. Supports single IP or CIDR, e.g. 10.0.0.0/8.',
+    paymentHttpAllowlist: 'Payment Script Outbound HTTP',
+    paymentHttpAllowlistDesc:
+      'Restrict which hosts payment method scripts may call. Each payment method can define its own host allowlist.',
+    paymentHttpStrictAllowlist: 'Strict mode',
+    paymentHttpStrictAllowlistHint:
+      'When enabled, payment methods without an allowlist cannot make outbound requests. When disabled, an empty allowlist allows any public host.',
     redisConfigReadonly: 'Redis Configuration (Read-only)',
     redisConfigReadonlyDesc: 'View Redis connection info',
     host: 'Host',
@@ -3685,6 +4397,15 @@ export const enTranslations = {
     currencyHint: 'Currency unit for displaying order amounts',
     autoCancelHours: 'Auto-cancel Hours',
     autoCancelHoursHint: 'Unpaid orders auto-cancel after this duration. Set 0 to disable.',
+    productAutoCancelHours: 'Auto-cancel Window (hours)',
+    productAutoCancelHoursHint:
+      'Overrides the global unpaid-order window for orders containing this product (shortest wins). 0 uses the global setting.',
+    draftExpireHours: 'Draft Expiration (hours)',
+    draftExpireHoursHint:
+      'API draft orders whose shipping form link expired this many hours ago are cancelled and the source platform is notified. Set 0 to disable.',
+    paymentReminderHours: 'Payment Reminder (hours before cancel)',
+    paymentReminderHoursHint:
+      'Email a payment link this many hours before an unpaid order is auto-cancelled. Each order is reminded once. 0 disables reminders.',
     maxPendingPaymentOrdersPerUser: 'Max Unpaid Orders Per User',
     maxPendingPaymentOrdersPerUserHint:
       'Users cannot create new orders after this limit is reached',
@@ -3717,6 +4438,15 @@ export const enTranslations = {
     highConcurrencyProtectionRedisLease: 'Redis Lease Duration (ms)',
     highConcurrencyProtectionRedisLeaseHint:
       'Automatic reclaim time for distributed slots. Keep it above the real transaction duration ceiling to avoid premature reuse.',
+    highConcurrencyMaxInFlightPerUser: 'Max In-flight Orders per User',
+    highConcurrencyMaxInFlightPerUserHint:
+      'How many create-order requests one user may have in progress at once. 0 means unlimited.',
+    highConcurrencyMaxInFlightPerIp: 'Max In-flight Orders per IP',
+    highConcurrencyMaxInFlightPerIpHint:
+      'How many create-order requests one IP may have in progress at once. 0 means unlimited.',
+    highConcurrencyBlindBoxMaxInFlight: 'Blind Box Allocation Concurrency',
+    highConcurrencyBlindBoxMaxInFlightHint:
+      'Global cap on concurrent blind-box allocations to limit inventory lock contention. 0 means unlimited.',
     stockDisplayTitle: 'Stock Display',
     stockDisplayMode: 'Display Mode',
     stockDisplayModeExact: 'Exact Quantity',
@@ -3734,10 +4464,46 @@ export const enTranslations = {
     virtualDeliveryRandom: 'Random',
     virtualDeliveryNewest: 'Newest First',
     virtualDeliveryOldest: 'Oldest First',
-    virtualDeliveryOrderHint: 'Order in which virtual stock is selected when orders are placed',
+    virtualDeliveryExpiring: 'Earliest Expiry First',
+    virtualDeliveryInheritGlobal: 'Use global setting',
+    virtualDeliveryInheritInventory: 'Use inventory setting',
+    virtualDeliveryOverrideHint:
+      'Overrides the global delivery order for this inventory; gift cards usually need oldest first',
+    virtualDeliveryOrderHint:
+      'Order in which virtual stock is selected when orders are placed; inventories and bindings can override it',
     virtualScriptTimeoutMaxMs: 'Virtual Script Timeout Cap (ms)',
     virtualScriptTimeoutMaxMsHint:
       'Host-level maximum execution time. A script can request a shorter or longer timeout with script_config.timeout_ms, but the effective timeout never exceeds this cap.',
+    virtualStockExpiryWarningDays: 'Virtual Stock Expiry Warning (days)',
+    virtualStockExpiryWarningDaysHint:
+      'Stock items expiring within this many days are flagged and listed in the expiry report. Expired items are no longer allocated.',
+    cartReservationTtlSeconds: 'Cart Reservation TTL (seconds)',
+    cartReservationTtlSecondsHint:
+      'How long stock stays held for products with reserve-on-cart enabled before it is released',
+    tierPricingPromoStacking: 'Bulk Pricing & Promo Codes',
+    tierPricingPromoStack: 'Stack (promo applies to bulk-priced subtotal)',
+    tierPricingPromoExclusive: 'Exclusive (reject promo codes on bulk-priced orders)',
+    tierPricingPromoBest: 'Best price (use whichever saves more)',
+    tierPricingPromoStackingHint:
+      'How promo codes combine with product quantity-break pricing when an order reaches a tier',
+    waitingRoomSettings: 'Flash Sale Waiting Room',
+    waitingRoomEnabled: 'Enable Waiting Room',
+    waitingRoomEnabledHint:
+      'Shoppers queue and are admitted at a controlled rate before they can place orders',
+    waitingRoomAllProducts: 'Apply to Whole Store',
+    waitingRoomAllProductsHint:
+      'When off, only products with the waiting room switch enabled require queueing',
+    waitingRoomAdmitPerSecond: 'Admissions per Second',
+    waitingRoomBurst: 'Burst Size',
+    waitingRoomAdmissionTtl: 'Admission Validity (seconds)',
+    waitingRoomAbandonAfter: 'Drop Idle After (seconds)',
+    waitingRoomMetrics: 'Waiting Room Metrics',
+    waitingRoomQueueLength: 'In Queue',
+    waitingRoomAdmittedActive: 'Admitted (active)',
+    waitingRoomJoinedTotal: 'Joined',
+    waitingRoomAdmittedTotal: 'Admitted',
+    waitingRoomAbandonedTotal: 'Abandoned',
+    waitingRoomAbandonRate: 'Abandon Rate',
     showVirtualStockRemark: 'Show Virtual Stock Remark to Users',
     showVirtualStockRemarkHint:
       'When enabled, users can see the remark/notes of virtual product stock items on the order detail page',
@@ -3768,12 +4534,49 @@ export const enTranslations = {
     invoiceFooterPlaceholder: 'e.g. Thank you for your business!',
     invoiceCustomTemplate: 'Custom HTML Template',
     invoiceCustomTemplateTip:
-      'Available variables: {{.CompanyName}}, {{.OrderNo}}, {{.InvoiceNo}}, {{.OrderDate}}, {{.CompletedDate}}, {{.CustomerName}}, {{.CustomerEmail}}, {{.CustomerPhone}}, {{.CustomerAddress}}, {{.Items}}, {{.Subtotal}}, {{.DiscountAmount}}, {{.HasDiscount}}, {{.TotalAmount}}, {{.Currency}}, {{.FooterText}}, {{.AppName}}',
+      'Available variables: {{.CompanyName}}, {{.OrderNo}}, {{.InvoiceNo}}, {{.OrderDate}}, {{.CompletedDate}}, {{.CustomerName}}, {{.CustomerEmail}}, {{.CustomerPhone}}, {{.CustomerAddress}}, {{.Items}}, {{.Subtotal}}, {{.DiscountAmount}}, {{.HasDiscount}}, {{.TotalAmount}}, {{.Currency}}, {{.FooterText}}, {{.AppName}}. Item fields: {{.Name}}, {{.SKU}}, {{.Quantity}}, {{.UnitPrice}}, {{.Discount}}, {{.LineTotal}}',
     formAndLinkSettings: 'Form & Link Settings',
     formAndLinkSettingsDesc: 'Configure form and magic link expiration',
+    exchangeRateSettings: 'Exchange Rates',
+    exchangeRateSettingsDesc:
+      'Live fiat and crypto rates for payment scripts (AuraLogic.rates) and multi-currency order display',
+    exchangeRateEnabled: 'Enable exchange rate service',
+    exchangeRateEnabledHint:
+      'When disabled, builtin USDT scripts fall back to the cny_rate configured on the payment method',
+    exchangeRateFiatApiKey: 'exchangerate.host API Key',
+    exchangeRateCryptoApiKey: 'CoinGecko API Key (optional)',
+    exchangeRateApiKeyKeepHint: 'Leave API keys blank to keep the current values',
+    exchangeRateCacheMinutes: 'Cache duration (minutes)',
+    exchangeRateStaleAlertMinutes: 'Stale alert after (minutes)',
+    exchangeRateDisplayCurrencies: 'Order display currencies',
+    exchangeRateDisplayCurrenciesHint:
+      'Comma-separated currency codes shown as converted reference amounts on order details, e.g. USD, EUR',
+    exchangeRatePair: 'Pair',
+    exchangeRateRate: 'Rate',
+    exchangeRateProvider: 'Provider',
+    exchangeRateUpdatedAt: 'Updated At',
+    exchangeRateStale: 'Stale',
+    shipmentTrackingSettings: 'Shipment Tracking',
+    shipmentTrackingSettingsDesc:
+      'Register tracking numbers with 17track or AfterShip, sync carrier events and auto-complete delivered orders',
+    shipmentTrackingEnabled: 'Enable shipment tracking',
+    shipmentTrackingProvider: 'Tracking provider',
+    shipmentTrackingApiKey: 'API Key',
+    shipmentTrackingWebhookSecret: 'Webhook Secret',
+    shipmentTrackingWebhookSecretHint:
+      'Used to verify AfterShip pushes. 17track pushes are verified with the API key',
+    shipmentTrackingWebhookUrlHint: 'Webhook URL to configure at the provider: {url}',
+    shipmentTrackingPollInterval: 'Poll interval (minutes)',
+    shipmentTrackingAutoCompleteDays: 'Auto-complete after delivery (days)',
+    shipmentTrackingAutoCompleteDaysHint:
+      'Shipped orders are completed automatically this many days after delivery. 0 disables auto-complete',
     magicLinkExpiry: 'Magic Link Expiry (minutes)',
     magicLinkMaxUses: 'Magic Link Max Uses',
     formExpiry: 'Form Expiry (hours)',
+    disabledCountries: 'Disabled Shipping Countries',
+    disabledCountriesDesc:
+      'Checked countries are hidden from the shipping form and rejected on submission',
+    disabledCountriesFilterPlaceholder: 'Filter by code or name',
     // Personalization
     themeColor: 'Theme Color',
     themeColorDesc: 'Customize system theme colors',
@@ -3904,6 +4707,61 @@ export const enTranslations = {
     maxPurchaseLimitPlaceholder: '0 for unlimited',
     maxPurchaseLimitHint:
       'Set to 0 for no limit, other values set max purchase quantity per account',
+    minOrderQuantity: 'Min Quantity Per Item',
+    maxOrderQuantity: 'Max Quantity Per Order',
+    quantityIncrement: 'Sold in Multiples Of',
+    orderQuantityRulesHint:
+      'Minimum and multiple apply to each cart/order line; the maximum counts all variants of this product in one order. 0 means no rule',
+    priceTiersLabel: 'Bulk Pricing Tiers',
+    priceTierMinQuantity: 'Min quantity',
+    priceTierUnitPrice: 'Unit price',
+    addPriceTier: 'Add tier',
+    priceTiersHint:
+      'Quantity breaks apply to the total quantity of this product in one order, across all variants',
+    shippingAllowedCountries: 'Ship Only To (country codes)',
+    shippingAllowedCountriesHint: 'Comma-separated ISO codes. Leave empty to allow all countries',
+    shippingBlockedCountries: 'Embargoed Countries',
+    shippingBlockedCountriesHint:
+      'Orders to these countries are rejected. Takes precedence over the allow list',
+    customsInfo: 'Customs Declaration',
+    customsInfoHint:
+      'Used for CN22/CN23 forms on international orders. Declared value defaults to the sale price',
+    hsCodePlaceholder: 'HS code (6-10 digits)',
+    declaredValuePlaceholder: 'Declared unit value',
+    weightGramsPlaceholder: 'Net weight (g)',
+    originCountryPlaceholder: 'Origin country (e.g. CN)',
+    weightAndDimensions: 'Weight & Dimensions',
+    packageDimensions: 'Package Dimensions',
+    packageDimensionsHint: 'Packed size in millimetres, used to suggest box sizes for orders',
+    inventoryDimensionsHint:
+      'Overrides the product weight and size for this variant. Leave 0 to use the product values',
+    lengthMmPlaceholder: 'Length (mm)',
+    widthMmPlaceholder: 'Width (mm)',
+    heightMmPlaceholder: 'Height (mm)',
+    packagePlan: 'Packaging',
+    packagePlanWeight: 'Net weight',
+    packagePlanVolume: 'Volume',
+    packagePlanNoBoxes: 'No box suggestion. Configure package boxes in order settings',
+    packagePlanUnpackable: 'Too large for every box',
+    packagePlanMissingData: 'Missing weight or dimensions',
+    shipmentTrackingSync: 'Sync tracking',
+    shipmentTrackingSynced: 'Tracking synced',
+    shipmentTrackingSyncFailed: 'Failed to sync tracking',
+    shipmentTrackingLastSynced: 'Last synced',
+    shipmentTrackingLastError: 'Last error',
+    shipmentTrackingNotRegistered: 'Not registered with the provider yet',
+    shippingBlocks: 'Shipping Blocks',
+    shippingBlocksDesc:
+      'Attempts rejected by product shipping restrictions, grouped by product, country and source',
+    shippingBlocksCountryFilter: 'Country code',
+    shippingBlocksClear: 'Clear Records',
+    shippingBlocksCleared: 'Shipping block records cleared',
+    shippingBlocksSource: 'Source',
+    shippingBlocksSourceForm: 'Shipping form',
+    shippingBlocksSourceAdmin: 'Admin order',
+    shippingBlocksCount: 'Attempts',
+    shippingBlocksLastSeen: 'Last Attempt',
+    shippingBlocksEmpty: 'No blocked attempts',
     productImages: 'Product Images',
     uploadImage: 'Upload Image',
     uploading: 'Uploading...',
@@ -3931,6 +4789,18 @@ export const enTranslations = {
     featuredProduct: 'Featured',
     recommendedProduct: 'Recommended',
     autoDelivery: 'Auto Delivery',
+    reserveOnCart: 'Reserve on Add to Cart',
+    reserveOnCartHint:
+      'Hold stock for a short time when added to cart; released automatically if not checked out',
+    waitingRoom: 'Waiting Room',
+    waitingRoomHint:
+      'When the waiting room is enabled, shoppers must queue and be admitted before ordering this product',
+    emailDeliveryMode: 'Email Delivery',
+    emailDeliveryModeNone: 'Link to order page only',
+    emailDeliveryModeInline: 'Include codes in email',
+    emailDeliveryModeLink: 'Signed retrieval link (expires in 72h)',
+    emailDeliveryModeHint:
+      'Controls how delivered items appear in the payment/shipping email. Use the signed link for security-sensitive items.',
     sortOrder: 'Sort Order',
     remarkLabel: 'Remarks',
     virtualStockManageBtn: 'Virtual Inventory',
@@ -4194,6 +5064,21 @@ export const enTranslations = {
     pmPollInterval: 'Poll Interval (seconds)',
     pmPollIntervalHint:
       'Interval for checking payment status automatically. 30-60 seconds recommended.',
+    pmAutoCancelHours: 'Auto-cancel Window (hours)',
+    pmAutoCancelHoursHint:
+      'Unpaid orders using this method are cancelled after this many hours from creation. Overrides product and global settings; 0 keeps them.',
+    pmAllowedHosts: 'Allowed outbound hosts',
+    pmAllowedHostsHint:
+      'One host per line, e.g. api.example.com or *.example.com (subdomains only). Requests to other hosts are rejected and logged. Leave empty to follow the global strict mode setting.',
+    pmRequiredOrderTags: 'Required order tags',
+    pmExcludedOrderTags: 'Excluded order tags',
+    pmOrderTagsHint:
+      'Comma-separated. The method is only offered for orders carrying all required tags and none of the excluded tags. Leave both empty to allow all orders.',
+    pmSandbox: 'Sandbox mode',
+    pmSandboxHint:
+      'Orders using this method are marked as test orders, excluded from revenue analytics, and can be marked paid with "Simulate payment" on the order page.',
+    pmSandboxBadge: 'Sandbox',
+    pmCashOnDeliveryBadge: 'Cash on delivery',
     pmPackageFile: 'Package File',
     pmPackageTarget: 'Import Target',
     pmPackageTargetNew: 'Create New Method',
@@ -4206,6 +5091,10 @@ export const enTranslations = {
     pmPackageImportedSuccess: 'Payment package imported',
     pmPackageMissingRequiredConfig: 'Fill the required config field first: {field}',
     pmPackageImportedBadge: 'Package',
+    pmBreakerOpen: 'Circuit open',
+    pmBreakerHalfOpen: 'Probing',
+    pmBreakerReset: 'Reset breaker',
+    pmBreakerResetSuccess: 'Circuit breaker reset',
     pmPackageSummary: 'Package Summary',
     pmPackageVersion: 'Package Version',
     pmPackageEntry: 'Entry Script',
@@ -4246,6 +5135,7 @@ export const enTranslations = {
       "// Generate payment card HTML\nfunction onGeneratePaymentCard(order, config) {\n  return {\n    html: '<div>Custom payment information</div>',\n    title: 'Payment method name',\n    cache_ttl: 300  // Cache for 5 minutes (0=no cache, -1=permanent, >0=seconds)\n  }\n}",
     pmTestScript: 'Test Script',
     pmTestResult: 'Test Result',
+    pmTestConsole: 'Console Output',
     pmApiRef: 'API Reference',
     pmApiRefDesc: 'Full docs: docs/PAYMENT_JS_API.md',
     pmRequiredCallbacks: 'Required Callbacks',
@@ -4266,6 +5156,11 @@ export const enTranslations = {
     pmPostRequest: 'POST request',
     pmGeneralRequest: 'General HTTP request',
     pmHttpReturns: 'Returns: {status, headers, body, data, error}',
+    pmRsaSign: 'PKCS#1 v1.5, SHA256 by default, PEM or base64 keys',
+    pmRandomBytes: 'Cryptographically secure random bytes (hex by default)',
+    pmHttpAsync: 'Promise versions for async callbacks (use with await)',
+    pmConsoleDesc:
+      "Output is shown in test results and saved to the payment method's script logs",
     pmGetTimestamp: 'Get Unix timestamp',
     pmGetMethodInfo: 'Get payment method info',
     pmThemeAdaptation: 'Theme Adaptation',
@@ -4298,6 +5193,17 @@ export const enTranslations = {
     clearSelected: 'Clear Selected',
     listView: 'List view',
     cardView: 'Card view',
+    waitingRoomTitle: 'Waiting Room',
+    waitingRoomDesc:
+      'This drop is in high demand. Keep this window open; your order will be submitted automatically once it is your turn.',
+    waitingRoomJoining: 'Joining the queue...',
+    waitingRoomPosition: 'Your position: {position} / {total}',
+    waitingRoomEstimatedWait: 'Estimated wait: about {seconds}s',
+    waitingRoomAdmitted: 'It is your turn, submitting your order...',
+    waitingRoomDropped: 'You left the queue because of inactivity. Please queue again.',
+    waitingRoomRejoin: 'Queue Again',
+    waitingRoomLeave: 'Leave Queue',
+    reservedUntil: 'Reserved for you until {time}',
     outOfStock: 'Out of stock',
     select: 'Select',
     removeItem: 'Remove item',
@@ -4389,6 +5295,8 @@ export const enTranslations = {
     firstQuery: 'First Query',
     lastQuery: 'Last Query',
     queryWarning: 'This serial number has been queried {n} times, please verify carefully',
+    invalidatedTitle: 'This serial number has been invalidated',
+    invalidatedDesc: 'The item was returned on {date} and this serial number is no longer valid.',
     queryAnother: 'Query Another Serial Number',
     instructions: 'Instructions',
     instruction1:
@@ -4474,6 +5382,8 @@ export const enTranslations = {
     login: 'Login',
     register: 'Register',
     verifyEmail: 'Verify Email',
+    revertEmailChange: 'Restore Email',
+    virtualDelivery: 'Delivered Items',
     products: 'Products',
     productDetail: 'Product Detail',
     cart: 'Shopping Cart',
@@ -4509,6 +5419,11 @@ export const enTranslations = {
     adminPromoCodes: 'Promo Code Management',
     adminPromoCodeNew: 'New Promo Code',
     adminPromoCodeEdit: 'Edit Promo Code',
+    adminPaymentMatching: 'Payment Matching',
+    adminCODReconciliation: 'COD Reconciliation',
+    adminQuoteRequests: 'Quote Requests',
+    adminReturnRequests: 'Return Requests',
+    quoteRequests: 'My Quotes',
     knowledge: 'Knowledge Base',
     knowledgeArticle: 'Article Detail',
     announcements: 'Announcements',
@@ -4587,6 +5502,17 @@ export const enTranslations = {
     remaining: 'Remaining',
   },
 
+  virtualDelivery: {
+    title: 'Your Delivered Items',
+    description: 'Order {orderNo}. Keep these codes private and do not share this link.',
+    expiresAt: 'This link expires at {time}',
+    empty: 'No items are available through this link.',
+    invalidLink: 'This link is invalid or has expired. Please log in to view your order.',
+    copy: 'Copy',
+    copied: 'Copied to clipboard',
+    viewOrder: 'Log in to View Order',
+  },
+
   knowledge: {
     knowledgeBase: 'Knowledge Base',
     categories: 'Categories',
@@ -4893,7 +5819,392 @@ export const enTranslations = {
       'promo_code.notFound': 'Promo code not found',
       'promo_code.unavailable': 'Promo code is not available',
       'promo_code.notApplicable': 'Promo code is not applicable to the selected products',
+      'promo_code.notStackableWithTierPricing': 'Promo codes cannot be combined with bulk pricing',
       'promo_code.minOrderAmountNotMet': 'Order amount does not meet the minimum requirement',
+      'promo_code.exhausted': 'Promo code usage limit has been reached',
+      'promo_code.campaignNameRequired': 'Campaign name cannot be empty',
+      'promo_code.campaignQuantityInvalid': 'Code quantity must be between 1 and {max}',
+      'promo_code.campaignPrefixInvalid': 'Prefix may only contain letters, digits, - or _ and at most {max} characters',
+      'promo_code.campaignPatternInvalid': 'Pattern must contain at least {min} placeholders (# digit, ? letter, * letter or digit) and the code cannot exceed {max} characters',
+      'promo_code.campaignCodeSpaceExhausted': 'Not enough unique codes for this pattern, use a longer pattern',
+      'promo_code.campaignNotFound': 'Promo code campaign not found',
+    },
+  },
+
+  store: {
+    bizError: {
+      'store.notFound': 'Store not found',
+      'store.codeInvalid': 'Store code may only contain lowercase letters, digits and hyphens',
+      'store.nameRequired': 'Store name is required',
+      'store.codeAlreadyExists': 'Store code already exists',
+      'store.domainInvalid': 'Invalid domain: {domain}',
+      'store.domainAlreadyBound': 'Domain {domain} is already bound to another store',
+      'store.adminInvalid': 'Only existing administrators can be assigned to a store',
+    },
+  },
+
+  domain: {
+    bizError: {
+      'domain.notFound': 'Domain not found',
+      'domain.alreadyExists': 'Domain {domain} is already registered',
+      'domain.landingPageNotFound': 'Landing page {slug} not found',
+    },
+  },
+
+  landingPage: {
+    bizError: {
+      'landingPage.revisionNotFound': 'Landing page revision not found',
+      'landingPage.tooManyBlocks': 'A landing page can contain at most {max} blocks',
+      'landingPage.blockTypeInvalid': 'Block #{index} has an unsupported type: {type}',
+      'landingPage.blockPropsInvalid': 'Block #{index} ({type}) has invalid properties',
+      'landingPage.blockFieldRequired': 'Block #{index} ({type}) requires field {field}',
+      'landingPage.blockURLInvalid': 'Block #{index} ({type}) field {field} must be an http(s) URL or a site-relative path',
+      'landingPage.blockFieldTooLong': 'Block #{index} ({type}) field {field} is too long',
+      'settings.revisionNotFound': 'Configuration revision not found',
+      'settings.bundleInvalid': 'Not a valid settings bundle',
+      'settings.bundlePassphraseRequired': 'A passphrase is required to encrypt or decrypt secrets',
+      'settings.bundleDecryptFailed': 'Failed to decrypt secrets, check the passphrase',
+      'settings.importConflict': 'Configuration changed since the preview, please preview again',
+      'demo.outboundBlocked': 'Outbound requests are disabled in demo mode',
+    },
+  },
+
+  shortLink: {
+    bizError: {
+      'shortLink.notFound': 'Short link not found',
+      'shortLink.expired': 'Short link has expired',
+      'shortLink.formUnavailable': 'Shipping form is not available for this order',
+      'shortLink.paymentUnavailable': 'Order is not awaiting payment',
+      'shortLink.appURLRequired': 'Site URL is not configured',
+    },
+  },
+
+  ledger: {
+    bizError: {
+      'ledger.creditAmountInvalid': 'Credit amount must be greater than 0',
+      'ledger.creditReasonRequired': 'Credit reason is required',
+      'ledger.creditReasonTooLong': 'Credit reason cannot exceed {max} characters',
+    },
+  },
+
+  giftPromotion: {
+    bizError: {
+      'giftPromotion.notFound': 'Gift promotion not found',
+      'giftPromotion.nameRequired': 'Promotion name is required',
+      'giftPromotion.thresholdNegative': 'Spend threshold must be greater than or equal to 0',
+      'giftPromotion.conditionRequired': 'Set a spend threshold or qualifying products',
+      'giftPromotion.quantityInvalid': 'Gift quantity must be between 1 and {max}',
+      'giftPromotion.timeRangeInvalid': 'End time must be later than start time',
+      'giftPromotion.giftProductNotFound': 'Gift product not found',
+      'giftPromotion.giftProductVirtual': 'Virtual products cannot be used as gifts',
+      'giftPromotion.giftInventoryNotFound': 'Gift inventory not found',
+    },
+  },
+
+  quoteRequest: {
+    requestQuote: 'Request a Quote',
+    requestQuoteDesc: 'Tell us about your needs for {quantity} × {product}',
+    message: 'Requirements',
+    messagePlaceholder: 'Delivery schedule, customization, payment terms...',
+    company: 'Company',
+    contactName: 'Contact Name',
+    contactEmail: 'Contact Email',
+    contactPhone: 'Contact Phone',
+    submit: 'Submit Request',
+    submitted: 'Quote request submitted',
+    submitFailed: 'Failed to submit quote request',
+    myQuotes: 'My Quotes',
+    myQuotesDesc: 'Track quote requests and accept or decline offers',
+    noQuotes: 'No quote requests yet',
+    quoteNo: 'Quote No.',
+    customer: 'Customer',
+    product: 'Product',
+    quotedPrice: 'Quoted Price',
+    unitPrice: 'Unit Price',
+    total: 'Total',
+    validUntil: 'Valid Until',
+    validDays: 'Valid for (days)',
+    validDaysHint: '0 means the quote does not expire',
+    reply: 'Reply',
+    accept: 'Accept Quote',
+    decline: 'Decline',
+    withdraw: 'Withdraw',
+    acceptedHint: 'Quote accepted, we will create your order shortly',
+    updated: 'Quote request updated',
+    updateFailed: 'Failed to update quote request',
+    sendQuote: 'Send Quote',
+    sendQuoteDesc: '{quantity} × {product}',
+    requote: 'Update Quote',
+    quoteSent: 'Quote sent',
+    close: 'Close',
+    closed: 'Quote request closed',
+    searchPlaceholder: 'Quote No. / product / company / email',
+    statusAll: 'All Statuses',
+    statusPending: 'Awaiting Quote',
+    statusQuoted: 'Quoted',
+    statusAccepted: 'Accepted',
+    statusDeclined: 'Declined',
+    statusClosed: 'Closed',
+    bizError: {
+      'quote.notFound': 'Quote request not found',
+      'quote.productNotFound': 'Product not found',
+      'quote.notRequired': 'This product can be ordered directly',
+      'quote.quantityInvalid': 'Quantity must be between 1 and {max}',
+      'quote.messageTooLong': 'Requirements cannot exceed {max} characters',
+      'quote.attributeInvalid': 'Invalid {attribute} option',
+      'quote.tooManyOpen': 'You can have at most {max} open quote requests',
+      'quote.statusInvalid':
+        'This quote request can no longer be changed (current status: {status})',
+      'quote.priceInvalid': 'Quoted unit price must be greater than 0',
+      'quote.validDaysInvalid': 'Validity must be between 0 and {max} days',
+      'quote.expired': 'This quote has expired, please request a new one',
+      'user.customerTierInvalid': 'Customer tier cannot exceed {max} characters',
+    },
+  },
+  returnRequest: {
+    title: 'Returns',
+    cardDesc: 'Request a return for items from this order',
+    empty: 'No return requests yet',
+    requestReturn: 'Request a Return',
+    requestReturnDesc:
+      'Choose the items and quantities to return, describe the reason and attach photos if possible.',
+    items: 'Items',
+    quantity: 'Quantity',
+    returnableQuantity: '{count} returnable',
+    reason: 'Reason',
+    reasonPlaceholder: 'e.g. item arrived damaged, wrong size...',
+    photos: 'Photos',
+    addPhoto: 'Add photo',
+    photosHint: 'Up to {max} photos (JPG, PNG, GIF, WebP)',
+    photoUploadFailed: 'Failed to upload photo',
+    submit: 'Submit Request',
+    submitted: 'Return request submitted',
+    submitFailed: 'Failed to submit return request',
+    cancelled: 'Return request withdrawn',
+    cancelFailed: 'Failed to withdraw return request',
+    withdraw: 'Withdraw',
+    returnTrackingNo: 'Return No.',
+    shipBackHint: 'Please write this number on the package when shipping the items back.',
+    adminRemark: 'Remark',
+    rmaNo: 'RMA No.',
+    order: 'Order',
+    restockedQuantity: '{count} restocked',
+    approve: 'Approve',
+    reject: 'Reject',
+    receive: 'Mark Received',
+    approved: 'Return approved',
+    rejected: 'Return rejected',
+    receivedToast: 'Return received',
+    approveDesc: 'A return number will be generated for the customer to ship the items back.',
+    rejectDesc: 'The remark is shown to the customer as the rejection reason.',
+    receiveDesc: 'Confirm the items of {rmaNo} have arrived.',
+    restock: 'Restock bound inventory',
+    invalidateSerials: 'Invalidate serial numbers of returned items',
+    searchPlaceholder: 'RMA No. / order No. / return No.',
+    statusAll: 'All Statuses',
+    statusPending: 'Pending Review',
+    statusApproved: 'Awaiting Return',
+    statusRejected: 'Rejected',
+    statusReceived: 'Received',
+    statusCancelled: 'Withdrawn',
+    bizError: {
+      'return.notFound': 'Return request not found',
+      'return.statusInvalid':
+        'This return request can no longer be changed (current status: {status})',
+      'return.alreadyOpen': 'This order already has a return request in progress',
+      'return.reasonRequired': 'Please describe the reason for the return',
+      'return.reasonTooLong': 'Reason cannot exceed {max} characters',
+      'return.remarkRequired': 'Please provide a reason for rejecting the return',
+      'return.remarkTooLong': 'Remark cannot exceed {max} characters',
+      'return.tooManyPhotos': 'At most {max} photos can be attached',
+      'return.photoInvalid': 'Invalid return photo, please upload it again',
+      'return.photoRequired': 'Please select a photo to upload',
+      'return.photoTooLarge': 'Photo cannot exceed {max} MB',
+      'return.photoFormatUnsupported': 'Unsupported photo format',
+    },
+  },
+
+  adminActivity: {
+    bizError: {
+      'adminActivity.rangeInvalid': 'Date range must be between 1 and {max} days',
+      'adminActivity.adminNotFound': 'Admin not found',
+    },
+  },
+
+  orderSubStatus: {
+    bizError: {
+      'orderSubStatus.notFound': 'Order sub-status not found',
+      'orderSubStatus.nameRequired': 'Sub-status name is required',
+      'orderSubStatus.statusInvalid': 'Invalid order status: {status}',
+      'orderSubStatus.codeInvalid': 'Code must start with a lowercase letter and contain only lowercase letters, digits and underscores (2-50 characters)',
+      'orderSubStatus.codeExists': 'Sub-status code {code} already exists',
+      'orderSubStatus.inUse': 'Sub-status is used by {count} orders',
+      'orderSubStatus.statusMismatch': 'Sub-status {code} requires order status {required}, current status is {status}',
+      'orderSubStatus.orderChanged': 'Order status changed, please refresh and retry',
+    },
+  },
+
+  orderAutomation: {
+    bizError: {
+      'orderAutomation.ruleNotFound': 'Automation rule not found',
+      'orderAutomation.nameRequired': 'Rule name is required',
+      'orderAutomation.triggerInvalid': 'Invalid trigger: {trigger}',
+      'orderAutomation.matchTypeInvalid': 'Match type must be all or any',
+      'orderAutomation.tooManyConditions': 'At most {max} conditions are allowed',
+      'orderAutomation.tooManyActions': 'At most {max} actions are allowed',
+      'orderAutomation.actionsRequired': 'At least one action is required',
+      'orderAutomation.conditionInvalid': 'Invalid condition on {field}: {reason}',
+      'orderAutomation.actionInvalid': 'Invalid {type} action: {reason}',
+    },
+  },
+
+  ticketAutomation: {
+    bizError: {
+      'ticketAutomation.ruleNotFound': 'Automation rule not found',
+      'ticketAutomation.nameRequired': 'Rule name is required',
+      'ticketAutomation.triggerInvalid': 'Invalid trigger: {trigger}',
+      'ticketAutomation.matchTypeInvalid': 'Match type must be all or any',
+      'ticketAutomation.tooManyConditions': 'At most {max} conditions are allowed',
+      'ticketAutomation.tooManyActions': 'At most {max} actions are allowed',
+      'ticketAutomation.actionsRequired': 'At least one action is required',
+      'ticketAutomation.conditionInvalid': 'Invalid condition on {field}: {reason}',
+      'ticketAutomation.actionInvalid': 'Invalid {type} action: {reason}',
+    },
+  },
+
+  webhook: {
+    bizError: {
+      'webhook.endpointNotFound': 'Webhook not found',
+      'webhook.deliveryNotFound': 'Webhook delivery not found',
+      'webhook.nameRequired': 'Webhook name is required',
+      'webhook.nameTooLong': 'Webhook name must be at most 100 characters',
+      'webhook.platformTooLong': 'Source platform must be at most 100 characters',
+      'webhook.urlInvalid': 'Webhook URL must be a public http(s) address',
+      'webhook.eventInvalid': 'Unknown webhook event: {event}',
+      'webhook.eventsRequired': 'Select at least one event',
+      'webhook.tooManyEndpoints': 'At most {max} webhooks are allowed',
+    },
+  },
+
+  listFilter: {
+    bizError: {
+      'listFilter.invalid': 'Invalid filter: {reason}',
+    },
+  },
+
+  savedView: {
+    bizError: {
+      'savedView.notFound': 'Saved view not found',
+      'savedView.nameRequired': 'View name is required (max 100 characters)',
+      'savedView.paramsInvalid': 'Invalid view parameters',
+      'savedView.resourceInvalid': 'Unsupported list: {resource}',
+      'savedView.limitReached': 'At most {max} views per list',
+    },
+  },
+
+  fieldMask: {
+    bizError: {
+      'fieldMask.roleInvalid': 'Unsupported role: {role}',
+      'fieldMask.policyInvalid': 'Invalid masking policy for field {field}',
+    },
+  },
+
+  security: {
+    bizError: {
+      'security.eventNotFound': 'Security event not found',
+      'security.banNotFound': 'IP ban not found',
+      'security.lockoutNotFound': 'Account lockout not found',
+      'security.ipInvalid': 'Invalid IP address: {ip}',
+      'security.durationInvalid': 'Ban duration must not be negative',
+    },
+  },
+
+  approval: {
+    bizError: {
+      'approval.notFound': 'Approval request not found',
+      'approval.notPending': 'Approval request has already been processed',
+      'approval.selfReview': 'You cannot review your own approval request',
+      'approval.notApproved': 'Approval request has not been approved',
+      'approval.expired': 'Approval request has expired, please submit again',
+      'approval.mismatch': 'Approval request does not match this operation',
+    },
+  },
+
+  orderNote: {
+    bizError: {
+      'orderNote.notFound': 'Order note not found',
+      'orderNote.contentRequired': 'Note content is required',
+      'orderNote.contentTooLong': 'Note content cannot exceed {max} characters',
+      'orderNote.notAuthor': 'Only the author can modify this note',
+      'orderNote.mentionInvalid': 'Mentioned users must be active administrators with order access',
+      'orderNote.tooManyMentions': 'A note can mention at most {max} administrators',
+    },
+  },
+
+  orderMessage: {
+    bizError: {
+      'orderMessage.contentRequired': 'Message content is required',
+      'orderMessage.contentTooLong': 'Message cannot exceed {max} characters',
+      'orderMessage.guestOrder':
+        'Messages are only available for orders placed by registered users',
+      'orderMessage.escalated': 'This conversation has moved to a support ticket',
+      'orderMessage.ticketDisabled': 'Ticket system is disabled',
+      'orderMessage.emptyThread': 'There are no messages to escalate',
+    },
+  },
+
+  orderClaim: {
+    bizError: {
+      'orderClaim.emailNotVerified': 'Please verify your email before claiming orders',
+      'orderClaim.notFound': 'No claimable order matches this order number and your email',
+      'orderClaim.alreadyOwned': 'This order already belongs to an account',
+    },
+  },
+
+  region: {
+    bizError: {
+      'region.countryNotFound': 'Country not found',
+      'region.countryDisabled': 'Shipping to {country} is not available',
+      'region.provinceInvalid': 'Province/state {province} does not exist in {country}',
+      'region.cityInvalid': 'City {city} does not exist in {province}',
+    },
+  },
+
+  adminNotification: {
+    bizError: {
+      'notification.subscriptionNotFound': 'Notification subscription not found',
+      'notification.notFound': 'Notification not found',
+      'notification.eventInvalid': 'Unknown notification event: {event}',
+      'notification.channelInvalid': 'Unknown notification channel: {channel}',
+      'notification.telegramNotConfigured': 'Telegram bot token is not configured',
+      'notification.telegramChatIDRequired': 'Telegram chat ID is required',
+      'notification.filterTooLarge': 'At most {max} filter values are allowed',
+      'notification.countryFilterInvalid': 'Country filter only applies to order events',
+      'notification.categoryFilterInvalid':
+        'Category filter only applies to ticket and low stock events',
+      'notification.tooManySubscriptions': 'At most {max} notification subscriptions are allowed',
+    },
+  },
+
+  virtual_inventory: {
+    bizError: {
+      'virtual_inventory.revokeReasonRequired': 'Revoke reason is required',
+      'virtual_inventory.stockItemNotDelivered': 'Only delivered stock items can be revoked',
+      'virtual_inventory.reissueScriptUnsupported': 'Script inventories cannot reissue a replacement',
+      'virtual_inventory.expiresAtInvalid': 'Invalid expiry time',
+      'virtual_inventory.expiresAtPast': 'Expiry time must be in the future',
+      'virtual_inventory.batchNoRequired': 'Batch number is required',
+      'virtual_inventory.batchNotFound': 'No unsold stock items found in batch {batch_no}',
+      'waiting_room.admissionRequired': 'This item is in high demand, please wait in the queue before ordering',
+      'waiting_room.tokenInvalid': 'Your queue admission has expired, please queue again',
+      'waiting_room.disabled': 'The waiting room is not enabled',
+      'virtual_inventory.deliveryOrderInvalid': 'Invalid delivery order: {order}',
+      'virtual_inventory.notFound': 'Virtual inventory not found',
+      'virtual_inventory.transferTargetInvalid': 'Choose a different target inventory',
+      'virtual_inventory.transferQuantityInvalid': 'Transfer quantity must be between 1 and {max}',
+      'virtual_inventory.transferScriptUnsupported': 'Script inventories do not hold stock items',
+      'virtual_inventory.transferNoMatch': 'No available stock items match the filters',
+      'virtual_inventory.transferInsufficient': 'Only {available} matching items are available',
     },
   },
 
@@ -36,6 +36,8 @@ export const zhTranslations = {
     noData: '暂无数据',
     prevPage: '上一页',
     nextPage: '下一页',
+    demoModeBadge: '演示',
+    demoModeNotice: '演示模式：邮件、短信与对外请求均已禁用',
     pageInfo: '第 {page} 页，共 {totalPages} 页',
     refresh: '刷新',
     verifying: '验证权限...',
@@ -88,6 +90,7 @@ export const zhTranslations = {
     productCenter: '商品中心',
     cart: '购物车',
     myOrders: '我的订单',
+    myQuotes: '我的询价',
     serialVerify: '序列号验证',
     profile: '个人中心',
     accountSettings: '账户设置',
@@ -113,6 +116,17 @@ export const zhTranslations = {
     dark: '深色',
     system: '跟随系统',
     toggleTheme: '切换主题',
+    bizError: {
+      'theme.notFound': '主题不存在',
+      'theme.slugInvalid': '主题标识只能包含小写字母、数字和中划线',
+      'theme.nameRequired': '主题名称不能为空',
+      'theme.slugAlreadyExists': '主题标识 {slug} 已存在',
+      'theme.colorInvalid': '主题字段 {field} 的颜色值无效：{value}',
+      'theme.typographyInvalid': '主题字段 {field} 的取值无效：{value}',
+      'theme.emailTemplateInvalid': '{field} 模板语法错误',
+      'theme.importInvalid': '主题文件无效',
+      'theme.importVersionUnsupported': '不支持的主题文件版本：{version}',
+    },
   },
 
   language: {
@@ -207,6 +221,13 @@ export const zhTranslations = {
       'auth.invalidPhoneFormat': '手机号格式无效',
       'auth.captchaRequired': '请完成验证码',
       'auth.captchaFailed': '验证码验证失败',
+      'auth.accountLocked': '登录失败次数过多，账户已被临时锁定',
+      'auth.ipBanned': '您的IP地址已被禁止访问',
+      'scraper.challengeRequired': '浏览器验证未通过，请刷新页面后重试',
+      'auth.csrfInvalid': '安全令牌已失效，请刷新页面后重试',
+      'auth.emailVerificationRequired': '请先验证邮箱后再下单',
+      'auth.emailChangeLinkInvalid': '邮箱恢复链接无效或已过期',
+      'auth.invalidDisplayCurrency': '展示币种代码无效',
     },
     // 表单验证
     invalidEmail: '邮箱格式错误',
@@ -225,6 +246,14 @@ export const zhTranslations = {
       '我们已向 {email} 发送了一封验证邮件。请检查您的收件箱并点击邮件中的链接以激活您的账户。',
     backToLogin: '返回登录',
     retryVerification: '重新验证',
+    revertEmailTitle: '恢复账户邮箱',
+    revertEmailDesc: '如果您没有更改过账户邮箱，请恢复原邮箱，之后的所有邮箱更改都将被撤销。',
+    revertEmailConfirm: '恢复我的邮箱',
+    revertEmailSuccess: '邮箱已恢复',
+    revertEmailSuccessDesc: '您的账户邮箱已恢复为 {email}，请立即重置密码以保护账户安全。',
+    revertEmailResetPassword: '重置密码',
+    revertEmailFailed: '恢复邮箱失败',
+    revertEmailMissingLink: '恢复链接不完整，请从邮件中重新打开。',
     resendVerification: '重新发送验证邮件',
     resend: '重新发送',
     sending: '发送中...',
@@ -291,6 +320,11 @@ export const zhTranslations = {
   },
 
   product: {
+    bulkPricing: '批量优惠价',
+    minOrderQuantityHint: '{min} 件起订',
+    quantityIncrementHint: '按 {increment} 件倍数购买',
+    maxOrderQuantityHint: '每单最多 {max} 件',
+    bulkPricingFrom: '{quantity} 件起',
     products: '商品',
     productList: '商品列表',
     productDetail: '商品详情',
@@ -342,6 +376,9 @@ export const zhTranslations = {
     views: '浏览',
     sales: '销量',
     save: '省',
+    approximatePriceHint: '参考价，实际按原币种结算',
+    priceOnRequest: '价格面议',
+    priceOnRequestHint: '提交询价单后，我们会按您的采购数量回复报价',
     blindBoxAttribute: '盲盒随机属性',
     blindBoxDesc: '本商品含随机属性，购买后系统将随机分配，增加惊喜感！',
     blindBoxRandomTip: '💡 系统将在下单后随机分配以上规格，为您带来惊喜体验',
@@ -385,6 +422,27 @@ export const zhTranslations = {
       'product.stockNegative': '库存不能小于 0',
       'product.quantityInvalid': '商品数量必须大于 0',
       'product.stockInsufficient': '商品库存不足，当前可用库存：{available}',
+      'product.metaTitleTooLong': 'SEO 标题不能超过 255 个字符',
+      'product.metaDescriptionTooLong': 'SEO 描述不能超过 500 个字符',
+      'product.ogImageInvalid': 'OG 图片必须是 http(s) 地址或站内路径',
+      'product.shipWithinDaysInvalid': '发货时效需在 0 到 365 天之间',
+      'product.autoCancelHoursInvalid': '自动取消时限需在 0 到 {max} 小时之间',
+      'productPrice.scheduleNotFound': '定时调价不存在',
+      'productPrice.originalPriceNegative': '划线价不能小于 0',
+      'productPrice.effectiveAtInPast': '生效时间必须晚于当前时间',
+      'productPrice.scheduleNotPending': '只能取消待生效的定时调价（当前状态：{status}）',
+      'product.shippingCountryInvalid': '未知的国家代码：{country}',
+      'product.hsCodeInvalid': 'HS 编码须为 6、8 或 10 位数字',
+      'product.dimensionsInvalid': '重量和尺寸不能为负数',
+      'product.customsValueInvalid': '申报价值和重量不能为负数',
+      'product.priceTiersTooMany': '阶梯价最多设置 {max} 档',
+      'product.orderQuantityRulesInvalid': '数量规则无效：上限不能小于起订量，起订量须为购买倍数的整数倍',
+      'product.orderQuantityBelowMin': '{product} 起订量为 {min} 件',
+      'product.orderQuantityIncrement': '{product} 须按 {increment} 的倍数购买',
+      'product.orderQuantityAboveMax': '{product} 每笔订单最多购买 {max} 件',
+      'product.priceTierQuantityInvalid': '阶梯起订数量须大于 1 且不能重复',
+      'product.priceTierPriceInvalid': '阶梯单价不能高于基础价或更低数量档位的单价',
+      'product.priceOnRequest': '{product} 为询价商品，请提交询价单',
     },
   },
 
@@ -396,6 +454,8 @@ export const zhTranslations = {
     orderStatus: '订单状态',
     orderTime: '下单时间',
     orderAmount: '订单金额',
+    displayAmountApproximate: '参考金额，实际按原币种结算',
+    exchangeRateStale: '汇率可能已过期',
     createOrder: '创建订单',
     cancelOrder: '取消订单',
     refundOrder: '退款',
@@ -485,7 +545,71 @@ export const zhTranslations = {
     updatedAt: '更新时间',
     remarks: '备注',
     userRemark: '用户备注',
-    adminRemark: '管理员备注',
+    orderNotes: '内部备注',
+    orderNotesEmpty: '暂无内部备注',
+    orderNotePlaceholder: '添加内部备注（仅管理员可见）',
+    orderNoteAdd: '添加备注',
+    orderNoteSaveFailed: '保存备注失败',
+    orderNoteSystem: '系统',
+    orderNotePinned: '已置顶',
+    orderNotePin: '置顶',
+    orderNoteUnpin: '取消置顶',
+    orderNoteMention: '通知管理员',
+    orderNoteMentioned: '已提及',
+    orderNoteDeleteConfirm: '确定删除这条备注吗？',
+    orderNoteSourceCreate: '创建订单',
+    orderNoteSourceMarkPaid: '标记已付款',
+    orderNoteSourceComplete: '完成订单',
+    orderNoteSourceResubmit: '要求重填',
+    orderNoteSourceCancel: '取消订单',
+    orderNoteSourceAutoCancel: '自动取消',
+    orderNoteSourceRefund: '退款',
+    orderNoteSourceRefundConfirm: '确认退款',
+    orderNoteSourceReturn: '退货入库',
+    orderNoteSourceVirtualRevoke: '撤销虚拟商品',
+    orderNoteSourceAutomation: '自动化规则',
+    orderNoteSourceCODCollection: '货到付款代收',
+    orderNoteSourceLegacy: '历史备注',
+    orderNoteSourceAutoComplete: '自动完成',
+    orderNoteSourceMerge: '合并订单',
+    shipmentTracking: '物流轨迹',
+    shipmentTrackingCarrier: '承运商',
+    shipmentTrackingDeliveredAt: '签收时间',
+    shipmentTrackingNoEvents: '暂无物流轨迹',
+    shipmentTrackingStatusPending: '等待揽收信息',
+    shipmentTrackingStatusInTransit: '运输中',
+    shipmentTrackingStatusOutForDelivery: '派送中',
+    shipmentTrackingStatusDelivered: '已签收',
+    shipmentTrackingStatusException: '异常',
+    orderMessages: '订单留言',
+    orderMessagesDesc: '对订单有疑问？在这里联系卖家。',
+    orderMessagesEmpty: '暂无留言',
+    orderMessagePlaceholder: '输入留言...',
+    orderMessageSend: '发送',
+    orderMessageSendFailed: '留言发送失败',
+    orderMessageYou: '我',
+    orderMessageSeller: '卖家',
+    orderMessageCustomer: '客户',
+    orderMessageEscalate: '转为工单',
+    orderMessageEscalateConfirm: '将该会话转为工单？订单会自动分享到工单，后续沟通将在工单中进行。',
+    orderMessageEscalateFailed: '转为工单失败',
+    orderMessageEscalated: '该会话已转至工单 #{ticketNo}',
+    orderMessageViewTicket: '查看工单',
+    claimOrder: '认领订单',
+    claimOrderDesc: '将在其他平台使用已验证邮箱下的订单关联到当前账号。',
+    claimOrderPlaceholder: '请输入订单号',
+    claimOrderConfirm: '认领',
+    claimOrderSuccess: '订单已关联到您的账号',
+    claimOrderFailed: '订单认领失败',
+    exportOrders: '导出',
+    exportOrdersDesc: '下载所选日期范围内的订单，包含商品明细、金额与状态。',
+    exportStartDate: '开始日期',
+    exportEndDate: '结束日期',
+    exportFormat: '格式',
+    exportRangeHint: '单次最多导出 366 天，日期按 UTC 计算。',
+    exportOrdersConfirm: '下载',
+    exportOrdersSuccess: '订单已导出',
+    exportOrdersFailed: '订单导出失败',
     trackingInfo: '物流信息',
     trackingNo: '物流单号',
     privacyProtected: '隐私保护',
@@ -515,6 +639,10 @@ export const zhTranslations = {
     retryLoadPaymentInfo: '重试加载付款信息',
     paymentMethodListLoading: '正在加载可选付款方式，请稍候。',
     paymentCardPendingHint: '当前已选付款方式，但暂时还没有可展示的付款卡片内容。',
+    stripePayNow: '立即支付',
+    stripePaymentSubmitted: '付款已提交，Stripe 确认后订单状态将自动更新。',
+    stripePaymentFailed: '付款失败，请检查银行卡信息后重试。',
+    stripeLoadFailed: 'Stripe 付款表单加载失败，请刷新页面后重试。',
     noPaymentMethods: '暂无可用的付款方式',
     noPaymentMethodsHint: '当前没有可用于此订单的付款方式，请稍后重试或联系管理员。',
     paymentMethodNoDescription: '该付款方式暂未提供更多说明。',
@@ -538,6 +666,21 @@ export const zhTranslations = {
     virtualProductShipped: '虚拟商品已发货，点击查看卡密',
     delivered: '已发货',
     deliveryTime: '发货时间',
+    virtualStockRevoked: '已撤销',
+    virtualStockRevokedAt: '撤销时间',
+    virtualStockFirstViewedAt: '首次查看',
+    virtualStockNotViewed: '尚未查看',
+    resendVirtualEmail: '重发卡密邮件',
+    resendVirtualEmailSuccess: '邮件已发送，请查收',
+    resendVirtualEmailFailed: '邮件发送失败',
+    redeliverVirtualStock: '替换卡密',
+    redeliverVirtualStockTitle: '确认替换该卡密？',
+    redeliverVirtualStockDesc:
+      '当前卡密将作为泄露卡密撤销，并从同一库存补发新卡密到该订单，同时邮件通知买家。',
+    redeliverVirtualStockReason: '原因（可选）',
+    redeliverVirtualStockSuccess: '卡密已替换',
+    redeliverVirtualStockFailed: '替换卡密失败',
+    revealVirtualStockFailed: '显示卡密失败',
     totalCodes: '共 {count} 个卡密',
     copiedToClipboard: '已复制到剪贴板',
 
@@ -590,6 +733,43 @@ export const zhTranslations = {
     markComplete: '标记完成',
     markCompleteTitle: '标记订单完成',
     remarkOptional: '备注（可选）',
+    recordReturn: '登记退货',
+    returnTitle: '登记退货商品',
+    returnDesc: '填写每个商品实际退回的数量，回补库存的数量将重新计入可用库存。',
+    returnRemaining: '可退 {n} 件',
+    returnRestock: '退货商品回补库存',
+    returnInvalidateSerials: '作废退货商品的序列号',
+    returnConfirm: '确认登记',
+    returnRecorded: '退货已登记',
+    mergeOrders: '合并发货',
+    mergeOrdersTitle: '合并订单发货',
+    mergeOrdersDesc: '同一买家、同一收货人的待发货订单将并入本订单一起发货，被合并的订单随之关闭。',
+    mergeOrdersEmpty: '暂无可并入本订单的其他待发货订单',
+    mergeOrdersConfirm: '确认合并',
+    mergeOrdersDone: '已合并 {n} 个订单',
+    mergedIntoOrder: '本订单已合并到',
+    mergedFromOrders: '已并入的订单',
+    mergedIntoNotice: '本订单已合并到订单 {order_no}，将随该订单一起发货。',
+    viewMergedOrder: '查看订单',
+    refunds: '退款记录',
+    partialRefund: '部分退款',
+    partialRefundDesc:
+      '选择要退款的商品与金额。商品金额留空时按数量折算实付金额；退款总额留空时使用商品合计。',
+    refundableAmount: '可退金额',
+    refundedAmount: '已退金额',
+    refundPendingAmount: '待确认',
+    refundTotalAmount: '退款总额',
+    refundItemQuantity: '数量',
+    refundItemAmount: '金额',
+    refundItemRemaining: '可退 {n} 件',
+    refundFull: '全额',
+    refundCreated: '退款已提交',
+    confirmRefundRecord: '确认到账',
+    noRefunds: '暂无退款记录',
+    refundStatusProcessing: '处理中',
+    refundStatusPending: '待确认',
+    refundStatusSucceeded: '已完成',
+    refundStatusFailed: '失败',
     remarkPlaceholder: '订单处理备注',
     confirmComplete: '确认完成',
     cancelOrderTitle: '取消订单',
@@ -630,6 +810,14 @@ export const zhTranslations = {
     shippingUpdated: '收货信息已更新',
     resubmitRequested: '已要求用户重新填写收货信息',
     orderMarkedPaid: '订单已标记为已付款',
+    sandboxOrder: '沙箱测试单',
+    tags: '标签',
+    filterTag: '标签',
+    tagFilterPlaceholder: '如 vip, wholesale',
+    addTagPlaceholder: '添加标签',
+    removeTag: '移除标签',
+    simulateSandboxPayment: '模拟付款',
+    sandboxPaymentSimulated: '已模拟付款成功',
     priceUpdated: '订单价格已更新',
     priceUpdatedAndPaymentReset: '订单价格已更新，付款缓存与金额上下文已重置',
     virtualDelivered: '虚拟商品已发货',
@@ -659,6 +847,8 @@ export const zhTranslations = {
     paymentCacheEmpty: '未缓存',
     bizError: {
       'order.invalidOrderID': '订单 ID 格式无效',
+      'order.virtualEmailUnavailable': '该订单无法发送虚拟商品邮件',
+      'order.virtualProductsNotDelivered': '虚拟商品尚未发放',
       'order.invalidRequestParameters': '请求参数无效',
       'order.trackingNumberLengthInvalid': '物流单号长度必须在 {min}-{max} 个字符之间',
       'order.adminRemarkTooLong': '管理员备注长度不能超过 {max} 个字符',
@@ -686,9 +876,16 @@ export const zhTranslations = {
       'order.batchLimitExceeded': '单次最多只能处理 {max} 个订单',
       'order.itemsEmpty': '订单商品不能为空',
       'order.tooManyItems': '订单商品不能超过{max}项',
+      'order.tagInvalid': '标签最多 {max} 个字符，且不能包含逗号',
+      'order.tooManyTags': '每个订单最多 {max} 个标签',
       'order.skuEmpty': '商品SKU不能为空',
       'order.quantityInvalid': '商品数量必须大于0',
       'order.quantityExceeded': '单个商品数量不能超过{max}',
+      'order_import.duplicateHeader': '表头重复：{header}',
+      'order_import.missingHeader': '缺少必需的表头：{header}',
+      'order_import.empty': '导入文件中没有数据行',
+      'order_import.tooManyRows': '导入行数过多（最多 {max} 行），请拆分文件',
+      'order_import.jobNotFound': '导入任务不存在',
       'order.attributesTooMany': '商品属性不能超过{max}项',
       'order.productNotAvailable': '商品暂时不可购买',
       'order.productNotFound': '商品 {sku} 不存在',
@@ -717,6 +914,33 @@ export const zhTranslations = {
       'order.externalOrderIDTooLong': '外部订单号长度不能超过 {max} 个字符',
       'order.platformNameTooLong': '平台名称长度不能超过 {max} 个字符',
       'order.orderRemarkTooLong': '订单备注长度不能超过 {max} 个字符',
+      'order.receiverPhoneInvalidForCountry': '{country} 的电话号码格式不正确，示例：{example}',
+      'order.postcodeInvalidForCountry': '{country} 的邮政编码格式不正确，示例：{example}',
+      'order.shippingRestricted': '商品「{name}」无法配送至 {country}',
+      'order.returnStatusInvalid': '只有已发货的订单可以登记退货',
+      'order.returnItemsRequired': '请至少选择一个退货商品',
+      'order.returnItemInvalid': '退货商品无效',
+      'order.returnQuantityExceeded': '{sku} 的退货数量超过购买数量（剩余可退：{remaining}）',
+      'order.mergeSourcesRequired': '请至少选择一个要合并的其他订单',
+      'order.mergeTooManySources': '一次最多合并 {max} 个订单',
+      'order.mergeStatusInvalid': '订单 {order_no} 不是待发货状态，无法合并',
+      'order.mergeBuyerMismatch': '订单 {order_no} 属于其他买家',
+      'order.mergeReceiverMismatch': '订单 {order_no} 的收货人信息不一致',
+      'order.mergeIncompatible': '订单 {order_no} 的店铺、币种或沙盒模式不一致',
+      'order.mergeVirtualItems': '订单 {order_no} 包含虚拟商品，无法合并',
+      'order.mergePromoCode': '订单 {order_no} 使用了优惠码，请改为将其他订单并入该订单',
+      'order.mergeSerialGenerationBusy': '订单 {order_no} 的序列号尚未生成完成',
+      'order.mergeRefunded': '订单 {order_no} 存在退款记录，无法合并',
+      'order.mergePaymentMismatch': '订单 {order_no} 的支付方式不一致',
+      'order.refundNothingRefundable': '该订单已无可退金额',
+      'order.refundItemInvalid': '退款商品无效',
+      'order.refundNotFound': '退款记录不存在',
+      'order.exportRangeInvalid': '日期范围需在 1 到 {max} 天之间',
+      'order.exportTooManyRows': '该范围内订单过多（单次最多 {max} 条），请缩小日期范围',
+      'order.refundAmountInvalid': '退款金额必须大于 0 且不超过 {refundable}',
+      'order.refundItemExceeded':
+        '{sku} 的退款超过实付（剩余可退数量：{quantity}，金额：{amount}）',
+      'order.refundConfirmStatusInvalid': '只能确认待确认的退款（当前状态：{status}）',
     },
   },
 
@@ -726,6 +950,29 @@ export const zhTranslations = {
       'payment.pollingUserQueueLimitExceeded':
         '您当前有 {current} 个支付轮询任务，已达到上限 {max}',
       'payment.pollingGlobalQueueLimitExceeded': '系统支付轮询队列已满（上限 {max}），请稍后重试',
+      'payment.sandboxInvalidOrderStatus': '仅待付款订单可模拟付款（当前状态：{status}）',
+      'payment.sandboxMethodRequired': '该订单未选择沙箱付款方式',
+      'payment.methodNotAllowedForOrder': '该订单不可使用此付款方式',
+      'payment.amountReserveInvalidOrderStatus': '仅待付款订单可分配付款金额（当前状态：{status}）',
+      'payment.amountOffsetExhausted': '相同金额的待付款订单过多，请稍后重试',
+      'payment.codVirtualNotSupported': '含虚拟商品的订单不支持货到付款',
+      'payment.stripeNotConfigured': 'Stripe 尚未配置，请选择其他付款方式',
+      'payment.stripeAmountInvalid': '订单金额过小，无法使用 Stripe 支付',
+      'payment_match.importEmpty': '导入文件没有数据行',
+      'payment_match.importMissingHeader': '缺少必填列：{header}',
+      'payment_match.importTooManyRows': '导入文件超过 {max} 行',
+      'payment_match.invalidAmount': '金额必须为正数',
+      'payment_match.transactionIdRequired': '请填写交易号',
+      'payment_match.transactionIdTooLong': '交易号过长',
+      'payment_match.transactionNotFound': '到账流水不存在',
+      'payment_match.transactionNotUnmatched': '该到账流水已处理（状态：{status}）',
+      'cod.carrierRequired': '请填写承运商',
+      'cod.collectionNotFound': '该订单不是货到付款订单',
+      'cod.collectionNotPending': '该订单的代收已登记（状态：{status}）',
+      'cod.invalidCollectedAmount': '实收金额须大于 0 且不超过应收金额',
+      'cod.orderNotShipped': '仅已发货的订单可登记代收（状态：{status}）',
+      'cod.settleEmpty': '请至少选择一条代收记录',
+      'cod.settleTooMany': '单次最多结算 {max} 条代收记录',
     },
   },
 
@@ -763,6 +1010,11 @@ export const zhTranslations = {
     languagePreferenceDesc: '选择页面界面语言。',
     themePreference: '主题',
     themePreferenceDesc: '选择你希望使用的界面外观。',
+    displayCurrencyPreference: '展示币种',
+    displayCurrencyPreferenceDesc: '以其他币种显示参考价格（约数），实际仍按原币种结算。',
+    displayCurrencyDefault: '原币种',
+    displayCurrencySaveSuccess: '展示币种已更新',
+    displayCurrencySaveFailed: '展示币种更新失败',
     themeLightDesc: '浅色外观，明亮清晰',
     themeDarkDesc: '深色外观，适合弱光环境',
     themeSystemDesc: '跟随操作系统主题设置',
@@ -807,6 +1059,11 @@ export const zhTranslations = {
     securityTip3: '• 建议使用至少8位的强密码，包含大小写字母、数字和特殊符号',
     securityTip4: '• 如果您怀疑账户被盗用，请立即修改密码并联系管理员',
     bindEmail: '绑定邮箱',
+    changeEmail: '更换邮箱',
+    changeEmailDesc: '需通过验证码验证新邮箱；原邮箱将收到通知，7 天内可撤销此次更改。',
+    newEmail: '新邮箱',
+    changeEmailConfirm: '确认更换',
+    changeEmailSuccess: '邮箱已更换，已向原邮箱发送通知',
     bindEmailDesc: '绑定邮箱地址，用于邮箱登录和接收通知',
     emailBindUnavailableHint: '当前未启用邮件服务，暂时无法绑定邮箱。',
     bindPhone: '绑定手机号',
@@ -965,6 +1222,8 @@ export const zhTranslations = {
       'ticket.imageUploadDisabled': '当前不允许上传图片',
       'ticket.imageFileTooLarge': '图片大小不能超过 {max}MB',
       'ticket.imageFormatUnsupported': '图片格式不受支持',
+      'ticket.tagInvalid': '标签最多 {max} 个字符，且不能包含逗号',
+      'ticket.tooManyTags': '每个工单最多 {max} 个标签',
     },
     items: '商品',
     ticketStatus: {
@@ -989,6 +1248,10 @@ export const zhTranslations = {
     assignAll: '全部',
     assignMe: '我的',
     assignUnassigned: '未分配',
+    tags: '标签',
+    tagFilterPlaceholder: '按标签筛选...',
+    addTagPlaceholder: '添加标签',
+    removeTag: '移除标签',
     selectTicket: '请从左侧选择一个工单',
     selectionHint: '左侧列表会优先展示未关闭工单，选中后即可继续处理回复。',
     filterHint: '当前未应用额外筛选，列表优先展示未关闭工单。',
@@ -1020,6 +1283,25 @@ export const zhTranslations = {
     shippedAt: '发货时间',
     userRemark: '用户备注',
     orderNotAccessible: '订单不存在或无权访问',
+    virtualItems: '已发货卡密',
+    virtualItemsMaskedHint: '卡密已脱敏，如需完整卡密请向买家确认。',
+    virtualItemRevoked: '已撤销',
+    virtualItemFirstViewed: '首次查看',
+    virtualItemNotViewed: '尚未查看',
+    paymentInfo: '付款信息',
+    paymentMethod: '付款方式',
+    paymentSelectedAt: '选择时间',
+    paymentData: '付款数据',
+    paymentRecordPayment: '收款',
+    paymentRecordRefund: '退款',
+    shipmentHistory: '发货记录',
+    shipmentActionAssignTracking: '填写物流单号',
+    shipmentActionShipped: '已发货',
+    shipmentActionDeliverVirtualStock: '虚拟商品已发货',
+    shipmentActionRevokeVirtualStock: '卡密已撤销',
+    shipmentActionComplete: '已完成',
+    shipmentActionCancel: '已取消',
+    shipmentActionReceiveReturn: '已收到退货',
     updateSuccess: '更新成功',
     updateFailed: '更新失败',
     adminMessagePlaceholder: '输入消息... (Enter 发送, Shift+Enter 换行，支持 Markdown)',
@@ -1029,6 +1311,53 @@ export const zhTranslations = {
     // 侧边栏
     adminPanel: '管理后台',
     backToUser: '返回用户端',
+    globalSearch: '搜索',
+    globalSearchPlaceholder: '订单号、物流单号、邮箱、工单、SKU 或序列号...',
+    globalSearchHint: '请至少输入 2 个字符',
+    globalSearchEmpty: '没有匹配结果',
+    globalSearchFailed: '搜索失败',
+    globalSearchTypeOrder: '订单',
+    globalSearchTypeUser: '用户',
+    globalSearchTypeTicket: '工单',
+    globalSearchTypeProduct: '商品',
+    globalSearchTypeSerial: '序列号',
+    paletteQuickActions: '快捷操作',
+    paletteRecent: '最近处理',
+    paletteAssignedTickets: '分配给我的工单',
+    quickActionPendingShipment: '待发货订单',
+    quickActionCreateProduct: '新建商品',
+    quickActionTickets: '处理工单',
+    quickActionReturnRequests: '审核退货申请',
+    quickActionUsers: '用户管理',
+    quickActionSerials: '查询序列号',
+    quickActionAnnouncements: '公告管理',
+    quickActionOperationLogs: '操作日志',
+    quickActionDashboard: '仪表板',
+    notifications: '通知',
+    notificationInbox: '收件箱',
+    notificationSubscriptions: '订阅',
+    notificationNone: '暂无通知',
+    notificationMarkAllRead: '全部标为已读',
+    notificationSubscriptionsDesc:
+      '选择需要关注的事件及接收方式。国家筛选仅对订单事件生效；分类筛选匹配工单分类，低库存事件匹配商品分类。',
+    notificationSubscriptionNone: '暂无订阅',
+    notificationSubscriptionCreate: '添加订阅',
+    notificationSubscriptionEdit: '编辑订阅',
+    notificationEvent: '事件',
+    notificationChannel: '渠道',
+    notificationChannelEmail: '邮件',
+    notificationChannelTelegram: 'Telegram',
+    notificationChannelInApp: '站内通知',
+    notificationCountries: '国家',
+    notificationCountriesHint: '以逗号分隔的国家代码，如 US, DE；留空表示全部',
+    notificationCategories: '分类',
+    notificationCategoriesHint: '以逗号分隔的分类；留空表示全部',
+    notificationTelegramChatId: 'Telegram 会话 ID',
+    notificationTelegramUnavailable: '尚未配置 Telegram 机器人 Token，暂不可用',
+    notificationEnabled: '启用',
+    notificationDisabled: '已停用',
+    notificationSaved: '订阅已保存',
+    notificationDeleted: '订阅已删除',
     dashboard: '仪表板',
     productManagement: '商品管理',
     inventoryManagement: '库存管理',
@@ -2392,8 +2721,16 @@ export const zhTranslations = {
     orderTrend: '订单趋势',
     statusDistribution: '状态分布',
     sourceDistribution: '来源分布',
+    cancelReasonDistribution: '取消原因分布',
+    cancelReasonPaymentTimeout: '付款超时',
+    cancelReasonDraftExpired: '草稿过期',
+    cancelReasonMerged: '已合并到其他订单',
+    cancelReasonManual: '手动/其他',
     platformDistribution: '平台分布',
     orderCountryDistribution: '订单国家分布',
+    orderTagDistribution: '订单标签',
+    orderTag: '标签',
+    paidOrders: '已付款',
     amountDistribution: '金额分布',
     topProducts: '热销商品',
     salesCount: '销量',
@@ -2548,6 +2885,9 @@ export const zhTranslations = {
     moreProducts: '+{count} 个商品',
     exportSuccess: '订单数据已导出到Excel文件',
     exportFailed: '导出失败',
+    exportCustomsDeclarations: '导出报关明细',
+    customsDeclaration: '报关单',
+    customsDeclarationFailed: '报关单生成失败',
     usersExportSuccess: '用户数据已导出',
     productsImportSuccess: '商品导入完成',
     productsExportSuccess: '商品数据已导出',
@@ -2600,6 +2940,10 @@ export const zhTranslations = {
     userFilterCountry: '筛选：国家（如 CN）',
     verified: '已验证',
     unverified: '未验证',
+    emailVerified: '邮箱已验证',
+    emailVerificationExempt: '豁免邮箱验证',
+    emailVerificationExemptHint: '该用户无需验证邮箱即可登录和下单',
+    emailChangeReverifyHint: '修改邮箱后用户需要重新验证新邮箱',
     withPhone: '有手机号',
     withoutPhone: '无手机号',
     viewOrders: '查看订单',
@@ -2667,12 +3011,17 @@ export const zhTranslations = {
     permCategoryPayment: '支付方式权限',
     permOrderView: '查看订单',
     permOrderViewPrivacy: '查看隐私保护订单',
+    permOrderViewEmail: '查看未打码邮箱',
+    permOrderViewPhone: '查看未打码电话',
+    permOrderViewAddress: '查看未打码姓名和地址',
+    permOrderViewPayment: '查看未打码付款数据',
     permOrderEdit: '编辑订单',
     permOrderDelete: '删除订单',
     permOrderStatusUpdate: '更新订单状态',
     permOrderRefund: '订单退款',
     permOrderAssignTracking: '分配物流单号',
     permOrderRequestResubmit: '要求重填信息',
+    permOrderAutomation: '管理订单自动化',
     permProductView: '查看商品',
     permProductEdit: '编辑商品',
     permProductDelete: '删除商品',
@@ -2682,13 +3031,18 @@ export const zhTranslations = {
     permTicketView: '查看工单',
     permTicketReply: '回复工单',
     permTicketStatusUpdate: '更新工单状态',
+    permTicketAutomation: '管理工单自动化',
     permAdminCreate: '创建管理员',
     permAdminEdit: '编辑管理员',
     permAdminDelete: '删除管理员',
     permAdminPermission: '分配管理员权限',
+    permAdminActivity: '查看管理员工作量',
+    permAdminApprove: '审批危险操作',
     permSystemConfig: '系统配置',
     permSystemLogs: '查看日志',
     permApiManage: 'API密钥管理',
+    permSecurityView: '查看登录安全',
+    permSecurityManage: '管理IP封禁和账户锁定',
     permPaymentMethodView: '查看支付方式',
     permCategoryPlugin: '插件权限',
     permPluginView: '查看插件',
@@ -2706,7 +3060,10 @@ export const zhTranslations = {
     permAnnouncementEdit: '编辑公告',
     permMarketingView: '查看营销管理',
     permMarketingSend: '发送营销消息',
+    permCategoryReturn: '退货权限',
     permCategorySerial: '序列号权限',
+    permReturnView: '查看退货申请',
+    permReturnManage: '管理退货申请',
     permSerialView: '查看序列号',
     permSerialManage: '管理序列号',
     permSelectAll: '全选',
@@ -2912,6 +3269,54 @@ export const zhTranslations = {
     reserveFailed: '预留失败',
     releaseSuccess: '释放成功',
     releaseFailed: '释放失败',
+    statusRevoked: '已撤销',
+    revokeStock: '撤销',
+    revokeStockTitle: '撤销已发货内容',
+    revokeStockDesc: '订单 {orderNo} 的买家将无法再查看此内容，撤销的库存项不会回到可用库存。',
+    revokeReasonLabel: '撤销原因',
+    revokeReasonPlaceholder: '例如：拒付、发错库存',
+    revokeReissueLabel: '从此库存补发一份新的内容',
+    revokeNotifyLabel: '发送邮件通知买家',
+    revealStockContent: '显示明文',
+    hideStockContent: '隐藏明文',
+    revealStockFailed: '显示明文失败',
+    stockRevealLogs: '明文查看记录',
+    stockRevealLogsDesc: '库存项 #{id} 每次显示明文都会记录查看人。',
+    noStockRevealLogs: '该卡密尚未被查看过',
+    revealViewerUser: '买家',
+    revealViewerAdmin: '管理员',
+    revealViewerLink: '邮件链接',
+    revealedAtColumn: '时间',
+    revealViewerColumn: '查看人',
+    revokeSuccess: '已撤销',
+    revokeFailed: '撤销失败',
+    expiresAtColumn: '有效期',
+    expiresAtOptionalLabel: '有效期（可选）',
+    expiresAtHint: '留空表示不过期。过期的库存项不会被发货，并会自动标记为失效',
+    stockExpired: '已过期',
+    stockExpiringSoon: '即将过期',
+    batchExpiryTitle: '批次有效期',
+    batchExpiryDesc: '设置批次 {batchNo} 中所有未售出库存项的有效期',
+    batchExpiryUpdated: '已更新 {count} 个库存项的有效期',
+    batchExpiryFailed: '更新批次有效期失败',
+    expiryReportTitle: '临期库存',
+    expiryReportDesc: '{days} 天内到期的未售出库存，估算价值 {value}',
+    expiryReportExpiring: '即将过期',
+    expiryReportExpired: '已过期',
+    expiryReportEarliest: '最早到期',
+    expiryReportValue: '估算价值',
+    transferStockBtn: '调拨库存',
+    transferStockTitle: '调拨库存',
+    transferStockDesc: '将可用库存项转移到另一个库存，库存项保留原批次号。筛选条件留空则匹配全部可用库存项',
+    transferTargetLabel: '目标库存',
+    transferTargetPlaceholder: '选择静态库存',
+    transferRemarkKeywordLabel: '备注包含',
+    transferQuantityLabel: '数量',
+    transferQuantityHint: '留空或填 0 转移全部匹配项（最多 10000 个）',
+    transferReasonLabel: '原因（可选）',
+    transferHistoryTitle: '最近调拨',
+    transferSuccess: '已调拨 {count} 个库存项',
+    transferFailed: '调拨失败',
     importSuccessCount: '成功导入 {count} 条库存',
 
     // 序列号管理
@@ -2971,13 +3376,64 @@ export const zhTranslations = {
     keyNamePlaceholder: '第三方平台A',
     platformId: '平台标识 *',
     platformIdPlaceholder: 'platform_a',
+    apiKeyWebhookUrl: '回调地址（可选）',
+    apiKeyWebhookUrlHint: '接收该平台所建订单的事件通知，如 order.draft_expired。',
+    allowDuplicateExternalOrders: '允许外部订单号重复',
+    allowDuplicateExternalOrdersHint:
+      '默认同一平台重复提交相同 external_order_id 时直接返回已存在的订单；开启后每次都创建新订单。',
     rateLimit: '限流（次/小时）',
     scopesRequired: '权限范围 *',
     platform: '平台',
     rateLimitDisplay: '{count}/小时',
+    burstLimit: '突发额度',
+    burstLimitHint: '可瞬时连续发出的请求数，用完后按每小时速率恢复。填 0 表示按一分钟的速率计算。',
+    rateLimitBurstDisplay: '突发 {count}',
+    apiKeyUsage: '用量',
+    apiKeyUsageTitle: 'API 密钥用量 · {name}',
+    apiKeyUsageRequests: '请求数（24 小时）',
+    apiKeyUsageThrottled: '被限流（24 小时）',
+    apiKeyUsageRemaining: '当前可用额度',
+    apiKeyUsageHour: '时段',
+    apiKeyUsageEmpty: '最近 24 小时没有请求',
     apiKeyCreated: 'API密钥创建成功，请妥善保管',
     apiSecretOnce: 'API Secret (仅显示一次)',
     confirmDeleteApiKey: '确定要删除这个API密钥吗？',
+    webhooks: 'Webhook',
+    webhooksDesc: '将订单、工单事件签名后推送到 ERP 等外部系统',
+    webhookCreate: '添加 Webhook',
+    webhookEdit: '编辑 Webhook',
+    webhookName: '名称',
+    webhookUrl: '推送地址',
+    webhookEvents: '订阅事件',
+    webhookAllEvents: '全部事件',
+    webhookDescription: '描述',
+    webhookPlatform: '来源平台',
+    webhookPlatformHint:
+      '可选。设置后仅推送通过 API 从该平台（source_platform）创建的订单事件，用于把状态变更回传给合作平台。',
+    webhookEnabled: '启用',
+    webhookDisabled: '已停用',
+    webhookNone: '暂无 Webhook',
+    webhookSaved: 'Webhook 已保存',
+    webhookDeleted: 'Webhook 已删除',
+    webhookConfirmDelete: '确定删除该 Webhook 及其投递日志吗？',
+    webhookSecretOnce: '签名密钥（仅显示一次）',
+    webhookSecretHint:
+      '接收方请校验 X-AuraLogic-Signature 请求头：t 为时间戳，v1 为使用该密钥对“时间戳.请求体”计算的 HMAC-SHA256',
+    webhookRotateSecret: '轮换密钥',
+    webhookConfirmRotate: '当前密钥将立即失效，确定继续吗？',
+    webhookTest: '发送测试',
+    webhookTestSucceeded: '测试事件投递成功',
+    webhookTestFailed: '测试事件投递失败：{error}',
+    webhookDeliveries: '投递日志',
+    webhookNoDeliveries: '暂无投递记录',
+    webhookAttempts: '尝试次数',
+    webhookNextAttempt: '下次重试',
+    webhookRedeliver: '重新投递',
+    webhookRedelivered: '已重新投递',
+    webhookStatusPending: '重试中',
+    webhookStatusSucceeded: '已送达',
+    webhookStatusFailed: '失败',
+    webhookLastDelivery: '最近投递',
     apiKeyDeleted: 'API密钥已删除',
 
     // 系统日志
@@ -3001,6 +3457,146 @@ export const zhTranslations = {
     failedSms: '失败短信',
     smsPhone: '手机号',
     smsLogProvider: '服务商',
+    orderBulkImport: '批量导入订单',
+    orderCodeLookup: '按卡密查单',
+    orderCodeLookupDesc:
+      '粘贴买家提供的卡密或序列号，定位对应订单和买家。每次查询都会记录操作日志。',
+    orderCodeLookupPlaceholder: '卡密或序列号',
+    orderCodeLookupSearch: '查询',
+    orderCodeLookupNoMatch: '没有订单使用该卡密',
+    orderCodeLookupFailed: '查询失败',
+    orderCodeLookupSourceStock: '卡密',
+    orderCodeLookupSourceSerial: '序列号',
+    orderCodeLookupNotDelivered: '未发货到任何订单',
+    orderCodeLookupItemStatus: '库存状态：{status}',
+    orderBulkImportDesc:
+      '从 CSV 或 Excel 文件创建订单，每行一个商品，相同 Order Ref 的行合并为一个订单；已导入过的 Order Ref 会被跳过。',
+    orderBulkImportNotify: '通知客户',
+    orderBulkImportNotifyHint: '为导入的订单向客户发送订单邮件，迁移历史订单时通常关闭。',
+    orderBulkImportUpload: '上传文件',
+    orderBulkImportFileFormatError: '仅支持 .csv 或 .xlsx 文件',
+    orderBulkImportStatusQueued: '排队中',
+    orderBulkImportStatusRunning: '导入中',
+    orderBulkImportStatusCompleted: '已完成',
+    orderBulkImportStatusFailed: '失败',
+    orderBulkImportProgress:
+      '已处理 {processed}/{total} 个订单：创建 {created}，跳过 {skipped}，错误 {errors}',
+    orderBulkImportRow: '行号',
+    orderBulkImportOrderRef: '订单引用',
+    orderBulkImportError: '错误',
+    orderBulkImportAnother: '继续导入',
+    paymentMatching: '人工对账',
+    paymentMatchingDesc: '将金额或附言有误的银行/加密货币转账匹配到待付款订单并标记已付款。',
+    paymentMatchAddTransaction: '录入流水',
+    paymentMatchImport: '导入流水',
+    paymentMatchImportResult: '已导入 {created} 条，跳过重复 {duplicates} 条，错误 {errors} 条',
+    paymentMatchTransactions: '到账流水',
+    paymentMatchTransactionId: '交易号',
+    paymentMatchChannel: '渠道',
+    paymentMatchAmount: '金额',
+    paymentMatchCurrency: '币种',
+    paymentMatchMemo: '附言',
+    paymentMatchPayer: '付款人',
+    paymentMatchReceivedAt: '到账时间',
+    paymentMatchStatusAll: '全部',
+    paymentMatchStatusUnmatched: '未匹配',
+    paymentMatchStatusMatched: '已匹配',
+    paymentMatchStatusIgnored: '已忽略',
+    paymentMatchSelectHint: '选择一条流水查看匹配建议',
+    paymentMatchSuggestions: '建议订单',
+    paymentMatchNoSuggestions: '没有可能的匹配，请在下方搜索待付款订单。',
+    paymentMatchPendingOrders: '待付款订单',
+    paymentMatchSearchOrder: '搜索订单号',
+    paymentMatchScore: '匹配度 {score}',
+    paymentMatchReservedAmount: '唯一金额',
+    paymentMatchMatch: '匹配并标记已付款',
+    paymentMatchConfirmTitle: '确认匹配',
+    paymentMatchConfirm: '确认使用流水 {transactionId} 将订单 {orderNo} 标记为已付款？',
+    paymentMatchRemark: '备注（可选）',
+    paymentMatchSuccess: '订单已标记为已付款',
+    paymentMatchFailed: '匹配失败',
+    paymentMatchIgnore: '忽略',
+    paymentMatchIgnoreSuccess: '流水已忽略',
+    paymentMatchCreateSuccess: '流水已录入',
+    paymentMatchMatchedOrder: '已匹配订单 {orderNo}',
+    paymentMatchReasonReservedAmount: '唯一金额一致',
+    paymentMatchReasonExactAmount: '金额一致',
+    paymentMatchReasonCloseAmount: '金额相差 1% 以内',
+    paymentMatchReasonNearAmount: '金额相差 5% 以内',
+    paymentMatchReasonMemoOrderNo: '附言含订单号',
+    paymentMatchReasonMemoPartialOrderNo: '附言含部分订单号',
+    paymentMatchReasonPaymentMethod: '付款方式一致',
+    quoteRequests: '询价单',
+    quoteRequestsDesc: '回复询价模式商品的询价单，客户接受报价后请手动创建订单。',
+    returnRequests: '退货申请',
+    returnRequestsDesc: '审核客户退货申请（RMA），生成退货单号并在收货后回补库存',
+    customerTier: '客户等级',
+    customerTierHint: '询价商品对等级在商品可见名单内的客户直接显示价格，留空表示无等级',
+    priceOnRequest: '询价模式',
+    priceOnRequestHint: '隐藏价格并以询价单代替下单，可见名单内等级的客户仍可看到价格并直接下单',
+    priceVisibleTiersPlaceholder: '可见价格的客户等级，逗号分隔，如 wholesale, vip',
+    codReconciliation: '货到付款对账',
+    codReconciliationDesc: '登记承运商派送时代收的货款，并跟踪各承运商尚未回款的金额。',
+    codReport: '按承运商汇总',
+    codCarrier: '承运商',
+    codCarrierUnassigned: '待派送',
+    codPending: '待收款',
+    codOutstanding: '未回款',
+    codSettled: '已回款',
+    codShortfall: '少收差额',
+    codCollections: '代收记录',
+    codAmountDue: '应收金额',
+    codCollectedAmount: '实收金额',
+    codCollectedAt: '代收时间',
+    codStatusAll: '全部',
+    codStatusPending: '待收款',
+    codStatusCollected: '已代收',
+    codStatusSettled: '已回款',
+    codStatusCancelled: '已取消',
+    codFilterCarrier: '按承运商筛选',
+    codRecordCollection: '登记代收',
+    codRecordCollectionDesc: '订单 {orderNo}，应收 {amount}',
+    codRecordSuccess: '代收已登记',
+    codSettleSelected: '标记已回款（{count}）',
+    codSettleSuccess: '已将 {count} 条代收记录标记为已回款',
+    codNoCollections: '暂无货到付款记录',
+    slowQueries: '慢查询',
+    slowQueriesDesc:
+      '自上次重启以来耗时超过 {threshold} 毫秒的查询，按 SQL 结构聚合，字面量已脱敏。',
+    slowQueriesDisabled: '慢查询记录已关闭（database.slow_query_threshold_ms 小于 0）。',
+    slowQueriesClear: '清空',
+    slowQueriesCleared: '慢查询统计已清空',
+    slowQueriesClearFailed: '清空慢查询统计失败',
+    slowQuerySql: 'SQL',
+    slowQueryCount: '次数',
+    slowQueryAvgMs: '平均 (ms)',
+    slowQueryMaxMs: '最大 (ms)',
+    slowQueryTotalMs: '累计 (ms)',
+    slowQueryLastSeen: '最近出现',
+    backgroundJobs: '后台任务',
+    backgroundJobsDesc: '当前服务进程内的后台定时任务，统计在重启后清空。',
+    backgroundJobName: '任务',
+    backgroundJobSchedule: '调度',
+    backgroundJobKindLoop: '常驻',
+    backgroundJobHealth: '健康状态',
+    backgroundJobHealthy: '正常',
+    backgroundJobUnhealthy: '异常',
+    backgroundJobRunning: '运行中',
+    backgroundJobLastRun: '上次运行',
+    backgroundJobNextRun: '下次运行',
+    backgroundJobRuns: '运行 / 失败 / 跳过',
+    backgroundJobLastError: '最近错误',
+    backgroundJobJitter: '（随机延后至多 {seconds} 秒）',
+    scraperOffenders: '爬虫 IP',
+    scraperOffendersDesc: '最近 24 小时内被商品目录防爬标记的 IP，同一 IP 同类事件每分钟最多记录一次。',
+    scraperIP: 'IP',
+    scraperEvents: '事件数',
+    scraperEventBreakdown: '突发 / 蜜罐 / 挑战',
+    scraperLastSeen: '最近出现',
+    scraperBan: '封禁',
+    scraperBanned: '已封禁',
+    scraperBanSuccess: 'IP 已封禁',
+    scraperBanFailed: '封禁 IP 失败',
     smsContent: '内容',
     smsEventType: '事件类型',
     filterConditions: '筛选条件',
@@ -3042,6 +3638,10 @@ export const zhTranslations = {
     stockImport: '导入',
     stockDeliver: '发货',
     stockDelete: '删除',
+    stockReturn: '退货入库',
+    stockRevoke: '撤销发货',
+    stockExpire: '过期失效',
+    stockTransfer: '调拨',
     batchNo: '批次号',
     order: '订单',
     user: '用户',
@@ -3066,6 +3666,46 @@ export const zhTranslations = {
     tabUpload: '上传',
     tabPersonalization: '个性化',
     tabAdvanced: '高级',
+    tabHistory: '变更历史',
+    settingsRevisions: '配置变更历史',
+    settingsRevisionsDesc:
+      '每次保存都会记录修改前后的差异（密钥已脱敏）。回滚会恢复到所选版本的配置，密码、密钥、令牌等保持当前值不变。',
+    settingsRevisionsEmpty: '暂无配置变更记录',
+    settingsRevisionActionBaseline: '初始配置',
+    settingsRevisionActionUpdate: '修改',
+    settingsRevisionActionRollback: '回滚',
+    settingsRevisionActionImport: '导入',
+    settingsRevisionRollbackOf: '至 #{id}',
+    settingsRevisionChangeCount: '{count} 项变更',
+    settingsRevisionPath: '配置项',
+    settingsRevisionBefore: '修改前',
+    settingsRevisionAfter: '修改后',
+    settingsRevisionRollback: '回滚',
+    settingsRevisionRollbackTitle: '回滚到版本 #{id}？',
+    settingsRevisionRollbackConfirm: '配置文件将恢复为该版本并热更新，本次回滚也会记录为新的版本。',
+    settingsRevisionRolledBack: '配置已回滚',
+    settingsRevisionRollbackFailed: '回滚失败',
+    settingsBundle: '导入与导出',
+    settingsBundleDesc:
+      '将可迁移的配置导出为配置包，或导入其他环境导出的配置包。数据库、Redis、JWT、站点地址等环境相关配置不会被导出或覆盖。',
+    settingsBundleEncryptSecrets: '包含加密的密钥',
+    settingsBundleEncryptSecretsDesc: '默认脱敏密钥；开启后使用口令加密导出。',
+    settingsBundlePassphrase: '口令',
+    settingsBundleExport: '导出配置包',
+    settingsBundleExported: '配置包已导出',
+    settingsBundleExportFailed: '导出失败',
+    settingsBundleChooseFile: '选择配置包文件',
+    settingsBundleInvalidFile: '文件不是有效的 JSON',
+    settingsBundlePreview: '预检导入',
+    settingsBundlePreviewFailed: '预检失败',
+    settingsBundleNoChanges: '配置包与当前配置一致',
+    settingsBundleInvalidConfig: '导入后的配置无法通过校验',
+    settingsBundleUnresolvedSecrets: '当前环境缺少的密钥（导入后需手动配置）',
+    settingsBundleSecretsApplied: '从配置包导入的密钥',
+    settingsBundleSkippedPaths: '保持不变的环境配置',
+    settingsBundleApply: '应用导入',
+    settingsBundleImported: '配置已导入',
+    settingsBundleImportFailed: '导入失败',
     pluginPlatformSettings: '插件平台设置',
     pluginPlatformSettingsDesc: '配置可用插件运行时、业务类型白名单和沙箱策略。',
     pluginPlatformEnabled: '启用插件平台',
@@ -3211,6 +3851,10 @@ export const zhTranslations = {
     orderCancelledDesc: '订单取消后通知用户',
     resubmitRequired: '要求重新提交',
     resubmitRequiredDesc: '要求用户重新提交信息时通知',
+    orderMessageUserNotify: '用户订单留言',
+    orderMessageUserNotifyDesc: '用户就订单留言后通知管理员',
+    orderMessageAdminNotify: '订单留言回复',
+    orderMessageAdminNotifyDesc: '卖家回复订单留言后通知用户',
     ticketSection: '工单',
     ticketCreatedNotify: '工单创建',
     ticketCreatedNotifyDesc: '用户创建工单后通知管理员',
@@ -3258,12 +3902,19 @@ export const zhTranslations = {
     // 模板事件名称
     templateEventWelcome: '注册欢迎',
     templateEventEmailVerification: '邮箱验证',
+    templateEventEmailChanged: '邮箱变更通知',
     templateEventMarketing: '营销邮件',
     templateEventOrderCreated: '订单创建',
     templateEventOrderPaid: '付款成功',
     templateEventOrderShipped: '订单发货',
     templateEventOrderCompleted: '订单完成',
     templateEventOrderCancelled: '订单取消',
+    templateEventOrderSubStatus: '订单子状态更新',
+    templateEventOrderPaymentReminder: '待付款提醒',
+    templateEventVirtualStockRevoked: '虚拟商品撤销',
+    templateEventVirtualItems: '虚拟商品重发',
+    templateEventOrderNoteMention: '订单备注提及',
+    templateEventOrderMessage: '订单留言',
     templateEventOrderResubmit: '重新提交',
     templateEventTicketCreated: '工单创建',
     templateEventTicketReply: '工单回复',
@@ -3281,6 +3932,13 @@ export const zhTranslations = {
     allowGuestProductBrowseHint: '开启后未登录用户可浏览商品列表、详情页与购物车页',
     requireEmailVerification: '注册邮箱验证',
     requireEmailVerificationHint: '开启后用户注册需要验证邮箱才能登录，需先配置SMTP',
+    emailVerificationMode: '强制方式',
+    emailVerificationModeLogin: '未验证禁止登录',
+    emailVerificationModeCheckout: '仅禁止下单',
+    emailVerificationModeGrace: '宽限期后禁止登录',
+    emailVerificationModeHint: '管理员与被单独豁免的用户不受限制',
+    emailVerificationGraceDays: '宽限期（天）',
+    emailVerificationGraceDaysHint: '注册或更换邮箱后超过该天数仍未验证的用户将无法登录',
     allowEmailLogin: '允许邮件验证码登录',
     allowEmailLoginHint: '开启后用户可使用邮箱验证码登录，需先启用SMTP',
     allowPasswordReset: '允许重置密码',
@@ -3318,6 +3976,12 @@ export const zhTranslations = {
     serialVerifyCaptchaHint: '序列号防伪验证时需要验证码',
     bindCaptcha: '绑定验证码',
     bindCaptchaHint: '绑定邮箱或手机号时需要验证码',
+    recaptchaMinScore: 'reCAPTCHA v3 最低分数',
+    recaptchaMinScoreHint: '得分低于该值（0-1）的请求将被拒绝，默认 0.5',
+    captchaSceneProviders: '按场景选择提供商',
+    captchaSceneProvidersHint: '为特定场景使用不同的提供商，例如登录使用无感的 reCAPTCHA v3',
+    captchaSceneDefault: '跟随默认',
+    captchaOverrideKeys: '{provider} 密钥',
     // OAuth
     googleOAuthDesc: '配置Google第三方登录',
     enableGoogleLogin: '启用Google登录',
@@ -3381,6 +4045,12 @@ export const zhTranslations = {
     trustedProxiesPlaceholder: '127.0.0.1\n::1\n10.0.0.0/8\n172.16.0.0/12\n192.168.0.0/16',
     trustedProxiesHint:
       '留空则默认信任本机回环代理（127.0.0.1 / ::1）的 IP Header，适合本机反向代理环境。若填写值，则仅当请求对端IP属于这些地址/网段时才信任。支持单个IP或CIDR，例如 10.0.0.0/8。',
+    paymentHttpAllowlist: '付款脚本出站请求',
+    paymentHttpAllowlistDesc:
+      '限制付款方式脚本可访问的外部主机，每个付款方式可单独配置主机白名单。',
+    paymentHttpStrictAllowlist: '严格模式',
+    paymentHttpStrictAllowlistHint:
+      '开启后，未配置主机白名单的付款方式无法发起外部请求；关闭时白名单为空则允许访问任意公网主机。',
     redisConfigReadonly: 'Redis配置（只读）',
     redisConfigReadonlyDesc: '查看Redis连接信息',
     host: '主机',
@@ -3473,6 +4143,15 @@ export const zhTranslations = {
     currencyHint: '订单金额显示的货币单位',
     autoCancelHours: '自动取消时长（小时）',
     autoCancelHoursHint: '待付款订单超过此时长未付款将自动取消，设为0则禁用自动取消',
+    productAutoCancelHours: '未付款自动取消时限（小时）',
+    productAutoCancelHoursHint:
+      '含该商品的订单使用此未付款时限（多个商品取最短），0 表示使用全局设置',
+    draftExpireHours: '草稿过期时间（小时）',
+    draftExpireHoursHint:
+      '表单链接过期超过该小时数仍未填写的 API 草稿订单将被取消并通知来源平台，0 表示不自动取消。',
+    paymentReminderHours: '付款提醒（取消前小时数）',
+    paymentReminderHoursHint:
+      '待付款订单在自动取消前多少小时发送带付款链接的提醒邮件，每个订单只提醒一次，0 表示不提醒',
     maxPendingPaymentOrdersPerUser: '每用户待支付订单上限',
     maxPendingPaymentOrdersPerUserHint: '超过上限后将无法继续创建新订单',
     maxPaymentPollingTasksPerUser: '每用户支付轮询任务上限',
@@ -3504,6 +4183,12 @@ export const zhTranslations = {
     highConcurrencyProtectionRedisLease: 'Redis 租约时长（毫秒）',
     highConcurrencyProtectionRedisLeaseHint:
       '分布式槽位的自动回收时间，建议高于真实事务耗时上限，避免进程异常退出后长期占槽。',
+    highConcurrencyMaxInFlightPerUser: '单用户同时下单数',
+    highConcurrencyMaxInFlightPerUserHint: '同一用户同时处理中的下单请求上限，0 表示不限制',
+    highConcurrencyMaxInFlightPerIp: '单 IP 同时下单数',
+    highConcurrencyMaxInFlightPerIpHint: '同一 IP 同时处理中的下单请求上限，0 表示不限制',
+    highConcurrencyBlindBoxMaxInFlight: '盲盒分配并发上限',
+    highConcurrencyBlindBoxMaxInFlightHint: '盲盒随机分配的全局并发上限，用于降低库存锁竞争，0 表示不限制',
     stockDisplayTitle: '库存显示',
     stockDisplayMode: '显示模式',
     stockDisplayModeExact: '精确数量',
@@ -3521,10 +4206,39 @@ export const zhTranslations = {
     virtualDeliveryRandom: '随机发货',
     virtualDeliveryNewest: '先发新库存',
     virtualDeliveryOldest: '先发老库存',
-    virtualDeliveryOrderHint: '虚拟商品下单时从库存中选取的顺序',
+    virtualDeliveryExpiring: '先发临期库存',
+    virtualDeliveryInheritGlobal: '使用全局设置',
+    virtualDeliveryInheritInventory: '使用虚拟库存设置',
+    virtualDeliveryOverrideHint: '覆盖全局发货顺序；礼品卡通常需要先进先出',
+    virtualDeliveryOrderHint: '虚拟商品下单时从库存中选取的顺序，虚拟库存和商品绑定可单独覆盖',
     virtualScriptTimeoutMaxMs: '虚拟脚本发货超时上限（毫秒）',
     virtualScriptTimeoutMaxMsHint:
       '宿主允许的最大执行时长。脚本可在 script_config 中用 timeout_ms 请求更短或更长的超时，但最终不会超过这里。',
+    virtualStockExpiryWarningDays: '虚拟库存临期提醒（天）',
+    virtualStockExpiryWarningDaysHint: '在此天数内到期的库存项会被标记并列入临期报表，已过期的库存项不再参与分配',
+    cartReservationTtlSeconds: '购物车预留时长（秒）',
+    cartReservationTtlSecondsHint: '开启加购即预留的商品，加入购物车后库存保留的时长，超时自动释放',
+    tierPricingPromoStacking: '阶梯价与优惠码',
+    tierPricingPromoStack: '叠加（优惠码按阶梯价后小计计算）',
+    tierPricingPromoExclusive: '互斥（已享阶梯价的订单不能使用优惠码）',
+    tierPricingPromoBest: '取优（自动选择更优惠的一种）',
+    tierPricingPromoStackingHint: '订单达到商品阶梯价档位时，优惠码的使用规则',
+    waitingRoomSettings: '抢购等候室',
+    waitingRoomEnabled: '开启等候室',
+    waitingRoomEnabledHint: '用户需先排队，按设定速率放行后才能下单',
+    waitingRoomAllProducts: '整站排队',
+    waitingRoomAllProductsHint: '关闭时仅开启了“抢购排队”的商品需要排队',
+    waitingRoomAdmitPerSecond: '每秒放行人数',
+    waitingRoomBurst: '瞬时放行上限',
+    waitingRoomAdmissionTtl: '放行有效期（秒）',
+    waitingRoomAbandonAfter: '未响应移出队列（秒）',
+    waitingRoomMetrics: '等候室指标',
+    waitingRoomQueueLength: '排队中',
+    waitingRoomAdmittedActive: '有效放行',
+    waitingRoomJoinedTotal: '累计排队',
+    waitingRoomAdmittedTotal: '累计放行',
+    waitingRoomAbandonedTotal: '累计放弃',
+    waitingRoomAbandonRate: '放弃率',
     showVirtualStockRemark: '向用户显示虚拟产品备注',
     showVirtualStockRemarkHint: '启用后，用户可以在订单详情页看到虚拟产品库存的备注信息',
     enableVirtualStockInlineIframe: '启用虚拟库存内联 iframe',
@@ -3553,12 +4267,47 @@ export const zhTranslations = {
     invoiceFooterPlaceholder: '例如：感谢您的惠顾！',
     invoiceCustomTemplate: '自定义 HTML 模板',
     invoiceCustomTemplateTip:
-      '可用变量：{{.CompanyName}}, {{.OrderNo}}, {{.InvoiceNo}}, {{.OrderDate}}, {{.CompletedDate}}, {{.CustomerName}}, {{.CustomerEmail}}, {{.CustomerPhone}}, {{.CustomerAddress}}, {{.Items}}, {{.Subtotal}}, {{.DiscountAmount}}, {{.HasDiscount}}, {{.TotalAmount}}, {{.Currency}}, {{.FooterText}}, {{.AppName}}',
+      '可用变量：{{.CompanyName}}, {{.OrderNo}}, {{.InvoiceNo}}, {{.OrderDate}}, {{.CompletedDate}}, {{.CustomerName}}, {{.CustomerEmail}}, {{.CustomerPhone}}, {{.CustomerAddress}}, {{.Items}}, {{.Subtotal}}, {{.DiscountAmount}}, {{.HasDiscount}}, {{.TotalAmount}}, {{.Currency}}, {{.FooterText}}, {{.AppName}}。商品行字段：{{.Name}}, {{.SKU}}, {{.Quantity}}, {{.UnitPrice}}, {{.Discount}}, {{.LineTotal}}',
     formAndLinkSettings: '表单和链接设置',
     formAndLinkSettingsDesc: '配置表单和魔法链接过期时间',
+    exchangeRateSettings: '汇率服务',
+    exchangeRateSettingsDesc:
+      '为付款脚本（AuraLogic.rates）与订单多币种展示提供实时法币与加密货币汇率',
+    exchangeRateEnabled: '启用汇率服务',
+    exchangeRateEnabledHint: '关闭时内置 USDT 脚本回退使用付款方式中配置的 cny_rate',
+    exchangeRateFiatApiKey: 'exchangerate.host API Key',
+    exchangeRateCryptoApiKey: 'CoinGecko API Key（可选）',
+    exchangeRateApiKeyKeepHint: 'API Key 留空表示保持当前值',
+    exchangeRateCacheMinutes: '缓存时长（分钟）',
+    exchangeRateStaleAlertMinutes: '过期告警阈值（分钟）',
+    exchangeRateDisplayCurrencies: '订单展示币种',
+    exchangeRateDisplayCurrenciesHint:
+      '以逗号分隔的币种代码，在订单详情中展示换算后的参考金额，如 USD, EUR',
+    exchangeRatePair: '币对',
+    exchangeRateRate: '汇率',
+    exchangeRateProvider: '数据源',
+    exchangeRateUpdatedAt: '更新时间',
+    exchangeRateStale: '已过期',
+    shipmentTrackingSettings: '物流轨迹',
+    shipmentTrackingSettingsDesc:
+      '向 17track 或 AfterShip 注册运单号，同步物流轨迹并自动完成已签收订单',
+    shipmentTrackingEnabled: '启用物流轨迹',
+    shipmentTrackingProvider: '轨迹服务商',
+    shipmentTrackingApiKey: 'API Key',
+    shipmentTrackingWebhookSecret: 'Webhook 密钥',
+    shipmentTrackingWebhookSecretHint:
+      '用于校验 AfterShip 推送，17track 推送使用 API Key 校验',
+    shipmentTrackingWebhookUrlHint: '在服务商后台配置的推送地址：{url}',
+    shipmentTrackingPollInterval: '轮询间隔（分钟）',
+    shipmentTrackingAutoCompleteDays: '签收后自动完成（天）',
+    shipmentTrackingAutoCompleteDaysHint:
+      '已发货订单在签收满指定天数后自动完成，0 表示不自动完成',
     magicLinkExpiry: '魔法链接过期时间（分钟）',
     magicLinkMaxUses: '魔法链接最大使用次数',
     formExpiry: '表单过期时间（小时）',
+    disabledCountries: '停运国家/地区',
+    disabledCountriesDesc: '勾选的国家不会出现在收货表单中，提交时也会被拒绝',
+    disabledCountriesFilterPlaceholder: '按代码或名称筛选',
     // 个性化设置
     themeColor: '主题配色',
     themeColorDesc: '自定义系统主题色调',
@@ -3683,6 +4432,55 @@ export const zhTranslations = {
     maxPurchaseLimitLabel: '每个账户限购数量',
     maxPurchaseLimitPlaceholder: '0 表示不限购',
     maxPurchaseLimitHint: '设置为 0 表示不限制购买数量，设置为其他数字则每个账户最多购买该数量',
+    minOrderQuantity: '单项起订量',
+    maxOrderQuantity: '单笔订单上限',
+    quantityIncrement: '购买倍数',
+    orderQuantityRulesHint: '起订量与倍数按每个购物车/订单项校验，单笔上限按同一订单内该商品所有规格合计；0 表示不限制',
+    priceTiersLabel: '阶梯批发价',
+    priceTierMinQuantity: '起订数量',
+    priceTierUnitPrice: '单价',
+    addPriceTier: '添加档位',
+    priceTiersHint: '按单笔订单中该商品（合并所有规格）的总数量取档',
+    shippingAllowedCountries: '仅可配送至（国家代码）',
+    shippingAllowedCountriesHint: '逗号分隔的国家代码，留空表示不限制',
+    shippingBlockedCountries: '禁运国家',
+    shippingBlockedCountriesHint: '配送至这些国家的订单会被拒绝，优先于允许列表',
+    customsInfo: '报关信息',
+    customsInfoHint: '用于国际订单的 CN22/CN23 报关单，申报价值留空时使用售价',
+    hsCodePlaceholder: 'HS 编码（6-10 位）',
+    declaredValuePlaceholder: '单件申报价值',
+    weightGramsPlaceholder: '净重（克）',
+    originCountryPlaceholder: '原产国（如 CN）',
+    weightAndDimensions: '重量与尺寸',
+    packageDimensions: '包装尺寸',
+    packageDimensionsHint: '包装后的长宽高（毫米），用于订单装箱建议',
+    inventoryDimensionsHint: '覆盖该规格的商品重量与尺寸，填 0 沿用商品设置',
+    lengthMmPlaceholder: '长（毫米）',
+    widthMmPlaceholder: '宽（毫米）',
+    heightMmPlaceholder: '高（毫米）',
+    packagePlan: '包装',
+    packagePlanWeight: '净重',
+    packagePlanVolume: '体积',
+    packagePlanNoBoxes: '暂无装箱建议，请在订单设置中配置包装箱',
+    packagePlanUnpackable: '超出所有箱型',
+    packagePlanMissingData: '缺少重量或尺寸',
+    shipmentTrackingSync: '同步轨迹',
+    shipmentTrackingSynced: '物流轨迹已同步',
+    shipmentTrackingSyncFailed: '同步物流轨迹失败',
+    shipmentTrackingLastSynced: '上次同步',
+    shipmentTrackingLastError: '最近错误',
+    shipmentTrackingNotRegistered: '尚未在服务商处注册',
+    shippingBlocks: '配送拦截',
+    shippingBlocksDesc: '因商品配送限制被拒绝的尝试，按商品、国家和来源汇总',
+    shippingBlocksCountryFilter: '国家代码',
+    shippingBlocksClear: '清空记录',
+    shippingBlocksCleared: '配送拦截记录已清空',
+    shippingBlocksSource: '来源',
+    shippingBlocksSourceForm: '收货表单',
+    shippingBlocksSourceAdmin: '后台建单',
+    shippingBlocksCount: '次数',
+    shippingBlocksLastSeen: '最近拦截',
+    shippingBlocksEmpty: '暂无拦截记录',
     productImages: '商品图片',
     uploadImage: '上传图片',
     uploading: '上传中...',
@@ -3710,6 +4508,16 @@ export const zhTranslations = {
     featuredProduct: '精选商品',
     recommendedProduct: '推荐商品',
     autoDelivery: '自动发货',
+    reserveOnCart: '加购即预留',
+    reserveOnCartHint: '加入购物车时短时占用库存，未及时下单会自动释放',
+    waitingRoom: '抢购排队',
+    waitingRoomHint: '开启等候室后，下单此商品前需先排队获得放行',
+    emailDeliveryMode: '邮件交付方式',
+    emailDeliveryModeNone: '仅附订单页面链接',
+    emailDeliveryModeInline: '在邮件中直接附带卡密',
+    emailDeliveryModeLink: '附带签名提取链接（72 小时有效）',
+    emailDeliveryModeHint:
+      '控制付款/发货邮件中如何展示已发放的卡密，安全敏感商品建议使用签名链接',
     sortOrder: '排序',
     remarkLabel: '备注',
     virtualStockManageBtn: '虚拟库存管理',
@@ -3955,6 +4763,21 @@ export const zhTranslations = {
     pmPollInterval: '轮询检查间隔 (秒)',
     pmPollIntervalHint:
       '自动检查付款状态的时间间隔，建议 30-60 秒。区块链付款建议 30 秒，银行转账建议 60 秒或更长。',
+    pmAutoCancelHours: '未付款自动取消时限（小时）',
+    pmAutoCancelHoursHint:
+      '选择该付款方式后，订单自创建起超过此时长未付款将自动取消，优先于商品与全局设置；0 表示不覆盖',
+    pmAllowedHosts: '允许访问的主机',
+    pmAllowedHostsHint:
+      '每行一个主机，如 api.example.com 或 *.example.com（仅匹配子域名）。访问其他主机的请求会被拒绝并记录日志。留空则按全局严格模式处理。',
+    pmRequiredOrderTags: '必须包含的订单标签',
+    pmExcludedOrderTags: '排除的订单标签',
+    pmOrderTagsHint:
+      '多个标签用逗号分隔。订单需包含全部必需标签且不含任一排除标签时才会提供该付款方式；两项均留空表示不限制。',
+    pmSandbox: '沙箱模式',
+    pmSandboxHint:
+      '使用该方式的订单会标记为测试单、不计入营收统计，并可在订单详情中通过“模拟付款”确认付款。',
+    pmSandboxBadge: '沙箱',
+    pmCashOnDeliveryBadge: '货到付款',
     pmPackageFile: '付款包文件',
     pmPackageTarget: '导入目标',
     pmPackageTargetNew: '新建付款方式',
@@ -3965,6 +4788,10 @@ export const zhTranslations = {
     pmPackageImportedSuccess: '付款包导入成功',
     pmPackageMissingRequiredConfig: '请先填写必填配置项：{field}',
     pmPackageImportedBadge: '包导入',
+    pmBreakerOpen: '已熔断',
+    pmBreakerHalfOpen: '探测中',
+    pmBreakerReset: '重置熔断',
+    pmBreakerResetSuccess: '熔断已重置',
     pmPackageSummary: '付款包摘要',
     pmPackageVersion: '包版本',
     pmPackageEntry: '入口脚本',
@@ -4003,6 +4830,7 @@ export const zhTranslations = {
       "// 生成付款卡片 HTML\nfunction onGeneratePaymentCard(order, config) {\n  return {\n    html: '<div>自定义付款信息</div>',\n    title: '付款方式名称',\n    cache_ttl: 300  // 缓存5分钟 (0=不缓存, -1=永久, >0=秒数)\n  }\n}",
     pmTestScript: '测试脚本',
     pmTestResult: '测试结果',
+    pmTestConsole: 'Console 输出',
     pmApiRef: 'API 参考',
     pmApiRefDesc: '完整文档见 docs/PAYMENT_JS_API.md',
     pmRequiredCallbacks: '必须实现的回调函数',
@@ -4023,6 +4851,10 @@ export const zhTranslations = {
     pmPostRequest: 'POST请求',
     pmGeneralRequest: '通用HTTP请求',
     pmHttpReturns: '返回: {status, headers, body, data, error}',
+    pmRsaSign: 'PKCS#1 v1.5 签名，默认 SHA256，密钥支持 PEM 或 Base64',
+    pmRandomBytes: '加密安全的随机字节（默认十六进制）',
+    pmHttpAsync: '返回 Promise，可在 async 回调中 await',
+    pmConsoleDesc: '输出会显示在测试结果中，并保存到付款方式的脚本日志',
     pmGetTimestamp: '获取Unix时间戳',
     pmGetMethodInfo: '获取付款方式信息',
     pmThemeAdaptation: '主题适配提示',
@@ -4051,6 +4883,16 @@ export const zhTranslations = {
     clearSelected: '删除选中',
     listView: '列表视图',
     cardView: '卡片视图',
+    waitingRoomTitle: '排队等候',
+    waitingRoomDesc: '当前抢购人数较多，请保持此窗口开启，轮到您时将自动提交订单。',
+    waitingRoomJoining: '正在加入队列...',
+    waitingRoomPosition: '当前排队位置：{position} / {total}',
+    waitingRoomEstimatedWait: '预计等待约 {seconds} 秒',
+    waitingRoomAdmitted: '已轮到您，正在提交订单...',
+    waitingRoomDropped: '因长时间未响应已离开队列，请重新排队。',
+    waitingRoomRejoin: '重新排队',
+    waitingRoomLeave: '离开队列',
+    reservedUntil: '已为你预留至 {time}',
     outOfStock: '库存不足',
     select: '选择',
     removeItem: '移除商品',
@@ -4138,6 +4980,8 @@ export const zhTranslations = {
     firstQuery: '首次查询',
     lastQuery: '最近查询',
     queryWarning: '此序列号已被查询 {n} 次，请注意辨别真伪',
+    invalidatedTitle: '此序列号已作废',
+    invalidatedDesc: '该商品已于 {date} 退货，此序列号不再有效。',
     queryAnother: '查询其他序列号',
     instructions: '使用说明',
     instruction1: '序列号格式：产品码 + 序号 + 防伪码（如：ABC001XY2Z）',
@@ -4220,6 +5064,8 @@ export const zhTranslations = {
     login: '登录',
     register: '注册',
     verifyEmail: '验证邮箱',
+    revertEmailChange: '恢复邮箱',
+    virtualDelivery: '提取虚拟商品',
     products: '商品中心',
     productDetail: '商品详情',
     cart: '购物车',
@@ -4255,6 +5101,11 @@ export const zhTranslations = {
     adminPromoCodes: '优惠码管理',
     adminPromoCodeNew: '新建优惠码',
     adminPromoCodeEdit: '编辑优惠码',
+    adminPaymentMatching: '人工对账',
+    adminCODReconciliation: '货到付款对账',
+    adminQuoteRequests: '询价单',
+    adminReturnRequests: '退货申请',
+    quoteRequests: '我的询价',
     knowledge: '知识库',
     knowledgeArticle: '文章详情',
     announcements: '公告',
@@ -4332,6 +5183,17 @@ export const zhTranslations = {
     remaining: '剩余',
   },
 
+  virtualDelivery: {
+    title: '已发放的虚拟商品',
+    description: '订单 {orderNo}。请妥善保管以下卡密，切勿分享此链接。',
+    expiresAt: '此链接将于 {time} 失效',
+    empty: '此链接暂无可提取的商品。',
+    invalidLink: '链接无效或已过期，请登录后查看订单。',
+    copy: '复制',
+    copied: '已复制到剪贴板',
+    viewOrder: '登录查看订单',
+  },
+
   knowledge: {
     knowledgeBase: '知识库',
     categories: '分类',
@@ -4619,7 +5481,387 @@ export const zhTranslations = {
       'promo_code.notFound': '优惠码不存在',
       'promo_code.unavailable': '优惠码当前不可用',
       'promo_code.notApplicable': '优惠码不适用于所选商品',
+      'promo_code.notStackableWithTierPricing': '优惠码不能与阶梯批发价同时使用',
       'promo_code.minOrderAmountNotMet': '订单金额未达到最低要求',
+      'promo_code.exhausted': '优惠码已被领完',
+      'promo_code.campaignNameRequired': '活动名称不能为空',
+      'promo_code.campaignQuantityInvalid': '生成数量必须在 1 到 {max} 之间',
+      'promo_code.campaignPrefixInvalid': '前缀只能包含字母、数字、- 或 _，且不超过 {max} 个字符',
+      'promo_code.campaignPatternInvalid': '模式至少需要 {min} 个占位符（# 数字，? 字母，* 字母或数字），且优惠码总长度不超过 {max} 个字符',
+      'promo_code.campaignCodeSpaceExhausted': '该模式可生成的唯一优惠码不足，请使用更长的模式',
+      'promo_code.campaignNotFound': '优惠码活动不存在',
+    },
+  },
+
+  store: {
+    bizError: {
+      'store.notFound': '店铺不存在',
+      'store.codeInvalid': '店铺标识只能包含小写字母、数字和中划线',
+      'store.nameRequired': '店铺名称不能为空',
+      'store.codeAlreadyExists': '店铺标识已存在',
+      'store.domainInvalid': '域名无效：{domain}',
+      'store.domainAlreadyBound': '域名 {domain} 已绑定到其他店铺',
+      'store.adminInvalid': '只能为店铺分配已存在的管理员',
+    },
+  },
+
+  domain: {
+    bizError: {
+      'domain.notFound': '域名不存在',
+      'domain.alreadyExists': '域名 {domain} 已注册',
+      'domain.landingPageNotFound': '落地页 {slug} 不存在',
+    },
+  },
+
+  landingPage: {
+    bizError: {
+      'landingPage.revisionNotFound': '落地页版本不存在',
+      'landingPage.tooManyBlocks': '落地页最多包含 {max} 个区块',
+      'landingPage.blockTypeInvalid': '第 {index} 个区块类型不支持：{type}',
+      'landingPage.blockPropsInvalid': '第 {index} 个区块（{type}）属性无效',
+      'landingPage.blockFieldRequired': '第 {index} 个区块（{type}）缺少字段 {field}',
+      'landingPage.blockURLInvalid': '第 {index} 个区块（{type}）的 {field} 必须是 http(s) 地址或站内路径',
+      'landingPage.blockFieldTooLong': '第 {index} 个区块（{type}）的 {field} 过长',
+      'settings.revisionNotFound': '配置版本不存在',
+      'settings.bundleInvalid': '不是有效的配置包',
+      'settings.bundlePassphraseRequired': '加密或解密密钥需要提供口令',
+      'settings.bundleDecryptFailed': '密钥解密失败，请检查口令',
+      'settings.importConflict': '配置在预检后已被修改，请重新预检',
+      'demo.outboundBlocked': '演示模式下已禁止对外发送请求',
+    },
+  },
+
+  shortLink: {
+    bizError: {
+      'shortLink.notFound': '短链接不存在',
+      'shortLink.expired': '短链接已过期',
+      'shortLink.formUnavailable': '该订单当前无需填写收货信息',
+      'shortLink.paymentUnavailable': '订单不处于待付款状态',
+      'shortLink.appURLRequired': '未配置站点地址',
+    },
+  },
+
+  ledger: {
+    bizError: {
+      'ledger.creditAmountInvalid': '补偿金额必须大于 0',
+      'ledger.creditReasonRequired': '请填写补偿原因',
+      'ledger.creditReasonTooLong': '补偿原因不能超过 {max} 个字符',
+    },
+  },
+
+  giftPromotion: {
+    bizError: {
+      'giftPromotion.notFound': '满赠活动不存在',
+      'giftPromotion.nameRequired': '活动名称不能为空',
+      'giftPromotion.thresholdNegative': '满赠门槛不能小于 0',
+      'giftPromotion.conditionRequired': '请设置消费门槛或指定商品',
+      'giftPromotion.quantityInvalid': '赠品数量必须在 1 到 {max} 之间',
+      'giftPromotion.timeRangeInvalid': '结束时间必须晚于开始时间',
+      'giftPromotion.giftProductNotFound': '赠品商品不存在',
+      'giftPromotion.giftProductVirtual': '虚拟商品不能作为赠品',
+      'giftPromotion.giftInventoryNotFound': '赠品库存不存在',
+    },
+  },
+
+  quoteRequest: {
+    requestQuote: '询价',
+    requestQuoteDesc: '请描述 {product} × {quantity} 的采购需求',
+    message: '需求说明',
+    messagePlaceholder: '交期、定制要求、付款方式等',
+    company: '公司',
+    contactName: '联系人',
+    contactEmail: '联系邮箱',
+    contactPhone: '联系电话',
+    submit: '提交询价',
+    submitted: '询价单已提交',
+    submitFailed: '提交询价单失败',
+    myQuotes: '我的询价',
+    myQuotesDesc: '查看询价进度，接受或拒绝商家报价',
+    noQuotes: '暂无询价单',
+    quoteNo: '询价单号',
+    customer: '客户',
+    product: '商品',
+    quotedPrice: '报价',
+    unitPrice: '单价',
+    total: '合计',
+    validUntil: '有效期至',
+    validDays: '有效天数',
+    validDaysHint: '0 表示报价长期有效',
+    reply: '回复',
+    accept: '接受报价',
+    decline: '拒绝',
+    withdraw: '撤回',
+    acceptedHint: '已接受报价，我们会尽快为您创建订单',
+    updated: '询价单已更新',
+    updateFailed: '更新询价单失败',
+    sendQuote: '报价',
+    sendQuoteDesc: '{product} × {quantity}',
+    requote: '修改报价',
+    quoteSent: '报价已发送',
+    close: '关闭',
+    closed: '询价单已关闭',
+    searchPlaceholder: '询价单号 / 商品 / 公司 / 邮箱',
+    statusAll: '全部状态',
+    statusPending: '待报价',
+    statusQuoted: '已报价',
+    statusAccepted: '已接受',
+    statusDeclined: '已拒绝',
+    statusClosed: '已关闭',
+    bizError: {
+      'quote.notFound': '询价单不存在',
+      'quote.productNotFound': '商品不存在',
+      'quote.notRequired': '该商品可直接下单，无需询价',
+      'quote.quantityInvalid': '数量必须在 1 到 {max} 之间',
+      'quote.messageTooLong': '需求说明不能超过 {max} 个字符',
+      'quote.attributeInvalid': '{attribute} 选项无效',
+      'quote.tooManyOpen': '同时进行中的询价单最多 {max} 个',
+      'quote.statusInvalid': '询价单当前状态（{status}）不允许此操作',
+      'quote.priceInvalid': '报价单价必须大于 0',
+      'quote.validDaysInvalid': '有效天数必须在 0 到 {max} 之间',
+      'quote.expired': '报价已过期，请重新询价',
+      'user.customerTierInvalid': '客户等级不能超过 {max} 个字符',
+    },
+  },
+  returnRequest: {
+    title: '退货',
+    cardDesc: '对本订单的商品申请退货',
+    empty: '暂无退货申请',
+    requestReturn: '申请退货',
+    requestReturnDesc: '选择要退回的商品和数量，说明退货原因，并尽量附上照片。',
+    items: '商品',
+    quantity: '数量',
+    returnableQuantity: '可退 {count} 件',
+    reason: '退货原因',
+    reasonPlaceholder: '例如：商品到货损坏、尺码不合适…',
+    photos: '照片',
+    addPhoto: '添加照片',
+    photosHint: '最多 {max} 张（JPG、PNG、GIF、WebP）',
+    photoUploadFailed: '照片上传失败',
+    submit: '提交申请',
+    submitted: '退货申请已提交',
+    submitFailed: '退货申请提交失败',
+    cancelled: '退货申请已撤回',
+    cancelFailed: '撤回退货申请失败',
+    withdraw: '撤回',
+    returnTrackingNo: '退货单号',
+    shipBackHint: '寄回商品时请在包裹上注明此单号。',
+    adminRemark: '备注',
+    rmaNo: '退货申请号',
+    order: '订单',
+    restockedQuantity: '已回补 {count} 件',
+    approve: '同意退货',
+    reject: '拒绝',
+    receive: '确认收货',
+    approved: '已同意退货',
+    rejected: '已拒绝退货',
+    receivedToast: '已登记收到退货',
+    approveDesc: '将生成退货单号，客户寄回商品时注明。',
+    rejectDesc: '备注将作为拒绝原因展示给客户。',
+    receiveDesc: '确认已收到 {rmaNo} 退回的商品。',
+    restock: '回补绑定库存',
+    invalidateSerials: '作废退回商品的序列号',
+    searchPlaceholder: '退货申请号 / 订单号 / 退货单号',
+    statusAll: '全部状态',
+    statusPending: '待审核',
+    statusApproved: '待寄回',
+    statusRejected: '已拒绝',
+    statusReceived: '已收货',
+    statusCancelled: '已撤回',
+    bizError: {
+      'return.notFound': '退货申请不存在',
+      'return.statusInvalid': '当前状态（{status}）的退货申请不能执行此操作',
+      'return.alreadyOpen': '该订单已有处理中的退货申请',
+      'return.reasonRequired': '请填写退货原因',
+      'return.reasonTooLong': '退货原因不能超过 {max} 个字符',
+      'return.remarkRequired': '请填写拒绝原因',
+      'return.remarkTooLong': '备注不能超过 {max} 个字符',
+      'return.tooManyPhotos': '最多只能附带 {max} 张照片',
+      'return.photoInvalid': '退货照片无效，请重新上传',
+      'return.photoRequired': '请选择要上传的照片',
+      'return.photoTooLarge': '照片不能超过 {max} MB',
+      'return.photoFormatUnsupported': '不支持的照片格式',
+    },
+  },
+
+  adminActivity: {
+    bizError: {
+      'adminActivity.rangeInvalid': '统计区间需在 1 到 {max} 天之间',
+      'adminActivity.adminNotFound': '管理员不存在',
+    },
+  },
+
+  orderSubStatus: {
+    bizError: {
+      'orderSubStatus.notFound': '订单子状态不存在',
+      'orderSubStatus.nameRequired': '子状态名称不能为空',
+      'orderSubStatus.statusInvalid': '无效的订单状态：{status}',
+      'orderSubStatus.codeInvalid': '编码必须以小写字母开头，仅包含小写字母、数字和下划线（2-50 个字符）',
+      'orderSubStatus.codeExists': '子状态编码 {code} 已存在',
+      'orderSubStatus.inUse': '该子状态正被 {count} 个订单使用',
+      'orderSubStatus.statusMismatch': '子状态 {code} 要求订单状态为 {required}，当前状态为 {status}',
+      'orderSubStatus.orderChanged': '订单状态已变化，请刷新后重试',
+    },
+  },
+
+  orderAutomation: {
+    bizError: {
+      'orderAutomation.ruleNotFound': '自动化规则不存在',
+      'orderAutomation.nameRequired': '规则名称不能为空',
+      'orderAutomation.triggerInvalid': '无效的触发事件：{trigger}',
+      'orderAutomation.matchTypeInvalid': '匹配方式必须为 all 或 any',
+      'orderAutomation.tooManyConditions': '最多允许 {max} 个条件',
+      'orderAutomation.tooManyActions': '最多允许 {max} 个动作',
+      'orderAutomation.actionsRequired': '至少需要一个动作',
+      'orderAutomation.conditionInvalid': '条件 {field} 无效：{reason}',
+      'orderAutomation.actionInvalid': '动作 {type} 无效：{reason}',
+    },
+  },
+
+  ticketAutomation: {
+    bizError: {
+      'ticketAutomation.ruleNotFound': '自动化规则不存在',
+      'ticketAutomation.nameRequired': '规则名称不能为空',
+      'ticketAutomation.triggerInvalid': '无效的触发事件：{trigger}',
+      'ticketAutomation.matchTypeInvalid': '匹配方式必须为 all 或 any',
+      'ticketAutomation.tooManyConditions': '最多允许 {max} 个条件',
+      'ticketAutomation.tooManyActions': '最多允许 {max} 个动作',
+      'ticketAutomation.actionsRequired': '至少需要一个动作',
+      'ticketAutomation.conditionInvalid': '条件 {field} 无效：{reason}',
+      'ticketAutomation.actionInvalid': '动作 {type} 无效：{reason}',
+    },
+  },
+
+  webhook: {
+    bizError: {
+      'webhook.endpointNotFound': 'Webhook 不存在',
+      'webhook.deliveryNotFound': '投递记录不存在',
+      'webhook.nameRequired': 'Webhook 名称不能为空',
+      'webhook.nameTooLong': 'Webhook 名称不能超过 100 个字符',
+      'webhook.platformTooLong': '来源平台不能超过 100 个字符',
+      'webhook.urlInvalid': '推送地址必须是公网 http(s) 地址',
+      'webhook.eventInvalid': '未知的 Webhook 事件：{event}',
+      'webhook.eventsRequired': '请至少选择一个事件',
+      'webhook.tooManyEndpoints': '最多允许 {max} 个 Webhook',
+    },
+  },
+
+  listFilter: {
+    bizError: {
+      'listFilter.invalid': '筛选条件无效：{reason}',
+    },
+  },
+
+  savedView: {
+    bizError: {
+      'savedView.notFound': '视图不存在',
+      'savedView.nameRequired': '请填写视图名称（最多 100 个字符）',
+      'savedView.paramsInvalid': '视图参数无效',
+      'savedView.resourceInvalid': '不支持的列表：{resource}',
+      'savedView.limitReached': '每个列表最多保存 {max} 个视图',
+    },
+  },
+
+  fieldMask: {
+    bizError: {
+      'fieldMask.roleInvalid': '不支持的角色：{role}',
+      'fieldMask.policyInvalid': '字段 {field} 的打码策略无效',
+    },
+  },
+
+  security: {
+    bizError: {
+      'security.eventNotFound': '安全事件不存在',
+      'security.banNotFound': 'IP封禁记录不存在',
+      'security.lockoutNotFound': '账户锁定记录不存在',
+      'security.ipInvalid': '无效的IP地址：{ip}',
+      'security.durationInvalid': '封禁时长不能为负数',
+    },
+  },
+
+  approval: {
+    bizError: {
+      'approval.notFound': '审批单不存在',
+      'approval.notPending': '审批单已处理',
+      'approval.selfReview': '不能审批自己发起的操作',
+      'approval.notApproved': '审批单尚未通过',
+      'approval.expired': '审批单已过期，请重新发起',
+      'approval.mismatch': '审批单与当前操作不匹配',
+    },
+  },
+
+  orderNote: {
+    bizError: {
+      'orderNote.notFound': '订单备注不存在',
+      'orderNote.contentRequired': '备注内容不能为空',
+      'orderNote.contentTooLong': '备注内容不能超过 {max} 个字符',
+      'orderNote.notAuthor': '只有备注作者可以修改该备注',
+      'orderNote.mentionInvalid': '只能提及已启用且有订单查看权限的管理员',
+      'orderNote.tooManyMentions': '一条备注最多提及 {max} 位管理员',
+    },
+  },
+
+  orderMessage: {
+    bizError: {
+      'orderMessage.contentRequired': '留言内容不能为空',
+      'orderMessage.contentTooLong': '留言不能超过 {max} 个字符',
+      'orderMessage.guestOrder': '仅注册用户的订单支持留言',
+      'orderMessage.escalated': '该会话已转为工单，请在工单中继续沟通',
+      'orderMessage.ticketDisabled': '工单系统未启用',
+      'orderMessage.emptyThread': '暂无可转为工单的留言',
+    },
+  },
+
+  orderClaim: {
+    bizError: {
+      'orderClaim.emailNotVerified': '请先验证邮箱后再认领订单',
+      'orderClaim.notFound': '未找到与该订单号及您的邮箱匹配的可认领订单',
+      'orderClaim.alreadyOwned': '该订单已关联到其他账号',
+    },
+  },
+
+  region: {
+    bizError: {
+      'region.countryNotFound': '国家/地区不存在',
+      'region.countryDisabled': '暂不支持配送至 {country}',
+      'region.provinceInvalid': '{country} 不存在省/州：{province}',
+      'region.cityInvalid': '{province} 下不存在城市：{city}',
+    },
+  },
+
+  adminNotification: {
+    bizError: {
+      'notification.subscriptionNotFound': '通知订阅不存在',
+      'notification.notFound': '通知不存在',
+      'notification.eventInvalid': '未知的通知事件：{event}',
+      'notification.channelInvalid': '未知的通知渠道：{channel}',
+      'notification.telegramNotConfigured': '尚未配置 Telegram 机器人 Token',
+      'notification.telegramChatIDRequired': '请填写 Telegram 会话 ID',
+      'notification.filterTooLarge': '筛选值最多 {max} 个',
+      'notification.countryFilterInvalid': '国家筛选仅适用于订单事件',
+      'notification.categoryFilterInvalid': '分类筛选仅适用于工单和低库存事件',
+      'notification.tooManySubscriptions': '通知订阅最多 {max} 个',
+    },
+  },
+
+  virtual_inventory: {
+    bizError: {
+      'virtual_inventory.revokeReasonRequired': '请填写撤销原因',
+      'virtual_inventory.stockItemNotDelivered': '只有已发货的库存项才能撤销',
+      'virtual_inventory.reissueScriptUnsupported': '脚本库存不支持补发',
+      'virtual_inventory.expiresAtInvalid': '有效期格式无效',
+      'virtual_inventory.expiresAtPast': '有效期必须晚于当前时间',
+      'virtual_inventory.batchNoRequired': '批次号不能为空',
+      'virtual_inventory.batchNotFound': '批次 {batch_no} 中没有未售出的库存项',
+      'waiting_room.admissionRequired': '该商品正在抢购中，请先排队再下单',
+      'waiting_room.tokenInvalid': '排队放行已过期，请重新排队',
+      'waiting_room.disabled': '等候室未开启',
+      'virtual_inventory.deliveryOrderInvalid': '无效的发货顺序：{order}',
+      'virtual_inventory.notFound': '虚拟库存不存在',
+      'virtual_inventory.transferTargetInvalid': '请选择其他目标库存',
+      'virtual_inventory.transferQuantityInvalid': '调拨数量必须在 1 到 {max} 之间',
+      'virtual_inventory.transferScriptUnsupported': '脚本库存没有库存项，无法调拨',
+      'virtual_inventory.transferNoMatch': '没有符合筛选条件的可用库存项',
+      'virtual_inventory.transferInsufficient': '符合条件的可用库存项只有 {available} 个',
     },
   },
 
@@ -12,6 +12,10 @@ describe('payment query helpers', () => {
     expect(userPaymentMethodsQueryKey).toEqual(['userPaymentMethods'])
     expect(adminPaymentMethodsQueryKey).not.toEqual(userPaymentMethodsQueryKey)
     expect(getUserPaymentMethodsQueryOptions().queryKey).toEqual(userPaymentMethodsQueryKey)
+    expect(getUserPaymentMethodsQueryOptions('ORD-1001').queryKey).toEqual([
+      'userPaymentMethods',
+      'ORD-1001',
+    ])
     expect(getOrderPaymentInfoQueryKey('ORD-1001')).toEqual(['orderPaymentInfo', 'ORD-1001'])
   })
 
@@ -30,10 +30,10 @@ export function getOrderPaymentInfoQueryOptions(orderNo: string) {
   }
 }
 
-export function getUserPaymentMethodsQueryOptions() {
+export function getUserPaymentMethodsQueryOptions(orderNo?: string) {
   return {
-    queryKey: userPaymentMethodsQueryKey,
-    queryFn: () => getUserPaymentMethods(),
+    queryKey: orderNo ? ([...userPaymentMethodsQueryKey, orderNo] as const) : userPaymentMethodsQueryKey,
+    queryFn: () => getUserPaymentMethods(orderNo),
     staleTime: 1000 * 60 * 5,
   }
 }
@@ -1,6 +1,6 @@
 /** @jest-environment node */
 
-import { AUTH_TOKEN_COOKIE_NAME } from '@/lib/auth'
+import { AUTH_SESSION_HINT_COOKIE_NAME, AUTH_TOKEN_COOKIE_NAME } from '@/lib/auth'
 
 type HeaderInitMap = Record<string, string | undefined>
 type CookieValueMap = Record<string, string | undefined>
@@ -331,4 +331,37 @@ describe('server-api', () => {
       },
     })
   })
+
+  test('forwards browser cookies for backend cookie sessions without a proxy token', async () => {
+    const fetchMock = jest.fn().mockResolvedValue(
+      new Response(JSON.stringify({ data: { order_no: 'ORD-2001' } }), {
+        status: 200,
+        headers: {
+          'content-type': 'application/json',
+        },
+      })
+    )
+    global.fetch = fetchMock as unknown as typeof fetch
+
+    const { module } = loadServerAPIModule({
+      requestHeaders: {
+        cookie: `${AUTH_SESSION_HINT_COOKIE_NAME}=1; auralogic_sid=jwt`,
+      },
+      cookieValues: {
+        [AUTH_SESSION_HINT_COOKIE_NAME]: '1',
+      },
+    })
+
+    await expect(module.getServerOrder('ORD-2001')).resolves.toEqual({
+      data: { order_no: 'ORD-2001' },
+    })
+    expect(fetchMock.mock.calls[0][1]).toEqual({
+      cache: 'no-store',
+      headers: {
+        Accept: 'application/json',
+        Cookie: `${AUTH_SESSION_HINT_COOKIE_NAME}=1; auralogic_sid=jwt`,
+        'X-Auth-Mode': 'cookie',
+      },
+    })
+  })
 })
@@ -1,7 +1,11 @@
 import 'server-only'
 
 import { cookies, headers } from 'next/headers'
-import { AUTH_TOKEN_COOKIE_NAME } from '@/lib/auth'
+import {
+  AUTH_MODE_HEADER,
+  AUTH_SESSION_HINT_COOKIE_NAME,
+  AUTH_TOKEN_COOKIE_NAME,
+} from '@/lib/auth'
 import { resolveServerAPIBaseURL } from '@/lib/server-api-base-url'
 import type { OrderQueryParams } from '@/types/order'
 
@@ -66,6 +70,15 @@ export async function getServerAuthToken(): Promise<string | undefined> {
   return token || undefined
 }
 
+// 后端 Cookie 会话没有代理保存的令牌，凭会话标记转发浏览器 Cookie
+async function getServerSessionCookieHeader(): Promise<string | undefined> {
+  const [cookieStore, requestHeaders] = await Promise.all([cookies(), headers()])
+  if (cookieStore.get(AUTH_SESSION_HINT_COOKIE_NAME)?.value !== '1') {
+    return undefined
+  }
+  return requestHeaders.get('cookie')?.trim() || undefined
+}
+
 function extractServerErrorMessage(payload: any, statusText: string): string {
   return (
     payload?.message ||
@@ -83,7 +96,9 @@ async function fetchServerAPI(path: string, options?: { auth?: boolean }) {
     resolveServerLocaleHeader(),
     options?.auth ? getServerAuthToken() : Promise.resolve(undefined),
   ])
-  if (options?.auth && !authToken) {
+  const sessionCookie =
+    options?.auth && !authToken ? await getServerSessionCookieHeader() : undefined
+  if (options?.auth && !authToken && !sessionCookie) {
     const error: any = new Error('Authentication required')
     error.status = 401
     throw error
@@ -94,6 +109,7 @@ async function fetchServerAPI(path: string, options?: { auth?: boolean }) {
       Accept: 'application/json',
       ...(locale ? { [APP_LOCALE_HEADER]: locale } : {}),
       ...(authToken ? { Authorization: `Bearer ${authToken}` } : {}),
+      ...(sessionCookie ? { Cookie: sessionCookie, [AUTH_MODE_HEADER]: 'cookie' } : {}),
     },
   })
 
@@ -31,6 +31,8 @@ export interface Order {
   items: OrderItem[]
   total_amount_minor?: number
   currency?: string
+  display_amounts?: OrderDisplayAmount[]
+  display_amount?: OrderDisplayAmount
   receiverName?: string
   receiver_name?: string
   receiverPhone?: string
@@ -59,17 +61,21 @@ export interface Order {
   serial_generated_at?: string
   shippedAt?: string
   shipped_at?: string
+  shipment_tracking?: ShipmentTracking | null
+  returned_quantities?: Record<string, number>
+  merged_into_order_id?: number
+  merged_into_order_no?: string
   formToken?: string
   form_token?: string
   formSubmittedAt?: string
   form_submitted_at?: string
   formExpiresAt?: string
   form_expires_at?: string
+  payment_deadline_at?: string
+  is_sandbox?: boolean
   userEmail?: string
   user_email?: string
   remark?: string
-  adminRemark?: string
-  admin_remark?: string
   sharedToSupport?: boolean
   shared_to_support?: boolean
   createdAt: string
@@ -78,6 +84,49 @@ export interface Order {
   updated_at?: string
 }
 
+// 合并发货时关联的订单
+export interface OrderMergeRef {
+  id: number
+  order_no: string
+}
+
+export interface OrderMergeCandidate extends OrderMergeRef {
+  items: OrderItem[]
+  total_amount_minor: number
+  currency: string
+  created_at: string
+}
+
+export type ShipmentTrackingStatus =
+  | 'pending'
+  | 'in_transit'
+  | 'out_for_delivery'
+  | 'delivered'
+  | 'exception'
+
+export interface ShipmentTrackingEvent {
+  time: string
+  status?: string
+  description: string
+  location?: string
+}
+
+// 物流轨迹（用户侧订单详情不含 provider、last_error 等字段）
+export interface ShipmentTracking {
+  id?: number
+  order_id?: number
+  tracking_no: string
+  carrier?: string
+  provider?: string
+  status: ShipmentTrackingStatus
+  events?: ShipmentTrackingEvent[] | null
+  registered?: boolean
+  last_error?: string
+  last_synced_at?: string | null
+  next_sync_at?: string | null
+  delivered_at?: string | null
+}
+
 export type OrderStatus =
   | 'pending_payment'
   | 'draft'
@@ -108,3 +157,54 @@ export interface OrderQueryParams {
   search?: string
 }
 
+export interface OrderNote {
+  id: number
+  order_id: number
+  author_id?: number
+  source: string
+  content: string
+  pinned: boolean
+  mention_ids?: number[]
+  created_at: string
+  updated_at: string
+  author?: {
+    id: number
+    name?: string
+    email?: string
+    avatar?: string
+  }
+}
+
+export interface OrderMessage {
+  id: number
+  order_id: number
+  sender_type: 'user' | 'admin' | 'system'
+  sender_id: number
+  sender_name: string
+  content: string
+  ticket_id?: number
+  is_read_by_user: boolean
+  is_read_by_admin: boolean
+  created_at: string
+}
+
+export interface OrderMessageThread {
+  messages: OrderMessage[]
+  ticket?: {
+    id: number
+    ticket_no: string
+    subject: string
+    status: string
+  }
+  can_escalate: boolean
+}
+
+// 按汇率换算的参考金额，仅用于展示
+export interface OrderDisplayAmount {
+  currency: string
+  amount_minor: number
+  rate: number
+  updated_at: string
+  stale: boolean
+  approximate?: boolean
+}
@@ -14,6 +14,23 @@ export type ProductStatus = 'draft' | 'active' | 'inactive' | 'out_of_stock'
 
 export type ProductType = 'physical' | 'virtual'
 
+// 按用户展示币种换算的参考价（约数），下单仍按原币种结算
+export interface ProductDisplayPrice {
+  currency: string
+  price_minor: number
+  original_price_minor: number
+  rate: number
+  updated_at: string
+  stale: boolean
+  approximate: boolean
+}
+
+// 阶梯批发价：同一订单购买数量达到 min_quantity 时的单价
+export interface ProductPriceTier {
+  min_quantity: number
+  price_minor: number
+}
+
 export interface Product {
   id: number
   sku: string
@@ -25,6 +42,15 @@ export interface Product {
   tags?: string[]
   price_minor: number
   original_price_minor: number
+  price_tiers?: ProductPriceTier[]
+  min_order_quantity?: number
+  max_order_quantity?: number
+  quantity_increment?: number
+  // 询价模式：price_hidden 表示当前访问者看不到价格，需提交询价单
+  price_on_request?: boolean
+  price_visible_tiers?: string[]
+  price_hidden?: boolean
+  display_price?: ProductDisplayPrice
   stock: number
   images?: ProductImage[]
   attributes?: ProductAttribute[]
@@ -39,6 +65,7 @@ export interface Product {
   is_recommended?: boolean
   auto_delivery?: boolean
   autoDelivery?: boolean
+  email_delivery_mode?: 'none' | 'inline' | 'link'
   viewCount?: number
   view_count?: number
   saleCount?: number
@@ -80,6 +107,12 @@ export interface CreateProductRequest {
   tags?: string[]
   price_minor: number
   original_price_minor?: number
+  price_tiers?: ProductPriceTier[]
+  min_order_quantity?: number
+  max_order_quantity?: number
+  quantity_increment?: number
+  price_on_request?: boolean
+  price_visible_tiers?: string[]
   stock: number
   images?: ProductImage[]
   attributes?: ProductAttribute[]
@@ -93,7 +126,7 @@ export interface CreateProductRequest {
 export interface UpdateProductRequest extends Partial<CreateProductRequest> { }
 
 // Virtual Product Stock Types
-export type VirtualStockStatus = 'available' | 'sold' | 'reserved' | 'invalid'
+export type VirtualStockStatus = 'available' | 'sold' | 'reserved' | 'invalid' | 'revoked'
 
 export interface VirtualStockInlineIframe {
   title?: string
@@ -119,6 +152,10 @@ export interface VirtualProductStock {
   order_no?: string
   delivered_at?: string
   delivered_by?: number
+  first_viewed_at?: string
+  revoked_at?: string
+  revoke_reason?: string
+  replacement_id?: number
   batch_no?: string
   imported_by?: string
   created_at: string
@@ -130,5 +167,6 @@ export interface VirtualStockStats {
   available: number
   reserved: number
   sold: number
+  revoked?: number
 }
 
@@ -10,6 +10,7 @@ export interface User {
   is_active?: boolean
   total_spent_minor?: number
   total_order_count?: number
+  display_currency?: string
   createdAt: string
   created_at?: string
 }